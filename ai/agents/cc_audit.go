@@ -0,0 +1,157 @@
+package agent
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Statuses of the tool calls of CLI sessions.
+const (
+	ToolUseSuccess = "success"
+	ToolUseError   = "error"
+	// ToolUseBlocked is the status of the tools rejected by the tool policy
+	// or the write guard of the session, which never ran.
+	ToolUseBlocked = "blocked"
+	// ToolUseUnfinished is the status of the tools without a result when the
+	// session ended, e.g. stopped or timed out sessions.
+	ToolUseUnfinished = "unfinished"
+)
+
+// ToolUse is a tool call of a CLI session, with its outcome.
+type ToolUse struct {
+	SessionID      string
+	ConversationID int64
+	UserID         int32
+	Mode           string
+	Tool           string
+	ToolID         string
+	// Input is the summary of the input of the tool, e.g. the command of Bash.
+	Input string
+	// FilePaths are the files named by the input.
+	FilePaths []string
+	Status    string
+	// Output is the summary of the output, the error for failed tools.
+	Output     string
+	DurationMs int64
+}
+
+// ToolUseHandler records the tool calls of CLI sessions, e.g. in an audit
+// log. It is called once per tool_use event, when the tool completes.
+type ToolUseHandler func(ctx context.Context, use *ToolUse)
+
+// WithToolUseHandler sets the handler of the tool calls.
+func WithToolUseHandler(handler ToolUseHandler) CCRunnerOption {
+	return func(o *ccRunnerOptions) {
+		o.onToolUse = handler
+	}
+}
+
+// toolUseRecorder pairs the tool_use events of a session with their
+// tool_result events, passing each call with its outcome to the handler.
+type toolUseRecorder struct {
+	cfg     *CCRunnerConfig
+	handler ToolUseHandler
+
+	mu      sync.Mutex
+	pending []*ToolUse // tools without a result yet, in call order
+}
+
+// observe records the tool_use and tool_result events of the session.
+func (r *toolUseRecorder) observe(ctx context.Context, eventType string, data any) {
+	if r.handler == nil || (eventType != EventTypeToolUse && eventType != EventTypeToolResult) {
+		return
+	}
+	event, ok := data.(*EventWithMeta)
+	if !ok || event.Meta == nil {
+		return
+	}
+	meta := event.Meta
+
+	r.mu.Lock()
+	if eventType == EventTypeToolUse {
+		r.pending = append(r.pending, r.toolUse(meta.ToolName, meta.ToolID, meta.InputSummary))
+		r.mu.Unlock()
+		return
+	}
+	use := r.take(meta.ToolID, meta.ToolName)
+	r.mu.Unlock()
+	if use == nil {
+		return
+	}
+	use.Status = ToolUseSuccess
+	if meta.Status == ToolUseError {
+		use.Status = ToolUseError
+	}
+	use.Output = meta.OutputSummary
+	if meta.ErrorMsg != "" {
+		use.Output = meta.ErrorMsg
+	}
+	use.DurationMs = meta.DurationMs
+	r.handler(ctx, use)
+}
+
+// blocked records a tool rejected by the gate of the session.
+func (r *toolUseRecorder) blocked(ctx context.Context, v *ToolViolation) {
+	if r.handler == nil {
+		return
+	}
+	use := r.toolUse(v.Tool, v.ToolID, v.Input)
+	use.Status = ToolUseBlocked
+	use.Output = v.Reason()
+	r.handler(ctx, use)
+}
+
+// finish records the tools left without a result at the end of the session.
+func (r *toolUseRecorder) finish(ctx context.Context) {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+	for _, use := range pending {
+		use.Status = ToolUseUnfinished
+		r.handler(ctx, use)
+	}
+}
+
+func (r *toolUseRecorder) toolUse(tool, toolID, input string) *ToolUse {
+	return &ToolUse{
+		SessionID:      r.cfg.SessionID,
+		ConversationID: r.cfg.ConversationID,
+		UserID:         r.cfg.UserID,
+		Mode:           r.cfg.Mode,
+		Tool:           tool,
+		ToolID:         toolID,
+		Input:          input,
+		FilePaths:      inputFilePaths(input),
+	}
+}
+
+// take removes the pending call of a tool result: the call of its ID, else
+// the oldest call of its tool for CLIs reporting no IDs. r.mu must be held.
+func (r *toolUseRecorder) take(toolID, tool string) *ToolUse {
+	for i, use := range r.pending {
+		if (toolID != "" && use.ToolID == toolID) || (toolID == "" && use.Tool == tool) {
+			r.pending = append(r.pending[:i], r.pending[i+1:]...)
+			return use
+		}
+	}
+	return nil
+}
+
+// inputPathPattern matches the paths of the input summaries: "file: <path>"
+// for tools with a path, and the path fields of the other inputs, which the
+// engine summarizes as "map[file_path:<path> ...]".
+var inputPathPattern = regexp.MustCompile(`(?:^file: |\b(?:file_path|notebook_path|path):)([^\s\]]+)`)
+
+// inputFilePaths returns the files named by the summary of a tool input.
+func inputFilePaths(input string) []string {
+	var paths []string
+	for _, m := range inputPathPattern.FindAllStringSubmatch(input, -1) {
+		if path := strings.TrimSpace(m[1]); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
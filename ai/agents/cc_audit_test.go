@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestToolUseRecorder(t *testing.T) {
+	ctx := context.Background()
+	var uses []*ToolUse
+	recorder := &toolUseRecorder{
+		cfg:     &CCRunnerConfig{SessionID: "s1", ConversationID: 3, UserID: 7, Mode: "geek"},
+		handler: func(_ context.Context, use *ToolUse) { uses = append(uses, use) },
+	}
+	toolUse := func(tool, id, input string) *EventWithMeta {
+		return &EventWithMeta{EventType: EventTypeToolUse, Meta: &EventMeta{ToolName: tool, ToolID: id, InputSummary: input}}
+	}
+	toolResult := func(id, status, output string) *EventWithMeta {
+		return &EventWithMeta{EventType: EventTypeToolResult, Meta: &EventMeta{ToolID: id, Status: status, OutputSummary: output, DurationMs: 12}}
+	}
+
+	recorder.observe(ctx, EventTypeToolUse, toolUse("Bash", "t1", "rm -rf build"))
+	recorder.observe(ctx, EventTypeToolUse, toolUse("Write", "t2", "map[content:x file_path:/work/main.go]"))
+	recorder.observe(ctx, EventTypeAnswer, "ok")
+	recorder.observe(ctx, EventTypeToolResult, toolResult("t2", "success", "written"))
+	recorder.observe(ctx, EventTypeToolResult, toolResult("t1", "error", "permission denied"))
+	recorder.observe(ctx, EventTypeToolUse, toolUse("Read", "t3", "file: /work/go.mod"))
+	recorder.blocked(ctx, &ToolViolation{Tool: "WebFetch", ToolID: "t4", Input: "https://example.com", Err: errors.New("quota exceeded")})
+	recorder.finish(ctx)
+
+	want := []*ToolUse{
+		{SessionID: "s1", ConversationID: 3, UserID: 7, Mode: "geek", Tool: "Write", ToolID: "t2", Input: "map[content:x file_path:/work/main.go]",
+			FilePaths: []string{"/work/main.go"}, Status: ToolUseSuccess, Output: "written", DurationMs: 12},
+		{SessionID: "s1", ConversationID: 3, UserID: 7, Mode: "geek", Tool: "Bash", ToolID: "t1", Input: "rm -rf build",
+			Status: ToolUseError, Output: "permission denied", DurationMs: 12},
+		{SessionID: "s1", ConversationID: 3, UserID: 7, Mode: "geek", Tool: "WebFetch", ToolID: "t4", Input: "https://example.com",
+			Status: ToolUseBlocked, Output: "quota exceeded"},
+		{SessionID: "s1", ConversationID: 3, UserID: 7, Mode: "geek", Tool: "Read", ToolID: "t3", Input: "file: /work/go.mod",
+			FilePaths: []string{"/work/go.mod"}, Status: ToolUseUnfinished},
+	}
+	if !reflect.DeepEqual(uses, want) {
+		for i, use := range uses {
+			t.Logf("uses[%d] = %+v", i, use)
+		}
+		t.Fatalf("recorded %d tool uses, want %d in call and completion order", len(uses), len(want))
+	}
+}
+
+func TestToolUseRecorder_NoHandler(t *testing.T) {
+	recorder := &toolUseRecorder{cfg: &CCRunnerConfig{}}
+	recorder.observe(context.Background(), EventTypeToolUse, &EventWithMeta{Meta: &EventMeta{ToolName: "Bash"}})
+	recorder.blocked(context.Background(), &ToolViolation{Tool: "Bash"})
+	recorder.finish(context.Background())
+	if len(recorder.pending) != 0 {
+		t.Errorf("pending = %d, want nothing recorded without a handler", len(recorder.pending))
+	}
+}
@@ -0,0 +1,129 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hrygo/hotplex"
+)
+
+// Transports of the MCP servers.
+const (
+	MCPTransportStdio = "stdio"
+	MCPTransportHTTP  = "http"
+	MCPTransportSSE   = "sse"
+)
+
+// MCPServer is an external MCP server a CLI session may use. Its tools are
+// named mcp__<name>__<tool>, which tool policies can allow or deny.
+type MCPServer struct {
+	Name string
+	// Transport is MCPTransportStdio, started by the CLI from Command, or
+	// MCPTransportHTTP or MCPTransportSSE, reached at URL.
+	Transport string
+	Command   string
+	Args      []string
+	Env       map[string]string
+	URL       string
+	Headers   map[string]string
+}
+
+// mcpServerEntry is a server in the --mcp-config file of the CLI.
+type mcpServerEntry struct {
+	Type    string            `json:"type"`
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// mcpConfig returns the --mcp-config file declaring the servers.
+func mcpConfig(servers []MCPServer) ([]byte, error) {
+	entries := make(map[string]mcpServerEntry, len(servers))
+	for _, s := range servers {
+		if _, ok := entries[s.Name]; ok {
+			return nil, fmt.Errorf("duplicate MCP server %q", s.Name)
+		}
+		entries[s.Name] = mcpServerEntry{Type: s.Transport, Command: s.Command, Args: s.Args, Env: s.Env, URL: s.URL, Headers: s.Headers}
+	}
+	return json.MarshalIndent(map[string]any{"mcpServers": entries}, "", "  ")
+}
+
+// mcpConfigProvider wraps the Claude Code provider to pass the MCP servers
+// of each CLI session as an --mcp-config file. Like the tool policies, the
+// servers are keyed by CLI session ID. The files hold the credentials of the
+// servers, so they are written outside the workspaces, readable by the
+// server user only.
+type mcpConfigProvider struct {
+	hotplex.Provider
+	dir string
+
+	mu      sync.RWMutex
+	configs map[string][]byte // CLI session ID -> config file content
+}
+
+func newMCPConfigProvider(p hotplex.Provider, dir string) *mcpConfigProvider {
+	return &mcpConfigProvider{Provider: p, dir: dir, configs: make(map[string][]byte)}
+}
+
+// BuildCLIArgs adds the --mcp-config of the session, if it has servers.
+func (p *mcpConfigProvider) BuildCLIArgs(providerSessionID string, opts *hotplex.ProviderSessionOptions) []string {
+	args := p.Provider.BuildCLIArgs(providerSessionID, opts)
+	p.mu.RLock()
+	_, ok := p.configs[providerSessionID]
+	p.mu.RUnlock()
+	if ok {
+		// One argument: --mcp-config takes several values otherwise
+		args = append(args, "--mcp-config="+p.path(providerSessionID))
+	}
+	return args
+}
+
+// SetServers sets the MCP servers of a CLI session, and reports whether they
+// changed, which the running CLI of the session does not apply.
+func (p *mcpConfigProvider) SetServers(cliSessionID string, servers []MCPServer) (changed bool, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	previous, had := p.configs[cliSessionID]
+	if len(servers) == 0 {
+		if !had {
+			return false, nil
+		}
+		delete(p.configs, cliSessionID)
+		if err := os.Remove(p.path(cliSessionID)); err != nil && !os.IsNotExist(err) {
+			return true, fmt.Errorf("failed to remove MCP config: %w", err)
+		}
+		return true, nil
+	}
+
+	config, err := mcpConfig(servers)
+	if err != nil {
+		return false, err
+	}
+	if had && bytes.Equal(previous, config) {
+		return false, nil
+	}
+	if err := os.MkdirAll(p.dir, 0o700); err != nil {
+		return false, fmt.Errorf("failed to create MCP config directory: %w", err)
+	}
+	if err := os.WriteFile(p.path(cliSessionID), config, 0o600); err != nil {
+		return false, fmt.Errorf("failed to write MCP config: %w", err)
+	}
+	p.configs[cliSessionID] = config
+	return true, nil
+}
+
+// path returns the --mcp-config file of a CLI session.
+func (p *mcpConfigProvider) path(cliSessionID string) string {
+	return filepath.Join(p.dir, cliSessionID+".json")
+}
+
+// mcpConfigDir is the directory of the --mcp-config files.
+func mcpConfigDir() string {
+	return filepath.Join(os.TempDir(), "divinesense-mcp")
+}
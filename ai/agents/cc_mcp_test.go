@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hrygo/hotplex"
+)
+
+func TestMCPConfigProvider(t *testing.T) {
+	p := newMCPConfigProvider(&argsProvider{}, t.TempDir())
+	opts := &hotplex.ProviderSessionOptions{}
+	servers := []MCPServer{
+		{Name: "github", Transport: MCPTransportStdio, Command: "npx", Args: []string{"-y", "github-mcp"}, Env: map[string]string{"GITHUB_TOKEN": "t"}},
+		{Name: "docs", Transport: MCPTransportHTTP, URL: "https://docs.example.com/mcp"},
+	}
+
+	if got := p.BuildCLIArgs("s1", opts); slices.ContainsFunc(got, func(arg string) bool { return strings.HasPrefix(arg, "--mcp-config") }) {
+		t.Errorf("no servers: args = %v", got)
+	}
+	changed, err := p.SetServers("s1", servers)
+	if err != nil || !changed {
+		t.Fatalf("first servers: changed = %v, err = %v", changed, err)
+	}
+	if changed, _ := p.SetServers("s1", slices.Clone(servers)); changed {
+		t.Error("the same servers should not change the session config")
+	}
+
+	args := p.BuildCLIArgs("s1", opts)
+	path := p.path("s1")
+	if args[len(args)-1] != "--mcp-config="+path {
+		t.Fatalf("args = %v, want --mcp-config=%s last", args, path)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("config file: %v, %v", info, err)
+	}
+	data, _ := os.ReadFile(path)
+	var config struct {
+		MCPServers map[string]map[string]any `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.MCPServers["github"]["command"] != "npx" || config.MCPServers["docs"]["type"] != "http" || config.MCPServers["docs"]["command"] != nil {
+		t.Errorf("config = %s", data)
+	}
+
+	if _, err := p.SetServers("s2", []MCPServer{{Name: "a"}, {Name: "a"}}); err == nil {
+		t.Error("duplicate server names should fail")
+	}
+	if changed, _ := p.SetServers("s1", nil); !changed {
+		t.Error("removing the servers should change the session config")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("config file should be removed, stat err = %v", err)
+	}
+}
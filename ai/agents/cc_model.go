@@ -0,0 +1,109 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/hrygo/hotplex"
+)
+
+// modelTrackingProvider wraps the Claude Code provider to record the model each
+// CLI session runs on. The model is only present in the raw stream (the
+// system/init message and assistant messages): hotplex drops system messages and
+// reports the provider name as the model in its session stats.
+type modelTrackingProvider struct {
+	hotplex.Provider
+
+	mu     sync.RWMutex
+	models map[string]string // CLI session ID -> model
+}
+
+func newModelTrackingProvider(p hotplex.Provider) *modelTrackingProvider {
+	return &modelTrackingProvider{Provider: p, models: make(map[string]string)}
+}
+
+// ParseEvent records the model of the line before delegating to the provider.
+func (p *modelTrackingProvider) ParseEvent(line string) (*hotplex.ProviderEvent, error) {
+	if sessionID, model := parseStreamModel(line); sessionID != "" && model != "" {
+		p.mu.Lock()
+		p.models[sessionID] = model
+		p.mu.Unlock()
+	}
+	return p.Provider.ParseEvent(line)
+}
+
+// Model returns the model last reported by a CLI session, or "".
+func (p *modelTrackingProvider) Model(cliSessionID string) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.models[cliSessionID]
+}
+
+// parseStreamModel extracts the session ID and model from a stream-json line:
+//
+//	{"type":"system","subtype":"init","session_id":"...","model":"claude-sonnet-4-5"}
+//	{"type":"assistant","session_id":"...","message":{"model":"claude-sonnet-4-5",...}}
+func parseStreamModel(line string) (sessionID, model string) {
+	var msg struct {
+		Type      string `json:"type"`
+		Subtype   string `json:"subtype"`
+		SessionID string `json:"session_id"`
+		Model     string `json:"model"`
+		Message   *struct {
+			Model string `json:"model"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(line), &msg); err != nil {
+		return "", ""
+	}
+	switch {
+	case msg.Type == "system" && msg.Subtype == "init":
+		return msg.SessionID, msg.Model
+	case msg.Type == "assistant" && msg.Message != nil:
+		return msg.SessionID, msg.Message.Model
+	}
+	return "", ""
+}
+
+// cliSessionID returns the session ID hotplex passes to the CLI (--session-id)
+// for a business session, mirroring the derivation of its session pool.
+func cliSessionID(namespace, sessionID string) string {
+	name := fmt.Sprintf("%s:session:%s", namespace, sessionID)
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(name)).String()
+}
+
+// toSessionStatsData converts the session stats reported by hotplex, filling in
+// the session context and the model the CLI actually ran on.
+func toSessionStatsData(s *hotplex.SessionStatsData, cfg *CCRunnerConfig, model string) *SessionStatsData {
+	data := &SessionStatsData{
+		SessionID:            s.SessionID,
+		ConversationID:       cfg.ConversationID,
+		UserID:               cfg.UserID,
+		AgentType:            cfg.Mode,
+		StartTime:            s.StartTime,
+		EndTime:              s.EndTime,
+		TotalDurationMs:      s.TotalDurationMs,
+		ThinkingDurationMs:   s.ThinkingDurationMs,
+		ToolDurationMs:       s.ToolDurationMs,
+		GenerationDurationMs: s.GenerationDurationMs,
+		InputTokens:          s.InputTokens,
+		OutputTokens:         s.OutputTokens,
+		CacheWriteTokens:     s.CacheWriteTokens,
+		CacheReadTokens:      s.CacheReadTokens,
+		TotalTokens:          s.TotalTokens,
+		ToolCallCount:        s.ToolCallCount,
+		ToolsUsed:            s.ToolsUsed,
+		FilesModified:        s.FilesModified,
+		FilePaths:            s.FilePaths,
+		TotalCostUSD:         s.TotalCostUSD,
+		ModelUsed:            s.ModelUsed,
+		IsError:              s.IsError,
+		ErrorMessage:         s.ErrorMessage,
+	}
+	if model != "" {
+		data.ModelUsed = model
+	}
+	return data
+}
@@ -0,0 +1,44 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/hrygo/hotplex"
+)
+
+func TestParseStreamModel(t *testing.T) {
+	tests := []struct {
+		line      string
+		sessionID string
+		model     string
+	}{
+		{`{"type":"system","subtype":"init","session_id":"s1","model":"claude-sonnet-4-5"}`, "s1", "claude-sonnet-4-5"},
+		{`{"type":"assistant","session_id":"s1","message":{"model":"claude-opus-4-1","content":[]}}`, "s1", "claude-opus-4-1"},
+		{`{"type":"result","session_id":"s1","total_cost_usd":0.1}`, "", ""},
+		{`not json`, "", ""},
+	}
+	for _, tt := range tests {
+		sessionID, model := parseStreamModel(tt.line)
+		if sessionID != tt.sessionID || model != tt.model {
+			t.Errorf("parseStreamModel(%s) = (%q, %q), want (%q, %q)", tt.line, sessionID, model, tt.sessionID, tt.model)
+		}
+	}
+}
+
+func TestToSessionStatsData(t *testing.T) {
+	stats := &hotplex.SessionStatsData{SessionID: "s1", InputTokens: 10, TotalCostUSD: 0.5, ModelUsed: "claude-code"}
+	cfg := &CCRunnerConfig{Mode: "geek", ConversationID: 7, UserID: 3}
+
+	data := toSessionStatsData(stats, cfg, "claude-sonnet-4-5")
+	if data.ModelUsed != "claude-sonnet-4-5" || data.ConversationID != 7 || data.UserID != 3 || data.AgentType != "geek" {
+		t.Errorf("toSessionStatsData() = %+v", data)
+	}
+	if data.InputTokens != 10 || data.TotalCostUSD != 0.5 {
+		t.Errorf("toSessionStatsData() lost usage: %+v", data)
+	}
+
+	// Without a reported model the hotplex value is kept.
+	if data := toSessionStatsData(stats, cfg, ""); data.ModelUsed != "claude-code" {
+		t.Errorf("ModelUsed = %q, want claude-code", data.ModelUsed)
+	}
+}
@@ -14,11 +14,14 @@ import (
 	"github.com/hrygo/hotplex"
 )
 
+// CCRunner is the AgentRunner of the Claude Code CLI.
 type CCRunner struct {
-	engine     hotplex.HotPlexClient
-	adminToken string // Token for SetDangerBypassEnabled calls
+	engineRunner
+	cli CLICompatibility
 }
 
+var _ AgentRunner = (*CCRunner)(nil)
+
 // CCRunnerConfig defines the configuration for CCRunner execution.
 // DeviceContext is used to build TaskInstructions, not passed to hotplex directly.
 //
@@ -33,6 +36,9 @@ type CCRunnerConfig struct {
 	TaskInstructions string // Session-persistent instructions (mapped to hotplex.TaskInstructions)
 	DeviceContext    string // Used to build TaskInstructions via BuildUserContextPrompt()
 	PermissionMode   string
+	Tools            *ToolPolicy  // Tools the CLI may use; nil permits all the tools
+	WriteGuard       func() error // Rejects the WriteTools while it fails, e.g. on a full workspace; nil admits them
+	MCPServers       []MCPServer  // External MCP servers of the session, besides the CLI's own
 }
 
 type StreamMessage = hotplex.StreamMessage
@@ -126,6 +132,7 @@ const (
 	EventTypeAnswer       = "answer"
 	EventTypeError        = "error"
 	EventTypeSessionStats = "session_stats"
+	EventTypeDangerBlock  = "danger_block"
 )
 
 // CCRunnerOption is a functional option for configuring CCRunner.
@@ -135,6 +142,8 @@ type ccRunnerOptions struct {
 	adminToken       string
 	baseSystemPrompt string
 	namespace        string
+	onToolViolation  ToolViolationHandler
+	onToolUse        ToolUseHandler
 }
 
 // WithAdminToken sets the admin token for danger bypass mode.
@@ -167,64 +176,44 @@ func NewCCRunner(timeout time.Duration, logger *slog.Logger, opts ...CCRunnerOpt
 		o(opt)
 	}
 
-	// Default namespace
-	namespace := opt.namespace
-	if namespace == "" {
-		namespace = "divinesense"
-	}
+	cli := DetectCLI(context.Background(), "claude")
+	logCLICompatibility(logger, cli)
 
-	engineOpts := hotplex.EngineOptions{
-		Timeout:          timeout,
-		IdleTimeout:      30 * time.Minute,
-		Logger:           logger,
-		Namespace:        namespace,
-		BaseSystemPrompt: opt.baseSystemPrompt,
-		AdminToken:       opt.adminToken,
+	// Same provider hotplex creates by default, with resumes validated against
+	// the CLI transcripts, the session tool policies and MCP servers, gated by
+	// the CLI version and wrapped to capture the model
+	prv, err := hotplex.NewClaudeCodeProvider(hotplex.ProviderConfig{}, logger)
+	if err != nil {
+		return nil, fmt.Errorf("create claude code provider: %w", err)
 	}
+	projectsDir := ClaudeProjectsDir()
+	if removed := gcSessionMarkers(prv.GetMarkerDir(), projectsDir, sessionMarkerMaxAge); removed > 0 && logger != nil {
+		logger.Info("cc_runner: removed orphan session markers", "count", removed, "dir", prv.GetMarkerDir())
+	}
+	resumable := &resumeValidatingProvider{Provider: prv, projectsDir: projectsDir}
+	tools := newToolPolicyProvider(resumable)
+	mcp := newMCPConfigProvider(tools, mcpConfigDir())
+	models := newModelTrackingProvider(&versionGatedProvider{Provider: mcp, features: cli.Features})
 
-	engine, err := hotplex.NewEngine(engineOpts)
+	runner, err := newEngineRunner(models, timeout, logger, opt)
 	if err != nil {
 		return nil, err
 	}
+	runner.models, runner.tools, runner.mcp = models, tools, mcp
+	return &CCRunner{engineRunner: *runner, cli: cli}, nil
+}
 
-	return &CCRunner{engine: engine, adminToken: opt.adminToken}, nil
+// CLI returns the compatibility of the Claude Code CLI detected at startup.
+func (r *CCRunner) CLI() CLICompatibility {
+	return r.cli
 }
 
+// Execute runs a prompt in the CLI session of cfg.
 func (r *CCRunner) Execute(ctx context.Context, cfg *CCRunnerConfig, prompt string, callback EventCallback) error {
-	if cfg.SessionID == "" && cfg.ConversationID > 0 {
-		cfg.SessionID = ConversationIDToSessionID(cfg.ConversationID)
-	}
-
-	hotplexCfg := &hotplex.Config{
-		WorkDir:          cfg.WorkDir,
-		SessionID:        cfg.SessionID,
-		TaskInstructions: cfg.TaskInstructions,
-	}
-
-	if cfg.PermissionMode == "bypassPermissions" && r.adminToken != "" {
-		if err := r.engine.SetDangerBypassEnabled(r.adminToken, true); err != nil {
-			return fmt.Errorf("failed to enable danger bypass: %w", err)
-		}
+	if err := r.cli.Err(); err != nil {
+		return err
 	}
-
-	var cb hotplex.Callback
-	if callback != nil {
-		cb = hotplex.Callback(callback)
-	}
-
-	return r.engine.Execute(ctx, hotplexCfg, prompt, cb)
-}
-
-func (r *CCRunner) Close() error {
-	return r.engine.Close()
-}
-
-func (r *CCRunner) GetSessionStats(sessionID string) *SessionStats {
-	return r.engine.GetSessionStats(sessionID)
-}
-
-func (r *CCRunner) StopSession(sessionID string, reason string) error {
-	return r.engine.StopSession(sessionID, reason)
+	return r.execute(ctx, cfg, prompt, callback)
 }
 
 func (r *CCRunner) StopSessionByConversationID(conversationID int64, reason string) error {
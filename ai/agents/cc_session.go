@@ -0,0 +1,103 @@
+package agent
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hrygo/hotplex"
+)
+
+// sessionMarkerMaxAge is how long a session marker without a CLI transcript
+// is kept. The grace period covers sessions whose CLI has not written its
+// transcript yet.
+const sessionMarkerMaxAge = 24 * time.Hour
+
+// ClaudeProjectsDir returns the directory the Claude Code CLI stores session
+// transcripts in: $CLAUDE_CONFIG_DIR/projects, or ~/.claude/projects.
+func ClaudeProjectsDir() string {
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "projects")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".claude", "projects")
+}
+
+// hasCLITranscript reports whether the CLI has a transcript of a session, i.e.
+// whether `--resume <sessionID>` can succeed. The transcript is stored per
+// work directory as <projectsDir>/<project>/<sessionID>.jsonl.
+func hasCLITranscript(projectsDir, sessionID string) bool {
+	if projectsDir == "" || sessionID == "" {
+		return false
+	}
+	matches, err := filepath.Glob(filepath.Join(projectsDir, "*", sessionID+".jsonl"))
+	return err == nil && len(matches) > 0
+}
+
+// resumeArgs replaces `--resume <id>` with `--session-id <id>` when the CLI
+// has no transcript to resume, so the session starts over instead of failing.
+func resumeArgs(args []string, projectsDir string) []string {
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] != "--resume" || hasCLITranscript(projectsDir, args[i+1]) {
+			continue
+		}
+		slog.Warn("cc_runner: CLI session is not resumable, starting a new session",
+			"provider_session_id", args[i+1])
+		validated := slices.Clone(args)
+		validated[i] = "--session-id"
+		return validated
+	}
+	return args
+}
+
+// resumeValidatingProvider starts a new CLI session when hotplex resumes one
+// the CLI has no transcript for. hotplex decides to resume from its session
+// marker alone, which outlives the transcript when the CLI data is cleared or
+// the session never got past startup.
+type resumeValidatingProvider struct {
+	hotplex.Provider
+	projectsDir string
+}
+
+// BuildCLIArgs builds the provider's arguments, replacing an unresumable
+// --resume with --session-id.
+func (p *resumeValidatingProvider) BuildCLIArgs(providerSessionID string, opts *hotplex.ProviderSessionOptions) []string {
+	return resumeArgs(p.Provider.BuildCLIArgs(providerSessionID, opts), p.projectsDir)
+}
+
+// gcSessionMarkers removes the hotplex session markers older than maxAge
+// whose session has no CLI transcript. Such orphans are left by sessions that
+// failed at startup or whose transcripts were deleted, and would make hotplex
+// resume a session that no longer exists. It returns the number of removed markers.
+func gcSessionMarkers(markerDir, projectsDir string, maxAge time.Duration) int {
+	if markerDir == "" || projectsDir == "" {
+		return 0
+	}
+	entries, err := os.ReadDir(markerDir)
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	cutoff := time.Now().Add(-maxAge)
+	for _, entry := range entries {
+		sessionID, ok := strings.CutSuffix(entry.Name(), ".lock")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) || hasCLITranscript(projectsDir, sessionID) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(markerDir, entry.Name())); err == nil {
+			removed++
+		}
+	}
+	return removed
+}
@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path string, modTime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResumeArgs(t *testing.T) {
+	projects := t.TempDir()
+	writeFile(t, filepath.Join(projects, "-home-user-work", "live.jsonl"), time.Now())
+
+	args := []string{"--print", "--resume", "live", "--model", "m"}
+	if got := resumeArgs(args, projects); !reflect.DeepEqual(got, args) {
+		t.Errorf("resumable session: got %v, want %v", got, args)
+	}
+
+	stale := []string{"--print", "--resume", "stale", "--model", "m"}
+	want := []string{"--print", "--session-id", "stale", "--model", "m"}
+	if got := resumeArgs(stale, projects); !reflect.DeepEqual(got, want) {
+		t.Errorf("stale session: got %v, want %v", got, want)
+	}
+	if stale[1] != "--resume" {
+		t.Errorf("resumeArgs modified its input: %v", stale)
+	}
+
+	fresh := []string{"--print", "--session-id", "new"}
+	if got := resumeArgs(fresh, projects); !reflect.DeepEqual(got, fresh) {
+		t.Errorf("new session: got %v, want %v", got, fresh)
+	}
+}
+
+func TestGCSessionMarkers(t *testing.T) {
+	markers, projects := t.TempDir(), t.TempDir()
+	old := time.Now().Add(-2 * sessionMarkerMaxAge)
+
+	writeFile(t, filepath.Join(markers, "orphan.lock"), old)
+	writeFile(t, filepath.Join(markers, "recent.lock"), time.Now())
+	writeFile(t, filepath.Join(markers, "live.lock"), old)
+	writeFile(t, filepath.Join(projects, "-home-user-work", "live.jsonl"), old)
+
+	if removed := gcSessionMarkers(markers, projects, sessionMarkerMaxAge); removed != 1 {
+		t.Errorf("removed %d markers, want 1", removed)
+	}
+	for name, want := range map[string]bool{"orphan.lock": false, "recent.lock": true, "live.lock": true} {
+		_, err := os.Stat(filepath.Join(markers, name))
+		if exists := err == nil; exists != want {
+			t.Errorf("%s exists = %v, want %v", name, exists, want)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hrygo/hotplex"
+)
+
+// ErrToolNotPermitted is returned by Execute when the CLI used a tool its
+// tool policy does not permit; the session is stopped.
+var ErrToolNotPermitted = errors.New("tool not permitted by the workspace policy")
+
+// ClaudeCodeTools are the built-in tools of the Claude Code CLI. An allowlist
+// disallows the ones it does not name: under bypassPermissions, the CLI only
+// uses --allowed-tools to skip prompts, not to restrict tools.
+var ClaudeCodeTools = []string{
+	"Bash", "BashOutput", "Edit", "ExitPlanMode", "Glob", "Grep", "KillShell", "MultiEdit",
+	"NotebookEdit", "Read", "SlashCommand", "Task", "TodoWrite", "WebFetch", "WebSearch", "Write",
+}
+
+// WriteTools are the built-in tools of the Claude Code CLI that may write to
+// the working directory, rejected while CCRunnerConfig.WriteGuard fails.
+var WriteTools = []string{"Bash", "Edit", "MultiEdit", "NotebookEdit", "Write"}
+
+// ToolPolicy restricts the tools of a CLI session. Rules are tool names, or
+// MCP server prefixes (mcp__server) covering all the tools of a server.
+type ToolPolicy struct {
+	// Allowed are the only tools permitted; empty permits all the tools.
+	Allowed []string `json:"allowed"`
+	// Disallowed are denied, even if allowed.
+	Disallowed []string `json:"disallowed"`
+}
+
+// Restricted reports whether the policy restricts anything; nil policies do not.
+func (p *ToolPolicy) Restricted() bool {
+	return p != nil && (len(p.Allowed) > 0 || len(p.Disallowed) > 0)
+}
+
+// Permits reports whether a tool is permitted.
+func (p *ToolPolicy) Permits(tool string) bool {
+	if !p.Restricted() {
+		return true
+	}
+	if matchesTool(p.Disallowed, tool) {
+		return false
+	}
+	return len(p.Allowed) == 0 || matchesTool(p.Allowed, tool)
+}
+
+// Equal reports whether two policies have the same rules.
+func (p *ToolPolicy) Equal(other *ToolPolicy) bool {
+	if !p.Restricted() || !other.Restricted() {
+		return p.Restricted() == other.Restricted()
+	}
+	return slices.Equal(p.Allowed, other.Allowed) && slices.Equal(p.Disallowed, other.Disallowed)
+}
+
+// cliTools returns the --allowed-tools and --disallowed-tools of the policy.
+func (p *ToolPolicy) cliTools() (allowed, disallowed []string) {
+	if !p.Restricted() {
+		return nil, nil
+	}
+	disallowed = slices.Clone(p.Disallowed)
+	if len(p.Allowed) > 0 {
+		for _, tool := range ClaudeCodeTools {
+			if !matchesTool(p.Allowed, tool) && !slices.Contains(disallowed, tool) {
+				disallowed = append(disallowed, tool)
+			}
+		}
+	}
+	return p.Allowed, disallowed
+}
+
+// matchesTool reports whether a tool is named by one of the rules.
+func matchesTool(rules []string, tool string) bool {
+	for _, rule := range rules {
+		if tool == rule || (strings.HasPrefix(rule, "mcp__") && strings.HasPrefix(tool, rule+"__")) {
+			return true
+		}
+	}
+	return false
+}
+
+// ToolViolation is a tool used by a CLI session against its tool policy, or
+// a write its write guard rejected.
+type ToolViolation struct {
+	SessionID      string
+	ConversationID int64
+	UserID         int32
+	Mode           string
+	Tool           string
+	ToolID         string
+	Input          string
+	// Err is the error of the write guard; nil for the tool policy.
+	Err error
+}
+
+// Reason describes why the tool was rejected.
+func (v *ToolViolation) Reason() string {
+	if v.Err != nil {
+		return v.Err.Error()
+	}
+	return "tool not permitted by the workspace policy"
+}
+
+// DangerBlock is the danger_block event of a rejected tool, shaped as the
+// events of the danger detector of the engine.
+type DangerBlock struct {
+	Operation      string `json:"operation"`
+	Reason         string `json:"reason"`
+	PatternMatched string `json:"pattern_matched"`
+	Level          string `json:"level"`
+	Category       string `json:"category"`
+	BypassAllowed  bool   `json:"bypass_allowed"`
+}
+
+// dangerBlock returns the danger_block event of the violation.
+func (v *ToolViolation) dangerBlock() *DangerBlock {
+	operation := v.Tool
+	if v.Input != "" {
+		operation += ": " + v.Input
+	}
+	category := "permission"
+	if v.Err != nil {
+		category = "quota"
+	}
+	return &DangerBlock{Operation: operation, Reason: v.Reason(), PatternMatched: v.Tool, Level: "high", Category: category}
+}
+
+// ToolViolationHandler records the tool violations, e.g. in the security audit.
+type ToolViolationHandler func(ctx context.Context, violation *ToolViolation)
+
+// WithToolViolationHandler sets the handler of the tool violations.
+func WithToolViolationHandler(handler ToolViolationHandler) CCRunnerOption {
+	return func(o *ccRunnerOptions) {
+		o.onToolViolation = handler
+	}
+}
+
+// toolPolicyProvider wraps the Claude Code provider to pass the tool policy
+// of each CLI session as CLI arguments. hotplex only passes engine-wide
+// options to the provider, so policies are keyed by CLI session ID.
+type toolPolicyProvider struct {
+	hotplex.Provider
+
+	mu       sync.RWMutex
+	policies map[string]*ToolPolicy // CLI session ID -> policy
+}
+
+func newToolPolicyProvider(p hotplex.Provider) *toolPolicyProvider {
+	return &toolPolicyProvider{Provider: p, policies: make(map[string]*ToolPolicy)}
+}
+
+// BuildCLIArgs adds the tools of the session policy to the options.
+func (p *toolPolicyProvider) BuildCLIArgs(providerSessionID string, opts *hotplex.ProviderSessionOptions) []string {
+	allowed, disallowed := p.Policy(providerSessionID).cliTools()
+	if len(allowed) > 0 || len(disallowed) > 0 {
+		withTools := hotplex.ProviderSessionOptions{}
+		if opts != nil {
+			withTools = *opts
+		}
+		withTools.AllowedTools = append(slices.Clone(withTools.AllowedTools), allowed...)
+		withTools.DisallowedTools = append(slices.Clone(withTools.DisallowedTools), disallowed...)
+		opts = &withTools
+	}
+	return p.Provider.BuildCLIArgs(providerSessionID, opts)
+}
+
+// Policy returns the policy of a CLI session, or nil.
+func (p *toolPolicyProvider) Policy(cliSessionID string) *ToolPolicy {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.policies[cliSessionID]
+}
+
+// SetPolicy sets the policy of a CLI session, and reports whether it replaced
+// a different one, which the running CLI of the session does not apply.
+func (p *toolPolicyProvider) SetPolicy(cliSessionID string, policy *ToolPolicy) (changed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	previous := p.policies[cliSessionID]
+	if policy.Restricted() {
+		p.policies[cliSessionID] = policy
+	} else {
+		delete(p.policies, cliSessionID)
+	}
+	return !previous.Equal(policy)
+}
+
+// toolViolation returns the violation of a tool_use event of a session, or
+// nil if its policy permits the tool and its write guard the write.
+func toolViolation(cfg *CCRunnerConfig, data any) *ToolViolation {
+	if !cfg.Tools.Restricted() && cfg.WriteGuard == nil {
+		return nil
+	}
+	event, ok := data.(*EventWithMeta)
+	if !ok || event.Meta == nil {
+		return nil
+	}
+	v := &ToolViolation{
+		SessionID:      cfg.SessionID,
+		ConversationID: cfg.ConversationID,
+		UserID:         cfg.UserID,
+		Mode:           cfg.Mode,
+		Tool:           event.Meta.ToolName,
+		ToolID:         event.Meta.ToolID,
+		Input:          event.Meta.InputSummary,
+	}
+	if !cfg.Tools.Permits(v.Tool) {
+		return v
+	}
+	if cfg.WriteGuard != nil && slices.Contains(WriteTools, v.Tool) {
+		if v.Err = cfg.WriteGuard(); v.Err != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// toolGate dispatches the events of a CLI session to its callback, rejecting
+// the tool_use events its tool policy does not permit, and the writes its
+// write guard rejects. The CLI flags already keep the CLI from using the
+// tools the policy does not permit; the gate catches the ones they miss,
+// e.g. tools of MCP servers or of a CLI ignoring the flags.
+type toolGate struct {
+	cfg       *CCRunnerConfig
+	block     func(*ToolViolation) // stops the session of the first violation
+	violation atomic.Pointer[ToolViolation]
+}
+
+// admit reports whether an event reaches the callback. A violating tool_use
+// event does not, nor do the events after it but the session stats: the
+// stopped session may still stream the output of the tool.
+func (g *toolGate) admit(eventType string, data any) bool {
+	if g.violation.Load() != nil {
+		return eventType == EventTypeSessionStats
+	}
+	if eventType != EventTypeToolUse {
+		return true
+	}
+	v := toolViolation(g.cfg, data)
+	if v == nil {
+		return true
+	}
+	if g.violation.CompareAndSwap(nil, v) && g.block != nil {
+		g.block(v)
+	}
+	return false
+}
+
+// blockToolViolation stops the session of a violation and records it.
+func (r *engineRunner) blockToolViolation(ctx context.Context, v *ToolViolation) {
+	slog.Warn("cc_runner: tool rejected, stopping session",
+		"session_id", v.SessionID, "user_id", v.UserID, "tool", v.Tool, "reason", v.Reason())
+	if err := r.engine.StopSession(v.SessionID, "tool rejected: "+v.Tool); err != nil {
+		slog.Warn("cc_runner: failed to stop session", "session_id", v.SessionID, "error", err)
+	}
+	if r.onToolViolation != nil {
+		r.onToolViolation(ctx, v)
+	}
+}
@@ -0,0 +1,158 @@
+package agent
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/hrygo/hotplex"
+)
+
+// argsProvider returns the tools of the options it is given as arguments.
+type argsProvider struct {
+	hotplex.Provider
+}
+
+func (p *argsProvider) BuildCLIArgs(_ string, opts *hotplex.ProviderSessionOptions) []string {
+	return append(append([]string{"allowed"}, opts.AllowedTools...), append([]string{"disallowed"}, opts.DisallowedTools...)...)
+}
+
+func TestToolPolicy_Permits(t *testing.T) {
+	var unrestricted *ToolPolicy
+	if !unrestricted.Permits("Bash") {
+		t.Error("nil policies should permit all the tools")
+	}
+
+	denylist := &ToolPolicy{Disallowed: []string{"Bash", "mcp__github"}}
+	for tool, want := range map[string]bool{"Bash": false, "Read": true, "mcp__github__create_pr": false, "mcp__githubx__x": true} {
+		if got := denylist.Permits(tool); got != want {
+			t.Errorf("denylist Permits(%q) = %v, want %v", tool, got, want)
+		}
+	}
+
+	allowlist := &ToolPolicy{Allowed: []string{"Read", "Grep", "Edit"}, Disallowed: []string{"Edit"}}
+	for tool, want := range map[string]bool{"Read": true, "Edit": false, "Write": false, "mcp__github__create_pr": false} {
+		if got := allowlist.Permits(tool); got != want {
+			t.Errorf("allowlist Permits(%q) = %v, want %v", tool, got, want)
+		}
+	}
+}
+
+func TestToolPolicy_CLITools(t *testing.T) {
+	allowed, disallowed := (&ToolPolicy{Allowed: []string{"Read", "Grep"}, Disallowed: []string{"WebFetch"}}).cliTools()
+	if !reflect.DeepEqual(allowed, []string{"Read", "Grep"}) {
+		t.Errorf("allowed = %v", allowed)
+	}
+	if disallowed[0] != "WebFetch" || slices.Contains(disallowed, "Read") || !slices.Contains(disallowed, "Bash") {
+		t.Errorf("disallowed = %v, want WebFetch and the built-in tools not allowed", disallowed)
+	}
+	if n := len(disallowed); n != len(ClaudeCodeTools)-2 {
+		t.Errorf("len(disallowed) = %d, want %d (WebFetch once)", n, len(ClaudeCodeTools)-2)
+	}
+}
+
+func TestToolPolicyProvider(t *testing.T) {
+	p := newToolPolicyProvider(&argsProvider{})
+	opts := &hotplex.ProviderSessionOptions{DisallowedTools: []string{"KillShell"}}
+
+	if got := p.BuildCLIArgs("s1", opts); !reflect.DeepEqual(got, []string{"allowed", "disallowed", "KillShell"}) {
+		t.Errorf("no policy: args = %v", got)
+	}
+
+	policy := &ToolPolicy{Disallowed: []string{"Bash", "WebFetch"}}
+	if !p.SetPolicy("s1", policy) {
+		t.Error("a first restriction should change the session policy")
+	}
+	if p.SetPolicy("s1", &ToolPolicy{Disallowed: []string{"Bash", "WebFetch"}}) {
+		t.Error("the same rules should not change the session policy")
+	}
+	if got := p.BuildCLIArgs("s1", opts); !reflect.DeepEqual(got, []string{"allowed", "disallowed", "KillShell", "Bash", "WebFetch"}) {
+		t.Errorf("policy: args = %v", got)
+	}
+	if !reflect.DeepEqual(opts.DisallowedTools, []string{"KillShell"}) {
+		t.Errorf("engine options were modified: %v", opts.DisallowedTools)
+	}
+	if got := p.BuildCLIArgs("s2", opts); !reflect.DeepEqual(got, []string{"allowed", "disallowed", "KillShell"}) {
+		t.Errorf("other session: args = %v", got)
+	}
+
+	if !p.SetPolicy("s1", nil) || p.Policy("s1") != nil {
+		t.Error("lifting the policy should change the session policy")
+	}
+	if p.SetPolicy("s1", nil) {
+		t.Error("no policy twice should not change the session policy")
+	}
+}
+
+func TestToolViolation(t *testing.T) {
+	cfg := &CCRunnerConfig{Mode: "geek", SessionID: "s1", ConversationID: 3, UserID: 7, Tools: &ToolPolicy{Allowed: []string{"Read"}}}
+	event := func(tool string) *EventWithMeta {
+		return &EventWithMeta{EventType: EventTypeToolUse, EventData: tool, Meta: &EventMeta{ToolName: tool, ToolID: "t1", InputSummary: "rm -rf"}}
+	}
+
+	if v := toolViolation(cfg, event("Read")); v != nil {
+		t.Errorf("permitted tool: got violation %+v", v)
+	}
+	want := &ToolViolation{SessionID: "s1", ConversationID: 3, UserID: 7, Mode: "geek", Tool: "Bash", ToolID: "t1", Input: "rm -rf"}
+	if v := toolViolation(cfg, event("Bash")); !reflect.DeepEqual(v, want) {
+		t.Errorf("violation = %+v, want %+v", v, want)
+	}
+
+	cfg.Tools = nil
+	if v := toolViolation(cfg, event("Bash")); v != nil {
+		t.Errorf("no policy: got violation %+v", v)
+	}
+}
+
+func TestToolViolation_WriteGuard(t *testing.T) {
+	errFull := errors.New("workspace full")
+	cfg := &CCRunnerConfig{SessionID: "s1", WriteGuard: func() error { return errFull }}
+	event := func(tool string) *EventWithMeta {
+		return &EventWithMeta{EventType: EventTypeToolUse, EventData: tool, Meta: &EventMeta{ToolName: tool, InputSummary: "notes.md"}}
+	}
+
+	if v := toolViolation(cfg, event("Read")); v != nil {
+		t.Errorf("read tool: got violation %+v", v)
+	}
+	v := toolViolation(cfg, event("Write"))
+	if v == nil || !errors.Is(v.Err, errFull) || v.Reason() != "workspace full" {
+		t.Fatalf("write tool: violation = %+v, want the guard error", v)
+	}
+	want := &DangerBlock{Operation: "Write: notes.md", Reason: "workspace full", PatternMatched: "Write", Level: "high", Category: "quota"}
+	if block := v.dangerBlock(); !reflect.DeepEqual(block, want) {
+		t.Errorf("danger block = %+v, want %+v", block, want)
+	}
+
+	cfg.WriteGuard = func() error { return nil }
+	if v := toolViolation(cfg, event("Write")); v != nil {
+		t.Errorf("passing guard: got violation %+v", v)
+	}
+}
+
+func TestToolGate(t *testing.T) {
+	var blocked []*ToolViolation
+	gate := &toolGate{
+		cfg:   &CCRunnerConfig{SessionID: "s1", Tools: &ToolPolicy{Disallowed: []string{"WebFetch"}}},
+		block: func(v *ToolViolation) { blocked = append(blocked, v) },
+	}
+	event := func(tool string) *EventWithMeta {
+		return &EventWithMeta{EventType: EventTypeToolUse, EventData: tool, Meta: &EventMeta{ToolName: tool}}
+	}
+
+	if !gate.admit(EventTypeToolUse, event("Read")) || !gate.admit(EventTypeAnswer, "ok") {
+		t.Error("permitted events should reach the callback")
+	}
+	if gate.admit(EventTypeToolUse, event("WebFetch")) {
+		t.Error("the violating tool_use should be rejected")
+	}
+	if gate.admit(EventTypeToolResult, "page") || gate.admit(EventTypeToolUse, event("WebFetch")) {
+		t.Error("events after a violation should be rejected")
+	}
+	if !gate.admit(EventTypeSessionStats, nil) {
+		t.Error("session stats should still be reported")
+	}
+	if len(blocked) != 1 || blocked[0].Tool != "WebFetch" {
+		t.Errorf("blocked = %+v, want the first violation once", blocked)
+	}
+}
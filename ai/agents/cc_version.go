@@ -0,0 +1,185 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hrygo/hotplex"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	// MinCLIVersion is the oldest Claude Code CLI Geek and Evolution modes run on.
+	MinCLIVersion = "0.2.0"
+	// testedCLIMin and testedCLIMax bound the CLI versions DivineSense is tested
+	// with: [testedCLIMin, testedCLIMax). Versions outside run with a warning.
+	testedCLIMin = "1.0.0"
+	testedCLIMax = "3.0.0"
+
+	cliVersionTimeout = 5 * time.Second
+)
+
+var cliVersionPattern = regexp.MustCompile(`\d+\.\d+\.\d+`)
+
+// CLIFeatures are the CLI capabilities DivineSense relies on that vary by version.
+type CLIFeatures struct {
+	// StreamJSON is --input-format/--output-format stream-json, required for the
+	// hot-multiplexed sessions of hotplex.
+	StreamJSON bool `json:"stream_json"`
+	// Resume is --resume <session-id>. Without it a session is continued with
+	// --continue, which picks the latest conversation of the work directory.
+	Resume bool `json:"resume"`
+	// PermissionModes are the accepted --permission-mode values.
+	PermissionModes []string `json:"permission_modes"`
+	// MCPConfig is --mcp-config, declaring the MCP servers of a session.
+	MCPConfig bool `json:"mcp_config"`
+}
+
+// cliFeatureMatrix lists the features of each CLI version range, newest first.
+// A version gets the features of the first range whose min it reaches.
+var cliFeatureMatrix = []struct {
+	min      string
+	features CLIFeatures
+}{
+	{"1.0.0", CLIFeatures{StreamJSON: true, Resume: true, PermissionModes: []string{"default", "acceptEdits", "bypassPermissions", "plan"}, MCPConfig: true}},
+	{"0.2.0", CLIFeatures{StreamJSON: true, PermissionModes: []string{"default", "acceptEdits", "bypassPermissions"}}},
+	{"0.0.0", CLIFeatures{}},
+}
+
+// CLICompatibility describes the installed Claude Code CLI against the
+// compatibility matrix. It is reported in the AI diagnostics.
+type CLICompatibility struct {
+	Path      string `json:"path,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Installed bool   `json:"installed"`
+	// Tested reports whether Version is within the tested matrix.
+	Tested   bool        `json:"tested"`
+	Features CLIFeatures `json:"features"`
+	Warning  string      `json:"warning,omitempty"`
+}
+
+// Supported reports whether the CLI can run Geek and Evolution modes.
+func (c CLICompatibility) Supported() bool {
+	return c.Installed && c.Features.StreamJSON
+}
+
+// Err returns why an installed CLI cannot be used, or nil. A missing CLI is
+// not an error: it only leaves Geek and Evolution modes unavailable.
+func (c CLICompatibility) Err() error {
+	if !c.Installed || c.Supported() {
+		return nil
+	}
+	return fmt.Errorf("Claude Code CLI %s is older than the minimum supported %s; upgrade with `claude update`",
+		c.Version, MinCLIVersion)
+}
+
+// DetectCLI runs `<binary> --version` and looks the version up in the
+// compatibility matrix.
+func DetectCLI(ctx context.Context, binary string) CLICompatibility {
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return CLICompatibility{Warning: "Claude Code CLI not found; Geek and Evolution modes are unavailable"}
+	}
+	ctx, cancel := context.WithTimeout(ctx, cliVersionTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, path, "--version").Output()
+	if err != nil {
+		return CLICompatibility{
+			Path:      path,
+			Installed: true,
+			Features:  cliFeatureMatrix[0].features,
+			Warning:   fmt.Sprintf("failed to read CLI version: %v; assuming the latest feature set", err),
+		}
+	}
+	compat := cliCompatibility(parseCLIVersion(string(out)))
+	compat.Path = path
+	return compat
+}
+
+// cliCompatibility returns the compatibility of a CLI version. An unrecognized
+// version is assumed to be recent.
+func cliCompatibility(version string) CLICompatibility {
+	compat := CLICompatibility{Version: version, Installed: true}
+	if version == "" {
+		compat.Features = cliFeatureMatrix[0].features
+		compat.Warning = "unrecognized CLI version; assuming the latest feature set"
+		return compat
+	}
+
+	v := "v" + version
+	for _, row := range cliFeatureMatrix {
+		if semver.Compare(v, "v"+row.min) >= 0 {
+			compat.Features = row.features
+			break
+		}
+	}
+	compat.Tested = semver.Compare(v, "v"+testedCLIMin) >= 0 && semver.Compare(v, "v"+testedCLIMax) < 0
+	if !compat.Tested {
+		compat.Warning = fmt.Sprintf("CLI %s is outside the tested range [%s, %s)", version, testedCLIMin, testedCLIMax)
+	}
+	return compat
+}
+
+// parseCLIVersion extracts the version from `claude --version`, e.g.
+// "2.0.14 (Claude Code)" returns "2.0.14".
+func parseCLIVersion(out string) string {
+	return cliVersionPattern.FindString(out)
+}
+
+// gateCLIArgs adapts the CLI arguments built by the provider to the features
+// of the installed CLI.
+func gateCLIArgs(args []string, features CLIFeatures) []string {
+	gated := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--resume" && i+1 < len(args) && !features.Resume:
+			gated = append(gated, "--continue")
+			i++
+		case args[i] == "--permission-mode" && i+1 < len(args) && !slices.Contains(features.PermissionModes, args[i+1]):
+			slog.Warn("cc_runner: permission mode not supported by the installed CLI, using its default",
+				"permission_mode", args[i+1])
+			i++
+		case strings.HasPrefix(args[i], "--mcp-config=") && !features.MCPConfig:
+			slog.Warn("cc_runner: MCP servers not supported by the installed CLI, starting without them")
+		default:
+			gated = append(gated, args[i])
+		}
+	}
+	return gated
+}
+
+// versionGatedProvider adapts the CLI arguments of the wrapped provider to
+// the features of the installed CLI version.
+type versionGatedProvider struct {
+	hotplex.Provider
+	features CLIFeatures
+}
+
+// BuildCLIArgs builds the provider's arguments and drops or replaces the
+// ones the installed CLI does not support.
+func (p *versionGatedProvider) BuildCLIArgs(providerSessionID string, opts *hotplex.ProviderSessionOptions) []string {
+	return gateCLIArgs(p.Provider.BuildCLIArgs(providerSessionID, opts), p.features)
+}
+
+// logCLICompatibility warns at startup when the CLI is outside the tested matrix.
+func logCLICompatibility(logger *slog.Logger, compat CLICompatibility) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if compat.Warning == "" {
+		logger.Info("Claude Code CLI detected", "version", compat.Version, "path", compat.Path)
+		return
+	}
+	logger.Warn("Claude Code CLI compatibility",
+		"version", compat.Version,
+		"path", compat.Path,
+		"warning", compat.Warning,
+		"permission_modes", strings.Join(compat.Features.PermissionModes, ","))
+}
@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestCLICompatibility(t *testing.T) {
+	if got := parseCLIVersion("2.0.14 (Claude Code)\n"); got != "2.0.14" {
+		t.Errorf("parseCLIVersion = %q, want 2.0.14", got)
+	}
+
+	current := cliCompatibility("2.0.14")
+	if !current.Tested || current.Warning != "" || !current.Features.Resume || current.Err() != nil {
+		t.Errorf("2.0.14: got %+v, want a tested, fully featured CLI", current)
+	}
+
+	old := cliCompatibility("0.2.9")
+	if old.Tested || old.Warning == "" || old.Features.Resume || !old.Supported() {
+		t.Errorf("0.2.9: got %+v, want an untested CLI without --resume", old)
+	}
+
+	tooOld := cliCompatibility("0.1.5")
+	if tooOld.Supported() || tooOld.Err() == nil {
+		t.Errorf("0.1.5: got %+v, want an unsupported CLI", tooOld)
+	}
+
+	if missing := (CLICompatibility{}); missing.Err() != nil {
+		t.Errorf("missing CLI should not be an error, got %v", missing.Err())
+	}
+}
+
+func TestGateCLIArgs(t *testing.T) {
+	args := []string{"--print", "--resume", "s1", "--permission-mode", "plan", "--model", "m", "--mcp-config=/tmp/s1.json"}
+
+	full := cliCompatibility("2.0.0").Features
+	if got := gateCLIArgs(args, full); !reflect.DeepEqual(got, args) {
+		t.Errorf("full features: got %v, want args unchanged", got)
+	}
+
+	old := cliCompatibility("0.2.9").Features
+	want := []string{"--print", "--continue", "--model", "m"}
+	if got := gateCLIArgs(args, old); !reflect.DeepEqual(got, want) {
+		t.Errorf("old CLI: got %v, want %v", got, want)
+	}
+}
+
+func TestDetectCLI(t *testing.T) {
+	if compat := DetectCLI(context.Background(), "divinesense-missing-cli"); compat.Installed || compat.Warning == "" {
+		t.Errorf("missing CLI: got %+v", compat)
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the CLI")
+	}
+
+	path := filepath.Join(t.TempDir(), "claude")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\necho '2.0.14 (Claude Code)'\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	compat := DetectCLI(context.Background(), path)
+	if compat.Version != "2.0.14" || compat.Path != path || !compat.Tested {
+		t.Errorf("installed CLI: got %+v", compat)
+	}
+}
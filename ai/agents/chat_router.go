@@ -152,12 +152,24 @@ func ExtractIntent(route ChatRouteType) string {
 	}
 }
 
+// IntentName returns the intent to record for the route: the classified
+// intent if any, else the default intent of the route.
+func (r *ChatRouteResult) IntentName() string {
+	if r.Intent != "" {
+		return r.Intent
+	}
+	return ExtractIntent(r.Route)
+}
+
 // ChatRouteResult represents the routing classification result.
 type ChatRouteResult struct {
-	Route              ChatRouteType `json:"route"`
-	Method             string        `json:"method"`
-	Confidence         float64       `json:"confidence"`
-	NeedsOrchestration bool          `json:"needs_orchestration"`
+	Route ChatRouteType `json:"route"`
+	// Intent is the classified intent, e.g. an intent of the admin taxonomy;
+	// empty when the route was not classified.
+	Intent             string  `json:"intent,omitempty"`
+	Method             string  `json:"method"`
+	Confidence         float64 `json:"confidence"`
+	NeedsOrchestration bool    `json:"needs_orchestration"`
 	// Handoff indicates whether a handoff occurred during sticky route execution.
 	Handoff bool `json:"handoff"`
 	// HandoffResult contains the result of a handoff operation (if Handoff is true).
@@ -294,6 +306,14 @@ func (r *ChatRouter) RouteWithContext(ctx context.Context, input string, session
 		Method:             "router",
 		NeedsOrchestration: needsOrch,
 	}
+	if intent != routerpkg.IntentUnknown {
+		result.Intent = string(intent)
+	}
+	// Admin-defined intents route to their target expert
+	if ti, ok := r.routerService.TaxonomyIntent(intent); ok {
+		result.Route = ChatRouteType(ti.TargetExpert)
+		result.Method = "taxonomy"
+	}
 
 	// Store the route for future stickiness (only if confident)
 	if sessionCtx != nil && result.Route != "" && !needsOrch {
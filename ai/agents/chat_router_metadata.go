@@ -90,7 +90,7 @@ func (r *ChatRouterWithMetadata) RouteWithContextWithMetadata(
 	// Layer 2: Persist routing result to metadata (if successful)
 	if r.metadataMgr != nil && conversationID > 0 && blockID > 0 {
 		if result.Route != "" && !result.NeedsOrchestration {
-			intent := ExtractIntent(result.Route)
+			intent := result.IntentName()
 			if err := r.metadataMgr.SetCurrentAgent(
 				ctx,
 				conversationID,
@@ -38,6 +38,10 @@ var (
 	ErrInvalidInput       = errors.New("invalid input")
 )
 
+// ErrToolUnavailable is returned by the factories of optional tools whose
+// feature is unavailable in the deployment; parrots leave the tool out.
+var ErrToolUnavailable = errors.New("tool unavailable")
+
 // ErrorClass represents the category of error for retry decisions.
 type ErrorClass int
 
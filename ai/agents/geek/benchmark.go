@@ -0,0 +1,317 @@
+package geek
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+)
+
+const (
+	// benchmarkToolName is the tool name of the benchmark events.
+	benchmarkToolName = "Benchmark"
+	// defaultBenchmarkTimeout bounds the runs before and after the changes.
+	defaultBenchmarkTimeout = 10 * time.Minute
+	// defaultBenchmarkThreshold is the slowdown in percent reported as a
+	// regression.
+	defaultBenchmarkThreshold = 10.0
+	// defaultBenchmarkCount is the number of runs averaged per benchmark.
+	defaultBenchmarkCount = 3
+)
+
+// BenchmarkPackage is a performance-sensitive package whose benchmarks run
+// on the Evolution changes touching it.
+type BenchmarkPackage struct {
+	Path    string // Package relative to the source dir, e.g. "./ai/routing" or "./store/..."
+	Pattern string // -bench regexp, "." for all
+}
+
+// BenchmarkSuite compares the registered benchmarks before and after the
+// changes of an Evolution turn, for their PR.
+type BenchmarkSuite struct {
+	Packages  []BenchmarkPackage
+	Threshold float64       // Slowdown in percent reported as a regression
+	Count     int           // Runs averaged per benchmark
+	Benchtime string        // -benchtime of the runs, "" for the go default
+	Timeout   time.Duration // Bound of the runs before and after the changes
+}
+
+// BenchmarkSuiteFromEnv returns the suite configured by
+// DIVINESENSE_EVOLUTION_BENCHMARKS (comma-separated "package[=regexp]"),
+// DIVINESENSE_EVOLUTION_BENCHMARK_THRESHOLD (percent) and
+// DIVINESENSE_EVOLUTION_BENCHMARK_TIMEOUT_SECONDS, or nil if no package is
+// registered.
+func BenchmarkSuiteFromEnv() *BenchmarkSuite {
+	var packages []BenchmarkPackage
+	for entry := range strings.SplitSeq(os.Getenv("DIVINESENSE_EVOLUTION_BENCHMARKS"), ",") {
+		pkg, pattern, _ := strings.Cut(strings.TrimSpace(entry), "=")
+		if pkg == "" {
+			continue
+		}
+		if pattern == "" {
+			pattern = "."
+		}
+		packages = append(packages, BenchmarkPackage{Path: pkg, Pattern: pattern})
+	}
+	if len(packages) == 0 {
+		return nil
+	}
+	suite := &BenchmarkSuite{
+		Packages:  packages,
+		Threshold: defaultBenchmarkThreshold,
+		Count:     defaultBenchmarkCount,
+		Timeout:   defaultBenchmarkTimeout,
+	}
+	if threshold, err := strconv.ParseFloat(os.Getenv("DIVINESENSE_EVOLUTION_BENCHMARK_THRESHOLD"), 64); err == nil && threshold > 0 {
+		suite.Threshold = threshold
+	}
+	if seconds, err := strconv.Atoi(os.Getenv("DIVINESENSE_EVOLUTION_BENCHMARK_TIMEOUT_SECONDS")); err == nil && seconds > 0 {
+		suite.Timeout = time.Duration(seconds) * time.Second
+	}
+	return suite
+}
+
+// BenchmarkResult is the comparison of a benchmark.
+type BenchmarkResult struct {
+	Name     string  // Package and benchmark, e.g. "github.com/x/y BenchmarkZ-8"
+	BeforeNs float64 // ns/op before the changes, 0 for a new benchmark
+	AfterNs  float64 // ns/op after the changes, 0 for a removed benchmark
+}
+
+// Delta returns the change of ns/op in percent, false if the benchmark does
+// not run on both sides.
+func (r BenchmarkResult) Delta() (float64, bool) {
+	if r.BeforeNs == 0 || r.AfterNs == 0 {
+		return 0, false
+	}
+	return (r.AfterNs/r.BeforeNs - 1) * 100, true
+}
+
+// BenchmarkReport compares the benchmarks of the packages touched by the
+// changes of a turn.
+type BenchmarkReport struct {
+	Results   []BenchmarkResult // Sorted by name
+	Threshold float64           // Slowdown in percent reported as a regression
+}
+
+// Regressions returns the benchmarks slowed down beyond the threshold.
+func (r *BenchmarkReport) Regressions() []BenchmarkResult {
+	var regressions []BenchmarkResult
+	for _, result := range r.Results {
+		if delta, ok := result.Delta(); ok && delta > r.Threshold {
+			regressions = append(regressions, result)
+		}
+	}
+	return regressions
+}
+
+// Markdown returns the comparison as a Markdown table.
+func (r *BenchmarkReport) Markdown() string {
+	var b strings.Builder
+	b.WriteString("| Benchmark | Before (ns/op) | After (ns/op) | Delta |\n|---|---:|---:|---:|\n")
+	for _, result := range r.Results {
+		change := "n/a"
+		if delta, ok := result.Delta(); ok {
+			change = fmt.Sprintf("%+.1f%%", delta)
+			if delta > r.Threshold {
+				change += " ⚠️"
+			}
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", result.Name, formatNs(result.BeforeNs), formatNs(result.AfterNs), change)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// prompt asks the CLI to put the comparison in the PR description.
+func (r *BenchmarkReport) prompt() string {
+	prompt := "Add this benchmark comparison of the changed performance-sensitive packages to the PR description " +
+		"(if the PR already exists, update it with `gh pr edit`):\n\n" + r.Markdown()
+	if len(r.Regressions()) > 0 {
+		prompt += fmt.Sprintf("\n\nFlag the benchmarks marked ⚠️ as regressions beyond %g%% in the description.", r.Threshold)
+	}
+	return prompt
+}
+
+// Run runs the benchmarks of the registered packages the changes from base
+// touch, on base and on dir, and reports them as tool events. It returns a
+// nil report if the changes touch no registered package.
+func (s *BenchmarkSuite) Run(ctx context.Context, dir, base string, callback agentpkg.EventCallback) (*BenchmarkReport, error) {
+	packages, err := s.touched(ctx, dir, base)
+	if err != nil || len(packages) == 0 {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.Timeout)
+	defer cancel()
+	start := time.Now()
+	send := agentpkg.SafeCallback(callback)
+	sendEvent := func(eventType, data string, meta *agentpkg.EventMeta) {
+		if send != nil {
+			meta.ToolName = benchmarkToolName
+			meta.TotalDurationMs = time.Since(start).Milliseconds()
+			send(eventType, &agentpkg.EventWithMeta{EventType: eventType, EventData: data, Meta: meta})
+		}
+	}
+	paths := make([]string, len(packages))
+	for i, pkg := range packages {
+		paths[i] = pkg.Path
+	}
+	input := "go test -bench " + strings.Join(paths, " ")
+	sendEvent(agentpkg.EventTypeToolUse, input, &agentpkg.EventMeta{Status: "running", InputSummary: input})
+
+	report, err := s.compare(ctx, dir, base, packages)
+	meta := &agentpkg.EventMeta{DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %s", s.Timeout)
+		}
+		meta.Status, meta.ErrorMsg = "error", err.Error()
+		sendEvent(agentpkg.EventTypeToolResult, fmt.Sprintf("benchmarks failed: %v", err), meta)
+		return nil, fmt.Errorf("benchmarks failed: %w", err)
+	}
+	meta.Status, meta.OutputSummary = "success", report.Markdown()
+	sendEvent(agentpkg.EventTypeToolResult, report.Markdown(), meta)
+	return report, nil
+}
+
+// compare runs the benchmarks after the changes in dir, and before them in a
+// worktree of base.
+func (s *BenchmarkSuite) compare(ctx context.Context, dir, base string, packages []BenchmarkPackage) (*BenchmarkReport, error) {
+	after := map[string]float64{}
+	for _, pkg := range packages {
+		if err := s.runPackage(ctx, dir, pkg, after); err != nil {
+			return nil, err
+		}
+	}
+
+	worktree, err := os.MkdirTemp("", "divinesense-benchmark-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(worktree)
+	if _, err := gitOutput(ctx, dir, "worktree", "add", "--detach", worktree, base); err != nil {
+		return nil, fmt.Errorf("git worktree add %s: %w", base, err)
+	}
+	defer func() {
+		_, _ = gitOutput(context.WithoutCancel(ctx), dir, "worktree", "remove", "--force", worktree)
+	}()
+	before := map[string]float64{}
+	for _, pkg := range packages {
+		// Packages created by the changes have no benchmarks before them
+		_ = s.runPackage(ctx, worktree, pkg, before)
+	}
+
+	report := &BenchmarkReport{Threshold: s.Threshold}
+	for name, ns := range after {
+		report.Results = append(report.Results, BenchmarkResult{Name: name, BeforeNs: before[name], AfterNs: ns})
+	}
+	for name, ns := range before {
+		if _, ok := after[name]; !ok {
+			report.Results = append(report.Results, BenchmarkResult{Name: name, BeforeNs: ns})
+		}
+	}
+	slices.SortFunc(report.Results, func(a, b BenchmarkResult) int { return strings.Compare(a.Name, b.Name) })
+	return report, nil
+}
+
+// runPackage runs the benchmarks of a package in dir, adding their mean
+// ns/op to results.
+func (s *BenchmarkSuite) runPackage(ctx context.Context, dir string, pkg BenchmarkPackage, results map[string]float64) error {
+	args := []string{"test", "-run", "^$", "-bench", pkg.Pattern, "-count", strconv.Itoa(max(s.Count, 1))}
+	if s.Benchtime != "" {
+		args = append(args, "-benchtime", s.Benchtime)
+	}
+	cmd := exec.CommandContext(ctx, "go", append(args, pkg.Path)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("go test -bench %s: %w: %s", pkg.Path, err, lastLines(string(out), testGateTailLines))
+	}
+	for name, ns := range parseBenchmarks(string(out)) {
+		results[name] = ns
+	}
+	return nil
+}
+
+// touched returns the registered packages with Go files changed from base,
+// committed or not.
+func (s *BenchmarkSuite) touched(ctx context.Context, dir, base string) ([]BenchmarkPackage, error) {
+	changed, err := gitOutput(ctx, dir, "diff", "--name-only", base)
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s: %w", base, err)
+	}
+	untracked, err := gitOutput(ctx, dir, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files: %w", err)
+	}
+	var packages []BenchmarkPackage
+	for _, pkg := range s.Packages {
+		pkgPath, recursive := strings.CutSuffix(pkg.Path, "/...")
+		pkgDir := path.Clean(pkgPath)
+		for file := range strings.Lines(changed + untracked) {
+			file = strings.TrimSuffix(file, "\n")
+			if !strings.HasSuffix(file, ".go") {
+				continue
+			}
+			fileDir := path.Dir(file)
+			if fileDir == pkgDir || recursive && (pkgDir == "." || strings.HasPrefix(fileDir, pkgDir+"/")) {
+				packages = append(packages, pkg)
+				break
+			}
+		}
+	}
+	return packages, nil
+}
+
+// parseBenchmarks returns the mean ns/op of the benchmarks of a go test
+// output, by package and name.
+func parseBenchmarks(output string) map[string]float64 {
+	sums, counts := map[string]float64{}, map[string]int{}
+	var pkg string
+	for line := range strings.Lines(output) {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "pkg:" {
+			pkg = fields[1]
+			continue
+		}
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		i := slices.Index(fields, "ns/op")
+		if i < 2 {
+			continue
+		}
+		ns, err := strconv.ParseFloat(fields[i-1], 64)
+		if err != nil {
+			continue
+		}
+		name := strings.TrimSpace(pkg + " " + fields[0])
+		sums[name] += ns
+		counts[name]++
+	}
+	means := make(map[string]float64, len(sums))
+	for name, sum := range sums {
+		means[name] = sum / float64(counts[name])
+	}
+	return means
+}
+
+// formatNs formats a ns/op value of a report.
+func formatNs(ns float64) string {
+	if ns == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(ns, 'f', 1, 64)
+}
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	return strings.Join(lines[max(len(lines)-n, 0):], "\n")
+}
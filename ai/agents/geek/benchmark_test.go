@@ -0,0 +1,148 @@
+package geek
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+)
+
+func TestBenchmarkSuiteFromEnv(t *testing.T) {
+	t.Setenv("DIVINESENSE_EVOLUTION_BENCHMARKS", "")
+	if suite := BenchmarkSuiteFromEnv(); suite != nil {
+		t.Errorf("BenchmarkSuiteFromEnv() = %+v without packages, want nil", suite)
+	}
+
+	t.Setenv("DIVINESENSE_EVOLUTION_BENCHMARKS", "./ai/routing, ./store/...=BenchmarkList,")
+	t.Setenv("DIVINESENSE_EVOLUTION_BENCHMARK_THRESHOLD", "5")
+	suite := BenchmarkSuiteFromEnv()
+	if suite == nil || suite.Threshold != 5 || suite.Timeout != defaultBenchmarkTimeout {
+		t.Fatalf("BenchmarkSuiteFromEnv() = %+v", suite)
+	}
+	want := []BenchmarkPackage{{Path: "./ai/routing", Pattern: "."}, {Path: "./store/...", Pattern: "BenchmarkList"}}
+	if len(suite.Packages) != 2 || suite.Packages[0] != want[0] || suite.Packages[1] != want[1] {
+		t.Errorf("Packages = %+v, want %+v", suite.Packages, want)
+	}
+}
+
+func TestParseBenchmarks(t *testing.T) {
+	output := `goos: linux
+pkg: example.com/calc
+BenchmarkSum-8   	 1000000	      1000 ns/op	      16 B/op	       1 allocs/op
+BenchmarkSum-8   	 1000000	      1200 ns/op	      16 B/op	       1 allocs/op
+BenchmarkBroken-8	--- FAIL
+PASS
+ok  	example.com/calc	2.5s
+`
+	got := parseBenchmarks(output)
+	if len(got) != 1 || got["example.com/calc BenchmarkSum-8"] != 1100 {
+		t.Errorf("parseBenchmarks() = %v", got)
+	}
+}
+
+func TestBenchmarkReport(t *testing.T) {
+	report := &BenchmarkReport{Threshold: 10, Results: []BenchmarkResult{
+		{Name: "calc BenchmarkNew", AfterNs: 50},
+		{Name: "calc BenchmarkSlow", BeforeNs: 100, AfterNs: 125},
+		{Name: "calc BenchmarkSum", BeforeNs: 100, AfterNs: 105},
+	}}
+	if regressions := report.Regressions(); len(regressions) != 1 || regressions[0].Name != "calc BenchmarkSlow" {
+		t.Errorf("Regressions() = %+v", regressions)
+	}
+	markdown := report.Markdown()
+	for _, row := range []string{
+		"| `calc BenchmarkNew` | - | 50.0 | n/a |",
+		"| `calc BenchmarkSlow` | 100.0 | 125.0 | +25.0% ⚠️ |",
+		"| `calc BenchmarkSum` | 100.0 | 105.0 | +5.0% |",
+	} {
+		if !strings.Contains(markdown, row) {
+			t.Errorf("Markdown() misses %q:\n%s", row, markdown)
+		}
+	}
+	if !strings.Contains(report.prompt(), "regressions beyond 10%") {
+		t.Error("the PR prompt should flag the regressions")
+	}
+}
+
+func TestBenchmarkSuite_Run(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not installed")
+	}
+	dir := gitCheckout(t)
+	t.Setenv("GOWORK", "off")
+	for name, content := range map[string]string{
+		"go.mod":           "module example.com/bench\n\ngo 1.25\n",
+		"calc/calc.go":     "package calc\n\nfunc Sum(n int) (s int) {\n\tfor i := range n {\n\t\ts += i\n\t}\n\treturn s\n}\n",
+		"calc/sum_test.go": "package calc\n\nimport \"testing\"\n\nfunc BenchmarkSum(b *testing.B) {\n\tfor range b.N {\n\t\tSum(100)\n\t}\n}\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "calc"},
+	} {
+		if _, err := gitOutput(context.Background(), dir, args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	base, _ := gitOutput(context.Background(), dir, "rev-parse", "HEAD")
+	base = strings.TrimSpace(base)
+
+	suite := &BenchmarkSuite{
+		Packages:  []BenchmarkPackage{{Path: "./calc", Pattern: "."}, {Path: "./other/...", Pattern: "."}},
+		Threshold: 10,
+		Count:     1,
+		Benchtime: "10x",
+		Timeout:   time.Minute,
+	}
+	// Changes outside the registered packages are not benchmarked
+	if report, err := suite.Run(context.Background(), dir, base, nil); err != nil || report != nil {
+		t.Fatalf("Run() = %+v, %v without changes", report, err)
+	}
+
+	// A new benchmark of the changes has no baseline
+	newBench := "package calc\n\nimport \"testing\"\n\nfunc BenchmarkDouble(b *testing.B) {\n\tfor range b.N {\n\t\tSum(200)\n\t}\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "calc", "double_test.go"), []byte(newBench), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var events []*agentpkg.EventWithMeta
+	report, err := suite.Run(context.Background(), dir, base, func(_ string, data any) error {
+		events = append(events, data.(*agentpkg.EventWithMeta))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := map[string]BenchmarkResult{}
+	for _, result := range report.Results {
+		results[strings.Fields(result.Name)[1]] = result
+	}
+	var sum, double BenchmarkResult
+	for name, result := range results {
+		switch {
+		case strings.HasPrefix(name, "BenchmarkSum"):
+			sum = result
+		case strings.HasPrefix(name, "BenchmarkDouble"):
+			double = result
+		}
+	}
+	if len(results) != 2 || sum.BeforeNs == 0 || sum.AfterNs == 0 || double.BeforeNs != 0 || double.AfterNs == 0 {
+		t.Fatalf("Results = %+v", report.Results)
+	}
+	if last := events[len(events)-1]; last.Meta.ToolName != benchmarkToolName || last.Meta.Status != "success" || last.Meta.OutputSummary != report.Markdown() {
+		t.Errorf("last event = %+v", last.Meta)
+	}
+	if out, _ := gitOutput(context.Background(), dir, "worktree", "list"); strings.Count(out, "\n") != 1 {
+		t.Errorf("the baseline worktree was not removed:\n%s", out)
+	}
+}
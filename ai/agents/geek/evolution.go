@@ -2,9 +2,11 @@ package geek
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,7 +22,7 @@ import (
 // strict safety constraints. All git operations and PR creation are handled
 // by Claude Code CLI itself - this parrot only provides configuration.
 type EvolutionParrot struct {
-	runner      *agentpkg.CCRunner
+	runner      agentpkg.AgentRunner
 	mode        *EvolutionMode
 	workDir     string
 	sessionID   string
@@ -28,19 +30,21 @@ type EvolutionParrot struct {
 	deviceCtx   string
 	taskID      string
 	initialized bool
+	tasks       TaskQueue   // Backlog of approved tasks (nil disables TaskCommand)
+	task        *QueuedTask // Backlog task run by the last Execute, if any
 }
 
 // NewEvolutionParrot creates a new EvolutionParrot instance.
 // NewEvolutionParrot 创建一个新的 EvolutionParrot 实例。
 //
 // Parameters:
-//   - runner: CCRunner instance for execution
+//   - runner: Agent runner for execution
 //   - sourceDir: DivineSense source code directory
 //   - userID: User ID requesting evolution mode
 //   - sessionID: Session identifier for persistence
 //   - st: Store for user role checking (required for admin verification)
 //   - adminOnly: Whether only admins can use evolution mode (default: from env or true)
-func NewEvolutionParrot(runner *agentpkg.CCRunner, sourceDir string, userID int32, sessionID string, st *store.Store, adminOnly ...bool) (*EvolutionParrot, error) {
+func NewEvolutionParrot(runner agentpkg.AgentRunner, sourceDir string, userID int32, sessionID string, st *store.Store, adminOnly ...bool) (*EvolutionParrot, error) {
 	// Generate task ID if not provided
 	taskID := uuid.New().String()[:8]
 	if sessionID == "" {
@@ -93,7 +97,7 @@ func (p *EvolutionParrot) Execute(
 	userInput string,
 	history []string, // Ignored - Evolution mode manages its own state
 	callback agentpkg.EventCallback,
-) error {
+) (err error) {
 	// Check permissions first
 	// 首先检查权限
 	if err := p.mode.CheckPermission(ctx, p.userID); err != nil {
@@ -115,12 +119,89 @@ func (p *EvolutionParrot) Execute(
 	// TaskInstructions intentionally left empty - hotplex will use empty instructions
 	cfg.TaskInstructions = ""
 
+	// Run an approved task of the backlog, tracing the run on the task
+	// 执行待办队列中已批准的任务，并在任务上记录执行结果
+	prompt := userInput
+	var outcome *TaskOutcome
+	if taskID, ok, cmdErr := parseTaskCommand(userInput); ok {
+		task, err := p.claimTask(ctx, taskID, cmdErr)
+		if errors.Is(err, ErrNoApprovedTask) {
+			p.sendAnswer(callback, "没有可执行的已批准进化任务。")
+			return nil
+		}
+		if err != nil {
+			p.sendError(callback, err.Error())
+			return agentpkg.NewParrotError(p.Name(), "ClaimTask", err)
+		}
+		p.task, prompt, outcome = task, task.prompt(), &TaskOutcome{}
+		callback = trackPRURL(callback, outcome)
+		base, _ := gitOutput(ctx, p.workDir, "rev-parse", "HEAD")
+		defer func() {
+			recordChanges(context.WithoutCancel(ctx), p.workDir, strings.TrimSpace(base), outcome)
+			outcome.Err = err
+			if finishErr := p.tasks.Finish(context.WithoutCancel(ctx), task.ID, outcome); finishErr != nil {
+				slog.Warn("EvolutionParrot: failed to record the task outcome",
+					"evolution_task_id", task.ID,
+					"error", finishErr)
+			}
+		}()
+	}
+
+	// Snapshot the source to test and benchmark the changes of the turn
+	// 记录源码状态，用于检测本轮变更
+	gate, benchmarks := p.mode.TestGate(), p.mode.Benchmarks()
+	var before, baseCommit string
+	if gate != nil || benchmarks != nil {
+		before = sourceState(ctx, p.workDir)
+		head, _ := gitOutput(ctx, p.workDir, "rev-parse", "HEAD")
+		baseCommit = strings.TrimSpace(head)
+	}
+
 	// Execute via CCRunner
 	// 通过 CCRunner 执行
-	if err := p.runner.Execute(ctx, cfg, userInput, callback); err != nil {
+	if err := p.runner.Execute(ctx, cfg, prompt, callback); err != nil {
 		return agentpkg.NewParrotError(p.Name(), "Execute", err)
 	}
 
+	changed := (gate != nil || benchmarks != nil) && sourceState(ctx, p.workDir) != before
+
+	// Test the changes before the CLI may push them and open their PR
+	// 在 CLI 推送并创建 PR 之前运行测试
+	if gate != nil && changed {
+		output, err := gate.Run(ctx, p.workDir, callback)
+		if outcome != nil {
+			outcome.TestStatus, outcome.TestOutput = TaskTestsPassed, output
+			if err != nil {
+				outcome.TestStatus = TaskTestsFailed
+			}
+		}
+		if err != nil {
+			p.sendError(callback, err.Error())
+			return agentpkg.NewParrotError(p.Name(), "TestGate", err)
+		}
+	}
+
+	// Compare the benchmarks of the performance-sensitive packages for the PR
+	// 对比性能敏感包的基准测试，写入 PR 描述
+	var benchmarkPrompt string
+	if benchmarks != nil && changed && baseCommit != "" {
+		benchmarkPrompt = p.runBenchmarks(ctx, benchmarks, baseCommit, callback)
+	}
+
+	if gate != nil && changed {
+		prompt := gate.createPRPrompt()
+		if benchmarkPrompt != "" {
+			prompt += "\n\n" + benchmarkPrompt
+		}
+		if err := p.runner.Execute(ctx, cfg, prompt, callback); err != nil {
+			return agentpkg.NewParrotError(p.Name(), "Execute", err)
+		}
+	} else if benchmarkPrompt != "" {
+		if err := p.runner.Execute(ctx, cfg, benchmarkPrompt, callback); err != nil {
+			return agentpkg.NewParrotError(p.Name(), "Execute", err)
+		}
+	}
+
 	// Mark as initialized after first successful execution
 	// 首次成功执行后标记为已初始化
 	if !p.initialized {
@@ -133,6 +214,52 @@ func (p *EvolutionParrot) Execute(
 	return nil
 }
 
+// runBenchmarks compares the benchmarks of the packages the changes from base
+// touch, warning about the regressions. It returns the prompt adding the
+// comparison to the PR, or "" without comparison.
+func (p *EvolutionParrot) runBenchmarks(ctx context.Context, benchmarks *BenchmarkSuite, base string, callback agentpkg.EventCallback) string {
+	report, err := benchmarks.Run(ctx, p.workDir, base, callback)
+	if err != nil {
+		// Benchmarks inform the review, they do not gate the PR
+		slog.Warn("EvolutionParrot: benchmarks failed",
+			"user_id", p.userID,
+			"error", err)
+		return ""
+	}
+	if report == nil || len(report.Results) == 0 {
+		return ""
+	}
+	if regressions := report.Regressions(); len(regressions) > 0 {
+		var names []string
+		for _, regression := range regressions {
+			delta, _ := regression.Delta()
+			names = append(names, fmt.Sprintf("`%s` %+.1f%%", regression.Name, delta))
+		}
+		p.sendAnswer(callback, fmt.Sprintf("⚠️ 基准测试发现性能回退（超过 %g%%）：%s", report.Threshold, strings.Join(names, "，")))
+	}
+	return report.prompt()
+}
+
+// claimTask claims the task of a TaskCommand from the backlog.
+func (p *EvolutionParrot) claimTask(ctx context.Context, taskID int64, cmdErr error) (*QueuedTask, error) {
+	if cmdErr != nil {
+		return nil, cmdErr
+	}
+	if p.tasks == nil {
+		return nil, errors.New("the evolution task backlog is not available")
+	}
+	return p.tasks.Claim(ctx, taskID, p.userID)
+}
+
+// sendAnswer sends an answer event via callback.
+func (p *EvolutionParrot) sendAnswer(callback agentpkg.EventCallback, message string) {
+	if callback != nil {
+		if err := callback(agentpkg.EventTypeAnswer, message); err != nil {
+			slog.Warn("Failed to send answer to client", "error", err)
+		}
+	}
+}
+
 // sendError sends an error event via callback.
 // sendError 通过回调发送错误事件。
 func (p *EvolutionParrot) sendError(callback agentpkg.EventCallback, message string) {
@@ -158,6 +285,18 @@ func (p *EvolutionParrot) GetSessionID() string {
 	return p.sessionID
 }
 
+// SetTaskQueue sets the backlog of approved tasks TaskCommand runs.
+// SetTaskQueue 设置 TaskCommand 执行的已批准任务队列。
+func (p *EvolutionParrot) SetTaskQueue(tasks TaskQueue) {
+	p.tasks = tasks
+}
+
+// Task returns the backlog task run by the last Execute, or nil.
+// Task 返回上次 Execute 执行的待办任务。
+func (p *EvolutionParrot) Task() *QueuedTask {
+	return p.task
+}
+
 // GetTaskID returns the evolution task ID.
 // GetTaskID 返回进化任务 ID。
 func (p *EvolutionParrot) GetTaskID() string {
@@ -231,7 +370,7 @@ func (p *EvolutionParrot) GetSessionStats() *agentpkg.NormalSessionStats {
 		StartTime:            stats.StartTime,
 		EndTime:              time.Now(),
 		AgentType:            "evolution",
-		ModelUsed:            "",
+		ModelUsed:            p.runner.Model(p.sessionID),
 		PromptTokens:         int(stats.InputTokens),
 		CompletionTokens:     int(stats.OutputTokens),
 		TotalTokens:          int(stats.InputTokens + stats.OutputTokens),
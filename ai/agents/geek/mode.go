@@ -97,16 +97,19 @@ func (m *GeekMode) BuildContextPrompt(cfg *agentpkg.CCRunnerConfig) string {
 
 // GetWorkDir returns the user-specific sandbox directory.
 func (m *GeekMode) GetWorkDir(userID int32) string {
-	base := m.baseWorkDir
-	if base == "" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			base = "/tmp"
-		} else {
-			base = filepath.Join(homeDir, ".divinesense", "claude")
-		}
+	return filepath.Join(m.workspaceRoot(), fmt.Sprintf("user_%d", userID))
+}
+
+// workspaceRoot returns the directory holding the user sandboxes.
+func (m *GeekMode) workspaceRoot() string {
+	if m.baseWorkDir != "" {
+		return m.baseWorkDir
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "/tmp"
 	}
-	return filepath.Join(base, fmt.Sprintf("user_%d", userID))
+	return filepath.Join(homeDir, ".divinesense", "claude")
 }
 
 // CheckPermission validates that the user can use Geek Mode.
@@ -133,25 +136,39 @@ type EvolutionMode struct {
 	sourceDir  string
 	adminOnly  bool
 	envEnabled bool
-	store      *store.Store // For user role checking
+	store      *store.Store    // For user role checking
+	testGate   *TestGate       // Tests the changes before their PR (nil disables it)
+	benchmarks *BenchmarkSuite // Benchmarks the changes for their PR (nil disables it)
 }
 
 // EvolutionModeConfig holds configuration for EvolutionMode.
 // EvolutionModeConfig 保存 EvolutionMode 的配置。
 type EvolutionModeConfig struct {
-	SourceDir string       // Project root directory for evolution
-	AdminOnly bool         // Whether only admins can use evolution mode
-	Store     *store.Store // Store for user role checking (optional, skips admin check if nil)
+	SourceDir  string          // Project root directory for evolution
+	AdminOnly  bool            // Whether only admins can use evolution mode
+	Store      *store.Store    // Store for user role checking (optional, skips admin check if nil)
+	TestGate   *TestGate       // Test gate of the changes (optional, defaults to TestGateFromEnv)
+	Benchmarks *BenchmarkSuite // Benchmarks of the changes (optional, defaults to BenchmarkSuiteFromEnv)
 }
 
 // NewEvolutionMode creates a new EvolutionMode instance.
 // NewEvolutionMode 创建一个新的 EvolutionMode 实例。
 func NewEvolutionMode(cfg *EvolutionModeConfig) *EvolutionMode {
+	testGate := cfg.TestGate
+	if testGate == nil {
+		testGate = TestGateFromEnv()
+	}
+	benchmarks := cfg.Benchmarks
+	if benchmarks == nil {
+		benchmarks = BenchmarkSuiteFromEnv()
+	}
 	return &EvolutionMode{
 		sourceDir:  cfg.SourceDir,
 		adminOnly:  cfg.AdminOnly,
 		envEnabled: os.Getenv("DIVINESENSE_EVOLUTION_ENABLED") == "true",
 		store:      cfg.Store,
+		testGate:   testGate,
+		benchmarks: benchmarks,
 	}
 }
 
@@ -198,7 +215,25 @@ You are evolving DivineSense's source code through a structured, interactive pro
 - Follow @.claude/rules/git-workflow.md
 - All changes via PR
 - Always confirm before execution
-`
+` + m.testGatePrompt()
+}
+
+// testGatePrompt returns the rules of the test gate, if enabled.
+func (m *EvolutionMode) testGatePrompt() string {
+	if m.testGate == nil {
+		return ""
+	}
+	return m.testGate.prompt()
+}
+
+// TestGate returns the test gate of the changes, or nil if disabled.
+func (m *EvolutionMode) TestGate() *TestGate {
+	return m.testGate
+}
+
+// Benchmarks returns the benchmark suite of the changes, or nil if disabled.
+func (m *EvolutionMode) Benchmarks() *BenchmarkSuite {
+	return m.benchmarks
 }
 
 // GetWorkDir returns the source code directory for evolution.
@@ -18,17 +18,21 @@ import (
 // using the unified CCRunner + GeekMode architecture.
 // 它提供 Claude Code CLI 的直接访问，不经过任何 LLM 处理，使用统一的 CCRunner + GeekMode 架构。
 type GeekParrot struct {
-	runner    *agentpkg.CCRunner
+	runner    agentpkg.AgentRunner
 	mode      *GeekMode
 	sessionID string
 	userID    int32
 	workDir   string
 	deviceCtx string
+	snippets  SnippetExporter      // Optional: the user's snippet library
+	tools     *agentpkg.ToolPolicy // Optional: the tools of the user's workspace
+	mcp       []agentpkg.MCPServer // Optional: the MCP servers of the user
+	guard     func() error         // Optional: rejects writes, e.g. on a full workspace
 }
 
 // NewGeekParrot creates a new GeekParrot instance.
 // NewGeekParrot 创建一个新的 GeekParrot 实例。
-func NewGeekParrot(runner *agentpkg.CCRunner, sourceDir string, userID int32, sessionID string) (*GeekParrot, error) {
+func NewGeekParrot(runner agentpkg.AgentRunner, sourceDir string, userID int32, sessionID string) (*GeekParrot, error) {
 
 	// Create GeekMode
 	// Geek Mode uses its own sandbox directory, so we pass empty string to let it
@@ -58,6 +62,27 @@ func (p *GeekParrot) SetDeviceContext(contextJson string) {
 	p.deviceCtx = contextJson
 }
 
+// SetToolPolicy restricts the Claude Code tools of the parrot to the policy
+// of the user's workspace.
+// SetToolPolicy 将鹦鹉可用的 Claude Code 工具限制为用户工作区的策略。
+func (p *GeekParrot) SetToolPolicy(policy *agentpkg.ToolPolicy) {
+	p.tools = policy
+}
+
+// SetMCPServers gives the Claude Code CLI of the parrot the MCP servers the
+// user registered.
+// SetMCPServers 为鹦鹉的 Claude Code CLI 提供用户注册的 MCP 服务器。
+func (p *GeekParrot) SetMCPServers(servers []agentpkg.MCPServer) {
+	p.mcp = servers
+}
+
+// SetWriteGuard rejects the writes of the Claude Code CLI while guard fails,
+// e.g. while the user's workspace is over its disk quota.
+// SetWriteGuard 在 guard 返回错误时拒绝 Claude Code CLI 的写操作（如工作区超出磁盘配额）。
+func (p *GeekParrot) SetWriteGuard(guard func() error) {
+	p.guard = guard
+}
+
 // Name returns the name of the parrot.
 // Name 返回鹦鹉名称。
 func (p *GeekParrot) Name() string {
@@ -83,6 +108,11 @@ func (p *GeekParrot) Execute(
 		return agentpkg.NewParrotError(p.Name(), "CheckPermission", err)
 	}
 
+	if err := EnsureWorkspace(p.workDir); err != nil {
+		p.sendError(callback, "Failed to prepare the workspace")
+		return agentpkg.NewParrotError(p.Name(), "EnsureWorkspace", err)
+	}
+
 	// Build config for CCRunner
 	cfg := &agentpkg.CCRunnerConfig{
 		Mode:           p.mode.Name(),
@@ -91,8 +121,11 @@ func (p *GeekParrot) Execute(
 		UserID:         p.userID,
 		DeviceContext:  p.deviceCtx,
 		PermissionMode: "bypassPermissions",
+		Tools:          p.tools,
+		MCPServers:     p.mcp,
+		WriteGuard:     p.guard,
 	}
-	cfg.TaskInstructions = p.mode.BuildContextPrompt(cfg)
+	cfg.TaskInstructions = p.mode.BuildContextPrompt(cfg) + p.syncSnippets(ctx)
 
 	// Execute via CCRunner
 	if err := p.runner.Execute(ctx, cfg, userInput, callback); err != nil {
@@ -196,7 +229,7 @@ func (p *GeekParrot) GetSessionStats() *agentpkg.NormalSessionStats {
 		StartTime:            stats.StartTime,
 		EndTime:              time.Now(),
 		AgentType:            "geek",
-		ModelUsed:            "",
+		ModelUsed:            p.runner.Model(p.sessionID),
 		PromptTokens:         int(stats.InputTokens),
 		CompletionTokens:     int(stats.OutputTokens),
 		TotalTokens:          int(stats.InputTokens + stats.OutputTokens),
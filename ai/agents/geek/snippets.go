@@ -0,0 +1,52 @@
+package geek
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+)
+
+// SnippetDir is the workspace directory holding the user's snippet library.
+const SnippetDir = ".snippets"
+
+// SnippetExporter writes the code snippets of a user into a directory.
+type SnippetExporter interface {
+	Export(ctx context.Context, userID int32, dir string) (int, error)
+}
+
+// snippetLibraryPrompt tells the CLI to look for reusable code in the
+// snippet library before writing new code.
+const snippetLibraryPrompt = `
+
+# Snippet Library
+
+The user's code snippets (memos tagged #snippet) are in ` + SnippetDir + `/, one file per code block.
+` + SnippetDir + `/INDEX.md lists each file with its language, the symbols it defines and its memo UID.
+
+Before writing new code:
+- Search the library first: Grep INDEX.md for function, class or type names, or a regex over ` + SnippetDir + `/
+- Reuse or adapt a matching snippet instead of starting from scratch, and mention its memo UID
+- Never edit ` + SnippetDir + `/: it is regenerated before every request
+`
+
+// SetSnippets gives the parrot the user's snippet library, refreshed in
+// the workspace before every request.
+func (p *GeekParrot) SetSnippets(snippets SnippetExporter) {
+	p.snippets = snippets
+}
+
+// syncSnippets exports the snippet library into the workspace and returns
+// the instructions to append to the task instructions, empty without a
+// library. A failed export leaves the previous copy in place.
+func (p *GeekParrot) syncSnippets(ctx context.Context) string {
+	if p.snippets == nil {
+		return ""
+	}
+	count, err := p.snippets.Export(ctx, p.userID, filepath.Join(p.workDir, SnippetDir))
+	if err != nil {
+		slog.Warn("GeekParrot: failed to export snippet library", "user_id", p.userID, "error", err)
+	} else {
+		slog.Debug("GeekParrot: snippet library exported", "user_id", p.userID, "count", count)
+	}
+	return snippetLibraryPrompt
+}
@@ -0,0 +1,42 @@
+package geek
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type fakeSnippetExporter struct {
+	dir string
+	err error
+}
+
+func (e *fakeSnippetExporter) Export(_ context.Context, _ int32, dir string) (int, error) {
+	e.dir = dir
+	return 1, e.err
+}
+
+// TestSyncSnippets tests the export of the snippet library into the workspace.
+func TestSyncSnippets(t *testing.T) {
+	parrot := &GeekParrot{userID: 1, workDir: t.TempDir()}
+	if prompt := parrot.syncSnippets(context.Background()); prompt != "" {
+		t.Errorf("syncSnippets() without library = %q, want empty", prompt)
+	}
+
+	exporter := &fakeSnippetExporter{}
+	parrot.SetSnippets(exporter)
+	prompt := parrot.syncSnippets(context.Background())
+	if want := filepath.Join(parrot.workDir, SnippetDir); exporter.dir != want {
+		t.Errorf("exported to %q, want %q", exporter.dir, want)
+	}
+	if !strings.Contains(prompt, SnippetDir+"/INDEX.md") {
+		t.Errorf("syncSnippets() prompt does not mention the index: %q", prompt)
+	}
+
+	exporter.err = errors.New("database down")
+	if parrot.syncSnippets(context.Background()) != prompt {
+		t.Error("a failed export should keep the instructions for the previous copy")
+	}
+}
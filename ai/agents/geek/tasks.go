@@ -0,0 +1,124 @@
+package geek
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+)
+
+// TaskCommand is the Evolution chat command running an approved task of the
+// backlog: "/task" runs the oldest one, "/task <id>" a given one.
+const TaskCommand = "/task"
+
+// ErrNoApprovedTask is returned by TaskQueue.Claim when no approved task
+// matches.
+var ErrNoApprovedTask = errors.New("no approved evolution task")
+
+// Test statuses of a TaskOutcome.
+const (
+	TaskTestsPassed = "passed"
+	TaskTestsFailed = "failed"
+)
+
+// prURLPattern matches the URLs of the pull requests the CLI creates.
+var prURLPattern = regexp.MustCompile(`https://github\.com/[\w.-]+/[\w.-]+/pull/\d+`)
+
+// QueuedTask is an approved Evolution task claimed for a run.
+type QueuedTask struct {
+	ID          int64
+	Title       string
+	Description string
+}
+
+// TaskOutcome is the trace of the run of a task.
+type TaskOutcome struct {
+	BaseCommit string // HEAD before the run
+	HeadCommit string // HEAD after the run
+	DiffStat   string // git diff --stat of the run
+	PRURL      string // Pull request created by the CLI, if any
+	TestStatus string // TaskTestsPassed, TaskTestsFailed, or "" without a test gate run
+	TestOutput string // Last lines of the test suite output
+	Err        error  // Failure of the run, nil if it succeeded
+}
+
+// TaskQueue is the backlog of admin-approved Evolution tasks.
+type TaskQueue interface {
+	// Claim takes an approved task for a run by the user: the given one, or
+	// the oldest with taskID 0. It returns ErrNoApprovedTask if none matches.
+	Claim(ctx context.Context, taskID int64, userID int32) (*QueuedTask, error)
+	// Finish records the outcome of the run of a claimed task.
+	Finish(ctx context.Context, taskID int64, outcome *TaskOutcome) error
+}
+
+// parseTaskCommand parses a TaskCommand, returning the task ID (0 for the
+// oldest task). ok is false for other inputs.
+func parseTaskCommand(input string) (taskID int64, ok bool, err error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 || fields[0] != TaskCommand {
+		return 0, false, nil
+	}
+	switch len(fields) {
+	case 1:
+		return 0, true, nil
+	case 2:
+		taskID, err = strconv.ParseInt(strings.TrimPrefix(fields[1], "#"), 10, 64)
+		if err == nil && taskID > 0 {
+			return taskID, true, nil
+		}
+	}
+	return 0, true, fmt.Errorf("usage: %s [task id]", TaskCommand)
+}
+
+// prompt returns the prompt running the task.
+func (t *QueuedTask) prompt() string {
+	return fmt.Sprintf(`Run the approved evolution task #%d. Admins reviewed and approved it: execute it without waiting for a confirmation of the plan.
+
+# %s
+
+%s`, t.ID, t.Title, t.Description)
+}
+
+// trackPRURL returns a callback recording the last pull request URL of the
+// events of the CLI in outcome.
+func trackPRURL(callback agentpkg.EventCallback, outcome *TaskOutcome) agentpkg.EventCallback {
+	return func(eventType string, data any) error {
+		var text string
+		switch data := data.(type) {
+		case string:
+			text = data
+		case *agentpkg.EventWithMeta:
+			text = data.EventData
+		}
+		if urls := prURLPattern.FindAllString(text, -1); len(urls) > 0 {
+			outcome.PRURL = urls[len(urls)-1]
+		}
+		return callback(eventType, data)
+	}
+}
+
+// recordChanges records the commits and the diff of a run from base.
+func recordChanges(ctx context.Context, dir, base string, outcome *TaskOutcome) {
+	outcome.BaseCommit = base
+	if head, err := gitOutput(ctx, dir, "rev-parse", "HEAD"); err == nil {
+		outcome.HeadCommit = strings.TrimSpace(head)
+	}
+	if base == "" {
+		return
+	}
+	stat, err := gitOutput(ctx, dir, "diff", "--stat", base)
+	if err != nil {
+		return
+	}
+	// New files left uncommitted are not in the diff
+	if untracked, err := gitOutput(ctx, dir, "ls-files", "--others", "--exclude-standard"); err == nil {
+		for file := range strings.Lines(untracked) {
+			stat += " " + strings.TrimSuffix(file, "\n") + " (untracked)\n"
+		}
+	}
+	outcome.DiffStat = strings.TrimRight(stat, "\n")
+}
@@ -0,0 +1,123 @@
+package geek
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+)
+
+// fakeTaskQueue is a TaskQueue of one approved task.
+type fakeTaskQueue struct {
+	task    *QueuedTask
+	outcome *TaskOutcome
+}
+
+func (q *fakeTaskQueue) Claim(_ context.Context, taskID int64, _ int32) (*QueuedTask, error) {
+	if q.task == nil || (taskID != 0 && taskID != q.task.ID) {
+		return nil, ErrNoApprovedTask
+	}
+	task := q.task
+	q.task = nil
+	return task, nil
+}
+
+func (q *fakeTaskQueue) Finish(_ context.Context, _ int64, outcome *TaskOutcome) error {
+	q.outcome = outcome
+	return nil
+}
+
+// prRunner is an editingRunner announcing the PR it creates.
+type prRunner struct {
+	editingRunner
+}
+
+func (r *prRunner) Execute(ctx context.Context, cfg *agentpkg.CCRunnerConfig, prompt string, callback agentpkg.EventCallback) error {
+	if err := r.editingRunner.Execute(ctx, cfg, prompt, callback); err != nil {
+		return err
+	}
+	if len(r.prompts) == 2 {
+		return callback(agentpkg.EventTypeAnswer, &agentpkg.EventWithMeta{
+			EventType: agentpkg.EventTypeAnswer,
+			EventData: "Created https://github.com/hrygo/divinesense/pull/42.",
+		})
+	}
+	return nil
+}
+
+func TestParseTaskCommand(t *testing.T) {
+	for _, tt := range []struct {
+		input   string
+		taskID  int64
+		ok      bool
+		wantErr bool
+	}{
+		{input: "/task", ok: true},
+		{input: " /task #12 ", taskID: 12, ok: true},
+		{input: "/task twelve", ok: true, wantErr: true},
+		{input: "/tasks"},
+		{input: "run /task 1"},
+	} {
+		taskID, ok, err := parseTaskCommand(tt.input)
+		if taskID != tt.taskID || ok != tt.ok || (err != nil) != tt.wantErr {
+			t.Errorf("parseTaskCommand(%q) = %d, %v, %v", tt.input, taskID, ok, err)
+		}
+	}
+}
+
+func TestEvolutionParrot_RunsQueuedTask(t *testing.T) {
+	t.Setenv("DIVINESENSE_EVOLUTION_ENABLED", "true")
+	t.Setenv("DIVINESENSE_EVOLUTION_TEST_COMMAND", "echo 3 passed")
+	dir := gitCheckout(t)
+	runner := &prRunner{editingRunner{dir: dir}}
+	parrot, err := NewEvolutionParrot(runner, dir, 1, "session", nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	noop := func(string, any) error { return nil }
+
+	// Without a backlog, the command is refused
+	if err := parrot.Execute(context.Background(), TaskCommand, nil, noop); err == nil {
+		t.Fatal("Execute(/task) should fail without a task queue")
+	}
+
+	queue := &fakeTaskQueue{task: &QueuedTask{ID: 7, Title: "Add a feature", Description: "Add feature.go"}}
+	parrot.SetTaskQueue(queue)
+	if err := parrot.Execute(context.Background(), "/task 8", nil, noop); err != nil || len(runner.prompts) != 0 {
+		t.Fatalf("Execute(/task 8) = %v with prompts %q, want no run", err, runner.prompts)
+	}
+
+	if err := parrot.Execute(context.Background(), TaskCommand, nil, noop); err != nil {
+		t.Fatal(err)
+	}
+	if parrot.Task() == nil || parrot.Task().ID != 7 {
+		t.Fatalf("Task() = %+v, want task 7", parrot.Task())
+	}
+	if len(runner.prompts) != 2 || !strings.Contains(runner.prompts[0], "Add feature.go") {
+		t.Fatalf("prompts = %q, want the task then the PR", runner.prompts)
+	}
+
+	outcome := queue.outcome
+	if outcome == nil {
+		t.Fatal("the outcome of the task was not recorded")
+	}
+	if outcome.Err != nil || outcome.TestStatus != TaskTestsPassed || outcome.TestOutput != "3 passed" {
+		t.Errorf("outcome = %+v", outcome)
+	}
+	if outcome.PRURL != "https://github.com/hrygo/divinesense/pull/42" {
+		t.Errorf("PRURL = %q", outcome.PRURL)
+	}
+	if outcome.BaseCommit == "" || outcome.BaseCommit != outcome.HeadCommit || !strings.Contains(outcome.DiffStat, "feature.go") {
+		t.Errorf("changes = %q..%q: %q", outcome.BaseCommit, outcome.HeadCommit, outcome.DiffStat)
+	}
+
+	// Once run, the task is no longer queued
+	if err := parrot.Execute(context.Background(), TaskCommand, nil, noop); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := queue.Claim(context.Background(), 0, 1); !errors.Is(err, ErrNoApprovedTask) {
+		t.Errorf("Claim() = %v, want ErrNoApprovedTask", err)
+	}
+}
@@ -0,0 +1,148 @@
+package geek
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+)
+
+// ErrTestsFailed is returned for Evolution changes failing the test gate.
+var ErrTestsFailed = errors.New("test suite failed")
+
+const (
+	// testGateToolName is the tool name of the test gate events.
+	testGateToolName = "TestGate"
+	// defaultTestGateTimeout bounds the test suite runs.
+	defaultTestGateTimeout = 10 * time.Minute
+	// testGateTailLines is the number of output lines kept for the summary.
+	testGateTailLines = 20
+)
+
+// TestGate runs the test suite of the source dir on the changes of an
+// Evolution turn, before the CLI may push them and open their PR.
+type TestGate struct {
+	Command string        // Shell command, e.g. "make test"
+	Timeout time.Duration // Bound of a run
+}
+
+// TestGateFromEnv returns the gate configured by
+// DIVINESENSE_EVOLUTION_TEST_COMMAND and
+// DIVINESENSE_EVOLUTION_TEST_TIMEOUT_SECONDS, or nil if no command is set.
+func TestGateFromEnv() *TestGate {
+	command := strings.TrimSpace(os.Getenv("DIVINESENSE_EVOLUTION_TEST_COMMAND"))
+	if command == "" {
+		return nil
+	}
+	timeout := defaultTestGateTimeout
+	if seconds, err := strconv.Atoi(os.Getenv("DIVINESENSE_EVOLUTION_TEST_TIMEOUT_SECONDS")); err == nil && seconds > 0 {
+		timeout = time.Duration(seconds) * time.Second
+	}
+	return &TestGate{Command: command, Timeout: timeout}
+}
+
+// Run runs the test suite in dir, streaming its output as tool_result events.
+// It returns the last lines of the output, and ErrTestsFailed if it fails.
+func (g *TestGate) Run(ctx context.Context, dir string, callback agentpkg.EventCallback) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.Timeout)
+	defer cancel()
+	start := time.Now()
+	send := agentpkg.SafeCallback(callback)
+	sendEvent := func(eventType, data string, meta *agentpkg.EventMeta) {
+		if send != nil {
+			meta.ToolName = testGateToolName
+			meta.TotalDurationMs = time.Since(start).Milliseconds()
+			send(eventType, &agentpkg.EventWithMeta{EventType: eventType, EventData: data, Meta: meta})
+		}
+	}
+	sendEvent(agentpkg.EventTypeToolUse, g.Command, &agentpkg.EventMeta{Status: "running", InputSummary: g.Command})
+
+	pr, pw := io.Pipe()
+	cmd := exec.CommandContext(ctx, "sh", "-c", g.Command)
+	cmd.Dir = dir
+	cmd.Stdout, cmd.Stderr = pw, pw
+	// Background processes of the suite must not keep the output open
+	cmd.WaitDelay = 5 * time.Second
+
+	var tail []string
+	scanned := make(chan struct{})
+	go func() {
+		defer close(scanned)
+		scanner := bufio.NewScanner(pr)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if tail = append(tail, line); len(tail) > testGateTailLines {
+				tail = tail[1:]
+			}
+			sendEvent(agentpkg.EventTypeToolResult, line, &agentpkg.EventMeta{Status: "running"})
+		}
+		// Drain what the scanner gave up on, so the suite never blocks
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+	err := cmd.Run()
+	pw.Close()
+	<-scanned
+
+	summary := strings.Join(tail, "\n")
+	meta := &agentpkg.EventMeta{DurationMs: time.Since(start).Milliseconds(), OutputSummary: summary}
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %s", g.Timeout)
+		}
+		meta.Status, meta.ErrorMsg = "error", err.Error()
+		sendEvent(agentpkg.EventTypeToolResult, fmt.Sprintf("`%s` failed: %v", g.Command, err), meta)
+		return summary, fmt.Errorf("%w: %s: %v", ErrTestsFailed, g.Command, err)
+	}
+	meta.Status = "success"
+	sendEvent(agentpkg.EventTypeToolResult, fmt.Sprintf("`%s` passed", g.Command), meta)
+	return summary, nil
+}
+
+// sourceState returns the commit and the uncommitted changes of a git
+// checkout, which change when a turn modifies the source.
+func sourceState(ctx context.Context, dir string) string {
+	var state strings.Builder
+	for _, args := range [][]string{{"rev-parse", "HEAD"}, {"status", "--porcelain"}} {
+		out, err := gitOutput(ctx, dir, args...)
+		if err != nil {
+			return ""
+		}
+		state.WriteString(out)
+	}
+	return state.String()
+}
+
+// gitOutput runs a git command in dir and returns its output.
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// prompt returns the rules of the gate for the Evolution system prompt.
+func (g *TestGate) prompt() string {
+	return fmt.Sprintf(`
+## Test Gate
+
+DivineSense runs `+"`%s`"+` after every turn that changes the source.
+
+- Commit your changes on the feature branch, but do NOT push or create the PR
+- Tell the user the changes are ready for the test gate and end your turn
+- You will be asked to push and create the PR once the tests pass
+`, g.Command)
+}
+
+// createPRPrompt is the prompt sent to the CLI once the tests pass.
+func (g *TestGate) createPRPrompt() string {
+	return fmt.Sprintf("The test gate passed (`%s`). Push the branch and create the PR now, following @.claude/rules/git-workflow.md.", g.Command)
+}
@@ -0,0 +1,151 @@
+package geek
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+)
+
+// editingRunner is an AgentRunner recording its prompts, whose first turn
+// edits the source.
+type editingRunner struct {
+	agentpkg.AgentRunner
+	dir     string
+	prompts []string
+}
+
+func (r *editingRunner) Execute(_ context.Context, _ *agentpkg.CCRunnerConfig, prompt string, _ agentpkg.EventCallback) error {
+	r.prompts = append(r.prompts, prompt)
+	if len(r.prompts) == 1 {
+		return os.WriteFile(filepath.Join(r.dir, "feature.go"), []byte("package feature\n"), 0o644)
+	}
+	return nil
+}
+
+// gitCheckout returns a git checkout with a commit.
+func gitCheckout(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the test suites are shell commands")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestTestGateFromEnv(t *testing.T) {
+	t.Setenv("DIVINESENSE_EVOLUTION_TEST_COMMAND", "")
+	if gate := TestGateFromEnv(); gate != nil {
+		t.Errorf("TestGateFromEnv() = %+v without a command, want nil", gate)
+	}
+
+	t.Setenv("DIVINESENSE_EVOLUTION_TEST_COMMAND", "make test")
+	t.Setenv("DIVINESENSE_EVOLUTION_TEST_TIMEOUT_SECONDS", "60")
+	gate := TestGateFromEnv()
+	if gate == nil || gate.Command != "make test" || gate.Timeout != time.Minute {
+		t.Fatalf("TestGateFromEnv() = %+v", gate)
+	}
+	mode := NewEvolutionMode(&EvolutionModeConfig{SourceDir: "."})
+	if !strings.Contains(mode.BaseSystemPrompt(), "do NOT push or create the PR") {
+		t.Error("the system prompt should hold the PR back for the test gate")
+	}
+}
+
+func TestTestGate_Run(t *testing.T) {
+	dir := gitCheckout(t)
+
+	var events []*agentpkg.EventWithMeta
+	callback := func(_ string, data any) error {
+		events = append(events, data.(*agentpkg.EventWithMeta))
+		return nil
+	}
+	gate := &TestGate{Command: "echo ok; echo done", Timeout: time.Minute}
+	if summary, err := gate.Run(context.Background(), dir, callback); err != nil || summary != "ok\ndone" {
+		t.Fatalf("Run() = %q, %v", summary, err)
+	}
+	var lines []string
+	for _, event := range events {
+		if event.Meta.ToolName != testGateToolName {
+			t.Errorf("event of tool %q", event.Meta.ToolName)
+		}
+		if event.Meta.Status == "running" && event.EventType == agentpkg.EventTypeToolResult {
+			lines = append(lines, event.EventData)
+		}
+	}
+	if strings.Join(lines, ",") != "ok,done" {
+		t.Errorf("streamed lines = %v", lines)
+	}
+	if last := events[len(events)-1]; last.Meta.Status != "success" || last.Meta.OutputSummary != "ok\ndone" {
+		t.Errorf("last event = %+v", last.Meta)
+	}
+
+	events = nil
+	gate = &TestGate{Command: "echo FAIL; exit 1", Timeout: time.Minute}
+	if _, err := gate.Run(context.Background(), dir, callback); !errors.Is(err, ErrTestsFailed) {
+		t.Fatalf("Run() error = %v, want ErrTestsFailed", err)
+	}
+	if last := events[len(events)-1]; last.Meta.Status != "error" || last.Meta.OutputSummary != "FAIL" {
+		t.Errorf("last event = %+v", last.Meta)
+	}
+
+	gate = &TestGate{Command: "exec sleep 5", Timeout: 100 * time.Millisecond}
+	if _, err := gate.Run(context.Background(), dir, nil); !errors.Is(err, ErrTestsFailed) || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Run() error = %v, want a timeout", err)
+	}
+}
+
+func TestEvolutionParrot_TestGate(t *testing.T) {
+	t.Setenv("DIVINESENSE_EVOLUTION_ENABLED", "true")
+	for _, tt := range []struct {
+		command string
+		prompts int // Turns of the CLI: the request, then the PR once the tests pass
+		wantErr error
+	}{
+		{command: "true", prompts: 2},
+		{command: "false", prompts: 1, wantErr: ErrTestsFailed},
+	} {
+		t.Run(tt.command, func(t *testing.T) {
+			t.Setenv("DIVINESENSE_EVOLUTION_TEST_COMMAND", tt.command)
+			dir := gitCheckout(t)
+			runner := &editingRunner{dir: dir}
+			parrot, err := NewEvolutionParrot(runner, dir, 1, "session", nil, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = parrot.Execute(context.Background(), "add a feature", nil, func(string, any) error { return nil })
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(runner.prompts) != tt.prompts {
+				t.Fatalf("prompts = %q, want %d turns", runner.prompts, tt.prompts)
+			}
+
+			// Turns not changing the source are not tested
+			err = parrot.Execute(context.Background(), "explain the plan", nil, func(string, any) error { return nil })
+			if err != nil || len(runner.prompts) != tt.prompts+1 {
+				t.Errorf("Execute() = %v with prompts %q", err, runner.prompts)
+			}
+		})
+	}
+}
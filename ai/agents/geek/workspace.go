@@ -0,0 +1,84 @@
+package geek
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+)
+
+// WorkspaceDirPerm is the mode of Geek Mode workspaces and of the CLI
+// transcript directory. Workspaces can contain sensitive code, so only the
+// server user may access them, whatever its umask.
+//
+// For encryption at rest, point DIVINESENSE_CLAUDE_CODE_WORKDIR at an
+// encrypted volume (e.g. a LUKS or fscrypt managed directory).
+const WorkspaceDirPerm os.FileMode = 0o700
+
+// WorkspaceRemediation is the command that restricts the permissions of
+// existing workspaces.
+const WorkspaceRemediation = "divinesense secure-workspaces"
+
+// EnsureWorkspace creates the workspace dir with WorkspaceDirPerm, or
+// restricts the permissions of an existing one.
+func EnsureWorkspace(dir string) error {
+	if err := os.MkdirAll(dir, WorkspaceDirPerm); err != nil {
+		return fmt.Errorf("create workspace %s: %w", dir, err)
+	}
+	// MkdirAll leaves existing directories alone and is subject to the umask.
+	if err := os.Chmod(dir, WorkspaceDirPerm); err != nil {
+		return fmt.Errorf("restrict permissions of workspace %s: %w", dir, err)
+	}
+	return nil
+}
+
+// WorkspaceDirs returns the existing directories holding Geek Mode data: the
+// user workspaces and the CLI transcript directory.
+func WorkspaceDirs() []string {
+	dirs, _ := filepath.Glob(filepath.Join(NewGeekMode("").workspaceRoot(), "user_*"))
+	if projects := agentpkg.ClaudeProjectsDir(); projects != "" {
+		if info, err := os.Stat(projects); err == nil && info.IsDir() {
+			dirs = append(dirs, projects)
+		}
+	}
+	return dirs
+}
+
+// CheckWorkspacePermissions verifies that no workspace is accessible to other
+// users than the server user.
+func CheckWorkspacePermissions() error {
+	var exposed []string
+	for _, dir := range WorkspaceDirs() {
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		if info.Mode().Perm()&^WorkspaceDirPerm != 0 {
+			exposed = append(exposed, fmt.Sprintf("%s (%s)", dir, info.Mode().Perm()))
+		}
+	}
+	if len(exposed) > 0 {
+		return fmt.Errorf("Geek Mode workspaces are accessible to other users: %s; run `%s` to restrict them to %s",
+			strings.Join(exposed, ", "), WorkspaceRemediation, WorkspaceDirPerm)
+	}
+	return nil
+}
+
+// SecureWorkspaces restricts the permissions of all workspaces to
+// WorkspaceDirPerm and returns the directories it changed.
+func SecureWorkspaces() ([]string, error) {
+	var changed []string
+	for _, dir := range WorkspaceDirs() {
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() || info.Mode().Perm() == WorkspaceDirPerm {
+			continue
+		}
+		if err := os.Chmod(dir, WorkspaceDirPerm); err != nil {
+			return changed, fmt.Errorf("restrict permissions of %s: %w", dir, err)
+		}
+		changed = append(changed, dir)
+	}
+	return changed, nil
+}
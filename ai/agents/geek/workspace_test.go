@@ -0,0 +1,50 @@
+package geek
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestWorkspacePermissions tests the workspace permission check and remediation.
+func TestWorkspacePermissions(t *testing.T) {
+	root, config := t.TempDir(), t.TempDir()
+	t.Setenv("DIVINESENSE_CLAUDE_CODE_WORKDIR", root)
+	t.Setenv("CLAUDE_CONFIG_DIR", config)
+
+	secured := filepath.Join(root, "user_1")
+	if err := EnsureWorkspace(secured); err != nil {
+		t.Fatalf("EnsureWorkspace() error = %v", err)
+	}
+	if info, _ := os.Stat(secured); info.Mode().Perm() != WorkspaceDirPerm {
+		t.Errorf("new workspace mode = %v, want %v", info.Mode().Perm(), WorkspaceDirPerm)
+	}
+	if err := os.Mkdir(filepath.Join(config, "projects"), WorkspaceDirPerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckWorkspacePermissions(); err != nil {
+		t.Errorf("CheckWorkspacePermissions() error = %v, want nil", err)
+	}
+
+	exposed := filepath.Join(root, "user_2")
+	if err := os.Mkdir(exposed, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(exposed, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckWorkspacePermissions(); err == nil {
+		t.Error("CheckWorkspacePermissions() error = nil, want an error for an exposed workspace")
+	}
+
+	changed, err := SecureWorkspaces()
+	if err != nil {
+		t.Fatalf("SecureWorkspaces() error = %v", err)
+	}
+	if len(changed) != 1 || changed[0] != exposed {
+		t.Errorf("SecureWorkspaces() changed %v, want [%s]", changed, exposed)
+	}
+	if err := CheckWorkspacePermissions(); err != nil {
+		t.Errorf("CheckWorkspacePermissions() after remediation error = %v, want nil", err)
+	}
+}
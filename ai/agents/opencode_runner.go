@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"time"
+
+	"github.com/hrygo/hotplex"
+)
+
+// openCodeBinary is the binary of the OpenCode CLI.
+const openCodeBinary = "opencode"
+
+// OpenCodeRunner is the AgentRunner of the OpenCode CLI, whose Part-based
+// JSON output the hotplex provider parses into the events of the Claude Code
+// stream.
+//
+// OpenCode takes no tool flags: tool policies are enforced by stopping the
+// sessions using a tool they do not permit. It does not report its model.
+type OpenCodeRunner struct {
+	engineRunner
+}
+
+var _ AgentRunner = (*OpenCodeRunner)(nil)
+
+// NewOpenCodeRunner creates a runner of the OpenCode CLI found in PATH.
+func NewOpenCodeRunner(timeout time.Duration, logger *slog.Logger, opts ...CCRunnerOption) (*OpenCodeRunner, error) {
+	opt := &ccRunnerOptions{}
+	for _, o := range opts {
+		o(opt)
+	}
+
+	if _, err := exec.LookPath(openCodeBinary); err != nil {
+		return nil, fmt.Errorf("%s CLI not found in PATH: %w", openCodeBinary, err)
+	}
+	prv, err := hotplex.NewOpenCodeProvider(hotplex.ProviderConfig{Type: hotplex.ProviderTypeOpenCode}, logger)
+	if err != nil {
+		return nil, fmt.Errorf("create opencode provider: %w", err)
+	}
+
+	runner, err := newEngineRunner(prv, timeout, logger, opt)
+	if err != nil {
+		return nil, err
+	}
+	return &OpenCodeRunner{engineRunner: *runner}, nil
+}
+
+// Execute runs a prompt in the OpenCode session of cfg.
+func (r *OpenCodeRunner) Execute(ctx context.Context, cfg *CCRunnerConfig, prompt string, callback EventCallback) error {
+	return r.execute(ctx, cfg, prompt, callback)
+}
@@ -4,6 +4,9 @@ package orchestrator
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"log/slog"
 	"math"
 	"regexp"
@@ -563,6 +566,39 @@ func (cm *CapabilityMap) GetAllExpertNames() []string {
 	return names
 }
 
+// Fingerprint returns a digest of the experts, their capabilities and the
+// capability triggers; it changes whenever the map is rebuilt differently.
+// Fingerprint 返回能力映射的摘要，映射内容变化时摘要随之变化。
+func (cm *CapabilityMap) Fingerprint() string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	h := sha256.New()
+	names := make([]string, 0, len(cm.experts))
+	for name := range cm.experts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(h, "expert:%s:%s\n", name, strings.Join(cm.experts[name].Capabilities, ","))
+	}
+
+	keywords := make([]string, 0, len(cm.keywordToCapabilities))
+	for keyword := range cm.keywordToCapabilities {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+	for _, keyword := range keywords {
+		caps := make([]string, 0, len(cm.keywordToCapabilities[keyword]))
+		for _, c := range cm.keywordToCapabilities[keyword] {
+			caps = append(caps, string(c))
+		}
+		sort.Strings(caps)
+		fmt.Fprintf(h, "trigger:%s:%s\n", keyword, strings.Join(caps, ","))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 // FindExpertsByCapability returns all experts that provide the given capability.
 // FindExpertsByCapability 返回提供指定能力的所有专家。
 func (cm *CapabilityMap) FindExpertsByCapability(capability string) []*ExpertInfo {
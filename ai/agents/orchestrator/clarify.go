@@ -0,0 +1,84 @@
+package orchestrator
+
+import (
+	"context"
+	"strings"
+)
+
+// Clarification is a single clarifying question asked when the request is too
+// ambiguous to route. Options, if any, are rendered as buttons.
+type Clarification struct {
+	Question string   `json:"question"`
+	Options  []string `json:"options,omitempty"`
+}
+
+// clarificationStateKey is the context key for clarificationState.
+type clarificationStateKey struct{}
+
+// clarificationState carries the routing outcome of the current request.
+type clarificationState struct {
+	confidence float64
+	round      int
+}
+
+// WithClarificationState records the routing confidence of the request and how
+// many clarifying questions were already answered for it. Without this state
+// the orchestrator never asks for clarification.
+func WithClarificationState(ctx context.Context, confidence float64, round int) context.Context {
+	return context.WithValue(ctx, clarificationStateKey{}, clarificationState{
+		confidence: confidence,
+		round:      round,
+	})
+}
+
+// lowConfidence reports whether the request was routed with low confidence,
+// i.e. whether the Decomposer may answer with a clarifying question.
+func lowConfidence(ctx context.Context, config *OrchestratorConfig) bool {
+	if config.MaxClarificationRounds <= 0 {
+		return false
+	}
+	state, ok := ctx.Value(clarificationStateKey{}).(clarificationState)
+	return ok && state.confidence < config.ClarificationThreshold
+}
+
+// clarificationRoundsLeft reports whether another clarifying question may be asked.
+func clarificationRoundsLeft(ctx context.Context, config *OrchestratorConfig) bool {
+	state, _ := ctx.Value(clarificationStateKey{}).(clarificationState)
+	return state.round < config.MaxClarificationRounds
+}
+
+// normalize trims the question and drops empty or duplicate options.
+// Returns false if there is no question left to ask.
+func (c *Clarification) normalize() bool {
+	c.Question = strings.TrimSpace(c.Question)
+	if c.Question == "" {
+		return false
+	}
+	seen := make(map[string]bool, len(c.Options))
+	options := c.Options[:0]
+	for _, opt := range c.Options {
+		opt = strings.TrimSpace(opt)
+		if opt == "" || seen[opt] {
+			continue
+		}
+		seen[opt] = true
+		options = append(options, opt)
+	}
+	c.Options = options
+	return true
+}
+
+// memoFallbackPlan hands the request to the memo expert once clarification
+// rounds are exhausted, rather than guessing between experts.
+func memoFallbackPlan(userInput string) *TaskPlan {
+	return &TaskPlan{
+		Analysis: "[Clarification Fallback] Request still ambiguous, routing to memo expert",
+		Tasks: []*Task{{
+			ID:      "t1",
+			Agent:   "memo",
+			Input:   userInput,
+			Purpose: "Handle user request",
+			Status:  TaskStatusPending,
+		}},
+	}
+}
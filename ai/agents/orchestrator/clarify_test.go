@@ -0,0 +1,81 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/ai/core/llm"
+)
+
+// fixedLLM answers every Chat call with the same response.
+type fixedLLM struct {
+	response string
+}
+
+func (f *fixedLLM) Chat(ctx context.Context, messages []llm.Message) (string, *llm.LLMCallStats, error) {
+	return f.response, &llm.LLMCallStats{}, nil
+}
+
+func (f *fixedLLM) ChatStream(ctx context.Context, messages []llm.Message) (<-chan string, <-chan *llm.LLMCallStats, <-chan error) {
+	return nil, nil, nil
+}
+
+func (f *fixedLLM) ChatWithTools(ctx context.Context, messages []llm.Message, tools []llm.ToolDescriptor) (*llm.ChatResponse, *llm.LLMCallStats, error) {
+	return nil, nil, nil
+}
+
+func (f *fixedLLM) Warmup(ctx context.Context) {}
+
+func newClarifyRegistry() *MockRegistry {
+	registry := new(MockRegistry)
+	registry.On("GetAvailableExperts").Return([]string{"memo", "schedule"})
+	registry.On("GetExpertDescription", mock.Anything).Return("expert")
+	registry.On("GetExpertConfig", mock.Anything).Return(nil)
+	registry.On("ExecuteExpert", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return registry
+}
+
+func newClarifyOrchestrator() *Orchestrator {
+	return NewOrchestrator(&fixedLLM{response: clarificationResponse}, newClarifyRegistry(),
+		WithHandoff(false), WithAggregation(false), WithClarification(2, 0.6))
+}
+
+const clarificationResponse = `{"analysis":"日程还是笔记不明确","clarification":{"question":"您是想创建日程，还是记录笔记？","options":["创建日程"," 记录笔记 ","创建日程",""]}}`
+
+func TestClarification_AskWhenLowConfidence(t *testing.T) {
+	orch := newClarifyOrchestrator()
+
+	ctx := WithClarificationState(context.Background(), 0.4, 0)
+	result, err := orch.Process(ctx, "明天下午三点和老王", nil)
+	require.NoError(t, err)
+	require.NotNil(t, result.Clarification)
+	assert.Equal(t, "您是想创建日程，还是记录笔记？", result.Clarification.Question)
+	assert.Equal(t, []string{"创建日程", "记录笔记"}, result.Clarification.Options)
+	assert.Empty(t, result.Plan.Tasks)
+}
+
+func TestClarification_FallbackToMemoWhenExhausted(t *testing.T) {
+	orch := newClarifyOrchestrator()
+
+	ctx := WithClarificationState(context.Background(), 0.4, 2)
+	result, err := orch.Process(ctx, "明天下午三点和老王", nil)
+	require.NoError(t, err)
+	assert.Nil(t, result.Clarification)
+	require.Len(t, result.Plan.Tasks, 1)
+	assert.Equal(t, "memo", result.Plan.Tasks[0].Agent)
+}
+
+func TestClarification_NotAllowedForConfidentRoute(t *testing.T) {
+	orch := newClarifyOrchestrator()
+
+	// No clarification state: the unexpected question is ignored and the
+	// decomposer falls back to a direct expert plan.
+	result, err := orch.Process(context.Background(), "明天下午三点和老王", nil)
+	require.NoError(t, err)
+	assert.Nil(t, result.Clarification)
+	require.Len(t, result.Plan.Tasks, 1)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"runtime/debug"
 	"sync"
 	"time"
 )
@@ -166,26 +167,25 @@ func (s *DAGScheduler) Run(ctx context.Context) error {
 				// Wrap execution in a function to handle panics and state updates safely
 				func() {
 					defer func() {
-						// Handle panic
+						// Handle panic outside the expert (context injection,
+						// handoff, event forwarding): the task fails, the others
+						// keep running.
 						if r := recover(); r != nil {
 							slog.Error("executor: panic in task execution",
 								"trace_id", s.traceID,
 								"task_id", tid,
-								"panic", r)
-
-							// Mark task as failed and cascade
-							// Note: s.mu protects DAG state (tasks map, inDegree, etc)
-							// but Task internal state is protected by its own mutex.
-							// However, s.cascadeSkip reads/writes task status too.
-							// We need to be careful about lock ordering if we hold both.
-							// Here we hold s.mu.
+								"panic", r,
+								"stack", string(debug.Stack()))
 
+							// Task internal state is protected by its own mutex,
+							// s.mu protects the DAG state cascadeSkip updates.
 							task := s.tasks[tid]
-							// Use safe accessor - though we are holding s.mu, other goroutines (Executor)
-							// might be modifying Task without s.mu.
 							if !task.GetStatus().IsTerminal() {
 								task.SetError(fmt.Sprintf("Panic: %v", r))
+								s.mu.Lock()
 								s.cascadeSkip(tid)
+								s.mu.Unlock()
+								s.executor.sendTaskEndEvent(task, s.taskIndices[tid], 0, s.dispatcher)
 							}
 						}
 
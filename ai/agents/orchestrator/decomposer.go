@@ -52,7 +52,8 @@ func (d *Decomposer) Decompose(ctx context.Context, userInput string, registry E
 
 	// Build the decomposition prompt with time context and history
 	history := ctxpkg.GetHistory(ctx)
-	prompt := d.buildDecompositionPrompt(userInput, expertDescriptions, timeContext, history)
+	clarify := lowConfidence(ctx, d.config)
+	prompt := d.buildDecompositionPrompt(userInput, expertDescriptions, timeContext, history, clarify)
 
 	// Call LLM for decomposition
 	messages := []llm.Message{
@@ -69,6 +70,9 @@ func (d *Decomposer) Decompose(ctx context.Context, userInput string, registry E
 
 	// Parse the response into a TaskPlan
 	plan, err := d.parseTaskPlan(response, experts)
+	if err == nil && plan.Clarification != nil && !clarify {
+		err = fmt.Errorf("clarification not allowed for confident route")
+	}
 	if err != nil {
 		slog.Warn("decomposer: failed to parse plan, using fallback",
 			"trace_id", traceID,
@@ -76,6 +80,7 @@ func (d *Decomposer) Decompose(ctx context.Context, userInput string, registry E
 			"response_length", len(response))
 		plan := d.fallbackPlan(userInput, experts)
 		plan.Analysis = "[Fallback Mode] " + plan.Analysis
+		plan.fallback = true
 		return plan, nil
 	}
 
@@ -102,8 +107,8 @@ func (d *Decomposer) buildExpertDescriptions(experts []string, registry ExpertRe
 }
 
 // buildDecompositionPrompt creates the prompt for task decomposition.
-func (d *Decomposer) buildDecompositionPrompt(userInput, expertDescriptions string, timeContext *universal.TimeContext, history []string) string {
-	return d.promptConfig.BuildDecomposerPrompt(userInput, expertDescriptions, timeContext, history)
+func (d *Decomposer) buildDecompositionPrompt(userInput, expertDescriptions string, timeContext *universal.TimeContext, history []string, clarify bool) string {
+	return d.promptConfig.BuildDecomposerPrompt(userInput, expertDescriptions, timeContext, history, clarify)
 }
 
 // parseTaskPlan parses the LLM response into a TaskPlan.
@@ -126,6 +131,13 @@ func (d *Decomposer) parseTaskPlan(response string, validAgents []string) (*Task
 		return &plan, nil
 	}
 
+	// Handle clarification case - ask the user before executing anything
+	if plan.Clarification != nil && plan.Clarification.normalize() {
+		plan.Tasks = nil
+		return &plan, nil
+	}
+	plan.Clarification = nil
+
 	// Validate and set defaults for normal cases
 	if len(plan.Tasks) == 0 {
 		return nil, fmt.Errorf("no tasks in plan")
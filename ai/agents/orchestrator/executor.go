@@ -6,9 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"runtime/debug"
 	"strings"
 	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/hrygo/divinesense/ai/observability/tracing"
 )
 
 // Event types for orchestrator events
@@ -19,8 +24,23 @@ const (
 	EventTypeTaskStart = "task_start"
 	// EventTypeTaskEnd is sent when a task finishes executing
 	EventTypeTaskEnd = "task_end"
+	// EventTypeTaskRetry is sent when a task failed with a transient error and
+	// is about to be retried; the output of the failed attempt is discarded
+	EventTypeTaskRetry = "task_retry"
 )
 
+// ExpertPanicError is the error of a task whose expert panicked. The panic
+// fails the task only: the other tasks of the plan keep running.
+type ExpertPanicError struct {
+	Expert string
+	Value  any
+	Stack  []byte
+}
+
+func (e *ExpertPanicError) Error() string {
+	return fmt.Sprintf("expert %s panicked: %v", e.Expert, e.Value)
+}
+
 // Executor executes tasks by dispatching them to expert agents.
 type Executor struct {
 	registry       ExpertRegistry
@@ -70,6 +90,13 @@ func (e *Executor) ExecutePlan(ctx context.Context, plan *TaskPlan, callback Eve
 		return result
 	}
 
+	// Cap the plan duration, retries included
+	if e.config.MaxPlanDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.config.MaxPlanDuration)
+		defer cancel()
+	}
+
 	// Initialize EventDispatcher
 	dispatcher := NewEventDispatcher(traceID, callback)
 	defer dispatcher.Close()
@@ -184,10 +211,10 @@ func (e *Executor) executeTaskWithHandoff(ctx context.Context, task *Task, index
 	maxRetries := e.config.MaxRetries
 	backoff := e.config.RetryBackoff
 
-	for i := 0; i <= maxRetries; i++ {
+	for attempt := 0; ; attempt++ {
 		// Use resultCollector.onEvent as callback
 		// Note: history is automatically extracted from context in ExecuteExpert via GetHistory
-		err = e.registry.ExecuteExpert(ctx, task.Agent, task.Input, resultCollector.onEvent)
+		err = e.executeExpert(ctx, task, resultCollector.onEvent, traceID)
 		if err == nil {
 			break
 		}
@@ -201,16 +228,34 @@ func (e *Executor) executeTaskWithHandoff(ctx context.Context, task *Task, index
 			)
 			break
 		}
-
-		if i < maxRetries {
-			slog.Warn("executor: task execution failed, retrying transient error",
+		if attempt >= maxRetries {
+			break
+		}
+		if !retryFits(ctx, backoff) {
+			slog.Warn("executor: task execution failed, retry would exceed the plan duration",
 				"trace_id", traceID,
 				"task_id", task.ID,
-				"attempt", i+1,
+				"attempt", attempt+1,
 				"error", err,
 			)
-			time.Sleep(backoff)
-			backoff *= 2 // Exponential backoff
+			break
+		}
+
+		slog.Warn("executor: task execution failed, retrying transient error",
+			"trace_id", traceID,
+			"task_id", task.ID,
+			"attempt", attempt+1,
+			"error", err,
+		)
+		e.sendTaskRetryEvent(task, index, attempt+1, backoff, err, dispatcher)
+		resultCollector.reset()
+
+		if !sleepContext(ctx, backoff) {
+			break
+		}
+		backoff *= 2 // Exponential backoff
+		if e.config.MaxRetryBackoff > 0 && backoff > e.config.MaxRetryBackoff {
+			backoff = e.config.MaxRetryBackoff
 		}
 	}
 
@@ -258,7 +303,7 @@ func (e *Executor) executeTaskWithHandoff(ctx context.Context, task *Task, index
 		}
 
 		// Send task_end event with error
-		e.sendTaskEndEvent(task, index, dispatcher)
+		e.sendTaskEndEvent(task, index, time.Since(startTime), dispatcher)
 		return err
 	}
 
@@ -275,10 +320,52 @@ func (e *Executor) executeTaskWithHandoff(ctx context.Context, task *Task, index
 	)
 
 	// Send task_end event
-	e.sendTaskEndEvent(task, index, dispatcher)
+	e.sendTaskEndEvent(task, index, duration, dispatcher)
 	return nil
 }
 
+// executeExpert runs the expert of a task in an "agent.execute" span,
+// recovering a panic of the expert into an ExpertPanicError.
+func (e *Executor) executeExpert(ctx context.Context, task *Task, callback EventCallback, traceID string) (err error) {
+	ctx, span := tracing.Start(ctx, "agent.execute",
+		attribute.String("agent.type", task.Agent),
+		attribute.String("orchestrator.task_id", task.ID),
+	)
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := &ExpertPanicError{Expert: task.Agent, Value: r, Stack: debug.Stack()}
+			slog.Error("executor: expert panicked",
+				"trace_id", traceID,
+				"task_id", task.ID,
+				"agent", task.Agent,
+				"panic", r,
+				"stack", string(panicErr.Stack))
+			err = panicErr
+		}
+		tracing.End(span, err)
+	}()
+	return e.registry.ExecuteExpert(ctx, task.Agent, task.Input, callback)
+}
+
+// retryFits reports whether a retry after backoff starts before the deadline
+// of ctx, i.e. within the plan duration.
+func retryFits(ctx context.Context, backoff time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Until(deadline) > backoff
+}
+
+// sleepContext waits for d, returning false if ctx is done first.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 // sendPlanEvent sends the task plan to the frontend.
 func (e *Executor) sendPlanEvent(plan *TaskPlan, dispatcher *EventDispatcher) {
 	event := map[string]interface{}{
@@ -311,14 +398,18 @@ func (e *Executor) sendTaskStartEvent(task *Task, index int, dispatcher *EventDi
 	dispatcher.Send(EventTypeTaskStart, string(eventJSON))
 }
 
-// sendTaskEndEvent sends a task end event to the frontend.
-func (e *Executor) sendTaskEndEvent(task *Task, index int, dispatcher *EventDispatcher) {
+// sendTaskEndEvent sends a task end event to the frontend, with the duration
+// of the task when known.
+func (e *Executor) sendTaskEndEvent(task *Task, index int, duration time.Duration, dispatcher *EventDispatcher) {
 	event := map[string]interface{}{
 		"id":     task.ID,
 		"index":  index,
 		"agent":  task.Agent,
 		"status": string(task.GetStatus()),
 	}
+	if duration > 0 {
+		event["duration_ms"] = duration.Milliseconds()
+	}
 	if errVal := task.GetError(); errVal != "" {
 		event["error"] = errVal
 	}
@@ -330,6 +421,25 @@ func (e *Executor) sendTaskEndEvent(task *Task, index int, dispatcher *EventDisp
 	dispatcher.Send(EventTypeTaskEnd, string(eventJSON))
 }
 
+// sendTaskRetryEvent sends a task retry event to the frontend.
+func (e *Executor) sendTaskRetryEvent(task *Task, index, attempt int, backoff time.Duration, taskErr error, dispatcher *EventDispatcher) {
+	event := map[string]interface{}{
+		"id":          task.ID,
+		"index":       index,
+		"agent":       task.Agent,
+		"attempt":     attempt,
+		"max_retries": e.config.MaxRetries,
+		"backoff_ms":  backoff.Milliseconds(),
+		"error":       taskErr.Error(),
+	}
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("executor: failed to marshal task_retry event", "error", err, "id", task.ID)
+		return
+	}
+	dispatcher.Send(EventTypeTaskRetry, string(eventJSON))
+}
+
 // Max result size to prevent OOM (10MB)
 const maxResultSize = 10 * 1024 * 1024
 
@@ -368,6 +478,14 @@ func (rc *resultCollector) onEvent(eventType string, eventData string) {
 	}
 }
 
+// reset drops the result collected from a failed attempt.
+func (rc *resultCollector) reset() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.result.Reset()
+	rc.truncated = false
+}
+
 // getResult returns the collected result.
 func (rc *resultCollector) getResult() string {
 	rc.mu.Lock()
@@ -389,6 +507,8 @@ var transientErrorKeywords = []string{
 	"503",
 	"502",
 	"504",
+	"internal server error",
+	"bad gateway",
 	"context deadline exceeded",
 	"i/o timeout",
 	"network unreachable",
@@ -408,6 +528,12 @@ func isTransientError(err error) bool {
 	errMsg := err.Error()
 	errMsgLower := strings.ToLower(errMsg)
 
+	// A panic is a bug of the expert, retrying would panic again
+	var panicErr *ExpertPanicError
+	if errors.As(err, &panicErr) {
+		return false
+	}
+
 	// Check for context cancellation/deadline - don't retry if explicitly cancelled
 	if errors.Is(err, context.Canceled) {
 		return false
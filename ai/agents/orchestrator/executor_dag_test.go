@@ -3,6 +3,7 @@ package orchestrator
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -309,3 +310,143 @@ func TestDAG_RetrySuccess(t *testing.T) {
 	assert.Equal(t, "success_result", t1.Result)
 	assert.Equal(t, 2, attempts, "Should have executed twice")
 }
+
+// Case 8: 专家 panic 隔离 (A panics -> A failed, B skipped, C completed)
+func TestDAG_ExpertPanic(t *testing.T) {
+	registry := new(MockRegistry)
+	config := DefaultOrchestratorConfig()
+	config.MaxParallelTasks = 3
+	executor := NewExecutor(registry, config)
+
+	tA := createTask("A", "memo", "Panic", nil)
+	tB := createTask("B", "memo", "Dependent", []string{"A"})
+	tC := createTask("C", "schedule", "Independent", nil)
+
+	plan := &TaskPlan{Tasks: []*Task{tA, tB, tC}}
+
+	registry.On("ExecuteExpert", mock.Anything, "memo", "Panic", mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) { panic("boom") }).
+		Once()
+	registry.On("ExecuteExpert", mock.Anything, "schedule", "Independent", mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			args.Get(3).(EventCallback)("content", "done")
+		})
+
+	var mu sync.Mutex
+	var taskEnds []string
+	callback := func(eventType, eventData string) {
+		if eventType == EventTypeTaskEnd {
+			mu.Lock()
+			taskEnds = append(taskEnds, eventData)
+			mu.Unlock()
+		}
+	}
+
+	result := executor.ExecutePlan(context.Background(), plan, callback, "test-expert-panic")
+
+	assert.Equal(t, TaskStatusFailed, tA.Status)
+	assert.Contains(t, tA.Error, "expert memo panicked: boom")
+	assert.Equal(t, TaskStatusSkipped, tB.Status)
+	assert.Equal(t, TaskStatusCompleted, tC.Status)
+	assert.Equal(t, "done", result.FinalResponse)
+	registry.AssertNumberOfCalls(t, "ExecuteExpert", 2) // panics are not retried
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, taskEnds, 2)
+	found := false
+	for _, data := range taskEnds {
+		if strings.Contains(data, `"id":"A"`) && strings.Contains(data, "panicked") {
+			found = true
+		}
+	}
+	assert.True(t, found, "the panicked task must end with its error")
+}
+
+func TestIsTransientError_ExpertPanic(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &ExpertPanicError{Expert: "memo", Value: "timeout"})
+	assert.False(t, isTransientError(err))
+}
+
+// Case 9: 重试事件 (Retry events, output of failed attempts dropped)
+func TestDAG_RetryEvents(t *testing.T) {
+	registry := new(MockRegistry)
+	config := DefaultOrchestratorConfig()
+	config.RetryBackoff = time.Millisecond
+	executor := NewExecutor(registry, config)
+
+	t1 := createTask("t1", "memo", "FlakyTask", nil)
+	plan := &TaskPlan{Tasks: []*Task{t1}}
+
+	registry.On("ExecuteExpert", mock.Anything, "memo", "FlakyTask", mock.Anything).
+		Return(fmt.Errorf("provider returned 503 service unavailable")).
+		Run(func(args mock.Arguments) {
+			args.Get(3).(EventCallback)("content", "partial")
+		}).
+		Twice()
+	registry.On("ExecuteExpert", mock.Anything, "memo", "FlakyTask", mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			args.Get(3).(EventCallback)("content", "final")
+		}).
+		Once()
+
+	var mu sync.Mutex
+	var retries []string
+	callback := func(eventType, eventData string) {
+		if eventType == EventTypeTaskRetry {
+			mu.Lock()
+			retries = append(retries, eventData)
+			mu.Unlock()
+		}
+	}
+
+	result := executor.ExecutePlan(context.Background(), plan, callback, "test-retry-events")
+
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, "final", t1.Result)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, retries, 2)
+	assert.Contains(t, retries[0], `"attempt":1`)
+	assert.Contains(t, retries[1], `"attempt":2`)
+	assert.Contains(t, retries[1], `"backoff_ms":2`)
+	assert.Contains(t, retries[0], "503 service unavailable")
+}
+
+// Case 10: 计划时长上限 (No retry past the plan duration)
+func TestDAG_RetryExceedsPlanDuration(t *testing.T) {
+	registry := new(MockRegistry)
+	config := DefaultOrchestratorConfig()
+	config.RetryBackoff = time.Minute
+	config.MaxPlanDuration = time.Second
+	executor := NewExecutor(registry, config)
+
+	t1 := createTask("t1", "memo", "FlakyTask", nil)
+	plan := &TaskPlan{Tasks: []*Task{t1}}
+
+	registry.On("ExecuteExpert", mock.Anything, "memo", "FlakyTask", mock.Anything).
+		Return(fmt.Errorf("request timed out"))
+
+	start := time.Now()
+	result := executor.ExecutePlan(context.Background(), plan, nil, "test-retry-plan-duration")
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.NotEmpty(t, result.Errors)
+	assert.Equal(t, TaskStatusFailed, t1.Status)
+	registry.AssertNumberOfCalls(t, "ExecuteExpert", 1)
+}
+
+func TestRetryPolicyOptions(t *testing.T) {
+	config := DefaultOrchestratorConfig()
+	WithRetryPolicy(1, 10*time.Millisecond, 0)(config)
+	WithMaxPlanDuration(time.Minute)(config)
+
+	assert.Equal(t, 1, config.MaxRetries)
+	assert.Equal(t, 10*time.Millisecond, config.RetryBackoff)
+	assert.Zero(t, config.MaxRetryBackoff)
+	assert.Equal(t, time.Minute, config.MaxPlanDuration)
+}
@@ -35,17 +35,23 @@ import (
 	"log/slog"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	agents "github.com/hrygo/divinesense/ai/agents"
+	ctxpkg "github.com/hrygo/divinesense/ai/context"
 	"github.com/hrygo/divinesense/ai/core/llm"
+	"github.com/hrygo/divinesense/ai/observability/tracing"
 )
 
 // Orchestrator coordinates the decomposition, execution, and aggregation of tasks.
 // It implements the Orchestrator-Workers pattern as recommended by Anthropic.
 type Orchestrator struct {
-	decomposer *Decomposer
-	executor   *Executor
-	aggregator *Aggregator
-	config     *OrchestratorConfig
+	decomposer   *Decomposer
+	executor     *Executor
+	aggregator   *Aggregator
+	config       *OrchestratorConfig
+	capabilities *CapabilityMap
+	plans        *PlanCache // nil when plan caching is disabled
 }
 
 // NewOrchestrator creates a new orchestrator with the given LLM service and expert registry.
@@ -55,33 +61,50 @@ func NewOrchestrator(llmService llm.Service, registry ExpertRegistry, opts ...Op
 		opt(config)
 	}
 
+	// Build the capability map from the registry; it drives handoff and keys the plan cache
+	capabilityMap := NewCapabilityMap()
+	capabilityMap.BuildFromConfigs(expertConfigs(registry))
+
 	// Create executor with or without handoff support based on config
 	var executor *Executor
 	if config.EnableHandoff {
-		// Create capability map and handoff handler
-		capabilityMap := NewCapabilityMap()
-
-		// Populate capability map from registry
-		var configs []*agents.ParrotSelfCognition
-		for _, name := range registry.GetAvailableExperts() {
-			if config := registry.GetExpertConfig(name); config != nil {
-				configs = append(configs, config)
-			}
-		}
-		capabilityMap.BuildFromConfigs(configs)
-
 		handoffHandler := NewHandoffHandler(capabilityMap, 2)
 		executor = NewExecutorWithHandoff(registry, config, handoffHandler)
 	} else {
 		executor = NewExecutor(registry, config)
 	}
 
+	var plans *PlanCache
+	if config.PlanCacheSize > 0 {
+		plans = NewPlanCache(config.PlanCacheSize, config.PlanCacheTTL)
+	}
+
 	return &Orchestrator{
-		decomposer: NewDecomposer(llmService, config),
-		executor:   executor,
-		aggregator: NewAggregator(llmService, config),
-		config:     config,
+		decomposer:   NewDecomposer(llmService, config),
+		executor:     executor,
+		aggregator:   NewAggregator(llmService, config),
+		config:       config,
+		capabilities: capabilityMap,
+		plans:        plans,
+	}
+}
+
+// expertConfigs returns the self-cognition configurations of the registry's experts.
+func expertConfigs(registry ExpertRegistry) []*agents.ParrotSelfCognition {
+	var configs []*agents.ParrotSelfCognition
+	for _, name := range registry.GetAvailableExperts() {
+		if config := registry.GetExpertConfig(name); config != nil {
+			configs = append(configs, config)
+		}
 	}
+	return configs
+}
+
+// RefreshCapabilities rebuilds the capability map from the expert registry,
+// e.g. after expert configurations were reloaded. Cached plans are dropped
+// if the capabilities changed.
+func (o *Orchestrator) RefreshCapabilities() {
+	o.capabilities.BuildFromConfigs(expertConfigs(o.executor.registry))
 }
 
 // Option configures the orchestrator.
@@ -112,14 +135,89 @@ func WithHandoff(enabled bool) Option {
 	}
 }
 
+// WithRetryPolicy configures the retries of tasks failing with transient
+// expert errors (provider 5xx, timeouts, rate limits): up to maxRetries
+// retries per task, with an exponential backoff starting at backoff and
+// capped at maxBackoff (0 is uncapped).
+func WithRetryPolicy(maxRetries int, backoff, maxBackoff time.Duration) Option {
+	return func(c *OrchestratorConfig) {
+		if maxRetries >= 0 {
+			c.MaxRetries = maxRetries
+		}
+		if backoff > 0 {
+			c.RetryBackoff = backoff
+		}
+		if maxBackoff >= 0 {
+			c.MaxRetryBackoff = maxBackoff
+		}
+	}
+}
+
+// WithMaxPlanDuration caps the execution of a plan, retries included.
+// A retry whose backoff would cross the cap is not attempted (0 is uncapped).
+func WithMaxPlanDuration(d time.Duration) Option {
+	return func(c *OrchestratorConfig) {
+		if d >= 0 {
+			c.MaxPlanDuration = d
+		}
+	}
+}
+
+// WithPlanCache configures the cache of task plans by intent signature:
+// up to size plans reused for ttl (size 0 disables plan caching).
+func WithPlanCache(size int, ttl time.Duration) Option {
+	return func(c *OrchestratorConfig) {
+		if size >= 0 {
+			c.PlanCacheSize = size
+		}
+		if ttl > 0 {
+			c.PlanCacheTTL = ttl
+		}
+	}
+}
+
+// WithValidators adds validators run on the output of each completed task
+// before aggregation, next to the default ones.
+func WithValidators(validators ...ResultValidator) Option {
+	return func(c *OrchestratorConfig) {
+		c.Validators = append(c.Validators, validators...)
+	}
+}
+
+// WithClarification configures the clarifying-question loop for low-confidence requests.
+// maxRounds bounds how many questions are asked before falling back to the memo
+// expert (0 disables clarification); threshold is the routing confidence below
+// which a question may be asked.
+func WithClarification(maxRounds int, threshold float64) Option {
+	return func(c *OrchestratorConfig) {
+		if maxRounds >= 0 {
+			c.MaxClarificationRounds = maxRounds
+		}
+		if threshold > 0 {
+			c.ClarificationThreshold = threshold
+		}
+	}
+}
+
 // Process handles a user request by decomposing, executing, and aggregating.
 // This is the main entry point for the orchestrator.
 func (o *Orchestrator) Process(ctx context.Context, userInput string, callback EventCallback) (*ExecutionResult, error) {
-	startTime := time.Now()
-
 	// Generate trace_id for request tracing
 	traceID := GenerateTraceID()
 
+	ctx, span := tracing.Start(ctx, "orchestrator.process", attribute.String("orchestrator.trace_id", traceID))
+	result, err := o.process(ctx, userInput, callback, traceID)
+	if result != nil && result.Plan != nil {
+		span.SetAttributes(attribute.Int("orchestrator.tasks", len(result.Plan.Tasks)))
+	}
+	tracing.End(span, err)
+	return result, err
+}
+
+// process runs Process for a request of a trace ID.
+func (o *Orchestrator) process(ctx context.Context, userInput string, callback EventCallback, traceID string) (*ExecutionResult, error) {
+	startTime := time.Now()
+
 	slog.Info("orchestrator: processing request",
 		"trace_id", traceID,
 		"input_length", len(userInput))
@@ -129,8 +227,8 @@ func (o *Orchestrator) Process(ctx context.Context, userInput string, callback E
 		callback("decompose_start", `{"status":"analyzing"}`)
 	}
 
-	// Step 1: Decompose the request into tasks
-	plan, err := o.decomposer.Decompose(ctx, userInput, o.executor.registry, traceID)
+	// Step 1: Decompose the request into tasks, reusing the plan of a recurring request
+	plan, cached, err := o.plan(ctx, userInput, traceID)
 	if err != nil {
 		slog.Error("orchestrator: decomposition failed",
 			"trace_id", traceID,
@@ -138,15 +236,33 @@ func (o *Orchestrator) Process(ctx context.Context, userInput string, callback E
 		return nil, err
 	}
 
+	// Ask instead of guessing while clarification rounds remain; once they are
+	// exhausted, hand the still-ambiguous request to the memo expert.
+	if plan.Clarification != nil && !clarificationRoundsLeft(ctx, o.config) {
+		slog.Info("orchestrator: clarification rounds exhausted, falling back to memo",
+			"trace_id", traceID)
+		plan = memoFallbackPlan(userInput)
+	}
+
 	// Send decompose_end event with task count
 	if callback != nil {
-		taskInfo := fmt.Sprintf(`{"task_count":%d,"analysis":%q}`, len(plan.Tasks), plan.Analysis)
+		taskInfo := fmt.Sprintf(`{"task_count":%d,"analysis":%q,"cached":%t}`, len(plan.Tasks), plan.Analysis, cached)
 		callback("decompose_end", taskInfo)
 	}
 
+	if plan.Clarification != nil {
+		slog.Info("orchestrator: asking for clarification",
+			"trace_id", traceID,
+			"question", plan.Clarification.Question)
+		return &ExecutionResult{Plan: plan, Clarification: plan.Clarification}, nil
+	}
+
 	// Step 2: Execute the tasks
 	result := o.executor.ExecutePlan(ctx, plan, callback, traceID)
 
+	// Check the experts' outputs before they are combined
+	result.Warnings = validateResults(ctx, o.config.Validators, plan, callback, traceID)
+
 	// Step 3: Aggregate results if needed
 	if result.IsAggregated && o.config.EnableAggregation {
 		aggregated, err := o.aggregator.Aggregate(ctx, result, callback)
@@ -171,6 +287,32 @@ func (o *Orchestrator) Process(ctx context.Context, userInput string, callback E
 	return result, nil
 }
 
+// plan decomposes the request, or returns the cached plan of the same intent
+// signature. Only standalone requests use the cache: see PlanCache.
+func (o *Orchestrator) plan(ctx context.Context, userInput, traceID string) (*TaskPlan, bool, error) {
+	if o.plans == nil || len(ctxpkg.GetHistory(ctx)) > 0 || lowConfidence(ctx, o.config) {
+		plan, err := o.decomposer.Decompose(ctx, userInput, o.executor.registry, traceID)
+		return plan, false, err
+	}
+
+	fingerprint := o.capabilities.Fingerprint()
+	o.plans.Sync(fingerprint)
+	signature := intentSignature(userInput, time.Now(), fingerprint)
+	if plan, ok := o.plans.Get(signature); ok {
+		slog.Info("orchestrator: reusing cached plan",
+			"trace_id", traceID,
+			"tasks", len(plan.Tasks))
+		return plan, true, nil
+	}
+
+	plan, err := o.decomposer.Decompose(ctx, userInput, o.executor.registry, traceID)
+	if err != nil {
+		return nil, false, err
+	}
+	o.plans.Put(signature, plan)
+	return plan, false, nil
+}
+
 // ProcessSimple is a convenience method that returns just the final response string.
 // Use this when you don't need the full execution result.
 func (o *Orchestrator) ProcessSimple(ctx context.Context, userInput string, callback EventCallback) (string, error) {
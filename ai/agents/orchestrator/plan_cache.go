@@ -0,0 +1,134 @@
+package orchestrator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/hrygo/divinesense/ai/cache"
+)
+
+// PlanCache caches the task plans of requests by intent signature, so that
+// recurring multi-intent requests skip the decomposition LLM call.
+//
+// Only standalone requests are cached: plans decomposed with conversation
+// history or with clarification allowed depend on more than the request.
+// The signature includes the day, since plans carry resolved relative dates,
+// and the cache is emptied when the capability map of the experts changes.
+type PlanCache struct {
+	plans *cache.LRUCache[string, *TaskPlan]
+
+	mu          sync.Mutex
+	fingerprint string
+}
+
+// NewPlanCache creates a plan cache of size plans kept for ttl.
+func NewPlanCache(size int, ttl time.Duration) *PlanCache {
+	return &PlanCache{plans: cache.NewLRUCache[string, *TaskPlan](size, ttl)}
+}
+
+// Get returns a copy of the cached plan of a signature.
+func (c *PlanCache) Get(signature string) (*TaskPlan, bool) {
+	plan, ok := c.plans.Get(signature)
+	if !ok {
+		return nil, false
+	}
+	return plan.clone(), true
+}
+
+// Put caches a copy of a freshly decomposed plan. Plans that are not worth
+// replaying (fallbacks, clarifications, direct responses) are ignored.
+func (c *PlanCache) Put(signature string, plan *TaskPlan) {
+	if !cacheablePlan(plan) {
+		return
+	}
+	c.plans.SetWithDefaultTTL(signature, plan.clone())
+}
+
+// Sync empties the cache when the capability fingerprint changed since the
+// last call.
+func (c *PlanCache) Sync(fingerprint string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if fingerprint == c.fingerprint {
+		return
+	}
+	if c.fingerprint != "" {
+		c.plans.Clear()
+	}
+	c.fingerprint = fingerprint
+}
+
+// Len returns the number of cached plans.
+func (c *PlanCache) Len() int {
+	return c.plans.Size()
+}
+
+// intentSignature returns the cache key of a request: its normalized text,
+// the day it is made and the capability fingerprint of the experts.
+func intentSignature(userInput string, now time.Time, fingerprint string) string {
+	h := sha256.New()
+	h.Write([]byte(normalizeIntent(userInput)))
+	h.Write([]byte{0})
+	h.Write([]byte(now.Format("2006-01-02")))
+	h.Write([]byte{0})
+	h.Write([]byte(fingerprint))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalizeIntent lowercases the request, drops punctuation and collapses
+// whitespace, so that trivially different phrasings share a signature.
+func normalizeIntent(userInput string) string {
+	var sb strings.Builder
+	space := false
+	for _, r := range strings.ToLower(userInput) {
+		switch {
+		case unicode.IsSpace(r):
+			space = true
+		case unicode.IsPunct(r):
+			continue
+		default:
+			if space && sb.Len() > 0 {
+				sb.WriteByte(' ')
+			}
+			space = false
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// cacheablePlan reports whether a plan may be replayed for the same request.
+func cacheablePlan(plan *TaskPlan) bool {
+	return plan != nil && !plan.fallback && !plan.DirectResponse &&
+		plan.Clarification == nil && len(plan.Tasks) > 0
+}
+
+// clone returns a copy of a plan with pending tasks, so that executing it
+// leaves the original untouched.
+func (p *TaskPlan) clone() *TaskPlan {
+	clone := &TaskPlan{
+		Analysis:       p.Analysis,
+		Parallel:       p.Parallel,
+		Aggregate:      p.Aggregate,
+		DirectResponse: p.DirectResponse,
+		Response:       p.Response,
+		Clarification:  p.Clarification,
+		fallback:       p.fallback,
+		Tasks:          make([]*Task, 0, len(p.Tasks)),
+	}
+	for _, task := range p.Tasks {
+		clone.Tasks = append(clone.Tasks, &Task{
+			ID:           task.ID,
+			Agent:        task.Agent,
+			Input:        task.Input,
+			Purpose:      task.Purpose,
+			Dependencies: append([]string(nil), task.Dependencies...),
+			Status:       TaskStatusPending,
+		})
+	}
+	return clone
+}
@@ -0,0 +1,119 @@
+package orchestrator
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	agents "github.com/hrygo/divinesense/ai/agents"
+	ctxpkg "github.com/hrygo/divinesense/ai/context"
+	"github.com/hrygo/divinesense/ai/core/llm"
+)
+
+// countingLLM answers every Chat call with the same response and counts the calls.
+type countingLLM struct {
+	fixedLLM
+	calls atomic.Int32
+}
+
+func (c *countingLLM) Chat(ctx context.Context, messages []llm.Message) (string, *llm.LLMCallStats, error) {
+	c.calls.Add(1)
+	return c.fixedLLM.Chat(ctx, messages)
+}
+
+const multiIntentResponse = `{"analysis":"两个意图","tasks":[{"id":"t1","agent":"memo","input":"总结今天的笔记","purpose":"总结"},{"id":"t2","agent":"schedule","input":"列出明天的日程","purpose":"日程"}],"parallel":true,"aggregate":false}`
+
+// configRegistry serves expert configurations that tests may change.
+type configRegistry struct {
+	*MockRegistry
+	configs map[string]*agents.ParrotSelfCognition
+}
+
+func (r *configRegistry) GetExpertConfig(name string) *agents.ParrotSelfCognition {
+	return r.configs[name]
+}
+
+func newPlanCacheOrchestrator(llmService llm.Service, configs map[string]*agents.ParrotSelfCognition) *Orchestrator {
+	registry := new(MockRegistry)
+	registry.On("GetAvailableExperts").Return([]string{"memo", "schedule"})
+	registry.On("GetExpertDescription", mock.Anything).Return("expert")
+	registry.On("ExecuteExpert", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return NewOrchestrator(llmService, &configRegistry{MockRegistry: registry, configs: configs},
+		WithHandoff(false), WithAggregation(false))
+}
+
+func TestPlanCache_ReusesPlanOfRecurringRequest(t *testing.T) {
+	configs := map[string]*agents.ParrotSelfCognition{
+		"memo": {Name: "memo", Capabilities: []string{"笔记搜索"}},
+	}
+	llmService := &countingLLM{fixedLLM: fixedLLM{response: multiIntentResponse}}
+	orch := newPlanCacheOrchestrator(llmService, configs)
+
+	first, err := orch.Process(context.Background(), "Summarize today's memos and list tomorrow's schedule", nil)
+	require.NoError(t, err)
+	require.Len(t, first.Plan.Tasks, 2)
+	assert.Equal(t, TaskStatusCompleted, first.Plan.Tasks[0].GetStatus())
+
+	var decomposeEnd string
+	second, err := orch.Process(context.Background(), "  summarize today's memos, and list tomorrow's schedule! ", func(eventType, eventData string) {
+		if eventType == "decompose_end" {
+			decomposeEnd = eventData
+		}
+	})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, llmService.calls.Load(), "the recurring request must skip the planning call")
+	require.Len(t, second.Plan.Tasks, 2)
+	assert.Equal(t, "总结今天的笔记", second.Plan.Tasks[0].Input)
+	assert.NotSame(t, first.Plan.Tasks[0], second.Plan.Tasks[0])
+	assert.Contains(t, decomposeEnd, `"cached":true`)
+
+	// Requests with history depend on the conversation and are not cached
+	ctx := context.WithValue(context.Background(), ctxpkg.KeyHistory, []string{"user: hi"})
+	_, err = orch.Process(ctx, "Summarize today's memos and list tomorrow's schedule", nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, llmService.calls.Load())
+
+	// A changed capability map invalidates the cached plans
+	configs["schedule"] = &agents.ParrotSelfCognition{Name: "schedule", Capabilities: []string{"日程查询"}}
+	orch.RefreshCapabilities()
+	_, err = orch.Process(context.Background(), "Summarize today's memos and list tomorrow's schedule", nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, llmService.calls.Load())
+}
+
+func TestPlanCache_SkipsFallbackPlans(t *testing.T) {
+	llmService := &countingLLM{fixedLLM: fixedLLM{response: "not json"}}
+	orch := newPlanCacheOrchestrator(llmService, nil)
+
+	for range 2 {
+		_, err := orch.Process(context.Background(), "hello", nil)
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 2, llmService.calls.Load())
+	assert.Zero(t, orch.plans.Len())
+}
+
+func TestPlanCache_Sync(t *testing.T) {
+	plans := NewPlanCache(10, time.Hour)
+	plans.Sync("a")
+	plans.Put("sig", &TaskPlan{Tasks: []*Task{{ID: "t1", Agent: "memo", Input: "x"}}})
+	plans.Sync("a")
+	assert.Equal(t, 1, plans.Len())
+	plans.Sync("b")
+	assert.Zero(t, plans.Len())
+}
+
+func TestNormalizeIntent(t *testing.T) {
+	assert.Equal(t, "总结今天的笔记 列出明天的日程", normalizeIntent(" 总结今天的笔记，\n列出明天的日程。"))
+	assert.Equal(t, "hello world", normalizeIntent("Hello,   WORLD!"))
+
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, intentSignature("Hello world", now, "f"), intentSignature("hello, world", now, "f"))
+	assert.NotEqual(t, intentSignature("hello world", now, "f"), intentSignature("hello world", now.AddDate(0, 0, 1), "f"))
+	assert.NotEqual(t, intentSignature("hello world", now, "f"), intentSignature("hello world", now, "g"))
+}
@@ -31,6 +31,7 @@ type DecomposerPrompts struct {
 	UserRequestTemplate  string `yaml:"user_request_template"`
 	TimeContextTemplate  string `yaml:"time_context_template"`
 	Examples             string `yaml:"examples"`
+	ClarificationRules   string `yaml:"clarification_rules"`
 }
 
 // AggregatorPrompts holds prompts for result aggregation.
@@ -145,6 +146,11 @@ Output:
   ],
   "aggregate": true
 }`,
+			ClarificationRules: `## 澄清规则
+当前请求的意图路由置信度较低。如果无法判断应交给哪个专家（例如"明天下午三点和老王"既可能是日程也可能是笔记），不要猜测，改为输出一个澄清问题：
+{"analysis": "歧义说明", "clarification": {"question": "一个简短的问题", "options": ["选项1", "选项2"]}}
+- 只问一个问题，提供 2-4 个简短选项
+- 意图清晰或可直接回答时，按正常格式输出，不要澄清`,
 		},
 		Aggregator: AggregatorPrompts{
 			SystemContext: "你是 DivineSense 的结果整合助手。将多个专家的结果合并为连贯回复。",
@@ -171,9 +177,15 @@ Output:
 
 // BuildDecomposerPrompt builds the full decomposition prompt from config.
 // If history is provided, it will be included to enable context-aware task decomposition.
-func (c *PromptConfig) BuildDecomposerPrompt(userInput, expertDescriptions string, timeContext *universal.TimeContext, history []string) string {
+// If clarify is true, the LLM may answer with a clarifying question instead of tasks.
+func (c *PromptConfig) BuildDecomposerPrompt(userInput, expertDescriptions string, timeContext *universal.TimeContext, history []string, clarify bool) string {
 	d := c.Decomposer
 
+	rules := d.Rules
+	if clarify && d.ClarificationRules != "" {
+		rules += "\n\n" + d.ClarificationRules
+	}
+
 	// Build time context section
 	timeContextSection := ""
 	if timeContext != nil && d.TimeContextTemplate != "" {
@@ -208,7 +220,7 @@ func (c *PromptConfig) BuildDecomposerPrompt(userInput, expertDescriptions strin
 %s
 %s
 
-%s`, d.SystemContext, expertDescriptions, historySection, timeContextSection, d.AnalysisInstructions, d.OutputFormat, d.Rules, d.Examples,
+%s`, d.SystemContext, expertDescriptions, historySection, timeContextSection, d.AnalysisInstructions, d.OutputFormat, rules, d.Examples,
 		fmt.Sprintf(d.UserRequestTemplate, userInput))
 }
 
@@ -196,6 +196,13 @@ type TaskPlan struct {
 	// This field is populated by the Decomposer when it determines the task
 	// can be handled directly without expert agents.
 	Response string `json:"response,omitempty"`
+
+	// Clarification is set when the request is too ambiguous to route and the
+	// Decomposer asks the user a clarifying question instead of guessing.
+	Clarification *Clarification `json:"clarification,omitempty"`
+
+	// fallback marks plans made without the LLM after a failed decomposition
+	fallback bool
 }
 
 // ExecutionResult represents the result of executing a task plan.
@@ -214,6 +221,12 @@ type ExecutionResult struct {
 
 	// Errors contains any errors that occurred during execution
 	Errors []string `json:"errors,omitempty"`
+
+	// Clarification is the question to ask the user; no tasks were executed
+	Clarification *Clarification `json:"clarification,omitempty"`
+
+	// Warnings are the failed validation checks of the tasks' outputs
+	Warnings []ValidationWarning `json:"warnings,omitempty"`
 }
 
 // TokenUsage tracks token consumption for the orchestration.
@@ -249,6 +262,31 @@ type OrchestratorConfig struct {
 
 	// RetryBackoff is the initial backoff duration for retries
 	RetryBackoff time.Duration `json:"retry_backoff"`
+
+	// MaxRetryBackoff caps the exponential backoff between retries (0 is uncapped)
+	MaxRetryBackoff time.Duration `json:"max_retry_backoff"`
+
+	// MaxPlanDuration caps the execution of a plan, retries included (0 is
+	// uncapped); the deadline of the request applies when it is sooner
+	MaxPlanDuration time.Duration `json:"max_plan_duration"`
+
+	// PlanCacheSize is the number of task plans cached by intent signature
+	// (0 disables plan caching)
+	PlanCacheSize int `json:"plan_cache_size"`
+
+	// PlanCacheTTL is how long a cached task plan is reused
+	PlanCacheTTL time.Duration `json:"plan_cache_ttl"`
+
+	// Validators check the output of each completed task before aggregation
+	Validators []ResultValidator `json:"-"`
+
+	// MaxClarificationRounds is the maximum number of clarifying questions asked
+	// for one request before falling back to the memo expert (0 disables)
+	MaxClarificationRounds int `json:"max_clarification_rounds"`
+
+	// ClarificationThreshold is the routing confidence below which the
+	// orchestrator may ask a clarifying question
+	ClarificationThreshold float64 `json:"clarification_threshold"`
 }
 
 // DefaultOrchestratorConfig returns the default configuration.
@@ -262,6 +300,14 @@ func DefaultOrchestratorConfig() *OrchestratorConfig {
 		DefaultLanguage:    "zh",
 		MaxRetries:         3,
 		RetryBackoff:       time.Second,
+		MaxRetryBackoff:    8 * time.Second,
+		MaxPlanDuration:    5 * time.Minute,
+		PlanCacheSize:      256,
+		PlanCacheTTL:       6 * time.Hour,
+		Validators:         DefaultValidators(),
+
+		MaxClarificationRounds: 2,
+		ClarificationThreshold: 0.6,
 	}
 }
 
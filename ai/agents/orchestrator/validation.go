@@ -0,0 +1,188 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// EventTypeValidationWarning is sent when a validator flags the output of a
+// completed task; EventData is the JSON-encoded ValidationWarning.
+const EventTypeValidationWarning = "validation_warning"
+
+// ValidationWarning is a failed check of an expert's output.
+type ValidationWarning struct {
+	TaskID  string `json:"task_id"`
+	Agent   string `json:"agent"`
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
+
+// ResultValidator checks the output of a completed task before aggregation.
+// Validators must be cheap: they run for every task of every plan. A failed
+// check only warns, the output is still used.
+type ResultValidator interface {
+	// Name identifies the check in warnings.
+	Name() string
+	// Validate returns the problems found in the task's result, if any.
+	Validate(ctx context.Context, task *Task) []string
+}
+
+// DefaultValidators returns the validators that need no dependencies: the
+// schema check and the schedule date sanity check.
+func DefaultValidators() []ResultValidator {
+	return []ResultValidator{SchemaValidator{}, ScheduleDateValidator{}}
+}
+
+// validateResults runs the validators over the completed tasks of a plan and
+// sends a validation_warning event per failed check.
+func validateResults(ctx context.Context, validators []ResultValidator, plan *TaskPlan, callback EventCallback, traceID string) []ValidationWarning {
+	var warnings []ValidationWarning
+	for _, task := range plan.Tasks {
+		if task.GetStatus() != TaskStatusCompleted {
+			continue
+		}
+		for _, validator := range validators {
+			for _, problem := range validator.Validate(ctx, task) {
+				warning := ValidationWarning{
+					TaskID:  task.ID,
+					Agent:   task.Agent,
+					Check:   validator.Name(),
+					Message: problem,
+				}
+				warnings = append(warnings, warning)
+
+				slog.Warn("orchestrator: expert output failed validation",
+					"trace_id", traceID,
+					"task_id", task.ID,
+					"agent", task.Agent,
+					"check", warning.Check,
+					"message", problem)
+				if callback != nil {
+					data, _ := json.Marshal(warning)
+					callback(EventTypeValidationWarning, string(data))
+				}
+			}
+		}
+	}
+	return warnings
+}
+
+// SchemaValidator checks that a result is non-empty UTF-8 text, and valid
+// JSON when it is shaped like JSON.
+type SchemaValidator struct{}
+
+// Name implements ResultValidator.
+func (SchemaValidator) Name() string { return "schema" }
+
+// Validate implements ResultValidator.
+func (SchemaValidator) Validate(_ context.Context, task *Task) []string {
+	result := strings.TrimSpace(task.GetResult())
+	switch {
+	case result == "":
+		return []string{"empty result"}
+	case !utf8.ValidString(result):
+		return []string{"result is not valid UTF-8"}
+	case (strings.HasPrefix(result, "{") && strings.HasSuffix(result, "}")) ||
+		(strings.HasPrefix(result, "[") && strings.HasSuffix(result, "]")):
+		if !json.Valid([]byte(result)) {
+			return []string{"result looks like JSON but does not parse"}
+		}
+	}
+	return nil
+}
+
+var (
+	isoDatePattern   = regexp.MustCompile(`\b(\d{4})-(\d{1,2})-(\d{1,2})\b`)
+	clockTimePattern = regexp.MustCompile(`(?:^|[^\d:])(\d{1,2}):(\d{2})(?:[^\d:]|$)`)
+)
+
+// scheduleDateRange is how far from now a date in a schedule result may be
+// before it is reported as suspicious.
+const scheduleDateRange = 10 * 365 * 24 * time.Hour
+
+// ScheduleDateValidator checks the dates and times of the schedule expert's
+// results: they must exist (no February 30th or 25:00) and be within ten
+// years of now.
+type ScheduleDateValidator struct {
+	// Now returns the current time; defaults to time.Now.
+	Now func() time.Time
+}
+
+// Name implements ResultValidator.
+func (ScheduleDateValidator) Name() string { return "schedule_dates" }
+
+// Validate implements ResultValidator.
+func (v ScheduleDateValidator) Validate(_ context.Context, task *Task) []string {
+	if task.Agent != "schedule" {
+		return nil
+	}
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+
+	result := task.GetResult()
+	var problems []string
+	for _, match := range isoDatePattern.FindAllStringSubmatch(result, -1) {
+		year, _ := strconv.Atoi(match[1])
+		month, _ := strconv.Atoi(match[2])
+		day, _ := strconv.Atoi(match[3])
+		date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, now.Location())
+		if date.Year() != year || int(date.Month()) != month || date.Day() != day {
+			problems = append(problems, fmt.Sprintf("invalid date %s", match[0]))
+			continue
+		}
+		if d := date.Sub(now); d > scheduleDateRange || d < -scheduleDateRange {
+			problems = append(problems, fmt.Sprintf("date %s is more than ten years away", match[0]))
+		}
+	}
+	for _, match := range clockTimePattern.FindAllStringSubmatch(result, -1) {
+		hour, _ := strconv.Atoi(match[1])
+		minute, _ := strconv.Atoi(match[2])
+		if hour > 24 || minute > 59 || (hour == 24 && minute != 0) {
+			problems = append(problems, fmt.Sprintf("invalid time %s:%s", match[1], match[2]))
+		}
+	}
+	return problems
+}
+
+var memoCitationPattern = regexp.MustCompile(`/memos/([A-Za-z0-9_-]+)`)
+
+// MemoCitationValidator checks that the memos cited by a result (links to
+// /memos/{uid}) exist.
+type MemoCitationValidator struct {
+	// Exists reports whether the memo of a UID exists.
+	Exists func(ctx context.Context, uid string) (bool, error)
+}
+
+// Name implements ResultValidator.
+func (MemoCitationValidator) Name() string { return "memo_citations" }
+
+// Validate implements ResultValidator.
+func (v MemoCitationValidator) Validate(ctx context.Context, task *Task) []string {
+	var problems []string
+	seen := make(map[string]bool)
+	for _, match := range memoCitationPattern.FindAllStringSubmatch(task.GetResult(), -1) {
+		uid := match[1]
+		if seen[uid] {
+			continue
+		}
+		seen[uid] = true
+		exists, err := v.Exists(ctx, uid)
+		if err != nil {
+			slog.Warn("orchestrator: failed to check memo citation", "uid", uid, "error", err)
+			continue
+		}
+		if !exists {
+			problems = append(problems, fmt.Sprintf("cited memo %s does not exist", uid))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,83 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func completedTask(agent, result string) *Task {
+	task := &Task{ID: "t1", Agent: agent}
+	task.SetResult(result)
+	return task
+}
+
+func TestSchemaValidator(t *testing.T) {
+	v := SchemaValidator{}
+	assert.Equal(t, []string{"empty result"}, v.Validate(context.Background(), completedTask("memo", "  ")))
+	assert.Equal(t, []string{"result looks like JSON but does not parse"}, v.Validate(context.Background(), completedTask("memo", `{"a":`+"\n}")))
+	assert.Empty(t, v.Validate(context.Background(), completedTask("memo", `{"a":1}`)))
+	assert.Empty(t, v.Validate(context.Background(), completedTask("memo", "[标题](/memos/abc) 总结")))
+}
+
+func TestScheduleDateValidator(t *testing.T) {
+	v := ScheduleDateValidator{Now: func() time.Time { return time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC) }}
+
+	problems := v.Validate(context.Background(), completedTask("schedule",
+		"2026-10-17 15:00 评审会\n2027-02-30 10:00 复盘\n2046-01-01 09:00 远期\n2026-10-18 25:30 夜跑"))
+	assert.Equal(t, []string{
+		"invalid date 2027-02-30",
+		"date 2046-01-01 is more than ten years away",
+		"invalid time 25:30",
+	}, problems)
+
+	assert.Empty(t, v.Validate(context.Background(), completedTask("memo", "2027-02-30")), "only schedule results are checked")
+	assert.Empty(t, v.Validate(context.Background(), completedTask("schedule", "明天 09:30 站会，见 http://127.0.0.1:5230")))
+}
+
+func TestMemoCitationValidator(t *testing.T) {
+	var checked []string
+	v := MemoCitationValidator{Exists: func(_ context.Context, uid string) (bool, error) {
+		checked = append(checked, uid)
+		return uid == "m_001", nil
+	}}
+
+	problems := v.Validate(context.Background(), completedTask("memo",
+		"| 1 | [配置](http://x/memos/m_001) |\n| 2 | [评审](http://x/memos/m_404) |\n[again](http://x/memos/m_404)"))
+	assert.Equal(t, []string{"cited memo m_404 does not exist"}, problems)
+	assert.Equal(t, []string{"m_001", "m_404"}, checked)
+}
+
+func TestProcess_EmitsValidationWarnings(t *testing.T) {
+	registry := new(MockRegistry)
+	registry.On("GetAvailableExperts").Return([]string{"memo", "schedule"})
+	registry.On("GetExpertDescription", mock.Anything).Return("expert")
+	registry.On("GetExpertConfig", mock.Anything).Return(nil)
+	registry.On("ExecuteExpert", mock.Anything, "memo", mock.Anything, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			args.Get(3).(EventCallback)("content", "[评审](/memos/m_404)")
+		})
+	registry.On("ExecuteExpert", mock.Anything, "schedule", mock.Anything, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			args.Get(3).(EventCallback)("content", "明天 10:00 站会")
+		})
+
+	missing := MemoCitationValidator{Exists: func(context.Context, string) (bool, error) { return false, nil }}
+	orch := NewOrchestrator(&fixedLLM{response: multiIntentResponse}, registry,
+		WithHandoff(false), WithAggregation(false), WithPlanCache(0, 0), WithValidators(missing))
+
+	var events []string
+	result, err := orch.Process(context.Background(), "总结今天的笔记并列出明天的日程", func(eventType, eventData string) {
+		if eventType == EventTypeValidationWarning {
+			events = append(events, eventData)
+		}
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, ValidationWarning{TaskID: "t1", Agent: "memo", Check: "memo_citations", Message: "cited memo m_404 does not exist"}, result.Warnings[0])
+	assert.Equal(t, []string{`{"task_id":"t1","agent":"memo","check":"memo_citations","message":"cited memo m_404 does not exist"}`}, events)
+}
@@ -0,0 +1,261 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hrygo/hotplex"
+)
+
+// AgentRunner runs the prompts of Geek and Evolution modes on a coding agent
+// backend, in sessions that keep their own history.
+type AgentRunner interface {
+	// Execute runs a prompt in the session of cfg, streaming its events.
+	Execute(ctx context.Context, cfg *CCRunnerConfig, prompt string, callback EventCallback) error
+	// GetSessionStats returns the accumulated stats of a session, or nil.
+	GetSessionStats(sessionID string) *SessionStats
+	// Model returns the model a session runs on, or "" if unknown.
+	Model(sessionID string) string
+	StopSession(sessionID string, reason string) error
+	Close() error
+}
+
+// Runner backends built in.
+const (
+	RunnerBackendClaude   = "claude"
+	RunnerBackendOpenCode = "opencode"
+)
+
+// ErrUnknownRunnerBackend is returned for backends that are not registered.
+var ErrUnknownRunnerBackend = errors.New("unknown agent runner backend")
+
+// RunnerBackend creates the runners of a backend.
+type RunnerBackend struct {
+	// New creates a runner; it fails if the backend is not installed.
+	New func(timeout time.Duration, logger *slog.Logger, opts ...CCRunnerOption) (AgentRunner, error)
+	// Check verifies the installed backend at startup; nil checks nothing.
+	Check func(ctx context.Context) error
+}
+
+var (
+	runnerBackendsMu sync.RWMutex
+	runnerBackends   = map[string]RunnerBackend{
+		RunnerBackendClaude: {
+			New: func(timeout time.Duration, logger *slog.Logger, opts ...CCRunnerOption) (AgentRunner, error) {
+				runner, err := NewCCRunner(timeout, logger, opts...)
+				if err != nil {
+					return nil, err
+				}
+				return runner, nil
+			},
+			Check: func(ctx context.Context) error {
+				return DetectCLI(ctx, "claude").Err()
+			},
+		},
+		RunnerBackendOpenCode: {
+			New: func(timeout time.Duration, logger *slog.Logger, opts ...CCRunnerOption) (AgentRunner, error) {
+				runner, err := NewOpenCodeRunner(timeout, logger, opts...)
+				if err != nil {
+					return nil, err
+				}
+				return runner, nil
+			},
+		},
+	}
+)
+
+// RegisterRunnerBackend adds or replaces a runner backend, e.g. another CLI
+// or a runner calling a model API directly.
+func RegisterRunnerBackend(name string, backend RunnerBackend) {
+	runnerBackendsMu.Lock()
+	defer runnerBackendsMu.Unlock()
+	runnerBackends[name] = backend
+}
+
+// RunnerBackends returns the names of the registered backends.
+func RunnerBackends() []string {
+	runnerBackendsMu.RLock()
+	defer runnerBackendsMu.RUnlock()
+	names := make([]string, 0, len(runnerBackends))
+	for name := range runnerBackends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func runnerBackend(name string) (RunnerBackend, error) {
+	runnerBackendsMu.RLock()
+	defer runnerBackendsMu.RUnlock()
+	backend, ok := runnerBackends[name]
+	if !ok {
+		return RunnerBackend{}, fmt.Errorf("%w: %q (registered: %v)", ErrUnknownRunnerBackend, name, RunnerBackends())
+	}
+	return backend, nil
+}
+
+// NewAgentRunner creates a runner of a backend; "" is the Claude Code CLI.
+func NewAgentRunner(backend string, timeout time.Duration, logger *slog.Logger, opts ...CCRunnerOption) (AgentRunner, error) {
+	if backend == "" {
+		backend = RunnerBackendClaude
+	}
+	b, err := runnerBackend(backend)
+	if err != nil {
+		return nil, err
+	}
+	return b.New(timeout, logger, opts...)
+}
+
+// CheckRunnerBackend verifies a backend at startup; "" is the Claude Code CLI.
+func CheckRunnerBackend(ctx context.Context, backend string) error {
+	if backend == "" {
+		backend = RunnerBackendClaude
+	}
+	b, err := runnerBackend(backend)
+	if err != nil || b.Check == nil {
+		return err
+	}
+	return b.Check(ctx)
+}
+
+// engineRunner runs prompts on a hotplex engine, whatever the CLI its
+// provider drives: the provider builds the CLI arguments and parses its
+// output into normalized events.
+type engineRunner struct {
+	engine     hotplex.HotPlexClient
+	adminToken string // Token for SetDangerBypassEnabled calls
+	namespace  string
+	models     *modelTrackingProvider // nil if the CLI does not report its model
+	tools      *toolPolicyProvider    // nil if the CLI takes no tool flags
+	mcp        *mcpConfigProvider     // nil if the CLI takes no MCP config
+
+	onToolViolation ToolViolationHandler
+	onToolUse       ToolUseHandler
+}
+
+// newEngineRunner creates the engine of a runner on a provider.
+func newEngineRunner(prv hotplex.Provider, timeout time.Duration, logger *slog.Logger, opt *ccRunnerOptions) (*engineRunner, error) {
+	// Default namespace
+	namespace := opt.namespace
+	if namespace == "" {
+		namespace = "divinesense"
+	}
+
+	engine, err := hotplex.NewEngine(hotplex.EngineOptions{
+		Timeout:          timeout,
+		IdleTimeout:      30 * time.Minute,
+		Logger:           logger,
+		Namespace:        namespace,
+		BaseSystemPrompt: opt.baseSystemPrompt,
+		AdminToken:       opt.adminToken,
+		Provider:         prv,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &engineRunner{
+		engine:          engine,
+		adminToken:      opt.adminToken,
+		namespace:       namespace,
+		onToolViolation: opt.onToolViolation,
+		onToolUse:       opt.onToolUse,
+	}, nil
+}
+
+// execute runs a prompt in the session of cfg.
+func (r *engineRunner) execute(ctx context.Context, cfg *CCRunnerConfig, prompt string, callback EventCallback) error {
+	if cfg.SessionID == "" && cfg.ConversationID > 0 {
+		cfg.SessionID = ConversationIDToSessionID(cfg.ConversationID)
+	}
+
+	hotplexCfg := &hotplex.Config{
+		WorkDir:          cfg.WorkDir,
+		SessionID:        cfg.SessionID,
+		TaskInstructions: cfg.TaskInstructions,
+	}
+
+	if cfg.PermissionMode == "bypassPermissions" && r.adminToken != "" {
+		if err := r.engine.SetDangerBypassEnabled(r.adminToken, true); err != nil {
+			return fmt.Errorf("failed to enable danger bypass: %w", err)
+		}
+	}
+
+	// A running CLI keeps the tools and MCP servers it started with: restart
+	// it when they change
+	cliSession := cliSessionID(r.namespace, cfg.SessionID)
+	restart := r.tools != nil && r.tools.SetPolicy(cliSession, cfg.Tools)
+	if r.mcp != nil {
+		changed, err := r.mcp.SetServers(cliSession, cfg.MCPServers)
+		if err != nil {
+			return fmt.Errorf("failed to configure MCP servers: %w", err)
+		}
+		restart = restart || changed
+	}
+	if restart {
+		if err := r.engine.StopSession(cfg.SessionID, "tools changed"); err == nil {
+			slog.Info("cc_runner: session restarted with new tools", "session_id", cfg.SessionID)
+		}
+	}
+
+	// Record the tool calls of the session, e.g. in the audit log
+	uses := &toolUseRecorder{cfg: cfg, handler: r.onToolUse}
+	gate := &toolGate{cfg: cfg, block: func(v *ToolViolation) {
+		r.blockToolViolation(ctx, v)
+		uses.blocked(ctx, v)
+		// Tell the client why its session stopped, as the danger detector does
+		if callback != nil {
+			_ = callback(EventTypeDangerBlock, v.dangerBlock())
+		}
+	}}
+	cb := func(eventType string, data any) error {
+		// Reject the tools the policy does not permit, stopping the session
+		if !gate.admit(eventType, data) {
+			return nil
+		}
+		uses.observe(ctx, eventType, data)
+		if callback == nil {
+			return nil
+		}
+		// Report session stats with the session context and the actual model
+		if stats, ok := data.(*hotplex.SessionStatsData); ok {
+			data = toSessionStatsData(stats, cfg, r.Model(cfg.SessionID))
+		}
+		return callback(eventType, data)
+	}
+
+	err := r.engine.Execute(ctx, hotplexCfg, prompt, cb)
+	uses.finish(ctx)
+	if v := gate.violation.Load(); v != nil {
+		if v.Err != nil {
+			return fmt.Errorf("%s rejected: %w", v.Tool, v.Err)
+		}
+		return fmt.Errorf("%w: %s", ErrToolNotPermitted, v.Tool)
+	}
+	return err
+}
+
+func (r *engineRunner) Close() error {
+	return r.engine.Close()
+}
+
+func (r *engineRunner) GetSessionStats(sessionID string) *SessionStats {
+	return r.engine.GetSessionStats(sessionID)
+}
+
+// Model returns the model the CLI of a session runs on, as reported in its
+// stream, or "" if it has not been reported yet.
+func (r *engineRunner) Model(sessionID string) string {
+	if r.models == nil {
+		return ""
+	}
+	return r.models.Model(cliSessionID(r.namespace, sessionID))
+}
+
+func (r *engineRunner) StopSession(sessionID string, reason string) error {
+	return r.engine.StopSession(sessionID, reason)
+}
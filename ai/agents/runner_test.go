@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeRunner is an AgentRunner answering every prompt.
+type fakeRunner struct {
+	engineRunner
+}
+
+func (r *fakeRunner) Execute(_ context.Context, _ *CCRunnerConfig, prompt string, callback EventCallback) error {
+	return callback(EventTypeAnswer, prompt)
+}
+
+func TestNewAgentRunner_Backends(t *testing.T) {
+	if _, err := NewAgentRunner("aider", time.Minute, slog.Default()); !errors.Is(err, ErrUnknownRunnerBackend) {
+		t.Fatalf("NewAgentRunner(aider) error = %v, want ErrUnknownRunnerBackend", err)
+	}
+	if err := CheckRunnerBackend(context.Background(), "aider"); !errors.Is(err, ErrUnknownRunnerBackend) {
+		t.Fatalf("CheckRunnerBackend(aider) error = %v, want ErrUnknownRunnerBackend", err)
+	}
+
+	checked := false
+	RegisterRunnerBackend("fake", RunnerBackend{
+		New: func(time.Duration, *slog.Logger, ...CCRunnerOption) (AgentRunner, error) {
+			return &fakeRunner{}, nil
+		},
+		Check: func(context.Context) error {
+			checked = true
+			return nil
+		},
+	})
+	t.Cleanup(func() {
+		runnerBackendsMu.Lock()
+		delete(runnerBackends, "fake")
+		runnerBackendsMu.Unlock()
+	})
+	if !slices.Contains(RunnerBackends(), "fake") {
+		t.Fatalf("RunnerBackends() = %v, want fake registered", RunnerBackends())
+	}
+	if err := CheckRunnerBackend(context.Background(), "fake"); err != nil || !checked {
+		t.Fatalf("CheckRunnerBackend(fake) = %v, checked %v", err, checked)
+	}
+
+	runner, err := NewAgentRunner("fake", time.Minute, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var answer any
+	if err := runner.Execute(context.Background(), &CCRunnerConfig{}, "hi", func(_ string, data any) error {
+		answer = data
+		return nil
+	}); err != nil || answer != "hi" {
+		t.Fatalf("Execute = %v, answer %v", err, answer)
+	}
+	if model := runner.Model("session"); model != "" {
+		t.Errorf("Model() = %q for a runner not reporting models", model)
+	}
+}
+
+func TestNewAgentRunner_OpenCodeNotInstalled(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	runner, err := NewAgentRunner(RunnerBackendOpenCode, time.Minute, slog.Default())
+	if err == nil {
+		t.Fatal("NewAgentRunner(opencode) should fail without the CLI")
+	}
+	if runner != nil {
+		t.Errorf("runner = %#v, want a nil interface disabling the mode", runner)
+	}
+}
+
+func TestNewAgentRunner_OpenCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake CLI is a shell script")
+	}
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "opencode"), []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir)
+
+	runner, err := NewAgentRunner(RunnerBackendOpenCode, time.Minute, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer runner.Close()
+	if _, ok := runner.(*OpenCodeRunner); !ok {
+		t.Fatalf("runner = %T, want *OpenCodeRunner", runner)
+	}
+	if err := CheckRunnerBackend(context.Background(), RunnerBackendOpenCode); err != nil {
+		t.Errorf("CheckRunnerBackend(opencode) = %v", err)
+	}
+}
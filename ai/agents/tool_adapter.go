@@ -521,6 +521,11 @@ func shouldEarlyStop(toolResult string) bool {
 		return true
 	}
 
+	// A form request hands the turn back to the user until the form is submitted
+	if strings.Contains(toolResult, "FORM_REQUESTED:") {
+		return true
+	}
+
 	return false
 }
 
@@ -0,0 +1,231 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lithammer/shortuuid/v4"
+)
+
+// FormRequestMarker prefixes the tool result of request_form.
+// The chat handler detects it, emits a form_request event and the agent stops,
+// waiting for the user to submit the form.
+const FormRequestMarker = "FORM_REQUESTED:"
+
+// Form field types supported by the client renderer.
+const (
+	FormFieldText    = "text"
+	FormFieldNumber  = "number"
+	FormFieldSelect  = "select"
+	FormFieldBoolean = "boolean"
+	FormFieldDate    = "date"
+)
+
+// MaxFormFields bounds how many fields a single form may contain.
+const MaxFormFields = 10
+
+var formFieldTypes = []string{FormFieldText, FormFieldNumber, FormFieldSelect, FormFieldBoolean, FormFieldDate}
+
+// FormField describes one input of an in-chat form.
+type FormField struct {
+	Name     string   `json:"name"`
+	Label    string   `json:"label"`
+	Type     string   `json:"type"`
+	Options  []string `json:"options,omitempty"`
+	Required bool     `json:"required,omitempty"`
+}
+
+// FormRequest is a structured clarifying question rendered by the client as a form
+// or a set of buttons (a single select field).
+type FormRequest struct {
+	ID          string      `json:"id"`
+	Title       string      `json:"title"`
+	Description string      `json:"description,omitempty"`
+	Fields      []FormField `json:"fields"`
+	SubmitLabel string      `json:"submit_label,omitempty"`
+}
+
+// Validate checks the form definition produced by the agent.
+func (f *FormRequest) Validate() error {
+	if strings.TrimSpace(f.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	if len(f.Fields) == 0 || len(f.Fields) > MaxFormFields {
+		return fmt.Errorf("fields must contain between 1 and %d entries", MaxFormFields)
+	}
+	seen := make(map[string]bool, len(f.Fields))
+	for i := range f.Fields {
+		field := &f.Fields[i]
+		if field.Name == "" {
+			return fmt.Errorf("field %d: name is required", i)
+		}
+		if seen[field.Name] {
+			return fmt.Errorf("field %s: duplicate name", field.Name)
+		}
+		seen[field.Name] = true
+		if field.Type == "" {
+			field.Type = FormFieldText
+		}
+		if !slices.Contains(formFieldTypes, field.Type) {
+			return fmt.Errorf("field %s: unsupported type %q", field.Name, field.Type)
+		}
+		if field.Type == FormFieldSelect && len(field.Options) == 0 {
+			return fmt.Errorf("field %s: select requires options", field.Name)
+		}
+		if field.Label == "" {
+			field.Label = field.Name
+		}
+	}
+	return nil
+}
+
+// ValidateResponse checks a submitted payload against the form definition.
+func (f *FormRequest) ValidateResponse(payload map[string]any) error {
+	for _, field := range f.Fields {
+		value, ok := payload[field.Name]
+		if !ok || value == nil || value == "" {
+			if field.Required {
+				return fmt.Errorf("field %s is required", field.Name)
+			}
+			continue
+		}
+		switch field.Type {
+		case FormFieldNumber:
+			if _, ok := value.(float64); !ok {
+				return fmt.Errorf("field %s must be a number", field.Name)
+			}
+		case FormFieldBoolean:
+			if _, ok := value.(bool); !ok {
+				return fmt.Errorf("field %s must be a boolean", field.Name)
+			}
+		case FormFieldSelect:
+			s, ok := value.(string)
+			if !ok || !slices.Contains(field.Options, s) {
+				return fmt.Errorf("field %s must be one of the options", field.Name)
+			}
+		default:
+			if _, ok := value.(string); !ok {
+				return fmt.Errorf("field %s must be a string", field.Name)
+			}
+		}
+	}
+	for name := range payload {
+		if !slices.ContainsFunc(f.Fields, func(field FormField) bool { return field.Name == name }) {
+			return fmt.Errorf("unknown field %s", name)
+		}
+	}
+	return nil
+}
+
+// FormatResponse renders a submitted payload as the user message fed back to the agent.
+func (f *FormRequest) FormatResponse(payload map[string]any) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "[%s]\n", f.Title)
+	for _, field := range f.Fields {
+		value, ok := payload[field.Name]
+		if !ok || value == nil {
+			continue
+		}
+		fmt.Fprintf(&sb, "- %s: %v\n", field.Label, value)
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// ParseFormRequest extracts the form from a request_form tool result.
+// Returns false if the result does not carry a form.
+func ParseFormRequest(toolResult string) (*FormRequest, bool) {
+	idx := strings.Index(toolResult, FormRequestMarker)
+	if idx < 0 {
+		return nil, false
+	}
+	var form FormRequest
+	if err := json.Unmarshal([]byte(strings.TrimSpace(toolResult[idx+len(FormRequestMarker):])), &form); err != nil {
+		return nil, false
+	}
+	return &form, true
+}
+
+// FormRequestTool lets an agent ask a structured clarifying question.
+type FormRequestTool struct{}
+
+// NewFormRequestTool creates a new request_form tool.
+func NewFormRequestTool() *FormRequestTool {
+	return &FormRequestTool{}
+}
+
+// Name returns the name of the tool.
+func (t *FormRequestTool) Name() string {
+	return "request_form"
+}
+
+// Description returns a description of what the tool does.
+func (t *FormRequestTool) Description() string {
+	return `Asks the user a structured clarifying question as an in-chat form or buttons.
+
+Use this instead of a free-text question when required information is missing
+and the possible answers are known (e.g. pick a time slot, confirm a deletion).
+After calling this tool, stop and wait: the user's answers arrive as the next message.
+
+INPUT FORMAT:
+{"title": "...", "fields": [{"name": "slot", "label": "Time slot", "type": "select", "options": ["09:00", "14:00"], "required": true}]}
+
+Field types: text, number, select, boolean, date. A single select field renders as buttons.`
+}
+
+// InputType returns the JSON schema for the tool's input parameters.
+func (t *FormRequestTool) InputType() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"title": map[string]interface{}{
+				"type":        "string",
+				"description": "The question shown above the form",
+			},
+			"description": map[string]interface{}{
+				"type":        "string",
+				"description": "Optional additional explanation",
+			},
+			"fields": map[string]interface{}{
+				"type":        "array",
+				"description": "Form inputs",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name":     map[string]interface{}{"type": "string"},
+						"label":    map[string]interface{}{"type": "string"},
+						"type":     map[string]interface{}{"type": "string", "enum": formFieldTypes},
+						"options":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+						"required": map[string]interface{}{"type": "boolean"},
+					},
+					"required": []string{"name", "type"},
+				},
+			},
+			"submit_label": map[string]interface{}{
+				"type":        "string",
+				"description": "Optional submit button text",
+			},
+		},
+		"required": []string{"title", "fields"},
+	}
+}
+
+// Run validates the form and returns it behind FormRequestMarker.
+func (t *FormRequestTool) Run(ctx context.Context, input string) (string, error) {
+	var form FormRequest
+	if err := json.Unmarshal([]byte(input), &form); err != nil {
+		return "", fmt.Errorf("invalid JSON input: %w", err)
+	}
+	if err := form.Validate(); err != nil {
+		return "", err
+	}
+	form.ID = shortuuid.New()
+
+	data, err := json.Marshal(&form)
+	if err != nil {
+		return "", fmt.Errorf("encode form: %w", err)
+	}
+	return FormRequestMarker + " " + string(data), nil
+}
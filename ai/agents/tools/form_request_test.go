@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormRequestTool_Run(t *testing.T) {
+	tool := NewFormRequestTool()
+
+	out, err := tool.Run(context.Background(), `{
+		"title": "Pick a slot",
+		"fields": [{"name": "slot", "type": "select", "options": ["09:00", "14:00"], "required": true}]
+	}`)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(out, FormRequestMarker))
+
+	form, ok := ParseFormRequest(out)
+	require.True(t, ok)
+	require.NotEmpty(t, form.ID)
+	require.Equal(t, "slot", form.Fields[0].Label)
+
+	_, err = tool.Run(context.Background(), `{"title": "x", "fields": [{"name": "a", "type": "select"}]}`)
+	require.Error(t, err)
+
+	_, err = tool.Run(context.Background(), `{"title": "x", "fields": []}`)
+	require.Error(t, err)
+
+	_, ok = ParseFormRequest("INABILITY_REPORTED: x - y")
+	require.False(t, ok)
+}
+
+func TestFormRequest_ValidateResponse(t *testing.T) {
+	form := &FormRequest{
+		Title: "Meeting",
+		Fields: []FormField{
+			{Name: "slot", Label: "Slot", Type: FormFieldSelect, Options: []string{"09:00", "14:00"}, Required: true},
+			{Name: "minutes", Label: "Minutes", Type: FormFieldNumber},
+			{Name: "remind", Label: "Remind", Type: FormFieldBoolean},
+		},
+	}
+
+	require.NoError(t, form.ValidateResponse(map[string]any{"slot": "14:00", "minutes": float64(30)}))
+	require.Error(t, form.ValidateResponse(map[string]any{}))
+	require.Error(t, form.ValidateResponse(map[string]any{"slot": "10:00"}))
+	require.Error(t, form.ValidateResponse(map[string]any{"slot": "09:00", "minutes": "thirty"}))
+	require.Error(t, form.ValidateResponse(map[string]any{"slot": "09:00", "extra": true}))
+
+	require.Equal(t, "[Meeting]\n- Slot: 14:00\n- Remind: true",
+		form.FormatResponse(map[string]any{"slot": "14:00", "remind": true}))
+}
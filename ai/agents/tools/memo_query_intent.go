@@ -60,9 +60,9 @@ func (i MemoQueryIntent) ToStrategy() string {
 	case IntentKeyword:
 		return "memo_bm25_only"
 	case IntentSemantic:
-		return "memo_semantic_only"
+		return "memo_hybrid"
 	default:
-		return "memo_semantic_only"
+		return "memo_hybrid"
 	}
 }
 
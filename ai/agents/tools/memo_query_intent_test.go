@@ -166,8 +166,8 @@ func TestMemoQueryIntent_ToStrategy(t *testing.T) {
 		{IntentList, "memo_list_only"},
 		{IntentFilter, "memo_filter_only"},
 		{IntentKeyword, "memo_bm25_only"},
-		{IntentSemantic, "memo_semantic_only"},
-		{MemoQueryIntent(99), "memo_semantic_only"}, // Unknown defaults to semantic
+		{IntentSemantic, "memo_hybrid"},
+		{MemoQueryIntent(99), "memo_hybrid"}, // Unknown defaults to hybrid
 	}
 
 	for _, tt := range tests {
@@ -94,7 +94,7 @@ This tool automatically selects the best retrieval strategy based on query inten
 - List/browsing queries → Time-ordered list
 - Filter queries → Time/tag-based filtering
 - Keyword queries → BM25 text search
-- Semantic queries → Vector similarity search
+- Semantic queries → Vector similarity + keyword search, fused by rank
 - Complex queries → Hybrid retrieval + Rerank
 
 Input: {"query": "keywords", "limit": 10}
@@ -145,6 +145,12 @@ func (t *MemoSearchTool) Run(ctx context.Context, input string) (string, error)
 		searchInput.MinScore = defaultMinScore
 	}
 
+	// The conversation may restrict memo retrieval to some tags, or turn it off
+	scope := retrieval.ScopeFromContext(ctx)
+	if scope.Restricted() && scope.Disabled {
+		return "Memo search is disabled in this conversation; answer without searching memos.", nil
+	}
+
 	// Get user ID
 	userID := t.userIDGetter(ctx)
 
@@ -190,6 +196,11 @@ func (t *MemoSearchTool) Run(ctx context.Context, input string) (string, error)
 		return "", fmt.Errorf("search failed: %w", err)
 	}
 
+	// A conversation bound to a document searches its passages only
+	if doc := retrieval.DocumentFromContext(ctx); doc != nil {
+		return formatDocumentResults(doc, searchInput.Query, results), nil
+	}
+
 	// Filter only memo results (exclude schedules)
 	var memoResults []*retrieval.SearchResult
 	for _, result := range results {
@@ -200,16 +211,28 @@ func (t *MemoSearchTool) Run(ctx context.Context, input string) (string, error)
 
 	// Format results
 	if len(memoResults) == 0 {
+		if scope.Restricted() {
+			return fmt.Sprintf("No memos found matching query: %s (search is limited to %s in this conversation)", searchInput.Query, scope), nil
+		}
 		return fmt.Sprintf("No memos found matching query: %s", searchInput.Query), nil
 	}
 
 	// Build response
 	var response strings.Builder
-	fmt.Fprintf(&response, "Found %d memo(s) matching query: %s\n\n", len(memoResults), searchInput.Query)
+	fmt.Fprintf(&response, "Found %d memo(s) matching query: %s\n", len(memoResults), searchInput.Query)
+	if scope.Restricted() {
+		fmt.Fprintf(&response, "Search is limited to %s in this conversation.\n", scope)
+	}
+	response.WriteString("\n")
 
 	for i, result := range memoResults {
 		fmt.Fprintf(&response, "%d. %s\n", i+1, result.Content)
 
+		// Text recognized in the memo's images and documents
+		if result.AttachmentText != "" {
+			fmt.Fprintf(&response, "   Attachment text: %s\n", strings.ReplaceAll(result.AttachmentText, "\n", " "))
+		}
+
 		// Add memo UID if available
 		if result.Memo != nil && result.Memo.UID != "" {
 			fmt.Fprintf(&response, "   UID: %s\n", result.Memo.UID)
@@ -221,11 +244,33 @@ func (t *MemoSearchTool) Run(ctx context.Context, input string) (string, error)
 	return response.String(), nil
 }
 
+// formatDocumentResults renders the passages of the document bound to the
+// conversation, each headed by its citation.
+func formatDocumentResults(doc *retrieval.Document, query string, results []*retrieval.SearchResult) string {
+	var response strings.Builder
+	fmt.Fprintf(&response, "This conversation is about a single document, %q; only its passages are searched.\n", doc.Title)
+	response.WriteString("Cite every fact taken from a passage with its offsets, e.g. [@120-480].\n\n")
+
+	count := 0
+	for _, result := range results {
+		if result.Type != "document" {
+			continue
+		}
+		count++
+		fmt.Fprintf(&response, "%s %s\n\n", retrieval.CitePassage(result.Start, result.End), result.Content)
+	}
+	if count == 0 {
+		fmt.Fprintf(&response, "No passage of the document matches query: %s", query)
+	}
+	return strings.TrimRight(response.String(), "\n")
+}
+
 // MemoSummary represents a simplified memo for query results.
 type MemoSummary struct {
-	UID     string  `json:"uid"`
-	Content string  `json:"content"`
-	Score   float32 `json:"score"`
+	UID            string  `json:"uid"`
+	Content        string  `json:"content"`
+	AttachmentText string  `json:"attachment_text,omitempty"`
+	Score          float32 `json:"score"`
 }
 
 // MemoSearchToolResult represents the structured result of memo search.
@@ -275,7 +320,7 @@ func (t *MemoSearchTool) RunWithStructuredResult(ctx context.Context, input stri
 	// Set strategy
 	strategy := searchInput.Strategy
 	if strategy == "" {
-		strategy = "memo_semantic_only"
+		strategy = "memo_hybrid"
 	}
 
 	// Execute search
@@ -298,9 +343,10 @@ func (t *MemoSearchTool) RunWithStructuredResult(ctx context.Context, input stri
 	for _, result := range results {
 		if result.Type == "memo" && result.Memo != nil {
 			memos = append(memos, MemoSummary{
-				UID:     result.Memo.UID,
-				Content: result.Content,
-				Score:   result.Score,
+				UID:            result.Memo.UID,
+				Content:        result.Content,
+				AttachmentText: result.AttachmentText,
+				Score:          result.Score,
 			})
 		}
 	}
@@ -9,6 +9,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	aischedule "github.com/hrygo/divinesense/ai/services/schedule"
 )
 
 const DefaultTimezone = "Asia/Shanghai"
@@ -80,6 +82,9 @@ func normalizeJSONFields(inputJSON string) string {
 		"endTime":   "end_time",
 		"allDay":    "all_day",
 		"minScore":  "min_score",
+		// recurrence
+		"recurrenceEnd":  "recurrence_end",
+		"occurrenceDate": "occurrence_date",
 	}
 
 	normalized := make(map[string]interface{})
@@ -127,3 +132,13 @@ func getBool(m map[string]interface{}, key string) bool {
 	}
 	return false
 }
+
+// describeRecurrence describes the JSON recurrence rule of a schedule, e.g.
+// "every Wednesday", or returns "" for invalid rules.
+func describeRecurrence(ruleJSON string) string {
+	rule, err := aischedule.ParseRecurrenceRuleFromJSON(ruleJSON)
+	if err != nil {
+		return ""
+	}
+	return rule.Describe()
+}
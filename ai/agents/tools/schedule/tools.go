@@ -32,9 +32,10 @@ func NewScheduleQueryTool(service schedsvc.Service, userIDGetter func(ctx contex
 func (t *ScheduleQueryTool) Name() string { return "schedule_query" }
 
 func (t *ScheduleQueryTool) Description() string {
-	return `Query existing schedules in a time range.
+	return `Query existing schedules in a time range, with recurring ones expanded into occurrences.
 
 USAGE: Call BEFORE schedule_add to check conflicts.
+Each schedule is listed with its id (for schedule_update/schedule_delete) and uid; ↻ marks recurring ones.
 
 Input: {"start_time": "ISO8601", "end_time": "ISO8601"}
 Example: {"start_time": "2026-01-25T00:00:00+08:00", "end_time": "2026-01-26T00:00:00+08:00"}`
@@ -96,7 +97,13 @@ func (t *ScheduleQueryTool) Run(ctx context.Context, inputJSON string) (string,
 		if s.EndTs != nil {
 			fmt.Fprintf(&b, " - %s", formatTime(*s.EndTs, s.Timezone))
 		}
-		b.WriteString(")\n")
+		fmt.Fprintf(&b, ") [id=%d uid=%s]", s.ID, s.UID)
+		if s.IsRecurring {
+			if description := describeRecurrence(s.RecurrenceRule); description != "" {
+				fmt.Fprintf(&b, " ↻ %s", description)
+			}
+		}
+		b.WriteString("\n")
 	}
 	return b.String(), nil
 }
@@ -123,19 +130,25 @@ CRITICAL RULES:
 3. Call schedule_query FIRST to check for conflicts
 
 Input: {"title": "...", "start_time": "ISO8601", "end_time": "ISO8601"}
-Example: {"title": "Meeting", "start_time": "2026-02-10T15:00:00+08:00", "end_time": "2026-02-10T16:00:00+08:00"}`
+Example: {"title": "Meeting", "start_time": "2026-02-10T15:00:00+08:00", "end_time": "2026-02-10T16:00:00+08:00"}
+
+RECURRING: pass the user's phrase (e.g. "每周三", "工作日", "每两周的周一", "每月15号") or an RRULE as recurrence;
+start_time is the time of the first occurrence and moves to the first matching day.
+Example: {"title": "周会", "start_time": "2026-02-11T15:00:00+08:00", "recurrence": "每周三"}`
 }
 
 func (t *ScheduleAddTool) InputType() map[string]interface{} {
 	return map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
-			"title":       map[string]interface{}{"type": "string", "description": "Event title"},
-			"start_time":  map[string]interface{}{"type": "string", "description": "ISO8601"},
-			"end_time":    map[string]interface{}{"type": "string", "description": "ISO8601 (optional)"},
-			"description": map[string]interface{}{"type": "string", "description": "Optional"},
-			"location":    map[string]interface{}{"type": "string", "description": "Optional"},
-			"all_day":     map[string]interface{}{"type": "boolean", "description": "Default false"},
+			"title":          map[string]interface{}{"type": "string", "description": "Event title"},
+			"start_time":     map[string]interface{}{"type": "string", "description": "ISO8601"},
+			"end_time":       map[string]interface{}{"type": "string", "description": "ISO8601 (optional)"},
+			"description":    map[string]interface{}{"type": "string", "description": "Optional"},
+			"location":       map[string]interface{}{"type": "string", "description": "Optional"},
+			"all_day":        map[string]interface{}{"type": "boolean", "description": "Default false"},
+			"recurrence":     map[string]interface{}{"type": "string", "description": "Optional: phrase like 每周三 or RRULE like FREQ=WEEKLY;BYDAY=WE"},
+			"recurrence_end": map[string]interface{}{"type": "string", "description": "Optional: ISO8601, no occurrence after it"},
 		},
 		"required": []string{"title", "start_time"},
 	}
@@ -189,6 +202,17 @@ func (t *ScheduleAddTool) Run(ctx context.Context, inputJSON string) (string, er
 		AllDay:      getBool(raw, "all_day"),
 		Timezone:    DefaultTimezone,
 	}
+	if recurrence := getString(raw, "recurrence"); recurrence != "" {
+		req.RecurrenceRule = &recurrence
+	}
+	if endStr := getString(raw, "recurrence_end"); endStr != "" {
+		recurrenceEnd, err := parseISO8601(endStr)
+		if err != nil {
+			return "", fmt.Errorf("invalid recurrence_end: %w", err)
+		}
+		ts := recurrenceEnd.Unix()
+		req.RecurrenceEndTs = &ts
+	}
 
 	created, err := t.service.CreateSchedule(ctx, userID, req)
 	if err != nil {
@@ -203,6 +227,14 @@ func (t *ScheduleAddTool) Run(ctx context.Context, inputJSON string) (string, er
 	if created.Location != "" {
 		result += fmt.Sprintf(" @ %s", created.Location)
 	}
+	if created.RecurrenceRule != nil && *created.RecurrenceRule != "" {
+		if description := describeRecurrence(*created.RecurrenceRule); description != "" {
+			result += fmt.Sprintf(" ↻ %s", description)
+		}
+		if created.RecurrenceEndTs != nil {
+			result += fmt.Sprintf(" until %s", formatTime(*created.RecurrenceEndTs, created.Timezone))
+		}
+	}
 	return result, nil
 }
 
@@ -403,14 +435,16 @@ func (t *ScheduleDeleteTool) Description() string {
 	return `Delete an existing schedule.
 
 Input: {"id": 123}
-All fields except id are required.`
+For a recurring schedule, pass occurrence_date to cancel only that occurrence and keep the others
+(e.g. "这周三的周会取消"): {"id": 123, "occurrence_date": "2026-02-11"}`
 }
 
 func (t *ScheduleDeleteTool) InputType() map[string]interface{} {
 	return map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
-			"id": map[string]interface{}{"type": "integer", "description": "Schedule ID to delete"},
+			"id":              map[string]interface{}{"type": "integer", "description": "Schedule ID to delete"},
+			"occurrence_date": map[string]interface{}{"type": "string", "description": "Optional YYYY-MM-DD: skip only this occurrence of a recurring schedule"},
 		},
 		"required": []string{"id"},
 	}
@@ -443,6 +477,14 @@ func (t *ScheduleDeleteTool) Run(ctx context.Context, inputJSON string) (string,
 		return "", fmt.Errorf("unauthorized")
 	}
 
+	if day := getString(raw, "occurrence_date"); day != "" {
+		updated, err := t.service.SkipOccurrence(ctx, userID, scheduleID, day)
+		if err != nil {
+			return "", fmt.Errorf("failed to skip occurrence: %w", err)
+		}
+		return fmt.Sprintf("Skipped the %s occurrence of %s; the other occurrences are kept", day, updated.Title), nil
+	}
+
 	err = t.service.DeleteSchedule(ctx, userID, scheduleID)
 	if err != nil {
 		return "", fmt.Errorf("failed to delete: %w", err)
@@ -72,6 +72,9 @@ type ExecutionStats struct {
 	TotalDurationMs  int64
 	ThinkingDuration int64
 	ToolDurationMs   int64
+
+	// Model is the model of the last LLM call.
+	Model string
 }
 
 // AccumulateLLM adds LLM call statistics to this execution stats.
@@ -85,6 +88,9 @@ func (s *ExecutionStats) AccumulateLLM(llmStats *ai.LLMCallStats) {
 	s.CacheWriteTokens += llmStats.CacheWriteTokens
 	// Track thinking duration (TTFT - Time To First Token)
 	s.ThinkingDuration += llmStats.ThinkingDurationMs
+	if llmStats.Model != "" {
+		s.Model = llmStats.Model
+	}
 }
 
 // StrategyType defines the supported execution strategies.
@@ -37,6 +37,36 @@ type ParrotConfig struct {
 
 	// Metadata
 	SelfDescription *agent.ParrotSelfCognition `json:"self_description" yaml:"self_description"`
+
+	// Rollout (optional): the parrot is a canary of an existing one
+	Canary *CanaryConfig `json:"canary,omitempty" yaml:"canary,omitempty"`
+	// Rollout (optional): the parrot is a shadow of a production one
+	Shadow *ShadowConfig `json:"shadow,omitempty" yaml:"shadow,omitempty"`
+}
+
+// CanaryConfig rolls a new parrot out against the parrot it replaces. A
+// canary is not routed to on its own: it serves a share of the traffic routed
+// to its incumbent, which answers the same requests in shadow for comparison.
+type CanaryConfig struct {
+	// Incumbent is the parrot whose traffic the canary takes a share of.
+	Incumbent string `json:"incumbent" yaml:"incumbent"`
+	// Percent is the initial share of eligible traffic (0-100).
+	Percent int `json:"percent" yaml:"percent"`
+	// Flag (optional) is a feature flag, off by default, restricting the
+	// canary to the users it is on for.
+	Flag string `json:"flag,omitempty" yaml:"flag,omitempty"`
+}
+
+// ShadowConfig runs a modified prompt or model in shadow. A shadow is not
+// routed to: it answers a sample of its production parrot's requests, and its
+// answers are logged for comparison but never shown to users.
+type ShadowConfig struct {
+	// Of is the production parrot the shadow is a variant of.
+	Of string `json:"of" yaml:"of"`
+	// SampleRate is the initial percentage of requests run in shadow (0-100).
+	SampleRate int `json:"sample_rate" yaml:"sample_rate"`
+	// Model overrides the LLM model (optional).
+	Model string `json:"model,omitempty" yaml:"model,omitempty"`
 }
 
 // ToolSetConfig defines a set of tools for a parrot.
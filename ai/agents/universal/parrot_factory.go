@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/hrygo/divinesense/ai"
@@ -26,11 +27,34 @@ type ParrotFactory struct {
 	toolFactories    map[string]ToolFactoryFunc // Dynamic tool creation
 	retrieverFactory func() any                 // Retriever factory
 	scheduleFactory  func() any                 // Schedule service factory
+	dynamicTools     DynamicToolsFunc           // Runtime-registered tools (e.g. HTTP actions)
+	fewShots         FewShotsFunc               // Admin-curated answer examples
+	modelLLM         ModelLLMFunc               // LLMs of the models of shadows
+	agentLLM         AgentLLMFunc               // LLMs selected per parrot
 }
 
 // ToolFactoryFunc creates a tool with given userID.
 type ToolFactoryFunc func(userID int32) (agent.ToolWithSchema, error)
 
+// DynamicToolsFunc returns extra tools available to userID that are not
+// declared in parrot YAML configs (e.g. admin-registered HTTP actions).
+type DynamicToolsFunc func(userID int32) []agent.ToolWithSchema
+
+// FewShot is an example query with an answer in the desired style.
+type FewShot struct {
+	Query  string
+	Answer string
+}
+
+// FewShotsFunc returns the few-shot examples of the parrot named parrot.
+type FewShotsFunc func(parrot string) []FewShot
+
+// ModelLLMFunc returns an LLM service using model instead of the default one.
+type ModelLLMFunc func(model string) (ai.LLMService, error)
+
+// AgentLLMFunc returns the LLM service selected for the parrot named parrot.
+type AgentLLMFunc func(parrot string) ai.LLMService
+
 // FactoryOption configures the ParrotFactory.
 type FactoryOption func(*ParrotFactory) error
 
@@ -129,6 +153,42 @@ func WithBaseURL(baseURL string) FactoryOption {
 	}
 }
 
+// WithDynamicTools sets the provider of runtime-registered tools.
+// Dynamic tools are attached to every parrot that declares at least one tool.
+func WithDynamicTools(fn DynamicToolsFunc) FactoryOption {
+	return func(f *ParrotFactory) error {
+		f.dynamicTools = fn
+		return nil
+	}
+}
+
+// WithFewShots sets the provider of few-shot examples, appended to the
+// system prompts of the parrots they belong to.
+func WithFewShots(fn FewShotsFunc) FactoryOption {
+	return func(f *ParrotFactory) error {
+		f.fewShots = fn
+		return nil
+	}
+}
+
+// WithModelLLM sets the provider of the LLMs of the shadows that override
+// the model.
+func WithModelLLM(fn ModelLLMFunc) FactoryOption {
+	return func(f *ParrotFactory) error {
+		f.modelLLM = fn
+		return nil
+	}
+}
+
+// WithAgentLLM sets the provider of the LLMs selected per parrot, which
+// replace the default LLM.
+func WithAgentLLM(fn AgentLLMFunc) FactoryOption {
+	return func(f *ParrotFactory) error {
+		f.agentLLM = fn
+		return nil
+	}
+}
+
 // NewParrotFactory creates a new ParrotFactory with options.
 func NewParrotFactory(opts ...FactoryOption) (*ParrotFactory, error) {
 	factory := &ParrotFactory{
@@ -208,6 +268,15 @@ func (f *ParrotFactory) RegisterConfig(config *ParrotConfig) error {
 	return nil
 }
 
+// RemoveConfig removes a parrot configuration, e.g. of an optional parrot
+// whose backend is not configured in this deployment.
+func (f *ParrotFactory) RemoveConfig(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.configs, name)
+}
+
 // GetConfig retrieves a parrot configuration by name.
 func (f *ParrotFactory) GetConfig(name string) (*ParrotConfig, bool) {
 	f.mu.RLock()
@@ -217,18 +286,49 @@ func (f *ParrotFactory) GetConfig(name string) (*ParrotConfig, bool) {
 	return config, ok
 }
 
-// ListConfigs returns all registered parrot names.
+// ListConfigs returns all registered parrot names, except canaries and
+// shadows: they only serve a share of another parrot's traffic.
 func (f *ParrotFactory) ListConfigs() []string {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
 	names := make([]string, 0, len(f.configs))
-	for name := range f.configs {
-		names = append(names, name)
+	for name, config := range f.configs {
+		if config.Canary == nil && config.Shadow == nil {
+			names = append(names, name)
+		}
 	}
 	return names
 }
 
+// ListCanaries returns the configs of the canary parrots.
+func (f *ParrotFactory) ListCanaries() []*ParrotConfig {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var canaries []*ParrotConfig
+	for _, config := range f.configs {
+		if config.Canary != nil {
+			canaries = append(canaries, config)
+		}
+	}
+	return canaries
+}
+
+// ListShadows returns the configs of the shadow parrots.
+func (f *ParrotFactory) ListShadows() []*ParrotConfig {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var shadows []*ParrotConfig
+	for _, config := range f.configs {
+		if config.Shadow != nil {
+			shadows = append(shadows, config)
+		}
+	}
+	return shadows
+}
+
 // CreateParrot creates a parrot from configuration by name.
 func (f *ParrotFactory) CreateParrot(name string, userID int32) (agent.ParrotAgent, error) {
 	config, ok := f.GetConfig(name)
@@ -254,14 +354,52 @@ func (f *ParrotFactory) CreateParrotFromConfig(config *ParrotConfig, userID int3
 			return nil, fmt.Errorf("tool factory not found: %s", toolName)
 		}
 		tool, err := toolFactory(userID)
+		if errors.Is(err, agent.ErrToolUnavailable) {
+			// Optional tools unavailable in this deployment are left out
+			continue
+		}
 		if err != nil {
 			return nil, fmt.Errorf("create tool %s: %w", toolName, err)
 		}
 		tools[toolName] = tool
 	}
 
+	// Attach dynamic tools to tool-using parrots; static tools win on name conflicts
+	if f.dynamicTools != nil && len(config.Tools) > 0 {
+		for _, tool := range f.dynamicTools(userID) {
+			if _, exists := tools[tool.Name()]; !exists {
+				tools[tool.Name()] = tool
+			}
+		}
+	}
+
+	// Show the admin-curated examples without touching the shared config
+	if f.fewShots != nil {
+		if shots := f.fewShots(config.Name); len(shots) > 0 {
+			withShots := *config
+			withShots.SystemPrompt = appendFewShots(config.SystemPrompt, shots)
+			config = &withShots
+		}
+	}
+
+	// Parrots may run on the LLM selected for them, and shadows try another model
+	llm := f.llm
+	if f.agentLLM != nil {
+		llm = f.agentLLM(config.Name)
+	}
+	if config.Shadow != nil && config.Shadow.Model != "" {
+		if f.modelLLM == nil {
+			return nil, fmt.Errorf("no LLM provider for model %s", config.Shadow.Model)
+		}
+		modelLLM, err := f.modelLLM(config.Shadow.Model)
+		if err != nil {
+			return nil, fmt.Errorf("create LLM for model %s: %w", config.Shadow.Model, err)
+		}
+		llm = modelLLM
+	}
+
 	// Create UniversalParrot
-	parrot, err := NewUniversalParrot(config, f.llm, tools, userID)
+	parrot, err := NewUniversalParrot(config, llm, tools, userID)
 	if err != nil {
 		return nil, fmt.Errorf("create universal parrot: %w", err)
 	}
@@ -310,3 +448,15 @@ func (f *ParrotFactory) CreateScheduleParrot(userID int32, scheduleService any)
 
 	return parrot, nil
 }
+
+// appendFewShots appends the examples of the desired answers to a system prompt.
+func appendFewShots(prompt string, shots []FewShot) string {
+	var sb strings.Builder
+	sb.WriteString(prompt)
+	sb.WriteString("\n\n<examples>\nAnswer in the style of these examples:\n")
+	for _, shot := range shots {
+		fmt.Fprintf(&sb, "\nUser: %s\nAssistant: %s\n", shot.Query, shot.Answer)
+	}
+	sb.WriteString("</examples>\n")
+	return sb.String()
+}
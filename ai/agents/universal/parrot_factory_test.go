@@ -2,7 +2,13 @@
 package universal
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
+
+	"github.com/hrygo/divinesense/ai"
+	"github.com/hrygo/divinesense/ai/agents"
 )
 
 // TestLoadConfigs verifies that all parrot configs can be loaded.
@@ -124,3 +130,90 @@ func TestToolFactoryFunc(t *testing.T) {
 	// If this compiles, the signature is correct in parrot_factory.go
 	t.Log("ToolFactoryFunc signature verified at compile time")
 }
+
+// TestAppendFewShots verifies that few-shot examples are appended to system prompts.
+func TestAppendFewShots(t *testing.T) {
+	prompt := appendFewShots("You are a memo assistant.", []FewShot{
+		{Query: "找一下周报", Answer: "找到 2 条周报：…"},
+	})
+	if !strings.HasPrefix(prompt, "You are a memo assistant.") {
+		t.Errorf("base prompt lost: %q", prompt)
+	}
+	if !strings.Contains(prompt, "<examples>") || !strings.Contains(prompt, "User: 找一下周报\nAssistant: 找到 2 条周报：…") {
+		t.Errorf("examples missing: %q", prompt)
+	}
+}
+
+// TestShadowModelLLM verifies that shadows are hidden from routing and use the LLM of their model.
+func TestShadowModelLLM(t *testing.T) {
+	mainLLM, reasonerLLM := &mockLLM{}, &mockLLM{}
+	factory, err := NewParrotFactory(
+		WithConfigDir(t.TempDir()),
+		WithLLM(mainLLM),
+		WithModelLLM(func(model string) (ai.LLMService, error) {
+			if model != "deepseek-reasoner" {
+				return nil, errors.New("unknown model")
+			}
+			return reasonerLLM, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewParrotFactory: %v", err)
+	}
+	_ = factory.RegisterConfig(&ParrotConfig{Name: "memo", Strategy: StrategyDirect})
+	_ = factory.RegisterConfig(&ParrotConfig{
+		Name:     "memo_reasoner",
+		Strategy: StrategyDirect,
+		Shadow:   &ShadowConfig{Of: "memo", SampleRate: 10, Model: "deepseek-reasoner"},
+	})
+	_ = factory.RegisterConfig(&ParrotConfig{
+		Name:     "memo_unknown",
+		Strategy: StrategyDirect,
+		Shadow:   &ShadowConfig{Of: "memo", Model: "gpt-x"},
+	})
+
+	if configs := factory.ListConfigs(); len(configs) != 1 || configs[0] != "memo" {
+		t.Errorf("ListConfigs() = %v, want [memo]", configs)
+	}
+	if shadows := factory.ListShadows(); len(shadows) != 2 {
+		t.Errorf("ListShadows() returned %d configs, want 2", len(shadows))
+	}
+
+	parrot, err := factory.CreateParrot("memo_reasoner", 1)
+	if err != nil {
+		t.Fatalf("CreateParrot: %v", err)
+	}
+	if up := parrot.(*UniversalParrot); up.llm != reasonerLLM {
+		t.Error("shadow does not use the LLM of its model")
+	}
+	if _, err := factory.CreateParrot("memo_unknown", 1); err == nil {
+		t.Error("expected an error for a model without LLM")
+	}
+}
+
+// TestOptionalTools verifies that unavailable optional tools are left out.
+func TestOptionalTools(t *testing.T) {
+	factory, err := NewParrotFactory(
+		WithConfigDir(t.TempDir()),
+		WithLLM(&mockLLM{}),
+		WithToolFactories(map[string]ToolFactoryFunc{
+			"present": func(int32) (agent.ToolWithSchema, error) {
+				return agent.NewNativeTool("present", "", func(context.Context, string) (string, error) { return "", nil }, nil), nil
+			},
+			"optional": func(int32) (agent.ToolWithSchema, error) { return nil, agent.ErrToolUnavailable },
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewParrotFactory: %v", err)
+	}
+	_ = factory.RegisterConfig(&ParrotConfig{Name: "general", Strategy: StrategyDirect, Tools: []string{"present", "optional"}})
+
+	parrot, err := factory.CreateParrot("general", 1)
+	if err != nil {
+		t.Fatalf("CreateParrot: %v", err)
+	}
+	tools := parrot.(*UniversalParrot).tools
+	if _, ok := tools["present"]; !ok || len(tools) != 1 {
+		t.Errorf("tools = %v, want only present", tools)
+	}
+}
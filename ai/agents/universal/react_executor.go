@@ -247,7 +247,7 @@ func (e *ReActExecutor) StreamingSupported() bool {
 func executeTool(ctx context.Context, tools []agent.ToolWithSchema, name, input string) (string, error) {
 	for _, tool := range tools {
 		if tool.Name() == name {
-			return tool.Run(ctx, input)
+			return runTool(ctx, tool, input)
 		}
 	}
 	return "", fmt.Errorf("unknown tool: %s", name)
@@ -272,6 +272,11 @@ func shouldEarlyStop(toolResult string) bool {
 		return true
 	}
 
+	// A form request hands the turn back to the user until the form is submitted
+	if strings.Contains(toolResult, "FORM_REQUESTED:") {
+		return true
+	}
+
 	return false
 }
 
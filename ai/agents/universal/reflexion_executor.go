@@ -143,6 +143,9 @@ func (e *ReflexionExecutor) Execute(
 	stats.CacheReadTokens += underlyingStats.CacheReadTokens
 	stats.CacheWriteTokens += underlyingStats.CacheWriteTokens
 	stats.ToolCalls = underlyingStats.ToolCalls
+	if stats.Model == "" {
+		stats.Model = underlyingStats.Model
+	}
 
 	currentAnswer := initialAnswer
 	iteration := 0
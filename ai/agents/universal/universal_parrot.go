@@ -8,6 +8,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -143,8 +144,8 @@ func (p *UniversalParrot) Execute(
 		return agent.NewParrotError(p.config.Name, "Execute", err)
 	}
 
-	// Cache result
-	if p.cache != nil && result != "" {
+	// Cache result (form requests are interactive and must not be replayed)
+	if p.cache != nil && result != "" && !strings.Contains(result, "FORM_REQUESTED:") {
 		cacheKey := p.generateCacheKey(p.config.Name, p.userID, userInput)
 		p.cache.SetWithDefaultTTL(cacheKey, result)
 	}
@@ -310,6 +311,22 @@ func (p *UniversalParrot) resolveTools() []agent.ToolWithSchema {
 			slog.Warn("tool not found", "tool", toolName)
 		}
 	}
+
+	// Append dynamic tools injected by the factory, in stable name order
+	declared := make(map[string]bool, len(p.config.Tools))
+	for _, toolName := range p.config.Tools {
+		declared[toolName] = true
+	}
+	extra := make([]string, 0)
+	for name := range p.tools {
+		if !declared[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		tools = append(tools, p.tools[name])
+	}
 	return tools
 }
 
@@ -330,12 +347,12 @@ func (p *UniversalParrot) accumulateStats(execStats *ExecutionStats, startTime t
 	p.stats.ToolDurationMs += execStats.ToolDurationMs
 	p.stats.ThinkingDurationMs += execStats.ThinkingDuration
 	p.stats.TotalDurationMs += duration.Milliseconds()
+	if execStats.Model != "" {
+		p.stats.ModelUsed = execStats.Model
+	}
 
 	// Calculate cost (in milli-cents: 1/100000 USD)
-	// Cost = (input_tokens * input_price + output_tokens * output_price) / 1M * 100000
-	inputCost := float64(execStats.PromptTokens) * defaultInputCostPerMillion
-	outputCost := float64(execStats.CompletionTokens) * defaultOutputCostPerMillion
-	totalCost := (inputCost + outputCost) / 1_000_000 * 100000
+	totalCost := EstimateCostUSD(execStats.PromptTokens, execStats.CompletionTokens) * 100000
 	p.stats.TotalCostMilliCents += int64(totalCost)
 
 	// Merge unique tool names
@@ -353,6 +370,14 @@ func (p *UniversalParrot) accumulateStats(execStats *ExecutionStats, startTime t
 	}
 }
 
+// EstimateCostUSD prices token usage at the default model pricing.
+// Cost = (input_tokens * input_price + output_tokens * output_price) / 1M
+func EstimateCostUSD(promptTokens, completionTokens int) float64 {
+	inputCost := float64(promptTokens) * defaultInputCostPerMillion
+	outputCost := float64(completionTokens) * defaultOutputCostPerMillion
+	return (inputCost + outputCost) / 1_000_000
+}
+
 // generateCacheKey generates a cache key for the input.
 func (p *UniversalParrot) generateCacheKey(name string, userID int32, input string) string {
 	return fmt.Sprintf("%s:%d:%x", name, userID, hashString(input))
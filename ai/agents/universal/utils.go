@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/hrygo/divinesense/ai"
 	"github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/ai/observability/tracing"
 )
 
 const (
@@ -50,12 +53,20 @@ func FindAndExecuteTool(
 	// Find tool - check for nil before calling Name()
 	for _, t := range tools {
 		if t != nil && t.Name() == toolName {
-			return t.Run(ctx, toolInput)
+			return runTool(ctx, t, toolInput)
 		}
 	}
 	return "", fmt.Errorf("tool not found: %s", toolName)
 }
 
+// runTool runs a tool in a "tool.call" span.
+func runTool(ctx context.Context, tool agent.ToolWithSchema, input string) (string, error) {
+	ctx, span := tracing.Start(ctx, "tool.call", attribute.String("tool.name", tool.Name()))
+	result, err := tool.Run(ctx, input)
+	tracing.End(span, err)
+	return result, err
+}
+
 // BuildMessagesWithInput creates a message slice from history and user input.
 // This is the single source of truth for building message arrays.
 func BuildMessagesWithInput(history []ai.Message, input string) []ai.Message {
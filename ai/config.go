@@ -13,6 +13,8 @@ type Config struct {
 	Reranker         RerankerConfig
 	IntentClassifier IntentClassifierConfig
 	LLM              LLMConfig
+	LLMProviders     map[string]LLMConfig  // Named providers besides LLM, by registry name
+	AgentModels      map[string]string     // Agent type → "provider[:model]" of the provider registry
 	UniversalParrot  UniversalParrotConfig // Phase 2: Configuration-driven parrots
 	Enabled          bool
 }
@@ -103,6 +105,21 @@ func NewConfigFromProfile(p *profile.Profile) *Config {
 		Timeout:     p.ALLMTimeout,
 	}
 
+	// Named providers of the registry, with the unified LLM settings as defaults
+	cfg.LLMProviders = make(map[string]LLMConfig, len(p.ALLMProviders))
+	for _, provider := range p.ALLMProviders {
+		cfg.LLMProviders[provider.Name] = LLMConfig{
+			Provider:    provider.Provider,
+			Model:       provider.Model,
+			APIKey:      provider.APIKey,
+			BaseURL:     provider.BaseURL,
+			MaxTokens:   cfg.LLM.MaxTokens,
+			Temperature: cfg.LLM.Temperature,
+			Timeout:     cfg.LLM.Timeout,
+		}
+	}
+	cfg.AgentModels = p.ALLMAgentModels
+
 	// Intent Classifier configuration
 	// Default uses SiliconFlow with Qwen2.5-7B-Instruct for fast, cost-effective classification
 	cfg.IntentClassifier = IntentClassifierConfig{
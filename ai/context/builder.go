@@ -37,7 +37,8 @@ type ContextRequest struct {
 type RetrievalItem struct {
 	ID      string
 	Content string
-	Source  string
+	Source  string // "memo" items are subject to the retrieval scope
+	Tags    []string
 	Score   float32
 }
 
@@ -8,6 +8,8 @@ import (
 	"strings"
 	"sync/atomic"
 	"time"
+
+	"github.com/hrygo/divinesense/ai/core/retrieval"
 )
 
 // Service implements ContextBuilder with caching support.
@@ -126,6 +128,14 @@ func (s *Service) Build(ctx context.Context, req *ContextRequest) (*ContextResul
 		}
 	}
 
+	// The retrieval scope of the conversation filters the retrieved memos;
+	// a conversation bound to a document retrieves none
+	if retrieval.DocumentFromContext(ctx) != nil {
+		req.RetrievalResults = scopeRetrieval(req.RetrievalResults, &retrieval.Scope{Disabled: true})
+	} else if scope := retrieval.ScopeFromContext(ctx); scope.Restricted() {
+		req.RetrievalResults = scopeRetrieval(req.RetrievalResults, scope)
+	}
+
 	// Allocate token budget (Issue #93: profile-based allocation)
 	// Issue #211: Phase 3 - Dynamic adjustment based on conversation length
 	hasRetrieval := len(req.RetrievalResults) > 0
@@ -268,6 +278,17 @@ func (s *Service) buildSystemPrompt(agentType string) string {
 	}
 }
 
+// scopeRetrieval drops the memos outside the retrieval scope.
+func scopeRetrieval(items []*RetrievalItem, scope *retrieval.Scope) []*RetrievalItem {
+	scoped := make([]*RetrievalItem, 0, len(items))
+	for _, item := range items {
+		if item.Source != "memo" || scope.AllowsTags(item.Tags) {
+			scoped = append(scoped, item)
+		}
+	}
+	return scoped
+}
+
 // formatRetrieval formats retrieval results into context.
 func (s *Service) formatRetrieval(results []*RetrievalItem) string {
 	if len(results) == 0 {
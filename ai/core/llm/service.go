@@ -47,6 +47,10 @@ type LLMCallStats struct {
 
 	// TotalDurationMs is the total wall-clock time for the request.
 	TotalDurationMs int64 `json:"total_duration_ms"`
+
+	// Model is the model that served the request, as reported by the provider
+	// (falls back to the configured model).
+	Model string `json:"model,omitempty"`
 }
 
 // Service is the LLM service interface.
@@ -253,6 +257,7 @@ func (s *service) Chat(ctx context.Context, messages []Message) (string, *LLMCal
 		TotalTokens:        resp.Usage.TotalTokens,
 		ThinkingDurationMs: totalDuration.Milliseconds(),
 		TotalDurationMs:    totalDuration.Milliseconds(),
+		Model:              s.modelUsed(resp.Model),
 	}
 
 	// Handle cached tokens (provider-specific, mostly OpenAI)
@@ -260,6 +265,8 @@ func (s *service) Chat(ctx context.Context, messages []Message) (string, *LLMCal
 		stats.CacheReadTokens = resp.Usage.PromptTokensDetails.CachedTokens
 	}
 
+	newUsageReporter(ctx).report(stats, "", true)
+
 	slog.Debug("LLM: Chat response received",
 		"content_length", len(resp.Choices[0].Message.Content),
 		"total_tokens", stats.TotalTokens,
@@ -319,12 +326,15 @@ func (s *service) ChatWithTools(ctx context.Context, messages []Message, tools [
 		TotalTokens:        resp.Usage.TotalTokens,
 		ThinkingDurationMs: totalDuration.Milliseconds(),
 		TotalDurationMs:    totalDuration.Milliseconds(),
+		Model:              s.modelUsed(resp.Model),
 	}
 
 	if resp.Usage.PromptTokensDetails != nil && resp.Usage.PromptTokensDetails.CachedTokens > 0 {
 		stats.CacheReadTokens = resp.Usage.PromptTokensDetails.CachedTokens
 	}
 
+	newUsageReporter(ctx).report(stats, "", true)
+
 	choice := resp.Choices[0]
 	response := &ChatResponse{
 		Content: choice.Message.Content,
@@ -387,28 +397,50 @@ func (s *service) ChatStream(ctx context.Context, messages []Message) (<-chan st
 		}
 		defer func() { _ = stream.Close() }() //nolint:errcheck // cleanup
 
+		usage := newUsageReporter(ctx)
 		chunkCount := 0
-		var totalTokens int
+		finished := false
+		// Latest usage reported by the provider. Some providers report cumulative
+		// usage on every chunk; OpenAI sends it in a trailing chunk after the finish reason.
+		var lastUsage *openai.Usage
+		var model string
+
+		buildStats := func() *LLMCallStats {
+			totalDuration := time.Since(startTime)
+			stats := &LLMCallStats{TotalDurationMs: totalDuration.Milliseconds(), Model: s.modelUsed(model)}
+			if !firstChunkTime.IsZero() {
+				stats.ThinkingDurationMs = firstChunkTime.Sub(startTime).Milliseconds()
+				stats.GenerationDurationMs = time.Since(firstChunkTime).Milliseconds()
+			}
+			if lastUsage != nil {
+				stats.PromptTokens = lastUsage.PromptTokens
+				stats.CompletionTokens = lastUsage.CompletionTokens
+				stats.TotalTokens = lastUsage.TotalTokens
+				if lastUsage.PromptTokensDetails != nil && lastUsage.PromptTokensDetails.CachedTokens > 0 {
+					stats.CacheReadTokens = lastUsage.PromptTokensDetails.CachedTokens
+				}
+			} else {
+				stats.TotalTokens = chunkCount * 10 // rough estimate when no usage was reported
+			}
+			return stats
+		}
+		complete := func() {
+			stats := buildStats()
+			slog.Debug("LLM ChatStream completed",
+				"chunks", chunkCount,
+				"usage_reported", lastUsage != nil,
+				"total_tokens", stats.TotalTokens,
+				"duration_ms", stats.TotalDurationMs,
+			)
+			usage.report(stats, "", true)
+			statsChan <- stats
+		}
 
 		for {
 			response, err := stream.Recv()
 			if err != nil {
 				if strings.Contains(err.Error(), "EOF") || err.Error() == "EOF" {
-					totalDuration := time.Since(startTime)
-					var generationDurationMs int64
-					if !firstChunkTime.IsZero() {
-						generationDurationMs = time.Since(firstChunkTime).Milliseconds()
-					}
-
-					stats := &LLMCallStats{
-						TotalTokens:          totalTokens,
-						ThinkingDurationMs:   firstChunkTime.Sub(startTime).Milliseconds(),
-						GenerationDurationMs: generationDurationMs,
-						TotalDurationMs:      totalDuration.Milliseconds(),
-					}
-
-					slog.Debug("LLM ChatStream completed", "chunks", chunkCount, "duration_ms", totalDuration.Milliseconds())
-					statsChan <- stats
+					complete()
 					return
 				}
 				slog.Error("LLM ChatStream receive error", "error", err, "chunks_so_far", chunkCount)
@@ -422,43 +454,19 @@ func (s *service) ChatStream(ctx context.Context, messages []Message) (<-chan st
 			if firstChunkTime.IsZero() && len(response.Choices) > 0 && response.Choices[0].Delta.Content != "" {
 				firstChunkTime = time.Now()
 			}
-
-			if response.Usage != nil && response.Usage.TotalTokens > 0 {
-				totalDuration := time.Since(startTime)
-				var generationDurationMs int64
-				if !firstChunkTime.IsZero() {
-					generationDurationMs = time.Since(firstChunkTime).Milliseconds()
-				}
-
-				stats := &LLMCallStats{
-					PromptTokens:         response.Usage.PromptTokens,
-					CompletionTokens:     response.Usage.CompletionTokens,
-					TotalTokens:          response.Usage.TotalTokens,
-					ThinkingDurationMs:   firstChunkTime.Sub(startTime).Milliseconds(),
-					GenerationDurationMs: generationDurationMs,
-					TotalDurationMs:      totalDuration.Milliseconds(),
-				}
-
-				if response.Usage.PromptTokensDetails != nil && response.Usage.PromptTokensDetails.CachedTokens > 0 {
-					stats.CacheReadTokens = response.Usage.PromptTokensDetails.CachedTokens
-				}
-
-				slog.Debug("LLM ChatStream finished with usage",
-					"reason", response.Choices[0].FinishReason,
-					"chunks", chunkCount,
-					"total_tokens", stats.TotalTokens,
-					"duration_ms", totalDuration.Milliseconds(),
-				)
-
-				statsChan <- stats
-				return
+			if response.Model != "" {
+				model = response.Model
 			}
 
-			if len(response.Choices) == 0 {
-				continue
+			var delta, finishReason string
+			if len(response.Choices) > 0 {
+				delta = response.Choices[0].Delta.Content
+				finishReason = string(response.Choices[0].FinishReason)
+			}
+			if finishReason != "" {
+				finished = true
 			}
 
-			delta := response.Choices[0].Delta.Content
 			if delta != "" {
 				chunkCount++
 				select {
@@ -469,40 +477,38 @@ func (s *service) ChatStream(ctx context.Context, messages []Message) (<-chan st
 				}
 			}
 
-			if response.Choices[0].FinishReason != "" {
-				totalDuration := time.Since(startTime)
-				var generationDurationMs int64
-				if !firstChunkTime.IsZero() {
-					generationDurationMs = time.Since(firstChunkTime).Milliseconds()
-				}
-
-				estimatedTokens := chunkCount * 10
-				var thinkingDurationMs int64
-				if !firstChunkTime.IsZero() {
-					thinkingDurationMs = firstChunkTime.Sub(startTime).Milliseconds()
-				}
-				stats := &LLMCallStats{
-					TotalTokens:          estimatedTokens,
-					ThinkingDurationMs:   thinkingDurationMs,
-					GenerationDurationMs: generationDurationMs,
-					TotalDurationMs:      totalDuration.Milliseconds(),
-				}
-
-				slog.Debug("LLM ChatStream finished (no usage)",
-					"reason", response.Choices[0].FinishReason,
-					"chunks", chunkCount,
-					"duration_ms", totalDuration.Milliseconds(),
-				)
+			hasUsage := response.Usage != nil && response.Usage.TotalTokens > 0
+			if hasUsage {
+				lastUsage = response.Usage
+			}
 
-				statsChan <- stats
+			// Usage after (or with) the finish reason is the final usage of the call
+			if hasUsage && (finished || len(response.Choices) == 0) {
+				complete()
 				return
 			}
+
+			if delta != "" || hasUsage {
+				var partial *LLMCallStats
+				if lastUsage != nil {
+					partial = buildStats()
+				}
+				usage.report(partial, delta, false)
+			}
 		}
 	}()
 
 	return contentChan, statsChan, errChan
 }
 
+// modelUsed returns the model reported in a response, or the configured model.
+func (s *service) modelUsed(reported string) string {
+	if reported != "" {
+		return reported
+	}
+	return s.model
+}
+
 func (s *service) Warmup(ctx context.Context) {
 	warmupCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
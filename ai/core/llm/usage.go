@@ -0,0 +1,58 @@
+package llm
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// UsageUpdate is reported to a UsageObserver while an LLM call runs.
+type UsageUpdate struct {
+	// CallID identifies the LLM call; updates of concurrent calls interleave.
+	CallID uint64
+
+	// Stats is the usage reported by the provider, cumulative for the call.
+	// Nil when the provider has not reported usage yet.
+	Stats *LLMCallStats
+
+	// Delta is the content generated since the previous update of a streaming call,
+	// for estimating usage of providers that only report it at the end.
+	Delta string
+
+	// Final marks the last update of the call.
+	Final bool
+}
+
+// UsageObserver receives incremental usage of the LLM calls made with a context.
+// It is called synchronously from the call and must not block.
+type UsageObserver func(update UsageUpdate)
+
+type usageObserverKey struct{}
+
+var usageCallSeq atomic.Uint64
+
+// WithUsageObserver returns a context whose LLM calls report usage to observer.
+func WithUsageObserver(ctx context.Context, observer UsageObserver) context.Context {
+	return context.WithValue(ctx, usageObserverKey{}, observer)
+}
+
+// usageReporter reports the usage of one LLM call, or does nothing when the
+// context has no observer.
+type usageReporter struct {
+	observer UsageObserver
+	callID   uint64
+}
+
+func newUsageReporter(ctx context.Context) *usageReporter {
+	observer, _ := ctx.Value(usageObserverKey{}).(UsageObserver)
+	if observer == nil {
+		return &usageReporter{}
+	}
+	return &usageReporter{observer: observer, callID: usageCallSeq.Add(1)}
+}
+
+func (r *usageReporter) report(stats *LLMCallStats, delta string, final bool) {
+	if r.observer == nil {
+		return
+	}
+	r.observer(UsageUpdate{CallID: r.callID, Stats: stats, Delta: delta, Final: final})
+}
@@ -0,0 +1,104 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// streamServer serves a chat completion stream made of the given data chunks.
+func streamServer(chunks ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range chunks {
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+}
+
+func collectStream(t *testing.T, server *httptest.Server) (string, *LLMCallStats, []UsageUpdate) {
+	t.Helper()
+	svc, err := NewService(&Config{Provider: "openai", APIKey: "test-key", BaseURL: server.URL, Model: "test"})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	var mu sync.Mutex
+	var updates []UsageUpdate
+	ctx := WithUsageObserver(context.Background(), func(u UsageUpdate) {
+		mu.Lock()
+		defer mu.Unlock()
+		updates = append(updates, u)
+	})
+
+	contentCh, statsCh, errCh := svc.ChatStream(ctx, []Message{UserMessage("hi")})
+	var content string
+	for c := range contentCh {
+		content += c
+	}
+	stats := <-statsCh
+	if err := <-errCh; err != nil {
+		t.Fatalf("ChatStream() error = %v", err)
+	}
+	return content, stats, updates
+}
+
+func TestChatStream_TrailingUsage(t *testing.T) {
+	server := streamServer(
+		`{"model":"test-2025","choices":[{"index":0,"delta":{"content":"Hel"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"lo"},"finish_reason":"stop"}]}`,
+		`{"choices":[],"usage":{"prompt_tokens":12,"completion_tokens":2,"total_tokens":14}}`,
+	)
+	defer server.Close()
+
+	content, stats, updates := collectStream(t, server)
+	if content != "Hello" {
+		t.Errorf("content = %q, want Hello", content)
+	}
+	if stats.PromptTokens != 12 || stats.CompletionTokens != 2 {
+		t.Errorf("stats = %+v, want the trailing usage", stats)
+	}
+	if stats.Model != "test-2025" {
+		t.Errorf("stats.Model = %q, want the model reported by the provider", stats.Model)
+	}
+	if len(updates) != 3 {
+		t.Fatalf("got %d usage updates, want 3", len(updates))
+	}
+	if updates[0].Delta != "Hel" || updates[0].Stats != nil {
+		t.Errorf("first update = %+v, want content only", updates[0])
+	}
+	last := updates[2]
+	if !last.Final || last.Stats == nil || last.Stats.TotalTokens != 14 {
+		t.Errorf("last update = %+v, want final usage", last)
+	}
+	if last.CallID != updates[0].CallID {
+		t.Errorf("updates of one call have different ids")
+	}
+}
+
+func TestChatStream_IncrementalUsage(t *testing.T) {
+	server := streamServer(
+		`{"choices":[{"index":0,"delta":{"content":"a"}}],"usage":{"prompt_tokens":12,"completion_tokens":1,"total_tokens":13}}`,
+		`{"choices":[{"index":0,"delta":{"content":"b"}}],"usage":{"prompt_tokens":12,"completion_tokens":2,"total_tokens":14}}`,
+		`{"choices":[{"index":0,"delta":{"content":"c"},"finish_reason":"stop"}],"usage":{"prompt_tokens":12,"completion_tokens":3,"total_tokens":15}}`,
+	)
+	defer server.Close()
+
+	content, stats, updates := collectStream(t, server)
+	if content != "abc" {
+		t.Errorf("content = %q, want abc (usage chunks must not end the stream)", content)
+	}
+	if stats.CompletionTokens != 3 {
+		t.Errorf("stats = %+v, want 3 completion tokens", stats)
+	}
+	if stats.Model != "test" {
+		t.Errorf("stats.Model = %q, want the configured model", stats.Model)
+	}
+	if len(updates) != 3 || updates[1].Stats == nil || updates[1].Stats.CompletionTokens != 2 || updates[1].Final {
+		t.Errorf("updates = %+v, want incremental usage", updates)
+	}
+}
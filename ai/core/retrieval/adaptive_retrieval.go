@@ -25,6 +25,16 @@ type AdaptiveRetriever struct {
 	store            *store.Store
 	embeddingService ai.EmbeddingService
 	rerankerService  ai.RerankerService
+	tuner            WeightTuner
+	hybrid           HybridConfig
+}
+
+// WeightTuner 根据用户的相关性反馈调整混合检索的语义权重.
+type WeightTuner interface {
+	// SemanticWeight 返回为用户调优的语义权重；没有时返回 false.
+	SemanticWeight(ctx context.Context, userID int32) (float32, bool)
+	// LogSearch 记录一次混合检索及两路检索结果（按排名）的笔记 ID.
+	LogSearch(ctx context.Context, userID int32, query string, weight float32, vectorIDs, bm25IDs []int32)
 }
 
 // SearchResult 检索结果.
@@ -33,8 +43,13 @@ type SearchResult struct {
 	Schedule *store.Schedule
 	Type     string
 	Content  string
-	ID       int64
-	Score    float32
+	// AttachmentText 笔记附件的 OCR/提取文字（截断），便于 Agent 引用图片与文档内容
+	AttachmentText string
+	ID             int64
+	Score          float32
+	// Start/End 段落在会话绑定文档中的字符偏移（Type 为 "document"）
+	Start int
+	End   int
 }
 
 // RetrievalOptions 检索选项.
@@ -63,9 +78,15 @@ func NewAdaptiveRetriever(
 		store:            st,
 		embeddingService: embeddingService,
 		rerankerService:  rerankerService,
+		hybrid:           DefaultHybridConfig(),
 	}
 }
 
+// SetWeightTuner 设置语义权重调优器（仅 PostgreSQL 可用）.
+func (r *AdaptiveRetriever) SetWeightTuner(tuner WeightTuner) {
+	r.tuner = tuner
+}
+
 // Retrieve 自适应检索主入口.
 func (r *AdaptiveRetriever) Retrieve(ctx context.Context, opts *RetrievalOptions) ([]*SearchResult, error) {
 	if opts == nil {
@@ -89,7 +110,44 @@ func (r *AdaptiveRetriever) Retrieve(ctx context.Context, opts *RetrievalOptions
 		opts.RequestID = generateRequestID()
 	}
 
-	// 根据路由策略选择检索路径
+	// 会话绑定了单个文档时只检索该文档的段落
+	if doc := DocumentFromContext(ctx); doc != nil && !strings.HasPrefix(opts.Strategy, "schedule_") {
+		opts.Logger.InfoContext(ctx, "Searching bound document",
+			"request_id", opts.RequestID,
+			"kind", doc.Kind,
+			"uid", doc.UID,
+		)
+		return documentResults(doc, opts.Query, opts.Limit), nil
+	}
+
+	// 会话的检索范围：关闭时不检索笔记，限定标签时多取候选再过滤
+	scope := ScopeFromContext(ctx)
+	limit := opts.Limit
+	if scope.Restricted() {
+		opts.Logger.InfoContext(ctx, "Applying retrieval scope",
+			"request_id", opts.RequestID,
+			"scope", scope.String(),
+		)
+		if scope.Disabled && !strings.HasPrefix(opts.Strategy, "schedule_") {
+			return []*SearchResult{}, nil
+		}
+		opts.Limit = limit * scopeOverfetch
+	}
+
+	results, err := r.retrieveByStrategy(ctx, opts)
+	opts.Limit = limit
+	if err != nil {
+		return nil, err
+	}
+	if scope.Restricted() {
+		results = r.truncateResults(applyScope(results, scope), limit)
+	}
+	r.attachAttachmentText(ctx, results)
+	return results, nil
+}
+
+// retrieveByStrategy 根据路由策略选择检索路径.
+func (r *AdaptiveRetriever) retrieveByStrategy(ctx context.Context, opts *RetrievalOptions) ([]*SearchResult, error) {
 	switch opts.Strategy {
 	case "schedule_bm25_only":
 		return r.scheduleBM25Only(ctx, opts)
@@ -106,6 +164,9 @@ func (r *AdaptiveRetriever) Retrieve(ctx context.Context, opts *RetrievalOptions
 	case "memo_semantic_only":
 		return r.memoSemanticOnly(ctx, opts)
 
+	case "memo_hybrid":
+		return r.memoHybrid(ctx, opts)
+
 	case "hybrid_bm25_weighted":
 		return r.hybridBM25Weighted(ctx, opts)
 
@@ -752,18 +813,21 @@ func (r *AdaptiveRetriever) fullPipelineWithReranker(ctx context.Context, opts *
 	return r.truncateResults(reordered, opts.Limit), nil
 }
 
-// 使用 RRF (Reciprocal Rank Fusion) 融合 BM25 和向量检索结果.
-func (r *AdaptiveRetriever) hybridSearch(ctx context.Context, opts *RetrievalOptions, semanticWeight float32) ([]*SearchResult, error) {
-	// 并行执行 BM25 和向量检索
-	type vectorResult struct {
-		err     error
-		results []*store.MemoWithScore
-	}
-	type bm25Result struct {
-		err     error
-		results []*store.BM25Result
-	}
+// vectorResult 向量检索结果.
+type vectorResult struct {
+	err     error
+	results []*store.MemoWithScore
+}
+
+// bm25Result BM25 检索结果.
+type bm25Result struct {
+	err     error
+	results []*store.BM25Result
+}
 
+// searchBoth 并行执行向量检索与 BM25 检索，每路最多取 limit 条.
+// substring 为 true 时关键词按子串匹配（短中文查询）.
+func (r *AdaptiveRetriever) searchBoth(ctx context.Context, opts *RetrievalOptions, limit int, substring bool) (vectorResult, bm25Result) {
 	vectorCh := make(chan vectorResult, 1)
 	bm25Ch := make(chan bm25Result, 1)
 
@@ -771,14 +835,7 @@ func (r *AdaptiveRetriever) hybridSearch(ctx context.Context, opts *RetrievalOpt
 	go func() {
 		queryVector, err := r.embeddingService.Embed(ctx, opts.Query)
 		if err != nil {
-			select {
-			case <-ctx.Done():
-				return
-			case vectorCh <- vectorResult{
-				err:     fmt.Errorf("failed to embed query: %w", err),
-				results: nil,
-			}:
-			}
+			vectorCh <- vectorResult{err: fmt.Errorf("failed to embed query: %w", err)}
 			return
 		}
 
@@ -787,17 +844,11 @@ func (r *AdaptiveRetriever) hybridSearch(ctx context.Context, opts *RetrievalOpt
 		results, err := r.store.VectorSearch(ctx, &store.VectorSearchOptions{
 			UserID:          opts.UserID,
 			Vector:          queryVector,
-			Limit:           20,
+			Limit:           limit,
 			CreatedAfter:    ninetyDaysAgo,
 			ExcludeComments: opts.ExcludeComments,
 		})
-		select {
-		case <-ctx.Done():
-		case vectorCh <- vectorResult{
-			err:     err,
-			results: results,
-		}:
-		}
+		vectorCh <- vectorResult{err: err, results: results}
 	}()
 
 	// 并行执行 BM25 检索
@@ -805,22 +856,21 @@ func (r *AdaptiveRetriever) hybridSearch(ctx context.Context, opts *RetrievalOpt
 		results, err := r.store.BM25Search(ctx, &store.BM25SearchOptions{
 			UserID:          opts.UserID,
 			Query:           opts.Query,
-			Limit:           20,
+			Limit:           limit,
 			MinScore:        0.1,
 			ExcludeComments: opts.ExcludeComments,
+			Substring:       substring,
 		})
-		select {
-		case <-ctx.Done():
-		case bm25Ch <- bm25Result{
-			err:     err,
-			results: results,
-		}:
-		}
+		bm25Ch <- bm25Result{err: err, results: results}
 	}()
 
 	// 等待两个检索完成
-	vectorRes := <-vectorCh
-	bm25Res := <-bm25Ch
+	return <-vectorCh, <-bm25Ch
+}
+
+// 使用 RRF (Reciprocal Rank Fusion) 融合 BM25 和向量检索结果.
+func (r *AdaptiveRetriever) hybridSearch(ctx context.Context, opts *RetrievalOptions, semanticWeight float32) ([]*SearchResult, error) {
+	vectorRes, bm25Res := r.searchBoth(ctx, opts, 20, false)
 
 	// 处理错误
 	if vectorRes.err != nil && bm25Res.err != nil {
@@ -867,6 +917,14 @@ func (r *AdaptiveRetriever) hybridSearch(ctx context.Context, opts *RetrievalOpt
 		}
 	}
 
+	// 用户的相关性反馈调优出的权重优先于动态权重
+	if r.tuner != nil {
+		if weight, ok := r.tuner.SemanticWeight(ctx, opts.UserID); ok {
+			semanticWeight = weight
+		}
+		r.tuner.LogSearch(ctx, opts.UserID, opts.Query, semanticWeight, vectorMemoIDs(vectorRes.results), bm25MemoIDs(bm25Res.results))
+	}
+
 	// Phase 2 P1 改进: 动态候选集扩展
 	// 当质量为 Low 时，扩展候选集以提供更多选择给 Reranker
 	// 当前默认检索 Top 20，低质量时保持但让 Rerank 发挥更大作用
@@ -878,6 +936,22 @@ func (r *AdaptiveRetriever) hybridSearch(ctx context.Context, opts *RetrievalOpt
 	return results, nil
 }
 
+func vectorMemoIDs(results []*store.MemoWithScore) []int32 {
+	ids := make([]int32, len(results))
+	for i, r := range results {
+		ids[i] = r.Memo.ID
+	}
+	return ids
+}
+
+func bm25MemoIDs(results []*store.BM25Result) []int32 {
+	ids := make([]int32, len(results))
+	for i, r := range results {
+		ids[i] = r.Memo.ID
+	}
+	return ids
+}
+
 // convertVectorResults 转换向量检索结果.
 func (r *AdaptiveRetriever) convertVectorResults(results []*store.MemoWithScore) []*SearchResult {
 	searchResults := make([]*SearchResult, len(results))
@@ -945,6 +1019,11 @@ func (r *AdaptiveRetriever) convertBM25Results(results []*store.BM25Result) []*S
 
 // 其中 k 是常数 (通常取 60)，rank_i(d) 是文档在第 i 个列表中的排名.
 func (r *AdaptiveRetriever) rrfFusion(vectorResults []*store.MemoWithScore, bm25Results []*store.BM25Result, semanticWeight float32) []*SearchResult {
+	return r.rrfFusionK(vectorResults, bm25Results, semanticWeight, RRFK)
+}
+
+// rrfFusionK 以排名常数 k 进行 RRF 融合.
+func (r *AdaptiveRetriever) rrfFusionK(vectorResults []*store.MemoWithScore, bm25Results []*store.BM25Result, semanticWeight float32, k int) []*SearchResult {
 	// 用于存储每个文档的 RRF 分数
 	type rrfScore struct {
 		memo       *store.Memo
@@ -994,11 +1073,11 @@ func (r *AdaptiveRetriever) rrfFusion(vectorResults []*store.MemoWithScore, bm25
 	for _, s := range scores {
 		// 向量检索贡献
 		if s.vectorRank > 0 {
-			s.score += semanticWeight / (float32(RRFK) + float32(s.vectorRank))
+			s.score += semanticWeight / (float32(k) + float32(s.vectorRank))
 		}
 		// BM25 检索贡献
 		if s.bm25Rank > 0 {
-			s.score += bm25Weight / (float32(RRFK) + float32(s.bm25Rank))
+			s.score += bm25Weight / (float32(k) + float32(s.bm25Rank))
 		}
 	}
 
@@ -1183,6 +1262,65 @@ func getMemoTags(memo *store.Memo) []string {
 	return memo.Payload.Tags
 }
 
+// maxAttachmentTextRunes 每条笔记附带的附件文字上限.
+const maxAttachmentTextRunes = 500
+
+// attachAttachmentText 为笔记结果补充附件（图片 OCR、文档提取）文字.
+// 失败时仅记录日志，不影响检索结果.
+func (r *AdaptiveRetriever) attachAttachmentText(ctx context.Context, results []*SearchResult) {
+	if r.store == nil {
+		return
+	}
+	var memoIDs []int32
+	for _, result := range results {
+		if result.Type == "memo" && result.Memo != nil {
+			memoIDs = append(memoIDs, result.Memo.ID)
+		}
+	}
+	if len(memoIDs) == 0 {
+		return
+	}
+
+	attachments, err := r.store.ListAttachments(ctx, &store.FindAttachment{MemoIDList: memoIDs})
+	if err != nil {
+		slog.WarnContext(ctx, "failed to list attachments for search results", "error", err)
+		return
+	}
+	texts := collectAttachmentText(attachments, maxAttachmentTextRunes)
+	for _, result := range results {
+		if result.Type == "memo" && result.Memo != nil {
+			result.AttachmentText = texts[result.Memo.ID]
+		}
+	}
+}
+
+// collectAttachmentText 按笔记汇总附件文字（OCR 优先于文档提取），并截断到 maxRunes.
+func collectAttachmentText(attachments []*store.Attachment, maxRunes int) map[int32]string {
+	parts := make(map[int32][]string)
+	for _, attachment := range attachments {
+		if attachment.MemoID == nil || attachment.RowStatus != string(store.Normal) {
+			continue
+		}
+		text := attachment.OCRText
+		if text == "" {
+			text = attachment.ExtractedText
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			parts[*attachment.MemoID] = append(parts[*attachment.MemoID], text)
+		}
+	}
+
+	texts := make(map[int32]string, len(parts))
+	for memoID, list := range parts {
+		text := []rune(strings.Join(list, "\n---\n"))
+		if len(text) > maxRunes {
+			text = append(text[:maxRunes], '…')
+		}
+		texts[memoID] = string(text)
+	}
+	return texts
+}
+
 // filterByScore 过滤低分结果.
 func (r *AdaptiveRetriever) filterByScore(results []*SearchResult, minScore float32) []*SearchResult {
 	if minScore <= 0 {
@@ -649,3 +649,23 @@ func createMockBM25Results(ids []int64) []*store.BM25Result {
 	}
 	return results
 }
+
+func TestCollectAttachmentText(t *testing.T) {
+	memo1, memo2 := int32(1), int32(2)
+	attachments := []*store.Attachment{
+		{MemoID: &memo1, RowStatus: "NORMAL", OCRText: " 截图文字 ", ExtractedText: "ignored"},
+		{MemoID: &memo1, RowStatus: "NORMAL", ExtractedText: "文档内容"},
+		{MemoID: &memo1, RowStatus: "ARCHIVED", OCRText: "archived"},
+		{MemoID: &memo2, RowStatus: "NORMAL", OCRText: "一二三四五六"},
+		{MemoID: &memo2, RowStatus: "NORMAL"},
+		{RowStatus: "NORMAL", OCRText: "unlinked"},
+	}
+
+	texts := collectAttachmentText(attachments, 5)
+	assert.Len(t, texts, 2)
+	assert.Equal(t, "截图文字\n…", texts[1])
+	assert.Equal(t, "一二三四五…", texts[2])
+
+	texts = collectAttachmentText(attachments, 100)
+	assert.Equal(t, "截图文字\n---\n文档内容", texts[1])
+}
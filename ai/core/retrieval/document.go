@@ -0,0 +1,218 @@
+package retrieval
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	// passageRunes 与 passageOverlapRunes 决定文档段落的长度与重叠.
+	passageRunes        = 600
+	passageOverlapRunes = 100
+)
+
+// Document 会话绑定的单个文档（笔记或附件）. 绑定后检索只在该文档的段落中进行，
+// 引用以段落在文档中的字符偏移表示.
+type Document struct {
+	// Kind 为 "memo" 或 "attachment"
+	Kind  string
+	UID   string
+	Title string
+	Text  string
+
+	once     sync.Once
+	passages []Passage
+}
+
+// Passage 文档的一个段落，Start/End 为其在文档中的字符（rune）偏移，左闭右开.
+type Passage struct {
+	Start   int
+	End     int
+	Content string
+	Score   float32
+}
+
+// CitePassage 返回段落的引用标记，如 [@120-480].
+func CitePassage(start, end int) string {
+	return fmt.Sprintf("[@%d-%d]", start, end)
+}
+
+// Passages 返回文档切分出的段落，按偏移排序.
+func (d *Document) Passages() []Passage {
+	d.once.Do(func() {
+		d.passages = ChunkDocument(d.Text)
+	})
+	return d.passages
+}
+
+// Excerpt 返回文档中偏移 [start, end) 的文字，越界部分截去.
+func (d *Document) Excerpt(start, end int) string {
+	runes := []rune(d.Text)
+	start, end = max(start, 0), min(end, len(runes))
+	if start >= end {
+		return ""
+	}
+	return string(runes[start:end])
+}
+
+// Search 按查询词在文档段落中的出现次数（以 IDF 加权）排序，返回前 limit 个段落.
+// 查询为空或无段落命中时按顺序返回文档开头的段落，以便概括全文.
+func (d *Document) Search(query string, limit int) []Passage {
+	passages := d.Passages()
+	terms := queryTerms(query)
+	if len(terms) == 0 {
+		return leadingPassages(passages, limit)
+	}
+
+	lowered := make([]string, len(passages))
+	df := make(map[string]int, len(terms))
+	for i, p := range passages {
+		lowered[i] = strings.ToLower(p.Content)
+		for _, term := range terms {
+			if strings.Contains(lowered[i], term) {
+				df[term]++
+			}
+		}
+	}
+	var ranked []Passage
+	for i, p := range passages {
+		var score float64
+		for _, term := range terms {
+			if n := strings.Count(lowered[i], term); n > 0 {
+				score += float64(n) * math.Log(1+float64(len(passages))/float64(df[term]))
+			}
+		}
+		if score > 0 {
+			p.Score = float32(score)
+			ranked = append(ranked, p)
+		}
+	}
+	if len(ranked) == 0 {
+		return leadingPassages(passages, limit)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Score > ranked[j].Score })
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+func leadingPassages(passages []Passage, limit int) []Passage {
+	if limit > 0 && len(passages) > limit {
+		passages = passages[:limit]
+	}
+	return append([]Passage(nil), passages...)
+}
+
+// queryTerms 将查询切分为小写词项；中文按相邻两字切分，因为中文没有空格分词.
+func queryTerms(query string) []string {
+	var terms []string
+	add := func(term string) {
+		if term != "" && term != "*" {
+			for _, t := range terms {
+				if t == term {
+					return
+				}
+			}
+			terms = append(terms, term)
+		}
+	}
+	for _, field := range strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(field)
+		if !hasHan(field) {
+			add(field)
+			continue
+		}
+		if len(runes) == 1 {
+			add(field)
+			continue
+		}
+		for i := 0; i+1 < len(runes); i++ {
+			add(string(runes[i : i+2]))
+		}
+	}
+	return terms
+}
+
+// ChunkDocument 将文本切分为约 passageRunes 个字符、相互重叠 passageOverlapRunes
+// 的段落，尽量在句末或换行处断开. 段落去除首尾空白，偏移仍指向原文.
+func ChunkDocument(text string) []Passage {
+	runes := []rune(text)
+	var passages []Passage
+	add := func(start, end int) {
+		for start < end && unicode.IsSpace(runes[start]) {
+			start++
+		}
+		for end > start && unicode.IsSpace(runes[end-1]) {
+			end--
+		}
+		if start < end {
+			passages = append(passages, Passage{Start: start, End: end, Content: string(runes[start:end])})
+		}
+	}
+	for start := 0; start < len(runes); {
+		end := start + passageRunes
+		if end >= len(runes) {
+			add(start, len(runes))
+			break
+		}
+		end = passageBreak(runes, start+passageRunes/2, end)
+		add(start, end)
+		start = max(end-passageOverlapRunes, start+1)
+	}
+	return passages
+}
+
+// passageBreak 返回 runes[from:to] 中最后一个句末或换行之后的位置，
+// 其次为最后一个空白之后，都没有时返回 to.
+func passageBreak(runes []rune, from, to int) int {
+	for i := to - 1; i >= from; i-- {
+		switch runes[i] {
+		case '\n', '。', '！', '？', '；', '.', '!', '?', ';':
+			return i + 1
+		}
+	}
+	for i := to - 1; i >= from; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return i + 1
+		}
+	}
+	return to
+}
+
+type documentKey struct{}
+
+// WithDocument 返回携带会话绑定文档的 context.
+func WithDocument(ctx context.Context, doc *Document) context.Context {
+	return context.WithValue(ctx, documentKey{}, doc)
+}
+
+// DocumentFromContext 返回 context 携带的绑定文档，没有时返回 nil.
+func DocumentFromContext(ctx context.Context) *Document {
+	doc, _ := ctx.Value(documentKey{}).(*Document)
+	return doc
+}
+
+// documentResults 将绑定文档中与查询相关的段落转换为检索结果.
+func documentResults(doc *Document, query string, limit int) []*SearchResult {
+	passages := doc.Search(query, limit)
+	results := make([]*SearchResult, 0, len(passages))
+	for _, p := range passages {
+		results = append(results, &SearchResult{
+			Type:    "document",
+			Content: p.Content,
+			ID:      int64(p.Start),
+			Score:   p.Score,
+			Start:   p.Start,
+			End:     p.End,
+		})
+	}
+	return results
+}
@@ -0,0 +1,66 @@
+package retrieval
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkDocument(t *testing.T) {
+	text := "  " + strings.Repeat("第一部分讲预算。", 60) + "\n\n" + strings.Repeat("The second part covers hiring. ", 40)
+	passages := ChunkDocument(text)
+	require.Greater(t, len(passages), 2)
+
+	doc := &Document{Text: text}
+	for i, p := range passages {
+		// Offsets point into the original text
+		assert.Equal(t, p.Content, doc.Excerpt(p.Start, p.End))
+		assert.LessOrEqual(t, p.End-p.Start, passageRunes)
+		if i > 0 {
+			assert.Greater(t, p.Start, passages[i-1].Start)
+			assert.Less(t, p.Start, passages[i-1].End, "passages overlap")
+		}
+	}
+	assert.Equal(t, 2, passages[0].Start)
+	assert.Equal(t, len([]rune(strings.TrimRight(text, " "))), passages[len(passages)-1].End)
+	assert.Empty(t, ChunkDocument(" \n "))
+}
+
+func TestDocumentSearch(t *testing.T) {
+	text := strings.Repeat("项目进度正常，没有风险。", 50) + "\n" + strings.Repeat("Budget approved for Q3 hiring. ", 20)
+	doc := &Document{Kind: "attachment", UID: "a1", Title: "plan.pdf", Text: text}
+
+	// Chinese queries match by bigrams
+	hits := doc.Search("项目风险", 3)
+	require.NotEmpty(t, hits)
+	assert.Contains(t, hits[0].Content, "风险")
+	assert.Positive(t, hits[0].Score)
+
+	hits = doc.Search("budget", 2)
+	require.NotEmpty(t, hits)
+	assert.Contains(t, hits[0].Content, "Budget")
+
+	// Without a match, the start of the document stands for it
+	hits = doc.Search("unrelated", 2)
+	require.Len(t, hits, 2)
+	assert.Equal(t, 0, hits[0].Start)
+	assert.Equal(t, hits, doc.Search("*", 2))
+}
+
+func TestRetrieveBoundDocument(t *testing.T) {
+	doc := &Document{Kind: "memo", UID: "m1", Title: "Plan", Text: "Ship v2 in May. Hire two engineers."}
+	ctx := WithDocument(context.Background(), doc)
+	assert.Same(t, doc, DocumentFromContext(ctx))
+	assert.Nil(t, DocumentFromContext(context.Background()))
+
+	results, err := (&AdaptiveRetriever{}).Retrieve(ctx, &RetrievalOptions{Query: "engineers", Strategy: "memo_semantic_only", Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "document", results[0].Type)
+	assert.Equal(t, 0, results[0].Start)
+	assert.Equal(t, doc.Excerpt(results[0].Start, results[0].End), results[0].Content)
+	assert.Equal(t, "[@0-35]", CitePassage(results[0].Start, results[0].End))
+}
@@ -0,0 +1,146 @@
+package retrieval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hrygo/divinesense/internal/profile"
+)
+
+// HybridConfig 笔记混合检索（memo_hybrid）的融合参数.
+type HybridConfig struct {
+	// SemanticWeight 向量检索在 RRF 中的权重，BM25 权重为 1 - SemanticWeight
+	SemanticWeight float32
+	// ShortQueryWeight 不超过 ShortQueryRunes 个字符的短查询使用的语义权重
+	ShortQueryWeight float32
+	ShortQueryRunes  int
+	// RRFK RRF 排名常数
+	RRFK int
+	// Candidates 每路检索取回的候选数
+	Candidates int
+}
+
+// DefaultHybridConfig 返回默认融合参数：长查询两路等权，短查询偏重关键词.
+func DefaultHybridConfig() HybridConfig {
+	return HybridConfig{
+		SemanticWeight:   0.5,
+		ShortQueryWeight: 0.3,
+		ShortQueryRunes:  4,
+		RRFK:             RRFK,
+		Candidates:       30,
+	}
+}
+
+// HybridConfigFromProfile 读取 DIVINESENSE_HYBRID_* 配置.
+func HybridConfigFromProfile(p *profile.Profile) HybridConfig {
+	return HybridConfig{
+		SemanticWeight:   float32(p.HybridSemanticWeight),
+		ShortQueryWeight: float32(p.HybridShortQueryWeight),
+		ShortQueryRunes:  p.HybridShortQueryRunes,
+		RRFK:             p.HybridRRFK,
+		Candidates:       p.HybridCandidates,
+	}
+}
+
+// Validate 校验融合参数.
+func (c HybridConfig) Validate() error {
+	if c.SemanticWeight < 0 || c.SemanticWeight > 1 || c.ShortQueryWeight < 0 || c.ShortQueryWeight > 1 {
+		return fmt.Errorf("hybrid semantic weights must be between 0 and 1")
+	}
+	if c.ShortQueryRunes < 0 {
+		return fmt.Errorf("hybrid short query runes cannot be negative")
+	}
+	if c.RRFK < 1 {
+		return fmt.Errorf("hybrid RRF k must be positive")
+	}
+	if c.Candidates < 1 || c.Candidates > 200 {
+		return fmt.Errorf("hybrid candidates must be between 1 and 200")
+	}
+	return nil
+}
+
+// isShort 判断查询是否为短查询.
+func (c HybridConfig) isShort(query string) bool {
+	return utf8.RuneCountInString(strings.TrimSpace(query)) <= c.ShortQueryRunes
+}
+
+// weightFor 返回查询使用的语义权重.
+func (c HybridConfig) weightFor(query string) float32 {
+	if c.isShort(query) {
+		return c.ShortQueryWeight
+	}
+	return c.SemanticWeight
+}
+
+// SetHybridConfig 设置笔记混合检索的融合参数，无效参数保留默认值.
+func (r *AdaptiveRetriever) SetHybridConfig(config HybridConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	r.hybrid = config
+	return nil
+}
+
+// memoHybrid 笔记混合检索：向量相似度与关键词（BM25）两路结果以加权 RRF 融合.
+// 短查询偏重关键词；短中文查询的关键词按子串匹配，因为 simple 分词不切分中文，
+// 「周会」匹配不到「项目周会」. 任一路失败时使用另一路的结果.
+func (r *AdaptiveRetriever) memoHybrid(ctx context.Context, opts *RetrievalOptions) ([]*SearchResult, error) {
+	config := r.hybrid
+	semanticWeight := config.weightFor(opts.Query)
+	substring := config.isShort(opts.Query) && hasHan(opts.Query)
+	opts.Logger.InfoContext(ctx, "Using retrieval strategy",
+		"request_id", opts.RequestID,
+		"strategy", "memo_hybrid",
+		"user_id", opts.UserID,
+		"semantic_weight", semanticWeight,
+		"substring", substring,
+	)
+
+	vectorRes, bm25Res := r.searchBoth(ctx, opts, config.Candidates, substring)
+	if vectorRes.err != nil && bm25Res.err != nil {
+		return nil, fmt.Errorf("both vector and BM25 search failed: vector: %v, bm25: %v", vectorRes.err, bm25Res.err) //nolint:errorlint // Cannot use multiple %w
+	}
+	if vectorRes.err != nil {
+		opts.Logger.WarnContext(ctx, "Vector search failed, using BM25 only",
+			"request_id", opts.RequestID,
+			"error", vectorRes.err,
+		)
+		return r.truncateResults(r.convertBM25Results(bm25Res.results), opts.Limit), nil
+	}
+	if bm25Res.err != nil {
+		opts.Logger.WarnContext(ctx, "BM25 search failed, using vector only",
+			"request_id", opts.RequestID,
+			"error", bm25Res.err,
+		)
+		return r.truncateResults(r.convertVectorResults(vectorRes.results), opts.Limit), nil
+	}
+
+	// 用户的相关性反馈调优出的权重优先于配置
+	if r.tuner != nil {
+		if weight, ok := r.tuner.SemanticWeight(ctx, opts.UserID); ok {
+			semanticWeight = weight
+		}
+		r.tuner.LogSearch(ctx, opts.UserID, opts.Query, semanticWeight, vectorMemoIDs(vectorRes.results), bm25MemoIDs(bm25Res.results))
+	}
+
+	results := r.rrfFusionK(vectorRes.results, bm25Res.results, semanticWeight, config.RRFK)
+	opts.Logger.InfoContext(ctx, "Hybrid retrieval completed",
+		"request_id", opts.RequestID,
+		"vector_count", len(vectorRes.results),
+		"bm25_count", len(bm25Res.results),
+		"fused_count", len(results),
+	)
+	return r.truncateResults(results, opts.Limit), nil
+}
+
+func hasHan(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package retrieval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/store"
+)
+
+func TestHybridConfig(t *testing.T) {
+	config := DefaultHybridConfig()
+	require.NoError(t, config.Validate())
+
+	// Short queries lean on keywords
+	assert.Equal(t, float32(0.3), config.weightFor("周会"))
+	assert.Equal(t, float32(0.3), config.weightFor(" rust "))
+	assert.Equal(t, float32(0.5), config.weightFor("上周项目周会的结论"))
+	assert.True(t, hasHan("周会 notes"))
+	assert.False(t, hasHan("notes"))
+
+	for _, invalid := range []HybridConfig{
+		{SemanticWeight: 1.5, RRFK: 60, Candidates: 30},
+		{SemanticWeight: 0.5, ShortQueryWeight: -0.1, RRFK: 60, Candidates: 30},
+		{SemanticWeight: 0.5, RRFK: 0, Candidates: 30},
+		{SemanticWeight: 0.5, RRFK: 60, Candidates: 0},
+	} {
+		assert.Error(t, invalid.Validate(), "%+v", invalid)
+	}
+
+	// Invalid configurations keep the defaults
+	retriever := NewAdaptiveRetriever(nil, nil, nil)
+	assert.Error(t, retriever.SetHybridConfig(HybridConfig{SemanticWeight: 2}))
+	assert.Equal(t, DefaultHybridConfig(), retriever.hybrid)
+	require.NoError(t, retriever.SetHybridConfig(HybridConfig{SemanticWeight: 0.7, ShortQueryWeight: 0.2, ShortQueryRunes: 2, RRFK: 10, Candidates: 50}))
+	assert.Equal(t, float32(0.7), retriever.hybrid.weightFor("周会纪要"))
+}
+
+func TestRRFFusionK(t *testing.T) {
+	retriever := &AdaptiveRetriever{}
+	memo := func(id int32) *store.Memo { return &store.Memo{ID: id} }
+	vectorResults := []*store.MemoWithScore{{Memo: memo(1)}, {Memo: memo(2)}}
+	bm25Results := []*store.BM25Result{{Memo: memo(2)}, {Memo: memo(1)}}
+
+	// Keyword-heavy weight ranks the BM25 winner first
+	results := retriever.rrfFusionK(vectorResults, bm25Results, 0.3, 60)
+	require.Len(t, results, 2)
+	assert.Equal(t, int64(2), results[0].ID)
+
+	// A smaller k separates the ranks more
+	wide := retriever.rrfFusionK(vectorResults, nil, 1, 1)
+	narrow := retriever.rrfFusionK(vectorResults, nil, 1, 60)
+	assert.Greater(t, wide[0].Score-wide[1].Score, narrow[0].Score-narrow[1].Score)
+}
@@ -0,0 +1,88 @@
+package retrieval
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hrygo/divinesense/store"
+)
+
+// scopeOverfetch 限定标签时多取的候选倍数，弥补按标签过滤掉的结果.
+const scopeOverfetch = 4
+
+// Scope 会话的检索范围：只检索带有指定标签（含子标签）的笔记，或关闭笔记检索.
+// 日程不受影响.
+type Scope struct {
+	Disabled bool     `json:"disabled,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// Restricted 判断检索范围是否有限制；nil 不限制.
+func (s *Scope) Restricted() bool {
+	return s != nil && (s.Disabled || len(s.Tags) > 0)
+}
+
+// Allows 判断笔记是否在检索范围内.
+func (s *Scope) Allows(memo *store.Memo) bool {
+	if !s.Restricted() {
+		return true
+	}
+	if s.Disabled || memo == nil {
+		return false
+	}
+	return s.AllowsTags(getMemoTags(memo))
+}
+
+// AllowsTags 判断带有这些标签的笔记是否在检索范围内.
+func (s *Scope) AllowsTags(tags []string) bool {
+	if !s.Restricted() {
+		return true
+	}
+	if s.Disabled {
+		return false
+	}
+	for _, scopeTag := range s.Tags {
+		for _, tag := range tags {
+			if strings.EqualFold(tag, scopeTag) || (len(tag) > len(scopeTag) && tag[len(scopeTag)] == '/' && strings.EqualFold(tag[:len(scopeTag)], scopeTag)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// String 描述检索范围，用于提示与日志.
+func (s *Scope) String() string {
+	switch {
+	case !s.Restricted():
+		return "all memos"
+	case s.Disabled:
+		return "disabled"
+	default:
+		return "tags: #" + strings.Join(s.Tags, ", #")
+	}
+}
+
+type scopeKey struct{}
+
+// WithScope 返回携带会话检索范围的 context.
+func WithScope(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// ScopeFromContext 返回 context 携带的检索范围，没有时返回 nil.
+func ScopeFromContext(ctx context.Context) *Scope {
+	scope, _ := ctx.Value(scopeKey{}).(*Scope)
+	return scope
+}
+
+// applyScope 按检索范围过滤笔记结果，日程结果保留.
+func applyScope(results []*SearchResult, scope *Scope) []*SearchResult {
+	filtered := make([]*SearchResult, 0, len(results))
+	for _, result := range results {
+		if result.Type != "memo" || scope.Allows(result.Memo) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
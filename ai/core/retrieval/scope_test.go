@@ -0,0 +1,58 @@
+package retrieval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	storepb "github.com/hrygo/divinesense/proto/gen/store"
+	"github.com/hrygo/divinesense/store"
+)
+
+func TestScopeAllowsTags(t *testing.T) {
+	var unrestricted *Scope
+	assert.False(t, unrestricted.Restricted())
+	assert.True(t, unrestricted.AllowsTags(nil))
+	assert.Equal(t, "all memos", unrestricted.String())
+
+	scope := &Scope{Tags: []string{"work", "读书"}}
+	assert.True(t, scope.AllowsTags([]string{"Work"}))
+	assert.True(t, scope.AllowsTags([]string{"misc", "work/meeting"}))
+	assert.True(t, scope.AllowsTags([]string{"读书/笔记"}))
+	assert.False(t, scope.AllowsTags([]string{"workshop"}))
+	assert.False(t, scope.AllowsTags(nil))
+	assert.Equal(t, "tags: #work, #读书", scope.String())
+
+	disabled := &Scope{Disabled: true}
+	assert.True(t, disabled.Restricted())
+	assert.False(t, disabled.AllowsTags([]string{"work"}))
+	assert.Equal(t, "disabled", disabled.String())
+}
+
+func TestApplyScope(t *testing.T) {
+	memo := func(id int32, tags ...string) *SearchResult {
+		return &SearchResult{ID: int64(id), Type: "memo", Memo: &store.Memo{ID: id, Payload: &storepb.MemoPayload{Tags: tags}}}
+	}
+	results := []*SearchResult{
+		memo(1, "work"),
+		memo(2, "home"),
+		{ID: 3, Type: "schedule"},
+		memo(4),
+	}
+
+	filtered := applyScope(results, &Scope{Tags: []string{"work"}})
+	assert.Len(t, filtered, 2)
+	assert.Equal(t, int64(1), filtered[0].ID)
+	assert.Equal(t, "schedule", filtered[1].Type)
+
+	assert.Len(t, applyScope(results, nil), 4)
+	assert.Len(t, applyScope(results, &Scope{Disabled: true}), 1)
+}
+
+func TestScopeFromContext(t *testing.T) {
+	assert.Nil(t, ScopeFromContext(context.Background()))
+
+	scope := &Scope{Tags: []string{"work"}}
+	assert.Same(t, scope, ScopeFromContext(WithScope(context.Background(), scope)))
+}
@@ -61,6 +61,9 @@ const DefaultEaseFactor = 2.5
 // MinEaseFactor is the minimum ease factor to prevent intervals from getting too short.
 const MinEaseFactor = 1.3
 
+// MaxIntervalDays caps the interval between two reviews.
+const MaxIntervalDays = 365
+
 // ReviewConfig contains configuration for the review system.
 type ReviewConfig struct {
 	MaxDailyReviews     int
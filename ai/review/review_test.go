@@ -478,9 +478,9 @@ func TestCalculateStreak(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := calculateStreak(tt.dates, today)
+			result := CalculateStreak(tt.dates, today)
 			if result != tt.expected {
-				t.Errorf("calculateStreak() = %d, want %d", result, tt.expected)
+				t.Errorf("CalculateStreak() = %d, want %d", result, tt.expected)
 			}
 		})
 	}
@@ -191,7 +191,7 @@ func (s *Service) GetReviewStats(ctx context.Context, userID int32) (*ReviewStat
 	}
 
 	// Calculate streak days (consecutive days with reviews ending today or yesterday)
-	stats.StreakDays = calculateStreak(reviewDates, today)
+	stats.StreakDays = CalculateStreak(reviewDates, today)
 
 	// Average accuracy: estimate based on mastered ratio (simplified)
 	if stats.TotalMemos > 0 {
@@ -201,8 +201,9 @@ func (s *Service) GetReviewStats(ctx context.Context, userID int32) (*ReviewStat
 	return stats, nil
 }
 
-// calculateStreak counts consecutive days with reviews ending today or yesterday.
-func calculateStreak(reviewDates map[string]bool, today time.Time) int {
+// CalculateStreak counts consecutive days with reviews ending today or
+// yesterday. reviewDates holds the review days formatted as "2006-01-02".
+func CalculateStreak(reviewDates map[string]bool, today time.Time) int {
 	streak := 0
 	checkDate := today
 
@@ -305,53 +306,62 @@ func (s *Service) applySM2Algorithm(state *ReviewState, quality ReviewQuality) *
 	state.ReviewCount++
 	state.LastReview = now
 
+	state.EaseFactor, state.IntervalDays = Schedule(state.EaseFactor, state.IntervalDays, quality)
+
+	// Set next review date
+	state.NextReview = now.AddDate(0, 0, state.IntervalDays)
+
+	return state
+}
+
+// Schedule applies one SM-2 step to an item with the given ease factor and
+// interval, returning the new ease factor and the days until the next review.
+// It is shared by memo reviews and flashcards.
+func Schedule(easeFactor float64, intervalDays int, quality ReviewQuality) (float64, int) {
 	// Calculate new ease factor
 	// EF' = EF + (0.1 - (3 - q) * (0.08 + (3 - q) * 0.02))
 	q := float64(quality)
-	state.EaseFactor += 0.1 - (3-q)*(0.08+(3-q)*0.02)
-	if state.EaseFactor < MinEaseFactor {
-		state.EaseFactor = MinEaseFactor
+	easeFactor += 0.1 - (3-q)*(0.08+(3-q)*0.02)
+	if easeFactor < MinEaseFactor {
+		easeFactor = MinEaseFactor
 	}
 
 	// Calculate new interval
 	switch quality {
 	case QualityAgain:
 		// Reset to beginning
-		state.IntervalDays = 1
+		intervalDays = 1
 	case QualityHard:
 		// Reduce interval slightly
-		state.IntervalDays = int(float64(state.IntervalDays) * 1.2)
-		if state.IntervalDays < 1 {
-			state.IntervalDays = 1
+		intervalDays = int(float64(intervalDays) * 1.2)
+		if intervalDays < 1 {
+			intervalDays = 1
 		}
 	case QualityGood:
 		// Standard progression
 		//nolint:staticcheck // if-else is more readable than nested switch
-		if state.IntervalDays == 0 {
-			state.IntervalDays = 1
-		} else if state.IntervalDays == 1 {
-			state.IntervalDays = 3
+		if intervalDays == 0 {
+			intervalDays = 1
+		} else if intervalDays == 1 {
+			intervalDays = 3
 		} else {
-			state.IntervalDays = int(float64(state.IntervalDays) * state.EaseFactor)
+			intervalDays = int(float64(intervalDays) * easeFactor)
 		}
 	case QualityEasy:
 		// Accelerated progression
-		if state.IntervalDays == 0 {
-			state.IntervalDays = 3
+		if intervalDays == 0 {
+			intervalDays = 3
 		} else {
-			state.IntervalDays = int(float64(state.IntervalDays) * state.EaseFactor * 1.3)
+			intervalDays = int(float64(intervalDays) * easeFactor * 1.3)
 		}
 	}
 
 	// Cap maximum interval at 365 days
-	if state.IntervalDays > 365 {
-		state.IntervalDays = 365
+	if intervalDays > MaxIntervalDays {
+		intervalDays = MaxIntervalDays
 	}
 
-	// Set next review date
-	state.NextReview = now.AddDate(0, 0, state.IntervalDays)
-
-	return state
+	return easeFactor, intervalDays
 }
 
 // calculatePriority computes the review priority for sorting.
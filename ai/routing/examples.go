@@ -0,0 +1,179 @@
+package routing
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+const (
+	// exampleMinSimilarity is the similarity from which an input is routed
+	// like its nearest example.
+	exampleMinSimilarity = 0.7
+	// exampleConfidence is the confidence of routes taken from examples:
+	// curated by admins, so routed directly, but below the taxonomy.
+	exampleConfidence float32 = 0.85
+)
+
+// RouteExample is an admin-curated query with the route it should take.
+type RouteExample struct {
+	Query string
+	Route AgentType
+}
+
+// ExampleSet is an immutable set of few-shot routing examples, matched by the
+// character bigram similarity of their queries.
+type ExampleSet struct {
+	examples []exampleEntry
+}
+
+type exampleEntry struct {
+	example RouteExample
+	bigrams map[string]int
+	size    int
+}
+
+// NewExampleSet builds the set of examples.
+func NewExampleSet(examples []RouteExample) *ExampleSet {
+	s := &ExampleSet{}
+	for _, example := range examples {
+		bigrams, size := exampleBigrams(example.Query)
+		if size == 0 {
+			continue
+		}
+		s.examples = append(s.examples, exampleEntry{example: example, bigrams: bigrams, size: size})
+	}
+	return s
+}
+
+// Nearest returns the example most similar to input and its similarity in
+// [0, 1], if any example reaches exampleMinSimilarity.
+func (s *ExampleSet) Nearest(input string) (RouteExample, float64, bool) {
+	if s == nil || len(s.examples) == 0 {
+		return RouteExample{}, 0, false
+	}
+	bigrams, size := exampleBigrams(input)
+	if size == 0 {
+		return RouteExample{}, 0, false
+	}
+
+	var best *exampleEntry
+	var bestSimilarity float64
+	for i := range s.examples {
+		entry := &s.examples[i]
+		shared := 0
+		for bigram, count := range bigrams {
+			shared += min(count, entry.bigrams[bigram])
+		}
+		// Dice coefficient of the bigram multisets
+		similarity := 2 * float64(shared) / float64(size+entry.size)
+		if similarity > bestSimilarity {
+			best, bestSimilarity = entry, similarity
+		}
+	}
+	if best == nil || bestSimilarity < exampleMinSimilarity {
+		return RouteExample{}, bestSimilarity, false
+	}
+	return best.example, bestSimilarity, true
+}
+
+// Len returns the number of examples.
+func (s *ExampleSet) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.examples)
+}
+
+// exampleBigrams returns the character bigrams of the letters and digits of
+// text, lowercased; a single character counts as its own bigram.
+func exampleBigrams(text string) (map[string]int, int) {
+	var runes []rune
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, r)
+		}
+	}
+	bigrams := make(map[string]int)
+	if len(runes) == 1 {
+		bigrams[string(runes)]++
+		return bigrams, 1
+	}
+	for i := 0; i+1 < len(runes); i++ {
+		bigrams[string(runes[i:i+2])]++
+	}
+	return bigrams, max(len(runes)-1, 0)
+}
+
+// RouteEvaluation reports the routing accuracy of a holdout set of examples
+// without and with the few-shot examples.
+type RouteEvaluation struct {
+	Holdout          int               `json:"holdout"`
+	Examples         int               `json:"examples"`
+	BaselineCorrect  int               `json:"baseline_correct"`
+	ExamplesCorrect  int               `json:"examples_correct"`
+	BaselineAccuracy float64           `json:"baseline_accuracy"`
+	ExamplesAccuracy float64           `json:"examples_accuracy"`
+	Changes          []RouteEvalChange `json:"changes"`
+}
+
+// RouteEvalChange is a holdout query whose route changed with the examples.
+type RouteEvalChange struct {
+	Query        string    `json:"query"`
+	Expected     AgentType `json:"expected"`
+	Baseline     AgentType `json:"baseline"`
+	WithExamples AgentType `json:"with_examples"`
+}
+
+// Fixed reports whether the examples fixed the route of the query.
+func (c RouteEvalChange) Fixed() bool {
+	return c.WithExamples == c.Expected
+}
+
+// EvaluateExamples routes the holdout queries with a router without
+// examples and one with the given examples. newRouter must return a fresh
+// router on every call, so the two do not share examples or cache.
+func EvaluateExamples(ctx context.Context, newRouter func() *Service, examples, holdout []RouteExample) *RouteEvaluation {
+	baseline := newRouter()
+	baseline.SetExamples(nil)
+	withExamples := newRouter()
+	withExamples.SetExamples(NewExampleSet(examples))
+
+	eval := &RouteEvaluation{Holdout: len(holdout), Examples: len(examples), Changes: []RouteEvalChange{}}
+	for _, example := range holdout {
+		before := baseline.routeOf(ctx, example.Query)
+		after := withExamples.routeOf(ctx, example.Query)
+		if before == example.Route {
+			eval.BaselineCorrect++
+		}
+		if after == example.Route {
+			eval.ExamplesCorrect++
+		}
+		if before != after {
+			eval.Changes = append(eval.Changes, RouteEvalChange{
+				Query:        example.Query,
+				Expected:     example.Route,
+				Baseline:     before,
+				WithExamples: after,
+			})
+		}
+	}
+	if eval.Holdout > 0 {
+		eval.BaselineAccuracy = float64(eval.BaselineCorrect) / float64(eval.Holdout)
+		eval.ExamplesAccuracy = float64(eval.ExamplesCorrect) / float64(eval.Holdout)
+	}
+	return eval
+}
+
+// routeOf returns the agent a chat input is routed to, or AgentTypeUnknown
+// when it goes to the orchestrator.
+func (s *Service) routeOf(ctx context.Context, input string) AgentType {
+	intent, _, needsOrch, err := s.ClassifyIntent(ctx, input)
+	if err != nil || needsOrch {
+		return AgentTypeUnknown
+	}
+	if ti, ok := s.TaxonomyIntent(intent); ok {
+		return AgentType(ti.TargetExpert)
+	}
+	return IntentToAgentType(intent)
+}
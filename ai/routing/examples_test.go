@@ -0,0 +1,77 @@
+package routing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExampleSet_Nearest(t *testing.T) {
+	set := NewExampleSet([]RouteExample{
+		{Query: "帮我整理一下读书摘抄", Route: AgentTypeMemo},
+		{Query: "把下周的站会挪到周三", Route: AgentTypeSchedule},
+		{Query: "   ", Route: AgentTypeGeneral},
+	})
+	assert.Equal(t, 2, set.Len())
+
+	example, similarity, ok := set.Nearest("帮我整理一下读书摘抄吧")
+	require.True(t, ok)
+	assert.Equal(t, AgentTypeMemo, example.Route)
+	assert.Greater(t, similarity, 0.9)
+
+	example, _, ok = set.Nearest("把下周的站会挪到周四")
+	require.True(t, ok)
+	assert.Equal(t, AgentTypeSchedule, example.Route)
+
+	_, _, ok = set.Nearest("今天天气怎么样")
+	assert.False(t, ok)
+
+	var empty *ExampleSet
+	_, _, ok = empty.Nearest("帮我整理一下读书摘抄")
+	assert.False(t, ok)
+	assert.Equal(t, 0, empty.Len())
+}
+
+func TestService_ClassifyIntent_Examples(t *testing.T) {
+	svc := newTestService(Config{EnableCache: true})
+	ctx := context.Background()
+
+	intent, _, needsOrch, err := svc.ClassifyIntent(ctx, "帮我整理一下读书摘抄")
+	require.NoError(t, err)
+	before := IntentToAgentType(intent)
+	if needsOrch {
+		before = AgentTypeUnknown
+	}
+	require.NotEqual(t, AgentTypeMemo, before)
+
+	svc.SetExamples(NewExampleSet([]RouteExample{{Query: "帮我整理一下读书摘抄", Route: AgentTypeMemo}}))
+
+	// The cached decision is dropped and the example routes the query
+	intent, confidence, needsOrch, err := svc.ClassifyIntent(ctx, "帮我整理一下读书摘抄")
+	require.NoError(t, err)
+	assert.Equal(t, AgentTypeMemo, IntentToAgentType(intent))
+	assert.Equal(t, exampleConfidence, confidence)
+	assert.False(t, needsOrch)
+}
+
+func TestEvaluateExamples(t *testing.T) {
+	newRouter := func() *Service { return newTestService(Config{}) }
+	examples := []RouteExample{{Query: "帮我整理一下读书摘抄", Route: AgentTypeMemo}}
+	holdout := []RouteExample{
+		{Query: "帮我整理一下读书摘抄吧", Route: AgentTypeMemo},
+		{Query: "搜索关于 Go 的笔记", Route: AgentTypeMemo},
+	}
+
+	eval := EvaluateExamples(context.Background(), newRouter, examples, holdout)
+	assert.Equal(t, 2, eval.Holdout)
+	assert.Equal(t, 1, eval.Examples)
+	assert.Equal(t, 1, eval.BaselineCorrect)
+	assert.Equal(t, 2, eval.ExamplesCorrect)
+	assert.InDelta(t, 0.5, eval.BaselineAccuracy, 0.001)
+	assert.InDelta(t, 1.0, eval.ExamplesAccuracy, 0.001)
+	require.Len(t, eval.Changes, 1)
+	assert.Equal(t, "帮我整理一下读书摘抄吧", eval.Changes[0].Query)
+	assert.True(t, eval.Changes[0].Fixed())
+}
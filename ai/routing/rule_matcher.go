@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"unicode"
 )
 
@@ -57,6 +58,7 @@ type RuleMatcher struct {
 	// User-specific custom weights (optional, for dynamic adjustment)
 	customWeights   map[int32]map[string]map[string]int // userID -> category -> keyword -> weight
 	customWeightsMu sync.RWMutex
+	examples        atomic.Pointer[ExampleSet] // Admin-curated few-shot examples
 }
 
 // NewRuleMatcher creates a new rule matcher.
@@ -92,6 +94,25 @@ func (m *RuleMatcher) SetSemanticMatcher(matcher SemanticMatcher) {
 	m.semanticMatcher = matcher
 }
 
+// SetExamples replaces the few-shot examples (nil removes them).
+func (m *RuleMatcher) SetExamples(examples *ExampleSet) {
+	m.examples.Store(examples)
+}
+
+// MatchExample routes input like its nearest few-shot example, if one is
+// similar enough. Examples are admin corrections, so they win over keywords.
+func (m *RuleMatcher) MatchExample(input string) (Intent, float32, bool) {
+	example, _, ok := m.examples.Load().Nearest(input)
+	if !ok {
+		return IntentUnknown, 0, false
+	}
+	intent := m.expertToIntent(string(example.Route), input)
+	if intent == IntentUnknown {
+		return IntentUnknown, 0, false
+	}
+	return intent, exampleConfidence, true
+}
+
 // Match performs rule-based pattern matching and returns generic action + matched keywords.
 // This is SOLID compliant: RuleMatcher only recognizes patterns, not expert types.
 // The mapping from GenericAction + Keywords to Expert is handled by IntentRegistry/ExpertRouter.
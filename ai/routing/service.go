@@ -1,4 +1,4 @@
-// Package routing provides the FastRouter service (cache -> rule).
+// Package routing provides the FastRouter service (cache -> taxonomy -> rule).
 package routing
 
 import (
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,9 +18,10 @@ type Service struct {
 	cache             *RouterCache // Performance optimization: cache routing decisions
 	feedbackCollector *FeedbackCollector
 	weightStorage     RouterWeightStorage
-	registry          *IntentRegistry // OCP-compliant intent registry
-	modelStrategy     ModelStrategy   // OCP-compliant model selection
-	bgWg              sync.WaitGroup  // WaitGroup for background goroutines
+	registry          *IntentRegistry          // OCP-compliant intent registry
+	modelStrategy     ModelStrategy            // OCP-compliant model selection
+	bgWg              sync.WaitGroup           // WaitGroup for background goroutines
+	taxonomy          atomic.Pointer[Taxonomy] // Admin-defined intents, hot reloaded
 }
 
 // Config contains the configuration for the router service.
@@ -105,7 +107,7 @@ func (s *Service) ClassifyIntent(ctx context.Context, input string) (Intent, flo
 	// Layer 0: Cache lookup (fastest path - ~0ms)
 	if s.cache != nil {
 		if intent, confidence, found := s.cache.Get(input); found {
-			needsOrch := s.needsOrchestration(intent, confidence, input)
+			needsOrch := s.orchestrationNeeded(intent, confidence, input)
 			slog.Debug("intent classified by cache",
 				"input", truncate(input, 50),
 				"intent", intent,
@@ -116,14 +118,32 @@ func (s *Service) ClassifyIntent(ctx context.Context, input string) (Intent, flo
 		}
 	}
 
-	// Layer 1: Rule-based matching
+	// Layer 1: Admin-defined intent taxonomy
+	if ti, ok := s.taxonomy.Load().Match(input); ok {
+		intent := Intent(ti.Name)
+		if s.cache != nil {
+			s.cache.Set(input, intent, taxonomyConfidence, "taxonomy")
+		}
+		slog.Debug("intent classified by taxonomy",
+			"input", truncate(input, 50),
+			"intent", intent,
+			"target", ti.TargetExpert,
+			"needs_orchestration", ti.Orchestrate,
+			"latency_ms", time.Since(start).Milliseconds())
+		return intent, taxonomyConfidence, ti.Orchestrate, nil
+	}
+
+	// Layer 2: Rule-based matching
 	// Use MatchWithUser if userID is available to apply custom weights
 	userID := getUserIDFromContext(ctx)
 	var intent Intent
 	var confidence float32
 	var matched bool
 
-	if userID > 0 {
+	if exampleIntent, exampleConf, ok := s.ruleMatcher.MatchExample(input); ok {
+		// Few-shot examples curated by admins come first
+		intent, confidence, matched = exampleIntent, exampleConf, true
+	} else if userID > 0 {
 		// MatchWithUser still returns 3 values for backward compatibility
 		intent, confidence, matched = s.ruleMatcher.MatchWithUser(input, userID)
 	} else {
@@ -153,13 +173,48 @@ func (s *Service) ClassifyIntent(ctx context.Context, input string) (Intent, flo
 		return intent, confidence, needsOrch, nil
 	}
 
-	// Layer 2: No match → needs orchestration
+	// Layer 3: No match → needs orchestration
 	slog.Debug("no intent match found, needs orchestration",
 		"input", truncate(input, 50),
 		"latency_ms", time.Since(start).Milliseconds())
 	return IntentUnknown, 0, true, nil
 }
 
+// SetTaxonomy replaces the admin-defined intents (nil removes them). Cached
+// routing decisions are dropped, since they may predate the taxonomy.
+func (s *Service) SetTaxonomy(t *Taxonomy) {
+	s.taxonomy.Store(t)
+	if s.cache != nil {
+		s.cache.Clear()
+	}
+	slog.Info("intent taxonomy reloaded", "intents", t.Len())
+}
+
+// SetExamples replaces the few-shot routing examples of the rule layer (nil
+// removes them). Cached routing decisions are dropped.
+func (s *Service) SetExamples(examples *ExampleSet) {
+	s.ruleMatcher.SetExamples(examples)
+	if s.cache != nil {
+		s.cache.Clear()
+	}
+	slog.Info("routing examples reloaded", "examples", examples.Len())
+}
+
+// TaxonomyIntent returns the taxonomy intent of a classified intent, if the
+// intent comes from the taxonomy.
+func (s *Service) TaxonomyIntent(intent Intent) (*TaxonomyIntent, bool) {
+	return s.taxonomy.Load().Lookup(intent)
+}
+
+// orchestrationNeeded is needsOrchestration, except for taxonomy intents
+// whose orchestration flag decides.
+func (s *Service) orchestrationNeeded(intent Intent, confidence float32, input string) bool {
+	if ti, ok := s.TaxonomyIntent(intent); ok {
+		return ti.Orchestrate
+	}
+	return s.needsOrchestration(intent, confidence, input)
+}
+
 // needsOrchestration determines if the request needs Orchestrator handling.
 // Threshold: 0.8 (stricter, more requests go to Orchestrator)
 func (s *Service) needsOrchestration(intent Intent, confidence float32, input string) bool {
@@ -0,0 +1,88 @@
+// Package routing provides the administrable intent taxonomy of the router.
+package routing
+
+import (
+	"sort"
+	"strings"
+)
+
+// taxonomyConfidence is the confidence of intents matched by an example of
+// the taxonomy: examples are curated by admins, so they win over rules.
+const taxonomyConfidence float32 = 0.95
+
+// TaxonomyIntent is an intent defined by admins rather than in code.
+type TaxonomyIntent struct {
+	Name string
+	// Examples are phrases of the intent; an input containing one matches.
+	Examples []string
+	// TargetExpert is the route of the intent, e.g. "memo" or "schedule".
+	TargetExpert string
+	// Orchestrate sends the intent to the orchestrator instead of the target.
+	Orchestrate bool
+}
+
+// Taxonomy is an immutable set of intents matched by their examples.
+type Taxonomy struct {
+	intents  map[Intent]*TaxonomyIntent
+	examples []taxonomyExample // Longest first
+}
+
+type taxonomyExample struct {
+	phrase string
+	intent *TaxonomyIntent
+}
+
+// NewTaxonomy builds the taxonomy of intents.
+func NewTaxonomy(intents []TaxonomyIntent) *Taxonomy {
+	t := &Taxonomy{intents: make(map[Intent]*TaxonomyIntent, len(intents))}
+	for i := range intents {
+		intent := &intents[i]
+		t.intents[Intent(intent.Name)] = intent
+		for _, example := range intent.Examples {
+			if phrase := normalizeExample(example); phrase != "" {
+				t.examples = append(t.examples, taxonomyExample{phrase: phrase, intent: intent})
+			}
+		}
+	}
+	// The most specific example wins
+	sort.SliceStable(t.examples, func(i, j int) bool {
+		return len(t.examples[i].phrase) > len(t.examples[j].phrase)
+	})
+	return t
+}
+
+// Match returns the intent of the longest example contained in input.
+func (t *Taxonomy) Match(input string) (*TaxonomyIntent, bool) {
+	if t == nil {
+		return nil, false
+	}
+	normalized := normalizeExample(input)
+	for _, example := range t.examples {
+		if strings.Contains(normalized, example.phrase) {
+			return example.intent, true
+		}
+	}
+	return nil, false
+}
+
+// Lookup returns the taxonomy intent of a classified intent.
+func (t *Taxonomy) Lookup(intent Intent) (*TaxonomyIntent, bool) {
+	if t == nil {
+		return nil, false
+	}
+	ti, ok := t.intents[intent]
+	return ti, ok
+}
+
+// Len returns the number of intents.
+func (t *Taxonomy) Len() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.intents)
+}
+
+// normalizeExample lowercases text and collapses its whitespace.
+func normalizeExample(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
@@ -0,0 +1,84 @@
+package routing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTaxonomy() *Taxonomy {
+	return NewTaxonomy([]TaxonomyIntent{
+		{Name: "weekly_review", Examples: []string{"周回顾", "Weekly  Review"}, TargetExpert: "memo"},
+		{Name: "trip_planning", Examples: []string{"旅行计划", "周回顾旅行计划"}, Orchestrate: true},
+	})
+}
+
+func TestTaxonomy_Match(t *testing.T) {
+	taxonomy := testTaxonomy()
+
+	ti, ok := taxonomy.Match("帮我做一下周回顾")
+	require.True(t, ok)
+	assert.Equal(t, "weekly_review", ti.Name)
+
+	// Case and whitespace are ignored
+	ti, ok = taxonomy.Match("start my weekly review please")
+	require.True(t, ok)
+	assert.Equal(t, "weekly_review", ti.Name)
+
+	// The longest example wins
+	ti, ok = taxonomy.Match("整理周回顾旅行计划")
+	require.True(t, ok)
+	assert.Equal(t, "trip_planning", ti.Name)
+
+	_, ok = taxonomy.Match("今天天气怎么样")
+	assert.False(t, ok)
+
+	var empty *Taxonomy
+	_, ok = empty.Match("周回顾")
+	assert.False(t, ok)
+	assert.Equal(t, 0, empty.Len())
+	assert.Equal(t, 2, taxonomy.Len())
+}
+
+func TestService_ClassifyIntent_Taxonomy(t *testing.T) {
+	svc := newTestService(Config{EnableCache: true})
+	ctx := context.Background()
+
+	// Rules classify the input until the taxonomy defines it
+	intent, _, _, err := svc.ClassifyIntent(ctx, "搜索周回顾笔记")
+	require.NoError(t, err)
+	assert.Equal(t, IntentMemoSearch, intent)
+
+	svc.SetTaxonomy(testTaxonomy())
+
+	intent, confidence, needsOrch, err := svc.ClassifyIntent(ctx, "搜索周回顾笔记")
+	require.NoError(t, err)
+	assert.Equal(t, Intent("weekly_review"), intent)
+	assert.Equal(t, taxonomyConfidence, confidence)
+	assert.False(t, needsOrch)
+
+	intent, _, needsOrch, err = svc.ClassifyIntent(ctx, "做个旅行计划")
+	require.NoError(t, err)
+	assert.Equal(t, Intent("trip_planning"), intent)
+	assert.True(t, needsOrch)
+
+	// Cache hits keep the orchestration flag of the taxonomy
+	intent, _, needsOrch, err = svc.ClassifyIntent(ctx, "做个旅行计划")
+	require.NoError(t, err)
+	assert.Equal(t, Intent("trip_planning"), intent)
+	assert.True(t, needsOrch)
+
+	ti, ok := svc.TaxonomyIntent("weekly_review")
+	require.True(t, ok)
+	assert.Equal(t, "memo", ti.TargetExpert)
+	_, ok = svc.TaxonomyIntent(IntentMemoSearch)
+	assert.False(t, ok)
+
+	// Removing the taxonomy also drops its cached decisions
+	svc.SetTaxonomy(nil)
+	intent, _, _, err = svc.ClassifyIntent(ctx, "搜索周回顾笔记")
+	require.NoError(t, err)
+	assert.Equal(t, IntentMemoSearch, intent)
+}
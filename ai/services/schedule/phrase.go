@@ -0,0 +1,186 @@
+package schedule
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrUnrecognizedRecurrence is returned for phrases without a recurrence.
+var ErrUnrecognizedRecurrence = errors.New("unrecognized recurrence")
+
+var (
+	// zhInterval matches "每两周", "每隔3天" and "每2个月".
+	zhInterval = regexp.MustCompile(`(每隔|隔|每)([0-9]+|[一二两三四五六七八九十]+)个?(天|日|周|月)`)
+	// zhWeekRange matches "周一到周五".
+	zhWeekRange = regexp.MustCompile(`周([一二三四五六日天1-7])(?:到|至|~|-|—)周?([一二三四五六日天1-7])`)
+	// zhWeekdays matches "周三", "周一三五" and "周二和周四".
+	zhWeekdays = regexp.MustCompile(`周((?:[一二三四五六日天1-7][、,，和及与/]?周?)+)`)
+	// zhMonthDay matches "月15号" and "月的十五日".
+	zhMonthDay = regexp.MustCompile(`月的?([0-9]{1,2}|[一二三四五六七八九十]+)[号日]`)
+
+	// recurring matches the words that make a phrase recurring.
+	recurring = regexp.MustCompile(`每|隔|双周|天天|工作日|周末|every|daily|weekly|monthly|biweekly|fortnight|weekday|weekend|workday|\b(mon|tues|wednes|thurs|fri|satur|sun)days\b`)
+
+	// enInterval matches "every 2 weeks" and "every other day".
+	enInterval = regexp.MustCompile(`every\s+(\d+|other|second)\s+(day|week|month)s?\b`)
+	// enWeekday matches the names of the weekdays and their abbreviations.
+	enWeekday = regexp.MustCompile(`\b(mon(?:day)?|tue(?:s|sday)?|wed(?:nesday)?|thu(?:r|rs|rsday)?|fri(?:day)?|sat(?:urday)?|sun(?:day)?)s?\b`)
+	// enMonthDay matches "day 15" and "the 15th".
+	enMonthDay = regexp.MustCompile(`\b(?:day\s+(\d{1,2})|(\d{1,2})(?:st|nd|rd|th))\b`)
+)
+
+// zhWeekdayIndex maps the weekday characters of Chinese to 1 (Monday) to 7.
+var zhWeekdayIndex = map[rune]int{
+	'一': 1, '二': 2, '三': 3, '四': 4, '五': 5, '六': 6, '日': 7, '天': 7,
+	'1': 1, '2': 2, '3': 3, '4': 4, '5': 5, '6': 6, '7': 7,
+}
+
+// ParseRecurrencePhrase parses the recurrence of a phrase, e.g. "每周三下午三点",
+// "每两周的周一和周四", "工作日", "每月15号" or "every other Friday". The time of day
+// is ignored: it is the start time of the schedule. Weekly and monthly rules
+// without a weekday or day are left for Resolve to complete.
+func ParseRecurrencePhrase(phrase string) (*RecurrenceRule, error) {
+	text := strings.ToLower(strings.Join(strings.Fields(phrase), " "))
+	text = strings.NewReplacer(
+		"星期", "周", "礼拜", "周", "週", "周", "個", "个", "每一", "每",
+	).Replace(text)
+	if !strings.ContainsFunc(text, func(r rune) bool { return r >= 'a' && r <= 'z' }) {
+		// the spaces only matter to English phrases
+		text = strings.ReplaceAll(text, " ", "")
+	}
+	if !recurring.MatchString(text) {
+		// "周三" alone is a day, not a recurrence
+		return nil, ErrUnrecognizedRecurrence
+	}
+
+	rule := &RecurrenceRule{Interval: 1}
+	unit := RecurrenceType("")
+	setInterval := func(n int, t RecurrenceType) {
+		if n > 0 {
+			rule.Interval, unit = n, t
+		}
+	}
+	units := map[string]RecurrenceType{
+		"天": RecurrenceTypeDaily, "日": RecurrenceTypeDaily, "day": RecurrenceTypeDaily,
+		"周": RecurrenceTypeWeekly, "week": RecurrenceTypeWeekly,
+		"月": RecurrenceTypeMonthly, "month": RecurrenceTypeMonthly,
+	}
+
+	// Intervals
+	switch {
+	case strings.Contains(text, "隔周") || strings.Contains(text, "双周") ||
+		strings.Contains(text, "biweekly") || strings.Contains(text, "fortnight"):
+		setInterval(2, RecurrenceTypeWeekly)
+	case strings.Contains(text, "隔天") || strings.Contains(text, "隔日"):
+		setInterval(2, RecurrenceTypeDaily)
+	}
+	if m := zhInterval.FindStringSubmatch(text); m != nil {
+		n := chineseNumber(m[2])
+		if strings.Contains(m[1], "隔") && n > 0 {
+			// 每隔一周 is every other week
+			n++
+		}
+		setInterval(n, units[m[3]])
+	}
+	if m := enInterval.FindStringSubmatch(text); m != nil {
+		n := 2
+		if m[1] != "other" && m[1] != "second" {
+			n, _ = strconv.Atoi(m[1])
+		}
+		setInterval(n, units[m[2]])
+	}
+
+	// Weekdays
+	switch {
+	case strings.Contains(text, "工作日") || strings.Contains(text, "weekday") || strings.Contains(text, "workday"):
+		rule.Weekdays = []int{1, 2, 3, 4, 5}
+	case strings.Contains(text, "周末") || strings.Contains(text, "weekend"):
+		rule.Weekdays = []int{6, 7}
+	default:
+		if m := zhWeekRange.FindStringSubmatch(text); m != nil {
+			from, to := zhWeekdayIndex[[]rune(m[1])[0]], zhWeekdayIndex[[]rune(m[2])[0]]
+			for day := from; day <= to; day++ {
+				rule.Weekdays = append(rule.Weekdays, day)
+			}
+		}
+		if len(rule.Weekdays) == 0 {
+			for _, m := range zhWeekdays.FindAllStringSubmatch(text, -1) {
+				for _, r := range m[1] {
+					if day, ok := zhWeekdayIndex[r]; ok && !slices.Contains(rule.Weekdays, day) {
+						rule.Weekdays = append(rule.Weekdays, day)
+					}
+				}
+			}
+		}
+		for _, m := range enWeekday.FindAllStringSubmatch(text, -1) {
+			day := slices.Index([]string{"", "mon", "tue", "wed", "thu", "fri", "sat", "sun"}, m[1][:3])
+			if day > 0 && !slices.Contains(rule.Weekdays, day) {
+				rule.Weekdays = append(rule.Weekdays, day)
+			}
+		}
+	}
+
+	switch {
+	case len(rule.Weekdays) > 0:
+		rule.Type = RecurrenceTypeWeekly
+		switch {
+		case unit == "" && (strings.Contains(text, "every other") || strings.Contains(text, "every second")):
+			// every other Friday
+			rule.Interval = 2
+		case unit != "" && unit != RecurrenceTypeWeekly:
+			rule.Interval = 1
+		}
+	case unit != "":
+		rule.Type = unit
+	case strings.Contains(text, "每天") || strings.Contains(text, "每日") || strings.Contains(text, "天天") ||
+		strings.Contains(text, "daily") || strings.Contains(text, "every day") || strings.Contains(text, "everyday"):
+		rule.Type = RecurrenceTypeDaily
+	case strings.Contains(text, "每周") || strings.Contains(text, "weekly") || strings.Contains(text, "every week"):
+		rule.Type = RecurrenceTypeWeekly
+	case strings.Contains(text, "每月") || strings.Contains(text, "每个月") || strings.Contains(text, "monthly") ||
+		strings.Contains(text, "every month"):
+		rule.Type = RecurrenceTypeMonthly
+	default:
+		return nil, ErrUnrecognizedRecurrence
+	}
+
+	if rule.Type == RecurrenceTypeMonthly {
+		if m := zhMonthDay.FindStringSubmatch(text); m != nil {
+			rule.MonthDay = chineseNumber(m[1])
+		} else if m := enMonthDay.FindStringSubmatch(text); m != nil {
+			rule.MonthDay, _ = strconv.Atoi(m[1] + m[2])
+		}
+	}
+	return rule, nil
+}
+
+// chineseNumber parses a number up to 99 in digits or Chinese numerals, e.g.
+// "15", "两" or "二十一"; it returns 0 for others.
+func chineseNumber(s string) int {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	digits := map[rune]int{'一': 1, '二': 2, '两': 2, '三': 3, '四': 4, '五': 5, '六': 6, '七': 7, '八': 8, '九': 9}
+	runes := []rune(s)
+	switch {
+	case len(runes) == 1 && runes[0] == '十':
+		return 10
+	case len(runes) == 1:
+		return digits[runes[0]]
+	}
+	tens, rest, ok := strings.Cut(s, "十")
+	if !ok {
+		return 0
+	}
+	n := 10
+	if tens != "" {
+		n = chineseNumber(tens) * 10
+	}
+	if rest != "" {
+		n += chineseNumber(rest)
+	}
+	return n
+}
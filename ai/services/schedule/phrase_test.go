@@ -0,0 +1,55 @@
+package schedule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurrencePhrase(t *testing.T) {
+	tests := []struct {
+		phrase string
+		want   RecurrenceRule
+	}{
+		{"每周三下午三点", RecurrenceRule{Type: RecurrenceTypeWeekly, Interval: 1, Weekdays: []int{3}}},
+		{"每星期一三五早上9点", RecurrenceRule{Type: RecurrenceTypeWeekly, Interval: 1, Weekdays: []int{1, 3, 5}}},
+		{"每两周的周二和周四", RecurrenceRule{Type: RecurrenceTypeWeekly, Interval: 2, Weekdays: []int{2, 4}}},
+		{"隔周周日", RecurrenceRule{Type: RecurrenceTypeWeekly, Interval: 2, Weekdays: []int{7}}},
+		{"每周一到周五", RecurrenceRule{Type: RecurrenceTypeWeekly, Interval: 1, Weekdays: []int{1, 2, 3, 4, 5}}},
+		{"工作日早上", RecurrenceRule{Type: RecurrenceTypeWeekly, Interval: 1, Weekdays: []int{1, 2, 3, 4, 5}}},
+		{"每个周末", RecurrenceRule{Type: RecurrenceTypeWeekly, Interval: 1, Weekdays: []int{6, 7}}},
+		{"每天", RecurrenceRule{Type: RecurrenceTypeDaily, Interval: 1}},
+		{"每隔一天", RecurrenceRule{Type: RecurrenceTypeDaily, Interval: 2}},
+		{"每3天", RecurrenceRule{Type: RecurrenceTypeDaily, Interval: 3}},
+		{"每周", RecurrenceRule{Type: RecurrenceTypeWeekly, Interval: 1}},
+		{"每月十五号", RecurrenceRule{Type: RecurrenceTypeMonthly, Interval: 1, MonthDay: 15}},
+		{"每两个月的1日", RecurrenceRule{Type: RecurrenceTypeMonthly, Interval: 2, MonthDay: 1}},
+		{"every other Friday", RecurrenceRule{Type: RecurrenceTypeWeekly, Interval: 2, Weekdays: []int{5}}},
+		{"every Mon and Wed at 9am", RecurrenceRule{Type: RecurrenceTypeWeekly, Interval: 1, Weekdays: []int{1, 3}}},
+		{"weekdays", RecurrenceRule{Type: RecurrenceTypeWeekly, Interval: 1, Weekdays: []int{1, 2, 3, 4, 5}}},
+		{"daily", RecurrenceRule{Type: RecurrenceTypeDaily, Interval: 1}},
+		{"every 3 days", RecurrenceRule{Type: RecurrenceTypeDaily, Interval: 3}},
+		{"monthly on the 21st", RecurrenceRule{Type: RecurrenceTypeMonthly, Interval: 1, MonthDay: 21}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.phrase, func(t *testing.T) {
+			rule, err := ParseRecurrencePhrase(tt.phrase)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *rule)
+		})
+	}
+}
+
+func TestParseRecurrencePhrase_Unrecognized(t *testing.T) {
+	for _, phrase := range []string{"", "明天下午三点", "这周三", "next Monday"} {
+		_, err := ParseRecurrencePhrase(phrase)
+		assert.ErrorIs(t, err, ErrUnrecognizedRecurrence, phrase)
+	}
+}
+
+func TestChineseNumber(t *testing.T) {
+	for s, want := range map[string]int{"7": 7, "两": 2, "十": 10, "十五": 15, "二十": 20, "三十一": 31, "周": 0} {
+		assert.Equal(t, want, chineseNumber(s), s)
+	}
+}
@@ -36,12 +36,16 @@ func (rt RecurrenceType) String() string {
 }
 
 // RecurrenceRule represents a simplified recurrence rule.
-// We use a custom JSON format instead of full RFC 5545 RRULE for simplicity.
+// We use a custom JSON format instead of full RFC 5545 RRULE for simplicity;
+// ParseRRule and RRule convert from and to the RRULEs it can represent.
 type RecurrenceRule struct {
 	Type     RecurrenceType `json:"type"`
 	Weekdays []int          `json:"weekdays"`
 	Interval int            `json:"interval"`
 	MonthDay int            `json:"month_day"`
+	// Exceptions are the days of the skipped occurrences, YYYY-MM-DD in the
+	// time zone of the schedule.
+	Exceptions []string `json:"exceptions,omitempty"`
 }
 
 // Validate checks if the recurrence rule is valid.
@@ -69,6 +73,15 @@ func (r *RecurrenceRule) Validate() error {
 		}
 	}
 
+	if len(r.Exceptions) > MaxExceptions {
+		return fmt.Errorf("too many exceptions: %d (at most %d)", len(r.Exceptions), MaxExceptions)
+	}
+	for _, day := range r.Exceptions {
+		if _, err := time.Parse(time.DateOnly, day); err != nil {
+			return fmt.Errorf("invalid exception: %q (must be YYYY-MM-DD)", day)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,358 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxExceptions bounds the skipped occurrences of a recurring schedule.
+	MaxExceptions = 500
+
+	// maxExpandSteps bounds the days, weeks or months Expand walks through.
+	maxExpandSteps = 4000
+)
+
+// ErrUnsupportedRecurrence is returned for recurrences that rules cannot
+// represent, e.g. yearly ones or RRULEs with BYSETPOS.
+var ErrUnsupportedRecurrence = errors.New("unsupported recurrence")
+
+// rruleWeekdays maps the weekdays of recurrence rules, 1 (Monday) to 7
+// (Sunday), to RRULE.
+var rruleWeekdays = []string{"", "MO", "TU", "WE", "TH", "FR", "SA", "SU"}
+
+// weekdayNames are the English names of the weekdays, 1 (Monday) to 7.
+var weekdayNames = []string{"", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+// isoWeekday returns the weekday of t, 1 (Monday) to 7 (Sunday).
+func isoWeekday(t time.Time) int {
+	return (int(t.Weekday())+6)%7 + 1
+}
+
+// Resolve completes a rule with its defaults for a schedule starting at
+// start: an interval of 1, the weekday of start for weekly rules and the day
+// of start for monthly ones.
+func (r *RecurrenceRule) Resolve(start time.Time) {
+	if r.Interval <= 0 {
+		r.Interval = 1
+	}
+	switch r.Type {
+	case RecurrenceTypeWeekly:
+		if len(r.Weekdays) == 0 {
+			r.Weekdays = []int{isoWeekday(start)}
+		}
+		slices.Sort(r.Weekdays)
+		r.Weekdays = slices.Compact(r.Weekdays)
+	case RecurrenceTypeMonthly:
+		if r.MonthDay == 0 {
+			r.MonthDay = start.Day()
+		}
+	}
+}
+
+// Expand returns the occurrences of a schedule starting at start within
+// [from, to], at most limit of them. The occurrences are in the location of
+// start and keep its wall clock time across DST changes; the skipped ones
+// are left out. until, when non-zero, is the recurrence end: no occurrence
+// starts after it.
+func (r *RecurrenceRule) Expand(start, from, to time.Time, until int64, limit int) []time.Time {
+	end := to
+	if until > 0 && time.Unix(until, 0).Before(end) {
+		end = time.Unix(until, 0)
+	}
+	if limit <= 0 || end.Before(start) || end.Before(from) {
+		return nil
+	}
+	loc := start.Location()
+	interval := max(r.Interval, 1)
+	hour, minute, second := start.Clock()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, minute, second, 0, loc)
+	}
+	// skip is the number of periods before from, less one for DST shifts.
+	skip := func(period time.Duration) int {
+		if !from.After(start) {
+			return 0
+		}
+		return max(int(from.Sub(start)/period)/interval-1, 0)
+	}
+
+	var occurrences []time.Time
+	// add collects an occurrence; it returns false once the expansion ends.
+	add := func(t time.Time) bool {
+		if t.After(end) {
+			return false
+		}
+		if !t.Before(start) && !t.Before(from) && !slices.Contains(r.Exceptions, t.Format(time.DateOnly)) {
+			occurrences = append(occurrences, t)
+		}
+		return len(occurrences) < limit
+	}
+
+	switch r.Type {
+	case RecurrenceTypeDaily:
+		for i := skip(24 * time.Hour); i < maxExpandSteps; i++ {
+			if !add(at(start.Year(), start.Month(), start.Day()+i*interval)) {
+				break
+			}
+		}
+	case RecurrenceTypeWeekly:
+		weekdays := slices.Sorted(slices.Values(r.Weekdays))
+		if len(weekdays) == 0 {
+			weekdays = []int{isoWeekday(start)}
+		}
+		monday := start.Day() - isoWeekday(start) + 1
+	weeks:
+		for i := skip(7 * 24 * time.Hour); i < maxExpandSteps; i++ {
+			for _, weekday := range weekdays {
+				if !add(at(start.Year(), start.Month(), monday+i*7*interval+weekday-1)) {
+					break weeks
+				}
+			}
+		}
+	case RecurrenceTypeMonthly:
+		day := r.MonthDay
+		if day == 0 {
+			day = start.Day()
+		}
+		for i := skip(31 * 24 * time.Hour); i < maxExpandSteps; i++ {
+			first := time.Date(start.Year(), start.Month()+time.Month(i*interval), 1, 0, 0, 0, 0, loc)
+			if !add(at(first.Year(), first.Month(), min(day, getLastDayOfMonth(first.Year(), first.Month())))) {
+				break
+			}
+		}
+	}
+	return occurrences
+}
+
+// Anchor returns the first occurrence of a schedule starting at start, which
+// is start itself when it matches the rule, or the zero time when none.
+func (r *RecurrenceRule) Anchor(start time.Time) time.Time {
+	skipped := r.Exceptions
+	r.Exceptions = nil
+	defer func() { r.Exceptions = skipped }()
+	occurrences := r.Expand(start, start, start.AddDate(1, 1, 0), 0, 1)
+	if len(occurrences) == 0 {
+		return time.Time{}
+	}
+	return occurrences[0]
+}
+
+// Skip adds the day of an occurrence to the exceptions.
+func (r *RecurrenceRule) Skip(day string) error {
+	if _, err := time.Parse(time.DateOnly, day); err != nil {
+		return fmt.Errorf("invalid day: %q (must be YYYY-MM-DD)", day)
+	}
+	if slices.Contains(r.Exceptions, day) {
+		return nil
+	}
+	if len(r.Exceptions) >= MaxExceptions {
+		return fmt.Errorf("too many exceptions (at most %d)", MaxExceptions)
+	}
+	r.Exceptions = append(r.Exceptions, day)
+	slices.Sort(r.Exceptions)
+	return nil
+}
+
+// Describe returns the rule for people, e.g. "every 2 weeks on Monday,
+// Wednesday".
+func (r *RecurrenceRule) Describe() string {
+	interval := max(r.Interval, 1)
+	var s string
+	switch r.Type {
+	case RecurrenceTypeDaily:
+		s = "every day"
+		if interval > 1 {
+			s = fmt.Sprintf("every %d days", interval)
+		}
+	case RecurrenceTypeWeekly:
+		names := make([]string, 0, len(r.Weekdays))
+		for _, day := range r.Weekdays {
+			if day >= 1 && day <= 7 {
+				names = append(names, weekdayNames[day])
+			}
+		}
+		switch {
+		case interval == 1 && slices.Equal(r.Weekdays, []int{1, 2, 3, 4, 5}):
+			s = "every weekday"
+		case interval == 1:
+			s = "every " + strings.Join(names, ", ")
+		default:
+			s = fmt.Sprintf("every %d weeks on %s", interval, strings.Join(names, ", "))
+		}
+	case RecurrenceTypeMonthly:
+		s = fmt.Sprintf("monthly on day %d", r.MonthDay)
+		if interval > 1 {
+			s = fmt.Sprintf("every %d months on day %d", interval, r.MonthDay)
+		}
+	default:
+		return string(r.Type)
+	}
+	if n := len(r.Exceptions); n > 0 {
+		shown := r.Exceptions[:min(n, 3)]
+		s += ", skipping " + strings.Join(shown, ", ")
+		if n > len(shown) {
+			s += fmt.Sprintf(" and %d more", n-len(shown))
+		}
+	}
+	return s
+}
+
+// RRule returns the RRULE of the rule, without its exceptions (EXDATE);
+// until, when non-zero, is the recurrence end.
+func (r *RecurrenceRule) RRule(until int64) string {
+	parts := []string{}
+	switch r.Type {
+	case RecurrenceTypeDaily:
+		parts = append(parts, "FREQ=DAILY")
+	case RecurrenceTypeWeekly:
+		days := make([]string, 0, len(r.Weekdays))
+		for _, day := range r.Weekdays {
+			days = append(days, rruleWeekdays[day])
+		}
+		parts = append(parts, "FREQ=WEEKLY", "BYDAY="+strings.Join(days, ","))
+	case RecurrenceTypeMonthly:
+		parts = append(parts, "FREQ=MONTHLY", "BYMONTHDAY="+strconv.Itoa(r.MonthDay))
+	}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if until > 0 {
+		parts = append(parts, "UNTIL="+time.Unix(until, 0).UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// ParseRRule parses the RRULE of a schedule starting at start, e.g.
+// "FREQ=WEEKLY;BYDAY=WE", with or without its "RRULE:" prefix. It returns the
+// resolved rule and the recurrence end, zero without UNTIL or COUNT.
+func ParseRRule(rrule string, start time.Time) (*RecurrenceRule, int64, error) {
+	rule := &RecurrenceRule{Interval: 1}
+	var until int64
+	count := 0
+	rrule = strings.TrimPrefix(strings.TrimSpace(rrule), "RRULE:")
+	for _, part := range strings.Split(rrule, ";") {
+		key, value, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(strings.TrimSpace(key)) {
+		case "FREQ":
+			switch strings.ToUpper(value) {
+			case "DAILY":
+				rule.Type = RecurrenceTypeDaily
+			case "WEEKLY":
+				rule.Type = RecurrenceTypeWeekly
+			case "MONTHLY":
+				rule.Type = RecurrenceTypeMonthly
+			default:
+				return nil, 0, fmt.Errorf("%w: FREQ=%s", ErrUnsupportedRecurrence, value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, 0, fmt.Errorf("invalid INTERVAL: %q", value)
+			}
+			rule.Interval = n
+		case "BYDAY":
+			for _, day := range strings.Split(strings.ToUpper(value), ",") {
+				index := slices.Index(rruleWeekdays, day)
+				if index < 1 {
+					// positions like 1MO are not supported
+					return nil, 0, fmt.Errorf("%w: BYDAY=%s", ErrUnsupportedRecurrence, value)
+				}
+				rule.Weekdays = append(rule.Weekdays, index)
+			}
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, 0, fmt.Errorf("%w: BYMONTHDAY=%s", ErrUnsupportedRecurrence, value)
+			}
+			rule.MonthDay = n
+		case "UNTIL":
+			t, err := parseRRuleTime(value, start.Location())
+			if err != nil {
+				return nil, 0, fmt.Errorf("invalid UNTIL: %q", value)
+			}
+			until = t.Unix()
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, 0, fmt.Errorf("invalid COUNT: %q", value)
+			}
+			count = n
+		case "WKST", "":
+		default:
+			// BYMONTH, BYSETPOS...
+			return nil, 0, fmt.Errorf("%w: %s", ErrUnsupportedRecurrence, key)
+		}
+	}
+	if rule.Type == "" {
+		return nil, 0, fmt.Errorf("invalid RRULE: FREQ is required")
+	}
+	if rule.Type == RecurrenceTypeDaily && len(rule.Weekdays) > 0 {
+		return nil, 0, fmt.Errorf("%w: daily rule with BYDAY", ErrUnsupportedRecurrence)
+	}
+	rule.Resolve(start)
+	if err := rule.Validate(); err != nil {
+		return nil, 0, err
+	}
+	if count > 0 {
+		// COUNT becomes the start of the last occurrence
+		occurrences := rule.Expand(start, start, start.AddDate(maxExpandSteps/12, 0, 0), until, count)
+		if len(occurrences) > 0 {
+			until = occurrences[len(occurrences)-1].Unix()
+		}
+	}
+	return rule, until, nil
+}
+
+// parseRRuleTime parses the UNTIL of an RRULE, a UTC or local DATE-TIME or a
+// DATE, the latter ending with its day.
+func parseRRuleTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("20060102", value, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.AddDate(0, 0, 1).Add(-time.Second), nil
+}
+
+// ParseRecurrenceText parses a recurrence given as the JSON rule of
+// schedules, an RRULE like "FREQ=WEEKLY;BYDAY=WE" or a phrase like "每周三"
+// or "every other Monday", for a schedule starting at start. It returns the
+// resolved and validated rule, and the recurrence end given by the RRULE,
+// zero otherwise.
+func ParseRecurrenceText(text string, start time.Time) (*RecurrenceRule, int64, error) {
+	text = strings.TrimSpace(text)
+	upper := strings.ToUpper(text)
+	switch {
+	case strings.HasPrefix(text, "{"):
+		rule, err := ParseRecurrenceRuleFromJSON(text)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid recurrence rule: %w", err)
+		}
+		rule.Resolve(start)
+		if err := rule.Validate(); err != nil {
+			return nil, 0, err
+		}
+		return rule, 0, nil
+	case strings.HasPrefix(upper, "FREQ=") || strings.HasPrefix(upper, "RRULE:"):
+		return ParseRRule(text, start)
+	}
+	rule, err := ParseRecurrencePhrase(text)
+	if err != nil {
+		return nil, 0, err
+	}
+	rule.Resolve(start)
+	if err := rule.Validate(); err != nil {
+		return nil, 0, err
+	}
+	return rule, 0, nil
+}
@@ -0,0 +1,144 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand_WeeklyMultipleWeekdays(t *testing.T) {
+	// Monday 2024-01-01 09:00
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	rule := &RecurrenceRule{Type: RecurrenceTypeWeekly, Interval: 1, Weekdays: []int{1, 3, 5}}
+
+	occurrences := rule.Expand(start, start, start.AddDate(0, 0, 13), 0, 100)
+
+	var days []int
+	for _, o := range occurrences {
+		days = append(days, o.Day())
+		assert.Equal(t, 9, o.Hour())
+	}
+	assert.Equal(t, []int{1, 3, 5, 8, 10, 12}, days)
+}
+
+func TestExpand_KeepsWallClockAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	// DST starts on 2024-03-10
+	start := time.Date(2024, 3, 6, 15, 0, 0, 0, loc)
+	rule := &RecurrenceRule{Type: RecurrenceTypeWeekly, Interval: 1, Weekdays: []int{3}}
+
+	occurrences := rule.Expand(start, start, start.AddDate(0, 0, 14), 0, 10)
+
+	require.Len(t, occurrences, 3)
+	for _, o := range occurrences {
+		assert.Equal(t, 15, o.Hour())
+		assert.Equal(t, time.Wednesday, o.Weekday())
+	}
+}
+
+func TestExpand_WindowUntilAndExceptions(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	rule := &RecurrenceRule{Type: RecurrenceTypeDaily, Interval: 1, Exceptions: []string{"2024-03-02"}}
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC).Unix()
+	occurrences := rule.Expand(start, from, from.AddDate(0, 0, 30), until, 100)
+
+	var days []string
+	for _, o := range occurrences {
+		days = append(days, o.Format(time.DateOnly))
+	}
+	assert.Equal(t, []string{"2024-03-01", "2024-03-03", "2024-03-04"}, days)
+}
+
+func TestExpand_MonthlyClampsToLastDay(t *testing.T) {
+	start := time.Date(2024, 1, 31, 8, 0, 0, 0, time.UTC)
+	rule := &RecurrenceRule{Type: RecurrenceTypeMonthly, Interval: 1, MonthDay: 31}
+
+	occurrences := rule.Expand(start, start, time.Date(2024, 4, 30, 23, 0, 0, 0, time.UTC), 0, 10)
+
+	require.Len(t, occurrences, 4)
+	assert.Equal(t, 29, occurrences[1].Day())
+	assert.Equal(t, 30, occurrences[3].Day())
+}
+
+func TestAnchor(t *testing.T) {
+	// Friday 2024-01-05 15:00
+	start := time.Date(2024, 1, 5, 15, 0, 0, 0, time.UTC)
+	rule := &RecurrenceRule{Type: RecurrenceTypeWeekly, Interval: 1, Weekdays: []int{3}, Exceptions: []string{"2024-01-10"}}
+
+	assert.Equal(t, time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC), rule.Anchor(start))
+	assert.Equal(t, []string{"2024-01-10"}, rule.Exceptions)
+}
+
+func TestSkipAndDescribe(t *testing.T) {
+	rule := &RecurrenceRule{Type: RecurrenceTypeWeekly, Interval: 2, Weekdays: []int{1, 3}}
+	require.NoError(t, rule.Skip("2024-01-10"))
+	require.NoError(t, rule.Skip("2024-01-08"))
+	require.NoError(t, rule.Skip("2024-01-08"))
+	assert.Error(t, rule.Skip("January 8"))
+
+	assert.Equal(t, []string{"2024-01-08", "2024-01-10"}, rule.Exceptions)
+	assert.Equal(t, "every 2 weeks on Monday, Wednesday, skipping 2024-01-08, 2024-01-10", rule.Describe())
+	assert.Equal(t, "every weekday", (&RecurrenceRule{Type: RecurrenceTypeWeekly, Interval: 1, Weekdays: []int{1, 2, 3, 4, 5}}).Describe())
+}
+
+func TestParseRRule_RoundTrip(t *testing.T) {
+	start := time.Date(2024, 1, 3, 15, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 6, 26, 15, 0, 0, 0, time.UTC).Unix()
+	rule := &RecurrenceRule{Type: RecurrenceTypeWeekly, Interval: 2, Weekdays: []int{3, 5}}
+
+	rrule := rule.RRule(until)
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=WE,FR;INTERVAL=2;UNTIL=20240626T150000Z", rrule)
+
+	parsed, parsedUntil, err := ParseRRule("RRULE:"+rrule, start)
+	require.NoError(t, err)
+	assert.Equal(t, rule, parsed)
+	assert.Equal(t, until, parsedUntil)
+}
+
+func TestParseRRule_Count(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	rule, until, err := ParseRRule("FREQ=DAILY;COUNT=3", start)
+	require.NoError(t, err)
+	assert.Equal(t, RecurrenceTypeDaily, rule.Type)
+	assert.Equal(t, start.AddDate(0, 0, 2).Unix(), until)
+}
+
+func TestParseRRule_Unsupported(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	for _, rrule := range []string{"FREQ=YEARLY", "FREQ=MONTHLY;BYDAY=1MO", "FREQ=MONTHLY;BYSETPOS=-1"} {
+		_, _, err := ParseRRule(rrule, start)
+		assert.ErrorIs(t, err, ErrUnsupportedRecurrence, rrule)
+	}
+	_, _, err := ParseRRule("INTERVAL=2", start)
+	assert.Error(t, err)
+}
+
+func TestParseRecurrenceText(t *testing.T) {
+	// Friday 2024-01-05
+	start := time.Date(2024, 1, 5, 15, 0, 0, 0, time.UTC)
+
+	rule, _, err := ParseRecurrenceText("每周三下午三点", start)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, rule.Weekdays)
+
+	rule, _, err = ParseRecurrenceText(`{"type":"weekly"}`, start)
+	require.NoError(t, err)
+	assert.Equal(t, []int{5}, rule.Weekdays)
+
+	rule, _, err = ParseRecurrenceText("每月", start)
+	require.NoError(t, err)
+	assert.Equal(t, 5, rule.MonthDay)
+
+	_, until, err := ParseRecurrenceText("FREQ=DAILY;UNTIL=20240110", start)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 10, 23, 59, 59, 0, time.UTC).Unix(), until)
+
+	_, _, err = ParseRecurrenceText("下午三点", start)
+	assert.ErrorIs(t, err, ErrUnrecognizedRecurrence)
+}
@@ -1,4 +1,4 @@
-// Package stats provides cost alerting for agent sessions.
+// Package stats provides cost alerting and usage quotas for agent sessions.
 package stats
 
 import (
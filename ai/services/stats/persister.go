@@ -27,6 +27,7 @@ type Persister struct {
 	once         sync.Once
 	seenSessions sync.Map // map[string]int64 - session ID -> last enqueued timestamp
 	dedupEnabled atomic.Bool
+	dropped      atomic.Int64 // records dropped on a full queue
 }
 
 // NewPersister creates a new async persister.
@@ -78,6 +79,7 @@ func (p *Persister) Enqueue(stats *agent.AgentSessionStatsForStorage) bool {
 			"queue_size", len(p.queue))
 		return true
 	default:
+		p.dropped.Add(1)
 		p.logger.Warn("Persister: queue full, dropping stats record",
 			"session_id", stats.SessionID,
 			"queue_size", len(p.queue))
@@ -228,3 +230,15 @@ func (p *Persister) Close(timeout time.Duration) error {
 func (p *Persister) QueueSize() int {
 	return len(p.queue)
 }
+
+// Dropped returns the number of records dropped on a full queue; duplicates
+// are not counted.
+func (p *Persister) Dropped() int64 {
+	return p.dropped.Load()
+}
+
+// QueueCapacity returns the maximum queue size.
+// QueueCapacity 返回队列容量。
+func (p *Persister) QueueCapacity() int {
+	return cap(p.queue)
+}
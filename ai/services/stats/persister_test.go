@@ -2,6 +2,7 @@ package stats
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -72,6 +73,32 @@ func TestPersister_QueueFull(t *testing.T) {
 	}
 }
 
+func TestPersister_Dropped(t *testing.T) {
+	mockStore := &mockAgentStatsStore{slow: true}
+	p := NewPersister(mockStore, 1, nil)
+
+	// Distinct sessions fill the queue while the first record is saved
+	enqueued := 0
+	for i := 0; i < 5; i++ {
+		if p.Enqueue(&agent.AgentSessionStatsForStorage{SessionID: fmt.Sprintf("session-%d", i)}) {
+			enqueued++
+		}
+	}
+	// Duplicates are not drops
+	p.Enqueue(&agent.AgentSessionStatsForStorage{SessionID: "session-0"})
+
+	if got := p.Dropped(); got != int64(5-enqueued) {
+		t.Errorf("expected %d dropped records, got %d", 5-enqueued, got)
+	}
+	if p.Dropped() == 0 {
+		t.Error("expected records to be dropped on a full queue")
+	}
+
+	if err := p.Close(5 * time.Second); err != nil {
+		t.Fatalf("failed to close persister: %v", err)
+	}
+}
+
 func TestPersister_EnqueueSessionStatsData(t *testing.T) {
 	mockStore := &mockAgentStatsStore{}
 	p := NewPersister(mockStore, 10, nil)
@@ -178,6 +205,10 @@ func (m *mockAgentStatsStore) GetCostStats(ctx context.Context, userID int32, da
 	return nil, nil
 }
 
+func (m *mockAgentStatsStore) GetUsageTotals(ctx context.Context, userID int32, start, end time.Time) (*store.UsageTotals, error) {
+	return &store.UsageTotals{}, nil
+}
+
 func (m *mockAgentStatsStore) GetUserCostSettings(ctx context.Context, userID int32) (*store.UserCostSettings, error) {
 	return nil, nil
 }
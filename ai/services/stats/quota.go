@@ -0,0 +1,114 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hrygo/divinesense/store"
+)
+
+// ErrQuotaExceeded is returned by QuotaService.Check when the user used up a
+// monthly quota.
+var ErrQuotaExceeded = errors.New("monthly AI quota exceeded")
+
+// QuotaConfig holds the monthly quotas of each user; 0 means unlimited.
+// QuotaConfig 保存每个用户的月度配额；0 表示不限制。
+type QuotaConfig struct {
+	MonthlyTokens  int64
+	MonthlyCostUSD float64
+}
+
+// Enabled reports whether any quota is set.
+func (c QuotaConfig) Enabled() bool {
+	return c.MonthlyTokens > 0 || c.MonthlyCostUSD > 0
+}
+
+// Usage is a user's AI usage of a calendar month (UTC) against the quotas.
+// Usage 表示用户在一个自然月 (UTC) 内的 AI 用量及配额。
+type Usage struct {
+	PeriodStart  time.Time
+	PeriodEnd    time.Time
+	TotalTokens  int64
+	TotalCostUSD float64
+	SessionCount int64 // Agent sessions and normal mode chat turns
+	Quota        QuotaConfig
+}
+
+// Exceeded reports whether the usage reached a quota.
+func (u *Usage) Exceeded() bool {
+	return (u.Quota.MonthlyTokens > 0 && u.TotalTokens >= u.Quota.MonthlyTokens) ||
+		(u.Quota.MonthlyCostUSD > 0 && u.TotalCostUSD >= u.Quota.MonthlyCostUSD)
+}
+
+// QuotaService aggregates the monthly usage of users and enforces the quotas.
+// QuotaService 汇总用户的月度用量并执行配额限制。
+type QuotaService struct {
+	store  store.AgentStatsStore
+	config QuotaConfig
+	price  func(inputTokens, outputTokens int) float64
+	now    func() time.Time
+	logger *slog.Logger
+}
+
+// NewQuotaService creates a quota service. price estimates the cost of normal
+// mode chat tokens, which are stored without one.
+func NewQuotaService(store store.AgentStatsStore, config QuotaConfig, price func(inputTokens, outputTokens int) float64, logger *slog.Logger) *QuotaService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &QuotaService{
+		store:  store,
+		config: config,
+		price:  price,
+		now:    time.Now,
+		logger: logger,
+	}
+}
+
+// Usage returns the user's usage of the current month.
+// Usage 返回用户当月的用量。
+func (s *QuotaService) Usage(ctx context.Context, userID int32) (*Usage, error) {
+	now := s.now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	totals, err := s.store.GetUsageTotals(ctx, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	chatTokens := totals.ChatInputTokens + totals.ChatOutputTokens
+	return &Usage{
+		PeriodStart:  start,
+		PeriodEnd:    end,
+		TotalTokens:  totals.SessionTokens + chatTokens,
+		TotalCostUSD: totals.SessionCostUSD + s.price(int(totals.ChatInputTokens), int(totals.ChatOutputTokens)),
+		SessionCount: totals.SessionCount + totals.ChatCount,
+		Quota:        s.config,
+	}, nil
+}
+
+// Check returns an error wrapping ErrQuotaExceeded if the user used up a
+// monthly quota. Usage that cannot be read does not block the user.
+// Check 在用户用完月度配额时返回包装 ErrQuotaExceeded 的错误。
+func (s *QuotaService) Check(ctx context.Context, userID int32) error {
+	if s == nil || !s.config.Enabled() {
+		return nil
+	}
+
+	usage, err := s.Usage(ctx, userID)
+	if err != nil {
+		s.logger.Warn("Quota: failed to get usage",
+			"user_id", userID,
+			"error", err)
+		return nil
+	}
+
+	if usage.Exceeded() {
+		return fmt.Errorf("%w: %d tokens and $%.4f used since %s",
+			ErrQuotaExceeded, usage.TotalTokens, usage.TotalCostUSD, usage.PeriodStart.Format("2006-01-02"))
+	}
+	return nil
+}
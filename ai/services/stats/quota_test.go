@@ -0,0 +1,85 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/store"
+)
+
+// usageStore returns fixed usage totals and records the queried period.
+type usageStore struct {
+	store.AgentStatsStore
+	totals     *store.UsageTotals
+	err        error
+	start, end time.Time
+}
+
+func (s *usageStore) GetUsageTotals(_ context.Context, _ int32, start, end time.Time) (*store.UsageTotals, error) {
+	s.start, s.end = start, end
+	return s.totals, s.err
+}
+
+// centPerToken prices every token at one cent.
+func centPerToken(inputTokens, outputTokens int) float64 {
+	return float64(inputTokens+outputTokens) / 100
+}
+
+func newTestQuotaService(s *usageStore, config QuotaConfig) *QuotaService {
+	service := NewQuotaService(s, config, centPerToken, nil)
+	service.now = func() time.Time { return time.Date(2026, 3, 31, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*3600)) }
+	return service
+}
+
+func TestQuotaService_Usage(t *testing.T) {
+	s := &usageStore{totals: &store.UsageTotals{
+		SessionCount:     2,
+		SessionTokens:    1000,
+		SessionCostUSD:   1.5,
+		ChatCount:        3,
+		ChatInputTokens:  60,
+		ChatOutputTokens: 40,
+	}}
+	service := newTestQuotaService(s, QuotaConfig{MonthlyTokens: 5000})
+
+	usage, err := service.Usage(context.Background(), 1)
+	require.NoError(t, err)
+	// Periods are UTC calendar months
+	assert.Equal(t, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), usage.PeriodStart)
+	assert.Equal(t, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), usage.PeriodEnd)
+	assert.Equal(t, usage.PeriodStart, s.start)
+	assert.Equal(t, usage.PeriodEnd, s.end)
+	assert.Equal(t, int64(1100), usage.TotalTokens)
+	assert.InDelta(t, 2.5, usage.TotalCostUSD, 0.0001)
+	assert.Equal(t, int64(5), usage.SessionCount)
+	assert.Equal(t, int64(5000), usage.Quota.MonthlyTokens)
+	assert.False(t, usage.Exceeded())
+}
+
+func TestQuotaService_Check(t *testing.T) {
+	ctx := context.Background()
+	s := &usageStore{totals: &store.UsageTotals{SessionTokens: 900, SessionCostUSD: 0.9, ChatInputTokens: 100}}
+
+	// No quota, no limit
+	require.NoError(t, newTestQuotaService(s, QuotaConfig{}).Check(ctx, 1))
+	var nilService *QuotaService
+	require.NoError(t, nilService.Check(ctx, 1))
+
+	require.NoError(t, newTestQuotaService(s, QuotaConfig{MonthlyTokens: 1001}).Check(ctx, 1))
+	err := newTestQuotaService(s, QuotaConfig{MonthlyTokens: 1000}).Check(ctx, 1)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+
+	// 0.9 USD of sessions and 1 USD of chat tokens
+	require.NoError(t, newTestQuotaService(s, QuotaConfig{MonthlyCostUSD: 2}).Check(ctx, 1))
+	err = newTestQuotaService(s, QuotaConfig{MonthlyTokens: 5000, MonthlyCostUSD: 1.5}).Check(ctx, 1)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+
+	// Usage that cannot be read does not block chats
+	s.err = errors.New("db down")
+	require.NoError(t, newTestQuotaService(s, QuotaConfig{MonthlyTokens: 1}).Check(ctx, 1))
+}
@@ -0,0 +1,10 @@
+// Package divinesense embeds the project files needed at runtime from the
+// root of the repository.
+package divinesense
+
+import _ "embed"
+
+// Changelog is CHANGELOG.md, the release notes shown in the product.
+//
+//go:embed CHANGELOG.md
+var Changelog string
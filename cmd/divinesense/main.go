@@ -1,22 +1,43 @@
 package main
 
 import (
+	"archive/zip"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/hrygo/divinesense/ai"
+	agent "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/ai/agents/geek"
+	"github.com/hrygo/divinesense/ai/agents/orchestrator"
+	"github.com/hrygo/divinesense/ai/agents/universal"
+	"github.com/hrygo/divinesense/ai/routing"
 	"github.com/hrygo/divinesense/internal/profile"
 	"github.com/hrygo/divinesense/internal/version"
+	"github.com/hrygo/divinesense/plugin/embedindex"
+	"github.com/hrygo/divinesense/plugin/fewshot"
+	"github.com/hrygo/divinesense/plugin/markdown"
+	"github.com/hrygo/divinesense/plugin/memoexport"
+	"github.com/hrygo/divinesense/plugin/memoimport"
+	"github.com/hrygo/divinesense/plugin/vectorindex"
 	"github.com/hrygo/divinesense/server"
+	apiv1 "github.com/hrygo/divinesense/server/router/api/v1"
+	"github.com/hrygo/divinesense/server/runner/embedding"
 	"github.com/hrygo/divinesense/store"
 	"github.com/hrygo/divinesense/store/db"
 )
@@ -62,19 +83,8 @@ var (
 			// Setup logger with configured log level
 			setupLogger(viper.GetString("log-level"))
 
-			instanceProfile := &profile.Profile{
-				Mode:        viper.GetString("mode"),
-				Addr:        viper.GetString("addr"),
-				Port:        viper.GetInt("port"),
-				UNIXSock:    viper.GetString("unix-sock"),
-				Data:        viper.GetString("data"),
-				Driver:      viper.GetString("driver"),
-				DSN:         viper.GetString("dsn"),
-				InstanceURL: viper.GetString("instance-url"),
-				Version:     version.GetCurrentVersion(viper.GetString("mode")),
-			}
-			instanceProfile.FromEnv()
-			if err := instanceProfile.Validate(); err != nil {
+			instanceProfile, err := newInstanceProfile()
+			if err != nil {
 				panic(err)
 			}
 
@@ -88,6 +98,29 @@ var (
 			}
 
 			storeInstance := store.New(dbDriver, instanceProfile)
+			if viper.GetBool("check-upgrade") {
+				ok, err := checkUpgrade(ctx, os.Stdout, storeInstance)
+				cancel()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "\n❌ Upgrade check failed: %v\n", err)
+				}
+				if err != nil || !ok {
+					os.Exit(1)
+				}
+				return
+			}
+
+			if viper.GetBool("skip-preflight") {
+				slog.Warn("preflight checks skipped")
+			} else if err := preflight(ctx, instanceProfile, dbDriver.GetDB()); err != nil {
+				cancel()
+				fmt.Fprintf(os.Stderr, "\n❌ Preflight check failed: %v\n", err)
+				fmt.Fprintln(os.Stderr, "   Fix the configuration, or start with --skip-preflight to bypass the checks.")
+				slog.Error("preflight check failed", "error", err)
+				return
+			}
+
+			logUpgradePlan(ctx, storeInstance)
 			if err := storeInstance.Migrate(ctx); err != nil {
 				cancel()
 				slog.Error("failed to migrate", "error", err)
@@ -128,6 +161,27 @@ var (
 	}
 )
 
+// newInstanceProfile builds the instance profile from flags and environment.
+func newInstanceProfile() (*profile.Profile, error) {
+	instanceProfile := &profile.Profile{
+		Mode:        viper.GetString("mode"),
+		Addr:        viper.GetString("addr"),
+		Port:        viper.GetInt("port"),
+		UNIXSock:    viper.GetString("unix-sock"),
+		Data:        viper.GetString("data"),
+		Driver:      viper.GetString("driver"),
+		DSN:         viper.GetString("dsn"),
+		InstanceURL: viper.GetString("instance-url"),
+		Version:     version.GetCurrentVersion(viper.GetString("mode")),
+		SafeMode:    viper.GetBool("safe-mode"),
+	}
+	instanceProfile.FromEnv()
+	if err := instanceProfile.Validate(); err != nil {
+		return nil, err
+	}
+	return instanceProfile, nil
+}
+
 func init() {
 	viper.SetDefault("mode", "dev")
 	viper.SetDefault("driver", "postgres")
@@ -148,6 +202,151 @@ func init() {
 	}
 	rootCmd.AddCommand(versionCmd)
 
+	secureWorkspacesCmd := &cobra.Command{
+		Use:   "secure-workspaces",
+		Short: "Restrict Geek Mode workspaces and CLI transcripts to the server user",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			changed, err := geek.SecureWorkspaces()
+			for _, dir := range changed {
+				fmt.Printf("restricted %s to %s\n", dir, geek.WorkspaceDirPerm)
+			}
+			if err != nil {
+				return err
+			}
+			if len(changed) == 0 {
+				fmt.Println("all workspaces are already restricted")
+			}
+			return nil
+		},
+	}
+	rootCmd.AddCommand(secureWorkspacesCmd)
+
+	evalExamplesCmd := &cobra.Command{
+		Use:   "eval-examples",
+		Short: "Report how the few-shot examples change routing accuracy on their holdout set",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			setupLogger(viper.GetString("log-level"))
+			return evalExamples(cmd.Context(), os.Stdout)
+		},
+	}
+	rootCmd.AddCommand(evalExamplesCmd)
+
+	reembedCmd := &cobra.Command{
+		Use:   "reembed",
+		Short: "Re-embed the memos with another embedding model and swap it in once its recall is verified",
+		Long: `Re-embed the memos into a shadow index of --model while the active index keeps
+serving searches, verify the recall of the shadow index on a sample of memos,
+and swap it in. An interrupted rebuild resumes when run again with the same model.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			setupLogger(viper.GetString("log-level"))
+			ctx, stop := signal.NotifyContext(cmd.Context(), terminationSignals...)
+			defer stop()
+			flags := cmd.Flags()
+			model, _ := flags.GetString("model")
+			batch, _ := flags.GetInt("batch")
+			sample, _ := flags.GetInt("sample")
+			minRecall, _ := flags.GetFloat64("min-recall")
+			return reembed(ctx, os.Stdout, embedding.RebuildOptions{
+				Model:      model,
+				BatchSize:  batch,
+				SampleSize: sample,
+				MinRecall:  minRecall,
+			})
+		},
+	}
+	reembedCmd.Flags().String("model", "", "embedding model to switch to, served by the configured embedding provider")
+	reembedCmd.Flags().Int("batch", 16, "memos per embedding request")
+	reembedCmd.Flags().Int("sample", 50, "memos of the recall verification")
+	reembedCmd.Flags().Float64("min-recall", 0.9, fmt.Sprintf("recall@%d required to swap the index", embedding.RecallK))
+	_ = reembedCmd.MarkFlagRequired("model")
+	rootCmd.AddCommand(reembedCmd)
+
+	vectorBenchCmd := &cobra.Command{
+		Use:   "vector-bench",
+		Short: "Measure the recall and latency of the memo vector index search parameter",
+		Long: `Search the memo vector index for a sample of memo embeddings with each value of
+the search parameter of the configured index (hnsw.ef_search or ivfflat.probes),
+and report the recall against an exact search with the latency of each value.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			setupLogger(viper.GetString("log-level"))
+			flags := cmd.Flags()
+			queries, _ := flags.GetInt("queries")
+			k, _ := flags.GetInt("k")
+			values, _ := flags.GetIntSlice("values")
+			return vectorBench(cmd.Context(), os.Stdout, vectorindex.BenchmarkOptions{
+				Queries: queries,
+				K:       k,
+				Values:  values,
+			})
+		},
+	}
+	vectorBenchCmd.Flags().Int("queries", 100, "memo embeddings searched for")
+	vectorBenchCmd.Flags().Int("k", 10, "neighbours of the recall")
+	vectorBenchCmd.Flags().IntSlice("values", nil, "values of the search parameter (default depends on the index type)")
+	rootCmd.AddCommand(vectorBenchCmd)
+
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import a folder of Markdown notes, an Obsidian vault, a Logseq graph or an export as memos",
+		Long: `Import the Markdown files of --path, a folder or a zip archive, as memos of --user,
+keeping their frontmatter, tags and times. Notes whose content the user already has
+as a memo are skipped, so importing a folder again only adds its new notes.
+Archives of "divinesense export" are imported with --format divinesense.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			setupLogger(viper.GetString("log-level"))
+			ctx, stop := signal.NotifyContext(cmd.Context(), terminationSignals...)
+			defer stop()
+			flags := cmd.Flags()
+			format, _ := flags.GetString("format")
+			folder, _ := flags.GetString("path")
+			username, _ := flags.GetString("user")
+			visibility, _ := flags.GetString("visibility")
+			dryRun, _ := flags.GetBool("dry-run")
+			return importNotes(ctx, os.Stdout, folder, username, &memoimport.Options{
+				Format:     memoimport.Format(format),
+				Visibility: strings.ToUpper(visibility),
+				DryRun:     dryRun,
+			})
+		},
+	}
+	importCmd.Flags().String("format", string(memoimport.FormatMarkdown), "format of the folder (markdown, obsidian, logseq, divinesense)")
+	importCmd.Flags().String("path", "", "folder or zip archive to import")
+	importCmd.Flags().String("user", "", "username of the owner of the memos")
+	importCmd.Flags().String("visibility", "PRIVATE", "visibility of the memos (PRIVATE, PROTECTED, PUBLIC)")
+	importCmd.Flags().Bool("dry-run", false, "report what would be imported without creating memos")
+	_ = importCmd.MarkFlagRequired("path")
+	_ = importCmd.MarkFlagRequired("user")
+	rootCmd.AddCommand(importCmd)
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the memos of a user with their attachments as a zip archive",
+		Long: `Export the memos of --user to --output, a zip archive of Markdown files with
+YAML frontmatter and of their attachments, which "divinesense import --format
+divinesense" imports back. With --since, only the memos updated since then are
+exported; each export prints the time the next one can start from.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			setupLogger(viper.GetString("log-level"))
+			ctx, stop := signal.NotifyContext(cmd.Context(), terminationSignals...)
+			defer stop()
+			flags := cmd.Flags()
+			output, _ := flags.GetString("output")
+			username, _ := flags.GetString("user")
+			sinceFlag, _ := flags.GetString("since")
+			since, err := parseSince(sinceFlag)
+			if err != nil {
+				return err
+			}
+			return exportMemos(ctx, os.Stdout, output, username, since)
+		},
+	}
+	exportCmd.Flags().String("output", "", "zip archive to write")
+	exportCmd.Flags().String("user", "", "username of the owner of the memos")
+	exportCmd.Flags().String("since", "", "only export the memos updated since this time (RFC 3339 or Unix seconds)")
+	_ = exportCmd.MarkFlagRequired("output")
+	_ = exportCmd.MarkFlagRequired("user")
+	rootCmd.AddCommand(exportCmd)
+
 	rootCmd.PersistentFlags().String("mode", "dev", `mode of server, can be "prod" or "dev" or "demo"`)
 	rootCmd.PersistentFlags().String("addr", "", "address of server")
 	rootCmd.PersistentFlags().Int("port", 28081, "port of server")
@@ -157,6 +356,9 @@ func init() {
 	rootCmd.PersistentFlags().String("dsn", "", "database source name(aka. DSN)")
 	rootCmd.PersistentFlags().String("instance-url", "", "the url of your divinesense instance")
 	rootCmd.PersistentFlags().String("log-level", "INFO", "log level (DEBUG, INFO, WARN, ERROR)")
+	rootCmd.PersistentFlags().Bool("skip-preflight", false, "skip the startup preflight checks")
+	rootCmd.PersistentFlags().Bool("safe-mode", false, "start with AI chat, CC runners and background AI jobs disabled")
+	rootCmd.Flags().Bool("check-upgrade", false, "report the pending schema migrations, their table locks and manual steps, and exit (1 when the upgrade would fail)")
 
 	if err := viper.BindPFlag("mode", rootCmd.PersistentFlags().Lookup("mode")); err != nil {
 		panic(err)
@@ -185,6 +387,15 @@ func init() {
 	if err := viper.BindPFlag("log-level", rootCmd.PersistentFlags().Lookup("log-level")); err != nil {
 		panic(err)
 	}
+	if err := viper.BindPFlag("skip-preflight", rootCmd.PersistentFlags().Lookup("skip-preflight")); err != nil {
+		panic(err)
+	}
+	if err := viper.BindPFlag("safe-mode", rootCmd.PersistentFlags().Lookup("safe-mode")); err != nil {
+		panic(err)
+	}
+	if err := viper.BindPFlag("check-upgrade", rootCmd.Flags().Lookup("check-upgrade")); err != nil {
+		panic(err)
+	}
 
 	viper.SetEnvPrefix("divinesense")
 	viper.AutomaticEnv()
@@ -203,6 +414,407 @@ func init() {
 	if err := viper.BindEnv("log-level", "DIVINESENSE_LOG_LEVEL"); err != nil {
 		panic(err)
 	}
+	if err := viper.BindEnv("safe-mode", "DIVINESENSE_SAFE_MODE"); err != nil {
+		panic(err)
+	}
+}
+
+// preflight runs the startup checks of the profile and of the agent runner
+// backends. The backends are not checked in safe mode, which never runs them.
+func preflight(ctx context.Context, instanceProfile *profile.Profile, db *sql.DB) error {
+	if err := instanceProfile.Preflight(ctx, db); err != nil {
+		return err
+	}
+	if instanceProfile.SafeMode {
+		return nil
+	}
+	for _, backend := range []string{instanceProfile.GeekRunner, instanceProfile.EvolutionRunner} {
+		if err := agent.CheckRunnerBackend(ctx, backend); err != nil {
+			return err
+		}
+	}
+	return geek.CheckWorkspacePermissions()
+}
+
+// checkUpgrade prints the preview of the schema upgrade of the next startup,
+// and reports whether it would succeed.
+func checkUpgrade(ctx context.Context, w io.Writer, st *store.Store) (bool, error) {
+	plan, err := st.PlanUpgrade(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case plan.FreshInstall:
+		fmt.Fprintf(w, "empty database: the %s schema %s will be created\n", plan.Driver, plan.TargetVersion)
+	case plan.Mode != "prod":
+		fmt.Fprintf(w, "schema %s: %s mode does not migrate the database, use --mode prod\n", plan.DatabaseVersion, plan.Mode)
+	default:
+		fmt.Fprintf(w, "schema %s, target %s: %d pending migrations\n", plan.DatabaseVersion, plan.TargetVersion, len(plan.Migrations))
+	}
+	for _, migration := range plan.Migrations {
+		fmt.Fprintf(w, "  %s (%d statements)\n", migration.File, migration.Statements)
+		for _, lock := range migration.Locks {
+			rows := "unknown rows"
+			if lock.Rows >= 0 {
+				rows = fmt.Sprintf("~%d rows", lock.Rows)
+			}
+			mark := ""
+			if lock.Large() {
+				mark = " [large table]"
+			}
+			fmt.Fprintf(w, "    %-34s %-20s %-18s %-5s %s, %d MB%s\n",
+				lock.Operation, lock.Table, lock.Mode, lock.Impact, rows, lock.SizeBytes>>20, mark)
+		}
+	}
+	if len(plan.Migrations) > 0 {
+		fmt.Fprintln(w, "migrations run in one transaction: their locks are held until the last one commits")
+	}
+	if large := plan.LargeLocks(); len(large) > 0 {
+		fmt.Fprintf(w, "%d locks scan large tables and block their writes for long: upgrade in a maintenance window\n", len(large))
+	}
+	if len(plan.ManualSteps) > 0 {
+		fmt.Fprintln(w, "manual steps:")
+		for _, step := range plan.ManualSteps {
+			fmt.Fprintf(w, "  - %s\n", step)
+		}
+	}
+	if plan.Blocked() {
+		fmt.Fprintln(w, "blockers:")
+		for _, blocker := range plan.Blockers {
+			fmt.Fprintf(w, "  - %s\n", blocker)
+		}
+		fmt.Fprintln(w, "upgrade check failed")
+		return false, nil
+	}
+	fmt.Fprintln(w, "upgrade check passed")
+	return true, nil
+}
+
+// logUpgradePlan logs the pending migrations before the startup applies them.
+// Failures to plan the upgrade do not stop the startup.
+func logUpgradePlan(ctx context.Context, st *store.Store) {
+	plan, err := st.PlanUpgrade(ctx)
+	if err != nil {
+		slog.Warn("failed to plan the schema upgrade", "error", err)
+		return
+	}
+	if len(plan.Migrations) == 0 {
+		return
+	}
+	slog.Info("applying pending migrations",
+		"from", plan.DatabaseVersion, "to", plan.TargetVersion, "migrations", len(plan.Migrations))
+	for _, lock := range plan.LargeLocks() {
+		slog.Warn("migration locks a large table", "table", lock.Table, "operation", lock.Operation,
+			"lock", lock.Mode, "rows", lock.Rows, "size_mb", lock.SizeBytes>>20)
+	}
+	for _, step := range plan.ManualSteps {
+		slog.Warn("migration requires a manual step", "step", step)
+	}
+}
+
+// evalExamples routes the holdout few-shot examples with and without the
+// other examples, and prints the accuracy of both and the changed routes.
+func evalExamples(ctx context.Context, w io.Writer) error {
+	instanceProfile, err := newInstanceProfile()
+	if err != nil {
+		return err
+	}
+	if instanceProfile.Driver != "postgres" {
+		return fmt.Errorf("few-shot examples require the postgres driver, not %s", instanceProfile.Driver)
+	}
+	dbDriver, err := db.NewDBDriver(instanceProfile)
+	if err != nil {
+		return fmt.Errorf("failed to create db driver: %w", err)
+	}
+	defer dbDriver.Close()
+
+	stored, err := fewshot.NewDBStore(dbDriver.GetDB()).ListExamples(ctx)
+	if err != nil {
+		return err
+	}
+	used, holdout := fewshot.Split(stored)
+	if len(holdout) == 0 {
+		return errors.New("no holdout examples: mark some examples as holdout to evaluate the others")
+	}
+
+	// Route with the keyword index of the parrot configs, like the server
+	factory, err := universal.NewParrotFactory()
+	if err != nil {
+		return err
+	}
+	var configs []*agent.ParrotSelfCognition
+	for _, name := range factory.ListConfigs() {
+		if cfg, ok := factory.GetConfig(name); ok && cfg.SelfDescription != nil {
+			configs = append(configs, cfg.SelfDescription)
+		}
+	}
+	newRouter := func() *routing.Service {
+		cfg := routing.Config{}
+		if len(configs) > 0 {
+			cm := orchestrator.NewCapabilityMap()
+			cm.BuildFromConfigs(configs)
+			cm.BuildKeywordIndex(configs)
+			cfg.CapabilityMap = cm
+			cfg.RoutingMatcher = cm
+		}
+		return routing.NewService(cfg)
+	}
+
+	// Signed-in users take the keyword index path of the rule layer
+	ctx = routing.WithUserID(ctx, 1)
+	eval := routing.EvaluateExamples(ctx, newRouter, routeExamples(used), routeExamples(holdout))
+
+	fmt.Fprintf(w, "examples: %d, holdout: %d\n", eval.Examples, eval.Holdout)
+	fmt.Fprintf(w, "baseline accuracy:      %5.1f%% (%d/%d)\n", eval.BaselineAccuracy*100, eval.BaselineCorrect, eval.Holdout)
+	fmt.Fprintf(w, "with examples accuracy: %5.1f%% (%d/%d)\n", eval.ExamplesAccuracy*100, eval.ExamplesCorrect, eval.Holdout)
+	for _, change := range eval.Changes {
+		mark := "-"
+		if change.Fixed() {
+			mark = "+"
+		}
+		fmt.Fprintf(w, "%s %q: expected %s, baseline %s, with examples %s\n",
+			mark, change.Query, change.Expected, change.Baseline, change.WithExamples)
+	}
+	return nil
+}
+
+// reembed rebuilds the memo embedding index with opts.Model.
+func reembed(ctx context.Context, w io.Writer, opts embedding.RebuildOptions) error {
+	instanceProfile, err := newInstanceProfile()
+	if err != nil {
+		return err
+	}
+	if instanceProfile.Driver != "postgres" {
+		return fmt.Errorf("index rebuilds require the postgres driver, not %s", instanceProfile.Driver)
+	}
+	if !instanceProfile.IsAIEnabled() {
+		return errors.New("AI is disabled: set DIVINESENSE_AI_ENABLED=true")
+	}
+	aiConfig := ai.NewConfigFromProfile(instanceProfile)
+	if err := aiConfig.Validate(); err != nil {
+		return err
+	}
+	config := aiConfig.Embedding
+	config.Model = opts.Model
+	embeddingService, err := ai.NewEmbeddingService(&config)
+	if err != nil {
+		return err
+	}
+	dbDriver, err := db.NewDBDriver(instanceProfile)
+	if err != nil {
+		return fmt.Errorf("failed to create db driver: %w", err)
+	}
+	defer dbDriver.Close()
+
+	opts.ActiveModel = aiConfig.Embedding.Model
+	opts.Progress = func(rebuild *embedindex.Rebuild) {
+		fmt.Fprintf(w, "embedded %d/%d memos with %s\n", rebuild.Processed, rebuild.Total, rebuild.TargetModel)
+	}
+	rebuilder := embedding.NewRebuilder(store.New(dbDriver, instanceProfile), embedindex.NewDBStore(dbDriver.GetDB()), embeddingService)
+	rebuild, err := rebuilder.Rebuild(ctx, opts)
+	if err == nil || errors.Is(err, embedindex.ErrLowRecall) {
+		fmt.Fprintf(w, "recall@%d: %.2f (minimum %.2f)\n", embedding.RecallK, rebuild.Recall, opts.MinRecall)
+	}
+	if err != nil {
+		if rebuild != nil && rebuild.Status != embedindex.StatusSwapped {
+			fmt.Fprintf(w, "rebuild %d stopped at %d/%d memos; run the command again to resume\n",
+				rebuild.ID, rebuild.Processed, rebuild.Total)
+		}
+		return err
+	}
+	fmt.Fprintf(w, "swapped the active index from %s to %s; servers follow within a minute\n",
+		rebuild.SourceModel, rebuild.TargetModel)
+	return nil
+}
+
+// vectorBench benchmarks the search parameter of the memo vector index.
+func vectorBench(ctx context.Context, w io.Writer, opts vectorindex.BenchmarkOptions) error {
+	instanceProfile, err := newInstanceProfile()
+	if err != nil {
+		return err
+	}
+	if instanceProfile.Driver != "postgres" {
+		return fmt.Errorf("the vector index requires the postgres driver, not %s", instanceProfile.Driver)
+	}
+	dbDriver, err := db.NewDBDriver(instanceProfile)
+	if err != nil {
+		return fmt.Errorf("failed to create db driver: %w", err)
+	}
+	defer dbDriver.Close()
+
+	// Search the embeddings of the active model
+	opts.Model, err = embedindex.NewDBStore(dbDriver.GetDB()).ActiveModel(ctx)
+	if err != nil {
+		return err
+	}
+	if opts.Model == "" {
+		opts.Model = instanceProfile.AIEmbeddingModel
+	}
+	index := vectorindex.New(vectorindex.NewDBStore(dbDriver.GetDB()), vectorindex.ConfigFromProfile(instanceProfile))
+	status, err := index.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, info := range status.Indexes {
+		fmt.Fprintf(w, "index %s (%d MB): %s\n", info.Name, info.SizeBytes>>20, info.Definition)
+	}
+	if len(status.Indexes) == 0 {
+		fmt.Fprintln(w, "no vector index: searches scan the table")
+	}
+
+	result, err := index.Benchmark(ctx, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%d queries of %s, recall@%d against an exact search\n", result.Queries, opts.Model, result.K)
+	fmt.Fprintf(w, "%-16s %8s %10s %10s\n", result.Param, "recall", "avg", "p95")
+	fmt.Fprintf(w, "%-16s %8.3f %10s %10s\n", "exact", result.Exact.Recall,
+		result.Exact.AvgLatency.Round(time.Microsecond), result.Exact.P95Latency.Round(time.Microsecond))
+	for _, m := range result.Values {
+		fmt.Fprintf(w, "%-16d %8.3f %10s %10s\n", m.Value, m.Recall,
+			m.AvgLatency.Round(time.Microsecond), m.P95Latency.Round(time.Microsecond))
+	}
+	return nil
+}
+
+// importNotes imports a folder or zip archive of notes as memos of a user.
+func importNotes(ctx context.Context, w io.Writer, folder, username string, opts *memoimport.Options) error {
+	info, err := os.Stat(folder)
+	if err != nil {
+		return err
+	}
+	var fsys fs.FS
+	switch {
+	case info.IsDir():
+		fsys = os.DirFS(folder)
+	case strings.EqualFold(filepath.Ext(folder), ".zip"):
+		archive, err := zip.OpenReader(folder)
+		if err != nil {
+			return fmt.Errorf("failed to open archive: %w", err)
+		}
+		defer archive.Close()
+		fsys = archive
+	default:
+		return fmt.Errorf("%s is not a folder or a zip archive", folder)
+	}
+	instanceProfile, err := newInstanceProfile()
+	if err != nil {
+		return err
+	}
+	dbDriver, err := db.NewDBDriver(instanceProfile)
+	if err != nil {
+		return fmt.Errorf("failed to create db driver: %w", err)
+	}
+	defer dbDriver.Close()
+
+	st := store.New(dbDriver, instanceProfile)
+	user, err := findUser(ctx, st, username)
+	if err != nil {
+		return err
+	}
+	opts.UserID = user.ID
+
+	importer := memoimport.New(memoimport.NewMemoStore(st, markdown.NewService(markdown.WithTagExtension()),
+		func(ctx context.Context, attachment *store.Attachment) error {
+			return apiv1.SaveAttachmentBlob(ctx, instanceProfile, st, attachment)
+		}))
+	result, err := importer.Import(ctx, fsys, opts, func(event *memoimport.Event) {
+		switch event.Type {
+		case memoimport.EventStarted:
+			fmt.Fprintf(w, "importing %d notes from %s\n", event.Progress.Total, folder)
+		case memoimport.EventSkipped, memoimport.EventFailed:
+			fmt.Fprintf(w, "[%d/%d] %s %s: %s\n", event.Progress.Processed, event.Progress.Total, event.Type, event.Path, event.Error)
+		case memoimport.EventImported, memoimport.EventDuplicate:
+			fmt.Fprintf(w, "[%d/%d] %s %s\n", event.Progress.Processed, event.Progress.Total, event.Type, event.Path)
+		}
+	})
+	if result != nil {
+		verb := "imported"
+		if opts.DryRun {
+			verb = "would import"
+		}
+		fmt.Fprintf(w, "%s %d notes; %d duplicates, %d skipped, %d failed\n",
+			verb, result.Imported, result.Duplicates, result.Skipped, result.Failed)
+	}
+	return err
+}
+
+// exportMemos exports the memos of a user updated since a time, in seconds,
+// to a zip archive.
+func exportMemos(ctx context.Context, w io.Writer, output, username string, since int64) error {
+	instanceProfile, err := newInstanceProfile()
+	if err != nil {
+		return err
+	}
+	dbDriver, err := db.NewDBDriver(instanceProfile)
+	if err != nil {
+		return fmt.Errorf("failed to create db driver: %w", err)
+	}
+	defer dbDriver.Close()
+
+	st := store.New(dbDriver, instanceProfile)
+	user, err := findUser(ctx, st, username)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	attachments := &apiv1.AttachmentService{Store: st, Profile: instanceProfile}
+	exporter := memoexport.New(memoexport.NewMemoStore(st, attachments.GetAttachmentBlob))
+	manifest, err := exporter.Export(ctx, file, &memoexport.Options{UserID: user.ID, Since: since})
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(output)
+		return err
+	}
+	fmt.Fprintf(w, "exported %d memos and %d attachments to %s\n", manifest.Memos, manifest.Attachments, output)
+	for _, missing := range manifest.Missing {
+		fmt.Fprintf(w, "missing %s\n", missing)
+	}
+	fmt.Fprintf(w, "next export: --since %d\n", manifest.ExportedTs)
+	return nil
+}
+
+// parseSince parses a time as RFC 3339 or Unix seconds; empty is 0.
+func parseSince(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return seconds, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: use RFC 3339 or Unix seconds", value)
+	}
+	return t.Unix(), nil
+}
+
+// findUser returns the user of a username.
+func findUser(ctx context.Context, st *store.Store, username string) (*store.User, error) {
+	user, err := st.GetUser(ctx, &store.FindUser{Username: &username})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user %q not found", username)
+	}
+	return user, nil
+}
+
+// routeExamples converts few-shot examples for the router.
+func routeExamples(examples []*fewshot.Example) []routing.RouteExample {
+	converted := make([]routing.RouteExample, 0, len(examples))
+	for _, example := range examples {
+		converted = append(converted, routing.RouteExample{Query: example.Query, Route: routing.AgentType(example.Route)})
+	}
+	return converted
 }
 
 func printGreetings(profile *profile.Profile) {
@@ -219,6 +831,9 @@ func printGreetings(profile *profile.Profile) {
 	fmt.Printf("Data directory: %s\n", profile.Data)
 	fmt.Printf("Database driver: %s\n", profile.Driver)
 	fmt.Printf("Mode: %s\n", profile.Mode)
+	if profile.SafeMode {
+		fmt.Fprint(os.Stderr, "Safe mode is enabled: AI features are disabled\n")
+	}
 
 	// Connection information
 	if len(profile.UNIXSock) == 0 {
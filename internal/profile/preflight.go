@@ -0,0 +1,98 @@
+package profile
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// preflightTimeout bounds each check that talks to an external service.
+const preflightTimeout = 10 * time.Second
+
+// Preflight verifies the environment the profile runs in, beyond what Validate
+// checks on the profile itself: the data directory is writable, pgvector is
+// available when semantic features are on, and the LLM API key is accepted.
+// db is the opened database.
+//
+// Checks that cannot be completed (e.g. the LLM provider is unreachable) only
+// log a warning; Preflight fails when a check finds a configuration that cannot work.
+func (p *Profile) Preflight(ctx context.Context, db *sql.DB) error {
+	if err := checkDataDirWritable(p.Data); err != nil {
+		return err
+	}
+	if p.IsAIEnabled() && p.Driver == "postgres" && db != nil {
+		if err := checkPgvector(ctx, db); err != nil {
+			return err
+		}
+	}
+	if p.IsAIEnabled() {
+		if err := checkLLMKey(ctx, http.DefaultClient, p.ALLMProvider, p.ALLMBaseURL, p.ALLMAPIKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkDataDirWritable verifies that files can be created in the data directory.
+func checkDataDirWritable(dataDir string) error {
+	f, err := os.CreateTemp(dataDir, ".preflight-*")
+	if err != nil {
+		return errors.Wrapf(err, "data folder %s is not writable", dataDir)
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+// checkPgvector verifies that the vector extension is installed or can be installed.
+func checkPgvector(ctx context.Context, db *sql.DB) error {
+	ctx, cancel := context.WithTimeout(ctx, preflightTimeout)
+	defer cancel()
+
+	var available bool
+	err := db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'vector')").Scan(&available)
+	if err != nil {
+		return errors.Wrap(err, "failed to check pgvector availability")
+	}
+	if !available {
+		return errors.New("pgvector extension is not available in PostgreSQL; install pgvector or disable AI features")
+	}
+	return nil
+}
+
+// checkLLMKey verifies the LLM API key by listing the provider's models.
+// Only a rejected key fails; an unreachable provider is logged.
+func checkLLMKey(ctx context.Context, client *http.Client, provider, baseURL, apiKey string) error {
+	// Local providers take no key; Anthropic authenticates its model list
+	// with x-api-key rather than a bearer token
+	if provider == "ollama" || provider == "vllm" || provider == "anthropic" || baseURL == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, preflightTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+"/models", nil)
+	if err != nil {
+		return errors.Wrapf(err, "invalid LLM base URL %s", baseURL)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	resp, err := client.Do(req)
+	if err != nil {
+		slog.Warn("preflight: LLM provider unreachable, skipping API key check",
+			slog.String("provider", provider), slog.String("error", err.Error()))
+		return nil
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return errors.Errorf("LLM API key was rejected by %s (HTTP %d); check DIVINESENSE_AI_LLM_API_KEY", provider, resp.StatusCode)
+	}
+	return nil
+}
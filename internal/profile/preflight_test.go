@@ -0,0 +1,53 @@
+package profile
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCheckDataDirWritable 测试数据目录可写性检查。
+func TestCheckDataDirWritable(t *testing.T) {
+	dir := t.TempDir()
+	if err := checkDataDirWritable(dir); err != nil {
+		t.Fatalf("expected writable dir, got %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("expected probe file to be removed, found %d entries", len(entries))
+	}
+	if err := checkDataDirWritable(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error for missing dir")
+	}
+}
+
+// TestCheckLLMKey 测试 LLM API Key 校验。
+func TestCheckLLMKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer good-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	if err := checkLLMKey(ctx, server.Client(), "openai", server.URL+"/v1/", "good-key"); err != nil {
+		t.Errorf("expected valid key, got %v", err)
+	}
+	if err := checkLLMKey(ctx, server.Client(), "openai", server.URL+"/v1", "bad-key"); err == nil {
+		t.Error("expected rejected key to fail")
+	}
+	// An unreachable provider does not fail startup.
+	if err := checkLLMKey(ctx, server.Client(), "openai", "http://127.0.0.1:1", "bad-key"); err != nil {
+		t.Errorf("expected unreachable provider to be skipped, got %v", err)
+	}
+}
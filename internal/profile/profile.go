@@ -22,11 +22,41 @@ type Profile struct {
 	ALLMModel    string // Model name: glm-4.7, deepseek-chat, gpt-4o, etc.
 	ALLMTimeout  int    // LLM request timeout in seconds (default: 120)
 
+	// Named LLM providers besides the unified one, and the provider[:model]
+	// of the agent types that do not use the unified LLM
+	ALLMProviders   []LLMProvider
+	ALLMAgentModels map[string]string // Agent type → "provider" or "provider:model"
+
+	// PostgreSQL text search configuration of each query language (zh, ja, ko
+	// or default); languages without one are searched by CJK bigrams with the
+	// default configuration, "simple" unless set
+	FTSLanguages map[string]string
+
 	// Embedding configuration
 	AIEmbeddingProvider string
 	AIEmbeddingModel    string
 	AIEmbeddingAPIKey   string
 	AIEmbeddingBaseURL  string
+	// Query embedding cache: entries (0 disables) and minutes they are kept
+	AIEmbeddingCacheSize       int
+	AIEmbeddingCacheTTLMinutes int
+	// pgvector index of the memo embeddings (PostgreSQL): type (hnsw or
+	// ivfflat), build parameters, and search parameters (0 is the default)
+	VectorIndexType           string
+	VectorIndexM              int
+	VectorIndexEfConstruction int
+	VectorIndexLists          int
+	VectorIndexEfSearch       int
+	VectorIndexProbes         int
+	// Hybrid memo retrieval of the memo agent: semantic weight of the
+	// reciprocal rank fusion (the BM25 weight is 1 - weight), the weight of
+	// short queries of at most HybridShortQueryRunes runes, the RRF rank
+	// constant and the candidates taken from each ranking
+	HybridSemanticWeight   float64
+	HybridShortQueryWeight float64
+	HybridShortQueryRunes  int
+	HybridRRFK             int
+	HybridCandidates       int
 
 	// Reranker configuration
 	AIRerankProvider string
@@ -40,6 +70,95 @@ type Profile struct {
 	AIIntentAPIKey   string
 	AIIntentBaseURL  string
 
+	// OCR provider API configuration (DIVINESENSE_OCR_PROVIDER=api)
+	OCRProvider   string // tesseract (default) or api
+	OCRAPIModel   string // Vision model, e.g. Qwen/Qwen2.5-VL-72B-Instruct
+	OCRAPIKey     string // Defaults to the unified LLM API key
+	OCRAPIBaseURL string // Defaults to the unified LLM base URL
+
+	// PDF ingestion limits (requires text extraction and AI)
+	PDFMaxSizeMB int // Larger PDFs are not ingested (default: 50)
+	PDFMaxPages  int // PDFs with more pages are not ingested (default: 500)
+
+	// AI chat load shedding thresholds; 0 disables a signal
+	LoadShedCPUPercent   int // System CPU usage (default: 90)
+	LoadShedDBLatencyMs  int // Database ping round trip (default: 1000)
+	LoadShedQueuePercent int // Fill of the session stats queue (default: 80)
+
+	// Monthly AI quotas of each user; 0 means unlimited
+	AIMonthlyTokenQuota   int64   // Input and output tokens (default: 0)
+	AIMonthlyCostQuotaUSD float64 // Estimated cost in USD (default: 0)
+
+	// Browser extension capture API
+	CaptureAllowedOrigins string // Comma-separated origins; any extension origin when empty
+
+	// Providers of the weather tool and of the currency conversions of the
+	// unit_convert tool; empty disables them
+	WeatherProvider  string // open-meteo or openweathermap
+	WeatherAPIKey    string // Required by openweathermap
+	CurrencyProvider string // frankfurter
+
+	// Home Assistant instance of the home parrot; empty URL disables it
+	HomeAssistantURL     string // e.g. http://homeassistant.local:8123
+	HomeAssistantToken   string // Long-lived access token
+	HomeAssistantRoles   string // Comma-separated roles allowed to control the home (default: HOST,ADMIN)
+	HomeAssistantDomains string // Comma-separated entity domains visible and controllable
+
+	// Instance bot of the Telegram gateway; empty token disables it
+	TelegramBotToken string
+	TelegramAPIURL   string // Bot API (default: https://api.telegram.org)
+
+	// Inbound email capture; both are required to enable it
+	EmailInDomain     string // Domain of the secret addresses, routed to the inbound webhook
+	EmailInSigningKey string // Webhook signing key of the mail provider
+
+	// Key encrypting the IMAP passwords of the mail parrot (32 bytes); empty disables it
+	EmailSecretKey string
+
+	// Key encrypting the CalDAV passwords of calendar sync (32 bytes); empty disables CalDAV
+	CalDAVSecretKey string
+
+	// SMTP server of the email reminders; empty host disables them
+	SMTPHost     string
+	SMTPPort     int // default: 587 with STARTTLS, 465 uses implicit TLS
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string // Sender address
+	SMTPFromName string // default: DivineSense
+
+	// VAPID keys of the web push reminders (base64url); empty private key disables them
+	VAPIDPublicKey  string
+	VAPIDPrivateKey string
+	VAPIDSubject    string // mailto: or https: contact for push services
+
+	// StatusPage serves the public status page at /api/v1/status (default: true)
+	StatusPage bool
+
+	// Metrics serves the Prometheus metrics at /metrics (default: true)
+	Metrics      bool
+	MetricsToken string // Bearer token required from scrapers; empty leaves /metrics open
+
+	// TracingEndpoint is the OTLP/HTTP collector receiving the OpenTelemetry
+	// traces of the chat path, e.g. http://localhost:4318; empty disables tracing
+	TracingEndpoint    string
+	TracingSampleRatio float64 // Share of the chat rounds traced, 0-1 (default: 1)
+
+	// Agent runner backends of the Geek and Evolution modes (claude, opencode)
+	GeekRunner      string // default: claude
+	EvolutionRunner string // default: claude
+
+	// Disk quota and garbage collection of the Geek Mode workspaces; 0 disables a limit
+	GeekWorkspaceQuotaMB       int // Size of each user's workspace (default: 1024)
+	GeekWorkspaceMaxAgeDays    int // Unused CLI sessions and temp files are pruned (default: 30)
+	GeekWorkspaceTempFileMaxMB int // Larger temp files are pruned (default: 100)
+
+	// Instance of a multi-instance deployment: InstanceURL is then the address
+	// other instances forward the CC sessions it holds to
+	InstanceID string // Default: hostname
+	// Redis holding the sessions of the instances, redis://[[username]:password@]host[:port][/db];
+	// empty uses the PostgreSQL database
+	AffinityRedisURL string
+
 	// Other configurations
 	TikaServerURL      string
 	UNIXSock           string
@@ -57,6 +176,19 @@ type Profile struct {
 	OCREnabled         bool
 	TextExtractEnabled bool
 	AIEnabled          bool
+	// SafeMode disables AI chat, CC runners and background AI jobs, so data
+	// stays accessible when an AI dependency is broken (--safe-mode).
+	SafeMode bool
+}
+
+// LLMProvider is a named LLM provider of the registry, configured by
+// DIVINESENSE_AI_LLM_<NAME>_{PROVIDER,API_KEY,BASE_URL,MODEL}.
+type LLMProvider struct {
+	Name     string // Registry name, e.g. cheap
+	Provider string // Provider identifier, defaults to the name
+	APIKey   string
+	BaseURL  string // Defaults to the provider default
+	Model    string // Default model, defaults to the provider default
 }
 
 // Provider default configurations for LLM.
@@ -93,6 +225,14 @@ var llmProviderDefaults = map[string]struct {
 		BaseURL: "http://localhost:11434",
 		Model:   "llama3.1",
 	},
+	"anthropic": {
+		BaseURL: "https://api.anthropic.com/v1", // OpenAI SDK compatibility endpoint
+		Model:   "claude-sonnet-4-5",
+	},
+	"vllm": {
+		BaseURL: "http://localhost:8000/v1",
+		Model:   "", // The model served by the local vLLM must be set
+	},
 }
 
 func (p *Profile) IsDev() bool {
@@ -100,8 +240,9 @@ func (p *Profile) IsDev() bool {
 }
 
 // IsAIEnabled returns true if AI is enabled and LLM API key is configured.
+// AI is always disabled in safe mode.
 func (p *Profile) IsAIEnabled() bool {
-	return p.ALLMAPIKey != ""
+	return !p.SafeMode && p.ALLMAPIKey != ""
 }
 
 // getEnvOrDefault returns environment variable value or default value.
@@ -122,6 +263,16 @@ func getEnvOrDefaultInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvOrDefaultFloat returns environment variable value as float64 or default value.
+func getEnvOrDefaultFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultValue
+}
+
 // FromEnv loads configuration from environment variables.
 func (p *Profile) FromEnv() {
 	// Unified LLM configuration
@@ -131,8 +282,8 @@ func (p *Profile) FromEnv() {
 	p.ALLMModel = getEnvOrDefault("DIVINESENSE_AI_LLM_MODEL", "")
 	p.ALLMTimeout = getEnvOrDefaultInt("DIVINESENSE_AI_LLM_TIMEOUT_SECONDS", 120)
 
-	// AI is enabled if API key is configured
-	p.AIEnabled = p.ALLMAPIKey != ""
+	// AI is enabled if API key is configured, unless in safe mode
+	p.AIEnabled = !p.SafeMode && p.ALLMAPIKey != ""
 
 	// Validate and apply provider defaults if not explicitly set
 	if p.ALLMProvider != "" {
@@ -152,12 +303,28 @@ func (p *Profile) FromEnv() {
 		}
 	}
 
-	// Embedding configuration
+	p.ALLMProviders = llmProvidersFromEnv()
+	p.ALLMAgentModels = agentModelsFromEnv()
+	p.FTSLanguages = keyValuesFromEnv("DIVINESENSE_FTS_LANGUAGES", "language=config")
+
 	// Embedding configuration
 	p.AIEmbeddingProvider = getEnvOrDefault("DIVINESENSE_AI_EMBEDDING_PROVIDER", "siliconflow")
 	p.AIEmbeddingModel = getEnvOrDefault("DIVINESENSE_AI_EMBEDDING_MODEL", "BAAI/bge-m3")
 	p.AIEmbeddingAPIKey = getEnvOrDefault("DIVINESENSE_AI_EMBEDDING_API_KEY", "")
 	p.AIEmbeddingBaseURL = getEnvOrDefault("DIVINESENSE_AI_EMBEDDING_BASE_URL", "https://api.siliconflow.cn/v1")
+	p.AIEmbeddingCacheSize = getEnvOrDefaultInt("DIVINESENSE_AI_EMBEDDING_CACHE_SIZE", 1000)
+	p.AIEmbeddingCacheTTLMinutes = getEnvOrDefaultInt("DIVINESENSE_AI_EMBEDDING_CACHE_TTL_MINUTES", 60)
+	p.VectorIndexType = getEnvOrDefault("DIVINESENSE_VECTOR_INDEX_TYPE", "hnsw")
+	p.VectorIndexM = getEnvOrDefaultInt("DIVINESENSE_VECTOR_INDEX_M", 16)
+	p.VectorIndexEfConstruction = getEnvOrDefaultInt("DIVINESENSE_VECTOR_INDEX_EF_CONSTRUCTION", 64)
+	p.VectorIndexLists = getEnvOrDefaultInt("DIVINESENSE_VECTOR_INDEX_LISTS", 100)
+	p.VectorIndexEfSearch = getEnvOrDefaultInt("DIVINESENSE_VECTOR_INDEX_EF_SEARCH", 0)
+	p.VectorIndexProbes = getEnvOrDefaultInt("DIVINESENSE_VECTOR_INDEX_PROBES", 0)
+	p.HybridSemanticWeight = getEnvOrDefaultFloat("DIVINESENSE_HYBRID_SEMANTIC_WEIGHT", 0.5)
+	p.HybridShortQueryWeight = getEnvOrDefaultFloat("DIVINESENSE_HYBRID_SHORT_QUERY_WEIGHT", 0.3)
+	p.HybridShortQueryRunes = getEnvOrDefaultInt("DIVINESENSE_HYBRID_SHORT_QUERY_RUNES", 4)
+	p.HybridRRFK = getEnvOrDefaultInt("DIVINESENSE_HYBRID_RRF_K", 60)
+	p.HybridCandidates = getEnvOrDefaultInt("DIVINESENSE_HYBRID_CANDIDATES", 30)
 
 	// Reranker configuration
 	p.AIRerankProvider = getEnvOrDefault("DIVINESENSE_AI_RERANK_PROVIDER", "siliconflow")
@@ -178,6 +345,109 @@ func (p *Profile) FromEnv() {
 	p.TessdataPath = getEnvOrDefault("DIVINESENSE_OCR_TESSDATA_PATH", "")
 	p.OCRLanguages = getEnvOrDefault("DIVINESENSE_OCR_LANGUAGES", "chi_sim+eng")
 	p.TikaServerURL = getEnvOrDefault("DIVINESENSE_TEXTEXTRACT_TIKA_URL", "http://localhost:9998")
+	p.OCRProvider = getEnvOrDefault("DIVINESENSE_OCR_PROVIDER", "tesseract")
+	p.OCRAPIModel = getEnvOrDefault("DIVINESENSE_OCR_API_MODEL", "")
+	p.OCRAPIKey = getEnvOrDefault("DIVINESENSE_OCR_API_KEY", p.ALLMAPIKey)
+	p.OCRAPIBaseURL = getEnvOrDefault("DIVINESENSE_OCR_API_BASE_URL", p.ALLMBaseURL)
+	p.PDFMaxSizeMB = getEnvOrDefaultInt("DIVINESENSE_PDF_MAX_SIZE_MB", 50)
+	p.PDFMaxPages = getEnvOrDefaultInt("DIVINESENSE_PDF_MAX_PAGES", 500)
+	p.LoadShedCPUPercent = getEnvOrDefaultInt("DIVINESENSE_LOADSHED_CPU_PERCENT", 90)
+	p.LoadShedDBLatencyMs = getEnvOrDefaultInt("DIVINESENSE_LOADSHED_DB_LATENCY_MS", 1000)
+	p.LoadShedQueuePercent = getEnvOrDefaultInt("DIVINESENSE_LOADSHED_QUEUE_PERCENT", 80)
+	p.AIMonthlyTokenQuota = int64(getEnvOrDefaultInt("DIVINESENSE_AI_MONTHLY_TOKEN_QUOTA", 0))
+	p.AIMonthlyCostQuotaUSD = getEnvOrDefaultFloat("DIVINESENSE_AI_MONTHLY_COST_QUOTA_USD", 0)
+	p.CaptureAllowedOrigins = getEnvOrDefault("DIVINESENSE_CAPTURE_ALLOWED_ORIGINS", "")
+	p.WeatherProvider = getEnvOrDefault("DIVINESENSE_WEATHER_PROVIDER", "")
+	p.WeatherAPIKey = getEnvOrDefault("DIVINESENSE_WEATHER_API_KEY", "")
+	p.CurrencyProvider = getEnvOrDefault("DIVINESENSE_CURRENCY_PROVIDER", "")
+	p.HomeAssistantURL = getEnvOrDefault("DIVINESENSE_HOMEASSISTANT_URL", "")
+	p.HomeAssistantToken = getEnvOrDefault("DIVINESENSE_HOMEASSISTANT_TOKEN", "")
+	p.HomeAssistantRoles = getEnvOrDefault("DIVINESENSE_HOMEASSISTANT_ROLES", "")
+	p.HomeAssistantDomains = getEnvOrDefault("DIVINESENSE_HOMEASSISTANT_DOMAINS", "")
+	p.TelegramBotToken = getEnvOrDefault("DIVINESENSE_TELEGRAM_BOT_TOKEN", "")
+	p.TelegramAPIURL = getEnvOrDefault("DIVINESENSE_TELEGRAM_API_URL", "")
+	p.EmailInDomain = getEnvOrDefault("DIVINESENSE_EMAIL_IN_DOMAIN", "")
+	p.EmailInSigningKey = getEnvOrDefault("DIVINESENSE_EMAIL_IN_SIGNING_KEY", "")
+	p.EmailSecretKey = getEnvOrDefault("DIVINESENSE_EMAIL_SECRET_KEY", "")
+	p.CalDAVSecretKey = getEnvOrDefault("DIVINESENSE_CALDAV_SECRET_KEY", "")
+	p.SMTPHost = getEnvOrDefault("DIVINESENSE_SMTP_HOST", "")
+	p.SMTPPort = getEnvOrDefaultInt("DIVINESENSE_SMTP_PORT", 587)
+	p.SMTPUsername = getEnvOrDefault("DIVINESENSE_SMTP_USERNAME", "")
+	p.SMTPPassword = getEnvOrDefault("DIVINESENSE_SMTP_PASSWORD", "")
+	p.SMTPFrom = getEnvOrDefault("DIVINESENSE_SMTP_FROM", "")
+	p.SMTPFromName = getEnvOrDefault("DIVINESENSE_SMTP_FROM_NAME", "DivineSense")
+	p.VAPIDPublicKey = getEnvOrDefault("DIVINESENSE_VAPID_PUBLIC_KEY", "")
+	p.VAPIDPrivateKey = getEnvOrDefault("DIVINESENSE_VAPID_PRIVATE_KEY", "")
+	p.VAPIDSubject = getEnvOrDefault("DIVINESENSE_VAPID_SUBJECT", "")
+	p.StatusPage = getEnvOrDefault("DIVINESENSE_STATUS_PAGE", "true") != "false"
+	p.Metrics = getEnvOrDefault("DIVINESENSE_METRICS", "true") != "false"
+	p.MetricsToken = getEnvOrDefault("DIVINESENSE_METRICS_TOKEN", "")
+	p.TracingEndpoint = getEnvOrDefault("DIVINESENSE_TRACING_ENDPOINT", "")
+	p.TracingSampleRatio = getEnvOrDefaultFloat("DIVINESENSE_TRACING_SAMPLE_RATIO", 1)
+	p.GeekRunner = getEnvOrDefault("DIVINESENSE_GEEK_RUNNER", "claude")
+	p.EvolutionRunner = getEnvOrDefault("DIVINESENSE_EVOLUTION_RUNNER", "claude")
+	p.GeekWorkspaceQuotaMB = getEnvOrDefaultInt("DIVINESENSE_GEEK_WORKSPACE_QUOTA_MB", 1024)
+	p.GeekWorkspaceMaxAgeDays = getEnvOrDefaultInt("DIVINESENSE_GEEK_WORKSPACE_MAX_AGE_DAYS", 30)
+	p.GeekWorkspaceTempFileMaxMB = getEnvOrDefaultInt("DIVINESENSE_GEEK_WORKSPACE_TEMP_FILE_MAX_MB", 100)
+	p.InstanceID = getEnvOrDefault("DIVINESENSE_INSTANCE_ID", "")
+	p.AffinityRedisURL = getEnvOrDefault("DIVINESENSE_AFFINITY_REDIS_URL", "")
+}
+
+// llmProvidersFromEnv returns the providers named by
+// DIVINESENSE_AI_LLM_PROVIDERS (comma-separated), skipping the unknown ones.
+func llmProvidersFromEnv() []LLMProvider {
+	var providers []LLMProvider
+	for name := range strings.SplitSeq(os.Getenv("DIVINESENSE_AI_LLM_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "DIVINESENSE_AI_LLM_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		provider := LLMProvider{
+			Name:     name,
+			Provider: getEnvOrDefault(prefix+"PROVIDER", name),
+			APIKey:   os.Getenv(prefix + "API_KEY"),
+			BaseURL:  os.Getenv(prefix + "BASE_URL"),
+			Model:    os.Getenv(prefix + "MODEL"),
+		}
+		defaults, ok := llmProviderDefaults[provider.Provider]
+		if !ok {
+			slog.Warn("Unknown LLM provider, skipping", "name", name, "provider", provider.Provider)
+			continue
+		}
+		if provider.BaseURL == "" {
+			provider.BaseURL = defaults.BaseURL
+		}
+		if provider.Model == "" {
+			provider.Model = defaults.Model
+		}
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
+// agentModelsFromEnv parses DIVINESENSE_AI_AGENT_MODELS, comma-separated
+// "agent=provider[:model]" entries, e.g. "memo=cheap,orchestrator=strong:gpt-5.2".
+func agentModelsFromEnv() map[string]string {
+	return keyValuesFromEnv("DIVINESENSE_AI_AGENT_MODELS", "agent=provider[:model]")
+}
+
+// keyValuesFromEnv parses comma-separated "key=value" entries of an
+// environment variable, skipping the invalid ones.
+func keyValuesFromEnv(name, format string) map[string]string {
+	values := map[string]string{}
+	for entry := range strings.SplitSeq(os.Getenv(name), ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				slog.Warn("Invalid entry, expected "+format, "env", name, "entry", entry)
+			}
+			continue
+		}
+		values[key] = value
+	}
+	return values
 }
 
 func checkDataDir(dataDir string) (string, error) {
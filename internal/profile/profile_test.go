@@ -158,6 +158,37 @@ func TestAIProfileProviderDefaults(t *testing.T) {
 	}
 }
 
+// TestLLMProvidersFromEnv 测试多 Provider 注册与 Agent 模型选择的环境变量。
+func TestLLMProvidersFromEnv(t *testing.T) {
+	clearAIEnvVars()
+	t.Setenv("DIVINESENSE_AI_LLM_PROVIDERS", "deepseek, local-gpu,unknown,")
+	t.Setenv("DIVINESENSE_AI_LLM_DEEPSEEK_API_KEY", "sk-deepseek")
+	t.Setenv("DIVINESENSE_AI_LLM_LOCAL_GPU_PROVIDER", "vllm")
+	t.Setenv("DIVINESENSE_AI_LLM_LOCAL_GPU_MODEL", "qwen3-32b")
+	t.Setenv("DIVINESENSE_AI_AGENT_MODELS", "memo=deepseek, orchestrator=local-gpu:qwen3-32b,invalid")
+
+	profile := &Profile{}
+	profile.FromEnv()
+
+	expected := []LLMProvider{
+		{Name: "deepseek", Provider: "deepseek", APIKey: "sk-deepseek", BaseURL: "https://api.deepseek.com", Model: "deepseek-chat"},
+		{Name: "local-gpu", Provider: "vllm", BaseURL: "http://localhost:8000/v1", Model: "qwen3-32b"},
+	}
+	if len(profile.ALLMProviders) != len(expected) {
+		t.Fatalf("expected providers %+v, got %+v", expected, profile.ALLMProviders)
+	}
+	for i := range expected {
+		if profile.ALLMProviders[i] != expected[i] {
+			t.Errorf("provider %d: expected %+v, got %+v", i, expected[i], profile.ALLMProviders[i])
+		}
+	}
+	if len(profile.ALLMAgentModels) != 2 ||
+		profile.ALLMAgentModels["memo"] != "deepseek" ||
+		profile.ALLMAgentModels["orchestrator"] != "local-gpu:qwen3-32b" {
+		t.Errorf("unexpected agent models %v", profile.ALLMAgentModels)
+	}
+}
+
 // TestIsAIEnabled 测试 IsAIEnabled 逻辑。
 // Note: IsAIEnabled() now only checks if LLM API key is configured,
 // not the AIEnabled field. This allows dynamic enable/disable based on config.
@@ -181,6 +212,14 @@ func TestIsAIEnabled(t *testing.T) {
 			},
 			expectedResult: true,
 		},
+		{
+			name: "safe mode returns false",
+			setupProfile: func(p *Profile) {
+				p.ALLMAPIKey = "test-key"
+				p.SafeMode = true
+			},
+			expectedResult: false,
+		},
 	}
 
 	for _, tt := range tests {
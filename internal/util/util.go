@@ -2,10 +2,12 @@ package util //nolint:revive // util namespace is intentional for shared helpers
 
 import (
 	"crypto/rand"
+	"fmt"
 	"math/big"
 	"net/mail"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -71,3 +73,24 @@ func ReplaceString(slice []string, old, new string) []string {
 	}
 	return slice
 }
+
+// OrEmpty returns an empty list for nil, so that lists are saved to NOT NULL
+// columns and serialized as empty arrays.
+func OrEmpty[T any](list []T) []T {
+	if list == nil {
+		return []T{}
+	}
+	return list
+}
+
+// NowIn returns the current time in an IANA time zone, or the local one.
+func NowIn(timezone string) (time.Time, error) {
+	if timezone == "" {
+		return time.Now(), nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unknown timezone %q", timezone)
+	}
+	return time.Now().In(loc), nil
+}
@@ -29,3 +29,22 @@ func TestValidateEmail(t *testing.T) {
 		}
 	}
 }
+
+func TestOrEmpty(t *testing.T) {
+	if list := OrEmpty[string](nil); list == nil || len(list) != 0 {
+		t.Errorf("OrEmpty(nil) = %#v, want an empty list", list)
+	}
+	if list := OrEmpty([]string{"a"}); len(list) != 1 {
+		t.Errorf("OrEmpty([a]) = %#v, want [a]", list)
+	}
+}
+
+func TestNowIn(t *testing.T) {
+	now, err := NowIn("Asia/Shanghai")
+	if err != nil || now.Location().String() != "Asia/Shanghai" {
+		t.Errorf("NowIn(Asia/Shanghai) = %v, %v", now, err)
+	}
+	if _, err := NowIn("Mars/Olympus"); err == nil {
+		t.Error("NowIn(Mars/Olympus) succeeded, want an error")
+	}
+}
@@ -0,0 +1,258 @@
+// Package actionaudit keeps the append-only audit log of the actions of the
+// Geek and Evolution Mode sessions, which run arbitrary commands.
+//
+// Every tool the CLI of a session calls is recorded once it completes: its
+// user, mode and session, the tool and a summary of its input, the files the
+// input names, and its exit status (success, error, blocked by the tool
+// policy, or unfinished when the session ended first). Records are never
+// updated nor deleted; admins query and export them.
+package actionaudit
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+)
+
+var (
+	// ErrInvalid is returned for queries and export formats that fail validation.
+	ErrInvalid = errors.New("invalid audit query")
+)
+
+// Modes are the modes whose actions are audited.
+var Modes = []string{"geek", "evolution"}
+
+// Statuses are the exit statuses of the recorded tools.
+var Statuses = []string{agentpkg.ToolUseSuccess, agentpkg.ToolUseError, agentpkg.ToolUseBlocked, agentpkg.ToolUseUnfinished}
+
+// Export formats.
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+const (
+	// defaultListLimit and maxListLimit bound the records of a list.
+	defaultListLimit = 100
+	maxListLimit     = 500
+	// maxExportRecords is the number of records an export holds at most.
+	maxExportRecords = 100000
+	// maxInputLength and maxOutputLength truncate the summaries, in runes.
+	maxInputLength  = 2000
+	maxOutputLength = 1000
+	// maxFilePaths is the number of paths a record keeps.
+	maxFilePaths = 50
+)
+
+// Record is an audited tool call.
+type Record struct {
+	ID        int64  `json:"id"`
+	UserID    int32  `json:"user_id"`
+	Mode      string `json:"mode"`
+	SessionID string `json:"session_id"`
+	Tool      string `json:"tool"`
+	ToolID    string `json:"tool_id,omitempty"`
+	// Input is the summary of the input of the tool, e.g. the command of Bash.
+	Input     string   `json:"input"`
+	FilePaths []string `json:"file_paths"`
+	Status    string   `json:"status"`
+	// Output is the summary of the output, the error for failed and blocked tools.
+	Output     string `json:"output,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	CreatedTs  int64  `json:"created_ts"`
+}
+
+// Find selects records; zero fields select all.
+type Find struct {
+	UserID    int32
+	Mode      string
+	SessionID string
+	Tool      string
+	Status    string
+	// From and To bound the creation time, in Unix seconds, inclusive.
+	From int64
+	To   int64
+	// BeforeID pages through the log: only records older than it are returned.
+	BeforeID int64
+	Limit    int
+}
+
+// Store persists the records.
+type Store interface {
+	AppendRecord(ctx context.Context, record *Record) (*Record, error)
+	// ListRecords returns the records matching find, latest first.
+	ListRecords(ctx context.Context, find *Find) ([]*Record, error)
+}
+
+// Audit is the audit log.
+type Audit struct {
+	store Store
+	now   func() time.Time
+}
+
+// New creates the audit log.
+func New(store Store) *Audit {
+	return &Audit{store: store, now: time.Now}
+}
+
+// Record appends a tool call to the log.
+func (a *Audit) Record(ctx context.Context, use *agentpkg.ToolUse) (*Record, error) {
+	paths := use.FilePaths
+	if len(paths) > maxFilePaths {
+		paths = paths[:maxFilePaths]
+	}
+	return a.store.AppendRecord(ctx, &Record{
+		UserID:     use.UserID,
+		Mode:       use.Mode,
+		SessionID:  use.SessionID,
+		Tool:       use.Tool,
+		ToolID:     use.ToolID,
+		Input:      truncate(use.Input, maxInputLength),
+		FilePaths:  append([]string{}, paths...),
+		Status:     use.Status,
+		Output:     truncate(use.Output, maxOutputLength),
+		DurationMs: use.DurationMs,
+		CreatedTs:  a.now().Unix(),
+	})
+}
+
+// Handler returns the handler recording the tool calls of the runners, nil
+// for a nil audit log. Records outlive the request of the session; failures
+// are logged.
+func (a *Audit) Handler() agentpkg.ToolUseHandler {
+	if a == nil {
+		return nil
+	}
+	return func(ctx context.Context, use *agentpkg.ToolUse) {
+		if _, err := a.Record(context.WithoutCancel(ctx), use); err != nil {
+			slog.Error("failed to audit tool call",
+				"user_id", use.UserID, "session_id", use.SessionID, "tool", use.Tool, "error", err)
+		}
+	}
+}
+
+// List returns the records matching find, latest first.
+func (a *Audit) List(ctx context.Context, find *Find) ([]*Record, error) {
+	if err := find.Validate(); err != nil {
+		return nil, err
+	}
+	if find.Limit <= 0 {
+		find.Limit = defaultListLimit
+	}
+	find.Limit = min(find.Limit, maxListLimit)
+	return a.store.ListRecords(ctx, find)
+}
+
+// Export writes the records matching find, latest first, as CSV or JSON
+// Lines, at most maxExportRecords of them.
+func (a *Audit) Export(ctx context.Context, w io.Writer, find *Find, format string) error {
+	if format != FormatCSV && format != FormatJSONL {
+		return fmt.Errorf("%w: format must be %s or %s", ErrInvalid, FormatCSV, FormatJSONL)
+	}
+	if err := find.Validate(); err != nil {
+		return err
+	}
+	write := newJSONLWriter(w)
+	if format == FormatCSV {
+		write = newCSVWriter(w)
+	}
+
+	page := *find
+	for exported := 0; exported < maxExportRecords; {
+		page.Limit = min(maxListLimit, maxExportRecords-exported)
+		records, err := a.store.ListRecords(ctx, &page)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := write(record); err != nil {
+				return fmt.Errorf("failed to write audit record: %w", err)
+			}
+		}
+		exported += len(records)
+		if len(records) < page.Limit {
+			break
+		}
+		page.BeforeID = records[len(records)-1].ID
+	}
+	return write(nil)
+}
+
+// recordWriter writes a record, and flushes the output on nil.
+type recordWriter func(record *Record) error
+
+func newJSONLWriter(w io.Writer) recordWriter {
+	encoder := json.NewEncoder(w)
+	return func(record *Record) error {
+		if record == nil {
+			return nil
+		}
+		return encoder.Encode(record)
+	}
+}
+
+// csvHeader are the columns of the CSV exports.
+var csvHeader = []string{"id", "created_at", "user_id", "mode", "session_id", "tool", "tool_id", "status", "duration_ms", "input", "file_paths", "output"}
+
+func newCSVWriter(w io.Writer) recordWriter {
+	writer := csv.NewWriter(w)
+	header := false
+	return func(record *Record) error {
+		if !header {
+			header = true
+			if err := writer.Write(csvHeader); err != nil {
+				return err
+			}
+		}
+		if record == nil {
+			writer.Flush()
+			return writer.Error()
+		}
+		return writer.Write([]string{
+			strconv.FormatInt(record.ID, 10),
+			time.Unix(record.CreatedTs, 0).UTC().Format(time.RFC3339),
+			strconv.FormatInt(int64(record.UserID), 10),
+			record.Mode,
+			record.SessionID,
+			record.Tool,
+			record.ToolID,
+			record.Status,
+			strconv.FormatInt(record.DurationMs, 10),
+			record.Input,
+			strings.Join(record.FilePaths, ";"),
+			record.Output,
+		})
+	}
+}
+
+// Validate checks the filters of a query.
+func (f *Find) Validate() error {
+	if f.Mode != "" && !slices.Contains(Modes, f.Mode) {
+		return fmt.Errorf("%w: mode must be one of %s", ErrInvalid, strings.Join(Modes, ", "))
+	}
+	if f.Status != "" && !slices.Contains(Statuses, f.Status) {
+		return fmt.Errorf("%w: status must be one of %s", ErrInvalid, strings.Join(Statuses, ", "))
+	}
+	if f.From > 0 && f.To > 0 && f.From > f.To {
+		return fmt.Errorf("%w: from is after to", ErrInvalid)
+	}
+	return nil
+}
+
+// truncate truncates s to n runes.
+func truncate(s string, n int) string {
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,123 @@
+package actionaudit
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+)
+
+type fakeStore struct {
+	records []*Record
+	lists   int
+}
+
+func (s *fakeStore) AppendRecord(_ context.Context, record *Record) (*Record, error) {
+	appended := *record
+	appended.ID = int64(len(s.records) + 1)
+	s.records = append(s.records, &appended)
+	return &appended, nil
+}
+
+func (s *fakeStore) ListRecords(_ context.Context, find *Find) ([]*Record, error) {
+	s.lists++
+	records := []*Record{}
+	for i := len(s.records) - 1; i >= 0 && len(records) < find.Limit; i-- {
+		record := s.records[i]
+		if (find.UserID == 0 || record.UserID == find.UserID) &&
+			(find.Status == "" || record.Status == find.Status) &&
+			(find.BeforeID == 0 || record.ID < find.BeforeID) {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func newTestAudit() (*Audit, *fakeStore) {
+	store := &fakeStore{}
+	audit := New(store)
+	audit.now = func() time.Time { return time.Unix(1700000000, 0) }
+	return audit, store
+}
+
+func TestAudit_Handler(t *testing.T) {
+	audit, store := newTestAudit()
+	handler := audit.Handler()
+	handler(context.Background(), &agentpkg.ToolUse{
+		SessionID: "s1", UserID: 7, Mode: "geek", Tool: "Bash", ToolID: "t1",
+		Input: strings.Repeat("x", maxInputLength+10), Status: agentpkg.ToolUseError, Output: "exit 1",
+	})
+
+	require.Len(t, store.records, 1)
+	record := store.records[0]
+	assert.Equal(t, int32(7), record.UserID)
+	assert.Equal(t, "geek", record.Mode)
+	assert.Equal(t, agentpkg.ToolUseError, record.Status)
+	assert.Equal(t, []string{}, record.FilePaths)
+	assert.Equal(t, int64(1700000000), record.CreatedTs)
+	assert.Len(t, []rune(record.Input), maxInputLength+1)
+
+	var nilAudit *Audit
+	assert.Nil(t, nilAudit.Handler())
+}
+
+func TestAudit_List(t *testing.T) {
+	audit, store := newTestAudit()
+	for i := 0; i < 3; i++ {
+		_, err := audit.Record(context.Background(), &agentpkg.ToolUse{UserID: 1, Mode: "geek", Tool: "Read", Status: agentpkg.ToolUseSuccess})
+		require.NoError(t, err)
+	}
+	_, err := audit.Record(context.Background(), &agentpkg.ToolUse{UserID: 2, Mode: "evolution", Tool: "Bash", Status: agentpkg.ToolUseBlocked})
+	require.NoError(t, err)
+	require.Len(t, store.records, 4)
+
+	records, err := audit.List(context.Background(), &Find{Status: agentpkg.ToolUseSuccess, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, int64(3), records[0].ID)
+
+	_, err = audit.List(context.Background(), &Find{Mode: "normal"})
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = audit.List(context.Background(), &Find{Status: "ok"})
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = audit.List(context.Background(), &Find{From: 2, To: 1})
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestAudit_Export(t *testing.T) {
+	audit, store := newTestAudit()
+	for i := 0; i < maxListLimit+5; i++ {
+		_, err := audit.Record(context.Background(), &agentpkg.ToolUse{
+			UserID: 1, Mode: "geek", SessionID: "s1", Tool: "Write", Status: agentpkg.ToolUseSuccess,
+			Input: "map[file_path:/work/a.go]", FilePaths: []string{"/work/a.go", "/work/b.go"},
+		})
+		require.NoError(t, err)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, audit.Export(context.Background(), &buf, &Find{UserID: 1}, FormatCSV))
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, maxListLimit+6)
+	assert.Equal(t, csvHeader, rows[0])
+	assert.Equal(t, []string{"505", "2023-11-14T22:13:20Z", "1", "geek", "s1", "Write", "", "success", "0",
+		"map[file_path:/work/a.go]", "/work/a.go;/work/b.go", ""}, rows[1])
+	assert.Equal(t, "1", rows[len(rows)-1][0])
+	assert.Equal(t, 2, store.lists, "exports page through the log")
+
+	buf.Reset()
+	require.NoError(t, audit.Export(context.Background(), &buf, &Find{UserID: 2}, FormatJSONL))
+	assert.Empty(t, buf.String())
+	require.NoError(t, audit.Export(context.Background(), &buf, &Find{UserID: 1, Limit: 1}, FormatJSONL))
+	assert.Equal(t, maxListLimit+5, strings.Count(buf.String(), "\n"))
+	assert.Contains(t, buf.String(), `"file_paths":["/work/a.go","/work/b.go"]`)
+
+	assert.ErrorIs(t, audit.Export(context.Background(), &buf, &Find{}, "xml"), ErrInvalid)
+}
@@ -0,0 +1,108 @@
+package actionaudit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// DBStore persists the records in the append-only action_audit table
+// (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new audit store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const recordColumns = "id, user_id, mode, session_id, tool, tool_id, input, file_paths, status, output, duration_ms, created_ts"
+
+// AppendRecord implements Store.
+func (s *DBStore) AppendRecord(ctx context.Context, record *Record) (*Record, error) {
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO action_audit (user_id, mode, session_id, tool, tool_id, input, file_paths, status, output, duration_ms, created_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING `+recordColumns,
+		record.UserID, record.Mode, record.SessionID, record.Tool, record.ToolID, record.Input,
+		pq.Array(record.FilePaths), record.Status, record.Output, record.DurationMs, record.CreatedTs)
+	appended, err := scanRecord(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to append audit record: %w", err)
+	}
+	return appended, nil
+}
+
+// ListRecords implements Store.
+func (s *DBStore) ListRecords(ctx context.Context, find *Find) ([]*Record, error) {
+	where, args := []string{"TRUE"}, []any{}
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(condition, len(args)))
+	}
+	if find.UserID != 0 {
+		add("user_id = $%d", find.UserID)
+	}
+	if find.Mode != "" {
+		add("mode = $%d", find.Mode)
+	}
+	if find.SessionID != "" {
+		add("session_id = $%d", find.SessionID)
+	}
+	if find.Tool != "" {
+		add("tool = $%d", find.Tool)
+	}
+	if find.Status != "" {
+		add("status = $%d", find.Status)
+	}
+	if find.From > 0 {
+		add("created_ts >= $%d", find.From)
+	}
+	if find.To > 0 {
+		add("created_ts <= $%d", find.To)
+	}
+	if find.BeforeID > 0 {
+		add("id < $%d", find.BeforeID)
+	}
+	args = append(args, find.Limit)
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(
+		"SELECT %s FROM action_audit WHERE %s ORDER BY id DESC LIMIT $%d",
+		recordColumns, strings.Join(where, " AND "), len(args)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit records: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*Record{}
+	for rows.Next() {
+		record, err := scanRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit record: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list audit records: %w", err)
+	}
+	return records, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRecord(row rowScanner) (*Record, error) {
+	record := &Record{}
+	if err := row.Scan(&record.ID, &record.UserID, &record.Mode, &record.SessionID, &record.Tool, &record.ToolID,
+		&record.Input, pq.Array(&record.FilePaths), &record.Status, &record.Output, &record.DurationMs, &record.CreatedTs); err != nil {
+		return nil, err
+	}
+	if record.FilePaths == nil {
+		record.FilePaths = []string{}
+	}
+	return record, nil
+}
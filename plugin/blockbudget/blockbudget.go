@@ -0,0 +1,154 @@
+// Package blockbudget holds the cost guardrail of chat blocks: a maximum of
+// tokens and estimated cost a single block (round) may use.
+//
+// Users set a default budget for their blocks, and clients can tighten it for
+// one request. When the cumulative usage of a block crosses its budget, the
+// server stops the generation and the tools, and ends the block with what was
+// produced so far.
+package blockbudget
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Budget limits the usage of a block; zero fields are unlimited.
+type Budget struct {
+	MaxTokens  int     `json:"max_tokens"`
+	MaxCostUSD float64 `json:"max_cost_usd"`
+}
+
+// Validate checks a budget about to be saved.
+func (b *Budget) Validate() error {
+	if b.MaxTokens < 0 {
+		return fmt.Errorf("max_tokens must not be negative")
+	}
+	if b.MaxCostUSD < 0 || math.IsNaN(b.MaxCostUSD) || math.IsInf(b.MaxCostUSD, 0) {
+		return fmt.Errorf("max_cost_usd must be a non-negative number")
+	}
+	return nil
+}
+
+// Limited reports whether the budget limits anything; nil budgets do not.
+func (b *Budget) Limited() bool {
+	return b != nil && (b.MaxTokens > 0 || b.MaxCostUSD > 0)
+}
+
+// Exceeded reports whether usage crossed the budget.
+func (b *Budget) Exceeded(tokens int, costUSD float64) bool {
+	if b == nil {
+		return false
+	}
+	return (b.MaxTokens > 0 && tokens > b.MaxTokens) || (b.MaxCostUSD > 0 && costUSD > b.MaxCostUSD)
+}
+
+// Tighter returns the stricter of two budgets, limit by limit. Either may be nil.
+func Tighter(a, b *Budget) *Budget {
+	if !a.Limited() {
+		return b
+	}
+	if !b.Limited() {
+		return a
+	}
+	return &Budget{
+		MaxTokens:  tighter(a.MaxTokens, b.MaxTokens),
+		MaxCostUSD: tighter(a.MaxCostUSD, b.MaxCostUSD),
+	}
+}
+
+func tighter[T int | float64](a, b T) T {
+	switch {
+	case a == 0:
+		return b
+	case b == 0:
+		return a
+	}
+	return min(a, b)
+}
+
+// Parse reads the budget of a request from its max tokens and max cost (USD)
+// values, either of which may be empty. It returns nil if both are.
+func Parse(maxTokens, maxCostUSD string) (*Budget, error) {
+	if maxTokens == "" && maxCostUSD == "" {
+		return nil, nil
+	}
+	budget := &Budget{}
+	var err error
+	if maxTokens != "" {
+		if budget.MaxTokens, err = strconv.Atoi(maxTokens); err != nil {
+			return nil, fmt.Errorf("max tokens must be an integer")
+		}
+	}
+	if maxCostUSD != "" {
+		if budget.MaxCostUSD, err = strconv.ParseFloat(maxCostUSD, 64); err != nil {
+			return nil, fmt.Errorf("max cost must be a number")
+		}
+	}
+	if err := budget.Validate(); err != nil {
+		return nil, err
+	}
+	return budget, nil
+}
+
+type requestBudgetKey struct{}
+
+// WithRequestBudget returns a context carrying the budget of a chat request.
+func WithRequestBudget(ctx context.Context, budget *Budget) context.Context {
+	return context.WithValue(ctx, requestBudgetKey{}, budget)
+}
+
+// RequestBudget returns the budget of the chat request of ctx, or nil.
+func RequestBudget(ctx context.Context) *Budget {
+	budget, _ := ctx.Value(requestBudgetKey{}).(*Budget)
+	return budget
+}
+
+// Store persists the default budgets of users.
+type Store interface {
+	// GetBudget returns the default budget of a user; zero if none was saved.
+	GetBudget(ctx context.Context, userID int32) (*Budget, error)
+	SaveBudget(ctx context.Context, userID int32, budget *Budget) (*Budget, error)
+}
+
+// Budgets serves the budgets of blocks.
+type Budgets struct {
+	store Store
+}
+
+// New creates the block budgets.
+func New(store Store) *Budgets {
+	return &Budgets{store: store}
+}
+
+// GetBudget returns the default budget of a user.
+func (b *Budgets) GetBudget(ctx context.Context, userID int32) (*Budget, error) {
+	return b.store.GetBudget(ctx, userID)
+}
+
+// SaveBudget validates and saves the default budget of a user.
+func (b *Budgets) SaveBudget(ctx context.Context, userID int32, budget *Budget) (*Budget, error) {
+	if err := budget.Validate(); err != nil {
+		return nil, err
+	}
+	return b.store.SaveBudget(ctx, userID, budget)
+}
+
+// ForRequest returns the budget of a block of a user: the user's default,
+// tightened by the budget of the request. It is nil if nothing is limited.
+// Budgets are optional: a nil Budgets only applies the request budget.
+func (b *Budgets) ForRequest(ctx context.Context, userID int32, request *Budget) (*Budget, error) {
+	var user *Budget
+	if b != nil {
+		var err error
+		if user, err = b.store.GetBudget(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	budget := Tighter(user, request)
+	if !budget.Limited() {
+		return nil, nil
+	}
+	return budget, nil
+}
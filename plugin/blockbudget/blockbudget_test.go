@@ -0,0 +1,91 @@
+package blockbudget
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTighter(t *testing.T) {
+	user := &Budget{MaxTokens: 20000, MaxCostUSD: 0.05}
+	assert.Equal(t, user, Tighter(user, nil))
+	assert.Equal(t, user, Tighter(&Budget{}, user))
+	assert.Equal(t, &Budget{MaxTokens: 5000, MaxCostUSD: 0.05}, Tighter(user, &Budget{MaxTokens: 5000}))
+	assert.Equal(t, &Budget{MaxTokens: 20000, MaxCostUSD: 0.01}, Tighter(user, &Budget{MaxTokens: 90000, MaxCostUSD: 0.01}))
+	assert.Nil(t, Tighter(nil, nil))
+}
+
+func TestExceeded(t *testing.T) {
+	budget := &Budget{MaxTokens: 1000, MaxCostUSD: 0.01}
+	assert.False(t, budget.Exceeded(1000, 0.01))
+	assert.True(t, budget.Exceeded(1001, 0))
+	assert.True(t, budget.Exceeded(10, 0.011))
+	assert.False(t, (&Budget{}).Exceeded(1_000_000, 100))
+	assert.False(t, (*Budget)(nil).Exceeded(1_000_000, 100))
+}
+
+func TestParse(t *testing.T) {
+	budget, err := Parse("", "")
+	require.NoError(t, err)
+	assert.Nil(t, budget)
+
+	budget, err = Parse("5000", "")
+	require.NoError(t, err)
+	assert.Equal(t, &Budget{MaxTokens: 5000}, budget)
+
+	budget, err = Parse("", "0.02")
+	require.NoError(t, err)
+	assert.Equal(t, &Budget{MaxCostUSD: 0.02}, budget)
+
+	for _, invalid := range [][2]string{{"many", ""}, {"-1", ""}, {"", "cheap"}, {"", "-0.5"}, {"", "NaN"}} {
+		_, err := Parse(invalid[0], invalid[1])
+		assert.Error(t, err, "%v", invalid)
+	}
+}
+
+type fakeStore struct {
+	budgets map[int32]*Budget
+}
+
+func (s *fakeStore) GetBudget(_ context.Context, userID int32) (*Budget, error) {
+	if budget, ok := s.budgets[userID]; ok {
+		return budget, nil
+	}
+	return &Budget{}, nil
+}
+
+func (s *fakeStore) SaveBudget(_ context.Context, userID int32, budget *Budget) (*Budget, error) {
+	s.budgets[userID] = budget
+	return budget, nil
+}
+
+func TestForRequest(t *testing.T) {
+	ctx := context.Background()
+	budgets := New(&fakeStore{budgets: map[int32]*Budget{}})
+
+	// Nothing is limited until the user or the request sets a budget.
+	budget, err := budgets.ForRequest(ctx, 1, nil)
+	require.NoError(t, err)
+	assert.Nil(t, budget)
+
+	_, err = budgets.SaveBudget(ctx, 1, &Budget{MaxTokens: -5})
+	require.Error(t, err)
+	_, err = budgets.SaveBudget(ctx, 1, &Budget{MaxCostUSD: 0.05})
+	require.NoError(t, err)
+
+	budget, err = budgets.ForRequest(ctx, 1, &Budget{MaxTokens: 3000, MaxCostUSD: 0.5})
+	require.NoError(t, err)
+	assert.Equal(t, &Budget{MaxTokens: 3000, MaxCostUSD: 0.05}, budget)
+
+	// Without stored budgets, only the request budget applies.
+	var none *Budgets
+	budget, err = none.ForRequest(ctx, 1, &Budget{MaxTokens: 3000})
+	require.NoError(t, err)
+	assert.Equal(t, &Budget{MaxTokens: 3000}, budget)
+
+	ctx = WithRequestBudget(ctx, budget)
+	assert.Equal(t, budget, RequestBudget(ctx))
+	assert.Nil(t, RequestBudget(context.Background()))
+}
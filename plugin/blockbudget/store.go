@@ -0,0 +1,52 @@
+package blockbudget
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DBStore persists default budgets in the user_block_budget table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new block budget store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// GetBudget implements Store.
+func (s *DBStore) GetBudget(ctx context.Context, userID int32) (*Budget, error) {
+	budget := &Budget{}
+	err := s.db.QueryRowContext(ctx,
+		"SELECT max_tokens, max_cost_usd FROM user_block_budget WHERE user_id = $1", userID,
+	).Scan(&budget.MaxTokens, &budget.MaxCostUSD)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &Budget{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block budget: %w", err)
+	}
+	return budget, nil
+}
+
+// SaveBudget implements Store.
+func (s *DBStore) SaveBudget(ctx context.Context, userID int32, budget *Budget) (*Budget, error) {
+	saved := &Budget{}
+	if err := s.db.QueryRowContext(ctx, `
+		INSERT INTO user_block_budget (user_id, max_tokens, max_cost_usd, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (user_id) DO UPDATE SET
+			max_tokens = EXCLUDED.max_tokens,
+			max_cost_usd = EXCLUDED.max_cost_usd,
+			updated_ts = EXCLUDED.updated_ts
+		RETURNING max_tokens, max_cost_usd`,
+		userID, budget.MaxTokens, budget.MaxCostUSD, time.Now().Unix(),
+	).Scan(&saved.MaxTokens, &saved.MaxCostUSD); err != nil {
+		return nil, fmt.Errorf("failed to save block budget: %w", err)
+	}
+	return saved, nil
+}
@@ -0,0 +1,208 @@
+package calsync
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hrygo/divinesense/plugin/netguard"
+)
+
+const (
+	// requestTimeout bounds the requests to CalDAV servers.
+	requestTimeout = 30 * time.Second
+	// maxResponseBytes bounds the responses of CalDAV servers.
+	maxResponseBytes = 20 << 20
+	// maxEvents bounds the events of a synced calendar.
+	maxEvents = 5000
+)
+
+// client talks to the calendar collection of an account.
+type client struct {
+	http     *http.Client
+	base     *url.URL
+	username string
+	password string
+}
+
+// remoteEvent is an event of a calendar collection.
+type remoteEvent struct {
+	Href string
+	ETag string
+	Data string
+}
+
+func newClient(account *Account, password string) *client {
+	base, _ := url.Parse(account.URL)
+	return &client{
+		http:     &http.Client{Transport: guardedTransport(account.AllowPrivate), Timeout: requestTimeout},
+		base:     base,
+		username: account.Username,
+		password: password,
+	}
+}
+
+// href returns the path of the event of a UID in the calendar.
+func (c *client) href(uid string) string {
+	return strings.TrimRight(c.base.Path, "/") + "/" + url.PathEscape(uid) + ".ics"
+}
+
+// check verifies that the URL is a calendar the account can read.
+func (c *client) check(ctx context.Context) error {
+	body := `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:resourcetype/></d:prop></d:propfind>`
+	resp, err := c.do(ctx, "PROPFIND", c.base.Path, body, map[string]string{"Depth": "0"})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return fmt.Errorf("the server answered HTTP %d", resp.StatusCode)
+	}
+	var multistatus struct {
+		Responses []struct {
+			ResourceTypes []struct {
+				Calendar *struct{} `xml:"urn:ietf:params:xml:ns:caldav calendar"`
+			} `xml:"propstat>prop>resourcetype"`
+		} `xml:"response"`
+	}
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&multistatus); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	for _, response := range multistatus.Responses {
+		for _, resourceType := range response.ResourceTypes {
+			if resourceType.Calendar != nil {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("the URL is not a calendar collection")
+}
+
+// list returns the events of the calendar.
+func (c *client) list(ctx context.Context) ([]*remoteEvent, error) {
+	body := `<?xml version="1.0" encoding="utf-8"?>
+<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop><d:getetag/><c:calendar-data/></d:prop>
+  <c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="VEVENT"/></c:comp-filter></c:filter>
+</c:calendar-query>`
+	resp, err := c.do(ctx, "REPORT", c.base.Path, body, map[string]string{"Depth": "1"})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("failed to list events: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	if len(data) > maxResponseBytes {
+		return nil, fmt.Errorf("the calendar exceeds %d MiB, use a dedicated calendar", maxResponseBytes>>20)
+	}
+	var multistatus struct {
+		Responses []struct {
+			Href     string `xml:"href"`
+			Propstat []struct {
+				Status string `xml:"status"`
+				ETag   string `xml:"prop>getetag"`
+				Data   string `xml:"prop>calendar-data"`
+			} `xml:"propstat"`
+		} `xml:"response"`
+	}
+	if err := xml.Unmarshal(data, &multistatus); err != nil {
+		return nil, fmt.Errorf("invalid events: %w", err)
+	}
+	events := []*remoteEvent{}
+	for _, response := range multistatus.Responses {
+		for _, propstat := range response.Propstat {
+			if propstat.Data == "" || (propstat.Status != "" && !strings.Contains(propstat.Status, " 200")) {
+				continue
+			}
+			href := response.Href
+			if u, err := url.Parse(href); err == nil {
+				href = u.Path
+			}
+			events = append(events, &remoteEvent{Href: href, ETag: propstat.ETag, Data: propstat.Data})
+		}
+	}
+	if len(events) > maxEvents {
+		return nil, fmt.Errorf("the calendar has more than %d events, use a dedicated calendar", maxEvents)
+	}
+	return events, nil
+}
+
+// put writes an event and returns its new ETag, empty when the server does
+// not answer it; create fails when the event exists.
+func (c *client) put(ctx context.Context, href string, data []byte, create bool) (string, error) {
+	headers := map[string]string{"Content-Type": "text/calendar; charset=utf-8"}
+	if create {
+		headers["If-None-Match"] = "*"
+	}
+	resp, err := c.do(ctx, http.MethodPut, href, string(data), headers)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("failed to write event %s: HTTP %d", href, resp.StatusCode)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// delete deletes an event; events already deleted are fine.
+func (c *client) delete(ctx context.Context, href string) error {
+	resp, err := c.do(ctx, http.MethodDelete, href, "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to delete event %s: HTTP %d", href, resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *client) do(ctx context.Context, method, path, body string, headers map[string]string) (*http.Response, error) {
+	target := *c.base
+	target.Path, target.RawPath = path, ""
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(c.username, c.password)
+	if body != "" && method != http.MethodPut {
+		req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the calendar: %w", err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		resp.Body.Close()
+		return nil, fmt.Errorf("the calendar rejected the username or password (HTTP %d)", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// guardedTransport dials public addresses only unless allowPrivate, so that
+// users cannot reach the private network of the server through their
+// calendars.
+func guardedTransport(allowPrivate bool) *http.Transport {
+	if allowPrivate {
+		return http.DefaultTransport.(*http.Transport).Clone()
+	}
+	return netguard.Transport(requestTimeout, ErrDenied)
+}
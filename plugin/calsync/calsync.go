@@ -0,0 +1,552 @@
+// Package calsync shares the schedules of users with calendar apps.
+//
+// Each user may create a secret iCalendar feed URL, read-only, which Apple,
+// Google or Outlook calendars subscribe to. Users may also connect a CalDAV
+// calendar, preferably a dedicated one: the sync job pushes their schedules
+// to it and saves the events created, changed or deleted in the calendar app
+// back to their schedules. When a schedule and its event both changed since
+// the last sync, the schedule wins.
+package calsync
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hrygo/divinesense/internal/util"
+	chatstore "github.com/hrygo/divinesense/plugin/chat_apps/store"
+	"github.com/hrygo/divinesense/store"
+)
+
+const (
+	// FeedPath is the path of the iCalendar feeds, followed by
+	// "<token>.ics".
+	FeedPath = "/api/v1/calendar/"
+	// DefaultTimezone is the time zone of events without one, the schedule
+	// default.
+	DefaultTimezone = "Asia/Shanghai"
+	// maxFeedSchedules bounds the schedules of a feed and of a sync.
+	maxFeedSchedules = 5000
+	// syncWindow is how far back schedules are pushed to a calendar on their
+	// first sync; older ones stay out of it.
+	syncWindow = 30 * 24 * time.Hour
+	// maxWrites bounds the events written by a sync; the next sync goes on.
+	maxWrites = 200
+	// maxTitleRunes bounds the titles of schedules of events.
+	maxTitleRunes = 200
+	// maxErrorRunes bounds the sync errors kept on accounts.
+	maxErrorRunes = 500
+)
+
+var (
+	// ErrInvalid is returned for invalid accounts and calendars.
+	ErrInvalid = errors.New("invalid calendar")
+	// ErrNotFound is returned for users without a feed or a CalDAV account,
+	// and for unknown feed tokens.
+	ErrNotFound = errors.New("calendar not found")
+	// ErrDisabled is returned for CalDAV accounts when the instance has no
+	// secret key.
+	ErrDisabled = errors.New("CalDAV sync is not enabled on this instance")
+	// ErrDenied is returned when connecting to private addresses.
+	ErrDenied = errors.New("address not allowed")
+)
+
+// Feed is the secret iCalendar feed of a user.
+type Feed struct {
+	UserID    int32  `json:"-"`
+	Token     string `json:"-"`
+	URL       string `json:"url"`
+	CreatedTs int64  `json:"created_ts"`
+}
+
+// Account is the CalDAV calendar of a user.
+type Account struct {
+	UserID int32 `json:"-"`
+	// URL is the calendar collection, e.g.
+	// https://caldav.fastmail.com/dav/calendars/user/me@fastmail.com/Default/
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	// Password is only set in requests.
+	Password          string `json:"password,omitempty"`
+	PasswordEncrypted string `json:"-"`
+	// Timezone is the time zone of the events without one.
+	Timezone     string `json:"timezone"`
+	AllowPrivate bool   `json:"-"`
+	LastSyncTs   int64  `json:"last_sync_ts"`
+	LastError    string `json:"last_error"`
+	UpdatedTs    int64  `json:"updated_ts"`
+}
+
+// Link is a schedule synced with an event of a CalDAV calendar.
+type Link struct {
+	UserID     int32
+	ScheduleID int32
+	// UID is the iCalendar UID of the event.
+	UID  string
+	Href string
+	ETag string
+	// UpdatedTs is the update time of the schedule at the last sync.
+	UpdatedTs int64
+}
+
+// SyncResult counts the changes of a sync.
+type SyncResult struct {
+	Pushed  int `json:"pushed"`
+	Pulled  int `json:"pulled"`
+	Deleted int `json:"deleted"`
+}
+
+// Store persists the feeds, the accounts and the links.
+type Store interface {
+	GetFeed(ctx context.Context, userID int32) (*Feed, error)
+	GetFeedByToken(ctx context.Context, token string) (*Feed, error)
+	// SaveFeed sets the token of the feed of a user, creating it.
+	SaveFeed(ctx context.Context, userID int32, token string) (*Feed, error)
+	DeleteFeed(ctx context.Context, userID int32) error
+
+	GetAccount(ctx context.Context, userID int32) (*Account, error)
+	ListAccounts(ctx context.Context) ([]*Account, error)
+	SaveAccount(ctx context.Context, account *Account) (*Account, error)
+	SaveSyncState(ctx context.Context, userID int32, lastSyncTs int64, lastError string) error
+	// DeleteAccount deletes the account of a user with their links.
+	DeleteAccount(ctx context.Context, userID int32) error
+
+	ListLinks(ctx context.Context, userID int32) ([]*Link, error)
+	SaveLink(ctx context.Context, link *Link) error
+	DeleteLink(ctx context.Context, userID int32, uid string) error
+}
+
+// Config configures the calendars.
+type Config struct {
+	// InstanceURL is the public URL of the instance, prefixing the feed URLs.
+	InstanceURL string
+	// SecretKey encrypts the passwords of the CalDAV accounts; it must be 32
+	// bytes. CalDAV sync is disabled without it.
+	SecretKey string
+}
+
+// Calendars serves the feeds and syncs the CalDAV accounts.
+type Calendars struct {
+	config    Config
+	store     Store
+	schedules store.ScheduleService
+	// client creates the CalDAV client of an account; tests replace it.
+	client func(account *Account, password string) *client
+}
+
+// New creates the calendars.
+func New(config Config, calendarStore Store, schedules store.ScheduleService) (*Calendars, error) {
+	if config.SecretKey != "" && len(config.SecretKey) != 32 {
+		return nil, fmt.Errorf("%w: the secret key must be 32 bytes", ErrInvalid)
+	}
+	config.InstanceURL = strings.TrimRight(config.InstanceURL, "/")
+	return &Calendars{config: config, store: calendarStore, schedules: schedules, client: newClient}, nil
+}
+
+// CalDAVEnabled reports whether CalDAV accounts may be connected.
+func (c *Calendars) CalDAVEnabled() bool {
+	return c.config.SecretKey != ""
+}
+
+// Feed returns the feed of a user.
+func (c *Calendars) Feed(ctx context.Context, userID int32) (*Feed, error) {
+	feed, err := c.store.GetFeed(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return c.withURL(feed), nil
+}
+
+// RotateFeed gives a user a new feed URL, creating their feed. Calendars
+// subscribed to the previous URL stop updating.
+func (c *Calendars) RotateFeed(ctx context.Context, userID int32) (*Feed, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	feed, err := c.store.SaveFeed(ctx, userID, hex.EncodeToString(b))
+	if err != nil {
+		return nil, err
+	}
+	return c.withURL(feed), nil
+}
+
+// DeleteFeed deletes the feed of a user.
+func (c *Calendars) DeleteFeed(ctx context.Context, userID int32) error {
+	return c.store.DeleteFeed(ctx, userID)
+}
+
+// ResolveFeed returns the feed of a token.
+func (c *Calendars) ResolveFeed(ctx context.Context, token string) (*Feed, error) {
+	if token == "" {
+		return nil, ErrNotFound
+	}
+	return c.store.GetFeedByToken(ctx, token)
+}
+
+// Calendar returns the iCalendar document of the schedules of a user.
+func (c *Calendars) Calendar(ctx context.Context, userID int32) ([]byte, error) {
+	schedules, err := c.listSchedules(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeCalendar("DivineSense", schedules, time.Now()), nil
+}
+
+func (c *Calendars) withURL(feed *Feed) *Feed {
+	feed.URL = c.config.InstanceURL + FeedPath + feed.Token + ".ics"
+	return feed
+}
+
+// Account returns the CalDAV account of a user, without its password.
+func (c *Calendars) Account(ctx context.Context, userID int32) (*Account, error) {
+	account, err := c.store.GetAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return redact(account), nil
+}
+
+// SaveAccount connects the CalDAV calendar of a user once it answers; an
+// empty password keeps the saved one. Calendars on private addresses are
+// only reachable when allowPrivate.
+func (c *Calendars) SaveAccount(ctx context.Context, userID int32, account *Account, allowPrivate bool) (*Account, error) {
+	if !c.CalDAVEnabled() {
+		return nil, ErrDisabled
+	}
+	account.UserID = userID
+	account.URL = strings.TrimSpace(account.URL)
+	account.Username = strings.TrimSpace(account.Username)
+	account.Timezone = strings.TrimSpace(account.Timezone)
+	account.AllowPrivate = allowPrivate
+	u, err := url.Parse(account.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || u.User != nil {
+		return nil, fmt.Errorf("%w: the URL must be the http(s) URL of a calendar", ErrInvalid)
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	account.URL = u.String()
+	if account.Username == "" {
+		return nil, fmt.Errorf("%w: a username is required", ErrInvalid)
+	}
+	if account.Timezone == "" {
+		account.Timezone = DefaultTimezone
+	}
+	if location(account.Timezone, nil) == nil {
+		return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalid, account.Timezone)
+	}
+	password := account.Password
+	if password == "" {
+		saved, err := c.store.GetAccount(ctx, userID)
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("%w: a password is required", ErrInvalid)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if password, err = chatstore.DecryptToken(saved.PasswordEncrypted, c.config.SecretKey); err != nil {
+			return nil, fmt.Errorf("failed to decrypt CalDAV password: %w", err)
+		}
+	}
+
+	if err := c.client(account, password).check(ctx); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if account.PasswordEncrypted, err = chatstore.EncryptToken(password, c.config.SecretKey); err != nil {
+		return nil, fmt.Errorf("failed to encrypt CalDAV password: %w", err)
+	}
+	saved, err := c.store.SaveAccount(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	return redact(saved), nil
+}
+
+// DeleteAccount disconnects the CalDAV calendar of a user; the schedules
+// and the events are kept.
+func (c *Calendars) DeleteAccount(ctx context.Context, userID int32) error {
+	return c.store.DeleteAccount(ctx, userID)
+}
+
+// SyncAll syncs the CalDAV accounts.
+func (c *Calendars) SyncAll(ctx context.Context) error {
+	if !c.CalDAVEnabled() {
+		return nil
+	}
+	accounts, err := c.store.ListAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, account := range accounts {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := c.syncAccount(ctx, account); err != nil {
+			failed++
+			slog.Warn("calendar: failed to sync", "user", account.UserID, "error", err)
+		}
+	}
+	if failed > 0 {
+		slog.Info("calendars synced", "accounts", len(accounts), "failed", failed)
+	}
+	return nil
+}
+
+// Sync syncs the CalDAV account of a user now.
+func (c *Calendars) Sync(ctx context.Context, userID int32) (*SyncResult, error) {
+	if !c.CalDAVEnabled() {
+		return nil, ErrDisabled
+	}
+	account, err := c.store.GetAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return c.syncAccount(ctx, account)
+}
+
+// syncAccount syncs an account and saves the outcome on it.
+func (c *Calendars) syncAccount(ctx context.Context, account *Account) (*SyncResult, error) {
+	result, syncErr := c.sync(ctx, account)
+	lastError := ""
+	if syncErr != nil {
+		lastError = truncate(syncErr.Error(), maxErrorRunes)
+	}
+	if err := c.store.SaveSyncState(ctx, account.UserID, time.Now().Unix(), lastError); err != nil {
+		return nil, err
+	}
+	return result, syncErr
+}
+
+func (c *Calendars) sync(ctx context.Context, account *Account) (*SyncResult, error) {
+	password, err := chatstore.DecryptToken(account.PasswordEncrypted, c.config.SecretKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt CalDAV password: %w", err)
+	}
+	cal := c.client(account, password)
+	remote, err := cal.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	remoteByHref := make(map[string]*remoteEvent, len(remote))
+	for _, event := range remote {
+		remoteByHref[event.Href] = event
+	}
+	schedules, err := c.listSchedules(ctx, account.UserID)
+	if err != nil {
+		return nil, err
+	}
+	schedulesByID := make(map[int32]*store.Schedule, len(schedules))
+	for _, schedule := range schedules {
+		schedulesByID[schedule.ID] = schedule
+	}
+	links, err := c.store.ListLinks(ctx, account.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := location(account.Timezone, time.UTC)
+	now := time.Now()
+	result := &SyncResult{}
+	writes := 0
+	linked := map[int32]bool{}
+	linkedHrefs := map[string]bool{}
+	for _, link := range links {
+		if writes >= maxWrites || ctx.Err() != nil {
+			break
+		}
+		schedule, hasSchedule := schedulesByID[link.ScheduleID]
+		event, hasEvent := remoteByHref[link.Href]
+		linked[link.ScheduleID], linkedHrefs[link.Href] = true, true
+		switch {
+		case !hasSchedule && hasEvent:
+			// deleted here
+			writes++
+			if err := cal.delete(ctx, link.Href); err != nil {
+				return result, err
+			}
+			result.Deleted++
+			fallthrough
+		case !hasSchedule:
+			if err := c.store.DeleteLink(ctx, account.UserID, link.UID); err != nil {
+				return result, err
+			}
+		case !hasEvent:
+			// deleted in the calendar app
+			writes++
+			if err := c.schedules.DeleteSchedule(ctx, &store.DeleteSchedule{ID: schedule.ID}); err != nil {
+				return result, fmt.Errorf("failed to delete schedule: %w", err)
+			}
+			if err := c.store.DeleteLink(ctx, account.UserID, link.UID); err != nil {
+				return result, err
+			}
+			result.Deleted++
+		case schedule.UpdatedTs != link.UpdatedTs:
+			writes++
+			etag, err := cal.put(ctx, link.Href, EncodeEvent(schedule, link.UID, now), false)
+			if err != nil {
+				return result, err
+			}
+			link.ETag, link.UpdatedTs = etag, schedule.UpdatedTs
+			if err := c.store.SaveLink(ctx, link); err != nil {
+				return result, err
+			}
+			result.Pushed++
+		case link.ETag == "" || event.ETag == link.ETag:
+			// unchanged; servers not answering the ETag of writes give it now
+			if link.ETag != event.ETag {
+				link.ETag = event.ETag
+				if err := c.store.SaveLink(ctx, link); err != nil {
+					return result, err
+				}
+			}
+		default:
+			events := ParseEvents(event.Data, loc)
+			if len(events) == 0 {
+				continue
+			}
+			writes++
+			updatedTs, err := c.updateSchedule(ctx, schedule, events[0].Schedule(account.Timezone))
+			if err != nil {
+				return result, err
+			}
+			link.ETag, link.UpdatedTs = event.ETag, updatedTs
+			if err := c.store.SaveLink(ctx, link); err != nil {
+				return result, err
+			}
+			result.Pulled++
+		}
+	}
+
+	from := now.Add(-syncWindow).Unix()
+	for _, schedule := range schedules {
+		if writes >= maxWrites || ctx.Err() != nil {
+			break
+		}
+		if linked[schedule.ID] || !inWindow(schedule, from) {
+			continue
+		}
+		writes++
+		uid := eventUID(schedule.UID)
+		href := cal.href(uid)
+		// the event of a calendar connected again is overwritten
+		_, exists := remoteByHref[href]
+		linkedHrefs[href] = true
+		etag, err := cal.put(ctx, href, EncodeEvent(schedule, uid, now), !exists)
+		if err != nil {
+			return result, err
+		}
+		if err := c.store.SaveLink(ctx, &Link{
+			UserID: account.UserID, ScheduleID: schedule.ID, UID: uid, Href: href, ETag: etag, UpdatedTs: schedule.UpdatedTs,
+		}); err != nil {
+			return result, err
+		}
+		result.Pushed++
+	}
+
+	for _, event := range remote {
+		if writes >= maxWrites || ctx.Err() != nil {
+			break
+		}
+		if linkedHrefs[event.Href] {
+			continue
+		}
+		events := ParseEvents(event.Data, loc)
+		if len(events) == 0 {
+			continue
+		}
+		writes++
+		create := events[0].Schedule(account.Timezone)
+		reminders, payload := "[]", "{}"
+		create.UID = util.GenUUID()
+		create.CreatorID = account.UserID
+		create.RowStatus = store.Normal
+		create.Reminders, create.Payload = &reminders, &payload
+		created, err := c.schedules.CreateSchedule(ctx, create)
+		if err != nil {
+			return result, fmt.Errorf("failed to create schedule: %w", err)
+		}
+		if err := c.store.SaveLink(ctx, &Link{
+			UserID: account.UserID, ScheduleID: created.ID, UID: events[0].UID, Href: event.Href, ETag: event.ETag, UpdatedTs: created.UpdatedTs,
+		}); err != nil {
+			return result, err
+		}
+		result.Pulled++
+	}
+	return result, ctx.Err()
+}
+
+// updateSchedule saves the changes of an event to its schedule and returns
+// its new update time.
+func (c *Calendars) updateSchedule(ctx context.Context, schedule, changed *store.Schedule) (int64, error) {
+	recurrenceRule, recurrenceEndTs := "", int64(0)
+	if changed.RecurrenceRule != nil {
+		recurrenceRule = *changed.RecurrenceRule
+	}
+	if changed.RecurrenceEndTs != nil {
+		recurrenceEndTs = *changed.RecurrenceEndTs
+	}
+	endTs := changed.StartTs
+	if changed.EndTs != nil {
+		endTs = *changed.EndTs
+	}
+	update := &store.UpdateSchedule{
+		ID:              schedule.ID,
+		Title:           &changed.Title,
+		Description:     &changed.Description,
+		Location:        &changed.Location,
+		StartTs:         &changed.StartTs,
+		EndTs:           &endTs,
+		AllDay:          &changed.AllDay,
+		Timezone:        &changed.Timezone,
+		RecurrenceRule:  &recurrenceRule,
+		RecurrenceEndTs: &recurrenceEndTs,
+	}
+	if err := c.schedules.UpdateSchedule(ctx, update); err != nil {
+		return 0, fmt.Errorf("failed to update schedule: %w", err)
+	}
+	updated, err := c.schedules.ListSchedules(ctx, &store.FindSchedule{ID: &schedule.ID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get schedule: %w", err)
+	}
+	if len(updated) == 0 {
+		return 0, nil
+	}
+	return updated[0].UpdatedTs, nil
+}
+
+// listSchedules lists the schedules of a user.
+func (c *Calendars) listSchedules(ctx context.Context, userID int32) ([]*store.Schedule, error) {
+	normal := store.Normal
+	limit := maxFeedSchedules
+	schedules, err := c.schedules.ListSchedules(ctx, &store.FindSchedule{CreatorID: &userID, RowStatus: &normal, Limit: &limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// inWindow reports whether a schedule ends, or recurs, after from.
+func inWindow(schedule *store.Schedule, from int64) bool {
+	if schedule.RecurrenceRule != nil && *schedule.RecurrenceRule != "" {
+		return schedule.RecurrenceEndTs == nil || *schedule.RecurrenceEndTs == 0 || *schedule.RecurrenceEndTs >= from
+	}
+	end := schedule.StartTs
+	if schedule.EndTs != nil {
+		end = *schedule.EndTs
+	}
+	return end >= from
+}
+
+// redact removes the password of an account.
+func redact(account *Account) *Account {
+	account.Password = ""
+	return account
+}
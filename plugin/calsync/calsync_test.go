@@ -0,0 +1,517 @@
+package calsync
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/store"
+)
+
+const testKey = "0123456789abcdef0123456789abcdef"
+
+func TestEncodeCalendar(t *testing.T) {
+	end := int64(1724151600) // 2024-08-20 11:00 UTC
+	rule := `{"type":"weekly","weekdays":[1,3],"interval":2,"exceptions":["2024-09-02"]}`
+	allDayEnd := time.Date(2024, 8, 23, 0, 0, 0, 0, shanghai(t)).Unix()
+	data := string(EncodeCalendar("DivineSense", []*store.Schedule{
+		{
+			UID: "s1", Title: "Review; design, v2", Description: "line 1\nline 2", Location: "Room 1",
+			StartTs: 1724148000, EndTs: &end, RecurrenceRule: &rule, Timezone: "Asia/Shanghai",
+		},
+		{
+			UID: "s2", Title: "Trip", AllDay: true, Timezone: "Asia/Shanghai",
+			StartTs: time.Date(2024, 8, 21, 0, 0, 0, 0, shanghai(t)).Unix(), EndTs: &allDayEnd,
+		},
+	}, time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)))
+
+	assert.True(t, strings.HasPrefix(data, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.Contains(t, data, "UID:s1@divinesense\r\n")
+	assert.Contains(t, data, "DTSTART:20240820T100000Z\r\nDTEND:20240820T110000Z\r\n")
+	assert.Contains(t, data, "RRULE:FREQ=WEEKLY;BYDAY=MO,WE;INTERVAL=2\r\nEXDATE:20240902T100000Z\r\n")
+	assert.Contains(t, data, `SUMMARY:Review\; design\, v2`)
+	assert.Contains(t, data, `DESCRIPTION:line 1\nline 2`)
+	assert.Contains(t, data, "DTSTART;VALUE=DATE:20240821\r\nDTEND;VALUE=DATE:20240823\r\n")
+
+	events := ParseEvents(data, shanghai(t))
+	require.Len(t, events, 2)
+	assert.Equal(t, "s1@divinesense", events[0].UID)
+	assert.Equal(t, "Review; design, v2", events[0].Title)
+	assert.Equal(t, "line 1\nline 2", events[0].Description)
+	assert.Equal(t, int64(1724148000), events[0].Start.Unix())
+	assert.Equal(t, end, events[0].End.Unix())
+	assert.JSONEq(t, `{"type":"weekly","weekdays":[1,3],"interval":2,"month_day":0}`, events[0].RecurrenceRule)
+	review := events[0].Schedule("Asia/Shanghai")
+	assert.JSONEq(t, `{"type":"weekly","weekdays":[1,3],"interval":2,"month_day":0,"exceptions":["2024-09-02"]}`, *review.RecurrenceRule)
+	trip := events[1].Schedule("Asia/Shanghai")
+	assert.True(t, trip.AllDay)
+	assert.Equal(t, time.Date(2024, 8, 21, 0, 0, 0, 0, shanghai(t)).Unix(), trip.StartTs)
+	assert.Equal(t, allDayEnd, *trip.EndTs)
+}
+
+func TestWriteLineFolds(t *testing.T) {
+	var b strings.Builder
+	writeLine(&b, "SUMMARY:"+strings.Repeat("日程", 40))
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+	}
+	assert.Equal(t, "SUMMARY:"+strings.Repeat("日程", 40), unfold(b.String())[0])
+}
+
+func TestParseEvents(t *testing.T) {
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:a",
+		"SUMMARY:Standup",
+		"DTSTART;TZID=America/New_York:20240820T090000",
+		"DURATION:PT15M",
+		"RRULE:FREQ=WEEKLY",
+		"EXDATE;TZID=America/New_York:20240903T090000,20240910T090000",
+		"BEGIN:VALARM",
+		"DESCRIPTION:ignored",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:a",
+		"RECURRENCE-ID:20240827T090000",
+		"DTSTART:20240827T100000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:b",
+		"SUMMARY:Cancelled",
+		"STATUS:CANCELLED",
+		"DTSTART:20240820T090000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:c",
+		"SUMMARY:Floating, every 2nd Monday",
+		"DTSTART:20240820T090000",
+		"RRULE:FREQ=MONTHLY;BYDAY=2MO",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	events := ParseEvents(data, time.UTC)
+	require.Len(t, events, 2)
+
+	standup := events[0]
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 8, 20, 9, 0, 0, 0, newYork).Unix(), standup.Start.Unix())
+	assert.Equal(t, 15*time.Minute, standup.End.Sub(standup.Start))
+	assert.JSONEq(t, `{"type":"weekly","weekdays":[2],"interval":1,"month_day":0}`, standup.RecurrenceRule, "the weekday of the start")
+	standupSchedule := standup.Schedule("UTC")
+	assert.Equal(t, "America/New_York", standupSchedule.Timezone)
+	assert.JSONEq(t, `{"type":"weekly","weekdays":[2],"interval":1,"month_day":0,"exceptions":["2024-09-03","2024-09-10"]}`,
+		*standupSchedule.RecurrenceRule)
+
+	floating := events[1]
+	assert.Equal(t, time.Date(2024, 8, 20, 9, 0, 0, 0, time.UTC), floating.Start.UTC())
+	assert.Empty(t, floating.RecurrenceRule, "unsupported rules are dropped")
+}
+
+func TestFeed(t *testing.T) {
+	ctx := context.Background()
+	schedules := newMemSchedules()
+	schedules.add(&store.Schedule{CreatorID: 1, UID: "s1", Title: "Mine", StartTs: 1724148000})
+	schedules.add(&store.Schedule{CreatorID: 2, UID: "s2", Title: "Theirs", StartTs: 1724148000})
+	calendars, err := New(Config{InstanceURL: "https://memo.example.com/"}, newMemStore(), schedules)
+	require.NoError(t, err)
+	assert.False(t, calendars.CalDAVEnabled())
+
+	_, err = calendars.Feed(ctx, 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+	feed, err := calendars.RotateFeed(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "https://memo.example.com/api/v1/calendar/"+feed.Token+".ics", feed.URL)
+	assert.Len(t, feed.Token, 48)
+
+	resolved, err := calendars.ResolveFeed(ctx, feed.Token)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), resolved.UserID)
+	data, err := calendars.Calendar(ctx, resolved.UserID)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "SUMMARY:Mine")
+	assert.NotContains(t, string(data), "Theirs")
+
+	rotated, err := calendars.RotateFeed(ctx, 1)
+	require.NoError(t, err)
+	_, err = calendars.ResolveFeed(ctx, feed.Token)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = calendars.ResolveFeed(ctx, rotated.Token)
+	assert.NoError(t, err)
+	_, err = calendars.ResolveFeed(ctx, "")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = calendars.SaveAccount(ctx, 1, &Account{URL: "https://dav.example.com/cal/"}, false)
+	assert.ErrorIs(t, err, ErrDisabled)
+	_, err = New(Config{SecretKey: "short"}, newMemStore(), schedules)
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	server := newDAVServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	schedules := newMemSchedules()
+	now := time.Now().Truncate(time.Hour)
+	end := now.Add(time.Hour).Unix()
+	old := now.AddDate(0, -3, 0).Unix()
+	mine := schedules.add(&store.Schedule{CreatorID: 1, UID: "s1", Title: "Planning", StartTs: now.Unix(), EndTs: &end})
+	schedules.add(&store.Schedule{CreatorID: 1, UID: "s0", Title: "Long ago", StartTs: old})
+	server.events["/cal/remote.ics"] = &davEvent{etag: `"r1"`, data: strings.Join([]string{
+		"BEGIN:VCALENDAR", "BEGIN:VEVENT", "UID:remote-1", "SUMMARY:Dentist",
+		"DTSTART:" + now.Add(48*time.Hour).UTC().Format(utcLayout), "DTEND:" + now.Add(49*time.Hour).UTC().Format(utcLayout),
+		"END:VEVENT", "END:VCALENDAR",
+	}, "\r\n")}
+
+	calendarStore := newMemStore()
+	calendars, err := New(Config{SecretKey: testKey}, calendarStore, schedules)
+	require.NoError(t, err)
+	calendars.client = func(account *Account, password string) *client {
+		c := newClient(account, password)
+		c.http = ts.Client()
+		return c
+	}
+
+	t.Run("private addresses are denied", func(t *testing.T) {
+		denied, err := New(Config{SecretKey: testKey}, newMemStore(), schedules)
+		require.NoError(t, err)
+		_, err = denied.SaveAccount(ctx, 1, &Account{URL: ts.URL + "/cal", Username: "me", Password: "secret"}, false)
+		assert.ErrorIs(t, err, ErrInvalid)
+		assert.ErrorContains(t, err, "not a public address")
+	})
+
+	_, err = calendars.SaveAccount(ctx, 1, &Account{URL: ts.URL + "/cal", Username: "me", Password: "wrong"}, false)
+	assert.ErrorContains(t, err, "rejected the username or password")
+	account, err := calendars.SaveAccount(ctx, 1, &Account{URL: ts.URL + "/cal", Username: "me", Password: "secret"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, ts.URL+"/cal/", account.URL)
+	assert.Equal(t, DefaultTimezone, account.Timezone)
+	assert.Empty(t, account.Password)
+	saved, _ := calendarStore.GetAccount(ctx, 1)
+	assert.NotContains(t, saved.PasswordEncrypted, "secret")
+
+	result, err := calendars.Sync(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, &SyncResult{Pushed: 1, Pulled: 1}, result)
+	pushed := server.events["/cal/s1@divinesense.ics"]
+	require.NotNil(t, pushed, "the schedule is pushed")
+	assert.Contains(t, pushed.data, "SUMMARY:Planning")
+	assert.Nil(t, server.events["/cal/s0@divinesense.ics"], "old schedules stay out")
+	dentist := schedules.byTitle("Dentist")
+	require.NotNil(t, dentist, "the event is pulled")
+	assert.Equal(t, now.Add(48*time.Hour).Unix(), dentist.StartTs)
+
+	result, err = calendars.Sync(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, &SyncResult{}, result, "nothing changed")
+
+	t.Run("changes in the app are pulled", func(t *testing.T) {
+		server.mu.Lock()
+		event := server.events["/cal/s1@divinesense.ics"]
+		event.data = strings.Replace(event.data, "SUMMARY:Planning", "SUMMARY:Planning (moved)", 1)
+		event.etag = `"changed"`
+		server.mu.Unlock()
+		result, err := calendars.Sync(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Pulled)
+		assert.Equal(t, "Planning (moved)", schedules.get(mine.ID).Title)
+	})
+
+	t.Run("changes here are pushed", func(t *testing.T) {
+		schedules.update(mine.ID, func(s *store.Schedule) { s.Location = "Room 2" })
+		result, err := calendars.Sync(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Pushed)
+		assert.Contains(t, server.events["/cal/s1@divinesense.ics"].data, "LOCATION:Room 2")
+	})
+
+	t.Run("deletions go both ways", func(t *testing.T) {
+		require.NoError(t, schedules.DeleteSchedule(ctx, &store.DeleteSchedule{ID: mine.ID}))
+		server.mu.Lock()
+		delete(server.events, "/cal/remote.ics")
+		server.mu.Unlock()
+		result, err := calendars.Sync(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Deleted)
+		assert.Nil(t, server.events["/cal/s1@divinesense.ics"])
+		assert.Nil(t, schedules.byTitle("Dentist"))
+		links, _ := calendarStore.ListLinks(ctx, 1)
+		assert.Empty(t, links)
+	})
+
+	t.Run("failures are kept on the account", func(t *testing.T) {
+		server.mu.Lock()
+		server.password = "rotated"
+		server.mu.Unlock()
+		require.NoError(t, calendars.SyncAll(ctx))
+		saved, _ := calendars.Account(ctx, 1)
+		assert.Contains(t, saved.LastError, "rejected the username or password")
+	})
+}
+
+func shanghai(t *testing.T) *time.Location {
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	return loc
+}
+
+type davEvent struct {
+	etag string
+	data string
+}
+
+// davServer is a CalDAV calendar at /cal/.
+type davServer struct {
+	mu       sync.Mutex
+	password string
+	events   map[string]*davEvent
+	version  int
+}
+
+func newDAVServer() *davServer {
+	return &davServer{password: "secret", events: map[string]*davEvent{}}
+}
+
+func (s *davServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, password, _ := r.BasicAuth(); password != s.password {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch r.Method {
+	case "PROPFIND":
+		w.WriteHeader(http.StatusMultiStatus)
+		fmt.Fprint(w, `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+<d:response><d:href>/cal/</d:href><d:propstat><d:prop><d:resourcetype><d:collection/><c:calendar/></d:resourcetype></d:prop>
+<d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response></d:multistatus>`)
+	case "REPORT":
+		w.WriteHeader(http.StatusMultiStatus)
+		fmt.Fprint(w, `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">`)
+		for href, event := range s.events {
+			fmt.Fprintf(w, `<d:response><d:href>%s</d:href><d:propstat><d:prop><d:getetag>%s</d:getetag>
+<c:calendar-data>%s</c:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`,
+				html.EscapeString(href), html.EscapeString(event.etag), html.EscapeString(event.data))
+		}
+		fmt.Fprint(w, `</d:multistatus>`)
+	case http.MethodPut:
+		if _, ok := s.events[r.URL.Path]; ok && r.Header.Get("If-None-Match") == "*" {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		s.version++
+		etag := fmt.Sprintf(`"v%d"`, s.version)
+		s.events[r.URL.Path] = &davEvent{etag: etag, data: string(body)}
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		if _, ok := s.events[r.URL.Path]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(s.events, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// memSchedules implements store.ScheduleService, bumping updated_ts as the
+// trigger of the schedule table does.
+type memSchedules struct {
+	schedules map[int32]*store.Schedule
+	nextID    int32
+	clock     int64
+}
+
+func newMemSchedules() *memSchedules {
+	return &memSchedules{schedules: map[int32]*store.Schedule{}, clock: 1000}
+}
+
+func (m *memSchedules) tick() int64 {
+	m.clock++
+	return m.clock
+}
+
+func (m *memSchedules) add(schedule *store.Schedule) *store.Schedule {
+	created, _ := m.CreateSchedule(context.Background(), schedule)
+	return created
+}
+
+func (m *memSchedules) get(id int32) *store.Schedule {
+	return m.schedules[id]
+}
+
+func (m *memSchedules) update(id int32, change func(*store.Schedule)) {
+	change(m.schedules[id])
+	m.schedules[id].UpdatedTs = m.tick()
+}
+
+func (m *memSchedules) byTitle(title string) *store.Schedule {
+	for _, schedule := range m.schedules {
+		if schedule.Title == title {
+			return schedule
+		}
+	}
+	return nil
+}
+
+func (m *memSchedules) CreateSchedule(_ context.Context, create *store.Schedule) (*store.Schedule, error) {
+	m.nextID++
+	created := *create
+	created.ID = m.nextID
+	created.RowStatus = store.Normal
+	created.UpdatedTs = m.tick()
+	m.schedules[created.ID] = &created
+	return &created, nil
+}
+
+func (m *memSchedules) ListSchedules(_ context.Context, find *store.FindSchedule) ([]*store.Schedule, error) {
+	schedules := []*store.Schedule{}
+	for id := int32(1); id <= m.nextID; id++ {
+		schedule, ok := m.schedules[id]
+		if !ok || (find.ID != nil && *find.ID != id) || (find.CreatorID != nil && *find.CreatorID != schedule.CreatorID) {
+			continue
+		}
+		copied := *schedule
+		schedules = append(schedules, &copied)
+	}
+	return schedules, nil
+}
+
+func (m *memSchedules) UpdateSchedule(_ context.Context, update *store.UpdateSchedule) error {
+	schedule, ok := m.schedules[update.ID]
+	if !ok {
+		return fmt.Errorf("schedule not found")
+	}
+	schedule.Title, schedule.Description, schedule.Location = *update.Title, *update.Description, *update.Location
+	schedule.StartTs, schedule.EndTs, schedule.AllDay = *update.StartTs, update.EndTs, *update.AllDay
+	schedule.UpdatedTs = m.tick()
+	return nil
+}
+
+func (m *memSchedules) DeleteSchedule(_ context.Context, del *store.DeleteSchedule) error {
+	if _, ok := m.schedules[del.ID]; !ok {
+		return fmt.Errorf("schedule not found")
+	}
+	delete(m.schedules, del.ID)
+	return nil
+}
+
+type memStore struct {
+	feeds    map[int32]*Feed
+	accounts map[int32]*Account
+	links    map[string]*Link
+}
+
+func newMemStore() *memStore {
+	return &memStore{feeds: map[int32]*Feed{}, accounts: map[int32]*Account{}, links: map[string]*Link{}}
+}
+
+func (s *memStore) GetFeed(_ context.Context, userID int32) (*Feed, error) {
+	feed, ok := s.feeds[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *feed
+	return &copied, nil
+}
+
+func (s *memStore) GetFeedByToken(_ context.Context, token string) (*Feed, error) {
+	for _, feed := range s.feeds {
+		if feed.Token == token {
+			copied := *feed
+			return &copied, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *memStore) SaveFeed(_ context.Context, userID int32, token string) (*Feed, error) {
+	s.feeds[userID] = &Feed{UserID: userID, Token: token, CreatedTs: time.Now().Unix()}
+	copied := *s.feeds[userID]
+	return &copied, nil
+}
+
+func (s *memStore) DeleteFeed(_ context.Context, userID int32) error {
+	if _, ok := s.feeds[userID]; !ok {
+		return ErrNotFound
+	}
+	delete(s.feeds, userID)
+	return nil
+}
+
+func (s *memStore) GetAccount(_ context.Context, userID int32) (*Account, error) {
+	account, ok := s.accounts[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *account
+	return &copied, nil
+}
+
+func (s *memStore) ListAccounts(_ context.Context) ([]*Account, error) {
+	accounts := []*Account{}
+	for _, account := range s.accounts {
+		copied := *account
+		accounts = append(accounts, &copied)
+	}
+	return accounts, nil
+}
+
+func (s *memStore) SaveAccount(_ context.Context, account *Account) (*Account, error) {
+	copied := *account
+	copied.Password = ""
+	s.accounts[account.UserID] = &copied
+	result := copied
+	return &result, nil
+}
+
+func (s *memStore) SaveSyncState(_ context.Context, userID int32, lastSyncTs int64, lastError string) error {
+	if account, ok := s.accounts[userID]; ok {
+		account.LastSyncTs, account.LastError = lastSyncTs, lastError
+	}
+	return nil
+}
+
+func (s *memStore) DeleteAccount(_ context.Context, userID int32) error {
+	delete(s.accounts, userID)
+	return nil
+}
+
+func (s *memStore) ListLinks(_ context.Context, userID int32) ([]*Link, error) {
+	links := []*Link{}
+	for _, link := range s.links {
+		if link.UserID == userID {
+			copied := *link
+			links = append(links, &copied)
+		}
+	}
+	return links, nil
+}
+
+func (s *memStore) SaveLink(_ context.Context, link *Link) error {
+	copied := *link
+	s.links[link.UID] = &copied
+	return nil
+}
+
+func (s *memStore) DeleteLink(_ context.Context, _ int32, uid string) error {
+	delete(s.links, uid)
+	return nil
+}
@@ -0,0 +1,446 @@
+package calsync
+
+import (
+	"strings"
+	"time"
+
+	aischedule "github.com/hrygo/divinesense/ai/services/schedule"
+	"github.com/hrygo/divinesense/store"
+)
+
+const (
+	// prodID identifies the calendars of the instance.
+	prodID = "-//DivineSense//Schedules//EN"
+	// utcLayout and dateLayout are the iCalendar DATE-TIME (UTC) and DATE
+	// formats.
+	utcLayout  = "20060102T150405Z"
+	dateLayout = "20060102"
+	// localLayout is a DATE-TIME in the time zone of its TZID, or floating.
+	localLayout = "20060102T150405"
+)
+
+// Event is a VEVENT of a calendar.
+type Event struct {
+	UID         string
+	Title       string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	// Timezone is the TZID of the start, empty for UTC and floating times.
+	Timezone string
+	// RecurrenceRule is the JSON recurrence rule of schedules, empty for
+	// single events and for rules schedules cannot represent.
+	RecurrenceRule  string
+	RecurrenceEndTs int64
+	// Exceptions are the skipped occurrences (EXDATE) of recurring events.
+	Exceptions []time.Time
+}
+
+// EncodeCalendar encodes schedules as an iCalendar document named name.
+func EncodeCalendar(name string, schedules []*store.Schedule, now time.Time) []byte {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+prodID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	writeLine(&b, "X-WR-CALNAME:"+escapeText(name))
+	for _, schedule := range schedules {
+		writeEvent(&b, schedule, eventUID(schedule.UID), now)
+	}
+	b.WriteString("END:VCALENDAR\r\n")
+	return []byte(b.String())
+}
+
+// EncodeEvent encodes a schedule as an iCalendar document with a single
+// event of the given UID, as stored by CalDAV servers.
+func EncodeEvent(schedule *store.Schedule, uid string, now time.Time) []byte {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+prodID)
+	writeEvent(&b, schedule, uid, now)
+	b.WriteString("END:VCALENDAR\r\n")
+	return []byte(b.String())
+}
+
+// eventUID returns the iCalendar UID of a schedule.
+func eventUID(scheduleUID string) string {
+	return scheduleUID + "@divinesense"
+}
+
+func writeEvent(b *strings.Builder, schedule *store.Schedule, uid string, now time.Time) {
+	writeLine(b, "BEGIN:VEVENT")
+	writeLine(b, "UID:"+escapeText(uid))
+	writeLine(b, "DTSTAMP:"+now.UTC().Format(utcLayout))
+	if schedule.UpdatedTs > 0 {
+		writeLine(b, "LAST-MODIFIED:"+time.Unix(schedule.UpdatedTs, 0).UTC().Format(utcLayout))
+	}
+	start := time.Unix(schedule.StartTs, 0)
+	if schedule.AllDay {
+		loc := location(schedule.Timezone, time.UTC)
+		start = start.In(loc)
+		end := start.AddDate(0, 0, 1)
+		if schedule.EndTs != nil && *schedule.EndTs > schedule.StartTs {
+			// DTEND is exclusive: the day after the last one
+			last := time.Unix(*schedule.EndTs-1, 0).In(loc)
+			end = time.Date(last.Year(), last.Month(), last.Day()+1, 0, 0, 0, 0, loc)
+		}
+		writeLine(b, "DTSTART;VALUE=DATE:"+start.Format(dateLayout))
+		writeLine(b, "DTEND;VALUE=DATE:"+end.Format(dateLayout))
+	} else {
+		writeLine(b, "DTSTART:"+start.UTC().Format(utcLayout))
+		if schedule.EndTs != nil && *schedule.EndTs > schedule.StartTs {
+			writeLine(b, "DTEND:"+time.Unix(*schedule.EndTs, 0).UTC().Format(utcLayout))
+		}
+	}
+	writeRecurrence(b, schedule, time.Unix(schedule.StartTs, 0).In(location(schedule.Timezone, time.UTC)))
+	writeLine(b, "SUMMARY:"+escapeText(schedule.Title))
+	if schedule.Description != "" {
+		writeLine(b, "DESCRIPTION:"+escapeText(schedule.Description))
+	}
+	if schedule.Location != "" {
+		writeLine(b, "LOCATION:"+escapeText(schedule.Location))
+	}
+	writeLine(b, "END:VEVENT")
+}
+
+// scheduleRecurrence returns the recurrence rule of a schedule, nil when it has none
+// or an invalid one.
+func scheduleRecurrence(schedule *store.Schedule) *aischedule.RecurrenceRule {
+	if schedule.RecurrenceRule == nil || *schedule.RecurrenceRule == "" {
+		return nil
+	}
+	rule, err := aischedule.ParseRecurrenceRuleFromJSON(*schedule.RecurrenceRule)
+	if err != nil || rule.Validate() != nil {
+		return nil
+	}
+	return rule
+}
+
+// writeRecurrence writes the RRULE of a recurring schedule starting at start,
+// and its skipped occurrences as EXDATE.
+func writeRecurrence(b *strings.Builder, schedule *store.Schedule, start time.Time) {
+	rule := scheduleRecurrence(schedule)
+	if rule == nil {
+		return
+	}
+	rule.Resolve(start)
+	var until int64
+	if schedule.RecurrenceEndTs != nil {
+		until = *schedule.RecurrenceEndTs
+	}
+	writeLine(b, "RRULE:"+rule.RRule(until))
+	for _, day := range rule.Exceptions {
+		date, err := time.ParseInLocation(time.DateOnly, day, start.Location())
+		if err != nil {
+			continue
+		}
+		if schedule.AllDay {
+			writeLine(b, "EXDATE;VALUE=DATE:"+date.Format(dateLayout))
+			continue
+		}
+		hour, minute, second := start.Clock()
+		occurrence := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, second, 0, start.Location())
+		writeLine(b, "EXDATE:"+occurrence.UTC().Format(utcLayout))
+	}
+}
+
+// decodeRRule converts the RRULE of an event starting at start to the JSON
+// recurrence rule of schedules; ok is false for rules schedules cannot
+// represent.
+func decodeRRule(rrule string, start time.Time) (rule string, endTs int64, ok bool) {
+	recurrence, endTs, err := aischedule.ParseRRule(rrule, start)
+	if err != nil {
+		return "", 0, false
+	}
+	encoded, err := recurrence.ToJSON()
+	if err != nil {
+		return "", 0, false
+	}
+	return encoded, endTs, true
+}
+
+// ParseEvents returns the events of an iCalendar document; the times without
+// zone are in loc. Overrides of recurring events (RECURRENCE-ID) are skipped;
+// their excluded occurrences (EXDATE) are kept as exceptions.
+func ParseEvents(data string, loc *time.Location) []*Event {
+	var events []*Event
+	var event *Event
+	var rrule string
+	skip := false
+	depth := 0
+	hasEnd := false
+	var duration time.Duration
+	for _, line := range unfold(data) {
+		name, params, value := parseLine(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			event, rrule, skip, depth, hasEnd, duration = &Event{}, "", false, 0, false, 0
+			continue
+		case event == nil:
+			continue
+		case name == "BEGIN":
+			// VALARM and other nested components
+			depth++
+			continue
+		case name == "END" && depth > 0:
+			depth--
+			continue
+		case depth > 0:
+			continue
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if !skip && event.UID != "" && !event.Start.IsZero() {
+				if !hasEnd {
+					switch {
+					case duration > 0:
+						event.End = event.Start.Add(duration)
+					case event.AllDay:
+						event.End = event.Start.AddDate(0, 0, 1)
+					default:
+						event.End = event.Start
+					}
+				}
+				if rrule != "" {
+					event.RecurrenceRule, event.RecurrenceEndTs, _ = decodeRRule(rrule, event.Start)
+				}
+				events = append(events, event)
+			}
+			event = nil
+			continue
+		}
+		switch name {
+		case "UID":
+			event.UID = value
+		case "SUMMARY":
+			event.Title = unescapeText(value)
+		case "DESCRIPTION":
+			event.Description = unescapeText(value)
+		case "LOCATION":
+			event.Location = unescapeText(value)
+		case "DTSTART":
+			event.Timezone = params["TZID"]
+			event.AllDay = strings.EqualFold(params["VALUE"], "DATE") || len(value) == len(dateLayout)
+			if start, ok := parseTime(value, params["TZID"], loc); ok {
+				event.Start = start
+			} else {
+				skip = true
+			}
+		case "DTEND":
+			if end, ok := parseTime(value, params["TZID"], loc); ok {
+				event.End, hasEnd = end, true
+			}
+		case "DURATION":
+			duration = parseDuration(value)
+		case "RRULE":
+			rrule = value
+		case "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				if exception, ok := parseTime(v, params["TZID"], loc); ok {
+					event.Exceptions = append(event.Exceptions, exception)
+				}
+			}
+		case "RECURRENCE-ID":
+			skip = true
+		case "STATUS":
+			if strings.EqualFold(value, "CANCELLED") {
+				skip = true
+			}
+		}
+	}
+	return events
+}
+
+// Schedule returns the schedule of an event; zone is the time zone of
+// schedules whose event has none.
+func (e *Event) Schedule(zone string) *store.Schedule {
+	schedule := &store.Schedule{
+		Title:       truncate(strings.TrimSpace(e.Title), maxTitleRunes),
+		Description: e.Description,
+		Location:    e.Location,
+		StartTs:     e.Start.Unix(),
+		AllDay:      e.AllDay,
+		Timezone:    zone,
+	}
+	if schedule.Title == "" {
+		schedule.Title = "(no title)"
+	}
+	if e.Timezone != "" && location(e.Timezone, nil) != nil {
+		schedule.Timezone = e.Timezone
+	}
+	if end := e.End.Unix(); end > schedule.StartTs {
+		schedule.EndTs = &end
+	}
+	if e.RecurrenceRule != "" {
+		rule := e.RecurrenceRule
+		schedule.RecurrenceRule = &rule
+		if e.RecurrenceEndTs > 0 {
+			endTs := e.RecurrenceEndTs
+			schedule.RecurrenceEndTs = &endTs
+		}
+		if len(e.Exceptions) > 0 {
+			schedule.RecurrenceRule = e.exceptions(rule, location(schedule.Timezone, time.UTC))
+		}
+	}
+	return schedule
+}
+
+// exceptions adds the exceptions of a recurring event to its JSON recurrence
+// rule, as days in loc; the dates of all-day events are days already.
+func (e *Event) exceptions(rule string, loc *time.Location) *string {
+	recurrence, err := aischedule.ParseRecurrenceRuleFromJSON(rule)
+	if err != nil {
+		return &rule
+	}
+	for _, exception := range e.Exceptions {
+		if !e.AllDay {
+			exception = exception.In(loc)
+		}
+		if recurrence.Skip(exception.Format(time.DateOnly)) != nil {
+			break
+		}
+	}
+	encoded, err := recurrence.ToJSON()
+	if err != nil {
+		return &rule
+	}
+	return &encoded
+}
+
+// parseTime parses a DATE or DATE-TIME; DATEs are midnight in loc, and
+// DATE-TIMEs without zone are in tzid, or loc.
+func parseTime(value, tzid string, loc *time.Location) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if tzid != "" {
+		loc = location(tzid, loc)
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(utcLayout, value)
+		return t, err == nil
+	}
+	layout := localLayout
+	if len(value) == len(dateLayout) {
+		layout = dateLayout
+	}
+	t, err := time.ParseInLocation(layout, value, loc)
+	return t, err == nil
+}
+
+// parseDuration parses the positive iCalendar durations, e.g. PT1H30M.
+func parseDuration(value string) time.Duration {
+	value = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "+")
+	if !strings.HasPrefix(value, "P") {
+		return 0
+	}
+	var total time.Duration
+	n := 0
+	for _, r := range value[1:] {
+		switch {
+		case r >= '0' && r <= '9':
+			n = n*10 + int(r-'0')
+		case r == 'W':
+			total, n = total+time.Duration(n)*7*24*time.Hour, 0
+		case r == 'D':
+			total, n = total+time.Duration(n)*24*time.Hour, 0
+		case r == 'H':
+			total, n = total+time.Duration(n)*time.Hour, 0
+		case r == 'M':
+			total, n = total+time.Duration(n)*time.Minute, 0
+		case r == 'S':
+			total, n = total+time.Duration(n)*time.Second, 0
+		}
+	}
+	return total
+}
+
+// location loads a time zone, fallback when it is unknown.
+func location(name string, fallback *time.Location) *time.Location {
+	if name == "" {
+		return fallback
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return fallback
+	}
+	return loc
+}
+
+// unfold splits an iCalendar document into its unfolded lines.
+func unfold(data string) []string {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	var lines []string
+	for _, line := range strings.Split(data, "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// parseLine splits a content line into its upper-cased name, its parameters
+// and its value.
+func parseLine(line string) (name string, params map[string]string, value string) {
+	params = map[string]string{}
+	// the value starts at the first colon outside of quoted parameters
+	quoted, colon := false, -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return "", params, ""
+	}
+	head, value := line[:colon], line[colon+1:]
+	fields := strings.Split(head, ";")
+	for _, field := range fields[1:] {
+		if key, v, ok := strings.Cut(field, "="); ok {
+			params[strings.ToUpper(key)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(fields[0]), params, value
+}
+
+// writeLine writes a content line folded at 75 octets, without splitting
+// UTF-8 sequences.
+func writeLine(b *strings.Builder, line string) {
+	// continuation lines start with a space
+	for limit := 75; len(line) > limit; limit = 74 {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+	}
+	b.WriteString(line + "\r\n")
+}
+
+var (
+	textEscaper   = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	textUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+)
+
+func escapeText(text string) string {
+	return textEscaper.Replace(text)
+}
+
+func unescapeText(text string) string {
+	return textUnescaper.Replace(text)
+}
+
+// truncate shortens a text to limit runes.
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit])
+}
@@ -0,0 +1,233 @@
+package calsync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DBStore persists the calendars in the calendar_feed, calendar_account and
+// calendar_event tables (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new calendar store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const feedColumns = "user_id, token, created_ts"
+
+// GetFeed implements Store.
+func (s *DBStore) GetFeed(ctx context.Context, userID int32) (*Feed, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+feedColumns+" FROM calendar_feed WHERE user_id = $1", userID)
+	return getFeed(row)
+}
+
+// GetFeedByToken implements Store.
+func (s *DBStore) GetFeedByToken(ctx context.Context, token string) (*Feed, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+feedColumns+" FROM calendar_feed WHERE token = $1", token)
+	return getFeed(row)
+}
+
+// SaveFeed implements Store.
+func (s *DBStore) SaveFeed(ctx context.Context, userID int32, token string) (*Feed, error) {
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO calendar_feed (user_id, token, created_ts)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET
+			token = EXCLUDED.token,
+			created_ts = EXCLUDED.created_ts
+		RETURNING `+feedColumns,
+		userID, token, time.Now().Unix())
+	feed, err := scanFeed(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save calendar feed: %w", err)
+	}
+	return feed, nil
+}
+
+// DeleteFeed implements Store.
+func (s *DBStore) DeleteFeed(ctx context.Context, userID int32) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM calendar_feed WHERE user_id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar feed: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+const accountColumns = `user_id, url, username, password_encrypted, timezone, allow_private,
+	last_sync_ts, last_error, updated_ts`
+
+// GetAccount implements Store.
+func (s *DBStore) GetAccount(ctx context.Context, userID int32) (*Account, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+accountColumns+" FROM calendar_account WHERE user_id = $1", userID)
+	account, err := scanAccount(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar account: %w", err)
+	}
+	return account, nil
+}
+
+// ListAccounts implements Store.
+func (s *DBStore) ListAccounts(ctx context.Context) ([]*Account, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+accountColumns+" FROM calendar_account ORDER BY last_sync_ts, user_id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list calendar accounts: %w", err)
+	}
+	defer rows.Close()
+	accounts := []*Account{}
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan calendar account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+// SaveAccount implements Store. The links of a user connecting another
+// calendar are deleted.
+func (s *DBStore) SaveAccount(ctx context.Context, account *Account) (*Account, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM calendar_event e USING calendar_account a
+		WHERE e.user_id = $1 AND a.user_id = $1 AND a.url <> $2`,
+		account.UserID, account.URL); err != nil {
+		return nil, fmt.Errorf("failed to delete calendar events: %w", err)
+	}
+	row := tx.QueryRowContext(ctx, `
+		INSERT INTO calendar_account (user_id, url, username, password_encrypted, timezone, allow_private, updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id) DO UPDATE SET
+			url = EXCLUDED.url,
+			username = EXCLUDED.username,
+			password_encrypted = EXCLUDED.password_encrypted,
+			timezone = EXCLUDED.timezone,
+			allow_private = EXCLUDED.allow_private,
+			last_error = '',
+			updated_ts = EXCLUDED.updated_ts
+		RETURNING `+accountColumns,
+		account.UserID, account.URL, account.Username, account.PasswordEncrypted, account.Timezone,
+		account.AllowPrivate, time.Now().Unix())
+	saved, err := scanAccount(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save calendar account: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit calendar account: %w", err)
+	}
+	return saved, nil
+}
+
+// SaveSyncState implements Store.
+func (s *DBStore) SaveSyncState(ctx context.Context, userID int32, lastSyncTs int64, lastError string) error {
+	if _, err := s.db.ExecContext(ctx, "UPDATE calendar_account SET last_sync_ts = $2, last_error = $3 WHERE user_id = $1",
+		userID, lastSyncTs, lastError); err != nil {
+		return fmt.Errorf("failed to save calendar sync state: %w", err)
+	}
+	return nil
+}
+
+// DeleteAccount implements Store; the links are deleted in cascade.
+func (s *DBStore) DeleteAccount(ctx context.Context, userID int32) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM calendar_account WHERE user_id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar account: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListLinks implements Store.
+func (s *DBStore) ListLinks(ctx context.Context, userID int32) ([]*Link, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id, schedule_id, uid, href, etag, schedule_updated_ts
+		FROM calendar_event WHERE user_id = $1 ORDER BY schedule_id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list calendar events: %w", err)
+	}
+	defer rows.Close()
+	links := []*Link{}
+	for rows.Next() {
+		link := &Link{}
+		if err := rows.Scan(&link.UserID, &link.ScheduleID, &link.UID, &link.Href, &link.ETag, &link.UpdatedTs); err != nil {
+			return nil, fmt.Errorf("failed to scan calendar event: %w", err)
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
+
+// SaveLink implements Store.
+func (s *DBStore) SaveLink(ctx context.Context, link *Link) error {
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO calendar_event (user_id, schedule_id, uid, href, etag, schedule_updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, uid) DO UPDATE SET
+			schedule_id = EXCLUDED.schedule_id,
+			href = EXCLUDED.href,
+			etag = EXCLUDED.etag,
+			schedule_updated_ts = EXCLUDED.schedule_updated_ts`,
+		link.UserID, link.ScheduleID, link.UID, link.Href, link.ETag, link.UpdatedTs); err != nil {
+		return fmt.Errorf("failed to save calendar event: %w", err)
+	}
+	return nil
+}
+
+// DeleteLink implements Store.
+func (s *DBStore) DeleteLink(ctx context.Context, userID int32, uid string) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM calendar_event WHERE user_id = $1 AND uid = $2", userID, uid); err != nil {
+		return fmt.Errorf("failed to delete calendar event: %w", err)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// getFeed scans a feed, mapping a missing row to ErrNotFound.
+func getFeed(row rowScanner) (*Feed, error) {
+	feed, err := scanFeed(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+	return feed, nil
+}
+
+func scanFeed(row rowScanner) (*Feed, error) {
+	feed := &Feed{}
+	if err := row.Scan(&feed.UserID, &feed.Token, &feed.CreatedTs); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+func scanAccount(row rowScanner) (*Account, error) {
+	account := &Account{}
+	if err := row.Scan(&account.UserID, &account.URL, &account.Username, &account.PasswordEncrypted, &account.Timezone,
+		&account.AllowPrivate, &account.LastSyncTs, &account.LastError, &account.UpdatedTs); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
@@ -0,0 +1,278 @@
+// Package canary rolls new experts out on a share of their incumbent's traffic.
+//
+// A canary is a parrot declared as replacing an existing one (its
+// incumbent). A configurable percentage of the users whose requests are
+// routed to the incumbent are served by the canary instead, while the
+// incumbent answers the same requests in shadow. Both answers, latencies,
+// token counts and errors are recorded, and aggregated into comparison
+// metrics for deciding on the full rollout.
+package canary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrNotFound is returned for canaries that are not registered.
+	ErrNotFound = errors.New("canary not found")
+	// ErrInvalid is returned for percentages out of 0-100.
+	ErrInvalid = errors.New("percent must be between 0 and 100")
+)
+
+// maxAnswerLength is the number of runes of the answers kept per comparison.
+const maxAnswerLength = 4000
+
+// Rollout is the rollout state of a canary.
+type Rollout struct {
+	Name      string `json:"name"`
+	Incumbent string `json:"incumbent"`
+	// Percent is the share of the incumbent's eligible traffic the canary serves.
+	Percent int `json:"percent"`
+}
+
+// Comparison is a request served by a canary and answered by its incumbent
+// in shadow.
+type Comparison struct {
+	ID                 int32   `json:"id"`
+	Canary             string  `json:"canary"`
+	Incumbent          string  `json:"incumbent"`
+	UserID             int32   `json:"user_id"`
+	Query              string  `json:"query"`
+	CanaryAnswer       string  `json:"canary_answer"`
+	IncumbentAnswer    string  `json:"incumbent_answer"`
+	CanaryLatencyMs    int64   `json:"canary_latency_ms"`
+	IncumbentLatencyMs int64   `json:"incumbent_latency_ms"`
+	CanaryTokens       int     `json:"canary_tokens"`
+	IncumbentTokens    int     `json:"incumbent_tokens"`
+	CanaryError        string  `json:"canary_error,omitempty"`
+	IncumbentError     string  `json:"incumbent_error,omitempty"`
+	Similarity         float64 `json:"similarity"`
+	CreatedTs          int64   `json:"created_ts"`
+}
+
+// Metrics compares a canary with its incumbent over the recorded requests.
+type Metrics struct {
+	Requests              int     `json:"requests"`
+	CanaryAvgLatencyMs    float64 `json:"canary_avg_latency_ms"`
+	IncumbentAvgLatencyMs float64 `json:"incumbent_avg_latency_ms"`
+	CanaryErrorRate       float64 `json:"canary_error_rate"`
+	IncumbentErrorRate    float64 `json:"incumbent_error_rate"`
+	CanaryAvgTokens       float64 `json:"canary_avg_tokens"`
+	IncumbentAvgTokens    float64 `json:"incumbent_avg_tokens"`
+	CanaryAvgLength       float64 `json:"canary_avg_length"`
+	IncumbentAvgLength    float64 `json:"incumbent_avg_length"`
+	// AvgSimilarity is the mean bigram similarity of the two answers (0-1).
+	AvgSimilarity float64 `json:"avg_similarity"`
+}
+
+// Store persists the rollout percentages and the comparisons.
+type Store interface {
+	// ListPercents returns the percentages set by admins, by canary.
+	ListPercents(ctx context.Context) (map[string]int, error)
+	SavePercent(ctx context.Context, name string, percent int) error
+	SaveComparison(ctx context.Context, comparison *Comparison) error
+	// ListComparisons returns the latest comparisons of a canary.
+	ListComparisons(ctx context.Context, name string, limit int) ([]*Comparison, error)
+	// Metrics aggregates the comparisons of a canary since a Unix time.
+	Metrics(ctx context.Context, name string, since int64) (*Metrics, error)
+}
+
+// Canaries holds the rollouts of the registered canaries.
+type Canaries struct {
+	store Store
+
+	mu        sync.RWMutex
+	rollouts  map[string]*Rollout
+	overrides map[string]int // nil until loaded
+}
+
+// New creates the canaries.
+func New(store Store) *Canaries {
+	return &Canaries{store: store, rollouts: make(map[string]*Rollout)}
+}
+
+// Register adds a canary of incumbent with its configured percentage; a
+// percentage set by admins takes precedence.
+func (c *Canaries) Register(ctx context.Context, name, incumbent string, percent int) {
+	overrides := c.loadOverrides(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if override, ok := overrides[name]; ok {
+		percent = override
+	}
+	c.rollouts[name] = &Rollout{Name: name, Incumbent: incumbent, Percent: min(max(percent, 0), 100)}
+}
+
+// loadOverrides loads the percentages set by admins once.
+func (c *Canaries) loadOverrides(ctx context.Context) map[string]int {
+	c.mu.RLock()
+	overrides := c.overrides
+	c.mu.RUnlock()
+	if overrides != nil {
+		return overrides
+	}
+	overrides, err := c.store.ListPercents(ctx)
+	if err != nil || overrides == nil {
+		// Retried on the next registration
+		return map[string]int{}
+	}
+	c.mu.Lock()
+	c.overrides = overrides
+	c.mu.Unlock()
+	return overrides
+}
+
+// Pick returns the canary that serves the user's requests routed to
+// incumbent, if any. Users are bucketed by a hash, so a user consistently
+// gets the same expert for a given percentage.
+func (c *Canaries) Pick(incumbent string, userID int32) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var names []string
+	for name, rollout := range c.rollouts {
+		if rollout.Incumbent == incumbent && rollout.Percent > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if bucket(name, userID) < c.rollouts[name].Percent {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// bucket returns the bucket (0-99) of a user for a canary.
+func bucket(name string, userID int32) int {
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + strconv.Itoa(int(userID))))
+	return int(h.Sum32() % 100)
+}
+
+// List returns the rollouts ordered by name.
+func (c *Canaries) List() []*Rollout {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	rollouts := make([]*Rollout, 0, len(c.rollouts))
+	for _, rollout := range c.rollouts {
+		copied := *rollout
+		rollouts = append(rollouts, &copied)
+	}
+	sort.Slice(rollouts, func(i, j int) bool { return rollouts[i].Name < rollouts[j].Name })
+	return rollouts
+}
+
+// Get returns the rollout of a canary.
+func (c *Canaries) Get(name string) (*Rollout, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	rollout, ok := c.rollouts[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *rollout
+	return &copied, nil
+}
+
+// SetPercent changes the share of traffic of a canary; 100 is a full
+// rollout, 0 stops the canary.
+func (c *Canaries) SetPercent(ctx context.Context, name string, percent int) (*Rollout, error) {
+	if percent < 0 || percent > 100 {
+		return nil, ErrInvalid
+	}
+	if _, err := c.Get(name); err != nil {
+		return nil, err
+	}
+	if err := c.store.SavePercent(ctx, name, percent); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rollouts[name].Percent = percent
+	if c.overrides != nil {
+		c.overrides[name] = percent
+	}
+	copied := *c.rollouts[name]
+	return &copied, nil
+}
+
+// Record saves a comparison, with the similarity of its answers.
+func (c *Canaries) Record(ctx context.Context, comparison *Comparison) error {
+	comparison.Similarity = Similarity(comparison.CanaryAnswer, comparison.IncumbentAnswer)
+	comparison.CanaryAnswer = truncate(comparison.CanaryAnswer)
+	comparison.IncumbentAnswer = truncate(comparison.IncumbentAnswer)
+	if err := c.store.SaveComparison(ctx, comparison); err != nil {
+		return fmt.Errorf("failed to record canary comparison: %w", err)
+	}
+	return nil
+}
+
+// Comparisons returns the latest comparisons of a canary.
+func (c *Canaries) Comparisons(ctx context.Context, name string, limit int) ([]*Comparison, error) {
+	if _, err := c.Get(name); err != nil {
+		return nil, err
+	}
+	return c.store.ListComparisons(ctx, name, limit)
+}
+
+// Metrics returns the comparison metrics of a canary since a Unix time.
+func (c *Canaries) Metrics(ctx context.Context, name string, since int64) (*Metrics, error) {
+	if _, err := c.Get(name); err != nil {
+		return nil, err
+	}
+	return c.store.Metrics(ctx, name, since)
+}
+
+// Similarity returns the Dice coefficient of the character bigrams of two
+// answers, ignoring case, spaces and punctuation: 1 for the same text, 0 for
+// texts without a bigram in common.
+func Similarity(a, b string) float64 {
+	bigramsA, sizeA := bigrams(a)
+	bigramsB, sizeB := bigrams(b)
+	if sizeA == 0 && sizeB == 0 {
+		return 1
+	}
+	if sizeA == 0 || sizeB == 0 {
+		return 0
+	}
+	shared := 0
+	for bigram, count := range bigramsA {
+		shared += min(count, bigramsB[bigram])
+	}
+	return 2 * float64(shared) / float64(sizeA+sizeB)
+}
+
+func bigrams(text string) (map[string]int, int) {
+	var runes []rune
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, r)
+		}
+	}
+	counts := make(map[string]int)
+	for i := 0; i+1 < len(runes); i++ {
+		counts[string(runes[i:i+2])]++
+	}
+	if len(runes) == 1 {
+		counts[string(runes)]++
+		return counts, 1
+	}
+	return counts, max(len(runes)-1, 0)
+}
+
+func truncate(answer string) string {
+	if utf8.RuneCountInString(answer) <= maxAnswerLength {
+		return answer
+	}
+	return string([]rune(answer)[:maxAnswerLength])
+}
@@ -0,0 +1,168 @@
+package canary
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	percents    map[string]int
+	comparisons []*Comparison
+}
+
+func (s *fakeStore) ListPercents(_ context.Context) (map[string]int, error) {
+	percents := make(map[string]int, len(s.percents))
+	for name, percent := range s.percents {
+		percents[name] = percent
+	}
+	return percents, nil
+}
+
+func (s *fakeStore) SavePercent(_ context.Context, name string, percent int) error {
+	if s.percents == nil {
+		s.percents = make(map[string]int)
+	}
+	s.percents[name] = percent
+	return nil
+}
+
+func (s *fakeStore) SaveComparison(_ context.Context, comparison *Comparison) error {
+	s.comparisons = append(s.comparisons, comparison)
+	return nil
+}
+
+func (s *fakeStore) ListComparisons(_ context.Context, name string, limit int) ([]*Comparison, error) {
+	var comparisons []*Comparison
+	for _, comparison := range s.comparisons {
+		if comparison.Canary == name && len(comparisons) < limit {
+			comparisons = append(comparisons, comparison)
+		}
+	}
+	return comparisons, nil
+}
+
+func (s *fakeStore) Metrics(_ context.Context, name string, _ int64) (*Metrics, error) {
+	m := &Metrics{}
+	for _, comparison := range s.comparisons {
+		if comparison.Canary == name {
+			m.Requests++
+		}
+	}
+	return m, nil
+}
+
+func TestCanaries_Register(t *testing.T) {
+	ctx := context.Background()
+	canaries := New(&fakeStore{percents: map[string]int{"memo_v2": 40}})
+
+	canaries.Register(ctx, "memo_v2", "memo", 10)
+	canaries.Register(ctx, "schedule_v2", "schedule", 150)
+
+	// The percentage set by admins wins over the configured one
+	rollout, err := canaries.Get("memo_v2")
+	require.NoError(t, err)
+	assert.Equal(t, 40, rollout.Percent)
+	rollout, err = canaries.Get("schedule_v2")
+	require.NoError(t, err)
+	assert.Equal(t, 100, rollout.Percent)
+
+	rollouts := canaries.List()
+	require.Len(t, rollouts, 2)
+	assert.Equal(t, "memo_v2", rollouts[0].Name)
+
+	_, err = canaries.Get("general_v2")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestCanaries_Pick(t *testing.T) {
+	ctx := context.Background()
+	canaries := New(&fakeStore{})
+	canaries.Register(ctx, "memo_v2", "memo", 30)
+
+	picked := 0
+	for userID := int32(1); userID <= 1000; userID++ {
+		name, ok := canaries.Pick("memo", userID)
+		if ok {
+			assert.Equal(t, "memo_v2", name)
+			picked++
+		}
+		// Users consistently get the same expert
+		_, again := canaries.Pick("memo", userID)
+		assert.Equal(t, ok, again)
+	}
+	assert.InDelta(t, 300, picked, 60)
+
+	_, ok := canaries.Pick("schedule", 1)
+	assert.False(t, ok)
+
+	_, err := canaries.SetPercent(ctx, "memo_v2", 0)
+	require.NoError(t, err)
+	for userID := int32(1); userID <= 100; userID++ {
+		_, ok := canaries.Pick("memo", userID)
+		assert.False(t, ok)
+	}
+}
+
+func TestCanaries_SetPercent(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{}
+	canaries := New(store)
+	canaries.Register(ctx, "memo_v2", "memo", 10)
+
+	_, err := canaries.SetPercent(ctx, "memo_v2", 101)
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = canaries.SetPercent(ctx, "general_v2", 50)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	rollout, err := canaries.SetPercent(ctx, "memo_v2", 100)
+	require.NoError(t, err)
+	assert.Equal(t, 100, rollout.Percent)
+	assert.Equal(t, 100, store.percents["memo_v2"])
+
+	// The override survives a new registration of the config
+	canaries.Register(ctx, "memo_v2", "memo", 10)
+	rollout, err = canaries.Get("memo_v2")
+	require.NoError(t, err)
+	assert.Equal(t, 100, rollout.Percent)
+}
+
+func TestCanaries_Record(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{}
+	canaries := New(store)
+	canaries.Register(ctx, "memo_v2", "memo", 10)
+
+	require.NoError(t, canaries.Record(ctx, &Comparison{
+		Canary:          "memo_v2",
+		Incumbent:       "memo",
+		CanaryAnswer:    strings.Repeat("周报", maxAnswerLength),
+		IncumbentAnswer: "找到 2 条周报",
+	}))
+	require.Len(t, store.comparisons, 1)
+	assert.Equal(t, maxAnswerLength, len([]rune(store.comparisons[0].CanaryAnswer)))
+	assert.Greater(t, store.comparisons[0].Similarity, 0.0)
+
+	comparisons, err := canaries.Comparisons(ctx, "memo_v2", 10)
+	require.NoError(t, err)
+	assert.Len(t, comparisons, 1)
+	metrics, err := canaries.Metrics(ctx, "memo_v2", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, metrics.Requests)
+
+	_, err = canaries.Comparisons(ctx, "general_v2", 10)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, Similarity("找到 2 条周报。", "找到2条周报"))
+	assert.Equal(t, 1.0, Similarity("", ""))
+	assert.Equal(t, 0.0, Similarity("找到 2 条周报", ""))
+	assert.Equal(t, 0.0, Similarity("周报", "日程"))
+	similarity := Similarity("找到 2 条周报", "找到 3 条周报")
+	assert.Greater(t, similarity, 0.5)
+	assert.Less(t, similarity, 1.0)
+}
@@ -0,0 +1,118 @@
+package canary
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DBStore persists canaries in the canary_rollout and canary_comparison
+// tables (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new canary store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// ListPercents implements Store.
+func (s *DBStore) ListPercents(ctx context.Context) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT name, percent FROM canary_rollout")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list canary percents: %w", err)
+	}
+	defer rows.Close()
+
+	percents := make(map[string]int)
+	for rows.Next() {
+		var name string
+		var percent int
+		if err := rows.Scan(&name, &percent); err != nil {
+			return nil, fmt.Errorf("failed to scan canary percent: %w", err)
+		}
+		percents[name] = percent
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list canary percents: %w", err)
+	}
+	return percents, nil
+}
+
+// SavePercent implements Store.
+func (s *DBStore) SavePercent(ctx context.Context, name string, percent int) error {
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO canary_rollout (name, percent, updated_ts) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET percent = EXCLUDED.percent, updated_ts = EXCLUDED.updated_ts`,
+		name, percent, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to save canary percent: %w", err)
+	}
+	return nil
+}
+
+const comparisonColumns = `id, canary, incumbent, user_id, query, canary_answer, incumbent_answer,
+	canary_latency_ms, incumbent_latency_ms, canary_tokens, incumbent_tokens,
+	canary_error, incumbent_error, similarity, created_ts`
+
+// SaveComparison implements Store.
+func (s *DBStore) SaveComparison(ctx context.Context, c *Comparison) error {
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO canary_comparison (canary, incumbent, user_id, query, canary_answer, incumbent_answer,
+			canary_latency_ms, incumbent_latency_ms, canary_tokens, incumbent_tokens,
+			canary_error, incumbent_error, similarity, created_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		c.Canary, c.Incumbent, c.UserID, c.Query, c.CanaryAnswer, c.IncumbentAnswer,
+		c.CanaryLatencyMs, c.IncumbentLatencyMs, c.CanaryTokens, c.IncumbentTokens,
+		c.CanaryError, c.IncumbentError, c.Similarity, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to save canary comparison: %w", err)
+	}
+	return nil
+}
+
+// ListComparisons implements Store.
+func (s *DBStore) ListComparisons(ctx context.Context, name string, limit int) ([]*Comparison, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+comparisonColumns+`
+		FROM canary_comparison WHERE canary = $1 ORDER BY id DESC LIMIT $2`, name, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list canary comparisons: %w", err)
+	}
+	defer rows.Close()
+
+	comparisons := []*Comparison{}
+	for rows.Next() {
+		c := &Comparison{}
+		if err := rows.Scan(&c.ID, &c.Canary, &c.Incumbent, &c.UserID, &c.Query, &c.CanaryAnswer, &c.IncumbentAnswer,
+			&c.CanaryLatencyMs, &c.IncumbentLatencyMs, &c.CanaryTokens, &c.IncumbentTokens,
+			&c.CanaryError, &c.IncumbentError, &c.Similarity, &c.CreatedTs); err != nil {
+			return nil, fmt.Errorf("failed to scan canary comparison: %w", err)
+		}
+		comparisons = append(comparisons, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list canary comparisons: %w", err)
+	}
+	return comparisons, nil
+}
+
+// Metrics implements Store.
+func (s *DBStore) Metrics(ctx context.Context, name string, since int64) (*Metrics, error) {
+	m := &Metrics{}
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*),
+			COALESCE(AVG(canary_latency_ms), 0), COALESCE(AVG(incumbent_latency_ms), 0),
+			COALESCE(AVG(CASE WHEN canary_error <> '' THEN 1 ELSE 0 END), 0),
+			COALESCE(AVG(CASE WHEN incumbent_error <> '' THEN 1 ELSE 0 END), 0),
+			COALESCE(AVG(canary_tokens), 0), COALESCE(AVG(incumbent_tokens), 0),
+			COALESCE(AVG(LENGTH(canary_answer)), 0), COALESCE(AVG(LENGTH(incumbent_answer)), 0),
+			COALESCE(AVG(similarity), 0)
+		FROM canary_comparison WHERE canary = $1 AND created_ts >= $2`, name, since).Scan(
+		&m.Requests, &m.CanaryAvgLatencyMs, &m.IncumbentAvgLatencyMs,
+		&m.CanaryErrorRate, &m.IncumbentErrorRate,
+		&m.CanaryAvgTokens, &m.IncumbentAvgTokens,
+		&m.CanaryAvgLength, &m.IncumbentAvgLength, &m.AvgSimilarity); err != nil {
+		return nil, fmt.Errorf("failed to aggregate canary comparisons: %w", err)
+	}
+	return m, nil
+}
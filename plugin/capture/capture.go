@@ -0,0 +1,204 @@
+// Package capture turns what a browser extension captures on a web page,
+// the page URL, the selected text and a screenshot, into a memo that keeps
+// track of its source page.
+package capture
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hrygo/divinesense/plugin/i18n"
+)
+
+const (
+	// maxSelectionRunes bounds the selected text kept in the memo.
+	maxSelectionRunes = 20000
+	// maxTitleRunes bounds the page title.
+	maxTitleRunes = 500
+	// maxClientRunes bounds the client name reported by the extension.
+	maxClientRunes = 100
+)
+
+// screenshotTypes are the accepted screenshot formats, by MIME type, with
+// the extension of their file.
+var screenshotTypes = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpg",
+	"image/webp": "webp",
+}
+
+// Clip is what an extension captured on a page.
+type Clip struct {
+	URL       string `json:"url"`
+	Title     string `json:"title"`
+	Selection string `json:"selection"`
+	Note      string `json:"note"`
+	// Screenshot is a data URL, e.g. "data:image/png;base64,...".
+	Screenshot string   `json:"screenshot"`
+	Tags       []string `json:"tags"`
+	// Visibility of the memo: PRIVATE (default), PROTECTED or PUBLIC.
+	Visibility string `json:"visibility"`
+	// Summarize adds an AI summary of the selection to the memo.
+	Summarize bool `json:"summarize"`
+	// ReadLater adds the memo to the read-later queue.
+	ReadLater bool `json:"readLater"`
+	// Client identifies the extension, e.g. "divinesense-chrome/1.0.0".
+	Client string `json:"client"`
+}
+
+// Source is the page a memo was captured from.
+type Source struct {
+	MemoID    int32  `json:"-"`
+	MemoUID   string `json:"memoUid"`
+	CreatorID int32  `json:"-"`
+	URL       string `json:"url"`
+	Title     string `json:"title"`
+	Site      string `json:"site"`
+	Client    string `json:"client"`
+	CreatedTs int64  `json:"createdTs"`
+}
+
+// Normalize validates a clip and trims its fields.
+func (c *Clip) Normalize() error {
+	c.URL = strings.TrimSpace(c.URL)
+	if c.URL == "" {
+		return errors.New("url is required")
+	}
+	page, err := url.Parse(c.URL)
+	if err != nil || (page.Scheme != "http" && page.Scheme != "https") || page.Host == "" {
+		return errors.New("url must be an http or https URL")
+	}
+	c.URL = page.String()
+	c.Title = truncate(strings.Join(strings.Fields(c.Title), " "), maxTitleRunes)
+	c.Selection = truncate(strings.TrimSpace(c.Selection), maxSelectionRunes)
+	c.Note = strings.TrimSpace(c.Note)
+	c.Client = truncate(strings.TrimSpace(c.Client), maxClientRunes)
+	c.Visibility = strings.ToUpper(strings.TrimSpace(c.Visibility))
+	if c.Visibility == "" {
+		c.Visibility = "PRIVATE"
+	}
+	switch c.Visibility {
+	case "PRIVATE", "PROTECTED", "PUBLIC":
+	default:
+		return fmt.Errorf("visibility must be PRIVATE, PROTECTED or PUBLIC")
+	}
+	return nil
+}
+
+// Site returns the host name of the clip's page.
+func (c *Clip) Site() string {
+	page, err := url.Parse(c.URL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(page.Hostname(), "www.")
+}
+
+// Content renders the memo of a clip: the note, the quoted selection, the
+// AI summary if any, labeled in the locale of the user, and a link to the
+// page.
+func (c *Clip) Content(summary string, locale i18n.Locale) string {
+	var parts []string
+	if c.Note != "" {
+		parts = append(parts, c.Note)
+	}
+	if c.Selection != "" {
+		parts = append(parts, quote(c.Selection))
+	}
+	if summary = strings.TrimSpace(summary); summary != "" {
+		parts = append(parts, quote(i18n.T(locale, "capture.summary", summary)))
+	}
+	title := c.Title
+	if title == "" {
+		title = c.URL
+	}
+	link := strings.NewReplacer("(", "%28", ")", "%29").Replace(c.URL)
+	parts = append(parts, fmt.Sprintf("🔗 [%s](%s)", escapeLinkText(title), link))
+	return strings.Join(parts, "\n\n")
+}
+
+// Screenshot is a decoded screenshot.
+type Screenshot struct {
+	Data     []byte
+	MimeType string
+	Filename string
+}
+
+// DecodeScreenshot decodes the screenshot data URL of a clip, or returns
+// nil without screenshot.
+func (c *Clip) DecodeScreenshot() (*Screenshot, error) {
+	if c.Screenshot == "" {
+		return nil, nil
+	}
+	header, payload, ok := strings.Cut(c.Screenshot, ",")
+	mimeType, isBase64 := strings.CutSuffix(strings.TrimPrefix(header, "data:"), ";base64")
+	if !ok || !strings.HasPrefix(header, "data:") || !isBase64 {
+		return nil, errors.New("screenshot must be a base64 data URL")
+	}
+	ext, ok := screenshotTypes[mimeType]
+	if !ok {
+		return nil, errors.New("screenshot must be a PNG, JPEG or WebP image")
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, errors.New("screenshot is not valid base64")
+	}
+	filename := "screenshot." + ext
+	if site := c.Site(); site != "" {
+		filename = fmt.Sprintf("screenshot-%s.%s", site, ext)
+	}
+	return &Screenshot{Data: data, MimeType: mimeType, Filename: filename}, nil
+}
+
+// extensionSchemes are the origin schemes of browser extensions.
+var extensionSchemes = []string{"chrome-extension://", "moz-extension://", "safari-web-extension://"}
+
+// AllowOrigin reports whether a browser origin may call the capture API:
+// one of the allowed origins if any are configured, any browser extension
+// otherwise.
+func AllowOrigin(origin string, allowed []string) bool {
+	if len(allowed) > 0 {
+		return slices.Contains(allowed, origin)
+	}
+	for _, scheme := range extensionSchemes {
+		if strings.HasPrefix(origin, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseOrigins parses a comma-separated list of origins.
+func ParseOrigins(list string) []string {
+	var origins []string
+	for _, origin := range strings.Split(list, ",") {
+		if origin = strings.TrimRight(strings.TrimSpace(origin), "/"); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+func quote(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func escapeLinkText(text string) string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`).Replace(text)
+}
+
+func truncate(text string, maxRunes int) string {
+	if utf8.RuneCountInString(text) <= maxRunes {
+		return text
+	}
+	return string([]rune(text)[:maxRunes]) + "…"
+}
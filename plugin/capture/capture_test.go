@@ -0,0 +1,84 @@
+package capture
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/plugin/i18n"
+)
+
+func TestNormalize(t *testing.T) {
+	clip := &Clip{URL: " https://www.example.com/a b ", Title: "  Go\n  generics ", Visibility: "public"}
+	require.NoError(t, clip.Normalize())
+	assert.Equal(t, "https://www.example.com/a%20b", clip.URL)
+	assert.Equal(t, "Go generics", clip.Title)
+	assert.Equal(t, "PUBLIC", clip.Visibility)
+	assert.Equal(t, "example.com", clip.Site())
+
+	clip = &Clip{URL: "https://example.com"}
+	require.NoError(t, clip.Normalize())
+	assert.Equal(t, "PRIVATE", clip.Visibility)
+
+	for _, invalid := range []*Clip{
+		{},
+		{URL: "javascript:alert(1)"},
+		{URL: "file:///etc/passwd"},
+		{URL: "https://example.com", Visibility: "WORKSPACE"},
+	} {
+		assert.Error(t, invalid.Normalize(), invalid.URL)
+	}
+}
+
+func TestContent(t *testing.T) {
+	clip := &Clip{
+		URL:       "https://en.wikipedia.org/wiki/Go_(programming_language)",
+		Title:     "Go [language]",
+		Selection: "Go is statically typed.\n\nIt is compiled.",
+		Note:      "Read later",
+	}
+	assert.Equal(t, "Read later\n\n"+
+		"> Go is statically typed.\n>\n> It is compiled.\n\n"+
+		"> 🤖 **AI 摘要**：Go 是静态类型语言。\n\n"+
+		"🔗 [Go \\[language\\]](https://en.wikipedia.org/wiki/Go_%28programming_language%29)",
+		clip.Content(" Go 是静态类型语言。 ", i18n.LocaleSimplifiedChinese))
+	assert.Contains(t, clip.Content("Go is typed.", i18n.LocaleEnglish), "> 🤖 **AI summary**: Go is typed.\n\n")
+
+	bookmark := &Clip{URL: "https://example.com"}
+	assert.Equal(t, "🔗 [https://example.com](https://example.com)", bookmark.Content("", i18n.LocaleEnglish))
+}
+
+func TestDecodeScreenshot(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n")
+	clip := &Clip{URL: "https://www.example.com/page", Screenshot: "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)}
+	screenshot, err := clip.DecodeScreenshot()
+	require.NoError(t, err)
+	assert.Equal(t, &Screenshot{Data: png, MimeType: "image/png", Filename: "screenshot-example.com.png"}, screenshot)
+
+	screenshot, err = (&Clip{}).DecodeScreenshot()
+	assert.NoError(t, err)
+	assert.Nil(t, screenshot)
+
+	for _, invalid := range []string{
+		"aGVsbG8=",
+		"data:image/png,raw",
+		"data:image/svg+xml;base64,PHN2Zz4=",
+		"data:image/png;base64,not base64!",
+	} {
+		_, err := (&Clip{Screenshot: invalid}).DecodeScreenshot()
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestAllowOrigin(t *testing.T) {
+	assert.True(t, AllowOrigin("chrome-extension://abcdefghijklmnop", nil))
+	assert.True(t, AllowOrigin("moz-extension://2f3c0c4e-1111-2222-3333-444455556666", nil))
+	assert.False(t, AllowOrigin("https://evil.example", nil))
+
+	allowed := ParseOrigins(" chrome-extension://abcdefghijklmnop/ , ,https://notes.example.com")
+	assert.Equal(t, []string{"chrome-extension://abcdefghijklmnop", "https://notes.example.com"}, allowed)
+	assert.True(t, AllowOrigin("https://notes.example.com", allowed))
+	assert.False(t, AllowOrigin("chrome-extension://other", allowed))
+}
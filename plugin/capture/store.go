@@ -0,0 +1,52 @@
+package capture
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DBStore persists the source of captured memos in the memo_source table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new source store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// SaveSource records the page a memo was captured from. The memo is
+// identified by source.MemoUID.
+func (s *DBStore) SaveSource(ctx context.Context, source *Source) error {
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO memo_source (memo_id, creator_id, url, title, site, client, created_ts)
+		SELECT id, creator_id, $2, $3, $4, $5, $6 FROM memo WHERE uid = $1
+		ON CONFLICT (memo_id) DO UPDATE SET url = EXCLUDED.url, title = EXCLUDED.title,
+			site = EXCLUDED.site, client = EXCLUDED.client`,
+		source.MemoUID, source.URL, source.Title, source.Site, source.Client, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to save memo source: %w", err)
+	}
+	return nil
+}
+
+// GetSource returns the source of a memo of a user by memo UID, or nil if
+// the memo was not captured from a page.
+func (s *DBStore) GetSource(ctx context.Context, creatorID int32, memoUID string) (*Source, error) {
+	source := &Source{}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT s.memo_id, m.uid, s.creator_id, s.url, s.title, s.site, s.client, s.created_ts
+		FROM memo_source s
+		JOIN memo m ON m.id = s.memo_id
+		WHERE s.creator_id = $1 AND m.uid = $2`, creatorID, memoUID).
+		Scan(&source.MemoID, &source.MemoUID, &source.CreatorID, &source.URL, &source.Title, &source.Site, &source.Client, &source.CreatedTs)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get memo source: %w", err)
+	}
+	return source, nil
+}
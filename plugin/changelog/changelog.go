@@ -0,0 +1,256 @@
+// Package changelog serves the release notes of the instance in the product
+// and tracks the latest release each user has seen, so that clients show what
+// is new after an upgrade.
+//
+// The release notes are parsed from CHANGELOG.md, embedded in the binary: a
+// release is a "## [vX.Y.Z] - date" heading, its summary a quote, and its
+// changes bullet lists grouped by "###" (and "####") headings.
+package changelog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hrygo/divinesense/internal/version"
+)
+
+var (
+	// ErrNotFound is returned for releases missing from the release notes.
+	ErrNotFound = errors.New("release not found")
+)
+
+// Release is a release with its notes.
+type Release struct {
+	// Version is the semantic version, without the "v" prefix.
+	Version string `json:"version"`
+	Date    string `json:"date"`
+	// Title is the text of the heading after the date, if any.
+	Title string `json:"title,omitempty"`
+	// Summary is the quoted summary of the release.
+	Summary string `json:"summary,omitempty"`
+	// Notes is the text before the first section.
+	Notes    string     `json:"notes,omitempty"`
+	Sections []*Section `json:"sections"`
+	// Markdown is the full notes of the release, for clients rendering them
+	// as they are.
+	Markdown string `json:"markdown"`
+}
+
+// Section is a group of changes, e.g. "✨ New Features".
+type Section struct {
+	Title string `json:"title"`
+	// Subtitle is the "####" heading of subsections, under Title.
+	Subtitle string `json:"subtitle,omitempty"`
+	// Items are the changes, in Markdown; nested bullets stay in their item.
+	Items []string `json:"items"`
+	// Text is the rest of the section, e.g. paragraphs and code blocks.
+	Text string `json:"text,omitempty"`
+}
+
+var releaseHeading = regexp.MustCompile(`^## \[v?([^\]]+)\](?:\s*-\s*(\S+))?\s*(.*)$`)
+
+// Parse parses the releases of a changelog, latest first as written.
+func Parse(markdown string) []*Release {
+	var releases []*Release
+	var release *Release
+	var section *Section
+	var body, notes, text []string
+	inCode := false
+
+	flush := func() {
+		if section != nil {
+			section.Text = strings.TrimSpace(strings.Join(text, "\n"))
+		}
+		text = nil
+	}
+	finish := func() {
+		if release == nil {
+			return
+		}
+		flush()
+		release.Notes = strings.TrimSpace(strings.Join(notes, "\n"))
+		release.Markdown = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(strings.Join(body, "\n")), "---"))
+		releases = append(releases, release)
+		notes, body = nil, nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		if !inCode {
+			if m := releaseHeading.FindStringSubmatch(line); m != nil {
+				finish()
+				release = &Release{Version: m[1], Date: m[2], Title: strings.TrimSpace(m[3]), Sections: []*Section{}}
+				section = nil
+				continue
+			}
+		}
+		if release == nil {
+			continue
+		}
+		body = append(body, line)
+
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+		}
+		switch {
+		case inCode || strings.HasPrefix(trimmed, "```"):
+			if section != nil {
+				text = append(text, line)
+			} else {
+				notes = append(notes, line)
+			}
+		case strings.HasPrefix(line, "#### "):
+			flush()
+			title := ""
+			if section != nil {
+				title = section.Title
+			}
+			section = &Section{Title: title, Subtitle: strings.TrimSpace(line[5:]), Items: []string{}}
+			release.Sections = append(release.Sections, section)
+		case strings.HasPrefix(line, "### "):
+			flush()
+			section = &Section{Title: strings.TrimSpace(line[4:]), Items: []string{}}
+			release.Sections = append(release.Sections, section)
+		case trimmed == "---" || trimmed == "":
+		case section == nil && strings.HasPrefix(line, ">"):
+			quote := strings.TrimSpace(strings.TrimPrefix(line, ">"))
+			release.Summary = strings.TrimSpace(release.Summary + "\n" + quote)
+		case section == nil:
+			notes = append(notes, line)
+		case strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* "):
+			section.Items = append(section.Items, strings.TrimSpace(line[2:]))
+		case strings.HasPrefix(line, " ") && len(section.Items) > 0 && len(text) == 0:
+			// Nested bullets and wrapped lines of the last item
+			section.Items[len(section.Items)-1] += "\n" + line
+		default:
+			text = append(text, line)
+		}
+	}
+	finish()
+	return releases
+}
+
+// Store persists the latest release each user has seen.
+type Store interface {
+	// GetSeen returns the latest version a user has seen, or "" if none.
+	GetSeen(ctx context.Context, userID int32) (string, error)
+	SetSeen(ctx context.Context, userID int32, version string, seenTs int64) error
+}
+
+// Config configures the changelog.
+type Config struct {
+	// Version is the running version (version.GetCurrentVersion).
+	Version string
+	// Markdown is the changelog.
+	Markdown string
+}
+
+// Changelog serves the release notes up to the running version.
+type Changelog struct {
+	store   Store
+	current string
+	// releases are the releases up to the running version, latest first.
+	releases []*Release
+	now      func() time.Time
+}
+
+// New creates the changelog. Releases newer than the running version, e.g.
+// unreleased notes, are left out, unless the running version predates every
+// release, as development builds do.
+func New(store Store, config Config) *Changelog {
+	current := strings.TrimPrefix(config.Version, "v")
+	all := Parse(config.Markdown)
+	var releases []*Release
+	for _, release := range all {
+		if !version.IsVersionGreaterThan(release.Version, current) {
+			releases = append(releases, release)
+		}
+	}
+	if len(releases) == 0 {
+		releases = all
+	}
+	return &Changelog{store: store, current: current, releases: releases, now: time.Now}
+}
+
+// Version returns the running version.
+func (c *Changelog) Version() string {
+	return c.current
+}
+
+// Releases returns the latest releases, at most limit if positive.
+func (c *Changelog) Releases(limit int) []*Release {
+	if limit > 0 && limit < len(c.releases) {
+		return c.releases[:limit]
+	}
+	return c.releases
+}
+
+// Release returns a release by version, with or without the "v" prefix.
+func (c *Changelog) Release(v string) (*Release, error) {
+	v = strings.TrimPrefix(v, "v")
+	for _, release := range c.releases {
+		if release.Version == v {
+			return release, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, v)
+}
+
+// WhatsNew is what is new for a user since the latest release they have seen.
+type WhatsNew struct {
+	CurrentVersion string `json:"current_version"`
+	// LastSeenVersion is "" for users who have seen no release.
+	LastSeenVersion string `json:"last_seen_version"`
+	// Releases are the releases the user has not seen, latest first.
+	Releases []*Release `json:"releases"`
+}
+
+// WhatsNew returns the releases a user has not seen. Users who have seen
+// none get the latest release only, not the whole history.
+func (c *Changelog) WhatsNew(ctx context.Context, userID int32) (*WhatsNew, error) {
+	seen, err := c.store.GetSeen(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	news := &WhatsNew{CurrentVersion: c.current, LastSeenVersion: seen, Releases: []*Release{}}
+	if seen == "" {
+		news.Releases = append(news.Releases, c.Releases(1)...)
+		return news, nil
+	}
+	for _, release := range c.releases {
+		if version.IsVersionGreaterThan(release.Version, seen) {
+			news.Releases = append(news.Releases, release)
+		}
+	}
+	return news, nil
+}
+
+// MarkSeen records that a user has seen the notes up to a version, the
+// latest release if empty. Versions older than the one already seen are
+// ignored.
+func (c *Changelog) MarkSeen(ctx context.Context, userID int32, v string) (*WhatsNew, error) {
+	if v == "" {
+		if len(c.releases) == 0 {
+			return c.WhatsNew(ctx, userID)
+		}
+		v = c.releases[0].Version
+	}
+	release, err := c.Release(v)
+	if err != nil {
+		return nil, err
+	}
+	seen, err := c.store.GetSeen(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if seen == "" || version.IsVersionGreaterThan(release.Version, seen) {
+		if err := c.store.SetSeen(ctx, userID, release.Version, c.now().Unix()); err != nil {
+			return nil, err
+		}
+	}
+	return c.WhatsNew(ctx, userID)
+}
@@ -0,0 +1,125 @@
+package changelog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense"
+)
+
+const testChangelog = "# Changelog\n\nIntro.\n\n---\n\n" +
+	"## [v1.2.0] - 2026-03-01\n\n> Unreleased\n\n### ✨ New Features\n\n- **Later**: not yet\n\n---\n\n" +
+	"## [v1.1.0] - 2026-02-16 🐴 Spring\n\n> **Search upgrade**\n\nA major release.\n\n" +
+	"### ✨ New Features\n\n#### 🔍 Search\n- **Hybrid search**: vector + full text\n  - rerank\n- Filters\n\n" +
+	"### 📦 Database Migration\n\n```bash\npsql -f up.sql\n- not an item\n```\n\n---\n\n" +
+	"## [v1.0.0] - 2026-01-01\n\n### 🐛 Bug Fixes\n\n- Fixed login\n"
+
+type fakeStore struct {
+	seen map[int32]string
+}
+
+func (s *fakeStore) GetSeen(_ context.Context, userID int32) (string, error) {
+	return s.seen[userID], nil
+}
+
+func (s *fakeStore) SetSeen(_ context.Context, userID int32, version string, _ int64) error {
+	if s.seen == nil {
+		s.seen = make(map[int32]string)
+	}
+	s.seen[userID] = version
+	return nil
+}
+
+func versions(releases []*Release) []string {
+	var list []string
+	for _, release := range releases {
+		list = append(list, release.Version)
+	}
+	return list
+}
+
+func TestParse(t *testing.T) {
+	releases := Parse(testChangelog)
+	require.Equal(t, []string{"1.2.0", "1.1.0", "1.0.0"}, versions(releases))
+
+	release := releases[1]
+	assert.Equal(t, "2026-02-16", release.Date)
+	assert.Equal(t, "🐴 Spring", release.Title)
+	assert.Equal(t, "**Search upgrade**", release.Summary)
+	assert.Equal(t, "A major release.", release.Notes)
+	require.Len(t, release.Sections, 3)
+	assert.Equal(t, "✨ New Features", release.Sections[0].Title)
+	assert.Empty(t, release.Sections[0].Items)
+	assert.Equal(t, &Section{
+		Title:    "✨ New Features",
+		Subtitle: "🔍 Search",
+		Items:    []string{"**Hybrid search**: vector + full text\n  - rerank", "Filters"},
+	}, release.Sections[1])
+	assert.Equal(t, "📦 Database Migration", release.Sections[2].Title)
+	assert.Empty(t, release.Sections[2].Items)
+	assert.Equal(t, "```bash\npsql -f up.sql\n- not an item\n```", release.Sections[2].Text)
+	assert.NotContains(t, release.Markdown, "---")
+	assert.Contains(t, release.Markdown, "#### 🔍 Search")
+
+	assert.Equal(t, []string{"Fixed login"}, releases[2].Sections[0].Items)
+}
+
+func TestParse_EmbeddedChangelog(t *testing.T) {
+	releases := Parse(divinesense.Changelog)
+	require.NotEmpty(t, releases)
+	for _, release := range releases {
+		assert.NotEmpty(t, release.Version)
+		assert.NotEmpty(t, release.Markdown, release.Version)
+	}
+}
+
+func TestChangelog_Releases(t *testing.T) {
+	c := New(&fakeStore{}, Config{Version: "1.1.0", Markdown: testChangelog})
+	assert.Equal(t, []string{"1.1.0", "1.0.0"}, versions(c.Releases(0)))
+	assert.Equal(t, []string{"1.1.0"}, versions(c.Releases(1)))
+
+	release, err := c.Release("v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", release.Version)
+	_, err = c.Release("1.2.0")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Development builds get every release
+	dev := New(&fakeStore{}, Config{Version: "0.0.0-dev", Markdown: testChangelog})
+	assert.Len(t, dev.Releases(0), 3)
+}
+
+func TestChangelog_WhatsNew(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{}
+	c := New(store, Config{Version: "1.1.0", Markdown: testChangelog})
+
+	// New users get the latest release only
+	news, err := c.WhatsNew(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", news.CurrentVersion)
+	assert.Empty(t, news.LastSeenVersion)
+	assert.Equal(t, []string{"1.1.0"}, versions(news.Releases))
+
+	// After an upgrade, the releases since the last seen one
+	store.seen = map[int32]string{2: "0.9.0"}
+	news, err = c.WhatsNew(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.1.0", "1.0.0"}, versions(news.Releases))
+
+	news, err = c.MarkSeen(ctx, 2, "")
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", news.LastSeenVersion)
+	assert.Empty(t, news.Releases)
+
+	// Older versions do not roll back what was seen
+	news, err = c.MarkSeen(ctx, 2, "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", news.LastSeenVersion)
+
+	_, err = c.MarkSeen(ctx, 2, "9.9.9")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
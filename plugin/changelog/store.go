@@ -0,0 +1,43 @@
+package changelog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// DBStore persists the seen releases in the changelog_seen table
+// (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new changelog store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// GetSeen implements Store.
+func (s *DBStore) GetSeen(ctx context.Context, userID int32) (string, error) {
+	var version string
+	err := s.db.QueryRowContext(ctx, "SELECT version FROM changelog_seen WHERE user_id = $1", userID).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get seen release: %w", err)
+	}
+	return version, nil
+}
+
+// SetSeen implements Store.
+func (s *DBStore) SetSeen(ctx context.Context, userID int32, version string, seenTs int64) error {
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO changelog_seen (user_id, version, seen_ts) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET version = EXCLUDED.version, seen_ts = EXCLUDED.seen_ts`,
+		userID, version, seenTs); err != nil {
+		return fmt.Errorf("failed to set seen release: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,351 @@
+// Package chatimport parses the conversations exported by other assistants,
+// so that users moving to DivineSense keep their history.
+//
+// It reads the conversations.json of a ChatGPT data export and of a Claude
+// data export, and turns each conversation into rounds: the consecutive
+// messages of the user and the answer that follows them, as the blocks of a
+// conversation hold them. Only the text of the messages is kept; system
+// prompts, tool calls and attachments are dropped.
+package chatimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrInvalid is returned for exports that cannot be parsed.
+var ErrInvalid = errors.New("invalid conversation export")
+
+// Format is the format of an export, named after the assistant.
+type Format string
+
+const (
+	FormatChatGPT Format = "chatgpt"
+	FormatClaude  Format = "claude"
+)
+
+const (
+	// MaxConversations bounds the conversations of an export.
+	MaxConversations = 2000
+	// maxRounds bounds the rounds kept of a conversation; the last ones are
+	// dropped.
+	maxRounds = 1000
+	// maxTitleRunes bounds the titles of the conversations.
+	maxTitleRunes = 200
+	// fallbackTitleRunes bounds the titles taken from the first message.
+	fallbackTitleRunes = 50
+)
+
+// Conversation is a conversation of an export.
+type Conversation struct {
+	Source Format
+	// ID is the ID of the conversation in the export.
+	ID    string
+	Title string
+	// CreatedTs and UpdatedTs are in seconds.
+	CreatedTs int64
+	UpdatedTs int64
+	Rounds    []*Round
+}
+
+// Round is the messages of the user and the answer that follows them.
+type Round struct {
+	Inputs []*Input
+	// Answer joins the messages of the assistant; empty when the user had
+	// the last word.
+	Answer string
+	// AnsweredTs is the time of the last message of the answer, in ms.
+	AnsweredTs int64
+	// MessageIDs are the IDs of the messages of the round in the export.
+	MessageIDs []string
+}
+
+// Input is a message of the user.
+type Input struct {
+	Content string
+	// Ts is in ms.
+	Ts int64
+}
+
+// CreatedTs returns the time of the first message of the round, in seconds.
+func (r *Round) CreatedTs() int64 {
+	if len(r.Inputs) > 0 {
+		return r.Inputs[0].Ts / 1000
+	}
+	return r.AnsweredTs / 1000
+}
+
+// message is a message of an export, in the order of the conversation.
+type message struct {
+	id   string
+	user bool
+	text string
+	// ts is in ms; 0 when unknown.
+	ts int64
+}
+
+// Parse parses an export. An empty format is detected from the export,
+// which is an array of conversations or a single one. Conversations without
+// any message of the user are skipped and counted.
+func Parse(data []byte, format Format) (conversations []*Conversation, skipped int, err error) {
+	var raws []json.RawMessage
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		raws = []json.RawMessage{data}
+	} else if err := json.Unmarshal(data, &raws); err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if len(raws) > MaxConversations {
+		return nil, 0, fmt.Errorf("%w: more than %d conversations", ErrInvalid, MaxConversations)
+	}
+
+	for i, raw := range raws {
+		f := format
+		if f == "" {
+			if f, err = detect(raw); err != nil {
+				return nil, 0, fmt.Errorf("conversation %d: %w", i, err)
+			}
+		}
+		var conversation *Conversation
+		switch f {
+		case FormatChatGPT:
+			conversation, err = parseChatGPT(raw)
+		case FormatClaude:
+			conversation, err = parseClaude(raw)
+		default:
+			return nil, 0, fmt.Errorf("%w: unknown format %q", ErrInvalid, f)
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("conversation %d: %w", i, err)
+		}
+		if len(conversation.Rounds) == 0 {
+			skipped++
+			continue
+		}
+		conversations = append(conversations, conversation)
+	}
+	return conversations, skipped, nil
+}
+
+// detect returns the format of a conversation by its keys.
+func detect(raw json.RawMessage) (Format, error) {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &keys); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if _, ok := keys["mapping"]; ok {
+		return FormatChatGPT, nil
+	}
+	if _, ok := keys["chat_messages"]; ok {
+		return FormatClaude, nil
+	}
+	return "", fmt.Errorf("%w: neither a ChatGPT nor a Claude conversation", ErrInvalid)
+}
+
+// chatGPTConversation is a conversation of a ChatGPT export: a tree of
+// messages, of which the branch ending at current_node is the one shown.
+type chatGPTConversation struct {
+	ID          string                  `json:"id"`
+	Title       string                  `json:"title"`
+	CreateTime  float64                 `json:"create_time"`
+	UpdateTime  float64                 `json:"update_time"`
+	Mapping     map[string]*chatGPTNode `json:"mapping"`
+	CurrentNode string                  `json:"current_node"`
+}
+
+type chatGPTNode struct {
+	Message *struct {
+		ID     string `json:"id"`
+		Author struct {
+			Role string `json:"role"`
+		} `json:"author"`
+		Content struct {
+			ContentType string            `json:"content_type"`
+			Parts       []json.RawMessage `json:"parts"`
+		} `json:"content"`
+		CreateTime float64 `json:"create_time"`
+		Recipient  string  `json:"recipient"`
+		Metadata   struct {
+			Hidden bool `json:"is_visually_hidden_from_conversation"`
+		} `json:"metadata"`
+	} `json:"message"`
+	Parent string `json:"parent"`
+}
+
+func parseChatGPT(raw json.RawMessage) (*Conversation, error) {
+	var c chatGPTConversation
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if c.Mapping[c.CurrentNode] == nil {
+		return nil, fmt.Errorf("%w: current node %q not found", ErrInvalid, c.CurrentNode)
+	}
+
+	// Walk up from the current node; the guard stops on cycles.
+	var path []*chatGPTNode
+	for id := c.CurrentNode; id != "" && len(path) <= len(c.Mapping); {
+		node := c.Mapping[id]
+		if node == nil {
+			break
+		}
+		path = append(path, node)
+		id = node.Parent
+	}
+
+	messages := make([]*message, 0, len(path))
+	for i := len(path) - 1; i >= 0; i-- {
+		m := path[i].Message
+		if m == nil || m.Metadata.Hidden || (m.Recipient != "" && m.Recipient != "all") {
+			continue
+		}
+		if m.Author.Role != "user" && m.Author.Role != "assistant" {
+			continue
+		}
+		if m.Content.ContentType != "text" && m.Content.ContentType != "multimodal_text" {
+			continue
+		}
+		// Parts are strings, or objects for attachments.
+		var parts []string
+		for _, part := range m.Content.Parts {
+			var text string
+			if json.Unmarshal(part, &text) == nil && strings.TrimSpace(text) != "" {
+				parts = append(parts, text)
+			}
+		}
+		messages = append(messages, &message{
+			id:   m.ID,
+			user: m.Author.Role == "user",
+			text: strings.Join(parts, "\n\n"),
+			ts:   secondsToMs(m.CreateTime),
+		})
+	}
+	return newConversation(FormatChatGPT, c.ID, c.Title, secondsToMs(c.CreateTime), secondsToMs(c.UpdateTime), messages), nil
+}
+
+// claudeConversation is a conversation of a Claude export.
+type claudeConversation struct {
+	UUID      string `json:"uuid"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+	Messages  []struct {
+		UUID    string `json:"uuid"`
+		Sender  string `json:"sender"`
+		Text    string `json:"text"`
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		CreatedAt string `json:"created_at"`
+	} `json:"chat_messages"`
+}
+
+func parseClaude(raw json.RawMessage) (*Conversation, error) {
+	var c claudeConversation
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	messages := make([]*message, 0, len(c.Messages))
+	for _, m := range c.Messages {
+		if m.Sender != "human" && m.Sender != "assistant" {
+			continue
+		}
+		// The content blocks split the text from tool uses; older exports
+		// only have the text.
+		text := m.Text
+		if len(m.Content) > 0 {
+			var parts []string
+			for _, block := range m.Content {
+				if block.Type == "text" && strings.TrimSpace(block.Text) != "" {
+					parts = append(parts, block.Text)
+				}
+			}
+			text = strings.Join(parts, "\n\n")
+		}
+		messages = append(messages, &message{id: m.UUID, user: m.Sender == "human", text: text, ts: isoToMs(m.CreatedAt)})
+	}
+	return newConversation(FormatClaude, c.UUID, c.Name, isoToMs(c.CreatedAt), isoToMs(c.UpdatedAt), messages), nil
+}
+
+// newConversation groups messages into rounds. Messages without text and
+// the answers before the first message of the user are dropped; messages
+// without a time take the time of the previous one.
+func newConversation(source Format, id, title string, createdMs, updatedMs int64, messages []*message) *Conversation {
+	conversation := &Conversation{Source: source, ID: id}
+	var round *Round
+	ts := createdMs
+	for _, m := range messages {
+		if m.ts > 0 {
+			ts = m.ts
+		}
+		text := strings.TrimSpace(m.text)
+		if text == "" || (!m.user && round == nil) {
+			continue
+		}
+		if m.user && (round == nil || round.Answer != "") {
+			if len(conversation.Rounds) == maxRounds {
+				break
+			}
+			round = &Round{}
+			conversation.Rounds = append(conversation.Rounds, round)
+		}
+		if m.user {
+			round.Inputs = append(round.Inputs, &Input{Content: text, Ts: ts})
+		} else {
+			if round.Answer != "" {
+				round.Answer += "\n\n"
+			}
+			round.Answer += text
+			round.AnsweredTs = ts
+		}
+		round.MessageIDs = append(round.MessageIDs, m.id)
+	}
+	if len(conversation.Rounds) == 0 {
+		return conversation
+	}
+
+	first := conversation.Rounds[0]
+	if createdMs <= 0 || createdMs > first.Inputs[0].Ts {
+		createdMs = first.Inputs[0].Ts
+	}
+	last := conversation.Rounds[len(conversation.Rounds)-1]
+	lastTs := max(last.Inputs[len(last.Inputs)-1].Ts, last.AnsweredTs)
+	conversation.CreatedTs = createdMs / 1000
+	conversation.UpdatedTs = max(updatedMs, lastTs, createdMs) / 1000
+
+	conversation.Title = truncate(strings.Join(strings.Fields(title), " "), maxTitleRunes)
+	if conversation.Title == "" {
+		conversation.Title = truncate(strings.Join(strings.Fields(first.Inputs[0].Content), " "), fallbackTitleRunes)
+	}
+	return conversation
+}
+
+// secondsToMs converts the float seconds of ChatGPT exports.
+func secondsToMs(seconds float64) int64 {
+	if seconds <= 0 || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return 0
+	}
+	return int64(seconds * 1000)
+}
+
+// isoToMs converts the RFC 3339 times of Claude exports; 0 when invalid.
+func isoToMs(iso string) int64 {
+	t, err := time.Parse(time.RFC3339Nano, iso)
+	if err != nil {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func truncate(s string, runes int) string {
+	if utf8.RuneCountInString(s) <= runes {
+		return s
+	}
+	return string([]rune(s)[:runes])
+}
@@ -0,0 +1,108 @@
+package chatimport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chatGPTExport has a regenerated answer: the branch of current_node keeps
+// the second one.
+const chatGPTExport = `[{
+	"id": "c1",
+	"title": "Trip to Kyoto",
+	"create_time": 1700000000.5,
+	"update_time": 1700000100.25,
+	"current_node": "a2",
+	"mapping": {
+		"root": {"message": null, "parent": null, "children": ["sys"]},
+		"sys": {"message": {"id": "sys", "author": {"role": "system"}, "content": {"content_type": "text", "parts": ["You are ChatGPT"]}, "create_time": null}, "parent": "root"},
+		"u1": {"message": {"id": "u1", "author": {"role": "user"}, "content": {"content_type": "text", "parts": ["Plan a trip"]}, "create_time": 1700000001}, "parent": "sys"},
+		"a1": {"message": {"id": "a1", "author": {"role": "assistant"}, "content": {"content_type": "text", "parts": ["Old answer"]}, "create_time": 1700000002}, "parent": "u1"},
+		"a1b": {"message": {"id": "a1b", "author": {"role": "assistant"}, "content": {"content_type": "text", "parts": ["Day 1: temples"]}, "create_time": 1700000003}, "parent": "u1"},
+		"t1": {"message": {"id": "t1", "author": {"role": "assistant"}, "recipient": "browser", "content": {"content_type": "code", "text": "search"}, "create_time": 1700000004}, "parent": "a1b"},
+		"u2": {"message": {"id": "u2", "author": {"role": "user"}, "content": {"content_type": "multimodal_text", "parts": [{"asset_pointer": "file-1"}, "What about food?"]}, "create_time": 1700000010}, "parent": "t1"},
+		"u3": {"message": {"id": "u3", "author": {"role": "user"}, "content": {"content_type": "text", "parts": ["Vegetarian"]}, "create_time": 1700000011}, "parent": "u2"},
+		"a2": {"message": {"id": "a2", "author": {"role": "assistant"}, "content": {"content_type": "text", "parts": ["Try shojin ryori"]}, "create_time": 1700000020}, "parent": "u3"}
+	}
+}]`
+
+const claudeExport = `{
+	"uuid": "k1",
+	"name": "",
+	"created_at": "2024-05-01T10:00:00.000000Z",
+	"updated_at": "2024-05-01T10:05:00.000000Z",
+	"chat_messages": [
+		{"uuid": "m1", "sender": "human", "text": "Explain  goroutines\nbriefly", "content": [], "created_at": "2024-05-01T10:00:01Z"},
+		{"uuid": "m2", "sender": "assistant", "text": "ignored", "content": [{"type": "text", "text": "Lightweight threads."}, {"type": "tool_use", "name": "search"}, {"type": "text", "text": "Scheduled by Go."}], "created_at": "2024-05-01T10:00:05Z"},
+		{"uuid": "m3", "sender": "human", "text": "Thanks", "content": [], "created_at": "2024-05-01T10:01:00Z"}
+	]
+}`
+
+func TestParseChatGPT(t *testing.T) {
+	conversations, skipped, err := Parse([]byte(chatGPTExport), "")
+	require.NoError(t, err)
+	assert.Zero(t, skipped)
+	require.Len(t, conversations, 1)
+	c := conversations[0]
+	assert.Equal(t, FormatChatGPT, c.Source)
+	assert.Equal(t, "Trip to Kyoto", c.Title)
+	assert.Equal(t, int64(1700000000), c.CreatedTs)
+	assert.Equal(t, int64(1700000100), c.UpdatedTs)
+
+	require.Len(t, c.Rounds, 2)
+	first := c.Rounds[0]
+	assert.Equal(t, []*Input{{Content: "Plan a trip", Ts: 1700000001000}}, first.Inputs)
+	assert.Equal(t, "Day 1: temples", first.Answer, "the answer of the current branch")
+	assert.Equal(t, int64(1700000003000), first.AnsweredTs)
+	assert.Equal(t, []string{"u1", "a1b"}, first.MessageIDs)
+	assert.Equal(t, int64(1700000001), first.CreatedTs())
+
+	second := c.Rounds[1]
+	assert.Equal(t, []*Input{{Content: "What about food?", Ts: 1700000010000}, {Content: "Vegetarian", Ts: 1700000011000}}, second.Inputs)
+	assert.Equal(t, "Try shojin ryori", second.Answer)
+	assert.Equal(t, []string{"u2", "u3", "a2"}, second.MessageIDs)
+}
+
+func TestParseClaude(t *testing.T) {
+	conversations, _, err := Parse([]byte(claudeExport), FormatClaude)
+	require.NoError(t, err)
+	require.Len(t, conversations, 1)
+	c := conversations[0]
+	assert.Equal(t, FormatClaude, c.Source)
+	assert.Equal(t, "Explain goroutines briefly", c.Title, "untitled conversations are named after their first message")
+	assert.Equal(t, int64(1714557600), c.CreatedTs)
+	assert.Equal(t, int64(1714557900), c.UpdatedTs)
+
+	require.Len(t, c.Rounds, 2)
+	assert.Equal(t, "Lightweight threads.\n\nScheduled by Go.", c.Rounds[0].Answer)
+	assert.Equal(t, int64(1714557605000), c.Rounds[0].AnsweredTs)
+	assert.Equal(t, "Thanks", c.Rounds[1].Inputs[0].Content)
+	assert.Empty(t, c.Rounds[1].Answer, "the user had the last word")
+}
+
+func TestParseSkipsEmptyConversations(t *testing.T) {
+	export := `[` + claudeExport + `, {"uuid": "k2", "name": "Empty", "chat_messages": [
+		{"uuid": "m1", "sender": "assistant", "text": "Hello!", "created_at": "2024-05-01T10:00:01Z"}
+	]}]`
+	conversations, skipped, err := Parse([]byte(export), "")
+	require.NoError(t, err)
+	assert.Len(t, conversations, 1)
+	assert.Equal(t, 1, skipped)
+}
+
+func TestParseInvalid(t *testing.T) {
+	for name, export := range map[string]string{
+		"not json":       `conversations`,
+		"unknown format": `[{"id": "x", "messages": []}]`,
+		"no current":     `[{"id": "x", "mapping": {}, "current_node": "a"}]`,
+		"too many":       `[` + strings.Repeat(`{},`, MaxConversations) + `{}]`,
+	} {
+		_, _, err := Parse([]byte(export), "")
+		assert.ErrorIs(t, err, ErrInvalid, name)
+	}
+	_, _, err := Parse([]byte(claudeExport), "gemini")
+	assert.ErrorIs(t, err, ErrInvalid)
+}
@@ -0,0 +1,234 @@
+// Package checklist holds the checklists of users: packing lists, travel
+// preparations, moving checklists and the like.
+//
+// The checklist expert generates the items of a list and saves them with the
+// checklist_create tool, so the list outlives the answer: the user checks
+// items off in the UI or through the API, and follow-up requests ("add a
+// charger", "I packed the passports") update the same list with the
+// checklist_update tool.
+package checklist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	// ErrNotFound is returned for checklists and items the user does not have.
+	ErrNotFound = errors.New("checklist not found")
+	// ErrInvalid is returned for checklists and edits that fail validation.
+	ErrInvalid = errors.New("invalid checklist")
+)
+
+const (
+	// MaxItems bounds the items of a checklist.
+	MaxItems = 200
+	// maxTitleRunes and maxTextRunes bound the title of a checklist and the
+	// text of an item.
+	maxTitleRunes = 100
+	maxTextRunes  = 500
+)
+
+// Checklist is a list of items to check off.
+type Checklist struct {
+	ID        int32  `json:"id"`
+	UserID    int32  `json:"-"`
+	Title     string `json:"title"`
+	Items     []Item `json:"items"`
+	CreatedTs int64  `json:"created_ts"`
+	UpdatedTs int64  `json:"updated_ts"`
+}
+
+// Item is an entry of a checklist.
+type Item struct {
+	// ID identifies the item within its checklist.
+	ID      int32  `json:"id"`
+	Text    string `json:"text"`
+	Checked bool   `json:"checked"`
+	// Due is the day the item is due (YYYY-MM-DD), if any.
+	Due string `json:"due,omitempty"`
+}
+
+// Progress returns the number of checked items and of items.
+func (c *Checklist) Progress() (checked, total int) {
+	for _, item := range c.Items {
+		if item.Checked {
+			checked++
+		}
+	}
+	return checked, len(c.Items)
+}
+
+// Edit is a change to a checklist. Its parts apply in order: the title, the
+// item updates, the removals, then the new items.
+type Edit struct {
+	Title  *string    `json:"title,omitempty"`
+	Update []ItemEdit `json:"update,omitempty"`
+	Remove []int32    `json:"remove,omitempty"`
+	// Add are new items; their IDs are assigned.
+	Add []Item `json:"add,omitempty"`
+}
+
+// ItemEdit changes the fields of an item that are set. An empty Due clears it.
+type ItemEdit struct {
+	ID      int32   `json:"id"`
+	Text    *string `json:"text,omitempty"`
+	Checked *bool   `json:"checked,omitempty"`
+	Due     *string `json:"due,omitempty"`
+}
+
+// Apply applies the edit to a checklist, failing with ErrNotFound for items
+// it does not have.
+func (e *Edit) Apply(c *Checklist) error {
+	if e.Title != nil {
+		c.Title = *e.Title
+	}
+	for _, update := range e.Update {
+		item := c.item(update.ID)
+		if item == nil {
+			return fmt.Errorf("%w: no item %d", ErrNotFound, update.ID)
+		}
+		if update.Text != nil {
+			item.Text = *update.Text
+		}
+		if update.Checked != nil {
+			item.Checked = *update.Checked
+		}
+		if update.Due != nil {
+			item.Due = *update.Due
+		}
+	}
+	// New items do not take the IDs of the items removed by the same edit.
+	next := int32(1)
+	for _, item := range c.Items {
+		next = max(next, item.ID+1)
+	}
+	for _, id := range e.Remove {
+		i := c.index(id)
+		if i < 0 {
+			return fmt.Errorf("%w: no item %d", ErrNotFound, id)
+		}
+		c.Items = append(c.Items[:i], c.Items[i+1:]...)
+	}
+	for _, item := range e.Add {
+		item.ID = next
+		next++
+		c.Items = append(c.Items, item)
+	}
+	return nil
+}
+
+func (c *Checklist) index(id int32) int {
+	for i := range c.Items {
+		if c.Items[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *Checklist) item(id int32) *Item {
+	if i := c.index(id); i >= 0 {
+		return &c.Items[i]
+	}
+	return nil
+}
+
+// Validate checks and normalizes a checklist about to be saved.
+func (c *Checklist) Validate() error {
+	c.Title = strings.Join(strings.Fields(c.Title), " ")
+	if c.Title == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalid)
+	}
+	if utf8.RuneCountInString(c.Title) > maxTitleRunes {
+		return fmt.Errorf("%w: title exceeds %d characters", ErrInvalid, maxTitleRunes)
+	}
+	if len(c.Items) > MaxItems {
+		return fmt.Errorf("%w: at most %d items", ErrInvalid, MaxItems)
+	}
+	for i := range c.Items {
+		item := &c.Items[i]
+		item.Text = strings.TrimSpace(item.Text)
+		item.Due = strings.TrimSpace(item.Due)
+		if item.Text == "" {
+			return fmt.Errorf("%w: item %d has no text", ErrInvalid, item.ID)
+		}
+		if utf8.RuneCountInString(item.Text) > maxTextRunes {
+			return fmt.Errorf("%w: item %d exceeds %d characters", ErrInvalid, item.ID, maxTextRunes)
+		}
+		if item.Due != "" {
+			if _, err := time.Parse(time.DateOnly, item.Due); err != nil {
+				return fmt.Errorf("%w: due date of item %d must be YYYY-MM-DD", ErrInvalid, item.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// Store persists the checklists.
+type Store interface {
+	// ListChecklists returns the checklists of a user, most recently updated
+	// first.
+	ListChecklists(ctx context.Context, userID int32) ([]*Checklist, error)
+	// GetChecklist returns ErrNotFound for checklists the user does not have.
+	GetChecklist(ctx context.Context, userID, id int32) (*Checklist, error)
+	CreateChecklist(ctx context.Context, checklist *Checklist) (*Checklist, error)
+	// UpdateChecklist changes a checklist of a user with fn and saves it,
+	// with no concurrent update in between. It returns ErrNotFound for
+	// checklists the user does not have, and the errors of fn.
+	UpdateChecklist(ctx context.Context, userID, id int32, fn func(*Checklist) error) (*Checklist, error)
+	// DeleteChecklist returns ErrNotFound for checklists the user does not have.
+	DeleteChecklist(ctx context.Context, userID, id int32) error
+}
+
+// Checklists manages the checklists of the users.
+type Checklists struct {
+	store Store
+}
+
+// New creates the checklists.
+func New(store Store) *Checklists {
+	return &Checklists{store: store}
+}
+
+// List returns the checklists of a user, most recently updated first.
+func (c *Checklists) List(ctx context.Context, userID int32) ([]*Checklist, error) {
+	return c.store.ListChecklists(ctx, userID)
+}
+
+// Get returns a checklist of a user.
+func (c *Checklists) Get(ctx context.Context, userID, id int32) (*Checklist, error) {
+	return c.store.GetChecklist(ctx, userID, id)
+}
+
+// Create validates and saves a new checklist of a user. The IDs of its items
+// are assigned.
+func (c *Checklists) Create(ctx context.Context, userID int32, title string, items []Item) (*Checklist, error) {
+	checklist := &Checklist{UserID: userID, Title: title}
+	if err := (&Edit{Add: items}).Apply(checklist); err != nil {
+		return nil, err
+	}
+	if err := checklist.Validate(); err != nil {
+		return nil, err
+	}
+	return c.store.CreateChecklist(ctx, checklist)
+}
+
+// Edit applies an edit to a checklist of a user and returns it.
+func (c *Checklists) Edit(ctx context.Context, userID, id int32, edit *Edit) (*Checklist, error) {
+	return c.store.UpdateChecklist(ctx, userID, id, func(checklist *Checklist) error {
+		if err := edit.Apply(checklist); err != nil {
+			return err
+		}
+		return checklist.Validate()
+	})
+}
+
+// Delete removes a checklist of a user.
+func (c *Checklists) Delete(ctx context.Context, userID, id int32) error {
+	return c.store.DeleteChecklist(ctx, userID, id)
+}
@@ -0,0 +1,150 @@
+package checklist
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	checklists []*Checklist
+	nextID     int32
+}
+
+func (s *fakeStore) ListChecklists(_ context.Context, userID int32) ([]*Checklist, error) {
+	var checklists []*Checklist
+	for _, checklist := range slices.Backward(s.checklists) {
+		if checklist.UserID == userID {
+			checklists = append(checklists, checklist)
+		}
+	}
+	return checklists, nil
+}
+
+func (s *fakeStore) GetChecklist(_ context.Context, userID, id int32) (*Checklist, error) {
+	for _, checklist := range s.checklists {
+		if checklist.ID == id && checklist.UserID == userID {
+			copied := *checklist
+			copied.Items = slices.Clone(checklist.Items)
+			return &copied, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *fakeStore) CreateChecklist(_ context.Context, checklist *Checklist) (*Checklist, error) {
+	s.nextID++
+	checklist.ID = s.nextID
+	s.checklists = append(s.checklists, checklist)
+	return checklist, nil
+}
+
+func (s *fakeStore) UpdateChecklist(ctx context.Context, userID, id int32, fn func(*Checklist) error) (*Checklist, error) {
+	checklist, err := s.GetChecklist(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(checklist); err != nil {
+		return nil, err
+	}
+	for i := range s.checklists {
+		if s.checklists[i].ID == id {
+			s.checklists[i] = checklist
+		}
+	}
+	return checklist, nil
+}
+
+func (s *fakeStore) DeleteChecklist(_ context.Context, userID, id int32) error {
+	for i, checklist := range s.checklists {
+		if checklist.ID == id && checklist.UserID == userID {
+			s.checklists = slices.Delete(s.checklists, i, i+1)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestChecklists_Create(t *testing.T) {
+	ctx := context.Background()
+	checklists := New(&fakeStore{})
+
+	created, err := checklists.Create(ctx, 1, "  Tokyo   packing ", []Item{{Text: "Passport", Due: "2026-11-01"}, {Text: " Adapter "}})
+	require.NoError(t, err)
+	assert.Equal(t, "Tokyo packing", created.Title)
+	assert.Equal(t, []Item{{ID: 1, Text: "Passport", Due: "2026-11-01"}, {ID: 2, Text: "Adapter"}}, created.Items)
+
+	_, err = checklists.Create(ctx, 1, " ", nil)
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = checklists.Create(ctx, 1, "Trip", []Item{{Text: ""}})
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = checklists.Create(ctx, 1, "Trip", []Item{{Text: "Visa", Due: "next friday"}})
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = checklists.Create(ctx, 1, "Trip", make([]Item, MaxItems+1))
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestChecklists_Edit(t *testing.T) {
+	ctx := context.Background()
+	checklists := New(&fakeStore{})
+	created, err := checklists.Create(ctx, 1, "Trip", []Item{{Text: "Passport"}, {Text: "Umbrella"}, {Text: "Visa", Due: "2026-10-25"}})
+	require.NoError(t, err)
+
+	updated, err := checklists.Edit(ctx, 1, created.ID, &Edit{
+		Title:  ptr("Tokyo trip"),
+		Update: []ItemEdit{{ID: 1, Checked: ptr(true)}, {ID: 3, Due: ptr("")}},
+		Remove: []int32{3},
+		Add:    []Item{{Text: "Charger"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Tokyo trip", updated.Title)
+	// The new item does not take the ID of the item removed by the same edit.
+	assert.Equal(t, []Item{{ID: 1, Text: "Passport", Checked: true}, {ID: 2, Text: "Umbrella"}, {ID: 4, Text: "Charger"}}, updated.Items)
+	checked, total := updated.Progress()
+	assert.Equal(t, 1, checked)
+	assert.Equal(t, 3, total)
+
+	_, err = checklists.Edit(ctx, 1, created.ID, &Edit{Update: []ItemEdit{{ID: 9, Checked: ptr(true)}}})
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = checklists.Edit(ctx, 1, created.ID, &Edit{Update: []ItemEdit{{ID: 1, Text: ptr(" ")}}})
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = checklists.Edit(ctx, 2, created.ID, &Edit{Title: ptr("Mine")})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Failed edits are not saved.
+	got, err := checklists.Get(ctx, 1, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, updated, got)
+}
+
+func TestChecklists_Tools(t *testing.T) {
+	ctx := context.Background()
+	checklists := New(&fakeStore{})
+
+	out, err := checklists.CreateToolFor(1).Run(ctx, `{"title": "Camping", "items": [{"text": "Tent"}, {"text": "Stove", "due": "2026-10-30"}]}`)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Checklist 1: Camping (0/2 done")
+	assert.Contains(t, out, "[ ] 2. Stove (due 2026-10-30)")
+
+	out, err = checklists.UpdateToolFor(1).Run(ctx, `{"id": 1, "update": [{"id": 1, "checked": true}], "add": [{"text": "Lamp"}]}`)
+	require.NoError(t, err)
+	assert.Contains(t, out, "[x] 1. Tent")
+	assert.Contains(t, out, "[ ] 3. Lamp")
+
+	out, err = checklists.ListToolFor(1).Run(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, "Checklists:\n- 1: Camping (1/3 done)", out)
+
+	out, err = checklists.ListToolFor(2).Run(ctx, "{}")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "The user has no checklists"))
+
+	var nilChecklists *Checklists
+	assert.Nil(t, nilChecklists.CreateToolFor(1))
+}
@@ -0,0 +1,144 @@
+package checklist
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hrygo/divinesense/internal/util"
+)
+
+// DBStore persists checklists in the checklist table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new checklist store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const checklistColumns = "id, user_id, title, items, created_ts, updated_ts"
+
+// ListChecklists implements Store.
+func (s *DBStore) ListChecklists(ctx context.Context, userID int32) ([]*Checklist, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+checklistColumns+" FROM checklist WHERE user_id = $1 ORDER BY updated_ts DESC, id DESC", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checklists: %w", err)
+	}
+	defer rows.Close()
+
+	checklists := []*Checklist{}
+	for rows.Next() {
+		checklist, err := scanChecklist(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan checklist: %w", err)
+		}
+		checklists = append(checklists, checklist)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list checklists: %w", err)
+	}
+	return checklists, nil
+}
+
+// GetChecklist implements Store.
+func (s *DBStore) GetChecklist(ctx context.Context, userID, id int32) (*Checklist, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+checklistColumns+" FROM checklist WHERE id = $1 AND user_id = $2", id, userID)
+	checklist, err := scanChecklist(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checklist: %w", err)
+	}
+	return checklist, nil
+}
+
+// CreateChecklist implements Store.
+func (s *DBStore) CreateChecklist(ctx context.Context, checklist *Checklist) (*Checklist, error) {
+	items, err := json.Marshal(util.OrEmpty(checklist.Items))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal checklist items: %w", err)
+	}
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO checklist (user_id, title, items, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $4)
+		RETURNING `+checklistColumns,
+		checklist.UserID, checklist.Title, items, time.Now().Unix())
+	created, err := scanChecklist(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checklist: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateChecklist implements Store. The row is locked while fn runs.
+func (s *DBStore) UpdateChecklist(ctx context.Context, userID, id int32, fn func(*Checklist) error) (*Checklist, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	row := tx.QueryRowContext(ctx, "SELECT "+checklistColumns+" FROM checklist WHERE id = $1 AND user_id = $2 FOR UPDATE", id, userID)
+	checklist, err := scanChecklist(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checklist: %w", err)
+	}
+	if err := fn(checklist); err != nil {
+		return nil, err
+	}
+	items, err := json.Marshal(util.OrEmpty(checklist.Items))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal checklist items: %w", err)
+	}
+	row = tx.QueryRowContext(ctx, `
+		UPDATE checklist SET title = $2, items = $3, updated_ts = $4
+		WHERE id = $1
+		RETURNING `+checklistColumns,
+		id, checklist.Title, items, time.Now().Unix())
+	updated, err := scanChecklist(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update checklist: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit checklist: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteChecklist implements Store.
+func (s *DBStore) DeleteChecklist(ctx context.Context, userID, id int32) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM checklist WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete checklist: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanChecklist(row rowScanner) (*Checklist, error) {
+	checklist := &Checklist{}
+	var items []byte
+	if err := row.Scan(&checklist.ID, &checklist.UserID, &checklist.Title, &items, &checklist.CreatedTs, &checklist.UpdatedTs); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(items, &checklist.Items); err != nil {
+		return nil, fmt.Errorf("invalid checklist items: %w", err)
+	}
+	checklist.Items = util.OrEmpty(checklist.Items)
+	return checklist, nil
+}
@@ -0,0 +1,198 @@
+package checklist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	agents "github.com/hrygo/divinesense/ai/agents"
+)
+
+// Names of the agent tools of the checklist expert.
+const (
+	CreateToolName = "checklist_create"
+	ListToolName   = "checklist_list"
+	UpdateToolName = "checklist_update"
+)
+
+// CreateInput is the input of the checklist_create tool.
+type CreateInput struct {
+	Title string `json:"title"`
+	Items []Item `json:"items"`
+}
+
+// ListInput is the input of the checklist_list tool.
+type ListInput struct {
+	// ID selects a checklist to return with its items; zero lists them all.
+	ID int32 `json:"id"`
+}
+
+// UpdateInput is the input of the checklist_update tool.
+type UpdateInput struct {
+	ID int32 `json:"id"`
+	Edit
+}
+
+// itemSchema is the JSON schema of a new item.
+var itemSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"text":    map[string]any{"type": "string"},
+		"checked": map[string]any{"type": "boolean"},
+		"due":     map[string]any{"type": "string", "description": "Due date, YYYY-MM-DD; omit when there is none"},
+	},
+	"required": []string{"text"},
+}
+
+// CreateToolFor returns the checklist_create tool of a user, or nil without
+// checklists.
+func (c *Checklists) CreateToolFor(userID int32) agents.ToolWithSchema {
+	if c == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		CreateToolName,
+		"Save a new checklist (packing list, travel preparations, to-dos for an event...) for the user. "+
+			"The user can check items off later and you can change it with checklist_update. "+
+			"Returns the checklist with the IDs of its items.",
+		func(ctx context.Context, input string) (string, error) {
+			var in CreateInput
+			if err := json.Unmarshal([]byte(input), &in); err != nil {
+				return "", fmt.Errorf("invalid input: %w", err)
+			}
+			checklist, err := c.Create(ctx, userID, in.Title, in.Items)
+			if err != nil {
+				return "", err
+			}
+			return "✓ Checklist saved\n" + formatChecklist(checklist), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"title": map[string]any{"type": "string", "description": "Short title, e.g. 东京 5 日行李清单"},
+				"items": map[string]any{"type": "array", "items": itemSchema},
+			},
+			"required": []string{"title", "items"},
+		},
+	)
+}
+
+// ListToolFor returns the checklist_list tool of a user, or nil without
+// checklists.
+func (c *Checklists) ListToolFor(userID int32) agents.ToolWithSchema {
+	if c == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		ListToolName,
+		"List the user's checklists with their progress, most recently updated first. "+
+			"Pass an id to get one checklist with its items and their IDs, before changing it with checklist_update.",
+		func(ctx context.Context, input string) (string, error) {
+			var in ListInput
+			if strings.TrimSpace(input) != "" {
+				if err := json.Unmarshal([]byte(input), &in); err != nil {
+					return "", fmt.Errorf("invalid input: %w", err)
+				}
+			}
+			if in.ID != 0 {
+				checklist, err := c.Get(ctx, userID, in.ID)
+				if err != nil {
+					return "", err
+				}
+				return formatChecklist(checklist), nil
+			}
+			checklists, err := c.List(ctx, userID)
+			if err != nil {
+				return "", err
+			}
+			return formatChecklists(checklists), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id": map[string]any{"type": "integer", "description": "ID of a checklist to return with its items"},
+			},
+		},
+	)
+}
+
+// UpdateToolFor returns the checklist_update tool of a user, or nil without
+// checklists.
+func (c *Checklists) UpdateToolFor(userID int32) agents.ToolWithSchema {
+	if c == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		UpdateToolName,
+		"Change a checklist of the user: rename it, check or uncheck items, change their text or due date, "+
+			"remove items and add new ones. Items are referenced by the IDs checklist_list returns. "+
+			`Input: {"id": 3, "update": [{"id": 2, "checked": true}], "remove": [5], "add": [{"text": "充电器"}]}.`,
+		func(ctx context.Context, input string) (string, error) {
+			var in UpdateInput
+			if err := json.Unmarshal([]byte(input), &in); err != nil {
+				return "", fmt.Errorf("invalid input: %w", err)
+			}
+			checklist, err := c.Edit(ctx, userID, in.ID, &in.Edit)
+			if err != nil {
+				return "", err
+			}
+			return "✓ Checklist updated\n" + formatChecklist(checklist), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id":    map[string]any{"type": "integer", "description": "ID of the checklist"},
+				"title": map[string]any{"type": "string", "description": "New title"},
+				"update": map[string]any{
+					"type": "array",
+					"items": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"id":      map[string]any{"type": "integer"},
+							"text":    map[string]any{"type": "string"},
+							"checked": map[string]any{"type": "boolean"},
+							"due":     map[string]any{"type": "string", "description": "YYYY-MM-DD, or empty to clear it"},
+						},
+						"required": []string{"id"},
+					},
+				},
+				"remove": map[string]any{"type": "array", "items": map[string]any{"type": "integer"}, "description": "IDs of the items to remove"},
+				"add":    map[string]any{"type": "array", "items": itemSchema},
+			},
+			"required": []string{"id"},
+		},
+	)
+}
+
+// formatChecklist reports a checklist with its items to the agent.
+func formatChecklist(checklist *Checklist) string {
+	var b strings.Builder
+	checked, total := checklist.Progress()
+	fmt.Fprintf(&b, "Checklist %d: %s (%d/%d done, /checklists/%d)", checklist.ID, checklist.Title, checked, total, checklist.ID)
+	for _, item := range checklist.Items {
+		mark := " "
+		if item.Checked {
+			mark = "x"
+		}
+		fmt.Fprintf(&b, "\n[%s] %d. %s", mark, item.ID, item.Text)
+		if item.Due != "" {
+			fmt.Fprintf(&b, " (due %s)", item.Due)
+		}
+	}
+	return b.String()
+}
+
+// formatChecklists reports the checklists of a user to the agent.
+func formatChecklists(checklists []*Checklist) string {
+	if len(checklists) == 0 {
+		return "The user has no checklists."
+	}
+	var b strings.Builder
+	b.WriteString("Checklists:")
+	for _, checklist := range checklists {
+		checked, total := checklist.Progress()
+		fmt.Fprintf(&b, "\n- %d: %s (%d/%d done)", checklist.ID, checklist.Title, checked, total)
+	}
+	return b.String()
+}
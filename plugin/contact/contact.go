@@ -0,0 +1,351 @@
+// Package contact keeps light notes on the people a user knows ("CRM-lite").
+//
+// The people are the PERSON entities of the knowledge graph, extracted from
+// the user's memos and conversations. A contact profile aggregates what the
+// graph knows about a person (latest mentions, related entities, the last
+// interaction) with the follow-up reminders the user set ("ping Alice in 2
+// weeks"). Reminders are keyed by the name of the person rather than by
+// entity, so they survive the graph forgetting a person whose memos were
+// deleted. Due reminders are a section of the read-later digest.
+package contact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hrygo/divinesense/plugin/entitygraph"
+	"github.com/hrygo/divinesense/plugin/i18n"
+)
+
+var (
+	// ErrNotFound is returned for people and reminders the user does not have.
+	ErrNotFound = errors.New("contact not found")
+	// ErrInvalid is returned for reminders and queries that fail validation.
+	ErrInvalid = errors.New("invalid contact reminder")
+)
+
+const (
+	// maxPersonRunes bounds the name of the person of a reminder.
+	maxPersonRunes = 64
+	// maxNoteRunes bounds the note of a reminder.
+	maxNoteRunes = 200
+	// maxListLimit bounds the people and reminders returned by a listing.
+	maxListLimit = 200
+	// defaultListLimit is the number of people listed without limit.
+	defaultListLimit = 50
+	// profileReminders bounds the reminders of a profile.
+	profileReminders = 20
+)
+
+// Reminder is a follow-up with a person, due on a day.
+type Reminder struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"-"`
+	// Person is the name of the person to follow up with, e.g. "Alice".
+	Person string `json:"person"`
+	// Note says what the follow-up is about, e.g. "ask about the offer".
+	Note string `json:"note"`
+	// DueDate is the day of the follow-up, YYYY-MM-DD.
+	DueDate string `json:"due_date"`
+	// DoneTs is when the follow-up was done; 0 while pending.
+	DoneTs    int64 `json:"done_ts"`
+	CreatedTs int64 `json:"created_ts"`
+	UpdatedTs int64 `json:"updated_ts"`
+}
+
+// Validate checks and normalizes a reminder about to be saved.
+func (r *Reminder) Validate() error {
+	r.Person = strings.Join(strings.Fields(r.Person), " ")
+	if r.Person == "" || utf8.RuneCountInString(r.Person) > maxPersonRunes {
+		return fmt.Errorf("%w: person is required, up to %d characters", ErrInvalid, maxPersonRunes)
+	}
+	r.Note = strings.Join(strings.Fields(r.Note), " ")
+	if utf8.RuneCountInString(r.Note) > maxNoteRunes {
+		return fmt.Errorf("%w: note exceeds %d characters", ErrInvalid, maxNoteRunes)
+	}
+	if _, err := time.Parse(time.DateOnly, r.DueDate); err != nil {
+		return fmt.Errorf("%w: due_date must be YYYY-MM-DD", ErrInvalid)
+	}
+	return nil
+}
+
+// Pending reports whether the follow-up is not done yet.
+func (r *Reminder) Pending() bool {
+	return r.DoneTs == 0
+}
+
+// FindReminder selects the reminders of a user.
+type FindReminder struct {
+	UserID int32
+	// Person matches the normalized name of the person exactly; empty for all.
+	Person string
+	// DueBy bounds the due dates, inclusive, YYYY-MM-DD; empty for none.
+	DueBy string
+	// Pending selects the reminders not done yet.
+	Pending bool
+	Limit   int
+}
+
+// Contact is a person in a listing, with their next follow-up.
+type Contact struct {
+	Person *entitygraph.Entity `json:"person"`
+	// NextReminder is the pending reminder due first, if any.
+	NextReminder *Reminder `json:"next_reminder,omitempty"`
+}
+
+// Profile is what the user knows about a person.
+type Profile struct {
+	Name string `json:"name"`
+	// Person is the entity of the person; nil when the knowledge graph does
+	// not know them, e.g. for people only named in reminders.
+	Person    *entitygraph.Entity          `json:"person,omitempty"`
+	Mentions  []*entitygraph.Mention       `json:"mentions"`
+	Relations []*entitygraph.RelatedEntity `json:"relations"`
+	// LastInteractionTs is the time of the latest mention; 0 for none.
+	LastInteractionTs int64 `json:"last_interaction_ts"`
+	// Reminders are the pending reminders, due first, then the done ones.
+	Reminders []*Reminder `json:"reminders"`
+}
+
+// Store persists the reminders.
+type Store interface {
+	// ListReminders returns reminders, pending ones by due date first, then
+	// done ones, latest first.
+	ListReminders(ctx context.Context, find *FindReminder) ([]*Reminder, error)
+	// GetReminder returns ErrNotFound for reminders the user does not have.
+	GetReminder(ctx context.Context, userID, id int32) (*Reminder, error)
+	CreateReminder(ctx context.Context, reminder *Reminder) (*Reminder, error)
+	// UpdateReminder returns ErrNotFound for reminders the user does not have.
+	UpdateReminder(ctx context.Context, reminder *Reminder) (*Reminder, error)
+	// DeleteReminder returns ErrNotFound for reminders the user does not have.
+	DeleteReminder(ctx context.Context, userID, id int32) error
+}
+
+// Graph is the knowledge graph the people come from; implemented by
+// *entitygraph.Graph.
+type Graph interface {
+	List(ctx context.Context, find *entitygraph.FindEntity) ([]*entitygraph.Entity, error)
+	Get(ctx context.Context, id, creatorID int32) (*entitygraph.EntityDetail, error)
+}
+
+// Contacts manages the people of the users and their follow-ups.
+type Contacts struct {
+	store Store
+	graph Graph
+}
+
+// New creates the contacts of the people of a knowledge graph.
+func New(store Store, graph Graph) *Contacts {
+	return &Contacts{store: store, graph: graph}
+}
+
+// List returns the people of a user whose names contain query, the latest
+// interaction first, with their next follow-ups.
+func (c *Contacts) List(ctx context.Context, userID int32, query string, limit int) ([]*Contact, error) {
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	kind := entitygraph.KindPerson
+	people, err := c.graph.List(ctx, &entitygraph.FindEntity{
+		CreatorID:   &userID,
+		Kind:        &kind,
+		Query:       query,
+		RecentFirst: true,
+		Limit:       min(limit, maxListLimit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	pending, err := c.store.ListReminders(ctx, &FindReminder{UserID: userID, Pending: true, Limit: maxListLimit})
+	if err != nil {
+		return nil, err
+	}
+	contacts := make([]*Contact, 0, len(people))
+	for _, person := range people {
+		contact := &Contact{Person: person}
+		name := entitygraph.NormalizeName(person.Name)
+		// Pending reminders are listed by due date: the first one is next
+		if i := slices.IndexFunc(pending, func(r *Reminder) bool { return entitygraph.NormalizeName(r.Person) == name }); i >= 0 {
+			contact.NextReminder = pending[i]
+		}
+		contacts = append(contacts, contact)
+	}
+	return contacts, nil
+}
+
+// Get returns the profile of a person of a user by entity ID.
+func (c *Contacts) Get(ctx context.Context, userID, id int32) (*Profile, error) {
+	detail, err := c.graph.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if detail == nil || detail.Entity.Kind != entitygraph.KindPerson {
+		return nil, ErrNotFound
+	}
+	return c.profile(ctx, userID, detail.Entity.Name, detail)
+}
+
+// Lookup returns the profile of the person of a user best matching name: an
+// exact match of the name, else the latest person whose name contains it.
+// People only named in reminders have profiles too.
+func (c *Contacts) Lookup(ctx context.Context, userID int32, name string) (*Profile, error) {
+	normalized := entitygraph.NormalizeName(name)
+	if normalized == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalid)
+	}
+	person, err := c.findPerson(ctx, userID, normalized)
+	if err != nil {
+		return nil, err
+	}
+	if person == nil {
+		profile, err := c.profile(ctx, userID, strings.Join(strings.Fields(name), " "), nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(profile.Reminders) == 0 {
+			return nil, ErrNotFound
+		}
+		return profile, nil
+	}
+	detail, err := c.graph.Get(ctx, person.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if detail == nil {
+		// Forgotten since it was found
+		return nil, ErrNotFound
+	}
+	return c.profile(ctx, userID, person.Name, detail)
+}
+
+// findPerson returns the person of a user named normalized, else the latest
+// one whose name contains it, or nil.
+func (c *Contacts) findPerson(ctx context.Context, userID int32, normalized string) (*entitygraph.Entity, error) {
+	kind := entitygraph.KindPerson
+	people, err := c.graph.List(ctx, &entitygraph.FindEntity{CreatorID: &userID, Kind: &kind, NormalizedName: &normalized, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(people) == 0 {
+		people, err = c.graph.List(ctx, &entitygraph.FindEntity{CreatorID: &userID, Kind: &kind, Query: normalized, RecentFirst: true, Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(people) == 0 {
+		return nil, nil
+	}
+	return people[0], nil
+}
+
+// profile aggregates the entity detail of a person, if any, with their reminders.
+func (c *Contacts) profile(ctx context.Context, userID int32, name string, detail *entitygraph.EntityDetail) (*Profile, error) {
+	reminders, err := c.store.ListReminders(ctx, &FindReminder{
+		UserID: userID,
+		Person: entitygraph.NormalizeName(name),
+		Limit:  profileReminders,
+	})
+	if err != nil {
+		return nil, err
+	}
+	profile := &Profile{
+		Name:      name,
+		Mentions:  []*entitygraph.Mention{},
+		Relations: []*entitygraph.RelatedEntity{},
+		Reminders: reminders,
+	}
+	if detail != nil {
+		profile.Person = detail.Entity
+		profile.Mentions = detail.Mentions
+		profile.Relations = detail.Relations
+		profile.LastInteractionTs = detail.Entity.LastMentionTs
+	}
+	return profile, nil
+}
+
+// Remind validates and saves a follow-up of a user. The person takes the
+// name the knowledge graph knows them by, if it does.
+func (c *Contacts) Remind(ctx context.Context, reminder *Reminder) (*Reminder, error) {
+	if err := reminder.Validate(); err != nil {
+		return nil, err
+	}
+	person, err := c.findPerson(ctx, reminder.UserID, entitygraph.NormalizeName(reminder.Person))
+	if err != nil {
+		return nil, err
+	}
+	if person != nil && entitygraph.NormalizeName(person.Name) == entitygraph.NormalizeName(reminder.Person) {
+		reminder.Person = person.Name
+	}
+	return c.store.CreateReminder(ctx, reminder)
+}
+
+// Reminders returns the reminders of a user.
+func (c *Contacts) Reminders(ctx context.Context, find *FindReminder) ([]*Reminder, error) {
+	if _, err := time.Parse(time.DateOnly, find.DueBy); find.DueBy != "" && err != nil {
+		return nil, fmt.Errorf("%w: due_by must be YYYY-MM-DD", ErrInvalid)
+	}
+	find.Person = entitygraph.NormalizeName(find.Person)
+	if find.Limit <= 0 || find.Limit > maxListLimit {
+		find.Limit = maxListLimit
+	}
+	return c.store.ListReminders(ctx, find)
+}
+
+// GetReminder returns a reminder of a user.
+func (c *Contacts) GetReminder(ctx context.Context, userID, id int32) (*Reminder, error) {
+	return c.store.GetReminder(ctx, userID, id)
+}
+
+// UpdateReminder validates and replaces a reminder of a user.
+func (c *Contacts) UpdateReminder(ctx context.Context, reminder *Reminder) (*Reminder, error) {
+	if err := reminder.Validate(); err != nil {
+		return nil, err
+	}
+	return c.store.UpdateReminder(ctx, reminder)
+}
+
+// DeleteReminder removes a reminder of a user.
+func (c *Contacts) DeleteReminder(ctx context.Context, userID, id int32) error {
+	return c.store.DeleteReminder(ctx, userID, id)
+}
+
+// DigestSection returns the follow-ups of a user due today or overdue as a
+// section of the read-later digest, or "" when none is due.
+func (c *Contacts) DigestSection(ctx context.Context, userID int32, locale i18n.Locale) (string, error) {
+	today := time.Now().Format(time.DateOnly)
+	due, err := c.store.ListReminders(ctx, &FindReminder{UserID: userID, DueBy: today, Pending: true, Limit: maxListLimit})
+	if err != nil || len(due) == 0 {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString(i18n.T(locale, "contact.digest.title") + "\n\n")
+	for _, reminder := range due {
+		b.WriteString("- " + reminder.Person)
+		if reminder.Note != "" {
+			b.WriteString(i18n.T(locale, "contact.digest.note", reminder.Note))
+		}
+		if overdue := daysBetween(reminder.DueDate, today); overdue > 0 {
+			b.WriteString(i18n.N(locale, "contact.digest.overdue", overdue, overdue))
+		}
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// daysBetween returns the days from one day to another, YYYY-MM-DD.
+func daysBetween(from, to string) int {
+	start, err := time.Parse(time.DateOnly, from)
+	if err != nil {
+		return 0
+	}
+	end, err := time.Parse(time.DateOnly, to)
+	if err != nil {
+		return 0
+	}
+	return int(end.Sub(start).Hours() / 24)
+}
@@ -0,0 +1,281 @@
+package contact
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/plugin/entitygraph"
+	"github.com/hrygo/divinesense/plugin/i18n"
+)
+
+// fakeStore is an in-memory Store.
+type fakeStore struct {
+	reminders []*Reminder
+}
+
+func (s *fakeStore) ListReminders(_ context.Context, find *FindReminder) ([]*Reminder, error) {
+	reminders := []*Reminder{}
+	for _, r := range s.reminders {
+		if r.UserID != find.UserID ||
+			(find.Person != "" && entitygraph.NormalizeName(r.Person) != find.Person) ||
+			(find.DueBy != "" && r.DueDate > find.DueBy) ||
+			(find.Pending && !r.Pending()) {
+			continue
+		}
+		copied := *r
+		reminders = append(reminders, &copied)
+	}
+	slices.SortStableFunc(reminders, func(a, b *Reminder) int {
+		if a.Pending() != b.Pending() {
+			if a.Pending() {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.DueDate, b.DueDate)
+	})
+	return reminders[:min(len(reminders), find.Limit)], nil
+}
+
+func (s *fakeStore) GetReminder(_ context.Context, userID, id int32) (*Reminder, error) {
+	for _, r := range s.reminders {
+		if r.ID == id && r.UserID == userID {
+			copied := *r
+			return &copied, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *fakeStore) CreateReminder(_ context.Context, reminder *Reminder) (*Reminder, error) {
+	created := *reminder
+	created.ID = int32(len(s.reminders) + 1)
+	s.reminders = append(s.reminders, &created)
+	return &created, nil
+}
+
+func (s *fakeStore) UpdateReminder(_ context.Context, reminder *Reminder) (*Reminder, error) {
+	for i, r := range s.reminders {
+		if r.ID == reminder.ID && r.UserID == reminder.UserID {
+			updated := *reminder
+			s.reminders[i] = &updated
+			return reminder, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *fakeStore) DeleteReminder(_ context.Context, userID, id int32) error {
+	for i, r := range s.reminders {
+		if r.ID == id && r.UserID == userID {
+			s.reminders = slices.Delete(s.reminders, i, i+1)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// fakeGraph is a knowledge graph of a few entities.
+type fakeGraph struct {
+	entities []*entitygraph.Entity
+}
+
+func (g *fakeGraph) List(_ context.Context, find *entitygraph.FindEntity) ([]*entitygraph.Entity, error) {
+	entities := []*entitygraph.Entity{}
+	for _, e := range g.entities {
+		name := entitygraph.NormalizeName(e.Name)
+		if (find.CreatorID != nil && e.CreatorID != *find.CreatorID) ||
+			(find.Kind != nil && e.Kind != *find.Kind) ||
+			(find.NormalizedName != nil && name != *find.NormalizedName) ||
+			(find.Query != "" && !strings.Contains(name, entitygraph.NormalizeName(find.Query))) {
+			continue
+		}
+		entities = append(entities, e)
+	}
+	if find.RecentFirst {
+		slices.SortStableFunc(entities, func(a, b *entitygraph.Entity) int { return int(b.LastMentionTs - a.LastMentionTs) })
+	}
+	if find.Limit > 0 {
+		entities = entities[:min(len(entities), find.Limit)]
+	}
+	return entities, nil
+}
+
+func (g *fakeGraph) Get(_ context.Context, id, creatorID int32) (*entitygraph.EntityDetail, error) {
+	for _, e := range g.entities {
+		if e.ID == id && e.CreatorID == creatorID {
+			return &entitygraph.EntityDetail{
+				Entity:    e,
+				Mentions:  []*entitygraph.Mention{{EntityID: id, Source: entitygraph.Source{Type: entitygraph.SourceMemo, ID: 1, Ref: "m1"}, Snippet: "lunch with " + e.Name, CreatedTs: e.LastMentionTs}},
+				Relations: []*entitygraph.RelatedEntity{},
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+func newContacts() (*Contacts, *fakeStore) {
+	store := &fakeStore{}
+	graph := &fakeGraph{entities: []*entitygraph.Entity{
+		{ID: 1, CreatorID: 1, Name: "Alice Chen", Kind: entitygraph.KindPerson, MentionCount: 3, LastMentionTs: 1000},
+		{ID: 2, CreatorID: 1, Name: "Bob", Kind: entitygraph.KindPerson, MentionCount: 1, LastMentionTs: 2000},
+		{ID: 3, CreatorID: 1, Name: "Alice Project", Kind: entitygraph.KindProject, MentionCount: 9, LastMentionTs: 3000},
+		{ID: 4, CreatorID: 2, Name: "Alice", Kind: entitygraph.KindPerson, MentionCount: 1, LastMentionTs: 4000},
+	}}
+	return New(store, graph), store
+}
+
+func TestReminderValidate(t *testing.T) {
+	reminder := &Reminder{Person: "  Alice   Chen ", Note: " ask \n about the offer ", DueDate: "2026-10-31"}
+	require.NoError(t, reminder.Validate())
+	assert.Equal(t, "Alice Chen", reminder.Person)
+	assert.Equal(t, "ask about the offer", reminder.Note)
+
+	for _, invalid := range []*Reminder{
+		{Person: " ", DueDate: "2026-10-31"},
+		{Person: strings.Repeat("a", maxPersonRunes+1), DueDate: "2026-10-31"},
+		{Person: "Alice", Note: strings.Repeat("n", maxNoteRunes+1), DueDate: "2026-10-31"},
+		{Person: "Alice", DueDate: "in two weeks"},
+	} {
+		assert.ErrorIs(t, invalid.Validate(), ErrInvalid, "%+v", invalid)
+	}
+}
+
+func TestRemindAndLookup(t *testing.T) {
+	ctx := context.Background()
+	contacts, _ := newContacts()
+
+	// The person takes the name the graph knows them by
+	reminder, err := contacts.Remind(ctx, &Reminder{UserID: 1, Person: "alice  chen", Note: "ping", DueDate: "2026-10-31"})
+	require.NoError(t, err)
+	assert.Equal(t, "Alice Chen", reminder.Person)
+	_, err = contacts.Remind(ctx, &Reminder{UserID: 1, Person: "Carol", DueDate: "2026-11-01"})
+	require.NoError(t, err)
+
+	// A partial name matches the person, not the project
+	profile, err := contacts.Lookup(ctx, 1, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice Chen", profile.Name)
+	assert.Equal(t, int32(1), profile.Person.ID)
+	assert.Equal(t, int64(1000), profile.LastInteractionTs)
+	assert.Len(t, profile.Mentions, 1)
+	require.Len(t, profile.Reminders, 1)
+	assert.Equal(t, "ping", profile.Reminders[0].Note)
+
+	// People only named in reminders have profiles too
+	profile, err = contacts.Lookup(ctx, 1, "Carol")
+	require.NoError(t, err)
+	assert.Nil(t, profile.Person)
+	assert.Len(t, profile.Reminders, 1)
+
+	_, err = contacts.Lookup(ctx, 1, "Dave")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = contacts.Lookup(ctx, 1, " ")
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestGet(t *testing.T) {
+	ctx := context.Background()
+	contacts, _ := newContacts()
+
+	profile, err := contacts.Get(ctx, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "Bob", profile.Name)
+
+	_, err = contacts.Get(ctx, 1, 3)
+	assert.ErrorIs(t, err, ErrNotFound, "projects are not contacts")
+	_, err = contacts.Get(ctx, 1, 4)
+	assert.ErrorIs(t, err, ErrNotFound, "people of other users are not contacts")
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	contacts, store := newContacts()
+	store.reminders = []*Reminder{
+		{ID: 1, UserID: 1, Person: "Alice Chen", DueDate: "2026-11-20"},
+		{ID: 2, UserID: 1, Person: "Alice Chen", DueDate: "2026-11-01"},
+		{ID: 3, UserID: 1, Person: "Bob", DueDate: "2026-10-01", DoneTs: 1},
+	}
+
+	listed, err := contacts.List(ctx, 1, "", 0)
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	assert.Equal(t, "Bob", listed[0].Person.Name, "latest interaction first")
+	assert.Nil(t, listed[0].NextReminder, "done reminders are not next")
+	assert.Equal(t, int32(2), listed[1].NextReminder.ID, "the reminder due first is next")
+}
+
+func TestDigestSection(t *testing.T) {
+	ctx := context.Background()
+	contacts, store := newContacts()
+
+	section, err := contacts.DigestSection(ctx, 1, i18n.DefaultLocale)
+	require.NoError(t, err)
+	assert.Empty(t, section)
+
+	today := time.Now()
+	store.reminders = []*Reminder{
+		{ID: 1, UserID: 1, Person: "Alice Chen", Note: "ask about the offer", DueDate: today.AddDate(0, 0, -3).Format(time.DateOnly)},
+		{ID: 2, UserID: 1, Person: "Bob", DueDate: today.Format(time.DateOnly)},
+		{ID: 3, UserID: 1, Person: "Carol", DueDate: today.AddDate(0, 0, 1).Format(time.DateOnly)},
+		{ID: 4, UserID: 1, Person: "Dave", DueDate: today.Format(time.DateOnly), DoneTs: 1},
+	}
+	section, err = contacts.DigestSection(ctx, 1, i18n.LocaleSimplifiedChinese)
+	require.NoError(t, err)
+	assert.Equal(t, "## 待联系\n\n- Alice Chen：ask about the offer（已逾期 3 天）\n- Bob\n", section)
+	section, err = contacts.DigestSection(ctx, 1, i18n.LocaleEnglish)
+	require.NoError(t, err)
+	assert.Equal(t, "## Follow-ups\n\n- Alice Chen: ask about the offer (3 days overdue)\n- Bob\n", section)
+}
+
+func TestRemindTool(t *testing.T) {
+	ctx := context.Background()
+	contacts, store := newContacts()
+	tool := contacts.RemindToolFor(1)
+
+	out, err := tool.Run(ctx, `{"person": "Bob", "note": "send the slides", "in_days": 14, "timezone": "Asia/Shanghai"}`)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Follow-up set: #1")
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	assert.Equal(t, time.Now().In(loc).AddDate(0, 0, 14).Format(time.DateOnly), store.reminders[0].DueDate)
+
+	out, err = tool.Run(ctx, `{"done": 1}`)
+	require.NoError(t, err)
+	assert.Contains(t, out, "(done)")
+	assert.False(t, store.reminders[0].Pending())
+
+	_, err = tool.Run(ctx, `{"person": "Bob"}`)
+	assert.ErrorIs(t, err, ErrInvalid, "a day is required")
+
+	var nilContacts *Contacts
+	assert.Nil(t, nilContacts.RemindToolFor(1))
+	assert.Nil(t, nilContacts.LookupToolFor(1))
+}
+
+func TestLookupTool(t *testing.T) {
+	ctx := context.Background()
+	contacts, store := newContacts()
+	store.reminders = []*Reminder{{ID: 1, UserID: 1, Person: "Alice Chen", Note: "ping", DueDate: "2000-01-01"}}
+	tool := contacts.LookupToolFor(1)
+
+	out, err := tool.Run(ctx, `{"name": "Alice"}`)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Mentioned 3 times")
+	assert.Contains(t, out, "memo [m1]")
+	assert.Contains(t, out, "#1 2000-01-01 Alice Chen · ping (overdue by")
+
+	out, err = tool.Run(ctx, "")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Pending follow-ups:")
+	assert.Contains(t, out, "- Bob (1 mentions")
+
+	out, err = tool.Run(ctx, `{"name": "Zoe"}`)
+	require.NoError(t, err)
+	assert.Contains(t, out, "No person named")
+}
@@ -0,0 +1,133 @@
+package contact
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hrygo/divinesense/plugin/entitygraph"
+)
+
+// DBStore persists reminders in the contact_reminder table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new reminder store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const reminderColumns = "id, user_id, person, note, to_char(due_date, 'YYYY-MM-DD'), done_ts, created_ts, updated_ts"
+
+// ListReminders implements Store.
+func (s *DBStore) ListReminders(ctx context.Context, find *FindReminder) ([]*Reminder, error) {
+	where, args := []string{"user_id = $1"}, []any{find.UserID}
+	if find.Person != "" {
+		args = append(args, find.Person)
+		where = append(where, fmt.Sprintf("normalized_person = $%d", len(args)))
+	}
+	if find.DueBy != "" {
+		args = append(args, find.DueBy)
+		where = append(where, fmt.Sprintf("due_date <= $%d::date", len(args)))
+	}
+	if find.Pending {
+		where = append(where, "done_ts = 0")
+	}
+	args = append(args, find.Limit)
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(
+		"SELECT %s FROM contact_reminder WHERE %s ORDER BY done_ts > 0, CASE WHEN done_ts = 0 THEN due_date END, done_ts DESC, id LIMIT $%d",
+		reminderColumns, strings.Join(where, " AND "), len(args)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reminders: %w", err)
+	}
+	defer rows.Close()
+
+	reminders := []*Reminder{}
+	for rows.Next() {
+		reminder, err := scanReminder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		reminders = append(reminders, reminder)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list reminders: %w", err)
+	}
+	return reminders, nil
+}
+
+// GetReminder implements Store.
+func (s *DBStore) GetReminder(ctx context.Context, userID, id int32) (*Reminder, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+reminderColumns+" FROM contact_reminder WHERE id = $1 AND user_id = $2", id, userID)
+	reminder, err := scanReminder(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminder: %w", err)
+	}
+	return reminder, nil
+}
+
+// CreateReminder implements Store.
+func (s *DBStore) CreateReminder(ctx context.Context, reminder *Reminder) (*Reminder, error) {
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO contact_reminder (user_id, person, normalized_person, note, due_date, done_ts, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $5::date, $6, $7, $7)
+		RETURNING `+reminderColumns,
+		reminder.UserID, reminder.Person, entitygraph.NormalizeName(reminder.Person), reminder.Note, reminder.DueDate,
+		reminder.DoneTs, time.Now().Unix())
+	created, err := scanReminder(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reminder: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateReminder implements Store.
+func (s *DBStore) UpdateReminder(ctx context.Context, reminder *Reminder) (*Reminder, error) {
+	row := s.db.QueryRowContext(ctx, `
+		UPDATE contact_reminder
+		SET person = $3, normalized_person = $4, note = $5, due_date = $6::date, done_ts = $7, updated_ts = $8
+		WHERE id = $1 AND user_id = $2
+		RETURNING `+reminderColumns,
+		reminder.ID, reminder.UserID, reminder.Person, entitygraph.NormalizeName(reminder.Person), reminder.Note,
+		reminder.DueDate, reminder.DoneTs, time.Now().Unix())
+	updated, err := scanReminder(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update reminder: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteReminder implements Store.
+func (s *DBStore) DeleteReminder(ctx context.Context, userID, id int32) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM contact_reminder WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete reminder: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReminder(row rowScanner) (*Reminder, error) {
+	reminder := &Reminder{}
+	if err := row.Scan(&reminder.ID, &reminder.UserID, &reminder.Person, &reminder.Note, &reminder.DueDate,
+		&reminder.DoneTs, &reminder.CreatedTs, &reminder.UpdatedTs); err != nil {
+		return nil, err
+	}
+	return reminder, nil
+}
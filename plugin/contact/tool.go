@@ -0,0 +1,246 @@
+package contact
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	agents "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/internal/util"
+)
+
+// Names of the agent tools of the contacts.
+const (
+	LookupToolName = "contact_lookup"
+	RemindToolName = "contact_remind"
+)
+
+const (
+	// toolMentions bounds the mentions of a profile reported to the agent.
+	toolMentions = 5
+	// toolPeople bounds the people of a listing reported to the agent.
+	toolPeople = 10
+)
+
+// LookupInput is the input of the contact_lookup tool.
+type LookupInput struct {
+	// Name is the person; empty lists the follow-ups and the latest people.
+	Name     string `json:"name"`
+	Timezone string `json:"timezone"`
+}
+
+// RemindInput is the input of the contact_remind tool.
+type RemindInput struct {
+	Person string `json:"person"`
+	Note   string `json:"note"`
+	// DueDate is the day of the follow-up, YYYY-MM-DD; else InDays from today.
+	DueDate string `json:"due_date"`
+	InDays  int    `json:"in_days"`
+	// Done is the ID of a follow-up the user did, marked done instead.
+	Done     int32  `json:"done"`
+	Timezone string `json:"timezone"`
+}
+
+// LookupToolFor returns the contact_lookup tool of a user, or nil without
+// contacts.
+func (c *Contacts) LookupToolFor(userID int32) agents.ToolWithSchema {
+	if c == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		LookupToolName,
+		"Look up a person the user knows: the last interaction, the latest mentions in memos and conversations "+
+			"(with memo UIDs to cite), related people and projects, and the pending follow-ups. "+
+			`Use it for questions like "when did I last talk to Alice?". Without name, it lists the follow-ups due `+
+			"and the people the user interacted with lately.",
+		func(ctx context.Context, input string) (string, error) {
+			var in LookupInput
+			if strings.TrimSpace(input) != "" {
+				if err := json.Unmarshal([]byte(input), &in); err != nil {
+					return "", fmt.Errorf("invalid input: %w", err)
+				}
+			}
+			now, err := util.NowIn(in.Timezone)
+			if err != nil {
+				return "", fmt.Errorf("%w: %w", ErrInvalid, err)
+			}
+			today := now.Format(time.DateOnly)
+			if strings.TrimSpace(in.Name) == "" {
+				return c.formatOverview(ctx, userID, now)
+			}
+			profile, err := c.Lookup(ctx, userID, in.Name)
+			if errors.Is(err, ErrNotFound) {
+				return fmt.Sprintf("No person named %q in the user's memos, conversations or follow-ups. Try memo_search instead.", in.Name), nil
+			}
+			if err != nil {
+				return "", err
+			}
+			return formatProfile(profile, today, now.Location()), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name":     map[string]any{"type": "string", "description": "Name of the person; omit to list the follow-ups due"},
+				"timezone": map[string]any{"type": "string", "description": "IANA time zone of the user, e.g. Asia/Shanghai"},
+			},
+		},
+	)
+}
+
+// RemindToolFor returns the contact_remind tool of a user, or nil without
+// contacts.
+func (c *Contacts) RemindToolFor(userID int32) agents.ToolWithSchema {
+	if c == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		RemindToolName,
+		`Set a follow-up with a person the user asked to be reminded of, e.g. "ping Alice in 2 weeks" or "两周后联系张三": `+
+			"give the person, what it is about and the day, as due_date or in_days from today. "+
+			`When the user says they followed up ("I called Alice"), pass the ID of the follow-up as done instead. `+
+			"Due follow-ups are listed in the user's digest.",
+		func(ctx context.Context, input string) (string, error) {
+			var in RemindInput
+			if err := json.Unmarshal([]byte(input), &in); err != nil {
+				return "", fmt.Errorf("invalid input: %w", err)
+			}
+			if in.Done != 0 {
+				reminder, err := c.GetReminder(ctx, userID, in.Done)
+				if err != nil {
+					return "", err
+				}
+				reminder.DoneTs = time.Now().Unix()
+				if _, err := c.UpdateReminder(ctx, reminder); err != nil {
+					return "", err
+				}
+				return "✓ Done: " + formatReminder(reminder, ""), nil
+			}
+			now, err := util.NowIn(in.Timezone)
+			if err != nil {
+				return "", fmt.Errorf("%w: %w", ErrInvalid, err)
+			}
+			if in.DueDate == "" {
+				if in.InDays <= 0 {
+					return "", fmt.Errorf("%w: due_date or a positive in_days is required", ErrInvalid)
+				}
+				in.DueDate = now.AddDate(0, 0, in.InDays).Format(time.DateOnly)
+			}
+			reminder, err := c.Remind(ctx, &Reminder{UserID: userID, Person: in.Person, Note: in.Note, DueDate: in.DueDate})
+			if err != nil {
+				return "", err
+			}
+			return "✓ Follow-up set: " + formatReminder(reminder, now.Format(time.DateOnly)), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"person":   map[string]any{"type": "string", "description": "Name of the person, as the user calls them"},
+				"note":     map[string]any{"type": "string", "description": "What the follow-up is about, e.g. ask about the offer"},
+				"due_date": map[string]any{"type": "string", "description": "Day of the follow-up, YYYY-MM-DD"},
+				"in_days":  map[string]any{"type": "integer", "description": "Days from today, e.g. 14 for in 2 weeks; used without due_date"},
+				"done":     map[string]any{"type": "integer", "description": "ID of a follow-up the user did, to mark it done"},
+				"timezone": map[string]any{"type": "string", "description": "IANA time zone of the user, e.g. Asia/Shanghai"},
+			},
+		},
+	)
+}
+
+// formatOverview reports the follow-ups of a user and the people they
+// interacted with lately to the agent.
+func (c *Contacts) formatOverview(ctx context.Context, userID int32, now time.Time) (string, error) {
+	pending, err := c.Reminders(ctx, &FindReminder{UserID: userID, Pending: true})
+	if err != nil {
+		return "", err
+	}
+	people, err := c.List(ctx, userID, "", toolPeople)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if len(pending) == 0 {
+		sb.WriteString("No pending follow-ups.\n")
+	} else {
+		sb.WriteString("Pending follow-ups:\n")
+		for _, reminder := range pending {
+			fmt.Fprintf(&sb, "- %s\n", formatReminder(reminder, now.Format(time.DateOnly)))
+		}
+	}
+	if len(people) > 0 {
+		sb.WriteString("Latest interactions:\n")
+		for _, contact := range people {
+			fmt.Fprintf(&sb, "- %s (%d mentions, last %s)\n", contact.Person.Name, contact.Person.MentionCount,
+				formatDay(contact.Person.LastMentionTs, now.Location()))
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n"), nil
+}
+
+// formatProfile reports a profile to the agent.
+func formatProfile(profile *Profile, today string, loc *time.Location) string {
+	var sb strings.Builder
+	sb.WriteString(profile.Name)
+	if profile.Person != nil && profile.Person.Description != "" {
+		sb.WriteString(": " + profile.Person.Description)
+	}
+	sb.WriteString("\n")
+	if profile.Person == nil {
+		sb.WriteString("Not mentioned in the user's memos or conversations.\n")
+	} else {
+		fmt.Fprintf(&sb, "Mentioned %d times, last interaction %s.\n", profile.Person.MentionCount, formatDay(profile.LastInteractionTs, loc))
+	}
+	if len(profile.Mentions) > 0 {
+		sb.WriteString("Latest mentions:\n")
+		for _, mention := range profile.Mentions[:min(len(profile.Mentions), toolMentions)] {
+			source := "conversation"
+			if mention.Ref != "" {
+				source = "memo [" + mention.Ref + "]"
+			}
+			fmt.Fprintf(&sb, "- %s, %s: %s\n", formatDay(mention.CreatedTs, loc), source, mention.Snippet)
+		}
+	}
+	if len(profile.Relations) > 0 {
+		sb.WriteString("Related:\n")
+		for _, related := range profile.Relations {
+			if related.Outgoing {
+				fmt.Fprintf(&sb, "- %s %s %s\n", profile.Name, related.Relation, related.Entity.Name)
+			} else {
+				fmt.Fprintf(&sb, "- %s %s %s\n", related.Entity.Name, related.Relation, profile.Name)
+			}
+		}
+	}
+	if len(profile.Reminders) > 0 {
+		sb.WriteString("Follow-ups:\n")
+		for _, reminder := range profile.Reminders {
+			fmt.Fprintf(&sb, "- %s\n", formatReminder(reminder, today))
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// formatReminder reports a reminder to the agent, with its state on a day.
+func formatReminder(reminder *Reminder, today string) string {
+	line := fmt.Sprintf("#%d %s %s", reminder.ID, reminder.DueDate, reminder.Person)
+	if reminder.Note != "" {
+		line += " · " + reminder.Note
+	}
+	switch {
+	case !reminder.Pending():
+		line += " (done)"
+	case today != "" && reminder.DueDate < today:
+		line += fmt.Sprintf(" (overdue by %d days)", daysBetween(reminder.DueDate, today))
+	case reminder.DueDate == today:
+		line += " (due today)"
+	}
+	return line
+}
+
+// formatDay formats a time as a day, or "never" for 0.
+func formatDay(ts int64, loc *time.Location) string {
+	if ts == 0 {
+		return "never"
+	}
+	return time.Unix(ts, 0).In(loc).Format(time.DateOnly)
+}
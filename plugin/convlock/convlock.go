@@ -0,0 +1,373 @@
+// Package convlock locks AI conversations with a passphrase held by the user.
+//
+// The blocks of a locked conversation (user inputs, assistant content and
+// event contents) are stored encrypted with AES-256-GCM, under a key derived
+// from the passphrase with Argon2id. Only the salt and a verifier of the key
+// are stored: the key is held in memory while the conversation is unlocked,
+// so the server streams answers live and persists them sealed, but the
+// stored history can neither be read nor searched without the passphrase.
+// A lost passphrase cannot be recovered.
+package convlock
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/hrygo/divinesense/store"
+)
+
+var (
+	// ErrInvalid is returned for passphrases that are too short.
+	ErrInvalid = errors.New("passphrase must be at least 8 characters")
+	// ErrWrongPassphrase is returned when a passphrase does not match the lock.
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+	// ErrAlreadyLocked is returned when locking a locked conversation.
+	ErrAlreadyLocked = errors.New("conversation is already locked")
+	// ErrNotLocked is returned when unlocking a conversation that is not locked.
+	ErrNotLocked = errors.New("conversation is not locked")
+	// ErrCorrupted is returned for sealed contents that fail to decrypt.
+	ErrCorrupted = errors.New("sealed content is corrupted")
+	// ErrTooManyAttempts is returned while unlocking is paused after wrong
+	// passphrases.
+	ErrTooManyAttempts = errors.New("too many wrong passphrases, try again later")
+)
+
+const (
+	// DefaultUnlockTTL is how long a key is held after its last use.
+	DefaultUnlockTTL = 30 * time.Minute
+
+	minPassphraseLength = 8
+	saltSize            = 16
+	keySize             = 32
+	sealedPrefix        = "sealed:v1:"
+	verifierLabel       = "divinesense-conversation-lock"
+
+	// maxAttempts wrong passphrases pause the unlocking of a conversation
+	// for attemptsPause.
+	maxAttempts   = 5
+	attemptsPause = time.Minute
+)
+
+// Lock is a locked conversation.
+type Lock struct {
+	ConversationID int32
+	CreatorID      int32
+	Salt           []byte
+	// Verifier is an HMAC of the key, to check passphrases.
+	Verifier  []byte
+	CreatedTs int64
+}
+
+// Status is the lock state of a conversation, as shown to its owner.
+type Status struct {
+	ConversationID int32 `json:"conversation_id"`
+	Unlocked       bool  `json:"unlocked"`
+	// ExpiresAt is when the held key is dropped, if unused (Unix time).
+	ExpiresAt int64 `json:"expires_at,omitempty"`
+	CreatedTs int64 `json:"created_ts"`
+}
+
+// Store persists the locks.
+type Store interface {
+	ListLocks(ctx context.Context) ([]*Lock, error)
+	// CreateLock returns ErrAlreadyLocked for locked conversations.
+	CreateLock(ctx context.Context, lock *Lock) error
+}
+
+// attempts counts the wrong passphrases of a conversation.
+type attempts struct {
+	count       int
+	pausedUntil time.Time
+}
+
+// unlockedKey is the key of an unlocked conversation.
+type unlockedKey struct {
+	aead      cipher.AEAD
+	expiresAt time.Time
+}
+
+// Locks holds the locked conversations and the keys of the unlocked ones.
+// It implements store.BlockSealer.
+type Locks struct {
+	store Store
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu       sync.RWMutex
+	locks    map[int32]*Lock
+	keys     map[int32]*unlockedKey
+	attempts map[int32]*attempts
+}
+
+var _ store.BlockSealer = (*Locks)(nil)
+
+// New creates the locks; ttl defaults to DefaultUnlockTTL.
+func New(store Store, ttl time.Duration) *Locks {
+	if ttl <= 0 {
+		ttl = DefaultUnlockTTL
+	}
+	return &Locks{
+		store:    store,
+		ttl:      ttl,
+		now:      time.Now,
+		locks:    make(map[int32]*Lock),
+		keys:     make(map[int32]*unlockedKey),
+		attempts: make(map[int32]*attempts),
+	}
+}
+
+// Load loads the locks of the store.
+func (l *Locks) Load(ctx context.Context) error {
+	locks, err := l.store.ListLocks(ctx)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, lock := range locks {
+		l.locks[lock.ConversationID] = lock
+	}
+	return nil
+}
+
+// Lock locks a conversation of a user with a passphrase. The conversation is
+// left unlocked, for its history to be sealed.
+func (l *Locks) Lock(ctx context.Context, userID, conversationID int32, passphrase string) error {
+	if len([]rune(passphrase)) < minPassphraseLength {
+		return ErrInvalid
+	}
+	if l.Locked(conversationID) {
+		return ErrAlreadyLocked
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	key := deriveKey(passphrase, salt)
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	lock := &Lock{
+		ConversationID: conversationID,
+		CreatorID:      userID,
+		Salt:           salt,
+		Verifier:       verifier(key),
+		CreatedTs:      l.now().Unix(),
+	}
+	if err := l.store.CreateLock(ctx, lock); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.locks[conversationID] = lock
+	l.keys[conversationID] = &unlockedKey{aead: aead, expiresAt: l.now().Add(l.ttl)}
+	return nil
+}
+
+// Unlock holds the key of a locked conversation of a user.
+func (l *Locks) Unlock(userID, conversationID int32, passphrase string) (*Status, error) {
+	l.mu.RLock()
+	lock := l.locks[conversationID]
+	failed := l.attempts[conversationID]
+	paused := failed != nil && l.now().Before(failed.pausedUntil)
+	l.mu.RUnlock()
+	if lock == nil || lock.CreatorID != userID {
+		return nil, ErrNotLocked
+	}
+	if paused {
+		return nil, ErrTooManyAttempts
+	}
+	key := deriveKey(passphrase, lock.Salt)
+	if !hmac.Equal(verifier(key), lock.Verifier) {
+		l.failAttempt(conversationID)
+		return nil, ErrWrongPassphrase
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.attempts, conversationID)
+	l.keys[conversationID] = &unlockedKey{aead: aead, expiresAt: l.now().Add(l.ttl)}
+	return l.status(lock), nil
+}
+
+// failAttempt counts a wrong passphrase, pausing the unlocking of the
+// conversation every maxAttempts.
+func (l *Locks) failAttempt(conversationID int32) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	failed := l.attempts[conversationID]
+	if failed == nil {
+		failed = &attempts{}
+		l.attempts[conversationID] = failed
+	}
+	failed.count++
+	if failed.count%maxAttempts == 0 {
+		failed.pausedUntil = l.now().Add(attemptsPause)
+	}
+}
+
+// Relock drops the key of a conversation of a user.
+func (l *Locks) Relock(userID, conversationID int32) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lock := l.locks[conversationID]
+	if lock == nil || lock.CreatorID != userID {
+		return ErrNotLocked
+	}
+	delete(l.keys, conversationID)
+	return nil
+}
+
+// List returns the locked conversations of a user, by conversation ID.
+func (l *Locks) List(userID int32) []*Status {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	statuses := []*Status{}
+	for _, lock := range l.locks {
+		if lock.CreatorID == userID {
+			statuses = append(statuses, l.status(lock))
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ConversationID < statuses[j].ConversationID })
+	return statuses
+}
+
+// status returns the status of a lock; l.mu must be held.
+func (l *Locks) status(lock *Lock) *Status {
+	status := &Status{ConversationID: lock.ConversationID, CreatedTs: lock.CreatedTs}
+	if key := l.keys[lock.ConversationID]; key != nil && l.now().Before(key.expiresAt) {
+		status.Unlocked = true
+		status.ExpiresAt = key.expiresAt.Unix()
+	}
+	return status
+}
+
+// HasLocks implements store.BlockSealer.
+func (l *Locks) HasLocks() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.locks) > 0
+}
+
+// Locked implements store.BlockSealer.
+func (l *Locks) Locked(conversationID int32) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.locks[conversationID] != nil
+}
+
+// Unlocked implements store.BlockSealer.
+func (l *Locks) Unlocked(conversationID int32) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	key := l.keys[conversationID]
+	return key != nil && l.now().Before(key.expiresAt)
+}
+
+// key returns the AEAD of an unlocked conversation, extending its hold.
+func (l *Locks) key(conversationID int32) cipher.AEAD {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key := l.keys[conversationID]
+	if key == nil {
+		return nil
+	}
+	now := l.now()
+	if !now.Before(key.expiresAt) {
+		delete(l.keys, conversationID)
+		return nil
+	}
+	key.expiresAt = now.Add(l.ttl)
+	return key.aead
+}
+
+// Seal implements store.BlockSealer.
+func (l *Locks) Seal(conversationID int32, content string) (string, error) {
+	if !l.Locked(conversationID) {
+		return content, nil
+	}
+	aead := l.key(conversationID)
+	if aead == nil {
+		return "", store.ErrConversationLocked
+	}
+	if content == "" {
+		return "", nil
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(content), additionalData(conversationID))
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open implements store.BlockSealer.
+func (l *Locks) Open(conversationID int32, content string) (string, error) {
+	encoded, ok := strings.CutPrefix(content, sealedPrefix)
+	if !ok {
+		return content, nil
+	}
+	aead := l.key(conversationID)
+	if aead == nil {
+		return "", store.ErrConversationLocked
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(data) < aead.NonceSize() {
+		return "", ErrCorrupted
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData(conversationID))
+	if err != nil {
+		return "", ErrCorrupted
+	}
+	return string(plaintext), nil
+}
+
+// deriveKey derives the key of a passphrase with Argon2id.
+func deriveKey(passphrase string, salt []byte) []byte {
+	return argon2.IDKey([]byte(passphrase), salt, 1, 64*1024, 4, keySize)
+}
+
+// verifier returns the HMAC stored to check the passphrases of a key.
+func verifier(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(verifierLabel))
+	return mac.Sum(nil)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return aead, nil
+}
+
+// additionalData binds sealed contents to their conversation.
+func additionalData(conversationID int32) []byte {
+	return []byte("conversation:" + strconv.Itoa(int(conversationID)))
+}
@@ -0,0 +1,137 @@
+package convlock
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/store"
+)
+
+type fakeStore struct {
+	locks []*Lock
+}
+
+func (s *fakeStore) ListLocks(_ context.Context) ([]*Lock, error) {
+	return s.locks, nil
+}
+
+func (s *fakeStore) CreateLock(_ context.Context, lock *Lock) error {
+	for _, existing := range s.locks {
+		if existing.ConversationID == lock.ConversationID {
+			return ErrAlreadyLocked
+		}
+	}
+	s.locks = append(s.locks, lock)
+	return nil
+}
+
+func TestLocks_SealOpen(t *testing.T) {
+	ctx := context.Background()
+	locks := New(&fakeStore{}, time.Minute)
+
+	// Conversations that are not locked are stored in clear
+	assert.False(t, locks.HasLocks())
+	content, err := locks.Seal(7, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", content)
+
+	assert.ErrorIs(t, locks.Lock(ctx, 1, 7, "short"), ErrInvalid)
+	require.NoError(t, locks.Lock(ctx, 1, 7, "correct horse"))
+	assert.ErrorIs(t, locks.Lock(ctx, 1, 7, "correct horse"), ErrAlreadyLocked)
+	assert.True(t, locks.HasLocks())
+	assert.True(t, locks.Unlocked(7))
+
+	sealed, err := locks.Seal(7, "my secret question")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sealed, sealedPrefix))
+	assert.NotContains(t, sealed, "secret")
+	opened, err := locks.Open(7, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "my secret question", opened)
+
+	// History stored before the lock is read as is
+	opened, err = locks.Open(7, "stored before the lock")
+	require.NoError(t, err)
+	assert.Equal(t, "stored before the lock", opened)
+
+	// Sealed contents are bound to their conversation
+	require.NoError(t, locks.Lock(ctx, 1, 8, "correct horse"))
+	_, err = locks.Open(8, sealed)
+	assert.ErrorIs(t, err, ErrCorrupted)
+
+	// Relocked conversations can be neither read nor written
+	require.NoError(t, locks.Relock(1, 7))
+	_, err = locks.Open(7, sealed)
+	assert.ErrorIs(t, err, store.ErrConversationLocked)
+	_, err = locks.Seal(7, "more")
+	assert.ErrorIs(t, err, store.ErrConversationLocked)
+}
+
+func TestLocks_Unlock(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeStore{}
+	now := time.Unix(1760000000, 0)
+	locks := New(fake, time.Minute)
+	locks.now = func() time.Time { return now }
+	require.NoError(t, locks.Lock(ctx, 1, 7, "correct horse"))
+	sealed, err := locks.Seal(7, "answer")
+	require.NoError(t, err)
+
+	// A restarted server holds no keys
+	restarted := New(fake, time.Minute)
+	restarted.now = func() time.Time { return now }
+	require.NoError(t, restarted.Load(ctx))
+	assert.True(t, restarted.Locked(7))
+	assert.False(t, restarted.Unlocked(7))
+
+	_, err = restarted.Unlock(1, 7, "wrong horse")
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+	_, err = restarted.Unlock(2, 7, "correct horse")
+	assert.ErrorIs(t, err, ErrNotLocked, "locks of other users are not disclosed")
+	status, err := restarted.Unlock(1, 7, "correct horse")
+	require.NoError(t, err)
+	assert.True(t, status.Unlocked)
+	assert.Equal(t, now.Add(time.Minute).Unix(), status.ExpiresAt)
+
+	opened, err := restarted.Open(7, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "answer", opened)
+
+	// Keys are held for the TTL after their last use
+	now = now.Add(50 * time.Second)
+	_, err = restarted.Open(7, sealed)
+	require.NoError(t, err)
+	now = now.Add(50 * time.Second)
+	assert.True(t, restarted.Unlocked(7))
+	now = now.Add(time.Minute)
+	assert.False(t, restarted.Unlocked(7))
+	_, err = restarted.Open(7, sealed)
+	assert.ErrorIs(t, err, store.ErrConversationLocked)
+
+	assert.Equal(t, []*Status{{ConversationID: 7, CreatedTs: 1760000000}}, restarted.List(1))
+	assert.Empty(t, restarted.List(2))
+}
+
+func TestLocks_TooManyAttempts(t *testing.T) {
+	now := time.Unix(1760000000, 0)
+	locks := New(&fakeStore{}, time.Minute)
+	locks.now = func() time.Time { return now }
+	require.NoError(t, locks.Lock(context.Background(), 1, 7, "correct horse"))
+
+	for range maxAttempts {
+		_, err := locks.Unlock(1, 7, "wrong horse")
+		assert.ErrorIs(t, err, ErrWrongPassphrase)
+	}
+	// Even the right passphrase is refused while paused
+	_, err := locks.Unlock(1, 7, "correct horse")
+	assert.ErrorIs(t, err, ErrTooManyAttempts)
+
+	now = now.Add(attemptsPause)
+	_, err = locks.Unlock(1, 7, "correct horse")
+	require.NoError(t, err)
+}
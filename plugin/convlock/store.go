@@ -0,0 +1,54 @@
+package convlock
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DBStore persists locks in the ai_conversation_lock table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new lock store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// ListLocks implements Store.
+func (s *DBStore) ListLocks(ctx context.Context) ([]*Lock, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT conversation_id, creator_id, salt, verifier, created_ts FROM ai_conversation_lock")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversation locks: %w", err)
+	}
+	defer rows.Close()
+
+	locks := []*Lock{}
+	for rows.Next() {
+		lock := &Lock{}
+		if err := rows.Scan(&lock.ConversationID, &lock.CreatorID, &lock.Salt, &lock.Verifier, &lock.CreatedTs); err != nil {
+			return nil, fmt.Errorf("failed to scan conversation lock: %w", err)
+		}
+		locks = append(locks, lock)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list conversation locks: %w", err)
+	}
+	return locks, nil
+}
+
+// CreateLock implements Store.
+func (s *DBStore) CreateLock(ctx context.Context, lock *Lock) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO ai_conversation_lock (conversation_id, creator_id, salt, verifier, created_ts)
+		VALUES ($1, $2, $3, $4, $5) ON CONFLICT (conversation_id) DO NOTHING`,
+		lock.ConversationID, lock.CreatorID, lock.Salt, lock.Verifier, lock.CreatedTs)
+	if err != nil {
+		return fmt.Errorf("failed to create conversation lock: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrAlreadyLocked
+	}
+	return nil
+}
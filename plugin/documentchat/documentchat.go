@@ -0,0 +1,140 @@
+// Package documentchat binds AI conversations to a single document.
+//
+// A conversation bound to a memo or an attachment is about that document
+// only: retrieval searches the passages of the document instead of the
+// memos, answers cite passages by their character offsets in the document,
+// and exports carry the cited passages so that answers stand on their own.
+// The binding is a setting of the conversation stored in its metadata; chat
+// requests carry the loaded document in their context.
+package documentchat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hrygo/divinesense/ai/core/retrieval"
+)
+
+// Kinds of the documents a conversation can be bound to.
+const (
+	KindMemo       = "memo"
+	KindAttachment = "attachment"
+)
+
+var (
+	// ErrInvalid is returned for invalid bindings.
+	ErrInvalid = errors.New("invalid document binding")
+	// ErrNotFound is returned when the document of a binding does not exist
+	// or has no text.
+	ErrNotFound = errors.New("document not found")
+)
+
+// Binding is the document a conversation is bound to.
+type Binding struct {
+	Kind string `json:"kind"`
+	UID  string `json:"uid"`
+}
+
+// Info describes a bound document.
+type Info struct {
+	Kind  string `json:"kind"`
+	UID   string `json:"uid"`
+	Title string `json:"title"`
+	// Length is the length of the text of the document, in characters.
+	Length int `json:"length"`
+	// Passages is the number of passages retrieval searches.
+	Passages int `json:"passages"`
+}
+
+// InfoOf describes a document.
+func InfoOf(doc *retrieval.Document) *Info {
+	return &Info{
+		Kind:     doc.Kind,
+		UID:      doc.UID,
+		Title:    doc.Title,
+		Length:   utf8.RuneCountInString(doc.Text),
+		Passages: len(doc.Passages()),
+	}
+}
+
+// Store persists the bindings in the metadata of the conversations.
+type Store interface {
+	// GetBinding returns the binding of a conversation, or nil.
+	GetBinding(ctx context.Context, conversationID int32) (*Binding, error)
+	// SaveBinding sets the binding of a conversation; nil removes it.
+	SaveBinding(ctx context.Context, conversationID int32, binding *Binding) error
+}
+
+// Loader loads the text of the documents of a user.
+type Loader interface {
+	// LoadDocument returns the document of a binding, or nil if the user has
+	// no such document.
+	LoadDocument(ctx context.Context, userID int32, binding *Binding) (*retrieval.Document, error)
+}
+
+// Documents binds conversations to documents and loads them.
+type Documents struct {
+	store  Store
+	loader Loader
+}
+
+// New creates the document bindings.
+func New(store Store, loader Loader) *Documents {
+	return &Documents{store: store, loader: loader}
+}
+
+// Bind binds a conversation to a document of the user and returns the
+// document.
+func (d *Documents) Bind(ctx context.Context, userID, conversationID int32, binding *Binding) (*retrieval.Document, error) {
+	binding = &Binding{Kind: strings.TrimSpace(binding.Kind), UID: strings.TrimSpace(binding.UID)}
+	if binding.Kind != KindMemo && binding.Kind != KindAttachment {
+		return nil, fmt.Errorf("%w: kind must be %s or %s", ErrInvalid, KindMemo, KindAttachment)
+	}
+	if binding.UID == "" {
+		return nil, fmt.Errorf("%w: uid is required", ErrInvalid)
+	}
+	doc, err := d.Open(ctx, userID, binding)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.store.SaveBinding(ctx, conversationID, binding); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Unbind returns a conversation to memo retrieval.
+func (d *Documents) Unbind(ctx context.Context, conversationID int32) error {
+	return d.store.SaveBinding(ctx, conversationID, nil)
+}
+
+// Load returns the document a conversation of the user is bound to, or nil.
+func (d *Documents) Load(ctx context.Context, userID, conversationID int32) (*retrieval.Document, error) {
+	if conversationID <= 0 {
+		return nil, nil
+	}
+	binding, err := d.store.GetBinding(ctx, conversationID)
+	if err != nil || binding == nil {
+		return nil, err
+	}
+	return d.Open(ctx, userID, binding)
+}
+
+// Open loads the document of a binding; ErrNotFound when the user has no
+// such document or it has no text.
+func (d *Documents) Open(ctx context.Context, userID int32, binding *Binding) (*retrieval.Document, error) {
+	doc, err := d.loader.LoadDocument(ctx, userID, binding)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrNotFound, binding.Kind, binding.UID)
+	}
+	if strings.TrimSpace(doc.Text) == "" {
+		return nil, fmt.Errorf("%w: %s %s has no text", ErrNotFound, binding.Kind, binding.UID)
+	}
+	return doc, nil
+}
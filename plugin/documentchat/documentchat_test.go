@@ -0,0 +1,82 @@
+package documentchat
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/ai/core/retrieval"
+)
+
+// fakeStore keeps the bindings in memory.
+type fakeStore struct {
+	bindings map[int32]*Binding
+}
+
+func (s *fakeStore) GetBinding(_ context.Context, conversationID int32) (*Binding, error) {
+	return s.bindings[conversationID], nil
+}
+
+func (s *fakeStore) SaveBinding(_ context.Context, conversationID int32, binding *Binding) error {
+	if binding == nil {
+		delete(s.bindings, conversationID)
+		return nil
+	}
+	s.bindings[conversationID] = binding
+	return nil
+}
+
+// fakeLoader holds the documents of user 1 by UID.
+type fakeLoader map[string]*retrieval.Document
+
+func (l fakeLoader) LoadDocument(_ context.Context, userID int32, binding *Binding) (*retrieval.Document, error) {
+	doc := l[binding.UID]
+	if userID != 1 || doc == nil || doc.Kind != binding.Kind {
+		return nil, nil
+	}
+	return doc, nil
+}
+
+func TestDocuments(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{bindings: map[int32]*Binding{}}
+	documents := New(store, fakeLoader{
+		"m1":    {Kind: KindMemo, UID: "m1", Title: "Plan", Text: "Ship v2 in May."},
+		"blank": {Kind: KindAttachment, UID: "blank", Title: "scan.pdf", Text: " \n"},
+	})
+
+	doc, err := documents.Bind(ctx, 1, 7, &Binding{Kind: " memo", UID: "m1 "})
+	require.NoError(t, err)
+	assert.Equal(t, &Info{Kind: KindMemo, UID: "m1", Title: "Plan", Length: 15, Passages: 1}, InfoOf(doc))
+	assert.Equal(t, &Binding{Kind: KindMemo, UID: "m1"}, store.bindings[7])
+
+	loaded, err := documents.Load(ctx, 1, 7)
+	require.NoError(t, err)
+	assert.Same(t, doc, loaded)
+
+	// Another user's conversation cannot open the document
+	_, err = documents.Load(ctx, 2, 7)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = documents.Bind(ctx, 1, 8, &Binding{Kind: "space", UID: "m1"})
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = documents.Bind(ctx, 1, 8, &Binding{Kind: KindMemo})
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = documents.Bind(ctx, 1, 8, &Binding{Kind: KindAttachment, UID: "blank"})
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = documents.Bind(ctx, 1, 8, &Binding{Kind: KindAttachment, UID: "m1"})
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotContains(t, store.bindings, int32(8))
+
+	require.NoError(t, documents.Unbind(ctx, 7))
+	loaded, err = documents.Load(ctx, 1, 7)
+	require.NoError(t, err)
+	assert.Nil(t, loaded)
+}
+
+func TestMemoTitle(t *testing.T) {
+	assert.Equal(t, "Weekly review", memoTitle("\n# Weekly review\n\nNotes"))
+	assert.Equal(t, "一二三四五六七八九十一二三四五六七八九十一二三四五六七八九十一二三四五六七八九十…", memoTitle("一二三四五六七八九十一二三四五六七八九十一二三四五六七八九十一二三四五六七八九十多"))
+}
@@ -0,0 +1,64 @@
+package documentchat
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hrygo/divinesense/ai/core/retrieval"
+	"github.com/hrygo/divinesense/store"
+)
+
+// titleRunes bounds the titles taken from the first line of memos.
+const titleRunes = 40
+
+// StoreLoader loads memos and the extracted text of attachments.
+type StoreLoader struct {
+	store *store.Store
+}
+
+// NewStoreLoader creates a loader reading the store.
+func NewStoreLoader(s *store.Store) *StoreLoader {
+	return &StoreLoader{store: s}
+}
+
+// LoadDocument implements Loader. Attachments are loaded with the text
+// extracted from their document, or recognized in their image.
+func (l *StoreLoader) LoadDocument(ctx context.Context, userID int32, binding *Binding) (*retrieval.Document, error) {
+	switch binding.Kind {
+	case KindMemo:
+		memo, err := l.store.GetMemo(ctx, &store.FindMemo{UID: &binding.UID, CreatorID: &userID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get memo: %w", err)
+		}
+		if memo == nil || memo.RowStatus != store.Normal {
+			return nil, nil
+		}
+		return &retrieval.Document{Kind: KindMemo, UID: memo.UID, Title: memoTitle(memo.Content), Text: memo.Content}, nil
+	case KindAttachment:
+		attachment, err := l.store.GetAttachment(ctx, &store.FindAttachment{UID: &binding.UID, CreatorID: &userID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get attachment: %w", err)
+		}
+		if attachment == nil || attachment.RowStatus != string(store.Normal) {
+			return nil, nil
+		}
+		text := attachment.ExtractedText
+		if strings.TrimSpace(text) == "" {
+			text = attachment.OCRText
+		}
+		return &retrieval.Document{Kind: KindAttachment, UID: attachment.UID, Title: attachment.Filename, Text: text}, nil
+	}
+	return nil, nil
+}
+
+// memoTitle returns the first line of a memo, without its heading marks.
+func memoTitle(content string) string {
+	title, _, _ := strings.Cut(strings.TrimSpace(content), "\n")
+	title = strings.TrimSpace(strings.TrimLeft(title, "# "))
+	if utf8.RuneCountInString(title) > titleRunes {
+		title = string([]rune(title)[:titleRunes]) + "…"
+	}
+	return title
+}
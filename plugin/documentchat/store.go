@@ -0,0 +1,56 @@
+package documentchat
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// DBStore persists the bindings under the document key of the metadata
+// column of the ai_conversation table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new binding store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// GetBinding implements Store.
+func (s *DBStore) GetBinding(ctx context.Context, conversationID int32) (*Binding, error) {
+	var raw []byte
+	err := s.db.QueryRowContext(ctx, "SELECT metadata -> 'document' FROM ai_conversation WHERE id = $1", conversationID).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && raw == nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document binding: %w", err)
+	}
+	var binding Binding
+	if err := json.Unmarshal(raw, &binding); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal document binding: %w", err)
+	}
+	return &binding, nil
+}
+
+// SaveBinding implements Store.
+func (s *DBStore) SaveBinding(ctx context.Context, conversationID int32, binding *Binding) error {
+	var err error
+	if binding == nil {
+		_, err = s.db.ExecContext(ctx, "UPDATE ai_conversation SET metadata = metadata - 'document' WHERE id = $1", conversationID)
+	} else {
+		var raw []byte
+		if raw, err = json.Marshal(binding); err != nil {
+			return fmt.Errorf("failed to marshal document binding: %w", err)
+		}
+		_, err = s.db.ExecContext(ctx, "UPDATE ai_conversation SET metadata = jsonb_set(metadata, '{document}', $2::jsonb) WHERE id = $1",
+			conversationID, string(raw))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save document binding: %w", err)
+	}
+	return nil
+}
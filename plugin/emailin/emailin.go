@@ -0,0 +1,337 @@
+// Package emailin turns the emails users send to their secret address into
+// memos.
+//
+// Each user may create an inbox: a secret address "<token>@<domain>" on the
+// inbound domain of the instance. The mail provider receiving that domain
+// (Mailgun routes, or any provider posting the same fields) forwards the
+// emails to the inbound webhook, which verifies the signature of the
+// provider, resolves the user from the recipient and saves the email as a
+// memo: the subject as heading, the text without its signature, and the
+// attached files as attachments.
+package emailin
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/mail"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Tag is the tag of the memos of emails.
+	Tag = "email"
+	// MaxAttachments bounds the attached files saved from an email.
+	MaxAttachments = 10
+	// signatureMaxAge bounds the age of webhook signatures, and how long
+	// their tokens are remembered against replays.
+	signatureMaxAge = 15 * time.Minute
+)
+
+var (
+	// ErrInvalid is returned for emails without content.
+	ErrInvalid = errors.New("invalid email")
+	// ErrNotFound is returned for users without an inbox and for recipients
+	// matching no inbox.
+	ErrNotFound = errors.New("email inbox not found")
+	// ErrSignature is returned for webhooks without a valid, fresh signature.
+	ErrSignature = errors.New("invalid webhook signature")
+)
+
+// Inbox is the secret address of a user.
+type Inbox struct {
+	UserID    int32  `json:"-"`
+	Token     string `json:"-"`
+	Address   string `json:"address"`
+	CreatedTs int64  `json:"created_ts"`
+}
+
+// Store persists the inboxes.
+type Store interface {
+	GetInbox(ctx context.Context, userID int32) (*Inbox, error)
+	GetInboxByToken(ctx context.Context, token string) (*Inbox, error)
+	// SaveInbox sets the token of the inbox of a user, creating it.
+	SaveInbox(ctx context.Context, userID int32, token string) (*Inbox, error)
+	DeleteInbox(ctx context.Context, userID int32) error
+}
+
+// Config configures the inboxes.
+type Config struct {
+	// Domain is the inbound domain of the addresses.
+	Domain string
+	// SigningKey verifies the signatures of the webhooks of the provider.
+	SigningKey string
+}
+
+// Inboxes manages the inboxes and verifies the inbound webhooks.
+type Inboxes struct {
+	config Config
+	store  Store
+
+	mu sync.Mutex
+	// seen are the tokens of the recent webhooks, by their expiry.
+	seen map[string]time.Time
+}
+
+// New creates the inboxes.
+func New(config Config, store Store) *Inboxes {
+	config.Domain = strings.ToLower(strings.TrimSpace(config.Domain))
+	return &Inboxes{config: config, store: store, seen: map[string]time.Time{}}
+}
+
+// Inbox returns the inbox of a user.
+func (i *Inboxes) Inbox(ctx context.Context, userID int32) (*Inbox, error) {
+	inbox, err := i.store.GetInbox(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return i.withAddress(inbox), nil
+}
+
+// Rotate gives a user a new secret address, creating their inbox. Emails to
+// the previous address are rejected.
+func (i *Inboxes) Rotate(ctx context.Context, userID int32) (*Inbox, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate inbox token: %w", err)
+	}
+	inbox, err := i.store.SaveInbox(ctx, userID, hex.EncodeToString(b))
+	if err != nil {
+		return nil, err
+	}
+	return i.withAddress(inbox), nil
+}
+
+// Delete deletes the inbox of a user.
+func (i *Inboxes) Delete(ctx context.Context, userID int32) error {
+	return i.store.DeleteInbox(ctx, userID)
+}
+
+// Resolve returns the inbox of the first recipient on the inbound domain
+// matching one. Recipients are an address list, as in the To header.
+func (i *Inboxes) Resolve(ctx context.Context, recipients string) (*Inbox, error) {
+	for _, address := range parseAddresses(recipients) {
+		local, domain, ok := strings.Cut(strings.ToLower(address), "@")
+		if !ok || domain != i.config.Domain || local == "" {
+			continue
+		}
+		inbox, err := i.store.GetInboxByToken(ctx, local)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return i.withAddress(inbox), nil
+	}
+	return nil, ErrNotFound
+}
+
+// Verify checks the signature of a webhook: the hex HMAC-SHA256 of the
+// timestamp and token under the signing key. Stale timestamps and replayed
+// tokens are rejected.
+func (i *Inboxes) Verify(timestamp, token, signature string, now time.Time) error {
+	if i.config.SigningKey == "" || token == "" {
+		return ErrSignature
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrSignature
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > signatureMaxAge || age < -signatureMaxAge {
+		return fmt.Errorf("%w: stale timestamp", ErrSignature)
+	}
+	mac := hmac.New(sha256.New, []byte(i.config.SigningKey))
+	mac.Write([]byte(timestamp + token))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return ErrSignature
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for seen, expires := range i.seen {
+		if now.After(expires) {
+			delete(i.seen, seen)
+		}
+	}
+	if _, ok := i.seen[token]; ok {
+		return fmt.Errorf("%w: replayed token", ErrSignature)
+	}
+	i.seen[token] = now.Add(2 * signatureMaxAge)
+	return nil
+}
+
+func (i *Inboxes) withAddress(inbox *Inbox) *Inbox {
+	inbox.Address = inbox.Token + "@" + i.config.Domain
+	return inbox
+}
+
+// parseAddresses returns the addresses of an address list, tolerating
+// malformed entries.
+func parseAddresses(list string) []string {
+	if parsed, err := mail.ParseAddressList(list); err == nil {
+		addresses := make([]string, 0, len(parsed))
+		for _, address := range parsed {
+			addresses = append(addresses, address.Address)
+		}
+		return addresses
+	}
+	var addresses []string
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if start, end := strings.LastIndex(entry, "<"), strings.LastIndex(entry, ">"); start >= 0 && end > start {
+			entry = entry[start+1 : end]
+		}
+		if entry != "" {
+			addresses = append(addresses, entry)
+		}
+	}
+	return addresses
+}
+
+// Email is a received email.
+type Email struct {
+	From    string
+	Subject string
+	// Text is the plain text body.
+	Text        string
+	Attachments []*Attachment
+}
+
+// Attachment is a file attached to an email.
+type Attachment struct {
+	Filename string
+	MimeType string
+	Data     []byte
+}
+
+// FromForm reads an email from the fields of a Mailgun-style webhook:
+// "from", "subject", "body-plain" (or "stripped-text") and the files
+// "attachment-1" to "attachment-N".
+func FromForm(form *multipart.Form) (*Email, error) {
+	value := func(key string) string {
+		if values := form.Value[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	email := &Email{
+		From:    value("from"),
+		Subject: strings.TrimSpace(value("subject")),
+		Text:    value("body-plain"),
+	}
+	if strings.TrimSpace(email.Text) == "" {
+		email.Text = value("stripped-text")
+	}
+	for n := 1; len(email.Attachments) < MaxAttachments; n++ {
+		files := form.File["attachment-"+strconv.Itoa(n)]
+		if len(files) == 0 {
+			break
+		}
+		attachment, err := readAttachment(files[0])
+		if err != nil {
+			return nil, err
+		}
+		email.Attachments = append(email.Attachments, attachment)
+	}
+	if email.Subject == "" && strings.TrimSpace(email.Text) == "" && len(email.Attachments) == 0 {
+		return nil, fmt.Errorf("%w: no subject, text or attachment", ErrInvalid)
+	}
+	return email, nil
+}
+
+func readAttachment(header *multipart.FileHeader) (*Attachment, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open attachment %q: %w", header.Filename, err)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment %q: %w", header.Filename, err)
+	}
+	return &Attachment{
+		Filename: SanitizeFilename(header.Filename),
+		MimeType: header.Header.Get("Content-Type"),
+		Data:     data,
+	}, nil
+}
+
+// Content returns the memo of an email: the subject as heading, the text
+// without signature, and the email tag.
+func (e *Email) Content() string {
+	var parts []string
+	if e.Subject != "" {
+		parts = append(parts, "## "+strings.Join(strings.Fields(e.Subject), " "))
+	}
+	if text := StripSignature(e.Text); text != "" {
+		parts = append(parts, text)
+	}
+	parts = append(parts, "#"+Tag)
+	return strings.Join(parts, "\n\n")
+}
+
+// mobileSignatures start the one-line signatures mail apps append.
+var mobileSignatures = []string{
+	"sent from my ",
+	"sent from mail for ",
+	"sent from outlook",
+	"get outlook for ",
+	"发自我的",
+	"来自我的",
+}
+
+// StripSignature removes the signature of an email text: everything from the
+// "-- " delimiter line on, or a trailing "Sent from my iPhone" line.
+func StripSignature(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for n, line := range lines {
+		if strings.TrimRight(line, " ") == "--" {
+			lines = lines[:n]
+			break
+		}
+	}
+	for n := len(lines) - 1; n >= 0; n-- {
+		line := strings.ToLower(strings.TrimSpace(lines[n]))
+		if line == "" {
+			continue
+		}
+		for _, prefix := range mobileSignatures {
+			if strings.HasPrefix(line, prefix) {
+				lines = lines[:n]
+				break
+			}
+		}
+		break
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// SanitizeFilename returns a file name valid for attachments: its base name
+// without leading or trailing dots and spaces.
+func SanitizeFilename(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = filepath.Base(name)
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, " .")
+	if name == "" || name == "/" {
+		return "attachment"
+	}
+	return name
+}
@@ -0,0 +1,170 @@
+package emailin
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore is an in-memory Store.
+type memStore struct {
+	inboxes map[int32]*Inbox
+}
+
+func (s *memStore) GetInbox(_ context.Context, userID int32) (*Inbox, error) {
+	if inbox, ok := s.inboxes[userID]; ok {
+		copied := *inbox
+		return &copied, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (s *memStore) GetInboxByToken(_ context.Context, token string) (*Inbox, error) {
+	for _, inbox := range s.inboxes {
+		if inbox.Token == token {
+			copied := *inbox
+			return &copied, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *memStore) SaveInbox(_ context.Context, userID int32, token string) (*Inbox, error) {
+	s.inboxes[userID] = &Inbox{UserID: userID, Token: token, CreatedTs: 1}
+	copied := *s.inboxes[userID]
+	return &copied, nil
+}
+
+func (s *memStore) DeleteInbox(_ context.Context, userID int32) error {
+	if _, ok := s.inboxes[userID]; !ok {
+		return ErrNotFound
+	}
+	delete(s.inboxes, userID)
+	return nil
+}
+
+func newInboxes() *Inboxes {
+	return New(Config{Domain: "In.Example.com", SigningKey: "key"}, &memStore{inboxes: map[int32]*Inbox{}})
+}
+
+func TestRotateAndResolve(t *testing.T) {
+	ctx := context.Background()
+	inboxes := newInboxes()
+
+	_, err := inboxes.Inbox(ctx, 7)
+	require.ErrorIs(t, err, ErrNotFound)
+	first, err := inboxes.Rotate(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, first.Token+"@in.example.com", first.Address)
+	assert.Len(t, first.Token, 24)
+
+	got, err := inboxes.Resolve(ctx, `"Notes" <`+strings.ToUpper(first.Token)+`@IN.example.com>, other@example.com`)
+	require.NoError(t, err)
+	assert.Equal(t, int32(7), got.UserID)
+
+	second, err := inboxes.Rotate(ctx, 7)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Token, second.Token)
+	_, err = inboxes.Resolve(ctx, first.Address)
+	assert.ErrorIs(t, err, ErrNotFound, "the previous address is rejected")
+	_, err = inboxes.Resolve(ctx, second.Token+"@elsewhere.com")
+	assert.ErrorIs(t, err, ErrNotFound, "other domains never match")
+
+	require.NoError(t, inboxes.Delete(ctx, 7))
+	assert.ErrorIs(t, inboxes.Delete(ctx, 7), ErrNotFound)
+}
+
+func sign(key, timestamp, token string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(timestamp + token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerify(t *testing.T) {
+	inboxes := newInboxes()
+	now := time.Unix(1_800_000_000, 0)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	require.NoError(t, inboxes.Verify(timestamp, "tok1", sign("key", timestamp, "tok1"), now))
+	assert.ErrorIs(t, inboxes.Verify(timestamp, "tok1", sign("key", timestamp, "tok1"), now), ErrSignature, "replayed")
+	assert.ErrorIs(t, inboxes.Verify(timestamp, "tok2", sign("other", timestamp, "tok2"), now), ErrSignature, "wrong key")
+	assert.ErrorIs(t, inboxes.Verify(timestamp, "tok3", sign("key", timestamp, "tok3"), now.Add(time.Hour)), ErrSignature, "stale")
+	assert.ErrorIs(t, inboxes.Verify("abc", "tok4", sign("key", "abc", "tok4"), now), ErrSignature)
+
+	unsigned := New(Config{Domain: "in.example.com"}, &memStore{})
+	assert.ErrorIs(t, unsigned.Verify(timestamp, "tok5", sign("", timestamp, "tok5"), now), ErrSignature, "no signing key")
+}
+
+func TestStripSignature(t *testing.T) {
+	for _, tc := range []struct {
+		name, text, want string
+	}{
+		{"delimiter", "Buy milk\r\n\r\n-- \r\nAlice\r\nACME Corp", "Buy milk"},
+		{"mobile", "Buy milk\n\nSent from my iPhone\n", "Buy milk"},
+		{"chinese mobile", "买牛奶\n\n发自我的iPhone", "买牛奶"},
+		{"mobile mid-text stays", "Sent from my iPhone is a signature\nbut this is not", "Sent from my iPhone is a signature\nbut this is not"},
+		{"dashes in text", "a -- b\n--- \nkeep", "a -- b\n--- \nkeep"},
+	} {
+		assert.Equal(t, tc.want, StripSignature(tc.text), tc.name)
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	assert.Equal(t, "report.pdf", SanitizeFilename("..\\..\\report.pdf"))
+	assert.Equal(t, "passwd", SanitizeFilename("/etc/passwd"))
+	assert.Equal(t, "notes.txt", SanitizeFilename(" notes.txt. "))
+	assert.Equal(t, "attachment", SanitizeFilename(".."))
+	assert.Equal(t, "ab.txt", SanitizeFilename("a\x00b.txt"))
+}
+
+func newForm(t *testing.T, fields map[string]string, files map[string]string) *multipart.Form {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for key, value := range fields {
+		require.NoError(t, writer.WriteField(key, value))
+	}
+	for key, content := range files {
+		part, err := writer.CreateFormFile(key, "../"+key+".txt")
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	form, err := multipart.NewReader(body, writer.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = form.RemoveAll() })
+	return form
+}
+
+func TestFromForm(t *testing.T) {
+	form := newForm(t, map[string]string{
+		"from":       "Alice <alice@example.com>",
+		"subject":    "  Weekly   plan ",
+		"body-plain": "Ship the release\n\n-- \nAlice",
+	}, map[string]string{"attachment-1": "one", "attachment-2": "two", "attachment-4": "skipped"})
+
+	email, err := FromForm(form)
+	require.NoError(t, err)
+	assert.Equal(t, "## Weekly plan\n\nShip the release\n\n#email", email.Content())
+	require.Len(t, email.Attachments, 2, "attachments stop at the first gap")
+	assert.Equal(t, "attachment-1.txt", email.Attachments[0].Filename)
+	assert.Equal(t, []byte("two"), email.Attachments[1].Data)
+
+	_, err = FromForm(newForm(t, map[string]string{"from": "alice@example.com", "body-plain": "  "}, nil))
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	email, err = FromForm(newForm(t, map[string]string{"body-plain": "", "stripped-text": "only text"}, nil))
+	require.NoError(t, err)
+	assert.Equal(t, "only text\n\n#email", email.Content())
+}
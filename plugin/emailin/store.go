@@ -0,0 +1,86 @@
+package emailin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DBStore persists the inboxes in the email_inbox table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new inbox store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const inboxColumns = "user_id, token, created_ts"
+
+// GetInbox implements Store.
+func (s *DBStore) GetInbox(ctx context.Context, userID int32) (*Inbox, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+inboxColumns+" FROM email_inbox WHERE user_id = $1", userID)
+	return getInbox(row)
+}
+
+// GetInboxByToken implements Store.
+func (s *DBStore) GetInboxByToken(ctx context.Context, token string) (*Inbox, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+inboxColumns+" FROM email_inbox WHERE token = $1", token)
+	return getInbox(row)
+}
+
+// SaveInbox implements Store.
+func (s *DBStore) SaveInbox(ctx context.Context, userID int32, token string) (*Inbox, error) {
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO email_inbox (user_id, token, created_ts)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET
+			token = EXCLUDED.token,
+			created_ts = EXCLUDED.created_ts
+		RETURNING `+inboxColumns,
+		userID, token, time.Now().Unix())
+	inbox, err := scanInbox(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save email inbox: %w", err)
+	}
+	return inbox, nil
+}
+
+// DeleteInbox implements Store.
+func (s *DBStore) DeleteInbox(ctx context.Context, userID int32) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM email_inbox WHERE user_id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete email inbox: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// getInbox scans an inbox, mapping a missing row to ErrNotFound.
+func getInbox(row rowScanner) (*Inbox, error) {
+	inbox, err := scanInbox(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email inbox: %w", err)
+	}
+	return inbox, nil
+}
+
+func scanInbox(row rowScanner) (*Inbox, error) {
+	inbox := &Inbox{}
+	if err := row.Scan(&inbox.UserID, &inbox.Token, &inbox.CreatedTs); err != nil {
+		return nil, err
+	}
+	return inbox, nil
+}
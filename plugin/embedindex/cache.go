@@ -0,0 +1,68 @@
+package embedindex
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/hrygo/divinesense/ai/cache"
+)
+
+// CacheStats are the statistics of the query embedding cache.
+type CacheStats struct {
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	HitRate  float64 `json:"hit_rate"`
+	Size     int     `json:"size"`
+	Capacity int     `json:"capacity"`
+}
+
+// queryCache caches the embeddings of texts by model, so that repeated
+// searches and routing checks do not call the provider again.
+type queryCache struct {
+	vectors *cache.LRUCache[string, []float32]
+	hits    atomic.Int64
+	misses  atomic.Int64
+}
+
+func newQueryCache(size int, ttl time.Duration) *queryCache {
+	return &queryCache{vectors: cache.NewLRUCache[string, []float32](size, ttl)}
+}
+
+// queryCacheKey hashes the text so that long texts do not pin memory in keys.
+func queryCacheKey(model, text string) string {
+	hash := sha256.Sum256([]byte(model + "\x00" + text))
+	return hex.EncodeToString(hash[:])
+}
+
+// get returns a copy of the cached embedding of text, since callers may
+// normalize vectors in place.
+func (c *queryCache) get(model, text string) ([]float32, bool) {
+	vector, ok := c.vectors.Get(queryCacheKey(model, text))
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	return slices.Clone(vector), true
+}
+
+func (c *queryCache) set(model, text string, vector []float32) {
+	c.vectors.SetWithDefaultTTL(queryCacheKey(model, text), slices.Clone(vector))
+}
+
+func (c *queryCache) stats() *CacheStats {
+	hits, misses := c.hits.Load(), c.misses.Load()
+	stats := &CacheStats{
+		Hits:     hits,
+		Misses:   misses,
+		Size:     c.vectors.Size(),
+		Capacity: c.vectors.Capacity(),
+	}
+	if total := hits + misses; total > 0 {
+		stats.HitRate = float64(hits) / float64(total)
+	}
+	return stats
+}
@@ -0,0 +1,156 @@
+// Package embedindex tracks the rebuilds of the memo embedding index and
+// which embedding model is active.
+//
+// Switching embedding models re-embeds the corpus into a shadow index: the
+// memo_embedding rows of the target model, written next to the active ones
+// that keep serving searches. A rebuild resumes where it stopped, since it
+// only embeds the memos without a target embedding. Once its recall is
+// verified on a sample, the swap marks the rebuild as swapped in one
+// transaction; servers pick the new active model up with Watch.
+package embedindex
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned for unknown rebuilds.
+	ErrNotFound = errors.New("embedding rebuild not found")
+	// ErrInvalid is returned for rebuilds that cannot start or swap.
+	ErrInvalid = errors.New("invalid embedding rebuild")
+	// ErrLowRecall is returned when the shadow index fails its verification.
+	ErrLowRecall = errors.New("shadow index recall below threshold")
+)
+
+// Status of a rebuild.
+type Status string
+
+const (
+	// StatusRunning rebuilds are embedding the corpus, or were interrupted.
+	StatusRunning Status = "running"
+	// StatusRejected rebuilds failed the recall verification.
+	StatusRejected Status = "rejected"
+	// StatusFailed rebuilds stopped on an error.
+	StatusFailed Status = "failed"
+	// StatusSwapped rebuilds hold the active index; the latest one wins.
+	StatusSwapped Status = "swapped"
+	// StatusCanceled rebuilds were replaced by a newer rebuild.
+	StatusCanceled Status = "canceled"
+)
+
+// Open reports whether a rebuild of the status can resume.
+func (s Status) Open() bool {
+	return s == StatusRunning || s == StatusRejected || s == StatusFailed
+}
+
+// Rebuild is the re-embedding of the corpus from the active model to a
+// target model.
+type Rebuild struct {
+	ID          int32   `json:"id"`
+	SourceModel string  `json:"source_model"`
+	TargetModel string  `json:"target_model"`
+	Status      Status  `json:"status"`
+	Total       int     `json:"total"`     // Memos to embed
+	Processed   int     `json:"processed"` // Memos with a target embedding
+	Recall      float64 `json:"recall"`    // Recall on the verification sample
+	Error       string  `json:"error,omitempty"`
+	CreatedTs   int64   `json:"created_ts"`
+	UpdatedTs   int64   `json:"updated_ts"`
+}
+
+// Store persists the rebuilds and reads the memo corpus.
+type Store interface {
+	// ActiveModel returns the target of the latest swapped rebuild, or ""
+	// when no rebuild was swapped.
+	ActiveModel(ctx context.Context) (string, error)
+	// FindOpenRebuild returns the latest open rebuild to model, or nil.
+	FindOpenRebuild(ctx context.Context, model string) (*Rebuild, error)
+	CreateRebuild(ctx context.Context, rebuild *Rebuild) (*Rebuild, error)
+	// UpdateRebuild saves the status, progress, recall and error of an
+	// open rebuild.
+	UpdateRebuild(ctx context.Context, rebuild *Rebuild) (*Rebuild, error)
+	// SwapRebuild makes the target of an open rebuild the active model if
+	// its source model still is, else returns ErrInvalid.
+	SwapRebuild(ctx context.Context, id int32, activeModel string) (*Rebuild, error)
+
+	// CountMemos counts the memos to embed.
+	CountMemos(ctx context.Context) (int, error)
+	// CountEmbeddings counts the memos to embed with an embedding of model.
+	CountEmbeddings(ctx context.Context, model string) (int, error)
+	// DeleteEmbeddings drops the embeddings of model.
+	DeleteEmbeddings(ctx context.Context, model string) error
+	// SampleMemos returns the IDs of up to n random memos to embed.
+	SampleMemos(ctx context.Context, n int) ([]int32, error)
+}
+
+// ModelSetter switches the active model of the memo searches.
+type ModelSetter interface {
+	SetEmbeddingModel(model string)
+}
+
+// Index resolves the active embedding model.
+type Index struct {
+	store        Store
+	target       ModelSetter
+	defaultModel string
+
+	mu     sync.RWMutex
+	active string
+}
+
+// New creates an index; defaultModel is active until a rebuild is swapped.
+// The active model is pushed to target, which may be nil.
+func New(store Store, target ModelSetter, defaultModel string) *Index {
+	return &Index{store: store, target: target, defaultModel: defaultModel, active: defaultModel}
+}
+
+// Active returns the active model as of the last load.
+func (i *Index) Active() string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.active
+}
+
+// Load reads the active model from the store.
+func (i *Index) Load(ctx context.Context) error {
+	model, err := i.store.ActiveModel(ctx)
+	if err != nil {
+		return err
+	}
+	if model == "" {
+		model = i.defaultModel
+	}
+
+	i.mu.Lock()
+	changed := model != i.active
+	i.active = model
+	i.mu.Unlock()
+	if changed {
+		slog.Info("embedding model swapped", "model", model)
+	}
+	if i.target != nil {
+		i.target.SetEmbeddingModel(model)
+	}
+	return nil
+}
+
+// Watch reloads the active model every interval until ctx is done, so
+// that servers follow the swaps of rebuilds run elsewhere.
+func (i *Index) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := i.Load(ctx); err != nil {
+				slog.Warn("failed to load the active embedding model", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package embedindex
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/ai"
+)
+
+// fakeStore only knows the active model.
+type fakeStore struct {
+	Store
+	active  string
+	loadErr error
+}
+
+func (s *fakeStore) ActiveModel(_ context.Context) (string, error) {
+	return s.active, s.loadErr
+}
+
+type fakeTarget struct {
+	model string
+}
+
+func (t *fakeTarget) SetEmbeddingModel(model string) {
+	t.model = model
+}
+
+// fakeEmbedding embeds every text as the length of its model name.
+type fakeEmbedding struct {
+	model string
+}
+
+func (e *fakeEmbedding) Embed(_ context.Context, _ string) ([]float32, error) {
+	return []float32{float32(len(e.model))}, nil
+}
+
+func (e *fakeEmbedding) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := [][]float32{}
+	for _, text := range texts {
+		vector, _ := e.Embed(ctx, text)
+		vectors = append(vectors, vector)
+	}
+	return vectors, nil
+}
+
+func (e *fakeEmbedding) Dimensions() int {
+	return 1
+}
+
+func TestIndex_Load(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{}
+	target := &fakeTarget{}
+	index := New(store, target, "BAAI/bge-m3")
+	assert.Equal(t, "BAAI/bge-m3", index.Active())
+
+	// The default model is active until a rebuild is swapped
+	require.NoError(t, index.Load(ctx))
+	assert.Equal(t, "BAAI/bge-m3", target.model)
+
+	store.active = "Qwen/Qwen3-Embedding-0.6B"
+	require.NoError(t, index.Load(ctx))
+	assert.Equal(t, "Qwen/Qwen3-Embedding-0.6B", index.Active())
+	assert.Equal(t, "Qwen/Qwen3-Embedding-0.6B", target.model)
+
+	// Failed loads keep the last active model
+	store.loadErr = errors.New("db down")
+	require.Error(t, index.Load(ctx))
+	assert.Equal(t, "Qwen/Qwen3-Embedding-0.6B", index.Active())
+}
+
+func TestService_FollowsActiveModel(t *testing.T) {
+	ctx := context.Background()
+	config := &ai.EmbeddingConfig{Provider: "siliconflow", Model: "BAAI/bge-m3", Dimensions: 1024}
+	model := config.Model
+	service := NewService(config, &fakeEmbedding{model: config.Model}, func() string { return model })
+	var created []string
+	service.newService = func(cfg *ai.EmbeddingConfig) (ai.EmbeddingService, error) {
+		if cfg.Model == "broken" {
+			return nil, errors.New("unknown model")
+		}
+		created = append(created, cfg.Provider+"/"+cfg.Model)
+		return &fakeEmbedding{model: cfg.Model}, nil
+	}
+
+	vector, err := service.Embed(ctx, "query")
+	require.NoError(t, err)
+	assert.Equal(t, []float32{11}, vector)
+
+	// Swaps switch the model of the next embeddings
+	model = "Qwen/Qwen3-Embedding-0.6B"
+	vectors, err := service.EmbedBatch(ctx, []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{25}, {25}}, vectors)
+	_, err = service.Embed(ctx, "query")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"siliconflow/Qwen/Qwen3-Embedding-0.6B"}, created, "services are created once per model")
+
+	model = "broken"
+	_, err = service.Embed(ctx, "query")
+	require.Error(t, err)
+	assert.Equal(t, 1024, service.Dimensions())
+}
+
+// countingEmbedding counts the texts sent to the provider.
+type countingEmbedding struct {
+	fakeEmbedding
+	calls int
+}
+
+func (e *countingEmbedding) Embed(ctx context.Context, text string) ([]float32, error) {
+	e.calls++
+	return e.fakeEmbedding.Embed(ctx, text)
+}
+
+func TestService_QueryCache(t *testing.T) {
+	ctx := context.Background()
+	config := &ai.EmbeddingConfig{Model: "BAAI/bge-m3"}
+	model := config.Model
+	base := &countingEmbedding{fakeEmbedding: fakeEmbedding{model: config.Model}}
+	service := NewService(config, base, func() string { return model })
+	assert.Nil(t, service.CacheStats(), "the cache is disabled by default")
+
+	service.EnableQueryCache(10, time.Minute)
+	for i := 0; i < 3; i++ {
+		vector, err := service.Embed(ctx, "query")
+		require.NoError(t, err)
+		assert.Equal(t, []float32{11}, vector)
+	}
+	assert.Equal(t, 1, base.calls)
+
+	// Cached vectors are copies
+	vector, err := service.Embed(ctx, "query")
+	require.NoError(t, err)
+	vector[0] = 0
+	vector, err = service.Embed(ctx, "query")
+	require.NoError(t, err)
+	assert.Equal(t, []float32{11}, vector)
+
+	// Embeddings of another model are not reused across swaps
+	other := &countingEmbedding{fakeEmbedding: fakeEmbedding{model: "Qwen/Qwen3-Embedding-0.6B"}}
+	service.services["Qwen/Qwen3-Embedding-0.6B"] = other
+	model = "Qwen/Qwen3-Embedding-0.6B"
+	_, err = service.Embed(ctx, "query")
+	require.NoError(t, err)
+	assert.Equal(t, 1, other.calls)
+
+	stats := service.CacheStats()
+	assert.Equal(t, int64(4), stats.Hits)
+	assert.Equal(t, int64(2), stats.Misses)
+	assert.InDelta(t, 4.0/6, stats.HitRate, 0.0001)
+	assert.Equal(t, 2, stats.Size)
+	assert.Equal(t, 10, stats.Capacity)
+}
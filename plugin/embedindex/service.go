@@ -0,0 +1,114 @@
+package embedindex
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hrygo/divinesense/ai"
+)
+
+// Service embeds with the active model, so that queries keep matching the
+// active index across swaps. The models share the provider configuration.
+type Service struct {
+	config *ai.EmbeddingConfig
+	model  func() string
+
+	mu       sync.Mutex
+	services map[string]ai.EmbeddingService
+
+	newService func(*ai.EmbeddingConfig) (ai.EmbeddingService, error)
+
+	// cache holds the embeddings of Embed; nil until EnableQueryCache
+	cache *queryCache
+}
+
+// NewService wraps service, the embedding service of config, to embed with
+// the model returned by model.
+func NewService(config *ai.EmbeddingConfig, service ai.EmbeddingService, model func() string) *Service {
+	return &Service{
+		config:     config,
+		model:      model,
+		services:   map[string]ai.EmbeddingService{config.Model: service},
+		newService: ai.NewEmbeddingService,
+	}
+}
+
+// For returns the embedding service of model.
+func (s *Service) For(model string) (ai.EmbeddingService, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if service, ok := s.services[model]; ok {
+		return service, nil
+	}
+	config := *s.config
+	config.Model = model
+	service, err := s.newService(&config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the embedding service of %s: %w", model, err)
+	}
+	s.services[model] = service
+	return service, nil
+}
+
+// EnableQueryCache caches the embeddings of Embed, up to size texts for ttl.
+// Batches are not cached, as they embed memos rather than queries.
+func (s *Service) EnableQueryCache(size int, ttl time.Duration) {
+	if size <= 0 {
+		return
+	}
+	s.cache = newQueryCache(size, ttl)
+}
+
+// CacheStats returns the statistics of the query cache, nil if disabled.
+func (s *Service) CacheStats() *CacheStats {
+	if s.cache == nil {
+		return nil
+	}
+	return s.cache.stats()
+}
+
+func (s *Service) active() (ai.EmbeddingService, error) {
+	return s.For(s.model())
+}
+
+// Embed implements ai.EmbeddingService.
+func (s *Service) Embed(ctx context.Context, text string) ([]float32, error) {
+	model := s.model()
+	if s.cache != nil {
+		if vector, ok := s.cache.get(model, text); ok {
+			return vector, nil
+		}
+	}
+	service, err := s.For(model)
+	if err != nil {
+		return nil, err
+	}
+	vector, err := service.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	if s.cache != nil {
+		s.cache.set(model, text, vector)
+	}
+	return vector, nil
+}
+
+// EmbedBatch implements ai.EmbeddingService.
+func (s *Service) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	service, err := s.active()
+	if err != nil {
+		return nil, err
+	}
+	return service.EmbedBatch(ctx, texts)
+}
+
+// Dimensions implements ai.EmbeddingService.
+func (s *Service) Dimensions() int {
+	service, err := s.active()
+	if err != nil {
+		return s.config.Dimensions
+	}
+	return service.Dimensions()
+}
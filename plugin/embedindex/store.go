@@ -0,0 +1,207 @@
+package embedindex
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DBStore persists rebuilds in the embedding_rebuild table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new rebuild store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const rebuildColumns = "id, source_model, target_model, status, total, processed, recall, error, created_ts, updated_ts"
+
+// activeModelQuery selects the target of the latest swapped rebuild.
+const activeModelQuery = `
+	SELECT target_model FROM embedding_rebuild
+	WHERE status = 'swapped'
+	ORDER BY updated_ts DESC, id DESC
+	LIMIT 1`
+
+// corpusCondition selects the memos to embed, like the embedding runner.
+const corpusCondition = "m.row_status = 'NORMAL' AND LENGTH(m.content) > 0"
+
+// ActiveModel implements Store.
+func (s *DBStore) ActiveModel(ctx context.Context) (string, error) {
+	var model string
+	err := s.db.QueryRowContext(ctx, activeModelQuery).Scan(&model)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get active embedding model: %w", err)
+	}
+	return model, nil
+}
+
+// FindOpenRebuild implements Store.
+func (s *DBStore) FindOpenRebuild(ctx context.Context, model string) (*Rebuild, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT `+rebuildColumns+` FROM embedding_rebuild
+		WHERE target_model = $1 AND status IN ('running', 'rejected', 'failed')
+		ORDER BY id DESC
+		LIMIT 1`, model)
+	rebuild, err := scanRebuild(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find embedding rebuild: %w", err)
+	}
+	return rebuild, nil
+}
+
+// CreateRebuild implements Store.
+func (s *DBStore) CreateRebuild(ctx context.Context, rebuild *Rebuild) (*Rebuild, error) {
+	now := time.Now().Unix()
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO embedding_rebuild (source_model, target_model, status, total, processed, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		RETURNING `+rebuildColumns,
+		rebuild.SourceModel, rebuild.TargetModel, rebuild.Status, rebuild.Total, rebuild.Processed, now)
+	created, err := scanRebuild(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedding rebuild: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateRebuild implements Store.
+func (s *DBStore) UpdateRebuild(ctx context.Context, rebuild *Rebuild) (*Rebuild, error) {
+	row := s.db.QueryRowContext(ctx, `
+		UPDATE embedding_rebuild
+		SET status = $2, total = $3, processed = $4, recall = $5, error = $6, updated_ts = $7
+		WHERE id = $1 AND status IN ('running', 'rejected', 'failed')
+		RETURNING `+rebuildColumns,
+		rebuild.ID, rebuild.Status, rebuild.Total, rebuild.Processed, rebuild.Recall, rebuild.Error, time.Now().Unix())
+	updated, err := scanRebuild(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update embedding rebuild: %w", err)
+	}
+	return updated, nil
+}
+
+// SwapRebuild implements Store.
+func (s *DBStore) SwapRebuild(ctx context.Context, id int32, activeModel string) (*Rebuild, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin swap: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Serialize the swaps so that each one sees the model it replaces
+	if _, err := tx.ExecContext(ctx, "LOCK TABLE embedding_rebuild IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return nil, fmt.Errorf("failed to lock embedding rebuilds: %w", err)
+	}
+	row := tx.QueryRowContext(ctx, `
+		UPDATE embedding_rebuild
+		SET status = 'swapped', updated_ts = $2
+		WHERE id = $1 AND status IN ('running', 'rejected', 'failed')
+			AND source_model = COALESCE((`+activeModelQuery+`), $3)
+		RETURNING `+rebuildColumns,
+		id, time.Now().Unix(), activeModel)
+	swapped, err := scanRebuild(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: the rebuild is not open or the active model changed", ErrInvalid)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to swap embedding rebuild: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE embedding_rebuild SET status = 'canceled', updated_ts = $2
+		WHERE id <> $1 AND status IN ('running', 'rejected', 'failed')`,
+		id, swapped.UpdatedTs); err != nil {
+		return nil, fmt.Errorf("failed to cancel embedding rebuilds: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit swap: %w", err)
+	}
+	return swapped, nil
+}
+
+// CountMemos implements Store.
+func (s *DBStore) CountMemos(ctx context.Context) (int, error) {
+	var n int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM memo m WHERE "+corpusCondition).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count memos: %w", err)
+	}
+	return n, nil
+}
+
+// CountEmbeddings implements Store.
+func (s *DBStore) CountEmbeddings(ctx context.Context, model string) (int, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM memo m
+		JOIN memo_embedding e ON e.memo_id = m.id AND e.model = $1
+		WHERE `+corpusCondition, model).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count memo embeddings: %w", err)
+	}
+	return n, nil
+}
+
+// DeleteEmbeddings implements Store.
+func (s *DBStore) DeleteEmbeddings(ctx context.Context, model string) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM memo_embedding WHERE model = $1", model); err != nil {
+		return fmt.Errorf("failed to delete memo embeddings: %w", err)
+	}
+	return nil
+}
+
+// SampleMemos implements Store.
+func (s *DBStore) SampleMemos(ctx context.Context, n int) ([]int32, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT m.id FROM memo m WHERE "+corpusCondition+" ORDER BY random() LIMIT $1", n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample memos: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int32{}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan memo: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to sample memos: %w", err)
+	}
+	return ids, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRebuild(row rowScanner) (*Rebuild, error) {
+	rebuild := &Rebuild{}
+	if err := row.Scan(
+		&rebuild.ID,
+		&rebuild.SourceModel,
+		&rebuild.TargetModel,
+		&rebuild.Status,
+		&rebuild.Total,
+		&rebuild.Processed,
+		&rebuild.Recall,
+		&rebuild.Error,
+		&rebuild.CreatedTs,
+		&rebuild.UpdatedTs,
+	); err != nil {
+		return nil, err
+	}
+	return rebuild, nil
+}
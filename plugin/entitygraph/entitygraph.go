@@ -0,0 +1,428 @@
+// Package entitygraph maintains a personal knowledge graph of the people,
+// projects and terms a user writes about.
+//
+// Entities are extracted from memos and conversation messages by an
+// Extractor (usually a cheap LLM), merged by normalized name, and linked to
+// the places that mention them. Agents query the graph through the
+// entity_lookup tool to answer "what do I know about Project X?".
+package entitygraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	agents "github.com/hrygo/divinesense/ai/agents"
+)
+
+// Kind is the category of an entity.
+type Kind string
+
+const (
+	KindPerson  Kind = "PERSON"
+	KindProject Kind = "PROJECT"
+	KindTerm    Kind = "TERM"
+)
+
+// Valid reports whether k is a known kind.
+func (k Kind) Valid() bool {
+	switch k {
+	case KindPerson, KindProject, KindTerm:
+		return true
+	}
+	return false
+}
+
+// SourceType is the kind of content an entity is mentioned in.
+type SourceType string
+
+const (
+	SourceMemo         SourceType = "MEMO"
+	SourceConversation SourceType = "CONVERSATION"
+)
+
+const (
+	// maxEntitiesPerSource bounds the entities kept from one source.
+	maxEntitiesPerSource = 20
+	// maxNameRunes bounds the length of an entity name.
+	maxNameRunes = 64
+	// snippetRunes is the length of the text kept around a mention.
+	snippetRunes = 120
+	// lookupMentions bounds the mentions returned by a lookup.
+	lookupMentions = 10
+	// indexTimeout bounds the asynchronous indexing of one source.
+	indexTimeout = time.Minute
+	// maxConcurrentIndexing bounds the sources indexed at the same time.
+	maxConcurrentIndexing = 4
+)
+
+// ToolName is the name of the agent tool querying the graph.
+const ToolName = "entity_lookup"
+
+// Entity is a person, project or term known to a user.
+type Entity struct {
+	ID          int32  `json:"id"`
+	CreatorID   int32  `json:"creator_id"`
+	Name        string `json:"name"`
+	Kind        Kind   `json:"kind"`
+	Description string `json:"description,omitempty"`
+	// MentionCount is the number of mentions; filled by listings.
+	MentionCount int `json:"mention_count"`
+	// LastMentionTs is the time of the latest mention, e.g. the last
+	// interaction with a person; filled by listings.
+	LastMentionTs int64 `json:"last_mention_ts"`
+	CreatedTs     int64 `json:"created_ts"`
+	UpdatedTs     int64 `json:"updated_ts"`
+}
+
+// Source identifies the content an entity is mentioned in.
+type Source struct {
+	Type SourceType `json:"source_type"`
+	ID   int32      `json:"source_id"`
+	// Ref is the memo UID of memo sources, used for links.
+	Ref string `json:"source_ref,omitempty"`
+}
+
+// Mention is a place where an entity is mentioned.
+type Mention struct {
+	EntityID int32 `json:"entity_id"`
+	Source
+	Snippet   string `json:"snippet"`
+	CreatedTs int64  `json:"created_ts"`
+}
+
+// Relation links two entities of the same user, e.g. a person working on a project.
+type Relation struct {
+	FromEntityID int32  `json:"from_entity_id"`
+	ToEntityID   int32  `json:"to_entity_id"`
+	Relation     string `json:"relation"`
+	Source
+}
+
+// RelatedEntity is an entity related to another one, aggregated over sources.
+type RelatedEntity struct {
+	Entity   *Entity `json:"entity"`
+	Relation string  `json:"relation"`
+	// Outgoing is true when the relation reads "entity <relation> related".
+	Outgoing bool `json:"outgoing"`
+	// Count is the number of sources stating the relation.
+	Count int `json:"count"`
+}
+
+// EntityDetail is an entity with its mentions and relations.
+type EntityDetail struct {
+	Entity    *Entity          `json:"entity"`
+	Mentions  []*Mention       `json:"mentions"`
+	Relations []*RelatedEntity `json:"relations"`
+}
+
+// FindEntity specifies conditions for listing entities.
+type FindEntity struct {
+	ID        *int32
+	CreatorID *int32
+	Kind      *Kind
+	// NormalizedName matches the normalized name exactly.
+	NormalizedName *string
+	// Query matches names containing it, case-insensitively.
+	Query string
+	// RecentFirst orders by latest mention instead of mention count.
+	RecentFirst bool
+	Limit       int
+}
+
+// Store persists the graph; implemented by DBStore.
+type Store interface {
+	// UpsertEntity creates the entity or refreshes the one with the same
+	// creator, kind and normalized name, and returns it.
+	UpsertEntity(ctx context.Context, entity *Entity) (*Entity, error)
+	// ReplaceSource replaces the mentions and relations recorded for source
+	// with the given ones, deleting the entities it no longer mentions that
+	// are mentioned nowhere else. With appendOnly, existing ones are kept.
+	ReplaceSource(ctx context.Context, creatorID int32, source Source, mentions []*Mention, relations []*Relation, appendOnly bool) error
+	// DeleteSource removes the mentions and relations of source, and the
+	// entities it mentioned that are mentioned nowhere else.
+	DeleteSource(ctx context.Context, creatorID int32, source Source) error
+	ListEntities(ctx context.Context, find *FindEntity) ([]*Entity, error)
+	ListMentions(ctx context.Context, entityID int32, limit int) ([]*Mention, error)
+	ListRelated(ctx context.Context, entityID int32) ([]*RelatedEntity, error)
+	DeleteEntity(ctx context.Context, id, creatorID int32) (bool, error)
+}
+
+// Extracted is what an Extractor found in a text.
+type Extracted struct {
+	Entities  []ExtractedEntity   `json:"entities"`
+	Relations []ExtractedRelation `json:"relations"`
+}
+
+// ExtractedEntity is an entity found in a text.
+type ExtractedEntity struct {
+	Name        string `json:"name"`
+	Kind        Kind   `json:"kind"`
+	Description string `json:"description"`
+}
+
+// ExtractedRelation is a relation found in a text, between entity names.
+type ExtractedRelation struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Relation string `json:"relation"`
+}
+
+// Extractor finds entities and relations in a text.
+type Extractor interface {
+	Extract(ctx context.Context, text string) (*Extracted, error)
+}
+
+// Graph indexes content into the knowledge graph and answers lookups.
+// A nil *Graph is valid and does nothing.
+type Graph struct {
+	store     Store
+	extractor Extractor
+	sem       chan struct{}
+}
+
+// NewGraph creates a graph.
+func NewGraph(store Store, extractor Extractor) *Graph {
+	return &Graph{store: store, extractor: extractor, sem: make(chan struct{}, maxConcurrentIndexing)}
+}
+
+// NormalizeName returns the key entity names are merged by.
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// Index extracts the entities of text and records them as mentioned in
+// source. Memo sources are replaced on each call; conversation sources
+// accumulate the mentions of each message.
+func (g *Graph) Index(ctx context.Context, creatorID int32, source Source, text string) error {
+	if g == nil {
+		return nil
+	}
+	if strings.TrimSpace(text) == "" {
+		if source.Type == SourceMemo {
+			return g.store.DeleteSource(ctx, creatorID, source)
+		}
+		return nil
+	}
+	extracted, err := g.extractor.Extract(ctx, text)
+	if err != nil {
+		return fmt.Errorf("extract entities: %w", err)
+	}
+
+	now := time.Now().Unix()
+	ids := make(map[string]int32)
+	var mentions []*Mention
+	for _, e := range cleanEntities(extracted.Entities) {
+		entity, err := g.store.UpsertEntity(ctx, &Entity{
+			CreatorID:   creatorID,
+			Name:        e.Name,
+			Kind:        e.Kind,
+			Description: e.Description,
+		})
+		if err != nil {
+			return err
+		}
+		ids[NormalizeName(e.Name)] = entity.ID
+		mentions = append(mentions, &Mention{
+			EntityID:  entity.ID,
+			Source:    source,
+			Snippet:   snippetAround(text, e.Name, snippetRunes),
+			CreatedTs: now,
+		})
+	}
+
+	var relations []*Relation
+	for _, r := range extracted.Relations {
+		from, to := ids[NormalizeName(r.From)], ids[NormalizeName(r.To)]
+		relation := strings.TrimSpace(r.Relation)
+		if from == 0 || to == 0 || from == to || relation == "" {
+			continue
+		}
+		relations = append(relations, &Relation{FromEntityID: from, ToEntityID: to, Relation: relation, Source: source})
+	}
+
+	return g.store.ReplaceSource(ctx, creatorID, source, mentions, relations, source.Type != SourceMemo)
+}
+
+// IndexAsync runs Index in the background, detached from ctx's cancellation.
+// Sources are dropped when too many are already being indexed.
+func (g *Graph) IndexAsync(ctx context.Context, creatorID int32, source Source, text string) {
+	if g == nil {
+		return
+	}
+	select {
+	case g.sem <- struct{}{}:
+	default:
+		slog.Warn("entity graph: indexing busy, skipping source", "source_type", source.Type, "source_id", source.ID)
+		return
+	}
+	go func() {
+		defer func() { <-g.sem }()
+		indexCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), indexTimeout)
+		defer cancel()
+		if err := g.Index(indexCtx, creatorID, source, text); err != nil {
+			slog.Warn("entity graph: failed to index source",
+				"source_type", source.Type,
+				"source_id", source.ID,
+				"error", err,
+			)
+		}
+	}()
+}
+
+// Forget removes everything recorded from source.
+func (g *Graph) Forget(ctx context.Context, creatorID int32, source Source) error {
+	if g == nil {
+		return nil
+	}
+	return g.store.DeleteSource(ctx, creatorID, source)
+}
+
+// List lists the entities of a user, most mentioned first.
+func (g *Graph) List(ctx context.Context, find *FindEntity) ([]*Entity, error) {
+	return g.store.ListEntities(ctx, find)
+}
+
+// Delete deletes an entity of a user with its mentions and relations.
+func (g *Graph) Delete(ctx context.Context, id, creatorID int32) (bool, error) {
+	return g.store.DeleteEntity(ctx, id, creatorID)
+}
+
+// Get returns an entity of a user with its latest mentions and its relations,
+// or nil if it does not exist.
+func (g *Graph) Get(ctx context.Context, id, creatorID int32) (*EntityDetail, error) {
+	entities, err := g.store.ListEntities(ctx, &FindEntity{ID: &id, CreatorID: &creatorID})
+	if err != nil || len(entities) == 0 {
+		return nil, err
+	}
+	return g.detail(ctx, entities[0])
+}
+
+// Lookup finds the entity of a user best matching name: an exact match of the
+// normalized name, else the most mentioned entity whose name contains it.
+// It returns nil if none matches.
+func (g *Graph) Lookup(ctx context.Context, creatorID int32, name string) (*EntityDetail, error) {
+	normalized := NormalizeName(name)
+	if normalized == "" {
+		return nil, nil
+	}
+	entities, err := g.store.ListEntities(ctx, &FindEntity{CreatorID: &creatorID, NormalizedName: &normalized, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(entities) == 0 {
+		entities, err = g.store.ListEntities(ctx, &FindEntity{CreatorID: &creatorID, Query: normalized, Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(entities) == 0 {
+		return nil, nil
+	}
+	return g.detail(ctx, entities[0])
+}
+
+func (g *Graph) detail(ctx context.Context, entity *Entity) (*EntityDetail, error) {
+	mentions, err := g.store.ListMentions(ctx, entity.ID, lookupMentions)
+	if err != nil {
+		return nil, err
+	}
+	related, err := g.store.ListRelated(ctx, entity.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &EntityDetail{Entity: entity, Mentions: mentions, Relations: related}, nil
+}
+
+// ToolFor returns the entity_lookup tool for a user's agents, or nil when the
+// graph is unavailable.
+func (g *Graph) ToolFor(userID int32) agents.ToolWithSchema {
+	if g == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		ToolName,
+		"Look up what the user's memos and conversations say about a person, project or term: "+
+			"its description, the latest mentions (with memo UIDs to cite) and related entities. "+
+			`Use it for questions like "what do I know about Project X?". Input: {"name": "..."}.`,
+		func(ctx context.Context, input string) (string, error) {
+			var args struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal([]byte(input), &args); err != nil || strings.TrimSpace(args.Name) == "" {
+				return "", fmt.Errorf("name is required")
+			}
+			detail, err := g.Lookup(ctx, userID, args.Name)
+			if err != nil {
+				return "", err
+			}
+			if detail == nil {
+				return fmt.Sprintf("No entity named %q in the knowledge graph. Try memo_search instead.", args.Name), nil
+			}
+			data, err := json.Marshal(detail)
+			if err != nil {
+				return "", err
+			}
+			return string(data), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name": map[string]any{
+					"type":        "string",
+					"description": "Name of the person, project or term",
+				},
+			},
+			"required": []string{"name"},
+		},
+	)
+}
+
+// cleanEntities drops invalid and duplicate entities and bounds their number.
+func cleanEntities(entities []ExtractedEntity) []ExtractedEntity {
+	seen := make(map[string]bool)
+	var cleaned []ExtractedEntity
+	for _, e := range entities {
+		e.Name = strings.Join(strings.Fields(e.Name), " ")
+		e.Kind = Kind(strings.ToUpper(string(e.Kind)))
+		e.Description = strings.TrimSpace(e.Description)
+		key := NormalizeName(e.Name)
+		if key == "" || len([]rune(e.Name)) > maxNameRunes || !e.Kind.Valid() || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, e)
+		if len(cleaned) == maxEntitiesPerSource {
+			break
+		}
+	}
+	return cleaned
+}
+
+// snippetAround returns about size runes of text centered on the first
+// occurrence of name, or the start of text if name does not occur.
+func snippetAround(text, name string, size int) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	if len(runes) <= size {
+		return string(runes)
+	}
+	start := 0
+	// ToLower maps rune by rune, so rune offsets match those of text.
+	lower := strings.ToLower(string(runes))
+	if idx := strings.Index(lower, strings.ToLower(name)); idx >= 0 {
+		center := utf8.RuneCountInString(lower[:idx])
+		start = max(0, min(center-size/2, len(runes)-size))
+	}
+	snippet := string(runes[start : start+size])
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if start+size < len(runes) {
+		snippet += "…"
+	}
+	return snippet
+}
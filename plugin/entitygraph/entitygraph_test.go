@@ -0,0 +1,218 @@
+package entitygraph
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is an in-memory Store.
+type memoryStore struct {
+	entities  []*Entity
+	mentions  []*Mention
+	relations []*Relation
+}
+
+func (s *memoryStore) UpsertEntity(_ context.Context, entity *Entity) (*Entity, error) {
+	for _, e := range s.entities {
+		if e.CreatorID == entity.CreatorID && e.Kind == entity.Kind && NormalizeName(e.Name) == NormalizeName(entity.Name) {
+			if entity.Description != "" {
+				e.Description = entity.Description
+			}
+			return e, nil
+		}
+	}
+	created := *entity
+	created.ID = int32(len(s.entities) + 1)
+	s.entities = append(s.entities, &created)
+	return &created, nil
+}
+
+func (s *memoryStore) ReplaceSource(ctx context.Context, creatorID int32, source Source, mentions []*Mention, relations []*Relation, appendOnly bool) error {
+	if !appendOnly {
+		_ = s.DeleteSource(ctx, creatorID, source)
+	}
+	s.mentions = append(s.mentions, mentions...)
+	s.relations = append(s.relations, relations...)
+	return nil
+}
+
+func (s *memoryStore) DeleteSource(_ context.Context, _ int32, source Source) error {
+	var kept []*Mention
+	for _, m := range s.mentions {
+		if m.Type != source.Type || m.ID != source.ID {
+			kept = append(kept, m)
+		}
+	}
+	s.mentions = kept
+	var keptRelations []*Relation
+	for _, r := range s.relations {
+		if r.Type != source.Type || r.ID != source.ID {
+			keptRelations = append(keptRelations, r)
+		}
+	}
+	s.relations = keptRelations
+	return nil
+}
+
+func (s *memoryStore) ListEntities(_ context.Context, find *FindEntity) ([]*Entity, error) {
+	var entities []*Entity
+	for _, e := range s.entities {
+		name := NormalizeName(e.Name)
+		if (find.ID != nil && e.ID != *find.ID) ||
+			(find.CreatorID != nil && e.CreatorID != *find.CreatorID) ||
+			(find.NormalizedName != nil && name != *find.NormalizedName) ||
+			(find.Query != "" && !strings.Contains(name, NormalizeName(find.Query))) {
+			continue
+		}
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+func (s *memoryStore) ListMentions(_ context.Context, entityID int32, _ int) ([]*Mention, error) {
+	var mentions []*Mention
+	for _, m := range s.mentions {
+		if m.EntityID == entityID {
+			mentions = append(mentions, m)
+		}
+	}
+	return mentions, nil
+}
+
+func (s *memoryStore) ListRelated(_ context.Context, entityID int32) ([]*RelatedEntity, error) {
+	var related []*RelatedEntity
+	for _, r := range s.relations {
+		if r.FromEntityID == entityID {
+			related = append(related, &RelatedEntity{Entity: s.entities[r.ToEntityID-1], Relation: r.Relation, Outgoing: true, Count: 1})
+		} else if r.ToEntityID == entityID {
+			related = append(related, &RelatedEntity{Entity: s.entities[r.FromEntityID-1], Relation: r.Relation, Count: 1})
+		}
+	}
+	return related, nil
+}
+
+func (s *memoryStore) DeleteEntity(context.Context, int32, int32) (bool, error) {
+	return false, nil
+}
+
+type fixedExtractor struct {
+	extracted *Extracted
+}
+
+func (f *fixedExtractor) Extract(context.Context, string) (*Extracted, error) {
+	return f.extracted, nil
+}
+
+func TestGraphIndexAndLookup(t *testing.T) {
+	ctx := context.Background()
+	st := &memoryStore{}
+	extractor := &fixedExtractor{extracted: &Extracted{
+		Entities: []ExtractedEntity{
+			{Name: "老王", Kind: "person", Description: "后端负责人"},
+			{Name: "Project  Atlas", Kind: KindProject, Description: "数据平台迁移"},
+			{Name: "project atlas", Kind: KindProject},
+			{Name: "周五", Kind: "DATE"},
+		},
+		Relations: []ExtractedRelation{
+			{From: "老王", To: "Project Atlas", Relation: "负责"},
+			{From: "老王", To: "周五", Relation: "截止"},
+		},
+	}}
+	graph := NewGraph(st, extractor)
+	memo := Source{Type: SourceMemo, ID: 1, Ref: "m_001"}
+
+	require.NoError(t, graph.Index(ctx, 1, memo, "老王负责 Project Atlas 的迁移，周五上线。"))
+	require.Len(t, st.entities, 2)
+	require.Len(t, st.mentions, 2)
+	require.Len(t, st.relations, 1)
+
+	// Re-indexing a memo replaces its mentions.
+	require.NoError(t, graph.Index(ctx, 1, memo, "老王负责 Project Atlas 的迁移，周五上线。"))
+	require.Len(t, st.mentions, 2)
+
+	// Conversation messages accumulate.
+	extractor.extracted = &Extracted{Entities: []ExtractedEntity{{Name: "Project Atlas", Kind: KindProject}}}
+	conversation := Source{Type: SourceConversation, ID: 9}
+	require.NoError(t, graph.Index(ctx, 1, conversation, "Project Atlas 进展如何？"))
+	require.NoError(t, graph.Index(ctx, 1, conversation, "Project Atlas 延期了"))
+	require.Len(t, st.mentions, 4)
+
+	detail, err := graph.Lookup(ctx, 1, "project atlas")
+	require.NoError(t, err)
+	require.NotNil(t, detail)
+	assert.Equal(t, "数据平台迁移", detail.Entity.Description)
+	assert.Len(t, detail.Mentions, 3)
+	require.Len(t, detail.Relations, 1)
+	assert.Equal(t, "老王", detail.Relations[0].Entity.Name)
+	assert.False(t, detail.Relations[0].Outgoing)
+
+	// Partial names fall back to a substring match.
+	detail, err = graph.Lookup(ctx, 1, "Atlas")
+	require.NoError(t, err)
+	require.NotNil(t, detail)
+	assert.Equal(t, KindProject, detail.Entity.Kind)
+
+	detail, err = graph.Lookup(ctx, 2, "Atlas")
+	require.NoError(t, err)
+	assert.Nil(t, detail)
+
+	require.NoError(t, graph.Forget(ctx, 1, memo))
+	require.Len(t, st.mentions, 2)
+	require.Empty(t, st.relations)
+}
+
+func TestEntityLookupTool(t *testing.T) {
+	ctx := context.Background()
+	st := &memoryStore{}
+	graph := NewGraph(st, &fixedExtractor{extracted: &Extracted{
+		Entities: []ExtractedEntity{{Name: "RAG", Kind: KindTerm}},
+	}})
+	require.NoError(t, graph.Index(ctx, 1, Source{Type: SourceMemo, ID: 3, Ref: "m_003"}, "RAG 的召回率"))
+
+	tool := graph.ToolFor(1)
+	require.Equal(t, ToolName, tool.Name())
+	out, err := tool.Run(ctx, `{"name": "rag"}`)
+	require.NoError(t, err)
+	var detail EntityDetail
+	require.NoError(t, json.Unmarshal([]byte(out), &detail))
+	assert.Equal(t, "RAG", detail.Entity.Name)
+	assert.Equal(t, "m_003", detail.Mentions[0].Ref)
+
+	out, err = tool.Run(ctx, `{"name": "Kafka"}`)
+	require.NoError(t, err)
+	assert.Contains(t, out, "No entity")
+
+	_, err = tool.Run(ctx, `{}`)
+	require.Error(t, err)
+
+	var nilGraph *Graph
+	assert.Nil(t, nilGraph.ToolFor(1))
+	assert.NoError(t, nilGraph.Index(ctx, 1, Source{}, "text"))
+}
+
+func TestSnippetAround(t *testing.T) {
+	assert.Equal(t, "short text", snippetAround("short  text", "text", 20))
+
+	text := strings.Repeat("a", 50) + "Needle" + strings.Repeat("b", 50)
+	snippet := snippetAround(text, "needle", 20)
+	assert.Contains(t, snippet, "Needle")
+	assert.True(t, strings.HasPrefix(snippet, "…"))
+	assert.True(t, strings.HasSuffix(snippet, "…"))
+
+	assert.Equal(t, strings.Repeat("中", 10)+"…", snippetAround(strings.Repeat("中", 30), "缺失", 10))
+}
+
+func TestParseExtracted(t *testing.T) {
+	extracted, err := parseExtracted("```json\n{\"entities\": [{\"name\": \"老王\", \"kind\": \"PERSON\"}], \"relations\": []}\n```")
+	require.NoError(t, err)
+	require.Len(t, extracted.Entities, 1)
+	assert.Equal(t, KindPerson, extracted.Entities[0].Kind)
+
+	_, err = parseExtracted("no entities here")
+	require.Error(t, err)
+}
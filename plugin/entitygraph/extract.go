@@ -0,0 +1,68 @@
+package entitygraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hrygo/divinesense/ai/core/llm"
+)
+
+// maxExtractRunes bounds the text sent to the model.
+const maxExtractRunes = 4000
+
+// LLMExtractor extracts entities with a language model. A cheap model is enough.
+type LLMExtractor struct {
+	llm llm.Service
+}
+
+// NewLLMExtractor creates an extractor backed by llmSvc.
+func NewLLMExtractor(llmSvc llm.Service) *LLMExtractor {
+	return &LLMExtractor{llm: llmSvc}
+}
+
+// Extract implements Extractor.
+func (e *LLMExtractor) Extract(ctx context.Context, text string) (*Extracted, error) {
+	if runes := []rune(text); len(runes) > maxExtractRunes {
+		text = string(runes[:maxExtractRunes])
+	}
+	content, _, err := e.llm.Chat(ctx, []llm.Message{
+		llm.SystemPrompt(extractSystemPrompt),
+		llm.UserMessage(text),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseExtracted(content)
+}
+
+// parseExtracted decodes the JSON answer of the model, tolerating a markdown
+// code fence around it.
+func parseExtracted(content string) (*Extracted, error) {
+	content = strings.TrimSpace(content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	content = strings.TrimSpace(content)
+
+	extracted := &Extracted{}
+	if err := json.Unmarshal([]byte(content), extracted); err != nil {
+		return nil, fmt.Errorf("parse extraction: %w", err)
+	}
+	return extracted, nil
+}
+
+const extractSystemPrompt = `你是一个知识图谱构建助手。从用户的笔记或消息中抽取实体及其关系。
+
+实体类型（kind）：
+- PERSON：具体的人（同事、朋友、作者等）
+- PROJECT：项目、产品、计划或组织内的工作事项
+- TERM：专业术语、技术、概念
+
+要求：
+1. 只抽取文本中明确出现、对用户有长期价值的实体；忽略泛泛的词语、日期和代词
+2. name 使用文本中的原始写法；description 用一句话概括文本中关于该实体的信息
+3. relations 只描述文本中明确陈述的关系，from/to 必须是抽取到的实体 name，relation 用简短的动词短语（如"负责"、"使用"、"属于"）
+4. 没有实体时返回空数组
+5. 只返回JSON：{"entities": [{"name": "", "kind": "", "description": ""}], "relations": [{"from": "", "to": "", "relation": ""}]}`
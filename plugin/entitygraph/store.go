@@ -0,0 +1,293 @@
+package entitygraph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// DBStore persists the graph in the kg_entity, kg_mention and kg_relation
+// tables (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new graph store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const entityColumns = "e.id, e.creator_id, e.name, e.kind, e.description, e.created_ts, e.updated_ts"
+
+// mentionCount counts the mentions of the entity aliased e.
+const mentionCount = "(SELECT COUNT(*) FROM kg_mention m WHERE m.entity_id = e.id)"
+
+// lastMention is the time of the latest mention of the entity aliased e.
+const lastMention = "(SELECT COALESCE(MAX(m.created_ts), 0) FROM kg_mention m WHERE m.entity_id = e.id)"
+
+// UpsertEntity implements Store. An existing entity keeps its description
+// unless a new one is given.
+func (s *DBStore) UpsertEntity(ctx context.Context, entity *Entity) (*Entity, error) {
+	now := time.Now().Unix()
+	query := `
+		INSERT INTO kg_entity AS e (creator_id, name, normalized_name, kind, description, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (creator_id, kind, normalized_name) DO UPDATE SET
+			name = EXCLUDED.name,
+			description = CASE WHEN EXCLUDED.description = '' THEN e.description ELSE EXCLUDED.description END,
+			updated_ts = EXCLUDED.updated_ts
+		RETURNING ` + entityColumns + ", " + mentionCount + ", " + lastMention
+	row := s.db.QueryRowContext(ctx, query,
+		entity.CreatorID, entity.Name, NormalizeName(entity.Name), entity.Kind, entity.Description, now)
+	upserted, err := scanEntity(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert entity: %w", err)
+	}
+	return upserted, nil
+}
+
+// ReplaceSource implements Store.
+func (s *DBStore) ReplaceSource(ctx context.Context, creatorID int32, source Source, mentions []*Mention, relations []*Relation, appendOnly bool) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previous []int32
+	if !appendOnly {
+		if previous, err = deleteSource(ctx, tx, creatorID, source); err != nil {
+			return err
+		}
+	}
+	for _, m := range mentions {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO kg_mention (entity_id, source_type, source_id, source_ref, snippet, created_ts) VALUES ($1, $2, $3, $4, $5, $6)",
+			m.EntityID, m.Type, m.ID, m.Ref, m.Snippet, m.CreatedTs); err != nil {
+			return fmt.Errorf("failed to insert mention: %w", err)
+		}
+	}
+	now := time.Now().Unix()
+	for _, r := range relations {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO kg_relation (from_entity_id, to_entity_id, relation, source_type, source_id, created_ts) VALUES ($1, $2, $3, $4, $5, $6)",
+			r.FromEntityID, r.ToEntityID, r.Relation, r.Type, r.ID, now); err != nil {
+			return fmt.Errorf("failed to insert relation: %w", err)
+		}
+	}
+	if err := deleteOrphans(ctx, tx, previous); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteSource implements Store.
+func (s *DBStore) DeleteSource(ctx context.Context, creatorID int32, source Source) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	previous, err := deleteSource(ctx, tx, creatorID, source)
+	if err != nil {
+		return err
+	}
+	if err := deleteOrphans(ctx, tx, previous); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deleteSource deletes the mentions and relations of source among the
+// creator's entities and returns the entities it mentioned.
+func deleteSource(ctx context.Context, tx *sql.Tx, creatorID int32, source Source) ([]int32, error) {
+	rows, err := tx.QueryContext(ctx, `
+		DELETE FROM kg_mention
+		WHERE source_type = $1 AND source_id = $2
+			AND entity_id IN (SELECT id FROM kg_entity WHERE creator_id = $3)
+		RETURNING entity_id`,
+		source.Type, source.ID, creatorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete mentions: %w", err)
+	}
+	defer rows.Close()
+	var entityIDs []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan mention: %w", err)
+		}
+		entityIDs = append(entityIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM kg_relation
+		WHERE source_type = $1 AND source_id = $2
+			AND from_entity_id IN (SELECT id FROM kg_entity WHERE creator_id = $3)`,
+		source.Type, source.ID, creatorID); err != nil {
+		return nil, fmt.Errorf("failed to delete relations: %w", err)
+	}
+	return entityIDs, nil
+}
+
+// deleteOrphans deletes the given entities that are no longer mentioned.
+func deleteOrphans(ctx context.Context, tx *sql.Tx, entityIDs []int32) error {
+	if len(entityIDs) == 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM kg_entity e
+		WHERE e.id = ANY($1) AND NOT EXISTS (SELECT 1 FROM kg_mention m WHERE m.entity_id = e.id)`,
+		pq.Array(entityIDs)); err != nil {
+		return fmt.Errorf("failed to delete orphan entities: %w", err)
+	}
+	return nil
+}
+
+// ListEntities implements Store, ordering by mention count or latest mention.
+func (s *DBStore) ListEntities(ctx context.Context, find *FindEntity) ([]*Entity, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if find.ID != nil {
+		args = append(args, *find.ID)
+		where = append(where, fmt.Sprintf("e.id = $%d", len(args)))
+	}
+	if find.CreatorID != nil {
+		args = append(args, *find.CreatorID)
+		where = append(where, fmt.Sprintf("e.creator_id = $%d", len(args)))
+	}
+	if find.Kind != nil {
+		args = append(args, *find.Kind)
+		where = append(where, fmt.Sprintf("e.kind = $%d", len(args)))
+	}
+	if find.NormalizedName != nil {
+		args = append(args, *find.NormalizedName)
+		where = append(where, fmt.Sprintf("e.normalized_name = $%d", len(args)))
+	}
+	if find.Query != "" {
+		args = append(args, "%"+escapeLike(NormalizeName(find.Query))+"%")
+		where = append(where, fmt.Sprintf("e.normalized_name LIKE $%d", len(args)))
+	}
+
+	order := "mention_count DESC"
+	if find.RecentFirst {
+		order = "last_mention_ts DESC"
+	}
+	query := "SELECT " + entityColumns + ", " + mentionCount + " AS mention_count, " + lastMention + " AS last_mention_ts FROM kg_entity e WHERE " +
+		strings.Join(where, " AND ") + " ORDER BY " + order + ", e.updated_ts DESC"
+	if find.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", find.Limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list entities: %w", err)
+	}
+	defer rows.Close()
+
+	entities := []*Entity{}
+	for rows.Next() {
+		entity, err := scanEntity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan entity: %w", err)
+		}
+		entities = append(entities, entity)
+	}
+	return entities, rows.Err()
+}
+
+// ListMentions implements Store, latest first.
+func (s *DBStore) ListMentions(ctx context.Context, entityID int32, limit int) ([]*Mention, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT entity_id, source_type, source_id, source_ref, snippet, created_ts
+		FROM kg_mention WHERE entity_id = $1
+		ORDER BY created_ts DESC, id DESC
+		LIMIT $2`,
+		entityID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mentions: %w", err)
+	}
+	defer rows.Close()
+
+	mentions := []*Mention{}
+	for rows.Next() {
+		m := &Mention{}
+		if err := rows.Scan(&m.EntityID, &m.Type, &m.ID, &m.Ref, &m.Snippet, &m.CreatedTs); err != nil {
+			return nil, fmt.Errorf("failed to scan mention: %w", err)
+		}
+		mentions = append(mentions, m)
+	}
+	return mentions, rows.Err()
+}
+
+// ListRelated implements Store, most stated relations first.
+func (s *DBStore) ListRelated(ctx context.Context, entityID int32) ([]*RelatedEntity, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+entityColumns+`, `+mentionCount+`, `+lastMention+`, r.relation, r.outgoing, r.count
+		FROM (
+			SELECT to_entity_id AS other_id, relation, TRUE AS outgoing, COUNT(*) AS count
+			FROM kg_relation WHERE from_entity_id = $1
+			GROUP BY to_entity_id, relation
+			UNION ALL
+			SELECT from_entity_id AS other_id, relation, FALSE AS outgoing, COUNT(*) AS count
+			FROM kg_relation WHERE to_entity_id = $1
+			GROUP BY from_entity_id, relation
+		) r
+		JOIN kg_entity e ON e.id = r.other_id
+		ORDER BY r.count DESC, e.name`,
+		entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list related entities: %w", err)
+	}
+	defer rows.Close()
+
+	related := []*RelatedEntity{}
+	for rows.Next() {
+		entity := &Entity{}
+		r := &RelatedEntity{Entity: entity}
+		if err := rows.Scan(&entity.ID, &entity.CreatorID, &entity.Name, &entity.Kind, &entity.Description,
+			&entity.CreatedTs, &entity.UpdatedTs, &entity.MentionCount, &entity.LastMentionTs, &r.Relation, &r.Outgoing, &r.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan related entity: %w", err)
+		}
+		related = append(related, r)
+	}
+	return related, rows.Err()
+}
+
+// DeleteEntity implements Store. It returns false when the creator has no
+// such entity.
+func (s *DBStore) DeleteEntity(ctx context.Context, id, creatorID int32) (bool, error) {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM kg_entity WHERE id = $1 AND creator_id = $2", id, creatorID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete entity: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete entity: %w", err)
+	}
+	return n > 0, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEntity(row rowScanner) (*Entity, error) {
+	entity := &Entity{}
+	if err := row.Scan(&entity.ID, &entity.CreatorID, &entity.Name, &entity.Kind, &entity.Description,
+		&entity.CreatedTs, &entity.UpdatedTs, &entity.MentionCount, &entity.LastMentionTs); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// escapeLike escapes the LIKE wildcards of s.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,212 @@
+// Package evolutiontask holds the backlog of Evolution Mode tasks.
+//
+// Admins queue improvement requests, which an admin approves or rejects
+// before any code changes. In Evolution Mode, the "/task" command has
+// the EvolutionParrot claim an approved task and run it; the task then traces
+// the run end to end: its blocks, the commits and diff it produced, the test
+// gate result and the pull request.
+package evolutiontask
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hrygo/divinesense/ai/agents/geek"
+)
+
+var (
+	// ErrNotFound is returned for tasks that do not exist.
+	ErrNotFound = errors.New("evolution task not found")
+	// ErrInvalid is returned for tasks that fail validation.
+	ErrInvalid = errors.New("invalid evolution task")
+	// ErrConflict is returned for reviews of tasks not awaiting one.
+	ErrConflict = errors.New("evolution task is not awaiting this review")
+)
+
+// Status is the stage of a task in the workflow.
+type Status string
+
+const (
+	// StatusPending tasks await the approval of an admin.
+	StatusPending Status = "pending"
+	// StatusApproved tasks are queued for a run.
+	StatusApproved Status = "approved"
+	// StatusRejected tasks are closed without a run.
+	StatusRejected Status = "rejected"
+	// StatusRunning tasks are being run by an EvolutionParrot.
+	StatusRunning Status = "running"
+	// StatusCompleted tasks ran successfully.
+	StatusCompleted Status = "completed"
+	// StatusFailed tasks failed their run or its tests; approving them again
+	// queues a new run.
+	StatusFailed Status = "failed"
+)
+
+// Statuses lists the statuses in workflow order.
+var Statuses = []Status{StatusPending, StatusApproved, StatusRejected, StatusRunning, StatusCompleted, StatusFailed}
+
+const (
+	maxTitleLength       = 200
+	maxDescriptionLength = 20000
+	maxNoteLength        = 2000
+	// maxTestOutputLength bounds the test output kept on a task.
+	maxTestOutputLength = 8000
+)
+
+// Task is an improvement request of the Evolution backlog.
+type Task struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	CreatorID   int32  `json:"creator_id"`
+	Status      Status `json:"status"`
+	// ReviewerID is the admin who approved or rejected the task, 0 before.
+	ReviewerID int32  `json:"reviewer_id"`
+	ReviewNote string `json:"review_note"`
+	// RunnerID is the admin whose Evolution session ran the task.
+	RunnerID       int32   `json:"runner_id"`
+	ConversationID int32   `json:"conversation_id"`
+	BlockIDs       []int64 `json:"block_ids"`
+	BaseCommit     string  `json:"base_commit"`
+	HeadCommit     string  `json:"head_commit"`
+	DiffStat       string  `json:"diff_stat"`
+	PRURL          string  `json:"pr_url"`
+	TestStatus     string  `json:"test_status"`
+	TestOutput     string  `json:"test_output"`
+	Error          string  `json:"error"`
+	CreatedTs      int64   `json:"created_ts"`
+	UpdatedTs      int64   `json:"updated_ts"`
+}
+
+// Validate checks and normalizes a task about to be created.
+func (t *Task) Validate() error {
+	t.Title = strings.TrimSpace(t.Title)
+	t.Description = strings.TrimSpace(t.Description)
+	switch {
+	case t.Title == "":
+		return fmt.Errorf("%w: title is required", ErrInvalid)
+	case utf8.RuneCountInString(t.Title) > maxTitleLength:
+		return fmt.Errorf("%w: title exceeds %d characters", ErrInvalid, maxTitleLength)
+	case t.Description == "":
+		return fmt.Errorf("%w: description is required", ErrInvalid)
+	case utf8.RuneCountInString(t.Description) > maxDescriptionLength:
+		return fmt.Errorf("%w: description exceeds %d characters", ErrInvalid, maxDescriptionLength)
+	}
+	return nil
+}
+
+// Review is the approval or rejection of a task.
+type Review struct {
+	ReviewerID int32
+	Note       string
+	// From are the statuses the task may be reviewed in.
+	From []Status
+	// To is the status of the reviewed task.
+	To Status
+}
+
+// Store persists the tasks.
+type Store interface {
+	CreateTask(ctx context.Context, task *Task) (*Task, error)
+	// GetTask returns ErrNotFound for tasks that do not exist.
+	GetTask(ctx context.Context, id int64) (*Task, error)
+	// ListTasks lists the tasks of a status ("" for all), newest first.
+	ListTasks(ctx context.Context, status Status) ([]*Task, error)
+	// ReviewTask returns ErrNotFound for tasks that do not exist, and
+	// ErrConflict for tasks not in a status of the review.
+	ReviewTask(ctx context.Context, id int64, review *Review) (*Task, error)
+	// ClaimTask moves an approved task to running: the given one, or the
+	// oldest with id 0. It returns ErrNotFound if none matches.
+	ClaimTask(ctx context.Context, id int64, runnerID int32) (*Task, error)
+	// FinishTask records the outcome of a running task.
+	FinishTask(ctx context.Context, id int64, status Status, outcome *geek.TaskOutcome) error
+	// LinkBlock links a block of a conversation to a task.
+	LinkBlock(ctx context.Context, id int64, conversationID int32, blockID int64) error
+}
+
+// Tasks manages the Evolution backlog. It is the geek.TaskQueue of the
+// EvolutionParrot.
+type Tasks struct {
+	store Store
+}
+
+var _ geek.TaskQueue = (*Tasks)(nil)
+
+// New creates the backlog.
+func New(store Store) *Tasks {
+	return &Tasks{store: store}
+}
+
+// Create queues a task for approval.
+func (t *Tasks) Create(ctx context.Context, task *Task) (*Task, error) {
+	if err := task.Validate(); err != nil {
+		return nil, err
+	}
+	task.Status = StatusPending
+	return t.store.CreateTask(ctx, task)
+}
+
+// Get returns a task.
+func (t *Tasks) Get(ctx context.Context, id int64) (*Task, error) {
+	return t.store.GetTask(ctx, id)
+}
+
+// List lists the tasks of a status ("" for all), newest first.
+func (t *Tasks) List(ctx context.Context, status Status) ([]*Task, error) {
+	if status != "" && !slices.Contains(Statuses, status) {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalid, status)
+	}
+	return t.store.ListTasks(ctx, status)
+}
+
+// Approve queues a pending task for a run, or a failed one for a new run.
+func (t *Tasks) Approve(ctx context.Context, id int64, reviewerID int32, note string) (*Task, error) {
+	return t.review(ctx, id, &Review{ReviewerID: reviewerID, Note: note, From: []Status{StatusPending, StatusFailed}, To: StatusApproved})
+}
+
+// Reject closes a pending task without a run.
+func (t *Tasks) Reject(ctx context.Context, id int64, reviewerID int32, note string) (*Task, error) {
+	return t.review(ctx, id, &Review{ReviewerID: reviewerID, Note: note, From: []Status{StatusPending}, To: StatusRejected})
+}
+
+func (t *Tasks) review(ctx context.Context, id int64, review *Review) (*Task, error) {
+	review.Note = strings.TrimSpace(review.Note)
+	if utf8.RuneCountInString(review.Note) > maxNoteLength {
+		return nil, fmt.Errorf("%w: note exceeds %d characters", ErrInvalid, maxNoteLength)
+	}
+	return t.store.ReviewTask(ctx, id, review)
+}
+
+// Claim implements geek.TaskQueue.
+func (t *Tasks) Claim(ctx context.Context, taskID int64, userID int32) (*geek.QueuedTask, error) {
+	task, err := t.store.ClaimTask(ctx, taskID, userID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, geek.ErrNoApprovedTask
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &geek.QueuedTask{ID: task.ID, Title: task.Title, Description: task.Description}, nil
+}
+
+// Finish implements geek.TaskQueue: tasks whose run or tests failed are
+// failed, the others completed.
+func (t *Tasks) Finish(ctx context.Context, taskID int64, outcome *geek.TaskOutcome) error {
+	status := StatusCompleted
+	if outcome.Err != nil || outcome.TestStatus == geek.TaskTestsFailed {
+		status = StatusFailed
+	}
+	if len(outcome.TestOutput) > maxTestOutputLength {
+		outcome.TestOutput = strings.ToValidUTF8(outcome.TestOutput[len(outcome.TestOutput)-maxTestOutputLength:], "")
+	}
+	return t.store.FinishTask(ctx, taskID, status, outcome)
+}
+
+// LinkBlock links the block of a run to its task.
+func (t *Tasks) LinkBlock(ctx context.Context, taskID int64, conversationID int32, blockID int64) error {
+	return t.store.LinkBlock(ctx, taskID, conversationID, blockID)
+}
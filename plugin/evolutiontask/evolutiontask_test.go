@@ -0,0 +1,154 @@
+package evolutiontask
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/ai/agents/geek"
+)
+
+type fakeStore struct {
+	tasks []*Task
+}
+
+func (s *fakeStore) CreateTask(_ context.Context, task *Task) (*Task, error) {
+	task.ID = int64(len(s.tasks) + 1)
+	s.tasks = append(s.tasks, task)
+	return task, nil
+}
+
+func (s *fakeStore) GetTask(_ context.Context, id int64) (*Task, error) {
+	if id < 1 || int(id) > len(s.tasks) {
+		return nil, ErrNotFound
+	}
+	return s.tasks[id-1], nil
+}
+
+func (s *fakeStore) ListTasks(_ context.Context, status Status) ([]*Task, error) {
+	tasks := []*Task{}
+	for _, task := range slices.Backward(s.tasks) {
+		if status == "" || task.Status == status {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (s *fakeStore) ReviewTask(ctx context.Context, id int64, review *Review) (*Task, error) {
+	task, err := s.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(review.From, task.Status) {
+		return nil, ErrConflict
+	}
+	task.Status, task.ReviewerID, task.ReviewNote = review.To, review.ReviewerID, review.Note
+	return task, nil
+}
+
+func (s *fakeStore) ClaimTask(_ context.Context, id int64, runnerID int32) (*Task, error) {
+	for _, task := range s.tasks {
+		if task.Status == StatusApproved && (id == 0 || task.ID == id) {
+			task.Status, task.RunnerID = StatusRunning, runnerID
+			return task, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *fakeStore) FinishTask(ctx context.Context, id int64, status Status, outcome *geek.TaskOutcome) error {
+	task, err := s.GetTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	task.Status, task.PRURL, task.TestStatus, task.TestOutput = status, outcome.PRURL, outcome.TestStatus, outcome.TestOutput
+	return nil
+}
+
+func (s *fakeStore) LinkBlock(ctx context.Context, id int64, conversationID int32, blockID int64) error {
+	task, err := s.GetTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	task.ConversationID = conversationID
+	task.BlockIDs = append(task.BlockIDs, blockID)
+	return nil
+}
+
+func TestTasks_Workflow(t *testing.T) {
+	ctx := context.Background()
+	tasks := New(&fakeStore{})
+
+	_, err := tasks.Create(ctx, &Task{Title: "  ", Description: "cache the router"})
+	require.ErrorIs(t, err, ErrInvalid)
+
+	first, err := tasks.Create(ctx, &Task{Title: " Router cache ", Description: "cache the router", CreatorID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, first.Status)
+	assert.Equal(t, "Router cache", first.Title)
+	second, err := tasks.Create(ctx, &Task{Title: "Rename", Description: "rename the handler", CreatorID: 1})
+	require.NoError(t, err)
+
+	// Pending tasks are not run
+	_, err = tasks.Claim(ctx, 0, 1)
+	require.ErrorIs(t, err, geek.ErrNoApprovedTask)
+
+	_, err = tasks.Reject(ctx, second.ID, 2, "out of scope")
+	require.NoError(t, err)
+	_, err = tasks.Approve(ctx, second.ID, 2, "")
+	require.ErrorIs(t, err, ErrConflict, "rejected tasks stay closed")
+	_, err = tasks.Approve(ctx, 42, 2, "")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	approved, err := tasks.Approve(ctx, first.ID, 2, "go ahead")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), approved.ReviewerID)
+
+	queued, err := tasks.Claim(ctx, 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, &geek.QueuedTask{ID: first.ID, Title: "Router cache", Description: "cache the router"}, queued)
+	_, err = tasks.Claim(ctx, first.ID, 1)
+	require.ErrorIs(t, err, geek.ErrNoApprovedTask, "a task runs once")
+
+	// A failed test gate fails the task, which may be approved again
+	require.NoError(t, tasks.Finish(ctx, first.ID, &geek.TaskOutcome{
+		TestStatus: geek.TaskTestsFailed,
+		TestOutput: strings.Repeat("x", maxTestOutputLength+10),
+	}))
+	failed, err := tasks.Get(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, failed.Status)
+	assert.Len(t, failed.TestOutput, maxTestOutputLength)
+	_, err = tasks.Approve(ctx, first.ID, 2, "retry")
+	require.NoError(t, err)
+
+	_, err = tasks.Claim(ctx, first.ID, 1)
+	require.NoError(t, err)
+	require.NoError(t, tasks.LinkBlock(ctx, first.ID, 7, 70))
+	require.NoError(t, tasks.Finish(ctx, first.ID, &geek.TaskOutcome{
+		TestStatus: geek.TaskTestsPassed,
+		PRURL:      "https://github.com/hrygo/divinesense/pull/1",
+	}))
+	done, err := tasks.Get(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, done.Status)
+	assert.Equal(t, []int64{70}, done.BlockIDs)
+	assert.Equal(t, int32(7), done.ConversationID)
+
+	require.NoError(t, tasks.Finish(ctx, first.ID, &geek.TaskOutcome{Err: errors.New("CLI crashed")}))
+	crashed, _ := tasks.Get(ctx, first.ID)
+	assert.Equal(t, StatusFailed, crashed.Status)
+
+	listed, err := tasks.List(ctx, StatusRejected)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, second.ID, listed[0].ID)
+	_, err = tasks.List(ctx, "done")
+	require.ErrorIs(t, err, ErrInvalid)
+}
@@ -0,0 +1,182 @@
+package evolutiontask
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/hrygo/divinesense/ai/agents/geek"
+)
+
+// DBStore persists tasks in the evolution_task table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new task store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const taskColumns = `id, title, description, creator_id, status, reviewer_id, review_note, runner_id,
+	conversation_id, block_ids, base_commit, head_commit, diff_stat, pr_url, test_status, test_output,
+	error, created_ts, updated_ts`
+
+// CreateTask implements Store.
+func (s *DBStore) CreateTask(ctx context.Context, task *Task) (*Task, error) {
+	now := time.Now().Unix()
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO evolution_task (title, description, creator_id, status, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		RETURNING `+taskColumns,
+		task.Title, task.Description, task.CreatorID, task.Status, now)
+	created, err := scanTask(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create evolution task: %w", err)
+	}
+	return created, nil
+}
+
+// GetTask implements Store.
+func (s *DBStore) GetTask(ctx context.Context, id int64) (*Task, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+taskColumns+" FROM evolution_task WHERE id = $1", id)
+	task, err := scanTask(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get evolution task: %w", err)
+	}
+	return task, nil
+}
+
+// ListTasks implements Store.
+func (s *DBStore) ListTasks(ctx context.Context, status Status) ([]*Task, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+taskColumns+` FROM evolution_task
+		WHERE $1 = '' OR status = $1
+		ORDER BY id DESC`, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list evolution tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []*Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan evolution task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list evolution tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// ReviewTask implements Store.
+func (s *DBStore) ReviewTask(ctx context.Context, id int64, review *Review) (*Task, error) {
+	from := make([]string, len(review.From))
+	for i, status := range review.From {
+		from[i] = string(status)
+	}
+	row := s.db.QueryRowContext(ctx, `
+		UPDATE evolution_task
+		SET status = $2, reviewer_id = $3, review_note = $4, updated_ts = $5
+		WHERE id = $1 AND status = ANY($6)
+		RETURNING `+taskColumns,
+		id, review.To, review.ReviewerID, review.Note, time.Now().Unix(), pq.Array(from))
+	task, err := scanTask(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Tell a missing task from one in another status
+		if _, err := s.GetTask(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrConflict
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to review evolution task: %w", err)
+	}
+	return task, nil
+}
+
+// ClaimTask implements Store. Concurrent claims never take the same task.
+func (s *DBStore) ClaimTask(ctx context.Context, id int64, runnerID int32) (*Task, error) {
+	row := s.db.QueryRowContext(ctx, `
+		UPDATE evolution_task
+		SET status = 'running', runner_id = $2, error = '', updated_ts = $3
+		WHERE id = (
+			SELECT id FROM evolution_task
+			WHERE status = 'approved' AND ($1 = 0 OR id = $1)
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+taskColumns,
+		id, runnerID, time.Now().Unix())
+	task, err := scanTask(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim evolution task: %w", err)
+	}
+	return task, nil
+}
+
+// FinishTask implements Store.
+func (s *DBStore) FinishTask(ctx context.Context, id int64, status Status, outcome *geek.TaskOutcome) error {
+	var runErr string
+	if outcome.Err != nil {
+		runErr = outcome.Err.Error()
+	}
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE evolution_task
+		SET status = $2, base_commit = $3, head_commit = $4, diff_stat = $5, pr_url = $6,
+			test_status = $7, test_output = $8, error = $9, updated_ts = $10
+		WHERE id = $1 AND status = 'running'`,
+		id, status, outcome.BaseCommit, outcome.HeadCommit, outcome.DiffStat,
+		outcome.PRURL, outcome.TestStatus, outcome.TestOutput, runErr, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("failed to finish evolution task: %w", err)
+	}
+	return nil
+}
+
+// LinkBlock implements Store.
+func (s *DBStore) LinkBlock(ctx context.Context, id int64, conversationID int32, blockID int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE evolution_task
+		SET conversation_id = $2,
+			block_ids = CASE WHEN $3 = ANY(block_ids) THEN block_ids ELSE array_append(block_ids, $3) END,
+			updated_ts = $4
+		WHERE id = $1`,
+		id, conversationID, blockID, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("failed to link block to evolution task: %w", err)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row rowScanner) (*Task, error) {
+	task := &Task{}
+	if err := row.Scan(&task.ID, &task.Title, &task.Description, &task.CreatorID, &task.Status,
+		&task.ReviewerID, &task.ReviewNote, &task.RunnerID, &task.ConversationID, pq.Array(&task.BlockIDs),
+		&task.BaseCommit, &task.HeadCommit, &task.DiffStat, &task.PRURL, &task.TestStatus, &task.TestOutput,
+		&task.Error, &task.CreatedTs, &task.UpdatedTs); err != nil {
+		return nil, err
+	}
+	if task.BlockIDs == nil {
+		task.BlockIDs = []int64{}
+	}
+	return task, nil
+}
@@ -0,0 +1,311 @@
+// Package expense records the expenses of users and sums them up by month.
+//
+// The expense expert parses messages such as "spent 45 on lunch" into
+// expenses with an amount, a currency, a category and a day. Like other
+// writes of the experts, it shows the parsed expenses in a request_form and
+// records them with the expense_add tool only once the user confirmed them.
+// Amounts are kept in minor units of their currency (cents for CNY, yen for
+// JPY), and sums are never mixed across currencies.
+package expense
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	// ErrNotFound is returned for expenses the user does not have.
+	ErrNotFound = errors.New("expense not found")
+	// ErrInvalid is returned for expenses and queries that fail validation.
+	ErrInvalid = errors.New("invalid expense")
+)
+
+// Categories of the expenses.
+const (
+	CategoryFood          = "food"
+	CategoryTransport     = "transport"
+	CategoryShopping      = "shopping"
+	CategoryHousing       = "housing"
+	CategoryBills         = "bills"
+	CategoryEntertainment = "entertainment"
+	CategoryHealth        = "health"
+	CategoryTravel        = "travel"
+	CategoryEducation     = "education"
+	CategoryOther         = "other"
+)
+
+// Categories lists the categories of the expenses.
+var Categories = []string{
+	CategoryFood, CategoryTransport, CategoryShopping, CategoryHousing, CategoryBills,
+	CategoryEntertainment, CategoryHealth, CategoryTravel, CategoryEducation, CategoryOther,
+}
+
+const (
+	// DefaultCurrency is the currency of expenses that name none.
+	DefaultCurrency = "CNY"
+	// maxAmount bounds the amount of an expense, in major units.
+	maxAmount = 1e9
+	// maxNoteRunes bounds the note of an expense.
+	maxNoteRunes = 200
+	// maxListLimit bounds the expenses returned by List.
+	maxListLimit = 500
+	// maxSummaryMonths bounds the months of a monthly summary.
+	maxSummaryMonths = 36
+)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// zeroDecimalCurrencies have no minor unit.
+var zeroDecimalCurrencies = []string{"JPY", "KRW", "VND", "CLP", "ISK"}
+
+// Expense is money a user spent.
+type Expense struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"-"`
+	// Amount is in minor units of the currency, e.g. 4550 for 45.50 CNY.
+	Amount int64 `json:"amount"`
+	// Currency is an ISO 4217 code, e.g. CNY.
+	Currency string `json:"currency"`
+	Category string `json:"category"`
+	// Note says what the money was spent on, e.g. "lunch".
+	Note string `json:"note"`
+	// Date is the day of the expense, YYYY-MM-DD.
+	Date      string `json:"date"`
+	CreatedTs int64  `json:"created_ts"`
+	UpdatedTs int64  `json:"updated_ts"`
+}
+
+// Validate checks and normalizes an expense about to be saved.
+func (e *Expense) Validate() error {
+	e.Currency = strings.ToUpper(strings.TrimSpace(e.Currency))
+	if e.Currency == "" {
+		e.Currency = DefaultCurrency
+	}
+	if !currencyPattern.MatchString(e.Currency) {
+		return fmt.Errorf("%w: currency must be an ISO 4217 code such as CNY", ErrInvalid)
+	}
+	if e.Amount <= 0 || float64(e.Amount) > maxAmount*math.Pow10(exponent(e.Currency)) {
+		return fmt.Errorf("%w: amount must be positive and at most %.0f", ErrInvalid, maxAmount)
+	}
+	e.Category = strings.ToLower(strings.TrimSpace(e.Category))
+	if e.Category == "" {
+		e.Category = CategoryOther
+	}
+	if !slices.Contains(Categories, e.Category) {
+		return fmt.Errorf("%w: category must be one of %s", ErrInvalid, strings.Join(Categories, ", "))
+	}
+	e.Note = strings.Join(strings.Fields(e.Note), " ")
+	if utf8.RuneCountInString(e.Note) > maxNoteRunes {
+		return fmt.Errorf("%w: note exceeds %d characters", ErrInvalid, maxNoteRunes)
+	}
+	if _, err := time.Parse(time.DateOnly, e.Date); err != nil {
+		return fmt.Errorf("%w: date must be YYYY-MM-DD", ErrInvalid)
+	}
+	return nil
+}
+
+// exponent returns the number of decimals of the minor unit of a currency.
+func exponent(currency string) int {
+	if slices.Contains(zeroDecimalCurrencies, currency) {
+		return 0
+	}
+	return 2
+}
+
+// ToMinor converts an amount in major units of a currency, e.g. 45.5, to
+// minor units, e.g. 4550.
+func ToMinor(amount float64, currency string) int64 {
+	return int64(math.Round(amount * math.Pow10(exponent(strings.ToUpper(currency)))))
+}
+
+// FormatAmount formats an amount in minor units of a currency, e.g. "45.50 CNY".
+func FormatAmount(amount int64, currency string) string {
+	exp := exponent(currency)
+	return fmt.Sprintf("%.*f %s", exp, float64(amount)/math.Pow10(exp), currency)
+}
+
+// Find selects the expenses of a user.
+type Find struct {
+	UserID int32
+	// From and To bound the days of the expenses, inclusive; empty for none.
+	From     string
+	To       string
+	Category string
+	Limit    int
+}
+
+// Total is the sum of expenses in a currency.
+type Total struct {
+	// Month is the month of the total in a monthly summary, YYYY-MM.
+	Month    string `json:"month,omitempty"`
+	Currency string `json:"currency"`
+	// Category is the category of the total in a category breakdown.
+	Category string `json:"category,omitempty"`
+	Amount   int64  `json:"amount"`
+	Count    int    `json:"count"`
+}
+
+// Summary sums up the expenses of a user in a month.
+type Summary struct {
+	Month string `json:"month"`
+	// Totals are the totals by currency, largest first.
+	Totals []Total `json:"totals"`
+	// Categories are the totals by currency and category, largest first.
+	Categories []Total `json:"categories"`
+}
+
+// Store persists the expenses.
+type Store interface {
+	// ListExpenses returns expenses, latest day first.
+	ListExpenses(ctx context.Context, find *Find) ([]*Expense, error)
+	// GetExpense returns ErrNotFound for expenses the user does not have.
+	GetExpense(ctx context.Context, userID, id int32) (*Expense, error)
+	// CreateExpenses saves expenses at once.
+	CreateExpenses(ctx context.Context, expenses []*Expense) ([]*Expense, error)
+	// UpdateExpense returns ErrNotFound for expenses the user does not have.
+	UpdateExpense(ctx context.Context, expense *Expense) (*Expense, error)
+	// DeleteExpense returns ErrNotFound for expenses the user does not have.
+	DeleteExpense(ctx context.Context, userID, id int32) error
+	// SumExpenses returns the totals of the expenses of a user between two
+	// days, inclusive, by month, currency and category.
+	SumExpenses(ctx context.Context, userID int32, from, to string) ([]Total, error)
+}
+
+// Expenses manages the expenses of the users.
+type Expenses struct {
+	store Store
+}
+
+// New creates the expenses.
+func New(store Store) *Expenses {
+	return &Expenses{store: store}
+}
+
+// List returns the expenses of a user, latest day first.
+func (e *Expenses) List(ctx context.Context, find *Find) ([]*Expense, error) {
+	for _, date := range []string{find.From, find.To} {
+		if _, err := time.Parse(time.DateOnly, date); date != "" && err != nil {
+			return nil, fmt.Errorf("%w: dates must be YYYY-MM-DD", ErrInvalid)
+		}
+	}
+	if find.Limit <= 0 || find.Limit > maxListLimit {
+		find.Limit = maxListLimit
+	}
+	return e.store.ListExpenses(ctx, find)
+}
+
+// Get returns an expense of a user.
+func (e *Expenses) Get(ctx context.Context, userID, id int32) (*Expense, error) {
+	return e.store.GetExpense(ctx, userID, id)
+}
+
+// Create validates and saves expenses of a user, all or none.
+func (e *Expenses) Create(ctx context.Context, userID int32, expenses []*Expense) ([]*Expense, error) {
+	if len(expenses) == 0 {
+		return nil, fmt.Errorf("%w: no expenses", ErrInvalid)
+	}
+	for i, expense := range expenses {
+		expense.UserID = userID
+		if err := expense.Validate(); err != nil {
+			return nil, fmt.Errorf("expense %d: %w", i+1, err)
+		}
+	}
+	return e.store.CreateExpenses(ctx, expenses)
+}
+
+// Update validates and replaces an expense of a user.
+func (e *Expenses) Update(ctx context.Context, expense *Expense) (*Expense, error) {
+	if err := expense.Validate(); err != nil {
+		return nil, err
+	}
+	return e.store.UpdateExpense(ctx, expense)
+}
+
+// Delete removes an expense of a user.
+func (e *Expenses) Delete(ctx context.Context, userID, id int32) error {
+	return e.store.DeleteExpense(ctx, userID, id)
+}
+
+// Summarize sums up the expenses of a user in a month, YYYY-MM.
+func (e *Expenses) Summarize(ctx context.Context, userID int32, month string) (*Summary, error) {
+	start, err := time.Parse("2006-01", month)
+	if err != nil {
+		return nil, fmt.Errorf("%w: month must be YYYY-MM", ErrInvalid)
+	}
+	totals, err := e.store.SumExpenses(ctx, userID, start.Format(time.DateOnly), start.AddDate(0, 1, -1).Format(time.DateOnly))
+	if err != nil {
+		return nil, err
+	}
+	summary := &Summary{Month: month, Totals: []Total{}, Categories: []Total{}}
+	for _, total := range totals {
+		total.Month = ""
+		summary.Categories = append(summary.Categories, total)
+		i := slices.IndexFunc(summary.Totals, func(t Total) bool { return t.Currency == total.Currency })
+		if i < 0 {
+			summary.Totals = append(summary.Totals, Total{Currency: total.Currency})
+			i = len(summary.Totals) - 1
+		}
+		summary.Totals[i].Amount += total.Amount
+		summary.Totals[i].Count += total.Count
+	}
+	sortTotals(summary.Totals)
+	sortTotals(summary.Categories)
+	return summary, nil
+}
+
+// Monthly returns the totals of the expenses of a user by month and
+// currency, from one month to another, YYYY-MM, inclusive.
+func (e *Expenses) Monthly(ctx context.Context, userID int32, from, to string) ([]Total, error) {
+	start, err := time.Parse("2006-01", from)
+	if err != nil {
+		return nil, fmt.Errorf("%w: from must be YYYY-MM", ErrInvalid)
+	}
+	end, err := time.Parse("2006-01", to)
+	if err != nil {
+		return nil, fmt.Errorf("%w: to must be YYYY-MM", ErrInvalid)
+	}
+	if end.Before(start) || start.AddDate(0, maxSummaryMonths, 0).Before(end) {
+		return nil, fmt.Errorf("%w: months must span 1 to %d months", ErrInvalid, maxSummaryMonths)
+	}
+	totals, err := e.store.SumExpenses(ctx, userID, start.Format(time.DateOnly), end.AddDate(0, 1, -1).Format(time.DateOnly))
+	if err != nil {
+		return nil, err
+	}
+	monthly := []Total{}
+	for _, total := range totals {
+		i := slices.IndexFunc(monthly, func(t Total) bool { return t.Month == total.Month && t.Currency == total.Currency })
+		if i < 0 {
+			monthly = append(monthly, Total{Month: total.Month, Currency: total.Currency})
+			i = len(monthly) - 1
+		}
+		monthly[i].Amount += total.Amount
+		monthly[i].Count += total.Count
+	}
+	slices.SortFunc(monthly, func(a, b Total) int {
+		return strings.Compare(a.Month+a.Currency, b.Month+b.Currency)
+	})
+	return monthly, nil
+}
+
+// sortTotals sorts totals by currency, then largest first.
+func sortTotals(totals []Total) {
+	slices.SortStableFunc(totals, func(a, b Total) int {
+		if c := strings.Compare(a.Currency, b.Currency); c != 0 {
+			return c
+		}
+		switch {
+		case a.Amount > b.Amount:
+			return -1
+		case a.Amount < b.Amount:
+			return 1
+		}
+		return strings.Compare(a.Category, b.Category)
+	})
+}
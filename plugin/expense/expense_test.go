@@ -0,0 +1,196 @@
+package expense
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	expenses []*Expense
+	nextID   int32
+}
+
+func (s *fakeStore) ListExpenses(_ context.Context, find *Find) ([]*Expense, error) {
+	expenses := []*Expense{}
+	for _, expense := range s.expenses {
+		if expense.UserID != find.UserID || (find.From != "" && expense.Date < find.From) ||
+			(find.To != "" && expense.Date > find.To) || (find.Category != "" && expense.Category != find.Category) {
+			continue
+		}
+		expenses = append(expenses, expense)
+	}
+	slices.SortFunc(expenses, func(a, b *Expense) int { return strings.Compare(b.Date, a.Date) })
+	return expenses[:min(len(expenses), find.Limit)], nil
+}
+
+func (s *fakeStore) GetExpense(_ context.Context, userID, id int32) (*Expense, error) {
+	for _, expense := range s.expenses {
+		if expense.ID == id && expense.UserID == userID {
+			return expense, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *fakeStore) CreateExpenses(_ context.Context, expenses []*Expense) ([]*Expense, error) {
+	for _, expense := range expenses {
+		s.nextID++
+		expense.ID = s.nextID
+		s.expenses = append(s.expenses, expense)
+	}
+	return expenses, nil
+}
+
+func (s *fakeStore) UpdateExpense(ctx context.Context, expense *Expense) (*Expense, error) {
+	current, err := s.GetExpense(ctx, expense.UserID, expense.ID)
+	if err != nil {
+		return nil, err
+	}
+	*current = *expense
+	return current, nil
+}
+
+func (s *fakeStore) DeleteExpense(ctx context.Context, userID, id int32) error {
+	if _, err := s.GetExpense(ctx, userID, id); err != nil {
+		return err
+	}
+	s.expenses = slices.DeleteFunc(s.expenses, func(expense *Expense) bool { return expense.ID == id })
+	return nil
+}
+
+func (s *fakeStore) SumExpenses(_ context.Context, userID int32, from, to string) ([]Total, error) {
+	totals := []Total{}
+	for _, expense := range s.expenses {
+		if expense.UserID != userID || expense.Date < from || expense.Date > to {
+			continue
+		}
+		month := expense.Date[:7]
+		i := slices.IndexFunc(totals, func(t Total) bool {
+			return t.Month == month && t.Currency == expense.Currency && t.Category == expense.Category
+		})
+		if i < 0 {
+			totals = append(totals, Total{Month: month, Currency: expense.Currency, Category: expense.Category})
+			i = len(totals) - 1
+		}
+		totals[i].Amount += expense.Amount
+		totals[i].Count++
+	}
+	return totals, nil
+}
+
+func TestExpenseValidate(t *testing.T) {
+	expense := &Expense{Amount: 4500, Currency: " usd", Category: "Food", Note: " lunch  with  team ", Date: "2026-10-16"}
+	require.NoError(t, expense.Validate())
+	assert.Equal(t, "USD", expense.Currency)
+	assert.Equal(t, "food", expense.Category)
+	assert.Equal(t, "lunch with team", expense.Note)
+
+	expense = &Expense{Amount: 100, Date: "2026-10-16"}
+	require.NoError(t, expense.Validate())
+	assert.Equal(t, DefaultCurrency, expense.Currency)
+	assert.Equal(t, CategoryOther, expense.Category)
+
+	assert.ErrorIs(t, (&Expense{Amount: 0, Date: "2026-10-16"}).Validate(), ErrInvalid)
+	assert.ErrorIs(t, (&Expense{Amount: 100, Currency: "yuan", Date: "2026-10-16"}).Validate(), ErrInvalid)
+	assert.ErrorIs(t, (&Expense{Amount: 100, Category: "gifts", Date: "2026-10-16"}).Validate(), ErrInvalid)
+	assert.ErrorIs(t, (&Expense{Amount: 100, Date: "yesterday"}).Validate(), ErrInvalid)
+}
+
+func TestAmounts(t *testing.T) {
+	assert.Equal(t, int64(4550), ToMinor(45.5, "CNY"))
+	assert.Equal(t, int64(1999), ToMinor(19.99, "usd"))
+	assert.Equal(t, int64(1200), ToMinor(1200, "JPY"))
+	assert.Equal(t, "45.50 CNY", FormatAmount(4550, "CNY"))
+	assert.Equal(t, "1200 JPY", FormatAmount(1200, "JPY"))
+}
+
+func TestSummaries(t *testing.T) {
+	ctx := context.Background()
+	expenses := New(&fakeStore{})
+	_, err := expenses.Create(ctx, 1, []*Expense{
+		{Amount: 4500, Category: CategoryFood, Date: "2026-10-16"},
+		{Amount: 3200, Category: CategoryTransport, Date: "2026-10-16"},
+		{Amount: 1800, Category: CategoryFood, Date: "2026-10-02"},
+		{Amount: 1200, Currency: "USD", Category: CategoryShopping, Date: "2026-10-05"},
+		{Amount: 9900, Category: CategoryFood, Date: "2026-09-30"},
+	})
+	require.NoError(t, err)
+	_, err = expenses.Create(ctx, 2, []*Expense{{Amount: 100, Date: "2026-10-16"}})
+	require.NoError(t, err)
+
+	summary, err := expenses.Summarize(ctx, 1, "2026-10")
+	require.NoError(t, err)
+	assert.Equal(t, []Total{
+		{Currency: "CNY", Amount: 9500, Count: 3},
+		{Currency: "USD", Amount: 1200, Count: 1},
+	}, summary.Totals)
+	assert.Equal(t, []Total{
+		{Currency: "CNY", Category: CategoryFood, Amount: 6300, Count: 2},
+		{Currency: "CNY", Category: CategoryTransport, Amount: 3200, Count: 1},
+		{Currency: "USD", Category: CategoryShopping, Amount: 1200, Count: 1},
+	}, summary.Categories)
+
+	monthly, err := expenses.Monthly(ctx, 1, "2026-09", "2026-10")
+	require.NoError(t, err)
+	assert.Equal(t, []Total{
+		{Month: "2026-09", Currency: "CNY", Amount: 9900, Count: 1},
+		{Month: "2026-10", Currency: "CNY", Amount: 9500, Count: 3},
+		{Month: "2026-10", Currency: "USD", Amount: 1200, Count: 1},
+	}, monthly)
+
+	_, err = expenses.Summarize(ctx, 1, "October")
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = expenses.Monthly(ctx, 1, "2026-10", "2026-09")
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = expenses.Monthly(ctx, 1, "2020-01", "2026-10")
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestCreateAllOrNone(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{}
+	expenses := New(store)
+	_, err := expenses.Create(ctx, 1, []*Expense{
+		{Amount: 4500, Category: CategoryFood, Date: "2026-10-16"},
+		{Amount: -1, Category: CategoryFood, Date: "2026-10-16"},
+	})
+	assert.ErrorIs(t, err, ErrInvalid)
+	assert.ErrorContains(t, err, "expense 2")
+	assert.Empty(t, store.expenses)
+
+	_, err = expenses.Create(ctx, 1, nil)
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestExpenseTools(t *testing.T) {
+	ctx := context.Background()
+	expenses := New(&fakeStore{})
+
+	out, err := expenses.AddToolFor(1).Run(ctx, `{"expenses": [
+		{"amount": 45, "category": "food", "note": "午饭", "date": "2026-10-16"},
+		{"amount": 12.5, "currency": "usd", "category": "transport", "note": "taxi", "date": "2026-10-15"}
+	]}`)
+	require.NoError(t, err)
+	assert.Equal(t, "✓ Recorded 2 expense(s):\n- #1 2026-10-16 45.00 CNY food · 午饭\n- #2 2026-10-15 12.50 USD transport · taxi", out)
+
+	_, err = expenses.AddToolFor(1).Run(ctx, `{"expenses": [{"amount": 10, "category": "gifts"}]}`)
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	out, err = expenses.SummaryToolFor(1).Run(ctx, `{"month": "2026-10"}`)
+	require.NoError(t, err)
+	assert.Contains(t, out, "- Total: 45.00 CNY (1 expenses)")
+	assert.Contains(t, out, "- transport: 12.50 USD (1)")
+	assert.Contains(t, out, "- #1 2026-10-16 45.00 CNY food · 午饭")
+
+	out, err = expenses.SummaryToolFor(1).Run(ctx, `{"month": "2026-09"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "No expenses recorded in 2026-09.", out)
+
+	var nilExpenses *Expenses
+	assert.Nil(t, nilExpenses.AddToolFor(1))
+}
@@ -0,0 +1,171 @@
+package expense
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DBStore persists expenses in the expense table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new expense store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const expenseColumns = "id, user_id, amount, currency, category, note, to_char(date, 'YYYY-MM-DD'), created_ts, updated_ts"
+
+// ListExpenses implements Store.
+func (s *DBStore) ListExpenses(ctx context.Context, find *Find) ([]*Expense, error) {
+	where, args := []string{"user_id = $1"}, []any{find.UserID}
+	if find.From != "" {
+		args = append(args, find.From)
+		where = append(where, fmt.Sprintf("date >= $%d::date", len(args)))
+	}
+	if find.To != "" {
+		args = append(args, find.To)
+		where = append(where, fmt.Sprintf("date <= $%d::date", len(args)))
+	}
+	if find.Category != "" {
+		args = append(args, find.Category)
+		where = append(where, fmt.Sprintf("category = $%d", len(args)))
+	}
+	args = append(args, find.Limit)
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(
+		"SELECT %s FROM expense WHERE %s ORDER BY date DESC, id DESC LIMIT $%d",
+		expenseColumns, strings.Join(where, " AND "), len(args)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expenses: %w", err)
+	}
+	defer rows.Close()
+
+	expenses := []*Expense{}
+	for rows.Next() {
+		expense, err := scanExpense(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan expense: %w", err)
+		}
+		expenses = append(expenses, expense)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list expenses: %w", err)
+	}
+	return expenses, nil
+}
+
+// GetExpense implements Store.
+func (s *DBStore) GetExpense(ctx context.Context, userID, id int32) (*Expense, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+expenseColumns+" FROM expense WHERE id = $1 AND user_id = $2", id, userID)
+	expense, err := scanExpense(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expense: %w", err)
+	}
+	return expense, nil
+}
+
+// CreateExpenses implements Store.
+func (s *DBStore) CreateExpenses(ctx context.Context, expenses []*Expense) ([]*Expense, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now().Unix()
+	created := make([]*Expense, 0, len(expenses))
+	for _, expense := range expenses {
+		row := tx.QueryRowContext(ctx, `
+			INSERT INTO expense (user_id, amount, currency, category, note, date, created_ts, updated_ts)
+			VALUES ($1, $2, $3, $4, $5, $6::date, $7, $7)
+			RETURNING `+expenseColumns,
+			expense.UserID, expense.Amount, expense.Currency, expense.Category, expense.Note, expense.Date, now)
+		saved, err := scanExpense(row)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create expense: %w", err)
+		}
+		created = append(created, saved)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit expenses: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateExpense implements Store.
+func (s *DBStore) UpdateExpense(ctx context.Context, expense *Expense) (*Expense, error) {
+	row := s.db.QueryRowContext(ctx, `
+		UPDATE expense SET amount = $3, currency = $4, category = $5, note = $6, date = $7::date, updated_ts = $8
+		WHERE id = $1 AND user_id = $2
+		RETURNING `+expenseColumns,
+		expense.ID, expense.UserID, expense.Amount, expense.Currency, expense.Category, expense.Note, expense.Date, time.Now().Unix())
+	updated, err := scanExpense(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update expense: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteExpense implements Store.
+func (s *DBStore) DeleteExpense(ctx context.Context, userID, id int32) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM expense WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete expense: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SumExpenses implements Store.
+func (s *DBStore) SumExpenses(ctx context.Context, userID int32, from, to string) ([]Total, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT to_char(date, 'YYYY-MM') AS month, currency, category, SUM(amount), COUNT(*)
+		FROM expense
+		WHERE user_id = $1 AND date >= $2::date AND date <= $3::date
+		GROUP BY month, currency, category
+		ORDER BY month, currency, category`,
+		userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum expenses: %w", err)
+	}
+	defer rows.Close()
+
+	totals := []Total{}
+	for rows.Next() {
+		var total Total
+		if err := rows.Scan(&total.Month, &total.Currency, &total.Category, &total.Amount, &total.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan expense total: %w", err)
+		}
+		totals = append(totals, total)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to sum expenses: %w", err)
+	}
+	return totals, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanExpense(row rowScanner) (*Expense, error) {
+	expense := &Expense{}
+	if err := row.Scan(&expense.ID, &expense.UserID, &expense.Amount, &expense.Currency, &expense.Category,
+		&expense.Note, &expense.Date, &expense.CreatedTs, &expense.UpdatedTs); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
@@ -0,0 +1,187 @@
+package expense
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	agents "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/internal/util"
+)
+
+// Names of the agent tools of the expense expert.
+const (
+	AddToolName     = "expense_add"
+	SummaryToolName = "expense_summary"
+)
+
+// AddInput is the input of the expense_add tool.
+type AddInput struct {
+	Expenses []ExpenseInput `json:"expenses"`
+	Timezone string         `json:"timezone"`
+}
+
+// ExpenseInput is an expense as the agent parsed it.
+type ExpenseInput struct {
+	// Amount is in major units, e.g. 45.5.
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+	Category string  `json:"category"`
+	Note     string  `json:"note"`
+	// Date is the day of the expense, YYYY-MM-DD; today when empty.
+	Date string `json:"date"`
+}
+
+// SummaryInput is the input of the expense_summary tool.
+type SummaryInput struct {
+	// Month is YYYY-MM; the current month when empty.
+	Month    string `json:"month"`
+	Timezone string `json:"timezone"`
+}
+
+// AddToolFor returns the expense_add tool of a user, or nil without expenses.
+func (e *Expenses) AddToolFor(userID int32) agents.ToolWithSchema {
+	if e == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		AddToolName,
+		"Record expenses of the user. Only call it with expenses the user confirmed: first show the parsed expenses with request_form, "+
+			"stop, and call expense_add with the confirmed values once the user submitted the form. "+
+			"Amounts are in major units (45.5 for 45.50), currencies are ISO 4217 codes (CNY when the user names none), "+
+			"categories are one of "+strings.Join(Categories, ", ")+".",
+		func(ctx context.Context, input string) (string, error) {
+			var in AddInput
+			if err := json.Unmarshal([]byte(input), &in); err != nil {
+				return "", fmt.Errorf("invalid input: %w", err)
+			}
+			now, err := util.NowIn(in.Timezone)
+			if err != nil {
+				return "", fmt.Errorf("%w: %w", ErrInvalid, err)
+			}
+			expenses := make([]*Expense, 0, len(in.Expenses))
+			for _, parsed := range in.Expenses {
+				expense := &Expense{
+					Currency: parsed.Currency,
+					Category: parsed.Category,
+					Note:     parsed.Note,
+					Date:     parsed.Date,
+				}
+				if expense.Currency == "" {
+					expense.Currency = DefaultCurrency
+				}
+				expense.Amount = ToMinor(parsed.Amount, expense.Currency)
+				if expense.Date == "" {
+					expense.Date = now.Format(time.DateOnly)
+				}
+				expenses = append(expenses, expense)
+			}
+			created, err := e.Create(ctx, userID, expenses)
+			if err != nil {
+				return "", err
+			}
+			lines := make([]string, 0, len(created))
+			for _, expense := range created {
+				lines = append(lines, "- "+formatExpense(expense))
+			}
+			return fmt.Sprintf("✓ Recorded %d expense(s):\n%s", len(created), strings.Join(lines, "\n")), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"expenses": map[string]any{
+					"type": "array",
+					"items": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"amount":   map[string]any{"type": "number", "description": "Amount in major units, e.g. 45.5"},
+							"currency": map[string]any{"type": "string", "description": "ISO 4217 code, e.g. CNY, USD; defaults to CNY"},
+							"category": map[string]any{"type": "string", "enum": Categories},
+							"note":     map[string]any{"type": "string", "description": "What the money was spent on, e.g. lunch"},
+							"date":     map[string]any{"type": "string", "description": "Day of the expense, YYYY-MM-DD; defaults to today"},
+						},
+						"required": []string{"amount", "category"},
+					},
+				},
+				"timezone": map[string]any{"type": "string", "description": "IANA time zone of the user, e.g. Asia/Shanghai"},
+			},
+			"required": []string{"expenses"},
+		},
+	)
+}
+
+// SummaryToolFor returns the expense_summary tool of a user, or nil without
+// expenses.
+func (e *Expenses) SummaryToolFor(userID int32) agents.ToolWithSchema {
+	if e == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		SummaryToolName,
+		"Sum up the expenses of the user in a month: totals by currency and by category, with the latest expenses.",
+		func(ctx context.Context, input string) (string, error) {
+			var in SummaryInput
+			if strings.TrimSpace(input) != "" {
+				if err := json.Unmarshal([]byte(input), &in); err != nil {
+					return "", fmt.Errorf("invalid input: %w", err)
+				}
+			}
+			now, err := util.NowIn(in.Timezone)
+			if err != nil {
+				return "", fmt.Errorf("%w: %w", ErrInvalid, err)
+			}
+			if in.Month == "" {
+				in.Month = now.Format("2006-01")
+			}
+			summary, err := e.Summarize(ctx, userID, in.Month)
+			if err != nil {
+				return "", err
+			}
+			if len(summary.Totals) == 0 {
+				return fmt.Sprintf("No expenses recorded in %s.", summary.Month), nil
+			}
+			var sb strings.Builder
+			fmt.Fprintf(&sb, "Expenses in %s:\n", summary.Month)
+			for _, total := range summary.Totals {
+				fmt.Fprintf(&sb, "- Total: %s (%d expenses)\n", FormatAmount(total.Amount, total.Currency), total.Count)
+			}
+			sb.WriteString("By category:\n")
+			for _, total := range summary.Categories {
+				fmt.Fprintf(&sb, "- %s: %s (%d)\n", total.Category, FormatAmount(total.Amount, total.Currency), total.Count)
+			}
+			start, _ := time.Parse("2006-01", summary.Month)
+			latest, err := e.List(ctx, &Find{
+				UserID: userID,
+				From:   start.Format(time.DateOnly),
+				To:     start.AddDate(0, 1, -1).Format(time.DateOnly),
+				Limit:  10,
+			})
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString("Latest:\n")
+			for _, expense := range latest {
+				fmt.Fprintf(&sb, "- %s\n", formatExpense(expense))
+			}
+			return strings.TrimRight(sb.String(), "\n"), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"month":    map[string]any{"type": "string", "description": "Month, YYYY-MM; defaults to the current month"},
+				"timezone": map[string]any{"type": "string", "description": "IANA time zone of the user, e.g. Asia/Shanghai"},
+			},
+		},
+	)
+}
+
+// formatExpense reports an expense to the agent.
+func formatExpense(expense *Expense) string {
+	line := fmt.Sprintf("#%d %s %s %s", expense.ID, expense.Date, FormatAmount(expense.Amount, expense.Currency), expense.Category)
+	if expense.Note != "" {
+		line += " · " + expense.Note
+	}
+	return line
+}
@@ -0,0 +1,298 @@
+// Package featureflag gates risky features of the instance, such as the
+// orchestrator, Evolution Mode or new experts, and rolls them out gradually
+// without code changes.
+//
+// Flags are declared by the features they gate, with a default state. Admins
+// override the default with a rule targeting everyone, some users, some roles
+// or a share of the users; a flag is instance-wide, the instance being the
+// workspace of its users. Rules are cached, and reloaded after a short delay
+// so that every replica follows the changes of admins.
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned for flags that are not registered.
+	ErrNotFound = errors.New("feature flag not found")
+	// ErrInvalid is returned for invalid rules.
+	ErrInvalid = errors.New("invalid feature flag rule")
+)
+
+// cacheTTL is how long the rules are used before they are reloaded.
+const cacheTTL = 30 * time.Second
+
+// Rule is the targeting of a flag set by admins, in place of its default.
+type Rule struct {
+	Key string `json:"key"`
+	// Enabled turns the flag on for everyone; the targeting below is then
+	// moot.
+	Enabled bool `json:"enabled"`
+	// Percent is the share of the users the flag is on for (0-100); users are
+	// bucketed by a hash, so a user keeps the feature as the share grows.
+	Percent int `json:"percent"`
+	// Users and Roles are the users and the roles (e.g. ADMIN) the flag is
+	// on for.
+	Users     []int32  `json:"users"`
+	Roles     []string `json:"roles"`
+	UpdatedTs int64    `json:"updated_ts"`
+}
+
+// Normalize deduplicates the targets of a rule.
+func (r *Rule) Normalize() {
+	users := make([]int32, 0, len(r.Users))
+	for _, id := range r.Users {
+		if !slices.Contains(users, id) {
+			users = append(users, id)
+		}
+	}
+	roles := make([]string, 0, len(r.Roles))
+	for _, role := range r.Roles {
+		if role = strings.ToUpper(strings.TrimSpace(role)); role != "" && !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	r.Users, r.Roles = users, roles
+}
+
+// Validate checks a normalized rule.
+func (r *Rule) Validate() error {
+	if r.Percent < 0 || r.Percent > 100 {
+		return fmt.Errorf("%w: percent must be between 0 and 100", ErrInvalid)
+	}
+	for _, id := range r.Users {
+		if id <= 0 {
+			return fmt.Errorf("%w: invalid user id %d", ErrInvalid, id)
+		}
+	}
+	return nil
+}
+
+// matches reports whether a rule turns its flag on for a user; role is
+// called only when the rule targets roles.
+func (r *Rule) matches(userID int32, role func() string) bool {
+	switch {
+	case r.Enabled:
+		return true
+	case userID > 0 && slices.Contains(r.Users, userID):
+		return true
+	case userID > 0 && bucket(r.Key, userID) < r.Percent:
+		return true
+	case len(r.Roles) > 0 && userID > 0:
+		return slices.Contains(r.Roles, role())
+	}
+	return false
+}
+
+// bucket returns the bucket (0-99) of a user for a flag.
+func bucket(key string, userID int32) int {
+	h := fnv.New32a()
+	h.Write([]byte(key + ":" + strconv.Itoa(int(userID))))
+	return int(h.Sum32() % 100)
+}
+
+// Flag is a registered flag with the rule of admins, if any.
+type Flag struct {
+	Key         string `json:"key"`
+	Description string `json:"description"`
+	// Default is the state of the flag without a rule.
+	Default bool `json:"default"`
+	// Rule is nil for flags in their default state.
+	Rule *Rule `json:"rule"`
+}
+
+// Store persists the rules.
+type Store interface {
+	ListRules(ctx context.Context) ([]*Rule, error)
+	// SaveRule creates or replaces the rule of a flag.
+	SaveRule(ctx context.Context, rule *Rule) error
+	// DeleteRule deletes the rule of a flag, if any.
+	DeleteRule(ctx context.Context, key string) error
+}
+
+// RoleResolver returns the role of a user, e.g. ADMIN.
+type RoleResolver func(ctx context.Context, userID int32) string
+
+// Flags holds the registered flags and the rules of admins.
+type Flags struct {
+	store Store
+	now   func() time.Time
+
+	mu       sync.RWMutex
+	flags    map[string]*Flag
+	rules    map[string]*Rule
+	loadedAt time.Time
+	role     RoleResolver
+}
+
+// New creates the flags.
+func New(store Store) *Flags {
+	return &Flags{store: store, now: time.Now, flags: make(map[string]*Flag), rules: make(map[string]*Rule)}
+}
+
+// SetRoleResolver sets the resolver of the roles of users, without which
+// rules targeting roles match nobody.
+func (f *Flags) SetRoleResolver(resolver RoleResolver) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.role = resolver
+}
+
+// Register declares a flag with its default state; registering it again
+// replaces its description and default.
+func (f *Flags) Register(key, description string, defaultOn bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.flags[key] = &Flag{Key: key, Description: description, Default: defaultOn}
+}
+
+// Enabled reports whether a flag is on for a user; unregistered flags are
+// off. A user ID of 0 stands for no user: only flags on for everyone are.
+func (f *Flags) Enabled(ctx context.Context, key string, userID int32) bool {
+	f.refresh(ctx, false)
+	f.mu.RLock()
+	flag, registered := f.flags[key]
+	rule := f.rules[key]
+	resolver := f.role
+	f.mu.RUnlock()
+	if !registered {
+		return false
+	}
+	if rule == nil {
+		return flag.Default
+	}
+	return rule.matches(userID, func() string {
+		if resolver == nil {
+			return ""
+		}
+		return resolver(ctx, userID)
+	})
+}
+
+// Evaluate returns the state of every flag for a user, e.g. for clients
+// hiding the features that are off.
+func (f *Flags) Evaluate(ctx context.Context, userID int32) map[string]bool {
+	f.mu.RLock()
+	keys := make([]string, 0, len(f.flags))
+	for key := range f.flags {
+		keys = append(keys, key)
+	}
+	f.mu.RUnlock()
+	states := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		states[key] = f.Enabled(ctx, key, userID)
+	}
+	return states
+}
+
+// refresh reloads the rules when they are stale, or when forced. Failed
+// loads keep the previous rules until the next attempt.
+func (f *Flags) refresh(ctx context.Context, force bool) error {
+	f.mu.RLock()
+	fresh := !f.loadedAt.IsZero() && f.now().Sub(f.loadedAt) < cacheTTL
+	f.mu.RUnlock()
+	if fresh && !force {
+		return nil
+	}
+	rules, err := f.store.ListRules(ctx)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.loadedAt = f.now()
+	if err != nil {
+		slog.Warn("failed to load feature flag rules", "error", err)
+		return err
+	}
+	f.rules = make(map[string]*Rule, len(rules))
+	for _, rule := range rules {
+		f.rules[rule.Key] = rule
+	}
+	return nil
+}
+
+// List returns the registered flags ordered by key, with their latest rules.
+func (f *Flags) List(ctx context.Context) ([]*Flag, error) {
+	if err := f.refresh(ctx, true); err != nil {
+		return nil, err
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	flags := make([]*Flag, 0, len(f.flags))
+	for key := range f.flags {
+		flags = append(flags, f.flag(key))
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Key < flags[j].Key })
+	return flags, nil
+}
+
+// Get returns a registered flag with its rule.
+func (f *Flags) Get(ctx context.Context, key string) (*Flag, error) {
+	if err := f.refresh(ctx, true); err != nil {
+		return nil, err
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if _, ok := f.flags[key]; !ok {
+		return nil, ErrNotFound
+	}
+	return f.flag(key), nil
+}
+
+// flag returns a copy of a registered flag with its rule; f.mu must be held.
+func (f *Flags) flag(key string) *Flag {
+	flag := *f.flags[key]
+	if rule, ok := f.rules[key]; ok {
+		copied := *rule
+		flag.Rule = &copied
+	}
+	return &flag
+}
+
+// SetRule overrides the default of a flag with a rule.
+func (f *Flags) SetRule(ctx context.Context, rule *Rule) (*Flag, error) {
+	rule.Normalize()
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	f.mu.RLock()
+	_, ok := f.flags[rule.Key]
+	f.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	rule.UpdatedTs = f.now().Unix()
+	if err := f.store.SaveRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules[rule.Key] = rule
+	return f.flag(rule.Key), nil
+}
+
+// Reset deletes the rule of a flag, returning it to its default.
+func (f *Flags) Reset(ctx context.Context, key string) (*Flag, error) {
+	f.mu.RLock()
+	_, ok := f.flags[key]
+	f.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if err := f.store.DeleteRule(ctx, key); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.rules, key)
+	return f.flag(key), nil
+}
@@ -0,0 +1,172 @@
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	rules map[string]*Rule
+	loads int
+	err   error
+}
+
+func (s *fakeStore) ListRules(_ context.Context) ([]*Rule, error) {
+	s.loads++
+	if s.err != nil {
+		return nil, s.err
+	}
+	var rules []*Rule
+	for _, rule := range s.rules {
+		copied := *rule
+		rules = append(rules, &copied)
+	}
+	return rules, nil
+}
+
+func (s *fakeStore) SaveRule(_ context.Context, rule *Rule) error {
+	if s.rules == nil {
+		s.rules = make(map[string]*Rule)
+	}
+	copied := *rule
+	s.rules[rule.Key] = &copied
+	return nil
+}
+
+func (s *fakeStore) DeleteRule(_ context.Context, key string) error {
+	delete(s.rules, key)
+	return nil
+}
+
+func newTestFlags(store Store) (*Flags, *time.Time) {
+	flags := New(store)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	flags.now = func() time.Time { return now }
+	return flags, &now
+}
+
+func TestFlags_Defaults(t *testing.T) {
+	ctx := context.Background()
+	flags, _ := newTestFlags(&fakeStore{})
+	flags.Register("orchestrator", "Orchestrator", true)
+	flags.Register("expert.travel", "Travel expert", false)
+
+	assert.True(t, flags.Enabled(ctx, "orchestrator", 1))
+	assert.False(t, flags.Enabled(ctx, "expert.travel", 1))
+	assert.False(t, flags.Enabled(ctx, "unknown", 1))
+	assert.Equal(t, map[string]bool{"orchestrator": true, "expert.travel": false}, flags.Evaluate(ctx, 1))
+}
+
+func TestFlags_Targeting(t *testing.T) {
+	ctx := context.Background()
+	flags, _ := newTestFlags(&fakeStore{})
+	flags.Register("evolution", "Evolution Mode", true)
+	flags.SetRoleResolver(func(_ context.Context, userID int32) string {
+		if userID == 1 {
+			return "HOST"
+		}
+		return "USER"
+	})
+
+	flag, err := flags.SetRule(ctx, &Rule{Key: "evolution", Users: []int32{7, 7}, Roles: []string{" host "}})
+	require.NoError(t, err)
+	assert.Equal(t, []int32{7}, flag.Rule.Users)
+	assert.Equal(t, []string{"HOST"}, flag.Rule.Roles)
+	assert.True(t, flag.Default)
+
+	assert.True(t, flags.Enabled(ctx, "evolution", 1))
+	assert.True(t, flags.Enabled(ctx, "evolution", 7))
+	assert.False(t, flags.Enabled(ctx, "evolution", 2))
+	assert.False(t, flags.Enabled(ctx, "evolution", 0))
+
+	_, err = flags.SetRule(ctx, &Rule{Key: "evolution", Enabled: true})
+	require.NoError(t, err)
+	assert.True(t, flags.Enabled(ctx, "evolution", 2))
+
+	// Resetting returns the flag to its default
+	flag, err = flags.Reset(ctx, "evolution")
+	require.NoError(t, err)
+	assert.Nil(t, flag.Rule)
+	assert.True(t, flags.Enabled(ctx, "evolution", 2))
+}
+
+func TestFlags_PercentRollout(t *testing.T) {
+	ctx := context.Background()
+	flags, _ := newTestFlags(&fakeStore{})
+	flags.Register("orchestrator", "Orchestrator", false)
+
+	count := func() (int, map[int32]bool) {
+		on := make(map[int32]bool)
+		for id := int32(1); id <= 1000; id++ {
+			if flags.Enabled(ctx, "orchestrator", id) {
+				on[id] = true
+			}
+		}
+		return len(on), on
+	}
+
+	_, err := flags.SetRule(ctx, &Rule{Key: "orchestrator", Percent: 20})
+	require.NoError(t, err)
+	n20, on20 := count()
+	assert.InDelta(t, 200, n20, 60)
+
+	// Growing the share keeps the users who had the feature
+	_, err = flags.SetRule(ctx, &Rule{Key: "orchestrator", Percent: 50})
+	require.NoError(t, err)
+	n50, on50 := count()
+	assert.Greater(t, n50, n20)
+	for id := range on20 {
+		assert.True(t, on50[id], id)
+	}
+}
+
+func TestFlags_ReloadsStaleRules(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{}
+	flags, now := newTestFlags(store)
+	flags.Register("orchestrator", "Orchestrator", true)
+
+	assert.True(t, flags.Enabled(ctx, "orchestrator", 1))
+	assert.True(t, flags.Enabled(ctx, "orchestrator", 1))
+	assert.Equal(t, 1, store.loads)
+
+	// Another replica turns the flag off
+	store.rules = map[string]*Rule{"orchestrator": {Key: "orchestrator"}}
+	assert.True(t, flags.Enabled(ctx, "orchestrator", 1))
+	*now = now.Add(cacheTTL)
+	assert.False(t, flags.Enabled(ctx, "orchestrator", 1))
+
+	// Failed loads keep the previous rules
+	store.err = errors.New("connection refused")
+	*now = now.Add(cacheTTL)
+	assert.False(t, flags.Enabled(ctx, "orchestrator", 1))
+	_, err := flags.List(ctx)
+	assert.Error(t, err)
+}
+
+func TestFlags_Errors(t *testing.T) {
+	ctx := context.Background()
+	flags, _ := newTestFlags(&fakeStore{})
+	flags.Register("orchestrator", "Orchestrator", true)
+
+	_, err := flags.SetRule(ctx, &Rule{Key: "unknown"})
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = flags.Reset(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = flags.Get(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = flags.SetRule(ctx, &Rule{Key: "orchestrator", Percent: 101})
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = flags.SetRule(ctx, &Rule{Key: "orchestrator", Users: []int32{-1}})
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	list, err := flags.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Nil(t, list[0].Rule)
+}
@@ -0,0 +1,64 @@
+package featureflag
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// DBStore persists the rules in the feature_flag table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new feature flag store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// ListRules implements Store.
+func (s *DBStore) ListRules(ctx context.Context) ([]*Rule, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT key, enabled, percent, users, roles, updated_ts FROM feature_flag")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feature flag rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []*Rule{}
+	for rows.Next() {
+		rule := &Rule{}
+		var users pq.Int32Array
+		var roles pq.StringArray
+		if err := rows.Scan(&rule.Key, &rule.Enabled, &rule.Percent, &users, &roles, &rule.UpdatedTs); err != nil {
+			return nil, fmt.Errorf("failed to scan feature flag rule: %w", err)
+		}
+		rule.Users, rule.Roles = []int32(users), []string(roles)
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list feature flag rules: %w", err)
+	}
+	return rules, nil
+}
+
+// SaveRule implements Store.
+func (s *DBStore) SaveRule(ctx context.Context, rule *Rule) error {
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO feature_flag (key, enabled, percent, users, roles, updated_ts) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (key) DO UPDATE SET enabled = EXCLUDED.enabled, percent = EXCLUDED.percent,
+			users = EXCLUDED.users, roles = EXCLUDED.roles, updated_ts = EXCLUDED.updated_ts`,
+		rule.Key, rule.Enabled, rule.Percent, pq.Array(rule.Users), pq.Array(rule.Roles), rule.UpdatedTs); err != nil {
+		return fmt.Errorf("failed to save feature flag rule: %w", err)
+	}
+	return nil
+}
+
+// DeleteRule implements Store.
+func (s *DBStore) DeleteRule(ctx context.Context, key string) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM feature_flag WHERE key = $1", key); err != nil {
+		return fmt.Errorf("failed to delete feature flag rule: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,470 @@
+// Package feed subscribes users to RSS and Atom feeds and turns their new
+// entries into read-later items.
+//
+// Subscriptions are polled by the feed-fetch job, with conditional requests
+// and a backoff for failing feeds; new entries are stored as pending. The
+// feed-ingest job then summarizes and tags each pending entry with the
+// language model, when available, and saves it as a memo queued in the
+// read-later queue of its user, so that entries are ranked against the
+// user's interests like captured pages.
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// Tag is the tag of the memos of feed entries.
+	Tag = "feed"
+	// MaxSubscriptions bounds the subscriptions of a user.
+	MaxSubscriptions = 100
+	// DefaultInterval is the time between two fetches of a feed.
+	DefaultInterval = time.Hour
+	// maxBackoff bounds the time between two fetches of a failing feed.
+	maxBackoff = 24 * time.Hour
+	// initialEntries is the number of entries taken on subscribing, the
+	// newest, so that subscribing does not flood the queue.
+	initialEntries = 5
+	// maxNewEntries bounds the entries taken per fetch, the newest.
+	maxNewEntries = 20
+	// fetchBatchSize bounds the feeds fetched per run.
+	fetchBatchSize = 50
+	// ingestBatchSize bounds the entries ingested per run.
+	ingestBatchSize = 20
+	// maxEnrichAttempts is the number of failed enrichments after which an
+	// entry is saved without summary and tags.
+	maxEnrichAttempts = 3
+	// maxSummaryRunes bounds the summaries of entries.
+	maxSummaryRunes = 2000
+	// maxMemoSummaryRunes bounds the feed summary quoted in memos when the
+	// entry was not summarized.
+	maxMemoSummaryRunes = 500
+	// maxTitleRunes bounds the titles of feeds and entries.
+	maxTitleRunes = 300
+)
+
+var (
+	// ErrInvalid is returned for invalid URLs and settings, and for documents
+	// that are not feeds.
+	ErrInvalid = errors.New("invalid feed")
+	// ErrNotFound is returned for unknown subscriptions.
+	ErrNotFound = errors.New("feed subscription not found")
+	// ErrExists is returned when subscribing twice to a feed.
+	ErrExists = errors.New("already subscribed to this feed")
+	// ErrLimit is returned when a user has MaxSubscriptions subscriptions.
+	ErrLimit = fmt.Errorf("at most %d feed subscriptions", MaxSubscriptions)
+	// ErrFetch is returned when a feed cannot be fetched.
+	ErrFetch = errors.New("failed to fetch feed")
+)
+
+// Subscription is the subscription of a user to a feed.
+type Subscription struct {
+	ID     int32  `json:"id"`
+	UserID int32  `json:"-"`
+	URL    string `json:"url"`
+	Title  string `json:"title"`
+	// SiteURL is the link of the feed to its site.
+	SiteURL string `json:"site_url"`
+	Enabled bool   `json:"enabled"`
+	// ETag and LastModified make the fetches conditional.
+	ETag          string `json:"-"`
+	LastModified  string `json:"-"`
+	LastFetchedTs int64  `json:"last_fetched_ts"`
+	NextFetchTs   int64  `json:"next_fetch_ts"`
+	// Failures is the number of consecutive failed fetches.
+	Failures  int    `json:"failures"`
+	LastError string `json:"last_error"`
+	CreatedTs int64  `json:"created_ts"`
+}
+
+// EntryStatus is the state of an entry.
+type EntryStatus string
+
+const (
+	// EntryPending is an entry waiting to be ingested.
+	EntryPending EntryStatus = "PENDING"
+	// EntryIngested is an entry saved as a memo.
+	EntryIngested EntryStatus = "INGESTED"
+)
+
+// Entry is an entry of a feed.
+type Entry struct {
+	ID             int32  `json:"id"`
+	SubscriptionID int32  `json:"subscription_id"`
+	UserID         int32  `json:"-"`
+	GUID           string `json:"-"`
+	Title          string `json:"title"`
+	URL            string `json:"url"`
+	// Summary is the text of the entry in the feed.
+	Summary     string      `json:"-"`
+	PublishedTs int64       `json:"published_ts"`
+	Status      EntryStatus `json:"status"`
+	// Attempts counts the failed enrichments.
+	Attempts int `json:"-"`
+	// AISummary and Tags are set by the enricher.
+	AISummary string   `json:"ai_summary"`
+	Tags      []string `json:"tags"`
+	MemoUID   string   `json:"memo_uid"`
+	CreatedTs int64    `json:"created_ts"`
+	// FeedTitle is the title of the feed, for the memo.
+	FeedTitle string `json:"-"`
+}
+
+// Store persists the subscriptions and their entries.
+type Store interface {
+	// CreateSubscription returns ErrExists when the user is subscribed to
+	// the URL already.
+	CreateSubscription(ctx context.Context, sub *Subscription) (*Subscription, error)
+	GetSubscription(ctx context.Context, userID, id int32) (*Subscription, error)
+	ListSubscriptions(ctx context.Context, userID int32) ([]*Subscription, error)
+	// UpdateSubscription saves the title and enabled flag of a subscription.
+	UpdateSubscription(ctx context.Context, sub *Subscription) (*Subscription, error)
+	DeleteSubscription(ctx context.Context, userID, id int32) error
+	// ListDueSubscriptions returns up to limit enabled subscriptions due at
+	// now, the most overdue first.
+	ListDueSubscriptions(ctx context.Context, now time.Time, limit int) ([]*Subscription, error)
+	// SaveFetch saves the fetch state of a subscription.
+	SaveFetch(ctx context.Context, sub *Subscription) error
+
+	// AddEntries stores the entries a subscription does not have yet, by
+	// GUID, and returns how many it stored.
+	AddEntries(ctx context.Context, sub *Subscription, entries []*Entry) (int, error)
+	ListPendingEntries(ctx context.Context, limit int) ([]*Entry, error)
+	ListEntries(ctx context.Context, userID, subscriptionID int32, limit int) ([]*Entry, error)
+	// SaveEntry saves the status, attempts, enrichment and memo of an entry.
+	SaveEntry(ctx context.Context, entry *Entry) error
+}
+
+// Enrichment is the summary and tags of an entry.
+type Enrichment struct {
+	Summary string   `json:"summary"`
+	Tags    []string `json:"tags"`
+}
+
+// Enricher summarizes and tags entries.
+type Enricher interface {
+	Enrich(ctx context.Context, entry *Entry) (*Enrichment, error)
+}
+
+// Saver saves an entry as a memo of its user queued for reading later, and
+// returns the UID of the memo.
+type Saver interface {
+	Save(ctx context.Context, entry *Entry) (string, error)
+}
+
+// Feeds manages the subscriptions of all users.
+type Feeds struct {
+	store    Store
+	saver    Saver
+	enricher Enricher
+	fetcher  *fetcher
+}
+
+// New creates the feeds.
+func New(store Store, saver Saver) *Feeds {
+	return &Feeds{store: store, saver: saver, fetcher: newFetcher()}
+}
+
+// SetEnricher sets the enricher of the entries, once the LLM is available.
+func (f *Feeds) SetEnricher(enricher Enricher) {
+	f.enricher = enricher
+}
+
+// Subscribe subscribes a user to a feed, which is fetched to check it and
+// find its title. The newest entries are taken at once.
+func (f *Feeds) Subscribe(ctx context.Context, userID int32, rawURL string) (*Subscription, error) {
+	u, err := parseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	subs, err := f.store.ListSubscriptions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(subs) >= MaxSubscriptions {
+		return nil, ErrLimit
+	}
+	result, err := f.fetcher.fetch(ctx, u.String(), "", "")
+	if err != nil {
+		return nil, err
+	}
+	parsed := result.feed
+	now := time.Now()
+	sub, err := f.store.CreateSubscription(ctx, &Subscription{
+		UserID:        userID,
+		URL:           u.String(),
+		Title:         truncate(parsed.Title, maxTitleRunes),
+		SiteURL:       parsed.SiteURL,
+		Enabled:       true,
+		ETag:          result.etag,
+		LastModified:  result.lastModified,
+		LastFetchedTs: now.Unix(),
+		NextFetchTs:   now.Add(DefaultInterval).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.store.AddEntries(ctx, sub, newest(parsed.Entries, initialEntries)); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Subscriptions returns the subscriptions of a user.
+func (f *Feeds) Subscriptions(ctx context.Context, userID int32) ([]*Subscription, error) {
+	return f.store.ListSubscriptions(ctx, userID)
+}
+
+// Update renames a subscription, or pauses or resumes it; a resumed
+// subscription is fetched at the next run.
+func (f *Feeds) Update(ctx context.Context, userID, id int32, title *string, enabled *bool) (*Subscription, error) {
+	sub, err := f.store.GetSubscription(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if title != nil {
+		sub.Title = strings.TrimSpace(*title)
+		if sub.Title == "" || utf8.RuneCountInString(sub.Title) > maxTitleRunes {
+			return nil, fmt.Errorf("%w: the title must have 1 to %d characters", ErrInvalid, maxTitleRunes)
+		}
+	}
+	if enabled != nil {
+		sub.Enabled = *enabled
+	}
+	return f.store.UpdateSubscription(ctx, sub)
+}
+
+// Unsubscribe deletes a subscription with its entries; their memos are kept.
+func (f *Feeds) Unsubscribe(ctx context.Context, userID, id int32) error {
+	return f.store.DeleteSubscription(ctx, userID, id)
+}
+
+// Entries returns the newest entries of a subscription.
+func (f *Feeds) Entries(ctx context.Context, userID, id int32, limit int) ([]*Entry, error) {
+	if _, err := f.store.GetSubscription(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	return f.store.ListEntries(ctx, userID, id, limit)
+}
+
+// Refresh fetches a subscription now and returns the number of new entries.
+func (f *Feeds) Refresh(ctx context.Context, userID, id int32) (int, error) {
+	sub, err := f.store.GetSubscription(ctx, userID, id)
+	if err != nil {
+		return 0, err
+	}
+	return f.fetchSubscription(ctx, sub, time.Now())
+}
+
+// FetchDue fetches the subscriptions due now; failing feeds are retried with
+// an exponential backoff.
+func (f *Feeds) FetchDue(ctx context.Context) error {
+	now := time.Now()
+	subs, err := f.store.ListDueSubscriptions(ctx, now, fetchBatchSize)
+	if err != nil {
+		return err
+	}
+	added, failed := 0, 0
+	for _, sub := range subs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		n, err := f.fetchSubscription(ctx, sub, now)
+		if err != nil {
+			failed++
+			slog.Warn("feed: failed to fetch", "subscription", sub.ID, "url", sub.URL, "error", err)
+			continue
+		}
+		added += n
+	}
+	if added > 0 || failed > 0 {
+		slog.Info("feeds fetched", "feeds", len(subs), "entries", added, "failed", failed)
+	}
+	return nil
+}
+
+// fetchSubscription fetches a feed, stores its new entries and saves the
+// fetch state, with the error of a failed fetch.
+func (f *Feeds) fetchSubscription(ctx context.Context, sub *Subscription, now time.Time) (int, error) {
+	result, fetchErr := f.fetcher.fetch(ctx, sub.URL, sub.ETag, sub.LastModified)
+	sub.LastFetchedTs = now.Unix()
+	added := 0
+	if fetchErr == nil && result.feed != nil {
+		var err error
+		if added, err = f.store.AddEntries(ctx, sub, newest(result.feed.Entries, maxNewEntries)); err != nil {
+			return 0, err
+		}
+		if sub.SiteURL == "" {
+			sub.SiteURL = result.feed.SiteURL
+		}
+	}
+	if fetchErr != nil {
+		sub.Failures++
+		sub.LastError = truncate(fetchErr.Error(), 500)
+		sub.NextFetchTs = now.Add(backoff(sub.Failures)).Unix()
+	} else {
+		sub.Failures, sub.LastError = 0, ""
+		sub.ETag, sub.LastModified = result.etag, result.lastModified
+		sub.NextFetchTs = now.Add(DefaultInterval).Unix()
+	}
+	if err := f.store.SaveFetch(ctx, sub); err != nil {
+		return 0, err
+	}
+	return added, fetchErr
+}
+
+// backoff returns the time before fetching a feed again after failures.
+func backoff(failures int) time.Duration {
+	delay := DefaultInterval
+	for i := 1; i < failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// IngestPending saves a batch of pending entries as read-later memos,
+// summarized and tagged first when an enricher is set. Entries whose
+// enrichment keeps failing are saved without it.
+func (f *Feeds) IngestPending(ctx context.Context) error {
+	entries, err := f.store.ListPendingEntries(ctx, ingestBatchSize)
+	if err != nil {
+		return err
+	}
+	ingested := 0
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if f.enricher != nil && entry.Attempts < maxEnrichAttempts {
+			enrichment, err := f.enricher.Enrich(ctx, entry)
+			if err != nil {
+				entry.Attempts++
+				slog.Warn("feed: failed to enrich entry", "entry", entry.ID, "attempts", entry.Attempts, "error", err)
+				if entry.Attempts < maxEnrichAttempts {
+					if err := f.store.SaveEntry(ctx, entry); err != nil {
+						return err
+					}
+					continue
+				}
+			} else {
+				entry.AISummary = truncate(strings.TrimSpace(enrichment.Summary), maxSummaryRunes)
+				entry.Tags = NormalizeTags(enrichment.Tags)
+			}
+		}
+		memoUID, err := f.saver.Save(ctx, entry)
+		if err != nil {
+			slog.Warn("feed: failed to save entry", "entry", entry.ID, "error", err)
+			continue
+		}
+		entry.MemoUID, entry.Status = memoUID, EntryIngested
+		if err := f.store.SaveEntry(ctx, entry); err != nil {
+			return err
+		}
+		ingested++
+	}
+	if ingested > 0 {
+		slog.Info("feed entries ingested", "entries", ingested)
+	}
+	return nil
+}
+
+// Content returns the memo of an entry: its title linked to the entry, its
+// summary, the feed and the tags.
+func (e *Entry) Content() string {
+	var b strings.Builder
+	title := strings.NewReplacer("[", "(", "]", ")").Replace(e.Title)
+	if title == "" {
+		title = e.URL
+	}
+	if e.URL != "" {
+		fmt.Fprintf(&b, "## [%s](%s)\n\n", title, e.URL)
+	} else {
+		fmt.Fprintf(&b, "## %s\n\n", title)
+	}
+	summary := e.AISummary
+	if summary == "" {
+		summary = truncate(e.Summary, maxMemoSummaryRunes)
+	}
+	if summary != "" {
+		b.WriteString(summary + "\n\n")
+	}
+	if e.FeedTitle != "" {
+		fmt.Fprintf(&b, "— %s\n\n", e.FeedTitle)
+	}
+	tags := []string{"#" + Tag}
+	for _, tag := range e.Tags {
+		tags = append(tags, "#"+tag)
+	}
+	b.WriteString(strings.Join(tags, " "))
+	return b.String()
+}
+
+// maxTags bounds the tags given to an entry.
+const maxTags = 3
+
+// NormalizeTags keeps up to maxTags valid memo tags: without "#", spaces and
+// punctuation other than "-" and "_", lowercase and distinct.
+func NormalizeTags(tags []string) []string {
+	var result []string
+	seen := map[string]bool{Tag: true}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tag), "#")))
+		tag = strings.Map(func(r rune) rune {
+			switch {
+			case r == ' ':
+				return '-'
+			case r == '-' || r == '_' || r == '/':
+				return r
+			case r < 0x80 && !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9'):
+				return -1
+			}
+			return r
+		}, tag)
+		tag = strings.Trim(tag, "-_/")
+		if tag == "" || seen[tag] || utf8.RuneCountInString(tag) > 32 {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+		if len(result) == maxTags {
+			break
+		}
+	}
+	return result
+}
+
+// newest returns the n newest entries of a feed, which lists them newest
+// first unless they are dated otherwise.
+func newest(entries []*Entry, n int) []*Entry {
+	if len(entries) <= n {
+		return entries
+	}
+	dated := true
+	for _, entry := range entries {
+		if entry.PublishedTs == 0 {
+			dated = false
+			break
+		}
+	}
+	if !dated {
+		return entries[:n]
+	}
+	sorted := append([]*Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].PublishedTs > sorted[j].PublishedTs })
+	return sorted[:n]
+}
+
+// truncate shortens a text to limit runes.
+func truncate(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	return string([]rune(text)[:limit]) + "…"
+}
@@ -0,0 +1,407 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rssDoc = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+<channel>
+  <title>Go Blog</title>
+  <link>https://go.dev/blog/</link>
+  <item>
+    <title>Range over functions</title>
+    <link>/blog/range-functions</link>
+    <guid>range-functions</guid>
+    <pubDate>Tue, 20 Aug 2024 10:00:00 +0000</pubDate>
+    <description>&lt;p&gt;Iterators &amp;amp; &lt;b&gt;generics&lt;/b&gt;.&lt;/p&gt;</description>
+  </item>
+  <item>
+    <title>Go 1.23</title>
+    <link>https://go.dev/blog/go1.23</link>
+    <pubDate>Tue, 13 Aug 2024 10:00:00 +0000</pubDate>
+  </item>
+</channel>
+</rss>`
+
+const atomDoc = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title type="text">Example Atom</title>
+  <link rel="alternate" href="https://example.com/"/>
+  <link rel="self" href="https://example.com/atom.xml"/>
+  <entry>
+    <title>First post</title>
+    <id>urn:uuid:1</id>
+    <link rel="alternate" href="https://example.com/first"/>
+    <updated>2024-08-20T10:00:00Z</updated>
+    <summary type="html">&lt;p&gt;Hello world&lt;/p&gt;</summary>
+  </entry>
+</feed>`
+
+const rdfDoc = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel rdf:about="https://example.org/">
+    <title>RDF Feed</title>
+    <link>https://example.org/</link>
+  </channel>
+  <item rdf:about="https://example.org/a">
+    <title>Item A</title>
+    <link>https://example.org/a</link>
+    <dc:date>2024-08-20T10:00:00Z</dc:date>
+  </item>
+</rdf:RDF>`
+
+func TestParse(t *testing.T) {
+	base, _ := url.Parse("https://go.dev/blog/feed.atom")
+
+	t.Run("RSS 2.0", func(t *testing.T) {
+		parsed, err := parse([]byte(rssDoc), base)
+		require.NoError(t, err)
+		assert.Equal(t, "Go Blog", parsed.Title)
+		assert.Equal(t, "https://go.dev/blog/", parsed.SiteURL)
+		require.Len(t, parsed.Entries, 2)
+		entry := parsed.Entries[0]
+		assert.Equal(t, "range-functions", entry.GUID)
+		assert.Equal(t, "https://go.dev/blog/range-functions", entry.URL)
+		assert.Equal(t, "Iterators & generics.", entry.Summary)
+		assert.Equal(t, time.Date(2024, 8, 20, 10, 0, 0, 0, time.UTC).Unix(), entry.PublishedTs)
+		assert.Equal(t, "https://go.dev/blog/go1.23", parsed.Entries[1].GUID, "the link identifies entries without a guid")
+	})
+
+	t.Run("Atom", func(t *testing.T) {
+		parsed, err := parse([]byte(atomDoc), base)
+		require.NoError(t, err)
+		assert.Equal(t, "Example Atom", parsed.Title)
+		assert.Equal(t, "https://example.com/", parsed.SiteURL)
+		require.Len(t, parsed.Entries, 1)
+		assert.Equal(t, "urn:uuid:1", parsed.Entries[0].GUID)
+		assert.Equal(t, "https://example.com/first", parsed.Entries[0].URL)
+		assert.Equal(t, "Hello world", parsed.Entries[0].Summary)
+	})
+
+	t.Run("RSS 1.0", func(t *testing.T) {
+		parsed, err := parse([]byte(rdfDoc), base)
+		require.NoError(t, err)
+		assert.Equal(t, "RDF Feed", parsed.Title)
+		require.Len(t, parsed.Entries, 1)
+		assert.Equal(t, "https://example.org/a", parsed.Entries[0].URL)
+		assert.NotZero(t, parsed.Entries[0].PublishedTs)
+	})
+
+	t.Run("not a feed", func(t *testing.T) {
+		_, err := parse([]byte("<html><body>hi</body></html>"), base)
+		assert.ErrorIs(t, err, ErrInvalid)
+	})
+}
+
+func TestNormalizeTags(t *testing.T) {
+	assert.Equal(t, []string{"golang", "机器学习", "open-source"},
+		NormalizeTags([]string{"#Golang", "feed", "golang", " 机器学习 ", "Open Source", "extra"}))
+	assert.Empty(t, NormalizeTags([]string{"#", "!!"}))
+}
+
+func TestEntryContent(t *testing.T) {
+	entry := &Entry{
+		Title:     "Go [1.23]",
+		URL:       "https://go.dev/blog/go1.23",
+		Summary:   "raw summary",
+		AISummary: "AI summary.",
+		FeedTitle: "Go Blog",
+		Tags:      []string{"golang"},
+	}
+	assert.Equal(t, "## [Go (1.23)](https://go.dev/blog/go1.23)\n\nAI summary.\n\n— Go Blog\n\n#feed #golang", entry.Content())
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, DefaultInterval, backoff(1))
+	assert.Equal(t, 4*DefaultInterval, backoff(3))
+	assert.Equal(t, maxBackoff, backoff(20))
+}
+
+func TestParseEnrichment(t *testing.T) {
+	enrichment, err := parseEnrichment("```json\n{\"summary\": \"讲迭代器。\", \"tags\": [\"golang\"]}\n```")
+	require.NoError(t, err)
+	assert.Equal(t, "讲迭代器。", enrichment.Summary)
+	assert.Equal(t, []string{"golang"}, enrichment.Tags)
+
+	_, err = parseEnrichment("not json")
+	assert.Error(t, err)
+}
+
+func TestFeeds(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	body, etag := rssDoc, `"v1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, UserAgent, r.UserAgent())
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	store := newMemStore()
+	saver := &memSaver{}
+	feeds := New(store, saver)
+	feeds.fetcher.client = server.Client()
+
+	t.Run("private addresses are denied", func(t *testing.T) {
+		_, err := New(store, saver).Subscribe(ctx, 1, server.URL)
+		assert.Error(t, err)
+	})
+
+	_, err := feeds.Subscribe(ctx, 1, "ftp://example.com/feed")
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	sub, err := feeds.Subscribe(ctx, 1, server.URL+"/feed#top")
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/feed", sub.URL)
+	assert.Equal(t, "Go Blog", sub.Title)
+	assert.Equal(t, `"v1"`, sub.ETag)
+	entries, err := feeds.Entries(ctx, 1, sub.ID, 10)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	_, err = feeds.Subscribe(ctx, 1, server.URL+"/feed")
+	assert.ErrorIs(t, err, ErrExists)
+	_, err = feeds.Entries(ctx, 2, sub.ID, 10)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	t.Run("not modified feeds are skipped", func(t *testing.T) {
+		added, err := feeds.Refresh(ctx, 1, sub.ID)
+		require.NoError(t, err)
+		assert.Zero(t, added)
+	})
+
+	t.Run("new entries are added once", func(t *testing.T) {
+		mu.Lock()
+		body = strings.Replace(rssDoc, "<item>", "<item><title>Newer</title><link>https://go.dev/blog/newer</link></item><item>", 1)
+		etag = `"v2"`
+		mu.Unlock()
+		added, err := feeds.Refresh(ctx, 1, sub.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, added)
+		saved, _ := store.GetSubscription(ctx, 1, sub.ID)
+		assert.Equal(t, `"v2"`, saved.ETag)
+	})
+
+	t.Run("failing feeds back off", func(t *testing.T) {
+		mu.Lock()
+		body, etag = "garbage", `"v3"`
+		mu.Unlock()
+		store.subs[sub.ID].NextFetchTs = 0
+		require.NoError(t, feeds.FetchDue(ctx))
+		saved, _ := store.GetSubscription(ctx, 1, sub.ID)
+		assert.Equal(t, 1, saved.Failures)
+		assert.NotEmpty(t, saved.LastError)
+		assert.Greater(t, saved.NextFetchTs, time.Now().Unix())
+	})
+
+	t.Run("entries are enriched and saved", func(t *testing.T) {
+		enricher := &fakeEnricher{fail: map[string]int{"https://go.dev/blog/go1.23": maxEnrichAttempts}}
+		feeds.SetEnricher(enricher)
+		for range maxEnrichAttempts {
+			require.NoError(t, feeds.IngestPending(ctx))
+		}
+		require.Len(t, saver.entries, 3)
+		contents := map[string]string{}
+		for _, entry := range saver.entries {
+			contents[entry.URL] = entry.Content()
+		}
+		assert.Contains(t, contents["https://go.dev/blog/newer"], "Summary of Newer")
+		assert.Contains(t, contents["https://go.dev/blog/newer"], "#feed #golang")
+		assert.Contains(t, contents["https://go.dev/blog/newer"], "— Go Blog")
+		assert.NotContains(t, contents["https://go.dev/blog/go1.23"], "#golang", "entries failing enrichment are saved without it")
+
+		entries, _ := feeds.Entries(ctx, 1, sub.ID, 10)
+		for _, entry := range entries {
+			assert.Equal(t, EntryIngested, entry.Status)
+			assert.NotEmpty(t, entry.MemoUID)
+		}
+		require.NoError(t, feeds.IngestPending(ctx))
+		assert.Len(t, saver.entries, 3)
+	})
+
+	t.Run("paused and deleted subscriptions", func(t *testing.T) {
+		enabled := false
+		updated, err := feeds.Update(ctx, 1, sub.ID, nil, &enabled)
+		require.NoError(t, err)
+		assert.False(t, updated.Enabled)
+		require.NoError(t, feeds.Unsubscribe(ctx, 1, sub.ID))
+		assert.ErrorIs(t, feeds.Unsubscribe(ctx, 1, sub.ID), ErrNotFound)
+	})
+}
+
+type fakeEnricher struct {
+	fail map[string]int
+}
+
+func (e *fakeEnricher) Enrich(_ context.Context, entry *Entry) (*Enrichment, error) {
+	if e.fail[entry.URL] > 0 {
+		e.fail[entry.URL]--
+		return nil, errors.New("model unavailable")
+	}
+	return &Enrichment{Summary: "Summary of " + entry.Title, Tags: []string{"Golang", "feed"}}, nil
+}
+
+type memSaver struct {
+	entries []*Entry
+}
+
+func (s *memSaver) Save(_ context.Context, entry *Entry) (string, error) {
+	copied := *entry
+	s.entries = append(s.entries, &copied)
+	return "memo-" + entry.GUID, nil
+}
+
+type memStore struct {
+	subs    map[int32]*Subscription
+	entries []*Entry
+	nextID  int32
+}
+
+func newMemStore() *memStore {
+	return &memStore{subs: map[int32]*Subscription{}}
+}
+
+func (s *memStore) CreateSubscription(_ context.Context, sub *Subscription) (*Subscription, error) {
+	for _, existing := range s.subs {
+		if existing.UserID == sub.UserID && existing.URL == sub.URL {
+			return nil, ErrExists
+		}
+	}
+	s.nextID++
+	created := *sub
+	created.ID = s.nextID
+	s.subs[created.ID] = &created
+	copied := created
+	return &copied, nil
+}
+
+func (s *memStore) GetSubscription(_ context.Context, userID, id int32) (*Subscription, error) {
+	sub, ok := s.subs[id]
+	if !ok || sub.UserID != userID {
+		return nil, ErrNotFound
+	}
+	copied := *sub
+	return &copied, nil
+}
+
+func (s *memStore) ListSubscriptions(_ context.Context, userID int32) ([]*Subscription, error) {
+	subs := []*Subscription{}
+	for _, sub := range s.subs {
+		if sub.UserID == userID {
+			copied := *sub
+			subs = append(subs, &copied)
+		}
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].ID < subs[j].ID })
+	return subs, nil
+}
+
+func (s *memStore) UpdateSubscription(_ context.Context, sub *Subscription) (*Subscription, error) {
+	existing, ok := s.subs[sub.ID]
+	if !ok || existing.UserID != sub.UserID {
+		return nil, ErrNotFound
+	}
+	existing.Title, existing.Enabled = sub.Title, sub.Enabled
+	copied := *existing
+	return &copied, nil
+}
+
+func (s *memStore) DeleteSubscription(_ context.Context, userID, id int32) error {
+	sub, ok := s.subs[id]
+	if !ok || sub.UserID != userID {
+		return ErrNotFound
+	}
+	delete(s.subs, id)
+	return nil
+}
+
+func (s *memStore) ListDueSubscriptions(_ context.Context, now time.Time, limit int) ([]*Subscription, error) {
+	subs := []*Subscription{}
+	for _, sub := range s.subs {
+		if sub.Enabled && sub.NextFetchTs <= now.Unix() && len(subs) < limit {
+			copied := *sub
+			subs = append(subs, &copied)
+		}
+	}
+	return subs, nil
+}
+
+func (s *memStore) SaveFetch(_ context.Context, sub *Subscription) error {
+	copied := *sub
+	s.subs[sub.ID] = &copied
+	return nil
+}
+
+func (s *memStore) AddEntries(_ context.Context, sub *Subscription, entries []*Entry) (int, error) {
+	added := 0
+outer:
+	for _, entry := range entries {
+		for _, existing := range s.entries {
+			if existing.SubscriptionID == sub.ID && existing.GUID == entry.GUID {
+				continue outer
+			}
+		}
+		copied := *entry
+		copied.ID = int32(len(s.entries) + 1)
+		copied.SubscriptionID, copied.UserID, copied.Status = sub.ID, sub.UserID, EntryPending
+		s.entries = append(s.entries, &copied)
+		added++
+	}
+	return added, nil
+}
+
+func (s *memStore) ListPendingEntries(_ context.Context, limit int) ([]*Entry, error) {
+	entries := []*Entry{}
+	for _, entry := range s.entries {
+		sub, ok := s.subs[entry.SubscriptionID]
+		if entry.Status == EntryPending && ok && sub.Enabled && len(entries) < limit {
+			copied := *entry
+			copied.FeedTitle = sub.Title
+			entries = append(entries, &copied)
+		}
+	}
+	return entries, nil
+}
+
+func (s *memStore) ListEntries(_ context.Context, userID, subscriptionID int32, limit int) ([]*Entry, error) {
+	entries := []*Entry{}
+	for _, entry := range s.entries {
+		if entry.UserID == userID && entry.SubscriptionID == subscriptionID && len(entries) < limit {
+			copied := *entry
+			entries = append(entries, &copied)
+		}
+	}
+	return entries, nil
+}
+
+func (s *memStore) SaveEntry(_ context.Context, entry *Entry) error {
+	for i, existing := range s.entries {
+		if existing.ID == entry.ID {
+			copied := *entry
+			s.entries[i] = &copied
+			return nil
+		}
+	}
+	return ErrNotFound
+}
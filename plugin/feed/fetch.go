@@ -0,0 +1,119 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hrygo/divinesense/plugin/netguard"
+)
+
+const (
+	// UserAgent identifies the fetches of feeds.
+	UserAgent = "DivineSenseFeedFetcher/1.0 (+https://github.com/hrygo/divinesense)"
+	// fetchTimeout bounds the fetch of a feed.
+	fetchTimeout = 20 * time.Second
+	// maxFeedBytes bounds the documents of feeds.
+	maxFeedBytes = 5 << 20
+	// maxRedirects bounds the redirects of a fetch.
+	maxRedirects = 5
+)
+
+// errDenied is returned when dialing private addresses.
+var errDenied = errors.New("address not allowed")
+
+// fetcher fetches feeds from public addresses.
+type fetcher struct {
+	client *http.Client
+}
+
+// fetchResult is a fetched feed; feed is nil when it was not modified.
+type fetchResult struct {
+	feed         *parsedFeed
+	etag         string
+	lastModified string
+}
+
+func newFetcher() *fetcher {
+	return &fetcher{client: &http.Client{
+		Transport: publicTransport(),
+		Timeout:   fetchTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return nil
+		},
+	}}
+}
+
+// fetch fetches and parses a feed, conditionally when etag or lastModified
+// is set.
+func (f *fetcher) fetch(ctx context.Context, feedURL, etag, lastModified string) (*fetchResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/rdf+xml, application/xml;q=0.9, text/xml;q=0.9, */*;q=0.5")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFetch, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		return &fetchResult{etag: etag, lastModified: lastModified}, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%w: HTTP %d", ErrFetch, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFetch, err)
+	}
+	if len(body) > maxFeedBytes {
+		return nil, fmt.Errorf("%w: the feed exceeds %d MiB", ErrInvalid, maxFeedBytes>>20)
+	}
+	parsed, err := parse(body, resp.Request.URL)
+	if err != nil {
+		return nil, err
+	}
+	return &fetchResult{
+		feed:         parsed,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}, nil
+}
+
+// parseURL parses the URL of a feed, dropping its fragment.
+func parseURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: the URL must be an absolute http(s) URL", ErrInvalid)
+	}
+	if u.User != nil {
+		return nil, fmt.Errorf("%w: the URL must not have credentials", ErrInvalid)
+	}
+	u.Fragment, u.RawFragment = "", ""
+	return u, nil
+}
+
+// publicTransport dials public addresses only, so that users cannot reach
+// the private network of the server through their feeds.
+func publicTransport() *http.Transport {
+	return netguard.Transport(fetchTimeout, errDenied)
+}
@@ -0,0 +1,286 @@
+package feed
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// parsedFeed is a parsed RSS or Atom document.
+type parsedFeed struct {
+	Title   string
+	SiteURL string
+	// Entries are in the order of the document.
+	Entries []*Entry
+}
+
+// RSS 2.0, and RSS 1.0 (RDF) whose items are siblings of the channel.
+type rssDocument struct {
+	Channel struct {
+		Title string    `xml:"title"`
+		Links []rssLink `xml:"link"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Items []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Text string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string    `xml:"title"`
+	Links       []rssLink `xml:"link"`
+	GUID        string    `xml:"guid"`
+	About       string    `xml:"about,attr"`
+	Description string    `xml:"description"`
+	Encoded     string    `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate     string    `xml:"pubDate"`
+	Date        string    `xml:"http://purl.org/dc/elements/1.1/ date"`
+}
+
+// Atom 1.0.
+type atomFeed struct {
+	Title   atomText    `xml:"title"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+type atomEntry struct {
+	Title     atomText   `xml:"title"`
+	Links     []atomLink `xml:"link"`
+	ID        string     `xml:"id"`
+	Summary   atomText   `xml:"summary"`
+	Content   atomText   `xml:"content"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+}
+
+// parse parses an RSS 2.0, RSS 1.0 or Atom document; relative links are
+// resolved against base.
+func parse(body []byte, base *url.URL) (*parsedFeed, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.CharsetReader = charset.NewReaderLabel
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	var root xml.StartElement
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: empty document", ErrInvalid)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			root = start
+			break
+		}
+	}
+
+	var parsed *parsedFeed
+	switch strings.ToLower(root.Name.Local) {
+	case "rss", "rdf":
+		doc := &rssDocument{}
+		if err := decoder.DecodeElement(doc, &root); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		parsed = parseRSS(doc, base)
+	case "feed":
+		doc := &atomFeed{}
+		if err := decoder.DecodeElement(doc, &root); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		parsed = parseAtom(doc, base)
+	default:
+		return nil, fmt.Errorf("%w: not an RSS or Atom document", ErrInvalid)
+	}
+	if parsed.Title == "" {
+		parsed.Title = base.Hostname()
+	}
+	return parsed, nil
+}
+
+func parseRSS(doc *rssDocument, base *url.URL) *parsedFeed {
+	parsed := &parsedFeed{Title: cleanLine(doc.Channel.Title), SiteURL: resolve(base, rssLinkOf(doc.Channel.Links))}
+	for _, item := range append(doc.Channel.Items, doc.Items...) {
+		text := item.Description
+		if strings.TrimSpace(text) == "" {
+			text = item.Encoded
+		}
+		entry := &Entry{
+			Title:       cleanLine(htmlToText(item.Title)),
+			URL:         resolve(base, rssLinkOf(item.Links)),
+			Summary:     truncate(htmlToText(text), maxSummaryRunes),
+			PublishedTs: parseDate(item.PubDate, item.Date),
+		}
+		entry.GUID = firstNonEmpty(strings.TrimSpace(item.GUID), strings.TrimSpace(item.About), entry.URL)
+		parsed.Entries = appendEntry(parsed.Entries, entry)
+	}
+	return parsed
+}
+
+func parseAtom(doc *atomFeed, base *url.URL) *parsedFeed {
+	parsed := &parsedFeed{Title: cleanLine(doc.Title.text()), SiteURL: resolve(base, atomLinkOf(doc.Links))}
+	for _, item := range doc.Entries {
+		text := item.Summary.text()
+		if text == "" {
+			text = item.Content.text()
+		}
+		entry := &Entry{
+			Title:       cleanLine(item.Title.text()),
+			URL:         resolve(base, atomLinkOf(item.Links)),
+			Summary:     truncate(text, maxSummaryRunes),
+			PublishedTs: parseDate(item.Published, item.Updated),
+		}
+		entry.GUID = firstNonEmpty(strings.TrimSpace(item.ID), entry.URL)
+		parsed.Entries = appendEntry(parsed.Entries, entry)
+	}
+	return parsed
+}
+
+// appendEntry appends an entry with a title, identified by its content when
+// it has neither GUID nor link.
+func appendEntry(entries []*Entry, entry *Entry) []*Entry {
+	if entry.Title == "" {
+		entry.Title = truncate(cleanLine(entry.Summary), 80)
+	}
+	if entry.Title == "" && entry.URL == "" {
+		return entries
+	}
+	entry.Title = truncate(entry.Title, maxTitleRunes)
+	if entry.GUID == "" {
+		sum := sha256.Sum256([]byte(entry.Title + "\x00" + entry.Summary))
+		entry.GUID = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	return append(entries, entry)
+}
+
+// text returns the text of an Atom text construct.
+func (t atomText) text() string {
+	switch strings.ToLower(t.Type) {
+	case "xhtml":
+		return htmlToText(t.Inner)
+	case "html":
+		return htmlToText(t.Text)
+	}
+	return strings.TrimSpace(t.Text)
+}
+
+// rssLinkOf returns the link of an RSS element: the text of its <link>, or
+// the href of an <atom:link>.
+func rssLinkOf(links []rssLink) string {
+	for _, link := range links {
+		if text := strings.TrimSpace(link.Text); text != "" {
+			return text
+		}
+	}
+	for _, link := range links {
+		if link.Href != "" {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+// atomLinkOf returns the alternate link of an Atom element.
+func atomLinkOf(links []atomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+// resolve resolves a link against the feed URL; only http(s) links are kept.
+func resolve(base *url.URL, link string) string {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return ""
+	}
+	u, err := base.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
+
+// dateLayouts are the date formats of feeds, RFC 822 variants first.
+var dateLayouts = []string{
+	time.RFC1123Z, time.RFC1123, "Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04 -0700", time.RFC3339, time.RFC3339Nano,
+	"2006-01-02T15:04:05", "2006-01-02",
+}
+
+// parseDate returns the Unix time of the first parsable date, or 0.
+func parseDate(dates ...string) int64 {
+	for _, date := range dates {
+		date = strings.TrimSpace(date)
+		if date == "" {
+			continue
+		}
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, date); err == nil {
+				return t.Unix()
+			}
+		}
+	}
+	return 0
+}
+
+var (
+	htmlSkipped = regexp.MustCompile(`(?is)<(style|script)[^>]*>.*?</(style|script)>`)
+	htmlBreaks  = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|tr|h[1-6]|blockquote)>`)
+	htmlTags    = regexp.MustCompile(`<[^>]*>`)
+	blankLines  = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToText strips the markup of an HTML fragment.
+func htmlToText(fragment string) string {
+	fragment = htmlSkipped.ReplaceAllString(fragment, "")
+	fragment = htmlBreaks.ReplaceAllString(fragment, "\n")
+	fragment = htmlTags.ReplaceAllString(fragment, "")
+	fragment = html.UnescapeString(fragment)
+	lines := strings.Split(strings.ReplaceAll(fragment, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// cleanLine collapses the whitespace of a single-line text.
+func cleanLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,224 @@
+package feed
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// DBStore persists the feeds in the feed_subscription and feed_entry tables
+// (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new feed store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const subscriptionColumns = `id, user_id, url, title, site_url, enabled, etag, last_modified,
+	last_fetched_ts, next_fetch_ts, failures, last_error, created_ts`
+
+// CreateSubscription implements Store.
+func (s *DBStore) CreateSubscription(ctx context.Context, sub *Subscription) (*Subscription, error) {
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO feed_subscription (user_id, url, title, site_url, enabled, etag, last_modified, last_fetched_ts, next_fetch_ts, created_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (user_id, url) DO NOTHING
+		RETURNING `+subscriptionColumns,
+		sub.UserID, sub.URL, sub.Title, sub.SiteURL, sub.Enabled, sub.ETag, sub.LastModified,
+		sub.LastFetchedTs, sub.NextFetchTs, time.Now().Unix())
+	created, err := scanSubscription(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create feed subscription: %w", err)
+	}
+	return created, nil
+}
+
+// GetSubscription implements Store.
+func (s *DBStore) GetSubscription(ctx context.Context, userID, id int32) (*Subscription, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+subscriptionColumns+" FROM feed_subscription WHERE id = $1 AND user_id = $2", id, userID)
+	sub, err := scanSubscription(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed subscription: %w", err)
+	}
+	return sub, nil
+}
+
+// ListSubscriptions implements Store.
+func (s *DBStore) ListSubscriptions(ctx context.Context, userID int32) ([]*Subscription, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+subscriptionColumns+" FROM feed_subscription WHERE user_id = $1 ORDER BY title, id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feed subscriptions: %w", err)
+	}
+	return scanSubscriptions(rows)
+}
+
+// UpdateSubscription implements Store.
+func (s *DBStore) UpdateSubscription(ctx context.Context, sub *Subscription) (*Subscription, error) {
+	row := s.db.QueryRowContext(ctx, `
+		UPDATE feed_subscription SET title = $3, enabled = $4,
+			next_fetch_ts = CASE WHEN $4 AND NOT enabled THEN 0 ELSE next_fetch_ts END
+		WHERE id = $1 AND user_id = $2
+		RETURNING `+subscriptionColumns,
+		sub.ID, sub.UserID, sub.Title, sub.Enabled)
+	updated, err := scanSubscription(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update feed subscription: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteSubscription implements Store.
+func (s *DBStore) DeleteSubscription(ctx context.Context, userID, id int32) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM feed_subscription WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete feed subscription: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListDueSubscriptions implements Store.
+func (s *DBStore) ListDueSubscriptions(ctx context.Context, now time.Time, limit int) ([]*Subscription, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+subscriptionColumns+` FROM feed_subscription
+		WHERE enabled AND next_fetch_ts <= $1
+		ORDER BY next_fetch_ts
+		LIMIT $2`, now.Unix(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due feed subscriptions: %w", err)
+	}
+	return scanSubscriptions(rows)
+}
+
+// SaveFetch implements Store.
+func (s *DBStore) SaveFetch(ctx context.Context, sub *Subscription) error {
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE feed_subscription SET site_url = $2, etag = $3, last_modified = $4, last_fetched_ts = $5,
+			next_fetch_ts = $6, failures = $7, last_error = $8
+		WHERE id = $1`,
+		sub.ID, sub.SiteURL, sub.ETag, sub.LastModified, sub.LastFetchedTs, sub.NextFetchTs, sub.Failures, sub.LastError); err != nil {
+		return fmt.Errorf("failed to save feed fetch: %w", err)
+	}
+	return nil
+}
+
+const entryColumns = `e.id, e.subscription_id, e.user_id, e.guid, e.title, e.url, e.summary, e.published_ts,
+	e.status, e.attempts, e.ai_summary, e.tags, e.memo_uid, e.created_ts, s.title`
+
+// AddEntries implements Store.
+func (s *DBStore) AddEntries(ctx context.Context, sub *Subscription, entries []*Entry) (int, error) {
+	added := 0
+	now := time.Now().Unix()
+	for _, entry := range entries {
+		result, err := s.db.ExecContext(ctx, `
+			INSERT INTO feed_entry (subscription_id, user_id, guid, title, url, summary, published_ts, created_ts)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (subscription_id, guid) DO NOTHING`,
+			sub.ID, sub.UserID, entry.GUID, entry.Title, entry.URL, entry.Summary, entry.PublishedTs, now)
+		if err != nil {
+			return added, fmt.Errorf("failed to add feed entry: %w", err)
+		}
+		if n, err := result.RowsAffected(); err == nil {
+			added += int(n)
+		}
+	}
+	return added, nil
+}
+
+// ListPendingEntries implements Store. Entries of paused subscriptions wait.
+func (s *DBStore) ListPendingEntries(ctx context.Context, limit int) ([]*Entry, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+entryColumns+`
+		FROM feed_entry e JOIN feed_subscription s ON s.id = e.subscription_id
+		WHERE e.status = 'PENDING' AND s.enabled
+		ORDER BY e.created_ts, e.id
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending feed entries: %w", err)
+	}
+	return scanEntries(rows)
+}
+
+// ListEntries implements Store.
+func (s *DBStore) ListEntries(ctx context.Context, userID, subscriptionID int32, limit int) ([]*Entry, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+entryColumns+`
+		FROM feed_entry e JOIN feed_subscription s ON s.id = e.subscription_id
+		WHERE e.user_id = $1 AND e.subscription_id = $2
+		ORDER BY e.published_ts DESC, e.id DESC
+		LIMIT $3`, userID, subscriptionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feed entries: %w", err)
+	}
+	return scanEntries(rows)
+}
+
+// SaveEntry implements Store.
+func (s *DBStore) SaveEntry(ctx context.Context, entry *Entry) error {
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE feed_entry SET status = $2, attempts = $3, ai_summary = $4, tags = $5, memo_uid = $6
+		WHERE id = $1`,
+		entry.ID, entry.Status, entry.Attempts, entry.AISummary, pq.Array(entry.Tags), entry.MemoUID); err != nil {
+		return fmt.Errorf("failed to save feed entry: %w", err)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSubscription(row rowScanner) (*Subscription, error) {
+	sub := &Subscription{}
+	if err := row.Scan(&sub.ID, &sub.UserID, &sub.URL, &sub.Title, &sub.SiteURL, &sub.Enabled, &sub.ETag, &sub.LastModified,
+		&sub.LastFetchedTs, &sub.NextFetchTs, &sub.Failures, &sub.LastError, &sub.CreatedTs); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func scanSubscriptions(rows *sql.Rows) ([]*Subscription, error) {
+	defer rows.Close()
+	subs := []*Subscription{}
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan feed subscription: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+func scanEntries(rows *sql.Rows) ([]*Entry, error) {
+	defer rows.Close()
+	entries := []*Entry{}
+	for rows.Next() {
+		entry := &Entry{}
+		if err := rows.Scan(&entry.ID, &entry.SubscriptionID, &entry.UserID, &entry.GUID, &entry.Title, &entry.URL,
+			&entry.Summary, &entry.PublishedTs, &entry.Status, &entry.Attempts, &entry.AISummary,
+			pq.Array(&entry.Tags), &entry.MemoUID, &entry.CreatedTs, &entry.FeedTitle); err != nil {
+			return nil, fmt.Errorf("failed to scan feed entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,189 @@
+// Package fewshot is the admin-curated store of few-shot examples.
+//
+// An example is a chat query with the route it should take and, optionally,
+// an answer in the desired style. The router's rule layer routes queries like
+// their nearest example, and expert prompts show the answers of their route.
+// Holdout examples are only used to evaluate how the others affect routing.
+package fewshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrNotFound is returned for examples that do not exist.
+	ErrNotFound = errors.New("example not found")
+	// ErrInvalid wraps the validation errors of examples.
+	ErrInvalid = errors.New("invalid example")
+)
+
+const (
+	maxQueryLength  = 500
+	maxAnswerLength = 4000
+)
+
+// Example is a few-shot example.
+type Example struct {
+	ID int32 `json:"id"`
+	// Route is the expert the query should be routed to.
+	Route string `json:"route"`
+	Query string `json:"query"`
+	// Answer is an answer in the desired style; optional.
+	Answer string `json:"answer"`
+	// Holdout keeps the example out of the router and prompts for evaluation.
+	Holdout   bool  `json:"holdout"`
+	CreatedTs int64 `json:"created_ts"`
+	UpdatedTs int64 `json:"updated_ts"`
+}
+
+// validate checks an example against the routes it may take.
+func (e *Example) validate(routes []string) error {
+	e.Route = strings.TrimSpace(e.Route)
+	e.Query = strings.TrimSpace(e.Query)
+	e.Answer = strings.TrimSpace(e.Answer)
+	if !slices.Contains(routes, e.Route) {
+		return fmt.Errorf("%w: route must be one of %s", ErrInvalid, strings.Join(routes, ", "))
+	}
+	if e.Query == "" {
+		return fmt.Errorf("%w: query is required", ErrInvalid)
+	}
+	if len(e.Query) > maxQueryLength {
+		return fmt.Errorf("%w: query must be at most %d bytes", ErrInvalid, maxQueryLength)
+	}
+	if len(e.Answer) > maxAnswerLength {
+		return fmt.Errorf("%w: answer must be at most %d bytes", ErrInvalid, maxAnswerLength)
+	}
+	return nil
+}
+
+// Store persists the examples.
+type Store interface {
+	// ListExamples returns the examples ordered by ID.
+	ListExamples(ctx context.Context) ([]*Example, error)
+	CreateExample(ctx context.Context, example *Example) (*Example, error)
+	// UpdateExample returns ErrNotFound if the example does not exist.
+	UpdateExample(ctx context.Context, example *Example) (*Example, error)
+	// DeleteExample returns ErrNotFound if the example does not exist.
+	DeleteExample(ctx context.Context, id int32) error
+}
+
+// Library serves the examples and keeps the router in sync.
+type Library struct {
+	store    Store
+	routes   []string
+	onChange func([]*Example)
+
+	mu       sync.Mutex
+	examples []*Example // nil until loaded
+}
+
+// New creates the library. routes are the routes examples may take;
+// onChange, if not nil, receives the examples after every change.
+func New(store Store, routes []string, onChange func([]*Example)) *Library {
+	return &Library{store: store, routes: routes, onChange: onChange}
+}
+
+// Examples returns the current examples, loading them on first use.
+func (l *Library) Examples(ctx context.Context) ([]*Example, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.examples != nil {
+		return l.examples, nil
+	}
+	examples, err := l.store.ListExamples(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l.examples = examples
+	if l.examples == nil {
+		l.examples = []*Example{}
+	}
+	return l.examples, nil
+}
+
+// Answers returns up to limit examples of a route with an answer, for the
+// prompt of its expert. Holdout examples are left out.
+func (l *Library) Answers(ctx context.Context, route string, limit int) []*Example {
+	examples, err := l.Examples(ctx)
+	if err != nil {
+		return nil
+	}
+	var answers []*Example
+	for _, example := range examples {
+		if len(answers) == limit {
+			break
+		}
+		if example.Route == route && example.Answer != "" && !example.Holdout {
+			answers = append(answers, example)
+		}
+	}
+	return answers
+}
+
+// Create adds an example.
+func (l *Library) Create(ctx context.Context, example *Example) (*Example, error) {
+	if err := example.validate(l.routes); err != nil {
+		return nil, err
+	}
+	created, err := l.store.CreateExample(ctx, example)
+	if err != nil {
+		return nil, err
+	}
+	return created, l.reload(ctx)
+}
+
+// Update replaces an example.
+func (l *Library) Update(ctx context.Context, example *Example) (*Example, error) {
+	if err := example.validate(l.routes); err != nil {
+		return nil, err
+	}
+	updated, err := l.store.UpdateExample(ctx, example)
+	if err != nil {
+		return nil, err
+	}
+	return updated, l.reload(ctx)
+}
+
+// Delete removes an example.
+func (l *Library) Delete(ctx context.Context, id int32) error {
+	if err := l.store.DeleteExample(ctx, id); err != nil {
+		return err
+	}
+	return l.reload(ctx)
+}
+
+// Split separates the examples used by the router and prompts from the
+// holdout examples.
+func Split(examples []*Example) (used, holdout []*Example) {
+	for _, example := range examples {
+		if example.Holdout {
+			holdout = append(holdout, example)
+		} else {
+			used = append(used, example)
+		}
+	}
+	return used, holdout
+}
+
+// reload refreshes the current examples and notifies the router.
+func (l *Library) reload(ctx context.Context) error {
+	examples, err := l.store.ListExamples(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to reload few-shot examples: %w", err)
+	}
+	if examples == nil {
+		examples = []*Example{}
+	}
+	l.mu.Lock()
+	l.examples = examples
+	l.mu.Unlock()
+	if l.onChange != nil {
+		l.onChange(examples)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package fewshot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	examples []*Example
+	nextID   int32
+}
+
+func (s *fakeStore) ListExamples(_ context.Context) ([]*Example, error) {
+	return append([]*Example(nil), s.examples...), nil
+}
+
+func (s *fakeStore) CreateExample(_ context.Context, example *Example) (*Example, error) {
+	s.nextID++
+	example.ID = s.nextID
+	s.examples = append(s.examples, example)
+	return example, nil
+}
+
+func (s *fakeStore) UpdateExample(_ context.Context, example *Example) (*Example, error) {
+	for i, existing := range s.examples {
+		if existing.ID == example.ID {
+			s.examples[i] = example
+			return example, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *fakeStore) DeleteExample(_ context.Context, id int32) error {
+	for i, existing := range s.examples {
+		if existing.ID == id {
+			s.examples = append(s.examples[:i], s.examples[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+var testRoutes = []string{"memo", "schedule", "general"}
+
+func TestExampleValidate(t *testing.T) {
+	example := &Example{Route: " memo ", Query: " 找一下周报 "}
+	require.NoError(t, example.validate(testRoutes))
+	assert.Equal(t, "memo", example.Route)
+	assert.Equal(t, "找一下周报", example.Query)
+
+	for _, invalid := range []*Example{
+		{Route: "calendar", Query: "找一下周报"},
+		{Route: "memo", Query: "  "},
+		{Route: "memo", Query: strings.Repeat("周", maxQueryLength)},
+		{Route: "memo", Query: "找一下周报", Answer: strings.Repeat("a", maxAnswerLength+1)},
+	} {
+		assert.ErrorIs(t, invalid.validate(testRoutes), ErrInvalid)
+	}
+}
+
+func TestLibrary(t *testing.T) {
+	ctx := context.Background()
+	var changes [][]*Example
+	library := New(&fakeStore{}, testRoutes, func(examples []*Example) {
+		changes = append(changes, examples)
+	})
+
+	examples, err := library.Examples(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, examples)
+
+	memo, err := library.Create(ctx, &Example{Route: "memo", Query: "找一下周报", Answer: "找到 2 条周报"})
+	require.NoError(t, err)
+	_, err = library.Create(ctx, &Example{Route: "memo", Query: "搜索读书笔记"})
+	require.NoError(t, err)
+	_, err = library.Create(ctx, &Example{Route: "memo", Query: "查一下会议纪要", Answer: "找到 1 条纪要", Holdout: true})
+	require.NoError(t, err)
+	_, err = library.Create(ctx, &Example{Route: "calendar", Query: "明天开会"})
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	// Only non-holdout examples with an answer are shown to the expert
+	answers := library.Answers(ctx, "memo", 5)
+	require.Len(t, answers, 1)
+	assert.Equal(t, memo.ID, answers[0].ID)
+	assert.Empty(t, library.Answers(ctx, "schedule", 5))
+
+	used, holdout := Split(changes[len(changes)-1])
+	assert.Len(t, used, 2)
+	assert.Len(t, holdout, 1)
+
+	_, err = library.Update(ctx, &Example{ID: 99, Route: "memo", Query: "x"})
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = library.Update(ctx, &Example{ID: memo.ID, Route: "schedule", Query: "下周有什么安排", Answer: "下周有 3 个日程"})
+	require.NoError(t, err)
+	assert.Len(t, library.Answers(ctx, "schedule", 5), 1)
+
+	assert.ErrorIs(t, library.Delete(ctx, 99), ErrNotFound)
+	require.NoError(t, library.Delete(ctx, memo.ID))
+	assert.Empty(t, library.Answers(ctx, "schedule", 5))
+
+	// The router is notified of every change
+	assert.Len(t, changes, 5)
+}
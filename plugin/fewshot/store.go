@@ -0,0 +1,98 @@
+package fewshot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DBStore persists the examples in the few_shot_example table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new example store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const exampleColumns = "id, route, query, answer, holdout, created_ts, updated_ts"
+
+// ListExamples implements Store.
+func (s *DBStore) ListExamples(ctx context.Context) ([]*Example, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+exampleColumns+" FROM few_shot_example ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list examples: %w", err)
+	}
+	defer rows.Close()
+
+	var examples []*Example
+	for rows.Next() {
+		example, err := scanExample(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan example: %w", err)
+		}
+		examples = append(examples, example)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list examples: %w", err)
+	}
+	return examples, nil
+}
+
+// CreateExample implements Store.
+func (s *DBStore) CreateExample(ctx context.Context, example *Example) (*Example, error) {
+	now := time.Now().Unix()
+	created, err := scanExample(s.db.QueryRowContext(ctx, `
+		INSERT INTO few_shot_example (route, query, answer, holdout, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		RETURNING `+exampleColumns,
+		example.Route, example.Query, example.Answer, example.Holdout, now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create example: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateExample implements Store.
+func (s *DBStore) UpdateExample(ctx context.Context, example *Example) (*Example, error) {
+	updated, err := scanExample(s.db.QueryRowContext(ctx, `
+		UPDATE few_shot_example SET route = $2, query = $3, answer = $4, holdout = $5, updated_ts = $6
+		WHERE id = $1
+		RETURNING `+exampleColumns,
+		example.ID, example.Route, example.Query, example.Answer, example.Holdout, time.Now().Unix()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update example: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteExample implements Store.
+func (s *DBStore) DeleteExample(ctx context.Context, id int32) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM few_shot_example WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete example: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanExample(row rowScanner) (*Example, error) {
+	example := &Example{}
+	if err := row.Scan(&example.ID, &example.Route, &example.Query, &example.Answer, &example.Holdout,
+		&example.CreatedTs, &example.UpdatedTs); err != nil {
+		return nil, err
+	}
+	return example, nil
+}
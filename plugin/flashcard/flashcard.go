@@ -0,0 +1,336 @@
+// Package flashcard turns memos into spaced-repetition flashcards.
+//
+// A Generator (usually a cheap LLM) writes question/answer cards from the
+// memos a user selects. Cards are scheduled with the SM-2 algorithm shared
+// with memo reviews (ai/review): each graded review moves the card's due time
+// further out, or back to tomorrow when it was forgotten.
+package flashcard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	agents "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/ai/review"
+)
+
+const (
+	// MaxMemosPerGeneration bounds the memos cards are generated from at once.
+	MaxMemosPerGeneration = 10
+	// maxCardsPerMemo bounds the cards kept from one memo.
+	maxCardsPerMemo = 5
+	// maxQuestionRunes and maxAnswerRunes bound the length of a card.
+	maxQuestionRunes = 200
+	maxAnswerRunes   = 1000
+	// masteredIntervalDays is the interval from which a card counts as mastered.
+	masteredIntervalDays = 30
+	// streakDays bounds the review history scanned for the streak.
+	streakDays = 366
+)
+
+// ToolName is the name of the agent tool generating flashcards.
+const ToolName = "flashcard_generate"
+
+// Card is a question/answer flashcard generated from a memo.
+type Card struct {
+	ID        int32  `json:"id"`
+	CreatorID int32  `json:"creator_id"`
+	MemoID    int32  `json:"memo_id"`
+	MemoUID   string `json:"memo_uid"`
+	Question  string `json:"question"`
+	Answer    string `json:"answer"`
+	// SM-2 state.
+	EaseFactor   float64 `json:"ease_factor"`
+	IntervalDays int     `json:"interval_days"`
+	ReviewCount  int     `json:"review_count"`
+	DueTs        int64   `json:"due_ts"`
+	LastReviewTs int64   `json:"last_review_ts"`
+	CreatedTs    int64   `json:"created_ts"`
+	UpdatedTs    int64   `json:"updated_ts"`
+}
+
+// Stats summarizes the flashcards of a user.
+type Stats struct {
+	TotalCards    int `json:"total_cards"`
+	DueNow        int `json:"due_now"`
+	NewCards      int `json:"new_cards"`
+	MasteredCards int `json:"mastered_cards"` // interval > 30 days
+	ReviewedToday int `json:"reviewed_today"`
+	TotalReviews  int `json:"total_reviews"`
+	StreakDays    int `json:"streak_days"`
+	// Accuracy is the percentage of reviews not graded "again".
+	Accuracy int `json:"accuracy"`
+}
+
+// FindCard specifies conditions for listing cards.
+type FindCard struct {
+	ID        *int32
+	CreatorID *int32
+	MemoID    *int32
+	// DueBefore lists the cards due at or before this time, earliest first.
+	DueBefore *int64
+	Limit     int
+}
+
+// Memo is a memo cards are generated from.
+type Memo struct {
+	ID      int32
+	UID     string
+	Content string
+}
+
+// Store persists cards; implemented by DBStore.
+type Store interface {
+	// ReplaceMemoCards replaces the cards of a memo with the given ones and
+	// returns them as created.
+	ReplaceMemoCards(ctx context.Context, creatorID, memoID int32, cards []*Card) ([]*Card, error)
+	ListCards(ctx context.Context, find *FindCard) ([]*Card, error)
+	// CountMemoCards returns the number of cards of each memo that has some.
+	CountMemoCards(ctx context.Context, creatorID int32, memoIDs []int32) (map[int32]int, error)
+	// RecordReview saves the new scheduling state of card and logs the review.
+	RecordReview(ctx context.Context, card *Card, quality review.ReviewQuality) error
+	DeleteCard(ctx context.Context, id, creatorID int32) (bool, error)
+	// Stats fills the card and review counts; dayStart is the start of today.
+	Stats(ctx context.Context, creatorID int32, now, dayStart int64) (*Stats, error)
+	// ReviewDays returns the days with reviews since the given time, as
+	// "2006-01-02" in the given UTC offset.
+	ReviewDays(ctx context.Context, creatorID int32, since int64, utcOffset int) ([]string, error)
+}
+
+// MemoFinder loads the memos of a user by UID.
+type MemoFinder interface {
+	FindMemos(ctx context.Context, creatorID int32, uids []string) ([]*Memo, error)
+}
+
+// QA is a question/answer pair written by a Generator.
+type QA struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// Generator writes flashcards for a memo.
+type Generator interface {
+	Generate(ctx context.Context, content string) ([]QA, error)
+}
+
+// GenerateResult is the outcome of generating cards from memos.
+type GenerateResult struct {
+	Cards []*Card `json:"cards"`
+	// Skipped lists the memos that already had cards, were not found or
+	// produced no card.
+	Skipped []string `json:"skipped"`
+}
+
+// Deck generates, schedules and reviews the flashcards of users.
+// A nil *Deck is valid and exposes no tool.
+type Deck struct {
+	store     Store
+	generator Generator
+	memos     MemoFinder
+	now       func() time.Time
+}
+
+// NewDeck creates a deck.
+func NewDeck(store Store, generator Generator, memos MemoFinder) *Deck {
+	return &Deck{store: store, generator: generator, memos: memos, now: time.Now}
+}
+
+// Generate writes cards for the given memos of a user. Memos that already have
+// cards are skipped unless replace is set, which discards their cards and
+// review progress.
+func (d *Deck) Generate(ctx context.Context, creatorID int32, memoUIDs []string, replace bool) (*GenerateResult, error) {
+	if len(memoUIDs) == 0 {
+		return nil, fmt.Errorf("at least one memo is required")
+	}
+	if len(memoUIDs) > MaxMemosPerGeneration {
+		return nil, fmt.Errorf("at most %d memos can be used at once", MaxMemosPerGeneration)
+	}
+	memos, err := d.memos.FindMemos(ctx, creatorID, memoUIDs)
+	if err != nil {
+		return nil, fmt.Errorf("find memos: %w", err)
+	}
+	found := make(map[string]*Memo, len(memos))
+	ids := make([]int32, 0, len(memos))
+	for _, memo := range memos {
+		found[memo.UID] = memo
+		ids = append(ids, memo.ID)
+	}
+	existing := map[int32]int{}
+	if !replace && len(ids) > 0 {
+		if existing, err = d.store.CountMemoCards(ctx, creatorID, ids); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &GenerateResult{Cards: []*Card{}, Skipped: []string{}}
+	seen := make(map[string]bool)
+	for _, uid := range memoUIDs {
+		memo := found[uid]
+		if seen[uid] {
+			continue
+		}
+		seen[uid] = true
+		if memo == nil || existing[memo.ID] > 0 || strings.TrimSpace(memo.Content) == "" {
+			result.Skipped = append(result.Skipped, uid)
+			continue
+		}
+		qas, err := d.generator.Generate(ctx, memo.Content)
+		if err != nil {
+			return nil, fmt.Errorf("generate cards for memo %s: %w", uid, err)
+		}
+		qas = cleanCards(qas)
+		if len(qas) == 0 {
+			result.Skipped = append(result.Skipped, uid)
+			continue
+		}
+		now := d.now().Unix()
+		cards := make([]*Card, 0, len(qas))
+		for _, qa := range qas {
+			cards = append(cards, &Card{
+				CreatorID:  creatorID,
+				MemoID:     memo.ID,
+				MemoUID:    memo.UID,
+				Question:   qa.Question,
+				Answer:     qa.Answer,
+				EaseFactor: review.DefaultEaseFactor,
+				DueTs:      now,
+				CreatedTs:  now,
+				UpdatedTs:  now,
+			})
+		}
+		created, err := d.store.ReplaceMemoCards(ctx, creatorID, memo.ID, cards)
+		if err != nil {
+			return nil, err
+		}
+		result.Cards = append(result.Cards, created...)
+	}
+	return result, nil
+}
+
+// List lists the cards of a user.
+func (d *Deck) List(ctx context.Context, find *FindCard) ([]*Card, error) {
+	return d.store.ListCards(ctx, find)
+}
+
+// Due returns the cards of a user due for review, earliest first: the cards of
+// a review session.
+func (d *Deck) Due(ctx context.Context, creatorID int32, limit int) ([]*Card, error) {
+	now := d.now().Unix()
+	return d.store.ListCards(ctx, &FindCard{CreatorID: &creatorID, DueBefore: &now, Limit: limit})
+}
+
+// Review grades the recall of a card of a user and schedules its next review.
+// It returns nil if the card does not exist.
+func (d *Deck) Review(ctx context.Context, id, creatorID int32, quality review.ReviewQuality) (*Card, error) {
+	if quality < review.QualityAgain || quality > review.QualityEasy {
+		return nil, fmt.Errorf("invalid review quality: %d", quality)
+	}
+	cards, err := d.store.ListCards(ctx, &FindCard{ID: &id, CreatorID: &creatorID})
+	if err != nil || len(cards) == 0 {
+		return nil, err
+	}
+	card := cards[0]
+	now := d.now()
+	card.EaseFactor, card.IntervalDays = review.Schedule(card.EaseFactor, card.IntervalDays, quality)
+	card.ReviewCount++
+	card.LastReviewTs = now.Unix()
+	card.DueTs = now.AddDate(0, 0, card.IntervalDays).Unix()
+	card.UpdatedTs = now.Unix()
+	if err := d.store.RecordReview(ctx, card, quality); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// Delete deletes a card of a user.
+func (d *Deck) Delete(ctx context.Context, id, creatorID int32) (bool, error) {
+	return d.store.DeleteCard(ctx, id, creatorID)
+}
+
+// Stats returns the flashcard statistics of a user.
+func (d *Deck) Stats(ctx context.Context, creatorID int32) (*Stats, error) {
+	now := d.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	stats, err := d.store.Stats(ctx, creatorID, now.Unix(), today.Unix())
+	if err != nil {
+		return nil, err
+	}
+	_, offset := now.Zone()
+	days, err := d.store.ReviewDays(ctx, creatorID, today.AddDate(0, 0, -streakDays).Unix(), offset)
+	if err != nil {
+		return nil, err
+	}
+	reviewDates := make(map[string]bool, len(days))
+	for _, day := range days {
+		reviewDates[day] = true
+	}
+	stats.StreakDays = review.CalculateStreak(reviewDates, today)
+	return stats, nil
+}
+
+// ToolFor returns the flashcard_generate tool for a user's agents, or nil when
+// flashcards are unavailable.
+func (d *Deck) ToolFor(userID int32) agents.ToolWithSchema {
+	if d == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		ToolName,
+		"Create spaced-repetition flashcards (question/answer cards) from the user's memos, "+
+			"for review sessions. Find the memos first (e.g. with memo_search), then pass their UIDs. "+
+			`Memos that already have cards are skipped. Input: {"memo_uids": ["..."]}.`,
+		func(ctx context.Context, input string) (string, error) {
+			var args struct {
+				MemoUIDs []string `json:"memo_uids"`
+			}
+			if err := json.Unmarshal([]byte(input), &args); err != nil || len(args.MemoUIDs) == 0 {
+				return "", fmt.Errorf("memo_uids is required")
+			}
+			result, err := d.Generate(ctx, userID, args.MemoUIDs, false)
+			if err != nil {
+				return "", err
+			}
+			data, err := json.Marshal(result)
+			if err != nil {
+				return "", err
+			}
+			return string(data), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"memo_uids": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": fmt.Sprintf("UIDs of the memos to make cards from (at most %d)", MaxMemosPerGeneration),
+				},
+			},
+			"required": []string{"memo_uids"},
+		},
+	)
+}
+
+// cleanCards drops empty, oversized and duplicate cards and bounds their number.
+func cleanCards(qas []QA) []QA {
+	seen := make(map[string]bool)
+	var cleaned []QA
+	for _, qa := range qas {
+		qa.Question = strings.TrimSpace(qa.Question)
+		qa.Answer = strings.TrimSpace(qa.Answer)
+		if qa.Question == "" || qa.Answer == "" || seen[qa.Question] ||
+			utf8.RuneCountInString(qa.Question) > maxQuestionRunes ||
+			utf8.RuneCountInString(qa.Answer) > maxAnswerRunes {
+			continue
+		}
+		seen[qa.Question] = true
+		cleaned = append(cleaned, qa)
+		if len(cleaned) == maxCardsPerMemo {
+			break
+		}
+	}
+	return cleaned
+}
@@ -0,0 +1,232 @@
+package flashcard
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/ai/review"
+)
+
+// memoryStore is an in-memory Store.
+type memoryStore struct {
+	cards   []*Card
+	reviews []review.ReviewQuality
+	days    []string
+}
+
+func (s *memoryStore) ReplaceMemoCards(_ context.Context, creatorID, memoID int32, cards []*Card) ([]*Card, error) {
+	var kept []*Card
+	for _, c := range s.cards {
+		if c.CreatorID != creatorID || c.MemoID != memoID {
+			kept = append(kept, c)
+		}
+	}
+	s.cards = kept
+	for _, c := range cards {
+		c.ID = int32(len(s.cards) + 100)
+		s.cards = append(s.cards, c)
+	}
+	return cards, nil
+}
+
+func (s *memoryStore) ListCards(_ context.Context, find *FindCard) ([]*Card, error) {
+	var cards []*Card
+	for _, c := range s.cards {
+		if (find.ID != nil && c.ID != *find.ID) ||
+			(find.CreatorID != nil && c.CreatorID != *find.CreatorID) ||
+			(find.MemoID != nil && c.MemoID != *find.MemoID) ||
+			(find.DueBefore != nil && c.DueTs > *find.DueBefore) {
+			continue
+		}
+		copied := *c
+		cards = append(cards, &copied)
+	}
+	return cards, nil
+}
+
+func (s *memoryStore) CountMemoCards(_ context.Context, creatorID int32, memoIDs []int32) (map[int32]int, error) {
+	counts := map[int32]int{}
+	for _, c := range s.cards {
+		for _, id := range memoIDs {
+			if c.CreatorID == creatorID && c.MemoID == id {
+				counts[id]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+func (s *memoryStore) RecordReview(_ context.Context, card *Card, quality review.ReviewQuality) error {
+	for i, c := range s.cards {
+		if c.ID == card.ID {
+			s.cards[i] = card
+		}
+	}
+	s.reviews = append(s.reviews, quality)
+	return nil
+}
+
+func (s *memoryStore) DeleteCard(context.Context, int32, int32) (bool, error) {
+	return false, nil
+}
+
+func (s *memoryStore) Stats(context.Context, int32, int64, int64) (*Stats, error) {
+	return &Stats{TotalReviews: len(s.reviews)}, nil
+}
+
+func (s *memoryStore) ReviewDays(context.Context, int32, int64, int) ([]string, error) {
+	return s.days, nil
+}
+
+type memoryMemos []*Memo
+
+func (m memoryMemos) FindMemos(_ context.Context, _ int32, uids []string) ([]*Memo, error) {
+	var memos []*Memo
+	for _, memo := range m {
+		for _, uid := range uids {
+			if memo.UID == uid {
+				memos = append(memos, memo)
+			}
+		}
+	}
+	return memos, nil
+}
+
+type fixedGenerator struct {
+	cards []QA
+	calls int
+}
+
+func (g *fixedGenerator) Generate(context.Context, string) ([]QA, error) {
+	g.calls++
+	return g.cards, nil
+}
+
+func TestDeckGenerate(t *testing.T) {
+	ctx := context.Background()
+	st := &memoryStore{}
+	generator := &fixedGenerator{cards: []QA{
+		{Question: "Redis 默认端口？", Answer: "6379"},
+		{Question: " Redis 默认端口？ ", Answer: "6379"},
+		{Question: "空答案", Answer: " "},
+		{Question: strings.Repeat("长", maxQuestionRunes+1), Answer: "x"},
+	}}
+	memos := memoryMemos{
+		{ID: 1, UID: "m_001", Content: "Redis 默认端口是 6379"},
+		{ID: 2, UID: "m_002", Content: "  "},
+	}
+	deck := NewDeck(st, generator, memos)
+
+	result, err := deck.Generate(ctx, 7, []string{"m_001", "m_002", "m_404", "m_001"}, false)
+	require.NoError(t, err)
+	require.Len(t, result.Cards, 1)
+	card := result.Cards[0]
+	assert.Equal(t, "m_001", card.MemoUID)
+	assert.Equal(t, review.DefaultEaseFactor, card.EaseFactor)
+	assert.NotZero(t, card.DueTs)
+	assert.Equal(t, []string{"m_002", "m_404"}, result.Skipped)
+
+	// Memos with cards are skipped unless replaced.
+	result, err = deck.Generate(ctx, 7, []string{"m_001"}, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Cards)
+	assert.Equal(t, []string{"m_001"}, result.Skipped)
+	assert.Equal(t, 1, generator.calls)
+
+	result, err = deck.Generate(ctx, 7, []string{"m_001"}, true)
+	require.NoError(t, err)
+	assert.Len(t, result.Cards, 1)
+	assert.Len(t, st.cards, 1)
+
+	_, err = deck.Generate(ctx, 7, nil, false)
+	require.Error(t, err)
+	_, err = deck.Generate(ctx, 7, make([]string, MaxMemosPerGeneration+1), false)
+	require.Error(t, err)
+}
+
+func TestDeckReview(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+	st := &memoryStore{}
+	deck := NewDeck(st, &fixedGenerator{cards: []QA{{Question: "Q", Answer: "A"}}},
+		memoryMemos{{ID: 1, UID: "m_001", Content: "content"}})
+	deck.now = func() time.Time { return now }
+
+	result, err := deck.Generate(ctx, 7, []string{"m_001"}, false)
+	require.NoError(t, err)
+	id := result.Cards[0].ID
+
+	due, err := deck.Due(ctx, 7, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+
+	card, err := deck.Review(ctx, id, 7, review.QualityGood)
+	require.NoError(t, err)
+	require.NotNil(t, card)
+	assert.Equal(t, 1, card.IntervalDays)
+	assert.Equal(t, 1, card.ReviewCount)
+	assert.Equal(t, now.AddDate(0, 0, 1).Unix(), card.DueTs)
+
+	due, err = deck.Due(ctx, 7, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	now = now.AddDate(0, 0, 1)
+	card, err = deck.Review(ctx, id, 7, review.QualityGood)
+	require.NoError(t, err)
+	assert.Equal(t, 3, card.IntervalDays)
+
+	card, err = deck.Review(ctx, id, 8, review.QualityGood)
+	require.NoError(t, err)
+	assert.Nil(t, card)
+
+	_, err = deck.Review(ctx, id, 7, review.ReviewQuality(4))
+	require.Error(t, err)
+
+	st.days = []string{now.Format("2006-01-02"), now.AddDate(0, 0, -1).Format("2006-01-02")}
+	stats, err := deck.Stats(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.TotalReviews)
+	assert.Equal(t, 2, stats.StreakDays)
+}
+
+func TestFlashcardTool(t *testing.T) {
+	ctx := context.Background()
+	deck := NewDeck(&memoryStore{}, &fixedGenerator{cards: []QA{{Question: "Q", Answer: "A"}}},
+		memoryMemos{{ID: 1, UID: "m_001", Content: "content"}})
+
+	tool := deck.ToolFor(7)
+	require.Equal(t, ToolName, tool.Name())
+	out, err := tool.Run(ctx, `{"memo_uids": ["m_001"]}`)
+	require.NoError(t, err)
+	var result GenerateResult
+	require.NoError(t, json.Unmarshal([]byte(out), &result))
+	require.Len(t, result.Cards, 1)
+	assert.Equal(t, "Q", result.Cards[0].Question)
+
+	_, err = tool.Run(ctx, `{}`)
+	require.Error(t, err)
+
+	var nilDeck *Deck
+	assert.Nil(t, nilDeck.ToolFor(7))
+}
+
+func TestParseCards(t *testing.T) {
+	cards, err := parseCards("```json\n{\"cards\": [{\"question\": \"Q\", \"answer\": \"A\"}]}\n```")
+	require.NoError(t, err)
+	require.Len(t, cards, 1)
+	assert.Equal(t, QA{Question: "Q", Answer: "A"}, cards[0])
+
+	cards, err = parseCards(`{"cards": []}`)
+	require.NoError(t, err)
+	assert.Empty(t, cards)
+
+	_, err = parseCards("no cards")
+	require.Error(t, err)
+}
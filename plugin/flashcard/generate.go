@@ -0,0 +1,66 @@
+package flashcard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hrygo/divinesense/ai/core/llm"
+)
+
+// maxGenerateRunes bounds the memo text sent to the model.
+const maxGenerateRunes = 4000
+
+// LLMGenerator writes cards with a language model. A cheap model is enough.
+type LLMGenerator struct {
+	llm llm.Service
+}
+
+// NewLLMGenerator creates a generator backed by llmSvc.
+func NewLLMGenerator(llmSvc llm.Service) *LLMGenerator {
+	return &LLMGenerator{llm: llmSvc}
+}
+
+// Generate implements Generator.
+func (g *LLMGenerator) Generate(ctx context.Context, content string) ([]QA, error) {
+	if runes := []rune(content); len(runes) > maxGenerateRunes {
+		content = string(runes[:maxGenerateRunes])
+	}
+	answer, _, err := g.llm.Chat(ctx, []llm.Message{
+		llm.SystemPrompt(generateSystemPrompt),
+		llm.UserMessage(content),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseCards(answer)
+}
+
+// parseCards decodes the JSON answer of the model, tolerating a markdown code
+// fence around it.
+func parseCards(content string) ([]QA, error) {
+	content = strings.TrimSpace(content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	content = strings.TrimSpace(content)
+
+	var result struct {
+		Cards []QA `json:"cards"`
+	}
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return nil, fmt.Errorf("parse cards: %w", err)
+	}
+	return result.Cards, nil
+}
+
+const generateSystemPrompt = `你是一个学习卡片制作助手。根据用户的笔记制作用于间隔重复复习的问答卡片。
+
+要求：
+1. 每张卡片只考查一个知识点：事实、定义、步骤、结论或关键数字
+2. 问题要能脱离笔记独立理解，不要使用"这篇笔记"、"上文"等指代
+3. 答案简洁准确，只使用笔记中的信息，不要编造
+4. 最多 5 张卡片；笔记没有值得记忆的内容（如待办、闲聊）时返回空数组
+5. 使用与笔记一致的语言
+6. 只返回JSON：{"cards": [{"question": "", "answer": ""}]}`
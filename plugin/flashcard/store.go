@@ -0,0 +1,225 @@
+package flashcard
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/hrygo/divinesense/ai/review"
+)
+
+// DBStore persists cards in the flashcard and flashcard_review tables (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new card store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const cardColumns = "c.id, c.creator_id, c.memo_id, m.uid, c.question, c.answer, c.ease_factor, c.interval_days, " +
+	"c.review_count, c.due_ts, c.last_review_ts, c.created_ts, c.updated_ts"
+
+// ReplaceMemoCards implements Store.
+func (s *DBStore) ReplaceMemoCards(ctx context.Context, creatorID, memoID int32, cards []*Card) ([]*Card, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM flashcard WHERE creator_id = $1 AND memo_id = $2", creatorID, memoID); err != nil {
+		return nil, fmt.Errorf("failed to delete cards: %w", err)
+	}
+	created := make([]*Card, 0, len(cards))
+	for _, card := range cards {
+		c := *card
+		c.CreatorID, c.MemoID = creatorID, memoID
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO flashcard (creator_id, memo_id, question, answer, ease_factor, due_ts, created_ts, updated_ts)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id`,
+			c.CreatorID, c.MemoID, c.Question, c.Answer, c.EaseFactor, c.DueTs, c.CreatedTs, c.UpdatedTs,
+		).Scan(&c.ID); err != nil {
+			return nil, fmt.Errorf("failed to insert card: %w", err)
+		}
+		created = append(created, &c)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit cards: %w", err)
+	}
+	return created, nil
+}
+
+// ListCards implements Store, earliest due first.
+func (s *DBStore) ListCards(ctx context.Context, find *FindCard) ([]*Card, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if find.ID != nil {
+		args = append(args, *find.ID)
+		where = append(where, fmt.Sprintf("c.id = $%d", len(args)))
+	}
+	if find.CreatorID != nil {
+		args = append(args, *find.CreatorID)
+		where = append(where, fmt.Sprintf("c.creator_id = $%d", len(args)))
+	}
+	if find.MemoID != nil {
+		args = append(args, *find.MemoID)
+		where = append(where, fmt.Sprintf("c.memo_id = $%d", len(args)))
+	}
+	if find.DueBefore != nil {
+		args = append(args, *find.DueBefore)
+		where = append(where, fmt.Sprintf("c.due_ts <= $%d", len(args)))
+	}
+
+	query := "SELECT " + cardColumns + " FROM flashcard c JOIN memo m ON m.id = c.memo_id WHERE " +
+		strings.Join(where, " AND ") + " ORDER BY c.due_ts, c.id"
+	if find.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", find.Limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cards: %w", err)
+	}
+	defer rows.Close()
+
+	cards := []*Card{}
+	for rows.Next() {
+		card, err := scanCard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan card: %w", err)
+		}
+		cards = append(cards, card)
+	}
+	return cards, rows.Err()
+}
+
+// CountMemoCards implements Store.
+func (s *DBStore) CountMemoCards(ctx context.Context, creatorID int32, memoIDs []int32) (map[int32]int, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT memo_id, COUNT(*) FROM flashcard
+		WHERE creator_id = $1 AND memo_id = ANY($2)
+		GROUP BY memo_id`,
+		creatorID, pq.Array(memoIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count cards: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int32]int)
+	for rows.Next() {
+		var memoID int32
+		var count int
+		if err := rows.Scan(&memoID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan card count: %w", err)
+		}
+		counts[memoID] = count
+	}
+	return counts, rows.Err()
+}
+
+// RecordReview implements Store.
+func (s *DBStore) RecordReview(ctx context.Context, card *Card, quality review.ReviewQuality) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE flashcard SET ease_factor = $1, interval_days = $2, review_count = $3,
+			due_ts = $4, last_review_ts = $5, updated_ts = $6
+		WHERE id = $7`,
+		card.EaseFactor, card.IntervalDays, card.ReviewCount, card.DueTs, card.LastReviewTs, card.UpdatedTs, card.ID); err != nil {
+		return fmt.Errorf("failed to update card: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO flashcard_review (card_id, creator_id, quality, interval_days, reviewed_ts) VALUES ($1, $2, $3, $4, $5)",
+		card.ID, card.CreatorID, int(quality), card.IntervalDays, card.LastReviewTs); err != nil {
+		return fmt.Errorf("failed to log review: %w", err)
+	}
+	return tx.Commit()
+}
+
+// DeleteCard implements Store. It returns false when the creator has no such card.
+func (s *DBStore) DeleteCard(ctx context.Context, id, creatorID int32) (bool, error) {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM flashcard WHERE id = $1 AND creator_id = $2", id, creatorID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete card: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete card: %w", err)
+	}
+	return n > 0, nil
+}
+
+// Stats implements Store.
+func (s *DBStore) Stats(ctx context.Context, creatorID int32, now, dayStart int64) (*Stats, error) {
+	stats := &Stats{}
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*),
+			COUNT(*) FILTER (WHERE due_ts <= $2),
+			COUNT(*) FILTER (WHERE review_count = 0),
+			COUNT(*) FILTER (WHERE interval_days > $3)
+		FROM flashcard WHERE creator_id = $1`,
+		creatorID, now, masteredIntervalDays,
+	).Scan(&stats.TotalCards, &stats.DueNow, &stats.NewCards, &stats.MasteredCards); err != nil {
+		return nil, fmt.Errorf("failed to count cards: %w", err)
+	}
+
+	var recalled int
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*),
+			COUNT(*) FILTER (WHERE reviewed_ts >= $2),
+			COUNT(*) FILTER (WHERE quality > $3)
+		FROM flashcard_review WHERE creator_id = $1`,
+		creatorID, dayStart, int(review.QualityAgain),
+	).Scan(&stats.TotalReviews, &stats.ReviewedToday, &recalled); err != nil {
+		return nil, fmt.Errorf("failed to count reviews: %w", err)
+	}
+	if stats.TotalReviews > 0 {
+		stats.Accuracy = recalled * 100 / stats.TotalReviews
+	}
+	return stats, nil
+}
+
+// ReviewDays implements Store.
+func (s *DBStore) ReviewDays(ctx context.Context, creatorID int32, since int64, utcOffset int) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT to_char(to_timestamp(reviewed_ts + $3) AT TIME ZONE 'UTC', 'YYYY-MM-DD')
+		FROM flashcard_review
+		WHERE creator_id = $1 AND reviewed_ts >= $2`,
+		creatorID, since, utcOffset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review days: %w", err)
+	}
+	defer rows.Close()
+
+	var days []string
+	for rows.Next() {
+		var day string
+		if err := rows.Scan(&day); err != nil {
+			return nil, fmt.Errorf("failed to scan review day: %w", err)
+		}
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCard(row rowScanner) (*Card, error) {
+	card := &Card{}
+	if err := row.Scan(&card.ID, &card.CreatorID, &card.MemoID, &card.MemoUID, &card.Question, &card.Answer,
+		&card.EaseFactor, &card.IntervalDays, &card.ReviewCount, &card.DueTs, &card.LastReviewTs,
+		&card.CreatedTs, &card.UpdatedTs); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
@@ -0,0 +1,293 @@
+// Package geekworkspace browses the Geek Mode working directories of users.
+//
+// The Claude Code sessions of a user run in a sandbox directory,
+// ~/.divinesense/claude/user_N, where they leave their artifacts: reports,
+// scripts, generated pages. The API lists, reads, downloads and deletes the
+// files of the current user's sandbox. Paths are relative to the sandbox and
+// resolved with os.Root, so neither ".." nor symbolic links reach outside it.
+package geekworkspace
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrNotFound is returned for files the workspace does not have.
+	ErrNotFound = errors.New("workspace file not found")
+	// ErrInvalid is returned for paths outside the workspace and for
+	// operations that do not apply to a file.
+	ErrInvalid = errors.New("invalid workspace path")
+)
+
+const (
+	// MaxReadBytes bounds the content returned by Read.
+	MaxReadBytes = 1 << 20
+	// maxEntries bounds the entries returned by List.
+	maxEntries = 1000
+)
+
+// BaseDir returns the directory holding the sandboxes of the users.
+func BaseDir() string {
+	// Persistent directory in the home of the server, kept across restarts
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "/tmp"
+	}
+	return filepath.Join(homeDir, ".divinesense", "claude")
+}
+
+// Dir returns the sandbox of a user.
+func Dir(userID int32) string {
+	return filepath.Join(BaseDir(), fmt.Sprintf("user_%d", userID))
+}
+
+// Entry is a file or directory of a workspace.
+type Entry struct {
+	Name string `json:"name"`
+	// Path is relative to the workspace, with forward slashes.
+	Path       string `json:"path"`
+	IsDir      bool   `json:"is_dir"`
+	Symlink    bool   `json:"symlink"`
+	Size       int64  `json:"size"`
+	ModifiedTs int64  `json:"modified_ts"`
+}
+
+// Listing is the content of a directory of a workspace.
+type Listing struct {
+	Path    string  `json:"path"`
+	Entries []Entry `json:"entries"`
+	// Truncated is set when the directory has more entries than listed.
+	Truncated bool `json:"truncated"`
+}
+
+// Content is a file of a workspace with its content.
+type Content struct {
+	Entry
+	MimeType string `json:"mime_type"`
+	// Binary files have no Content; download them instead.
+	Binary bool `json:"binary"`
+	// Truncated is set when the file is larger than MaxReadBytes.
+	Truncated bool   `json:"truncated"`
+	Content   string `json:"content"`
+}
+
+// Workspace is the sandbox of a user.
+type Workspace struct {
+	dir string
+}
+
+// New returns the workspace in a directory.
+func New(dir string) *Workspace {
+	return &Workspace{dir: dir}
+}
+
+// ForUser returns the workspace of a user.
+func ForUser(userID int32) *Workspace {
+	return New(Dir(userID))
+}
+
+// List returns the entries of a directory, directories first, then by name.
+// The workspace of a user who never ran a Geek session is empty.
+func (w *Workspace) List(name string) (*Listing, error) {
+	name, err := clean(name)
+	if err != nil {
+		return nil, err
+	}
+	root, err := os.OpenRoot(w.dir)
+	if errors.Is(err, fs.ErrNotExist) && name == "." {
+		return &Listing{Path: "", Entries: []Entry{}}, nil
+	}
+	if err != nil {
+		return nil, wrap(err, "open workspace")
+	}
+	defer root.Close()
+
+	dir, err := root.Open(name)
+	if err != nil {
+		return nil, wrap(err, "open directory")
+	}
+	defer dir.Close()
+	dirEntries, err := dir.ReadDir(-1)
+	if err != nil {
+		return nil, wrap(err, "read directory")
+	}
+
+	listing := &Listing{Path: relPath(name), Entries: []Entry{}}
+	for _, dirEntry := range dirEntries {
+		info, err := dirEntry.Info()
+		if err != nil {
+			// Removed since the directory was read
+			continue
+		}
+		listing.Entries = append(listing.Entries, newEntry(path.Join(name, dirEntry.Name()), info))
+	}
+	slices.SortFunc(listing.Entries, func(a, b Entry) int {
+		if a.IsDir != b.IsDir {
+			if a.IsDir {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	if len(listing.Entries) > maxEntries {
+		listing.Entries, listing.Truncated = listing.Entries[:maxEntries], true
+	}
+	return listing, nil
+}
+
+// Read returns a file with up to MaxReadBytes of its content, unless it is
+// binary.
+func (w *Workspace) Read(name string) (*Content, error) {
+	file, entry, err := w.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, MaxReadBytes+1))
+	if err != nil {
+		return nil, wrap(err, "read file")
+	}
+	content := &Content{Entry: *entry}
+	if len(data) > MaxReadBytes {
+		data, content.Truncated = data[:MaxReadBytes], true
+		// Do not cut the last character in half
+		for i := 0; i < utf8.UTFMax && !utf8.Valid(data); i++ {
+			data = data[:len(data)-1]
+		}
+	}
+	content.MimeType = mimeType(entry.Name, data)
+	if !utf8.Valid(data) || slices.Contains(data, 0) {
+		content.Binary = true
+		return content, nil
+	}
+	content.Content = string(data)
+	return content, nil
+}
+
+// Open opens a file to download; the caller closes it.
+func (w *Workspace) Open(name string) (*os.File, *Entry, error) {
+	name, err := clean(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	root, err := os.OpenRoot(w.dir)
+	if err != nil {
+		return nil, nil, wrap(err, "open workspace")
+	}
+	// The file stays open when the root is closed
+	defer root.Close()
+
+	file, err := root.Open(name)
+	if err != nil {
+		return nil, nil, wrap(err, "open file")
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, wrap(err, "stat file")
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, nil, fmt.Errorf("%w: %s is a directory", ErrInvalid, relPath(name))
+	}
+	entry := newEntry(name, info)
+	return file, &entry, nil
+}
+
+// Delete removes a file, or a directory with its content. A symbolic link is
+// removed, not its target.
+func (w *Workspace) Delete(name string) error {
+	name, err := clean(name)
+	if err != nil {
+		return err
+	}
+	if name == "." {
+		return fmt.Errorf("%w: cannot delete the workspace", ErrInvalid)
+	}
+	root, err := os.OpenRoot(w.dir)
+	if err != nil {
+		return wrap(err, "open workspace")
+	}
+	defer root.Close()
+
+	if _, err := root.Lstat(name); err != nil {
+		return wrap(err, "stat file")
+	}
+	if err := root.RemoveAll(name); err != nil {
+		return wrap(err, "delete file")
+	}
+	return nil
+}
+
+// clean returns a path of the workspace as os.Root takes it: relative, with
+// "." for the workspace itself. Paths with ".." are rejected rather than
+// clamped to the workspace.
+func clean(name string) (string, error) {
+	if strings.ContainsRune(name, 0) || slices.Contains(strings.Split(name, "/"), "..") {
+		return "", fmt.Errorf("%w: path must stay within the workspace", ErrInvalid)
+	}
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return ".", nil
+	}
+	return name, nil
+}
+
+// relPath returns a cleaned path as returned to clients, "" for the workspace.
+func relPath(name string) string {
+	if name == "." {
+		return ""
+	}
+	return name
+}
+
+func newEntry(name string, info fs.FileInfo) Entry {
+	entry := Entry{
+		Name:       info.Name(),
+		Path:       relPath(name),
+		IsDir:      info.IsDir(),
+		Symlink:    info.Mode()&fs.ModeSymlink != 0,
+		ModifiedTs: info.ModTime().Unix(),
+	}
+	if !entry.IsDir {
+		entry.Size = info.Size()
+	}
+	return entry
+}
+
+// mimeType returns the MIME type of a file from its extension, or else from
+// the start of its content.
+func mimeType(name string, data []byte) string {
+	if mimeType := mime.TypeByExtension(path.Ext(name)); mimeType != "" {
+		return mimeType
+	}
+	return http.DetectContentType(data)
+}
+
+// wrap maps the errors of the file system: missing files to ErrNotFound,
+// paths that cannot be used (escaping symbolic links, files that are not
+// directories) to ErrInvalid.
+func wrap(err error, op string) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) && !errors.Is(err, fs.ErrPermission) {
+		return fmt.Errorf("%w: %v", ErrInvalid, pathErr.Err)
+	}
+	return fmt.Errorf("failed to %s: %w", op, err)
+}
@@ -0,0 +1,121 @@
+package geekworkspace
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWorkspace returns a workspace with a few files, next to a secret file
+// outside of it.
+func newWorkspace(t *testing.T) (*Workspace, string) {
+	t.Helper()
+	base := t.TempDir()
+	dir := filepath.Join(base, "user_1")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "reports"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "reports", "summary.md"), []byte("# 周报\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "chart.png"), []byte("\x89PNG\r\n\x1a\n\x00\x00"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "secret.txt"), []byte("secret"), 0o644))
+	require.NoError(t, os.Symlink(filepath.Join(base, "secret.txt"), filepath.Join(dir, "escape.txt")))
+	require.NoError(t, os.Symlink(base, filepath.Join(dir, "parent")))
+	return New(dir), base
+}
+
+func TestList(t *testing.T) {
+	workspace, _ := newWorkspace(t)
+
+	listing, err := workspace.List("")
+	require.NoError(t, err)
+	assert.Equal(t, "", listing.Path)
+	var names []string
+	for _, entry := range listing.Entries {
+		names = append(names, entry.Name)
+	}
+	assert.Equal(t, []string{"reports", "chart.png", "escape.txt", "parent"}, names)
+	assert.True(t, listing.Entries[0].IsDir)
+	assert.True(t, listing.Entries[2].Symlink)
+
+	listing, err = workspace.List("/reports/")
+	require.NoError(t, err)
+	require.Len(t, listing.Entries, 1)
+	assert.Equal(t, Entry{Name: "summary.md", Path: "reports/summary.md", Size: 9, ModifiedTs: listing.Entries[0].ModifiedTs}, listing.Entries[0])
+
+	_, err = workspace.List("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = workspace.List("chart.png")
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	// A user who never ran a session has an empty workspace
+	listing, err = New(filepath.Join(t.TempDir(), "user_2")).List("")
+	require.NoError(t, err)
+	assert.Empty(t, listing.Entries)
+}
+
+func TestPathTraversal(t *testing.T) {
+	workspace, base := newWorkspace(t)
+
+	for _, name := range []string{"../secret.txt", "reports/../../secret.txt", "..", "escape.txt", "parent/secret.txt"} {
+		_, err := workspace.Read(name)
+		assert.ErrorIs(t, err, ErrInvalid, name)
+	}
+	_, err := workspace.List("parent")
+	assert.ErrorIs(t, err, ErrInvalid)
+	assert.ErrorIs(t, workspace.Delete("../secret.txt"), ErrInvalid)
+	assert.ErrorIs(t, workspace.Delete("parent/secret.txt"), ErrInvalid)
+
+	// Deleting a link leaves its target
+	require.NoError(t, workspace.Delete("escape.txt"))
+	_, err = os.Stat(filepath.Join(base, "secret.txt"))
+	assert.NoError(t, err)
+}
+
+func TestRead(t *testing.T) {
+	workspace, _ := newWorkspace(t)
+
+	content, err := workspace.Read("reports/summary.md")
+	require.NoError(t, err)
+	assert.Equal(t, "# 周报\n", content.Content)
+	assert.Equal(t, "text/markdown; charset=utf-8", content.MimeType)
+	assert.False(t, content.Binary)
+
+	content, err = workspace.Read("chart.png")
+	require.NoError(t, err)
+	assert.True(t, content.Binary)
+	assert.Empty(t, content.Content)
+	assert.Equal(t, "image/png", content.MimeType)
+
+	_, err = workspace.Read("reports")
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	// Large files are cut on a character boundary
+	large := strings.Repeat("a", MaxReadBytes-1) + "周"
+	require.NoError(t, os.WriteFile(filepath.Join(workspace.dir, "large.txt"), []byte(large), 0o644))
+	content, err = workspace.Read("large.txt")
+	require.NoError(t, err)
+	assert.True(t, content.Truncated)
+	assert.False(t, content.Binary)
+	assert.Len(t, content.Content, MaxReadBytes-1)
+}
+
+func TestOpenAndDelete(t *testing.T) {
+	workspace, _ := newWorkspace(t)
+
+	file, entry, err := workspace.Open("reports/summary.md")
+	require.NoError(t, err)
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	assert.Equal(t, "# 周报\n", string(data))
+	assert.Equal(t, "summary.md", entry.Name)
+
+	assert.ErrorIs(t, workspace.Delete(""), ErrInvalid)
+	assert.ErrorIs(t, workspace.Delete("missing"), ErrNotFound)
+	require.NoError(t, workspace.Delete("reports"))
+	_, err = workspace.List("reports")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,338 @@
+package geekworkspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExceeded is returned by Check for workspaces at or over their limit.
+var ErrQuotaExceeded = errors.New("geek workspace quota exceeded")
+
+// usageTTL is how long a measured workspace size is trusted.
+const usageTTL = time.Minute
+
+// tempDirs are the directories whose files are temporary.
+var tempDirs = []string{"tmp", "temp", ".tmp", ".cache"}
+
+// tempSuffixes are the suffixes of temporary files.
+var tempSuffixes = []string{".tmp", ".temp", ".swp", "~"}
+
+// QuotaConfig sets the limits of the workspaces.
+type QuotaConfig struct {
+	// LimitBytes bounds the size of each workspace; 0 for no limit.
+	LimitBytes int64
+	// MaxAge is how long unused CLI sessions and temporary files are kept;
+	// 0 keeps them.
+	MaxAge time.Duration
+	// TempFileMaxBytes bounds the size of temporary files; 0 for no bound.
+	TempFileMaxBytes int64
+	// ProjectsDir holds the CLI transcripts of the sessions, one directory
+	// per working directory; "" leaves them alone.
+	ProjectsDir string
+}
+
+// Usage is the disk usage of a workspace.
+type Usage struct {
+	Bytes int64 `json:"bytes"`
+	Files int   `json:"files"`
+	// LimitBytes is 0 for workspaces without limit.
+	LimitBytes int64 `json:"limit_bytes"`
+	MeasuredTs int64 `json:"measured_ts"`
+}
+
+// Exceeded reports whether the workspace is at or over its limit.
+func (u *Usage) Exceeded() bool {
+	return u.LimitBytes > 0 && u.Bytes >= u.LimitBytes
+}
+
+// GCReport is what a garbage collection removed.
+type GCReport struct {
+	Workspaces int   `json:"workspaces"`
+	Sessions   int   `json:"sessions"`
+	TempFiles  int   `json:"temp_files"`
+	FreedBytes int64 `json:"freed_bytes"`
+}
+
+// Quota tracks the size of the workspaces of the users, enforces their limit
+// and prunes what the sessions leave behind. Sizes are measured by walking
+// the workspace and cached for a minute, so a session may write a little
+// past the limit before its next write is rejected.
+type Quota struct {
+	baseDir string
+	cfg     QuotaConfig
+
+	mu    sync.Mutex
+	usage map[int32]*Usage
+}
+
+// NewQuota creates the quota of the workspaces in a directory.
+func NewQuota(baseDir string, cfg QuotaConfig) *Quota {
+	return &Quota{baseDir: baseDir, cfg: cfg, usage: make(map[int32]*Usage)}
+}
+
+// Usage returns the disk usage of the workspace of a user.
+func (q *Quota) Usage(userID int32) (*Usage, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if usage, ok := q.usage[userID]; ok && time.Since(time.Unix(usage.MeasuredTs, 0)) < usageTTL {
+		return usage, nil
+	}
+	usage := &Usage{LimitBytes: q.cfg.LimitBytes, MeasuredTs: time.Now().Unix()}
+	err := filepath.WalkDir(q.dir(userID), func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			// Removed since the directory was read
+			return nil
+		}
+		usage.Bytes += info.Size()
+		usage.Files++
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure workspace: %w", err)
+	}
+	q.usage[userID] = usage
+	return usage, nil
+}
+
+// Check returns ErrQuotaExceeded if the workspace of a user is full. A
+// workspace that cannot be measured is not rejected.
+func (q *Quota) Check(userID int32) error {
+	if q.cfg.LimitBytes <= 0 {
+		return nil
+	}
+	usage, err := q.Usage(userID)
+	if err != nil {
+		slog.Warn("geek workspace: failed to check quota", "user_id", userID, "error", err)
+		return nil
+	}
+	if usage.Exceeded() {
+		return fmt.Errorf("%w: %s used of %s; delete files from the workspace to continue",
+			ErrQuotaExceeded, formatMiB(usage.Bytes), formatMiB(usage.LimitBytes))
+	}
+	return nil
+}
+
+// Forget drops the cached size of the workspace of a user, e.g. after files
+// were deleted.
+func (q *Quota) Forget(userID int32) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.usage, userID)
+}
+
+// GC collects the garbage of the workspaces now; it is run as a periodic
+// job of the server.
+func (q *Quota) GC(ctx context.Context) error {
+	report, err := q.Collect(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	if report.Sessions > 0 || report.TempFiles > 0 {
+		slog.Info("geek workspace: collected garbage",
+			"workspaces", report.Workspaces,
+			"sessions", report.Sessions,
+			"temp_files", report.TempFiles,
+			"freed_bytes", report.FreedBytes)
+	}
+	return nil
+}
+
+// Collect removes from every workspace the temporary files over the size
+// bound or unused for MaxAge, and the CLI sessions unused for MaxAge. The
+// sessions of the removed transcripts start afresh instead of resuming.
+func (q *Quota) Collect(ctx context.Context, now time.Time) (*GCReport, error) {
+	entries, err := os.ReadDir(q.baseDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &GCReport{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspaces: %w", err)
+	}
+	report := &GCReport{}
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		userID, ok := workspaceUser(entry)
+		if !ok {
+			continue
+		}
+		report.Workspaces++
+		dir := filepath.Join(q.baseDir, entry.Name())
+		q.collectTempFiles(dir, now, report)
+		q.collectSessions(dir, now, report)
+		q.Forget(userID)
+	}
+	return report, nil
+}
+
+// collectTempFiles removes the temporary files of a workspace over the size
+// bound or unused for MaxAge.
+func (q *Quota) collectTempFiles(dir string, now time.Time, report *GCReport) {
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() || !isTempFile(dir, path) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		large := q.cfg.TempFileMaxBytes > 0 && info.Size() > q.cfg.TempFileMaxBytes
+		if !large && !q.stale(info, now) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			slog.Warn("geek workspace: failed to remove temp file", "path", path, "error", err)
+			return nil
+		}
+		report.TempFiles++
+		report.FreedBytes += info.Size()
+		return nil
+	})
+}
+
+// collectSessions removes the CLI sessions of a workspace unused for MaxAge:
+// their <session>.jsonl transcripts, with the <session> directories of
+// their tool results.
+func (q *Quota) collectSessions(dir string, now time.Time, report *GCReport) {
+	if q.cfg.ProjectsDir == "" || q.cfg.MaxAge <= 0 {
+		return
+	}
+	projectDir := filepath.Join(q.cfg.ProjectsDir, projectName(dir))
+	entries, err := os.ReadDir(projectDir)
+	if err != nil {
+		return
+	}
+	type session struct {
+		paths    []string
+		size     int64
+		modified time.Time
+	}
+	sessions := make(map[string]*session)
+	for _, entry := range entries {
+		path := filepath.Join(projectDir, entry.Name())
+		size, modified, err := treeStat(path)
+		if err != nil {
+			continue
+		}
+		id := strings.TrimSuffix(entry.Name(), ".jsonl")
+		s, ok := sessions[id]
+		if !ok {
+			s = &session{}
+			sessions[id] = s
+		}
+		s.paths = append(s.paths, path)
+		s.size += size
+		if modified.After(s.modified) {
+			s.modified = modified
+		}
+	}
+	for id, s := range sessions {
+		if now.Sub(s.modified) < q.cfg.MaxAge {
+			continue
+		}
+		removed := true
+		for _, path := range s.paths {
+			if err := os.RemoveAll(path); err != nil {
+				slog.Warn("geek workspace: failed to remove session", "session_id", id, "path", path, "error", err)
+				removed = false
+			}
+		}
+		if removed {
+			report.Sessions++
+			report.FreedBytes += s.size
+		}
+	}
+}
+
+// stale reports whether a file was not modified for MaxAge.
+func (q *Quota) stale(info fs.FileInfo, now time.Time) bool {
+	return q.cfg.MaxAge > 0 && now.Sub(info.ModTime()) >= q.cfg.MaxAge
+}
+
+func (q *Quota) dir(userID int32) string {
+	return filepath.Join(q.baseDir, fmt.Sprintf("user_%d", userID))
+}
+
+// workspaceUser returns the user of a user_N workspace directory.
+func workspaceUser(entry fs.DirEntry) (int32, bool) {
+	id, ok := strings.CutPrefix(entry.Name(), "user_")
+	if !ok || !entry.IsDir() {
+		return 0, false
+	}
+	userID, err := strconv.ParseInt(id, 10, 32)
+	return int32(userID), err == nil
+}
+
+// isTempFile reports whether a file of a workspace is temporary: in a
+// temporary directory, or with a temporary suffix.
+func isTempFile(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for _, part := range parts[:len(parts)-1] {
+		if slices.Contains(tempDirs, strings.ToLower(part)) {
+			return true
+		}
+	}
+	name := strings.ToLower(parts[len(parts)-1])
+	return slices.ContainsFunc(tempSuffixes, func(suffix string) bool { return strings.HasSuffix(name, suffix) })
+}
+
+var projectNamePattern = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+// projectName returns the directory the CLI keeps the transcripts of a
+// working directory in: its path with every other character than letters
+// and digits replaced by "-".
+func projectName(dir string) string {
+	return projectNamePattern.ReplaceAllString(dir, "-")
+}
+
+// treeStat returns the size of the regular files of a file or directory and
+// its last modification, the latest of its files; directories, whose times
+// change as their files are removed, do not count.
+func treeStat(root string) (size int64, modified time.Time, err error) {
+	err = filepath.WalkDir(root, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(modified) {
+			modified = info.ModTime()
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, modified, err
+}
+
+// formatMiB formats a size in MiB.
+func formatMiB(bytes int64) string {
+	return fmt.Sprintf("%.1f MiB", float64(bytes)/(1<<20))
+}
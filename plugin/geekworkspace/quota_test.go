@@ -0,0 +1,103 @@
+package geekworkspace
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile writes a file of a size, last modified at a time.
+func writeFile(t *testing.T, path string, size int, modified time.Time) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0o644))
+	require.NoError(t, os.Chtimes(path, modified, modified))
+}
+
+func TestQuota_Check(t *testing.T) {
+	base := t.TempDir()
+	dir := filepath.Join(base, "user_1")
+	writeFile(t, filepath.Join(dir, "a.bin"), 600, time.Now())
+	writeFile(t, filepath.Join(dir, "src", "b.go"), 300, time.Now())
+	quota := NewQuota(base, QuotaConfig{LimitBytes: 1000})
+
+	usage, err := quota.Usage(1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(900), usage.Bytes)
+	assert.Equal(t, 2, usage.Files)
+	assert.Equal(t, int64(1000), usage.LimitBytes)
+	assert.NoError(t, quota.Check(1))
+
+	// The cached size is kept until forgotten
+	writeFile(t, filepath.Join(dir, "c.bin"), 200, time.Now())
+	assert.NoError(t, quota.Check(1))
+	quota.Forget(1)
+	err = quota.Check(1)
+	assert.True(t, errors.Is(err, ErrQuotaExceeded))
+	assert.Contains(t, err.Error(), "delete files")
+
+	// Workspaces not created yet are empty
+	usage, err = quota.Usage(2)
+	require.NoError(t, err)
+	assert.Zero(t, usage.Bytes)
+
+	assert.NoError(t, NewQuota(base, QuotaConfig{}).Check(1), "no limit")
+}
+
+func TestQuota_CollectTempFiles(t *testing.T) {
+	base := t.TempDir()
+	dir := filepath.Join(base, "user_1")
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+	writeFile(t, filepath.Join(dir, "tmp", "old.txt"), 10, old)
+	writeFile(t, filepath.Join(dir, "tmp", "new.txt"), 10, now)
+	writeFile(t, filepath.Join(dir, "build.log.tmp"), 500, now)
+	writeFile(t, filepath.Join(dir, "report.md"), 500, old)
+	writeFile(t, filepath.Join(base, "shared", "tmp", "x.txt"), 10, old)
+	quota := NewQuota(base, QuotaConfig{MaxAge: 24 * time.Hour, TempFileMaxBytes: 100})
+
+	report, err := quota.Collect(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, &GCReport{Workspaces: 1, TempFiles: 2, FreedBytes: 510}, report)
+	assert.NoFileExists(t, filepath.Join(dir, "tmp", "old.txt"))
+	assert.NoFileExists(t, filepath.Join(dir, "build.log.tmp"))
+	assert.FileExists(t, filepath.Join(dir, "tmp", "new.txt"))
+	assert.FileExists(t, filepath.Join(dir, "report.md"), "only temporary files are collected")
+	assert.FileExists(t, filepath.Join(base, "shared", "tmp", "x.txt"), "only workspaces are collected")
+}
+
+func TestQuota_CollectSessions(t *testing.T) {
+	base := t.TempDir()
+	projects := t.TempDir()
+	dir := filepath.Join(base, "user_1")
+	require.NoError(t, os.MkdirAll(dir, 0o700))
+	project := filepath.Join(projects, projectName(dir))
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+	writeFile(t, filepath.Join(project, "s-old.jsonl"), 100, old)
+	writeFile(t, filepath.Join(project, "s-old", "tool-results", "r1.txt"), 20, old)
+	writeFile(t, filepath.Join(project, "s-new.jsonl"), 100, now)
+	// A session resumed recently is kept with its older tool results
+	writeFile(t, filepath.Join(project, "s-resumed.jsonl"), 100, now)
+	writeFile(t, filepath.Join(project, "s-resumed", "r1.txt"), 20, old)
+	quota := NewQuota(base, QuotaConfig{MaxAge: 24 * time.Hour, ProjectsDir: projects})
+
+	report, err := quota.Collect(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Sessions)
+	assert.Equal(t, int64(120), report.FreedBytes)
+	assert.NoFileExists(t, filepath.Join(project, "s-old.jsonl"))
+	assert.NoDirExists(t, filepath.Join(project, "s-old"))
+	assert.FileExists(t, filepath.Join(project, "s-new.jsonl"))
+	assert.FileExists(t, filepath.Join(project, "s-resumed", "r1.txt"))
+}
+
+func TestProjectName(t *testing.T) {
+	assert.Equal(t, "-home-ds--divinesense-claude-user-1", projectName("/home/ds/.divinesense/claude/user_1"))
+}
@@ -0,0 +1,455 @@
+// Package github gives Geek Mode sessions read access to the issues and pull
+// requests of allow-listed GitHub repositories, and lets them comment.
+//
+// Each user saves a GitHub token and the repositories it may be used on. The
+// tools are served to the Claude Code CLI of the user's sessions by an MCP
+// endpoint of the server (see MCPHandler), so that a session can triage
+// issues without a shell, git or the token: the CLI only holds a token of the
+// endpoint, which acts for its user on their allow-list.
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hrygo/divinesense/store"
+)
+
+var (
+	// ErrInvalid is returned for invalid settings and tool inputs.
+	ErrInvalid = errors.New("invalid github request")
+	// ErrNotFound is returned for users without settings, and for unknown
+	// issues and pull requests.
+	ErrNotFound = errors.New("github not found")
+	// ErrPermissionDenied is returned for repositories out of the allow-list,
+	// and for comments the user did not allow.
+	ErrPermissionDenied = errors.New("github permission denied")
+)
+
+const (
+	// DefaultAPIURL is the API of github.com.
+	DefaultAPIURL = "https://api.github.com"
+	// MaxRepositories bounds the allow-list of a user.
+	MaxRepositories = 50
+	// MaxDiffBytes bounds the pull request diffs returned to the CLI.
+	MaxDiffBytes = 200 << 10
+	// maxIssues bounds the issues listed at once.
+	maxIssues = 50
+	// maxComments bounds the comments of an issue returned at once.
+	maxComments = 30
+	// maxCommentLength is the limit of GitHub on comment bodies.
+	maxCommentLength = 65536
+	// requestTimeout bounds the calls to the GitHub API.
+	requestTimeout = 20 * time.Second
+)
+
+// repositoryPattern matches the allow-list entries: owner/name, or owner/*
+// for all the repositories of an owner.
+var repositoryPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]{0,38})/(?:\*|[a-z0-9._-]{1,100})$`)
+
+// Settings are the GitHub settings of a user.
+type Settings struct {
+	UserID int32 `json:"user_id"`
+	// Token is a GitHub token of the user. It is never returned to clients.
+	Token    string `json:"-"`
+	HasToken bool   `json:"has_token"`
+	// Repositories are the allow-listed repositories, owner/name or owner/*.
+	Repositories []string `json:"repositories"`
+	// AllowComments permits the sessions to comment on issues and pull
+	// requests; they are read-only otherwise.
+	AllowComments bool  `json:"allow_comments"`
+	UpdatedTs     int64 `json:"updated_ts"`
+}
+
+// Validate checks and normalizes settings about to be saved.
+func (s *Settings) Validate() error {
+	s.Token = strings.TrimSpace(s.Token)
+	if s.Token == "" {
+		return fmt.Errorf("%w: token is required", ErrInvalid)
+	}
+	if len(s.Repositories) > MaxRepositories {
+		return fmt.Errorf("%w: at most %d repositories", ErrInvalid, MaxRepositories)
+	}
+	repositories := []string{}
+	for _, repository := range s.Repositories {
+		repository = strings.ToLower(strings.TrimSpace(repository))
+		if !validRepository(repository) {
+			return fmt.Errorf("%w: repository %q must be owner/name or owner/*", ErrInvalid, repository)
+		}
+		if !slices.Contains(repositories, repository) {
+			repositories = append(repositories, repository)
+		}
+	}
+	s.Repositories = repositories
+	return nil
+}
+
+// Allows reports whether a repository, owner/name, is allow-listed.
+func (s *Settings) Allows(repository string) bool {
+	repository = strings.ToLower(repository)
+	owner, _, _ := strings.Cut(repository, "/")
+	return slices.Contains(s.Repositories, repository) || slices.Contains(s.Repositories, owner+"/*")
+}
+
+// Store persists the settings of users.
+type Store interface {
+	// GetSettings returns ErrNotFound for users without settings.
+	GetSettings(ctx context.Context, userID int32) (*Settings, error)
+	SaveSettings(ctx context.Context, settings *Settings) (*Settings, error)
+	DeleteSettings(ctx context.Context, userID int32) error
+}
+
+// Auditor records the comments of the sessions.
+type Auditor interface {
+	LogSecurityEvent(ctx context.Context, event *store.SecurityAuditEvent) error
+}
+
+// Issue is an issue or a pull request.
+type Issue struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	State       string    `json:"state"`
+	Body        string    `json:"body"`
+	HTMLURL     string    `json:"html_url"`
+	Comments    int       `json:"comments"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	User        user      `json:"user"`
+	Labels      []label   `json:"labels"`
+	Assignees   []user    `json:"assignees"`
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+// IsPullRequest reports whether the issue is a pull request.
+func (i *Issue) IsPullRequest() bool {
+	return i.PullRequest != nil
+}
+
+// Comment is a comment of an issue or a pull request.
+type Comment struct {
+	ID        int64     `json:"id"`
+	Body      string    `json:"body"`
+	HTMLURL   string    `json:"html_url"`
+	CreatedAt time.Time `json:"created_at"`
+	User      user      `json:"user"`
+}
+
+type user struct {
+	Login string `json:"login"`
+}
+
+type label struct {
+	Name string `json:"name"`
+}
+
+// ListIssues specifies the issues to list.
+type ListIssues struct {
+	Repository string
+	// State is open (default), closed or all.
+	State string
+	// Labels are the labels the issues all have.
+	Labels []string
+	// PullRequests includes the pull requests, which GitHub lists as issues.
+	PullRequests bool
+	Limit        int
+}
+
+// Config configures the GitHub tools.
+type Config struct {
+	// APIURL is the GitHub API, DefaultAPIURL when empty.
+	APIURL string
+	// MCPURL is the URL of the MCP endpoint, as the CLI reaches it; the
+	// sessions get no tools without it.
+	MCPURL string
+	// Secret keys the tokens of the MCP endpoint.
+	Secret string
+}
+
+// GitHub calls the GitHub API on behalf of users.
+type GitHub struct {
+	cfg     Config
+	store   Store
+	auditor Auditor
+	client  *http.Client
+}
+
+// New creates the GitHub tools.
+func New(cfg Config, store Store, auditor Auditor) *GitHub {
+	cfg.APIURL = strings.TrimRight(cfg.APIURL, "/")
+	if cfg.APIURL == "" {
+		cfg.APIURL = DefaultAPIURL
+	}
+	return &GitHub{cfg: cfg, store: store, auditor: auditor, client: &http.Client{Timeout: requestTimeout}}
+}
+
+// Settings returns the settings of a user.
+func (g *GitHub) Settings(ctx context.Context, userID int32) (*Settings, error) {
+	return g.store.GetSettings(ctx, userID)
+}
+
+// SaveSettings saves the settings of a user. An empty token keeps the saved one.
+func (g *GitHub) SaveSettings(ctx context.Context, settings *Settings) (*Settings, error) {
+	if strings.TrimSpace(settings.Token) == "" {
+		current, err := g.store.GetSettings(ctx, settings.UserID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		if current != nil {
+			settings.Token = current.Token
+		}
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	return g.store.SaveSettings(ctx, settings)
+}
+
+// DeleteSettings deletes the settings, and so the token, of a user.
+func (g *GitHub) DeleteSettings(ctx context.Context, userID int32) error {
+	return g.store.DeleteSettings(ctx, userID)
+}
+
+// ListIssues lists the issues of an allow-listed repository, latest updated
+// first.
+func (g *GitHub) ListIssues(ctx context.Context, userID int32, list *ListIssues) ([]*Issue, error) {
+	settings, err := g.authorize(ctx, userID, list.Repository)
+	if err != nil {
+		return nil, err
+	}
+	state := list.State
+	if state == "" {
+		state = "open"
+	}
+	if !slices.Contains([]string{"open", "closed", "all"}, state) {
+		return nil, fmt.Errorf("%w: state must be open, closed or all", ErrInvalid)
+	}
+	limit := list.Limit
+	if limit <= 0 || limit > maxIssues {
+		limit = maxIssues
+	}
+	query := url.Values{"state": {state}, "sort": {"updated"}, "per_page": {strconv.Itoa(maxIssues)}}
+	if len(list.Labels) > 0 {
+		query.Set("labels", strings.Join(list.Labels, ","))
+	}
+	var issues []*Issue
+	if err := g.get(ctx, settings, "/repos/"+list.Repository+"/issues?"+query.Encode(), &issues); err != nil {
+		return nil, err
+	}
+	if !list.PullRequests {
+		issues = slices.DeleteFunc(issues, (*Issue).IsPullRequest)
+	}
+	return issues[:min(limit, len(issues))], nil
+}
+
+// GetIssue returns an issue or a pull request of an allow-listed repository
+// with its latest comments.
+func (g *GitHub) GetIssue(ctx context.Context, userID int32, repository string, number int) (*Issue, []*Comment, error) {
+	settings, err := g.authorize(ctx, userID, repository)
+	if err != nil {
+		return nil, nil, err
+	}
+	if number <= 0 {
+		return nil, nil, fmt.Errorf("%w: number must be positive", ErrInvalid)
+	}
+	issue := &Issue{}
+	path := fmt.Sprintf("/repos/%s/issues/%d", repository, number)
+	if err := g.get(ctx, settings, path, issue); err != nil {
+		return nil, nil, err
+	}
+	var comments []*Comment
+	if issue.Comments > 0 {
+		// The API lists the oldest first: read the last page
+		page := (issue.Comments + maxComments - 1) / maxComments
+		if err := g.get(ctx, settings, fmt.Sprintf("%s/comments?per_page=%d&page=%d", path, maxComments, page), &comments); err != nil {
+			return nil, nil, err
+		}
+	}
+	return issue, comments, nil
+}
+
+// PullRequestDiff returns the diff of a pull request of an allow-listed
+// repository, truncated to MaxDiffBytes, and whether it was.
+func (g *GitHub) PullRequestDiff(ctx context.Context, userID int32, repository string, number int) (string, bool, error) {
+	settings, err := g.authorize(ctx, userID, repository)
+	if err != nil {
+		return "", false, err
+	}
+	if number <= 0 {
+		return "", false, fmt.Errorf("%w: number must be positive", ErrInvalid)
+	}
+	resp, err := g.do(ctx, settings, http.MethodGet, fmt.Sprintf("/repos/%s/pulls/%d", repository, number), "application/vnd.github.diff", nil)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+	diff, err := io.ReadAll(io.LimitReader(resp.Body, MaxDiffBytes+1))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read the diff: %w", err)
+	}
+	if len(diff) > MaxDiffBytes {
+		return string(diff[:MaxDiffBytes]), true, nil
+	}
+	return string(diff), false, nil
+}
+
+// Comment comments on an issue or a pull request of an allow-listed
+// repository, if the user allows comments. Every attempt is audited.
+func (g *GitHub) Comment(ctx context.Context, userID int32, repository string, number int, body string) (*Comment, error) {
+	comment, err := g.comment(ctx, userID, repository, number, body)
+	g.audit(ctx, userID, repository, number, body, err)
+	return comment, err
+}
+
+func (g *GitHub) comment(ctx context.Context, userID int32, repository string, number int, body string) (*Comment, error) {
+	settings, err := g.authorize(ctx, userID, repository)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.AllowComments {
+		return nil, fmt.Errorf("%w: comments are not allowed in the GitHub settings", ErrPermissionDenied)
+	}
+	body = strings.TrimSpace(body)
+	if number <= 0 || body == "" || len(body) > maxCommentLength {
+		return nil, fmt.Errorf("%w: a positive number and a body of up to %d bytes are required", ErrInvalid, maxCommentLength)
+	}
+	payload, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the comment: %w", err)
+	}
+	resp, err := g.do(ctx, settings, http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/comments", repository, number), "application/vnd.github+json", payload)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	comment := &Comment{}
+	if err := json.NewDecoder(resp.Body).Decode(comment); err != nil {
+		return nil, fmt.Errorf("failed to decode the comment: %w", err)
+	}
+	return comment, nil
+}
+
+// audit records a comment attempt.
+func (g *GitHub) audit(ctx context.Context, userID int32, repository string, number int, body string, err error) {
+	if g.auditor == nil {
+		return
+	}
+	taken, reason := "allowed", ""
+	if err != nil {
+		taken, reason = "denied", err.Error()
+		if !errors.Is(err, ErrPermissionDenied) && !errors.Is(err, ErrInvalid) {
+			taken = "failed"
+		}
+	}
+	event := &store.SecurityAuditEvent{
+		UserID:        userID,
+		AgentType:     "geek",
+		OperationType: "github",
+		OperationName: "comment",
+		RiskLevel:     "medium",
+		CommandInput:  fmt.Sprintf("%s#%d: %s", repository, number, truncate(body, 500)),
+		ActionTaken:   taken,
+		Reason:        reason,
+		ToolID:        "comment",
+		OccurredAt:    time.Now(),
+	}
+	// Audit must outlive a cancelled or timed-out request context.
+	if err := g.auditor.LogSecurityEvent(context.WithoutCancel(ctx), event); err != nil {
+		slog.Warn("failed to audit github comment", "repository", repository, "error", err)
+	}
+}
+
+// authorize returns the settings of a user permitting a repository.
+func (g *GitHub) authorize(ctx context.Context, userID int32, repository string) (*Settings, error) {
+	if !validRepository(strings.ToLower(repository)) || strings.HasSuffix(repository, "/*") {
+		return nil, fmt.Errorf("%w: repository must be owner/name", ErrInvalid)
+	}
+	settings, err := g.store.GetSettings(ctx, userID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("%w: GitHub is not configured", ErrPermissionDenied)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !settings.Allows(repository) {
+		return nil, fmt.Errorf("%w: %s is not in the allow-listed repositories", ErrPermissionDenied, repository)
+	}
+	return settings, nil
+}
+
+// get calls the API and decodes its JSON response into out.
+func (g *GitHub) get(ctx context.Context, settings *Settings, path string, out any) error {
+	resp, err := g.do(ctx, settings, http.MethodGet, path, "application/vnd.github+json", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode the GitHub response: %w", err)
+	}
+	return nil
+}
+
+// do calls the API with the token of the user and returns the successful
+// responses.
+func (g *GitHub) do(ctx context.Context, settings *Settings, method, path, accept string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = strings.NewReader(string(body))
+	}
+	req, err := http.NewRequestWithContext(ctx, method, g.cfg.APIURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the GitHub request: %w", err)
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("Authorization", "Bearer "+settings.Token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("User-Agent", "DivineSense")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call GitHub: %w", err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	var message struct {
+		Message string `json:"message"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&message)
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+	case http.StatusUnprocessableEntity:
+		return nil, fmt.Errorf("%w: %s", ErrInvalid, message.Message)
+	}
+	return nil, fmt.Errorf("GitHub returned HTTP %d: %s", resp.StatusCode, message.Message)
+}
+
+// validRepository reports whether a repository is owner/name or owner/*.
+func validRepository(repository string) bool {
+	_, name, _ := strings.Cut(repository, "/")
+	return repositoryPattern.MatchString(repository) && name != "." && name != ".."
+}
+
+// truncate returns the first n runes of s.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "…"
+}
@@ -0,0 +1,247 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/store"
+)
+
+type memStore struct {
+	settings map[int32]*Settings
+}
+
+func (s *memStore) GetSettings(_ context.Context, userID int32) (*Settings, error) {
+	settings, ok := s.settings[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *settings
+	return &copied, nil
+}
+
+func (s *memStore) SaveSettings(_ context.Context, settings *Settings) (*Settings, error) {
+	saved := *settings
+	saved.HasToken = saved.Token != ""
+	s.settings[settings.UserID] = &saved
+	return &saved, nil
+}
+
+func (s *memStore) DeleteSettings(_ context.Context, userID int32) error {
+	if _, ok := s.settings[userID]; !ok {
+		return ErrNotFound
+	}
+	delete(s.settings, userID)
+	return nil
+}
+
+type fakeAuditor struct {
+	mu     sync.Mutex
+	events []*store.SecurityAuditEvent
+}
+
+func (a *fakeAuditor) LogSecurityEvent(_ context.Context, event *store.SecurityAuditEvent) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.events = append(a.events, event)
+	return nil
+}
+
+// newTestGitHub serves a fake GitHub API for the token "secret" and records
+// the comments posted to it.
+func newTestGitHub(t *testing.T) (*GitHub, *fakeAuditor, *[]string) {
+	t.Helper()
+	var comments []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/acme/app/issues", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "open", r.URL.Query().Get("state"))
+		fmt.Fprint(w, `[
+			{"number": 12, "title": "Crash on start", "state": "open", "body": "Steps:\n1. open the app", "comments": 1,
+			 "user": {"login": "ann"}, "labels": [{"name": "bug"}], "assignees": [{"login": "bob"}], "updated_at": "2026-10-16T08:00:00Z"},
+			{"number": 11, "title": "Add dark mode", "state": "open", "user": {"login": "cid"}, "pull_request": {}, "updated_at": "2026-10-15T08:00:00Z"}
+		]`)
+	})
+	mux.HandleFunc("GET /repos/acme/app/issues/12", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"number": 12, "title": "Crash on start", "state": "open", "body": "Steps", "comments": 1, "user": {"login": "ann"}, "updated_at": "2026-10-16T08:00:00Z"}`)
+	})
+	mux.HandleFunc("GET /repos/acme/app/issues/12/comments", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"id": 1, "body": "Same here", "user": {"login": "dan"}, "created_at": "2026-10-16T09:00:00Z"}]`)
+	})
+	mux.HandleFunc("GET /repos/acme/app/pulls/11", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/vnd.github.diff", r.Header.Get("Accept"))
+		fmt.Fprint(w, "diff --git a/main.go b/main.go\n"+strings.Repeat("+x\n", MaxDiffBytes))
+	})
+	mux.HandleFunc("POST /repos/acme/app/issues/12/comments", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		comments = append(comments, body["body"])
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id": 2, "html_url": "https://github.com/acme/app/issues/12#issuecomment-2"}`)
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message": "Bad credentials"}`)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	auditor := &fakeAuditor{}
+	g := New(Config{APIURL: server.URL, MCPURL: "http://127.0.0.1:5230/api/v1/geek/github/mcp", Secret: "instance"},
+		&memStore{settings: map[int32]*Settings{}}, auditor)
+	_, err := g.SaveSettings(context.Background(), &Settings{UserID: 1, Token: "secret", Repositories: []string{"Acme/App", "tools/*"}})
+	require.NoError(t, err)
+	return g, auditor, &comments
+}
+
+func TestSettings(t *testing.T) {
+	settings := &Settings{Token: " t ", Repositories: []string{"Acme/App", "acme/app", "tools/*"}}
+	require.NoError(t, settings.Validate())
+	assert.Equal(t, []string{"acme/app", "tools/*"}, settings.Repositories)
+	assert.True(t, settings.Allows("ACME/app"))
+	assert.True(t, settings.Allows("tools/cli"))
+	assert.False(t, settings.Allows("acme/other"))
+
+	for _, invalid := range []*Settings{
+		{Repositories: []string{"acme/app"}},
+		{Token: "t", Repositories: []string{"acme"}},
+		{Token: "t", Repositories: []string{"acme/.."}},
+		{Token: "t", Repositories: []string{"*/*"}},
+	} {
+		assert.ErrorIs(t, invalid.Validate(), ErrInvalid, invalid)
+	}
+
+	g, _, _ := newTestGitHub(t)
+	ctx := context.Background()
+	saved, err := g.SaveSettings(ctx, &Settings{UserID: 1, Repositories: []string{"acme/app"}, AllowComments: true})
+	require.NoError(t, err, "an empty token keeps the saved one")
+	assert.True(t, saved.HasToken)
+	_, err = g.SaveSettings(ctx, &Settings{UserID: 2, Repositories: []string{"acme/app"}})
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestTools(t *testing.T) {
+	g, auditor, comments := newTestGitHub(t)
+	ctx := context.Background()
+
+	issues, err := g.ListIssues(ctx, 1, &ListIssues{Repository: "acme/app"})
+	require.NoError(t, err)
+	require.Len(t, issues, 1, "pull requests are left out")
+	assert.Equal(t, "#12 [open issue] Crash on start (@ann, updated 2026-10-16, 1 comments) labels: bug assignees: @bob", formatIssue(issues[0]))
+	issues, err = g.ListIssues(ctx, 1, &ListIssues{Repository: "acme/app", PullRequests: true})
+	require.NoError(t, err)
+	assert.Len(t, issues, 2)
+
+	issue, issueComments, err := g.GetIssue(ctx, 1, "acme/app", 12)
+	require.NoError(t, err)
+	assert.Equal(t, "Crash on start", issue.Title)
+	require.Len(t, issueComments, 1)
+	assert.Equal(t, "dan", issueComments[0].User.Login)
+
+	diff, truncated, err := g.PullRequestDiff(ctx, 1, "acme/app", 11)
+	require.NoError(t, err)
+	assert.True(t, truncated)
+	assert.Len(t, diff, MaxDiffBytes)
+
+	_, err = g.ListIssues(ctx, 1, &ListIssues{Repository: "acme/other"})
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	_, err = g.ListIssues(ctx, 1, &ListIssues{Repository: "acme/../admin"})
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = g.ListIssues(ctx, 2, &ListIssues{Repository: "acme/app"})
+	assert.ErrorIs(t, err, ErrPermissionDenied, "GitHub is not configured")
+	_, _, err = g.GetIssue(ctx, 1, "tools/cli", 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = g.Comment(ctx, 1, "acme/app", 12, "Looking into it")
+	assert.ErrorIs(t, err, ErrPermissionDenied, "comments are not allowed")
+	_, err = g.SaveSettings(ctx, &Settings{UserID: 1, Repositories: []string{"acme/app"}, AllowComments: true})
+	require.NoError(t, err)
+	comment, err := g.Comment(ctx, 1, "acme/app", 12, "Looking into it")
+	require.NoError(t, err)
+	assert.Contains(t, comment.HTMLURL, "issuecomment-2")
+	assert.Equal(t, []string{"Looking into it"}, *comments)
+	require.Len(t, auditor.events, 2, "every comment is audited")
+	assert.Equal(t, "denied", auditor.events[0].ActionTaken)
+	assert.Equal(t, "allowed", auditor.events[1].ActionTaken)
+	assert.Equal(t, "acme/app#12: Looking into it", auditor.events[1].CommandInput)
+}
+
+// callMCP posts a JSON-RPC request to the MCP endpoint with a token.
+func callMCP(t *testing.T, handler http.Handler, token, request string) (int, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewBufferString(request))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	var response map[string]any
+	if rec.Body.Len() > 0 {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	}
+	return rec.Code, response
+}
+
+func TestMCPHandler(t *testing.T) {
+	g, _, _ := newTestGitHub(t)
+	handler := g.MCPHandler()
+	token := g.Token(1)
+
+	server, ok, err := g.MCPServer(context.Background(), 1)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, ServerName, server.Name)
+	assert.Equal(t, "Bearer "+token, server.Headers["Authorization"])
+	_, ok, err = g.MCPServer(context.Background(), 2)
+	require.NoError(t, err)
+	assert.False(t, ok, "without settings")
+
+	code, _ := callMCP(t, handler, "1.forged", `{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}`)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = callMCP(t, handler, g.Token(2)[:2]+token[2:], `{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}`)
+	assert.Equal(t, http.StatusUnauthorized, code, "the token of a user is not valid for another")
+
+	code, response := callMCP(t, handler, token, `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26"}}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "2025-03-26", response["result"].(map[string]any)["protocolVersion"])
+	code, _ = callMCP(t, handler, token, `{"jsonrpc": "2.0", "method": "notifications/initialized"}`)
+	assert.Equal(t, http.StatusAccepted, code)
+
+	_, response = callMCP(t, handler, token, `{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}`)
+	listed := response["result"].(map[string]any)["tools"].([]any)
+	assert.Len(t, listed, 4)
+	assert.Equal(t, ListIssuesToolName, listed[0].(map[string]any)["name"])
+
+	_, response = callMCP(t, handler, token, `{"jsonrpc": "2.0", "id": 3, "method": "tools/call", "params": {"name": "get_issue", "arguments": {"repository": "acme/app", "number": 12}}}`)
+	result := response["result"].(map[string]any)
+	assert.Equal(t, false, result["isError"])
+	text := result["content"].([]any)[0].(map[string]any)["text"].(string)
+	assert.Contains(t, text, "#12 [open issue] Crash on start")
+	assert.Contains(t, text, "--- @dan, 2026-10-16 09:00\nSame here")
+
+	_, response = callMCP(t, handler, token, `{"jsonrpc": "2.0", "id": 4, "method": "tools/call", "params": {"name": "list_issues", "arguments": {"repository": "acme/other"}}}`)
+	result = response["result"].(map[string]any)
+	assert.Equal(t, true, result["isError"], "tool errors are results")
+
+	_, response = callMCP(t, handler, token, `{"jsonrpc": "2.0", "id": 5, "method": "tools/call", "params": {"name": "push"}}`)
+	assert.EqualValues(t, codeInvalidParams, response["error"].(map[string]any)["code"])
+	_, response = callMCP(t, handler, token, `{"jsonrpc": "2.0", "id": 6, "method": "resources/list"}`)
+	assert.EqualValues(t, codeMethodNotFound, response["error"].(map[string]any)["code"])
+}
+
+func TestNilGitHub(t *testing.T) {
+	var g *GitHub
+	_, ok, err := g.MCPServer(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,397 @@
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+)
+
+// ServerName is the name of the MCP server of the tools in the CLI, whose
+// tools are named mcp__divinesense-github__<tool>.
+const ServerName = "divinesense-github"
+
+// Names of the tools of the MCP server.
+const (
+	ListIssuesToolName = "list_issues"
+	GetIssueToolName   = "get_issue"
+	DiffToolName       = "get_pull_request_diff"
+	CommentToolName    = "comment"
+)
+
+const (
+	// protocolVersion is the latest MCP version the endpoint speaks; it also
+	// accepts the versions of supportedVersions.
+	protocolVersion = "2025-06-18"
+	// maxRequestBytes bounds the JSON-RPC requests.
+	maxRequestBytes = 1 << 20
+	// maxListedBody bounds the bodies of listed issues.
+	maxListedBody = 300
+)
+
+var supportedVersions = []string{"2024-11-05", "2025-03-26", protocolVersion}
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// tool is a tool of the MCP server.
+type tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+	run         func(g *GitHub, ctx context.Context, userID int32, args json.RawMessage) (string, error)
+}
+
+var repositoryProperty = map[string]any{"type": "string", "description": "Repository, owner/name"}
+
+var numberProperty = map[string]any{"type": "integer", "description": "Number of the issue or pull request"}
+
+var tools = []*tool{
+	{
+		Name: ListIssuesToolName,
+		Description: "List the issues of an allow-listed GitHub repository, latest updated first, " +
+			"with their labels, assignees and the start of their body.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"repository":    repositoryProperty,
+				"state":         map[string]any{"type": "string", "enum": []string{"open", "closed", "all"}, "description": "Default open"},
+				"labels":        map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Only issues with all these labels"},
+				"pull_requests": map[string]any{"type": "boolean", "description": "Include pull requests"},
+				"limit":         map[string]any{"type": "integer", "minimum": 1, "maximum": maxIssues},
+			},
+			"required": []string{"repository"},
+		},
+		run: func(g *GitHub, ctx context.Context, userID int32, args json.RawMessage) (string, error) {
+			var in struct {
+				Repository   string   `json:"repository"`
+				State        string   `json:"state"`
+				Labels       []string `json:"labels"`
+				PullRequests bool     `json:"pull_requests"`
+				Limit        int      `json:"limit"`
+			}
+			if err := decodeArguments(args, &in); err != nil {
+				return "", err
+			}
+			issues, err := g.ListIssues(ctx, userID, &ListIssues{
+				Repository: in.Repository, State: in.State, Labels: in.Labels, PullRequests: in.PullRequests, Limit: in.Limit,
+			})
+			if err != nil {
+				return "", err
+			}
+			if len(issues) == 0 {
+				return "No matching issues.", nil
+			}
+			var sb strings.Builder
+			for _, issue := range issues {
+				sb.WriteString(formatIssue(issue))
+				if body := strings.Join(strings.Fields(issue.Body), " "); body != "" {
+					sb.WriteString("\n  " + truncate(body, maxListedBody))
+				}
+				sb.WriteString("\n")
+			}
+			return strings.TrimRight(sb.String(), "\n"), nil
+		},
+	},
+	{
+		Name:        GetIssueToolName,
+		Description: "Read an issue or a pull request of an allow-listed GitHub repository with its latest comments.",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"repository": repositoryProperty, "number": numberProperty},
+			"required":   []string{"repository", "number"},
+		},
+		run: func(g *GitHub, ctx context.Context, userID int32, args json.RawMessage) (string, error) {
+			var in struct {
+				Repository string `json:"repository"`
+				Number     int    `json:"number"`
+			}
+			if err := decodeArguments(args, &in); err != nil {
+				return "", err
+			}
+			issue, comments, err := g.GetIssue(ctx, userID, in.Repository, in.Number)
+			if err != nil {
+				return "", err
+			}
+			var sb strings.Builder
+			sb.WriteString(formatIssue(issue) + "\n" + issue.HTMLURL + "\n\n" + strings.TrimSpace(issue.Body))
+			if issue.Comments > len(comments) {
+				fmt.Fprintf(&sb, "\n\n(%d earlier comments not shown)", issue.Comments-len(comments))
+			}
+			for _, comment := range comments {
+				fmt.Fprintf(&sb, "\n\n--- @%s, %s\n%s", comment.User.Login, comment.CreatedAt.Format("2006-01-02 15:04"), strings.TrimSpace(comment.Body))
+			}
+			return sb.String(), nil
+		},
+	},
+	{
+		Name:        DiffToolName,
+		Description: "Read the unified diff of a pull request of an allow-listed GitHub repository.",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"repository": repositoryProperty, "number": map[string]any{"type": "integer", "description": "Number of the pull request"}},
+			"required":   []string{"repository", "number"},
+		},
+		run: func(g *GitHub, ctx context.Context, userID int32, args json.RawMessage) (string, error) {
+			var in struct {
+				Repository string `json:"repository"`
+				Number     int    `json:"number"`
+			}
+			if err := decodeArguments(args, &in); err != nil {
+				return "", err
+			}
+			diff, truncated, err := g.PullRequestDiff(ctx, userID, in.Repository, in.Number)
+			if err != nil {
+				return "", err
+			}
+			if truncated {
+				diff += fmt.Sprintf("\n\n(diff truncated at %d KB)", MaxDiffBytes>>10)
+			}
+			return diff, nil
+		},
+	},
+	{
+		Name: CommentToolName,
+		Description: "Comment on an issue or a pull request of an allow-listed GitHub repository, as the user. " +
+			"Only when the user asked for it, and if their settings allow comments.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"repository": repositoryProperty,
+				"number":     numberProperty,
+				"body":       map[string]any{"type": "string", "description": "Markdown body of the comment"},
+			},
+			"required": []string{"repository", "number", "body"},
+		},
+		run: func(g *GitHub, ctx context.Context, userID int32, args json.RawMessage) (string, error) {
+			var in struct {
+				Repository string `json:"repository"`
+				Number     int    `json:"number"`
+				Body       string `json:"body"`
+			}
+			if err := decodeArguments(args, &in); err != nil {
+				return "", err
+			}
+			comment, err := g.Comment(ctx, userID, in.Repository, in.Number, in.Body)
+			if err != nil {
+				return "", err
+			}
+			return "✓ Commented: " + comment.HTMLURL, nil
+		},
+	},
+}
+
+// Token returns the token of the MCP endpoint of a user.
+func (g *GitHub) Token(userID int32) string {
+	id := strconv.Itoa(int(userID))
+	mac := hmac.New(sha256.New, []byte(g.cfg.Secret))
+	mac.Write([]byte("github-mcp:" + id))
+	return id + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// userFromToken returns the user of a token of the MCP endpoint.
+func (g *GitHub) userFromToken(token string) (int32, bool) {
+	id, _, ok := strings.Cut(token, ".")
+	userID, err := strconv.ParseInt(id, 10, 32)
+	if !ok || err != nil || userID <= 0 {
+		return 0, false
+	}
+	return int32(userID), hmac.Equal([]byte(token), []byte(g.Token(int32(userID))))
+}
+
+// MCPServer returns the MCP server giving the CLI of a user's sessions the
+// tools, and false if the user has not configured GitHub or the endpoint is
+// not reachable. Safe to call on a nil GitHub.
+func (g *GitHub) MCPServer(ctx context.Context, userID int32) (agentpkg.MCPServer, bool, error) {
+	if g == nil || g.cfg.MCPURL == "" {
+		return agentpkg.MCPServer{}, false, nil
+	}
+	settings, err := g.store.GetSettings(ctx, userID)
+	if errors.Is(err, ErrNotFound) {
+		return agentpkg.MCPServer{}, false, nil
+	}
+	if err != nil {
+		return agentpkg.MCPServer{}, false, err
+	}
+	if len(settings.Repositories) == 0 {
+		return agentpkg.MCPServer{}, false, nil
+	}
+	return agentpkg.MCPServer{
+		Name:      ServerName,
+		Transport: agentpkg.MCPTransportHTTP,
+		URL:       g.cfg.MCPURL,
+		Headers:   map[string]string{"Authorization": "Bearer " + g.Token(userID)},
+	}, true, nil
+}
+
+// rpcRequest is a JSON-RPC request or notification.
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// MCPHandler serves the tools over the streamable HTTP transport of MCP,
+// answering each request with a JSON response, for the tokens of Token.
+func (g *GitHub) MCPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			// No server-initiated messages
+			w.Header().Set("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		userID, ok := g.userFromToken(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBytes))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		req := &rpcRequest{}
+		if err := json.Unmarshal(body, req); err != nil {
+			writeRPC(w, nil, nil, &rpcError{Code: codeParseError, Message: "parse error"})
+			return
+		}
+		if req.JSONRPC != "2.0" || req.Method == "" {
+			writeRPC(w, req.ID, nil, &rpcError{Code: codeInvalidRequest, Message: "invalid request"})
+			return
+		}
+		if len(req.ID) == 0 {
+			// Notifications, e.g. notifications/initialized, need no answer
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		result, rpcErr := g.handle(r.Context(), userID, req)
+		writeRPC(w, req.ID, result, rpcErr)
+	})
+}
+
+// handle answers a JSON-RPC request of a user.
+func (g *GitHub) handle(ctx context.Context, userID int32, req *rpcRequest) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		version := protocolVersion
+		if slices.Contains(supportedVersions, params.ProtocolVersion) {
+			version = params.ProtocolVersion
+		}
+		return map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": ServerName, "version": "1.0.0"},
+			"instructions":    "GitHub issues and pull requests of the repositories the user allow-listed in DivineSense.",
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		return map[string]any{"tools": tools}, nil
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "invalid params"}
+		}
+		i := slices.IndexFunc(tools, func(t *tool) bool { return t.Name == params.Name })
+		if i < 0 {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool " + params.Name}
+		}
+		text, err := tools[i].run(g, ctx, userID, params.Arguments)
+		if err != nil {
+			// Tool errors are results, for the model to see
+			if !errors.Is(err, ErrInvalid) && !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrPermissionDenied) {
+				slog.Warn("github tool failed", "tool", params.Name, "user_id", userID, "error", err)
+			}
+			return toolResult(err.Error(), true), nil
+		}
+		return toolResult(text, false), nil
+	}
+	return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+}
+
+func toolResult(text string, isError bool) map[string]any {
+	return map[string]any{"content": []map[string]any{{"type": "text", "text": text}}, "isError": isError}
+}
+
+// writeRPC writes a JSON-RPC response.
+func writeRPC(w http.ResponseWriter, id json.RawMessage, result any, rpcErr *rpcError) {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	response := map[string]any{"jsonrpc": "2.0", "id": id}
+	if rpcErr != nil {
+		response["error"] = rpcErr
+	} else {
+		response["result"] = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Warn("failed to write MCP response", "error", err)
+	}
+}
+
+// decodeArguments decodes the arguments of a tool call.
+func decodeArguments(args json.RawMessage, out any) error {
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+	if err := json.Unmarshal(args, out); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return nil
+}
+
+// formatIssue reports the headline of an issue to the CLI.
+func formatIssue(issue *Issue) string {
+	kind := "issue"
+	if issue.IsPullRequest() {
+		kind = "PR"
+	}
+	line := fmt.Sprintf("#%d [%s %s] %s (@%s, updated %s, %d comments)",
+		issue.Number, issue.State, kind, issue.Title, issue.User.Login, issue.UpdatedAt.Format("2006-01-02"), issue.Comments)
+	var labels []string
+	for _, label := range issue.Labels {
+		labels = append(labels, label.Name)
+	}
+	if len(labels) > 0 {
+		line += " labels: " + strings.Join(labels, ", ")
+	}
+	var assignees []string
+	for _, assignee := range issue.Assignees {
+		assignees = append(assignees, "@"+assignee.Login)
+	}
+	if len(assignees) > 0 {
+		line += " assignees: " + strings.Join(assignees, ", ")
+	}
+	return line
+}
@@ -0,0 +1,83 @@
+package github
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/hrygo/divinesense/internal/util"
+)
+
+// DBStore persists the settings in the github_tool_settings table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new settings store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const settingsColumns = "user_id, token, repositories, allow_comments, updated_ts"
+
+// GetSettings implements Store.
+func (s *DBStore) GetSettings(ctx context.Context, userID int32) (*Settings, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+settingsColumns+" FROM github_tool_settings WHERE user_id = $1", userID)
+	settings, err := scanSettings(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get github settings: %w", err)
+	}
+	return settings, nil
+}
+
+// SaveSettings implements Store.
+func (s *DBStore) SaveSettings(ctx context.Context, settings *Settings) (*Settings, error) {
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO github_tool_settings (user_id, token, repositories, allow_comments, updated_ts)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE SET
+			token = EXCLUDED.token,
+			repositories = EXCLUDED.repositories,
+			allow_comments = EXCLUDED.allow_comments,
+			updated_ts = EXCLUDED.updated_ts
+		RETURNING `+settingsColumns,
+		settings.UserID, settings.Token, pq.Array(util.OrEmpty(settings.Repositories)), settings.AllowComments, time.Now().Unix())
+	saved, err := scanSettings(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save github settings: %w", err)
+	}
+	return saved, nil
+}
+
+// DeleteSettings implements Store.
+func (s *DBStore) DeleteSettings(ctx context.Context, userID int32) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM github_tool_settings WHERE user_id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete github settings: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSettings(row rowScanner) (*Settings, error) {
+	settings := &Settings{}
+	if err := row.Scan(&settings.UserID, &settings.Token, pq.Array(&settings.Repositories), &settings.AllowComments, &settings.UpdatedTs); err != nil {
+		return nil, err
+	}
+	settings.Repositories = util.OrEmpty(settings.Repositories)
+	settings.HasToken = settings.Token != ""
+	return settings, nil
+}
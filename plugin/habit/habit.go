@@ -0,0 +1,354 @@
+// Package habit tracks the habits of users: things to do every day or a
+// number of times a week, their logged completions and their streaks.
+//
+// Users manage their habits through the API. The default parrot coaches
+// them: it logs completions the user mentions in chat ("I went for a run
+// this morning") with the habit_log tool and sums up their streaks with the
+// habit_status tool. The streaks are also a section of the read-later digest.
+package habit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hrygo/divinesense/plugin/i18n"
+)
+
+var (
+	// ErrNotFound is returned for habits the user does not have.
+	ErrNotFound = errors.New("habit not found")
+	// ErrInvalid is returned for habits and completions that fail validation.
+	ErrInvalid = errors.New("invalid habit")
+)
+
+// Frequency is the period of the target of a habit.
+type Frequency string
+
+const (
+	FrequencyDaily  Frequency = "DAILY"
+	FrequencyWeekly Frequency = "WEEKLY"
+)
+
+const (
+	// MaxHabits is the number of habits a user may have.
+	MaxHabits = 50
+	// MaxTarget bounds the target of a habit and the count of a completion.
+	MaxTarget = 50
+	// maxNameRunes bounds the name of a habit.
+	maxNameRunes = 100
+	// statsDays is how far back completions count towards the streaks.
+	statsDays = 2 * 366
+)
+
+// Habit is something a user wants to do regularly.
+type Habit struct {
+	ID        int32     `json:"id"`
+	UserID    int32     `json:"-"`
+	Name      string    `json:"name"`
+	Frequency Frequency `json:"frequency"`
+	// Target is the number of completions per day or week.
+	Target int `json:"target"`
+	// Archived habits keep their completions but are no longer tracked.
+	Archived  bool  `json:"archived"`
+	CreatedTs int64 `json:"created_ts"`
+	UpdatedTs int64 `json:"updated_ts"`
+}
+
+// Validate checks and normalizes a habit about to be saved.
+func (h *Habit) Validate() error {
+	h.Name = strings.Join(strings.Fields(h.Name), " ")
+	if h.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalid)
+	}
+	if utf8.RuneCountInString(h.Name) > maxNameRunes {
+		return fmt.Errorf("%w: name exceeds %d characters", ErrInvalid, maxNameRunes)
+	}
+	if h.Frequency == "" {
+		h.Frequency = FrequencyDaily
+	}
+	if h.Frequency != FrequencyDaily && h.Frequency != FrequencyWeekly {
+		return fmt.Errorf("%w: frequency must be DAILY or WEEKLY", ErrInvalid)
+	}
+	if h.Target == 0 {
+		h.Target = 1
+	}
+	if h.Target < 1 || h.Target > MaxTarget {
+		return fmt.Errorf("%w: target must be between 1 and %d", ErrInvalid, MaxTarget)
+	}
+	return nil
+}
+
+// Completion is the number of times a habit was done on a day.
+type Completion struct {
+	HabitID int32 `json:"habit_id"`
+	// Date is the day, YYYY-MM-DD.
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// Status is a habit with its progress.
+type Status struct {
+	*Habit
+	// Done is the number of completions of the current day or week.
+	Done int `json:"done"`
+	// Streak is the number of consecutive days or weeks whose target was
+	// met, up to the current one. The current one counts once it is met, and
+	// does not break the streak before it ends.
+	Streak int `json:"streak"`
+	// LongestStreak is the longest streak of the last two years.
+	LongestStreak int `json:"longest_streak"`
+	// LastDate is the last day the habit was done, if any.
+	LastDate string `json:"last_date,omitempty"`
+}
+
+// Store persists the habits.
+type Store interface {
+	// ListHabits returns the habits of a user, oldest first.
+	ListHabits(ctx context.Context, userID int32) ([]*Habit, error)
+	// GetHabit returns ErrNotFound for habits the user does not have.
+	GetHabit(ctx context.Context, userID, id int32) (*Habit, error)
+	CreateHabit(ctx context.Context, habit *Habit) (*Habit, error)
+	// UpdateHabit returns ErrNotFound for habits the user does not have.
+	UpdateHabit(ctx context.Context, habit *Habit) (*Habit, error)
+	// DeleteHabit removes a habit and its completions; it returns
+	// ErrNotFound for habits the user does not have.
+	DeleteHabit(ctx context.Context, userID, id int32) error
+
+	// AddCompletion adds count completions of a habit of a user on a day. It
+	// returns ErrNotFound for habits the user does not have.
+	AddCompletion(ctx context.Context, userID, habitID int32, date string, count int) error
+	// DeleteCompletion removes the completions of a habit of a user on a
+	// day. It returns ErrNotFound when there are none.
+	DeleteCompletion(ctx context.Context, userID, habitID int32, date string) error
+	// ListCompletions returns the completions of the habits of a user from a
+	// day on, in no particular order.
+	ListCompletions(ctx context.Context, userID int32, since string) ([]Completion, error)
+}
+
+// Habits manages the habits of the users.
+type Habits struct {
+	store Store
+}
+
+// New creates the habits.
+func New(store Store) *Habits {
+	return &Habits{store: store}
+}
+
+// List returns the habits of a user, oldest first.
+func (h *Habits) List(ctx context.Context, userID int32) ([]*Habit, error) {
+	return h.store.ListHabits(ctx, userID)
+}
+
+// Create validates and saves a new habit of a user.
+func (h *Habits) Create(ctx context.Context, habit *Habit) (*Habit, error) {
+	if err := habit.Validate(); err != nil {
+		return nil, err
+	}
+	existing, err := h.store.ListHabits(ctx, habit.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxHabits {
+		return nil, fmt.Errorf("%w: at most %d habits", ErrInvalid, MaxHabits)
+	}
+	if err := checkUniqueName(existing, habit); err != nil {
+		return nil, err
+	}
+	return h.store.CreateHabit(ctx, habit)
+}
+
+// Update validates and replaces a habit of a user.
+func (h *Habits) Update(ctx context.Context, habit *Habit) (*Habit, error) {
+	if err := habit.Validate(); err != nil {
+		return nil, err
+	}
+	existing, err := h.store.ListHabits(ctx, habit.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUniqueName(existing, habit); err != nil {
+		return nil, err
+	}
+	return h.store.UpdateHabit(ctx, habit)
+}
+
+// Delete removes a habit of a user with its completions.
+func (h *Habits) Delete(ctx context.Context, userID, id int32) error {
+	return h.store.DeleteHabit(ctx, userID, id)
+}
+
+// Log records count completions of a habit of a user on a day, which cannot
+// be after the day of now, and returns the status of the habit at now.
+func (h *Habits) Log(ctx context.Context, userID, habitID int32, date string, count int, now time.Time) (*Status, error) {
+	day, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return nil, fmt.Errorf("%w: date must be YYYY-MM-DD", ErrInvalid)
+	}
+	if day.After(dayOf(now)) {
+		return nil, fmt.Errorf("%w: cannot log %s, a day in the future", ErrInvalid, date)
+	}
+	if count < 1 || count > MaxTarget {
+		return nil, fmt.Errorf("%w: count must be between 1 and %d", ErrInvalid, MaxTarget)
+	}
+	habit, err := h.store.GetHabit(ctx, userID, habitID)
+	if err != nil {
+		return nil, err
+	}
+	if habit.Archived {
+		return nil, fmt.Errorf("%w: habit %q is archived", ErrInvalid, habit.Name)
+	}
+	if err := h.store.AddCompletion(ctx, userID, habitID, date, count); err != nil {
+		return nil, err
+	}
+	statuses, err := h.statuses(ctx, userID, []*Habit{habit}, now)
+	if err != nil {
+		return nil, err
+	}
+	return statuses[0], nil
+}
+
+// Unlog removes the completions of a habit of a user on a day.
+func (h *Habits) Unlog(ctx context.Context, userID, habitID int32, date string) error {
+	if _, err := time.Parse(time.DateOnly, date); err != nil {
+		return fmt.Errorf("%w: date must be YYYY-MM-DD", ErrInvalid)
+	}
+	return h.store.DeleteCompletion(ctx, userID, habitID, date)
+}
+
+// Statuses returns the habits of a user with their progress at now, whose
+// location sets the current day. Archived habits are left out unless asked
+// for.
+func (h *Habits) Statuses(ctx context.Context, userID int32, now time.Time, archived bool) ([]*Status, error) {
+	habits, err := h.store.ListHabits(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !archived {
+		habits = slices.DeleteFunc(habits, func(habit *Habit) bool { return habit.Archived })
+	}
+	return h.statuses(ctx, userID, habits, now)
+}
+
+func (h *Habits) statuses(ctx context.Context, userID int32, habits []*Habit, now time.Time) ([]*Status, error) {
+	statuses := make([]*Status, 0, len(habits))
+	if len(habits) == 0 {
+		return statuses, nil
+	}
+	today := dayOf(now)
+	completions, err := h.store.ListCompletions(ctx, userID, today.AddDate(0, 0, -statsDays).Format(time.DateOnly))
+	if err != nil {
+		return nil, err
+	}
+	counts := map[int32]map[string]int{}
+	for _, completion := range completions {
+		if counts[completion.HabitID] == nil {
+			counts[completion.HabitID] = map[string]int{}
+		}
+		counts[completion.HabitID][completion.Date] += completion.Count
+	}
+	for _, habit := range habits {
+		statuses = append(statuses, computeStatus(habit, counts[habit.ID], today))
+	}
+	return statuses, nil
+}
+
+// DigestSection returns the streaks of the habits of a user as a section of
+// the read-later digest, or "" when the user tracks no habits.
+func (h *Habits) DigestSection(ctx context.Context, userID int32, locale i18n.Locale) (string, error) {
+	statuses, err := h.Statuses(ctx, userID, time.Now(), false)
+	if err != nil || len(statuses) == 0 {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString(i18n.T(locale, "habit.digest.title") + "\n\n")
+	for _, status := range statuses {
+		// Streaks are counted in the periods of the frequency.
+		period := "daily"
+		if status.Frequency == FrequencyWeekly {
+			period = "weekly"
+		}
+		b.WriteString(i18n.N(locale, "habit.digest.streak."+period, status.Streak, status.Name, status.Streak))
+		b.WriteString(i18n.T(locale, "habit.digest.progress."+period, status.Done, status.Target))
+		if status.LongestStreak > status.Streak {
+			b.WriteString(i18n.N(locale, "habit.digest.longest."+period, status.LongestStreak, status.LongestStreak))
+		}
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// computeStatus returns the progress of a habit on a day from the counts of
+// its completions by day.
+func computeStatus(habit *Habit, counts map[string]int, today time.Time) *Status {
+	status := &Status{Habit: habit}
+	for date := range counts {
+		status.LastDate = max(status.LastDate, date)
+	}
+
+	// Sum the completions by period, from the first period of the window to
+	// the current one
+	current := periodStart(habit.Frequency, today)
+	var totals []int
+	for start := periodStart(habit.Frequency, today.AddDate(0, 0, -statsDays)); !start.After(current); start = nextPeriod(habit.Frequency, start) {
+		total := 0
+		for day := start; day.Before(nextPeriod(habit.Frequency, start)) && !day.After(today); day = day.AddDate(0, 0, 1) {
+			total += counts[day.Format(time.DateOnly)]
+		}
+		totals = append(totals, total)
+	}
+
+	run := 0
+	for _, total := range totals {
+		if total >= habit.Target {
+			run++
+			status.LongestStreak = max(status.LongestStreak, run)
+		} else {
+			run = 0
+		}
+	}
+	status.Done = totals[len(totals)-1]
+	status.Streak = run
+	if status.Done < habit.Target {
+		// The current period is not over: the streak runs up to the last one
+		for i := len(totals) - 2; i >= 0 && totals[i] >= habit.Target; i-- {
+			status.Streak++
+		}
+	}
+	return status
+}
+
+// dayOf returns the day of a time in its location, at midnight UTC.
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// periodStart returns the first day of the day or week (from Monday) of a day.
+func periodStart(frequency Frequency, day time.Time) time.Time {
+	if frequency != FrequencyWeekly {
+		return day
+	}
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// nextPeriod returns the first day of the period after the one starting at start.
+func nextPeriod(frequency Frequency, start time.Time) time.Time {
+	if frequency == FrequencyWeekly {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+func checkUniqueName(existing []*Habit, habit *Habit) error {
+	if slices.ContainsFunc(existing, func(other *Habit) bool {
+		return other.ID != habit.ID && strings.EqualFold(other.Name, habit.Name)
+	}) {
+		return fmt.Errorf("%w: a habit named %q already exists", ErrInvalid, habit.Name)
+	}
+	return nil
+}
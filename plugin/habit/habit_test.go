@@ -0,0 +1,208 @@
+package habit
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/plugin/i18n"
+)
+
+type fakeStore struct {
+	habits      []*Habit
+	completions []Completion
+	nextID      int32
+}
+
+func (s *fakeStore) ListHabits(_ context.Context, userID int32) ([]*Habit, error) {
+	habits := []*Habit{}
+	for _, habit := range s.habits {
+		if habit.UserID == userID {
+			habits = append(habits, habit)
+		}
+	}
+	return habits, nil
+}
+
+func (s *fakeStore) GetHabit(_ context.Context, userID, id int32) (*Habit, error) {
+	for _, habit := range s.habits {
+		if habit.ID == id && habit.UserID == userID {
+			return habit, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *fakeStore) CreateHabit(_ context.Context, habit *Habit) (*Habit, error) {
+	s.nextID++
+	habit.ID = s.nextID
+	s.habits = append(s.habits, habit)
+	return habit, nil
+}
+
+func (s *fakeStore) UpdateHabit(ctx context.Context, habit *Habit) (*Habit, error) {
+	current, err := s.GetHabit(ctx, habit.UserID, habit.ID)
+	if err != nil {
+		return nil, err
+	}
+	*current = *habit
+	return current, nil
+}
+
+func (s *fakeStore) DeleteHabit(ctx context.Context, userID, id int32) error {
+	if _, err := s.GetHabit(ctx, userID, id); err != nil {
+		return err
+	}
+	s.habits = slices.DeleteFunc(s.habits, func(habit *Habit) bool { return habit.ID == id })
+	return nil
+}
+
+func (s *fakeStore) AddCompletion(ctx context.Context, userID, habitID int32, date string, count int) error {
+	if _, err := s.GetHabit(ctx, userID, habitID); err != nil {
+		return err
+	}
+	s.completions = append(s.completions, Completion{HabitID: habitID, Date: date, Count: count})
+	return nil
+}
+
+func (s *fakeStore) DeleteCompletion(ctx context.Context, userID, habitID int32, date string) error {
+	if _, err := s.GetHabit(ctx, userID, habitID); err != nil {
+		return err
+	}
+	n := len(s.completions)
+	s.completions = slices.DeleteFunc(s.completions, func(c Completion) bool { return c.HabitID == habitID && c.Date == date })
+	if len(s.completions) == n {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *fakeStore) ListCompletions(_ context.Context, _ int32, since string) ([]Completion, error) {
+	var completions []Completion
+	for _, completion := range s.completions {
+		if completion.Date >= since {
+			completions = append(completions, completion)
+		}
+	}
+	return completions, nil
+}
+
+func TestHabitValidate(t *testing.T) {
+	habit := &Habit{Name: "  Morning   run "}
+	require.NoError(t, habit.Validate())
+	assert.Equal(t, &Habit{Name: "Morning run", Frequency: FrequencyDaily, Target: 1}, habit)
+
+	assert.ErrorIs(t, (&Habit{Name: " "}).Validate(), ErrInvalid)
+	assert.ErrorIs(t, (&Habit{Name: "Run", Frequency: "MONTHLY"}).Validate(), ErrInvalid)
+	assert.ErrorIs(t, (&Habit{Name: "Run", Target: MaxTarget + 1}).Validate(), ErrInvalid)
+}
+
+func TestComputeStatus(t *testing.T) {
+	// Friday 2026-10-16
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	daily := &Habit{Frequency: FrequencyDaily, Target: 1}
+	counts := map[string]int{
+		"2026-10-08": 1, "2026-10-09": 1, "2026-10-10": 1, "2026-10-11": 1,
+		"2026-10-13": 1, "2026-10-14": 1, "2026-10-15": 1,
+	}
+
+	// Today is not done yet and does not break the streak
+	status := computeStatus(daily, counts, today)
+	assert.Equal(t, 0, status.Done)
+	assert.Equal(t, 3, status.Streak)
+	assert.Equal(t, 4, status.LongestStreak)
+	assert.Equal(t, "2026-10-15", status.LastDate)
+
+	counts["2026-10-16"] = 1
+	status = computeStatus(daily, counts, today)
+	assert.Equal(t, 1, status.Done)
+	assert.Equal(t, 4, status.Streak)
+
+	// Yesterday was missed
+	status = computeStatus(daily, counts, today.AddDate(0, 0, 2))
+	assert.Equal(t, 0, status.Streak)
+
+	// Weeks run from Monday: 2026-10-05 to 11 has 4, 12 to 16 has 4
+	weekly := &Habit{Frequency: FrequencyWeekly, Target: 4}
+	status = computeStatus(weekly, counts, today)
+	assert.Equal(t, 4, status.Done)
+	assert.Equal(t, 2, status.Streak)
+	weekly.Target = 5
+	status = computeStatus(weekly, counts, today)
+	assert.Equal(t, 0, status.Streak)
+	assert.Equal(t, 0, status.LongestStreak)
+}
+
+func TestHabitsLog(t *testing.T) {
+	ctx := context.Background()
+	habits := New(&fakeStore{})
+	run, err := habits.Create(ctx, &Habit{UserID: 1, Name: "Run", Target: 2})
+	require.NoError(t, err)
+	_, err = habits.Create(ctx, &Habit{UserID: 1, Name: "run"})
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	now := time.Date(2026, 10, 16, 21, 0, 0, 0, time.UTC)
+	status, err := habits.Log(ctx, 1, run.ID, "2026-10-16", 1, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, status.Done)
+	assert.Equal(t, 0, status.Streak)
+	status, err = habits.Log(ctx, 1, run.ID, "2026-10-16", 1, now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, status.Done)
+	assert.Equal(t, 1, status.Streak)
+
+	_, err = habits.Log(ctx, 1, run.ID, "2026-10-17", 1, now)
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = habits.Log(ctx, 1, run.ID, "yesterday", 1, now)
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = habits.Log(ctx, 2, run.ID, "2026-10-16", 1, now)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	run.Archived = true
+	_, err = habits.Update(ctx, run)
+	require.NoError(t, err)
+	_, err = habits.Log(ctx, 1, run.ID, "2026-10-16", 1, now)
+	assert.ErrorIs(t, err, ErrInvalid)
+	statuses, err := habits.Statuses(ctx, 1, now, false)
+	require.NoError(t, err)
+	assert.Empty(t, statuses)
+}
+
+func TestHabitTools(t *testing.T) {
+	ctx := context.Background()
+	habits := New(&fakeStore{})
+	_, err := habits.Create(ctx, &Habit{UserID: 1, Name: "晨跑"})
+	require.NoError(t, err)
+	_, err = habits.Create(ctx, &Habit{UserID: 1, Name: "阅读", Frequency: FrequencyWeekly, Target: 3})
+	require.NoError(t, err)
+
+	out, err := habits.LogToolFor(1).Run(ctx, `{"habit": "跑"}`)
+	require.NoError(t, err)
+	assert.Contains(t, out, "✓ Logged 晨跑 ×1")
+	assert.Contains(t, out, "晨跑: 1/1 today, streak 1 day(s)")
+
+	_, err = habits.LogToolFor(1).Run(ctx, `{"habit": "游泳"}`)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorContains(t, err, "晨跑, 阅读")
+
+	out, err = habits.StatusToolFor(1).Run(ctx, "")
+	require.NoError(t, err)
+	assert.Contains(t, out, "- 阅读: 0/3 this week, streak 0 week(s)")
+
+	section, err := habits.DigestSection(ctx, 1, i18n.LocaleSimplifiedChinese)
+	require.NoError(t, err)
+	assert.Equal(t, "## 习惯打卡\n\n- 晨跑：连续 1 天，今天 1/1\n- 阅读：连续 0 周，本周 0/3\n", section)
+	section, err = habits.DigestSection(ctx, 1, i18n.LocaleEnglish)
+	require.NoError(t, err)
+	assert.Equal(t, "## Habits\n\n- 晨跑: 1 day in a row, 1/1 today\n- 阅读: 0 weeks in a row, 0/3 this week\n", section)
+	section, err = habits.DigestSection(ctx, 2, i18n.LocaleEnglish)
+	require.NoError(t, err)
+	assert.Empty(t, section)
+
+	var nilHabits *Habits
+	assert.Nil(t, nilHabits.LogToolFor(1))
+}
@@ -0,0 +1,172 @@
+package habit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DBStore persists habits in the habit and habit_completion tables
+// (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new habit store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const habitColumns = "id, user_id, name, frequency, target, archived, created_ts, updated_ts"
+
+// ListHabits implements Store.
+func (s *DBStore) ListHabits(ctx context.Context, userID int32) ([]*Habit, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+habitColumns+" FROM habit WHERE user_id = $1 ORDER BY id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list habits: %w", err)
+	}
+	defer rows.Close()
+
+	habits := []*Habit{}
+	for rows.Next() {
+		habit, err := scanHabit(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan habit: %w", err)
+		}
+		habits = append(habits, habit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list habits: %w", err)
+	}
+	return habits, nil
+}
+
+// GetHabit implements Store.
+func (s *DBStore) GetHabit(ctx context.Context, userID, id int32) (*Habit, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+habitColumns+" FROM habit WHERE id = $1 AND user_id = $2", id, userID)
+	habit, err := scanHabit(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get habit: %w", err)
+	}
+	return habit, nil
+}
+
+// CreateHabit implements Store.
+func (s *DBStore) CreateHabit(ctx context.Context, habit *Habit) (*Habit, error) {
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO habit (user_id, name, frequency, target, archived, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		RETURNING `+habitColumns,
+		habit.UserID, habit.Name, habit.Frequency, habit.Target, habit.Archived, time.Now().Unix())
+	created, err := scanHabit(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create habit: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateHabit implements Store.
+func (s *DBStore) UpdateHabit(ctx context.Context, habit *Habit) (*Habit, error) {
+	row := s.db.QueryRowContext(ctx, `
+		UPDATE habit SET name = $3, frequency = $4, target = $5, archived = $6, updated_ts = $7
+		WHERE id = $1 AND user_id = $2
+		RETURNING `+habitColumns,
+		habit.ID, habit.UserID, habit.Name, habit.Frequency, habit.Target, habit.Archived, time.Now().Unix())
+	updated, err := scanHabit(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update habit: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteHabit implements Store. The completions go with the habit.
+func (s *DBStore) DeleteHabit(ctx context.Context, userID, id int32) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM habit WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete habit: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// AddCompletion implements Store.
+func (s *DBStore) AddCompletion(ctx context.Context, userID, habitID int32, date string, count int) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO habit_completion (habit_id, date, count, updated_ts)
+		SELECT id, $3::date, $4, $5 FROM habit WHERE id = $1 AND user_id = $2
+		ON CONFLICT (habit_id, date) DO UPDATE SET
+			count = habit_completion.count + EXCLUDED.count,
+			updated_ts = EXCLUDED.updated_ts`,
+		habitID, userID, date, count, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("failed to add habit completion: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteCompletion implements Store.
+func (s *DBStore) DeleteCompletion(ctx context.Context, userID, habitID int32, date string) error {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM habit_completion c USING habit h
+		WHERE c.habit_id = h.id AND h.id = $1 AND h.user_id = $2 AND c.date = $3::date`,
+		habitID, userID, date)
+	if err != nil {
+		return fmt.Errorf("failed to delete habit completion: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListCompletions implements Store.
+func (s *DBStore) ListCompletions(ctx context.Context, userID int32, since string) ([]Completion, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT c.habit_id, to_char(c.date, 'YYYY-MM-DD'), c.count
+		FROM habit_completion c JOIN habit h ON h.id = c.habit_id
+		WHERE h.user_id = $1 AND c.date >= $2::date`,
+		userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list habit completions: %w", err)
+	}
+	defer rows.Close()
+
+	completions := []Completion{}
+	for rows.Next() {
+		var completion Completion
+		if err := rows.Scan(&completion.HabitID, &completion.Date, &completion.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan habit completion: %w", err)
+		}
+		completions = append(completions, completion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list habit completions: %w", err)
+	}
+	return completions, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanHabit(row rowScanner) (*Habit, error) {
+	habit := &Habit{}
+	if err := row.Scan(&habit.ID, &habit.UserID, &habit.Name, &habit.Frequency, &habit.Target,
+		&habit.Archived, &habit.CreatedTs, &habit.UpdatedTs); err != nil {
+		return nil, err
+	}
+	return habit, nil
+}
@@ -0,0 +1,163 @@
+package habit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	agents "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/internal/util"
+)
+
+// Names of the agent tools of the habit coach.
+const (
+	LogToolName    = "habit_log"
+	StatusToolName = "habit_status"
+)
+
+// LogInput is the input of the habit_log tool.
+type LogInput struct {
+	// Habit is the name of the habit.
+	Habit string `json:"habit"`
+	// Date is the day the habit was done, YYYY-MM-DD; today when empty.
+	Date string `json:"date"`
+	// Count is the number of times it was done; 1 when zero.
+	Count    int    `json:"count"`
+	Timezone string `json:"timezone"`
+}
+
+// StatusInput is the input of the habit_status tool.
+type StatusInput struct {
+	Timezone string `json:"timezone"`
+}
+
+// LogToolFor returns the habit_log tool of a user, or nil without habits.
+func (h *Habits) LogToolFor(userID int32) agents.ToolWithSchema {
+	if h == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		LogToolName,
+		"Log that the user did one of their tracked habits, when they say so (\"I went for a run this morning\", \"read 30 pages yesterday\"). "+
+			"Pass the habit name as habit_status lists it and the day it was done. Returns the progress and streak of the habit. "+
+			"Do not log habits the user only plans to do.",
+		func(ctx context.Context, input string) (string, error) {
+			var in LogInput
+			if err := json.Unmarshal([]byte(input), &in); err != nil {
+				return "", fmt.Errorf("invalid input: %w", err)
+			}
+			now, err := util.NowIn(in.Timezone)
+			if err != nil {
+				return "", fmt.Errorf("%w: %w", ErrInvalid, err)
+			}
+			habit, err := h.find(ctx, userID, in.Habit)
+			if err != nil {
+				return "", err
+			}
+			if in.Date == "" {
+				in.Date = now.Format(time.DateOnly)
+			}
+			if in.Count == 0 {
+				in.Count = 1
+			}
+			status, err := h.Log(ctx, userID, habit.ID, in.Date, in.Count, now)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("✓ Logged %s ×%d on %s\n%s", status.Name, in.Count, in.Date, formatStatus(status)), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"habit":    map[string]any{"type": "string", "description": "Name of the habit"},
+				"date":     map[string]any{"type": "string", "description": "Day it was done, YYYY-MM-DD; defaults to today"},
+				"count":    map[string]any{"type": "integer", "description": "Times it was done; defaults to 1"},
+				"timezone": map[string]any{"type": "string", "description": "IANA time zone of the user, e.g. Asia/Shanghai"},
+			},
+			"required": []string{"habit"},
+		},
+	)
+}
+
+// StatusToolFor returns the habit_status tool of a user, or nil without habits.
+func (h *Habits) StatusToolFor(userID int32) agents.ToolWithSchema {
+	if h == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		StatusToolName,
+		"List the habits the user tracks with their target, progress today or this week, current streak and longest streak.",
+		func(ctx context.Context, input string) (string, error) {
+			var in StatusInput
+			if strings.TrimSpace(input) != "" {
+				if err := json.Unmarshal([]byte(input), &in); err != nil {
+					return "", fmt.Errorf("invalid input: %w", err)
+				}
+			}
+			now, err := util.NowIn(in.Timezone)
+			if err != nil {
+				return "", fmt.Errorf("%w: %w", ErrInvalid, err)
+			}
+			statuses, err := h.Statuses(ctx, userID, now, false)
+			if err != nil {
+				return "", err
+			}
+			if len(statuses) == 0 {
+				return "The user tracks no habits. They can add habits in the habit settings.", nil
+			}
+			lines := make([]string, 0, len(statuses))
+			for _, status := range statuses {
+				lines = append(lines, "- "+formatStatus(status))
+			}
+			return fmt.Sprintf("Habits (today is %s):\n%s", now.Format(time.DateOnly), strings.Join(lines, "\n")), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"timezone": map[string]any{"type": "string", "description": "IANA time zone of the user, e.g. Asia/Shanghai"},
+			},
+		},
+	)
+}
+
+// find returns the tracked habit of a user with a name, ignoring case, or the
+// only one whose name contains it.
+func (h *Habits) find(ctx context.Context, userID int32, name string) (*Habit, error) {
+	name = strings.TrimSpace(name)
+	habits, err := h.Statuses(ctx, userID, time.Now(), false)
+	if err != nil {
+		return nil, err
+	}
+	var matches []*Habit
+	for _, status := range habits {
+		if strings.EqualFold(status.Name, name) {
+			return status.Habit, nil
+		}
+		if name != "" && strings.Contains(strings.ToLower(status.Name), strings.ToLower(name)) {
+			matches = append(matches, status.Habit)
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	names := make([]string, 0, len(habits))
+	for _, status := range habits {
+		names = append(names, status.Name)
+	}
+	return nil, fmt.Errorf("%w: no tracked habit named %q; the user's habits are: %s", ErrNotFound, name, strings.Join(names, ", "))
+}
+
+// formatStatus reports the progress of a habit to the agent.
+func formatStatus(status *Status) string {
+	period, unit := "today", "day"
+	if status.Frequency == FrequencyWeekly {
+		period, unit = "this week", "week"
+	}
+	line := fmt.Sprintf("%s: %d/%d %s, streak %d %s(s), longest %d", status.Name, status.Done, status.Target, period, status.Streak, unit, status.LongestStreak)
+	if status.LastDate != "" {
+		line += ", last done " + status.LastDate
+	}
+	return line
+}
@@ -0,0 +1,363 @@
+// Package homeassistant connects the home parrot to a Home Assistant
+// instance: it reads the states of entities and calls services on them
+// ("turn off the study lights") through the Home Assistant REST API.
+//
+// The instance is configured by the DIVINESENSE_HOMEASSISTANT_* variables.
+// Only the users of the allowed roles see the tools, only the entities of the
+// allowed domains are visible and controllable, and every service call
+// (allowed, denied or failed) is written to the security audit log. The home
+// parrot calls services only after the user confirmed them with request_form.
+package homeassistant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hrygo/divinesense/internal/profile"
+	"github.com/hrygo/divinesense/store"
+)
+
+// ParrotName is the name of the home parrot, which is only routed to when
+// Home Assistant is configured.
+const ParrotName = "home"
+
+var (
+	// ErrInvalid is returned for invalid configurations and tool inputs.
+	ErrInvalid = errors.New("invalid input")
+	// ErrPermissionDenied is returned when the caller's role may not control
+	// the home, or the entity is outside of the allowed domains.
+	ErrPermissionDenied = errors.New("home control not permitted")
+)
+
+// DefaultRoles are the roles allowed to control the home by default.
+var DefaultRoles = []string{string(store.RoleHost), string(store.RoleAdmin)}
+
+// DefaultDomains are the entity domains visible and controllable by default.
+// Locks and alarms are left out: admins opt in to them explicitly.
+var DefaultDomains = []string{
+	"light", "switch", "fan", "cover", "climate", "media_player",
+	"scene", "script", "input_boolean", "sensor", "binary_sensor",
+}
+
+const (
+	// requestTimeout bounds the requests to Home Assistant.
+	requestTimeout = 10 * time.Second
+	// maxResponseBytes bounds the responses of Home Assistant.
+	maxResponseBytes = 4 << 20
+	// maxEntities is the number of entities a service call may target.
+	maxEntities = 20
+	// maxListedStates is the number of states the ha_states tool lists.
+	maxListedStates = 50
+)
+
+var (
+	domainPattern  = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	entityPattern  = regexp.MustCompile(`^([a-z][a-z0-9_]*)\.[a-z0-9_]+$`)
+	servicePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// Config configures the Home Assistant instance.
+type Config struct {
+	// URL is the base URL of the instance, e.g. http://homeassistant.local:8123.
+	URL string
+	// Token is a long-lived access token of a Home Assistant user.
+	Token string
+	// Roles are the user roles allowed to see and control the home.
+	Roles []string
+	// Domains are the entity domains visible and controllable.
+	Domains []string
+}
+
+// ConfigFromProfile returns the configuration of the DIVINESENSE_HOMEASSISTANT_*
+// variables, with the default roles and domains when they are empty.
+func ConfigFromProfile(p *profile.Profile) Config {
+	cfg := Config{
+		URL:     strings.TrimRight(p.HomeAssistantURL, "/"),
+		Token:   p.HomeAssistantToken,
+		Roles:   splitList(p.HomeAssistantRoles, strings.ToUpper),
+		Domains: splitList(p.HomeAssistantDomains, strings.ToLower),
+	}
+	if len(cfg.Roles) == 0 {
+		cfg.Roles = DefaultRoles
+	}
+	if len(cfg.Domains) == 0 {
+		cfg.Domains = DefaultDomains
+	}
+	return cfg
+}
+
+// Enabled reports whether an instance is configured.
+func (c Config) Enabled() bool {
+	return c.URL != ""
+}
+
+// Validate checks a configuration.
+func (c Config) Validate() error {
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalid)
+	}
+	if c.Token == "" {
+		return fmt.Errorf("%w: an access token is required", ErrInvalid)
+	}
+	if len(c.Roles) == 0 {
+		return fmt.Errorf("%w: at least one role is required", ErrInvalid)
+	}
+	for _, role := range c.Roles {
+		switch store.Role(role) {
+		case store.RoleHost, store.RoleAdmin, store.RoleUser:
+		default:
+			return fmt.Errorf("%w: unknown role %q", ErrInvalid, role)
+		}
+	}
+	if len(c.Domains) == 0 {
+		return fmt.Errorf("%w: at least one domain is required", ErrInvalid)
+	}
+	for _, domain := range c.Domains {
+		if !domainPattern.MatchString(domain) {
+			return fmt.Errorf("%w: invalid domain %q", ErrInvalid, domain)
+		}
+	}
+	return nil
+}
+
+// State is the state of an entity.
+type State struct {
+	EntityID    string         `json:"entity_id"`
+	State       string         `json:"state"`
+	Attributes  map[string]any `json:"attributes"`
+	LastChanged time.Time      `json:"last_changed"`
+}
+
+// Domain returns the domain of the entity, e.g. light.
+func (s *State) Domain() string {
+	domain, _, _ := strings.Cut(s.EntityID, ".")
+	return domain
+}
+
+// FriendlyName returns the name of the entity shown in Home Assistant.
+func (s *State) FriendlyName() string {
+	if name, ok := s.Attributes["friendly_name"].(string); ok {
+		return name
+	}
+	return ""
+}
+
+// Auditor records service calls; implemented by store.SecurityAuditStore.
+type Auditor interface {
+	LogSecurityEvent(ctx context.Context, event *store.SecurityAuditEvent) error
+}
+
+// Home controls a Home Assistant instance.
+type Home struct {
+	cfg     Config
+	client  *http.Client
+	auditor Auditor
+}
+
+// New creates the client of a Home Assistant instance. auditor may be nil.
+func New(cfg Config, auditor Auditor) (*Home, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Home{cfg: cfg, client: &http.Client{Timeout: requestTimeout}, auditor: auditor}, nil
+}
+
+// Allows reports whether a user with role may see and control the home.
+// Safe to call on a nil Home.
+func (h *Home) Allows(role string) bool {
+	return h != nil && slices.Contains(h.cfg.Roles, role)
+}
+
+// allowsEntity reports whether an entity is in an allowed domain.
+func (h *Home) allowsEntity(entityID string) bool {
+	match := entityPattern.FindStringSubmatch(entityID)
+	return match != nil && slices.Contains(h.cfg.Domains, match[1])
+}
+
+// States returns the states of the entities of the allowed domains, sorted by
+// entity ID. domain restricts them to a domain and query to the entities
+// whose ID or name contains it.
+func (h *Home) States(ctx context.Context, domain, query string) ([]*State, error) {
+	if domain != "" && !slices.Contains(h.cfg.Domains, domain) {
+		return nil, fmt.Errorf("%w: domain %q is not allowed", ErrPermissionDenied, domain)
+	}
+	var states []*State
+	if err := h.do(ctx, http.MethodGet, "/api/states", nil, &states); err != nil {
+		return nil, err
+	}
+	query = strings.ToLower(strings.TrimSpace(query))
+	var matched []*State
+	for _, state := range states {
+		if !h.allowsEntity(state.EntityID) || (domain != "" && state.Domain() != domain) {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(state.EntityID), query) &&
+			!strings.Contains(strings.ToLower(state.FriendlyName()), query) {
+			continue
+		}
+		matched = append(matched, state)
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].EntityID < matched[j].EntityID })
+	return matched, nil
+}
+
+// ServiceCall is a call of a Home Assistant service on entities.
+type ServiceCall struct {
+	// Domain and Service name the service, e.g. light and turn_off.
+	Domain    string
+	Service   string
+	EntityIDs []string
+	// Data are the other fields of the service, e.g. brightness_pct.
+	Data map[string]any
+}
+
+// validateCall checks a service call against the allowed domains.
+func (h *Home) validateCall(call *ServiceCall) error {
+	if !domainPattern.MatchString(call.Domain) || !servicePattern.MatchString(call.Service) {
+		return fmt.Errorf("%w: invalid service %s.%s", ErrInvalid, call.Domain, call.Service)
+	}
+	if !slices.Contains(h.cfg.Domains, call.Domain) {
+		return fmt.Errorf("%w: domain %q is not allowed", ErrPermissionDenied, call.Domain)
+	}
+	if len(call.EntityIDs) == 0 {
+		return fmt.Errorf("%w: at least one entity is required", ErrInvalid)
+	}
+	if len(call.EntityIDs) > maxEntities {
+		return fmt.Errorf("%w: at most %d entities per call", ErrInvalid, maxEntities)
+	}
+	for _, entityID := range call.EntityIDs {
+		if !entityPattern.MatchString(entityID) {
+			return fmt.Errorf("%w: invalid entity %q", ErrInvalid, entityID)
+		}
+		if !strings.HasPrefix(entityID, call.Domain+".") {
+			return fmt.Errorf("%w: %s is not a %s entity", ErrInvalid, entityID, call.Domain)
+		}
+	}
+	if _, ok := call.Data["entity_id"]; ok {
+		return fmt.Errorf("%w: entities go in entity_ids, not in data", ErrInvalid)
+	}
+	return nil
+}
+
+// CallService calls a service for a user with role and returns the states
+// the call changed.
+func (h *Home) CallService(ctx context.Context, userID int32, role string, call *ServiceCall) ([]*State, error) {
+	if !h.Allows(role) {
+		h.audit(ctx, userID, call, "denied", ErrPermissionDenied.Error())
+		return nil, ErrPermissionDenied
+	}
+	if err := h.validateCall(call); err != nil {
+		taken := "failed"
+		if errors.Is(err, ErrPermissionDenied) {
+			taken = "denied"
+		}
+		h.audit(ctx, userID, call, taken, err.Error())
+		return nil, err
+	}
+	body := map[string]any{"entity_id": call.EntityIDs}
+	for key, value := range call.Data {
+		body[key] = value
+	}
+	var changed []*State
+	path := "/api/services/" + call.Domain + "/" + call.Service
+	if err := h.do(ctx, http.MethodPost, path, body, &changed); err != nil {
+		h.audit(ctx, userID, call, "failed", err.Error())
+		return nil, err
+	}
+	h.audit(ctx, userID, call, "allowed", fmt.Sprintf("%d state(s) changed", len(changed)))
+	return changed, nil
+}
+
+// statusError is the error of the unsuccessful responses of Home Assistant.
+type statusError struct {
+	code int
+	body string
+}
+
+func (e *statusError) Error() string {
+	if e.body == "" {
+		return fmt.Sprintf("home assistant returned HTTP %d", e.code)
+	}
+	return fmt.Sprintf("home assistant returned HTTP %d: %s", e.code, e.body)
+}
+
+// do sends an authenticated request and decodes its JSON response into out.
+func (h *Home) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, h.cfg.URL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+h.cfg.Token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("home assistant request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &statusError{code: resp.StatusCode, body: strings.TrimSpace(string(detail))}
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(out); err != nil {
+		return fmt.Errorf("invalid home assistant response: %w", err)
+	}
+	return nil
+}
+
+func (h *Home) audit(ctx context.Context, userID int32, call *ServiceCall, taken, reason string) {
+	if h == nil || h.auditor == nil {
+		return
+	}
+	name := call.Domain + "." + call.Service
+	event := &store.SecurityAuditEvent{
+		UserID:        userID,
+		AgentType:     "parrot",
+		OperationType: "home_assistant",
+		OperationName: name,
+		RiskLevel:     "medium",
+		CommandInput:  name + " " + strings.Join(call.EntityIDs, ","),
+		ActionTaken:   taken,
+		Reason:        reason,
+		ToolID:        ServiceToolName,
+		OccurredAt:    time.Now(),
+	}
+	// Audit must outlive a cancelled or timed-out request context.
+	if err := h.auditor.LogSecurityEvent(context.WithoutCancel(ctx), event); err != nil {
+		slog.Warn("failed to audit home assistant call", "service", name, "error", err)
+	}
+}
+
+// splitList splits a comma-separated list, normalizing its entries.
+func splitList(value string, normalize func(string) string) []string {
+	var list []string
+	for entry := range strings.SplitSeq(value, ",") {
+		if entry = normalize(strings.TrimSpace(entry)); entry != "" && !slices.Contains(list, entry) {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
@@ -0,0 +1,182 @@
+package homeassistant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/internal/profile"
+	"github.com/hrygo/divinesense/store"
+)
+
+type fakeAuditor struct {
+	mu     sync.Mutex
+	events []*store.SecurityAuditEvent
+}
+
+func (a *fakeAuditor) LogSecurityEvent(_ context.Context, event *store.SecurityAuditEvent) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.events = append(a.events, event)
+	return nil
+}
+
+func (a *fakeAuditor) taken() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var taken []string
+	for _, event := range a.events {
+		taken = append(taken, event.OperationName+":"+event.ActionTaken)
+	}
+	return taken
+}
+
+const statesJSON = `[
+	{"entity_id": "light.study", "state": "on", "attributes": {"friendly_name": "书房灯 Study", "brightness": 180}},
+	{"entity_id": "light.living_room", "state": "off", "attributes": {"friendly_name": "客厅灯"}},
+	{"entity_id": "sensor.study_temperature", "state": "22.5", "attributes": {"friendly_name": "Study Temperature", "unit_of_measurement": "°C"}},
+	{"entity_id": "lock.front_door", "state": "locked", "attributes": {"friendly_name": "Front Door"}}
+]`
+
+// newTestHome serves a fake Home Assistant and records the service calls.
+func newTestHome(t *testing.T) (*Home, *fakeAuditor, *[]map[string]any) {
+	t.Helper()
+	var calls []map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/states", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, statesJSON)
+	})
+	mux.HandleFunc("POST /api/services/{domain}/{service}", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		body["service"] = r.PathValue("domain") + "." + r.PathValue("service")
+		calls = append(calls, body)
+		fmt.Fprint(w, `[{"entity_id": "light.study", "state": "off", "attributes": {"friendly_name": "书房灯 Study"}}]`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	auditor := &fakeAuditor{}
+	home, err := New(Config{URL: server.URL, Token: "secret", Roles: DefaultRoles, Domains: DefaultDomains}, auditor)
+	require.NoError(t, err)
+	return home, auditor, &calls
+}
+
+func TestConfig(t *testing.T) {
+	cfg := ConfigFromProfile(&profile.Profile{HomeAssistantURL: "http://ha.local:8123/", HomeAssistantToken: "secret"})
+	assert.True(t, cfg.Enabled())
+	assert.Equal(t, "http://ha.local:8123", cfg.URL)
+	assert.Equal(t, DefaultRoles, cfg.Roles)
+	assert.Equal(t, DefaultDomains, cfg.Domains)
+	assert.NoError(t, cfg.Validate())
+
+	cfg = ConfigFromProfile(&profile.Profile{
+		HomeAssistantURL:     "https://ha.example.com",
+		HomeAssistantToken:   "secret",
+		HomeAssistantRoles:   "host, user,HOST",
+		HomeAssistantDomains: "Light, lock",
+	})
+	assert.Equal(t, []string{"HOST", "USER"}, cfg.Roles)
+	assert.Equal(t, []string{"light", "lock"}, cfg.Domains)
+	assert.NoError(t, cfg.Validate())
+
+	assert.False(t, ConfigFromProfile(&profile.Profile{}).Enabled())
+	for _, invalid := range []Config{
+		{URL: "ha.local", Token: "secret", Roles: DefaultRoles, Domains: DefaultDomains},
+		{URL: "http://ha.local", Roles: DefaultRoles, Domains: DefaultDomains},
+		{URL: "http://ha.local", Token: "secret", Roles: []string{"GUEST"}, Domains: DefaultDomains},
+		{URL: "http://ha.local", Token: "secret", Roles: DefaultRoles, Domains: []string{"light.study"}},
+	} {
+		_, err := New(invalid, nil)
+		assert.ErrorIs(t, err, ErrInvalid)
+	}
+}
+
+func TestStates(t *testing.T) {
+	home, _, _ := newTestHome(t)
+	ctx := context.Background()
+
+	states, err := home.States(ctx, "", "study")
+	require.NoError(t, err)
+	require.Len(t, states, 2)
+	assert.Equal(t, "light.study", states[0].EntityID)
+	assert.Equal(t, "sensor.study_temperature", states[1].EntityID)
+
+	states, err = home.States(ctx, "", "书房")
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+
+	states, err = home.States(ctx, "light", "")
+	require.NoError(t, err)
+	assert.Len(t, states, 2)
+
+	states, err = home.States(ctx, "", "front door")
+	require.NoError(t, err)
+	assert.Empty(t, states, "locks are not in the default domains")
+	_, err = home.States(ctx, "lock", "")
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	tool := home.StatesToolFor("ADMIN")
+	require.NotNil(t, tool)
+	out, err := tool.Run(ctx, `{"query": "study"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "- light.study (书房灯 Study): on [brightness=180]\n"+
+		"- sensor.study_temperature (Study Temperature): 22.5 °C", out)
+	assert.Nil(t, home.StatesToolFor("USER"))
+}
+
+func TestCallService(t *testing.T) {
+	home, auditor, calls := newTestHome(t)
+	ctx := context.Background()
+
+	tool := home.ServiceToolFor(1, "HOST")
+	require.NotNil(t, tool)
+	out, err := tool.Run(ctx, `{"domain": "light", "service": "turn_on", "entity_ids": ["light.study"], "data": {"brightness_pct": 30}}`)
+	require.NoError(t, err)
+	assert.Equal(t, "✓ Called light.turn_on on light.study\n- light.study (书房灯 Study): off", out)
+	require.Len(t, *calls, 1)
+	assert.Equal(t, "light.turn_on", (*calls)[0]["service"])
+	assert.Equal(t, []any{"light.study"}, (*calls)[0]["entity_id"])
+	assert.EqualValues(t, 30, (*calls)[0]["brightness_pct"])
+
+	for _, call := range []*ServiceCall{
+		{Domain: "light", Service: "turn_off"},
+		{Domain: "light", Service: "turn_off", EntityIDs: []string{"switch.kettle"}},
+		{Domain: "light", Service: "turn_off", EntityIDs: []string{"all"}},
+		{Domain: "light", Service: "../states", EntityIDs: []string{"light.study"}},
+		{Domain: "light", Service: "turn_on", EntityIDs: []string{"light.study"}, Data: map[string]any{"entity_id": "light.living_room"}},
+	} {
+		_, err := home.CallService(ctx, 1, "HOST", call)
+		assert.ErrorIs(t, err, ErrInvalid, call)
+	}
+	_, err = home.CallService(ctx, 1, "HOST", &ServiceCall{Domain: "lock", Service: "unlock", EntityIDs: []string{"lock.front_door"}})
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	_, err = home.CallService(ctx, 2, "USER", &ServiceCall{Domain: "light", Service: "turn_off", EntityIDs: []string{"light.study"}})
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	assert.Nil(t, home.ServiceToolFor(2, "USER"))
+
+	assert.Len(t, *calls, 1, "rejected calls never reach Home Assistant")
+	taken := auditor.taken()
+	assert.Equal(t, "light.turn_on:allowed", taken[0])
+	assert.Equal(t, "lock.unlock:denied", taken[len(taken)-2])
+	assert.Equal(t, "light.turn_off:denied", taken[len(taken)-1])
+	assert.Len(t, taken, 8, "every call is audited")
+}
+
+func TestNilHome(t *testing.T) {
+	var home *Home
+	assert.False(t, home.Allows("HOST"))
+	assert.Nil(t, home.StatesToolFor("HOST"))
+	assert.Nil(t, home.ServiceToolFor(1, "HOST"))
+}
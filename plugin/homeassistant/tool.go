@@ -0,0 +1,149 @@
+package homeassistant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	agents "github.com/hrygo/divinesense/ai/agents"
+)
+
+// Names of the agent tools of the home parrot.
+const (
+	StatesToolName  = "ha_states"
+	ServiceToolName = "ha_call_service"
+)
+
+// stateAttributes are the attributes of states reported to the agent, besides
+// the unit of measurement.
+var stateAttributes = []string{
+	"brightness", "color_temp_kelvin", "current_temperature", "temperature",
+	"hvac_action", "current_position", "percentage", "volume_level", "media_title",
+}
+
+// StatesInput is the input of the ha_states tool.
+type StatesInput struct {
+	Domain string `json:"domain"`
+	Query  string `json:"query"`
+}
+
+// ServiceInput is the input of the ha_call_service tool.
+type ServiceInput struct {
+	Domain    string         `json:"domain"`
+	Service   string         `json:"service"`
+	EntityIDs []string       `json:"entity_ids"`
+	Data      map[string]any `json:"data"`
+}
+
+// StatesToolFor returns the ha_states tool of a user with role, or nil when
+// the role may not see the home.
+func (h *Home) StatesToolFor(role string) agents.ToolWithSchema {
+	if !h.Allows(role) {
+		return nil
+	}
+	return agents.NewNativeTool(
+		StatesToolName,
+		"List the devices and sensors of the user's home from Home Assistant with their current state. "+
+			"Use it to find the entity IDs to control (search by room or device name, e.g. study or 书房) "+
+			"and to answer questions about the state of the home. Allowed domains: "+strings.Join(h.cfg.Domains, ", ")+".",
+		func(ctx context.Context, input string) (string, error) {
+			var in StatesInput
+			if strings.TrimSpace(input) != "" {
+				if err := json.Unmarshal([]byte(input), &in); err != nil {
+					return "", fmt.Errorf("invalid input: %w", err)
+				}
+			}
+			states, err := h.States(ctx, in.Domain, in.Query)
+			if err != nil {
+				return "", err
+			}
+			if len(states) == 0 {
+				return "No matching entities.", nil
+			}
+			var sb strings.Builder
+			for i, state := range states {
+				if i == maxListedStates {
+					fmt.Fprintf(&sb, "... and %d more; narrow the query.\n", len(states)-maxListedStates)
+					break
+				}
+				sb.WriteString("- " + formatState(state) + "\n")
+			}
+			return strings.TrimRight(sb.String(), "\n"), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"domain": map[string]any{"type": "string", "enum": h.cfg.Domains, "description": "Only list entities of this domain"},
+				"query":  map[string]any{"type": "string", "description": "Only list entities whose ID or name contains this, e.g. study"},
+			},
+		},
+	)
+}
+
+// ServiceToolFor returns the ha_call_service tool of a user with role, or nil
+// when the role may not control the home.
+func (h *Home) ServiceToolFor(userID int32, role string) agents.ToolWithSchema {
+	if !h.Allows(role) {
+		return nil
+	}
+	return agents.NewNativeTool(
+		ServiceToolName,
+		"Call a Home Assistant service on entities of the user's home, e.g. light.turn_off or climate.set_temperature. "+
+			"Only call it with an action the user confirmed: first show the action with request_form, stop, "+
+			"and call ha_call_service once the user submitted the form. Take entity IDs from ha_states; never guess them.",
+		func(ctx context.Context, input string) (string, error) {
+			var in ServiceInput
+			if err := json.Unmarshal([]byte(input), &in); err != nil {
+				return "", fmt.Errorf("invalid input: %w", err)
+			}
+			changed, err := h.CallService(ctx, userID, role, &ServiceCall{
+				Domain:    in.Domain,
+				Service:   in.Service,
+				EntityIDs: in.EntityIDs,
+				Data:      in.Data,
+			})
+			if err != nil {
+				return "", err
+			}
+			var sb strings.Builder
+			fmt.Fprintf(&sb, "✓ Called %s.%s on %s", in.Domain, in.Service, strings.Join(in.EntityIDs, ", "))
+			for _, state := range changed {
+				sb.WriteString("\n- " + formatState(state))
+			}
+			return sb.String(), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"domain":     map[string]any{"type": "string", "enum": h.cfg.Domains, "description": "Domain of the service and of the entities, e.g. light"},
+				"service":    map[string]any{"type": "string", "description": "Service, e.g. turn_on, turn_off, toggle, set_temperature"},
+				"entity_ids": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Entity IDs from ha_states, e.g. light.study"},
+				"data":       map[string]any{"type": "object", "description": "Other service fields, e.g. {\"brightness_pct\": 30} or {\"temperature\": 24}"},
+			},
+			"required": []string{"domain", "service", "entity_ids"},
+		},
+	)
+}
+
+// formatState reports a state to the agent.
+func formatState(state *State) string {
+	line := state.EntityID
+	if name := state.FriendlyName(); name != "" {
+		line += " (" + name + ")"
+	}
+	line += ": " + state.State
+	if unit, ok := state.Attributes["unit_of_measurement"].(string); ok {
+		line += " " + unit
+	}
+	var attributes []string
+	for _, key := range stateAttributes {
+		if value, ok := state.Attributes[key]; ok && value != nil {
+			attributes = append(attributes, fmt.Sprintf("%s=%v", key, value))
+		}
+	}
+	if len(attributes) > 0 {
+		line += " [" + strings.Join(attributes, ", ") + "]"
+	}
+	return line
+}
@@ -0,0 +1,363 @@
+// Package httpaction lets admins register external HTTP endpoints ("actions")
+// that parrots can call as tools, e.g. home automation or ticketing webhooks.
+//
+// Each action carries its own JSON Schema for arguments, an optional auth
+// header, an RBAC role allow-list and a per-action rate limit. Every invocation
+// (allowed, denied or failed) is written to the security audit log.
+package httpaction
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	agents "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/store"
+)
+
+// ToolPrefix is prepended to action names to form the tool name seen by the LLM.
+const ToolPrefix = "action_"
+
+const (
+	// DefaultTimeout applies when an action does not set TimeoutSeconds.
+	DefaultTimeout = 10 * time.Second
+	// MaxTimeout caps TimeoutSeconds.
+	MaxTimeout = 60 * time.Second
+	// maxResponseBytes bounds how much of the response body is returned to the agent.
+	maxResponseBytes = 16 * 1024
+	// actionCacheTTL bounds how long enabled actions are cached before reloading.
+	actionCacheTTL = 30 * time.Second
+)
+
+var (
+	// ErrRateLimited is returned when an action exceeds its per-minute limit.
+	ErrRateLimited = errors.New("action rate limit exceeded")
+	// ErrPermissionDenied is returned when the caller's role is not allowed.
+	ErrPermissionDenied = errors.New("action not permitted for this user")
+
+	namePattern      = regexp.MustCompile(`^[a-z][a-z0-9_]{1,47}$`)
+	placeholderRegex = regexp.MustCompile(`\{([a-zA-Z_][a-zA-Z0-9_]*)\}`)
+	allowedMethods   = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+)
+
+// Action is an admin-registered HTTP endpoint exposed to parrots as a tool.
+type Action struct {
+	ID          int32  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Method      string `json:"method"`
+	// URL may contain {param} placeholders filled from tool arguments.
+	URL string `json:"url"`
+	// Parameters is the JSON Schema describing tool arguments.
+	Parameters map[string]any `json:"parameters"`
+	// AuthHeader is the header name (e.g. "Authorization") sent with AuthValue.
+	AuthHeader string `json:"auth_header"`
+	// AuthValue is the secret header value. It is never serialized to clients.
+	AuthValue string `json:"-"`
+	// AllowedRoles restricts which user roles see the tool. Empty means all roles.
+	AllowedRoles       []string `json:"allowed_roles"`
+	RateLimitPerMinute int32    `json:"rate_limit_per_minute"`
+	TimeoutSeconds     int32    `json:"timeout_seconds"`
+	Enabled            bool     `json:"enabled"`
+	CreatorID          int32    `json:"creator_id"`
+	CreatedTs          int64    `json:"created_ts"`
+	UpdatedTs          int64    `json:"updated_ts"`
+}
+
+// ToolName returns the tool name the LLM sees for this action.
+func (a *Action) ToolName() string {
+	return ToolPrefix + a.Name
+}
+
+// Allows reports whether a user with role may invoke the action.
+func (a *Action) Allows(role string) bool {
+	return len(a.AllowedRoles) == 0 || slices.Contains(a.AllowedRoles, role)
+}
+
+// Validate checks the fields an admin supplies.
+func (a *Action) Validate() error {
+	if !namePattern.MatchString(a.Name) {
+		return fmt.Errorf("name must match %s", namePattern.String())
+	}
+	if strings.TrimSpace(a.Description) == "" {
+		return errors.New("description is required")
+	}
+	if !slices.Contains(allowedMethods, a.Method) {
+		return fmt.Errorf("unsupported method %q", a.Method)
+	}
+	u, err := url.Parse(placeholderRegex.ReplaceAllString(a.URL, "x"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http(s) URL")
+	}
+	for _, role := range a.AllowedRoles {
+		switch store.Role(role) {
+		case store.RoleHost, store.RoleAdmin, store.RoleUser:
+		default:
+			return fmt.Errorf("unknown role %q", role)
+		}
+	}
+	if a.RateLimitPerMinute < 0 {
+		return errors.New("rate_limit_per_minute must not be negative")
+	}
+	if a.TimeoutSeconds < 0 || time.Duration(a.TimeoutSeconds)*time.Second > MaxTimeout {
+		return fmt.Errorf("timeout_seconds must be between 0 and %d", int(MaxTimeout.Seconds()))
+	}
+	return nil
+}
+
+func (a *Action) timeout() time.Duration {
+	if a.TimeoutSeconds <= 0 {
+		return DefaultTimeout
+	}
+	return time.Duration(a.TimeoutSeconds) * time.Second
+}
+
+// ActionLister loads actions; implemented by ActionStore.
+type ActionLister interface {
+	ListActions(ctx context.Context, find *FindAction) ([]*Action, error)
+}
+
+// Auditor records action invocations; implemented by store.SecurityAuditStore.
+type Auditor interface {
+	LogSecurityEvent(ctx context.Context, event *store.SecurityAuditEvent) error
+}
+
+type limiterEntry struct {
+	perMinute int32
+	limiter   *rate.Limiter
+}
+
+// Registry serves enabled actions as tools and executes them.
+type Registry struct {
+	lister  ActionLister
+	auditor Auditor
+	client  *http.Client
+
+	mu       sync.Mutex
+	cache    []*Action
+	loadedAt time.Time
+	limiters map[int32]*limiterEntry
+}
+
+// NewRegistry creates an action registry. auditor may be nil.
+func NewRegistry(lister ActionLister, auditor Auditor) *Registry {
+	return &Registry{
+		lister:   lister,
+		auditor:  auditor,
+		client:   &http.Client{},
+		limiters: make(map[int32]*limiterEntry),
+	}
+}
+
+// Invalidate drops the action cache so the next call reloads from the store.
+// Called by the admin API after any mutation.
+func (r *Registry) Invalidate() {
+	r.mu.Lock()
+	r.cache = nil
+	r.mu.Unlock()
+}
+
+// ToolsFor returns the enabled actions visible to a user with role, wrapped as tools.
+// Safe to call on a nil Registry.
+func (r *Registry) ToolsFor(ctx context.Context, userID int32, role string) []agents.ToolWithSchema {
+	if r == nil {
+		return nil
+	}
+	var tools []agents.ToolWithSchema
+	for _, action := range r.actions(ctx) {
+		if !action.Allows(role) {
+			continue
+		}
+		params := action.Parameters
+		if params == nil {
+			params = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		tools = append(tools, agents.NewNativeTool(
+			action.ToolName(),
+			action.Description,
+			func(ctx context.Context, input string) (string, error) {
+				return r.Invoke(ctx, action, userID, role, input)
+			},
+			params,
+		))
+	}
+	return tools
+}
+
+// Invoke calls the action with JSON-encoded arguments and returns the response body.
+// URL placeholders are filled first; remaining arguments go to the query string
+// for GET/DELETE and to a JSON body otherwise.
+func (r *Registry) Invoke(ctx context.Context, action *Action, userID int32, role, input string) (string, error) {
+	if !action.Allows(role) {
+		r.audit(ctx, action, userID, "denied", ErrPermissionDenied.Error())
+		return "", ErrPermissionDenied
+	}
+	if !r.allow(action) {
+		r.audit(ctx, action, userID, "rate_limited", ErrRateLimited.Error())
+		return "", ErrRateLimited
+	}
+
+	args := map[string]any{}
+	if strings.TrimSpace(input) != "" {
+		if err := json.Unmarshal([]byte(input), &args); err != nil {
+			r.audit(ctx, action, userID, "failed", "invalid arguments")
+			return "", fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+
+	req, err := buildRequest(ctx, action, args)
+	if err != nil {
+		r.audit(ctx, action, userID, "failed", err.Error())
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, action.timeout())
+	defer cancel()
+	resp, err := r.client.Do(req.WithContext(ctx))
+	if err != nil {
+		r.audit(ctx, action, userID, "failed", err.Error())
+		return "", fmt.Errorf("call action %s: %w", action.Name, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		r.audit(ctx, action, userID, "failed", err.Error())
+		return "", fmt.Errorf("read action %s response: %w", action.Name, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		reason := fmt.Sprintf("HTTP %d", resp.StatusCode)
+		r.audit(ctx, action, userID, "failed", reason)
+		return "", fmt.Errorf("action %s returned %s: %s", action.Name, reason, string(body))
+	}
+
+	r.audit(ctx, action, userID, "allowed", fmt.Sprintf("HTTP %d", resp.StatusCode))
+	return string(body), nil
+}
+
+func buildRequest(ctx context.Context, action *Action, args map[string]any) (*http.Request, error) {
+	rawURL := placeholderRegex.ReplaceAllStringFunc(action.URL, func(m string) string {
+		key := m[1 : len(m)-1]
+		v, ok := args[key]
+		if !ok {
+			return m
+		}
+		delete(args, key)
+		return url.PathEscape(fmt.Sprint(v))
+	})
+	if placeholderRegex.MatchString(rawURL) {
+		return nil, fmt.Errorf("missing URL arguments for action %s", action.Name)
+	}
+
+	var body io.Reader
+	if action.Method == http.MethodGet || action.Method == http.MethodDelete {
+		if len(args) > 0 {
+			u, err := url.Parse(rawURL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid action url: %w", err)
+			}
+			query := u.Query()
+			for k, v := range args {
+				query.Set(k, fmt.Sprint(v))
+			}
+			u.RawQuery = query.Encode()
+			rawURL = u.String()
+		}
+	} else {
+		payload, err := json.Marshal(args)
+		if err != nil {
+			return nil, fmt.Errorf("encode arguments: %w", err)
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, action.Method, rawURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("build action request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if action.AuthHeader != "" && action.AuthValue != "" {
+		req.Header.Set(action.AuthHeader, action.AuthValue)
+	}
+	return req, nil
+}
+
+// allow consumes one token from the action's limiter.
+// The limiter is rebuilt when the admin changes the configured rate.
+func (r *Registry) allow(action *Action) bool {
+	if action.RateLimitPerMinute <= 0 {
+		return true
+	}
+	r.mu.Lock()
+	entry, ok := r.limiters[action.ID]
+	if !ok || entry.perMinute != action.RateLimitPerMinute {
+		perMinute := int(action.RateLimitPerMinute)
+		entry = &limiterEntry{
+			perMinute: action.RateLimitPerMinute,
+			limiter:   rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), perMinute),
+		}
+		r.limiters[action.ID] = entry
+	}
+	r.mu.Unlock()
+	return entry.limiter.Allow()
+}
+
+func (r *Registry) audit(ctx context.Context, action *Action, userID int32, taken, reason string) {
+	if r.auditor == nil {
+		return
+	}
+	risk := "medium"
+	if action.Method == http.MethodGet {
+		risk = "low"
+	}
+	event := &store.SecurityAuditEvent{
+		UserID:        userID,
+		AgentType:     "parrot",
+		OperationType: "http_action",
+		OperationName: action.Name,
+		RiskLevel:     risk,
+		CommandInput:  action.Method + " " + action.URL,
+		ActionTaken:   taken,
+		Reason:        reason,
+		ToolID:        action.ToolName(),
+		OccurredAt:    time.Now(),
+	}
+	// Audit must outlive a cancelled or timed-out request context.
+	if err := r.auditor.LogSecurityEvent(context.WithoutCancel(ctx), event); err != nil {
+		slog.Warn("failed to audit http action", "action", action.Name, "error", err)
+	}
+}
+
+func (r *Registry) actions(ctx context.Context) []*Action {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cache != nil && time.Since(r.loadedAt) < actionCacheTTL {
+		return r.cache
+	}
+
+	enabled := true
+	actions, err := r.lister.ListActions(ctx, &FindAction{Enabled: &enabled})
+	if err != nil {
+		slog.Warn("failed to load http actions", "error", err)
+		// Keep serving the stale cache (if any) rather than flapping.
+		return r.cache
+	}
+	r.cache = actions
+	r.loadedAt = time.Now()
+	return actions
+}
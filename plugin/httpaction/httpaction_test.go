@@ -0,0 +1,109 @@
+package httpaction
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/store"
+)
+
+type fakeLister struct {
+	actions []*Action
+}
+
+func (f *fakeLister) ListActions(_ context.Context, _ *FindAction) ([]*Action, error) {
+	return f.actions, nil
+}
+
+type fakeAuditor struct {
+	mu     sync.Mutex
+	events []*store.SecurityAuditEvent
+}
+
+func (f *fakeAuditor) LogSecurityEvent(_ context.Context, event *store.SecurityAuditEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+	return nil
+}
+
+func TestActionValidate(t *testing.T) {
+	valid := &Action{Name: "open_ticket", Description: "Open a ticket", Method: http.MethodPost, URL: "https://tickets.example.com/api/{queue}"}
+	require.NoError(t, valid.Validate())
+
+	bad := *valid
+	bad.Name = "Open Ticket"
+	require.Error(t, bad.Validate())
+
+	bad = *valid
+	bad.URL = "ftp://example.com"
+	require.Error(t, bad.Validate())
+
+	bad = *valid
+	bad.AllowedRoles = []string{"ROOT"}
+	require.Error(t, bad.Validate())
+}
+
+func TestRegistryInvoke(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("X-Api-Key")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	action := &Action{
+		ID:                 1,
+		Name:               "lights",
+		Description:        "Toggle lights",
+		Method:             http.MethodPost,
+		URL:                server.URL + "/rooms/{room}",
+		AuthHeader:         "X-Api-Key",
+		AuthValue:          "secret",
+		AllowedRoles:       []string{string(store.RoleAdmin)},
+		RateLimitPerMinute: 1,
+		Enabled:            true,
+	}
+	auditor := &fakeAuditor{}
+	registry := NewRegistry(&fakeLister{actions: []*Action{action}}, auditor)
+
+	require.Empty(t, registry.ToolsFor(context.Background(), 2, string(store.RoleUser)))
+	tools := registry.ToolsFor(context.Background(), 1, string(store.RoleAdmin))
+	require.Len(t, tools, 1)
+	require.Equal(t, "action_lights", tools[0].Name())
+
+	out, err := tools[0].Run(context.Background(), `{"room":"living room","on":true}`)
+	require.NoError(t, err)
+	require.Equal(t, `{"ok":true}`, out)
+	require.Equal(t, "/rooms/living room", gotPath)
+	require.Equal(t, "secret", gotAuth)
+	require.Equal(t, map[string]any{"on": true}, gotBody)
+
+	_, err = tools[0].Run(context.Background(), `{"room":"kitchen"}`)
+	require.ErrorIs(t, err, ErrRateLimited)
+
+	_, err = registry.Invoke(context.Background(), action, 2, string(store.RoleUser), `{}`)
+	require.ErrorIs(t, err, ErrPermissionDenied)
+
+	require.Len(t, auditor.events, 3)
+	require.Equal(t, "allowed", auditor.events[0].ActionTaken)
+	require.Equal(t, "rate_limited", auditor.events[1].ActionTaken)
+	require.Equal(t, "denied", auditor.events[2].ActionTaken)
+	require.Equal(t, "http_action", auditor.events[0].OperationType)
+}
+
+func TestRegistryNil(t *testing.T) {
+	var registry *Registry
+	require.Nil(t, registry.ToolsFor(context.Background(), 1, string(store.RoleUser)))
+}
@@ -0,0 +1,225 @@
+package httpaction
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FindAction specifies conditions for listing actions.
+type FindAction struct {
+	ID      *int32
+	Enabled *bool
+}
+
+// UpdateAction specifies fields to update on an action. Nil fields are left unchanged.
+type UpdateAction struct {
+	ID                 int32
+	Description        *string
+	Method             *string
+	URL                *string
+	Parameters         map[string]any
+	AuthHeader         *string
+	AuthValue          *string
+	AllowedRoles       []string
+	RateLimitPerMinute *int32
+	TimeoutSeconds     *int32
+	Enabled            *bool
+}
+
+// ActionStore persists actions in the http_action table (PostgreSQL).
+type ActionStore struct {
+	db *sql.DB
+}
+
+// NewActionStore creates a new action store.
+func NewActionStore(db *sql.DB) *ActionStore {
+	return &ActionStore{db: db}
+}
+
+const actionColumns = "id, name, description, method, url, parameters, auth_header, auth_value, allowed_roles, rate_limit_per_minute, timeout_seconds, enabled, creator_id, created_ts, updated_ts"
+
+// CreateAction inserts a new action.
+func (s *ActionStore) CreateAction(ctx context.Context, action *Action) (*Action, error) {
+	params, roles, err := encodeJSONFields(action.Parameters, action.AllowedRoles)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	query := `
+		INSERT INTO http_action (name, description, method, url, parameters, auth_header, auth_value, allowed_roles, rate_limit_per_minute, timeout_seconds, enabled, creator_id, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING ` + actionColumns
+	row := s.db.QueryRowContext(ctx, query,
+		action.Name, action.Description, action.Method, action.URL, params, action.AuthHeader, action.AuthValue, roles,
+		action.RateLimitPerMinute, action.TimeoutSeconds, action.Enabled, action.CreatorID, now, now)
+	created, err := scanAction(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http action: %w", err)
+	}
+	return created, nil
+}
+
+// ListActions lists actions matching find, ordered by name.
+func (s *ActionStore) ListActions(ctx context.Context, find *FindAction) ([]*Action, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if find.ID != nil {
+		args = append(args, *find.ID)
+		where = append(where, fmt.Sprintf("id = $%d", len(args)))
+	}
+	if find.Enabled != nil {
+		args = append(args, *find.Enabled)
+		where = append(where, fmt.Sprintf("enabled = $%d", len(args)))
+	}
+
+	query := "SELECT " + actionColumns + " FROM http_action WHERE " + strings.Join(where, " AND ") + " ORDER BY name"
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list http actions: %w", err)
+	}
+	defer rows.Close()
+
+	actions := []*Action{}
+	for rows.Next() {
+		action, err := scanAction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan http action: %w", err)
+		}
+		actions = append(actions, action)
+	}
+	return actions, rows.Err()
+}
+
+// GetAction returns an action by ID, or nil if it does not exist.
+func (s *ActionStore) GetAction(ctx context.Context, id int32) (*Action, error) {
+	actions, err := s.ListActions(ctx, &FindAction{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(actions) == 0 {
+		return nil, nil
+	}
+	return actions[0], nil
+}
+
+// UpdateAction applies a partial update and returns the updated action.
+func (s *ActionStore) UpdateAction(ctx context.Context, update *UpdateAction) (*Action, error) {
+	set, args := []string{}, []any{}
+	add := func(column string, value any) {
+		args = append(args, value)
+		set = append(set, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if update.Description != nil {
+		add("description", *update.Description)
+	}
+	if update.Method != nil {
+		add("method", *update.Method)
+	}
+	if update.URL != nil {
+		add("url", *update.URL)
+	}
+	if update.Parameters != nil {
+		params, _, err := encodeJSONFields(update.Parameters, nil)
+		if err != nil {
+			return nil, err
+		}
+		add("parameters", params)
+	}
+	if update.AuthHeader != nil {
+		add("auth_header", *update.AuthHeader)
+	}
+	if update.AuthValue != nil {
+		add("auth_value", *update.AuthValue)
+	}
+	if update.AllowedRoles != nil {
+		_, roles, err := encodeJSONFields(nil, update.AllowedRoles)
+		if err != nil {
+			return nil, err
+		}
+		add("allowed_roles", roles)
+	}
+	if update.RateLimitPerMinute != nil {
+		add("rate_limit_per_minute", *update.RateLimitPerMinute)
+	}
+	if update.TimeoutSeconds != nil {
+		add("timeout_seconds", *update.TimeoutSeconds)
+	}
+	if update.Enabled != nil {
+		add("enabled", *update.Enabled)
+	}
+	add("updated_ts", time.Now().Unix())
+	args = append(args, update.ID)
+
+	query := "UPDATE http_action SET " + strings.Join(set, ", ") + fmt.Sprintf(" WHERE id = $%d RETURNING ", len(args)) + actionColumns
+	action, err := scanAction(s.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update http action: %w", err)
+	}
+	return action, nil
+}
+
+// DeleteAction removes an action by ID.
+func (s *ActionStore) DeleteAction(ctx context.Context, id int32) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM http_action WHERE id = $1", id); err != nil {
+		return fmt.Errorf("failed to delete http action: %w", err)
+	}
+	return nil
+}
+
+func encodeJSONFields(params map[string]any, roles []string) (string, string, error) {
+	if params == nil {
+		params = map[string]any{}
+	}
+	if roles == nil {
+		roles = []string{}
+	}
+	p, err := json.Marshal(params)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode parameters: %w", err)
+	}
+	r, err := json.Marshal(roles)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode allowed roles: %w", err)
+	}
+	return string(p), string(r), nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAction(row rowScanner) (*Action, error) {
+	action := &Action{}
+	var params, roles []byte
+	if err := row.Scan(
+		&action.ID,
+		&action.Name,
+		&action.Description,
+		&action.Method,
+		&action.URL,
+		&params,
+		&action.AuthHeader,
+		&action.AuthValue,
+		&roles,
+		&action.RateLimitPerMinute,
+		&action.TimeoutSeconds,
+		&action.Enabled,
+		&action.CreatorID,
+		&action.CreatedTs,
+		&action.UpdatedTs,
+	); err != nil {
+		return nil, err
+	}
+	if len(params) > 0 && string(params) != "{}" {
+		if err := json.Unmarshal(params, &action.Parameters); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+	}
+	if err := json.Unmarshal(roles, &action.AllowedRoles); err != nil {
+		return nil, fmt.Errorf("invalid allowed roles: %w", err)
+	}
+	return action, nil
+}
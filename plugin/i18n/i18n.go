@@ -0,0 +1,107 @@
+// Package i18n localizes the content the server writes for users: digests,
+// reports, system-generated memos and the system messages of chat apps.
+//
+// Strings live in the message catalogs of locales/, one JSON object per
+// locale keyed by message, with fmt verbs for their arguments. The locale of
+// a user is the one of their general settings, as the web app uses it; a
+// locale without a catalog falls back to the catalog of its language, then
+// to DefaultLocale, whose catalog has every message.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Locale is a locale of the web app, e.g. en or zh-Hans.
+type Locale string
+
+const (
+	LocaleEnglish           Locale = "en"
+	LocaleSimplifiedChinese Locale = "zh-Hans"
+	// DefaultLocale is the locale of the users who did not choose one.
+	DefaultLocale = LocaleSimplifiedChinese
+)
+
+// Resolver returns the locale of a user.
+type Resolver func(ctx context.Context, userID int32) Locale
+
+//go:embed locales/*.json
+var catalogFiles embed.FS
+
+// catalogs are the messages by locale.
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[Locale]map[string]string {
+	entries, err := catalogFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	loaded := make(map[Locale]map[string]string, len(entries))
+	for _, entry := range entries {
+		data, err := catalogFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		messages := map[string]string{}
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog %s: %v", entry.Name(), err))
+		}
+		loaded[Locale(strings.TrimSuffix(entry.Name(), ".json"))] = messages
+	}
+	if loaded[DefaultLocale] == nil {
+		panic("i18n: no catalog for the default locale")
+	}
+	return loaded
+}
+
+// Match returns the locale with a catalog closest to a locale tag, e.g.
+// zh-Hans for zh-CN and en for en-US, or DefaultLocale for unknown tags.
+func Match(tag string) Locale {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	for locale := range catalogs {
+		if strings.EqualFold(string(locale), tag) {
+			return locale
+		}
+	}
+	language, _, _ := strings.Cut(tag, "-")
+	for locale := range catalogs {
+		base, _, _ := strings.Cut(string(locale), "-")
+		if language != "" && strings.EqualFold(base, language) {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
+
+// T returns a message in a locale, formatted with its arguments. Messages
+// missing from the catalog of the locale are taken from the one of
+// DefaultLocale; unknown messages are returned as their key.
+func T(locale Locale, key string, args ...any) string {
+	message, ok := catalogs[Match(string(locale))][key]
+	if !ok {
+		if message, ok = catalogs[DefaultLocale][key]; !ok {
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// N returns a message of a count in a locale: the key.one message when n is
+// 1 and the locale has one, else the key.other message. The count is not
+// an argument of the message unless passed in args.
+func N(locale Locale, key string, n int, args ...any) string {
+	if n == 1 {
+		if _, ok := catalogs[Match(string(locale))][key+".one"]; ok {
+			return T(locale, key+".one", args...)
+		}
+	}
+	return T(locale, key+".other", args...)
+}
@@ -0,0 +1,79 @@
+package i18n
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	for tag, want := range map[string]Locale{
+		"en":      LocaleEnglish,
+		"en-US":   LocaleEnglish,
+		"zh-hans": LocaleSimplifiedChinese,
+		"zh_CN":   LocaleSimplifiedChinese,
+		"fr":      DefaultLocale,
+		"":        DefaultLocale,
+	} {
+		assert.Equal(t, want, Match(tag), tag)
+	}
+}
+
+func TestT(t *testing.T) {
+	assert.Equal(t, "…and 3 more", T("en-GB", "common.more", 3))
+	assert.Equal(t, "……另有 3 项", T("", "common.more", 3))
+	assert.Equal(t, "missing.key", T(LocaleEnglish, "missing.key"))
+	assert.Equal(t, "12 memos: 1 orphaned, 4 not updated for 90 days, 0 broken links",
+		T(LocaleEnglish, "kbhealth.report.summary", 12, 1, 90, 4, 0), "arguments are reordered by index")
+}
+
+func TestN(t *testing.T) {
+	assert.Equal(t, " (1 day overdue)", N(LocaleEnglish, "contact.digest.overdue", 1, 1))
+	assert.Equal(t, " (2 days overdue)", N(LocaleEnglish, "contact.digest.overdue", 2, 2))
+	assert.Equal(t, "（已逾期 1 天）", N(LocaleSimplifiedChinese, "contact.digest.overdue", 1, 1), "languages without a singular use other")
+}
+
+var verbPattern = regexp.MustCompile(`%(?:\[(\d+)\])?([a-zA-Z%])`)
+
+// verbs returns the verbs of a message by argument index.
+func verbs(message string) map[int]string {
+	found := map[int]string{}
+	next := 1
+	for _, match := range verbPattern.FindAllStringSubmatch(message, -1) {
+		if match[2] == "%" {
+			continue
+		}
+		if match[1] != "" {
+			next, _ = strconv.Atoi(match[1])
+		}
+		found[next] = match[2]
+		next++
+	}
+	return found
+}
+
+// TestCatalogs checks that the catalogs have the messages of the default
+// locale, with the same arguments.
+func TestCatalogs(t *testing.T) {
+	defaults := catalogs[DefaultLocale]
+	for locale, messages := range catalogs {
+		for key, message := range messages {
+			defaultKey := key
+			if base, ok := strings.CutSuffix(key, ".one"); ok {
+				defaultKey = base + ".other"
+			}
+			want, ok := defaults[defaultKey]
+			if !assert.True(t, ok, "%s: %s is not in the default catalog", locale, key) {
+				continue
+			}
+			assert.Equal(t, verbs(want), verbs(message), "%s: %s", locale, key)
+		}
+		for key := range defaults {
+			_, ok := messages[key]
+			assert.True(t, ok, "%s: %s is missing", locale, key)
+		}
+	}
+}
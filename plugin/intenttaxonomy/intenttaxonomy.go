@@ -0,0 +1,265 @@
+// Package intenttaxonomy is the admin-managed taxonomy of chat intents.
+//
+// Intents are defined by a name, example phrases, the expert they route to
+// and whether they need the orchestrator. The router matches chat inputs
+// against the examples before its built-in rules, and reloads the taxonomy
+// whenever it changes. Taxonomies are exported and imported as JSON documents
+// to copy them between instances.
+package intenttaxonomy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned for intents that do not exist.
+	ErrNotFound = errors.New("intent not found")
+	// ErrExists is returned when creating an intent whose name is taken.
+	ErrExists = errors.New("intent already exists")
+	// ErrInvalid wraps the validation errors of intents and imports.
+	ErrInvalid = errors.New("invalid taxonomy")
+)
+
+// ExportVersion is the version of exported taxonomy documents.
+const ExportVersion = 1
+
+const (
+	maxExamples      = 50
+	maxExampleLength = 200
+)
+
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// Intent is an intent of the taxonomy.
+type Intent struct {
+	Name string `json:"name"`
+	// Examples are phrases of the intent; chat inputs containing one match.
+	Examples []string `json:"examples"`
+	// TargetExpert is the expert the intent routes to.
+	TargetExpert string `json:"target_expert"`
+	// Orchestrate sends the intent to the orchestrator instead of the expert.
+	Orchestrate bool  `json:"orchestrate"`
+	CreatedTs   int64 `json:"created_ts,omitempty"`
+	UpdatedTs   int64 `json:"updated_ts,omitempty"`
+}
+
+// normalize trims the examples and drops empty or duplicate ones.
+func (i *Intent) normalize() {
+	i.Name = strings.TrimSpace(i.Name)
+	i.TargetExpert = strings.TrimSpace(i.TargetExpert)
+	examples := make([]string, 0, len(i.Examples))
+	for _, example := range i.Examples {
+		example = strings.TrimSpace(example)
+		if example != "" && !slices.Contains(examples, example) {
+			examples = append(examples, example)
+		}
+	}
+	i.Examples = examples
+}
+
+// validate checks an intent against the experts it may target.
+func (i *Intent) validate(targets []string) error {
+	if !namePattern.MatchString(i.Name) {
+		return fmt.Errorf("%w: name must be lowercase letters, digits and underscores, starting with a letter", ErrInvalid)
+	}
+	if len(i.Examples) == 0 {
+		return fmt.Errorf("%w: intent %s needs at least one example", ErrInvalid, i.Name)
+	}
+	if len(i.Examples) > maxExamples {
+		return fmt.Errorf("%w: intent %s has more than %d examples", ErrInvalid, i.Name, maxExamples)
+	}
+	for _, example := range i.Examples {
+		if len(example) > maxExampleLength {
+			return fmt.Errorf("%w: examples of intent %s must be at most %d bytes", ErrInvalid, i.Name, maxExampleLength)
+		}
+	}
+	if i.TargetExpert == "" && !i.Orchestrate {
+		return fmt.Errorf("%w: intent %s needs a target expert unless it is orchestrated", ErrInvalid, i.Name)
+	}
+	if i.TargetExpert != "" && !slices.Contains(targets, i.TargetExpert) {
+		return fmt.Errorf("%w: target expert of intent %s must be one of %s", ErrInvalid, i.Name, strings.Join(targets, ", "))
+	}
+	return nil
+}
+
+// Export is an exported taxonomy.
+type Export struct {
+	Version    int       `json:"version"`
+	ExportedAt int64     `json:"exported_at"`
+	Intents    []*Intent `json:"intents"`
+}
+
+// Store persists the taxonomy.
+type Store interface {
+	// ListIntents returns the intents ordered by name.
+	ListIntents(ctx context.Context) ([]*Intent, error)
+	// GetIntent returns nil if the intent does not exist.
+	GetIntent(ctx context.Context, name string) (*Intent, error)
+	// SaveIntent inserts or updates an intent.
+	SaveIntent(ctx context.Context, intent *Intent) (*Intent, error)
+	// DeleteIntent returns ErrNotFound if the intent does not exist.
+	DeleteIntent(ctx context.Context, name string) error
+	// SaveIntents saves intents in one transaction; with replace, the
+	// intents not among them are deleted.
+	SaveIntents(ctx context.Context, intents []*Intent, replace bool) error
+}
+
+// Taxonomy serves the intent taxonomy and keeps the router in sync.
+type Taxonomy struct {
+	store    Store
+	targets  []string
+	onChange func([]*Intent)
+
+	mu      sync.Mutex
+	intents []*Intent // nil until loaded
+}
+
+// New creates the taxonomy. targets are the experts intents may route to;
+// onChange, if not nil, receives the intents after every change.
+func New(store Store, targets []string, onChange func([]*Intent)) *Taxonomy {
+	return &Taxonomy{store: store, targets: targets, onChange: onChange}
+}
+
+// Intents returns the current intents, loading them on first use.
+func (t *Taxonomy) Intents(ctx context.Context) ([]*Intent, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.intents != nil {
+		return t.intents, nil
+	}
+	intents, err := t.store.ListIntents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.intents = intents
+	if t.intents == nil {
+		t.intents = []*Intent{}
+	}
+	return t.intents, nil
+}
+
+// Get returns an intent.
+func (t *Taxonomy) Get(ctx context.Context, name string) (*Intent, error) {
+	intent, err := t.store.GetIntent(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if intent == nil {
+		return nil, ErrNotFound
+	}
+	return intent, nil
+}
+
+// Create adds an intent.
+func (t *Taxonomy) Create(ctx context.Context, intent *Intent) (*Intent, error) {
+	intent.normalize()
+	if err := intent.validate(t.targets); err != nil {
+		return nil, err
+	}
+	existing, err := t.store.GetIntent(ctx, intent.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrExists
+	}
+	return t.save(ctx, intent)
+}
+
+// Update replaces the definition of an existing intent.
+func (t *Taxonomy) Update(ctx context.Context, intent *Intent) (*Intent, error) {
+	intent.normalize()
+	if err := intent.validate(t.targets); err != nil {
+		return nil, err
+	}
+	if _, err := t.Get(ctx, intent.Name); err != nil {
+		return nil, err
+	}
+	return t.save(ctx, intent)
+}
+
+func (t *Taxonomy) save(ctx context.Context, intent *Intent) (*Intent, error) {
+	saved, err := t.store.SaveIntent(ctx, intent)
+	if err != nil {
+		return nil, err
+	}
+	return saved, t.reload(ctx)
+}
+
+// Delete removes an intent.
+func (t *Taxonomy) Delete(ctx context.Context, name string) error {
+	if err := t.store.DeleteIntent(ctx, name); err != nil {
+		return err
+	}
+	return t.reload(ctx)
+}
+
+// Export returns the taxonomy as a document for Import.
+func (t *Taxonomy) Export(ctx context.Context) (*Export, error) {
+	intents, err := t.store.ListIntents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exported := make([]*Intent, 0, len(intents))
+	for _, intent := range intents {
+		exported = append(exported, &Intent{
+			Name:         intent.Name,
+			Examples:     intent.Examples,
+			TargetExpert: intent.TargetExpert,
+			Orchestrate:  intent.Orchestrate,
+		})
+	}
+	return &Export{Version: ExportVersion, ExportedAt: time.Now().Unix(), Intents: exported}, nil
+}
+
+// Import saves the intents of an exported taxonomy, all or none. With
+// replace, the intents missing from the document are deleted; otherwise they
+// are kept and the imported ones are added or updated.
+func (t *Taxonomy) Import(ctx context.Context, export *Export, replace bool) (int, error) {
+	if export.Version != ExportVersion {
+		return 0, fmt.Errorf("%w: unsupported version %d", ErrInvalid, export.Version)
+	}
+	seen := make(map[string]bool, len(export.Intents))
+	for _, intent := range export.Intents {
+		if intent == nil {
+			return 0, fmt.Errorf("%w: null intent", ErrInvalid)
+		}
+		intent.normalize()
+		if err := intent.validate(t.targets); err != nil {
+			return 0, err
+		}
+		if seen[intent.Name] {
+			return 0, fmt.Errorf("%w: duplicate intent %s", ErrInvalid, intent.Name)
+		}
+		seen[intent.Name] = true
+	}
+	if err := t.store.SaveIntents(ctx, export.Intents, replace); err != nil {
+		return 0, err
+	}
+	return len(export.Intents), t.reload(ctx)
+}
+
+// reload refreshes the current intents and notifies the router.
+func (t *Taxonomy) reload(ctx context.Context) error {
+	intents, err := t.store.ListIntents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to reload intent taxonomy: %w", err)
+	}
+	if intents == nil {
+		intents = []*Intent{}
+	}
+	t.mu.Lock()
+	t.intents = intents
+	t.mu.Unlock()
+	if t.onChange != nil {
+		t.onChange(intents)
+	}
+	return nil
+}
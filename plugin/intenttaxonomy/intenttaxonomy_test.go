@@ -0,0 +1,173 @@
+package intenttaxonomy
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	intents map[string]*Intent
+}
+
+func (s *fakeStore) ListIntents(_ context.Context) ([]*Intent, error) {
+	var intents []*Intent
+	for _, intent := range s.intents {
+		intents = append(intents, intent)
+	}
+	sort.Slice(intents, func(i, j int) bool { return intents[i].Name < intents[j].Name })
+	return intents, nil
+}
+
+func (s *fakeStore) GetIntent(_ context.Context, name string) (*Intent, error) {
+	return s.intents[name], nil
+}
+
+func (s *fakeStore) SaveIntent(_ context.Context, intent *Intent) (*Intent, error) {
+	s.intents[intent.Name] = intent
+	return intent, nil
+}
+
+func (s *fakeStore) DeleteIntent(_ context.Context, name string) error {
+	if _, ok := s.intents[name]; !ok {
+		return ErrNotFound
+	}
+	delete(s.intents, name)
+	return nil
+}
+
+func (s *fakeStore) SaveIntents(_ context.Context, intents []*Intent, replace bool) error {
+	if replace {
+		s.intents = map[string]*Intent{}
+	}
+	for _, intent := range intents {
+		s.intents[intent.Name] = intent
+	}
+	return nil
+}
+
+var testTargets = []string{"memo", "schedule", "general"}
+
+func names(intents []*Intent) []string {
+	var names []string
+	for _, intent := range intents {
+		names = append(names, intent.Name)
+	}
+	return names
+}
+
+func TestIntentValidate(t *testing.T) {
+	valid := &Intent{Name: "weekly_review", Examples: []string{"周回顾"}, TargetExpert: "memo"}
+	require.NoError(t, valid.validate(testTargets))
+	require.NoError(t, (&Intent{Name: "trip", Examples: []string{"旅行"}, Orchestrate: true}).validate(testTargets))
+
+	for _, invalid := range []*Intent{
+		{Name: "Weekly", Examples: []string{"周回顾"}, TargetExpert: "memo"},
+		{Name: "1st", Examples: []string{"周回顾"}, TargetExpert: "memo"},
+		{Name: "weekly", TargetExpert: "memo"},
+		{Name: "weekly", Examples: []string{"周回顾"}},
+		{Name: "weekly", Examples: []string{"周回顾"}, TargetExpert: "calendar"},
+	} {
+		assert.ErrorIs(t, invalid.validate(testTargets), ErrInvalid, "%+v", invalid)
+	}
+
+	intent := &Intent{Name: " weekly ", Examples: []string{" 周回顾 ", "", "周回顾", "复盘"}, TargetExpert: "memo "}
+	intent.normalize()
+	assert.Equal(t, &Intent{Name: "weekly", Examples: []string{"周回顾", "复盘"}, TargetExpert: "memo"}, intent)
+}
+
+func TestTaxonomyCRUD(t *testing.T) {
+	ctx := context.Background()
+	var changes [][]*Intent
+	taxonomy := New(&fakeStore{intents: map[string]*Intent{}}, testTargets, func(intents []*Intent) {
+		changes = append(changes, intents)
+	})
+
+	intents, err := taxonomy.Intents(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, intents)
+
+	_, err = taxonomy.Create(ctx, &Intent{Name: "weekly_review", Examples: []string{"周回顾"}, TargetExpert: "memo"})
+	require.NoError(t, err)
+	_, err = taxonomy.Create(ctx, &Intent{Name: "weekly_review", Examples: []string{"复盘"}, TargetExpert: "memo"})
+	assert.ErrorIs(t, err, ErrExists)
+	_, err = taxonomy.Create(ctx, &Intent{Name: "bad", Examples: []string{"x"}, TargetExpert: "calendar"})
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	_, err = taxonomy.Update(ctx, &Intent{Name: "missing", Examples: []string{"x"}, TargetExpert: "memo"})
+	assert.ErrorIs(t, err, ErrNotFound)
+	updated, err := taxonomy.Update(ctx, &Intent{Name: "weekly_review", Examples: []string{"周回顾", "复盘"}, Orchestrate: true})
+	require.NoError(t, err)
+	assert.True(t, updated.Orchestrate)
+
+	intents, err = taxonomy.Intents(ctx)
+	require.NoError(t, err)
+	require.Len(t, intents, 1)
+	assert.Equal(t, []string{"周回顾", "复盘"}, intents[0].Examples)
+
+	_, err = taxonomy.Get(ctx, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, taxonomy.Delete(ctx, "missing"), ErrNotFound)
+	require.NoError(t, taxonomy.Delete(ctx, "weekly_review"))
+
+	// The router is notified of every change
+	require.Len(t, changes, 3)
+	assert.Empty(t, changes[2])
+}
+
+func TestTaxonomyExportImport(t *testing.T) {
+	ctx := context.Background()
+	notified := 0
+	source := New(&fakeStore{intents: map[string]*Intent{}}, testTargets, nil)
+	_, err := source.Create(ctx, &Intent{Name: "weekly_review", Examples: []string{"周回顾"}, TargetExpert: "memo"})
+	require.NoError(t, err)
+	_, err = source.Create(ctx, &Intent{Name: "trip", Examples: []string{"旅行计划"}, Orchestrate: true})
+	require.NoError(t, err)
+
+	export, err := source.Export(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ExportVersion, export.Version)
+	assert.Equal(t, []string{"trip", "weekly_review"}, names(export.Intents))
+
+	store := &fakeStore{intents: map[string]*Intent{
+		"standup": {Name: "standup", Examples: []string{"站会"}, TargetExpert: "schedule"},
+	}}
+	target := New(store, testTargets, func([]*Intent) { notified++ })
+
+	// Merge keeps the existing intents
+	imported, err := target.Import(ctx, export, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, imported)
+	intents, err := target.Intents(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"standup", "trip", "weekly_review"}, names(intents))
+
+	// Replace deletes the intents missing from the document
+	_, err = target.Import(ctx, &Export{Version: ExportVersion, Intents: export.Intents[:1]}, true)
+	require.NoError(t, err)
+	intents, err = target.Intents(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"trip"}, names(intents))
+	assert.Equal(t, 2, notified)
+
+	// Invalid documents change nothing
+	for _, invalid := range []*Export{
+		{Version: 2},
+		{Version: ExportVersion, Intents: []*Intent{nil}},
+		{Version: ExportVersion, Intents: []*Intent{{Name: "x", Examples: []string{"x"}, TargetExpert: "calendar"}}},
+		{Version: ExportVersion, Intents: []*Intent{
+			{Name: "dup", Examples: []string{"a"}, TargetExpert: "memo"},
+			{Name: "dup", Examples: []string{"b"}, TargetExpert: "memo"},
+		}},
+	} {
+		_, err := target.Import(ctx, invalid, true)
+		assert.ErrorIs(t, err, ErrInvalid)
+	}
+	stored, err := store.ListIntents(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"trip"}, names(stored))
+	assert.Equal(t, 2, notified)
+}
@@ -0,0 +1,141 @@
+package intenttaxonomy
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DBStore persists the taxonomy in the intent_taxonomy table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new taxonomy store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const intentColumns = "name, examples, target_expert, orchestrate, created_ts, updated_ts"
+
+// upsertIntent is shared by SaveIntent and SaveIntents.
+const upsertIntent = `
+	INSERT INTO intent_taxonomy (name, examples, target_expert, orchestrate, created_ts, updated_ts)
+	VALUES ($1, $2, $3, $4, $5, $5)
+	ON CONFLICT (name) DO UPDATE SET
+		examples = EXCLUDED.examples,
+		target_expert = EXCLUDED.target_expert,
+		orchestrate = EXCLUDED.orchestrate,
+		updated_ts = EXCLUDED.updated_ts
+	RETURNING ` + intentColumns
+
+// ListIntents implements Store.
+func (s *DBStore) ListIntents(ctx context.Context) ([]*Intent, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+intentColumns+" FROM intent_taxonomy ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list intents: %w", err)
+	}
+	defer rows.Close()
+
+	var intents []*Intent
+	for rows.Next() {
+		intent, err := scanIntent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan intent: %w", err)
+		}
+		intents = append(intents, intent)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list intents: %w", err)
+	}
+	return intents, nil
+}
+
+// GetIntent implements Store.
+func (s *DBStore) GetIntent(ctx context.Context, name string) (*Intent, error) {
+	intent, err := scanIntent(s.db.QueryRowContext(ctx,
+		"SELECT "+intentColumns+" FROM intent_taxonomy WHERE name = $1", name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get intent: %w", err)
+	}
+	return intent, nil
+}
+
+// SaveIntent implements Store.
+func (s *DBStore) SaveIntent(ctx context.Context, intent *Intent) (*Intent, error) {
+	examples, err := json.Marshal(intent.Examples)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode examples: %w", err)
+	}
+	saved, err := scanIntent(s.db.QueryRowContext(ctx, upsertIntent,
+		intent.Name, string(examples), intent.TargetExpert, intent.Orchestrate, time.Now().Unix()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save intent: %w", err)
+	}
+	return saved, nil
+}
+
+// DeleteIntent implements Store.
+func (s *DBStore) DeleteIntent(ctx context.Context, name string) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM intent_taxonomy WHERE name = $1", name)
+	if err != nil {
+		return fmt.Errorf("failed to delete intent: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SaveIntents implements Store.
+func (s *DBStore) SaveIntents(ctx context.Context, intents []*Intent, replace bool) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if replace {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM intent_taxonomy"); err != nil {
+			return fmt.Errorf("failed to clear intents: %w", err)
+		}
+	}
+	now := time.Now().Unix()
+	for _, intent := range intents {
+		examples, err := json.Marshal(intent.Examples)
+		if err != nil {
+			return fmt.Errorf("failed to encode examples: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, upsertIntent,
+			intent.Name, string(examples), intent.TargetExpert, intent.Orchestrate, now); err != nil {
+			return fmt.Errorf("failed to save intent %s: %w", intent.Name, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit intents: %w", err)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanIntent(row rowScanner) (*Intent, error) {
+	intent := &Intent{}
+	var examples []byte
+	if err := row.Scan(&intent.Name, &examples, &intent.TargetExpert, &intent.Orchestrate,
+		&intent.CreatedTs, &intent.UpdatedTs); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(examples, &intent.Examples); err != nil {
+		return nil, fmt.Errorf("failed to decode examples: %w", err)
+	}
+	return intent, nil
+}
@@ -0,0 +1,375 @@
+// Package kbhealth reports on the health of a user's knowledge base: orphan
+// notes, stale notes, broken memo links, clusters of near-duplicate notes and
+// embedding coverage.
+//
+// Analyze builds a Report from a snapshot of the user's memos; a Runner
+// delivers it weekly, as a memo or through the user's webhooks, to the users
+// who enabled it. Thresholds are per user (Settings).
+package kbhealth
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hrygo/divinesense/ai/duplicate"
+	"github.com/hrygo/divinesense/plugin/i18n"
+)
+
+// Delivery is how a scheduled report reaches the user.
+type Delivery string
+
+const (
+	// DeliveryMemo saves the report as a private memo.
+	DeliveryMemo Delivery = "MEMO"
+	// DeliveryWebhook posts the report to the user's webhooks.
+	DeliveryWebhook Delivery = "WEBHOOK"
+)
+
+// ReportTag tags report memos, which are left out of the analysis.
+const ReportTag = "kb-health"
+
+const (
+	// ReportInterval is the time between two scheduled reports of a user.
+	ReportInterval = 7 * 24 * time.Hour
+	// maxDuplicateScan bounds the memos compared pairwise for duplicates,
+	// most recently updated first.
+	maxDuplicateScan = 2000
+)
+
+// Settings are the per-user report settings.
+type Settings struct {
+	UserID int32 `json:"-"`
+	// Enabled turns the weekly report on. Defaults to false.
+	Enabled  bool     `json:"enabled"`
+	Delivery Delivery `json:"delivery"`
+	// StaleDays is the age, in days since the last update, of stale notes.
+	StaleDays int `json:"stale_days"`
+	// DuplicateThreshold is the cosine similarity of the embeddings from which
+	// two notes are near-duplicates.
+	DuplicateThreshold float64 `json:"duplicate_threshold"`
+	// MaxItems bounds the notes listed per section of the report.
+	MaxItems int `json:"max_items"`
+	// LastReportTs is the time of the last scheduled report; read-only.
+	LastReportTs int64 `json:"last_report_ts"`
+}
+
+// DefaultSettings returns the settings of users who saved none.
+func DefaultSettings(userID int32) *Settings {
+	return &Settings{
+		UserID:             userID,
+		Delivery:           DeliveryMemo,
+		StaleDays:          180,
+		DuplicateThreshold: 0.9,
+		MaxItems:           10,
+	}
+}
+
+// Validate checks settings about to be saved.
+func (s *Settings) Validate() error {
+	if s.Delivery != DeliveryMemo && s.Delivery != DeliveryWebhook {
+		return fmt.Errorf("delivery must be MEMO or WEBHOOK")
+	}
+	if s.StaleDays < 1 || s.StaleDays > 3650 {
+		return fmt.Errorf("stale_days must be between 1 and 3650")
+	}
+	if s.DuplicateThreshold < 0.5 || s.DuplicateThreshold > 1 {
+		return fmt.Errorf("duplicate_threshold must be between 0.5 and 1")
+	}
+	if s.MaxItems < 1 || s.MaxItems > 50 {
+		return fmt.Errorf("max_items must be between 1 and 50")
+	}
+	return nil
+}
+
+// Memo is a note of the analyzed knowledge base.
+type Memo struct {
+	ID        int32
+	UID       string
+	Content   string
+	Tags      []string
+	UpdatedTs int64
+}
+
+// Input is a snapshot of a user's knowledge base.
+type Input struct {
+	// Memos are the user's active notes, comments excluded.
+	Memos []*Memo
+	// Related holds the memos with a reference relation to or from another memo.
+	Related map[int32]bool
+	// ExistingUIDs holds the linked memos that exist outside Memos, e.g.
+	// archived notes or notes of other users.
+	ExistingUIDs map[string]bool
+	// Embeddings maps memos to their embedding; nil when embeddings are
+	// unavailable, in which case duplicates and coverage are not reported.
+	Embeddings map[int32][]float32
+	// Locale is the locale of the user, which the report is written in.
+	Locale i18n.Locale
+}
+
+// MemoRef identifies a note in a report.
+type MemoRef struct {
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	UpdatedTs int64  `json:"updated_ts"`
+}
+
+// BrokenLink is a link from a note to a memo that does not exist.
+type BrokenLink struct {
+	Memo      MemoRef `json:"memo"`
+	TargetUID string  `json:"target_uid"`
+}
+
+// Coverage is the share of notes with an embedding, i.e. findable by
+// semantic search.
+type Coverage struct {
+	Embedded int `json:"embedded"`
+	Total    int `json:"total"`
+	Percent  int `json:"percent"`
+}
+
+// Report is the health report of a user's knowledge base. Each section lists
+// at most Settings.MaxItems notes; the counts are the full totals.
+type Report struct {
+	GeneratedTs int64 `json:"generated_ts"`
+	TotalMemos  int   `json:"total_memos"`
+	StaleDays   int   `json:"stale_days"`
+
+	Orphans     []MemoRef `json:"orphans"`
+	OrphanCount int       `json:"orphan_count"`
+
+	Stale      []MemoRef `json:"stale"`
+	StaleCount int       `json:"stale_count"`
+
+	BrokenLinks     []BrokenLink `json:"broken_links"`
+	BrokenLinkCount int          `json:"broken_link_count"`
+
+	DuplicateClusters     [][]MemoRef `json:"duplicate_clusters"`
+	DuplicateClusterCount int         `json:"duplicate_cluster_count"`
+
+	// Embedding is nil when embeddings are unavailable.
+	Embedding *Coverage `json:"embedding,omitempty"`
+
+	Locale i18n.Locale `json:"locale"`
+}
+
+// memoLink matches links to memos: "[title](/memos/uid)", bare "memos/uid"
+// and absolute URLs of memos.
+var memoLink = regexp.MustCompile(`(?:^|[(<\s])(?:https?://[^\s/()<>]+)?/?memos/([A-Za-z0-9_-]+)`)
+
+// LinkedUIDs returns the UIDs of the memos linked from content, in order of
+// appearance and without duplicates.
+func LinkedUIDs(content string) []string {
+	var uids []string
+	seen := make(map[string]bool)
+	for _, match := range memoLink.FindAllStringSubmatch(content, -1) {
+		if uid := match[1]; !seen[uid] {
+			seen[uid] = true
+			uids = append(uids, uid)
+		}
+	}
+	return uids
+}
+
+// Analyze builds the health report of a knowledge base at now.
+func Analyze(input *Input, settings *Settings, now time.Time) *Report {
+	var memos []*Memo
+	uids := make(map[string]bool)
+	for _, memo := range input.Memos {
+		if isReport(memo) {
+			continue
+		}
+		memos = append(memos, memo)
+		uids[memo.UID] = true
+	}
+	// Oldest first: the notes most in need of attention lead each section.
+	sort.SliceStable(memos, func(i, j int) bool { return memos[i].UpdatedTs < memos[j].UpdatedTs })
+
+	report := &Report{
+		GeneratedTs:       now.Unix(),
+		TotalMemos:        len(memos),
+		StaleDays:         settings.StaleDays,
+		Orphans:           []MemoRef{},
+		Stale:             []MemoRef{},
+		BrokenLinks:       []BrokenLink{},
+		DuplicateClusters: [][]MemoRef{},
+		Locale:            input.Locale,
+	}
+	staleBefore := now.AddDate(0, 0, -settings.StaleDays).Unix()
+	for _, memo := range memos {
+		links := LinkedUIDs(memo.Content)
+		if len(memo.Tags) == 0 && len(links) == 0 && !input.Related[memo.ID] {
+			report.OrphanCount++
+			if len(report.Orphans) < settings.MaxItems {
+				report.Orphans = append(report.Orphans, ref(memo))
+			}
+		}
+		if memo.UpdatedTs < staleBefore {
+			report.StaleCount++
+			if len(report.Stale) < settings.MaxItems {
+				report.Stale = append(report.Stale, ref(memo))
+			}
+		}
+		for _, uid := range links {
+			if uids[uid] || input.ExistingUIDs[uid] {
+				continue
+			}
+			report.BrokenLinkCount++
+			if len(report.BrokenLinks) < settings.MaxItems {
+				report.BrokenLinks = append(report.BrokenLinks, BrokenLink{Memo: ref(memo), TargetUID: uid})
+			}
+		}
+	}
+
+	if input.Embeddings != nil {
+		coverage := &Coverage{Total: len(memos)}
+		for _, memo := range memos {
+			if len(input.Embeddings[memo.ID]) > 0 {
+				coverage.Embedded++
+			}
+		}
+		if coverage.Total > 0 {
+			coverage.Percent = coverage.Embedded * 100 / coverage.Total
+		}
+		report.Embedding = coverage
+
+		clusters := duplicateClusters(memos, input.Embeddings, settings.DuplicateThreshold)
+		report.DuplicateClusterCount = len(clusters)
+		for _, cluster := range clusters {
+			if len(report.DuplicateClusters) == settings.MaxItems {
+				break
+			}
+			refs := make([]MemoRef, 0, len(cluster))
+			for _, memo := range cluster {
+				refs = append(refs, ref(memo))
+			}
+			report.DuplicateClusters = append(report.DuplicateClusters, refs)
+		}
+	}
+	return report
+}
+
+// duplicateClusters groups the memos whose embeddings are at least threshold
+// similar, transitively. Clusters are returned largest first.
+func duplicateClusters(memos []*Memo, embeddings map[int32][]float32, threshold float64) [][]*Memo {
+	var candidates []*Memo
+	for i := len(memos) - 1; i >= 0 && len(candidates) < maxDuplicateScan; i-- {
+		if len(embeddings[memos[i].ID]) > 0 {
+			candidates = append(candidates, memos[i])
+		}
+	}
+
+	parent := make([]int, len(candidates))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range candidates {
+		for j := i + 1; j < len(candidates); j++ {
+			if duplicate.CosineSimilarity(embeddings[candidates[i].ID], embeddings[candidates[j].ID]) >= threshold {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	groups := make(map[int][]*Memo)
+	var roots []int
+	for i, memo := range candidates {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], memo)
+	}
+	var clusters [][]*Memo
+	for _, root := range roots {
+		if len(groups[root]) > 1 {
+			clusters = append(clusters, groups[root])
+		}
+	}
+	sort.SliceStable(clusters, func(i, j int) bool { return len(clusters[i]) > len(clusters[j]) })
+	return clusters
+}
+
+func isReport(memo *Memo) bool {
+	for _, tag := range memo.Tags {
+		if tag == ReportTag {
+			return true
+		}
+	}
+	return false
+}
+
+func ref(memo *Memo) MemoRef {
+	title := duplicate.ExtractTitle(memo.Content)
+	if title == "" {
+		title = memo.UID
+	}
+	return MemoRef{UID: memo.UID, Title: title, UpdatedTs: memo.UpdatedTs}
+}
+
+// Markdown renders the report as memo content in its locale, tagged with
+// ReportTag.
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	date := time.Unix(r.GeneratedTs, 0).Format("2006-01-02")
+	fmt.Fprintf(&b, "%s\n\n#%s\n\n", i18n.T(r.Locale, "kbhealth.report.title", date), ReportTag)
+	b.WriteString(i18n.T(r.Locale, "kbhealth.report.summary",
+		r.TotalMemos, r.OrphanCount, r.StaleDays, r.StaleCount, r.BrokenLinkCount))
+	if r.Embedding != nil {
+		b.WriteString(i18n.T(r.Locale, "kbhealth.report.embedding",
+			r.DuplicateClusterCount, r.Embedding.Percent, r.Embedding.Embedded, r.Embedding.Total))
+	}
+	b.WriteString(i18n.T(r.Locale, "kbhealth.report.end") + "\n")
+
+	writeSection := func(title string, total, listed int, items func()) {
+		if total == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%s\n\n", i18n.T(r.Locale, "kbhealth.report.section", title, total))
+		items()
+		if total > listed {
+			fmt.Fprintf(&b, "- %s\n", i18n.T(r.Locale, "common.more", total-listed))
+		}
+	}
+	writeSection(i18n.T(r.Locale, "kbhealth.report.orphans"), r.OrphanCount, len(r.Orphans), func() {
+		for _, m := range r.Orphans {
+			fmt.Fprintf(&b, "- %s\n", memoLinkMarkdown(m))
+		}
+	})
+	writeSection(i18n.T(r.Locale, "kbhealth.report.stale", r.StaleDays), r.StaleCount, len(r.Stale), func() {
+		for _, m := range r.Stale {
+			b.WriteString(i18n.T(r.Locale, "kbhealth.report.stale_item", memoLinkMarkdown(m), time.Unix(m.UpdatedTs, 0).Format("2006-01-02")) + "\n")
+		}
+	})
+	writeSection(i18n.T(r.Locale, "kbhealth.report.broken_links"), r.BrokenLinkCount, len(r.BrokenLinks), func() {
+		for _, l := range r.BrokenLinks {
+			fmt.Fprintf(&b, "- %s → `memos/%s`\n", memoLinkMarkdown(l.Memo), l.TargetUID)
+		}
+	})
+	if r.Embedding != nil {
+		writeSection(i18n.T(r.Locale, "kbhealth.report.duplicates"), r.DuplicateClusterCount, len(r.DuplicateClusters), func() {
+			for _, cluster := range r.DuplicateClusters {
+				links := make([]string, 0, len(cluster))
+				for _, m := range cluster {
+					links = append(links, memoLinkMarkdown(m))
+				}
+				fmt.Fprintf(&b, "- %s\n", strings.Join(links, " ≈ "))
+			}
+		})
+	}
+	return b.String()
+}
+
+func memoLinkMarkdown(m MemoRef) string {
+	title := strings.NewReplacer("[", "(", "]", ")").Replace(m.Title)
+	return fmt.Sprintf("[%s](/memos/%s)", title, m.UID)
+}
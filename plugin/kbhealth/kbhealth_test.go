@@ -0,0 +1,153 @@
+package kbhealth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/plugin/i18n"
+)
+
+func TestLinkedUIDs(t *testing.T) {
+	content := "见 [设计](/memos/abc123) 和 https://notes.example.com/memos/Xy_9-z，" +
+		"以及 memos/abc123 与 [附件](/files/memos/nope)\nmemos/first"
+	assert.Equal(t, []string{"abc123", "Xy_9-z", "first"}, LinkedUIDs(content))
+	assert.Empty(t, LinkedUIDs("no links here, not even somememos/x"))
+}
+
+func TestAnalyze(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	recent := now.AddDate(0, 0, -1).Unix()
+	old := now.AddDate(-1, 0, 0).Unix()
+	input := &Input{
+		Memos: []*Memo{
+			{ID: 1, UID: "orphan", Content: "# 孤立笔记\n内容", UpdatedTs: recent},
+			{ID: 2, UID: "tagged", Content: "#go 笔记", Tags: []string{"go"}, UpdatedTs: old},
+			{ID: 3, UID: "linker", Content: "见 [A](/memos/tagged) [B](/memos/gone) [C](/memos/archived)", UpdatedTs: recent},
+			{ID: 4, UID: "related", Content: "有关联的笔记", UpdatedTs: recent},
+			{ID: 5, UID: "dup", Content: "Redis 配置", Tags: []string{"redis"}, UpdatedTs: recent},
+			{ID: 6, UID: "report", Content: "# 周报 [x](/memos/gone)", Tags: []string{ReportTag}, UpdatedTs: recent},
+		},
+		Related:      map[int32]bool{4: true},
+		ExistingUIDs: map[string]bool{"archived": true},
+		Embeddings: map[int32][]float32{
+			2: {1, 0},
+			5: {0.99, 0.05},
+			3: {0, 1},
+		},
+	}
+	settings := DefaultSettings(1)
+	report := Analyze(input, settings, now)
+
+	assert.Equal(t, 5, report.TotalMemos)
+	assert.Equal(t, 1, report.OrphanCount)
+	assert.Equal(t, "orphan", report.Orphans[0].UID)
+	assert.Equal(t, "孤立笔记", report.Orphans[0].Title)
+
+	assert.Equal(t, 1, report.StaleCount)
+	assert.Equal(t, "tagged", report.Stale[0].UID)
+
+	require.Equal(t, 1, report.BrokenLinkCount)
+	assert.Equal(t, BrokenLink{Memo: report.BrokenLinks[0].Memo, TargetUID: "gone"}, report.BrokenLinks[0])
+	assert.Equal(t, "linker", report.BrokenLinks[0].Memo.UID)
+
+	require.Equal(t, 1, report.DuplicateClusterCount)
+	cluster := []string{report.DuplicateClusters[0][0].UID, report.DuplicateClusters[0][1].UID}
+	assert.ElementsMatch(t, []string{"tagged", "dup"}, cluster)
+
+	require.NotNil(t, report.Embedding)
+	assert.Equal(t, Coverage{Embedded: 3, Total: 5, Percent: 60}, *report.Embedding)
+
+	// Sections are bounded but counts are not.
+	settings.MaxItems = 1
+	settings.StaleDays = 1
+	report = Analyze(input, settings, now.AddDate(0, 0, 10))
+	assert.Equal(t, 5, report.StaleCount)
+	assert.Len(t, report.Stale, 1)
+
+	// Without embeddings, neither duplicates nor coverage are reported.
+	input.Embeddings = nil
+	report = Analyze(input, DefaultSettings(1), now)
+	assert.Nil(t, report.Embedding)
+	assert.Zero(t, report.DuplicateClusterCount)
+	assert.NotContains(t, report.Markdown(), "疑似重复")
+}
+
+func TestReportMarkdown(t *testing.T) {
+	report := &Report{
+		GeneratedTs:     time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local).Unix(),
+		TotalMemos:      3,
+		StaleDays:       180,
+		Orphans:         []MemoRef{{UID: "a1", Title: "[草稿] 想法"}},
+		OrphanCount:     2,
+		BrokenLinks:     []BrokenLink{{Memo: MemoRef{UID: "b2", Title: "索引"}, TargetUID: "gone"}},
+		BrokenLinkCount: 1,
+		Embedding:       &Coverage{Embedded: 2, Total: 3, Percent: 66},
+	}
+	markdown := report.Markdown()
+	assert.Contains(t, markdown, "# 知识库健康周报 2026-10-16")
+	assert.Contains(t, markdown, "#"+ReportTag)
+	assert.Contains(t, markdown, "- [(草稿) 想法](/memos/a1)\n- ……另有 1 项")
+	assert.Contains(t, markdown, "- [索引](/memos/b2) → `memos/gone`")
+	assert.Contains(t, markdown, "向量索引覆盖率 66%（2/3）")
+	assert.NotContains(t, markdown, "未更新（")
+
+	report.Locale = i18n.LocaleEnglish
+	markdown = report.Markdown()
+	assert.Contains(t, markdown, "# Knowledge base health report 2026-10-16")
+	assert.Contains(t, markdown, "3 memos: 2 orphaned, 0 not updated for 180 days, 1 broken links")
+	assert.Contains(t, markdown, "## Orphaned memos (no tags, no links) (2)\n\n- [(草稿) 想法](/memos/a1)\n- …and 1 more")
+}
+
+func TestSettingsValidate(t *testing.T) {
+	settings := DefaultSettings(1)
+	require.NoError(t, settings.Validate())
+
+	for _, mutate := range []func(*Settings){
+		func(s *Settings) { s.Delivery = "EMAIL" },
+		func(s *Settings) { s.StaleDays = 0 },
+		func(s *Settings) { s.DuplicateThreshold = 0.2 },
+		func(s *Settings) { s.MaxItems = 100 },
+	} {
+		s := DefaultSettings(1)
+		mutate(s)
+		assert.Error(t, s.Validate())
+	}
+}
+
+type fixedScheduler struct {
+	due []*Settings
+	err error
+}
+
+func (s *fixedScheduler) ClaimDue(context.Context, time.Time, int) ([]*Settings, error) {
+	return s.due, s.err
+}
+
+func TestRunnerRunOnce(t *testing.T) {
+	ctx := context.Background()
+	scheduler := &fixedScheduler{due: []*Settings{DefaultSettings(1), DefaultSettings(2)}}
+	var delivered []int32
+	runner := NewRunner(scheduler,
+		func(_ context.Context, settings *Settings) (*Report, error) {
+			if settings.UserID == 2 {
+				return nil, errors.New("boom")
+			}
+			return &Report{}, nil
+		},
+		func(_ context.Context, settings *Settings, _ *Report) error {
+			delivered = append(delivered, settings.UserID)
+			return nil
+		},
+	)
+
+	assert.Equal(t, 1, runner.RunOnce(ctx))
+	assert.Equal(t, []int32{1}, delivered)
+
+	scheduler.err = errors.New("db down")
+	assert.Zero(t, runner.RunOnce(ctx))
+}
@@ -0,0 +1,91 @@
+package kbhealth
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	// runInterval is how often the runner looks for due reports.
+	runInterval = time.Hour
+	// runBatchSize bounds the reports claimed per run.
+	runBatchSize = 20
+	// buildTimeout bounds the analysis and delivery of one report.
+	buildTimeout = 5 * time.Minute
+)
+
+// Scheduler is the runner's view of the settings store; implemented by DBStore.
+type Scheduler interface {
+	// ClaimDue marks up to limit enabled users whose last report is older
+	// than ReportInterval as reported at now, and returns their settings.
+	// Users in quiet hours are left for later.
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*Settings, error)
+}
+
+// Builder analyzes the knowledge base of a user.
+type Builder func(ctx context.Context, settings *Settings) (*Report, error)
+
+// Deliverer sends a report to its user.
+type Deliverer func(ctx context.Context, settings *Settings, report *Report) error
+
+// Runner delivers the weekly reports. Several instances may run runners on
+// the same database: a report is claimed by exactly one of them.
+type Runner struct {
+	scheduler Scheduler
+	build     Builder
+	deliver   Deliverer
+	interval  time.Duration
+}
+
+// NewRunner creates a runner.
+func NewRunner(scheduler Scheduler, build Builder, deliver Deliverer) *Runner {
+	return &Runner{scheduler: scheduler, build: build, deliver: deliver, interval: runInterval}
+}
+
+// Run delivers due reports until ctx is canceled.
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		r.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce delivers the reports due now and returns how many it delivered.
+// A report that fails is not retried before the next week.
+func (r *Runner) RunOnce(ctx context.Context) int {
+	due, err := r.scheduler.ClaimDue(ctx, time.Now(), runBatchSize)
+	if err != nil {
+		slog.Warn("kb health: failed to claim due reports", "error", err)
+		return 0
+	}
+	delivered := 0
+	for _, settings := range due {
+		if err := r.run(ctx, settings); err != nil {
+			slog.Warn("kb health: failed to deliver report", "user_id", settings.UserID, "error", err)
+			continue
+		}
+		delivered++
+	}
+	return delivered
+}
+
+func (r *Runner) run(ctx context.Context, settings *Settings) error {
+	ctx, cancel := context.WithTimeout(ctx, buildTimeout)
+	defer cancel()
+	report, err := r.build(ctx, settings)
+	if err != nil {
+		return err
+	}
+	if err := r.deliver(ctx, settings, report); err != nil {
+		return err
+	}
+	slog.Info("kb health report delivered", "user_id", settings.UserID, "delivery", settings.Delivery)
+	return nil
+}
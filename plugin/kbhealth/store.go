@@ -0,0 +1,103 @@
+package kbhealth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hrygo/divinesense/plugin/quiethours"
+)
+
+// DBStore persists settings in the kb_health_setting table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new settings store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const settingColumns = "user_id, enabled, delivery, stale_days, duplicate_threshold, max_items, last_report_ts"
+
+// GetSettings returns the settings of a user, or the defaults if none were saved.
+func (s *DBStore) GetSettings(ctx context.Context, userID int32) (*Settings, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+settingColumns+" FROM kb_health_setting WHERE user_id = $1", userID)
+	settings, err := scanSettings(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return DefaultSettings(userID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kb health settings: %w", err)
+	}
+	return settings, nil
+}
+
+// SaveSettings creates or updates the settings of a user, keeping the time of
+// the last report.
+func (s *DBStore) SaveSettings(ctx context.Context, settings *Settings) (*Settings, error) {
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO kb_health_setting (user_id, enabled, delivery, stale_days, duplicate_threshold, max_items, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (user_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			delivery = EXCLUDED.delivery,
+			stale_days = EXCLUDED.stale_days,
+			duplicate_threshold = EXCLUDED.duplicate_threshold,
+			max_items = EXCLUDED.max_items,
+			updated_ts = EXCLUDED.updated_ts
+		RETURNING `+settingColumns,
+		settings.UserID, settings.Enabled, settings.Delivery, settings.StaleDays, settings.DuplicateThreshold,
+		settings.MaxItems, time.Now().Unix())
+	saved, err := scanSettings(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save kb health settings: %w", err)
+	}
+	return saved, nil
+}
+
+// ClaimDue implements Scheduler. Users in quiet hours wait until they end;
+// rows locked by another runner are skipped.
+func (s *DBStore) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*Settings, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		UPDATE kb_health_setting SET last_report_ts = $1
+		WHERE user_id IN (
+			SELECT user_id FROM kb_health_setting
+			WHERE enabled AND last_report_ts <= $2
+				AND `+quiethours.NotQuietSQL("kb_health_setting.user_id", "$1")+`
+			ORDER BY last_report_ts
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+settingColumns,
+		now.Unix(), now.Add(-ReportInterval).Unix(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due reports: %w", err)
+	}
+	defer rows.Close()
+
+	var due []*Settings
+	for rows.Next() {
+		settings, err := scanSettings(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan kb health settings: %w", err)
+		}
+		due = append(due, settings)
+	}
+	return due, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSettings(row rowScanner) (*Settings, error) {
+	settings := &Settings{}
+	if err := row.Scan(&settings.UserID, &settings.Enabled, &settings.Delivery, &settings.StaleDays,
+		&settings.DuplicateThreshold, &settings.MaxItems, &settings.LastReportTs); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
@@ -0,0 +1,378 @@
+// Package llmregistry selects the LLM of each agent type among the
+// configured providers.
+//
+// The unified LLM is the "default" provider; more providers (OpenAI,
+// DeepSeek, Anthropic, Ollama, a local vLLM…) are configured by name. An
+// agent type runs on the default provider unless a selection picks another
+// provider or model for it, e.g. the memo parrot on a cheap model and the
+// orchestrator on a strong one. Selections come from the environment; admins
+// may change them at runtime, and theirs are persisted and take precedence.
+package llmregistry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hrygo/divinesense/ai"
+)
+
+var (
+	// ErrNotFound is returned for agent types without an admin selection.
+	ErrNotFound = errors.New("model selection not found")
+	// ErrInvalid is returned for selections that fail validation.
+	ErrInvalid = errors.New("invalid model selection")
+)
+
+// DefaultProvider is the name of the provider of the unified LLM.
+const DefaultProvider = "default"
+
+// AgentOrchestrator is the agent type of the orchestrator, besides the
+// parrots.
+const AgentOrchestrator = "orchestrator"
+
+// Sources of a selection.
+const (
+	SourceDefault = "default" // No selection: the default provider
+	SourceEnv     = "env"     // DIVINESENSE_AI_AGENT_MODELS
+	SourceAdmin   = "admin"   // Set at runtime by an admin
+)
+
+var (
+	agentPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,63}$`)
+	modelPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:/@-]{0,127}$`)
+)
+
+// Provider is a configured provider, without its credentials.
+type Provider struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	BaseURL  string `json:"base_url,omitempty"`
+	Model    string `json:"model"` // Default model
+}
+
+// Selection is the provider and model an agent type runs on.
+type Selection struct {
+	Agent     string `json:"agent"`
+	Provider  string `json:"provider"`
+	Model     string `json:"model,omitempty"` // "" for the default model of the provider
+	Source    string `json:"source"`
+	UpdatedTs int64  `json:"updated_ts,omitempty"`
+}
+
+// ParseSelection parses the "provider[:model]" selection of an agent type.
+func ParseSelection(agent, value string) *Selection {
+	provider, model, _ := strings.Cut(value, ":")
+	return &Selection{Agent: agent, Provider: strings.TrimSpace(provider), Model: strings.TrimSpace(model), Source: SourceEnv}
+}
+
+// Store persists the selections of admins.
+type Store interface {
+	ListSelections(ctx context.Context) ([]*Selection, error)
+	SaveSelection(ctx context.Context, selection *Selection) (*Selection, error)
+	// DeleteSelection returns ErrNotFound for agent types without a selection.
+	DeleteSelection(ctx context.Context, agent string) error
+}
+
+// Config configures a Registry.
+type Config struct {
+	Default     *ai.LLMConfig           // Settings of the unified LLM
+	DefaultLLM  ai.LLMService           // The unified LLM
+	Providers   map[string]ai.LLMConfig // Named providers besides the default one
+	AgentModels map[string]string       // Agent type → "provider[:model]"
+}
+
+// Registry holds the providers and the selections of the agent types.
+type Registry struct {
+	store    Store // nil keeps the admin selections in memory
+	newLLM   func(cfg *ai.LLMConfig) (ai.LLMService, error)
+	configs  map[string]ai.LLMConfig
+	fallback ai.LLMService
+	env      map[string]*Selection
+
+	mu     sync.RWMutex
+	agents []string                 // Known agent types, sorted
+	admin  map[string]*Selection    // nil until loaded
+	llms   map[string]ai.LLMService // By provider and model
+}
+
+// New creates the registry. Invalid environment selections are logged and
+// ignored.
+func New(store Store, cfg *Config) *Registry {
+	r := &Registry{
+		store:    store,
+		newLLM:   ai.NewLLMService,
+		configs:  map[string]ai.LLMConfig{},
+		fallback: cfg.DefaultLLM,
+		env:      map[string]*Selection{},
+		llms:     map[string]ai.LLMService{},
+	}
+	maps.Copy(r.configs, cfg.Providers)
+	if cfg.Default != nil {
+		r.configs[DefaultProvider] = *cfg.Default
+		r.llms[llmKey(DefaultProvider, cfg.Default.Model)] = cfg.DefaultLLM
+	}
+	for agent, value := range cfg.AgentModels {
+		selection := ParseSelection(agent, value)
+		if err := r.validate(selection); err != nil {
+			slog.Warn("Ignoring agent model selection", "agent", agent, "selection", value, "error", err)
+			continue
+		}
+		r.env[agent] = selection
+	}
+	return r
+}
+
+// llmKey is the cache key of the LLM of a provider and model.
+func llmKey(provider, model string) string {
+	return provider + "\x00" + model
+}
+
+// validate checks a selection against the providers.
+func (r *Registry) validate(selection *Selection) error {
+	if !agentPattern.MatchString(selection.Agent) {
+		return fmt.Errorf("%w: invalid agent type %q", ErrInvalid, selection.Agent)
+	}
+	if _, ok := r.configs[selection.Provider]; !ok {
+		return fmt.Errorf("%w: unknown provider %q", ErrInvalid, selection.Provider)
+	}
+	if selection.Model != "" && !modelPattern.MatchString(selection.Model) {
+		return fmt.Errorf("%w: invalid model %q", ErrInvalid, selection.Model)
+	}
+	return nil
+}
+
+// SetAgents sets the known agent types, which admins may select models for.
+func (r *Registry) SetAgents(agents []string) {
+	agents = slices.Clone(agents)
+	slices.Sort(agents)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.agents = slices.Compact(agents)
+}
+
+// Providers returns the providers, the default one first.
+func (r *Registry) Providers() []*Provider {
+	providers := make([]*Provider, 0, len(r.configs))
+	for name, cfg := range r.configs {
+		providers = append(providers, &Provider{Name: name, Provider: cfg.Provider, BaseURL: cfg.BaseURL, Model: cfg.Model})
+	}
+	slices.SortFunc(providers, func(a, b *Provider) int {
+		if (a.Name == DefaultProvider) != (b.Name == DefaultProvider) {
+			if a.Name == DefaultProvider {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return providers
+}
+
+// List returns the selections of the known agent types and of the ones with
+// a selection, by agent type.
+func (r *Registry) List(ctx context.Context) ([]*Selection, error) {
+	admin, err := r.loadAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	agents := slices.Clone(r.agents)
+	r.mu.RUnlock()
+	agents = slices.AppendSeq(agents, maps.Keys(r.env))
+	agents = slices.AppendSeq(agents, maps.Keys(admin))
+	slices.Sort(agents)
+
+	selections := make([]*Selection, 0, len(agents))
+	for _, agent := range slices.Compact(agents) {
+		selections = append(selections, r.selection(admin, agent))
+	}
+	return selections, nil
+}
+
+// selection returns the selection of an agent type: the admin one, the
+// environment one, or the default provider.
+func (r *Registry) selection(admin map[string]*Selection, agent string) *Selection {
+	for _, selections := range []map[string]*Selection{admin, r.env} {
+		if selection, ok := selections[agent]; ok {
+			copied := *selection
+			return &copied
+		}
+	}
+	return &Selection{Agent: agent, Provider: DefaultProvider, Source: SourceDefault}
+}
+
+// Select validates and saves the selection of an admin for an agent type,
+// which applies to the next LLM calls of the agent.
+func (r *Registry) Select(ctx context.Context, selection *Selection) (*Selection, error) {
+	selection.Provider, selection.Model = strings.TrimSpace(selection.Provider), strings.TrimSpace(selection.Model)
+	if err := r.validate(selection); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	known := len(r.agents) == 0 || slices.Contains(r.agents, selection.Agent)
+	r.mu.RUnlock()
+	if !known {
+		return nil, fmt.Errorf("%w: unknown agent type %q", ErrInvalid, selection.Agent)
+	}
+	if _, err := r.llm(selection.Provider, selection.Model); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if _, err := r.loadAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	selection.Source = SourceAdmin
+	if r.store != nil {
+		saved, err := r.store.SaveSelection(ctx, selection)
+		if err != nil {
+			return nil, err
+		}
+		selection = saved
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *selection
+	r.admin[selection.Agent] = &copied
+	return selection, nil
+}
+
+// Reset removes the admin selection of an agent type, which returns to its
+// environment selection or to the default provider.
+func (r *Registry) Reset(ctx context.Context, agent string) error {
+	admin, err := r.loadAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := admin[agent]; !ok {
+		return ErrNotFound
+	}
+	if r.store != nil {
+		if err := r.store.DeleteSelection(ctx, agent); err != nil {
+			return err
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.admin, agent)
+	return nil
+}
+
+// loadAdmin loads the admin selections once, retrying on the next call if
+// the store fails.
+func (r *Registry) loadAdmin(ctx context.Context) (map[string]*Selection, error) {
+	r.mu.RLock()
+	admin := r.admin
+	r.mu.RUnlock()
+	if admin != nil {
+		return admin, nil
+	}
+	loaded := map[string]*Selection{}
+	if r.store != nil {
+		selections, err := r.store.ListSelections(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, selection := range selections {
+			loaded[selection.Agent] = selection
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.admin == nil {
+		r.admin = loaded
+	}
+	return r.admin, nil
+}
+
+// llm returns the LLM of a provider and model ("" for the default model of
+// the provider), created once.
+func (r *Registry) llm(provider, model string) (ai.LLMService, error) {
+	cfg, ok := r.configs[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q", provider)
+	}
+	if model == "" {
+		model = cfg.Model
+	}
+	if model == "" {
+		return nil, fmt.Errorf("provider %q has no default model", provider)
+	}
+	key := llmKey(provider, model)
+	r.mu.RLock()
+	llm, ok := r.llms[key]
+	r.mu.RUnlock()
+	if ok {
+		return llm, nil
+	}
+
+	cfg.Model = model
+	llm, err := r.newLLM(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cached, ok := r.llms[key]; ok {
+		return cached, nil
+	}
+	r.llms[key] = llm
+	return llm, nil
+}
+
+// resolve returns the LLM selected for an agent type, the default LLM if its
+// selection cannot be served.
+func (r *Registry) resolve(ctx context.Context, agent string) ai.LLMService {
+	admin, err := r.loadAdmin(ctx)
+	if err != nil {
+		slog.Warn("Failed to load the model selections, using the environment ones", "error", err)
+	}
+	selection := r.selection(admin, agent)
+	if selection.Source == SourceDefault {
+		return r.fallback
+	}
+	llm, err := r.llm(selection.Provider, selection.Model)
+	if err != nil {
+		slog.Warn("Agent model selection unavailable, using the default LLM",
+			"agent", agent,
+			"provider", selection.Provider,
+			"model", selection.Model,
+			"error", err)
+		return r.fallback
+	}
+	return llm
+}
+
+// LLM returns the LLM of an agent type. It resolves the selection on each
+// call, so that admin changes apply to the running agents.
+func (r *Registry) LLM(agent string) ai.LLMService {
+	return &agentLLM{registry: r, agent: agent}
+}
+
+// agentLLM is the LLM of an agent type.
+type agentLLM struct {
+	registry *Registry
+	agent    string
+}
+
+func (l *agentLLM) Chat(ctx context.Context, messages []ai.Message) (string, *ai.LLMCallStats, error) {
+	return l.registry.resolve(ctx, l.agent).Chat(ctx, messages)
+}
+
+func (l *agentLLM) ChatStream(ctx context.Context, messages []ai.Message) (<-chan string, <-chan *ai.LLMCallStats, <-chan error) {
+	return l.registry.resolve(ctx, l.agent).ChatStream(ctx, messages)
+}
+
+func (l *agentLLM) ChatWithTools(ctx context.Context, messages []ai.Message, tools []ai.ToolDescriptor) (*ai.ChatResponse, *ai.LLMCallStats, error) {
+	return l.registry.resolve(ctx, l.agent).ChatWithTools(ctx, messages, tools)
+}
+
+func (l *agentLLM) Warmup(ctx context.Context) {
+	l.registry.resolve(ctx, l.agent).Warmup(ctx)
+}
@@ -0,0 +1,160 @@
+package llmregistry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/ai"
+)
+
+type fakeStore struct {
+	selections map[string]*Selection
+	listErr    error
+}
+
+func (s *fakeStore) ListSelections(_ context.Context) ([]*Selection, error) {
+	if s.listErr != nil {
+		return nil, s.listErr
+	}
+	selections := []*Selection{}
+	for _, selection := range s.selections {
+		selections = append(selections, selection)
+	}
+	return selections, nil
+}
+
+func (s *fakeStore) SaveSelection(_ context.Context, selection *Selection) (*Selection, error) {
+	saved := *selection
+	saved.UpdatedTs = 42
+	s.selections[selection.Agent] = &saved
+	return &saved, nil
+}
+
+func (s *fakeStore) DeleteSelection(_ context.Context, agent string) error {
+	if _, ok := s.selections[agent]; !ok {
+		return ErrNotFound
+	}
+	delete(s.selections, agent)
+	return nil
+}
+
+// fakeLLM answers with its provider and model.
+type fakeLLM struct {
+	ai.LLMService
+	name string
+}
+
+func (l *fakeLLM) Chat(_ context.Context, _ []ai.Message) (string, *ai.LLMCallStats, error) {
+	return l.name, &ai.LLMCallStats{}, nil
+}
+
+func newTestRegistry(store Store, agentModels map[string]string) *Registry {
+	r := New(store, &Config{
+		Default:    &ai.LLMConfig{Provider: "zai", Model: "glm-4.7"},
+		DefaultLLM: &fakeLLM{name: "default/glm-4.7"},
+		Providers: map[string]ai.LLMConfig{
+			"deepseek": {Provider: "deepseek", Model: "deepseek-chat", APIKey: "secret"},
+			"local":    {Provider: "vllm", BaseURL: "http://localhost:8000/v1"},
+		},
+		AgentModels: agentModels,
+	})
+	r.newLLM = func(cfg *ai.LLMConfig) (ai.LLMService, error) {
+		if cfg.Provider == "broken" {
+			return nil, errors.New("unreachable")
+		}
+		return &fakeLLM{name: cfg.Provider + "/" + cfg.Model}, nil
+	}
+	r.SetAgents([]string{"memo", "schedule", AgentOrchestrator})
+	return r
+}
+
+func chat(t *testing.T, llm ai.LLMService) string {
+	t.Helper()
+	answer, _, err := llm.Chat(context.Background(), nil)
+	require.NoError(t, err)
+	return answer
+}
+
+func TestRegistry_Providers(t *testing.T) {
+	providers := newTestRegistry(nil, nil).Providers()
+	require.Len(t, providers, 3)
+	assert.Equal(t, []string{DefaultProvider, "deepseek", "local"},
+		[]string{providers[0].Name, providers[1].Name, providers[2].Name})
+	assert.Equal(t, &Provider{Name: "deepseek", Provider: "deepseek", Model: "deepseek-chat"}, providers[1])
+}
+
+func TestRegistry_Selections(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{selections: map[string]*Selection{}}
+	r := newTestRegistry(store, map[string]string{
+		"memo":     "deepseek",
+		"schedule": "missing:model",
+	})
+	memo, schedule, orchestrator := r.LLM("memo"), r.LLM("schedule"), r.LLM(AgentOrchestrator)
+
+	// The environment selects the providers; invalid selections are ignored
+	assert.Equal(t, "deepseek/deepseek-chat", chat(t, memo))
+	assert.Equal(t, "default/glm-4.7", chat(t, schedule))
+	assert.Equal(t, "default/glm-4.7", chat(t, orchestrator))
+
+	// Admin selections take precedence and apply to the existing LLMs
+	saved, err := r.Select(ctx, &Selection{Agent: AgentOrchestrator, Provider: "local", Model: " qwen3-32b "})
+	require.NoError(t, err)
+	assert.Equal(t, &Selection{Agent: AgentOrchestrator, Provider: "local", Model: "qwen3-32b", Source: SourceAdmin, UpdatedTs: 42}, saved)
+	assert.Equal(t, "vllm/qwen3-32b", chat(t, orchestrator))
+	_, err = r.Select(ctx, &Selection{Agent: "memo", Provider: DefaultProvider, Model: "glm-4.5-air"})
+	require.NoError(t, err)
+	assert.Equal(t, "zai/glm-4.5-air", chat(t, memo))
+
+	selections, err := r.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*Selection{
+		{Agent: "memo", Provider: DefaultProvider, Model: "glm-4.5-air", Source: SourceAdmin, UpdatedTs: 42},
+		{Agent: AgentOrchestrator, Provider: "local", Model: "qwen3-32b", Source: SourceAdmin, UpdatedTs: 42},
+		{Agent: "schedule", Provider: DefaultProvider, Source: SourceDefault},
+	}, selections)
+
+	// Resetting returns to the environment selection
+	require.NoError(t, r.Reset(ctx, "memo"))
+	assert.Equal(t, "deepseek/deepseek-chat", chat(t, memo))
+	assert.ErrorIs(t, r.Reset(ctx, "memo"), ErrNotFound)
+	assert.NotContains(t, store.selections, "memo")
+
+	// A new registry loads the persisted selections
+	assert.Equal(t, "vllm/qwen3-32b", chat(t, newTestRegistry(store, nil).LLM(AgentOrchestrator)))
+}
+
+func TestRegistry_SelectInvalid(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRegistry(nil, nil)
+	for _, invalid := range []*Selection{
+		{Agent: "memo", Provider: "missing"},
+		{Agent: "memo", Provider: "deepseek", Model: "bad model"},
+		{Agent: "unknown", Provider: "deepseek"},
+		{Agent: "Memo!", Provider: "deepseek"},
+		{Agent: "memo", Provider: "local"}, // vLLM has no default model
+	} {
+		_, err := r.Select(ctx, invalid)
+		assert.ErrorIs(t, err, ErrInvalid, "%+v", invalid)
+	}
+}
+
+func TestRegistry_StoreFailure(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{selections: map[string]*Selection{}, listErr: errors.New("db down")}
+	r := newTestRegistry(store, map[string]string{"memo": "deepseek:deepseek-reasoner"})
+
+	// Agents keep running on the environment selections
+	assert.Equal(t, "deepseek/deepseek-reasoner", chat(t, r.LLM("memo")))
+	_, err := r.List(ctx)
+	require.Error(t, err)
+
+	store.listErr = nil
+	selections, err := r.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, selections, 3)
+}
@@ -0,0 +1,84 @@
+package llmregistry
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DBStore persists selections in the ai_agent_model table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new selection store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const selectionColumns = "agent, provider, model, updated_ts"
+
+// ListSelections implements Store.
+func (s *DBStore) ListSelections(ctx context.Context) ([]*Selection, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+selectionColumns+" FROM ai_agent_model ORDER BY agent")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agent models: %w", err)
+	}
+	defer rows.Close()
+
+	selections := []*Selection{}
+	for rows.Next() {
+		selection, err := scanSelection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan agent model: %w", err)
+		}
+		selections = append(selections, selection)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list agent models: %w", err)
+	}
+	return selections, nil
+}
+
+// SaveSelection implements Store.
+func (s *DBStore) SaveSelection(ctx context.Context, selection *Selection) (*Selection, error) {
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO ai_agent_model (agent, provider, model, updated_ts)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (agent) DO UPDATE SET
+			provider = EXCLUDED.provider,
+			model = EXCLUDED.model,
+			updated_ts = EXCLUDED.updated_ts
+		RETURNING `+selectionColumns,
+		selection.Agent, selection.Provider, selection.Model, time.Now().Unix())
+	saved, err := scanSelection(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save agent model: %w", err)
+	}
+	return saved, nil
+}
+
+// DeleteSelection implements Store.
+func (s *DBStore) DeleteSelection(ctx context.Context, agent string) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM ai_agent_model WHERE agent = $1", agent)
+	if err != nil {
+		return fmt.Errorf("failed to delete agent model: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSelection(row rowScanner) (*Selection, error) {
+	selection := &Selection{Source: SourceAdmin}
+	if err := row.Scan(&selection.Agent, &selection.Provider, &selection.Model, &selection.UpdatedTs); err != nil {
+		return nil, err
+	}
+	return selection, nil
+}
@@ -0,0 +1,356 @@
+package mailbox
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxLineBytes bounds the lines of IMAP responses.
+	maxLineBytes = 64 << 10
+	// maxLiteralBytes bounds the literals of IMAP responses, above the
+	// partial fetches of the client.
+	maxLiteralBytes = 64 << 10
+	// maxResponseTokens bounds the tokens of an IMAP response.
+	maxResponseTokens = 10000
+)
+
+// errProtocol is returned for responses the client does not understand or
+// that exceed its limits.
+var errProtocol = errors.New("unexpected IMAP response")
+
+// imapClient is a read-only IMAP4rev1 client: it examines mailboxes, which
+// never changes their flags, and peeks at messages.
+type imapClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+	tag  int
+	// broken is set once the responses cannot be followed anymore.
+	broken bool
+}
+
+// token is a token of an IMAP response.
+type token struct {
+	kind  tokenKind
+	value []byte
+}
+
+type tokenKind int
+
+const (
+	tokenAtom tokenKind = iota
+	tokenString
+	tokenOpen
+	tokenClose
+)
+
+// newIMAPClient reads the greeting of the server on conn.
+func newIMAPClient(conn net.Conn) (*imapClient, error) {
+	c := &imapClient{conn: conn, r: bufio.NewReaderSize(conn, 4096)}
+	greeting, err := c.readResponse()
+	if err != nil {
+		return nil, err
+	}
+	if len(greeting) < 2 || !greeting[0].is("*") || !greeting[1].is("OK") && !greeting[1].is("PREAUTH") {
+		return nil, fmt.Errorf("%w: greeting %s", errProtocol, describe(greeting))
+	}
+	return c, nil
+}
+
+// login authenticates with the LOGIN command.
+func (c *imapClient) login(username, password string) error {
+	user, err := quote(username)
+	if err != nil {
+		return err
+	}
+	pass, err := quote(password)
+	if err != nil {
+		return err
+	}
+	_, err = c.command("LOGIN " + user + " " + pass)
+	return err
+}
+
+// examine opens a mailbox read-only.
+func (c *imapClient) examine(mailbox string) error {
+	name, err := quote(mailbox)
+	if err != nil {
+		return err
+	}
+	_, err = c.command("EXAMINE " + name)
+	return err
+}
+
+// searchUnseenSince returns the UIDs of the unseen messages received since
+// the day of since, in ascending order.
+func (c *imapClient) searchUnseenSince(since time.Time) ([]uint32, error) {
+	responses, err := c.command("UID SEARCH UNSEEN SINCE " + since.Format("2-Jan-2006"))
+	if err != nil {
+		return nil, err
+	}
+	var uids []uint32
+	for _, response := range responses {
+		if len(response) < 2 || !response[0].is("*") || !response[1].is("SEARCH") {
+			continue
+		}
+		for _, t := range response[2:] {
+			uid, err := strconv.ParseUint(string(t.value), 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("%w: search result %q", errProtocol, t.value)
+			}
+			uids = append(uids, uint32(uid))
+		}
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+	return uids, nil
+}
+
+// fetchedMessage is a message as fetched: its header fields and the start of
+// its body.
+type fetchedMessage struct {
+	uid    uint32
+	size   int64
+	header []byte
+	body   []byte
+}
+
+// fetch peeks at the header fields and the first bodyBytes of the body of
+// messages, without setting their \Seen flag.
+func (c *imapClient) fetch(uids []uint32, bodyBytes int) ([]*fetchedMessage, error) {
+	if len(uids) == 0 {
+		return nil, nil
+	}
+	set := make([]string, len(uids))
+	for i, uid := range uids {
+		set[i] = strconv.FormatUint(uint64(uid), 10)
+	}
+	responses, err := c.command(fmt.Sprintf("UID FETCH %s (UID RFC822.SIZE BODY.PEEK[HEADER.FIELDS (%s)] BODY.PEEK[TEXT]<0.%d>)",
+		strings.Join(set, ","), strings.Join(headerFields, " "), bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	var messages []*fetchedMessage
+	for _, response := range responses {
+		if len(response) < 4 || !response[0].is("*") || !response[2].is("FETCH") || response[3].kind != tokenOpen {
+			continue
+		}
+		msg := &fetchedMessage{}
+		items := response[4:]
+		for len(items) >= 2 && items[0].kind == tokenAtom {
+			key := strings.ToUpper(string(items[0].value))
+			value := items[1]
+			items = items[2:]
+			// Lists, e.g. the FLAGS servers add, are skipped.
+			if value.kind == tokenOpen {
+				items = skipList(items)
+				continue
+			}
+			if value.is("NIL") {
+				continue
+			}
+			switch {
+			case key == "UID":
+				uid, _ := strconv.ParseUint(string(value.value), 10, 32)
+				msg.uid = uint32(uid)
+			case key == "RFC822.SIZE":
+				msg.size, _ = strconv.ParseInt(string(value.value), 10, 64)
+			case strings.HasPrefix(key, "BODY[HEADER"):
+				msg.header = value.value
+			case strings.HasPrefix(key, "BODY[TEXT]"):
+				msg.body = value.value
+			}
+		}
+		if msg.uid != 0 {
+			messages = append(messages, msg)
+		}
+	}
+	return messages, nil
+}
+
+// skipList skips the tokens of a list up to its closing parenthesis.
+func skipList(items []token) []token {
+	for depth := 1; len(items) > 0 && depth > 0; items = items[1:] {
+		switch items[0].kind {
+		case tokenOpen:
+			depth++
+		case tokenClose:
+			depth--
+		}
+	}
+	return items
+}
+
+// logout ends the session unless it is broken; errors are irrelevant as the
+// connection closes.
+func (c *imapClient) logout() {
+	if !c.broken {
+		_, _ = c.command("LOGOUT")
+	}
+}
+
+// command sends a command and returns its untagged responses, failing unless
+// the server completes it with OK.
+func (c *imapClient) command(command string) ([][]token, error) {
+	c.tag++
+	tag := "a" + strconv.Itoa(c.tag)
+	if _, err := io.WriteString(c.conn, tag+" "+command+"\r\n"); err != nil {
+		c.broken = true
+		return nil, fmt.Errorf("failed to send IMAP command: %w", err)
+	}
+	verb, _, _ := strings.Cut(command, " ")
+	var untagged [][]token
+	for {
+		response, err := c.readResponse()
+		if err != nil {
+			c.broken = true
+			return nil, err
+		}
+		if len(response) == 0 {
+			continue
+		}
+		if !response[0].is(tag) {
+			untagged = append(untagged, response)
+			continue
+		}
+		if len(response) < 2 || !response[1].is("OK") {
+			return nil, fmt.Errorf("IMAP %s failed: %s", verb, describe(response[1:]))
+		}
+		return untagged, nil
+	}
+}
+
+// readResponse reads a response, with the literals it announces.
+func (c *imapClient) readResponse() ([]token, error) {
+	var tokens []token
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+		literal := -1
+		if end := len(line) - 1; end > 0 && line[end] == '}' {
+			if start := bytes.LastIndexByte(line, '{'); start >= 0 {
+				n, err := strconv.Atoi(strings.TrimSuffix(string(line[start+1:end]), "+"))
+				if err == nil {
+					literal = n
+					line = line[:start]
+				}
+			}
+		}
+		tokens, err = scan(tokens, line)
+		if err != nil {
+			return nil, err
+		}
+		if literal < 0 {
+			return tokens, nil
+		}
+		if literal > maxLiteralBytes {
+			return nil, fmt.Errorf("%w: literal of %d bytes exceeds the limit", errProtocol, literal)
+		}
+		value := make([]byte, literal)
+		if _, err := io.ReadFull(c.r, value); err != nil {
+			return nil, fmt.Errorf("failed to read IMAP literal: %w", err)
+		}
+		tokens = append(tokens, token{kind: tokenString, value: value})
+	}
+}
+
+// readLine reads a line without its CRLF.
+func (c *imapClient) readLine() ([]byte, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := c.r.ReadLine()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read IMAP response: %w", err)
+		}
+		line = append(line, chunk...)
+		if len(line) > maxLineBytes {
+			return nil, fmt.Errorf("%w: line exceeds %d bytes", errProtocol, maxLineBytes)
+		}
+		if !isPrefix {
+			return line, nil
+		}
+	}
+}
+
+// scan appends the tokens of a response line. Atoms keep their [section] and
+// <partial> suffixes, e.g. BODY[HEADER.FIELDS (FROM)]<0>.
+func scan(tokens []token, line []byte) ([]token, error) {
+	for i := 0; i < len(line); {
+		if len(tokens) > maxResponseTokens {
+			return nil, fmt.Errorf("%w: too many tokens", errProtocol)
+		}
+		switch ch := line[i]; {
+		case ch == ' ':
+			i++
+		case ch == '(':
+			tokens = append(tokens, token{kind: tokenOpen})
+			i++
+		case ch == ')':
+			tokens = append(tokens, token{kind: tokenClose})
+			i++
+		case ch == '"':
+			var value []byte
+			i++
+			for ; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '\\' && i+1 < len(line) {
+					i++
+				}
+				value = append(value, line[i])
+			}
+			if i == len(line) {
+				return nil, fmt.Errorf("%w: unterminated string", errProtocol)
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value})
+			i++
+		default:
+			start := i
+			for i < len(line) && line[i] != ' ' && line[i] != '(' && line[i] != ')' {
+				if line[i] == '[' {
+					if end := bytes.IndexByte(line[i:], ']'); end >= 0 {
+						i += end
+					}
+				}
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenAtom, value: line[start:i]})
+		}
+	}
+	return tokens, nil
+}
+
+func (t token) is(atom string) bool {
+	return t.kind == tokenAtom && strings.EqualFold(string(t.value), atom)
+}
+
+// describe renders tokens for errors.
+func describe(tokens []token) string {
+	parts := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		switch t.kind {
+		case tokenOpen:
+			parts = append(parts, "(")
+		case tokenClose:
+			parts = append(parts, ")")
+		default:
+			parts = append(parts, string(t.value))
+		}
+	}
+	return truncate(strings.Join(parts, " "), 200)
+}
+
+// quote quotes an IMAP string.
+func quote(s string) (string, error) {
+	if strings.ContainsAny(s, "\r\n\x00") {
+		return "", fmt.Errorf("%w: line breaks are not allowed", ErrInvalid)
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`, nil
+}
@@ -0,0 +1,438 @@
+// Package mailbox connects the mail parrot to the IMAP mailboxes of users:
+// it summarizes the unread email of the day and saves the action items the
+// parrot extracts from it to the inbox of the user.
+//
+// Access is read-only: mailboxes are opened with EXAMINE and messages are
+// fetched with BODY.PEEK, so reading never marks email as seen, and no
+// command changing a mailbox is ever sent. Passwords are stored encrypted
+// with DIVINESENSE_EMAIL_SECRET_KEY, and every fetch is bounded: at most
+// MaxMessages messages, their header fields and the first bodyBytes of their
+// body, with limits on the lines and literals of the responses.
+package mailbox
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	chatstore "github.com/hrygo/divinesense/plugin/chat_apps/store"
+	"github.com/hrygo/divinesense/plugin/netguard"
+	storepb "github.com/hrygo/divinesense/proto/gen/store"
+	"github.com/hrygo/divinesense/store"
+)
+
+// ParrotName is the name of the mail parrot, which is only routed to when
+// email accounts can be connected.
+const ParrotName = "mail"
+
+const (
+	// DefaultPort is the port of IMAP over TLS.
+	DefaultPort = 993
+	// DefaultMailbox is the mailbox read by default.
+	DefaultMailbox = "INBOX"
+	// MaxMessages bounds the messages fetched at once, the newest first.
+	MaxMessages = 20
+	// MaxActionItems bounds the action items saved at once.
+	MaxActionItems = 20
+	// bodyBytes is the start of each body fetched.
+	bodyBytes = 8 << 10
+	// maxTextRunes bounds the text of each message reported to the agent.
+	maxTextRunes = 1000
+	// maxTaskRunes bounds the tasks of action items.
+	maxTaskRunes = 300
+	// maxListedActionItems bounds the action items listed, the newest first.
+	maxListedActionItems = 500
+	// dialTimeout bounds the connection to IMAP servers.
+	dialTimeout = 10 * time.Second
+	// sessionTimeout bounds IMAP sessions.
+	sessionTimeout = 30 * time.Second
+)
+
+var (
+	// ErrInvalid is returned for invalid accounts and action items.
+	ErrInvalid = errors.New("invalid input")
+	// ErrNotFound is returned for users without an account.
+	ErrNotFound = errors.New("email account not found")
+	// ErrItemNotFound is returned for unknown action items.
+	ErrItemNotFound = errors.New("action item not found")
+	// ErrDenied is returned when dialing a private address of an account
+	// not allowed to reach the private network.
+	ErrDenied = errors.New("address not allowed")
+)
+
+var (
+	hostPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9.-]*[A-Za-z0-9])?$`)
+	duePattern  = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// Account is the IMAP account of a user.
+type Account struct {
+	UserID   int32  `json:"-"`
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	// Password is only set on saving; accounts never return it.
+	Password          string `json:"password,omitempty"`
+	PasswordEncrypted string `json:"-"`
+	Mailbox           string `json:"mailbox"`
+	// AllowPrivate lets the account reach servers on private addresses; only
+	// admins may save such accounts.
+	AllowPrivate bool  `json:"-"`
+	UpdatedTs    int64 `json:"updated_ts"`
+}
+
+// Store persists the accounts.
+type Store interface {
+	GetAccount(ctx context.Context, userID int32) (*Account, error)
+	// SaveAccount creates or replaces the account of a user.
+	SaveAccount(ctx context.Context, account *Account) (*Account, error)
+	DeleteAccount(ctx context.Context, userID int32) error
+}
+
+// InboxStore holds the action items as inbox messages; *store.Store
+// implements it.
+type InboxStore interface {
+	CreateInbox(ctx context.Context, create *store.Inbox) (*store.Inbox, error)
+	ListInboxes(ctx context.Context, find *store.FindInbox) ([]*store.Inbox, error)
+	UpdateInbox(ctx context.Context, update *store.UpdateInbox) (*store.Inbox, error)
+}
+
+// Config configures the mailboxes.
+type Config struct {
+	// SecretKey encrypts the passwords of the accounts; it must be 32 bytes.
+	SecretKey string
+}
+
+// Mailbox reads the mailboxes of users.
+type Mailbox struct {
+	config Config
+	store  Store
+	inbox  InboxStore
+	// dial connects to an IMAP server over TLS; tests replace it.
+	dial func(ctx context.Context, address string, allowPrivate bool) (net.Conn, error)
+}
+
+// New creates the mailboxes.
+func New(config Config, store Store, inbox InboxStore) (*Mailbox, error) {
+	if len(config.SecretKey) != 32 {
+		return nil, fmt.Errorf("%w: the secret key must be 32 bytes", ErrInvalid)
+	}
+	return &Mailbox{config: config, store: store, inbox: inbox, dial: dialTLS}, nil
+}
+
+// Account returns the account of a user, without its password.
+func (m *Mailbox) Account(ctx context.Context, userID int32) (*Account, error) {
+	account, err := m.store.GetAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return redact(account), nil
+}
+
+// SaveAccount connects the account of a user once it signed in, keeping the
+// saved password when none is given. Accounts on private addresses are
+// refused unless allowPrivate.
+func (m *Mailbox) SaveAccount(ctx context.Context, userID int32, account *Account, allowPrivate bool) (*Account, error) {
+	account.UserID = userID
+	account.Host = strings.ToLower(strings.TrimSpace(account.Host))
+	account.Username = strings.TrimSpace(account.Username)
+	account.Mailbox = strings.TrimSpace(account.Mailbox)
+	account.AllowPrivate = allowPrivate
+	if account.Port == 0 {
+		account.Port = DefaultPort
+	}
+	if account.Mailbox == "" {
+		account.Mailbox = DefaultMailbox
+	}
+	if !hostPattern.MatchString(account.Host) || len(account.Host) > 253 {
+		return nil, fmt.Errorf("%w: host must be a host name, e.g. imap.example.com", ErrInvalid)
+	}
+	if account.Port < 1 || account.Port > 65535 {
+		return nil, fmt.Errorf("%w: invalid port %d", ErrInvalid, account.Port)
+	}
+	if account.Username == "" {
+		return nil, fmt.Errorf("%w: a username is required", ErrInvalid)
+	}
+	password := account.Password
+	if password == "" {
+		saved, err := m.store.GetAccount(ctx, userID)
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("%w: a password is required", ErrInvalid)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if password, err = chatstore.DecryptToken(saved.PasswordEncrypted, m.config.SecretKey); err != nil {
+			return nil, fmt.Errorf("failed to decrypt email password: %w", err)
+		}
+	}
+
+	session, err := m.open(ctx, account, password)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	session.close()
+
+	if account.PasswordEncrypted, err = chatstore.EncryptToken(password, m.config.SecretKey); err != nil {
+		return nil, fmt.Errorf("failed to encrypt email password: %w", err)
+	}
+	saved, err := m.store.SaveAccount(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	return redact(saved), nil
+}
+
+// DeleteAccount disconnects the account of a user.
+func (m *Mailbox) DeleteAccount(ctx context.Context, userID int32) error {
+	return m.store.DeleteAccount(ctx, userID)
+}
+
+// Unread returns the newest unread messages of a user received since since,
+// newest first, at most MaxMessages of them, with the number of unread
+// messages in total. Beyond the messages fetched, the total counts the older
+// unread messages of the search without checking their date.
+func (m *Mailbox) Unread(ctx context.Context, userID int32, since time.Time) ([]*Message, int, error) {
+	account, err := m.store.GetAccount(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	password, err := chatstore.DecryptToken(account.PasswordEncrypted, m.config.SecretKey)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to decrypt email password: %w", err)
+	}
+	session, err := m.open(ctx, account, password)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer session.close()
+
+	// SINCE matches days, in the timezone of the server: the day before is
+	// searched and the messages are filtered by their date.
+	uids, err := session.client.searchUnseenSince(since.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, 0, err
+	}
+	skipped := 0
+	if len(uids) > MaxMessages*2 {
+		skipped = len(uids) - MaxMessages*2
+		uids = uids[skipped:]
+	}
+	fetched, err := session.client.fetch(uids, bodyBytes)
+	if err != nil {
+		return nil, 0, err
+	}
+	var messages []*Message
+	for _, f := range fetched {
+		msg := parseMessage(f, bodyBytes)
+		if !msg.Date.IsZero() && msg.Date.Before(since) {
+			continue
+		}
+		msg.Text = truncate(msg.Text, maxTextRunes)
+		messages = append(messages, msg)
+	}
+	sort.Slice(messages, func(i, j int) bool { return messages[i].UID > messages[j].UID })
+	total := len(messages) + skipped
+	if len(messages) > MaxMessages {
+		messages = messages[:MaxMessages]
+	}
+	return messages, total, nil
+}
+
+// ActionItem is a task extracted from an email.
+type ActionItem struct {
+	ID     int32  `json:"id"`
+	Task   string `json:"task"`
+	Due    string `json:"due,omitempty"`
+	Sender string `json:"sender,omitempty"`
+	// Subject and MessageID identify the email of the task.
+	Subject   string `json:"subject,omitempty"`
+	MessageID string `json:"message_id,omitempty"`
+	Status    string `json:"status"`
+	CreatedTs int64  `json:"created_ts"`
+}
+
+// SaveActionItems saves action items to the inbox of a user, skipping the
+// items already saved for the same email and task. It returns the number of
+// items saved.
+func (m *Mailbox) SaveActionItems(ctx context.Context, userID int32, items []*ActionItem) (int, error) {
+	if len(items) == 0 {
+		return 0, fmt.Errorf("%w: no action items", ErrInvalid)
+	}
+	if len(items) > MaxActionItems {
+		return 0, fmt.Errorf("%w: at most %d action items at once", ErrInvalid, MaxActionItems)
+	}
+	for _, item := range items {
+		item.Task = strings.Join(strings.Fields(item.Task), " ")
+		if item.Task == "" {
+			return 0, fmt.Errorf("%w: every action item needs a task", ErrInvalid)
+		}
+		if item.Due != "" && !duePattern.MatchString(item.Due) {
+			return 0, fmt.Errorf("%w: due %q must be YYYY-MM-DD", ErrInvalid, item.Due)
+		}
+		item.Task = truncate(item.Task, maxTaskRunes)
+		item.Sender = truncate(strings.TrimSpace(item.Sender), 200)
+		item.Subject = truncate(strings.TrimSpace(item.Subject), 200)
+		item.MessageID = truncate(strings.TrimSpace(item.MessageID), 200)
+	}
+
+	existing, err := m.ActionItems(ctx, userID, nil)
+	if err != nil {
+		return 0, err
+	}
+	seen := map[string]bool{}
+	for _, item := range existing {
+		seen[actionItemKey(item)] = true
+	}
+	saved := 0
+	for _, item := range items {
+		key := actionItemKey(item)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if _, err := m.inbox.CreateInbox(ctx, &store.Inbox{
+			SenderID:   userID,
+			ReceiverID: userID,
+			Status:     store.UNREAD,
+			Message: &storepb.InboxMessage{
+				Type: storepb.InboxMessage_EMAIL_ACTION_ITEM,
+				EmailActionItem: &storepb.EmailActionItem{
+					Task:      item.Task,
+					Due:       item.Due,
+					Sender:    item.Sender,
+					Subject:   item.Subject,
+					MessageId: item.MessageID,
+				},
+			},
+		}); err != nil {
+			return saved, fmt.Errorf("failed to save action item: %w", err)
+		}
+		saved++
+	}
+	return saved, nil
+}
+
+// ActionItems returns the newest action items of a user, of any status when
+// status is nil.
+func (m *Mailbox) ActionItems(ctx context.Context, userID int32, status *store.InboxStatus) ([]*ActionItem, error) {
+	messageType := storepb.InboxMessage_EMAIL_ACTION_ITEM
+	limit := maxListedActionItems
+	inboxes, err := m.inbox.ListInboxes(ctx, &store.FindInbox{ReceiverID: &userID, Status: status, MessageType: &messageType, Limit: &limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list action items: %w", err)
+	}
+	items := make([]*ActionItem, 0, len(inboxes))
+	for _, inbox := range inboxes {
+		items = append(items, toActionItem(inbox))
+	}
+	return items, nil
+}
+
+// SetActionItemStatus marks an action item of a user UNREAD or ARCHIVED.
+func (m *Mailbox) SetActionItemStatus(ctx context.Context, userID, id int32, status store.InboxStatus) (*ActionItem, error) {
+	if status != store.UNREAD && status != store.ARCHIVED {
+		return nil, fmt.Errorf("%w: status must be UNREAD or ARCHIVED", ErrInvalid)
+	}
+	messageType := storepb.InboxMessage_EMAIL_ACTION_ITEM
+	inboxes, err := m.inbox.ListInboxes(ctx, &store.FindInbox{ID: &id, ReceiverID: &userID, MessageType: &messageType})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get action item: %w", err)
+	}
+	if len(inboxes) == 0 {
+		return nil, ErrItemNotFound
+	}
+	updated, err := m.inbox.UpdateInbox(ctx, &store.UpdateInbox{ID: id, Status: status})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update action item: %w", err)
+	}
+	return toActionItem(updated), nil
+}
+
+func toActionItem(inbox *store.Inbox) *ActionItem {
+	item := &ActionItem{ID: inbox.ID, Status: inbox.Status.String(), CreatedTs: inbox.CreatedTs}
+	if email := inbox.Message.GetEmailActionItem(); email != nil {
+		item.Task = email.Task
+		item.Due = email.Due
+		item.Sender = email.Sender
+		item.Subject = email.Subject
+		item.MessageID = email.MessageId
+	}
+	return item
+}
+
+func actionItemKey(item *ActionItem) string {
+	return item.MessageID + "\x00" + strings.ToLower(item.Task)
+}
+
+// session is a signed-in IMAP session with the mailbox of an account open.
+type session struct {
+	conn   net.Conn
+	client *imapClient
+}
+
+// open signs in to an account and examines its mailbox.
+func (m *Mailbox) open(ctx context.Context, account *Account, password string) (*session, error) {
+	address := net.JoinHostPort(account.Host, strconv.Itoa(account.Port))
+	conn, err := m.dial(ctx, address, account.AllowPrivate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	deadline := time.Now().Add(sessionTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+	client, err := newIMAPClient(conn)
+	if err == nil {
+		err = client.login(account.Username, password)
+	}
+	if err == nil {
+		err = client.examine(account.Mailbox)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &session{conn: conn, client: client}, nil
+}
+
+func (s *session) close() {
+	s.client.logout()
+	s.conn.Close()
+}
+
+// dialTLS connects to an IMAP server over TLS, to public addresses only
+// unless allowPrivate, so that users cannot reach the private network of the
+// server through their accounts.
+func dialTLS(ctx context.Context, address string, allowPrivate bool) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	if !allowPrivate {
+		dialer.Control = netguard.Control(ErrDenied)
+	}
+	tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{MinVersion: tls.VersionTLS12}}
+	return tlsDialer.DialContext(ctx, "tcp", address)
+}
+
+// redact removes the password of an account.
+func redact(account *Account) *Account {
+	account.Password = ""
+	account.PasswordEncrypted = ""
+	return account
+}
+
+// truncate shortens a text to limit runes.
+func truncate(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	return string([]rune(text)[:limit]) + "…"
+}
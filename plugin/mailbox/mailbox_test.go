@@ -0,0 +1,292 @@
+package mailbox
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	storepb "github.com/hrygo/divinesense/proto/gen/store"
+	"github.com/hrygo/divinesense/store"
+)
+
+const testKey = "0123456789abcdef0123456789abcdef"
+
+// memStore is an in-memory Store.
+type memStore struct {
+	accounts map[int32]*Account
+}
+
+func (s *memStore) GetAccount(_ context.Context, userID int32) (*Account, error) {
+	if account, ok := s.accounts[userID]; ok {
+		copied := *account
+		return &copied, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (s *memStore) SaveAccount(_ context.Context, account *Account) (*Account, error) {
+	copied := *account
+	copied.Password = ""
+	copied.UpdatedTs = 1
+	s.accounts[account.UserID] = &copied
+	saved := copied
+	return &saved, nil
+}
+
+func (s *memStore) DeleteAccount(_ context.Context, userID int32) error {
+	if _, ok := s.accounts[userID]; !ok {
+		return ErrNotFound
+	}
+	delete(s.accounts, userID)
+	return nil
+}
+
+// memInbox is an in-memory InboxStore.
+type memInbox struct {
+	inboxes []*store.Inbox
+}
+
+func (s *memInbox) CreateInbox(_ context.Context, create *store.Inbox) (*store.Inbox, error) {
+	create.ID = int32(len(s.inboxes) + 1)
+	s.inboxes = append(s.inboxes, create)
+	return create, nil
+}
+
+func (s *memInbox) ListInboxes(_ context.Context, find *store.FindInbox) ([]*store.Inbox, error) {
+	var list []*store.Inbox
+	for _, inbox := range s.inboxes {
+		if (find.ID == nil || *find.ID == inbox.ID) &&
+			(find.ReceiverID == nil || *find.ReceiverID == inbox.ReceiverID) &&
+			(find.Status == nil || *find.Status == inbox.Status) &&
+			(find.MessageType == nil || *find.MessageType == inbox.Message.Type) {
+			list = append(list, inbox)
+		}
+	}
+	return list, nil
+}
+
+func (s *memInbox) UpdateInbox(_ context.Context, update *store.UpdateInbox) (*store.Inbox, error) {
+	inbox := s.inboxes[update.ID-1]
+	inbox.Status = update.Status
+	return inbox, nil
+}
+
+// fakeServer is a scripted IMAP server recording the commands it receives.
+type fakeServer struct {
+	password string
+	// messages are the FETCH responses, by UID.
+	messages map[string]string
+
+	mu       sync.Mutex
+	commands []string
+}
+
+func (f *fakeServer) dial(_ context.Context, _ string, _ bool) (net.Conn, error) {
+	client, server := net.Pipe()
+	go f.serve(server)
+	return client, nil
+}
+
+func (f *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	fmt.Fprint(conn, "* OK IMAP4rev1 ready\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		tag, command, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		f.mu.Lock()
+		f.commands = append(f.commands, command)
+		f.mu.Unlock()
+		verb := strings.ToUpper(strings.Fields(command)[0])
+		if verb == "UID" {
+			verb += " " + strings.ToUpper(strings.Fields(command)[1])
+		}
+		switch verb {
+		case "LOGIN":
+			if !strings.HasSuffix(command, fmt.Sprintf("%q", f.password)) {
+				fmt.Fprintf(conn, "%s NO [AUTHENTICATIONFAILED] Invalid credentials\r\n", tag)
+				continue
+			}
+		case "EXAMINE":
+			fmt.Fprint(conn, "* 3 EXISTS\r\n* FLAGS (\\Seen \\Answered)\r\n")
+		case "UID SEARCH":
+			uids := make([]string, 0, len(f.messages))
+			for uid := range f.messages {
+				uids = append(uids, uid)
+			}
+			fmt.Fprintf(conn, "* SEARCH %s\r\n", strings.Join(uids, " "))
+		case "UID FETCH":
+			for uid, response := range f.messages {
+				if strings.Contains(command, uid) {
+					fmt.Fprint(conn, response)
+				}
+			}
+		case "LOGOUT":
+			fmt.Fprintf(conn, "* BYE\r\n%s OK LOGOUT completed\r\n", tag)
+			return
+		}
+		fmt.Fprintf(conn, "%s OK done\r\n", tag)
+	}
+}
+
+// fetchResponse renders the FETCH response of a message.
+func fetchResponse(seq, uid int, header, body string) string {
+	return fmt.Sprintf("* %d FETCH (UID %d FLAGS () RFC822.SIZE %d BODY[HEADER.FIELDS (FROM SUBJECT)] {%d}\r\n%s BODY[TEXT]<0> {%d}\r\n%s)\r\n",
+		seq, uid, len(header)+len(body), len(header), header, len(body), body)
+}
+
+func newMailbox(t *testing.T, server *fakeServer) (*Mailbox, *memStore, *memInbox) {
+	t.Helper()
+	accounts, inbox := &memStore{accounts: map[int32]*Account{}}, &memInbox{}
+	m, err := New(Config{SecretKey: testKey}, accounts, inbox)
+	require.NoError(t, err)
+	m.dial = server.dial
+	return m, accounts, inbox
+}
+
+func TestSaveAccount(t *testing.T) {
+	ctx := context.Background()
+	server := &fakeServer{password: "secret"}
+	m, accounts, _ := newMailbox(t, server)
+
+	_, err := New(Config{SecretKey: "short"}, accounts, nil)
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = m.SaveAccount(ctx, 1, &Account{Host: "imap.example.com", Username: "alice", Password: "wrong"}, false)
+	assert.ErrorIs(t, err, ErrInvalid, "the account must sign in")
+	_, err = m.SaveAccount(ctx, 1, &Account{Host: "https://imap.example.com", Username: "alice", Password: "secret"}, false)
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = m.SaveAccount(ctx, 1, &Account{Host: "imap.example.com", Username: "alice"}, false)
+	assert.ErrorIs(t, err, ErrInvalid, "a password is required at first")
+
+	saved, err := m.SaveAccount(ctx, 1, &Account{Host: " IMAP.example.com", Username: "alice", Password: "secret"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, &Account{UserID: 1, Host: "imap.example.com", Port: DefaultPort, Username: "alice", Mailbox: DefaultMailbox, UpdatedTs: 1}, saved)
+	stored := accounts.accounts[1]
+	assert.NotContains(t, stored.PasswordEncrypted, "secret", "passwords are stored encrypted")
+	assert.NotEmpty(t, stored.PasswordEncrypted)
+
+	_, err = m.SaveAccount(ctx, 1, &Account{Host: "imap.example.com", Username: "alice", Mailbox: "Work"}, false)
+	require.NoError(t, err, "the saved password is kept")
+	assert.Contains(t, server.commands, `EXAMINE "Work"`)
+	for _, command := range server.commands {
+		assert.NotRegexp(t, `^(SELECT|STORE|UID STORE|EXPUNGE|COPY|MOVE)`, command, "mailboxes are read-only")
+	}
+
+	require.NoError(t, m.DeleteAccount(ctx, 1))
+	_, err = m.Account(ctx, 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestUnread(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	date := func(d time.Time) string { return d.Format(time.RFC1123Z) }
+	server := &fakeServer{password: "secret", messages: map[string]string{
+		"41": fetchResponse(1, 41,
+			"From: =?UTF-8?B?5byg5LiJ?= <zhang@example.com>\r\nSubject: =?UTF-8?Q?Q3_=E6=8A=A5=E5=91=8A?=\r\nDate: "+date(now)+"\r\nMessage-ID: <a@example.com>\r\n"+
+				"Content-Type: multipart/alternative; boundary=b1\r\n",
+			"--b1\r\nContent-Type: text/html; charset=utf-8\r\n\r\n<p>html only</p>\r\n"+
+				"--b1\r\nContent-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\nPlease send the =\r\nreport by Friday.\r\n--b1--\r\n"),
+		"42": fetchResponse(2, 42,
+			"From: bob@example.com\r\nSubject: Lunch\r\nDate: "+date(now)+"\r\nContent-Type: text/html\r\n",
+			"<html><head><style>p{}</style></head><body><p>Lunch at&nbsp;12?</p></body></html>"),
+		"7": fetchResponse(3, 7,
+			"From: old@example.com\r\nSubject: Old\r\nDate: "+date(now.AddDate(0, 0, -3))+"\r\n",
+			"old"),
+	}}
+	m, _, _ := newMailbox(t, server)
+
+	_, _, err := m.Unread(ctx, 1, now)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = m.SaveAccount(ctx, 1, &Account{Host: "imap.example.com", Username: "alice", Password: "secret"}, false)
+	require.NoError(t, err)
+
+	messages, total, err := m.Unread(ctx, 1, now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, total, "older messages are left out")
+	require.Len(t, messages, 2)
+	assert.Equal(t, uint32(42), messages[0].UID, "newest first")
+	assert.Equal(t, "Lunch at 12?", messages[0].Text)
+	assert.Equal(t, "张三 <zhang@example.com>", messages[1].From)
+	assert.Equal(t, "Q3 报告", messages[1].Subject)
+	assert.Equal(t, "<a@example.com>", messages[1].MessageID)
+	assert.Equal(t, "Please send the report by Friday.", messages[1].Text, "text/plain is preferred")
+
+	var fetch string
+	for _, command := range server.commands {
+		if strings.HasPrefix(command, "UID FETCH") {
+			fetch = command
+		}
+	}
+	assert.Contains(t, fetch, "BODY.PEEK[TEXT]<0.8192>", "bodies are peeked at, within the size limit")
+	assert.NotContains(t, fetch, "BODY[", "fetches never mark email as seen")
+}
+
+func TestOversizedLiteral(t *testing.T) {
+	ctx := context.Background()
+	server := &fakeServer{password: "secret", messages: map[string]string{
+		"1": fmt.Sprintf("* 1 FETCH (UID 1 BODY[TEXT]<0> {%d}\r\n", maxLiteralBytes+1),
+	}}
+	m, _, _ := newMailbox(t, server)
+	_, err := m.SaveAccount(ctx, 1, &Account{Host: "imap.example.com", Username: "alice", Password: "secret"}, false)
+	require.NoError(t, err)
+
+	_, _, err = m.Unread(ctx, 1, time.Now())
+	assert.ErrorIs(t, err, errProtocol)
+}
+
+func TestQuote(t *testing.T) {
+	quoted, err := quote(`pa"ss\word`)
+	require.NoError(t, err)
+	assert.Equal(t, `"pa\"ss\\word"`, quoted)
+	_, err = quote("pass\r\na2 DELETE INBOX")
+	assert.ErrorIs(t, err, ErrInvalid, "commands cannot be injected")
+}
+
+func TestSaveActionItems(t *testing.T) {
+	ctx := context.Background()
+	m, _, inbox := newMailbox(t, &fakeServer{})
+
+	items := []*ActionItem{
+		{Task: " Send the   report ", Due: "2026-10-20", Sender: "zhang@example.com", Subject: "Q3", MessageID: "<a@example.com>"},
+		{Task: "Book a room", MessageID: "<b@example.com>"},
+	}
+	saved, err := m.SaveActionItems(ctx, 1, items)
+	require.NoError(t, err)
+	assert.Equal(t, 2, saved)
+	require.Len(t, inbox.inboxes, 2)
+	assert.Equal(t, storepb.InboxMessage_EMAIL_ACTION_ITEM, inbox.inboxes[0].Message.Type)
+	assert.Equal(t, "Send the report", inbox.inboxes[0].Message.EmailActionItem.Task)
+	assert.Equal(t, store.UNREAD, inbox.inboxes[0].Status)
+
+	saved, err = m.SaveActionItems(ctx, 1, []*ActionItem{{Task: "send the report", MessageID: "<a@example.com>"}, {Task: "Reply", MessageID: "<a@example.com>"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, saved, "saved items are skipped")
+
+	_, err = m.SaveActionItems(ctx, 1, []*ActionItem{{Task: "x", Due: "Friday"}})
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = m.SaveActionItems(ctx, 1, []*ActionItem{{Task: " "}})
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	item, err := m.SetActionItemStatus(ctx, 1, 2, store.ARCHIVED)
+	require.NoError(t, err)
+	assert.Equal(t, "ARCHIVED", item.Status)
+	_, err = m.SetActionItemStatus(ctx, 2, 2, store.ARCHIVED)
+	assert.ErrorIs(t, err, ErrItemNotFound, "items of other users are not found")
+
+	unread := store.UNREAD
+	items, err = m.ActionItems(ctx, 1, &unread)
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+}
@@ -0,0 +1,164 @@
+package mailbox
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// headerFields are the header fields fetched with each message.
+var headerFields = []string{"FROM", "SUBJECT", "DATE", "MESSAGE-ID", "CONTENT-TYPE", "CONTENT-TRANSFER-ENCODING"}
+
+// maxPartDepth bounds the nesting of multipart bodies.
+const maxPartDepth = 3
+
+// Message is an unread email.
+type Message struct {
+	UID       uint32    `json:"uid"`
+	From      string    `json:"from"`
+	Subject   string    `json:"subject"`
+	Date      time.Time `json:"date"`
+	MessageID string    `json:"message_id"`
+	// Text is the start of the plain text of the body.
+	Text string `json:"text"`
+	// Truncated is whether the body was longer than the fetched part.
+	Truncated bool `json:"truncated"`
+}
+
+var wordDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+// parseMessage decodes a fetched message. Bodies cut by the partial fetch
+// decode as far as they go.
+func parseMessage(fetched *fetchedMessage, bodyBytes int) *Message {
+	header, _ := textproto.NewReader(bufio.NewReader(bytes.NewReader(append(fetched.header, "\r\n\r\n"...)))).ReadMIMEHeader()
+	msg := &Message{
+		UID:       fetched.uid,
+		From:      decodeHeader(header.Get("From")),
+		Subject:   decodeHeader(header.Get("Subject")),
+		MessageID: strings.TrimSpace(header.Get("Message-Id")),
+		Truncated: fetched.size > 0 && len(fetched.body) >= bodyBytes,
+	}
+	if date, err := mail.ParseDate(header.Get("Date")); err == nil {
+		msg.Date = date
+	}
+	msg.Text = cleanText(bodyText(header.Get("Content-Type"), header.Get("Content-Transfer-Encoding"), fetched.body, 0))
+	return msg
+}
+
+// decodeHeader decodes the encoded words of a header, e.g. =?UTF-8?B?...?=.
+func decodeHeader(value string) string {
+	if decoded, err := wordDecoder.DecodeHeader(value); err == nil {
+		value = decoded
+	}
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// bodyText returns the text of a body: its first text/plain part, else its
+// first text/html part without markup.
+func bodyText(contentType, encoding string, body []byte, depth int) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		if depth >= maxPartDepth || params["boundary"] == "" {
+			return ""
+		}
+		reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		htmlText := ""
+		for {
+			part, err := reader.NextRawPart()
+			if err != nil {
+				return htmlText
+			}
+			data, _ := io.ReadAll(part)
+			partType := part.Header.Get("Content-Type")
+			if partType == "" {
+				partType = "text/plain"
+			}
+			if strings.HasPrefix(strings.ToLower(partType), "text/html") {
+				if htmlText == "" {
+					htmlText = bodyText(partType, part.Header.Get("Content-Transfer-Encoding"), data, depth+1)
+				}
+				continue
+			}
+			if text := bodyText(partType, part.Header.Get("Content-Transfer-Encoding"), data, depth+1); text != "" {
+				return text
+			}
+		}
+	case mediaType == "text/plain":
+		return decodeBody(body, encoding, params["charset"])
+	case mediaType == "text/html":
+		return htmlToText(decodeBody(body, encoding, params["charset"]))
+	}
+	return ""
+}
+
+// decodeBody decodes the transfer encoding and charset of a body.
+func decodeBody(body []byte, encoding, charset string) string {
+	var reader io.Reader = bytes.NewReader(body)
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		reader = base64.NewDecoder(base64.StdEncoding, reader)
+	case "quoted-printable":
+		reader = quotedprintable.NewReader(reader)
+	}
+	if charset != "" {
+		if decoded, err := charsetReader(charset, reader); err == nil {
+			reader = decoded
+		}
+	}
+	// Cut bodies fail at their end; what decoded until then is kept.
+	data, _ := io.ReadAll(reader)
+	return strings.ToValidUTF8(string(data), "")
+}
+
+// charsetReader decodes the charsets of the web, e.g. GBK and ISO-8859-1.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	if strings.EqualFold(charset, "utf-8") || strings.EqualFold(charset, "us-ascii") {
+		return input, nil
+	}
+	encoding, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported charset %q", charset)
+	}
+	return encoding.NewDecoder().Reader(input), nil
+}
+
+var (
+	htmlSkipped = regexp.MustCompile(`(?is)<(style|script|head)[^>]*>.*?</(style|script|head)>`)
+	htmlBreaks  = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|tr|h[1-6])>`)
+	htmlTags    = regexp.MustCompile(`<[^>]*>`)
+	blankLines  = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToText strips the markup of an HTML body.
+func htmlToText(body string) string {
+	body = htmlSkipped.ReplaceAllString(body, "")
+	body = htmlBreaks.ReplaceAllString(body, "\n")
+	body = htmlTags.ReplaceAllString(body, "")
+	return html.UnescapeString(body)
+}
+
+// cleanText trims the lines of a text and collapses its blank lines.
+func cleanText(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(strings.ReplaceAll(line, "\u00a0", " "), " \t")
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -0,0 +1,82 @@
+package mailbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DBStore persists the accounts in the email_account table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new account store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const accountColumns = "user_id, host, port, username, password_encrypted, mailbox, allow_private, updated_ts"
+
+// GetAccount implements Store.
+func (s *DBStore) GetAccount(ctx context.Context, userID int32) (*Account, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+accountColumns+" FROM email_account WHERE user_id = $1", userID)
+	account, err := scanAccount(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email account: %w", err)
+	}
+	return account, nil
+}
+
+// SaveAccount implements Store.
+func (s *DBStore) SaveAccount(ctx context.Context, account *Account) (*Account, error) {
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO email_account (user_id, host, port, username, password_encrypted, mailbox, allow_private, updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (user_id) DO UPDATE SET
+			host = EXCLUDED.host,
+			port = EXCLUDED.port,
+			username = EXCLUDED.username,
+			password_encrypted = EXCLUDED.password_encrypted,
+			mailbox = EXCLUDED.mailbox,
+			allow_private = EXCLUDED.allow_private,
+			updated_ts = EXCLUDED.updated_ts
+		RETURNING `+accountColumns,
+		account.UserID, account.Host, account.Port, account.Username, account.PasswordEncrypted,
+		account.Mailbox, account.AllowPrivate, time.Now().Unix())
+	saved, err := scanAccount(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save email account: %w", err)
+	}
+	return saved, nil
+}
+
+// DeleteAccount implements Store.
+func (s *DBStore) DeleteAccount(ctx context.Context, userID int32) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM email_account WHERE user_id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete email account: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAccount(row rowScanner) (*Account, error) {
+	account := &Account{}
+	if err := row.Scan(&account.UserID, &account.Host, &account.Port, &account.Username, &account.PasswordEncrypted,
+		&account.Mailbox, &account.AllowPrivate, &account.UpdatedTs); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
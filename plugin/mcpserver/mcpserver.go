@@ -0,0 +1,232 @@
+// Package mcpserver holds the external MCP servers users register for their
+// Geek Mode sessions.
+//
+// A server is a command the Claude Code CLI starts (stdio) or a remote
+// endpoint (http, sse). The CC runner passes the enabled servers of a user to
+// the CLI with --mcp-config; their tools are named mcp__<name>__<tool> and are
+// subject to the tool policy of the workspace. A stdio server runs a command
+// on the host, so it is only started in workspaces whose policy permits Bash.
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+)
+
+var (
+	// ErrNotFound is returned for servers the user has not registered.
+	ErrNotFound = errors.New("mcp server not found")
+	// ErrInvalid is returned for servers that fail validation.
+	ErrInvalid = errors.New("invalid mcp server")
+)
+
+const (
+	// MaxServers is the number of servers a user may register.
+	MaxServers = 20
+	// maxArgs bounds the arguments of a stdio server.
+	maxArgs = 50
+	// maxVariables bounds the environment variables and headers of a server.
+	maxVariables = 50
+)
+
+var (
+	// namePattern matches the server names. They cannot contain "__", which
+	// separates the server from the tool in the tool names.
+	namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*(_[A-Za-z0-9-]+)*$`)
+	// envPattern matches the environment variable names.
+	envPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// headerPattern matches the HTTP header names.
+	headerPattern = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+)
+
+// Server is an MCP server registered by a user. Env and Headers may hold
+// credentials: they are never serialized to clients, only their names are.
+type Server struct {
+	ID        int32    `json:"id"`
+	UserID    int32    `json:"user_id"`
+	Name      string   `json:"name"`
+	Transport string   `json:"transport"`
+	Command   string   `json:"command,omitempty"`
+	Args      []string `json:"args"`
+	URL       string   `json:"url,omitempty"`
+	Enabled   bool     `json:"enabled"`
+	CreatedTs int64    `json:"created_ts"`
+	UpdatedTs int64    `json:"updated_ts"`
+
+	Env     map[string]string `json:"-"`
+	Headers map[string]string `json:"-"`
+	// EnvNames and HeaderNames are the names of Env and Headers.
+	EnvNames    []string `json:"env_names"`
+	HeaderNames []string `json:"header_names"`
+}
+
+// Validate checks and normalizes a server about to be saved.
+func (s *Server) Validate() error {
+	s.Name = strings.TrimSpace(s.Name)
+	s.Command = strings.TrimSpace(s.Command)
+	s.URL = strings.TrimSpace(s.URL)
+	if len(s.Name) > 64 || !namePattern.MatchString(s.Name) {
+		return fmt.Errorf("%w: name must be up to 64 letters, digits, '-' or single '_'", ErrInvalid)
+	}
+	switch s.Transport {
+	case agentpkg.MCPTransportStdio:
+		if s.Command == "" {
+			return fmt.Errorf("%w: a stdio server needs a command", ErrInvalid)
+		}
+		if s.URL != "" || len(s.Headers) > 0 {
+			return fmt.Errorf("%w: a stdio server takes no url or headers", ErrInvalid)
+		}
+	case agentpkg.MCPTransportHTTP, agentpkg.MCPTransportSSE:
+		u, err := url.Parse(s.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: a %s server needs an http(s) url", ErrInvalid, s.Transport)
+		}
+		if s.Command != "" || len(s.Args) > 0 || len(s.Env) > 0 {
+			return fmt.Errorf("%w: a %s server takes no command, args or env", ErrInvalid, s.Transport)
+		}
+	default:
+		return fmt.Errorf("%w: transport must be stdio, http or sse", ErrInvalid)
+	}
+	if len(s.Args) > maxArgs {
+		return fmt.Errorf("%w: at most %d args", ErrInvalid, maxArgs)
+	}
+	if err := checkNames(s.Env, envPattern, "environment variable"); err != nil {
+		return err
+	}
+	return checkNames(s.Headers, headerPattern, "header")
+}
+
+func checkNames(values map[string]string, pattern *regexp.Regexp, kind string) error {
+	if len(values) > maxVariables {
+		return fmt.Errorf("%w: at most %d %ss", ErrInvalid, maxVariables, kind)
+	}
+	for name := range values {
+		if !pattern.MatchString(name) {
+			return fmt.Errorf("%w: invalid %s name %q", ErrInvalid, kind, name)
+		}
+	}
+	return nil
+}
+
+// MCPServer returns the server as passed to the CC runner.
+func (s *Server) MCPServer() agentpkg.MCPServer {
+	return agentpkg.MCPServer{
+		Name:      s.Name,
+		Transport: s.Transport,
+		Command:   s.Command,
+		Args:      s.Args,
+		Env:       s.Env,
+		URL:       s.URL,
+		Headers:   s.Headers,
+	}
+}
+
+// Store persists the servers.
+type Store interface {
+	ListServers(ctx context.Context, userID int32) ([]*Server, error)
+	// GetServer returns ErrNotFound for servers the user has not registered.
+	GetServer(ctx context.Context, userID, id int32) (*Server, error)
+	CreateServer(ctx context.Context, server *Server) (*Server, error)
+	// UpdateServer returns ErrNotFound for servers the user has not registered.
+	UpdateServer(ctx context.Context, server *Server) (*Server, error)
+	// DeleteServer returns ErrNotFound for servers the user has not registered.
+	DeleteServer(ctx context.Context, userID, id int32) error
+}
+
+// Servers manages the MCP servers of the users.
+type Servers struct {
+	store Store
+}
+
+// New creates the servers.
+func New(store Store) *Servers {
+	return &Servers{store: store}
+}
+
+// List returns the servers of a user, by name.
+func (s *Servers) List(ctx context.Context, userID int32) ([]*Server, error) {
+	return s.store.ListServers(ctx, userID)
+}
+
+// Create validates and registers a server of a user.
+func (s *Servers) Create(ctx context.Context, server *Server) (*Server, error) {
+	if err := server.Validate(); err != nil {
+		return nil, err
+	}
+	existing, err := s.store.ListServers(ctx, server.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxServers {
+		return nil, fmt.Errorf("%w: at most %d servers", ErrInvalid, MaxServers)
+	}
+	if err := checkUniqueName(existing, server); err != nil {
+		return nil, err
+	}
+	return s.store.CreateServer(ctx, server)
+}
+
+// Update validates and replaces a server of a user. Nil Env and Headers keep
+// the current ones, which clients cannot read back.
+func (s *Servers) Update(ctx context.Context, server *Server) (*Server, error) {
+	current, err := s.store.GetServer(ctx, server.UserID, server.ID)
+	if err != nil {
+		return nil, err
+	}
+	if server.Env == nil {
+		server.Env = current.Env
+	}
+	if server.Headers == nil {
+		server.Headers = current.Headers
+	}
+	if err := server.Validate(); err != nil {
+		return nil, err
+	}
+	existing, err := s.store.ListServers(ctx, server.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUniqueName(existing, server); err != nil {
+		return nil, err
+	}
+	return s.store.UpdateServer(ctx, server)
+}
+
+// Delete removes a server of a user.
+func (s *Servers) Delete(ctx context.Context, userID, id int32) error {
+	return s.store.DeleteServer(ctx, userID, id)
+}
+
+// Resolve returns the enabled servers of a user as passed to the CC runner.
+// Stdio servers are left out unless the tool policy of the workspace permits
+// Bash: they run commands on the host too.
+func (s *Servers) Resolve(ctx context.Context, userID int32, policy *agentpkg.ToolPolicy) ([]agentpkg.MCPServer, error) {
+	servers, err := s.store.ListServers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var resolved []agentpkg.MCPServer
+	for _, server := range servers {
+		if !server.Enabled || (server.Transport == agentpkg.MCPTransportStdio && !policy.Permits("Bash")) {
+			continue
+		}
+		resolved = append(resolved, server.MCPServer())
+	}
+	return resolved, nil
+}
+
+func checkUniqueName(existing []*Server, server *Server) error {
+	if slices.ContainsFunc(existing, func(other *Server) bool {
+		return other.ID != server.ID && strings.EqualFold(other.Name, server.Name)
+	}) {
+		return fmt.Errorf("%w: a server named %q already exists", ErrInvalid, server.Name)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package mcpserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+)
+
+type fakeStore struct {
+	servers map[int32]*Server
+	nextID  int32
+}
+
+func (s *fakeStore) ListServers(_ context.Context, userID int32) ([]*Server, error) {
+	servers := []*Server{}
+	for _, server := range s.servers {
+		if server.UserID == userID {
+			servers = append(servers, server)
+		}
+	}
+	return servers, nil
+}
+
+func (s *fakeStore) GetServer(_ context.Context, userID, id int32) (*Server, error) {
+	server, ok := s.servers[id]
+	if !ok || server.UserID != userID {
+		return nil, ErrNotFound
+	}
+	return server, nil
+}
+
+func (s *fakeStore) CreateServer(_ context.Context, server *Server) (*Server, error) {
+	s.nextID++
+	server.ID = s.nextID
+	s.servers[server.ID] = server
+	return server, nil
+}
+
+func (s *fakeStore) UpdateServer(_ context.Context, server *Server) (*Server, error) {
+	s.servers[server.ID] = server
+	return server, nil
+}
+
+func (s *fakeStore) DeleteServer(_ context.Context, userID, id int32) error {
+	if _, err := s.GetServer(context.Background(), userID, id); err != nil {
+		return err
+	}
+	delete(s.servers, id)
+	return nil
+}
+
+func TestServer_Validate(t *testing.T) {
+	stdio := &Server{Name: " github ", Transport: "stdio", Command: "npx", Args: []string{"-y", "github-mcp"}, Env: map[string]string{"GITHUB_TOKEN": "t"}}
+	require.NoError(t, stdio.Validate())
+	assert.Equal(t, "github", stdio.Name)
+	require.NoError(t, (&Server{Name: "my_docs-2", Transport: "http", URL: "https://docs.example.com/mcp", Headers: map[string]string{"Authorization": "Bearer t"}}).Validate())
+
+	for _, invalid := range []*Server{
+		{Name: "a__b", Transport: "stdio", Command: "x"},
+		{Name: "trailing_", Transport: "stdio", Command: "x"},
+		{Name: "", Transport: "stdio", Command: "x"},
+		{Name: "a", Transport: "ws", URL: "https://x"},
+		{Name: "a", Transport: "stdio"},
+		{Name: "a", Transport: "stdio", Command: "x", URL: "https://x"},
+		{Name: "a", Transport: "sse", URL: "file:///etc/passwd"},
+		{Name: "a", Transport: "http", URL: "https://x", Command: "x"},
+		{Name: "a", Transport: "stdio", Command: "x", Env: map[string]string{"BAD-NAME": "v"}},
+		{Name: "a", Transport: "http", URL: "https://x", Headers: map[string]string{"Bad Header": "v"}},
+	} {
+		assert.ErrorIs(t, invalid.Validate(), ErrInvalid, "%+v", invalid)
+	}
+}
+
+func TestServers_CRUD(t *testing.T) {
+	ctx := context.Background()
+	servers := New(&fakeStore{servers: map[int32]*Server{}})
+
+	created, err := servers.Create(ctx, &Server{UserID: 1, Name: "github", Transport: "stdio", Command: "npx", Env: map[string]string{"GITHUB_TOKEN": "t"}, Enabled: true})
+	require.NoError(t, err)
+	_, err = servers.Create(ctx, &Server{UserID: 1, Name: "GitHub", Transport: "http", URL: "https://x"})
+	assert.ErrorIs(t, err, ErrInvalid, "names are unique per user")
+	_, err = servers.Create(ctx, &Server{UserID: 2, Name: "github", Transport: "http", URL: "https://x"})
+	require.NoError(t, err, "other users may use the name")
+
+	// Omitted env keeps the current one, which clients cannot read back
+	updated, err := servers.Update(ctx, &Server{ID: created.ID, UserID: 1, Name: "github", Transport: "stdio", Command: "uvx", Enabled: true})
+	require.NoError(t, err)
+	assert.Equal(t, "uvx", updated.Command)
+	assert.Equal(t, map[string]string{"GITHUB_TOKEN": "t"}, updated.Env)
+
+	_, err = servers.Update(ctx, &Server{ID: created.ID, UserID: 2, Name: "github", Transport: "stdio", Command: "x"})
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, servers.Delete(ctx, 2, created.ID), ErrNotFound)
+	require.NoError(t, servers.Delete(ctx, 1, created.ID))
+}
+
+func TestServers_Resolve(t *testing.T) {
+	ctx := context.Background()
+	servers := New(&fakeStore{servers: map[int32]*Server{}})
+	for _, server := range []*Server{
+		{UserID: 1, Name: "github", Transport: "stdio", Command: "npx", Enabled: true},
+		{UserID: 1, Name: "docs", Transport: "http", URL: "https://docs.example.com/mcp", Enabled: true},
+		{UserID: 1, Name: "off", Transport: "sse", URL: "https://off.example.com", Enabled: false},
+	} {
+		_, err := servers.Create(ctx, server)
+		require.NoError(t, err)
+	}
+
+	names := func(resolved []agentpkg.MCPServer) []string {
+		var names []string
+		for _, server := range resolved {
+			names = append(names, server.Name)
+		}
+		return names
+	}
+	resolved, err := servers.Resolve(ctx, 1, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"github", "docs"}, names(resolved))
+
+	resolved, err = servers.Resolve(ctx, 1, &agentpkg.ToolPolicy{Disallowed: []string{"Bash"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs"}, names(resolved), "stdio servers need Bash")
+
+	resolved, err = servers.Resolve(ctx, 2, nil)
+	require.NoError(t, err)
+	assert.Empty(t, resolved)
+}
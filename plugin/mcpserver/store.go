@@ -0,0 +1,156 @@
+package mcpserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/hrygo/divinesense/internal/util"
+)
+
+// DBStore persists servers in the geek_mcp_server table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new server store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const serverColumns = "id, user_id, name, transport, command, args, env, url, headers, enabled, created_ts, updated_ts"
+
+// ListServers implements Store.
+func (s *DBStore) ListServers(ctx context.Context, userID int32) ([]*Server, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+serverColumns+" FROM geek_mcp_server WHERE user_id = $1 ORDER BY name", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mcp servers: %w", err)
+	}
+	defer rows.Close()
+
+	servers := []*Server{}
+	for rows.Next() {
+		server, err := scanServer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan mcp server: %w", err)
+		}
+		servers = append(servers, server)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list mcp servers: %w", err)
+	}
+	return servers, nil
+}
+
+// GetServer implements Store.
+func (s *DBStore) GetServer(ctx context.Context, userID, id int32) (*Server, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+serverColumns+" FROM geek_mcp_server WHERE id = $1 AND user_id = $2", id, userID)
+	server, err := scanServer(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mcp server: %w", err)
+	}
+	return server, nil
+}
+
+// CreateServer implements Store.
+func (s *DBStore) CreateServer(ctx context.Context, server *Server) (*Server, error) {
+	env, headers, err := marshalVariables(server)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO geek_mcp_server (user_id, name, transport, command, args, env, url, headers, enabled, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+		RETURNING `+serverColumns,
+		server.UserID, server.Name, server.Transport, server.Command, pq.Array(util.OrEmpty(server.Args)), env, server.URL, headers, server.Enabled, now)
+	created, err := scanServer(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mcp server: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateServer implements Store.
+func (s *DBStore) UpdateServer(ctx context.Context, server *Server) (*Server, error) {
+	env, headers, err := marshalVariables(server)
+	if err != nil {
+		return nil, err
+	}
+	row := s.db.QueryRowContext(ctx, `
+		UPDATE geek_mcp_server SET
+			name = $3, transport = $4, command = $5, args = $6, env = $7, url = $8, headers = $9, enabled = $10, updated_ts = $11
+		WHERE id = $1 AND user_id = $2
+		RETURNING `+serverColumns,
+		server.ID, server.UserID, server.Name, server.Transport, server.Command, pq.Array(util.OrEmpty(server.Args)), env, server.URL, headers, server.Enabled, time.Now().Unix())
+	updated, err := scanServer(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update mcp server: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteServer implements Store.
+func (s *DBStore) DeleteServer(ctx context.Context, userID, id int32) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM geek_mcp_server WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete mcp server: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanServer(row rowScanner) (*Server, error) {
+	server := &Server{}
+	var env, headers []byte
+	if err := row.Scan(&server.ID, &server.UserID, &server.Name, &server.Transport, &server.Command, pq.Array(&server.Args),
+		&env, &server.URL, &headers, &server.Enabled, &server.CreatedTs, &server.UpdatedTs); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(env, &server.Env); err != nil {
+		return nil, fmt.Errorf("invalid env: %w", err)
+	}
+	if err := json.Unmarshal(headers, &server.Headers); err != nil {
+		return nil, fmt.Errorf("invalid headers: %w", err)
+	}
+	server.Args = util.OrEmpty(server.Args)
+	server.EnvNames = util.OrEmpty(slices.Sorted(maps.Keys(server.Env)))
+	server.HeaderNames = util.OrEmpty(slices.Sorted(maps.Keys(server.Headers)))
+	return server, nil
+}
+
+func marshalVariables(server *Server) (env, headers []byte, err error) {
+	if env, err = json.Marshal(orEmptyMap(server.Env)); err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal env: %w", err)
+	}
+	if headers, err = json.Marshal(orEmptyMap(server.Headers)); err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal headers: %w", err)
+	}
+	return env, headers, nil
+}
+
+func orEmptyMap(values map[string]string) map[string]string {
+	if values == nil {
+		return map[string]string{}
+	}
+	return values
+}
@@ -0,0 +1,275 @@
+// Package meetingnotes turns raw meeting notes into structured memos.
+//
+// The meeting expert reads pasted notes or a transcript and extracts the
+// summary, the decisions and the action items with their owners and due
+// dates. The meeting_notes tool renders them as a memo in a fixed layout.
+// Once the user confirms which action items to track, the meeting_schedule
+// tool reads them back from that memo, which the user may have edited
+// meanwhile, and adds them to the schedule as all-day entries on their due
+// dates.
+package meetingnotes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Tag is the tag of the memos of meeting notes.
+const Tag = "meeting"
+
+const (
+	// MaxActionItems bounds the action items of a meeting.
+	MaxActionItems = 30
+	// maxTitleRunes bounds the title of a meeting.
+	maxTitleRunes = 100
+)
+
+// ErrInvalid is returned for invalid meeting notes and schedule requests.
+var ErrInvalid = errors.New("invalid meeting notes")
+
+// Meeting is the structure of meeting notes.
+type Meeting struct {
+	Title string `json:"title"`
+	// Date is the day of the meeting (YYYY-MM-DD).
+	Date        string       `json:"date,omitempty"`
+	Attendees   []string     `json:"attendees,omitempty"`
+	Summary     string       `json:"summary,omitempty"`
+	Decisions   []string     `json:"decisions,omitempty"`
+	ActionItems []ActionItem `json:"action_items,omitempty"`
+}
+
+// ActionItem is a task agreed on in a meeting.
+type ActionItem struct {
+	Task  string `json:"task"`
+	Owner string `json:"owner,omitempty"`
+	// Due is the due date (YYYY-MM-DD).
+	Due string `json:"due,omitempty"`
+}
+
+// Normalize trims the notes and drops their empty entries. It fails without
+// a title or content, or with malformed dates.
+func (m *Meeting) Normalize() error {
+	m.Title = oneLine(m.Title)
+	m.Date = strings.TrimSpace(m.Date)
+	m.Summary = strings.TrimSpace(m.Summary)
+	if m.Title == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalid)
+	}
+	if utf8.RuneCountInString(m.Title) > maxTitleRunes {
+		return fmt.Errorf("%w: title exceeds %d characters", ErrInvalid, maxTitleRunes)
+	}
+	if m.Date != "" && !isDate(m.Date) {
+		return fmt.Errorf("%w: date must be YYYY-MM-DD", ErrInvalid)
+	}
+	m.Attendees = nonEmpty(m.Attendees)
+	m.Decisions = nonEmpty(m.Decisions)
+
+	items := m.ActionItems[:0]
+	for _, item := range m.ActionItems {
+		item = ActionItem{Task: oneLine(item.Task), Owner: strings.TrimPrefix(oneLine(item.Owner), "@"), Due: strings.TrimSpace(item.Due)}
+		if item.Task == "" {
+			continue
+		}
+		if item.Due != "" && !isDate(item.Due) {
+			return fmt.Errorf("%w: due date of %q must be YYYY-MM-DD", ErrInvalid, item.Task)
+		}
+		items = append(items, item)
+	}
+	m.ActionItems = items
+	if len(m.ActionItems) > MaxActionItems {
+		return fmt.Errorf("%w: at most %d action items", ErrInvalid, MaxActionItems)
+	}
+	if m.Summary == "" && len(m.Decisions) == 0 && len(m.ActionItems) == 0 {
+		return fmt.Errorf("%w: summary, decisions or action items are required", ErrInvalid)
+	}
+	return nil
+}
+
+// Render renders the notes as the content of a memo.
+func Render(m *Meeting) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", m.Title)
+	var meta []string
+	if m.Date != "" {
+		meta = append(meta, "日期："+m.Date)
+	}
+	if len(m.Attendees) > 0 {
+		meta = append(meta, "参会："+strings.Join(m.Attendees, "、"))
+	}
+	if len(meta) > 0 {
+		fmt.Fprintf(&b, "%s\n\n", strings.Join(meta, " · "))
+	}
+	if m.Summary != "" {
+		fmt.Fprintf(&b, "%s\n\n", m.Summary)
+	}
+	if len(m.Decisions) > 0 {
+		b.WriteString("## 决策\n\n")
+		for _, decision := range m.Decisions {
+			fmt.Fprintf(&b, "- %s\n", decision)
+		}
+		b.WriteString("\n")
+	}
+	if len(m.ActionItems) > 0 {
+		b.WriteString("## 行动项\n\n")
+		for i, item := range m.ActionItems {
+			fmt.Fprintf(&b, "%d. [ ] %s", i+1, item.Task)
+			if item.Owner != "" {
+				fmt.Fprintf(&b, " @%s", item.Owner)
+			}
+			if item.Due != "" {
+				fmt.Fprintf(&b, " 截止 %s", item.Due)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "#%s\n", Tag)
+	return b.String()
+}
+
+// actionItemPattern matches the action items rendered by Render.
+var actionItemPattern = regexp.MustCompile(`^(\d+)\. \[[ xX]\] (.+?)(?: @([^@]+?))?(?: 截止 (\d{4}-\d{2}-\d{2}))?$`)
+
+// ParseActionItems returns the action items of a memo rendered by Render, by
+// their number.
+func ParseActionItems(content string) map[int]ActionItem {
+	items := map[int]ActionItem{}
+	inSection := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "#"+Tag) {
+			inSection = line == "## 行动项"
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		match := actionItemPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(match[1])
+		items[n] = ActionItem{Task: match[2], Owner: match[3], Due: match[4]}
+	}
+	return items
+}
+
+// Memos creates and reads the memos of users.
+type Memos interface {
+	// CreateMemo creates a private memo of the user and returns its UID.
+	CreateMemo(ctx context.Context, userID int32, content string) (string, error)
+	// GetMemoContent returns the content of a memo of the user, or false.
+	GetMemoContent(ctx context.Context, userID int32, uid string) (string, bool, error)
+}
+
+// Scheduler adds entries to the schedule of users.
+type Scheduler interface {
+	// CreateAllDay creates an all-day entry on day (midnight in its location).
+	CreateAllDay(ctx context.Context, userID int32, title, description string, day time.Time) error
+}
+
+// Notes records meeting notes and schedules their action items.
+type Notes struct {
+	memos     Memos
+	scheduler Scheduler
+}
+
+// New creates the meeting notes.
+func New(memos Memos, scheduler Scheduler) *Notes {
+	return &Notes{memos: memos, scheduler: scheduler}
+}
+
+// Save normalizes the notes and saves them as a memo of the user. It returns
+// the UID of the memo.
+func (n *Notes) Save(ctx context.Context, userID int32, m *Meeting) (string, error) {
+	if err := m.Normalize(); err != nil {
+		return "", err
+	}
+	uid, err := n.memos.CreateMemo(ctx, userID, Render(m))
+	if err != nil {
+		return "", fmt.Errorf("failed to save meeting notes: %w", err)
+	}
+	return uid, nil
+}
+
+// ScheduleResult reports the action items added to the schedule.
+type ScheduleResult struct {
+	Scheduled []ActionItem `json:"scheduled"`
+	// Skipped are the items left out, with the reason, by number.
+	Skipped map[int]string `json:"skipped,omitempty"`
+}
+
+// Schedule adds the action items of a meeting memo of the user, by number, to
+// their schedule on their due dates in loc. Items without a due date are
+// skipped.
+func (n *Notes) Schedule(ctx context.Context, userID int32, memoUID string, numbers []int, loc *time.Location) (*ScheduleResult, error) {
+	if len(numbers) == 0 {
+		return nil, fmt.Errorf("%w: no action item selected", ErrInvalid)
+	}
+	content, ok, err := n.memos.GetMemoContent(ctx, userID, memoUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get meeting memo: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: memo %s not found", ErrInvalid, memoUID)
+	}
+	items := ParseActionItems(content)
+	title, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(content), "# "), "\n")
+
+	result := &ScheduleResult{Scheduled: []ActionItem{}, Skipped: map[int]string{}}
+	seen := map[int]bool{}
+	for _, number := range numbers {
+		if seen[number] {
+			continue
+		}
+		seen[number] = true
+		item, ok := items[number]
+		switch {
+		case !ok:
+			result.Skipped[number] = "no such action item"
+			continue
+		case item.Due == "":
+			result.Skipped[number] = "no due date"
+			continue
+		}
+		day, err := time.ParseInLocation(time.DateOnly, item.Due, loc)
+		if err != nil {
+			result.Skipped[number] = "invalid due date"
+			continue
+		}
+		description := fmt.Sprintf("会议「%s」的行动项 /memos/%s", title, memoUID)
+		if item.Owner != "" {
+			description = fmt.Sprintf("负责人：%s\n%s", item.Owner, description)
+		}
+		if err := n.scheduler.CreateAllDay(ctx, userID, item.Task, description, day); err != nil {
+			return result, fmt.Errorf("failed to schedule %q: %w", item.Task, err)
+		}
+		result.Scheduled = append(result.Scheduled, item)
+	}
+	return result, nil
+}
+
+func isDate(s string) bool {
+	_, err := time.Parse(time.DateOnly, s)
+	return err == nil
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func nonEmpty(values []string) []string {
+	var kept []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
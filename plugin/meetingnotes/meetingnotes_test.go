@@ -0,0 +1,132 @@
+package meetingnotes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMemos struct {
+	contents map[string]string
+}
+
+func (m *fakeMemos) CreateMemo(_ context.Context, _ int32, content string) (string, error) {
+	if m.contents == nil {
+		m.contents = map[string]string{}
+	}
+	m.contents["m1"] = content
+	return "m1", nil
+}
+
+func (m *fakeMemos) GetMemoContent(_ context.Context, _ int32, uid string) (string, bool, error) {
+	content, ok := m.contents[uid]
+	return content, ok, nil
+}
+
+type scheduled struct {
+	title, description string
+	day                time.Time
+}
+
+type fakeScheduler struct {
+	entries []scheduled
+}
+
+func (s *fakeScheduler) CreateAllDay(_ context.Context, _ int32, title, description string, day time.Time) error {
+	s.entries = append(s.entries, scheduled{title: title, description: description, day: day})
+	return nil
+}
+
+func meeting() *Meeting {
+	return &Meeting{
+		Title:     "  产品\n周会 ",
+		Date:      "2026-10-12",
+		Attendees: []string{"张三", " ", "Li Si"},
+		Summary:   "讨论了发布计划",
+		Decisions: []string{"10 月底发布 v2", ""},
+		ActionItems: []ActionItem{
+			{Task: "整理发布清单", Owner: "@张三", Due: "2026-10-20"},
+			{Task: "  "},
+			{Task: "联系客户", Owner: "Li Si"},
+			{Task: "更新文档", Due: "2026-10-23"},
+		},
+	}
+}
+
+func TestMeetingNormalize(t *testing.T) {
+	m := meeting()
+	require.NoError(t, m.Normalize())
+	assert.Equal(t, "产品 周会", m.Title)
+	assert.Equal(t, []string{"张三", "Li Si"}, m.Attendees)
+	assert.Equal(t, []string{"10 月底发布 v2"}, m.Decisions)
+	assert.Equal(t, []ActionItem{
+		{Task: "整理发布清单", Owner: "张三", Due: "2026-10-20"},
+		{Task: "联系客户", Owner: "Li Si"},
+		{Task: "更新文档", Due: "2026-10-23"},
+	}, m.ActionItems)
+
+	for _, invalid := range []*Meeting{
+		{Summary: "no title"},
+		{Title: "empty"},
+		{Title: "bad date", Date: "12/10/2026", Summary: "x"},
+		{Title: "bad due", ActionItems: []ActionItem{{Task: "x", Due: "next friday"}}},
+	} {
+		assert.ErrorIs(t, invalid.Normalize(), ErrInvalid)
+	}
+}
+
+func TestRenderParseActionItems(t *testing.T) {
+	m := meeting()
+	require.NoError(t, m.Normalize())
+	content := Render(m)
+	assert.Contains(t, content, "# 产品 周会\n")
+	assert.Contains(t, content, "日期：2026-10-12 · 参会：张三、Li Si")
+	assert.Contains(t, content, "## 决策\n\n- 10 月底发布 v2\n")
+	assert.Contains(t, content, "1. [ ] 整理发布清单 @张三 截止 2026-10-20\n")
+	assert.Contains(t, content, "#meeting\n")
+
+	items := ParseActionItems(content)
+	assert.Equal(t, map[int]ActionItem{
+		1: {Task: "整理发布清单", Owner: "张三", Due: "2026-10-20"},
+		2: {Task: "联系客户", Owner: "Li Si"},
+		3: {Task: "更新文档", Due: "2026-10-23"},
+	}, items)
+
+	// Items checked off or edited by the user are read back too.
+	edited := "# Sync\n\n## 行动项\n\n1. [x] Ship it @Ann 截止 2026-11-02\n- not an item\n\n## Other\n\n2. [ ] Ignored\n"
+	assert.Equal(t, map[int]ActionItem{1: {Task: "Ship it", Owner: "Ann", Due: "2026-11-02"}}, ParseActionItems(edited))
+}
+
+func TestNotesSchedule(t *testing.T) {
+	memos := &fakeMemos{}
+	scheduler := &fakeScheduler{}
+	notes := New(memos, scheduler)
+	ctx := context.Background()
+
+	uid, err := notes.Save(ctx, 1, meeting())
+	require.NoError(t, err)
+	assert.Equal(t, "m1", uid)
+
+	loc := time.FixedZone("Asia/Shanghai", 8*3600)
+	result, err := notes.Schedule(ctx, 1, uid, []int{1, 2, 1, 3, 9}, loc)
+	require.NoError(t, err)
+	assert.Equal(t, []ActionItem{
+		{Task: "整理发布清单", Owner: "张三", Due: "2026-10-20"},
+		{Task: "更新文档", Due: "2026-10-23"},
+	}, result.Scheduled)
+	assert.Equal(t, map[int]string{2: "no due date", 9: "no such action item"}, result.Skipped)
+
+	require.Len(t, scheduler.entries, 2)
+	assert.Equal(t, "整理发布清单", scheduler.entries[0].title)
+	assert.Equal(t, "负责人：张三\n会议「产品 周会」的行动项 /memos/m1", scheduler.entries[0].description)
+	assert.Equal(t, time.Date(2026, 10, 20, 0, 0, 0, 0, loc), scheduler.entries[0].day)
+	assert.Equal(t, "会议「产品 周会」的行动项 /memos/m1", scheduler.entries[1].description)
+
+	_, err = notes.Schedule(ctx, 1, uid, nil, loc)
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = notes.Schedule(ctx, 1, "missing", []int{1}, loc)
+	assert.ErrorIs(t, err, ErrInvalid)
+}
@@ -0,0 +1,154 @@
+package meetingnotes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	agents "github.com/hrygo/divinesense/ai/agents"
+)
+
+// Names of the agent tools of the meeting expert.
+const (
+	NotesToolName    = "meeting_notes"
+	ScheduleToolName = "meeting_schedule"
+)
+
+// ScheduleInput is the input of the meeting_schedule tool.
+type ScheduleInput struct {
+	MemoUID string `json:"memo_uid"`
+	// Items are the numbers of the confirmed action items.
+	Items    []int  `json:"items"`
+	Timezone string `json:"timezone"`
+}
+
+// NotesToolFor returns the meeting_notes tool of a user, or nil without notes.
+func (n *Notes) NotesToolFor(userID int32) agents.ToolWithSchema {
+	if n == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		NotesToolName,
+		"Save structured meeting notes as a memo: title, date, attendees, a short summary, the decisions, "+
+			"and the action items with their owner and due date (YYYY-MM-DD) when the notes give them. "+
+			"Returns the memo UID and the numbered action items. Does not touch the schedule.",
+		func(ctx context.Context, input string) (string, error) {
+			var meeting Meeting
+			if err := json.Unmarshal([]byte(input), &meeting); err != nil {
+				return "", fmt.Errorf("invalid input: %w", err)
+			}
+			uid, err := n.Save(ctx, userID, &meeting)
+			if err != nil {
+				return "", err
+			}
+			return formatSaved(uid, &meeting), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"title":     map[string]any{"type": "string", "description": "Short title of the meeting"},
+				"date":      map[string]any{"type": "string", "description": "Day of the meeting, YYYY-MM-DD"},
+				"attendees": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				"summary":   map[string]any{"type": "string", "description": "A few sentences on what was discussed"},
+				"decisions": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				"action_items": map[string]any{
+					"type": "array",
+					"items": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"task":  map[string]any{"type": "string"},
+							"owner": map[string]any{"type": "string", "description": "Who does it, as named in the notes"},
+							"due":   map[string]any{"type": "string", "description": "Due date, YYYY-MM-DD; omit when the notes give none"},
+						},
+						"required": []string{"task"},
+					},
+				},
+			},
+			"required": []string{"title"},
+		},
+	)
+}
+
+// ScheduleToolFor returns the meeting_schedule tool of a user, or nil without
+// notes.
+func (n *Notes) ScheduleToolFor(userID int32) agents.ToolWithSchema {
+	if n == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		ScheduleToolName,
+		"Add action items of a meeting memo saved by meeting_notes to the user's schedule, as all-day entries on their due dates. "+
+			"Only call it once the user has confirmed, in a message after the notes were saved, which action items to add; "+
+			"pass the numbers of those items. Items without a due date are skipped. "+
+			`Input: {"memo_uid": "...", "items": [1, 3], "timezone": "Asia/Shanghai"}.`,
+		func(ctx context.Context, input string) (string, error) {
+			var in ScheduleInput
+			if err := json.Unmarshal([]byte(input), &in); err != nil {
+				return "", fmt.Errorf("invalid input: %w", err)
+			}
+			loc := time.Local
+			if in.Timezone != "" {
+				var err error
+				if loc, err = time.LoadLocation(in.Timezone); err != nil {
+					return "", fmt.Errorf("%w: unknown timezone %q", ErrInvalid, in.Timezone)
+				}
+			}
+			result, err := n.Schedule(ctx, userID, strings.TrimSpace(in.MemoUID), in.Items, loc)
+			if err != nil {
+				return "", err
+			}
+			return formatScheduled(result), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"memo_uid": map[string]any{"type": "string", "description": "UID of the meeting memo"},
+				"items":    map[string]any{"type": "array", "items": map[string]any{"type": "integer"}, "description": "Numbers of the confirmed action items"},
+				"timezone": map[string]any{"type": "string", "description": "IANA time zone of the due dates, e.g. Asia/Shanghai"},
+			},
+			"required": []string{"memo_uid", "items"},
+		},
+	)
+}
+
+// formatSaved reports saved notes to the agent.
+func formatSaved(uid string, m *Meeting) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "✓ Meeting notes saved: %s - %s (/memos/%s)\n", uid, m.Title, uid)
+	if len(m.ActionItems) == 0 {
+		b.WriteString("No action items.")
+		return b.String()
+	}
+	b.WriteString("Action items:\n")
+	for i, item := range m.ActionItems {
+		fmt.Fprintf(&b, "%d. %s", i+1, item.Task)
+		if item.Owner != "" {
+			fmt.Fprintf(&b, " (owner: %s)", item.Owner)
+		}
+		if item.Due != "" {
+			fmt.Fprintf(&b, " due %s", item.Due)
+		} else {
+			b.WriteString(" (no due date, cannot be scheduled)")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("Ask the user which action items to add to their schedule; do not schedule them before they confirm.")
+	return b.String()
+}
+
+// formatScheduled reports scheduled action items to the agent.
+func formatScheduled(result *ScheduleResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "✓ %d action item(s) added to the schedule", len(result.Scheduled))
+	for _, item := range result.Scheduled {
+		fmt.Fprintf(&b, "\n- %s on %s", item.Task, item.Due)
+	}
+	for _, number := range slices.Sorted(maps.Keys(result.Skipped)) {
+		fmt.Fprintf(&b, "\nSkipped item %d: %s", number, result.Skipped[number])
+	}
+	return b.String()
+}
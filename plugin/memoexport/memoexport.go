@@ -0,0 +1,222 @@
+// Package memoexport exports the memos of a user as a zip archive: a
+// Markdown file per memo, with its metadata as YAML frontmatter, and the
+// attachments of the memos. The memoimport package imports archives back
+// (format "divinesense"), skipping the memos the user already has.
+//
+// Exports can be incremental: an export since a time only has the memos
+// updated since then, and the manifest of each export records the time the
+// next export should start from. Comments and deleted memos are not
+// exported.
+package memoexport
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalid is returned for invalid exports.
+var ErrInvalid = errors.New("invalid export")
+
+const (
+	// Version is the version of the archive layout.
+	Version = 1
+	// ManifestPath is the path of the manifest in archives.
+	ManifestPath = "manifest.json"
+	// MemoDir and AttachmentDir are the folders of the memos and attachments
+	// of archives.
+	MemoDir       = "memos"
+	AttachmentDir = "attachments"
+	// pageSize is the number of memos listed at once.
+	pageSize = 200
+)
+
+// Attachment is an attachment of a memo.
+type Attachment struct {
+	UID      string
+	Filename string
+	Type     string
+	Size     int64
+}
+
+// Memo is a memo to export.
+type Memo struct {
+	UID        string
+	Content    string
+	Visibility string
+	Pinned     bool
+	Archived   bool
+	Tags       []string
+	// CreatedTs and UpdatedTs are in seconds.
+	CreatedTs   int64
+	UpdatedTs   int64
+	Attachments []*Attachment
+}
+
+// Store lists the memos to export.
+type Store interface {
+	// ListMemos returns a page of the memos of a user updated at or after
+	// since, oldest first, without comments.
+	ListMemos(ctx context.Context, userID int32, since int64, offset, limit int) ([]*Memo, error)
+	// ReadAttachment returns the content of an attachment.
+	ReadAttachment(ctx context.Context, attachment *Attachment) ([]byte, error)
+}
+
+// Options are the options of an export.
+type Options struct {
+	UserID int32
+	// Since exports only the memos updated at or after it, in seconds; 0
+	// exports all the memos.
+	Since int64
+}
+
+// Manifest describes an export; it is the manifest.json of the archive.
+type Manifest struct {
+	Version int `json:"version"`
+	// ExportedTs is the time of the export, which the next incremental
+	// export starts from.
+	ExportedTs  int64 `json:"exported_ts"`
+	Since       int64 `json:"since,omitempty"`
+	Memos       int   `json:"memos"`
+	Attachments int   `json:"attachments"`
+	// Missing are the attachments whose content could not be read, which
+	// the archive lacks.
+	Missing []string `json:"missing,omitempty"`
+}
+
+// Exporter exports memos.
+type Exporter struct {
+	store Store
+}
+
+// New creates an exporter.
+func New(store Store) *Exporter {
+	return &Exporter{store: store}
+}
+
+// Export writes the archive of the memos of a user to w. Once writing has
+// started, an error leaves w with a partial archive.
+func (e *Exporter) Export(ctx context.Context, w io.Writer, opts *Options) (*Manifest, error) {
+	if opts.Since < 0 {
+		return nil, fmt.Errorf("%w: since must not be negative", ErrInvalid)
+	}
+	manifest := &Manifest{Version: Version, ExportedTs: time.Now().Unix(), Since: opts.Since}
+	archive := zip.NewWriter(w)
+	for offset := 0; ; offset += pageSize {
+		memos, err := e.store.ListMemos(ctx, opts.UserID, opts.Since, offset, pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list memos: %w", err)
+		}
+		for _, memo := range memos {
+			if err := e.exportMemo(ctx, archive, memo, manifest); err != nil {
+				return nil, err
+			}
+		}
+		if len(memos) < pageSize {
+			break
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFile(archive, ManifestPath, data, time.Unix(manifest.ExportedTs, 0)); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// exportMemo writes a memo and its attachments to the archive.
+func (e *Exporter) exportMemo(ctx context.Context, archive *zip.Writer, memo *Memo, manifest *Manifest) error {
+	front := &Frontmatter{
+		UID:        memo.UID,
+		Created:    time.Unix(memo.CreatedTs, 0).UTC(),
+		Updated:    time.Unix(memo.UpdatedTs, 0).UTC(),
+		Visibility: memo.Visibility,
+		Pinned:     memo.Pinned,
+		Archived:   memo.Archived,
+		Tags:       memo.Tags,
+	}
+	for _, attachment := range memo.Attachments {
+		attachmentPath := AttachmentPath(attachment)
+		blob, err := e.store.ReadAttachment(ctx, attachment)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			manifest.Missing = append(manifest.Missing, attachmentPath)
+			continue
+		}
+		if err := writeFile(archive, attachmentPath, blob, time.Unix(memo.UpdatedTs, 0)); err != nil {
+			return err
+		}
+		front.Attachments = append(front.Attachments, attachmentPath)
+		manifest.Attachments++
+	}
+
+	data, err := yaml.Marshal(front)
+	if err != nil {
+		return fmt.Errorf("failed to encode memo %s: %w", memo.UID, err)
+	}
+	content := "---\n" + string(data) + "---\n" + memo.Content
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if err := writeFile(archive, MemoPath(memo.UID), []byte(content), time.Unix(memo.UpdatedTs, 0)); err != nil {
+		return err
+	}
+	manifest.Memos++
+	return nil
+}
+
+// Frontmatter is the YAML frontmatter of the memos of archives.
+type Frontmatter struct {
+	UID        string    `yaml:"uid"`
+	Created    time.Time `yaml:"created"`
+	Updated    time.Time `yaml:"updated"`
+	Visibility string    `yaml:"visibility"`
+	Pinned     bool      `yaml:"pinned,omitempty"`
+	Archived   bool      `yaml:"archived,omitempty"`
+	// Tags are the tags of the content, hierarchical tags included.
+	Tags []string `yaml:"tags,omitempty,flow"`
+	// Attachments are the paths of the attachments in the archive.
+	Attachments []string `yaml:"attachments,omitempty"`
+}
+
+// MemoPath returns the path of a memo in archives.
+func MemoPath(uid string) string {
+	return path.Join(MemoDir, uid+".md")
+}
+
+// AttachmentPath returns the path of an attachment in archives, which keeps
+// its file name.
+func AttachmentPath(attachment *Attachment) string {
+	filename := strings.NewReplacer("/", "_", "\\", "_").Replace(attachment.Filename)
+	if filename == "" || filename == "." || filename == ".." {
+		filename = "file"
+	}
+	return path.Join(AttachmentDir, attachment.UID, filename)
+}
+
+func writeFile(archive *zip.Writer, name string, data []byte, modified time.Time) error {
+	file, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
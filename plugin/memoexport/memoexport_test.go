@@ -0,0 +1,158 @@
+package memoexport_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/plugin/memoexport"
+	"github.com/hrygo/divinesense/plugin/memoimport"
+)
+
+type fakeStore struct {
+	memos []*memoexport.Memo
+	blobs map[string][]byte
+}
+
+func (s *fakeStore) ListMemos(_ context.Context, _ int32, since int64, offset, limit int) ([]*memoexport.Memo, error) {
+	var memos []*memoexport.Memo
+	for _, memo := range s.memos {
+		if memo.UpdatedTs >= since {
+			memos = append(memos, memo)
+		}
+	}
+	if offset >= len(memos) {
+		return nil, nil
+	}
+	return memos[offset:min(offset+limit, len(memos))], nil
+}
+
+func (s *fakeStore) ReadAttachment(_ context.Context, attachment *memoexport.Attachment) ([]byte, error) {
+	blob, ok := s.blobs[attachment.UID]
+	if !ok {
+		return nil, errors.New("file not found")
+	}
+	return blob, nil
+}
+
+type importStore struct {
+	memos []*memoimport.Memo
+}
+
+func (s *importStore) ContentHashes(context.Context, int32) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+func (s *importStore) CreateMemo(_ context.Context, memo *memoimport.Memo) (string, error) {
+	s.memos = append(s.memos, memo)
+	return fmt.Sprintf("m%d", len(s.memos)), nil
+}
+
+func newFakeStore() *fakeStore {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC).Unix()
+	return &fakeStore{
+		memos: []*memoexport.Memo{
+			{
+				UID: "trip", Content: "# Kyoto\n\n---\n\nBook the hotel #travel/japan", Visibility: "PROTECTED",
+				Pinned: true, Tags: []string{"travel/japan"}, CreatedTs: created, UpdatedTs: created + 60,
+				Attachments: []*memoexport.Attachment{
+					{UID: "a1", Filename: "map.png", Type: "image/png"},
+					{UID: "a2", Filename: "lost.pdf", Type: "application/pdf"},
+				},
+			},
+			{UID: "old", Content: "Archived idea", Visibility: "PRIVATE", Archived: true, CreatedTs: created - 3600, UpdatedTs: created + 120},
+		},
+		blobs: map[string][]byte{"a1": []byte("\x89PNG map")},
+	}
+}
+
+func TestExport(t *testing.T) {
+	var buf bytes.Buffer
+	manifest, err := memoexport.New(newFakeStore()).Export(context.Background(), &buf, &memoexport.Options{UserID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, manifest.Memos)
+	assert.Equal(t, 1, manifest.Attachments)
+	assert.Equal(t, []string{"attachments/a2/lost.pdf"}, manifest.Missing)
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	files := map[string]string{}
+	for _, file := range archive.File {
+		r, err := file.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		files[file.Name] = string(data)
+	}
+	assert.Equal(t, "---\n"+
+		"uid: trip\n"+
+		"created: 2026-01-02T03:04:05Z\n"+
+		"updated: 2026-01-02T03:05:05Z\n"+
+		"visibility: PROTECTED\n"+
+		"pinned: true\n"+
+		"tags: [travel/japan]\n"+
+		"attachments:\n"+
+		"    - attachments/a1/map.png\n"+
+		"---\n"+
+		"# Kyoto\n\n---\n\nBook the hotel #travel/japan\n", files["memos/trip.md"])
+	assert.Equal(t, "\x89PNG map", files["attachments/a1/map.png"])
+
+	var written memoexport.Manifest
+	require.NoError(t, json.Unmarshal([]byte(files["manifest.json"]), &written))
+	assert.Equal(t, *manifest, written)
+}
+
+func TestExportSince(t *testing.T) {
+	store := newFakeStore()
+	var buf bytes.Buffer
+	manifest, err := memoexport.New(store).Export(context.Background(), &buf, &memoexport.Options{Since: store.memos[1].UpdatedTs})
+	require.NoError(t, err)
+	assert.Equal(t, 1, manifest.Memos)
+	assert.Zero(t, manifest.Attachments)
+
+	_, err = memoexport.New(store).Export(context.Background(), &buf, &memoexport.Options{Since: -1})
+	assert.ErrorIs(t, err, memoexport.ErrInvalid)
+}
+
+func TestExportImport(t *testing.T) {
+	exported := newFakeStore()
+	var buf bytes.Buffer
+	_, err := memoexport.New(exported).Export(context.Background(), &buf, &memoexport.Options{UserID: 1})
+	require.NoError(t, err)
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	imported := &importStore{}
+	progress, err := memoimport.New(imported).Import(context.Background(), archive, &memoimport.Options{
+		Format: memoimport.FormatDivineSense,
+		UserID: 2,
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, progress.Imported, "the manifest and attachments are not notes")
+	require.Len(t, imported.memos, 2)
+
+	for i, memo := range imported.memos {
+		// Notes are imported in the order of their paths
+		original := exported.memos[1-i]
+		assert.Equal(t, original.Content, memo.Content)
+		assert.Equal(t, original.Visibility, memo.Visibility)
+		assert.Equal(t, original.Pinned, memo.Pinned)
+		assert.Equal(t, original.Archived, memo.Archived)
+		assert.Equal(t, original.CreatedTs, memo.CreatedTs)
+		assert.Equal(t, original.UpdatedTs, memo.UpdatedTs)
+	}
+	trip := imported.memos[1]
+	require.Len(t, trip.Attachments, 1)
+	assert.Equal(t, "map.png", trip.Attachments[0].Filename)
+	assert.Equal(t, "image/png", trip.Attachments[0].Type)
+	assert.Equal(t, []byte("\x89PNG map"), trip.Attachments[0].Blob)
+}
@@ -0,0 +1,97 @@
+package memoexport
+
+import (
+	"context"
+	"fmt"
+
+	storepb "github.com/hrygo/divinesense/proto/gen/store"
+	"github.com/hrygo/divinesense/store"
+)
+
+// MemoStore is the Store of the memos of the server.
+type MemoStore struct {
+	store *store.Store
+	// readBlob reads the content of the attachments stored by the server.
+	readBlob func(attachment *store.Attachment) ([]byte, error)
+}
+
+// NewMemoStore creates a Store over the memos of the server; readBlob reads
+// the content of the attachments stored on disk or in the database.
+func NewMemoStore(st *store.Store, readBlob func(attachment *store.Attachment) ([]byte, error)) *MemoStore {
+	return &MemoStore{store: st, readBlob: readBlob}
+}
+
+func (s *MemoStore) ListMemos(ctx context.Context, userID int32, since int64, offset, limit int) ([]*Memo, error) {
+	find := &store.FindMemo{
+		CreatorID:        &userID,
+		ExcludeComments:  true,
+		OrderByUpdatedTs: true,
+		OrderByTimeAsc:   true,
+		Offset:           &offset,
+		Limit:            &limit,
+	}
+	if since > 0 {
+		find.Filters = []string{fmt.Sprintf("updated_ts >= %d", since)}
+	}
+	list, err := s.store.ListMemos(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	memos := make([]*Memo, 0, len(list))
+	byID := make(map[int32]*Memo, len(list))
+	ids := make([]int32, 0, len(list))
+	for _, memo := range list {
+		exported := &Memo{
+			UID:        memo.UID,
+			Content:    memo.Content,
+			Visibility: memo.Visibility.String(),
+			Pinned:     memo.Pinned,
+			Archived:   memo.RowStatus == store.Archived,
+			CreatedTs:  memo.CreatedTs,
+			UpdatedTs:  memo.UpdatedTs,
+		}
+		if memo.Payload != nil {
+			exported.Tags = memo.Payload.Tags
+		}
+		memos = append(memos, exported)
+		byID[memo.ID] = exported
+		ids = append(ids, memo.ID)
+	}
+
+	attachments, err := s.store.ListAttachments(ctx, &store.FindAttachment{MemoIDList: ids})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	for _, attachment := range attachments {
+		if attachment.MemoID == nil || byID[*attachment.MemoID] == nil {
+			continue
+		}
+		memo := byID[*attachment.MemoID]
+		memo.Attachments = append(memo.Attachments, &Attachment{
+			UID:      attachment.UID,
+			Filename: attachment.Filename,
+			Type:     attachment.Type,
+			Size:     attachment.Size,
+		})
+	}
+	return memos, nil
+}
+
+func (s *MemoStore) ReadAttachment(ctx context.Context, attachment *Attachment) ([]byte, error) {
+	found, err := s.store.GetAttachment(ctx, &store.FindAttachment{UID: &attachment.UID, GetBlob: true})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, fmt.Errorf("attachment %s not found", attachment.UID)
+	}
+	if found.StorageType == storepb.AttachmentStorageType_S3 || found.StorageType == storepb.AttachmentStorageType_EXTERNAL {
+		// Only the links of these attachments are stored
+		return nil, fmt.Errorf("attachment %s is not stored by the server", attachment.UID)
+	}
+	return s.readBlob(found)
+}
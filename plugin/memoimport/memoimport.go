@@ -0,0 +1,343 @@
+// Package memoimport imports folders of Markdown notes as memos: Obsidian
+// vaults, Logseq graphs, plain Markdown folders and the archives of the
+// memoexport package.
+//
+// Each Markdown file becomes a private memo of the importing user, which
+// keeps the frontmatter, the tags and the times of the note (see Convert).
+// Notes whose content a memo of the user already has, from an earlier import
+// or within the folder, are skipped as duplicates, so importing a folder
+// again only adds the new notes. Only the attachments of exported memos are
+// imported.
+//
+// The importer reports its progress as events, for the command line and the
+// API to stream.
+package memoimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrInvalid is returned for invalid imports.
+	ErrInvalid = errors.New("invalid import")
+	// ErrSkipped is returned for files that are not notes to import.
+	ErrSkipped = errors.New("not imported")
+)
+
+// Format is the kind of folder imported.
+type Format string
+
+const (
+	// FormatMarkdown is a folder of Markdown files with optional YAML
+	// frontmatter.
+	FormatMarkdown Format = "markdown"
+	// FormatObsidian is an Obsidian vault; its notes are Markdown files.
+	FormatObsidian Format = "obsidian"
+	// FormatLogseq is a Logseq graph, whose pages have properties instead of
+	// frontmatter and whose journals are named after their day.
+	FormatLogseq Format = "logseq"
+	// FormatDivineSense is an archive of exported memos, whose frontmatter
+	// has the metadata of the memos and whose attachments are in the
+	// attachments folder.
+	FormatDivineSense Format = "divinesense"
+)
+
+// Valid reports whether f is a known format.
+func (f Format) Valid() bool {
+	return f == FormatMarkdown || f == FormatObsidian || f == FormatLogseq || f == FormatDivineSense
+}
+
+const (
+	// MaxNotes bounds the notes of an import.
+	MaxNotes = 20000
+	// maxNoteBytes bounds the size of a note.
+	maxNoteBytes = 1 << 20
+	// maxAttachmentBytes bounds the size of an attachment.
+	maxAttachmentBytes = 32 << 20
+	// attachmentDir is the folder of the attachments of exported memos.
+	attachmentDir = "attachments"
+)
+
+// EventType is the type of a progress event.
+type EventType string
+
+const (
+	// EventStarted is sent once the folder is scanned, with the total.
+	EventStarted   EventType = "started"
+	EventImported  EventType = "imported"
+	EventDuplicate EventType = "duplicate"
+	EventSkipped   EventType = "skipped"
+	EventFailed    EventType = "failed"
+	// EventFinished is sent last, unless the import is canceled.
+	EventFinished EventType = "finished"
+)
+
+// Progress counts the notes of an import.
+type Progress struct {
+	Total      int `json:"total"`
+	Processed  int `json:"processed"`
+	Imported   int `json:"imported"`
+	Duplicates int `json:"duplicates"`
+	Skipped    int `json:"skipped"`
+	Failed     int `json:"failed"`
+}
+
+// Event reports the progress of an import.
+type Event struct {
+	Type EventType `json:"type"`
+	// Path is the note of the event, if any.
+	Path string `json:"path,omitempty"`
+	// MemoUID is the memo created, or the one a duplicate duplicates; empty
+	// for dry runs.
+	MemoUID  string   `json:"memo_uid,omitempty"`
+	Error    string   `json:"error,omitempty"`
+	Progress Progress `json:"progress"`
+}
+
+// Memo is a memo to create.
+type Memo struct {
+	CreatorID  int32
+	Content    string
+	Visibility string
+	Pinned     bool
+	Archived   bool
+	// CreatedTs and UpdatedTs are in seconds; 0 for now.
+	CreatedTs   int64
+	UpdatedTs   int64
+	Attachments []*Attachment
+}
+
+// Attachment is an attachment of a memo to create.
+type Attachment struct {
+	Filename string
+	Type     string
+	Blob     []byte
+}
+
+// Store creates the memos of the imports.
+type Store interface {
+	// ContentHashes returns the UIDs of the memos of a user by ContentHash
+	// of their content.
+	ContentHashes(ctx context.Context, userID int32) (map[string]string, error)
+	// CreateMemo creates a memo and returns its UID.
+	CreateMemo(ctx context.Context, memo *Memo) (string, error)
+}
+
+// Options are the options of an import.
+type Options struct {
+	Format Format
+	UserID int32
+	// Visibility of the memos; defaults to PRIVATE. Exported memos keep
+	// their own.
+	Visibility string
+	// DryRun converts the notes without creating memos.
+	DryRun bool
+}
+
+// Importer imports folders of notes.
+type Importer struct {
+	store Store
+}
+
+// New creates an importer.
+func New(store Store) *Importer {
+	return &Importer{store: store}
+}
+
+// Import imports the Markdown files of a folder, sending the progress to
+// progress, which may be nil. Hidden files and folders, such as .obsidian
+// and .trash, the logseq folder of Logseq graphs and the attachments folder
+// of exported memos are not imported as notes. Notes
+// that fail are reported and the import goes on; it only stops when ctx is
+// canceled or the memos of the user cannot be listed.
+func (i *Importer) Import(ctx context.Context, fsys fs.FS, opts *Options, progress func(*Event)) (*Progress, error) {
+	if !opts.Format.Valid() {
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalid, opts.Format)
+	}
+	if opts.Visibility == "" {
+		opts.Visibility = "PRIVATE"
+	}
+	switch opts.Visibility {
+	case "PRIVATE", "PROTECTED", "PUBLIC":
+	default:
+		return nil, fmt.Errorf("%w: visibility must be PRIVATE, PROTECTED or PUBLIC", ErrInvalid)
+	}
+	if progress == nil {
+		progress = func(*Event) {}
+	}
+
+	paths, err := Scan(fsys, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+	hashes, err := i.store.ContentHashes(ctx, opts.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list memos: %w", err)
+	}
+
+	p := &Progress{Total: len(paths)}
+	progress(&Event{Type: EventStarted, Progress: *p})
+	for _, notePath := range paths {
+		if err := ctx.Err(); err != nil {
+			return p, err
+		}
+		event := i.importNote(ctx, fsys, notePath, opts, hashes)
+		p.Processed++
+		switch event.Type {
+		case EventImported:
+			p.Imported++
+		case EventDuplicate:
+			p.Duplicates++
+		case EventSkipped:
+			p.Skipped++
+		case EventFailed:
+			p.Failed++
+		}
+		event.Progress = *p
+		progress(event)
+	}
+	progress(&Event{Type: EventFinished, Progress: *p})
+	return p, nil
+}
+
+// importNote imports a note, returning its event. hashes gets the hash of
+// the memo created.
+func (i *Importer) importNote(ctx context.Context, fsys fs.FS, notePath string, opts *Options, hashes map[string]string) *Event {
+	event := &Event{Path: notePath}
+	note, err := readNote(fsys, notePath, opts.Format)
+	if err != nil {
+		event.Type, event.Error = EventSkipped, err.Error()
+		if !errors.Is(err, ErrSkipped) {
+			event.Type = EventFailed
+		}
+		return event
+	}
+	if uid, ok := hashes[note.Hash]; ok {
+		event.Type, event.MemoUID = EventDuplicate, uid
+		return event
+	}
+	memo := &Memo{
+		CreatorID:  opts.UserID,
+		Content:    note.Content,
+		Visibility: opts.Visibility,
+		Pinned:     note.Pinned,
+		Archived:   note.Archived,
+		CreatedTs:  note.CreatedTs,
+		UpdatedTs:  note.UpdatedTs,
+	}
+	if note.Visibility != "" {
+		memo.Visibility = note.Visibility
+	}
+	// A memo without its attachments fails, so that importing again retries
+	// it rather than skipping it as a duplicate
+	for _, attachmentPath := range note.Attachments {
+		attachment, err := readAttachment(fsys, attachmentPath)
+		if err != nil {
+			event.Type, event.Error = EventFailed, err.Error()
+			return event
+		}
+		memo.Attachments = append(memo.Attachments, attachment)
+	}
+	event.Type = EventImported
+	if !opts.DryRun {
+		event.MemoUID, err = i.store.CreateMemo(ctx, memo)
+		if err != nil {
+			event.Type, event.Error = EventFailed, err.Error()
+			return event
+		}
+	}
+	hashes[note.Hash] = event.MemoUID
+	return event
+}
+
+// readNote reads and converts a note of a folder.
+func readNote(fsys fs.FS, notePath string, format Format) (*Note, error) {
+	file, err := fsys.Open(notePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	// The size of archived files is not trusted
+	data, err := io.ReadAll(io.LimitReader(file, maxNoteBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxNoteBytes {
+		return nil, fmt.Errorf("%w: larger than %d bytes", ErrSkipped, maxNoteBytes)
+	}
+	return Convert(format, notePath, data, info.ModTime())
+}
+
+// readAttachment reads an attachment of an exported memo.
+func readAttachment(fsys fs.FS, attachmentPath string) (*Attachment, error) {
+	if !fs.ValidPath(attachmentPath) || !strings.HasPrefix(attachmentPath, attachmentDir+"/") {
+		return nil, fmt.Errorf("invalid attachment path %q", attachmentPath)
+	}
+	file, err := fsys.Open(attachmentPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	defer file.Close()
+	blob, err := io.ReadAll(io.LimitReader(file, maxAttachmentBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	if len(blob) > maxAttachmentBytes {
+		return nil, fmt.Errorf("attachment %s is larger than %d bytes", attachmentPath, maxAttachmentBytes)
+	}
+	filename := path.Base(attachmentPath)
+	contentType := mime.TypeByExtension(path.Ext(filename))
+	if contentType == "" {
+		contentType = http.DetectContentType(blob)
+	}
+	return &Attachment{Filename: filename, Type: contentType, Blob: blob}, nil
+}
+
+// Scan returns the paths of the notes of a folder, sorted.
+func Scan(fsys fs.FS, format Format) ([]string, error) {
+	var paths []string
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if p != "." && strings.HasPrefix(name, ".") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if format == FormatLogseq && p == "logseq" || format == FormatDivineSense && p == attachmentDir {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !strings.EqualFold(path.Ext(name), ".md") {
+			return nil
+		}
+		if len(paths) == MaxNotes {
+			return fmt.Errorf("%w: more than %d notes", ErrInvalid, MaxNotes)
+		}
+		paths = append(paths, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(paths)
+	return paths, nil
+}
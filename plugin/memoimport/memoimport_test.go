@@ -0,0 +1,143 @@
+package memoimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	memos  []*Memo
+	hashes map[string]string
+}
+
+func (s *fakeStore) ContentHashes(context.Context, int32) (map[string]string, error) {
+	hashes := map[string]string{}
+	for hash, uid := range s.hashes {
+		hashes[hash] = uid
+	}
+	return hashes, nil
+}
+
+func (s *fakeStore) CreateMemo(_ context.Context, memo *Memo) (string, error) {
+	if strings.Contains(memo.Content, "fail") {
+		return "", errors.New("database is down")
+	}
+	s.memos = append(s.memos, memo)
+	return fmt.Sprintf("m%d", len(s.memos)), nil
+}
+
+func TestConvertObsidian(t *testing.T) {
+	modTime := time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local)
+	note, err := Convert(FormatObsidian, "Projects/Trip plan.md", []byte("---\r\n"+
+		"tags: [travel, japan trip]\r\n"+
+		"created: 2024-05-01\r\n"+
+		"---\r\n"+
+		"Book the [[Kyoto]] hotel #todo\r\n"), modTime)
+	require.NoError(t, err)
+	assert.Equal(t, "# Trip plan\n\n"+
+		"```yaml\ntags: [travel, japan trip]\ncreated: 2024-05-01\n```\n\n"+
+		"Book the [[Kyoto]] hotel #todo\n\n"+
+		"#travel #japan-trip", note.Content)
+	assert.Equal(t, []string{"travel", "japan-trip"}, note.Tags)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC).Unix(), note.CreatedTs)
+	assert.Equal(t, modTime.Unix(), note.UpdatedTs)
+	assert.Equal(t, ContentHash(note.Content+"\n"), note.Hash)
+
+	note, err = Convert(FormatMarkdown, "readme.md", []byte("# Already titled\n\nBody"), time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "# Already titled\n\nBody", note.Content)
+
+	_, err = Convert(FormatMarkdown, "empty.md", []byte(" \n"), time.Time{})
+	assert.ErrorIs(t, err, ErrSkipped)
+	_, err = Convert(FormatMarkdown, "binary.md", []byte{0xff, 0xfe}, time.Time{})
+	assert.ErrorIs(t, err, ErrSkipped)
+}
+
+func TestConvertLogseq(t *testing.T) {
+	note, err := Convert(FormatLogseq, "pages/ml___papers%3F.md", []byte("tags:: research, [[deep learning]]\n"+
+		"- Read #[[attention is all you need]]\n"), time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "# ml/papers?\n\n"+
+		"tags:: research, [[deep learning]]\n- Read #attention-is-all-you-need\n\n"+
+		"#research #deep-learning", note.Content)
+
+	note, err = Convert(FormatLogseq, "journals/2024_01_15.md", []byte("- Ran 5k"), time.Time{})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(note.Content, "# 2024-01-15\n\n"))
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local).Unix(), note.CreatedTs)
+}
+
+func TestImport(t *testing.T) {
+	existing := "# Old\n\nAlready imported"
+	store := &fakeStore{hashes: map[string]string{ContentHash(existing): "old1"}}
+	vault := fstest.MapFS{
+		"Old.md":                 {Data: []byte("Already imported")},
+		"a/Note.md":              {Data: []byte("Hello #world")},
+		"b/Note.md":              {Data: []byte("Hello #world")},
+		"c/Note.md":              {Data: []byte("Hello #world")},
+		"Broken.md":              {Data: []byte("this will fail")},
+		"Empty.md":               {Data: []byte("")},
+		"image.png":              {Data: []byte("png")},
+		".obsidian/workspace.md": {Data: []byte("settings")},
+		".trash/Deleted.md":      {Data: []byte("gone")},
+	}
+	var events []*Event
+	progress, err := New(store).Import(context.Background(), vault, &Options{Format: FormatObsidian, UserID: 1}, func(e *Event) {
+		events = append(events, e)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &Progress{Total: 6, Processed: 6, Imported: 1, Duplicates: 3, Skipped: 1, Failed: 1}, progress)
+
+	require.Len(t, store.memos, 1)
+	assert.Equal(t, "PRIVATE", store.memos[0].Visibility)
+	assert.Equal(t, "# Note\n\nHello #world", store.memos[0].Content)
+
+	require.Len(t, events, 8)
+	assert.Equal(t, EventStarted, events[0].Type)
+	assert.Equal(t, EventFinished, events[7].Type)
+	byPath := map[string]*Event{}
+	for _, e := range events[1:7] {
+		byPath[e.Path] = e
+	}
+	assert.Equal(t, EventFailed, byPath["Broken.md"].Type)
+	assert.Equal(t, EventSkipped, byPath["Empty.md"].Type)
+	assert.Equal(t, "old1", byPath["Old.md"].MemoUID, "duplicates of earlier imports")
+	assert.Equal(t, EventImported, byPath["a/Note.md"].Type)
+	assert.Equal(t, &Event{Type: EventDuplicate, Path: "b/Note.md", MemoUID: "m1", Progress: byPath["b/Note.md"].Progress},
+		byPath["b/Note.md"], "duplicates within the folder")
+
+	// Importing again adds nothing
+	store.hashes[ContentHash(store.memos[0].Content)] = "m1"
+	progress, err = New(store).Import(context.Background(), vault, &Options{Format: FormatObsidian, UserID: 1}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 4, progress.Duplicates)
+	assert.Len(t, store.memos, 1)
+}
+
+func TestImportOptions(t *testing.T) {
+	store := &fakeStore{}
+	vault := fstest.MapFS{"logseq/config.md": {Data: []byte("config")}, "pages/a.md": {Data: []byte("- a")}}
+
+	progress, err := New(store).Import(context.Background(), vault, &Options{Format: FormatLogseq, DryRun: true}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, progress.Imported, "the logseq folder is not imported")
+	assert.Empty(t, store.memos)
+
+	_, err = New(store).Import(context.Background(), vault, &Options{Format: "notion"}, nil)
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = New(store).Import(context.Background(), vault, &Options{Format: FormatLogseq, Visibility: "SECRET"}, nil)
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = New(store).Import(ctx, vault, &Options{Format: FormatLogseq}, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
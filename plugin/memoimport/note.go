@@ -0,0 +1,316 @@
+package memoimport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Note is a note of a folder, converted to the content of a memo.
+type Note struct {
+	// Path is the slash-separated path of the note in the folder.
+	Path    string
+	Content string
+	// Tags are the tags of the frontmatter or page properties, which the
+	// content ends with.
+	Tags []string
+	// CreatedTs and UpdatedTs are in seconds; 0 when unknown.
+	CreatedTs int64
+	UpdatedTs int64
+	// Hash is the content hash that duplicates share.
+	Hash string
+	// Visibility, Pinned and Archived are those of exported memos;
+	// Visibility is empty for other notes.
+	Visibility string
+	Pinned     bool
+	Archived   bool
+	// Attachments are the paths of the attachments of exported memos.
+	Attachments []string
+}
+
+// ContentHash returns the hash of the content of a memo, which notes are
+// de-duplicated by: the SHA-256 of the trimmed content with Unix newlines.
+func ContentHash(content string) string {
+	content = strings.TrimSpace(strings.ReplaceAll(content, "\r\n", "\n"))
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+var (
+	// logseqProperty matches the page properties of Logseq, "tags:: a, b",
+	// which lead the first block of a page.
+	logseqProperty = regexp.MustCompile(`^(?:- )?([A-Za-z][\w-]*):: ?(.*)$`)
+	// logseqTag matches the multi-word tags of Logseq, #[[machine learning]].
+	logseqTag = regexp.MustCompile(`#\[\[([^\]\n]+)\]\]`)
+	// logseqJournal matches the file names of Logseq journals.
+	logseqJournal = regexp.MustCompile(`^(\d{4})_(\d{2})_(\d{2})$`)
+	// pageRef matches the page references of property values, [[page]].
+	pageRef = regexp.MustCompile(`\[\[([^\]\n]+)\]\]`)
+)
+
+// Frontmatter keys of the creation and update times, by precedence.
+var (
+	createdKeys = []string{"created", "created_at", "date", "creation-date"}
+	updatedKeys = []string{"updated", "updated_at", "modified", "last-modified"}
+)
+
+// Convert converts a Markdown file of a folder into a note. modTime, the
+// modification time of the file, stands in for missing times.
+//
+// The note keeps the frontmatter as a YAML code block, and starts with the
+// title of the file as a heading unless it has one; the tags of the
+// frontmatter, or of the page properties for Logseq, are added at its end.
+// The multi-word tags of Logseq become hyphenated tags.
+//
+// Exported memos keep their content as is: their frontmatter only has their
+// metadata.
+func Convert(format Format, filePath string, data []byte, modTime time.Time) (*Note, error) {
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("%w: not UTF-8 text", ErrSkipped)
+	}
+	text := strings.ReplaceAll(string(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))), "\r\n", "\n")
+	title := strings.TrimSuffix(path.Base(filePath), path.Ext(filePath))
+	note := &Note{Path: filePath}
+	if !modTime.IsZero() {
+		note.CreatedTs, note.UpdatedTs = modTime.Unix(), modTime.Unix()
+	}
+
+	if format == FormatDivineSense {
+		return convertExported(note, text)
+	}
+
+	var frontmatter string
+	var properties map[string]any
+	switch format {
+	case FormatLogseq:
+		title = logseqTitle(title)
+		properties = logseqProperties(text)
+		text = logseqTag.ReplaceAllStringFunc(text, func(tag string) string {
+			return "#" + sanitizeTag(logseqTag.FindStringSubmatch(tag)[1])
+		})
+		if day := logseqJournal.FindStringSubmatch(title); day != nil {
+			title = day[1] + "-" + day[2] + "-" + day[3]
+			if t, err := time.ParseInLocation(time.DateOnly, title, time.Local); err == nil {
+				note.CreatedTs = t.Unix()
+			}
+		}
+	default:
+		var err error
+		frontmatter, text, err = splitFrontmatter(text)
+		if err != nil {
+			return nil, err
+		}
+		if frontmatter != "" {
+			properties = map[string]any{}
+			if err := yaml.Unmarshal([]byte(frontmatter), &properties); err != nil {
+				// Kept as is, without its tags and times
+				properties = nil
+			}
+		}
+	}
+	if t := propertyTime(properties, createdKeys); !t.IsZero() {
+		note.CreatedTs = t.Unix()
+	}
+	if t := propertyTime(properties, updatedKeys); !t.IsZero() {
+		note.UpdatedTs = t.Unix()
+	}
+	if note.UpdatedTs < note.CreatedTs {
+		note.UpdatedTs = note.CreatedTs
+	}
+	if t, ok := properties["title"].(string); ok && strings.TrimSpace(t) != "" {
+		title = strings.TrimSpace(t)
+	}
+	note.Tags = propertyTags(properties)
+
+	body := strings.TrimSpace(text)
+	if body == "" && frontmatter == "" {
+		return nil, fmt.Errorf("%w: empty note", ErrSkipped)
+	}
+	var parts []string
+	if !strings.HasPrefix(body, "# ") && title != "" {
+		parts = append(parts, "# "+title)
+	}
+	if frontmatter != "" {
+		parts = append(parts, "```yaml\n"+strings.TrimRight(frontmatter, "\n")+"\n```")
+	}
+	if body != "" {
+		parts = append(parts, body)
+	}
+	if len(note.Tags) > 0 {
+		tags := make([]string, 0, len(note.Tags))
+		for _, tag := range note.Tags {
+			tags = append(tags, "#"+tag)
+		}
+		parts = append(parts, strings.Join(tags, " "))
+	}
+	note.Content = strings.Join(parts, "\n\n")
+	note.Hash = ContentHash(note.Content)
+	return note, nil
+}
+
+// convertExported converts a memo of an export archive.
+func convertExported(note *Note, text string) (*Note, error) {
+	frontmatter, body, err := splitFrontmatter(text)
+	if err != nil {
+		return nil, err
+	}
+	var meta struct {
+		Visibility  string    `yaml:"visibility"`
+		Pinned      bool      `yaml:"pinned"`
+		Archived    bool      `yaml:"archived"`
+		Tags        []string  `yaml:"tags"`
+		Created     time.Time `yaml:"created"`
+		Updated     time.Time `yaml:"updated"`
+		Attachments []string  `yaml:"attachments"`
+	}
+	if frontmatter == "" {
+		return nil, fmt.Errorf("%w: not an exported memo", ErrSkipped)
+	}
+	if err := yaml.Unmarshal([]byte(frontmatter), &meta); err != nil {
+		return nil, fmt.Errorf("invalid frontmatter: %w", err)
+	}
+	switch meta.Visibility {
+	case "PRIVATE", "PROTECTED", "PUBLIC":
+		note.Visibility = meta.Visibility
+	}
+	note.Pinned, note.Archived, note.Tags, note.Attachments = meta.Pinned, meta.Archived, meta.Tags, meta.Attachments
+	if !meta.Created.IsZero() {
+		note.CreatedTs = meta.Created.Unix()
+	}
+	if !meta.Updated.IsZero() {
+		note.UpdatedTs = meta.Updated.Unix()
+	}
+	if note.UpdatedTs < note.CreatedTs {
+		note.UpdatedTs = note.CreatedTs
+	}
+	// The export ends the content with a newline
+	note.Content = strings.TrimSuffix(strings.TrimPrefix(body, "\n"), "\n")
+	if strings.TrimSpace(note.Content) == "" && len(note.Attachments) == 0 {
+		return nil, fmt.Errorf("%w: empty note", ErrSkipped)
+	}
+	note.Hash = ContentHash(note.Content)
+	return note, nil
+}
+
+// splitFrontmatter splits the YAML frontmatter, between "---" lines, from
+// the body of a note.
+func splitFrontmatter(text string) (frontmatter, body string, err error) {
+	rest, ok := strings.CutPrefix(text, "---\n")
+	if !ok {
+		return "", text, nil
+	}
+	if strings.HasPrefix(rest, "---\n") || rest == "---" {
+		return "", strings.TrimPrefix(rest, "---"), nil
+	}
+	end := strings.Index(rest, "\n---\n")
+	if end < 0 {
+		if !strings.HasSuffix(rest, "\n---") {
+			return "", text, nil
+		}
+		end = len(rest) - len("\n---")
+	}
+	frontmatter = rest[:end+1]
+	body = strings.TrimPrefix(rest[end+1:], "---")
+	return frontmatter, body, nil
+}
+
+// logseqTitle decodes the title of a Logseq page from its file name:
+// "a___b" is the page a/b and reserved characters are percent-encoded.
+func logseqTitle(name string) string {
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	return strings.ReplaceAll(name, "___", "/")
+}
+
+// logseqProperties returns the page properties of a Logseq page, which are
+// its leading "key:: value" lines.
+func logseqProperties(text string) map[string]any {
+	properties := map[string]any{}
+	for line := range strings.SplitSeq(text, "\n") {
+		match := logseqProperty.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			break
+		}
+		properties[strings.ToLower(match[1])] = strings.TrimSpace(match[2])
+	}
+	return properties
+}
+
+// propertyTime returns the first time of keys in properties: dates or times
+// of the YAML frontmatter, or strings of Logseq properties.
+func propertyTime(properties map[string]any, keys []string) time.Time {
+	for _, key := range keys {
+		switch v := properties[key].(type) {
+		case time.Time:
+			return v
+		case string:
+			for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", time.DateOnly} {
+				if t, err := time.ParseInLocation(layout, strings.TrimSpace(v), time.Local); err == nil {
+					return t
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// propertyTags returns the tags of the "tags" or "tag" property: a list, or
+// a string of tags separated by commas or spaces. Page references and leading
+// "#" are stripped.
+func propertyTags(properties map[string]any) []string {
+	var values []string
+	for _, key := range []string{"tags", "tag"} {
+		switch v := properties[key].(type) {
+		case []any:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					values = append(values, s)
+				}
+			}
+		case string:
+			v = pageRef.ReplaceAllStringFunc(v, func(ref string) string {
+				return sanitizeTag(pageRef.FindStringSubmatch(ref)[1])
+			})
+			values = append(values, strings.FieldsFunc(v, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })...)
+		}
+	}
+	var tags []string
+	for _, value := range values {
+		if tag := sanitizeTag(value); tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// maxTagRunes is the longest tag memos recognize.
+const maxTagRunes = 100
+
+// sanitizeTag turns a tag name into a memo tag: runes tags cannot hold
+// become hyphens.
+func sanitizeTag(name string) string {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "#")
+	tag := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsSymbol(r) || r == '_' || r == '-' || r == '/' {
+			return r
+		}
+		return '-'
+	}, name)
+	tag = strings.Trim(tag, "-/")
+	if utf8.RuneCountInString(tag) > maxTagRunes {
+		tag = string([]rune(tag)[:maxTagRunes])
+	}
+	return tag
+}
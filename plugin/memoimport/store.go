@@ -0,0 +1,89 @@
+package memoimport
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lithammer/shortuuid/v4"
+
+	"github.com/hrygo/divinesense/plugin/markdown"
+	storepb "github.com/hrygo/divinesense/proto/gen/store"
+	"github.com/hrygo/divinesense/store"
+)
+
+// MemoStore is the Store of the memos of the server. Imported memos are
+// created without the webhooks and AI enrichment of the memos users write;
+// the embedding runner indexes them as it indexes any new memo.
+type MemoStore struct {
+	store    *store.Store
+	markdown markdown.Service
+	// saveBlob stores the content of an attachment before it is created.
+	saveBlob func(ctx context.Context, attachment *store.Attachment) error
+}
+
+// NewMemoStore creates a Store over the memos of the server; the markdown
+// service extracts the tags of the memos and saveBlob stores the content of
+// their attachments.
+func NewMemoStore(st *store.Store, markdownService markdown.Service, saveBlob func(ctx context.Context, attachment *store.Attachment) error) *MemoStore {
+	return &MemoStore{store: st, markdown: markdownService, saveBlob: saveBlob}
+}
+
+func (s *MemoStore) ContentHashes(ctx context.Context, userID int32) (map[string]string, error) {
+	memos, err := s.store.ListMemos(ctx, &store.FindMemo{CreatorID: &userID})
+	if err != nil {
+		return nil, err
+	}
+	hashes := make(map[string]string, len(memos))
+	for _, memo := range memos {
+		hashes[ContentHash(memo.Content)] = memo.UID
+	}
+	return hashes, nil
+}
+
+func (s *MemoStore) CreateMemo(ctx context.Context, memo *Memo) (string, error) {
+	data, err := s.markdown.ExtractAll([]byte(memo.Content))
+	if err != nil {
+		return "", fmt.Errorf("failed to extract markdown metadata: %w", err)
+	}
+	created, err := s.store.CreateMemo(ctx, &store.Memo{
+		UID:        shortuuid.New(),
+		CreatorID:  memo.CreatorID,
+		Content:    memo.Content,
+		Visibility: store.Visibility(memo.Visibility),
+		CreatedTs:  memo.CreatedTs,
+		UpdatedTs:  memo.UpdatedTs,
+		Payload:    &storepb.MemoPayload{Tags: data.Tags, Property: data.Property},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create memo: %w", err)
+	}
+	for _, attachment := range memo.Attachments {
+		create := &store.Attachment{
+			UID:       shortuuid.New(),
+			CreatorID: memo.CreatorID,
+			Filename:  attachment.Filename,
+			Type:      attachment.Type,
+			Size:      int64(len(attachment.Blob)),
+			Blob:      attachment.Blob,
+			MemoID:    &created.ID,
+		}
+		if err := s.saveBlob(ctx, create); err != nil {
+			return created.UID, fmt.Errorf("failed to save attachment %s: %w", attachment.Filename, err)
+		}
+		if _, err := s.store.CreateAttachment(ctx, create); err != nil {
+			return created.UID, fmt.Errorf("failed to create attachment %s: %w", attachment.Filename, err)
+		}
+	}
+	// Memos are created unpinned and not archived
+	if memo.Pinned || memo.Archived {
+		update := &store.UpdateMemo{ID: created.ID, Pinned: &memo.Pinned}
+		if memo.Archived {
+			archived := store.Archived
+			update.RowStatus = &archived
+		}
+		if err := s.store.UpdateMemo(ctx, update); err != nil {
+			return created.UID, fmt.Errorf("failed to update memo: %w", err)
+		}
+	}
+	return created.UID, nil
+}
@@ -0,0 +1,56 @@
+// Package netguard keeps the connections the server opens on behalf of users
+// on public addresses, so that users cannot reach the private network of the
+// server through the URLs and hosts they configure (SSRF).
+//
+// Addresses are checked once resolved, when dialing, so that names resolving
+// to the private network are refused too.
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrDenied is returned when dialing a non-public address, for callers
+// without an error of their own.
+var ErrDenied = errors.New("address not allowed")
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598).
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPublic reports whether ip is a public unicast address.
+func IsPublic(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsUnspecified() &&
+		!ip.IsMulticast() && !ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// Control is the net.Dialer Control function that refuses non-public
+// addresses, with an error wrapping denied.
+func Control(denied error) func(network, address string, c syscall.RawConn) error {
+	return func(_, address string, _ syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		if ip := net.ParseIP(host); ip == nil || !IsPublic(ip) {
+			return fmt.Errorf("%w: %s is not a public address", denied, host)
+		}
+		return nil
+	}
+}
+
+// Transport returns a transport that dials public addresses only, with an
+// error wrapping denied otherwise. Proxies are not used, as they would
+// connect on behalf of the server.
+func Transport(timeout time.Duration, denied error) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	dialer := &net.Dialer{Timeout: timeout, Control: Control(denied)}
+	transport.DialContext = dialer.DialContext
+	return transport
+}
@@ -0,0 +1,140 @@
+package ocr
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sashabaranov/go-openai"
+)
+
+// Engine extracts text from images. Implemented by Client (Tesseract) and
+// VisionClient (provider API).
+type Engine interface {
+	ExtractText(ctx context.Context, image []byte, mimeType string) (string, error)
+	IsSupported(mimeType string) bool
+}
+
+// Provider names accepted by NewEngine.
+const (
+	ProviderTesseract = "tesseract"
+	ProviderAPI       = "api"
+)
+
+// visionNoText is what the vision model answers for images without text.
+const visionNoText = "[NO_TEXT]"
+
+const visionPrompt = `识别图片中的全部文字，按原有阅读顺序逐行输出，保留换行。
+只输出识别到的文字本身，不要解释、翻译或补充。
+如果图片中没有任何文字，只输出 ` + visionNoText
+
+// VisionConfig holds the configuration of an OpenAI-compatible vision API.
+type VisionConfig struct {
+	// BaseURL is the API base URL (e.g., https://api.siliconflow.cn/v1)
+	BaseURL string
+	// APIKey is the API key
+	APIKey string
+	// Model is a vision-capable chat model (e.g., Qwen/Qwen2.5-VL-72B-Instruct)
+	Model string
+	// Timeout bounds one recognition request
+	Timeout time.Duration
+}
+
+// VisionClient provides OCR through a vision model behind an OpenAI-compatible
+// chat completions API.
+type VisionClient struct {
+	client  *openai.Client
+	model   string
+	timeout time.Duration
+}
+
+// NewVisionClient creates a new vision OCR client.
+func NewVisionClient(config *VisionConfig) *VisionClient {
+	clientConfig := openai.DefaultConfig(config.APIKey)
+	if config.BaseURL != "" {
+		clientConfig.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	return &VisionClient{
+		client:  openai.NewClientWithConfig(clientConfig),
+		model:   config.Model,
+		timeout: timeout,
+	}
+}
+
+// ExtractText extracts text from an image with the vision model.
+// It returns an empty string when the image contains no text.
+func (c *VisionClient) ExtractText(ctx context.Context, image []byte, mimeType string) (string, error) {
+	if !c.IsSupported(mimeType) {
+		return "", errors.Errorf("unsupported MIME type: %s", mimeType)
+	}
+	if len(image) == 0 {
+		return "", errors.New("empty image")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	dataURL := "data:" + strings.ToLower(mimeType) + ";base64," + base64.StdEncoding.EncodeToString(image)
+	resp, err := c.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model:       c.model,
+		Temperature: 0,
+		Messages: []openai.ChatCompletionMessage{{
+			Role: openai.ChatMessageRoleUser,
+			MultiContent: []openai.ChatMessagePart{
+				{Type: openai.ChatMessagePartTypeText, Text: visionPrompt},
+				{Type: openai.ChatMessagePartTypeImageURL, ImageURL: &openai.ChatMessageImageURL{URL: dataURL}},
+			},
+		}},
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "vision OCR request failed")
+	}
+	if len(resp.Choices) == 0 {
+		return "", errors.New("vision OCR returned no choices")
+	}
+
+	text := strings.TrimSpace(resp.Choices[0].Message.Content)
+	if text == visionNoText {
+		return "", nil
+	}
+	return text, nil
+}
+
+// IsSupported checks if a MIME type is supported for OCR.
+func (c *VisionClient) IsSupported(mimeType string) bool {
+	for _, supported := range SupportedMimeTypes {
+		if strings.EqualFold(mimeType, supported) {
+			return true
+		}
+	}
+	return false
+}
+
+// EngineConfig selects and configures an OCR engine.
+type EngineConfig struct {
+	Tesseract *Config
+	Vision    *VisionConfig
+	// Provider is ProviderTesseract (default) or ProviderAPI
+	Provider string
+}
+
+// NewEngine creates the engine of the configured provider.
+func NewEngine(config *EngineConfig) (Engine, error) {
+	switch strings.ToLower(config.Provider) {
+	case "", ProviderTesseract:
+		return NewClient(config.Tesseract), nil
+	case ProviderAPI:
+		if config.Vision == nil || config.Vision.Model == "" {
+			return nil, errors.New("OCR provider api requires a vision model")
+		}
+		return NewVisionClient(config.Vision), nil
+	default:
+		return nil, errors.Errorf("unknown OCR provider: %s", config.Provider)
+	}
+}
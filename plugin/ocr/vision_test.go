@@ -0,0 +1,80 @@
+package ocr
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// visionServer answers chat completions with the given content and records
+// the last request body.
+func visionServer(t *testing.T, content string, last *map[string]any) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(last))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]any{"role": "assistant", "content": content}}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVisionClientExtractText(t *testing.T) {
+	var request map[string]any
+	server := visionServer(t, "  第一行\nSecond line \n", &request)
+	client := NewVisionClient(&VisionConfig{BaseURL: server.URL + "/v1/", APIKey: "test-key", Model: "vl-model"})
+
+	text, err := client.ExtractText(context.Background(), []byte{0x89, 'P', 'N', 'G'}, "image/PNG")
+	require.NoError(t, err)
+	assert.Equal(t, "第一行\nSecond line", text)
+
+	assert.Equal(t, "vl-model", request["model"])
+	parts := request["messages"].([]any)[0].(map[string]any)["content"].([]any)
+	require.Len(t, parts, 2)
+	url := parts[1].(map[string]any)["image_url"].(map[string]any)["url"].(string)
+	assert.True(t, strings.HasPrefix(url, "data:image/png;base64,"), url)
+}
+
+func TestVisionClientNoText(t *testing.T) {
+	var request map[string]any
+	server := visionServer(t, visionNoText, &request)
+	client := NewVisionClient(&VisionConfig{BaseURL: server.URL + "/v1", APIKey: "test-key", Model: "vl-model"})
+
+	text, err := client.ExtractText(context.Background(), []byte("img"), "image/jpeg")
+	require.NoError(t, err)
+	assert.Empty(t, text)
+}
+
+func TestVisionClientRejectsInput(t *testing.T) {
+	client := NewVisionClient(&VisionConfig{Model: "vl-model"})
+
+	_, err := client.ExtractText(context.Background(), []byte("%PDF"), "application/pdf")
+	assert.Error(t, err)
+	_, err = client.ExtractText(context.Background(), nil, "image/png")
+	assert.Error(t, err)
+}
+
+func TestNewEngine(t *testing.T) {
+	engine, err := NewEngine(&EngineConfig{})
+	require.NoError(t, err)
+	assert.IsType(t, &Client{}, engine)
+
+	engine, err = NewEngine(&EngineConfig{Provider: "API", Vision: &VisionConfig{Model: "vl-model"}})
+	require.NoError(t, err)
+	assert.IsType(t, &VisionClient{}, engine)
+
+	_, err = NewEngine(&EngineConfig{Provider: ProviderAPI, Vision: &VisionConfig{}})
+	assert.Error(t, err)
+	_, err = NewEngine(&EngineConfig{Provider: "paddle"})
+	assert.Error(t, err)
+}
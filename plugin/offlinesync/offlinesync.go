@@ -0,0 +1,313 @@
+// Package offlinesync is the sync protocol of offline-first clients, such as
+// the mobile app, for memos and conversations.
+//
+// Every write to a memo or conversation is recorded in a change log by
+// database triggers, whatever API made it. Clients pull the changes after
+// their cursor: entities changed since, at their current state and version,
+// and tombstones for deleted ones. Offline edits are pushed as mutations
+// stating the version they were based on, and applied by these rules:
+//
+//   - A mutation based on the current version is applied.
+//   - When both sides edited, memo content differing from the server's is a
+//     conflict: the server content is kept and the client's recorded. Other
+//     fields (visibility, pinned, archived, title) go to the last writer, by
+//     the time of the client's edit against the server's update time.
+//   - Deleting an entity edited on the server is a conflict; the edit is kept.
+//   - Editing an entity deleted on the server is a conflict; the deletion is kept.
+//   - Memos created offline are created with their client UID; conversations
+//     are created online, by chatting.
+//
+// Conflicts are recorded as events that clients list and resolve, keeping
+// either side or a merged content.
+package offlinesync
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidCursor is returned for cursors not issued by Pull.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Entity is the kind of a synced entity.
+type Entity string
+
+const (
+	EntityMemo         Entity = "memo"
+	EntityConversation Entity = "conversation"
+)
+
+const (
+	// ChangeRetention is how long superseded changes and tombstones are kept.
+	// Clients that did not sync for longer must reset their copy.
+	ChangeRetention = 90 * 24 * time.Hour
+
+	defaultPullLimit = 200
+	maxPullLimit     = 1000
+	// MaxPushMutations bounds the mutations of a push.
+	MaxPushMutations = 100
+)
+
+// Cursor is the position of a client in the change log.
+type Cursor struct {
+	// ChangeID is the last change the client received.
+	ChangeID int64
+	// SyncedTs is when the client pulled it.
+	SyncedTs int64
+}
+
+// String encodes the cursor for clients, which treat it as opaque.
+func (c Cursor) String() string {
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%d:%d", c.ChangeID, c.SyncedTs))
+}
+
+// ParseCursor decodes a cursor; the empty cursor is the start of the log.
+func ParseCursor(s string) (Cursor, error) {
+	var c Cursor
+	if s == "" {
+		return c, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if _, err := fmt.Sscanf(string(data), "%d:%d", &c.ChangeID, &c.SyncedTs); err != nil || c.ChangeID < 0 {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// Expired reports whether changes after the cursor may have been pruned.
+func (c Cursor) Expired(now time.Time) bool {
+	return c.ChangeID > 0 && time.Unix(c.SyncedTs, 0).Before(now.Add(-ChangeRetention))
+}
+
+// Memo is the synced state of a memo.
+type Memo struct {
+	Content    string `json:"content"`
+	Visibility string `json:"visibility"`
+	Pinned     bool   `json:"pinned"`
+	Archived   bool   `json:"archived"`
+	CreatedTs  int64  `json:"created_ts"`
+	UpdatedTs  int64  `json:"updated_ts"`
+}
+
+// Conversation is the synced state of a conversation; its blocks are synced
+// with the block changes API.
+type Conversation struct {
+	ID        int32  `json:"id"`
+	Title     string `json:"title"`
+	ParrotID  string `json:"parrot_id"`
+	Pinned    bool   `json:"pinned"`
+	Archived  bool   `json:"archived"`
+	CreatedTs int64  `json:"created_ts"`
+	UpdatedTs int64  `json:"updated_ts"`
+}
+
+// Change is the current state of an entity changed after a cursor.
+type Change struct {
+	Entity Entity `json:"entity"`
+	UID    string `json:"uid"`
+	// Version increases with every change of the entity; mutations state the
+	// version they were based on.
+	Version int64 `json:"version"`
+	// Deleted marks a tombstone.
+	Deleted      bool          `json:"deleted"`
+	Memo         *Memo         `json:"memo,omitempty"`
+	Conversation *Conversation `json:"conversation,omitempty"`
+}
+
+// updatedTs returns the time the entity was last updated.
+func (c *Change) updatedTs() int64 {
+	switch {
+	case c.Memo != nil:
+		return c.Memo.UpdatedTs
+	case c.Conversation != nil:
+		return c.Conversation.UpdatedTs
+	}
+	return 0
+}
+
+// MemoPatch holds the memo fields a mutation sets; nil fields are unchanged.
+type MemoPatch struct {
+	Content    *string `json:"content,omitempty"`
+	Visibility *string `json:"visibility,omitempty"`
+	Pinned     *bool   `json:"pinned,omitempty"`
+	Archived   *bool   `json:"archived,omitempty"`
+}
+
+func (p *MemoPatch) empty() bool {
+	return p == nil || (p.Content == nil && p.Visibility == nil && p.Pinned == nil && p.Archived == nil)
+}
+
+// ConversationPatch holds the conversation fields a mutation sets.
+type ConversationPatch struct {
+	Title    *string `json:"title,omitempty"`
+	Pinned   *bool   `json:"pinned,omitempty"`
+	Archived *bool   `json:"archived,omitempty"`
+}
+
+func (p *ConversationPatch) empty() bool {
+	return p == nil || (p.Title == nil && p.Pinned == nil && p.Archived == nil)
+}
+
+// Mutation is an edit a client made, possibly offline.
+type Mutation struct {
+	// ID identifies the mutation in the results; chosen by the client.
+	ID     string `json:"id"`
+	Entity Entity `json:"entity"`
+	UID    string `json:"uid"`
+	// BaseVersion is the version the client edited; 0 for memos created offline.
+	BaseVersion int64 `json:"base_version"`
+	// EditedTs is when the client made the edit (Unix seconds).
+	EditedTs     int64              `json:"edited_ts"`
+	Delete       bool               `json:"delete"`
+	Memo         *MemoPatch         `json:"memo,omitempty"`
+	Conversation *ConversationPatch `json:"conversation,omitempty"`
+}
+
+// Validate checks the shape of a mutation.
+func (m *Mutation) Validate() error {
+	if m.UID == "" {
+		return fmt.Errorf("uid is required")
+	}
+	if m.BaseVersion < 0 {
+		return fmt.Errorf("base_version must not be negative")
+	}
+	switch m.Entity {
+	case EntityMemo:
+		if m.Conversation != nil {
+			return fmt.Errorf("memo mutations cannot set conversation fields")
+		}
+		if !m.Delete && m.Memo.empty() {
+			return fmt.Errorf("memo mutations must delete or set fields")
+		}
+	case EntityConversation:
+		if m.Memo != nil {
+			return fmt.Errorf("conversation mutations cannot set memo fields")
+		}
+		if !m.Delete && m.Conversation.empty() {
+			return fmt.Errorf("conversation mutations must delete or set fields")
+		}
+	default:
+		return fmt.Errorf("entity must be memo or conversation")
+	}
+	if m.Delete && (!m.Memo.empty() || !m.Conversation.empty()) {
+		return fmt.Errorf("delete mutations cannot set fields")
+	}
+	return nil
+}
+
+// ConflictKind is how a mutation conflicted with the server.
+type ConflictKind string
+
+const (
+	// ConflictEditEdit is memo content edited differently on both sides.
+	ConflictEditEdit ConflictKind = "edit_edit"
+	// ConflictEditDeleted is an edit of an entity deleted on the server.
+	ConflictEditDeleted ConflictKind = "edit_deleted"
+	// ConflictDeleteEdited is a deletion of an entity edited on the server.
+	ConflictDeleteEdited ConflictKind = "delete_edited"
+)
+
+// Plan is how a mutation applies to the current state of its entity.
+type Plan struct {
+	Create bool
+	Delete bool
+	// Memo and Conversation are the fields to write.
+	Memo         *MemoPatch
+	Conversation *ConversationPatch
+	// Conflict is set when part of the mutation was not applied.
+	Conflict ConflictKind
+	// Reject is the reason the mutation cannot be applied at all.
+	Reject string
+}
+
+// Writes reports whether the plan changes the entity.
+func (p *Plan) Writes() bool {
+	return p.Create || p.Delete || !p.Memo.empty() || !p.Conversation.empty()
+}
+
+// PlanMutation decides how a valid mutation applies to the current state of
+// its entity, nil or a tombstone if it does not exist, by the package rules.
+func PlanMutation(m *Mutation, current *Change) *Plan {
+	plan := &Plan{}
+	if current == nil || current.Deleted {
+		switch {
+		case m.Delete:
+			// Already gone.
+		case m.BaseVersion > 0:
+			plan.Conflict = ConflictEditDeleted
+		case m.Entity == EntityConversation:
+			plan.Reject = "conversations cannot be created offline"
+		default:
+			plan.Create, plan.Memo = true, m.Memo
+		}
+		return plan
+	}
+	if m.BaseVersion > current.Version {
+		plan.Reject = "base_version is ahead of the server; pull first"
+		return plan
+	}
+
+	// A memo created offline whose UID exists was pushed before, or collides:
+	// both are handled as edits of an unknown version.
+	concurrent := m.BaseVersion < current.Version
+	if m.Delete {
+		if concurrent {
+			plan.Conflict = ConflictDeleteEdited
+		} else {
+			plan.Delete = true
+		}
+		return plan
+	}
+	// lastWriter reports whether the client's edit of a field wins.
+	lastWriter := !concurrent || m.EditedTs > current.updatedTs()
+
+	switch m.Entity {
+	case EntityMemo:
+		cur, patch := current.Memo, &MemoPatch{}
+		if m.Memo.Content != nil && *m.Memo.Content != cur.Content {
+			if concurrent {
+				plan.Conflict = ConflictEditEdit
+			} else {
+				patch.Content = m.Memo.Content
+			}
+		}
+		if lastWriter {
+			patch.Visibility = changedString(m.Memo.Visibility, cur.Visibility)
+			patch.Pinned = changedBool(m.Memo.Pinned, cur.Pinned)
+			patch.Archived = changedBool(m.Memo.Archived, cur.Archived)
+		}
+		if !patch.empty() {
+			plan.Memo = patch
+		}
+	case EntityConversation:
+		cur, patch := current.Conversation, &ConversationPatch{}
+		if lastWriter {
+			patch.Title = changedString(m.Conversation.Title, cur.Title)
+			patch.Pinned = changedBool(m.Conversation.Pinned, cur.Pinned)
+			patch.Archived = changedBool(m.Conversation.Archived, cur.Archived)
+		}
+		if !patch.empty() {
+			plan.Conversation = patch
+		}
+	}
+	return plan
+}
+
+func changedString(value *string, current string) *string {
+	if value == nil || *value == current {
+		return nil
+	}
+	return value
+}
+
+func changedBool(value *bool, current bool) *bool {
+	if value == nil || *value == current {
+		return nil
+	}
+	return value
+}
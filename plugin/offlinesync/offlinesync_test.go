@@ -0,0 +1,366 @@
+package offlinesync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestCursor(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	cursor := Cursor{ChangeID: 42, SyncedTs: now.Unix()}
+	parsed, err := ParseCursor(cursor.String())
+	require.NoError(t, err)
+	assert.Equal(t, cursor, parsed)
+
+	start, err := ParseCursor("")
+	require.NoError(t, err)
+	assert.Equal(t, Cursor{}, start)
+	assert.False(t, start.Expired(now))
+
+	for _, invalid := range []string{"!!", "bm90LWEtY3Vyc29y", Cursor{ChangeID: -1}.String()} {
+		_, err := ParseCursor(invalid)
+		assert.ErrorIs(t, err, ErrInvalidCursor, invalid)
+	}
+
+	assert.False(t, cursor.Expired(now.Add(ChangeRetention-time.Hour)))
+	assert.True(t, cursor.Expired(now.Add(ChangeRetention+time.Hour)))
+}
+
+func TestPlanMutation(t *testing.T) {
+	memo := &Change{Entity: EntityMemo, UID: "m1", Version: 5, Memo: &Memo{
+		Content: "server", Visibility: "PRIVATE", UpdatedTs: 100,
+	}}
+	edit := func(base, editedTs int64, patch *MemoPatch) *Mutation {
+		return &Mutation{Entity: EntityMemo, UID: "m1", BaseVersion: base, EditedTs: editedTs, Memo: patch}
+	}
+
+	t.Run("current version applies", func(t *testing.T) {
+		plan := PlanMutation(edit(5, 50, &MemoPatch{Content: ptr("client"), Pinned: ptr(true)}), memo)
+		assert.Empty(t, plan.Conflict)
+		assert.Equal(t, &MemoPatch{Content: ptr("client"), Pinned: ptr(true)}, plan.Memo)
+	})
+
+	t.Run("unchanged fields are not written", func(t *testing.T) {
+		plan := PlanMutation(edit(5, 50, &MemoPatch{Content: ptr("server"), Visibility: ptr("PRIVATE")}), memo)
+		assert.False(t, plan.Writes())
+	})
+
+	t.Run("concurrent content edit conflicts", func(t *testing.T) {
+		plan := PlanMutation(edit(3, 200, &MemoPatch{Content: ptr("client"), Pinned: ptr(true)}), memo)
+		assert.Equal(t, ConflictEditEdit, plan.Conflict)
+		// The later edit of other fields still wins.
+		assert.Equal(t, &MemoPatch{Pinned: ptr(true)}, plan.Memo)
+	})
+
+	t.Run("concurrent same content does not conflict", func(t *testing.T) {
+		plan := PlanMutation(edit(3, 200, &MemoPatch{Content: ptr("server")}), memo)
+		assert.Empty(t, plan.Conflict)
+		assert.False(t, plan.Writes())
+	})
+
+	t.Run("earlier concurrent edit loses", func(t *testing.T) {
+		plan := PlanMutation(edit(3, 50, &MemoPatch{Visibility: ptr("PUBLIC")}), memo)
+		assert.Empty(t, plan.Conflict)
+		assert.False(t, plan.Writes())
+	})
+
+	t.Run("delete of edited entity conflicts", func(t *testing.T) {
+		plan := PlanMutation(&Mutation{Entity: EntityMemo, UID: "m1", BaseVersion: 3, Delete: true}, memo)
+		assert.Equal(t, ConflictDeleteEdited, plan.Conflict)
+		assert.False(t, plan.Writes())
+
+		plan = PlanMutation(&Mutation{Entity: EntityMemo, UID: "m1", BaseVersion: 5, Delete: true}, memo)
+		assert.True(t, plan.Delete)
+	})
+
+	t.Run("edit of deleted entity conflicts", func(t *testing.T) {
+		tombstone := &Change{Entity: EntityMemo, UID: "m1", Version: 6, Deleted: true}
+		plan := PlanMutation(edit(5, 50, &MemoPatch{Content: ptr("client")}), tombstone)
+		assert.Equal(t, ConflictEditDeleted, plan.Conflict)
+		assert.False(t, plan.Writes())
+
+		plan = PlanMutation(&Mutation{Entity: EntityMemo, UID: "m1", BaseVersion: 5, Delete: true}, tombstone)
+		assert.Empty(t, plan.Conflict)
+		assert.False(t, plan.Writes())
+	})
+
+	t.Run("offline creation", func(t *testing.T) {
+		plan := PlanMutation(edit(0, 50, &MemoPatch{Content: ptr("new")}), nil)
+		assert.True(t, plan.Create)
+
+		plan = PlanMutation(&Mutation{Entity: EntityConversation, UID: "c1", Conversation: &ConversationPatch{Title: ptr("t")}}, nil)
+		assert.NotEmpty(t, plan.Reject)
+	})
+
+	t.Run("base ahead of server is rejected", func(t *testing.T) {
+		plan := PlanMutation(edit(9, 50, &MemoPatch{Content: ptr("client")}), memo)
+		assert.NotEmpty(t, plan.Reject)
+	})
+}
+
+func TestMutationValidate(t *testing.T) {
+	require.NoError(t, (&Mutation{Entity: EntityMemo, UID: "m1", Memo: &MemoPatch{Content: ptr("x")}}).Validate())
+	require.NoError(t, (&Mutation{Entity: EntityConversation, UID: "c1", BaseVersion: 2, Delete: true}).Validate())
+
+	for _, invalid := range []*Mutation{
+		{Entity: EntityMemo, Memo: &MemoPatch{Content: ptr("x")}},
+		{Entity: "tag", UID: "t1", Delete: true},
+		{Entity: EntityMemo, UID: "m1"},
+		{Entity: EntityMemo, UID: "m1", Conversation: &ConversationPatch{Title: ptr("t")}},
+		{Entity: EntityMemo, UID: "m1", Delete: true, Memo: &MemoPatch{Pinned: ptr(true)}},
+		{Entity: EntityMemo, UID: "m1", BaseVersion: -1, Delete: true},
+	} {
+		assert.Error(t, invalid.Validate(), "%+v", invalid)
+	}
+}
+
+// fakeStore keeps memos in memory and logs their changes like the triggers.
+type fakeStore struct {
+	log       []*LogEntry
+	memos     map[string]*Memo
+	conflicts []*Conflict
+	resolved  map[int64]Resolution
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{memos: map[string]*Memo{}, resolved: map[int64]Resolution{}}
+}
+
+func (s *fakeStore) record(uid string) {
+	s.log = append(s.log, &LogEntry{ID: int64(len(s.log) + 1), Entity: EntityMemo, UID: uid})
+}
+
+func (s *fakeStore) ListChanges(_ context.Context, _ int32, after int64, limit int) ([]*LogEntry, error) {
+	var entries []*LogEntry
+	for _, entry := range s.log {
+		if entry.ID > after && len(entries) < limit {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (s *fakeStore) GetEntities(_ context.Context, _ int32, entity Entity, uids []string) (map[string]*Change, error) {
+	states := map[string]*Change{}
+	for _, uid := range uids {
+		var version int64
+		for _, entry := range s.log {
+			if entry.Entity == entity && entry.UID == uid {
+				version = entry.ID
+			}
+		}
+		if version == 0 {
+			continue
+		}
+		change := &Change{Entity: entity, UID: uid, Version: version, Deleted: true}
+		if memo, ok := s.memos[uid]; ok {
+			copied := *memo
+			change.Deleted, change.Memo = false, &copied
+		}
+		states[uid] = change
+	}
+	return states, nil
+}
+
+func (s *fakeStore) CreateConflict(_ context.Context, conflict *Conflict) (*Conflict, error) {
+	conflict.ID = int64(len(s.conflicts) + 1)
+	s.conflicts = append(s.conflicts, conflict)
+	return conflict, nil
+}
+
+func (s *fakeStore) ListConflicts(_ context.Context, _ int32) ([]*Conflict, error) {
+	conflicts := []*Conflict{}
+	for _, conflict := range s.conflicts {
+		if _, ok := s.resolved[conflict.ID]; !ok {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	return conflicts, nil
+}
+
+func (s *fakeStore) GetConflict(ctx context.Context, userID int32, id int64) (*Conflict, error) {
+	conflicts, _ := s.ListConflicts(ctx, userID)
+	for _, conflict := range conflicts {
+		if conflict.ID == id {
+			return conflict, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *fakeStore) ResolveConflict(_ context.Context, _ int32, id int64, resolution Resolution) error {
+	s.resolved[id] = resolution
+	return nil
+}
+
+func (*fakeStore) Prune(context.Context, time.Time) error { return nil }
+
+// fakeApplier writes memos to the fake store.
+type fakeApplier struct {
+	store *fakeStore
+	now   int64
+}
+
+func (a *fakeApplier) CreateMemo(_ context.Context, _ int32, uid string, patch *MemoPatch) error {
+	if patch.Content == nil {
+		return fmt.Errorf("%w: content is required", ErrRejected)
+	}
+	a.store.memos[uid] = &Memo{Visibility: "PRIVATE", CreatedTs: a.now}
+	return a.UpdateMemo(context.Background(), 0, uid, patch)
+}
+
+func (a *fakeApplier) UpdateMemo(_ context.Context, _ int32, uid string, patch *MemoPatch) error {
+	memo := a.store.memos[uid]
+	if patch.Content != nil {
+		memo.Content = *patch.Content
+	}
+	if patch.Visibility != nil {
+		memo.Visibility = *patch.Visibility
+	}
+	if patch.Pinned != nil {
+		memo.Pinned = *patch.Pinned
+	}
+	if patch.Archived != nil {
+		memo.Archived = *patch.Archived
+	}
+	memo.UpdatedTs = a.now
+	a.store.record(uid)
+	return nil
+}
+
+func (a *fakeApplier) DeleteMemo(_ context.Context, _ int32, uid string) error {
+	delete(a.store.memos, uid)
+	a.store.record(uid)
+	return nil
+}
+
+func (*fakeApplier) UpdateConversation(context.Context, int32, string, *ConversationPatch) error {
+	return nil
+}
+
+func (*fakeApplier) DeleteConversation(context.Context, int32, string) error { return nil }
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+	applier := &fakeApplier{store: store, now: 100}
+	sync := New(store, applier)
+	now := time.Unix(1000, 0)
+
+	// A memo created offline is created with its UID.
+	results, err := sync.Push(ctx, 1, []*Mutation{
+		{ID: "1", Entity: EntityMemo, UID: "m1", EditedTs: 90, Memo: &MemoPatch{Content: ptr("draft"), Pinned: ptr(true)}},
+		{ID: "2", Entity: EntityMemo, UID: "m2", EditedTs: 90, Memo: &MemoPatch{Pinned: ptr(true)}},
+		{ID: "3", Entity: EntityMemo, Memo: &MemoPatch{Pinned: ptr(true)}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, StatusApplied, results[0].Status)
+	assert.Equal(t, "draft", results[0].Change.Memo.Content)
+	assert.True(t, results[0].Change.Memo.Pinned)
+	assert.Equal(t, StatusRejected, results[1].Status)
+	assert.Equal(t, StatusRejected, results[2].Status)
+	version := results[0].Change.Version
+
+	// Another device pulls it.
+	pull, err := sync.Pull(ctx, 1, "", 0, now)
+	require.NoError(t, err)
+	require.Len(t, pull.Changes, 1)
+	assert.Equal(t, "m1", pull.Changes[0].UID)
+	assert.Equal(t, version, pull.Changes[0].Version)
+	cursor := pull.Cursor
+
+	// Both devices edit the content: the second to push conflicts.
+	applier.now = 200
+	results, err = sync.Push(ctx, 1, []*Mutation{
+		{ID: "4", Entity: EntityMemo, UID: "m1", BaseVersion: version, EditedTs: 150, Memo: &MemoPatch{Content: ptr("phone")}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, StatusApplied, results[0].Status)
+	results, err = sync.Push(ctx, 1, []*Mutation{
+		{ID: "5", Entity: EntityMemo, UID: "m1", BaseVersion: version, EditedTs: 160, Memo: &MemoPatch{Content: ptr("tablet")}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, StatusConflict, results[0].Status)
+	assert.Equal(t, "phone", results[0].Change.Memo.Content)
+	conflict := results[0].Conflict
+	require.NotNil(t, conflict)
+	assert.Equal(t, ConflictEditEdit, conflict.Kind)
+	assert.Equal(t, "phone", conflict.Server.Memo.Content)
+
+	// Pulling returns the change and the open conflict.
+	pull, err = sync.Pull(ctx, 1, cursor, 0, now)
+	require.NoError(t, err)
+	require.Len(t, pull.Changes, 1)
+	assert.Equal(t, "phone", pull.Changes[0].Memo.Content)
+	require.Len(t, pull.Conflicts, 1)
+
+	// Resolving with a merge writes it.
+	_, err = sync.Resolve(ctx, 1, conflict.ID, ResolveMerged, "phone\ntablet")
+	require.NoError(t, err)
+	change, err := sync.Resolve(ctx, 1, conflict.ID, ResolveServer, "")
+	require.ErrorIs(t, err, ErrConflictNotFound)
+	assert.Nil(t, change)
+	assert.Equal(t, "phone\ntablet", store.memos["m1"].Content)
+	conflicts, err := sync.Conflicts(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	// Deletions are pulled as tombstones.
+	current := store.log[len(store.log)-1].ID
+	results, err = sync.Push(ctx, 1, []*Mutation{{ID: "6", Entity: EntityMemo, UID: "m1", BaseVersion: current, Delete: true}})
+	require.NoError(t, err)
+	assert.Equal(t, StatusApplied, results[0].Status)
+	assert.True(t, results[0].Change.Deleted)
+	pull, err = sync.Pull(ctx, 1, cursor, 0, now)
+	require.NoError(t, err)
+	require.Len(t, pull.Changes, 1)
+	assert.True(t, pull.Changes[0].Deleted)
+
+	// Paging.
+	pull, err = sync.Pull(ctx, 1, "", 1, now)
+	require.NoError(t, err)
+	assert.True(t, pull.HasMore)
+	require.Len(t, pull.Changes, 1)
+
+	// Expired cursors reset the client.
+	pull, err = sync.Pull(ctx, 1, cursor, 0, now.Add(ChangeRetention+time.Hour))
+	require.NoError(t, err)
+	assert.True(t, pull.Reset)
+	assert.Empty(t, pull.Changes)
+}
+
+func TestResolve(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+	applier := &fakeApplier{store: store, now: 100}
+	sync := New(store, applier)
+
+	require.NoError(t, applier.CreateMemo(ctx, 1, "m1", &MemoPatch{Content: ptr("server")}))
+	results, err := sync.Push(ctx, 1, []*Mutation{
+		{ID: "1", Entity: EntityMemo, UID: "m1", BaseVersion: 0, EditedTs: 50, Delete: true},
+	})
+	require.NoError(t, err)
+	// The memo was unknown to the client; a delete of an unseen version conflicts.
+	require.Equal(t, StatusConflict, results[0].Status)
+	conflict := results[0].Conflict
+	assert.Equal(t, ConflictDeleteEdited, conflict.Kind)
+
+	_, err = sync.Resolve(ctx, 1, conflict.ID, ResolveMerged, "merged")
+	require.ErrorIs(t, err, ErrInvalidResolution)
+	_, err = sync.Resolve(ctx, 1, conflict.ID, "mine", "")
+	require.ErrorIs(t, err, ErrInvalidResolution)
+
+	change, err := sync.Resolve(ctx, 1, conflict.ID, ResolveClient, "")
+	require.NoError(t, err)
+	assert.True(t, change.Deleted)
+	assert.NotContains(t, store.memos, "m1")
+	assert.Equal(t, ResolveClient, store.resolved[conflict.ID])
+}
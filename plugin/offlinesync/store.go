@@ -0,0 +1,241 @@
+package offlinesync
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// settleSeconds is how old changes must be to be pulled. Change IDs are
+// allocated before their transaction commits, so a change may become visible
+// after one with a higher ID; waiting for writes to settle keeps cursors from
+// skipping it.
+const settleSeconds = 5
+
+// DBStore persists the change log in the sync_change table, written by
+// triggers on memo and ai_conversation, and conflicts in sync_conflict
+// (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new sync store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// ListChanges implements Store.
+func (s *DBStore) ListChanges(ctx context.Context, userID int32, after int64, limit int) ([]*LogEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, entity, entity_uid FROM sync_change
+		WHERE user_id = $1 AND id > $2 AND changed_ts <= EXTRACT(EPOCH FROM NOW())::BIGINT - $3
+		ORDER BY id
+		LIMIT $4`, userID, after, settleSeconds, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sync changes: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*LogEntry
+	for rows.Next() {
+		entry := &LogEntry{}
+		if err := rows.Scan(&entry.ID, &entry.Entity, &entry.UID); err != nil {
+			return nil, fmt.Errorf("failed to scan sync change: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// GetEntities implements Store.
+func (s *DBStore) GetEntities(ctx context.Context, userID int32, entity Entity, uids []string) (map[string]*Change, error) {
+	states := map[string]*Change{}
+	var query string
+	switch entity {
+	case EntityMemo:
+		query = `
+			SELECT m.uid, v.version, m.content, m.visibility, m.pinned, m.row_status = 'ARCHIVED', m.created_ts, m.updated_ts
+			FROM memo m
+			CROSS JOIN LATERAL (
+				SELECT COALESCE(MAX(id), 0) AS version FROM sync_change
+				WHERE entity = 'memo' AND entity_uid = m.uid
+			) v
+			WHERE m.creator_id = $1 AND m.uid = ANY($2)`
+	case EntityConversation:
+		query = `
+			SELECT c.uid, v.version, c.id, c.title, c.parrot_id, c.pinned, c.row_status = 'ARCHIVED', c.created_ts, c.updated_ts
+			FROM ai_conversation c
+			CROSS JOIN LATERAL (
+				SELECT COALESCE(MAX(id), 0) AS version FROM sync_change
+				WHERE entity = 'conversation' AND entity_uid = c.uid
+			) v
+			WHERE c.creator_id = $1 AND c.uid = ANY($2)`
+	default:
+		return nil, fmt.Errorf("unknown entity %q", entity)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, userID, pq.Array(uids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s states: %w", entity, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		change := &Change{Entity: entity}
+		if entity == EntityMemo {
+			memo := &Memo{}
+			err = rows.Scan(&change.UID, &change.Version, &memo.Content, &memo.Visibility, &memo.Pinned, &memo.Archived,
+				&memo.CreatedTs, &memo.UpdatedTs)
+			change.Memo = memo
+		} else {
+			conversation := &Conversation{}
+			err = rows.Scan(&change.UID, &change.Version, &conversation.ID, &conversation.Title, &conversation.ParrotID,
+				&conversation.Pinned, &conversation.Archived, &conversation.CreatedTs, &conversation.UpdatedTs)
+			change.Conversation = conversation
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s state: %w", entity, err)
+		}
+		states[change.UID] = change
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get %s states: %w", entity, err)
+	}
+
+	// The others are tombstones if the user's log knows them.
+	var missing []string
+	for _, uid := range uids {
+		if _, ok := states[uid]; !ok {
+			missing = append(missing, uid)
+		}
+	}
+	if len(missing) == 0 {
+		return states, nil
+	}
+	rows, err = s.db.QueryContext(ctx, `
+		SELECT entity_uid, MAX(id) FROM sync_change
+		WHERE user_id = $1 AND entity = $2 AND entity_uid = ANY($3)
+		GROUP BY entity_uid`, userID, entity, pq.Array(missing))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s tombstones: %w", entity, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		change := &Change{Entity: entity, Deleted: true}
+		if err := rows.Scan(&change.UID, &change.Version); err != nil {
+			return nil, fmt.Errorf("failed to scan %s tombstone: %w", entity, err)
+		}
+		states[change.UID] = change
+	}
+	return states, rows.Err()
+}
+
+const conflictColumns = "id, user_id, entity, entity_uid, kind, client, server, created_ts"
+
+// CreateConflict implements Store.
+func (s *DBStore) CreateConflict(ctx context.Context, conflict *Conflict) (*Conflict, error) {
+	client, err := json.Marshal(conflict.Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode conflict: %w", err)
+	}
+	server, err := json.Marshal(conflict.Server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode conflict: %w", err)
+	}
+	created, err := scanConflict(s.db.QueryRowContext(ctx, `
+		INSERT INTO sync_conflict (user_id, entity, entity_uid, kind, client, server, created_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+conflictColumns,
+		conflict.UserID, conflict.Entity, conflict.UID, conflict.Kind, client, server, time.Now().Unix()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create conflict: %w", err)
+	}
+	return created, nil
+}
+
+// ListConflicts implements Store.
+func (s *DBStore) ListConflicts(ctx context.Context, userID int32) ([]*Conflict, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+conflictColumns+" FROM sync_conflict WHERE user_id = $1 AND resolved_ts = 0 ORDER BY id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	conflicts := []*Conflict{}
+	for rows.Next() {
+		conflict, err := scanConflict(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan conflict: %w", err)
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts, rows.Err()
+}
+
+// GetConflict implements Store.
+func (s *DBStore) GetConflict(ctx context.Context, userID int32, id int64) (*Conflict, error) {
+	conflict, err := scanConflict(s.db.QueryRowContext(ctx,
+		"SELECT "+conflictColumns+" FROM sync_conflict WHERE id = $1 AND user_id = $2 AND resolved_ts = 0", id, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conflict: %w", err)
+	}
+	return conflict, nil
+}
+
+// ResolveConflict implements Store.
+func (s *DBStore) ResolveConflict(ctx context.Context, userID int32, id int64, resolution Resolution) error {
+	if _, err := s.db.ExecContext(ctx,
+		"UPDATE sync_conflict SET resolution = $1, resolved_ts = $2 WHERE id = $3 AND user_id = $4",
+		resolution, time.Now().Unix(), id, userID); err != nil {
+		return fmt.Errorf("failed to resolve conflict: %w", err)
+	}
+	return nil
+}
+
+// Prune implements Store.
+func (s *DBStore) Prune(ctx context.Context, before time.Time) error {
+	// The last change of existing entities is kept, so that pulling from the
+	// empty cursor lists them all.
+	if _, err := s.db.ExecContext(ctx, `
+		DELETE FROM sync_change c
+		WHERE c.changed_ts < $1 AND (
+			EXISTS (SELECT 1 FROM sync_change n WHERE n.entity = c.entity AND n.entity_uid = c.entity_uid AND n.id > c.id)
+			OR (c.entity = 'memo' AND NOT EXISTS (SELECT 1 FROM memo m WHERE m.uid = c.entity_uid))
+			OR (c.entity = 'conversation' AND NOT EXISTS (SELECT 1 FROM ai_conversation a WHERE a.uid = c.entity_uid))
+		)`, before.Unix()); err != nil {
+		return fmt.Errorf("failed to prune sync changes: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx,
+		"DELETE FROM sync_conflict WHERE resolved_ts > 0 AND resolved_ts < $1", before.Unix()); err != nil {
+		return fmt.Errorf("failed to prune sync conflicts: %w", err)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanConflict(row rowScanner) (*Conflict, error) {
+	conflict := &Conflict{}
+	var client, server []byte
+	if err := row.Scan(&conflict.ID, &conflict.UserID, &conflict.Entity, &conflict.UID, &conflict.Kind,
+		&client, &server, &conflict.CreatedTs); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(client, &conflict.Client); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(server, &conflict.Server); err != nil {
+		return nil, err
+	}
+	return conflict, nil
+}
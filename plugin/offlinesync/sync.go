@@ -0,0 +1,360 @@
+package offlinesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ErrRejected is wrapped by Applier errors about invalid mutations, such as
+// a memo content over the length limit; other errors fail the push.
+var ErrRejected = errors.New("mutation rejected")
+
+// ErrConflictNotFound is returned when resolving an unknown or resolved conflict.
+var ErrConflictNotFound = errors.New("conflict not found")
+
+// ErrInvalidResolution is returned for resolutions a conflict does not allow.
+var ErrInvalidResolution = errors.New("invalid resolution")
+
+// LogEntry is an entry of the change log.
+type LogEntry struct {
+	ID     int64
+	Entity Entity
+	UID    string
+}
+
+// Conflict is a mutation that could not be fully applied because both sides
+// changed the entity.
+type Conflict struct {
+	ID     int64        `json:"id"`
+	UserID int32        `json:"-"`
+	Entity Entity       `json:"entity"`
+	UID    string       `json:"uid"`
+	Kind   ConflictKind `json:"kind"`
+	// Client is the mutation that conflicted.
+	Client *Mutation `json:"client"`
+	// Server is the state the mutation conflicted with.
+	Server    *Change `json:"server"`
+	CreatedTs int64   `json:"created_ts"`
+}
+
+// Store persists the change log and conflicts.
+type Store interface {
+	// ListChanges returns up to limit entries of a user's change log after a
+	// change ID, oldest first.
+	ListChanges(ctx context.Context, userID int32, after int64, limit int) ([]*LogEntry, error)
+	// GetEntities returns the current state of entities of a user by UID:
+	// tombstones for deleted ones, nothing for unknown ones.
+	GetEntities(ctx context.Context, userID int32, entity Entity, uids []string) (map[string]*Change, error)
+	CreateConflict(ctx context.Context, conflict *Conflict) (*Conflict, error)
+	// ListConflicts returns the unresolved conflicts of a user, oldest first.
+	ListConflicts(ctx context.Context, userID int32) ([]*Conflict, error)
+	// GetConflict returns an unresolved conflict of a user, or nil.
+	GetConflict(ctx context.Context, userID int32, id int64) (*Conflict, error)
+	ResolveConflict(ctx context.Context, userID int32, id int64, resolution Resolution) error
+	// Prune deletes the changes and resolved conflicts older than before,
+	// except the last change of existing entities.
+	Prune(ctx context.Context, before time.Time) error
+}
+
+// Applier writes mutations through the regular APIs of memos and
+// conversations, so that their checks and side effects apply.
+type Applier interface {
+	CreateMemo(ctx context.Context, userID int32, uid string, patch *MemoPatch) error
+	UpdateMemo(ctx context.Context, userID int32, uid string, patch *MemoPatch) error
+	DeleteMemo(ctx context.Context, userID int32, uid string) error
+	UpdateConversation(ctx context.Context, userID int32, uid string, patch *ConversationPatch) error
+	DeleteConversation(ctx context.Context, userID int32, uid string) error
+}
+
+// Sync serves the sync protocol.
+type Sync struct {
+	store Store
+	apply Applier
+}
+
+// New creates the sync protocol.
+func New(store Store, apply Applier) *Sync {
+	return &Sync{store: store, apply: apply}
+}
+
+// PullResult is a page of changes.
+type PullResult struct {
+	Changes []*Change `json:"changes"`
+	// Cursor is the cursor of the next pull.
+	Cursor  string `json:"cursor"`
+	HasMore bool   `json:"has_more"`
+	// Reset is set when the cursor expired: the client discards its copy and
+	// pulls again from the empty cursor.
+	Reset bool `json:"reset"`
+	// Conflicts are the unresolved conflicts of the user.
+	Conflicts []*Conflict `json:"conflicts"`
+}
+
+// Pull returns the changes of a user after a cursor.
+func (s *Sync) Pull(ctx context.Context, userID int32, cursor string, limit int, now time.Time) (*PullResult, error) {
+	c, err := ParseCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if c.Expired(now) {
+		return &PullResult{Changes: []*Change{}, Reset: true, Conflicts: []*Conflict{}}, nil
+	}
+	if limit <= 0 {
+		limit = defaultPullLimit
+	}
+	limit = min(limit, maxPullLimit)
+
+	entries, err := s.store.ListChanges(ctx, userID, c.ChangeID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	result := &PullResult{HasMore: len(entries) > limit}
+	entries = entries[:min(len(entries), limit)]
+	if len(entries) > 0 {
+		c.ChangeID = entries[len(entries)-1].ID
+	}
+	c.SyncedTs = now.Unix()
+	result.Cursor = c.String()
+
+	if result.Changes, err = s.currentChanges(ctx, userID, entries); err != nil {
+		return nil, err
+	}
+	if result.Conflicts, err = s.store.ListConflicts(ctx, userID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// currentChanges returns the current state of the entities of log entries,
+// each once, ordered by their last entry.
+func (s *Sync) currentChanges(ctx context.Context, userID int32, entries []*LogEntry) ([]*Change, error) {
+	type key struct {
+		entity Entity
+		uid    string
+	}
+	last := map[key]int{}
+	uids := map[Entity][]string{}
+	for i, entry := range entries {
+		k := key{entry.Entity, entry.UID}
+		if _, ok := last[k]; !ok {
+			uids[entry.Entity] = append(uids[entry.Entity], entry.UID)
+		}
+		last[k] = i
+	}
+	current := map[Entity]map[string]*Change{}
+	for entity, list := range uids {
+		states, err := s.store.GetEntities(ctx, userID, entity, list)
+		if err != nil {
+			return nil, err
+		}
+		current[entity] = states
+	}
+
+	changes := []*Change{}
+	for i, entry := range entries {
+		if last[key{entry.Entity, entry.UID}] != i {
+			continue
+		}
+		change, ok := current[entry.Entity][entry.UID]
+		if !ok {
+			change = &Change{Entity: entry.Entity, UID: entry.UID, Version: entry.ID, Deleted: true}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// Status is the outcome of a mutation.
+type Status string
+
+const (
+	StatusApplied   Status = "applied"
+	StatusUnchanged Status = "unchanged"
+	StatusConflict  Status = "conflict"
+	StatusRejected  Status = "rejected"
+)
+
+// Result is the outcome of a pushed mutation.
+type Result struct {
+	ID     string `json:"id"`
+	Status Status `json:"status"`
+	// Change is the state of the entity after the mutation; the client adopts
+	// its version as the base of further edits.
+	Change   *Change   `json:"change,omitempty"`
+	Conflict *Conflict `json:"conflict,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Push applies the mutations of a user in order.
+func (s *Sync) Push(ctx context.Context, userID int32, mutations []*Mutation) ([]*Result, error) {
+	if len(mutations) > MaxPushMutations {
+		return nil, fmt.Errorf("%w: at most %d mutations can be pushed at once", ErrRejected, MaxPushMutations)
+	}
+	results := make([]*Result, 0, len(mutations))
+	for _, m := range mutations {
+		result, err := s.push(ctx, userID, m)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (s *Sync) push(ctx context.Context, userID int32, m *Mutation) (*Result, error) {
+	result := &Result{ID: m.ID}
+	if err := m.Validate(); err != nil {
+		result.Status, result.Error = StatusRejected, err.Error()
+		return result, nil
+	}
+	current, err := s.current(ctx, userID, m.Entity, m.UID)
+	if err != nil {
+		return nil, err
+	}
+	plan := PlanMutation(m, current)
+	if plan.Reject != "" {
+		result.Status, result.Error = StatusRejected, plan.Reject
+		return result, nil
+	}
+	if err := s.write(ctx, userID, m, plan); err != nil {
+		if errors.Is(err, ErrRejected) {
+			result.Status, result.Error = StatusRejected, err.Error()
+			return result, nil
+		}
+		return nil, err
+	}
+
+	switch {
+	case plan.Conflict != "":
+		result.Status = StatusConflict
+		server := current
+		if server == nil {
+			server = &Change{Entity: m.Entity, UID: m.UID, Deleted: true}
+		}
+		result.Conflict, err = s.store.CreateConflict(ctx, &Conflict{
+			UserID: userID, Entity: m.Entity, UID: m.UID, Kind: plan.Conflict, Client: m, Server: server,
+		})
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("offline sync conflict", "user_id", userID, "entity", m.Entity, "uid", m.UID, "kind", plan.Conflict)
+	case plan.Writes():
+		result.Status = StatusApplied
+	default:
+		result.Status = StatusUnchanged
+	}
+	if result.Change, err = s.current(ctx, userID, m.Entity, m.UID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// current returns the state of an entity of a user, or nil if unknown.
+func (s *Sync) current(ctx context.Context, userID int32, entity Entity, uid string) (*Change, error) {
+	states, err := s.store.GetEntities(ctx, userID, entity, []string{uid})
+	if err != nil {
+		return nil, err
+	}
+	return states[uid], nil
+}
+
+// write applies the writes of a plan.
+func (s *Sync) write(ctx context.Context, userID int32, m *Mutation, plan *Plan) error {
+	switch {
+	case plan.Create:
+		return s.apply.CreateMemo(ctx, userID, m.UID, plan.Memo)
+	case plan.Delete && m.Entity == EntityMemo:
+		return s.apply.DeleteMemo(ctx, userID, m.UID)
+	case plan.Delete:
+		return s.apply.DeleteConversation(ctx, userID, m.UID)
+	case !plan.Memo.empty():
+		return s.apply.UpdateMemo(ctx, userID, m.UID, plan.Memo)
+	case !plan.Conversation.empty():
+		return s.apply.UpdateConversation(ctx, userID, m.UID, plan.Conversation)
+	}
+	return nil
+}
+
+// Conflicts returns the unresolved conflicts of a user.
+func (s *Sync) Conflicts(ctx context.Context, userID int32) ([]*Conflict, error) {
+	return s.store.ListConflicts(ctx, userID)
+}
+
+// Resolution is how the user resolves a conflict.
+type Resolution string
+
+const (
+	// ResolveServer keeps the server state.
+	ResolveServer Resolution = "server"
+	// ResolveClient applies the client mutation over the server state.
+	ResolveClient Resolution = "client"
+	// ResolveMerged sets the memo content to a merge of both.
+	ResolveMerged Resolution = "merged"
+)
+
+// Resolve resolves a conflict of a user and returns the resulting state of
+// the entity. content is the merged content of ResolveMerged.
+func (s *Sync) Resolve(ctx context.Context, userID int32, id int64, resolution Resolution, content string) (*Change, error) {
+	conflict, err := s.store.GetConflict(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if conflict == nil {
+		return nil, ErrConflictNotFound
+	}
+
+	// The client side is applied as a fresh edit of the current state.
+	m := *conflict.Client
+	switch resolution {
+	case ResolveServer:
+	case ResolveMerged:
+		if conflict.Entity != EntityMemo || m.Delete {
+			return nil, fmt.Errorf("%w: only memo edits can be merged", ErrInvalidResolution)
+		}
+		patch := *m.Memo
+		patch.Content = &content
+		m.Memo = &patch
+		fallthrough
+	case ResolveClient:
+		if err := s.force(ctx, userID, &m); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: resolution must be server, client or merged", ErrInvalidResolution)
+	}
+
+	if err := s.store.ResolveConflict(ctx, userID, id, resolution); err != nil {
+		return nil, err
+	}
+	current, err := s.current(ctx, userID, conflict.Entity, conflict.UID)
+	if err != nil || current != nil {
+		return current, err
+	}
+	return &Change{Entity: conflict.Entity, UID: conflict.UID, Deleted: true}, nil
+}
+
+// force applies a mutation over the current state of its entity.
+func (s *Sync) force(ctx context.Context, userID int32, m *Mutation) error {
+	current, err := s.current(ctx, userID, m.Entity, m.UID)
+	if err != nil {
+		return err
+	}
+	m.BaseVersion = 0
+	if current != nil && !current.Deleted {
+		m.BaseVersion = current.Version
+	}
+	plan := PlanMutation(m, current)
+	if plan.Reject != "" {
+		return fmt.Errorf("%w: %s", ErrInvalidResolution, plan.Reject)
+	}
+	return s.write(ctx, userID, m, plan)
+}
+
+// Prune deletes the changes older than ChangeRetention; it is run as a
+// periodic job of the server.
+func (s *Sync) Prune(ctx context.Context) error {
+	return s.store.Prune(ctx, time.Now().Add(-ChangeRetention))
+}
@@ -0,0 +1,357 @@
+// Package pdfdoc makes uploaded PDF attachments searchable and citable by page.
+//
+// New PDF attachments are queued as documents. In the background, each
+// document's text is extracted page by page, split into overlapping chunks
+// that never cross a page boundary, and embedded. Searches return the chunks
+// closest to a query along with their page number, so that agents can cite
+// "file, page N" in their answers.
+package pdfdoc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	agents "github.com/hrygo/divinesense/ai/agents"
+)
+
+// MimeType is the MIME type of the attachments ingested.
+const MimeType = "application/pdf"
+
+// Document statuses. A document moves from PENDING to PROCESSING, then to
+// DONE, FAILED or SKIPPED (over the size or page limit).
+const (
+	StatusPending    = "PENDING"
+	StatusProcessing = "PROCESSING"
+	StatusDone       = "DONE"
+	StatusFailed     = "FAILED"
+	StatusSkipped    = "SKIPPED"
+)
+
+const (
+	// chunkRunes and chunkOverlapRunes size the chunks of a page.
+	chunkRunes        = 800
+	chunkOverlapRunes = 100
+	// embedBatchSize bounds the chunks embedded per request.
+	embedBatchSize = 32
+	// maxSearchLimit bounds the chunks returned by a search.
+	maxSearchLimit = 20
+	// snippetRunes bounds the chunk text returned to agents.
+	snippetRunes = 600
+)
+
+// ToolName is the name of the agent tool searching PDFs.
+const ToolName = "pdf_search"
+
+// Limits bound the documents ingested; larger documents are skipped.
+type Limits struct {
+	MaxSizeBytes int64
+	MaxPages     int
+}
+
+// Document is the ingestion state of a PDF attachment.
+type Document struct {
+	AttachmentID  int32  `json:"attachment_id"`
+	AttachmentUID string `json:"attachment_uid"`
+	CreatorID     int32  `json:"creator_id"`
+	Filename      string `json:"filename"`
+	MemoUID       string `json:"memo_uid,omitempty"`
+	Size          int64  `json:"size"`
+	Status        string `json:"status"`
+	Error         string `json:"error,omitempty"`
+	PageCount     int    `json:"page_count"`
+	ChunkCount    int    `json:"chunk_count"`
+	CreatedTs     int64  `json:"created_ts"`
+	UpdatedTs     int64  `json:"updated_ts"`
+}
+
+// Chunk is a passage of one page of a document.
+type Chunk struct {
+	Page    int    `json:"page"` // 1-based
+	Index   int    `json:"index"`
+	Content string `json:"content"`
+}
+
+// Hit is a chunk found by a search.
+type Hit struct {
+	AttachmentUID string  `json:"attachment_uid"`
+	Filename      string  `json:"filename"`
+	MemoUID       string  `json:"memo_uid,omitempty"`
+	Page          int     `json:"page"`
+	Content       string  `json:"content"`
+	Citation      string  `json:"citation"`
+	Score         float32 `json:"score"`
+}
+
+// Cite renders a page reference, e.g. "《report.pdf》第 3 页".
+func Cite(filename string, page int) string {
+	return fmt.Sprintf("《%s》第 %d 页", filename, page)
+}
+
+// Store persists documents and their chunks; implemented by DBStore.
+type Store interface {
+	// EnqueueNew queues the PDF attachments without a document.
+	EnqueueNew(ctx context.Context) (int, error)
+	// ClaimPending marks up to limit pending documents as processing and returns them.
+	ClaimPending(ctx context.Context, limit int) ([]*Document, error)
+	// Complete replaces the chunks of a document and marks it as done.
+	Complete(ctx context.Context, attachmentID int32, pageCount int, chunks []Chunk, vectors [][]float32, model string) error
+	// Finish marks a document as failed or skipped with a reason.
+	Finish(ctx context.Context, attachmentID int32, status, reason string, pageCount int) error
+	// Requeue marks a document of the user as pending again.
+	Requeue(ctx context.Context, creatorID int32, attachmentUID string) (*Document, error)
+	ListDocuments(ctx context.Context, creatorID int32) ([]*Document, error)
+	GetDocument(ctx context.Context, creatorID int32, attachmentUID string) (*Document, error)
+	// Search returns the chunks of the user's documents closest to vector.
+	Search(ctx context.Context, creatorID int32, vector []float32, model string, limit int) ([]*Hit, error)
+}
+
+// Extractor extracts the text of a PDF page by page.
+type Extractor interface {
+	ExtractPages(ctx context.Context, data []byte, contentType string) ([]string, error)
+}
+
+// Embedder embeds texts; satisfied by ai.EmbeddingService.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+	EmbedBatch(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// BlobLoader returns the content of an attachment.
+type BlobLoader func(ctx context.Context, attachmentID int32) ([]byte, error)
+
+// Library ingests and searches the PDF documents of all users.
+type Library struct {
+	store     Store
+	extractor Extractor
+	embedder  Embedder
+	load      BlobLoader
+	model     string
+	limits    Limits
+}
+
+// NewLibrary creates a library. model names the embedding model of embedder.
+func NewLibrary(store Store, extractor Extractor, embedder Embedder, load BlobLoader, model string, limits Limits) *Library {
+	return &Library{store: store, extractor: extractor, embedder: embedder, load: load, model: model, limits: limits}
+}
+
+// List returns the documents of a user with their ingestion status.
+func (l *Library) List(ctx context.Context, userID int32) ([]*Document, error) {
+	return l.store.ListDocuments(ctx, userID)
+}
+
+// Get returns the document of an attachment of the user, or nil.
+func (l *Library) Get(ctx context.Context, userID int32, attachmentUID string) (*Document, error) {
+	return l.store.GetDocument(ctx, userID, attachmentUID)
+}
+
+// Reprocess queues a document of the user again, or returns nil if it has
+// none. Documents being processed are left alone.
+func (l *Library) Reprocess(ctx context.Context, userID int32, attachmentUID string) (*Document, error) {
+	return l.store.Requeue(ctx, userID, attachmentUID)
+}
+
+// Search returns the chunks of the user's documents most related to query.
+func (l *Library) Search(ctx context.Context, userID int32, query string, limit int) ([]*Hit, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if limit <= 0 || limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	vector, err := l.embedder.Embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+	hits, err := l.store.Search(ctx, userID, vector, l.model, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, hit := range hits {
+		hit.Citation = Cite(hit.Filename, hit.Page)
+	}
+	return hits, nil
+}
+
+// Process ingests a claimed document. Errors are recorded on the document.
+func (l *Library) Process(ctx context.Context, doc *Document) error {
+	if l.limits.MaxSizeBytes > 0 && doc.Size > l.limits.MaxSizeBytes {
+		reason := fmt.Sprintf("file exceeds %d MB", l.limits.MaxSizeBytes>>20)
+		return l.store.Finish(ctx, doc.AttachmentID, StatusSkipped, reason, 0)
+	}
+
+	pageCount, err := l.ingest(ctx, doc)
+	if err == nil {
+		return nil
+	}
+	status := StatusFailed
+	if errors.As(err, new(skipError)) {
+		status = StatusSkipped
+	}
+	if finishErr := l.store.Finish(ctx, doc.AttachmentID, status, err.Error(), pageCount); finishErr != nil {
+		return finishErr
+	}
+	if status == StatusSkipped {
+		return nil
+	}
+	return err
+}
+
+// skipError reports a document that is not ingested by design.
+type skipError string
+
+func (e skipError) Error() string { return string(e) }
+
+func (l *Library) ingest(ctx context.Context, doc *Document) (int, error) {
+	data, err := l.load(ctx, doc.AttachmentID)
+	if err != nil {
+		return 0, fmt.Errorf("load file: %w", err)
+	}
+	pages, err := l.extractor.ExtractPages(ctx, data, MimeType)
+	if err != nil {
+		return 0, fmt.Errorf("extract text: %w", err)
+	}
+	if l.limits.MaxPages > 0 && len(pages) > l.limits.MaxPages {
+		return len(pages), skipError(fmt.Sprintf("document exceeds %d pages", l.limits.MaxPages))
+	}
+
+	chunks := ChunkPages(pages)
+	if len(chunks) == 0 {
+		return len(pages), skipError("no text found (scanned PDF?)")
+	}
+	vectors := make([][]float32, 0, len(chunks))
+	for start := 0; start < len(chunks); start += embedBatchSize {
+		end := min(start+embedBatchSize, len(chunks))
+		texts := make([]string, 0, end-start)
+		for _, chunk := range chunks[start:end] {
+			texts = append(texts, chunk.Content)
+		}
+		batch, err := l.embedder.EmbedBatch(ctx, texts)
+		if err != nil {
+			return len(pages), fmt.Errorf("embed chunks: %w", err)
+		}
+		if len(batch) != len(texts) {
+			return len(pages), fmt.Errorf("embed chunks: got %d vectors for %d chunks", len(batch), len(texts))
+		}
+		vectors = append(vectors, batch...)
+	}
+	return len(pages), l.store.Complete(ctx, doc.AttachmentID, len(pages), chunks, vectors, l.model)
+}
+
+// ChunkPages splits pages into chunks of about chunkRunes runes, overlapping
+// by chunkOverlapRunes. Chunks never span two pages; blank pages have none.
+func ChunkPages(pages []string) []Chunk {
+	var chunks []Chunk
+	for i, page := range pages {
+		for _, content := range chunkText(strings.TrimSpace(page), chunkRunes, chunkOverlapRunes) {
+			chunks = append(chunks, Chunk{Page: i + 1, Index: len(chunks), Content: content})
+		}
+	}
+	return chunks
+}
+
+// chunkText splits text into pieces of at most size runes, preferring to end
+// a piece after a sentence or line, each starting overlap runes before the
+// end of the previous one.
+func chunkText(text string, size, overlap int) []string {
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return nil
+	}
+	var pieces []string
+	for start := 0; ; {
+		end := start + size
+		if end >= len(runes) {
+			if piece := strings.TrimSpace(string(runes[start:])); piece != "" {
+				pieces = append(pieces, piece)
+			}
+			return pieces
+		}
+		end = breakPoint(runes, start+size/2, end)
+		if piece := strings.TrimSpace(string(runes[start:end])); piece != "" {
+			pieces = append(pieces, piece)
+		}
+		start = max(end-overlap, start+1)
+	}
+}
+
+// breakPoint returns the position after the last sentence end or line break
+// in runes[from:to], or after the last space, or to.
+func breakPoint(runes []rune, from, to int) int {
+	for i := to - 1; i >= from; i-- {
+		switch runes[i] {
+		case '\n', '。', '！', '？', '；', '.', '!', '?', ';':
+			return i + 1
+		}
+	}
+	for i := to - 1; i >= from; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return i + 1
+		}
+	}
+	return to
+}
+
+// ToolFor returns the pdf_search tool of a user, or nil without a library.
+func (l *Library) ToolFor(userID int32) agents.ToolWithSchema {
+	if l == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		ToolName,
+		"Search the text of the user's uploaded PDF files and return matching passages with their file name and page number. "+
+			"Cite every fact taken from a passage with its file and page, e.g. 《report.pdf》第 3 页. "+
+			`Input: {"query": "...", "limit": 5}.`,
+		func(ctx context.Context, input string) (string, error) {
+			var args struct {
+				Query string `json:"query"`
+				Limit int    `json:"limit"`
+			}
+			if err := json.Unmarshal([]byte(input), &args); err != nil {
+				return "", fmt.Errorf("invalid input: %w", err)
+			}
+			if args.Limit <= 0 {
+				args.Limit = 5
+			}
+			hits, err := l.Search(ctx, userID, args.Query, args.Limit)
+			if err != nil {
+				return "", err
+			}
+			return FormatHits(hits), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"query": map[string]any{"type": "string", "description": "What to look for in the PDFs"},
+				"limit": map[string]any{"type": "integer", "description": fmt.Sprintf("Number of passages (at most %d)", maxSearchLimit)},
+			},
+			"required": []string{"query"},
+		},
+	)
+}
+
+// FormatHits renders hits for agents, each headed by its citation.
+func FormatHits(hits []*Hit) string {
+	if len(hits) == 0 {
+		return "No matching passage in the user's PDF files."
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Found %d passage(s) in the user's PDF files:\n\n", len(hits))
+	for i, hit := range hits {
+		fmt.Fprintf(&b, "[%d] %s", i+1, hit.Citation)
+		if hit.MemoUID != "" {
+			fmt.Fprintf(&b, " (memo UID: %s)", hit.MemoUID)
+		}
+		content := hit.Content
+		if utf8.RuneCountInString(content) > snippetRunes {
+			content = string([]rune(content)[:snippetRunes]) + "…"
+		}
+		fmt.Fprintf(&b, "\n%s\n\n", content)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,142 @@
+package pdfdoc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkPages(t *testing.T) {
+	long := strings.Repeat("这是一个很长的句子，用于测试分块。", 80) // 1360 runes
+	chunks := ChunkPages([]string{"第一页内容", "  \n ", long})
+
+	require.Greater(t, len(chunks), 2)
+	assert.Equal(t, Chunk{Page: 1, Index: 0, Content: "第一页内容"}, chunks[0])
+	for i, chunk := range chunks[1:] {
+		assert.Equal(t, 3, chunk.Page, "blank pages have no chunk")
+		assert.Equal(t, i+1, chunk.Index)
+		assert.LessOrEqual(t, utf8.RuneCountInString(chunk.Content), chunkRunes)
+		assert.True(t, strings.HasSuffix(chunk.Content, "。"), "chunks end after a sentence")
+	}
+	// Consecutive chunks overlap.
+	assert.True(t, strings.HasPrefix(long, chunks[1].Content))
+	tail := []rune(chunks[1].Content)
+	assert.Contains(t, chunks[2].Content, string(tail[len(tail)-20:]))
+}
+
+func TestChunkTextWithoutBreaks(t *testing.T) {
+	pieces := chunkText(strings.Repeat("a", 25), 10, 2)
+	assert.Equal(t, []string{"aaaaaaaaaa", "aaaaaaaaaa", "aaaaaaaaa"}, pieces)
+	assert.Nil(t, chunkText("", 10, 2))
+}
+
+type fakeStore struct {
+	Store
+	finished map[int32]string
+	reasons  map[int32]string
+	chunks   map[int32][]Chunk
+	hits     []*Hit
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{finished: map[int32]string{}, reasons: map[int32]string{}, chunks: map[int32][]Chunk{}}
+}
+
+func (s *fakeStore) Complete(_ context.Context, id int32, _ int, chunks []Chunk, vectors [][]float32, _ string) error {
+	if len(chunks) != len(vectors) {
+		return errors.New("vector count mismatch")
+	}
+	s.finished[id] = StatusDone
+	s.chunks[id] = chunks
+	return nil
+}
+
+func (s *fakeStore) Finish(_ context.Context, id int32, status, reason string, _ int) error {
+	s.finished[id], s.reasons[id] = status, reason
+	return nil
+}
+
+func (s *fakeStore) Search(context.Context, int32, []float32, string, int) ([]*Hit, error) {
+	return s.hits, nil
+}
+
+type fakeExtractor map[string][]string
+
+func (e fakeExtractor) ExtractPages(_ context.Context, data []byte, _ string) ([]string, error) {
+	pages, ok := e[string(data)]
+	if !ok {
+		return nil, errors.New("corrupt pdf")
+	}
+	return pages, nil
+}
+
+type fakeEmbedder struct{ calls int }
+
+func (e *fakeEmbedder) Embed(context.Context, string) ([]float32, error) { return []float32{1}, nil }
+
+func (e *fakeEmbedder) EmbedBatch(_ context.Context, texts []string) ([][]float32, error) {
+	e.calls++
+	return make([][]float32, len(texts)), nil
+}
+
+func TestProcess(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+	embedder := &fakeEmbedder{}
+	blobs := map[int32]string{1: "ok", 2: "big", 3: "scan", 4: "bad"}
+	manyPages := make([]string, 40)
+	for i := range manyPages {
+		manyPages[i] = "page"
+	}
+	extractor := fakeExtractor{"ok": manyPages[:35], "big": append(manyPages, "x"), "scan": {"", " "}}
+	library := NewLibrary(store, extractor, embedder,
+		func(_ context.Context, id int32) ([]byte, error) { return []byte(blobs[id]), nil },
+		"model", Limits{MaxSizeBytes: 1 << 20, MaxPages: 40})
+
+	require.NoError(t, library.Process(ctx, &Document{AttachmentID: 1}))
+	assert.Equal(t, StatusDone, store.finished[1])
+	assert.Len(t, store.chunks[1], 35)
+	assert.Equal(t, 2, embedder.calls, "chunks are embedded in batches")
+
+	require.NoError(t, library.Process(ctx, &Document{AttachmentID: 2}))
+	assert.Equal(t, StatusSkipped, store.finished[2])
+	assert.Equal(t, "document exceeds 40 pages", store.reasons[2])
+
+	require.NoError(t, library.Process(ctx, &Document{AttachmentID: 3}))
+	assert.Equal(t, StatusSkipped, store.finished[3])
+
+	assert.Error(t, library.Process(ctx, &Document{AttachmentID: 4}))
+	assert.Equal(t, StatusFailed, store.finished[4])
+	assert.Contains(t, store.reasons[4], "corrupt pdf")
+
+	require.NoError(t, library.Process(ctx, &Document{AttachmentID: 5, Size: 2 << 20}))
+	assert.Equal(t, StatusSkipped, store.finished[5])
+	assert.Equal(t, "file exceeds 1 MB", store.reasons[5])
+}
+
+func TestSearchCitations(t *testing.T) {
+	store := newFakeStore()
+	store.hits = []*Hit{
+		{Filename: "report.pdf", Page: 3, Content: "营收增长 20%", MemoUID: "m1"},
+		{Filename: "spec.pdf", Page: 12, Content: strings.Repeat("长", snippetRunes+10)},
+	}
+	library := NewLibrary(store, nil, &fakeEmbedder{}, nil, "model", Limits{})
+
+	hits, err := library.Search(context.Background(), 1, "营收", 5)
+	require.NoError(t, err)
+	assert.Equal(t, "《report.pdf》第 3 页", hits[0].Citation)
+
+	text := FormatHits(hits)
+	assert.Contains(t, text, "[1] 《report.pdf》第 3 页 (memo UID: m1)\n营收增长 20%")
+	assert.Contains(t, text, "[2] 《spec.pdf》第 12 页\n")
+	assert.Contains(t, text, "…")
+
+	_, err = library.Search(context.Background(), 1, "  ", 5)
+	assert.Error(t, err)
+	assert.Equal(t, "No matching passage in the user's PDF files.", FormatHits(nil))
+}
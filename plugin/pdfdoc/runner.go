@@ -0,0 +1,68 @@
+package pdfdoc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	// runInterval is how often the runner looks for new PDFs.
+	runInterval = time.Minute
+	// runBatchSize bounds the documents claimed per run.
+	runBatchSize = 5
+	// processTimeout bounds the ingestion of one document.
+	processTimeout = 10 * time.Minute
+)
+
+// Run ingests new PDF attachments until ctx is canceled. Several instances
+// may run on the same database: a document is claimed by one of them.
+func (l *Library) Run(ctx context.Context) {
+	ticker := time.NewTicker(runInterval)
+	defer ticker.Stop()
+	for {
+		l.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce queues new PDF attachments and ingests the pending documents,
+// returning how many were processed.
+func (l *Library) RunOnce(ctx context.Context) int {
+	if queued, err := l.store.EnqueueNew(ctx); err != nil {
+		slog.Warn("pdf ingestion: failed to queue new documents", "error", err)
+	} else if queued > 0 {
+		slog.Info("pdf ingestion: documents queued", "count", queued)
+	}
+
+	processed := 0
+	for ctx.Err() == nil {
+		docs, err := l.store.ClaimPending(ctx, runBatchSize)
+		if err != nil {
+			slog.Warn("pdf ingestion: failed to claim documents", "error", err)
+			return processed
+		}
+		if len(docs) == 0 {
+			return processed
+		}
+		for _, doc := range docs {
+			l.process(ctx, doc)
+			processed++
+		}
+	}
+	return processed
+}
+
+func (l *Library) process(ctx context.Context, doc *Document) {
+	ctx, cancel := context.WithTimeout(ctx, processTimeout)
+	defer cancel()
+	if err := l.Process(ctx, doc); err != nil {
+		slog.Warn("pdf ingestion: document failed", "attachment_id", doc.AttachmentID, "error", err)
+		return
+	}
+	slog.Info("pdf ingestion: document processed", "attachment_id", doc.AttachmentID)
+}
@@ -0,0 +1,221 @@
+package pdfdoc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pgvector/pgvector-go"
+)
+
+// staleProcessingAfter is when a document left processing (e.g. by a crashed
+// instance) can be claimed again.
+const staleProcessingAfter = 30 * time.Minute
+
+// DBStore persists documents in the pdf_document and pdf_chunk tables (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new document store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// documentSelect selects the columns of documents aliased d.
+const documentSelect = `
+	SELECT d.attachment_id, a.uid, d.creator_id, a.filename, COALESCE(m.uid, ''), a.size,
+		d.status, d.error, d.page_count, d.chunk_count, d.created_ts, d.updated_ts`
+
+const documentJoins = `
+	JOIN attachment a ON a.id = d.attachment_id
+	LEFT JOIN memo m ON m.id = a.memo_id`
+
+// EnqueueNew implements Store.
+func (s *DBStore) EnqueueNew(ctx context.Context) (int, error) {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO pdf_document (attachment_id, creator_id, created_ts, updated_ts)
+		SELECT a.id, a.creator_id, $1, $1
+		FROM attachment a
+		WHERE a.type = $2 AND a.row_status = 'NORMAL'
+			AND NOT EXISTS (SELECT 1 FROM pdf_document d WHERE d.attachment_id = a.id)
+		ON CONFLICT (attachment_id) DO NOTHING`,
+		time.Now().Unix(), MimeType)
+	if err != nil {
+		return 0, fmt.Errorf("failed to queue pdf documents: %w", err)
+	}
+	queued, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(queued), nil
+}
+
+// ClaimPending implements Store. Rows locked by another runner are skipped.
+func (s *DBStore) ClaimPending(ctx context.Context, limit int) ([]*Document, error) {
+	now := time.Now()
+	rows, err := s.db.QueryContext(ctx, `
+		WITH d AS (
+			UPDATE pdf_document SET status = $1, updated_ts = $2
+			WHERE attachment_id IN (
+				SELECT attachment_id FROM pdf_document
+				WHERE status = $3 OR (status = $1 AND updated_ts < $4)
+				ORDER BY updated_ts
+				LIMIT $5
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)`+documentSelect+`
+		FROM d`+documentJoins,
+		StatusProcessing, now.Unix(), StatusPending, now.Add(-staleProcessingAfter).Unix(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pdf documents: %w", err)
+	}
+	return scanDocuments(rows)
+}
+
+// Complete implements Store.
+func (s *DBStore) Complete(ctx context.Context, attachmentID int32, pageCount int, chunks []Chunk, vectors [][]float32, model string) error {
+	if len(chunks) != len(vectors) {
+		return fmt.Errorf("got %d vectors for %d chunks", len(vectors), len(chunks))
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM pdf_chunk WHERE attachment_id = $1", attachmentID); err != nil {
+		return fmt.Errorf("failed to delete pdf chunks: %w", err)
+	}
+	for i, chunk := range chunks {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO pdf_chunk (attachment_id, creator_id, page, chunk_index, content, embedding, model)
+			SELECT attachment_id, creator_id, $2, $3, $4, $5, $6 FROM pdf_document WHERE attachment_id = $1`,
+			attachmentID, chunk.Page, chunk.Index, chunk.Content, pgvector.NewVector(vectors[i]), model); err != nil {
+			return fmt.Errorf("failed to insert pdf chunk: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE pdf_document SET status = $2, error = '', page_count = $3, chunk_count = $4, updated_ts = $5
+		WHERE attachment_id = $1`,
+		attachmentID, StatusDone, pageCount, len(chunks), time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to update pdf document: %w", err)
+	}
+	return tx.Commit()
+}
+
+// Finish implements Store. The chunks of a previous ingestion are dropped.
+func (s *DBStore) Finish(ctx context.Context, attachmentID int32, status, reason string, pageCount int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM pdf_chunk WHERE attachment_id = $1", attachmentID); err != nil {
+		return fmt.Errorf("failed to delete pdf chunks: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE pdf_document SET status = $2, error = $3, page_count = $4, chunk_count = 0, updated_ts = $5
+		WHERE attachment_id = $1`,
+		attachmentID, status, reason, pageCount, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to update pdf document: %w", err)
+	}
+	return tx.Commit()
+}
+
+// Requeue implements Store.
+func (s *DBStore) Requeue(ctx context.Context, creatorID int32, attachmentUID string) (*Document, error) {
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE pdf_document d SET status = $1, error = '', updated_ts = $2
+		FROM attachment a
+		WHERE a.id = d.attachment_id AND a.uid = $3 AND d.creator_id = $4 AND d.status <> $5`,
+		StatusPending, time.Now().Unix(), attachmentUID, creatorID, StatusProcessing); err != nil {
+		return nil, fmt.Errorf("failed to requeue pdf document: %w", err)
+	}
+	return s.GetDocument(ctx, creatorID, attachmentUID)
+}
+
+// ListDocuments implements Store.
+func (s *DBStore) ListDocuments(ctx context.Context, creatorID int32) ([]*Document, error) {
+	rows, err := s.db.QueryContext(ctx, documentSelect+`
+		FROM pdf_document d`+documentJoins+`
+		WHERE d.creator_id = $1 AND a.row_status = 'NORMAL'
+		ORDER BY d.created_ts DESC`, creatorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pdf documents: %w", err)
+	}
+	return scanDocuments(rows)
+}
+
+// GetDocument implements Store.
+func (s *DBStore) GetDocument(ctx context.Context, creatorID int32, attachmentUID string) (*Document, error) {
+	row := s.db.QueryRowContext(ctx, documentSelect+`
+		FROM pdf_document d`+documentJoins+`
+		WHERE d.creator_id = $1 AND a.uid = $2`, creatorID, attachmentUID)
+	doc, err := scanDocument(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pdf document: %w", err)
+	}
+	return doc, nil
+}
+
+// Search implements Store.
+func (s *DBStore) Search(ctx context.Context, creatorID int32, vector []float32, model string, limit int) ([]*Hit, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT a.uid, a.filename, COALESCE(m.uid, ''), c.page, c.content, 1 - (c.embedding <=> $1) AS score
+		FROM pdf_chunk c
+		JOIN pdf_document d ON d.attachment_id = c.attachment_id AND d.status = $4
+		JOIN attachment a ON a.id = c.attachment_id AND a.row_status = 'NORMAL'
+		LEFT JOIN memo m ON m.id = a.memo_id
+		WHERE c.creator_id = $2 AND c.model = $3
+		ORDER BY c.embedding <=> $1
+		LIMIT $5`,
+		pgvector.NewVector(vector), creatorID, model, StatusDone, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search pdf chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []*Hit
+	for rows.Next() {
+		hit := &Hit{}
+		if err := rows.Scan(&hit.AttachmentUID, &hit.Filename, &hit.MemoUID, &hit.Page, &hit.Content, &hit.Score); err != nil {
+			return nil, fmt.Errorf("failed to scan pdf chunk: %w", err)
+		}
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDocument(row rowScanner) (*Document, error) {
+	doc := &Document{}
+	if err := row.Scan(&doc.AttachmentID, &doc.AttachmentUID, &doc.CreatorID, &doc.Filename, &doc.MemoUID, &doc.Size,
+		&doc.Status, &doc.Error, &doc.PageCount, &doc.ChunkCount, &doc.CreatedTs, &doc.UpdatedTs); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func scanDocuments(rows *sql.Rows) ([]*Document, error) {
+	defer rows.Close()
+	var docs []*Document
+	for rows.Next() {
+		doc, err := scanDocument(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pdf document: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
@@ -0,0 +1,163 @@
+// Package quiethours holds the per-user quiet hours: a daily time window in
+// the user's timezone during which background jobs leave the user alone.
+//
+// Scheduled messages, read-later digests and knowledge-base health reports
+// due during quiet hours wait until they end, and notifications are not sent.
+// Scheduled messages marked urgent are sent regardless.
+package quiethours
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Settings are the quiet hours of a user.
+type Settings struct {
+	UserID  int32 `json:"-"`
+	Enabled bool  `json:"enabled"`
+	// Start and End are local times of day ("HH:MM"). The window includes Start
+	// and excludes End, and spans midnight when End is before Start.
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Timezone is the IANA timezone of Start and End.
+	Timezone string `json:"timezone"`
+}
+
+// DefaultSettings returns the settings of users who saved none.
+func DefaultSettings(userID int32) *Settings {
+	return &Settings{UserID: userID, Start: "22:00", End: "07:00", Timezone: "UTC"}
+}
+
+// Validate checks settings about to be saved.
+func (s *Settings) Validate() error {
+	start, err := ParseClock(s.Start)
+	if err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+	end, err := ParseClock(s.End)
+	if err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+	if start == end {
+		return fmt.Errorf("start and end must differ")
+	}
+	// Local is the server's timezone, which the database does not know by name.
+	if s.Timezone == "" || s.Timezone == "Local" {
+		return fmt.Errorf("timezone is required")
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q", s.Timezone)
+	}
+	return nil
+}
+
+// QuietUntil returns the end of the quiet hours t falls in, or the zero time
+// if t is outside quiet hours or they are disabled.
+func (s *Settings) QuietUntil(t time.Time) time.Time {
+	if !s.Enabled {
+		return time.Time{}
+	}
+	start, err := ParseClock(s.Start)
+	if err != nil {
+		return time.Time{}
+	}
+	end, err := ParseClock(s.End)
+	if err != nil {
+		return time.Time{}
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.Time{}
+	}
+
+	local := t.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	var quiet bool
+	if start < end {
+		quiet = minute >= start && minute < end
+	} else {
+		quiet = minute >= start || minute < end
+	}
+	if !quiet {
+		return time.Time{}
+	}
+	until := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, loc)
+	if !until.After(t) {
+		until = time.Date(local.Year(), local.Month(), local.Day()+1, end/60, end%60, 0, 0, loc)
+	}
+	return until
+}
+
+// ParseClock parses a time of day ("HH:MM") into minutes since midnight.
+func ParseClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a time of day (HH:MM)", clock)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// FormatClock formats minutes since midnight as a time of day ("HH:MM").
+func FormatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// Store persists quiet hours.
+type Store interface {
+	// GetSettings returns the settings of a user, or the defaults.
+	GetSettings(ctx context.Context, userID int32) (*Settings, error)
+	SaveSettings(ctx context.Context, settings *Settings) (*Settings, error)
+}
+
+// Hours gives access to the quiet hours of users.
+type Hours struct {
+	store Store
+}
+
+// New creates quiet hours backed by store.
+func New(store Store) *Hours {
+	return &Hours{store: store}
+}
+
+// GetSettings returns the settings of a user.
+func (h *Hours) GetSettings(ctx context.Context, userID int32) (*Settings, error) {
+	return h.store.GetSettings(ctx, userID)
+}
+
+// SaveSettings validates and saves the settings of a user.
+func (h *Hours) SaveSettings(ctx context.Context, settings *Settings) (*Settings, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	return h.store.SaveSettings(ctx, settings)
+}
+
+// QuietUntil returns the end of the user's current quiet hours, or the zero
+// time if the user is not in quiet hours at now. Nil Hours are never quiet.
+func (h *Hours) QuietUntil(ctx context.Context, userID int32, now time.Time) (time.Time, error) {
+	if h == nil {
+		return time.Time{}, nil
+	}
+	settings, err := h.store.GetSettings(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return settings.QuietUntil(now), nil
+}
+
+// NotQuietSQL returns a PostgreSQL condition that holds unless the user whose
+// ID is in userColumn is in quiet hours at the Unix time of nowParam. It is
+// the SQL counterpart of Settings.QuietUntil, used by the claims of background
+// jobs. userColumn must be qualified with its table name.
+func NotQuietSQL(userColumn, nowParam string) string {
+	return `NOT EXISTS (
+			SELECT 1 FROM user_quiet_hours qh,
+				LATERAL (SELECT TO_TIMESTAMP(` + nowParam + `::BIGINT) AT TIME ZONE qh.timezone AS local) l,
+				LATERAL (SELECT (EXTRACT(HOUR FROM l.local) * 60 + EXTRACT(MINUTE FROM l.local))::INTEGER AS minute) m
+			WHERE qh.user_id = ` + userColumn + ` AND qh.enabled AND CASE
+				WHEN qh.start_minute < qh.end_minute THEN m.minute >= qh.start_minute AND m.minute < qh.end_minute
+				ELSE m.minute >= qh.start_minute OR m.minute < qh.end_minute
+			END
+		)`
+}
@@ -0,0 +1,91 @@
+package quiethours
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, DefaultSettings(1).Validate())
+	require.NoError(t, (&Settings{Start: "13:00", End: "14:30", Timezone: "Asia/Shanghai"}).Validate())
+
+	for _, invalid := range []*Settings{
+		{Start: "22:00", End: "22:00", Timezone: "UTC"},
+		{Start: "24:00", End: "07:00", Timezone: "UTC"},
+		{Start: "10pm", End: "07:00", Timezone: "UTC"},
+		{Start: "22:00", End: "07:00"},
+		{Start: "22:00", End: "07:00", Timezone: "Local"},
+		{Start: "22:00", End: "07:00", Timezone: "Mars/Olympus"},
+	} {
+		assert.Error(t, invalid.Validate(), "%+v", invalid)
+	}
+}
+
+func TestQuietUntil(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, shanghai)
+	}
+
+	// Spanning midnight, in the user's timezone.
+	night := &Settings{Enabled: true, Start: "22:00", End: "07:00", Timezone: "Asia/Shanghai"}
+	assert.Equal(t, at(17, 7, 0), night.QuietUntil(at(16, 22, 0)).In(shanghai))
+	assert.Equal(t, at(17, 7, 0), night.QuietUntil(at(17, 6, 59)).In(shanghai))
+	assert.Equal(t, at(17, 7, 0), night.QuietUntil(at(16, 23, 0).UTC()).In(shanghai))
+	assert.True(t, night.QuietUntil(at(17, 7, 0)).IsZero())
+	assert.True(t, night.QuietUntil(at(16, 21, 59)).IsZero())
+
+	// Within a day.
+	nap := &Settings{Enabled: true, Start: "13:00", End: "14:00", Timezone: "Asia/Shanghai"}
+	assert.Equal(t, at(16, 14, 0), nap.QuietUntil(at(16, 13, 30)).In(shanghai))
+	assert.True(t, nap.QuietUntil(at(16, 12, 0)).IsZero())
+	assert.True(t, nap.QuietUntil(at(16, 14, 0)).IsZero())
+
+	night.Enabled = false
+	assert.True(t, night.QuietUntil(at(16, 23, 0)).IsZero())
+}
+
+type fakeStore struct {
+	settings map[int32]*Settings
+}
+
+func (s *fakeStore) GetSettings(_ context.Context, userID int32) (*Settings, error) {
+	if settings, ok := s.settings[userID]; ok {
+		return settings, nil
+	}
+	return DefaultSettings(userID), nil
+}
+
+func (s *fakeStore) SaveSettings(_ context.Context, settings *Settings) (*Settings, error) {
+	s.settings[settings.UserID] = settings
+	return settings, nil
+}
+
+func TestHours(t *testing.T) {
+	ctx := context.Background()
+	hours := New(&fakeStore{settings: map[int32]*Settings{}})
+	now := time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC)
+
+	// Quiet hours are off until the user enables them.
+	until, err := hours.QuietUntil(ctx, 1, now)
+	require.NoError(t, err)
+	assert.True(t, until.IsZero())
+
+	_, err = hours.SaveSettings(ctx, &Settings{UserID: 1, Enabled: true, Start: "22:00", End: "22:00", Timezone: "UTC"})
+	require.Error(t, err)
+	_, err = hours.SaveSettings(ctx, &Settings{UserID: 1, Enabled: true, Start: "22:00", End: "07:00", Timezone: "UTC"})
+	require.NoError(t, err)
+	until, err = hours.QuietUntil(ctx, 1, now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 17, 7, 0, 0, 0, time.UTC), until.UTC())
+
+	var none *Hours
+	until, err = none.QuietUntil(ctx, 1, now)
+	require.NoError(t, err)
+	assert.True(t, until.IsZero())
+}
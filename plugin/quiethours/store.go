@@ -0,0 +1,76 @@
+package quiethours
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DBStore persists quiet hours in the user_quiet_hours table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new quiet hours store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const settingColumns = "user_id, enabled, start_minute, end_minute, timezone"
+
+// GetSettings returns the settings of a user, or the defaults if none were saved.
+func (s *DBStore) GetSettings(ctx context.Context, userID int32) (*Settings, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+settingColumns+" FROM user_quiet_hours WHERE user_id = $1", userID)
+	settings, err := scanSettings(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return DefaultSettings(userID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiet hours: %w", err)
+	}
+	return settings, nil
+}
+
+// SaveSettings creates or updates the settings of a user. Settings must be valid.
+func (s *DBStore) SaveSettings(ctx context.Context, settings *Settings) (*Settings, error) {
+	start, err := ParseClock(settings.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := ParseClock(settings.End)
+	if err != nil {
+		return nil, err
+	}
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO user_quiet_hours (user_id, enabled, start_minute, end_minute, timezone, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (user_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			start_minute = EXCLUDED.start_minute,
+			end_minute = EXCLUDED.end_minute,
+			timezone = EXCLUDED.timezone,
+			updated_ts = EXCLUDED.updated_ts
+		RETURNING `+settingColumns,
+		settings.UserID, settings.Enabled, start, end, settings.Timezone, time.Now().Unix())
+	saved, err := scanSettings(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save quiet hours: %w", err)
+	}
+	return saved, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSettings(row rowScanner) (*Settings, error) {
+	settings := &Settings{}
+	var start, end int
+	if err := row.Scan(&settings.UserID, &settings.Enabled, &start, &end, &settings.Timezone); err != nil {
+		return nil, err
+	}
+	settings.Start, settings.End = FormatClock(start), FormatClock(end)
+	return settings, nil
+}
@@ -0,0 +1,354 @@
+// Package readlater is the read-later queue: memos, typically pages captured
+// by the browser extension, that the user wants to read later.
+//
+// Queued items are scored by a language model against the interests the user
+// states in their Settings, so the queue lists the most relevant items first.
+// Items can be snoozed, dismissed or marked as read, and the top of the queue
+// is delivered periodically as a digest, as a memo or through webhooks.
+// Other features add sections to the digest, such as the streaks of the
+// user's habits.
+package readlater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/hrygo/divinesense/plugin/i18n"
+)
+
+// ErrInvalidSnooze is returned when a snooze time is in the past or too far.
+var ErrInvalidSnooze = errors.New("snooze time must be within a year from now")
+
+// Status is the state of a queued item.
+type Status string
+
+const (
+	StatusQueued    Status = "QUEUED"
+	StatusRead      Status = "READ"
+	StatusDismissed Status = "DISMISSED"
+)
+
+// Valid reports whether s is a known status.
+func (s Status) Valid() bool {
+	return s == StatusQueued || s == StatusRead || s == StatusDismissed
+}
+
+// Delivery is how a digest reaches the user.
+type Delivery string
+
+const (
+	// DeliveryMemo saves the digest as a private memo.
+	DeliveryMemo Delivery = "MEMO"
+	// DeliveryWebhook posts the digest to the user's webhooks.
+	DeliveryWebhook Delivery = "WEBHOOK"
+)
+
+// DigestTag tags digest memos.
+const DigestTag = "read-later"
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+	// maxSnooze bounds how long an item can be snoozed.
+	maxSnooze = 365 * 24 * time.Hour
+)
+
+// Item is a memo in the read-later queue.
+type Item struct {
+	MemoID    int32  `json:"-"`
+	MemoUID   string `json:"memo_uid"`
+	CreatorID int32  `json:"-"`
+	// Title is the title of the captured page, or of the memo.
+	Title  string `json:"title"`
+	URL    string `json:"url,omitempty"`
+	Status Status `json:"status"`
+	// Score is the interest score from 0 to 100; nil until scored.
+	Score        *int   `json:"score"`
+	Reason       string `json:"reason"`
+	SnoozedUntil int64  `json:"snoozed_until"`
+	CreatedTs    int64  `json:"created_ts"`
+	UpdatedTs    int64  `json:"updated_ts"`
+	// Content is the memo content, given to the scorer.
+	Content string `json:"-"`
+}
+
+// Find selects the items of a user.
+type Find struct {
+	CreatorID int32
+	Status    Status
+	// IncludeSnoozed lists the items snoozed past Now as well.
+	IncludeSnoozed bool
+	Now            time.Time
+	Limit          int
+}
+
+// Settings are the per-user queue settings.
+type Settings struct {
+	UserID int32 `json:"-"`
+	// Interests describe what the user wants to read about, in their words.
+	// Items are not scored without interests.
+	Interests string `json:"interests"`
+	// DigestEnabled turns the periodic digest on. Defaults to false.
+	DigestEnabled bool     `json:"digest_enabled"`
+	Delivery      Delivery `json:"delivery"`
+	// DigestDays is the number of days between two digests.
+	DigestDays int `json:"digest_days"`
+	// DigestSize is the number of items of a digest.
+	DigestSize int `json:"digest_size"`
+	// LastDigestTs is the time of the last scheduled digest; read-only.
+	LastDigestTs int64 `json:"last_digest_ts"`
+}
+
+// DefaultSettings returns the settings of users who saved none.
+func DefaultSettings(userID int32) *Settings {
+	return &Settings{UserID: userID, Delivery: DeliveryMemo, DigestDays: 1, DigestSize: 5}
+}
+
+// Validate checks settings about to be saved.
+func (s *Settings) Validate() error {
+	s.Interests = strings.TrimSpace(s.Interests)
+	if len([]rune(s.Interests)) > 2000 {
+		return fmt.Errorf("interests must be at most 2000 characters")
+	}
+	if s.Delivery != DeliveryMemo && s.Delivery != DeliveryWebhook {
+		return fmt.Errorf("delivery must be MEMO or WEBHOOK")
+	}
+	if s.DigestDays < 1 || s.DigestDays > 30 {
+		return fmt.Errorf("digest_days must be between 1 and 30")
+	}
+	if s.DigestSize < 1 || s.DigestSize > 20 {
+		return fmt.Errorf("digest_size must be between 1 and 20")
+	}
+	return nil
+}
+
+// Score is the interest score of an item.
+type Score struct {
+	MemoID int32  `json:"id"`
+	Score  int    `json:"score"`
+	Reason string `json:"reason"`
+}
+
+// Store persists the queue.
+type Store interface {
+	// Enqueue queues a memo of a user, or queues it again, and returns the
+	// item; nil if the user has no such memo.
+	Enqueue(ctx context.Context, creatorID int32, memoUID string) (*Item, error)
+	// Get returns an item by memo UID, or nil.
+	Get(ctx context.Context, creatorID int32, memoUID string) (*Item, error)
+	// List returns items, highest score first, unscored items last.
+	List(ctx context.Context, find *Find) ([]*Item, error)
+	// Update sets the status and snooze time of an item and returns it, or
+	// nil if the user has no such item.
+	Update(ctx context.Context, creatorID int32, memoUID string, status Status, snoozedUntil int64) (*Item, error)
+	// ClaimUnscored leases up to limit unscored queued items of users with
+	// interests, and returns them.
+	ClaimUnscored(ctx context.Context, now time.Time, limit int) ([]*Item, error)
+	SaveScores(ctx context.Context, scores []Score) error
+	// ResetScores clears the scores of the queued items of a user.
+	ResetScores(ctx context.Context, creatorID int32) error
+
+	GetSettings(ctx context.Context, userID int32) (*Settings, error)
+	SaveSettings(ctx context.Context, settings *Settings) (*Settings, error)
+	// ClaimDueDigests marks up to limit users whose digest is due as
+	// delivered at now, and returns their settings. Users in quiet hours are
+	// left for later.
+	ClaimDueDigests(ctx context.Context, now time.Time, limit int) ([]*Settings, error)
+}
+
+// Scorer scores items against the interests of their user.
+type Scorer interface {
+	Score(ctx context.Context, interests string, items []*Item) ([]Score, error)
+}
+
+// Deliverer sends a digest to its user.
+type Deliverer func(ctx context.Context, settings *Settings, digest *Digest) error
+
+// Section returns a Markdown section of the digests of a user in their
+// locale, or "" for none.
+type Section func(ctx context.Context, userID int32, locale i18n.Locale) (string, error)
+
+// Queue is the read-later queue of all users.
+type Queue struct {
+	store    Store
+	scorer   Scorer
+	deliver  Deliverer
+	sections []Section
+	// locale is nil when digests are in the default locale.
+	locale i18n.Resolver
+}
+
+// NewQueue creates a queue. Without scorer, items are listed newest first.
+func NewQueue(store Store, scorer Scorer, deliver Deliverer) *Queue {
+	return &Queue{store: store, scorer: scorer, deliver: deliver}
+}
+
+// SetScorer sets the scorer of the queue, once the LLM is available.
+func (q *Queue) SetScorer(scorer Scorer) {
+	q.scorer = scorer
+}
+
+// SetLocaleResolver sets how the locale of the digests of users is found.
+// Must be called before Run.
+func (q *Queue) SetLocaleResolver(locale i18n.Resolver) {
+	q.locale = locale
+}
+
+// AddSection adds a section to the digests, after the queued items. Must be
+// called before Run.
+func (q *Queue) AddSection(section Section) {
+	q.sections = append(q.sections, section)
+}
+
+// Enqueue adds a memo of a user to the queue; nil if the user has no such memo.
+func (q *Queue) Enqueue(ctx context.Context, userID int32, memoUID string) (*Item, error) {
+	return q.store.Enqueue(ctx, userID, memoUID)
+}
+
+// List returns the items of a user, most interesting first.
+func (q *Queue) List(ctx context.Context, find *Find) ([]*Item, error) {
+	if find.Status == "" {
+		find.Status = StatusQueued
+	}
+	if find.Now.IsZero() {
+		find.Now = time.Now()
+	}
+	if find.Limit <= 0 {
+		find.Limit = defaultListLimit
+	}
+	find.Limit = min(find.Limit, maxListLimit)
+	return q.store.List(ctx, find)
+}
+
+// Snooze hides a queued item until a time, which must be in the future.
+func (q *Queue) Snooze(ctx context.Context, userID int32, memoUID string, until time.Time) (*Item, error) {
+	if until.Before(time.Now()) || time.Until(until) > maxSnooze {
+		return nil, ErrInvalidSnooze
+	}
+	return q.store.Update(ctx, userID, memoUID, StatusQueued, until.Unix())
+}
+
+// Dismiss removes an item from the queue without reading it.
+func (q *Queue) Dismiss(ctx context.Context, userID int32, memoUID string) (*Item, error) {
+	return q.store.Update(ctx, userID, memoUID, StatusDismissed, 0)
+}
+
+// MarkRead removes a read item from the queue.
+func (q *Queue) MarkRead(ctx context.Context, userID int32, memoUID string) (*Item, error) {
+	return q.store.Update(ctx, userID, memoUID, StatusRead, 0)
+}
+
+// GetSettings returns the settings of a user.
+func (q *Queue) GetSettings(ctx context.Context, userID int32) (*Settings, error) {
+	return q.store.GetSettings(ctx, userID)
+}
+
+// SaveSettings validates and saves the settings of a user. Changing the
+// interests has the queue scored again.
+func (q *Queue) SaveSettings(ctx context.Context, settings *Settings) (*Settings, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	previous, err := q.store.GetSettings(ctx, settings.UserID)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := q.store.SaveSettings(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+	if previous.Interests != saved.Interests {
+		if err := q.store.ResetScores(ctx, saved.UserID); err != nil {
+			return nil, err
+		}
+	}
+	return saved, nil
+}
+
+// Digest is the top of the queue of a user.
+type Digest struct {
+	GeneratedTs int64   `json:"generated_ts"`
+	Items       []*Item `json:"items"`
+	// Total is the number of queued items, snoozed ones excluded.
+	Total int `json:"total"`
+	// Sections are the Markdown sections added to the digest.
+	Sections []string `json:"sections,omitempty"`
+	// Locale is the locale of the user, which the digest is written in.
+	Locale i18n.Locale `json:"locale"`
+}
+
+// BuildDigest returns the digest of a user.
+func (q *Queue) BuildDigest(ctx context.Context, settings *Settings) (*Digest, error) {
+	items, err := q.List(ctx, &Find{CreatorID: settings.UserID, Limit: maxListLimit})
+	if err != nil {
+		return nil, err
+	}
+	digest := &Digest{GeneratedTs: time.Now().Unix(), Total: len(items), Locale: i18n.DefaultLocale}
+	digest.Items = items[:min(len(items), settings.DigestSize)]
+	if q.locale != nil {
+		digest.Locale = q.locale(ctx, settings.UserID)
+	}
+	for _, section := range q.sections {
+		// A failed section leaves the rest of the digest
+		content, err := section(ctx, settings.UserID, digest.Locale)
+		if err != nil {
+			slog.Warn("read later: failed to build digest section", "user_id", settings.UserID, "error", err)
+			continue
+		}
+		if content = strings.TrimSpace(content); content != "" {
+			digest.Sections = append(digest.Sections, content)
+		}
+	}
+	return digest, nil
+}
+
+// SendDigest builds and delivers the digest of a user. It returns false,
+// delivering nothing, when the queue and the sections are empty.
+func (q *Queue) SendDigest(ctx context.Context, settings *Settings) (bool, error) {
+	digest, err := q.BuildDigest(ctx, settings)
+	if err != nil {
+		return false, err
+	}
+	if len(digest.Items) == 0 && len(digest.Sections) == 0 {
+		return false, nil
+	}
+	if err := q.deliver(ctx, settings, digest); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Markdown renders the digest as memo content in its locale, tagged with
+// DigestTag.
+func (d *Digest) Markdown() string {
+	var b strings.Builder
+	b.WriteString(i18n.T(d.Locale, "readlater.digest.title", time.Unix(d.GeneratedTs, 0).Format("2006-01-02")) + "\n\n")
+	if d.Total == 0 {
+		b.WriteString(i18n.T(d.Locale, "readlater.digest.empty") + "\n")
+	} else {
+		b.WriteString(i18n.N(d.Locale, "readlater.digest.total", d.Total, d.Total) + "\n\n")
+	}
+	for i, item := range d.Items {
+		title := strings.NewReplacer("[", "(", "]", ")").Replace(item.Title)
+		fmt.Fprintf(&b, "%d. [%s](/memos/%s)", i+1, title, item.MemoUID)
+		if item.Score != nil {
+			b.WriteString(i18n.T(d.Locale, "readlater.digest.score", *item.Score))
+		}
+		if item.Reason != "" {
+			fmt.Fprintf(&b, "\n   %s", item.Reason)
+		}
+		b.WriteString("\n")
+	}
+	if d.Total > len(d.Items) {
+		b.WriteString("\n" + i18n.T(d.Locale, "common.more", d.Total-len(d.Items)) + "\n")
+	}
+	for _, section := range d.Sections {
+		fmt.Fprintf(&b, "\n%s\n", section)
+	}
+	fmt.Fprintf(&b, "\n#%s\n", DigestTag)
+	return b.String()
+}
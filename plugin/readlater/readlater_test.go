@@ -0,0 +1,215 @@
+package readlater
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/plugin/i18n"
+)
+
+type fakeStore struct {
+	Store
+	settings map[int32]*Settings
+	items    []*Item
+	scores   []Score
+	resets   []int32
+	due      []*Settings
+}
+
+func (s *fakeStore) GetSettings(_ context.Context, userID int32) (*Settings, error) {
+	if settings, ok := s.settings[userID]; ok {
+		return settings, nil
+	}
+	return DefaultSettings(userID), nil
+}
+
+func (s *fakeStore) SaveSettings(_ context.Context, settings *Settings) (*Settings, error) {
+	s.settings[settings.UserID] = settings
+	return settings, nil
+}
+
+func (s *fakeStore) ResetScores(_ context.Context, creatorID int32) error {
+	s.resets = append(s.resets, creatorID)
+	return nil
+}
+
+func (s *fakeStore) ClaimUnscored(context.Context, time.Time, int) ([]*Item, error) {
+	return s.items, nil
+}
+
+func (s *fakeStore) SaveScores(_ context.Context, scores []Score) error {
+	s.scores = append(s.scores, scores...)
+	return nil
+}
+
+func (s *fakeStore) List(_ context.Context, find *Find) ([]*Item, error) {
+	var items []*Item
+	for _, item := range s.items {
+		if item.CreatorID == find.CreatorID {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (s *fakeStore) ClaimDueDigests(context.Context, time.Time, int) ([]*Settings, error) {
+	return s.due, nil
+}
+
+type fakeScorer struct {
+	calls int
+	fail  bool
+}
+
+func (s *fakeScorer) Score(_ context.Context, _ string, items []*Item) ([]Score, error) {
+	s.calls++
+	if s.fail {
+		return nil, errors.New("llm down")
+	}
+	// Scores the first item only, twice, plus an unknown one.
+	return []Score{
+		{MemoID: items[0].MemoID, Score: 90, Reason: "相关"},
+		{MemoID: items[0].MemoID, Score: 10},
+		{MemoID: 999, Score: 100},
+	}, nil
+}
+
+func TestSettingsValidate(t *testing.T) {
+	settings := DefaultSettings(1)
+	settings.Interests = "  Go 与分布式系统 "
+	require.NoError(t, settings.Validate())
+	assert.Equal(t, "Go 与分布式系统", settings.Interests)
+
+	for _, invalid := range []Settings{
+		{Delivery: "EMAIL", DigestDays: 1, DigestSize: 5},
+		{Delivery: DeliveryMemo, DigestDays: 0, DigestSize: 5},
+		{Delivery: DeliveryMemo, DigestDays: 1, DigestSize: 21},
+	} {
+		assert.Error(t, invalid.Validate())
+	}
+}
+
+func TestSaveSettingsResetsScores(t *testing.T) {
+	store := &fakeStore{settings: map[int32]*Settings{}}
+	queue := NewQueue(store, nil, nil)
+
+	settings := DefaultSettings(1)
+	settings.Interests = "Go"
+	_, err := queue.SaveSettings(context.Background(), settings)
+	require.NoError(t, err)
+
+	same := *settings
+	same.DigestEnabled = true
+	_, err = queue.SaveSettings(context.Background(), &same)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1}, store.resets)
+}
+
+func TestScorePending(t *testing.T) {
+	store := &fakeStore{
+		settings: map[int32]*Settings{1: {UserID: 1, Interests: "Go"}},
+		items: []*Item{
+			{MemoID: 1, CreatorID: 1},
+			{MemoID: 2, CreatorID: 1},
+			// User 2 removed their interests after the claim.
+			{MemoID: 3, CreatorID: 2},
+		},
+	}
+	scorer := &fakeScorer{}
+	scored, err := NewQueue(store, scorer, nil).ScorePending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, scored)
+	assert.Equal(t, 1, scorer.calls)
+	assert.Equal(t, []Score{
+		{MemoID: 1, Score: 90, Reason: "相关"},
+		{MemoID: 2, Score: neutralScore},
+	}, store.scores)
+
+	store.scores = nil
+	scored, err = NewQueue(store, &fakeScorer{fail: true}, nil).ScorePending(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, scored)
+	assert.Empty(t, store.scores)
+}
+
+func TestParseScores(t *testing.T) {
+	scores, err := parseScores("```json\n{\"scores\": [{\"id\": 3, \"score\": 120, \"reason\": \" 很相关 \"}, {\"id\": 4, \"score\": -5}]}\n```")
+	require.NoError(t, err)
+	assert.Equal(t, []Score{{MemoID: 3, Score: 100, Reason: "很相关"}, {MemoID: 4, Score: 0}}, scores)
+
+	_, err = parseScores("我无法打分")
+	assert.Error(t, err)
+}
+
+func TestDigest(t *testing.T) {
+	score := 87
+	store := &fakeStore{
+		items: []*Item{
+			{MemoUID: "a1", CreatorID: 1, Title: "Go [generics]", Score: &score, Reason: "与 Go 兴趣相关"},
+			{MemoUID: "b2", CreatorID: 1, Title: "Rust"},
+			{MemoUID: "c3", CreatorID: 1, Title: "Zig"},
+		},
+		due: []*Settings{{UserID: 1, DigestSize: 2}, {UserID: 2, DigestSize: 2}},
+	}
+	var delivered []*Digest
+	queue := NewQueue(store, nil, func(_ context.Context, _ *Settings, digest *Digest) error {
+		delivered = append(delivered, digest)
+		return nil
+	})
+	// User 2 has an empty queue and gets no digest.
+	queue.RunOnce(context.Background())
+	require.Len(t, delivered, 1)
+
+	digest := delivered[0]
+	digest.GeneratedTs = time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local).Unix()
+	assert.Equal(t, "# 稍后阅读精选（2026-10-16）\n\n"+
+		"待读 3 项，按你的兴趣排序：\n\n"+
+		"1. [Go (generics)](/memos/a1) · 87 分\n   与 Go 兴趣相关\n"+
+		"2. [Rust](/memos/b2)\n"+
+		"\n……另有 1 项\n"+
+		"\n#read-later\n", digest.Markdown())
+
+	digest.Locale = i18n.LocaleEnglish
+	assert.Equal(t, "# Read later digest (2026-10-16)\n\n"+
+		"3 items to read, sorted by your interests:\n\n"+
+		"1. [Go (generics)](/memos/a1) · score 87\n   与 Go 兴趣相关\n"+
+		"2. [Rust](/memos/b2)\n"+
+		"\n…and 1 more\n"+
+		"\n#read-later\n", digest.Markdown())
+}
+
+func TestDigestSections(t *testing.T) {
+	store := &fakeStore{due: []*Settings{{UserID: 2, DigestSize: 2}, {UserID: 3, DigestSize: 2}}}
+	var delivered []*Digest
+	queue := NewQueue(store, nil, func(_ context.Context, _ *Settings, digest *Digest) error {
+		delivered = append(delivered, digest)
+		return nil
+	})
+	queue.SetLocaleResolver(func(_ context.Context, userID int32) i18n.Locale {
+		return i18n.LocaleEnglish
+	})
+	queue.AddSection(func(_ context.Context, userID int32, locale i18n.Locale) (string, error) {
+		if userID == 3 {
+			return "", nil
+		}
+		return "## Habits (" + string(locale) + ")\n\n- Running: 3 days in a row\n", nil
+	})
+	queue.AddSection(func(context.Context, int32, i18n.Locale) (string, error) {
+		return "", errors.New("unavailable")
+	})
+	// User 2 has an empty queue but a section; user 3 has neither.
+	queue.RunOnce(context.Background())
+	require.Len(t, delivered, 1)
+
+	digest := delivered[0]
+	digest.GeneratedTs = time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local).Unix()
+	assert.Equal(t, "# Read later digest (2026-10-16)\n\n"+
+		"Your read-later queue is empty.\n"+
+		"\n## Habits (en)\n\n- Running: 3 days in a row\n"+
+		"\n#read-later\n", digest.Markdown(), "the digest and its sections are in the locale of the user")
+}
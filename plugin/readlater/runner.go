@@ -0,0 +1,127 @@
+package readlater
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	// runInterval is how often the queue scores new items and sends due digests.
+	runInterval = 5 * time.Minute
+	// scoreBatchSize bounds the items scored per run.
+	scoreBatchSize = 50
+	// digestBatchSize bounds the digests claimed per run.
+	digestBatchSize = 20
+	// runTimeout bounds the scoring of the items of one user, or the
+	// delivery of one digest.
+	runTimeout = 2 * time.Minute
+)
+
+// Run scores queued items and sends due digests until ctx is canceled.
+// Several instances may run queues on the same database: an item or a digest
+// is claimed by exactly one of them.
+func (q *Queue) Run(ctx context.Context) {
+	ticker := time.NewTicker(runInterval)
+	defer ticker.Stop()
+	for {
+		q.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce scores the pending items and sends the digests due now.
+func (q *Queue) RunOnce(ctx context.Context) {
+	if q.scorer != nil {
+		if _, err := q.ScorePending(ctx); err != nil {
+			slog.Warn("read later: failed to score items", "error", err)
+		}
+	}
+	if q.deliver == nil {
+		return
+	}
+	due, err := q.store.ClaimDueDigests(ctx, time.Now(), digestBatchSize)
+	if err != nil {
+		slog.Warn("read later: failed to claim due digests", "error", err)
+		return
+	}
+	for _, settings := range due {
+		digestCtx, cancel := context.WithTimeout(ctx, runTimeout)
+		sent, err := q.SendDigest(digestCtx, settings)
+		cancel()
+		if err != nil {
+			slog.Warn("read later: failed to deliver digest", "user_id", settings.UserID, "error", err)
+		} else if sent {
+			slog.Info("read later digest delivered", "user_id", settings.UserID, "delivery", settings.Delivery)
+		}
+	}
+}
+
+// ScorePending scores a batch of unscored items and returns how many it
+// scored. Items the scorer skips get a neutral score; items of a failed
+// batch are retried once their lease expires.
+func (q *Queue) ScorePending(ctx context.Context) (int, error) {
+	items, err := q.store.ClaimUnscored(ctx, time.Now(), scoreBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	byUser := map[int32][]*Item{}
+	var users []int32
+	for _, item := range items {
+		if _, ok := byUser[item.CreatorID]; !ok {
+			users = append(users, item.CreatorID)
+		}
+		byUser[item.CreatorID] = append(byUser[item.CreatorID], item)
+	}
+	scored := 0
+	for _, userID := range users {
+		n, err := q.scoreUser(ctx, userID, byUser[userID])
+		if err != nil {
+			slog.Warn("read later: failed to score items", "user_id", userID, "error", err)
+			continue
+		}
+		scored += n
+	}
+	return scored, nil
+}
+
+func (q *Queue) scoreUser(ctx context.Context, userID int32, items []*Item) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, runTimeout)
+	defer cancel()
+	settings, err := q.store.GetSettings(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if settings.Interests == "" {
+		return 0, nil
+	}
+	scores, err := q.scorer.Score(ctx, settings.Interests, items)
+	if err != nil {
+		return 0, err
+	}
+	return len(items), q.store.SaveScores(ctx, completeScores(items, scores))
+}
+
+// completeScores keeps the scores of the given items, the first one of
+// each, and gives the neutral score to the items left unscored.
+func completeScores(items []*Item, scores []Score) []Score {
+	byID := make(map[int32]Score, len(scores))
+	for _, score := range scores {
+		if _, ok := byID[score.MemoID]; !ok {
+			byID[score.MemoID] = score
+		}
+	}
+	result := make([]Score, 0, len(items))
+	for _, item := range items {
+		score, ok := byID[item.MemoID]
+		if !ok {
+			score = Score{MemoID: item.MemoID, Score: neutralScore}
+		}
+		result = append(result, score)
+	}
+	return result
+}
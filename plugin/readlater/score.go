@@ -0,0 +1,80 @@
+package readlater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hrygo/divinesense/ai/core/llm"
+)
+
+const (
+	// maxScoreContentRunes bounds the memo text of an item sent to the model.
+	maxScoreContentRunes = 600
+	// neutralScore is given to items the model did not score.
+	neutralScore = 50
+)
+
+// LLMScorer scores items with a language model. A cheap model is enough.
+type LLMScorer struct {
+	llm llm.Service
+}
+
+// NewLLMScorer creates a scorer backed by llmSvc.
+func NewLLMScorer(llmSvc llm.Service) *LLMScorer {
+	return &LLMScorer{llm: llmSvc}
+}
+
+// Score implements Scorer. Items are scored together so that their scores
+// are comparable.
+func (s *LLMScorer) Score(ctx context.Context, interests string, items []*Item) ([]Score, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "用户兴趣：\n%s\n\n待评分条目：\n", interests)
+	for _, item := range items {
+		content := item.Content
+		if runes := []rune(content); len(runes) > maxScoreContentRunes {
+			content = string(runes[:maxScoreContentRunes])
+		}
+		fmt.Fprintf(&b, "\n[id=%d] %s\n%s\n", item.MemoID, item.Title, content)
+	}
+	answer, _, err := s.llm.Chat(ctx, []llm.Message{
+		llm.SystemPrompt(scoreSystemPrompt),
+		llm.UserMessage(b.String()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseScores(answer)
+}
+
+// parseScores decodes the JSON answer of the model, tolerating a markdown
+// code fence around it, and clamps the scores to 0-100.
+func parseScores(content string) ([]Score, error) {
+	content = strings.TrimSpace(content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	content = strings.TrimSpace(content)
+
+	var result struct {
+		Scores []Score `json:"scores"`
+	}
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return nil, fmt.Errorf("parse scores: %w", err)
+	}
+	for i := range result.Scores {
+		result.Scores[i].Score = max(0, min(100, result.Scores[i].Score))
+		result.Scores[i].Reason = strings.TrimSpace(result.Scores[i].Reason)
+	}
+	return result.Scores, nil
+}
+
+const scoreSystemPrompt = `你是一个阅读优先级助手。根据用户描述的兴趣，为稍后阅读列表中的每个条目打分。
+
+要求：
+1. 分数为 0 到 100 的整数，越符合用户兴趣、越值得优先阅读分数越高
+2. 只根据条目的标题和内容判断，内容不足时给出中间分数
+3. 理由一句话，不超过 30 字，使用与用户兴趣一致的语言
+4. 每个条目都要打分，id 使用条目给出的 id
+5. 只返回JSON：{"scores": [{"id": 1, "score": 80, "reason": ""}]}`
@@ -0,0 +1,268 @@
+package readlater
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hrygo/divinesense/ai/duplicate"
+	"github.com/hrygo/divinesense/plugin/quiethours"
+)
+
+// scoreLease is how long a scoring attempt holds unscored items.
+const scoreLease = 10 * time.Minute
+
+// DBStore persists the queue in the read_later_item and read_later_setting
+// tables (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new queue store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const itemColumns = `i.memo_id, m.uid, i.creator_id, m.content, COALESCE(s.title, ''), COALESCE(s.url, ''),
+	i.status, i.score, i.reason, i.snoozed_until, i.created_ts, i.updated_ts`
+
+const itemFrom = `
+	FROM read_later_item i
+	JOIN memo m ON m.id = i.memo_id
+	LEFT JOIN memo_source s ON s.memo_id = i.memo_id`
+
+// Enqueue implements Store.
+func (s *DBStore) Enqueue(ctx context.Context, creatorID int32, memoUID string) (*Item, error) {
+	var memoID int32
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO read_later_item (memo_id, creator_id, created_ts, updated_ts)
+		SELECT id, creator_id, $3, $3 FROM memo WHERE uid = $2 AND creator_id = $1
+		ON CONFLICT (memo_id) DO UPDATE SET status = 'QUEUED', snoozed_until = 0, updated_ts = EXCLUDED.updated_ts
+		RETURNING memo_id`, creatorID, memoUID, time.Now().Unix()).Scan(&memoID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue memo: %w", err)
+	}
+	return s.Get(ctx, creatorID, memoUID)
+}
+
+// Get implements Store.
+func (s *DBStore) Get(ctx context.Context, creatorID int32, memoUID string) (*Item, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+itemColumns+itemFrom+" WHERE i.creator_id = $1 AND m.uid = $2", creatorID, memoUID)
+	item, err := scanItem(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get read later item: %w", err)
+	}
+	return item, nil
+}
+
+// List implements Store. Archived memos are left out.
+func (s *DBStore) List(ctx context.Context, find *Find) ([]*Item, error) {
+	query := "SELECT " + itemColumns + itemFrom + `
+		WHERE i.creator_id = $1 AND i.status = $2 AND m.row_status = 'NORMAL'`
+	args := []any{find.CreatorID, find.Status, find.Limit}
+	if !find.IncludeSnoozed {
+		query += " AND i.snoozed_until <= $4"
+		args = append(args, find.Now.Unix())
+	}
+	query += " ORDER BY i.score DESC NULLS LAST, i.created_ts DESC LIMIT $3"
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list read later items: %w", err)
+	}
+	defer rows.Close()
+	return scanItems(rows)
+}
+
+// Update implements Store.
+func (s *DBStore) Update(ctx context.Context, creatorID int32, memoUID string, status Status, snoozedUntil int64) (*Item, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE read_later_item SET status = $3, snoozed_until = $4, updated_ts = $5
+		WHERE creator_id = $1 AND memo_id = (SELECT id FROM memo WHERE uid = $2)`,
+		creatorID, memoUID, status, snoozedUntil, time.Now().Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to update read later item: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return nil, err
+	}
+	return s.Get(ctx, creatorID, memoUID)
+}
+
+// ClaimUnscored implements Store. Items leased by another runner less than
+// scoreLease ago are skipped.
+func (s *DBStore) ClaimUnscored(ctx context.Context, now time.Time, limit int) ([]*Item, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		WITH claimed AS (
+			UPDATE read_later_item SET scored_ts = $1
+			WHERE memo_id IN (
+				SELECT i.memo_id FROM read_later_item i
+				JOIN read_later_setting rs ON rs.user_id = i.creator_id
+				WHERE i.status = 'QUEUED' AND i.score IS NULL AND i.scored_ts <= $2 AND rs.interests <> ''
+				ORDER BY i.scored_ts, i.created_ts
+				LIMIT $3
+				FOR UPDATE OF i SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT `+itemColumns+`
+		FROM claimed i
+		JOIN memo m ON m.id = i.memo_id
+		LEFT JOIN memo_source s ON s.memo_id = i.memo_id
+		ORDER BY i.creator_id, i.created_ts`,
+		now.Unix(), now.Add(-scoreLease).Unix(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim unscored items: %w", err)
+	}
+	defer rows.Close()
+	return scanItems(rows)
+}
+
+// SaveScores implements Store.
+func (s *DBStore) SaveScores(ctx context.Context, scores []Score) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	now := time.Now().Unix()
+	for _, score := range scores {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE read_later_item SET score = $2, reason = $3, scored_ts = $4 WHERE memo_id = $1`,
+			score.MemoID, score.Score, score.Reason, now); err != nil {
+			return fmt.Errorf("failed to save read later score: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ResetScores implements Store.
+func (s *DBStore) ResetScores(ctx context.Context, creatorID int32) error {
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE read_later_item SET score = NULL, reason = '', scored_ts = 0
+		WHERE creator_id = $1 AND status = 'QUEUED'`, creatorID); err != nil {
+		return fmt.Errorf("failed to reset read later scores: %w", err)
+	}
+	return nil
+}
+
+const settingColumns = "user_id, interests, digest_enabled, delivery, digest_days, digest_size, last_digest_ts"
+
+// GetSettings returns the settings of a user, or the defaults if none were saved.
+func (s *DBStore) GetSettings(ctx context.Context, userID int32) (*Settings, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+settingColumns+" FROM read_later_setting WHERE user_id = $1", userID)
+	settings, err := scanSettings(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return DefaultSettings(userID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get read later settings: %w", err)
+	}
+	return settings, nil
+}
+
+// SaveSettings creates or updates the settings of a user, keeping the time of
+// the last digest.
+func (s *DBStore) SaveSettings(ctx context.Context, settings *Settings) (*Settings, error) {
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO read_later_setting (user_id, interests, digest_enabled, delivery, digest_days, digest_size, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (user_id) DO UPDATE SET
+			interests = EXCLUDED.interests,
+			digest_enabled = EXCLUDED.digest_enabled,
+			delivery = EXCLUDED.delivery,
+			digest_days = EXCLUDED.digest_days,
+			digest_size = EXCLUDED.digest_size,
+			updated_ts = EXCLUDED.updated_ts
+		RETURNING `+settingColumns,
+		settings.UserID, settings.Interests, settings.DigestEnabled, settings.Delivery, settings.DigestDays,
+		settings.DigestSize, time.Now().Unix())
+	saved, err := scanSettings(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save read later settings: %w", err)
+	}
+	return saved, nil
+}
+
+// ClaimDueDigests implements Store. Users in quiet hours wait until they end;
+// rows locked by another runner are skipped.
+func (s *DBStore) ClaimDueDigests(ctx context.Context, now time.Time, limit int) ([]*Settings, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		UPDATE read_later_setting SET last_digest_ts = $1
+		WHERE user_id IN (
+			SELECT user_id FROM read_later_setting
+			WHERE digest_enabled AND last_digest_ts <= $1 - digest_days * 86400
+				AND `+quiethours.NotQuietSQL("read_later_setting.user_id", "$1")+`
+			ORDER BY last_digest_ts
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+settingColumns,
+		now.Unix(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due digests: %w", err)
+	}
+	defer rows.Close()
+
+	var due []*Settings
+	for rows.Next() {
+		settings, err := scanSettings(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan read later settings: %w", err)
+		}
+		due = append(due, settings)
+	}
+	return due, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanItem(row rowScanner) (*Item, error) {
+	item := &Item{}
+	var score sql.NullInt32
+	if err := row.Scan(&item.MemoID, &item.MemoUID, &item.CreatorID, &item.Content, &item.Title, &item.URL,
+		&item.Status, &score, &item.Reason, &item.SnoozedUntil, &item.CreatedTs, &item.UpdatedTs); err != nil {
+		return nil, err
+	}
+	if score.Valid {
+		value := int(score.Int32)
+		item.Score = &value
+	}
+	if item.Title == "" {
+		item.Title = duplicate.ExtractTitle(item.Content)
+	}
+	if item.Title == "" {
+		item.Title = item.MemoUID
+	}
+	return item, nil
+}
+
+func scanItems(rows *sql.Rows) ([]*Item, error) {
+	var items []*Item
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan read later item: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func scanSettings(row rowScanner) (*Settings, error) {
+	settings := &Settings{}
+	if err := row.Scan(&settings.UserID, &settings.Interests, &settings.DigestEnabled, &settings.Delivery,
+		&settings.DigestDays, &settings.DigestSize, &settings.LastDigestTs); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
@@ -0,0 +1,181 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Statuses of escalations.
+const (
+	EscalationActive       = "active"
+	EscalationAcknowledged = "acknowledged"
+	// EscalationExhausted is the status of escalations that went through
+	// their whole chain without being acknowledged.
+	EscalationExhausted = "exhausted"
+)
+
+const (
+	// defaultEscalationMinutes is the default wait for an acknowledgement.
+	defaultEscalationMinutes = 5
+	// maxEscalationDelay is the longest wait for an acknowledgement.
+	maxEscalationDelay = 2 * time.Hour
+	// maxCritical bounds the critical schedules of a user.
+	maxCritical = 200
+	// maxUIDLength bounds the UIDs of critical schedules.
+	maxUIDLength = 256
+)
+
+// Escalation is a critical reminder going through a chain of channels until
+// it is acknowledged.
+type Escalation struct {
+	ID     int64 `json:"id"`
+	UserID int32 `json:"-"`
+	// ScheduleUID, StartTs and LeadMinutes identify the reminder.
+	ScheduleUID string `json:"schedule_uid"`
+	StartTs     int64  `json:"start_ts"`
+	LeadMinutes int32  `json:"lead_minutes"`
+	// Channels is the chain, and DelayMinutes the wait for an acknowledgement
+	// on each channel, as set when the escalation started.
+	Channels     []string `json:"channels"`
+	DelayMinutes int32    `json:"delay_minutes"`
+	// Step is the number of channels tried; the user was last notified
+	// through Channels[Step-1].
+	Step   int32  `json:"step"`
+	Status string `json:"status"`
+	// NextTs is when an active escalation moves to its next channel.
+	NextTs         int64         `json:"next_ts,omitempty"`
+	AcknowledgedTs int64         `json:"acknowledged_ts,omitempty"`
+	Notification   *Notification `json:"notification"`
+	CreatedTs      int64         `json:"created_ts"`
+	UpdatedTs      int64         `json:"updated_ts"`
+}
+
+// FindEscalation filters escalations.
+type FindEscalation struct {
+	UserID      *int32
+	ScheduleUID *string
+	Status      *string
+	// DueTs selects the active escalations to move on at it, when set.
+	DueTs int64
+	Limit int
+}
+
+// Critical returns the UIDs of the critical schedules of a user.
+func (r *Reminders) Critical(ctx context.Context, userID int32) ([]string, error) {
+	return r.store.ListCritical(ctx, userID)
+}
+
+// SetCritical marks a schedule of a user critical, or not, and returns its
+// title. Unmarking a schedule does not stop its active escalations.
+func (r *Reminders) SetCritical(ctx context.Context, userID int32, uid string, critical bool) (string, error) {
+	uid = strings.TrimSpace(uid)
+	if uid == "" || len(uid) > maxUIDLength {
+		return "", fmt.Errorf("%w: invalid schedule UID", ErrInvalid)
+	}
+	title, err := r.schedules.Title(ctx, userID, uid)
+	if err != nil {
+		return "", err
+	}
+	if critical {
+		uids, err := r.store.ListCritical(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+		if !slices.Contains(uids, uid) && len(uids) >= maxCritical {
+			return "", fmt.Errorf("%w: at most %d critical schedules", ErrInvalid, maxCritical)
+		}
+	}
+	if err := r.store.SetCritical(ctx, userID, uid, critical); err != nil {
+		return "", err
+	}
+	return title, nil
+}
+
+// Escalations returns escalations.
+func (r *Reminders) Escalations(ctx context.Context, find *FindEscalation) ([]*Escalation, error) {
+	return r.store.ListEscalations(ctx, find)
+}
+
+// Acknowledge acknowledges an escalation of a user, stopping it.
+func (r *Reminders) Acknowledge(ctx context.Context, userID int32, id int64) (*Escalation, error) {
+	return r.store.AcknowledgeEscalation(ctx, userID, id, r.now().Unix())
+}
+
+// startEscalation starts escalating a critical reminder due at now, unless it
+// already escalates.
+func (r *Reminders) startEscalation(ctx context.Context, settings *Settings, notification *Notification, now time.Time) error {
+	escalation := &Escalation{
+		UserID:       settings.UserID,
+		ScheduleUID:  notification.UID,
+		StartTs:      notification.StartTs,
+		LeadMinutes:  notification.LeadMinutes,
+		Channels:     slices.Clone(settings.EscalationChannels),
+		DelayMinutes: settings.EscalationMinutes,
+		Status:       EscalationActive,
+		NextTs:       now.Unix(),
+		Notification: notification,
+	}
+	created, err := r.store.CreateEscalation(ctx, escalation)
+	if err != nil || !created {
+		return err
+	}
+	notification.EscalationID = escalation.ID
+	return r.advance(ctx, escalation, now)
+}
+
+// escalate moves the escalations not acknowledged in time to their next
+// channel.
+func (r *Reminders) escalate(ctx context.Context, now time.Time) error {
+	active := EscalationActive
+	escalations, err := r.store.ListEscalations(ctx, &FindEscalation{Status: &active, DueTs: now.Unix(), Limit: retryBatchSize})
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, escalation := range escalations {
+		if err := r.advance(ctx, escalation, now); err != nil {
+			errs = append(errs, fmt.Errorf("escalation %d: %w", escalation.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// advance notifies the user through the next channel of an escalation, and
+// the following ones while they fail or cannot reach the user; the
+// escalation is exhausted past its last channel.
+func (r *Reminders) advance(ctx context.Context, escalation *Escalation, now time.Time) error {
+	for int(escalation.Step) < len(escalation.Channels) {
+		delivery := &Delivery{
+			UserID:       escalation.UserID,
+			ScheduleUID:  escalation.ScheduleUID,
+			StartTs:      escalation.StartTs,
+			LeadMinutes:  escalation.LeadMinutes,
+			Channel:      escalation.Channels[escalation.Step],
+			Status:       StatusPending,
+			Notification: escalation.Notification,
+			EscalationID: escalation.ID,
+		}
+		escalation.Step++
+		created, err := r.store.CreateDelivery(ctx, delivery)
+		if err != nil {
+			return err
+		}
+		if !created {
+			// The channel was already notified of the reminder.
+			continue
+		}
+		r.deliver(ctx, delivery, now, true)
+		if delivery.Status == StatusSent {
+			escalation.NextTs = now.Add(time.Duration(escalation.DelayMinutes) * time.Minute).Unix()
+			_, err := r.store.UpdateEscalation(ctx, escalation)
+			return err
+		}
+	}
+	escalation.Status, escalation.NextTs = EscalationExhausted, 0
+	_, err := r.store.UpdateEscalation(ctx, escalation)
+	return err
+}
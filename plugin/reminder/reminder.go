@@ -0,0 +1,660 @@
+// Package reminder delivers the reminders of schedules.
+//
+// Each schedule holds its own reminders, e.g. 15 minutes and 1 day before it
+// starts; schedules without reminders use the default lead times of their
+// user. Every minute the dispatcher looks for the reminders due, recurring
+// schedules included, and notifies the user through the channels they chose:
+// web push, email, Telegram or webhook. Each notification is recorded as a
+// delivery per channel, which deduplicates it and keeps its status; failed
+// ones are retried until the schedule starts.
+//
+// During the quiet hours of a user, reminders skip every channel but
+// webhooks.
+//
+// Users may mark schedules critical: their reminders escalate through an
+// ordered chain of channels instead, e.g. web push, then Telegram, then
+// email, moving to the next channel after a delay until the user
+// acknowledges them. Critical reminders ignore quiet hours.
+package reminder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Channels of the reminders.
+const (
+	ChannelWebPush  = "web_push"
+	ChannelEmail    = "email"
+	ChannelTelegram = "telegram"
+	ChannelWebhook  = "webhook"
+)
+
+// Statuses of deliveries.
+const (
+	StatusPending = "pending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+	// StatusSkipped is the status of deliveries that were not attempted, e.g.
+	// during quiet hours or to users without a Telegram account.
+	StatusSkipped = "skipped"
+)
+
+const (
+	// MaxLead is the longest lead time of a reminder.
+	MaxLead = 7 * 24 * time.Hour
+	// MaxAttempts is the number of attempts of a delivery before it fails.
+	MaxAttempts = 3
+	// DeliveryRetention is how long deliveries are kept.
+	DeliveryRetention = 30 * 24 * time.Hour
+	// maxLeads bounds the default lead times of a user.
+	maxLeads = 5
+	// catchUp is how late a reminder may still be sent, e.g. after a restart.
+	catchUp = 10 * time.Minute
+	// retryDelay is the wait before a failed delivery is attempted again.
+	retryDelay = time.Minute
+	// sendTimeout bounds each notification.
+	sendTimeout = 30 * time.Second
+	// maxErrorLength bounds the error kept on a delivery.
+	maxErrorLength = 512
+	// retryBatchSize bounds the deliveries retried by a run.
+	retryBatchSize = 100
+)
+
+var (
+	// ErrInvalid is returned for invalid settings and push subscriptions.
+	ErrInvalid = errors.New("invalid reminder settings")
+	// ErrNotFound is returned for unknown push subscriptions.
+	ErrNotFound = errors.New("push subscription not found")
+	// ErrEscalationNotFound is returned for unknown escalations.
+	ErrEscalationNotFound = errors.New("reminder escalation not found")
+	// ErrScheduleNotFound is returned when marking unknown schedules critical.
+	ErrScheduleNotFound = errors.New("schedule not found")
+	// ErrUnavailable is returned by channels that cannot reach a user, e.g.
+	// without a linked Telegram account; their deliveries are skipped.
+	ErrUnavailable = errors.New("channel unavailable for the user")
+)
+
+// Settings are the reminder preferences of a user.
+type Settings struct {
+	UserID  int32 `json:"-"`
+	Enabled bool  `json:"enabled"`
+	// Channels are the channels notified of each reminder.
+	Channels []string `json:"channels"`
+	// LeadMinutes are the reminders of schedules without reminders of their
+	// own, in minutes before they start.
+	LeadMinutes []int32 `json:"lead_minutes"`
+	// EscalationChannels is the chain of channels the reminders of critical
+	// schedules escalate through, in order; critical reminders use the
+	// regular channels when it is empty.
+	EscalationChannels []string `json:"escalation_channels"`
+	// EscalationMinutes is how long a critical reminder waits for its
+	// acknowledgement before escalating to the next channel.
+	EscalationMinutes int32 `json:"escalation_minutes"`
+	UpdatedTs         int64 `json:"updated_ts"`
+}
+
+// DefaultSettings returns the settings of users who saved none.
+func DefaultSettings(userID int32) *Settings {
+	return &Settings{
+		UserID:             userID,
+		Channels:           []string{ChannelWebPush},
+		LeadMinutes:        []int32{15},
+		EscalationChannels: []string{ChannelWebPush, ChannelTelegram, ChannelEmail},
+		EscalationMinutes:  defaultEscalationMinutes,
+	}
+}
+
+// validate checks and normalizes settings about to be saved, the channels
+// among the available ones.
+func (s *Settings) validate(available []string) error {
+	channels := []string{}
+	for _, channel := range s.Channels {
+		if !slices.Contains(available, channel) {
+			return fmt.Errorf("%w: channel %q is not available", ErrInvalid, channel)
+		}
+		if !slices.Contains(channels, channel) {
+			channels = append(channels, channel)
+		}
+	}
+	if s.Enabled && len(channels) == 0 {
+		return fmt.Errorf("%w: at least one channel is required", ErrInvalid)
+	}
+	leads := []int32{}
+	for _, lead := range s.LeadMinutes {
+		if lead < 0 || time.Duration(lead)*time.Minute > MaxLead {
+			return fmt.Errorf("%w: lead times must be between 0 and %d minutes", ErrInvalid, int(MaxLead/time.Minute))
+		}
+		if !slices.Contains(leads, lead) {
+			leads = append(leads, lead)
+		}
+	}
+	if len(leads) > maxLeads {
+		return fmt.Errorf("%w: at most %d lead times", ErrInvalid, maxLeads)
+	}
+	slices.Sort(leads)
+	chain := []string{}
+	for _, channel := range s.EscalationChannels {
+		if !slices.Contains(available, channel) {
+			return fmt.Errorf("%w: escalation channel %q is not available", ErrInvalid, channel)
+		}
+		if slices.Contains(chain, channel) {
+			return fmt.Errorf("%w: escalation channel %q is repeated", ErrInvalid, channel)
+		}
+		chain = append(chain, channel)
+	}
+	if s.EscalationMinutes == 0 {
+		s.EscalationMinutes = defaultEscalationMinutes
+	}
+	if s.EscalationMinutes < 1 || time.Duration(s.EscalationMinutes)*time.Minute > maxEscalationDelay {
+		return fmt.Errorf("%w: the escalation delay must be between 1 and %d minutes", ErrInvalid, int(maxEscalationDelay/time.Minute))
+	}
+	s.Channels, s.LeadMinutes, s.EscalationChannels = channels, leads, chain
+	return nil
+}
+
+// available drops the channels not available from default settings, e.g.
+// Telegram when no bot is configured, so that they can be saved back as is.
+func (s *Settings) available(available []string) {
+	keep := func(channels []string) []string {
+		return slices.DeleteFunc(channels, func(channel string) bool { return !slices.Contains(available, channel) })
+	}
+	s.Channels, s.EscalationChannels = keep(s.Channels), keep(s.EscalationChannels)
+}
+
+// Occurrence is a schedule, or an occurrence of a recurring one.
+type Occurrence struct {
+	ScheduleID int32
+	// UID is the UID of the schedule, shared by its occurrences.
+	UID      string
+	Title    string
+	Location string
+	Timezone string
+	StartTs  int64
+	EndTs    *int64
+	AllDay   bool
+	// Reminders is the JSON reminders of the schedule.
+	Reminders string
+}
+
+// Notification is a reminder sent to a user.
+type Notification struct {
+	ScheduleID  int32  `json:"schedule_id"`
+	UID         string `json:"uid"`
+	Title       string `json:"title"`
+	Location    string `json:"location,omitempty"`
+	Timezone    string `json:"timezone,omitempty"`
+	StartTs     int64  `json:"start_ts"`
+	EndTs       *int64 `json:"end_ts,omitempty"`
+	AllDay      bool   `json:"all_day"`
+	LeadMinutes int32  `json:"lead_minutes"`
+	// EscalationID is the escalation of a critical reminder, to acknowledge.
+	EscalationID int64 `json:"escalation_id,omitempty"`
+}
+
+// Subject returns the subject of the notification, e.g. of its email.
+func (n *Notification) Subject() string {
+	if n.EscalationID != 0 {
+		return "Critical reminder: " + n.Title
+	}
+	return "Reminder: " + n.Title
+}
+
+// Body returns the text of the notification, when and where the schedule is.
+func (n *Notification) Body() string {
+	loc := time.UTC
+	if n.Timezone != "" {
+		if zone, err := time.LoadLocation(n.Timezone); err == nil {
+			loc = zone
+		}
+	}
+	start := time.Unix(n.StartTs, 0).In(loc)
+	var b strings.Builder
+	if n.AllDay {
+		fmt.Fprintf(&b, "%s, all day", start.Format("Mon, Jan 2 2006"))
+	} else {
+		fmt.Fprintf(&b, "%s (%s)", start.Format("Mon, Jan 2 2006 15:04"), loc)
+	}
+	if lead := formatLead(n.LeadMinutes); lead != "" {
+		fmt.Fprintf(&b, ", in %s", lead)
+	}
+	if n.Location != "" {
+		fmt.Fprintf(&b, "\nLocation: %s", n.Location)
+	}
+	if n.EscalationID != 0 {
+		b.WriteString("\nAcknowledge this reminder in DivineSense to stop it from escalating.")
+	}
+	return b.String()
+}
+
+// Text returns the subject and body of the notification.
+func (n *Notification) Text() string {
+	return n.Subject() + "\n" + n.Body()
+}
+
+// Delivery is a notification sent, or to send, through a channel.
+type Delivery struct {
+	ID     int64 `json:"id"`
+	UserID int32 `json:"-"`
+	// ScheduleUID and StartTs identify the occurrence; with LeadMinutes and
+	// Channel they deduplicate the notifications.
+	ScheduleUID  string        `json:"schedule_uid"`
+	StartTs      int64         `json:"start_ts"`
+	LeadMinutes  int32         `json:"lead_minutes"`
+	Channel      string        `json:"channel"`
+	Status       string        `json:"status"`
+	Attempts     int32         `json:"attempts"`
+	Error        string        `json:"error,omitempty"`
+	Notification *Notification `json:"notification"`
+	// EscalationID is the escalation the delivery is a step of; such
+	// deliveries are not retried, the escalation moves on instead.
+	EscalationID int64 `json:"escalation_id,omitempty"`
+	CreatedTs    int64 `json:"created_ts"`
+	UpdatedTs    int64 `json:"updated_ts"`
+	SentTs       int64 `json:"sent_ts,omitempty"`
+}
+
+// FindDelivery filters deliveries.
+type FindDelivery struct {
+	UserID      *int32
+	ScheduleUID *string
+	Status      *string
+	// Retryable selects the failed deliveries to attempt again at Now: with
+	// attempts left, last attempted before Now less the retry delay, of
+	// occurrences not started yet, and not escalation steps.
+	Retryable bool
+	Now       int64
+	Limit     int
+}
+
+// PushSubscription is a browser subscribed to web push notifications.
+type PushSubscription struct {
+	UserID int32 `json:"-"`
+	// Endpoint is the URL of the push service receiving the notifications.
+	Endpoint string `json:"endpoint"`
+	// Keys are the keys of the browser, base64url encoded, as given by the
+	// PushSubscription API; they are not listed.
+	Keys struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+	UserAgent string `json:"user_agent,omitempty"`
+	CreatedTs int64  `json:"created_ts"`
+}
+
+// Store persists the settings, push subscriptions and deliveries.
+type Store interface {
+	// GetSettings returns the settings of a user, or the defaults.
+	GetSettings(ctx context.Context, userID int32) (*Settings, error)
+	SaveSettings(ctx context.Context, settings *Settings) (*Settings, error)
+	// ListEnabledSettings returns the settings of the users with reminders
+	// enabled.
+	ListEnabledSettings(ctx context.Context) ([]*Settings, error)
+
+	ListSubscriptions(ctx context.Context, userID int32) ([]*PushSubscription, error)
+	// SaveSubscription creates or updates the subscription of its endpoint,
+	// moving it to the user.
+	SaveSubscription(ctx context.Context, subscription *PushSubscription) error
+	DeleteSubscription(ctx context.Context, userID int32, endpoint string) error
+
+	// CreateDelivery records a delivery, setting its ID; it returns false when
+	// the same notification was already recorded.
+	CreateDelivery(ctx context.Context, delivery *Delivery) (bool, error)
+	// UpdateDelivery saves the status, attempts, error and sent time of a
+	// delivery.
+	UpdateDelivery(ctx context.Context, delivery *Delivery) error
+	// ListDeliveries returns deliveries, latest occurrences first.
+	ListDeliveries(ctx context.Context, find *FindDelivery) ([]*Delivery, error)
+	// PruneDeliveries deletes the deliveries created before the time.
+	PruneDeliveries(ctx context.Context, beforeTs int64) error
+
+	// ListCritical returns the UIDs of the critical schedules of a user.
+	ListCritical(ctx context.Context, userID int32) ([]string, error)
+	// SetCritical marks a schedule of a user critical, or not.
+	SetCritical(ctx context.Context, userID int32, scheduleUID string, critical bool) error
+
+	// CreateEscalation records an escalation, setting its ID; it returns false
+	// when the same reminder already escalates.
+	CreateEscalation(ctx context.Context, escalation *Escalation) (bool, error)
+	// UpdateEscalation saves the step, status and next time of an active
+	// escalation; it returns false when the escalation is no longer active,
+	// e.g. acknowledged meanwhile.
+	UpdateEscalation(ctx context.Context, escalation *Escalation) (bool, error)
+	// AcknowledgeEscalation acknowledges an escalation of a user that is not
+	// acknowledged yet, and returns it; ErrEscalationNotFound when unknown.
+	AcknowledgeEscalation(ctx context.Context, userID int32, id int64, ts int64) (*Escalation, error)
+	// ListEscalations returns escalations, latest first.
+	ListEscalations(ctx context.Context, find *FindEscalation) ([]*Escalation, error)
+	// PruneEscalations deletes the escalations created before the time.
+	PruneEscalations(ctx context.Context, beforeTs int64) error
+}
+
+// Schedules lists the schedules of users.
+type Schedules interface {
+	// Occurrences returns the occurrences of the schedules of a user starting
+	// within [from, to].
+	Occurrences(ctx context.Context, userID int32, from, to time.Time) ([]*Occurrence, error)
+	// Title returns the title of a schedule of a user, or
+	// ErrScheduleNotFound.
+	Title(ctx context.Context, userID int32, uid string) (string, error)
+}
+
+// QuietHours tells whether users are in their quiet hours.
+type QuietHours interface {
+	// QuietUntil returns the end of the user's current quiet hours, or the
+	// zero time if the user is not in quiet hours at now.
+	QuietUntil(ctx context.Context, userID int32, now time.Time) (time.Time, error)
+}
+
+// Channel notifies users.
+type Channel interface {
+	// Send notifies a user. It returns ErrUnavailable when the user cannot be
+	// reached through the channel.
+	Send(ctx context.Context, userID int32, notification *Notification) error
+}
+
+// Reminders dispatches the reminders of schedules.
+type Reminders struct {
+	store     Store
+	schedules Schedules
+	quiet     QuietHours
+	channels  map[string]Channel
+	now       func() time.Time
+}
+
+// New creates reminders without channels; quiet may be nil.
+func New(store Store, schedules Schedules, quiet QuietHours) *Reminders {
+	return &Reminders{
+		store:     store,
+		schedules: schedules,
+		quiet:     quiet,
+		channels:  map[string]Channel{},
+		now:       time.Now,
+	}
+}
+
+// Register makes a channel available, e.g. email once SMTP is configured.
+func (r *Reminders) Register(name string, channel Channel) {
+	r.channels[name] = channel
+}
+
+// Channels returns the names of the available channels.
+func (r *Reminders) Channels() []string {
+	names := make([]string, 0, len(r.channels))
+	for name := range r.channels {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// GetSettings returns the settings of a user.
+func (r *Reminders) GetSettings(ctx context.Context, userID int32) (*Settings, error) {
+	settings, err := r.store.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings.UpdatedTs == 0 {
+		settings.available(r.Channels())
+	}
+	return settings, nil
+}
+
+// SaveSettings validates and saves the settings of a user.
+func (r *Reminders) SaveSettings(ctx context.Context, settings *Settings) (*Settings, error) {
+	if err := settings.validate(r.Channels()); err != nil {
+		return nil, err
+	}
+	return r.store.SaveSettings(ctx, settings)
+}
+
+// Subscriptions returns the push subscriptions of a user, without their keys.
+func (r *Reminders) Subscriptions(ctx context.Context, userID int32) ([]*PushSubscription, error) {
+	subscriptions, err := r.store.ListSubscriptions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, subscription := range subscriptions {
+		subscription.Keys.P256dh, subscription.Keys.Auth = "", ""
+	}
+	return subscriptions, nil
+}
+
+// Subscribe saves the push subscription of a browser of a user.
+func (r *Reminders) Subscribe(ctx context.Context, subscription *PushSubscription) error {
+	if err := subscription.validate(); err != nil {
+		return err
+	}
+	subscriptions, err := r.store.ListSubscriptions(ctx, subscription.UserID)
+	if err != nil {
+		return err
+	}
+	known := slices.ContainsFunc(subscriptions, func(s *PushSubscription) bool { return s.Endpoint == subscription.Endpoint })
+	if !known && len(subscriptions) >= maxSubscriptions {
+		return fmt.Errorf("%w: at most %d browsers may subscribe, unsubscribe one first", ErrInvalid, maxSubscriptions)
+	}
+	return r.store.SaveSubscription(ctx, subscription)
+}
+
+// Unsubscribe deletes a push subscription of a user.
+func (r *Reminders) Unsubscribe(ctx context.Context, userID int32, endpoint string) error {
+	return r.store.DeleteSubscription(ctx, userID, endpoint)
+}
+
+// Deliveries returns deliveries.
+func (r *Reminders) Deliveries(ctx context.Context, find *FindDelivery) ([]*Delivery, error) {
+	return r.store.ListDeliveries(ctx, find)
+}
+
+// Prune deletes the deliveries and escalations older than DeliveryRetention.
+func (r *Reminders) Prune(ctx context.Context) error {
+	before := r.now().Add(-DeliveryRetention).Unix()
+	if err := r.store.PruneEscalations(ctx, before); err != nil {
+		return err
+	}
+	return r.store.PruneDeliveries(ctx, before)
+}
+
+// Dispatch sends the reminders due, escalates the critical ones not
+// acknowledged in time, and retries the failed deliveries.
+func (r *Reminders) Dispatch(ctx context.Context) error {
+	now := r.now()
+	var errs []error
+	settings, err := r.store.ListEnabledSettings(ctx)
+	if err != nil {
+		return err
+	}
+	for _, setting := range settings {
+		if err := r.dispatch(ctx, setting, now); err != nil {
+			errs = append(errs, fmt.Errorf("user %d: %w", setting.UserID, err))
+		}
+	}
+	if err := r.escalate(ctx, now); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.retry(ctx, now); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// dispatch sends the reminders of a user due at now, or missed by less than
+// catchUp.
+func (r *Reminders) dispatch(ctx context.Context, settings *Settings, now time.Time) error {
+	occurrences, err := r.schedules.Occurrences(ctx, settings.UserID, now.Add(-catchUp), now.Add(MaxLead))
+	if err != nil {
+		return err
+	}
+	critical, err := r.store.ListCritical(ctx, settings.UserID)
+	if err != nil {
+		return err
+	}
+	for _, occurrence := range occurrences {
+		leads := Leads(occurrence.Reminders)
+		if leads == nil {
+			leads = settings.LeadMinutes
+		}
+		for _, lead := range leads {
+			remindTs := occurrence.StartTs - int64(lead)*60
+			if remindTs > now.Unix() || remindTs <= now.Add(-catchUp).Unix() {
+				continue
+			}
+			notification := &Notification{
+				ScheduleID:  occurrence.ScheduleID,
+				UID:         occurrence.UID,
+				Title:       occurrence.Title,
+				Location:    occurrence.Location,
+				Timezone:    occurrence.Timezone,
+				StartTs:     occurrence.StartTs,
+				EndTs:       occurrence.EndTs,
+				AllDay:      occurrence.AllDay,
+				LeadMinutes: lead,
+			}
+			if slices.Contains(critical, occurrence.UID) && len(settings.EscalationChannels) > 0 {
+				if err := r.startEscalation(ctx, settings, notification, now); err != nil {
+					return err
+				}
+				continue
+			}
+			for _, channel := range settings.Channels {
+				delivery := &Delivery{
+					UserID:       settings.UserID,
+					ScheduleUID:  occurrence.UID,
+					StartTs:      occurrence.StartTs,
+					LeadMinutes:  lead,
+					Channel:      channel,
+					Status:       StatusPending,
+					Notification: notification,
+				}
+				created, err := r.store.CreateDelivery(ctx, delivery)
+				if err != nil {
+					return err
+				}
+				if created {
+					r.deliver(ctx, delivery, now, false)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// retry attempts the failed deliveries again.
+func (r *Reminders) retry(ctx context.Context, now time.Time) error {
+	deliveries, err := r.store.ListDeliveries(ctx, &FindDelivery{Retryable: true, Now: now.Unix(), Limit: retryBatchSize})
+	if err != nil {
+		return err
+	}
+	for _, delivery := range deliveries {
+		r.deliver(ctx, delivery, now, false)
+	}
+	return nil
+}
+
+// deliver attempts a delivery and saves its outcome; critical deliveries
+// ignore quiet hours.
+func (r *Reminders) deliver(ctx context.Context, delivery *Delivery, now time.Time, critical bool) {
+	channel, ok := r.channels[delivery.Channel]
+	switch {
+	case !ok:
+		delivery.Status, delivery.Error = StatusSkipped, "the channel is not configured"
+	case !critical && delivery.Channel != ChannelWebhook && r.quietHours(ctx, delivery.UserID, now):
+		delivery.Status, delivery.Error = StatusSkipped, "quiet hours"
+	default:
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err := channel.Send(sendCtx, delivery.UserID, delivery.Notification)
+		cancel()
+		delivery.Attempts++
+		switch {
+		case err == nil:
+			delivery.Status, delivery.Error, delivery.SentTs = StatusSent, "", now.Unix()
+		case errors.Is(err, ErrUnavailable):
+			delivery.Status, delivery.Error = StatusSkipped, truncate(err.Error())
+		default:
+			delivery.Status, delivery.Error = StatusFailed, truncate(err.Error())
+		}
+	}
+	if err := r.store.UpdateDelivery(ctx, delivery); err != nil {
+		slog.Error("failed to save reminder delivery", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// quietHours reports whether a user is in their quiet hours, logging failures.
+func (r *Reminders) quietHours(ctx context.Context, userID int32, now time.Time) bool {
+	if r.quiet == nil {
+		return false
+	}
+	until, err := r.quiet.QuietUntil(ctx, userID, now)
+	if err != nil {
+		slog.Warn("failed to get quiet hours", "user_id", userID, "error", err)
+		return false
+	}
+	return !until.IsZero()
+}
+
+// Leads returns the lead times, in minutes, of the JSON reminders of a
+// schedule, e.g. [{"type":"before","value":15,"unit":"minutes"}], or nil
+// when it has none.
+func Leads(reminders string) []int32 {
+	var parsed []struct {
+		Type  string `json:"type"`
+		Value int32  `json:"value"`
+		Unit  string `json:"unit"`
+	}
+	if err := json.Unmarshal([]byte(reminders), &parsed); err != nil || len(parsed) == 0 {
+		return nil
+	}
+	leads := []int32{}
+	for _, reminder := range parsed {
+		var lead int32
+		switch reminder.Type {
+		case "at":
+		case "before":
+			unit := map[string]int32{"minutes": 1, "hours": 60, "days": 24 * 60}[reminder.Unit]
+			if unit == 0 || reminder.Value < 0 || reminder.Value > int32(MaxLead/time.Minute) {
+				continue
+			}
+			lead = reminder.Value * unit
+		default:
+			continue
+		}
+		if time.Duration(lead)*time.Minute <= MaxLead && !slices.Contains(leads, lead) {
+			leads = append(leads, lead)
+		}
+	}
+	return leads
+}
+
+// formatLead returns a lead time for people, empty for none.
+func formatLead(minutes int32) string {
+	plural := func(n int32, unit string) string {
+		if n == 1 {
+			return "1 " + unit
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+	switch {
+	case minutes <= 0:
+		return ""
+	case minutes%(24*60) == 0:
+		return plural(minutes/(24*60), "day")
+	case minutes%60 == 0:
+		return plural(minutes/60, "hour")
+	default:
+		return plural(minutes, "minute")
+	}
+}
+
+// truncate bounds an error kept on a delivery.
+func truncate(s string) string {
+	if len(s) <= maxErrorLength {
+		return s
+	}
+	return strings.ToValidUTF8(s[:maxErrorLength], "")
+}
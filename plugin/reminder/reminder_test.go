@@ -0,0 +1,723 @@
+package reminder
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeads(t *testing.T) {
+	assert.Nil(t, Leads(""))
+	assert.Nil(t, Leads("[]"))
+	assert.Nil(t, Leads("not json"))
+	assert.Equal(t, []int32{0, 15, 120, 1440}, Leads(`[
+		{"type":"at"},
+		{"type":"before","value":15,"unit":"minutes"},
+		{"type":"before","value":2,"unit":"hours"},
+		{"type":"before","value":1,"unit":"days"},
+		{"type":"before","value":15,"unit":"minutes"},
+		{"type":"before","value":30,"unit":"weeks"},
+		{"type":"before","value":8,"unit":"days"}
+	]`))
+}
+
+func TestSettingsValidate(t *testing.T) {
+	available := []string{ChannelTelegram, ChannelWebPush}
+	settings := &Settings{Enabled: true, Channels: []string{ChannelTelegram, ChannelTelegram}, LeadMinutes: []int32{60, 10, 60}}
+	require.NoError(t, settings.validate(available))
+	assert.Equal(t, []string{ChannelTelegram}, settings.Channels)
+	assert.Equal(t, []int32{10, 60}, settings.LeadMinutes)
+
+	assert.ErrorIs(t, (&Settings{Channels: []string{ChannelEmail}}).validate(available), ErrInvalid)
+	assert.ErrorIs(t, (&Settings{Enabled: true}).validate(available), ErrInvalid)
+	assert.NoError(t, (&Settings{}).validate(available), "disabled settings need no channel")
+	assert.ErrorIs(t, (&Settings{LeadMinutes: []int32{-1}}).validate(available), ErrInvalid)
+	assert.ErrorIs(t, (&Settings{LeadMinutes: []int32{7*24*60 + 1}}).validate(available), ErrInvalid)
+	assert.ErrorIs(t, (&Settings{LeadMinutes: []int32{1, 2, 3, 4, 5, 6}}).validate(available), ErrInvalid)
+
+	assert.Equal(t, int32(defaultEscalationMinutes), settings.EscalationMinutes)
+	assert.ErrorIs(t, (&Settings{EscalationChannels: []string{ChannelEmail}}).validate(available), ErrInvalid)
+	assert.ErrorIs(t, (&Settings{EscalationChannels: []string{ChannelTelegram, ChannelTelegram}}).validate(available), ErrInvalid,
+		"a chain repeats no channel")
+	assert.ErrorIs(t, (&Settings{EscalationMinutes: 121}).validate(available), ErrInvalid)
+}
+
+func TestGetSettingsDefaults(t *testing.T) {
+	st := newMemStore()
+	r := New(st, &fakeSchedules{}, nil)
+	r.Register(ChannelEmail, &fakeChannel{})
+	settings, err := r.GetSettings(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Empty(t, settings.Channels, "web push is not configured")
+	assert.Equal(t, []string{ChannelEmail}, settings.EscalationChannels)
+	_, err = r.SaveSettings(context.Background(), settings)
+	assert.NoError(t, err, "defaults are saved back as is")
+}
+
+func TestNotificationText(t *testing.T) {
+	start := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC).Unix()
+	n := &Notification{Title: "Standup", Location: "Room 1", Timezone: "Asia/Shanghai", StartTs: start, LeadMinutes: 15}
+	assert.Equal(t, "Reminder: Standup\nMon, Oct 19 2026 17:30 (Asia/Shanghai), in 15 minutes\nLocation: Room 1", n.Text())
+
+	n = &Notification{Title: "Trip", AllDay: true, StartTs: start, LeadMinutes: 1440}
+	assert.Equal(t, "Mon, Oct 19 2026, all day, in 1 day", n.Body())
+	n.LeadMinutes = 0
+	assert.Equal(t, "Mon, Oct 19 2026, all day", n.Body())
+
+	n.EscalationID = 3
+	assert.Equal(t, "Critical reminder: Trip", n.Subject())
+	assert.Equal(t, "Mon, Oct 19 2026, all day\nAcknowledge this reminder in DivineSense to stop it from escalating.", n.Body())
+}
+
+func TestDispatch(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	st := newMemStore()
+	st.settings[7] = &Settings{UserID: 7, Enabled: true, Channels: []string{ChannelTelegram, ChannelEmail, ChannelWebPush}, LeadMinutes: []int32{30}}
+	schedules := &fakeSchedules{occurrences: []*Occurrence{
+		// default lead time: due at 09:00
+		{ScheduleID: 1, UID: "standup", Title: "Standup", StartTs: now.Add(30 * time.Minute).Unix()},
+		// own reminder: due 5 minutes ago, within the catch up
+		{ScheduleID: 2, UID: "review", Title: "Review", StartTs: now.Add(10 * time.Minute).Unix(),
+			Reminders: `[{"type":"before","value":15,"unit":"minutes"}]`},
+		// own reminder, not due yet
+		{ScheduleID: 3, UID: "lunch", Title: "Lunch", StartTs: now.Add(3 * time.Hour).Unix(),
+			Reminders: `[{"type":"before","value":1,"unit":"hours"}]`},
+		// missed by more than the catch up
+		{ScheduleID: 4, UID: "early", Title: "Early", StartTs: now.Add(15 * time.Minute).Unix()},
+	}}
+	telegram := &fakeChannel{}
+	email := &fakeChannel{err: fmt.Errorf("%w: no email address", ErrUnavailable)}
+	r := New(st, schedules, nil)
+	r.now = func() time.Time { return now }
+	r.Register(ChannelTelegram, telegram)
+	r.Register(ChannelEmail, email)
+
+	require.NoError(t, r.Dispatch(ctx))
+	assert.Equal(t, []string{"standup:30", "review:15"}, telegram.sent)
+	assert.Equal(t, now.Add(-catchUp), schedules.from)
+	assert.Equal(t, now.Add(MaxLead), schedules.to)
+
+	deliveries := st.byChannel()
+	assert.Equal(t, []string{StatusSent, StatusSent}, deliveries[ChannelTelegram])
+	assert.Equal(t, []string{StatusSkipped, StatusSkipped}, deliveries[ChannelEmail])
+	assert.Equal(t, []string{StatusSkipped, StatusSkipped}, deliveries[ChannelWebPush], "web push is not configured")
+
+	// A second run sends nothing again
+	require.NoError(t, r.Dispatch(ctx))
+	assert.Len(t, telegram.sent, 2)
+	assert.Len(t, st.deliveries, 6)
+}
+
+func TestDispatchRetriesFailures(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	st := newMemStore()
+	st.clock = now.Unix()
+	st.settings[7] = &Settings{UserID: 7, Enabled: true, Channels: []string{ChannelTelegram}, LeadMinutes: []int32{10}}
+	schedules := &fakeSchedules{occurrences: []*Occurrence{
+		{ScheduleID: 1, UID: "standup", Title: "Standup", StartTs: now.Add(10 * time.Minute).Unix()},
+	}}
+	telegram := &fakeChannel{err: errors.New("bot API down")}
+	r := New(st, schedules, nil)
+	r.now = func() time.Time { return now }
+	r.Register(ChannelTelegram, telegram)
+
+	require.NoError(t, r.Dispatch(ctx))
+	delivery := st.deliveries[0]
+	assert.Equal(t, StatusFailed, delivery.Status)
+	assert.Equal(t, int32(1), delivery.Attempts)
+	assert.Equal(t, "bot API down", delivery.Error)
+
+	// Not retried before the retry delay
+	require.NoError(t, r.Dispatch(ctx))
+	assert.Empty(t, telegram.sent)
+	assert.Equal(t, int32(1), delivery.Attempts)
+
+	telegram.err = nil
+	now = now.Add(2 * time.Minute)
+	require.NoError(t, r.Dispatch(ctx))
+	assert.Equal(t, StatusSent, delivery.Status)
+	assert.Equal(t, int32(2), delivery.Attempts)
+	assert.Empty(t, delivery.Error)
+	assert.Equal(t, now.Unix(), delivery.SentTs)
+	assert.Len(t, st.deliveries, 1)
+}
+
+func TestDispatchQuietHours(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC)
+	st := newMemStore()
+	st.settings[7] = &Settings{UserID: 7, Enabled: true, Channels: []string{ChannelTelegram, ChannelWebhook}, LeadMinutes: []int32{0}}
+	schedules := &fakeSchedules{occurrences: []*Occurrence{{ScheduleID: 1, UID: "run", Title: "Run", StartTs: now.Unix()}}}
+	telegram, hook := &fakeChannel{}, &fakeChannel{}
+	r := New(st, schedules, quietUntil(now.Add(time.Hour)))
+	r.now = func() time.Time { return now }
+	r.Register(ChannelTelegram, telegram)
+	r.Register(ChannelWebhook, hook)
+
+	require.NoError(t, r.Dispatch(ctx))
+	assert.Empty(t, telegram.sent)
+	assert.Equal(t, []string{"run:0"}, hook.sent, "webhooks are not notifications")
+	assert.Equal(t, "quiet hours", st.deliveries[0].Error)
+}
+
+func TestDispatchEscalation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC)
+	st := newMemStore()
+	st.settings[7] = &Settings{UserID: 7, Enabled: true, Channels: []string{ChannelTelegram}, LeadMinutes: []int32{15},
+		EscalationChannels: []string{ChannelWebPush, ChannelTelegram, ChannelEmail}, EscalationMinutes: 5}
+	st.critical[7] = []string{"flight"}
+	schedules := &fakeSchedules{occurrences: []*Occurrence{
+		{ScheduleID: 1, UID: "flight", Title: "Flight", StartTs: now.Add(15 * time.Minute).Unix()},
+		{ScheduleID: 2, UID: "standup", Title: "Standup", StartTs: now.Add(15 * time.Minute).Unix()},
+	}}
+	telegram, email := &fakeChannel{}, &fakeChannel{}
+	r := New(st, schedules, quietUntil(now.Add(time.Hour)))
+	r.now = func() time.Time { return now }
+	r.Register(ChannelTelegram, telegram)
+	r.Register(ChannelEmail, email)
+
+	// Web push is not configured: Telegram is notified at once, even during
+	// quiet hours, unlike the regular schedule
+	require.NoError(t, r.Dispatch(ctx))
+	assert.Equal(t, []string{"flight:15"}, telegram.sent)
+	require.Len(t, st.escalations, 1)
+	escalation := st.escalations[0]
+	assert.Equal(t, EscalationActive, escalation.Status)
+	assert.Equal(t, int32(2), escalation.Step)
+	assert.Equal(t, now.Add(5*time.Minute).Unix(), escalation.NextTs)
+	assert.Equal(t, map[string][]string{
+		ChannelWebPush:  {StatusSkipped},
+		ChannelTelegram: {StatusSent, StatusSkipped},
+	}, st.byChannel())
+
+	now = now.Add(time.Minute)
+	require.NoError(t, r.Dispatch(ctx))
+	assert.Empty(t, email.sent, "not escalated before the delay")
+
+	now = now.Add(4 * time.Minute)
+	require.NoError(t, r.Dispatch(ctx))
+	assert.Equal(t, []string{"flight:15"}, email.sent)
+	assert.Equal(t, int32(3), st.escalations[0].Step)
+
+	acknowledged, err := r.Acknowledge(ctx, 7, escalation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, EscalationAcknowledged, acknowledged.Status)
+	assert.Equal(t, now.Unix(), acknowledged.AcknowledgedTs)
+	_, err = r.Acknowledge(ctx, 8, escalation.ID)
+	assert.ErrorIs(t, err, ErrEscalationNotFound, "escalations of other users are unknown")
+
+	now = now.Add(10 * time.Minute)
+	require.NoError(t, r.Dispatch(ctx))
+	assert.Equal(t, EscalationAcknowledged, st.escalations[0].Status)
+	assert.Len(t, st.escalations, 1)
+}
+
+func TestDispatchEscalationExhausted(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	st := newMemStore()
+	st.settings[7] = &Settings{UserID: 7, Enabled: true, Channels: []string{ChannelTelegram}, LeadMinutes: []int32{30},
+		EscalationChannels: []string{ChannelTelegram, ChannelEmail}, EscalationMinutes: 10}
+	st.critical[7] = []string{"exam"}
+	schedules := &fakeSchedules{occurrences: []*Occurrence{
+		{ScheduleID: 1, UID: "exam", Title: "Exam", StartTs: now.Add(30 * time.Minute).Unix()},
+	}}
+	telegram, email := &fakeChannel{}, &fakeChannel{err: errors.New("SMTP down")}
+	r := New(st, schedules, nil)
+	r.now = func() time.Time { return now }
+	r.Register(ChannelTelegram, telegram)
+	r.Register(ChannelEmail, email)
+
+	require.NoError(t, r.Dispatch(ctx))
+	now = now.Add(10 * time.Minute)
+	require.NoError(t, r.Dispatch(ctx))
+	escalation := st.escalations[0]
+	assert.Equal(t, EscalationExhausted, escalation.Status, "the last channel failed")
+	assert.Equal(t, int32(2), escalation.Step)
+	assert.Equal(t, []string{StatusFailed}, st.byChannel()[ChannelEmail])
+
+	// Failed escalation steps are not retried
+	now = now.Add(2 * time.Minute)
+	require.NoError(t, r.Dispatch(ctx))
+	assert.Equal(t, int32(1), st.deliveries[1].Attempts)
+
+	// Exhausted escalations may still be acknowledged
+	acknowledged, err := r.Acknowledge(ctx, 7, escalation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, EscalationAcknowledged, acknowledged.Status)
+}
+
+func TestSetCritical(t *testing.T) {
+	ctx := context.Background()
+	st := newMemStore()
+	r := New(st, &fakeSchedules{titles: map[string]string{"flight": "Flight"}}, nil)
+
+	title, err := r.SetCritical(ctx, 7, "flight", true)
+	require.NoError(t, err)
+	assert.Equal(t, "Flight", title)
+	uids, err := r.Critical(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"flight"}, uids)
+
+	_, err = r.SetCritical(ctx, 7, "unknown", true)
+	assert.ErrorIs(t, err, ErrScheduleNotFound)
+	_, err = r.SetCritical(ctx, 7, " ", true)
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	_, err = r.SetCritical(ctx, 7, "flight", false)
+	require.NoError(t, err)
+	uids, err = r.Critical(ctx, 7)
+	require.NoError(t, err)
+	assert.Empty(t, uids)
+}
+
+func TestEscalationsTool(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	st := newMemStore()
+	st.settings[7] = &Settings{UserID: 7, Enabled: true, UpdatedTs: 1,
+		EscalationChannels: []string{ChannelWebPush, ChannelTelegram}, EscalationMinutes: 5}
+	st.critical[7] = []string{"flight"}
+	st.escalations = []*Escalation{{
+		ID: 4, UserID: 7, ScheduleUID: "flight", StartTs: now.Add(15 * time.Minute).Unix(), LeadMinutes: 15,
+		Channels: []string{ChannelWebPush, ChannelTelegram}, DelayMinutes: 5, Step: 1, Status: EscalationActive,
+		NextTs: now.Add(5 * time.Minute).Unix(), Notification: &Notification{UID: "flight", Title: "Flight"}, UpdatedTs: now.Unix(),
+	}}
+	r := New(st, &fakeSchedules{}, nil)
+	r.now = func() time.Time { return now }
+	tool := r.EscalationsToolFor(7)
+
+	out, err := tool.Run(ctx, `{"timezone":"UTC"}`)
+	require.NoError(t, err)
+	assert.Equal(t, `Escalations:
+- [4] "Flight" at Mon Oct 19 09:15, active; notified via web_push; escalates to telegram at 09:05
+Escalation chain: web_push → telegram, every 5 minutes until acknowledged.
+Critical schedules: 1 (UIDs: flight)`, out)
+
+	out, err = tool.Run(ctx, `{"acknowledge":4,"timezone":"UTC"}`)
+	require.NoError(t, err)
+	assert.Equal(t, `✓ Acknowledged: [4] "Flight" at Mon Oct 19 09:15, acknowledged; notified via web_push at 09:00`, out)
+}
+
+func TestSubscribe(t *testing.T) {
+	ctx := context.Background()
+	st := newMemStore()
+	r := New(st, &fakeSchedules{}, nil)
+	browser := newBrowser(t)
+
+	subscription := browser.subscription("https://push.example.com/send/1")
+	require.NoError(t, r.Subscribe(ctx, subscription))
+	listed, err := r.Subscriptions(ctx, 7)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Empty(t, listed[0].Keys.Auth, "keys are not listed")
+	assert.NotEmpty(t, st.subscriptions[subscription.Endpoint].Keys.Auth)
+
+	invalid := browser.subscription("http://push.example.com/send/2")
+	assert.ErrorIs(t, r.Subscribe(ctx, invalid), ErrInvalid)
+	invalid = browser.subscription("https://push.example.com/send/2")
+	invalid.Keys.P256dh = base64.RawURLEncoding.EncodeToString(make([]byte, 65))
+	assert.ErrorIs(t, r.Subscribe(ctx, invalid), ErrInvalid)
+
+	require.NoError(t, r.Unsubscribe(ctx, 7, subscription.Endpoint))
+	assert.ErrorIs(t, r.Unsubscribe(ctx, 7, subscription.Endpoint), ErrNotFound)
+}
+
+func TestWebPush(t *testing.T) {
+	ctx := context.Background()
+	st := newMemStore()
+	browser := newBrowser(t)
+	vapid, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	private, err := vapid.Bytes()
+	require.NoError(t, err)
+
+	var received [][]byte
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		assert.Equal(t, "aes128gcm", r.Header.Get("Content-Encoding"))
+		authorization = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+
+	_, err = NewWebPush(WebPushConfig{PrivateKey: "invalid", Subject: "mailto:admin@example.com"}, st)
+	assert.Error(t, err)
+	push, err := NewWebPush(WebPushConfig{PrivateKey: base64.RawURLEncoding.EncodeToString(private), Subject: "mailto:admin@example.com"}, st)
+	require.NoError(t, err)
+	push.http = server.Client()
+
+	notification := &Notification{UID: "standup", Title: "Standup", StartTs: 1792400000, LeadMinutes: 15}
+	assert.ErrorIs(t, push.Send(ctx, 7, notification), ErrUnavailable)
+
+	for _, path := range []string{"/ok", "/gone"} {
+		subscription := browser.subscription(server.URL + path)
+		require.NoError(t, st.SaveSubscription(ctx, subscription))
+	}
+	require.NoError(t, push.Send(ctx, 7, notification))
+	assert.NotContains(t, st.subscriptions, server.URL+"/gone", "expired subscriptions are deleted")
+
+	require.Len(t, received, 1)
+	var payload struct {
+		Title string        `json:"title"`
+		Body  string        `json:"body"`
+		Data  *Notification `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(browser.decrypt(t, received[0]), &payload))
+	assert.Equal(t, "Reminder: Standup", payload.Title)
+	assert.Equal(t, "standup", payload.Data.UID)
+
+	// The VAPID token is signed for the push service by the public key
+	token, key, ok := strings.Cut(strings.TrimPrefix(authorization, "vapid t="), ", k=")
+	require.True(t, ok)
+	assert.Equal(t, push.PublicKey(), key)
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+	claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	assert.Contains(t, string(claims), `"aud":"`+server.URL+`"`)
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+	assert.True(t, ecdsa.Verify(&vapid.PublicKey, digest[:], r, s))
+}
+
+// browser is a browser subscribing to web push notifications.
+type browser struct {
+	key  *ecdh.PrivateKey
+	auth []byte
+}
+
+func newBrowser(t *testing.T) *browser {
+	t.Helper()
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	auth := make([]byte, 16)
+	_, _ = rand.Read(auth)
+	return &browser{key: key, auth: auth}
+}
+
+func (b *browser) subscription(endpoint string) *PushSubscription {
+	subscription := &PushSubscription{UserID: 7, Endpoint: endpoint}
+	subscription.Keys.P256dh = base64.RawURLEncoding.EncodeToString(b.key.PublicKey().Bytes())
+	subscription.Keys.Auth = base64.RawURLEncoding.EncodeToString(b.auth)
+	return subscription
+}
+
+// decrypt decrypts a notification as browsers do (RFC 8291).
+func (b *browser) decrypt(t *testing.T, body []byte) []byte {
+	t.Helper()
+	require.Greater(t, len(body), 21)
+	salt, recordSize, idLength := body[:16], binary.BigEndian.Uint32(body[16:20]), int(body[20])
+	assert.Equal(t, uint32(pushRecordSize), recordSize)
+	serverKey, ciphertext := body[21:21+idLength], body[21+idLength:]
+	serverPublic, err := ecdh.P256().NewPublicKey(serverKey)
+	require.NoError(t, err)
+	secret, err := b.key.ECDH(serverPublic)
+	require.NoError(t, err)
+	ikm, err := hkdf.Key(sha256.New, secret, b.auth, "WebPush: info\x00"+string(b.key.PublicKey().Bytes())+string(serverKey), 32)
+	require.NoError(t, err)
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	require.NoError(t, err)
+	contentKey, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	require.NoError(t, err)
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	require.NoError(t, err)
+	block, err := aes.NewCipher(contentKey)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	require.NoError(t, err)
+	require.Equal(t, byte(2), plaintext[len(plaintext)-1], "last record delimiter")
+	return plaintext[:len(plaintext)-1]
+}
+
+type fakeSchedules struct {
+	occurrences []*Occurrence
+	titles      map[string]string
+	from, to    time.Time
+}
+
+func (f *fakeSchedules) Title(_ context.Context, _ int32, uid string) (string, error) {
+	title, ok := f.titles[uid]
+	if !ok {
+		return "", ErrScheduleNotFound
+	}
+	return title, nil
+}
+
+func (f *fakeSchedules) Occurrences(_ context.Context, _ int32, from, to time.Time) ([]*Occurrence, error) {
+	f.from, f.to = from, to
+	return f.occurrences, nil
+}
+
+type fakeChannel struct {
+	err  error
+	sent []string
+}
+
+func (f *fakeChannel) Send(_ context.Context, _ int32, n *Notification) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, fmt.Sprintf("%s:%d", n.UID, n.LeadMinutes))
+	return nil
+}
+
+type quietUntil time.Time
+
+func (q quietUntil) QuietUntil(context.Context, int32, time.Time) (time.Time, error) {
+	return time.Time(q), nil
+}
+
+// memStore is an in-memory Store.
+type memStore struct {
+	mu            sync.Mutex
+	settings      map[int32]*Settings
+	subscriptions map[string]*PushSubscription
+	deliveries    []*Delivery
+	critical      map[int32][]string
+	escalations   []*Escalation
+	// clock is the update time of deliveries, the wall clock when 0.
+	clock int64
+}
+
+func newMemStore() *memStore {
+	return &memStore{settings: map[int32]*Settings{}, subscriptions: map[string]*PushSubscription{}, critical: map[int32][]string{}}
+}
+
+func (s *memStore) now() int64 {
+	if s.clock != 0 {
+		return s.clock
+	}
+	return time.Now().Unix()
+}
+
+func (s *memStore) GetSettings(_ context.Context, userID int32) (*Settings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if settings, ok := s.settings[userID]; ok {
+		return settings, nil
+	}
+	return DefaultSettings(userID), nil
+}
+
+func (s *memStore) SaveSettings(_ context.Context, settings *Settings) (*Settings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settings[settings.UserID] = settings
+	return settings, nil
+}
+
+func (s *memStore) ListEnabledSettings(context.Context) ([]*Settings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var list []*Settings
+	for _, settings := range s.settings {
+		if settings.Enabled {
+			list = append(list, settings)
+		}
+	}
+	return list, nil
+}
+
+func (s *memStore) ListSubscriptions(_ context.Context, userID int32) ([]*PushSubscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := []*PushSubscription{}
+	for _, subscription := range s.subscriptions {
+		if subscription.UserID == userID {
+			copied := *subscription
+			list = append(list, &copied)
+		}
+	}
+	return list, nil
+}
+
+func (s *memStore) SaveSubscription(_ context.Context, subscription *PushSubscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *subscription
+	s.subscriptions[subscription.Endpoint] = &copied
+	return nil
+}
+
+func (s *memStore) DeleteSubscription(_ context.Context, userID int32, endpoint string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if subscription, ok := s.subscriptions[endpoint]; !ok || subscription.UserID != userID {
+		return ErrNotFound
+	}
+	delete(s.subscriptions, endpoint)
+	return nil
+}
+
+func (s *memStore) CreateDelivery(_ context.Context, delivery *Delivery) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range s.deliveries {
+		if d.UserID == delivery.UserID && d.ScheduleUID == delivery.ScheduleUID && d.StartTs == delivery.StartTs &&
+			d.LeadMinutes == delivery.LeadMinutes && d.Channel == delivery.Channel {
+			return false, nil
+		}
+	}
+	delivery.ID = int64(len(s.deliveries) + 1)
+	delivery.CreatedTs, delivery.UpdatedTs = s.now(), s.now()
+	s.deliveries = append(s.deliveries, delivery)
+	return true, nil
+}
+
+func (s *memStore) UpdateDelivery(_ context.Context, delivery *Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delivery.UpdatedTs = s.now()
+	return nil
+}
+
+func (s *memStore) ListDeliveries(_ context.Context, find *FindDelivery) ([]*Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := []*Delivery{}
+	for _, d := range s.deliveries {
+		if find.Retryable && (d.Status != StatusFailed || d.Attempts >= MaxAttempts ||
+			d.UpdatedTs > find.Now-int64(retryDelay/time.Second) || d.StartTs <= find.Now || d.EscalationID != 0) {
+			continue
+		}
+		list = append(list, d)
+	}
+	return list, nil
+}
+
+func (s *memStore) PruneDeliveries(_ context.Context, beforeTs int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deliveries = slices.DeleteFunc(s.deliveries, func(d *Delivery) bool { return d.CreatedTs < beforeTs })
+	return nil
+}
+
+func (s *memStore) ListCritical(_ context.Context, userID int32) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.critical[userID]), nil
+}
+
+func (s *memStore) SetCritical(_ context.Context, userID int32, scheduleUID string, critical bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	uids := slices.DeleteFunc(s.critical[userID], func(uid string) bool { return uid == scheduleUID })
+	if critical {
+		uids = append(uids, scheduleUID)
+	}
+	s.critical[userID] = uids
+	return nil
+}
+
+func (s *memStore) CreateEscalation(_ context.Context, escalation *Escalation) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.escalations {
+		if e.UserID == escalation.UserID && e.ScheduleUID == escalation.ScheduleUID && e.StartTs == escalation.StartTs &&
+			e.LeadMinutes == escalation.LeadMinutes {
+			return false, nil
+		}
+	}
+	escalation.ID = int64(len(s.escalations) + 1)
+	escalation.CreatedTs, escalation.UpdatedTs = s.now(), s.now()
+	copied := *escalation
+	s.escalations = append(s.escalations, &copied)
+	return true, nil
+}
+
+func (s *memStore) UpdateEscalation(_ context.Context, escalation *Escalation) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.escalations {
+		if e.ID == escalation.ID && e.Status == EscalationActive {
+			e.Step, e.Status, e.NextTs, e.UpdatedTs = escalation.Step, escalation.Status, escalation.NextTs, s.now()
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *memStore) AcknowledgeEscalation(_ context.Context, userID int32, id int64, ts int64) (*Escalation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.escalations {
+		if e.ID == id && e.UserID == userID {
+			if e.Status != EscalationAcknowledged {
+				e.Status, e.NextTs, e.AcknowledgedTs, e.UpdatedTs = EscalationAcknowledged, 0, ts, ts
+			}
+			copied := *e
+			return &copied, nil
+		}
+	}
+	return nil, ErrEscalationNotFound
+}
+
+func (s *memStore) ListEscalations(_ context.Context, find *FindEscalation) ([]*Escalation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := []*Escalation{}
+	for _, e := range s.escalations {
+		if (find.UserID != nil && e.UserID != *find.UserID) || (find.Status != nil && e.Status != *find.Status) ||
+			(find.DueTs != 0 && (e.Status != EscalationActive || e.NextTs > find.DueTs)) {
+			continue
+		}
+		copied := *e
+		list = append(list, &copied)
+	}
+	return list, nil
+}
+
+func (s *memStore) PruneEscalations(_ context.Context, beforeTs int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.escalations = slices.DeleteFunc(s.escalations, func(e *Escalation) bool { return e.CreatedTs < beforeTs })
+	return nil
+}
+
+// byChannel returns the statuses of the deliveries by channel.
+func (s *memStore) byChannel() map[string][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := map[string][]string{}
+	for _, d := range s.deliveries {
+		statuses[d.Channel] = append(statuses[d.Channel], d.Status)
+	}
+	return statuses
+}
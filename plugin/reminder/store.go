@@ -0,0 +1,426 @@
+package reminder
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// DBStore persists the reminders in the reminder_setting,
+// reminder_push_subscription, reminder_delivery, reminder_critical and
+// reminder_escalation tables (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new reminder store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const settingColumns = "user_id, enabled, channels, lead_minutes, escalation_channels, escalation_minutes, updated_ts"
+
+// GetSettings implements Store.
+func (s *DBStore) GetSettings(ctx context.Context, userID int32) (*Settings, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+settingColumns+" FROM reminder_setting WHERE user_id = $1", userID)
+	settings, err := scanSettings(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return DefaultSettings(userID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminder settings: %w", err)
+	}
+	return settings, nil
+}
+
+// SaveSettings implements Store.
+func (s *DBStore) SaveSettings(ctx context.Context, settings *Settings) (*Settings, error) {
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO reminder_setting (user_id, enabled, channels, lead_minutes, escalation_channels, escalation_minutes, updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			channels = EXCLUDED.channels,
+			lead_minutes = EXCLUDED.lead_minutes,
+			escalation_channels = EXCLUDED.escalation_channels,
+			escalation_minutes = EXCLUDED.escalation_minutes,
+			updated_ts = EXCLUDED.updated_ts
+		RETURNING `+settingColumns,
+		settings.UserID, settings.Enabled, pq.Array(settings.Channels), pq.Array(settings.LeadMinutes),
+		pq.Array(settings.EscalationChannels), settings.EscalationMinutes, time.Now().Unix())
+	saved, err := scanSettings(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save reminder settings: %w", err)
+	}
+	return saved, nil
+}
+
+// ListEnabledSettings implements Store.
+func (s *DBStore) ListEnabledSettings(ctx context.Context) ([]*Settings, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+settingColumns+` FROM reminder_setting r
+		WHERE r.enabled AND EXISTS (SELECT 1 FROM "user" u WHERE u.id = r.user_id AND u.row_status = 'NORMAL')
+		ORDER BY r.user_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reminder settings: %w", err)
+	}
+	defer rows.Close()
+	list := []*Settings{}
+	for rows.Next() {
+		settings, err := scanSettings(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reminder settings: %w", err)
+		}
+		list = append(list, settings)
+	}
+	return list, rows.Err()
+}
+
+// ListSubscriptions implements Store.
+func (s *DBStore) ListSubscriptions(ctx context.Context, userID int32) ([]*PushSubscription, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id, endpoint, p256dh, auth, user_agent, created_ts
+		FROM reminder_push_subscription WHERE user_id = $1 ORDER BY created_ts`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list push subscriptions: %w", err)
+	}
+	defer rows.Close()
+	subscriptions := []*PushSubscription{}
+	for rows.Next() {
+		subscription := &PushSubscription{}
+		if err := rows.Scan(&subscription.UserID, &subscription.Endpoint, &subscription.Keys.P256dh, &subscription.Keys.Auth,
+			&subscription.UserAgent, &subscription.CreatedTs); err != nil {
+			return nil, fmt.Errorf("failed to scan push subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, rows.Err()
+}
+
+// SaveSubscription implements Store.
+func (s *DBStore) SaveSubscription(ctx context.Context, subscription *PushSubscription) error {
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO reminder_push_subscription (endpoint, user_id, p256dh, auth, user_agent, created_ts)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (endpoint) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			p256dh = EXCLUDED.p256dh,
+			auth = EXCLUDED.auth,
+			user_agent = EXCLUDED.user_agent`,
+		subscription.Endpoint, subscription.UserID, subscription.Keys.P256dh, subscription.Keys.Auth,
+		subscription.UserAgent, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to save push subscription: %w", err)
+	}
+	return nil
+}
+
+// DeleteSubscription implements Store.
+func (s *DBStore) DeleteSubscription(ctx context.Context, userID int32, endpoint string) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM reminder_push_subscription WHERE user_id = $1 AND endpoint = $2", userID, endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to delete push subscription: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+const deliveryColumns = `id, user_id, schedule_uid, start_ts, lead_minutes, channel, status, attempts, error,
+	notification, escalation_id, created_ts, updated_ts, sent_ts`
+
+// CreateDelivery implements Store.
+func (s *DBStore) CreateDelivery(ctx context.Context, delivery *Delivery) (bool, error) {
+	notification, err := json.Marshal(delivery.Notification)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode notification: %w", err)
+	}
+	now := time.Now().Unix()
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO reminder_delivery (user_id, schedule_uid, start_ts, lead_minutes, channel, status, notification,
+			escalation_id, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+		ON CONFLICT (user_id, schedule_uid, start_ts, lead_minutes, channel) DO NOTHING
+		RETURNING id`,
+		delivery.UserID, delivery.ScheduleUID, delivery.StartTs, delivery.LeadMinutes, delivery.Channel, delivery.Status,
+		notification, delivery.EscalationID, now).Scan(&delivery.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create reminder delivery: %w", err)
+	}
+	delivery.CreatedTs, delivery.UpdatedTs = now, now
+	return true, nil
+}
+
+// UpdateDelivery implements Store.
+func (s *DBStore) UpdateDelivery(ctx context.Context, delivery *Delivery) error {
+	delivery.UpdatedTs = time.Now().Unix()
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE reminder_delivery SET status = $2, attempts = $3, error = $4, sent_ts = $5, updated_ts = $6
+		WHERE id = $1`,
+		delivery.ID, delivery.Status, delivery.Attempts, delivery.Error, delivery.SentTs, delivery.UpdatedTs); err != nil {
+		return fmt.Errorf("failed to update reminder delivery: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries implements Store.
+func (s *DBStore) ListDeliveries(ctx context.Context, find *FindDelivery) ([]*Delivery, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.UserID; v != nil {
+		args = append(args, *v)
+		where = append(where, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+	if v := find.ScheduleUID; v != nil {
+		args = append(args, *v)
+		where = append(where, fmt.Sprintf("schedule_uid = $%d", len(args)))
+	}
+	if v := find.Status; v != nil {
+		args = append(args, *v)
+		where = append(where, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if find.Retryable {
+		args = append(args, StatusFailed, MaxAttempts, find.Now-int64(retryDelay/time.Second), find.Now)
+		n := len(args)
+		where = append(where, fmt.Sprintf("status = $%d AND attempts < $%d AND updated_ts <= $%d AND start_ts > $%d AND escalation_id = 0",
+			n-3, n-2, n-1, n))
+	}
+	query := "SELECT " + deliveryColumns + " FROM reminder_delivery WHERE " + strings.Join(where, " AND ") +
+		" ORDER BY start_ts DESC, id DESC"
+	if find.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", find.Limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reminder deliveries: %w", err)
+	}
+	defer rows.Close()
+	deliveries := []*Delivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reminder delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// PruneDeliveries implements Store.
+func (s *DBStore) PruneDeliveries(ctx context.Context, beforeTs int64) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM reminder_delivery WHERE created_ts < $1", beforeTs); err != nil {
+		return fmt.Errorf("failed to prune reminder deliveries: %w", err)
+	}
+	return nil
+}
+
+// ListCritical implements Store.
+func (s *DBStore) ListCritical(ctx context.Context, userID int32) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT schedule_uid FROM reminder_critical WHERE user_id = $1 ORDER BY created_ts", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list critical schedules: %w", err)
+	}
+	defer rows.Close()
+	uids := []string{}
+	for rows.Next() {
+		var uid string
+		if err := rows.Scan(&uid); err != nil {
+			return nil, fmt.Errorf("failed to scan critical schedule: %w", err)
+		}
+		uids = append(uids, uid)
+	}
+	return uids, rows.Err()
+}
+
+// SetCritical implements Store.
+func (s *DBStore) SetCritical(ctx context.Context, userID int32, scheduleUID string, critical bool) error {
+	var err error
+	if critical {
+		_, err = s.db.ExecContext(ctx, `
+			INSERT INTO reminder_critical (user_id, schedule_uid, created_ts) VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, schedule_uid) DO NOTHING`, userID, scheduleUID, time.Now().Unix())
+	} else {
+		_, err = s.db.ExecContext(ctx, "DELETE FROM reminder_critical WHERE user_id = $1 AND schedule_uid = $2", userID, scheduleUID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set critical schedule: %w", err)
+	}
+	return nil
+}
+
+const escalationColumns = `id, user_id, schedule_uid, start_ts, lead_minutes, channels, delay_minutes, step, status,
+	next_ts, acknowledged_ts, notification, created_ts, updated_ts`
+
+// CreateEscalation implements Store.
+func (s *DBStore) CreateEscalation(ctx context.Context, escalation *Escalation) (bool, error) {
+	notification, err := json.Marshal(escalation.Notification)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode notification: %w", err)
+	}
+	now := time.Now().Unix()
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO reminder_escalation (user_id, schedule_uid, start_ts, lead_minutes, channels, delay_minutes, step, status,
+			next_ts, notification, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $11)
+		ON CONFLICT (user_id, schedule_uid, start_ts, lead_minutes) DO NOTHING
+		RETURNING id`,
+		escalation.UserID, escalation.ScheduleUID, escalation.StartTs, escalation.LeadMinutes, pq.Array(escalation.Channels),
+		escalation.DelayMinutes, escalation.Step, escalation.Status, escalation.NextTs, notification, now).Scan(&escalation.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create reminder escalation: %w", err)
+	}
+	escalation.CreatedTs, escalation.UpdatedTs = now, now
+	return true, nil
+}
+
+// UpdateEscalation implements Store.
+func (s *DBStore) UpdateEscalation(ctx context.Context, escalation *Escalation) (bool, error) {
+	escalation.UpdatedTs = time.Now().Unix()
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE reminder_escalation SET step = $2, status = $3, next_ts = $4, updated_ts = $5
+		WHERE id = $1 AND status = $6`,
+		escalation.ID, escalation.Step, escalation.Status, escalation.NextTs, escalation.UpdatedTs, EscalationActive)
+	if err != nil {
+		return false, fmt.Errorf("failed to update reminder escalation: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to update reminder escalation: %w", err)
+	}
+	return n > 0, nil
+}
+
+// AcknowledgeEscalation implements Store.
+func (s *DBStore) AcknowledgeEscalation(ctx context.Context, userID int32, id int64, ts int64) (*Escalation, error) {
+	row := s.db.QueryRowContext(ctx, `
+		UPDATE reminder_escalation SET status = $3, next_ts = 0, acknowledged_ts = $4, updated_ts = $4
+		WHERE id = $1 AND user_id = $2 AND status <> $3
+		RETURNING `+escalationColumns,
+		id, userID, EscalationAcknowledged, ts)
+	escalation, err := scanEscalation(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Unknown, or already acknowledged.
+		row = s.db.QueryRowContext(ctx, "SELECT "+escalationColumns+" FROM reminder_escalation WHERE id = $1 AND user_id = $2", id, userID)
+		escalation, err = scanEscalation(row)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrEscalationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to acknowledge reminder escalation: %w", err)
+	}
+	return escalation, nil
+}
+
+// ListEscalations implements Store.
+func (s *DBStore) ListEscalations(ctx context.Context, find *FindEscalation) ([]*Escalation, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.UserID; v != nil {
+		args = append(args, *v)
+		where = append(where, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+	if v := find.ScheduleUID; v != nil {
+		args = append(args, *v)
+		where = append(where, fmt.Sprintf("schedule_uid = $%d", len(args)))
+	}
+	if v := find.Status; v != nil {
+		args = append(args, *v)
+		where = append(where, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if find.DueTs != 0 {
+		args = append(args, EscalationActive, find.DueTs)
+		where = append(where, fmt.Sprintf("status = $%d AND next_ts <= $%d", len(args)-1, len(args)))
+	}
+	query := "SELECT " + escalationColumns + " FROM reminder_escalation WHERE " + strings.Join(where, " AND ") +
+		" ORDER BY created_ts DESC, id DESC"
+	if find.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", find.Limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reminder escalations: %w", err)
+	}
+	defer rows.Close()
+	escalations := []*Escalation{}
+	for rows.Next() {
+		escalation, err := scanEscalation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reminder escalation: %w", err)
+		}
+		escalations = append(escalations, escalation)
+	}
+	return escalations, rows.Err()
+}
+
+// PruneEscalations implements Store.
+func (s *DBStore) PruneEscalations(ctx context.Context, beforeTs int64) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM reminder_escalation WHERE created_ts < $1", beforeTs); err != nil {
+		return fmt.Errorf("failed to prune reminder escalations: %w", err)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSettings(row rowScanner) (*Settings, error) {
+	settings := &Settings{}
+	if err := row.Scan(&settings.UserID, &settings.Enabled, pq.Array(&settings.Channels), pq.Array(&settings.LeadMinutes),
+		pq.Array(&settings.EscalationChannels), &settings.EscalationMinutes, &settings.UpdatedTs); err != nil {
+		return nil, err
+	}
+	if settings.Channels == nil {
+		settings.Channels = []string{}
+	}
+	if settings.LeadMinutes == nil {
+		settings.LeadMinutes = []int32{}
+	}
+	if settings.EscalationChannels == nil {
+		settings.EscalationChannels = []string{}
+	}
+	return settings, nil
+}
+
+func scanDelivery(row rowScanner) (*Delivery, error) {
+	delivery := &Delivery{}
+	var notification []byte
+	if err := row.Scan(&delivery.ID, &delivery.UserID, &delivery.ScheduleUID, &delivery.StartTs, &delivery.LeadMinutes,
+		&delivery.Channel, &delivery.Status, &delivery.Attempts, &delivery.Error, &notification, &delivery.EscalationID,
+		&delivery.CreatedTs, &delivery.UpdatedTs, &delivery.SentTs); err != nil {
+		return nil, err
+	}
+	delivery.Notification = &Notification{}
+	if err := json.Unmarshal(notification, delivery.Notification); err != nil {
+		return nil, fmt.Errorf("invalid notification: %w", err)
+	}
+	delivery.Notification.EscalationID = delivery.EscalationID
+	return delivery, nil
+}
+
+func scanEscalation(row rowScanner) (*Escalation, error) {
+	escalation := &Escalation{}
+	var notification []byte
+	if err := row.Scan(&escalation.ID, &escalation.UserID, &escalation.ScheduleUID, &escalation.StartTs, &escalation.LeadMinutes,
+		pq.Array(&escalation.Channels), &escalation.DelayMinutes, &escalation.Step, &escalation.Status, &escalation.NextTs,
+		&escalation.AcknowledgedTs, &notification, &escalation.CreatedTs, &escalation.UpdatedTs); err != nil {
+		return nil, err
+	}
+	escalation.Notification = &Notification{}
+	if err := json.Unmarshal(notification, escalation.Notification); err != nil {
+		return nil, fmt.Errorf("invalid notification: %w", err)
+	}
+	escalation.Notification.EscalationID = escalation.ID
+	return escalation, nil
+}
@@ -0,0 +1,207 @@
+package reminder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	agents "github.com/hrygo/divinesense/ai/agents"
+)
+
+// Names of the agent tools of the reminders.
+const (
+	EscalationsToolName = "reminder_escalations"
+	CriticalToolName    = "reminder_critical"
+)
+
+const (
+	// toolEscalations bounds the escalations reported to the agent.
+	toolEscalations = 10
+	// toolEscalationWindow is how far back settled escalations are reported.
+	toolEscalationWindow = 24 * time.Hour
+)
+
+// EscalationsInput is the input of the reminder_escalations tool.
+type EscalationsInput struct {
+	// Acknowledge is the ID of an escalation the user acknowledged.
+	Acknowledge int64  `json:"acknowledge"`
+	Timezone    string `json:"timezone"`
+}
+
+// CriticalInput is the input of the reminder_critical tool.
+type CriticalInput struct {
+	ScheduleUID string `json:"schedule_uid"`
+	// Critical is false to make the schedule a regular one again.
+	Critical *bool `json:"critical"`
+}
+
+// EscalationsToolFor returns the reminder_escalations tool of a user, or nil
+// without reminders.
+func (r *Reminders) EscalationsToolFor(userID int32) agents.ToolWithSchema {
+	if r == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		EscalationsToolName,
+		"List the reminders of critical schedules escalating to the user: the channels already tried, "+
+			"when the next channel is tried, and the ones acknowledged or exhausted during the last day, "+
+			"with the critical schedules and the escalation chain. "+
+			`When the user acknowledges a reminder ("got it", "知道了"), pass its ID as acknowledge to stop it.`,
+		func(ctx context.Context, input string) (string, error) {
+			var in EscalationsInput
+			if strings.TrimSpace(input) != "" {
+				if err := json.Unmarshal([]byte(input), &in); err != nil {
+					return "", fmt.Errorf("invalid input: %w", err)
+				}
+			}
+			loc := time.Local
+			if in.Timezone != "" {
+				zone, err := time.LoadLocation(in.Timezone)
+				if err != nil {
+					return "", fmt.Errorf("invalid timezone %q", in.Timezone)
+				}
+				loc = zone
+			}
+			if in.Acknowledge != 0 {
+				escalation, err := r.Acknowledge(ctx, userID, in.Acknowledge)
+				if errors.Is(err, ErrEscalationNotFound) {
+					return fmt.Sprintf("No escalation %d; list the escalations to get their IDs.", in.Acknowledge), nil
+				}
+				if err != nil {
+					return "", err
+				}
+				return "✓ Acknowledged: " + formatEscalation(escalation, loc), nil
+			}
+			return r.formatEscalations(ctx, userID, loc)
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"acknowledge": map[string]any{"type": "integer", "description": "ID of an escalation the user acknowledged, to stop it"},
+				"timezone":    map[string]any{"type": "string", "description": "IANA time zone of the user, e.g. Asia/Shanghai"},
+			},
+		},
+	)
+}
+
+// CriticalToolFor returns the reminder_critical tool of a user, or nil
+// without reminders.
+func (r *Reminders) CriticalToolFor(userID int32) agents.ToolWithSchema {
+	if r == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		CriticalToolName,
+		"Mark a schedule critical, given its UID from schedule_query: its reminders then escalate through the user's "+
+			"chain of channels (e.g. web push, then Telegram, then email) until the user acknowledges them, "+
+			"even during quiet hours. Pass critical false to make it a regular schedule again.",
+		func(ctx context.Context, input string) (string, error) {
+			var in CriticalInput
+			if err := json.Unmarshal([]byte(input), &in); err != nil {
+				return "", fmt.Errorf("invalid input: %w", err)
+			}
+			critical := in.Critical == nil || *in.Critical
+			title, err := r.SetCritical(ctx, userID, in.ScheduleUID, critical)
+			if errors.Is(err, ErrScheduleNotFound) {
+				return fmt.Sprintf("No schedule %q; use schedule_query to find its UID.", in.ScheduleUID), nil
+			}
+			if err != nil {
+				return "", err
+			}
+			if !critical {
+				return fmt.Sprintf("✓ %q is a regular schedule again.", title), nil
+			}
+			settings, err := r.GetSettings(ctx, userID)
+			if err != nil {
+				return "", err
+			}
+			switch {
+			case !settings.Enabled:
+				return fmt.Sprintf("✓ %q is critical, but the user turned reminders off in the settings.", title), nil
+			case len(settings.EscalationChannels) == 0:
+				return fmt.Sprintf("✓ %q is critical; without an escalation chain in the settings, its reminders use the regular channels.", title), nil
+			}
+			return fmt.Sprintf("✓ %q is critical: its reminders escalate through %s, every %d minutes until acknowledged.",
+				title, strings.Join(settings.EscalationChannels, " → "), settings.EscalationMinutes), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"schedule_uid": map[string]any{"type": "string", "description": "UID of the schedule, from schedule_query"},
+				"critical":     map[string]any{"type": "boolean", "description": "false to unmark the schedule; defaults to true"},
+			},
+			"required": []string{"schedule_uid"},
+		},
+	)
+}
+
+// formatEscalations reports the active and recent escalations of a user.
+func (r *Reminders) formatEscalations(ctx context.Context, userID int32, loc *time.Location) (string, error) {
+	escalations, err := r.store.ListEscalations(ctx, &FindEscalation{UserID: &userID, Limit: toolEscalations * 2})
+	if err != nil {
+		return "", err
+	}
+	settings, err := r.GetSettings(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	critical, err := r.store.ListCritical(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	since := r.now().Add(-toolEscalationWindow).Unix()
+	listed := 0
+	for _, escalation := range escalations {
+		if listed == toolEscalations || (escalation.Status != EscalationActive && escalation.UpdatedTs < since) {
+			continue
+		}
+		if listed == 0 {
+			b.WriteString("Escalations:\n")
+		}
+		fmt.Fprintf(&b, "- %s\n", formatEscalation(escalation, loc))
+		listed++
+	}
+	if listed == 0 {
+		b.WriteString("No reminder escalated during the last day.\n")
+	}
+	if len(settings.EscalationChannels) > 0 {
+		fmt.Fprintf(&b, "Escalation chain: %s, every %d minutes until acknowledged.\n",
+			strings.Join(settings.EscalationChannels, " → "), settings.EscalationMinutes)
+	} else {
+		b.WriteString("No escalation chain: critical reminders use the regular channels.\n")
+	}
+	fmt.Fprintf(&b, "Critical schedules: %d", len(critical))
+	if len(critical) > 0 {
+		fmt.Fprintf(&b, " (UIDs: %s)", strings.Join(critical, ", "))
+	}
+	return b.String(), nil
+}
+
+// formatEscalation describes an escalation in a line.
+func formatEscalation(escalation *Escalation, loc *time.Location) string {
+	n := escalation.Notification
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%d] %q at %s, %s", escalation.ID, n.Title,
+		time.Unix(escalation.StartTs, 0).In(loc).Format("Mon Jan 2 15:04"), escalation.Status)
+	tried := escalation.Channels[:min(int(escalation.Step), len(escalation.Channels))]
+	if len(tried) > 0 {
+		fmt.Fprintf(&b, "; notified via %s", strings.Join(tried, ", "))
+	}
+	switch escalation.Status {
+	case EscalationActive:
+		if int(escalation.Step) < len(escalation.Channels) {
+			fmt.Fprintf(&b, "; escalates to %s at %s", escalation.Channels[escalation.Step],
+				time.Unix(escalation.NextTs, 0).In(loc).Format("15:04"))
+		} else {
+			fmt.Fprintf(&b, "; last channel, exhausted at %s", time.Unix(escalation.NextTs, 0).In(loc).Format("15:04"))
+		}
+	case EscalationAcknowledged:
+		fmt.Fprintf(&b, " at %s", time.Unix(escalation.AcknowledgedTs, 0).In(loc).Format("15:04"))
+	}
+	return b.String()
+}
@@ -0,0 +1,303 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hrygo/divinesense/plugin/netguard"
+)
+
+const (
+	// pushTTL is how long push services keep notifications of offline
+	// browsers.
+	pushTTL = 6 * time.Hour
+	// vapidExpiry is the lifetime of the VAPID tokens.
+	vapidExpiry = 12 * time.Hour
+	// pushRecordSize is the record size of the encrypted notifications.
+	pushRecordSize = 4096
+	// maxSubscriptions bounds the push subscriptions of a user.
+	maxSubscriptions = 20
+	// maxUserAgentRunes bounds the user agent kept on a subscription.
+	maxUserAgentRunes = 256
+)
+
+// WebPushConfig holds the VAPID keys identifying the instance to push
+// services.
+type WebPushConfig struct {
+	// PublicKey is the uncompressed P-256 public key, base64url encoded, which
+	// browsers subscribe with.
+	PublicKey string
+	// PrivateKey is the P-256 private key, base64url encoded.
+	PrivateKey string
+	// Subject is a contact of the instance for push services, a mailto: or
+	// https: URL.
+	Subject string
+}
+
+// WebPush is the web push channel, notifying the browsers users subscribed.
+type WebPush struct {
+	config WebPushConfig
+	key    *ecdsa.PrivateKey
+	store  Store
+	http   *http.Client
+}
+
+// NewWebPush creates the web push channel.
+func NewWebPush(config WebPushConfig, store Store) (*WebPush, error) {
+	private, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(config.PrivateKey, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	key, err := ecdsa.ParseRawPrivateKey(elliptic.P256(), private)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	public, err := key.PublicKey.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	if config.PublicKey != "" && strings.TrimRight(config.PublicKey, "=") != base64.RawURLEncoding.EncodeToString(public) {
+		return nil, errors.New("the VAPID public key does not match the private key")
+	}
+	config.PublicKey = base64.RawURLEncoding.EncodeToString(public)
+	if !strings.HasPrefix(config.Subject, "mailto:") && !strings.HasPrefix(config.Subject, "https://") {
+		return nil, errors.New("the VAPID subject must be a mailto: or https: URL")
+	}
+	return &WebPush{
+		config: config,
+		key:    key,
+		store:  store,
+		http:   &http.Client{Transport: publicTransport(), Timeout: sendTimeout},
+	}, nil
+}
+
+// PublicKey returns the VAPID public key browsers subscribe with.
+func (w *WebPush) PublicKey() string {
+	return w.config.PublicKey
+}
+
+// Send implements Channel, notifying every browser of the user. The
+// subscriptions push services no longer know are deleted.
+func (w *WebPush) Send(ctx context.Context, userID int32, notification *Notification) error {
+	subscriptions, err := w.store.ListSubscriptions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(subscriptions) == 0 {
+		return fmt.Errorf("%w: no browser subscribed to notifications", ErrUnavailable)
+	}
+	payload, err := json.Marshal(map[string]any{
+		"title": notification.Subject(),
+		"body":  notification.Body(),
+		"tag":   fmt.Sprintf("schedule-%s-%d", notification.UID, notification.StartTs),
+		"data":  notification,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	sent := false
+	var errs []error
+	for _, subscription := range subscriptions {
+		err := w.push(ctx, subscription, payload)
+		switch {
+		case err == nil:
+			sent = true
+		case errors.Is(err, ErrNotFound):
+			if err := w.store.DeleteSubscription(ctx, userID, subscription.Endpoint); err != nil && !errors.Is(err, ErrNotFound) {
+				errs = append(errs, err)
+			}
+		default:
+			errs = append(errs, err)
+		}
+	}
+	if sent {
+		return nil
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("%w: the subscribed browsers expired", ErrUnavailable)
+	}
+	return errors.Join(errs...)
+}
+
+// push sends a payload to a browser; it returns ErrNotFound when the push
+// service no longer knows the subscription.
+func (w *WebPush) push(ctx context.Context, subscription *PushSubscription, payload []byte) error {
+	body, err := encrypt(subscription, payload)
+	if err != nil {
+		return err
+	}
+	token, err := w.vapidToken(subscription.Endpoint)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "vapid t="+token+", k="+w.config.PublicKey)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", fmt.Sprint(int(pushTTL/time.Second)))
+	req.Header.Set("Urgency", "high")
+	resp, err := w.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the push service: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrNotFound
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("the push service answered HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// vapidToken returns the VAPID token (RFC 8292) of the push service of an
+// endpoint.
+func (w *WebPush) vapidToken(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(vapidExpiry).Unix(),
+		"sub": w.config.Subject,
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, w.key, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign VAPID token: %w", err)
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// encrypt encrypts a payload for a browser (RFC 8291), in a single aes128gcm
+// record (RFC 8188).
+func encrypt(subscription *PushSubscription, payload []byte) ([]byte, error) {
+	browserKey, authSecret, err := subscription.keys()
+	if err != nil {
+		return nil, err
+	}
+	browserPublic, err := ecdh.P256().NewPublicKey(browserKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid p256dh key", ErrInvalid)
+	}
+	local, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := local.ECDH(browserPublic)
+	if err != nil {
+		return nil, err
+	}
+	localPublic := local.PublicKey().Bytes()
+
+	keyInfo := "WebPush: info\x00" + string(browserKey) + string(localPublic)
+	ikm, err := hkdf.Key(sha256.New, secret, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	contentKey, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(contentKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	// The padding delimiter 2 marks the last record.
+	plaintext := append(append([]byte{}, payload...), 2)
+	if len(plaintext)+gcm.Overhead() > pushRecordSize {
+		return nil, errors.New("the notification is too large")
+	}
+
+	header := make([]byte, 0, 16+4+1+len(localPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, pushRecordSize)
+	header = append(header, byte(len(localPublic)))
+	header = append(header, localPublic...)
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// keys decodes the p256dh key and auth secret of a subscription.
+func (s *PushSubscription) keys() ([]byte, []byte, error) {
+	key, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s.Keys.P256dh, "="))
+	if err != nil || len(key) != 65 {
+		return nil, nil, fmt.Errorf("%w: invalid p256dh key", ErrInvalid)
+	}
+	auth, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s.Keys.Auth, "="))
+	if err != nil || len(auth) != 16 {
+		return nil, nil, fmt.Errorf("%w: invalid auth secret", ErrInvalid)
+	}
+	return key, auth, nil
+}
+
+// validate checks a subscription about to be saved.
+func (s *PushSubscription) validate() error {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return fmt.Errorf("%w: the endpoint must be an https URL", ErrInvalid)
+	}
+	key, _, err := s.keys()
+	if err != nil {
+		return err
+	}
+	if _, err := ecdh.P256().NewPublicKey(key); err != nil {
+		return fmt.Errorf("%w: invalid p256dh key", ErrInvalid)
+	}
+	if runes := []rune(s.UserAgent); len(runes) > maxUserAgentRunes {
+		s.UserAgent = string(runes[:maxUserAgentRunes])
+	}
+	return nil
+}
+
+// publicTransport dials public addresses only, so that users cannot reach
+// the private network of the server through their push endpoints.
+func publicTransport() *http.Transport {
+	return netguard.Transport(sendTimeout, netguard.ErrDenied)
+}
@@ -0,0 +1,100 @@
+// Package retrievalscope holds the retrieval scope of AI conversations.
+//
+// A conversation may restrict the memos its answers draw on to a few tags
+// (a tag includes its subtags), or turn memo retrieval off, e.g. to chat
+// about a single project. The scope is a setting of the conversation stored
+// in its metadata; chat requests carry it in their context, where memo
+// searches and the context builder honor it.
+package retrievalscope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hrygo/divinesense/ai/core/retrieval"
+)
+
+// ErrInvalid is returned for invalid scopes.
+var ErrInvalid = errors.New("invalid retrieval scope")
+
+const (
+	// MaxTags bounds the tags of a scope.
+	MaxTags = 20
+	// maxTagRunes bounds the length of a tag.
+	maxTagRunes = 64
+)
+
+// Store persists the scopes in the metadata of the conversations.
+type Store interface {
+	// GetScope returns the scope of a conversation, or nil.
+	GetScope(ctx context.Context, conversationID int32) (*retrieval.Scope, error)
+	// SaveScope sets the scope of a conversation; nil removes it.
+	SaveScope(ctx context.Context, conversationID int32, scope *retrieval.Scope) error
+}
+
+// Scopes reads and sets the retrieval scopes of conversations.
+type Scopes struct {
+	store Store
+}
+
+// New creates the retrieval scopes.
+func New(store Store) *Scopes {
+	return &Scopes{store: store}
+}
+
+// Get returns the scope of a conversation; nil leaves retrieval unrestricted.
+func (s *Scopes) Get(ctx context.Context, conversationID int32) (*retrieval.Scope, error) {
+	if conversationID <= 0 {
+		return nil, nil
+	}
+	return s.store.GetScope(ctx, conversationID)
+}
+
+// Set normalizes and saves the scope of a conversation, and returns it. An
+// unrestricted scope removes the setting.
+func (s *Scopes) Set(ctx context.Context, conversationID int32, scope *retrieval.Scope) (*retrieval.Scope, error) {
+	scope, err := Normalize(scope)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.SaveScope(ctx, conversationID, scope); err != nil {
+		return nil, err
+	}
+	return scope, nil
+}
+
+// Normalize trims the tags of a scope, without their leading '#', and drops
+// the duplicates. It returns nil for unrestricted scopes. Disabled scopes
+// keep no tags.
+func Normalize(scope *retrieval.Scope) (*retrieval.Scope, error) {
+	if !scope.Restricted() {
+		return nil, nil
+	}
+	if scope.Disabled {
+		return &retrieval.Scope{Disabled: true}, nil
+	}
+	normalized := &retrieval.Scope{Tags: []string{}}
+	for _, tag := range scope.Tags {
+		tag = strings.Trim(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tag), "#")), "/")
+		if tag == "" {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagRunes || strings.ContainsAny(tag, " \t\n#") {
+			return nil, fmt.Errorf("%w: invalid tag %q", ErrInvalid, tag)
+		}
+		if !slices.ContainsFunc(normalized.Tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			normalized.Tags = append(normalized.Tags, tag)
+		}
+	}
+	if len(normalized.Tags) == 0 {
+		return nil, fmt.Errorf("%w: tags are empty", ErrInvalid)
+	}
+	if len(normalized.Tags) > MaxTags {
+		return nil, fmt.Errorf("%w: at most %d tags", ErrInvalid, MaxTags)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,83 @@
+package retrievalscope
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/ai/core/retrieval"
+)
+
+// fakeStore keeps the scopes in memory.
+type fakeStore struct {
+	scopes map[int32]*retrieval.Scope
+}
+
+func (s *fakeStore) GetScope(_ context.Context, conversationID int32) (*retrieval.Scope, error) {
+	return s.scopes[conversationID], nil
+}
+
+func (s *fakeStore) SaveScope(_ context.Context, conversationID int32, scope *retrieval.Scope) error {
+	if scope == nil {
+		delete(s.scopes, conversationID)
+		return nil
+	}
+	s.scopes[conversationID] = scope
+	return nil
+}
+
+func TestNormalize(t *testing.T) {
+	scope, err := Normalize(&retrieval.Scope{Tags: []string{" #work ", "Work", "读书/", "", "work/meeting"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"work", "读书", "work/meeting"}, scope.Tags)
+
+	scope, err = Normalize(&retrieval.Scope{Disabled: true, Tags: []string{"work"}})
+	require.NoError(t, err)
+	assert.Equal(t, &retrieval.Scope{Disabled: true}, scope)
+
+	scope, err = Normalize(&retrieval.Scope{})
+	require.NoError(t, err)
+	assert.Nil(t, scope)
+
+	for _, tags := range [][]string{{"#"}, {"two words"}, {"a#b"}} {
+		_, err = Normalize(&retrieval.Scope{Tags: tags})
+		assert.ErrorIs(t, err, ErrInvalid, "tags %q", tags)
+	}
+	tooMany := make([]string, MaxTags+1)
+	for i := range tooMany {
+		tooMany[i] = string(rune('a' + i))
+	}
+	_, err = Normalize(&retrieval.Scope{Tags: tooMany})
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestScopes(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{scopes: map[int32]*retrieval.Scope{}}
+	scopes := New(store)
+
+	scope, err := scopes.Set(ctx, 1, &retrieval.Scope{Tags: []string{"#work"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"work"}, scope.Tags)
+
+	got, err := scopes.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, scope, got)
+
+	// Conversations without an ID have no scope
+	got, err = scopes.Get(ctx, 0)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	// An unrestricted scope removes the setting
+	scope, err = scopes.Set(ctx, 1, &retrieval.Scope{})
+	require.NoError(t, err)
+	assert.Nil(t, scope)
+	assert.Empty(t, store.scopes)
+
+	_, err = scopes.Set(ctx, 1, &retrieval.Scope{Tags: []string{"a b"}})
+	assert.ErrorIs(t, err, ErrInvalid)
+	assert.Empty(t, store.scopes)
+}
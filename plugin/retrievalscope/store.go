@@ -0,0 +1,61 @@
+package retrievalscope
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hrygo/divinesense/ai/core/retrieval"
+)
+
+// DBStore persists the scopes under the retrieval_scope key of the metadata
+// column of the ai_conversation table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new scope store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// GetScope implements Store.
+func (s *DBStore) GetScope(ctx context.Context, conversationID int32) (*retrieval.Scope, error) {
+	var raw []byte
+	err := s.db.QueryRowContext(ctx, "SELECT metadata -> 'retrieval_scope' FROM ai_conversation WHERE id = $1", conversationID).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && raw == nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get retrieval scope: %w", err)
+	}
+	var scope retrieval.Scope
+	if err := json.Unmarshal(raw, &scope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal retrieval scope: %w", err)
+	}
+	if !scope.Restricted() {
+		return nil, nil
+	}
+	return &scope, nil
+}
+
+// SaveScope implements Store.
+func (s *DBStore) SaveScope(ctx context.Context, conversationID int32, scope *retrieval.Scope) error {
+	var err error
+	if scope == nil {
+		_, err = s.db.ExecContext(ctx, "UPDATE ai_conversation SET metadata = metadata - 'retrieval_scope' WHERE id = $1", conversationID)
+	} else {
+		var raw []byte
+		if raw, err = json.Marshal(scope); err != nil {
+			return fmt.Errorf("failed to marshal retrieval scope: %w", err)
+		}
+		_, err = s.db.ExecContext(ctx, "UPDATE ai_conversation SET metadata = jsonb_set(metadata, '{retrieval_scope}', $2::jsonb) WHERE id = $1",
+			conversationID, string(raw))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save retrieval scope: %w", err)
+	}
+	return nil
+}
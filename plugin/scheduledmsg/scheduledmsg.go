@@ -0,0 +1,169 @@
+// Package scheduledmsg sends chat messages into a conversation at a time
+// chosen by the user ("send this prompt at 9am").
+//
+// Messages are queued in the scheduled_message table. A Dispatcher polls for
+// due messages, sends each one through the chat handler like a message typed
+// by the user, and notifies the user when the answer is ready.
+package scheduledmsg
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Status is the lifecycle state of a scheduled message.
+type Status string
+
+const (
+	StatusPending   Status = "PENDING"
+	StatusRunning   Status = "RUNNING"
+	StatusCompleted Status = "COMPLETED"
+	StatusFailed    Status = "FAILED"
+	StatusCanceled  Status = "CANCELED"
+)
+
+const (
+	// MaxMessageLength bounds the length of a scheduled message.
+	MaxMessageLength = 8000
+	// MaxScheduleAhead bounds how far in the future a message can be scheduled.
+	MaxScheduleAhead = 365 * 24 * time.Hour
+	// MaxPendingPerUser bounds the pending messages of a user.
+	MaxPendingPerUser = 50
+
+	// dispatchInterval is how often due messages are polled.
+	dispatchInterval = 30 * time.Second
+	// dispatchBatchSize bounds the messages claimed per poll.
+	dispatchBatchSize = 10
+	// executeTimeout bounds the chat run of a message.
+	executeTimeout = 10 * time.Minute
+	// staleAfter is how long a RUNNING message may go without an update before
+	// it is dispatched again, e.g. after the instance running it crashed.
+	staleAfter = executeTimeout + 5*time.Minute
+)
+
+// Message is a chat message scheduled to be sent into a conversation.
+type Message struct {
+	ID             int32  `json:"id"`
+	CreatorID      int32  `json:"creator_id"`
+	ConversationID int32  `json:"conversation_id"`
+	Message        string `json:"message"`
+	// AgentType is the AgentType enum name of the chat request, e.g.
+	// AGENT_TYPE_MEMO. Empty means default routing.
+	AgentType   string `json:"agent_type"`
+	Timezone    string `json:"timezone"`
+	ScheduledTs int64  `json:"scheduled_ts"`
+	// Urgent messages are sent during the quiet hours of their creator, which
+	// otherwise delay them until the quiet hours end.
+	Urgent bool   `json:"urgent"`
+	Status Status `json:"status"`
+	// BlockID is the block holding the answer once the message was sent.
+	BlockID   int64  `json:"block_id"`
+	Error     string `json:"error,omitempty"`
+	CreatedTs int64  `json:"created_ts"`
+	UpdatedTs int64  `json:"updated_ts"`
+}
+
+// Validate checks a message about to be scheduled at now.
+func (m *Message) Validate(now time.Time) error {
+	if strings.TrimSpace(m.Message) == "" {
+		return fmt.Errorf("message is required")
+	}
+	if len(m.Message) > MaxMessageLength {
+		return fmt.Errorf("message exceeds %d characters", MaxMessageLength)
+	}
+	if m.ConversationID == 0 {
+		return fmt.Errorf("conversation_id is required")
+	}
+	scheduled := time.Unix(m.ScheduledTs, 0)
+	if !scheduled.After(now) {
+		return fmt.Errorf("scheduled time must be in the future")
+	}
+	if scheduled.After(now.Add(MaxScheduleAhead)) {
+		return fmt.Errorf("scheduled time must be within %d days", int(MaxScheduleAhead.Hours()/24))
+	}
+	return nil
+}
+
+// Queue is the dispatcher's view of the message store; implemented by MessageStore.
+type Queue interface {
+	// ClaimDue marks up to limit due messages RUNNING and returns them.
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*Message, error)
+	// Finish records the outcome of a dispatched message.
+	Finish(ctx context.Context, id int32, status Status, blockID int64, errMsg string) error
+}
+
+// Executor sends a message through the chat handler and returns the block
+// holding the answer.
+type Executor func(ctx context.Context, msg *Message) (blockID int64, err error)
+
+// Notifier tells the user that a dispatched message has completed or failed.
+type Notifier func(ctx context.Context, msg *Message)
+
+// Dispatcher sends due messages. Several instances may run dispatchers on
+// the same database: a message is claimed by exactly one of them.
+type Dispatcher struct {
+	queue    Queue
+	execute  Executor
+	notify   Notifier
+	interval time.Duration
+}
+
+// NewDispatcher creates a dispatcher. notify may be nil.
+func NewDispatcher(queue Queue, execute Executor, notify Notifier) *Dispatcher {
+	return &Dispatcher{queue: queue, execute: execute, notify: notify, interval: dispatchInterval}
+}
+
+// Run dispatches due messages until ctx is canceled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		d.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce dispatches the messages due now and returns how many it dispatched.
+func (d *Dispatcher) RunOnce(ctx context.Context) int {
+	messages, err := d.queue.ClaimDue(ctx, time.Now(), dispatchBatchSize)
+	if err != nil {
+		slog.Warn("scheduled message: failed to claim due messages", "error", err)
+		return 0
+	}
+	for _, msg := range messages {
+		d.dispatch(ctx, msg)
+	}
+	return len(messages)
+}
+
+// dispatch sends one message and records its outcome.
+func (d *Dispatcher) dispatch(ctx context.Context, msg *Message) {
+	runCtx, cancel := context.WithTimeout(ctx, executeTimeout)
+	blockID, err := d.execute(runCtx, msg)
+	cancel()
+
+	msg.Status, msg.BlockID, msg.Error = StatusCompleted, blockID, ""
+	if err != nil {
+		msg.Status, msg.Error = StatusFailed, err.Error()
+	}
+	slog.Info("scheduled message dispatched",
+		"id", msg.ID,
+		"conversation_id", msg.ConversationID,
+		"status", msg.Status,
+		"block_id", blockID,
+	)
+	// Record the outcome even if the dispatcher is shutting down.
+	if err := d.queue.Finish(context.WithoutCancel(ctx), msg.ID, msg.Status, msg.BlockID, msg.Error); err != nil {
+		slog.Warn("scheduled message: failed to record outcome", "id", msg.ID, "error", err)
+	}
+	if d.notify != nil {
+		d.notify(ctx, msg)
+	}
+}
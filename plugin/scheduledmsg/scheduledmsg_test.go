@@ -0,0 +1,80 @@
+package scheduledmsg
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueue struct {
+	due      []*Message
+	finished map[int32]*Message
+}
+
+func (f *fakeQueue) ClaimDue(_ context.Context, _ time.Time, limit int) ([]*Message, error) {
+	n := min(limit, len(f.due))
+	claimed := f.due[:n]
+	f.due = f.due[n:]
+	return claimed, nil
+}
+
+func (f *fakeQueue) Finish(_ context.Context, id int32, status Status, blockID int64, errMsg string) error {
+	f.finished[id] = &Message{ID: id, Status: status, BlockID: blockID, Error: errMsg}
+	return nil
+}
+
+func TestDispatcherRunOnce(t *testing.T) {
+	queue := &fakeQueue{
+		due: []*Message{
+			{ID: 1, ConversationID: 10, Message: "summarize my day"},
+			{ID: 2, ConversationID: 10, Message: "fail"},
+		},
+		finished: map[int32]*Message{},
+	}
+	execute := func(_ context.Context, msg *Message) (int64, error) {
+		if msg.Message == "fail" {
+			return 0, errors.New("model unavailable")
+		}
+		return 100 + int64(msg.ID), nil
+	}
+	var notified []Status
+	notify := func(_ context.Context, msg *Message) {
+		notified = append(notified, msg.Status)
+	}
+
+	d := NewDispatcher(queue, execute, notify)
+	require.Equal(t, 2, d.RunOnce(context.Background()))
+
+	require.Equal(t, &Message{ID: 1, Status: StatusCompleted, BlockID: 101}, queue.finished[1])
+	require.Equal(t, &Message{ID: 2, Status: StatusFailed, Error: "model unavailable"}, queue.finished[2])
+	require.Equal(t, []Status{StatusCompleted, StatusFailed}, notified)
+
+	require.Equal(t, 0, d.RunOnce(context.Background()))
+}
+
+func TestMessageValidate(t *testing.T) {
+	now := time.Now()
+	valid := func() *Message {
+		return &Message{ConversationID: 1, Message: "hello", ScheduledTs: now.Add(time.Hour).Unix()}
+	}
+	require.NoError(t, valid().Validate(now))
+
+	past := valid()
+	past.ScheduledTs = now.Add(-time.Minute).Unix()
+	require.Error(t, past.Validate(now))
+
+	tooFar := valid()
+	tooFar.ScheduledTs = now.Add(MaxScheduleAhead + time.Hour).Unix()
+	require.Error(t, tooFar.Validate(now))
+
+	empty := valid()
+	empty.Message = "  "
+	require.Error(t, empty.Validate(now))
+
+	noConversation := valid()
+	noConversation.ConversationID = 0
+	require.Error(t, noConversation.Validate(now))
+}
@@ -0,0 +1,164 @@
+package scheduledmsg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hrygo/divinesense/plugin/quiethours"
+)
+
+// FindMessage specifies conditions for listing messages.
+type FindMessage struct {
+	ID             *int32
+	CreatorID      *int32
+	ConversationID *int32
+	Status         *Status
+}
+
+// MessageStore persists messages in the scheduled_message table (PostgreSQL).
+type MessageStore struct {
+	db *sql.DB
+}
+
+// NewMessageStore creates a new message store.
+func NewMessageStore(db *sql.DB) *MessageStore {
+	return &MessageStore{db: db}
+}
+
+const messageColumns = "id, creator_id, conversation_id, message, agent_type, timezone, scheduled_ts, urgent, status, block_id, error, created_ts, updated_ts"
+
+// CreateMessage inserts a new PENDING message.
+func (s *MessageStore) CreateMessage(ctx context.Context, msg *Message) (*Message, error) {
+	now := time.Now().Unix()
+	query := `
+		INSERT INTO scheduled_message (creator_id, conversation_id, message, agent_type, timezone, scheduled_ts, urgent, status, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING ` + messageColumns
+	row := s.db.QueryRowContext(ctx, query,
+		msg.CreatorID, msg.ConversationID, msg.Message, msg.AgentType, msg.Timezone, msg.ScheduledTs, msg.Urgent, StatusPending, now, now)
+	created, err := scanMessage(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scheduled message: %w", err)
+	}
+	return created, nil
+}
+
+// ListMessages lists messages matching find, ordered by scheduled time.
+func (s *MessageStore) ListMessages(ctx context.Context, find *FindMessage) ([]*Message, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if find.ID != nil {
+		args = append(args, *find.ID)
+		where = append(where, fmt.Sprintf("id = $%d", len(args)))
+	}
+	if find.CreatorID != nil {
+		args = append(args, *find.CreatorID)
+		where = append(where, fmt.Sprintf("creator_id = $%d", len(args)))
+	}
+	if find.ConversationID != nil {
+		args = append(args, *find.ConversationID)
+		where = append(where, fmt.Sprintf("conversation_id = $%d", len(args)))
+	}
+	if find.Status != nil {
+		args = append(args, *find.Status)
+		where = append(where, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	query := "SELECT " + messageColumns + " FROM scheduled_message WHERE " + strings.Join(where, " AND ") + " ORDER BY scheduled_ts, id"
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled messages: %w", err)
+	}
+	defer rows.Close()
+	return scanMessages(rows)
+}
+
+// CountPending returns the number of PENDING messages of a user.
+func (s *MessageStore) CountPending(ctx context.Context, creatorID int32) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM scheduled_message WHERE creator_id = $1 AND status = $2", creatorID, StatusPending).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count scheduled messages: %w", err)
+	}
+	return count, nil
+}
+
+// CancelMessage cancels a PENDING message of a user. It returns false when no
+// such message exists, e.g. because it was already dispatched.
+func (s *MessageStore) CancelMessage(ctx context.Context, id, creatorID int32) (bool, error) {
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE scheduled_message SET status = $1, updated_ts = $2 WHERE id = $3 AND creator_id = $4 AND status = $5",
+		StatusCanceled, time.Now().Unix(), id, creatorID, StatusPending)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel scheduled message: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel scheduled message: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ClaimDue marks up to limit due messages RUNNING and returns them. Due
+// messages are PENDING ones whose time has come, unless their creator is in
+// quiet hours and they are not urgent, and RUNNING ones left stale by a
+// crashed dispatcher. Rows locked by another dispatcher are skipped.
+func (s *MessageStore) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*Message, error) {
+	query := `
+		UPDATE scheduled_message SET status = $1, updated_ts = $2
+		WHERE id IN (
+			SELECT id FROM scheduled_message
+			WHERE (status = $3 AND scheduled_ts <= $2 AND (urgent OR ` + quiethours.NotQuietSQL("scheduled_message.creator_id", "$2") + `))
+				OR (status = $1 AND updated_ts < $4)
+			ORDER BY scheduled_ts
+			LIMIT $5
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + messageColumns
+	rows, err := s.db.QueryContext(ctx, query,
+		StatusRunning, now.Unix(), StatusPending, now.Add(-staleAfter).Unix(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due scheduled messages: %w", err)
+	}
+	defer rows.Close()
+	return scanMessages(rows)
+}
+
+// Finish records the outcome of a dispatched message.
+func (s *MessageStore) Finish(ctx context.Context, id int32, status Status, blockID int64, errMsg string) error {
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE scheduled_message SET status = $1, block_id = $2, error = $3, updated_ts = $4 WHERE id = $5",
+		status, blockID, errMsg, time.Now().Unix(), id)
+	if err != nil {
+		return fmt.Errorf("failed to finish scheduled message %d: %w", id, err)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanMessage(row rowScanner) (*Message, error) {
+	msg := &Message{}
+	if err := row.Scan(&msg.ID, &msg.CreatorID, &msg.ConversationID, &msg.Message, &msg.AgentType, &msg.Timezone,
+		&msg.ScheduledTs, &msg.Urgent, &msg.Status, &msg.BlockID, &msg.Error, &msg.CreatedTs, &msg.UpdatedTs); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func scanMessages(rows *sql.Rows) ([]*Message, error) {
+	messages := []*Message{}
+	for rows.Next() {
+		msg, err := scanMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled message: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, rows.Err()
+}
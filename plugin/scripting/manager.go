@@ -0,0 +1,175 @@
+package scripting
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// hookCacheTTL bounds how long enabled hooks are cached before reloading.
+const hookCacheTTL = 30 * time.Second
+
+// HookLister loads hooks; implemented by HookStore.
+type HookLister interface {
+	ListHooks(ctx context.Context, find *FindHook) ([]*Hook, error)
+}
+
+// Outcome aggregates the results of all hooks for one trigger invocation.
+type Outcome struct {
+	// Rejected is true when any hook rejected the operation.
+	Rejected bool
+	// Reason is the rejecting hook's reason.
+	Reason string
+	// Content is the (possibly rewritten) memo content or chat message.
+	Content string
+	// Tags are accumulated from all hooks, de-duplicated.
+	Tags []string
+	// Dropped is true when a chat.event hook asked to drop the event.
+	Dropped bool
+	// Data is the (possibly rewritten) chat event data.
+	Data string
+}
+
+// Manager runs enabled hooks for a trigger in priority order.
+// Script failures are logged and skipped: a broken hook never blocks the
+// operation it is attached to (fail-open), only an explicit reject does.
+type Manager struct {
+	lister HookLister
+	engine *Engine
+
+	mu       sync.RWMutex
+	cache    map[Trigger][]*Hook
+	loadedAt time.Time
+}
+
+// NewManager creates a hook manager.
+func NewManager(lister HookLister, engine *Engine) *Manager {
+	return &Manager{
+		lister: lister,
+		engine: engine,
+	}
+}
+
+// Engine returns the underlying script engine.
+func (m *Manager) Engine() *Engine {
+	return m.engine
+}
+
+// Invalidate drops the hook cache so the next call reloads from the store.
+// Called by the admin API after any mutation.
+func (m *Manager) Invalidate() {
+	m.mu.Lock()
+	m.cache = nil
+	m.mu.Unlock()
+}
+
+// Apply runs every enabled hook for trigger against input.
+// content/data seed the Outcome and are threaded through successive hooks so
+// that each hook observes the previous hook's rewrite.
+func (m *Manager) Apply(ctx context.Context, trigger Trigger, input map[string]any) *Outcome {
+	outcome := &Outcome{}
+	if v, ok := input["content"].(string); ok {
+		outcome.Content = v
+	}
+	if v, ok := input["data"].(string); ok {
+		outcome.Data = v
+	}
+	if m == nil {
+		return outcome
+	}
+
+	hooks := m.hooksFor(ctx, trigger)
+	if len(hooks) == 0 {
+		return outcome
+	}
+
+	seenTags := make(map[string]bool)
+	for _, hook := range hooks {
+		input["content"] = outcome.Content
+		input["data"] = outcome.Data
+
+		result, err := m.engine.Run(ctx, hook.Source, input)
+		if err != nil {
+			slog.Warn("script hook failed, skipping",
+				"hook_id", hook.ID,
+				"hook_name", hook.Name,
+				"trigger", trigger,
+				"error", err)
+			continue
+		}
+		if result == nil {
+			continue
+		}
+
+		if result.Content != nil {
+			outcome.Content = *result.Content
+		}
+		if result.Data != nil {
+			outcome.Data = *result.Data
+		}
+		for _, tag := range result.Tags {
+			if tag != "" && !seenTags[tag] {
+				seenTags[tag] = true
+				outcome.Tags = append(outcome.Tags, tag)
+			}
+		}
+		if result.Drop {
+			outcome.Dropped = true
+			return outcome
+		}
+		if result.Reject {
+			outcome.Rejected = true
+			outcome.Reason = result.Reason
+			if outcome.Reason == "" {
+				outcome.Reason = "rejected by hook " + hook.Name
+			}
+			return outcome
+		}
+	}
+	return outcome
+}
+
+// HasHooks reports whether any enabled hook is bound to trigger.
+// Callers on hot paths (e.g. per-event streaming) use it to skip input building.
+func (m *Manager) HasHooks(ctx context.Context, trigger Trigger) bool {
+	if m == nil {
+		return false
+	}
+	return len(m.hooksFor(ctx, trigger)) > 0
+}
+
+func (m *Manager) hooksFor(ctx context.Context, trigger Trigger) []*Hook {
+	m.mu.RLock()
+	if m.cache != nil && time.Since(m.loadedAt) < hookCacheTTL {
+		hooks := m.cache[trigger]
+		m.mu.RUnlock()
+		return hooks
+	}
+	m.mu.RUnlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cache != nil && time.Since(m.loadedAt) < hookCacheTTL {
+		return m.cache[trigger]
+	}
+
+	enabled := true
+	hooks, err := m.lister.ListHooks(ctx, &FindHook{Enabled: &enabled})
+	if err != nil {
+		slog.Warn("failed to load script hooks", "error", err)
+		// Keep serving the stale cache (if any) rather than flapping.
+		if m.cache != nil {
+			return m.cache[trigger]
+		}
+		return nil
+	}
+
+	cache := make(map[Trigger][]*Hook)
+	for _, hook := range hooks {
+		cache[hook.Trigger] = append(cache[hook.Trigger], hook)
+	}
+	m.cache = cache
+	m.loadedAt = time.Now()
+	return cache[trigger]
+}
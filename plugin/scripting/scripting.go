@@ -0,0 +1,223 @@
+// Package scripting provides an embedded JavaScript runtime for admin-defined hooks.
+//
+// Hooks let operators who don't write Go customise behaviour at well-defined
+// trigger points: transforming chat events, auto-tagging memos, or rejecting
+// requests. Scripts run inside goja with a wall-clock budget, a bounded call
+// stack and a capped source/output size so a faulty script cannot stall the
+// server.
+//
+// A script must define a top-level `handle(input)` function and return an
+// object (or nothing, meaning "no change"):
+//
+//	function handle(input) {
+//	  if (input.content.includes("#secret")) {
+//	    return { reject: true, reason: "secrets are not allowed" };
+//	  }
+//	  return { tags: ["inbox"] };
+//	}
+package scripting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/pkg/errors"
+)
+
+// Trigger identifies the point at which a hook is executed.
+type Trigger string
+
+const (
+	// TriggerMemoCreate runs before a memo is persisted. Scripts may add tags,
+	// rewrite content, or reject the memo.
+	TriggerMemoCreate Trigger = "memo.create"
+	// TriggerChatRequest runs before a chat message is dispatched to an agent.
+	// Scripts may rewrite the message or reject the request.
+	TriggerChatRequest Trigger = "chat.request"
+	// TriggerChatEvent runs for each streamed chat event. Scripts may rewrite
+	// the event data or drop the event.
+	TriggerChatEvent Trigger = "chat.event"
+)
+
+// IsValid reports whether t is a supported trigger.
+func (t Trigger) IsValid() bool {
+	switch t {
+	case TriggerMemoCreate, TriggerChatRequest, TriggerChatEvent:
+		return true
+	default:
+		return false
+	}
+}
+
+// entrypoint is the function name every script must define.
+const entrypoint = "handle"
+
+var (
+	// ErrScriptTimeout is returned when a script exceeds its time budget.
+	ErrScriptTimeout = errors.New("script exceeded time limit")
+	// ErrSourceTooLarge is returned when a script exceeds MaxSourceBytes.
+	ErrSourceTooLarge = errors.New("script source too large")
+	// ErrOutputTooLarge is returned when a script result exceeds MaxOutputBytes.
+	ErrOutputTooLarge = errors.New("script output too large")
+	// ErrMissingEntrypoint is returned when a script does not define handle().
+	ErrMissingEntrypoint = errors.New("script must define function handle(input)")
+)
+
+// Limits bounds the resources a single script invocation may consume.
+type Limits struct {
+	// Timeout is the wall-clock budget for one invocation.
+	Timeout time.Duration
+	// MaxCallStackSize caps recursion depth.
+	MaxCallStackSize int
+	// MaxSourceBytes caps the script source length.
+	MaxSourceBytes int
+	// MaxOutputBytes caps the JSON-encoded result size.
+	MaxOutputBytes int
+}
+
+// DefaultLimits returns conservative limits suitable for request-path hooks.
+func DefaultLimits() Limits {
+	return Limits{
+		Timeout:          200 * time.Millisecond,
+		MaxCallStackSize: 256,
+		MaxSourceBytes:   64 * 1024,
+		MaxOutputBytes:   256 * 1024,
+	}
+}
+
+// Result is the decoded return value of a script.
+type Result struct {
+	// Reject aborts the triggering operation when true.
+	Reject bool `json:"reject"`
+	// Reason is surfaced to the user when Reject is true.
+	Reason string `json:"reason,omitempty"`
+	// Content replaces the memo content or chat message when non-nil.
+	Content *string `json:"content,omitempty"`
+	// Tags are appended to a memo (TriggerMemoCreate only).
+	Tags []string `json:"tags,omitempty"`
+	// Drop suppresses a streamed event (TriggerChatEvent only).
+	Drop bool `json:"drop,omitempty"`
+	// Data replaces the event data (TriggerChatEvent only).
+	Data *string `json:"data,omitempty"`
+}
+
+// Engine compiles and executes hook scripts.
+// Each invocation uses a fresh runtime, so scripts cannot share state.
+type Engine struct {
+	limits Limits
+}
+
+// NewEngine creates an engine with the given limits.
+// Zero-valued fields fall back to DefaultLimits.
+func NewEngine(limits Limits) *Engine {
+	defaults := DefaultLimits()
+	if limits.Timeout <= 0 {
+		limits.Timeout = defaults.Timeout
+	}
+	if limits.MaxCallStackSize <= 0 {
+		limits.MaxCallStackSize = defaults.MaxCallStackSize
+	}
+	if limits.MaxSourceBytes <= 0 {
+		limits.MaxSourceBytes = defaults.MaxSourceBytes
+	}
+	if limits.MaxOutputBytes <= 0 {
+		limits.MaxOutputBytes = defaults.MaxOutputBytes
+	}
+	return &Engine{limits: limits}
+}
+
+// Limits returns the engine's effective limits.
+func (e *Engine) Limits() Limits {
+	return e.limits
+}
+
+// Validate compiles the source and checks that it defines the entrypoint.
+// It is used by the admin API before a hook is saved.
+func (e *Engine) Validate(source string) error {
+	if len(source) > e.limits.MaxSourceBytes {
+		return ErrSourceTooLarge
+	}
+	if _, err := goja.Compile("hook.js", source, true); err != nil {
+		return errors.Wrap(err, "failed to compile script")
+	}
+	_, err := e.Run(context.Background(), source, map[string]any{})
+	if errors.Is(err, ErrMissingEntrypoint) {
+		return err
+	}
+	return nil
+}
+
+// Run executes the script's handle(input) and decodes its result.
+// A nil result (script returned undefined/null) means "no change".
+func (e *Engine) Run(ctx context.Context, source string, input map[string]any) (*Result, error) {
+	if len(source) > e.limits.MaxSourceBytes {
+		return nil, ErrSourceTooLarge
+	}
+
+	program, err := goja.Compile("hook.js", source, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compile script")
+	}
+
+	vm := goja.New()
+	vm.SetMaxCallStackSize(e.limits.MaxCallStackSize)
+	vm.SetFieldNameMapper(goja.TagFieldNameMapper("json", true))
+
+	ctx, cancel := context.WithTimeout(ctx, e.limits.Timeout)
+	defer cancel()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			vm.Interrupt(ErrScriptTimeout)
+		case <-stop:
+		}
+	}()
+
+	if _, err := vm.RunProgram(program); err != nil {
+		return nil, wrapRuntimeError(err)
+	}
+
+	handle, ok := goja.AssertFunction(vm.Get(entrypoint))
+	if !ok {
+		return nil, ErrMissingEntrypoint
+	}
+
+	value, err := handle(goja.Undefined(), vm.ToValue(input))
+	if err != nil {
+		return nil, wrapRuntimeError(err)
+	}
+	if value == nil || goja.IsUndefined(value) || goja.IsNull(value) {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(value.Export())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode script result")
+	}
+	if len(raw) > e.limits.MaxOutputBytes {
+		return nil, ErrOutputTooLarge
+	}
+
+	result := &Result{}
+	if err := json.Unmarshal(raw, result); err != nil {
+		return nil, fmt.Errorf("script must return an object: %w", err)
+	}
+	return result, nil
+}
+
+// wrapRuntimeError normalises goja errors, mapping interrupts to ErrScriptTimeout.
+func wrapRuntimeError(err error) error {
+	var interrupted *goja.InterruptedError
+	if errors.As(err, &interrupted) {
+		if v, ok := interrupted.Value().(error); ok && errors.Is(v, ErrScriptTimeout) {
+			return ErrScriptTimeout
+		}
+		return errors.Errorf("script interrupted: %v", interrupted.Value())
+	}
+	return errors.Wrap(err, "script execution failed")
+}
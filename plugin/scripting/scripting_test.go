@@ -0,0 +1,97 @@
+package scripting
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngineRun(t *testing.T) {
+	engine := NewEngine(DefaultLimits())
+
+	t.Run("reject", func(t *testing.T) {
+		result, err := engine.Run(context.Background(), `
+			function handle(input) {
+				if (input.content.includes("#secret")) {
+					return { reject: true, reason: "no secrets" };
+				}
+			}`, map[string]any{"content": "hello #secret"})
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		require.True(t, result.Reject)
+		require.Equal(t, "no secrets", result.Reason)
+	})
+
+	t.Run("undefined result means no change", func(t *testing.T) {
+		result, err := engine.Run(context.Background(), `function handle(input) {}`, map[string]any{})
+		require.NoError(t, err)
+		require.Nil(t, result)
+	})
+
+	t.Run("missing entrypoint", func(t *testing.T) {
+		_, err := engine.Run(context.Background(), `var x = 1;`, map[string]any{})
+		require.ErrorIs(t, err, ErrMissingEntrypoint)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		e := NewEngine(Limits{Timeout: 20 * time.Millisecond})
+		_, err := e.Run(context.Background(), `function handle(input) { while (true) {} }`, map[string]any{})
+		require.ErrorIs(t, err, ErrScriptTimeout)
+	})
+
+	t.Run("stack overflow", func(t *testing.T) {
+		_, err := engine.Run(context.Background(), `
+			function f(n) { return f(n + 1); }
+			function handle(input) { return f(0); }`, map[string]any{})
+		require.Error(t, err)
+	})
+
+	t.Run("source too large", func(t *testing.T) {
+		e := NewEngine(Limits{MaxSourceBytes: 16})
+		_, err := e.Run(context.Background(), strings.Repeat("x", 32), map[string]any{})
+		require.ErrorIs(t, err, ErrSourceTooLarge)
+	})
+}
+
+type fakeLister struct {
+	hooks []*Hook
+	err   error
+}
+
+func (f *fakeLister) ListHooks(_ context.Context, _ *FindHook) ([]*Hook, error) {
+	return f.hooks, f.err
+}
+
+func TestManagerApply(t *testing.T) {
+	lister := &fakeLister{hooks: []*Hook{
+		{ID: 1, Name: "tagger", Trigger: TriggerMemoCreate, Source: `function handle(i) { return { tags: ["inbox", "inbox"] }; }`},
+		{ID: 2, Name: "broken", Trigger: TriggerMemoCreate, Source: `function handle(i) { throw new Error("boom"); }`},
+		{ID: 3, Name: "upper", Trigger: TriggerMemoCreate, Source: `function handle(i) { return { content: i.content.toUpperCase() }; }`},
+		{ID: 4, Name: "dropper", Trigger: TriggerChatEvent, Source: `function handle(i) { return { drop: i.event_type === "ping" }; }`},
+	}}
+	manager := NewManager(lister, NewEngine(DefaultLimits()))
+
+	outcome := manager.Apply(context.Background(), TriggerMemoCreate, map[string]any{"content": "hello"})
+	require.False(t, outcome.Rejected)
+	require.Equal(t, "HELLO", outcome.Content)
+	require.Equal(t, []string{"inbox"}, outcome.Tags)
+
+	outcome = manager.Apply(context.Background(), TriggerChatEvent, map[string]any{"event_type": "ping", "data": "."})
+	require.True(t, outcome.Dropped)
+
+	require.False(t, manager.HasHooks(context.Background(), TriggerChatRequest))
+}
+
+func TestManagerNilAndLoadError(t *testing.T) {
+	var nilManager *Manager
+	require.False(t, nilManager.HasHooks(context.Background(), TriggerMemoCreate))
+	outcome := nilManager.Apply(context.Background(), TriggerMemoCreate, map[string]any{"content": "x"})
+	require.Equal(t, "x", outcome.Content)
+
+	manager := NewManager(&fakeLister{err: errors.New("db down")}, NewEngine(DefaultLimits()))
+	require.False(t, manager.HasHooks(context.Background(), TriggerMemoCreate))
+}
@@ -0,0 +1,169 @@
+package scripting
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Hook is an admin-defined script bound to a trigger.
+type Hook struct {
+	ID          int32   `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Trigger     Trigger `json:"trigger"`
+	Source      string  `json:"source"`
+	// Priority orders hooks sharing a trigger (ascending).
+	Priority  int32 `json:"priority"`
+	Enabled   bool  `json:"enabled"`
+	CreatorID int32 `json:"creator_id"`
+	CreatedTs int64 `json:"created_ts"`
+	UpdatedTs int64 `json:"updated_ts"`
+}
+
+// FindHook specifies conditions for listing hooks.
+type FindHook struct {
+	ID      *int32
+	Trigger *Trigger
+	Enabled *bool
+}
+
+// UpdateHook specifies fields to update on a hook. Nil fields are left unchanged.
+type UpdateHook struct {
+	ID          int32
+	Name        *string
+	Description *string
+	Source      *string
+	Priority    *int32
+	Enabled     *bool
+}
+
+// HookStore persists hooks in the script_hook table (PostgreSQL).
+type HookStore struct {
+	db *sql.DB
+}
+
+// NewHookStore creates a new hook store.
+func NewHookStore(db *sql.DB) *HookStore {
+	return &HookStore{db: db}
+}
+
+const hookColumns = "id, name, description, trigger, source, priority, enabled, creator_id, created_ts, updated_ts"
+
+// CreateHook inserts a new hook.
+func (s *HookStore) CreateHook(ctx context.Context, hook *Hook) (*Hook, error) {
+	now := time.Now().Unix()
+	query := `
+		INSERT INTO script_hook (name, description, trigger, source, priority, enabled, creator_id, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING ` + hookColumns
+	row := s.db.QueryRowContext(ctx, query,
+		hook.Name, hook.Description, hook.Trigger, hook.Source, hook.Priority, hook.Enabled, hook.CreatorID, now, now)
+	created, err := scanHook(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create script hook: %w", err)
+	}
+	return created, nil
+}
+
+// ListHooks lists hooks matching find, ordered by trigger and priority.
+func (s *HookStore) ListHooks(ctx context.Context, find *FindHook) ([]*Hook, error) {
+	where, args := []string{"1 = 1"}, []any{}
+	if find.ID != nil {
+		args = append(args, *find.ID)
+		where = append(where, fmt.Sprintf("id = $%d", len(args)))
+	}
+	if find.Trigger != nil {
+		args = append(args, *find.Trigger)
+		where = append(where, fmt.Sprintf("trigger = $%d", len(args)))
+	}
+	if find.Enabled != nil {
+		args = append(args, *find.Enabled)
+		where = append(where, fmt.Sprintf("enabled = $%d", len(args)))
+	}
+
+	query := "SELECT " + hookColumns + " FROM script_hook WHERE " + strings.Join(where, " AND ") + " ORDER BY trigger, priority, id"
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list script hooks: %w", err)
+	}
+	defer rows.Close()
+
+	hooks := []*Hook{}
+	for rows.Next() {
+		hook, err := scanHook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan script hook: %w", err)
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, rows.Err()
+}
+
+// UpdateHook applies a partial update and returns the updated hook.
+func (s *HookStore) UpdateHook(ctx context.Context, update *UpdateHook) (*Hook, error) {
+	set, args := []string{}, []any{}
+	if update.Name != nil {
+		args = append(args, *update.Name)
+		set = append(set, fmt.Sprintf("name = $%d", len(args)))
+	}
+	if update.Description != nil {
+		args = append(args, *update.Description)
+		set = append(set, fmt.Sprintf("description = $%d", len(args)))
+	}
+	if update.Source != nil {
+		args = append(args, *update.Source)
+		set = append(set, fmt.Sprintf("source = $%d", len(args)))
+	}
+	if update.Priority != nil {
+		args = append(args, *update.Priority)
+		set = append(set, fmt.Sprintf("priority = $%d", len(args)))
+	}
+	if update.Enabled != nil {
+		args = append(args, *update.Enabled)
+		set = append(set, fmt.Sprintf("enabled = $%d", len(args)))
+	}
+	args = append(args, time.Now().Unix())
+	set = append(set, fmt.Sprintf("updated_ts = $%d", len(args)))
+	args = append(args, update.ID)
+
+	query := "UPDATE script_hook SET " + strings.Join(set, ", ") + fmt.Sprintf(" WHERE id = $%d RETURNING ", len(args)) + hookColumns
+	hook, err := scanHook(s.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update script hook: %w", err)
+	}
+	return hook, nil
+}
+
+// DeleteHook removes a hook by ID.
+func (s *HookStore) DeleteHook(ctx context.Context, id int32) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM script_hook WHERE id = $1", id); err != nil {
+		return fmt.Errorf("failed to delete script hook: %w", err)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanHook(row rowScanner) (*Hook, error) {
+	hook := &Hook{}
+	if err := row.Scan(
+		&hook.ID,
+		&hook.Name,
+		&hook.Description,
+		&hook.Trigger,
+		&hook.Source,
+		&hook.Priority,
+		&hook.Enabled,
+		&hook.CreatorID,
+		&hook.CreatedTs,
+		&hook.UpdatedTs,
+	); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
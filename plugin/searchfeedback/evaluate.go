@@ -0,0 +1,184 @@
+package searchfeedback
+
+import (
+	"math"
+	"sort"
+)
+
+// RRFK is the rank constant of the reciprocal rank fusion; it matches the
+// hybrid search of the retriever.
+const RRFK = 60
+
+// NDCGDepth is the rank cutoff of the NDCG.
+const NDCGDepth = 10
+
+// Weights is the grid of semantic weights searched by tunings.
+var Weights = []float32{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9}
+
+// WeightScore is the mean NDCG of the judged searches fused with a weight.
+type WeightScore struct {
+	Weight float32 `json:"weight"`
+	NDCG   float64 `json:"ndcg"`
+}
+
+// Report compares the ranking of the judged searches of a user before and
+// after tuning.
+type Report struct {
+	UserID int32 `json:"-"`
+	// Searches is the number of judged searches with a relevant result, the
+	// only ones NDCG is defined for.
+	Searches  int `json:"searches"`
+	Judgments int `json:"judgments"`
+	// Before is the mean NDCG of the searches fused with the weight each one
+	// used.
+	Before float64 `json:"ndcg_before"`
+	// After is the mean NDCG with the tuned Weight.
+	After  float64 `json:"ndcg_after"`
+	Weight float32 `json:"weight"`
+	// Curve is the mean NDCG of every weight of the grid.
+	Curve []WeightScore `json:"curve"`
+	// Current is the weight in use for the user; nil for the default
+	// weighting.
+	Current *float32 `json:"current_weight"`
+	// Applied reports whether a tuning put Weight in use.
+	Applied bool `json:"applied"`
+}
+
+// Candidates merges the memo IDs of the vector and the BM25 ranking, best
+// first, into the candidates of a search.
+func Candidates(vectorIDs, bm25IDs []int32) []Candidate {
+	candidates := []Candidate{}
+	index := map[int32]int{}
+	add := func(memoID int32) *Candidate {
+		if i, ok := index[memoID]; ok {
+			return &candidates[i]
+		}
+		index[memoID] = len(candidates)
+		candidates = append(candidates, Candidate{MemoID: memoID})
+		return &candidates[len(candidates)-1]
+	}
+	for i, memoID := range vectorIDs {
+		add(memoID).VectorRank = i + 1
+	}
+	for i, memoID := range bm25IDs {
+		add(memoID).BM25Rank = i + 1
+	}
+	return candidates
+}
+
+// Fuse ranks the candidates by reciprocal rank fusion with a semantic weight,
+// as the hybrid search does, and returns their memo IDs. Ties are broken by
+// memo ID.
+func Fuse(candidates []Candidate, weight float32) []int32 {
+	type scored struct {
+		memoID int32
+		score  float64
+	}
+	ranked := make([]scored, 0, len(candidates))
+	for _, c := range candidates {
+		var score float64
+		if c.VectorRank > 0 {
+			score += float64(weight) / float64(RRFK+c.VectorRank)
+		}
+		if c.BM25Rank > 0 {
+			score += float64(1-weight) / float64(RRFK+c.BM25Rank)
+		}
+		ranked = append(ranked, scored{memoID: c.MemoID, score: score})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].memoID < ranked[j].memoID
+	})
+	ids := make([]int32, len(ranked))
+	for i, r := range ranked {
+		ids[i] = r.memoID
+	}
+	return ids
+}
+
+// NDCG is the normalized discounted cumulative gain at NDCGDepth of a ranking
+// with binary relevance judgments. Unjudged memos are left out of the ranking
+// (condensed lists), since users judge a few results only. It is 0 without
+// relevant judgments.
+func NDCG(ranking []int32, judgments map[int32]bool) float64 {
+	var dcg float64
+	rank := 0
+	for _, memoID := range ranking {
+		relevant, judged := judgments[memoID]
+		if !judged {
+			continue
+		}
+		rank++
+		if rank > NDCGDepth {
+			break
+		}
+		if relevant {
+			dcg += 1 / math.Log2(float64(rank+1))
+		}
+	}
+
+	var ideal float64
+	rank = 0
+	for _, relevant := range judgments {
+		if relevant && rank < NDCGDepth {
+			rank++
+			ideal += 1 / math.Log2(float64(rank+1))
+		}
+	}
+	if ideal == 0 {
+		return 0
+	}
+	return dcg / ideal
+}
+
+// Evaluate replays judged searches with every weight of the grid. The tuned
+// weight has the best mean NDCG; ties go to the weight closest to 0.5, the
+// balanced default.
+func Evaluate(searches []*Search) *Report {
+	report := &Report{Curve: make([]WeightScore, len(Weights))}
+	for i, weight := range Weights {
+		report.Curve[i].Weight = weight
+	}
+	for _, search := range searches {
+		report.Judgments += len(search.Judgments)
+		if !hasRelevant(search.Judgments) {
+			continue
+		}
+		report.Searches++
+		report.Before += NDCG(Fuse(search.Candidates, search.Weight), search.Judgments)
+		for i, weight := range Weights {
+			report.Curve[i].NDCG += NDCG(Fuse(search.Candidates, weight), search.Judgments)
+		}
+	}
+	if report.Searches == 0 {
+		return report
+	}
+
+	report.Before /= float64(report.Searches)
+	best := -1
+	for i := range report.Curve {
+		report.Curve[i].NDCG /= float64(report.Searches)
+		if best < 0 || report.Curve[i].NDCG > report.Curve[best].NDCG ||
+			(report.Curve[i].NDCG == report.Curve[best].NDCG && distance(Weights[i]) < distance(Weights[best])) {
+			best = i
+		}
+	}
+	report.Weight = report.Curve[best].Weight
+	report.After = report.Curve[best].NDCG
+	return report
+}
+
+func hasRelevant(judgments map[int32]bool) bool {
+	for _, relevant := range judgments {
+		if relevant {
+			return true
+		}
+	}
+	return false
+}
+
+func distance(weight float32) float32 {
+	return float32(math.Abs(float64(weight - 0.5)))
+}
@@ -0,0 +1,231 @@
+// Package searchfeedback tunes the hybrid memo search of each user from their
+// relevance feedback.
+//
+// Hybrid searches fuse a vector ranking and a BM25 ranking with reciprocal
+// rank fusion, weighted by a semantic weight. Every hybrid search is logged
+// with the rank of each candidate in both rankings, and users mark results as
+// relevant or irrelevant. Since the logged ranks are enough to fuse the
+// candidates again under any weight, tuning replays the judged searches of a
+// user over a grid of weights and keeps the one with the best NDCG. The
+// report compares the NDCG of the weights the searches used with the tuned
+// one.
+package searchfeedback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalid is returned for invalid feedback.
+var ErrInvalid = errors.New("invalid search feedback")
+
+const (
+	// MinSearches is the number of judged searches, with a relevant result,
+	// needed before a tuned weight is used.
+	MinSearches = 5
+	// maxSearches bounds the judged searches replayed by a tuning, newest first.
+	maxSearches = 500
+	// retention is how long searches without feedback are kept.
+	retention = 30 * 24 * time.Hour
+	// pruneInterval is the minimum time between two prunes of the log.
+	pruneInterval = time.Hour
+)
+
+// Candidate is a memo found by a hybrid search, with its rank in the vector
+// and in the BM25 ranking, from 1; 0 if the ranking missed it.
+type Candidate struct {
+	MemoID     int32 `json:"memo_id"`
+	VectorRank int   `json:"vector_rank,omitempty"`
+	BM25Rank   int   `json:"bm25_rank,omitempty"`
+}
+
+// Search is a logged hybrid search.
+type Search struct {
+	ID     int32
+	UserID int32
+	Query  string
+	// Weight is the semantic weight the search was fused with.
+	Weight     float32
+	Candidates []Candidate
+	CreatedTs  int64
+	// Judgments are the relevance judgments of the user by memo ID.
+	Judgments map[int32]bool
+}
+
+// Feedback is the judgment of a search result by its user.
+type Feedback struct {
+	UserID int32
+	// Query is the query of the search; the feedback goes to the latest
+	// search of the user with this query that found the memo.
+	Query    string
+	MemoUID  string
+	Relevant bool
+}
+
+// Weight is the semantic weight tuned for a user.
+type Weight struct {
+	UserID    int32   `json:"-"`
+	Weight    float32 `json:"weight"`
+	UpdatedTs int64   `json:"updated_ts"`
+}
+
+// Store persists the search log, the feedback and the tuned weights.
+type Store interface {
+	CreateSearch(ctx context.Context, search *Search) error
+	// SaveFeedback saves a judgment; false if no logged search of the user
+	// with the query found the memo.
+	SaveFeedback(ctx context.Context, feedback *Feedback) (bool, error)
+	// ListJudgedSearches returns up to limit searches of a user with
+	// feedback, newest first, with their judgments.
+	ListJudgedSearches(ctx context.Context, userID int32, limit int) ([]*Search, error)
+	// DeleteSearches deletes the searches without feedback created before.
+	DeleteSearches(ctx context.Context, before int64) error
+
+	// GetWeight returns the tuned weight of a user, or nil.
+	GetWeight(ctx context.Context, userID int32) (*Weight, error)
+	SaveWeight(ctx context.Context, weight *Weight) error
+	DeleteWeight(ctx context.Context, userID int32) error
+}
+
+// Service logs hybrid searches and tunes the weights of their users.
+type Service struct {
+	store Store
+
+	mu sync.Mutex
+	// weights caches the tuned weights by user; nil for users without one.
+	weights   map[int32]*Weight
+	lastPrune time.Time
+}
+
+// New creates a feedback service.
+func New(store Store) *Service {
+	return &Service{store: store, weights: map[int32]*Weight{}}
+}
+
+// SemanticWeight returns the weight tuned for a user, if any.
+func (s *Service) SemanticWeight(ctx context.Context, userID int32) (float32, bool) {
+	s.mu.Lock()
+	weight, ok := s.weights[userID]
+	s.mu.Unlock()
+	if !ok {
+		var err error
+		weight, err = s.store.GetWeight(ctx, userID)
+		if err != nil {
+			slog.Warn("failed to get search weight", "user_id", userID, "error", err)
+			return 0, false
+		}
+		s.mu.Lock()
+		s.weights[userID] = weight
+		s.mu.Unlock()
+	}
+	if weight == nil {
+		return 0, false
+	}
+	return weight.Weight, true
+}
+
+// LogSearch records a hybrid search from the memo IDs of both rankings, best
+// first. Searches without candidates are not logged. Failures are only
+// logged, searching goes on.
+func (s *Service) LogSearch(ctx context.Context, userID int32, query string, weight float32, vectorIDs, bm25IDs []int32) {
+	candidates := Candidates(vectorIDs, bm25IDs)
+	if userID == 0 || len(candidates) == 0 {
+		return
+	}
+	now := time.Now()
+	search := &Search{
+		UserID:     userID,
+		Query:      strings.TrimSpace(query),
+		Weight:     weight,
+		Candidates: candidates,
+		CreatedTs:  now.Unix(),
+	}
+	if err := s.store.CreateSearch(ctx, search); err != nil {
+		slog.Warn("failed to log search", "user_id", userID, "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	prune := now.Sub(s.lastPrune) >= pruneInterval
+	if prune {
+		s.lastPrune = now
+	}
+	s.mu.Unlock()
+	if prune {
+		if err := s.store.DeleteSearches(ctx, now.Add(-retention).Unix()); err != nil {
+			slog.Warn("failed to prune search log", "error", err)
+		}
+	}
+}
+
+// Mark saves the judgment of a search result and tunes the weight of the
+// user again. It returns nil if no search of the user found the memo.
+func (s *Service) Mark(ctx context.Context, feedback *Feedback) (*Report, error) {
+	feedback.Query = strings.TrimSpace(feedback.Query)
+	if feedback.Query == "" || feedback.MemoUID == "" {
+		return nil, fmt.Errorf("%w: query and memo_uid are required", ErrInvalid)
+	}
+	found, err := s.store.SaveFeedback(ctx, feedback)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return s.Tune(ctx, feedback.UserID)
+}
+
+// Report evaluates the judged searches of a user without changing the weight.
+func (s *Service) Report(ctx context.Context, userID int32) (*Report, error) {
+	searches, err := s.store.ListJudgedSearches(ctx, userID, maxSearches)
+	if err != nil {
+		return nil, err
+	}
+	report := Evaluate(searches)
+	report.UserID = userID
+	current, err := s.store.GetWeight(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		report.Current = &current.Weight
+	}
+	return report, nil
+}
+
+// Tune evaluates the judged searches of a user and uses the tuned weight
+// when there are enough of them and it improves on the logged weights, or
+// matches them while a tuned weight is in use; otherwise the default
+// weighting is used again.
+func (s *Service) Tune(ctx context.Context, userID int32) (*Report, error) {
+	report, err := s.Report(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	improves := report.After > report.Before || (report.Current != nil && report.After >= report.Before)
+	var weight *Weight
+	if report.Searches >= MinSearches && improves {
+		weight = &Weight{UserID: userID, Weight: report.Weight, UpdatedTs: time.Now().Unix()}
+		err = s.store.SaveWeight(ctx, weight)
+	} else if report.Current != nil {
+		err = s.store.DeleteWeight(ctx, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report.Applied = weight != nil
+	report.Current = nil
+	if weight != nil {
+		report.Current = &weight.Weight
+	}
+	s.mu.Lock()
+	s.weights[userID] = weight
+	s.mu.Unlock()
+	return report, nil
+}
@@ -0,0 +1,199 @@
+package searchfeedback
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore keeps the judged searches and the weights in memory.
+type fakeStore struct {
+	searches []*Search
+	weights  map[int32]*Weight
+	logged   []*Search
+	found    bool
+	gets     int
+}
+
+func (s *fakeStore) CreateSearch(_ context.Context, search *Search) error {
+	s.logged = append(s.logged, search)
+	return nil
+}
+
+func (s *fakeStore) SaveFeedback(context.Context, *Feedback) (bool, error) {
+	return s.found, nil
+}
+
+func (s *fakeStore) ListJudgedSearches(context.Context, int32, int) ([]*Search, error) {
+	return s.searches, nil
+}
+
+func (s *fakeStore) DeleteSearches(context.Context, int64) error {
+	return nil
+}
+
+func (s *fakeStore) GetWeight(_ context.Context, userID int32) (*Weight, error) {
+	s.gets++
+	return s.weights[userID], nil
+}
+
+func (s *fakeStore) SaveWeight(_ context.Context, weight *Weight) error {
+	s.weights[weight.UserID] = weight
+	return nil
+}
+
+func (s *fakeStore) DeleteWeight(_ context.Context, userID int32) error {
+	delete(s.weights, userID)
+	return nil
+}
+
+// keywordSearch is a search where BM25 ranks the relevant memo 1 first and
+// the vector search ranks it last.
+func keywordSearch(weight float32) *Search {
+	return &Search{
+		Weight:     weight,
+		Candidates: Candidates([]int32{2, 3, 1}, []int32{1, 2}),
+		Judgments:  map[int32]bool{1: true, 2: false},
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	assert.Equal(t, []Candidate{
+		{MemoID: 2, VectorRank: 1, BM25Rank: 2},
+		{MemoID: 3, VectorRank: 2},
+		{MemoID: 1, VectorRank: 3, BM25Rank: 1},
+	}, Candidates([]int32{2, 3, 1}, []int32{1, 2}))
+}
+
+func TestFuse(t *testing.T) {
+	candidates := Candidates([]int32{2, 3, 1}, []int32{1, 2})
+	assert.Equal(t, []int32{2, 1, 3}, Fuse(candidates, 0.9))
+	assert.Equal(t, []int32{1, 2, 3}, Fuse(candidates, 0.1))
+	// Ties go to the lower memo ID
+	assert.Equal(t, []int32{1, 2}, Fuse(Candidates([]int32{2}, []int32{1}), 0.5))
+}
+
+func TestNDCG(t *testing.T) {
+	judgments := map[int32]bool{1: true, 2: false}
+	assert.Equal(t, 1.0, NDCG([]int32{1, 2}, judgments))
+	assert.InDelta(t, 0.6309, NDCG([]int32{2, 1}, judgments), 0.0001)
+	// Unjudged memos are skipped
+	assert.Equal(t, 1.0, NDCG([]int32{3, 1, 2}, judgments))
+	assert.Equal(t, 0.0, NDCG([]int32{1, 2}, map[int32]bool{1: false}))
+}
+
+func TestEvaluate(t *testing.T) {
+	report := Evaluate([]*Search{
+		keywordSearch(0.8),
+		keywordSearch(0.8),
+		{Weight: 0.8, Candidates: Candidates([]int32{4}, nil), Judgments: map[int32]bool{4: false}},
+	})
+	assert.Equal(t, 2, report.Searches)
+	assert.Equal(t, 5, report.Judgments)
+	assert.InDelta(t, 0.6309, report.Before, 0.0001)
+	assert.Equal(t, 1.0, report.After)
+	// 0.1 to 0.3 rank memo 1 first; the closest to 0.5 wins
+	assert.Equal(t, float32(0.3), report.Weight)
+	require.Len(t, report.Curve, len(Weights))
+	assert.Equal(t, 1.0, report.Curve[0].NDCG)
+
+	empty := Evaluate(nil)
+	assert.Zero(t, empty.Searches)
+	assert.Zero(t, empty.After)
+}
+
+func TestService_Tune(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{weights: map[int32]*Weight{}}
+	service := New(store)
+
+	// Too few judged searches
+	store.searches = []*Search{keywordSearch(0.8)}
+	report, err := service.Tune(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, report.Applied)
+	_, ok := service.SemanticWeight(ctx, 1)
+	assert.False(t, ok)
+
+	for range MinSearches - 1 {
+		store.searches = append(store.searches, keywordSearch(0.8))
+	}
+	report, err = service.Tune(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, report.Applied)
+	require.NotNil(t, report.Current)
+	assert.Equal(t, float32(0.3), *report.Current)
+	weight, ok := service.SemanticWeight(ctx, 1)
+	assert.True(t, ok)
+	assert.Equal(t, float32(0.3), weight)
+
+	// Searches ranked with the tuned weight keep it
+	for _, search := range store.searches {
+		search.Weight = 0.3
+	}
+	report, err = service.Tune(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, report.Applied)
+	assert.Equal(t, report.Before, report.After)
+
+	// Searches without relevant results do not count
+	for _, search := range store.searches {
+		search.Judgments = map[int32]bool{1: false, 2: false}
+	}
+	report, err = service.Tune(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, report.Applied)
+	assert.Nil(t, report.Current)
+	assert.Empty(t, store.weights)
+	_, ok = service.SemanticWeight(ctx, 1)
+	assert.False(t, ok)
+}
+
+func TestService_SemanticWeight(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{weights: map[int32]*Weight{2: {UserID: 2, Weight: 0.7}}}
+	service := New(store)
+
+	weight, ok := service.SemanticWeight(ctx, 2)
+	assert.True(t, ok)
+	assert.Equal(t, float32(0.7), weight)
+	_, ok = service.SemanticWeight(ctx, 3)
+	assert.False(t, ok)
+
+	// Weights are cached, users without one too
+	service.SemanticWeight(ctx, 2)
+	service.SemanticWeight(ctx, 3)
+	assert.Equal(t, 2, store.gets)
+}
+
+func TestService_Mark(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{weights: map[int32]*Weight{}}
+	service := New(store)
+
+	_, err := service.Mark(ctx, &Feedback{UserID: 1, Query: "  ", MemoUID: "m1"})
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	report, err := service.Mark(ctx, &Feedback{UserID: 1, Query: "q", MemoUID: "m1", Relevant: true})
+	require.NoError(t, err)
+	assert.Nil(t, report)
+
+	store.found = true
+	report, err = service.Mark(ctx, &Feedback{UserID: 1, Query: "q", MemoUID: "m1", Relevant: true})
+	require.NoError(t, err)
+	assert.NotNil(t, report)
+}
+
+func TestService_LogSearch(t *testing.T) {
+	store := &fakeStore{}
+	service := New(store)
+
+	service.LogSearch(context.Background(), 1, " notes ", 0.5, []int32{1}, []int32{2})
+	service.LogSearch(context.Background(), 1, "empty", 0.5, nil, nil)
+	service.LogSearch(context.Background(), 0, "anonymous", 0.5, []int32{1}, nil)
+	require.Len(t, store.logged, 1)
+	assert.Equal(t, "notes", store.logged[0].Query)
+	assert.Len(t, store.logged[0].Candidates, 2)
+}
@@ -0,0 +1,152 @@
+package searchfeedback
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DBStore persists the feedback in the search_log, search_feedback and
+// search_weight tables (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new feedback store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// CreateSearch implements Store.
+func (s *DBStore) CreateSearch(ctx context.Context, search *Search) error {
+	candidates, err := json.Marshal(search.Candidates)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search candidates: %w", err)
+	}
+	if err := s.db.QueryRowContext(ctx, `
+		INSERT INTO search_log (user_id, query, semantic_weight, candidates, created_ts)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`,
+		search.UserID, search.Query, search.Weight, candidates, search.CreatedTs).Scan(&search.ID); err != nil {
+		return fmt.Errorf("failed to create search log: %w", err)
+	}
+	return nil
+}
+
+// SaveFeedback implements Store. A memo judged again keeps the last judgment.
+func (s *DBStore) SaveFeedback(ctx context.Context, feedback *Feedback) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO search_feedback (search_id, memo_id, relevant, created_ts)
+		SELECT l.id, m.id, $4, $5
+		FROM search_log l
+		JOIN memo m ON m.uid = $3
+		WHERE l.user_id = $1 AND l.query = $2
+			AND l.candidates @> jsonb_build_array(jsonb_build_object('memo_id', m.id))
+		ORDER BY l.created_ts DESC, l.id DESC
+		LIMIT 1
+		ON CONFLICT (search_id, memo_id) DO UPDATE SET relevant = EXCLUDED.relevant, created_ts = EXCLUDED.created_ts`,
+		feedback.UserID, feedback.Query, feedback.MemoUID, feedback.Relevant, time.Now().Unix())
+	if err != nil {
+		return false, fmt.Errorf("failed to save search feedback: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to save search feedback: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ListJudgedSearches implements Store.
+func (s *DBStore) ListJudgedSearches(ctx context.Context, userID int32, limit int) ([]*Search, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		WITH judged AS (
+			SELECT l.id, l.user_id, l.query, l.semantic_weight, l.candidates, l.created_ts
+			FROM search_log l
+			WHERE l.user_id = $1 AND EXISTS (SELECT 1 FROM search_feedback f WHERE f.search_id = l.id)
+			ORDER BY l.created_ts DESC, l.id DESC
+			LIMIT $2
+		)
+		SELECT j.id, j.user_id, j.query, j.semantic_weight, j.candidates, j.created_ts, f.memo_id, f.relevant
+		FROM judged j
+		JOIN search_feedback f ON f.search_id = j.id
+		ORDER BY j.created_ts DESC, j.id DESC`, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list judged searches: %w", err)
+	}
+	defer rows.Close()
+
+	searches := []*Search{}
+	for rows.Next() {
+		var search Search
+		var candidates []byte
+		var memoID int32
+		var relevant bool
+		if err := rows.Scan(&search.ID, &search.UserID, &search.Query, &search.Weight, &candidates, &search.CreatedTs,
+			&memoID, &relevant); err != nil {
+			return nil, fmt.Errorf("failed to scan judged search: %w", err)
+		}
+		if len(searches) == 0 || searches[len(searches)-1].ID != search.ID {
+			if err := json.Unmarshal(candidates, &search.Candidates); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal search candidates: %w", err)
+			}
+			search.Judgments = map[int32]bool{}
+			searches = append(searches, &search)
+		}
+		searches[len(searches)-1].Judgments[memoID] = relevant
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list judged searches: %w", err)
+	}
+	return searches, nil
+}
+
+// DeleteSearches implements Store.
+func (s *DBStore) DeleteSearches(ctx context.Context, before int64) error {
+	if _, err := s.db.ExecContext(ctx, `
+		DELETE FROM search_log l
+		WHERE l.created_ts < $1 AND NOT EXISTS (SELECT 1 FROM search_feedback f WHERE f.search_id = l.id)`,
+		before); err != nil {
+		return fmt.Errorf("failed to delete search logs: %w", err)
+	}
+	return nil
+}
+
+// GetWeight implements Store.
+func (s *DBStore) GetWeight(ctx context.Context, userID int32) (*Weight, error) {
+	weight := &Weight{UserID: userID}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT semantic_weight, updated_ts FROM search_weight WHERE user_id = $1`, userID).
+		Scan(&weight.Weight, &weight.UpdatedTs)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search weight: %w", err)
+	}
+	return weight, nil
+}
+
+// SaveWeight implements Store.
+func (s *DBStore) SaveWeight(ctx context.Context, weight *Weight) error {
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO search_weight (user_id, semantic_weight, updated_ts)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET
+			semantic_weight = EXCLUDED.semantic_weight,
+			updated_ts = EXCLUDED.updated_ts`,
+		weight.UserID, weight.Weight, weight.UpdatedTs); err != nil {
+		return fmt.Errorf("failed to save search weight: %w", err)
+	}
+	return nil
+}
+
+// DeleteWeight implements Store.
+func (s *DBStore) DeleteWeight(ctx context.Context, userID int32) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM search_weight WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("failed to delete search weight: %w", err)
+	}
+	return nil
+}
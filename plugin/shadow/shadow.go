@@ -0,0 +1,204 @@
+// Package shadow runs modified prompt and model configurations in shadow.
+//
+// A shadow is a parrot declared as a variant of a production parrot, usually
+// with a changed system prompt or model. A configurable share of the requests
+// answered by the production parrot is also executed by the shadow, whose
+// answer is logged but never shown to users. The paired results are kept for
+// offline comparison before the change is shipped.
+package shadow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"unicode/utf8"
+)
+
+var (
+	// ErrNotFound is returned for shadows that are not registered.
+	ErrNotFound = errors.New("shadow not found")
+	// ErrInvalid is returned for sample rates out of 0-100.
+	ErrInvalid = errors.New("sample_rate must be between 0 and 100")
+)
+
+// maxAnswerLength is the number of runes of the answers kept per run.
+const maxAnswerLength = 4000
+
+// Variant is a shadow of a production parrot.
+type Variant struct {
+	Name       string `json:"name"`
+	Production string `json:"production"`
+	// Model is the LLM model of the shadow, empty for the production model.
+	Model string `json:"model,omitempty"`
+	// SampleRate is the percentage of the production requests run in shadow.
+	SampleRate int `json:"sample_rate"`
+}
+
+// Run is a request answered by a production parrot and by its shadow.
+type Run struct {
+	ID                  int32  `json:"id"`
+	Shadow              string `json:"shadow"`
+	Production          string `json:"production"`
+	Model               string `json:"model,omitempty"`
+	UserID              int32  `json:"user_id"`
+	Query               string `json:"query"`
+	ProductionAnswer    string `json:"production_answer"`
+	ShadowAnswer        string `json:"shadow_answer"`
+	ProductionLatencyMs int64  `json:"production_latency_ms"`
+	ShadowLatencyMs     int64  `json:"shadow_latency_ms"`
+	ProductionTokens    int    `json:"production_tokens"`
+	ShadowTokens        int    `json:"shadow_tokens"`
+	ProductionError     string `json:"production_error,omitempty"`
+	ShadowError         string `json:"shadow_error,omitempty"`
+	CreatedTs           int64  `json:"created_ts"`
+}
+
+// Store persists the sample rates and the runs.
+type Store interface {
+	// ListSampleRates returns the sample rates set by admins, by shadow.
+	ListSampleRates(ctx context.Context) (map[string]int, error)
+	SaveSampleRate(ctx context.Context, name string, rate int) error
+	SaveRun(ctx context.Context, run *Run) error
+	// ListRuns returns the runs of a shadow since a Unix time, latest first.
+	ListRuns(ctx context.Context, name string, since int64, limit int) ([]*Run, error)
+}
+
+// Shadows holds the registered shadows.
+type Shadows struct {
+	store Store
+	// sample returns a number in 0-99 per request and shadow.
+	sample func() int
+
+	mu        sync.RWMutex
+	variants  map[string]*Variant
+	overrides map[string]int // nil until loaded
+}
+
+// New creates the shadows.
+func New(store Store) *Shadows {
+	return &Shadows{
+		store:    store,
+		sample:   func() int { return rand.IntN(100) },
+		variants: make(map[string]*Variant),
+	}
+}
+
+// Register adds a shadow of production with its configured sample rate; a
+// sample rate set by admins takes precedence.
+func (s *Shadows) Register(ctx context.Context, name, production, model string, rate int) {
+	overrides := s.loadOverrides(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if override, ok := overrides[name]; ok {
+		rate = override
+	}
+	s.variants[name] = &Variant{Name: name, Production: production, Model: model, SampleRate: min(max(rate, 0), 100)}
+}
+
+// loadOverrides loads the sample rates set by admins once.
+func (s *Shadows) loadOverrides(ctx context.Context) map[string]int {
+	s.mu.RLock()
+	overrides := s.overrides
+	s.mu.RUnlock()
+	if overrides != nil {
+		return overrides
+	}
+	overrides, err := s.store.ListSampleRates(ctx)
+	if err != nil || overrides == nil {
+		// Retried on the next registration
+		return map[string]int{}
+	}
+	s.mu.Lock()
+	s.overrides = overrides
+	s.mu.Unlock()
+	return overrides
+}
+
+// Sample returns the shadows of production that run the current request,
+// each sampled independently at its rate.
+func (s *Shadows) Sample(production string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var names []string
+	for name, variant := range s.variants {
+		if variant.Production == production && variant.SampleRate > 0 && s.sample() < variant.SampleRate {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// List returns the shadows ordered by name.
+func (s *Shadows) List() []*Variant {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	variants := make([]*Variant, 0, len(s.variants))
+	for _, variant := range s.variants {
+		copied := *variant
+		variants = append(variants, &copied)
+	}
+	sort.Slice(variants, func(i, j int) bool { return variants[i].Name < variants[j].Name })
+	return variants
+}
+
+// Get returns a shadow.
+func (s *Shadows) Get(name string) (*Variant, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	variant, ok := s.variants[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *variant
+	return &copied, nil
+}
+
+// SetSampleRate changes the share of requests run by a shadow; 0 pauses it.
+func (s *Shadows) SetSampleRate(ctx context.Context, name string, rate int) (*Variant, error) {
+	if rate < 0 || rate > 100 {
+		return nil, ErrInvalid
+	}
+	if _, err := s.Get(name); err != nil {
+		return nil, err
+	}
+	if err := s.store.SaveSampleRate(ctx, name, rate); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.variants[name].SampleRate = rate
+	if s.overrides != nil {
+		s.overrides[name] = rate
+	}
+	copied := *s.variants[name]
+	return &copied, nil
+}
+
+// Record saves the paired results of a request.
+func (s *Shadows) Record(ctx context.Context, run *Run) error {
+	run.ProductionAnswer = truncate(run.ProductionAnswer)
+	run.ShadowAnswer = truncate(run.ShadowAnswer)
+	if err := s.store.SaveRun(ctx, run); err != nil {
+		return fmt.Errorf("failed to record shadow run: %w", err)
+	}
+	return nil
+}
+
+// Runs returns the runs of a shadow since a Unix time, latest first.
+func (s *Shadows) Runs(ctx context.Context, name string, since int64, limit int) ([]*Run, error) {
+	if _, err := s.Get(name); err != nil {
+		return nil, err
+	}
+	return s.store.ListRuns(ctx, name, since, limit)
+}
+
+func truncate(answer string) string {
+	if utf8.RuneCountInString(answer) <= maxAnswerLength {
+		return answer
+	}
+	return string([]rune(answer)[:maxAnswerLength])
+}
@@ -0,0 +1,136 @@
+package shadow
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	rates map[string]int
+	runs  []*Run
+}
+
+func (s *fakeStore) ListSampleRates(_ context.Context) (map[string]int, error) {
+	rates := make(map[string]int, len(s.rates))
+	for name, rate := range s.rates {
+		rates[name] = rate
+	}
+	return rates, nil
+}
+
+func (s *fakeStore) SaveSampleRate(_ context.Context, name string, rate int) error {
+	if s.rates == nil {
+		s.rates = make(map[string]int)
+	}
+	s.rates[name] = rate
+	return nil
+}
+
+func (s *fakeStore) SaveRun(_ context.Context, run *Run) error {
+	s.runs = append(s.runs, run)
+	return nil
+}
+
+func (s *fakeStore) ListRuns(_ context.Context, name string, since int64, limit int) ([]*Run, error) {
+	var runs []*Run
+	for _, run := range s.runs {
+		if run.Shadow == name && run.CreatedTs >= since && len(runs) < limit {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+func TestShadows_Register(t *testing.T) {
+	ctx := context.Background()
+	shadows := New(&fakeStore{rates: map[string]int{"memo_concise": 5}})
+
+	shadows.Register(ctx, "memo_concise", "memo", "", 20)
+	shadows.Register(ctx, "schedule_reasoner", "schedule", "deepseek-reasoner", -1)
+
+	// The sample rate set by admins wins over the configured one
+	variant, err := shadows.Get("memo_concise")
+	require.NoError(t, err)
+	assert.Equal(t, 5, variant.SampleRate)
+	variant, err = shadows.Get("schedule_reasoner")
+	require.NoError(t, err)
+	assert.Equal(t, 0, variant.SampleRate)
+	assert.Equal(t, "deepseek-reasoner", variant.Model)
+
+	variants := shadows.List()
+	require.Len(t, variants, 2)
+	assert.Equal(t, "memo_concise", variants[0].Name)
+
+	_, err = shadows.Get("general_concise")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestShadows_Sample(t *testing.T) {
+	ctx := context.Background()
+	shadows := New(&fakeStore{})
+	shadows.Register(ctx, "memo_concise", "memo", "", 30)
+	shadows.Register(ctx, "memo_reasoner", "memo", "deepseek-reasoner", 100)
+
+	draw := 50
+	shadows.sample = func() int { return draw }
+	assert.Equal(t, []string{"memo_reasoner"}, shadows.Sample("memo"))
+	draw = 10
+	assert.Equal(t, []string{"memo_concise", "memo_reasoner"}, shadows.Sample("memo"))
+	assert.Empty(t, shadows.Sample("schedule"))
+
+	// A paused shadow never runs
+	_, err := shadows.SetSampleRate(ctx, "memo_reasoner", 0)
+	require.NoError(t, err)
+	draw = 0
+	assert.Equal(t, []string{"memo_concise"}, shadows.Sample("memo"))
+}
+
+func TestShadows_SetSampleRate(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{}
+	shadows := New(store)
+	shadows.Register(ctx, "memo_concise", "memo", "", 10)
+
+	_, err := shadows.SetSampleRate(ctx, "memo_concise", 101)
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = shadows.SetSampleRate(ctx, "general_concise", 50)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	variant, err := shadows.SetSampleRate(ctx, "memo_concise", 50)
+	require.NoError(t, err)
+	assert.Equal(t, 50, variant.SampleRate)
+	assert.Equal(t, 50, store.rates["memo_concise"])
+
+	// The override survives a new registration of the config
+	shadows.Register(ctx, "memo_concise", "memo", "", 10)
+	variant, err = shadows.Get("memo_concise")
+	require.NoError(t, err)
+	assert.Equal(t, 50, variant.SampleRate)
+}
+
+func TestShadows_Record(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{}
+	shadows := New(store)
+	shadows.Register(ctx, "memo_concise", "memo", "", 10)
+
+	require.NoError(t, shadows.Record(ctx, &Run{
+		Shadow:           "memo_concise",
+		Production:       "memo",
+		ProductionAnswer: "找到 2 条周报",
+		ShadowAnswer:     strings.Repeat("周报", maxAnswerLength),
+	}))
+	require.Len(t, store.runs, 1)
+	assert.Equal(t, maxAnswerLength, len([]rune(store.runs[0].ShadowAnswer)))
+
+	runs, err := shadows.Runs(ctx, "memo_concise", 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, runs, 1)
+
+	_, err = shadows.Runs(ctx, "general_concise", 0, 10)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,96 @@
+package shadow
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DBStore persists shadows in the shadow_sample_rate and shadow_run tables
+// (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new shadow store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// ListSampleRates implements Store.
+func (s *DBStore) ListSampleRates(ctx context.Context) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT name, sample_rate FROM shadow_sample_rate")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shadow sample rates: %w", err)
+	}
+	defer rows.Close()
+
+	rates := make(map[string]int)
+	for rows.Next() {
+		var name string
+		var rate int
+		if err := rows.Scan(&name, &rate); err != nil {
+			return nil, fmt.Errorf("failed to scan shadow sample rate: %w", err)
+		}
+		rates[name] = rate
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list shadow sample rates: %w", err)
+	}
+	return rates, nil
+}
+
+// SaveSampleRate implements Store.
+func (s *DBStore) SaveSampleRate(ctx context.Context, name string, rate int) error {
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO shadow_sample_rate (name, sample_rate, updated_ts) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET sample_rate = EXCLUDED.sample_rate, updated_ts = EXCLUDED.updated_ts`,
+		name, rate, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to save shadow sample rate: %w", err)
+	}
+	return nil
+}
+
+// SaveRun implements Store.
+func (s *DBStore) SaveRun(ctx context.Context, r *Run) error {
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO shadow_run (shadow, production, model, user_id, query, production_answer, shadow_answer,
+			production_latency_ms, shadow_latency_ms, production_tokens, shadow_tokens,
+			production_error, shadow_error, created_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		r.Shadow, r.Production, r.Model, r.UserID, r.Query, r.ProductionAnswer, r.ShadowAnswer,
+		r.ProductionLatencyMs, r.ShadowLatencyMs, r.ProductionTokens, r.ShadowTokens,
+		r.ProductionError, r.ShadowError, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to save shadow run: %w", err)
+	}
+	return nil
+}
+
+// ListRuns implements Store.
+func (s *DBStore) ListRuns(ctx context.Context, name string, since int64, limit int) ([]*Run, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, shadow, production, model, user_id, query, production_answer, shadow_answer,
+			production_latency_ms, shadow_latency_ms, production_tokens, shadow_tokens,
+			production_error, shadow_error, created_ts
+		FROM shadow_run WHERE shadow = $1 AND created_ts >= $2 ORDER BY id DESC LIMIT $3`, name, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shadow runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []*Run{}
+	for rows.Next() {
+		r := &Run{}
+		if err := rows.Scan(&r.ID, &r.Shadow, &r.Production, &r.Model, &r.UserID, &r.Query, &r.ProductionAnswer, &r.ShadowAnswer,
+			&r.ProductionLatencyMs, &r.ShadowLatencyMs, &r.ProductionTokens, &r.ShadowTokens,
+			&r.ProductionError, &r.ShadowError, &r.CreatedTs); err != nil {
+			return nil, fmt.Errorf("failed to scan shadow run: %w", err)
+		}
+		runs = append(runs, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list shadow runs: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,61 @@
+package snippet
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// IndexFile lists the exported snippets with their language and symbols.
+	IndexFile = "INDEX.md"
+	// maxExport bounds the snippets exported into a directory.
+	maxExport = 2000
+)
+
+// FileName returns the name of the file holding an exported snippet.
+func FileName(snippet *Snippet) string {
+	return fmt.Sprintf("%s-%d.%s", snippet.MemoUID, snippet.BlockIndex, Extension(snippet.Language))
+}
+
+// Export writes the snippets of a user into dir, one file per code block,
+// with an index of their symbols, and returns how many were written. The
+// previous content of dir is replaced.
+func (l *Library) Export(ctx context.Context, userID int32, dir string) (int, error) {
+	snippets, err := l.store.Search(ctx, &Query{CreatorID: userID, Limit: maxExport})
+	if err != nil {
+		return 0, err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return 0, fmt.Errorf("clear snippet directory: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return 0, fmt.Errorf("create snippet directory: %w", err)
+	}
+	for _, snippet := range snippets {
+		if err := os.WriteFile(filepath.Join(dir, FileName(snippet)), []byte(snippet.Code+"\n"), 0o600); err != nil {
+			return 0, fmt.Errorf("write snippet: %w", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, IndexFile), []byte(formatIndex(snippets)), 0o600); err != nil {
+		return 0, fmt.Errorf("write snippet index: %w", err)
+	}
+	return len(snippets), nil
+}
+
+func formatIndex(snippets []*Snippet) string {
+	var sb strings.Builder
+	sb.WriteString("# Snippet library\n\n")
+	if len(snippets) == 0 {
+		sb.WriteString("The user has no snippet yet. Memos tagged #snippet appear here.\n")
+		return sb.String()
+	}
+	sb.WriteString("| File | Language | Symbols | Memo |\n|---|---|---|---|\n")
+	for _, snippet := range snippets {
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n",
+			FileName(snippet), languageLabel(snippet.Language), strings.Join(snippet.Symbols, ", "), snippet.MemoUID)
+	}
+	return sb.String()
+}
@@ -0,0 +1,410 @@
+// Package snippet turns memos tagged #snippet into a code library: their
+// code blocks are indexed with their language and the symbols they define,
+// so they can be searched by symbol or regex, by users and by agents.
+package snippet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	agents "github.com/hrygo/divinesense/ai/agents"
+)
+
+const (
+	// Tag marks a memo as a snippet. "#snippet/<language>" also sets the
+	// language of the code blocks without one.
+	Tag = "snippet"
+	// ToolName is the name of the agent tool searching snippets.
+	ToolName = "snippet_search"
+
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+	// maxSymbols bounds the symbols indexed per code block.
+	maxSymbols = 50
+	// maxMatches bounds the matching lines reported per snippet.
+	maxMatches = 5
+	// maxToolLines bounds the lines of code of a snippet shown to agents.
+	maxToolLines = 40
+)
+
+// ErrInvalidPattern is returned when a search pattern is not a valid regex.
+var ErrInvalidPattern = errors.New("invalid pattern")
+
+// Block is a fenced code block of a snippet memo.
+type Block struct {
+	Index    int
+	Language string
+	Code     string
+	Symbols  []string
+}
+
+// Snippet is an indexed code block, with the UID of its memo.
+type Snippet struct {
+	MemoID     int32    `json:"-"`
+	MemoUID    string   `json:"memoUid"`
+	BlockIndex int      `json:"blockIndex"`
+	Language   string   `json:"language"`
+	Symbols    []string `json:"symbols"`
+	Code       string   `json:"code"`
+	UpdatedTs  int64    `json:"updatedTs"`
+	// Matches are the lines matching the search pattern, if any.
+	Matches []Match `json:"matches,omitempty"`
+}
+
+// Match is a line of code matching a search pattern.
+type Match struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// Query selects snippets. Empty filters match every snippet.
+type Query struct {
+	CreatorID int32
+	// Symbol matches the snippets defining a symbol containing it, ignoring case.
+	Symbol string
+	// Pattern is a regular expression matched against the code.
+	Pattern  string
+	Language string
+	Limit    int
+}
+
+// Store persists the snippets.
+type Store interface {
+	// Replace sets the code blocks of a memo; no blocks removes the memo
+	// from the library.
+	Replace(ctx context.Context, memoID, creatorID int32, blocks []Block) error
+	// Search returns the snippets matching query, most recently updated first.
+	Search(ctx context.Context, query *Query) ([]*Snippet, error)
+}
+
+// Library indexes and searches snippet memos.
+type Library struct {
+	store Store
+}
+
+// NewLibrary creates a new snippet library.
+func NewLibrary(store Store) *Library {
+	return &Library{store: store}
+}
+
+// IndexMemo indexes the code blocks of a memo if it is tagged #snippet, and
+// removes it from the library otherwise.
+func (l *Library) IndexMemo(ctx context.Context, memoID, creatorID int32, content string, tags []string) error {
+	if l == nil {
+		return nil
+	}
+	var blocks []Block
+	if ok, language := SnippetTag(tags); ok {
+		blocks = ParseBlocks(content, language)
+	}
+	return l.store.Replace(ctx, memoID, creatorID, blocks)
+}
+
+// Search returns the snippets of a user matching query, with the lines
+// matching its pattern.
+func (l *Library) Search(ctx context.Context, query *Query) ([]*Snippet, error) {
+	var pattern *regexp.Regexp
+	if query.Pattern != "" {
+		var err error
+		if pattern, err = regexp.Compile(query.Pattern); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+		}
+	}
+	q := *query
+	q.Symbol = strings.TrimSpace(q.Symbol)
+	q.Language = NormalizeLanguage(q.Language)
+	if q.Limit <= 0 {
+		q.Limit = defaultSearchLimit
+	}
+	q.Limit = min(q.Limit, maxSearchLimit)
+
+	snippets, err := l.store.Search(ctx, &q)
+	if err != nil {
+		return nil, err
+	}
+	if pattern != nil {
+		for _, snippet := range snippets {
+			snippet.Matches = matchLines(snippet.Code, pattern)
+		}
+	}
+	return snippets, nil
+}
+
+func matchLines(code string, pattern *regexp.Regexp) []Match {
+	var matches []Match
+	for i, line := range strings.Split(code, "\n") {
+		if pattern.MatchString(line) {
+			matches = append(matches, Match{Line: i + 1, Text: line})
+			if len(matches) == maxMatches {
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// SnippetTag reports whether tags mark a snippet memo, and the language set
+// by a "snippet/<language>" tag.
+func SnippetTag(tags []string) (bool, string) {
+	found, language := false, ""
+	for _, tag := range tags {
+		name, sub, _ := strings.Cut(strings.ToLower(tag), "/")
+		if name != Tag {
+			continue
+		}
+		found = true
+		if language == "" {
+			language = NormalizeLanguage(sub)
+		}
+	}
+	return found, language
+}
+
+// ParseBlocks returns the fenced code blocks of a markdown text. Blocks
+// without a language get defaultLanguage.
+func ParseBlocks(content, defaultLanguage string) []Block {
+	var (
+		blocks []Block
+		fence  string
+		block  *Block
+		lines  []string
+	)
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if block == nil {
+			if len(line)-len(trimmed) > 3 {
+				continue
+			}
+			if marker := fenceMarker(trimmed); marker != "" {
+				fence = marker
+				info := strings.Fields(strings.TrimPrefix(trimmed, marker))
+				block = &Block{Index: len(blocks), Language: defaultLanguage}
+				if len(info) > 0 {
+					block.Language = NormalizeLanguage(strings.Trim(info[0], "{}."))
+				}
+				lines = nil
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, fence) && strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1])) == "" {
+			if code := strings.Join(lines, "\n"); strings.TrimSpace(code) != "" {
+				block.Code = code
+				block.Symbols = ExtractSymbols(code)
+				blocks = append(blocks, *block)
+			}
+			block = nil
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return blocks
+}
+
+// fenceMarker returns the opening fence of a line, e.g. "```", or "".
+func fenceMarker(line string) string {
+	for _, char := range []string{"`", "~"} {
+		n := len(line) - len(strings.TrimLeft(line, char))
+		if n >= 3 {
+			// Backtick fences may not contain backticks in their info string.
+			if char == "`" && strings.Contains(line[n:], "`") {
+				return ""
+			}
+			return line[:n]
+		}
+	}
+	return ""
+}
+
+var languageAliases = map[string]string{
+	"golang":  "go",
+	"py":      "python",
+	"python3": "python",
+	"js":      "javascript",
+	"node":    "javascript",
+	"ts":      "typescript",
+	"sh":      "shell",
+	"bash":    "shell",
+	"zsh":     "shell",
+	"rb":      "ruby",
+	"rs":      "rust",
+	"kt":      "kotlin",
+	"c++":     "cpp",
+	"cc":      "cpp",
+	"cxx":     "cpp",
+	"cs":      "csharp",
+	"c#":      "csharp",
+	"yml":     "yaml",
+	"ps1":     "powershell",
+	"md":      "markdown",
+}
+
+// NormalizeLanguage returns the canonical name of a language, e.g. "python" for "py".
+func NormalizeLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if alias, ok := languageAliases[language]; ok {
+		return alias
+	}
+	return language
+}
+
+var extensions = map[string]string{
+	"go":         "go",
+	"python":     "py",
+	"javascript": "js",
+	"typescript": "ts",
+	"tsx":        "tsx",
+	"jsx":        "jsx",
+	"shell":      "sh",
+	"ruby":       "rb",
+	"rust":       "rs",
+	"java":       "java",
+	"kotlin":     "kt",
+	"c":          "c",
+	"cpp":        "cpp",
+	"csharp":     "cs",
+	"swift":      "swift",
+	"php":        "php",
+	"lua":        "lua",
+	"sql":        "sql",
+	"html":       "html",
+	"css":        "css",
+	"json":       "json",
+	"yaml":       "yaml",
+	"toml":       "toml",
+	"powershell": "ps1",
+	"markdown":   "md",
+	"dockerfile": "dockerfile",
+}
+
+// Extension returns the file extension of a language, "txt" when unknown.
+func Extension(language string) string {
+	if ext, ok := extensions[language]; ok {
+		return ext
+	}
+	return "txt"
+}
+
+// symbolPatterns capture the names defined by a code block. They are
+// heuristics shared by all languages rather than parsers.
+var symbolPatterns = []*regexp.Regexp{
+	// Go functions and methods.
+	regexp.MustCompile(`(?m)^[ \t]*func[ \t]+(?:\([^)]*\)[ \t]*)?([A-Za-z_]\w*)`),
+	// Functions, classes and types of most languages.
+	regexp.MustCompile(`(?m)^[ \t]*(?:(?:export|default|public|private|protected|static|abstract|final|async|pub(?:\([^)]*\))?)[ \t]+)*` +
+		`(?:def|class|fn|struct|enum|trait|interface|type|module|function\*?)[ \t]+([A-Za-z_$][\w$]*)`),
+	// JavaScript functions assigned to variables.
+	regexp.MustCompile(`(?m)^[ \t]*(?:export[ \t]+)?(?:const|let|var)[ \t]+([A-Za-z_$][\w$]*)[ \t]*=[ \t]*(?:async[ \t]*)?(?:function\b|\([^)]*\)[ \t]*=>|[A-Za-z_$][\w$]*[ \t]*=>)`),
+	// SQL objects.
+	regexp.MustCompile(`(?im)^[ \t]*create[ \t]+(?:or[ \t]+replace[ \t]+)?(?:table|view|function|procedure|index|trigger|type)[ \t]+(?:if[ \t]+not[ \t]+exists[ \t]+)?([\w."]+)`),
+}
+
+// ExtractSymbols returns the names of the functions, types and classes
+// defined by code, in order of appearance.
+func ExtractSymbols(code string) []string {
+	type position struct {
+		offset int
+		name   string
+	}
+	var found []position
+	for _, pattern := range symbolPatterns {
+		for _, match := range pattern.FindAllStringSubmatchIndex(code, -1) {
+			found = append(found, position{match[2], strings.Trim(code[match[2]:match[3]], `"`)})
+		}
+	}
+	slices.SortStableFunc(found, func(a, b position) int { return a.offset - b.offset })
+
+	var symbols []string
+	for _, p := range found {
+		if p.name != "" && !slices.Contains(symbols, p.name) {
+			symbols = append(symbols, p.name)
+			if len(symbols) == maxSymbols {
+				break
+			}
+		}
+	}
+	return symbols
+}
+
+// ToolFor returns the snippet_search tool of a user, or nil without a library.
+func (l *Library) ToolFor(userID int32) agents.ToolWithSchema {
+	if l == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		ToolName,
+		"Search the user's code snippet library (memos tagged #snippet) before writing new code, "+
+			"by defined symbol (function, class or type name), by regular expression over the code, or by language. "+
+			`Input: {"symbol": "...", "pattern": "...", "language": "go", "limit": 5}.`,
+		func(ctx context.Context, input string) (string, error) {
+			var args struct {
+				Symbol   string `json:"symbol"`
+				Pattern  string `json:"pattern"`
+				Language string `json:"language"`
+				Limit    int    `json:"limit"`
+			}
+			if err := json.Unmarshal([]byte(input), &args); err != nil {
+				return "", fmt.Errorf("invalid input: %w", err)
+			}
+			if args.Limit <= 0 {
+				args.Limit = 5
+			}
+			snippets, err := l.Search(ctx, &Query{
+				CreatorID: userID,
+				Symbol:    args.Symbol,
+				Pattern:   args.Pattern,
+				Language:  args.Language,
+				Limit:     args.Limit,
+			})
+			if err != nil {
+				return "", err
+			}
+			return FormatSnippets(snippets), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"symbol":   map[string]any{"type": "string", "description": "Part of a function, class or type name"},
+				"pattern":  map[string]any{"type": "string", "description": "Regular expression matched against the code"},
+				"language": map[string]any{"type": "string", "description": "Language of the snippets, e.g. go or python"},
+				"limit":    map[string]any{"type": "integer", "description": fmt.Sprintf("Number of snippets (at most %d)", maxSearchLimit)},
+			},
+		},
+	)
+}
+
+// FormatSnippets renders snippets for agents.
+func FormatSnippets(snippets []*Snippet) string {
+	if len(snippets) == 0 {
+		return "No matching snippet in the user's library."
+	}
+	var sb strings.Builder
+	for i, snippet := range snippets {
+		fmt.Fprintf(&sb, "[%d] %s snippet (memo UID: %s)", i+1, languageLabel(snippet.Language), snippet.MemoUID)
+		if len(snippet.Symbols) > 0 {
+			fmt.Fprintf(&sb, " defines %s", strings.Join(snippet.Symbols, ", "))
+		}
+		sb.WriteString("\n")
+		for _, match := range snippet.Matches {
+			fmt.Fprintf(&sb, "   line %d: %s\n", match.Line, strings.TrimSpace(match.Text))
+		}
+		lines := strings.Split(snippet.Code, "\n")
+		fmt.Fprintf(&sb, "```%s\n%s\n", snippet.Language, strings.Join(lines[:min(len(lines), maxToolLines)], "\n"))
+		if len(lines) > maxToolLines {
+			fmt.Fprintf(&sb, "… (%d more lines)\n", len(lines)-maxToolLines)
+		}
+		sb.WriteString("```\n\n")
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+func languageLabel(language string) string {
+	if language == "" {
+		return "Untyped"
+	}
+	return language
+}
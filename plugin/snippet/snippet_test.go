@@ -0,0 +1,144 @@
+package snippet
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBlocks(t *testing.T) {
+	content := "Retry helper #snippet\n\n" +
+		"```golang\nfunc Retry(n int) error {\n\treturn nil\n}\n```\n\n" +
+		"Usage:\n\n" +
+		"~~~\nRetry(3)\n~~~\n\n" +
+		"```py\n```\n\n" +
+		"    ```go\n    indented code is not a fence\n" +
+		"```js title=\"x.js\"\nexport const debounce = (fn) => fn\n````\n"
+
+	blocks := ParseBlocks(content, "go")
+	require.Len(t, blocks, 3)
+	assert.Equal(t, Block{Index: 0, Language: "go", Code: "func Retry(n int) error {\n\treturn nil\n}", Symbols: []string{"Retry"}}, blocks[0])
+	assert.Equal(t, "go", blocks[1].Language, "blocks without language use the default")
+	assert.Equal(t, "Retry(3)", blocks[1].Code)
+	assert.Equal(t, 2, blocks[2].Index, "empty blocks are dropped")
+	assert.Equal(t, "javascript", blocks[2].Language)
+	assert.Equal(t, []string{"debounce"}, blocks[2].Symbols)
+
+	assert.Empty(t, ParseBlocks("```go\nunterminated", ""))
+}
+
+func TestExtractSymbols(t *testing.T) {
+	code := `package main
+
+func (s *Server) Start() {}
+type Config struct{}
+export async function fetchAll() {}
+class Parser:
+    def parse(self):
+        pass
+pub fn tokenize() {}
+const add = async (a, b) => a + b
+create table if not exists "users" (id int);
+func Start() {}
+`
+	assert.Equal(t, []string{"Start", "Config", "fetchAll", "Parser", "parse", "tokenize", "add", "users"}, ExtractSymbols(code))
+	assert.Empty(t, ExtractSymbols("x := 1"))
+}
+
+func TestSnippetTag(t *testing.T) {
+	ok, language := SnippetTag([]string{"work", "Snippet/Py"})
+	assert.True(t, ok)
+	assert.Equal(t, "python", language)
+
+	ok, language = SnippetTag([]string{"snippet"})
+	assert.True(t, ok)
+	assert.Empty(t, language)
+
+	ok, _ = SnippetTag([]string{"snippets", "code"})
+	assert.False(t, ok)
+}
+
+type fakeStore struct {
+	blocks   map[int32][]Block
+	snippets []*Snippet
+	query    *Query
+}
+
+func (s *fakeStore) Replace(_ context.Context, memoID, _ int32, blocks []Block) error {
+	s.blocks[memoID] = blocks
+	return nil
+}
+
+func (s *fakeStore) Search(_ context.Context, query *Query) ([]*Snippet, error) {
+	s.query = query
+	return s.snippets, nil
+}
+
+func TestIndexMemo(t *testing.T) {
+	store := &fakeStore{blocks: map[int32][]Block{}}
+	library := NewLibrary(store)
+	content := "```\nSELECT 1;\n```"
+
+	require.NoError(t, library.IndexMemo(context.Background(), 1, 1, content, []string{"snippet/sql"}))
+	require.Len(t, store.blocks[1], 1)
+	assert.Equal(t, "sql", store.blocks[1][0].Language)
+
+	require.NoError(t, library.IndexMemo(context.Background(), 1, 1, content, nil))
+	assert.Empty(t, store.blocks[1], "untagged memos leave the library")
+
+	var nilLibrary *Library
+	assert.NoError(t, nilLibrary.IndexMemo(context.Background(), 1, 1, content, []string{"snippet"}))
+}
+
+func TestSearch(t *testing.T) {
+	store := &fakeStore{snippets: []*Snippet{{
+		MemoUID:  "m1",
+		Language: "go",
+		Symbols:  []string{"Retry"},
+		Code:     "func Retry(n int) error {\n\tfor i := 0; i < n; i++ {\n\t}\n\treturn nil\n}",
+	}}}
+	library := NewLibrary(store)
+
+	snippets, err := library.Search(context.Background(), &Query{CreatorID: 1, Pattern: `for .* < n`, Language: "Golang", Limit: 500})
+	require.NoError(t, err)
+	assert.Equal(t, "go", store.query.Language)
+	assert.Equal(t, maxSearchLimit, store.query.Limit)
+	assert.Equal(t, []Match{{Line: 2, Text: "\tfor i := 0; i < n; i++ {"}}, snippets[0].Matches)
+
+	text := FormatSnippets(snippets)
+	assert.Contains(t, text, "[1] go snippet (memo UID: m1) defines Retry\n   line 2: for i := 0; i < n; i++ {\n```go\nfunc Retry")
+
+	_, err = library.Search(context.Background(), &Query{CreatorID: 1, Pattern: "("})
+	assert.True(t, errors.Is(err, ErrInvalidPattern))
+	assert.Equal(t, "No matching snippet in the user's library.", FormatSnippets(nil))
+}
+
+func TestExport(t *testing.T) {
+	store := &fakeStore{snippets: []*Snippet{
+		{MemoUID: "m1", BlockIndex: 0, Language: "python", Symbols: []string{"parse"}, Code: "def parse(): pass"},
+		{MemoUID: "m1", BlockIndex: 1, Code: "echo hi"},
+	}}
+	dir := filepath.Join(t.TempDir(), ".snippets")
+	require.NoError(t, os.MkdirAll(dir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stale.go"), nil, 0o600))
+
+	n, err := NewLibrary(store).Export(context.Background(), 1, dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	code, err := os.ReadFile(filepath.Join(dir, "m1-0.py"))
+	require.NoError(t, err)
+	assert.Equal(t, "def parse(): pass\n", string(code))
+	assert.FileExists(t, filepath.Join(dir, "m1-1.txt"))
+	assert.NoFileExists(t, filepath.Join(dir, "stale.go"))
+
+	index, err := os.ReadFile(filepath.Join(dir, IndexFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "| m1-0.py | python | parse | m1 |")
+	assert.Equal(t, maxExport, store.query.Limit)
+}
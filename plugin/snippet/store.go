@@ -0,0 +1,97 @@
+package snippet
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// DBStore persists snippets in the code_snippet table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new snippet store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// Replace implements Store.
+func (s *DBStore) Replace(ctx context.Context, memoID, creatorID int32, blocks []Block) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM code_snippet WHERE memo_id = $1", memoID); err != nil {
+		return fmt.Errorf("failed to delete snippets: %w", err)
+	}
+	now := time.Now().Unix()
+	for _, block := range blocks {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO code_snippet (memo_id, block_index, creator_id, language, code, symbols, updated_ts)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			memoID, block.Index, creatorID, block.Language, block.Code, pq.Array(symbolsOrEmpty(block.Symbols)), now); err != nil {
+			return fmt.Errorf("failed to insert snippet: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Search implements Store. Snippets of archived memos are left out.
+func (s *DBStore) Search(ctx context.Context, query *Query) ([]*Snippet, error) {
+	where, args := []string{"c.creator_id = $1", "m.row_status = 'NORMAL'"}, []any{query.CreatorID}
+	if query.Symbol != "" {
+		args = append(args, "%"+escapeLike(query.Symbol)+"%")
+		where = append(where, fmt.Sprintf("EXISTS (SELECT 1 FROM unnest(c.symbols) AS symbol WHERE symbol ILIKE $%d)", len(args)))
+	}
+	if query.Pattern != "" {
+		args = append(args, query.Pattern)
+		where = append(where, fmt.Sprintf("c.code ~ $%d", len(args)))
+	}
+	if query.Language != "" {
+		args = append(args, query.Language)
+		where = append(where, fmt.Sprintf("c.language = $%d", len(args)))
+	}
+	args = append(args, query.Limit)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT c.memo_id, m.uid, c.block_index, c.language, c.symbols, c.code, c.updated_ts
+		FROM code_snippet c
+		JOIN memo m ON m.id = c.memo_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY c.updated_ts DESC, c.memo_id DESC, c.block_index
+		LIMIT $`+fmt.Sprint(len(args)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search snippets: %w", err)
+	}
+	defer rows.Close()
+
+	var snippets []*Snippet
+	for rows.Next() {
+		snippet := &Snippet{}
+		if err := rows.Scan(&snippet.MemoID, &snippet.MemoUID, &snippet.BlockIndex, &snippet.Language,
+			pq.Array(&snippet.Symbols), &snippet.Code, &snippet.UpdatedTs); err != nil {
+			return nil, fmt.Errorf("failed to scan snippet: %w", err)
+		}
+		snippets = append(snippets, snippet)
+	}
+	return snippets, rows.Err()
+}
+
+func symbolsOrEmpty(symbols []string) []string {
+	if symbols == nil {
+		return []string{}
+	}
+	return symbols
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,138 @@
+package statuspage
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/hrygo/divinesense/plugin/i18n"
+)
+
+// dateTimeLayout is the layout of the times on the page.
+const dateTimeLayout = "2006-01-02 15:04 MST"
+
+// pageTemplate is the HTML status page; it refreshes every minute and needs
+// no script or external resource.
+var pageTemplate = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta http-equiv="refresh" content="60">
+<title>{{.Title}}</title>
+<style>
+body{font-family:system-ui,-apple-system,sans-serif;max-width:720px;margin:0 auto;padding:24px 16px;color:#1f2328;background:#f6f8fa}
+h1{font-size:1.5rem;margin:0 0 16px}h2{font-size:1.1rem;margin:24px 0 8px}
+.banner{padding:16px;border-radius:8px;color:#fff;font-weight:600;font-size:1.1rem}
+.card{background:#fff;border:1px solid #d0d7de;border-radius:8px;padding:12px 16px;margin-bottom:8px}
+.row{display:flex;justify-content:space-between;align-items:center}
+.muted{color:#656d76;font-size:.875rem}.message{white-space:pre-wrap;margin:8px 0}
+.operational{background:#1a7f37}.degraded{background:#bf8700}.outage{background:#cf222e}
+.dot{display:inline-block;width:10px;height:10px;border-radius:50%;margin-right:6px}
+.text-operational{color:#1a7f37}.text-degraded{color:#9a6700}.text-outage{color:#cf222e}
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="banner {{.Status}}">{{.Overall}}</div>
+<p class="muted">{{.Uptime}}</p>
+<h2>{{.ComponentsTitle}}</h2>
+{{range .Components}}<div class="card row"><span>{{.Name}}</span><span class="text-{{.Status}}"><span class="dot {{.Status}}"></span>{{.Label}}</span></div>
+{{end}}<h2>{{.IncidentsTitle}}</h2>
+{{range .Incidents}}<div class="card">
+<div class="row"><strong>{{.Title}}</strong><span class="text-{{.Status}}">{{.Stage}}</span></div>
+<div class="muted">{{.Impact}} · {{.Updated}}{{if .Affects}} · {{.Affects}}{{end}}</div>
+{{if .Message}}<div class="message">{{.Message}}</div>{{end}}
+</div>
+{{else}}<p class="muted">{{.NoIncidents}}</p>
+{{end}}<p class="muted">{{.Checked}}</p>
+</body>
+</html>
+`))
+
+type pageData struct {
+	Lang, Title, Status, Overall, Uptime, Checked string
+	ComponentsTitle, IncidentsTitle, NoIncidents  string
+	Components                                    []pageComponent
+	Incidents                                     []pageIncident
+}
+
+type pageComponent struct {
+	Name, Status, Label string
+}
+
+type pageIncident struct {
+	Title, Message, Status, Stage, Impact, Updated, Affects string
+}
+
+// RenderHTML renders a status as the HTML page of an instance named name,
+// in a locale, with times in loc.
+func RenderHTML(status *Status, name string, locale i18n.Locale, loc *time.Location) ([]byte, error) {
+	data := pageData{
+		Lang:            string(locale),
+		Title:           i18n.T(locale, "statuspage.title", name),
+		Status:          status.Status,
+		Overall:         i18n.T(locale, "statuspage.overall."+status.Status),
+		Uptime:          i18n.T(locale, "statuspage.uptime", formatUptime(status.UptimeSeconds, locale), formatTime(status.StartedTs, loc)),
+		Checked:         i18n.T(locale, "statuspage.checked", formatTime(status.CheckedTs, loc)),
+		ComponentsTitle: i18n.T(locale, "statuspage.components"),
+		IncidentsTitle:  i18n.T(locale, "statuspage.incidents"),
+		NoIncidents:     i18n.T(locale, "statuspage.no_incidents"),
+	}
+	for _, c := range status.Components {
+		data.Components = append(data.Components, pageComponent{
+			Name:   componentName(c.Name, locale),
+			Status: c.Status,
+			Label:  i18n.T(locale, "statuspage.status."+c.Status),
+		})
+	}
+	for _, incident := range status.Incidents {
+		item := pageIncident{
+			Title:   incident.Title,
+			Message: incident.Message,
+			Status:  incident.status(),
+			Stage:   i18n.T(locale, "statuspage.stage."+incident.Stage),
+			Impact:  i18n.T(locale, "statuspage.impact."+incident.Impact),
+			Updated: i18n.T(locale, "statuspage.updated", formatTime(incident.UpdatedTs, loc)),
+		}
+		if !incident.Active() {
+			item.Status = StatusOperational
+		}
+		if len(incident.Components) > 0 {
+			names := make([]string, len(incident.Components))
+			for i, name := range incident.Components {
+				names[i] = componentName(name, locale)
+			}
+			item.Affects = i18n.T(locale, "statuspage.affects", strings.Join(names, ", "))
+		}
+		data.Incidents = append(data.Incidents, item)
+	}
+	var b bytes.Buffer
+	if err := pageTemplate.Execute(&b, data); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// componentName returns the localized name of a component, or its name.
+func componentName(name string, locale i18n.Locale) string {
+	key := "statuspage.component." + name
+	if localized := i18n.T(locale, key); localized != key {
+		return localized
+	}
+	return name
+}
+
+// formatUptime formats an uptime in days and hours, or hours and minutes.
+func formatUptime(seconds int64, locale i18n.Locale) string {
+	hours, minutes := seconds/3600, seconds%3600/60
+	if hours >= 24 {
+		return i18n.T(locale, "statuspage.duration.days", hours/24, hours%24)
+	}
+	return i18n.T(locale, "statuspage.duration.hours", hours, minutes)
+}
+
+func formatTime(ts int64, loc *time.Location) string {
+	return time.Unix(ts, 0).In(loc).Format(dateTimeLayout)
+}
@@ -0,0 +1,371 @@
+// Package statuspage serves the public status of the instance: its uptime,
+// the health of its components and the incidents admins post during outages,
+// so that self-hosters can point their family or team to a status URL.
+//
+// Components are probed by checks registered at startup; their results are
+// cached briefly, so that the public page cannot be used to load the
+// instance. Incidents are written by admins and stay on the page until a
+// week after they are resolved.
+package statuspage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	// ErrNotFound is returned for incidents that do not exist.
+	ErrNotFound = errors.New("incident not found")
+	// ErrInvalid is returned for invalid incidents.
+	ErrInvalid = errors.New("invalid incident")
+	// ErrDegraded is wrapped by the errors of checks whose component works,
+	// though poorly; other errors mean the component is down.
+	ErrDegraded = errors.New("degraded")
+)
+
+// Statuses of components and of the instance, from the best to the worst.
+const (
+	StatusOperational = "operational"
+	StatusDegraded    = "degraded"
+	StatusOutage      = "outage"
+)
+
+// Impacts of incidents.
+const (
+	ImpactMinor    = "minor"
+	ImpactMajor    = "major"
+	ImpactCritical = "critical"
+)
+
+// Stages of incidents.
+const (
+	StageInvestigating = "investigating"
+	StageIdentified    = "identified"
+	StageMonitoring    = "monitoring"
+	StageResolved      = "resolved"
+)
+
+const (
+	// checkTimeout bounds each check.
+	checkTimeout = 5 * time.Second
+	// cacheTTL is how long the results of the checks are reused.
+	cacheTTL = 30 * time.Second
+	// slowCheck is the latency past which a component is degraded.
+	slowCheck = 2 * time.Second
+	// resolvedWindow is how long resolved incidents stay on the page.
+	resolvedWindow = 7 * 24 * time.Hour
+	// maxIncidents bounds the incidents on the page.
+	maxIncidents = 20
+	// maxTitleRunes and maxMessageRunes bound the texts of incidents.
+	maxTitleRunes   = 200
+	maxMessageRunes = 5000
+)
+
+var (
+	impacts = []string{ImpactMinor, ImpactMajor, ImpactCritical}
+	stages  = []string{StageInvestigating, StageIdentified, StageMonitoring, StageResolved}
+	ranks   = map[string]int{StatusOperational: 0, StatusDegraded: 1, StatusOutage: 2}
+)
+
+// Check probes a component; it returns an error wrapping ErrDegraded when
+// the component works poorly, and any other error when it is down.
+type Check func(ctx context.Context) error
+
+// Component is the health of a component.
+type Component struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	// Detail is the error of the check, for admins only.
+	Detail string `json:"detail,omitempty"`
+}
+
+// Incident is an outage or a degradation posted by an admin.
+type Incident struct {
+	ID      int32  `json:"id"`
+	Title   string `json:"title"`
+	Message string `json:"message"`
+	Impact  string `json:"impact"`
+	Stage   string `json:"stage"`
+	// Components are the names of the affected components, which are shown
+	// degraded or down while the incident is not resolved.
+	Components []string `json:"components"`
+	CreatedTs  int64    `json:"created_ts"`
+	UpdatedTs  int64    `json:"updated_ts"`
+	ResolvedTs int64    `json:"resolved_ts,omitempty"`
+}
+
+// Active reports whether an incident is not resolved.
+func (i *Incident) Active() bool {
+	return i.Stage != StageResolved
+}
+
+// status is the status of the components affected by an incident.
+func (i *Incident) status() string {
+	if i.Impact == ImpactMinor {
+		return StatusDegraded
+	}
+	return StatusOutage
+}
+
+// Normalize trims the texts of an incident and sets its defaults.
+func (i *Incident) Normalize() {
+	i.Title = strings.TrimSpace(i.Title)
+	i.Message = strings.TrimSpace(i.Message)
+	if i.Impact == "" {
+		i.Impact = ImpactMajor
+	}
+	if i.Stage == "" {
+		i.Stage = StageInvestigating
+	}
+	components := make([]string, 0, len(i.Components))
+	for _, name := range i.Components {
+		if name = strings.TrimSpace(name); name != "" && !slices.Contains(components, name) {
+			components = append(components, name)
+		}
+	}
+	i.Components = components
+}
+
+// Validate checks a normalized incident.
+func (i *Incident) Validate() error {
+	switch {
+	case i.Title == "":
+		return fmt.Errorf("%w: title is required", ErrInvalid)
+	case utf8.RuneCountInString(i.Title) > maxTitleRunes:
+		return fmt.Errorf("%w: title is longer than %d characters", ErrInvalid, maxTitleRunes)
+	case utf8.RuneCountInString(i.Message) > maxMessageRunes:
+		return fmt.Errorf("%w: message is longer than %d characters", ErrInvalid, maxMessageRunes)
+	case !slices.Contains(impacts, i.Impact):
+		return fmt.Errorf("%w: impact must be one of %s", ErrInvalid, strings.Join(impacts, ", "))
+	case !slices.Contains(stages, i.Stage):
+		return fmt.Errorf("%w: stage must be one of %s", ErrInvalid, strings.Join(stages, ", "))
+	}
+	return nil
+}
+
+// Status is the status of the instance.
+type Status struct {
+	// Status is the worst status of the components.
+	Status        string       `json:"status"`
+	Version       string       `json:"version,omitempty"`
+	StartedTs     int64        `json:"started_ts"`
+	UptimeSeconds int64        `json:"uptime_seconds"`
+	Components    []*Component `json:"components"`
+	// Incidents are the active incidents, then the ones resolved during the
+	// last week, the latest first.
+	Incidents []*Incident `json:"incidents"`
+	CheckedTs int64       `json:"checked_ts"`
+}
+
+// Public returns a copy of a status without the details of its components.
+func (s *Status) Public() *Status {
+	public := *s
+	public.Components = make([]*Component, len(s.Components))
+	for i, component := range s.Components {
+		c := *component
+		c.Detail = ""
+		public.Components[i] = &c
+	}
+	return &public
+}
+
+// FindIncident filters incidents.
+type FindIncident struct {
+	ID *int32
+	// Since selects the incidents active or resolved since it, when set.
+	Since int64
+	Limit int
+}
+
+// Store persists incidents.
+type Store interface {
+	// ListIncidents returns incidents, the active ones then the latest.
+	ListIncidents(ctx context.Context, find *FindIncident) ([]*Incident, error)
+	CreateIncident(ctx context.Context, incident *Incident) (*Incident, error)
+	// UpdateIncident returns ErrNotFound for missing incidents.
+	UpdateIncident(ctx context.Context, incident *Incident) (*Incident, error)
+	// DeleteIncident returns ErrNotFound for missing incidents.
+	DeleteIncident(ctx context.Context, id int32) error
+}
+
+// Config configures the status page.
+type Config struct {
+	// Version is the version of the instance, shown on the page.
+	Version string
+}
+
+// component is a registered check.
+type component struct {
+	name  string
+	check Check
+}
+
+// Page is the status page of the instance.
+type Page struct {
+	store   Store
+	config  Config
+	started time.Time
+	now     func() time.Time
+
+	mu         sync.Mutex
+	components []component
+	cached     []*Component
+	cachedAt   time.Time
+}
+
+// New creates the status page of an instance started now.
+func New(store Store, config Config) *Page {
+	return &Page{store: store, config: config, started: time.Now(), now: time.Now}
+}
+
+// Register adds the check of a component, e.g. database; the components are
+// shown in the order they are registered.
+func (p *Page) Register(name string, check Check) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.components = append(p.components, component{name: name, check: check})
+	p.cached = nil
+}
+
+// Status returns the status of the instance, with the details of its
+// components; use Public before serving it to visitors.
+func (p *Page) Status(ctx context.Context) (*Status, error) {
+	now := p.now()
+	incidents, err := p.store.ListIncidents(ctx, &FindIncident{Since: now.Add(-resolvedWindow).Unix(), Limit: maxIncidents})
+	if err != nil {
+		return nil, err
+	}
+	status := &Status{
+		Status:        StatusOperational,
+		Version:       p.config.Version,
+		StartedTs:     p.started.Unix(),
+		UptimeSeconds: int64(now.Sub(p.started).Seconds()),
+		Incidents:     incidents,
+		CheckedTs:     now.Unix(),
+	}
+	for _, checked := range p.check(ctx, now) {
+		c := *checked
+		for _, incident := range incidents {
+			if incident.Active() && slices.Contains(incident.Components, c.Name) && ranks[incident.status()] > ranks[c.Status] {
+				c.Status = incident.status()
+			}
+		}
+		status.Components = append(status.Components, &c)
+		status.Status = worst(status.Status, c.Status)
+	}
+	for _, incident := range incidents {
+		if incident.Active() {
+			status.Status = worst(status.Status, incident.status())
+		}
+	}
+	return status, nil
+}
+
+// check runs the checks of the components, or returns their cached results.
+func (p *Page) check(ctx context.Context, now time.Time) []*Component {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cached != nil && now.Sub(p.cachedAt) < cacheTTL {
+		return p.cached
+	}
+	results := make([]*Component, len(p.components))
+	var wg sync.WaitGroup
+	for i, c := range p.components {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = probe(ctx, c)
+		}()
+	}
+	wg.Wait()
+	p.cached, p.cachedAt = results, now
+	return results
+}
+
+// probe runs the check of a component.
+func probe(ctx context.Context, c component) *Component {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	started := time.Now()
+	err := c.check(ctx)
+	latency := time.Since(started)
+	result := &Component{Name: c.name, Status: StatusOperational, LatencyMs: latency.Milliseconds()}
+	switch {
+	case errors.Is(err, ErrDegraded):
+		result.Status, result.Detail = StatusDegraded, err.Error()
+	case err != nil:
+		result.Status, result.Detail = StatusOutage, err.Error()
+	case latency > slowCheck:
+		result.Status, result.Detail = StatusDegraded, fmt.Sprintf("slow: %s", latency.Round(time.Millisecond))
+	}
+	return result
+}
+
+// worst returns the worst of two statuses.
+func worst(a, b string) string {
+	if ranks[b] > ranks[a] {
+		return b
+	}
+	return a
+}
+
+// Incidents returns the latest incidents, the active ones first.
+func (p *Page) Incidents(ctx context.Context, limit int) ([]*Incident, error) {
+	return p.store.ListIncidents(ctx, &FindIncident{Limit: limit})
+}
+
+// CreateIncident posts an incident.
+func (p *Page) CreateIncident(ctx context.Context, incident *Incident) (*Incident, error) {
+	incident.Normalize()
+	if err := incident.Validate(); err != nil {
+		return nil, err
+	}
+	now := p.now().Unix()
+	incident.CreatedTs, incident.UpdatedTs, incident.ResolvedTs = now, now, 0
+	if !incident.Active() {
+		incident.ResolvedTs = now
+	}
+	return p.store.CreateIncident(ctx, incident)
+}
+
+// Incident returns an incident.
+func (p *Page) Incident(ctx context.Context, id int32) (*Incident, error) {
+	incidents, err := p.store.ListIncidents(ctx, &FindIncident{ID: &id, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(incidents) == 0 {
+		return nil, ErrNotFound
+	}
+	return incidents[0], nil
+}
+
+// UpdateIncident updates an incident, e.g. to a later stage; resolving it
+// sets its resolution time, and reopening it clears it.
+func (p *Page) UpdateIncident(ctx context.Context, incident *Incident) (*Incident, error) {
+	incident.Normalize()
+	if err := incident.Validate(); err != nil {
+		return nil, err
+	}
+	now := p.now().Unix()
+	incident.UpdatedTs = now
+	switch {
+	case incident.Active():
+		incident.ResolvedTs = 0
+	case incident.ResolvedTs == 0:
+		incident.ResolvedTs = now
+	}
+	return p.store.UpdateIncident(ctx, incident)
+}
+
+// DeleteIncident deletes an incident, e.g. one posted by mistake.
+func (p *Page) DeleteIncident(ctx context.Context, id int32) error {
+	return p.store.DeleteIncident(ctx, id)
+}
@@ -0,0 +1,249 @@
+package statuspage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/plugin/i18n"
+)
+
+type fakeStore struct {
+	incidents []*Incident
+	nextID    int32
+}
+
+func (s *fakeStore) ListIncidents(_ context.Context, find *FindIncident) ([]*Incident, error) {
+	var incidents []*Incident
+	for _, incident := range s.incidents {
+		if find.ID != nil && incident.ID != *find.ID {
+			continue
+		}
+		if find.Since != 0 && !incident.Active() && incident.ResolvedTs < find.Since {
+			continue
+		}
+		copied := *incident
+		incidents = append(incidents, &copied)
+	}
+	slices.SortStableFunc(incidents, func(a, b *Incident) int {
+		if a.Active() != b.Active() {
+			if a.Active() {
+				return -1
+			}
+			return 1
+		}
+		return int(b.CreatedTs - a.CreatedTs)
+	})
+	if find.Limit > 0 && len(incidents) > find.Limit {
+		incidents = incidents[:find.Limit]
+	}
+	return incidents, nil
+}
+
+func (s *fakeStore) CreateIncident(_ context.Context, incident *Incident) (*Incident, error) {
+	s.nextID++
+	created := *incident
+	created.ID = s.nextID
+	s.incidents = append(s.incidents, &created)
+	return &created, nil
+}
+
+func (s *fakeStore) UpdateIncident(_ context.Context, incident *Incident) (*Incident, error) {
+	for i, existing := range s.incidents {
+		if existing.ID == incident.ID {
+			updated := *incident
+			updated.CreatedTs = existing.CreatedTs
+			s.incidents[i] = &updated
+			return &updated, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *fakeStore) DeleteIncident(_ context.Context, id int32) error {
+	for i, existing := range s.incidents {
+		if existing.ID == id {
+			s.incidents = slices.Delete(s.incidents, i, i+1)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func newTestPage(store Store) (*Page, *time.Time) {
+	page := New(store, Config{Version: "1.1.0"})
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	page.started = now.Add(-50 * time.Hour)
+	page.now = func() time.Time { return now }
+	return page, &now
+}
+
+func TestPage_Status(t *testing.T) {
+	ctx := context.Background()
+	page, _ := newTestPage(&fakeStore{})
+	page.Register("database", func(context.Context) error { return nil })
+	page.Register("ai", func(context.Context) error { return fmt.Errorf("%w: safe mode", ErrDegraded) })
+
+	status, err := page.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, StatusDegraded, status.Status)
+	assert.Equal(t, "1.1.0", status.Version)
+	assert.Equal(t, int64(50*3600), status.UptimeSeconds)
+	require.Len(t, status.Components, 2)
+	assert.Equal(t, "database", status.Components[0].Name)
+	assert.Equal(t, StatusOperational, status.Components[0].Status)
+	assert.Equal(t, StatusDegraded, status.Components[1].Status)
+	assert.Equal(t, "degraded: safe mode", status.Components[1].Detail)
+	// Public strips the details from a copy
+	assert.Empty(t, status.Public().Components[1].Detail)
+	assert.Equal(t, "degraded: safe mode", status.Components[1].Detail)
+
+	page.Register("jobs", func(context.Context) error { return errors.New("scheduler stopped") })
+	status, err = page.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, StatusOutage, status.Status)
+	assert.Equal(t, StatusOutage, status.Components[2].Status)
+}
+
+func TestPage_StatusCachesChecks(t *testing.T) {
+	ctx := context.Background()
+	page, now := newTestPage(&fakeStore{})
+	var calls atomic.Int32
+	page.Register("database", func(context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+
+	for range 3 {
+		_, err := page.Status(ctx)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+
+	*now = now.Add(cacheTTL)
+	_, err := page.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestPage_StatusWithIncidents(t *testing.T) {
+	ctx := context.Background()
+	page, now := newTestPage(&fakeStore{})
+	page.Register("database", func(context.Context) error { return nil })
+	page.Register("ai", func(context.Context) error { return nil })
+
+	incident, err := page.CreateIncident(ctx, &Incident{Title: " Slow replies ", Impact: ImpactMinor, Components: []string{"ai", "ai", " "}})
+	require.NoError(t, err)
+	assert.Equal(t, "Slow replies", incident.Title)
+	assert.Equal(t, StageInvestigating, incident.Stage)
+	assert.Equal(t, []string{"ai"}, incident.Components)
+
+	status, err := page.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, StatusDegraded, status.Status)
+	assert.Equal(t, StatusOperational, status.Components[0].Status)
+	assert.Equal(t, StatusDegraded, status.Components[1].Status)
+	require.Len(t, status.Incidents, 1)
+
+	// Resolved incidents stay on the page for a week without affecting it
+	incident.Stage = StageResolved
+	_, err = page.UpdateIncident(ctx, incident)
+	require.NoError(t, err)
+	status, err = page.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, StatusOperational, status.Status)
+	assert.Equal(t, StatusOperational, status.Components[1].Status)
+	require.Len(t, status.Incidents, 1)
+
+	*now = now.Add(resolvedWindow + time.Hour)
+	status, err = page.Status(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, status.Incidents)
+}
+
+func TestPage_UpdateIncident(t *testing.T) {
+	ctx := context.Background()
+	page, now := newTestPage(&fakeStore{})
+
+	incident, err := page.CreateIncident(ctx, &Incident{Title: "Database down", Impact: ImpactCritical})
+	require.NoError(t, err)
+	created := incident.CreatedTs
+	assert.Zero(t, incident.ResolvedTs)
+
+	*now = now.Add(time.Hour)
+	incident.Stage = StageResolved
+	incident, err = page.UpdateIncident(ctx, incident)
+	require.NoError(t, err)
+	assert.Equal(t, created, incident.CreatedTs)
+	assert.Equal(t, now.Unix(), incident.UpdatedTs)
+	assert.Equal(t, now.Unix(), incident.ResolvedTs)
+
+	// Reopening clears the resolution
+	incident.Stage = StageMonitoring
+	incident, err = page.UpdateIncident(ctx, incident)
+	require.NoError(t, err)
+	assert.Zero(t, incident.ResolvedTs)
+
+	got, err := page.Incident(ctx, incident.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StageMonitoring, got.Stage)
+
+	require.NoError(t, page.DeleteIncident(ctx, incident.ID))
+	_, err = page.Incident(ctx, incident.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = page.UpdateIncident(ctx, incident)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestIncident_Validate(t *testing.T) {
+	page, _ := newTestPage(&fakeStore{})
+	for _, incident := range []*Incident{
+		{Title: "  "},
+		{Title: "Outage", Impact: "huge"},
+		{Title: "Outage", Stage: "fixed"},
+	} {
+		_, err := page.CreateIncident(context.Background(), incident)
+		assert.ErrorIs(t, err, ErrInvalid)
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	status := &Status{
+		Status:        StatusOutage,
+		StartedTs:     time.Date(2024, 4, 29, 10, 0, 0, 0, time.UTC).Unix(),
+		UptimeSeconds: 50 * 3600,
+		Components: []*Component{
+			{Name: "database", Status: StatusOutage, Detail: "connection refused"},
+			{Name: "search", Status: StatusOperational},
+		},
+		Incidents: []*Incident{{
+			Title: "Database <down>", Message: "We are on it.", Impact: ImpactCritical, Stage: StageIdentified,
+			Components: []string{"database"}, UpdatedTs: time.Date(2024, 5, 1, 11, 30, 0, 0, time.UTC).Unix(),
+		}},
+		CheckedTs: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	page, err := RenderHTML(status, "DivineSense", i18n.LocaleEnglish, time.UTC)
+	require.NoError(t, err)
+	html := string(page)
+	assert.Contains(t, html, "<title>DivineSense status</title>")
+	assert.Contains(t, html, "Service outage")
+	assert.Contains(t, html, "Up for 2d 2h since 2024-04-29 10:00 UTC")
+	assert.Contains(t, html, "Database")
+	assert.Contains(t, html, "search")
+	assert.Contains(t, html, "Database &lt;down&gt;")
+	assert.Contains(t, html, "Critical · Updated 2024-05-01 11:30 UTC · Affects: Database")
+	assert.NotContains(t, html, "connection refused")
+
+	page, err = RenderHTML(&Status{Status: StatusOperational}, "DivineSense", i18n.LocaleSimplifiedChinese, time.UTC)
+	require.NoError(t, err)
+	assert.Contains(t, string(page), `lang="zh-Hans"`)
+	assert.Contains(t, string(page), i18n.T(i18n.LocaleSimplifiedChinese, "statuspage.no_incidents"))
+}
@@ -0,0 +1,120 @@
+package statuspage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// DBStore persists incidents in the status_incident table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new incident store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const incidentColumns = "id, title, message, impact, stage, components, created_ts, updated_ts, resolved_ts"
+
+// ListIncidents implements Store.
+func (s *DBStore) ListIncidents(ctx context.Context, find *FindIncident) ([]*Incident, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.ID; v != nil {
+		args = append(args, *v)
+		where = append(where, fmt.Sprintf("id = $%d", len(args)))
+	}
+	if find.Since != 0 {
+		args = append(args, StageResolved, find.Since)
+		where = append(where, fmt.Sprintf("(stage <> $%d OR resolved_ts >= $%d)", len(args)-1, len(args)))
+	}
+	args = append(args, StageResolved)
+	query := "SELECT " + incidentColumns + " FROM status_incident WHERE " + strings.Join(where, " AND ") +
+		fmt.Sprintf(" ORDER BY stage = $%d, created_ts DESC, id DESC", len(args))
+	if find.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", find.Limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list status incidents: %w", err)
+	}
+	defer rows.Close()
+	incidents := []*Incident{}
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan status incident: %w", err)
+		}
+		incidents = append(incidents, incident)
+	}
+	return incidents, rows.Err()
+}
+
+// CreateIncident implements Store.
+func (s *DBStore) CreateIncident(ctx context.Context, incident *Incident) (*Incident, error) {
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO status_incident (title, message, impact, stage, components, created_ts, updated_ts, resolved_ts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+incidentColumns,
+		incident.Title, incident.Message, incident.Impact, incident.Stage, pq.Array(incident.Components),
+		incident.CreatedTs, incident.UpdatedTs, incident.ResolvedTs)
+	created, err := scanIncident(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create status incident: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateIncident implements Store.
+func (s *DBStore) UpdateIncident(ctx context.Context, incident *Incident) (*Incident, error) {
+	row := s.db.QueryRowContext(ctx, `
+		UPDATE status_incident SET title = $2, message = $3, impact = $4, stage = $5, components = $6,
+			updated_ts = $7, resolved_ts = $8
+		WHERE id = $1
+		RETURNING `+incidentColumns,
+		incident.ID, incident.Title, incident.Message, incident.Impact, incident.Stage, pq.Array(incident.Components),
+		incident.UpdatedTs, incident.ResolvedTs)
+	updated, err := scanIncident(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update status incident: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteIncident implements Store.
+func (s *DBStore) DeleteIncident(ctx context.Context, id int32) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM status_incident WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete status incident: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanIncident(row rowScanner) (*Incident, error) {
+	incident := &Incident{}
+	var components pq.StringArray
+	if err := row.Scan(&incident.ID, &incident.Title, &incident.Message, &incident.Impact, &incident.Stage,
+		&components, &incident.CreatedTs, &incident.UpdatedTs, &incident.ResolvedTs); err != nil {
+		return nil, err
+	}
+	incident.Components = []string(components)
+	if incident.Components == nil {
+		incident.Components = []string{}
+	}
+	return incident, nil
+}
@@ -0,0 +1,128 @@
+package textextract
+
+import (
+	"bytes"
+	"context"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// pageDivRe matches the page containers of Tika's XHTML output for PDFs.
+	pageDivRe = regexp.MustCompile(`(?i)<div[^>]*class="page"[^>]*>`)
+	// blockEndRe matches the end of block elements, rendered as line breaks.
+	blockEndRe = regexp.MustCompile(`(?i)</(p|div|h[1-6]|li|tr)>|<br\s*/?>`)
+	tagRe      = regexp.MustCompile(`<[^>]*>`)
+	blankRe    = regexp.MustCompile(`\n{3,}`)
+)
+
+// ExtractPages extracts the text of a document page by page (PDF pages as
+// reported by Tika). Documents without page information are returned as one page.
+func (c *Client) ExtractPages(ctx context.Context, data []byte, contentType string) ([]string, error) {
+	if !c.IsSupported(contentType) {
+		return nil, errors.Errorf("unsupported content type: %s", contentType)
+	}
+
+	var document string
+	var err error
+	if c.config.UseEmbedded && c.config.TikaJarPath != "" {
+		document, err = c.extractHTMLEmbedded(ctx, data)
+	} else {
+		document, err = c.extractHTMLFromServer(ctx, data, contentType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return SplitHTMLPages(document), nil
+}
+
+// extractHTMLFromServer asks the Tika server for XHTML output.
+func (c *Client) extractHTMLFromServer(ctx context.Context, data []byte, contentType string) (string, error) {
+	if c.config.TikaServerURL == "" {
+		return "", errors.New("no Tika server available")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.config.TikaServerURL+"/tika", bytes.NewReader(data))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "tika server request failed")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("tika server returned status %d: %s", resp.StatusCode, string(body))
+	}
+	return string(body), nil
+}
+
+// extractHTMLEmbedded runs tika-app.jar with XHTML output.
+func (c *Client) extractHTMLEmbedded(ctx context.Context, data []byte) (string, error) {
+	inputFile, err := os.CreateTemp("", "tika_input_*")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create temp input file")
+	}
+	defer func() {
+		inputFile.Close()
+		os.Remove(inputFile.Name())
+	}()
+	if _, err := inputFile.Write(data); err != nil {
+		return "", errors.Wrap(err, "failed to write input file")
+	}
+
+	cmd := exec.CommandContext(ctx, c.config.JavaPath, "-jar", c.config.TikaJarPath, "-h", inputFile.Name())
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "tika-app.jar failed: %s", stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+// SplitHTMLPages splits Tika XHTML output into the plain text of its pages.
+// Without page containers, the whole body is one page.
+func SplitHTMLPages(document string) []string {
+	if i := strings.Index(strings.ToLower(document), "<body"); i >= 0 {
+		document = document[i:]
+	}
+	parts := pageDivRe.Split(document, -1)
+	if len(parts) > 1 {
+		// Drop what precedes the first page (the body tag).
+		parts = parts[1:]
+	}
+
+	pages := make([]string, 0, len(parts))
+	for _, part := range parts {
+		pages = append(pages, htmlToText(part))
+	}
+	return pages
+}
+
+// htmlToText renders an XHTML fragment as plain text, one block per line.
+func htmlToText(fragment string) string {
+	text := blockEndRe.ReplaceAllString(fragment, "\n")
+	text = html.UnescapeString(tagRe.ReplaceAllString(text, ""))
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	text = blankRe.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,48 @@
+package textextract
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tikaPDFHTML = `<html xmlns="http://www.w3.org/1999/xhtml"><head><title>doc</title>
+<meta name="xmpTPg:NPages" content="2"/></head>
+<body><div class="page"><p>第一页 标题</p>
+<p>Tom &amp; Jerry</p>
+</div>
+<div class="page"><p/>
+<p>Second page<br/>next line</p></div>
+</body></html>`
+
+func TestSplitHTMLPages(t *testing.T) {
+	pages := SplitHTMLPages(tikaPDFHTML)
+	require.Len(t, pages, 2)
+	assert.Equal(t, "第一页 标题\n\nTom & Jerry", pages[0])
+	assert.Equal(t, "Second page\nnext line", pages[1])
+
+	// Without page containers the body is one page.
+	assert.Equal(t, []string{"plain text"}, SplitHTMLPages("<html><body><p>plain text</p></body></html>"))
+}
+
+func TestExtractPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/tika", r.URL.Path)
+		assert.Equal(t, "text/html", r.Header.Get("Accept"))
+		_, _ = w.Write([]byte(tikaPDFHTML))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{TikaServerURL: server.URL})
+	pages, err := client.ExtractPages(context.Background(), []byte("%PDF-1.7"), "application/pdf")
+	require.NoError(t, err)
+	assert.Len(t, pages, 2)
+
+	_, err = client.ExtractPages(context.Background(), []byte("x"), "image/png")
+	assert.Error(t, err)
+}
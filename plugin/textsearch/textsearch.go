@@ -0,0 +1,160 @@
+// Package textsearch picks the PostgreSQL text search configuration of a
+// query by its language.
+//
+// The parsers of PostgreSQL do not segment CJK text: a run of Han characters
+// is a single token, so a query only matches whole runs. Languages without a
+// configuration of their own, e.g. zh=jiebacfg from pg_jieba, are searched
+// with the default configuration over the bigrams of their CJK runs, which
+// the cjk_bigrams SQL function computes on the documents and Bigrams on the
+// query.
+// Queries with a lone CJK character match no bigram and are searched by
+// substring instead.
+package textsearch
+
+import (
+	"log/slog"
+	"regexp"
+	"unicode"
+)
+
+// Language is the language of a query, as far as search cares.
+type Language string
+
+const (
+	// LanguageDefault is any language without CJK characters.
+	LanguageDefault  Language = "default"
+	LanguageChinese  Language = "zh"
+	LanguageJapanese Language = "ja"
+	LanguageKorean   Language = "ko"
+)
+
+// DefaultConfig is the configuration of the default language unless
+// configured; it neither stems nor drops stop words, which suits mixed
+// content.
+const DefaultConfig = "simple"
+
+// configName matches the names of text search configurations, which are
+// inlined in queries so that the planner can use the expression indexes.
+var configName = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// Detect returns the language of the CJK characters of a text, or
+// LanguageDefault: kana anywhere mark Japanese, else the first Hangul or Han
+// character marks Korean or Chinese.
+func Detect(text string) Language {
+	language := LanguageDefault
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			return LanguageJapanese
+		case language == LanguageDefault && unicode.Is(unicode.Hangul, r):
+			language = LanguageKorean
+		case language == LanguageDefault && unicode.Is(unicode.Han, r):
+			language = LanguageChinese
+		}
+	}
+	return language
+}
+
+// Configs maps languages to text search configurations; nil Configs search
+// every language with DefaultConfig.
+type Configs struct {
+	byLanguage map[Language]string
+}
+
+// NewConfigs returns the configurations of languages, keyed by language code
+// or "default", e.g. {"default": "english", "zh": "jiebacfg"}. Invalid
+// entries are skipped.
+func NewConfigs(configs map[string]string) *Configs {
+	c := &Configs{byLanguage: map[Language]string{}}
+	for language, config := range configs {
+		if !configName.MatchString(config) {
+			slog.Warn("Invalid text search configuration, skipping", "language", language, "config", config)
+			continue
+		}
+		c.byLanguage[Language(language)] = config
+	}
+	return c
+}
+
+// For returns the configuration of a language, and whether the CJK runs of
+// the documents and the query are split into bigrams first, which is the
+// case for languages searched with the default configuration.
+func (c *Configs) For(language Language) (config string, bigrams bool) {
+	if c == nil {
+		return DefaultConfig, true
+	}
+	if config, ok := c.byLanguage[language]; ok && language != LanguageDefault {
+		return config, false
+	}
+	return c.Default(), true
+}
+
+// Default returns the configuration of the default language.
+func (c *Configs) Default() string {
+	if c == nil {
+		return DefaultConfig
+	}
+	if config, ok := c.byLanguage[LanguageDefault]; ok {
+		return config
+	}
+	return DefaultConfig
+}
+
+// NeedsSubstring reports whether a query searched over bigrams cannot match:
+// it has a CJK run of a single character.
+func NeedsSubstring(query string) bool {
+	run := 0
+	for _, r := range query + " " {
+		if isCJK(r) {
+			run++
+			continue
+		}
+		if run == 1 {
+			return true
+		}
+		run = 0
+	}
+	return false
+}
+
+// Bigrams splits the CJK runs of a text into their overlapping bigrams, as
+// the cjk_bigrams SQL function does, e.g. "学习Go语言" into "学习 Go 语言".
+func Bigrams(text string) string {
+	out := make([]rune, 0, len(text)*2)
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if !isCJK(r) {
+			out = append(out, r)
+			continue
+		}
+		start := i
+		for i+1 < len(runes) && isCJK(runes[i+1]) {
+			i++
+		}
+		out = append(out, ' ')
+		if i == start {
+			out = append(out, r)
+		}
+		for j := start; j < i; j++ {
+			if j > start {
+				out = append(out, ' ')
+			}
+			out = append(out, runes[j], runes[j+1])
+		}
+		out = append(out, ' ')
+	}
+	return string(out)
+}
+
+// isCJK reports whether a character is written without spaces between
+// words: Han, kana or Hangul syllables. The ranges are the ones of the
+// cjk_bigrams SQL function.
+func isCJK(r rune) bool {
+	return (r >= 0x3040 && r <= 0x30FF) || // Hiragana, Katakana
+		(r >= 0x3400 && r <= 0x4DBF) || // CJK Extension A
+		(r >= 0x4E00 && r <= 0x9FFF) || // CJK Unified Ideographs
+		(r >= 0xAC00 && r <= 0xD7AF) || // Hangul Syllables
+		(r >= 0xF900 && r <= 0xFAFF) || // CJK Compatibility Ideographs
+		(r >= 0x20000 && r <= 0x2FA1F) // CJK Extensions B and later
+}
@@ -0,0 +1,58 @@
+package textsearch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	for text, want := range map[string]Language{
+		"kubernetes upgrade": LanguageDefault,
+		"":                   LanguageDefault,
+		"机器学习 notes":         LanguageChinese,
+		"東京の天気":              LanguageJapanese,
+		"한국어 검색":             LanguageKorean,
+		"Go 语言 and 한국어":      LanguageChinese,
+	} {
+		assert.Equal(t, want, Detect(text), text)
+	}
+}
+
+func TestConfigs(t *testing.T) {
+	configs := NewConfigs(map[string]string{"default": "english", "zh": "jiebacfg", "ja": "bad'; DROP TABLE memo"})
+
+	config, bigrams := configs.For(LanguageDefault)
+	assert.Equal(t, "english", config)
+	assert.True(t, bigrams)
+	config, bigrams = configs.For(LanguageChinese)
+	assert.Equal(t, "jiebacfg", config)
+	assert.False(t, bigrams, "the configuration of the language segments it")
+	config, bigrams = configs.For(LanguageJapanese)
+	assert.Equal(t, "english", config, "invalid configurations are skipped")
+	assert.True(t, bigrams)
+
+	config, bigrams = NewConfigs(nil).For(LanguageKorean)
+	assert.Equal(t, DefaultConfig, config)
+	assert.True(t, bigrams)
+}
+
+func TestBigrams(t *testing.T) {
+	for text, want := range map[string]string{
+		"学习Go语言":        "学习 Go 语言",
+		"机器学习":          "机器 器学 学习",
+		"猫 and dogs":    "猫 and dogs",
+		"plain english": "plain english",
+		"한국어":           "한국 국어",
+	} {
+		assert.Equal(t, want, strings.Join(strings.Fields(Bigrams(text)), " "), text)
+	}
+}
+
+func TestNeedsSubstring(t *testing.T) {
+	assert.True(t, NeedsSubstring("猫"))
+	assert.True(t, NeedsSubstring("Go 猫 语言"))
+	assert.False(t, NeedsSubstring("机器学习"))
+	assert.False(t, NeedsSubstring("kubernetes"))
+}
@@ -0,0 +1,107 @@
+package timeline
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/hrygo/divinesense/ai/duplicate"
+)
+
+// DBStore reads the timeline from the memo, ai_conversation, schedule and
+// agent_session_stats tables (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new timeline store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// kindQueries select the events of each kind as (kind, ref, title, ts,
+// files), for user $1 between $2 and $3. Memo titles are derived from the
+// beginning of their content.
+var kindQueries = map[Kind]string{
+	KindMemo: `
+		SELECT 'memo', uid, LEFT(content, 200), created_ts, NULL::TEXT[]
+		FROM memo WHERE creator_id = $1 AND created_ts BETWEEN $2 AND $3`,
+	KindChat: `
+		SELECT 'chat', uid, title, updated_ts, NULL::TEXT[]
+		FROM ai_conversation WHERE creator_id = $1 AND updated_ts BETWEEN $2 AND $3`,
+	KindSchedule: `
+		SELECT 'schedule', uid, title, COALESCE(end_ts, start_ts), NULL::TEXT[]
+		FROM schedule
+		WHERE creator_id = $1 AND row_status = 'NORMAL'
+			AND COALESCE(end_ts, start_ts) BETWEEN $2 AND LEAST($3, EXTRACT(EPOCH FROM NOW())::BIGINT)`,
+	KindGeek: `
+		SELECT 'geek', s.session_id, COALESCE(c.title, ''), EXTRACT(EPOCH FROM s.ended_at)::BIGINT, s.file_paths
+		FROM agent_session_stats s
+		LEFT JOIN ai_conversation c ON c.id = s.conversation_id
+		WHERE s.user_id = $1 AND s.agent_type = 'geek' AND s.files_modified > 0
+			AND EXTRACT(EPOCH FROM s.ended_at)::BIGINT BETWEEN $2 AND $3`,
+}
+
+// union returns the events of the kinds of q.
+func union(q *Query) string {
+	parts := make([]string, 0, len(q.Kinds))
+	for _, kind := range q.Kinds {
+		parts = append(parts, kindQueries[kind])
+	}
+	return strings.Join(parts, "\n\t\tUNION ALL")
+}
+
+// List implements Store.
+func (s *DBStore) List(ctx context.Context, q *Query) ([]*Event, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT kind, ref, title, ts, files FROM (`+union(q)+`
+		) AS e (kind, ref, title, ts, files)
+		ORDER BY ts DESC, kind, ref
+		LIMIT $4 OFFSET $5`,
+		q.UserID, q.Start, q.End, q.Limit, q.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list timeline: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*Event
+	for rows.Next() {
+		event := &Event{}
+		var files pq.StringArray
+		if err := rows.Scan(&event.Kind, &event.Ref, &event.Title, &event.Ts, &files); err != nil {
+			return nil, fmt.Errorf("failed to scan timeline event: %w", err)
+		}
+		event.Files = files
+		if event.Kind == KindMemo {
+			event.Title = duplicate.ExtractTitle(event.Title)
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// CountBuckets implements Store.
+func (s *DBStore) CountBuckets(ctx context.Context, q *Query) ([]Bucket, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT kind, ts - ts % $4 AS bucket, COUNT(*) FROM (`+union(q)+`
+		) AS e (kind, ref, title, ts, files)
+		GROUP BY kind, bucket`,
+		q.UserID, q.Start, q.End, BucketSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count timeline: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []Bucket
+	for rows.Next() {
+		var bucket Bucket
+		if err := rows.Scan(&bucket.Kind, &bucket.Ts, &bucket.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan timeline bucket: %w", err)
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, rows.Err()
+}
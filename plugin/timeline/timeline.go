@@ -0,0 +1,304 @@
+// Package timeline is the activity timeline of a user: the memos they
+// created, their chats, the schedules that took place and the files the geek
+// parrot modified for them, in one chronological list.
+//
+// The timeline is read from the tables of each activity; it stores nothing.
+package timeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	agents "github.com/hrygo/divinesense/ai/agents"
+)
+
+// ToolName is the name of the timeline tool of agents.
+const ToolName = "activity_timeline"
+
+// ErrInvalidQuery is returned for queries with unknown kinds or time ranges
+// out of bounds.
+var ErrInvalidQuery = errors.New("invalid timeline query")
+
+// Kind is the kind of an activity.
+type Kind string
+
+const (
+	// KindMemo is a memo created by the user.
+	KindMemo Kind = "memo"
+	// KindChat is a conversation with a parrot, at its last message.
+	KindChat Kind = "chat"
+	// KindSchedule is a schedule that took place, at its end.
+	KindSchedule Kind = "schedule"
+	// KindGeek is a geek mode session that modified files, at its end.
+	KindGeek Kind = "geek"
+)
+
+// Kinds are all the kinds of activity, in display order.
+var Kinds = []Kind{KindMemo, KindChat, KindSchedule, KindGeek}
+
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+	// maxRange bounds the time range of a summary.
+	maxRange = 366 * 24 * time.Hour
+)
+
+// Event is an activity of the timeline.
+type Event struct {
+	Kind Kind `json:"kind"`
+	// Ref identifies the memo, conversation or schedule by UID, or the geek
+	// session by ID.
+	Ref   string `json:"ref"`
+	Title string `json:"title"`
+	Ts    int64  `json:"ts"`
+	// Files are the paths modified by a geek session.
+	Files []string `json:"files,omitempty"`
+}
+
+// Query selects the events of a user, newest first.
+type Query struct {
+	UserID int32
+	// Kinds filters the events; all kinds when empty.
+	Kinds []Kind
+	// Start and End bound the event time, inclusive; End defaults to now.
+	Start  int64
+	End    int64
+	Limit  int
+	Offset int
+}
+
+// Normalize validates a query and applies the defaults.
+func (q *Query) Normalize() error {
+	if len(q.Kinds) == 0 {
+		q.Kinds = Kinds
+	}
+	for _, kind := range q.Kinds {
+		if !slices.Contains(Kinds, kind) {
+			return fmt.Errorf("%w: unknown activity kind %q", ErrInvalidQuery, kind)
+		}
+	}
+	if q.End <= 0 {
+		q.End = time.Now().Unix()
+	}
+	if q.Start > q.End {
+		return fmt.Errorf("%w: start must be before end", ErrInvalidQuery)
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultLimit
+	}
+	q.Limit = min(q.Limit, maxLimit)
+	q.Offset = max(q.Offset, 0)
+	return nil
+}
+
+// ParseKinds parses a comma-separated list of kinds.
+func ParseKinds(s string) []Kind {
+	var kinds []Kind
+	for _, kind := range strings.Split(s, ",") {
+		if kind = strings.TrimSpace(strings.ToLower(kind)); kind != "" {
+			kinds = append(kinds, Kind(kind))
+		}
+	}
+	return kinds
+}
+
+// DayCount is the number of events of a kind on a day.
+type DayCount struct {
+	// Date is the day in the summary's time zone, as YYYY-MM-DD.
+	Date  string `json:"date"`
+	Kind  Kind   `json:"kind"`
+	Count int    `json:"count"`
+}
+
+// BucketSeconds is the width of the buckets events are counted in. Time
+// zone offsets are multiples of it, so buckets never straddle two days.
+const BucketSeconds = 15 * 60
+
+// Bucket is the number of events of a kind in a BucketSeconds interval.
+type Bucket struct {
+	Kind Kind
+	// Ts is the start of the interval, a multiple of BucketSeconds.
+	Ts    int64
+	Count int
+}
+
+// Store reads the activities of users.
+type Store interface {
+	// List returns the events selected by a normalized query.
+	List(ctx context.Context, q *Query) ([]*Event, error)
+	// CountBuckets counts the events selected by a normalized query per
+	// kind and bucket, ignoring the limit and offset.
+	CountBuckets(ctx context.Context, q *Query) ([]Bucket, error)
+}
+
+// Timeline reads the activity timeline of users.
+type Timeline struct {
+	store Store
+}
+
+// New creates a timeline.
+func New(store Store) *Timeline {
+	return &Timeline{store: store}
+}
+
+// List returns a page of the events of a user, newest first, and whether
+// more events follow.
+func (t *Timeline) List(ctx context.Context, q *Query) ([]*Event, bool, error) {
+	if err := q.Normalize(); err != nil {
+		return nil, false, err
+	}
+	page := *q
+	page.Limit++
+	events, err := t.store.List(ctx, &page)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(events) > q.Limit {
+		return events[:q.Limit], true, nil
+	}
+	return events, false, nil
+}
+
+// Summary is the number of activities of a user over a period.
+type Summary struct {
+	Start  int64        `json:"start"`
+	End    int64        `json:"end"`
+	Totals map[Kind]int `json:"totals"`
+	Days   []DayCount   `json:"days"`
+}
+
+// Summarize counts the events of a user per kind and per day in tz.
+func (t *Timeline) Summarize(ctx context.Context, q *Query, tz *time.Location) (*Summary, error) {
+	if err := q.Normalize(); err != nil {
+		return nil, err
+	}
+	if time.Duration(q.End-q.Start)*time.Second > maxRange {
+		return nil, fmt.Errorf("%w: summary range must be at most a year", ErrInvalidQuery)
+	}
+	buckets, err := t.store.CountBuckets(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	summary := &Summary{Start: q.Start, End: q.End, Totals: make(map[Kind]int, len(q.Kinds)), Days: []DayCount{}}
+	for _, kind := range q.Kinds {
+		summary.Totals[kind] = 0
+	}
+	index := map[DayCount]int{}
+	for _, bucket := range buckets {
+		summary.Totals[bucket.Kind] += bucket.Count
+		key := DayCount{Date: time.Unix(bucket.Ts, 0).In(tz).Format("2006-01-02"), Kind: bucket.Kind}
+		i, ok := index[key]
+		if !ok {
+			i = len(summary.Days)
+			index[key] = i
+			summary.Days = append(summary.Days, key)
+		}
+		summary.Days[i].Count += bucket.Count
+	}
+	slices.SortFunc(summary.Days, func(a, b DayCount) int {
+		if c := strings.Compare(a.Date, b.Date); c != 0 {
+			return c
+		}
+		return slices.Index(Kinds, a.Kind) - slices.Index(Kinds, b.Kind)
+	})
+	return summary, nil
+}
+
+// ToolFor returns the activity_timeline tool of a user, or nil without a
+// timeline.
+func (t *Timeline) ToolFor(userID int32) agents.ToolWithSchema {
+	if t == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		ToolName,
+		"List what the user did recently: memos created, chats, schedules that took place and files "+
+			"modified in geek mode, newest first, with counts per kind. Use it for questions about past activity "+
+			`such as "what did I do this week". Input: {"days": 7, "kinds": ["memo", "chat", "schedule", "geek"], "limit": 20}.`,
+		func(ctx context.Context, input string) (string, error) {
+			var args struct {
+				Days  int      `json:"days"`
+				Kinds []string `json:"kinds"`
+				Limit int      `json:"limit"`
+			}
+			if err := json.Unmarshal([]byte(input), &args); err != nil {
+				return "", fmt.Errorf("invalid input: %w", err)
+			}
+			if args.Days <= 0 {
+				args.Days = 7
+			}
+			if args.Limit <= 0 {
+				args.Limit = 20
+			}
+			now := time.Now()
+			q := &Query{
+				UserID: userID,
+				Kinds:  ParseKinds(strings.Join(args.Kinds, ",")),
+				Start:  now.AddDate(0, 0, -min(args.Days, 366)).Unix(),
+				End:    now.Unix(),
+				Limit:  args.Limit,
+			}
+			summary, err := t.Summarize(ctx, q, time.Local)
+			if err != nil {
+				return "", err
+			}
+			events, more, err := t.List(ctx, q)
+			if err != nil {
+				return "", err
+			}
+			return FormatEvents(summary, events, more, time.Local), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"days": map[string]any{"type": "integer", "description": "Number of past days to cover (default 7)"},
+				"kinds": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string", "enum": []string{"memo", "chat", "schedule", "geek"}},
+					"description": "Kinds of activity; all kinds when empty",
+				},
+				"limit": map[string]any{"type": "integer", "description": fmt.Sprintf("Number of events listed (at most %d)", maxLimit)},
+			},
+		},
+	)
+}
+
+// FormatEvents renders a summary and its most recent events for agents.
+func FormatEvents(summary *Summary, events []*Event, more bool, tz *time.Location) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Activity from %s to %s:", time.Unix(summary.Start, 0).In(tz).Format("2006-01-02 15:04"),
+		time.Unix(summary.End, 0).In(tz).Format("2006-01-02 15:04"))
+	for _, kind := range Kinds {
+		if count, ok := summary.Totals[kind]; ok {
+			fmt.Fprintf(&b, " %s=%d", kind, count)
+		}
+	}
+	b.WriteString("\n")
+	if len(events) == 0 {
+		b.WriteString("No activity in this period.")
+		return b.String()
+	}
+	for _, event := range events {
+		fmt.Fprintf(&b, "\n- %s [%s] ", time.Unix(event.Ts, 0).In(tz).Format("2006-01-02 15:04"), event.Kind)
+		if event.Title != "" {
+			b.WriteString(event.Title)
+		} else {
+			b.WriteString(event.Ref)
+		}
+		if event.Kind == KindMemo {
+			fmt.Fprintf(&b, " (memos/%s)", event.Ref)
+		}
+		if len(event.Files) > 0 {
+			fmt.Fprintf(&b, " — files: %s", strings.Join(event.Files, ", "))
+		}
+	}
+	if more {
+		b.WriteString("\n\n(older events omitted)")
+	}
+	return b.String()
+}
@@ -0,0 +1,96 @@
+package timeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	events  []*Event
+	buckets []Bucket
+	query   *Query
+}
+
+func (s *fakeStore) List(_ context.Context, q *Query) ([]*Event, error) {
+	s.query = q
+	end := min(q.Offset+q.Limit, len(s.events))
+	return s.events[min(q.Offset, end):end], nil
+}
+
+func (s *fakeStore) CountBuckets(context.Context, *Query) ([]Bucket, error) {
+	return s.buckets, nil
+}
+
+func TestQueryNormalize(t *testing.T) {
+	q := &Query{Limit: 1000, Offset: -1}
+	require.NoError(t, q.Normalize())
+	assert.Equal(t, Kinds, q.Kinds)
+	assert.Equal(t, maxLimit, q.Limit)
+	assert.Zero(t, q.Offset)
+	assert.NotZero(t, q.End)
+
+	assert.Equal(t, []Kind{KindMemo, KindGeek}, ParseKinds(" Memo, ,geek"))
+	assert.Error(t, (&Query{Kinds: []Kind{"email"}}).Normalize())
+	assert.Error(t, (&Query{Start: 20, End: 10}).Normalize())
+}
+
+func TestList(t *testing.T) {
+	store := &fakeStore{events: []*Event{
+		{Kind: KindMemo, Ref: "m1", Ts: 30},
+		{Kind: KindChat, Ref: "c1", Ts: 20},
+		{Kind: KindSchedule, Ref: "s1", Ts: 10},
+	}}
+	timeline := New(store)
+
+	events, more, err := timeline.List(context.Background(), &Query{UserID: 1, Limit: 2})
+	require.NoError(t, err)
+	assert.True(t, more)
+	assert.Len(t, events, 2)
+	assert.Equal(t, 3, store.query.Limit)
+
+	events, more, err = timeline.List(context.Background(), &Query{UserID: 1, Limit: 2, Offset: 2})
+	require.NoError(t, err)
+	assert.False(t, more)
+	assert.Equal(t, "s1", events[0].Ref)
+}
+
+func TestSummarize(t *testing.T) {
+	tz := time.FixedZone("UTC+8", 8*3600)
+	day := time.Date(2026, 10, 12, 0, 0, 0, 0, tz).Unix()
+	store := &fakeStore{buckets: []Bucket{
+		{Kind: KindGeek, Ts: day + 3600, Count: 1},
+		{Kind: KindMemo, Ts: day + 9*3600, Count: 2},
+		{Kind: KindMemo, Ts: day + 20*3600, Count: 1},
+		// 23:45 on the 12th in UTC+8, the 12th in UTC too.
+		{Kind: KindMemo, Ts: day + 24*3600 - BucketSeconds, Count: 1},
+		{Kind: KindMemo, Ts: day + 24*3600, Count: 3},
+	}}
+	summary, err := New(store).Summarize(context.Background(), &Query{UserID: 1, Start: day, End: day + 7*86400}, tz)
+	require.NoError(t, err)
+	assert.Equal(t, map[Kind]int{KindMemo: 7, KindChat: 0, KindSchedule: 0, KindGeek: 1}, summary.Totals)
+	assert.Equal(t, []DayCount{
+		{Date: "2026-10-12", Kind: KindMemo, Count: 4},
+		{Date: "2026-10-12", Kind: KindGeek, Count: 1},
+		{Date: "2026-10-13", Kind: KindMemo, Count: 3},
+	}, summary.Days)
+
+	_, err = New(store).Summarize(context.Background(), &Query{Start: 1, End: 2 * 366 * 86400}, tz)
+	assert.Error(t, err)
+}
+
+func TestFormatEvents(t *testing.T) {
+	ts := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC).Unix()
+	summary := &Summary{Start: ts - 86400, End: ts, Totals: map[Kind]int{KindMemo: 1, KindGeek: 1}}
+	out := FormatEvents(summary, []*Event{
+		{Kind: KindGeek, Ref: "sess-1", Title: "修复登录", Ts: ts, Files: []string{"auth.go", "auth_test.go"}},
+		{Kind: KindMemo, Ref: "abc", Title: "周会纪要", Ts: ts - 60},
+	}, true, time.UTC)
+	assert.Equal(t, "Activity from 2026-10-14 09:30 to 2026-10-15 09:30: memo=1 geek=1\n"+
+		"\n- 2026-10-15 09:30 [geek] 修复登录 — files: auth.go, auth_test.go"+
+		"\n- 2026-10-15 09:29 [memo] 周会纪要 (memos/abc)"+
+		"\n\n(older events omitted)", out)
+}
@@ -0,0 +1,69 @@
+package toolpack
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// utcOffset matches the fixed offsets time zones may be given as: UTC+8,
+// GMT-03:30, +05:45.
+var utcOffset = regexp.MustCompile(`^(?i:UTC|GMT)?([+-])(\d{1,2})(?::?(\d{2}))?$`)
+
+// loadLocation returns the location of an IANA time zone name, such as
+// Asia/Shanghai, or of a UTC offset; UTC when empty.
+func loadLocation(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	switch strings.ToUpper(name) {
+	case "", "UTC", "GMT", "Z":
+		return time.UTC, nil
+	}
+	if match := utcOffset.FindStringSubmatch(name); match != nil {
+		hours, _ := strconv.Atoi(match[2])
+		minutes, _ := strconv.Atoi(match[3])
+		if hours > 14 || minutes > 59 {
+			return nil, fmt.Errorf("%w: offset %q out of range", ErrInvalid, name)
+		}
+		offset := hours*3600 + minutes*60
+		if match[1] == "-" {
+			offset = -offset
+		}
+		return time.FixedZone(formatOffset(offset), offset), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown time zone %q, use an IANA name such as Asia/Shanghai", ErrInvalid, name)
+	}
+	return loc, nil
+}
+
+// formatOffset formats an offset in seconds as UTC+08:00.
+func formatOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	return fmt.Sprintf("UTC%s%02d:%02d", sign, offset/3600, offset%3600/60)
+}
+
+// formatTime describes a time in the time zone of each name.
+func formatTime(now time.Time, names []string) (string, error) {
+	if len(names) == 0 {
+		names = []string{""}
+	}
+	var b strings.Builder
+	for _, name := range names {
+		loc, err := loadLocation(name)
+		if err != nil {
+			return "", err
+		}
+		t := now.In(loc)
+		_, offset := t.Zone()
+		_, week := t.ISOWeek()
+		fmt.Fprintf(&b, "%s: %s %s (%s), ISO week %d\n", loc, t.Format("2006-01-02 15:04:05"), t.Weekday(), formatOffset(offset), week)
+	}
+	fmt.Fprintf(&b, "Unix time: %d", now.Unix())
+	return b.String(), nil
+}
@@ -0,0 +1,268 @@
+package toolpack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// frankfurterURL is the endpoint of the latest rates of the Frankfurter API.
+const frankfurterURL = "https://api.frankfurter.app/latest"
+
+// unit is a unit of measure: a quantity of it is factor base units of its
+// dimension.
+type unit struct {
+	dimension string
+	factor    float64
+}
+
+// units are the units by lower-case name and symbol. Temperatures are not
+// proportional and are converted by convertTemperature.
+var units = map[string]unit{}
+
+func init() {
+	for dimension, table := range map[string]map[float64][]string{
+		"length": {
+			1:        {"m", "meter", "meters", "metre", "metres", "米"},
+			1000:     {"km", "kilometer", "kilometers", "kilometre", "kilometres", "公里", "千米"},
+			0.01:     {"cm", "centimeter", "centimeters", "centimetre", "centimetres", "厘米"},
+			0.001:    {"mm", "millimeter", "millimeters", "millimetre", "millimetres", "毫米"},
+			0.0254:   {"in", "inch", "inches", "英寸"},
+			0.3048:   {"ft", "foot", "feet", "英尺"},
+			0.9144:   {"yd", "yard", "yards", "码"},
+			1609.344: {"mi", "mile", "miles", "英里"},
+			1852:     {"nmi", "nautical mile", "nautical miles", "海里"},
+			500:      {"里"},
+			1.0 / 3:  {"尺"},
+			1.0 / 30: {"寸"},
+		},
+		"mass": {
+			1:          {"kg", "kilogram", "kilograms", "公斤", "千克"},
+			0.001:      {"g", "gram", "grams", "克"},
+			0.000001:   {"mg", "milligram", "milligrams", "毫克"},
+			1000:       {"t", "tonne", "tonnes", "metric ton", "吨"},
+			0.45359237: {"lb", "lbs", "pound", "pounds", "磅"},
+			0.0283495:  {"oz", "ounce", "ounces", "盎司"},
+			6.35029318: {"st", "stone", "stones"},
+			0.5:        {"斤"},
+			0.05:       {"两"},
+		},
+		"volume": {
+			1:          {"l", "liter", "liters", "litre", "litres", "升"},
+			0.001:      {"ml", "milliliter", "milliliters", "millilitre", "millilitres", "毫升"},
+			1000:       {"m3", "m³", "cubic meter", "cubic meters", "立方米"},
+			3.78541178: {"gal", "gallon", "gallons", "加仑"},
+			0.9463529:  {"qt", "quart", "quarts"},
+			0.4731765:  {"pt", "pint", "pints"},
+			0.2365882:  {"cup", "cups"},
+			0.0295735:  {"fl oz", "fluid ounce", "fluid ounces"},
+		},
+		"area": {
+			1:            {"m2", "m²", "square meter", "square meters", "平方米"},
+			1000000:      {"km2", "km²", "square kilometer", "square kilometers", "平方公里"},
+			10000:        {"ha", "hectare", "hectares", "公顷"},
+			4046.8564224: {"acre", "acres", "英亩"},
+			0.09290304:   {"ft2", "ft²", "sq ft", "square foot", "square feet", "平方英尺"},
+			2000.0 / 3:   {"亩"},
+		},
+		"speed": {
+			1:             {"m/s", "meters per second"},
+			1 / 3.6:       {"km/h", "kph", "kmh", "公里每小时"},
+			0.44704:       {"mph", "miles per hour"},
+			1852 / 3600.0: {"kn", "knot", "knots", "节"},
+		},
+		"duration": {
+			1:        {"s", "sec", "second", "seconds", "秒"},
+			60:       {"min", "minute", "minutes", "分钟"},
+			3600:     {"h", "hr", "hour", "hours", "小时"},
+			86400:    {"d", "day", "days", "天"},
+			604800:   {"wk", "week", "weeks", "周"},
+			31557600: {"yr", "year", "years", "年"},
+		},
+		"data": {
+			1:       {"b", "byte", "bytes", "字节"},
+			1e3:     {"kb", "kilobyte", "kilobytes"},
+			1e6:     {"mb", "megabyte", "megabytes"},
+			1e9:     {"gb", "gigabyte", "gigabytes"},
+			1e12:    {"tb", "terabyte", "terabytes"},
+			1 << 10: {"kib", "kibibyte", "kibibytes"},
+			1 << 20: {"mib", "mebibyte", "mebibytes"},
+			1 << 30: {"gib", "gibibyte", "gibibytes"},
+			1 << 40: {"tib", "tebibyte", "tebibytes"},
+		},
+		"energy": {
+			1:       {"j", "joule", "joules", "焦耳"},
+			1000:    {"kj", "kilojoule", "kilojoules", "千焦"},
+			4184:    {"kcal", "kilocalorie", "kilocalories", "calorie", "calories", "千卡", "大卡"},
+			3600000: {"kwh", "kilowatt hour", "kilowatt hours"},
+		},
+	} {
+		for factor, names := range table {
+			for _, name := range names {
+				units[name] = unit{dimension: dimension, factor: factor}
+			}
+		}
+	}
+}
+
+// temperatureUnits are the temperature scales by lower-case name and symbol.
+var temperatureUnits = map[string]string{
+	"c": "C", "°c": "C", "celsius": "C", "摄氏度": "C",
+	"f": "F", "°f": "F", "fahrenheit": "F", "华氏度": "F",
+	"k": "K", "kelvin": "K", "开尔文": "K",
+}
+
+// currencyCode matches ISO 4217 currency codes.
+var currencyCode = regexp.MustCompile(`^[A-Za-z]{3}$`)
+
+// Convert converts a value between units of the same dimension or, with a
+// currency provider, between currencies given by their ISO 4217 codes.
+func (t *Toolpack) Convert(ctx context.Context, value float64, from, to string) (string, error) {
+	fromKey, toKey := normalizeUnit(from), normalizeUnit(to)
+	if fromScale, ok := temperatureUnits[fromKey]; ok {
+		toScale, ok := temperatureUnits[toKey]
+		if !ok {
+			return "", fmt.Errorf("%w: cannot convert a temperature to %q", ErrInvalid, to)
+		}
+		result := convertTemperature(value, fromScale, toScale)
+		return fmt.Sprintf("%s %s = %s %s", formatNumber(value), temperatureSymbol(fromScale), formatNumber(result), temperatureSymbol(toScale)), nil
+	}
+	fromUnit, fromOK := units[fromKey]
+	toUnit, toOK := units[toKey]
+	if fromOK && toOK {
+		if fromUnit.dimension != toUnit.dimension {
+			return "", fmt.Errorf("%w: cannot convert %s (%s) to %s (%s)", ErrInvalid, from, fromUnit.dimension, to, toUnit.dimension)
+		}
+		result := value * fromUnit.factor / toUnit.factor
+		return fmt.Sprintf("%s %s = %s %s", formatNumber(value), from, formatNumber(result), to), nil
+	}
+	if !fromOK && !toOK && currencyCode.MatchString(from) && currencyCode.MatchString(to) {
+		return t.convertCurrency(ctx, value, strings.ToUpper(from), strings.ToUpper(to))
+	}
+	unknown := from
+	if fromOK {
+		unknown = to
+	}
+	return "", fmt.Errorf("%w: unknown unit %q", ErrInvalid, unknown)
+}
+
+// normalizeUnit returns the key of a unit name in the unit tables.
+func normalizeUnit(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// convertTemperature converts between the C, F and K scales.
+func convertTemperature(value float64, from, to string) float64 {
+	celsius := value
+	switch from {
+	case "F":
+		celsius = (value - 32) * 5 / 9
+	case "K":
+		celsius = value - 273.15
+	}
+	switch to {
+	case "F":
+		return celsius*9/5 + 32
+	case "K":
+		return celsius + 273.15
+	}
+	return celsius
+}
+
+// temperatureSymbol returns the symbol of a scale: kelvins have no degree
+// sign.
+func temperatureSymbol(scale string) string {
+	if scale == "K" {
+		return scale
+	}
+	return "°" + scale
+}
+
+// formatNumber formats a result with at most 8 significant digits.
+func formatNumber(v float64) string {
+	if v == 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'g', 8, 64), 64)
+	if math.Abs(rounded) >= 1e15 || math.Abs(rounded) < 1e-6 {
+		return strconv.FormatFloat(rounded, 'g', -1, 64)
+	}
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+func (t *Toolpack) convertCurrency(ctx context.Context, value float64, from, to string) (string, error) {
+	if t.currency == nil {
+		return "", errors.New("currency conversion is not configured on this server; do not guess exchange rates")
+	}
+	rate, date, err := t.currency.Rate(ctx, from, to)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s = %s %s (reference rate of %s)",
+		formatMoney(value), from, formatMoney(value*rate), to, date), nil
+}
+
+func formatMoney(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', 2, 64)
+}
+
+// rates caches the exchange rates of the currency provider.
+type rates struct {
+	client  *http.Client
+	baseURL string
+	mu      sync.Mutex
+	entries map[string]*ratesEntry
+}
+
+type ratesEntry struct {
+	rates   map[string]float64
+	date    string
+	expires time.Time
+}
+
+func newRates(client *http.Client, baseURL string) *rates {
+	return &rates{client: client, baseURL: baseURL, entries: map[string]*ratesEntry{}}
+}
+
+// Rate returns the exchange rate of from in to, and its date.
+func (r *rates) Rate(ctx context.Context, from, to string) (float64, string, error) {
+	if from == to {
+		return 1, time.Now().Format(time.DateOnly), nil
+	}
+	now := time.Now()
+	r.mu.Lock()
+	entry := r.entries[from]
+	r.mu.Unlock()
+	if entry == nil || now.After(entry.expires) {
+		var latest struct {
+			Date  string             `json:"date"`
+			Rates map[string]float64 `json:"rates"`
+		}
+		err := getJSON(ctx, r.client, r.baseURL+"?"+url.Values{"from": {from}}.Encode(), &latest)
+		var status *statusError
+		if errors.As(err, &status) && status.code == http.StatusNotFound {
+			return 0, "", fmt.Errorf("%w: unknown currency %q", ErrInvalid, from)
+		}
+		if err != nil {
+			return 0, "", err
+		}
+		entry = &ratesEntry{rates: latest.Rates, date: latest.Date, expires: now.Add(ratesTTL)}
+		r.mu.Lock()
+		// One entry per base currency: the cache stays small
+		r.entries[from] = entry
+		r.mu.Unlock()
+	}
+	rate, ok := entry.rates[to]
+	if !ok {
+		return 0, "", fmt.Errorf("%w: no rate of %s in %s", ErrInvalid, from, to)
+	}
+	return rate, entry.date, nil
+}
@@ -0,0 +1,116 @@
+package toolpack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	agents "github.com/hrygo/divinesense/ai/agents"
+)
+
+// TimeInput is the input of the current_time tool.
+type TimeInput struct {
+	// Timezones are IANA time zone names or UTC offsets; UTC when empty.
+	Timezones []string `json:"timezones"`
+}
+
+// WeatherInput is the input of the weather tool.
+type WeatherInput struct {
+	Location string `json:"location"`
+}
+
+// ConvertInput is the input of the unit_convert tool.
+type ConvertInput struct {
+	Value float64 `json:"value"`
+	From  string  `json:"from"`
+	To    string  `json:"to"`
+}
+
+// TimeTool returns the current_time tool.
+func (t *Toolpack) TimeTool() agents.ToolWithSchema {
+	return agents.NewNativeTool(
+		TimeToolName,
+		"Get the current date, time and weekday in one or more time zones. "+
+			"Use it whenever the answer depends on the current time, today's date or the time somewhere else; never guess them.",
+		func(_ context.Context, input string) (string, error) {
+			var in TimeInput
+			if err := json.Unmarshal([]byte(input), &in); err != nil {
+				return "", fmt.Errorf("invalid input: %w", err)
+			}
+			return formatTime(t.now(), in.Timezones)
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"timezones": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "IANA time zones (e.g. Asia/Shanghai, America/New_York) or UTC offsets (e.g. UTC+8); defaults to UTC",
+				},
+			},
+		},
+	)
+}
+
+// WeatherTool returns the weather tool, or nil without a weather provider.
+func (t *Toolpack) WeatherTool() agents.ToolWithSchema {
+	if !t.HasWeather() {
+		return nil
+	}
+	return agents.NewNativeTool(
+		WeatherToolName,
+		"Get the current weather of a city, and the forecast of the next days when available. "+
+			"Use it for any question about the weather; never guess it.",
+		func(ctx context.Context, input string) (string, error) {
+			var in WeatherInput
+			if err := json.Unmarshal([]byte(input), &in); err != nil {
+				return "", fmt.Errorf("invalid input: %w", err)
+			}
+			if strings.TrimSpace(in.Location) == "" {
+				return "", fmt.Errorf("%w: location is required", ErrInvalid)
+			}
+			weather, err := t.weather.Current(ctx, in.Location)
+			if err != nil {
+				return "", err
+			}
+			return formatWeather(weather), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"location": map[string]any{"type": "string", "description": "City name, in English if possible, e.g. Shanghai or Paris"},
+			},
+			"required": []string{"location"},
+		},
+	)
+}
+
+// ConvertTool returns the unit_convert tool.
+func (t *Toolpack) ConvertTool() agents.ToolWithSchema {
+	description := "Convert a value between units of length, mass, volume, area, speed, duration, data, energy or temperature, " +
+		"including Chinese units (斤, 两, 里, 尺, 寸, 亩). Use it instead of converting in your head."
+	if t.currency != nil {
+		description += " Also converts money between currencies given as ISO 4217 codes (USD, CNY, EUR...) at the latest reference rates."
+	}
+	return agents.NewNativeTool(
+		ConvertToolName,
+		description,
+		func(ctx context.Context, input string) (string, error) {
+			var in ConvertInput
+			if err := json.Unmarshal([]byte(input), &in); err != nil {
+				return "", fmt.Errorf("invalid input: %w", err)
+			}
+			return t.Convert(ctx, in.Value, in.From, in.To)
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"value": map[string]any{"type": "number", "description": "Value to convert"},
+				"from":  map[string]any{"type": "string", "description": "Unit of the value, e.g. km, lb, °F, 斤, or a currency code"},
+				"to":    map[string]any{"type": "string", "description": "Unit to convert to"},
+			},
+			"required": []string{"value", "from", "to"},
+		},
+	)
+}
@@ -0,0 +1,119 @@
+// Package toolpack provides the utility tools of the parrots: the current
+// time in a time zone, the weather of a place from a configurable provider,
+// and conversions between units and between currencies. Parrots answer these
+// questions with the tools instead of guessing.
+//
+// The time and unit tools work offline. The weather tool and the currency
+// conversions call the provider configured by the DIVINESENSE_WEATHER_* and
+// DIVINESENSE_CURRENCY_PROVIDER variables and are unavailable without one.
+package toolpack
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hrygo/divinesense/internal/profile"
+)
+
+// ErrInvalid is returned for invalid configurations and tool inputs.
+var ErrInvalid = errors.New("invalid input")
+
+// Names of the tools of the toolpack.
+const (
+	TimeToolName    = "current_time"
+	WeatherToolName = "weather"
+	ConvertToolName = "unit_convert"
+)
+
+// Weather providers.
+const (
+	// WeatherOpenMeteo is Open-Meteo, which needs no API key.
+	WeatherOpenMeteo = "open-meteo"
+	// WeatherOpenWeatherMap is OpenWeatherMap, which needs an API key.
+	WeatherOpenWeatherMap = "openweathermap"
+)
+
+// CurrencyFrankfurter is the Frankfurter API of the reference rates of the
+// European Central Bank, which needs no API key.
+const CurrencyFrankfurter = "frankfurter"
+
+const (
+	// weatherTTL and ratesTTL are how long the weather of a place and the
+	// rates of a currency are cached.
+	weatherTTL = 10 * time.Minute
+	ratesTTL   = time.Hour
+	// requestTimeout bounds the requests to the providers.
+	requestTimeout = 10 * time.Second
+)
+
+// Config configures the providers of the toolpack; empty providers disable
+// their tools.
+type Config struct {
+	WeatherProvider  string
+	WeatherAPIKey    string
+	CurrencyProvider string
+}
+
+// ConfigFromProfile returns the configuration of the DIVINESENSE_WEATHER_*
+// and DIVINESENSE_CURRENCY_PROVIDER variables.
+func ConfigFromProfile(p *profile.Profile) Config {
+	return Config{
+		WeatherProvider:  p.WeatherProvider,
+		WeatherAPIKey:    p.WeatherAPIKey,
+		CurrencyProvider: p.CurrencyProvider,
+	}
+}
+
+// Validate checks the providers.
+func (c Config) Validate() error {
+	switch c.WeatherProvider {
+	case "", WeatherOpenMeteo:
+	case WeatherOpenWeatherMap:
+		if c.WeatherAPIKey == "" {
+			return fmt.Errorf("%w: %s requires an API key", ErrInvalid, WeatherOpenWeatherMap)
+		}
+	default:
+		return fmt.Errorf("%w: unknown weather provider %q", ErrInvalid, c.WeatherProvider)
+	}
+	switch c.CurrencyProvider {
+	case "", CurrencyFrankfurter:
+	default:
+		return fmt.Errorf("%w: unknown currency provider %q", ErrInvalid, c.CurrencyProvider)
+	}
+	return nil
+}
+
+// Toolpack serves the utility tools.
+type Toolpack struct {
+	weather  weatherProvider
+	currency *rates
+	now      func() time.Time
+}
+
+// New creates the toolpack of a configuration. Invalid providers are
+// reported and left out, so that the offline tools stay available.
+func New(cfg Config) (*Toolpack, error) {
+	t := &Toolpack{now: time.Now}
+	err := cfg.Validate()
+	if err != nil {
+		return t, err
+	}
+	client := &http.Client{Timeout: requestTimeout}
+	switch cfg.WeatherProvider {
+	case WeatherOpenMeteo:
+		t.weather = newCachedWeather(&openMeteo{client: client, geocodingURL: openMeteoGeocodingURL, forecastURL: openMeteoForecastURL})
+	case WeatherOpenWeatherMap:
+		t.weather = newCachedWeather(&openWeatherMap{client: client, apiKey: cfg.WeatherAPIKey, baseURL: openWeatherMapURL})
+	}
+	if cfg.CurrencyProvider == CurrencyFrankfurter {
+		t.currency = newRates(client, frankfurterURL)
+	}
+	return t, nil
+}
+
+// HasWeather reports whether a weather provider is configured.
+func (t *Toolpack) HasWeather() bool {
+	return t != nil && t.weather != nil
+}
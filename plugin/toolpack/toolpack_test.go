@@ -0,0 +1,182 @@
+package toolpack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{WeatherProvider: WeatherOpenMeteo, CurrencyProvider: CurrencyFrankfurter}.Validate())
+	assert.ErrorIs(t, Config{WeatherProvider: WeatherOpenWeatherMap}.Validate(), ErrInvalid)
+	assert.ErrorIs(t, Config{WeatherProvider: "accuweather"}.Validate(), ErrInvalid)
+	assert.ErrorIs(t, Config{CurrencyProvider: "xe"}.Validate(), ErrInvalid)
+
+	toolkit, err := New(Config{WeatherProvider: "accuweather"})
+	require.Error(t, err)
+	assert.False(t, toolkit.HasWeather())
+	assert.Nil(t, toolkit.WeatherTool())
+	assert.NotNil(t, toolkit.TimeTool(), "the offline tools stay available")
+}
+
+func TestTimeTool(t *testing.T) {
+	toolkit, err := New(Config{})
+	require.NoError(t, err)
+	toolkit.now = func() time.Time { return time.Date(2026, 10, 17, 1, 30, 0, 0, time.UTC) }
+
+	out, err := toolkit.TimeTool().Run(context.Background(), `{"timezones": ["Asia/Shanghai", "America/New_York", "UTC+5:45"]}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Shanghai: 2026-10-17 09:30:00 Saturday (UTC+08:00), ISO week 42\n"+
+		"America/New_York: 2026-10-16 21:30:00 Friday (UTC-04:00), ISO week 42\n"+
+		"UTC+05:45: 2026-10-17 07:15:00 Saturday (UTC+05:45), ISO week 42\n"+
+		"Unix time: 1792200600", out)
+
+	out, err = toolkit.TimeTool().Run(context.Background(), `{}`)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "UTC: 2026-10-17 01:30:00"))
+
+	_, err = toolkit.TimeTool().Run(context.Background(), `{"timezones": ["Mars/Olympus"]}`)
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = toolkit.TimeTool().Run(context.Background(), `{"timezones": ["UTC+15"]}`)
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestConvert(t *testing.T) {
+	toolkit, err := New(Config{})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		value    float64
+		from, to string
+		want     string
+	}{
+		{5, "mi", "km", "5 mi = 8.04672 km"},
+		{3, "斤", "kg", "3 斤 = 1.5 kg"},
+		{1, "Pound", "两", "1 Pound = 9.0718474 两"},
+		{2, "亩", "m2", "2 亩 = 1333.3333 m2"},
+		{100, "°F", "celsius", "100 °F = 37.777778 °C"},
+		{0, "C", "K", "0 °C = 273.15 K"},
+		{1, "GiB", "MB", "1 GiB = 1073.7418 MB"},
+		{90, "min", "hours", "90 min = 1.5 hours"},
+	} {
+		out, err := toolkit.Convert(ctx, tc.value, tc.from, tc.to)
+		require.NoError(t, err, tc.want)
+		assert.Equal(t, tc.want, out)
+	}
+
+	_, err = toolkit.Convert(ctx, 1, "kg", "km")
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = toolkit.Convert(ctx, 1, "kg", "furlongs")
+	assert.ErrorContains(t, err, `unknown unit "furlongs"`)
+	_, err = toolkit.Convert(ctx, 1, "C", "kg")
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = toolkit.Convert(ctx, 100, "USD", "CNY")
+	assert.ErrorContains(t, err, "not configured")
+}
+
+func TestConvertCurrency(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Query().Get("from") != "USD" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"amount": 1.0, "base": "USD", "date": "2026-10-16", "rates": {"CNY": 7.1234, "EUR": 0.92}}`)
+	}))
+	defer server.Close()
+	toolkit, err := New(Config{})
+	require.NoError(t, err)
+	toolkit.currency = newRates(server.Client(), server.URL)
+	ctx := context.Background()
+
+	out, err := toolkit.Convert(ctx, 100, "usd", "CNY")
+	require.NoError(t, err)
+	assert.Equal(t, "100.00 USD = 712.34 CNY (reference rate of 2026-10-16)", out)
+	out, err = toolkit.Convert(ctx, 10, "USD", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "10.00 USD = 9.20 EUR (reference rate of 2026-10-16)", out)
+	assert.EqualValues(t, 1, requests.Load(), "rates are cached")
+
+	_, err = toolkit.Convert(ctx, 1, "USD", "XYZ")
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = toolkit.Convert(ctx, 1, "ABC", "USD")
+	assert.ErrorContains(t, err, `unknown currency "ABC"`)
+	assert.Contains(t, toolkit.ConvertTool().Description(), "currencies")
+}
+
+func TestOpenMeteo(t *testing.T) {
+	var forecasts atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("name") != "Shanghai" {
+			fmt.Fprint(w, `{"generationtime_ms": 0.5}`)
+			return
+		}
+		fmt.Fprint(w, `{"results": [{"name": "Shanghai", "admin1": "Shanghai", "country": "China", "latitude": 31.22222, "longitude": 121.45806}]}`)
+	})
+	mux.HandleFunc("/forecast", func(w http.ResponseWriter, r *http.Request) {
+		forecasts.Add(1)
+		assert.Equal(t, "31.2222", r.URL.Query().Get("latitude"))
+		fmt.Fprint(w, `{
+			"current": {"temperature_2m": 21.4, "relative_humidity_2m": 78, "apparent_temperature": 22.1, "weather_code": 61, "wind_speed_10m": 12.6},
+			"daily": {"time": ["2026-10-17", "2026-10-18"], "weather_code": [61, 2],
+				"temperature_2m_max": [23.5, 25], "temperature_2m_min": [18.2, 17.9], "precipitation_probability_max": [80, 10]}
+		}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	toolkit := &Toolpack{weather: newCachedWeather(&openMeteo{
+		client:       server.Client(),
+		geocodingURL: server.URL + "/search",
+		forecastURL:  server.URL + "/forecast",
+	})}
+
+	tool := toolkit.WeatherTool()
+	require.NotNil(t, tool)
+	out, err := tool.Run(context.Background(), `{"location": "Shanghai"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Weather in Shanghai, China: light rain, 21.4°C (feels like 22.1°C), humidity 78%, wind 12.6 km/h\n"+
+		"2026-10-17: light rain, 18.2–23.5°C, 80% chance of precipitation\n"+
+		"2026-10-18: partly cloudy, 17.9–25.0°C, 10% chance of precipitation", out)
+	_, err = tool.Run(context.Background(), `{"location": " shanghai "}`)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, forecasts.Load(), "the weather of a place is cached")
+
+	_, err = tool.Run(context.Background(), `{"location": "Atlantis"}`)
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = tool.Run(context.Background(), `{"location": ""}`)
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestOpenWeatherMap(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.URL.Query().Get("appid"))
+		if r.URL.Query().Get("q") != "Paris" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"cod": "404", "message": "city not found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"name": "Paris", "sys": {"country": "FR"}, "main": {"temp": 12.5, "feels_like": 11, "humidity": 81},
+			"weather": [{"description": "overcast clouds"}], "wind": {"speed": 5}}`)
+	}))
+	defer server.Close()
+	provider := &openWeatherMap{client: server.Client(), apiKey: "secret", baseURL: server.URL}
+
+	weather, err := provider.Current(context.Background(), "Paris")
+	require.NoError(t, err)
+	assert.Equal(t, "Weather in Paris, FR: overcast clouds, 12.5°C (feels like 11.0°C), humidity 81%, wind 18.0 km/h", formatWeather(weather))
+
+	_, err = provider.Current(context.Background(), "Atlantis")
+	assert.ErrorIs(t, err, ErrInvalid)
+}
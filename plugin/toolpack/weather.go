@@ -0,0 +1,298 @@
+package toolpack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Endpoints of the weather providers.
+const (
+	openMeteoGeocodingURL = "https://geocoding-api.open-meteo.com/v1/search"
+	openMeteoForecastURL  = "https://api.open-meteo.com/v1/forecast"
+	openWeatherMapURL     = "https://api.openweathermap.org/data/2.5/weather"
+)
+
+// Weather is the current weather of a place, with the forecast of the next
+// days when the provider has one.
+type Weather struct {
+	Location string
+	// Temperature and FeelsLike are in °C, WindKph in km/h.
+	Temperature float64
+	FeelsLike   float64
+	Humidity    int
+	WindKph     float64
+	Condition   string
+	Forecast    []*DayForecast
+}
+
+// DayForecast is the forecast of a day.
+type DayForecast struct {
+	Date      string
+	Condition string
+	// Min and Max are in °C.
+	Min, Max float64
+	// PrecipitationChance is in percent.
+	PrecipitationChance int
+}
+
+// weatherProvider returns the weather of a place name.
+type weatherProvider interface {
+	Current(ctx context.Context, location string) (*Weather, error)
+}
+
+// maxCachedPlaces bounds the places whose weather is cached.
+const maxCachedPlaces = 256
+
+// cachedWeather caches the weather of places for weatherTTL.
+type cachedWeather struct {
+	provider weatherProvider
+	mu       sync.Mutex
+	entries  map[string]*weatherEntry
+}
+
+type weatherEntry struct {
+	weather *Weather
+	expires time.Time
+}
+
+func newCachedWeather(provider weatherProvider) *cachedWeather {
+	return &cachedWeather{provider: provider, entries: map[string]*weatherEntry{}}
+}
+
+func (c *cachedWeather) Current(ctx context.Context, location string) (*Weather, error) {
+	key := strings.ToLower(strings.TrimSpace(location))
+	now := time.Now()
+	c.mu.Lock()
+	entry := c.entries[key]
+	c.mu.Unlock()
+	if entry != nil && now.Before(entry.expires) {
+		return entry.weather, nil
+	}
+	weather, err := c.provider.Current(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxCachedPlaces {
+		for k, e := range c.entries {
+			if now.After(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxCachedPlaces {
+			c.entries = map[string]*weatherEntry{}
+		}
+	}
+	c.entries[key] = &weatherEntry{weather: weather, expires: now.Add(weatherTTL)}
+	return weather, nil
+}
+
+// openMeteo is the Open-Meteo provider: places are geocoded, then their
+// weather is forecast.
+type openMeteo struct {
+	client       *http.Client
+	geocodingURL string
+	forecastURL  string
+}
+
+func (p *openMeteo) Current(ctx context.Context, location string) (*Weather, error) {
+	var places struct {
+		Results []struct {
+			Name      string  `json:"name"`
+			Admin1    string  `json:"admin1"`
+			Country   string  `json:"country"`
+			Latitude  float64 `json:"latitude"`
+			Longitude float64 `json:"longitude"`
+		} `json:"results"`
+	}
+	query := url.Values{"name": {location}, "count": {"1"}, "format": {"json"}}
+	if err := getJSON(ctx, p.client, p.geocodingURL+"?"+query.Encode(), &places); err != nil {
+		return nil, err
+	}
+	if len(places.Results) == 0 {
+		return nil, fmt.Errorf("%w: no place named %q", ErrInvalid, location)
+	}
+	place := places.Results[0]
+
+	var forecast struct {
+		Current struct {
+			Temperature float64 `json:"temperature_2m"`
+			FeelsLike   float64 `json:"apparent_temperature"`
+			Humidity    int     `json:"relative_humidity_2m"`
+			Wind        float64 `json:"wind_speed_10m"`
+			Code        int     `json:"weather_code"`
+		} `json:"current"`
+		Daily struct {
+			Time          []string  `json:"time"`
+			Code          []int     `json:"weather_code"`
+			Max           []float64 `json:"temperature_2m_max"`
+			Min           []float64 `json:"temperature_2m_min"`
+			Precipitation []int     `json:"precipitation_probability_max"`
+		} `json:"daily"`
+	}
+	query = url.Values{
+		"latitude":      {strconv.FormatFloat(place.Latitude, 'f', 4, 64)},
+		"longitude":     {strconv.FormatFloat(place.Longitude, 'f', 4, 64)},
+		"current":       {"temperature_2m,relative_humidity_2m,apparent_temperature,weather_code,wind_speed_10m"},
+		"daily":         {"weather_code,temperature_2m_max,temperature_2m_min,precipitation_probability_max"},
+		"timezone":      {"auto"},
+		"forecast_days": {"3"},
+	}
+	if err := getJSON(ctx, p.client, p.forecastURL+"?"+query.Encode(), &forecast); err != nil {
+		return nil, err
+	}
+
+	names := []string{place.Name}
+	for _, name := range []string{place.Admin1, place.Country} {
+		if name != "" && name != names[len(names)-1] {
+			names = append(names, name)
+		}
+	}
+	weather := &Weather{
+		Location:    strings.Join(names, ", "),
+		Temperature: forecast.Current.Temperature,
+		FeelsLike:   forecast.Current.FeelsLike,
+		Humidity:    forecast.Current.Humidity,
+		WindKph:     forecast.Current.Wind,
+		Condition:   wmoCondition(forecast.Current.Code),
+	}
+	daily := forecast.Daily
+	for i, date := range daily.Time {
+		if i >= len(daily.Code) || i >= len(daily.Max) || i >= len(daily.Min) {
+			break
+		}
+		day := &DayForecast{Date: date, Condition: wmoCondition(daily.Code[i]), Min: daily.Min[i], Max: daily.Max[i]}
+		if i < len(daily.Precipitation) {
+			day.PrecipitationChance = daily.Precipitation[i]
+		}
+		weather.Forecast = append(weather.Forecast, day)
+	}
+	return weather, nil
+}
+
+// wmoConditions are the conditions of the WMO weather codes of Open-Meteo.
+var wmoConditions = map[int]string{
+	0: "clear sky", 1: "mainly clear", 2: "partly cloudy", 3: "overcast",
+	45: "fog", 48: "depositing rime fog",
+	51: "light drizzle", 53: "drizzle", 55: "dense drizzle", 56: "freezing drizzle", 57: "dense freezing drizzle",
+	61: "light rain", 63: "rain", 65: "heavy rain", 66: "freezing rain", 67: "heavy freezing rain",
+	71: "light snow", 73: "snow", 75: "heavy snow", 77: "snow grains",
+	80: "light rain showers", 81: "rain showers", 82: "violent rain showers",
+	85: "snow showers", 86: "heavy snow showers",
+	95: "thunderstorm", 96: "thunderstorm with hail", 99: "thunderstorm with heavy hail",
+}
+
+func wmoCondition(code int) string {
+	if condition, ok := wmoConditions[code]; ok {
+		return condition
+	}
+	return fmt.Sprintf("weather code %d", code)
+}
+
+// openWeatherMap is the OpenWeatherMap provider, which has no forecast on
+// its free current weather endpoint.
+type openWeatherMap struct {
+	client  *http.Client
+	apiKey  string
+	baseURL string
+}
+
+func (p *openWeatherMap) Current(ctx context.Context, location string) (*Weather, error) {
+	var current struct {
+		Name string `json:"name"`
+		Sys  struct {
+			Country string `json:"country"`
+		} `json:"sys"`
+		Main struct {
+			Temp      float64 `json:"temp"`
+			FeelsLike float64 `json:"feels_like"`
+			Humidity  int     `json:"humidity"`
+		} `json:"main"`
+		Weather []struct {
+			Description string `json:"description"`
+		} `json:"weather"`
+		Wind struct {
+			Speed float64 `json:"speed"`
+		} `json:"wind"`
+	}
+	query := url.Values{"q": {location}, "appid": {p.apiKey}, "units": {"metric"}}
+	err := getJSON(ctx, p.client, p.baseURL+"?"+query.Encode(), &current)
+	var status *statusError
+	if errors.As(err, &status) && status.code == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: no place named %q", ErrInvalid, location)
+	}
+	if err != nil {
+		return nil, err
+	}
+	weather := &Weather{
+		Location:    current.Name,
+		Temperature: current.Main.Temp,
+		FeelsLike:   current.Main.FeelsLike,
+		Humidity:    current.Main.Humidity,
+		// m/s
+		WindKph: math.Round(current.Wind.Speed*3.6*10) / 10,
+	}
+	if current.Sys.Country != "" {
+		weather.Location += ", " + current.Sys.Country
+	}
+	if len(current.Weather) > 0 {
+		weather.Condition = current.Weather[0].Description
+	}
+	return weather, nil
+}
+
+// statusError is the error of the unsuccessful responses of providers.
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("provider returned HTTP %d", e.code)
+}
+
+// maxResponseBytes bounds the responses of providers.
+const maxResponseBytes = 1 << 20
+
+// getJSON decodes the JSON response of a GET request.
+func getJSON(ctx context.Context, client *http.Client, rawURL string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("provider request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &statusError{code: resp.StatusCode}
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(v); err != nil {
+		return fmt.Errorf("invalid provider response: %w", err)
+	}
+	return nil
+}
+
+// formatWeather describes the weather for the tool.
+func formatWeather(w *Weather) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Weather in %s: %s, %.1f°C (feels like %.1f°C), humidity %d%%, wind %.1f km/h",
+		w.Location, w.Condition, w.Temperature, w.FeelsLike, w.Humidity, w.WindKph)
+	for _, day := range w.Forecast {
+		fmt.Fprintf(&b, "\n%s: %s, %.1f–%.1f°C, %d%% chance of precipitation",
+			day.Date, day.Condition, day.Min, day.Max, day.PrecipitationChance)
+	}
+	return b.String()
+}
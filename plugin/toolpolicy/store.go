@@ -0,0 +1,103 @@
+package toolpolicy
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/hrygo/divinesense/internal/util"
+)
+
+// DBStore persists policies in the geek_tool_policy table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new policy store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const policyColumns = "user_id, role, allowed_tools, disallowed_tools, updated_ts"
+
+// GetPolicy implements Store.
+func (s *DBStore) GetPolicy(ctx context.Context, userID int32, role string) (*Policy, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+policyColumns+" FROM geek_tool_policy WHERE user_id = $1 AND role = $2", userID, role)
+	policy, err := scanPolicy(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tool policy: %w", err)
+	}
+	return policy, nil
+}
+
+// ListPolicies implements Store.
+func (s *DBStore) ListPolicies(ctx context.Context) ([]*Policy, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+policyColumns+" FROM geek_tool_policy ORDER BY user_id, role = '' DESC, role")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tool policies: %w", err)
+	}
+	defer rows.Close()
+
+	policies := []*Policy{}
+	for rows.Next() {
+		policy, err := scanPolicy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tool policy: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tool policies: %w", err)
+	}
+	return policies, nil
+}
+
+// SavePolicy implements Store.
+func (s *DBStore) SavePolicy(ctx context.Context, policy *Policy) (*Policy, error) {
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO geek_tool_policy (user_id, role, allowed_tools, disallowed_tools, updated_ts)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, role) DO UPDATE SET
+			allowed_tools = EXCLUDED.allowed_tools,
+			disallowed_tools = EXCLUDED.disallowed_tools,
+			updated_ts = EXCLUDED.updated_ts
+		RETURNING `+policyColumns,
+		policy.UserID, policy.Role, pq.Array(util.OrEmpty(policy.Allowed)), pq.Array(util.OrEmpty(policy.Disallowed)), time.Now().Unix())
+	saved, err := scanPolicy(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save tool policy: %w", err)
+	}
+	return saved, nil
+}
+
+// DeletePolicy implements Store.
+func (s *DBStore) DeletePolicy(ctx context.Context, userID int32, role string) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM geek_tool_policy WHERE user_id = $1 AND role = $2", userID, role)
+	if err != nil {
+		return fmt.Errorf("failed to delete tool policy: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPolicy(row rowScanner) (*Policy, error) {
+	policy := &Policy{}
+	if err := row.Scan(&policy.UserID, &policy.Role, pq.Array(&policy.Allowed), pq.Array(&policy.Disallowed), &policy.UpdatedTs); err != nil {
+		return nil, err
+	}
+	policy.Allowed, policy.Disallowed = util.OrEmpty(policy.Allowed), util.OrEmpty(policy.Disallowed)
+	return policy, nil
+}
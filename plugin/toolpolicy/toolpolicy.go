@@ -0,0 +1,165 @@
+// Package toolpolicy holds the tools Geek Mode workspaces may use.
+//
+// Admins set a default policy for all the workspaces and, optionally,
+// policies for the user roles (HOST, ADMIN, USER) and for the workspace of a
+// user replacing it: the workspace of a user gets their own policy, else the
+// policy of their role, else the default one. A policy is an allowlist and a
+// denylist of Claude Code tools (Bash, Write, Edit, WebFetch…), which the CC
+// runner passes to the CLI; sessions using any other tool are stopped and
+// audited.
+package toolpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+)
+
+var (
+	// ErrNotFound is returned for workspaces without a policy.
+	ErrNotFound = errors.New("tool policy not found")
+	// ErrInvalid is returned for policies that fail validation.
+	ErrInvalid = errors.New("invalid tool policy")
+)
+
+// DefaultWorkspace is the user ID of the default policy.
+const DefaultWorkspace int32 = 0
+
+// maxRules is the number of tools a list may name.
+const maxRules = 100
+
+// toolPattern matches the tool names, including MCP tools (mcp__server__tool).
+var toolPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]{0,127}$`)
+
+// Roles are the user roles policies can be set for.
+var Roles = []string{"HOST", "ADMIN", "USER"}
+
+// Policy is the tool policy of a workspace, or of the workspaces of a role.
+type Policy struct {
+	// UserID is the owner of the workspace, DefaultWorkspace for the default
+	// and role policies.
+	UserID int32 `json:"user_id"`
+	// Role is the user role of a role policy, "" otherwise.
+	Role       string   `json:"role,omitempty"`
+	Allowed    []string `json:"allowed"`
+	Disallowed []string `json:"disallowed"`
+	UpdatedTs  int64    `json:"updated_ts"`
+}
+
+// Validate checks and normalizes a policy about to be saved.
+func (p *Policy) Validate() error {
+	var err error
+	if p.Allowed, err = normalizeTools(p.Allowed); err != nil {
+		return err
+	}
+	if p.Disallowed, err = normalizeTools(p.Disallowed); err != nil {
+		return err
+	}
+	for _, tool := range p.Allowed {
+		if slices.Contains(p.Disallowed, tool) {
+			return fmt.Errorf("%w: %s is both allowed and disallowed", ErrInvalid, tool)
+		}
+	}
+	return nil
+}
+
+// normalizeTools checks the names of a list of tools and drops duplicates.
+func normalizeTools(tools []string) ([]string, error) {
+	if len(tools) > maxRules {
+		return nil, fmt.Errorf("%w: at most %d tools per list", ErrInvalid, maxRules)
+	}
+	normalized := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		if !toolPattern.MatchString(tool) {
+			return nil, fmt.Errorf("%w: invalid tool name %q", ErrInvalid, tool)
+		}
+		if !slices.Contains(normalized, tool) {
+			normalized = append(normalized, tool)
+		}
+	}
+	return normalized, nil
+}
+
+// ToolPolicy returns the policy as applied by the CC runner.
+func (p *Policy) ToolPolicy() *agentpkg.ToolPolicy {
+	return &agentpkg.ToolPolicy{Allowed: p.Allowed, Disallowed: p.Disallowed}
+}
+
+// Store persists the policies, keyed by user ID and role.
+type Store interface {
+	// GetPolicy returns ErrNotFound for workspaces without a policy.
+	GetPolicy(ctx context.Context, userID int32, role string) (*Policy, error)
+	ListPolicies(ctx context.Context) ([]*Policy, error)
+	SavePolicy(ctx context.Context, policy *Policy) (*Policy, error)
+	// DeletePolicy returns ErrNotFound for workspaces without a policy.
+	DeletePolicy(ctx context.Context, userID int32, role string) error
+}
+
+// Policies manages the tool policies of the workspaces.
+type Policies struct {
+	store Store
+}
+
+// New creates the policies.
+func New(store Store) *Policies {
+	return &Policies{store: store}
+}
+
+// Resolve returns the policy applying to the workspace of a user with a role:
+// their own, their role's, or the default one. It returns nil if none is set.
+func (p *Policies) Resolve(ctx context.Context, userID int32, role string) (*agentpkg.ToolPolicy, error) {
+	workspaces := []Policy{{UserID: userID}, {UserID: DefaultWorkspace}}
+	if slices.Contains(Roles, role) {
+		workspaces = slices.Insert(workspaces, 1, Policy{UserID: DefaultWorkspace, Role: role})
+	}
+	for _, workspace := range workspaces {
+		policy, err := p.store.GetPolicy(ctx, workspace.UserID, workspace.Role)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return policy.ToolPolicy(), nil
+	}
+	return nil, nil
+}
+
+// List returns the policies, the default one first, then the role policies.
+func (p *Policies) List(ctx context.Context) ([]*Policy, error) {
+	return p.store.ListPolicies(ctx)
+}
+
+// Save validates and saves the policy of a workspace or a role.
+func (p *Policies) Save(ctx context.Context, policy *Policy) (*Policy, error) {
+	if policy.UserID < 0 {
+		return nil, fmt.Errorf("%w: invalid user id", ErrInvalid)
+	}
+	if policy.Role != "" && (policy.UserID != DefaultWorkspace || !slices.Contains(Roles, policy.Role)) {
+		return nil, fmt.Errorf("%w: role must be one of %v", ErrInvalid, Roles)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return p.store.SavePolicy(ctx, policy)
+}
+
+// Delete removes the policy of a workspace, which falls back to the policy of
+// its role or the default one; deleting the default one lifts the
+// restrictions.
+func (p *Policies) Delete(ctx context.Context, userID int32) error {
+	return p.store.DeletePolicy(ctx, userID, "")
+}
+
+// DeleteRole removes the policy of a role, whose workspaces fall back to the
+// default policy.
+func (p *Policies) DeleteRole(ctx context.Context, role string) error {
+	if !slices.Contains(Roles, role) {
+		return fmt.Errorf("%w: role must be one of %v", ErrInvalid, Roles)
+	}
+	return p.store.DeletePolicy(ctx, DefaultWorkspace, role)
+}
@@ -0,0 +1,132 @@
+package toolpolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+)
+
+type workspace struct {
+	userID int32
+	role   string
+}
+
+type fakeStore struct {
+	policies map[workspace]*Policy
+}
+
+func (s *fakeStore) GetPolicy(_ context.Context, userID int32, role string) (*Policy, error) {
+	policy, ok := s.policies[workspace{userID, role}]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return policy, nil
+}
+
+func (s *fakeStore) ListPolicies(_ context.Context) ([]*Policy, error) {
+	policies := []*Policy{}
+	for _, policy := range s.policies {
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+func (s *fakeStore) SavePolicy(_ context.Context, policy *Policy) (*Policy, error) {
+	s.policies[workspace{policy.UserID, policy.Role}] = policy
+	return policy, nil
+}
+
+func (s *fakeStore) DeletePolicy(_ context.Context, userID int32, role string) error {
+	if _, ok := s.policies[workspace{userID, role}]; !ok {
+		return ErrNotFound
+	}
+	delete(s.policies, workspace{userID, role})
+	return nil
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	policy := &Policy{Allowed: []string{"Read", "Grep", "Read", "mcp__github"}, Disallowed: []string{"Bash"}}
+	require.NoError(t, policy.Validate())
+	assert.Equal(t, []string{"Read", "Grep", "mcp__github"}, policy.Allowed)
+
+	for _, invalid := range []*Policy{
+		{Allowed: []string{"Bash(git:*)"}},
+		{Disallowed: []string{""}},
+		{Allowed: []string{"Bash"}, Disallowed: []string{"Bash"}},
+	} {
+		assert.ErrorIs(t, invalid.Validate(), ErrInvalid, "%+v", invalid)
+	}
+}
+
+func TestPolicies_Resolve(t *testing.T) {
+	ctx := context.Background()
+	policies := New(&fakeStore{policies: map[workspace]*Policy{}})
+
+	// No policy lifts the restrictions
+	resolved, err := policies.Resolve(ctx, 7, "USER")
+	require.NoError(t, err)
+	assert.Nil(t, resolved)
+
+	_, err = policies.Save(ctx, &Policy{UserID: DefaultWorkspace, Disallowed: []string{"WebFetch"}})
+	require.NoError(t, err)
+	_, err = policies.Save(ctx, &Policy{UserID: 7, Allowed: []string{"Read", "Edit"}})
+	require.NoError(t, err)
+
+	resolved, err = policies.Resolve(ctx, 7, "USER")
+	require.NoError(t, err)
+	assert.Equal(t, &agentpkg.ToolPolicy{Allowed: []string{"Read", "Edit"}, Disallowed: []string{}}, resolved,
+		"the workspace policy replaces the default one")
+
+	resolved, err = policies.Resolve(ctx, 8, "USER")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"WebFetch"}, resolved.Disallowed)
+
+	require.NoError(t, policies.Delete(ctx, 7))
+	assert.ErrorIs(t, policies.Delete(ctx, 7), ErrNotFound)
+	resolved, err = policies.Resolve(ctx, 7, "USER")
+	require.NoError(t, err)
+	assert.False(t, resolved.Permits("WebFetch"))
+
+	_, err = policies.Save(ctx, &Policy{UserID: -1})
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestPolicies_ResolveRole(t *testing.T) {
+	ctx := context.Background()
+	policies := New(&fakeStore{policies: map[workspace]*Policy{}})
+
+	_, err := policies.Save(ctx, &Policy{UserID: DefaultWorkspace, Disallowed: []string{"WebFetch"}})
+	require.NoError(t, err)
+	_, err = policies.Save(ctx, &Policy{UserID: DefaultWorkspace, Role: "USER", Allowed: []string{"Read", "Grep"}})
+	require.NoError(t, err)
+	_, err = policies.Save(ctx, &Policy{UserID: 7, Allowed: []string{"Read", "Bash"}})
+	require.NoError(t, err)
+
+	resolved, err := policies.Resolve(ctx, 8, "USER")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Read", "Grep"}, resolved.Allowed, "the role policy replaces the default one")
+	resolved, err = policies.Resolve(ctx, 7, "USER")
+	require.NoError(t, err)
+	assert.True(t, resolved.Permits("Bash"), "the workspace policy replaces the role one")
+	resolved, err = policies.Resolve(ctx, 9, "ADMIN")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"WebFetch"}, resolved.Disallowed, "roles without a policy get the default one")
+
+	require.NoError(t, policies.DeleteRole(ctx, "USER"))
+	resolved, err = policies.Resolve(ctx, 8, "USER")
+	require.NoError(t, err)
+	assert.Empty(t, resolved.Allowed)
+
+	for _, invalid := range []*Policy{
+		{Role: "GUEST"},
+		{UserID: 7, Role: "USER"},
+	} {
+		_, err = policies.Save(ctx, invalid)
+		assert.ErrorIs(t, err, ErrInvalid, "%+v", invalid)
+	}
+	assert.ErrorIs(t, policies.DeleteRole(ctx, "GUEST"), ErrInvalid)
+}
@@ -0,0 +1,86 @@
+package usagestats
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DBStore reads samples from the memo, ai_block and agent_session_stats
+// tables (PostgreSQL). It only runs SELECT statements.
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new sample store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// sampleQueries select the samples of each metric as (bucket, agent, count,
+// input_tokens, output_tokens, session_tokens, session_cost_usd), for user
+// $1 between $2 (included) and $3 (excluded), in buckets of $4 seconds.
+// Chat blocks have millisecond timestamps; geek and evolution blocks are
+// counted from their sessions.
+var sampleQueries = map[Metric]string{
+	MetricMemos: `
+		SELECT created_ts - created_ts % $4 AS bucket, '', COUNT(*), 0, 0, 0, 0
+		FROM memo
+		WHERE creator_id = $1 AND created_ts >= $2 AND created_ts < $3
+		GROUP BY bucket`,
+	MetricChats: `
+		SELECT b.created_ts / 1000 - b.created_ts / 1000 % $4 AS bucket,
+			CASE WHEN b.mode = 'normal' THEN c.parrot_id ELSE b.mode END AS agent,
+			COUNT(*), 0, 0, 0, 0
+		FROM ai_block b
+		JOIN ai_conversation c ON c.id = b.conversation_id
+		WHERE c.creator_id = $1 AND b.block_type = 'message'
+			AND b.created_ts >= $2::BIGINT * 1000 AND b.created_ts < $3::BIGINT * 1000
+		GROUP BY bucket, agent`,
+	MetricAICost:   aiUsageQuery,
+	MetricAITokens: aiUsageQuery,
+}
+
+const aiUsageQuery = `
+		SELECT b.created_ts / 1000 - b.created_ts / 1000 % $4 AS bucket, c.parrot_id, COUNT(*),
+			COALESCE(SUM((b.token_usage->>'prompt_tokens')::BIGINT), 0),
+			COALESCE(SUM((b.token_usage->>'completion_tokens')::BIGINT), 0),
+			0, 0
+		FROM ai_block b
+		JOIN ai_conversation c ON c.id = b.conversation_id
+		WHERE c.creator_id = $1 AND b.block_type = 'message' AND b.mode = 'normal'
+			AND b.created_ts >= $2::BIGINT * 1000 AND b.created_ts < $3::BIGINT * 1000
+		GROUP BY bucket, c.parrot_id
+		UNION ALL
+		SELECT ts - ts % $4 AS bucket, agent_type, COUNT(*), 0, 0,
+			COALESCE(SUM(total_tokens), 0), COALESCE(SUM(total_cost_usd), 0)::FLOAT8
+		FROM (
+			SELECT EXTRACT(EPOCH FROM started_at)::BIGINT AS ts, agent_type, total_tokens, total_cost_usd
+			FROM agent_session_stats
+			WHERE user_id = $1 AND started_at >= TO_TIMESTAMP($2::BIGINT) AND started_at < TO_TIMESTAMP($3::BIGINT)
+		) s
+		GROUP BY bucket, agent_type`
+
+// Samples implements Store.
+func (s *DBStore) Samples(ctx context.Context, userID int32, metric Metric, start, end int64) ([]Sample, error) {
+	query, ok := sampleQueries[metric]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown metric %q", ErrInvalidQuery, metric)
+	}
+	rows, err := s.db.QueryContext(ctx, query, userID, start, end, BucketSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s samples: %w", metric, err)
+	}
+	defer rows.Close()
+
+	var samples []Sample
+	for rows.Next() {
+		var sample Sample
+		if err := rows.Scan(&sample.Ts, &sample.Agent, &sample.Count, &sample.InputTokens, &sample.OutputTokens,
+			&sample.SessionTokens, &sample.SessionCostUSD); err != nil {
+			return nil, fmt.Errorf("failed to scan %s sample: %w", metric, err)
+		}
+		samples = append(samples, sample)
+	}
+	return samples, rows.Err()
+}
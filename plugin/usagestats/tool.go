@@ -0,0 +1,109 @@
+package usagestats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	agents "github.com/hrygo/divinesense/ai/agents"
+)
+
+// ToolName is the name of the usage statistics tool of agents.
+const ToolName = "usage_stats"
+
+// ToolInput is the input of the usage_stats tool.
+type ToolInput struct {
+	Metric  string `json:"metric"`
+	GroupBy string `json:"group_by"`
+	// Start and End are dates (YYYY-MM-DD), both included.
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Days covers the last days when Start is empty.
+	Days     int    `json:"days"`
+	Timezone string `json:"timezone"`
+}
+
+// Query converts the input into a query of a user.
+func (in *ToolInput) Query(userID int32, now time.Time) (*Query, error) {
+	loc := time.Local
+	if in.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(in.Timezone); err != nil {
+			return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidQuery, in.Timezone)
+		}
+	}
+	q := &Query{UserID: userID, Metric: Metric(in.Metric), GroupBy: GroupBy(in.GroupBy), Location: loc}
+	if in.End != "" {
+		end, err := time.ParseInLocation(time.DateOnly, in.End, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: end must be a date (YYYY-MM-DD)", ErrInvalidQuery)
+		}
+		q.End = end.AddDate(0, 0, 1).Unix()
+	} else {
+		q.End = now.Unix()
+	}
+	switch {
+	case in.Start != "":
+		start, err := time.ParseInLocation(time.DateOnly, in.Start, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: start must be a date (YYYY-MM-DD)", ErrInvalidQuery)
+		}
+		q.Start = start.Unix()
+	case in.Days > 0:
+		q.Start = time.Unix(q.End, 0).AddDate(0, 0, -in.Days).Unix()
+	}
+	return q, nil
+}
+
+// ToolFor returns the usage_stats tool of a user, or nil without stats.
+func (s *Stats) ToolFor(userID int32) agents.ToolWithSchema {
+	if s == nil {
+		return nil
+	}
+	return agents.NewNativeTool(
+		ToolName,
+		"Compute statistics of the user's own usage: AI spending (ai_cost, USD), AI tokens (ai_tokens), "+
+			"chat rounds (chats) or memos created (memos), grouped by day, week, month, weekday, hour or agent. "+
+			"Returns a chart-ready dataset of labeled points and a total. "+
+			`Input: {"metric": "ai_cost", "group_by": "day", "start": "2026-09-01", "end": "2026-09-30"}.`,
+		func(ctx context.Context, input string) (string, error) {
+			var in ToolInput
+			if err := json.Unmarshal([]byte(input), &in); err != nil {
+				return "", fmt.Errorf("invalid input: %w", err)
+			}
+			q, err := in.Query(userID, time.Now())
+			if err != nil {
+				return "", err
+			}
+			dataset, err := s.Query(ctx, q)
+			if err != nil {
+				return "", err
+			}
+			data, err := json.Marshal(dataset)
+			if err != nil {
+				return "", fmt.Errorf("encode dataset: %w", err)
+			}
+			return DataMarker + " " + string(data), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"metric": map[string]any{
+					"type": "string",
+					"enum": []string{string(MetricAICost), string(MetricAITokens), string(MetricChats), string(MetricMemos)},
+				},
+				"group_by": map[string]any{
+					"type":        "string",
+					"enum":        []string{"day", "week", "month", "weekday", "hour", "agent"},
+					"description": "Grouping of the points (default day); agent is for AI metrics only",
+				},
+				"start":    map[string]any{"type": "string", "description": "First date included, YYYY-MM-DD"},
+				"end":      map[string]any{"type": "string", "description": "Last date included, YYYY-MM-DD (default today)"},
+				"days":     map[string]any{"type": "integer", "description": "Number of past days when start is empty (default 30)"},
+				"timezone": map[string]any{"type": "string", "description": "IANA time zone of the dates, e.g. Asia/Shanghai"},
+			},
+			"required": []string{"metric"},
+		},
+	)
+}
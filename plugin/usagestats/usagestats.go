@@ -0,0 +1,343 @@
+// Package usagestats answers questions about a user's own usage: what they
+// spent on AI, how many chats they had and when they write notes.
+//
+// Statistics are computed from read-only queries over the memo, ai_block and
+// agent_session_stats tables, bucketed so that they can be grouped by day,
+// weekday or hour in the user's time zone. The results are datasets ready to
+// be charted by the client.
+package usagestats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Metric is a measured quantity.
+type Metric string
+
+const (
+	// MetricAICost is the AI spending in US dollars: the cost reported by
+	// geek and evolution sessions, plus the chat tokens at the default pricing.
+	MetricAICost Metric = "ai_cost"
+	// MetricAITokens is the number of input and output tokens.
+	MetricAITokens Metric = "ai_tokens"
+	// MetricChats is the number of chat rounds.
+	MetricChats Metric = "chats"
+	// MetricMemos is the number of memos created.
+	MetricMemos Metric = "memos"
+)
+
+// Metrics are all the metrics, in display order.
+var Metrics = []Metric{MetricAICost, MetricAITokens, MetricChats, MetricMemos}
+
+// GroupBy is how samples are grouped into the points of a dataset.
+type GroupBy string
+
+const (
+	GroupByDay     GroupBy = "day"
+	GroupByWeek    GroupBy = "week"
+	GroupByMonth   GroupBy = "month"
+	GroupByWeekday GroupBy = "weekday"
+	GroupByHour    GroupBy = "hour"
+	// GroupByAgent groups AI usage by agent: memo, schedule, geek, etc.
+	GroupByAgent GroupBy = "agent"
+)
+
+var groupBys = []GroupBy{GroupByDay, GroupByWeek, GroupByMonth, GroupByWeekday, GroupByHour, GroupByAgent}
+
+// BucketSeconds is the width of the buckets samples are aggregated in. Time
+// zone offsets are multiples of it, so buckets never straddle two hours.
+const BucketSeconds = 15 * 60
+
+const (
+	defaultDays = 30
+	// maxRange bounds the time range of a query.
+	maxRange = 2 * 366 * 24 * time.Hour
+)
+
+// ErrInvalidQuery is returned for unknown metrics or groupings, and time
+// ranges out of bounds.
+var ErrInvalidQuery = errors.New("invalid usage query")
+
+// Query selects a dataset.
+type Query struct {
+	UserID  int32
+	Metric  Metric
+	GroupBy GroupBy
+	// Start and End bound the sample time, End excluded. End defaults to
+	// now and Start to 30 days before End.
+	Start int64
+	End   int64
+	// Location groups the samples; defaults to time.Local.
+	Location *time.Location
+}
+
+// Normalize validates a query and applies the defaults.
+func (q *Query) Normalize() error {
+	if !slices.Contains(Metrics, q.Metric) {
+		return fmt.Errorf("%w: unknown metric %q", ErrInvalidQuery, q.Metric)
+	}
+	if q.GroupBy == "" {
+		q.GroupBy = GroupByDay
+	}
+	if !slices.Contains(groupBys, q.GroupBy) {
+		return fmt.Errorf("%w: unknown grouping %q", ErrInvalidQuery, q.GroupBy)
+	}
+	if q.GroupBy == GroupByAgent && q.Metric == MetricMemos {
+		return fmt.Errorf("%w: memos cannot be grouped by agent", ErrInvalidQuery)
+	}
+	if q.End <= 0 {
+		q.End = time.Now().Unix()
+	}
+	if q.Start <= 0 {
+		q.Start = time.Unix(q.End, 0).AddDate(0, 0, -defaultDays).Unix()
+	}
+	if q.Start >= q.End {
+		return fmt.Errorf("%w: start must be before end", ErrInvalidQuery)
+	}
+	if time.Duration(q.End-q.Start)*time.Second > maxRange {
+		return fmt.Errorf("%w: range must be at most two years", ErrInvalidQuery)
+	}
+	if q.Location == nil {
+		q.Location = time.Local
+	}
+	return nil
+}
+
+// Sample is the usage of a user in a bucket, by agent for AI usage.
+type Sample struct {
+	// Ts is the start of the bucket, a multiple of BucketSeconds.
+	Ts    int64
+	Agent string
+	Count int64
+	// InputTokens and OutputTokens are the tokens of chats, priced by the
+	// Pricer of the stats.
+	InputTokens  int64
+	OutputTokens int64
+	// SessionTokens and SessionCostUSD are the tokens and the reported cost
+	// of geek and evolution sessions.
+	SessionTokens  int64
+	SessionCostUSD float64
+}
+
+// Store reads the samples of a metric.
+type Store interface {
+	Samples(ctx context.Context, userID int32, metric Metric, start, end int64) ([]Sample, error)
+}
+
+// Pricer prices chat tokens in US dollars.
+type Pricer func(inputTokens, outputTokens int) float64
+
+// Point is a value of a dataset.
+type Point struct {
+	Label string  `json:"label"`
+	Value float64 `json:"value"`
+}
+
+// Dataset is a metric grouped into points, ready to be charted.
+type Dataset struct {
+	Metric  Metric  `json:"metric"`
+	Unit    string  `json:"unit"`
+	GroupBy GroupBy `json:"group_by"`
+	// Chart suggests a chart type: line for time series, bar otherwise.
+	Chart    string  `json:"chart"`
+	Start    string  `json:"start"`
+	End      string  `json:"end"`
+	Timezone string  `json:"timezone"`
+	Points   []Point `json:"points"`
+	Total    float64 `json:"total"`
+}
+
+// Stats computes the usage statistics of users.
+type Stats struct {
+	store Store
+	price Pricer
+}
+
+// New creates the stats.
+func New(store Store, price Pricer) *Stats {
+	return &Stats{store: store, price: price}
+}
+
+// Query returns the dataset of a query.
+func (s *Stats) Query(ctx context.Context, q *Query) (*Dataset, error) {
+	if err := q.Normalize(); err != nil {
+		return nil, err
+	}
+	samples, err := s.store.Samples(ctx, q.UserID, q.Metric, q.Start, q.End)
+	if err != nil {
+		return nil, err
+	}
+
+	dataset := &Dataset{
+		Metric:   q.Metric,
+		Unit:     unit(q.Metric),
+		GroupBy:  q.GroupBy,
+		Chart:    "bar",
+		Start:    time.Unix(q.Start, 0).In(q.Location).Format(time.RFC3339),
+		End:      time.Unix(q.End, 0).In(q.Location).Format(time.RFC3339),
+		Timezone: q.Location.String(),
+	}
+	labels := axis(q)
+	if q.GroupBy == GroupByDay || q.GroupBy == GroupByWeek || q.GroupBy == GroupByMonth {
+		dataset.Chart = "line"
+	}
+	values := make(map[string]float64, len(labels))
+	for _, sample := range samples {
+		values[label(q, sample)] += s.value(q.Metric, sample)
+	}
+	if q.GroupBy == GroupByAgent {
+		for agent := range values {
+			labels = append(labels, agent)
+		}
+		sort.Slice(labels, func(i, j int) bool {
+			if values[labels[i]] != values[labels[j]] {
+				return values[labels[i]] > values[labels[j]]
+			}
+			return labels[i] < labels[j]
+		})
+	}
+	dataset.Points = make([]Point, 0, len(labels))
+	for _, label := range labels {
+		value := round(q.Metric, values[label])
+		dataset.Points = append(dataset.Points, Point{Label: label, Value: value})
+		dataset.Total += value
+	}
+	dataset.Total = round(q.Metric, dataset.Total)
+	return dataset, nil
+}
+
+// value is the value of a sample for a metric.
+func (s *Stats) value(metric Metric, sample Sample) float64 {
+	switch metric {
+	case MetricAICost:
+		cost := sample.SessionCostUSD
+		if s.price != nil {
+			cost += s.price(int(sample.InputTokens), int(sample.OutputTokens))
+		}
+		return cost
+	case MetricAITokens:
+		return float64(sample.InputTokens + sample.OutputTokens + sample.SessionTokens)
+	default:
+		return float64(sample.Count)
+	}
+}
+
+func unit(metric Metric) string {
+	switch metric {
+	case MetricAICost:
+		return "USD"
+	case MetricAITokens:
+		return "tokens"
+	case MetricChats:
+		return "chats"
+	default:
+		return "memos"
+	}
+}
+
+// round keeps cents of a dollar, and whole counts.
+func round(metric Metric, value float64) float64 {
+	if metric == MetricAICost {
+		return math.Round(value*100) / 100
+	}
+	return math.Round(value)
+}
+
+var weekdays = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// axis returns the labels of all the points of a query, so that charts show
+// the periods without usage; nil when grouping by agent.
+func axis(q *Query) []string {
+	switch q.GroupBy {
+	case GroupByWeekday:
+		return slices.Clone(weekdays)
+	case GroupByHour:
+		labels := make([]string, 24)
+		for hour := range labels {
+			labels[hour] = fmt.Sprintf("%02d", hour)
+		}
+		return labels
+	case GroupByAgent:
+		return nil
+	}
+	var labels []string
+	end := time.Unix(q.End-1, 0).In(q.Location)
+	for t := periodStart(q.GroupBy, time.Unix(q.Start, 0).In(q.Location)); !t.After(end); t = nextPeriod(q.GroupBy, t) {
+		labels = append(labels, periodLabel(q.GroupBy, t))
+	}
+	return labels
+}
+
+// label returns the label of the point a sample belongs to.
+func label(q *Query, sample Sample) string {
+	t := time.Unix(sample.Ts, 0).In(q.Location)
+	switch q.GroupBy {
+	case GroupByWeekday:
+		return weekdays[(int(t.Weekday())+6)%7]
+	case GroupByHour:
+		return fmt.Sprintf("%02d", t.Hour())
+	case GroupByAgent:
+		if sample.Agent == "" {
+			return "auto"
+		}
+		return strings.ToLower(sample.Agent)
+	}
+	return periodLabel(q.GroupBy, periodStart(q.GroupBy, t))
+}
+
+// periodStart returns the start of the day, week (Monday) or month of t.
+func periodStart(groupBy GroupBy, t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch groupBy {
+	case GroupByWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case GroupByMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+	return day
+}
+
+func nextPeriod(groupBy GroupBy, t time.Time) time.Time {
+	switch groupBy {
+	case GroupByWeek:
+		return t.AddDate(0, 0, 7)
+	case GroupByMonth:
+		return t.AddDate(0, 1, 0)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// periodLabel labels days and weeks by their first day, months as YYYY-MM.
+func periodLabel(groupBy GroupBy, start time.Time) string {
+	if groupBy == GroupByMonth {
+		return start.Format("2006-01")
+	}
+	return start.Format("2006-01-02")
+}
+
+// DataMarker prefixes the result of the usage_stats tool. The chat handler
+// detects it and streams the dataset as a stats_data event for the client to
+// chart.
+const DataMarker = "STATS_DATA:"
+
+// ParseDataset extracts the dataset from a usage_stats tool result.
+// Returns false if the result does not carry a dataset.
+func ParseDataset(toolResult string) (*Dataset, bool) {
+	idx := strings.Index(toolResult, DataMarker)
+	if idx < 0 {
+		return nil, false
+	}
+	var dataset Dataset
+	if err := json.NewDecoder(strings.NewReader(toolResult[idx+len(DataMarker):])).Decode(&dataset); err != nil {
+		return nil, false
+	}
+	return &dataset, true
+}
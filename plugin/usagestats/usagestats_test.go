@@ -0,0 +1,140 @@
+package usagestats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	samples []Sample
+	metric  Metric
+}
+
+func (s *fakeStore) Samples(_ context.Context, _ int32, metric Metric, start, end int64) ([]Sample, error) {
+	s.metric = metric
+	var samples []Sample
+	for _, sample := range s.samples {
+		if sample.Ts >= start && sample.Ts < end {
+			samples = append(samples, sample)
+		}
+	}
+	return samples, nil
+}
+
+var shanghai = time.FixedZone("Asia/Shanghai", 8*3600)
+
+func at(month time.Month, day, hour int) int64 {
+	return time.Date(2026, month, day, hour, 0, 0, 0, shanghai).Unix()
+}
+
+func TestQueryNormalize(t *testing.T) {
+	q := &Query{Metric: MetricMemos, End: at(10, 16, 0)}
+	require.NoError(t, q.Normalize())
+	assert.Equal(t, GroupByDay, q.GroupBy)
+	assert.Equal(t, at(9, 16, 0), q.Start)
+	assert.Equal(t, time.Local, q.Location)
+
+	for _, invalid := range []*Query{
+		{Metric: "cpu"},
+		{Metric: MetricMemos, GroupBy: "year"},
+		{Metric: MetricMemos, GroupBy: GroupByAgent},
+		{Metric: MetricChats, Start: 20, End: 10},
+		{Metric: MetricChats, Start: 1, End: at(10, 16, 0)},
+	} {
+		assert.ErrorIs(t, invalid.Normalize(), ErrInvalidQuery)
+	}
+}
+
+func TestQueryByDay(t *testing.T) {
+	store := &fakeStore{samples: []Sample{
+		{Ts: at(9, 1, 10), Agent: "MEMO", InputTokens: 1_000_000},
+		{Ts: at(9, 1, 23), Agent: "geek", SessionCostUSD: 1.234},
+		{Ts: at(9, 3, 0), Agent: "evolution", SessionCostUSD: 0.5},
+	}}
+	stats := New(store, func(in, out int) float64 { return float64(in+out) / 1_000_000 })
+
+	dataset, err := stats.Query(context.Background(), &Query{
+		Metric: MetricAICost, Start: at(9, 1, 0), End: at(9, 4, 0), Location: shanghai,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, MetricAICost, store.metric)
+	assert.Equal(t, "USD", dataset.Unit)
+	assert.Equal(t, "line", dataset.Chart)
+	assert.Equal(t, "2026-09-01T00:00:00+08:00", dataset.Start)
+	assert.Equal(t, []Point{
+		{Label: "2026-09-01", Value: 2.23},
+		{Label: "2026-09-02", Value: 0},
+		{Label: "2026-09-03", Value: 0.5},
+	}, dataset.Points)
+	assert.Equal(t, 2.73, dataset.Total)
+
+	dataset, err = stats.Query(context.Background(), &Query{
+		Metric: MetricAICost, GroupBy: GroupByAgent, Start: at(9, 1, 0), End: at(9, 4, 0), Location: shanghai,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "bar", dataset.Chart)
+	assert.Equal(t, []Point{{Label: "geek", Value: 1.23}, {Label: "memo", Value: 1}, {Label: "evolution", Value: 0.5}}, dataset.Points)
+}
+
+func TestQueryByWeekdayAndMonth(t *testing.T) {
+	store := &fakeStore{samples: []Sample{
+		// 2026-10-12 is a Monday, 2026-10-18 a Sunday.
+		{Ts: at(10, 12, 9), Count: 3},
+		{Ts: at(10, 18, 22), Count: 1},
+		{Ts: at(10, 19, 8), Count: 2},
+		{Ts: at(11, 2, 8), Count: 5},
+	}}
+	stats := New(store, nil)
+
+	dataset, err := stats.Query(context.Background(), &Query{
+		Metric: MetricMemos, GroupBy: GroupByWeekday, Start: at(10, 1, 0), End: at(11, 30, 0), Location: shanghai,
+	})
+	require.NoError(t, err)
+	require.Len(t, dataset.Points, 7)
+	assert.Equal(t, Point{Label: "Mon", Value: 10}, dataset.Points[0])
+	assert.Equal(t, Point{Label: "Sun", Value: 1}, dataset.Points[6])
+	assert.Equal(t, float64(11), dataset.Total)
+
+	dataset, err = stats.Query(context.Background(), &Query{
+		Metric: MetricMemos, GroupBy: GroupByMonth, Start: at(10, 1, 0), End: at(11, 30, 0), Location: shanghai,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []Point{{Label: "2026-10", Value: 6}, {Label: "2026-11", Value: 5}}, dataset.Points)
+
+	dataset, err = stats.Query(context.Background(), &Query{
+		Metric: MetricMemos, GroupBy: GroupByWeek, Start: at(10, 14, 0), End: at(10, 20, 0), Location: shanghai,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []Point{{Label: "2026-10-12", Value: 1}, {Label: "2026-10-19", Value: 2}}, dataset.Points)
+}
+
+func TestToolInputQuery(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	in := &ToolInput{Metric: "ai_cost", Start: "2026-09-01", End: "2026-09-30", Timezone: "Asia/Shanghai"}
+	q, err := in.Query(7, now)
+	require.NoError(t, err)
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	assert.Equal(t, time.Date(2026, 9, 1, 0, 0, 0, 0, loc).Unix(), q.Start)
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, loc).Unix(), q.End)
+
+	q, err = (&ToolInput{Metric: "memos", Days: 7}).Query(7, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, -7).Unix(), q.Start)
+
+	_, err = (&ToolInput{Metric: "memos", Start: "last month"}).Query(7, now)
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestParseDataset(t *testing.T) {
+	dataset, ok := ParseDataset(`STATS_DATA: {"metric": "memos", "points": [{"label": "Mon", "value": 3}], "total": 3}`)
+	require.True(t, ok)
+	assert.Equal(t, MetricMemos, dataset.Metric)
+	assert.Equal(t, []Point{{Label: "Mon", Value: 3}}, dataset.Points)
+
+	_, ok = ParseDataset("Found 3 memos")
+	assert.False(t, ok)
+}
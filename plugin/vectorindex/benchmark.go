@@ -0,0 +1,126 @@
+package vectorindex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// BenchmarkOptions configures a benchmark.
+type BenchmarkOptions struct {
+	// Model of the embeddings to search.
+	Model string
+	// Queries is the number of sampled embeddings searched for.
+	Queries int
+	// K is the number of neighbours of the recall.
+	K int
+	// Values of the search-time parameter to measure; DefaultValues if empty.
+	Values []int
+}
+
+// DefaultValues returns the search-time values measured by default.
+func DefaultValues(indexType string) []int {
+	if indexType == TypeIVFFlat {
+		return []int{1, 5, 10, 20, 50}
+	}
+	return []int{20, 40, 80, 160, 320}
+}
+
+// Measurement is the recall and latency of a value of the search-time parameter.
+type Measurement struct {
+	Value      int           `json:"value"`
+	Recall     float64       `json:"recall"`
+	AvgLatency time.Duration `json:"avg_latency"`
+	P95Latency time.Duration `json:"p95_latency"`
+}
+
+// BenchmarkResult compares the values of the search-time parameter with an
+// exact search.
+type BenchmarkResult struct {
+	Param   string         `json:"param"`
+	K       int            `json:"k"`
+	Queries int            `json:"queries"`
+	Exact   *Measurement   `json:"exact"`
+	Values  []*Measurement `json:"values"`
+}
+
+// Benchmark searches for sampled embeddings with each value of the
+// search-time parameter, measuring the recall@K against an exact search.
+func (s *Service) Benchmark(ctx context.Context, opts BenchmarkOptions) (*BenchmarkResult, error) {
+	if opts.Queries <= 0 || opts.K <= 0 {
+		return nil, fmt.Errorf("%w: queries and k must be positive", ErrInvalid)
+	}
+	values := opts.Values
+	if len(values) == 0 {
+		values = DefaultValues(s.config.Type)
+	}
+	queries, err := s.store.SampleEmbeddings(ctx, opts.Model, opts.Queries)
+	if err != nil {
+		return nil, err
+	}
+	if len(queries) == 0 {
+		return nil, errors.New("no embeddings to benchmark: embed the memos first")
+	}
+
+	param := s.config.SearchParam()
+	result := &BenchmarkResult{Param: param, K: opts.K, Queries: len(queries)}
+	truth := make([][]int32, len(queries))
+	var latencies []time.Duration
+	for i, vector := range queries {
+		start := time.Now()
+		ids, err := s.store.Search(ctx, &SearchQuery{Model: opts.Model, Vector: vector, K: opts.K, Exact: true})
+		if err != nil {
+			return nil, err
+		}
+		latencies = append(latencies, time.Since(start))
+		truth[i] = ids
+	}
+	result.Exact = measure(0, 1, latencies)
+
+	for _, value := range values {
+		latencies = latencies[:0]
+		recall := 0.0
+		for i, vector := range queries {
+			start := time.Now()
+			ids, err := s.store.Search(ctx, &SearchQuery{Model: opts.Model, Vector: vector, K: opts.K, Param: param, Value: value})
+			if err != nil {
+				return nil, err
+			}
+			latencies = append(latencies, time.Since(start))
+			recall += recallOf(ids, truth[i])
+		}
+		result.Values = append(result.Values, measure(value, recall/float64(len(queries)), latencies))
+	}
+	return result, nil
+}
+
+// recallOf returns the share of the exact neighbours found.
+func recallOf(found, exact []int32) float64 {
+	if len(exact) == 0 {
+		return 1
+	}
+	hits := 0
+	for _, id := range exact {
+		if slices.Contains(found, id) {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(exact))
+}
+
+func measure(value int, recall float64, latencies []time.Duration) *Measurement {
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+	var total time.Duration
+	for _, latency := range sorted {
+		total += latency
+	}
+	return &Measurement{
+		Value:      value,
+		Recall:     recall,
+		AvgLatency: total / time.Duration(len(sorted)),
+		P95Latency: sorted[(len(sorted)*95+99)/100-1],
+	}
+}
@@ -0,0 +1,172 @@
+package vectorindex
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/pgvector/pgvector-go"
+)
+
+// DBStore maintains the vector indexes of the memo_embedding table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new vector index store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// ListIndexes implements Store.
+func (s *DBStore) ListIndexes(ctx context.Context) ([]*IndexInfo, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT c.relname, pg_get_indexdef(c.oid), pg_relation_size(c.oid), i.indisvalid
+		FROM pg_index i
+		JOIN pg_class c ON c.oid = i.indexrelid
+		JOIN pg_am am ON am.oid = c.relam
+		WHERE i.indrelid = 'memo_embedding'::regclass AND am.amname IN ('hnsw', 'ivfflat')
+		ORDER BY c.relname`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vector indexes: %w", err)
+	}
+	defer rows.Close()
+
+	indexes := []*IndexInfo{}
+	for rows.Next() {
+		index := &IndexInfo{}
+		if err := rows.Scan(&index.Name, &index.Definition, &index.SizeBytes, &index.Valid); err != nil {
+			return nil, fmt.Errorf("failed to scan vector index: %w", err)
+		}
+		indexes = append(indexes, index)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list vector indexes: %w", err)
+	}
+	return indexes, nil
+}
+
+// CountEmbeddings implements Store.
+func (s *DBStore) CountEmbeddings(ctx context.Context) (int64, error) {
+	var n int64
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM memo_embedding").Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count memo embeddings: %w", err)
+	}
+	return n, nil
+}
+
+// LastAnalyzed implements Store.
+func (s *DBStore) LastAnalyzed(ctx context.Context) (int64, error) {
+	var analyzed int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COALESCE(EXTRACT(EPOCH FROM GREATEST(last_analyze, last_autoanalyze))::BIGINT, 0)
+		FROM pg_stat_user_tables
+		WHERE relid = 'memo_embedding'::regclass`).Scan(&analyzed)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get memo embedding statistics: %w", err)
+	}
+	return analyzed, nil
+}
+
+// CreateIndex implements Store.
+func (s *DBStore) CreateIndex(ctx context.Context, name string, config Config) error {
+	stmt := fmt.Sprintf("CREATE INDEX CONCURRENTLY %s ON memo_embedding USING %s (embedding vector_cosine_ops) %s",
+		pq.QuoteIdentifier(name), config.Type, config.WithClause())
+	if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+		return fmt.Errorf("failed to create vector index: %w", err)
+	}
+	return nil
+}
+
+// DropIndex implements Store.
+func (s *DBStore) DropIndex(ctx context.Context, name string) error {
+	if _, err := s.db.ExecContext(ctx, "DROP INDEX CONCURRENTLY IF EXISTS "+pq.QuoteIdentifier(name)); err != nil {
+		return fmt.Errorf("failed to drop vector index: %w", err)
+	}
+	return nil
+}
+
+// RenameIndex implements Store.
+func (s *DBStore) RenameIndex(ctx context.Context, name, newName string) error {
+	stmt := fmt.Sprintf("ALTER INDEX %s RENAME TO %s", pq.QuoteIdentifier(name), pq.QuoteIdentifier(newName))
+	if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+		return fmt.Errorf("failed to rename vector index: %w", err)
+	}
+	return nil
+}
+
+// Analyze implements Store.
+func (s *DBStore) Analyze(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, "ANALYZE memo_embedding"); err != nil {
+		return fmt.Errorf("failed to analyze memo embeddings: %w", err)
+	}
+	return nil
+}
+
+// SampleEmbeddings implements Store.
+func (s *DBStore) SampleEmbeddings(ctx context.Context, model string, n int) ([][]float32, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT embedding FROM memo_embedding WHERE model = $1 ORDER BY random() LIMIT $2", model, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample memo embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	vectors := [][]float32{}
+	for rows.Next() {
+		var vector pgvector.Vector
+		if err := rows.Scan(&vector); err != nil {
+			return nil, fmt.Errorf("failed to scan memo embedding: %w", err)
+		}
+		vectors = append(vectors, vector.Slice())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to sample memo embeddings: %w", err)
+	}
+	return vectors, nil
+}
+
+// Search implements Store. The settings only last for the transaction of
+// the search.
+func (s *DBStore) Search(ctx context.Context, query *SearchQuery) ([]int32, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin search: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	setting := ""
+	if query.Exact {
+		setting = "SET LOCAL enable_indexscan = off"
+	} else if query.Value > 0 {
+		setting = fmt.Sprintf("SET LOCAL %s = %d", query.Param, query.Value)
+	}
+	if setting != "" {
+		if _, err := tx.ExecContext(ctx, setting); err != nil {
+			return nil, fmt.Errorf("failed to configure search: %w", err)
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT memo_id FROM memo_embedding
+		WHERE model = $1
+		ORDER BY embedding <=> $2
+		LIMIT $3`, query.Model, pgvector.NewVector(query.Vector), query.K)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search memo embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int32{}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan memo embedding: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search memo embeddings: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,246 @@
+// Package vectorindex maintains the pgvector index of the memo embeddings.
+//
+// The index type and its parameters come from the configuration: HNSW
+// (m, ef_construction) or IVFFlat (lists), with the search-time ef_search or
+// probes applied to every memo vector search. Rebuilds build the configured
+// index concurrently next to the one serving searches, then drop the old one.
+// Benchmark measures the recall and latency of the search-time parameter
+// against an exact search, to pick the tradeoff of a corpus.
+package vectorindex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hrygo/divinesense/internal/profile"
+)
+
+var (
+	// ErrInvalid is returned for invalid index configurations.
+	ErrInvalid = errors.New("invalid vector index configuration")
+	// ErrBusy is returned while another rebuild runs.
+	ErrBusy = errors.New("a vector index rebuild is already running")
+)
+
+// Index types.
+const (
+	TypeHNSW    = "hnsw"
+	TypeIVFFlat = "ivfflat"
+)
+
+// IndexName is the name of the index built by rebuilds.
+const IndexName = "idx_memo_embedding_vector"
+
+// Config is the index type and its parameters.
+type Config struct {
+	Type string `json:"type"`
+	// HNSW build parameters
+	M              int `json:"m,omitempty"`
+	EfConstruction int `json:"ef_construction,omitempty"`
+	// IVFFlat build parameter
+	Lists int `json:"lists,omitempty"`
+	// Search parameters; 0 keeps the pgvector default (ef_search 40, probes 1)
+	EfSearch int `json:"ef_search,omitempty"`
+	Probes   int `json:"probes,omitempty"`
+}
+
+// ConfigFromProfile returns the configuration of the DIVINESENSE_VECTOR_INDEX_* variables.
+func ConfigFromProfile(p *profile.Profile) Config {
+	return Config{
+		Type:           p.VectorIndexType,
+		M:              p.VectorIndexM,
+		EfConstruction: p.VectorIndexEfConstruction,
+		Lists:          p.VectorIndexLists,
+		EfSearch:       p.VectorIndexEfSearch,
+		Probes:         p.VectorIndexProbes,
+	}
+}
+
+// Validate checks the parameters of the index type.
+func (c Config) Validate() error {
+	switch c.Type {
+	case TypeHNSW:
+		if c.M < 2 || c.M > 100 {
+			return fmt.Errorf("%w: m must be between 2 and 100", ErrInvalid)
+		}
+		if c.EfConstruction < 2*c.M || c.EfConstruction > 1000 {
+			return fmt.Errorf("%w: ef_construction must be between 2*m and 1000", ErrInvalid)
+		}
+		if c.EfSearch < 0 || c.EfSearch > 1000 {
+			return fmt.Errorf("%w: ef_search must be at most 1000", ErrInvalid)
+		}
+	case TypeIVFFlat:
+		if c.Lists < 1 || c.Lists > 32768 {
+			return fmt.Errorf("%w: lists must be between 1 and 32768", ErrInvalid)
+		}
+		if c.Probes < 0 || c.Probes > c.Lists {
+			return fmt.Errorf("%w: probes must be at most lists", ErrInvalid)
+		}
+	default:
+		return fmt.Errorf("%w: unknown index type %q (hnsw or ivfflat)", ErrInvalid, c.Type)
+	}
+	return nil
+}
+
+// WithClause returns the storage parameters of the index, e.g.
+// "WITH (m = 16, ef_construction = 64)".
+func (c Config) WithClause() string {
+	if c.Type == TypeIVFFlat {
+		return fmt.Sprintf("WITH (lists = %d)", c.Lists)
+	}
+	return fmt.Sprintf("WITH (m = %d, ef_construction = %d)", c.M, c.EfConstruction)
+}
+
+// SearchParam returns the setting of the search-time parameter of the index type.
+func (c Config) SearchParam() string {
+	if c.Type == TypeIVFFlat {
+		return "ivfflat.probes"
+	}
+	return "hnsw.ef_search"
+}
+
+// SearchSetting returns the search-time parameter and its configured value;
+// 0 keeps the pgvector default.
+func (c Config) SearchSetting() (string, int) {
+	if c.Type == TypeIVFFlat {
+		return c.SearchParam(), c.Probes
+	}
+	return c.SearchParam(), c.EfSearch
+}
+
+// IndexInfo is a vector index of the memo embeddings.
+type IndexInfo struct {
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+	SizeBytes  int64  `json:"size_bytes"`
+	Valid      bool   `json:"valid"` // False for failed concurrent builds
+}
+
+// Status is the configured index and the indexes in the database.
+type Status struct {
+	Config     Config       `json:"config"`
+	Indexes    []*IndexInfo `json:"indexes"`
+	Embeddings int64        `json:"embeddings"`
+	// LastAnalyzed is the unix time of the latest ANALYZE, 0 if never.
+	LastAnalyzed int64 `json:"last_analyzed"`
+}
+
+// SearchQuery is a nearest neighbour search of the memo embeddings of a model.
+type SearchQuery struct {
+	Model  string
+	Vector []float32
+	K      int
+	// Param is set to Value for the search when Value > 0.
+	Param string
+	Value int
+	// Exact disables the index scans, for the ground truth of benchmarks.
+	Exact bool
+}
+
+// Store builds and queries the vector indexes.
+type Store interface {
+	// ListIndexes returns the vector indexes of the memo embeddings.
+	ListIndexes(ctx context.Context) ([]*IndexInfo, error)
+	// CountEmbeddings counts the memo embeddings.
+	CountEmbeddings(ctx context.Context) (int64, error)
+	// LastAnalyzed returns the unix time of the latest ANALYZE of the memo
+	// embeddings, 0 if never.
+	LastAnalyzed(ctx context.Context) (int64, error)
+	// CreateIndex builds an index of config concurrently.
+	CreateIndex(ctx context.Context, name string, config Config) error
+	// DropIndex drops an index concurrently if it exists.
+	DropIndex(ctx context.Context, name string) error
+	RenameIndex(ctx context.Context, name, newName string) error
+	Analyze(ctx context.Context) error
+
+	// SampleEmbeddings returns up to n random embeddings of model.
+	SampleEmbeddings(ctx context.Context, model string, n int) ([][]float32, error)
+	// Search returns the memo IDs of the K nearest embeddings, nearest first.
+	Search(ctx context.Context, query *SearchQuery) ([]int32, error)
+}
+
+// Service maintains the vector index of the configuration.
+type Service struct {
+	store  Store
+	config Config
+
+	// rebuilding is held during rebuilds
+	rebuilding sync.Mutex
+}
+
+// New creates the service of config.
+func New(store Store, config Config) *Service {
+	return &Service{store: store, config: config}
+}
+
+// Config returns the configured index.
+func (s *Service) Config() Config {
+	return s.config
+}
+
+// Status returns the configured index and the indexes in the database.
+func (s *Service) Status(ctx context.Context) (*Status, error) {
+	indexes, err := s.store.ListIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	embeddings, err := s.store.CountEmbeddings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	analyzed, err := s.store.LastAnalyzed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Status{Config: s.config, Indexes: indexes, Embeddings: embeddings, LastAnalyzed: analyzed}, nil
+}
+
+// Rebuild builds the configured index and drops the other vector indexes of
+// the memo embeddings. Searches keep using the old index until the new one
+// is built; the table takes writes throughout.
+func (s *Service) Rebuild(ctx context.Context) (*Status, error) {
+	if err := s.config.Validate(); err != nil {
+		return nil, err
+	}
+	if !s.rebuilding.TryLock() {
+		return nil, ErrBusy
+	}
+	defer s.rebuilding.Unlock()
+
+	// A build interrupted earlier leaves an invalid index behind
+	building := IndexName + "_new"
+	if err := s.store.DropIndex(ctx, building); err != nil {
+		return nil, err
+	}
+	if err := s.store.CreateIndex(ctx, building, s.config); err != nil {
+		return nil, err
+	}
+	indexes, err := s.store.ListIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indexes {
+		if index.Name != building {
+			if err := s.store.DropIndex(ctx, index.Name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := s.store.RenameIndex(ctx, building, IndexName); err != nil {
+		return nil, err
+	}
+	if err := s.store.Analyze(ctx); err != nil {
+		return nil, err
+	}
+	return s.Status(ctx)
+}
+
+// Analyze refreshes the planner statistics of the memo embeddings.
+func (s *Service) Analyze(ctx context.Context) (*Status, error) {
+	if err := s.store.Analyze(ctx); err != nil {
+		return nil, err
+	}
+	return s.Status(ctx)
+}
@@ -0,0 +1,153 @@
+package vectorindex
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore records the index operations and searches a fixed corpus.
+type fakeStore struct {
+	indexes []*IndexInfo
+	ops     []string
+	queries []*SearchQuery
+}
+
+func (s *fakeStore) ListIndexes(_ context.Context) ([]*IndexInfo, error) {
+	return s.indexes, nil
+}
+
+func (s *fakeStore) CountEmbeddings(_ context.Context) (int64, error) {
+	return 42, nil
+}
+
+func (s *fakeStore) LastAnalyzed(_ context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (s *fakeStore) CreateIndex(_ context.Context, name string, config Config) error {
+	s.ops = append(s.ops, "create "+name+" "+config.WithClause())
+	s.indexes = append(s.indexes, &IndexInfo{Name: name, Valid: true})
+	return nil
+}
+
+func (s *fakeStore) DropIndex(_ context.Context, name string) error {
+	s.ops = append(s.ops, "drop "+name)
+	for i, index := range s.indexes {
+		if index.Name == name {
+			s.indexes = append(s.indexes[:i], s.indexes[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (s *fakeStore) RenameIndex(_ context.Context, name, newName string) error {
+	s.ops = append(s.ops, "rename "+name+" "+newName)
+	for _, index := range s.indexes {
+		if index.Name == name {
+			index.Name = newName
+		}
+	}
+	return nil
+}
+
+func (s *fakeStore) Analyze(_ context.Context) error {
+	s.ops = append(s.ops, "analyze")
+	return nil
+}
+
+func (s *fakeStore) SampleEmbeddings(_ context.Context, _ string, n int) ([][]float32, error) {
+	return [][]float32{{1}, {2}}[:min(n, 2)], nil
+}
+
+// Search finds 1, 2, 3 exactly, and only the first Value neighbours
+// otherwise.
+func (s *fakeStore) Search(_ context.Context, query *SearchQuery) ([]int32, error) {
+	s.queries = append(s.queries, query)
+	ids := []int32{1, 2, 3}
+	if !query.Exact {
+		ids = ids[:min(query.Value, 3)]
+	}
+	return ids, nil
+}
+
+func hnswConfig() Config {
+	return Config{Type: TypeHNSW, M: 16, EfConstruction: 64}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	require.NoError(t, hnswConfig().Validate())
+	require.NoError(t, Config{Type: TypeIVFFlat, Lists: 100, Probes: 10}.Validate())
+
+	for _, config := range []Config{
+		{Type: "flat"},
+		{Type: TypeHNSW, M: 1, EfConstruction: 64},
+		{Type: TypeHNSW, M: 16, EfConstruction: 16},
+		{Type: TypeHNSW, M: 16, EfConstruction: 64, EfSearch: 5000},
+		{Type: TypeIVFFlat, Lists: 0},
+		{Type: TypeIVFFlat, Lists: 10, Probes: 20},
+	} {
+		assert.ErrorIs(t, config.Validate(), ErrInvalid, "%+v", config)
+	}
+
+	assert.Equal(t, "WITH (m = 16, ef_construction = 64)", hnswConfig().WithClause())
+	assert.Equal(t, "WITH (lists = 100)", Config{Type: TypeIVFFlat, Lists: 100}.WithClause())
+	param, value := Config{Type: TypeIVFFlat, Lists: 100, Probes: 10}.SearchSetting()
+	assert.Equal(t, "ivfflat.probes", param)
+	assert.Equal(t, 10, value)
+}
+
+func TestService_Rebuild(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{indexes: []*IndexInfo{{Name: "idx_memo_embedding_hnsw", Valid: true}}}
+	service := New(store, hnswConfig())
+
+	status, err := service.Rebuild(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"drop idx_memo_embedding_vector_new",
+		"create idx_memo_embedding_vector_new WITH (m = 16, ef_construction = 64)",
+		"drop idx_memo_embedding_hnsw",
+		"rename idx_memo_embedding_vector_new idx_memo_embedding_vector",
+		"analyze",
+	}, store.ops)
+	require.Len(t, status.Indexes, 1)
+	assert.Equal(t, IndexName, status.Indexes[0].Name)
+	assert.Equal(t, int64(42), status.Embeddings)
+
+	// Invalid configurations do not touch the indexes
+	store.ops = nil
+	_, err = New(store, Config{Type: TypeIVFFlat}).Rebuild(ctx)
+	assert.ErrorIs(t, err, ErrInvalid)
+	assert.Empty(t, store.ops)
+
+	// One rebuild at a time
+	service.rebuilding.Lock()
+	_, err = service.Rebuild(ctx)
+	assert.ErrorIs(t, err, ErrBusy)
+}
+
+func TestService_Benchmark(t *testing.T) {
+	store := &fakeStore{}
+	service := New(store, hnswConfig())
+
+	result, err := service.Benchmark(context.Background(), BenchmarkOptions{Model: "BAAI/bge-m3", Queries: 10, K: 3, Values: []int{1, 3}})
+	require.NoError(t, err)
+	assert.Equal(t, "hnsw.ef_search", result.Param)
+	assert.Equal(t, 2, result.Queries)
+	assert.Equal(t, 1.0, result.Exact.Recall)
+	require.Len(t, result.Values, 2)
+	assert.InDelta(t, 1.0/3, result.Values[0].Recall, 0.0001)
+	assert.Equal(t, 1.0, result.Values[1].Recall)
+
+	last := store.queries[len(store.queries)-1]
+	assert.Equal(t, "hnsw.ef_search", last.Param)
+	assert.Equal(t, 3, last.Value)
+	assert.Equal(t, "BAAI/bge-m3", last.Model)
+
+	_, err = service.Benchmark(context.Background(), BenchmarkOptions{Queries: 10})
+	assert.ErrorIs(t, err, ErrInvalid)
+}
@@ -0,0 +1,167 @@
+package webfetch
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// skippedElements are the elements without readable text.
+var skippedElements = map[atom.Atom]bool{
+	atom.Head: true, atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Svg: true, atom.Iframe: true, atom.Nav: true, atom.Footer: true, atom.Form: true,
+	atom.Button: true, atom.Select: true, atom.Aside: true,
+}
+
+// blockElements are the elements whose text starts on a new line.
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Br: true, atom.Li: true, atom.Tr: true, atom.Table: true,
+	atom.Section: true, atom.Article: true, atom.Main: true, atom.Header: true, atom.Blockquote: true,
+	atom.Pre: true, atom.Ul: true, atom.Ol: true, atom.Dl: true, atom.Dt: true, atom.Dd: true,
+	atom.Figure: true, atom.Figcaption: true, atom.Hr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+}
+
+// headingLevels are the levels of the heading elements, written as Markdown
+// headings.
+var headingLevels = map[atom.Atom]int{atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6}
+
+// extractHTML returns the title and the readable text of an HTML page: the
+// text of its body without scripts, styles, navigation and forms, with a line
+// per block, headings as Markdown headings and list items as "- " lines.
+func extractHTML(r io.Reader) (title, text string) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return "", ""
+	}
+	w := &textWriter{}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			w.text(n.Data)
+			return
+		case html.ElementNode:
+			if n.DataAtom == atom.Head {
+				title = findTitle(n)
+			}
+			if skippedElements[n.DataAtom] {
+				return
+			}
+			if blockElements[n.DataAtom] {
+				w.newline()
+			}
+			if level := headingLevels[n.DataAtom]; level > 0 {
+				w.b.WriteString(strings.Repeat("#", level) + " ")
+			}
+			if n.DataAtom == atom.Li {
+				w.b.WriteString("- ")
+			}
+			if n.DataAtom == atom.Pre {
+				w.pre++
+				defer func() { w.pre-- }()
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if n.Type == html.ElementNode && blockElements[n.DataAtom] {
+			w.newline()
+		}
+	}
+	walk(doc)
+	return title, w.String()
+}
+
+// findTitle returns the text of the title element under n, with its
+// whitespace collapsed.
+func findTitle(n *html.Node) string {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if c.DataAtom != atom.Title {
+			if title := findTitle(c); title != "" {
+				return title
+			}
+			continue
+		}
+		var b strings.Builder
+		for t := c.FirstChild; t != nil; t = t.NextSibling {
+			if t.Type == html.TextNode {
+				b.WriteString(t.Data)
+			}
+		}
+		return strings.Join(strings.Fields(b.String()), " ")
+	}
+	return ""
+}
+
+// textWriter writes the text of a page, collapsing the whitespace outside
+// pre elements.
+type textWriter struct {
+	b   strings.Builder
+	pre int
+}
+
+func (w *textWriter) atLineStart() bool {
+	s := w.b.String()
+	return s == "" || strings.HasSuffix(s, "\n") || strings.HasSuffix(s, "- ") || strings.HasSuffix(s, "# ")
+}
+
+func (w *textWriter) newline() {
+	if s := w.b.String(); s != "" && !strings.HasSuffix(s, "\n") {
+		w.b.WriteByte('\n')
+	}
+}
+
+func (w *textWriter) text(s string) {
+	if w.pre > 0 {
+		w.b.WriteString(s)
+		return
+	}
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		if s != "" && !w.atLineStart() && !strings.HasSuffix(w.b.String(), " ") {
+			w.b.WriteByte(' ')
+		}
+		return
+	}
+	if startsWithSpace(s) && !w.atLineStart() && !strings.HasSuffix(w.b.String(), " ") {
+		w.b.WriteByte(' ')
+	}
+	w.b.WriteString(strings.Join(fields, " "))
+	if endsWithSpace(s) {
+		w.b.WriteByte(' ')
+	}
+}
+
+// String returns the text, with trailing spaces trimmed and runs of blank
+// lines collapsed into one.
+func (w *textWriter) String() string {
+	var lines []string
+	blank := true
+	for line := range strings.SplitSeq(w.b.String(), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if strings.TrimSpace(line) == "" || strings.TrimSpace(line) == "-" {
+			if !blank {
+				lines = append(lines, "")
+			}
+			blank = true
+			continue
+		}
+		lines = append(lines, line)
+		blank = false
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func startsWithSpace(s string) bool {
+	return s != "" && strings.TrimLeft(s, " \t\r\n\f") != s
+}
+
+func endsWithSpace(s string) bool {
+	return s != "" && strings.TrimRight(s, " \t\r\n\f") != s
+}
@@ -0,0 +1,238 @@
+package webfetch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+
+	"github.com/hrygo/divinesense/plugin/netguard"
+)
+
+const (
+	// maxRedirects bounds the redirects of a fetch.
+	maxRedirects = 5
+	// maxCachedPages bounds the pages of the cache.
+	maxCachedPages = 256
+)
+
+// Page is a fetched page.
+type Page struct {
+	// URL is the URL of the page, after redirects.
+	URL         string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	ContentType string `json:"content_type"`
+	// Text is the readable text of HTML pages, or the body of text documents.
+	Text string `json:"text"`
+	// Truncated is set when the body was longer than the MaxBytes of the
+	// policy.
+	Truncated bool `json:"truncated"`
+	// Cached is set for pages served from the cache.
+	Cached    bool  `json:"cached"`
+	FetchedTs int64 `json:"fetched_ts"`
+}
+
+type cachedPage struct {
+	page    *Page
+	expires time.Time
+}
+
+// Fetch fetches a page for a user under the policy: http and https URLs of
+// allowed domains whose robots.txt lets DivineSenseBot in. Redirects are
+// followed within the allowed domains. HTML pages are reduced to their
+// readable text; other text documents (plain text, JSON, XML) are returned
+// as is, and binary documents are refused.
+func (f *Fetcher) Fetch(ctx context.Context, userID int32, rawURL string) (*Page, error) {
+	policy, err := f.Policy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.Enabled {
+		return nil, ErrDisabled
+	}
+	u, err := parseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	host := u.Hostname()
+	if !policy.Allows(host) {
+		f.audit(ctx, userID, u.String(), host, "denied", "domain not allowed")
+		return nil, fmt.Errorf("%w: %s is not an allowed domain", ErrDenied, host)
+	}
+	if page := f.cachedPage(u.String()); page != nil {
+		return page, nil
+	}
+	if !f.allow(userID, policy) {
+		f.audit(ctx, userID, u.String(), host, "rate_limited", ErrRateLimited.Error())
+		return nil, ErrRateLimited
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, policy.timeout())
+	defer cancel()
+	client := f.httpClient(policy)
+	allowed, err := f.robotsAllow(ctx, client, u)
+	if err != nil {
+		f.audit(ctx, userID, u.String(), host, "denied", err.Error())
+		return nil, fmt.Errorf("%w: %v", ErrDenied, err)
+	}
+	if !allowed {
+		f.audit(ctx, userID, u.String(), host, "denied", "disallowed by robots.txt")
+		return nil, fmt.Errorf("%w: robots.txt of %s disallows %s", ErrDenied, host, u.Path)
+	}
+
+	page, err := f.get(ctx, client, u, policy)
+	if err != nil {
+		f.audit(ctx, userID, u.String(), host, "failed", err.Error())
+		return nil, err
+	}
+	f.audit(ctx, userID, u.String(), host, "allowed", page.ContentType)
+	f.cachePage(u.String(), page, policy.cacheTTL())
+	return page, nil
+}
+
+// parseURL parses the URL of a fetch, dropping its fragment.
+func parseURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: the URL must be an absolute http(s) URL", ErrInvalid)
+	}
+	if u.User != nil {
+		return nil, fmt.Errorf("%w: the URL must not have credentials", ErrInvalid)
+	}
+	u.Fragment, u.RawFragment = "", ""
+	return u, nil
+}
+
+// get sends the request of a fetch and reads its page.
+func (f *Fetcher) get(ctx context.Context, client *http.Client, u *url.URL, policy *Policy) (*Page, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain;q=0.9,*/*;q=0.5")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s returned HTTP %d", u, resp.StatusCode)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	isHTML := mediaType == "text/html" || mediaType == "application/xhtml+xml"
+	if !isHTML && !isText(mediaType) {
+		return nil, fmt.Errorf("%s is not a text document (%s)", u, mediaType)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(policy.MaxBytes)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", u, err)
+	}
+	page := &Page{URL: resp.Request.URL.String(), ContentType: mediaType, FetchedTs: time.Now().Unix()}
+	if len(body) > int(policy.MaxBytes) {
+		body, page.Truncated = body[:policy.MaxBytes], true
+	}
+	reader, err := charset.NewReader(bytes.NewReader(body), contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", u, err)
+	}
+	if isHTML {
+		page.Title, page.Text = extractHTML(reader)
+		return page, nil
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", u, err)
+	}
+	page.Text = strings.ToValidUTF8(string(decoded), "")
+	return page, nil
+}
+
+// isText reports whether a media type is a text document.
+func isText(mediaType string) bool {
+	switch {
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	case mediaType == "application/json", mediaType == "application/xml",
+		strings.HasSuffix(mediaType, "+json"), strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	return false
+}
+
+// httpClient returns the client of a fetch, which follows redirects within
+// the allowed domains.
+func (f *Fetcher) httpClient(policy *Policy) *http.Client {
+	return &http.Client{
+		Transport: f.transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return errors.New("too many redirects")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("%w: redirect to %s", ErrDenied, req.URL.Scheme)
+			}
+			if !policy.Allows(req.URL.Hostname()) {
+				return fmt.Errorf("%w: redirect to %s, which is not an allowed domain", ErrDenied, req.URL.Hostname())
+			}
+			return nil
+		},
+	}
+}
+
+// newTransport returns the transport of fetches, which only connects to
+// public addresses.
+func newTransport() *http.Transport {
+	return netguard.Transport(10*time.Second, ErrDenied)
+}
+
+// cachedPage returns a page of the cache, or nil.
+func (f *Fetcher) cachedPage(key string) *Page {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entry, ok := f.pages[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil
+	}
+	page := *entry.page
+	page.Cached = true
+	return &page
+}
+
+// cachePage caches a page, dropping expired pages, or an arbitrary one, when
+// the cache is full.
+func (f *Fetcher) cachePage(key string, page *Page, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.pages) >= maxCachedPages {
+		now := time.Now()
+		for k, entry := range f.pages {
+			if now.After(entry.expires) {
+				delete(f.pages, k)
+			}
+		}
+		for k := range f.pages {
+			if len(f.pages) < maxCachedPages {
+				break
+			}
+			delete(f.pages, k)
+		}
+	}
+	f.pages[key] = &cachedPage{page: page, expires: time.Now().Add(ttl)}
+}
@@ -0,0 +1,177 @@
+package webfetch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// robotsTTL is how long the robots.txt of a site is cached.
+	robotsTTL = time.Hour
+	// robotsErrorTTL is how long a site whose robots.txt could not be read
+	// stays off limits.
+	robotsErrorTTL = 5 * time.Minute
+	// maxRobotsBytes bounds the robots.txt read (RFC 9309 requires at least
+	// 500 KiB).
+	maxRobotsBytes = 512 << 10
+	// maxCachedRobots bounds the sites of the robots.txt cache.
+	maxCachedRobots = 256
+)
+
+// robotsRule is an Allow or Disallow rule of robots.txt.
+type robotsRule struct {
+	allow   bool
+	pattern string
+	match   *regexp.Regexp
+}
+
+// robotsRules are the rules of robots.txt that apply to DivineSenseBot.
+type robotsRules []robotsRule
+
+type cachedRobots struct {
+	rules   robotsRules
+	err     error
+	expires time.Time
+}
+
+// parseRobots returns the rules of the group of DivineSenseBot of a
+// robots.txt, or else of the group of all agents ("*"). Groups naming the
+// same agent are merged.
+func parseRobots(data []byte) robotsRules {
+	var own, all robotsRules
+	var agents []string
+	inRules := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64<<10), maxRobotsBytes)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch key {
+		case "user-agent":
+			if inRules {
+				agents, inRules = nil, false
+			}
+			agents = append(agents, strings.ToLower(value))
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				// An empty Disallow allows everything
+				continue
+			}
+			rule := robotsRule{allow: key == "allow", pattern: value, match: robotsPattern(value)}
+			for _, agent := range agents {
+				switch {
+				case agent == "*":
+					all = append(all, rule)
+				case agent == robotsAgent:
+					own = append(own, rule)
+				}
+			}
+		}
+	}
+	if own != nil {
+		return own
+	}
+	return all
+}
+
+// robotsPattern compiles the path pattern of a rule, where "*" matches any
+// characters and a trailing "$" anchors the end of the path.
+func robotsPattern(pattern string) *regexp.Regexp {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// Allowed reports whether the rules allow a path (with its query): the
+// longest matching rule wins, and Allow wins ties.
+func (r robotsRules) Allowed(path string) bool {
+	allowed, longest := true, -1
+	for _, rule := range r {
+		if !rule.match.MatchString(path) {
+			continue
+		}
+		if n := len(rule.pattern); n > longest || (n == longest && rule.allow) {
+			allowed, longest = rule.allow, n
+		}
+	}
+	return allowed
+}
+
+// robotsAllow reports whether the robots.txt of the site of u allows
+// fetching it. Sites without a robots.txt (4xx) allow everything; sites
+// whose robots.txt cannot be read allow nothing for a while.
+func (f *Fetcher) robotsAllow(ctx context.Context, client *http.Client, u *url.URL) (bool, error) {
+	origin := u.Scheme + "://" + u.Host
+	f.mu.Lock()
+	entry, ok := f.robots[origin]
+	f.mu.Unlock()
+	if !ok || time.Now().After(entry.expires) {
+		entry = &cachedRobots{expires: time.Now().Add(robotsTTL)}
+		entry.rules, entry.err = fetchRobots(ctx, client, origin)
+		if entry.err != nil {
+			if ctx.Err() != nil {
+				return false, entry.err
+			}
+			entry.expires = time.Now().Add(robotsErrorTTL)
+		}
+		f.mu.Lock()
+		if len(f.robots) >= maxCachedRobots {
+			clear(f.robots)
+		}
+		f.robots[origin] = entry
+		f.mu.Unlock()
+	}
+	if entry.err != nil {
+		return false, entry.err
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return entry.rules.Allowed(path), nil
+}
+
+// fetchRobots fetches and parses the robots.txt of a site.
+func fetchRobots(ctx context.Context, client *http.Client, origin string) (robotsRules, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch robots.txt: %w", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return nil, nil
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, fmt.Errorf("robots.txt returned HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRobotsBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read robots.txt: %w", err)
+	}
+	return parseRobots(data), nil
+}
@@ -0,0 +1,71 @@
+package webfetch
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/hrygo/divinesense/internal/util"
+)
+
+// DBStore persists the policy in the web_fetch_policy table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new policy store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const policyColumns = "enabled, allowed_domains, denied_domains, rate_limit_per_minute, max_bytes, timeout_seconds, cache_ttl_seconds, updated_ts"
+
+// GetPolicy implements Store.
+func (s *DBStore) GetPolicy(ctx context.Context) (*Policy, error) {
+	policy, err := scanPolicy(s.db.QueryRowContext(ctx, "SELECT "+policyColumns+" FROM web_fetch_policy WHERE id = 1"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return DefaultPolicy(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get web fetch policy: %w", err)
+	}
+	return policy, nil
+}
+
+// SavePolicy implements Store.
+func (s *DBStore) SavePolicy(ctx context.Context, policy *Policy) (*Policy, error) {
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO web_fetch_policy (id, enabled, allowed_domains, denied_domains, rate_limit_per_minute, max_bytes, timeout_seconds, cache_ttl_seconds, updated_ts)
+		VALUES (1, $1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			allowed_domains = EXCLUDED.allowed_domains,
+			denied_domains = EXCLUDED.denied_domains,
+			rate_limit_per_minute = EXCLUDED.rate_limit_per_minute,
+			max_bytes = EXCLUDED.max_bytes,
+			timeout_seconds = EXCLUDED.timeout_seconds,
+			cache_ttl_seconds = EXCLUDED.cache_ttl_seconds,
+			updated_ts = EXCLUDED.updated_ts
+		RETURNING `+policyColumns,
+		policy.Enabled, pq.Array(util.OrEmpty(policy.AllowedDomains)), pq.Array(util.OrEmpty(policy.DeniedDomains)),
+		policy.RateLimitPerMinute, policy.MaxBytes, policy.TimeoutSeconds, policy.CacheTTLSeconds, time.Now().Unix())
+	saved, err := scanPolicy(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save web fetch policy: %w", err)
+	}
+	return saved, nil
+}
+
+func scanPolicy(row *sql.Row) (*Policy, error) {
+	policy := &Policy{}
+	if err := row.Scan(&policy.Enabled, pq.Array(&policy.AllowedDomains), pq.Array(&policy.DeniedDomains),
+		&policy.RateLimitPerMinute, &policy.MaxBytes, &policy.TimeoutSeconds, &policy.CacheTTLSeconds, &policy.UpdatedTs); err != nil {
+		return nil, err
+	}
+	policy.AllowedDomains, policy.DeniedDomains = util.OrEmpty(policy.AllowedDomains), util.OrEmpty(policy.DeniedDomains)
+	return policy, nil
+}
@@ -0,0 +1,83 @@
+package webfetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	agents "github.com/hrygo/divinesense/ai/agents"
+)
+
+// maxToolRunes bounds the text of a page returned to agents.
+const maxToolRunes = 12000
+
+// ToolFor returns the web_fetch tool of a user, or nil without a fetcher or
+// while web fetches are disabled.
+func (f *Fetcher) ToolFor(userID int32) agents.ToolWithSchema {
+	if f == nil {
+		return nil
+	}
+	policy, err := f.Policy(context.Background())
+	if err != nil || !policy.Enabled {
+		return nil
+	}
+	description := "Fetch a web page or text document by URL and return its readable text. Use it when the user " +
+		"gives a link or asks about fresh information that is not in their memos, and cite the URL. "
+	if len(policy.AllowedDomains) > 0 {
+		description += "Only these domains and their subdomains can be fetched: " + strings.Join(policy.AllowedDomains, ", ") + ". "
+	}
+	description += `Input: {"url": "https://example.com/page"}.`
+	return agents.NewNativeTool(
+		ToolName,
+		description,
+		func(ctx context.Context, input string) (string, error) {
+			var args struct {
+				URL string `json:"url"`
+			}
+			if err := json.Unmarshal([]byte(input), &args); err != nil {
+				return "", fmt.Errorf("invalid input: %w", err)
+			}
+			page, err := f.Fetch(ctx, userID, args.URL)
+			if err != nil {
+				return "", err
+			}
+			return formatPage(page), nil
+		},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"url": map[string]any{
+					"type":        "string",
+					"description": "Absolute http(s) URL to fetch",
+				},
+			},
+			"required": []string{"url"},
+		},
+	)
+}
+
+// formatPage formats a page for agents.
+func formatPage(page *Page) string {
+	var b strings.Builder
+	if page.Title != "" {
+		fmt.Fprintf(&b, "Title: %s\n", page.Title)
+	}
+	fmt.Fprintf(&b, "URL: %s\n", page.URL)
+	fmt.Fprintf(&b, "Fetched: %s\n\n", time.Unix(page.FetchedTs, 0).UTC().Format(time.RFC3339))
+	text := page.Text
+	truncated := page.Truncated
+	if utf8.RuneCountInString(text) > maxToolRunes {
+		text, truncated = string([]rune(text)[:maxToolRunes]), true
+	}
+	if text == "" {
+		text = "(no readable text)"
+	}
+	b.WriteString(text)
+	if truncated {
+		b.WriteString("\n\n[truncated]")
+	}
+	return b.String()
+}
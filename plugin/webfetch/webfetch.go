@@ -0,0 +1,292 @@
+// Package webfetch lets parrots read web pages: the web_fetch tool fetches a
+// URL server-side and returns its readable text, so answers can include
+// fresh information.
+//
+// Admins control the tool with a single policy: it is disabled until they
+// enable it, and it may be limited to an allowlist of domains, with a
+// denylist that always wins. Fetches are rate limited per user, bounded in
+// size and time, cached, and respect the robots.txt of the sites. Addresses
+// of the private network are never fetched, and every fetch is written to the
+// security audit log.
+package webfetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/hrygo/divinesense/store"
+)
+
+// ToolName is the name of the web fetch tool of agents.
+const ToolName = "web_fetch"
+
+const (
+	// UserAgent is sent with the requests; robots.txt rules for
+	// DivineSenseBot apply to them.
+	UserAgent = "DivineSenseBot/1.0"
+	// robotsAgent is the user agent token robots.txt groups are matched with.
+	robotsAgent = "divinesensebot"
+)
+
+var (
+	// ErrInvalid is returned for invalid policies and URLs.
+	ErrInvalid = errors.New("invalid web fetch")
+	// ErrDisabled is returned when the admin has not enabled web fetches.
+	ErrDisabled = errors.New("web fetch is disabled")
+	// ErrDenied is returned for URLs the policy, robots.txt or the private
+	// network rule forbids.
+	ErrDenied = errors.New("web fetch denied")
+	// ErrRateLimited is returned when a user exceeds the per-minute limit.
+	ErrRateLimited = errors.New("web fetch rate limit exceeded")
+)
+
+const (
+	// maxDomains bounds the domains of each list of the policy.
+	maxDomains = 200
+	// MaxBytesLimit caps the MaxBytes of the policy.
+	MaxBytesLimit = 10 << 20
+	// MaxTimeout caps the TimeoutSeconds of the policy.
+	MaxTimeout = 60 * time.Second
+	// maxCacheTTL caps the CacheTTLSeconds of the policy.
+	maxCacheTTL = 24 * time.Hour
+	// maxRateLimit caps the RateLimitPerMinute of the policy.
+	maxRateLimit = 600
+	// policyCacheTTL bounds how long the policy is cached before reloading.
+	policyCacheTTL = 30 * time.Second
+)
+
+// domainPattern matches the domains of the policy lists.
+var domainPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`)
+
+// Policy is the admin policy of web fetches. A domain of the lists matches
+// itself and its subdomains.
+type Policy struct {
+	Enabled bool `json:"enabled"`
+	// AllowedDomains restricts the domains fetched; empty allows all the
+	// domains but the denied ones.
+	AllowedDomains []string `json:"allowed_domains"`
+	DeniedDomains  []string `json:"denied_domains"`
+	// RateLimitPerMinute bounds the fetches of each user; 0 is unlimited.
+	RateLimitPerMinute int32 `json:"rate_limit_per_minute"`
+	// MaxBytes bounds the body read of a response; longer bodies are
+	// truncated.
+	MaxBytes       int32 `json:"max_bytes"`
+	TimeoutSeconds int32 `json:"timeout_seconds"`
+	// CacheTTLSeconds is how long fetched pages are served from the cache;
+	// 0 disables the cache.
+	CacheTTLSeconds int32 `json:"cache_ttl_seconds"`
+	UpdatedTs       int64 `json:"updated_ts"`
+}
+
+// DefaultPolicy returns the policy of instances whose admin saved none:
+// web fetches are disabled.
+func DefaultPolicy() *Policy {
+	return &Policy{
+		AllowedDomains:     []string{},
+		DeniedDomains:      []string{},
+		RateLimitPerMinute: 10,
+		MaxBytes:           1 << 20,
+		TimeoutSeconds:     10,
+		CacheTTLSeconds:    900,
+	}
+}
+
+// Validate checks a policy about to be saved and normalizes its domains:
+// lowercased, without a leading "*." or trailing dot, and de-duplicated.
+func (p *Policy) Validate() error {
+	var err error
+	if p.AllowedDomains, err = normalizeDomains("allowed_domains", p.AllowedDomains); err != nil {
+		return err
+	}
+	if p.DeniedDomains, err = normalizeDomains("denied_domains", p.DeniedDomains); err != nil {
+		return err
+	}
+	if p.RateLimitPerMinute < 0 || p.RateLimitPerMinute > maxRateLimit {
+		return fmt.Errorf("%w: rate_limit_per_minute must be between 0 and %d", ErrInvalid, maxRateLimit)
+	}
+	if p.MaxBytes < 1024 || p.MaxBytes > MaxBytesLimit {
+		return fmt.Errorf("%w: max_bytes must be between 1024 and %d", ErrInvalid, MaxBytesLimit)
+	}
+	if p.TimeoutSeconds < 1 || time.Duration(p.TimeoutSeconds)*time.Second > MaxTimeout {
+		return fmt.Errorf("%w: timeout_seconds must be between 1 and %d", ErrInvalid, int(MaxTimeout.Seconds()))
+	}
+	if p.CacheTTLSeconds < 0 || time.Duration(p.CacheTTLSeconds)*time.Second > maxCacheTTL {
+		return fmt.Errorf("%w: cache_ttl_seconds must be between 0 and %d", ErrInvalid, int(maxCacheTTL.Seconds()))
+	}
+	return nil
+}
+
+func normalizeDomains(field string, domains []string) ([]string, error) {
+	if len(domains) > maxDomains {
+		return nil, fmt.Errorf("%w: %s may list at most %d domains", ErrInvalid, field, maxDomains)
+	}
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		d := strings.TrimSuffix(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "*."), ".")
+		if !domainPattern.MatchString(d) {
+			return nil, fmt.Errorf("%w: %s: %q is not a domain", ErrInvalid, field, domain)
+		}
+		if !slices.Contains(normalized, d) {
+			normalized = append(normalized, d)
+		}
+	}
+	return normalized, nil
+}
+
+// Allows reports whether the policy allows fetching from host.
+func (p *Policy) Allows(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" || matchDomain(host, p.DeniedDomains) {
+		return false
+	}
+	return len(p.AllowedDomains) == 0 || matchDomain(host, p.AllowedDomains)
+}
+
+func matchDomain(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Policy) timeout() time.Duration {
+	return time.Duration(p.TimeoutSeconds) * time.Second
+}
+
+func (p *Policy) cacheTTL() time.Duration {
+	return time.Duration(p.CacheTTLSeconds) * time.Second
+}
+
+// Store persists the policy.
+type Store interface {
+	// GetPolicy returns the policy, or DefaultPolicy if none was saved.
+	GetPolicy(ctx context.Context) (*Policy, error)
+	SavePolicy(ctx context.Context, policy *Policy) (*Policy, error)
+}
+
+// Auditor records fetches; implemented by store.SecurityAuditStore.
+type Auditor interface {
+	LogSecurityEvent(ctx context.Context, event *store.SecurityAuditEvent) error
+}
+
+type limiterEntry struct {
+	perMinute int32
+	limiter   *rate.Limiter
+}
+
+// Fetcher fetches web pages under the policy.
+type Fetcher struct {
+	store     Store
+	auditor   Auditor
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	policy   *Policy
+	loadedAt time.Time
+	limiters map[int32]*limiterEntry
+	pages    map[string]*cachedPage
+	robots   map[string]*cachedRobots
+}
+
+// New creates a fetcher. auditor may be nil.
+func New(store Store, auditor Auditor) *Fetcher {
+	return &Fetcher{
+		store:     store,
+		auditor:   auditor,
+		transport: newTransport(),
+		limiters:  make(map[int32]*limiterEntry),
+		pages:     make(map[string]*cachedPage),
+		robots:    make(map[string]*cachedRobots),
+	}
+}
+
+// Policy returns the policy, cached for a little while.
+func (f *Fetcher) Policy(ctx context.Context) (*Policy, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.policy != nil && time.Since(f.loadedAt) < policyCacheTTL {
+		return f.policy, nil
+	}
+	policy, err := f.store.GetPolicy(ctx)
+	if err != nil {
+		if f.policy != nil {
+			// Keep serving the stale policy rather than flapping
+			slog.Warn("failed to reload web fetch policy", "error", err)
+			return f.policy, nil
+		}
+		return nil, fmt.Errorf("failed to get web fetch policy: %w", err)
+	}
+	f.policy, f.loadedAt = policy, time.Now()
+	return policy, nil
+}
+
+// SavePolicy validates and saves the policy. Cached pages are dropped, so
+// that they are not served past the new policy.
+func (f *Fetcher) SavePolicy(ctx context.Context, policy *Policy) (*Policy, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	saved, err := f.store.SavePolicy(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	f.policy, f.loadedAt = saved, time.Now()
+	clear(f.pages)
+	f.mu.Unlock()
+	return saved, nil
+}
+
+// allow consumes one token from the limiter of a user.
+// The limiter is rebuilt when the admin changes the configured rate.
+func (f *Fetcher) allow(userID int32, policy *Policy) bool {
+	if policy.RateLimitPerMinute <= 0 {
+		return true
+	}
+	f.mu.Lock()
+	entry, ok := f.limiters[userID]
+	if !ok || entry.perMinute != policy.RateLimitPerMinute {
+		perMinute := int(policy.RateLimitPerMinute)
+		entry = &limiterEntry{
+			perMinute: policy.RateLimitPerMinute,
+			limiter:   rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), perMinute),
+		}
+		f.limiters[userID] = entry
+	}
+	f.mu.Unlock()
+	return entry.limiter.Allow()
+}
+
+func (f *Fetcher) audit(ctx context.Context, userID int32, rawURL, host, taken, reason string) {
+	if f.auditor == nil {
+		return
+	}
+	event := &store.SecurityAuditEvent{
+		UserID:        userID,
+		AgentType:     "parrot",
+		OperationType: "web_fetch",
+		OperationName: host,
+		RiskLevel:     "low",
+		CommandInput:  "GET " + rawURL,
+		ActionTaken:   taken,
+		Reason:        reason,
+		ToolID:        ToolName,
+		OccurredAt:    time.Now(),
+	}
+	// Audit must outlive a cancelled or timed-out request context.
+	if err := f.auditor.LogSecurityEvent(context.WithoutCancel(ctx), event); err != nil {
+		slog.Warn("failed to audit web fetch", "host", host, "error", err)
+	}
+}
@@ -0,0 +1,268 @@
+package webfetch
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/store"
+)
+
+type fakeStore struct {
+	policy *Policy
+}
+
+func (s *fakeStore) GetPolicy(context.Context) (*Policy, error) {
+	if s.policy == nil {
+		return DefaultPolicy(), nil
+	}
+	return s.policy, nil
+}
+
+func (s *fakeStore) SavePolicy(_ context.Context, policy *Policy) (*Policy, error) {
+	s.policy = policy
+	return policy, nil
+}
+
+type fakeAuditor struct {
+	events []*store.SecurityAuditEvent
+}
+
+func (a *fakeAuditor) LogSecurityEvent(_ context.Context, event *store.SecurityAuditEvent) error {
+	a.events = append(a.events, event)
+	return nil
+}
+
+func TestPolicy(t *testing.T) {
+	policy := DefaultPolicy()
+	policy.AllowedDomains = []string{" Example.com. ", "*.docs.io", "example.com"}
+	policy.DeniedDomains = []string{"private.example.com"}
+	require.NoError(t, policy.Validate())
+	assert.Equal(t, []string{"example.com", "docs.io"}, policy.AllowedDomains)
+
+	assert.True(t, policy.Allows("example.com"))
+	assert.True(t, policy.Allows("WWW.example.com."))
+	assert.True(t, policy.Allows("api.docs.io"))
+	assert.False(t, policy.Allows("notexample.com"))
+	assert.False(t, policy.Allows("private.example.com"), "denied domains win")
+	assert.False(t, policy.Allows("a.private.example.com"))
+
+	policy.AllowedDomains = nil
+	require.NoError(t, policy.Validate())
+	assert.True(t, policy.Allows("anything.org"))
+	assert.False(t, policy.Allows("private.example.com"))
+
+	for _, invalid := range []*Policy{
+		{AllowedDomains: []string{"https://example.com"}, MaxBytes: 1024, TimeoutSeconds: 1},
+		{MaxBytes: 100, TimeoutSeconds: 1},
+		{MaxBytes: 1024, TimeoutSeconds: 120},
+		{MaxBytes: 1024, TimeoutSeconds: 1, RateLimitPerMinute: -1},
+	} {
+		assert.ErrorIs(t, invalid.Validate(), ErrInvalid)
+	}
+}
+
+func TestRobots(t *testing.T) {
+	rules := parseRobots([]byte(`
+# Comment
+User-agent: *
+Disallow: /
+
+User-agent: Googlebot
+User-agent: DivineSenseBot
+Disallow: /private
+Allow: /private/public
+Disallow: /*.pdf$
+Disallow: /search?
+`))
+	assert.True(t, rules.Allowed("/"))
+	assert.True(t, rules.Allowed("/blog/post"))
+	assert.False(t, rules.Allowed("/private/notes"))
+	assert.True(t, rules.Allowed("/private/public/page"), "the longest rule wins")
+	assert.False(t, rules.Allowed("/files/report.pdf"))
+	assert.True(t, rules.Allowed("/files/report.pdf.html"))
+	assert.False(t, rules.Allowed("/search?q=go"))
+
+	rules = parseRobots([]byte("User-agent: *\nDisallow: /admin\nDisallow:\n"))
+	assert.False(t, rules.Allowed("/admin/users"))
+	assert.True(t, rules.Allowed("/about"))
+	assert.True(t, parseRobots(nil).Allowed("/"))
+}
+
+func TestExtractHTML(t *testing.T) {
+	title, text := extractHTML(strings.NewReader(`<!DOCTYPE html>
+<html><head><title> Release
+ notes </title><style>body { color: red }</style></head>
+<body>
+<nav><a href="/">Home</a></nav>
+<h1>Version 2.0</h1>
+<p>The <b>new</b>   release
+adds <a href="/x">search</a>.</p>
+<script>alert("x")</script>
+<ul><li>Faster</li><li>Smaller</li></ul>
+<pre>go run  ./cmd
+  --flag</pre>
+<footer>© 2026</footer>
+</body></html>`))
+	assert.Equal(t, "Release notes", title)
+	assert.Equal(t, "# Version 2.0\nThe new release adds search.\n- Faster\n- Smaller\ngo run  ./cmd\n  --flag", text)
+}
+
+// newTestFetcher returns a fetcher of the local test server, whose policy
+// allows the server.
+func newTestFetcher(t *testing.T, handler http.Handler) (*Fetcher, *fakeAuditor, string) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	policy := DefaultPolicy()
+	policy.Enabled = true
+	policy.RateLimitPerMinute = 3
+	policy.AllowedDomains = []string{"127.0.0.1"}
+	auditor := &fakeAuditor{}
+	fetcher := New(&fakeStore{policy: policy}, auditor)
+	// The test server is on the loopback address
+	fetcher.transport = http.DefaultTransport
+	return fetcher, auditor, server.URL
+}
+
+func TestFetch(t *testing.T) {
+	var fetches atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+	})
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		assert.Equal(t, UserAgent, r.UserAgent())
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, "<html><head><title>Page</title></head><body><p>Fresh news</p></body></html>")
+	})
+	mux.HandleFunc("/private/page", func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("robots.txt was not respected")
+	})
+	mux.HandleFunc("/image.png", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		fmt.Fprint(w, "\x89PNG")
+	})
+	mux.HandleFunc("/big.txt", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, strings.Repeat("a", 2000))
+	})
+	mux.HandleFunc("/away", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://elsewhere.example/", http.StatusFound)
+	})
+	fetcher, auditor, baseURL := newTestFetcher(t, mux)
+	ctx := context.Background()
+
+	page, err := fetcher.Fetch(ctx, 1, baseURL+"/page#section")
+	require.NoError(t, err)
+	assert.Equal(t, "Page", page.Title)
+	assert.Equal(t, "Fresh news", page.Text)
+	assert.Equal(t, baseURL+"/page", page.URL)
+	assert.False(t, page.Cached)
+
+	page, err = fetcher.Fetch(ctx, 1, baseURL+"/page")
+	require.NoError(t, err)
+	assert.True(t, page.Cached)
+	assert.EqualValues(t, 1, fetches.Load())
+
+	_, err = fetcher.Fetch(ctx, 1, baseURL+"/private/page")
+	assert.ErrorIs(t, err, ErrDenied)
+
+	_, err = fetcher.Fetch(ctx, 2, baseURL+"/image.png")
+	assert.ErrorContains(t, err, "not a text document")
+
+	fetcher.policy.MaxBytes = 1024
+	page, err = fetcher.Fetch(ctx, 2, baseURL+"/big.txt")
+	require.NoError(t, err)
+	assert.True(t, page.Truncated)
+	assert.Len(t, page.Text, 1024)
+
+	_, err = fetcher.Fetch(ctx, 2, baseURL+"/away")
+	assert.ErrorIs(t, err, ErrDenied, "redirects leave the allowed domains")
+
+	_, err = fetcher.Fetch(ctx, 1, "ftp://example.com/file")
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	var taken []string
+	for _, event := range auditor.events {
+		taken = append(taken, event.ActionTaken)
+	}
+	assert.Equal(t, []string{"allowed", "denied", "failed", "allowed"}, taken[:4])
+}
+
+func TestFetchPolicy(t *testing.T) {
+	fetcher, _, baseURL := newTestFetcher(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	ctx := context.Background()
+
+	for i := range 3 {
+		_, err := fetcher.Fetch(ctx, 1, fmt.Sprintf("%s/%d", baseURL, i))
+		require.NoError(t, err, "sites without robots.txt allow everything")
+	}
+	_, err := fetcher.Fetch(ctx, 1, baseURL+"/4")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	_, err = fetcher.Fetch(ctx, 2, baseURL+"/4")
+	assert.NoError(t, err, "the limit is per user")
+
+	host, _, _ := net.SplitHostPort(strings.TrimPrefix(baseURL, "http://"))
+	policy := *fetcher.policy
+	policy.DeniedDomains = []string{host}
+	_, err = fetcher.SavePolicy(ctx, &policy)
+	require.NoError(t, err)
+	_, err = fetcher.Fetch(ctx, 3, baseURL+"/0")
+	assert.ErrorIs(t, err, ErrDenied)
+
+	policy.Enabled = false
+	_, err = fetcher.SavePolicy(ctx, &policy)
+	require.NoError(t, err)
+	_, err = fetcher.Fetch(ctx, 3, baseURL+"/0")
+	assert.ErrorIs(t, err, ErrDisabled)
+	assert.Nil(t, fetcher.ToolFor(3))
+}
+
+func TestFetchPrivateAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("a private address was fetched")
+	}))
+	defer server.Close()
+	policy := DefaultPolicy()
+	policy.Enabled = true
+	fetcher := New(&fakeStore{policy: policy}, nil)
+
+	_, err := fetcher.Fetch(context.Background(), 1, server.URL+"/page")
+	assert.ErrorIs(t, err, ErrDenied)
+	u, _ := url.Parse(server.URL)
+	_, err = fetcher.Fetch(context.Background(), 1, "http://localhost:"+u.Port()+"/page")
+	assert.ErrorIs(t, err, ErrDenied, "names are checked once resolved")
+}
+
+func TestTool(t *testing.T) {
+	fetcher, _, baseURL := newTestFetcher(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, strings.Repeat("界", maxToolRunes+1))
+	}))
+	var nilFetcher *Fetcher
+	assert.Nil(t, nilFetcher.ToolFor(1))
+
+	tool := fetcher.ToolFor(1)
+	require.NotNil(t, tool)
+	out, err := tool.Run(context.Background(), `{"url": "`+baseURL+`/doc.txt"}`)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "URL: "+baseURL+"/doc.txt\nFetched: "))
+	assert.True(t, strings.HasSuffix(out, "界\n\n[truncated]"))
+}
@@ -0,0 +1,533 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hrygo/divinesense/plugin/netguard"
+)
+
+// Events of the webhook registry.
+const (
+	EventBlockCompleted = "block.completed"
+	EventBlockError     = "block.error"
+	EventMemoCreated    = "memo.created"
+	EventMemoUpdated    = "memo.updated"
+	EventMemoDeleted    = "memo.deleted"
+	EventScheduleDue    = "schedule.due"
+	// EventScheduleReminder is a reminder of a schedule, for users choosing
+	// the webhook channel of the reminders.
+	EventScheduleReminder = "schedule.reminder"
+)
+
+// Events are the events endpoints can subscribe to.
+var Events = []string{
+	EventBlockCompleted, EventBlockError,
+	EventMemoCreated, EventMemoUpdated, EventMemoDeleted,
+	EventScheduleDue, EventScheduleReminder,
+}
+
+// Statuses of deliveries.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Headers of the callbacks.
+const (
+	HeaderEvent     = "X-DivineSense-Event"
+	HeaderDelivery  = "X-DivineSense-Delivery"
+	HeaderTimestamp = "X-DivineSense-Timestamp"
+	// HeaderSignature is "sha256=" and the hex HMAC-SHA256, keyed by the
+	// secret of the endpoint, of the timestamp, a dot and the body.
+	HeaderSignature = "X-DivineSense-Signature"
+)
+
+const (
+	// MaxAttempts is the number of attempts of a delivery before it fails.
+	MaxAttempts = 6
+	// DeliveryRetention is how long delivery logs are kept.
+	DeliveryRetention = 30 * 24 * time.Hour
+	// baseBackoff is the delay before the first retry, doubled for each of
+	// the next ones up to maxBackoff: 30s, 1m, 2m, 4m, 8m.
+	baseBackoff = 30 * time.Second
+	maxBackoff  = time.Hour
+	// claimLease is how long a delivery attempt holds its delivery.
+	claimLease = 5 * time.Minute
+	// pollInterval is how often due retries are looked for.
+	pollInterval = 10 * time.Second
+	// deliveryTimeout bounds each callback.
+	deliveryTimeout = 10 * time.Second
+	// deliveryBatchSize and deliveryWorkers bound the deliveries of a poll.
+	deliveryBatchSize = 50
+	deliveryWorkers   = 8
+	// maxErrorLength bounds the error logged for an attempt.
+	maxErrorLength = 512
+)
+
+var (
+	// ErrInvalid is returned for invalid endpoints.
+	ErrInvalid = errors.New("invalid webhook endpoint")
+	// ErrNotFound is returned for unknown endpoints.
+	ErrNotFound = errors.New("webhook endpoint not found")
+	// ErrDenied is returned when a user endpoint resolves to a non-public address.
+	ErrDenied = errors.New("webhook address not allowed")
+)
+
+// Endpoint is a URL receiving signed callbacks of events.
+type Endpoint struct {
+	ID int32 `json:"id"`
+	// UserID is the user whose events the endpoint receives, or 0 for an
+	// instance endpoint receiving the events of all the users.
+	UserID int32  `json:"user_id"`
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	// Secret keys the signatures. It is only returned when created.
+	Secret string `json:"-"`
+	// Events are the subscribed events; all of them when empty.
+	Events    []string `json:"events"`
+	Enabled   bool     `json:"enabled"`
+	CreatedTs int64    `json:"created_ts"`
+	UpdatedTs int64    `json:"updated_ts"`
+}
+
+// Validate checks and normalizes the fields a user supplies.
+func (e *Endpoint) Validate() error {
+	e.Name = strings.TrimSpace(e.Name)
+	if e.Name == "" || len(e.Name) > 64 {
+		return fmt.Errorf("%w: name must have 1 to 64 characters", ErrInvalid)
+	}
+	u, err := url.Parse(strings.TrimSpace(e.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalid)
+	}
+	e.URL = u.String()
+	events := []string{}
+	for _, event := range e.Events {
+		if !slices.Contains(Events, event) {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalid, event)
+		}
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+	e.Events = events
+	return nil
+}
+
+// Subscribes reports whether the endpoint receives an event.
+func (e *Endpoint) Subscribes(event string) bool {
+	return e.Enabled && (len(e.Events) == 0 || slices.Contains(e.Events, event))
+}
+
+// Delivery is the delivery log of an event to an endpoint.
+type Delivery struct {
+	ID         int64  `json:"id"`
+	EndpointID int32  `json:"endpoint_id"`
+	EventID    string `json:"event_id"`
+	Event      string `json:"event"`
+	// Payload is the body of the callback.
+	Payload  json.RawMessage `json:"payload"`
+	Status   string          `json:"status"`
+	Attempts int32           `json:"attempts"`
+	// ResponseCode is the HTTP status of the last attempt, 0 without response.
+	ResponseCode  int32  `json:"response_code"`
+	Error         string `json:"error"`
+	NextAttemptTs int64  `json:"next_attempt_ts"`
+	CreatedTs     int64  `json:"created_ts"`
+	UpdatedTs     int64  `json:"updated_ts"`
+}
+
+// Payload is the body of the callbacks.
+type Payload struct {
+	// ID identifies the event; the deliveries of an event share it.
+	ID        string `json:"id"`
+	Event     string `json:"event"`
+	UserID    int32  `json:"user_id"`
+	CreatedTs int64  `json:"created_ts"`
+	Data      any    `json:"data"`
+}
+
+// FindEndpoint specifies conditions for listing endpoints.
+type FindEndpoint struct {
+	ID      *int32
+	UserID  *int32
+	Enabled *bool
+	// Recipient restricts them to the endpoints receiving the events of a
+	// user: theirs and the instance ones.
+	Recipient *int32
+}
+
+// FindDelivery specifies conditions for listing deliveries, latest first.
+type FindDelivery struct {
+	EndpointID int32
+	Status     string
+	Limit      int
+}
+
+// Store persists endpoints and deliveries.
+type Store interface {
+	ListEndpoints(ctx context.Context, find *FindEndpoint) ([]*Endpoint, error)
+	CreateEndpoint(ctx context.Context, endpoint *Endpoint) (*Endpoint, error)
+	UpdateEndpoint(ctx context.Context, endpoint *Endpoint) (*Endpoint, error)
+	DeleteEndpoint(ctx context.Context, id int32) error
+	// EnqueueDeliveries saves pending deliveries, skipping those whose event
+	// was already delivered to their endpoint.
+	EnqueueDeliveries(ctx context.Context, deliveries []*Delivery) error
+	// ClaimDueDeliveries returns the pending deliveries due at now and
+	// postpones them by claimLease, so that a single worker attempts them.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*Delivery, error)
+	// SaveAttempt saves the outcome of an attempt.
+	SaveAttempt(ctx context.Context, delivery *Delivery) error
+	ListDeliveries(ctx context.Context, find *FindDelivery) ([]*Delivery, error)
+	// PruneDeliveries deletes the finished deliveries created before a time.
+	PruneDeliveries(ctx context.Context, before int64) (int64, error)
+}
+
+// Registry manages the endpoints and delivers the events with retries.
+type Registry struct {
+	store Store
+	// client posts to instance endpoints, which admins may point to private
+	// addresses; userClient posts to user endpoints, public addresses only.
+	client     *http.Client
+	userClient *http.Client
+	wake       chan struct{}
+	now        func() time.Time
+}
+
+// New creates a webhook registry.
+func New(store Store) *Registry {
+	return &Registry{
+		store:      store,
+		client:     &http.Client{Timeout: deliveryTimeout},
+		userClient: &http.Client{Timeout: deliveryTimeout, Transport: publicTransport()},
+		wake:       make(chan struct{}, 1),
+		now:        time.Now,
+	}
+}
+
+// List returns the endpoints of a user, or the instance ones for 0.
+func (r *Registry) List(ctx context.Context, userID int32) ([]*Endpoint, error) {
+	return r.store.ListEndpoints(ctx, &FindEndpoint{UserID: &userID})
+}
+
+// Get returns an endpoint of a user, or an instance one for 0.
+func (r *Registry) Get(ctx context.Context, userID, id int32) (*Endpoint, error) {
+	endpoints, err := r.store.ListEndpoints(ctx, &FindEndpoint{ID: &id, UserID: &userID})
+	if err != nil {
+		return nil, err
+	}
+	if len(endpoints) == 0 {
+		return nil, ErrNotFound
+	}
+	return endpoints[0], nil
+}
+
+// Create creates an endpoint with a new secret, returned once.
+func (r *Registry) Create(ctx context.Context, endpoint *Endpoint) (*Endpoint, string, error) {
+	if err := endpoint.Validate(); err != nil {
+		return nil, "", err
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	endpoint.Secret = secret
+	created, err := r.store.CreateEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, "", err
+	}
+	return created, secret, nil
+}
+
+// Update replaces the fields of an endpoint, keeping its secret.
+func (r *Registry) Update(ctx context.Context, endpoint *Endpoint) (*Endpoint, error) {
+	if err := endpoint.Validate(); err != nil {
+		return nil, err
+	}
+	return r.store.UpdateEndpoint(ctx, endpoint)
+}
+
+// Delete deletes an endpoint of a user, or an instance one for 0, with its
+// deliveries.
+func (r *Registry) Delete(ctx context.Context, userID, id int32) error {
+	if _, err := r.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	return r.store.DeleteEndpoint(ctx, id)
+}
+
+// Deliveries returns the latest deliveries of an endpoint.
+func (r *Registry) Deliveries(ctx context.Context, find *FindDelivery) ([]*Delivery, error) {
+	return r.store.ListDeliveries(ctx, find)
+}
+
+// Subscribers returns the users whose endpoints subscribe to an event, and
+// whether an instance endpoint does, which receives the events of all users.
+func (r *Registry) Subscribers(ctx context.Context, event string) ([]int32, bool, error) {
+	if r == nil {
+		return nil, false, nil
+	}
+	enabled := true
+	endpoints, err := r.store.ListEndpoints(ctx, &FindEndpoint{Enabled: &enabled})
+	if err != nil {
+		return nil, false, err
+	}
+	var users []int32
+	instance := false
+	for _, endpoint := range endpoints {
+		switch {
+		case !endpoint.Subscribes(event):
+		case endpoint.UserID == 0:
+			instance = true
+		case !slices.Contains(users, endpoint.UserID):
+			users = append(users, endpoint.UserID)
+		}
+	}
+	return users, instance, nil
+}
+
+// Publish queues an event of a user for the endpoints subscribing to it.
+// eventID deduplicates deliveries, e.g. of a schedule due; a random one is
+// used when empty. Safe to call on a nil Registry.
+func (r *Registry) Publish(ctx context.Context, event string, userID int32, eventID string, data any) error {
+	if r == nil {
+		return nil
+	}
+	enabled := true
+	endpoints, err := r.store.ListEndpoints(ctx, &FindEndpoint{Recipient: &userID, Enabled: &enabled})
+	if err != nil {
+		return err
+	}
+	endpoints = slices.DeleteFunc(endpoints, func(e *Endpoint) bool { return !e.Subscribes(event) })
+	if len(endpoints) == 0 {
+		return nil
+	}
+	if eventID == "" {
+		if eventID, err = newEventID(); err != nil {
+			return err
+		}
+	}
+	now := r.now()
+	payload, err := json.Marshal(&Payload{ID: eventID, Event: event, UserID: userID, CreatedTs: now.Unix(), Data: data})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	deliveries := make([]*Delivery, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		deliveries = append(deliveries, &Delivery{
+			EndpointID:    endpoint.ID,
+			EventID:       eventID,
+			Event:         event,
+			Payload:       payload,
+			Status:        StatusPending,
+			NextAttemptTs: now.Unix(),
+		})
+	}
+	if err := r.store.EnqueueDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run delivers the queued events as they are published, and retries the
+// failed ones, until ctx is done.
+func (r *Registry) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		r.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// RunOnce attempts the deliveries due now and returns how many it attempted.
+func (r *Registry) RunOnce(ctx context.Context) int {
+	deliveries, err := r.store.ClaimDueDeliveries(ctx, r.now(), deliveryBatchSize)
+	if err != nil {
+		slog.Warn("webhook: failed to claim due deliveries", "error", err)
+		return 0
+	}
+	endpoints := map[int32]*Endpoint{}
+	var wg sync.WaitGroup
+	workers := make(chan struct{}, deliveryWorkers)
+	for _, delivery := range deliveries {
+		endpoint, ok := endpoints[delivery.EndpointID]
+		if !ok {
+			found, err := r.store.ListEndpoints(ctx, &FindEndpoint{ID: &delivery.EndpointID})
+			if err != nil {
+				slog.Warn("webhook: failed to load endpoint", "endpoint_id", delivery.EndpointID, "error", err)
+				continue
+			}
+			if len(found) > 0 {
+				endpoint = found[0]
+			}
+			endpoints[delivery.EndpointID] = endpoint
+		}
+		if endpoint == nil {
+			// Deleted while the delivery was claimed
+			continue
+		}
+		wg.Add(1)
+		workers <- struct{}{}
+		go func() {
+			defer func() { <-workers; wg.Done() }()
+			r.attempt(ctx, endpoint, delivery)
+		}()
+	}
+	wg.Wait()
+	return len(deliveries)
+}
+
+// attempt posts a delivery and saves its outcome, scheduling a retry of the
+// failures worth retrying.
+func (r *Registry) attempt(ctx context.Context, endpoint *Endpoint, delivery *Delivery) {
+	code, err := r.post(ctx, endpoint, delivery)
+	now := r.now()
+	delivery.Attempts++
+	delivery.ResponseCode = int32(code)
+	delivery.UpdatedTs = now.Unix()
+	switch {
+	case err == nil:
+		delivery.Status = StatusSucceeded
+		delivery.Error = ""
+	case !endpoint.Enabled || !retryable(code) || delivery.Attempts >= MaxAttempts:
+		delivery.Status = StatusFailed
+		delivery.Error = truncate(err.Error())
+	default:
+		delivery.Status = StatusPending
+		delivery.Error = truncate(err.Error())
+		delivery.NextAttemptTs = now.Add(backoff(delivery.Attempts)).Unix()
+	}
+	if err := r.store.SaveAttempt(context.WithoutCancel(ctx), delivery); err != nil {
+		slog.Warn("webhook: failed to save delivery attempt", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// post sends a signed callback and returns the HTTP status of the response.
+func (r *Registry) post(ctx context.Context, endpoint *Endpoint, delivery *Delivery) (int, error) {
+	if !endpoint.Enabled {
+		return 0, errors.New("endpoint disabled")
+	}
+	timestamp := strconv.FormatInt(r.now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "DivineSense-Webhook/1.0")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, timestamp, delivery.Payload))
+	client := r.client
+	if endpoint.UserID != 0 {
+		client = r.userClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.StatusCode, nil
+}
+
+// Prune deletes the finished deliveries older than DeliveryRetention.
+func (r *Registry) Prune(ctx context.Context) error {
+	pruned, err := r.store.PruneDeliveries(ctx, r.now().Add(-DeliveryRetention).Unix())
+	if err != nil {
+		return err
+	}
+	if pruned > 0 {
+		slog.Info("webhook: pruned deliveries", "count", pruned)
+	}
+	return nil
+}
+
+// Sign returns the signature header of a callback body sent at timestamp.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether a signature header matches a callback body, for
+// receivers.
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// retryable reports whether a failed attempt is worth retrying: network
+// errors, timeouts, rate limits and server errors.
+func retryable(code int) bool {
+	return code == 0 || code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500
+}
+
+// backoff returns the delay before the retry following an attempt.
+func backoff(attempts int32) time.Duration {
+	delay := baseBackoff
+	for i := int32(1); i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+func truncate(s string) string {
+	if len(s) <= maxErrorLength {
+		return s
+	}
+	return s[:maxErrorLength]
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func newEventID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook event id: %w", err)
+	}
+	return "evt_" + hex.EncodeToString(b), nil
+}
+
+// publicTransport dials public addresses only, so that users cannot reach
+// the private network of the server through their endpoints.
+func publicTransport() *http.Transport {
+	return netguard.Transport(deliveryTimeout, ErrDenied)
+}
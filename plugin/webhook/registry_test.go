@@ -0,0 +1,328 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore is an in-memory Store.
+type memStore struct {
+	mu         sync.Mutex
+	endpoints  []*Endpoint
+	deliveries []*Delivery
+}
+
+func (s *memStore) ListEndpoints(_ context.Context, find *FindEndpoint) ([]*Endpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	endpoints := []*Endpoint{}
+	for _, e := range s.endpoints {
+		if (find.ID != nil && e.ID != *find.ID) ||
+			(find.UserID != nil && e.UserID != *find.UserID) ||
+			(find.Enabled != nil && e.Enabled != *find.Enabled) ||
+			(find.Recipient != nil && e.UserID != 0 && e.UserID != *find.Recipient) {
+			continue
+		}
+		endpoint := *e
+		endpoints = append(endpoints, &endpoint)
+	}
+	return endpoints, nil
+}
+
+func (s *memStore) CreateEndpoint(_ context.Context, endpoint *Endpoint) (*Endpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	created := *endpoint
+	created.ID = int32(len(s.endpoints) + 1)
+	s.endpoints = append(s.endpoints, &created)
+	result := created
+	return &result, nil
+}
+
+func (s *memStore) UpdateEndpoint(_ context.Context, endpoint *Endpoint) (*Endpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.endpoints {
+		if e.ID == endpoint.ID && e.UserID == endpoint.UserID {
+			e.Name, e.URL, e.Events, e.Enabled = endpoint.Name, endpoint.URL, endpoint.Events, endpoint.Enabled
+			updated := *e
+			return &updated, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *memStore) DeleteEndpoint(_ context.Context, id int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.endpoints = slices.DeleteFunc(s.endpoints, func(e *Endpoint) bool { return e.ID == id })
+	s.deliveries = slices.DeleteFunc(s.deliveries, func(d *Delivery) bool { return d.EndpointID == id })
+	return nil
+}
+
+func (s *memStore) EnqueueDeliveries(_ context.Context, deliveries []*Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, delivery := range deliveries {
+		if slices.ContainsFunc(s.deliveries, func(d *Delivery) bool {
+			return d.EndpointID == delivery.EndpointID && d.EventID == delivery.EventID
+		}) {
+			continue
+		}
+		saved := *delivery
+		saved.ID = int64(len(s.deliveries) + 1)
+		s.deliveries = append(s.deliveries, &saved)
+	}
+	return nil
+}
+
+func (s *memStore) ClaimDueDeliveries(_ context.Context, now time.Time, limit int) ([]*Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var claimed []*Delivery
+	for _, d := range s.deliveries {
+		if d.Status == StatusPending && d.NextAttemptTs <= now.Unix() && len(claimed) < limit {
+			d.NextAttemptTs = now.Add(claimLease).Unix()
+			delivery := *d
+			claimed = append(claimed, &delivery)
+		}
+	}
+	return claimed, nil
+}
+
+func (s *memStore) SaveAttempt(_ context.Context, delivery *Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, d := range s.deliveries {
+		if d.ID == delivery.ID {
+			saved := *delivery
+			s.deliveries[i] = &saved
+		}
+	}
+	return nil
+}
+
+func (s *memStore) ListDeliveries(_ context.Context, find *FindDelivery) ([]*Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deliveries := []*Delivery{}
+	for _, d := range slices.Backward(s.deliveries) {
+		if d.EndpointID == find.EndpointID && (find.Status == "" || d.Status == find.Status) && len(deliveries) < find.Limit {
+			delivery := *d
+			deliveries = append(deliveries, &delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (s *memStore) PruneDeliveries(_ context.Context, before int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.deliveries)
+	s.deliveries = slices.DeleteFunc(s.deliveries, func(d *Delivery) bool { return d.Status != StatusPending && d.CreatedTs < before })
+	return int64(n - len(s.deliveries)), nil
+}
+
+// receiver is a webhook receiver answering with the queued status codes,
+// then 200, and recording the callbacks with a valid signature.
+type receiver struct {
+	mu       sync.Mutex
+	secrets  map[string]string
+	codes    []int
+	received []*Payload
+	invalid  int
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if !Verify(rc.secrets[r.URL.Path], r.Header.Get(HeaderTimestamp), body, r.Header.Get(HeaderSignature)) {
+		rc.invalid++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if len(rc.codes) > 0 {
+		code := rc.codes[0]
+		rc.codes = rc.codes[1:]
+		w.WriteHeader(code)
+		return
+	}
+	payload := &Payload{}
+	if err := json.Unmarshal(body, payload); err == nil && payload.Event == r.Header.Get(HeaderEvent) {
+		rc.received = append(rc.received, payload)
+	}
+}
+
+func (rc *receiver) events() []string {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	var events []string
+	for _, payload := range rc.received {
+		events = append(events, payload.Event)
+	}
+	return events
+}
+
+// newTestRegistry creates a registry with a fake clock posting to a receiver
+// from any client, and an endpoint creator.
+func newTestRegistry(t *testing.T) (*Registry, *memStore, *receiver, *time.Time, func(path string, userID int32, events ...string) *Endpoint) {
+	t.Helper()
+	rc := &receiver{secrets: map[string]string{}}
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+	store := &memStore{}
+	registry := New(store)
+	registry.client, registry.userClient = server.Client(), server.Client()
+	now := time.Unix(1_800_000_000, 0)
+	registry.now = func() time.Time { return now }
+	create := func(path string, userID int32, events ...string) *Endpoint {
+		endpoint, secret, err := registry.Create(context.Background(), &Endpoint{UserID: userID, Name: path, URL: server.URL + path, Events: events, Enabled: true})
+		require.NoError(t, err)
+		rc.mu.Lock()
+		rc.secrets[path] = secret
+		rc.mu.Unlock()
+		return endpoint
+	}
+	return registry, store, rc, &now, create
+}
+
+func TestEndpointValidate(t *testing.T) {
+	endpoint := &Endpoint{Name: " n8n ", URL: "https://hooks.example.com/x", Events: []string{EventMemoCreated, EventMemoCreated}}
+	require.NoError(t, endpoint.Validate())
+	assert.Equal(t, "n8n", endpoint.Name)
+	assert.Equal(t, []string{EventMemoCreated}, endpoint.Events)
+
+	for _, invalid := range []*Endpoint{
+		{Name: "", URL: "https://hooks.example.com"},
+		{Name: "x", URL: "ftp://hooks.example.com"},
+		{Name: "x", URL: "/relative"},
+		{Name: "x", URL: "https://hooks.example.com", Events: []string{"memo.archived"}},
+	} {
+		assert.ErrorIs(t, invalid.Validate(), ErrInvalid, invalid)
+	}
+
+	assert.True(t, (&Endpoint{Enabled: true}).Subscribes(EventScheduleDue), "no events means all of them")
+	assert.False(t, (&Endpoint{Enabled: true, Events: []string{EventMemoCreated}}).Subscribes(EventScheduleDue))
+	assert.False(t, (&Endpoint{Events: []string{EventMemoCreated}}).Subscribes(EventMemoCreated))
+}
+
+func TestPublish(t *testing.T) {
+	registry, store, rc, _, create := newTestRegistry(t)
+	ctx := context.Background()
+	create("/alice", 1, EventMemoCreated, EventBlockCompleted)
+	create("/bob", 2)
+	create("/instance", 0, EventMemoCreated)
+
+	require.NoError(t, registry.Publish(ctx, EventMemoCreated, 1, "", map[string]string{"name": "memos/1"}))
+	require.NoError(t, registry.Publish(ctx, EventBlockError, 1, "", nil), "no endpoint subscribes")
+	require.NoError(t, registry.Publish(ctx, EventBlockCompleted, 1, "block.completed:b1", nil))
+	require.NoError(t, registry.Publish(ctx, EventBlockCompleted, 1, "block.completed:b1", nil), "duplicate")
+	assert.Len(t, store.deliveries, 3)
+
+	assert.Equal(t, 3, registry.RunOnce(ctx))
+	assert.ElementsMatch(t, []string{EventMemoCreated, EventMemoCreated, EventBlockCompleted}, rc.events())
+	assert.Zero(t, rc.invalid)
+	assert.EqualValues(t, 1, rc.received[0].UserID)
+	for _, d := range store.deliveries {
+		assert.Equal(t, StatusSucceeded, d.Status)
+		assert.EqualValues(t, http.StatusOK, d.ResponseCode)
+	}
+	assert.Zero(t, registry.RunOnce(ctx), "nothing is due")
+
+	users, instance, err := registry.Subscribers(ctx, EventScheduleDue)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{2}, users)
+	assert.False(t, instance)
+	_, instance, err = registry.Subscribers(ctx, EventMemoCreated)
+	require.NoError(t, err)
+	assert.True(t, instance)
+
+	var nilRegistry *Registry
+	assert.NoError(t, nilRegistry.Publish(ctx, EventMemoCreated, 1, "", nil))
+}
+
+func TestRetry(t *testing.T) {
+	registry, store, rc, now, create := newTestRegistry(t)
+	ctx := context.Background()
+	flaky := create("/flaky", 1)
+	rejecting := create("/rejecting", 2)
+	rc.codes = []int{http.StatusServiceUnavailable, http.StatusBadRequest}
+
+	require.NoError(t, registry.Publish(ctx, EventMemoCreated, 1, "", nil))
+	registry.RunOnce(ctx)
+	deliveries, err := registry.Deliveries(ctx, &FindDelivery{EndpointID: flaky.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, StatusPending, deliveries[0].Status)
+	assert.EqualValues(t, 1, deliveries[0].Attempts)
+	assert.EqualValues(t, http.StatusServiceUnavailable, deliveries[0].ResponseCode)
+	assert.Equal(t, now.Add(30*time.Second).Unix(), deliveries[0].NextAttemptTs)
+
+	require.NoError(t, registry.Publish(ctx, EventMemoCreated, 2, "", nil))
+	registry.RunOnce(ctx)
+	deliveries, err = registry.Deliveries(ctx, &FindDelivery{EndpointID: rejecting.ID, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, deliveries[0].Status, "client errors are not retried")
+	assert.Contains(t, deliveries[0].Error, "HTTP 400")
+
+	*now = now.Add(30 * time.Second)
+	assert.Equal(t, 1, registry.RunOnce(ctx))
+	deliveries, err = registry.Deliveries(ctx, &FindDelivery{EndpointID: flaky.ID, Status: StatusSucceeded, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.EqualValues(t, 2, deliveries[0].Attempts)
+	assert.Empty(t, deliveries[0].Error)
+
+	rc.codes = slices.Repeat([]int{http.StatusInternalServerError}, MaxAttempts)
+	require.NoError(t, registry.Publish(ctx, EventMemoUpdated, 1, "", nil))
+	for range MaxAttempts {
+		registry.RunOnce(ctx)
+		*now = now.Add(maxBackoff)
+	}
+	deliveries, err = registry.Deliveries(ctx, &FindDelivery{EndpointID: flaky.ID, Status: StatusFailed, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.EqualValues(t, MaxAttempts, deliveries[0].Attempts)
+
+	assert.Equal(t, []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute}, []time.Duration{backoff(1), backoff(2), backoff(3), backoff(4)})
+	assert.Equal(t, maxBackoff, backoff(20))
+
+	*now = now.Add(DeliveryRetention + time.Hour)
+	for _, d := range store.deliveries {
+		d.CreatedTs = now.Add(-DeliveryRetention - time.Minute).Unix()
+	}
+	require.NoError(t, registry.Prune(ctx))
+	assert.Empty(t, store.deliveries)
+}
+
+func TestUserEndpointsArePublicOnly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+	registry := New(&memStore{})
+	ctx := context.Background()
+	user, _, err := registry.Create(ctx, &Endpoint{UserID: 1, Name: "local", URL: server.URL, Enabled: true})
+	require.NoError(t, err)
+	instance, _, err := registry.Create(ctx, &Endpoint{Name: "local", URL: server.URL, Enabled: true})
+	require.NoError(t, err)
+
+	require.NoError(t, registry.Publish(ctx, EventMemoDeleted, 1, "", nil))
+	registry.RunOnce(ctx)
+	deliveries, err := registry.Deliveries(ctx, &FindDelivery{EndpointID: user.ID, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, deliveries[0].Status, "network errors are retried")
+	assert.Contains(t, deliveries[0].Error, "not a public address")
+	deliveries, err = registry.Deliveries(ctx, &FindDelivery{EndpointID: instance.ID, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, deliveries[0].Status, "instance endpoints may be private")
+}
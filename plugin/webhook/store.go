@@ -0,0 +1,224 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/hrygo/divinesense/internal/util"
+)
+
+// DBStore persists endpoints and deliveries in the webhook_endpoint and
+// webhook_delivery tables (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new webhook store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const endpointColumns = "id, COALESCE(user_id, 0), name, url, secret, events, enabled, created_ts, updated_ts"
+
+const deliveryColumns = "id, endpoint_id, event_id, event, payload, status, attempts, response_code, error, next_attempt_ts, created_ts, updated_ts"
+
+// ListEndpoints implements Store.
+func (s *DBStore) ListEndpoints(ctx context.Context, find *FindEndpoint) ([]*Endpoint, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if find.ID != nil {
+		args = append(args, *find.ID)
+		where = append(where, fmt.Sprintf("id = $%d", len(args)))
+	}
+	if find.UserID != nil {
+		args = append(args, *find.UserID)
+		where = append(where, fmt.Sprintf("COALESCE(user_id, 0) = $%d", len(args)))
+	}
+	if find.Enabled != nil {
+		args = append(args, *find.Enabled)
+		where = append(where, fmt.Sprintf("enabled = $%d", len(args)))
+	}
+	if find.Recipient != nil {
+		args = append(args, *find.Recipient)
+		where = append(where, fmt.Sprintf("(user_id IS NULL OR user_id = $%d)", len(args)))
+	}
+	rows, err := s.db.QueryContext(ctx, "SELECT "+endpointColumns+" FROM webhook_endpoint WHERE "+strings.Join(where, " AND ")+" ORDER BY id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+	defer rows.Close()
+
+	endpoints := []*Endpoint{}
+	for rows.Next() {
+		endpoint, err := scanEndpoint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook endpoint: %w", err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+	return endpoints, nil
+}
+
+// CreateEndpoint implements Store.
+func (s *DBStore) CreateEndpoint(ctx context.Context, endpoint *Endpoint) (*Endpoint, error) {
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO webhook_endpoint (user_id, name, url, secret, events, enabled)
+		VALUES (NULLIF($1, 0), $2, $3, $4, $5, $6)
+		RETURNING `+endpointColumns,
+		endpoint.UserID, endpoint.Name, endpoint.URL, endpoint.Secret, pq.Array(util.OrEmpty(endpoint.Events)), endpoint.Enabled)
+	created, err := scanEndpoint(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateEndpoint implements Store.
+func (s *DBStore) UpdateEndpoint(ctx context.Context, endpoint *Endpoint) (*Endpoint, error) {
+	row := s.db.QueryRowContext(ctx, `
+		UPDATE webhook_endpoint SET name = $3, url = $4, events = $5, enabled = $6, updated_ts = $7
+		WHERE id = $1 AND COALESCE(user_id, 0) = $2
+		RETURNING `+endpointColumns,
+		endpoint.ID, endpoint.UserID, endpoint.Name, endpoint.URL, pq.Array(util.OrEmpty(endpoint.Events)), endpoint.Enabled, time.Now().Unix())
+	updated, err := scanEndpoint(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update webhook endpoint: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteEndpoint implements Store.
+func (s *DBStore) DeleteEndpoint(ctx context.Context, id int32) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM webhook_endpoint WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook endpoint: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// EnqueueDeliveries implements Store.
+func (s *DBStore) EnqueueDeliveries(ctx context.Context, deliveries []*Delivery) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	for _, delivery := range deliveries {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO webhook_delivery (endpoint_id, event_id, event, payload, status, next_attempt_ts)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (endpoint_id, event_id) DO NOTHING`,
+			delivery.EndpointID, delivery.EventID, delivery.Event, []byte(delivery.Payload), delivery.Status, delivery.NextAttemptTs); err != nil {
+			return fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// ClaimDueDeliveries implements Store.
+func (s *DBStore) ClaimDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*Delivery, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		UPDATE webhook_delivery SET next_attempt_ts = $2
+		WHERE id IN (
+			SELECT id FROM webhook_delivery
+			WHERE status = 'pending' AND next_attempt_ts <= $1
+			ORDER BY next_attempt_ts, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+deliveryColumns,
+		now.Unix(), now.Add(claimLease).Unix(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	return scanDeliveries(rows)
+}
+
+// SaveAttempt implements Store.
+func (s *DBStore) SaveAttempt(ctx context.Context, delivery *Delivery) error {
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE webhook_delivery
+		SET status = $2, attempts = $3, response_code = $4, error = $5, next_attempt_ts = $6, updated_ts = $7
+		WHERE id = $1`,
+		delivery.ID, delivery.Status, delivery.Attempts, delivery.ResponseCode, delivery.Error, delivery.NextAttemptTs, delivery.UpdatedTs); err != nil {
+		return fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries implements Store.
+func (s *DBStore) ListDeliveries(ctx context.Context, find *FindDelivery) ([]*Delivery, error) {
+	query, args := "SELECT "+deliveryColumns+" FROM webhook_delivery WHERE endpoint_id = $1", []any{find.EndpointID}
+	if find.Status != "" {
+		args = append(args, find.Status)
+		query += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+	args = append(args, find.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return scanDeliveries(rows)
+}
+
+// PruneDeliveries implements Store.
+func (s *DBStore) PruneDeliveries(ctx context.Context, before int64) (int64, error) {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM webhook_delivery WHERE status <> 'pending' AND created_ts < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune webhook deliveries: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEndpoint(row rowScanner) (*Endpoint, error) {
+	endpoint := &Endpoint{}
+	if err := row.Scan(&endpoint.ID, &endpoint.UserID, &endpoint.Name, &endpoint.URL, &endpoint.Secret,
+		pq.Array(&endpoint.Events), &endpoint.Enabled, &endpoint.CreatedTs, &endpoint.UpdatedTs); err != nil {
+		return nil, err
+	}
+	endpoint.Events = util.OrEmpty(endpoint.Events)
+	return endpoint, nil
+}
+
+func scanDeliveries(rows *sql.Rows) ([]*Delivery, error) {
+	defer rows.Close()
+	deliveries := []*Delivery{}
+	for rows.Next() {
+		delivery := &Delivery{}
+		var payload []byte
+		if err := rows.Scan(&delivery.ID, &delivery.EndpointID, &delivery.EventID, &delivery.Event, &payload,
+			&delivery.Status, &delivery.Attempts, &delivery.ResponseCode, &delivery.Error,
+			&delivery.NextAttemptTs, &delivery.CreatedTs, &delivery.UpdatedTs); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		delivery.Payload = payload
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
@@ -23,6 +23,28 @@ type WebhookRequestPayload struct {
 	URL          string     `json:"url"`
 	ActivityType string     `json:"activityType"`
 	Creator      string     `json:"creator"`
+	// Chat is set for chat activities, e.g. a scheduled message was answered.
+	Chat *ChatPayload `json:"chat,omitempty"`
+	// Report is set for reports, e.g. the weekly knowledge-base health report.
+	Report *ReportPayload `json:"report,omitempty"`
+}
+
+// ChatPayload describes a chat activity.
+type ChatPayload struct {
+	ConversationID int32  `json:"conversationId"`
+	BlockID        int64  `json:"blockId,omitempty"`
+	Message        string `json:"message"`
+	Status         string `json:"status"`
+	Error          string `json:"error,omitempty"`
+}
+
+// ReportPayload is a report sent to the user.
+type ReportPayload struct {
+	Title string `json:"title"`
+	// Markdown is the report rendered for reading.
+	Markdown string `json:"markdown"`
+	// Data is the report itself.
+	Data any `json:"data"`
 }
 
 // Post posts the message to webhook endpoint.
@@ -11,6 +11,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -297,6 +298,7 @@ const (
 	BlockStatus_BLOCK_STATUS_STREAMING   BlockStatus = 2 // AI is currently responding
 	BlockStatus_BLOCK_STATUS_COMPLETED   BlockStatus = 3 // Response completed
 	BlockStatus_BLOCK_STATUS_ERROR       BlockStatus = 4 // Error occurred
+	BlockStatus_BLOCK_STATUS_INTERRUPTED BlockStatus = 5 // Stopped by the user
 )
 
 // Enum value maps for BlockStatus.
@@ -307,6 +309,7 @@ var (
 		2: "BLOCK_STATUS_STREAMING",
 		3: "BLOCK_STATUS_COMPLETED",
 		4: "BLOCK_STATUS_ERROR",
+		5: "BLOCK_STATUS_INTERRUPTED",
 	}
 	BlockStatus_value = map[string]int32{
 		"BLOCK_STATUS_UNSPECIFIED": 0,
@@ -314,6 +317,7 @@ var (
 		"BLOCK_STATUS_STREAMING":   2,
 		"BLOCK_STATUS_COMPLETED":   3,
 		"BLOCK_STATUS_ERROR":       4,
+		"BLOCK_STATUS_INTERRUPTED": 5,
 	}
 )
 
@@ -344,6 +348,156 @@ func (BlockStatus) EnumDescriptor() ([]byte, []int) {
 	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{5}
 }
 
+// TranscriptDetail is the detail of a block transcript.
+type TranscriptDetail int32
+
+const (
+	TranscriptDetail_TRANSCRIPT_DETAIL_UNSPECIFIED TranscriptDetail = 0 // Minimal
+	TranscriptDetail_TRANSCRIPT_DETAIL_MINIMAL     TranscriptDetail = 1 // The final answer and a one-liner per tool call
+	TranscriptDetail_TRANSCRIPT_DETAIL_FULL        TranscriptDetail = 2 // Also the thinking, tool inputs and outputs, and errors, in order
+)
+
+// Enum value maps for TranscriptDetail.
+var (
+	TranscriptDetail_name = map[int32]string{
+		0: "TRANSCRIPT_DETAIL_UNSPECIFIED",
+		1: "TRANSCRIPT_DETAIL_MINIMAL",
+		2: "TRANSCRIPT_DETAIL_FULL",
+	}
+	TranscriptDetail_value = map[string]int32{
+		"TRANSCRIPT_DETAIL_UNSPECIFIED": 0,
+		"TRANSCRIPT_DETAIL_MINIMAL":     1,
+		"TRANSCRIPT_DETAIL_FULL":        2,
+	}
+)
+
+func (x TranscriptDetail) Enum() *TranscriptDetail {
+	p := new(TranscriptDetail)
+	*p = x
+	return p
+}
+
+func (x TranscriptDetail) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TranscriptDetail) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_v1_ai_service_proto_enumTypes[6].Descriptor()
+}
+
+func (TranscriptDetail) Type() protoreflect.EnumType {
+	return &file_api_v1_ai_service_proto_enumTypes[6]
+}
+
+func (x TranscriptDetail) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TranscriptDetail.Descriptor instead.
+func (TranscriptDetail) EnumDescriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{6}
+}
+
+// ConversationImportFormat is the format of an exported conversation.
+type ConversationImportFormat int32
+
+const (
+	ConversationImportFormat_CONVERSATION_IMPORT_FORMAT_UNSPECIFIED ConversationImportFormat = 0 // Detected from the data
+	ConversationImportFormat_CONVERSATION_IMPORT_FORMAT_CHATGPT     ConversationImportFormat = 1
+	ConversationImportFormat_CONVERSATION_IMPORT_FORMAT_CLAUDE      ConversationImportFormat = 2
+)
+
+// Enum value maps for ConversationImportFormat.
+var (
+	ConversationImportFormat_name = map[int32]string{
+		0: "CONVERSATION_IMPORT_FORMAT_UNSPECIFIED",
+		1: "CONVERSATION_IMPORT_FORMAT_CHATGPT",
+		2: "CONVERSATION_IMPORT_FORMAT_CLAUDE",
+	}
+	ConversationImportFormat_value = map[string]int32{
+		"CONVERSATION_IMPORT_FORMAT_UNSPECIFIED": 0,
+		"CONVERSATION_IMPORT_FORMAT_CHATGPT":     1,
+		"CONVERSATION_IMPORT_FORMAT_CLAUDE":      2,
+	}
+)
+
+func (x ConversationImportFormat) Enum() *ConversationImportFormat {
+	p := new(ConversationImportFormat)
+	*p = x
+	return p
+}
+
+func (x ConversationImportFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ConversationImportFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_v1_ai_service_proto_enumTypes[7].Descriptor()
+}
+
+func (ConversationImportFormat) Type() protoreflect.EnumType {
+	return &file_api_v1_ai_service_proto_enumTypes[7]
+}
+
+func (x ConversationImportFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ConversationImportFormat.Descriptor instead.
+func (ConversationImportFormat) EnumDescriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{7}
+}
+
+// ActionAuditExportFormat is the format of an audit export.
+type ActionAuditExportFormat int32
+
+const (
+	ActionAuditExportFormat_ACTION_AUDIT_EXPORT_FORMAT_UNSPECIFIED ActionAuditExportFormat = 0 // CSV
+	ActionAuditExportFormat_ACTION_AUDIT_EXPORT_FORMAT_CSV         ActionAuditExportFormat = 1
+	ActionAuditExportFormat_ACTION_AUDIT_EXPORT_FORMAT_JSONL       ActionAuditExportFormat = 2
+)
+
+// Enum value maps for ActionAuditExportFormat.
+var (
+	ActionAuditExportFormat_name = map[int32]string{
+		0: "ACTION_AUDIT_EXPORT_FORMAT_UNSPECIFIED",
+		1: "ACTION_AUDIT_EXPORT_FORMAT_CSV",
+		2: "ACTION_AUDIT_EXPORT_FORMAT_JSONL",
+	}
+	ActionAuditExportFormat_value = map[string]int32{
+		"ACTION_AUDIT_EXPORT_FORMAT_UNSPECIFIED": 0,
+		"ACTION_AUDIT_EXPORT_FORMAT_CSV":         1,
+		"ACTION_AUDIT_EXPORT_FORMAT_JSONL":       2,
+	}
+)
+
+func (x ActionAuditExportFormat) Enum() *ActionAuditExportFormat {
+	p := new(ActionAuditExportFormat)
+	*p = x
+	return p
+}
+
+func (x ActionAuditExportFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ActionAuditExportFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_v1_ai_service_proto_enumTypes[8].Descriptor()
+}
+
+func (ActionAuditExportFormat) Type() protoreflect.EnumType {
+	return &file_api_v1_ai_service_proto_enumTypes[8]
+}
+
+func (x ActionAuditExportFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ActionAuditExportFormat.Descriptor instead.
+func (ActionAuditExportFormat) EnumDescriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{8}
+}
+
 // SemanticSearchRequest is the request for SemanticSearch.
 type SemanticSearchRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1515,6 +1669,97 @@ func (x *ClearConversationMessagesRequest) GetConversationId() int32 {
 	return 0
 }
 
+// CreateChatStreamTicketRequest is the request for CreateChatStreamTicket.
+type CreateChatStreamTicketRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateChatStreamTicketRequest) Reset() {
+	*x = CreateChatStreamTicketRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateChatStreamTicketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateChatStreamTicketRequest) ProtoMessage() {}
+
+func (x *CreateChatStreamTicketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateChatStreamTicketRequest.ProtoReflect.Descriptor instead.
+func (*CreateChatStreamTicketRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{21}
+}
+
+// ChatStreamTicket authenticates a single chat stream, passed as its ticket
+// query parameter.
+type ChatStreamTicket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticket        string                 `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	ExpiresTs     int64                  `protobuf:"varint,2,opt,name=expires_ts,json=expiresTs,proto3" json:"expires_ts,omitempty"` // Unix timestamp after which the ticket is rejected
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatStreamTicket) Reset() {
+	*x = ChatStreamTicket{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatStreamTicket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatStreamTicket) ProtoMessage() {}
+
+func (x *ChatStreamTicket) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatStreamTicket.ProtoReflect.Descriptor instead.
+func (*ChatStreamTicket) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ChatStreamTicket) GetTicket() string {
+	if x != nil {
+		return x.Ticket
+	}
+	return ""
+}
+
+func (x *ChatStreamTicket) GetExpiresTs() int64 {
+	if x != nil {
+		return x.ExpiresTs
+	}
+	return 0
+}
+
 // StopChatRequest is the request for stopping an ongoing chat stream.
 type StopChatRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
@@ -1526,7 +1771,7 @@ type StopChatRequest struct {
 
 func (x *StopChatRequest) Reset() {
 	*x = StopChatRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[21]
+	mi := &file_api_v1_ai_service_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1538,7 +1783,7 @@ func (x *StopChatRequest) String() string {
 func (*StopChatRequest) ProtoMessage() {}
 
 func (x *StopChatRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[21]
+	mi := &file_api_v1_ai_service_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1551,7 +1796,7 @@ func (x *StopChatRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StopChatRequest.ProtoReflect.Descriptor instead.
 func (*StopChatRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{21}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *StopChatRequest) GetConversationId() int32 {
@@ -1568,6 +1813,75 @@ func (x *StopChatRequest) GetReason() string {
 	return ""
 }
 
+// SubmitFormRequest answers the form_request event pending on a block.
+type SubmitFormRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BlockId       int64                  `protobuf:"varint,1,opt,name=block_id,json=blockId,proto3" json:"block_id,omitempty"` // Block waiting on the form
+	FormId        string                 `protobuf:"bytes,2,opt,name=form_id,json=formId,proto3" json:"form_id,omitempty"`     // Must match the pending form, guarding against stale submissions
+	Payload       *structpb.Struct       `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`                 // Answers keyed by field name
+	Timezone      string                 `protobuf:"bytes,4,opt,name=timezone,proto3" json:"timezone,omitempty"`               // User timezone for the follow-up (IANA format, e.g., "Asia/Shanghai")
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitFormRequest) Reset() {
+	*x = SubmitFormRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitFormRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitFormRequest) ProtoMessage() {}
+
+func (x *SubmitFormRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitFormRequest.ProtoReflect.Descriptor instead.
+func (*SubmitFormRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SubmitFormRequest) GetBlockId() int64 {
+	if x != nil {
+		return x.BlockId
+	}
+	return 0
+}
+
+func (x *SubmitFormRequest) GetFormId() string {
+	if x != nil {
+		return x.FormId
+	}
+	return ""
+}
+
+func (x *SubmitFormRequest) GetPayload() *structpb.Struct {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *SubmitFormRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
 // DangerBlockEvent represents a dangerous operation that was blocked.
 type DangerBlockEvent struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
@@ -1581,7 +1895,7 @@ type DangerBlockEvent struct {
 
 func (x *DangerBlockEvent) Reset() {
 	*x = DangerBlockEvent{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[22]
+	mi := &file_api_v1_ai_service_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1593,7 +1907,7 @@ func (x *DangerBlockEvent) String() string {
 func (*DangerBlockEvent) ProtoMessage() {}
 
 func (x *DangerBlockEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[22]
+	mi := &file_api_v1_ai_service_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1606,7 +1920,7 @@ func (x *DangerBlockEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DangerBlockEvent.ProtoReflect.Descriptor instead.
 func (*DangerBlockEvent) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{22}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *DangerBlockEvent) GetOperation() string {
@@ -1653,14 +1967,16 @@ type ChatResponse struct {
 	// Block summary sent when done=true (statistics for this single chat round)
 	BlockSummary *BlockSummary `protobuf:"bytes,9,opt,name=block_summary,json=blockSummary,proto3" json:"block_summary,omitempty"` // Summary statistics for the completed block
 	// Phase 4: Block ID for Unified Block Model
-	BlockId       int64 `protobuf:"varint,10,opt,name=block_id,json=blockId,proto3" json:"block_id,omitempty"` // Block ID for this conversation round (allows frontend to update Block state during streaming)
+	BlockId int64 `protobuf:"varint,10,opt,name=block_id,json=blockId,proto3" json:"block_id,omitempty"` // Block ID for this conversation round (allows frontend to update Block state during streaming)
+	// Tracing: OpenTelemetry trace ID of this chat round, for debug views to link to the trace
+	TraceId       string `protobuf:"bytes,11,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"` // Hex trace ID; empty when tracing is off or the round is not sampled
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ChatResponse) Reset() {
 	*x = ChatResponse{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[23]
+	mi := &file_api_v1_ai_service_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1672,7 +1988,7 @@ func (x *ChatResponse) String() string {
 func (*ChatResponse) ProtoMessage() {}
 
 func (x *ChatResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[23]
+	mi := &file_api_v1_ai_service_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1685,7 +2001,7 @@ func (x *ChatResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChatResponse.ProtoReflect.Descriptor instead.
 func (*ChatResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{23}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *ChatResponse) GetContent() string {
@@ -1758,6 +2074,13 @@ func (x *ChatResponse) GetBlockId() int64 {
 	return 0
 }
 
+func (x *ChatResponse) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
 // ScheduleCreationIntent represents AI's analysis of user's intent to create a schedule.
 type ScheduleCreationIntent struct {
 	state               protoimpl.MessageState `protogen:"open.v1"`
@@ -1770,7 +2093,7 @@ type ScheduleCreationIntent struct {
 
 func (x *ScheduleCreationIntent) Reset() {
 	*x = ScheduleCreationIntent{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[24]
+	mi := &file_api_v1_ai_service_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1782,7 +2105,7 @@ func (x *ScheduleCreationIntent) String() string {
 func (*ScheduleCreationIntent) ProtoMessage() {}
 
 func (x *ScheduleCreationIntent) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[24]
+	mi := &file_api_v1_ai_service_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1795,7 +2118,7 @@ func (x *ScheduleCreationIntent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ScheduleCreationIntent.ProtoReflect.Descriptor instead.
 func (*ScheduleCreationIntent) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{24}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *ScheduleCreationIntent) GetDetected() bool {
@@ -1832,7 +2155,7 @@ type ScheduleQueryResult struct {
 
 func (x *ScheduleQueryResult) Reset() {
 	*x = ScheduleQueryResult{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[25]
+	mi := &file_api_v1_ai_service_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1844,7 +2167,7 @@ func (x *ScheduleQueryResult) String() string {
 func (*ScheduleQueryResult) ProtoMessage() {}
 
 func (x *ScheduleQueryResult) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[25]
+	mi := &file_api_v1_ai_service_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1857,7 +2180,7 @@ func (x *ScheduleQueryResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ScheduleQueryResult.ProtoReflect.Descriptor instead.
 func (*ScheduleQueryResult) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{25}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *ScheduleQueryResult) GetDetected() bool {
@@ -1906,7 +2229,7 @@ type ScheduleSummary struct {
 
 func (x *ScheduleSummary) Reset() {
 	*x = ScheduleSummary{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[26]
+	mi := &file_api_v1_ai_service_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1918,7 +2241,7 @@ func (x *ScheduleSummary) String() string {
 func (*ScheduleSummary) ProtoMessage() {}
 
 func (x *ScheduleSummary) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[26]
+	mi := &file_api_v1_ai_service_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1931,7 +2254,7 @@ func (x *ScheduleSummary) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ScheduleSummary.ProtoReflect.Descriptor instead.
 func (*ScheduleSummary) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{26}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *ScheduleSummary) GetUid() string {
@@ -2001,7 +2324,7 @@ type GetRelatedMemosRequest struct {
 
 func (x *GetRelatedMemosRequest) Reset() {
 	*x = GetRelatedMemosRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[27]
+	mi := &file_api_v1_ai_service_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2013,7 +2336,7 @@ func (x *GetRelatedMemosRequest) String() string {
 func (*GetRelatedMemosRequest) ProtoMessage() {}
 
 func (x *GetRelatedMemosRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[27]
+	mi := &file_api_v1_ai_service_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2026,7 +2349,7 @@ func (x *GetRelatedMemosRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRelatedMemosRequest.ProtoReflect.Descriptor instead.
 func (*GetRelatedMemosRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{27}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *GetRelatedMemosRequest) GetName() string {
@@ -2053,7 +2376,7 @@ type GetRelatedMemosResponse struct {
 
 func (x *GetRelatedMemosResponse) Reset() {
 	*x = GetRelatedMemosResponse{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[28]
+	mi := &file_api_v1_ai_service_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2065,7 +2388,7 @@ func (x *GetRelatedMemosResponse) String() string {
 func (*GetRelatedMemosResponse) ProtoMessage() {}
 
 func (x *GetRelatedMemosResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[28]
+	mi := &file_api_v1_ai_service_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2078,7 +2401,7 @@ func (x *GetRelatedMemosResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRelatedMemosResponse.ProtoReflect.Descriptor instead.
 func (*GetRelatedMemosResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{28}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *GetRelatedMemosResponse) GetMemos() []*SearchResult {
@@ -2107,7 +2430,7 @@ type ParrotSelfCognition struct {
 
 func (x *ParrotSelfCognition) Reset() {
 	*x = ParrotSelfCognition{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[29]
+	mi := &file_api_v1_ai_service_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2119,7 +2442,7 @@ func (x *ParrotSelfCognition) String() string {
 func (*ParrotSelfCognition) ProtoMessage() {}
 
 func (x *ParrotSelfCognition) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[29]
+	mi := &file_api_v1_ai_service_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2132,7 +2455,7 @@ func (x *ParrotSelfCognition) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ParrotSelfCognition.ProtoReflect.Descriptor instead.
 func (*ParrotSelfCognition) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{29}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *ParrotSelfCognition) GetName() string {
@@ -2215,7 +2538,7 @@ type GetParrotSelfCognitionRequest struct {
 
 func (x *GetParrotSelfCognitionRequest) Reset() {
 	*x = GetParrotSelfCognitionRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[30]
+	mi := &file_api_v1_ai_service_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2227,7 +2550,7 @@ func (x *GetParrotSelfCognitionRequest) String() string {
 func (*GetParrotSelfCognitionRequest) ProtoMessage() {}
 
 func (x *GetParrotSelfCognitionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[30]
+	mi := &file_api_v1_ai_service_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2240,7 +2563,7 @@ func (x *GetParrotSelfCognitionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetParrotSelfCognitionRequest.ProtoReflect.Descriptor instead.
 func (*GetParrotSelfCognitionRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{30}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *GetParrotSelfCognitionRequest) GetAgentType() AgentType {
@@ -2260,7 +2583,7 @@ type GetParrotSelfCognitionResponse struct {
 
 func (x *GetParrotSelfCognitionResponse) Reset() {
 	*x = GetParrotSelfCognitionResponse{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[31]
+	mi := &file_api_v1_ai_service_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2272,7 +2595,7 @@ func (x *GetParrotSelfCognitionResponse) String() string {
 func (*GetParrotSelfCognitionResponse) ProtoMessage() {}
 
 func (x *GetParrotSelfCognitionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[31]
+	mi := &file_api_v1_ai_service_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2285,7 +2608,7 @@ func (x *GetParrotSelfCognitionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetParrotSelfCognitionResponse.ProtoReflect.Descriptor instead.
 func (*GetParrotSelfCognitionResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{31}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *GetParrotSelfCognitionResponse) GetSelfCognition() *ParrotSelfCognition {
@@ -2304,7 +2627,7 @@ type ListParrotsRequest struct {
 
 func (x *ListParrotsRequest) Reset() {
 	*x = ListParrotsRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[32]
+	mi := &file_api_v1_ai_service_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2316,7 +2639,7 @@ func (x *ListParrotsRequest) String() string {
 func (*ListParrotsRequest) ProtoMessage() {}
 
 func (x *ListParrotsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[32]
+	mi := &file_api_v1_ai_service_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2329,7 +2652,7 @@ func (x *ListParrotsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListParrotsRequest.ProtoReflect.Descriptor instead.
 func (*ListParrotsRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{32}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{35}
 }
 
 // ListParrotsResponse is the response for ListParrots.
@@ -2342,7 +2665,7 @@ type ListParrotsResponse struct {
 
 func (x *ListParrotsResponse) Reset() {
 	*x = ListParrotsResponse{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[33]
+	mi := &file_api_v1_ai_service_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2354,7 +2677,7 @@ func (x *ListParrotsResponse) String() string {
 func (*ListParrotsResponse) ProtoMessage() {}
 
 func (x *ListParrotsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[33]
+	mi := &file_api_v1_ai_service_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2367,7 +2690,7 @@ func (x *ListParrotsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListParrotsResponse.ProtoReflect.Descriptor instead.
 func (*ListParrotsResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{33}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *ListParrotsResponse) GetParrots() []*ParrotInfo {
@@ -2389,7 +2712,7 @@ type ParrotInfo struct {
 
 func (x *ParrotInfo) Reset() {
 	*x = ParrotInfo{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[34]
+	mi := &file_api_v1_ai_service_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2401,7 +2724,7 @@ func (x *ParrotInfo) String() string {
 func (*ParrotInfo) ProtoMessage() {}
 
 func (x *ParrotInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[34]
+	mi := &file_api_v1_ai_service_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2414,7 +2737,7 @@ func (x *ParrotInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ParrotInfo.ProtoReflect.Descriptor instead.
 func (*ParrotInfo) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{34}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *ParrotInfo) GetAgentType() AgentType {
@@ -2451,7 +2774,7 @@ type DetectDuplicatesRequest struct {
 
 func (x *DetectDuplicatesRequest) Reset() {
 	*x = DetectDuplicatesRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[35]
+	mi := &file_api_v1_ai_service_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2463,7 +2786,7 @@ func (x *DetectDuplicatesRequest) String() string {
 func (*DetectDuplicatesRequest) ProtoMessage() {}
 
 func (x *DetectDuplicatesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[35]
+	mi := &file_api_v1_ai_service_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2476,7 +2799,7 @@ func (x *DetectDuplicatesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DetectDuplicatesRequest.ProtoReflect.Descriptor instead.
 func (*DetectDuplicatesRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{35}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *DetectDuplicatesRequest) GetTitle() string {
@@ -2521,7 +2844,7 @@ type DetectDuplicatesResponse struct {
 
 func (x *DetectDuplicatesResponse) Reset() {
 	*x = DetectDuplicatesResponse{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[36]
+	mi := &file_api_v1_ai_service_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2533,7 +2856,7 @@ func (x *DetectDuplicatesResponse) String() string {
 func (*DetectDuplicatesResponse) ProtoMessage() {}
 
 func (x *DetectDuplicatesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[36]
+	mi := &file_api_v1_ai_service_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2546,7 +2869,7 @@ func (x *DetectDuplicatesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DetectDuplicatesResponse.ProtoReflect.Descriptor instead.
 func (*DetectDuplicatesResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{36}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *DetectDuplicatesResponse) GetHasDuplicate() bool {
@@ -2601,7 +2924,7 @@ type SimilarMemo struct {
 
 func (x *SimilarMemo) Reset() {
 	*x = SimilarMemo{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[37]
+	mi := &file_api_v1_ai_service_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2613,7 +2936,7 @@ func (x *SimilarMemo) String() string {
 func (*SimilarMemo) ProtoMessage() {}
 
 func (x *SimilarMemo) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[37]
+	mi := &file_api_v1_ai_service_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2626,7 +2949,7 @@ func (x *SimilarMemo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SimilarMemo.ProtoReflect.Descriptor instead.
 func (*SimilarMemo) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{37}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *SimilarMemo) GetId() string {
@@ -2697,7 +3020,7 @@ type SimilarityBreakdown struct {
 
 func (x *SimilarityBreakdown) Reset() {
 	*x = SimilarityBreakdown{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[38]
+	mi := &file_api_v1_ai_service_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2709,7 +3032,7 @@ func (x *SimilarityBreakdown) String() string {
 func (*SimilarityBreakdown) ProtoMessage() {}
 
 func (x *SimilarityBreakdown) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[38]
+	mi := &file_api_v1_ai_service_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2722,7 +3045,7 @@ func (x *SimilarityBreakdown) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SimilarityBreakdown.ProtoReflect.Descriptor instead.
 func (*SimilarityBreakdown) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{38}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *SimilarityBreakdown) GetVector() float64 {
@@ -2757,7 +3080,7 @@ type MergeMemosRequest struct {
 
 func (x *MergeMemosRequest) Reset() {
 	*x = MergeMemosRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[39]
+	mi := &file_api_v1_ai_service_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2769,7 +3092,7 @@ func (x *MergeMemosRequest) String() string {
 func (*MergeMemosRequest) ProtoMessage() {}
 
 func (x *MergeMemosRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[39]
+	mi := &file_api_v1_ai_service_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2782,7 +3105,7 @@ func (x *MergeMemosRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MergeMemosRequest.ProtoReflect.Descriptor instead.
 func (*MergeMemosRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{39}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *MergeMemosRequest) GetSourceName() string {
@@ -2809,7 +3132,7 @@ type MergeMemosResponse struct {
 
 func (x *MergeMemosResponse) Reset() {
 	*x = MergeMemosResponse{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[40]
+	mi := &file_api_v1_ai_service_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2821,7 +3144,7 @@ func (x *MergeMemosResponse) String() string {
 func (*MergeMemosResponse) ProtoMessage() {}
 
 func (x *MergeMemosResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[40]
+	mi := &file_api_v1_ai_service_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2834,7 +3157,7 @@ func (x *MergeMemosResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MergeMemosResponse.ProtoReflect.Descriptor instead.
 func (*MergeMemosResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{40}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{43}
 }
 
 func (x *MergeMemosResponse) GetMergedName() string {
@@ -2855,7 +3178,7 @@ type LinkMemosRequest struct {
 
 func (x *LinkMemosRequest) Reset() {
 	*x = LinkMemosRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[41]
+	mi := &file_api_v1_ai_service_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2867,7 +3190,7 @@ func (x *LinkMemosRequest) String() string {
 func (*LinkMemosRequest) ProtoMessage() {}
 
 func (x *LinkMemosRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[41]
+	mi := &file_api_v1_ai_service_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2880,7 +3203,7 @@ func (x *LinkMemosRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LinkMemosRequest.ProtoReflect.Descriptor instead.
 func (*LinkMemosRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{41}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{44}
 }
 
 func (x *LinkMemosRequest) GetMemoName_1() string {
@@ -2907,7 +3230,7 @@ type LinkMemosResponse struct {
 
 func (x *LinkMemosResponse) Reset() {
 	*x = LinkMemosResponse{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[42]
+	mi := &file_api_v1_ai_service_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2919,7 +3242,7 @@ func (x *LinkMemosResponse) String() string {
 func (*LinkMemosResponse) ProtoMessage() {}
 
 func (x *LinkMemosResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[42]
+	mi := &file_api_v1_ai_service_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2932,7 +3255,7 @@ func (x *LinkMemosResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LinkMemosResponse.ProtoReflect.Descriptor instead.
 func (*LinkMemosResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{42}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{45}
 }
 
 func (x *LinkMemosResponse) GetSuccess() bool {
@@ -2954,7 +3277,7 @@ type GetKnowledgeGraphRequest struct {
 
 func (x *GetKnowledgeGraphRequest) Reset() {
 	*x = GetKnowledgeGraphRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[43]
+	mi := &file_api_v1_ai_service_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2966,7 +3289,7 @@ func (x *GetKnowledgeGraphRequest) String() string {
 func (*GetKnowledgeGraphRequest) ProtoMessage() {}
 
 func (x *GetKnowledgeGraphRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[43]
+	mi := &file_api_v1_ai_service_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2979,7 +3302,7 @@ func (x *GetKnowledgeGraphRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetKnowledgeGraphRequest.ProtoReflect.Descriptor instead.
 func (*GetKnowledgeGraphRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{43}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{46}
 }
 
 func (x *GetKnowledgeGraphRequest) GetTags() []string {
@@ -3016,7 +3339,7 @@ type GetKnowledgeGraphResponse struct {
 
 func (x *GetKnowledgeGraphResponse) Reset() {
 	*x = GetKnowledgeGraphResponse{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[44]
+	mi := &file_api_v1_ai_service_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3028,7 +3351,7 @@ func (x *GetKnowledgeGraphResponse) String() string {
 func (*GetKnowledgeGraphResponse) ProtoMessage() {}
 
 func (x *GetKnowledgeGraphResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[44]
+	mi := &file_api_v1_ai_service_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3041,7 +3364,7 @@ func (x *GetKnowledgeGraphResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetKnowledgeGraphResponse.ProtoReflect.Descriptor instead.
 func (*GetKnowledgeGraphResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{44}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{47}
 }
 
 func (x *GetKnowledgeGraphResponse) GetNodes() []*GraphNode {
@@ -3088,7 +3411,7 @@ type GraphNode struct {
 
 func (x *GraphNode) Reset() {
 	*x = GraphNode{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[45]
+	mi := &file_api_v1_ai_service_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3100,7 +3423,7 @@ func (x *GraphNode) String() string {
 func (*GraphNode) ProtoMessage() {}
 
 func (x *GraphNode) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[45]
+	mi := &file_api_v1_ai_service_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3113,7 +3436,7 @@ func (x *GraphNode) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GraphNode.ProtoReflect.Descriptor instead.
 func (*GraphNode) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{45}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{48}
 }
 
 func (x *GraphNode) GetId() string {
@@ -3178,7 +3501,7 @@ type GraphEdge struct {
 
 func (x *GraphEdge) Reset() {
 	*x = GraphEdge{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[46]
+	mi := &file_api_v1_ai_service_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3190,7 +3513,7 @@ func (x *GraphEdge) String() string {
 func (*GraphEdge) ProtoMessage() {}
 
 func (x *GraphEdge) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[46]
+	mi := &file_api_v1_ai_service_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3203,7 +3526,7 @@ func (x *GraphEdge) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GraphEdge.ProtoReflect.Descriptor instead.
 func (*GraphEdge) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{46}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{49}
 }
 
 func (x *GraphEdge) GetSource() string {
@@ -3249,7 +3572,7 @@ type GraphStats struct {
 
 func (x *GraphStats) Reset() {
 	*x = GraphStats{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[47]
+	mi := &file_api_v1_ai_service_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3261,7 +3584,7 @@ func (x *GraphStats) String() string {
 func (*GraphStats) ProtoMessage() {}
 
 func (x *GraphStats) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[47]
+	mi := &file_api_v1_ai_service_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3274,7 +3597,7 @@ func (x *GraphStats) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GraphStats.ProtoReflect.Descriptor instead.
 func (*GraphStats) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{47}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{50}
 }
 
 func (x *GraphStats) GetNodeCount() int32 {
@@ -3329,7 +3652,7 @@ type GetDueReviewsRequest struct {
 
 func (x *GetDueReviewsRequest) Reset() {
 	*x = GetDueReviewsRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[48]
+	mi := &file_api_v1_ai_service_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3341,7 +3664,7 @@ func (x *GetDueReviewsRequest) String() string {
 func (*GetDueReviewsRequest) ProtoMessage() {}
 
 func (x *GetDueReviewsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[48]
+	mi := &file_api_v1_ai_service_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3354,7 +3677,7 @@ func (x *GetDueReviewsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetDueReviewsRequest.ProtoReflect.Descriptor instead.
 func (*GetDueReviewsRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{48}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{51}
 }
 
 func (x *GetDueReviewsRequest) GetLimit() int32 {
@@ -3375,7 +3698,7 @@ type GetDueReviewsResponse struct {
 
 func (x *GetDueReviewsResponse) Reset() {
 	*x = GetDueReviewsResponse{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[49]
+	mi := &file_api_v1_ai_service_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3387,7 +3710,7 @@ func (x *GetDueReviewsResponse) String() string {
 func (*GetDueReviewsResponse) ProtoMessage() {}
 
 func (x *GetDueReviewsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[49]
+	mi := &file_api_v1_ai_service_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3400,7 +3723,7 @@ func (x *GetDueReviewsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetDueReviewsResponse.ProtoReflect.Descriptor instead.
 func (*GetDueReviewsResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{49}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{52}
 }
 
 func (x *GetDueReviewsResponse) GetItems() []*ReviewItem {
@@ -3436,7 +3759,7 @@ type ReviewItem struct {
 
 func (x *ReviewItem) Reset() {
 	*x = ReviewItem{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[50]
+	mi := &file_api_v1_ai_service_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3448,7 +3771,7 @@ func (x *ReviewItem) String() string {
 func (*ReviewItem) ProtoMessage() {}
 
 func (x *ReviewItem) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[50]
+	mi := &file_api_v1_ai_service_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3461,7 +3784,7 @@ func (x *ReviewItem) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReviewItem.ProtoReflect.Descriptor instead.
 func (*ReviewItem) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{50}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{53}
 }
 
 func (x *ReviewItem) GetMemoUid() string {
@@ -3545,7 +3868,7 @@ type RecordReviewRequest struct {
 
 func (x *RecordReviewRequest) Reset() {
 	*x = RecordReviewRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[51]
+	mi := &file_api_v1_ai_service_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3557,7 +3880,7 @@ func (x *RecordReviewRequest) String() string {
 func (*RecordReviewRequest) ProtoMessage() {}
 
 func (x *RecordReviewRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[51]
+	mi := &file_api_v1_ai_service_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3570,7 +3893,7 @@ func (x *RecordReviewRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RecordReviewRequest.ProtoReflect.Descriptor instead.
 func (*RecordReviewRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{51}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{54}
 }
 
 func (x *RecordReviewRequest) GetMemoUid() string {
@@ -3600,7 +3923,7 @@ type RecordRouterFeedbackRequest struct {
 
 func (x *RecordRouterFeedbackRequest) Reset() {
 	*x = RecordRouterFeedbackRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[52]
+	mi := &file_api_v1_ai_service_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3612,7 +3935,7 @@ func (x *RecordRouterFeedbackRequest) String() string {
 func (*RecordRouterFeedbackRequest) ProtoMessage() {}
 
 func (x *RecordRouterFeedbackRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[52]
+	mi := &file_api_v1_ai_service_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3625,7 +3948,7 @@ func (x *RecordRouterFeedbackRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RecordRouterFeedbackRequest.ProtoReflect.Descriptor instead.
 func (*RecordRouterFeedbackRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{52}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{55}
 }
 
 func (x *RecordRouterFeedbackRequest) GetInput() string {
@@ -3665,7 +3988,7 @@ type GetReviewStatsRequest struct {
 
 func (x *GetReviewStatsRequest) Reset() {
 	*x = GetReviewStatsRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[53]
+	mi := &file_api_v1_ai_service_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3677,7 +4000,7 @@ func (x *GetReviewStatsRequest) String() string {
 func (*GetReviewStatsRequest) ProtoMessage() {}
 
 func (x *GetReviewStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[53]
+	mi := &file_api_v1_ai_service_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3690,7 +4013,7 @@ func (x *GetReviewStatsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReviewStatsRequest.ProtoReflect.Descriptor instead.
 func (*GetReviewStatsRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{53}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{56}
 }
 
 // GetReviewStatsResponse is the response for GetReviewStats.
@@ -3710,7 +4033,7 @@ type GetReviewStatsResponse struct {
 
 func (x *GetReviewStatsResponse) Reset() {
 	*x = GetReviewStatsResponse{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[54]
+	mi := &file_api_v1_ai_service_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3722,7 +4045,7 @@ func (x *GetReviewStatsResponse) String() string {
 func (*GetReviewStatsResponse) ProtoMessage() {}
 
 func (x *GetReviewStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[54]
+	mi := &file_api_v1_ai_service_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3735,7 +4058,7 @@ func (x *GetReviewStatsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReviewStatsResponse.ProtoReflect.Descriptor instead.
 func (*GetReviewStatsResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{54}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{57}
 }
 
 func (x *GetReviewStatsResponse) GetTotalMemos() int32 {
@@ -3824,7 +4147,7 @@ type EventMetadata struct {
 
 func (x *EventMetadata) Reset() {
 	*x = EventMetadata{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[55]
+	mi := &file_api_v1_ai_service_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3836,7 +4159,7 @@ func (x *EventMetadata) String() string {
 func (*EventMetadata) ProtoMessage() {}
 
 func (x *EventMetadata) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[55]
+	mi := &file_api_v1_ai_service_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3849,7 +4172,7 @@ func (x *EventMetadata) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EventMetadata.ProtoReflect.Descriptor instead.
 func (*EventMetadata) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{55}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{58}
 }
 
 func (x *EventMetadata) GetDurationMs() int64 {
@@ -3988,7 +4311,7 @@ type BlockSummary struct {
 
 func (x *BlockSummary) Reset() {
 	*x = BlockSummary{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[56]
+	mi := &file_api_v1_ai_service_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4000,7 +4323,7 @@ func (x *BlockSummary) String() string {
 func (*BlockSummary) ProtoMessage() {}
 
 func (x *BlockSummary) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[56]
+	mi := &file_api_v1_ai_service_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4013,7 +4336,7 @@ func (x *BlockSummary) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BlockSummary.ProtoReflect.Descriptor instead.
 func (*BlockSummary) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{56}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{59}
 }
 
 func (x *BlockSummary) GetSessionId() string {
@@ -4163,7 +4486,7 @@ type SessionStats struct {
 
 func (x *SessionStats) Reset() {
 	*x = SessionStats{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[57]
+	mi := &file_api_v1_ai_service_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4175,7 +4498,7 @@ func (x *SessionStats) String() string {
 func (*SessionStats) ProtoMessage() {}
 
 func (x *SessionStats) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[57]
+	mi := &file_api_v1_ai_service_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4188,7 +4511,7 @@ func (x *SessionStats) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SessionStats.ProtoReflect.Descriptor instead.
 func (*SessionStats) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{57}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{60}
 }
 
 func (x *SessionStats) GetId() int64 {
@@ -4383,7 +4706,7 @@ type GetSessionStatsRequest struct {
 
 func (x *GetSessionStatsRequest) Reset() {
 	*x = GetSessionStatsRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[58]
+	mi := &file_api_v1_ai_service_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4395,7 +4718,7 @@ func (x *GetSessionStatsRequest) String() string {
 func (*GetSessionStatsRequest) ProtoMessage() {}
 
 func (x *GetSessionStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[58]
+	mi := &file_api_v1_ai_service_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4408,7 +4731,7 @@ func (x *GetSessionStatsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetSessionStatsRequest.ProtoReflect.Descriptor instead.
 func (*GetSessionStatsRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{58}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{61}
 }
 
 func (x *GetSessionStatsRequest) GetSessionId() string {
@@ -4430,7 +4753,7 @@ type ListSessionStatsRequest struct {
 
 func (x *ListSessionStatsRequest) Reset() {
 	*x = ListSessionStatsRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[59]
+	mi := &file_api_v1_ai_service_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4442,7 +4765,7 @@ func (x *ListSessionStatsRequest) String() string {
 func (*ListSessionStatsRequest) ProtoMessage() {}
 
 func (x *ListSessionStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[59]
+	mi := &file_api_v1_ai_service_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4455,7 +4778,7 @@ func (x *ListSessionStatsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSessionStatsRequest.ProtoReflect.Descriptor instead.
 func (*ListSessionStatsRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{59}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{62}
 }
 
 func (x *ListSessionStatsRequest) GetLimit() int32 {
@@ -4491,7 +4814,7 @@ type ListSessionStatsResponse struct {
 
 func (x *ListSessionStatsResponse) Reset() {
 	*x = ListSessionStatsResponse{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[60]
+	mi := &file_api_v1_ai_service_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4503,7 +4826,7 @@ func (x *ListSessionStatsResponse) String() string {
 func (*ListSessionStatsResponse) ProtoMessage() {}
 
 func (x *ListSessionStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[60]
+	mi := &file_api_v1_ai_service_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4516,7 +4839,7 @@ func (x *ListSessionStatsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSessionStatsResponse.ProtoReflect.Descriptor instead.
 func (*ListSessionStatsResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{60}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{63}
 }
 
 func (x *ListSessionStatsResponse) GetSessions() []*SessionStats {
@@ -4550,7 +4873,7 @@ type GetCostStatsRequest struct {
 
 func (x *GetCostStatsRequest) Reset() {
 	*x = GetCostStatsRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[61]
+	mi := &file_api_v1_ai_service_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4562,7 +4885,7 @@ func (x *GetCostStatsRequest) String() string {
 func (*GetCostStatsRequest) ProtoMessage() {}
 
 func (x *GetCostStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[61]
+	mi := &file_api_v1_ai_service_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4575,7 +4898,7 @@ func (x *GetCostStatsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetCostStatsRequest.ProtoReflect.Descriptor instead.
 func (*GetCostStatsRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{61}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{64}
 }
 
 func (x *GetCostStatsRequest) GetDays() int32 {
@@ -4599,7 +4922,7 @@ type CostStats struct {
 
 func (x *CostStats) Reset() {
 	*x = CostStats{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[62]
+	mi := &file_api_v1_ai_service_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4611,7 +4934,7 @@ func (x *CostStats) String() string {
 func (*CostStats) ProtoMessage() {}
 
 func (x *CostStats) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[62]
+	mi := &file_api_v1_ai_service_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4624,7 +4947,7 @@ func (x *CostStats) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CostStats.ProtoReflect.Descriptor instead.
 func (*CostStats) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{62}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{65}
 }
 
 func (x *CostStats) GetTotalCostUsd() float64 {
@@ -4674,7 +4997,7 @@ type DailyCostData struct {
 
 func (x *DailyCostData) Reset() {
 	*x = DailyCostData{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[63]
+	mi := &file_api_v1_ai_service_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4686,7 +5009,7 @@ func (x *DailyCostData) String() string {
 func (*DailyCostData) ProtoMessage() {}
 
 func (x *DailyCostData) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[63]
+	mi := &file_api_v1_ai_service_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4699,7 +5022,7 @@ func (x *DailyCostData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DailyCostData.ProtoReflect.Descriptor instead.
 func (*DailyCostData) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{63}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{66}
 }
 
 func (x *DailyCostData) GetDate() string {
@@ -4738,7 +5061,7 @@ type UserCostSettings struct {
 
 func (x *UserCostSettings) Reset() {
 	*x = UserCostSettings{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[64]
+	mi := &file_api_v1_ai_service_proto_msgTypes[67]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4750,7 +5073,7 @@ func (x *UserCostSettings) String() string {
 func (*UserCostSettings) ProtoMessage() {}
 
 func (x *UserCostSettings) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[64]
+	mi := &file_api_v1_ai_service_proto_msgTypes[67]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4763,7 +5086,7 @@ func (x *UserCostSettings) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserCostSettings.ProtoReflect.Descriptor instead.
 func (*UserCostSettings) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{64}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{67}
 }
 
 func (x *UserCostSettings) GetDailyBudgetUsd() float64 {
@@ -4822,7 +5145,7 @@ type SetUserCostSettingsRequest struct {
 
 func (x *SetUserCostSettingsRequest) Reset() {
 	*x = SetUserCostSettingsRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[65]
+	mi := &file_api_v1_ai_service_proto_msgTypes[68]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4834,7 +5157,7 @@ func (x *SetUserCostSettingsRequest) String() string {
 func (*SetUserCostSettingsRequest) ProtoMessage() {}
 
 func (x *SetUserCostSettingsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[65]
+	mi := &file_api_v1_ai_service_proto_msgTypes[68]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4847,7 +5170,7 @@ func (x *SetUserCostSettingsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetUserCostSettingsRequest.ProtoReflect.Descriptor instead.
 func (*SetUserCostSettingsRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{65}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{68}
 }
 
 func (x *SetUserCostSettingsRequest) GetDailyBudgetUsd() float64 {
@@ -4936,7 +5259,7 @@ type Block struct {
 
 func (x *Block) Reset() {
 	*x = Block{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[66]
+	mi := &file_api_v1_ai_service_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4948,7 +5271,7 @@ func (x *Block) String() string {
 func (*Block) ProtoMessage() {}
 
 func (x *Block) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[66]
+	mi := &file_api_v1_ai_service_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4961,7 +5284,7 @@ func (x *Block) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Block.ProtoReflect.Descriptor instead.
 func (*Block) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{66}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{69}
 }
 
 func (x *Block) GetId() int64 {
@@ -5153,7 +5476,7 @@ type TokenUsage struct {
 
 func (x *TokenUsage) Reset() {
 	*x = TokenUsage{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[67]
+	mi := &file_api_v1_ai_service_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5165,7 +5488,7 @@ func (x *TokenUsage) String() string {
 func (*TokenUsage) ProtoMessage() {}
 
 func (x *TokenUsage) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[67]
+	mi := &file_api_v1_ai_service_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5178,7 +5501,7 @@ func (x *TokenUsage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TokenUsage.ProtoReflect.Descriptor instead.
 func (*TokenUsage) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{67}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{70}
 }
 
 func (x *TokenUsage) GetPromptTokens() int32 {
@@ -5228,7 +5551,7 @@ type UserInput struct {
 
 func (x *UserInput) Reset() {
 	*x = UserInput{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[68]
+	mi := &file_api_v1_ai_service_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5240,7 +5563,7 @@ func (x *UserInput) String() string {
 func (*UserInput) ProtoMessage() {}
 
 func (x *UserInput) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[68]
+	mi := &file_api_v1_ai_service_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5253,7 +5576,7 @@ func (x *UserInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserInput.ProtoReflect.Descriptor instead.
 func (*UserInput) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{68}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{71}
 }
 
 func (x *UserInput) GetContent() string {
@@ -5291,7 +5614,7 @@ type BlockEvent struct {
 
 func (x *BlockEvent) Reset() {
 	*x = BlockEvent{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[69]
+	mi := &file_api_v1_ai_service_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5303,7 +5626,7 @@ func (x *BlockEvent) String() string {
 func (*BlockEvent) ProtoMessage() {}
 
 func (x *BlockEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[69]
+	mi := &file_api_v1_ai_service_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5316,7 +5639,7 @@ func (x *BlockEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BlockEvent.ProtoReflect.Descriptor instead.
 func (*BlockEvent) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{69}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{72}
 }
 
 func (x *BlockEvent) GetType() string {
@@ -5363,7 +5686,7 @@ type ListBlocksRequest struct {
 
 func (x *ListBlocksRequest) Reset() {
 	*x = ListBlocksRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[70]
+	mi := &file_api_v1_ai_service_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5375,7 +5698,7 @@ func (x *ListBlocksRequest) String() string {
 func (*ListBlocksRequest) ProtoMessage() {}
 
 func (x *ListBlocksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[70]
+	mi := &file_api_v1_ai_service_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5388,7 +5711,7 @@ func (x *ListBlocksRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListBlocksRequest.ProtoReflect.Descriptor instead.
 func (*ListBlocksRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{70}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{73}
 }
 
 func (x *ListBlocksRequest) GetConversationId() int32 {
@@ -5448,7 +5771,7 @@ type ListBlocksResponse struct {
 
 func (x *ListBlocksResponse) Reset() {
 	*x = ListBlocksResponse{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[71]
+	mi := &file_api_v1_ai_service_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5460,7 +5783,7 @@ func (x *ListBlocksResponse) String() string {
 func (*ListBlocksResponse) ProtoMessage() {}
 
 func (x *ListBlocksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[71]
+	mi := &file_api_v1_ai_service_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5473,7 +5796,7 @@ func (x *ListBlocksResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListBlocksResponse.ProtoReflect.Descriptor instead.
 func (*ListBlocksResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{71}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{74}
 }
 
 func (x *ListBlocksResponse) GetBlocks() []*Block {
@@ -5521,7 +5844,7 @@ type GetBlockRequest struct {
 
 func (x *GetBlockRequest) Reset() {
 	*x = GetBlockRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[72]
+	mi := &file_api_v1_ai_service_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5533,7 +5856,7 @@ func (x *GetBlockRequest) String() string {
 func (*GetBlockRequest) ProtoMessage() {}
 
 func (x *GetBlockRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[72]
+	mi := &file_api_v1_ai_service_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5546,7 +5869,7 @@ func (x *GetBlockRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBlockRequest.ProtoReflect.Descriptor instead.
 func (*GetBlockRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{72}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{75}
 }
 
 func (x *GetBlockRequest) GetId() int64 {
@@ -5571,7 +5894,7 @@ type CreateBlockRequest struct {
 
 func (x *CreateBlockRequest) Reset() {
 	*x = CreateBlockRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[73]
+	mi := &file_api_v1_ai_service_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5583,7 +5906,7 @@ func (x *CreateBlockRequest) String() string {
 func (*CreateBlockRequest) ProtoMessage() {}
 
 func (x *CreateBlockRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[73]
+	mi := &file_api_v1_ai_service_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5596,7 +5919,7 @@ func (x *CreateBlockRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateBlockRequest.ProtoReflect.Descriptor instead.
 func (*CreateBlockRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{73}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{76}
 }
 
 func (x *CreateBlockRequest) GetConversationId() int32 {
@@ -5657,7 +5980,7 @@ type UpdateBlockRequest struct {
 
 func (x *UpdateBlockRequest) Reset() {
 	*x = UpdateBlockRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[74]
+	mi := &file_api_v1_ai_service_proto_msgTypes[77]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5669,7 +5992,7 @@ func (x *UpdateBlockRequest) String() string {
 func (*UpdateBlockRequest) ProtoMessage() {}
 
 func (x *UpdateBlockRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[74]
+	mi := &file_api_v1_ai_service_proto_msgTypes[77]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5682,7 +6005,7 @@ func (x *UpdateBlockRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateBlockRequest.ProtoReflect.Descriptor instead.
 func (*UpdateBlockRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{74}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{77}
 }
 
 func (x *UpdateBlockRequest) GetId() int64 {
@@ -5744,7 +6067,7 @@ type DeleteBlockRequest struct {
 
 func (x *DeleteBlockRequest) Reset() {
 	*x = DeleteBlockRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[75]
+	mi := &file_api_v1_ai_service_proto_msgTypes[78]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5756,7 +6079,7 @@ func (x *DeleteBlockRequest) String() string {
 func (*DeleteBlockRequest) ProtoMessage() {}
 
 func (x *DeleteBlockRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[75]
+	mi := &file_api_v1_ai_service_proto_msgTypes[78]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5769,7 +6092,7 @@ func (x *DeleteBlockRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteBlockRequest.ProtoReflect.Descriptor instead.
 func (*DeleteBlockRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{75}
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{78}
 }
 
 func (x *DeleteBlockRequest) GetId() int64 {
@@ -5788,21 +6111,3268 @@ type AppendUserInputRequest struct {
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AppendUserInputRequest) Reset() {
-	*x = AppendUserInputRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[76]
+func (x *AppendUserInputRequest) Reset() {
+	*x = AppendUserInputRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppendUserInputRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppendUserInputRequest) ProtoMessage() {}
+
+func (x *AppendUserInputRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppendUserInputRequest.ProtoReflect.Descriptor instead.
+func (*AppendUserInputRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *AppendUserInputRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AppendUserInputRequest) GetInput() *UserInput {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+// AppendEventRequest is the request for AppendEvent.
+type AppendEventRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"` // Block ID
+	Event         *BlockEvent            `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AppendEventRequest) Reset() {
+	*x = AppendEventRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppendEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppendEventRequest) ProtoMessage() {}
+
+func (x *AppendEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppendEventRequest.ProtoReflect.Descriptor instead.
+func (*AppendEventRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *AppendEventRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AppendEventRequest) GetEvent() *BlockEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+// ForkBlockRequest is the request for ForkBlock.
+type ForkBlockRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                                                         // Parent block ID
+	Reason            *string                `protobuf:"bytes,2,opt,name=reason,proto3,oneof" json:"reason,omitempty"`                                            // Optional reason for forking (e.g., "regenerate", "edit")
+	ReplaceUserInputs []*UserInput           `protobuf:"bytes,3,rep,name=replace_user_inputs,json=replaceUserInputs,proto3" json:"replace_user_inputs,omitempty"` // Optional: replace inherited user inputs with new ones (for message editing)
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ForkBlockRequest) Reset() {
+	*x = ForkBlockRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForkBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForkBlockRequest) ProtoMessage() {}
+
+func (x *ForkBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForkBlockRequest.ProtoReflect.Descriptor instead.
+func (*ForkBlockRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *ForkBlockRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ForkBlockRequest) GetReason() string {
+	if x != nil && x.Reason != nil {
+		return *x.Reason
+	}
+	return ""
+}
+
+func (x *ForkBlockRequest) GetReplaceUserInputs() []*UserInput {
+	if x != nil {
+		return x.ReplaceUserInputs
+	}
+	return nil
+}
+
+// ListBlockBranchesRequest is the request for ListBlockBranches.
+type ListBlockBranchesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"` // Root block ID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlockBranchesRequest) Reset() {
+	*x = ListBlockBranchesRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlockBranchesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlockBranchesRequest) ProtoMessage() {}
+
+func (x *ListBlockBranchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlockBranchesRequest.ProtoReflect.Descriptor instead.
+func (*ListBlockBranchesRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *ListBlockBranchesRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// ListBlockBranchesResponse is the response for ListBlockBranches.
+type ListBlockBranchesResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Branches         []*BlockBranch         `protobuf:"bytes,1,rep,name=branches,proto3" json:"branches,omitempty"`
+	ActiveBranchPath string                 `protobuf:"bytes,2,opt,name=active_branch_path,json=activeBranchPath,proto3" json:"active_branch_path,omitempty"` // Currently active branch path
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ListBlockBranchesResponse) Reset() {
+	*x = ListBlockBranchesResponse{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlockBranchesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlockBranchesResponse) ProtoMessage() {}
+
+func (x *ListBlockBranchesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlockBranchesResponse.ProtoReflect.Descriptor instead.
+func (*ListBlockBranchesResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *ListBlockBranchesResponse) GetBranches() []*BlockBranch {
+	if x != nil {
+		return x.Branches
+	}
+	return nil
+}
+
+func (x *ListBlockBranchesResponse) GetActiveBranchPath() string {
+	if x != nil {
+		return x.ActiveBranchPath
+	}
+	return ""
+}
+
+// BlockBranch represents a branch in the conversation tree.
+type BlockBranch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Block         *Block                 `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`                             // The block at this branch position
+	BranchPath    string                 `protobuf:"bytes,2,opt,name=branch_path,json=branchPath,proto3" json:"branch_path,omitempty"` // Path from root to this block (e.g., "0/1/3")
+	IsActive      bool                   `protobuf:"varint,3,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`      // Whether this is the currently active branch
+	Children      []*BlockBranch         `protobuf:"bytes,4,rep,name=children,proto3" json:"children,omitempty"`                       // Child branches
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockBranch) Reset() {
+	*x = BlockBranch{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockBranch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockBranch) ProtoMessage() {}
+
+func (x *BlockBranch) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockBranch.ProtoReflect.Descriptor instead.
+func (*BlockBranch) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *BlockBranch) GetBlock() *Block {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+func (x *BlockBranch) GetBranchPath() string {
+	if x != nil {
+		return x.BranchPath
+	}
+	return ""
+}
+
+func (x *BlockBranch) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *BlockBranch) GetChildren() []*BlockBranch {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+// SwitchBranchRequest is the request for SwitchBranch.
+type SwitchBranchRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId   int32                  `protobuf:"varint,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	TargetBranchPath string                 `protobuf:"bytes,2,opt,name=target_branch_path,json=targetBranchPath,proto3" json:"target_branch_path,omitempty"` // Target branch to activate
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SwitchBranchRequest) Reset() {
+	*x = SwitchBranchRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SwitchBranchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SwitchBranchRequest) ProtoMessage() {}
+
+func (x *SwitchBranchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SwitchBranchRequest.ProtoReflect.Descriptor instead.
+func (*SwitchBranchRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *SwitchBranchRequest) GetConversationId() int32 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+func (x *SwitchBranchRequest) GetTargetBranchPath() string {
+	if x != nil {
+		return x.TargetBranchPath
+	}
+	return ""
+}
+
+// DeleteBranchRequest is the request for DeleteBranch.
+type DeleteBranchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`           // Block ID to delete (and all descendants)
+	Cascade       bool                   `protobuf:"varint,2,opt,name=cascade,proto3" json:"cascade,omitempty"` // If true, delete all descendants; otherwise only delete this block
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteBranchRequest) Reset() {
+	*x = DeleteBranchRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBranchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBranchRequest) ProtoMessage() {}
+
+func (x *DeleteBranchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBranchRequest.ProtoReflect.Descriptor instead.
+func (*DeleteBranchRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *DeleteBranchRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeleteBranchRequest) GetCascade() bool {
+	if x != nil {
+		return x.Cascade
+	}
+	return false
+}
+
+// GetUsageRequest is the request for GetUsage.
+type GetUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageRequest) Reset() {
+	*x = GetUsageRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageRequest) ProtoMessage() {}
+
+func (x *GetUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetUsageRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{87}
+}
+
+// Usage is the user's AI usage of the current calendar month (UTC).
+type Usage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PeriodStart   int64                  `protobuf:"varint,1,opt,name=period_start,json=periodStart,proto3" json:"period_start,omitempty"` // Unix timestamp of the month start
+	PeriodEnd     int64                  `protobuf:"varint,2,opt,name=period_end,json=periodEnd,proto3" json:"period_end,omitempty"`       // Unix timestamp of the next month start
+	TotalTokens   int64                  `protobuf:"varint,3,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"` // Input and output tokens
+	TotalCostUsd  float64                `protobuf:"fixed64,4,opt,name=total_cost_usd,json=totalCostUsd,proto3" json:"total_cost_usd,omitempty"`
+	SessionCount  int64                  `protobuf:"varint,5,opt,name=session_count,json=sessionCount,proto3" json:"session_count,omitempty"`
+	TokenQuota    int64                  `protobuf:"varint,6,opt,name=token_quota,json=tokenQuota,proto3" json:"token_quota,omitempty"`          // Monthly token quota (0 = unlimited)
+	CostQuotaUsd  float64                `protobuf:"fixed64,7,opt,name=cost_quota_usd,json=costQuotaUsd,proto3" json:"cost_quota_usd,omitempty"` // Monthly cost quota (0 = unlimited)
+	Exceeded      bool                   `protobuf:"varint,8,opt,name=exceeded,proto3" json:"exceeded,omitempty"`                                // Whether new chats are rejected
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Usage) Reset() {
+	*x = Usage{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Usage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Usage) ProtoMessage() {}
+
+func (x *Usage) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Usage.ProtoReflect.Descriptor instead.
+func (*Usage) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *Usage) GetPeriodStart() int64 {
+	if x != nil {
+		return x.PeriodStart
+	}
+	return 0
+}
+
+func (x *Usage) GetPeriodEnd() int64 {
+	if x != nil {
+		return x.PeriodEnd
+	}
+	return 0
+}
+
+func (x *Usage) GetTotalTokens() int64 {
+	if x != nil {
+		return x.TotalTokens
+	}
+	return 0
+}
+
+func (x *Usage) GetTotalCostUsd() float64 {
+	if x != nil {
+		return x.TotalCostUsd
+	}
+	return 0
+}
+
+func (x *Usage) GetSessionCount() int64 {
+	if x != nil {
+		return x.SessionCount
+	}
+	return 0
+}
+
+func (x *Usage) GetTokenQuota() int64 {
+	if x != nil {
+		return x.TokenQuota
+	}
+	return 0
+}
+
+func (x *Usage) GetCostQuotaUsd() float64 {
+	if x != nil {
+		return x.CostQuotaUsd
+	}
+	return 0
+}
+
+func (x *Usage) GetExceeded() bool {
+	if x != nil {
+		return x.Exceeded
+	}
+	return false
+}
+
+// RunSelfTestRequest is the request for RunSelfTest.
+type RunSelfTestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Live          bool                   `protobuf:"varint,1,opt,name=live,proto3" json:"live,omitempty"` // Run against the configured model instead of a mock LLM
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunSelfTestRequest) Reset() {
+	*x = RunSelfTestRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunSelfTestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunSelfTestRequest) ProtoMessage() {}
+
+func (x *RunSelfTestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunSelfTestRequest.ProtoReflect.Descriptor instead.
+func (*RunSelfTestRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *RunSelfTestRequest) GetLive() bool {
+	if x != nil {
+		return x.Live
+	}
+	return false
+}
+
+// SelfTestCheck is the outcome of one step of a self-test.
+type SelfTestCheck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Passed        bool                   `protobuf:"varint,2,opt,name=passed,proto3" json:"passed,omitempty"`
+	Detail        string                 `protobuf:"bytes,3,opt,name=detail,proto3" json:"detail,omitempty"`
+	DurationMs    int64                  `protobuf:"varint,4,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelfTestCheck) Reset() {
+	*x = SelfTestCheck{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelfTestCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelfTestCheck) ProtoMessage() {}
+
+func (x *SelfTestCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelfTestCheck.ProtoReflect.Descriptor instead.
+func (*SelfTestCheck) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *SelfTestCheck) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SelfTestCheck) GetPassed() bool {
+	if x != nil {
+		return x.Passed
+	}
+	return false
+}
+
+func (x *SelfTestCheck) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *SelfTestCheck) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+// SelfTestReport is the pass/fail report of a self-test.
+type SelfTestReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Passed        bool                   `protobuf:"varint,1,opt,name=passed,proto3" json:"passed,omitempty"` // Whether all checks passed
+	Mode          string                 `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"`      // "mock" or "live"
+	Checks        []*SelfTestCheck       `protobuf:"bytes,3,rep,name=checks,proto3" json:"checks,omitempty"`
+	DurationMs    int64                  `protobuf:"varint,4,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelfTestReport) Reset() {
+	*x = SelfTestReport{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelfTestReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelfTestReport) ProtoMessage() {}
+
+func (x *SelfTestReport) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelfTestReport.ProtoReflect.Descriptor instead.
+func (*SelfTestReport) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *SelfTestReport) GetPassed() bool {
+	if x != nil {
+		return x.Passed
+	}
+	return false
+}
+
+func (x *SelfTestReport) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *SelfTestReport) GetChecks() []*SelfTestCheck {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+func (x *SelfTestReport) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+// GetBlockChangesRequest is the request for GetBlockChanges.
+type GetBlockChangesRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId int32                  `protobuf:"varint,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	SinceTs        int64                  `protobuf:"varint,2,opt,name=since_ts,json=sinceTs,proto3" json:"since_ts,omitempty"` // sync_ts of the previous response in milliseconds (0 = all blocks)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetBlockChangesRequest) Reset() {
+	*x = GetBlockChangesRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBlockChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBlockChangesRequest) ProtoMessage() {}
+
+func (x *GetBlockChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBlockChangesRequest.ProtoReflect.Descriptor instead.
+func (*GetBlockChangesRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *GetBlockChangesRequest) GetConversationId() int32 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+func (x *GetBlockChangesRequest) GetSinceTs() int64 {
+	if x != nil {
+		return x.SinceTs
+	}
+	return 0
+}
+
+// BlockChange is a block created or updated since a sync.
+type BlockChange struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// For blocks that existed at the time of the sync, event_stream only holds
+	// the events from event_offset on.
+	Block *Block `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	// Created blocks are sent whole; clients insert them, or replace their copy.
+	Created bool `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	// Index of the first event of block.event_stream; clients replace their
+	// events from that index on.
+	EventOffset   int32 `protobuf:"varint,3,opt,name=event_offset,json=eventOffset,proto3" json:"event_offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockChange) Reset() {
+	*x = BlockChange{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockChange) ProtoMessage() {}
+
+func (x *BlockChange) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockChange.ProtoReflect.Descriptor instead.
+func (*BlockChange) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *BlockChange) GetBlock() *Block {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+func (x *BlockChange) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
+}
+
+func (x *BlockChange) GetEventOffset() int32 {
+	if x != nil {
+		return x.EventOffset
+	}
+	return 0
+}
+
+// GetBlockChangesResponse is the response for GetBlockChanges.
+type GetBlockChangesResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Changes []*BlockChange         `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	// UIDs of all blocks of the conversation in order; clients drop the blocks
+	// they hold that are not listed, e.g. deleted branches.
+	BlockUids     []string `protobuf:"bytes,2,rep,name=block_uids,json=blockUids,proto3" json:"block_uids,omitempty"`
+	SyncTs        int64    `protobuf:"varint,3,opt,name=sync_ts,json=syncTs,proto3" json:"sync_ts,omitempty"` // since_ts of the next request
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBlockChangesResponse) Reset() {
+	*x = GetBlockChangesResponse{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBlockChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBlockChangesResponse) ProtoMessage() {}
+
+func (x *GetBlockChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[94]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBlockChangesResponse.ProtoReflect.Descriptor instead.
+func (*GetBlockChangesResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *GetBlockChangesResponse) GetChanges() []*BlockChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *GetBlockChangesResponse) GetBlockUids() []string {
+	if x != nil {
+		return x.BlockUids
+	}
+	return nil
+}
+
+func (x *GetBlockChangesResponse) GetSyncTs() int64 {
+	if x != nil {
+		return x.SyncTs
+	}
+	return 0
+}
+
+// GetBlockTranscriptRequest is the request for GetBlockTranscript.
+type GetBlockTranscriptRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Detail        TranscriptDetail       `protobuf:"varint,2,opt,name=detail,proto3,enum=memos.api.v1.TranscriptDetail" json:"detail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBlockTranscriptRequest) Reset() {
+	*x = GetBlockTranscriptRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[95]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBlockTranscriptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBlockTranscriptRequest) ProtoMessage() {}
+
+func (x *GetBlockTranscriptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[95]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBlockTranscriptRequest.ProtoReflect.Descriptor instead.
+func (*GetBlockTranscriptRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{95}
+}
+
+func (x *GetBlockTranscriptRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetBlockTranscriptRequest) GetDetail() TranscriptDetail {
+	if x != nil {
+		return x.Detail
+	}
+	return TranscriptDetail_TRANSCRIPT_DETAIL_UNSPECIFIED
+}
+
+// BlockTranscript is the compact transcript of a block.
+type BlockTranscript struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	BlockId        int64                  `protobuf:"varint,1,opt,name=block_id,json=blockId,proto3" json:"block_id,omitempty"`
+	ConversationId int32                  `protobuf:"varint,2,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Detail         TranscriptDetail       `protobuf:"varint,3,opt,name=detail,proto3,enum=memos.api.v1.TranscriptDetail" json:"detail,omitempty"`
+	Status         string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	UserInputs     []string               `protobuf:"bytes,5,rep,name=user_inputs,json=userInputs,proto3" json:"user_inputs,omitempty"`
+	Answer         string                 `protobuf:"bytes,6,opt,name=answer,proto3" json:"answer,omitempty"`
+	// Tool calls in order.
+	Tools []*TranscriptTool `protobuf:"bytes,7,rep,name=tools,proto3" json:"tools,omitempty"`
+	// Steps of the block in order (full detail only).
+	Entries []*TranscriptEntry `protobuf:"bytes,8,rep,name=entries,proto3" json:"entries,omitempty"`
+	Error   string             `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	// Document of a conversation bound to one.
+	Source *TranscriptSource `protobuf:"bytes,10,opt,name=source,proto3" json:"source,omitempty"`
+	// The transcript rendered for exports.
+	Markdown      string `protobuf:"bytes,11,opt,name=markdown,proto3" json:"markdown,omitempty"`
+	CreatedTs     int64  `protobuf:"varint,12,opt,name=created_ts,json=createdTs,proto3" json:"created_ts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockTranscript) Reset() {
+	*x = BlockTranscript{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[96]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockTranscript) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockTranscript) ProtoMessage() {}
+
+func (x *BlockTranscript) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[96]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockTranscript.ProtoReflect.Descriptor instead.
+func (*BlockTranscript) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{96}
+}
+
+func (x *BlockTranscript) GetBlockId() int64 {
+	if x != nil {
+		return x.BlockId
+	}
+	return 0
+}
+
+func (x *BlockTranscript) GetConversationId() int32 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+func (x *BlockTranscript) GetDetail() TranscriptDetail {
+	if x != nil {
+		return x.Detail
+	}
+	return TranscriptDetail_TRANSCRIPT_DETAIL_UNSPECIFIED
+}
+
+func (x *BlockTranscript) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *BlockTranscript) GetUserInputs() []string {
+	if x != nil {
+		return x.UserInputs
+	}
+	return nil
+}
+
+func (x *BlockTranscript) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *BlockTranscript) GetTools() []*TranscriptTool {
+	if x != nil {
+		return x.Tools
+	}
+	return nil
+}
+
+func (x *BlockTranscript) GetEntries() []*TranscriptEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *BlockTranscript) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BlockTranscript) GetSource() *TranscriptSource {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *BlockTranscript) GetMarkdown() string {
+	if x != nil {
+		return x.Markdown
+	}
+	return ""
+}
+
+func (x *BlockTranscript) GetCreatedTs() int64 {
+	if x != nil {
+		return x.CreatedTs
+	}
+	return 0
+}
+
+// TranscriptTool is a tool call of a block transcript.
+type TranscriptTool struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Name       string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Line       string                 `protobuf:"bytes,2,opt,name=line,proto3" json:"line,omitempty"` // The call summarized on one line
+	Failed     bool                   `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
+	DurationMs int64                  `protobuf:"varint,4,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	// Input and output are kept in full transcripts only.
+	Input         string `protobuf:"bytes,5,opt,name=input,proto3" json:"input,omitempty"`
+	Output        string `protobuf:"bytes,6,opt,name=output,proto3" json:"output,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TranscriptTool) Reset() {
+	*x = TranscriptTool{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[97]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranscriptTool) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranscriptTool) ProtoMessage() {}
+
+func (x *TranscriptTool) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[97]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranscriptTool.ProtoReflect.Descriptor instead.
+func (*TranscriptTool) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{97}
+}
+
+func (x *TranscriptTool) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TranscriptTool) GetLine() string {
+	if x != nil {
+		return x.Line
+	}
+	return ""
+}
+
+func (x *TranscriptTool) GetFailed() bool {
+	if x != nil {
+		return x.Failed
+	}
+	return false
+}
+
+func (x *TranscriptTool) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *TranscriptTool) GetInput() string {
+	if x != nil {
+		return x.Input
+	}
+	return ""
+}
+
+func (x *TranscriptTool) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+// TranscriptEntry is a step of a full transcript.
+type TranscriptEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"` // "thinking", "tool", "answer" or "error"
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	Tool          *TranscriptTool        `protobuf:"bytes,3,opt,name=tool,proto3" json:"tool,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TranscriptEntry) Reset() {
+	*x = TranscriptEntry{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[98]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranscriptEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranscriptEntry) ProtoMessage() {}
+
+func (x *TranscriptEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[98]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranscriptEntry.ProtoReflect.Descriptor instead.
+func (*TranscriptEntry) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{98}
+}
+
+func (x *TranscriptEntry) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TranscriptEntry) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *TranscriptEntry) GetTool() *TranscriptTool {
+	if x != nil {
+		return x.Tool
+	}
+	return nil
+}
+
+// TranscriptSource is the document a block answered about, with the passages
+// the answer cites, so that exports stand on their own.
+type TranscriptSource struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Kind  string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Uid   string                 `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	Title string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	// Cited passages as they read when cited.
+	Passages []*TranscriptPassage `protobuf:"bytes,4,rep,name=passages,proto3" json:"passages,omitempty"`
+	// The whole document (full detail only).
+	Text          string `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TranscriptSource) Reset() {
+	*x = TranscriptSource{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[99]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranscriptSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranscriptSource) ProtoMessage() {}
+
+func (x *TranscriptSource) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[99]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranscriptSource.ProtoReflect.Descriptor instead.
+func (*TranscriptSource) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{99}
+}
+
+func (x *TranscriptSource) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *TranscriptSource) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *TranscriptSource) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *TranscriptSource) GetPassages() []*TranscriptPassage {
+	if x != nil {
+		return x.Passages
+	}
+	return nil
+}
+
+func (x *TranscriptSource) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// TranscriptPassage is a cited passage of a document.
+type TranscriptPassage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         int32                  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"` // Offset of the passage in the document
+	End           int32                  `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TranscriptPassage) Reset() {
+	*x = TranscriptPassage{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[100]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranscriptPassage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranscriptPassage) ProtoMessage() {}
+
+func (x *TranscriptPassage) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[100]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranscriptPassage.ProtoReflect.Descriptor instead.
+func (*TranscriptPassage) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{100}
+}
+
+func (x *TranscriptPassage) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *TranscriptPassage) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *TranscriptPassage) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// ReindexAllRequest is the request for ReindexAll.
+type ReindexAllRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReindexAllRequest) Reset() {
+	*x = ReindexAllRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[101]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReindexAllRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReindexAllRequest) ProtoMessage() {}
+
+func (x *ReindexAllRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[101]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReindexAllRequest.ProtoReflect.Descriptor instead.
+func (*ReindexAllRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{101}
+}
+
+// ReindexSinceRequest is the request for ReindexSince.
+type ReindexSinceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Since         int64                  `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"` // Unix timestamp of the oldest memo update to reindex
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReindexSinceRequest) Reset() {
+	*x = ReindexSinceRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[102]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReindexSinceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReindexSinceRequest) ProtoMessage() {}
+
+func (x *ReindexSinceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[102]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReindexSinceRequest.ProtoReflect.Descriptor instead.
+func (*ReindexSinceRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{102}
+}
+
+func (x *ReindexSinceRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+// GetReindexProgressRequest is the request for GetReindexProgress.
+type GetReindexProgressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReindexProgressRequest) Reset() {
+	*x = GetReindexProgressRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[103]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReindexProgressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReindexProgressRequest) ProtoMessage() {}
+
+func (x *GetReindexProgressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[103]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReindexProgressRequest.ProtoReflect.Descriptor instead.
+func (*GetReindexProgressRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{103}
+}
+
+// ReindexProgress is the progress of a reindex of the memo embeddings.
+type ReindexProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Since         int64                  `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"` // Unix timestamp from which memos are reindexed (0 = all)
+	Running       bool                   `protobuf:"varint,2,opt,name=running,proto3" json:"running,omitempty"`
+	Total         int32                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	Processed     int32                  `protobuf:"varint,4,opt,name=processed,proto3" json:"processed,omitempty"`
+	Failed        int32                  `protobuf:"varint,5,opt,name=failed,proto3" json:"failed,omitempty"`
+	Model         string                 `protobuf:"bytes,6,opt,name=model,proto3" json:"model,omitempty"` // Embedding model the memos are reindexed with
+	StartedTs     int64                  `protobuf:"varint,7,opt,name=started_ts,json=startedTs,proto3" json:"started_ts,omitempty"`
+	FinishedTs    int64                  `protobuf:"varint,8,opt,name=finished_ts,json=finishedTs,proto3" json:"finished_ts,omitempty"`
+	Error         string                 `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReindexProgress) Reset() {
+	*x = ReindexProgress{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[104]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReindexProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReindexProgress) ProtoMessage() {}
+
+func (x *ReindexProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[104]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReindexProgress.ProtoReflect.Descriptor instead.
+func (*ReindexProgress) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{104}
+}
+
+func (x *ReindexProgress) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *ReindexProgress) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *ReindexProgress) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ReindexProgress) GetProcessed() int32 {
+	if x != nil {
+		return x.Processed
+	}
+	return 0
+}
+
+func (x *ReindexProgress) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *ReindexProgress) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *ReindexProgress) GetStartedTs() int64 {
+	if x != nil {
+		return x.StartedTs
+	}
+	return 0
+}
+
+func (x *ReindexProgress) GetFinishedTs() int64 {
+	if x != nil {
+		return x.FinishedTs
+	}
+	return 0
+}
+
+func (x *ReindexProgress) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// MCPServer is an MCP server a user registered for Geek Mode. Environment
+// variables and headers may hold credentials, so only their names are returned.
+type MCPServer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`           // Tools are named mcp__<name>__<tool>
+	Transport     string                 `protobuf:"bytes,3,opt,name=transport,proto3" json:"transport,omitempty"` // "stdio", "http" or "sse"
+	Command       string                 `protobuf:"bytes,4,opt,name=command,proto3" json:"command,omitempty"`     // Command of a stdio server
+	Args          []string               `protobuf:"bytes,5,rep,name=args,proto3" json:"args,omitempty"`
+	Url           string                 `protobuf:"bytes,6,opt,name=url,proto3" json:"url,omitempty"` // Endpoint of an http or sse server
+	Enabled       bool                   `protobuf:"varint,7,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	EnvNames      []string               `protobuf:"bytes,8,rep,name=env_names,json=envNames,proto3" json:"env_names,omitempty"`
+	HeaderNames   []string               `protobuf:"bytes,9,rep,name=header_names,json=headerNames,proto3" json:"header_names,omitempty"`
+	CreatedTs     int64                  `protobuf:"varint,10,opt,name=created_ts,json=createdTs,proto3" json:"created_ts,omitempty"`
+	UpdatedTs     int64                  `protobuf:"varint,11,opt,name=updated_ts,json=updatedTs,proto3" json:"updated_ts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MCPServer) Reset() {
+	*x = MCPServer{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[105]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MCPServer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MCPServer) ProtoMessage() {}
+
+func (x *MCPServer) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[105]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MCPServer.ProtoReflect.Descriptor instead.
+func (*MCPServer) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{105}
+}
+
+func (x *MCPServer) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *MCPServer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MCPServer) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *MCPServer) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *MCPServer) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *MCPServer) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *MCPServer) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *MCPServer) GetEnvNames() []string {
+	if x != nil {
+		return x.EnvNames
+	}
+	return nil
+}
+
+func (x *MCPServer) GetHeaderNames() []string {
+	if x != nil {
+		return x.HeaderNames
+	}
+	return nil
+}
+
+func (x *MCPServer) GetCreatedTs() int64 {
+	if x != nil {
+		return x.CreatedTs
+	}
+	return 0
+}
+
+func (x *MCPServer) GetUpdatedTs() int64 {
+	if x != nil {
+		return x.UpdatedTs
+	}
+	return 0
+}
+
+// ListMCPServersRequest is the request for ListMCPServers.
+type ListMCPServersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMCPServersRequest) Reset() {
+	*x = ListMCPServersRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[106]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMCPServersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMCPServersRequest) ProtoMessage() {}
+
+func (x *ListMCPServersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[106]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMCPServersRequest.ProtoReflect.Descriptor instead.
+func (*ListMCPServersRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{106}
+}
+
+// ListMCPServersResponse is the response for ListMCPServers.
+type ListMCPServersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Servers       []*MCPServer           `protobuf:"bytes,1,rep,name=servers,proto3" json:"servers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMCPServersResponse) Reset() {
+	*x = ListMCPServersResponse{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[107]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMCPServersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMCPServersResponse) ProtoMessage() {}
+
+func (x *ListMCPServersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[107]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMCPServersResponse.ProtoReflect.Descriptor instead.
+func (*ListMCPServersResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{107}
+}
+
+func (x *ListMCPServersResponse) GetServers() []*MCPServer {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+// CreateMCPServerRequest is the request for CreateMCPServer.
+type CreateMCPServerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Transport     string                 `protobuf:"bytes,2,opt,name=transport,proto3" json:"transport,omitempty"` // "stdio", "http" or "sse"
+	Command       string                 `protobuf:"bytes,3,opt,name=command,proto3" json:"command,omitempty"`
+	Args          []string               `protobuf:"bytes,4,rep,name=args,proto3" json:"args,omitempty"`
+	Env           map[string]string      `protobuf:"bytes,5,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Environment variables of a stdio server
+	Url           string                 `protobuf:"bytes,6,opt,name=url,proto3" json:"url,omitempty"`
+	Headers       map[string]string      `protobuf:"bytes,7,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // HTTP headers of an http or sse server
+	Enabled       *bool                  `protobuf:"varint,8,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`                                                                    // Defaults to true
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateMCPServerRequest) Reset() {
+	*x = CreateMCPServerRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[108]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateMCPServerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateMCPServerRequest) ProtoMessage() {}
+
+func (x *CreateMCPServerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[108]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateMCPServerRequest.ProtoReflect.Descriptor instead.
+func (*CreateMCPServerRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{108}
+}
+
+func (x *CreateMCPServerRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateMCPServerRequest) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *CreateMCPServerRequest) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *CreateMCPServerRequest) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *CreateMCPServerRequest) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *CreateMCPServerRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateMCPServerRequest) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *CreateMCPServerRequest) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+// UpdateMCPServerRequest is the request for UpdateMCPServer. Empty env and
+// headers keep the current values, which clients cannot read back.
+type UpdateMCPServerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Transport     string                 `protobuf:"bytes,3,opt,name=transport,proto3" json:"transport,omitempty"`
+	Command       string                 `protobuf:"bytes,4,opt,name=command,proto3" json:"command,omitempty"`
+	Args          []string               `protobuf:"bytes,5,rep,name=args,proto3" json:"args,omitempty"`
+	Env           map[string]string      `protobuf:"bytes,6,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Url           string                 `protobuf:"bytes,7,opt,name=url,proto3" json:"url,omitempty"`
+	Headers       map[string]string      `protobuf:"bytes,8,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Enabled       *bool                  `protobuf:"varint,9,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`                          // Defaults to true
+	ClearEnv      bool                   `protobuf:"varint,10,opt,name=clear_env,json=clearEnv,proto3" json:"clear_env,omitempty"`             // Remove the environment variables instead of keeping them
+	ClearHeaders  bool                   `protobuf:"varint,11,opt,name=clear_headers,json=clearHeaders,proto3" json:"clear_headers,omitempty"` // Remove the headers instead of keeping them
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMCPServerRequest) Reset() {
+	*x = UpdateMCPServerRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[109]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMCPServerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMCPServerRequest) ProtoMessage() {}
+
+func (x *UpdateMCPServerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[109]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMCPServerRequest.ProtoReflect.Descriptor instead.
+func (*UpdateMCPServerRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{109}
+}
+
+func (x *UpdateMCPServerRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateMCPServerRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateMCPServerRequest) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *UpdateMCPServerRequest) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *UpdateMCPServerRequest) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *UpdateMCPServerRequest) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *UpdateMCPServerRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *UpdateMCPServerRequest) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *UpdateMCPServerRequest) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *UpdateMCPServerRequest) GetClearEnv() bool {
+	if x != nil {
+		return x.ClearEnv
+	}
+	return false
+}
+
+func (x *UpdateMCPServerRequest) GetClearHeaders() bool {
+	if x != nil {
+		return x.ClearHeaders
+	}
+	return false
+}
+
+// DeleteMCPServerRequest is the request for DeleteMCPServer.
+type DeleteMCPServerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMCPServerRequest) Reset() {
+	*x = DeleteMCPServerRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[110]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMCPServerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMCPServerRequest) ProtoMessage() {}
+
+func (x *DeleteMCPServerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[110]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMCPServerRequest.ProtoReflect.Descriptor instead.
+func (*DeleteMCPServerRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{110}
+}
+
+func (x *DeleteMCPServerRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// ImportConversationRequest is the request for ImportConversation.
+type ImportConversationRequest struct {
+	state  protoimpl.MessageState   `protogen:"open.v1"`
+	Format ConversationImportFormat `protobuf:"varint,1,opt,name=format,proto3,enum=memos.api.v1.ConversationImportFormat" json:"format,omitempty"`
+	// The conversations.json of a ChatGPT or Claude data export, or a single
+	// conversation of it.
+	Data          string `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportConversationRequest) Reset() {
+	*x = ImportConversationRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[111]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportConversationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportConversationRequest) ProtoMessage() {}
+
+func (x *ImportConversationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[111]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportConversationRequest.ProtoReflect.Descriptor instead.
+func (*ImportConversationRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{111}
+}
+
+func (x *ImportConversationRequest) GetFormat() ConversationImportFormat {
+	if x != nil {
+		return x.Format
+	}
+	return ConversationImportFormat_CONVERSATION_IMPORT_FORMAT_UNSPECIFIED
+}
+
+func (x *ImportConversationRequest) GetData() string {
+	if x != nil {
+		return x.Data
+	}
+	return ""
+}
+
+// ImportConversationResponse is the response for ImportConversation.
+type ImportConversationResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Conversations []*ImportedConversation `protobuf:"bytes,1,rep,name=conversations,proto3" json:"conversations,omitempty"`
+	Skipped       int32                   `protobuf:"varint,2,opt,name=skipped,proto3" json:"skipped,omitempty"` // Conversations without any message of the user
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportConversationResponse) Reset() {
+	*x = ImportConversationResponse{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[112]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportConversationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportConversationResponse) ProtoMessage() {}
+
+func (x *ImportConversationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[112]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportConversationResponse.ProtoReflect.Descriptor instead.
+func (*ImportConversationResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{112}
+}
+
+func (x *ImportConversationResponse) GetConversations() []*ImportedConversation {
+	if x != nil {
+		return x.Conversations
+	}
+	return nil
+}
+
+func (x *ImportConversationResponse) GetSkipped() int32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+// ImportedConversation is a conversation created by an import.
+type ImportedConversation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uid           string                 `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Rounds        int32                  `protobuf:"varint,3,opt,name=rounds,proto3" json:"rounds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportedConversation) Reset() {
+	*x = ImportedConversation{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[113]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportedConversation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportedConversation) ProtoMessage() {}
+
+func (x *ImportedConversation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[113]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportedConversation.ProtoReflect.Descriptor instead.
+func (*ImportedConversation) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{113}
+}
+
+func (x *ImportedConversation) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *ImportedConversation) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ImportedConversation) GetRounds() int32 {
+	if x != nil {
+		return x.Rounds
+	}
+	return 0
+}
+
+// ActionAuditRecord is a tool call of a Geek or Evolution Mode session.
+type ActionAuditRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Mode          string                 `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"` // "geek" or "evolution"
+	SessionId     string                 `protobuf:"bytes,4,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Tool          string                 `protobuf:"bytes,5,opt,name=tool,proto3" json:"tool,omitempty"`
+	ToolId        string                 `protobuf:"bytes,6,opt,name=tool_id,json=toolId,proto3" json:"tool_id,omitempty"`
+	Input         string                 `protobuf:"bytes,7,opt,name=input,proto3" json:"input,omitempty"` // Summary of the input of the tool, e.g. the command of Bash
+	FilePaths     []string               `protobuf:"bytes,8,rep,name=file_paths,json=filePaths,proto3" json:"file_paths,omitempty"`
+	Status        string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`  // "success", "error", "blocked" or "unfinished"
+	Output        string                 `protobuf:"bytes,10,opt,name=output,proto3" json:"output,omitempty"` // Summary of the output, the error for failed and blocked tools
+	DurationMs    int64                  `protobuf:"varint,11,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	CreatedTs     int64                  `protobuf:"varint,12,opt,name=created_ts,json=createdTs,proto3" json:"created_ts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActionAuditRecord) Reset() {
+	*x = ActionAuditRecord{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[114]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActionAuditRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionAuditRecord) ProtoMessage() {}
+
+func (x *ActionAuditRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[114]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionAuditRecord.ProtoReflect.Descriptor instead.
+func (*ActionAuditRecord) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{114}
+}
+
+func (x *ActionAuditRecord) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ActionAuditRecord) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ActionAuditRecord) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *ActionAuditRecord) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ActionAuditRecord) GetTool() string {
+	if x != nil {
+		return x.Tool
+	}
+	return ""
+}
+
+func (x *ActionAuditRecord) GetToolId() string {
+	if x != nil {
+		return x.ToolId
+	}
+	return ""
+}
+
+func (x *ActionAuditRecord) GetInput() string {
+	if x != nil {
+		return x.Input
+	}
+	return ""
+}
+
+func (x *ActionAuditRecord) GetFilePaths() []string {
+	if x != nil {
+		return x.FilePaths
+	}
+	return nil
+}
+
+func (x *ActionAuditRecord) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ActionAuditRecord) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *ActionAuditRecord) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *ActionAuditRecord) GetCreatedTs() int64 {
+	if x != nil {
+		return x.CreatedTs
+	}
+	return 0
+}
+
+// ActionAuditFilter selects audit records; zero fields select all.
+type ActionAuditFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Mode          string                 `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"` // "geek" or "evolution"
+	SessionId     string                 `protobuf:"bytes,3,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Tool          string                 `protobuf:"bytes,4,opt,name=tool,proto3" json:"tool,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`                      // "success", "error", "blocked" or "unfinished"
+	From          int64                  `protobuf:"varint,6,opt,name=from,proto3" json:"from,omitempty"`                         // Unix timestamp of the oldest call, inclusive
+	To            int64                  `protobuf:"varint,7,opt,name=to,proto3" json:"to,omitempty"`                             // Unix timestamp of the latest call, inclusive
+	BeforeId      int64                  `protobuf:"varint,8,opt,name=before_id,json=beforeId,proto3" json:"before_id,omitempty"` // Only records older than this one
+	Limit         int32                  `protobuf:"varint,9,opt,name=limit,proto3" json:"limit,omitempty"`                       // 1 to 500
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActionAuditFilter) Reset() {
+	*x = ActionAuditFilter{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[115]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActionAuditFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionAuditFilter) ProtoMessage() {}
+
+func (x *ActionAuditFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[115]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionAuditFilter.ProtoReflect.Descriptor instead.
+func (*ActionAuditFilter) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{115}
+}
+
+func (x *ActionAuditFilter) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ActionAuditFilter) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *ActionAuditFilter) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ActionAuditFilter) GetTool() string {
+	if x != nil {
+		return x.Tool
+	}
+	return ""
+}
+
+func (x *ActionAuditFilter) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ActionAuditFilter) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *ActionAuditFilter) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *ActionAuditFilter) GetBeforeId() int64 {
+	if x != nil {
+		return x.BeforeId
+	}
+	return 0
+}
+
+func (x *ActionAuditFilter) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// ListActionAuditRequest is the request for ListActionAudit.
+type ListActionAuditRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *ActionAuditFilter     `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListActionAuditRequest) Reset() {
+	*x = ListActionAuditRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[116]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListActionAuditRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListActionAuditRequest) ProtoMessage() {}
+
+func (x *ListActionAuditRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[116]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListActionAuditRequest.ProtoReflect.Descriptor instead.
+func (*ListActionAuditRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{116}
+}
+
+func (x *ListActionAuditRequest) GetFilter() *ActionAuditFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+// ListActionAuditResponse is the response for ListActionAudit.
+type ListActionAuditResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Records []*ActionAuditRecord   `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	// The before_id of the next page, set while more records may follow.
+	NextBeforeId  int64 `protobuf:"varint,2,opt,name=next_before_id,json=nextBeforeId,proto3" json:"next_before_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListActionAuditResponse) Reset() {
+	*x = ListActionAuditResponse{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[117]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListActionAuditResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListActionAuditResponse) ProtoMessage() {}
+
+func (x *ListActionAuditResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[117]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListActionAuditResponse.ProtoReflect.Descriptor instead.
+func (*ListActionAuditResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{117}
+}
+
+func (x *ListActionAuditResponse) GetRecords() []*ActionAuditRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *ListActionAuditResponse) GetNextBeforeId() int64 {
+	if x != nil {
+		return x.NextBeforeId
+	}
+	return 0
+}
+
+// ExportActionAuditRequest is the request for ExportActionAudit.
+type ExportActionAuditRequest struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Filter        *ActionAuditFilter      `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	Format        ActionAuditExportFormat `protobuf:"varint,2,opt,name=format,proto3,enum=memos.api.v1.ActionAuditExportFormat" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportActionAuditRequest) Reset() {
+	*x = ExportActionAuditRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[118]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportActionAuditRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportActionAuditRequest) ProtoMessage() {}
+
+func (x *ExportActionAuditRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[118]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportActionAuditRequest.ProtoReflect.Descriptor instead.
+func (*ExportActionAuditRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{118}
+}
+
+func (x *ExportActionAuditRequest) GetFilter() *ActionAuditFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ExportActionAuditRequest) GetFormat() ActionAuditExportFormat {
+	if x != nil {
+		return x.Format
+	}
+	return ActionAuditExportFormat_ACTION_AUDIT_EXPORT_FORMAT_UNSPECIFIED
+}
+
+// ExportActionAuditResponse is the response for ExportActionAudit.
+type ExportActionAuditResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Filename      string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	ContentType   string                 `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportActionAuditResponse) Reset() {
+	*x = ExportActionAuditResponse{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[119]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportActionAuditResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportActionAuditResponse) ProtoMessage() {}
+
+func (x *ExportActionAuditResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[119]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportActionAuditResponse.ProtoReflect.Descriptor instead.
+func (*ExportActionAuditResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{119}
+}
+
+func (x *ExportActionAuditResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ExportActionAuditResponse) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *ExportActionAuditResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+// LLMProvider is a configured LLM provider.
+type LLMProvider struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Provider      string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"` // e.g. "openai", "deepseek" or "ollama"
+	BaseUrl       string                 `protobuf:"bytes,3,opt,name=base_url,json=baseUrl,proto3" json:"base_url,omitempty"`
+	Model         string                 `protobuf:"bytes,4,opt,name=model,proto3" json:"model,omitempty"` // Default model
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LLMProvider) Reset() {
+	*x = LLMProvider{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[120]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LLMProvider) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LLMProvider) ProtoMessage() {}
+
+func (x *LLMProvider) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[120]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LLMProvider.ProtoReflect.Descriptor instead.
+func (*LLMProvider) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{120}
+}
+
+func (x *LLMProvider) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LLMProvider) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *LLMProvider) GetBaseUrl() string {
+	if x != nil {
+		return x.BaseUrl
+	}
+	return ""
+}
+
+func (x *LLMProvider) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+// AgentModel is the provider and model of an agent type.
+type AgentModel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agent         string                 `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"` // A parrot name or "orchestrator"
+	Provider      string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Model         string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`   // Empty for the default model of the provider
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"` // "default", "env" or "admin"
+	UpdatedTs     int64                  `protobuf:"varint,5,opt,name=updated_ts,json=updatedTs,proto3" json:"updated_ts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentModel) Reset() {
+	*x = AgentModel{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[121]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentModel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentModel) ProtoMessage() {}
+
+func (x *AgentModel) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[121]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentModel.ProtoReflect.Descriptor instead.
+func (*AgentModel) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{121}
+}
+
+func (x *AgentModel) GetAgent() string {
+	if x != nil {
+		return x.Agent
+	}
+	return ""
+}
+
+func (x *AgentModel) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *AgentModel) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *AgentModel) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *AgentModel) GetUpdatedTs() int64 {
+	if x != nil {
+		return x.UpdatedTs
+	}
+	return 0
+}
+
+// ListAgentModelsRequest is the request for ListAgentModels.
+type ListAgentModelsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentModelsRequest) Reset() {
+	*x = ListAgentModelsRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[122]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentModelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentModelsRequest) ProtoMessage() {}
+
+func (x *ListAgentModelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[122]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentModelsRequest.ProtoReflect.Descriptor instead.
+func (*ListAgentModelsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{122}
+}
+
+// ListAgentModelsResponse is the response for ListAgentModels.
+type ListAgentModelsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Providers     []*LLMProvider         `protobuf:"bytes,1,rep,name=providers,proto3" json:"providers,omitempty"`
+	Agents        []*AgentModel          `protobuf:"bytes,2,rep,name=agents,proto3" json:"agents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentModelsResponse) Reset() {
+	*x = ListAgentModelsResponse{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[123]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentModelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentModelsResponse) ProtoMessage() {}
+
+func (x *ListAgentModelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[123]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentModelsResponse.ProtoReflect.Descriptor instead.
+func (*ListAgentModelsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{123}
+}
+
+func (x *ListAgentModelsResponse) GetProviders() []*LLMProvider {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+func (x *ListAgentModelsResponse) GetAgents() []*AgentModel {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+// UpdateAgentModelRequest is the request for UpdateAgentModel.
+type UpdateAgentModelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agent         string                 `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"` // A parrot name or "orchestrator"
+	Provider      string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Model         string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"` // Empty for the default model of the provider
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAgentModelRequest) Reset() {
+	*x = UpdateAgentModelRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[124]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAgentModelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAgentModelRequest) ProtoMessage() {}
+
+func (x *UpdateAgentModelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[124]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAgentModelRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAgentModelRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{124}
+}
+
+func (x *UpdateAgentModelRequest) GetAgent() string {
+	if x != nil {
+		return x.Agent
+	}
+	return ""
+}
+
+func (x *UpdateAgentModelRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *UpdateAgentModelRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+// DeleteAgentModelRequest is the request for DeleteAgentModel.
+type DeleteAgentModelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agent         string                 `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAgentModelRequest) Reset() {
+	*x = DeleteAgentModelRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[125]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAgentModelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAgentModelRequest) ProtoMessage() {}
+
+func (x *DeleteAgentModelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[125]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAgentModelRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAgentModelRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{125}
+}
+
+func (x *DeleteAgentModelRequest) GetAgent() string {
+	if x != nil {
+		return x.Agent
+	}
+	return ""
+}
+
+// GeekWorkspaceUsage is the disk usage of a Geek Mode workspace.
+type GeekWorkspaceUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bytes         int64                  `protobuf:"varint,1,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	Files         int32                  `protobuf:"varint,2,opt,name=files,proto3" json:"files,omitempty"`
+	LimitBytes    int64                  `protobuf:"varint,3,opt,name=limit_bytes,json=limitBytes,proto3" json:"limit_bytes,omitempty"` // 0 for workspaces without limit
+	MeasuredTs    int64                  `protobuf:"varint,4,opt,name=measured_ts,json=measuredTs,proto3" json:"measured_ts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeekWorkspaceUsage) Reset() {
+	*x = GeekWorkspaceUsage{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[126]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeekWorkspaceUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeekWorkspaceUsage) ProtoMessage() {}
+
+func (x *GeekWorkspaceUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[126]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeekWorkspaceUsage.ProtoReflect.Descriptor instead.
+func (*GeekWorkspaceUsage) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{126}
+}
+
+func (x *GeekWorkspaceUsage) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *GeekWorkspaceUsage) GetFiles() int32 {
+	if x != nil {
+		return x.Files
+	}
+	return 0
+}
+
+func (x *GeekWorkspaceUsage) GetLimitBytes() int64 {
+	if x != nil {
+		return x.LimitBytes
+	}
+	return 0
+}
+
+func (x *GeekWorkspaceUsage) GetMeasuredTs() int64 {
+	if x != nil {
+		return x.MeasuredTs
+	}
+	return 0
+}
+
+// GeekWorkspaceEntry is a file or directory of a Geek Mode workspace.
+type GeekWorkspaceEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"` // Relative to the workspace, with forward slashes
+	IsDir         bool                   `protobuf:"varint,3,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
+	Symlink       bool                   `protobuf:"varint,4,opt,name=symlink,proto3" json:"symlink,omitempty"`
+	Size          int64                  `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	ModifiedTs    int64                  `protobuf:"varint,6,opt,name=modified_ts,json=modifiedTs,proto3" json:"modified_ts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeekWorkspaceEntry) Reset() {
+	*x = GeekWorkspaceEntry{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[127]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeekWorkspaceEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeekWorkspaceEntry) ProtoMessage() {}
+
+func (x *GeekWorkspaceEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[127]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeekWorkspaceEntry.ProtoReflect.Descriptor instead.
+func (*GeekWorkspaceEntry) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{127}
+}
+
+func (x *GeekWorkspaceEntry) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GeekWorkspaceEntry) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GeekWorkspaceEntry) GetIsDir() bool {
+	if x != nil {
+		return x.IsDir
+	}
+	return false
+}
+
+func (x *GeekWorkspaceEntry) GetSymlink() bool {
+	if x != nil {
+		return x.Symlink
+	}
+	return false
+}
+
+func (x *GeekWorkspaceEntry) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *GeekWorkspaceEntry) GetModifiedTs() int64 {
+	if x != nil {
+		return x.ModifiedTs
+	}
+	return 0
+}
+
+// GetGeekWorkspaceUsageRequest is the request for GetGeekWorkspaceUsage.
+type GetGeekWorkspaceUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGeekWorkspaceUsageRequest) Reset() {
+	*x = GetGeekWorkspaceUsageRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[128]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AppendUserInputRequest) String() string {
+func (x *GetGeekWorkspaceUsageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AppendUserInputRequest) ProtoMessage() {}
+func (*GetGeekWorkspaceUsageRequest) ProtoMessage() {}
 
-func (x *AppendUserInputRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[76]
+func (x *GetGeekWorkspaceUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[128]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5813,49 +9383,34 @@ func (x *AppendUserInputRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AppendUserInputRequest.ProtoReflect.Descriptor instead.
-func (*AppendUserInputRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{76}
-}
-
-func (x *AppendUserInputRequest) GetId() int64 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
-}
-
-func (x *AppendUserInputRequest) GetInput() *UserInput {
-	if x != nil {
-		return x.Input
-	}
-	return nil
+// Deprecated: Use GetGeekWorkspaceUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetGeekWorkspaceUsageRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{128}
 }
 
-// AppendEventRequest is the request for AppendEvent.
-type AppendEventRequest struct {
+// ListGeekWorkspaceFilesRequest is the request for ListGeekWorkspaceFiles.
+type ListGeekWorkspaceFilesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"` // Block ID
-	Event         *BlockEvent            `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // Relative to the workspace; empty for the workspace itself
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AppendEventRequest) Reset() {
-	*x = AppendEventRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[77]
+func (x *ListGeekWorkspaceFilesRequest) Reset() {
+	*x = ListGeekWorkspaceFilesRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[129]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AppendEventRequest) String() string {
+func (x *ListGeekWorkspaceFilesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AppendEventRequest) ProtoMessage() {}
+func (*ListGeekWorkspaceFilesRequest) ProtoMessage() {}
 
-func (x *AppendEventRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[77]
+func (x *ListGeekWorkspaceFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[129]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5866,50 +9421,43 @@ func (x *AppendEventRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AppendEventRequest.ProtoReflect.Descriptor instead.
-func (*AppendEventRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{77}
-}
-
-func (x *AppendEventRequest) GetId() int64 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
+// Deprecated: Use ListGeekWorkspaceFilesRequest.ProtoReflect.Descriptor instead.
+func (*ListGeekWorkspaceFilesRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{129}
 }
 
-func (x *AppendEventRequest) GetEvent() *BlockEvent {
+func (x *ListGeekWorkspaceFilesRequest) GetPath() string {
 	if x != nil {
-		return x.Event
+		return x.Path
 	}
-	return nil
+	return ""
 }
 
-// ForkBlockRequest is the request for ForkBlock.
-type ForkBlockRequest struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	Id                int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                                                         // Parent block ID
-	Reason            *string                `protobuf:"bytes,2,opt,name=reason,proto3,oneof" json:"reason,omitempty"`                                            // Optional reason for forking (e.g., "regenerate", "edit")
-	ReplaceUserInputs []*UserInput           `protobuf:"bytes,3,rep,name=replace_user_inputs,json=replaceUserInputs,proto3" json:"replace_user_inputs,omitempty"` // Optional: replace inherited user inputs with new ones (for message editing)
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+// ListGeekWorkspaceFilesResponse is the response for ListGeekWorkspaceFiles.
+type ListGeekWorkspaceFilesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Entries       []*GeekWorkspaceEntry  `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	Truncated     bool                   `protobuf:"varint,3,opt,name=truncated,proto3" json:"truncated,omitempty"` // Set when the directory has more entries than listed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ForkBlockRequest) Reset() {
-	*x = ForkBlockRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[78]
+func (x *ListGeekWorkspaceFilesResponse) Reset() {
+	*x = ListGeekWorkspaceFilesResponse{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[130]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ForkBlockRequest) String() string {
+func (x *ListGeekWorkspaceFilesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ForkBlockRequest) ProtoMessage() {}
+func (*ListGeekWorkspaceFilesResponse) ProtoMessage() {}
 
-func (x *ForkBlockRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[78]
+func (x *ListGeekWorkspaceFilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[130]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5920,55 +9468,55 @@ func (x *ForkBlockRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ForkBlockRequest.ProtoReflect.Descriptor instead.
-func (*ForkBlockRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{78}
+// Deprecated: Use ListGeekWorkspaceFilesResponse.ProtoReflect.Descriptor instead.
+func (*ListGeekWorkspaceFilesResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{130}
 }
 
-func (x *ForkBlockRequest) GetId() int64 {
+func (x *ListGeekWorkspaceFilesResponse) GetPath() string {
 	if x != nil {
-		return x.Id
+		return x.Path
 	}
-	return 0
+	return ""
 }
 
-func (x *ForkBlockRequest) GetReason() string {
-	if x != nil && x.Reason != nil {
-		return *x.Reason
+func (x *ListGeekWorkspaceFilesResponse) GetEntries() []*GeekWorkspaceEntry {
+	if x != nil {
+		return x.Entries
 	}
-	return ""
+	return nil
 }
 
-func (x *ForkBlockRequest) GetReplaceUserInputs() []*UserInput {
+func (x *ListGeekWorkspaceFilesResponse) GetTruncated() bool {
 	if x != nil {
-		return x.ReplaceUserInputs
+		return x.Truncated
 	}
-	return nil
+	return false
 }
 
-// ListBlockBranchesRequest is the request for ListBlockBranches.
-type ListBlockBranchesRequest struct {
+// ReadGeekWorkspaceFileRequest is the request for ReadGeekWorkspaceFile.
+type ReadGeekWorkspaceFileRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"` // Root block ID
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListBlockBranchesRequest) Reset() {
-	*x = ListBlockBranchesRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[79]
+func (x *ReadGeekWorkspaceFileRequest) Reset() {
+	*x = ReadGeekWorkspaceFileRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[131]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListBlockBranchesRequest) String() string {
+func (x *ReadGeekWorkspaceFileRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListBlockBranchesRequest) ProtoMessage() {}
+func (*ReadGeekWorkspaceFileRequest) ProtoMessage() {}
 
-func (x *ListBlockBranchesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[79]
+func (x *ReadGeekWorkspaceFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[131]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5979,42 +9527,45 @@ func (x *ListBlockBranchesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListBlockBranchesRequest.ProtoReflect.Descriptor instead.
-func (*ListBlockBranchesRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{79}
+// Deprecated: Use ReadGeekWorkspaceFileRequest.ProtoReflect.Descriptor instead.
+func (*ReadGeekWorkspaceFileRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{131}
 }
 
-func (x *ListBlockBranchesRequest) GetId() int64 {
+func (x *ReadGeekWorkspaceFileRequest) GetPath() string {
 	if x != nil {
-		return x.Id
+		return x.Path
 	}
-	return 0
+	return ""
 }
 
-// ListBlockBranchesResponse is the response for ListBlockBranches.
-type ListBlockBranchesResponse struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	Branches         []*BlockBranch         `protobuf:"bytes,1,rep,name=branches,proto3" json:"branches,omitempty"`
-	ActiveBranchPath string                 `protobuf:"bytes,2,opt,name=active_branch_path,json=activeBranchPath,proto3" json:"active_branch_path,omitempty"` // Currently active branch path
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+// GeekWorkspaceFileContent is a file of a Geek Mode workspace with its content.
+type GeekWorkspaceFileContent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entry         *GeekWorkspaceEntry    `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	MimeType      string                 `protobuf:"bytes,2,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	Binary        bool                   `protobuf:"varint,3,opt,name=binary,proto3" json:"binary,omitempty"`       // Binary files have no content; download them instead
+	Truncated     bool                   `protobuf:"varint,4,opt,name=truncated,proto3" json:"truncated,omitempty"` // Set when the file is larger than 1 MiB
+	Content       string                 `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListBlockBranchesResponse) Reset() {
-	*x = ListBlockBranchesResponse{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[80]
+func (x *GeekWorkspaceFileContent) Reset() {
+	*x = GeekWorkspaceFileContent{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[132]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListBlockBranchesResponse) String() string {
+func (x *GeekWorkspaceFileContent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListBlockBranchesResponse) ProtoMessage() {}
+func (*GeekWorkspaceFileContent) ProtoMessage() {}
 
-func (x *ListBlockBranchesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[80]
+func (x *GeekWorkspaceFileContent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[132]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6025,51 +9576,69 @@ func (x *ListBlockBranchesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListBlockBranchesResponse.ProtoReflect.Descriptor instead.
-func (*ListBlockBranchesResponse) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{80}
+// Deprecated: Use GeekWorkspaceFileContent.ProtoReflect.Descriptor instead.
+func (*GeekWorkspaceFileContent) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{132}
 }
 
-func (x *ListBlockBranchesResponse) GetBranches() []*BlockBranch {
+func (x *GeekWorkspaceFileContent) GetEntry() *GeekWorkspaceEntry {
 	if x != nil {
-		return x.Branches
+		return x.Entry
 	}
 	return nil
 }
 
-func (x *ListBlockBranchesResponse) GetActiveBranchPath() string {
+func (x *GeekWorkspaceFileContent) GetMimeType() string {
 	if x != nil {
-		return x.ActiveBranchPath
+		return x.MimeType
 	}
 	return ""
 }
 
-// BlockBranch represents a branch in the conversation tree.
-type BlockBranch struct {
+func (x *GeekWorkspaceFileContent) GetBinary() bool {
+	if x != nil {
+		return x.Binary
+	}
+	return false
+}
+
+func (x *GeekWorkspaceFileContent) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *GeekWorkspaceFileContent) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+// DownloadGeekWorkspaceFileRequest is the request for DownloadGeekWorkspaceFile.
+type DownloadGeekWorkspaceFileRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Block         *Block                 `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`                             // The block at this branch position
-	BranchPath    string                 `protobuf:"bytes,2,opt,name=branch_path,json=branchPath,proto3" json:"branch_path,omitempty"` // Path from root to this block (e.g., "0/1/3")
-	IsActive      bool                   `protobuf:"varint,3,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`      // Whether this is the currently active branch
-	Children      []*BlockBranch         `protobuf:"bytes,4,rep,name=children,proto3" json:"children,omitempty"`                       // Child branches
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BlockBranch) Reset() {
-	*x = BlockBranch{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[81]
+func (x *DownloadGeekWorkspaceFileRequest) Reset() {
+	*x = DownloadGeekWorkspaceFileRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[133]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BlockBranch) String() string {
+func (x *DownloadGeekWorkspaceFileRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BlockBranch) ProtoMessage() {}
+func (*DownloadGeekWorkspaceFileRequest) ProtoMessage() {}
 
-func (x *BlockBranch) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[81]
+func (x *DownloadGeekWorkspaceFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[133]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6080,63 +9649,43 @@ func (x *BlockBranch) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BlockBranch.ProtoReflect.Descriptor instead.
-func (*BlockBranch) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{81}
-}
-
-func (x *BlockBranch) GetBlock() *Block {
-	if x != nil {
-		return x.Block
-	}
-	return nil
+// Deprecated: Use DownloadGeekWorkspaceFileRequest.ProtoReflect.Descriptor instead.
+func (*DownloadGeekWorkspaceFileRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{133}
 }
 
-func (x *BlockBranch) GetBranchPath() string {
+func (x *DownloadGeekWorkspaceFileRequest) GetPath() string {
 	if x != nil {
-		return x.BranchPath
+		return x.Path
 	}
 	return ""
 }
 
-func (x *BlockBranch) GetIsActive() bool {
-	if x != nil {
-		return x.IsActive
-	}
-	return false
-}
-
-func (x *BlockBranch) GetChildren() []*BlockBranch {
-	if x != nil {
-		return x.Children
-	}
-	return nil
-}
-
-// SwitchBranchRequest is the request for SwitchBranch.
-type SwitchBranchRequest struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	ConversationId   int32                  `protobuf:"varint,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
-	TargetBranchPath string                 `protobuf:"bytes,2,opt,name=target_branch_path,json=targetBranchPath,proto3" json:"target_branch_path,omitempty"` // Target branch to activate
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+// DownloadGeekWorkspaceFileResponse is the response for DownloadGeekWorkspaceFile.
+type DownloadGeekWorkspaceFileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Filename      string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	ContentType   string                 `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // Always application/octet-stream
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SwitchBranchRequest) Reset() {
-	*x = SwitchBranchRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[82]
+func (x *DownloadGeekWorkspaceFileResponse) Reset() {
+	*x = DownloadGeekWorkspaceFileResponse{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[134]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SwitchBranchRequest) String() string {
+func (x *DownloadGeekWorkspaceFileResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SwitchBranchRequest) ProtoMessage() {}
+func (*DownloadGeekWorkspaceFileResponse) ProtoMessage() {}
 
-func (x *SwitchBranchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[82]
+func (x *DownloadGeekWorkspaceFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[134]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6147,49 +9696,55 @@ func (x *SwitchBranchRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SwitchBranchRequest.ProtoReflect.Descriptor instead.
-func (*SwitchBranchRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{82}
+// Deprecated: Use DownloadGeekWorkspaceFileResponse.ProtoReflect.Descriptor instead.
+func (*DownloadGeekWorkspaceFileResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{134}
 }
 
-func (x *SwitchBranchRequest) GetConversationId() int32 {
+func (x *DownloadGeekWorkspaceFileResponse) GetData() []byte {
 	if x != nil {
-		return x.ConversationId
+		return x.Data
 	}
-	return 0
+	return nil
 }
 
-func (x *SwitchBranchRequest) GetTargetBranchPath() string {
+func (x *DownloadGeekWorkspaceFileResponse) GetFilename() string {
 	if x != nil {
-		return x.TargetBranchPath
+		return x.Filename
 	}
 	return ""
 }
 
-// DeleteBranchRequest is the request for DeleteBranch.
-type DeleteBranchRequest struct {
+func (x *DownloadGeekWorkspaceFileResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+// DeleteGeekWorkspaceFileRequest is the request for DeleteGeekWorkspaceFile.
+type DeleteGeekWorkspaceFileRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`           // Block ID to delete (and all descendants)
-	Cascade       bool                   `protobuf:"varint,2,opt,name=cascade,proto3" json:"cascade,omitempty"` // If true, delete all descendants; otherwise only delete this block
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteBranchRequest) Reset() {
-	*x = DeleteBranchRequest{}
-	mi := &file_api_v1_ai_service_proto_msgTypes[83]
+func (x *DeleteGeekWorkspaceFileRequest) Reset() {
+	*x = DeleteGeekWorkspaceFileRequest{}
+	mi := &file_api_v1_ai_service_proto_msgTypes[135]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteBranchRequest) String() string {
+func (x *DeleteGeekWorkspaceFileRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteBranchRequest) ProtoMessage() {}
+func (*DeleteGeekWorkspaceFileRequest) ProtoMessage() {}
 
-func (x *DeleteBranchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_ai_service_proto_msgTypes[83]
+func (x *DeleteGeekWorkspaceFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ai_service_proto_msgTypes[135]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6200,30 +9755,23 @@ func (x *DeleteBranchRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteBranchRequest.ProtoReflect.Descriptor instead.
-func (*DeleteBranchRequest) Descriptor() ([]byte, []int) {
-	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{83}
-}
-
-func (x *DeleteBranchRequest) GetId() int64 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
+// Deprecated: Use DeleteGeekWorkspaceFileRequest.ProtoReflect.Descriptor instead.
+func (*DeleteGeekWorkspaceFileRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ai_service_proto_rawDescGZIP(), []int{135}
 }
 
-func (x *DeleteBranchRequest) GetCascade() bool {
+func (x *DeleteGeekWorkspaceFileRequest) GetPath() string {
 	if x != nil {
-		return x.Cascade
+		return x.Path
 	}
-	return false
+	return ""
 }
 
 var File_api_v1_ai_service_proto protoreflect.FileDescriptor
 
 const file_api_v1_ai_service_proto_rawDesc = "" +
 	"\n" +
-	"\x17api/v1/ai_service.proto\x12\fmemos.api.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x1bgoogle/protobuf/empty.proto\"H\n" +
+	"\x17api/v1/ai_service.proto\x12\fmemos.api.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1cgoogle/protobuf/struct.proto\"H\n" +
 	"\x15SemanticSearchRequest\x12\x19\n" +
 	"\x05query\x18\x01 \x01(\tB\x03\xe0A\x02R\x05query\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\x05R\x05limit\"N\n" +
@@ -6303,15 +9851,25 @@ const file_api_v1_ai_service_proto_rawDesc = "" +
 	"\x1aAddContextSeparatorRequest\x12,\n" +
 	"\x0fconversation_id\x18\x01 \x01(\x05B\x03\xe0A\x02R\x0econversationId\"P\n" +
 	" ClearConversationMessagesRequest\x12,\n" +
-	"\x0fconversation_id\x18\x01 \x01(\x05B\x03\xe0A\x02R\x0econversationId\"W\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x05B\x03\xe0A\x02R\x0econversationId\"\x1f\n" +
+	"\x1dCreateChatStreamTicketRequest\"I\n" +
+	"\x10ChatStreamTicket\x12\x16\n" +
+	"\x06ticket\x18\x01 \x01(\tR\x06ticket\x12\x1d\n" +
+	"\n" +
+	"expires_ts\x18\x02 \x01(\x03R\texpiresTs\"W\n" +
 	"\x0fStopChatRequest\x12,\n" +
 	"\x0fconversation_id\x18\x01 \x01(\x05B\x03\xe0A\x02R\x0econversationId\x12\x16\n" +
-	"\x06reason\x18\x02 \x01(\tR\x06reason\"\x98\x01\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\xa0\x01\n" +
+	"\x11SubmitFormRequest\x12\x1e\n" +
+	"\bblock_id\x18\x01 \x01(\x03B\x03\xe0A\x02R\ablockId\x12\x1c\n" +
+	"\aform_id\x18\x02 \x01(\tB\x03\xe0A\x02R\x06formId\x121\n" +
+	"\apayload\x18\x03 \x01(\v2\x17.google.protobuf.StructR\apayload\x12\x1a\n" +
+	"\btimezone\x18\x04 \x01(\tR\btimezone\"\x98\x01\n" +
 	"\x10DangerBlockEvent\x12\x1c\n" +
 	"\toperation\x18\x01 \x01(\tR\toperation\x12\x16\n" +
 	"\x06reason\x18\x02 \x01(\tR\x06reason\x12'\n" +
 	"\x0fpattern_matched\x18\x03 \x01(\tR\x0epatternMatched\x12%\n" +
-	"\x0ebypass_allowed\x18\x04 \x01(\bR\rbypassAllowed\"\xe3\x03\n" +
+	"\x0ebypass_allowed\x18\x04 \x01(\bR\rbypassAllowed\"\xfe\x03\n" +
 	"\fChatResponse\x12\x18\n" +
 	"\acontent\x18\x01 \x01(\tR\acontent\x12\x18\n" +
 	"\asources\x18\x02 \x03(\tR\asources\x12\x12\n" +
@@ -6326,7 +9884,8 @@ const file_api_v1_ai_service_proto_rawDesc = "" +
 	"event_meta\x18\b \x01(\v2\x1b.memos.api.v1.EventMetadataR\teventMeta\x12?\n" +
 	"\rblock_summary\x18\t \x01(\v2\x1a.memos.api.v1.BlockSummaryR\fblockSummary\x12\x19\n" +
 	"\bblock_id\x18\n" +
-	" \x01(\x03R\ablockId\"\x85\x01\n" +
+	" \x01(\x03R\ablockId\x12\x19\n" +
+	"\btrace_id\x18\v \x01(\tR\atraceId\"\x85\x01\n" +
 	"\x16ScheduleCreationIntent\x12\x1a\n" +
 	"\bdetected\x18\x01 \x01(\bR\bdetected\x121\n" +
 	"\x14schedule_description\x18\x02 \x01(\tR\x13scheduleDescription\x12\x1c\n" +
@@ -6734,7 +10293,272 @@ const file_api_v1_ai_service_proto_rawDesc = "" +
 	"\x12target_branch_path\x18\x02 \x01(\tB\x03\xe0A\x02R\x10targetBranchPath\"D\n" +
 	"\x13DeleteBranchRequest\x12\x13\n" +
 	"\x02id\x18\x01 \x01(\x03B\x03\xe0A\x02R\x02id\x12\x18\n" +
-	"\acascade\x18\x02 \x01(\bR\acascade*7\n" +
+	"\acascade\x18\x02 \x01(\bR\acascade\"\x11\n" +
+	"\x0fGetUsageRequest\"\x9a\x02\n" +
+	"\x05Usage\x12!\n" +
+	"\fperiod_start\x18\x01 \x01(\x03R\vperiodStart\x12\x1d\n" +
+	"\n" +
+	"period_end\x18\x02 \x01(\x03R\tperiodEnd\x12!\n" +
+	"\ftotal_tokens\x18\x03 \x01(\x03R\vtotalTokens\x12$\n" +
+	"\x0etotal_cost_usd\x18\x04 \x01(\x01R\ftotalCostUsd\x12#\n" +
+	"\rsession_count\x18\x05 \x01(\x03R\fsessionCount\x12\x1f\n" +
+	"\vtoken_quota\x18\x06 \x01(\x03R\n" +
+	"tokenQuota\x12$\n" +
+	"\x0ecost_quota_usd\x18\a \x01(\x01R\fcostQuotaUsd\x12\x1a\n" +
+	"\bexceeded\x18\b \x01(\bR\bexceeded\"(\n" +
+	"\x12RunSelfTestRequest\x12\x12\n" +
+	"\x04live\x18\x01 \x01(\bR\x04live\"t\n" +
+	"\rSelfTestCheck\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06passed\x18\x02 \x01(\bR\x06passed\x12\x16\n" +
+	"\x06detail\x18\x03 \x01(\tR\x06detail\x12\x1f\n" +
+	"\vduration_ms\x18\x04 \x01(\x03R\n" +
+	"durationMs\"\x92\x01\n" +
+	"\x0eSelfTestReport\x12\x16\n" +
+	"\x06passed\x18\x01 \x01(\bR\x06passed\x12\x12\n" +
+	"\x04mode\x18\x02 \x01(\tR\x04mode\x123\n" +
+	"\x06checks\x18\x03 \x03(\v2\x1b.memos.api.v1.SelfTestCheckR\x06checks\x12\x1f\n" +
+	"\vduration_ms\x18\x04 \x01(\x03R\n" +
+	"durationMs\"a\n" +
+	"\x16GetBlockChangesRequest\x12,\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x05B\x03\xe0A\x02R\x0econversationId\x12\x19\n" +
+	"\bsince_ts\x18\x02 \x01(\x03R\asinceTs\"u\n" +
+	"\vBlockChange\x12)\n" +
+	"\x05block\x18\x01 \x01(\v2\x13.memos.api.v1.BlockR\x05block\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\bR\acreated\x12!\n" +
+	"\fevent_offset\x18\x03 \x01(\x05R\veventOffset\"\x86\x01\n" +
+	"\x17GetBlockChangesResponse\x123\n" +
+	"\achanges\x18\x01 \x03(\v2\x19.memos.api.v1.BlockChangeR\achanges\x12\x1d\n" +
+	"\n" +
+	"block_uids\x18\x02 \x03(\tR\tblockUids\x12\x17\n" +
+	"\async_ts\x18\x03 \x01(\x03R\x06syncTs\"h\n" +
+	"\x19GetBlockTranscriptRequest\x12\x13\n" +
+	"\x02id\x18\x01 \x01(\x03B\x03\xe0A\x02R\x02id\x126\n" +
+	"\x06detail\x18\x02 \x01(\x0e2\x1e.memos.api.v1.TranscriptDetailR\x06detail\"\xd4\x03\n" +
+	"\x0fBlockTranscript\x12\x19\n" +
+	"\bblock_id\x18\x01 \x01(\x03R\ablockId\x12'\n" +
+	"\x0fconversation_id\x18\x02 \x01(\x05R\x0econversationId\x126\n" +
+	"\x06detail\x18\x03 \x01(\x0e2\x1e.memos.api.v1.TranscriptDetailR\x06detail\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1f\n" +
+	"\vuser_inputs\x18\x05 \x03(\tR\n" +
+	"userInputs\x12\x16\n" +
+	"\x06answer\x18\x06 \x01(\tR\x06answer\x122\n" +
+	"\x05tools\x18\a \x03(\v2\x1c.memos.api.v1.TranscriptToolR\x05tools\x127\n" +
+	"\aentries\x18\b \x03(\v2\x1d.memos.api.v1.TranscriptEntryR\aentries\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\x126\n" +
+	"\x06source\x18\n" +
+	" \x01(\v2\x1e.memos.api.v1.TranscriptSourceR\x06source\x12\x1a\n" +
+	"\bmarkdown\x18\v \x01(\tR\bmarkdown\x12\x1d\n" +
+	"\n" +
+	"created_ts\x18\f \x01(\x03R\tcreatedTs\"\x9f\x01\n" +
+	"\x0eTranscriptTool\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04line\x18\x02 \x01(\tR\x04line\x12\x16\n" +
+	"\x06failed\x18\x03 \x01(\bR\x06failed\x12\x1f\n" +
+	"\vduration_ms\x18\x04 \x01(\x03R\n" +
+	"durationMs\x12\x14\n" +
+	"\x05input\x18\x05 \x01(\tR\x05input\x12\x16\n" +
+	"\x06output\x18\x06 \x01(\tR\x06output\"q\n" +
+	"\x0fTranscriptEntry\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x120\n" +
+	"\x04tool\x18\x03 \x01(\v2\x1c.memos.api.v1.TranscriptToolR\x04tool\"\x9f\x01\n" +
+	"\x10TranscriptSource\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x10\n" +
+	"\x03uid\x18\x02 \x01(\tR\x03uid\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12;\n" +
+	"\bpassages\x18\x04 \x03(\v2\x1f.memos.api.v1.TranscriptPassageR\bpassages\x12\x12\n" +
+	"\x04text\x18\x05 \x01(\tR\x04text\"O\n" +
+	"\x11TranscriptPassage\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\x05R\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\x05R\x03end\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\"\x13\n" +
+	"\x11ReindexAllRequest\"0\n" +
+	"\x13ReindexSinceRequest\x12\x19\n" +
+	"\x05since\x18\x01 \x01(\x03B\x03\xe0A\x02R\x05since\"\x1b\n" +
+	"\x19GetReindexProgressRequest\"\xf9\x01\n" +
+	"\x0fReindexProgress\x12\x14\n" +
+	"\x05since\x18\x01 \x01(\x03R\x05since\x12\x18\n" +
+	"\arunning\x18\x02 \x01(\bR\arunning\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x05R\x05total\x12\x1c\n" +
+	"\tprocessed\x18\x04 \x01(\x05R\tprocessed\x12\x16\n" +
+	"\x06failed\x18\x05 \x01(\x05R\x06failed\x12\x14\n" +
+	"\x05model\x18\x06 \x01(\tR\x05model\x12\x1d\n" +
+	"\n" +
+	"started_ts\x18\a \x01(\x03R\tstartedTs\x12\x1f\n" +
+	"\vfinished_ts\x18\b \x01(\x03R\n" +
+	"finishedTs\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\"\xa5\x02\n" +
+	"\tMCPServer\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\ttransport\x18\x03 \x01(\tR\ttransport\x12\x18\n" +
+	"\acommand\x18\x04 \x01(\tR\acommand\x12\x12\n" +
+	"\x04args\x18\x05 \x03(\tR\x04args\x12\x10\n" +
+	"\x03url\x18\x06 \x01(\tR\x03url\x12\x18\n" +
+	"\aenabled\x18\a \x01(\bR\aenabled\x12\x1b\n" +
+	"\tenv_names\x18\b \x03(\tR\benvNames\x12!\n" +
+	"\fheader_names\x18\t \x03(\tR\vheaderNames\x12\x1d\n" +
+	"\n" +
+	"created_ts\x18\n" +
+	" \x01(\x03R\tcreatedTs\x12\x1d\n" +
+	"\n" +
+	"updated_ts\x18\v \x01(\x03R\tupdatedTs\"\x17\n" +
+	"\x15ListMCPServersRequest\"K\n" +
+	"\x16ListMCPServersResponse\x121\n" +
+	"\aservers\x18\x01 \x03(\v2\x17.memos.api.v1.MCPServerR\aservers\"\xc1\x03\n" +
+	"\x16CreateMCPServerRequest\x12\x17\n" +
+	"\x04name\x18\x01 \x01(\tB\x03\xe0A\x02R\x04name\x12!\n" +
+	"\ttransport\x18\x02 \x01(\tB\x03\xe0A\x02R\ttransport\x12\x18\n" +
+	"\acommand\x18\x03 \x01(\tR\acommand\x12\x12\n" +
+	"\x04args\x18\x04 \x03(\tR\x04args\x12?\n" +
+	"\x03env\x18\x05 \x03(\v2-.memos.api.v1.CreateMCPServerRequest.EnvEntryR\x03env\x12\x10\n" +
+	"\x03url\x18\x06 \x01(\tR\x03url\x12K\n" +
+	"\aheaders\x18\a \x03(\v21.memos.api.v1.CreateMCPServerRequest.HeadersEntryR\aheaders\x12\x1d\n" +
+	"\aenabled\x18\b \x01(\bH\x00R\aenabled\x88\x01\x01\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\n" +
+	"\n" +
+	"\b_enabled\"\x98\x04\n" +
+	"\x16UpdateMCPServerRequest\x12\x13\n" +
+	"\x02id\x18\x01 \x01(\x05B\x03\xe0A\x02R\x02id\x12\x17\n" +
+	"\x04name\x18\x02 \x01(\tB\x03\xe0A\x02R\x04name\x12!\n" +
+	"\ttransport\x18\x03 \x01(\tB\x03\xe0A\x02R\ttransport\x12\x18\n" +
+	"\acommand\x18\x04 \x01(\tR\acommand\x12\x12\n" +
+	"\x04args\x18\x05 \x03(\tR\x04args\x12?\n" +
+	"\x03env\x18\x06 \x03(\v2-.memos.api.v1.UpdateMCPServerRequest.EnvEntryR\x03env\x12\x10\n" +
+	"\x03url\x18\a \x01(\tR\x03url\x12K\n" +
+	"\aheaders\x18\b \x03(\v21.memos.api.v1.UpdateMCPServerRequest.HeadersEntryR\aheaders\x12\x1d\n" +
+	"\aenabled\x18\t \x01(\bH\x00R\aenabled\x88\x01\x01\x12\x1b\n" +
+	"\tclear_env\x18\n" +
+	" \x01(\bR\bclearEnv\x12#\n" +
+	"\rclear_headers\x18\v \x01(\bR\fclearHeaders\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\n" +
+	"\n" +
+	"\b_enabled\"-\n" +
+	"\x16DeleteMCPServerRequest\x12\x13\n" +
+	"\x02id\x18\x01 \x01(\x05B\x03\xe0A\x02R\x02id\"t\n" +
+	"\x19ImportConversationRequest\x12>\n" +
+	"\x06format\x18\x01 \x01(\x0e2&.memos.api.v1.ConversationImportFormatR\x06format\x12\x17\n" +
+	"\x04data\x18\x02 \x01(\tB\x03\xe0A\x02R\x04data\"\x80\x01\n" +
+	"\x1aImportConversationResponse\x12H\n" +
+	"\rconversations\x18\x01 \x03(\v2\".memos.api.v1.ImportedConversationR\rconversations\x12\x18\n" +
+	"\askipped\x18\x02 \x01(\x05R\askipped\"V\n" +
+	"\x14ImportedConversation\x12\x10\n" +
+	"\x03uid\x18\x01 \x01(\tR\x03uid\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
+	"\x06rounds\x18\x03 \x01(\x05R\x06rounds\"\xc1\x02\n" +
+	"\x11ActionAuditRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\tR\x04mode\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x04 \x01(\tR\tsessionId\x12\x12\n" +
+	"\x04tool\x18\x05 \x01(\tR\x04tool\x12\x17\n" +
+	"\atool_id\x18\x06 \x01(\tR\x06toolId\x12\x14\n" +
+	"\x05input\x18\a \x01(\tR\x05input\x12\x1d\n" +
+	"\n" +
+	"file_paths\x18\b \x03(\tR\tfilePaths\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\x12\x16\n" +
+	"\x06output\x18\n" +
+	" \x01(\tR\x06output\x12\x1f\n" +
+	"\vduration_ms\x18\v \x01(\x03R\n" +
+	"durationMs\x12\x1d\n" +
+	"\n" +
+	"created_ts\x18\f \x01(\x03R\tcreatedTs\"\xe2\x01\n" +
+	"\x11ActionAuditFilter\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x12\n" +
+	"\x04mode\x18\x02 \x01(\tR\x04mode\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x03 \x01(\tR\tsessionId\x12\x12\n" +
+	"\x04tool\x18\x04 \x01(\tR\x04tool\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x12\n" +
+	"\x04from\x18\x06 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\a \x01(\x03R\x02to\x12\x1b\n" +
+	"\tbefore_id\x18\b \x01(\x03R\bbeforeId\x12\x14\n" +
+	"\x05limit\x18\t \x01(\x05R\x05limit\"Q\n" +
+	"\x16ListActionAuditRequest\x127\n" +
+	"\x06filter\x18\x01 \x01(\v2\x1f.memos.api.v1.ActionAuditFilterR\x06filter\"z\n" +
+	"\x17ListActionAuditResponse\x129\n" +
+	"\arecords\x18\x01 \x03(\v2\x1f.memos.api.v1.ActionAuditRecordR\arecords\x12$\n" +
+	"\x0enext_before_id\x18\x02 \x01(\x03R\fnextBeforeId\"\x92\x01\n" +
+	"\x18ExportActionAuditRequest\x127\n" +
+	"\x06filter\x18\x01 \x01(\v2\x1f.memos.api.v1.ActionAuditFilterR\x06filter\x12=\n" +
+	"\x06format\x18\x02 \x01(\x0e2%.memos.api.v1.ActionAuditExportFormatR\x06format\"n\n" +
+	"\x19ExportActionAuditResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x1a\n" +
+	"\bfilename\x18\x02 \x01(\tR\bfilename\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\"n\n" +
+	"\vLLMProvider\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x19\n" +
+	"\bbase_url\x18\x03 \x01(\tR\abaseUrl\x12\x14\n" +
+	"\x05model\x18\x04 \x01(\tR\x05model\"\x8b\x01\n" +
+	"\n" +
+	"AgentModel\x12\x14\n" +
+	"\x05agent\x18\x01 \x01(\tR\x05agent\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12\x1d\n" +
+	"\n" +
+	"updated_ts\x18\x05 \x01(\x03R\tupdatedTs\"\x18\n" +
+	"\x16ListAgentModelsRequest\"\x84\x01\n" +
+	"\x17ListAgentModelsResponse\x127\n" +
+	"\tproviders\x18\x01 \x03(\v2\x19.memos.api.v1.LLMProviderR\tproviders\x120\n" +
+	"\x06agents\x18\x02 \x03(\v2\x18.memos.api.v1.AgentModelR\x06agents\"a\n" +
+	"\x17UpdateAgentModelRequest\x12\x14\n" +
+	"\x05agent\x18\x01 \x01(\tR\x05agent\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\"/\n" +
+	"\x17DeleteAgentModelRequest\x12\x14\n" +
+	"\x05agent\x18\x01 \x01(\tR\x05agent\"\x82\x01\n" +
+	"\x12GeekWorkspaceUsage\x12\x14\n" +
+	"\x05bytes\x18\x01 \x01(\x03R\x05bytes\x12\x14\n" +
+	"\x05files\x18\x02 \x01(\x05R\x05files\x12\x1f\n" +
+	"\vlimit_bytes\x18\x03 \x01(\x03R\n" +
+	"limitBytes\x12\x1f\n" +
+	"\vmeasured_ts\x18\x04 \x01(\x03R\n" +
+	"measuredTs\"\xa2\x01\n" +
+	"\x12GeekWorkspaceEntry\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x15\n" +
+	"\x06is_dir\x18\x03 \x01(\bR\x05isDir\x12\x18\n" +
+	"\asymlink\x18\x04 \x01(\bR\asymlink\x12\x12\n" +
+	"\x04size\x18\x05 \x01(\x03R\x04size\x12\x1f\n" +
+	"\vmodified_ts\x18\x06 \x01(\x03R\n" +
+	"modifiedTs\"\x1e\n" +
+	"\x1cGetGeekWorkspaceUsageRequest\"3\n" +
+	"\x1dListGeekWorkspaceFilesRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"\x8e\x01\n" +
+	"\x1eListGeekWorkspaceFilesResponse\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12:\n" +
+	"\aentries\x18\x02 \x03(\v2 .memos.api.v1.GeekWorkspaceEntryR\aentries\x12\x1c\n" +
+	"\ttruncated\x18\x03 \x01(\bR\ttruncated\"2\n" +
+	"\x1cReadGeekWorkspaceFileRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"\xbf\x01\n" +
+	"\x18GeekWorkspaceFileContent\x126\n" +
+	"\x05entry\x18\x01 \x01(\v2 .memos.api.v1.GeekWorkspaceEntryR\x05entry\x12\x1b\n" +
+	"\tmime_type\x18\x02 \x01(\tR\bmimeType\x12\x16\n" +
+	"\x06binary\x18\x03 \x01(\bR\x06binary\x12\x1c\n" +
+	"\ttruncated\x18\x04 \x01(\bR\ttruncated\x12\x18\n" +
+	"\acontent\x18\x05 \x01(\tR\acontent\"6\n" +
+	" DownloadGeekWorkspaceFileRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"v\n" +
+	"!DownloadGeekWorkspaceFileResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x1a\n" +
+	"\bfilename\x18\x02 \x01(\tR\bfilename\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\"4\n" +
+	"\x1eDeleteGeekWorkspaceFileRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path*7\n" +
 	"\x11ScheduleQueryMode\x12\b\n" +
 	"\x04AUTO\x10\x00\x12\f\n" +
 	"\bSTANDARD\x10\x01\x12\n" +
@@ -6760,13 +10584,26 @@ const file_api_v1_ai_service_proto_rawDesc = "" +
 	"\x16BLOCK_MODE_UNSPECIFIED\x10\x00\x12\x15\n" +
 	"\x11BLOCK_MODE_NORMAL\x10\x01\x12\x13\n" +
 	"\x0fBLOCK_MODE_GEEK\x10\x02\x12\x18\n" +
-	"\x14BLOCK_MODE_EVOLUTION\x10\x03*\x95\x01\n" +
+	"\x14BLOCK_MODE_EVOLUTION\x10\x03*\xb3\x01\n" +
 	"\vBlockStatus\x12\x1c\n" +
 	"\x18BLOCK_STATUS_UNSPECIFIED\x10\x00\x12\x18\n" +
 	"\x14BLOCK_STATUS_PENDING\x10\x01\x12\x1a\n" +
 	"\x16BLOCK_STATUS_STREAMING\x10\x02\x12\x1a\n" +
 	"\x16BLOCK_STATUS_COMPLETED\x10\x03\x12\x16\n" +
-	"\x12BLOCK_STATUS_ERROR\x10\x042\xd4(\n" +
+	"\x12BLOCK_STATUS_ERROR\x10\x04\x12\x1c\n" +
+	"\x18BLOCK_STATUS_INTERRUPTED\x10\x05*p\n" +
+	"\x10TranscriptDetail\x12!\n" +
+	"\x1dTRANSCRIPT_DETAIL_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19TRANSCRIPT_DETAIL_MINIMAL\x10\x01\x12\x1a\n" +
+	"\x16TRANSCRIPT_DETAIL_FULL\x10\x02*\x95\x01\n" +
+	"\x18ConversationImportFormat\x12*\n" +
+	"&CONVERSATION_IMPORT_FORMAT_UNSPECIFIED\x10\x00\x12&\n" +
+	"\"CONVERSATION_IMPORT_FORMAT_CHATGPT\x10\x01\x12%\n" +
+	"!CONVERSATION_IMPORT_FORMAT_CLAUDE\x10\x02*\x8f\x01\n" +
+	"\x17ActionAuditExportFormat\x12*\n" +
+	"&ACTION_AUDIT_EXPORT_FORMAT_UNSPECIFIED\x10\x00\x12\"\n" +
+	"\x1eACTION_AUDIT_EXPORT_FORMAT_CSV\x10\x01\x12$\n" +
+	" ACTION_AUDIT_EXPORT_FORMAT_JSONL\x10\x022\xd6A\n" +
 	"\tAIService\x12y\n" +
 	"\x0eSemanticSearch\x12#.memos.api.v1.SemanticSearchRequest\x1a$.memos.api.v1.SemanticSearchResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/api/v1/ai/search\x12v\n" +
 	"\vSuggestTags\x12 .memos.api.v1.SuggestTagsRequest\x1a!.memos.api.v1.SuggestTagsResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/api/v1/ai/suggest-tags\x12a\n" +
@@ -6789,19 +10626,25 @@ const file_api_v1_ai_service_proto_rawDesc = "" +
 	"\x11GetAIConversation\x12&.memos.api.v1.GetAIConversationRequest\x1a\x1c.memos.api.v1.AIConversation\"%\x82\xd3\xe4\x93\x02\x1f\x12\x1d/api/v1/ai/conversations/{id}\x12\x84\x01\n" +
 	"\x14CreateAIConversation\x12).memos.api.v1.CreateAIConversationRequest\x1a\x1c.memos.api.v1.AIConversation\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/api/v1/ai/conversations\x12\x89\x01\n" +
 	"\x14UpdateAIConversation\x12).memos.api.v1.UpdateAIConversationRequest\x1a\x1c.memos.api.v1.AIConversation\"(\x82\xd3\xe4\x93\x02\":\x01*2\x1d/api/v1/ai/conversations/{id}\x12\xb5\x01\n" +
-	"\x19GenerateConversationTitle\x12..memos.api.v1.GenerateConversationTitleRequest\x1a/.memos.api.v1.GenerateConversationTitleResponse\"7\x82\xd3\xe4\x93\x021:\x01*\",/api/v1/ai/conversations/{id}/generate-title\x12\x80\x01\n" +
+	"\x19GenerateConversationTitle\x12..memos.api.v1.GenerateConversationTitleRequest\x1a/.memos.api.v1.GenerateConversationTitleResponse\"7\x82\xd3\xe4\x93\x021:\x01*\",/api/v1/ai/conversations/{id}/generate-title\x12\x93\x01\n" +
+	"\x12ImportConversation\x12'.memos.api.v1.ImportConversationRequest\x1a(.memos.api.v1.ImportConversationResponse\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/api/v1/ai/conversations/import\x12\x80\x01\n" +
 	"\x14DeleteAIConversation\x12).memos.api.v1.DeleteAIConversationRequest\x1a\x16.google.protobuf.Empty\"%\x82\xd3\xe4\x93\x02\x1f*\x1d/api/v1/ai/conversations/{id}\x12\x98\x01\n" +
 	"\x13AddContextSeparator\x12(.memos.api.v1.AddContextSeparatorRequest\x1a\x16.google.protobuf.Empty\"?\x82\xd3\xe4\x93\x029:\x01*\"4/api/v1/ai/conversations/{conversation_id}/separator\x12\xa0\x01\n" +
-	"\x19ClearConversationMessages\x12..memos.api.v1.ClearConversationMessagesRequest\x1a\x16.google.protobuf.Empty\";\x82\xd3\xe4\x93\x025*3/api/v1/ai/conversations/{conversation_id}/messages\x12b\n" +
-	"\bStopChat\x12\x1d.memos.api.v1.StopChatRequest\x1a\x16.google.protobuf.Empty\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/api/v1/ai/chat/stop\x12}\n" +
+	"\x19ClearConversationMessages\x12..memos.api.v1.ClearConversationMessagesRequest\x1a\x16.google.protobuf.Empty\";\x82\xd3\xe4\x93\x025*3/api/v1/ai/conversations/{conversation_id}/messages\x12\x90\x01\n" +
+	"\x16CreateChatStreamTicket\x12+.memos.api.v1.CreateChatStreamTicketRequest\x1a\x1e.memos.api.v1.ChatStreamTicket\")\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/api/v1/ai/chat/stream-tickets\x12b\n" +
+	"\bStopChat\x12\x1d.memos.api.v1.StopChatRequest\x1a\x16.google.protobuf.Empty\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/api/v1/ai/chat/stop\x12y\n" +
+	"\n" +
+	"SubmitForm\x12\x1f.memos.api.v1.SubmitFormRequest\x1a\x1a.memos.api.v1.ChatResponse\",\x82\xd3\xe4\x93\x02&:\x01*\"!/api/v1/ai/blocks/{block_id}/form0\x01\x12}\n" +
 	"\x0fGetSessionStats\x12$.memos.api.v1.GetSessionStatsRequest\x1a\x1a.memos.api.v1.SessionStats\"(\x82\xd3\xe4\x93\x02\"\x12 /api/v1/ai/sessions/{session_id}\x12~\n" +
 	"\x10ListSessionStats\x12%.memos.api.v1.ListSessionStatsRequest\x1a&.memos.api.v1.ListSessionStatsResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/api/v1/ai/sessions\x12i\n" +
 	"\fGetCostStats\x12!.memos.api.v1.GetCostStatsRequest\x1a\x17.memos.api.v1.CostStats\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/api/v1/ai/cost-stats\x12o\n" +
 	"\x13GetUserCostSettings\x12\x16.google.protobuf.Empty\x1a\x1e.memos.api.v1.UserCostSettings\" \x82\xd3\xe4\x93\x02\x1a\x12\x18/api/v1/ai/cost-settings\x12\x84\x01\n" +
 	"\x13SetUserCostSettings\x12(.memos.api.v1.SetUserCostSettingsRequest\x1a\x1e.memos.api.v1.UserCostSettings\"#\x82\xd3\xe4\x93\x02\x1d:\x01*2\x18/api/v1/ai/cost-settings\x12\x8a\x01\n" +
 	"\n" +
-	"ListBlocks\x12\x1f.memos.api.v1.ListBlocksRequest\x1a .memos.api.v1.ListBlocksResponse\"9\x82\xd3\xe4\x93\x023\x121/api/v1/ai/conversations/{conversation_id}/blocks\x12^\n" +
-	"\bGetBlock\x12\x1d.memos.api.v1.GetBlockRequest\x1a\x13.memos.api.v1.Block\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/api/v1/ai/blocks/{id}\x12\x82\x01\n" +
+	"ListBlocks\x12\x1f.memos.api.v1.ListBlocksRequest\x1a .memos.api.v1.ListBlocksResponse\"9\x82\xd3\xe4\x93\x023\x121/api/v1/ai/conversations/{conversation_id}/blocks\x12\xa0\x01\n" +
+	"\x0fGetBlockChanges\x12$.memos.api.v1.GetBlockChangesRequest\x1a%.memos.api.v1.GetBlockChangesResponse\"@\x82\xd3\xe4\x93\x02:\x128/api/v1/ai/conversations/{conversation_id}/block-changes\x12^\n" +
+	"\bGetBlock\x12\x1d.memos.api.v1.GetBlockRequest\x1a\x13.memos.api.v1.Block\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/api/v1/ai/blocks/{id}\x12\x87\x01\n" +
+	"\x12GetBlockTranscript\x12'.memos.api.v1.GetBlockTranscriptRequest\x1a\x1d.memos.api.v1.BlockTranscript\")\x82\xd3\xe4\x93\x02#\x12!/api/v1/ai/blocks/{id}/transcript\x12\x82\x01\n" +
 	"\vCreateBlock\x12 .memos.api.v1.CreateBlockRequest\x1a\x13.memos.api.v1.Block\"<\x82\xd3\xe4\x93\x026:\x01*\"1/api/v1/ai/conversations/{conversation_id}/blocks\x12g\n" +
 	"\vUpdateBlock\x12 .memos.api.v1.UpdateBlockRequest\x1a\x13.memos.api.v1.Block\"!\x82\xd3\xe4\x93\x02\x1b:\x01*2\x16/api/v1/ai/blocks/{id}\x12g\n" +
 	"\vDeleteBlock\x12 .memos.api.v1.DeleteBlockRequest\x1a\x16.google.protobuf.Empty\"\x1e\x82\xd3\xe4\x93\x02\x18*\x16/api/v1/ai/blocks/{id}\x12y\n" +
@@ -6810,7 +10653,27 @@ const file_api_v1_ai_service_proto_rawDesc = "" +
 	"\tForkBlock\x12\x1e.memos.api.v1.ForkBlockRequest\x1a\x13.memos.api.v1.Block\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/api/v1/ai/blocks/{id}/fork\x12\x8d\x01\n" +
 	"\x11ListBlockBranches\x12&.memos.api.v1.ListBlockBranchesRequest\x1a'.memos.api.v1.ListBlockBranchesResponse\"'\x82\xd3\xe4\x93\x02!\x12\x1f/api/v1/ai/blocks/{id}/branches\x12\x8e\x01\n" +
 	"\fSwitchBranch\x12!.memos.api.v1.SwitchBranchRequest\x1a\x16.google.protobuf.Empty\"C\x82\xd3\xe4\x93\x02=:\x01*\"8/api/v1/ai/conversations/{conversation_id}/switch-branch\x12p\n" +
-	"\fDeleteBranch\x12!.memos.api.v1.DeleteBranchRequest\x1a\x16.google.protobuf.Empty\"%\x82\xd3\xe4\x93\x02\x1f*\x1d/api/v1/ai/blocks/{id}/branchB\xa9\x01\n" +
+	"\fDeleteBranch\x12!.memos.api.v1.DeleteBranchRequest\x1a\x16.google.protobuf.Empty\"%\x82\xd3\xe4\x93\x02\x1f*\x1d/api/v1/ai/blocks/{id}/branch\x12X\n" +
+	"\bGetUsage\x12\x1d.memos.api.v1.GetUsageRequest\x1a\x13.memos.api.v1.Usage\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/api/v1/ai/usage\x12n\n" +
+	"\vRunSelfTest\x12 .memos.api.v1.RunSelfTestRequest\x1a\x1c.memos.api.v1.SelfTestReport\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/api/v1/ai/self-test\x12v\n" +
+	"\n" +
+	"ReindexAll\x12\x1f.memos.api.v1.ReindexAllRequest\x1a\x1d.memos.api.v1.ReindexProgress\"(\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/api/v1/ai/embeddings/reindex\x12\x80\x01\n" +
+	"\fReindexSince\x12!.memos.api.v1.ReindexSinceRequest\x1a\x1d.memos.api.v1.ReindexProgress\".\x82\xd3\xe4\x93\x02(:\x01*\"#/api/v1/ai/embeddings/reindex-since\x12\x83\x01\n" +
+	"\x12GetReindexProgress\x12'.memos.api.v1.GetReindexProgressRequest\x1a\x1d.memos.api.v1.ReindexProgress\"%\x82\xd3\xe4\x93\x02\x1f\x12\x1d/api/v1/ai/embeddings/reindex\x12{\n" +
+	"\x0eListMCPServers\x12#.memos.api.v1.ListMCPServersRequest\x1a$.memos.api.v1.ListMCPServersResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/api/v1/ai/mcp-servers\x12s\n" +
+	"\x0fCreateMCPServer\x12$.memos.api.v1.CreateMCPServerRequest\x1a\x17.memos.api.v1.MCPServer\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/api/v1/ai/mcp-servers\x12x\n" +
+	"\x0fUpdateMCPServer\x12$.memos.api.v1.UpdateMCPServerRequest\x1a\x17.memos.api.v1.MCPServer\"&\x82\xd3\xe4\x93\x02 :\x01*\x1a\x1b/api/v1/ai/mcp-servers/{id}\x12t\n" +
+	"\x0fDeleteMCPServer\x12$.memos.api.v1.DeleteMCPServerRequest\x1a\x16.google.protobuf.Empty\"#\x82\xd3\xe4\x93\x02\x1d*\x1b/api/v1/ai/mcp-servers/{id}\x12\x7f\n" +
+	"\x0fListActionAudit\x12$.memos.api.v1.ListActionAuditRequest\x1a%.memos.api.v1.ListActionAuditResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/api/v1/ai/action-audit\x12\x8c\x01\n" +
+	"\x11ExportActionAudit\x12&.memos.api.v1.ExportActionAuditRequest\x1a'.memos.api.v1.ExportActionAuditResponse\"&\x82\xd3\xe4\x93\x02 \x12\x1e/api/v1/ai/action-audit:export\x12y\n" +
+	"\x0fListAgentModels\x12$.memos.api.v1.ListAgentModelsRequest\x1a%.memos.api.v1.ListAgentModelsResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/api/v1/ai/models\x12y\n" +
+	"\x10UpdateAgentModel\x12%.memos.api.v1.UpdateAgentModelRequest\x1a\x18.memos.api.v1.AgentModel\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\x1a\x19/api/v1/ai/models/{agent}\x12t\n" +
+	"\x10DeleteAgentModel\x12%.memos.api.v1.DeleteAgentModelRequest\x1a\x16.google.protobuf.Empty\"!\x82\xd3\xe4\x93\x02\x1b*\x19/api/v1/ai/models/{agent}\x12\x8e\x01\n" +
+	"\x15GetGeekWorkspaceUsage\x12*.memos.api.v1.GetGeekWorkspaceUsageRequest\x1a .memos.api.v1.GeekWorkspaceUsage\"'\x82\xd3\xe4\x93\x02!\x12\x1f/api/v1/ai/geek-workspace/usage\x12\x9c\x01\n" +
+	"\x16ListGeekWorkspaceFiles\x12+.memos.api.v1.ListGeekWorkspaceFilesRequest\x1a,.memos.api.v1.ListGeekWorkspaceFilesResponse\"'\x82\xd3\xe4\x93\x02!\x12\x1f/api/v1/ai/geek-workspace/files\x12\x99\x01\n" +
+	"\x15ReadGeekWorkspaceFile\x12*.memos.api.v1.ReadGeekWorkspaceFileRequest\x1a&.memos.api.v1.GeekWorkspaceFileContent\",\x82\xd3\xe4\x93\x02&\x12$/api/v1/ai/geek-workspace/files:read\x12\xae\x01\n" +
+	"\x19DownloadGeekWorkspaceFile\x12..memos.api.v1.DownloadGeekWorkspaceFileRequest\x1a/.memos.api.v1.DownloadGeekWorkspaceFileResponse\"0\x82\xd3\xe4\x93\x02*\x12(/api/v1/ai/geek-workspace/files:download\x12\x88\x01\n" +
+	"\x17DeleteGeekWorkspaceFile\x12,.memos.api.v1.DeleteGeekWorkspaceFileRequest\x1a\x16.google.protobuf.Empty\"'\x82\xd3\xe4\x93\x02!*\x1f/api/v1/ai/geek-workspace/filesB\xa9\x01\n" +
 	"\x10com.memos.api.v1B\x0eAiServiceProtoP\x01Z3github.com/hrygo/divinesense/proto/gen/api/v1;apiv1\xa2\x02\x03MAX\xaa\x02\fMemos.Api.V1\xca\x02\fMemos\\Api\\V1\xe2\x02\x18Memos\\Api\\V1\\GPBMetadata\xea\x02\x0eMemos::Api::V1b\x06proto3"
 
 var (
@@ -6825,8 +10688,8 @@ func file_api_v1_ai_service_proto_rawDescGZIP() []byte {
 	return file_api_v1_ai_service_proto_rawDescData
 }
 
-var file_api_v1_ai_service_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
-var file_api_v1_ai_service_proto_msgTypes = make([]protoimpl.MessageInfo, 84)
+var file_api_v1_ai_service_proto_enumTypes = make([]protoimpl.EnumInfo, 9)
+var file_api_v1_ai_service_proto_msgTypes = make([]protoimpl.MessageInfo, 140)
 var file_api_v1_ai_service_proto_goTypes = []any{
 	(ScheduleQueryMode)(0),                    // 0: memos.api.v1.ScheduleQueryMode
 	(AgentType)(0),                            // 1: memos.api.v1.AgentType
@@ -6834,231 +10697,365 @@ var file_api_v1_ai_service_proto_goTypes = []any{
 	(BlockType)(0),                            // 3: memos.api.v1.BlockType
 	(BlockMode)(0),                            // 4: memos.api.v1.BlockMode
 	(BlockStatus)(0),                          // 5: memos.api.v1.BlockStatus
-	(*SemanticSearchRequest)(nil),             // 6: memos.api.v1.SemanticSearchRequest
-	(*SemanticSearchResponse)(nil),            // 7: memos.api.v1.SemanticSearchResponse
-	(*SearchResult)(nil),                      // 8: memos.api.v1.SearchResult
-	(*SuggestTagsRequest)(nil),                // 9: memos.api.v1.SuggestTagsRequest
-	(*SuggestTagsResponse)(nil),               // 10: memos.api.v1.SuggestTagsResponse
-	(*FormatRequest)(nil),                     // 11: memos.api.v1.FormatRequest
-	(*FormatResponse)(nil),                    // 12: memos.api.v1.FormatResponse
-	(*SummaryRequest)(nil),                    // 13: memos.api.v1.SummaryRequest
-	(*SummaryResponse)(nil),                   // 14: memos.api.v1.SummaryResponse
-	(*ChatRequest)(nil),                       // 15: memos.api.v1.ChatRequest
-	(*AIConversation)(nil),                    // 16: memos.api.v1.AIConversation
-	(*ListAIConversationsRequest)(nil),        // 17: memos.api.v1.ListAIConversationsRequest
-	(*ListAIConversationsResponse)(nil),       // 18: memos.api.v1.ListAIConversationsResponse
-	(*GetAIConversationRequest)(nil),          // 19: memos.api.v1.GetAIConversationRequest
-	(*CreateAIConversationRequest)(nil),       // 20: memos.api.v1.CreateAIConversationRequest
-	(*UpdateAIConversationRequest)(nil),       // 21: memos.api.v1.UpdateAIConversationRequest
-	(*GenerateConversationTitleRequest)(nil),  // 22: memos.api.v1.GenerateConversationTitleRequest
-	(*GenerateConversationTitleResponse)(nil), // 23: memos.api.v1.GenerateConversationTitleResponse
-	(*DeleteAIConversationRequest)(nil),       // 24: memos.api.v1.DeleteAIConversationRequest
-	(*AddContextSeparatorRequest)(nil),        // 25: memos.api.v1.AddContextSeparatorRequest
-	(*ClearConversationMessagesRequest)(nil),  // 26: memos.api.v1.ClearConversationMessagesRequest
-	(*StopChatRequest)(nil),                   // 27: memos.api.v1.StopChatRequest
-	(*DangerBlockEvent)(nil),                  // 28: memos.api.v1.DangerBlockEvent
-	(*ChatResponse)(nil),                      // 29: memos.api.v1.ChatResponse
-	(*ScheduleCreationIntent)(nil),            // 30: memos.api.v1.ScheduleCreationIntent
-	(*ScheduleQueryResult)(nil),               // 31: memos.api.v1.ScheduleQueryResult
-	(*ScheduleSummary)(nil),                   // 32: memos.api.v1.ScheduleSummary
-	(*GetRelatedMemosRequest)(nil),            // 33: memos.api.v1.GetRelatedMemosRequest
-	(*GetRelatedMemosResponse)(nil),           // 34: memos.api.v1.GetRelatedMemosResponse
-	(*ParrotSelfCognition)(nil),               // 35: memos.api.v1.ParrotSelfCognition
-	(*GetParrotSelfCognitionRequest)(nil),     // 36: memos.api.v1.GetParrotSelfCognitionRequest
-	(*GetParrotSelfCognitionResponse)(nil),    // 37: memos.api.v1.GetParrotSelfCognitionResponse
-	(*ListParrotsRequest)(nil),                // 38: memos.api.v1.ListParrotsRequest
-	(*ListParrotsResponse)(nil),               // 39: memos.api.v1.ListParrotsResponse
-	(*ParrotInfo)(nil),                        // 40: memos.api.v1.ParrotInfo
-	(*DetectDuplicatesRequest)(nil),           // 41: memos.api.v1.DetectDuplicatesRequest
-	(*DetectDuplicatesResponse)(nil),          // 42: memos.api.v1.DetectDuplicatesResponse
-	(*SimilarMemo)(nil),                       // 43: memos.api.v1.SimilarMemo
-	(*SimilarityBreakdown)(nil),               // 44: memos.api.v1.SimilarityBreakdown
-	(*MergeMemosRequest)(nil),                 // 45: memos.api.v1.MergeMemosRequest
-	(*MergeMemosResponse)(nil),                // 46: memos.api.v1.MergeMemosResponse
-	(*LinkMemosRequest)(nil),                  // 47: memos.api.v1.LinkMemosRequest
-	(*LinkMemosResponse)(nil),                 // 48: memos.api.v1.LinkMemosResponse
-	(*GetKnowledgeGraphRequest)(nil),          // 49: memos.api.v1.GetKnowledgeGraphRequest
-	(*GetKnowledgeGraphResponse)(nil),         // 50: memos.api.v1.GetKnowledgeGraphResponse
-	(*GraphNode)(nil),                         // 51: memos.api.v1.GraphNode
-	(*GraphEdge)(nil),                         // 52: memos.api.v1.GraphEdge
-	(*GraphStats)(nil),                        // 53: memos.api.v1.GraphStats
-	(*GetDueReviewsRequest)(nil),              // 54: memos.api.v1.GetDueReviewsRequest
-	(*GetDueReviewsResponse)(nil),             // 55: memos.api.v1.GetDueReviewsResponse
-	(*ReviewItem)(nil),                        // 56: memos.api.v1.ReviewItem
-	(*RecordReviewRequest)(nil),               // 57: memos.api.v1.RecordReviewRequest
-	(*RecordRouterFeedbackRequest)(nil),       // 58: memos.api.v1.RecordRouterFeedbackRequest
-	(*GetReviewStatsRequest)(nil),             // 59: memos.api.v1.GetReviewStatsRequest
-	(*GetReviewStatsResponse)(nil),            // 60: memos.api.v1.GetReviewStatsResponse
-	(*EventMetadata)(nil),                     // 61: memos.api.v1.EventMetadata
-	(*BlockSummary)(nil),                      // 62: memos.api.v1.BlockSummary
-	(*SessionStats)(nil),                      // 63: memos.api.v1.SessionStats
-	(*GetSessionStatsRequest)(nil),            // 64: memos.api.v1.GetSessionStatsRequest
-	(*ListSessionStatsRequest)(nil),           // 65: memos.api.v1.ListSessionStatsRequest
-	(*ListSessionStatsResponse)(nil),          // 66: memos.api.v1.ListSessionStatsResponse
-	(*GetCostStatsRequest)(nil),               // 67: memos.api.v1.GetCostStatsRequest
-	(*CostStats)(nil),                         // 68: memos.api.v1.CostStats
-	(*DailyCostData)(nil),                     // 69: memos.api.v1.DailyCostData
-	(*UserCostSettings)(nil),                  // 70: memos.api.v1.UserCostSettings
-	(*SetUserCostSettingsRequest)(nil),        // 71: memos.api.v1.SetUserCostSettingsRequest
-	(*Block)(nil),                             // 72: memos.api.v1.Block
-	(*TokenUsage)(nil),                        // 73: memos.api.v1.TokenUsage
-	(*UserInput)(nil),                         // 74: memos.api.v1.UserInput
-	(*BlockEvent)(nil),                        // 75: memos.api.v1.BlockEvent
-	(*ListBlocksRequest)(nil),                 // 76: memos.api.v1.ListBlocksRequest
-	(*ListBlocksResponse)(nil),                // 77: memos.api.v1.ListBlocksResponse
-	(*GetBlockRequest)(nil),                   // 78: memos.api.v1.GetBlockRequest
-	(*CreateBlockRequest)(nil),                // 79: memos.api.v1.CreateBlockRequest
-	(*UpdateBlockRequest)(nil),                // 80: memos.api.v1.UpdateBlockRequest
-	(*DeleteBlockRequest)(nil),                // 81: memos.api.v1.DeleteBlockRequest
-	(*AppendUserInputRequest)(nil),            // 82: memos.api.v1.AppendUserInputRequest
-	(*AppendEventRequest)(nil),                // 83: memos.api.v1.AppendEventRequest
-	(*ForkBlockRequest)(nil),                  // 84: memos.api.v1.ForkBlockRequest
-	(*ListBlockBranchesRequest)(nil),          // 85: memos.api.v1.ListBlockBranchesRequest
-	(*ListBlockBranchesResponse)(nil),         // 86: memos.api.v1.ListBlockBranchesResponse
-	(*BlockBranch)(nil),                       // 87: memos.api.v1.BlockBranch
-	(*SwitchBranchRequest)(nil),               // 88: memos.api.v1.SwitchBranchRequest
-	(*DeleteBranchRequest)(nil),               // 89: memos.api.v1.DeleteBranchRequest
-	(*emptypb.Empty)(nil),                     // 90: google.protobuf.Empty
+	(TranscriptDetail)(0),                     // 6: memos.api.v1.TranscriptDetail
+	(ConversationImportFormat)(0),             // 7: memos.api.v1.ConversationImportFormat
+	(ActionAuditExportFormat)(0),              // 8: memos.api.v1.ActionAuditExportFormat
+	(*SemanticSearchRequest)(nil),             // 9: memos.api.v1.SemanticSearchRequest
+	(*SemanticSearchResponse)(nil),            // 10: memos.api.v1.SemanticSearchResponse
+	(*SearchResult)(nil),                      // 11: memos.api.v1.SearchResult
+	(*SuggestTagsRequest)(nil),                // 12: memos.api.v1.SuggestTagsRequest
+	(*SuggestTagsResponse)(nil),               // 13: memos.api.v1.SuggestTagsResponse
+	(*FormatRequest)(nil),                     // 14: memos.api.v1.FormatRequest
+	(*FormatResponse)(nil),                    // 15: memos.api.v1.FormatResponse
+	(*SummaryRequest)(nil),                    // 16: memos.api.v1.SummaryRequest
+	(*SummaryResponse)(nil),                   // 17: memos.api.v1.SummaryResponse
+	(*ChatRequest)(nil),                       // 18: memos.api.v1.ChatRequest
+	(*AIConversation)(nil),                    // 19: memos.api.v1.AIConversation
+	(*ListAIConversationsRequest)(nil),        // 20: memos.api.v1.ListAIConversationsRequest
+	(*ListAIConversationsResponse)(nil),       // 21: memos.api.v1.ListAIConversationsResponse
+	(*GetAIConversationRequest)(nil),          // 22: memos.api.v1.GetAIConversationRequest
+	(*CreateAIConversationRequest)(nil),       // 23: memos.api.v1.CreateAIConversationRequest
+	(*UpdateAIConversationRequest)(nil),       // 24: memos.api.v1.UpdateAIConversationRequest
+	(*GenerateConversationTitleRequest)(nil),  // 25: memos.api.v1.GenerateConversationTitleRequest
+	(*GenerateConversationTitleResponse)(nil), // 26: memos.api.v1.GenerateConversationTitleResponse
+	(*DeleteAIConversationRequest)(nil),       // 27: memos.api.v1.DeleteAIConversationRequest
+	(*AddContextSeparatorRequest)(nil),        // 28: memos.api.v1.AddContextSeparatorRequest
+	(*ClearConversationMessagesRequest)(nil),  // 29: memos.api.v1.ClearConversationMessagesRequest
+	(*CreateChatStreamTicketRequest)(nil),     // 30: memos.api.v1.CreateChatStreamTicketRequest
+	(*ChatStreamTicket)(nil),                  // 31: memos.api.v1.ChatStreamTicket
+	(*StopChatRequest)(nil),                   // 32: memos.api.v1.StopChatRequest
+	(*SubmitFormRequest)(nil),                 // 33: memos.api.v1.SubmitFormRequest
+	(*DangerBlockEvent)(nil),                  // 34: memos.api.v1.DangerBlockEvent
+	(*ChatResponse)(nil),                      // 35: memos.api.v1.ChatResponse
+	(*ScheduleCreationIntent)(nil),            // 36: memos.api.v1.ScheduleCreationIntent
+	(*ScheduleQueryResult)(nil),               // 37: memos.api.v1.ScheduleQueryResult
+	(*ScheduleSummary)(nil),                   // 38: memos.api.v1.ScheduleSummary
+	(*GetRelatedMemosRequest)(nil),            // 39: memos.api.v1.GetRelatedMemosRequest
+	(*GetRelatedMemosResponse)(nil),           // 40: memos.api.v1.GetRelatedMemosResponse
+	(*ParrotSelfCognition)(nil),               // 41: memos.api.v1.ParrotSelfCognition
+	(*GetParrotSelfCognitionRequest)(nil),     // 42: memos.api.v1.GetParrotSelfCognitionRequest
+	(*GetParrotSelfCognitionResponse)(nil),    // 43: memos.api.v1.GetParrotSelfCognitionResponse
+	(*ListParrotsRequest)(nil),                // 44: memos.api.v1.ListParrotsRequest
+	(*ListParrotsResponse)(nil),               // 45: memos.api.v1.ListParrotsResponse
+	(*ParrotInfo)(nil),                        // 46: memos.api.v1.ParrotInfo
+	(*DetectDuplicatesRequest)(nil),           // 47: memos.api.v1.DetectDuplicatesRequest
+	(*DetectDuplicatesResponse)(nil),          // 48: memos.api.v1.DetectDuplicatesResponse
+	(*SimilarMemo)(nil),                       // 49: memos.api.v1.SimilarMemo
+	(*SimilarityBreakdown)(nil),               // 50: memos.api.v1.SimilarityBreakdown
+	(*MergeMemosRequest)(nil),                 // 51: memos.api.v1.MergeMemosRequest
+	(*MergeMemosResponse)(nil),                // 52: memos.api.v1.MergeMemosResponse
+	(*LinkMemosRequest)(nil),                  // 53: memos.api.v1.LinkMemosRequest
+	(*LinkMemosResponse)(nil),                 // 54: memos.api.v1.LinkMemosResponse
+	(*GetKnowledgeGraphRequest)(nil),          // 55: memos.api.v1.GetKnowledgeGraphRequest
+	(*GetKnowledgeGraphResponse)(nil),         // 56: memos.api.v1.GetKnowledgeGraphResponse
+	(*GraphNode)(nil),                         // 57: memos.api.v1.GraphNode
+	(*GraphEdge)(nil),                         // 58: memos.api.v1.GraphEdge
+	(*GraphStats)(nil),                        // 59: memos.api.v1.GraphStats
+	(*GetDueReviewsRequest)(nil),              // 60: memos.api.v1.GetDueReviewsRequest
+	(*GetDueReviewsResponse)(nil),             // 61: memos.api.v1.GetDueReviewsResponse
+	(*ReviewItem)(nil),                        // 62: memos.api.v1.ReviewItem
+	(*RecordReviewRequest)(nil),               // 63: memos.api.v1.RecordReviewRequest
+	(*RecordRouterFeedbackRequest)(nil),       // 64: memos.api.v1.RecordRouterFeedbackRequest
+	(*GetReviewStatsRequest)(nil),             // 65: memos.api.v1.GetReviewStatsRequest
+	(*GetReviewStatsResponse)(nil),            // 66: memos.api.v1.GetReviewStatsResponse
+	(*EventMetadata)(nil),                     // 67: memos.api.v1.EventMetadata
+	(*BlockSummary)(nil),                      // 68: memos.api.v1.BlockSummary
+	(*SessionStats)(nil),                      // 69: memos.api.v1.SessionStats
+	(*GetSessionStatsRequest)(nil),            // 70: memos.api.v1.GetSessionStatsRequest
+	(*ListSessionStatsRequest)(nil),           // 71: memos.api.v1.ListSessionStatsRequest
+	(*ListSessionStatsResponse)(nil),          // 72: memos.api.v1.ListSessionStatsResponse
+	(*GetCostStatsRequest)(nil),               // 73: memos.api.v1.GetCostStatsRequest
+	(*CostStats)(nil),                         // 74: memos.api.v1.CostStats
+	(*DailyCostData)(nil),                     // 75: memos.api.v1.DailyCostData
+	(*UserCostSettings)(nil),                  // 76: memos.api.v1.UserCostSettings
+	(*SetUserCostSettingsRequest)(nil),        // 77: memos.api.v1.SetUserCostSettingsRequest
+	(*Block)(nil),                             // 78: memos.api.v1.Block
+	(*TokenUsage)(nil),                        // 79: memos.api.v1.TokenUsage
+	(*UserInput)(nil),                         // 80: memos.api.v1.UserInput
+	(*BlockEvent)(nil),                        // 81: memos.api.v1.BlockEvent
+	(*ListBlocksRequest)(nil),                 // 82: memos.api.v1.ListBlocksRequest
+	(*ListBlocksResponse)(nil),                // 83: memos.api.v1.ListBlocksResponse
+	(*GetBlockRequest)(nil),                   // 84: memos.api.v1.GetBlockRequest
+	(*CreateBlockRequest)(nil),                // 85: memos.api.v1.CreateBlockRequest
+	(*UpdateBlockRequest)(nil),                // 86: memos.api.v1.UpdateBlockRequest
+	(*DeleteBlockRequest)(nil),                // 87: memos.api.v1.DeleteBlockRequest
+	(*AppendUserInputRequest)(nil),            // 88: memos.api.v1.AppendUserInputRequest
+	(*AppendEventRequest)(nil),                // 89: memos.api.v1.AppendEventRequest
+	(*ForkBlockRequest)(nil),                  // 90: memos.api.v1.ForkBlockRequest
+	(*ListBlockBranchesRequest)(nil),          // 91: memos.api.v1.ListBlockBranchesRequest
+	(*ListBlockBranchesResponse)(nil),         // 92: memos.api.v1.ListBlockBranchesResponse
+	(*BlockBranch)(nil),                       // 93: memos.api.v1.BlockBranch
+	(*SwitchBranchRequest)(nil),               // 94: memos.api.v1.SwitchBranchRequest
+	(*DeleteBranchRequest)(nil),               // 95: memos.api.v1.DeleteBranchRequest
+	(*GetUsageRequest)(nil),                   // 96: memos.api.v1.GetUsageRequest
+	(*Usage)(nil),                             // 97: memos.api.v1.Usage
+	(*RunSelfTestRequest)(nil),                // 98: memos.api.v1.RunSelfTestRequest
+	(*SelfTestCheck)(nil),                     // 99: memos.api.v1.SelfTestCheck
+	(*SelfTestReport)(nil),                    // 100: memos.api.v1.SelfTestReport
+	(*GetBlockChangesRequest)(nil),            // 101: memos.api.v1.GetBlockChangesRequest
+	(*BlockChange)(nil),                       // 102: memos.api.v1.BlockChange
+	(*GetBlockChangesResponse)(nil),           // 103: memos.api.v1.GetBlockChangesResponse
+	(*GetBlockTranscriptRequest)(nil),         // 104: memos.api.v1.GetBlockTranscriptRequest
+	(*BlockTranscript)(nil),                   // 105: memos.api.v1.BlockTranscript
+	(*TranscriptTool)(nil),                    // 106: memos.api.v1.TranscriptTool
+	(*TranscriptEntry)(nil),                   // 107: memos.api.v1.TranscriptEntry
+	(*TranscriptSource)(nil),                  // 108: memos.api.v1.TranscriptSource
+	(*TranscriptPassage)(nil),                 // 109: memos.api.v1.TranscriptPassage
+	(*ReindexAllRequest)(nil),                 // 110: memos.api.v1.ReindexAllRequest
+	(*ReindexSinceRequest)(nil),               // 111: memos.api.v1.ReindexSinceRequest
+	(*GetReindexProgressRequest)(nil),         // 112: memos.api.v1.GetReindexProgressRequest
+	(*ReindexProgress)(nil),                   // 113: memos.api.v1.ReindexProgress
+	(*MCPServer)(nil),                         // 114: memos.api.v1.MCPServer
+	(*ListMCPServersRequest)(nil),             // 115: memos.api.v1.ListMCPServersRequest
+	(*ListMCPServersResponse)(nil),            // 116: memos.api.v1.ListMCPServersResponse
+	(*CreateMCPServerRequest)(nil),            // 117: memos.api.v1.CreateMCPServerRequest
+	(*UpdateMCPServerRequest)(nil),            // 118: memos.api.v1.UpdateMCPServerRequest
+	(*DeleteMCPServerRequest)(nil),            // 119: memos.api.v1.DeleteMCPServerRequest
+	(*ImportConversationRequest)(nil),         // 120: memos.api.v1.ImportConversationRequest
+	(*ImportConversationResponse)(nil),        // 121: memos.api.v1.ImportConversationResponse
+	(*ImportedConversation)(nil),              // 122: memos.api.v1.ImportedConversation
+	(*ActionAuditRecord)(nil),                 // 123: memos.api.v1.ActionAuditRecord
+	(*ActionAuditFilter)(nil),                 // 124: memos.api.v1.ActionAuditFilter
+	(*ListActionAuditRequest)(nil),            // 125: memos.api.v1.ListActionAuditRequest
+	(*ListActionAuditResponse)(nil),           // 126: memos.api.v1.ListActionAuditResponse
+	(*ExportActionAuditRequest)(nil),          // 127: memos.api.v1.ExportActionAuditRequest
+	(*ExportActionAuditResponse)(nil),         // 128: memos.api.v1.ExportActionAuditResponse
+	(*LLMProvider)(nil),                       // 129: memos.api.v1.LLMProvider
+	(*AgentModel)(nil),                        // 130: memos.api.v1.AgentModel
+	(*ListAgentModelsRequest)(nil),            // 131: memos.api.v1.ListAgentModelsRequest
+	(*ListAgentModelsResponse)(nil),           // 132: memos.api.v1.ListAgentModelsResponse
+	(*UpdateAgentModelRequest)(nil),           // 133: memos.api.v1.UpdateAgentModelRequest
+	(*DeleteAgentModelRequest)(nil),           // 134: memos.api.v1.DeleteAgentModelRequest
+	(*GeekWorkspaceUsage)(nil),                // 135: memos.api.v1.GeekWorkspaceUsage
+	(*GeekWorkspaceEntry)(nil),                // 136: memos.api.v1.GeekWorkspaceEntry
+	(*GetGeekWorkspaceUsageRequest)(nil),      // 137: memos.api.v1.GetGeekWorkspaceUsageRequest
+	(*ListGeekWorkspaceFilesRequest)(nil),     // 138: memos.api.v1.ListGeekWorkspaceFilesRequest
+	(*ListGeekWorkspaceFilesResponse)(nil),    // 139: memos.api.v1.ListGeekWorkspaceFilesResponse
+	(*ReadGeekWorkspaceFileRequest)(nil),      // 140: memos.api.v1.ReadGeekWorkspaceFileRequest
+	(*GeekWorkspaceFileContent)(nil),          // 141: memos.api.v1.GeekWorkspaceFileContent
+	(*DownloadGeekWorkspaceFileRequest)(nil),  // 142: memos.api.v1.DownloadGeekWorkspaceFileRequest
+	(*DownloadGeekWorkspaceFileResponse)(nil), // 143: memos.api.v1.DownloadGeekWorkspaceFileResponse
+	(*DeleteGeekWorkspaceFileRequest)(nil),    // 144: memos.api.v1.DeleteGeekWorkspaceFileRequest
+	nil,                                       // 145: memos.api.v1.CreateMCPServerRequest.EnvEntry
+	nil,                                       // 146: memos.api.v1.CreateMCPServerRequest.HeadersEntry
+	nil,                                       // 147: memos.api.v1.UpdateMCPServerRequest.EnvEntry
+	nil,                                       // 148: memos.api.v1.UpdateMCPServerRequest.HeadersEntry
+	(*structpb.Struct)(nil),                   // 149: google.protobuf.Struct
+	(*emptypb.Empty)(nil),                     // 150: google.protobuf.Empty
 }
 var file_api_v1_ai_service_proto_depIdxs = []int32{
-	8,  // 0: memos.api.v1.SemanticSearchResponse.results:type_name -> memos.api.v1.SearchResult
-	0,  // 1: memos.api.v1.ChatRequest.schedule_query_mode:type_name -> memos.api.v1.ScheduleQueryMode
-	1,  // 2: memos.api.v1.ChatRequest.agent_type:type_name -> memos.api.v1.AgentType
-	1,  // 3: memos.api.v1.AIConversation.parrot_id:type_name -> memos.api.v1.AgentType
-	72, // 4: memos.api.v1.AIConversation.blocks:type_name -> memos.api.v1.Block
-	16, // 5: memos.api.v1.ListAIConversationsResponse.conversations:type_name -> memos.api.v1.AIConversation
-	1,  // 6: memos.api.v1.CreateAIConversationRequest.parrot_id:type_name -> memos.api.v1.AgentType
-	30, // 7: memos.api.v1.ChatResponse.schedule_creation_intent:type_name -> memos.api.v1.ScheduleCreationIntent
-	31, // 8: memos.api.v1.ChatResponse.schedule_query_result:type_name -> memos.api.v1.ScheduleQueryResult
-	61, // 9: memos.api.v1.ChatResponse.event_meta:type_name -> memos.api.v1.EventMetadata
-	62, // 10: memos.api.v1.ChatResponse.block_summary:type_name -> memos.api.v1.BlockSummary
-	32, // 11: memos.api.v1.ScheduleQueryResult.schedules:type_name -> memos.api.v1.ScheduleSummary
-	8,  // 12: memos.api.v1.GetRelatedMemosResponse.memos:type_name -> memos.api.v1.SearchResult
-	1,  // 13: memos.api.v1.GetParrotSelfCognitionRequest.agent_type:type_name -> memos.api.v1.AgentType
-	35, // 14: memos.api.v1.GetParrotSelfCognitionResponse.self_cognition:type_name -> memos.api.v1.ParrotSelfCognition
-	40, // 15: memos.api.v1.ListParrotsResponse.parrots:type_name -> memos.api.v1.ParrotInfo
-	1,  // 16: memos.api.v1.ParrotInfo.agent_type:type_name -> memos.api.v1.AgentType
-	35, // 17: memos.api.v1.ParrotInfo.self_cognition:type_name -> memos.api.v1.ParrotSelfCognition
-	43, // 18: memos.api.v1.DetectDuplicatesResponse.duplicates:type_name -> memos.api.v1.SimilarMemo
-	43, // 19: memos.api.v1.DetectDuplicatesResponse.related:type_name -> memos.api.v1.SimilarMemo
-	44, // 20: memos.api.v1.SimilarMemo.breakdown:type_name -> memos.api.v1.SimilarityBreakdown
-	51, // 21: memos.api.v1.GetKnowledgeGraphResponse.nodes:type_name -> memos.api.v1.GraphNode
-	52, // 22: memos.api.v1.GetKnowledgeGraphResponse.edges:type_name -> memos.api.v1.GraphEdge
-	53, // 23: memos.api.v1.GetKnowledgeGraphResponse.stats:type_name -> memos.api.v1.GraphStats
-	56, // 24: memos.api.v1.GetDueReviewsResponse.items:type_name -> memos.api.v1.ReviewItem
-	2,  // 25: memos.api.v1.RecordReviewRequest.quality:type_name -> memos.api.v1.ReviewQuality
-	63, // 26: memos.api.v1.ListSessionStatsResponse.sessions:type_name -> memos.api.v1.SessionStats
-	63, // 27: memos.api.v1.CostStats.most_expensive_session:type_name -> memos.api.v1.SessionStats
-	69, // 28: memos.api.v1.CostStats.daily_breakdown:type_name -> memos.api.v1.DailyCostData
-	3,  // 29: memos.api.v1.Block.block_type:type_name -> memos.api.v1.BlockType
-	4,  // 30: memos.api.v1.Block.mode:type_name -> memos.api.v1.BlockMode
-	74, // 31: memos.api.v1.Block.user_inputs:type_name -> memos.api.v1.UserInput
-	75, // 32: memos.api.v1.Block.event_stream:type_name -> memos.api.v1.BlockEvent
-	63, // 33: memos.api.v1.Block.session_stats:type_name -> memos.api.v1.SessionStats
-	5,  // 34: memos.api.v1.Block.status:type_name -> memos.api.v1.BlockStatus
-	73, // 35: memos.api.v1.Block.token_usage:type_name -> memos.api.v1.TokenUsage
-	5,  // 36: memos.api.v1.ListBlocksRequest.status:type_name -> memos.api.v1.BlockStatus
-	4,  // 37: memos.api.v1.ListBlocksRequest.mode:type_name -> memos.api.v1.BlockMode
-	72, // 38: memos.api.v1.ListBlocksResponse.blocks:type_name -> memos.api.v1.Block
-	3,  // 39: memos.api.v1.CreateBlockRequest.block_type:type_name -> memos.api.v1.BlockType
-	4,  // 40: memos.api.v1.CreateBlockRequest.mode:type_name -> memos.api.v1.BlockMode
-	74, // 41: memos.api.v1.CreateBlockRequest.user_inputs:type_name -> memos.api.v1.UserInput
-	75, // 42: memos.api.v1.UpdateBlockRequest.event_stream:type_name -> memos.api.v1.BlockEvent
-	63, // 43: memos.api.v1.UpdateBlockRequest.session_stats:type_name -> memos.api.v1.SessionStats
-	5,  // 44: memos.api.v1.UpdateBlockRequest.status:type_name -> memos.api.v1.BlockStatus
-	74, // 45: memos.api.v1.AppendUserInputRequest.input:type_name -> memos.api.v1.UserInput
-	75, // 46: memos.api.v1.AppendEventRequest.event:type_name -> memos.api.v1.BlockEvent
-	74, // 47: memos.api.v1.ForkBlockRequest.replace_user_inputs:type_name -> memos.api.v1.UserInput
-	87, // 48: memos.api.v1.ListBlockBranchesResponse.branches:type_name -> memos.api.v1.BlockBranch
-	72, // 49: memos.api.v1.BlockBranch.block:type_name -> memos.api.v1.Block
-	87, // 50: memos.api.v1.BlockBranch.children:type_name -> memos.api.v1.BlockBranch
-	6,  // 51: memos.api.v1.AIService.SemanticSearch:input_type -> memos.api.v1.SemanticSearchRequest
-	9,  // 52: memos.api.v1.AIService.SuggestTags:input_type -> memos.api.v1.SuggestTagsRequest
-	11, // 53: memos.api.v1.AIService.Format:input_type -> memos.api.v1.FormatRequest
-	13, // 54: memos.api.v1.AIService.Summary:input_type -> memos.api.v1.SummaryRequest
-	15, // 55: memos.api.v1.AIService.Chat:input_type -> memos.api.v1.ChatRequest
-	33, // 56: memos.api.v1.AIService.GetRelatedMemos:input_type -> memos.api.v1.GetRelatedMemosRequest
-	36, // 57: memos.api.v1.AIService.GetParrotSelfCognition:input_type -> memos.api.v1.GetParrotSelfCognitionRequest
-	38, // 58: memos.api.v1.AIService.ListParrots:input_type -> memos.api.v1.ListParrotsRequest
-	41, // 59: memos.api.v1.AIService.DetectDuplicates:input_type -> memos.api.v1.DetectDuplicatesRequest
-	45, // 60: memos.api.v1.AIService.MergeMemos:input_type -> memos.api.v1.MergeMemosRequest
-	47, // 61: memos.api.v1.AIService.LinkMemos:input_type -> memos.api.v1.LinkMemosRequest
-	49, // 62: memos.api.v1.AIService.GetKnowledgeGraph:input_type -> memos.api.v1.GetKnowledgeGraphRequest
-	54, // 63: memos.api.v1.AIService.GetDueReviews:input_type -> memos.api.v1.GetDueReviewsRequest
-	57, // 64: memos.api.v1.AIService.RecordReview:input_type -> memos.api.v1.RecordReviewRequest
-	58, // 65: memos.api.v1.AIService.RecordRouterFeedback:input_type -> memos.api.v1.RecordRouterFeedbackRequest
-	59, // 66: memos.api.v1.AIService.GetReviewStats:input_type -> memos.api.v1.GetReviewStatsRequest
-	17, // 67: memos.api.v1.AIService.ListAIConversations:input_type -> memos.api.v1.ListAIConversationsRequest
-	19, // 68: memos.api.v1.AIService.GetAIConversation:input_type -> memos.api.v1.GetAIConversationRequest
-	20, // 69: memos.api.v1.AIService.CreateAIConversation:input_type -> memos.api.v1.CreateAIConversationRequest
-	21, // 70: memos.api.v1.AIService.UpdateAIConversation:input_type -> memos.api.v1.UpdateAIConversationRequest
-	22, // 71: memos.api.v1.AIService.GenerateConversationTitle:input_type -> memos.api.v1.GenerateConversationTitleRequest
-	24, // 72: memos.api.v1.AIService.DeleteAIConversation:input_type -> memos.api.v1.DeleteAIConversationRequest
-	25, // 73: memos.api.v1.AIService.AddContextSeparator:input_type -> memos.api.v1.AddContextSeparatorRequest
-	26, // 74: memos.api.v1.AIService.ClearConversationMessages:input_type -> memos.api.v1.ClearConversationMessagesRequest
-	27, // 75: memos.api.v1.AIService.StopChat:input_type -> memos.api.v1.StopChatRequest
-	64, // 76: memos.api.v1.AIService.GetSessionStats:input_type -> memos.api.v1.GetSessionStatsRequest
-	65, // 77: memos.api.v1.AIService.ListSessionStats:input_type -> memos.api.v1.ListSessionStatsRequest
-	67, // 78: memos.api.v1.AIService.GetCostStats:input_type -> memos.api.v1.GetCostStatsRequest
-	90, // 79: memos.api.v1.AIService.GetUserCostSettings:input_type -> google.protobuf.Empty
-	71, // 80: memos.api.v1.AIService.SetUserCostSettings:input_type -> memos.api.v1.SetUserCostSettingsRequest
-	76, // 81: memos.api.v1.AIService.ListBlocks:input_type -> memos.api.v1.ListBlocksRequest
-	78, // 82: memos.api.v1.AIService.GetBlock:input_type -> memos.api.v1.GetBlockRequest
-	79, // 83: memos.api.v1.AIService.CreateBlock:input_type -> memos.api.v1.CreateBlockRequest
-	80, // 84: memos.api.v1.AIService.UpdateBlock:input_type -> memos.api.v1.UpdateBlockRequest
-	81, // 85: memos.api.v1.AIService.DeleteBlock:input_type -> memos.api.v1.DeleteBlockRequest
-	82, // 86: memos.api.v1.AIService.AppendUserInput:input_type -> memos.api.v1.AppendUserInputRequest
-	83, // 87: memos.api.v1.AIService.AppendEvent:input_type -> memos.api.v1.AppendEventRequest
-	84, // 88: memos.api.v1.AIService.ForkBlock:input_type -> memos.api.v1.ForkBlockRequest
-	85, // 89: memos.api.v1.AIService.ListBlockBranches:input_type -> memos.api.v1.ListBlockBranchesRequest
-	88, // 90: memos.api.v1.AIService.SwitchBranch:input_type -> memos.api.v1.SwitchBranchRequest
-	89, // 91: memos.api.v1.AIService.DeleteBranch:input_type -> memos.api.v1.DeleteBranchRequest
-	7,  // 92: memos.api.v1.AIService.SemanticSearch:output_type -> memos.api.v1.SemanticSearchResponse
-	10, // 93: memos.api.v1.AIService.SuggestTags:output_type -> memos.api.v1.SuggestTagsResponse
-	12, // 94: memos.api.v1.AIService.Format:output_type -> memos.api.v1.FormatResponse
-	14, // 95: memos.api.v1.AIService.Summary:output_type -> memos.api.v1.SummaryResponse
-	29, // 96: memos.api.v1.AIService.Chat:output_type -> memos.api.v1.ChatResponse
-	34, // 97: memos.api.v1.AIService.GetRelatedMemos:output_type -> memos.api.v1.GetRelatedMemosResponse
-	37, // 98: memos.api.v1.AIService.GetParrotSelfCognition:output_type -> memos.api.v1.GetParrotSelfCognitionResponse
-	39, // 99: memos.api.v1.AIService.ListParrots:output_type -> memos.api.v1.ListParrotsResponse
-	42, // 100: memos.api.v1.AIService.DetectDuplicates:output_type -> memos.api.v1.DetectDuplicatesResponse
-	46, // 101: memos.api.v1.AIService.MergeMemos:output_type -> memos.api.v1.MergeMemosResponse
-	48, // 102: memos.api.v1.AIService.LinkMemos:output_type -> memos.api.v1.LinkMemosResponse
-	50, // 103: memos.api.v1.AIService.GetKnowledgeGraph:output_type -> memos.api.v1.GetKnowledgeGraphResponse
-	55, // 104: memos.api.v1.AIService.GetDueReviews:output_type -> memos.api.v1.GetDueReviewsResponse
-	90, // 105: memos.api.v1.AIService.RecordReview:output_type -> google.protobuf.Empty
-	90, // 106: memos.api.v1.AIService.RecordRouterFeedback:output_type -> google.protobuf.Empty
-	60, // 107: memos.api.v1.AIService.GetReviewStats:output_type -> memos.api.v1.GetReviewStatsResponse
-	18, // 108: memos.api.v1.AIService.ListAIConversations:output_type -> memos.api.v1.ListAIConversationsResponse
-	16, // 109: memos.api.v1.AIService.GetAIConversation:output_type -> memos.api.v1.AIConversation
-	16, // 110: memos.api.v1.AIService.CreateAIConversation:output_type -> memos.api.v1.AIConversation
-	16, // 111: memos.api.v1.AIService.UpdateAIConversation:output_type -> memos.api.v1.AIConversation
-	23, // 112: memos.api.v1.AIService.GenerateConversationTitle:output_type -> memos.api.v1.GenerateConversationTitleResponse
-	90, // 113: memos.api.v1.AIService.DeleteAIConversation:output_type -> google.protobuf.Empty
-	90, // 114: memos.api.v1.AIService.AddContextSeparator:output_type -> google.protobuf.Empty
-	90, // 115: memos.api.v1.AIService.ClearConversationMessages:output_type -> google.protobuf.Empty
-	90, // 116: memos.api.v1.AIService.StopChat:output_type -> google.protobuf.Empty
-	63, // 117: memos.api.v1.AIService.GetSessionStats:output_type -> memos.api.v1.SessionStats
-	66, // 118: memos.api.v1.AIService.ListSessionStats:output_type -> memos.api.v1.ListSessionStatsResponse
-	68, // 119: memos.api.v1.AIService.GetCostStats:output_type -> memos.api.v1.CostStats
-	70, // 120: memos.api.v1.AIService.GetUserCostSettings:output_type -> memos.api.v1.UserCostSettings
-	70, // 121: memos.api.v1.AIService.SetUserCostSettings:output_type -> memos.api.v1.UserCostSettings
-	77, // 122: memos.api.v1.AIService.ListBlocks:output_type -> memos.api.v1.ListBlocksResponse
-	72, // 123: memos.api.v1.AIService.GetBlock:output_type -> memos.api.v1.Block
-	72, // 124: memos.api.v1.AIService.CreateBlock:output_type -> memos.api.v1.Block
-	72, // 125: memos.api.v1.AIService.UpdateBlock:output_type -> memos.api.v1.Block
-	90, // 126: memos.api.v1.AIService.DeleteBlock:output_type -> google.protobuf.Empty
-	90, // 127: memos.api.v1.AIService.AppendUserInput:output_type -> google.protobuf.Empty
-	90, // 128: memos.api.v1.AIService.AppendEvent:output_type -> google.protobuf.Empty
-	72, // 129: memos.api.v1.AIService.ForkBlock:output_type -> memos.api.v1.Block
-	86, // 130: memos.api.v1.AIService.ListBlockBranches:output_type -> memos.api.v1.ListBlockBranchesResponse
-	90, // 131: memos.api.v1.AIService.SwitchBranch:output_type -> google.protobuf.Empty
-	90, // 132: memos.api.v1.AIService.DeleteBranch:output_type -> google.protobuf.Empty
-	92, // [92:133] is the sub-list for method output_type
-	51, // [51:92] is the sub-list for method input_type
-	51, // [51:51] is the sub-list for extension type_name
-	51, // [51:51] is the sub-list for extension extendee
-	0,  // [0:51] is the sub-list for field type_name
+	11,  // 0: memos.api.v1.SemanticSearchResponse.results:type_name -> memos.api.v1.SearchResult
+	0,   // 1: memos.api.v1.ChatRequest.schedule_query_mode:type_name -> memos.api.v1.ScheduleQueryMode
+	1,   // 2: memos.api.v1.ChatRequest.agent_type:type_name -> memos.api.v1.AgentType
+	1,   // 3: memos.api.v1.AIConversation.parrot_id:type_name -> memos.api.v1.AgentType
+	78,  // 4: memos.api.v1.AIConversation.blocks:type_name -> memos.api.v1.Block
+	19,  // 5: memos.api.v1.ListAIConversationsResponse.conversations:type_name -> memos.api.v1.AIConversation
+	1,   // 6: memos.api.v1.CreateAIConversationRequest.parrot_id:type_name -> memos.api.v1.AgentType
+	149, // 7: memos.api.v1.SubmitFormRequest.payload:type_name -> google.protobuf.Struct
+	36,  // 8: memos.api.v1.ChatResponse.schedule_creation_intent:type_name -> memos.api.v1.ScheduleCreationIntent
+	37,  // 9: memos.api.v1.ChatResponse.schedule_query_result:type_name -> memos.api.v1.ScheduleQueryResult
+	67,  // 10: memos.api.v1.ChatResponse.event_meta:type_name -> memos.api.v1.EventMetadata
+	68,  // 11: memos.api.v1.ChatResponse.block_summary:type_name -> memos.api.v1.BlockSummary
+	38,  // 12: memos.api.v1.ScheduleQueryResult.schedules:type_name -> memos.api.v1.ScheduleSummary
+	11,  // 13: memos.api.v1.GetRelatedMemosResponse.memos:type_name -> memos.api.v1.SearchResult
+	1,   // 14: memos.api.v1.GetParrotSelfCognitionRequest.agent_type:type_name -> memos.api.v1.AgentType
+	41,  // 15: memos.api.v1.GetParrotSelfCognitionResponse.self_cognition:type_name -> memos.api.v1.ParrotSelfCognition
+	46,  // 16: memos.api.v1.ListParrotsResponse.parrots:type_name -> memos.api.v1.ParrotInfo
+	1,   // 17: memos.api.v1.ParrotInfo.agent_type:type_name -> memos.api.v1.AgentType
+	41,  // 18: memos.api.v1.ParrotInfo.self_cognition:type_name -> memos.api.v1.ParrotSelfCognition
+	49,  // 19: memos.api.v1.DetectDuplicatesResponse.duplicates:type_name -> memos.api.v1.SimilarMemo
+	49,  // 20: memos.api.v1.DetectDuplicatesResponse.related:type_name -> memos.api.v1.SimilarMemo
+	50,  // 21: memos.api.v1.SimilarMemo.breakdown:type_name -> memos.api.v1.SimilarityBreakdown
+	57,  // 22: memos.api.v1.GetKnowledgeGraphResponse.nodes:type_name -> memos.api.v1.GraphNode
+	58,  // 23: memos.api.v1.GetKnowledgeGraphResponse.edges:type_name -> memos.api.v1.GraphEdge
+	59,  // 24: memos.api.v1.GetKnowledgeGraphResponse.stats:type_name -> memos.api.v1.GraphStats
+	62,  // 25: memos.api.v1.GetDueReviewsResponse.items:type_name -> memos.api.v1.ReviewItem
+	2,   // 26: memos.api.v1.RecordReviewRequest.quality:type_name -> memos.api.v1.ReviewQuality
+	69,  // 27: memos.api.v1.ListSessionStatsResponse.sessions:type_name -> memos.api.v1.SessionStats
+	69,  // 28: memos.api.v1.CostStats.most_expensive_session:type_name -> memos.api.v1.SessionStats
+	75,  // 29: memos.api.v1.CostStats.daily_breakdown:type_name -> memos.api.v1.DailyCostData
+	3,   // 30: memos.api.v1.Block.block_type:type_name -> memos.api.v1.BlockType
+	4,   // 31: memos.api.v1.Block.mode:type_name -> memos.api.v1.BlockMode
+	80,  // 32: memos.api.v1.Block.user_inputs:type_name -> memos.api.v1.UserInput
+	81,  // 33: memos.api.v1.Block.event_stream:type_name -> memos.api.v1.BlockEvent
+	69,  // 34: memos.api.v1.Block.session_stats:type_name -> memos.api.v1.SessionStats
+	5,   // 35: memos.api.v1.Block.status:type_name -> memos.api.v1.BlockStatus
+	79,  // 36: memos.api.v1.Block.token_usage:type_name -> memos.api.v1.TokenUsage
+	5,   // 37: memos.api.v1.ListBlocksRequest.status:type_name -> memos.api.v1.BlockStatus
+	4,   // 38: memos.api.v1.ListBlocksRequest.mode:type_name -> memos.api.v1.BlockMode
+	78,  // 39: memos.api.v1.ListBlocksResponse.blocks:type_name -> memos.api.v1.Block
+	3,   // 40: memos.api.v1.CreateBlockRequest.block_type:type_name -> memos.api.v1.BlockType
+	4,   // 41: memos.api.v1.CreateBlockRequest.mode:type_name -> memos.api.v1.BlockMode
+	80,  // 42: memos.api.v1.CreateBlockRequest.user_inputs:type_name -> memos.api.v1.UserInput
+	81,  // 43: memos.api.v1.UpdateBlockRequest.event_stream:type_name -> memos.api.v1.BlockEvent
+	69,  // 44: memos.api.v1.UpdateBlockRequest.session_stats:type_name -> memos.api.v1.SessionStats
+	5,   // 45: memos.api.v1.UpdateBlockRequest.status:type_name -> memos.api.v1.BlockStatus
+	80,  // 46: memos.api.v1.AppendUserInputRequest.input:type_name -> memos.api.v1.UserInput
+	81,  // 47: memos.api.v1.AppendEventRequest.event:type_name -> memos.api.v1.BlockEvent
+	80,  // 48: memos.api.v1.ForkBlockRequest.replace_user_inputs:type_name -> memos.api.v1.UserInput
+	93,  // 49: memos.api.v1.ListBlockBranchesResponse.branches:type_name -> memos.api.v1.BlockBranch
+	78,  // 50: memos.api.v1.BlockBranch.block:type_name -> memos.api.v1.Block
+	93,  // 51: memos.api.v1.BlockBranch.children:type_name -> memos.api.v1.BlockBranch
+	99,  // 52: memos.api.v1.SelfTestReport.checks:type_name -> memos.api.v1.SelfTestCheck
+	78,  // 53: memos.api.v1.BlockChange.block:type_name -> memos.api.v1.Block
+	102, // 54: memos.api.v1.GetBlockChangesResponse.changes:type_name -> memos.api.v1.BlockChange
+	6,   // 55: memos.api.v1.GetBlockTranscriptRequest.detail:type_name -> memos.api.v1.TranscriptDetail
+	6,   // 56: memos.api.v1.BlockTranscript.detail:type_name -> memos.api.v1.TranscriptDetail
+	106, // 57: memos.api.v1.BlockTranscript.tools:type_name -> memos.api.v1.TranscriptTool
+	107, // 58: memos.api.v1.BlockTranscript.entries:type_name -> memos.api.v1.TranscriptEntry
+	108, // 59: memos.api.v1.BlockTranscript.source:type_name -> memos.api.v1.TranscriptSource
+	106, // 60: memos.api.v1.TranscriptEntry.tool:type_name -> memos.api.v1.TranscriptTool
+	109, // 61: memos.api.v1.TranscriptSource.passages:type_name -> memos.api.v1.TranscriptPassage
+	114, // 62: memos.api.v1.ListMCPServersResponse.servers:type_name -> memos.api.v1.MCPServer
+	145, // 63: memos.api.v1.CreateMCPServerRequest.env:type_name -> memos.api.v1.CreateMCPServerRequest.EnvEntry
+	146, // 64: memos.api.v1.CreateMCPServerRequest.headers:type_name -> memos.api.v1.CreateMCPServerRequest.HeadersEntry
+	147, // 65: memos.api.v1.UpdateMCPServerRequest.env:type_name -> memos.api.v1.UpdateMCPServerRequest.EnvEntry
+	148, // 66: memos.api.v1.UpdateMCPServerRequest.headers:type_name -> memos.api.v1.UpdateMCPServerRequest.HeadersEntry
+	7,   // 67: memos.api.v1.ImportConversationRequest.format:type_name -> memos.api.v1.ConversationImportFormat
+	122, // 68: memos.api.v1.ImportConversationResponse.conversations:type_name -> memos.api.v1.ImportedConversation
+	124, // 69: memos.api.v1.ListActionAuditRequest.filter:type_name -> memos.api.v1.ActionAuditFilter
+	123, // 70: memos.api.v1.ListActionAuditResponse.records:type_name -> memos.api.v1.ActionAuditRecord
+	124, // 71: memos.api.v1.ExportActionAuditRequest.filter:type_name -> memos.api.v1.ActionAuditFilter
+	8,   // 72: memos.api.v1.ExportActionAuditRequest.format:type_name -> memos.api.v1.ActionAuditExportFormat
+	129, // 73: memos.api.v1.ListAgentModelsResponse.providers:type_name -> memos.api.v1.LLMProvider
+	130, // 74: memos.api.v1.ListAgentModelsResponse.agents:type_name -> memos.api.v1.AgentModel
+	136, // 75: memos.api.v1.ListGeekWorkspaceFilesResponse.entries:type_name -> memos.api.v1.GeekWorkspaceEntry
+	136, // 76: memos.api.v1.GeekWorkspaceFileContent.entry:type_name -> memos.api.v1.GeekWorkspaceEntry
+	9,   // 77: memos.api.v1.AIService.SemanticSearch:input_type -> memos.api.v1.SemanticSearchRequest
+	12,  // 78: memos.api.v1.AIService.SuggestTags:input_type -> memos.api.v1.SuggestTagsRequest
+	14,  // 79: memos.api.v1.AIService.Format:input_type -> memos.api.v1.FormatRequest
+	16,  // 80: memos.api.v1.AIService.Summary:input_type -> memos.api.v1.SummaryRequest
+	18,  // 81: memos.api.v1.AIService.Chat:input_type -> memos.api.v1.ChatRequest
+	39,  // 82: memos.api.v1.AIService.GetRelatedMemos:input_type -> memos.api.v1.GetRelatedMemosRequest
+	42,  // 83: memos.api.v1.AIService.GetParrotSelfCognition:input_type -> memos.api.v1.GetParrotSelfCognitionRequest
+	44,  // 84: memos.api.v1.AIService.ListParrots:input_type -> memos.api.v1.ListParrotsRequest
+	47,  // 85: memos.api.v1.AIService.DetectDuplicates:input_type -> memos.api.v1.DetectDuplicatesRequest
+	51,  // 86: memos.api.v1.AIService.MergeMemos:input_type -> memos.api.v1.MergeMemosRequest
+	53,  // 87: memos.api.v1.AIService.LinkMemos:input_type -> memos.api.v1.LinkMemosRequest
+	55,  // 88: memos.api.v1.AIService.GetKnowledgeGraph:input_type -> memos.api.v1.GetKnowledgeGraphRequest
+	60,  // 89: memos.api.v1.AIService.GetDueReviews:input_type -> memos.api.v1.GetDueReviewsRequest
+	63,  // 90: memos.api.v1.AIService.RecordReview:input_type -> memos.api.v1.RecordReviewRequest
+	64,  // 91: memos.api.v1.AIService.RecordRouterFeedback:input_type -> memos.api.v1.RecordRouterFeedbackRequest
+	65,  // 92: memos.api.v1.AIService.GetReviewStats:input_type -> memos.api.v1.GetReviewStatsRequest
+	20,  // 93: memos.api.v1.AIService.ListAIConversations:input_type -> memos.api.v1.ListAIConversationsRequest
+	22,  // 94: memos.api.v1.AIService.GetAIConversation:input_type -> memos.api.v1.GetAIConversationRequest
+	23,  // 95: memos.api.v1.AIService.CreateAIConversation:input_type -> memos.api.v1.CreateAIConversationRequest
+	24,  // 96: memos.api.v1.AIService.UpdateAIConversation:input_type -> memos.api.v1.UpdateAIConversationRequest
+	25,  // 97: memos.api.v1.AIService.GenerateConversationTitle:input_type -> memos.api.v1.GenerateConversationTitleRequest
+	120, // 98: memos.api.v1.AIService.ImportConversation:input_type -> memos.api.v1.ImportConversationRequest
+	27,  // 99: memos.api.v1.AIService.DeleteAIConversation:input_type -> memos.api.v1.DeleteAIConversationRequest
+	28,  // 100: memos.api.v1.AIService.AddContextSeparator:input_type -> memos.api.v1.AddContextSeparatorRequest
+	29,  // 101: memos.api.v1.AIService.ClearConversationMessages:input_type -> memos.api.v1.ClearConversationMessagesRequest
+	30,  // 102: memos.api.v1.AIService.CreateChatStreamTicket:input_type -> memos.api.v1.CreateChatStreamTicketRequest
+	32,  // 103: memos.api.v1.AIService.StopChat:input_type -> memos.api.v1.StopChatRequest
+	33,  // 104: memos.api.v1.AIService.SubmitForm:input_type -> memos.api.v1.SubmitFormRequest
+	70,  // 105: memos.api.v1.AIService.GetSessionStats:input_type -> memos.api.v1.GetSessionStatsRequest
+	71,  // 106: memos.api.v1.AIService.ListSessionStats:input_type -> memos.api.v1.ListSessionStatsRequest
+	73,  // 107: memos.api.v1.AIService.GetCostStats:input_type -> memos.api.v1.GetCostStatsRequest
+	150, // 108: memos.api.v1.AIService.GetUserCostSettings:input_type -> google.protobuf.Empty
+	77,  // 109: memos.api.v1.AIService.SetUserCostSettings:input_type -> memos.api.v1.SetUserCostSettingsRequest
+	82,  // 110: memos.api.v1.AIService.ListBlocks:input_type -> memos.api.v1.ListBlocksRequest
+	101, // 111: memos.api.v1.AIService.GetBlockChanges:input_type -> memos.api.v1.GetBlockChangesRequest
+	84,  // 112: memos.api.v1.AIService.GetBlock:input_type -> memos.api.v1.GetBlockRequest
+	104, // 113: memos.api.v1.AIService.GetBlockTranscript:input_type -> memos.api.v1.GetBlockTranscriptRequest
+	85,  // 114: memos.api.v1.AIService.CreateBlock:input_type -> memos.api.v1.CreateBlockRequest
+	86,  // 115: memos.api.v1.AIService.UpdateBlock:input_type -> memos.api.v1.UpdateBlockRequest
+	87,  // 116: memos.api.v1.AIService.DeleteBlock:input_type -> memos.api.v1.DeleteBlockRequest
+	88,  // 117: memos.api.v1.AIService.AppendUserInput:input_type -> memos.api.v1.AppendUserInputRequest
+	89,  // 118: memos.api.v1.AIService.AppendEvent:input_type -> memos.api.v1.AppendEventRequest
+	90,  // 119: memos.api.v1.AIService.ForkBlock:input_type -> memos.api.v1.ForkBlockRequest
+	91,  // 120: memos.api.v1.AIService.ListBlockBranches:input_type -> memos.api.v1.ListBlockBranchesRequest
+	94,  // 121: memos.api.v1.AIService.SwitchBranch:input_type -> memos.api.v1.SwitchBranchRequest
+	95,  // 122: memos.api.v1.AIService.DeleteBranch:input_type -> memos.api.v1.DeleteBranchRequest
+	96,  // 123: memos.api.v1.AIService.GetUsage:input_type -> memos.api.v1.GetUsageRequest
+	98,  // 124: memos.api.v1.AIService.RunSelfTest:input_type -> memos.api.v1.RunSelfTestRequest
+	110, // 125: memos.api.v1.AIService.ReindexAll:input_type -> memos.api.v1.ReindexAllRequest
+	111, // 126: memos.api.v1.AIService.ReindexSince:input_type -> memos.api.v1.ReindexSinceRequest
+	112, // 127: memos.api.v1.AIService.GetReindexProgress:input_type -> memos.api.v1.GetReindexProgressRequest
+	115, // 128: memos.api.v1.AIService.ListMCPServers:input_type -> memos.api.v1.ListMCPServersRequest
+	117, // 129: memos.api.v1.AIService.CreateMCPServer:input_type -> memos.api.v1.CreateMCPServerRequest
+	118, // 130: memos.api.v1.AIService.UpdateMCPServer:input_type -> memos.api.v1.UpdateMCPServerRequest
+	119, // 131: memos.api.v1.AIService.DeleteMCPServer:input_type -> memos.api.v1.DeleteMCPServerRequest
+	125, // 132: memos.api.v1.AIService.ListActionAudit:input_type -> memos.api.v1.ListActionAuditRequest
+	127, // 133: memos.api.v1.AIService.ExportActionAudit:input_type -> memos.api.v1.ExportActionAuditRequest
+	131, // 134: memos.api.v1.AIService.ListAgentModels:input_type -> memos.api.v1.ListAgentModelsRequest
+	133, // 135: memos.api.v1.AIService.UpdateAgentModel:input_type -> memos.api.v1.UpdateAgentModelRequest
+	134, // 136: memos.api.v1.AIService.DeleteAgentModel:input_type -> memos.api.v1.DeleteAgentModelRequest
+	137, // 137: memos.api.v1.AIService.GetGeekWorkspaceUsage:input_type -> memos.api.v1.GetGeekWorkspaceUsageRequest
+	138, // 138: memos.api.v1.AIService.ListGeekWorkspaceFiles:input_type -> memos.api.v1.ListGeekWorkspaceFilesRequest
+	140, // 139: memos.api.v1.AIService.ReadGeekWorkspaceFile:input_type -> memos.api.v1.ReadGeekWorkspaceFileRequest
+	142, // 140: memos.api.v1.AIService.DownloadGeekWorkspaceFile:input_type -> memos.api.v1.DownloadGeekWorkspaceFileRequest
+	144, // 141: memos.api.v1.AIService.DeleteGeekWorkspaceFile:input_type -> memos.api.v1.DeleteGeekWorkspaceFileRequest
+	10,  // 142: memos.api.v1.AIService.SemanticSearch:output_type -> memos.api.v1.SemanticSearchResponse
+	13,  // 143: memos.api.v1.AIService.SuggestTags:output_type -> memos.api.v1.SuggestTagsResponse
+	15,  // 144: memos.api.v1.AIService.Format:output_type -> memos.api.v1.FormatResponse
+	17,  // 145: memos.api.v1.AIService.Summary:output_type -> memos.api.v1.SummaryResponse
+	35,  // 146: memos.api.v1.AIService.Chat:output_type -> memos.api.v1.ChatResponse
+	40,  // 147: memos.api.v1.AIService.GetRelatedMemos:output_type -> memos.api.v1.GetRelatedMemosResponse
+	43,  // 148: memos.api.v1.AIService.GetParrotSelfCognition:output_type -> memos.api.v1.GetParrotSelfCognitionResponse
+	45,  // 149: memos.api.v1.AIService.ListParrots:output_type -> memos.api.v1.ListParrotsResponse
+	48,  // 150: memos.api.v1.AIService.DetectDuplicates:output_type -> memos.api.v1.DetectDuplicatesResponse
+	52,  // 151: memos.api.v1.AIService.MergeMemos:output_type -> memos.api.v1.MergeMemosResponse
+	54,  // 152: memos.api.v1.AIService.LinkMemos:output_type -> memos.api.v1.LinkMemosResponse
+	56,  // 153: memos.api.v1.AIService.GetKnowledgeGraph:output_type -> memos.api.v1.GetKnowledgeGraphResponse
+	61,  // 154: memos.api.v1.AIService.GetDueReviews:output_type -> memos.api.v1.GetDueReviewsResponse
+	150, // 155: memos.api.v1.AIService.RecordReview:output_type -> google.protobuf.Empty
+	150, // 156: memos.api.v1.AIService.RecordRouterFeedback:output_type -> google.protobuf.Empty
+	66,  // 157: memos.api.v1.AIService.GetReviewStats:output_type -> memos.api.v1.GetReviewStatsResponse
+	21,  // 158: memos.api.v1.AIService.ListAIConversations:output_type -> memos.api.v1.ListAIConversationsResponse
+	19,  // 159: memos.api.v1.AIService.GetAIConversation:output_type -> memos.api.v1.AIConversation
+	19,  // 160: memos.api.v1.AIService.CreateAIConversation:output_type -> memos.api.v1.AIConversation
+	19,  // 161: memos.api.v1.AIService.UpdateAIConversation:output_type -> memos.api.v1.AIConversation
+	26,  // 162: memos.api.v1.AIService.GenerateConversationTitle:output_type -> memos.api.v1.GenerateConversationTitleResponse
+	121, // 163: memos.api.v1.AIService.ImportConversation:output_type -> memos.api.v1.ImportConversationResponse
+	150, // 164: memos.api.v1.AIService.DeleteAIConversation:output_type -> google.protobuf.Empty
+	150, // 165: memos.api.v1.AIService.AddContextSeparator:output_type -> google.protobuf.Empty
+	150, // 166: memos.api.v1.AIService.ClearConversationMessages:output_type -> google.protobuf.Empty
+	31,  // 167: memos.api.v1.AIService.CreateChatStreamTicket:output_type -> memos.api.v1.ChatStreamTicket
+	150, // 168: memos.api.v1.AIService.StopChat:output_type -> google.protobuf.Empty
+	35,  // 169: memos.api.v1.AIService.SubmitForm:output_type -> memos.api.v1.ChatResponse
+	69,  // 170: memos.api.v1.AIService.GetSessionStats:output_type -> memos.api.v1.SessionStats
+	72,  // 171: memos.api.v1.AIService.ListSessionStats:output_type -> memos.api.v1.ListSessionStatsResponse
+	74,  // 172: memos.api.v1.AIService.GetCostStats:output_type -> memos.api.v1.CostStats
+	76,  // 173: memos.api.v1.AIService.GetUserCostSettings:output_type -> memos.api.v1.UserCostSettings
+	76,  // 174: memos.api.v1.AIService.SetUserCostSettings:output_type -> memos.api.v1.UserCostSettings
+	83,  // 175: memos.api.v1.AIService.ListBlocks:output_type -> memos.api.v1.ListBlocksResponse
+	103, // 176: memos.api.v1.AIService.GetBlockChanges:output_type -> memos.api.v1.GetBlockChangesResponse
+	78,  // 177: memos.api.v1.AIService.GetBlock:output_type -> memos.api.v1.Block
+	105, // 178: memos.api.v1.AIService.GetBlockTranscript:output_type -> memos.api.v1.BlockTranscript
+	78,  // 179: memos.api.v1.AIService.CreateBlock:output_type -> memos.api.v1.Block
+	78,  // 180: memos.api.v1.AIService.UpdateBlock:output_type -> memos.api.v1.Block
+	150, // 181: memos.api.v1.AIService.DeleteBlock:output_type -> google.protobuf.Empty
+	150, // 182: memos.api.v1.AIService.AppendUserInput:output_type -> google.protobuf.Empty
+	150, // 183: memos.api.v1.AIService.AppendEvent:output_type -> google.protobuf.Empty
+	78,  // 184: memos.api.v1.AIService.ForkBlock:output_type -> memos.api.v1.Block
+	92,  // 185: memos.api.v1.AIService.ListBlockBranches:output_type -> memos.api.v1.ListBlockBranchesResponse
+	150, // 186: memos.api.v1.AIService.SwitchBranch:output_type -> google.protobuf.Empty
+	150, // 187: memos.api.v1.AIService.DeleteBranch:output_type -> google.protobuf.Empty
+	97,  // 188: memos.api.v1.AIService.GetUsage:output_type -> memos.api.v1.Usage
+	100, // 189: memos.api.v1.AIService.RunSelfTest:output_type -> memos.api.v1.SelfTestReport
+	113, // 190: memos.api.v1.AIService.ReindexAll:output_type -> memos.api.v1.ReindexProgress
+	113, // 191: memos.api.v1.AIService.ReindexSince:output_type -> memos.api.v1.ReindexProgress
+	113, // 192: memos.api.v1.AIService.GetReindexProgress:output_type -> memos.api.v1.ReindexProgress
+	116, // 193: memos.api.v1.AIService.ListMCPServers:output_type -> memos.api.v1.ListMCPServersResponse
+	114, // 194: memos.api.v1.AIService.CreateMCPServer:output_type -> memos.api.v1.MCPServer
+	114, // 195: memos.api.v1.AIService.UpdateMCPServer:output_type -> memos.api.v1.MCPServer
+	150, // 196: memos.api.v1.AIService.DeleteMCPServer:output_type -> google.protobuf.Empty
+	126, // 197: memos.api.v1.AIService.ListActionAudit:output_type -> memos.api.v1.ListActionAuditResponse
+	128, // 198: memos.api.v1.AIService.ExportActionAudit:output_type -> memos.api.v1.ExportActionAuditResponse
+	132, // 199: memos.api.v1.AIService.ListAgentModels:output_type -> memos.api.v1.ListAgentModelsResponse
+	130, // 200: memos.api.v1.AIService.UpdateAgentModel:output_type -> memos.api.v1.AgentModel
+	150, // 201: memos.api.v1.AIService.DeleteAgentModel:output_type -> google.protobuf.Empty
+	135, // 202: memos.api.v1.AIService.GetGeekWorkspaceUsage:output_type -> memos.api.v1.GeekWorkspaceUsage
+	139, // 203: memos.api.v1.AIService.ListGeekWorkspaceFiles:output_type -> memos.api.v1.ListGeekWorkspaceFilesResponse
+	141, // 204: memos.api.v1.AIService.ReadGeekWorkspaceFile:output_type -> memos.api.v1.GeekWorkspaceFileContent
+	143, // 205: memos.api.v1.AIService.DownloadGeekWorkspaceFile:output_type -> memos.api.v1.DownloadGeekWorkspaceFileResponse
+	150, // 206: memos.api.v1.AIService.DeleteGeekWorkspaceFile:output_type -> google.protobuf.Empty
+	142, // [142:207] is the sub-list for method output_type
+	77,  // [77:142] is the sub-list for method input_type
+	77,  // [77:77] is the sub-list for extension type_name
+	77,  // [77:77] is the sub-list for extension extendee
+	0,   // [0:77] is the sub-list for field type_name
 }
 
 func init() { file_api_v1_ai_service_proto_init() }
@@ -7067,16 +11064,18 @@ func file_api_v1_ai_service_proto_init() {
 		return
 	}
 	file_api_v1_ai_service_proto_msgTypes[15].OneofWrappers = []any{}
-	file_api_v1_ai_service_proto_msgTypes[65].OneofWrappers = []any{}
-	file_api_v1_ai_service_proto_msgTypes[74].OneofWrappers = []any{}
-	file_api_v1_ai_service_proto_msgTypes[78].OneofWrappers = []any{}
+	file_api_v1_ai_service_proto_msgTypes[68].OneofWrappers = []any{}
+	file_api_v1_ai_service_proto_msgTypes[77].OneofWrappers = []any{}
+	file_api_v1_ai_service_proto_msgTypes[81].OneofWrappers = []any{}
+	file_api_v1_ai_service_proto_msgTypes[108].OneofWrappers = []any{}
+	file_api_v1_ai_service_proto_msgTypes[109].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_ai_service_proto_rawDesc), len(file_api_v1_ai_service_proto_rawDesc)),
-			NumEnums:      6,
-			NumMessages:   84,
+			NumEnums:      9,
+			NumMessages:   140,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -41,17 +41,22 @@ const (
 	AIService_CreateAIConversation_FullMethodName      = "/memos.api.v1.AIService/CreateAIConversation"
 	AIService_UpdateAIConversation_FullMethodName      = "/memos.api.v1.AIService/UpdateAIConversation"
 	AIService_GenerateConversationTitle_FullMethodName = "/memos.api.v1.AIService/GenerateConversationTitle"
+	AIService_ImportConversation_FullMethodName        = "/memos.api.v1.AIService/ImportConversation"
 	AIService_DeleteAIConversation_FullMethodName      = "/memos.api.v1.AIService/DeleteAIConversation"
 	AIService_AddContextSeparator_FullMethodName       = "/memos.api.v1.AIService/AddContextSeparator"
 	AIService_ClearConversationMessages_FullMethodName = "/memos.api.v1.AIService/ClearConversationMessages"
+	AIService_CreateChatStreamTicket_FullMethodName    = "/memos.api.v1.AIService/CreateChatStreamTicket"
 	AIService_StopChat_FullMethodName                  = "/memos.api.v1.AIService/StopChat"
+	AIService_SubmitForm_FullMethodName                = "/memos.api.v1.AIService/SubmitForm"
 	AIService_GetSessionStats_FullMethodName           = "/memos.api.v1.AIService/GetSessionStats"
 	AIService_ListSessionStats_FullMethodName          = "/memos.api.v1.AIService/ListSessionStats"
 	AIService_GetCostStats_FullMethodName              = "/memos.api.v1.AIService/GetCostStats"
 	AIService_GetUserCostSettings_FullMethodName       = "/memos.api.v1.AIService/GetUserCostSettings"
 	AIService_SetUserCostSettings_FullMethodName       = "/memos.api.v1.AIService/SetUserCostSettings"
 	AIService_ListBlocks_FullMethodName                = "/memos.api.v1.AIService/ListBlocks"
+	AIService_GetBlockChanges_FullMethodName           = "/memos.api.v1.AIService/GetBlockChanges"
 	AIService_GetBlock_FullMethodName                  = "/memos.api.v1.AIService/GetBlock"
+	AIService_GetBlockTranscript_FullMethodName        = "/memos.api.v1.AIService/GetBlockTranscript"
 	AIService_CreateBlock_FullMethodName               = "/memos.api.v1.AIService/CreateBlock"
 	AIService_UpdateBlock_FullMethodName               = "/memos.api.v1.AIService/UpdateBlock"
 	AIService_DeleteBlock_FullMethodName               = "/memos.api.v1.AIService/DeleteBlock"
@@ -61,6 +66,25 @@ const (
 	AIService_ListBlockBranches_FullMethodName         = "/memos.api.v1.AIService/ListBlockBranches"
 	AIService_SwitchBranch_FullMethodName              = "/memos.api.v1.AIService/SwitchBranch"
 	AIService_DeleteBranch_FullMethodName              = "/memos.api.v1.AIService/DeleteBranch"
+	AIService_GetUsage_FullMethodName                  = "/memos.api.v1.AIService/GetUsage"
+	AIService_RunSelfTest_FullMethodName               = "/memos.api.v1.AIService/RunSelfTest"
+	AIService_ReindexAll_FullMethodName                = "/memos.api.v1.AIService/ReindexAll"
+	AIService_ReindexSince_FullMethodName              = "/memos.api.v1.AIService/ReindexSince"
+	AIService_GetReindexProgress_FullMethodName        = "/memos.api.v1.AIService/GetReindexProgress"
+	AIService_ListMCPServers_FullMethodName            = "/memos.api.v1.AIService/ListMCPServers"
+	AIService_CreateMCPServer_FullMethodName           = "/memos.api.v1.AIService/CreateMCPServer"
+	AIService_UpdateMCPServer_FullMethodName           = "/memos.api.v1.AIService/UpdateMCPServer"
+	AIService_DeleteMCPServer_FullMethodName           = "/memos.api.v1.AIService/DeleteMCPServer"
+	AIService_ListActionAudit_FullMethodName           = "/memos.api.v1.AIService/ListActionAudit"
+	AIService_ExportActionAudit_FullMethodName         = "/memos.api.v1.AIService/ExportActionAudit"
+	AIService_ListAgentModels_FullMethodName           = "/memos.api.v1.AIService/ListAgentModels"
+	AIService_UpdateAgentModel_FullMethodName          = "/memos.api.v1.AIService/UpdateAgentModel"
+	AIService_DeleteAgentModel_FullMethodName          = "/memos.api.v1.AIService/DeleteAgentModel"
+	AIService_GetGeekWorkspaceUsage_FullMethodName     = "/memos.api.v1.AIService/GetGeekWorkspaceUsage"
+	AIService_ListGeekWorkspaceFiles_FullMethodName    = "/memos.api.v1.AIService/ListGeekWorkspaceFiles"
+	AIService_ReadGeekWorkspaceFile_FullMethodName     = "/memos.api.v1.AIService/ReadGeekWorkspaceFile"
+	AIService_DownloadGeekWorkspaceFile_FullMethodName = "/memos.api.v1.AIService/DownloadGeekWorkspaceFile"
+	AIService_DeleteGeekWorkspaceFile_FullMethodName   = "/memos.api.v1.AIService/DeleteGeekWorkspaceFile"
 )
 
 // AIServiceClient is the client API for AIService service.
@@ -112,14 +136,24 @@ type AIServiceClient interface {
 	UpdateAIConversation(ctx context.Context, in *UpdateAIConversationRequest, opts ...grpc.CallOption) (*AIConversation, error)
 	// GenerateConversationTitle generates a meaningful title for a conversation using AI.
 	GenerateConversationTitle(ctx context.Context, in *GenerateConversationTitleRequest, opts ...grpc.CallOption) (*GenerateConversationTitleResponse, error)
+	// ImportConversation imports the conversations of a ChatGPT or Claude data
+	// export as conversations of the user, with a block per round.
+	ImportConversation(ctx context.Context, in *ImportConversationRequest, opts ...grpc.CallOption) (*ImportConversationResponse, error)
 	// DeleteAIConversation deletes an AI conversation.
 	DeleteAIConversation(ctx context.Context, in *DeleteAIConversationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// AddContextSeparator adds a context separator marker to a conversation.
 	AddContextSeparator(ctx context.Context, in *AddContextSeparatorRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// ClearConversationMessages deletes all messages in a conversation.
 	ClearConversationMessages(ctx context.Context, in *ClearConversationMessagesRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// CreateChatStreamTicket issues a short-lived, single-use ticket
+	// authenticating a GET /api/v1/ai/chat/stream request, for the EventSource
+	// clients which cannot set the Authorization header.
+	CreateChatStreamTicket(ctx context.Context, in *CreateChatStreamTicketRequest, opts ...grpc.CallOption) (*ChatStreamTicket, error)
 	// StopChat cancels an ongoing chat stream and terminates the associated session.
 	StopChat(ctx context.Context, in *StopChatRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// SubmitForm answers the form a block is waiting on and continues the agent
+	// in the same block, streaming the follow-up like Chat.
+	SubmitForm(ctx context.Context, in *SubmitFormRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChatResponse], error)
 	// GetSessionStats retrieves statistics for a specific session.
 	GetSessionStats(ctx context.Context, in *GetSessionStatsRequest, opts ...grpc.CallOption) (*SessionStats, error)
 	// ListSessionStats retrieves session statistics with pagination.
@@ -132,8 +166,16 @@ type AIServiceClient interface {
 	SetUserCostSettings(ctx context.Context, in *SetUserCostSettingsRequest, opts ...grpc.CallOption) (*UserCostSettings, error)
 	// ListBlocks retrieves blocks for a conversation.
 	ListBlocks(ctx context.Context, in *ListBlocksRequest, opts ...grpc.CallOption) (*ListBlocksResponse, error)
+	// GetBlockChanges retrieves the blocks of a conversation created or updated
+	// since a sync, and of existing blocks only the events appended since then.
+	// Clients poll it in place of refetching the conversation.
+	GetBlockChanges(ctx context.Context, in *GetBlockChangesRequest, opts ...grpc.CallOption) (*GetBlockChangesResponse, error)
 	// GetBlock retrieves a specific block.
 	GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*Block, error)
+	// GetBlockTranscript retrieves the compact transcript of a block, collapsed
+	// from its raw event stream, for clients that do not render event streams
+	// and for exports.
+	GetBlockTranscript(ctx context.Context, in *GetBlockTranscriptRequest, opts ...grpc.CallOption) (*BlockTranscript, error)
 	// CreateBlock creates a new conversation block.
 	CreateBlock(ctx context.Context, in *CreateBlockRequest, opts ...grpc.CallOption) (*Block, error)
 	// UpdateBlock updates a block.
@@ -157,6 +199,59 @@ type AIServiceClient interface {
 	// DeleteBranch deletes a block and all its descendants.
 	// Used for pruning unwanted conversation branches.
 	DeleteBranch(ctx context.Context, in *DeleteBranchRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetUsage retrieves the user's AI usage of the current month against the quotas.
+	GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*Usage, error)
+	// RunSelfTest runs a tiny chat end to end in a throwaway conversation and
+	// reports each check. Admin only.
+	RunSelfTest(ctx context.Context, in *RunSelfTestRequest, opts ...grpc.CallOption) (*SelfTestReport, error)
+	// ReindexAll embeds all the memos again in the background. Admin only.
+	ReindexAll(ctx context.Context, in *ReindexAllRequest, opts ...grpc.CallOption) (*ReindexProgress, error)
+	// ReindexSince embeds again the memos updated at or after a time in the
+	// background. Admin only.
+	ReindexSince(ctx context.Context, in *ReindexSinceRequest, opts ...grpc.CallOption) (*ReindexProgress, error)
+	// GetReindexProgress retrieves the progress of the latest reindex. Admin only.
+	GetReindexProgress(ctx context.Context, in *GetReindexProgressRequest, opts ...grpc.CallOption) (*ReindexProgress, error)
+	// ListMCPServers lists the MCP servers the user registered for Geek Mode.
+	ListMCPServers(ctx context.Context, in *ListMCPServersRequest, opts ...grpc.CallOption) (*ListMCPServersResponse, error)
+	// CreateMCPServer registers an MCP server for the Geek Mode sessions of the
+	// user. Sessions pick it up at their next request; stdio servers only in
+	// workspaces whose tool policy permits Bash.
+	CreateMCPServer(ctx context.Context, in *CreateMCPServerRequest, opts ...grpc.CallOption) (*MCPServer, error)
+	// UpdateMCPServer replaces an MCP server of the user.
+	UpdateMCPServer(ctx context.Context, in *UpdateMCPServerRequest, opts ...grpc.CallOption) (*MCPServer, error)
+	// DeleteMCPServer removes an MCP server of the user.
+	DeleteMCPServer(ctx context.Context, in *DeleteMCPServerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ListActionAudit lists the tool calls of the Geek and Evolution Mode
+	// sessions, latest first. Admin only.
+	ListActionAudit(ctx context.Context, in *ListActionAuditRequest, opts ...grpc.CallOption) (*ListActionAuditResponse, error)
+	// ExportActionAudit exports the matching tool calls, latest first, as CSV or
+	// JSON Lines. Admin only.
+	ExportActionAudit(ctx context.Context, in *ExportActionAuditRequest, opts ...grpc.CallOption) (*ExportActionAuditResponse, error)
+	// ListAgentModels lists the configured LLM providers, the default one first,
+	// and the provider and model of each agent type. Admin only.
+	ListAgentModels(ctx context.Context, in *ListAgentModelsRequest, opts ...grpc.CallOption) (*ListAgentModelsResponse, error)
+	// UpdateAgentModel selects the provider and model of an agent type; its next
+	// LLM calls use them. Admin only.
+	UpdateAgentModel(ctx context.Context, in *UpdateAgentModelRequest, opts ...grpc.CallOption) (*AgentModel, error)
+	// DeleteAgentModel removes the selection of an agent type, which returns to
+	// its DIVINESENSE_AI_AGENT_MODELS selection, or to the default provider.
+	// Admin only.
+	DeleteAgentModel(ctx context.Context, in *DeleteAgentModelRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetGeekWorkspaceUsage returns the disk usage of the Geek Mode workspace of
+	// the user and its limit.
+	GetGeekWorkspaceUsage(ctx context.Context, in *GetGeekWorkspaceUsageRequest, opts ...grpc.CallOption) (*GeekWorkspaceUsage, error)
+	// ListGeekWorkspaceFiles lists a directory of the Geek Mode workspace of the
+	// user, where the sessions leave their artifacts.
+	ListGeekWorkspaceFiles(ctx context.Context, in *ListGeekWorkspaceFilesRequest, opts ...grpc.CallOption) (*ListGeekWorkspaceFilesResponse, error)
+	// ReadGeekWorkspaceFile returns a file of the workspace with its text
+	// content, up to 1 MiB; binary files have none.
+	ReadGeekWorkspaceFile(ctx context.Context, in *ReadGeekWorkspaceFileRequest, opts ...grpc.CallOption) (*GeekWorkspaceFileContent, error)
+	// DownloadGeekWorkspaceFile returns a file of the workspace, up to 64 MiB,
+	// to save as an attachment.
+	DownloadGeekWorkspaceFile(ctx context.Context, in *DownloadGeekWorkspaceFileRequest, opts ...grpc.CallOption) (*DownloadGeekWorkspaceFileResponse, error)
+	// DeleteGeekWorkspaceFile removes a file of the workspace, or a directory
+	// with its content.
+	DeleteGeekWorkspaceFile(ctx context.Context, in *DeleteGeekWorkspaceFileRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type aIServiceClient struct {
@@ -386,6 +481,16 @@ func (c *aIServiceClient) GenerateConversationTitle(ctx context.Context, in *Gen
 	return out, nil
 }
 
+func (c *aIServiceClient) ImportConversation(ctx context.Context, in *ImportConversationRequest, opts ...grpc.CallOption) (*ImportConversationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportConversationResponse)
+	err := c.cc.Invoke(ctx, AIService_ImportConversation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *aIServiceClient) DeleteAIConversation(ctx context.Context, in *DeleteAIConversationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -416,6 +521,16 @@ func (c *aIServiceClient) ClearConversationMessages(ctx context.Context, in *Cle
 	return out, nil
 }
 
+func (c *aIServiceClient) CreateChatStreamTicket(ctx context.Context, in *CreateChatStreamTicketRequest, opts ...grpc.CallOption) (*ChatStreamTicket, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChatStreamTicket)
+	err := c.cc.Invoke(ctx, AIService_CreateChatStreamTicket_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *aIServiceClient) StopChat(ctx context.Context, in *StopChatRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -426,6 +541,25 @@ func (c *aIServiceClient) StopChat(ctx context.Context, in *StopChatRequest, opt
 	return out, nil
 }
 
+func (c *aIServiceClient) SubmitForm(ctx context.Context, in *SubmitFormRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChatResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AIService_ServiceDesc.Streams[1], AIService_SubmitForm_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubmitFormRequest, ChatResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AIService_SubmitFormClient = grpc.ServerStreamingClient[ChatResponse]
+
 func (c *aIServiceClient) GetSessionStats(ctx context.Context, in *GetSessionStatsRequest, opts ...grpc.CallOption) (*SessionStats, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SessionStats)
@@ -486,6 +620,16 @@ func (c *aIServiceClient) ListBlocks(ctx context.Context, in *ListBlocksRequest,
 	return out, nil
 }
 
+func (c *aIServiceClient) GetBlockChanges(ctx context.Context, in *GetBlockChangesRequest, opts ...grpc.CallOption) (*GetBlockChangesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBlockChangesResponse)
+	err := c.cc.Invoke(ctx, AIService_GetBlockChanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *aIServiceClient) GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*Block, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Block)
@@ -496,6 +640,16 @@ func (c *aIServiceClient) GetBlock(ctx context.Context, in *GetBlockRequest, opt
 	return out, nil
 }
 
+func (c *aIServiceClient) GetBlockTranscript(ctx context.Context, in *GetBlockTranscriptRequest, opts ...grpc.CallOption) (*BlockTranscript, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BlockTranscript)
+	err := c.cc.Invoke(ctx, AIService_GetBlockTranscript_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *aIServiceClient) CreateBlock(ctx context.Context, in *CreateBlockRequest, opts ...grpc.CallOption) (*Block, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Block)
@@ -586,6 +740,196 @@ func (c *aIServiceClient) DeleteBranch(ctx context.Context, in *DeleteBranchRequ
 	return out, nil
 }
 
+func (c *aIServiceClient) GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*Usage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Usage)
+	err := c.cc.Invoke(ctx, AIService_GetUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) RunSelfTest(ctx context.Context, in *RunSelfTestRequest, opts ...grpc.CallOption) (*SelfTestReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SelfTestReport)
+	err := c.cc.Invoke(ctx, AIService_RunSelfTest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) ReindexAll(ctx context.Context, in *ReindexAllRequest, opts ...grpc.CallOption) (*ReindexProgress, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReindexProgress)
+	err := c.cc.Invoke(ctx, AIService_ReindexAll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) ReindexSince(ctx context.Context, in *ReindexSinceRequest, opts ...grpc.CallOption) (*ReindexProgress, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReindexProgress)
+	err := c.cc.Invoke(ctx, AIService_ReindexSince_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) GetReindexProgress(ctx context.Context, in *GetReindexProgressRequest, opts ...grpc.CallOption) (*ReindexProgress, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReindexProgress)
+	err := c.cc.Invoke(ctx, AIService_GetReindexProgress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) ListMCPServers(ctx context.Context, in *ListMCPServersRequest, opts ...grpc.CallOption) (*ListMCPServersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMCPServersResponse)
+	err := c.cc.Invoke(ctx, AIService_ListMCPServers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) CreateMCPServer(ctx context.Context, in *CreateMCPServerRequest, opts ...grpc.CallOption) (*MCPServer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MCPServer)
+	err := c.cc.Invoke(ctx, AIService_CreateMCPServer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) UpdateMCPServer(ctx context.Context, in *UpdateMCPServerRequest, opts ...grpc.CallOption) (*MCPServer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MCPServer)
+	err := c.cc.Invoke(ctx, AIService_UpdateMCPServer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) DeleteMCPServer(ctx context.Context, in *DeleteMCPServerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, AIService_DeleteMCPServer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) ListActionAudit(ctx context.Context, in *ListActionAuditRequest, opts ...grpc.CallOption) (*ListActionAuditResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListActionAuditResponse)
+	err := c.cc.Invoke(ctx, AIService_ListActionAudit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) ExportActionAudit(ctx context.Context, in *ExportActionAuditRequest, opts ...grpc.CallOption) (*ExportActionAuditResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportActionAuditResponse)
+	err := c.cc.Invoke(ctx, AIService_ExportActionAudit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) ListAgentModels(ctx context.Context, in *ListAgentModelsRequest, opts ...grpc.CallOption) (*ListAgentModelsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAgentModelsResponse)
+	err := c.cc.Invoke(ctx, AIService_ListAgentModels_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) UpdateAgentModel(ctx context.Context, in *UpdateAgentModelRequest, opts ...grpc.CallOption) (*AgentModel, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentModel)
+	err := c.cc.Invoke(ctx, AIService_UpdateAgentModel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) DeleteAgentModel(ctx context.Context, in *DeleteAgentModelRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, AIService_DeleteAgentModel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) GetGeekWorkspaceUsage(ctx context.Context, in *GetGeekWorkspaceUsageRequest, opts ...grpc.CallOption) (*GeekWorkspaceUsage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GeekWorkspaceUsage)
+	err := c.cc.Invoke(ctx, AIService_GetGeekWorkspaceUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) ListGeekWorkspaceFiles(ctx context.Context, in *ListGeekWorkspaceFilesRequest, opts ...grpc.CallOption) (*ListGeekWorkspaceFilesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGeekWorkspaceFilesResponse)
+	err := c.cc.Invoke(ctx, AIService_ListGeekWorkspaceFiles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) ReadGeekWorkspaceFile(ctx context.Context, in *ReadGeekWorkspaceFileRequest, opts ...grpc.CallOption) (*GeekWorkspaceFileContent, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GeekWorkspaceFileContent)
+	err := c.cc.Invoke(ctx, AIService_ReadGeekWorkspaceFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) DownloadGeekWorkspaceFile(ctx context.Context, in *DownloadGeekWorkspaceFileRequest, opts ...grpc.CallOption) (*DownloadGeekWorkspaceFileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DownloadGeekWorkspaceFileResponse)
+	err := c.cc.Invoke(ctx, AIService_DownloadGeekWorkspaceFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aIServiceClient) DeleteGeekWorkspaceFile(ctx context.Context, in *DeleteGeekWorkspaceFileRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, AIService_DeleteGeekWorkspaceFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AIServiceServer is the server API for AIService service.
 // All implementations must embed UnimplementedAIServiceServer
 // for forward compatibility.
@@ -635,14 +979,24 @@ type AIServiceServer interface {
 	UpdateAIConversation(context.Context, *UpdateAIConversationRequest) (*AIConversation, error)
 	// GenerateConversationTitle generates a meaningful title for a conversation using AI.
 	GenerateConversationTitle(context.Context, *GenerateConversationTitleRequest) (*GenerateConversationTitleResponse, error)
+	// ImportConversation imports the conversations of a ChatGPT or Claude data
+	// export as conversations of the user, with a block per round.
+	ImportConversation(context.Context, *ImportConversationRequest) (*ImportConversationResponse, error)
 	// DeleteAIConversation deletes an AI conversation.
 	DeleteAIConversation(context.Context, *DeleteAIConversationRequest) (*emptypb.Empty, error)
 	// AddContextSeparator adds a context separator marker to a conversation.
 	AddContextSeparator(context.Context, *AddContextSeparatorRequest) (*emptypb.Empty, error)
 	// ClearConversationMessages deletes all messages in a conversation.
 	ClearConversationMessages(context.Context, *ClearConversationMessagesRequest) (*emptypb.Empty, error)
+	// CreateChatStreamTicket issues a short-lived, single-use ticket
+	// authenticating a GET /api/v1/ai/chat/stream request, for the EventSource
+	// clients which cannot set the Authorization header.
+	CreateChatStreamTicket(context.Context, *CreateChatStreamTicketRequest) (*ChatStreamTicket, error)
 	// StopChat cancels an ongoing chat stream and terminates the associated session.
 	StopChat(context.Context, *StopChatRequest) (*emptypb.Empty, error)
+	// SubmitForm answers the form a block is waiting on and continues the agent
+	// in the same block, streaming the follow-up like Chat.
+	SubmitForm(*SubmitFormRequest, grpc.ServerStreamingServer[ChatResponse]) error
 	// GetSessionStats retrieves statistics for a specific session.
 	GetSessionStats(context.Context, *GetSessionStatsRequest) (*SessionStats, error)
 	// ListSessionStats retrieves session statistics with pagination.
@@ -655,8 +1009,16 @@ type AIServiceServer interface {
 	SetUserCostSettings(context.Context, *SetUserCostSettingsRequest) (*UserCostSettings, error)
 	// ListBlocks retrieves blocks for a conversation.
 	ListBlocks(context.Context, *ListBlocksRequest) (*ListBlocksResponse, error)
+	// GetBlockChanges retrieves the blocks of a conversation created or updated
+	// since a sync, and of existing blocks only the events appended since then.
+	// Clients poll it in place of refetching the conversation.
+	GetBlockChanges(context.Context, *GetBlockChangesRequest) (*GetBlockChangesResponse, error)
 	// GetBlock retrieves a specific block.
 	GetBlock(context.Context, *GetBlockRequest) (*Block, error)
+	// GetBlockTranscript retrieves the compact transcript of a block, collapsed
+	// from its raw event stream, for clients that do not render event streams
+	// and for exports.
+	GetBlockTranscript(context.Context, *GetBlockTranscriptRequest) (*BlockTranscript, error)
 	// CreateBlock creates a new conversation block.
 	CreateBlock(context.Context, *CreateBlockRequest) (*Block, error)
 	// UpdateBlock updates a block.
@@ -680,6 +1042,59 @@ type AIServiceServer interface {
 	// DeleteBranch deletes a block and all its descendants.
 	// Used for pruning unwanted conversation branches.
 	DeleteBranch(context.Context, *DeleteBranchRequest) (*emptypb.Empty, error)
+	// GetUsage retrieves the user's AI usage of the current month against the quotas.
+	GetUsage(context.Context, *GetUsageRequest) (*Usage, error)
+	// RunSelfTest runs a tiny chat end to end in a throwaway conversation and
+	// reports each check. Admin only.
+	RunSelfTest(context.Context, *RunSelfTestRequest) (*SelfTestReport, error)
+	// ReindexAll embeds all the memos again in the background. Admin only.
+	ReindexAll(context.Context, *ReindexAllRequest) (*ReindexProgress, error)
+	// ReindexSince embeds again the memos updated at or after a time in the
+	// background. Admin only.
+	ReindexSince(context.Context, *ReindexSinceRequest) (*ReindexProgress, error)
+	// GetReindexProgress retrieves the progress of the latest reindex. Admin only.
+	GetReindexProgress(context.Context, *GetReindexProgressRequest) (*ReindexProgress, error)
+	// ListMCPServers lists the MCP servers the user registered for Geek Mode.
+	ListMCPServers(context.Context, *ListMCPServersRequest) (*ListMCPServersResponse, error)
+	// CreateMCPServer registers an MCP server for the Geek Mode sessions of the
+	// user. Sessions pick it up at their next request; stdio servers only in
+	// workspaces whose tool policy permits Bash.
+	CreateMCPServer(context.Context, *CreateMCPServerRequest) (*MCPServer, error)
+	// UpdateMCPServer replaces an MCP server of the user.
+	UpdateMCPServer(context.Context, *UpdateMCPServerRequest) (*MCPServer, error)
+	// DeleteMCPServer removes an MCP server of the user.
+	DeleteMCPServer(context.Context, *DeleteMCPServerRequest) (*emptypb.Empty, error)
+	// ListActionAudit lists the tool calls of the Geek and Evolution Mode
+	// sessions, latest first. Admin only.
+	ListActionAudit(context.Context, *ListActionAuditRequest) (*ListActionAuditResponse, error)
+	// ExportActionAudit exports the matching tool calls, latest first, as CSV or
+	// JSON Lines. Admin only.
+	ExportActionAudit(context.Context, *ExportActionAuditRequest) (*ExportActionAuditResponse, error)
+	// ListAgentModels lists the configured LLM providers, the default one first,
+	// and the provider and model of each agent type. Admin only.
+	ListAgentModels(context.Context, *ListAgentModelsRequest) (*ListAgentModelsResponse, error)
+	// UpdateAgentModel selects the provider and model of an agent type; its next
+	// LLM calls use them. Admin only.
+	UpdateAgentModel(context.Context, *UpdateAgentModelRequest) (*AgentModel, error)
+	// DeleteAgentModel removes the selection of an agent type, which returns to
+	// its DIVINESENSE_AI_AGENT_MODELS selection, or to the default provider.
+	// Admin only.
+	DeleteAgentModel(context.Context, *DeleteAgentModelRequest) (*emptypb.Empty, error)
+	// GetGeekWorkspaceUsage returns the disk usage of the Geek Mode workspace of
+	// the user and its limit.
+	GetGeekWorkspaceUsage(context.Context, *GetGeekWorkspaceUsageRequest) (*GeekWorkspaceUsage, error)
+	// ListGeekWorkspaceFiles lists a directory of the Geek Mode workspace of the
+	// user, where the sessions leave their artifacts.
+	ListGeekWorkspaceFiles(context.Context, *ListGeekWorkspaceFilesRequest) (*ListGeekWorkspaceFilesResponse, error)
+	// ReadGeekWorkspaceFile returns a file of the workspace with its text
+	// content, up to 1 MiB; binary files have none.
+	ReadGeekWorkspaceFile(context.Context, *ReadGeekWorkspaceFileRequest) (*GeekWorkspaceFileContent, error)
+	// DownloadGeekWorkspaceFile returns a file of the workspace, up to 64 MiB,
+	// to save as an attachment.
+	DownloadGeekWorkspaceFile(context.Context, *DownloadGeekWorkspaceFileRequest) (*DownloadGeekWorkspaceFileResponse, error)
+	// DeleteGeekWorkspaceFile removes a file of the workspace, or a directory
+	// with its content.
+	DeleteGeekWorkspaceFile(context.Context, *DeleteGeekWorkspaceFileRequest) (*emptypb.Empty, error)
 	mustEmbedUnimplementedAIServiceServer()
 }
 
@@ -753,6 +1168,9 @@ func (UnimplementedAIServiceServer) UpdateAIConversation(context.Context, *Updat
 func (UnimplementedAIServiceServer) GenerateConversationTitle(context.Context, *GenerateConversationTitleRequest) (*GenerateConversationTitleResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GenerateConversationTitle not implemented")
 }
+func (UnimplementedAIServiceServer) ImportConversation(context.Context, *ImportConversationRequest) (*ImportConversationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ImportConversation not implemented")
+}
 func (UnimplementedAIServiceServer) DeleteAIConversation(context.Context, *DeleteAIConversationRequest) (*emptypb.Empty, error) {
 	return nil, status.Error(codes.Unimplemented, "method DeleteAIConversation not implemented")
 }
@@ -762,9 +1180,15 @@ func (UnimplementedAIServiceServer) AddContextSeparator(context.Context, *AddCon
 func (UnimplementedAIServiceServer) ClearConversationMessages(context.Context, *ClearConversationMessagesRequest) (*emptypb.Empty, error) {
 	return nil, status.Error(codes.Unimplemented, "method ClearConversationMessages not implemented")
 }
+func (UnimplementedAIServiceServer) CreateChatStreamTicket(context.Context, *CreateChatStreamTicketRequest) (*ChatStreamTicket, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateChatStreamTicket not implemented")
+}
 func (UnimplementedAIServiceServer) StopChat(context.Context, *StopChatRequest) (*emptypb.Empty, error) {
 	return nil, status.Error(codes.Unimplemented, "method StopChat not implemented")
 }
+func (UnimplementedAIServiceServer) SubmitForm(*SubmitFormRequest, grpc.ServerStreamingServer[ChatResponse]) error {
+	return status.Error(codes.Unimplemented, "method SubmitForm not implemented")
+}
 func (UnimplementedAIServiceServer) GetSessionStats(context.Context, *GetSessionStatsRequest) (*SessionStats, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetSessionStats not implemented")
 }
@@ -783,9 +1207,15 @@ func (UnimplementedAIServiceServer) SetUserCostSettings(context.Context, *SetUse
 func (UnimplementedAIServiceServer) ListBlocks(context.Context, *ListBlocksRequest) (*ListBlocksResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListBlocks not implemented")
 }
+func (UnimplementedAIServiceServer) GetBlockChanges(context.Context, *GetBlockChangesRequest) (*GetBlockChangesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetBlockChanges not implemented")
+}
 func (UnimplementedAIServiceServer) GetBlock(context.Context, *GetBlockRequest) (*Block, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetBlock not implemented")
 }
+func (UnimplementedAIServiceServer) GetBlockTranscript(context.Context, *GetBlockTranscriptRequest) (*BlockTranscript, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetBlockTranscript not implemented")
+}
 func (UnimplementedAIServiceServer) CreateBlock(context.Context, *CreateBlockRequest) (*Block, error) {
 	return nil, status.Error(codes.Unimplemented, "method CreateBlock not implemented")
 }
@@ -813,6 +1243,63 @@ func (UnimplementedAIServiceServer) SwitchBranch(context.Context, *SwitchBranchR
 func (UnimplementedAIServiceServer) DeleteBranch(context.Context, *DeleteBranchRequest) (*emptypb.Empty, error) {
 	return nil, status.Error(codes.Unimplemented, "method DeleteBranch not implemented")
 }
+func (UnimplementedAIServiceServer) GetUsage(context.Context, *GetUsageRequest) (*Usage, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUsage not implemented")
+}
+func (UnimplementedAIServiceServer) RunSelfTest(context.Context, *RunSelfTestRequest) (*SelfTestReport, error) {
+	return nil, status.Error(codes.Unimplemented, "method RunSelfTest not implemented")
+}
+func (UnimplementedAIServiceServer) ReindexAll(context.Context, *ReindexAllRequest) (*ReindexProgress, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReindexAll not implemented")
+}
+func (UnimplementedAIServiceServer) ReindexSince(context.Context, *ReindexSinceRequest) (*ReindexProgress, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReindexSince not implemented")
+}
+func (UnimplementedAIServiceServer) GetReindexProgress(context.Context, *GetReindexProgressRequest) (*ReindexProgress, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetReindexProgress not implemented")
+}
+func (UnimplementedAIServiceServer) ListMCPServers(context.Context, *ListMCPServersRequest) (*ListMCPServersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListMCPServers not implemented")
+}
+func (UnimplementedAIServiceServer) CreateMCPServer(context.Context, *CreateMCPServerRequest) (*MCPServer, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateMCPServer not implemented")
+}
+func (UnimplementedAIServiceServer) UpdateMCPServer(context.Context, *UpdateMCPServerRequest) (*MCPServer, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateMCPServer not implemented")
+}
+func (UnimplementedAIServiceServer) DeleteMCPServer(context.Context, *DeleteMCPServerRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteMCPServer not implemented")
+}
+func (UnimplementedAIServiceServer) ListActionAudit(context.Context, *ListActionAuditRequest) (*ListActionAuditResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListActionAudit not implemented")
+}
+func (UnimplementedAIServiceServer) ExportActionAudit(context.Context, *ExportActionAuditRequest) (*ExportActionAuditResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExportActionAudit not implemented")
+}
+func (UnimplementedAIServiceServer) ListAgentModels(context.Context, *ListAgentModelsRequest) (*ListAgentModelsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAgentModels not implemented")
+}
+func (UnimplementedAIServiceServer) UpdateAgentModel(context.Context, *UpdateAgentModelRequest) (*AgentModel, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateAgentModel not implemented")
+}
+func (UnimplementedAIServiceServer) DeleteAgentModel(context.Context, *DeleteAgentModelRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteAgentModel not implemented")
+}
+func (UnimplementedAIServiceServer) GetGeekWorkspaceUsage(context.Context, *GetGeekWorkspaceUsageRequest) (*GeekWorkspaceUsage, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetGeekWorkspaceUsage not implemented")
+}
+func (UnimplementedAIServiceServer) ListGeekWorkspaceFiles(context.Context, *ListGeekWorkspaceFilesRequest) (*ListGeekWorkspaceFilesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListGeekWorkspaceFiles not implemented")
+}
+func (UnimplementedAIServiceServer) ReadGeekWorkspaceFile(context.Context, *ReadGeekWorkspaceFileRequest) (*GeekWorkspaceFileContent, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReadGeekWorkspaceFile not implemented")
+}
+func (UnimplementedAIServiceServer) DownloadGeekWorkspaceFile(context.Context, *DownloadGeekWorkspaceFileRequest) (*DownloadGeekWorkspaceFileResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DownloadGeekWorkspaceFile not implemented")
+}
+func (UnimplementedAIServiceServer) DeleteGeekWorkspaceFile(context.Context, *DeleteGeekWorkspaceFileRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteGeekWorkspaceFile not implemented")
+}
 func (UnimplementedAIServiceServer) mustEmbedUnimplementedAIServiceServer() {}
 func (UnimplementedAIServiceServer) testEmbeddedByValue()                   {}
 
@@ -1205,6 +1692,24 @@ func _AIService_GenerateConversationTitle_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AIService_ImportConversation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportConversationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).ImportConversation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_ImportConversation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).ImportConversation(ctx, req.(*ImportConversationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AIService_DeleteAIConversation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteAIConversationRequest)
 	if err := dec(in); err != nil {
@@ -1259,6 +1764,24 @@ func _AIService_ClearConversationMessages_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AIService_CreateChatStreamTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateChatStreamTicketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).CreateChatStreamTicket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_CreateChatStreamTicket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).CreateChatStreamTicket(ctx, req.(*CreateChatStreamTicketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AIService_StopChat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(StopChatRequest)
 	if err := dec(in); err != nil {
@@ -1277,6 +1800,17 @@ func _AIService_StopChat_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AIService_SubmitForm_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubmitFormRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AIServiceServer).SubmitForm(m, &grpc.GenericServerStream[SubmitFormRequest, ChatResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AIService_SubmitFormServer = grpc.ServerStreamingServer[ChatResponse]
+
 func _AIService_GetSessionStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetSessionStatsRequest)
 	if err := dec(in); err != nil {
@@ -1385,56 +1919,92 @@ func _AIService_ListBlocks_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
-func _AIService_GetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetBlockRequest)
+func _AIService_GetBlockChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockChangesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AIServiceServer).GetBlock(ctx, in)
+		return srv.(AIServiceServer).GetBlockChanges(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: AIService_GetBlock_FullMethodName,
+		FullMethod: AIService_GetBlockChanges_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AIServiceServer).GetBlock(ctx, req.(*GetBlockRequest))
+		return srv.(AIServiceServer).GetBlockChanges(ctx, req.(*GetBlockChangesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _AIService_CreateBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateBlockRequest)
+func _AIService_GetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AIServiceServer).CreateBlock(ctx, in)
+		return srv.(AIServiceServer).GetBlock(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: AIService_CreateBlock_FullMethodName,
+		FullMethod: AIService_GetBlock_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AIServiceServer).CreateBlock(ctx, req.(*CreateBlockRequest))
+		return srv.(AIServiceServer).GetBlock(ctx, req.(*GetBlockRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _AIService_UpdateBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateBlockRequest)
+func _AIService_GetBlockTranscript_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockTranscriptRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AIServiceServer).UpdateBlock(ctx, in)
+		return srv.(AIServiceServer).GetBlockTranscript(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: AIService_UpdateBlock_FullMethodName,
+		FullMethod: AIService_GetBlockTranscript_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AIServiceServer).UpdateBlock(ctx, req.(*UpdateBlockRequest))
+		return srv.(AIServiceServer).GetBlockTranscript(ctx, req.(*GetBlockTranscriptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_CreateBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).CreateBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_CreateBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).CreateBlock(ctx, req.(*CreateBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_UpdateBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).UpdateBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_UpdateBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).UpdateBlock(ctx, req.(*UpdateBlockRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -1565,6 +2135,348 @@ func _AIService_DeleteBranch_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AIService_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_GetUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).GetUsage(ctx, req.(*GetUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_RunSelfTest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunSelfTestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).RunSelfTest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_RunSelfTest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).RunSelfTest(ctx, req.(*RunSelfTestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_ReindexAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReindexAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).ReindexAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_ReindexAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).ReindexAll(ctx, req.(*ReindexAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_ReindexSince_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReindexSinceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).ReindexSince(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_ReindexSince_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).ReindexSince(ctx, req.(*ReindexSinceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_GetReindexProgress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReindexProgressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).GetReindexProgress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_GetReindexProgress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).GetReindexProgress(ctx, req.(*GetReindexProgressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_ListMCPServers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMCPServersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).ListMCPServers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_ListMCPServers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).ListMCPServers(ctx, req.(*ListMCPServersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_CreateMCPServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMCPServerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).CreateMCPServer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_CreateMCPServer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).CreateMCPServer(ctx, req.(*CreateMCPServerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_UpdateMCPServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMCPServerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).UpdateMCPServer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_UpdateMCPServer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).UpdateMCPServer(ctx, req.(*UpdateMCPServerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_DeleteMCPServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMCPServerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).DeleteMCPServer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_DeleteMCPServer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).DeleteMCPServer(ctx, req.(*DeleteMCPServerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_ListActionAudit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListActionAuditRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).ListActionAudit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_ListActionAudit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).ListActionAudit(ctx, req.(*ListActionAuditRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_ExportActionAudit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportActionAuditRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).ExportActionAudit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_ExportActionAudit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).ExportActionAudit(ctx, req.(*ExportActionAuditRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_ListAgentModels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAgentModelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).ListAgentModels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_ListAgentModels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).ListAgentModels(ctx, req.(*ListAgentModelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_UpdateAgentModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAgentModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).UpdateAgentModel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_UpdateAgentModel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).UpdateAgentModel(ctx, req.(*UpdateAgentModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_DeleteAgentModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAgentModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).DeleteAgentModel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_DeleteAgentModel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).DeleteAgentModel(ctx, req.(*DeleteAgentModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_GetGeekWorkspaceUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGeekWorkspaceUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).GetGeekWorkspaceUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_GetGeekWorkspaceUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).GetGeekWorkspaceUsage(ctx, req.(*GetGeekWorkspaceUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_ListGeekWorkspaceFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGeekWorkspaceFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).ListGeekWorkspaceFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_ListGeekWorkspaceFiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).ListGeekWorkspaceFiles(ctx, req.(*ListGeekWorkspaceFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_ReadGeekWorkspaceFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadGeekWorkspaceFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).ReadGeekWorkspaceFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_ReadGeekWorkspaceFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).ReadGeekWorkspaceFile(ctx, req.(*ReadGeekWorkspaceFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_DownloadGeekWorkspaceFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DownloadGeekWorkspaceFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).DownloadGeekWorkspaceFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_DownloadGeekWorkspaceFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).DownloadGeekWorkspaceFile(ctx, req.(*DownloadGeekWorkspaceFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AIService_DeleteGeekWorkspaceFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteGeekWorkspaceFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AIServiceServer).DeleteGeekWorkspaceFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AIService_DeleteGeekWorkspaceFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AIServiceServer).DeleteGeekWorkspaceFile(ctx, req.(*DeleteGeekWorkspaceFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AIService_ServiceDesc is the grpc.ServiceDesc for AIService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1652,6 +2564,10 @@ var AIService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GenerateConversationTitle",
 			Handler:    _AIService_GenerateConversationTitle_Handler,
 		},
+		{
+			MethodName: "ImportConversation",
+			Handler:    _AIService_ImportConversation_Handler,
+		},
 		{
 			MethodName: "DeleteAIConversation",
 			Handler:    _AIService_DeleteAIConversation_Handler,
@@ -1664,6 +2580,10 @@ var AIService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ClearConversationMessages",
 			Handler:    _AIService_ClearConversationMessages_Handler,
 		},
+		{
+			MethodName: "CreateChatStreamTicket",
+			Handler:    _AIService_CreateChatStreamTicket_Handler,
+		},
 		{
 			MethodName: "StopChat",
 			Handler:    _AIService_StopChat_Handler,
@@ -1692,10 +2612,18 @@ var AIService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListBlocks",
 			Handler:    _AIService_ListBlocks_Handler,
 		},
+		{
+			MethodName: "GetBlockChanges",
+			Handler:    _AIService_GetBlockChanges_Handler,
+		},
 		{
 			MethodName: "GetBlock",
 			Handler:    _AIService_GetBlock_Handler,
 		},
+		{
+			MethodName: "GetBlockTranscript",
+			Handler:    _AIService_GetBlockTranscript_Handler,
+		},
 		{
 			MethodName: "CreateBlock",
 			Handler:    _AIService_CreateBlock_Handler,
@@ -1732,6 +2660,82 @@ var AIService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteBranch",
 			Handler:    _AIService_DeleteBranch_Handler,
 		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _AIService_GetUsage_Handler,
+		},
+		{
+			MethodName: "RunSelfTest",
+			Handler:    _AIService_RunSelfTest_Handler,
+		},
+		{
+			MethodName: "ReindexAll",
+			Handler:    _AIService_ReindexAll_Handler,
+		},
+		{
+			MethodName: "ReindexSince",
+			Handler:    _AIService_ReindexSince_Handler,
+		},
+		{
+			MethodName: "GetReindexProgress",
+			Handler:    _AIService_GetReindexProgress_Handler,
+		},
+		{
+			MethodName: "ListMCPServers",
+			Handler:    _AIService_ListMCPServers_Handler,
+		},
+		{
+			MethodName: "CreateMCPServer",
+			Handler:    _AIService_CreateMCPServer_Handler,
+		},
+		{
+			MethodName: "UpdateMCPServer",
+			Handler:    _AIService_UpdateMCPServer_Handler,
+		},
+		{
+			MethodName: "DeleteMCPServer",
+			Handler:    _AIService_DeleteMCPServer_Handler,
+		},
+		{
+			MethodName: "ListActionAudit",
+			Handler:    _AIService_ListActionAudit_Handler,
+		},
+		{
+			MethodName: "ExportActionAudit",
+			Handler:    _AIService_ExportActionAudit_Handler,
+		},
+		{
+			MethodName: "ListAgentModels",
+			Handler:    _AIService_ListAgentModels_Handler,
+		},
+		{
+			MethodName: "UpdateAgentModel",
+			Handler:    _AIService_UpdateAgentModel_Handler,
+		},
+		{
+			MethodName: "DeleteAgentModel",
+			Handler:    _AIService_DeleteAgentModel_Handler,
+		},
+		{
+			MethodName: "GetGeekWorkspaceUsage",
+			Handler:    _AIService_GetGeekWorkspaceUsage_Handler,
+		},
+		{
+			MethodName: "ListGeekWorkspaceFiles",
+			Handler:    _AIService_ListGeekWorkspaceFiles_Handler,
+		},
+		{
+			MethodName: "ReadGeekWorkspaceFile",
+			Handler:    _AIService_ReadGeekWorkspaceFile_Handler,
+		},
+		{
+			MethodName: "DownloadGeekWorkspaceFile",
+			Handler:    _AIService_DownloadGeekWorkspaceFile_Handler,
+		},
+		{
+			MethodName: "DeleteGeekWorkspaceFile",
+			Handler:    _AIService_DeleteGeekWorkspaceFile_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1739,6 +2743,11 @@ var AIService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _AIService_Chat_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubmitForm",
+			Handler:       _AIService_SubmitForm_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api/v1/ai_service.proto",
 }
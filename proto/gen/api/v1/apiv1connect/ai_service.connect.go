@@ -88,6 +88,9 @@ const (
 	// AIServiceGenerateConversationTitleProcedure is the fully-qualified name of the AIService's
 	// GenerateConversationTitle RPC.
 	AIServiceGenerateConversationTitleProcedure = "/memos.api.v1.AIService/GenerateConversationTitle"
+	// AIServiceImportConversationProcedure is the fully-qualified name of the AIService's
+	// ImportConversation RPC.
+	AIServiceImportConversationProcedure = "/memos.api.v1.AIService/ImportConversation"
 	// AIServiceDeleteAIConversationProcedure is the fully-qualified name of the AIService's
 	// DeleteAIConversation RPC.
 	AIServiceDeleteAIConversationProcedure = "/memos.api.v1.AIService/DeleteAIConversation"
@@ -97,8 +100,13 @@ const (
 	// AIServiceClearConversationMessagesProcedure is the fully-qualified name of the AIService's
 	// ClearConversationMessages RPC.
 	AIServiceClearConversationMessagesProcedure = "/memos.api.v1.AIService/ClearConversationMessages"
+	// AIServiceCreateChatStreamTicketProcedure is the fully-qualified name of the AIService's
+	// CreateChatStreamTicket RPC.
+	AIServiceCreateChatStreamTicketProcedure = "/memos.api.v1.AIService/CreateChatStreamTicket"
 	// AIServiceStopChatProcedure is the fully-qualified name of the AIService's StopChat RPC.
 	AIServiceStopChatProcedure = "/memos.api.v1.AIService/StopChat"
+	// AIServiceSubmitFormProcedure is the fully-qualified name of the AIService's SubmitForm RPC.
+	AIServiceSubmitFormProcedure = "/memos.api.v1.AIService/SubmitForm"
 	// AIServiceGetSessionStatsProcedure is the fully-qualified name of the AIService's GetSessionStats
 	// RPC.
 	AIServiceGetSessionStatsProcedure = "/memos.api.v1.AIService/GetSessionStats"
@@ -115,8 +123,14 @@ const (
 	AIServiceSetUserCostSettingsProcedure = "/memos.api.v1.AIService/SetUserCostSettings"
 	// AIServiceListBlocksProcedure is the fully-qualified name of the AIService's ListBlocks RPC.
 	AIServiceListBlocksProcedure = "/memos.api.v1.AIService/ListBlocks"
+	// AIServiceGetBlockChangesProcedure is the fully-qualified name of the AIService's GetBlockChanges
+	// RPC.
+	AIServiceGetBlockChangesProcedure = "/memos.api.v1.AIService/GetBlockChanges"
 	// AIServiceGetBlockProcedure is the fully-qualified name of the AIService's GetBlock RPC.
 	AIServiceGetBlockProcedure = "/memos.api.v1.AIService/GetBlock"
+	// AIServiceGetBlockTranscriptProcedure is the fully-qualified name of the AIService's
+	// GetBlockTranscript RPC.
+	AIServiceGetBlockTranscriptProcedure = "/memos.api.v1.AIService/GetBlockTranscript"
 	// AIServiceCreateBlockProcedure is the fully-qualified name of the AIService's CreateBlock RPC.
 	AIServiceCreateBlockProcedure = "/memos.api.v1.AIService/CreateBlock"
 	// AIServiceUpdateBlockProcedure is the fully-qualified name of the AIService's UpdateBlock RPC.
@@ -137,6 +151,59 @@ const (
 	AIServiceSwitchBranchProcedure = "/memos.api.v1.AIService/SwitchBranch"
 	// AIServiceDeleteBranchProcedure is the fully-qualified name of the AIService's DeleteBranch RPC.
 	AIServiceDeleteBranchProcedure = "/memos.api.v1.AIService/DeleteBranch"
+	// AIServiceGetUsageProcedure is the fully-qualified name of the AIService's GetUsage RPC.
+	AIServiceGetUsageProcedure = "/memos.api.v1.AIService/GetUsage"
+	// AIServiceRunSelfTestProcedure is the fully-qualified name of the AIService's RunSelfTest RPC.
+	AIServiceRunSelfTestProcedure = "/memos.api.v1.AIService/RunSelfTest"
+	// AIServiceReindexAllProcedure is the fully-qualified name of the AIService's ReindexAll RPC.
+	AIServiceReindexAllProcedure = "/memos.api.v1.AIService/ReindexAll"
+	// AIServiceReindexSinceProcedure is the fully-qualified name of the AIService's ReindexSince RPC.
+	AIServiceReindexSinceProcedure = "/memos.api.v1.AIService/ReindexSince"
+	// AIServiceGetReindexProgressProcedure is the fully-qualified name of the AIService's
+	// GetReindexProgress RPC.
+	AIServiceGetReindexProgressProcedure = "/memos.api.v1.AIService/GetReindexProgress"
+	// AIServiceListMCPServersProcedure is the fully-qualified name of the AIService's ListMCPServers
+	// RPC.
+	AIServiceListMCPServersProcedure = "/memos.api.v1.AIService/ListMCPServers"
+	// AIServiceCreateMCPServerProcedure is the fully-qualified name of the AIService's CreateMCPServer
+	// RPC.
+	AIServiceCreateMCPServerProcedure = "/memos.api.v1.AIService/CreateMCPServer"
+	// AIServiceUpdateMCPServerProcedure is the fully-qualified name of the AIService's UpdateMCPServer
+	// RPC.
+	AIServiceUpdateMCPServerProcedure = "/memos.api.v1.AIService/UpdateMCPServer"
+	// AIServiceDeleteMCPServerProcedure is the fully-qualified name of the AIService's DeleteMCPServer
+	// RPC.
+	AIServiceDeleteMCPServerProcedure = "/memos.api.v1.AIService/DeleteMCPServer"
+	// AIServiceListActionAuditProcedure is the fully-qualified name of the AIService's ListActionAudit
+	// RPC.
+	AIServiceListActionAuditProcedure = "/memos.api.v1.AIService/ListActionAudit"
+	// AIServiceExportActionAuditProcedure is the fully-qualified name of the AIService's
+	// ExportActionAudit RPC.
+	AIServiceExportActionAuditProcedure = "/memos.api.v1.AIService/ExportActionAudit"
+	// AIServiceListAgentModelsProcedure is the fully-qualified name of the AIService's ListAgentModels
+	// RPC.
+	AIServiceListAgentModelsProcedure = "/memos.api.v1.AIService/ListAgentModels"
+	// AIServiceUpdateAgentModelProcedure is the fully-qualified name of the AIService's
+	// UpdateAgentModel RPC.
+	AIServiceUpdateAgentModelProcedure = "/memos.api.v1.AIService/UpdateAgentModel"
+	// AIServiceDeleteAgentModelProcedure is the fully-qualified name of the AIService's
+	// DeleteAgentModel RPC.
+	AIServiceDeleteAgentModelProcedure = "/memos.api.v1.AIService/DeleteAgentModel"
+	// AIServiceGetGeekWorkspaceUsageProcedure is the fully-qualified name of the AIService's
+	// GetGeekWorkspaceUsage RPC.
+	AIServiceGetGeekWorkspaceUsageProcedure = "/memos.api.v1.AIService/GetGeekWorkspaceUsage"
+	// AIServiceListGeekWorkspaceFilesProcedure is the fully-qualified name of the AIService's
+	// ListGeekWorkspaceFiles RPC.
+	AIServiceListGeekWorkspaceFilesProcedure = "/memos.api.v1.AIService/ListGeekWorkspaceFiles"
+	// AIServiceReadGeekWorkspaceFileProcedure is the fully-qualified name of the AIService's
+	// ReadGeekWorkspaceFile RPC.
+	AIServiceReadGeekWorkspaceFileProcedure = "/memos.api.v1.AIService/ReadGeekWorkspaceFile"
+	// AIServiceDownloadGeekWorkspaceFileProcedure is the fully-qualified name of the AIService's
+	// DownloadGeekWorkspaceFile RPC.
+	AIServiceDownloadGeekWorkspaceFileProcedure = "/memos.api.v1.AIService/DownloadGeekWorkspaceFile"
+	// AIServiceDeleteGeekWorkspaceFileProcedure is the fully-qualified name of the AIService's
+	// DeleteGeekWorkspaceFile RPC.
+	AIServiceDeleteGeekWorkspaceFileProcedure = "/memos.api.v1.AIService/DeleteGeekWorkspaceFile"
 )
 
 // AIServiceClient is a client for the memos.api.v1.AIService service.
@@ -184,14 +251,24 @@ type AIServiceClient interface {
 	UpdateAIConversation(context.Context, *connect.Request[v1.UpdateAIConversationRequest]) (*connect.Response[v1.AIConversation], error)
 	// GenerateConversationTitle generates a meaningful title for a conversation using AI.
 	GenerateConversationTitle(context.Context, *connect.Request[v1.GenerateConversationTitleRequest]) (*connect.Response[v1.GenerateConversationTitleResponse], error)
+	// ImportConversation imports the conversations of a ChatGPT or Claude data
+	// export as conversations of the user, with a block per round.
+	ImportConversation(context.Context, *connect.Request[v1.ImportConversationRequest]) (*connect.Response[v1.ImportConversationResponse], error)
 	// DeleteAIConversation deletes an AI conversation.
 	DeleteAIConversation(context.Context, *connect.Request[v1.DeleteAIConversationRequest]) (*connect.Response[emptypb.Empty], error)
 	// AddContextSeparator adds a context separator marker to a conversation.
 	AddContextSeparator(context.Context, *connect.Request[v1.AddContextSeparatorRequest]) (*connect.Response[emptypb.Empty], error)
 	// ClearConversationMessages deletes all messages in a conversation.
 	ClearConversationMessages(context.Context, *connect.Request[v1.ClearConversationMessagesRequest]) (*connect.Response[emptypb.Empty], error)
+	// CreateChatStreamTicket issues a short-lived, single-use ticket
+	// authenticating a GET /api/v1/ai/chat/stream request, for the EventSource
+	// clients which cannot set the Authorization header.
+	CreateChatStreamTicket(context.Context, *connect.Request[v1.CreateChatStreamTicketRequest]) (*connect.Response[v1.ChatStreamTicket], error)
 	// StopChat cancels an ongoing chat stream and terminates the associated session.
 	StopChat(context.Context, *connect.Request[v1.StopChatRequest]) (*connect.Response[emptypb.Empty], error)
+	// SubmitForm answers the form a block is waiting on and continues the agent
+	// in the same block, streaming the follow-up like Chat.
+	SubmitForm(context.Context, *connect.Request[v1.SubmitFormRequest]) (*connect.ServerStreamForClient[v1.ChatResponse], error)
 	// GetSessionStats retrieves statistics for a specific session.
 	GetSessionStats(context.Context, *connect.Request[v1.GetSessionStatsRequest]) (*connect.Response[v1.SessionStats], error)
 	// ListSessionStats retrieves session statistics with pagination.
@@ -204,8 +281,16 @@ type AIServiceClient interface {
 	SetUserCostSettings(context.Context, *connect.Request[v1.SetUserCostSettingsRequest]) (*connect.Response[v1.UserCostSettings], error)
 	// ListBlocks retrieves blocks for a conversation.
 	ListBlocks(context.Context, *connect.Request[v1.ListBlocksRequest]) (*connect.Response[v1.ListBlocksResponse], error)
+	// GetBlockChanges retrieves the blocks of a conversation created or updated
+	// since a sync, and of existing blocks only the events appended since then.
+	// Clients poll it in place of refetching the conversation.
+	GetBlockChanges(context.Context, *connect.Request[v1.GetBlockChangesRequest]) (*connect.Response[v1.GetBlockChangesResponse], error)
 	// GetBlock retrieves a specific block.
 	GetBlock(context.Context, *connect.Request[v1.GetBlockRequest]) (*connect.Response[v1.Block], error)
+	// GetBlockTranscript retrieves the compact transcript of a block, collapsed
+	// from its raw event stream, for clients that do not render event streams
+	// and for exports.
+	GetBlockTranscript(context.Context, *connect.Request[v1.GetBlockTranscriptRequest]) (*connect.Response[v1.BlockTranscript], error)
 	// CreateBlock creates a new conversation block.
 	CreateBlock(context.Context, *connect.Request[v1.CreateBlockRequest]) (*connect.Response[v1.Block], error)
 	// UpdateBlock updates a block.
@@ -229,6 +314,59 @@ type AIServiceClient interface {
 	// DeleteBranch deletes a block and all its descendants.
 	// Used for pruning unwanted conversation branches.
 	DeleteBranch(context.Context, *connect.Request[v1.DeleteBranchRequest]) (*connect.Response[emptypb.Empty], error)
+	// GetUsage retrieves the user's AI usage of the current month against the quotas.
+	GetUsage(context.Context, *connect.Request[v1.GetUsageRequest]) (*connect.Response[v1.Usage], error)
+	// RunSelfTest runs a tiny chat end to end in a throwaway conversation and
+	// reports each check. Admin only.
+	RunSelfTest(context.Context, *connect.Request[v1.RunSelfTestRequest]) (*connect.Response[v1.SelfTestReport], error)
+	// ReindexAll embeds all the memos again in the background. Admin only.
+	ReindexAll(context.Context, *connect.Request[v1.ReindexAllRequest]) (*connect.Response[v1.ReindexProgress], error)
+	// ReindexSince embeds again the memos updated at or after a time in the
+	// background. Admin only.
+	ReindexSince(context.Context, *connect.Request[v1.ReindexSinceRequest]) (*connect.Response[v1.ReindexProgress], error)
+	// GetReindexProgress retrieves the progress of the latest reindex. Admin only.
+	GetReindexProgress(context.Context, *connect.Request[v1.GetReindexProgressRequest]) (*connect.Response[v1.ReindexProgress], error)
+	// ListMCPServers lists the MCP servers the user registered for Geek Mode.
+	ListMCPServers(context.Context, *connect.Request[v1.ListMCPServersRequest]) (*connect.Response[v1.ListMCPServersResponse], error)
+	// CreateMCPServer registers an MCP server for the Geek Mode sessions of the
+	// user. Sessions pick it up at their next request; stdio servers only in
+	// workspaces whose tool policy permits Bash.
+	CreateMCPServer(context.Context, *connect.Request[v1.CreateMCPServerRequest]) (*connect.Response[v1.MCPServer], error)
+	// UpdateMCPServer replaces an MCP server of the user.
+	UpdateMCPServer(context.Context, *connect.Request[v1.UpdateMCPServerRequest]) (*connect.Response[v1.MCPServer], error)
+	// DeleteMCPServer removes an MCP server of the user.
+	DeleteMCPServer(context.Context, *connect.Request[v1.DeleteMCPServerRequest]) (*connect.Response[emptypb.Empty], error)
+	// ListActionAudit lists the tool calls of the Geek and Evolution Mode
+	// sessions, latest first. Admin only.
+	ListActionAudit(context.Context, *connect.Request[v1.ListActionAuditRequest]) (*connect.Response[v1.ListActionAuditResponse], error)
+	// ExportActionAudit exports the matching tool calls, latest first, as CSV or
+	// JSON Lines. Admin only.
+	ExportActionAudit(context.Context, *connect.Request[v1.ExportActionAuditRequest]) (*connect.Response[v1.ExportActionAuditResponse], error)
+	// ListAgentModels lists the configured LLM providers, the default one first,
+	// and the provider and model of each agent type. Admin only.
+	ListAgentModels(context.Context, *connect.Request[v1.ListAgentModelsRequest]) (*connect.Response[v1.ListAgentModelsResponse], error)
+	// UpdateAgentModel selects the provider and model of an agent type; its next
+	// LLM calls use them. Admin only.
+	UpdateAgentModel(context.Context, *connect.Request[v1.UpdateAgentModelRequest]) (*connect.Response[v1.AgentModel], error)
+	// DeleteAgentModel removes the selection of an agent type, which returns to
+	// its DIVINESENSE_AI_AGENT_MODELS selection, or to the default provider.
+	// Admin only.
+	DeleteAgentModel(context.Context, *connect.Request[v1.DeleteAgentModelRequest]) (*connect.Response[emptypb.Empty], error)
+	// GetGeekWorkspaceUsage returns the disk usage of the Geek Mode workspace of
+	// the user and its limit.
+	GetGeekWorkspaceUsage(context.Context, *connect.Request[v1.GetGeekWorkspaceUsageRequest]) (*connect.Response[v1.GeekWorkspaceUsage], error)
+	// ListGeekWorkspaceFiles lists a directory of the Geek Mode workspace of the
+	// user, where the sessions leave their artifacts.
+	ListGeekWorkspaceFiles(context.Context, *connect.Request[v1.ListGeekWorkspaceFilesRequest]) (*connect.Response[v1.ListGeekWorkspaceFilesResponse], error)
+	// ReadGeekWorkspaceFile returns a file of the workspace with its text
+	// content, up to 1 MiB; binary files have none.
+	ReadGeekWorkspaceFile(context.Context, *connect.Request[v1.ReadGeekWorkspaceFileRequest]) (*connect.Response[v1.GeekWorkspaceFileContent], error)
+	// DownloadGeekWorkspaceFile returns a file of the workspace, up to 64 MiB,
+	// to save as an attachment.
+	DownloadGeekWorkspaceFile(context.Context, *connect.Request[v1.DownloadGeekWorkspaceFileRequest]) (*connect.Response[v1.DownloadGeekWorkspaceFileResponse], error)
+	// DeleteGeekWorkspaceFile removes a file of the workspace, or a directory
+	// with its content.
+	DeleteGeekWorkspaceFile(context.Context, *connect.Request[v1.DeleteGeekWorkspaceFileRequest]) (*connect.Response[emptypb.Empty], error)
 }
 
 // NewAIServiceClient constructs a client for the memos.api.v1.AIService service. By default, it
@@ -368,6 +506,12 @@ func NewAIServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...c
 			connect.WithSchema(aIServiceMethods.ByName("GenerateConversationTitle")),
 			connect.WithClientOptions(opts...),
 		),
+		importConversation: connect.NewClient[v1.ImportConversationRequest, v1.ImportConversationResponse](
+			httpClient,
+			baseURL+AIServiceImportConversationProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("ImportConversation")),
+			connect.WithClientOptions(opts...),
+		),
 		deleteAIConversation: connect.NewClient[v1.DeleteAIConversationRequest, emptypb.Empty](
 			httpClient,
 			baseURL+AIServiceDeleteAIConversationProcedure,
@@ -386,12 +530,24 @@ func NewAIServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...c
 			connect.WithSchema(aIServiceMethods.ByName("ClearConversationMessages")),
 			connect.WithClientOptions(opts...),
 		),
+		createChatStreamTicket: connect.NewClient[v1.CreateChatStreamTicketRequest, v1.ChatStreamTicket](
+			httpClient,
+			baseURL+AIServiceCreateChatStreamTicketProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("CreateChatStreamTicket")),
+			connect.WithClientOptions(opts...),
+		),
 		stopChat: connect.NewClient[v1.StopChatRequest, emptypb.Empty](
 			httpClient,
 			baseURL+AIServiceStopChatProcedure,
 			connect.WithSchema(aIServiceMethods.ByName("StopChat")),
 			connect.WithClientOptions(opts...),
 		),
+		submitForm: connect.NewClient[v1.SubmitFormRequest, v1.ChatResponse](
+			httpClient,
+			baseURL+AIServiceSubmitFormProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("SubmitForm")),
+			connect.WithClientOptions(opts...),
+		),
 		getSessionStats: connect.NewClient[v1.GetSessionStatsRequest, v1.SessionStats](
 			httpClient,
 			baseURL+AIServiceGetSessionStatsProcedure,
@@ -428,12 +584,24 @@ func NewAIServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...c
 			connect.WithSchema(aIServiceMethods.ByName("ListBlocks")),
 			connect.WithClientOptions(opts...),
 		),
+		getBlockChanges: connect.NewClient[v1.GetBlockChangesRequest, v1.GetBlockChangesResponse](
+			httpClient,
+			baseURL+AIServiceGetBlockChangesProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("GetBlockChanges")),
+			connect.WithClientOptions(opts...),
+		),
 		getBlock: connect.NewClient[v1.GetBlockRequest, v1.Block](
 			httpClient,
 			baseURL+AIServiceGetBlockProcedure,
 			connect.WithSchema(aIServiceMethods.ByName("GetBlock")),
 			connect.WithClientOptions(opts...),
 		),
+		getBlockTranscript: connect.NewClient[v1.GetBlockTranscriptRequest, v1.BlockTranscript](
+			httpClient,
+			baseURL+AIServiceGetBlockTranscriptProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("GetBlockTranscript")),
+			connect.WithClientOptions(opts...),
+		),
 		createBlock: connect.NewClient[v1.CreateBlockRequest, v1.Block](
 			httpClient,
 			baseURL+AIServiceCreateBlockProcedure,
@@ -488,6 +656,120 @@ func NewAIServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...c
 			connect.WithSchema(aIServiceMethods.ByName("DeleteBranch")),
 			connect.WithClientOptions(opts...),
 		),
+		getUsage: connect.NewClient[v1.GetUsageRequest, v1.Usage](
+			httpClient,
+			baseURL+AIServiceGetUsageProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("GetUsage")),
+			connect.WithClientOptions(opts...),
+		),
+		runSelfTest: connect.NewClient[v1.RunSelfTestRequest, v1.SelfTestReport](
+			httpClient,
+			baseURL+AIServiceRunSelfTestProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("RunSelfTest")),
+			connect.WithClientOptions(opts...),
+		),
+		reindexAll: connect.NewClient[v1.ReindexAllRequest, v1.ReindexProgress](
+			httpClient,
+			baseURL+AIServiceReindexAllProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("ReindexAll")),
+			connect.WithClientOptions(opts...),
+		),
+		reindexSince: connect.NewClient[v1.ReindexSinceRequest, v1.ReindexProgress](
+			httpClient,
+			baseURL+AIServiceReindexSinceProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("ReindexSince")),
+			connect.WithClientOptions(opts...),
+		),
+		getReindexProgress: connect.NewClient[v1.GetReindexProgressRequest, v1.ReindexProgress](
+			httpClient,
+			baseURL+AIServiceGetReindexProgressProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("GetReindexProgress")),
+			connect.WithClientOptions(opts...),
+		),
+		listMCPServers: connect.NewClient[v1.ListMCPServersRequest, v1.ListMCPServersResponse](
+			httpClient,
+			baseURL+AIServiceListMCPServersProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("ListMCPServers")),
+			connect.WithClientOptions(opts...),
+		),
+		createMCPServer: connect.NewClient[v1.CreateMCPServerRequest, v1.MCPServer](
+			httpClient,
+			baseURL+AIServiceCreateMCPServerProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("CreateMCPServer")),
+			connect.WithClientOptions(opts...),
+		),
+		updateMCPServer: connect.NewClient[v1.UpdateMCPServerRequest, v1.MCPServer](
+			httpClient,
+			baseURL+AIServiceUpdateMCPServerProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("UpdateMCPServer")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteMCPServer: connect.NewClient[v1.DeleteMCPServerRequest, emptypb.Empty](
+			httpClient,
+			baseURL+AIServiceDeleteMCPServerProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("DeleteMCPServer")),
+			connect.WithClientOptions(opts...),
+		),
+		listActionAudit: connect.NewClient[v1.ListActionAuditRequest, v1.ListActionAuditResponse](
+			httpClient,
+			baseURL+AIServiceListActionAuditProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("ListActionAudit")),
+			connect.WithClientOptions(opts...),
+		),
+		exportActionAudit: connect.NewClient[v1.ExportActionAuditRequest, v1.ExportActionAuditResponse](
+			httpClient,
+			baseURL+AIServiceExportActionAuditProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("ExportActionAudit")),
+			connect.WithClientOptions(opts...),
+		),
+		listAgentModels: connect.NewClient[v1.ListAgentModelsRequest, v1.ListAgentModelsResponse](
+			httpClient,
+			baseURL+AIServiceListAgentModelsProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("ListAgentModels")),
+			connect.WithClientOptions(opts...),
+		),
+		updateAgentModel: connect.NewClient[v1.UpdateAgentModelRequest, v1.AgentModel](
+			httpClient,
+			baseURL+AIServiceUpdateAgentModelProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("UpdateAgentModel")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteAgentModel: connect.NewClient[v1.DeleteAgentModelRequest, emptypb.Empty](
+			httpClient,
+			baseURL+AIServiceDeleteAgentModelProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("DeleteAgentModel")),
+			connect.WithClientOptions(opts...),
+		),
+		getGeekWorkspaceUsage: connect.NewClient[v1.GetGeekWorkspaceUsageRequest, v1.GeekWorkspaceUsage](
+			httpClient,
+			baseURL+AIServiceGetGeekWorkspaceUsageProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("GetGeekWorkspaceUsage")),
+			connect.WithClientOptions(opts...),
+		),
+		listGeekWorkspaceFiles: connect.NewClient[v1.ListGeekWorkspaceFilesRequest, v1.ListGeekWorkspaceFilesResponse](
+			httpClient,
+			baseURL+AIServiceListGeekWorkspaceFilesProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("ListGeekWorkspaceFiles")),
+			connect.WithClientOptions(opts...),
+		),
+		readGeekWorkspaceFile: connect.NewClient[v1.ReadGeekWorkspaceFileRequest, v1.GeekWorkspaceFileContent](
+			httpClient,
+			baseURL+AIServiceReadGeekWorkspaceFileProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("ReadGeekWorkspaceFile")),
+			connect.WithClientOptions(opts...),
+		),
+		downloadGeekWorkspaceFile: connect.NewClient[v1.DownloadGeekWorkspaceFileRequest, v1.DownloadGeekWorkspaceFileResponse](
+			httpClient,
+			baseURL+AIServiceDownloadGeekWorkspaceFileProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("DownloadGeekWorkspaceFile")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteGeekWorkspaceFile: connect.NewClient[v1.DeleteGeekWorkspaceFileRequest, emptypb.Empty](
+			httpClient,
+			baseURL+AIServiceDeleteGeekWorkspaceFileProcedure,
+			connect.WithSchema(aIServiceMethods.ByName("DeleteGeekWorkspaceFile")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
@@ -514,17 +796,22 @@ type aIServiceClient struct {
 	createAIConversation      *connect.Client[v1.CreateAIConversationRequest, v1.AIConversation]
 	updateAIConversation      *connect.Client[v1.UpdateAIConversationRequest, v1.AIConversation]
 	generateConversationTitle *connect.Client[v1.GenerateConversationTitleRequest, v1.GenerateConversationTitleResponse]
+	importConversation        *connect.Client[v1.ImportConversationRequest, v1.ImportConversationResponse]
 	deleteAIConversation      *connect.Client[v1.DeleteAIConversationRequest, emptypb.Empty]
 	addContextSeparator       *connect.Client[v1.AddContextSeparatorRequest, emptypb.Empty]
 	clearConversationMessages *connect.Client[v1.ClearConversationMessagesRequest, emptypb.Empty]
+	createChatStreamTicket    *connect.Client[v1.CreateChatStreamTicketRequest, v1.ChatStreamTicket]
 	stopChat                  *connect.Client[v1.StopChatRequest, emptypb.Empty]
+	submitForm                *connect.Client[v1.SubmitFormRequest, v1.ChatResponse]
 	getSessionStats           *connect.Client[v1.GetSessionStatsRequest, v1.SessionStats]
 	listSessionStats          *connect.Client[v1.ListSessionStatsRequest, v1.ListSessionStatsResponse]
 	getCostStats              *connect.Client[v1.GetCostStatsRequest, v1.CostStats]
 	getUserCostSettings       *connect.Client[emptypb.Empty, v1.UserCostSettings]
 	setUserCostSettings       *connect.Client[v1.SetUserCostSettingsRequest, v1.UserCostSettings]
 	listBlocks                *connect.Client[v1.ListBlocksRequest, v1.ListBlocksResponse]
+	getBlockChanges           *connect.Client[v1.GetBlockChangesRequest, v1.GetBlockChangesResponse]
 	getBlock                  *connect.Client[v1.GetBlockRequest, v1.Block]
+	getBlockTranscript        *connect.Client[v1.GetBlockTranscriptRequest, v1.BlockTranscript]
 	createBlock               *connect.Client[v1.CreateBlockRequest, v1.Block]
 	updateBlock               *connect.Client[v1.UpdateBlockRequest, v1.Block]
 	deleteBlock               *connect.Client[v1.DeleteBlockRequest, emptypb.Empty]
@@ -534,6 +821,25 @@ type aIServiceClient struct {
 	listBlockBranches         *connect.Client[v1.ListBlockBranchesRequest, v1.ListBlockBranchesResponse]
 	switchBranch              *connect.Client[v1.SwitchBranchRequest, emptypb.Empty]
 	deleteBranch              *connect.Client[v1.DeleteBranchRequest, emptypb.Empty]
+	getUsage                  *connect.Client[v1.GetUsageRequest, v1.Usage]
+	runSelfTest               *connect.Client[v1.RunSelfTestRequest, v1.SelfTestReport]
+	reindexAll                *connect.Client[v1.ReindexAllRequest, v1.ReindexProgress]
+	reindexSince              *connect.Client[v1.ReindexSinceRequest, v1.ReindexProgress]
+	getReindexProgress        *connect.Client[v1.GetReindexProgressRequest, v1.ReindexProgress]
+	listMCPServers            *connect.Client[v1.ListMCPServersRequest, v1.ListMCPServersResponse]
+	createMCPServer           *connect.Client[v1.CreateMCPServerRequest, v1.MCPServer]
+	updateMCPServer           *connect.Client[v1.UpdateMCPServerRequest, v1.MCPServer]
+	deleteMCPServer           *connect.Client[v1.DeleteMCPServerRequest, emptypb.Empty]
+	listActionAudit           *connect.Client[v1.ListActionAuditRequest, v1.ListActionAuditResponse]
+	exportActionAudit         *connect.Client[v1.ExportActionAuditRequest, v1.ExportActionAuditResponse]
+	listAgentModels           *connect.Client[v1.ListAgentModelsRequest, v1.ListAgentModelsResponse]
+	updateAgentModel          *connect.Client[v1.UpdateAgentModelRequest, v1.AgentModel]
+	deleteAgentModel          *connect.Client[v1.DeleteAgentModelRequest, emptypb.Empty]
+	getGeekWorkspaceUsage     *connect.Client[v1.GetGeekWorkspaceUsageRequest, v1.GeekWorkspaceUsage]
+	listGeekWorkspaceFiles    *connect.Client[v1.ListGeekWorkspaceFilesRequest, v1.ListGeekWorkspaceFilesResponse]
+	readGeekWorkspaceFile     *connect.Client[v1.ReadGeekWorkspaceFileRequest, v1.GeekWorkspaceFileContent]
+	downloadGeekWorkspaceFile *connect.Client[v1.DownloadGeekWorkspaceFileRequest, v1.DownloadGeekWorkspaceFileResponse]
+	deleteGeekWorkspaceFile   *connect.Client[v1.DeleteGeekWorkspaceFileRequest, emptypb.Empty]
 }
 
 // SemanticSearch calls memos.api.v1.AIService.SemanticSearch.
@@ -641,6 +947,11 @@ func (c *aIServiceClient) GenerateConversationTitle(ctx context.Context, req *co
 	return c.generateConversationTitle.CallUnary(ctx, req)
 }
 
+// ImportConversation calls memos.api.v1.AIService.ImportConversation.
+func (c *aIServiceClient) ImportConversation(ctx context.Context, req *connect.Request[v1.ImportConversationRequest]) (*connect.Response[v1.ImportConversationResponse], error) {
+	return c.importConversation.CallUnary(ctx, req)
+}
+
 // DeleteAIConversation calls memos.api.v1.AIService.DeleteAIConversation.
 func (c *aIServiceClient) DeleteAIConversation(ctx context.Context, req *connect.Request[v1.DeleteAIConversationRequest]) (*connect.Response[emptypb.Empty], error) {
 	return c.deleteAIConversation.CallUnary(ctx, req)
@@ -656,11 +967,21 @@ func (c *aIServiceClient) ClearConversationMessages(ctx context.Context, req *co
 	return c.clearConversationMessages.CallUnary(ctx, req)
 }
 
+// CreateChatStreamTicket calls memos.api.v1.AIService.CreateChatStreamTicket.
+func (c *aIServiceClient) CreateChatStreamTicket(ctx context.Context, req *connect.Request[v1.CreateChatStreamTicketRequest]) (*connect.Response[v1.ChatStreamTicket], error) {
+	return c.createChatStreamTicket.CallUnary(ctx, req)
+}
+
 // StopChat calls memos.api.v1.AIService.StopChat.
 func (c *aIServiceClient) StopChat(ctx context.Context, req *connect.Request[v1.StopChatRequest]) (*connect.Response[emptypb.Empty], error) {
 	return c.stopChat.CallUnary(ctx, req)
 }
 
+// SubmitForm calls memos.api.v1.AIService.SubmitForm.
+func (c *aIServiceClient) SubmitForm(ctx context.Context, req *connect.Request[v1.SubmitFormRequest]) (*connect.ServerStreamForClient[v1.ChatResponse], error) {
+	return c.submitForm.CallServerStream(ctx, req)
+}
+
 // GetSessionStats calls memos.api.v1.AIService.GetSessionStats.
 func (c *aIServiceClient) GetSessionStats(ctx context.Context, req *connect.Request[v1.GetSessionStatsRequest]) (*connect.Response[v1.SessionStats], error) {
 	return c.getSessionStats.CallUnary(ctx, req)
@@ -691,11 +1012,21 @@ func (c *aIServiceClient) ListBlocks(ctx context.Context, req *connect.Request[v
 	return c.listBlocks.CallUnary(ctx, req)
 }
 
+// GetBlockChanges calls memos.api.v1.AIService.GetBlockChanges.
+func (c *aIServiceClient) GetBlockChanges(ctx context.Context, req *connect.Request[v1.GetBlockChangesRequest]) (*connect.Response[v1.GetBlockChangesResponse], error) {
+	return c.getBlockChanges.CallUnary(ctx, req)
+}
+
 // GetBlock calls memos.api.v1.AIService.GetBlock.
 func (c *aIServiceClient) GetBlock(ctx context.Context, req *connect.Request[v1.GetBlockRequest]) (*connect.Response[v1.Block], error) {
 	return c.getBlock.CallUnary(ctx, req)
 }
 
+// GetBlockTranscript calls memos.api.v1.AIService.GetBlockTranscript.
+func (c *aIServiceClient) GetBlockTranscript(ctx context.Context, req *connect.Request[v1.GetBlockTranscriptRequest]) (*connect.Response[v1.BlockTranscript], error) {
+	return c.getBlockTranscript.CallUnary(ctx, req)
+}
+
 // CreateBlock calls memos.api.v1.AIService.CreateBlock.
 func (c *aIServiceClient) CreateBlock(ctx context.Context, req *connect.Request[v1.CreateBlockRequest]) (*connect.Response[v1.Block], error) {
 	return c.createBlock.CallUnary(ctx, req)
@@ -741,6 +1072,101 @@ func (c *aIServiceClient) DeleteBranch(ctx context.Context, req *connect.Request
 	return c.deleteBranch.CallUnary(ctx, req)
 }
 
+// GetUsage calls memos.api.v1.AIService.GetUsage.
+func (c *aIServiceClient) GetUsage(ctx context.Context, req *connect.Request[v1.GetUsageRequest]) (*connect.Response[v1.Usage], error) {
+	return c.getUsage.CallUnary(ctx, req)
+}
+
+// RunSelfTest calls memos.api.v1.AIService.RunSelfTest.
+func (c *aIServiceClient) RunSelfTest(ctx context.Context, req *connect.Request[v1.RunSelfTestRequest]) (*connect.Response[v1.SelfTestReport], error) {
+	return c.runSelfTest.CallUnary(ctx, req)
+}
+
+// ReindexAll calls memos.api.v1.AIService.ReindexAll.
+func (c *aIServiceClient) ReindexAll(ctx context.Context, req *connect.Request[v1.ReindexAllRequest]) (*connect.Response[v1.ReindexProgress], error) {
+	return c.reindexAll.CallUnary(ctx, req)
+}
+
+// ReindexSince calls memos.api.v1.AIService.ReindexSince.
+func (c *aIServiceClient) ReindexSince(ctx context.Context, req *connect.Request[v1.ReindexSinceRequest]) (*connect.Response[v1.ReindexProgress], error) {
+	return c.reindexSince.CallUnary(ctx, req)
+}
+
+// GetReindexProgress calls memos.api.v1.AIService.GetReindexProgress.
+func (c *aIServiceClient) GetReindexProgress(ctx context.Context, req *connect.Request[v1.GetReindexProgressRequest]) (*connect.Response[v1.ReindexProgress], error) {
+	return c.getReindexProgress.CallUnary(ctx, req)
+}
+
+// ListMCPServers calls memos.api.v1.AIService.ListMCPServers.
+func (c *aIServiceClient) ListMCPServers(ctx context.Context, req *connect.Request[v1.ListMCPServersRequest]) (*connect.Response[v1.ListMCPServersResponse], error) {
+	return c.listMCPServers.CallUnary(ctx, req)
+}
+
+// CreateMCPServer calls memos.api.v1.AIService.CreateMCPServer.
+func (c *aIServiceClient) CreateMCPServer(ctx context.Context, req *connect.Request[v1.CreateMCPServerRequest]) (*connect.Response[v1.MCPServer], error) {
+	return c.createMCPServer.CallUnary(ctx, req)
+}
+
+// UpdateMCPServer calls memos.api.v1.AIService.UpdateMCPServer.
+func (c *aIServiceClient) UpdateMCPServer(ctx context.Context, req *connect.Request[v1.UpdateMCPServerRequest]) (*connect.Response[v1.MCPServer], error) {
+	return c.updateMCPServer.CallUnary(ctx, req)
+}
+
+// DeleteMCPServer calls memos.api.v1.AIService.DeleteMCPServer.
+func (c *aIServiceClient) DeleteMCPServer(ctx context.Context, req *connect.Request[v1.DeleteMCPServerRequest]) (*connect.Response[emptypb.Empty], error) {
+	return c.deleteMCPServer.CallUnary(ctx, req)
+}
+
+// ListActionAudit calls memos.api.v1.AIService.ListActionAudit.
+func (c *aIServiceClient) ListActionAudit(ctx context.Context, req *connect.Request[v1.ListActionAuditRequest]) (*connect.Response[v1.ListActionAuditResponse], error) {
+	return c.listActionAudit.CallUnary(ctx, req)
+}
+
+// ExportActionAudit calls memos.api.v1.AIService.ExportActionAudit.
+func (c *aIServiceClient) ExportActionAudit(ctx context.Context, req *connect.Request[v1.ExportActionAuditRequest]) (*connect.Response[v1.ExportActionAuditResponse], error) {
+	return c.exportActionAudit.CallUnary(ctx, req)
+}
+
+// ListAgentModels calls memos.api.v1.AIService.ListAgentModels.
+func (c *aIServiceClient) ListAgentModels(ctx context.Context, req *connect.Request[v1.ListAgentModelsRequest]) (*connect.Response[v1.ListAgentModelsResponse], error) {
+	return c.listAgentModels.CallUnary(ctx, req)
+}
+
+// UpdateAgentModel calls memos.api.v1.AIService.UpdateAgentModel.
+func (c *aIServiceClient) UpdateAgentModel(ctx context.Context, req *connect.Request[v1.UpdateAgentModelRequest]) (*connect.Response[v1.AgentModel], error) {
+	return c.updateAgentModel.CallUnary(ctx, req)
+}
+
+// DeleteAgentModel calls memos.api.v1.AIService.DeleteAgentModel.
+func (c *aIServiceClient) DeleteAgentModel(ctx context.Context, req *connect.Request[v1.DeleteAgentModelRequest]) (*connect.Response[emptypb.Empty], error) {
+	return c.deleteAgentModel.CallUnary(ctx, req)
+}
+
+// GetGeekWorkspaceUsage calls memos.api.v1.AIService.GetGeekWorkspaceUsage.
+func (c *aIServiceClient) GetGeekWorkspaceUsage(ctx context.Context, req *connect.Request[v1.GetGeekWorkspaceUsageRequest]) (*connect.Response[v1.GeekWorkspaceUsage], error) {
+	return c.getGeekWorkspaceUsage.CallUnary(ctx, req)
+}
+
+// ListGeekWorkspaceFiles calls memos.api.v1.AIService.ListGeekWorkspaceFiles.
+func (c *aIServiceClient) ListGeekWorkspaceFiles(ctx context.Context, req *connect.Request[v1.ListGeekWorkspaceFilesRequest]) (*connect.Response[v1.ListGeekWorkspaceFilesResponse], error) {
+	return c.listGeekWorkspaceFiles.CallUnary(ctx, req)
+}
+
+// ReadGeekWorkspaceFile calls memos.api.v1.AIService.ReadGeekWorkspaceFile.
+func (c *aIServiceClient) ReadGeekWorkspaceFile(ctx context.Context, req *connect.Request[v1.ReadGeekWorkspaceFileRequest]) (*connect.Response[v1.GeekWorkspaceFileContent], error) {
+	return c.readGeekWorkspaceFile.CallUnary(ctx, req)
+}
+
+// DownloadGeekWorkspaceFile calls memos.api.v1.AIService.DownloadGeekWorkspaceFile.
+func (c *aIServiceClient) DownloadGeekWorkspaceFile(ctx context.Context, req *connect.Request[v1.DownloadGeekWorkspaceFileRequest]) (*connect.Response[v1.DownloadGeekWorkspaceFileResponse], error) {
+	return c.downloadGeekWorkspaceFile.CallUnary(ctx, req)
+}
+
+// DeleteGeekWorkspaceFile calls memos.api.v1.AIService.DeleteGeekWorkspaceFile.
+func (c *aIServiceClient) DeleteGeekWorkspaceFile(ctx context.Context, req *connect.Request[v1.DeleteGeekWorkspaceFileRequest]) (*connect.Response[emptypb.Empty], error) {
+	return c.deleteGeekWorkspaceFile.CallUnary(ctx, req)
+}
+
 // AIServiceHandler is an implementation of the memos.api.v1.AIService service.
 type AIServiceHandler interface {
 	// SemanticSearch performs semantic search on memos.
@@ -786,14 +1212,24 @@ type AIServiceHandler interface {
 	UpdateAIConversation(context.Context, *connect.Request[v1.UpdateAIConversationRequest]) (*connect.Response[v1.AIConversation], error)
 	// GenerateConversationTitle generates a meaningful title for a conversation using AI.
 	GenerateConversationTitle(context.Context, *connect.Request[v1.GenerateConversationTitleRequest]) (*connect.Response[v1.GenerateConversationTitleResponse], error)
+	// ImportConversation imports the conversations of a ChatGPT or Claude data
+	// export as conversations of the user, with a block per round.
+	ImportConversation(context.Context, *connect.Request[v1.ImportConversationRequest]) (*connect.Response[v1.ImportConversationResponse], error)
 	// DeleteAIConversation deletes an AI conversation.
 	DeleteAIConversation(context.Context, *connect.Request[v1.DeleteAIConversationRequest]) (*connect.Response[emptypb.Empty], error)
 	// AddContextSeparator adds a context separator marker to a conversation.
 	AddContextSeparator(context.Context, *connect.Request[v1.AddContextSeparatorRequest]) (*connect.Response[emptypb.Empty], error)
 	// ClearConversationMessages deletes all messages in a conversation.
 	ClearConversationMessages(context.Context, *connect.Request[v1.ClearConversationMessagesRequest]) (*connect.Response[emptypb.Empty], error)
+	// CreateChatStreamTicket issues a short-lived, single-use ticket
+	// authenticating a GET /api/v1/ai/chat/stream request, for the EventSource
+	// clients which cannot set the Authorization header.
+	CreateChatStreamTicket(context.Context, *connect.Request[v1.CreateChatStreamTicketRequest]) (*connect.Response[v1.ChatStreamTicket], error)
 	// StopChat cancels an ongoing chat stream and terminates the associated session.
 	StopChat(context.Context, *connect.Request[v1.StopChatRequest]) (*connect.Response[emptypb.Empty], error)
+	// SubmitForm answers the form a block is waiting on and continues the agent
+	// in the same block, streaming the follow-up like Chat.
+	SubmitForm(context.Context, *connect.Request[v1.SubmitFormRequest], *connect.ServerStream[v1.ChatResponse]) error
 	// GetSessionStats retrieves statistics for a specific session.
 	GetSessionStats(context.Context, *connect.Request[v1.GetSessionStatsRequest]) (*connect.Response[v1.SessionStats], error)
 	// ListSessionStats retrieves session statistics with pagination.
@@ -806,8 +1242,16 @@ type AIServiceHandler interface {
 	SetUserCostSettings(context.Context, *connect.Request[v1.SetUserCostSettingsRequest]) (*connect.Response[v1.UserCostSettings], error)
 	// ListBlocks retrieves blocks for a conversation.
 	ListBlocks(context.Context, *connect.Request[v1.ListBlocksRequest]) (*connect.Response[v1.ListBlocksResponse], error)
+	// GetBlockChanges retrieves the blocks of a conversation created or updated
+	// since a sync, and of existing blocks only the events appended since then.
+	// Clients poll it in place of refetching the conversation.
+	GetBlockChanges(context.Context, *connect.Request[v1.GetBlockChangesRequest]) (*connect.Response[v1.GetBlockChangesResponse], error)
 	// GetBlock retrieves a specific block.
 	GetBlock(context.Context, *connect.Request[v1.GetBlockRequest]) (*connect.Response[v1.Block], error)
+	// GetBlockTranscript retrieves the compact transcript of a block, collapsed
+	// from its raw event stream, for clients that do not render event streams
+	// and for exports.
+	GetBlockTranscript(context.Context, *connect.Request[v1.GetBlockTranscriptRequest]) (*connect.Response[v1.BlockTranscript], error)
 	// CreateBlock creates a new conversation block.
 	CreateBlock(context.Context, *connect.Request[v1.CreateBlockRequest]) (*connect.Response[v1.Block], error)
 	// UpdateBlock updates a block.
@@ -831,6 +1275,59 @@ type AIServiceHandler interface {
 	// DeleteBranch deletes a block and all its descendants.
 	// Used for pruning unwanted conversation branches.
 	DeleteBranch(context.Context, *connect.Request[v1.DeleteBranchRequest]) (*connect.Response[emptypb.Empty], error)
+	// GetUsage retrieves the user's AI usage of the current month against the quotas.
+	GetUsage(context.Context, *connect.Request[v1.GetUsageRequest]) (*connect.Response[v1.Usage], error)
+	// RunSelfTest runs a tiny chat end to end in a throwaway conversation and
+	// reports each check. Admin only.
+	RunSelfTest(context.Context, *connect.Request[v1.RunSelfTestRequest]) (*connect.Response[v1.SelfTestReport], error)
+	// ReindexAll embeds all the memos again in the background. Admin only.
+	ReindexAll(context.Context, *connect.Request[v1.ReindexAllRequest]) (*connect.Response[v1.ReindexProgress], error)
+	// ReindexSince embeds again the memos updated at or after a time in the
+	// background. Admin only.
+	ReindexSince(context.Context, *connect.Request[v1.ReindexSinceRequest]) (*connect.Response[v1.ReindexProgress], error)
+	// GetReindexProgress retrieves the progress of the latest reindex. Admin only.
+	GetReindexProgress(context.Context, *connect.Request[v1.GetReindexProgressRequest]) (*connect.Response[v1.ReindexProgress], error)
+	// ListMCPServers lists the MCP servers the user registered for Geek Mode.
+	ListMCPServers(context.Context, *connect.Request[v1.ListMCPServersRequest]) (*connect.Response[v1.ListMCPServersResponse], error)
+	// CreateMCPServer registers an MCP server for the Geek Mode sessions of the
+	// user. Sessions pick it up at their next request; stdio servers only in
+	// workspaces whose tool policy permits Bash.
+	CreateMCPServer(context.Context, *connect.Request[v1.CreateMCPServerRequest]) (*connect.Response[v1.MCPServer], error)
+	// UpdateMCPServer replaces an MCP server of the user.
+	UpdateMCPServer(context.Context, *connect.Request[v1.UpdateMCPServerRequest]) (*connect.Response[v1.MCPServer], error)
+	// DeleteMCPServer removes an MCP server of the user.
+	DeleteMCPServer(context.Context, *connect.Request[v1.DeleteMCPServerRequest]) (*connect.Response[emptypb.Empty], error)
+	// ListActionAudit lists the tool calls of the Geek and Evolution Mode
+	// sessions, latest first. Admin only.
+	ListActionAudit(context.Context, *connect.Request[v1.ListActionAuditRequest]) (*connect.Response[v1.ListActionAuditResponse], error)
+	// ExportActionAudit exports the matching tool calls, latest first, as CSV or
+	// JSON Lines. Admin only.
+	ExportActionAudit(context.Context, *connect.Request[v1.ExportActionAuditRequest]) (*connect.Response[v1.ExportActionAuditResponse], error)
+	// ListAgentModels lists the configured LLM providers, the default one first,
+	// and the provider and model of each agent type. Admin only.
+	ListAgentModels(context.Context, *connect.Request[v1.ListAgentModelsRequest]) (*connect.Response[v1.ListAgentModelsResponse], error)
+	// UpdateAgentModel selects the provider and model of an agent type; its next
+	// LLM calls use them. Admin only.
+	UpdateAgentModel(context.Context, *connect.Request[v1.UpdateAgentModelRequest]) (*connect.Response[v1.AgentModel], error)
+	// DeleteAgentModel removes the selection of an agent type, which returns to
+	// its DIVINESENSE_AI_AGENT_MODELS selection, or to the default provider.
+	// Admin only.
+	DeleteAgentModel(context.Context, *connect.Request[v1.DeleteAgentModelRequest]) (*connect.Response[emptypb.Empty], error)
+	// GetGeekWorkspaceUsage returns the disk usage of the Geek Mode workspace of
+	// the user and its limit.
+	GetGeekWorkspaceUsage(context.Context, *connect.Request[v1.GetGeekWorkspaceUsageRequest]) (*connect.Response[v1.GeekWorkspaceUsage], error)
+	// ListGeekWorkspaceFiles lists a directory of the Geek Mode workspace of the
+	// user, where the sessions leave their artifacts.
+	ListGeekWorkspaceFiles(context.Context, *connect.Request[v1.ListGeekWorkspaceFilesRequest]) (*connect.Response[v1.ListGeekWorkspaceFilesResponse], error)
+	// ReadGeekWorkspaceFile returns a file of the workspace with its text
+	// content, up to 1 MiB; binary files have none.
+	ReadGeekWorkspaceFile(context.Context, *connect.Request[v1.ReadGeekWorkspaceFileRequest]) (*connect.Response[v1.GeekWorkspaceFileContent], error)
+	// DownloadGeekWorkspaceFile returns a file of the workspace, up to 64 MiB,
+	// to save as an attachment.
+	DownloadGeekWorkspaceFile(context.Context, *connect.Request[v1.DownloadGeekWorkspaceFileRequest]) (*connect.Response[v1.DownloadGeekWorkspaceFileResponse], error)
+	// DeleteGeekWorkspaceFile removes a file of the workspace, or a directory
+	// with its content.
+	DeleteGeekWorkspaceFile(context.Context, *connect.Request[v1.DeleteGeekWorkspaceFileRequest]) (*connect.Response[emptypb.Empty], error)
 }
 
 // NewAIServiceHandler builds an HTTP handler from the service implementation. It returns the path
@@ -966,6 +1463,12 @@ func NewAIServiceHandler(svc AIServiceHandler, opts ...connect.HandlerOption) (s
 		connect.WithSchema(aIServiceMethods.ByName("GenerateConversationTitle")),
 		connect.WithHandlerOptions(opts...),
 	)
+	aIServiceImportConversationHandler := connect.NewUnaryHandler(
+		AIServiceImportConversationProcedure,
+		svc.ImportConversation,
+		connect.WithSchema(aIServiceMethods.ByName("ImportConversation")),
+		connect.WithHandlerOptions(opts...),
+	)
 	aIServiceDeleteAIConversationHandler := connect.NewUnaryHandler(
 		AIServiceDeleteAIConversationProcedure,
 		svc.DeleteAIConversation,
@@ -984,12 +1487,24 @@ func NewAIServiceHandler(svc AIServiceHandler, opts ...connect.HandlerOption) (s
 		connect.WithSchema(aIServiceMethods.ByName("ClearConversationMessages")),
 		connect.WithHandlerOptions(opts...),
 	)
+	aIServiceCreateChatStreamTicketHandler := connect.NewUnaryHandler(
+		AIServiceCreateChatStreamTicketProcedure,
+		svc.CreateChatStreamTicket,
+		connect.WithSchema(aIServiceMethods.ByName("CreateChatStreamTicket")),
+		connect.WithHandlerOptions(opts...),
+	)
 	aIServiceStopChatHandler := connect.NewUnaryHandler(
 		AIServiceStopChatProcedure,
 		svc.StopChat,
 		connect.WithSchema(aIServiceMethods.ByName("StopChat")),
 		connect.WithHandlerOptions(opts...),
 	)
+	aIServiceSubmitFormHandler := connect.NewServerStreamHandler(
+		AIServiceSubmitFormProcedure,
+		svc.SubmitForm,
+		connect.WithSchema(aIServiceMethods.ByName("SubmitForm")),
+		connect.WithHandlerOptions(opts...),
+	)
 	aIServiceGetSessionStatsHandler := connect.NewUnaryHandler(
 		AIServiceGetSessionStatsProcedure,
 		svc.GetSessionStats,
@@ -1026,12 +1541,24 @@ func NewAIServiceHandler(svc AIServiceHandler, opts ...connect.HandlerOption) (s
 		connect.WithSchema(aIServiceMethods.ByName("ListBlocks")),
 		connect.WithHandlerOptions(opts...),
 	)
+	aIServiceGetBlockChangesHandler := connect.NewUnaryHandler(
+		AIServiceGetBlockChangesProcedure,
+		svc.GetBlockChanges,
+		connect.WithSchema(aIServiceMethods.ByName("GetBlockChanges")),
+		connect.WithHandlerOptions(opts...),
+	)
 	aIServiceGetBlockHandler := connect.NewUnaryHandler(
 		AIServiceGetBlockProcedure,
 		svc.GetBlock,
 		connect.WithSchema(aIServiceMethods.ByName("GetBlock")),
 		connect.WithHandlerOptions(opts...),
 	)
+	aIServiceGetBlockTranscriptHandler := connect.NewUnaryHandler(
+		AIServiceGetBlockTranscriptProcedure,
+		svc.GetBlockTranscript,
+		connect.WithSchema(aIServiceMethods.ByName("GetBlockTranscript")),
+		connect.WithHandlerOptions(opts...),
+	)
 	aIServiceCreateBlockHandler := connect.NewUnaryHandler(
 		AIServiceCreateBlockProcedure,
 		svc.CreateBlock,
@@ -1086,6 +1613,120 @@ func NewAIServiceHandler(svc AIServiceHandler, opts ...connect.HandlerOption) (s
 		connect.WithSchema(aIServiceMethods.ByName("DeleteBranch")),
 		connect.WithHandlerOptions(opts...),
 	)
+	aIServiceGetUsageHandler := connect.NewUnaryHandler(
+		AIServiceGetUsageProcedure,
+		svc.GetUsage,
+		connect.WithSchema(aIServiceMethods.ByName("GetUsage")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceRunSelfTestHandler := connect.NewUnaryHandler(
+		AIServiceRunSelfTestProcedure,
+		svc.RunSelfTest,
+		connect.WithSchema(aIServiceMethods.ByName("RunSelfTest")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceReindexAllHandler := connect.NewUnaryHandler(
+		AIServiceReindexAllProcedure,
+		svc.ReindexAll,
+		connect.WithSchema(aIServiceMethods.ByName("ReindexAll")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceReindexSinceHandler := connect.NewUnaryHandler(
+		AIServiceReindexSinceProcedure,
+		svc.ReindexSince,
+		connect.WithSchema(aIServiceMethods.ByName("ReindexSince")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceGetReindexProgressHandler := connect.NewUnaryHandler(
+		AIServiceGetReindexProgressProcedure,
+		svc.GetReindexProgress,
+		connect.WithSchema(aIServiceMethods.ByName("GetReindexProgress")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceListMCPServersHandler := connect.NewUnaryHandler(
+		AIServiceListMCPServersProcedure,
+		svc.ListMCPServers,
+		connect.WithSchema(aIServiceMethods.ByName("ListMCPServers")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceCreateMCPServerHandler := connect.NewUnaryHandler(
+		AIServiceCreateMCPServerProcedure,
+		svc.CreateMCPServer,
+		connect.WithSchema(aIServiceMethods.ByName("CreateMCPServer")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceUpdateMCPServerHandler := connect.NewUnaryHandler(
+		AIServiceUpdateMCPServerProcedure,
+		svc.UpdateMCPServer,
+		connect.WithSchema(aIServiceMethods.ByName("UpdateMCPServer")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceDeleteMCPServerHandler := connect.NewUnaryHandler(
+		AIServiceDeleteMCPServerProcedure,
+		svc.DeleteMCPServer,
+		connect.WithSchema(aIServiceMethods.ByName("DeleteMCPServer")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceListActionAuditHandler := connect.NewUnaryHandler(
+		AIServiceListActionAuditProcedure,
+		svc.ListActionAudit,
+		connect.WithSchema(aIServiceMethods.ByName("ListActionAudit")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceExportActionAuditHandler := connect.NewUnaryHandler(
+		AIServiceExportActionAuditProcedure,
+		svc.ExportActionAudit,
+		connect.WithSchema(aIServiceMethods.ByName("ExportActionAudit")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceListAgentModelsHandler := connect.NewUnaryHandler(
+		AIServiceListAgentModelsProcedure,
+		svc.ListAgentModels,
+		connect.WithSchema(aIServiceMethods.ByName("ListAgentModels")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceUpdateAgentModelHandler := connect.NewUnaryHandler(
+		AIServiceUpdateAgentModelProcedure,
+		svc.UpdateAgentModel,
+		connect.WithSchema(aIServiceMethods.ByName("UpdateAgentModel")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceDeleteAgentModelHandler := connect.NewUnaryHandler(
+		AIServiceDeleteAgentModelProcedure,
+		svc.DeleteAgentModel,
+		connect.WithSchema(aIServiceMethods.ByName("DeleteAgentModel")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceGetGeekWorkspaceUsageHandler := connect.NewUnaryHandler(
+		AIServiceGetGeekWorkspaceUsageProcedure,
+		svc.GetGeekWorkspaceUsage,
+		connect.WithSchema(aIServiceMethods.ByName("GetGeekWorkspaceUsage")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceListGeekWorkspaceFilesHandler := connect.NewUnaryHandler(
+		AIServiceListGeekWorkspaceFilesProcedure,
+		svc.ListGeekWorkspaceFiles,
+		connect.WithSchema(aIServiceMethods.ByName("ListGeekWorkspaceFiles")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceReadGeekWorkspaceFileHandler := connect.NewUnaryHandler(
+		AIServiceReadGeekWorkspaceFileProcedure,
+		svc.ReadGeekWorkspaceFile,
+		connect.WithSchema(aIServiceMethods.ByName("ReadGeekWorkspaceFile")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceDownloadGeekWorkspaceFileHandler := connect.NewUnaryHandler(
+		AIServiceDownloadGeekWorkspaceFileProcedure,
+		svc.DownloadGeekWorkspaceFile,
+		connect.WithSchema(aIServiceMethods.ByName("DownloadGeekWorkspaceFile")),
+		connect.WithHandlerOptions(opts...),
+	)
+	aIServiceDeleteGeekWorkspaceFileHandler := connect.NewUnaryHandler(
+		AIServiceDeleteGeekWorkspaceFileProcedure,
+		svc.DeleteGeekWorkspaceFile,
+		connect.WithSchema(aIServiceMethods.ByName("DeleteGeekWorkspaceFile")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/memos.api.v1.AIService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case AIServiceSemanticSearchProcedure:
@@ -1130,14 +1771,20 @@ func NewAIServiceHandler(svc AIServiceHandler, opts ...connect.HandlerOption) (s
 			aIServiceUpdateAIConversationHandler.ServeHTTP(w, r)
 		case AIServiceGenerateConversationTitleProcedure:
 			aIServiceGenerateConversationTitleHandler.ServeHTTP(w, r)
+		case AIServiceImportConversationProcedure:
+			aIServiceImportConversationHandler.ServeHTTP(w, r)
 		case AIServiceDeleteAIConversationProcedure:
 			aIServiceDeleteAIConversationHandler.ServeHTTP(w, r)
 		case AIServiceAddContextSeparatorProcedure:
 			aIServiceAddContextSeparatorHandler.ServeHTTP(w, r)
 		case AIServiceClearConversationMessagesProcedure:
 			aIServiceClearConversationMessagesHandler.ServeHTTP(w, r)
+		case AIServiceCreateChatStreamTicketProcedure:
+			aIServiceCreateChatStreamTicketHandler.ServeHTTP(w, r)
 		case AIServiceStopChatProcedure:
 			aIServiceStopChatHandler.ServeHTTP(w, r)
+		case AIServiceSubmitFormProcedure:
+			aIServiceSubmitFormHandler.ServeHTTP(w, r)
 		case AIServiceGetSessionStatsProcedure:
 			aIServiceGetSessionStatsHandler.ServeHTTP(w, r)
 		case AIServiceListSessionStatsProcedure:
@@ -1150,8 +1797,12 @@ func NewAIServiceHandler(svc AIServiceHandler, opts ...connect.HandlerOption) (s
 			aIServiceSetUserCostSettingsHandler.ServeHTTP(w, r)
 		case AIServiceListBlocksProcedure:
 			aIServiceListBlocksHandler.ServeHTTP(w, r)
+		case AIServiceGetBlockChangesProcedure:
+			aIServiceGetBlockChangesHandler.ServeHTTP(w, r)
 		case AIServiceGetBlockProcedure:
 			aIServiceGetBlockHandler.ServeHTTP(w, r)
+		case AIServiceGetBlockTranscriptProcedure:
+			aIServiceGetBlockTranscriptHandler.ServeHTTP(w, r)
 		case AIServiceCreateBlockProcedure:
 			aIServiceCreateBlockHandler.ServeHTTP(w, r)
 		case AIServiceUpdateBlockProcedure:
@@ -1170,6 +1821,44 @@ func NewAIServiceHandler(svc AIServiceHandler, opts ...connect.HandlerOption) (s
 			aIServiceSwitchBranchHandler.ServeHTTP(w, r)
 		case AIServiceDeleteBranchProcedure:
 			aIServiceDeleteBranchHandler.ServeHTTP(w, r)
+		case AIServiceGetUsageProcedure:
+			aIServiceGetUsageHandler.ServeHTTP(w, r)
+		case AIServiceRunSelfTestProcedure:
+			aIServiceRunSelfTestHandler.ServeHTTP(w, r)
+		case AIServiceReindexAllProcedure:
+			aIServiceReindexAllHandler.ServeHTTP(w, r)
+		case AIServiceReindexSinceProcedure:
+			aIServiceReindexSinceHandler.ServeHTTP(w, r)
+		case AIServiceGetReindexProgressProcedure:
+			aIServiceGetReindexProgressHandler.ServeHTTP(w, r)
+		case AIServiceListMCPServersProcedure:
+			aIServiceListMCPServersHandler.ServeHTTP(w, r)
+		case AIServiceCreateMCPServerProcedure:
+			aIServiceCreateMCPServerHandler.ServeHTTP(w, r)
+		case AIServiceUpdateMCPServerProcedure:
+			aIServiceUpdateMCPServerHandler.ServeHTTP(w, r)
+		case AIServiceDeleteMCPServerProcedure:
+			aIServiceDeleteMCPServerHandler.ServeHTTP(w, r)
+		case AIServiceListActionAuditProcedure:
+			aIServiceListActionAuditHandler.ServeHTTP(w, r)
+		case AIServiceExportActionAuditProcedure:
+			aIServiceExportActionAuditHandler.ServeHTTP(w, r)
+		case AIServiceListAgentModelsProcedure:
+			aIServiceListAgentModelsHandler.ServeHTTP(w, r)
+		case AIServiceUpdateAgentModelProcedure:
+			aIServiceUpdateAgentModelHandler.ServeHTTP(w, r)
+		case AIServiceDeleteAgentModelProcedure:
+			aIServiceDeleteAgentModelHandler.ServeHTTP(w, r)
+		case AIServiceGetGeekWorkspaceUsageProcedure:
+			aIServiceGetGeekWorkspaceUsageHandler.ServeHTTP(w, r)
+		case AIServiceListGeekWorkspaceFilesProcedure:
+			aIServiceListGeekWorkspaceFilesHandler.ServeHTTP(w, r)
+		case AIServiceReadGeekWorkspaceFileProcedure:
+			aIServiceReadGeekWorkspaceFileHandler.ServeHTTP(w, r)
+		case AIServiceDownloadGeekWorkspaceFileProcedure:
+			aIServiceDownloadGeekWorkspaceFileHandler.ServeHTTP(w, r)
+		case AIServiceDeleteGeekWorkspaceFileProcedure:
+			aIServiceDeleteGeekWorkspaceFileHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -1263,6 +1952,10 @@ func (UnimplementedAIServiceHandler) GenerateConversationTitle(context.Context,
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.GenerateConversationTitle is not implemented"))
 }
 
+func (UnimplementedAIServiceHandler) ImportConversation(context.Context, *connect.Request[v1.ImportConversationRequest]) (*connect.Response[v1.ImportConversationResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.ImportConversation is not implemented"))
+}
+
 func (UnimplementedAIServiceHandler) DeleteAIConversation(context.Context, *connect.Request[v1.DeleteAIConversationRequest]) (*connect.Response[emptypb.Empty], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.DeleteAIConversation is not implemented"))
 }
@@ -1275,10 +1968,18 @@ func (UnimplementedAIServiceHandler) ClearConversationMessages(context.Context,
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.ClearConversationMessages is not implemented"))
 }
 
+func (UnimplementedAIServiceHandler) CreateChatStreamTicket(context.Context, *connect.Request[v1.CreateChatStreamTicketRequest]) (*connect.Response[v1.ChatStreamTicket], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.CreateChatStreamTicket is not implemented"))
+}
+
 func (UnimplementedAIServiceHandler) StopChat(context.Context, *connect.Request[v1.StopChatRequest]) (*connect.Response[emptypb.Empty], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.StopChat is not implemented"))
 }
 
+func (UnimplementedAIServiceHandler) SubmitForm(context.Context, *connect.Request[v1.SubmitFormRequest], *connect.ServerStream[v1.ChatResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.SubmitForm is not implemented"))
+}
+
 func (UnimplementedAIServiceHandler) GetSessionStats(context.Context, *connect.Request[v1.GetSessionStatsRequest]) (*connect.Response[v1.SessionStats], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.GetSessionStats is not implemented"))
 }
@@ -1303,10 +2004,18 @@ func (UnimplementedAIServiceHandler) ListBlocks(context.Context, *connect.Reques
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.ListBlocks is not implemented"))
 }
 
+func (UnimplementedAIServiceHandler) GetBlockChanges(context.Context, *connect.Request[v1.GetBlockChangesRequest]) (*connect.Response[v1.GetBlockChangesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.GetBlockChanges is not implemented"))
+}
+
 func (UnimplementedAIServiceHandler) GetBlock(context.Context, *connect.Request[v1.GetBlockRequest]) (*connect.Response[v1.Block], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.GetBlock is not implemented"))
 }
 
+func (UnimplementedAIServiceHandler) GetBlockTranscript(context.Context, *connect.Request[v1.GetBlockTranscriptRequest]) (*connect.Response[v1.BlockTranscript], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.GetBlockTranscript is not implemented"))
+}
+
 func (UnimplementedAIServiceHandler) CreateBlock(context.Context, *connect.Request[v1.CreateBlockRequest]) (*connect.Response[v1.Block], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.CreateBlock is not implemented"))
 }
@@ -1342,3 +2051,79 @@ func (UnimplementedAIServiceHandler) SwitchBranch(context.Context, *connect.Requ
 func (UnimplementedAIServiceHandler) DeleteBranch(context.Context, *connect.Request[v1.DeleteBranchRequest]) (*connect.Response[emptypb.Empty], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.DeleteBranch is not implemented"))
 }
+
+func (UnimplementedAIServiceHandler) GetUsage(context.Context, *connect.Request[v1.GetUsageRequest]) (*connect.Response[v1.Usage], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.GetUsage is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) RunSelfTest(context.Context, *connect.Request[v1.RunSelfTestRequest]) (*connect.Response[v1.SelfTestReport], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.RunSelfTest is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) ReindexAll(context.Context, *connect.Request[v1.ReindexAllRequest]) (*connect.Response[v1.ReindexProgress], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.ReindexAll is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) ReindexSince(context.Context, *connect.Request[v1.ReindexSinceRequest]) (*connect.Response[v1.ReindexProgress], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.ReindexSince is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) GetReindexProgress(context.Context, *connect.Request[v1.GetReindexProgressRequest]) (*connect.Response[v1.ReindexProgress], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.GetReindexProgress is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) ListMCPServers(context.Context, *connect.Request[v1.ListMCPServersRequest]) (*connect.Response[v1.ListMCPServersResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.ListMCPServers is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) CreateMCPServer(context.Context, *connect.Request[v1.CreateMCPServerRequest]) (*connect.Response[v1.MCPServer], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.CreateMCPServer is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) UpdateMCPServer(context.Context, *connect.Request[v1.UpdateMCPServerRequest]) (*connect.Response[v1.MCPServer], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.UpdateMCPServer is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) DeleteMCPServer(context.Context, *connect.Request[v1.DeleteMCPServerRequest]) (*connect.Response[emptypb.Empty], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.DeleteMCPServer is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) ListActionAudit(context.Context, *connect.Request[v1.ListActionAuditRequest]) (*connect.Response[v1.ListActionAuditResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.ListActionAudit is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) ExportActionAudit(context.Context, *connect.Request[v1.ExportActionAuditRequest]) (*connect.Response[v1.ExportActionAuditResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.ExportActionAudit is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) ListAgentModels(context.Context, *connect.Request[v1.ListAgentModelsRequest]) (*connect.Response[v1.ListAgentModelsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.ListAgentModels is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) UpdateAgentModel(context.Context, *connect.Request[v1.UpdateAgentModelRequest]) (*connect.Response[v1.AgentModel], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.UpdateAgentModel is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) DeleteAgentModel(context.Context, *connect.Request[v1.DeleteAgentModelRequest]) (*connect.Response[emptypb.Empty], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.DeleteAgentModel is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) GetGeekWorkspaceUsage(context.Context, *connect.Request[v1.GetGeekWorkspaceUsageRequest]) (*connect.Response[v1.GeekWorkspaceUsage], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.GetGeekWorkspaceUsage is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) ListGeekWorkspaceFiles(context.Context, *connect.Request[v1.ListGeekWorkspaceFilesRequest]) (*connect.Response[v1.ListGeekWorkspaceFilesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.ListGeekWorkspaceFiles is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) ReadGeekWorkspaceFile(context.Context, *connect.Request[v1.ReadGeekWorkspaceFileRequest]) (*connect.Response[v1.GeekWorkspaceFileContent], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.ReadGeekWorkspaceFile is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) DownloadGeekWorkspaceFile(context.Context, *connect.Request[v1.DownloadGeekWorkspaceFileRequest]) (*connect.Response[v1.DownloadGeekWorkspaceFileResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.DownloadGeekWorkspaceFile is not implemented"))
+}
+
+func (UnimplementedAIServiceHandler) DeleteGeekWorkspaceFile(context.Context, *connect.Request[v1.DeleteGeekWorkspaceFileRequest]) (*connect.Response[emptypb.Empty], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.AIService.DeleteGeekWorkspaceFile is not implemented"))
+}
@@ -9,6 +9,7 @@ import (
 	context "context"
 	errors "errors"
 	v1 "github.com/hrygo/divinesense/proto/gen/api/v1"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	http "net/http"
 	strings "strings"
 )
@@ -42,6 +43,12 @@ const (
 	// InstanceServiceUpdateInstanceSettingProcedure is the fully-qualified name of the
 	// InstanceService's UpdateInstanceSetting RPC.
 	InstanceServiceUpdateInstanceSettingProcedure = "/memos.api.v1.InstanceService/UpdateInstanceSetting"
+	// InstanceServiceListInstanceJobsProcedure is the fully-qualified name of the InstanceService's
+	// ListInstanceJobs RPC.
+	InstanceServiceListInstanceJobsProcedure = "/memos.api.v1.InstanceService/ListInstanceJobs"
+	// InstanceServiceRunInstanceJobProcedure is the fully-qualified name of the InstanceService's
+	// RunInstanceJob RPC.
+	InstanceServiceRunInstanceJobProcedure = "/memos.api.v1.InstanceService/RunInstanceJob"
 )
 
 // InstanceServiceClient is a client for the memos.api.v1.InstanceService service.
@@ -52,6 +59,12 @@ type InstanceServiceClient interface {
 	GetInstanceSetting(context.Context, *connect.Request[v1.GetInstanceSettingRequest]) (*connect.Response[v1.InstanceSetting], error)
 	// Updates an instance setting.
 	UpdateInstanceSetting(context.Context, *connect.Request[v1.UpdateInstanceSettingRequest]) (*connect.Response[v1.InstanceSetting], error)
+	// Lists the periodic background jobs with their next and last runs.
+	// Admin only.
+	ListInstanceJobs(context.Context, *connect.Request[v1.ListInstanceJobsRequest]) (*connect.Response[v1.ListInstanceJobsResponse], error)
+	// Starts a run of a periodic job now; its result is reported by
+	// ListInstanceJobs once done. Admin only.
+	RunInstanceJob(context.Context, *connect.Request[v1.RunInstanceJobRequest]) (*connect.Response[emptypb.Empty], error)
 }
 
 // NewInstanceServiceClient constructs a client for the memos.api.v1.InstanceService service. By
@@ -83,6 +96,18 @@ func NewInstanceServiceClient(httpClient connect.HTTPClient, baseURL string, opt
 			connect.WithSchema(instanceServiceMethods.ByName("UpdateInstanceSetting")),
 			connect.WithClientOptions(opts...),
 		),
+		listInstanceJobs: connect.NewClient[v1.ListInstanceJobsRequest, v1.ListInstanceJobsResponse](
+			httpClient,
+			baseURL+InstanceServiceListInstanceJobsProcedure,
+			connect.WithSchema(instanceServiceMethods.ByName("ListInstanceJobs")),
+			connect.WithClientOptions(opts...),
+		),
+		runInstanceJob: connect.NewClient[v1.RunInstanceJobRequest, emptypb.Empty](
+			httpClient,
+			baseURL+InstanceServiceRunInstanceJobProcedure,
+			connect.WithSchema(instanceServiceMethods.ByName("RunInstanceJob")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
@@ -91,6 +116,8 @@ type instanceServiceClient struct {
 	getInstanceProfile    *connect.Client[v1.GetInstanceProfileRequest, v1.InstanceProfile]
 	getInstanceSetting    *connect.Client[v1.GetInstanceSettingRequest, v1.InstanceSetting]
 	updateInstanceSetting *connect.Client[v1.UpdateInstanceSettingRequest, v1.InstanceSetting]
+	listInstanceJobs      *connect.Client[v1.ListInstanceJobsRequest, v1.ListInstanceJobsResponse]
+	runInstanceJob        *connect.Client[v1.RunInstanceJobRequest, emptypb.Empty]
 }
 
 // GetInstanceProfile calls memos.api.v1.InstanceService.GetInstanceProfile.
@@ -108,6 +135,16 @@ func (c *instanceServiceClient) UpdateInstanceSetting(ctx context.Context, req *
 	return c.updateInstanceSetting.CallUnary(ctx, req)
 }
 
+// ListInstanceJobs calls memos.api.v1.InstanceService.ListInstanceJobs.
+func (c *instanceServiceClient) ListInstanceJobs(ctx context.Context, req *connect.Request[v1.ListInstanceJobsRequest]) (*connect.Response[v1.ListInstanceJobsResponse], error) {
+	return c.listInstanceJobs.CallUnary(ctx, req)
+}
+
+// RunInstanceJob calls memos.api.v1.InstanceService.RunInstanceJob.
+func (c *instanceServiceClient) RunInstanceJob(ctx context.Context, req *connect.Request[v1.RunInstanceJobRequest]) (*connect.Response[emptypb.Empty], error) {
+	return c.runInstanceJob.CallUnary(ctx, req)
+}
+
 // InstanceServiceHandler is an implementation of the memos.api.v1.InstanceService service.
 type InstanceServiceHandler interface {
 	// Gets the instance profile.
@@ -116,6 +153,12 @@ type InstanceServiceHandler interface {
 	GetInstanceSetting(context.Context, *connect.Request[v1.GetInstanceSettingRequest]) (*connect.Response[v1.InstanceSetting], error)
 	// Updates an instance setting.
 	UpdateInstanceSetting(context.Context, *connect.Request[v1.UpdateInstanceSettingRequest]) (*connect.Response[v1.InstanceSetting], error)
+	// Lists the periodic background jobs with their next and last runs.
+	// Admin only.
+	ListInstanceJobs(context.Context, *connect.Request[v1.ListInstanceJobsRequest]) (*connect.Response[v1.ListInstanceJobsResponse], error)
+	// Starts a run of a periodic job now; its result is reported by
+	// ListInstanceJobs once done. Admin only.
+	RunInstanceJob(context.Context, *connect.Request[v1.RunInstanceJobRequest]) (*connect.Response[emptypb.Empty], error)
 }
 
 // NewInstanceServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -143,6 +186,18 @@ func NewInstanceServiceHandler(svc InstanceServiceHandler, opts ...connect.Handl
 		connect.WithSchema(instanceServiceMethods.ByName("UpdateInstanceSetting")),
 		connect.WithHandlerOptions(opts...),
 	)
+	instanceServiceListInstanceJobsHandler := connect.NewUnaryHandler(
+		InstanceServiceListInstanceJobsProcedure,
+		svc.ListInstanceJobs,
+		connect.WithSchema(instanceServiceMethods.ByName("ListInstanceJobs")),
+		connect.WithHandlerOptions(opts...),
+	)
+	instanceServiceRunInstanceJobHandler := connect.NewUnaryHandler(
+		InstanceServiceRunInstanceJobProcedure,
+		svc.RunInstanceJob,
+		connect.WithSchema(instanceServiceMethods.ByName("RunInstanceJob")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/memos.api.v1.InstanceService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case InstanceServiceGetInstanceProfileProcedure:
@@ -151,6 +206,10 @@ func NewInstanceServiceHandler(svc InstanceServiceHandler, opts ...connect.Handl
 			instanceServiceGetInstanceSettingHandler.ServeHTTP(w, r)
 		case InstanceServiceUpdateInstanceSettingProcedure:
 			instanceServiceUpdateInstanceSettingHandler.ServeHTTP(w, r)
+		case InstanceServiceListInstanceJobsProcedure:
+			instanceServiceListInstanceJobsHandler.ServeHTTP(w, r)
+		case InstanceServiceRunInstanceJobProcedure:
+			instanceServiceRunInstanceJobHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -171,3 +230,11 @@ func (UnimplementedInstanceServiceHandler) GetInstanceSetting(context.Context, *
 func (UnimplementedInstanceServiceHandler) UpdateInstanceSetting(context.Context, *connect.Request[v1.UpdateInstanceSettingRequest]) (*connect.Response[v1.InstanceSetting], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.InstanceService.UpdateInstanceSetting is not implemented"))
 }
+
+func (UnimplementedInstanceServiceHandler) ListInstanceJobs(context.Context, *connect.Request[v1.ListInstanceJobsRequest]) (*connect.Response[v1.ListInstanceJobsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.InstanceService.ListInstanceJobs is not implemented"))
+}
+
+func (UnimplementedInstanceServiceHandler) RunInstanceJob(context.Context, *connect.Request[v1.RunInstanceJobRequest]) (*connect.Response[emptypb.Empty], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.InstanceService.RunInstanceJob is not implemented"))
+}
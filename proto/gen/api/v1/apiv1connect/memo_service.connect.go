@@ -77,6 +77,12 @@ const (
 	// MemoServiceGetRelatedMemosProcedure is the fully-qualified name of the MemoService's
 	// GetRelatedMemos RPC.
 	MemoServiceGetRelatedMemosProcedure = "/memos.api.v1.MemoService/GetRelatedMemos"
+	// MemoServiceSearchMemosProcedure is the fully-qualified name of the MemoService's
+	// SearchMemos RPC.
+	MemoServiceSearchMemosProcedure = "/memos.api.v1.MemoService/SearchMemos"
+	// MemoServiceImportMemosProcedure is the fully-qualified name of the MemoService's
+	// ImportMemos RPC.
+	MemoServiceImportMemosProcedure = "/memos.api.v1.MemoService/ImportMemos"
 )
 
 // MemoServiceClient is a client for the memos.api.v1.MemoService service.
@@ -113,6 +119,14 @@ type MemoServiceClient interface {
 	SearchWithHighlight(context.Context, *connect.Request[v1.SearchWithHighlightRequest]) (*connect.Response[v1.SearchWithHighlightResponse], error)
 	// GetRelatedMemos returns related memos for a given memo.
 	GetRelatedMemos(context.Context, *connect.Request[v1.GetRelatedMemosRequest]) (*connect.Response[v1.GetRelatedMemosResponse], error)
+	// SearchMemos searches the memos visible to the user by keywords.
+	// It uses the full-text search of the database, so it works without AI.
+	SearchMemos(context.Context, *connect.Request[v1.SearchMemosRequest]) (*connect.Response[v1.SearchMemosResponse], error)
+	// ImportMemos imports a zipped folder of Markdown notes, an Obsidian vault,
+	// a Logseq graph or an export as memos of the user, like the import command.
+	// It streams the progress once the folder is scanned; the last event is
+	// "finished" unless the import stopped.
+	ImportMemos(context.Context, *connect.Request[v1.ImportMemosRequest]) (*connect.ServerStreamForClient[v1.ImportMemosResponse], error)
 }
 
 // NewMemoServiceClient constructs a client for the memos.api.v1.MemoService service. By default, it
@@ -222,6 +236,18 @@ func NewMemoServiceClient(httpClient connect.HTTPClient, baseURL string, opts ..
 			connect.WithSchema(memoServiceMethods.ByName("GetRelatedMemos")),
 			connect.WithClientOptions(opts...),
 		),
+		searchMemos: connect.NewClient[v1.SearchMemosRequest, v1.SearchMemosResponse](
+			httpClient,
+			baseURL+MemoServiceSearchMemosProcedure,
+			connect.WithSchema(memoServiceMethods.ByName("SearchMemos")),
+			connect.WithClientOptions(opts...),
+		),
+		importMemos: connect.NewClient[v1.ImportMemosRequest, v1.ImportMemosResponse](
+			httpClient,
+			baseURL+MemoServiceImportMemosProcedure,
+			connect.WithSchema(memoServiceMethods.ByName("ImportMemos")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
@@ -243,6 +269,8 @@ type memoServiceClient struct {
 	deleteMemoReaction  *connect.Client[v1.DeleteMemoReactionRequest, emptypb.Empty]
 	searchWithHighlight *connect.Client[v1.SearchWithHighlightRequest, v1.SearchWithHighlightResponse]
 	getRelatedMemos     *connect.Client[v1.GetRelatedMemosRequest, v1.GetRelatedMemosResponse]
+	searchMemos         *connect.Client[v1.SearchMemosRequest, v1.SearchMemosResponse]
+	importMemos         *connect.Client[v1.ImportMemosRequest, v1.ImportMemosResponse]
 }
 
 // CreateMemo calls memos.api.v1.MemoService.CreateMemo.
@@ -325,6 +353,16 @@ func (c *memoServiceClient) GetRelatedMemos(ctx context.Context, req *connect.Re
 	return c.getRelatedMemos.CallUnary(ctx, req)
 }
 
+// SearchMemos calls memos.api.v1.MemoService.SearchMemos.
+func (c *memoServiceClient) SearchMemos(ctx context.Context, req *connect.Request[v1.SearchMemosRequest]) (*connect.Response[v1.SearchMemosResponse], error) {
+	return c.searchMemos.CallUnary(ctx, req)
+}
+
+// ImportMemos calls memos.api.v1.MemoService.ImportMemos.
+func (c *memoServiceClient) ImportMemos(ctx context.Context, req *connect.Request[v1.ImportMemosRequest]) (*connect.ServerStreamForClient[v1.ImportMemosResponse], error) {
+	return c.importMemos.CallServerStream(ctx, req)
+}
+
 // MemoServiceHandler is an implementation of the memos.api.v1.MemoService service.
 type MemoServiceHandler interface {
 	// CreateMemo creates a memo.
@@ -359,6 +397,14 @@ type MemoServiceHandler interface {
 	SearchWithHighlight(context.Context, *connect.Request[v1.SearchWithHighlightRequest]) (*connect.Response[v1.SearchWithHighlightResponse], error)
 	// GetRelatedMemos returns related memos for a given memo.
 	GetRelatedMemos(context.Context, *connect.Request[v1.GetRelatedMemosRequest]) (*connect.Response[v1.GetRelatedMemosResponse], error)
+	// SearchMemos searches the memos visible to the user by keywords.
+	// It uses the full-text search of the database, so it works without AI.
+	SearchMemos(context.Context, *connect.Request[v1.SearchMemosRequest]) (*connect.Response[v1.SearchMemosResponse], error)
+	// ImportMemos imports a zipped folder of Markdown notes, an Obsidian vault,
+	// a Logseq graph or an export as memos of the user, like the import command.
+	// It streams the progress once the folder is scanned; the last event is
+	// "finished" unless the import stopped.
+	ImportMemos(context.Context, *connect.Request[v1.ImportMemosRequest], *connect.ServerStream[v1.ImportMemosResponse]) error
 }
 
 // NewMemoServiceHandler builds an HTTP handler from the service implementation. It returns the path
@@ -464,6 +510,18 @@ func NewMemoServiceHandler(svc MemoServiceHandler, opts ...connect.HandlerOption
 		connect.WithSchema(memoServiceMethods.ByName("GetRelatedMemos")),
 		connect.WithHandlerOptions(opts...),
 	)
+	memoServiceSearchMemosHandler := connect.NewUnaryHandler(
+		MemoServiceSearchMemosProcedure,
+		svc.SearchMemos,
+		connect.WithSchema(memoServiceMethods.ByName("SearchMemos")),
+		connect.WithHandlerOptions(opts...),
+	)
+	memoServiceImportMemosHandler := connect.NewServerStreamHandler(
+		MemoServiceImportMemosProcedure,
+		svc.ImportMemos,
+		connect.WithSchema(memoServiceMethods.ByName("ImportMemos")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/memos.api.v1.MemoService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case MemoServiceCreateMemoProcedure:
@@ -498,6 +556,10 @@ func NewMemoServiceHandler(svc MemoServiceHandler, opts ...connect.HandlerOption
 			memoServiceSearchWithHighlightHandler.ServeHTTP(w, r)
 		case MemoServiceGetRelatedMemosProcedure:
 			memoServiceGetRelatedMemosHandler.ServeHTTP(w, r)
+		case MemoServiceSearchMemosProcedure:
+			memoServiceSearchMemosHandler.ServeHTTP(w, r)
+		case MemoServiceImportMemosProcedure:
+			memoServiceImportMemosHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -570,3 +632,11 @@ func (UnimplementedMemoServiceHandler) SearchWithHighlight(context.Context, *con
 func (UnimplementedMemoServiceHandler) GetRelatedMemos(context.Context, *connect.Request[v1.GetRelatedMemosRequest]) (*connect.Response[v1.GetRelatedMemosResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.MemoService.GetRelatedMemos is not implemented"))
 }
+
+func (UnimplementedMemoServiceHandler) SearchMemos(context.Context, *connect.Request[v1.SearchMemosRequest]) (*connect.Response[v1.SearchMemosResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.MemoService.SearchMemos is not implemented"))
+}
+
+func (UnimplementedMemoServiceHandler) ImportMemos(context.Context, *connect.Request[v1.ImportMemosRequest], *connect.ServerStream[v1.ImportMemosResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("memos.api.v1.MemoService.ImportMemos is not implemented"))
+}
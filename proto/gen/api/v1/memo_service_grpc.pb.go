@@ -36,6 +36,8 @@ const (
 	MemoService_DeleteMemoReaction_FullMethodName  = "/memos.api.v1.MemoService/DeleteMemoReaction"
 	MemoService_SearchWithHighlight_FullMethodName = "/memos.api.v1.MemoService/SearchWithHighlight"
 	MemoService_GetRelatedMemos_FullMethodName     = "/memos.api.v1.MemoService/GetRelatedMemos"
+	MemoService_SearchMemos_FullMethodName         = "/memos.api.v1.MemoService/SearchMemos"
+	MemoService_ImportMemos_FullMethodName         = "/memos.api.v1.MemoService/ImportMemos"
 )
 
 // MemoServiceClient is the client API for MemoService service.
@@ -74,6 +76,14 @@ type MemoServiceClient interface {
 	SearchWithHighlight(ctx context.Context, in *SearchWithHighlightRequest, opts ...grpc.CallOption) (*SearchWithHighlightResponse, error)
 	// GetRelatedMemos returns related memos for a given memo.
 	GetRelatedMemos(ctx context.Context, in *GetRelatedMemosRequest, opts ...grpc.CallOption) (*GetRelatedMemosResponse, error)
+	// SearchMemos searches the memos visible to the user by keywords.
+	// It uses the full-text search of the database, so it works without AI.
+	SearchMemos(ctx context.Context, in *SearchMemosRequest, opts ...grpc.CallOption) (*SearchMemosResponse, error)
+	// ImportMemos imports a zipped folder of Markdown notes, an Obsidian vault,
+	// a Logseq graph or an export as memos of the user, like the import command.
+	// It streams the progress once the folder is scanned; the last event is
+	// "finished" unless the import stopped.
+	ImportMemos(ctx context.Context, in *ImportMemosRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ImportMemosResponse], error)
 }
 
 type memoServiceClient struct {
@@ -244,6 +254,35 @@ func (c *memoServiceClient) GetRelatedMemos(ctx context.Context, in *GetRelatedM
 	return out, nil
 }
 
+func (c *memoServiceClient) SearchMemos(ctx context.Context, in *SearchMemosRequest, opts ...grpc.CallOption) (*SearchMemosResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchMemosResponse)
+	err := c.cc.Invoke(ctx, MemoService_SearchMemos_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *memoServiceClient) ImportMemos(ctx context.Context, in *ImportMemosRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ImportMemosResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MemoService_ServiceDesc.Streams[0], MemoService_ImportMemos_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportMemosRequest, ImportMemosResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MemoService_ImportMemosClient = grpc.ServerStreamingClient[ImportMemosResponse]
+
 // MemoServiceServer is the server API for MemoService service.
 // All implementations must embed UnimplementedMemoServiceServer
 // for forward compatibility.
@@ -280,6 +319,14 @@ type MemoServiceServer interface {
 	SearchWithHighlight(context.Context, *SearchWithHighlightRequest) (*SearchWithHighlightResponse, error)
 	// GetRelatedMemos returns related memos for a given memo.
 	GetRelatedMemos(context.Context, *GetRelatedMemosRequest) (*GetRelatedMemosResponse, error)
+	// SearchMemos searches the memos visible to the user by keywords.
+	// It uses the full-text search of the database, so it works without AI.
+	SearchMemos(context.Context, *SearchMemosRequest) (*SearchMemosResponse, error)
+	// ImportMemos imports a zipped folder of Markdown notes, an Obsidian vault,
+	// a Logseq graph or an export as memos of the user, like the import command.
+	// It streams the progress once the folder is scanned; the last event is
+	// "finished" unless the import stopped.
+	ImportMemos(*ImportMemosRequest, grpc.ServerStreamingServer[ImportMemosResponse]) error
 	mustEmbedUnimplementedMemoServiceServer()
 }
 
@@ -338,6 +385,12 @@ func (UnimplementedMemoServiceServer) SearchWithHighlight(context.Context, *Sear
 func (UnimplementedMemoServiceServer) GetRelatedMemos(context.Context, *GetRelatedMemosRequest) (*GetRelatedMemosResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetRelatedMemos not implemented")
 }
+func (UnimplementedMemoServiceServer) SearchMemos(context.Context, *SearchMemosRequest) (*SearchMemosResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SearchMemos not implemented")
+}
+func (UnimplementedMemoServiceServer) ImportMemos(*ImportMemosRequest, grpc.ServerStreamingServer[ImportMemosResponse]) error {
+	return status.Error(codes.Unimplemented, "method ImportMemos not implemented")
+}
 func (UnimplementedMemoServiceServer) mustEmbedUnimplementedMemoServiceServer() {}
 func (UnimplementedMemoServiceServer) testEmbeddedByValue()                     {}
 
@@ -647,6 +700,35 @@ func _MemoService_GetRelatedMemos_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MemoService_SearchMemos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchMemosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoServiceServer).SearchMemos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoService_SearchMemos_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoServiceServer).SearchMemos(ctx, req.(*SearchMemosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MemoService_ImportMemos_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ImportMemosRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MemoServiceServer).ImportMemos(m, &grpc.GenericServerStream[ImportMemosRequest, ImportMemosResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MemoService_ImportMemosServer = grpc.ServerStreamingServer[ImportMemosResponse]
+
 // MemoService_ServiceDesc is the grpc.ServiceDesc for MemoService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -718,7 +800,17 @@ var MemoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetRelatedMemos",
 			Handler:    _MemoService_GetRelatedMemos_Handler,
 		},
+		{
+			MethodName: "SearchMemos",
+			Handler:    _MemoService_SearchMemos_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ImportMemos",
+			Handler:       _MemoService_ImportMemos_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/v1/memo_service.proto",
 }
@@ -27,6 +27,8 @@ const (
 	InboxMessage_TYPE_UNSPECIFIED InboxMessage_Type = 0
 	// Memo comment notification.
 	InboxMessage_MEMO_COMMENT InboxMessage_Type = 1
+	// Action item the email expert extracted from an email.
+	InboxMessage_EMAIL_ACTION_ITEM InboxMessage_Type = 2
 )
 
 // Enum value maps for InboxMessage_Type.
@@ -34,10 +36,12 @@ var (
 	InboxMessage_Type_name = map[int32]string{
 		0: "TYPE_UNSPECIFIED",
 		1: "MEMO_COMMENT",
+		2: "EMAIL_ACTION_ITEM",
 	}
 	InboxMessage_Type_value = map[string]int32{
-		"TYPE_UNSPECIFIED": 0,
-		"MEMO_COMMENT":     1,
+		"TYPE_UNSPECIFIED":  0,
+		"MEMO_COMMENT":      1,
+		"EMAIL_ACTION_ITEM": 2,
 	}
 )
 
@@ -73,9 +77,11 @@ type InboxMessage struct {
 	// The type of the inbox message.
 	Type InboxMessage_Type `protobuf:"varint,1,opt,name=type,proto3,enum=memos.store.InboxMessage_Type" json:"type,omitempty"`
 	// The system-generated unique ID of related activity.
-	ActivityId    *int32 `protobuf:"varint,2,opt,name=activity_id,json=activityId,proto3,oneof" json:"activity_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	ActivityId *int32 `protobuf:"varint,2,opt,name=activity_id,json=activityId,proto3,oneof" json:"activity_id,omitempty"`
+	// The action item of EMAIL_ACTION_ITEM messages.
+	EmailActionItem *EmailActionItem `protobuf:"bytes,3,opt,name=email_action_item,json=emailActionItem,proto3" json:"email_action_item,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *InboxMessage) Reset() {
@@ -122,19 +128,117 @@ func (x *InboxMessage) GetActivityId() int32 {
 	return 0
 }
 
+func (x *InboxMessage) GetEmailActionItem() *EmailActionItem {
+	if x != nil {
+		return x.EmailActionItem
+	}
+	return nil
+}
+
+// EmailActionItem is a task extracted from an email.
+type EmailActionItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The task to do.
+	Task string `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	// The due date (YYYY-MM-DD), if any.
+	Due string `protobuf:"bytes,2,opt,name=due,proto3" json:"due,omitempty"`
+	// The sender of the email.
+	Sender string `protobuf:"bytes,3,opt,name=sender,proto3" json:"sender,omitempty"`
+	// The subject of the email.
+	Subject string `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`
+	// The Message-ID header of the email.
+	MessageId     string `protobuf:"bytes,5,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmailActionItem) Reset() {
+	*x = EmailActionItem{}
+	mi := &file_store_inbox_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmailActionItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmailActionItem) ProtoMessage() {}
+
+func (x *EmailActionItem) ProtoReflect() protoreflect.Message {
+	mi := &file_store_inbox_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmailActionItem.ProtoReflect.Descriptor instead.
+func (*EmailActionItem) Descriptor() ([]byte, []int) {
+	return file_store_inbox_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EmailActionItem) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *EmailActionItem) GetDue() string {
+	if x != nil {
+		return x.Due
+	}
+	return ""
+}
+
+func (x *EmailActionItem) GetSender() string {
+	if x != nil {
+		return x.Sender
+	}
+	return ""
+}
+
+func (x *EmailActionItem) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *EmailActionItem) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
 var File_store_inbox_proto protoreflect.FileDescriptor
 
 const file_store_inbox_proto_rawDesc = "" +
 	"\n" +
-	"\x11store/inbox.proto\x12\vmemos.store\"\xa8\x01\n" +
+	"\x11store/inbox.proto\x12\vmemos.store\"\x89\x02\n" +
 	"\fInboxMessage\x122\n" +
 	"\x04type\x18\x01 \x01(\x0e2\x1e.memos.store.InboxMessage.TypeR\x04type\x12$\n" +
 	"\vactivity_id\x18\x02 \x01(\x05H\x00R\n" +
-	"activityId\x88\x01\x01\".\n" +
+	"activityId\x88\x01\x01\x12H\n" +
+	"\x11email_action_item\x18\x03 \x01(\v2\x1c.memos.store.EmailActionItemR\x0femailActionItem\"E\n" +
 	"\x04Type\x12\x14\n" +
 	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x10\n" +
-	"\fMEMO_COMMENT\x10\x01B\x0e\n" +
-	"\f_activity_idB\x98\x01\n" +
+	"\fMEMO_COMMENT\x10\x01\x12\x15\n" +
+	"\x11EMAIL_ACTION_ITEM\x10\x02B\x0e\n" +
+	"\f_activity_id\"\x88\x01\n" +
+	"\x0fEmailActionItem\x12\x12\n" +
+	"\x04task\x18\x01 \x01(\tR\x04task\x12\x10\n" +
+	"\x03due\x18\x02 \x01(\tR\x03due\x12\x16\n" +
+	"\x06sender\x18\x03 \x01(\tR\x06sender\x12\x18\n" +
+	"\asubject\x18\x04 \x01(\tR\asubject\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x05 \x01(\tR\tmessageIdB\x98\x01\n" +
 	"\x0fcom.memos.storeB\n" +
 	"InboxProtoP\x01Z,github.com/hrygo/divinesense/proto/gen/store\xa2\x02\x03MSX\xaa\x02\vMemos.Store\xca\x02\vMemos\\Store\xe2\x02\x17Memos\\Store\\GPBMetadata\xea\x02\fMemos::Storeb\x06proto3"
 
@@ -151,18 +255,20 @@ func file_store_inbox_proto_rawDescGZIP() []byte {
 }
 
 var file_store_inbox_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_store_inbox_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_store_inbox_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_store_inbox_proto_goTypes = []any{
-	(InboxMessage_Type)(0), // 0: memos.store.InboxMessage.Type
-	(*InboxMessage)(nil),   // 1: memos.store.InboxMessage
+	(InboxMessage_Type)(0),  // 0: memos.store.InboxMessage.Type
+	(*InboxMessage)(nil),    // 1: memos.store.InboxMessage
+	(*EmailActionItem)(nil), // 2: memos.store.EmailActionItem
 }
 var file_store_inbox_proto_depIdxs = []int32{
 	0, // 0: memos.store.InboxMessage.type:type_name -> memos.store.InboxMessage.Type
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	2, // 1: memos.store.InboxMessage.email_action_item:type_name -> memos.store.EmailActionItem
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_store_inbox_proto_init() }
@@ -177,7 +283,7 @@ func file_store_inbox_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_store_inbox_proto_rawDesc), len(file_store_inbox_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
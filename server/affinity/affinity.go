@@ -0,0 +1,116 @@
+// Package affinity keeps the follow-up messages of a Claude Code session on the
+// instance running its CLI process, in deployments with several instances
+// behind a load balancer.
+//
+// Each instance records the conversations whose CLI session it runs in a
+// registry shared by all instances: RedisRegistry on a Redis server, or
+// DBRegistry on the PostgreSQL database of deployments without Redis. An
+// instance receiving a message for a session held by another one forwards the
+// request to it, whatever the chat transport, and chat responses name the
+// holding instance so a load balancer can route sticky.
+package affinity
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// InstanceHeader is the response header naming the instance holding the
+	// session, a sticky routing hint for load balancers.
+	InstanceHeader = "X-DivineSense-Instance"
+	// ForwardedHeader marks a request forwarded by another instance. Forwarded
+	// requests are handled locally, never forwarded again.
+	ForwardedHeader = "X-DivineSense-Forwarded-By"
+
+	// SessionTTL is how long a claim outlives the last message of its session:
+	// the idle timeout after which the CLI process is stopped.
+	SessionTTL = 30 * time.Minute
+)
+
+// Instance is a server instance of a multi-instance deployment.
+type Instance struct {
+	// ID identifies the instance, e.g. its hostname.
+	ID string
+	// URL is the internal base URL other instances forward requests to.
+	URL string
+}
+
+// NewInstance returns the current instance of the profile settings, or nil
+// when no internal URL is configured (single-instance deployment). The ID
+// defaults to the hostname.
+func NewInstance(id, url string) *Instance {
+	url = strings.TrimRight(url, "/")
+	if url == "" {
+		return nil
+	}
+	if id == "" {
+		id, _ = os.Hostname()
+	}
+	if id == "" {
+		id = url
+	}
+	return &Instance{ID: id, URL: url}
+}
+
+// Registry maps conversations to the instance holding their CLI session.
+// RedisRegistry and DBRegistry implement it.
+type Registry interface {
+	// Claim records instance as the holder of the conversation's session.
+	Claim(ctx context.Context, conversationID int32, instance *Instance) error
+	// Owner returns the holder of the conversation's session, or nil when no
+	// instance claimed it within SessionTTL.
+	Owner(ctx context.Context, conversationID int32) (*Instance, error)
+}
+
+// Router decides which instance handles the messages of a session.
+// A nil Router handles everything locally.
+type Router struct {
+	self     *Instance
+	registry Registry
+}
+
+// NewRouter creates a router for the current instance.
+func NewRouter(self *Instance, registry Registry) *Router {
+	return &Router{self: self, registry: registry}
+}
+
+// Self returns the current instance, or nil.
+func (r *Router) Self() *Instance {
+	if r == nil {
+		return nil
+	}
+	return r.self
+}
+
+// Peer returns the other instance holding the conversation's session, or nil
+// when the current instance should handle it. Registry failures are logged
+// and handled locally.
+func (r *Router) Peer(ctx context.Context, conversationID int32) *Instance {
+	if r == nil || conversationID == 0 {
+		return nil
+	}
+	owner, err := r.registry.Owner(ctx, conversationID)
+	if err != nil {
+		slog.Warn("affinity: failed to look up session owner", "conversation_id", conversationID, "error", err)
+		return nil
+	}
+	if owner == nil || owner.ID == r.self.ID {
+		return nil
+	}
+	return owner
+}
+
+// Claim records the current instance as the holder of the conversation's
+// session. It is called for every message, refreshing the claim.
+func (r *Router) Claim(ctx context.Context, conversationID int32) {
+	if r == nil || conversationID == 0 {
+		return
+	}
+	if err := r.registry.Claim(ctx, conversationID, r.self); err != nil {
+		slog.Warn("affinity: failed to claim session", "conversation_id", conversationID, "error", err)
+	}
+}
@@ -0,0 +1,77 @@
+package affinity
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryRegistry struct {
+	owners map[int32]*Instance
+	err    error
+}
+
+func (m *memoryRegistry) Claim(_ context.Context, conversationID int32, instance *Instance) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.owners[conversationID] = instance
+	return nil
+}
+
+func (m *memoryRegistry) Owner(_ context.Context, conversationID int32) (*Instance, error) {
+	return m.owners[conversationID], m.err
+}
+
+func TestRouter(t *testing.T) {
+	ctx := context.Background()
+	a := &Instance{ID: "a", URL: "http://a:28081"}
+	b := &Instance{ID: "b", URL: "http://b:28081"}
+	registry := &memoryRegistry{owners: map[int32]*Instance{}}
+	routerA, routerB := NewRouter(a, registry), NewRouter(b, registry)
+
+	t.Run("unclaimed sessions are handled locally", func(t *testing.T) {
+		assert.Nil(t, routerA.Peer(ctx, 1))
+	})
+
+	t.Run("claimed sessions go to their owner", func(t *testing.T) {
+		routerB.Claim(ctx, 1)
+		assert.Equal(t, b, routerA.Peer(ctx, 1))
+		assert.Nil(t, routerB.Peer(ctx, 1))
+	})
+
+	t.Run("a new claim moves the session", func(t *testing.T) {
+		routerA.Claim(ctx, 1)
+		assert.Nil(t, routerA.Peer(ctx, 1))
+		assert.Equal(t, a, routerB.Peer(ctx, 1))
+	})
+
+	t.Run("registry failures are handled locally", func(t *testing.T) {
+		failing := NewRouter(a, &memoryRegistry{owners: map[int32]*Instance{1: b}, err: errors.New("down")})
+		assert.Nil(t, failing.Peer(ctx, 1))
+	})
+
+	t.Run("nil router handles everything locally", func(t *testing.T) {
+		var router *Router
+		assert.Nil(t, router.Self())
+		assert.Nil(t, router.Peer(ctx, 1))
+		router.Claim(ctx, 1)
+	})
+}
+
+func TestNewInstance(t *testing.T) {
+	assert.Nil(t, NewInstance("node-1", ""))
+
+	instance := NewInstance("node-1", "http://10.0.0.5:28081/")
+	require.NotNil(t, instance)
+	assert.Equal(t, &Instance{ID: "node-1", URL: "http://10.0.0.5:28081"}, instance)
+
+	hostname, _ := os.Hostname()
+	if hostname != "" {
+		assert.Equal(t, hostname, NewInstance("", "http://10.0.0.5:28081").ID)
+	}
+}
@@ -0,0 +1,216 @@
+package affinity
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// redisKeyPrefix prefixes the keys of the claims in Redis.
+	redisKeyPrefix = "divinesense:cc-session:"
+	// redisDialTimeout bounds the connection to Redis when ctx has no deadline.
+	redisDialTimeout = 5 * time.Second
+)
+
+// RedisRegistry stores claims as Redis keys expiring after SessionTTL. It
+// speaks the Redis protocol over a single connection, reopened after a failure.
+type RedisRegistry struct {
+	addr     string
+	username string
+	password string
+	db       int
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedisRegistry creates a registry on the Redis server of a
+// redis://[[username]:password@]host[:port][/db] URL.
+func NewRedisRegistry(rawURL string) (*RedisRegistry, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "redis" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid redis url %q: expected redis://[[username]:password@]host[:port][/db]", rawURL)
+	}
+	r := &RedisRegistry{addr: u.Host}
+	if u.Port() == "" {
+		r.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		r.username = u.User.Username()
+		r.password, _ = u.User.Password()
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		if r.db, err = strconv.Atoi(db); err != nil || r.db < 0 {
+			return nil, fmt.Errorf("invalid redis database %q", db)
+		}
+	}
+	return r, nil
+}
+
+// Claim sets the claim of instance on the conversation, expiring after SessionTTL.
+func (r *RedisRegistry) Claim(ctx context.Context, conversationID int32, instance *Instance) error {
+	value, err := json.Marshal(instance)
+	if err != nil {
+		return err
+	}
+	ttl := strconv.FormatInt(SessionTTL.Milliseconds(), 10)
+	if _, err := r.do(ctx, "SET", redisKey(conversationID), string(value), "PX", ttl); err != nil {
+		return fmt.Errorf("failed to claim session of conversation %d: %w", conversationID, err)
+	}
+	return nil
+}
+
+// Owner returns the instance that claimed the conversation within SessionTTL.
+func (r *RedisRegistry) Owner(ctx context.Context, conversationID int32) (*Instance, error) {
+	reply, err := r.do(ctx, "GET", redisKey(conversationID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session owner of conversation %d: %w", conversationID, err)
+	}
+	value, ok := reply.(string)
+	if !ok {
+		return nil, nil
+	}
+	instance := &Instance{}
+	if err := json.Unmarshal([]byte(value), instance); err != nil {
+		return nil, fmt.Errorf("invalid session owner of conversation %d: %w", conversationID, err)
+	}
+	return instance, nil
+}
+
+// Close closes the connection to Redis.
+func (r *RedisRegistry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closeConn()
+}
+
+func redisKey(conversationID int32) string {
+	return redisKeyPrefix + strconv.FormatInt(int64(conversationID), 10)
+}
+
+// do sends a command and returns its reply: a string, an int64, or nil for a
+// missing value.
+func (r *RedisRegistry) do(ctx context.Context, args ...string) (any, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conn == nil {
+		if err := r.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := r.roundTrip(ctx, args)
+	var redisErr redisError
+	if err != nil && !errors.As(err, &redisErr) {
+		// The connection is in an unknown state
+		_ = r.closeConn()
+	}
+	return reply, err
+}
+
+// connect opens the connection, authenticating and selecting the database.
+func (r *RedisRegistry) connect(ctx context.Context) error {
+	dialer := &net.Dialer{Timeout: redisDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", r.addr)
+	if err != nil {
+		return err
+	}
+	r.conn, r.reader = conn, bufio.NewReader(conn)
+
+	var setup [][]string
+	switch {
+	case r.username != "" && r.password != "":
+		setup = append(setup, []string{"AUTH", r.username, r.password})
+	case r.password != "":
+		setup = append(setup, []string{"AUTH", r.password})
+	}
+	if r.db != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(r.db)})
+	}
+	for _, args := range setup {
+		if _, err := r.roundTrip(ctx, args); err != nil {
+			_ = r.closeConn()
+			return fmt.Errorf("redis %s failed: %w", args[0], err)
+		}
+	}
+	return nil
+}
+
+func (r *RedisRegistry) closeConn() error {
+	if r.conn == nil {
+		return nil
+	}
+	err := r.conn.Close()
+	r.conn, r.reader = nil, nil
+	return err
+}
+
+func (r *RedisRegistry) roundTrip(ctx context.Context, args []string) (any, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(redisDialTimeout)
+	}
+	if err := r.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(r.conn, b.String()); err != nil {
+		return nil, err
+	}
+	return readRedisReply(r.reader)
+}
+
+// redisError is an error reply of Redis; the connection stays usable.
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// readRedisReply reads a simple string, error, integer or bulk string reply.
+func readRedisReply(reader *bufio.Reader) (any, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty redis reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis reply %q", line)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	}
+	return nil, fmt.Errorf("unexpected redis reply %q", line)
+}
@@ -0,0 +1,48 @@
+package affinity
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DBRegistry stores claims in the cc_session_affinity table (PostgreSQL).
+type DBRegistry struct {
+	db *sql.DB
+}
+
+// NewDBRegistry creates a registry on the shared database.
+func NewDBRegistry(db *sql.DB) *DBRegistry {
+	return &DBRegistry{db: db}
+}
+
+// Claim upserts the claim of instance on the conversation.
+func (r *DBRegistry) Claim(ctx context.Context, conversationID int32, instance *Instance) error {
+	query := `
+		INSERT INTO cc_session_affinity (conversation_id, instance_id, instance_url, updated_ts)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (conversation_id) DO UPDATE
+		SET instance_id = EXCLUDED.instance_id, instance_url = EXCLUDED.instance_url, updated_ts = EXCLUDED.updated_ts`
+	if _, err := r.db.ExecContext(ctx, query, conversationID, instance.ID, instance.URL, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to claim session of conversation %d: %w", conversationID, err)
+	}
+	return nil
+}
+
+// Owner returns the instance that claimed the conversation within SessionTTL.
+func (r *DBRegistry) Owner(ctx context.Context, conversationID int32) (*Instance, error) {
+	query := `
+		SELECT instance_id, instance_url FROM cc_session_affinity
+		WHERE conversation_id = $1 AND updated_ts > $2`
+	instance := &Instance{}
+	err := r.db.QueryRowContext(ctx, query, conversationID, time.Now().Add(-SessionTTL).Unix()).Scan(&instance.ID, &instance.URL)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session owner of conversation %d: %w", conversationID, err)
+	}
+	return instance, nil
+}
@@ -8,6 +8,7 @@
 // - JWT access tokens: Short-lived tokens (15 minutes) for API access
 // - JWT refresh tokens: Long-lived tokens (30 days) for obtaining new access tokens
 // - Personal Access Tokens (PAT): Long-lived tokens for programmatic access
+// - Stream tickets: Single-use tokens (30 seconds) for the chat stream of EventSource clients
 package auth
 
 import (
@@ -50,6 +51,12 @@ const (
 
 	// PersonalAccessTokenPrefix is the prefix for PAT tokens.
 	PersonalAccessTokenPrefix = "memos_pat_"
+
+	// StreamTicketAudienceName is the audience claim for stream tickets.
+	StreamTicketAudienceName = "user.stream-ticket"
+
+	// StreamTicketDuration is the lifetime of stream tickets (30 seconds).
+	StreamTicketDuration = 30 * time.Second
 )
 
 // ClaimsMessage represents the claims structure in a JWT token.
@@ -84,6 +91,13 @@ type RefreshTokenClaims struct {
 	jwt.RegisteredClaims
 }
 
+// StreamTicketClaims contains claims for single-use stream tickets.
+// The ID claim identifies the ticket so that it is accepted only once.
+type StreamTicketClaims struct {
+	Type string `json:"type"` // "stream-ticket"
+	jwt.RegisteredClaims
+}
+
 // GenerateAccessToken generates a JWT access token for a user.
 //
 // Parameters:
@@ -186,6 +200,33 @@ func GenerateRefreshToken(userID int32, tokenID string, secret []byte) (string,
 	return tokenString, expiresAt, nil
 }
 
+// GenerateStreamTicket generates a short-lived stream ticket.
+func GenerateStreamTicket(userID int32, secret []byte) (string, time.Time, error) {
+	expiresAt := time.Now().Add(StreamTicketDuration)
+
+	claims := &StreamTicketClaims{
+		Type: "stream-ticket",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        util.GenUUID(),
+			Issuer:    Issuer,
+			Audience:  jwt.ClaimStrings{StreamTicketAudienceName},
+			Subject:   fmt.Sprint(userID),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = KeyID
+
+	tokenString, err := token.SignedString(secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expiresAt, nil
+}
+
 // GeneratePersonalAccessToken generates a random PAT string.
 func GeneratePersonalAccessToken() string {
 	randomStr, err := util.RandomString(32)
@@ -247,3 +288,21 @@ func ParseRefreshToken(tokenString string, secret []byte) (*RefreshTokenClaims,
 	}
 	return claims, nil
 }
+
+// ParseStreamTicket parses and validates a stream ticket. Callers must reject
+// the tickets already used, by their ID.
+func ParseStreamTicket(tokenString string, secret []byte) (*StreamTicketClaims, error) {
+	claims := &StreamTicketClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, verifyJWTKeyFunc(secret),
+		jwt.WithIssuer(Issuer),
+		jwt.WithAudience(StreamTicketAudienceName),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Type != "stream-ticket" || claims.ID == "" {
+		return nil, errors.New("invalid token type: expected stream ticket")
+	}
+	return claims, nil
+}
@@ -147,6 +147,52 @@ func TestParseRefreshToken(t *testing.T) {
 	})
 }
 
+func TestParseStreamTicket(t *testing.T) {
+	secret := []byte("test-secret")
+
+	t.Run("parses valid stream ticket", func(t *testing.T) {
+		ticket, expiresAt, err := GenerateStreamTicket(1, secret)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(StreamTicketDuration), expiresAt, time.Second)
+
+		claims, err := ParseStreamTicket(ticket, secret)
+		require.NoError(t, err)
+		assert.Equal(t, "1", claims.Subject)
+		assert.Equal(t, "stream-ticket", claims.Type)
+		assert.NotEmpty(t, claims.ID)
+	})
+
+	t.Run("generates unique tickets", func(t *testing.T) {
+		ticket1, _, err := GenerateStreamTicket(1, secret)
+		require.NoError(t, err)
+		ticket2, _, err := GenerateStreamTicket(1, secret)
+		require.NoError(t, err)
+
+		claims1, err := ParseStreamTicket(ticket1, secret)
+		require.NoError(t, err)
+		claims2, err := ParseStreamTicket(ticket2, secret)
+		require.NoError(t, err)
+		assert.NotEqual(t, claims1.ID, claims2.ID)
+	})
+
+	t.Run("fails with wrong secret", func(t *testing.T) {
+		ticket, _, err := GenerateStreamTicket(1, secret)
+		require.NoError(t, err)
+
+		_, err = ParseStreamTicket(ticket, []byte("wrong-secret"))
+		assert.Error(t, err)
+	})
+
+	t.Run("fails with access token", func(t *testing.T) {
+		accessToken, _, err := GenerateAccessTokenV2(1, "testuser", "USER", "ACTIVE", secret)
+		require.NoError(t, err)
+
+		_, err = ParseStreamTicket(accessToken, secret)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid audience")
+	})
+}
+
 func TestGeneratePersonalAccessToken(t *testing.T) {
 	t.Run("generates token with correct prefix", func(t *testing.T) {
 		token := GeneratePersonalAccessToken()
@@ -0,0 +1,137 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is the Telegram Bot API.
+const DefaultAPIURL = "https://api.telegram.org"
+
+// User is a Telegram user.
+type User struct {
+	ID       int64  `json:"id"`
+	IsBot    bool   `json:"is_bot"`
+	Username string `json:"username,omitempty"`
+}
+
+// Chat is a Telegram chat.
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+// Message is a Telegram message.
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from,omitempty"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text,omitempty"`
+}
+
+// Update is an update of the bot.
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message,omitempty"`
+}
+
+// client calls the Bot API methods of a bot. Unlike tgbotapi, its requests
+// follow their context, so that shutdown interrupts long polling.
+type client struct {
+	http    *http.Client
+	baseURL string
+	token   string
+}
+
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+}
+
+// call calls a Bot API method with JSON params and decodes its result.
+func (c *client) call(ctx context.Context, method string, params, result any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s params: %w", method, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/bot"+c.token+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		// The URL holds the token, which must stay out of the logs.
+		return fmt.Errorf("failed to call %s: %w", method, redact(err, c.token))
+	}
+	defer resp.Body.Close()
+	decoded := &apiResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(decoded); err != nil {
+		return fmt.Errorf("failed to decode %s response (status %d): %w", method, resp.StatusCode, err)
+	}
+	if !decoded.OK {
+		return fmt.Errorf("telegram %s failed (%d): %s", method, decoded.ErrorCode, decoded.Description)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(decoded.Result, result); err != nil {
+		return fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+	return nil
+}
+
+func (c *client) getMe(ctx context.Context) (*User, error) {
+	me := &User{}
+	if err := c.call(ctx, "getMe", struct{}{}, me); err != nil {
+		return nil, err
+	}
+	return me, nil
+}
+
+// getUpdates long-polls the message updates from offset on.
+func (c *client) getUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	params := map[string]any{
+		"offset":          offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": []string{"message"},
+	}
+	var updates []Update
+	if err := c.call(ctx, "getUpdates", params, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+func (c *client) sendMessage(ctx context.Context, chatID int64, text string) (*Message, error) {
+	msg := &Message{}
+	if err := c.call(ctx, "sendMessage", map[string]any{"chat_id": chatID, "text": text}, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// editMessageText replaces the text of a message. Edits to the same text are
+// not errors.
+func (c *client) editMessageText(ctx context.Context, chatID, messageID int64, text string) error {
+	err := c.call(ctx, "editMessageText", map[string]any{"chat_id": chatID, "message_id": messageID, "text": text}, nil)
+	if err != nil && strings.Contains(err.Error(), "message is not modified") {
+		return nil
+	}
+	return err
+}
+
+// redact removes the bot token from an error.
+func redact(err error, token string) error {
+	if token == "" || !strings.Contains(err.Error(), token) {
+		return err
+	}
+	return fmt.Errorf("%s", strings.ReplaceAll(err.Error(), token, "<token>"))
+}
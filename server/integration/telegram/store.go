@@ -0,0 +1,144 @@
+package telegram
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DBStore persists the links in the telegram_link table (PostgreSQL).
+type DBStore struct {
+	db *sql.DB
+}
+
+// NewDBStore creates a new link store.
+func NewDBStore(db *sql.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+const linkColumns = "user_id, enabled, telegram_user_id, telegram_username, conversation_id, created_ts, updated_ts"
+
+// GetLink implements Store.
+func (s *DBStore) GetLink(ctx context.Context, userID int32) (*Link, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+linkColumns+" FROM telegram_link WHERE user_id = $1", userID)
+	return getLink(row)
+}
+
+// GetLinkByTelegramUser implements Store.
+func (s *DBStore) GetLinkByTelegramUser(ctx context.Context, telegramUserID int64) (*Link, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+linkColumns+" FROM telegram_link WHERE telegram_user_id = $1", telegramUserID)
+	return getLink(row)
+}
+
+// SaveCode implements Store.
+func (s *DBStore) SaveCode(ctx context.Context, userID int32, code string, expiresTs int64) error {
+	now := time.Now().Unix()
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO telegram_link (user_id, link_code, link_code_expires_ts, created_ts, updated_ts)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (user_id) DO UPDATE SET
+			link_code = EXCLUDED.link_code,
+			link_code_expires_ts = EXCLUDED.link_code_expires_ts,
+			updated_ts = EXCLUDED.updated_ts`,
+		userID, code, expiresTs, now)
+	if err != nil {
+		return fmt.Errorf("failed to save telegram link code: %w", err)
+	}
+	return nil
+}
+
+// ClaimCode implements Store.
+func (s *DBStore) ClaimCode(ctx context.Context, code string, account *User, now int64) (*Link, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The account moves from the user it linked before, if any.
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE telegram_link SET telegram_user_id = NULL, telegram_username = '', conversation_id = 0, updated_ts = $2
+		WHERE telegram_user_id = $1 AND link_code IS DISTINCT FROM $3`,
+		account.ID, now, code); err != nil {
+		return nil, fmt.Errorf("failed to unlink telegram account: %w", err)
+	}
+	row := tx.QueryRowContext(ctx, `
+		UPDATE telegram_link SET
+			telegram_user_id = $2,
+			telegram_username = $3,
+			conversation_id = CASE WHEN telegram_user_id = $2 THEN conversation_id ELSE 0 END,
+			link_code = NULL,
+			link_code_expires_ts = 0,
+			updated_ts = $4
+		WHERE link_code = $1 AND link_code_expires_ts > $4
+		RETURNING `+linkColumns,
+		code, account.ID, account.Username, now)
+	link, err := getLink(row)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit telegram link: %w", err)
+	}
+	return link, nil
+}
+
+// SetEnabled implements Store.
+func (s *DBStore) SetEnabled(ctx context.Context, userID int32, enabled bool) (*Link, error) {
+	row := s.db.QueryRowContext(ctx,
+		"UPDATE telegram_link SET enabled = $2, updated_ts = $3 WHERE user_id = $1 RETURNING "+linkColumns,
+		userID, enabled, time.Now().Unix())
+	return getLink(row)
+}
+
+// SetConversation implements Store.
+func (s *DBStore) SetConversation(ctx context.Context, userID, conversationID int32) error {
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE telegram_link SET conversation_id = $2, updated_ts = $3 WHERE user_id = $1",
+		userID, conversationID, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("failed to set telegram conversation: %w", err)
+	}
+	return nil
+}
+
+// DeleteLink implements Store.
+func (s *DBStore) DeleteLink(ctx context.Context, userID int32) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM telegram_link WHERE user_id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete telegram link: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// getLink scans a link, mapping a missing row to ErrNotFound.
+func getLink(row rowScanner) (*Link, error) {
+	link, err := scanLink(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get telegram link: %w", err)
+	}
+	return link, nil
+}
+
+func scanLink(row rowScanner) (*Link, error) {
+	link := &Link{}
+	var telegramUserID sql.NullInt64
+	if err := row.Scan(&link.UserID, &link.Enabled, &telegramUserID, &link.TelegramUsername, &link.ConversationID, &link.CreatedTs, &link.UpdatedTs); err != nil {
+		return nil, err
+	}
+	link.TelegramUserID = telegramUserID.Int64
+	link.Linked = telegramUserID.Valid
+	return link, nil
+}
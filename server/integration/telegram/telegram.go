@@ -0,0 +1,510 @@
+// Package telegram is the optional Telegram gateway of the instance.
+//
+// A single instance bot, configured by DIVINESENSE_TELEGRAM_BOT_TOKEN, long
+// polls the Bot API for updates, so the instance needs no public URL. Users
+// enable the gateway by linking their Telegram account: they create a link
+// code in their settings and send "/start <code>" to the bot in a private
+// chat. Their messages then run through the chat path in an AIConversation,
+// the answer streaming back as edits of a single Telegram message. "/new"
+// starts a new conversation and "/memo <text>" saves a memo.
+//
+// Unlike the chat apps channels, which receive the webhooks of a bot each
+// user registers, the gateway shares one bot across the users of the
+// instance. Only one replica may poll a bot, so multi-instance deployments
+// configure the token on one replica only.
+package telegram
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// LinkCodeTTL is how long link codes stay valid.
+	LinkCodeTTL = 15 * time.Minute
+	// MaxMessageRunes is the longest text of a Telegram message; longer
+	// answers continue in new messages.
+	MaxMessageRunes = 4096
+
+	// pollTimeout is the long polling timeout of getUpdates.
+	pollTimeout = 30 * time.Second
+	// editInterval throttles the edits of streaming answers, below the rate
+	// limits of the Bot API.
+	editInterval = 1500 * time.Millisecond
+	// retryDelay is the wait after failed polls.
+	retryDelay = 5 * time.Second
+	// placeholder is the text of answers until their first edit.
+	placeholder = "…"
+)
+
+// ErrNotFound is returned for users without a link and for unknown or expired
+// link codes.
+var ErrNotFound = errors.New("telegram link not found")
+
+// Link is the Telegram link of a user.
+type Link struct {
+	UserID int32 `json:"user_id"`
+	// Enabled is false while the user paused the gateway; the link stays.
+	Enabled bool `json:"enabled"`
+	// Linked is whether a Telegram account claimed the link.
+	Linked           bool   `json:"linked"`
+	TelegramUserID   int64  `json:"telegram_user_id,omitempty"`
+	TelegramUsername string `json:"telegram_username,omitempty"`
+	// ConversationID is the conversation of the next messages; 0 starts a
+	// new one.
+	ConversationID int32 `json:"conversation_id,omitempty"`
+	CreatedTs      int64 `json:"created_ts"`
+	UpdatedTs      int64 `json:"updated_ts"`
+}
+
+// LinkCode is a code linking the Telegram account sending it to a user.
+type LinkCode struct {
+	Code      string `json:"code"`
+	ExpiresTs int64  `json:"expires_ts"`
+	// URL opens the chat with the bot, the code prefilled, once the bot's
+	// username is known.
+	URL string `json:"url,omitempty"`
+}
+
+// Store persists the links.
+type Store interface {
+	GetLink(ctx context.Context, userID int32) (*Link, error)
+	GetLinkByTelegramUser(ctx context.Context, telegramUserID int64) (*Link, error)
+	// SaveCode sets the link code of a user, creating their link enabled.
+	SaveCode(ctx context.Context, userID int32, code string, expiresTs int64) error
+	// ClaimCode links the Telegram account to the user holding the unexpired
+	// code, unlinking it from any other user, and clears the code.
+	ClaimCode(ctx context.Context, code string, account *User, now int64) (*Link, error)
+	SetEnabled(ctx context.Context, userID int32, enabled bool) (*Link, error)
+	SetConversation(ctx context.Context, userID, conversationID int32) error
+	DeleteLink(ctx context.Context, userID int32) error
+}
+
+// Chatter runs messages through the chat path.
+type Chatter interface {
+	// Chat sends a message of the user to the conversation, or to a new one
+	// when conversationID is 0 or the conversation is gone, calling answer
+	// with the answer so far as it streams. It returns the conversation.
+	// Errors are user-facing.
+	Chat(ctx context.Context, userID, conversationID int32, message string, answer func(text string)) (int32, error)
+}
+
+// Memos saves the memos of /memo.
+type Memos interface {
+	// CreateMemo creates a private memo and returns its UID.
+	CreateMemo(ctx context.Context, userID int32, content string) (string, error)
+}
+
+// Config configures the gateway.
+type Config struct {
+	// Token is the token of the bot.
+	Token string
+	// APIURL is the Bot API, DefaultAPIURL when empty.
+	APIURL string
+	// InstanceURL links the saved memos when set.
+	InstanceURL string
+}
+
+// Gateway relays the private chats of linked users with the bot.
+type Gateway struct {
+	config       Config
+	api          *client
+	store        Store
+	chat         Chatter
+	memos        Memos
+	editInterval time.Duration
+
+	mu          sync.Mutex
+	botUsername string
+	// answering holds the users whose previous message is still answering.
+	answering map[int32]bool
+}
+
+// New creates a gateway.
+func New(config Config, store Store, chat Chatter, memos Memos) *Gateway {
+	if config.APIURL == "" {
+		config.APIURL = DefaultAPIURL
+	}
+	return &Gateway{
+		config: config,
+		api: &client{
+			http:    &http.Client{Timeout: pollTimeout + 30*time.Second},
+			baseURL: strings.TrimRight(config.APIURL, "/"),
+			token:   config.Token,
+		},
+		store:        store,
+		chat:         chat,
+		memos:        memos,
+		editInterval: editInterval,
+		answering:    map[int32]bool{},
+	}
+}
+
+// BotUsername returns the username of the bot, empty until the gateway
+// reached the Bot API.
+func (g *Gateway) BotUsername() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.botUsername
+}
+
+// Link returns the link of a user.
+func (g *Gateway) Link(ctx context.Context, userID int32) (*Link, error) {
+	return g.store.GetLink(ctx, userID)
+}
+
+// CreateLinkCode creates a link code for a user, replacing their previous
+// one. Links of other Telegram accounts stay until a code is claimed.
+func (g *Gateway) CreateLinkCode(ctx context.Context, userID int32) (*LinkCode, error) {
+	code, err := newCode()
+	if err != nil {
+		return nil, err
+	}
+	expires := time.Now().Add(LinkCodeTTL).Unix()
+	if err := g.store.SaveCode(ctx, userID, code, expires); err != nil {
+		return nil, err
+	}
+	linkCode := &LinkCode{Code: code, ExpiresTs: expires}
+	if username := g.BotUsername(); username != "" {
+		linkCode.URL = "https://t.me/" + username + "?start=" + code
+	}
+	return linkCode, nil
+}
+
+// SetEnabled enables or pauses the gateway for a user.
+func (g *Gateway) SetEnabled(ctx context.Context, userID int32, enabled bool) (*Link, error) {
+	return g.store.SetEnabled(ctx, userID, enabled)
+}
+
+// Unlink deletes the link of a user.
+func (g *Gateway) Unlink(ctx context.Context, userID int32) error {
+	return g.store.DeleteLink(ctx, userID)
+}
+
+// Notify sends a text message to the Telegram account of a user, split
+// beyond MaxMessageRunes. It returns ErrNotFound for users without a linked
+// account or who paused the gateway.
+func (g *Gateway) Notify(ctx context.Context, userID int32, text string) error {
+	link, err := g.store.GetLink(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !link.Linked || !link.Enabled {
+		return ErrNotFound
+	}
+	// The chat of a private chat is the Telegram user
+	for _, part := range split(text, MaxMessageRunes) {
+		if _, err := g.api.sendMessage(ctx, link.TelegramUserID, part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run polls the updates of the bot until the context is canceled.
+func (g *Gateway) Run(ctx context.Context) {
+	for g.BotUsername() == "" {
+		me, err := g.api.getMe(ctx)
+		if err == nil {
+			g.mu.Lock()
+			g.botUsername = me.Username
+			g.mu.Unlock()
+			slog.Info("telegram gateway connected", "bot", me.Username)
+			break
+		}
+		slog.Warn("failed to reach telegram bot", "error", err)
+		if !sleep(ctx, retryDelay) {
+			return
+		}
+	}
+
+	var offset int64
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for ctx.Err() == nil {
+		next, err := g.poll(ctx, offset, &wg)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			slog.Warn("failed to poll telegram updates", "error", err)
+			if !sleep(ctx, retryDelay) {
+				return
+			}
+			continue
+		}
+		offset = next
+	}
+}
+
+// poll handles the updates from offset on, each in its own goroutine, and
+// returns the offset of the next poll.
+func (g *Gateway) poll(ctx context.Context, offset int64, wg *sync.WaitGroup) (int64, error) {
+	updates, err := g.api.getUpdates(ctx, offset, pollTimeout)
+	if err != nil {
+		return offset, err
+	}
+	for _, update := range updates {
+		if update.UpdateID >= offset {
+			offset = update.UpdateID + 1
+		}
+		if update.Message == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(msg *Message) {
+			defer wg.Done()
+			g.handle(ctx, msg)
+		}(update.Message)
+	}
+	return offset, nil
+}
+
+// handle answers a message. Only the private chats of people are served, so
+// that answers never reach group members.
+func (g *Gateway) handle(ctx context.Context, msg *Message) {
+	if msg.From == nil || msg.From.IsBot || msg.Chat.Type != "private" {
+		return
+	}
+	text := strings.TrimSpace(msg.Text)
+	if text == "" {
+		g.reply(ctx, msg.Chat.ID, "Only text messages are supported.")
+		return
+	}
+	command, arg := parseCommand(text)
+	if command == "/start" && arg != "" {
+		g.claim(ctx, msg, arg)
+		return
+	}
+
+	link, err := g.store.GetLinkByTelegramUser(ctx, msg.From.ID)
+	if errors.Is(err, ErrNotFound) {
+		g.reply(ctx, msg.Chat.ID, "This Telegram account is not linked. Create a link code in the Telegram settings of DivineSense, then send /start <code>.")
+		return
+	}
+	if err != nil {
+		slog.Error("failed to get telegram link", "telegram_user_id", msg.From.ID, "error", err)
+		g.reply(ctx, msg.Chat.ID, "Something went wrong, please try again later.")
+		return
+	}
+	if !link.Enabled {
+		g.reply(ctx, msg.Chat.ID, "The Telegram gateway is paused for your account. Enable it in the Telegram settings of DivineSense.")
+		return
+	}
+
+	switch command {
+	case "":
+		g.answer(ctx, link, msg.Chat.ID, text)
+	case "/new":
+		if err := g.store.SetConversation(ctx, link.UserID, 0); err != nil {
+			slog.Error("failed to reset telegram conversation", "user_id", link.UserID, "error", err)
+			g.reply(ctx, msg.Chat.ID, "Something went wrong, please try again later.")
+			return
+		}
+		g.reply(ctx, msg.Chat.ID, "Started a new conversation.")
+	case "/memo":
+		g.saveMemo(ctx, link, msg.Chat.ID, arg)
+	default:
+		g.reply(ctx, msg.Chat.ID, helpText)
+	}
+}
+
+const helpText = "Send a message to chat with DivineSense.\n/memo <text> saves a memo.\n/new starts a new conversation."
+
+// claim links the sender to the user of a link code.
+func (g *Gateway) claim(ctx context.Context, msg *Message, code string) {
+	link, err := g.store.ClaimCode(ctx, code, msg.From, time.Now().Unix())
+	if errors.Is(err, ErrNotFound) {
+		g.reply(ctx, msg.Chat.ID, "This link code is invalid or expired. Create a new one in the Telegram settings of DivineSense.")
+		return
+	}
+	if err != nil {
+		slog.Error("failed to claim telegram link code", "telegram_user_id", msg.From.ID, "error", err)
+		g.reply(ctx, msg.Chat.ID, "Something went wrong, please try again later.")
+		return
+	}
+	slog.Info("telegram account linked", "user_id", link.UserID, "telegram_user_id", link.TelegramUserID)
+	g.reply(ctx, msg.Chat.ID, "Linked to DivineSense.\n"+helpText)
+}
+
+// saveMemo saves the text of /memo as a memo.
+func (g *Gateway) saveMemo(ctx context.Context, link *Link, chatID int64, content string) {
+	if content == "" {
+		g.reply(ctx, chatID, "Usage: /memo <text>")
+		return
+	}
+	uid, err := g.memos.CreateMemo(ctx, link.UserID, content)
+	if err != nil {
+		slog.Error("failed to save telegram memo", "user_id", link.UserID, "error", err)
+		g.reply(ctx, chatID, "Failed to save the memo, please try again later.")
+		return
+	}
+	if g.config.InstanceURL == "" {
+		g.reply(ctx, chatID, "Memo saved.")
+		return
+	}
+	g.reply(ctx, chatID, "Memo saved: "+strings.TrimRight(g.config.InstanceURL, "/")+"/memos/"+uid)
+}
+
+// answer chats with the message in the conversation of the link, editing a
+// placeholder message with the answer as it streams.
+func (g *Gateway) answer(ctx context.Context, link *Link, chatID int64, text string) {
+	if !g.startAnswer(link.UserID) {
+		g.reply(ctx, chatID, "Still answering your previous message, please wait.")
+		return
+	}
+	defer g.finishAnswer(link.UserID)
+
+	sent, err := g.api.sendMessage(ctx, chatID, placeholder)
+	if err != nil {
+		slog.Warn("failed to send telegram message", "user_id", link.UserID, "error", err)
+		return
+	}
+
+	var mu sync.Mutex
+	var latest string
+	done := make(chan struct{})
+	edits := make(chan struct{})
+	// Edits run apart from the chat, which never waits for the Bot API.
+	go func() {
+		defer close(edits)
+		ticker := time.NewTicker(g.editInterval)
+		defer ticker.Stop()
+		var shown string
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				mu.Lock()
+				current := latest
+				mu.Unlock()
+				if current == shown || current == "" {
+					continue
+				}
+				shown = current
+				if err := g.api.editMessageText(ctx, chatID, sent.MessageID, truncate(current, MaxMessageRunes)); err != nil {
+					slog.Debug("failed to edit telegram answer", "user_id", link.UserID, "error", err)
+				}
+			}
+		}
+	}()
+
+	conversationID, chatErr := g.chat.Chat(ctx, link.UserID, link.ConversationID, text, func(answer string) {
+		mu.Lock()
+		latest = answer
+		mu.Unlock()
+	})
+	close(done)
+	<-edits
+
+	if conversationID != 0 && conversationID != link.ConversationID {
+		if err := g.store.SetConversation(context.WithoutCancel(ctx), link.UserID, conversationID); err != nil {
+			slog.Error("failed to save telegram conversation", "user_id", link.UserID, "conversation_id", conversationID, "error", err)
+		}
+	}
+
+	final := strings.TrimSpace(latest)
+	switch {
+	case chatErr != nil:
+		final = "Sorry, the answer failed: " + chatErr.Error()
+	case final == "":
+		final = "(no answer)"
+	}
+	chunks := split(final, MaxMessageRunes)
+	if err := g.api.editMessageText(ctx, chatID, sent.MessageID, chunks[0]); err != nil {
+		slog.Warn("failed to edit telegram answer", "user_id", link.UserID, "error", err)
+	}
+	for _, chunk := range chunks[1:] {
+		g.reply(ctx, chatID, chunk)
+	}
+}
+
+func (g *Gateway) startAnswer(userID int32) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.answering[userID] {
+		return false
+	}
+	g.answering[userID] = true
+	return true
+}
+
+func (g *Gateway) finishAnswer(userID int32) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.answering, userID)
+}
+
+// reply sends a text message, logging failures.
+func (g *Gateway) reply(ctx context.Context, chatID int64, text string) {
+	if _, err := g.api.sendMessage(ctx, chatID, text); err != nil {
+		slog.Warn("failed to send telegram message", "chat_id", chatID, "error", err)
+	}
+}
+
+// parseCommand splits a "/command@bot arg" message. Plain messages have no
+// command.
+func parseCommand(text string) (string, string) {
+	if !strings.HasPrefix(text, "/") {
+		return "", text
+	}
+	command, arg, _ := strings.Cut(text, " ")
+	if i := strings.IndexByte(command, '@'); i >= 0 {
+		command = command[:i]
+	}
+	return strings.ToLower(command), strings.TrimSpace(arg)
+}
+
+// newCode returns a random link code, valid as a deep link start parameter.
+func newCode() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate link code: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// split splits a text into chunks of at most limit runes.
+func split(text string, limit int) []string {
+	var chunks []string
+	for utf8.RuneCountInString(text) > limit {
+		i := 0
+		for n := 0; n < limit; n++ {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			i += size
+		}
+		chunks = append(chunks, text[:i])
+		text = text[i:]
+	}
+	return append(chunks, text)
+}
+
+// truncate shortens a streaming answer to one message.
+func truncate(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	return split(text, limit-1)[0] + "…"
+}
+
+// sleep waits for d, returning false when the context ends first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,379 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBot is a Bot API recording the calls of the gateway.
+type fakeBot struct {
+	mu      sync.Mutex
+	calls   []botCall
+	updates []Update
+	nextID  int64
+}
+
+type botCall struct {
+	method string
+	params map[string]any
+}
+
+func (b *fakeBot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	params := map[string]any{}
+	_ = json.NewDecoder(r.Body).Decode(&params)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls = append(b.calls, botCall{method: method, params: params})
+	var result any = true
+	switch method {
+	case "getMe":
+		result = User{ID: 1, IsBot: true, Username: "divinesense_bot"}
+	case "getUpdates":
+		result = b.updates
+		b.updates = nil
+	case "sendMessage":
+		b.nextID++
+		result = Message{MessageID: b.nextID, Chat: Chat{ID: int64(params["chat_id"].(float64))}}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+}
+
+// texts returns the texts sent or edited, in order.
+func (b *fakeBot) texts() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var texts []string
+	for _, call := range b.calls {
+		if call.method == "sendMessage" || call.method == "editMessageText" {
+			texts = append(texts, call.params["text"].(string))
+		}
+	}
+	return texts
+}
+
+// memStore is an in-memory Store.
+type memStore struct {
+	mu    sync.Mutex
+	links map[int32]*Link
+	codes map[string]int32
+	exp   map[string]int64
+}
+
+func newMemStore() *memStore {
+	return &memStore{links: map[int32]*Link{}, codes: map[string]int32{}, exp: map[string]int64{}}
+}
+
+func (s *memStore) GetLink(_ context.Context, userID int32) (*Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if link, ok := s.links[userID]; ok {
+		copied := *link
+		return &copied, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (s *memStore) GetLinkByTelegramUser(_ context.Context, telegramUserID int64) (*Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, link := range s.links {
+		if link.Linked && link.TelegramUserID == telegramUserID {
+			copied := *link
+			return &copied, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *memStore) SaveCode(_ context.Context, userID int32, code string, expiresTs int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.links[userID]; !ok {
+		s.links[userID] = &Link{UserID: userID, Enabled: true}
+	}
+	s.codes[code] = userID
+	s.exp[code] = expiresTs
+	return nil
+}
+
+func (s *memStore) ClaimCode(_ context.Context, code string, account *User, now int64) (*Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	userID, ok := s.codes[code]
+	if !ok || s.exp[code] <= now {
+		return nil, ErrNotFound
+	}
+	delete(s.codes, code)
+	for _, link := range s.links {
+		if link.TelegramUserID == account.ID {
+			link.Linked, link.TelegramUserID, link.ConversationID = false, 0, 0
+		}
+	}
+	link := s.links[userID]
+	link.Linked, link.TelegramUserID, link.TelegramUsername = true, account.ID, account.Username
+	copied := *link
+	return &copied, nil
+}
+
+func (s *memStore) SetEnabled(_ context.Context, userID int32, enabled bool) (*Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	link, ok := s.links[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	link.Enabled = enabled
+	copied := *link
+	return &copied, nil
+}
+
+func (s *memStore) SetConversation(_ context.Context, userID, conversationID int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if link, ok := s.links[userID]; ok {
+		link.ConversationID = conversationID
+	}
+	return nil
+}
+
+func (s *memStore) DeleteLink(_ context.Context, userID int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.links[userID]; !ok {
+		return ErrNotFound
+	}
+	delete(s.links, userID)
+	return nil
+}
+
+// fakeChat streams a canned answer in two chunks.
+type fakeChat struct {
+	answer         string
+	err            error
+	conversationID int32
+	messages       []string
+}
+
+func (c *fakeChat) Chat(_ context.Context, _, conversationID int32, message string, answer func(string)) (int32, error) {
+	c.messages = append(c.messages, message)
+	if conversationID == 0 {
+		conversationID = c.conversationID
+	}
+	half := len(c.answer) / 2
+	answer(c.answer[:half])
+	answer(c.answer)
+	return conversationID, c.err
+}
+
+type fakeMemos struct {
+	contents []string
+}
+
+func (m *fakeMemos) CreateMemo(_ context.Context, _ int32, content string) (string, error) {
+	m.contents = append(m.contents, content)
+	return "abc123", nil
+}
+
+func newTestGateway(t *testing.T) (*Gateway, *fakeBot, *memStore, *fakeChat, *fakeMemos) {
+	t.Helper()
+	bot := &fakeBot{}
+	server := httptest.NewServer(bot)
+	t.Cleanup(server.Close)
+	st := newMemStore()
+	chat := &fakeChat{answer: "Hello from DivineSense", conversationID: 42}
+	memos := &fakeMemos{}
+	g := New(Config{Token: "secret-token", APIURL: server.URL, InstanceURL: "https://notes.example.com/"}, st, chat, memos)
+	g.editInterval = time.Millisecond
+	return g, bot, st, chat, memos
+}
+
+func privateMessage(from int64, text string) *Message {
+	return &Message{MessageID: 1, From: &User{ID: from, Username: "alice"}, Chat: Chat{ID: from, Type: "private"}, Text: text}
+}
+
+// link links the Telegram user 100 to the user 7.
+func link(t *testing.T, g *Gateway) {
+	t.Helper()
+	code, err := g.CreateLinkCode(context.Background(), 7)
+	require.NoError(t, err)
+	g.handle(context.Background(), privateMessage(100, "/start "+code.Code))
+}
+
+func TestLinkCode(t *testing.T) {
+	g, bot, st, _, _ := newTestGateway(t)
+	ctx := context.Background()
+
+	code, err := g.CreateLinkCode(ctx, 7)
+	require.NoError(t, err)
+	assert.Len(t, code.Code, 24)
+	assert.Empty(t, code.URL, "no URL before the bot is known")
+	g.botUsername = "divinesense_bot"
+	code, err = g.CreateLinkCode(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, "https://t.me/divinesense_bot?start="+code.Code, code.URL)
+
+	g.handle(ctx, privateMessage(100, "/start unknown"))
+	g.handle(ctx, privateMessage(100, "/start@divinesense_bot "+code.Code))
+	got, err := st.GetLink(ctx, 7)
+	require.NoError(t, err)
+	assert.True(t, got.Linked)
+	assert.Equal(t, int64(100), got.TelegramUserID)
+	assert.Equal(t, "alice", got.TelegramUsername)
+
+	texts := bot.texts()
+	require.Len(t, texts, 2)
+	assert.Contains(t, texts[0], "invalid or expired")
+	assert.Contains(t, texts[1], "Linked to DivineSense")
+}
+
+func TestHandleUnlinkedAndPaused(t *testing.T) {
+	g, bot, _, chat, _ := newTestGateway(t)
+	ctx := context.Background()
+
+	g.handle(ctx, privateMessage(100, "hello"))
+	link(t, g)
+	_, err := g.SetEnabled(ctx, 7, false)
+	require.NoError(t, err)
+	g.handle(ctx, privateMessage(100, "hello"))
+
+	texts := bot.texts()
+	require.Len(t, texts, 3)
+	assert.Contains(t, texts[0], "not linked")
+	assert.Contains(t, texts[2], "paused")
+	assert.Empty(t, chat.messages)
+}
+
+func TestNotify(t *testing.T) {
+	g, bot, _, _, _ := newTestGateway(t)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, g.Notify(ctx, 7, "Reminder: standup"), ErrNotFound)
+	link(t, g)
+	require.NoError(t, g.Notify(ctx, 7, "Reminder: standup"))
+	_, err := g.SetEnabled(ctx, 7, false)
+	require.NoError(t, err)
+	assert.ErrorIs(t, g.Notify(ctx, 7, "Reminder: retro"), ErrNotFound)
+
+	texts := bot.texts()
+	assert.Equal(t, "Reminder: standup", texts[len(texts)-1])
+	assert.NotContains(t, texts, "Reminder: retro")
+}
+
+func TestHandleIgnoresGroupsAndBots(t *testing.T) {
+	g, bot, _, _, _ := newTestGateway(t)
+	link(t, g)
+	group := privateMessage(100, "hello")
+	group.Chat = Chat{ID: -5, Type: "group"}
+	g.handle(context.Background(), group)
+	robot := privateMessage(100, "hello")
+	robot.From.IsBot = true
+	g.handle(context.Background(), robot)
+
+	assert.Len(t, bot.texts(), 1, "only the link reply")
+}
+
+func TestMemo(t *testing.T) {
+	g, bot, _, _, memos := newTestGateway(t)
+	link(t, g)
+
+	g.handle(context.Background(), privateMessage(100, "/memo"))
+	g.handle(context.Background(), privateMessage(100, "/memo buy  milk\nand eggs"))
+
+	assert.Equal(t, []string{"buy  milk\nand eggs"}, memos.contents)
+	texts := bot.texts()
+	assert.Equal(t, "Usage: /memo <text>", texts[1])
+	assert.Equal(t, "Memo saved: https://notes.example.com/memos/abc123", texts[2])
+}
+
+func TestAnswerStreamsIntoConversation(t *testing.T) {
+	g, bot, st, chat, _ := newTestGateway(t)
+	ctx := context.Background()
+	link(t, g)
+
+	g.handle(ctx, privateMessage(100, "hi"))
+	got, err := st.GetLink(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, int32(42), got.ConversationID)
+	texts := bot.texts()
+	assert.Equal(t, placeholder, texts[1])
+	assert.Equal(t, "Hello from DivineSense", texts[len(texts)-1])
+
+	g.handle(ctx, privateMessage(100, "/new"))
+	got, err = st.GetLink(ctx, 7)
+	require.NoError(t, err)
+	assert.Zero(t, got.ConversationID)
+	assert.Equal(t, []string{"hi"}, chat.messages)
+}
+
+func TestAnswerFailureAndLongAnswers(t *testing.T) {
+	g, bot, _, chat, _ := newTestGateway(t)
+	link(t, g)
+
+	chat.err = errors.New("rate limit exceeded")
+	g.handle(context.Background(), privateMessage(100, "hi"))
+	texts := bot.texts()
+	assert.Equal(t, "Sorry, the answer failed: rate limit exceeded", texts[len(texts)-1])
+
+	chat.err = nil
+	chat.answer = strings.Repeat("好", MaxMessageRunes+10)
+	g.handle(context.Background(), privateMessage(100, "long"))
+	texts = bot.texts()
+	assert.Equal(t, strings.Repeat("好", 10), texts[len(texts)-1])
+	assert.Equal(t, strings.Repeat("好", MaxMessageRunes), texts[len(texts)-2])
+}
+
+func TestAnswerRejectsConcurrentMessages(t *testing.T) {
+	g, bot, _, _, _ := newTestGateway(t)
+	link(t, g)
+	require.True(t, g.startAnswer(7))
+
+	g.handle(context.Background(), privateMessage(100, "hi"))
+	texts := bot.texts()
+	assert.Contains(t, texts[len(texts)-1], "Still answering")
+}
+
+func TestPoll(t *testing.T) {
+	g, bot, _, _, _ := newTestGateway(t)
+	bot.updates = []Update{
+		{UpdateID: 10, Message: privateMessage(100, "hello")},
+		{UpdateID: 11},
+	}
+	var wg sync.WaitGroup
+	offset, err := g.poll(context.Background(), 0, &wg)
+	wg.Wait()
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), offset)
+	assert.Contains(t, bot.texts()[0], "not linked")
+}
+
+func TestParseCommand(t *testing.T) {
+	for _, tc := range []struct {
+		text, command, arg string
+	}{
+		{"hello", "", "hello"},
+		{"/memo  note", "/memo", "note"},
+		{"/New@divinesense_bot", "/new", ""},
+		{"/start abc", "/start", "abc"},
+	} {
+		command, arg := parseCommand(tc.text)
+		assert.Equal(t, tc.command, command, tc.text)
+		assert.Equal(t, tc.arg, arg, tc.text)
+	}
+}
+
+func TestRedactToken(t *testing.T) {
+	err := redact(errors.New(`Post "https://api.telegram.org/botsecret-token/getMe": timeout`), "secret-token")
+	assert.NotContains(t, err.Error(), "secret-token")
+}
@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"os/exec"
+
+	"github.com/hrygo/hotplex/types"
+	"github.com/sashabaranov/go-openai"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/ai/agents/geek"
+	"github.com/hrygo/divinesense/ai/services/stats"
+	"github.com/hrygo/divinesense/plugin/geekworkspace"
+)
+
+// codeInfo describes how an error code is presented to users.
+type codeInfo struct {
+	message   string
+	retryable bool
+}
+
+// codeInfos holds the user-facing message of each code and whether retrying
+// the same request may succeed.
+var codeInfos = map[ErrorCode]codeInfo{
+	ErrCodeUnauthorized:         {"Please sign in again.", false},
+	ErrCodeRateLimitExceeded:    {"Too many requests. Please wait a moment and try again.", true},
+	ErrCodeInvalidArgument:      {"The request is invalid.", false},
+	ErrCodeServiceUnavailable:   {"The AI service is temporarily unavailable. Please try again later.", true},
+	ErrCodeAgentExecutionFailed: {"The assistant failed to complete the request. Please try again.", true},
+	ErrCodeAgentNotFound:        {"The requested assistant does not exist.", false},
+	ErrCodeLLMUnavailable:       {"The AI model is not configured or unavailable.", true},
+	ErrCodeContextCanceled:      {"The request was canceled.", false},
+	ErrCodeTimeout:              {"The request took too long. Please try again.", true},
+	ErrCodeProviderTimeout:      {"The AI model took too long to respond. Please try again.", true},
+	ErrCodeProviderRateLimited:  {"The AI model is busy. Please wait a moment and try again.", true},
+	ErrCodeProviderAuthFailed:   {"The AI model rejected the server's credentials. Please contact the administrator.", false},
+	ErrCodeProviderError:        {"The AI model returned an error. Please try again.", true},
+	ErrCodeCLINotFound:          {"Claude Code CLI is not installed on the server. Please contact the administrator.", false},
+	ErrCodeBudgetExceeded:       {"Your AI budget has been used up. Raise the budget in settings or try again later.", false},
+	ErrCodeQuotaExceeded:        {"Your monthly AI quota has been used up. It resets at the start of next month.", false},
+	ErrCodeDangerBlocked:        {"The request was blocked because it contains a dangerous operation.", false},
+	ErrCodeContextBuildFailed:   {"Failed to load the conversation history. Please try again.", true},
+	ErrCodeStorageFailed:        {"Failed to save or load data. Please try again.", true},
+	ErrCodeOverloaded:           {"The server is busy. Please try again in a moment, or pick the memo assistant for memo questions.", true},
+	ErrCodeConversationLocked:   {"This conversation is locked. Unlock it with its passphrase to continue.", false},
+	ErrCodeToolNotPermitted:     {"The request was stopped because it used a tool this workspace does not permit.", false},
+	ErrCodeWorkspaceFull:        {"Your Geek workspace is full. Delete files from the workspace to continue.", false},
+	ErrCodeTestsFailed:          {"The changes failed the test suite, so no PR was created. Ask for a fix to try again.", false},
+}
+
+// UserMessage returns the message shown to users for an error code.
+func UserMessage(code ErrorCode) string {
+	if info, ok := codeInfos[code]; ok {
+		return info.message
+	}
+	return codeInfos[ErrCodeAgentExecutionFailed].message
+}
+
+// Retryable reports whether retrying a request that failed with code may succeed.
+func Retryable(code ErrorCode) bool {
+	return codeInfos[code].retryable
+}
+
+// Classify returns err as an AIError. An AIError in the chain is returned as-is;
+// other errors are classified by their cause (timeouts, provider responses,
+// missing CLI, blocked commands), falling back to fallback. The message of a
+// classified error is the user-facing message of its code.
+func Classify(err error, fallback ErrorCode) *AIError {
+	if err == nil {
+		return nil
+	}
+	var aiErr *AIError
+	if stderrors.As(err, &aiErr) {
+		return aiErr
+	}
+	code := classifyCause(err)
+	if code == "" {
+		code = fallback
+	}
+	return Wrap(err, code, UserMessage(code))
+}
+
+// classifyCause returns the code of a well-known cause of err, or "".
+func classifyCause(err error) ErrorCode {
+	switch {
+	case stderrors.Is(err, context.Canceled):
+		return ErrCodeContextCanceled
+	case stderrors.Is(err, context.DeadlineExceeded), stderrors.Is(err, types.ErrTimeout):
+		return ErrCodeProviderTimeout
+	case stderrors.Is(err, types.ErrDangerBlocked):
+		return ErrCodeDangerBlocked
+	case stderrors.Is(err, agentpkg.ErrToolNotPermitted):
+		return ErrCodeToolNotPermitted
+	case stderrors.Is(err, geekworkspace.ErrQuotaExceeded):
+		return ErrCodeWorkspaceFull
+	case stderrors.Is(err, geek.ErrTestsFailed):
+		return ErrCodeTestsFailed
+	case stderrors.Is(err, stats.ErrQuotaExceeded):
+		return ErrCodeQuotaExceeded
+	case stderrors.Is(err, exec.ErrNotFound):
+		return ErrCodeCLINotFound
+	}
+
+	var apiErr *openai.APIError
+	if stderrors.As(err, &apiErr) {
+		return classifyHTTPStatus(apiErr.HTTPStatusCode)
+	}
+	var reqErr *openai.RequestError
+	if stderrors.As(err, &reqErr) {
+		return classifyHTTPStatus(reqErr.HTTPStatusCode)
+	}
+	var netErr net.Error
+	if stderrors.As(err, &netErr) && netErr.Timeout() {
+		return ErrCodeProviderTimeout
+	}
+	return ""
+}
+
+// classifyHTTPStatus maps the HTTP status of a failed provider call to a code.
+func classifyHTTPStatus(code int) ErrorCode {
+	switch code {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrCodeProviderAuthFailed
+	case http.StatusTooManyRequests:
+		return ErrCodeProviderRateLimited
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return ErrCodeProviderTimeout
+	default:
+		return ErrCodeProviderError
+	}
+}
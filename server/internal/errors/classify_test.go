@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"testing"
+
+	"github.com/hrygo/hotplex/types"
+	"github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/require"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/ai/agents/geek"
+	"github.com/hrygo/divinesense/ai/services/stats"
+	"github.com/hrygo/divinesense/plugin/geekworkspace"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCode
+	}{
+		{"deadline", fmt.Errorf("LLM chat failed: %w", context.DeadlineExceeded), ErrCodeProviderTimeout},
+		{"canceled", context.Canceled, ErrCodeContextCanceled},
+		{"cli not found", fmt.Errorf("cli not found: %w", exec.ErrNotFound), ErrCodeCLINotFound},
+		{"danger", fmt.Errorf("execute: %w", types.ErrDangerBlocked), ErrCodeDangerBlocked},
+		{"tool not permitted", fmt.Errorf("execute: %w", agentpkg.ErrToolNotPermitted), ErrCodeToolNotPermitted},
+		{"tests failed", agentpkg.NewParrotError("evolution", "TestGate", fmt.Errorf("%w: make test", geek.ErrTestsFailed)), ErrCodeTestsFailed},
+		{"quota", fmt.Errorf("check: %w", stats.ErrQuotaExceeded), ErrCodeQuotaExceeded},
+		{"workspace full", agentpkg.NewParrotError("geek", "Execute", fmt.Errorf("Write rejected: %w", geekworkspace.ErrQuotaExceeded)), ErrCodeWorkspaceFull},
+		{"provider auth", &openai.APIError{HTTPStatusCode: http.StatusUnauthorized}, ErrCodeProviderAuthFailed},
+		{"provider rate limit", fmt.Errorf("LLM chat failed: %w", &openai.APIError{HTTPStatusCode: http.StatusTooManyRequests}), ErrCodeProviderRateLimited},
+		{"provider 5xx", &openai.RequestError{HTTPStatusCode: http.StatusBadGateway}, ErrCodeProviderError},
+		{"unknown", fmt.Errorf("boom"), ErrCodeAgentExecutionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Classify(tt.err, ErrCodeAgentExecutionFailed)
+			require.Equal(t, tt.want, got.Code)
+			require.ErrorIs(t, got, tt.err)
+		})
+	}
+
+	// An AIError in the chain keeps its code and message.
+	budget := BudgetExceeded("daily budget used up")
+	require.Same(t, budget, Classify(fmt.Errorf("wrapped: %w", budget), ErrCodeAgentExecutionFailed))
+
+	require.Nil(t, Classify(nil, ErrCodeAgentExecutionFailed))
+	require.Equal(t, UserMessage(ErrCodeCLINotFound), Classify(exec.ErrNotFound, ErrCodeAgentExecutionFailed).Message)
+}
@@ -1,6 +1,7 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 )
 
@@ -26,6 +27,36 @@ const (
 	ErrCodeContextCanceled ErrorCode = "CONTEXT_CANCELED"
 	// ErrCodeTimeout indicates the operation timed out.
 	ErrCodeTimeout ErrorCode = "TIMEOUT"
+	// ErrCodeProviderTimeout indicates the LLM provider did not answer in time.
+	ErrCodeProviderTimeout ErrorCode = "PROVIDER_TIMEOUT"
+	// ErrCodeProviderRateLimited indicates the LLM provider throttled the request.
+	ErrCodeProviderRateLimited ErrorCode = "PROVIDER_RATE_LIMITED"
+	// ErrCodeProviderAuthFailed indicates the LLM provider rejected the configured credentials.
+	ErrCodeProviderAuthFailed ErrorCode = "PROVIDER_AUTH_FAILED"
+	// ErrCodeProviderError indicates the LLM provider failed the request.
+	ErrCodeProviderError ErrorCode = "PROVIDER_ERROR"
+	// ErrCodeCLINotFound indicates the Claude Code CLI is not installed on the server.
+	ErrCodeCLINotFound ErrorCode = "CLI_NOT_FOUND"
+	// ErrCodeBudgetExceeded indicates the user's cost budget has been used up.
+	ErrCodeBudgetExceeded ErrorCode = "BUDGET_EXCEEDED"
+	// ErrCodeQuotaExceeded indicates the user's monthly token or cost quota has been used up.
+	ErrCodeQuotaExceeded ErrorCode = "QUOTA_EXCEEDED"
+	// ErrCodeDangerBlocked indicates the input was blocked by the dangerous command detector.
+	ErrCodeDangerBlocked ErrorCode = "DANGER_BLOCKED"
+	// ErrCodeContextBuildFailed indicates the conversation context could not be built.
+	ErrCodeContextBuildFailed ErrorCode = "CONTEXT_BUILD_FAILED"
+	// ErrCodeStorageFailed indicates a database read or write failed.
+	ErrCodeStorageFailed ErrorCode = "STORAGE_FAILED"
+	// ErrCodeOverloaded indicates the server shed the request under resource pressure.
+	ErrCodeOverloaded ErrorCode = "OVERLOADED"
+	// ErrCodeConversationLocked indicates the conversation is locked and was not unlocked.
+	ErrCodeConversationLocked ErrorCode = "CONVERSATION_LOCKED"
+	// ErrCodeToolNotPermitted indicates Geek Mode used a tool the workspace policy does not permit.
+	ErrCodeToolNotPermitted ErrorCode = "TOOL_NOT_PERMITTED"
+	// ErrCodeWorkspaceFull indicates Geek Mode wrote to a workspace over its disk quota.
+	ErrCodeWorkspaceFull ErrorCode = "WORKSPACE_FULL"
+	// ErrCodeTestsFailed indicates Evolution changes failed the test gate.
+	ErrCodeTestsFailed ErrorCode = "TESTS_FAILED"
 )
 
 // AIError represents a structured error for AI operations.
@@ -124,6 +155,32 @@ func Timeout(msg string) *AIError {
 	return &AIError{Code: ErrCodeTimeout, Message: msg}
 }
 
+// BudgetExceeded creates a budget exceeded error.
+func BudgetExceeded(msg string) *AIError {
+	return &AIError{Code: ErrCodeBudgetExceeded, Message: msg}
+}
+
+// ContextBuildFailed creates a context build failed error.
+func ContextBuildFailed(cause error) *AIError {
+	return &AIError{Code: ErrCodeContextBuildFailed, Message: UserMessage(ErrCodeContextBuildFailed), Cause: cause}
+}
+
+// StorageFailed creates a storage failed error.
+func StorageFailed(cause error) *AIError {
+	return &AIError{Code: ErrCodeStorageFailed, Message: UserMessage(ErrCodeStorageFailed), Cause: cause}
+}
+
+// Overloaded creates an error for a request shed under resource pressure.
+func Overloaded() *AIError {
+	return &AIError{Code: ErrCodeOverloaded, Message: UserMessage(ErrCodeOverloaded)}
+}
+
+// ConversationLocked creates an error for a chat in a locked conversation
+// that was not unlocked.
+func ConversationLocked() *AIError {
+	return &AIError{Code: ErrCodeConversationLocked, Message: UserMessage(ErrCodeConversationLocked)}
+}
+
 // Wrap wraps an existing error with additional context.
 func Wrap(cause error, code ErrorCode, msg string) *AIError {
 	return &AIError{Code: code, Message: msg, Cause: cause}
@@ -131,7 +188,8 @@ func Wrap(cause error, code ErrorCode, msg string) *AIError {
 
 // IsCode checks if an error is of a specific code.
 func IsCode(err error, code ErrorCode) bool {
-	if aiErr, ok := err.(*AIError); ok {
+	var aiErr *AIError
+	if stderrors.As(err, &aiErr) {
 		return aiErr.Code == code
 	}
 	return false
@@ -140,7 +198,8 @@ func IsCode(err error, code ErrorCode) bool {
 // GetCodeFromError extracts the error code from any error.
 // Returns the provided default code if the error is not an AIError.
 func GetCodeFromError(err error, defaultCode ErrorCode) ErrorCode {
-	if aiErr, ok := err.(*AIError); ok {
+	var aiErr *AIError
+	if stderrors.As(err, &aiErr) {
 		return aiErr.Code
 	}
 	return defaultCode
@@ -0,0 +1,205 @@
+package observability
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const metricsNamespace = "divinesense"
+
+const (
+	// StatusSuccess labels the requests, tasks and sessions that succeeded.
+	StatusSuccess = "success"
+	// StatusError labels the requests, tasks and sessions that failed.
+	StatusError = "error"
+)
+
+// durationBuckets are the buckets (seconds) of the AI pipeline durations,
+// from a fast expert answer to a long CLI session.
+var durationBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 900}
+
+// Metrics of the AI pipeline, exposed by MetricsHandler in the Prometheus
+// format. They are process-wide: each replica exposes its own.
+var (
+	registry = prometheus.NewRegistry()
+
+	chatRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "ai",
+		Name:      "chat_requests_total",
+		Help:      "Chat requests by agent type and status.",
+	}, []string{"agent_type", "status"})
+
+	chatDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "ai",
+		Name:      "chat_request_duration_seconds",
+		Help:      "Duration of chat requests by agent type.",
+		Buckets:   durationBuckets,
+	}, []string{"agent_type"})
+
+	routingDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "ai",
+		Name:      "routing_decisions_total",
+		Help:      "Routing decisions of AUTO chat requests by method (e.g. router, session_sticky, fallback) and route.",
+	}, []string{"method", "route"})
+
+	orchestratorTaskDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "ai",
+		Name:      "orchestrator_task_duration_seconds",
+		Help:      "Duration of the orchestrator tasks by expert agent and status.",
+		Buckets:   durationBuckets,
+	}, []string{"agent", "status"})
+
+	runnerSessionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "ai",
+		Name:      "runner_session_duration_seconds",
+		Help:      "Duration of the CLI runner sessions of the Geek and Evolution modes by mode and status.",
+		Buckets:   durationBuckets,
+	}, []string{"mode", "status"})
+
+	tokensUsed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "ai",
+		Name:      "tokens_total",
+		Help:      "LLM tokens used by agent type and kind (input, output, cache_read, cache_write).",
+	}, []string{"agent_type", "kind"})
+
+	blockQueueDepth  atomic.Pointer[func() int]
+	blockEventDrops  atomic.Pointer[func() int64]
+	persisterDepth   atomic.Pointer[func() int]
+	persisterDropped atomic.Pointer[func() int64]
+)
+
+// init registers the metrics, along with the Go runtime and process metrics.
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		chatRequests,
+		chatDuration,
+		routingDecisions,
+		orchestratorTaskDuration,
+		runnerSessionDuration,
+		tokensUsed,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "ai",
+			Name:      "block_event_queue_depth",
+			Help:      "Streamed events waiting to be persisted to their blocks.",
+		}, func() float64 { return float64(load(&blockQueueDepth)) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "ai",
+			Name:      "block_events_dropped_total",
+			Help:      "Streamed events dropped on full block persistence queues.",
+		}, func() float64 { return float64(load(&blockEventDrops)) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "ai",
+			Name:      "stats_persister_queue_depth",
+			Help:      "Session stats records waiting to be persisted.",
+		}, func() float64 { return float64(load(&persisterDepth)) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "ai",
+			Name:      "stats_persister_dropped_total",
+			Help:      "Session stats records dropped on a full persister queue.",
+		}, func() float64 { return float64(load(&persisterDropped)) }),
+	)
+}
+
+// load calls the source of a gauge or counter, if set.
+func load[T int | int64](source *atomic.Pointer[func() T]) T {
+	if fn := source.Load(); fn != nil {
+		return (*fn)()
+	}
+	return 0
+}
+
+// status returns the status label of an outcome.
+func status(err error) string {
+	if err != nil {
+		return StatusError
+	}
+	return StatusSuccess
+}
+
+// MetricsHandler serves the metrics in the Prometheus text format. A
+// non-empty token is required from scrapers as a bearer token. Responses are
+// not compressed, the server's gzip middleware doing it.
+func MetricsHandler(token string) http.Handler {
+	handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry, DisableCompression: true})
+	if token == "" {
+		return handler
+	}
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// ObserveChatRequest records a chat request of an agent type, e.g. memo,
+// geek or orchestrator, with its outcome.
+func ObserveChatRequest(agentType string, err error, duration time.Duration) {
+	chatRequests.WithLabelValues(agentType, status(err)).Inc()
+	chatDuration.WithLabelValues(agentType).Observe(duration.Seconds())
+}
+
+// ObserveRouting records the routing decision of an AUTO chat request; an
+// empty route stands for the orchestrator.
+func ObserveRouting(method, route string) {
+	if route == "" {
+		route = "orchestrator"
+	}
+	routingDecisions.WithLabelValues(method, route).Inc()
+}
+
+// ObserveOrchestratorTask records an orchestrator task run by an expert
+// agent, with its final status (e.g. completed, failed, skipped).
+func ObserveOrchestratorTask(agent, status string, duration time.Duration) {
+	orchestratorTaskDuration.WithLabelValues(agent, status).Observe(duration.Seconds())
+}
+
+// ObserveRunnerSession records a CLI runner session of the Geek or Evolution
+// mode.
+func ObserveRunnerSession(mode string, err error, duration time.Duration) {
+	runnerSessionDuration.WithLabelValues(mode, status(err)).Observe(duration.Seconds())
+}
+
+// AddTokens records the LLM tokens used by a chat request.
+func AddTokens(agentType string, input, output, cacheRead, cacheWrite int64) {
+	for kind, n := range map[string]int64{"input": input, "output": output, "cache_read": cacheRead, "cache_write": cacheWrite} {
+		if n > 0 {
+			tokensUsed.WithLabelValues(agentType, kind).Add(float64(n))
+		}
+	}
+}
+
+// SetBlockQueueSource sets the sources of the block persistence queue depth
+// and of the count of its dropped events.
+func SetBlockQueueSource(depth func() int, dropped func() int64) {
+	blockQueueDepth.Store(&depth)
+	blockEventDrops.Store(&dropped)
+}
+
+// SetPersisterSource sets the sources of the session stats persister queue
+// depth and of the count of its dropped records.
+func SetPersisterSource(depth func() int, dropped func() int64) {
+	persisterDepth.Store(&depth)
+	persisterDropped.Store(&dropped)
+}
@@ -0,0 +1,60 @@
+package observability
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveChatRequest(t *testing.T) {
+	before := testutil.ToFloat64(chatRequests.WithLabelValues("memo", StatusError))
+	ObserveChatRequest("memo", errors.New("boom"), time.Second)
+	ObserveChatRequest("memo", nil, time.Second)
+
+	assert.Equal(t, before+1, testutil.ToFloat64(chatRequests.WithLabelValues("memo", StatusError)))
+	assert.GreaterOrEqual(t, testutil.ToFloat64(chatRequests.WithLabelValues("memo", StatusSuccess)), 1.0)
+}
+
+func TestAddTokens(t *testing.T) {
+	AddTokens("schedule", 100, 20, 0, 0)
+
+	assert.Equal(t, 100.0, testutil.ToFloat64(tokensUsed.WithLabelValues("schedule", "input")))
+	assert.Equal(t, 20.0, testutil.ToFloat64(tokensUsed.WithLabelValues("schedule", "output")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(tokensUsed.WithLabelValues("schedule", "cache_read")))
+}
+
+func TestMetricsHandler(t *testing.T) {
+	SetPersisterSource(func() int { return 3 }, func() int64 { return 7 })
+	ObserveRouting("router", "")
+
+	scrape := func(handler http.Handler, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := scrape(MetricsHandler(""), "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "divinesense_ai_stats_persister_dropped_total 7")
+	assert.Contains(t, body, "divinesense_ai_stats_persister_queue_depth 3")
+	assert.Contains(t, body, "divinesense_ai_block_event_queue_depth 0")
+	assert.Contains(t, body, `divinesense_ai_routing_decisions_total{method="router",route="orchestrator"}`)
+	assert.Contains(t, body, "go_goroutines")
+
+	// A token is required once set
+	handler := MetricsHandler("secret")
+	assert.Equal(t, http.StatusUnauthorized, scrape(handler, "").Code)
+	assert.Equal(t, http.StatusUnauthorized, scrape(handler, "Bearer wrong").Code)
+	assert.Equal(t, http.StatusOK, scrape(handler, "Bearer secret").Code)
+}
@@ -0,0 +1,49 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetupTracing(t *testing.T) {
+	shutdown, err := SetupTracing(context.Background(), TracingConfig{})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	_, err = SetupTracing(context.Background(), TracingConfig{Endpoint: "localhost"})
+	assert.Error(t, err)
+}
+
+func TestStartSpan(t *testing.T) {
+	// Without a tracer provider, spans carry no trace ID
+	ctx, span := StartSpan(context.Background(), "chat")
+	assert.Empty(t, TraceID(ctx))
+	EndSpan(span, nil)
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	ctx, parent := StartSpan(context.Background(), "chat")
+	_, child := StartSpan(ctx, "chat.route")
+	EndSpan(child, errors.New("boom"))
+	EndSpan(parent, nil)
+
+	traceID := TraceID(ctx)
+	assert.Len(t, traceID, 32)
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "chat.route", spans[0].Name())
+	assert.Equal(t, traceID, spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
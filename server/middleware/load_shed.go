@@ -0,0 +1,294 @@
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LoadClass is the shedding priority of a request. Lower classes are shed first.
+type LoadClass int
+
+const (
+	// LoadClassRouted is an AUTO request: intent routing and orchestration.
+	LoadClassRouted LoadClass = iota
+	// LoadClassDirect is a request to an explicitly chosen agent.
+	LoadClassDirect
+	// LoadClassEssential is never shed (direct memo queries).
+	LoadClassEssential
+)
+
+func (c LoadClass) String() string {
+	switch c {
+	case LoadClassRouted:
+		return "routed"
+	case LoadClassDirect:
+		return "direct"
+	default:
+		return "essential"
+	}
+}
+
+// LoadLevel is the pressure of the server.
+type LoadLevel int
+
+const (
+	// LoadNormal admits every request.
+	LoadNormal LoadLevel = iota
+	// LoadHigh sheds routed requests: one signal crossed its threshold.
+	LoadHigh
+	// LoadCritical sheds all but essential requests: several signals crossed
+	// their thresholds.
+	LoadCritical
+)
+
+func (l LoadLevel) String() string {
+	switch l {
+	case LoadHigh:
+		return "high"
+	case LoadCritical:
+		return "critical"
+	default:
+		return "normal"
+	}
+}
+
+// LoadShedConfig holds the thresholds of the pressure signals; a zero
+// threshold disables its signal.
+type LoadShedConfig struct {
+	CPUPercent   int           // System CPU usage
+	DBLatency    time.Duration // Round trip of a database ping
+	QueuePercent int           // Fill of the session stats persister queue
+	Interval     time.Duration // Between probes (default: 5s)
+}
+
+// LoadSignals are the pressure signals of a probe.
+type LoadSignals struct {
+	CPUPercent   float64 `json:"cpu_percent"`
+	DBLatencyMs  int64   `json:"db_latency_ms"`
+	QueuePercent float64 `json:"queue_percent"`
+}
+
+// LoadShedStatus is the state and the metrics of a load shedder.
+type LoadShedStatus struct {
+	Level      string           `json:"level"`
+	Signals    LoadSignals      `json:"signals"`
+	Thresholds LoadSignals      `json:"thresholds"`
+	Pressured  []string         `json:"pressured,omitempty"` // Signals over their threshold
+	Shed       map[string]int64 `json:"shed"`                // Rejected requests by class
+	ProbedAt   int64            `json:"probed_at,omitempty"`
+}
+
+// Pinger is the database probed for latency.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// QueueGauge is the queue probed for depth.
+type QueueGauge interface {
+	QueueSize() int
+	QueueCapacity() int
+}
+
+// LoadShedder rejects low-priority requests while CPU, database latency or
+// the persister queue are under pressure. It is probed periodically by Run.
+type LoadShedder struct {
+	config LoadShedConfig
+	db     Pinger
+	queue  QueueGauge
+	// cpuTimes returns the idle and total CPU time of the system.
+	cpuTimes func() (idle, total uint64, err error)
+
+	level atomic.Int32
+	shed  [LoadClassEssential]atomic.Int64
+
+	mu        sync.Mutex
+	signals   LoadSignals
+	pressured []string
+	probedAt  time.Time
+	lastIdle  uint64
+	lastTotal uint64
+}
+
+// NewLoadShedder creates a load shedder; db and queue may be nil.
+func NewLoadShedder(config LoadShedConfig, db Pinger, queue QueueGauge) *LoadShedder {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Second
+	}
+	return &LoadShedder{config: config, db: db, queue: queue, cpuTimes: readCPUTimes}
+}
+
+// Admit reports whether a request of a class is admitted, counting the
+// rejected ones. A nil shedder admits every request.
+func (l *LoadShedder) Admit(class LoadClass) bool {
+	if l == nil || class >= LoadClassEssential {
+		return true
+	}
+	level := LoadLevel(l.level.Load())
+	if level == LoadNormal || (level == LoadHigh && class > LoadClassRouted) {
+		return true
+	}
+	l.shed[class].Add(1)
+	return false
+}
+
+// Level returns the current pressure level.
+func (l *LoadShedder) Level() LoadLevel {
+	if l == nil {
+		return LoadNormal
+	}
+	return LoadLevel(l.level.Load())
+}
+
+// Run probes the signals until ctx is canceled.
+func (l *LoadShedder) Run(ctx context.Context) {
+	ticker := time.NewTicker(l.config.Interval)
+	defer ticker.Stop()
+	for {
+		l.probe(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Status returns the state and the metrics of the shedder.
+func (l *LoadShedder) Status() *LoadShedStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	status := &LoadShedStatus{
+		Level:   l.Level().String(),
+		Signals: l.signals,
+		Thresholds: LoadSignals{
+			CPUPercent:   float64(l.config.CPUPercent),
+			DBLatencyMs:  l.config.DBLatency.Milliseconds(),
+			QueuePercent: float64(l.config.QueuePercent),
+		},
+		Pressured: l.pressured,
+		Shed:      make(map[string]int64, len(l.shed)),
+	}
+	for class := range l.shed {
+		status.Shed[LoadClass(class).String()] = l.shed[class].Load()
+	}
+	if !l.probedAt.IsZero() {
+		status.ProbedAt = l.probedAt.Unix()
+	}
+	return status
+}
+
+// probe samples the enabled signals and updates the level.
+func (l *LoadShedder) probe(ctx context.Context) {
+	var signals LoadSignals
+	if l.config.CPUPercent > 0 {
+		signals.CPUPercent = l.cpuPercent()
+	}
+	if l.config.DBLatency > 0 && l.db != nil {
+		pingCtx, cancel := context.WithTimeout(ctx, 2*l.config.DBLatency)
+		start := time.Now()
+		err := l.db.PingContext(pingCtx)
+		cancel()
+		signals.DBLatencyMs = time.Since(start).Milliseconds()
+		if err != nil && ctx.Err() == nil {
+			// A ping timing out is as slow as its deadline
+			signals.DBLatencyMs = max(signals.DBLatencyMs, (2 * l.config.DBLatency).Milliseconds())
+		}
+	}
+	if l.config.QueuePercent > 0 && l.queue != nil && l.queue.QueueCapacity() > 0 {
+		signals.QueuePercent = 100 * float64(l.queue.QueueSize()) / float64(l.queue.QueueCapacity())
+	}
+	l.observe(signals)
+}
+
+// observe sets the level from the signals of a probe.
+func (l *LoadShedder) observe(signals LoadSignals) {
+	var pressured []string
+	if l.config.CPUPercent > 0 && signals.CPUPercent >= float64(l.config.CPUPercent) {
+		pressured = append(pressured, "cpu")
+	}
+	if l.config.DBLatency > 0 && signals.DBLatencyMs >= l.config.DBLatency.Milliseconds() {
+		pressured = append(pressured, "db_latency")
+	}
+	if l.config.QueuePercent > 0 && signals.QueuePercent >= float64(l.config.QueuePercent) {
+		pressured = append(pressured, "queue")
+	}
+	level := LoadNormal
+	switch {
+	case len(pressured) > 1:
+		level = LoadCritical
+	case len(pressured) == 1:
+		level = LoadHigh
+	}
+
+	l.mu.Lock()
+	l.signals = signals
+	l.pressured = pressured
+	l.probedAt = time.Now()
+	l.mu.Unlock()
+
+	if previous := LoadLevel(l.level.Swap(int32(level))); previous != level {
+		slog.Warn("load shedding level changed",
+			"from", previous.String(),
+			"to", level.String(),
+			"pressured", pressured,
+			"cpu_percent", signals.CPUPercent,
+			"db_latency_ms", signals.DBLatencyMs,
+			"queue_percent", signals.QueuePercent,
+		)
+	}
+}
+
+// cpuPercent returns the CPU usage since the previous probe; 0 on the first
+// probe or when the CPU time is not available.
+func (l *LoadShedder) cpuPercent() float64 {
+	idle, total, err := l.cpuTimes()
+	if err != nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	idleDelta, totalDelta := idle-l.lastIdle, total-l.lastTotal
+	first := l.lastTotal == 0
+	l.lastIdle, l.lastTotal = idle, total
+	if first || totalDelta == 0 || idleDelta > totalDelta {
+		return 0
+	}
+	return 100 * float64(totalDelta-idleDelta) / float64(totalDelta)
+}
+
+// readCPUTimes reads the aggregate CPU times of /proc/stat (Linux only).
+func readCPUTimes() (idle, total uint64, err error) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return 0, 0, fmt.Errorf("empty /proc/stat")
+	}
+	fields := strings.Fields(scanner.Text())
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return 0, 0, fmt.Errorf("unexpected /proc/stat line: %q", scanner.Text())
+	}
+	for i, field := range fields[1:] {
+		value, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to parse /proc/stat: %w", err)
+		}
+		total += value
+		// idle and iowait
+		if i == 3 || i == 4 {
+			idle += value
+		}
+	}
+	return idle, total, nil
+}
@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type slowPinger struct{ latency time.Duration }
+
+func (p slowPinger) PingContext(ctx context.Context) error {
+	select {
+	case <-time.After(p.latency):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type fixedQueue struct{ size, capacity int }
+
+func (q fixedQueue) QueueSize() int     { return q.size }
+func (q fixedQueue) QueueCapacity() int { return q.capacity }
+
+func TestLoadShedder_Admit(t *testing.T) {
+	shedder := NewLoadShedder(LoadShedConfig{CPUPercent: 90, DBLatency: time.Second, QueuePercent: 80}, nil, nil)
+
+	// Normal: everything is admitted
+	shedder.observe(LoadSignals{CPUPercent: 40, DBLatencyMs: 20, QueuePercent: 10})
+	assert.Equal(t, LoadNormal, shedder.Level())
+	assert.True(t, shedder.Admit(LoadClassRouted))
+	assert.True(t, shedder.Admit(LoadClassDirect))
+
+	// One pressured signal sheds routed requests first
+	shedder.observe(LoadSignals{CPUPercent: 95, DBLatencyMs: 20, QueuePercent: 10})
+	assert.Equal(t, LoadHigh, shedder.Level())
+	assert.False(t, shedder.Admit(LoadClassRouted))
+	assert.True(t, shedder.Admit(LoadClassDirect))
+	assert.True(t, shedder.Admit(LoadClassEssential))
+
+	// Several pressured signals keep only the essential requests
+	shedder.observe(LoadSignals{CPUPercent: 95, DBLatencyMs: 1500, QueuePercent: 10})
+	assert.Equal(t, LoadCritical, shedder.Level())
+	assert.False(t, shedder.Admit(LoadClassRouted))
+	assert.False(t, shedder.Admit(LoadClassDirect))
+	assert.True(t, shedder.Admit(LoadClassEssential))
+
+	status := shedder.Status()
+	assert.Equal(t, "critical", status.Level)
+	assert.Equal(t, []string{"cpu", "db_latency"}, status.Pressured)
+	assert.Equal(t, map[string]int64{"routed": 2, "direct": 1}, status.Shed)
+
+	// Recovery
+	shedder.observe(LoadSignals{})
+	assert.Equal(t, LoadNormal, shedder.Level())
+	assert.True(t, shedder.Admit(LoadClassRouted))
+}
+
+func TestLoadShedder_DisabledSignals(t *testing.T) {
+	shedder := NewLoadShedder(LoadShedConfig{QueuePercent: 80}, nil, nil)
+	shedder.observe(LoadSignals{CPUPercent: 100, DBLatencyMs: 60000})
+	assert.Equal(t, LoadNormal, shedder.Level())
+
+	var nilShedder *LoadShedder
+	assert.True(t, nilShedder.Admit(LoadClassRouted))
+	assert.Equal(t, LoadNormal, nilShedder.Level())
+}
+
+func TestLoadShedder_Probe(t *testing.T) {
+	shedder := NewLoadShedder(
+		LoadShedConfig{CPUPercent: 90, DBLatency: 20 * time.Millisecond, QueuePercent: 80},
+		slowPinger{latency: time.Second},
+		fixedQueue{size: 90, capacity: 100},
+	)
+	times := [][2]uint64{{100, 1000}, {105, 1100}}
+	shedder.cpuTimes = func() (uint64, uint64, error) {
+		next := times[0]
+		times = times[1:]
+		return next[0], next[1], nil
+	}
+
+	// The first probe has no CPU baseline
+	shedder.probe(context.Background())
+	status := shedder.Status()
+	assert.Zero(t, status.Signals.CPUPercent)
+	assert.GreaterOrEqual(t, status.Signals.DBLatencyMs, int64(40), "a timed out ping counts as its deadline")
+	assert.InDelta(t, 90, status.Signals.QueuePercent, 0.01)
+	assert.Equal(t, LoadCritical, shedder.Level())
+
+	shedder.probe(context.Background())
+	assert.InDelta(t, 95, shedder.Status().Signals.CPUPercent, 0.01)
+}
+
+func TestReadCPUTimes(t *testing.T) {
+	idle, total, err := readCPUTimes()
+	if err != nil {
+		t.Skip("/proc/stat not available")
+	}
+	require.Positive(t, total)
+	assert.LessOrEqual(t, idle, total)
+}
@@ -891,6 +891,7 @@ func (r *QueryRouter) GetStrategyDescription(strategy string) string {
 	descriptions := map[string]string{
 		"schedule_bm25_only":          "纯日程查询（BM25 + 时间过滤）",
 		"memo_semantic_only":          "纯笔记查询（语义向量）",
+		"memo_hybrid":                 "笔记混合检索（语义向量 + 关键词 RRF）",
 		"hybrid_bm25_weighted":        "混合检索（BM25 加权）",
 		"hybrid_with_time_filter":     "混合检索（时间过滤）",
 		"hybrid_standard":             "标准混合检索（BM25 + 语义）",
@@ -32,6 +32,7 @@ var PublicMethods = map[string]struct{}{
 	"/memos.api.v1.MemoService/GetMemo":          {},
 	"/memos.api.v1.MemoService/ListMemos":        {},
 	"/memos.api.v1.MemoService/ListMemoComments": {},
+	"/memos.api.v1.MemoService/SearchMemos":      {},
 }
 
 // IsPublicMethod checks if a procedure path is public (no authentication required).
@@ -0,0 +1,174 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hrygo/divinesense/ai/routing"
+	"github.com/hrygo/divinesense/store"
+)
+
+// EventTypeAgentSuggestions is streamed instead of auto-routing when the router
+// confidence is below the user's threshold. EventData is the JSON-encoded
+// AgentSuggestions; the user's pick is submitted through SelectAgent.
+const EventTypeAgentSuggestions = "agent_suggestions"
+
+// routingPreferencesKey is the user_preferences key holding RoutingSettings.
+const routingPreferencesKey = "routing"
+
+// RoutingSettings are the per-user auto-routing preferences.
+type RoutingSettings struct {
+	// ConfidenceThreshold is the ChatRouter confidence below which candidate
+	// experts are suggested instead of routing automatically. 0 disables it.
+	ConfidenceThreshold float64 `json:"confidence_threshold"`
+}
+
+// Validate checks the settings submitted by the user.
+func (s *RoutingSettings) Validate() error {
+	if s.ConfidenceThreshold < 0 || s.ConfidenceThreshold > 1 {
+		return fmt.Errorf("confidence_threshold must be between 0 and 1")
+	}
+	return nil
+}
+
+// GetRoutingSettings returns the routing settings of a user, or the defaults if
+// none were saved.
+func GetRoutingSettings(ctx context.Context, st *store.Store, userID int32) (*RoutingSettings, error) {
+	prefs, err := loadPreferences(ctx, st, userID)
+	if err != nil {
+		return nil, err
+	}
+	settings := &RoutingSettings{}
+	if raw, ok := prefs[routingPreferencesKey]; ok {
+		if err := json.Unmarshal(raw, settings); err != nil {
+			return nil, fmt.Errorf("decode routing settings: %w", err)
+		}
+	}
+	return settings, nil
+}
+
+// UpdateRoutingSettings saves the routing settings of a user, keeping the other
+// preferences untouched.
+func UpdateRoutingSettings(ctx context.Context, st *store.Store, userID int32, settings *RoutingSettings) error {
+	return savePreference(ctx, st, userID, routingPreferencesKey, settings)
+}
+
+// savePreference stores value under key in the user's preferences, keeping the
+// other keys untouched.
+func savePreference(ctx context.Context, st *store.Store, userID int32, key string, value any) error {
+	prefs, err := loadPreferences(ctx, st, userID)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	prefs[key] = raw
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return err
+	}
+	_, err = st.UpsertUserPreferences(ctx, &store.UpsertUserPreferences{
+		UserID:      userID,
+		Preferences: string(data),
+	})
+	return err
+}
+
+func loadPreferences(ctx context.Context, st *store.Store, userID int32) (map[string]json.RawMessage, error) {
+	prefs := map[string]json.RawMessage{}
+	row, err := st.GetUserPreferences(ctx, &store.FindUserPreferences{UserID: &userID})
+	if err != nil {
+		return nil, err
+	}
+	if row != nil && row.Preferences != "" {
+		if err := json.Unmarshal([]byte(row.Preferences), &prefs); err != nil {
+			return nil, fmt.Errorf("decode user preferences: %w", err)
+		}
+	}
+	return prefs, nil
+}
+
+// AgentSuggestion is one candidate expert offered to the user.
+type AgentSuggestion struct {
+	Agent AgentType `json:"agent"`
+	// Predicted marks the router's own (low-confidence) choice.
+	Predicted bool `json:"predicted,omitempty"`
+}
+
+// AgentSuggestions is the payload of an agent_suggestions event, also stored
+// in block metadata until the user picks an agent.
+type AgentSuggestions struct {
+	Message    string            `json:"message"`
+	Confidence float64           `json:"confidence"`
+	Candidates []AgentSuggestion `json:"candidates"`
+}
+
+// suggestAgents lists the experts the user can choose from, with the router's
+// prediction first.
+func suggestAgents(message string, predicted AgentType, confidence float64) *AgentSuggestions {
+	suggestions := &AgentSuggestions{Message: message, Confidence: confidence}
+	for _, agent := range []AgentType{AgentTypeMemo, AgentTypeSchedule} {
+		candidate := AgentSuggestion{Agent: agent, Predicted: agent == predicted}
+		if candidate.Predicted {
+			suggestions.Candidates = append([]AgentSuggestion{candidate}, suggestions.Candidates...)
+		} else {
+			suggestions.Candidates = append(suggestions.Candidates, candidate)
+		}
+	}
+	return suggestions
+}
+
+// Predicted returns the router's choice among the candidates.
+func (s *AgentSuggestions) Predicted() AgentType {
+	for _, c := range s.Candidates {
+		if c.Predicted {
+			return c.Agent
+		}
+	}
+	return ""
+}
+
+// Has reports whether agent is one of the candidates.
+func (s *AgentSuggestions) Has(agent AgentType) bool {
+	for _, c := range s.Candidates {
+		if c.Agent == agent {
+			return true
+		}
+	}
+	return false
+}
+
+// PendingAgentSuggestionsFromBlock returns the suggestions awaiting a choice on block, if any.
+func PendingAgentSuggestionsFromBlock(block *store.AIBlock) (*AgentSuggestions, bool) {
+	if block == nil || block.Metadata == nil {
+		return nil, false
+	}
+	raw, ok := block.Metadata[PendingAgentChoiceMetadataKey]
+	if !ok || raw == nil {
+		return nil, false
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, false
+	}
+	var suggestions AgentSuggestions
+	if err := json.Unmarshal(data, &suggestions); err != nil || len(suggestions.Candidates) == 0 {
+		return nil, false
+	}
+	return &suggestions, true
+}
+
+// AgentTypeIntent maps an expert to the routing intent used for feedback.
+func AgentTypeIntent(agent AgentType) routing.Intent {
+	switch agent {
+	case AgentTypeMemo:
+		return routing.IntentMemoSearch
+	case AgentTypeSchedule:
+		return routing.IntentScheduleQuery
+	default:
+		return routing.IntentUnknown
+	}
+}
@@ -0,0 +1,42 @@
+package ai
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/ai/routing"
+	"github.com/hrygo/divinesense/store"
+)
+
+func TestSuggestAgents(t *testing.T) {
+	suggestions := suggestAgents("明天和老王", AgentTypeSchedule, 0.55)
+	require.Len(t, suggestions.Candidates, 2)
+	require.Equal(t, AgentTypeSchedule, suggestions.Candidates[0].Agent)
+	require.Equal(t, AgentTypeSchedule, suggestions.Predicted())
+	require.True(t, suggestions.Has(AgentTypeMemo))
+	require.False(t, suggestions.Has(AgentTypeAuto))
+
+	// Block metadata is stored as generic JSON.
+	data, err := json.Marshal(suggestions)
+	require.NoError(t, err)
+	var metadata map[string]any
+	require.NoError(t, json.Unmarshal(data, &metadata))
+	block := &store.AIBlock{Metadata: map[string]any{PendingAgentChoiceMetadataKey: metadata}}
+
+	pending, ok := PendingAgentSuggestionsFromBlock(block)
+	require.True(t, ok)
+	require.Equal(t, suggestions, pending)
+
+	block.Metadata[PendingAgentChoiceMetadataKey] = nil
+	_, ok = PendingAgentSuggestionsFromBlock(block)
+	require.False(t, ok)
+}
+
+func TestRoutingSettingsValidate(t *testing.T) {
+	require.NoError(t, (&RoutingSettings{}).Validate())
+	require.NoError(t, (&RoutingSettings{ConfidenceThreshold: 0.7}).Validate())
+	require.Error(t, (&RoutingSettings{ConfidenceThreshold: 1.5}).Validate())
+	require.Equal(t, routing.IntentScheduleQuery, AgentTypeIntent(AgentTypeSchedule))
+}
@@ -4,14 +4,28 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/hrygo/divinesense/ai/core/retrieval"
+	"github.com/hrygo/divinesense/server/internal/observability"
 	"github.com/hrygo/divinesense/store"
 	"github.com/lithammer/shortuuid/v4"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
+	// PendingFormMetadataKey is the block metadata key holding the form awaiting submission.
+	PendingFormMetadataKey = "pending_form"
+
+	// PendingAgentChoiceMetadataKey is the block metadata key holding the agent
+	// suggestions awaiting the user's choice.
+	PendingAgentChoiceMetadataKey = "pending_agent_choice"
+
 	// serializerTimeout is the maximum time a serializer can exist without completion.
 	// After this timeout, the serializer is stopped to prevent resource leaks.
 	// 30 minutes is chosen as a safe upper bound for normal chat completion.
@@ -20,6 +34,14 @@ const (
 	// serializerStopTimeout is the maximum time to wait for serializer drain during stop.
 	// After this timeout, the serializer is forcibly stopped to prevent indefinite blocking.
 	serializerStopTimeout = 5 * time.Second
+
+	// eventFlushInterval is the longest time a streamed event stays buffered
+	// before it is written to the block.
+	eventFlushInterval = 250 * time.Millisecond
+
+	// eventBatchSize is the number of buffered events that triggers a write
+	// before the flush interval.
+	eventBatchSize = 32
 )
 
 // BlockManager manages the lifecycle of conversation blocks.
@@ -32,6 +54,12 @@ type BlockManager struct {
 	// Event serialization: ensures events are persisted in order
 	// Key: blockID, Value: event serializer for that block
 	serializers sync.Map // map[int64]*eventSerializer
+
+	// dropped counts the events dropped on full serializer queues.
+	dropped atomic.Int64
+
+	// onStatus is called with the block once its status is updated.
+	onStatus func(ctx context.Context, block *store.AIBlock)
 }
 
 // NewBlockManager creates a new BlockManager.
@@ -39,30 +67,65 @@ func NewBlockManager(store *store.Store) *BlockManager {
 	return &BlockManager{store: store}
 }
 
+// OnStatusChange sets a function called with each block whose status is
+// updated, e.g. to notify the webhooks of completed and failed blocks. Set it
+// before the manager is used.
+func (m *BlockManager) OnStatusChange(fn func(ctx context.Context, block *store.AIBlock)) {
+	m.onStatus = fn
+}
+
+// QueueDepth returns the number of events waiting to be persisted, over all
+// blocks.
+func (m *BlockManager) QueueDepth() int {
+	depth := 0
+	m.serializers.Range(func(_, value any) bool {
+		if s, ok := value.(*eventSerializer); ok {
+			depth += len(s.channel)
+		}
+		return true
+	})
+	return depth
+}
+
+// DroppedEvents returns the number of events dropped on full queues.
+func (m *BlockManager) DroppedEvents() int64 {
+	return m.dropped.Load()
+}
+
 // eventSerializer serializes event persistence for a single block.
 // Events are queued and persisted in order by a dedicated goroutine.
 type eventSerializer struct {
 	blockID    int64
-	manager    *BlockManager
-	channel    chan *blockEvent
+	write      func(ctx context.Context, blockID int64, events []store.BlockEvent) error
+	channel    chan store.BlockEvent
 	wg         sync.WaitGroup
 	stopCh     chan struct{}
 	once       sync.Once
-	createTime time.Time // Track creation time for timeout cleanup
-}
-
-type blockEvent struct {
-	eventType string
-	content   string
-	metadata  map[string]any
+	createTime time.Time     // Track creation time for timeout cleanup
+	dropped    *atomic.Int64 // Counter of dropped events, if any
 }
 
 // start begins the event processing goroutine.
+//
+// Events are buffered and written with one AppendEventsBatch call when
+// eventBatchSize events are pending or every eventFlushInterval, so a long
+// streaming round updates the block row a few times per second instead of
+// once per chunk. Pending events are always written before the goroutine exits.
 func (s *eventSerializer) start() {
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
 		ctx := context.Background()
+		ticker := time.NewTicker(eventFlushInterval)
+		defer ticker.Stop()
+
+		var pending []store.BlockEvent
+		flush := func() {
+			if len(pending) > 0 {
+				s.persist(ctx, pending)
+				pending = nil
+			}
+		}
 		for {
 			select {
 			case <-s.stopCh:
@@ -71,31 +134,34 @@ func (s *eventSerializer) start() {
 				for {
 					select {
 					case event := <-s.channel:
-						s.persist(ctx, event)
+						pending = append(pending, event)
+						if len(pending) >= eventBatchSize {
+							flush()
+						}
 					default:
-						// Channel is empty, safe to exit
+						// Channel is empty, flush and exit
+						flush()
 						return
 					}
 				}
 			case event := <-s.channel:
-				s.persist(ctx, event)
+				pending = append(pending, event)
+				if len(pending) >= eventBatchSize {
+					flush()
+				}
+			case <-ticker.C:
+				flush()
 			}
 		}
 	}()
 }
 
-// persist writes a single event to the database.
-func (s *eventSerializer) persist(ctx context.Context, event *blockEvent) {
-	blockEvent := store.BlockEvent{
-		Type:      event.eventType,
-		Content:   event.content,
-		Timestamp: time.Now().UnixMilli(),
-		Meta:      event.metadata,
-	}
-	if err := s.manager.store.AppendEvent(ctx, s.blockID, blockEvent); err != nil {
-		slog.Error("Failed to append event",
+// persist writes a batch of events to the database.
+func (s *eventSerializer) persist(ctx context.Context, events []store.BlockEvent) {
+	if err := s.write(ctx, s.blockID, events); err != nil {
+		slog.Error("Failed to append events",
 			"block_id", s.blockID,
-			"event_type", event.eventType,
+			"event_count", len(events),
 			"error", err,
 		)
 	}
@@ -107,20 +173,28 @@ func (s *eventSerializer) persist(ctx context.Context, event *blockEvent) {
 // The non-blocking select with default case means events may be dropped when
 // the channel buffer (100 events) is saturated. This is intentional: it prevents
 // slow event persistence from blocking the streaming response. In practice, with
-// a 100-event buffer and typical persistence latency (<1ms per event), the channel
-// should rarely be full. If events are being dropped, consider:
+// a 100-event buffer and events written in batches, the channel should rarely
+// be full. Dropped events are counted (see BlockManager.DroppedEvents); if
+// events are being dropped, consider:
 // 1. Increasing the buffer size in getOrCreateSerializer
-// 2. Adding metrics to track dropped events
-// 3. Using a blocking enqueue with timeout
+// 2. Using a blocking enqueue with timeout
 func (s *eventSerializer) enqueue(eventType string, content string, metadata map[string]any) bool {
+	event := store.BlockEvent{
+		Type:      eventType,
+		Content:   content,
+		Timestamp: time.Now().UnixMilli(), // Stamped here since writes are deferred
+		Meta:      metadata,
+	}
 	select {
-	case s.channel <- &blockEvent{eventType, content, metadata}:
+	case s.channel <- event:
 		return true
 	case <-s.stopCh:
 		return false
 	default:
 		// Channel is full - event is dropped
-		// TODO: Add metrics to track dropped events
+		if s.dropped != nil {
+			s.dropped.Add(1)
+		}
 		return false
 	}
 }
@@ -168,10 +242,11 @@ func (m *BlockManager) getOrCreateSerializer(blockID int64) *eventSerializer {
 
 	s := &eventSerializer{
 		blockID:    blockID,
-		manager:    m,
-		channel:    make(chan *blockEvent, 100), // Buffered channel for throughput
+		write:      m.store.AppendEventsBatch,
+		channel:    make(chan store.BlockEvent, 100), // Buffered channel for throughput
 		stopCh:     make(chan struct{}),
 		createTime: time.Now(), // Track creation time for timeout cleanup
+		dropped:    &m.dropped,
 	}
 	s.start()
 
@@ -251,6 +326,10 @@ func (m *BlockManager) CreateBlockForChat(
 	agentType AgentType,
 	mode BlockMode,
 ) (*store.AIBlock, error) {
+	ctx, span := observability.StartSpan(ctx, "block.create",
+		attribute.Int("conversation.id", int(conversationID)),
+		attribute.String("block.mode", string(mode)),
+	)
 	now := time.Now().UnixMilli()
 
 	// All modes use MESSAGE type (context_separator is created separately)
@@ -279,8 +358,11 @@ func (m *BlockManager) CreateBlockForChat(
 			"conversation_id", conversationID,
 			"error", err,
 		)
+		observability.EndSpan(span, err)
 		return nil, err
 	}
+	span.SetAttributes(attribute.Int64("block.id", block.ID))
+	observability.EndSpan(span, nil)
 
 	slog.Info("Created block for chat",
 		"block_id", block.ID,
@@ -291,6 +373,113 @@ func (m *BlockManager) CreateBlockForChat(
 	return block, nil
 }
 
+// ResumeBlock reopens an existing block so a follow-up round (e.g. a submitted
+// form) streams into it instead of creating a new block.
+func (m *BlockManager) ResumeBlock(
+	ctx context.Context,
+	blockID int64,
+) (*store.AIBlock, error) {
+	status := store.AIBlockStatusStreaming
+	now := time.Now().UnixMilli()
+	block, err := m.store.UpdateAIBlock(ctx, &store.UpdateAIBlock{
+		ID:        blockID,
+		Status:    &status,
+		UpdatedTs: &now,
+	})
+	if err != nil {
+		slog.Error("Failed to resume block",
+			"block_id", blockID,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	slog.Info("Resumed block for chat",
+		"block_id", block.ID,
+		"conversation_id", block.ConversationID,
+	)
+
+	return block, nil
+}
+
+// SetPendingForm records the form awaiting submission in block metadata.
+// Passing nil clears it after the form has been submitted.
+func (m *BlockManager) SetPendingForm(
+	ctx context.Context,
+	blockID int64,
+	form any,
+) error {
+	if _, err := m.store.UpdateAIBlock(ctx, &store.UpdateAIBlock{
+		ID:       blockID,
+		Metadata: map[string]any{PendingFormMetadataKey: form},
+	}); err != nil {
+		slog.Error("Failed to set pending form",
+			"block_id", blockID,
+			"error", err,
+		)
+		return err
+	}
+	return nil
+}
+
+// SetPendingAgentChoice records the agent suggestions awaiting a choice in block
+// metadata. Passing nil clears it after the user has picked an agent.
+func (m *BlockManager) SetPendingAgentChoice(
+	ctx context.Context,
+	blockID int64,
+	suggestions any,
+) error {
+	if _, err := m.store.UpdateAIBlock(ctx, &store.UpdateAIBlock{
+		ID:       blockID,
+		Metadata: map[string]any{PendingAgentChoiceMetadataKey: suggestions},
+	}); err != nil {
+		slog.Error("Failed to set pending agent choice",
+			"block_id", blockID,
+			"error", err,
+		)
+		return err
+	}
+	return nil
+}
+
+// SetSuggestions records the follow-up questions for a completed block in its metadata.
+func (m *BlockManager) SetSuggestions(
+	ctx context.Context,
+	blockID int64,
+	suggestions []string,
+) error {
+	if _, err := m.store.UpdateAIBlock(ctx, &store.UpdateAIBlock{
+		ID:       blockID,
+		Metadata: map[string]any{SuggestionsMetadataKey: suggestions},
+	}); err != nil {
+		slog.Error("Failed to set follow-up suggestions",
+			"block_id", blockID,
+			"error", err,
+		)
+		return err
+	}
+	return nil
+}
+
+// SetBudgetExceeded records in the budget section of block metadata that the
+// block was stopped by its budget.
+func (m *BlockManager) SetBudgetExceeded(
+	ctx context.Context,
+	blockID int64,
+	exceeded *BudgetExceeded,
+) error {
+	update := &store.UpdateAIBlock{ID: blockID}
+	update.SetBudget(exceeded.metadata())
+	if _, err := m.store.UpdateAIBlock(ctx, update); err != nil {
+		slog.Error("Failed to set budget exceeded",
+			"block_id", blockID,
+			"error", err,
+		)
+		return err
+	}
+	return nil
+}
+
 // AppendEvent appends an event to the block's event stream.
 //
 // Events are queued and persisted in order by a dedicated goroutine per block.
@@ -407,6 +596,16 @@ func (m *BlockManager) UpdateBlockStatus(
 		UpdatedTs:        &now,
 	}
 
+	if status == store.AIBlockStatusCompleted {
+		citations := &store.CitationsMetadata{Memos: citedMemos(assistantContent)}
+		if doc := retrieval.DocumentFromContext(ctx); doc != nil {
+			citations.Document = citedDocument(doc, assistantContent)
+		}
+		if len(citations.Memos) > 0 || citations.Document != nil {
+			update.SetCitations(citations)
+		}
+	}
+
 	if sessionStats != nil {
 		update.SessionStats = sessionStats
 
@@ -442,52 +641,107 @@ func (m *BlockManager) UpdateBlockStatus(
 		"round_number", block.RoundNumber,
 	)
 
+	if m.onStatus != nil {
+		m.onStatus(ctx, block)
+	}
 	return nil
 }
 
+// memoCitationPattern matches the links to memos (/memos/{uid}) of answers.
+var memoCitationPattern = regexp.MustCompile(`/memos/([A-Za-z0-9_-]+)`)
+
+// citedMemos returns the UIDs of the memos an answer links to, in order.
+func citedMemos(content string) []string {
+	var memos []string
+	for _, match := range memoCitationPattern.FindAllStringSubmatch(content, -1) {
+		if !slices.Contains(memos, match[1]) {
+			memos = append(memos, match[1])
+		}
+	}
+	return memos
+}
+
+// documentCitationPattern matches the citations of passages of the bound
+// document ([@start-end]) of answers.
+var documentCitationPattern = regexp.MustCompile(`\[@(\d+)-(\d+)\]`)
+
+// citedDocument returns the document of a conversation bound to one, with the
+// passages the answer cites, in order. Citations out of the document are
+// dropped.
+func citedDocument(doc *retrieval.Document, content string) *store.DocumentCitation {
+	citation := &store.DocumentCitation{Kind: doc.Kind, UID: doc.UID, Title: doc.Title, Spans: []store.CitationSpan{}}
+	for _, match := range documentCitationPattern.FindAllStringSubmatch(content, -1) {
+		start, _ := strconv.Atoi(match[1])
+		end, _ := strconv.Atoi(match[2])
+		text := doc.Excerpt(start, end)
+		if text == "" || slices.ContainsFunc(citation.Spans, func(s store.CitationSpan) bool { return s.Start == start && s.End == end }) {
+			continue
+		}
+		citation.Spans = append(citation.Spans, store.CitationSpan{Start: start, End: end, Text: text})
+	}
+	return citation
+}
+
 // CompleteBlock marks a block as completed with the final assistant content.
 //
-// Stops the event serializer for this block after updating status.
+// Stops the event serializer for this block before updating status, so every
+// buffered event is written by the time the block is marked done.
 //
 // Safety: This is safe even if UpdateBlockStatus fails because:
 //  1. stopSerializer uses sync.Once, so multiple calls are idempotent
 //  2. The serializer drain in stop() ensures queued events are persisted
-//  3. If status update fails, the block remains in streaming state but events
-//     continue to be persisted until a subsequent CompleteBlock/MarkBlockError call
+//  3. If status update fails, the block remains in streaming state and later
+//     events get a new serializer until a subsequent CompleteBlock/MarkBlockError call
 func (m *BlockManager) CompleteBlock(
 	ctx context.Context,
 	blockID int64,
 	assistantContent string,
 	sessionStats *store.SessionStats,
 ) error {
-	err := m.UpdateBlockStatus(ctx, blockID, store.AIBlockStatusCompleted, assistantContent, sessionStats)
-	// Stop the event serializer after completing the block
-	// Even if UpdateBlockStatus failed, we stop the serializer to prevent resource leaks
+	// Flush buffered events before the block is marked completed
+	ctx, span := observability.StartSpan(ctx, "block.complete", attribute.Int64("block.id", blockID))
 	m.stopSerializer(blockID)
+	err := m.UpdateBlockStatus(ctx, blockID, store.AIBlockStatusCompleted, assistantContent, sessionStats)
+	observability.EndSpan(span, err)
 	return err
 }
 
 // MarkBlockError marks a block as failed with error status.
 //
-// Stops the event serializer for this block after updating status.
+// Stops the event serializer for this block before updating status, so every
+// buffered event is written by the time the block is marked done.
 //
 // Safety: This is safe even if UpdateBlockStatus fails because:
 //  1. stopSerializer uses sync.Once, so multiple calls are idempotent
 //  2. The serializer drain in stop() ensures queued events are persisted
-//  3. If status update fails, the block remains in streaming state but events
-//     continue to be persisted until a subsequent CompleteBlock/MarkBlockError call
+//  3. If status update fails, the block remains in streaming state and later
+//     events get a new serializer until a subsequent CompleteBlock/MarkBlockError call
 func (m *BlockManager) MarkBlockError(
 	ctx context.Context,
 	blockID int64,
 	errorMessage string,
 ) error {
-	err := m.UpdateBlockStatus(ctx, blockID, store.AIBlockStatusError, errorMessage, nil)
-	// Stop the event serializer after marking error
-	// Even if UpdateBlockStatus failed, we stop the serializer to prevent resource leaks
+	// Flush buffered events before the block is marked failed
+	ctx, span := observability.StartSpan(ctx, "block.error", attribute.Int64("block.id", blockID))
 	m.stopSerializer(blockID)
+	err := m.UpdateBlockStatus(ctx, blockID, store.AIBlockStatusError, errorMessage, nil)
+	observability.EndSpan(span, err)
 	return err
 }
 
+// MarkBlockInterrupted marks a block stopped by the user, keeping the content
+// produced before the stop.
+//
+// Stops the event serializer for this block before updating status.
+func (m *BlockManager) MarkBlockInterrupted(
+	ctx context.Context,
+	blockID int64,
+	partialContent string,
+) error {
+	m.stopSerializer(blockID)
+	return m.UpdateBlockStatus(ctx, blockID, store.AIBlockStatusInterrupted, partialContent, nil)
+}
+
 // GetLatestBlock retrieves the most recent block for a conversation.
 func (m *BlockManager) GetLatestBlock(
 	ctx context.Context,
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hrygo/divinesense/ai/core/retrieval"
 	"github.com/hrygo/divinesense/store"
 )
 
@@ -645,8 +646,8 @@ func TestEventSerializer_DoubleStop(t *testing.T) {
 
 	s := &eventSerializer{
 		blockID:    int64(1),
-		manager:    manager.BlockManager,
-		channel:    make(chan *blockEvent, 10),
+		write:      manager.BlockManager.store.AppendEventsBatch,
+		channel:    make(chan store.BlockEvent, 10),
 		stopCh:     make(chan struct{}),
 		createTime: time.Now(),
 	}
@@ -664,6 +665,85 @@ func TestEventSerializer_DoubleStop(t *testing.T) {
 	}
 }
 
+// batchRecorder records the batches written by an event serializer.
+type batchRecorder struct {
+	mu      sync.Mutex
+	batches [][]store.BlockEvent
+}
+
+func (r *batchRecorder) write(_ context.Context, _ int64, events []store.BlockEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, events)
+	return nil
+}
+
+func (r *batchRecorder) sizes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sizes []int
+	for _, batch := range r.batches {
+		sizes = append(sizes, len(batch))
+	}
+	return sizes
+}
+
+func newRecordedSerializer(r *batchRecorder) *eventSerializer {
+	s := &eventSerializer{
+		blockID:    int64(1),
+		write:      r.write,
+		channel:    make(chan store.BlockEvent, 100),
+		stopCh:     make(chan struct{}),
+		createTime: time.Now(),
+	}
+	s.start()
+	return s
+}
+
+// TestEventSerializer_BatchesEvents verifies that events are coalesced into
+// batches and that stop flushes the rest in order.
+func TestEventSerializer_BatchesEvents(t *testing.T) {
+	recorder := &batchRecorder{}
+	s := newRecordedSerializer(recorder)
+
+	const numEvents = eventBatchSize*2 + 5
+	for i := 0; i < numEvents; i++ {
+		require.True(t, s.enqueue("answer", fmt.Sprintf("chunk %d", i), nil))
+	}
+	s.stop()
+
+	sizes := recorder.sizes()
+	total := 0
+	for _, size := range sizes {
+		assert.LessOrEqual(t, size, eventBatchSize)
+		total += size
+	}
+	assert.Equal(t, numEvents, total, "stop should flush every pending event")
+	assert.Less(t, len(sizes), numEvents, "events should be written in batches")
+
+	i := 0
+	for _, batch := range recorder.batches {
+		for _, event := range batch {
+			assert.Equal(t, fmt.Sprintf("chunk %d", i), event.Content)
+			assert.NotZero(t, event.Timestamp)
+			i++
+		}
+	}
+}
+
+// TestEventSerializer_FlushInterval verifies that a partial batch is written
+// without waiting for stop.
+func TestEventSerializer_FlushInterval(t *testing.T) {
+	recorder := &batchRecorder{}
+	s := newRecordedSerializer(recorder)
+	defer s.stop()
+
+	require.True(t, s.enqueue("thinking", "hmm", nil))
+	assert.Eventually(t, func() bool {
+		return len(recorder.sizes()) == 1
+	}, 10*eventFlushInterval, eventFlushInterval/5)
+}
+
 // TestBlockManager_CleanupStaleSerializers tests the cleanup of old serializers.
 // Since we cannot directly manipulate createTime without reflection complexity,
 // we test that the cleanup function works correctly and serializers are properly stopped.
@@ -763,3 +843,45 @@ func TestBlockManager_MarkBlockError_CleansSerializer(t *testing.T) {
 		t.Errorf("AppendEvent should still work after MarkBlockError: %v", err)
 	}
 }
+
+func TestCitedMemos(t *testing.T) {
+	content := "See [trip](/memos/abc123) and [notes](https://example.com/memos/x_y-9), again [trip](/memos/abc123)."
+	assert.Equal(t, []string{"abc123", "x_y-9"}, citedMemos(content))
+	assert.Empty(t, citedMemos("No citations"))
+}
+
+func TestCitedDocument(t *testing.T) {
+	doc := &retrieval.Document{Kind: "memo", UID: "m1", Title: "Plan", Text: "Ship v2 in May. Hire two engineers."}
+
+	citation := citedDocument(doc, "Shipping is planned for May [@0-15], with hiring [@16-35] [@0-15] and more [@90-120].")
+	assert.Equal(t, "memo", citation.Kind)
+	assert.Equal(t, []store.CitationSpan{
+		{Start: 0, End: 15, Text: "Ship v2 in May."},
+		{Start: 16, End: 35, Text: "Hire two engineers."},
+	}, citation.Spans)
+
+	// The document is recorded even when no passage is cited
+	assert.Empty(t, citedDocument(doc, "No citation.").Spans)
+}
+
+// TestBlockManager_QueueDepthAndDrops verifies that the queued events are
+// reported and that events dropped on a full queue are counted.
+func TestBlockManager_QueueDepthAndDrops(t *testing.T) {
+	manager := &BlockManager{}
+	// Not started: nothing consumes the queue
+	s := &eventSerializer{
+		blockID:    int64(1),
+		channel:    make(chan store.BlockEvent, 2),
+		stopCh:     make(chan struct{}),
+		createTime: time.Now(),
+		dropped:    &manager.dropped,
+	}
+	manager.serializers.Store(s.blockID, s)
+
+	assert.True(t, s.enqueue("answer", "a", nil))
+	assert.True(t, s.enqueue("answer", "b", nil))
+	assert.False(t, s.enqueue("answer", "c", nil))
+
+	assert.Equal(t, 2, manager.QueueDepth())
+	assert.Equal(t, int64(1), manager.DroppedEvents())
+}
@@ -0,0 +1,205 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/hrygo/divinesense/ai/core/llm"
+	"github.com/hrygo/divinesense/plugin/blockbudget"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/server/internal/observability"
+	"github.com/hrygo/divinesense/store"
+)
+
+// EventTypeBudgetExceeded reports that the block crossed its budget and was
+// stopped. EventData is the JSON-encoded BudgetExceeded; the done event
+// follows with the "budget_exceeded" status.
+const EventTypeBudgetExceeded = "budget_exceeded"
+
+// BlockSummaryStatusBudgetExceeded is the BlockSummary status of blocks
+// stopped by their budget.
+const BlockSummaryStatusBudgetExceeded = "budget_exceeded"
+
+// budgetCheckInterval is the minimum gap between budget checks of streamed
+// content, whose tokens are estimated; reported usage is always checked.
+const budgetCheckInterval = 200 * time.Millisecond
+
+// errBudgetExceeded is the cancellation cause of blocks stopped by their budget.
+var errBudgetExceeded = stderrors.New("block budget exceeded")
+
+// BudgetExceeded is the payload of a budget_exceeded event.
+type BudgetExceeded struct {
+	Budget *blockbudget.Budget `json:"budget"`
+	// Usage is the usage of the block when it was stopped.
+	Usage UsageDelta `json:"usage"`
+	// PartialContent is the answer produced before the stop.
+	PartialContent string `json:"partial_content"`
+}
+
+// metadata returns the budget section of the block metadata recording the stop.
+func (e *BudgetExceeded) metadata() *store.BudgetMetadata {
+	section := &store.BudgetMetadata{
+		Usage: store.BudgetUsage{
+			InputTokens:      e.Usage.InputTokens,
+			OutputTokens:     e.Usage.OutputTokens,
+			TotalTokens:      e.Usage.TotalTokens,
+			EstimatedCostUSD: e.Usage.EstimatedCostUSD,
+			Estimated:        e.Usage.Estimated,
+			Model:            e.Usage.Model,
+			ElapsedMs:        e.Usage.ElapsedMs,
+		},
+		PartialContent: e.PartialContent,
+	}
+	if e.Budget != nil {
+		section.Budget = store.BudgetLimits{MaxTokens: e.Budget.MaxTokens, MaxCostUSD: e.Budget.MaxCostUSD}
+	}
+	return section
+}
+
+// budgetGuard stops a block when its usage crosses its budget.
+type budgetGuard struct {
+	budget *blockbudget.Budget
+	usage  *usageTracker
+	cancel context.CancelCauseFunc
+
+	mu        sync.Mutex
+	lastCheck time.Time
+	exceeded  *UsageDelta
+}
+
+// withBudgetGuard returns the context to run a block in: its LLM calls report
+// usage to the tracker, and it is canceled once the usage crosses the budget.
+// Without a budget, ctx is returned as is. Call stop when the block is done.
+func withBudgetGuard(ctx context.Context, budget *blockbudget.Budget, usage *usageTracker) (context.Context, *budgetGuard, context.CancelFunc) {
+	guard := &budgetGuard{budget: budget, usage: usage}
+	if !budget.Limited() {
+		return ctx, guard, func() {}
+	}
+	ctx, guard.cancel = context.WithCancelCause(ctx)
+	ctx = llm.WithUsageObserver(ctx, guard.observe)
+	return ctx, guard, func() { guard.cancel(context.Canceled) }
+}
+
+// observe records an update and checks the budget.
+func (g *budgetGuard) observe(update llm.UsageUpdate) {
+	g.usage.Observe(update)
+
+	now := time.Now()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.exceeded != nil || (update.Stats == nil && !update.Final && now.Sub(g.lastCheck) < budgetCheckInterval) {
+		return
+	}
+	g.lastCheck = now
+	snapshot := g.usage.Snapshot(now)
+	if g.budget.Exceeded(snapshot.TotalTokens, snapshot.EstimatedCostUSD) {
+		g.exceeded = &snapshot
+		g.cancel(errBudgetExceeded)
+	}
+}
+
+// Exceeded returns the usage at which the block was stopped, or nil if it
+// stayed within its budget.
+func (g *budgetGuard) Exceeded() *UsageDelta {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.exceeded
+}
+
+// Event returns the budget_exceeded payload of a stopped block.
+func (g *budgetGuard) Event(partialContent string) (*BudgetExceeded, string) {
+	exceeded := &BudgetExceeded{Budget: g.budget, Usage: *g.Exceeded(), PartialContent: partialContent}
+	data, _ := json.Marshal(exceeded)
+	return exceeded, string(data)
+}
+
+// emitBudgetExceeded sends the budget_exceeded event of a stopped block and
+// records it in the block's events and metadata.
+func (h *ParrotHandler) emitBudgetExceeded(
+	ctx context.Context,
+	send func(*v1pb.ChatResponse) error,
+	block *store.AIBlock,
+	guard *budgetGuard,
+	partialContent string,
+	logger *observability.RequestContext,
+) {
+	exceeded, data := guard.Event(partialContent)
+	var blockID int64
+	if block != nil && h.blockManager != nil {
+		blockID = block.ID
+		if err := h.blockManager.AppendEvent(ctx, block.ID, EventTypeBudgetExceeded, data, nil); err != nil {
+			logger.Warn("Failed to enqueue budget_exceeded for persistence",
+				slog.Int64("block_id", block.ID),
+				slog.String("error", err.Error()))
+		}
+		if err := h.blockManager.SetBudgetExceeded(ctx, block.ID, exceeded); err != nil {
+			logger.Warn("Failed to record budget_exceeded",
+				slog.Int64("block_id", block.ID),
+				slog.String("error", err.Error()))
+		}
+	}
+
+	logger.Info("ai.block.budget_exceeded",
+		slog.Int64("block_id", blockID),
+		slog.Int("total_tokens", exceeded.Usage.TotalTokens),
+		slog.Float64("cost_usd", exceeded.Usage.EstimatedCostUSD),
+		slog.Int("max_tokens", exceeded.Budget.MaxTokens),
+		slog.Float64("max_cost_usd", exceeded.Budget.MaxCostUSD))
+
+	if err := send(&v1pb.ChatResponse{
+		EventType: EventTypeBudgetExceeded,
+		EventData: data,
+		BlockId:   blockID,
+	}); err != nil {
+		logger.Warn("Failed to send budget_exceeded", slog.String("error", err.Error()))
+	}
+}
+
+// completeOverBudget ends a block stopped by its budget: the budget_exceeded
+// event is sent, and the block is completed with the content produced so far.
+// It returns the summary of the done event.
+func (h *ParrotHandler) completeOverBudget(
+	ctx context.Context,
+	req *ChatRequest,
+	block *store.AIBlock,
+	guard *budgetGuard,
+	partialContent string,
+	durationMs int64,
+	send func(*v1pb.ChatResponse) error,
+	logger *observability.RequestContext,
+) *v1pb.BlockSummary {
+	h.emitBudgetExceeded(ctx, send, block, guard, partialContent, logger)
+
+	usage := guard.usage.Snapshot(time.Now())
+	summary := &v1pb.BlockSummary{
+		SessionId:         fmt.Sprintf("conv_%d", req.ConversationID),
+		TotalDurationMs:   durationMs,
+		Status:            BlockSummaryStatusBudgetExceeded,
+		TotalInputTokens:  int32(usage.InputTokens),
+		TotalOutputTokens: int32(usage.OutputTokens),
+		TotalCostUsd:      usage.EstimatedCostUSD,
+	}
+	if block != nil && h.blockManager != nil {
+		if err := h.blockManager.CompleteBlock(ctx, block.ID, partialContent, &store.SessionStats{
+			SessionID:       summary.SessionId,
+			UserID:          req.UserID,
+			AgentType:       string(req.AgentType),
+			TotalDurationMs: durationMs,
+			InputTokens:     usage.InputTokens,
+			OutputTokens:    usage.OutputTokens,
+			TotalTokens:     usage.TotalTokens,
+			TotalCostUsd:    usage.EstimatedCostUSD,
+			ModelUsed:       usage.Model,
+		}); err != nil {
+			logger.Warn("Failed to complete block stopped by its budget",
+				slog.Int64("block_id", block.ID),
+				slog.String("error", err.Error()))
+		}
+	}
+	return summary
+}
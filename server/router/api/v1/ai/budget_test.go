@@ -0,0 +1,45 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/ai/core/llm"
+	"github.com/hrygo/divinesense/plugin/blockbudget"
+)
+
+func TestBudgetGuard(t *testing.T) {
+	usage := newUsageTracker()
+	ctx, guard, stop := withBudgetGuard(context.Background(), &blockbudget.Budget{MaxTokens: 1000}, usage)
+	defer stop()
+	report := guard.observe
+
+	report(llm.UsageUpdate{CallID: 1, Stats: &llm.LLMCallStats{PromptTokens: 900, CompletionTokens: 50}})
+	require.Nil(t, guard.Exceeded())
+	require.NoError(t, ctx.Err())
+	require.Equal(t, 950, usage.Snapshot(usage.startedAt).TotalTokens, "the tracker still sees the usage")
+
+	report(llm.UsageUpdate{CallID: 2, Stats: &llm.LLMCallStats{PromptTokens: 100, CompletionTokens: 10}, Final: true})
+	exceeded := guard.Exceeded()
+	require.NotNil(t, exceeded)
+	require.Equal(t, 1060, exceeded.TotalTokens)
+	require.ErrorIs(t, context.Cause(ctx), errBudgetExceeded)
+
+	payload, data := guard.Event("partial answer")
+	require.Equal(t, "partial answer", payload.PartialContent)
+	var decoded BudgetExceeded
+	require.NoError(t, json.Unmarshal([]byte(data), &decoded))
+	require.Equal(t, 1000, decoded.Budget.MaxTokens)
+	require.Equal(t, 1060, decoded.Usage.TotalTokens)
+}
+
+func TestBudgetGuardUnlimited(t *testing.T) {
+	parent := context.Background()
+	ctx, guard, stop := withBudgetGuard(parent, nil, newUsageTracker())
+	stop()
+	require.Equal(t, parent, ctx)
+	require.Nil(t, guard.Exceeded())
+}
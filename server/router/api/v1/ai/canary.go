@@ -0,0 +1,150 @@
+package ai
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/ai/routing"
+	"github.com/hrygo/divinesense/plugin/canary"
+)
+
+// shadowTimeout bounds the shadow run of an incumbent, which outlives the
+// request when the canary answers first.
+const shadowTimeout = 2 * time.Minute
+
+// shadowSafeIntents are the intents eligible for canaries: read-only, so
+// running the incumbent in shadow has no side effects.
+var shadowSafeIntents = map[string]bool{
+	string(routing.IntentMemoSearch):    true,
+	string(routing.IntentScheduleQuery): true,
+}
+
+// canaryAgent returns the agent serving a request routed to incumbent: a
+// canary of incumbent when the user falls in its share of the traffic and
+// its feature flag, if any, is on for them; the incumbent otherwise.
+func (h *ParrotHandler) canaryAgent(ctx context.Context, incumbent agentpkg.ParrotAgent, cfg *CreateConfig, req *ChatRequest, intent string) agentpkg.ParrotAgent {
+	if h.canaries == nil || !shadowSafeIntents[intent] {
+		return incumbent
+	}
+	name, ok := h.canaries.Pick(incumbent.Name(), req.UserID)
+	if !ok {
+		return incumbent
+	}
+	if flag := h.canaryFlag(name); flag != "" && !h.featureOn(ctx, flag, req.UserID) {
+		return incumbent
+	}
+	candidate, err := h.factory.CreateCanary(name, cfg)
+	if err != nil {
+		slog.Warn("failed to create canary, using incumbent", "canary", name, "error", err)
+		return incumbent
+	}
+	return &canaryRun{canary: candidate, name: name, incumbent: incumbent, canaries: h.canaries, userID: req.UserID}
+}
+
+// canaryRun serves a request with a canary while its incumbent answers it in
+// shadow, then records the comparison.
+type canaryRun struct {
+	canary    agentpkg.ParrotAgent
+	name      string
+	incumbent agentpkg.ParrotAgent
+	canaries  *canary.Canaries
+	userID    int32
+}
+
+// Name returns the incumbent's name, so follow-ups keep their route.
+func (r *canaryRun) Name() string {
+	return r.incumbent.Name()
+}
+
+func (r *canaryRun) SelfDescribe() *agentpkg.ParrotSelfCognition {
+	return r.canary.SelfDescribe()
+}
+
+func (r *canaryRun) GetSessionStats() *agentpkg.NormalSessionStats {
+	return r.canary.GetSessionStats()
+}
+
+// Execute runs the canary for the user and the incumbent in shadow.
+func (r *canaryRun) Execute(ctx context.Context, userInput string, history []string, callback agentpkg.EventCallback) error {
+	var wg sync.WaitGroup
+	shadow := &answerCapture{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		shadowCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shadowTimeout)
+		defer cancel()
+		shadow.run(shadowCtx, r.incumbent, userInput, history, nil)
+	}()
+
+	served := &answerCapture{}
+	served.run(ctx, r.canary, userInput, history, callback)
+
+	go func() {
+		wg.Wait()
+		comparison := &canary.Comparison{
+			Canary:             r.name,
+			Incumbent:          r.incumbent.Name(),
+			UserID:             r.userID,
+			Query:              userInput,
+			CanaryAnswer:       served.answer.String(),
+			IncumbentAnswer:    shadow.answer.String(),
+			CanaryLatencyMs:    served.latency.Milliseconds(),
+			IncumbentLatencyMs: shadow.latency.Milliseconds(),
+			CanaryTokens:       served.tokens,
+			IncumbentTokens:    shadow.tokens,
+			CanaryError:        errorText(served.err),
+			IncumbentError:     errorText(shadow.err),
+		}
+		recordCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := r.canaries.Record(recordCtx, comparison); err != nil {
+			slog.Warn("failed to record canary comparison", "canary", r.name, "error", err)
+		}
+	}()
+	return served.err
+}
+
+// answerCapture collects the answer and the cost of an agent run.
+type answerCapture struct {
+	mu      sync.Mutex
+	answer  strings.Builder
+	latency time.Duration
+	tokens  int
+	err     error
+}
+
+// run executes agent, forwarding its events to callback when set.
+func (c *answerCapture) run(ctx context.Context, agent agentpkg.ParrotAgent, userInput string, history []string, callback agentpkg.EventCallback) {
+	start := time.Now()
+	c.err = agent.Execute(ctx, userInput, history, func(eventType string, eventData any) error {
+		if eventType == "answer" || eventType == "content" {
+			c.mu.Lock()
+			switch data := eventData.(type) {
+			case string:
+				c.answer.WriteString(data)
+			case *agentpkg.EventWithMeta:
+				c.answer.WriteString(data.EventData)
+			}
+			c.mu.Unlock()
+		}
+		if callback == nil {
+			return nil
+		}
+		return callback(eventType, eventData)
+	})
+	c.latency = time.Since(start)
+	if stats := agent.GetSessionStats(); stats != nil {
+		c.tokens = stats.TotalTokens
+	}
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
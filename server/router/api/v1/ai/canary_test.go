@@ -0,0 +1,106 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/plugin/canary"
+)
+
+type fakeCanaryAgent struct {
+	name   string
+	answer string
+	err    error
+}
+
+func (a *fakeCanaryAgent) Name() string { return a.name }
+
+func (a *fakeCanaryAgent) Execute(_ context.Context, _ string, _ []string, callback agentpkg.EventCallback) error {
+	if err := callback("answer", a.answer); err != nil {
+		return err
+	}
+	return a.err
+}
+
+func (a *fakeCanaryAgent) SelfDescribe() *agentpkg.ParrotSelfCognition {
+	return &agentpkg.ParrotSelfCognition{Name: a.name}
+}
+
+func (a *fakeCanaryAgent) GetSessionStats() *agentpkg.NormalSessionStats {
+	return &agentpkg.NormalSessionStats{TotalTokens: len(a.answer)}
+}
+
+type fakeCanaryStore struct {
+	recorded chan *canary.Comparison
+}
+
+func (s *fakeCanaryStore) ListPercents(context.Context) (map[string]int, error) {
+	return map[string]int{}, nil
+}
+
+func (s *fakeCanaryStore) SavePercent(context.Context, string, int) error { return nil }
+
+func (s *fakeCanaryStore) SaveComparison(_ context.Context, comparison *canary.Comparison) error {
+	s.recorded <- comparison
+	return nil
+}
+
+func (s *fakeCanaryStore) ListComparisons(context.Context, string, int) ([]*canary.Comparison, error) {
+	return nil, nil
+}
+
+func (s *fakeCanaryStore) Metrics(context.Context, string, int64) (*canary.Metrics, error) {
+	return &canary.Metrics{}, nil
+}
+
+func TestCanaryRun_Execute(t *testing.T) {
+	store := &fakeCanaryStore{recorded: make(chan *canary.Comparison, 1)}
+	run := &canaryRun{
+		canary:    &fakeCanaryAgent{name: "memo_v2", answer: "找到 2 条周报"},
+		name:      "memo_v2",
+		incumbent: &fakeCanaryAgent{name: "memo", answer: "找到 3 条周报", err: errors.New("tool failed")},
+		canaries:  canary.New(store),
+		userID:    7,
+	}
+
+	// The user only sees the canary, under the incumbent's name
+	var streamed []string
+	err := run.Execute(context.Background(), "找一下周报", nil, func(eventType string, eventData any) error {
+		streamed = append(streamed, eventData.(string))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"找到 2 条周报"}, streamed)
+	assert.Equal(t, "memo", run.Name())
+
+	select {
+	case comparison := <-store.recorded:
+		assert.Equal(t, "memo_v2", comparison.Canary)
+		assert.Equal(t, "memo", comparison.Incumbent)
+		assert.Equal(t, int32(7), comparison.UserID)
+		assert.Equal(t, "找到 2 条周报", comparison.CanaryAnswer)
+		assert.Equal(t, "找到 3 条周报", comparison.IncumbentAnswer)
+		assert.Empty(t, comparison.CanaryError)
+		assert.Equal(t, "tool failed", comparison.IncumbentError)
+		assert.Greater(t, comparison.Similarity, 0.5)
+	case <-time.After(5 * time.Second):
+		t.Fatal("comparison not recorded")
+	}
+}
+
+func TestCanaryAgent_NotEligible(t *testing.T) {
+	canaries := canary.New(&fakeCanaryStore{})
+	canaries.Register(context.Background(), "memo_v2", "memo", 100)
+	h := &ParrotHandler{canaries: canaries}
+	incumbent := &fakeCanaryAgent{name: "memo"}
+
+	// Intents with side effects never reach a canary
+	agent := h.canaryAgent(context.Background(), incumbent, &CreateConfig{Type: AgentTypeMemo}, &ChatRequest{UserID: 1}, "memo_create")
+	assert.Same(t, incumbent, agent)
+}
@@ -0,0 +1,42 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/hrygo/divinesense/ai/core/retrieval"
+	"github.com/hrygo/divinesense/plugin/documentchat"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+)
+
+// EventTypeDocument reports the document a conversation is bound to before
+// routing. EventData is the JSON-encoded documentchat.Info.
+const EventTypeDocument = "document"
+
+// withDocument loads the document the conversation of a request is bound to
+// into ctx, for the memo searches, the context builder and the citations of
+// the answer, and reports it on the stream. The conversation falls back to
+// memo retrieval when the document fails to load, e.g. once deleted.
+func (h *ParrotHandler) withDocument(ctx context.Context, req *ChatRequest, stream ChatStream) context.Context {
+	if h.documentChats == nil || req.ConversationID <= 0 {
+		return ctx
+	}
+	doc, err := h.documentChats.Load(ctx, req.UserID, req.ConversationID)
+	if err != nil {
+		slog.Warn("failed to load conversation document", "conversation_id", req.ConversationID, "error", err)
+		return ctx
+	}
+	if doc == nil {
+		return ctx
+	}
+
+	data, err := json.Marshal(documentchat.InfoOf(doc))
+	if err == nil {
+		err = stream.Send(&v1pb.ChatResponse{EventType: EventTypeDocument, EventData: string(data)})
+	}
+	if err != nil {
+		slog.Warn("failed to send document event", "error", err)
+	}
+	return retrieval.WithDocument(ctx, doc)
+}
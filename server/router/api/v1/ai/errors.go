@@ -0,0 +1,50 @@
+package ai
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/server/internal/errors"
+)
+
+// ErrorDomain is the Domain of the ErrorInfo detail attached to AI errors.
+const ErrorDomain = "divinesense.ai"
+
+// EventTypeError is the event type of errors. The terminal error event of a
+// failed request (Done set) carries the JSON-encoded ErrorEvent.
+const EventTypeError = "error"
+
+// ErrorEvent is the payload of the terminal error event of a failed request.
+type ErrorEvent struct {
+	// Code is the error code, e.g. PROVIDER_TIMEOUT or CLI_NOT_FOUND.
+	Code string `json:"code"`
+	// Message is the user-facing message.
+	Message string `json:"message"`
+	// Retryable reports whether sending the same request again may succeed.
+	Retryable bool `json:"retryable"`
+}
+
+// NewErrorEvent returns the terminal error event of a request that failed with
+// err, an error returned by HandleError.
+func NewErrorEvent(err error, blockID int64) *v1pb.ChatResponse {
+	data, _ := json.Marshal(errorEventOf(err))
+	return &v1pb.ChatResponse{EventType: EventTypeError, EventData: string(data), Done: true, BlockId: blockID}
+}
+
+// errorEventOf describes err from its ErrorInfo detail, or from its gRPC code
+// when it has none.
+func errorEventOf(err error) ErrorEvent {
+	st := status.Convert(err)
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == ErrorDomain {
+			retryable, _ := strconv.ParseBool(info.GetMetadata()["retryable"])
+			return ErrorEvent{Code: info.GetReason(), Message: st.Message(), Retryable: retryable}
+		}
+	}
+	code := ToAIError(err).Code
+	return ErrorEvent{Code: string(code), Message: st.Message(), Retryable: errors.Retryable(code)}
+}
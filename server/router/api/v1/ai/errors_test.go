@@ -0,0 +1,38 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hrygo/divinesense/server/internal/errors"
+)
+
+func TestHandleErrorDetails(t *testing.T) {
+	err := HandleError(fmt.Errorf("execute: %w", errors.ContextBuildFailed(fmt.Errorf("db down"))))
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.Equal(t, errors.UserMessage(errors.ErrCodeContextBuildFailed), status.Convert(err).Message())
+
+	event := NewErrorEvent(err, 7)
+	require.Equal(t, EventTypeError, event.EventType)
+	require.True(t, event.Done)
+	require.Equal(t, int64(7), event.BlockId)
+
+	var payload ErrorEvent
+	require.NoError(t, json.Unmarshal([]byte(event.EventData), &payload))
+	require.Equal(t, ErrorEvent{
+		Code:      string(errors.ErrCodeContextBuildFailed),
+		Message:   errors.UserMessage(errors.ErrCodeContextBuildFailed),
+		Retryable: true,
+	}, payload)
+
+	// Status errors without details are described by their gRPC code.
+	var fallback ErrorEvent
+	event = NewErrorEvent(status.Error(codes.DeadlineExceeded, "too slow"), 0)
+	require.NoError(t, json.Unmarshal([]byte(event.EventData), &fallback))
+	require.Equal(t, ErrorEvent{Code: string(errors.ErrCodeTimeout), Message: "too slow", Retryable: true}, fallback)
+}
@@ -12,6 +12,24 @@ import (
 	scheduletools "github.com/hrygo/divinesense/ai/agents/tools/schedule"
 	"github.com/hrygo/divinesense/ai/agents/universal"
 	"github.com/hrygo/divinesense/ai/core/retrieval"
+	"github.com/hrygo/divinesense/plugin/checklist"
+	"github.com/hrygo/divinesense/plugin/contact"
+	"github.com/hrygo/divinesense/plugin/entitygraph"
+	"github.com/hrygo/divinesense/plugin/expense"
+	"github.com/hrygo/divinesense/plugin/fewshot"
+	"github.com/hrygo/divinesense/plugin/flashcard"
+	"github.com/hrygo/divinesense/plugin/habit"
+	"github.com/hrygo/divinesense/plugin/homeassistant"
+	"github.com/hrygo/divinesense/plugin/httpaction"
+	"github.com/hrygo/divinesense/plugin/mailbox"
+	"github.com/hrygo/divinesense/plugin/meetingnotes"
+	"github.com/hrygo/divinesense/plugin/pdfdoc"
+	"github.com/hrygo/divinesense/plugin/reminder"
+	"github.com/hrygo/divinesense/plugin/snippet"
+	"github.com/hrygo/divinesense/plugin/timeline"
+	"github.com/hrygo/divinesense/plugin/toolpack"
+	"github.com/hrygo/divinesense/plugin/usagestats"
+	"github.com/hrygo/divinesense/plugin/webfetch"
 	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
 	"github.com/hrygo/divinesense/server/service/schedule"
 	"github.com/hrygo/divinesense/store"
@@ -72,6 +90,26 @@ type AgentFactory struct {
 	retriever     *retrieval.AdaptiveRetriever
 	store         *store.Store
 	parrotFactory *universal.ParrotFactory
+	httpActions   *httpaction.Registry
+	webFetch      *webfetch.Fetcher
+	entityGraph   *entitygraph.Graph
+	contacts      *contact.Contacts
+	flashcards    *flashcard.Deck
+	documents     *pdfdoc.Library
+	snippets      *snippet.Library
+	timeline      *timeline.Timeline
+	usageStats    *usagestats.Stats
+	meetingNotes  *meetingnotes.Notes
+	checklists    *checklist.Checklists
+	habits        *habit.Habits
+	toolpack      *toolpack.Toolpack
+	expenses      *expense.Expenses
+	homeAssistant *homeassistant.Home
+	mailbox       *mailbox.Mailbox
+	reminders     *reminder.Reminders
+	fewShots      *fewshot.Library
+	modelLLM      universal.ModelLLMFunc
+	agentLLM      universal.AgentLLMFunc
 	mu            sync.RWMutex
 	initialized   bool
 }
@@ -91,6 +129,173 @@ func NewAgentFactory(
 	return factory
 }
 
+// SetHTTPActions exposes admin-registered HTTP actions as tools.
+// Must be called before Initialize.
+func (f *AgentFactory) SetHTTPActions(registry *httpaction.Registry) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.httpActions = registry
+}
+
+// SetWebFetch lets agents read web pages with the web_fetch tool, while the
+// admin policy enables it. Must be called before Initialize.
+func (f *AgentFactory) SetWebFetch(fetcher *webfetch.Fetcher) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.webFetch = fetcher
+}
+
+// SetEntityGraph exposes the user's knowledge graph as the entity_lookup tool.
+// Must be called before Initialize.
+func (f *AgentFactory) SetEntityGraph(graph *entitygraph.Graph) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.entityGraph = graph
+}
+
+// SetContacts exposes the people of the user's knowledge graph and their
+// follow-ups as the contact_lookup and contact_remind tools.
+// Must be called before Initialize.
+func (f *AgentFactory) SetContacts(contacts *contact.Contacts) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.contacts = contacts
+}
+
+// SetFlashcards lets agents generate flashcards with the flashcard_generate tool.
+// Must be called before Initialize.
+func (f *AgentFactory) SetFlashcards(deck *flashcard.Deck) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.flashcards = deck
+}
+
+// SetDocuments lets agents search the user's PDFs with the pdf_search tool.
+// Must be called before Initialize.
+func (f *AgentFactory) SetDocuments(documents *pdfdoc.Library) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.documents = documents
+}
+
+// SetSnippets lets agents search the user's code snippets with the
+// snippet_search tool, and the geek parrot browse them in its workspace.
+// Must be called before Initialize.
+func (f *AgentFactory) SetSnippets(library *snippet.Library) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.snippets = library
+}
+
+// SetTimeline lets agents answer questions about the user's past activity
+// with the activity_timeline tool.
+// Must be called before Initialize.
+func (f *AgentFactory) SetTimeline(t *timeline.Timeline) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.timeline = t
+}
+
+// SetUsageStats provides the usage_stats tool of the stats parrot.
+// Must be called before Initialize.
+func (f *AgentFactory) SetUsageStats(stats *usagestats.Stats) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.usageStats = stats
+}
+
+// SetFewShots shows the admin-curated answer examples of each route in the
+// system prompt of its parrot.
+// Must be called before Initialize.
+func (f *AgentFactory) SetFewShots(library *fewshot.Library) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fewShots = library
+}
+
+// SetModelLLM provides the LLMs of the shadows that try another model.
+// Must be called before Initialize.
+func (f *AgentFactory) SetModelLLM(fn universal.ModelLLMFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.modelLLM = fn
+}
+
+// SetAgentLLM provides the LLMs selected per parrot.
+// Must be called before Initialize.
+func (f *AgentFactory) SetAgentLLM(fn universal.AgentLLMFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.agentLLM = fn
+}
+
+// SetMeetingNotes provides the meeting_notes and meeting_schedule tools of
+// the meeting parrot. Must be called before Initialize.
+func (f *AgentFactory) SetMeetingNotes(notes *meetingnotes.Notes) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.meetingNotes = notes
+}
+
+// SetChecklists provides the checklist_create, checklist_list and
+// checklist_update tools of the checklist parrot. Must be called before
+// Initialize.
+func (f *AgentFactory) SetChecklists(checklists *checklist.Checklists) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.checklists = checklists
+}
+
+// SetHabits provides the habit_log and habit_status tools of the habit coach
+// of the general parrot. Must be called before Initialize.
+func (f *AgentFactory) SetHabits(habits *habit.Habits) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.habits = habits
+}
+
+// SetToolpack provides the current_time, weather and unit_convert tools of
+// the general parrot. Must be called before Initialize.
+func (f *AgentFactory) SetToolpack(toolkit *toolpack.Toolpack) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.toolpack = toolkit
+}
+
+// SetExpenses provides the expense_add and expense_summary tools of the
+// expense parrot. Must be called before Initialize.
+func (f *AgentFactory) SetExpenses(expenses *expense.Expenses) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.expenses = expenses
+}
+
+// SetHomeAssistant provides the ha_states and ha_call_service tools of the
+// home parrot; without it, the home parrot is not routed to. Must be called
+// before Initialize.
+func (f *AgentFactory) SetHomeAssistant(home *homeassistant.Home) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.homeAssistant = home
+}
+
+// SetMailbox provides the email_unread and email_action_items tools of the
+// mail parrot; without it, the mail parrot is not routed to. Must be called
+// before Initialize.
+func (f *AgentFactory) SetMailbox(mailboxes *mailbox.Mailbox) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.mailbox = mailboxes
+}
+
+// SetReminders provides the reminder_escalations and reminder_critical tools
+// of the schedule parrot. Must be called before Initialize.
+func (f *AgentFactory) SetReminders(reminders *reminder.Reminders) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reminders = reminders
+}
+
 // Initialize initializes the ParrotFactory with the given configuration.
 func (f *AgentFactory) Initialize(cfg *ai.UniversalParrotConfig) error {
 	f.mu.Lock()
@@ -118,11 +323,24 @@ func (f *AgentFactory) Initialize(cfg *ai.UniversalParrotConfig) error {
 		universal.WithConfigDir(configDir),
 		universal.WithToolFactories(toolFactories),
 		universal.WithBaseURL(cfg.BaseURL),
+		universal.WithDynamicTools(f.dynamicTools),
+		universal.WithFewShots(f.promptFewShots),
+		universal.WithModelLLM(f.modelLLM),
+		universal.WithAgentLLM(f.agentLLM),
 	)
 	if err != nil {
 		return fmt.Errorf("initialize parrot factory: %w", err)
 	}
 
+	// The home parrot is optional: it needs a Home Assistant instance
+	if f.homeAssistant == nil {
+		pf.RemoveConfig(homeassistant.ParrotName)
+	}
+	// The mail parrot is optional: it needs the email secret key
+	if f.mailbox == nil {
+		pf.RemoveConfig(mailbox.ParrotName)
+	}
+
 	f.parrotFactory = pf
 	f.initialized = true
 	slog.Info("AgentFactory initialized successfully",
@@ -293,9 +511,240 @@ func (f *AgentFactory) buildToolFactories() map[string]universal.ToolFactoryFunc
 		), nil
 	}
 
+	// usage_stats tool factory
+	// Read-only statistics of the user's own usage for the stats parrot
+	factories[usagestats.ToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		if f.usageStats == nil {
+			return nil, fmt.Errorf("usage statistics require PostgreSQL")
+		}
+		return f.usageStats.ToolFor(userID), nil
+	}
+
+	// meeting_notes and meeting_schedule tool factories
+	// Structured meeting memos and their confirmed action items for the meeting parrot
+	factories[meetingnotes.NotesToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		if f.meetingNotes == nil {
+			return nil, fmt.Errorf("meeting notes are not available")
+		}
+		return f.meetingNotes.NotesToolFor(userID), nil
+	}
+	factories[meetingnotes.ScheduleToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		if f.meetingNotes == nil {
+			return nil, fmt.Errorf("meeting notes are not available")
+		}
+		return f.meetingNotes.ScheduleToolFor(userID), nil
+	}
+
+	// checklist_create, checklist_list and checklist_update tool factories
+	// Persistent, editable checklists of the checklist parrot (PostgreSQL only)
+	factories[checklist.CreateToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		if f.checklists == nil {
+			return nil, fmt.Errorf("checklists require PostgreSQL")
+		}
+		return f.checklists.CreateToolFor(userID), nil
+	}
+	factories[checklist.ListToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		if f.checklists == nil {
+			return nil, fmt.Errorf("checklists require PostgreSQL")
+		}
+		return f.checklists.ListToolFor(userID), nil
+	}
+	factories[checklist.UpdateToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		if f.checklists == nil {
+			return nil, fmt.Errorf("checklists require PostgreSQL")
+		}
+		return f.checklists.UpdateToolFor(userID), nil
+	}
+
+	// habit_log and habit_status tool factories
+	// Habit coaching of the general parrot; without habits (not PostgreSQL)
+	// the tools are left out rather than failing the fallback parrot
+	factories[habit.LogToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		if f.habits == nil {
+			return nil, fmt.Errorf("habits require PostgreSQL: %w", agents.ErrToolUnavailable)
+		}
+		return f.habits.LogToolFor(userID), nil
+	}
+	factories[habit.StatusToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		if f.habits == nil {
+			return nil, fmt.Errorf("habits require PostgreSQL: %w", agents.ErrToolUnavailable)
+		}
+		return f.habits.StatusToolFor(userID), nil
+	}
+
+	// current_time, weather and unit_convert tool factories
+	// Utility tools of the general parrot; the weather tool is left out
+	// without a weather provider
+	factories[toolpack.TimeToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		if f.toolpack == nil {
+			return nil, fmt.Errorf("utility tools are not available: %w", agents.ErrToolUnavailable)
+		}
+		return f.toolpack.TimeTool(), nil
+	}
+	factories[toolpack.WeatherToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		if !f.toolpack.HasWeather() {
+			return nil, fmt.Errorf("no weather provider is configured: %w", agents.ErrToolUnavailable)
+		}
+		return f.toolpack.WeatherTool(), nil
+	}
+	factories[toolpack.ConvertToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		if f.toolpack == nil {
+			return nil, fmt.Errorf("utility tools are not available: %w", agents.ErrToolUnavailable)
+		}
+		return f.toolpack.ConvertTool(), nil
+	}
+
+	// expense_add and expense_summary tool factories
+	// Expenses of the expense parrot, recorded once the user confirmed them
+	// through request_form (PostgreSQL only)
+	factories[expense.AddToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		if f.expenses == nil {
+			return nil, fmt.Errorf("expenses require PostgreSQL")
+		}
+		return f.expenses.AddToolFor(userID), nil
+	}
+	factories[expense.SummaryToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		if f.expenses == nil {
+			return nil, fmt.Errorf("expenses require PostgreSQL")
+		}
+		return f.expenses.SummaryToolFor(userID), nil
+	}
+
+	// ha_states and ha_call_service tool factories
+	// Home Assistant devices of the home parrot, controlled once the user
+	// confirmed the action through request_form; only the allowed roles see them
+	factories[homeassistant.StatesToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		tool := f.homeAssistant.StatesToolFor(f.userRole(userID))
+		if tool == nil {
+			return nil, fmt.Errorf("home control is not available: %w", agents.ErrToolUnavailable)
+		}
+		return tool, nil
+	}
+	factories[homeassistant.ServiceToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		tool := f.homeAssistant.ServiceToolFor(userID, f.userRole(userID))
+		if tool == nil {
+			return nil, fmt.Errorf("home control is not available: %w", agents.ErrToolUnavailable)
+		}
+		return tool, nil
+	}
+
+	// email_unread and email_action_items tool factories
+	// Read-only IMAP access of the mail parrot, saving action items to the inbox
+	factories[mailbox.UnreadToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		if f.mailbox == nil {
+			return nil, fmt.Errorf("email is not available: %w", agents.ErrToolUnavailable)
+		}
+		return f.mailbox.UnreadToolFor(userID), nil
+	}
+	factories[mailbox.ActionItemsToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		if f.mailbox == nil {
+			return nil, fmt.Errorf("email is not available: %w", agents.ErrToolUnavailable)
+		}
+		return f.mailbox.ActionItemsToolFor(userID), nil
+	}
+
+	// reminder_escalations and reminder_critical tool factories
+	// Critical schedules of the schedule parrot, whose reminders escalate
+	// through a chain of channels until acknowledged
+	factories[reminder.EscalationsToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		if f.reminders == nil {
+			return nil, fmt.Errorf("reminders are not available: %w", agents.ErrToolUnavailable)
+		}
+		return f.reminders.EscalationsToolFor(userID), nil
+	}
+	factories[reminder.CriticalToolName] = func(userID int32) (agents.ToolWithSchema, error) {
+		if f.reminders == nil {
+			return nil, fmt.Errorf("reminders are not available: %w", agents.ErrToolUnavailable)
+		}
+		return f.reminders.CriticalToolFor(userID), nil
+	}
+
+	// request_form tool factory
+	// Lets experts ask structured clarifying questions answered via SubmitForm
+	factories["request_form"] = func(userID int32) (agents.ToolWithSchema, error) {
+		tool := tools.NewFormRequestTool()
+		return agents.ToolFromLegacy(
+			tool.Name(),
+			tool.Description(),
+			func(ctx context.Context, input string) (string, error) {
+				return tool.Run(ctx, input)
+			},
+			tool.InputType,
+		), nil
+	}
+
 	return factories
 }
 
+// dynamicTools returns the optional tools available to the user: the
+// entity_lookup, contact_lookup, contact_remind, flashcard_generate,
+// pdf_search, snippet_search, activity_timeline and web_fetch tools and the
+// HTTP actions of their role.
+func (f *AgentFactory) dynamicTools(userID int32) []agents.ToolWithSchema {
+	tools := f.httpActionTools(userID)
+	if tool := f.entityGraph.ToolFor(userID); tool != nil {
+		tools = append(tools, tool)
+	}
+	if tool := f.contacts.LookupToolFor(userID); tool != nil {
+		tools = append(tools, tool, f.contacts.RemindToolFor(userID))
+	}
+	if tool := f.flashcards.ToolFor(userID); tool != nil {
+		tools = append(tools, tool)
+	}
+	if tool := f.documents.ToolFor(userID); tool != nil {
+		tools = append(tools, tool)
+	}
+	if tool := f.snippets.ToolFor(userID); tool != nil {
+		tools = append(tools, tool)
+	}
+	if tool := f.timeline.ToolFor(userID); tool != nil {
+		tools = append(tools, tool)
+	}
+	if tool := f.webFetch.ToolFor(userID); tool != nil {
+		tools = append(tools, tool)
+	}
+	return tools
+}
+
+// maxPromptFewShots is the number of answer examples shown to a parrot.
+const maxPromptFewShots = 5
+
+// promptFewShots returns the answer examples of the parrot's route.
+func (f *AgentFactory) promptFewShots(parrot string) []universal.FewShot {
+	if f.fewShots == nil {
+		return nil
+	}
+	var shots []universal.FewShot
+	for _, example := range f.fewShots.Answers(context.Background(), parrot, maxPromptFewShots) {
+		shots = append(shots, universal.FewShot{Query: example.Query, Answer: example.Answer})
+	}
+	return shots
+}
+
+// httpActionTools returns the HTTP actions the user's role may invoke.
+func (f *AgentFactory) httpActionTools(userID int32) []agents.ToolWithSchema {
+	if f.httpActions == nil {
+		return nil
+	}
+	role := f.userRole(userID)
+	if role == "" {
+		return nil
+	}
+	return f.httpActions.ToolsFor(context.Background(), userID, role)
+}
+
+// userRole returns the role of a user, or "" when it cannot be loaded.
+func (f *AgentFactory) userRole(userID int32) string {
+	if f.store == nil {
+		return ""
+	}
+	user, err := f.store.GetUser(context.Background(), &store.FindUser{ID: &userID})
+	if err != nil || user == nil {
+		return ""
+	}
+	return user.Role.String()
+}
+
 // Create creates an agent based on the configuration.
 func (f *AgentFactory) Create(ctx context.Context, cfg *CreateConfig) (agents.ParrotAgent, error) {
 	f.mu.RLock()
@@ -339,6 +788,67 @@ func (f *AgentFactory) createScheduleParrot(_ context.Context, cfg *CreateConfig
 	return f.parrotFactory.CreateScheduleParrot(cfg.UserID, scheduleSvc)
 }
 
+// CreateCanary creates a canary parrot by name, with the services of the
+// agent type it stands in for.
+func (f *AgentFactory) CreateCanary(name string, cfg *CreateConfig) (agents.ParrotAgent, error) {
+	return f.createVariant(name, cfg)
+}
+
+// CreateShadow creates a shadow parrot by name, with the services of the
+// agent type it runs alongside.
+func (f *AgentFactory) CreateShadow(name string, cfg *CreateConfig) (agents.ParrotAgent, error) {
+	return f.createVariant(name, cfg)
+}
+
+// createVariant creates a parrot that is not routed to on its own, with the
+// services of the agent type of the request.
+func (f *AgentFactory) createVariant(name string, cfg *CreateConfig) (agents.ParrotAgent, error) {
+	f.mu.RLock()
+	pf := f.parrotFactory
+	f.mu.RUnlock()
+	if pf == nil {
+		return nil, fmt.Errorf("factory not initialized, call Initialize first")
+	}
+
+	parrot, err := pf.CreateParrot(name, cfg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if up, ok := parrot.(*universal.UniversalParrot); ok {
+		switch cfg.Type {
+		case AgentTypeSchedule:
+			if f.store != nil {
+				up.SetScheduleService(schedule.NewService(f.store))
+			}
+		default:
+			if f.retriever != nil {
+				up.SetRetriever(f.retriever)
+			}
+		}
+	}
+	return parrot, nil
+}
+
+// CanaryConfigs returns the configs of the canary parrots.
+func (f *AgentFactory) CanaryConfigs() []*universal.ParrotConfig {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.parrotFactory == nil {
+		return nil
+	}
+	return f.parrotFactory.ListCanaries()
+}
+
+// ShadowConfigs returns the configs of the shadow parrots.
+func (f *AgentFactory) ShadowConfigs() []*universal.ParrotConfig {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.parrotFactory == nil {
+		return nil
+	}
+	return f.parrotFactory.ListShadows()
+}
+
 // GetSelfCognitionConfigs returns all ParrotSelfCognition configurations from the factory.
 // This is used by routing to build config-driven intent mappings.
 func (f *AgentFactory) GetSelfCognitionConfigs() []*agents.ParrotSelfCognition {
@@ -0,0 +1,53 @@
+package ai
+
+import (
+	"context"
+
+	"github.com/hrygo/divinesense/plugin/featureflag"
+)
+
+// Feature flags gating the risky features of chat.
+const (
+	// FlagOrchestrator sends complex and multi-intent requests to the
+	// orchestrator; off, they go to the memo parrot.
+	FlagOrchestrator = "orchestrator"
+	// FlagEvolutionMode allows Evolution Mode to admins.
+	FlagEvolutionMode = "evolution_mode"
+)
+
+// RegisterFeatureFlags declares the flags of chat, including those of the
+// canaries of an initialized factory.
+func RegisterFeatureFlags(flags *featureflag.Flags, factory *AgentFactory) {
+	flags.Register(FlagOrchestrator, "Send complex and multi-intent chat requests to the orchestrator", true)
+	flags.Register(FlagEvolutionMode, "Allow Evolution Mode, in which admins let the assistant change its source code", true)
+	if factory == nil {
+		return
+	}
+	for _, config := range factory.CanaryConfigs() {
+		if config.Canary.Flag != "" {
+			flags.Register(config.Canary.Flag, "Serve the users it is on for with the canary expert "+config.Name, false)
+		}
+	}
+}
+
+// featureOn reports whether a flag is on for a user; without flags, every
+// feature is.
+func (h *ParrotHandler) featureOn(ctx context.Context, key string, userID int32) bool {
+	return h.featureFlags == nil || h.featureFlags.Enabled(ctx, key, userID)
+}
+
+// canaryFlag returns the feature flag of a canary, if any.
+func (h *ParrotHandler) canaryFlag(name string) string {
+	if h.factory == nil {
+		return ""
+	}
+	pf := h.factory.GetParrotFactory()
+	if pf == nil {
+		return ""
+	}
+	config, ok := pf.GetConfig(name)
+	if !ok || config.Canary == nil {
+		return ""
+	}
+	return config.Canary.Flag
+}
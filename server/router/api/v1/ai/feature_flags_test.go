@@ -0,0 +1,40 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/plugin/featureflag"
+)
+
+type fakeFlagStore struct{}
+
+func (fakeFlagStore) ListRules(context.Context) ([]*featureflag.Rule, error) { return nil, nil }
+func (fakeFlagStore) SaveRule(context.Context, *featureflag.Rule) error      { return nil }
+func (fakeFlagStore) DeleteRule(context.Context, string) error               { return nil }
+
+func TestFeatureOn(t *testing.T) {
+	ctx := context.Background()
+
+	// Without flags, every feature is on
+	h := &ParrotHandler{}
+	assert.True(t, h.featureOn(ctx, FlagOrchestrator, 1))
+
+	flags := featureflag.New(fakeFlagStore{})
+	RegisterFeatureFlags(flags, nil)
+	h = &ParrotHandler{featureFlags: flags}
+	assert.True(t, h.featureOn(ctx, FlagOrchestrator, 1))
+	assert.True(t, h.featureOn(ctx, FlagEvolutionMode, 1))
+
+	_, err := flags.SetRule(ctx, &featureflag.Rule{Key: FlagOrchestrator, Users: []int32{2}})
+	require.NoError(t, err)
+	assert.False(t, h.featureOn(ctx, FlagOrchestrator, 1))
+	assert.True(t, h.featureOn(ctx, FlagOrchestrator, 2))
+}
+
+func TestCanaryFlag_WithoutFactory(t *testing.T) {
+	assert.Empty(t, (&ParrotHandler{}).canaryFlag("memo_v2"))
+}
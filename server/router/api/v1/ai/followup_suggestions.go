@@ -0,0 +1,229 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hrygo/divinesense/ai"
+	"github.com/hrygo/divinesense/ai/core/llm"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/store"
+)
+
+// EventTypeSuggestions is streamed after the done marker with follow-up
+// questions for the answer. EventData is the JSON-encoded list of questions,
+// also stored in block metadata under SuggestionsMetadataKey for clients that
+// have stopped listening.
+const EventTypeSuggestions = "suggestions"
+
+// SuggestionsMetadataKey is the block metadata key holding the follow-up questions.
+const SuggestionsMetadataKey = "suggestions"
+
+// suggestionPreferencesKey is the user_preferences key holding SuggestionSettings.
+const suggestionPreferencesKey = "suggestions"
+
+const (
+	// maxFollowUpSuggestions bounds the follow-up questions kept per answer.
+	maxFollowUpSuggestions = 3
+	// maxFollowUpRunes bounds the length of a follow-up question.
+	maxFollowUpRunes = 60
+	// followUpInputRunes bounds the question and answer sent to the model.
+	followUpInputRunes = 2000
+	// followUpTimeout bounds the generation of follow-up questions.
+	followUpTimeout = 15 * time.Second
+)
+
+// SuggestionSettings are the per-user follow-up suggestion preferences.
+type SuggestionSettings struct {
+	// Enabled turns follow-up questions after each answer on. Defaults to true.
+	Enabled bool `json:"enabled"`
+}
+
+// GetSuggestionSettings returns the suggestion settings of a user, or the
+// defaults if none were saved.
+func GetSuggestionSettings(ctx context.Context, st *store.Store, userID int32) (*SuggestionSettings, error) {
+	prefs, err := loadPreferences(ctx, st, userID)
+	if err != nil {
+		return nil, err
+	}
+	settings := &SuggestionSettings{Enabled: true}
+	if raw, ok := prefs[suggestionPreferencesKey]; ok {
+		if err := json.Unmarshal(raw, settings); err != nil {
+			return nil, fmt.Errorf("decode suggestion settings: %w", err)
+		}
+	}
+	return settings, nil
+}
+
+// UpdateSuggestionSettings saves the suggestion settings of a user, keeping the
+// other preferences untouched.
+func UpdateSuggestionSettings(ctx context.Context, st *store.Store, userID int32, settings *SuggestionSettings) error {
+	return savePreference(ctx, st, userID, suggestionPreferencesKey, settings)
+}
+
+// startFollowUpSuggestions generates follow-up questions for a completed block
+// in the background and stores them in block metadata. The returned channel
+// yields the questions once, or is closed without a value when suggestions are
+// disabled or fail. It returns nil when there is nothing to generate.
+func (h *ParrotHandler) startFollowUpSuggestions(ctx context.Context, userID int32, block *store.AIBlock, question, answer string) <-chan []string {
+	if h.suggestionLLM == nil || h.blockManager == nil || h.factory == nil || h.factory.store == nil ||
+		block == nil || strings.TrimSpace(answer) == "" {
+		return nil
+	}
+	// Suggestions are stored in clear in block metadata
+	if locked, _ := h.conversationLocked(block.ConversationID); locked {
+		return nil
+	}
+	if len(block.UserInputs) > 0 {
+		// A resumed block answers the original question, not the form answers.
+		question = block.UserInputs[0].Content
+	}
+
+	ch := make(chan []string, 1)
+	go func() {
+		defer close(ch)
+		// Outlive the chat request: the suggestions are stored even if the client left.
+		genCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), followUpTimeout)
+		defer cancel()
+
+		settings, err := GetSuggestionSettings(genCtx, h.factory.store, userID)
+		if err != nil {
+			slog.Warn("failed to load suggestion settings", "user_id", userID, "error", err)
+			return
+		}
+		if !settings.Enabled {
+			return
+		}
+		suggestions, err := generateFollowUps(genCtx, h.suggestionLLM, question, answer)
+		if err != nil {
+			slog.Warn("failed to generate follow-up suggestions", "block_id", block.ID, "error", err)
+			return
+		}
+		if len(suggestions) == 0 {
+			return
+		}
+		if err := h.blockManager.SetSuggestions(genCtx, block.ID, suggestions); err != nil {
+			return
+		}
+		ch <- suggestions
+	}()
+	return ch
+}
+
+// sendFollowUpSuggestions streams the suggestions event once the questions of
+// startFollowUpSuggestions are ready. It is called after the done marker, once
+// nothing else sends on stream.
+func sendFollowUpSuggestions(ctx context.Context, stream ChatStream, blockID int64, ch <-chan []string) {
+	if ch == nil {
+		return
+	}
+	var suggestions []string
+	select {
+	case suggestions = <-ch:
+	case <-ctx.Done():
+		// The questions are still stored in block metadata.
+		return
+	}
+	if len(suggestions) == 0 {
+		return
+	}
+	data, err := json.Marshal(suggestions)
+	if err != nil {
+		return
+	}
+	if err := stream.Send(&v1pb.ChatResponse{
+		BlockId:   blockID,
+		EventType: EventTypeSuggestions,
+		EventData: string(data),
+	}); err != nil {
+		slog.Warn("failed to send follow-up suggestions", "block_id", blockID, "error", err)
+	}
+}
+
+// SuggestionsFromBlock returns the follow-up questions stored on block, if any.
+func SuggestionsFromBlock(block *store.AIBlock) []string {
+	if block == nil || block.Metadata == nil {
+		return nil
+	}
+	raw, ok := block.Metadata[SuggestionsMetadataKey]
+	if !ok || raw == nil {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var suggestions []string
+	if err := json.Unmarshal(data, &suggestions); err != nil {
+		return nil
+	}
+	return suggestions
+}
+
+// generateFollowUps asks the model for questions the user may ask next.
+func generateFollowUps(ctx context.Context, llmSvc ai.LLMService, question, answer string) ([]string, error) {
+	prompt := fmt.Sprintf("用户的问题：\n%s\n\n助手的回答：\n%s",
+		TruncateString(question, followUpInputRunes),
+		TruncateString(answer, followUpInputRunes))
+	content, _, err := llmSvc.Chat(ctx, []llm.Message{
+		llm.SystemPrompt(followUpSystemPrompt),
+		llm.UserMessage(prompt),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseFollowUps(content), nil
+}
+
+// listMarker matches the bullet or number a model may put before a question.
+var listMarker = regexp.MustCompile(`^(?:[-*•]|\d+[.、)])\s*`)
+
+// parseFollowUps extracts the questions from a model response: a JSON object
+// {"questions": [...]}, a bare JSON array, or one question per line.
+func parseFollowUps(content string) []string {
+	content = strings.TrimSpace(content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	content = strings.TrimSpace(content)
+
+	var candidates []string
+	var result struct {
+		Questions []string `json:"questions"`
+	}
+	if err := json.Unmarshal([]byte(content), &result); err == nil {
+		candidates = result.Questions
+	} else if err := json.Unmarshal([]byte(content), &candidates); err != nil {
+		candidates = strings.Split(content, "\n")
+	}
+
+	questions := make([]string, 0, maxFollowUpSuggestions)
+	seen := make(map[string]bool)
+	for _, q := range candidates {
+		q = strings.TrimSpace(listMarker.ReplaceAllString(strings.TrimSpace(q), ""))
+		if q == "" || seen[q] || utf8.RuneCountInString(q) > maxFollowUpRunes {
+			continue
+		}
+		seen[q] = true
+		questions = append(questions, q)
+		if len(questions) == maxFollowUpSuggestions {
+			break
+		}
+	}
+	return questions
+}
+
+const followUpSystemPrompt = `你是一个对话助手。根据用户的问题和助手的回答，推测用户接下来最可能追问的问题。
+
+要求：
+1. 给出 2 到 3 个问题，每个不超过 30 字
+2. 以用户的口吻提问，可以直接发送给助手
+3. 紧扣回答内容，不要重复用户已经问过的问题
+4. 使用与用户问题一致的语言
+5. 返回JSON格式：{"questions": ["问题1", "问题2"]}`
@@ -0,0 +1,97 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/store"
+)
+
+func TestParseFollowUps(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "json object",
+			content: "```json\n{\"questions\": [\"明天的会议在哪里？\", \"帮我设个提醒\"]}\n```",
+			want:    []string{"明天的会议在哪里？", "帮我设个提醒"},
+		},
+		{
+			name:    "json array",
+			content: `["What else?", "What else?", "Why?"]`,
+			want:    []string{"What else?", "Why?"},
+		},
+		{
+			name:    "numbered lines",
+			content: "1. 第一个问题\n2、第二个问题\n- 第三个问题\n4) 第四个问题",
+			want:    []string{"第一个问题", "第二个问题", "第三个问题"},
+		},
+		{
+			name:    "numbers inside questions are kept",
+			content: "2025年的计划有哪些？",
+			want:    []string{"2025年的计划有哪些？"},
+		},
+		{
+			name:    "empty",
+			content: `{"questions": []}`,
+			want:    []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, parseFollowUps(tt.content))
+		})
+	}
+}
+
+type recordingStream struct {
+	ctx       context.Context
+	responses []*v1pb.ChatResponse
+}
+
+func (s *recordingStream) Send(resp *v1pb.ChatResponse) error {
+	s.responses = append(s.responses, resp)
+	return nil
+}
+
+func (s *recordingStream) Context() context.Context { return s.ctx }
+
+func TestSendFollowUpSuggestions(t *testing.T) {
+	ctx := context.Background()
+	stream := &recordingStream{ctx: ctx}
+
+	ch := make(chan []string, 1)
+	ch <- []string{"Next?"}
+	close(ch)
+	sendFollowUpSuggestions(ctx, stream, 7, ch)
+	require.Len(t, stream.responses, 1)
+	require.Equal(t, EventTypeSuggestions, stream.responses[0].EventType)
+	require.Equal(t, int64(7), stream.responses[0].BlockId)
+	require.JSONEq(t, `["Next?"]`, stream.responses[0].EventData)
+
+	// Disabled or failed generation closes the channel without a value.
+	closed := make(chan []string)
+	close(closed)
+	sendFollowUpSuggestions(ctx, stream, 7, closed)
+	sendFollowUpSuggestions(ctx, stream, 7, nil)
+	require.Len(t, stream.responses, 1)
+}
+
+func TestSuggestionsFromBlock(t *testing.T) {
+	require.Nil(t, SuggestionsFromBlock(nil))
+
+	// Block metadata is stored as generic JSON.
+	var metadata map[string]any
+	require.NoError(t, json.Unmarshal([]byte(`{"suggestions": ["a", "b"]}`), &metadata))
+	block := &store.AIBlock{Metadata: metadata}
+	require.Equal(t, []string{"a", "b"}, SuggestionsFromBlock(block))
+
+	block.Metadata[SuggestionsMetadataKey] = nil
+	require.Nil(t, SuggestionsFromBlock(block))
+}
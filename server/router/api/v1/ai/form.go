@@ -0,0 +1,79 @@
+package ai
+
+import (
+	"encoding/json"
+
+	"github.com/lithammer/shortuuid/v4"
+
+	"github.com/hrygo/divinesense/ai/agents/orchestrator"
+	"github.com/hrygo/divinesense/ai/agents/tools"
+	"github.com/hrygo/divinesense/store"
+)
+
+// EventTypeFormRequest is streamed when an agent asks a structured clarifying question.
+// EventData is the JSON-encoded tools.FormRequest.
+const EventTypeFormRequest = "form_request"
+
+// PendingForm is the form awaiting submission, stored in block metadata.
+type PendingForm struct {
+	Form *tools.FormRequest `json:"form"`
+	// Agent is the parrot that asked, so the answers are routed back to it.
+	// Empty for orchestrator clarifications, whose answers are routed again.
+	Agent string `json:"agent"`
+	// Message is the ambiguous request a clarification was asked about.
+	Message string `json:"message,omitempty"`
+	// Round is the number of clarifying questions asked so far, including this one.
+	Round int `json:"round,omitempty"`
+}
+
+// IsClarification reports whether the form was asked by the orchestrator
+// rather than by an expert agent.
+func (p *PendingForm) IsClarification() bool {
+	return p.Agent == "" && p.Message != ""
+}
+
+// PendingFormFromBlock returns the form awaiting submission on block, if any.
+func PendingFormFromBlock(block *store.AIBlock) (*PendingForm, bool) {
+	if block == nil || block.Metadata == nil {
+		return nil, false
+	}
+	raw, ok := block.Metadata[PendingFormMetadataKey]
+	if !ok || raw == nil {
+		return nil, false
+	}
+	// Metadata is decoded as generic JSON; round-trip into the typed form.
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, false
+	}
+	var pending PendingForm
+	if err := json.Unmarshal(data, &pending); err != nil || pending.Form == nil {
+		return nil, false
+	}
+	return &pending, true
+}
+
+// AgentTypeFromName maps a parrot name back to the AgentType used to recreate it.
+func AgentTypeFromName(name string) AgentType {
+	switch name {
+	case "schedule":
+		return AgentTypeSchedule
+	default:
+		return AgentTypeMemo
+	}
+}
+
+// clarificationForm renders an orchestrator clarification as a form. Options
+// become a single select field (buttons); without options the user types an answer.
+func clarificationForm(c *orchestrator.Clarification) *tools.FormRequest {
+	field := tools.FormField{Name: "answer", Label: "Answer", Type: tools.FormFieldText, Required: true}
+	if len(c.Options) > 0 {
+		field.Type = tools.FormFieldSelect
+		field.Options = c.Options
+	}
+	return &tools.FormRequest{
+		ID:     shortuuid.New(),
+		Title:  c.Question,
+		Fields: []tools.FormField{field},
+	}
+}
@@ -3,29 +3,42 @@ package ai
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"strings"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/hrygo/divinesense/ai"
 	agentpkg "github.com/hrygo/divinesense/ai/agents"
 	"github.com/hrygo/divinesense/ai/agents/geek"
 	"github.com/hrygo/divinesense/ai/agents/orchestrator"
+	"github.com/hrygo/divinesense/ai/agents/tools"
 	ctxpkg "github.com/hrygo/divinesense/ai/context"
+	"github.com/hrygo/divinesense/ai/core/llm"
 	"github.com/hrygo/divinesense/ai/memory"
 	"github.com/hrygo/divinesense/ai/routing"
 	aistats "github.com/hrygo/divinesense/ai/services/stats"
+	"github.com/hrygo/divinesense/plugin/canary"
+	"github.com/hrygo/divinesense/plugin/documentchat"
+	"github.com/hrygo/divinesense/plugin/evolutiontask"
+	"github.com/hrygo/divinesense/plugin/featureflag"
+	"github.com/hrygo/divinesense/plugin/geekworkspace"
+	"github.com/hrygo/divinesense/plugin/github"
+	"github.com/hrygo/divinesense/plugin/mcpserver"
+	"github.com/hrygo/divinesense/plugin/retrievalscope"
+	"github.com/hrygo/divinesense/plugin/shadow"
+	"github.com/hrygo/divinesense/plugin/toolpolicy"
 	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
 	"github.com/hrygo/divinesense/server/internal/errors"
 	"github.com/hrygo/divinesense/server/internal/observability"
+	"github.com/hrygo/divinesense/server/middleware"
 	"github.com/hrygo/divinesense/store"
 )
 
@@ -49,84 +62,34 @@ type ParrotHandler struct {
 	metadataMgr            *ctxpkg.MetadataManager          // Context engineering: metadata-based sticky routing
 	contextBuilder         *ctxpkg.Service                  // P0 fix: backend-driven context construction
 	memoryGenerator        memory.Generator                 // Phase 3: async episodic memory generation (extension point)
-	geekRunner             *agentpkg.CCRunner               // Singleton CCRunner for Geek mode
-	evoRunner              *agentpkg.CCRunner               // Singleton CCRunner for Evolution mode
+	geekRunner             agentpkg.AgentRunner             // Singleton runner for Geek mode
+	evoRunner              agentpkg.AgentRunner             // Singleton runner for Evolution mode
+	suggestionLLM          ai.LLMService                    // Cheap model for follow-up suggestions (nil disables them)
+	canaries               *canary.Canaries                 // Canary rollout of new experts (nil disables it)
+	featureFlags           *featureflag.Flags               // Gates risky features (nil leaves them on)
+	shadows                *shadow.Shadows                  // Shadow runs of prompt changes (nil disables them)
+	loadShedder            *middleware.LoadShedder          // Sheds requests under resource pressure (nil disables it)
+	toolPolicies           *toolpolicy.Policies             // Tools of the Geek Mode workspaces (nil permits all the tools)
+	mcpServers             *mcpserver.Servers               // MCP servers users register for Geek Mode (nil disables them)
+	github                 *github.GitHub                   // GitHub tools of Geek Mode (nil disables them)
+	workspaceQuota         *geekworkspace.Quota             // Disk quota of the Geek Mode workspaces (nil leaves them unbounded)
+	evolutionTasks         *evolutiontask.Tasks             // Backlog of approved Evolution tasks (nil disables /task)
+	retrievalScopes        *retrievalscope.Scopes           // Retrieval scope of conversations (nil leaves retrieval unrestricted)
+	documentChats          *documentchat.Documents          // Documents conversations are bound to (nil disables the document mode)
 }
 
-// NewParrotHandler creates a new parrot handler.
-func NewParrotHandler(factory *AgentFactory, llm ai.LLMService, persister *aistats.Persister, blockManager *BlockManager, titleGenerator *ai.TitleGenerator) *ParrotHandler {
-	// Read admin token for danger bypass mode (Geek and Evolution modes)
-	adminToken := os.Getenv("DIVINESENSE_GEEK_ADMIN_TOKEN")
-
-	// Create mode instances for BaseSystemPrompt
-	geekMode := geek.NewGeekMode("")
-	evoMode := geek.NewEvolutionMode(&geek.EvolutionModeConfig{
-		SourceDir: ".",
-		AdminOnly: os.Getenv("DIVINESENSE_EVOLUTION_ADMIN_ONLY") == "true",
-		Store:     factory.store,
-	})
-
-	// Create singletons for CC execution. Evolution and Geek use isolated runners.
-	// Each runner has its own BaseSystemPrompt and Namespace for physical isolation.
-	geekRunner, err := agentpkg.NewCCRunner(30*time.Minute, slog.Default(),
-		agentpkg.WithAdminToken(adminToken),
-		agentpkg.WithBaseSystemPrompt(geekMode.BaseSystemPrompt()),
-		agentpkg.WithNamespace("divinesense-geek"),
-	)
-	if err != nil {
-		slog.Warn("Failed to create geekRunner in init (CLI not found?)", "error", err)
+// loadClassOf returns the shedding priority of a request.
+func loadClassOf(req *ChatRequest) middleware.LoadClass {
+	switch {
+	case req.GeekMode || req.EvolutionMode:
+		return middleware.LoadClassDirect
+	case req.AgentType == AgentTypeAuto:
+		return middleware.LoadClassRouted
+	case req.AgentType == AgentTypeMemo:
+		return middleware.LoadClassEssential
+	default:
+		return middleware.LoadClassDirect
 	}
-	evoRunner, err := agentpkg.NewCCRunner(30*time.Minute, slog.Default(),
-		agentpkg.WithAdminToken(adminToken),
-		agentpkg.WithBaseSystemPrompt(evoMode.BaseSystemPrompt()),
-		agentpkg.WithNamespace("divinesense-evolution"),
-	)
-	if err != nil {
-		slog.Warn("Failed to create evoRunner in init (CLI not found?)", "error", err)
-	}
-
-	return &ParrotHandler{
-		factory:        factory,
-		llm:            llm,
-		persister:      persister,
-		blockManager:   blockManager, // Phase 5
-		titleGenerator: titleGenerator,
-		geekRunner:     geekRunner,
-		evoRunner:      evoRunner,
-	}
-}
-
-// SetChatRouter configures the intelligent chat router for auto-routing.
-func (h *ParrotHandler) SetChatRouter(router *agentpkg.ChatRouter) {
-	h.chatRouter = router
-}
-
-// SetChatRouterWithMetadata configures the chat router with metadata-based sticky routing.
-// This enables persistent routing state across sessions using database-stored metadata.
-// P0 fix: enables context-engineering.md Phase 2 sticky routing.
-func (h *ParrotHandler) SetChatRouterWithMetadata(router *agentpkg.ChatRouterWithMetadata) {
-	h.chatRouterWithMetadata = router
-	// Also set base router for fallback
-	if router != nil {
-		h.chatRouter = router.ChatRouter
-	}
-}
-
-// SetOrchestrator configures the orchestrator for complex/multi-intent requests.
-func (h *ParrotHandler) SetOrchestrator(orch *orchestrator.Orchestrator) {
-	h.orchestrator = orch
-}
-
-// SetCapabilityMap configures the capability map for handoff expert lookup.
-// Orchestrator uses this to find alternative experts when an expert reports inability.
-func (h *ParrotHandler) SetCapabilityMap(cm *orchestrator.CapabilityMap) {
-	h.capabilityMap = cm
-}
-
-// SetMetadataManager configures the metadata manager for context engineering.
-// This enables metadata-based sticky routing and state persistence.
-func (h *ParrotHandler) SetMetadataManager(mgr *ctxpkg.MetadataManager) {
-	h.metadataMgr = mgr
 }
 
 // Close gracefully shuts down all managed singleton runners and active sessions.
@@ -143,25 +106,15 @@ func (h *ParrotHandler) Close() error {
 	return nil
 }
 
-// SetContextBuilder configures the context builder for backend-driven context construction.
-// P0 fix: enables context-engineering.md Phase 1 backend-driven context.
-func (h *ParrotHandler) SetContextBuilder(builder *ctxpkg.Service) {
-	h.contextBuilder = builder
-}
-
-// SetMemoryGenerator configures the memory generator for async episodic memory creation.
-// Phase 3: enables context-engineering.md Phase 3 memory generation.
-// Default is NoOpGenerator (no-op). Use simple.Generator for dev/test, or integrate
-// with professional memory services (Mem0, Letta) for production.
-func (h *ParrotHandler) SetMemoryGenerator(gen memory.Generator) {
-	h.memoryGenerator = gen
-}
-
 // maybeGenerateConversationTitle auto-generates a conversation title for the first block.
 // Only generates if title_source is "default" (never been auto-generated or user-edited).
 // Runs asynchronously in a background goroutine to avoid blocking the chat flow.
 // Optimization: Called immediately after block creation (not after block completion) for parallel execution.
 func (h *ParrotHandler) maybeGenerateConversationTitle(ctx context.Context, conversationID int32, userMessage string) {
+	// The title of a locked conversation would disclose its first message
+	if locked, _ := h.conversationLocked(conversationID); locked {
+		return
+	}
 	// Run asynchronously in background - don't block the chat flow
 	go h.generateTitleAsync(conversationID, userMessage)
 }
@@ -239,6 +192,15 @@ func (h *ParrotHandler) generateTitleAsync(conversationID int32, userMessage str
 	)
 }
 
+// conversationLocked reports whether a conversation is locked, and whether
+// it is unlocked.
+func (h *ParrotHandler) conversationLocked(conversationID int32) (locked, unlocked bool) {
+	if h.factory == nil || h.factory.store == nil || conversationID <= 0 {
+		return false, false
+	}
+	return h.factory.store.ConversationLockState(conversationID)
+}
+
 // storePtr returns a pointer to the given value.
 func storePtr[T any](v T) *T {
 	return &v
@@ -254,17 +216,36 @@ func (h *ParrotHandler) Handle(ctx context.Context, req *ChatRequest, stream Cha
 		"evolution_mode_raw", fmt.Sprintf("%v", req.EvolutionMode),
 	)
 
+	// Under resource pressure, AUTO requests are shed first and memo queries last
+	if class := loadClassOf(req); !h.loadShedder.Admit(class) {
+		slog.Warn("AI chat request shed under load",
+			"user_id", req.UserID,
+			"agent_type", req.AgentType,
+			"class", class.String(),
+			"level", h.loadShedder.Level().String(),
+		)
+		return errors.Overloaded()
+	}
+
+	// Answers stream live, but are stored sealed with the key of the unlocked conversation
+	if locked, unlocked := h.conversationLocked(req.ConversationID); locked && !unlocked {
+		return errors.ConversationLocked()
+	}
+
 	// PRIORITY CHECK: EvolutionMode has highest priority (admin-only, self-evolution)
 	// 优先检查：进化模式具有最高优先级（仅管理员，自我进化）
 	if req.EvolutionMode {
+		if !h.featureOn(ctx, FlagEvolutionMode, req.UserID) {
+			return errors.ServiceUnavailable("evolution mode is disabled")
+		}
 		slog.Info("Evolution mode detected, routing to EvolutionParrot")
-		return h.handleEvolutionMode(ctx, req, stream)
+		return observeChat("evolution", time.Now(), h.handleEvolutionMode(ctx, req, stream))
 	}
 
 	// PRIORITY CHECK: GeekMode bypasses ALL normal routing
 	// 优先检查：极客模式绕过所有常规路由
 	if req.GeekMode {
-		return h.handleGeekMode(ctx, req, stream)
+		return observeChat("geek", time.Now(), h.handleGeekMode(ctx, req, stream))
 	}
 
 	// PROGRESS EVENT: Send received event immediately to acknowledge message receipt
@@ -276,13 +257,18 @@ func (h *ParrotHandler) Handle(ctx context.Context, req *ChatRequest, stream Cha
 		// Non-critical error, continue processing
 	}
 
+	// Memo retrieval of the request follows the scope of its conversation
+	ctx = h.withRetrievalScope(ctx, req, stream)
+	ctx = h.withDocument(ctx, req, stream)
+
 	if h.llm == nil {
-		return status.Error(codes.Unavailable, "LLM service is not available")
+		return errors.LLMUnavailable(errors.UserMessage(errors.ErrCodeLLMUnavailable))
 	}
 
 	// Auto-route if AgentType is AUTO
 	agentType := req.AgentType
 	var needsOrchestration bool
+	var routedIntent string // classified intent of directly routed requests
 
 	if agentType == AgentTypeAuto && h.chatRouter != nil {
 		// Add user ID to context for history matching.
@@ -303,10 +289,11 @@ func (h *ParrotHandler) Handle(ctx context.Context, req *ChatRequest, stream Cha
 		// P0 fix: enables context-engineering.md Phase 2 sticky routing
 		var routeResult *agentpkg.ChatRouteResult
 		var err error
+		routeCtx, routeSpan := observability.StartSpan(ctx, "chat.route")
 		if h.chatRouterWithMetadata != nil && req.ConversationID > 0 {
 			// Use sticky routing with metadata (blockID=0 means persist later after block creation)
 			routeResult, err = h.chatRouterWithMetadata.RouteWithContextWithMetadata(
-				ctx, req.Message, nil, req.ConversationID, 0)
+				routeCtx, req.Message, nil, req.ConversationID, 0)
 			if err == nil && routeResult.Method == "metadata_sticky" {
 				slog.Info("route reused from metadata sticky",
 					"conversation_id", req.ConversationID,
@@ -315,18 +302,28 @@ func (h *ParrotHandler) Handle(ctx context.Context, req *ChatRequest, stream Cha
 			}
 		} else {
 			// Fallback to standard routing
-			routeResult, err = h.chatRouter.Route(ctx, req.Message)
+			routeResult, err = h.chatRouter.Route(routeCtx, req.Message)
 		}
 		duration := time.Since(startTime)
+		endRouteSpan(routeSpan, routeResult, err)
 
 		if err != nil {
 			// Router error → Orchestrator
 			needsOrchestration = true
+			observability.ObserveRouting("error", "")
 			slog.Warn("chat router failed, using orchestrator",
 				"error", err,
 				"message", req.Message[:min(len(req.Message), 30)])
 		} else {
+			observability.ObserveRouting(routeResult.Method, string(routeResult.Route))
 			needsOrchestration = routeResult.NeedsOrchestration
+			if !needsOrchestration {
+				routedIntent = routeResult.Intent
+			}
+			if needsOrchestration {
+				// Lets the orchestrator ask a clarifying question for low-confidence routes
+				ctx = orchestrator.WithClarificationState(ctx, routeResult.Confidence, req.ClarificationRound)
+			}
 
 			// Map ChatRouteType to AgentType
 			switch routeResult.Route {
@@ -355,6 +352,13 @@ func (h *ParrotHandler) Handle(ctx context.Context, req *ChatRequest, stream Cha
 				"confidence", routeResult.Confidence,
 				"needs_orchestration", needsOrchestration)
 
+			// Manual selection: below the user's confidence threshold, suggest
+			// candidate experts instead of routing automatically
+			if agentType != "" && req.ContinueBlockID == 0 && h.canSuggestAgents(req) &&
+				routeResult.Confidence < h.routingThreshold(ctx, req.UserID) {
+				return h.suggestAgents(ctx, req, stream, suggestAgents(req.Message, agentType, routeResult.Confidence))
+			}
+
 			// Store route result for metadata persistence
 			if !needsOrchestration && routeResult.Route != "" {
 				req.RouteResult = &RouteResultMeta{
@@ -370,7 +374,7 @@ func (h *ParrotHandler) Handle(ctx context.Context, req *ChatRequest, stream Cha
 					h.metadataMgr.UpdateCacheOnly(
 						req.ConversationID,
 						string(routeResult.Route),
-						agentpkg.ExtractIntent(routeResult.Route),
+						routeResult.IntentName(),
 						float32(routeResult.Confidence),
 					)
 				}
@@ -382,11 +386,11 @@ func (h *ParrotHandler) Handle(ctx context.Context, req *ChatRequest, stream Cha
 	}
 
 	// Core branch: direct to Expert vs Orchestrator
-	if needsOrchestration && h.orchestrator != nil {
+	if needsOrchestration && h.orchestrator != nil && h.featureOn(ctx, FlagOrchestrator, req.UserID) {
 		// Use Orchestrator for complex/multi-intent requests
-		return h.executeWithOrchestrator(ctx, req, stream)
+		return observeChat("orchestrator", time.Now(), h.executeWithOrchestrator(ctx, req, stream))
 	} else if needsOrchestration {
-		// No orchestrator available, fallback to Memo agent
+		// No orchestrator available or enabled, fallback to Memo agent
 		agentType = AgentTypeMemo
 	}
 
@@ -399,14 +403,19 @@ func (h *ParrotHandler) Handle(ctx context.Context, req *ChatRequest, stream Cha
 	)
 
 	// Create agent using factory
-	agent, err := h.factory.Create(ctx, &CreateConfig{
+	createCfg := &CreateConfig{
 		Type:     agentType,
 		UserID:   req.UserID,
 		Timezone: req.Timezone,
-	})
+	}
+	agent, err := h.factory.Create(ctx, createCfg)
 	if err != nil {
 		logger.Error("Failed to create agent", err)
-		return status.Error(codes.Internal, fmt.Sprintf("failed to create agent: %v", err))
+		return errors.Classify(err, errors.ErrCodeAgentExecutionFailed)
+	}
+	if routedIntent != "" {
+		agent = h.canaryAgent(ctx, agent, createCfg, req, routedIntent)
+		agent = h.shadowAgent(agent, createCfg, req, routedIntent)
 	}
 
 	logger.Debug("Agent created",
@@ -414,9 +423,9 @@ func (h *ParrotHandler) Handle(ctx context.Context, req *ChatRequest, stream Cha
 	)
 
 	// Execute agent with streaming
-	if err := h.executeAgent(ctx, agent, req, stream, logger); err != nil {
+	if err := observeChat(agentType.String(), logger.StartTime, h.executeAgent(ctx, agent, req, stream, logger)); err != nil {
 		logger.Error("AI chat failed", err)
-		return status.Error(codes.Internal, fmt.Sprintf("agent execution failed: %v", err))
+		return errors.Classify(err, errors.ErrCodeAgentExecutionFailed)
 	}
 
 	logger.Info("ai.chat.completed",
@@ -426,6 +435,24 @@ func (h *ParrotHandler) Handle(ctx context.Context, req *ChatRequest, stream Cha
 	return nil
 }
 
+// geekSessionID returns the CLI session ID of a Geek Mode conversation.
+func geekSessionID(userID, conversationID int32) string {
+	return modeSessionID("geek", userID, conversationID)
+}
+
+// evolutionSessionID returns the CLI session ID of an Evolution Mode conversation.
+func evolutionSessionID(userID, conversationID int32) string {
+	return modeSessionID("evolution", userID, conversationID)
+}
+
+// modeSessionID derives a stable UUID v5 session ID from the mode, the user
+// and the conversation.
+// 统一 Namespace 规则：使用模式名称作为前缀并结合 UserID，确保跨用户、跨模式完全隔离
+func modeSessionID(mode string, userID, conversationID int32) string {
+	namespace := uuid.NewMD5(uuid.NameSpaceOID, []byte(fmt.Sprintf("%s_%d", mode, userID)))
+	return uuid.NewSHA1(namespace, []byte(fmt.Sprintf("conversation_%d", conversationID))).String()
+}
+
 // handleGeekMode creates and executes GeekParrot directly.
 // handleGeekMode 创建并直接执行 GeekParrot。
 // GeekMode bypasses all LLM processing and routing, providing direct
@@ -454,14 +481,11 @@ func (h *ParrotHandler) handleGeekMode(
 	}
 
 	// Generate a stable session ID based on Conversation ID using UUID v5
-	// 统一 Namespace 规则：使用模式名称(geek)作为前缀并结合 UserID，确保跨用户、跨模式完全隔离
-	namespaceBase := fmt.Sprintf("geek_%d", req.UserID)
-	namespace := uuid.NewMD5(uuid.NameSpaceOID, []byte(namespaceBase))
-	sessionID := uuid.NewSHA1(namespace, []byte(fmt.Sprintf("conversation_%d", req.ConversationID))).String()
+	sessionID := geekSessionID(req.UserID, req.ConversationID)
 
 	if h.geekRunner == nil {
 		logger.Error("GeekRunner global singleton is null, cannot perform Hot-Multiplexing", nil)
-		return status.Error(codes.Unavailable, "GeekMode CLI runner not initialized")
+		return errors.Wrap(fmt.Errorf("GeekMode CLI runner not initialized"), errors.ErrCodeCLINotFound, errors.UserMessage(errors.ErrCodeCLINotFound))
 	}
 
 	// Create GeekParrot directly (no LLM dependency)
@@ -474,12 +498,53 @@ func (h *ParrotHandler) handleGeekMode(
 	)
 	if err != nil {
 		logger.Error("Failed to create GeekParrot", err)
-		return status.Error(codes.Internal, fmt.Sprintf("failed to create GeekParrot: %v", err))
+		return errors.Classify(err, errors.ErrCodeAgentExecutionFailed)
 	}
 
 	// Pass detailed device context to GeekParrot
 	// 将详细的设备上下文传递给极客鹦鹉
 	geekParrot.SetDeviceContext(req.DeviceContext)
+	// Let the parrot reuse the user's snippets before writing new code
+	if h.factory.snippets != nil {
+		geekParrot.SetSnippets(h.factory.snippets)
+	}
+	// Restrict the CLI to the tools the workspace, or the user's role, permits
+	var tools *agentpkg.ToolPolicy
+	if h.toolPolicies != nil {
+		role, err := h.userRole(ctx, req.UserID)
+		if err != nil {
+			logger.Error("Failed to get the user role", err)
+			return errors.StorageFailed(err)
+		}
+		if tools, err = h.toolPolicies.Resolve(ctx, req.UserID, role); err != nil {
+			logger.Error("Failed to resolve the workspace tool policy", err)
+			return errors.StorageFailed(err)
+		}
+		geekParrot.SetToolPolicy(tools)
+	}
+	// Give the CLI the MCP servers the user registered, within that policy
+	var servers []agentpkg.MCPServer
+	if h.mcpServers != nil {
+		if servers, err = h.mcpServers.Resolve(ctx, req.UserID, tools); err != nil {
+			logger.Error("Failed to resolve the MCP servers", err)
+			return errors.StorageFailed(err)
+		}
+	}
+	// and the GitHub tools of the user's allow-listed repositories
+	githubServer, ok, err := h.github.MCPServer(ctx, req.UserID)
+	if err != nil {
+		logger.Error("Failed to resolve the GitHub tools", err)
+		return errors.StorageFailed(err)
+	}
+	if ok && tools.Permits("mcp__"+github.ServerName+"__"+github.ListIssuesToolName) &&
+		!slices.ContainsFunc(servers, func(s agentpkg.MCPServer) bool { return s.Name == githubServer.Name }) {
+		servers = append(servers, githubServer)
+	}
+	geekParrot.SetMCPServers(servers)
+	// Reject the writes of the CLI while the workspace is over its quota
+	if h.workspaceQuota != nil {
+		geekParrot.SetWriteGuard(func() error { return h.workspaceQuota.Check(req.UserID) })
+	}
 
 	logger.Debug("GeekParrot created",
 		slog.String("agent_name", geekParrot.Name()),
@@ -489,9 +554,12 @@ func (h *ParrotHandler) handleGeekMode(
 
 	// Execute with streaming (same pattern as other agents)
 	// 执行并流式输出（与其他 Agent 相同的模式）
-	if err := h.executeAgent(ctx, geekParrot, req, stream, logger); err != nil {
+	runStart := time.Now()
+	err = h.executeAgent(ctx, geekParrot, req, stream, logger)
+	observability.ObserveRunnerSession("geek", err, time.Since(runStart))
+	if err != nil {
 		logger.Error("GeekMode execution failed", err)
-		return status.Error(codes.Internal, fmt.Sprintf("GeekMode execution failed: %v", err))
+		return errors.Classify(err, errors.ErrCodeAgentExecutionFailed)
 	}
 
 	logger.Info("ai.chat.completed",
@@ -502,6 +570,18 @@ func (h *ParrotHandler) handleGeekMode(
 	return nil
 }
 
+// userRole returns the role of a user, or "" if unknown.
+func (h *ParrotHandler) userRole(ctx context.Context, userID int32) (string, error) {
+	if h.factory.store == nil {
+		return "", nil
+	}
+	user, err := h.factory.store.GetUser(ctx, &store.FindUser{ID: &userID})
+	if err != nil || user == nil {
+		return "", err
+	}
+	return user.Role.String(), nil
+}
+
 // handleEvolutionMode creates and executes EvolutionParrot for self-evolution.
 // handleEvolutionMode 创建并执行 EvolutionParrot 进行自我进化。
 // Evolution Mode allows DivineSense to modify its own source code under
@@ -533,29 +613,30 @@ func (h *ParrotHandler) handleEvolutionMode(
 	sourceDir, err := h.getSourceDir()
 	if err != nil {
 		logger.Error("Failed to get source directory", err)
-		return status.Error(codes.Internal, "evolution mode requires source directory configuration")
+		return errors.ServiceUnavailable("evolution mode requires source directory configuration")
 	}
 
 	// Generate a stable session ID based on Conversation ID using UUID v5
-	// 统一 Namespace 规则：使用模式名称(evolution)作为前缀并结合 UserID，确保跨用户、跨模式完全隔离
-	namespaceBase := fmt.Sprintf("evolution_%d", req.UserID)
-	namespace := uuid.NewMD5(uuid.NameSpaceOID, []byte(namespaceBase))
-	sessionID := uuid.NewSHA1(namespace, []byte(fmt.Sprintf("conversation_%d", req.ConversationID))).String()
+	sessionID := evolutionSessionID(req.UserID, req.ConversationID)
 
 	if h.evoRunner == nil {
 		logger.Error("EvoRunner global singleton is null, cannot perform Hot-Multiplexing", nil)
-		return status.Error(codes.Unavailable, "EvolutionMode CLI runner not initialized")
+		return errors.Wrap(fmt.Errorf("EvolutionMode CLI runner not initialized"), errors.ErrCodeCLINotFound, errors.UserMessage(errors.ErrCodeCLINotFound))
 	}
 
 	// Create EvolutionParrot (pass store for admin verification, inject global evoRunner)
 	evoParrot, err := geek.NewEvolutionParrot(h.evoRunner, sourceDir, req.UserID, sessionID, h.factory.store)
 	if err != nil {
 		logger.Error("Failed to create EvolutionParrot", err)
-		return status.Error(codes.Internal, fmt.Sprintf("failed to create EvolutionParrot: %v", err))
+		return errors.Classify(err, errors.ErrCodeAgentExecutionFailed)
 	}
 
 	// Pass device context
 	evoParrot.SetDeviceContext(req.DeviceContext)
+	// Let the parrot run the approved tasks of the backlog
+	if h.evolutionTasks != nil {
+		evoParrot.SetTaskQueue(h.evolutionTasks)
+	}
 
 	logger.Debug("EvolutionParrot created",
 		slog.String("agent_name", evoParrot.Name()),
@@ -564,9 +645,15 @@ func (h *ParrotHandler) handleEvolutionMode(
 	)
 
 	// Execute with streaming
-	if err := h.executeAgent(ctx, evoParrot, req, stream, logger); err != nil {
+	runStart := time.Now()
+	err = h.executeAgent(ctx, evoParrot, req, stream, logger)
+	observability.ObserveRunnerSession("evolution", err, time.Since(runStart))
+	if task := evoParrot.Task(); task != nil {
+		h.linkEvolutionTaskBlock(ctx, task.ID, req.ConversationID, logger)
+	}
+	if err != nil {
 		logger.Error("EvolutionMode execution failed", err)
-		return status.Error(codes.Internal, fmt.Sprintf("EvolutionMode execution failed: %v", err))
+		return errors.Classify(err, errors.ErrCodeAgentExecutionFailed)
 	}
 
 	logger.Info("ai.chat.completed",
@@ -577,6 +664,25 @@ func (h *ParrotHandler) handleEvolutionMode(
 	return nil
 }
 
+// linkEvolutionTaskBlock links the block of a task run, the latest block of
+// its conversation, to the task.
+func (h *ParrotHandler) linkEvolutionTaskBlock(ctx context.Context, taskID int64, conversationID int32, logger *observability.RequestContext) {
+	ctx = context.WithoutCancel(ctx)
+	block, err := h.blockManager.GetLatestBlock(ctx, conversationID)
+	if err != nil || block == nil {
+		logger.Warn("Failed to find the block of the evolution task",
+			slog.Int64("evolution_task_id", taskID),
+			slog.Any("error", err))
+		return
+	}
+	if err := h.evolutionTasks.LinkBlock(ctx, taskID, conversationID, block.ID); err != nil {
+		logger.Warn("Failed to link the block to the evolution task",
+			slog.Int64("evolution_task_id", taskID),
+			slog.Int64("block_id", block.ID),
+			slog.String("error", err.Error()))
+	}
+}
+
 // executeWithOrchestrator uses Orchestrator for complex/multi-intent requests.
 // executeWithOrchestrator 使用 Orchestrator 处理复杂/多意图请求。
 func (h *ParrotHandler) executeWithOrchestrator(
@@ -607,10 +713,10 @@ func (h *ParrotHandler) executeWithOrchestrator(
 			UserID:        req.UserID,
 			HistoryLength: historyLength,
 		}
-		builtHistory, err := h.contextBuilder.BuildHistory(ctx, ctxReq)
+		builtHistory, err := h.buildHistory(ctx, ctxReq)
 		if err != nil {
 			logger.Error("Failed to build history for orchestrator", err)
-			return status.Error(codes.Internal, "failed to build context")
+			return errors.ContextBuildFailed(err)
 		}
 		if builtHistory == nil {
 			builtHistory = []string{}
@@ -629,7 +735,15 @@ func (h *ParrotHandler) executeWithOrchestrator(
 	// ========== Phase 2: Create Block for this chat round ==========
 	var currentBlock *store.AIBlock
 	var blockID int64
-	if h.blockManager != nil && req.ConversationID > 0 && !req.IsTempConversation {
+	if h.blockManager != nil && req.ContinueBlockID > 0 {
+		// Answered clarification: continue streaming into the block that asked
+		var resumeErr error
+		currentBlock, resumeErr = h.blockManager.ResumeBlock(ctx, req.ContinueBlockID)
+		if resumeErr != nil {
+			return resumeErr
+		}
+		blockID = currentBlock.ID
+	} else if h.blockManager != nil && req.ConversationID > 0 && !req.IsTempConversation {
 		var createErr error
 		currentBlock, createErr = h.blockManager.CreateBlockForChat(
 			ctx,
@@ -647,8 +761,13 @@ func (h *ParrotHandler) executeWithOrchestrator(
 	}
 
 	// Variable to collect AI response content
+	// A resumed block keeps the clarifying question asked before
 	var assistantContent strings.Builder
 	var assistantContentMu sync.Mutex
+	if req.ContinueBlockID > 0 && currentBlock != nil && currentBlock.AssistantContent != "" {
+		assistantContent.WriteString(currentBlock.AssistantContent)
+		assistantContent.WriteString("\n\n")
+	}
 
 	// ========== Phase 2.5: Send block_created event immediately ==========
 	// CRITICAL: Send blockId to frontend BEFORE orchestrator starts processing
@@ -682,9 +801,22 @@ func (h *ParrotHandler) executeWithOrchestrator(
 	}
 	ctx = ctxpkg.WithOrchestratorContext(ctx, orchCtx)
 
+	// Serialize stream sends between orchestrator events and progress heartbeats
+	var streamMu sync.Mutex
+	sendLocked := func(resp *v1pb.ChatResponse) error {
+		streamMu.Lock()
+		defer streamMu.Unlock()
+		return stream.Send(resp)
+	}
+	narrator := newProgressNarrator()
+
 	// Create callback adapter for streaming events
 	// Phase 4 fix: Include BlockId in all orchestrator events for frontend optimistic block creation
 	callback := func(eventType string, eventData string) {
+		if eventType == orchestrator.EventTypeTaskEnd {
+			observeTaskEnd(eventData)
+		}
+
 		// Parse event data for tool_use and tool_result events
 		// Format from expert_registry: {"data": "...", "meta": {...}}
 		var finalData string
@@ -755,7 +887,12 @@ func (h *ParrotHandler) executeWithOrchestrator(
 			assistantContentMu.Unlock()
 		}
 
-		if err := stream.Send(&v1pb.ChatResponse{
+		narrator.Observe(eventType, finalData)
+		if eventType == "tool_use" && eventMeta != nil {
+			narrator.ObserveTool(eventMeta.ToolName)
+		}
+
+		if err := sendLocked(&v1pb.ChatResponse{
 			BlockId:   blockID,
 			EventType: eventType,
 			EventData: finalData,
@@ -806,13 +943,77 @@ func (h *ParrotHandler) executeWithOrchestrator(
 				}
 			}
 		}
+
+		// Chart-ready statistics are sent as their own event after the tool_result
+		if eventType == "tool_result" {
+			if statsData, ok := statsDataEvent(finalData); ok {
+				if err := sendLocked(&v1pb.ChatResponse{
+					BlockId:   blockID,
+					EventType: EventTypeStatsData,
+					EventData: statsData,
+				}); err != nil {
+					slog.Warn("failed to send orchestrator event", "error", err, "event_type", EventTypeStatsData)
+				}
+				if currentBlock != nil && h.blockManager != nil {
+					if err := h.blockManager.AppendEvent(ctx, currentBlock.ID, EventTypeStatsData, statsData, nil); err != nil {
+						logger.Warn("orchestrator: failed to persist event",
+							slog.String("event_type", EventTypeStatsData),
+							slog.Int64("block_id", currentBlock.ID),
+							slog.String("error", err.Error()))
+					}
+				}
+			}
+		}
 	}
 
+	// Narrate progress while the orchestrator is busy (planning, long expert runs)
+	stopHeartbeat := startProgressHeartbeat(narrator, func() int64 { return blockID }, sendLocked)
+
+	// Stream token usage of the LLM calls for the live cost ticker
+	usage := newUsageTracker()
+	ctx = llm.WithUsageObserver(ctx, usage.Observe)
+	stopUsage := startUsageReporter(usage, func() int64 { return blockID }, sendLocked)
+
+	// Stop the round once it crosses its budget
+	runCtx, budget, stopBudget := withBudgetGuard(ctx, req.Budget, usage)
+
 	// Execute Orchestrator
-	result, err := h.orchestrator.Process(ctx, req.Message, callback)
+	result, err := h.orchestrator.Process(runCtx, req.Message, callback)
+	stopBudget()
+	stopHeartbeat()
+	stopUsage()
+	if budget.Exceeded() != nil {
+		assistantContentMu.Lock()
+		partialContent := assistantContent.String()
+		assistantContentMu.Unlock()
+		summary := h.completeOverBudget(ctx, req, currentBlock, budget, partialContent, time.Since(startTime).Milliseconds(), sendLocked, logger)
+		return sendLocked(&v1pb.ChatResponse{BlockId: blockID, Done: true, BlockSummary: summary})
+	}
+	if chatStopped(ctx) {
+		assistantContentMu.Lock()
+		partialContent := assistantContent.String()
+		assistantContentMu.Unlock()
+		return sendLocked(h.completeStopped(ctx, req, currentBlock, partialContent, time.Since(startTime).Milliseconds(), logger))
+	}
 	if err != nil {
 		logger.Error("Orchestrator execution failed", err)
-		return status.Error(codes.Internal, fmt.Sprintf("orchestrator failed: %v", err))
+		return errors.Classify(err, errors.ErrCodeAgentExecutionFailed)
+	}
+
+	// Ambiguous request: ask the clarifying question and finish the round.
+	// The answer arrives through SubmitForm and is routed again.
+	if result.Clarification != nil {
+		form := clarificationForm(result.Clarification)
+		assistantContentMu.Lock()
+		assistantContent.WriteString(form.Title)
+		assistantContentMu.Unlock()
+		if err := h.emitFormRequest(ctx, stream, currentBlock, &PendingForm{
+			Form:    form,
+			Message: req.Message,
+			Round:   req.ClarificationRound + 1,
+		}, logger); err != nil {
+			logger.Warn("Failed to send clarification", slog.String("error", err.Error()))
+		}
 	}
 
 	// Send completion event
@@ -842,11 +1043,15 @@ func (h *ParrotHandler) executeWithOrchestrator(
 		}
 	}
 
+	observability.AddTokens("orchestrator", int64(result.TokenUsage.InputTokens), int64(result.TokenUsage.OutputTokens),
+		int64(result.TokenUsage.CacheReadTokens), int64(result.TokenUsage.CacheWriteTokens))
+
 	// ========== Phase 5: Persist block to database BEFORE sending done ==========
 	// CRITICAL: Complete block BEFORE sending done marker to prevent race condition
 	// This ensures that when frontend receives done=true and refetches blocks,
 	// the assistantContent is already persisted in the database.
 	// This fixes the "Initializing..." stuck issue.
+	var suggestionsCh <-chan []string
 	if currentBlock != nil && h.blockManager != nil {
 		assistantContentMu.Lock()
 		finalContent := assistantContent.String()
@@ -863,6 +1068,7 @@ func (h *ParrotHandler) executeWithOrchestrator(
 			CacheWriteTokens: int(result.TokenUsage.CacheWriteTokens),
 			CacheReadTokens:  int(result.TokenUsage.CacheReadTokens),
 			ToolCallCount:    toolCallCount,
+			ModelUsed:        usage.Model(),
 		}
 
 		if completeErr := h.blockManager.CompleteBlock(ctx, currentBlock.ID, finalContent, blockSessionStats); completeErr != nil {
@@ -874,6 +1080,11 @@ func (h *ParrotHandler) executeWithOrchestrator(
 				slog.Int("content_length", len(finalContent)),
 			)
 			// Title generation moved to block creation time (Phase 2) for parallel execution
+
+			// Follow-up questions are generated while the done marker is sent
+			if result.Clarification == nil && status == "completed" {
+				suggestionsCh = h.startFollowUpSuggestions(ctx, req.UserID, currentBlock, req.Message, finalContent)
+			}
 		}
 	}
 
@@ -892,6 +1103,7 @@ func (h *ParrotHandler) executeWithOrchestrator(
 			TotalCacheReadTokens:  result.TokenUsage.CacheReadTokens,
 		},
 	})
+	sendFollowUpSuggestions(ctx, stream, blockID, suggestionsCh)
 
 	logger.Info("ai.chat.completed",
 		slog.String("mode", "orchestrator"),
@@ -930,15 +1142,9 @@ func (h *ParrotHandler) getSourceDir() (string, error) {
 // getWorkDirForUser 返回特定用户的 Claude Code CLI 工作目录。
 // Each user gets an isolated working directory for security and session management.
 // 每个用户都有独立的工作目录，用于安全和会话管理。
+// The workspace file API browses the same directory.
 func (h *ParrotHandler) getWorkDirForUser(userID int32) string {
-	// Use persistent directory in user's home to avoid data loss on restart
-	// 使用用户主目录下的持久化目录，避免重启时数据丢失
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		homeDir = "/tmp" // Fallback if home dir cannot be determined
-	}
-
-	return fmt.Sprintf("%s/.divinesense/claude/user_%d", homeDir, userID)
+	return geekworkspace.Dir(userID)
 }
 
 // executeAgent executes the agent and streams responses.
@@ -962,7 +1168,14 @@ func (h *ParrotHandler) executeAgent(
 
 	// Only create block for non-temporary conversations with valid ID
 	var currentBlock *store.AIBlock
-	if h.blockManager != nil && req.ConversationID > 0 && !req.IsTempConversation {
+	if h.blockManager != nil && req.ContinueBlockID > 0 {
+		// Submitted form: continue streaming into the block that asked for it
+		var resumeErr error
+		currentBlock, resumeErr = h.blockManager.ResumeBlock(ctx, req.ContinueBlockID)
+		if resumeErr != nil {
+			return resumeErr
+		}
+	} else if h.blockManager != nil && req.ConversationID > 0 && !req.IsTempConversation {
 		var createErr error
 		currentBlock, createErr = h.blockManager.CreateBlockForChat(
 			ctx,
@@ -1005,13 +1218,17 @@ func (h *ParrotHandler) executeAgent(
 	var totalCostUsd float64
 	var costMu sync.Mutex
 
-	// Track last event time for heartbeats
-	lastEventTime := atomic.Int64{}
-	lastEventTime.Store(time.Now().UnixNano())
+	// Track agent state for progress heartbeats
+	narrator := newProgressNarrator()
 
 	// Track assistant content for block completion
+	// A resumed block keeps the content streamed before the form was shown
 	var assistantContent strings.Builder
 	var assistantContentMu sync.Mutex
+	if req.ContinueBlockID > 0 && currentBlock != nil && currentBlock.AssistantContent != "" {
+		assistantContent.WriteString(currentBlock.AssistantContent)
+		assistantContent.WriteString("\n\n")
+	}
 
 	// Track inability report for handoff mechanism
 	// Expert reports what it CANNOT do. Orchestrator finds the appropriate expert via CapabilityMap.
@@ -1022,10 +1239,16 @@ func (h *ParrotHandler) executeAgent(
 	}
 	var inabilityMu sync.Mutex
 
+	// Pending request_form result, emitted as a form_request event (guarded by streamMu)
+	var formRequest *tools.FormRequest
+	var formEmitted bool
+	// Pending usage_stats dataset, emitted as a stats_data event
+	var statsData string
+
 	// Create stream adapter
 	streamAdapter := agentpkg.NewParrotStreamAdapter(func(eventType string, eventData any) error {
-		// Update last event time
-		lastEventTime.Store(time.Now().UnixNano())
+		// Update progress state (also resets the heartbeat idle timer)
+		narrator.Observe(eventType, "")
 
 		// Atomically increment event count
 		countMu.Lock()
@@ -1065,6 +1288,7 @@ func (h *ParrotHandler) executeAgent(
 					toolMu.Lock()
 					toolsUsed = append(toolsUsed, eventWithMeta.Meta.ToolName)
 					toolMu.Unlock()
+					narrator.ObserveTool(eventWithMeta.Meta.ToolName)
 				}
 			}
 		} else if eventType == agentpkg.EventTypeSessionStats {
@@ -1254,6 +1478,24 @@ func (h *ParrotHandler) executeAgent(
 					slog.String("reason", reason))
 			}
 
+			// Detect request_form results: the form is sent as its own event after the tool_result
+			if eventType == "tool_result" {
+				if form, ok := tools.ParseFormRequest(dataStr); ok {
+					formRequest = form
+				}
+				// Likewise chart-ready statistics of usage_stats results
+				if data, ok := statsDataEvent(dataStr); ok {
+					statsData = data
+					if currentBlock != nil && h.blockManager != nil {
+						if err := h.blockManager.AppendEvent(ctx, currentBlock.ID, EventTypeStatsData, data, nil); err != nil {
+							logger.Warn("Failed to enqueue stats data for persistence",
+								slog.Int64("block_id", currentBlock.ID),
+								slog.String("error", err.Error()))
+						}
+					}
+				}
+			}
+
 			// Collect assistant content for block completion
 			if eventType == "answer" || eventType == "content" {
 				assistantContentMu.Lock()
@@ -1272,12 +1514,34 @@ func (h *ParrotHandler) executeAgent(
 			blockId = currentBlock.ID
 		}
 
-		return stream.Send(&v1pb.ChatResponse{
+		if err := stream.Send(&v1pb.ChatResponse{
 			EventType: eventType,
 			EventData: dataStr,
 			EventMeta: eventMeta,
 			BlockId:   blockId,
-		})
+		}); err != nil {
+			return err
+		}
+
+		if statsData != "" {
+			data := statsData
+			statsData = ""
+			if err := stream.Send(&v1pb.ChatResponse{
+				EventType: EventTypeStatsData,
+				EventData: data,
+				BlockId:   blockId,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if formRequest != nil {
+			form := formRequest
+			formRequest = nil
+			formEmitted = true
+			return h.emitFormRequest(ctx, stream, currentBlock, &PendingForm{Form: form, Agent: agent.Name()}, logger)
+		}
+		return nil
 	})
 
 	// Create callback wrapper
@@ -1286,49 +1550,39 @@ func (h *ParrotHandler) executeAgent(
 	}
 
 	// Start Heartbeat Goroutine
-	// Sends a "thinking" event every 5 seconds if no other events occur.
-	// This prevents load balancers and clients from closing the connection due to timeout.
-	heartbeatDone := make(chan struct{})
-	go func() {
-		ticker := time.NewTicker(5 * time.Second)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-heartbeatDone:
-				return
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				// Check time since last activity
-				lastTime := time.Unix(0, lastEventTime.Load())
-				if time.Since(lastTime) > 5*time.Second {
-					// Send heartbeat
-					streamMu.Lock()
-					// Phase 4: Include BlockId in heartbeat
-					var blockId int64
-					if currentBlock != nil {
-						blockId = currentBlock.ID
-					}
-					// Just send a lightweight ping chunk
-					err := stream.Send(&v1pb.ChatResponse{
-						EventType: "ping",
-						EventData: ".", // Minimal data
-						BlockId:   blockId,
-					})
-					streamMu.Unlock()
-					// If send fails, client disconnected - stop heartbeat early
-					if err != nil {
-						logger.Debug("Heartbeat send failed, stopping", slog.String("error", err.Error()))
-						return
-					}
-				}
-			}
+	// Narrates progress ("running task 2/4: searching memos") while the stream is idle.
+	// This also prevents load balancers and clients from closing the connection due to timeout.
+	tickerBlockID := func() int64 {
+		// Phase 4: Include BlockId in heartbeat
+		if currentBlock != nil {
+			return currentBlock.ID
 		}
-	}()
+		return 0
+	}
+	tickerSend := func(resp *v1pb.ChatResponse) error {
+		streamMu.Lock()
+		defer streamMu.Unlock()
+		if err := stream.Send(resp); err != nil {
+			// Client disconnected - stop heartbeat early
+			logger.Debug("Heartbeat send failed, stopping", slog.String("error", err.Error()))
+			return err
+		}
+		return nil
+	}
+	stopHeartbeat := startProgressHeartbeat(narrator, tickerBlockID, tickerSend)
+
+	// Stream token usage of the LLM calls for the live cost ticker
+	usage := newUsageTracker()
+	ctx = llm.WithUsageObserver(ctx, usage.Observe)
+	stopUsage := startUsageReporter(usage, tickerBlockID, tickerSend)
+
+	// Stop the round once it crosses its budget
+	runCtx, budget, stopBudget := withBudgetGuard(ctx, req.Budget, usage)
 
 	// Execute agent
-	defer close(heartbeatDone) // Ensure heartbeat stops even on panic
+	defer stopHeartbeat() // Ensure heartbeat stops even on panic
+	defer stopUsage()
+	defer stopBudget()
 
 	// Backend-driven context: use contextBuilder to build history
 	// No longer accept req.History from frontend (Backend as Source of Truth)
@@ -1360,11 +1614,11 @@ func (h *ParrotHandler) executeAgent(
 			UserID:        req.UserID,
 			HistoryLength: historyLength,
 		}
-		builtHistory, err := h.contextBuilder.BuildHistory(ctx, ctxReq)
+		builtHistory, err := h.buildHistory(ctx, ctxReq)
 		if err != nil {
 			logger.Error("Failed to build history from context engine", err)
 			// Return error instead of falling back to req.History
-			return err
+			return errors.ContextBuildFailed(err)
 		}
 		// Handle nil history gracefully
 		if builtHistory == nil {
@@ -1384,14 +1638,16 @@ func (h *ParrotHandler) executeAgent(
 		// contextBuilder not initialized: this should not happen in production
 		err := fmt.Errorf("context builder not initialized for conversation %d", req.ConversationID)
 		logger.Error("Context builder not initialized", err)
-		return err
+		return errors.ContextBuildFailed(err)
 	}
 
-	execErr := agent.Execute(ctx, req.Message, history, callback)
+	execCtx, execSpan := startAgentSpan(runCtx, agent.Name())
+	execErr := agent.Execute(execCtx, req.Message, history, callback)
+	observability.EndSpan(execSpan, execErr)
 	logger.Info("ai.agent.completed",
 		slog.String("execErr", fmt.Sprintf("%v", execErr)),
 		slog.Int64("duration_ms", time.Since(sessionStartTime).Milliseconds()))
-	if execErr != nil {
+	if execErr != nil && budget.Exceeded() == nil && !chatStopped(ctx) {
 		logger.Error("Agent execution failed", execErr)
 		// Don't return here, continue to send session summary
 	}
@@ -1404,7 +1660,7 @@ func (h *ParrotHandler) executeAgent(
 	handoffReason := inabilityReport.reason
 	inabilityMu.Unlock()
 
-	if shouldHandoff && handoffCapability != "" && h.capabilityMap != nil {
+	if shouldHandoff && handoffCapability != "" && h.capabilityMap != nil && budget.Exceeded() == nil && !chatStopped(ctx) {
 		// Use CapabilityMap to find alternative experts that can handle the missing capability
 		alternatives := h.capabilityMap.FindAlternativeExperts(handoffCapability, agent.Name())
 
@@ -1511,7 +1767,9 @@ func (h *ParrotHandler) executeAgent(
 				}
 
 				// Execute handoff expert
-				handoffErr := handoffExpert.Execute(ctx, req.Message, history, handoffCallback)
+				handoffCtx, handoffSpan := startAgentSpan(runCtx, handoffAgent)
+				handoffErr := handoffExpert.Execute(handoffCtx, req.Message, history, handoffCallback)
+				observability.EndSpan(handoffSpan, handoffErr)
 				if handoffErr != nil {
 					logger.Error("handoff: execution failed", handoffErr)
 					execFailData, _ := json.Marshal(map[string]string{"error": handoffErr.Error()})
@@ -1540,6 +1798,30 @@ func (h *ParrotHandler) executeAgent(
 		}
 	}
 
+	// Send the final usage before the session summary
+	stopUsage()
+
+	// A round stopped by its budget ends with what it produced so far
+	if budget.Exceeded() != nil {
+		assistantContentMu.Lock()
+		partialContent := assistantContent.String()
+		assistantContentMu.Unlock()
+		summary := h.completeOverBudget(ctx, req, currentBlock, budget, partialContent,
+			time.Since(sessionStartTime).Milliseconds(), tickerSend, logger)
+		stopHeartbeat()
+		return tickerSend(&v1pb.ChatResponse{BlockId: tickerBlockID(), Done: true, BlockSummary: summary})
+	}
+
+	// A round stopped by the user ends with what it produced so far
+	if chatStopped(ctx) {
+		assistantContentMu.Lock()
+		partialContent := assistantContent.String()
+		assistantContentMu.Unlock()
+		stopHeartbeat()
+		return tickerSend(h.completeStopped(ctx, req, currentBlock, partialContent,
+			time.Since(sessionStartTime).Milliseconds(), logger))
+	}
+
 	// Prepare session summary
 
 	// Calculate session summary
@@ -1569,6 +1851,15 @@ func (h *ParrotHandler) executeAgent(
 		}
 	}
 
+	// Model actually used: reported by the agent (CLI stream for Geek/Evolution),
+	// otherwise by the LLM responses of the round
+	modelUsed := usage.Model()
+	if normalStats != nil {
+		if model := normalStats.GetStatsSnapshot().ModelUsed; model != "" {
+			modelUsed = model
+		}
+	}
+
 	// Safely get tool usage stats
 	toolMu.Lock()
 	finalToolCallCount := int32(len(toolsUsed))
@@ -1590,6 +1881,9 @@ func (h *ParrotHandler) executeAgent(
 		ToolsUsed:       finalToolsUsed,
 		TotalCostUsd:    totalCostUsd,
 	}
+	if execErr != nil {
+		blockSummary.ErrorMsg = errors.Classify(execErr, errors.ErrCodeAgentExecutionFailed).Message
+	}
 
 	// Set SessionId - use conversation ID as default
 	// Note: Only Geek/Evolution modes have real UUID session IDs
@@ -1638,11 +1932,15 @@ func (h *ParrotHandler) executeAgent(
 		}
 	}
 
+	observability.AddTokens(logger.AgentType, int64(blockSummary.TotalInputTokens), int64(blockSummary.TotalOutputTokens),
+		int64(blockSummary.TotalCacheReadTokens), int64(blockSummary.TotalCacheWriteTokens))
+
 	// Phase 5: Complete or mark error on Block BEFORE sending done marker
 	// This ensures that when the frontend calls refetchBlocks() after receiving the done event,
 	// the Block's assistantContent is already persisted in the database.
 	// This fixes the "Initializing..." stuck issue caused by the race condition where
 	// refetchBlocks() executes before CompleteBlock() completes.
+	var suggestionsCh <-chan []string
 	if currentBlock != nil && h.blockManager != nil {
 		assistantContentMu.Lock()
 		finalContent := assistantContent.String()
@@ -1667,6 +1965,7 @@ func (h *ParrotHandler) executeAgent(
 			ToolsUsed:            blockSummary.ToolsUsed,
 			FilesModified:        int(blockSummary.FilesModified),
 			FilePaths:            blockSummary.FilePaths,
+			ModelUsed:            modelUsed,
 		}
 
 		if execErr != nil {
@@ -1692,7 +1991,9 @@ func (h *ParrotHandler) executeAgent(
 
 				// Phase 3: Async episodic memory generation
 				// Trigger memory generation after successful block completion
-				if h.memoryGenerator != nil && len(currentBlock.UserInputs) > 0 {
+				// (never for locked conversations: memories are stored in clear)
+				locked, _ := h.conversationLocked(currentBlock.ConversationID)
+				if h.memoryGenerator != nil && len(currentBlock.UserInputs) > 0 && !locked {
 					h.memoryGenerator.GenerateAsync(ctx, memory.MemoryRequest{
 						BlockID:   currentBlock.ID,
 						UserID:    req.UserID,
@@ -1718,6 +2019,14 @@ func (h *ParrotHandler) executeAgent(
 					}
 				}
 				// Title generation moved to block creation time for parallel execution
+
+				// Follow-up questions are generated while the done marker is sent
+				streamMu.Lock()
+				askedForm := formEmitted
+				streamMu.Unlock()
+				if blockMode == BlockModeNormal && !askedForm {
+					suggestionsCh = h.startFollowUpSuggestions(ctx, req.UserID, currentBlock, req.Message, finalContent)
+				}
 			}
 		}
 	}
@@ -1731,6 +2040,7 @@ func (h *ParrotHandler) executeAgent(
 		slog.Int("output_tokens", int(blockSummary.TotalOutputTokens)),
 		slog.Int64("tool_calls", int64(blockSummary.ToolCallCount)),
 		slog.Float64("cost_usd", blockSummary.TotalCostUsd),
+		slog.String("model", modelUsed),
 	)
 	// Phase 4: Include BlockId in done marker
 	var blockId int64
@@ -1757,6 +2067,10 @@ func (h *ParrotHandler) executeAgent(
 		return sendErr
 	}
 
+	// No progress after the done marker while waiting for follow-up questions
+	stopHeartbeat()
+	sendFollowUpSuggestions(ctx, stream, blockId, suggestionsCh)
+
 	// Safely get unique event count
 	countMu.Lock()
 	uniqueEventTokenCount := len(eventCounts)
@@ -1773,6 +2087,135 @@ func (h *ParrotHandler) executeAgent(
 	return execErr
 }
 
+// emitFormRequest streams a form_request event and records the form on the block
+// so that SubmitForm can validate the answers and resume the same block.
+func (h *ParrotHandler) emitFormRequest(
+	ctx context.Context,
+	stream ChatStream,
+	block *store.AIBlock,
+	pending *PendingForm,
+	logger *observability.RequestContext,
+) error {
+	form := pending.Form
+	data, err := json.Marshal(form)
+	if err != nil {
+		return err
+	}
+
+	var blockID int64
+	if block != nil && h.blockManager != nil {
+		blockID = block.ID
+		if err := h.blockManager.AppendEvent(ctx, block.ID, EventTypeFormRequest, string(data), nil); err != nil {
+			logger.Warn("Failed to enqueue form request for persistence",
+				slog.Int64("block_id", block.ID),
+				slog.String("error", err.Error()))
+		}
+		if err := h.blockManager.SetPendingForm(ctx, block.ID, pending); err != nil {
+			logger.Warn("Failed to record pending form",
+				slog.Int64("block_id", block.ID),
+				slog.String("error", err.Error()))
+		}
+	}
+
+	logger.Info("ai.agent.form_requested",
+		slog.String("form_id", form.ID),
+		slog.Int("field_count", len(form.Fields)))
+
+	return stream.Send(&v1pb.ChatResponse{
+		EventType: EventTypeFormRequest,
+		EventData: string(data),
+		BlockId:   blockID,
+	})
+}
+
+// canSuggestAgents reports whether the request has a block to hold agent
+// suggestions until the user picks one.
+func (h *ParrotHandler) canSuggestAgents(req *ChatRequest) bool {
+	return h.blockManager != nil && h.factory != nil && h.factory.store != nil &&
+		req.ConversationID > 0 && !req.IsTempConversation
+}
+
+// routingThreshold returns the user's ChatRouter confidence threshold for manual
+// agent selection (0 when disabled or unavailable).
+func (h *ParrotHandler) routingThreshold(ctx context.Context, userID int32) float64 {
+	settings, err := GetRoutingSettings(ctx, h.factory.store, userID)
+	if err != nil {
+		slog.Warn("failed to load routing settings", "user_id", userID, "error", err)
+		return 0
+	}
+	return settings.ConfidenceThreshold
+}
+
+// suggestAgents streams an agent_suggestions event and finishes the round.
+// The user's pick arrives through SelectAgent, which resumes the same block.
+func (h *ParrotHandler) suggestAgents(
+	ctx context.Context,
+	req *ChatRequest,
+	stream ChatStream,
+	suggestions *AgentSuggestions,
+) error {
+	logger := observability.NewRequestContext(slog.Default(), "suggestions", req.UserID)
+
+	block, err := h.blockManager.CreateBlockForChat(
+		ctx,
+		req.ConversationID,
+		req.Message,
+		req.AgentType,
+		h.determineBlockMode(req),
+	)
+	if err != nil {
+		logger.Error("Failed to create block for agent suggestions", err)
+		return errors.StorageFailed(err)
+	}
+	if err := stream.Send(&v1pb.ChatResponse{
+		BlockId:   block.ID,
+		EventType: "block_created",
+		EventData: req.Message,
+	}); err != nil {
+		logger.Warn("Failed to send block_created event", slog.String("error", err.Error()))
+	}
+	if h.titleGenerator != nil {
+		h.maybeGenerateConversationTitle(ctx, req.ConversationID, req.Message)
+	}
+
+	data, err := json.Marshal(suggestions)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeAgentExecutionFailed, errors.UserMessage(errors.ErrCodeAgentExecutionFailed))
+	}
+	if err := h.blockManager.AppendEvent(ctx, block.ID, EventTypeAgentSuggestions, string(data), nil); err != nil {
+		logger.Warn("Failed to enqueue agent suggestions for persistence",
+			slog.Int64("block_id", block.ID),
+			slog.String("error", err.Error()))
+	}
+	if err := h.blockManager.SetPendingAgentChoice(ctx, block.ID, suggestions); err != nil {
+		return errors.StorageFailed(err)
+	}
+	if err := stream.Send(&v1pb.ChatResponse{
+		BlockId:   block.ID,
+		EventType: EventTypeAgentSuggestions,
+		EventData: string(data),
+	}); err != nil {
+		logger.Warn("Failed to send agent suggestions", slog.String("error", err.Error()))
+	}
+
+	if err := h.blockManager.CompleteBlock(ctx, block.ID, "", nil); err != nil {
+		logger.Warn("Failed to complete suggestions block", slog.String("error", err.Error()))
+	}
+
+	logger.Info("ai.chat.agent_suggested",
+		slog.Int64("block_id", block.ID),
+		slog.String("predicted", suggestions.Predicted().String()),
+		slog.Float64("confidence", suggestions.Confidence))
+
+	return stream.Send(&v1pb.ChatResponse{
+		BlockId: block.ID,
+		Done:    true,
+		BlockSummary: &v1pb.BlockSummary{
+			Status: "completed",
+		},
+	})
+}
+
 // RoutingHandler routes all agent requests through the parrot handler.
 // All agent types (including DEFAULT) are now implemented as standard parrots.
 type RoutingHandler struct {
@@ -1787,10 +2230,14 @@ func NewRoutingHandler(parrot *ParrotHandler) *RoutingHandler {
 }
 
 // Handle implements Handler interface by routing to the appropriate handler.
+// Each chat round is traced in a "chat" span, whose trace ID its events carry.
 func (h *RoutingHandler) Handle(ctx context.Context, req *ChatRequest, stream ChatStream) error {
+	ctx, span := startChatSpan(ctx, req)
 	// All agent types (including DEFAULT) now use parrot handler
 	// DEFAULT parrot (羽飞/Navi) is implemented as a standard parrot with pure LLM mode
-	return h.parrotHandler.Handle(ctx, req, stream)
+	err := h.parrotHandler.Handle(ctx, req, withTraceID(ctx, stream))
+	observability.EndSpan(span, err)
+	return err
 }
 
 // Close gracefully shuts down the underlying ParrotHandler and its singletons.
@@ -1822,18 +2269,19 @@ func HandleError(err error) error {
 		return nil
 	}
 
+	// If it's an AIError, convert it
+	var aiErr *errors.AIError
+	if stderrors.As(err, &aiErr) {
+		return FromAIError(aiErr)
+	}
+
 	// If it's already a gRPC status error, return as-is
 	if _, ok := status.FromError(err); ok {
 		return err
 	}
 
-	// If it's an AIError, convert it
-	if aiErr, ok := err.(*errors.AIError); ok {
-		return FromAIError(aiErr)
-	}
-
-	// Default to internal error
-	return status.Error(codes.Internal, err.Error())
+	// Classify any other error by its cause
+	return FromAIError(errors.Classify(err, errors.ErrCodeAgentExecutionFailed))
 }
 
 // NewChatRouter creates a new chat router for auto-routing based on intent classification.
@@ -0,0 +1,29 @@
+package ai
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hrygo/divinesense/server/internal/observability"
+)
+
+// observeChat records a chat request of an agent type started at start, and
+// returns its error.
+func observeChat(agentType string, start time.Time, err error) error {
+	observability.ObserveChatRequest(agentType, err, time.Since(start))
+	return err
+}
+
+// observeTaskEnd records an orchestrator task from its task_end event; tasks
+// that never ran, e.g. skipped ones, carry no duration and are not recorded.
+func observeTaskEnd(eventData string) {
+	var event struct {
+		Agent      string `json:"agent"`
+		Status     string `json:"status"`
+		DurationMs int64  `json:"duration_ms"`
+	}
+	if err := json.Unmarshal([]byte(eventData), &event); err != nil || event.DurationMs <= 0 {
+		return
+	}
+	observability.ObserveOrchestratorTask(event.Agent, event.Status, time.Duration(event.DurationMs)*time.Millisecond)
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"strconv"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/hrygo/divinesense/ai"
+	"github.com/hrygo/divinesense/plugin/blockbudget"
 	"github.com/hrygo/divinesense/server/auth"
 	"github.com/hrygo/divinesense/server/internal/errors"
 	"github.com/hrygo/divinesense/server/middleware"
@@ -26,6 +28,14 @@ type ChatRequest struct {
 	IsTempConversation bool
 	GeekMode           bool
 	EvolutionMode      bool
+	// ContinueBlockID resumes an existing block instead of creating a new one.
+	// Set by SubmitForm so the agent's follow-up streams into the asking block.
+	ContinueBlockID int64
+	// ClarificationRound counts the clarifying questions already answered for
+	// this request; the orchestrator stops asking once it reaches the limit.
+	ClarificationRound int
+	// Budget limits the tokens and cost of the block; nil is unlimited.
+	Budget *blockbudget.Budget
 	// RouteResult stores the routing decision for metadata persistence.
 	// Set by ParrotHandler.Handle after routing, used in executeAgent.
 	RouteResult *RouteResultMeta
@@ -70,7 +80,7 @@ type validationHandler struct {
 
 func (h *validationHandler) Handle(ctx context.Context, req *ChatRequest, stream ChatStream) error {
 	if req.Message == "" {
-		return status.Error(codes.InvalidArgument, "message is required")
+		return errors.InvalidArgument("message is required")
 	}
 	return h.next.Handle(ctx, req, stream)
 }
@@ -93,7 +103,7 @@ func (h *authHandler) Handle(ctx context.Context, req *ChatRequest, stream ChatS
 	// Get user ID from context (set by authentication layer)
 	userID, err := getUserID(ctx)
 	if err != nil {
-		return status.Error(codes.Unauthenticated, "unauthorized")
+		return errors.Unauthorized("unauthorized")
 	}
 
 	req.UserID = userID
@@ -121,7 +131,7 @@ type rateLimitHandler struct {
 func (h *rateLimitHandler) Handle(ctx context.Context, req *ChatRequest, stream ChatStream) error {
 	userKey := strconv.FormatInt(int64(req.UserID), 10)
 	if !h.limiter.Allow(userKey) {
-		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		return errors.RateLimitExceeded(errors.UserMessage(errors.ErrCodeRateLimitExceeded))
 	}
 	return h.next.Handle(ctx, req, stream)
 }
@@ -176,27 +186,52 @@ func ToAIError(err error) *errors.AIError {
 	}
 }
 
-// FromAIError converts an AIError to a gRPC status error.
+// FromAIError converts an AIError to a gRPC status error. The error code travels
+// as the Reason of an ErrorInfo detail so clients can show an actionable message.
 func FromAIError(err *errors.AIError) error {
 	if err == nil {
 		return nil
 	}
 
-	switch err.Code {
+	st := status.New(grpcCode(err.Code), err.Message)
+	info := &errdetails.ErrorInfo{
+		Reason: string(err.Code),
+		Domain: ErrorDomain,
+		Metadata: map[string]string{
+			"retryable": strconv.FormatBool(errors.Retryable(err.Code)),
+		},
+	}
+	if detailed, detailErr := st.WithDetails(info); detailErr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// grpcCode returns the gRPC status code of an error code.
+func grpcCode(code errors.ErrorCode) codes.Code {
+	switch code {
 	case errors.ErrCodeUnauthorized:
-		return status.Error(codes.Unauthenticated, err.Message)
-	case errors.ErrCodeRateLimitExceeded:
-		return status.Error(codes.ResourceExhausted, err.Message)
+		return codes.Unauthenticated
+	case errors.ErrCodeRateLimitExceeded, errors.ErrCodeProviderRateLimited, errors.ErrCodeBudgetExceeded,
+		errors.ErrCodeQuotaExceeded, errors.ErrCodeWorkspaceFull:
+		return codes.ResourceExhausted
 	case errors.ErrCodeInvalidArgument:
-		return status.Error(codes.InvalidArgument, err.Message)
-	case errors.ErrCodeServiceUnavailable, errors.ErrCodeLLMUnavailable:
-		return status.Error(codes.Unavailable, err.Message)
-	case errors.ErrCodeTimeout:
-		return status.Error(codes.DeadlineExceeded, err.Message)
+		return codes.InvalidArgument
+	case errors.ErrCodeServiceUnavailable, errors.ErrCodeLLMUnavailable,
+		errors.ErrCodeProviderError, errors.ErrCodeCLINotFound, errors.ErrCodeOverloaded:
+		return codes.Unavailable
+	case errors.ErrCodeTimeout, errors.ErrCodeProviderTimeout:
+		return codes.DeadlineExceeded
 	case errors.ErrCodeContextCanceled:
-		return status.Error(codes.Canceled, err.Message)
+		return codes.Canceled
+	case errors.ErrCodeAgentNotFound:
+		return codes.NotFound
+	case errors.ErrCodeDangerBlocked, errors.ErrCodeToolNotPermitted:
+		return codes.PermissionDenied
+	case errors.ErrCodeProviderAuthFailed, errors.ErrCodeConversationLocked, errors.ErrCodeTestsFailed:
+		return codes.FailedPrecondition
 	default:
-		return status.Error(codes.Internal, err.Message)
+		return codes.Internal
 	}
 }
 
@@ -0,0 +1,236 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hrygo/divinesense/ai/agents/orchestrator"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+)
+
+// EventTypeProgress is the heartbeat sent while the stream is otherwise idle.
+// EventData is the JSON-encoded Progress, narrating what the agent is doing.
+const EventTypeProgress = "progress"
+
+const (
+	// progressIdleInterval is how long the stream must be silent before
+	// progress is narrated; it is also the minimum gap between narrations.
+	progressIdleInterval = 3 * time.Second
+	// progressKeepaliveMin is the keepalive gap at the start of a run, when the
+	// narration has not changed. It grows with the run time up to progressKeepaliveMax,
+	// so long runs are not flooded with identical heartbeats.
+	progressKeepaliveMin = 5 * time.Second
+	progressKeepaliveMax = 15 * time.Second
+)
+
+// toolNarrations describes well-known tools in progress messages.
+var toolNarrations = map[string]string{
+	"memo_search":     "searching memos",
+	"memo_write":      "saving a memo",
+	"schedule_query":  "checking your schedule",
+	"schedule_add":    "creating the event",
+	"schedule_update": "updating the event",
+	"schedule_delete": "deleting the event",
+	"find_free_time":  "looking for free time",
+}
+
+// Progress is the payload of a progress event.
+type Progress struct {
+	Message   string `json:"message"`
+	Phase     string `json:"phase"`
+	Tool      string `json:"tool,omitempty"`
+	TaskIndex int    `json:"task_index,omitempty"`
+	TaskTotal int    `json:"task_total,omitempty"`
+	ElapsedMs int64  `json:"elapsed_ms"`
+}
+
+// progressNarrator tracks agent and orchestrator state from the streamed events
+// and turns it into progress messages for the heartbeat.
+type progressNarrator struct {
+	startedAt time.Time
+	lastEvent atomic.Int64 // unix nanos of the last streamed event
+
+	mu        sync.Mutex
+	phase     string
+	activity  string
+	tool      string
+	taskIndex int
+	taskTotal int
+	lastSent  string
+	sentAt    time.Time
+}
+
+func newProgressNarrator() *progressNarrator {
+	n := &progressNarrator{startedAt: time.Now(), phase: "thinking", activity: "thinking"}
+	n.lastEvent.Store(n.startedAt.UnixNano())
+	return n
+}
+
+// Observe updates the narration state from a streamed event.
+func (n *progressNarrator) Observe(eventType, eventData string) {
+	n.lastEvent.Store(time.Now().UnixNano())
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	switch eventType {
+	case "decompose_start":
+		n.phase, n.activity = "planning", "planning the steps"
+	case orchestrator.EventTypePlan:
+		var plan struct {
+			Tasks []json.RawMessage `json:"tasks"`
+		}
+		if json.Unmarshal([]byte(eventData), &plan) == nil {
+			n.taskTotal = len(plan.Tasks)
+		}
+	case orchestrator.EventTypeTaskStart:
+		var task struct {
+			Index   int    `json:"index"`
+			Agent   string `json:"agent"`
+			Purpose string `json:"purpose"`
+		}
+		if json.Unmarshal([]byte(eventData), &task) == nil {
+			if task.Index >= 0 {
+				n.taskIndex = task.Index + 1
+			}
+			n.phase, n.tool = "task", ""
+			n.activity = task.Purpose
+			if n.activity == "" {
+				n.activity = "asking the " + task.Agent + " expert"
+			}
+		}
+	case "thinking":
+		n.phase, n.activity, n.tool = "thinking", "thinking", ""
+	case "tool_use":
+		n.phase = "tool"
+	case "answer", "content":
+		n.phase, n.activity, n.tool = "answering", "writing the answer", ""
+	case "aggregation":
+		n.phase, n.activity = "aggregating", "combining the results"
+	}
+}
+
+// ObserveTool records the tool an agent is calling.
+func (n *progressNarrator) ObserveTool(toolName string) {
+	if toolName == "" {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.phase, n.tool = "tool", toolName
+	n.activity = toolNarrations[toolName]
+	if n.activity == "" {
+		n.activity = "calling " + toolName
+	}
+}
+
+// Next returns the progress to send now, or nil if the stream is active or the
+// last narration was sent too recently.
+func (n *progressNarrator) Next(now time.Time) *Progress {
+	if now.Sub(time.Unix(0, n.lastEvent.Load())) < progressIdleInterval {
+		return nil
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	progress := &Progress{
+		Phase:     n.phase,
+		Tool:      n.tool,
+		TaskIndex: n.taskIndex,
+		TaskTotal: n.taskTotal,
+		ElapsedMs: now.Sub(n.startedAt).Milliseconds(),
+	}
+	progress.Message = n.message()
+
+	sinceSent := now.Sub(n.sentAt)
+	if sinceSent < progressIdleInterval {
+		return nil
+	}
+	if progress.Message == n.lastSent && sinceSent < n.keepalive(now) {
+		return nil
+	}
+	n.lastSent, n.sentAt = progress.Message, now
+	return progress
+}
+
+// message renders the narration, e.g. "running task 2/4: searching memos".
+func (n *progressNarrator) message() string {
+	activity := strings.TrimSpace(n.activity)
+	if n.taskTotal > 1 && n.taskIndex > 0 {
+		return fmt.Sprintf("running task %d/%d: %s", n.taskIndex, n.taskTotal, activity)
+	}
+	return activity
+}
+
+// keepalive returns the current heartbeat gap for unchanged narration.
+func (n *progressNarrator) keepalive(now time.Time) time.Duration {
+	gap := progressKeepaliveMin + now.Sub(n.startedAt)/10
+	return min(gap, progressKeepaliveMax)
+}
+
+// startProgressHeartbeat narrates progress through send while the stream is idle.
+// It stops when send fails (client disconnected) or when the returned stop
+// function is called; stop waits for an in-flight send to finish.
+func startProgressHeartbeat(narrator *progressNarrator, blockID func() int64, send func(*v1pb.ChatResponse) error) (stop func()) {
+	return startStreamTicker(time.Second, func(now time.Time, final bool) *v1pb.ChatResponse {
+		if final {
+			return nil
+		}
+		return tickerEvent(EventTypeProgress, narrator.Next(now), blockID())
+	}, send)
+}
+
+// startStreamTicker sends the event returned by next every interval, skipping
+// nil events. On stop, next is called once more with final set. The ticker
+// also stops when send fails; stop waits for an in-flight send to finish.
+func startStreamTicker(interval time.Duration, next func(now time.Time, final bool) *v1pb.ChatResponse, send func(*v1pb.ChatResponse) error) (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				if resp := next(time.Now(), true); resp != nil {
+					_ = send(resp)
+				}
+				return
+			case now := <-ticker.C:
+				resp := next(now, false)
+				if resp == nil {
+					continue
+				}
+				if err := send(resp); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+// tickerEvent encodes payload as a ChatResponse, or returns nil if payload is nil.
+func tickerEvent[T any](eventType string, payload *T, blockID int64) *v1pb.ChatResponse {
+	if payload == nil {
+		return nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil
+	}
+	return &v1pb.ChatResponse{EventType: eventType, EventData: string(data), BlockId: blockID}
+}
@@ -0,0 +1,38 @@
+package ai
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressNarrator(t *testing.T) {
+	n := newProgressNarrator()
+	start := n.startedAt
+
+	// Active stream: no heartbeat.
+	require.Nil(t, n.Next(start.Add(time.Second)))
+
+	n.Observe("plan", `{"tasks":[{"id":"t1"},{"id":"t2"},{"id":"t3"},{"id":"t4"}]}`)
+	n.Observe("task_start", `{"id":"t2","index":1,"agent":"memo","purpose":""}`)
+	n.ObserveTool("memo_search")
+	n.lastEvent.Store(start.UnixNano())
+
+	p := n.Next(start.Add(4 * time.Second))
+	require.NotNil(t, p)
+	require.Equal(t, "running task 2/4: searching memos", p.Message)
+	require.Equal(t, "memo_search", p.Tool)
+
+	// Unchanged narration waits for the keepalive gap.
+	require.Nil(t, n.Next(start.Add(8*time.Second)))
+	require.NotNil(t, n.Next(start.Add(10*time.Second)))
+
+	// Changed narration is sent once the minimum gap has passed.
+	n.ObserveTool("find_free_time")
+	n.lastEvent.Store(start.UnixNano())
+	require.Nil(t, n.Next(start.Add(11*time.Second)))
+	p = n.Next(start.Add(13 * time.Second))
+	require.NotNil(t, p)
+	require.Equal(t, "running task 2/4: looking for free time", p.Message)
+}
@@ -0,0 +1,42 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/hrygo/divinesense/ai/core/retrieval"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+)
+
+// EventTypeRetrievalScope reports the retrieval scope of the conversation
+// before routing. EventData is the JSON-encoded retrieval.Scope; it is only
+// sent for conversations whose scope restricts retrieval.
+const EventTypeRetrievalScope = "retrieval_scope"
+
+// withRetrievalScope loads the retrieval scope of the conversation of a
+// request into ctx, for the memo searches and the context builder, and
+// reports it on the stream. Retrieval is left unrestricted when the scope
+// fails to load.
+func (h *ParrotHandler) withRetrievalScope(ctx context.Context, req *ChatRequest, stream ChatStream) context.Context {
+	if h.retrievalScopes == nil || req.ConversationID <= 0 {
+		return ctx
+	}
+	scope, err := h.retrievalScopes.Get(ctx, req.ConversationID)
+	if err != nil {
+		slog.Warn("failed to load retrieval scope", "conversation_id", req.ConversationID, "error", err)
+		return ctx
+	}
+	if !scope.Restricted() {
+		return ctx
+	}
+
+	data, err := json.Marshal(scope)
+	if err == nil {
+		err = stream.Send(&v1pb.ChatResponse{EventType: EventTypeRetrievalScope, EventData: string(data)})
+	}
+	if err != nil {
+		slog.Warn("failed to send retrieval_scope event", "error", err)
+	}
+	return retrieval.WithScope(ctx, scope)
+}
@@ -0,0 +1,101 @@
+package ai
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/plugin/shadow"
+)
+
+// shadowAgent returns the agent serving a request routed to production: the
+// production agent, also run in shadow by the sampled shadows of production.
+func (h *ParrotHandler) shadowAgent(production agentpkg.ParrotAgent, cfg *CreateConfig, req *ChatRequest, intent string) agentpkg.ParrotAgent {
+	if h.shadows == nil || !shadowSafeIntents[intent] {
+		return production
+	}
+	// Pairs compare with the production parrot, not with a canary
+	if _, ok := production.(*canaryRun); ok {
+		return production
+	}
+	names := h.shadows.Sample(production.Name())
+	if len(names) == 0 {
+		return production
+	}
+	run := &shadowRun{ParrotAgent: production, shadows: h.shadows, userID: req.UserID}
+	for _, name := range names {
+		variant, err := h.shadows.Get(name)
+		if err != nil {
+			continue
+		}
+		agent, err := h.factory.CreateShadow(name, cfg)
+		if err != nil {
+			slog.Warn("failed to create shadow, skipping it", "shadow", name, "error", err)
+			continue
+		}
+		run.variants = append(run.variants, variant)
+		run.agents = append(run.agents, agent)
+	}
+	if len(run.agents) == 0 {
+		return production
+	}
+	return run
+}
+
+// shadowRun serves a request with the production agent while shadows answer
+// it in the background, then records the paired results.
+type shadowRun struct {
+	agentpkg.ParrotAgent
+	variants []*shadow.Variant
+	agents   []agentpkg.ParrotAgent
+	shadows  *shadow.Shadows
+	userID   int32
+}
+
+// Execute runs the production agent for the user and the shadows unseen.
+func (r *shadowRun) Execute(ctx context.Context, userInput string, history []string, callback agentpkg.EventCallback) error {
+	var wg sync.WaitGroup
+	captures := make([]*answerCapture, len(r.agents))
+	for i, agent := range r.agents {
+		captures[i] = &answerCapture{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			shadowCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shadowTimeout)
+			defer cancel()
+			captures[i].run(shadowCtx, agent, userInput, history, nil)
+		}()
+	}
+
+	served := &answerCapture{}
+	served.run(ctx, r.ParrotAgent, userInput, history, callback)
+
+	go func() {
+		wg.Wait()
+		recordCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		for i, variant := range r.variants {
+			run := &shadow.Run{
+				Shadow:              variant.Name,
+				Production:          variant.Production,
+				Model:               variant.Model,
+				UserID:              r.userID,
+				Query:               userInput,
+				ProductionAnswer:    served.answer.String(),
+				ShadowAnswer:        captures[i].answer.String(),
+				ProductionLatencyMs: served.latency.Milliseconds(),
+				ShadowLatencyMs:     captures[i].latency.Milliseconds(),
+				ProductionTokens:    served.tokens,
+				ShadowTokens:        captures[i].tokens,
+				ProductionError:     errorText(served.err),
+				ShadowError:         errorText(captures[i].err),
+			}
+			if err := r.shadows.Record(recordCtx, run); err != nil {
+				slog.Warn("failed to record shadow run", "shadow", variant.Name, "error", err)
+			}
+		}
+	}()
+	return served.err
+}
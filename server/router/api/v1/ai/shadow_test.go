@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/plugin/shadow"
+)
+
+type fakeShadowStore struct {
+	recorded chan *shadow.Run
+}
+
+func (s *fakeShadowStore) ListSampleRates(context.Context) (map[string]int, error) {
+	return map[string]int{}, nil
+}
+
+func (s *fakeShadowStore) SaveSampleRate(context.Context, string, int) error { return nil }
+
+func (s *fakeShadowStore) SaveRun(_ context.Context, run *shadow.Run) error {
+	s.recorded <- run
+	return nil
+}
+
+func (s *fakeShadowStore) ListRuns(context.Context, string, int64, int) ([]*shadow.Run, error) {
+	return nil, nil
+}
+
+func TestShadowRun_Execute(t *testing.T) {
+	store := &fakeShadowStore{recorded: make(chan *shadow.Run, 1)}
+	run := &shadowRun{
+		ParrotAgent: &fakeCanaryAgent{name: "memo", answer: "找到 3 条周报"},
+		variants:    []*shadow.Variant{{Name: "memo_reasoner", Production: "memo", Model: "deepseek-reasoner"}},
+		agents:      []agentpkg.ParrotAgent{&fakeCanaryAgent{name: "memo_reasoner", answer: "共 3 条周报"}},
+		shadows:     shadow.New(store),
+		userID:      7,
+	}
+
+	// The user only sees the production answer
+	var streamed []string
+	err := run.Execute(context.Background(), "找一下周报", nil, func(eventType string, eventData any) error {
+		streamed = append(streamed, eventData.(string))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"找到 3 条周报"}, streamed)
+	assert.Equal(t, "memo", run.Name())
+
+	select {
+	case recorded := <-store.recorded:
+		assert.Equal(t, "memo_reasoner", recorded.Shadow)
+		assert.Equal(t, "memo", recorded.Production)
+		assert.Equal(t, "deepseek-reasoner", recorded.Model)
+		assert.Equal(t, int32(7), recorded.UserID)
+		assert.Equal(t, "找到 3 条周报", recorded.ProductionAnswer)
+		assert.Equal(t, "共 3 条周报", recorded.ShadowAnswer)
+		assert.Empty(t, recorded.ShadowError)
+	case <-time.After(5 * time.Second):
+		t.Fatal("shadow run not recorded")
+	}
+}
+
+func TestShadowAgent_NotEligible(t *testing.T) {
+	shadows := shadow.New(&fakeShadowStore{})
+	shadows.Register(context.Background(), "memo_reasoner", "memo", "", 100)
+	h := &ParrotHandler{shadows: shadows}
+	production := &fakeCanaryAgent{name: "memo"}
+
+	// Intents with side effects are never run twice
+	agent := h.shadowAgent(production, &CreateConfig{Type: AgentTypeMemo}, &ChatRequest{UserID: 1}, "memo_create")
+	assert.Same(t, production, agent)
+}
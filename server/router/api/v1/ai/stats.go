@@ -0,0 +1,24 @@
+package ai
+
+import (
+	"encoding/json"
+
+	"github.com/hrygo/divinesense/plugin/usagestats"
+)
+
+// EventTypeStatsData is streamed after a usage_stats tool result so the client
+// can chart it. EventData is the JSON-encoded usagestats.Dataset.
+const EventTypeStatsData = "stats_data"
+
+// statsDataEvent returns the stats_data event data carried by a tool result.
+func statsDataEvent(toolResult string) (string, bool) {
+	dataset, ok := usagestats.ParseDataset(toolResult)
+	if !ok {
+		return "", false
+	}
+	data, err := json.Marshal(dataset)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
@@ -0,0 +1,141 @@
+package ai
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/server/internal/observability"
+	"github.com/hrygo/divinesense/store"
+)
+
+// EventTypeStopped is the done event of a block stopped by the user; the
+// block is marked interrupted with the content produced before the stop.
+const EventTypeStopped = "stopped"
+
+// BlockSummaryStatusInterrupted is the BlockSummary status of blocks
+// stopped by the user.
+const BlockSummaryStatusInterrupted = "interrupted"
+
+// ErrChatStopped is the cancellation cause of chats stopped by StopChat.
+var ErrChatStopped = stderrors.New("chat stopped by the user")
+
+// chatStopped reports whether the chat of ctx was stopped by the user.
+func chatStopped(ctx context.Context) bool {
+	return stderrors.Is(context.Cause(ctx), ErrChatStopped)
+}
+
+// ActiveChats tracks the chats running on this instance by conversation, so
+// that StopChat can cancel them. The zero value is ready to use.
+type ActiveChats struct {
+	mu    sync.Mutex
+	chats map[int32]*activeChat
+}
+
+// activeChat is a running chat.
+type activeChat struct {
+	req    *ChatRequest
+	cancel context.CancelCauseFunc
+}
+
+// Start registers the chat of req, returning the context to run it in and
+// the function unregistering it once it ends. A newer chat of the
+// conversation replaces the older one.
+func (a *ActiveChats) Start(ctx context.Context, req *ChatRequest) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	chat := &activeChat{req: req, cancel: cancel}
+
+	a.mu.Lock()
+	if a.chats == nil {
+		a.chats = make(map[int32]*activeChat)
+	}
+	a.chats[req.ConversationID] = chat
+	a.mu.Unlock()
+
+	return ctx, func() {
+		a.mu.Lock()
+		if a.chats[req.ConversationID] == chat {
+			delete(a.chats, req.ConversationID)
+		}
+		a.mu.Unlock()
+		cancel(context.Canceled)
+	}
+}
+
+// Stop cancels the running chat of a conversation of the user with
+// ErrChatStopped. It returns the request of the stopped chat, or nil if none
+// was running.
+func (a *ActiveChats) Stop(userID, conversationID int32) *ChatRequest {
+	a.mu.Lock()
+	chat, ok := a.chats[conversationID]
+	if ok && chat.req.UserID == userID {
+		delete(a.chats, conversationID)
+	}
+	a.mu.Unlock()
+
+	if !ok || chat.req.UserID != userID {
+		return nil
+	}
+	chat.cancel(ErrChatStopped)
+	return chat.req
+}
+
+// StopSession terminates the Geek or Evolution CLI session of a stopped
+// chat: canceling the chat only stops waiting for the CLI's turn.
+func (h *ParrotHandler) StopSession(req *ChatRequest, reason string) error {
+	runner, sessionID := h.geekRunner, geekSessionID(req.UserID, req.ConversationID)
+	if req.EvolutionMode {
+		runner, sessionID = h.evoRunner, evolutionSessionID(req.UserID, req.ConversationID)
+	}
+	if runner == nil || !(req.GeekMode || req.EvolutionMode) {
+		return nil
+	}
+	return runner.StopSession(sessionID, reason)
+}
+
+// StopSession terminates the CLI session of a stopped chat.
+func (h *RoutingHandler) StopSession(req *ChatRequest, reason string) error {
+	return h.parrotHandler.StopSession(req, reason)
+}
+
+// completeStopped ends a block stopped by the user: the block is marked
+// interrupted with the content produced so far. It returns the done event.
+func (h *ParrotHandler) completeStopped(
+	ctx context.Context,
+	req *ChatRequest,
+	block *store.AIBlock,
+	partialContent string,
+	durationMs int64,
+	logger *observability.RequestContext,
+) *v1pb.ChatResponse {
+	summary := &v1pb.BlockSummary{
+		SessionId:       fmt.Sprintf("conv_%d", req.ConversationID),
+		TotalDurationMs: durationMs,
+		Status:          BlockSummaryStatusInterrupted,
+	}
+	var blockID int64
+	if block != nil && h.blockManager != nil {
+		blockID = block.ID
+		// The chat context is canceled; the block must still be updated
+		if err := h.blockManager.MarkBlockInterrupted(context.WithoutCancel(ctx), block.ID, partialContent); err != nil {
+			logger.Warn("Failed to mark block as interrupted",
+				slog.Int64("block_id", block.ID),
+				slog.String("error", err.Error()))
+		}
+	}
+
+	logger.Info("ai.block.interrupted",
+		slog.Int64("block_id", blockID),
+		slog.Int("content_length", len(partialContent)),
+		slog.Int64("duration_ms", durationMs))
+
+	return &v1pb.ChatResponse{
+		EventType:    EventTypeStopped,
+		BlockId:      blockID,
+		Done:         true,
+		BlockSummary: summary,
+	}
+}
@@ -0,0 +1,64 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+)
+
+// stoppingRunner is an AgentRunner recording the sessions it stops.
+type stoppingRunner struct {
+	agentpkg.AgentRunner
+	stopped []string
+}
+
+func (r *stoppingRunner) StopSession(sessionID string, _ string) error {
+	r.stopped = append(r.stopped, sessionID)
+	return nil
+}
+
+func TestActiveChats(t *testing.T) {
+	var chats ActiveChats
+	req := &ChatRequest{UserID: 1, ConversationID: 10}
+	ctx, done := chats.Start(context.Background(), req)
+	defer done()
+
+	require.Nil(t, chats.Stop(2, 10), "only the owner stops a chat")
+	require.Nil(t, chats.Stop(1, 11))
+	require.NoError(t, ctx.Err())
+
+	require.Same(t, req, chats.Stop(1, 10))
+	require.ErrorIs(t, context.Cause(ctx), ErrChatStopped)
+	require.True(t, chatStopped(ctx))
+	require.Nil(t, chats.Stop(1, 10), "a chat is stopped once")
+
+	// A chat that ended is not stopped, and does not unregister its successor
+	_, doneOld := chats.Start(context.Background(), req)
+	next := &ChatRequest{UserID: 1, ConversationID: 10}
+	nextCtx, doneNext := chats.Start(context.Background(), next)
+	defer doneNext()
+	doneOld()
+	require.Same(t, next, chats.Stop(1, 10))
+	require.True(t, chatStopped(nextCtx))
+
+	endedCtx, doneEnded := chats.Start(context.Background(), req)
+	doneEnded()
+	require.Nil(t, chats.Stop(1, 10))
+	require.False(t, chatStopped(endedCtx))
+}
+
+func TestParrotHandlerStopSession(t *testing.T) {
+	geekRunner, evoRunner := &stoppingRunner{}, &stoppingRunner{}
+	h := &ParrotHandler{geekRunner: geekRunner, evoRunner: evoRunner}
+
+	require.NoError(t, h.StopSession(&ChatRequest{UserID: 1, ConversationID: 10}, "stopped"))
+	require.NoError(t, h.StopSession(&ChatRequest{UserID: 1, ConversationID: 10, GeekMode: true}, "stopped"))
+	require.NoError(t, h.StopSession(&ChatRequest{UserID: 1, ConversationID: 10, EvolutionMode: true}, "stopped"))
+
+	require.Equal(t, []string{geekSessionID(1, 10)}, geekRunner.stopped, "the session the Geek chat ran in")
+	require.Equal(t, []string{evolutionSessionID(1, 10)}, evoRunner.stopped)
+	require.NotEqual(t, geekSessionID(1, 10), evolutionSessionID(1, 10))
+}
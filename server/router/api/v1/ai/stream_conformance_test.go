@@ -0,0 +1,230 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/server/internal/observability"
+)
+
+// The stream conformance tests pin what clients receive for a given agent
+// output. Each fixture in testdata/stream has a .golden.json file with the
+// expected ChatResponse sequence; after an intended change of the event
+// mapping, regenerate them with:
+//
+//	go test ./server/router/api/v1/ai -run TestStreamConformance -update
+var updateGolden = flag.Bool("update", false, "update the golden files of the stream conformance tests")
+
+// fakeCLI stands in for the Claude Code CLI: it prints its version, and
+// replays the stream-json transcript of $STREAM_TRANSCRIPT for each input
+// message, with the session ID it was started with.
+const fakeCLI = `#!/bin/sh
+if [ "$1" = "--version" ]; then
+	echo "2.0.0 (Claude Code)"
+	exit 0
+fi
+session=""
+while [ $# -gt 0 ]; do
+	case "$1" in
+	--session-id | --resume) session="$2" ;;
+	esac
+	shift
+done
+while IFS= read -r _; do
+	sed "s/{{SESSION_ID}}/$session/g" "$STREAM_TRANSCRIPT"
+done
+`
+
+// TestStreamConformance_CLITranscripts runs Claude Code CLI transcripts
+// through the CC runner and the Geek mode of the handler.
+func TestStreamConformance_CLITranscripts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake CLI is a shell script")
+	}
+	transcripts, err := filepath.Glob(filepath.Join("testdata", "stream", "cli_*.jsonl"))
+	require.NoError(t, err)
+	require.NotEmpty(t, transcripts)
+
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "claude"), []byte(fakeCLI), 0o755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	for _, transcript := range transcripts {
+		name := strings.TrimSuffix(filepath.Base(transcript), ".jsonl")
+		t.Run(name, func(t *testing.T) {
+			abs, err := filepath.Abs(transcript)
+			require.NoError(t, err)
+			t.Setenv("STREAM_TRANSCRIPT", abs)
+			// Workspaces and session markers of the run
+			t.Setenv("HOME", t.TempDir())
+
+			runner, err := agentpkg.NewCCRunner(30*time.Second, slog.Default())
+			require.NoError(t, err)
+			defer runner.Close()
+
+			deps := requiredDeps()
+			deps.GeekRunner = runner
+			h, err := BuildParrotHandler(deps)
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			stream := &recordingStream{ctx: ctx}
+			// Errors of the CLI are part of the stream, the returned error is not
+			_ = h.Handle(ctx, &ChatRequest{Message: "hi", UserID: 1, ConversationID: 42, IsTempConversation: true, GeekMode: true}, stream)
+
+			assertGoldenStream(t, name, stream.responses)
+		})
+	}
+}
+
+// parrotEvent is a line of a parrot events fixture: an event of a parrot
+// callback, as data with optional metadata, or as an error value.
+type parrotEvent struct {
+	Type  string              `json:"type"`
+	Data  string              `json:"data"`
+	Meta  *agentpkg.EventMeta `json:"meta,omitempty"`
+	Error bool                `json:"error,omitempty"`
+}
+
+// scriptedAgent replays parrot events.
+type scriptedAgent struct {
+	events []parrotEvent
+}
+
+func (a *scriptedAgent) Name() string { return "scripted" }
+
+func (a *scriptedAgent) Execute(_ context.Context, _ string, _ []string, callback agentpkg.EventCallback) error {
+	for _, event := range a.events {
+		var data any = event.Data
+		switch {
+		case event.Error:
+			data = errors.New(event.Data)
+		case event.Meta != nil:
+			data = agentpkg.NewEventWithMeta(event.Type, event.Data, event.Meta)
+		}
+		if err := callback(event.Type, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *scriptedAgent) SelfDescribe() *agentpkg.ParrotSelfCognition { return nil }
+
+func (a *scriptedAgent) GetSessionStats() *agentpkg.NormalSessionStats { return nil }
+
+// TestStreamConformance_ParrotEvents runs the events of parrot callbacks
+// through the handler.
+func TestStreamConformance_ParrotEvents(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "stream", "parrot_*.jsonl"))
+	require.NoError(t, err)
+	require.NotEmpty(t, fixtures)
+
+	for _, fixture := range fixtures {
+		name := strings.TrimSuffix(filepath.Base(fixture), ".jsonl")
+		t.Run(name, func(t *testing.T) {
+			agent := &scriptedAgent{events: readParrotEvents(t, fixture)}
+			h, err := BuildParrotHandler(requiredDeps())
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			stream := &recordingStream{ctx: ctx}
+			logger := observability.NewRequestContext(slog.Default(), "conformance", 1)
+			require.NoError(t, h.executeAgent(ctx, agent, &ChatRequest{Message: "hi", UserID: 1}, stream, logger))
+
+			assertGoldenStream(t, name, stream.responses)
+		})
+	}
+}
+
+func readParrotEvents(t *testing.T, path string) []parrotEvent {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var events []parrotEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event parrotEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event), scanner.Text())
+		events = append(events, event)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+// assertGoldenStream compares the normalized responses with the golden file
+// of a fixture, or rewrites it with -update.
+func assertGoldenStream(t *testing.T, name string, responses []*v1pb.ChatResponse) {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	first := true
+	for _, resp := range responses {
+		if !normalizeStreamResponse(resp) {
+			continue
+		}
+		data, err := protojson.Marshal(resp)
+		require.NoError(t, err)
+		if !first {
+			buf.WriteString(",")
+		}
+		first = false
+		buf.Write(data)
+	}
+	buf.WriteString("]")
+	var got bytes.Buffer
+	require.NoError(t, json.Indent(&got, buf.Bytes(), "", "  "))
+	got.WriteString("\n")
+
+	golden := filepath.Join("testdata", "stream", name+".golden.json")
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(golden, got.Bytes(), 0o644))
+		return
+	}
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err, "missing golden file, run with -update to create it")
+	assert.JSONEq(t, string(want), got.String())
+}
+
+// normalizeStreamResponse clears the timings of a response, and reports
+// whether it belongs to the golden sequence: the heartbeats and usage
+// tickers depend on the wall clock, not on the agent output.
+func normalizeStreamResponse(resp *v1pb.ChatResponse) bool {
+	if resp.EventType == EventTypeProgress || resp.EventType == EventTypeUsageDelta {
+		return false
+	}
+	if meta := resp.EventMeta; meta != nil {
+		meta.DurationMs = 0
+		meta.TotalDurationMs = 0
+	}
+	if summary := resp.BlockSummary; summary != nil {
+		summary.TotalDurationMs = 0
+		summary.ThinkingDurationMs = 0
+		summary.ToolDurationMs = 0
+		summary.GenerationDurationMs = 0
+		// Collected from a map by the CC runner
+		slices.Sort(summary.ToolsUsed)
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package ai
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+	ctxpkg "github.com/hrygo/divinesense/ai/context"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/server/internal/observability"
+)
+
+// startChatSpan starts the root span of a chat round.
+func startChatSpan(ctx context.Context, req *ChatRequest) (context.Context, trace.Span) {
+	return observability.StartSpan(ctx, "chat",
+		attribute.Int("user.id", int(req.UserID)),
+		attribute.Int("conversation.id", int(req.ConversationID)),
+		attribute.String("chat.agent_type", req.AgentType.String()),
+		attribute.Bool("chat.geek_mode", req.GeekMode),
+		attribute.Bool("chat.evolution_mode", req.EvolutionMode),
+	)
+}
+
+// endRouteSpan ends the span of the routing of a chat round with its decision.
+func endRouteSpan(span trace.Span, result *agentpkg.ChatRouteResult, err error) {
+	if result != nil {
+		span.SetAttributes(
+			attribute.String("route.route", string(result.Route)),
+			attribute.String("route.method", result.Method),
+			attribute.Float64("route.confidence", result.Confidence),
+			attribute.Bool("route.needs_orchestration", result.NeedsOrchestration),
+		)
+	}
+	observability.EndSpan(span, err)
+}
+
+// startAgentSpan starts the span of the execution of an agent.
+func startAgentSpan(ctx context.Context, agent string) (context.Context, trace.Span) {
+	return observability.StartSpan(ctx, "agent.execute", attribute.String("agent.type", agent))
+}
+
+// buildHistory builds the history of a chat round in a "chat.context" span.
+func (h *ParrotHandler) buildHistory(ctx context.Context, req *ctxpkg.ContextRequest) ([]string, error) {
+	ctx, span := observability.StartSpan(ctx, "chat.context",
+		attribute.String("chat.agent_type", req.AgentType),
+		attribute.Int("context.history_length", req.HistoryLength),
+	)
+	history, err := h.contextBuilder.BuildHistory(ctx, req)
+	span.SetAttributes(attribute.Int("context.history_count", len(history)))
+	observability.EndSpan(span, err)
+	return history, err
+}
+
+// tracedStream stamps the events of a chat round with its trace ID, so that
+// debug views link them to the trace.
+type tracedStream struct {
+	ChatStream
+	traceID string
+}
+
+// withTraceID returns the stream stamping events with the trace ID of ctx;
+// streams of untraced rounds are returned as they are.
+func withTraceID(ctx context.Context, stream ChatStream) ChatStream {
+	traceID := observability.TraceID(ctx)
+	if traceID == "" {
+		return stream
+	}
+	return &tracedStream{ChatStream: stream, traceID: traceID}
+}
+
+func (s *tracedStream) Send(resp *v1pb.ChatResponse) error {
+	if resp.TraceId == "" {
+		resp.TraceId = s.traceID
+	}
+	return s.ChatStream.Send(resp)
+}
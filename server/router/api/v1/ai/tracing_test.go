@@ -0,0 +1,41 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+)
+
+func TestWithTraceID(t *testing.T) {
+	stream := &recordingStream{ctx: context.Background()}
+
+	// Untraced rounds keep their stream
+	ctx, span := startChatSpan(context.Background(), &ChatRequest{UserID: 1})
+	assert.Same(t, stream, withTraceID(ctx, stream))
+	span.End()
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	ctx, span = startChatSpan(context.Background(), &ChatRequest{UserID: 1, AgentType: AgentTypeMemo})
+	traced := withTraceID(ctx, stream)
+	require.NoError(t, traced.Send(&v1pb.ChatResponse{EventType: "received"}))
+	require.NoError(t, traced.Send(&v1pb.ChatResponse{EventType: "answer", TraceId: "other"}))
+	span.End()
+
+	traceID := span.SpanContext().TraceID().String()
+	require.Len(t, stream.responses, 2)
+	assert.Equal(t, traceID, stream.responses[0].TraceId)
+	assert.Equal(t, "other", stream.responses[1].TraceId)
+	require.Len(t, recorder.Ended(), 1)
+	assert.Equal(t, "chat", recorder.Ended()[0].Name())
+}
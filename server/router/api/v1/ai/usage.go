@@ -0,0 +1,130 @@
+package ai
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hrygo/divinesense/ai/agents/universal"
+	ctxpkg "github.com/hrygo/divinesense/ai/context"
+	"github.com/hrygo/divinesense/ai/core/llm"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+)
+
+// EventTypeUsageDelta reports the tokens used so far by the running block, for
+// a live cost ticker. EventData is the JSON-encoded UsageDelta; the final
+// numbers are in the BlockSummary of the done event.
+const EventTypeUsageDelta = "usage_delta"
+
+// usageReportInterval is the minimum gap between usage_delta events.
+const usageReportInterval = time.Second
+
+// UsageDelta is the payload of a usage_delta event. Counts are cumulative for the block.
+type UsageDelta struct {
+	InputTokens      int     `json:"input_tokens"`
+	OutputTokens     int     `json:"output_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+	// Estimated is set while part of the output is estimated from the streamed
+	// content because the provider has not reported usage yet.
+	Estimated bool `json:"estimated,omitempty"`
+	// Model is the model of the last LLM call that reported usage.
+	Model     string `json:"model,omitempty"`
+	ElapsedMs int64  `json:"elapsed_ms"`
+}
+
+// callUsage is the usage of one LLM call.
+type callUsage struct {
+	inputTokens  int
+	outputTokens int
+	reported     bool            // usage was reported by the provider
+	streamed     strings.Builder // content streamed before usage was reported
+}
+
+// usageTracker collects the usage of the LLM calls of a block as they run.
+type usageTracker struct {
+	startedAt time.Time
+
+	mu       sync.Mutex
+	calls    map[uint64]*callUsage
+	model    string
+	lastSent int // total tokens of the last usage_delta
+}
+
+func newUsageTracker() *usageTracker {
+	return &usageTracker{startedAt: time.Now(), calls: make(map[uint64]*callUsage)}
+}
+
+// Observe is the llm.UsageObserver of the block.
+func (t *usageTracker) Observe(update llm.UsageUpdate) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	call, ok := t.calls[update.CallID]
+	if !ok {
+		call = &callUsage{}
+		t.calls[update.CallID] = call
+	}
+	if update.Stats != nil {
+		call.inputTokens = update.Stats.PromptTokens
+		call.outputTokens = update.Stats.CompletionTokens
+		call.reported = true
+		call.streamed.Reset()
+		if update.Stats.Model != "" {
+			t.model = update.Stats.Model
+		}
+		return
+	}
+	if !call.reported {
+		call.streamed.WriteString(update.Delta)
+	}
+}
+
+// Snapshot returns the usage so far.
+func (t *usageTracker) Snapshot(now time.Time) UsageDelta {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delta := UsageDelta{Model: t.model, ElapsedMs: now.Sub(t.startedAt).Milliseconds()}
+	for _, call := range t.calls {
+		delta.InputTokens += call.inputTokens
+		if call.reported {
+			delta.OutputTokens += call.outputTokens
+		} else if call.streamed.Len() > 0 {
+			delta.OutputTokens += ctxpkg.EstimateTokens(call.streamed.String())
+			delta.Estimated = true
+		}
+	}
+	delta.TotalTokens = delta.InputTokens + delta.OutputTokens
+	delta.EstimatedCostUSD = universal.EstimateCostUSD(delta.InputTokens, delta.OutputTokens)
+	return delta
+}
+
+// Model returns the model of the last LLM call that reported usage, or "".
+func (t *usageTracker) Model() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.model
+}
+
+// Next returns the usage to send now, or nil if it has not changed since the
+// last usage_delta.
+func (t *usageTracker) Next(now time.Time) *UsageDelta {
+	delta := t.Snapshot(now)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if delta.TotalTokens == t.lastSent {
+		return nil
+	}
+	t.lastSent = delta.TotalTokens
+	return &delta
+}
+
+// startUsageReporter streams usage_delta events while the block runs, with a
+// last one on stop so the ticker ends on the final numbers.
+func startUsageReporter(tracker *usageTracker, blockID func() int64, send func(*v1pb.ChatResponse) error) (stop func()) {
+	return startStreamTicker(usageReportInterval, func(now time.Time, _ bool) *v1pb.ChatResponse {
+		return tickerEvent(EventTypeUsageDelta, tracker.Next(now), blockID())
+	}, send)
+}
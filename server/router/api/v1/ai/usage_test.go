@@ -0,0 +1,42 @@
+package ai
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/ai/core/llm"
+)
+
+func TestUsageTracker(t *testing.T) {
+	tracker := newUsageTracker()
+	now := tracker.startedAt
+
+	require.Nil(t, tracker.Next(now))
+
+	// A streaming call without provider usage is estimated from its content.
+	tracker.Observe(llm.UsageUpdate{CallID: 1, Delta: "hello world, "})
+	tracker.Observe(llm.UsageUpdate{CallID: 1, Delta: "how are you?"})
+	delta := tracker.Next(now.Add(time.Second))
+	require.NotNil(t, delta)
+	require.True(t, delta.Estimated)
+	require.Equal(t, 6, delta.OutputTokens)
+
+	// Unchanged usage is not sent again.
+	require.Nil(t, tracker.Next(now.Add(2*time.Second)))
+
+	// Reported usage replaces the estimate; concurrent calls add up.
+	tracker.Observe(llm.UsageUpdate{CallID: 1, Stats: &llm.LLMCallStats{PromptTokens: 1000, CompletionTokens: 8}, Final: true})
+	tracker.Observe(llm.UsageUpdate{CallID: 2, Stats: &llm.LLMCallStats{PromptTokens: 500, CompletionTokens: 20, Model: "deepseek-chat"}})
+	delta = tracker.Next(now.Add(3 * time.Second))
+	require.NotNil(t, delta)
+	require.False(t, delta.Estimated)
+	require.Equal(t, 1500, delta.InputTokens)
+	require.Equal(t, 28, delta.OutputTokens)
+	require.Equal(t, 1528, delta.TotalTokens)
+	require.InDelta(t, (1500*0.27+28*2.25)/1_000_000, delta.EstimatedCostUSD, 1e-12)
+	require.Equal(t, int64(3000), delta.ElapsedMs)
+	require.Equal(t, "deepseek-chat", delta.Model)
+	require.Equal(t, "deepseek-chat", tracker.Model())
+}
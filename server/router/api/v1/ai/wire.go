@@ -0,0 +1,251 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hrygo/divinesense/ai"
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/ai/agents/geek"
+	"github.com/hrygo/divinesense/ai/agents/orchestrator"
+	ctxpkg "github.com/hrygo/divinesense/ai/context"
+	"github.com/hrygo/divinesense/ai/memory"
+	aistats "github.com/hrygo/divinesense/ai/services/stats"
+	"github.com/hrygo/divinesense/plugin/canary"
+	"github.com/hrygo/divinesense/plugin/documentchat"
+	"github.com/hrygo/divinesense/plugin/evolutiontask"
+	"github.com/hrygo/divinesense/plugin/featureflag"
+	"github.com/hrygo/divinesense/plugin/geekworkspace"
+	"github.com/hrygo/divinesense/plugin/github"
+	"github.com/hrygo/divinesense/plugin/mcpserver"
+	"github.com/hrygo/divinesense/plugin/retrievalscope"
+	"github.com/hrygo/divinesense/plugin/shadow"
+	"github.com/hrygo/divinesense/plugin/toolpolicy"
+	"github.com/hrygo/divinesense/server/middleware"
+	"github.com/hrygo/divinesense/store"
+)
+
+// ErrInvalidDeps is returned by BuildParrotHandler for missing or
+// inconsistent dependencies.
+var ErrInvalidDeps = errors.New("invalid parrot handler dependencies")
+
+// HandlerDeps are the dependencies of a ParrotHandler. Required ones are
+// checked by BuildParrotHandler, so a misconfigured server fails at startup
+// rather than with a nil pointer at its first request.
+type HandlerDeps struct {
+	// Required
+	Factory        *AgentFactory
+	LLM            ai.LLMService
+	BlockManager   *BlockManager
+	ContextBuilder *ctxpkg.Service // Backend-driven history of conversations
+
+	// Routing of AUTO requests (optional, nil sends them to the default parrot)
+	ChatRouter      *agentpkg.ChatRouterWithMetadata // Sticky routing over the block metadata
+	MetadataManager *ctxpkg.MetadataManager          // Required with ChatRouter
+	Orchestrator    *orchestrator.Orchestrator       // Complex and multi-intent requests
+	CapabilityMap   *orchestrator.CapabilityMap      // Handoff expert lookup; requires Orchestrator
+
+	// Optional
+	Persister       *aistats.Persister
+	TitleGenerator  *ai.TitleGenerator
+	MemoryGenerator memory.Generator
+	SuggestionLLM   ai.LLMService      // nil disables follow-up suggestions
+	Canaries        *canary.Canaries   // Requires ChatRouter
+	FeatureFlags    *featureflag.Flags // Gates the orchestrator, Evolution Mode and canaries (nil leaves them on)
+	Shadows         *shadow.Shadows    // Requires ChatRouter
+	LoadShedder     *middleware.LoadShedder
+	GeekRunner      agentpkg.AgentRunner
+	EvolutionRunner agentpkg.AgentRunner
+	ToolPolicies    *toolpolicy.Policies    // Tools of the Geek Mode workspaces (nil permits all the tools)
+	MCPServers      *mcpserver.Servers      // MCP servers users register for Geek Mode (nil disables them)
+	GitHub          *github.GitHub          // GitHub tools of Geek Mode (nil disables them)
+	WorkspaceQuota  *geekworkspace.Quota    // Disk quota of the Geek Mode workspaces (nil leaves them unbounded)
+	EvolutionTasks  *evolutiontask.Tasks    // Backlog of approved Evolution tasks (nil disables /task)
+	RetrievalScopes *retrievalscope.Scopes  // Retrieval scope of conversations (nil leaves retrieval unrestricted)
+	DocumentChats   *documentchat.Documents // Documents conversations are bound to (nil disables the document mode)
+}
+
+// Validate reports the missing and inconsistent dependencies.
+func (d *HandlerDeps) Validate() error {
+	var problems []string
+	if d.Factory == nil {
+		problems = append(problems, "agent factory is required")
+	}
+	if d.LLM == nil {
+		problems = append(problems, "LLM service is required")
+	}
+	if d.BlockManager == nil {
+		problems = append(problems, "block manager is required")
+	}
+	if d.ContextBuilder == nil {
+		problems = append(problems, "context builder is required")
+	}
+	if d.ChatRouter != nil && d.MetadataManager == nil {
+		problems = append(problems, "chat router requires the metadata manager")
+	}
+	if d.CapabilityMap != nil && d.Orchestrator == nil {
+		problems = append(problems, "capability map requires the orchestrator")
+	}
+	if d.Orchestrator != nil && d.Factory != nil && d.Factory.GetParrotFactory() == nil {
+		problems = append(problems, "orchestrator requires an initialized agent factory")
+	}
+	if d.Canaries != nil && d.ChatRouter == nil {
+		problems = append(problems, "canaries require the chat router")
+	}
+	if d.Shadows != nil && d.ChatRouter == nil {
+		problems = append(problems, "shadows require the chat router")
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidDeps, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// BuildParrotHandler assembles a parrot handler from validated dependencies.
+func BuildParrotHandler(deps HandlerDeps) (*ParrotHandler, error) {
+	if err := deps.Validate(); err != nil {
+		return nil, err
+	}
+	h := &ParrotHandler{
+		factory:                deps.Factory,
+		llm:                    deps.LLM,
+		chatRouterWithMetadata: deps.ChatRouter,
+		orchestrator:           deps.Orchestrator,
+		capabilityMap:          deps.CapabilityMap,
+		persister:              deps.Persister,
+		blockManager:           deps.BlockManager,
+		titleGenerator:         deps.TitleGenerator,
+		metadataMgr:            deps.MetadataManager,
+		contextBuilder:         deps.ContextBuilder,
+		memoryGenerator:        deps.MemoryGenerator,
+		geekRunner:             deps.GeekRunner,
+		evoRunner:              deps.EvolutionRunner,
+		suggestionLLM:          deps.SuggestionLLM,
+		canaries:               deps.Canaries,
+		featureFlags:           deps.FeatureFlags,
+		shadows:                deps.Shadows,
+		loadShedder:            deps.LoadShedder,
+		toolPolicies:           deps.ToolPolicies,
+		mcpServers:             deps.MCPServers,
+		github:                 deps.GitHub,
+		workspaceQuota:         deps.WorkspaceQuota,
+		evolutionTasks:         deps.EvolutionTasks,
+		retrievalScopes:        deps.RetrievalScopes,
+		documentChats:          deps.DocumentChats,
+	}
+	if deps.ChatRouter != nil {
+		h.chatRouter = deps.ChatRouter.ChatRouter
+	}
+	return h, nil
+}
+
+// ModeRunnerBackends are the agent runner backends of the Geek and Evolution
+// modes; "" is the Claude Code CLI.
+type ModeRunnerBackends struct {
+	Geek      string
+	Evolution string
+}
+
+// NewModeRunners creates the singleton agent runners of the Geek and
+// Evolution modes. They are isolated, each with its own base system prompt
+// and namespace. A runner is nil when its backend is unavailable, which
+// disables its mode. onToolUse, if not nil, records the tool calls of both
+// modes, e.g. in the action audit log; the calls of locked conversations
+// reach it without their inputs and outputs.
+func NewModeRunners(st *store.Store, backends ModeRunnerBackends, onToolUse agentpkg.ToolUseHandler) (geekRunner, evoRunner agentpkg.AgentRunner) {
+	onToolUse = redactLockedToolUses(st, onToolUse)
+	// Admin token for danger bypass mode (Geek and Evolution modes)
+	adminToken := os.Getenv("DIVINESENSE_GEEK_ADMIN_TOKEN")
+
+	geekMode := geek.NewGeekMode("")
+	evoMode := geek.NewEvolutionMode(&geek.EvolutionModeConfig{
+		SourceDir: ".",
+		AdminOnly: os.Getenv("DIVINESENSE_EVOLUTION_ADMIN_ONLY") == "true",
+		Store:     st,
+	})
+
+	geekRunner, err := agentpkg.NewAgentRunner(backends.Geek, 30*time.Minute, slog.Default(),
+		agentpkg.WithAdminToken(adminToken),
+		agentpkg.WithBaseSystemPrompt(geekMode.BaseSystemPrompt()),
+		agentpkg.WithNamespace("divinesense-geek"),
+		agentpkg.WithToolViolationHandler(auditToolViolation(st)),
+		agentpkg.WithToolUseHandler(onToolUse),
+	)
+	if err != nil {
+		slog.Warn("Failed to create geekRunner (CLI not found?)", "backend", backends.Geek, "error", err)
+	}
+	evoRunner, err = agentpkg.NewAgentRunner(backends.Evolution, 30*time.Minute, slog.Default(),
+		agentpkg.WithAdminToken(adminToken),
+		agentpkg.WithBaseSystemPrompt(evoMode.BaseSystemPrompt()),
+		agentpkg.WithNamespace("divinesense-evolution"),
+		agentpkg.WithToolUseHandler(onToolUse),
+	)
+	if err != nil {
+		slog.Warn("Failed to create evoRunner (CLI not found?)", "backend", backends.Evolution, "error", err)
+	}
+	return geekRunner, evoRunner
+}
+
+// auditToolViolation records the tools Geek Mode sessions used against their
+// workspace policy, and the writes rejected on full workspaces, in the
+// security audit.
+func auditToolViolation(st *store.Store) agentpkg.ToolViolationHandler {
+	return func(ctx context.Context, v *agentpkg.ToolViolation) {
+		if st == nil || st.SecurityAuditStore == nil {
+			return
+		}
+		input := v.Input
+		if conversationLocked(st, v.ConversationID) {
+			input = ""
+		}
+		event := &store.SecurityAuditEvent{
+			SessionID:     v.SessionID,
+			UserID:        v.UserID,
+			AgentType:     v.Mode,
+			OperationType: "cc_tool",
+			OperationName: v.Tool,
+			RiskLevel:     "high",
+			CommandInput:  input,
+			ActionTaken:   "blocked",
+			Reason:        v.Reason(),
+			ToolID:        v.ToolID,
+			OccurredAt:    time.Now(),
+		}
+		// Audit must outlive the stopped session and its request context.
+		if err := st.SecurityAuditStore.LogSecurityEvent(context.WithoutCancel(ctx), event); err != nil {
+			slog.Warn("failed to audit tool violation", "tool", v.Tool, "error", err)
+		}
+	}
+}
+
+// redactLockedToolUses drops the inputs, files and outputs of the tool calls
+// of locked conversations before passing them to handler, which only records
+// their tool, status and timing: the audit log must not hold in cleartext
+// what the conversation seals.
+func redactLockedToolUses(st *store.Store, handler agentpkg.ToolUseHandler) agentpkg.ToolUseHandler {
+	if handler == nil {
+		return nil
+	}
+	return func(ctx context.Context, use *agentpkg.ToolUse) {
+		if conversationLocked(st, use.ConversationID) {
+			redacted := *use
+			redacted.Input, redacted.FilePaths, redacted.Output = "", nil, ""
+			use = &redacted
+		}
+		handler(ctx, use)
+	}
+}
+
+// conversationLocked reports whether the conversation of a CLI session is locked.
+func conversationLocked(st *store.Store, conversationID int64) bool {
+	if st == nil || conversationID == 0 {
+		return false
+	}
+	locked, _ := st.ConversationLockState(int32(conversationID))
+	return locked
+}
@@ -0,0 +1,95 @@
+package ai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/ai/agents/orchestrator"
+	ctxpkg "github.com/hrygo/divinesense/ai/context"
+	"github.com/hrygo/divinesense/ai/routing"
+	"github.com/hrygo/divinesense/plugin/canary"
+	"github.com/hrygo/divinesense/store"
+)
+
+func requiredDeps() HandlerDeps {
+	return HandlerDeps{
+		Factory:        NewAgentFactory(&mockLLM{}, nil, nil),
+		LLM:            &mockLLM{},
+		BlockManager:   NewBlockManager(nil),
+		ContextBuilder: ctxpkg.NewService(ctxpkg.DefaultConfig()),
+	}
+}
+
+func TestBuildParrotHandler(t *testing.T) {
+	deps := requiredDeps()
+	metadataMgr := ctxpkg.NewMetadataManager(nil, time.Minute)
+	deps.MetadataManager = metadataMgr
+	deps.ChatRouter = agentpkg.NewChatRouterWithMetadata(NewChatRouter(routing.NewService(routing.DefaultConfig())), metadataMgr)
+
+	h, err := BuildParrotHandler(deps)
+	require.NoError(t, err)
+	assert.Same(t, deps.ChatRouter, h.chatRouterWithMetadata)
+	assert.Same(t, deps.ChatRouter.ChatRouter, h.chatRouter)
+	assert.Same(t, metadataMgr, h.metadataMgr)
+	assert.Nil(t, h.geekRunner)
+}
+
+func TestBuildParrotHandler_MissingDeps(t *testing.T) {
+	_, err := BuildParrotHandler(HandlerDeps{})
+	require.ErrorIs(t, err, ErrInvalidDeps)
+	for _, dep := range []string{"agent factory", "LLM service", "block manager", "context builder"} {
+		assert.Contains(t, err.Error(), dep)
+	}
+}
+
+func TestBuildParrotHandler_InconsistentDeps(t *testing.T) {
+	deps := requiredDeps()
+	deps.CapabilityMap = orchestrator.NewCapabilityMap()
+	deps.Canaries = canary.New(nil)
+
+	_, err := BuildParrotHandler(deps)
+	require.ErrorIs(t, err, ErrInvalidDeps)
+	assert.Contains(t, err.Error(), "capability map requires the orchestrator")
+	assert.Contains(t, err.Error(), "canaries require the chat router")
+}
+
+// lockedSealer locks one conversation, never unlocked.
+type lockedSealer struct {
+	conversationID int32
+}
+
+func (s *lockedSealer) HasLocks() bool                   { return true }
+func (s *lockedSealer) Locked(conversationID int32) bool { return conversationID == s.conversationID }
+func (s *lockedSealer) Unlocked(int32) bool              { return false }
+func (s *lockedSealer) Seal(int32, string) (string, error) {
+	return "", store.ErrConversationLocked
+}
+func (s *lockedSealer) Open(_ int32, content string) (string, error) { return content, nil }
+
+func TestRedactLockedToolUses(t *testing.T) {
+	st := &store.Store{}
+	st.SetBlockSealer(&lockedSealer{conversationID: 1})
+	var uses []*agentpkg.ToolUse
+	handler := redactLockedToolUses(st, func(_ context.Context, use *agentpkg.ToolUse) { uses = append(uses, use) })
+
+	locked := &agentpkg.ToolUse{SessionID: "s1", ConversationID: 1, UserID: 7, Mode: "geek", Tool: "Write", ToolID: "t1",
+		Input: "map[content:secret file_path:/work/plan.md]", FilePaths: []string{"/work/plan.md"},
+		Status: agentpkg.ToolUseSuccess, Output: "written", DurationMs: 12}
+	clear := &agentpkg.ToolUse{SessionID: "s2", ConversationID: 2, Tool: "Bash", Input: "ls", Status: agentpkg.ToolUseSuccess, Output: "main.go"}
+	handler(context.Background(), locked)
+	handler(context.Background(), clear)
+
+	require.Len(t, uses, 2)
+	// The call of the locked conversation keeps its tool, status and timing only
+	assert.Equal(t, &agentpkg.ToolUse{SessionID: "s1", ConversationID: 1, UserID: 7, Mode: "geek", Tool: "Write", ToolID: "t1",
+		Status: agentpkg.ToolUseSuccess, DurationMs: 12}, uses[0])
+	assert.Equal(t, "written", locked.Output, "the event of the session is left untouched")
+	assert.Same(t, clear, uses[1])
+
+	assert.Nil(t, redactLockedToolUses(st, nil))
+}
@@ -17,10 +17,46 @@ import (
 	"github.com/hrygo/divinesense/ai/routing"
 	aistats "github.com/hrygo/divinesense/ai/services/stats"
 	"github.com/hrygo/divinesense/ai/tags"
+	"github.com/hrygo/divinesense/plugin/actionaudit"
+	"github.com/hrygo/divinesense/plugin/blockbudget"
+	"github.com/hrygo/divinesense/plugin/canary"
+	"github.com/hrygo/divinesense/plugin/checklist"
+	"github.com/hrygo/divinesense/plugin/contact"
+	"github.com/hrygo/divinesense/plugin/documentchat"
+	"github.com/hrygo/divinesense/plugin/entitygraph"
+	"github.com/hrygo/divinesense/plugin/evolutiontask"
+	"github.com/hrygo/divinesense/plugin/expense"
+	"github.com/hrygo/divinesense/plugin/featureflag"
+	"github.com/hrygo/divinesense/plugin/fewshot"
+	"github.com/hrygo/divinesense/plugin/flashcard"
+	"github.com/hrygo/divinesense/plugin/geekworkspace"
+	"github.com/hrygo/divinesense/plugin/github"
+	"github.com/hrygo/divinesense/plugin/habit"
+	"github.com/hrygo/divinesense/plugin/homeassistant"
+	"github.com/hrygo/divinesense/plugin/httpaction"
+	"github.com/hrygo/divinesense/plugin/intenttaxonomy"
+	"github.com/hrygo/divinesense/plugin/llmregistry"
+	"github.com/hrygo/divinesense/plugin/mailbox"
+	"github.com/hrygo/divinesense/plugin/mcpserver"
+	"github.com/hrygo/divinesense/plugin/meetingnotes"
+	"github.com/hrygo/divinesense/plugin/pdfdoc"
+	"github.com/hrygo/divinesense/plugin/reminder"
+	"github.com/hrygo/divinesense/plugin/retrievalscope"
+	"github.com/hrygo/divinesense/plugin/scripting"
+	"github.com/hrygo/divinesense/plugin/shadow"
+	"github.com/hrygo/divinesense/plugin/snippet"
+	"github.com/hrygo/divinesense/plugin/timeline"
+	"github.com/hrygo/divinesense/plugin/toolpack"
+	"github.com/hrygo/divinesense/plugin/toolpolicy"
+	"github.com/hrygo/divinesense/plugin/usagestats"
+	"github.com/hrygo/divinesense/plugin/webfetch"
+	"github.com/hrygo/divinesense/plugin/webhook"
 	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/server/affinity"
 	"github.com/hrygo/divinesense/server/auth"
 	"github.com/hrygo/divinesense/server/middleware"
 	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
+	embeddingrunner "github.com/hrygo/divinesense/server/runner/embedding"
 	"github.com/hrygo/divinesense/store"
 	dbpostgres "github.com/hrygo/divinesense/store/db/postgres"
 )
@@ -60,6 +96,7 @@ type AIService struct {
 	EmbeddingService         pluginai.EmbeddingService
 	LLMService               pluginai.LLMService
 	IntentLLMService         pluginai.LLMService // Simple tasks: title, summary, tags
+	LLMConfig                *pluginai.LLMConfig // Main LLM settings, the base of the models tried by shadows
 	conversationService      *aichat.ConversationService
 	AdaptiveRetriever        *retrieval.AdaptiveRetriever
 	IntentClassifierConfig   *pluginai.IntentClassifierConfig
@@ -68,13 +105,54 @@ type AIService struct {
 	routerService            *routing.Service
 	chatEventBus             *aichat.EventBus
 	Store                    *store.Store
+	Secret                   string // Signs the chat stream tickets
 	contextBuilder           *aichat.ContextBuilder
 	conversationSummarizer   *aichat.ConversationSummarizer
-	TitleGenerator           *pluginai.TitleGenerator // Conversation title generator
-	EmbeddingModel           string
-	persister                *aistats.Persister  // session stats async persister
-	enrichmentTrigger        *enrichment.Trigger // Async enrichment trigger
-	chatHandler              aichat.Handler      // Cached chat handler (created once)
+	TitleGenerator           *pluginai.TitleGenerator  // Conversation title generator
+	ScriptHooks              *scripting.Manager        // Optional: chat.request / chat.event hooks
+	HTTPActions              *httpaction.Registry      // Optional: admin-registered HTTP actions as tools
+	WebFetch                 *webfetch.Fetcher         // Optional: web pages fetched under the admin policy
+	SessionAffinity          *affinity.Router          // Optional: CC session affinity across instances
+	EntityGraph              *entitygraph.Graph        // Optional: personal knowledge graph of memos and chats
+	Contacts                 *contact.Contacts         // Optional: people of the knowledge graph with their follow-ups
+	Flashcards               *flashcard.Deck           // Optional: spaced-repetition flashcards from memos
+	Documents                *pdfdoc.Library           // Optional: page-aware search of PDF attachments
+	Snippets                 *snippet.Library          // Optional: code library of memos tagged #snippet
+	Timeline                 *timeline.Timeline        // Optional: activity timeline of the user
+	UsageStats               *usagestats.Stats         // Optional: statistics of the user's own usage
+	MeetingNotes             *meetingnotes.Notes       // Optional: structured meeting memos of the meeting parrot
+	Checklists               *checklist.Checklists     // Optional: persistent checklists of the checklist parrot
+	Habits                   *habit.Habits             // Optional: habits coached by the general parrot
+	Toolpack                 *toolpack.Toolpack        // Optional: time, weather and unit tools of the general parrot
+	Expenses                 *expense.Expenses         // Optional: expenses recorded by the expense parrot
+	HomeAssistant            *homeassistant.Home       // Optional: Home Assistant instance controlled by the home parrot
+	Mailbox                  *mailbox.Mailbox          // Optional: IMAP accounts read by the mail parrot
+	Reminders                *reminder.Reminders       // Optional: reminder escalations of the schedule parrot
+	BlockBudgets             *blockbudget.Budgets      // Optional: default cost guardrails of users' blocks
+	IntentTaxonomy           *intenttaxonomy.Taxonomy  // Optional: admin-managed chat intents of the router
+	FewShots                 *fewshot.Library          // Optional: few-shot examples of the router and experts
+	Canaries                 *canary.Canaries          // Optional: canary rollout of new experts
+	FeatureFlags             *featureflag.Flags        // Optional: gates the orchestrator, Evolution Mode and canaries
+	Shadows                  *shadow.Shadows           // Optional: shadow runs of prompt and model changes
+	LoadShedder              *middleware.LoadShedder   // Optional: sheds chat requests under resource pressure
+	ToolPolicies             *toolpolicy.Policies      // Optional: tools of the Geek Mode workspaces
+	MCPServers               *mcpserver.Servers        // Optional: MCP servers users register for Geek Mode
+	GitHub                   *github.GitHub            // Optional: GitHub tools of Geek Mode
+	Webhooks                 *webhook.Registry         // Optional: signed callbacks of block events
+	EvolutionTasks           *evolutiontask.Tasks      // Optional: backlog of approved Evolution tasks
+	RetrievalScopes          *retrievalscope.Scopes    // Optional: retrieval scope of conversations
+	DocumentChats            *documentchat.Documents   // Optional: conversations bound to a single document
+	LLMRegistry              *llmregistry.Registry     // Optional: LLM provider and model of each agent type
+	Quotas                   *aistats.QuotaService     // Optional: monthly token and cost quotas of users
+	WorkspaceQuota           *geekworkspace.Quota      // Optional: disk quota and garbage collection of the Geek Mode workspaces
+	ActionAudit              *actionaudit.Audit        // Optional: append-only audit log of the Geek and Evolution Mode tool calls
+	EmbeddingRunner          *embeddingrunner.Runner   // Optional: background embedding of memos, reindexed by admins
+	RunnerBackends           aichat.ModeRunnerBackends // Agent runner backends of Geek and Evolution modes
+	persister                *aistats.Persister        // session stats async persister
+	enrichmentTrigger        *enrichment.Trigger       // Async enrichment trigger
+	chatHandler              aichat.Handler            // Cached chat handler (created once)
+	activeChats              aichat.ActiveChats        // Running chats, canceled by StopChat
+	modelLLMs                sync.Map                  // LLM services of the models tried by shadows
 	routerServiceMu          sync.RWMutex
 	chatEventBusMu           sync.RWMutex
 	contextBuilderMu         sync.RWMutex
@@ -208,6 +286,8 @@ func (s *AIService) getRouterService() *routing.Service {
 		RoutingMatcher:  routingMatcher,
 		SemanticMatcher: semanticMatcher,
 	})
+	s.loadIntentTaxonomy(context.Background(), s.routerService)
+	s.loadFewShots(context.Background(), s.routerService)
 
 	return s.routerService
 }
@@ -277,6 +357,28 @@ func (s *AIService) getAgentFactory() *aichat.AgentFactory {
 		s.AdaptiveRetriever,
 		s.Store,
 	)
+	factory.SetHTTPActions(s.HTTPActions)
+	factory.SetWebFetch(s.WebFetch)
+	factory.SetEntityGraph(s.EntityGraph)
+	factory.SetContacts(s.Contacts)
+	factory.SetFlashcards(s.Flashcards)
+	factory.SetDocuments(s.Documents)
+	factory.SetSnippets(s.Snippets)
+	factory.SetTimeline(s.Timeline)
+	factory.SetUsageStats(s.UsageStats)
+	factory.SetMeetingNotes(s.MeetingNotes)
+	factory.SetChecklists(s.Checklists)
+	factory.SetHabits(s.Habits)
+	factory.SetToolpack(s.Toolpack)
+	factory.SetExpenses(s.Expenses)
+	factory.SetHomeAssistant(s.HomeAssistant)
+	factory.SetMailbox(s.Mailbox)
+	factory.SetReminders(s.Reminders)
+	factory.SetFewShots(s.FewShots)
+	factory.SetModelLLM(s.shadowModelLLM)
+	if s.LLMRegistry != nil {
+		factory.SetAgentLLM(s.LLMRegistry.LLM)
+	}
 
 	// Initialize UniversalParrot if configured
 	if s.UniversalParrotConfig != nil && s.UniversalParrotConfig.Enabled {
@@ -285,6 +387,12 @@ func (s *AIService) getAgentFactory() *aichat.AgentFactory {
 				"error", err)
 		} else {
 			slog.Info("AgentFactory initialized successfully")
+			s.registerCanaries(factory)
+			s.registerFeatureFlags(factory)
+			s.registerShadows(factory)
+			if s.LLMRegistry != nil {
+				s.LLMRegistry.SetAgents(append(factory.GetParrotFactory().ListConfigs(), llmregistry.AgentOrchestrator))
+			}
 		}
 	} else {
 		slog.Info("UniversalParrot not enabled, using legacy agent creation")
@@ -0,0 +1,144 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hrygo/divinesense/plugin/actionaudit"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+)
+
+// ListActionAudit lists the tool calls of the Geek and Evolution Mode
+// sessions, latest first. The next page is the one before next_before_id, set
+// while more records may follow.
+func (s *AIService) ListActionAudit(ctx context.Context, req *v1pb.ListActionAuditRequest) (*v1pb.ListActionAuditResponse, error) {
+	if err := s.requireActionAuditAdmin(ctx); err != nil {
+		return nil, err
+	}
+	find, err := convertActionAuditFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.ActionAudit.List(ctx, find)
+	if err != nil {
+		return nil, actionAuditError(err, "failed to list audit records")
+	}
+	resp := &v1pb.ListActionAuditResponse{}
+	for _, record := range records {
+		resp.Records = append(resp.Records, convertActionAuditRecord(record))
+	}
+	if len(records) > 0 && len(records) == find.Limit {
+		resp.NextBeforeId = records[len(records)-1].ID
+	}
+	return resp, nil
+}
+
+// ExportActionAudit exports the matching tool calls, latest first, as CSV or
+// JSON Lines.
+func (s *AIService) ExportActionAudit(ctx context.Context, req *v1pb.ExportActionAuditRequest) (*v1pb.ExportActionAuditResponse, error) {
+	if err := s.requireActionAuditAdmin(ctx); err != nil {
+		return nil, err
+	}
+	find, err := convertActionAuditFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	format, contentType := actionaudit.FormatCSV, "text/csv; charset=utf-8"
+	switch req.Format {
+	case v1pb.ActionAuditExportFormat_ACTION_AUDIT_EXPORT_FORMAT_UNSPECIFIED, v1pb.ActionAuditExportFormat_ACTION_AUDIT_EXPORT_FORMAT_CSV:
+	case v1pb.ActionAuditExportFormat_ACTION_AUDIT_EXPORT_FORMAT_JSONL:
+		format, contentType = actionaudit.FormatJSONL, "application/x-ndjson"
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "format must be csv or jsonl")
+	}
+
+	var buf bytes.Buffer
+	if err := s.ActionAudit.Export(ctx, &buf, find, format); err != nil {
+		return nil, actionAuditError(err, "failed to export audit records")
+	}
+	return &v1pb.ExportActionAuditResponse{
+		Data:        buf.Bytes(),
+		Filename:    fmt.Sprintf("divinesense-geek-audit-%s.%s", time.Now().Format("20060102-150405"), format),
+		ContentType: contentType,
+	}, nil
+}
+
+// requireActionAuditAdmin checks that the audit log is configured and that
+// the current user is an admin.
+func (s *AIService) requireActionAuditAdmin(ctx context.Context) error {
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, "unauthorized")
+	}
+	if !isSuperUser(user) {
+		return status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+	if s.ActionAudit == nil {
+		return status.Errorf(codes.Unavailable, "the action audit log is not available")
+	}
+	return nil
+}
+
+// convertActionAuditFilter converts the filters of a request; a nil filter
+// selects all the records.
+func convertActionAuditFilter(filter *v1pb.ActionAuditFilter) (*actionaudit.Find, error) {
+	if filter == nil {
+		return &actionaudit.Find{}, nil
+	}
+	switch {
+	case filter.UserId < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user_id")
+	case filter.From < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid from")
+	case filter.To < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid to")
+	case filter.BeforeId < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid before_id")
+	case filter.Limit < 0 || filter.Limit > 500:
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 1 and 500")
+	}
+	return &actionaudit.Find{
+		UserID:    filter.UserId,
+		Mode:      filter.Mode,
+		SessionID: filter.SessionId,
+		Tool:      filter.Tool,
+		Status:    filter.Status,
+		From:      filter.From,
+		To:        filter.To,
+		BeforeID:  filter.BeforeId,
+		Limit:     int(filter.Limit),
+	}, nil
+}
+
+// actionAuditError maps the errors of the audit log to statuses.
+func actionAuditError(err error, msg string) error {
+	if errors.Is(err, actionaudit.ErrInvalid) {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	slog.Error(msg, "error", err)
+	return status.Errorf(codes.Internal, "%s", msg)
+}
+
+func convertActionAuditRecord(record *actionaudit.Record) *v1pb.ActionAuditRecord {
+	return &v1pb.ActionAuditRecord{
+		Id:         record.ID,
+		UserId:     record.UserID,
+		Mode:       record.Mode,
+		SessionId:  record.SessionID,
+		Tool:       record.Tool,
+		ToolId:     record.ToolID,
+		Input:      record.Input,
+		FilePaths:  record.FilePaths,
+		Status:     record.Status,
+		Output:     record.Output,
+		DurationMs: record.DurationMs,
+		CreatedTs:  record.CreatedTs,
+	}
+}
@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hrygo/divinesense/plugin/actionaudit"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+)
+
+func TestActionAuditUnauthenticated(t *testing.T) {
+	_, err := (&AIService{}).ListActionAudit(context.Background(), &v1pb.ListActionAuditRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = (&AIService{}).ExportActionAudit(context.Background(), &v1pb.ExportActionAuditRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestConvertActionAuditFilter(t *testing.T) {
+	find, err := convertActionAuditFilter(nil)
+	require.NoError(t, err)
+	assert.Equal(t, &actionaudit.Find{}, find)
+
+	find, err = convertActionAuditFilter(&v1pb.ActionAuditFilter{UserId: 2, Mode: "geek", From: 10, To: 20, BeforeId: 7, Limit: 50})
+	require.NoError(t, err)
+	assert.Equal(t, &actionaudit.Find{UserID: 2, Mode: "geek", From: 10, To: 20, BeforeID: 7, Limit: 50}, find)
+
+	for _, filter := range []*v1pb.ActionAuditFilter{{UserId: -1}, {From: -1}, {BeforeId: -1}, {Limit: 501}} {
+		_, err := convertActionAuditFilter(filter)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), filter.String())
+	}
+}
+
+func TestActionAuditError(t *testing.T) {
+	assert.Equal(t, codes.InvalidArgument, status.Code(actionAuditError(fmt.Errorf("%w: bad mode", actionaudit.ErrInvalid), "failed")))
+	assert.Equal(t, codes.Internal, status.Code(actionAuditError(fmt.Errorf("boom"), "failed")))
+}
@@ -0,0 +1,153 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/hrygo/divinesense/internal/profile"
+	"github.com/hrygo/divinesense/plugin/blockbudget"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/proto/gen/api/v1/apiv1connect"
+	"github.com/hrygo/divinesense/server/affinity"
+	"github.com/hrygo/divinesense/store"
+)
+
+// forwardedRequestHeaders are the request headers forwarded to the instance
+// holding a session, so it authenticates the user like the local one would.
+var forwardedRequestHeaders = []string{"Authorization", "Cookie", "User-Agent", "X-Forwarded-For", blockMaxTokensHeader, blockMaxCostUSDHeader}
+
+// sessionRegistry returns the registry of the CC sessions of the instances:
+// Redis when configured, else the PostgreSQL database, nil on other drivers.
+func sessionRegistry(profile *profile.Profile, store *store.Store) affinity.Registry {
+	if profile.AffinityRedisURL != "" {
+		registry, err := affinity.NewRedisRegistry(profile.AffinityRedisURL)
+		if err != nil {
+			slog.Warn("CC session affinity disabled", "error", err)
+			return nil
+		}
+		return registry
+	}
+	if profile.Driver != "postgres" {
+		slog.Warn("CC session affinity disabled: it needs DIVINESENSE_AFFINITY_REDIS_URL or PostgreSQL", "driver", profile.Driver)
+		return nil
+	}
+	return affinity.NewDBRegistry(store.GetDriver().GetDB())
+}
+
+// sessionPeer returns the other instance holding the CC session of a chat
+// request, or nil when the request is handled locally.
+func (s *APIV1Service) sessionPeer(ctx context.Context, header http.Header, conversationID int32, ccMode bool) *affinity.Instance {
+	if !ccMode || header.Get(affinity.ForwardedHeader) != "" {
+		return nil
+	}
+	return s.SessionAffinity.Peer(ctx, conversationID)
+}
+
+// peerClient returns an AI service client for peer.
+func (s *APIV1Service) peerClient(peer *affinity.Instance) apiv1connect.AIServiceClient {
+	return apiv1connect.NewAIServiceClient(http.DefaultClient, peer.URL)
+}
+
+// forwardHeaders copies the credentials of the original request to the forwarded one.
+func (s *APIV1Service) forwardHeaders(from, to http.Header) {
+	for _, name := range forwardedRequestHeaders {
+		if v := from.Values(name); len(v) > 0 {
+			to[http.CanonicalHeaderKey(name)] = v
+		}
+	}
+	to.Set(affinity.ForwardedHeader, s.SessionAffinity.Self().ID)
+}
+
+// chatResponseSender is the response stream of a chat transport.
+type chatResponseSender interface {
+	Send(resp *v1pb.ChatResponse) error
+}
+
+// routeChat applies the request headers shared by the chat transports: it
+// returns the context of the chat, tightened by the block budget headers, and
+// the other instance holding the CC session of the chat, nil when the chat is
+// handled locally. The instance handling a CC chat is set on responseHeader.
+func (s *APIV1Service) routeChat(ctx context.Context, header, responseHeader http.Header, req *v1pb.ChatRequest) (context.Context, *affinity.Instance, error) {
+	budget, err := requestBlockBudget(header)
+	if err != nil {
+		return ctx, nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if budget != nil {
+		ctx = blockbudget.WithRequestBudget(ctx, budget)
+	}
+
+	// Follow-up messages of a CC session go to the instance running its CLI
+	ccMode := req.GeekMode || req.EvolutionMode
+	if peer := s.sessionPeer(ctx, header, req.ConversationId, ccMode); peer != nil {
+		responseHeader.Set(affinity.InstanceHeader, peer.ID)
+		return ctx, peer, nil
+	}
+	if self := s.SessionAffinity.Self(); self != nil && ccMode {
+		responseHeader.Set(affinity.InstanceHeader, self.ID)
+	}
+	return ctx, nil, nil
+}
+
+// forwardChat relays a chat request to the instance holding its CC session.
+// It returns false when the peer is unreachable before streaming anything, in
+// which case the request is handled locally and the session moves here.
+func (s *APIV1Service) forwardChat(ctx context.Context, peer *affinity.Instance, header http.Header, req *v1pb.ChatRequest, stream chatResponseSender) (bool, error) {
+	forwarded := connect.NewRequest(req)
+	s.forwardHeaders(header, forwarded.Header())
+
+	peerStream, err := s.peerClient(peer).Chat(ctx, forwarded)
+	if err != nil {
+		return s.peerFailed(peer, req.ConversationId, err)
+	}
+	defer peerStream.Close()
+
+	sent := false
+	for peerStream.Receive() {
+		if err := stream.Send(peerStream.Msg()); err != nil {
+			return true, err
+		}
+		sent = true
+	}
+	if err := peerStream.Err(); err != nil {
+		if !sent {
+			return s.peerFailed(peer, req.ConversationId, err)
+		}
+		return true, err
+	}
+	return true, nil
+}
+
+// forwardStopChat relays a stop request to the instance holding the CC session.
+func (s *APIV1Service) forwardStopChat(ctx context.Context, peer *affinity.Instance, req *connect.Request[v1pb.StopChatRequest]) (*connect.Response[emptypb.Empty], bool, error) {
+	forwarded := connect.NewRequest(req.Msg)
+	s.forwardHeaders(req.Header(), forwarded.Header())
+
+	resp, err := s.peerClient(peer).StopChat(ctx, forwarded)
+	if err != nil {
+		handled, err := s.peerFailed(peer, req.Msg.ConversationId, err)
+		return nil, handled, err
+	}
+	return resp, true, nil
+}
+
+// peerFailed reports whether a failed forward is final. Only an unreachable
+// peer falls back to local handling; errors returned by the peer stand.
+func (s *APIV1Service) peerFailed(peer *affinity.Instance, conversationID int32, err error) (bool, error) {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) && connectErr.Code() != connect.CodeUnavailable {
+		return true, err
+	}
+	slog.Warn("affinity: session owner unreachable, handling locally",
+		"conversation_id", conversationID,
+		"owner", peer.ID,
+		"error", err,
+	)
+	return false, nil
+}
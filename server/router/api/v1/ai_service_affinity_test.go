@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hrygo/divinesense/internal/profile"
+	"github.com/hrygo/divinesense/plugin/blockbudget"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/proto/gen/api/v1/apiv1connect"
+	"github.com/hrygo/divinesense/server/affinity"
+	"github.com/hrygo/divinesense/server/auth"
+	"github.com/hrygo/divinesense/store"
+)
+
+// ownerRegistry is a registry where one instance holds every session.
+type ownerRegistry struct {
+	owner *affinity.Instance
+}
+
+func (r *ownerRegistry) Claim(context.Context, int32, *affinity.Instance) error { return nil }
+
+func (r *ownerRegistry) Owner(context.Context, int32) (*affinity.Instance, error) {
+	return r.owner, nil
+}
+
+// peerChatHandler answers the chats forwarded to a peer instance.
+type peerChatHandler struct {
+	apiv1connect.UnimplementedAIServiceHandler
+	header http.Header
+}
+
+func (h *peerChatHandler) Chat(_ context.Context, req *connect.Request[v1pb.ChatRequest], stream *connect.ServerStream[v1pb.ChatResponse]) error {
+	h.header = req.Header().Clone()
+	return stream.Send(&v1pb.ChatResponse{Content: "from peer " + req.Msg.Message, Done: true})
+}
+
+func TestRouteChat(t *testing.T) {
+	s := &APIV1Service{}
+	req := &v1pb.ChatRequest{ConversationId: 7, GeekMode: true}
+
+	header := http.Header{}
+	header.Set(blockMaxTokensHeader, "many")
+	_, _, err := s.routeChat(context.Background(), header, http.Header{}, req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	header.Set(blockMaxTokensHeader, "1000")
+	responseHeader := http.Header{}
+	ctx, peer, err := s.routeChat(context.Background(), header, responseHeader, req)
+	require.NoError(t, err)
+	assert.Nil(t, peer)
+	assert.Equal(t, 1000, blockbudget.RequestBudget(ctx).MaxTokens)
+	assert.Empty(t, responseHeader.Get(affinity.InstanceHeader))
+
+	// CC chats of sessions held elsewhere go to the holder
+	owner := &affinity.Instance{ID: "b", URL: "http://b"}
+	s.SessionAffinity = affinity.NewRouter(&affinity.Instance{ID: "a", URL: "http://a"}, &ownerRegistry{owner: owner})
+	_, peer, err = s.routeChat(context.Background(), http.Header{}, responseHeader, req)
+	require.NoError(t, err)
+	assert.Equal(t, owner, peer)
+	assert.Equal(t, "b", responseHeader.Get(affinity.InstanceHeader))
+
+	responseHeader = http.Header{}
+	_, peer, err = s.routeChat(context.Background(), http.Header{}, responseHeader, &v1pb.ChatRequest{ConversationId: 7})
+	require.NoError(t, err)
+	assert.Nil(t, peer)
+	assert.Empty(t, responseHeader.Get(affinity.InstanceHeader))
+}
+
+func TestChatStreamForwardsToSessionHolder(t *testing.T) {
+	peerHandler := &peerChatHandler{}
+	mux := http.NewServeMux()
+	mux.Handle(apiv1connect.NewAIServiceHandler(peerHandler))
+	peerServer := httptest.NewServer(mux)
+	defer peerServer.Close()
+
+	s := &APIV1Service{
+		Secret:          "secret",
+		Profile:         &profile.Profile{},
+		AIService:       &AIService{},
+		SessionAffinity: affinity.NewRouter(&affinity.Instance{ID: "a", URL: "http://a"}, &ownerRegistry{owner: &affinity.Instance{ID: "b", URL: peerServer.URL}}),
+	}
+
+	// A request authenticated by a stream ticket carries no Authorization header
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/ai/chat/stream?message=hi&conversation_id=7&geek_mode=true", nil), rec)
+	c.Set(restUserContextKey, &store.User{ID: 3, Username: "jo", Role: store.RoleUser, RowStatus: store.Normal})
+	require.NoError(t, s.ChatStream(c))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "b", rec.Header().Get(affinity.InstanceHeader))
+	assert.Contains(t, rec.Body.String(), "from peer hi")
+	assert.Equal(t, "a", peerHandler.header.Get(affinity.ForwardedHeader))
+
+	claims, err := auth.ParseAccessTokenV2(strings.TrimPrefix(peerHandler.header.Get("Authorization"), "Bearer "), []byte("secret"))
+	require.NoError(t, err)
+	assert.Equal(t, "3", claims.Subject)
+}
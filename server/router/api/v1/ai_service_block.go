@@ -3,6 +3,7 @@ package v1
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"strings"
 	"time"
@@ -19,6 +20,10 @@ import (
 // emptyMetadata is the default empty JSON object for metadata.
 const emptyMetadata = "{}"
 
+// LockedMetadataKey is set in the metadata of the blocks whose contents are
+// withheld because their conversation is locked.
+const LockedMetadataKey = "locked"
+
 // ListBlocks retrieves blocks for a conversation with pagination support.
 func (s *AIService) ListBlocks(ctx context.Context, req *v1pb.ListBlocksRequest) (*v1pb.ListBlocksResponse, error) {
 	// Parameter validation
@@ -238,6 +243,9 @@ func (s *AIService) CreateBlock(ctx context.Context, req *v1pb.CreateBlockReques
 		CreatedTs:      now,
 		UpdatedTs:      now,
 	})
+	if errors.Is(err, store.ErrInvalidBlockMetadata) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create block: %v", err)
 	}
@@ -307,6 +315,9 @@ func (s *AIService) UpdateBlock(ctx context.Context, req *v1pb.UpdateBlockReques
 	}
 
 	updated, err := s.Store.UpdateAIBlock(ctx, update)
+	if errors.Is(err, store.ErrInvalidBlockMetadata) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to update block: %v", err)
 	}
@@ -436,6 +447,7 @@ func convertBlockFromStore(b *store.AIBlock) *v1pb.Block {
 		AssistantTimestamp: b.AssistantTimestamp,
 		CcSessionId:        b.CCSessionID,
 		Status:             convertBlockStatusToProto(b.Status),
+		ModelVersion:       b.ModelVersion,
 		CreatedTs:          b.CreatedTs,
 		UpdatedTs:          b.UpdatedTs,
 	}
@@ -473,7 +485,16 @@ func convertBlockFromStore(b *store.AIBlock) *v1pb.Block {
 	pbBlock.BranchPath = b.BranchPath
 
 	// Convert metadata to JSON string
-	pbBlock.Metadata = formatMetadata(b.Metadata)
+	metadata := b.Metadata
+	if b.Locked {
+		// The contents are sealed by a locked conversation that is not unlocked
+		metadata = make(map[string]any, len(b.Metadata)+1)
+		for k, v := range b.Metadata {
+			metadata[k] = v
+		}
+		metadata[LockedMetadataKey] = true
+	}
+	pbBlock.Metadata = formatMetadata(metadata)
 
 	return pbBlock
 }
@@ -564,6 +585,8 @@ func convertBlockStatusToProto(bs store.AIBlockStatus) v1pb.BlockStatus {
 		return v1pb.BlockStatus_BLOCK_STATUS_COMPLETED
 	case store.AIBlockStatusError:
 		return v1pb.BlockStatus_BLOCK_STATUS_ERROR
+	case store.AIBlockStatusInterrupted:
+		return v1pb.BlockStatus_BLOCK_STATUS_INTERRUPTED
 	default:
 		return v1pb.BlockStatus_BLOCK_STATUS_UNSPECIFIED
 	}
@@ -580,6 +603,8 @@ func convertBlockStatusFromProto(bs v1pb.BlockStatus) store.AIBlockStatus {
 		return store.AIBlockStatusCompleted
 	case v1pb.BlockStatus_BLOCK_STATUS_ERROR:
 		return store.AIBlockStatusError
+	case v1pb.BlockStatus_BLOCK_STATUS_INTERRUPTED:
+		return store.AIBlockStatusInterrupted
 	default:
 		return store.AIBlockStatusPending // Default
 	}
@@ -689,13 +714,17 @@ func (s *AIService) ForkBlock(ctx context.Context, req *v1pb.ForkBlockRequest) (
 		return nil, status.Errorf(codes.Internal, "failed to fork block: %v", err)
 	}
 
+	var forkType string
+	if fork, ok := newBlock.Fork(); ok {
+		forkType = fork.Type
+	}
 	slog.Info("Forked block",
 		"parent_id", req.Id,
 		"new_block_id", newBlock.ID,
 		"reason", reason,
 		"user_id", user.ID,
 		"replaced_inputs", len(replaceUserInputs) > 0,
-		"fork_type", newBlock.Metadata["fork_type"],
+		"fork_type", forkType,
 	)
 
 	return convertBlockFromStore(newBlock), nil
@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/store"
+)
+
+// blockChangesOverlapMs is how far before since_ts changes are looked up
+// again. Block updates are timestamped when their transaction starts and
+// events when they are queued, so a change committed shortly after a sync can
+// carry an earlier time; the overlap catches it. Changes are idempotent for
+// clients, so seeing one twice is harmless.
+const blockChangesOverlapMs = 10_000
+
+// GetBlockChanges returns the blocks of a conversation of the current user
+// created or updated since since_ts (milliseconds, 0 for all), and of existing
+// blocks only the events appended since then.
+func (s *AIService) GetBlockChanges(ctx context.Context, req *v1pb.GetBlockChangesRequest) (*v1pb.GetBlockChangesResponse, error) {
+	if req.ConversationId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "conversation_id is required")
+	}
+	if req.SinceTs < 0 {
+		return nil, status.Error(codes.InvalidArgument, "since_ts must be a timestamp in milliseconds")
+	}
+
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthorized")
+	}
+	conversations, err := s.Store.ListAIConversations(ctx, &store.FindAIConversation{
+		ID:        &req.ConversationId,
+		CreatorID: &user.ID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get conversation: %v", err)
+	}
+	if len(conversations) == 0 {
+		return nil, status.Errorf(codes.NotFound, "conversation not found")
+	}
+
+	since := req.SinceTs
+	find := &store.FindAIBlock{ConversationID: &req.ConversationId}
+	if since > 0 {
+		after := since - blockChangesOverlapMs
+		find.UpdatedAfter = &after
+	}
+	blocks, err := s.Store.ListAIBlocks(ctx, find)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list block changes: %v", err)
+	}
+	uids, err := s.Store.ListAIBlockUIDs(ctx, req.ConversationId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list block uids: %v", err)
+	}
+
+	resp := &v1pb.GetBlockChangesResponse{BlockUids: uids, SyncTs: since}
+	for _, change := range blockChanges(blocks, since) {
+		resp.Changes = append(resp.Changes, &v1pb.BlockChange{
+			Block:       convertBlockFromStore(change.block),
+			Created:     change.created,
+			EventOffset: int32(change.eventOffset),
+		})
+		resp.SyncTs = max(resp.SyncTs, change.block.UpdatedTs)
+	}
+	return resp, nil
+}
+
+// blockChange is a changed block, trimmed to the events clients miss.
+type blockChange struct {
+	block       *store.AIBlock
+	created     bool
+	eventOffset int
+}
+
+// blockChanges trims blocks changed since a sync to what clients that synced
+// at since miss: whole new blocks, and the events appended to existing ones.
+// Both are compared with the same overlap as the lookup of blocks.
+func blockChanges(blocks []*store.AIBlock, since int64) []*blockChange {
+	cutoff := since - blockChangesOverlapMs
+	changes := make([]*blockChange, 0, len(blocks))
+	for _, block := range blocks {
+		change := &blockChange{block: block, created: since == 0 || block.CreatedTs > cutoff}
+		if !change.created {
+			change.eventOffset = len(block.EventStream)
+			for i, event := range block.EventStream {
+				if event.Timestamp > cutoff {
+					change.eventOffset = i
+					break
+				}
+			}
+			trimmed := *block
+			trimmed.EventStream = block.EventStream[change.eventOffset:]
+			change.block = &trimmed
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
@@ -0,0 +1,66 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/store"
+)
+
+func TestBlockChanges(t *testing.T) {
+	const since = int64(1_000_000)
+	existing := &store.AIBlock{
+		UID:       "old",
+		CreatedTs: since - 60_000,
+		UpdatedTs: since + 500,
+		EventStream: []store.BlockEvent{
+			{Type: "thinking", Timestamp: since - 50_000},
+			{Type: "tool_use", Timestamp: since - blockChangesOverlapMs + 1},
+			{Type: "answer", Timestamp: since + 400},
+		},
+	}
+	created := &store.AIBlock{
+		UID:         "new",
+		CreatedTs:   since + 100,
+		UpdatedTs:   since + 900,
+		EventStream: []store.BlockEvent{{Type: "thinking", Timestamp: since + 200}},
+	}
+
+	changes := blockChanges([]*store.AIBlock{existing, created}, since)
+	require.Len(t, changes, 2)
+
+	// Existing blocks only carry the events appended since the sync, overlap included.
+	assert.False(t, changes[0].created)
+	assert.Equal(t, 1, changes[0].eventOffset)
+	assert.Equal(t, existing.EventStream[1:], changes[0].block.EventStream)
+	assert.Len(t, existing.EventStream, 3, "the stored block is left untouched")
+
+	assert.True(t, changes[1].created)
+	assert.Equal(t, 0, changes[1].eventOffset)
+	assert.Equal(t, created.EventStream, changes[1].block.EventStream)
+
+	// A block updated without new events, e.g. completed, has none to append.
+	completed := &store.AIBlock{CreatedTs: since - 60_000, UpdatedTs: since + 10, EventStream: existing.EventStream[:1]}
+	changes = blockChanges([]*store.AIBlock{completed}, since)
+	assert.Equal(t, 1, changes[0].eventOffset)
+	assert.Empty(t, changes[0].block.EventStream)
+
+	// A first sync returns whole blocks.
+	changes = blockChanges([]*store.AIBlock{existing}, 0)
+	assert.True(t, changes[0].created)
+	assert.Len(t, changes[0].block.EventStream, 3)
+}
+
+func TestGetBlockChangesValidation(t *testing.T) {
+	s := &AIService{}
+	_, err := s.GetBlockChanges(context.Background(), &v1pb.GetBlockChangesRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.GetBlockChanges(context.Background(), &v1pb.GetBlockChangesRequest{ConversationId: 1, SinceTs: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
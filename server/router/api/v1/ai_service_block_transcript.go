@@ -0,0 +1,289 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hrygo/divinesense/ai/core/retrieval"
+	"github.com/hrygo/divinesense/plugin/documentchat"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
+	"github.com/hrygo/divinesense/store"
+)
+
+const (
+	// transcriptLineLen bounds the tool one-liners, in runes.
+	transcriptLineLen = 160
+	// transcriptOutputLen bounds the tool inputs and outputs of full transcripts.
+	transcriptOutputLen = 2000
+)
+
+// GetBlockTranscript returns the transcript of a block of the current user,
+// minimal by default.
+func (s *AIService) GetBlockTranscript(ctx context.Context, req *v1pb.GetBlockTranscriptRequest) (*v1pb.BlockTranscript, error) {
+	if req.Id == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	detail := req.Detail
+	switch detail {
+	case v1pb.TranscriptDetail_TRANSCRIPT_DETAIL_UNSPECIFIED:
+		detail = v1pb.TranscriptDetail_TRANSCRIPT_DETAIL_MINIMAL
+	case v1pb.TranscriptDetail_TRANSCRIPT_DETAIL_MINIMAL, v1pb.TranscriptDetail_TRANSCRIPT_DETAIL_FULL:
+	default:
+		return nil, status.Error(codes.InvalidArgument, "detail must be minimal or full")
+	}
+
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthorized")
+	}
+	block, err := s.Store.GetAIBlock(ctx, req.Id)
+	if err != nil || block == nil {
+		return nil, status.Errorf(codes.NotFound, "block not found")
+	}
+	conversations, err := s.Store.ListAIConversations(ctx, &store.FindAIConversation{
+		ID:        &block.ConversationID,
+		CreatorID: &user.ID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get conversation: %v", err)
+	}
+	if len(conversations) == 0 {
+		return nil, status.Errorf(codes.NotFound, "block not found")
+	}
+
+	transcript := blockTranscript(block, detail)
+	if source := transcript.Source; source != nil && detail == v1pb.TranscriptDetail_TRANSCRIPT_DETAIL_FULL && s.DocumentChats != nil {
+		doc, err := s.DocumentChats.Open(ctx, user.ID, &documentchat.Binding{Kind: source.Kind, UID: source.Uid})
+		if err != nil {
+			// The cited passages still stand for the document.
+			slog.Warn("failed to load transcript source", "block_id", block.ID, "error", err)
+		} else {
+			source.Text = doc.Text
+			transcript.Markdown = transcriptMarkdown(transcript)
+		}
+	}
+	return transcript, nil
+}
+
+// blockTranscript collapses the events of a block: streamed chunks are
+// joined, tool calls are paired with their results, and bookkeeping events
+// such as progress and session stats are dropped.
+func blockTranscript(block *store.AIBlock, detail v1pb.TranscriptDetail) *v1pb.BlockTranscript {
+	full := detail == v1pb.TranscriptDetail_TRANSCRIPT_DETAIL_FULL
+	t := &v1pb.BlockTranscript{
+		BlockId:        block.ID,
+		ConversationId: block.ConversationID,
+		Detail:         detail,
+		Status:         string(block.Status),
+		UserInputs:     make([]string, 0, len(block.UserInputs)),
+		Tools:          []*v1pb.TranscriptTool{},
+		Error:          block.ErrorMessage,
+		CreatedTs:      block.CreatedTs,
+	}
+	for _, input := range block.UserInputs {
+		t.UserInputs = append(t.UserInputs, input.Content)
+	}
+
+	var answer strings.Builder
+	// pending are the calls awaiting their result, by tool ID.
+	pending := map[string]*v1pb.TranscriptTool{}
+	var unpaired []*v1pb.TranscriptTool
+	appendText := func(entryType, content string) {
+		if !full || content == "" {
+			return
+		}
+		if n := len(t.Entries); n > 0 && t.Entries[n-1].Type == entryType {
+			t.Entries[n-1].Content += content
+			return
+		}
+		t.Entries = append(t.Entries, &v1pb.TranscriptEntry{Type: entryType, Content: content})
+	}
+	for _, event := range block.EventStream {
+		switch event.Type {
+		case "thinking":
+			appendText("thinking", event.Content)
+		case "answer", "content":
+			answer.WriteString(event.Content)
+			appendText("answer", event.Content)
+		case "error":
+			if t.Error == "" {
+				t.Error = event.Content
+			}
+			appendText("error", event.Content)
+		case "tool_use":
+			tool := &v1pb.TranscriptTool{Name: metaString(event.Meta, "tool_name")}
+			if tool.Name == "" {
+				tool.Name = "tool"
+			}
+			tool.Input = metaString(event.Meta, "input_summary")
+			if tool.Input == "" {
+				tool.Input = event.Content
+			}
+			if id := metaString(event.Meta, "tool_id"); id != "" {
+				pending[id] = tool
+			} else {
+				unpaired = append(unpaired, tool)
+			}
+			t.Tools = append(t.Tools, tool)
+			if full {
+				t.Entries = append(t.Entries, &v1pb.TranscriptEntry{Type: "tool", Tool: tool})
+			}
+		case "tool_result":
+			tool := pending[metaString(event.Meta, "tool_id")]
+			if tool != nil {
+				delete(pending, metaString(event.Meta, "tool_id"))
+			} else if len(unpaired) > 0 {
+				tool, unpaired = unpaired[0], unpaired[1:]
+			} else {
+				// A result without its call, e.g. after dropped events.
+				tool = &v1pb.TranscriptTool{Name: metaString(event.Meta, "tool_name")}
+				t.Tools = append(t.Tools, tool)
+				if full {
+					t.Entries = append(t.Entries, &v1pb.TranscriptEntry{Type: "tool", Tool: tool})
+				}
+			}
+			tool.Output = metaString(event.Meta, "output_summary")
+			if tool.Output == "" {
+				tool.Output = event.Content
+			}
+			tool.Failed = metaString(event.Meta, "status") == "error" || event.Meta["is_error"] == true
+			if msg := metaString(event.Meta, "error_msg"); tool.Failed && msg != "" {
+				tool.Output = msg
+			}
+			tool.DurationMs = metaInt(event.Meta, "duration_ms")
+		}
+	}
+
+	t.Answer = block.AssistantContent
+	if t.Answer == "" {
+		t.Answer = answer.String()
+	} else if full && answer.Len() == 0 {
+		// The answer was not streamed as events.
+		t.Entries = append(t.Entries, &v1pb.TranscriptEntry{Type: "answer", Content: t.Answer})
+	}
+	for _, tool := range t.Tools {
+		tool.Line = toolLine(tool)
+		if full {
+			tool.Input = aichat.TruncateString(tool.Input, transcriptOutputLen)
+			tool.Output = aichat.TruncateString(tool.Output, transcriptOutputLen)
+		} else {
+			tool.Input, tool.Output = "", ""
+		}
+	}
+	if citations, ok := block.Citations(); ok && citations.Document != nil {
+		doc := citations.Document
+		t.Source = &v1pb.TranscriptSource{Kind: doc.Kind, Uid: doc.UID, Title: doc.Title}
+		for _, span := range doc.Spans {
+			t.Source.Passages = append(t.Source.Passages, &v1pb.TranscriptPassage{
+				Start: int32(span.Start),
+				End:   int32(span.End),
+				Text:  span.Text,
+			})
+		}
+	}
+	t.Markdown = transcriptMarkdown(t)
+	return t
+}
+
+// toolLine summarizes a tool call as "✓ name(input) → output (120ms)".
+func toolLine(tool *v1pb.TranscriptTool) string {
+	mark := "✓"
+	if tool.Failed {
+		mark = "✗"
+	}
+	line := fmt.Sprintf("%s %s", mark, tool.Name)
+	if input := oneLine(tool.Input); input != "" {
+		line += "(" + aichat.TruncateString(input, transcriptLineLen/2) + ")"
+	}
+	if output := oneLine(tool.Output); output != "" {
+		line += " → " + output
+	}
+	line = aichat.TruncateString(line, transcriptLineLen)
+	if tool.DurationMs > 0 {
+		line += fmt.Sprintf(" (%dms)", tool.DurationMs)
+	}
+	return line
+}
+
+// transcriptMarkdown renders a transcript.
+func transcriptMarkdown(t *v1pb.BlockTranscript) string {
+	var b strings.Builder
+	for _, input := range t.UserInputs {
+		fmt.Fprintf(&b, "> %s\n\n", strings.ReplaceAll(strings.TrimSpace(input), "\n", "\n> "))
+	}
+	if t.Detail == v1pb.TranscriptDetail_TRANSCRIPT_DETAIL_FULL && len(t.Entries) > 0 {
+		for _, entry := range t.Entries {
+			switch entry.Type {
+			case "thinking":
+				fmt.Fprintf(&b, "_%s_\n\n", oneLine(entry.Content))
+			case "tool":
+				fmt.Fprintf(&b, "- %s\n\n", entry.Tool.Line)
+			case "error":
+				fmt.Fprintf(&b, "**Error:** %s\n\n", strings.TrimSpace(entry.Content))
+			case "answer":
+				fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(entry.Content))
+			}
+		}
+		writeTranscriptSource(&b, t.Source)
+		return strings.TrimSpace(b.String()) + "\n"
+	}
+	for _, tool := range t.Tools {
+		fmt.Fprintf(&b, "- %s\n", tool.Line)
+	}
+	if len(t.Tools) > 0 {
+		b.WriteString("\n")
+	}
+	if answer := strings.TrimSpace(t.Answer); answer != "" {
+		fmt.Fprintf(&b, "%s\n\n", answer)
+	}
+	if t.Error != "" {
+		fmt.Fprintf(&b, "**Error:** %s\n\n", t.Error)
+	}
+	writeTranscriptSource(&b, t.Source)
+	return strings.TrimSpace(b.String()) + "\n"
+}
+
+// writeTranscriptSource renders the source document: the cited passages, by
+// offsets, and the whole document when loaded.
+func writeTranscriptSource(b *strings.Builder, source *v1pb.TranscriptSource) {
+	if source == nil {
+		return
+	}
+	fmt.Fprintf(b, "---\n\n**Source:** %s (%s %s)\n\n", source.Title, source.Kind, source.Uid)
+	for _, passage := range source.Passages {
+		fmt.Fprintf(b, "> %s %s\n\n", retrieval.CitePassage(int(passage.Start), int(passage.End)),
+			strings.ReplaceAll(strings.TrimSpace(passage.Text), "\n", "\n> "))
+	}
+	if text := strings.TrimSpace(source.Text); text != "" {
+		fmt.Fprintf(b, "%s\n\n", text)
+	}
+}
+
+// oneLine collapses whitespace, newlines included.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func metaString(meta map[string]any, key string) string {
+	value, _ := meta[key].(string)
+	return value
+}
+
+// metaInt reads a number of event metadata, a float64 once decoded from JSON.
+func metaInt(meta map[string]any, key string) int64 {
+	switch value := meta[key].(type) {
+	case float64:
+		return int64(value)
+	case int64:
+		return value
+	case int:
+		return int64(value)
+	}
+	return 0
+}
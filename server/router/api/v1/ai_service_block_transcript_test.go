@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/store"
+)
+
+func TestBlockTranscript(t *testing.T) {
+	block := &store.AIBlock{
+		ID:               7,
+		ConversationID:   3,
+		Status:           store.AIBlockStatusCompleted,
+		UserInputs:       []store.UserInput{{Content: "What did I write about Go?"}},
+		AssistantContent: "You wrote two memos about Go.",
+		EventStream: []store.BlockEvent{
+			{Type: "phase_change", Content: "analyzing"},
+			{Type: "thinking", Content: "Let me "},
+			{Type: "thinking", Content: "search."},
+			{Type: "tool_use", Content: `{"query":"go"}`, Meta: map[string]any{"tool_name": "memo_search", "tool_id": "a"}},
+			{Type: "tool_use", Content: `{"days":7}`, Meta: map[string]any{"tool_name": "schedule_query"}},
+			{Type: "tool_result", Content: "2 memos\nfound", Meta: map[string]any{"tool_id": "a", "duration_ms": float64(120)}},
+			{Type: "tool_result", Content: "boom", Meta: map[string]any{"status": "error", "error_msg": "timeout"}},
+			{Type: "answer", Content: "You wrote two "},
+			{Type: "answer", Content: "memos about Go."},
+			{Type: "session_stats", Content: "{}"},
+		},
+	}
+
+	minimal := blockTranscript(block, v1pb.TranscriptDetail_TRANSCRIPT_DETAIL_MINIMAL)
+	assert.Equal(t, []string{"What did I write about Go?"}, minimal.UserInputs)
+	assert.Equal(t, "You wrote two memos about Go.", minimal.Answer)
+	require.Len(t, minimal.Tools, 2)
+	assert.Equal(t, `✓ memo_search({"query":"go"}) → 2 memos found (120ms)`, minimal.Tools[0].Line)
+	assert.Equal(t, `✗ schedule_query({"days":7}) → timeout`, minimal.Tools[1].Line)
+	assert.True(t, minimal.Tools[1].Failed)
+	assert.Empty(t, minimal.Tools[0].Output, "minimal transcripts drop tool outputs")
+	assert.Nil(t, minimal.Entries)
+	assert.Contains(t, minimal.Markdown, "> What did I write about Go?")
+	assert.Contains(t, minimal.Markdown, "- ✓ memo_search")
+	assert.True(t, strings.HasSuffix(minimal.Markdown, "You wrote two memos about Go.\n"))
+
+	full := blockTranscript(block, v1pb.TranscriptDetail_TRANSCRIPT_DETAIL_FULL)
+	require.Len(t, full.Entries, 4)
+	assert.Equal(t, "thinking", full.Entries[0].Type)
+	assert.Equal(t, "Let me search.", full.Entries[0].Content)
+	assert.Equal(t, "memo_search", full.Entries[1].Tool.Name)
+	assert.Equal(t, "2 memos\nfound", full.Entries[1].Tool.Output)
+	assert.Equal(t, "schedule_query", full.Entries[2].Tool.Name)
+	assert.Equal(t, "answer", full.Entries[3].Type)
+	assert.Equal(t, "You wrote two memos about Go.", full.Entries[3].Content)
+	assert.Contains(t, full.Markdown, "_Let me search._")
+}
+
+func TestBlockTranscriptWithoutAnswerEvents(t *testing.T) {
+	block := &store.AIBlock{
+		AssistantContent: "Done.",
+		EventStream: []store.BlockEvent{
+			{Type: "tool_result", Content: "ok", Meta: map[string]any{"tool_name": "bash"}},
+		},
+	}
+
+	full := blockTranscript(block, v1pb.TranscriptDetail_TRANSCRIPT_DETAIL_FULL)
+	require.Len(t, full.Entries, 2)
+	assert.Equal(t, "✓ bash → ok", full.Entries[0].Tool.Line)
+	assert.Equal(t, "answer", full.Entries[1].Type)
+	assert.Equal(t, "Done.", full.Entries[1].Content)
+	assert.Equal(t, "- ✓ bash → ok\n\nDone.\n", full.Markdown)
+}
+
+func TestBlockTranscriptSource(t *testing.T) {
+	block := &store.AIBlock{AssistantContent: "The budget grew by 10% [@12-30]."}
+	update := &store.UpdateAIBlock{}
+	update.SetCitations(&store.CitationsMetadata{Document: &store.DocumentCitation{
+		Kind:  "attachment",
+		UID:   "report",
+		Title: "report.pdf",
+		Spans: []store.CitationSpan{{Start: 12, End: 30, Text: "Budget grew\nby 10%."}},
+	}})
+	block.Metadata = update.Metadata
+
+	transcript := blockTranscript(block, v1pb.TranscriptDetail_TRANSCRIPT_DETAIL_MINIMAL)
+	require.NotNil(t, transcript.Source)
+	assert.Equal(t, "report.pdf", transcript.Source.Title)
+	assert.Equal(t, "The budget grew by 10% [@12-30].\n\n---\n\n**Source:** report.pdf (attachment report)\n\n> [@12-30] Budget grew\n> by 10%.\n",
+		transcript.Markdown)
+
+	// The whole document follows the passages once loaded
+	transcript.Source.Text = "Q3 report.\n\nBudget grew\nby 10%."
+	assert.True(t, strings.HasSuffix(transcriptMarkdown(transcript), "> by 10%.\n\nQ3 report.\n\nBudget grew\nby 10%.\n"))
+
+	assert.Nil(t, blockTranscript(&store.AIBlock{AssistantContent: "Done."}, v1pb.TranscriptDetail_TRANSCRIPT_DETAIL_MINIMAL).Source)
+}
+
+func TestGetBlockTranscriptValidation(t *testing.T) {
+	s := &AIService{}
+	_, err := s.GetBlockTranscript(context.Background(), &v1pb.GetBlockTranscriptRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.GetBlockTranscript(context.Background(), &v1pb.GetBlockTranscriptRequest{Id: 1, Detail: 9})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"strconv"
 	"strings"
@@ -12,9 +13,7 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
-	agentpkg "github.com/hrygo/divinesense/ai/agents"
-	"github.com/hrygo/divinesense/ai/agents/orchestrator"
-	ctxpkg "github.com/hrygo/divinesense/ai/context"
+	"github.com/hrygo/divinesense/plugin/scripting"
 	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
 	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
 	"github.com/hrygo/divinesense/store"
@@ -29,6 +28,9 @@ func (s *AIService) getChatEventBus() *aichat.EventBus {
 		s.chatEventBus = aichat.NewEventBus()
 		s.conversationService = aichat.NewConversationService(s.Store)
 		s.conversationService.Subscribe(s.chatEventBus)
+		if s.EntityGraph != nil {
+			s.chatEventBus.Subscribe(aichat.EventUserMessage, s.indexChatMessageEntities)
+		}
 	}
 
 	return s.chatEventBus
@@ -63,8 +65,12 @@ func (s *AIService) getConversationSummarizer() *aichat.ConversationSummarizer {
 // Chat streams a chat response with AI agents.
 // Emits events for conversation persistence (handled by ConversationService).
 func (s *AIService) Chat(req *v1pb.ChatRequest, stream v1pb.AIService_ChatServer) error {
-	ctx := stream.Context()
+	return s.chat(stream.Context(), req, stream)
+}
 
+// chat streams a chat response to stream, the gRPC one of Chat or the SSE one
+// of the REST endpoint.
+func (s *AIService) chat(ctx context.Context, req *v1pb.ChatRequest, stream aichat.ChatStream) error {
 	if !s.IsEnabled() {
 		return status.Errorf(codes.Unavailable, "AI features are disabled")
 	}
@@ -83,13 +89,33 @@ func (s *AIService) Chat(req *v1pb.ChatRequest, stream v1pb.AIService_ChatServer
 		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded")
 	}
 
+	// Users who used up a monthly quota cannot start new chats
+	if err := s.Quotas.Check(ctx, user.ID); err != nil {
+		return aichat.HandleError(err)
+	}
+
 	chatReq := aichat.ToChatRequest(req)
 	chatReq.UserID = user.ID
+	chatReq.Budget = s.blockBudget(ctx, user.ID)
 
 	if chatReq.Timezone == "" || !aichat.IsValidTimezone(chatReq.Timezone) {
 		chatReq.Timezone = aichat.GetDefaultTimezone()
 	}
 
+	// Admin-defined chat.request hooks may rewrite or reject the message.
+	if s.ScriptHooks.HasHooks(ctx, scripting.TriggerChatRequest) {
+		outcome := s.ScriptHooks.Apply(ctx, scripting.TriggerChatRequest, map[string]any{
+			"content":    chatReq.Message,
+			"agent_type": chatReq.AgentType.String(),
+			"geek_mode":  chatReq.GeekMode,
+			"user_id":    user.ID,
+		})
+		if outcome.Rejected {
+			return status.Errorf(codes.FailedPrecondition, "request rejected: %s", outcome.Reason)
+		}
+		chatReq.Message = outcome.Content
+	}
+
 	// Get event bus (initializes on first use)
 	eventBus := s.getChatEventBus()
 
@@ -117,6 +143,11 @@ func (s *AIService) Chat(req *v1pb.ChatRequest, stream v1pb.AIService_ChatServer
 		}
 	}
 
+	// The CLI session of the conversation runs on this instance.
+	if chatReq.GeekMode || chatReq.EvolutionMode {
+		s.SessionAffinity.Claim(ctx, chatReq.ConversationID)
+	}
+
 	// Handle separator (---) - emit event and return without agent processing
 	if req.Message == "---" && chatReq.ConversationID != 0 {
 		_, _ = eventBus.Publish(ctx, &aichat.ChatEvent{
@@ -189,7 +220,10 @@ func (s *AIService) Chat(req *v1pb.ChatRequest, stream v1pb.AIService_ChatServer
 	// chatReq.History = history // Removed
 
 	// Create handler and process request
-	handler := s.getChatHandler()
+	handler, err := s.getChatHandler()
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "chat is not available: %v", err)
+	}
 
 	// Wrap stream to collect assistant response
 	collectingStream := &eventCollectingStream{
@@ -202,123 +236,43 @@ func (s *AIService) Chat(req *v1pb.ChatRequest, stream v1pb.AIService_ChatServer
 		isTemp:            chatReq.IsTempConversation,
 	}
 
-	if err := handler.Handle(ctx, chatReq, collectingStream); err != nil {
-		return aichat.HandleError(err)
-	}
-
-	return nil
-}
-
-// getChatHandler returns the cached chat handler, creating it on first use.
-// This avoids creating expensive components (ChatRouter, Orchestrator, etc.) on every request.
-func (s *AIService) getChatHandler() aichat.Handler {
-	s.chatHandlerMu.RLock()
-	if s.chatHandler != nil {
-		s.chatHandlerMu.RUnlock()
-		return s.chatHandler
+	// Register the chat so that StopChat can cancel it
+	if chatReq.ConversationID != 0 {
+		var done func()
+		ctx, done = s.activeChats.Start(ctx, chatReq)
+		defer done()
 	}
-	s.chatHandlerMu.RUnlock()
 
-	s.chatHandlerMu.Lock()
-	defer s.chatHandlerMu.Unlock()
-
-	// Double-check after acquiring write lock
-	if s.chatHandler != nil {
-		return s.chatHandler
+	if err := handler.Handle(ctx, chatReq, collectingStream); err != nil {
+		// Stopped before its block ran: the stream still ends with the stopped event
+		if errors.Is(context.Cause(ctx), aichat.ErrChatStopped) {
+			return stream.Send(&v1pb.ChatResponse{EventType: aichat.EventTypeStopped, Done: true})
+		}
+		err = aichat.HandleError(err)
+		// Also report the failure in-band for clients that only read events.
+		if sendErr := stream.Send(aichat.NewErrorEvent(err, 0)); sendErr != nil {
+			slog.Debug("failed to send error event", "error", sendErr)
+		}
+		return err
 	}
 
-	s.chatHandler = s.createChatHandler()
-	return s.chatHandler
+	return nil
 }
 
-// createChatHandler creates the chat handler with all routing components.
-// Called once by getChatHandler on first use.
-func (s *AIService) createChatHandler() aichat.Handler {
-	// Get cached agent factory (initializes on first use)
-	factory := s.getAgentFactory()
-
-	// Phase 5: Create BlockManager for Unified Block Model support
-	blockManager := aichat.NewBlockManager(s.Store)
-	parrotHandler := aichat.NewParrotHandler(factory, s.LLMService, s.persister, blockManager, s.TitleGenerator)
-
-	// Configure chat router for auto-routing.
-	// routerSvc provides two-layer routing (cache → rule).
-	// Orchestrator handles LLM-based task decomposition when needed.
-	routerSvc := s.getRouterService()
-	chatRouter := aichat.NewChatRouter(routerSvc)
-	slog.Info("Chat router enabled with cache + rule routing")
-
-	// P0 fix: Enable metadata-based sticky routing (context-engineering.md Phase 2)
-	// This allows routing decisions to be based on persisted database state (AIBlock.Metadata),
-	// not just in-memory session state.
-	metadataMgr := ctxpkg.NewMetadataManager(s.Store, 5*time.Minute) // 5 min cache TTL
-	chatRouterWithMetadata := agentpkg.NewChatRouterWithMetadata(chatRouter, metadataMgr)
-	parrotHandler.SetChatRouterWithMetadata(chatRouterWithMetadata)
-	parrotHandler.SetMetadataManager(metadataMgr)
-	slog.Info("Chat router with metadata-based sticky routing enabled")
-
-	// P0-2: Enable backend-driven context construction (context-engineering.md Phase 1)
-	// This replaces client-side history with server-side context building.
-	// The ContextBuilder fetches history from AIBlockStore instead of trusting req.History.
-	storeAdapter := ctxpkg.NewStoreAdapter(s.Store)
-	msgProvider := ctxpkg.NewBlockStoreMessageProvider(storeAdapter, 0) // userID not used in GetRecentMessages
-	contextBuilder := ctxpkg.NewService(ctxpkg.DefaultConfig()).WithMessageProvider(msgProvider)
-
-	// Phase 3: Inject EpisodicProvider for long-term memory retrieval
-	// This enables semantic search over past conversation episodes.
-	if s.EmbeddingService != nil {
-		vectorSearchAdapter := ctxpkg.NewVectorSearchStoreAdapter(s.Store)
-		episodicProvider := ctxpkg.NewEpisodicProvider(
-			vectorSearchAdapter,
-			s.EmbeddingService, // EmbeddingService implements ctxpkg.EmbeddingService
-			ctxpkg.DefaultEpisodicConfig(),
-			"", // agentType is set per-request
-		)
-		contextBuilder = contextBuilder.WithEpisodicProvider(episodicProvider)
-		slog.Info("Episodic memory provider enabled for context building")
-	}
-
-	parrotHandler.SetContextBuilder(contextBuilder)
-	slog.Info("Backend-driven context construction enabled")
-
-	// P0-3: Create and inject Orchestrator for handoff support
-	// Orchestrator handles: (1) needs_orchestration=true requests, (2) expert handoff when report_inability is called
-	// This enables seamless expert switching when the initial expert cannot handle the task.
-	if s.LLMService != nil && factory.GetParrotFactory() != nil {
-		// Get expert configurations from factory
-		expertConfigs := factory.GetSelfCognitionConfigs()
-
-		// Build CapabilityMap for handoff expert lookup
-		// CapabilityMap knows all experts' capabilities, used to find alternative experts
-		if len(expertConfigs) > 0 {
-			cm := orchestrator.NewCapabilityMap()
-			cm.BuildFromConfigs(expertConfigs)
-			cm.BuildKeywordIndex(expertConfigs)
-			parrotHandler.SetCapabilityMap(cm)
-			slog.Info("CapabilityMap initialized for handoff support")
-		}
-
-		// Create ExpertRegistry from ParrotFactory
-		// Note: userID is set per-request in ExecuteExpert, so we use 0 here as placeholder
-		expertRegistry := orchestrator.NewParrotExpertRegistry(factory.GetParrotFactory(), 0)
-
-		// Create Orchestrator with handoff enabled
-		orch := orchestrator.NewOrchestrator(
-			s.LLMService,
-			expertRegistry,
-			orchestrator.WithHandoff(true),
-			orchestrator.WithAggregation(true),
-		)
-		parrotHandler.SetOrchestrator(orch)
-		slog.Info("Orchestrator enabled with handoff support")
+// memoExists reports whether the memo of a UID exists; it backs the memo
+// citation check of expert outputs.
+func (s *AIService) memoExists(ctx context.Context, uid string) (bool, error) {
+	memo, err := s.Store.GetMemo(ctx, &store.FindMemo{UID: &uid})
+	if err != nil {
+		return false, err
 	}
-
-	return aichat.NewRoutingHandler(parrotHandler)
+	return memo != nil, nil
 }
 
-// grpcStreamWrapper wraps the gRPC stream to implement aichat.ChatStream.
+// grpcStreamWrapper wraps the gRPC stream (or the SSE stream of the REST endpoints)
+// to implement aichat.ChatStream.
 type grpcStreamWrapper struct {
-	stream v1pb.AIService_ChatServer
+	stream aichat.ChatStream
 }
 
 func (w *grpcStreamWrapper) Send(resp *v1pb.ChatResponse) error {
@@ -343,6 +297,19 @@ type eventCollectingStream struct {
 }
 
 func (s *eventCollectingStream) Send(resp *v1pb.ChatResponse) error {
+	// Admin-defined chat.event hooks may rewrite or drop non-terminal events.
+	if !resp.Done && resp.EventType != "" && s.service.ScriptHooks.HasHooks(s.Context(), scripting.TriggerChatEvent) {
+		outcome := s.service.ScriptHooks.Apply(s.Context(), scripting.TriggerChatEvent, map[string]any{
+			"event_type": resp.EventType,
+			"data":       resp.EventData,
+			"block_id":   resp.BlockId,
+		})
+		if outcome.Dropped {
+			return nil
+		}
+		resp.EventData = outcome.Data
+	}
+
 	// Log for debugging
 	if resp.Done {
 		slog.Info("eventCollectingStream: Sending done=true to frontend",
@@ -420,12 +387,12 @@ func (s *eventCollectingStream) Send(resp *v1pb.ChatResponse) error {
 // StopChat 取消正在进行的聊天流并终止相关会话。
 // 这是异步架构规范中 session.stop 的实现。
 //
-// Architecture Note: Session termination is primarily client-driven.
-//   - The client should cancel the streaming request (gRPC/HTTP) to immediately stop processing.
-//   - This method emits monitoring events for observability and metrics collection.
-//   - Active sessions are cleaned up after a 30-minute idle timeout (CCSessionManager).
-//   - For server-initiated termination in future, consider adding a session registry
-//     that maps conversationID to active context.CancelFunc for immediate cancellation.
+// The running chat of the conversation is canceled with aichat.ErrChatStopped:
+//   - The orchestrator or agent stops, and its block is marked interrupted with the partial answer.
+//   - The Geek/Evolution CLI session is terminated, so the CLI stops working on the turn.
+//   - The chat stream ends with a final "stopped" event (done=true).
+//
+// Only chats running on this instance are stopped.
 func (s *AIService) StopChat(ctx context.Context, req *v1pb.StopChatRequest) (*emptypb.Empty, error) {
 	if !s.IsEnabled() {
 		return nil, status.Errorf(codes.Unavailable, "AI features are disabled")
@@ -473,6 +440,25 @@ func (s *AIService) StopChat(ctx context.Context, req *v1pb.StopChatRequest) (*e
 		"reason", req.Reason,
 	)
 
+	if chat := s.activeChats.Stop(user.ID, req.ConversationId); chat != nil {
+		reason := req.Reason
+		if reason == "" {
+			reason = "stopped by the user"
+		}
+		handler, err := s.getChatHandler()
+		if stopper, ok := handler.(interface {
+			StopSession(*aichat.ChatRequest, string) error
+		}); ok && err == nil {
+			if err := stopper.StopSession(chat, reason); err != nil {
+				slog.Warn("StopChat: failed to stop the CLI session",
+					"user_id", user.ID,
+					"conversation_id", req.ConversationId,
+					"error", err,
+				)
+			}
+		}
+	}
+
 	// Emit stop event for monitoring, metrics, and potential async cleanup handlers
 	if eventBus := s.getChatEventBus(); eventBus != nil {
 		_, _ = eventBus.Publish(ctx, &aichat.ChatEvent{
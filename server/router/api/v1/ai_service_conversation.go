@@ -12,6 +12,7 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	titlegen "github.com/hrygo/divinesense/ai"
+	"github.com/hrygo/divinesense/plugin/entitygraph"
 	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
 	"github.com/hrygo/divinesense/store"
 )
@@ -326,6 +327,10 @@ func (s *AIService) DeleteAIConversation(ctx context.Context, req *v1pb.DeleteAI
 	if err := s.Store.DeleteAIConversation(ctx, &store.DeleteAIConversation{ID: req.Id}); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to delete conversation: %v", err)
 	}
+	source := entitygraph.Source{Type: entitygraph.SourceConversation, ID: req.Id}
+	if err := s.EntityGraph.Forget(ctx, user.ID, source); err != nil {
+		slog.Warn("failed to remove conversation from knowledge graph", "conversation_id", req.Id, "error", err)
+	}
 
 	return &emptypb.Empty{}, nil
 }
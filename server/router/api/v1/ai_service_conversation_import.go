@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/lithammer/shortuuid/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hrygo/divinesense/plugin/chatimport"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/store"
+	dbpostgres "github.com/hrygo/divinesense/store/db/postgres"
+)
+
+// ImportConversation imports the conversations.json of a ChatGPT or Claude
+// data export, or a single conversation of it; the format is detected when not
+// given. Each conversation becomes a conversation of the current user, with a
+// block per round that keeps the times of the messages. Conversations are
+// imported one by one: a failure leaves the ones before it imported.
+func (s *AIService) ImportConversation(ctx context.Context, req *v1pb.ImportConversationRequest) (*v1pb.ImportConversationResponse, error) {
+	var format chatimport.Format
+	switch req.Format {
+	case v1pb.ConversationImportFormat_CONVERSATION_IMPORT_FORMAT_UNSPECIFIED:
+	case v1pb.ConversationImportFormat_CONVERSATION_IMPORT_FORMAT_CHATGPT:
+		format = chatimport.FormatChatGPT
+	case v1pb.ConversationImportFormat_CONVERSATION_IMPORT_FORMAT_CLAUDE:
+		format = chatimport.FormatClaude
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "format must be chatgpt or claude")
+	}
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthorized")
+	}
+	// Blocks need PostgreSQL
+	if _, ok := s.Store.GetDriver().(*dbpostgres.DB); !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "conversation import requires PostgreSQL")
+	}
+
+	conversations, skipped, err := chatimport.Parse([]byte(req.Data), format)
+	if errors.Is(err, chatimport.ErrInvalid) {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to parse conversation export: %v", err)
+	}
+
+	resp := &v1pb.ImportConversationResponse{Skipped: int32(skipped)}
+	for _, conversation := range conversations {
+		created, err := s.importConversation(ctx, user.ID, conversation)
+		if err != nil {
+			slog.Error("failed to import conversation", "source", conversation.Source, "id", conversation.ID, "error", err)
+			return nil, status.Errorf(codes.Internal, "failed to import conversation %q", conversation.Title)
+		}
+		resp.Conversations = append(resp.Conversations, &v1pb.ImportedConversation{
+			Uid:    created.UID,
+			Title:  created.Title,
+			Rounds: int32(len(conversation.Rounds)),
+		})
+	}
+	return resp, nil
+}
+
+// importConversation creates a conversation of a user from an exported one.
+func (s *AIService) importConversation(ctx context.Context, userID int32, conversation *chatimport.Conversation) (*store.AIConversation, error) {
+	blocks := make([]*store.AIBlock, 0, len(conversation.Rounds))
+	for i, round := range conversation.Rounds {
+		inputs := make([]store.UserInput, 0, len(round.Inputs))
+		for _, input := range round.Inputs {
+			inputs = append(inputs, store.UserInput{Content: input.Content, Timestamp: input.Ts})
+		}
+		updatedTs := round.CreatedTs()
+		if round.AnsweredTs > 0 {
+			updatedTs = max(updatedTs, round.AnsweredTs/1000)
+		}
+		blocks = append(blocks, &store.AIBlock{
+			RoundNumber:        int32(i + 1),
+			BlockType:          store.AIBlockTypeMessage,
+			Mode:               store.AIBlockModeNormal,
+			UserInputs:         inputs,
+			AssistantContent:   round.Answer,
+			AssistantTimestamp: round.AnsweredTs,
+			Status:             store.AIBlockStatusCompleted,
+			Metadata: map[string]any{store.MetadataSectionImport: &store.ImportMetadata{
+				Version:    store.ImportMetadataVersion,
+				Source:     string(conversation.Source),
+				MessageIDs: round.MessageIDs,
+			}},
+			CreatedTs: round.CreatedTs(),
+			UpdatedTs: updatedTs,
+		})
+	}
+	return s.Store.ImportAIConversation(ctx, &store.ImportAIConversation{
+		Conversation: &store.AIConversation{
+			UID:         shortuuid.New(),
+			CreatorID:   userID,
+			Title:       conversation.Title,
+			TitleSource: store.TitleSourceUser,
+			ParrotID:    v1pb.AgentType_AGENT_TYPE_DEFAULT.String(),
+			CreatedTs:   conversation.CreatedTs,
+			UpdatedTs:   conversation.UpdatedTs,
+			RowStatus:   store.Normal,
+		},
+		Blocks: blocks,
+	})
+}
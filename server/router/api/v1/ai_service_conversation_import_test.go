@@ -0,0 +1,21 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+)
+
+func TestImportConversationValidation(t *testing.T) {
+	s := &AIService{}
+	_, err := s.ImportConversation(context.Background(), &v1pb.ImportConversationRequest{Format: 9, Data: "[]"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.ImportConversation(context.Background(), &v1pb.ImportConversationRequest{Data: "[]"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
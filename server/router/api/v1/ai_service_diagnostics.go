@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/plugin/embedindex"
+	"github.com/hrygo/divinesense/server/middleware"
+)
+
+// AIDiagnostics is the response of GET /api/v1/system/ai/diagnostics.
+type AIDiagnostics struct {
+	AIEnabled bool `json:"ai_enabled"`
+	// SafeMode reports that AI was disabled at boot with --safe-mode.
+	SafeMode bool `json:"safe_mode"`
+	// ClaudeCLI is the installed Claude Code CLI checked against the
+	// compatibility matrix of Geek and Evolution modes.
+	ClaudeCLI agentpkg.CLICompatibility `json:"claude_cli"`
+	// Runners are the agent runner backends of Geek and Evolution modes.
+	Runners map[string]string `json:"runners"`
+	// LoadShedding is the resource pressure of AI chat with the requests
+	// shed by class.
+	LoadShedding *middleware.LoadShedStatus `json:"load_shedding,omitempty"`
+	// EmbeddingCache is the hit rate of the query embedding cache.
+	EmbeddingCache *embedindex.CacheStats `json:"embedding_cache,omitempty"`
+}
+
+// registerDiagnosticsRoutes registers the admin AI diagnostics endpoint.
+func (s *APIV1Service) registerDiagnosticsRoutes(group *echo.Group) {
+	group.GET("/ai/diagnostics", s.GetAIDiagnostics, restAdminMiddleware)
+}
+
+// GET /api/v1/system/ai/diagnostics.
+func (s *APIV1Service) GetAIDiagnostics(c echo.Context) error {
+	diagnostics := AIDiagnostics{
+		AIEnabled: s.AIService != nil,
+		SafeMode:  s.Profile.SafeMode,
+		ClaudeCLI: agentpkg.DetectCLI(c.Request().Context(), "claude"),
+		Runners:   map[string]string{"geek": s.Profile.GeekRunner, "evolution": s.Profile.EvolutionRunner},
+	}
+	if shedder := s.LoadShedder(); shedder != nil {
+		diagnostics.LoadShedding = shedder.Status()
+	}
+	if s.AIService != nil {
+		if embedding, ok := s.AIService.EmbeddingService.(*embedindex.Service); ok {
+			diagnostics.EmbeddingCache = embedding.CacheStats()
+		}
+	}
+	return c.JSON(http.StatusOK, diagnostics)
+}
@@ -0,0 +1,125 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/internal/profile"
+	"github.com/hrygo/divinesense/plugin/pdfdoc"
+	"github.com/hrygo/divinesense/plugin/textextract"
+	"github.com/hrygo/divinesense/store"
+)
+
+// pdfExtractTimeout bounds the extraction of one PDF by Tika.
+const pdfExtractTimeout = 5 * time.Minute
+
+// newPDFExtractor returns the Tika client extracting PDFs page by page.
+func newPDFExtractor(profile *profile.Profile) *textextract.Client {
+	config := textextract.DefaultConfig()
+	config.TikaServerURL = profile.TikaServerURL
+	config.Timeout = pdfExtractTimeout
+	return textextract.NewClient(config)
+}
+
+// loadAttachmentBlob returns the content of an attachment, whatever its storage.
+func (s *APIV1Service) loadAttachmentBlob(ctx context.Context, attachmentID int32) ([]byte, error) {
+	attachment, err := s.Store.GetAttachment(ctx, &store.FindAttachment{ID: &attachmentID, GetBlob: true})
+	if err != nil {
+		return nil, err
+	}
+	if attachment == nil {
+		return nil, fmt.Errorf("attachment %d not found", attachmentID)
+	}
+	return s.AttachmentService.GetAttachmentBlob(attachment)
+}
+
+// registerDocumentRoutes registers the API for the ingestion status and the
+// search of PDF attachments.
+func (s *APIV1Service) registerDocumentRoutes(group *echo.Group) {
+	if s.AIService == nil || s.AIService.Documents == nil {
+		return
+	}
+	documents := group.Group("/ai/documents")
+	documents.GET("", s.ListDocuments)
+	documents.GET("/search", s.SearchDocuments)
+	documents.GET("/:uid", s.GetDocument)
+	documents.POST("/:uid/reprocess", s.ReprocessDocument)
+}
+
+// PDFDocuments returns the library ingesting PDF attachments in the
+// background, or nil when PDF ingestion is unavailable.
+func (s *APIV1Service) PDFDocuments() *pdfdoc.Library {
+	if s.AIService == nil {
+		return nil
+	}
+	return s.AIService.Documents
+}
+
+// GET /api/v1/system/ai/documents.
+// Lists the current user's PDF attachments with their processing status.
+func (s *APIV1Service) ListDocuments(c echo.Context) error {
+	documents, err := s.AIService.Documents.List(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		slog.Error("failed to list documents", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to list documents")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"documents": documents})
+}
+
+// GET /api/v1/system/ai/documents/:uid.
+// Returns the processing status of a PDF attachment, by attachment UID.
+func (s *APIV1Service) GetDocument(c echo.Context) error {
+	document, err := s.AIService.Documents.Get(c.Request().Context(), restCurrentUser(c).ID, c.Param("uid"))
+	if err != nil {
+		slog.Error("failed to get document", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get document")
+	}
+	if document == nil {
+		return restError(c, http.StatusNotFound, "document not found")
+	}
+	return c.JSON(http.StatusOK, document)
+}
+
+// POST /api/v1/system/ai/documents/:uid/reprocess.
+// Queues a PDF attachment for processing again, e.g. after a failure.
+func (s *APIV1Service) ReprocessDocument(c echo.Context) error {
+	document, err := s.AIService.Documents.Reprocess(c.Request().Context(), restCurrentUser(c).ID, c.Param("uid"))
+	if err != nil {
+		slog.Error("failed to reprocess document", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to reprocess document")
+	}
+	if document == nil {
+		return restError(c, http.StatusNotFound, "document not found")
+	}
+	return c.JSON(http.StatusOK, document)
+}
+
+// GET /api/v1/system/ai/documents/search?q=&limit=.
+// Returns the passages of the current user's PDFs closest to q, with their page.
+func (s *APIV1Service) SearchDocuments(c echo.Context) error {
+	query := c.QueryParam("q")
+	if query == "" {
+		return restError(c, http.StatusBadRequest, "q is required")
+	}
+	limit := 0
+	if raw := c.QueryParam("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return restError(c, http.StatusBadRequest, "invalid limit")
+		}
+		limit = n
+	}
+
+	hits, err := s.AIService.Documents.Search(c.Request().Context(), restCurrentUser(c).ID, query, limit)
+	if err != nil {
+		slog.Error("failed to search documents", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to search documents")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"hits": hits})
+}
@@ -45,7 +45,7 @@ func (s *AIService) DetectDuplicates(ctx context.Context, req *v1pb.DetectDuplic
 	}
 
 	// Create detector
-	detector := duplicate.NewDuplicateDetector(s.Store, s.EmbeddingService, s.EmbeddingModel)
+	detector := duplicate.NewDuplicateDetector(s.Store, s.EmbeddingService, s.Store.EmbeddingModel())
 
 	// Detect duplicates
 	result, err := detector.Detect(ctx, &duplicate.DetectRequest{
@@ -99,7 +99,7 @@ func (s *AIService) MergeMemos(ctx context.Context, req *v1pb.MergeMemosRequest)
 	}
 
 	// Create detector and merge
-	detector := duplicate.NewDuplicateDetector(s.Store, s.EmbeddingService, s.EmbeddingModel)
+	detector := duplicate.NewDuplicateDetector(s.Store, s.EmbeddingService, s.Store.EmbeddingModel())
 	err = detector.Merge(ctx, user.ID, sourceUID, targetUID)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "merge failed: %v", err)
@@ -133,7 +133,7 @@ func (s *AIService) LinkMemos(ctx context.Context, req *v1pb.LinkMemosRequest) (
 	}
 
 	// Create detector and link
-	detector := duplicate.NewDuplicateDetector(s.Store, s.EmbeddingService, s.EmbeddingModel)
+	detector := duplicate.NewDuplicateDetector(s.Store, s.EmbeddingService, s.Store.EmbeddingModel())
 	err = detector.Link(ctx, user.ID, uid1, uid2)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "link failed: %v", err)
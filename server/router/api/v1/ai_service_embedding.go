@@ -0,0 +1,85 @@
+package v1
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	embeddingrunner "github.com/hrygo/divinesense/server/runner/embedding"
+)
+
+// ReindexAll embeds all the memos again in the background and returns the
+// progress of the started reindex.
+func (s *AIService) ReindexAll(ctx context.Context, _ *v1pb.ReindexAllRequest) (*v1pb.ReindexProgress, error) {
+	if err := s.requireEmbeddingAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return convertReindexResult(s.EmbeddingRunner.ReindexAll())
+}
+
+// ReindexSince embeds again the memos updated at or after since (unix
+// seconds) in the background and returns the progress of the started reindex.
+func (s *AIService) ReindexSince(ctx context.Context, req *v1pb.ReindexSinceRequest) (*v1pb.ReindexProgress, error) {
+	if req.Since <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "since must be a unix timestamp")
+	}
+	if err := s.requireEmbeddingAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return convertReindexResult(s.EmbeddingRunner.ReindexSince(req.Since))
+}
+
+// GetReindexProgress returns the progress of the latest reindex.
+func (s *AIService) GetReindexProgress(ctx context.Context, _ *v1pb.GetReindexProgressRequest) (*v1pb.ReindexProgress, error) {
+	if err := s.requireEmbeddingAdmin(ctx); err != nil {
+		return nil, err
+	}
+	progress := s.EmbeddingRunner.Progress()
+	if progress == nil {
+		return nil, status.Errorf(codes.NotFound, "no reindex was started")
+	}
+	return convertReindexProgress(progress), nil
+}
+
+// requireEmbeddingAdmin checks that embeddings are configured and that the
+// current user is an admin.
+func (s *AIService) requireEmbeddingAdmin(ctx context.Context) error {
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, "unauthorized")
+	}
+	if !isSuperUser(user) {
+		return status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+	if s.EmbeddingRunner == nil {
+		return status.Errorf(codes.Unavailable, "memo embeddings are not configured")
+	}
+	return nil
+}
+
+func convertReindexResult(progress *embeddingrunner.ReindexProgress, err error) (*v1pb.ReindexProgress, error) {
+	if errors.Is(err, embeddingrunner.ErrReindexRunning) {
+		return nil, status.Errorf(codes.Aborted, "%v", err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	return convertReindexProgress(progress), nil
+}
+
+func convertReindexProgress(progress *embeddingrunner.ReindexProgress) *v1pb.ReindexProgress {
+	return &v1pb.ReindexProgress{
+		Since:      progress.Since,
+		Running:    progress.Running,
+		Total:      int32(progress.Total),
+		Processed:  int32(progress.Processed),
+		Failed:     int32(progress.Failed),
+		Model:      progress.Model,
+		StartedTs:  progress.StartedTs,
+		FinishedTs: progress.FinishedTs,
+		Error:      progress.Error,
+	}
+}
@@ -0,0 +1,34 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	embeddingrunner "github.com/hrygo/divinesense/server/runner/embedding"
+)
+
+func TestReindexSinceValidation(t *testing.T) {
+	_, err := (&AIService{}).ReindexSince(context.Background(), &v1pb.ReindexSinceRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = (&AIService{}).ReindexAll(context.Background(), &v1pb.ReindexAllRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestConvertReindexResult(t *testing.T) {
+	_, err := convertReindexResult(nil, embeddingrunner.ErrReindexRunning)
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	progress, err := convertReindexResult(&embeddingrunner.ReindexProgress{Since: 10, Running: true, Total: 3, StartedTs: 20}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), progress.Since)
+	assert.True(t, progress.Running)
+	assert.Equal(t, int32(3), progress.Total)
+	assert.Equal(t, int64(20), progress.StartedTs)
+}
@@ -0,0 +1,161 @@
+package v1
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/entitygraph"
+	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
+	"github.com/hrygo/divinesense/store"
+)
+
+const (
+	// defaultEntityLimit and maxEntityLimit bound the entities listed per request.
+	defaultEntityLimit = 50
+	maxEntityLimit     = 200
+)
+
+// entityReindexes holds the users whose memos are being reindexed.
+var entityReindexes sync.Map // map[int32]struct{}
+
+// IndexMemoEntities extracts the entities of a memo into its creator's
+// knowledge graph in the background.
+func (s *AIService) IndexMemoEntities(ctx context.Context, memo *store.Memo) {
+	s.EntityGraph.IndexAsync(ctx, memo.CreatorID, memoEntitySource(memo), memo.Content)
+}
+
+// ForgetMemoEntities removes the mentions of a deleted memo from the knowledge graph.
+func (s *AIService) ForgetMemoEntities(ctx context.Context, memo *store.Memo) {
+	if err := s.EntityGraph.Forget(ctx, memo.CreatorID, memoEntitySource(memo)); err != nil {
+		slog.Warn("failed to remove memo from knowledge graph", "memo_id", memo.ID, "error", err)
+	}
+}
+
+func memoEntitySource(memo *store.Memo) entitygraph.Source {
+	return entitygraph.Source{Type: entitygraph.SourceMemo, ID: memo.ID, Ref: memo.UID}
+}
+
+// indexChatMessageEntities is the user_message listener extracting the
+// entities of chat messages. Temporary conversations are not indexed, nor
+// locked ones, whose messages would leak into the mentions in clear.
+func (s *AIService) indexChatMessageEntities(ctx context.Context, event *aichat.ChatEvent) (interface{}, error) {
+	if event.IsTempConversation || event.ConversationID == 0 {
+		return nil, nil
+	}
+	if locked, _ := s.Store.ConversationLockState(event.ConversationID); locked {
+		return nil, nil
+	}
+	s.EntityGraph.IndexAsync(ctx, event.UserID, entitygraph.Source{
+		Type: entitygraph.SourceConversation,
+		ID:   event.ConversationID,
+	}, event.UserMessage)
+	return nil, nil
+}
+
+// registerEntityRoutes registers the API for browsing the knowledge graph.
+func (s *APIV1Service) registerEntityRoutes(group *echo.Group) {
+	if s.AIService == nil || s.AIService.EntityGraph == nil {
+		return
+	}
+	entities := group.Group("/ai/entities")
+	entities.GET("", s.ListEntities)
+	entities.POST("/reindex", s.ReindexEntities)
+	entities.GET("/:id", s.GetEntity)
+	entities.DELETE("/:id", s.DeleteEntity)
+}
+
+// GET /api/v1/system/ai/entities?q=&kind=&limit=.
+// Lists the current user's entities, most mentioned first.
+func (s *APIV1Service) ListEntities(c echo.Context) error {
+	user := restCurrentUser(c)
+	find := &entitygraph.FindEntity{CreatorID: &user.ID, Query: c.QueryParam("q"), Limit: defaultEntityLimit}
+	if raw := c.QueryParam("kind"); raw != "" {
+		kind := entitygraph.Kind(raw)
+		if !kind.Valid() {
+			return restError(c, http.StatusBadRequest, "kind must be PERSON, PROJECT or TERM")
+		}
+		find.Kind = &kind
+	}
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return restError(c, http.StatusBadRequest, "invalid limit")
+		}
+		find.Limit = min(limit, maxEntityLimit)
+	}
+
+	entities, err := s.AIService.EntityGraph.List(c.Request().Context(), find)
+	if err != nil {
+		slog.Error("failed to list entities", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to list entities")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"entities": entities})
+}
+
+// GET /api/v1/system/ai/entities/:id.
+// Returns an entity with its latest mentions and related entities.
+func (s *APIV1Service) GetEntity(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid entity id")
+	}
+	detail, err := s.AIService.EntityGraph.Get(c.Request().Context(), int32(id), restCurrentUser(c).ID)
+	if err != nil {
+		slog.Error("failed to get entity", "id", id, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get entity")
+	}
+	if detail == nil {
+		return restError(c, http.StatusNotFound, "entity not found")
+	}
+	return c.JSON(http.StatusOK, detail)
+}
+
+// DELETE /api/v1/system/ai/entities/:id.
+func (s *APIV1Service) DeleteEntity(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid entity id")
+	}
+	deleted, err := s.AIService.EntityGraph.Delete(c.Request().Context(), int32(id), restCurrentUser(c).ID)
+	if err != nil {
+		slog.Error("failed to delete entity", "id", id, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to delete entity")
+	}
+	if !deleted {
+		return restError(c, http.StatusNotFound, "entity not found")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// POST /api/v1/system/ai/entities/reindex.
+// Extracts the entities of all the current user's memos in the background,
+// e.g. for memos written before the knowledge graph existed.
+func (s *APIV1Service) ReindexEntities(c echo.Context) error {
+	user := restCurrentUser(c)
+	if _, running := entityReindexes.LoadOrStore(user.ID, struct{}{}); running {
+		return restError(c, http.StatusConflict, "a reindex is already running")
+	}
+	go func(ctx context.Context, userID int32) {
+		defer entityReindexes.Delete(userID)
+		memos, err := s.Store.ListMemos(ctx, &store.FindMemo{CreatorID: &userID})
+		if err != nil {
+			slog.Warn("entity reindex: failed to list memos", "user_id", userID, "error", err)
+			return
+		}
+		indexed := 0
+		for _, memo := range memos {
+			if err := s.AIService.EntityGraph.Index(ctx, userID, memoEntitySource(memo), memo.Content); err != nil {
+				slog.Warn("entity reindex: failed to index memo", "memo_id", memo.ID, "error", err)
+				continue
+			}
+			indexed++
+		}
+		slog.Info("entity reindex completed", "user_id", userID, "memos", len(memos), "indexed", indexed)
+	}(context.WithoutCancel(c.Request().Context()), user.ID)
+	return c.NoContent(http.StatusAccepted)
+}
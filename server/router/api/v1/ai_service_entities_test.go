@@ -0,0 +1,62 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hrygo/divinesense/plugin/entitygraph"
+	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
+	"github.com/hrygo/divinesense/store"
+)
+
+// lockedSealer locks one conversation, never unlocked.
+type lockedSealer struct {
+	conversationID int32
+}
+
+func (s *lockedSealer) HasLocks() bool                   { return true }
+func (s *lockedSealer) Locked(conversationID int32) bool { return conversationID == s.conversationID }
+func (s *lockedSealer) Unlocked(int32) bool              { return false }
+func (s *lockedSealer) Seal(int32, string) (string, error) {
+	return "", store.ErrConversationLocked
+}
+func (s *lockedSealer) Open(_ int32, content string) (string, error) { return content, nil }
+
+// recordingExtractor records the texts it is given and extracts nothing.
+type recordingExtractor struct {
+	texts chan string
+}
+
+func (e *recordingExtractor) Extract(_ context.Context, text string) (*entitygraph.Extracted, error) {
+	e.texts <- text
+	return nil, errors.New("no entities")
+}
+
+func TestIndexChatMessageEntitiesSkipsLockedConversations(t *testing.T) {
+	st := &store.Store{}
+	st.SetBlockSealer(&lockedSealer{conversationID: 1})
+	extractor := &recordingExtractor{texts: make(chan string, 2)}
+	s := &AIService{Store: st, EntityGraph: entitygraph.NewGraph(nil, extractor)}
+
+	_, err := s.indexChatMessageEntities(context.Background(), &aichat.ChatEvent{UserID: 1, ConversationID: 1, UserMessage: "meet Alice about Project X"})
+	assert.NoError(t, err)
+	_, err = s.indexChatMessageEntities(context.Background(), &aichat.ChatEvent{UserID: 1, ConversationID: 2, UserMessage: "meet Bob"})
+	assert.NoError(t, err)
+
+	// Only the message of the conversation in clear is extracted
+	select {
+	case text := <-extractor.texts:
+		assert.Equal(t, "meet Bob", text)
+	case <-time.After(time.Second):
+		t.Fatal("message of an unlocked conversation not indexed")
+	}
+	select {
+	case text := <-extractor.texts:
+		t.Fatalf("message of a locked conversation indexed: %q", text)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,195 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/ai/review"
+	"github.com/hrygo/divinesense/plugin/flashcard"
+	"github.com/hrygo/divinesense/store"
+)
+
+const (
+	// defaultFlashcardLimit and maxFlashcardLimit bound the cards listed per request.
+	defaultFlashcardLimit = 20
+	maxFlashcardLimit     = 200
+)
+
+// flashcardMemoFinder loads the memos cards are generated from.
+type flashcardMemoFinder struct {
+	store *store.Store
+}
+
+// FindMemos implements flashcard.MemoFinder.
+func (f flashcardMemoFinder) FindMemos(ctx context.Context, creatorID int32, uids []string) ([]*flashcard.Memo, error) {
+	memos, err := f.store.ListMemos(ctx, &store.FindMemo{CreatorID: &creatorID, UIDList: uids})
+	if err != nil {
+		return nil, err
+	}
+	found := make([]*flashcard.Memo, 0, len(memos))
+	for _, memo := range memos {
+		found = append(found, &flashcard.Memo{ID: memo.ID, UID: memo.UID, Content: memo.Content})
+	}
+	return found, nil
+}
+
+// GenerateFlashcardsRequest is the body of POST /ai/flashcards/generate.
+type GenerateFlashcardsRequest struct {
+	MemoUIDs []string `json:"memo_uids"`
+	// Replace regenerates the cards of memos that already have some,
+	// discarding their review progress.
+	Replace bool `json:"replace"`
+}
+
+// ReviewFlashcardRequest is the body of POST /ai/flashcards/:id/review.
+type ReviewFlashcardRequest struct {
+	// Quality is 0 (again), 1 (hard), 2 (good) or 3 (easy).
+	Quality *int `json:"quality"`
+}
+
+// registerFlashcardRoutes registers the API for generating and reviewing flashcards.
+func (s *APIV1Service) registerFlashcardRoutes(group *echo.Group) {
+	if s.AIService == nil || s.AIService.Flashcards == nil {
+		return
+	}
+	cards := group.Group("/ai/flashcards")
+	cards.GET("", s.ListFlashcards)
+	cards.POST("/generate", s.GenerateFlashcards)
+	cards.GET("/due", s.ListDueFlashcards)
+	cards.GET("/stats", s.GetFlashcardStats)
+	cards.POST("/:id/review", s.ReviewFlashcard)
+	cards.DELETE("/:id", s.DeleteFlashcard)
+}
+
+// GET /api/v1/system/ai/flashcards?memo_id=&limit=.
+// Lists the current user's cards, earliest due first.
+func (s *APIV1Service) ListFlashcards(c echo.Context) error {
+	user := restCurrentUser(c)
+	limit, err := parseFlashcardLimit(c)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	find := &flashcard.FindCard{CreatorID: &user.ID, Limit: limit}
+	if raw := c.QueryParam("memo_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return restError(c, http.StatusBadRequest, "invalid memo_id")
+		}
+		memoID := int32(id)
+		find.MemoID = &memoID
+	}
+
+	cards, err := s.AIService.Flashcards.List(c.Request().Context(), find)
+	if err != nil {
+		slog.Error("failed to list flashcards", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to list flashcards")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"cards": cards})
+}
+
+// POST /api/v1/system/ai/flashcards/generate.
+// Generates cards from the given memos of the current user.
+func (s *APIV1Service) GenerateFlashcards(c echo.Context) error {
+	req := &GenerateFlashcardsRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	if len(req.MemoUIDs) == 0 || len(req.MemoUIDs) > flashcard.MaxMemosPerGeneration {
+		return restError(c, http.StatusBadRequest,
+			fmt.Sprintf("memo_uids must list 1 to %d memos", flashcard.MaxMemosPerGeneration))
+	}
+
+	user := restCurrentUser(c)
+	result, err := s.AIService.Flashcards.Generate(c.Request().Context(), user.ID, req.MemoUIDs, req.Replace)
+	if err != nil {
+		slog.Error("failed to generate flashcards", "user_id", user.ID, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to generate flashcards")
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GET /api/v1/system/ai/flashcards/due?limit=.
+// Returns the cards of the next review session: those due now, earliest first.
+func (s *APIV1Service) ListDueFlashcards(c echo.Context) error {
+	limit, err := parseFlashcardLimit(c)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	cards, err := s.AIService.Flashcards.Due(c.Request().Context(), restCurrentUser(c).ID, limit)
+	if err != nil {
+		slog.Error("failed to list due flashcards", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to list due flashcards")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"cards": cards})
+}
+
+// POST /api/v1/system/ai/flashcards/:id/review.
+// Grades the recall of a card and returns it with its next due time.
+func (s *APIV1Service) ReviewFlashcard(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid card id")
+	}
+	req := &ReviewFlashcardRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.Quality == nil || *req.Quality < int(review.QualityAgain) || *req.Quality > int(review.QualityEasy) {
+		return restError(c, http.StatusBadRequest, "quality must be 0 (again), 1 (hard), 2 (good) or 3 (easy)")
+	}
+
+	card, err := s.AIService.Flashcards.Review(c.Request().Context(), int32(id), restCurrentUser(c).ID, review.ReviewQuality(*req.Quality))
+	if err != nil {
+		slog.Error("failed to review flashcard", "id", id, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to review flashcard")
+	}
+	if card == nil {
+		return restError(c, http.StatusNotFound, "card not found")
+	}
+	return c.JSON(http.StatusOK, card)
+}
+
+// DELETE /api/v1/system/ai/flashcards/:id.
+func (s *APIV1Service) DeleteFlashcard(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid card id")
+	}
+	deleted, err := s.AIService.Flashcards.Delete(c.Request().Context(), int32(id), restCurrentUser(c).ID)
+	if err != nil {
+		slog.Error("failed to delete flashcard", "id", id, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to delete flashcard")
+	}
+	if !deleted {
+		return restError(c, http.StatusNotFound, "card not found")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GET /api/v1/system/ai/flashcards/stats.
+func (s *APIV1Service) GetFlashcardStats(c echo.Context) error {
+	stats, err := s.AIService.Flashcards.Stats(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		slog.Error("failed to get flashcard stats", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get flashcard stats")
+	}
+	return c.JSON(http.StatusOK, stats)
+}
+
+// parseFlashcardLimit reads the limit query parameter.
+func parseFlashcardLimit(c echo.Context) (int, error) {
+	raw := c.QueryParam("limit")
+	if raw == "" {
+		return defaultFlashcardLimit, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid limit")
+	}
+	return min(limit, maxFlashcardLimit), nil
+}
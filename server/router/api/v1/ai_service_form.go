@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
+	"github.com/hrygo/divinesense/store"
+)
+
+// SubmitForm answers the form_request pending on a block and continues the agent
+// in the same block. The follow-up is streamed like a Chat response.
+func (s *AIService) SubmitForm(req *v1pb.SubmitFormRequest, stream v1pb.AIService_SubmitFormServer) error {
+	ctx := stream.Context()
+	if !s.IsEnabled() {
+		return status.Errorf(codes.Unavailable, "AI features are disabled")
+	}
+	if !s.IsLLMEnabled() {
+		return status.Errorf(codes.Unavailable, "LLM service is not available")
+	}
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, "unauthorized")
+	}
+	if req.BlockId <= 0 {
+		return status.Errorf(codes.InvalidArgument, "block_id is required")
+	}
+	blockID := req.BlockId
+	if !globalAILimiter.Allow(strconv.FormatInt(int64(user.ID), 10)) {
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded")
+	}
+
+	block, err := s.Store.GetAIBlock(ctx, blockID)
+	if err != nil || block == nil {
+		return status.Errorf(codes.NotFound, "block not found")
+	}
+	conversations, err := s.Store.ListAIConversations(ctx, &store.FindAIConversation{
+		ID:        &block.ConversationID,
+		CreatorID: &user.ID,
+	})
+	if err != nil || len(conversations) == 0 {
+		return status.Errorf(codes.PermissionDenied, "access denied to this block")
+	}
+
+	pending, ok := aichat.PendingFormFromBlock(block)
+	if !ok {
+		return status.Errorf(codes.FailedPrecondition, "no form is pending on this block")
+	}
+	if req.FormId != pending.Form.ID {
+		return status.Errorf(codes.FailedPrecondition, "form %s is no longer pending", req.FormId)
+	}
+	payload := req.Payload.AsMap()
+	if err := pending.Form.ValidateResponse(payload); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid form payload: %v", err)
+	}
+
+	// Record the answers on the block and clear the pending form before resuming,
+	// so a double submit is rejected instead of running the agent twice.
+	message := pending.Form.FormatResponse(payload)
+	if err := s.Store.AppendUserInput(ctx, blockID, store.UserInput{
+		Content:   message,
+		Timestamp: time.Now().UnixMilli(),
+		Metadata: map[string]any{
+			"form_id":       pending.Form.ID,
+			"form_response": payload,
+		},
+	}); err != nil {
+		return status.Errorf(codes.Internal, "failed to record form response: %v", err)
+	}
+	if _, err := s.Store.UpdateAIBlock(ctx, &store.UpdateAIBlock{
+		ID:       blockID,
+		Metadata: map[string]any{aichat.PendingFormMetadataKey: nil},
+	}); err != nil {
+		return status.Errorf(codes.Internal, "failed to clear pending form: %v", err)
+	}
+
+	chatReq := &aichat.ChatRequest{
+		Message:         message,
+		AgentType:       aichat.AgentTypeFromName(pending.Agent),
+		Timezone:        req.Timezone,
+		UserID:          user.ID,
+		ConversationID:  block.ConversationID,
+		ContinueBlockID: blockID,
+		Budget:          s.blockBudget(ctx, user.ID),
+	}
+	if pending.IsClarification() {
+		// Route the original request again, now disambiguated by the answer
+		chatReq.Message = pending.Message + "\n" + message
+		chatReq.AgentType = aichat.AgentTypeAuto
+		chatReq.ClarificationRound = pending.Round
+	}
+	if chatReq.Timezone == "" || !aichat.IsValidTimezone(chatReq.Timezone) {
+		chatReq.Timezone = aichat.GetDefaultTimezone()
+	}
+
+	slog.Info("ai.chat.form_submitted",
+		"user_id", user.ID,
+		"block_id", blockID,
+		"form_id", pending.Form.ID,
+		"agent", pending.Agent,
+	)
+
+	collectingStream := &eventCollectingStream{
+		grpcStreamWrapper: &grpcStreamWrapper{stream: stream},
+		service:           s,
+		eventBus:          s.getChatEventBus(),
+		userID:            user.ID,
+		agentType:         chatReq.AgentType,
+		conversationID:    chatReq.ConversationID,
+	}
+	handler, err := s.getChatHandler()
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "chat is not available: %v", err)
+	}
+	if err := handler.Handle(ctx, chatReq, collectingStream); err != nil {
+		return aichat.HandleError(err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/internal/profile"
+	"github.com/hrygo/divinesense/plugin/geekworkspace"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+)
+
+// maxGeekWorkspaceDownloadBytes bounds the files DownloadGeekWorkspaceFile
+// returns, which it holds in memory.
+const maxGeekWorkspaceDownloadBytes = 64 << 20
+
+// newGeekWorkspaceQuota creates the quota of the Geek Mode workspaces from
+// the limits of the profile.
+func newGeekWorkspaceQuota(profile *profile.Profile) *geekworkspace.Quota {
+	return geekworkspace.NewQuota(geekworkspace.BaseDir(), geekworkspace.QuotaConfig{
+		LimitBytes:       int64(profile.GeekWorkspaceQuotaMB) << 20,
+		MaxAge:           time.Duration(profile.GeekWorkspaceMaxAgeDays) * 24 * time.Hour,
+		TempFileMaxBytes: int64(profile.GeekWorkspaceTempFileMaxMB) << 20,
+		ProjectsDir:      agentpkg.ClaudeProjectsDir(),
+	})
+}
+
+// GeekWorkspaceQuota returns the quota of the Geek Mode workspaces, nil
+// without AI.
+func (s *APIV1Service) GeekWorkspaceQuota() *geekworkspace.Quota {
+	if s.AIService == nil {
+		return nil
+	}
+	return s.AIService.WorkspaceQuota
+}
+
+// GetGeekWorkspaceUsage returns the disk usage of the workspace of the user
+// and its limit.
+func (s *AIService) GetGeekWorkspaceUsage(ctx context.Context, _ *v1pb.GetGeekWorkspaceUsageRequest) (*v1pb.GeekWorkspaceUsage, error) {
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthorized")
+	}
+	if s.WorkspaceQuota == nil {
+		return nil, status.Errorf(codes.Unavailable, "the workspace quota is not available")
+	}
+	usage, err := s.WorkspaceQuota.Usage(user.ID)
+	if err != nil {
+		return nil, geekWorkspaceError(err, "failed to measure workspace")
+	}
+	return &v1pb.GeekWorkspaceUsage{
+		Bytes:      usage.Bytes,
+		Files:      int32(usage.Files),
+		LimitBytes: usage.LimitBytes,
+		MeasuredTs: usage.MeasuredTs,
+	}, nil
+}
+
+// ListGeekWorkspaceFiles lists a directory of the workspace of the user, the
+// workspace itself without path.
+func (s *AIService) ListGeekWorkspaceFiles(ctx context.Context, req *v1pb.ListGeekWorkspaceFilesRequest) (*v1pb.ListGeekWorkspaceFilesResponse, error) {
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthorized")
+	}
+	listing, err := geekworkspace.ForUser(user.ID).List(req.Path)
+	if err != nil {
+		return nil, geekWorkspaceError(err, "failed to list workspace files")
+	}
+	resp := &v1pb.ListGeekWorkspaceFilesResponse{Path: listing.Path, Truncated: listing.Truncated}
+	for i := range listing.Entries {
+		resp.Entries = append(resp.Entries, convertGeekWorkspaceEntry(&listing.Entries[i]))
+	}
+	return resp, nil
+}
+
+// ReadGeekWorkspaceFile returns a file of the workspace with its text
+// content, up to 1 MiB; binary files have none.
+func (s *AIService) ReadGeekWorkspaceFile(ctx context.Context, req *v1pb.ReadGeekWorkspaceFileRequest) (*v1pb.GeekWorkspaceFileContent, error) {
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthorized")
+	}
+	content, err := geekworkspace.ForUser(user.ID).Read(req.Path)
+	if err != nil {
+		return nil, geekWorkspaceError(err, "failed to read workspace file")
+	}
+	return &v1pb.GeekWorkspaceFileContent{
+		Entry:     convertGeekWorkspaceEntry(&content.Entry),
+		MimeType:  content.MimeType,
+		Binary:    content.Binary,
+		Truncated: content.Truncated,
+		Content:   content.Content,
+	}, nil
+}
+
+// DownloadGeekWorkspaceFile returns a file of the workspace to save as an
+// attachment. Its content type is never that of the file, so that clients do
+// not render the artifacts of the sessions in the origin of the app.
+func (s *AIService) DownloadGeekWorkspaceFile(ctx context.Context, req *v1pb.DownloadGeekWorkspaceFileRequest) (*v1pb.DownloadGeekWorkspaceFileResponse, error) {
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthorized")
+	}
+	file, entry, err := geekworkspace.ForUser(user.ID).Open(req.Path)
+	if err != nil {
+		return nil, geekWorkspaceError(err, "failed to open workspace file")
+	}
+	defer file.Close()
+
+	if entry.Size > maxGeekWorkspaceDownloadBytes {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s is larger than %d MiB", entry.Path, maxGeekWorkspaceDownloadBytes>>20)
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxGeekWorkspaceDownloadBytes))
+	if err != nil {
+		return nil, geekWorkspaceError(err, "failed to download workspace file")
+	}
+	return &v1pb.DownloadGeekWorkspaceFileResponse{
+		Data:        data,
+		Filename:    entry.Name,
+		ContentType: "application/octet-stream",
+	}, nil
+}
+
+// DeleteGeekWorkspaceFile removes a file of the workspace, or a directory
+// with its content.
+func (s *AIService) DeleteGeekWorkspaceFile(ctx context.Context, req *v1pb.DeleteGeekWorkspaceFileRequest) (*emptypb.Empty, error) {
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthorized")
+	}
+	if err := geekworkspace.ForUser(user.ID).Delete(req.Path); err != nil {
+		return nil, geekWorkspaceError(err, "failed to delete workspace file")
+	}
+	if s.WorkspaceQuota != nil {
+		// Admit the writes of the sessions again once below the limit
+		s.WorkspaceQuota.Forget(user.ID)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// geekWorkspaceError maps the errors of the workspace to statuses.
+func geekWorkspaceError(err error, msg string) error {
+	switch {
+	case errors.Is(err, geekworkspace.ErrInvalid):
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	case errors.Is(err, geekworkspace.ErrNotFound):
+		return status.Errorf(codes.NotFound, "%v", err)
+	}
+	slog.Error(msg, "error", err)
+	return status.Errorf(codes.Internal, "%s", msg)
+}
+
+func convertGeekWorkspaceEntry(entry *geekworkspace.Entry) *v1pb.GeekWorkspaceEntry {
+	return &v1pb.GeekWorkspaceEntry{
+		Name:       entry.Name,
+		Path:       entry.Path,
+		IsDir:      entry.IsDir,
+		Symlink:    entry.Symlink,
+		Size:       entry.Size,
+		ModifiedTs: entry.ModifiedTs,
+	}
+}
@@ -0,0 +1,35 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hrygo/divinesense/plugin/geekworkspace"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+)
+
+func TestGeekWorkspaceUnauthenticated(t *testing.T) {
+	ctx := context.Background()
+	s := &AIService{}
+	_, err := s.GetGeekWorkspaceUsage(ctx, &v1pb.GetGeekWorkspaceUsageRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.ListGeekWorkspaceFiles(ctx, &v1pb.ListGeekWorkspaceFilesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.ReadGeekWorkspaceFile(ctx, &v1pb.ReadGeekWorkspaceFileRequest{Path: "a.md"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.DownloadGeekWorkspaceFile(ctx, &v1pb.DownloadGeekWorkspaceFileRequest{Path: "a.md"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.DeleteGeekWorkspaceFile(ctx, &v1pb.DeleteGeekWorkspaceFileRequest{Path: "a.md"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestGeekWorkspaceError(t *testing.T) {
+	assert.Equal(t, codes.InvalidArgument, status.Code(geekWorkspaceError(fmt.Errorf("%w: ../etc", geekworkspace.ErrInvalid), "failed")))
+	assert.Equal(t, codes.NotFound, status.Code(geekWorkspaceError(fmt.Errorf("%w: a.md", geekworkspace.ErrNotFound), "failed")))
+	assert.Equal(t, codes.Internal, status.Code(geekWorkspaceError(fmt.Errorf("boom"), "failed")))
+}
@@ -25,7 +25,7 @@ func (s *AIService) GetKnowledgeGraph(ctx context.Context, req *v1pb.GetKnowledg
 	}
 
 	// Create graph builder
-	builder := graph.NewGraphBuilder(s.Store, s.EmbeddingService, s.EmbeddingModel)
+	builder := graph.NewGraphBuilder(s.Store, s.EmbeddingService, s.Store.EmbeddingModel())
 
 	// Build filter
 	filter := graph.GraphFilter{
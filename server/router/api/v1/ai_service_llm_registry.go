@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/hrygo/divinesense/plugin/llmregistry"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+)
+
+// ListAgentModels lists the configured providers, the default one first, and
+// the provider and model of each agent type with the source of its selection
+// (default, env or admin).
+func (s *AIService) ListAgentModels(ctx context.Context, _ *v1pb.ListAgentModelsRequest) (*v1pb.ListAgentModelsResponse, error) {
+	if err := s.requireLLMRegistryAdmin(ctx); err != nil {
+		return nil, err
+	}
+	agents, err := s.LLMRegistry.List(ctx)
+	if err != nil {
+		return nil, llmRegistryError(err, "failed to list agent models")
+	}
+	resp := &v1pb.ListAgentModelsResponse{}
+	for _, provider := range s.LLMRegistry.Providers() {
+		resp.Providers = append(resp.Providers, &v1pb.LLMProvider{
+			Name:     provider.Name,
+			Provider: provider.Provider,
+			BaseUrl:  provider.BaseURL,
+			Model:    provider.Model,
+		})
+	}
+	for _, agent := range agents {
+		resp.Agents = append(resp.Agents, convertAgentModel(agent))
+	}
+	return resp, nil
+}
+
+// UpdateAgentModel selects the provider and model of an agent type, a parrot
+// name or "orchestrator"; an empty model is the default model of the
+// provider. The next LLM calls of the agent use the selection.
+func (s *AIService) UpdateAgentModel(ctx context.Context, req *v1pb.UpdateAgentModelRequest) (*v1pb.AgentModel, error) {
+	if err := s.requireLLMRegistryAdmin(ctx); err != nil {
+		return nil, err
+	}
+	saved, err := s.LLMRegistry.Select(ctx, &llmregistry.Selection{
+		Agent:    req.Agent,
+		Provider: req.Provider,
+		Model:    req.Model,
+	})
+	if err != nil {
+		return nil, llmRegistryError(err, "failed to save agent model")
+	}
+	return convertAgentModel(saved), nil
+}
+
+// DeleteAgentModel removes the selection of an agent type, which returns to
+// its DIVINESENSE_AI_AGENT_MODELS selection, or to the default provider.
+func (s *AIService) DeleteAgentModel(ctx context.Context, req *v1pb.DeleteAgentModelRequest) (*emptypb.Empty, error) {
+	if err := s.requireLLMRegistryAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.LLMRegistry.Reset(ctx, req.Agent); err != nil {
+		return nil, llmRegistryError(err, "failed to delete agent model")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// requireLLMRegistryAdmin checks that the LLM registry is configured and that
+// the current user is an admin.
+func (s *AIService) requireLLMRegistryAdmin(ctx context.Context) error {
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, "unauthorized")
+	}
+	if !isSuperUser(user) {
+		return status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+	if s.LLMRegistry == nil {
+		return status.Errorf(codes.Unavailable, "the agent model selection is not available")
+	}
+	return nil
+}
+
+// llmRegistryError maps the errors of the LLM registry to statuses.
+func llmRegistryError(err error, msg string) error {
+	switch {
+	case errors.Is(err, llmregistry.ErrInvalid):
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	case errors.Is(err, llmregistry.ErrNotFound):
+		return status.Errorf(codes.NotFound, "%v", err)
+	}
+	slog.Error(msg, "error", err)
+	return status.Errorf(codes.Internal, "%s", msg)
+}
+
+func convertAgentModel(selection *llmregistry.Selection) *v1pb.AgentModel {
+	return &v1pb.AgentModel{
+		Agent:     selection.Agent,
+		Provider:  selection.Provider,
+		Model:     selection.Model,
+		Source:    selection.Source,
+		UpdatedTs: selection.UpdatedTs,
+	}
+}
@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hrygo/divinesense/plugin/llmregistry"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+)
+
+func TestAgentModelsUnauthenticated(t *testing.T) {
+	_, err := (&AIService{}).ListAgentModels(context.Background(), &v1pb.ListAgentModelsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = (&AIService{}).UpdateAgentModel(context.Background(), &v1pb.UpdateAgentModelRequest{Agent: "memo", Provider: "deepseek"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = (&AIService{}).DeleteAgentModel(context.Background(), &v1pb.DeleteAgentModelRequest{Agent: "memo"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestLLMRegistryError(t *testing.T) {
+	assert.Equal(t, codes.InvalidArgument, status.Code(llmRegistryError(fmt.Errorf("%w: unknown agent", llmregistry.ErrInvalid), "failed")))
+	assert.Equal(t, codes.NotFound, status.Code(llmRegistryError(fmt.Errorf("%w: no selection", llmregistry.ErrNotFound), "failed")))
+	assert.Equal(t, codes.Internal, status.Code(llmRegistryError(fmt.Errorf("boom"), "failed")))
+}
@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/hrygo/divinesense/plugin/mcpserver"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/store"
+)
+
+// ListMCPServers lists the MCP servers of the current user, by name.
+func (s *AIService) ListMCPServers(ctx context.Context, _ *v1pb.ListMCPServersRequest) (*v1pb.ListMCPServersResponse, error) {
+	user, err := s.mcpServerUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	servers, err := s.MCPServers.List(ctx, user.ID)
+	if err != nil {
+		return nil, mcpServerError(err, "failed to list mcp servers")
+	}
+	resp := &v1pb.ListMCPServersResponse{}
+	for _, server := range servers {
+		resp.Servers = append(resp.Servers, convertMCPServer(server))
+	}
+	return resp, nil
+}
+
+// CreateMCPServer registers an MCP server of the current user. Geek Mode
+// sessions pick it up at their next request; stdio servers only in workspaces
+// whose tool policy permits Bash.
+func (s *AIService) CreateMCPServer(ctx context.Context, req *v1pb.CreateMCPServerRequest) (*v1pb.MCPServer, error) {
+	user, err := s.mcpServerUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	server := &mcpserver.Server{
+		UserID:    user.ID,
+		Name:      req.Name,
+		Transport: req.Transport,
+		Command:   req.Command,
+		Args:      req.Args,
+		Env:       req.Env,
+		URL:       req.Url,
+		Headers:   req.Headers,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+	}
+	created, err := s.MCPServers.Create(ctx, server)
+	if err != nil {
+		return nil, mcpServerError(err, "failed to create mcp server")
+	}
+	return convertMCPServer(created), nil
+}
+
+// UpdateMCPServer replaces an MCP server of the current user. Empty env and
+// headers keep the current ones unless clear_env or clear_headers is set.
+func (s *AIService) UpdateMCPServer(ctx context.Context, req *v1pb.UpdateMCPServerRequest) (*v1pb.MCPServer, error) {
+	user, err := s.mcpServerUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	server := &mcpserver.Server{
+		ID:        req.Id,
+		UserID:    user.ID,
+		Name:      req.Name,
+		Transport: req.Transport,
+		Command:   req.Command,
+		Args:      req.Args,
+		URL:       req.Url,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+	}
+	if len(req.Env) > 0 || req.ClearEnv {
+		server.Env = req.Env
+		if server.Env == nil {
+			server.Env = map[string]string{}
+		}
+	}
+	if len(req.Headers) > 0 || req.ClearHeaders {
+		server.Headers = req.Headers
+		if server.Headers == nil {
+			server.Headers = map[string]string{}
+		}
+	}
+	updated, err := s.MCPServers.Update(ctx, server)
+	if err != nil {
+		return nil, mcpServerError(err, "failed to update mcp server")
+	}
+	return convertMCPServer(updated), nil
+}
+
+// DeleteMCPServer removes an MCP server of the current user.
+func (s *AIService) DeleteMCPServer(ctx context.Context, req *v1pb.DeleteMCPServerRequest) (*emptypb.Empty, error) {
+	user, err := s.mcpServerUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.MCPServers.Delete(ctx, user.ID, req.Id); err != nil {
+		return nil, mcpServerError(err, "failed to delete mcp server")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// mcpServerUser returns the current user once MCP servers are configured.
+func (s *AIService) mcpServerUser(ctx context.Context) (*store.User, error) {
+	if s.MCPServers == nil {
+		return nil, status.Errorf(codes.Unavailable, "mcp servers are not available")
+	}
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthorized")
+	}
+	return user, nil
+}
+
+// mcpServerError maps the errors of the MCP servers to statuses.
+func mcpServerError(err error, msg string) error {
+	switch {
+	case errors.Is(err, mcpserver.ErrInvalid):
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	case errors.Is(err, mcpserver.ErrNotFound):
+		return status.Errorf(codes.NotFound, "%v", err)
+	}
+	slog.Error(msg, "error", err)
+	return status.Errorf(codes.Internal, "%s", msg)
+}
+
+// convertMCPServer converts a server, leaving out its environment variables
+// and headers but their names.
+func convertMCPServer(server *mcpserver.Server) *v1pb.MCPServer {
+	return &v1pb.MCPServer{
+		Id:          server.ID,
+		Name:        server.Name,
+		Transport:   server.Transport,
+		Command:     server.Command,
+		Args:        server.Args,
+		Url:         server.URL,
+		Enabled:     server.Enabled,
+		EnvNames:    server.EnvNames,
+		HeaderNames: server.HeaderNames,
+		CreatedTs:   server.CreatedTs,
+		UpdatedTs:   server.UpdatedTs,
+	}
+}
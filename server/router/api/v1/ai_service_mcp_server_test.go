@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hrygo/divinesense/plugin/mcpserver"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+)
+
+func TestMCPServerError(t *testing.T) {
+	err := mcpServerError(fmt.Errorf("%w: name is required", mcpserver.ErrInvalid), "failed")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, codes.NotFound, status.Code(mcpServerError(mcpserver.ErrNotFound, "failed")))
+
+	err = mcpServerError(errors.New("connection refused"), "failed to list mcp servers")
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "failed to list mcp servers", status.Convert(err).Message(), "store errors are not leaked")
+}
+
+func TestConvertMCPServer(t *testing.T) {
+	server := convertMCPServer(&mcpserver.Server{
+		ID:        3,
+		Name:      "github",
+		Transport: "stdio",
+		Command:   "npx",
+		Env:       map[string]string{"GITHUB_TOKEN": "secret"},
+		EnvNames:  []string{"GITHUB_TOKEN"},
+		Enabled:   true,
+	})
+	assert.Equal(t, int32(3), server.Id)
+	assert.Equal(t, []string{"GITHUB_TOKEN"}, server.EnvNames)
+	assert.NotContains(t, server.String(), "secret")
+}
+
+func TestMCPServersUnavailable(t *testing.T) {
+	_, err := (&AIService{}).ListMCPServers(context.Background(), &v1pb.ListMCPServersRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
@@ -0,0 +1,192 @@
+package v1
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hrygo/divinesense/ai/routing"
+	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
+	"github.com/hrygo/divinesense/store"
+)
+
+// SelectAgentRequest is the body of SelectAgent.
+type SelectAgentRequest struct {
+	Agent    aichat.AgentType `json:"agent"`
+	Timezone string           `json:"timezone"`
+}
+
+// SelectAgent answers the agent_suggestions pending on blockID: the choice is
+// recorded as routing feedback and the chosen expert handles the original
+// message in the same block. The response is streamed like a Chat response.
+func (s *AIService) SelectAgent(ctx context.Context, user *store.User, blockID int64, req *SelectAgentRequest, stream aichat.ChatStream) error {
+	if !s.IsEnabled() {
+		return status.Errorf(codes.Unavailable, "AI features are disabled")
+	}
+	if !s.IsLLMEnabled() {
+		return status.Errorf(codes.Unavailable, "LLM service is not available")
+	}
+	if !globalAILimiter.Allow(strconv.FormatInt(int64(user.ID), 10)) {
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded")
+	}
+
+	block, err := s.Store.GetAIBlock(ctx, blockID)
+	if err != nil || block == nil {
+		return status.Errorf(codes.NotFound, "block not found")
+	}
+	conversations, err := s.Store.ListAIConversations(ctx, &store.FindAIConversation{
+		ID:        &block.ConversationID,
+		CreatorID: &user.ID,
+	})
+	if err != nil || len(conversations) == 0 {
+		return status.Errorf(codes.PermissionDenied, "access denied to this block")
+	}
+
+	suggestions, ok := aichat.PendingAgentSuggestionsFromBlock(block)
+	if !ok {
+		return status.Errorf(codes.FailedPrecondition, "no agent choice is pending on this block")
+	}
+	if !suggestions.Has(req.Agent) {
+		return status.Errorf(codes.InvalidArgument, "agent %s is not one of the suggestions", req.Agent)
+	}
+
+	// Clear the pending choice before resuming, so a double submit is rejected.
+	if _, err := s.Store.UpdateAIBlock(ctx, &store.UpdateAIBlock{
+		ID:       blockID,
+		Metadata: map[string]any{aichat.PendingAgentChoiceMetadataKey: nil},
+	}); err != nil {
+		return status.Errorf(codes.Internal, "failed to clear agent choice: %v", err)
+	}
+
+	predicted := suggestions.Predicted()
+	s.recordAgentChoice(ctx, user.ID, suggestions.Message, predicted, req.Agent)
+
+	chatReq := &aichat.ChatRequest{
+		Message:         suggestions.Message,
+		AgentType:       req.Agent,
+		Timezone:        req.Timezone,
+		UserID:          user.ID,
+		ConversationID:  block.ConversationID,
+		ContinueBlockID: blockID,
+		Budget:          s.blockBudget(ctx, user.ID),
+	}
+	if chatReq.Timezone == "" || !aichat.IsValidTimezone(chatReq.Timezone) {
+		chatReq.Timezone = aichat.GetDefaultTimezone()
+	}
+
+	slog.Info("ai.chat.agent_selected",
+		"user_id", user.ID,
+		"block_id", blockID,
+		"predicted", predicted,
+		"selected", req.Agent,
+	)
+
+	collectingStream := &eventCollectingStream{
+		grpcStreamWrapper: &grpcStreamWrapper{stream: stream},
+		service:           s,
+		eventBus:          s.getChatEventBus(),
+		userID:            user.ID,
+		agentType:         chatReq.AgentType,
+		conversationID:    chatReq.ConversationID,
+	}
+	handler, err := s.getChatHandler()
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "chat is not available: %v", err)
+	}
+	if err := handler.Handle(ctx, chatReq, collectingStream); err != nil {
+		return aichat.HandleError(err)
+	}
+	return nil
+}
+
+// recordAgentChoice feeds the user's pick back to the router: confirming the
+// prediction reinforces it, picking another expert counts as a switch.
+func (s *AIService) recordAgentChoice(ctx context.Context, userID int32, message string, predicted, selected aichat.AgentType) {
+	routerSvc := s.getRouterService()
+	if routerSvc == nil {
+		return
+	}
+	feedbackType := routing.FeedbackSwitch
+	if predicted == selected {
+		feedbackType = routing.FeedbackPositive
+	}
+	if err := routerSvc.RecordFeedback(ctx, &routing.RouterFeedback{
+		UserID:    userID,
+		Input:     message,
+		Predicted: aichat.AgentTypeIntent(predicted),
+		Actual:    aichat.AgentTypeIntent(selected),
+		Feedback:  feedbackType,
+		Timestamp: time.Now().Unix(),
+		Source:    "agent_suggestions",
+	}); err != nil {
+		slog.Warn("failed to record agent choice feedback", "user_id", userID, "error", err)
+	}
+}
+
+// registerRoutingRoutes registers the routing settings and agent selection endpoints.
+func (s *APIV1Service) registerRoutingRoutes(group *echo.Group) {
+	group.GET("/ai/routing-settings", s.GetRoutingSettings)
+	group.PUT("/ai/routing-settings", s.UpdateRoutingSettings)
+	group.POST("/ai/blocks/:id/agent", s.SelectAgent)
+}
+
+// GET /api/v1/system/ai/routing-settings.
+func (s *APIV1Service) GetRoutingSettings(c echo.Context) error {
+	settings, err := aichat.GetRoutingSettings(c.Request().Context(), s.Store, restCurrentUser(c).ID)
+	if err != nil {
+		slog.Error("failed to get routing settings", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get routing settings")
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+// PUT /api/v1/system/ai/routing-settings.
+func (s *APIV1Service) UpdateRoutingSettings(c echo.Context) error {
+	settings := &aichat.RoutingSettings{}
+	if err := c.Bind(settings); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := settings.Validate(); err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	if err := aichat.UpdateRoutingSettings(c.Request().Context(), s.Store, restCurrentUser(c).ID, settings); err != nil {
+		slog.Error("failed to update routing settings", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to update routing settings")
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+// POST /api/v1/system/ai/blocks/:id/agent.
+// Validation errors are returned as JSON; once the agent starts, the response
+// switches to a text/event-stream of ChatResponse messages.
+func (s *APIV1Service) SelectAgent(c echo.Context) error {
+	if s.AIService == nil {
+		return restError(c, http.StatusServiceUnavailable, "AI features are disabled")
+	}
+	blockID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid block id")
+	}
+	req := &SelectAgentRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	stream := &sseChatStream{c: c}
+	err = s.AIService.SelectAgent(c.Request().Context(), restCurrentUser(c), blockID, req, stream)
+	if err == nil {
+		return nil
+	}
+	if stream.started {
+		slog.Warn("agent selection stream failed", "block_id", blockID, "error", err)
+		return stream.Send(aichat.NewErrorEvent(err, blockID))
+	}
+	return restError(c, runtime.HTTPStatusFromCode(status.Code(err)), status.Convert(err).Message())
+}
@@ -0,0 +1,254 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/hrygo/divinesense/plugin/scheduledmsg"
+	"github.com/hrygo/divinesense/plugin/webhook"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/server/auth"
+	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
+	"github.com/hrygo/divinesense/store"
+)
+
+// ScheduledMessageRequest is the body of POST /api/v1/system/ai/scheduled-messages.
+type ScheduledMessageRequest struct {
+	ConversationID int32  `json:"conversation_id"`
+	Message        string `json:"message"`
+	// ScheduledTime is the RFC 3339 time to send the message at.
+	ScheduledTime string `json:"scheduled_time"`
+	// AgentType is an AgentType enum name, e.g. AGENT_TYPE_MEMO. Optional.
+	AgentType string `json:"agent_type"`
+	// Timezone is the IANA timezone of the chat request. Optional.
+	Timezone string `json:"timezone"`
+	// Urgent sends the message even during the user's quiet hours.
+	Urgent bool `json:"urgent"`
+}
+
+// registerScheduledMessageRoutes registers the API for scheduling chat messages.
+func (s *APIV1Service) registerScheduledMessageRoutes(group *echo.Group) {
+	if s.scheduledMessageStore == nil || s.AIService == nil {
+		return
+	}
+	messages := group.Group("/ai/scheduled-messages")
+	messages.GET("", s.ListScheduledMessages)
+	messages.POST("", s.CreateScheduledMessage)
+	messages.DELETE("/:id", s.CancelScheduledMessage)
+}
+
+// GET /api/v1/system/ai/scheduled-messages?conversation_id=.
+// Lists the current user's scheduled messages, including dispatched ones.
+func (s *APIV1Service) ListScheduledMessages(c echo.Context) error {
+	user := restCurrentUser(c)
+	find := &scheduledmsg.FindMessage{CreatorID: &user.ID}
+	if raw := c.QueryParam("conversation_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return restError(c, http.StatusBadRequest, "invalid conversation_id")
+		}
+		conversationID := int32(id)
+		find.ConversationID = &conversationID
+	}
+
+	messages, err := s.scheduledMessageStore.ListMessages(c.Request().Context(), find)
+	if err != nil {
+		slog.Error("failed to list scheduled messages", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to list scheduled messages")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"messages": messages})
+}
+
+// POST /api/v1/system/ai/scheduled-messages.
+func (s *APIV1Service) CreateScheduledMessage(c echo.Context) error {
+	req := &ScheduledMessageRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	scheduledAt, err := time.Parse(time.RFC3339, req.ScheduledTime)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "scheduled_time must be an RFC 3339 time")
+	}
+	if _, ok := v1pb.AgentType_value[req.AgentType]; req.AgentType != "" && !ok {
+		return restError(c, http.StatusBadRequest, "unknown agent_type")
+	}
+	if req.Timezone != "" && !aichat.IsValidTimezone(req.Timezone) {
+		return restError(c, http.StatusBadRequest, "invalid timezone")
+	}
+
+	user := restCurrentUser(c)
+	msg := &scheduledmsg.Message{
+		CreatorID:      user.ID,
+		ConversationID: req.ConversationID,
+		Message:        req.Message,
+		AgentType:      req.AgentType,
+		Timezone:       req.Timezone,
+		ScheduledTs:    scheduledAt.Unix(),
+		Urgent:         req.Urgent,
+	}
+	if err := msg.Validate(time.Now()); err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	conversations, err := s.Store.ListAIConversations(ctx, &store.FindAIConversation{ID: &req.ConversationID, CreatorID: &user.ID})
+	if err != nil {
+		slog.Error("failed to get conversation", "conversation_id", req.ConversationID, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get conversation")
+	}
+	if len(conversations) == 0 {
+		return restError(c, http.StatusNotFound, "conversation not found")
+	}
+	pending, err := s.scheduledMessageStore.CountPending(ctx, user.ID)
+	if err != nil {
+		slog.Error("failed to count scheduled messages", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to create scheduled message")
+	}
+	if pending >= scheduledmsg.MaxPendingPerUser {
+		return restError(c, http.StatusTooManyRequests, fmt.Sprintf("at most %d messages can be pending", scheduledmsg.MaxPendingPerUser))
+	}
+
+	created, err := s.scheduledMessageStore.CreateMessage(ctx, msg)
+	if err != nil {
+		slog.Error("failed to create scheduled message", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to create scheduled message")
+	}
+	return c.JSON(http.StatusOK, created)
+}
+
+// DELETE /api/v1/system/ai/scheduled-messages/:id.
+// Cancels a pending message; dispatched messages cannot be canceled.
+func (s *APIV1Service) CancelScheduledMessage(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid message id")
+	}
+	canceled, err := s.scheduledMessageStore.CancelMessage(c.Request().Context(), int32(id), restCurrentUser(c).ID)
+	if err != nil {
+		slog.Error("failed to cancel scheduled message", "id", id, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to cancel scheduled message")
+	}
+	if !canceled {
+		return restError(c, http.StatusNotFound, "no pending scheduled message found")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// NewScheduledMessageDispatcher returns the dispatcher of scheduled messages,
+// or nil when scheduling is unavailable (AI disabled or not PostgreSQL).
+func (s *APIV1Service) NewScheduledMessageDispatcher() *scheduledmsg.Dispatcher {
+	if s.scheduledMessageStore == nil || s.AIService == nil {
+		return nil
+	}
+	return scheduledmsg.NewDispatcher(s.scheduledMessageStore, s.AIService.SendScheduledMessage, s.notifyScheduledMessage)
+}
+
+// SendScheduledMessage sends a scheduled message through the chat path, as if
+// its creator typed it, and returns the block holding the answer.
+func (s *AIService) SendScheduledMessage(ctx context.Context, msg *scheduledmsg.Message) (int64, error) {
+	user, err := s.Store.GetUser(ctx, &store.FindUser{ID: &msg.CreatorID})
+	if err != nil {
+		return 0, fmt.Errorf("get user %d: %w", msg.CreatorID, err)
+	}
+	if user == nil {
+		return 0, fmt.Errorf("user %d not found", msg.CreatorID)
+	}
+
+	stream := &scheduledMessageStream{ctx: auth.SetUserInContext(ctx, user, "")}
+	err = s.Chat(&v1pb.ChatRequest{
+		Message:        msg.Message,
+		AgentType:      v1pb.AgentType(v1pb.AgentType_value[msg.AgentType]),
+		ConversationId: msg.ConversationID,
+		UserTimezone:   msg.Timezone,
+	}, stream)
+	if err != nil {
+		// Record the user-facing message of the failure.
+		return stream.blockID, errors.New(status.Convert(aichat.HandleError(err)).Message())
+	}
+	return stream.blockID, nil
+}
+
+// notifyScheduledMessage notifies the creator of a dispatched message through
+// their webhooks. Only urgent messages are notified during quiet hours; the
+// answer of the others stays in the conversation.
+func (s *APIV1Service) notifyScheduledMessage(ctx context.Context, msg *scheduledmsg.Message) {
+	if !msg.Urgent {
+		until, err := s.QuietHours.QuietUntil(ctx, msg.CreatorID, time.Now())
+		if err != nil {
+			slog.Warn("failed to get quiet hours for scheduled message", "id", msg.ID, "error", err)
+		} else if !until.IsZero() {
+			slog.Info("scheduled message notification skipped during quiet hours", "id", msg.ID, "quiet_until", until.Unix())
+			return
+		}
+	}
+	webhooks, err := s.Store.GetUserWebhooks(ctx, msg.CreatorID)
+	if err != nil {
+		slog.Warn("failed to get webhooks for scheduled message", "id", msg.ID, "error", err)
+		return
+	}
+	activityType := "memos.ai.scheduled_message.completed"
+	if msg.Status == scheduledmsg.StatusFailed {
+		activityType = "memos.ai.scheduled_message.failed"
+	}
+	for _, hook := range webhooks {
+		webhook.PostAsync(&webhook.WebhookRequestPayload{
+			URL:          hook.Url,
+			ActivityType: activityType,
+			Creator:      fmt.Sprintf("%s%d", UserNamePrefix, msg.CreatorID),
+			Chat: &webhook.ChatPayload{
+				ConversationID: msg.ConversationID,
+				BlockID:        msg.BlockID,
+				Message:        msg.Message,
+				Status:         string(msg.Status),
+				Error:          msg.Error,
+			},
+		})
+	}
+}
+
+// scheduledMessageStream runs a scheduled message through AIService.Chat,
+// recording the block announced by the block_created event.
+type scheduledMessageStream struct {
+	ctx context.Context
+
+	mu      sync.Mutex
+	blockID int64
+}
+
+func (s *scheduledMessageStream) Send(resp *v1pb.ChatResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if resp.EventType == "block_created" && s.blockID == 0 {
+		s.blockID = resp.BlockId
+	}
+	return nil
+}
+
+func (s *scheduledMessageStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *scheduledMessageStream) SendMsg(m any) error {
+	if resp, ok := m.(*v1pb.ChatResponse); ok {
+		return s.Send(resp)
+	}
+	return fmt.Errorf("invalid message type: %T", m)
+}
+
+func (s *scheduledMessageStream) RecvMsg(any) error {
+	return fmt.Errorf("RecvMsg not supported for server streaming")
+}
+
+func (s *scheduledMessageStream) SetHeader(metadata.MD) error  { return nil }
+func (s *scheduledMessageStream) SendHeader(metadata.MD) error { return nil }
+func (s *scheduledMessageStream) SetTrailer(metadata.MD)       {}
@@ -0,0 +1,304 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/lithammer/shortuuid/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hrygo/divinesense/ai/core/llm"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
+	"github.com/hrygo/divinesense/store"
+)
+
+const (
+	// selfTestMessage is the chat message of a self-test run.
+	selfTestMessage = "Self-test: reply with the single word OK."
+	// selfTestMockModel is the model reported by the mock LLM of a self-test.
+	selfTestMockModel = "self-test-mock"
+	selfTestTimeout   = 60 * time.Second
+)
+
+// RunSelfTest runs the self-test of the chat pipeline as the current user.
+// Failed checks are reported in the response rather than as an error, so
+// monitoring can read which step broke.
+func (s *AIService) RunSelfTest(ctx context.Context, req *v1pb.RunSelfTestRequest) (*v1pb.SelfTestReport, error) {
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthorized")
+	}
+	if !isSuperUser(user) {
+		return nil, status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+	return s.runSelfTest(ctx, user, req.Live), nil
+}
+
+// runSelfTest runs a tiny chat end to end as user in a throwaway conversation,
+// checking the streamed events, the persisted block and its session stats.
+// Unless live is set, the chat runs against a mock LLM, so the test is free
+// and independent of the provider.
+func (s *AIService) runSelfTest(ctx context.Context, user *store.User, live bool) *v1pb.SelfTestReport {
+	startedAt := time.Now()
+	report := &v1pb.SelfTestReport{Mode: "mock"}
+	if live {
+		report.Mode = "live"
+	}
+	check := func(name string, run func() (string, error)) bool {
+		stepStart := time.Now()
+		detail, err := run()
+		result := &v1pb.SelfTestCheck{Name: name, Passed: err == nil, Detail: detail, DurationMs: time.Since(stepStart).Milliseconds()}
+		if err != nil {
+			result.Detail = err.Error()
+		}
+		report.Checks = append(report.Checks, result)
+		return result.Passed
+	}
+	defer func() {
+		report.Passed = len(report.Checks) > 0
+		for _, c := range report.Checks {
+			report.Passed = report.Passed && c.Passed
+		}
+		report.DurationMs = time.Since(startedAt).Milliseconds()
+		slog.Info("ai.self_test.completed", "mode", report.Mode, "passed", report.Passed, "duration_ms", report.DurationMs)
+	}()
+
+	var handler aichat.Handler
+	if !check("handler", func() (string, error) {
+		var err error
+		handler, err = s.selfTestHandler(live)
+		return "", err
+	}) {
+		return report
+	}
+	// The mock handler owns its own CLI runners; release them with the run.
+	if closer, ok := handler.(io.Closer); ok && !live {
+		defer closer.Close()
+	}
+
+	var conversation *store.AIConversation
+	if !check("conversation", func() (string, error) {
+		now := time.Now().Unix()
+		var err error
+		conversation, err = s.Store.CreateAIConversation(ctx, &store.AIConversation{
+			UID:         shortuuid.New(),
+			CreatorID:   user.ID,
+			Title:       "Self-test",
+			TitleSource: store.TitleSourceDefault,
+			CreatedTs:   now,
+			UpdatedTs:   now,
+			RowStatus:   store.Normal,
+		})
+		if err != nil {
+			return "", fmt.Errorf("create conversation: %w", err)
+		}
+		return fmt.Sprintf("conversation %d", conversation.ID), nil
+	}) {
+		return report
+	}
+	// The conversation's blocks are deleted with it.
+	defer func() {
+		if err := s.Store.DeleteAIConversation(context.WithoutCancel(ctx), &store.DeleteAIConversation{ID: conversation.ID}); err != nil {
+			slog.Warn("failed to delete self-test conversation", "conversation_id", conversation.ID, "error", err)
+		}
+	}()
+
+	runCtx, cancel := context.WithTimeout(ctx, selfTestTimeout)
+	defer cancel()
+	stream := &selfTestStream{ctx: runCtx}
+	if !check("chat", func() (string, error) {
+		err := handler.Handle(runCtx, &aichat.ChatRequest{
+			Message:        selfTestMessage,
+			AgentType:      aichat.AgentTypeSchedule,
+			Timezone:       aichat.GetDefaultTimezone(),
+			UserID:         user.ID,
+			ConversationID: conversation.ID,
+		}, stream)
+		if err != nil {
+			return "", aichat.HandleError(err)
+		}
+		return "", nil
+	}) {
+		return report
+	}
+
+	check("events", func() (string, error) {
+		return stream.verify()
+	})
+
+	var block *store.AIBlock
+	if !check("block", func() (string, error) {
+		blockID := stream.blockID()
+		if blockID == 0 {
+			return "", fmt.Errorf("no block was created")
+		}
+		var err error
+		block, err = s.Store.GetAIBlock(ctx, blockID)
+		if err != nil {
+			return "", fmt.Errorf("get block %d: %w", blockID, err)
+		}
+		if block == nil {
+			return "", fmt.Errorf("block %d was not persisted", blockID)
+		}
+		if block.Status != store.AIBlockStatusCompleted {
+			return "", fmt.Errorf("block %d has status %q", blockID, block.Status)
+		}
+		if block.AssistantContent == "" {
+			return "", fmt.Errorf("block %d has no assistant content", blockID)
+		}
+		return fmt.Sprintf("block %d", blockID), nil
+	}) {
+		return report
+	}
+
+	check("stats", func() (string, error) {
+		stats := block.SessionStats
+		if stats == nil {
+			return "", fmt.Errorf("block %d has no session stats", block.ID)
+		}
+		if stats.InputTokens+stats.OutputTokens == 0 {
+			return "", fmt.Errorf("block %d recorded no token usage", block.ID)
+		}
+		return fmt.Sprintf("%d input / %d output tokens, model %q", stats.InputTokens, stats.OutputTokens, stats.ModelUsed), nil
+	})
+	return report
+}
+
+// selfTestHandler returns the chat handler of a self-test: the shared handler
+// for live runs, or a handler wired like it around a mock LLM. The caller
+// closes the mock handler.
+func (s *AIService) selfTestHandler(live bool) (aichat.Handler, error) {
+	if s.UniversalParrotConfig == nil || !s.UniversalParrotConfig.Enabled {
+		return nil, fmt.Errorf("UniversalParrot is not enabled")
+	}
+	if live {
+		if s.LLMService == nil {
+			return nil, fmt.Errorf("LLM service is not configured")
+		}
+		return s.getChatHandler()
+	}
+
+	mock := &selfTestLLM{}
+	factory := aichat.NewAgentFactory(mock, s.AdaptiveRetriever, s.Store)
+	if err := factory.Initialize(s.UniversalParrotConfig); err != nil {
+		return nil, fmt.Errorf("initialize agent factory: %w", err)
+	}
+	handler, err := aichat.BuildParrotHandler(aichat.HandlerDeps{
+		Factory:        factory,
+		LLM:            mock,
+		BlockManager:   aichat.NewBlockManager(s.Store),
+		ContextBuilder: s.newContextBuilder(),
+		Persister:      s.persister,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return handler, nil
+}
+
+// selfTestStream collects the events of a self-test chat.
+type selfTestStream struct {
+	ctx context.Context
+
+	mu     sync.Mutex
+	events []*v1pb.ChatResponse
+}
+
+func (s *selfTestStream) Send(resp *v1pb.ChatResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, resp)
+	return nil
+}
+
+func (s *selfTestStream) Context() context.Context {
+	return s.ctx
+}
+
+// blockID returns the block announced by the block_created event, or 0.
+func (s *selfTestStream) blockID() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.events {
+		if e.EventType == "block_created" {
+			return e.BlockId
+		}
+	}
+	return 0
+}
+
+// verify checks that the stream announced a block, streamed an answer and
+// ended with a done event carrying the block summary.
+func (s *selfTestStream) verify() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var created, answered bool
+	var done *v1pb.ChatResponse
+	for _, e := range s.events {
+		switch {
+		case e.Done:
+			done = e
+		case e.EventType == "block_created":
+			created = true
+		case e.EventType == "answer" || e.EventType == "content":
+			answered = answered || e.EventData != ""
+		}
+	}
+	switch {
+	case !created:
+		return "", fmt.Errorf("no block_created event")
+	case !answered:
+		return "", fmt.Errorf("no answer was streamed")
+	case done == nil:
+		return "", fmt.Errorf("no done event")
+	case done.EventType == aichat.EventTypeError:
+		return "", fmt.Errorf("stream ended with an error: %s", done.EventData)
+	case done.BlockSummary == nil:
+		return "", fmt.Errorf("done event has no block summary")
+	case done.BlockSummary.Status != "success":
+		return "", fmt.Errorf("block summary has status %q", done.BlockSummary.Status)
+	}
+	return fmt.Sprintf("%d events", len(s.events)), nil
+}
+
+// selfTestLLM is the mock LLM of a self-test. It answers every call with "OK"
+// and reports fixed usage, without calling tools.
+type selfTestLLM struct{}
+
+func (m *selfTestLLM) stats() *llm.LLMCallStats {
+	return &llm.LLMCallStats{
+		PromptTokens:     20,
+		CompletionTokens: 1,
+		TotalTokens:      21,
+		Model:            selfTestMockModel,
+	}
+}
+
+func (m *selfTestLLM) Chat(_ context.Context, _ []llm.Message) (string, *llm.LLMCallStats, error) {
+	return "OK", m.stats(), nil
+}
+
+func (m *selfTestLLM) ChatStream(_ context.Context, _ []llm.Message) (<-chan string, <-chan *llm.LLMCallStats, <-chan error) {
+	content := make(chan string, 1)
+	stats := make(chan *llm.LLMCallStats, 1)
+	errs := make(chan error)
+	content <- "OK"
+	stats <- m.stats()
+	close(content)
+	close(stats)
+	close(errs)
+	return content, stats, errs
+}
+
+func (m *selfTestLLM) ChatWithTools(_ context.Context, _ []llm.Message, _ []llm.ToolDescriptor) (*llm.ChatResponse, *llm.LLMCallStats, error) {
+	return &llm.ChatResponse{Content: "OK"}, m.stats(), nil
+}
+
+func (m *selfTestLLM) Warmup(context.Context) {}
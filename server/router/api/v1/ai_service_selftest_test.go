@@ -0,0 +1,46 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+)
+
+func TestRunSelfTestRequiresUser(t *testing.T) {
+	_, err := (&AIService{}).RunSelfTest(context.Background(), &v1pb.RunSelfTestRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestSelfTestStreamVerify(t *testing.T) {
+	stream := &selfTestStream{ctx: context.Background()}
+	_, err := stream.verify()
+	require.ErrorContains(t, err, "block_created")
+
+	require.NoError(t, stream.Send(&v1pb.ChatResponse{EventType: "block_created", BlockId: 42}))
+	require.NoError(t, stream.Send(&v1pb.ChatResponse{EventType: "answer", EventData: "OK", BlockId: 42}))
+	_, err = stream.verify()
+	require.ErrorContains(t, err, "no done event")
+
+	require.NoError(t, stream.Send(&v1pb.ChatResponse{
+		Done:         true,
+		BlockId:      42,
+		BlockSummary: &v1pb.BlockSummary{Status: "success"},
+	}))
+	detail, err := stream.verify()
+	require.NoError(t, err)
+	require.Equal(t, "3 events", detail)
+	require.Equal(t, int64(42), stream.blockID())
+}
+
+func TestSelfTestLLMStream(t *testing.T) {
+	content, stats, errs := (&selfTestLLM{}).ChatStream(context.Background(), nil)
+	require.Equal(t, "OK", <-content)
+	require.Equal(t, selfTestMockModel, (<-stats).Model)
+	_, open := <-errs
+	require.False(t, open)
+}
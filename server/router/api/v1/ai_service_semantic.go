@@ -288,7 +288,8 @@ func (s *AIService) GetRelatedMemos(ctx context.Context, req *v1pb.GetRelatedMem
 	}
 
 	// Get embedding for the memo
-	embedding, err := s.Store.GetMemoEmbedding(ctx, memo.ID, "BAAI/bge-m3")
+	model := s.Store.EmbeddingModel()
+	embedding, err := s.Store.GetMemoEmbedding(ctx, memo.ID, model)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get memo embedding: %v", err)
 	}
@@ -304,7 +305,7 @@ func (s *AIService) GetRelatedMemos(ctx context.Context, req *v1pb.GetRelatedMem
 		_, _ = s.Store.UpsertMemoEmbedding(ctx, &store.MemoEmbedding{
 			MemoID:    memo.ID,
 			Embedding: vector,
-			Model:     "BAAI/bge-m3",
+			Model:     model,
 		})
 	} else {
 		vector = embedding.Embedding
@@ -320,6 +321,7 @@ func (s *AIService) GetRelatedMemos(ctx context.Context, req *v1pb.GetRelatedMem
 		UserID: user.ID,
 		Vector: vector,
 		Limit:  limit + 1, // +1 to exclude the original memo
+		Model:  model,
 	})
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to search: %v", err)
@@ -237,6 +237,37 @@ func (s *AIService) GetCostStats(ctx context.Context, req *v1pb.GetCostStatsRequ
 	}, nil
 }
 
+// GetUsage retrieves the user's AI usage of the current month against the quotas.
+func (s *AIService) GetUsage(ctx context.Context, _ *v1pb.GetUsageRequest) (*v1pb.Usage, error) {
+	if s.Quotas == nil {
+		return nil, status.Error(codes.Unavailable, "usage quotas not available")
+	}
+
+	userID := auth.GetUserID(ctx)
+	if userID == 0 {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+
+	usage, err := s.Quotas.Usage(ctx, userID)
+	if err != nil {
+		slog.Warn("Failed to get usage",
+			"user_id", userID,
+			"error", err)
+		return nil, status.Error(codes.Internal, "failed to get usage")
+	}
+
+	return &v1pb.Usage{
+		PeriodStart:  usage.PeriodStart.Unix(),
+		PeriodEnd:    usage.PeriodEnd.Unix(),
+		TotalTokens:  usage.TotalTokens,
+		TotalCostUsd: usage.TotalCostUSD,
+		SessionCount: usage.SessionCount,
+		TokenQuota:   usage.Quota.MonthlyTokens,
+		CostQuotaUsd: usage.Quota.MonthlyCostUSD,
+		Exceeded:     usage.Exceeded(),
+	}, nil
+}
+
 // GetUserCostSettings retrieves user-specific cost control settings.
 func (s *AIService) GetUserCostSettings(ctx context.Context, _ *emptypb.Empty) (*v1pb.UserCostSettings, error) {
 	if s.Store == nil {
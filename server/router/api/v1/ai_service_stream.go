@@ -0,0 +1,255 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/server/auth"
+	"github.com/hrygo/divinesense/store"
+)
+
+// streamTicketQueryParam carries the stream ticket of the EventSource clients,
+// which cannot set the Authorization header.
+const streamTicketQueryParam = "ticket"
+
+// registerChatStreamRoutes registers the SSE transport of Chat, for the
+// clients without gRPC or Connect (curl, mobile webviews, EventSource).
+func (s *APIV1Service) registerChatStreamRoutes(echoServer *echo.Echo, cors echo.MiddlewareFunc) {
+	group := echoServer.Group("/api/v1/ai/chat/stream", cors, s.chatStreamAuthMiddleware())
+	group.GET("", s.ChatStream)
+	group.POST("", s.ChatStream)
+}
+
+// GET|POST /api/v1/ai/chat/stream.
+// Streams the Chat events as a text/event-stream of ChatResponse messages,
+// the last one with done set. POST takes a JSON ChatRequest; GET takes its
+// fields as query parameters (?message=...&conversation_id=...&ticket=...) for
+// EventSource. Validation errors are returned as JSON before the stream starts.
+func (s *APIV1Service) ChatStream(c echo.Context) error {
+	if s.Profile.SafeMode {
+		return restSafeModeError(c)
+	}
+	if s.AIService == nil {
+		return restError(c, http.StatusServiceUnavailable, "AI features are disabled")
+	}
+	req, err := chatStreamRequest(c)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid chat request: "+err.Error())
+	}
+
+	header := c.Request().Header
+	ctx, peer, err := s.routeChat(c.Request().Context(), header, c.Response().Header(), req)
+	if err != nil {
+		return restStatusError(c, err)
+	}
+
+	stream := &sseChatStream{c: c}
+	if peer != nil {
+		forwardHeader, err := s.chatStreamForwardHeader(header, restCurrentUser(c))
+		if err != nil {
+			slog.Error("failed to authenticate forwarded chat stream", "error", err)
+			return restError(c, http.StatusInternalServerError, "failed to forward chat stream")
+		}
+		handled, err := s.forwardChat(ctx, peer, forwardHeader, req, stream)
+		if handled {
+			return chatStreamError(c, stream, req, err)
+		}
+	}
+	return chatStreamError(c, stream, req, s.AIService.chat(ctx, req, stream))
+}
+
+// chatStreamError ends a chat stream with err: returned as JSON before the
+// stream starts, only logged after, since chat reports failures in-band.
+func chatStreamError(c echo.Context, stream *sseChatStream, req *v1pb.ChatRequest, err error) error {
+	if err == nil {
+		return nil
+	}
+	if stream.started {
+		// Headers are already sent and chat reported the failure in-band.
+		slog.Warn("chat stream failed", "conversation_id", req.ConversationId, "error", err)
+		return nil
+	}
+	return restError(c, runtime.HTTPStatusFromCode(status.Code(err)), status.Convert(err).Message())
+}
+
+// chatStreamForwardHeader returns the headers of a chat stream forwarded to
+// the instance holding its CC session. Requests authenticated by a stream
+// ticket, which the peer cannot check again, get a fresh access token.
+func (s *APIV1Service) chatStreamForwardHeader(header http.Header, user *store.User) (http.Header, error) {
+	if header.Get(echo.HeaderAuthorization) != "" {
+		return header, nil
+	}
+	token, _, err := auth.GenerateAccessTokenV2(user.ID, user.Username, string(user.Role), string(user.RowStatus), []byte(s.Secret))
+	if err != nil {
+		return nil, err
+	}
+	forwarded := header.Clone()
+	forwarded.Set(echo.HeaderAuthorization, "Bearer "+token)
+	return forwarded, nil
+}
+
+// chatStreamRequest parses the ChatRequest of the query (GET) or of the JSON
+// body (POST).
+func chatStreamRequest(c echo.Context) (*v1pb.ChatRequest, error) {
+	req := &v1pb.ChatRequest{}
+	if c.Request().Method == http.MethodGet {
+		filter := utilities.NewDoubleArray([][]string{{streamTicketQueryParam}})
+		if err := runtime.PopulateQueryParameters(req, c.QueryParams(), filter); err != nil {
+			return nil, err
+		}
+		return req, nil
+	}
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// CreateChatStreamTicket issues a stream ticket of the current user, valid
+// for auth.StreamTicketDuration and accepted once.
+func (s *AIService) CreateChatStreamTicket(ctx context.Context, _ *v1pb.CreateChatStreamTicketRequest) (*v1pb.ChatStreamTicket, error) {
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "unauthorized")
+	}
+	ticket, expiresAt, err := auth.GenerateStreamTicket(user.ID, []byte(s.Secret))
+	if err != nil {
+		slog.Error("failed to generate stream ticket", "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to generate stream ticket")
+	}
+	return &v1pb.ChatStreamTicket{Ticket: ticket, ExpiresTs: expiresAt.Unix()}, nil
+}
+
+// chatStreamAuthMiddleware authenticates the chat stream requests by their
+// ticket query parameter, or else like restAuthMiddleware. The ticket is
+// removed from the request URL before anything else so that request logs
+// never record it.
+func (s *APIV1Service) chatStreamAuthMiddleware() echo.MiddlewareFunc {
+	authenticate := s.restAuthMiddleware()
+	tickets := &streamTickets{used: map[string]time.Time{}}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		byHeader := authenticate(next)
+		return func(c echo.Context) error {
+			ticket := c.QueryParam(streamTicketQueryParam)
+			if ticket == "" {
+				return byHeader(c)
+			}
+			stripQueryParam(c, streamTicketQueryParam)
+
+			user := s.streamTicketUser(c.Request().Context(), tickets, ticket)
+			if user == nil {
+				return restError(c, http.StatusUnauthorized, "invalid or used stream ticket")
+			}
+			ctx := withAuthResult(c.Request().Context(), &auth.AuthResult{User: user})
+			c.SetRequest(c.Request().WithContext(ctx))
+			c.Set(restUserContextKey, user)
+			return next(c)
+		}
+	}
+}
+
+// streamTicketUser returns the active user of a valid and unused ticket, nil
+// otherwise.
+func (s *APIV1Service) streamTicketUser(ctx context.Context, tickets *streamTickets, ticket string) *store.User {
+	claims, err := auth.ParseStreamTicket(ticket, []byte(s.Secret))
+	if err != nil || !tickets.use(claims.ID, claims.ExpiresAt.Time) {
+		return nil
+	}
+	userID, err := strconv.ParseInt(claims.Subject, 10, 32)
+	if err != nil {
+		return nil
+	}
+	id := int32(userID)
+	user, err := s.Store.GetUser(ctx, &store.FindUser{ID: &id})
+	if err != nil || user == nil || user.RowStatus == store.Archived {
+		return nil
+	}
+	return user
+}
+
+// stripQueryParam removes a query parameter from the request URL and from the
+// query params cached by c.
+func stripQueryParam(c echo.Context, name string) {
+	c.QueryParams().Del(name)
+	req := c.Request()
+	query := req.URL.Query()
+	query.Del(name)
+	req.URL.RawQuery = query.Encode()
+	req.RequestURI = req.URL.RequestURI()
+}
+
+// streamTickets remembers the stream tickets accepted by this instance until
+// they expire.
+type streamTickets struct {
+	mu   sync.Mutex
+	used map[string]time.Time
+}
+
+// use marks a ticket as used and reports whether it was not used before.
+func (t *streamTickets) use(id string, expiresAt time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	for usedID, usedExpiresAt := range t.used {
+		if now.After(usedExpiresAt) {
+			delete(t.used, usedID)
+		}
+	}
+	if _, ok := t.used[id]; ok {
+		return false
+	}
+	t.used[id] = expiresAt
+	return true
+}
+
+// sseChatStream streams ChatResponse messages as server-sent events for REST callers.
+// Headers are written on the first event so that failures before the agent starts
+// can still be returned as plain JSON errors.
+type sseChatStream struct {
+	c       echo.Context
+	mu      sync.Mutex
+	started bool
+}
+
+func (w *sseChatStream) Send(resp *v1pb.ChatResponse) error {
+	data, err := protojson.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.started {
+		w.started = true
+		header := w.c.Response().Header()
+		header.Set(echo.HeaderContentType, "text/event-stream")
+		header.Set("Cache-Control", "no-cache")
+		w.c.Response().WriteHeader(http.StatusOK)
+	}
+	if _, err := fmt.Fprintf(w.c.Response(), "data: %s\n\n", data); err != nil {
+		return err
+	}
+	w.c.Response().Flush()
+	return nil
+}
+
+func (w *sseChatStream) Context() context.Context {
+	return w.c.Request().Context()
+}
@@ -0,0 +1,94 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hrygo/divinesense/internal/profile"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+)
+
+func TestChatStreamRequest(t *testing.T) {
+	e := echo.New()
+	parse := func(req *http.Request) (string, int32, bool, error) {
+		parsed, err := chatStreamRequest(e.NewContext(req, httptest.NewRecorder()))
+		if err != nil {
+			return "", 0, false, err
+		}
+		return parsed.Message, parsed.ConversationId, parsed.GeekMode, nil
+	}
+
+	// EventSource clients pass the request as query parameters
+	message, conversationID, geekMode, err := parse(httptest.NewRequest(http.MethodGet,
+		"/api/v1/ai/chat/stream?message=hello%20there&conversation_id=7&geek_mode=true&ticket=secret", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "hello there", message)
+	assert.Equal(t, int32(7), conversationID)
+	assert.True(t, geekMode)
+
+	message, conversationID, _, err = parse(httptest.NewRequest(http.MethodPost,
+		"/api/v1/ai/chat/stream", strings.NewReader(`{"message": "hi", "conversationId": 3, "unknown": 1}`)))
+	require.NoError(t, err)
+	assert.Equal(t, "hi", message)
+	assert.Equal(t, int32(3), conversationID)
+
+	_, _, _, err = parse(httptest.NewRequest(http.MethodGet, "/api/v1/ai/chat/stream?conversation_id=seven", nil))
+	require.Error(t, err)
+	_, _, _, err = parse(httptest.NewRequest(http.MethodPost, "/api/v1/ai/chat/stream", strings.NewReader("not json")))
+	require.Error(t, err)
+}
+
+func TestChatStreamRoutes(t *testing.T) {
+	e := echo.New()
+	s := &APIV1Service{Profile: &profile.Profile{}}
+	cors := func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+	s.registerChatStreamRoutes(e, cors)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/ai/chat/stream?message=hi", nil))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// Tickets stand in for the Authorization header EventSource cannot set
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/ai/chat/stream?message=hi&ticket=forged", nil))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid or used stream ticket")
+
+	_, err := (&AIService{}).CreateChatStreamTicket(context.Background(), &v1pb.CreateChatStreamTicketRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestStripQueryParam(t *testing.T) {
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/ai/chat/stream?message=hi&ticket=abc", nil), httptest.NewRecorder())
+	require.Equal(t, "abc", c.QueryParam("ticket"))
+
+	stripQueryParam(c, "ticket")
+	assert.Empty(t, c.QueryParam("ticket"))
+	assert.Equal(t, "hi", c.QueryParam("message"))
+	assert.Equal(t, "message=hi", c.Request().URL.RawQuery)
+	assert.Equal(t, "/api/v1/ai/chat/stream?message=hi", c.Request().RequestURI)
+}
+
+func TestStreamTicketsUse(t *testing.T) {
+	tickets := &streamTickets{used: map[string]time.Time{}}
+	expiresAt := time.Now().Add(time.Minute)
+	assert.True(t, tickets.use("a", expiresAt))
+	assert.False(t, tickets.use("a", expiresAt))
+	assert.True(t, tickets.use("b", expiresAt))
+
+	// Expired tickets are forgotten; the signature check rejects them anyway
+	tickets.used["old"] = time.Now().Add(-time.Second)
+	assert.True(t, tickets.use("c", expiresAt))
+	assert.NotContains(t, tickets.used, "old")
+}
@@ -0,0 +1,39 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
+)
+
+// registerSuggestionRoutes registers the follow-up suggestion settings endpoints.
+func (s *APIV1Service) registerSuggestionRoutes(group *echo.Group) {
+	group.GET("/ai/suggestion-settings", s.GetSuggestionSettings)
+	group.PUT("/ai/suggestion-settings", s.UpdateSuggestionSettings)
+}
+
+// GET /api/v1/system/ai/suggestion-settings.
+func (s *APIV1Service) GetSuggestionSettings(c echo.Context) error {
+	settings, err := aichat.GetSuggestionSettings(c.Request().Context(), s.Store, restCurrentUser(c).ID)
+	if err != nil {
+		slog.Error("failed to get suggestion settings", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get suggestion settings")
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+// PUT /api/v1/system/ai/suggestion-settings.
+func (s *APIV1Service) UpdateSuggestionSettings(c echo.Context) error {
+	settings := &aichat.SuggestionSettings{}
+	if err := c.Bind(settings); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := aichat.UpdateSuggestionSettings(c.Request().Context(), s.Store, restCurrentUser(c).ID, settings); err != nil {
+		slog.Error("failed to update suggestion settings", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to update suggestion settings")
+	}
+	return c.JSON(http.StatusOK, settings)
+}
@@ -0,0 +1,165 @@
+package v1
+
+import (
+	"log/slog"
+	"time"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/ai/agents/orchestrator"
+	ctxpkg "github.com/hrygo/divinesense/ai/context"
+	"github.com/hrygo/divinesense/plugin/llmregistry"
+	"github.com/hrygo/divinesense/server/internal/observability"
+	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
+)
+
+// WireChat assembles the chat handler at startup, so that missing
+// dependencies fail the boot rather than the first chat. It is a no-op
+// without an LLM, when chat is unavailable anyway.
+func (s *AIService) WireChat() error {
+	if !s.IsLLMEnabled() {
+		return nil
+	}
+	_, err := s.getChatHandler()
+	return err
+}
+
+// getChatHandler returns the cached chat handler, wiring it on first use.
+// This avoids creating expensive components (ChatRouter, Orchestrator, etc.) on every request.
+func (s *AIService) getChatHandler() (aichat.Handler, error) {
+	s.chatHandlerMu.RLock()
+	if s.chatHandler != nil {
+		s.chatHandlerMu.RUnlock()
+		return s.chatHandler, nil
+	}
+	s.chatHandlerMu.RUnlock()
+
+	s.chatHandlerMu.Lock()
+	defer s.chatHandlerMu.Unlock()
+
+	// Double-check after acquiring write lock
+	if s.chatHandler != nil {
+		return s.chatHandler, nil
+	}
+
+	handler, err := s.wireChatHandler()
+	if err != nil {
+		return nil, err
+	}
+	s.chatHandler = handler
+	return s.chatHandler, nil
+}
+
+// wireChatHandler assembles the chat handler with all routing components.
+func (s *AIService) wireChatHandler() (aichat.Handler, error) {
+	// Get cached agent factory (initializes on first use)
+	factory := s.getAgentFactory()
+
+	// Follow-up suggestions use the simple-task model, falling back to the chat model
+	suggestionLLM := s.IntentLLMService
+	if suggestionLLM == nil {
+		suggestionLLM = s.LLMService
+	}
+
+	// Phase 5: Unified Block Model support
+	blockManager := aichat.NewBlockManager(s.Store)
+	if s.Webhooks != nil {
+		blockManager.OnStatusChange(s.publishBlockWebhook)
+	}
+
+	// Persistence queues are exposed at /metrics
+	observability.SetBlockQueueSource(blockManager.QueueDepth, blockManager.DroppedEvents)
+	if s.persister != nil {
+		observability.SetPersisterSource(s.persister.QueueSize, s.persister.Dropped)
+	}
+
+	deps := aichat.HandlerDeps{
+		Factory:         factory,
+		LLM:             s.LLMService,
+		BlockManager:    blockManager,
+		ContextBuilder:  s.newContextBuilder(),
+		Persister:       s.persister,
+		TitleGenerator:  s.TitleGenerator,
+		SuggestionLLM:   suggestionLLM,
+		Canaries:        s.Canaries,
+		FeatureFlags:    s.FeatureFlags,
+		Shadows:         s.Shadows,
+		LoadShedder:     s.LoadShedder,
+		ToolPolicies:    s.ToolPolicies,
+		MCPServers:      s.MCPServers,
+		GitHub:          s.GitHub,
+		EvolutionTasks:  s.EvolutionTasks,
+		RetrievalScopes: s.RetrievalScopes,
+		DocumentChats:   s.DocumentChats,
+		WorkspaceQuota:  s.WorkspaceQuota,
+	}
+	deps.GeekRunner, deps.EvolutionRunner = aichat.NewModeRunners(s.Store, s.RunnerBackends, s.ActionAudit.Handler())
+
+	// Configure chat router for auto-routing.
+	// routerSvc provides two-layer routing (cache → rule).
+	// Orchestrator handles LLM-based task decomposition when needed.
+	// Metadata-based sticky routing bases decisions on persisted state
+	// (AIBlock.Metadata), not just in-memory session state.
+	deps.MetadataManager = ctxpkg.NewMetadataManager(s.Store, 5*time.Minute) // 5 min cache TTL
+	deps.ChatRouter = agentpkg.NewChatRouterWithMetadata(aichat.NewChatRouter(s.getRouterService()), deps.MetadataManager)
+
+	// Orchestrator handles: (1) needs_orchestration=true requests, (2) expert handoff when report_inability is called
+	if factory.GetParrotFactory() != nil {
+		// CapabilityMap knows all experts' capabilities, used to find alternative experts
+		if expertConfigs := factory.GetSelfCognitionConfigs(); len(expertConfigs) > 0 {
+			cm := orchestrator.NewCapabilityMap()
+			cm.BuildFromConfigs(expertConfigs)
+			cm.BuildKeywordIndex(expertConfigs)
+			deps.CapabilityMap = cm
+		}
+
+		// Note: userID is set per-request in ExecuteExpert, so we use 0 here as placeholder
+		expertRegistry := orchestrator.NewParrotExpertRegistry(factory.GetParrotFactory(), 0)
+		orchestratorLLM := s.LLMService
+		if s.LLMRegistry != nil {
+			orchestratorLLM = s.LLMRegistry.LLM(llmregistry.AgentOrchestrator)
+		}
+		deps.Orchestrator = orchestrator.NewOrchestrator(
+			orchestratorLLM,
+			expertRegistry,
+			orchestrator.WithHandoff(true),
+			orchestrator.WithAggregation(true),
+			orchestrator.WithValidators(orchestrator.MemoCitationValidator{Exists: s.memoExists}),
+		)
+	}
+
+	parrotHandler, err := aichat.BuildParrotHandler(deps)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Chat handler wired",
+		"orchestrator", deps.Orchestrator != nil,
+		"capability_map", deps.CapabilityMap != nil,
+		"geek_mode", deps.GeekRunner != nil,
+		"evolution_mode", deps.EvolutionRunner != nil,
+	)
+	return aichat.NewRoutingHandler(parrotHandler), nil
+}
+
+// newContextBuilder creates the context builder of chat handlers.
+func (s *AIService) newContextBuilder() *ctxpkg.Service {
+	// The ContextBuilder fetches history from AIBlockStore instead of trusting req.History.
+	storeAdapter := ctxpkg.NewStoreAdapter(s.Store)
+	msgProvider := ctxpkg.NewBlockStoreMessageProvider(storeAdapter, 0) // userID not used in GetRecentMessages
+	contextBuilder := ctxpkg.NewService(ctxpkg.DefaultConfig()).WithMessageProvider(msgProvider)
+
+	// Phase 3: Inject EpisodicProvider for long-term memory retrieval
+	// This enables semantic search over past conversation episodes.
+	if s.EmbeddingService != nil {
+		vectorSearchAdapter := ctxpkg.NewVectorSearchStoreAdapter(s.Store)
+		episodicProvider := ctxpkg.NewEpisodicProvider(
+			vectorSearchAdapter,
+			s.EmbeddingService, // EmbeddingService implements ctxpkg.EmbeddingService
+			ctxpkg.DefaultEpisodicConfig(),
+			"", // agentType is set per-request
+		)
+		contextBuilder = contextBuilder.WithEpisodicProvider(episodicProvider)
+		slog.Info("Episodic memory provider enabled for context building")
+	}
+
+	return contextBuilder
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/hrygo/divinesense/plugin/filter"
 	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
 	storepb "github.com/hrygo/divinesense/proto/gen/store"
+	ocrrunner "github.com/hrygo/divinesense/server/runner/ocr"
 	"github.com/hrygo/divinesense/store"
 )
 
@@ -35,6 +36,8 @@ type AttachmentService struct {
 	Store              *store.Store
 	Profile            *profile.Profile
 	thumbnailSemaphore *semaphore.Weighted
+	// ocrRunner extracts the text of new attachments; nil when disabled.
+	ocrRunner *ocrrunner.Runner
 }
 
 const (
@@ -144,6 +147,9 @@ func (s *AttachmentService) CreateAttachment(ctx context.Context, request *v1pb.
 		slog.Error("failed to create attachment", "error", err)
 		return nil, status.Errorf(codes.Internal, "failed to create attachment")
 	}
+	if s.ocrRunner != nil {
+		s.ocrRunner.ProcessAttachmentAsync(ctx, attachment.ID)
+	}
 
 	return convertAttachmentFromStore(attachment), nil
 }
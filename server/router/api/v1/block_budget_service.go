@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/blockbudget"
+)
+
+// Request headers of a chat request tightening the block budget of the user
+// for that request.
+const (
+	blockMaxTokensHeader  = "X-Block-Max-Tokens"
+	blockMaxCostUSDHeader = "X-Block-Max-Cost-Usd"
+)
+
+// requestBlockBudget reads the block budget of a chat request from its headers.
+func requestBlockBudget(header http.Header) (*blockbudget.Budget, error) {
+	return blockbudget.Parse(header.Get(blockMaxTokensHeader), header.Get(blockMaxCostUSDHeader))
+}
+
+// blockBudget returns the budget of a block of a user: their default budget,
+// tightened by the budget of the chat request of ctx.
+func (s *AIService) blockBudget(ctx context.Context, userID int32) *blockbudget.Budget {
+	request := blockbudget.RequestBudget(ctx)
+	budget, err := s.BlockBudgets.ForRequest(ctx, userID, request)
+	if err != nil {
+		slog.Warn("failed to get block budget", "user_id", userID, "error", err)
+		return request
+	}
+	return budget
+}
+
+// registerBlockBudgetRoutes registers the block budget endpoints.
+func (s *APIV1Service) registerBlockBudgetRoutes(group *echo.Group) {
+	if s.BlockBudgets == nil {
+		return
+	}
+	group.GET("/block-budget", s.GetBlockBudget)
+	group.PUT("/block-budget", s.UpdateBlockBudget)
+}
+
+// GET /api/v1/system/block-budget.
+func (s *APIV1Service) GetBlockBudget(c echo.Context) error {
+	budget, err := s.BlockBudgets.GetBudget(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		slog.Error("failed to get block budget", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get block budget")
+	}
+	return c.JSON(http.StatusOK, budget)
+}
+
+// PUT /api/v1/system/block-budget {"max_tokens": 20000, "max_cost_usd": 0.05}.
+// Sets the default budget of the user's chat blocks; 0 is unlimited. Fields
+// missing from the body keep their current value.
+func (s *APIV1Service) UpdateBlockBudget(c echo.Context) error {
+	ctx := c.Request().Context()
+	user := restCurrentUser(c)
+	budget, err := s.BlockBudgets.GetBudget(ctx, user.ID)
+	if err != nil {
+		slog.Error("failed to get block budget", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get block budget")
+	}
+	if err := c.Bind(budget); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := budget.Validate(); err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	saved, err := s.BlockBudgets.SaveBudget(ctx, user.ID, budget)
+	if err != nil {
+		slog.Error("failed to save block budget", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to save block budget")
+	}
+	return c.JSON(http.StatusOK, saved)
+}
@@ -0,0 +1,152 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/calsync"
+	"github.com/hrygo/divinesense/store"
+)
+
+// registerCalendarRoutes registers the iCalendar feeds, authenticated by
+// their secret token, and the API of the current user's feed and CalDAV
+// calendar.
+func (s *APIV1Service) registerCalendarRoutes(echoServer *echo.Echo, group *echo.Group) {
+	if s.Calendars == nil {
+		return
+	}
+	echoServer.GET(calsync.FeedPath+":file", s.GetCalendarFeedFile)
+	feed := group.Group("/calendar/feed")
+	feed.GET("", s.GetCalendarFeed)
+	feed.POST("", s.RotateCalendarFeed)
+	feed.DELETE("", s.DeleteCalendarFeed)
+	account := group.Group("/calendar/account")
+	account.GET("", s.GetCalendarAccount)
+	account.PUT("", s.SaveCalendarAccount)
+	account.DELETE("", s.DeleteCalendarAccount)
+	account.POST("/sync", s.SyncCalendarAccount)
+}
+
+// GET /api/v1/calendar/<token>.ics.
+// Returns the schedules of the user owning the token as an iCalendar
+// document, for calendar apps to subscribe to.
+func (s *APIV1Service) GetCalendarFeedFile(c echo.Context) error {
+	ctx := c.Request().Context()
+	feed, err := s.Calendars.ResolveFeed(ctx, strings.TrimSuffix(c.Param("file"), ".ics"))
+	if err != nil {
+		return calendarError(c, err, "failed to resolve calendar feed")
+	}
+	user, err := s.Store.GetUser(ctx, &store.FindUser{ID: &feed.UserID})
+	if err != nil {
+		slog.Error("failed to get calendar feed user", "user_id", feed.UserID, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get user")
+	}
+	if user == nil || user.RowStatus != store.Normal {
+		return restError(c, http.StatusNotFound, calsync.ErrNotFound.Error())
+	}
+	data, err := s.Calendars.Calendar(ctx, feed.UserID)
+	if err != nil {
+		return calendarError(c, err, "failed to get calendar")
+	}
+	c.Response().Header().Set("Cache-Control", "private, max-age=300")
+	return c.Blob(http.StatusOK, "text/calendar; charset=utf-8", data)
+}
+
+// GET /api/v1/system/calendar/feed returns the feed URL of the current user.
+func (s *APIV1Service) GetCalendarFeed(c echo.Context) error {
+	feed, err := s.Calendars.Feed(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		return calendarError(c, err, "failed to get calendar feed")
+	}
+	return c.JSON(http.StatusOK, feed)
+}
+
+// POST /api/v1/system/calendar/feed creates the feed URL of the current
+// user, or replaces it; calendars subscribed to the previous URL stop
+// updating.
+func (s *APIV1Service) RotateCalendarFeed(c echo.Context) error {
+	feed, err := s.Calendars.RotateFeed(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		return calendarError(c, err, "failed to create calendar feed")
+	}
+	return c.JSON(http.StatusOK, feed)
+}
+
+// DELETE /api/v1/system/calendar/feed deletes the feed URL of the current
+// user.
+func (s *APIV1Service) DeleteCalendarFeed(c echo.Context) error {
+	if err := s.Calendars.DeleteFeed(c.Request().Context(), restCurrentUser(c).ID); err != nil {
+		return calendarError(c, err, "failed to delete calendar feed")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GET /api/v1/system/calendar/account returns the CalDAV calendar of the
+// current user, without its password, with the outcome of the last sync.
+func (s *APIV1Service) GetCalendarAccount(c echo.Context) error {
+	account, err := s.Calendars.Account(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		return calendarError(c, err, "failed to get calendar account")
+	}
+	return c.JSON(http.StatusOK, account)
+}
+
+// PUT /api/v1/system/calendar/account {"url": "https://...", "username":
+// "me", "password": "app-password", "timezone": "Asia/Shanghai"} connects the
+// CalDAV calendar of the current user once it answers; an empty password
+// keeps the saved one. Only hosts and admins may connect servers on private
+// addresses.
+func (s *APIV1Service) SaveCalendarAccount(c echo.Context) error {
+	account := &calsync.Account{}
+	if err := c.Bind(account); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	user := restCurrentUser(c)
+	allowPrivate := user.Role == store.RoleHost || user.Role == store.RoleAdmin
+	saved, err := s.Calendars.SaveAccount(c.Request().Context(), user.ID, account, allowPrivate)
+	if err != nil {
+		return calendarError(c, err, "failed to save calendar account")
+	}
+	return c.JSON(http.StatusOK, saved)
+}
+
+// DELETE /api/v1/system/calendar/account disconnects the CalDAV calendar of
+// the current user; their schedules and its events are kept.
+func (s *APIV1Service) DeleteCalendarAccount(c echo.Context) error {
+	if err := s.Calendars.DeleteAccount(c.Request().Context(), restCurrentUser(c).ID); err != nil {
+		return calendarError(c, err, "failed to delete calendar account")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// POST /api/v1/system/calendar/account/sync syncs the CalDAV calendar of the
+// current user now and returns the changes.
+func (s *APIV1Service) SyncCalendarAccount(c echo.Context) error {
+	result, err := s.Calendars.Sync(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		if errors.Is(err, calsync.ErrNotFound) || errors.Is(err, calsync.ErrDisabled) {
+			return calendarError(c, err, "failed to sync calendar")
+		}
+		// the error is kept on the account too
+		return restError(c, http.StatusBadGateway, err.Error())
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// calendarError maps the errors of the calendars to responses.
+func calendarError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, calsync.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, calsync.ErrNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, calsync.ErrDisabled):
+		return restError(c, http.StatusNotImplemented, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/canary"
+	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
+)
+
+// canaryMetricsWindow is the period of the metrics listed with the canaries.
+const canaryMetricsWindow = 7 * 24 * time.Hour
+
+// registerCanaries registers the canary parrots of an initialized factory.
+func (s *AIService) registerCanaries(factory *aichat.AgentFactory) {
+	if s.Canaries == nil {
+		return
+	}
+	for _, config := range factory.CanaryConfigs() {
+		s.Canaries.Register(context.Background(), config.Name, config.Canary.Incumbent, config.Canary.Percent)
+		slog.Info("canary registered", "canary", config.Name, "incumbent", config.Canary.Incumbent)
+	}
+}
+
+// registerCanaryRoutes registers the admin API of the canary rollouts.
+func (s *APIV1Service) registerCanaryRoutes(group *echo.Group) {
+	if s.Canaries == nil {
+		return
+	}
+	canaries := group.Group("/canaries", restAdminMiddleware)
+	canaries.GET("", s.ListCanaries)
+	canaries.PUT("/:name", s.UpdateCanary)
+	canaries.GET("/:name/comparisons", s.ListCanaryComparisons)
+}
+
+// canaryView is a rollout with its comparison metrics.
+type canaryView struct {
+	*canary.Rollout
+	Metrics *canary.Metrics `json:"metrics"`
+}
+
+// GET /api/v1/system/canaries lists the rollouts with the metrics of the last 7 days.
+func (s *APIV1Service) ListCanaries(c echo.Context) error {
+	ctx := c.Request().Context()
+	since := time.Now().Add(-canaryMetricsWindow).Unix()
+	views := []canaryView{}
+	for _, rollout := range s.Canaries.List() {
+		metrics, err := s.Canaries.Metrics(ctx, rollout.Name, since)
+		if err != nil {
+			slog.Error("failed to get canary metrics", "canary", rollout.Name, "error", err)
+			return restError(c, http.StatusInternalServerError, "failed to list canaries")
+		}
+		views = append(views, canaryView{Rollout: rollout, Metrics: metrics})
+	}
+	return c.JSON(http.StatusOK, map[string]any{"canaries": views})
+}
+
+// PUT /api/v1/system/canaries/:name {"percent": 25}; 100 is a full rollout,
+// 0 stops the canary.
+func (s *APIV1Service) UpdateCanary(c echo.Context) error {
+	var body struct {
+		Percent *int `json:"percent"`
+	}
+	if err := c.Bind(&body); err != nil || body.Percent == nil {
+		return restError(c, http.StatusBadRequest, "percent is required")
+	}
+	rollout, err := s.Canaries.SetPercent(c.Request().Context(), c.Param("name"), *body.Percent)
+	if err != nil {
+		return canaryError(c, err, "failed to update canary")
+	}
+	return c.JSON(http.StatusOK, rollout)
+}
+
+// GET /api/v1/system/canaries/:name/comparisons?limit=50.
+func (s *APIV1Service) ListCanaryComparisons(c echo.Context) error {
+	limit := 50
+	if raw := c.QueryParam("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > 500 {
+			return restError(c, http.StatusBadRequest, "limit must be between 1 and 500")
+		}
+		limit = parsed
+	}
+	comparisons, err := s.Canaries.Comparisons(c.Request().Context(), c.Param("name"), limit)
+	if err != nil {
+		return canaryError(c, err, "failed to list comparisons")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"comparisons": comparisons})
+}
+
+// canaryError maps the errors of the canaries to responses.
+func canaryError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, canary.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, canary.ErrNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
@@ -0,0 +1,145 @@
+package v1
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+
+	"github.com/hrygo/divinesense/ai/summary"
+	"github.com/hrygo/divinesense/plugin/capture"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+)
+
+const (
+	// captureBodyLimit bounds capture requests: a base64 screenshot up to the
+	// default upload limit, and the text.
+	captureBodyLimit = "48M"
+	// captureSummaryRunes bounds the AI summary of a selection.
+	captureSummaryRunes = 200
+)
+
+// registerCaptureRoutes registers the capture API of the browser extension.
+// It has its own CORS policy, open to extension origins, and only accepts
+// personal access tokens, which extensions store in place of a session.
+func (s *APIV1Service) registerCaptureRoutes(echoServer *echo.Echo, group *echo.Group) {
+	allowed := capture.ParseOrigins(s.Profile.CaptureAllowedOrigins)
+	cors := middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOriginFunc: func(origin string) (bool, error) {
+			return capture.AllowOrigin(origin, allowed), nil
+		},
+		AllowMethods: []string{http.MethodPost, http.MethodOptions},
+		AllowHeaders: []string{echo.HeaderAuthorization, echo.HeaderContentType},
+		MaxAge:       86400,
+	})
+	captureGroup := echoServer.Group("/api/v1/capture", cors, middleware.BodyLimit(captureBodyLimit), restPATMiddleware, s.restAuthMiddleware())
+	captureGroup.POST("", s.CaptureClip)
+
+	if s.captureStore != nil {
+		group.GET("/memos/:uid/source", s.GetMemoSource)
+	}
+}
+
+// POST /api/v1/capture.
+// Creates a memo from a web page captured by the browser extension: the
+// selection is quoted, the screenshot attached and the page linked. With
+// summarize, an AI summary of the selection is added when the LLM is available;
+// with readLater, the memo is queued for reading later.
+func (s *APIV1Service) CaptureClip(c echo.Context) error {
+	clip := &capture.Clip{}
+	if err := c.Bind(clip); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := clip.Normalize(); err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	screenshot, err := clip.DecodeScreenshot()
+	if err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	ctx := c.Request().Context()
+
+	clipSummary := ""
+	if clip.Summarize && clip.Selection != "" {
+		clipSummary = s.summarizeClip(ctx, clip)
+	}
+	memo := &v1pb.Memo{
+		Content:    appendMemoTags(clip.Content(clipSummary, userLocale(ctx, s.Store, restCurrentUser(c).ID)), clip.Tags),
+		Visibility: v1pb.Visibility(v1pb.Visibility_value[clip.Visibility]),
+	}
+	attachmentName := ""
+	if screenshot != nil {
+		attachment, err := s.AttachmentService.CreateAttachment(ctx, &v1pb.CreateAttachmentRequest{
+			Attachment: &v1pb.Attachment{Filename: screenshot.Filename, Type: screenshot.MimeType, Content: screenshot.Data},
+		})
+		if err != nil {
+			return restStatusError(c, err)
+		}
+		attachmentName = attachment.Name
+		memo.Attachments = []*v1pb.Attachment{{Name: attachment.Name}}
+	}
+
+	created, err := s.MemoService.CreateMemo(ctx, &v1pb.CreateMemoRequest{Memo: memo})
+	if err != nil {
+		return restStatusError(c, err)
+	}
+	memoUID := strings.TrimPrefix(created.Name, MemoNamePrefix)
+	if s.captureStore != nil {
+		if err := s.captureStore.SaveSource(ctx, &capture.Source{
+			MemoUID: memoUID,
+			URL:     clip.URL,
+			Title:   clip.Title,
+			Site:    clip.Site(),
+			Client:  clip.Client,
+		}); err != nil {
+			slog.Warn("failed to save memo source", "memo", created.Name, "error", err)
+		}
+	}
+	if clip.ReadLater && s.readLater != nil {
+		if _, err := s.readLater.Enqueue(ctx, restCurrentUser(c).ID, memoUID); err != nil {
+			slog.Warn("failed to queue captured memo", "memo", created.Name, "error", err)
+		}
+	}
+
+	return c.JSON(http.StatusCreated, map[string]any{
+		"name":       created.Name,
+		"uid":        memoUID,
+		"attachment": attachmentName,
+		"summary":    clipSummary,
+	})
+}
+
+// summarizeClip returns an AI summary of the selection of a clip, or "" when
+// the LLM is unavailable or the selection is too short to need one.
+func (s *APIV1Service) summarizeClip(ctx context.Context, clip *capture.Clip) string {
+	if s.AIService == nil || !s.AIService.IsLLMEnabled() {
+		return ""
+	}
+	resp, err := summary.NewSummarizer(s.AIService.LLMService).Summarize(ctx, &summary.SummarizeRequest{
+		Content: clip.Selection,
+		Title:   clip.Title,
+		MaxLen:  captureSummaryRunes,
+	})
+	// Fallback summaries are excerpts of the selection, not worth a block.
+	if err != nil || resp.Source != "llm" {
+		return ""
+	}
+	return resp.Summary
+}
+
+// GET /api/v1/system/memos/:uid/source.
+// Returns the web page a memo of the current user was captured from.
+func (s *APIV1Service) GetMemoSource(c echo.Context) error {
+	source, err := s.captureStore.GetSource(c.Request().Context(), restCurrentUser(c).ID, c.Param("uid"))
+	if err != nil {
+		slog.Error("failed to get memo source", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get memo source")
+	}
+	if source == nil {
+		return restError(c, http.StatusNotFound, "memo has no source")
+	}
+	return c.JSON(http.StatusOK, source)
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/internal/profile"
+)
+
+func TestCaptureRoutesCORSAndAuth(t *testing.T) {
+	e := echo.New()
+	s := &APIV1Service{Profile: &profile.Profile{}}
+	s.registerCaptureRoutes(e, e.Group("/api/v1/system"))
+
+	serve := func(method, origin, authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/capture", nil)
+		req.Header.Set(echo.HeaderOrigin, origin)
+		req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPost)
+		if authorization != "" {
+			req.Header.Set(echo.HeaderAuthorization, authorization)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Preflight requests of extensions are answered before authentication.
+	rec := serve(http.MethodOptions, "chrome-extension://abcdefghijklmnop", "")
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Equal(t, "chrome-extension://abcdefghijklmnop", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	require.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowCredentials))
+
+	rec = serve(http.MethodOptions, "https://evil.example", "")
+	require.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+
+	// Session tokens are rejected: extensions authenticate with a personal access token.
+	rec = serve(http.MethodPost, "chrome-extension://abcdefghijklmnop", "Bearer eyJhbGciOiJIUzI1NiJ9.e30.sig")
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Contains(t, rec.Body.String(), "personal access token")
+}
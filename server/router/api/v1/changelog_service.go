@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/changelog"
+)
+
+// registerChangelogRoutes registers the release notes and the "what's new"
+// of the current user.
+func (s *APIV1Service) registerChangelogRoutes(group *echo.Group) {
+	if s.Changelog == nil {
+		return
+	}
+	group.GET("/changelog", s.ListReleases)
+	group.GET("/changelog/whats-new", s.GetWhatsNew)
+	group.POST("/changelog/seen", s.MarkChangelogSeen)
+	group.GET("/changelog/:version", s.GetRelease)
+}
+
+// GET /api/v1/system/changelog[?limit=20] returns the running version and
+// the notes of the latest releases up to it.
+func (s *APIV1Service) ListReleases(c echo.Context) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"current_version": s.Changelog.Version(),
+		"releases":        s.Changelog.Releases(limit),
+	})
+}
+
+// GET /api/v1/system/changelog/:version returns the notes of a release.
+func (s *APIV1Service) GetRelease(c echo.Context) error {
+	release, err := s.Changelog.Release(c.Param("version"))
+	if err != nil {
+		return changelogError(c, err, "failed to get release")
+	}
+	return c.JSON(http.StatusOK, release)
+}
+
+// GET /api/v1/system/changelog/whats-new returns the releases the current
+// user has not seen, for clients to show them after an upgrade.
+func (s *APIV1Service) GetWhatsNew(c echo.Context) error {
+	news, err := s.Changelog.WhatsNew(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		return changelogError(c, err, "failed to get what's new")
+	}
+	return c.JSON(http.StatusOK, news)
+}
+
+// POST /api/v1/system/changelog/seen {"version": "0.101.0"} records that the
+// current user has seen the notes up to a version, the latest if omitted.
+func (s *APIV1Service) MarkChangelogSeen(c echo.Context) error {
+	var req struct {
+		Version string `json:"version"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	news, err := s.Changelog.MarkSeen(c.Request().Context(), restCurrentUser(c).ID, req.Version)
+	if err != nil {
+		return changelogError(c, err, "failed to mark release seen")
+	}
+	return c.JSON(http.StatusOK, news)
+}
+
+func changelogError(c echo.Context, err error, msg string) error {
+	if errors.Is(err, changelog.ErrNotFound) {
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/hrygo/divinesense/plugin/chat_apps/channels"
 	"github.com/hrygo/divinesense/plugin/chat_apps/metrics"
 	chatstore "github.com/hrygo/divinesense/plugin/chat_apps/store"
+	"github.com/hrygo/divinesense/plugin/i18n"
 	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
 	"github.com/hrygo/divinesense/server/auth"
 	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
@@ -406,7 +407,7 @@ func (s *ChatAppService) routeAndSendAIResponse(
 	registry *metrics.Registry,
 ) {
 	// Build prompt for AI
-	prompt := s.buildAIPrompt(msg)
+	prompt := s.buildAIPrompt(msg, userLocale(ctx, s.Store, cred.UserID))
 
 	// Use streaming response for better UX
 	s.sendStreamingResponse(ctx, cred, msg, platform, channel, prompt)
@@ -417,12 +418,13 @@ func (s *ChatAppService) routeAndSendAIResponse(
 	}
 }
 
-// buildAIPrompt builds a prompt for the AI based on the incoming message.
-func (s *ChatAppService) buildAIPrompt(msg *chat_apps.IncomingMessage) string {
+// buildAIPrompt builds a prompt for the AI based on the incoming message, in
+// the locale of the user.
+func (s *ChatAppService) buildAIPrompt(msg *chat_apps.IncomingMessage, locale i18n.Locale) string {
 	// Build a simple prompt with context
 	prompt := msg.Content
 	if msg.Type == chat_apps.MessageTypePhoto || msg.Type == chat_apps.MessageTypeVideo {
-		prompt = "[用户发送了一张图片/视频，请询问用户希望我如何处理]"
+		prompt = i18n.T(locale, "chatapp.media_prompt")
 	}
 	return prompt
 }
@@ -468,10 +470,13 @@ func (s *ChatAppService) sendStreamingResponse(
 	channel channels.ChatChannel,
 	prompt string,
 ) {
+	// System messages are in the locale of the user
+	locale := userLocale(ctx, s.Store, cred.UserID)
+
 	// Check if AI is enabled
 	if s.AIService == nil || !s.AIService.IsLLMEnabled() {
 		s.sendSimpleResponse(ctx, cred, msg, platform, channel,
-			"AI 功能未启用。请在服务器配置中启用 AI 服务。")
+			i18n.T(locale, "chatapp.ai_disabled"))
 		return
 	}
 
@@ -492,6 +497,23 @@ func (s *ChatAppService) sendStreamingResponse(
 			s.AIService.AdaptiveRetriever,
 			s.Store,
 		)
+		factory.SetHTTPActions(s.AIService.HTTPActions)
+		factory.SetWebFetch(s.AIService.WebFetch)
+		factory.SetEntityGraph(s.AIService.EntityGraph)
+		factory.SetContacts(s.AIService.Contacts)
+		factory.SetFlashcards(s.AIService.Flashcards)
+		factory.SetDocuments(s.AIService.Documents)
+		factory.SetSnippets(s.AIService.Snippets)
+		factory.SetTimeline(s.AIService.Timeline)
+		factory.SetUsageStats(s.AIService.UsageStats)
+		factory.SetMeetingNotes(s.AIService.MeetingNotes)
+		factory.SetChecklists(s.AIService.Checklists)
+		factory.SetHabits(s.AIService.Habits)
+		factory.SetToolpack(s.AIService.Toolpack)
+		factory.SetExpenses(s.AIService.Expenses)
+		factory.SetHomeAssistant(s.AIService.HomeAssistant)
+		factory.SetMailbox(s.AIService.Mailbox)
+		factory.SetReminders(s.AIService.Reminders)
 
 		// Initialize UniversalParrot if configured
 		if s.AIService.UniversalParrotConfig != nil {
@@ -501,7 +523,7 @@ func (s *ChatAppService) sendStreamingResponse(
 					"user_id", cred.UserID,
 					"platform", platform,
 				)
-				chunks <- "抱歉，AI 服务配置错误。请联系管理员。"
+				chunks <- i18n.T(locale, "chatapp.ai_misconfigured")
 				return
 			}
 		}
@@ -518,7 +540,7 @@ func (s *ChatAppService) sendStreamingResponse(
 				"platform", platform,
 				"error", err,
 			)
-			chunks <- "抱歉，AI 服务暂时不可用。请稍后再试。"
+			chunks <- i18n.T(locale, "chatapp.ai_unavailable")
 			return
 		}
 
@@ -547,7 +569,7 @@ func (s *ChatAppService) sendStreamingResponse(
 				"error", err,
 			)
 			select {
-			case chunks <- "抱歉，AI 服务出现错误。请稍后再试。":
+			case chunks <- i18n.T(locale, "chatapp.ai_failed"):
 			case <-agentCtx.Done():
 			}
 		}
@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/checklist"
+)
+
+// CreateChecklistRequest is the body for creating a checklist.
+type CreateChecklistRequest struct {
+	Title string           `json:"title"`
+	Items []checklist.Item `json:"items"`
+}
+
+// registerChecklistRoutes registers the API of the checklists generated by
+// the checklist parrot.
+func (s *APIV1Service) registerChecklistRoutes(group *echo.Group) {
+	if s.Checklists == nil {
+		return
+	}
+	checklists := group.Group("/checklists")
+	checklists.GET("", s.ListChecklists)
+	checklists.POST("", s.CreateChecklist)
+	checklists.GET("/:id", s.GetChecklist)
+	checklists.PATCH("/:id", s.EditChecklist)
+	checklists.DELETE("/:id", s.DeleteChecklist)
+}
+
+// GET /api/v1/system/checklists lists the current user's checklists, most
+// recently updated first.
+func (s *APIV1Service) ListChecklists(c echo.Context) error {
+	checklists, err := s.Checklists.List(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		return checklistError(c, err, "failed to list checklists")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"checklists": checklists})
+}
+
+// POST /api/v1/system/checklists {"title": "...", "items": [{"text": "护照",
+// "due": "2026-11-01"}]}.
+func (s *APIV1Service) CreateChecklist(c echo.Context) error {
+	req := &CreateChecklistRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	created, err := s.Checklists.Create(c.Request().Context(), restCurrentUser(c).ID, req.Title, req.Items)
+	if err != nil {
+		return checklistError(c, err, "failed to create checklist")
+	}
+	return c.JSON(http.StatusOK, created)
+}
+
+// GET /api/v1/system/checklists/:id.
+func (s *APIV1Service) GetChecklist(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid checklist id")
+	}
+	found, err := s.Checklists.Get(c.Request().Context(), restCurrentUser(c).ID, int32(id))
+	if err != nil {
+		return checklistError(c, err, "failed to get checklist")
+	}
+	return c.JSON(http.StatusOK, found)
+}
+
+// PATCH /api/v1/system/checklists/:id {"title": "...", "update": [{"id": 2,
+// "checked": true}], "remove": [5], "add": [{"text": "充电器"}]}, the same
+// edit the checklist_update tool makes.
+func (s *APIV1Service) EditChecklist(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid checklist id")
+	}
+	edit := &checklist.Edit{}
+	if err := c.Bind(edit); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	updated, err := s.Checklists.Edit(c.Request().Context(), restCurrentUser(c).ID, int32(id), edit)
+	if err != nil {
+		return checklistError(c, err, "failed to update checklist")
+	}
+	return c.JSON(http.StatusOK, updated)
+}
+
+// DELETE /api/v1/system/checklists/:id.
+func (s *APIV1Service) DeleteChecklist(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid checklist id")
+	}
+	if err := s.Checklists.Delete(c.Request().Context(), restCurrentUser(c).ID, int32(id)); err != nil {
+		return checklistError(c, err, "failed to delete checklist")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// checklistError maps the errors of the checklists to responses.
+func checklistError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, checklist.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, checklist.ErrNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
@@ -52,6 +52,8 @@ func (s *ConnectServiceHandler) RegisterConnectHandlers(mux *http.ServeMux, opts
 
 	if s.AIService != nil {
 		handlers = append(handlers, wrap(apiv1connect.NewAIServiceHandler(s, opts...)))
+	} else if s.Profile.SafeMode {
+		handlers = append(handlers, wrap("/"+apiv1connect.AIServiceName+"/", connectSafeModeHandler(opts...)))
 	}
 
 	// Register Schedule service handlers
@@ -171,47 +173,81 @@ func (s *ConnectServiceHandler) Chat(ctx context.Context, req *connect.Request[v
 		"is_default", req.Msg.AgentType == v1pb.AgentType_AGENT_TYPE_DEFAULT,
 	)
 
+	ctx, peer, err := s.routeChat(ctx, req.Header(), stream.ResponseHeader(), req.Msg)
+	if err != nil {
+		return convertGRPCError(err)
+	}
+	if peer != nil {
+		if handled, err := s.forwardChat(ctx, peer, req.Header(), req.Msg, stream); handled {
+			return err
+		}
+	}
+
 	// Delegate to AIService.Chat which has the full agent routing logic
-	return s.AIService.Chat(req.Msg, &connectStreamAdapter{
+	return s.AIService.Chat(req.Msg, &connectStreamAdapter[v1pb.ChatResponse]{
+		stream: stream,
+		ctx:    ctx,
+	})
+}
+
+// SubmitForm answers the form pending on a block and streams the follow-up of the agent.
+func (s *ConnectServiceHandler) SubmitForm(ctx context.Context, req *connect.Request[v1pb.SubmitFormRequest], stream *connect.ServerStream[v1pb.ChatResponse]) error {
+	if err := s.requireAI(); err != nil {
+		return err
+	}
+	return s.AIService.SubmitForm(req.Msg, &connectStreamAdapter[v1pb.ChatResponse]{
+		stream: stream,
+		ctx:    ctx,
+	})
+}
+
+// ImportMemos imports a zipped folder of notes as memos, streaming the progress.
+func (s *ConnectServiceHandler) ImportMemos(ctx context.Context, req *connect.Request[v1pb.ImportMemosRequest], stream *connect.ServerStream[v1pb.ImportMemosResponse]) error {
+	err := s.MemoService.ImportMemos(req.Msg, &connectStreamAdapter[v1pb.ImportMemosResponse]{
 		stream: stream,
 		ctx:    ctx,
 	})
+	if err != nil {
+		return convertGRPCError(err)
+	}
+	return nil
 }
 
-// connectStreamAdapter wraps Connect ServerStream to implement AIService_ChatServer.
-type connectStreamAdapter struct {
-	stream *connect.ServerStream[v1pb.ChatResponse]
+// connectStreamAdapter wraps Connect ServerStream to implement the gRPC
+// server stream of a service, e.g. AIService_ChatServer.
+type connectStreamAdapter[Res any] struct {
+	stream *connect.ServerStream[Res]
 	ctx    context.Context
 }
 
-func (a *connectStreamAdapter) Send(resp *v1pb.ChatResponse) error {
+func (a *connectStreamAdapter[Res]) Send(resp *Res) error {
 	return a.stream.Send(resp)
 }
 
-func (a *connectStreamAdapter) Context() context.Context {
+func (a *connectStreamAdapter[Res]) Context() context.Context {
 	return a.ctx
 }
 
-func (a *connectStreamAdapter) SendMsg(m any) error {
-	if resp, ok := m.(*v1pb.ChatResponse); ok {
+func (a *connectStreamAdapter[Res]) SendMsg(m any) error {
+	if resp, ok := m.(*Res); ok {
 		return a.Send(resp)
 	}
 	return fmt.Errorf("invalid message type: %T", m)
 }
 
-func (a *connectStreamAdapter) RecvMsg(m any) error {
+func (a *connectStreamAdapter[Res]) RecvMsg(m any) error {
 	return fmt.Errorf("RecvMsg not supported for server streaming")
 }
 
-func (a *connectStreamAdapter) SetHeader(md metadata.MD) error {
+func (a *connectStreamAdapter[Res]) SetHeader(md metadata.MD) error {
 	return nil
 }
 
-func (a *connectStreamAdapter) SendHeader(md metadata.MD) error {
+func (a *connectStreamAdapter[Res]) SendHeader(md metadata.MD) error {
 	return nil
 }
 
-func (a *connectStreamAdapter) SetTrailer(md metadata.MD) {
+func (a *connectStreamAdapter[Res]) SetTrailer(md metadata.MD) {
 }
 
 // truncateStringForLog truncates a string for logging.
@@ -517,12 +553,29 @@ func (s *ConnectServiceHandler) ClearConversationMessages(ctx context.Context, r
 	return connect.NewResponse(resp), nil
 }
 
+// CreateChatStreamTicket issues a single-use ticket for the SSE chat stream.
+func (s *ConnectServiceHandler) CreateChatStreamTicket(ctx context.Context, req *connect.Request[v1pb.CreateChatStreamTicketRequest]) (*connect.Response[v1pb.ChatStreamTicket], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.CreateChatStreamTicket(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
 // StopChat cancels an ongoing chat stream and terminates the associated session.
 // StopChat 取消正在进行的聊天流并终止相关会话。
 func (s *ConnectServiceHandler) StopChat(ctx context.Context, req *connect.Request[v1pb.StopChatRequest]) (*connect.Response[emptypb.Empty], error) {
 	if err := s.requireAI(); err != nil {
 		return nil, err
 	}
+	if peer := s.sessionPeer(ctx, req.Header(), req.Msg.ConversationId, true); peer != nil {
+		if resp, handled, err := s.forwardStopChat(ctx, peer, req); handled {
+			return resp, err
+		}
+	}
 	resp, err := s.AIService.StopChat(ctx, req.Msg)
 	if err != nil {
 		return nil, convertGRPCError(err)
@@ -628,6 +681,14 @@ func (s *ConnectServiceHandler) SearchWithHighlight(ctx context.Context, req *co
 	return connect.NewResponse(resp), nil
 }
 
+func (s *ConnectServiceHandler) SearchMemos(ctx context.Context, req *connect.Request[v1pb.SearchMemosRequest]) (*connect.Response[v1pb.SearchMemosResponse], error) {
+	resp, err := s.MemoService.SearchMemos(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
 // AIBlock (Unified Block Model) wrappers for Connect
 
 func (s *ConnectServiceHandler) ListBlocks(ctx context.Context, req *connect.Request[v1pb.ListBlocksRequest]) (*connect.Response[v1pb.ListBlocksResponse], error) {
@@ -641,6 +702,28 @@ func (s *ConnectServiceHandler) ListBlocks(ctx context.Context, req *connect.Req
 	return connect.NewResponse(resp), nil
 }
 
+func (s *ConnectServiceHandler) GetBlockChanges(ctx context.Context, req *connect.Request[v1pb.GetBlockChangesRequest]) (*connect.Response[v1pb.GetBlockChangesResponse], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.GetBlockChanges(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) GetBlockTranscript(ctx context.Context, req *connect.Request[v1pb.GetBlockTranscriptRequest]) (*connect.Response[v1pb.BlockTranscript], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.GetBlockTranscript(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
 func (s *ConnectServiceHandler) GetBlock(ctx context.Context, req *connect.Request[v1pb.GetBlockRequest]) (*connect.Response[v1pb.Block], error) {
 	if err := s.requireAI(); err != nil {
 		return nil, err
@@ -39,6 +39,22 @@ func (s *ConnectServiceHandler) UpdateInstanceSetting(ctx context.Context, req *
 	return connect.NewResponse(resp), nil
 }
 
+func (s *ConnectServiceHandler) ListInstanceJobs(ctx context.Context, req *connect.Request[v1pb.ListInstanceJobsRequest]) (*connect.Response[v1pb.ListInstanceJobsResponse], error) {
+	resp, err := s.InstanceService.ListInstanceJobs(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) RunInstanceJob(ctx context.Context, req *connect.Request[v1pb.RunInstanceJobRequest]) (*connect.Response[emptypb.Empty], error) {
+	resp, err := s.InstanceService.RunInstanceJob(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
 // AuthService
 //
 // Auth service methods need special handling for response headers (cookies).
@@ -530,3 +546,220 @@ func (s *ConnectServiceHandler) SetUserCostSettings(ctx context.Context, req *co
 	}
 	return connect.NewResponse(resp), nil
 }
+
+func (s *ConnectServiceHandler) GetUsage(ctx context.Context, req *connect.Request[v1pb.GetUsageRequest]) (*connect.Response[v1pb.Usage], error) {
+	resp, err := s.AIService.GetUsage(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) RunSelfTest(ctx context.Context, req *connect.Request[v1pb.RunSelfTestRequest]) (*connect.Response[v1pb.SelfTestReport], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.RunSelfTest(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) ReindexAll(ctx context.Context, req *connect.Request[v1pb.ReindexAllRequest]) (*connect.Response[v1pb.ReindexProgress], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.ReindexAll(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) ReindexSince(ctx context.Context, req *connect.Request[v1pb.ReindexSinceRequest]) (*connect.Response[v1pb.ReindexProgress], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.ReindexSince(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) GetReindexProgress(ctx context.Context, req *connect.Request[v1pb.GetReindexProgressRequest]) (*connect.Response[v1pb.ReindexProgress], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.GetReindexProgress(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) ListMCPServers(ctx context.Context, req *connect.Request[v1pb.ListMCPServersRequest]) (*connect.Response[v1pb.ListMCPServersResponse], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.ListMCPServers(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) CreateMCPServer(ctx context.Context, req *connect.Request[v1pb.CreateMCPServerRequest]) (*connect.Response[v1pb.MCPServer], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.CreateMCPServer(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) UpdateMCPServer(ctx context.Context, req *connect.Request[v1pb.UpdateMCPServerRequest]) (*connect.Response[v1pb.MCPServer], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.UpdateMCPServer(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) DeleteMCPServer(ctx context.Context, req *connect.Request[v1pb.DeleteMCPServerRequest]) (*connect.Response[emptypb.Empty], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.DeleteMCPServer(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) ImportConversation(ctx context.Context, req *connect.Request[v1pb.ImportConversationRequest]) (*connect.Response[v1pb.ImportConversationResponse], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.ImportConversation(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) ListActionAudit(ctx context.Context, req *connect.Request[v1pb.ListActionAuditRequest]) (*connect.Response[v1pb.ListActionAuditResponse], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.ListActionAudit(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) ExportActionAudit(ctx context.Context, req *connect.Request[v1pb.ExportActionAuditRequest]) (*connect.Response[v1pb.ExportActionAuditResponse], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.ExportActionAudit(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) ListAgentModels(ctx context.Context, req *connect.Request[v1pb.ListAgentModelsRequest]) (*connect.Response[v1pb.ListAgentModelsResponse], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.ListAgentModels(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) UpdateAgentModel(ctx context.Context, req *connect.Request[v1pb.UpdateAgentModelRequest]) (*connect.Response[v1pb.AgentModel], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.UpdateAgentModel(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) DeleteAgentModel(ctx context.Context, req *connect.Request[v1pb.DeleteAgentModelRequest]) (*connect.Response[emptypb.Empty], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.DeleteAgentModel(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) GetGeekWorkspaceUsage(ctx context.Context, req *connect.Request[v1pb.GetGeekWorkspaceUsageRequest]) (*connect.Response[v1pb.GeekWorkspaceUsage], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.GetGeekWorkspaceUsage(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) ListGeekWorkspaceFiles(ctx context.Context, req *connect.Request[v1pb.ListGeekWorkspaceFilesRequest]) (*connect.Response[v1pb.ListGeekWorkspaceFilesResponse], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.ListGeekWorkspaceFiles(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) ReadGeekWorkspaceFile(ctx context.Context, req *connect.Request[v1pb.ReadGeekWorkspaceFileRequest]) (*connect.Response[v1pb.GeekWorkspaceFileContent], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.ReadGeekWorkspaceFile(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) DownloadGeekWorkspaceFile(ctx context.Context, req *connect.Request[v1pb.DownloadGeekWorkspaceFileRequest]) (*connect.Response[v1pb.DownloadGeekWorkspaceFileResponse], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.DownloadGeekWorkspaceFile(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *ConnectServiceHandler) DeleteGeekWorkspaceFile(ctx context.Context, req *connect.Request[v1pb.DeleteGeekWorkspaceFileRequest]) (*connect.Response[emptypb.Empty], error) {
+	if err := s.requireAI(); err != nil {
+		return nil, err
+	}
+	resp, err := s.AIService.DeleteGeekWorkspaceFile(ctx, req.Msg)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	return connect.NewResponse(resp), nil
+}
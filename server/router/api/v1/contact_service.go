@@ -0,0 +1,179 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/contact"
+)
+
+// ContactReminderRequest is the body for setting or replacing a follow-up.
+type ContactReminderRequest struct {
+	Person string `json:"person"`
+	Note   string `json:"note"`
+	// DueDate is the day of the follow-up, YYYY-MM-DD.
+	DueDate string `json:"due_date"`
+	// Done marks the follow-up done; false reopens it.
+	Done bool `json:"done"`
+}
+
+// registerContactRoutes registers the API of the people users know and
+// their follow-ups.
+func (s *APIV1Service) registerContactRoutes(group *echo.Group) {
+	if s.AIService == nil || s.AIService.Contacts == nil {
+		return
+	}
+	contacts := group.Group("/contacts")
+	contacts.GET("", s.ListContacts)
+	contacts.GET("/profile", s.LookupContact)
+	contacts.GET("/reminders", s.ListContactReminders)
+	contacts.POST("/reminders", s.CreateContactReminder)
+	contacts.PUT("/reminders/:id", s.UpdateContactReminder)
+	contacts.DELETE("/reminders/:id", s.DeleteContactReminder)
+	contacts.GET("/:id", s.GetContact)
+}
+
+// GET /api/v1/system/contacts?q=ali&limit=50 lists the people of the current
+// user, the latest interaction first, with their next follow-ups.
+func (s *APIV1Service) ListContacts(c echo.Context) error {
+	limit := 0
+	if raw := c.QueryParam("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return restError(c, http.StatusBadRequest, "invalid limit")
+		}
+		limit = n
+	}
+	contacts, err := s.AIService.Contacts.List(c.Request().Context(), restCurrentUser(c).ID, c.QueryParam("q"), limit)
+	if err != nil {
+		return contactError(c, err, "failed to list contacts")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"contacts": contacts})
+}
+
+// GET /api/v1/system/contacts/profile?name=Alice returns the profile of the
+// person best matching a name.
+func (s *APIV1Service) LookupContact(c echo.Context) error {
+	profile, err := s.AIService.Contacts.Lookup(c.Request().Context(), restCurrentUser(c).ID, c.QueryParam("name"))
+	if err != nil {
+		return contactError(c, err, "failed to look up contact")
+	}
+	return c.JSON(http.StatusOK, profile)
+}
+
+// GET /api/v1/system/contacts/:id returns the profile of a person, by the ID
+// of their entity in the knowledge graph.
+func (s *APIV1Service) GetContact(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid contact id")
+	}
+	profile, err := s.AIService.Contacts.Get(c.Request().Context(), restCurrentUser(c).ID, int32(id))
+	if err != nil {
+		return contactError(c, err, "failed to get contact")
+	}
+	return c.JSON(http.StatusOK, profile)
+}
+
+// GET /api/v1/system/contacts/reminders?person=Alice&due_by=2026-10-31&pending=true
+// lists the current user's follow-ups, pending ones by due date first.
+func (s *APIV1Service) ListContactReminders(c echo.Context) error {
+	find := &contact.FindReminder{
+		UserID: restCurrentUser(c).ID,
+		Person: c.QueryParam("person"),
+		DueBy:  c.QueryParam("due_by"),
+	}
+	if raw := c.QueryParam("pending"); raw != "" {
+		pending, err := strconv.ParseBool(raw)
+		if err != nil {
+			return restError(c, http.StatusBadRequest, "invalid pending")
+		}
+		find.Pending = pending
+	}
+	reminders, err := s.AIService.Contacts.Reminders(c.Request().Context(), find)
+	if err != nil {
+		return contactError(c, err, "failed to list reminders")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"reminders": reminders})
+}
+
+// POST /api/v1/system/contacts/reminders {"person": "Alice",
+// "note": "ask about the offer", "due_date": "2026-10-31"}.
+func (s *APIV1Service) CreateContactReminder(c echo.Context) error {
+	req := &ContactReminderRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	created, err := s.AIService.Contacts.Remind(c.Request().Context(), req.reminder(restCurrentUser(c).ID, 0))
+	if err != nil {
+		return contactError(c, err, "failed to create reminder")
+	}
+	return c.JSON(http.StatusOK, created)
+}
+
+// PUT /api/v1/system/contacts/reminders/:id replaces a follow-up of the
+// current user, e.g. to mark it done.
+func (s *APIV1Service) UpdateContactReminder(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid reminder id")
+	}
+	req := &ContactReminderRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	ctx, userID := c.Request().Context(), restCurrentUser(c).ID
+	existing, err := s.AIService.Contacts.GetReminder(ctx, userID, int32(id))
+	if err != nil {
+		return contactError(c, err, "failed to get reminder")
+	}
+	updated := req.reminder(userID, existing.DoneTs)
+	updated.ID = existing.ID
+	updated, err = s.AIService.Contacts.UpdateReminder(ctx, updated)
+	if err != nil {
+		return contactError(c, err, "failed to update reminder")
+	}
+	return c.JSON(http.StatusOK, updated)
+}
+
+// DELETE /api/v1/system/contacts/reminders/:id.
+func (s *APIV1Service) DeleteContactReminder(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid reminder id")
+	}
+	if err := s.AIService.Contacts.DeleteReminder(c.Request().Context(), restCurrentUser(c).ID, int32(id)); err != nil {
+		return contactError(c, err, "failed to delete reminder")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// reminder returns the reminder of a request; doneTs is kept for follow-ups
+// that stay done.
+func (r *ContactReminderRequest) reminder(userID int32, doneTs int64) *contact.Reminder {
+	reminder := &contact.Reminder{UserID: userID, Person: r.Person, Note: r.Note, DueDate: r.DueDate}
+	switch {
+	case r.Done && doneTs != 0:
+		reminder.DoneTs = doneTs
+	case r.Done:
+		reminder.DoneTs = time.Now().Unix()
+	}
+	return reminder
+}
+
+// contactError maps the errors of the contacts to responses.
+func contactError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, contact.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, contact.ErrNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
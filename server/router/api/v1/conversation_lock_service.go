@@ -0,0 +1,184 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/convlock"
+	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
+	"github.com/hrygo/divinesense/store"
+)
+
+// registerConversationLockRoutes registers the locked conversations API.
+func (s *APIV1Service) registerConversationLockRoutes(group *echo.Group) {
+	if s.ConversationLocks == nil {
+		return
+	}
+	group.GET("/ai/conversations/locks", s.ListConversationLocks)
+	group.POST("/ai/conversations/:id/lock", s.LockConversation)
+	group.POST("/ai/conversations/:id/unlock", s.UnlockConversation)
+	group.POST("/ai/conversations/:id/relock", s.RelockConversation)
+}
+
+// GET /api/v1/system/ai/conversations/locks lists the locked conversations
+// of the current user, and whether they are unlocked.
+func (s *APIV1Service) ListConversationLocks(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]any{"locks": s.ConversationLocks.List(restCurrentUser(c).ID)})
+}
+
+// POST /api/v1/system/ai/conversations/:id/lock {"passphrase": "..."}.
+// Locks a conversation of the current user and seals its history; the
+// conversation is left unlocked. Locking a locked conversation with its
+// passphrase seals again the history left in clear by a failed lock.
+func (s *APIV1Service) LockConversation(c echo.Context) error {
+	conversationID, passphrase, ok := s.conversationLockRequest(c, true)
+	if !ok {
+		return nil
+	}
+	ctx := c.Request().Context()
+	userID := restCurrentUser(c).ID
+	err := s.ConversationLocks.Lock(ctx, userID, conversationID, passphrase)
+	if errors.Is(err, convlock.ErrAlreadyLocked) {
+		_, err = s.ConversationLocks.Unlock(userID, conversationID, passphrase)
+	}
+	if err != nil {
+		return conversationLockError(c, err, "failed to lock conversation")
+	}
+	if err := s.sealConversationHistory(ctx, conversationID); err != nil {
+		slog.Error("failed to seal conversation history", "conversation_id", conversationID, "error", err)
+		return restError(c, http.StatusInternalServerError, "conversation locked, but its history is partly in clear: lock it again")
+	}
+	return c.JSON(http.StatusOK, s.conversationLockStatus(userID, conversationID))
+}
+
+// POST /api/v1/system/ai/conversations/:id/unlock {"passphrase": "..."}.
+// Holds the key of the conversation until it is unused for a while, for its
+// history to be read and for chats to continue.
+func (s *APIV1Service) UnlockConversation(c echo.Context) error {
+	conversationID, passphrase, ok := s.conversationLockRequest(c, true)
+	if !ok {
+		return nil
+	}
+	status, err := s.ConversationLocks.Unlock(restCurrentUser(c).ID, conversationID, passphrase)
+	if err != nil {
+		return conversationLockError(c, err, "failed to unlock conversation")
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+// POST /api/v1/system/ai/conversations/:id/relock drops the key of a
+// conversation before it expires.
+func (s *APIV1Service) RelockConversation(c echo.Context) error {
+	conversationID, _, ok := s.conversationLockRequest(c, false)
+	if !ok {
+		return nil
+	}
+	userID := restCurrentUser(c).ID
+	if err := s.ConversationLocks.Relock(userID, conversationID); err != nil {
+		return conversationLockError(c, err, "failed to relock conversation")
+	}
+	return c.JSON(http.StatusOK, s.conversationLockStatus(userID, conversationID))
+}
+
+// conversationLockRequest parses the conversation of a request, checking it
+// belongs to the current user, and its passphrase. It writes the error
+// response itself and returns false on failure.
+func (s *APIV1Service) conversationLockRequest(c echo.Context, withPassphrase bool) (int32, string, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil || id <= 0 {
+		_ = restError(c, http.StatusBadRequest, "invalid conversation id")
+		return 0, "", false
+	}
+	conversationID := int32(id)
+	conversations, err := s.Store.ListAIConversations(c.Request().Context(), &store.FindAIConversation{
+		ID:        &conversationID,
+		CreatorID: &restCurrentUser(c).ID,
+	})
+	if err != nil {
+		slog.Error("failed to get conversation", "conversation_id", conversationID, "error", err)
+		_ = restError(c, http.StatusInternalServerError, "failed to get conversation")
+		return 0, "", false
+	}
+	if len(conversations) == 0 {
+		_ = restError(c, http.StatusNotFound, "conversation not found")
+		return 0, "", false
+	}
+	if !withPassphrase {
+		return conversationID, "", true
+	}
+	var body struct {
+		Passphrase string `json:"passphrase"`
+	}
+	if err := c.Bind(&body); err != nil || body.Passphrase == "" {
+		_ = restError(c, http.StatusBadRequest, "passphrase is required")
+		return 0, "", false
+	}
+	return conversationID, body.Passphrase, true
+}
+
+// conversationLockStatus returns the lock status of a conversation of a user.
+func (s *APIV1Service) conversationLockStatus(userID, conversationID int32) *convlock.Status {
+	for _, status := range s.ConversationLocks.List(userID) {
+		if status.ConversationID == conversationID {
+			return status
+		}
+	}
+	return nil
+}
+
+// sealConversationHistory rewrites the blocks of an unlocked conversation,
+// which the store seals, and drops the follow-up suggestions and citations
+// kept in clear.
+func (s *APIV1Service) sealConversationHistory(ctx context.Context, conversationID int32) error {
+	blocks, err := s.Store.ListAIBlocks(ctx, &store.FindAIBlock{ConversationID: &conversationID})
+	if err != nil {
+		return err
+	}
+	for _, block := range blocks {
+		update := &store.UpdateAIBlock{
+			ID:          block.ID,
+			UserInputs:  &block.UserInputs,
+			EventStream: &block.EventStream,
+		}
+		if block.AssistantContent != "" {
+			update.AssistantContent = &block.AssistantContent
+		}
+		if block.ErrorMessage != "" {
+			update.ErrorMessage = &block.ErrorMessage
+		}
+		if _, ok := block.Metadata[aichat.SuggestionsMetadataKey]; ok {
+			update.Metadata = map[string]any{aichat.SuggestionsMetadataKey: nil}
+		}
+		if _, ok := block.Citations(); ok {
+			if update.Metadata == nil {
+				update.Metadata = map[string]any{}
+			}
+			update.Metadata[store.MetadataSectionCitations] = nil
+		}
+		if _, err := s.Store.UpdateAIBlock(ctx, update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// conversationLockError maps the errors of the conversation locks to responses.
+func conversationLockError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, convlock.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, convlock.ErrWrongPassphrase):
+		return restError(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, convlock.ErrNotLocked):
+		return restError(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, convlock.ErrTooManyAttempts):
+		return restError(c, http.StatusTooManyRequests, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
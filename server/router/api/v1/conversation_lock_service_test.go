@@ -0,0 +1,94 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/internal/profile"
+	"github.com/hrygo/divinesense/plugin/convlock"
+	"github.com/hrygo/divinesense/store"
+)
+
+// conversationDriver holds the conversations of the tests.
+type conversationDriver struct {
+	store.Driver
+
+	conversations []*store.AIConversation
+}
+
+func (d *conversationDriver) AgentStatsStore() store.AgentStatsStore       { return nil }
+func (d *conversationDriver) SecurityAuditStore() store.SecurityAuditStore { return nil }
+
+func (d *conversationDriver) ListAIConversations(_ context.Context, find *store.FindAIConversation) ([]*store.AIConversation, error) {
+	var conversations []*store.AIConversation
+	for _, conversation := range d.conversations {
+		if (find.ID == nil || conversation.ID == *find.ID) && (find.CreatorID == nil || conversation.CreatorID == *find.CreatorID) {
+			conversations = append(conversations, conversation)
+		}
+	}
+	return conversations, nil
+}
+
+// lockStore counts the locks created.
+type lockStore struct {
+	created []*convlock.Lock
+}
+
+func (s *lockStore) ListLocks(context.Context) ([]*convlock.Lock, error) { return nil, nil }
+
+func (s *lockStore) CreateLock(_ context.Context, lock *convlock.Lock) error {
+	s.created = append(s.created, lock)
+	return nil
+}
+
+func TestConversationLockRequest(t *testing.T) {
+	locks := &lockStore{}
+	service := &APIV1Service{
+		Store: store.New(&conversationDriver{conversations: []*store.AIConversation{
+			{ID: 1, CreatorID: 1},
+			{ID: 2, CreatorID: 2},
+		}}, &profile.Profile{}),
+		ConversationLocks: convlock.New(locks, 0),
+	}
+
+	tests := []struct {
+		name   string
+		id     string
+		body   string
+		status int
+	}{
+		{name: "invalid id", id: "x", body: `{"passphrase": "secret"}`, status: http.StatusBadRequest},
+		{name: "unknown conversation", id: "3", body: `{"passphrase": "secret"}`, status: http.StatusNotFound},
+		{name: "conversation of another user", id: "2", body: `{"passphrase": "secret"}`, status: http.StatusNotFound},
+		{name: "missing passphrase", id: "1", body: `{}`, status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			c.Set(restUserContextKey, &store.User{ID: 1})
+
+			require.NoError(t, service.LockConversation(c))
+			assert.Equal(t, tt.status, rec.Code)
+			// A single error response, and no lock
+			decoder := json.NewDecoder(rec.Body)
+			var body map[string]any
+			require.NoError(t, decoder.Decode(&body))
+			assert.Equal(t, io.EOF, decoder.Decode(&body))
+			assert.Empty(t, locks.created)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/documentchat"
+)
+
+// registerDocumentChatRoutes registers the API binding conversations to a
+// single document.
+func (s *APIV1Service) registerDocumentChatRoutes(group *echo.Group) {
+	if s.DocumentChats == nil {
+		return
+	}
+	group.GET("/ai/conversations/:id/document", s.GetConversationDocument)
+	group.PUT("/ai/conversations/:id/document", s.BindConversationDocument)
+	group.DELETE("/ai/conversations/:id/document", s.UnbindConversationDocument)
+}
+
+// GET /api/v1/system/ai/conversations/:id/document returns the document a
+// conversation of the current user is bound to; {} when it is not bound.
+func (s *APIV1Service) GetConversationDocument(c echo.Context) error {
+	conversationID, _, ok := s.conversationLockRequest(c, false)
+	if !ok {
+		return nil
+	}
+	doc, err := s.DocumentChats.Load(c.Request().Context(), restCurrentUser(c).ID, conversationID)
+	if errors.Is(err, documentchat.ErrNotFound) {
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		slog.Error("failed to load conversation document", "conversation_id", conversationID, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to load conversation document")
+	}
+	if doc == nil {
+		return c.JSON(http.StatusOK, struct{}{})
+	}
+	return c.JSON(http.StatusOK, documentchat.InfoOf(doc))
+}
+
+// PUT /api/v1/system/ai/conversations/:id/document {"kind": "memo"|"attachment", "uid": "..."}.
+// Binds the conversation to a memo or an attachment of the current user:
+// retrieval then searches its passages only, and answers cite them by
+// offsets. Returns the document.
+func (s *APIV1Service) BindConversationDocument(c echo.Context) error {
+	conversationID, _, ok := s.conversationLockRequest(c, false)
+	if !ok {
+		return nil
+	}
+	var req documentchat.Binding
+	if err := c.Bind(&req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid document binding")
+	}
+	doc, err := s.DocumentChats.Bind(c.Request().Context(), restCurrentUser(c).ID, conversationID, &req)
+	switch {
+	case errors.Is(err, documentchat.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, documentchat.ErrNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	case err != nil:
+		slog.Error("failed to bind conversation document", "conversation_id", conversationID, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to bind conversation document")
+	}
+	return c.JSON(http.StatusOK, documentchat.InfoOf(doc))
+}
+
+// DELETE /api/v1/system/ai/conversations/:id/document returns the
+// conversation to memo retrieval.
+func (s *APIV1Service) UnbindConversationDocument(c echo.Context) error {
+	conversationID, _, ok := s.conversationLockRequest(c, false)
+	if !ok {
+		return nil
+	}
+	if err := s.DocumentChats.Unbind(c.Request().Context(), conversationID); err != nil {
+		slog.Error("failed to unbind conversation document", "conversation_id", conversationID, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to unbind conversation document")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,153 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hrygo/divinesense/plugin/emailin"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/server/auth"
+	"github.com/hrygo/divinesense/store"
+)
+
+const (
+	// emailInPath is the inbound webhook of the mail provider.
+	emailInPath = "/api/v1/inbound/email"
+	// emailInBodyLimit bounds inbound emails with their attachments; each
+	// attachment is also held to the upload size limit.
+	emailInBodyLimit = "64M"
+)
+
+// registerEmailInRoutes registers the inbound webhook, authenticated by the
+// signature of the mail provider, and the API of the current user's inbox.
+func (s *APIV1Service) registerEmailInRoutes(echoServer *echo.Echo, group *echo.Group) {
+	if s.EmailIn == nil {
+		return
+	}
+	echoServer.POST(emailInPath, s.ReceiveEmail, middleware.BodyLimit(emailInBodyLimit))
+	inbox := group.Group("/email-inbox")
+	inbox.GET("", s.GetEmailInbox)
+	inbox.POST("", s.RotateEmailInbox)
+	inbox.DELETE("", s.DeleteEmailInbox)
+}
+
+// POST /api/v1/inbound/email.
+// Saves an email forwarded by the mail provider as a private memo of the user
+// owning the recipient address, with its files as attachments. Permanent
+// rejections answer 406, which Mailgun does not retry.
+func (s *APIV1Service) ReceiveEmail(c echo.Context) error {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid multipart form")
+	}
+	defer form.RemoveAll()
+	value := func(key string) string {
+		if values := form.Value[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	if err := s.EmailIn.Verify(value("timestamp"), value("token"), value("signature"), time.Now()); err != nil {
+		slog.Warn("rejected inbound email", "error", err)
+		return restError(c, http.StatusUnauthorized, "invalid signature")
+	}
+
+	ctx := c.Request().Context()
+	inbox, err := s.EmailIn.Resolve(ctx, value("recipient"))
+	if errors.Is(err, emailin.ErrNotFound) {
+		return restError(c, http.StatusNotAcceptable, "unknown recipient")
+	}
+	if err != nil {
+		slog.Error("failed to resolve inbound email recipient", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to resolve recipient")
+	}
+	user, err := s.Store.GetUser(ctx, &store.FindUser{ID: &inbox.UserID})
+	if err != nil {
+		slog.Error("failed to get inbound email user", "user_id", inbox.UserID, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get user")
+	}
+	if user == nil || user.RowStatus != store.Normal {
+		return restError(c, http.StatusNotAcceptable, "unknown recipient")
+	}
+	email, err := emailin.FromForm(form)
+	if errors.Is(err, emailin.ErrInvalid) {
+		return restError(c, http.StatusNotAcceptable, err.Error())
+	}
+	if err != nil {
+		slog.Error("failed to read inbound email", "user_id", user.ID, "error", err)
+		return restError(c, http.StatusBadRequest, "failed to read email")
+	}
+	ctx = auth.SetUserInContext(ctx, user, "")
+
+	memo := &v1pb.Memo{Content: email.Content(), Visibility: v1pb.Visibility_PRIVATE}
+	for _, file := range email.Attachments {
+		attachment, err := s.AttachmentService.CreateAttachment(ctx, &v1pb.CreateAttachmentRequest{
+			Attachment: &v1pb.Attachment{Filename: file.Filename, Type: file.MimeType, Content: file.Data},
+		})
+		// Files the attachments refuse, e.g. too large, are left out.
+		if status.Code(err) == codes.InvalidArgument {
+			slog.Warn("skipped inbound email attachment", "user_id", user.ID, "filename", file.Filename, "error", err)
+			continue
+		}
+		if err != nil {
+			return restStatusError(c, err)
+		}
+		memo.Attachments = append(memo.Attachments, &v1pb.Attachment{Name: attachment.Name})
+	}
+	created, err := s.MemoService.CreateMemo(ctx, &v1pb.CreateMemoRequest{Memo: memo})
+	if err != nil {
+		return restStatusError(c, err)
+	}
+	slog.Info("saved inbound email", "user_id", user.ID, "memo", created.Name, "attachments", len(memo.Attachments))
+	return c.JSON(http.StatusOK, map[string]any{
+		"name":        created.Name,
+		"uid":         strings.TrimPrefix(created.Name, MemoNamePrefix),
+		"attachments": len(memo.Attachments),
+	})
+}
+
+// GET /api/v1/system/email-inbox returns the secret address of the current
+// user.
+func (s *APIV1Service) GetEmailInbox(c echo.Context) error {
+	inbox, err := s.EmailIn.Inbox(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		return emailInError(c, err, "failed to get email inbox")
+	}
+	return c.JSON(http.StatusOK, inbox)
+}
+
+// POST /api/v1/system/email-inbox creates the secret address of the current
+// user, or replaces it; emails to the previous address are rejected.
+func (s *APIV1Service) RotateEmailInbox(c echo.Context) error {
+	inbox, err := s.EmailIn.Rotate(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		return emailInError(c, err, "failed to create email inbox")
+	}
+	return c.JSON(http.StatusOK, inbox)
+}
+
+// DELETE /api/v1/system/email-inbox deletes the secret address of the
+// current user.
+func (s *APIV1Service) DeleteEmailInbox(c echo.Context) error {
+	if err := s.EmailIn.Delete(c.Request().Context(), restCurrentUser(c).ID); err != nil {
+		return emailInError(c, err, "failed to delete email inbox")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// emailInError maps the errors of the email inboxes to responses.
+func emailInError(c echo.Context, err error, msg string) error {
+	if errors.Is(err, emailin.ErrNotFound) {
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
@@ -0,0 +1,117 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/evolutiontask"
+)
+
+// registerEvolutionTaskRoutes registers the admin API of the Evolution task
+// backlog.
+func (s *APIV1Service) registerEvolutionTaskRoutes(group *echo.Group) {
+	if s.EvolutionTasks == nil {
+		return
+	}
+	tasks := group.Group("/evolution/tasks", restAdminMiddleware)
+	tasks.GET("", s.ListEvolutionTasks)
+	tasks.POST("", s.CreateEvolutionTask)
+	tasks.GET("/:id", s.GetEvolutionTask)
+	tasks.POST("/:id/approve", s.ApproveEvolutionTask)
+	tasks.POST("/:id/reject", s.RejectEvolutionTask)
+}
+
+// GET /api/v1/system/evolution/tasks?status=approved lists the tasks, newest
+// first.
+func (s *APIV1Service) ListEvolutionTasks(c echo.Context) error {
+	tasks, err := s.EvolutionTasks.List(c.Request().Context(), evolutiontask.Status(c.QueryParam("status")))
+	if err != nil {
+		return evolutionTaskError(c, err, "failed to list evolution tasks")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"tasks": tasks})
+}
+
+// POST /api/v1/system/evolution/tasks {"title": "...", "description": "..."}
+// queues a task for approval.
+func (s *APIV1Service) CreateEvolutionTask(c echo.Context) error {
+	var body struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	task, err := s.EvolutionTasks.Create(c.Request().Context(), &evolutiontask.Task{
+		Title:       body.Title,
+		Description: body.Description,
+		CreatorID:   restCurrentUser(c).ID,
+	})
+	if err != nil {
+		return evolutionTaskError(c, err, "failed to create evolution task")
+	}
+	return c.JSON(http.StatusCreated, task)
+}
+
+// GET /api/v1/system/evolution/tasks/:id returns a task with the trace of its
+// run: blocks, commits, diff, test result and pull request.
+func (s *APIV1Service) GetEvolutionTask(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid task id")
+	}
+	task, err := s.EvolutionTasks.Get(c.Request().Context(), id)
+	if err != nil {
+		return evolutionTaskError(c, err, "failed to get evolution task")
+	}
+	return c.JSON(http.StatusOK, task)
+}
+
+// POST /api/v1/system/evolution/tasks/:id/approve {"note": "..."} queues a
+// pending task for a run, or a failed one for a new run. The "/task" command
+// of Evolution Mode runs it.
+func (s *APIV1Service) ApproveEvolutionTask(c echo.Context) error {
+	return s.reviewEvolutionTask(c, s.EvolutionTasks.Approve)
+}
+
+// POST /api/v1/system/evolution/tasks/:id/reject {"note": "..."} closes a
+// pending task without a run.
+func (s *APIV1Service) RejectEvolutionTask(c echo.Context) error {
+	return s.reviewEvolutionTask(c, s.EvolutionTasks.Reject)
+}
+
+func (s *APIV1Service) reviewEvolutionTask(c echo.Context, review func(ctx context.Context, id int64, reviewerID int32, note string) (*evolutiontask.Task, error)) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid task id")
+	}
+	var body struct {
+		Note string `json:"note"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	task, err := review(c.Request().Context(), id, restCurrentUser(c).ID, body.Note)
+	if err != nil {
+		return evolutionTaskError(c, err, "failed to review evolution task")
+	}
+	return c.JSON(http.StatusOK, task)
+}
+
+// evolutionTaskError maps the errors of the task backlog to responses.
+func evolutionTaskError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, evolutiontask.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, evolutiontask.ErrNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, evolutiontask.ErrConflict):
+		return restError(c, http.StatusConflict, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
@@ -0,0 +1,171 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/expense"
+)
+
+// ExpenseRequest is the body for recording or replacing an expense.
+type ExpenseRequest struct {
+	// Amount is in minor units of the currency, e.g. 4550 for 45.50 CNY.
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	Category string `json:"category"`
+	Note     string `json:"note"`
+	// Date is the day of the expense, YYYY-MM-DD.
+	Date string `json:"date"`
+}
+
+// registerExpenseRoutes registers the API of the expenses of users.
+func (s *APIV1Service) registerExpenseRoutes(group *echo.Group) {
+	if s.Expenses == nil {
+		return
+	}
+	expenses := group.Group("/expenses")
+	expenses.GET("", s.ListExpenses)
+	expenses.POST("", s.CreateExpense)
+	expenses.GET("/summary", s.GetExpenseSummary)
+	expenses.GET("/monthly", s.ListMonthlyExpenses)
+	expenses.GET("/:id", s.GetExpense)
+	expenses.PUT("/:id", s.UpdateExpense)
+	expenses.DELETE("/:id", s.DeleteExpense)
+}
+
+// GET /api/v1/system/expenses?from=2026-10-01&to=2026-10-31&category=food&limit=50
+// lists the current user's expenses, latest day first.
+func (s *APIV1Service) ListExpenses(c echo.Context) error {
+	find := &expense.Find{
+		UserID:   restCurrentUser(c).ID,
+		From:     c.QueryParam("from"),
+		To:       c.QueryParam("to"),
+		Category: c.QueryParam("category"),
+	}
+	if limit := c.QueryParam("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return restError(c, http.StatusBadRequest, "invalid limit")
+		}
+		find.Limit = n
+	}
+	expenses, err := s.Expenses.List(c.Request().Context(), find)
+	if err != nil {
+		return expenseError(c, err, "failed to list expenses")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"expenses": expenses})
+}
+
+// POST /api/v1/system/expenses {"amount": 4500, "currency": "CNY",
+// "category": "food", "note": "午饭", "date": "2026-10-16"}.
+func (s *APIV1Service) CreateExpense(c echo.Context) error {
+	req := &ExpenseRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	created, err := s.Expenses.Create(c.Request().Context(), restCurrentUser(c).ID, []*expense.Expense{req.expense(0)})
+	if err != nil {
+		return expenseError(c, err, "failed to create expense")
+	}
+	return c.JSON(http.StatusOK, created[0])
+}
+
+// GET /api/v1/system/expenses/summary?month=2026-10&timezone=Asia/Shanghai
+// sums up the current user's expenses of a month, the current one by default.
+func (s *APIV1Service) GetExpenseSummary(c echo.Context) error {
+	month := c.QueryParam("month")
+	if month == "" {
+		now, err := habitNow(c.QueryParam("timezone"))
+		if err != nil {
+			return restError(c, http.StatusBadRequest, err.Error())
+		}
+		month = now.Format("2006-01")
+	}
+	summary, err := s.Expenses.Summarize(c.Request().Context(), restCurrentUser(c).ID, month)
+	if err != nil {
+		return expenseError(c, err, "failed to summarize expenses")
+	}
+	return c.JSON(http.StatusOK, summary)
+}
+
+// GET /api/v1/system/expenses/monthly?from=2026-01&to=2026-10 returns the
+// current user's totals by month and currency.
+func (s *APIV1Service) ListMonthlyExpenses(c echo.Context) error {
+	totals, err := s.Expenses.Monthly(c.Request().Context(), restCurrentUser(c).ID, c.QueryParam("from"), c.QueryParam("to"))
+	if err != nil {
+		return expenseError(c, err, "failed to summarize expenses")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"months": totals})
+}
+
+// GET /api/v1/system/expenses/:id.
+func (s *APIV1Service) GetExpense(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid expense id")
+	}
+	found, err := s.Expenses.Get(c.Request().Context(), restCurrentUser(c).ID, int32(id))
+	if err != nil {
+		return expenseError(c, err, "failed to get expense")
+	}
+	return c.JSON(http.StatusOK, found)
+}
+
+// PUT /api/v1/system/expenses/:id replaces an expense of the current user.
+func (s *APIV1Service) UpdateExpense(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid expense id")
+	}
+	req := &ExpenseRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	updated := req.expense(restCurrentUser(c).ID)
+	updated.ID = int32(id)
+	updated, err = s.Expenses.Update(c.Request().Context(), updated)
+	if err != nil {
+		return expenseError(c, err, "failed to update expense")
+	}
+	return c.JSON(http.StatusOK, updated)
+}
+
+// DELETE /api/v1/system/expenses/:id.
+func (s *APIV1Service) DeleteExpense(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid expense id")
+	}
+	if err := s.Expenses.Delete(c.Request().Context(), restCurrentUser(c).ID, int32(id)); err != nil {
+		return expenseError(c, err, "failed to delete expense")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// expense returns the expense of a request.
+func (r *ExpenseRequest) expense(userID int32) *expense.Expense {
+	return &expense.Expense{
+		UserID:   userID,
+		Amount:   r.Amount,
+		Currency: r.Currency,
+		Category: r.Category,
+		Note:     r.Note,
+		Date:     r.Date,
+	}
+}
+
+// expenseError maps the errors of the expenses to responses.
+func expenseError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, expense.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, expense.ErrNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
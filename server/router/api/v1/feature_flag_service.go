@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/featureflag"
+	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
+	"github.com/hrygo/divinesense/store"
+)
+
+// newFeatureFlags creates the feature flags, targeting roles by the role of
+// users in the store.
+func newFeatureFlags(st *store.Store) *featureflag.Flags {
+	flags := featureflag.New(featureflag.NewDBStore(st.GetDriver().GetDB()))
+	flags.SetRoleResolver(func(ctx context.Context, userID int32) string {
+		user, err := st.GetUser(ctx, &store.FindUser{ID: &userID})
+		if err != nil || user == nil {
+			return ""
+		}
+		return string(user.Role)
+	})
+	return flags
+}
+
+// registerFeatureFlags declares the flags of chat and of the canaries of an
+// initialized factory.
+func (s *AIService) registerFeatureFlags(factory *aichat.AgentFactory) {
+	if s.FeatureFlags == nil {
+		return
+	}
+	aichat.RegisterFeatureFlags(s.FeatureFlags, factory)
+}
+
+// registerFeatureFlagRoutes registers the flags of the current user and the
+// admin API of the rules.
+func (s *APIV1Service) registerFeatureFlagRoutes(group *echo.Group) {
+	if s.FeatureFlags == nil {
+		return
+	}
+	group.GET("/feature-flags/me", s.GetMyFeatureFlags)
+	flags := group.Group("/feature-flags", restAdminMiddleware)
+	flags.GET("", s.ListFeatureFlags)
+	flags.PUT("/:key", s.UpdateFeatureFlag)
+	flags.DELETE("/:key", s.ResetFeatureFlag)
+}
+
+// GET /api/v1/system/feature-flags/me returns the state of every flag for
+// the current user, for clients to hide the features that are off.
+func (s *APIV1Service) GetMyFeatureFlags(c echo.Context) error {
+	flags := s.FeatureFlags.Evaluate(c.Request().Context(), restCurrentUser(c).ID)
+	return c.JSON(http.StatusOK, map[string]any{"flags": flags})
+}
+
+// GET /api/v1/system/feature-flags lists the flags with their rules (admin only).
+func (s *APIV1Service) ListFeatureFlags(c echo.Context) error {
+	flags, err := s.FeatureFlags.List(c.Request().Context())
+	if err != nil {
+		return featureFlagError(c, err, "failed to list feature flags")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"flags": flags})
+}
+
+// PUT /api/v1/system/feature-flags/:key
+// {"enabled": false, "percent": 10, "users": [2], "roles": ["ADMIN"]}
+// overrides the default of a flag (admin only).
+func (s *APIV1Service) UpdateFeatureFlag(c echo.Context) error {
+	rule := &featureflag.Rule{}
+	if err := c.Bind(rule); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	rule.Key = c.Param("key")
+	flag, err := s.FeatureFlags.SetRule(c.Request().Context(), rule)
+	if err != nil {
+		return featureFlagError(c, err, "failed to update feature flag")
+	}
+	return c.JSON(http.StatusOK, flag)
+}
+
+// DELETE /api/v1/system/feature-flags/:key returns a flag to its default
+// (admin only).
+func (s *APIV1Service) ResetFeatureFlag(c echo.Context) error {
+	flag, err := s.FeatureFlags.Reset(c.Request().Context(), c.Param("key"))
+	if err != nil {
+		return featureFlagError(c, err, "failed to reset feature flag")
+	}
+	return c.JSON(http.StatusOK, flag)
+}
+
+// featureFlagError maps the errors of the feature flags to responses.
+func featureFlagError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, featureflag.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, featureflag.ErrNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
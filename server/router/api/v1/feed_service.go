@@ -0,0 +1,196 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/capture"
+	"github.com/hrygo/divinesense/plugin/feed"
+)
+
+const (
+	// defaultFeedEntries and maxFeedEntries bound the entries listed at once.
+	defaultFeedEntries = 50
+	maxFeedEntries     = 200
+)
+
+// FeedRequest is the body for subscribing to a feed.
+type FeedRequest struct {
+	URL string `json:"url"`
+}
+
+// FeedUpdateRequest is the body for renaming, pausing or resuming a feed.
+type FeedUpdateRequest struct {
+	Title   *string `json:"title"`
+	Enabled *bool   `json:"enabled"`
+}
+
+// registerFeedRoutes registers the API of the feed subscriptions of the
+// current user.
+func (s *APIV1Service) registerFeedRoutes(group *echo.Group) {
+	if s.Feeds == nil {
+		return
+	}
+	feeds := group.Group("/feeds")
+	feeds.GET("", s.ListFeeds)
+	feeds.POST("", s.SubscribeFeed)
+	feeds.PATCH("/:id", s.UpdateFeed)
+	feeds.DELETE("/:id", s.UnsubscribeFeed)
+	feeds.GET("/:id/entries", s.ListFeedEntries)
+	feeds.POST("/:id/refresh", s.RefreshFeed)
+}
+
+// GET /api/v1/system/feeds lists the feed subscriptions of the current user.
+func (s *APIV1Service) ListFeeds(c echo.Context) error {
+	subs, err := s.Feeds.Subscriptions(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		return feedError(c, err, "failed to list feeds")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"feeds": subs})
+}
+
+// POST /api/v1/system/feeds {"url": "https://go.dev/blog/feed.atom"}
+// subscribes the current user to an RSS or Atom feed; its newest entries are
+// saved as read-later memos by the next ingest job.
+func (s *APIV1Service) SubscribeFeed(c echo.Context) error {
+	req := &FeedRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	sub, err := s.Feeds.Subscribe(c.Request().Context(), restCurrentUser(c).ID, req.URL)
+	if err != nil {
+		return feedError(c, err, "failed to subscribe to feed")
+	}
+	return c.JSON(http.StatusCreated, sub)
+}
+
+// PATCH /api/v1/system/feeds/:id {"title": "Go", "enabled": false} renames,
+// pauses or resumes a feed of the current user.
+func (s *APIV1Service) UpdateFeed(c echo.Context) error {
+	id, ok := feedID(c)
+	if !ok {
+		return restError(c, http.StatusBadRequest, "invalid feed id")
+	}
+	req := &FeedUpdateRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	sub, err := s.Feeds.Update(c.Request().Context(), restCurrentUser(c).ID, id, req.Title, req.Enabled)
+	if err != nil {
+		return feedError(c, err, "failed to update feed")
+	}
+	return c.JSON(http.StatusOK, sub)
+}
+
+// DELETE /api/v1/system/feeds/:id unsubscribes the current user from a feed;
+// the memos of its entries are kept.
+func (s *APIV1Service) UnsubscribeFeed(c echo.Context) error {
+	id, ok := feedID(c)
+	if !ok {
+		return restError(c, http.StatusBadRequest, "invalid feed id")
+	}
+	if err := s.Feeds.Unsubscribe(c.Request().Context(), restCurrentUser(c).ID, id); err != nil {
+		return feedError(c, err, "failed to unsubscribe from feed")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GET /api/v1/system/feeds/:id/entries?limit=50 lists the entries of a feed
+// of the current user, newest first, with their memos once ingested.
+func (s *APIV1Service) ListFeedEntries(c echo.Context) error {
+	id, ok := feedID(c)
+	if !ok {
+		return restError(c, http.StatusBadRequest, "invalid feed id")
+	}
+	limit := defaultFeedEntries
+	if value := c.QueryParam("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return restError(c, http.StatusBadRequest, "invalid limit")
+		}
+		limit = min(n, maxFeedEntries)
+	}
+	entries, err := s.Feeds.Entries(c.Request().Context(), restCurrentUser(c).ID, id, limit)
+	if err != nil {
+		return feedError(c, err, "failed to list feed entries")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"entries": entries})
+}
+
+// POST /api/v1/system/feeds/:id/refresh fetches a feed of the current user
+// now and returns the number of new entries.
+func (s *APIV1Service) RefreshFeed(c echo.Context) error {
+	id, ok := feedID(c)
+	if !ok {
+		return restError(c, http.StatusBadRequest, "invalid feed id")
+	}
+	added, err := s.Feeds.Refresh(c.Request().Context(), restCurrentUser(c).ID, id)
+	if err != nil {
+		return feedError(c, err, "failed to refresh feed")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"added": added})
+}
+
+func feedID(c echo.Context) (int32, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	return int32(id), err == nil
+}
+
+// feedError maps the errors of the feeds to responses. Feeds that cannot be
+// fetched are reported as bad gateways.
+func feedError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, feed.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, feed.ErrNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, feed.ErrExists):
+		return restError(c, http.StatusConflict, err.Error())
+	case errors.Is(err, feed.ErrLimit):
+		return restError(c, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, feed.ErrFetch):
+		return restError(c, http.StatusBadGateway, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
+
+// feedMemos implements feed.Saver: entries become private memos with their
+// source, queued for later reading.
+type feedMemos struct {
+	service *APIV1Service
+}
+
+func (f *feedMemos) Save(ctx context.Context, entry *feed.Entry) (string, error) {
+	memoUID, err := (&meetingMemos{service: f.service}).CreateMemo(ctx, entry.UserID, entry.Content())
+	if err != nil {
+		return "", err
+	}
+	if entry.URL != "" && f.service.captureStore != nil {
+		site := ""
+		if u, err := url.Parse(entry.URL); err == nil {
+			site = u.Hostname()
+		}
+		if err := f.service.captureStore.SaveSource(ctx, &capture.Source{
+			MemoUID: memoUID,
+			URL:     entry.URL,
+			Title:   entry.Title,
+			Site:    site,
+			Client:  feed.Tag,
+		}); err != nil {
+			slog.Warn("failed to save feed memo source", "memo", memoUID, "error", err)
+		}
+	}
+	if f.service.readLater != nil {
+		if _, err := f.service.readLater.Enqueue(ctx, entry.UserID, memoUID); err != nil {
+			slog.Warn("failed to queue feed memo", "memo", memoUID, "error", err)
+		}
+	}
+	return memoUID, nil
+}
@@ -0,0 +1,131 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/ai/routing"
+	"github.com/hrygo/divinesense/plugin/fewshot"
+)
+
+// routingExampleSet returns the non-holdout examples as the router's set.
+func routingExampleSet(examples []*fewshot.Example) *routing.ExampleSet {
+	used, _ := fewshot.Split(examples)
+	converted := make([]routing.RouteExample, 0, len(used))
+	for _, example := range used {
+		converted = append(converted, routing.RouteExample{
+			Query: example.Query,
+			Route: routing.AgentType(example.Route),
+		})
+	}
+	return routing.NewExampleSet(converted)
+}
+
+// reloadFewShots hot reloads changed examples into the router; prompts read
+// them when parrots are created.
+func (s *APIV1Service) reloadFewShots(examples []*fewshot.Example) {
+	if s.AIService == nil {
+		return
+	}
+	s.AIService.routerServiceMu.RLock()
+	defer s.AIService.routerServiceMu.RUnlock()
+	if s.AIService.routerService != nil {
+		s.AIService.routerService.SetExamples(routingExampleSet(examples))
+	}
+}
+
+// loadFewShots loads the examples into a new router.
+func (s *AIService) loadFewShots(ctx context.Context, router *routing.Service) {
+	if s.FewShots == nil {
+		return
+	}
+	examples, err := s.FewShots.Examples(ctx)
+	if err != nil {
+		slog.Warn("failed to load few-shot examples", "error", err)
+		return
+	}
+	router.SetExamples(routingExampleSet(examples))
+}
+
+// registerFewShotRoutes registers the admin API of the few-shot examples.
+func (s *APIV1Service) registerFewShotRoutes(group *echo.Group) {
+	if s.FewShots == nil {
+		return
+	}
+	examples := group.Group("/few-shot-examples", restAdminMiddleware)
+	examples.GET("", s.ListFewShotExamples)
+	examples.POST("", s.CreateFewShotExample)
+	examples.PUT("/:id", s.UpdateFewShotExample)
+	examples.DELETE("/:id", s.DeleteFewShotExample)
+}
+
+// GET /api/v1/system/few-shot-examples.
+func (s *APIV1Service) ListFewShotExamples(c echo.Context) error {
+	examples, err := s.FewShots.Examples(c.Request().Context())
+	if err != nil {
+		slog.Error("failed to list few-shot examples", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to list examples")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"examples": examples, "routes": chatRoutes})
+}
+
+// POST /api/v1/system/few-shot-examples
+// {"route": "memo", "query": "找一下上周的周报", "answer": "找到 2 条周报：…", "holdout": false}.
+func (s *APIV1Service) CreateFewShotExample(c echo.Context) error {
+	example := &fewshot.Example{}
+	if err := c.Bind(example); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	created, err := s.FewShots.Create(c.Request().Context(), example)
+	if err != nil {
+		return fewShotError(c, err, "failed to create example")
+	}
+	return c.JSON(http.StatusOK, created)
+}
+
+// PUT /api/v1/system/few-shot-examples/:id replaces an example.
+func (s *APIV1Service) UpdateFewShotExample(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid example id")
+	}
+	example := &fewshot.Example{}
+	if err := c.Bind(example); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	example.ID = int32(id)
+	updated, err := s.FewShots.Update(c.Request().Context(), example)
+	if err != nil {
+		return fewShotError(c, err, "failed to update example")
+	}
+	return c.JSON(http.StatusOK, updated)
+}
+
+// DELETE /api/v1/system/few-shot-examples/:id.
+func (s *APIV1Service) DeleteFewShotExample(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid example id")
+	}
+	if err := s.FewShots.Delete(c.Request().Context(), int32(id)); err != nil {
+		return fewShotError(c, err, "failed to delete example")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// fewShotError maps the errors of the examples to responses.
+func fewShotError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, fewshot.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, fewshot.ErrNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
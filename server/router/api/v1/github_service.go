@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/internal/profile"
+	"github.com/hrygo/divinesense/plugin/github"
+)
+
+// githubMCPPath is the MCP endpoint serving the GitHub tools to the Claude
+// Code CLI of Geek Mode sessions, authenticated by the tokens of the tools.
+const githubMCPPath = "/api/v1/geek/github/mcp"
+
+// GitHubSettingsRequest is the body for saving the GitHub settings. An
+// omitted token keeps the saved one.
+type GitHubSettingsRequest struct {
+	Token         string   `json:"token"`
+	Repositories  []string `json:"repositories"`
+	AllowComments bool     `json:"allow_comments"`
+}
+
+// registerGitHubRoutes registers the MCP endpoint of the GitHub tools and the
+// API of the current user's GitHub settings.
+func (s *APIV1Service) registerGitHubRoutes(echoServer *echo.Echo, group *echo.Group) {
+	if s.GitHub == nil {
+		return
+	}
+	echoServer.Any(githubMCPPath, echo.WrapHandler(s.GitHub.MCPHandler()))
+	settings := group.Group("/geek/github")
+	settings.GET("", s.GetGitHubSettings)
+	settings.PUT("", s.SaveGitHubSettings)
+	settings.DELETE("", s.DeleteGitHubSettings)
+}
+
+// GET /api/v1/system/geek/github returns the current user's GitHub settings,
+// without the token.
+func (s *APIV1Service) GetGitHubSettings(c echo.Context) error {
+	settings, err := s.GitHub.Settings(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		return githubError(c, err, "failed to get github settings")
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+// PUT /api/v1/system/geek/github {"token": "github_pat_...", "repositories":
+// ["owner/name", "owner/*"], "allow_comments": false} saves the current
+// user's GitHub settings. Geek Mode sessions get the tools at their next
+// request, on the allow-listed repositories only.
+func (s *APIV1Service) SaveGitHubSettings(c echo.Context) error {
+	req := &GitHubSettingsRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	settings, err := s.GitHub.SaveSettings(c.Request().Context(), &github.Settings{
+		UserID:        restCurrentUser(c).ID,
+		Token:         req.Token,
+		Repositories:  req.Repositories,
+		AllowComments: req.AllowComments,
+	})
+	if err != nil {
+		return githubError(c, err, "failed to save github settings")
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+// DELETE /api/v1/system/geek/github deletes the current user's GitHub
+// settings and token.
+func (s *APIV1Service) DeleteGitHubSettings(c echo.Context) error {
+	if err := s.GitHub.DeleteSettings(c.Request().Context(), restCurrentUser(c).ID); err != nil {
+		return githubError(c, err, "failed to delete github settings")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// githubError maps the errors of the GitHub tools to responses.
+func githubError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, github.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, github.ErrNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
+
+// githubMCPURL returns the URL of the MCP endpoint of the GitHub tools as the
+// CLI, running on this host, reaches it: the listening address, or the
+// instance URL when the server listens on a unix socket.
+func githubMCPURL(profile *profile.Profile) string {
+	if profile.UNIXSock != "" {
+		if profile.InstanceURL == "" {
+			return ""
+		}
+		return strings.TrimRight(profile.InstanceURL, "/") + githubMCPPath
+	}
+	host := profile.Addr
+	if host == "" || net.ParseIP(host) != nil && net.ParseIP(host).IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(profile.Port)) + githubMCPPath
+}
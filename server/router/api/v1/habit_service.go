@@ -0,0 +1,181 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/habit"
+	"github.com/hrygo/divinesense/server/timezone"
+)
+
+// HabitRequest is the body for creating or replacing a habit.
+type HabitRequest struct {
+	Name      string          `json:"name"`
+	Frequency habit.Frequency `json:"frequency"`
+	Target    int             `json:"target"`
+	Archived  bool            `json:"archived"`
+}
+
+// HabitCompletionRequest is the body for logging a habit.
+type HabitCompletionRequest struct {
+	// Date is the day the habit was done, YYYY-MM-DD; today when empty.
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+	// Timezone sets today; the server time zone when empty.
+	Timezone string `json:"timezone"`
+}
+
+// registerHabitRoutes registers the API of the habits users track.
+func (s *APIV1Service) registerHabitRoutes(group *echo.Group) {
+	if s.Habits == nil {
+		return
+	}
+	habits := group.Group("/habits")
+	habits.GET("", s.ListHabits)
+	habits.POST("", s.CreateHabit)
+	habits.PUT("/:id", s.UpdateHabit)
+	habits.DELETE("/:id", s.DeleteHabit)
+	habits.POST("/:id/completions", s.LogHabit)
+	habits.DELETE("/:id/completions/:date", s.UnlogHabit)
+}
+
+// GET /api/v1/system/habits?timezone=Asia/Shanghai&archived=true lists the
+// current user's habits with their progress and streaks.
+func (s *APIV1Service) ListHabits(c echo.Context) error {
+	now, err := habitNow(c.QueryParam("timezone"))
+	if err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	archived := c.QueryParam("archived") == "true"
+	statuses, err := s.Habits.Statuses(c.Request().Context(), restCurrentUser(c).ID, now, archived)
+	if err != nil {
+		return habitError(c, err, "failed to list habits")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"habits": statuses})
+}
+
+// POST /api/v1/system/habits {"name": "跑步", "frequency": "WEEKLY", "target": 3}.
+func (s *APIV1Service) CreateHabit(c echo.Context) error {
+	req := &HabitRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	created, err := s.Habits.Create(c.Request().Context(), req.habit(restCurrentUser(c).ID))
+	if err != nil {
+		return habitError(c, err, "failed to create habit")
+	}
+	return c.JSON(http.StatusOK, created)
+}
+
+// PUT /api/v1/system/habits/:id replaces a habit of the current user; its
+// completions are kept.
+func (s *APIV1Service) UpdateHabit(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid habit id")
+	}
+	req := &HabitRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	updated := req.habit(restCurrentUser(c).ID)
+	updated.ID = int32(id)
+	updated, err = s.Habits.Update(c.Request().Context(), updated)
+	if err != nil {
+		return habitError(c, err, "failed to update habit")
+	}
+	return c.JSON(http.StatusOK, updated)
+}
+
+// DELETE /api/v1/system/habits/:id removes a habit with its completions.
+func (s *APIV1Service) DeleteHabit(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid habit id")
+	}
+	if err := s.Habits.Delete(c.Request().Context(), restCurrentUser(c).ID, int32(id)); err != nil {
+		return habitError(c, err, "failed to delete habit")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// POST /api/v1/system/habits/:id/completions {"date": "2026-10-16", "count": 1,
+// "timezone": "Asia/Shanghai"} logs a habit and returns its status.
+func (s *APIV1Service) LogHabit(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid habit id")
+	}
+	req := &HabitCompletionRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	now, err := habitNow(req.Timezone)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	if req.Date == "" {
+		req.Date = now.Format(time.DateOnly)
+	}
+	if req.Count == 0 {
+		req.Count = 1
+	}
+	status, err := s.Habits.Log(c.Request().Context(), restCurrentUser(c).ID, int32(id), req.Date, req.Count, now)
+	if err != nil {
+		return habitError(c, err, "failed to log habit")
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+// DELETE /api/v1/system/habits/:id/completions/:date removes the completions
+// of a day.
+func (s *APIV1Service) UnlogHabit(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid habit id")
+	}
+	if err := s.Habits.Unlog(c.Request().Context(), restCurrentUser(c).ID, int32(id), c.Param("date")); err != nil {
+		return habitError(c, err, "failed to delete habit completion")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// habit returns the habit of a request.
+func (r *HabitRequest) habit(userID int32) *habit.Habit {
+	return &habit.Habit{
+		UserID:    userID,
+		Name:      r.Name,
+		Frequency: r.Frequency,
+		Target:    r.Target,
+		Archived:  r.Archived,
+	}
+}
+
+// habitNow returns the current time in a time zone, or in the server one.
+func habitNow(tz string) (time.Time, error) {
+	if tz == "" {
+		return time.Now(), nil
+	}
+	loc, err := timezone.ParseTimezone(tz)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Now().In(loc), nil
+}
+
+// habitError maps the errors of the habits to responses.
+func habitError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, habit.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, habit.ErrNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
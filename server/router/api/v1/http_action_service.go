@@ -0,0 +1,179 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/httpaction"
+)
+
+// HTTPActionRequest is the body for creating or updating an HTTP action.
+// AuthValue is write-only: it is never returned by the API.
+type HTTPActionRequest struct {
+	Name               *string        `json:"name"`
+	Description        *string        `json:"description"`
+	Method             *string        `json:"method"`
+	URL                *string        `json:"url"`
+	Parameters         map[string]any `json:"parameters"`
+	AuthHeader         *string        `json:"auth_header"`
+	AuthValue          *string        `json:"auth_value"`
+	AllowedRoles       []string       `json:"allowed_roles"`
+	RateLimitPerMinute *int32         `json:"rate_limit_per_minute"`
+	TimeoutSeconds     *int32         `json:"timeout_seconds"`
+	Enabled            *bool          `json:"enabled"`
+}
+
+// registerHTTPActionRoutes registers the admin API for HTTP actions.
+func (s *APIV1Service) registerHTTPActionRoutes(group *echo.Group) {
+	if s.httpActionStore == nil {
+		return
+	}
+	actions := group.Group("/http-actions", restAdminMiddleware)
+	actions.GET("", s.ListHTTPActions)
+	actions.POST("", s.CreateHTTPAction)
+	actions.PATCH("/:id", s.UpdateHTTPAction)
+	actions.DELETE("/:id", s.DeleteHTTPAction)
+}
+
+// GET /api/v1/system/http-actions.
+func (s *APIV1Service) ListHTTPActions(c echo.Context) error {
+	actions, err := s.httpActionStore.ListActions(c.Request().Context(), &httpaction.FindAction{})
+	if err != nil {
+		slog.Error("failed to list http actions", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to list http actions")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"actions": actions})
+}
+
+// POST /api/v1/system/http-actions.
+func (s *APIV1Service) CreateHTTPAction(c echo.Context) error {
+	req := &HTTPActionRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.Name == nil || req.Description == nil || req.URL == nil {
+		return restError(c, http.StatusBadRequest, "name, description and url are required")
+	}
+
+	action := &httpaction.Action{
+		Name:         strings.TrimSpace(*req.Name),
+		Description:  *req.Description,
+		Method:       http.MethodPost,
+		URL:          strings.TrimSpace(*req.URL),
+		Parameters:   req.Parameters,
+		AllowedRoles: req.AllowedRoles,
+		Enabled:      true,
+		CreatorID:    restCurrentUser(c).ID,
+	}
+	applyHTTPActionRequest(action, req)
+	if err := action.Validate(); err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+
+	created, err := s.httpActionStore.CreateAction(c.Request().Context(), action)
+	if err != nil {
+		slog.Error("failed to create http action", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to create http action")
+	}
+	s.HTTPActions.Invalidate()
+	return c.JSON(http.StatusOK, created)
+}
+
+// PATCH /api/v1/system/http-actions/:id.
+func (s *APIV1Service) UpdateHTTPAction(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid action id")
+	}
+	req := &HTTPActionRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	ctx := c.Request().Context()
+	existing, err := s.httpActionStore.GetAction(ctx, int32(id))
+	if err != nil {
+		slog.Error("failed to get http action", "id", id, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get http action")
+	}
+	if existing == nil {
+		return restError(c, http.StatusNotFound, "http action not found")
+	}
+
+	// Validate the merged result so partial updates cannot produce an invalid action.
+	merged := *existing
+	if req.Description != nil {
+		merged.Description = *req.Description
+	}
+	if req.URL != nil {
+		merged.URL = strings.TrimSpace(*req.URL)
+	}
+	if req.AllowedRoles != nil {
+		merged.AllowedRoles = req.AllowedRoles
+	}
+	applyHTTPActionRequest(&merged, req)
+	if err := merged.Validate(); err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+
+	updated, err := s.httpActionStore.UpdateAction(ctx, &httpaction.UpdateAction{
+		ID:                 int32(id),
+		Description:        req.Description,
+		Method:             req.Method,
+		URL:                req.URL,
+		Parameters:         req.Parameters,
+		AuthHeader:         req.AuthHeader,
+		AuthValue:          req.AuthValue,
+		AllowedRoles:       req.AllowedRoles,
+		RateLimitPerMinute: req.RateLimitPerMinute,
+		TimeoutSeconds:     req.TimeoutSeconds,
+		Enabled:            req.Enabled,
+	})
+	if err != nil {
+		slog.Error("failed to update http action", "id", id, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to update http action")
+	}
+	s.HTTPActions.Invalidate()
+	return c.JSON(http.StatusOK, updated)
+}
+
+// DELETE /api/v1/system/http-actions/:id.
+func (s *APIV1Service) DeleteHTTPAction(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid action id")
+	}
+	if err := s.httpActionStore.DeleteAction(c.Request().Context(), int32(id)); err != nil {
+		slog.Error("failed to delete http action", "id", id, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to delete http action")
+	}
+	s.HTTPActions.Invalidate()
+	return c.NoContent(http.StatusNoContent)
+}
+
+// applyHTTPActionRequest copies the optional scalar fields shared by create and update.
+func applyHTTPActionRequest(action *httpaction.Action, req *HTTPActionRequest) {
+	if req.Method != nil {
+		action.Method = strings.ToUpper(*req.Method)
+		req.Method = &action.Method
+	}
+	if req.AuthHeader != nil {
+		action.AuthHeader = *req.AuthHeader
+	}
+	if req.AuthValue != nil {
+		action.AuthValue = *req.AuthValue
+	}
+	if req.RateLimitPerMinute != nil {
+		action.RateLimitPerMinute = *req.RateLimitPerMinute
+	}
+	if req.TimeoutSeconds != nil {
+		action.TimeoutSeconds = *req.TimeoutSeconds
+	}
+	if req.Enabled != nil {
+		action.Enabled = *req.Enabled
+	}
+}
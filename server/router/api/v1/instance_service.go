@@ -11,6 +11,7 @@ import (
 	"github.com/hrygo/divinesense/internal/profile"
 	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
 	storepb "github.com/hrygo/divinesense/proto/gen/store"
+	"github.com/hrygo/divinesense/server/runner/jobs"
 	"github.com/hrygo/divinesense/store"
 )
 
@@ -19,6 +20,8 @@ type InstanceService struct {
 	Store     *store.Store
 	Profile   *profile.Profile
 	AIService *AIService
+	// Jobs is the scheduler of the periodic jobs the admins list and run.
+	Jobs *jobs.Scheduler
 }
 
 // GetInstanceProfile returns the instance profile.
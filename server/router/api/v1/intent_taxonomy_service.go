@@ -0,0 +1,176 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/ai/routing"
+	"github.com/hrygo/divinesense/plugin/intenttaxonomy"
+)
+
+// chatRoutes are the routes of the chat router that admin-defined intents
+// and few-shot examples may take.
+var chatRoutes = []string{
+	string(agentpkg.RouteTypeMemo),
+	string(agentpkg.RouteTypeSchedule),
+	string(agentpkg.RouteTypeGeneral),
+}
+
+// routingTaxonomy converts the intents of the taxonomy for the router.
+func routingTaxonomy(intents []*intenttaxonomy.Intent) *routing.Taxonomy {
+	converted := make([]routing.TaxonomyIntent, 0, len(intents))
+	for _, intent := range intents {
+		converted = append(converted, routing.TaxonomyIntent{
+			Name:         intent.Name,
+			Examples:     intent.Examples,
+			TargetExpert: intent.TargetExpert,
+			Orchestrate:  intent.Orchestrate,
+		})
+	}
+	return routing.NewTaxonomy(converted)
+}
+
+// reloadIntentTaxonomy hot reloads a changed taxonomy into the router.
+func (s *APIV1Service) reloadIntentTaxonomy(intents []*intenttaxonomy.Intent) {
+	if s.AIService == nil {
+		return
+	}
+	s.AIService.routerServiceMu.RLock()
+	defer s.AIService.routerServiceMu.RUnlock()
+	if s.AIService.routerService != nil {
+		s.AIService.routerService.SetTaxonomy(routingTaxonomy(intents))
+	}
+}
+
+// loadIntentTaxonomy loads the taxonomy into a new router.
+func (s *AIService) loadIntentTaxonomy(ctx context.Context, router *routing.Service) {
+	if s.IntentTaxonomy == nil {
+		return
+	}
+	intents, err := s.IntentTaxonomy.Intents(ctx)
+	if err != nil {
+		slog.Warn("failed to load intent taxonomy", "error", err)
+		return
+	}
+	router.SetTaxonomy(routingTaxonomy(intents))
+}
+
+// registerIntentTaxonomyRoutes registers the admin API of the intent taxonomy.
+func (s *APIV1Service) registerIntentTaxonomyRoutes(group *echo.Group) {
+	if s.IntentTaxonomy == nil {
+		return
+	}
+	intents := group.Group("/intent-taxonomy", restAdminMiddleware)
+	intents.GET("", s.ListTaxonomyIntents)
+	intents.POST("", s.CreateTaxonomyIntent)
+	intents.GET("/export", s.ExportIntentTaxonomy)
+	intents.POST("/import", s.ImportIntentTaxonomy)
+	intents.GET("/:name", s.GetTaxonomyIntent)
+	intents.PUT("/:name", s.UpdateTaxonomyIntent)
+	intents.DELETE("/:name", s.DeleteTaxonomyIntent)
+}
+
+// GET /api/v1/system/intent-taxonomy.
+func (s *APIV1Service) ListTaxonomyIntents(c echo.Context) error {
+	intents, err := s.IntentTaxonomy.Intents(c.Request().Context())
+	if err != nil {
+		slog.Error("failed to list taxonomy intents", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to list intents")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"intents": intents, "targets": chatRoutes})
+}
+
+// GET /api/v1/system/intent-taxonomy/:name.
+func (s *APIV1Service) GetTaxonomyIntent(c echo.Context) error {
+	intent, err := s.IntentTaxonomy.Get(c.Request().Context(), c.Param("name"))
+	if err != nil {
+		return intentTaxonomyError(c, err, "failed to get intent")
+	}
+	return c.JSON(http.StatusOK, intent)
+}
+
+// POST /api/v1/system/intent-taxonomy
+// {"name": "weekly_review", "examples": ["周回顾"], "target_expert": "memo", "orchestrate": false}.
+func (s *APIV1Service) CreateTaxonomyIntent(c echo.Context) error {
+	intent := &intenttaxonomy.Intent{}
+	if err := c.Bind(intent); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	created, err := s.IntentTaxonomy.Create(c.Request().Context(), intent)
+	if err != nil {
+		return intentTaxonomyError(c, err, "failed to create intent")
+	}
+	return c.JSON(http.StatusOK, created)
+}
+
+// PUT /api/v1/system/intent-taxonomy/:name replaces the definition of an intent.
+func (s *APIV1Service) UpdateTaxonomyIntent(c echo.Context) error {
+	intent := &intenttaxonomy.Intent{}
+	if err := c.Bind(intent); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	intent.Name = c.Param("name")
+	updated, err := s.IntentTaxonomy.Update(c.Request().Context(), intent)
+	if err != nil {
+		return intentTaxonomyError(c, err, "failed to update intent")
+	}
+	return c.JSON(http.StatusOK, updated)
+}
+
+// DELETE /api/v1/system/intent-taxonomy/:name.
+func (s *APIV1Service) DeleteTaxonomyIntent(c echo.Context) error {
+	if err := s.IntentTaxonomy.Delete(c.Request().Context(), c.Param("name")); err != nil {
+		return intentTaxonomyError(c, err, "failed to delete intent")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GET /api/v1/system/intent-taxonomy/export returns the taxonomy as a
+// document for the import endpoint of another instance.
+func (s *APIV1Service) ExportIntentTaxonomy(c echo.Context) error {
+	export, err := s.IntentTaxonomy.Export(c.Request().Context())
+	if err != nil {
+		slog.Error("failed to export intent taxonomy", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to export intent taxonomy")
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="intent-taxonomy.json"`)
+	return c.JSON(http.StatusOK, export)
+}
+
+// POST /api/v1/system/intent-taxonomy/import?mode=merge|replace with an
+// exported taxonomy as body. merge (default) adds and updates intents;
+// replace also deletes the intents missing from the document.
+func (s *APIV1Service) ImportIntentTaxonomy(c echo.Context) error {
+	mode := c.QueryParam("mode")
+	if mode != "" && mode != "merge" && mode != "replace" {
+		return restError(c, http.StatusBadRequest, "mode must be merge or replace")
+	}
+	export := &intenttaxonomy.Export{}
+	if err := c.Bind(export); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	imported, err := s.IntentTaxonomy.Import(c.Request().Context(), export, mode == "replace")
+	if err != nil {
+		return intentTaxonomyError(c, err, "failed to import intent taxonomy")
+	}
+	return c.JSON(http.StatusOK, map[string]int{"imported": imported})
+}
+
+// intentTaxonomyError maps the errors of the taxonomy to responses.
+func intentTaxonomyError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, intenttaxonomy.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, intenttaxonomy.ErrNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, intenttaxonomy.ErrExists):
+		return restError(c, http.StatusConflict, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
@@ -0,0 +1,179 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/server/runner/jobs"
+)
+
+// newJobs creates the scheduler of the periodic jobs of the available
+// features. Must be called once the features are created.
+func (s *APIV1Service) newJobs() *jobs.Scheduler {
+	var locker jobs.Locker
+	if s.Profile.Driver == "postgres" {
+		locker = jobs.NewPGLocker(s.Store.GetDriver().GetDB())
+	}
+	scheduler := jobs.New(locker)
+	register := func(job *jobs.Job) {
+		if err := scheduler.Register(job); err != nil {
+			slog.Error("failed to register job", "job", job.Name, "error", err)
+		}
+	}
+	if quota := s.GeekWorkspaceQuota(); quota != nil {
+		register(&jobs.Job{
+			Name:        "geek-workspace-gc",
+			Description: "Remove the stale CLI sessions and temporary files of the Geek Mode workspaces",
+			Schedule:    "0 */6 * * *",
+			Jitter:      10 * time.Minute,
+			Timeout:     30 * time.Minute,
+			Run:         quota.GC,
+		})
+	}
+	if s.OfflineSync != nil {
+		register(&jobs.Job{
+			Name:        "offline-sync-prune",
+			Description: "Delete the expired changes of the offline sync change log",
+			Schedule:    "30 3 * * *",
+			Jitter:      15 * time.Minute,
+			Timeout:     10 * time.Minute,
+			Run:         s.OfflineSync.Prune,
+		})
+	}
+	if s.Webhooks != nil {
+		register(&jobs.Job{
+			Name:        "webhook-schedule-due",
+			Description: "Publish the schedule.due webhook events of the schedules that just started",
+			Schedule:    "* * * * *",
+			Timeout:     time.Minute,
+			Run:         s.publishDueSchedules,
+		})
+		register(&jobs.Job{
+			Name:        "webhook-delivery-prune",
+			Description: "Delete the webhook delivery logs older than 30 days",
+			Schedule:    "15 4 * * *",
+			Jitter:      15 * time.Minute,
+			Timeout:     10 * time.Minute,
+			Run:         s.Webhooks.Prune,
+		})
+	}
+	if s.Feeds != nil {
+		register(&jobs.Job{
+			Name:        "feed-fetch",
+			Description: "Fetch the due RSS/Atom feed subscriptions and store their new entries",
+			Schedule:    "*/15 * * * *",
+			Jitter:      2 * time.Minute,
+			Timeout:     10 * time.Minute,
+			Run:         s.Feeds.FetchDue,
+		})
+		register(&jobs.Job{
+			Name:        "feed-ingest",
+			Description: "Summarize and tag the new feed entries and save them as read-later memos",
+			Schedule:    "*/5 * * * *",
+			Timeout:     5 * time.Minute,
+			Run:         s.Feeds.IngestPending,
+		})
+	}
+	if s.Calendars != nil && s.Calendars.CalDAVEnabled() {
+		register(&jobs.Job{
+			Name:        "calendar-sync",
+			Description: "Sync the schedules of users with their CalDAV calendars, both ways",
+			Schedule:    "*/15 * * * *",
+			Jitter:      2 * time.Minute,
+			Timeout:     10 * time.Minute,
+			Run:         s.Calendars.SyncAll,
+		})
+	}
+	if s.Reminders != nil {
+		register(&jobs.Job{
+			Name:        "schedule-reminders",
+			Description: "Send the schedule reminders due through the channels of their users, escalate the unacknowledged critical ones, and retry the failed ones",
+			Schedule:    "* * * * *",
+			Timeout:     time.Minute,
+			Run:         s.Reminders.Dispatch,
+		})
+		register(&jobs.Job{
+			Name:        "schedule-reminder-prune",
+			Description: "Delete the schedule reminder deliveries and escalations older than 30 days",
+			Schedule:    "45 4 * * *",
+			Jitter:      15 * time.Minute,
+			Timeout:     10 * time.Minute,
+			Run:         s.Reminders.Prune,
+		})
+	}
+	return scheduler
+}
+
+// ListInstanceJobs lists the periodic jobs with their next and last runs.
+func (s *InstanceService) ListInstanceJobs(ctx context.Context, _ *v1pb.ListInstanceJobsRequest) (*v1pb.ListInstanceJobsResponse, error) {
+	if err := s.requireJobsAdmin(ctx); err != nil {
+		return nil, err
+	}
+	resp := &v1pb.ListInstanceJobsResponse{}
+	for _, job := range s.Jobs.List() {
+		resp.Jobs = append(resp.Jobs, convertInstanceJob(job))
+	}
+	return resp, nil
+}
+
+// RunInstanceJob starts a run of a job now; its result is reported by the
+// list once done.
+func (s *InstanceService) RunInstanceJob(ctx context.Context, req *v1pb.RunInstanceJobRequest) (*emptypb.Empty, error) {
+	if err := s.requireJobsAdmin(ctx); err != nil {
+		return nil, err
+	}
+	err := s.Jobs.Trigger(req.Name)
+	switch {
+	case errors.Is(err, jobs.ErrNotFound):
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	case errors.Is(err, jobs.ErrRunning):
+		return nil, status.Errorf(codes.Aborted, "%v", err)
+	case err != nil:
+		slog.Error("failed to run job", "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to run job")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// requireJobsAdmin checks that the scheduler is configured and that the
+// current user is an admin.
+func (s *InstanceService) requireJobsAdmin(ctx context.Context) error {
+	user, err := getCurrentUser(ctx, s.Store)
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, "unauthorized")
+	}
+	if !isSuperUser(user) {
+		return status.Errorf(codes.PermissionDenied, "permission denied")
+	}
+	if s.Jobs == nil {
+		return status.Errorf(codes.Unavailable, "the job scheduler is not available")
+	}
+	return nil
+}
+
+func convertInstanceJob(job *jobs.Status) *v1pb.InstanceJob {
+	instanceJob := &v1pb.InstanceJob{
+		Name:        job.Name,
+		Description: job.Description,
+		Schedule:    job.Schedule,
+		NextRunTs:   job.NextRunTs,
+		Running:     job.Running,
+	}
+	if job.LastRun != nil {
+		instanceJob.LastRun = &v1pb.InstanceJobRun{
+			Trigger:    string(job.LastRun.Trigger),
+			Outcome:    string(job.LastRun.Outcome),
+			StartedTs:  job.LastRun.StartedTs,
+			FinishedTs: job.LastRun.FinishedTs,
+			Error:      job.LastRun.Error,
+		}
+	}
+	return instanceJob
+}
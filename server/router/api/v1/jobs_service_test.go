@@ -0,0 +1,30 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/server/runner/jobs"
+)
+
+func TestInstanceJobsUnauthenticated(t *testing.T) {
+	s := &InstanceService{Jobs: jobs.New(nil)}
+	_, err := s.ListInstanceJobs(context.Background(), &v1pb.ListInstanceJobsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = s.RunInstanceJob(context.Background(), &v1pb.RunInstanceJobRequest{Name: "feed-fetch"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestConvertInstanceJob(t *testing.T) {
+	job := &jobs.Status{Name: "feed-fetch", Description: "Fetch feeds", Schedule: "*/15 * * * *", NextRunTs: 900}
+	assert.Equal(t, &v1pb.InstanceJob{Name: "feed-fetch", Description: "Fetch feeds", Schedule: "*/15 * * * *", NextRunTs: 900}, convertInstanceJob(job))
+
+	job.LastRun = &jobs.Result{Trigger: jobs.TriggerManual, Outcome: jobs.OutcomeFailed, StartedTs: 10, FinishedTs: 12, Error: "timeout"}
+	assert.Equal(t, &v1pb.InstanceJobRun{Trigger: "manual", Outcome: "failed", StartedTs: 10, FinishedTs: 12, Error: "timeout"}, convertInstanceJob(job).LastRun)
+}
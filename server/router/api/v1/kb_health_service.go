@@ -0,0 +1,236 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lithammer/shortuuid/v4"
+
+	"github.com/hrygo/divinesense/plugin/i18n"
+	"github.com/hrygo/divinesense/plugin/kbhealth"
+	"github.com/hrygo/divinesense/plugin/webhook"
+	"github.com/hrygo/divinesense/server/runner/memopayload"
+	"github.com/hrygo/divinesense/store"
+)
+
+// registerKBHealthRoutes registers the knowledge-base health report endpoints.
+func (s *APIV1Service) registerKBHealthRoutes(group *echo.Group) {
+	if s.kbHealthStore == nil {
+		return
+	}
+	group.GET("/kb-health/settings", s.GetKBHealthSettings)
+	group.PUT("/kb-health/settings", s.UpdateKBHealthSettings)
+	group.GET("/kb-health/report", s.GetKBHealthReport)
+	group.POST("/kb-health/report/send", s.SendKBHealthReport)
+}
+
+// GET /api/v1/system/kb-health/settings.
+func (s *APIV1Service) GetKBHealthSettings(c echo.Context) error {
+	settings, err := s.kbHealthStore.GetSettings(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		slog.Error("failed to get kb health settings", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get kb health settings")
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+// PUT /api/v1/system/kb-health/settings.
+// Fields missing from the body keep their current value.
+func (s *APIV1Service) UpdateKBHealthSettings(c echo.Context) error {
+	ctx := c.Request().Context()
+	user := restCurrentUser(c)
+	settings, err := s.kbHealthStore.GetSettings(ctx, user.ID)
+	if err != nil {
+		slog.Error("failed to get kb health settings", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get kb health settings")
+	}
+	if err := c.Bind(settings); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	settings.UserID = user.ID
+	if err := settings.Validate(); err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	saved, err := s.kbHealthStore.SaveSettings(ctx, settings)
+	if err != nil {
+		slog.Error("failed to save kb health settings", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to save kb health settings")
+	}
+	return c.JSON(http.StatusOK, saved)
+}
+
+// GET /api/v1/system/kb-health/report.
+// Analyzes the current user's knowledge base now, without delivering the report.
+func (s *APIV1Service) GetKBHealthReport(c echo.Context) error {
+	ctx := c.Request().Context()
+	settings, err := s.kbHealthStore.GetSettings(ctx, restCurrentUser(c).ID)
+	if err != nil {
+		slog.Error("failed to get kb health settings", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get kb health settings")
+	}
+	report, err := s.buildKBHealthReport(ctx, settings)
+	if err != nil {
+		slog.Error("failed to build kb health report", "user_id", settings.UserID, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to build kb health report")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"report": report, "markdown": report.Markdown()})
+}
+
+// POST /api/v1/system/kb-health/report/send.
+// Builds and delivers a report now; the weekly schedule is unchanged.
+func (s *APIV1Service) SendKBHealthReport(c echo.Context) error {
+	ctx := c.Request().Context()
+	settings, err := s.kbHealthStore.GetSettings(ctx, restCurrentUser(c).ID)
+	if err != nil {
+		slog.Error("failed to get kb health settings", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get kb health settings")
+	}
+	report, err := s.buildKBHealthReport(ctx, settings)
+	if err != nil {
+		slog.Error("failed to build kb health report", "user_id", settings.UserID, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to build kb health report")
+	}
+	if err := s.deliverKBHealthReport(ctx, settings, report); err != nil {
+		slog.Error("failed to deliver kb health report", "user_id", settings.UserID, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to deliver kb health report")
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// NewKBHealthRunner returns the runner of weekly health reports, or nil when
+// reports are unavailable (not PostgreSQL).
+func (s *APIV1Service) NewKBHealthRunner() *kbhealth.Runner {
+	if s.kbHealthStore == nil {
+		return nil
+	}
+	return kbhealth.NewRunner(s.kbHealthStore, s.buildKBHealthReport, s.deliverKBHealthReport)
+}
+
+// buildKBHealthReport snapshots the knowledge base of the settings' user and
+// analyzes it. Embeddings are only used when AI is enabled.
+func (s *APIV1Service) buildKBHealthReport(ctx context.Context, settings *kbhealth.Settings) (*kbhealth.Report, error) {
+	userID := settings.UserID
+	normal := store.Normal
+	memos, err := s.Store.ListMemos(ctx, &store.FindMemo{CreatorID: &userID, RowStatus: &normal, ExcludeComments: true})
+	if err != nil {
+		return nil, fmt.Errorf("list memos: %w", err)
+	}
+
+	input := &kbhealth.Input{
+		Memos:        make([]*kbhealth.Memo, 0, len(memos)),
+		Related:      make(map[int32]bool),
+		ExistingUIDs: make(map[string]bool),
+		Locale:       userLocale(ctx, s.Store, userID),
+	}
+	ids := make(map[int32]bool, len(memos))
+	uids := make(map[string]bool, len(memos))
+	for _, memo := range memos {
+		ids[memo.ID], uids[memo.UID] = true, true
+		var tags []string
+		if memo.Payload != nil {
+			tags = memo.Payload.Tags
+		}
+		input.Memos = append(input.Memos, &kbhealth.Memo{
+			ID:        memo.ID,
+			UID:       memo.UID,
+			Content:   memo.Content,
+			Tags:      tags,
+			UpdatedTs: memo.UpdatedTs,
+		})
+	}
+
+	reference := store.MemoRelationReference
+	relations, err := s.Store.ListMemoRelations(ctx, &store.FindMemoRelation{Type: &reference})
+	if err != nil {
+		return nil, fmt.Errorf("list memo relations: %w", err)
+	}
+	for _, relation := range relations {
+		if ids[relation.MemoID] || ids[relation.RelatedMemoID] {
+			input.Related[relation.MemoID], input.Related[relation.RelatedMemoID] = true, true
+		}
+	}
+
+	// Links to archived memos or to memos of other users are not broken.
+	var outside []string
+	for _, memo := range input.Memos {
+		for _, uid := range kbhealth.LinkedUIDs(memo.Content) {
+			if !uids[uid] {
+				outside = append(outside, uid)
+			}
+		}
+	}
+	if len(outside) > 0 {
+		linked, err := s.Store.ListMemos(ctx, &store.FindMemo{UIDList: outside, ExcludeContent: true})
+		if err != nil {
+			return nil, fmt.Errorf("list linked memos: %w", err)
+		}
+		for _, memo := range linked {
+			input.ExistingUIDs[memo.UID] = true
+		}
+	}
+
+	if s.AIService != nil {
+		model := s.Store.EmbeddingModel()
+		embeddings, err := s.Store.ListMemoEmbeddings(ctx, &store.FindMemoEmbedding{Model: &model})
+		if err != nil {
+			return nil, fmt.Errorf("list memo embeddings: %w", err)
+		}
+		input.Embeddings = make(map[int32][]float32)
+		for _, embedding := range embeddings {
+			if ids[embedding.MemoID] {
+				input.Embeddings[embedding.MemoID] = embedding.Embedding
+			}
+		}
+	}
+
+	return kbhealth.Analyze(input, settings, time.Now()), nil
+}
+
+// deliverKBHealthReport saves the report as a private memo or posts it to the
+// user's webhooks, as the settings say.
+func (s *APIV1Service) deliverKBHealthReport(ctx context.Context, settings *kbhealth.Settings, report *kbhealth.Report) error {
+	content := report.Markdown()
+	if settings.Delivery == kbhealth.DeliveryWebhook {
+		webhooks, err := s.Store.GetUserWebhooks(ctx, settings.UserID)
+		if err != nil {
+			return fmt.Errorf("get webhooks: %w", err)
+		}
+		if len(webhooks) == 0 {
+			return fmt.Errorf("user %d has no webhook", settings.UserID)
+		}
+		for _, hook := range webhooks {
+			webhook.PostAsync(&webhook.WebhookRequestPayload{
+				URL:          hook.Url,
+				ActivityType: "memos.kb_health.report",
+				Creator:      fmt.Sprintf("%s%d", UserNamePrefix, settings.UserID),
+				Report: &webhook.ReportPayload{
+					Title:    i18n.T(report.Locale, "kbhealth.report.webhook_title"),
+					Markdown: content,
+					Data:     report,
+				},
+			})
+		}
+		return nil
+	}
+
+	now := time.Now().Unix()
+	create := &store.Memo{
+		UID:        shortuuid.New(),
+		CreatorID:  settings.UserID,
+		Content:    content,
+		Visibility: store.Private,
+		CreatedTs:  now,
+		UpdatedTs:  now,
+	}
+	if err := memopayload.RebuildMemoPayload(create, s.MarkdownService); err != nil {
+		return fmt.Errorf("rebuild memo payload: %w", err)
+	}
+	if _, err := s.Store.CreateMemo(ctx, create); err != nil {
+		return fmt.Errorf("create report memo: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package v1
+
+import (
+	"time"
+
+	aistats "github.com/hrygo/divinesense/ai/services/stats"
+	"github.com/hrygo/divinesense/internal/profile"
+	"github.com/hrygo/divinesense/server/middleware"
+	"github.com/hrygo/divinesense/store"
+)
+
+// newLoadShedder creates the load shedder of AI chat from the thresholds of
+// the profile, probing the database and the session stats queue.
+func newLoadShedder(profile *profile.Profile, store *store.Store, persister *aistats.Persister) *middleware.LoadShedder {
+	return middleware.NewLoadShedder(middleware.LoadShedConfig{
+		CPUPercent:   profile.LoadShedCPUPercent,
+		DBLatency:    time.Duration(profile.LoadShedDBLatencyMs) * time.Millisecond,
+		QueuePercent: profile.LoadShedQueuePercent,
+	}, store.GetDriver().GetDB(), persister)
+}
+
+// LoadShedder returns the load shedder of AI chat, nil without AI.
+func (s *APIV1Service) LoadShedder() *middleware.LoadShedder {
+	if s.AIService == nil {
+		return nil
+	}
+	return s.AIService.LoadShedder
+}
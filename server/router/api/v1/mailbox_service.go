@@ -0,0 +1,117 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/mailbox"
+	"github.com/hrygo/divinesense/store"
+)
+
+// ActionItemStatusRequest is the body for updating an action item.
+type ActionItemStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// registerMailboxRoutes registers the API of the current user's IMAP account
+// and of the action items the mail parrot saved from their email.
+func (s *APIV1Service) registerMailboxRoutes(group *echo.Group) {
+	if s.Mailbox == nil {
+		return
+	}
+	account := group.Group("/email/account")
+	account.GET("", s.GetEmailAccount)
+	account.PUT("", s.SaveEmailAccount)
+	account.DELETE("", s.DeleteEmailAccount)
+	items := group.Group("/email/action-items")
+	items.GET("", s.ListEmailActionItems)
+	items.PATCH("/:id", s.UpdateEmailActionItem)
+}
+
+// GET /api/v1/system/email/account returns the IMAP account of the current
+// user, without its password.
+func (s *APIV1Service) GetEmailAccount(c echo.Context) error {
+	account, err := s.Mailbox.Account(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		return mailboxError(c, err, "failed to get email account")
+	}
+	return c.JSON(http.StatusOK, account)
+}
+
+// PUT /api/v1/system/email/account connects the IMAP account of the current
+// user once it signed in; an empty password keeps the saved one. Only hosts
+// and admins may connect servers on private addresses.
+func (s *APIV1Service) SaveEmailAccount(c echo.Context) error {
+	account := &mailbox.Account{}
+	if err := c.Bind(account); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	user := restCurrentUser(c)
+	allowPrivate := user.Role == store.RoleHost || user.Role == store.RoleAdmin
+	saved, err := s.Mailbox.SaveAccount(c.Request().Context(), user.ID, account, allowPrivate)
+	if err != nil {
+		return mailboxError(c, err, "failed to save email account")
+	}
+	return c.JSON(http.StatusOK, saved)
+}
+
+// DELETE /api/v1/system/email/account disconnects the IMAP account of the
+// current user; their action items are kept.
+func (s *APIV1Service) DeleteEmailAccount(c echo.Context) error {
+	if err := s.Mailbox.DeleteAccount(c.Request().Context(), restCurrentUser(c).ID); err != nil {
+		return mailboxError(c, err, "failed to delete email account")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GET /api/v1/system/email/action-items?status=UNREAD lists the action items
+// of the current user, newest first.
+func (s *APIV1Service) ListEmailActionItems(c echo.Context) error {
+	var status *store.InboxStatus
+	if value := c.QueryParam("status"); value != "" {
+		inboxStatus := store.InboxStatus(value)
+		if inboxStatus != store.UNREAD && inboxStatus != store.ARCHIVED {
+			return restError(c, http.StatusBadRequest, "status must be UNREAD or ARCHIVED")
+		}
+		status = &inboxStatus
+	}
+	items, err := s.Mailbox.ActionItems(c.Request().Context(), restCurrentUser(c).ID, status)
+	if err != nil {
+		return mailboxError(c, err, "failed to list email action items")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"items": items})
+}
+
+// PATCH /api/v1/system/email/action-items/:id {"status": "ARCHIVED"} marks an
+// action item of the current user done, or UNREAD again.
+func (s *APIV1Service) UpdateEmailActionItem(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid action item id")
+	}
+	req := &ActionItemStatusRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	item, err := s.Mailbox.SetActionItemStatus(c.Request().Context(), restCurrentUser(c).ID, int32(id), store.InboxStatus(req.Status))
+	if err != nil {
+		return mailboxError(c, err, "failed to update email action item")
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// mailboxError maps the errors of the mailboxes to responses.
+func mailboxError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, mailbox.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, mailbox.ErrNotFound), errors.Is(err, mailbox.ErrItemNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lithammer/shortuuid/v4"
+
+	"github.com/hrygo/divinesense/plugin/meetingnotes"
+	"github.com/hrygo/divinesense/server/runner/memopayload"
+	"github.com/hrygo/divinesense/server/service/schedule"
+	"github.com/hrygo/divinesense/store"
+)
+
+// newMeetingNotes creates the meeting notes of the meeting parrot, saved as
+// private memos and scheduled with the schedule service.
+func (s *APIV1Service) newMeetingNotes() *meetingnotes.Notes {
+	return meetingnotes.New(&meetingMemos{service: s}, &meetingScheduler{schedules: schedule.NewService(s.Store)})
+}
+
+// meetingMemos implements meetingnotes.Memos.
+type meetingMemos struct {
+	service *APIV1Service
+}
+
+func (m *meetingMemos) CreateMemo(ctx context.Context, userID int32, content string) (string, error) {
+	now := time.Now().Unix()
+	create := &store.Memo{
+		UID:        shortuuid.New(),
+		CreatorID:  userID,
+		Content:    content,
+		Visibility: store.Private,
+		CreatedTs:  now,
+		UpdatedTs:  now,
+	}
+	if err := memopayload.RebuildMemoPayload(create, m.service.MarkdownService); err != nil {
+		return "", fmt.Errorf("rebuild memo payload: %w", err)
+	}
+	memo, err := m.service.Store.CreateMemo(ctx, create)
+	if err != nil {
+		return "", err
+	}
+	return memo.UID, nil
+}
+
+func (m *meetingMemos) GetMemoContent(ctx context.Context, userID int32, uid string) (string, bool, error) {
+	memo, err := m.service.Store.GetMemo(ctx, &store.FindMemo{UID: &uid, CreatorID: &userID})
+	if err != nil || memo == nil || memo.RowStatus != store.Normal {
+		return "", false, err
+	}
+	return memo.Content, true, nil
+}
+
+// meetingScheduler implements meetingnotes.Scheduler.
+type meetingScheduler struct {
+	schedules schedule.Service
+}
+
+func (m *meetingScheduler) CreateAllDay(ctx context.Context, userID int32, title, description string, day time.Time) error {
+	end := day.AddDate(0, 0, 1).Unix()
+	timezone := day.Location().String()
+	if day.Location() == time.Local {
+		timezone = "" // the schedule default
+	}
+	_, err := m.schedules.CreateSchedule(ctx, userID, &schedule.CreateScheduleRequest{
+		Title:       title,
+		Description: description,
+		StartTs:     day.Unix(),
+		EndTs:       &end,
+		AllDay:      true,
+		Timezone:    timezone,
+	})
+	return err
+}
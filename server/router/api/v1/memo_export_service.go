@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/memoexport"
+)
+
+// registerMemoExportRoutes registers the export of the memos.
+func (s *APIV1Service) registerMemoExportRoutes(group *echo.Group) {
+	group.GET("/memos/export", s.ExportMemos)
+}
+
+// GET /api/v1/system/memos/export?since=.
+// Streams a zip archive of the memos of the current user and their
+// attachments, like the export command does; the import API imports it back
+// with format=divinesense. since, in Unix seconds, only exports the memos
+// updated since then; the manifest.json of the archive has the exported_ts
+// of the next incremental export.
+func (s *APIV1Service) ExportMemos(c echo.Context) error {
+	var since int64
+	if v := c.QueryParam("since"); v != "" {
+		var err error
+		if since, err = strconv.ParseInt(v, 10, 64); err != nil || since < 0 {
+			return restError(c, http.StatusBadRequest, "invalid since")
+		}
+	}
+
+	filename := fmt.Sprintf("divinesense-memos-%s.zip", time.Now().Format("20060102-150405"))
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, "application/zip")
+	header.Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	c.Response().WriteHeader(http.StatusOK)
+
+	exporter := memoexport.New(memoexport.NewMemoStore(s.Store, s.AttachmentService.GetAttachmentBlob))
+	if _, err := exporter.Export(c.Request().Context(), c.Response(), &memoexport.Options{
+		UserID: restCurrentUser(c).ID,
+		Since:  since,
+	}); err != nil {
+		// The archive has started: clients get a truncated zip
+		slog.Error("failed to export memos", "error", err)
+	}
+	return nil
+}
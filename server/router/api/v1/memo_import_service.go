@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hrygo/divinesense/plugin/memoimport"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/store"
+)
+
+// memoImportMaxBytes bounds the zipped folders imported at once.
+const memoImportMaxBytes = 256 << 20
+
+// ImportMemos imports a zip of a folder of Markdown notes, an Obsidian vault,
+// a Logseq graph or an export as memos of the current user like the import
+// command does. Invalid requests fail with a status; once the folder is
+// scanned, the progress is streamed, the last event being "finished" unless
+// the import stopped.
+func (s *MemoService) ImportMemos(request *v1pb.ImportMemosRequest, stream grpc.ServerStreamingServer[v1pb.ImportMemosResponse]) error {
+	format := memoimport.Format(request.Format)
+	if format == "" {
+		format = memoimport.FormatMarkdown
+	}
+	if !format.Valid() {
+		return status.Errorf(codes.InvalidArgument, "format must be markdown, obsidian, logseq or divinesense")
+	}
+	if len(request.Archive) > memoImportMaxBytes {
+		return status.Errorf(codes.InvalidArgument, "the archive must be at most %d MiB", memoImportMaxBytes>>20)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(request.Archive), int64(len(request.Archive)))
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "the archive must be a zip of the folder")
+	}
+	ctx := stream.Context()
+	user, err := fetchCurrentUser(ctx, s.Store)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get user")
+	}
+	if user == nil {
+		return status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+	var visibility string
+	if request.Visibility != v1pb.Visibility_VISIBILITY_UNSPECIFIED {
+		visibility = request.Visibility.String()
+	}
+
+	// Send errors surface as the stream context ends
+	started := false
+	send := func(event *memoimport.Event) {
+		started = true
+		_ = stream.Send(convertMemoImportEvent(event))
+	}
+	importer := memoimport.New(memoimport.NewMemoStore(s.Store, s.MarkdownService,
+		func(ctx context.Context, attachment *store.Attachment) error {
+			return SaveAttachmentBlob(ctx, s.Profile, s.Store, attachment)
+		}))
+	_, err = importer.Import(ctx, archive, &memoimport.Options{
+		Format:     format,
+		UserID:     user.ID,
+		Visibility: visibility,
+		DryRun:     request.DryRun,
+	}, send)
+	if err == nil || ctx.Err() != nil {
+		return nil
+	}
+	if started {
+		slog.Error("memo import stopped", "error", err)
+		send(&memoimport.Event{Type: memoimport.EventFailed, Error: "import stopped"})
+		return nil
+	}
+	if errors.Is(err, memoimport.ErrInvalid) {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	slog.Error("failed to import memos", "error", err)
+	return status.Errorf(codes.Internal, "failed to import memos")
+}
+
+func convertMemoImportEvent(event *memoimport.Event) *v1pb.ImportMemosResponse {
+	return &v1pb.ImportMemosResponse{
+		Type:    string(event.Type),
+		Path:    event.Path,
+		MemoUid: event.MemoUID,
+		Error:   event.Error,
+		Progress: &v1pb.ImportMemosProgress{
+			Total:      int32(event.Progress.Total),
+			Processed:  int32(event.Progress.Processed),
+			Imported:   int32(event.Progress.Imported),
+			Duplicates: int32(event.Progress.Duplicates),
+			Skipped:    int32(event.Progress.Skipped),
+			Failed:     int32(event.Progress.Failed),
+		},
+	}
+}
@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hrygo/divinesense/plugin/memoimport"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+)
+
+// importStream records the events of an import.
+type importStream struct {
+	grpc.ServerStream
+	events []*v1pb.ImportMemosResponse
+}
+
+func (s *importStream) Context() context.Context { return context.Background() }
+
+func (s *importStream) Send(event *v1pb.ImportMemosResponse) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestImportMemosValidation(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	_, err := w.Create("note.md")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	s := &MemoService{}
+	for _, request := range []*v1pb.ImportMemosRequest{
+		{Archive: buf.Bytes(), Format: "notion"},
+		{Archive: []byte("not a zip")},
+	} {
+		stream := &importStream{}
+		err := s.ImportMemos(request, stream)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Empty(t, stream.events)
+	}
+
+	stream := &importStream{}
+	err = s.ImportMemos(&v1pb.ImportMemosRequest{Archive: buf.Bytes(), Format: "obsidian"}, stream)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestConvertMemoImportEvent(t *testing.T) {
+	event := &memoimport.Event{Type: memoimport.EventImported, Path: "a.md", MemoUID: "m1", Progress: memoimport.Progress{Total: 3, Processed: 1, Imported: 1}}
+	assert.Equal(t, &v1pb.ImportMemosResponse{
+		Type:     "imported",
+		Path:     "a.md",
+		MemoUid:  "m1",
+		Progress: &v1pb.ImportMemosProgress{Total: 3, Processed: 1, Imported: 1},
+	}, convertMemoImportEvent(event))
+}
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -11,11 +12,14 @@ import (
 	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/hrygo/divinesense/internal/base"
 	"github.com/hrygo/divinesense/internal/profile"
 	"github.com/hrygo/divinesense/plugin/markdown"
+	"github.com/hrygo/divinesense/plugin/scripting"
+	"github.com/hrygo/divinesense/plugin/snippet"
 	"github.com/hrygo/divinesense/plugin/webhook"
 	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
 	storepb "github.com/hrygo/divinesense/proto/gen/store"
@@ -30,6 +34,9 @@ type MemoService struct {
 	AIService       *AIService
 	MarkdownService markdown.Service
 	Profile         *profile.Profile
+	ScriptHooks     *scripting.Manager // Optional: admin-defined memo.create hooks
+	Snippets        *snippet.Library   // Optional: code library of memos tagged #snippet
+	Webhooks        *webhook.Registry  // Optional: signed callbacks of memo events
 }
 
 func (s *MemoService) CreateMemo(ctx context.Context, request *v1pb.CreateMemoRequest) (*v1pb.Memo, error) {
@@ -88,6 +95,17 @@ func (s *MemoService) CreateMemo(ctx context.Context, request *v1pb.CreateMemoRe
 	if instanceMemoRelatedSetting.DisallowPublicVisibility && create.Visibility == store.Public {
 		return nil, status.Errorf(codes.PermissionDenied, "disable public memos system setting is enabled")
 	}
+	if s.ScriptHooks.HasHooks(ctx, scripting.TriggerMemoCreate) {
+		outcome := s.ScriptHooks.Apply(ctx, scripting.TriggerMemoCreate, map[string]any{
+			"content":    create.Content,
+			"visibility": create.Visibility.String(),
+			"creator":    user.Username,
+		})
+		if outcome.Rejected {
+			return nil, status.Errorf(codes.FailedPrecondition, "memo rejected: %s", outcome.Reason)
+		}
+		create.Content = appendMemoTags(outcome.Content, outcome.Tags)
+	}
 	contentLengthLimit, err := s.getContentLengthLimit(ctx)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get content length limit")
@@ -151,10 +169,12 @@ func (s *MemoService) CreateMemo(ctx context.Context, request *v1pb.CreateMemoRe
 	if err := s.DispatchMemoCreatedWebhook(ctx, memoMessage); err != nil {
 		slog.Warn("Failed to dispatch memo created webhook", slog.Any("err", err))
 	}
+	s.indexMemoSnippets(ctx, memo)
 
 	// Trigger async enrichment (summary, tags, title) if AI service is available
 	if s.AIService != nil && s.AIService.IsLLMEnabled() {
 		s.AIService.TriggerEnrichment(fmt.Sprintf("%d", memo.ID), memo.Content, "", user.ID)
+		s.AIService.IndexMemoEntities(ctx, memo)
 	}
 
 	return memoMessage, nil
@@ -235,7 +255,6 @@ func (s *MemoService) ListMemos(ctx context.Context, request *v1pb.ListMemosRequ
 		return nil, status.Errorf(codes.Internal, "failed to list memos: %v", err)
 	}
 
-	memoMessages := []*v1pb.Memo{}
 	nextPageToken := ""
 	if len(memos) == limitPlusOne {
 		memos = memos[:limit]
@@ -245,12 +264,97 @@ func (s *MemoService) ListMemos(ctx context.Context, request *v1pb.ListMemosRequ
 		}
 	}
 
-	if len(memos) == 0 {
-		response := &v1pb.ListMemosResponse{
-			Memos:         memoMessages,
-			NextPageToken: nextPageToken,
+	memoMessages, err := s.convertMemosFromStore(ctx, memos)
+	if err != nil {
+		return nil, err
+	}
+	response := &v1pb.ListMemosResponse{
+		Memos:         memoMessages,
+		NextPageToken: nextPageToken,
+	}
+	return response, nil
+}
+
+// SearchMemos searches the memos visible to the user by keywords, with the
+// full-text search of the database.
+func (s *MemoService) SearchMemos(ctx context.Context, request *v1pb.SearchMemosRequest) (*v1pb.SearchMemosResponse, error) {
+	const (
+		defaultSearchPageSize = 20
+		maxSearchPageSize     = 100
+	)
+
+	query := strings.TrimSpace(request.Query)
+	if query == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "query is required")
+	}
+
+	currentUser, err := fetchCurrentUser(ctx, s.Store)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user")
+	}
+
+	var limit, offset int
+	if request.PageToken != "" {
+		var pageToken v1pb.PageToken
+		if err := unmarshalPageToken(request.PageToken, &pageToken); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token: %v", err)
 		}
-		return response, nil
+		limit = int(pageToken.Limit)
+		offset = int(pageToken.Offset)
+	} else {
+		limit = int(request.PageSize)
+	}
+	if limit <= 0 {
+		limit = defaultSearchPageSize
+	}
+	limit = min(limit, maxSearchPageSize)
+
+	search := &store.SearchMemos{
+		Query:           query,
+		ExcludeComments: true,
+		Limit:           limit + 1,
+		Offset:          offset,
+	}
+	if currentUser != nil {
+		search.ViewerID = currentUser.ID
+	}
+	if err := search.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid search: %v", err)
+	}
+	results, err := s.Store.SearchMemos(ctx, search)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to search memos: %v", err)
+	}
+
+	nextPageToken := ""
+	if len(results) > limit {
+		results = results[:limit]
+		nextPageToken, err = getPageToken(limit, offset+limit)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get next page token, error: %v", err)
+		}
+	}
+
+	memos := make([]*store.Memo, 0, len(results))
+	for _, result := range results {
+		memos = append(memos, result.Memo)
+	}
+	memoMessages, err := s.convertMemosFromStore(ctx, memos)
+	if err != nil {
+		return nil, err
+	}
+	return &v1pb.SearchMemosResponse{
+		Memos:         memoMessages,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// convertMemosFromStore converts memos with their reactions and attachments,
+// loaded for the whole list.
+func (s *MemoService) convertMemosFromStore(ctx context.Context, memos []*store.Memo) ([]*v1pb.Memo, error) {
+	memoMessages := []*v1pb.Memo{}
+	if len(memos) == 0 {
+		return memoMessages, nil
 	}
 
 	reactionMap := make(map[string][]*store.Reaction)
@@ -294,12 +398,7 @@ func (s *MemoService) ListMemos(ctx context.Context, request *v1pb.ListMemosRequ
 
 		memoMessages = append(memoMessages, memoMessage)
 	}
-
-	response := &v1pb.ListMemosResponse{
-		Memos:         memoMessages,
-		NextPageToken: nextPageToken,
-	}
-	return response, nil
+	return memoMessages, nil
 }
 
 func (s *MemoService) GetMemo(ctx context.Context, request *v1pb.GetMemoRequest) (*v1pb.Memo, error) {
@@ -487,10 +586,12 @@ func (s *MemoService) UpdateMemo(ctx context.Context, request *v1pb.UpdateMemoRe
 	if err := s.DispatchMemoUpdatedWebhook(ctx, memoMessage); err != nil {
 		slog.Warn("Failed to dispatch memo updated webhook", slog.Any("err", err))
 	}
+	s.indexMemoSnippets(ctx, memo)
 
 	// Trigger async enrichment (summary, tags, title) if content changed and AI service is available
 	if s.AIService != nil && s.AIService.IsLLMEnabled() {
 		s.AIService.TriggerEnrichment(fmt.Sprintf("%d", memo.ID), memo.Content, "", user.ID)
+		s.AIService.IndexMemoEntities(ctx, memo)
 	}
 
 	return memoMessage, nil
@@ -560,6 +661,9 @@ func (s *MemoService) DeleteMemo(ctx context.Context, request *v1pb.DeleteMemoRe
 	if err = s.Store.DeleteMemo(ctx, &store.DeleteMemo{ID: memo.ID}); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to delete memo")
 	}
+	if s.AIService != nil {
+		s.AIService.ForgetMemoEntities(ctx, memo)
+	}
 
 	return &emptypb.Empty{}, nil
 }
@@ -739,24 +843,32 @@ func (s *MemoService) getContentLengthLimit(ctx context.Context) (int, error) {
 
 // DispatchMemoCreatedWebhook dispatches webhook when memo is created.
 func (s *MemoService) DispatchMemoCreatedWebhook(ctx context.Context, memo *v1pb.Memo) error {
-	return s.dispatchMemoRelatedWebhook(ctx, memo, "memos.memo.created")
+	return s.dispatchMemoRelatedWebhook(ctx, memo, "memos.memo.created", webhook.EventMemoCreated)
 }
 
 // DispatchMemoUpdatedWebhook dispatches webhook when memo is updated.
 func (s *MemoService) DispatchMemoUpdatedWebhook(ctx context.Context, memo *v1pb.Memo) error {
-	return s.dispatchMemoRelatedWebhook(ctx, memo, "memos.memo.updated")
+	return s.dispatchMemoRelatedWebhook(ctx, memo, "memos.memo.updated", webhook.EventMemoUpdated)
 }
 
 // DispatchMemoDeletedWebhook dispatches webhook when memo is deleted.
 func (s *MemoService) DispatchMemoDeletedWebhook(ctx context.Context, memo *v1pb.Memo) error {
-	return s.dispatchMemoRelatedWebhook(ctx, memo, "memos.memo.deleted")
+	return s.dispatchMemoRelatedWebhook(ctx, memo, "memos.memo.deleted", webhook.EventMemoDeleted)
 }
 
-func (s *MemoService) dispatchMemoRelatedWebhook(ctx context.Context, memo *v1pb.Memo, activityType string) error {
+func (s *MemoService) dispatchMemoRelatedWebhook(ctx context.Context, memo *v1pb.Memo, activityType, event string) error {
 	creatorID, err := ExtractUserIDFromName(memo.Creator)
 	if err != nil {
 		return status.Errorf(codes.InvalidArgument, "invalid memo creator")
 	}
+	if s.Webhooks != nil {
+		// The registry queues the event; a failure must not fail the memo change
+		if data, err := protojson.Marshal(memo); err != nil {
+			slog.Warn("failed to encode memo for webhooks", "memo", memo.Name, "error", err)
+		} else if err := s.Webhooks.Publish(ctx, event, creatorID, "", json.RawMessage(data)); err != nil {
+			slog.Warn("failed to publish memo webhook event", "memo", memo.Name, "event", event, "error", err)
+		}
+	}
 	webhooks, err := s.Store.GetUserWebhooks(ctx, creatorID)
 	if err != nil {
 		return err
@@ -1000,3 +1112,20 @@ func (s *MemoService) GetRelatedMemos(ctx context.Context, request *v1pb.GetRela
 
 	return response, nil
 }
+
+// appendMemoTags appends hashtags not already present in content.
+// Tags are stored in the memo payload, which is rebuilt from content.
+func appendMemoTags(content string, tags []string) string {
+	var missing []string
+	for _, tag := range tags {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+		if tag == "" || strings.Contains(content, "#"+tag) {
+			continue
+		}
+		missing = append(missing, "#"+tag)
+	}
+	if len(missing) == 0 {
+		return content
+	}
+	return strings.TrimRight(content, "\n") + "\n\n" + strings.Join(missing, " ")
+}
@@ -0,0 +1,258 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/hrygo/divinesense/plugin/offlinesync"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/store"
+)
+
+// registerOfflineSyncRoutes registers the sync endpoints of offline-first clients.
+func (s *APIV1Service) registerOfflineSyncRoutes(group *echo.Group) {
+	if s.OfflineSync == nil {
+		return
+	}
+	group.GET("/sync/pull", s.PullSyncChanges)
+	group.POST("/sync/push", s.PushSyncMutations)
+	group.GET("/sync/conflicts", s.ListSyncConflicts)
+	group.POST("/sync/conflicts/:id/resolve", s.ResolveSyncConflict)
+}
+
+// GET /api/v1/system/sync/pull?cursor=&limit=.
+// Returns the memos and conversations of the current user changed after the
+// cursor (empty for all), tombstones of deleted ones, and open conflicts.
+func (s *APIV1Service) PullSyncChanges(c echo.Context) error {
+	limit := 0
+	if raw := c.QueryParam("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 0 {
+			return restError(c, http.StatusBadRequest, "limit must be a positive number")
+		}
+	}
+	result, err := s.OfflineSync.Pull(c.Request().Context(), restCurrentUser(c).ID, c.QueryParam("cursor"), limit, time.Now())
+	if err != nil {
+		if errors.Is(err, offlinesync.ErrInvalidCursor) {
+			return restError(c, http.StatusBadRequest, err.Error())
+		}
+		slog.Error("failed to pull sync changes", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to pull changes")
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// POST /api/v1/system/sync/push {"mutations": [{"id", "entity", "uid", "base_version", "edited_ts", ...}]}.
+// Applies the offline edits of the current user in order and returns the
+// outcome of each; conflicts are recorded and returned with them.
+func (s *APIV1Service) PushSyncMutations(c echo.Context) error {
+	var req struct {
+		Mutations []*offlinesync.Mutation `json:"mutations"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	results, err := s.OfflineSync.Push(c.Request().Context(), restCurrentUser(c).ID, req.Mutations)
+	if err != nil {
+		if errors.Is(err, offlinesync.ErrRejected) {
+			return restError(c, http.StatusBadRequest, err.Error())
+		}
+		slog.Error("failed to push sync mutations", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to push mutations")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"results": results})
+}
+
+// GET /api/v1/system/sync/conflicts.
+func (s *APIV1Service) ListSyncConflicts(c echo.Context) error {
+	conflicts, err := s.OfflineSync.Conflicts(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		slog.Error("failed to list sync conflicts", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to list conflicts")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"conflicts": conflicts})
+}
+
+// POST /api/v1/system/sync/conflicts/:id/resolve {"resolution": "server|client|merged", "content": "..."}.
+// Returns the state of the entity after the resolution.
+func (s *APIV1Service) ResolveSyncConflict(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid conflict id")
+	}
+	var req struct {
+		Resolution offlinesync.Resolution `json:"resolution"`
+		Content    string                 `json:"content"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	change, err := s.OfflineSync.Resolve(c.Request().Context(), restCurrentUser(c).ID, id, req.Resolution, req.Content)
+	switch {
+	case errors.Is(err, offlinesync.ErrConflictNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, offlinesync.ErrInvalidResolution), errors.Is(err, offlinesync.ErrRejected):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case err != nil:
+		slog.Error("failed to resolve sync conflict", "conflict_id", id, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to resolve conflict")
+	}
+	return c.JSON(http.StatusOK, change)
+}
+
+// offlineSyncApplier applies sync mutations through the memo service and the
+// conversation store. Memo writes run as the user of the request context.
+type offlineSyncApplier struct {
+	s *APIV1Service
+}
+
+func (a *offlineSyncApplier) CreateMemo(ctx context.Context, _ int32, uid string, patch *offlinesync.MemoPatch) error {
+	if patch.Content == nil {
+		return fmt.Errorf("%w: content is required to create a memo", offlinesync.ErrRejected)
+	}
+	// The UID may be taken by a memo of another user, which the sync store
+	// does not see.
+	existing, err := a.s.Store.GetMemo(ctx, &store.FindMemo{UID: &uid})
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("%w: uid %q is taken", offlinesync.ErrRejected, uid)
+	}
+	memo := &v1pb.Memo{Content: *patch.Content, Visibility: v1pb.Visibility_PRIVATE}
+	if patch.Visibility != nil {
+		if memo.Visibility, err = syncVisibility(*patch.Visibility); err != nil {
+			return err
+		}
+	}
+	if _, err := a.s.MemoService.CreateMemo(ctx, &v1pb.CreateMemoRequest{Memo: memo, MemoId: uid}); err != nil {
+		return syncApplyError(err)
+	}
+	// Creation does not take the pinned and archived flags.
+	rest := &offlinesync.MemoPatch{Pinned: patch.Pinned, Archived: patch.Archived}
+	if rest.Pinned == nil && rest.Archived == nil {
+		return nil
+	}
+	return a.UpdateMemo(ctx, 0, uid, rest)
+}
+
+func (a *offlineSyncApplier) UpdateMemo(ctx context.Context, _ int32, uid string, patch *offlinesync.MemoPatch) error {
+	memo := &v1pb.Memo{Name: MemoNamePrefix + uid}
+	mask := &fieldmaskpb.FieldMask{}
+	if patch.Content != nil {
+		memo.Content = *patch.Content
+		mask.Paths = append(mask.Paths, "content")
+	}
+	if patch.Visibility != nil {
+		visibility, err := syncVisibility(*patch.Visibility)
+		if err != nil {
+			return err
+		}
+		memo.Visibility = visibility
+		mask.Paths = append(mask.Paths, "visibility")
+	}
+	if patch.Pinned != nil {
+		memo.Pinned = *patch.Pinned
+		mask.Paths = append(mask.Paths, "pinned")
+	}
+	if patch.Archived != nil {
+		memo.State = v1pb.State_NORMAL
+		if *patch.Archived {
+			memo.State = v1pb.State_ARCHIVED
+		}
+		mask.Paths = append(mask.Paths, "state")
+	}
+	if _, err := a.s.MemoService.UpdateMemo(ctx, &v1pb.UpdateMemoRequest{Memo: memo, UpdateMask: mask}); err != nil {
+		return syncApplyError(err)
+	}
+	return nil
+}
+
+func (a *offlineSyncApplier) DeleteMemo(ctx context.Context, _ int32, uid string) error {
+	if _, err := a.s.MemoService.DeleteMemo(ctx, &v1pb.DeleteMemoRequest{Name: MemoNamePrefix + uid}); err != nil {
+		return syncApplyError(err)
+	}
+	return nil
+}
+
+func (a *offlineSyncApplier) UpdateConversation(ctx context.Context, userID int32, uid string, patch *offlinesync.ConversationPatch) error {
+	conversation, err := a.findConversation(ctx, userID, uid)
+	if err != nil {
+		return err
+	}
+	updatedTs := time.Now().Unix()
+	update := &store.UpdateAIConversation{ID: conversation.ID, Pinned: patch.Pinned, UpdatedTs: &updatedTs}
+	if patch.Title != nil {
+		// Mark as user-edited to prevent auto-title generation from overwriting
+		userSource := store.TitleSourceUser
+		update.Title, update.TitleSource = patch.Title, &userSource
+	}
+	if patch.Archived != nil {
+		rowStatus := store.Normal
+		if *patch.Archived {
+			rowStatus = store.Archived
+		}
+		update.RowStatus = &rowStatus
+	}
+	if _, err := a.s.Store.UpdateAIConversation(ctx, update); err != nil {
+		return fmt.Errorf("failed to update conversation: %w", err)
+	}
+	return nil
+}
+
+func (a *offlineSyncApplier) DeleteConversation(ctx context.Context, userID int32, uid string) error {
+	conversation, err := a.findConversation(ctx, userID, uid)
+	if err != nil {
+		return err
+	}
+	// The AI service also forgets the conversation in the knowledge graph.
+	if a.s.AIService != nil {
+		if _, err := a.s.AIService.DeleteAIConversation(ctx, &v1pb.DeleteAIConversationRequest{Id: conversation.ID}); err != nil {
+			return syncApplyError(err)
+		}
+		return nil
+	}
+	if err := a.s.Store.DeleteAIConversation(ctx, &store.DeleteAIConversation{ID: conversation.ID}); err != nil {
+		return fmt.Errorf("failed to delete conversation: %w", err)
+	}
+	return nil
+}
+
+func (a *offlineSyncApplier) findConversation(ctx context.Context, userID int32, uid string) (*store.AIConversation, error) {
+	conversations, err := a.s.Store.ListAIConversations(ctx, &store.FindAIConversation{UID: &uid, CreatorID: &userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+	if len(conversations) == 0 {
+		return nil, fmt.Errorf("%w: conversation not found", offlinesync.ErrRejected)
+	}
+	return conversations[0], nil
+}
+
+func syncVisibility(value string) (v1pb.Visibility, error) {
+	visibility := v1pb.Visibility(v1pb.Visibility_value[value])
+	if visibility == v1pb.Visibility_VISIBILITY_UNSPECIFIED {
+		return visibility, fmt.Errorf("%w: visibility must be PRIVATE, PROTECTED or PUBLIC", offlinesync.ErrRejected)
+	}
+	return visibility, nil
+}
+
+// syncApplyError turns the errors of invalid writes into rejections of the
+// mutation; other errors fail the push.
+func syncApplyError(err error) error {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.PermissionDenied, codes.NotFound, codes.AlreadyExists, codes.FailedPrecondition:
+		return fmt.Errorf("%w: %s", offlinesync.ErrRejected, status.Convert(err).Message())
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/quiethours"
+)
+
+// quietHoursResponse is the quiet hours of a user and whether they are on now.
+type quietHoursResponse struct {
+	*quiethours.Settings
+	// QuietUntil is the Unix time the current quiet hours end, or 0.
+	QuietUntil int64 `json:"quiet_until"`
+}
+
+// registerQuietHoursRoutes registers the quiet hours endpoints.
+func (s *APIV1Service) registerQuietHoursRoutes(group *echo.Group) {
+	if s.QuietHours == nil {
+		return
+	}
+	group.GET("/quiet-hours", s.GetQuietHours)
+	group.PUT("/quiet-hours", s.UpdateQuietHours)
+}
+
+// GET /api/v1/system/quiet-hours.
+func (s *APIV1Service) GetQuietHours(c echo.Context) error {
+	settings, err := s.QuietHours.GetSettings(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		slog.Error("failed to get quiet hours", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get quiet hours")
+	}
+	return c.JSON(http.StatusOK, newQuietHoursResponse(settings))
+}
+
+// PUT /api/v1/system/quiet-hours {"enabled": true, "start": "22:00", "end": "07:00", "timezone": "Asia/Shanghai"}.
+// Fields missing from the body keep their current value.
+func (s *APIV1Service) UpdateQuietHours(c echo.Context) error {
+	ctx := c.Request().Context()
+	user := restCurrentUser(c)
+	settings, err := s.QuietHours.GetSettings(ctx, user.ID)
+	if err != nil {
+		slog.Error("failed to get quiet hours", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get quiet hours")
+	}
+	if err := c.Bind(settings); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	settings.UserID = user.ID
+	if err := settings.Validate(); err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	saved, err := s.QuietHours.SaveSettings(ctx, settings)
+	if err != nil {
+		slog.Error("failed to save quiet hours", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to save quiet hours")
+	}
+	return c.JSON(http.StatusOK, newQuietHoursResponse(saved))
+}
+
+func newQuietHoursResponse(settings *quiethours.Settings) *quietHoursResponse {
+	resp := &quietHoursResponse{Settings: settings}
+	if until := settings.QuietUntil(time.Now()); !until.IsZero() {
+		resp.QuietUntil = until.Unix()
+	}
+	return resp
+}
@@ -0,0 +1,239 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lithammer/shortuuid/v4"
+
+	"github.com/hrygo/divinesense/plugin/i18n"
+	"github.com/hrygo/divinesense/plugin/readlater"
+	"github.com/hrygo/divinesense/plugin/webhook"
+	"github.com/hrygo/divinesense/server/runner/memopayload"
+	"github.com/hrygo/divinesense/store"
+)
+
+// registerReadLaterRoutes registers the read-later queue endpoints.
+func (s *APIV1Service) registerReadLaterRoutes(group *echo.Group) {
+	if s.readLater == nil {
+		return
+	}
+	group.GET("/read-later", s.ListReadLater)
+	group.POST("/read-later", s.AddReadLater)
+	group.GET("/read-later/settings", s.GetReadLaterSettings)
+	group.PUT("/read-later/settings", s.UpdateReadLaterSettings)
+	group.POST("/read-later/digest/send", s.SendReadLaterDigest)
+	group.POST("/read-later/:uid/snooze", s.SnoozeReadLater)
+	group.POST("/read-later/:uid/dismiss", s.DismissReadLater)
+	group.POST("/read-later/:uid/read", s.MarkReadLaterRead)
+}
+
+// GET /api/v1/system/read-later?status=QUEUED&snoozed=false&limit=50.
+// Lists the current user's queue, highest interest score first; items not
+// scored yet come last, newest first.
+func (s *APIV1Service) ListReadLater(c echo.Context) error {
+	find := &readlater.Find{
+		CreatorID: restCurrentUser(c).ID,
+		Status:    readlater.Status(c.QueryParam("status")),
+	}
+	if find.Status != "" && !find.Status.Valid() {
+		return restError(c, http.StatusBadRequest, "status must be QUEUED, READ or DISMISSED")
+	}
+	find.IncludeSnoozed, _ = strconv.ParseBool(c.QueryParam("snoozed"))
+	if limit := c.QueryParam("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return restError(c, http.StatusBadRequest, "limit must be a positive integer")
+		}
+		find.Limit = n
+	}
+	items, err := s.readLater.List(c.Request().Context(), find)
+	if err != nil {
+		slog.Error("failed to list read later items", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to list read later items")
+	}
+	if items == nil {
+		items = []*readlater.Item{}
+	}
+	return c.JSON(http.StatusOK, map[string]any{"items": items})
+}
+
+// POST /api/v1/system/read-later {"memo_uid": "..."}.
+// Queues a memo of the current user; a read or dismissed memo is queued again.
+func (s *APIV1Service) AddReadLater(c echo.Context) error {
+	var req struct {
+		MemoUID string `json:"memo_uid"`
+	}
+	if err := c.Bind(&req); err != nil || req.MemoUID == "" {
+		return restError(c, http.StatusBadRequest, "memo_uid is required")
+	}
+	item, err := s.readLater.Enqueue(c.Request().Context(), restCurrentUser(c).ID, req.MemoUID)
+	if err != nil {
+		slog.Error("failed to queue memo", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to queue memo")
+	}
+	if item == nil {
+		return restError(c, http.StatusNotFound, "memo not found")
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// POST /api/v1/system/read-later/:uid/snooze {"hours": 24} or {"until": <unix>}.
+// Hides a queued item from the queue and digests until then.
+func (s *APIV1Service) SnoozeReadLater(c echo.Context) error {
+	var req struct {
+		Hours int   `json:"hours"`
+		Until int64 `json:"until"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	var until time.Time
+	switch {
+	case req.Until > 0:
+		until = time.Unix(req.Until, 0)
+	case req.Hours > 0:
+		until = time.Now().Add(time.Duration(req.Hours) * time.Hour)
+	default:
+		return restError(c, http.StatusBadRequest, "hours or until is required")
+	}
+	item, err := s.readLater.Snooze(c.Request().Context(), restCurrentUser(c).ID, c.Param("uid"), until)
+	return s.readLaterItemResponse(c, item, err)
+}
+
+// POST /api/v1/system/read-later/:uid/dismiss.
+func (s *APIV1Service) DismissReadLater(c echo.Context) error {
+	item, err := s.readLater.Dismiss(c.Request().Context(), restCurrentUser(c).ID, c.Param("uid"))
+	return s.readLaterItemResponse(c, item, err)
+}
+
+// POST /api/v1/system/read-later/:uid/read.
+func (s *APIV1Service) MarkReadLaterRead(c echo.Context) error {
+	item, err := s.readLater.MarkRead(c.Request().Context(), restCurrentUser(c).ID, c.Param("uid"))
+	return s.readLaterItemResponse(c, item, err)
+}
+
+// readLaterItemResponse answers a change of a queued item.
+func (s *APIV1Service) readLaterItemResponse(c echo.Context, item *readlater.Item, err error) error {
+	if errors.Is(err, readlater.ErrInvalidSnooze) {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		slog.Error("failed to update read later item", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to update read later item")
+	}
+	if item == nil {
+		return restError(c, http.StatusNotFound, "memo is not in the read later queue")
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// GET /api/v1/system/read-later/settings.
+func (s *APIV1Service) GetReadLaterSettings(c echo.Context) error {
+	settings, err := s.readLater.GetSettings(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		slog.Error("failed to get read later settings", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get read later settings")
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+// PUT /api/v1/system/read-later/settings.
+// Fields missing from the body keep their current value. Changing the
+// interests has the queue scored again.
+func (s *APIV1Service) UpdateReadLaterSettings(c echo.Context) error {
+	ctx := c.Request().Context()
+	user := restCurrentUser(c)
+	settings, err := s.readLater.GetSettings(ctx, user.ID)
+	if err != nil {
+		slog.Error("failed to get read later settings", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get read later settings")
+	}
+	if err := c.Bind(settings); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	settings.UserID = user.ID
+	if err := settings.Validate(); err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	saved, err := s.readLater.SaveSettings(ctx, settings)
+	if err != nil {
+		slog.Error("failed to save read later settings", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to save read later settings")
+	}
+	return c.JSON(http.StatusOK, saved)
+}
+
+// POST /api/v1/system/read-later/digest/send.
+// Delivers a digest now; the schedule is unchanged.
+func (s *APIV1Service) SendReadLaterDigest(c echo.Context) error {
+	ctx := c.Request().Context()
+	settings, err := s.readLater.GetSettings(ctx, restCurrentUser(c).ID)
+	if err != nil {
+		slog.Error("failed to get read later settings", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get read later settings")
+	}
+	sent, err := s.readLater.SendDigest(ctx, settings)
+	if err != nil {
+		slog.Error("failed to deliver read later digest", "user_id", settings.UserID, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to deliver read later digest")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"sent": sent})
+}
+
+// ReadLaterQueue returns the read-later queue, whose runner scores items and
+// sends digests, or nil when the queue is unavailable (not PostgreSQL).
+func (s *APIV1Service) ReadLaterQueue() *readlater.Queue {
+	return s.readLater
+}
+
+// deliverReadLaterDigest saves the digest as a private memo or posts it to
+// the user's webhooks, as the settings say.
+func (s *APIV1Service) deliverReadLaterDigest(ctx context.Context, settings *readlater.Settings, digest *readlater.Digest) error {
+	content := digest.Markdown()
+	if settings.Delivery == readlater.DeliveryWebhook {
+		webhooks, err := s.Store.GetUserWebhooks(ctx, settings.UserID)
+		if err != nil {
+			return fmt.Errorf("get webhooks: %w", err)
+		}
+		if len(webhooks) == 0 {
+			return fmt.Errorf("user %d has no webhook", settings.UserID)
+		}
+		for _, hook := range webhooks {
+			webhook.PostAsync(&webhook.WebhookRequestPayload{
+				URL:          hook.Url,
+				ActivityType: "memos.read_later.digest",
+				Creator:      fmt.Sprintf("%s%d", UserNamePrefix, settings.UserID),
+				Report: &webhook.ReportPayload{
+					Title:    i18n.T(digest.Locale, "readlater.digest.webhook_title"),
+					Markdown: content,
+					Data:     digest,
+				},
+			})
+		}
+		return nil
+	}
+
+	now := time.Now().Unix()
+	create := &store.Memo{
+		UID:        shortuuid.New(),
+		CreatorID:  settings.UserID,
+		Content:    content,
+		Visibility: store.Private,
+		CreatedTs:  now,
+		UpdatedTs:  now,
+	}
+	if err := memopayload.RebuildMemoPayload(create, s.MarkdownService); err != nil {
+		return fmt.Errorf("rebuild memo payload: %w", err)
+	}
+	if _, err := s.Store.CreateMemo(ctx, create); err != nil {
+		return fmt.Errorf("create digest memo: %w", err)
+	}
+	return nil
+}
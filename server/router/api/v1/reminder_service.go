@@ -0,0 +1,403 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/internal/profile"
+	"github.com/hrygo/divinesense/plugin/email"
+	"github.com/hrygo/divinesense/plugin/reminder"
+	"github.com/hrygo/divinesense/plugin/webhook"
+	"github.com/hrygo/divinesense/server/integration/telegram"
+	"github.com/hrygo/divinesense/server/service/schedule"
+	"github.com/hrygo/divinesense/store"
+)
+
+const (
+	defaultReminderDeliveries = 50
+	maxReminderDeliveries     = 200
+)
+
+// newReminders creates the schedule reminders with the channels the instance
+// configured. Must be called once the Telegram gateway and webhook registry
+// are created.
+func newReminders(s *APIV1Service, profile *profile.Profile) *reminder.Reminders {
+	reminderStore := reminder.NewDBStore(s.Store.GetDriver().GetDB())
+	reminders := reminder.New(reminderStore, &reminderSchedules{store: s.Store}, s.QuietHours)
+	if profile.VAPIDPrivateKey != "" {
+		push, err := reminder.NewWebPush(reminder.WebPushConfig{
+			PublicKey:  profile.VAPIDPublicKey,
+			PrivateKey: profile.VAPIDPrivateKey,
+			Subject:    profile.VAPIDSubject,
+		}, reminderStore)
+		if err != nil {
+			slog.Warn("web push reminders disabled: invalid VAPID configuration", "error", err)
+		} else {
+			s.webPush = push
+			reminders.Register(reminder.ChannelWebPush, push)
+		}
+	}
+	if profile.SMTPHost != "" {
+		config := &email.Config{
+			SMTPHost:     profile.SMTPHost,
+			SMTPPort:     profile.SMTPPort,
+			SMTPUsername: profile.SMTPUsername,
+			SMTPPassword: profile.SMTPPassword,
+			FromEmail:    profile.SMTPFrom,
+			FromName:     profile.SMTPFromName,
+			UseSSL:       profile.SMTPPort == 465,
+			UseTLS:       profile.SMTPPort != 465,
+		}
+		if err := config.Validate(); err != nil {
+			slog.Warn("email reminders disabled: invalid SMTP configuration", "error", err)
+		} else {
+			reminders.Register(reminder.ChannelEmail, &reminderEmail{client: email.NewClient(config), store: s.Store})
+		}
+	}
+	if s.Telegram != nil {
+		reminders.Register(reminder.ChannelTelegram, &reminderTelegram{gateway: s.Telegram})
+	}
+	if s.Webhooks != nil {
+		reminders.Register(reminder.ChannelWebhook, &reminderWebhook{registry: s.Webhooks})
+	}
+	return reminders
+}
+
+// registerReminderRoutes registers the reminder settings, push subscriptions,
+// deliveries, critical schedules and escalations of the current user.
+func (s *APIV1Service) registerReminderRoutes(group *echo.Group) {
+	if s.Reminders == nil {
+		return
+	}
+	group.GET("/reminders/settings", s.GetReminderSettings)
+	group.PUT("/reminders/settings", s.UpdateReminderSettings)
+	group.GET("/reminders/push-subscriptions", s.ListPushSubscriptions)
+	group.POST("/reminders/push-subscriptions", s.CreatePushSubscription)
+	group.DELETE("/reminders/push-subscriptions", s.DeletePushSubscription)
+	group.GET("/reminders/deliveries", s.ListReminderDeliveries)
+	group.GET("/reminders/critical", s.ListCriticalSchedules)
+	group.PUT("/reminders/critical/:uid", s.MarkScheduleCritical)
+	group.DELETE("/reminders/critical/:uid", s.UnmarkScheduleCritical)
+	group.GET("/reminders/escalations", s.ListReminderEscalations)
+	group.POST("/reminders/escalations/:id/ack", s.AcknowledgeReminderEscalation)
+}
+
+// reminderSettingsResponse is the reminder settings of a user with the
+// channels of the instance.
+type reminderSettingsResponse struct {
+	*reminder.Settings
+	// AvailableChannels are the channels the instance configured.
+	AvailableChannels []string `json:"available_channels"`
+	// VAPIDPublicKey is the key browsers subscribe to web push with, empty
+	// without web push.
+	VAPIDPublicKey string `json:"vapid_public_key,omitempty"`
+}
+
+// GET /api/v1/system/reminders/settings.
+func (s *APIV1Service) GetReminderSettings(c echo.Context) error {
+	settings, err := s.Reminders.GetSettings(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		return reminderError(c, err, "failed to get reminder settings")
+	}
+	return c.JSON(http.StatusOK, s.newReminderSettingsResponse(settings))
+}
+
+// PUT /api/v1/system/reminders/settings {"enabled": true, "channels":
+// ["web_push", "telegram"], "lead_minutes": [10, 1440],
+// "escalation_channels": ["web_push", "telegram", "email"],
+// "escalation_minutes": 5}. Lead times apply to schedules without reminders
+// of their own; the escalation chain to critical schedules. Fields missing
+// from the body keep their current value.
+func (s *APIV1Service) UpdateReminderSettings(c echo.Context) error {
+	ctx := c.Request().Context()
+	user := restCurrentUser(c)
+	settings, err := s.Reminders.GetSettings(ctx, user.ID)
+	if err != nil {
+		return reminderError(c, err, "failed to get reminder settings")
+	}
+	if err := c.Bind(settings); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	settings.UserID = user.ID
+	saved, err := s.Reminders.SaveSettings(ctx, settings)
+	if err != nil {
+		return reminderError(c, err, "failed to save reminder settings")
+	}
+	return c.JSON(http.StatusOK, s.newReminderSettingsResponse(saved))
+}
+
+// GET /api/v1/system/reminders/push-subscriptions lists the browsers of the
+// current user subscribed to web push reminders.
+func (s *APIV1Service) ListPushSubscriptions(c echo.Context) error {
+	subscriptions, err := s.Reminders.Subscriptions(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		return reminderError(c, err, "failed to list push subscriptions")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"subscriptions": subscriptions})
+}
+
+// POST /api/v1/system/reminders/push-subscriptions {"endpoint": "https://...",
+// "keys": {"p256dh": "...", "auth": "..."}}, the JSON of the PushSubscription
+// of a browser, subscribes it to the web push reminders of the current user.
+func (s *APIV1Service) CreatePushSubscription(c echo.Context) error {
+	if s.webPush == nil {
+		return restError(c, http.StatusNotImplemented, "web push is not configured")
+	}
+	subscription := &reminder.PushSubscription{}
+	if err := c.Bind(subscription); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	subscription.UserID = restCurrentUser(c).ID
+	subscription.UserAgent = c.Request().UserAgent()
+	if err := s.Reminders.Subscribe(c.Request().Context(), subscription); err != nil {
+		return reminderError(c, err, "failed to save push subscription")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DELETE /api/v1/system/reminders/push-subscriptions?endpoint=https://...
+// unsubscribes a browser of the current user.
+func (s *APIV1Service) DeletePushSubscription(c echo.Context) error {
+	endpoint := c.QueryParam("endpoint")
+	if endpoint == "" {
+		return restError(c, http.StatusBadRequest, "endpoint is required")
+	}
+	if err := s.Reminders.Unsubscribe(c.Request().Context(), restCurrentUser(c).ID, endpoint); err != nil {
+		return reminderError(c, err, "failed to delete push subscription")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GET /api/v1/system/reminders/deliveries?schedule_uid=...&status=failed&limit=50
+// lists the latest reminders sent to the current user, one per channel, with
+// their status.
+func (s *APIV1Service) ListReminderDeliveries(c echo.Context) error {
+	user := restCurrentUser(c)
+	find := &reminder.FindDelivery{UserID: &user.ID, Limit: defaultReminderDeliveries}
+	if uid := c.QueryParam("schedule_uid"); uid != "" {
+		find.ScheduleUID = &uid
+	}
+	if status := c.QueryParam("status"); status != "" {
+		statuses := []string{reminder.StatusPending, reminder.StatusSent, reminder.StatusFailed, reminder.StatusSkipped}
+		if !slices.Contains(statuses, status) {
+			return restError(c, http.StatusBadRequest, "invalid status")
+		}
+		find.Status = &status
+	}
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxReminderDeliveries {
+			return restError(c, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxReminderDeliveries))
+		}
+		find.Limit = limit
+	}
+	deliveries, err := s.Reminders.Deliveries(c.Request().Context(), find)
+	if err != nil {
+		return reminderError(c, err, "failed to list reminder deliveries")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"deliveries": deliveries})
+}
+
+// GET /api/v1/system/reminders/critical lists the UIDs of the critical
+// schedules of the current user.
+func (s *APIV1Service) ListCriticalSchedules(c echo.Context) error {
+	uids, err := s.Reminders.Critical(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		return reminderError(c, err, "failed to list critical schedules")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"schedule_uids": uids})
+}
+
+// PUT /api/v1/system/reminders/critical/:uid marks a schedule of the current
+// user critical: its reminders escalate until acknowledged.
+func (s *APIV1Service) MarkScheduleCritical(c echo.Context) error {
+	return s.setScheduleCritical(c, true)
+}
+
+// DELETE /api/v1/system/reminders/critical/:uid makes a schedule of the
+// current user a regular one again.
+func (s *APIV1Service) UnmarkScheduleCritical(c echo.Context) error {
+	return s.setScheduleCritical(c, false)
+}
+
+func (s *APIV1Service) setScheduleCritical(c echo.Context, critical bool) error {
+	uid := c.Param("uid")
+	title, err := s.Reminders.SetCritical(c.Request().Context(), restCurrentUser(c).ID, uid, critical)
+	if err != nil {
+		return reminderError(c, err, "failed to set critical schedule")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"schedule_uid": uid, "title": title, "critical": critical})
+}
+
+// GET /api/v1/system/reminders/escalations?schedule_uid=...&status=active&limit=50
+// lists the latest escalations of critical reminders of the current user.
+func (s *APIV1Service) ListReminderEscalations(c echo.Context) error {
+	user := restCurrentUser(c)
+	find := &reminder.FindEscalation{UserID: &user.ID, Limit: defaultReminderDeliveries}
+	if uid := c.QueryParam("schedule_uid"); uid != "" {
+		find.ScheduleUID = &uid
+	}
+	if status := c.QueryParam("status"); status != "" {
+		statuses := []string{reminder.EscalationActive, reminder.EscalationAcknowledged, reminder.EscalationExhausted}
+		if !slices.Contains(statuses, status) {
+			return restError(c, http.StatusBadRequest, "invalid status")
+		}
+		find.Status = &status
+	}
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxReminderDeliveries {
+			return restError(c, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxReminderDeliveries))
+		}
+		find.Limit = limit
+	}
+	escalations, err := s.Reminders.Escalations(c.Request().Context(), find)
+	if err != nil {
+		return reminderError(c, err, "failed to list reminder escalations")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"escalations": escalations})
+}
+
+// POST /api/v1/system/reminders/escalations/:id/ack acknowledges an
+// escalation of the current user, stopping it.
+func (s *APIV1Service) AcknowledgeReminderEscalation(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid escalation id")
+	}
+	escalation, err := s.Reminders.Acknowledge(c.Request().Context(), restCurrentUser(c).ID, id)
+	if err != nil {
+		return reminderError(c, err, "failed to acknowledge reminder escalation")
+	}
+	return c.JSON(http.StatusOK, escalation)
+}
+
+func (s *APIV1Service) newReminderSettingsResponse(settings *reminder.Settings) *reminderSettingsResponse {
+	resp := &reminderSettingsResponse{Settings: settings, AvailableChannels: s.Reminders.Channels()}
+	if s.webPush != nil {
+		resp.VAPIDPublicKey = s.webPush.PublicKey()
+	}
+	return resp
+}
+
+// reminderError maps the errors of the reminders to responses.
+func reminderError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, reminder.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, reminder.ErrNotFound), errors.Is(err, reminder.ErrEscalationNotFound),
+		errors.Is(err, reminder.ErrScheduleNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
+
+// reminderSchedules lists the occurrences of the schedules of users for the
+// reminders.
+type reminderSchedules struct {
+	store *store.Store
+}
+
+// Occurrences implements reminder.Schedules.
+func (r *reminderSchedules) Occurrences(ctx context.Context, userID int32, from, to time.Time) ([]*reminder.Occurrence, error) {
+	instances, err := schedule.NewService(r.store).FindSchedules(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	occurrences := make([]*reminder.Occurrence, 0, len(instances))
+	for _, instance := range instances {
+		// FindSchedules also returns the schedules started before from
+		if instance.StartTs < from.Unix() || instance.StartTs > to.Unix() {
+			continue
+		}
+		occurrences = append(occurrences, &reminder.Occurrence{
+			ScheduleID: instance.ID,
+			UID:        instance.UID,
+			Title:      instance.Title,
+			Location:   instance.Location,
+			Timezone:   instance.Timezone,
+			StartTs:    instance.StartTs,
+			EndTs:      instance.EndTs,
+			AllDay:     instance.AllDay,
+			Reminders:  instance.Reminders,
+		})
+	}
+	return occurrences, nil
+}
+
+// Title implements reminder.Schedules.
+func (r *reminderSchedules) Title(ctx context.Context, userID int32, uid string) (string, error) {
+	normal := store.Normal
+	found, err := r.store.GetSchedule(ctx, &store.FindSchedule{UID: &uid, CreatorID: &userID, RowStatus: &normal})
+	if err != nil {
+		return "", err
+	}
+	if found == nil {
+		return "", reminder.ErrScheduleNotFound
+	}
+	return found.Title, nil
+}
+
+// reminderEmail emails the reminders to the address of users.
+type reminderEmail struct {
+	client *email.Client
+	store  *store.Store
+}
+
+// Send implements reminder.Channel.
+func (r *reminderEmail) Send(ctx context.Context, userID int32, notification *reminder.Notification) error {
+	user, err := r.store.GetUser(ctx, &store.FindUser{ID: &userID})
+	if err != nil {
+		return err
+	}
+	if user == nil || user.Email == "" {
+		return fmt.Errorf("%w: no email address in the profile", reminder.ErrUnavailable)
+	}
+	return r.client.Send(&email.Message{
+		To:      []string{user.Email},
+		Subject: notification.Subject(),
+		Body:    notification.Body(),
+	})
+}
+
+// reminderTelegram sends the reminders to the linked Telegram account of
+// users.
+type reminderTelegram struct {
+	gateway *telegram.Gateway
+}
+
+// Send implements reminder.Channel.
+func (r *reminderTelegram) Send(ctx context.Context, userID int32, notification *reminder.Notification) error {
+	err := r.gateway.Notify(ctx, userID, notification.Text())
+	if errors.Is(err, telegram.ErrNotFound) {
+		return fmt.Errorf("%w: no linked and enabled Telegram account", reminder.ErrUnavailable)
+	}
+	return err
+}
+
+// reminderWebhook publishes the reminders as schedule.reminder events.
+type reminderWebhook struct {
+	registry *webhook.Registry
+}
+
+// Send implements reminder.Channel.
+func (r *reminderWebhook) Send(ctx context.Context, userID int32, notification *reminder.Notification) error {
+	users, instance, err := r.registry.Subscribers(ctx, webhook.EventScheduleReminder)
+	if err != nil {
+		return err
+	}
+	if !instance && !slices.Contains(users, userID) {
+		return fmt.Errorf("%w: no webhook subscribes to %s", reminder.ErrUnavailable, webhook.EventScheduleReminder)
+	}
+	eventID := fmt.Sprintf("%s:%s:%d:%d", webhook.EventScheduleReminder, notification.UID, notification.StartTs, notification.LeadMinutes)
+	return r.registry.Publish(ctx, webhook.EventScheduleReminder, userID, eventID, notification)
+}
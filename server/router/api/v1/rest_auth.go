@@ -0,0 +1,94 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/status"
+
+	"github.com/hrygo/divinesense/server/auth"
+	"github.com/hrygo/divinesense/store"
+)
+
+// restUserContextKey is the echo context key holding the authenticated *store.User.
+const restUserContextKey = "rest_user"
+
+// restAuthMiddleware authenticates direct REST endpoints (non gRPC-Gateway).
+// It uses the same Authenticator as the gateway and Connect interceptors and
+// rejects anonymous requests with 401.
+func (s *APIV1Service) restAuthMiddleware() echo.MiddlewareFunc {
+	authenticator := auth.NewAuthenticator(s.Store, s.Secret)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			result := authenticator.Authenticate(ctx, c.Request().Header.Get("Authorization"))
+			if result == nil {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "authentication required"})
+			}
+
+			ctx = withAuthResult(ctx, result)
+			user, err := fetchCurrentUser(ctx, s.Store)
+			if err != nil || user == nil {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "authentication required"})
+			}
+
+			c.SetRequest(c.Request().WithContext(ctx))
+			c.Set(restUserContextKey, user)
+			return next(c)
+		}
+	}
+}
+
+// restAdminMiddleware must run after restAuthMiddleware and rejects non-superusers.
+func restAdminMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := restCurrentUser(c)
+		if user == nil || !isSuperUser(user) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "permission denied"})
+		}
+		return next(c)
+	}
+}
+
+// restPATMiddleware must run before restAuthMiddleware and rejects the
+// requests not authenticated by a personal access token.
+func restPATMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := auth.ExtractBearerToken(c.Request().Header.Get(echo.HeaderAuthorization))
+		if !strings.HasPrefix(token, auth.PersonalAccessTokenPrefix) {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "a personal access token is required"})
+		}
+		return next(c)
+	}
+}
+
+// restCurrentUser returns the user set by restAuthMiddleware.
+func restCurrentUser(c echo.Context) *store.User {
+	user, _ := c.Get(restUserContextKey).(*store.User)
+	return user
+}
+
+// withAuthResult sets the authenticated identity on ctx the same way the gateway middleware does.
+func withAuthResult(ctx context.Context, result *auth.AuthResult) context.Context {
+	if result.Claims != nil {
+		ctx = auth.SetUserClaimsInContext(ctx, result.Claims)
+		return context.WithValue(ctx, auth.UserIDContextKey, result.Claims.UserID)
+	}
+	if result.User != nil {
+		return auth.SetUserInContext(ctx, result.User, result.AccessToken)
+	}
+	return ctx
+}
+
+// restError writes a JSON error body with the given status.
+func restError(c echo.Context, code int, msg string) error {
+	return c.JSON(code, map[string]string{"error": msg})
+}
+
+// restStatusError reports a gRPC status error of a service method.
+func restStatusError(c echo.Context, err error) error {
+	return restError(c, runtime.HTTPStatusFromCode(status.Code(err)), status.Convert(err).Message())
+}
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/ai/core/retrieval"
+	"github.com/hrygo/divinesense/plugin/retrievalscope"
+)
+
+// registerRetrievalScopeRoutes registers the retrieval scope API of the
+// conversations.
+func (s *APIV1Service) registerRetrievalScopeRoutes(group *echo.Group) {
+	if s.RetrievalScopes == nil {
+		return
+	}
+	group.GET("/ai/conversations/:id/retrieval-scope", s.GetRetrievalScope)
+	group.PUT("/ai/conversations/:id/retrieval-scope", s.SetRetrievalScope)
+}
+
+// GET /api/v1/system/ai/conversations/:id/retrieval-scope returns the
+// retrieval scope of a conversation of the current user; {} when retrieval
+// is unrestricted.
+func (s *APIV1Service) GetRetrievalScope(c echo.Context) error {
+	conversationID, _, ok := s.conversationLockRequest(c, false)
+	if !ok {
+		return nil
+	}
+	scope, err := s.RetrievalScopes.Get(c.Request().Context(), conversationID)
+	if err != nil {
+		slog.Error("failed to get retrieval scope", "conversation_id", conversationID, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get retrieval scope")
+	}
+	return c.JSON(http.StatusOK, retrievalScopeResponse(scope))
+}
+
+// PUT /api/v1/system/ai/conversations/:id/retrieval-scope {"tags": ["work"]}
+// or {"disabled": true}.
+// Restricts the memos the conversation retrieves to the tags, subtags
+// included, or turns memo retrieval off; {} lifts the restriction. Returns
+// the saved scope.
+func (s *APIV1Service) SetRetrievalScope(c echo.Context) error {
+	conversationID, _, ok := s.conversationLockRequest(c, false)
+	if !ok {
+		return nil
+	}
+	var req retrieval.Scope
+	if err := c.Bind(&req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid retrieval scope")
+	}
+	scope, err := s.RetrievalScopes.Set(c.Request().Context(), conversationID, &req)
+	if errors.Is(err, retrievalscope.ErrInvalid) {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		slog.Error("failed to save retrieval scope", "conversation_id", conversationID, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to save retrieval scope")
+	}
+	return c.JSON(http.StatusOK, retrievalScopeResponse(scope))
+}
+
+// retrievalScopeResponse returns an empty scope for unrestricted retrieval.
+func retrievalScopeResponse(scope *retrieval.Scope) *retrieval.Scope {
+	if scope == nil {
+		return &retrieval.Scope{}
+	}
+	return scope
+}
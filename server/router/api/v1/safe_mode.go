@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+	"github.com/labstack/echo/v4"
+)
+
+// errSafeMode is returned by the AI endpoints of a server started with
+// --safe-mode, instead of a bare 404.
+var errSafeMode = errors.New("AI features are disabled: the server was started with --safe-mode")
+
+// connectSafeModeHandler answers every AIService procedure with an
+// unavailable error.
+func connectSafeModeHandler(opts ...connect.HandlerOption) http.Handler {
+	errorWriter := connect.NewErrorWriter(opts...)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if errorWriter.IsSupported(r) {
+			_ = errorWriter.Write(w, r, connect.NewError(connect.CodeUnavailable, errSafeMode))
+			return
+		}
+		http.Error(w, errSafeMode.Error(), http.StatusServiceUnavailable)
+	})
+}
+
+// restSafeModeError answers the AI endpoints of the REST gateway.
+func restSafeModeError(c echo.Context) error {
+	return restError(c, http.StatusServiceUnavailable, errSafeMode.Error())
+}
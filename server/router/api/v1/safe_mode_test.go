@@ -0,0 +1,29 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/internal/profile"
+	"github.com/hrygo/divinesense/proto/gen/api/v1/apiv1connect"
+)
+
+func TestSafeModeAIServiceUnavailable(t *testing.T) {
+	mux := http.NewServeMux()
+	s := NewConnectServiceHandler(&APIV1Service{Profile: &profile.Profile{SafeMode: true}})
+	s.RegisterConnectHandlers(mux)
+
+	req := httptest.NewRequest(http.MethodPost, apiv1connect.AIServiceChatProcedure, strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	// Clients get a clear error rather than a 404 of an unknown service
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.Contains(t, rec.Body.String(), `"code":"unavailable"`)
+	require.Contains(t, rec.Body.String(), "--safe-mode")
+}
@@ -0,0 +1,168 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/scripting"
+)
+
+// ScriptHookRequest is the body for creating or updating a script hook.
+type ScriptHookRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Trigger     string  `json:"trigger"`
+	Source      *string `json:"source"`
+	Priority    *int32  `json:"priority"`
+	Enabled     *bool   `json:"enabled"`
+}
+
+// ScriptHookTestRequest is the body for dry-running a script against sample input.
+type ScriptHookTestRequest struct {
+	Source string         `json:"source"`
+	Input  map[string]any `json:"input"`
+}
+
+// ScriptHookTestResponse is the result of a dry run.
+type ScriptHookTestResponse struct {
+	Result *scripting.Result `json:"result"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// registerScriptHookRoutes registers the admin editor API for script hooks.
+func (s *APIV1Service) registerScriptHookRoutes(group *echo.Group) {
+	if s.scriptHookStore == nil {
+		return
+	}
+	hooks := group.Group("/script-hooks", restAdminMiddleware)
+	hooks.GET("", s.ListScriptHooks)
+	hooks.POST("", s.CreateScriptHook)
+	hooks.PATCH("/:id", s.UpdateScriptHook)
+	hooks.DELETE("/:id", s.DeleteScriptHook)
+	hooks.POST("/test", s.TestScriptHook)
+}
+
+// GET /api/v1/system/script-hooks.
+func (s *APIV1Service) ListScriptHooks(c echo.Context) error {
+	find := &scripting.FindHook{}
+	if trigger := c.QueryParam("trigger"); trigger != "" {
+		t := scripting.Trigger(trigger)
+		find.Trigger = &t
+	}
+	hooks, err := s.scriptHookStore.ListHooks(c.Request().Context(), find)
+	if err != nil {
+		slog.Error("failed to list script hooks", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to list script hooks")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"hooks": hooks})
+}
+
+// POST /api/v1/system/script-hooks.
+func (s *APIV1Service) CreateScriptHook(c echo.Context) error {
+	req := &ScriptHookRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	trigger := scripting.Trigger(req.Trigger)
+	if !trigger.IsValid() {
+		return restError(c, http.StatusBadRequest, "invalid trigger")
+	}
+	if req.Name == nil || strings.TrimSpace(*req.Name) == "" || req.Source == nil {
+		return restError(c, http.StatusBadRequest, "name and source are required")
+	}
+	if err := s.ScriptHooks.Engine().Validate(*req.Source); err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+
+	hook := &scripting.Hook{
+		Name:      strings.TrimSpace(*req.Name),
+		Trigger:   trigger,
+		Source:    *req.Source,
+		Enabled:   true,
+		CreatorID: restCurrentUser(c).ID,
+	}
+	if req.Description != nil {
+		hook.Description = *req.Description
+	}
+	if req.Priority != nil {
+		hook.Priority = *req.Priority
+	}
+	if req.Enabled != nil {
+		hook.Enabled = *req.Enabled
+	}
+
+	created, err := s.scriptHookStore.CreateHook(c.Request().Context(), hook)
+	if err != nil {
+		slog.Error("failed to create script hook", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to create script hook")
+	}
+	s.ScriptHooks.Invalidate()
+	return c.JSON(http.StatusOK, created)
+}
+
+// PATCH /api/v1/system/script-hooks/:id.
+func (s *APIV1Service) UpdateScriptHook(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid hook id")
+	}
+	req := &ScriptHookRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.Source != nil {
+		if err := s.ScriptHooks.Engine().Validate(*req.Source); err != nil {
+			return restError(c, http.StatusBadRequest, err.Error())
+		}
+	}
+
+	updated, err := s.scriptHookStore.UpdateHook(c.Request().Context(), &scripting.UpdateHook{
+		ID:          int32(id),
+		Name:        req.Name,
+		Description: req.Description,
+		Source:      req.Source,
+		Priority:    req.Priority,
+		Enabled:     req.Enabled,
+	})
+	if err != nil {
+		slog.Error("failed to update script hook", "id", id, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to update script hook")
+	}
+	s.ScriptHooks.Invalidate()
+	return c.JSON(http.StatusOK, updated)
+}
+
+// DELETE /api/v1/system/script-hooks/:id.
+func (s *APIV1Service) DeleteScriptHook(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid hook id")
+	}
+	if err := s.scriptHookStore.DeleteHook(c.Request().Context(), int32(id)); err != nil {
+		slog.Error("failed to delete script hook", "id", id, "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to delete script hook")
+	}
+	s.ScriptHooks.Invalidate()
+	return c.NoContent(http.StatusNoContent)
+}
+
+// POST /api/v1/system/script-hooks/test.
+// Runs a script against sample input without saving it, for the admin editor.
+func (s *APIV1Service) TestScriptHook(c echo.Context) error {
+	req := &ScriptHookTestRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.Input == nil {
+		req.Input = map[string]any{}
+	}
+	result, err := s.ScriptHooks.Engine().Run(c.Request().Context(), req.Source, req.Input)
+	if err != nil {
+		return c.JSON(http.StatusOK, ScriptHookTestResponse{Error: err.Error()})
+	}
+	return c.JSON(http.StatusOK, ScriptHookTestResponse{Result: result})
+}
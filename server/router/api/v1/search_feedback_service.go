@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/searchfeedback"
+)
+
+// registerSearchFeedbackRoutes registers the relevance feedback API of the
+// hybrid memo search.
+func (s *APIV1Service) registerSearchFeedbackRoutes(group *echo.Group) {
+	if s.SearchFeedback == nil {
+		return
+	}
+	group.POST("/search/feedback", s.MarkSearchResult)
+	group.GET("/search/feedback/report", s.GetSearchFeedbackReport)
+}
+
+// POST /api/v1/system/search/feedback {"query": "...", "memo_uid": "...", "relevant": true}.
+// Marks a result of a search of the current user as relevant or not, and
+// tunes the user's hybrid search weighting again. Returns the evaluation
+// report.
+func (s *APIV1Service) MarkSearchResult(c echo.Context) error {
+	var req struct {
+		Query    string `json:"query"`
+		MemoUID  string `json:"memo_uid"`
+		Relevant *bool  `json:"relevant"`
+	}
+	if err := c.Bind(&req); err != nil || req.Relevant == nil {
+		return restError(c, http.StatusBadRequest, "query, memo_uid and relevant are required")
+	}
+	report, err := s.SearchFeedback.Mark(c.Request().Context(), &searchfeedback.Feedback{
+		UserID:   restCurrentUser(c).ID,
+		Query:    req.Query,
+		MemoUID:  req.MemoUID,
+		Relevant: *req.Relevant,
+	})
+	if errors.Is(err, searchfeedback.ErrInvalid) {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		slog.Error("failed to save search feedback", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to save search feedback")
+	}
+	if report == nil {
+		return restError(c, http.StatusNotFound, "no search of this query found the memo")
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// GET /api/v1/system/search/feedback/report.
+// Compares the NDCG of the current user's judged searches as they were
+// weighted with the tuned weight, without changing the weighting.
+func (s *APIV1Service) GetSearchFeedbackReport(c echo.Context) error {
+	report, err := s.SearchFeedback.Report(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		slog.Error("failed to evaluate search feedback", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to evaluate search feedback")
+	}
+	return c.JSON(http.StatusOK, report)
+}
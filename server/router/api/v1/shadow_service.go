@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	pluginai "github.com/hrygo/divinesense/ai"
+	"github.com/hrygo/divinesense/plugin/shadow"
+	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
+)
+
+// registerShadows registers the shadow parrots of an initialized factory.
+func (s *AIService) registerShadows(factory *aichat.AgentFactory) {
+	if s.Shadows == nil {
+		return
+	}
+	for _, config := range factory.ShadowConfigs() {
+		s.Shadows.Register(context.Background(), config.Name, config.Shadow.Of, config.Shadow.Model, config.Shadow.SampleRate)
+		slog.Info("shadow registered", "shadow", config.Name, "production", config.Shadow.Of, "model", config.Shadow.Model)
+	}
+}
+
+// shadowModelLLM returns the LLM service of a model tried by shadows: the main
+// LLM settings with another model, created once per model.
+func (s *AIService) shadowModelLLM(model string) (pluginai.LLMService, error) {
+	if s.LLMConfig == nil {
+		return nil, errors.New("LLM settings not available")
+	}
+	if llm, ok := s.modelLLMs.Load(model); ok {
+		return llm.(pluginai.LLMService), nil
+	}
+	cfg := *s.LLMConfig
+	cfg.Model = model
+	llm, err := pluginai.NewLLMService(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := s.modelLLMs.LoadOrStore(model, llm)
+	return actual.(pluginai.LLMService), nil
+}
+
+// registerShadowRoutes registers the admin API of the shadow runs.
+func (s *APIV1Service) registerShadowRoutes(group *echo.Group) {
+	if s.Shadows == nil {
+		return
+	}
+	shadows := group.Group("/shadows", restAdminMiddleware)
+	shadows.GET("", s.ListShadows)
+	shadows.PUT("/:name", s.UpdateShadow)
+	shadows.GET("/:name/runs", s.ListShadowRuns)
+}
+
+// GET /api/v1/system/shadows lists the shadows with their sample rates.
+func (s *APIV1Service) ListShadows(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]any{"shadows": s.Shadows.List()})
+}
+
+// PUT /api/v1/system/shadows/:name {"sample_rate": 10}; 0 pauses the shadow.
+func (s *APIV1Service) UpdateShadow(c echo.Context) error {
+	var body struct {
+		SampleRate *int `json:"sample_rate"`
+	}
+	if err := c.Bind(&body); err != nil || body.SampleRate == nil {
+		return restError(c, http.StatusBadRequest, "sample_rate is required")
+	}
+	variant, err := s.Shadows.SetSampleRate(c.Request().Context(), c.Param("name"), *body.SampleRate)
+	if err != nil {
+		return shadowError(c, err, "failed to update shadow")
+	}
+	return c.JSON(http.StatusOK, variant)
+}
+
+// GET /api/v1/system/shadows/:name/runs?since=1760000000&limit=100 returns
+// the paired production and shadow results for offline comparison.
+func (s *APIV1Service) ListShadowRuns(c echo.Context) error {
+	limit := 100
+	if raw := c.QueryParam("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > 1000 {
+			return restError(c, http.StatusBadRequest, "limit must be between 1 and 1000")
+		}
+		limit = parsed
+	}
+	var since int64
+	if raw := c.QueryParam("since"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || parsed < 0 {
+			return restError(c, http.StatusBadRequest, "since must be a Unix time")
+		}
+		since = parsed
+	}
+	runs, err := s.Shadows.Runs(c.Request().Context(), c.Param("name"), since, limit)
+	if err != nil {
+		return shadowError(c, err, "failed to list shadow runs")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"runs": runs})
+}
+
+// shadowError maps the errors of the shadows to responses.
+func shadowError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, shadow.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, shadow.ErrNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
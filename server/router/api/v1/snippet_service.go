@@ -0,0 +1,88 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/snippet"
+	"github.com/hrygo/divinesense/store"
+)
+
+// indexMemoSnippets updates the snippet library after a memo is saved.
+func (s *MemoService) indexMemoSnippets(ctx context.Context, memo *store.Memo) {
+	if s.Snippets == nil {
+		return
+	}
+	if err := s.Snippets.IndexMemo(ctx, memo.ID, memo.CreatorID, memo.Content, memo.Payload.GetTags()); err != nil {
+		slog.Warn("failed to index memo snippets", "memo_id", memo.ID, "error", err)
+	}
+}
+
+// registerSnippetRoutes registers the API for searching snippet memos.
+func (s *APIV1Service) registerSnippetRoutes(group *echo.Group) {
+	if s.Snippets == nil {
+		return
+	}
+	snippets := group.Group("/snippets")
+	snippets.GET("", s.SearchSnippets)
+	snippets.POST("/reindex", s.ReindexSnippets)
+}
+
+// GET /api/v1/system/snippets?symbol=&pattern=&language=&limit=.
+// Returns the current user's snippets defining a symbol or whose code
+// matches a regular expression, most recently updated first.
+func (s *APIV1Service) SearchSnippets(c echo.Context) error {
+	query := &snippet.Query{
+		CreatorID: restCurrentUser(c).ID,
+		Symbol:    c.QueryParam("symbol"),
+		Pattern:   c.QueryParam("pattern"),
+		Language:  c.QueryParam("language"),
+	}
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return restError(c, http.StatusBadRequest, "invalid limit")
+		}
+		query.Limit = limit
+	}
+
+	snippets, err := s.Snippets.Search(c.Request().Context(), query)
+	if errors.Is(err, snippet.ErrInvalidPattern) {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		slog.Error("failed to search snippets", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to search snippets")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"snippets": snippets})
+}
+
+// POST /api/v1/system/snippets/reindex.
+// Indexes the current user's memos tagged #snippet, e.g. memos written
+// before the snippet library existed.
+func (s *APIV1Service) ReindexSnippets(c echo.Context) error {
+	ctx := c.Request().Context()
+	user := restCurrentUser(c)
+	memos, err := s.Store.ListMemos(ctx, &store.FindMemo{CreatorID: &user.ID})
+	if err != nil {
+		slog.Error("failed to list memos", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to list memos")
+	}
+	indexed := 0
+	for _, memo := range memos {
+		if ok, _ := snippet.SnippetTag(memo.Payload.GetTags()); !ok {
+			continue
+		}
+		if err := s.Snippets.IndexMemo(ctx, memo.ID, memo.CreatorID, memo.Content, memo.Payload.GetTags()); err != nil {
+			slog.Error("failed to index memo snippets", "memo_id", memo.ID, "error", err)
+			return restError(c, http.StatusInternalServerError, "failed to index snippets")
+		}
+		indexed++
+	}
+	return c.JSON(http.StatusOK, map[string]any{"memos": indexed})
+}
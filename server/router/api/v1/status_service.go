@@ -0,0 +1,231 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/i18n"
+	"github.com/hrygo/divinesense/plugin/statuspage"
+	"github.com/hrygo/divinesense/server/middleware"
+	"github.com/hrygo/divinesense/server/runner/jobs"
+)
+
+// defaultStatusPageName names the instance on the status page when admins
+// did not set a custom title.
+const defaultStatusPageName = "DivineSense"
+
+// newStatusPage creates the status page and registers the checks of the
+// components of the instance. Must be called once the features are created.
+func (s *APIV1Service) newStatusPage() *statuspage.Page {
+	if s.Profile.Driver != "postgres" {
+		return nil
+	}
+	page := statuspage.New(statuspage.NewDBStore(s.Store.GetDriver().GetDB()), statuspage.Config{Version: s.Profile.Version})
+	page.Register("database", func(ctx context.Context) error {
+		return s.Store.GetDriver().GetDB().PingContext(ctx)
+	})
+	if s.Profile.IsAIEnabled() || s.Profile.SafeMode {
+		page.Register("ai", s.checkAI)
+	}
+	page.Register("jobs", s.checkJobs)
+	return page
+}
+
+// checkAI reports the AI assistant degraded in safe mode and under load.
+func (s *APIV1Service) checkAI(_ context.Context) error {
+	switch {
+	case s.Profile.SafeMode:
+		return fmt.Errorf("%w: safe mode", statuspage.ErrDegraded)
+	case s.AIService == nil:
+		return fmt.Errorf("%w: AI service unavailable", statuspage.ErrDegraded)
+	}
+	if level := s.LoadShedder().Level(); level != middleware.LoadNormal {
+		return fmt.Errorf("%w: %s load", statuspage.ErrDegraded, level)
+	}
+	return nil
+}
+
+// checkJobs reports the background jobs degraded when the last run of one
+// of them failed.
+func (s *APIV1Service) checkJobs(_ context.Context) error {
+	if s.Jobs == nil {
+		return nil
+	}
+	var failed []string
+	for _, job := range s.Jobs.List() {
+		if job.LastRun != nil && job.LastRun.Outcome == jobs.OutcomeFailed {
+			failed = append(failed, job.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: failed jobs: %s", statuspage.ErrDegraded, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// registerStatusRoutes registers the public status page, unless disabled,
+// and the admin API of its incidents.
+func (s *APIV1Service) registerStatusRoutes(echoServer *echo.Echo, group *echo.Group) {
+	if s.StatusPage == nil {
+		return
+	}
+	if s.Profile.StatusPage {
+		echoServer.GET("/api/v1/status", s.GetStatusPage)
+	}
+	status := group.Group("/status", restAdminMiddleware)
+	status.GET("", s.GetStatus)
+	status.GET("/incidents", s.ListStatusIncidents)
+	status.POST("/incidents", s.CreateStatusIncident)
+	status.PUT("/incidents/:id", s.UpdateStatusIncident)
+	status.DELETE("/incidents/:id", s.DeleteStatusIncident)
+}
+
+// GET /api/v1/status[?format=html|json][&lang=en].
+// Returns the public status of the instance, as an HTML page for browsers
+// and as JSON otherwise; it needs no authentication.
+func (s *APIV1Service) GetStatusPage(c echo.Context) error {
+	ctx := c.Request().Context()
+	status, err := s.StatusPage.Status(ctx)
+	if err != nil {
+		return statusError(c, err, "failed to get status")
+	}
+	status = status.Public()
+	c.Response().Header().Set("Cache-Control", "public, max-age=30")
+	if !wantsStatusHTML(c) {
+		return c.JSON(http.StatusOK, status)
+	}
+	page, err := statuspage.RenderHTML(status, s.statusPageName(ctx), statusLocale(c), time.Local)
+	if err != nil {
+		return statusError(c, err, "failed to render status page")
+	}
+	return c.HTMLBlob(http.StatusOK, page)
+}
+
+// wantsStatusHTML reports whether a status request asks for the HTML page,
+// by its format parameter or its Accept header.
+func wantsStatusHTML(c echo.Context) bool {
+	switch c.QueryParam("format") {
+	case "html":
+		return true
+	case "json":
+		return false
+	}
+	return strings.Contains(c.Request().Header.Get(echo.HeaderAccept), echo.MIMETextHTML)
+}
+
+// statusLocale returns the locale of the status page, from its lang
+// parameter or the preferred language of the browser.
+func statusLocale(c echo.Context) i18n.Locale {
+	if lang := c.QueryParam("lang"); lang != "" {
+		return i18n.Match(lang)
+	}
+	tag, _, _ := strings.Cut(c.Request().Header.Get("Accept-Language"), ",")
+	tag, _, _ = strings.Cut(tag, ";")
+	return i18n.Match(strings.TrimSpace(tag))
+}
+
+// statusPageName returns the custom title of the instance, or its default
+// name.
+func (s *APIV1Service) statusPageName(ctx context.Context) string {
+	setting, err := s.Store.GetInstanceGeneralSetting(ctx)
+	if err != nil {
+		slog.Warn("failed to get instance general setting", "error", err)
+		return defaultStatusPageName
+	}
+	if title := strings.TrimSpace(setting.GetCustomProfile().GetTitle()); title != "" {
+		return title
+	}
+	return defaultStatusPageName
+}
+
+// GET /api/v1/system/status returns the status of the instance with the
+// errors of its checks (admin only).
+func (s *APIV1Service) GetStatus(c echo.Context) error {
+	status, err := s.StatusPage.Status(c.Request().Context())
+	if err != nil {
+		return statusError(c, err, "failed to get status")
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+// GET /api/v1/system/status/incidents[?limit=50] lists the latest incidents,
+// the active ones first (admin only).
+func (s *APIV1Service) ListStatusIncidents(c echo.Context) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	incidents, err := s.StatusPage.Incidents(c.Request().Context(), limit)
+	if err != nil {
+		return statusError(c, err, "failed to list incidents")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"incidents": incidents})
+}
+
+// POST /api/v1/system/status/incidents posts an incident (admin only).
+func (s *APIV1Service) CreateStatusIncident(c echo.Context) error {
+	incident := &statuspage.Incident{}
+	if err := c.Bind(incident); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	created, err := s.StatusPage.CreateIncident(c.Request().Context(), incident)
+	if err != nil {
+		return statusError(c, err, "failed to create incident")
+	}
+	return c.JSON(http.StatusOK, created)
+}
+
+// PUT /api/v1/system/status/incidents/:id updates an incident, e.g. to
+// post progress or resolve it; omitted fields are kept (admin only).
+func (s *APIV1Service) UpdateStatusIncident(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid incident id")
+	}
+	ctx := c.Request().Context()
+	incident, err := s.StatusPage.Incident(ctx, int32(id))
+	if err != nil {
+		return statusError(c, err, "failed to get incident")
+	}
+	if err := c.Bind(incident); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	incident.ID = int32(id)
+	updated, err := s.StatusPage.UpdateIncident(ctx, incident)
+	if err != nil {
+		return statusError(c, err, "failed to update incident")
+	}
+	return c.JSON(http.StatusOK, updated)
+}
+
+// DELETE /api/v1/system/status/incidents/:id deletes an incident (admin
+// only).
+func (s *APIV1Service) DeleteStatusIncident(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid incident id")
+	}
+	if err := s.StatusPage.DeleteIncident(c.Request().Context(), int32(id)); err != nil {
+		return statusError(c, err, "failed to delete incident")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func statusError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, statuspage.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, statuspage.ErrNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
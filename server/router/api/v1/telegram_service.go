@@ -0,0 +1,187 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lithammer/shortuuid/v4"
+	"google.golang.org/grpc/status"
+
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/server/auth"
+	"github.com/hrygo/divinesense/server/integration/telegram"
+	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
+	"github.com/hrygo/divinesense/store"
+)
+
+// telegramConversationTitle is the title of the conversations the Telegram
+// gateway starts, until they are titled automatically.
+const telegramConversationTitle = "Telegram"
+
+// TelegramStatus is the Telegram gateway state of the current user.
+type TelegramStatus struct {
+	BotUsername string         `json:"bot_username"`
+	Link        *telegram.Link `json:"link"`
+}
+
+// TelegramSettingsRequest is the body for enabling or pausing the gateway.
+type TelegramSettingsRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// registerTelegramRoutes registers the API of the current user's Telegram
+// link.
+func (s *APIV1Service) registerTelegramRoutes(group *echo.Group) {
+	if s.Telegram == nil {
+		return
+	}
+	link := group.Group("/telegram")
+	link.GET("", s.GetTelegramStatus)
+	link.POST("/link-code", s.CreateTelegramLinkCode)
+	link.PUT("", s.UpdateTelegramSettings)
+	link.DELETE("", s.UnlinkTelegram)
+}
+
+// GET /api/v1/system/telegram returns the bot of the gateway and the current
+// user's link, null before their first link code.
+func (s *APIV1Service) GetTelegramStatus(c echo.Context) error {
+	result := &TelegramStatus{BotUsername: s.Telegram.BotUsername()}
+	link, err := s.Telegram.Link(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil && !errors.Is(err, telegram.ErrNotFound) {
+		return telegramError(c, err, "failed to get telegram link")
+	}
+	result.Link = link
+	return c.JSON(http.StatusOK, result)
+}
+
+// POST /api/v1/system/telegram/link-code creates a link code; sending
+// "/start <code>" to the bot within 15 minutes links the Telegram account.
+func (s *APIV1Service) CreateTelegramLinkCode(c echo.Context) error {
+	code, err := s.Telegram.CreateLinkCode(c.Request().Context(), restCurrentUser(c).ID)
+	if err != nil {
+		return telegramError(c, err, "failed to create telegram link code")
+	}
+	return c.JSON(http.StatusOK, code)
+}
+
+// PUT /api/v1/system/telegram {"enabled": false} pauses or resumes the
+// gateway for the current user, keeping their link.
+func (s *APIV1Service) UpdateTelegramSettings(c echo.Context) error {
+	req := &TelegramSettingsRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	link, err := s.Telegram.SetEnabled(c.Request().Context(), restCurrentUser(c).ID, req.Enabled)
+	if err != nil {
+		return telegramError(c, err, "failed to update telegram settings")
+	}
+	return c.JSON(http.StatusOK, link)
+}
+
+// DELETE /api/v1/system/telegram unlinks the current user's Telegram account.
+func (s *APIV1Service) UnlinkTelegram(c echo.Context) error {
+	if err := s.Telegram.Unlink(c.Request().Context(), restCurrentUser(c).ID); err != nil {
+		return telegramError(c, err, "failed to unlink telegram")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// telegramError maps the errors of the Telegram gateway to responses.
+func telegramError(c echo.Context, err error, msg string) error {
+	if errors.Is(err, telegram.ErrNotFound) {
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
+
+// telegramChat implements telegram.Chatter through the chat path of
+// AIService, as if the user typed the messages in the web app.
+type telegramChat struct {
+	service *APIV1Service
+}
+
+func (t *telegramChat) Chat(ctx context.Context, userID, conversationID int32, message string, answer func(string)) (int32, error) {
+	ai := t.service.AIService
+	if ai == nil {
+		return 0, errors.New("AI chat is disabled on this instance")
+	}
+	user, err := ai.Store.GetUser(ctx, &store.FindUser{ID: &userID})
+	if err != nil {
+		return 0, fmt.Errorf("get user %d: %w", userID, err)
+	}
+	if user == nil || user.RowStatus != store.Normal {
+		return 0, errors.New("your account is not available")
+	}
+	ctx = auth.SetUserInContext(ctx, user, "")
+
+	conversationID, err = t.conversation(ctx, user.ID, conversationID)
+	if err != nil {
+		return 0, err
+	}
+	stream := &telegramChatStream{ctx: ctx, answer: answer}
+	if err := ai.chat(ctx, &v1pb.ChatRequest{Message: message, ConversationId: conversationID}, stream); err != nil {
+		return conversationID, errors.New(status.Convert(aichat.HandleError(err)).Message())
+	}
+	return conversationID, nil
+}
+
+// conversation returns the conversation of the link while it exists, or a
+// new one.
+func (t *telegramChat) conversation(ctx context.Context, userID, conversationID int32) (int32, error) {
+	st := t.service.Store
+	if conversationID != 0 {
+		normal := store.Normal
+		conversations, err := st.ListAIConversations(ctx, &store.FindAIConversation{ID: &conversationID, CreatorID: &userID, RowStatus: &normal})
+		if err != nil {
+			return 0, fmt.Errorf("get conversation %d: %w", conversationID, err)
+		}
+		if len(conversations) > 0 {
+			return conversationID, nil
+		}
+	}
+	now := time.Now().Unix()
+	conversation, err := st.CreateAIConversation(ctx, &store.AIConversation{
+		UID:         shortuuid.New(),
+		CreatorID:   userID,
+		Title:       telegramConversationTitle,
+		TitleSource: store.TitleSourceDefault,
+		CreatedTs:   now,
+		UpdatedTs:   now,
+		RowStatus:   store.Normal,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("create conversation: %w", err)
+	}
+	return conversation.ID, nil
+}
+
+// telegramChatStream accumulates the answer of a chat for the gateway.
+type telegramChatStream struct {
+	ctx    context.Context
+	answer func(string)
+
+	mu   sync.Mutex
+	text strings.Builder
+}
+
+func (s *telegramChatStream) Send(resp *v1pb.ChatResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if resp.EventType == "answer" || resp.EventType == "content" {
+		s.text.WriteString(resp.EventData)
+		s.answer(s.text.String())
+	}
+	return nil
+}
+
+func (s *telegramChatStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,111 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/timeline"
+	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/server/timezone"
+)
+
+// registerTimelineRoutes registers the activity timeline endpoints.
+func (s *APIV1Service) registerTimelineRoutes(group *echo.Group) {
+	if s.Timeline == nil {
+		return
+	}
+	group.GET("/timeline", s.ListTimeline)
+	group.GET("/timeline/summary", s.GetTimelineSummary)
+}
+
+// GET /api/v1/system/timeline?kinds=memo,chat&start=&end=&page_size=&page_token=.
+// Lists the current user's activities newest first: memos created, chats at
+// their last message, schedules that took place and geek sessions that
+// modified files. start and end are Unix timestamps.
+func (s *APIV1Service) ListTimeline(c echo.Context) error {
+	q, err := parseTimelineQuery(c)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	if size := c.QueryParam("page_size"); size != "" {
+		if q.Limit, err = strconv.Atoi(size); err != nil {
+			return restError(c, http.StatusBadRequest, "page_size must be an integer")
+		}
+	}
+	if token := c.QueryParam("page_token"); token != "" {
+		var pageToken v1pb.PageToken
+		if err := unmarshalPageToken(token, &pageToken); err != nil {
+			return restError(c, http.StatusBadRequest, "invalid page_token")
+		}
+		q.Limit, q.Offset = int(pageToken.Limit), int(pageToken.Offset)
+	}
+
+	events, more, err := s.Timeline.List(c.Request().Context(), q)
+	if err != nil {
+		slog.Error("failed to list timeline", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to list timeline")
+	}
+	nextPageToken := ""
+	if more {
+		if nextPageToken, err = getPageToken(q.Limit, q.Offset+q.Limit); err != nil {
+			return restError(c, http.StatusInternalServerError, "failed to create page token")
+		}
+	}
+	if events == nil {
+		events = []*timeline.Event{}
+	}
+	return c.JSON(http.StatusOK, map[string]any{"events": events, "next_page_token": nextPageToken})
+}
+
+// GET /api/v1/system/timeline/summary?kinds=&start=&end=&timezone=Asia/Shanghai.
+// Counts the current user's activities per kind and per day in the time
+// zone; start defaults to 7 days ago.
+func (s *APIV1Service) GetTimelineSummary(c echo.Context) error {
+	q, err := parseTimelineQuery(c)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	if q.Start == 0 {
+		q.Start = time.Now().AddDate(0, 0, -7).Unix()
+	}
+	tz, err := timezone.ParseTimezone(c.QueryParam("timezone"))
+	if err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	summary, err := s.Timeline.Summarize(c.Request().Context(), q, tz)
+	if errors.Is(err, timeline.ErrInvalidQuery) {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		slog.Error("failed to summarize timeline", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to summarize timeline")
+	}
+	return c.JSON(http.StatusOK, summary)
+}
+
+// parseTimelineQuery reads the filters shared by the timeline endpoints.
+func parseTimelineQuery(c echo.Context) (*timeline.Query, error) {
+	q := &timeline.Query{
+		UserID: restCurrentUser(c).ID,
+		Kinds:  timeline.ParseKinds(c.QueryParam("kinds")),
+	}
+	for name, dst := range map[string]*int64{"start": &q.Start, "end": &q.End} {
+		if value := c.QueryParam(name); value != "" {
+			ts, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a Unix timestamp", name)
+			}
+			*dst = ts
+		}
+	}
+	if err := q.Normalize(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
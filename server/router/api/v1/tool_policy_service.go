@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	agentpkg "github.com/hrygo/divinesense/ai/agents"
+	"github.com/hrygo/divinesense/plugin/toolpolicy"
+	"github.com/hrygo/divinesense/store"
+)
+
+// defaultWorkspaceParam is the :workspace of the default tool policy.
+const defaultWorkspaceParam = "default"
+
+// registerToolPolicyRoutes registers the admin API of the Geek Mode tool policies.
+func (s *APIV1Service) registerToolPolicyRoutes(group *echo.Group) {
+	if s.ToolPolicies == nil {
+		return
+	}
+	policies := group.Group("/geek/tool-policies", restAdminMiddleware)
+	policies.GET("", s.ListToolPolicies)
+	policies.PUT("/:workspace", s.UpdateToolPolicy)
+	policies.DELETE("/:workspace", s.DeleteToolPolicy)
+	policies.PUT("/roles/:role", s.UpdateRoleToolPolicy)
+	policies.DELETE("/roles/:role", s.DeleteRoleToolPolicy)
+}
+
+// GET /api/v1/system/geek/tool-policies lists the policies, the default one
+// (user_id 0) first, then the role policies, and the built-in tools of the
+// CLI.
+func (s *APIV1Service) ListToolPolicies(c echo.Context) error {
+	policies, err := s.ToolPolicies.List(c.Request().Context())
+	if err != nil {
+		return toolPolicyError(c, err, "failed to list tool policies")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"policies": policies, "tools": agentpkg.ClaudeCodeTools})
+}
+
+// PUT /api/v1/system/geek/tool-policies/:workspace {"allowed": ["Read", "Grep"], "disallowed": ["WebFetch"]}.
+// :workspace is "default" or the ID of the user owning the workspace. An
+// empty allowlist permits all the tools but the disallowed ones. Running
+// sessions pick the new policy up at their next request.
+func (s *APIV1Service) UpdateToolPolicy(c echo.Context) error {
+	userID, ok := s.toolPolicyWorkspace(c)
+	if !ok {
+		return nil
+	}
+	return s.saveToolPolicy(c, &toolpolicy.Policy{UserID: userID})
+}
+
+// PUT /api/v1/system/geek/tool-policies/roles/:role {"allowed": [...], "disallowed": [...]}.
+// :role is HOST, ADMIN or USER. The policy applies to the workspaces of the
+// users of the role without their own policy, instead of the default one.
+func (s *APIV1Service) UpdateRoleToolPolicy(c echo.Context) error {
+	return s.saveToolPolicy(c, &toolpolicy.Policy{UserID: toolpolicy.DefaultWorkspace, Role: c.Param("role")})
+}
+
+// DELETE /api/v1/system/geek/tool-policies/roles/:role. The workspaces of the
+// role fall back to the default policy.
+func (s *APIV1Service) DeleteRoleToolPolicy(c echo.Context) error {
+	if err := s.ToolPolicies.DeleteRole(c.Request().Context(), c.Param("role")); err != nil {
+		return toolPolicyError(c, err, "failed to delete tool policy")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// saveToolPolicy saves a policy with the rules of the request body.
+func (s *APIV1Service) saveToolPolicy(c echo.Context, policy *toolpolicy.Policy) error {
+	var body struct {
+		Allowed    []string `json:"allowed"`
+		Disallowed []string `json:"disallowed"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	policy.Allowed, policy.Disallowed = body.Allowed, body.Disallowed
+	saved, err := s.ToolPolicies.Save(c.Request().Context(), policy)
+	if err != nil {
+		return toolPolicyError(c, err, "failed to save tool policy")
+	}
+	return c.JSON(http.StatusOK, saved)
+}
+
+// DELETE /api/v1/system/geek/tool-policies/:workspace. A user workspace falls
+// back to the policy of their role or the default one; deleting the default policy lifts the
+// restrictions of the workspaces without their own.
+func (s *APIV1Service) DeleteToolPolicy(c echo.Context) error {
+	userID, ok := s.toolPolicyWorkspace(c)
+	if !ok {
+		return nil
+	}
+	if err := s.ToolPolicies.Delete(c.Request().Context(), userID); err != nil {
+		return toolPolicyError(c, err, "failed to delete tool policy")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// toolPolicyWorkspace parses the workspace of a request, checking its user
+// exists. It writes the error response itself and returns false on failure.
+func (s *APIV1Service) toolPolicyWorkspace(c echo.Context) (int32, bool) {
+	param := c.Param("workspace")
+	if param == defaultWorkspaceParam {
+		return toolpolicy.DefaultWorkspace, true
+	}
+	id, err := strconv.ParseInt(param, 10, 32)
+	if err != nil || id <= 0 {
+		_ = restError(c, http.StatusBadRequest, "workspace must be \"default\" or a user id")
+		return 0, false
+	}
+	userID := int32(id)
+	user, err := s.Store.GetUser(c.Request().Context(), &store.FindUser{ID: &userID})
+	if err != nil {
+		slog.Error("failed to get user", "user_id", userID, "error", err)
+		_ = restError(c, http.StatusInternalServerError, "failed to get user")
+		return 0, false
+	}
+	if user == nil {
+		_ = restError(c, http.StatusNotFound, "user not found")
+		return 0, false
+	}
+	return userID, true
+}
+
+// toolPolicyError maps the errors of the tool policies to responses.
+func toolPolicyError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, toolpolicy.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, toolpolicy.ErrNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
@@ -0,0 +1,54 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/usagestats"
+)
+
+// registerUsageStatsRoutes registers the usage statistics endpoint.
+func (s *APIV1Service) registerUsageStatsRoutes(group *echo.Group) {
+	if s.UsageStats == nil {
+		return
+	}
+	group.GET("/usage-stats", s.GetUsageStats)
+}
+
+// GET /api/v1/system/usage-stats?metric=ai_cost&group_by=day&start=2026-09-01&end=2026-09-30&timezone=Asia/Shanghai.
+// Returns a chart-ready dataset of the current user's usage, the same the
+// stats agent streams as stats_data events. start and end are dates, both
+// included; without start, days (default 30) ending today are covered.
+func (s *APIV1Service) GetUsageStats(c echo.Context) error {
+	in := &usagestats.ToolInput{
+		Metric:   c.QueryParam("metric"),
+		GroupBy:  c.QueryParam("group_by"),
+		Start:    c.QueryParam("start"),
+		End:      c.QueryParam("end"),
+		Timezone: c.QueryParam("timezone"),
+	}
+	if days := c.QueryParam("days"); days != "" {
+		var err error
+		if in.Days, err = strconv.Atoi(days); err != nil {
+			return restError(c, http.StatusBadRequest, "days must be an integer")
+		}
+	}
+	q, err := in.Query(restCurrentUser(c).ID, time.Now())
+	if err != nil {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	dataset, err := s.UsageStats.Query(c.Request().Context(), q)
+	if errors.Is(err, usagestats.ErrInvalidQuery) {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		slog.Error("failed to compute usage stats", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to compute usage stats")
+	}
+	return c.JSON(http.StatusOK, dataset)
+}
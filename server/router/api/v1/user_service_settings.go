@@ -7,6 +7,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/hrygo/divinesense/plugin/i18n"
 	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
 	storepb "github.com/hrygo/divinesense/proto/gen/store"
 	"github.com/hrygo/divinesense/store"
@@ -20,6 +21,20 @@ func getDefaultUserGeneralSetting() *v1pb.UserSetting_GeneralSetting {
 	}
 }
 
+// userLocale returns the locale of the general settings of a user, in which
+// the content the server writes for them is localized; i18n.DefaultLocale
+// when they chose none.
+func userLocale(ctx context.Context, st *store.Store, userID int32) i18n.Locale {
+	setting, err := st.GetUserSetting(ctx, &store.FindUserSetting{
+		UserID: &userID,
+		Key:    storepb.UserSetting_GENERAL,
+	})
+	if err != nil || setting.GetGeneral().GetLocale() == "" {
+		return i18n.DefaultLocale
+	}
+	return i18n.Match(setting.GetGeneral().GetLocale())
+}
+
 func (s *UserService) GetUserSetting(ctx context.Context, request *v1pb.GetUserSettingRequest) (*v1pb.UserSetting, error) {
 	// Parse resource name: users/{user}/settings/{setting}
 	userID, settingKey, err := ExtractUserIDAndSettingKeyFromName(request.Name)
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -13,15 +14,71 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"golang.org/x/sync/semaphore"
 
+	"github.com/hrygo/divinesense"
 	"github.com/hrygo/divinesense/ai"
+	"github.com/hrygo/divinesense/ai/agents/universal"
 	"github.com/hrygo/divinesense/ai/core/retrieval"
 	aistats "github.com/hrygo/divinesense/ai/services/stats"
 	"github.com/hrygo/divinesense/internal/profile"
+	"github.com/hrygo/divinesense/plugin/actionaudit"
+	"github.com/hrygo/divinesense/plugin/blockbudget"
+	"github.com/hrygo/divinesense/plugin/calsync"
+	"github.com/hrygo/divinesense/plugin/canary"
+	"github.com/hrygo/divinesense/plugin/capture"
+	"github.com/hrygo/divinesense/plugin/changelog"
 	"github.com/hrygo/divinesense/plugin/chat_apps/channels"
 	chatstore "github.com/hrygo/divinesense/plugin/chat_apps/store"
+	"github.com/hrygo/divinesense/plugin/checklist"
+	"github.com/hrygo/divinesense/plugin/contact"
+	"github.com/hrygo/divinesense/plugin/convlock"
+	"github.com/hrygo/divinesense/plugin/documentchat"
+	"github.com/hrygo/divinesense/plugin/emailin"
+	"github.com/hrygo/divinesense/plugin/embedindex"
+	"github.com/hrygo/divinesense/plugin/entitygraph"
+	"github.com/hrygo/divinesense/plugin/evolutiontask"
+	"github.com/hrygo/divinesense/plugin/expense"
+	"github.com/hrygo/divinesense/plugin/featureflag"
+	"github.com/hrygo/divinesense/plugin/feed"
+	"github.com/hrygo/divinesense/plugin/fewshot"
+	"github.com/hrygo/divinesense/plugin/flashcard"
+	"github.com/hrygo/divinesense/plugin/github"
+	"github.com/hrygo/divinesense/plugin/habit"
+	"github.com/hrygo/divinesense/plugin/homeassistant"
+	"github.com/hrygo/divinesense/plugin/httpaction"
+	"github.com/hrygo/divinesense/plugin/i18n"
+	"github.com/hrygo/divinesense/plugin/intenttaxonomy"
+	"github.com/hrygo/divinesense/plugin/kbhealth"
+	"github.com/hrygo/divinesense/plugin/llmregistry"
+	"github.com/hrygo/divinesense/plugin/mailbox"
 	"github.com/hrygo/divinesense/plugin/markdown"
+	"github.com/hrygo/divinesense/plugin/mcpserver"
+	"github.com/hrygo/divinesense/plugin/offlinesync"
+	"github.com/hrygo/divinesense/plugin/pdfdoc"
+	"github.com/hrygo/divinesense/plugin/quiethours"
+	"github.com/hrygo/divinesense/plugin/readlater"
+	"github.com/hrygo/divinesense/plugin/reminder"
+	"github.com/hrygo/divinesense/plugin/retrievalscope"
+	"github.com/hrygo/divinesense/plugin/scheduledmsg"
+	"github.com/hrygo/divinesense/plugin/scripting"
+	"github.com/hrygo/divinesense/plugin/searchfeedback"
+	"github.com/hrygo/divinesense/plugin/shadow"
+	"github.com/hrygo/divinesense/plugin/snippet"
+	"github.com/hrygo/divinesense/plugin/statuspage"
+	"github.com/hrygo/divinesense/plugin/timeline"
+	"github.com/hrygo/divinesense/plugin/toolpack"
+	"github.com/hrygo/divinesense/plugin/toolpolicy"
+	"github.com/hrygo/divinesense/plugin/usagestats"
+	"github.com/hrygo/divinesense/plugin/vectorindex"
+	"github.com/hrygo/divinesense/plugin/webfetch"
+	"github.com/hrygo/divinesense/plugin/webhook"
 	v1pb "github.com/hrygo/divinesense/proto/gen/api/v1"
+	"github.com/hrygo/divinesense/server/affinity"
 	"github.com/hrygo/divinesense/server/auth"
+	"github.com/hrygo/divinesense/server/integration/telegram"
+	aichat "github.com/hrygo/divinesense/server/router/api/v1/ai"
+	embeddingrunner "github.com/hrygo/divinesense/server/runner/embedding"
+	"github.com/hrygo/divinesense/server/runner/jobs"
+	ocrrunner "github.com/hrygo/divinesense/server/runner/ocr"
 	"github.com/hrygo/divinesense/store"
 )
 
@@ -47,6 +104,144 @@ type APIV1Service struct {
 	Secret             string
 	chatChannelRouter  *channels.ChannelRouter
 	chatAppStore       *chatstore.ChatAppStore
+
+	// ScriptHooks runs admin-defined scripting hooks (PostgreSQL only).
+	ScriptHooks     *scripting.Manager
+	scriptHookStore *scripting.HookStore
+
+	// HTTPActions exposes admin-registered HTTP endpoints to parrots (PostgreSQL only).
+	HTTPActions     *httpaction.Registry
+	httpActionStore *httpaction.ActionStore
+	// WebFetch fetches web pages for parrots under the admin policy
+	// (PostgreSQL only).
+	WebFetch *webfetch.Fetcher
+
+	// scheduledMessageStore queues chat messages scheduled by users (PostgreSQL only).
+	scheduledMessageStore *scheduledmsg.MessageStore
+	// kbHealthStore holds the weekly health report settings (PostgreSQL only).
+	kbHealthStore *kbhealth.DBStore
+	// captureStore keeps the source page of memos captured by the browser
+	// extension (PostgreSQL only).
+	captureStore *capture.DBStore
+	// readLater is the read-later queue; items are scored once the simple
+	// task LLM is available (PostgreSQL only).
+	readLater *readlater.Queue
+	// Snippets indexes the code blocks of memos tagged #snippet (PostgreSQL only).
+	Snippets *snippet.Library
+	// Timeline lists the activities of users across memos, chats, schedules
+	// and geek sessions (PostgreSQL only).
+	Timeline *timeline.Timeline
+	// UsageStats computes statistics of users' own usage (PostgreSQL only).
+	UsageStats *usagestats.Stats
+	// Checklists are the checklists of users, generated by the checklist
+	// parrot (PostgreSQL only).
+	Checklists *checklist.Checklists
+	// Habits are the habits users track, coached by the general parrot
+	// (PostgreSQL only).
+	Habits *habit.Habits
+	// Expenses are the expenses of users, recorded by the expense parrot
+	// (PostgreSQL only).
+	Expenses *expense.Expenses
+	// QuietHours are the daily hours during which background jobs leave
+	// users alone (PostgreSQL only).
+	QuietHours *quiethours.Hours
+	// BlockBudgets are the default cost guardrails of users' chat blocks
+	// (PostgreSQL only).
+	BlockBudgets *blockbudget.Budgets
+	// OfflineSync serves the sync protocol of offline-first clients for memos
+	// and conversations (PostgreSQL only).
+	OfflineSync *offlinesync.Sync
+	// IntentTaxonomy holds the admin-managed chat intents of the router (PostgreSQL only)
+	IntentTaxonomy *intenttaxonomy.Taxonomy
+	// FewShots are the admin-curated examples of routes and answer styles (PostgreSQL only)
+	FewShots *fewshot.Library
+	// Canaries roll new experts out on a share of their incumbent's traffic
+	// (PostgreSQL only).
+	Canaries *canary.Canaries
+	// FeatureFlags gate risky features and roll them out gradually
+	// (PostgreSQL only).
+	FeatureFlags *featureflag.Flags
+	// Shadows run modified prompts and models unseen on sampled requests
+	// (PostgreSQL only).
+	Shadows *shadow.Shadows
+	// ToolPolicies are the Claude Code tools Geek Mode workspaces may use
+	// (PostgreSQL only).
+	ToolPolicies *toolpolicy.Policies
+	// ActionAudit is the append-only audit log of the tools called by Geek
+	// and Evolution Mode sessions (PostgreSQL only).
+	ActionAudit *actionaudit.Audit
+	// MCPServers are the external MCP servers users register for their Geek
+	// Mode sessions (PostgreSQL only).
+	MCPServers *mcpserver.Servers
+	// GitHub serves the GitHub tools of Geek Mode sessions on the
+	// repositories users allow-list (PostgreSQL only).
+	GitHub *github.GitHub
+	// Webhooks deliver signed callbacks of block, memo and schedule events to
+	// the endpoints of users and of the instance (PostgreSQL only).
+	Webhooks *webhook.Registry
+	// Telegram relays the private chats of linked users with the instance
+	// bot to conversations and memos (PostgreSQL only, with a bot token).
+	Telegram *telegram.Gateway
+	// EmailIn saves the emails sent to the secret addresses of users as memos
+	// (PostgreSQL only, with an inbound domain and signing key).
+	EmailIn *emailin.Inboxes
+	// Mailbox reads the IMAP accounts of users for the mail parrot, saving
+	// action items to their inbox (PostgreSQL only, with an email secret key).
+	Mailbox *mailbox.Mailbox
+	// Feeds fetches the RSS/Atom subscriptions of users, saving their entries
+	// as read-later memos (PostgreSQL only).
+	Feeds *feed.Feeds
+	// Calendars serves the iCalendar feeds of the schedules of users and syncs
+	// their CalDAV calendars (PostgreSQL only; CalDAV with a secret key).
+	Calendars *calsync.Calendars
+	// StatusPage serves the public status of the instance and the incidents
+	// posted by admins (PostgreSQL only).
+	StatusPage *statuspage.Page
+	// Changelog serves the release notes up to the running version and what
+	// is new for each user since the release they last saw (PostgreSQL only).
+	Changelog *changelog.Changelog
+	// Reminders sends the reminders of schedules through web push, email,
+	// Telegram and webhooks (PostgreSQL only).
+	Reminders *reminder.Reminders
+	// webPush is the web push channel of the reminders, with VAPID keys.
+	webPush *reminder.WebPush
+	// EvolutionTasks is the backlog of Evolution Mode tasks admins approve
+	// before a run (PostgreSQL only).
+	EvolutionTasks *evolutiontask.Tasks
+	// LLMRegistry selects the LLM provider and model of each agent type (with
+	// AI enabled; admin selections persist on PostgreSQL only).
+	LLMRegistry *llmregistry.Registry
+	// EmbeddingIndex follows the memo embedding model swapped in by index
+	// rebuilds (with AI enabled, PostgreSQL only).
+	EmbeddingIndex *embedindex.Index
+	// VectorIndex maintains the pgvector index of the memo embeddings
+	// (PostgreSQL only).
+	VectorIndex *vectorindex.Service
+	// SearchFeedback tunes the hybrid memo search of each user from their
+	// relevance feedback (PostgreSQL only).
+	SearchFeedback *searchfeedback.Service
+	// RetrievalScopes restrict the memos conversations retrieve to some tags,
+	// or turn retrieval off (PostgreSQL only).
+	RetrievalScopes *retrievalscope.Scopes
+	// DocumentChats bind conversations to a single memo or attachment,
+	// retrieval searching its passages only (PostgreSQL only).
+	DocumentChats *documentchat.Documents
+	// ConversationLocks lock conversations with a passphrase held by their
+	// user, the store sealing their history (PostgreSQL only).
+	ConversationLocks *convlock.Locks
+	// OCRRunner extracts the text of uploaded images and documents (PostgreSQL
+	// only, when OCR or text extraction is enabled).
+	OCRRunner *ocrrunner.Runner
+	// EmbeddingRunner embeds new and changed memos in the background and
+	// reindexes them on demand (with AI enabled).
+	EmbeddingRunner *embeddingrunner.Runner
+
+	// SessionAffinity routes CC session messages to the instance holding the
+	// session in multi-instance deployments (PostgreSQL only).
+	SessionAffinity *affinity.Router
+	// Jobs runs the periodic background jobs, once across the replicas on
+	// PostgreSQL.
+	Jobs *jobs.Scheduler
 }
 
 func NewAPIV1Service(secret string, profile *profile.Profile, store *store.Store) *APIV1Service {
@@ -64,6 +259,94 @@ func NewAPIV1Service(secret string, profile *profile.Profile, store *store.Store
 		chatAppStore:       chatstore.NewChatAppStore(store.GetDriver().GetDB()),
 	}
 
+	// Script hooks are persisted in PostgreSQL alongside the other AI tables.
+	if profile.Driver == "postgres" {
+		service.scriptHookStore = scripting.NewHookStore(store.GetDriver().GetDB())
+		service.ScriptHooks = scripting.NewManager(service.scriptHookStore, scripting.NewEngine(scripting.DefaultLimits()))
+		service.httpActionStore = httpaction.NewActionStore(store.GetDriver().GetDB())
+		service.HTTPActions = httpaction.NewRegistry(service.httpActionStore, store.SecurityAuditStore)
+		service.WebFetch = webfetch.New(webfetch.NewDBStore(store.GetDriver().GetDB()), store.SecurityAuditStore)
+		service.scheduledMessageStore = scheduledmsg.NewMessageStore(store.GetDriver().GetDB())
+		service.kbHealthStore = kbhealth.NewDBStore(store.GetDriver().GetDB())
+		service.Snippets = snippet.NewLibrary(snippet.NewDBStore(store.GetDriver().GetDB()))
+		service.Timeline = timeline.New(timeline.NewDBStore(store.GetDriver().GetDB()))
+		service.UsageStats = usagestats.New(usagestats.NewDBStore(store.GetDriver().GetDB()), universal.EstimateCostUSD)
+		service.Checklists = checklist.New(checklist.NewDBStore(store.GetDriver().GetDB()))
+		service.Habits = habit.New(habit.NewDBStore(store.GetDriver().GetDB()))
+		service.Expenses = expense.New(expense.NewDBStore(store.GetDriver().GetDB()))
+		service.QuietHours = quiethours.New(quiethours.NewDBStore(store.GetDriver().GetDB()))
+		service.BlockBudgets = blockbudget.New(blockbudget.NewDBStore(store.GetDriver().GetDB()))
+		service.VectorIndex = vectorindex.New(vectorindex.NewDBStore(store.GetDriver().GetDB()), vectorindex.ConfigFromProfile(profile))
+		if err := service.VectorIndex.Config().Validate(); err != nil {
+			slog.Warn("invalid vector index configuration", "error", err)
+		}
+		service.SearchFeedback = searchfeedback.New(searchfeedback.NewDBStore(store.GetDriver().GetDB()))
+		service.RetrievalScopes = retrievalscope.New(retrievalscope.NewDBStore(store.GetDriver().GetDB()))
+		service.DocumentChats = documentchat.New(documentchat.NewDBStore(store.GetDriver().GetDB()), documentchat.NewStoreLoader(store))
+		service.OfflineSync = offlinesync.New(offlinesync.NewDBStore(store.GetDriver().GetDB()), &offlineSyncApplier{s: service})
+		service.IntentTaxonomy = intenttaxonomy.New(intenttaxonomy.NewDBStore(store.GetDriver().GetDB()), chatRoutes, service.reloadIntentTaxonomy)
+		service.FewShots = fewshot.New(fewshot.NewDBStore(store.GetDriver().GetDB()), chatRoutes, service.reloadFewShots)
+		service.Canaries = canary.New(canary.NewDBStore(store.GetDriver().GetDB()))
+		service.FeatureFlags = newFeatureFlags(store)
+		service.Shadows = shadow.New(shadow.NewDBStore(store.GetDriver().GetDB()))
+		service.ToolPolicies = toolpolicy.New(toolpolicy.NewDBStore(store.GetDriver().GetDB()))
+		service.ActionAudit = actionaudit.New(actionaudit.NewDBStore(store.GetDriver().GetDB()))
+		service.MCPServers = mcpserver.New(mcpserver.NewDBStore(store.GetDriver().GetDB()))
+		service.GitHub = github.New(github.Config{MCPURL: githubMCPURL(profile), Secret: secret}, github.NewDBStore(store.GetDriver().GetDB()), store.SecurityAuditStore)
+		service.Webhooks = webhook.New(webhook.NewDBStore(store.GetDriver().GetDB()))
+		service.Changelog = changelog.New(changelog.NewDBStore(store.GetDriver().GetDB()), changelog.Config{Version: profile.Version, Markdown: divinesense.Changelog})
+		if profile.TelegramBotToken != "" {
+			service.Telegram = telegram.New(telegram.Config{
+				Token:       profile.TelegramBotToken,
+				APIURL:      profile.TelegramAPIURL,
+				InstanceURL: profile.InstanceURL,
+			}, telegram.NewDBStore(store.GetDriver().GetDB()), &telegramChat{service: service}, &meetingMemos{service: service})
+		}
+		if profile.EmailInDomain != "" && profile.EmailInSigningKey != "" {
+			service.EmailIn = emailin.New(emailin.Config{Domain: profile.EmailInDomain, SigningKey: profile.EmailInSigningKey}, emailin.NewDBStore(store.GetDriver().GetDB()))
+		}
+		if profile.EmailSecretKey != "" {
+			mailboxes, err := mailbox.New(mailbox.Config{SecretKey: profile.EmailSecretKey}, mailbox.NewDBStore(store.GetDriver().GetDB()), store)
+			if err != nil {
+				slog.Warn("mail parrot disabled: invalid email secret key", "error", err)
+			} else {
+				service.Mailbox = mailboxes
+			}
+		}
+		if calendars, err := calsync.New(calsync.Config{
+			InstanceURL: profile.InstanceURL,
+			SecretKey:   profile.CalDAVSecretKey,
+		}, calsync.NewDBStore(store.GetDriver().GetDB()), store); err != nil {
+			slog.Warn("calendar sync disabled: invalid CalDAV secret key", "error", err)
+		} else {
+			service.Calendars = calendars
+		}
+		service.Reminders = newReminders(service, profile)
+		service.EvolutionTasks = evolutiontask.New(evolutiontask.NewDBStore(store.GetDriver().GetDB()))
+		service.ConversationLocks = convlock.New(convlock.NewDBStore(store.GetDriver().GetDB()), convlock.DefaultUnlockTTL)
+		if err := service.ConversationLocks.Load(context.Background()); err != nil {
+			slog.Error("failed to load conversation locks", "error", err)
+		}
+		store.SetBlockSealer(service.ConversationLocks)
+		service.captureStore = capture.NewDBStore(store.GetDriver().GetDB())
+		service.readLater = readlater.NewQueue(readlater.NewDBStore(store.GetDriver().GetDB()), nil, service.deliverReadLaterDigest)
+		service.readLater.SetLocaleResolver(func(ctx context.Context, userID int32) i18n.Locale {
+			return userLocale(ctx, store, userID)
+		})
+		service.readLater.AddSection(service.Habits.DigestSection)
+		service.Feeds = feed.New(feed.NewDBStore(store.GetDriver().GetDB()), &feedMemos{service: service})
+		// OCR may call a vision model, so safe mode turns it off with the other AI jobs
+		if runner := ocrrunner.NewRunner(store, profile); runner.Enabled() && !profile.SafeMode {
+			service.OCRRunner = runner
+		}
+	}
+	if instance := affinity.NewInstance(profile.InstanceID, profile.InstanceURL); instance != nil {
+		if registry := sessionRegistry(profile, store); registry != nil {
+			service.SessionAffinity = affinity.NewRouter(instance, registry)
+			slog.Info("CC session affinity enabled", "instance_id", instance.ID, "instance_url", instance.URL)
+		}
+	}
+
 	// Initialize AI service if enabled
 	// AI features are supported on PostgreSQL (with pgvector) and SQLite (with application-layer vector search)
 	if profile.IsAIEnabled() && (profile.Driver == "postgres" || profile.Driver == "sqlite") {
@@ -71,6 +354,19 @@ func NewAPIV1Service(secret string, profile *profile.Profile, store *store.Store
 		if err := aiConfig.Validate(); err == nil {
 			embeddingService, err := ai.NewEmbeddingService(&aiConfig.Embedding)
 			if err == nil {
+				// Memo searches embed with the active model, which index rebuilds swap
+				store.SetEmbeddingModel(aiConfig.Embedding.Model)
+				if profile.Driver == "postgres" {
+					service.EmbeddingIndex = embedindex.New(embedindex.NewDBStore(store.GetDriver().GetDB()), store, aiConfig.Embedding.Model)
+					if err := service.EmbeddingIndex.Load(context.Background()); err != nil {
+						slog.Error("failed to load the active embedding model", "error", err)
+					}
+				}
+				// Embed with the active model, following the swaps of index rebuilds
+				service.EmbeddingRunner = embeddingrunner.NewRunner(store, embedindex.NewService(&aiConfig.Embedding, embeddingService, store.EmbeddingModel))
+				store.WatchMemos(service.EmbeddingRunner)
+				memoEmbedding := embedindex.NewService(&aiConfig.Embedding, embeddingService, store.EmbeddingModel)
+				memoEmbedding.EnableQueryCache(profile.AIEmbeddingCacheSize, time.Duration(profile.AIEmbeddingCacheTTLMinutes)*time.Minute)
 				rerankerService := ai.NewRerankerService(&aiConfig.Reranker)
 				var llmService ai.LLMService
 				if aiConfig.LLM.Provider != "" {
@@ -100,12 +396,38 @@ func NewAPIV1Service(secret string, profile *profile.Profile, store *store.Store
 					}
 				}
 
+				// Agent types run on the default LLM unless a provider is selected for them
+				if llmService != nil {
+					var selectionStore llmregistry.Store
+					if profile.Driver == "postgres" {
+						selectionStore = llmregistry.NewDBStore(store.GetDriver().GetDB())
+					}
+					service.LLMRegistry = llmregistry.New(selectionStore, &llmregistry.Config{
+						Default:     &aiConfig.LLM,
+						DefaultLLM:  llmService,
+						Providers:   aiConfig.LLMProviders,
+						AgentModels: aiConfig.AgentModels,
+					})
+				}
+
 				// 创建自适应检索器
-				adaptiveRetriever := retrieval.NewAdaptiveRetriever(store, embeddingService, rerankerService)
+				adaptiveRetriever := retrieval.NewAdaptiveRetriever(store, memoEmbedding, rerankerService)
+				if err := adaptiveRetriever.SetHybridConfig(retrieval.HybridConfigFromProfile(profile)); err != nil {
+					slog.Warn("invalid hybrid retrieval configuration, using the defaults", "error", err)
+				}
+				if service.SearchFeedback != nil {
+					adaptiveRetriever.SetWeightTuner(service.SearchFeedback)
+				}
 
 				// 创建 session stats 持久化器
 				persister := aistats.NewPersister(store.AgentStatsStore, 100, slog.Default())
 
+				// Monthly quotas count session costs and price normal chat tokens like the usage stats
+				quotas := aistats.NewQuotaService(store.AgentStatsStore, aistats.QuotaConfig{
+					MonthlyTokens:  profile.AIMonthlyTokenQuota,
+					MonthlyCostUSD: profile.AIMonthlyCostQuotaUSD,
+				}, universal.EstimateCostUSD, slog.Default())
+
 				// 创建简单任务 LLM 服务（标题生成、摘要、标签等）
 				// 使用 Intent 配置 (siliconflow)，未配置时回退到主 LLM
 				intentLLMService := ai.NewSimpleTaskLLMService(profile, llmService)
@@ -117,17 +439,123 @@ func NewAPIV1Service(secret string, profile *profile.Profile, store *store.Store
 					slog.Info("Title generator initialized with simple task LLM service")
 				}
 
+				// The knowledge graph lives in PostgreSQL; entities are extracted by the simple task LLM
+				var entityGraph *entitygraph.Graph
+				// Contacts are the people of the knowledge graph, with their follow-ups
+				var contacts *contact.Contacts
+				if profile.Driver == "postgres" && intentLLMService != nil {
+					entityGraph = entitygraph.NewGraph(
+						entitygraph.NewDBStore(store.GetDriver().GetDB()),
+						entitygraph.NewLLMExtractor(intentLLMService),
+					)
+					contacts = contact.New(contact.NewDBStore(store.GetDriver().GetDB()), entityGraph)
+					if service.readLater != nil {
+						service.readLater.AddSection(contacts.DigestSection)
+					}
+				}
+
+				// Flashcards are stored in PostgreSQL and written by the simple task LLM
+				var flashcards *flashcard.Deck
+				if profile.Driver == "postgres" && intentLLMService != nil {
+					flashcards = flashcard.NewDeck(
+						flashcard.NewDBStore(store.GetDriver().GetDB()),
+						flashcard.NewLLMGenerator(intentLLMService),
+						flashcardMemoFinder{store: store},
+					)
+				}
+
+				// Read-later items are ranked against the user's interests by the simple task LLM
+				if service.readLater != nil && intentLLMService != nil {
+					service.readLater.SetScorer(readlater.NewLLMScorer(intentLLMService))
+				}
+
+				// Feed entries are summarized and tagged by the simple task LLM before they are saved
+				if service.Feeds != nil && intentLLMService != nil {
+					service.Feeds.SetEnricher(feed.NewLLMEnricher(intentLLMService))
+				}
+
+				// PDFs are extracted page by page with Tika and embedded into PostgreSQL
+				var documents *pdfdoc.Library
+				if profile.Driver == "postgres" && profile.TextExtractEnabled {
+					documents = pdfdoc.NewLibrary(
+						pdfdoc.NewDBStore(store.GetDriver().GetDB()),
+						newPDFExtractor(profile),
+						embeddingService,
+						service.loadAttachmentBlob,
+						aiConfig.Embedding.Model,
+						pdfdoc.Limits{
+							MaxSizeBytes: int64(profile.PDFMaxSizeMB) << 20,
+							MaxPages:     profile.PDFMaxPages,
+						},
+					)
+				}
+
+				// Time and unit tools of the general parrot; weather and currencies need a provider
+				utilityTools, err := toolpack.New(toolpack.ConfigFromProfile(profile))
+				if err != nil {
+					slog.Warn("weather and currency tools disabled: invalid provider configuration", "error", err)
+				}
+
+				// Home Assistant of the home parrot, which is not routed to without it
+				var home *homeassistant.Home
+				if homeConfig := homeassistant.ConfigFromProfile(profile); homeConfig.Enabled() {
+					home, err = homeassistant.New(homeConfig, store.SecurityAuditStore)
+					if err != nil {
+						slog.Warn("home parrot disabled: invalid Home Assistant configuration", "error", err)
+					}
+				}
+
 				service.AIService = &AIService{
 					Store:                  store,
-					EmbeddingService:       embeddingService,
-					EmbeddingModel:         aiConfig.Embedding.Model,
+					Secret:                 secret,
+					EmbeddingService:       memoEmbedding,
 					RerankerService:        rerankerService,
 					LLMService:             llmService,
 					IntentLLMService:       intentLLMService,
+					LLMConfig:              &aiConfig.LLM,
 					AdaptiveRetriever:      adaptiveRetriever,
 					IntentClassifierConfig: &aiConfig.IntentClassifier,
 					UniversalParrotConfig:  &aiConfig.UniversalParrot, // Phase 2: Config-driven parrots
 					TitleGenerator:         titleGenerator,
+					ScriptHooks:            service.ScriptHooks,
+					HTTPActions:            service.HTTPActions,
+					WebFetch:               service.WebFetch,
+					SessionAffinity:        service.SessionAffinity,
+					EntityGraph:            entityGraph,
+					Contacts:               contacts,
+					Flashcards:             flashcards,
+					Documents:              documents,
+					Snippets:               service.Snippets,
+					Timeline:               service.Timeline,
+					UsageStats:             service.UsageStats,
+					MeetingNotes:           service.newMeetingNotes(),
+					Checklists:             service.Checklists,
+					Habits:                 service.Habits,
+					Toolpack:               utilityTools,
+					Expenses:               service.Expenses,
+					HomeAssistant:          home,
+					Mailbox:                service.Mailbox,
+					Reminders:              service.Reminders,
+					BlockBudgets:           service.BlockBudgets,
+					IntentTaxonomy:         service.IntentTaxonomy,
+					FewShots:               service.FewShots,
+					Canaries:               service.Canaries,
+					FeatureFlags:           service.FeatureFlags,
+					Shadows:                service.Shadows,
+					LoadShedder:            newLoadShedder(profile, store, persister),
+					ToolPolicies:           service.ToolPolicies,
+					MCPServers:             service.MCPServers,
+					GitHub:                 service.GitHub,
+					Webhooks:               service.Webhooks,
+					RetrievalScopes:        service.RetrievalScopes,
+					DocumentChats:          service.DocumentChats,
+					EvolutionTasks:         service.EvolutionTasks,
+					LLMRegistry:            service.LLMRegistry,
+					Quotas:                 quotas,
+					WorkspaceQuota:         newGeekWorkspaceQuota(profile),
+					ActionAudit:            service.ActionAudit,
+					EmbeddingRunner:        service.EmbeddingRunner,
+					RunnerBackends:         aichat.ModeRunnerBackends{Geek: profile.GeekRunner, Evolution: profile.EvolutionRunner},
 					persister:              persister,
 				}
 				// Warmup router service (build semantic index) asynchronously
@@ -156,19 +584,22 @@ func NewAPIV1Service(secret string, profile *profile.Profile, store *store.Store
 	} else {
 		slog.Info("AI features disabled",
 			"enabled", profile.IsAIEnabled(),
+			"safe_mode", profile.SafeMode,
 			"driver", profile.Driver,
 		)
 	}
 
 	service.UserService = &UserService{Store: store}
-	service.MemoService = &MemoService{Store: store, AIService: service.AIService, MarkdownService: markdownService, Profile: profile}
+	service.MemoService = &MemoService{Store: store, AIService: service.AIService, MarkdownService: markdownService, Profile: profile, ScriptHooks: service.ScriptHooks, Snippets: service.Snippets, Webhooks: service.Webhooks}
 	service.AuthService = &AuthService{Store: store, Secret: secret, Profile: profile}
-	service.AttachmentService = &AttachmentService{Store: store, Profile: profile, thumbnailSemaphore: service.thumbnailSemaphore}
+	service.AttachmentService = &AttachmentService{Store: store, Profile: profile, thumbnailSemaphore: service.thumbnailSemaphore, ocrRunner: service.OCRRunner}
 	service.ShortcutService = &ShortcutService{Store: store, Profile: profile}
-	service.InstanceService = &InstanceService{Store: store, Profile: profile, AIService: service.AIService}
+	service.Jobs = service.newJobs()
+	service.InstanceService = &InstanceService{Store: store, Profile: profile, AIService: service.AIService, Jobs: service.Jobs}
 	service.IdentityProviderService = &IdentityProviderService{Store: store}
 	service.ActivityService = &ActivityService{Store: store}
 	service.ChatAppService = &ChatAppService{Store: store, Secret: secret, Profile: profile, AIService: service.AIService, chatChannelRouter: service.chatChannelRouter, chatAppStore: service.chatAppStore}
+	service.StatusPage = service.newStatusPage()
 
 	return service
 }
@@ -183,6 +614,13 @@ func (s *APIV1Service) RegisterGateway(ctx context.Context, echoServer *echo.Ech
 		// Don't fail startup, just log a warning
 	}
 
+	// Wire the chat handler now: missing dependencies fail the startup
+	if s.AIService != nil {
+		if err := s.AIService.WireChat(); err != nil {
+			return fmt.Errorf("failed to wire chat handler: %w", err)
+		}
+	}
+
 	// Auth middleware for gRPC-Gateway - runs after routing, has access to method name.
 	// Uses the same PublicMethods config as the Connect AuthInterceptor.
 	authenticator := auth.NewAuthenticator(s.Store, s.Secret)
@@ -271,6 +709,9 @@ func (s *APIV1Service) RegisterGateway(ctx context.Context, echoServer *echo.Ech
 
 	gwGroup.Any("/api/v1/*", handler)
 	gwGroup.Any("/file/*", handler)
+	if s.Profile.SafeMode {
+		gwGroup.Any("/api/v1/ai/*", restSafeModeError)
+	}
 
 	// Connect handlers for browser clients (replaces grpc-web).
 	logStacktraces := s.Profile.IsDev()
@@ -296,10 +737,62 @@ func (s *APIV1Service) RegisterGateway(ctx context.Context, echoServer *echo.Ech
 	connectGroup := echoServer.Group("", corsHandler)
 	connectGroup.Any("/memos.api.v1.*", echo.WrapHandler(connectMux))
 
+	// SSE transport of Chat for clients without gRPC or Connect
+	s.registerChatStreamRoutes(echoServer, corsHandler)
+
 	// Register metrics routes (direct REST endpoints)
 	systemGroup := echoServer.Group("/api/v1/system", corsHandler)
 	systemGroup.GET("/metrics/overview", s.GetMetricsOverview)
 
+	// Authenticated REST endpoints (admin tools without a proto definition)
+	authedSystemGroup := systemGroup.Group("", s.restAuthMiddleware())
+	s.registerScriptHookRoutes(authedSystemGroup)
+	s.registerHTTPActionRoutes(authedSystemGroup)
+	s.registerRoutingRoutes(authedSystemGroup)
+	s.registerSuggestionRoutes(authedSystemGroup)
+	s.registerDiagnosticsRoutes(authedSystemGroup)
+	s.registerScheduledMessageRoutes(authedSystemGroup)
+	s.registerEntityRoutes(authedSystemGroup)
+	s.registerContactRoutes(authedSystemGroup)
+	s.registerFlashcardRoutes(authedSystemGroup)
+	s.registerKBHealthRoutes(authedSystemGroup)
+	s.registerDocumentRoutes(authedSystemGroup)
+	s.registerSnippetRoutes(authedSystemGroup)
+	s.registerCaptureRoutes(echoServer, authedSystemGroup)
+	s.registerReadLaterRoutes(authedSystemGroup)
+	s.registerTimelineRoutes(authedSystemGroup)
+	s.registerUsageStatsRoutes(authedSystemGroup)
+	s.registerChecklistRoutes(authedSystemGroup)
+	s.registerHabitRoutes(authedSystemGroup)
+	s.registerExpenseRoutes(authedSystemGroup)
+	s.registerQuietHoursRoutes(authedSystemGroup)
+	s.registerBlockBudgetRoutes(authedSystemGroup)
+	s.registerOfflineSyncRoutes(authedSystemGroup)
+	s.registerIntentTaxonomyRoutes(authedSystemGroup)
+	s.registerFewShotRoutes(authedSystemGroup)
+	s.registerCanaryRoutes(authedSystemGroup)
+	s.registerFeatureFlagRoutes(authedSystemGroup)
+	s.registerShadowRoutes(authedSystemGroup)
+	s.registerConversationLockRoutes(authedSystemGroup)
+	s.registerMemoExportRoutes(authedSystemGroup)
+	s.registerWebFetchRoutes(authedSystemGroup)
+	s.registerToolPolicyRoutes(authedSystemGroup)
+	s.registerGitHubRoutes(echoServer, authedSystemGroup)
+	s.registerTelegramRoutes(authedSystemGroup)
+	s.registerEmailInRoutes(echoServer, authedSystemGroup)
+	s.registerMailboxRoutes(authedSystemGroup)
+	s.registerFeedRoutes(authedSystemGroup)
+	s.registerCalendarRoutes(echoServer, authedSystemGroup)
+	s.registerReminderRoutes(authedSystemGroup)
+	s.registerStatusRoutes(echoServer, authedSystemGroup)
+	s.registerChangelogRoutes(authedSystemGroup)
+	s.registerWebhookRoutes(authedSystemGroup)
+	s.registerEvolutionTaskRoutes(authedSystemGroup)
+	s.registerVectorIndexRoutes(authedSystemGroup)
+	s.registerSearchFeedbackRoutes(authedSystemGroup)
+	s.registerRetrievalScopeRoutes(authedSystemGroup)
+	s.registerDocumentChatRoutes(authedSystemGroup)
+
 	// Initialize chat channels from database
 	if err := s.ChatAppService.initializeChatChannels(ctx); err != nil {
 		slog.Warn("failed to initialize chat channels", "error", err)
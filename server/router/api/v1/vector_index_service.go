@@ -0,0 +1,66 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/vectorindex"
+)
+
+// registerVectorIndexRoutes registers the admin API of the memo embedding index.
+func (s *APIV1Service) registerVectorIndexRoutes(group *echo.Group) {
+	if s.VectorIndex == nil {
+		return
+	}
+	index := group.Group("/vector-index", restAdminMiddleware)
+	index.GET("", s.GetVectorIndex)
+	index.POST("/rebuild", s.RebuildVectorIndex)
+	index.POST("/analyze", s.AnalyzeVectorIndex)
+}
+
+// GET /api/v1/system/vector-index returns the configured index type and
+// parameters, the vector indexes of the memo embeddings with their size, and
+// the time of the latest ANALYZE.
+func (s *APIV1Service) GetVectorIndex(c echo.Context) error {
+	status, err := s.VectorIndex.Status(c.Request().Context())
+	if err != nil {
+		return vectorIndexError(c, err, "failed to get vector index")
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+// POST /api/v1/system/vector-index/rebuild builds the configured index
+// concurrently, then drops the previous one. It returns once the index is
+// built; searches use the previous index meanwhile.
+func (s *APIV1Service) RebuildVectorIndex(c echo.Context) error {
+	status, err := s.VectorIndex.Rebuild(c.Request().Context())
+	if err != nil {
+		return vectorIndexError(c, err, "failed to rebuild vector index")
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+// POST /api/v1/system/vector-index/analyze refreshes the planner statistics
+// of the memo embeddings.
+func (s *APIV1Service) AnalyzeVectorIndex(c echo.Context) error {
+	status, err := s.VectorIndex.Analyze(c.Request().Context())
+	if err != nil {
+		return vectorIndexError(c, err, "failed to analyze vector index")
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+// vectorIndexError maps the errors of the vector index to responses.
+func vectorIndexError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, vectorindex.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, vectorindex.ErrBusy):
+		return restError(c, http.StatusConflict, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/webfetch"
+)
+
+// registerWebFetchRoutes registers the admin API of the web_fetch tool.
+func (s *APIV1Service) registerWebFetchRoutes(group *echo.Group) {
+	if s.WebFetch == nil {
+		return
+	}
+	webFetch := group.Group("/ai/web-fetch", restAdminMiddleware)
+	webFetch.GET("/policy", s.GetWebFetchPolicy)
+	webFetch.PUT("/policy", s.UpdateWebFetchPolicy)
+	webFetch.POST("/test", s.TestWebFetch)
+}
+
+// GET /api/v1/system/ai/web-fetch/policy.
+func (s *APIV1Service) GetWebFetchPolicy(c echo.Context) error {
+	policy, err := s.WebFetch.Policy(c.Request().Context())
+	if err != nil {
+		slog.Error("failed to get web fetch policy", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to get web fetch policy")
+	}
+	return c.JSON(http.StatusOK, policy)
+}
+
+// PUT /api/v1/system/ai/web-fetch/policy {"enabled": true, "allowed_domains": ["wikipedia.org"],
+// "denied_domains": [], "rate_limit_per_minute": 10, "max_bytes": 1048576, "timeout_seconds": 10,
+// "cache_ttl_seconds": 900}.
+// The policy replaces the current one; parrots get or lose the web_fetch tool
+// at their next request.
+func (s *APIV1Service) UpdateWebFetchPolicy(c echo.Context) error {
+	policy := &webfetch.Policy{}
+	if err := c.Bind(policy); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	saved, err := s.WebFetch.SavePolicy(c.Request().Context(), policy)
+	if errors.Is(err, webfetch.ErrInvalid) {
+		return restError(c, http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		slog.Error("failed to save web fetch policy", "error", err)
+		return restError(c, http.StatusInternalServerError, "failed to save web fetch policy")
+	}
+	return c.JSON(http.StatusOK, saved)
+}
+
+// POST /api/v1/system/ai/web-fetch/test {"url": "https://..."}.
+// Fetches a page as the web_fetch tool would for the current admin, to check
+// the policy; the fetch counts against their rate limit and is audited.
+func (s *APIV1Service) TestWebFetch(c echo.Context) error {
+	var body struct {
+		URL string `json:"url"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	page, err := s.WebFetch.Fetch(c.Request().Context(), restCurrentUser(c).ID, body.URL)
+	if err != nil {
+		if refused := webFetchError(c, err); refused {
+			return nil
+		}
+		// The site failed: its error is what admins test for
+		return restError(c, http.StatusBadGateway, err.Error())
+	}
+	return c.JSON(http.StatusOK, page)
+}
+
+// webFetchError writes the response of the fetches the fetcher refused,
+// reporting whether err is one.
+func webFetchError(c echo.Context, err error) bool {
+	switch {
+	case errors.Is(err, webfetch.ErrInvalid):
+		_ = restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, webfetch.ErrDisabled), errors.Is(err, webfetch.ErrDenied):
+		_ = restError(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, webfetch.ErrRateLimited):
+		_ = restError(c, http.StatusTooManyRequests, err.Error())
+	default:
+		return false
+	}
+	return true
+}
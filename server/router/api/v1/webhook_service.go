@@ -0,0 +1,261 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/hrygo/divinesense/plugin/webhook"
+	"github.com/hrygo/divinesense/server/service/schedule"
+	"github.com/hrygo/divinesense/store"
+)
+
+const (
+	// defaultWebhookDeliveries and maxWebhookDeliveries bound the deliveries
+	// listed at once.
+	defaultWebhookDeliveries = 50
+	maxWebhookDeliveries     = 200
+	// scheduleDueWindow is how far back the schedule.due job looks for
+	// schedules that started, so that a late or skipped run misses none; the
+	// event IDs keep them from being delivered twice.
+	scheduleDueWindow = 5 * time.Minute
+)
+
+// WebhookRequest is the body for creating or replacing a webhook endpoint.
+type WebhookRequest struct {
+	Name    string   `json:"name"`
+	URL     string   `json:"url"`
+	Events  []string `json:"events"`
+	Enabled *bool    `json:"enabled"`
+}
+
+// registerWebhookRoutes registers the API of the webhook endpoints of the
+// current user, and of the instance ones, which receive the events of all
+// users, for admins.
+func (s *APIV1Service) registerWebhookRoutes(group *echo.Group) {
+	if s.Webhooks == nil {
+		return
+	}
+	s.registerWebhookEndpointRoutes(group.Group("/webhooks"), func(c echo.Context) int32 { return restCurrentUser(c).ID })
+	s.registerWebhookEndpointRoutes(group.Group("/instance-webhooks", restAdminMiddleware), func(echo.Context) int32 { return 0 })
+}
+
+// registerWebhookEndpointRoutes registers the routes of the endpoints of the
+// owner of a request, a user or the instance (0).
+func (s *APIV1Service) registerWebhookEndpointRoutes(endpoints *echo.Group, owner func(echo.Context) int32) {
+	endpoints.GET("", func(c echo.Context) error { return s.listWebhooks(c, owner(c)) })
+	endpoints.POST("", func(c echo.Context) error { return s.createWebhook(c, owner(c)) })
+	endpoints.PUT("/:id", func(c echo.Context) error { return s.updateWebhook(c, owner(c)) })
+	endpoints.DELETE("/:id", func(c echo.Context) error { return s.deleteWebhook(c, owner(c)) })
+	endpoints.GET("/:id/deliveries", func(c echo.Context) error { return s.listWebhookDeliveries(c, owner(c)) })
+}
+
+// GET /api/v1/system/webhooks lists the endpoints of the current user, and
+// GET /api/v1/system/instance-webhooks the instance ones, with the events
+// they can subscribe to.
+func (s *APIV1Service) listWebhooks(c echo.Context, owner int32) error {
+	endpoints, err := s.Webhooks.List(c.Request().Context(), owner)
+	if err != nil {
+		return webhookError(c, err, "failed to list webhooks")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"webhooks": endpoints, "events": webhook.Events})
+}
+
+// POST /api/v1/system/webhooks {"name": "n8n", "url": "https://...",
+// "events": ["block.completed", "memo.created"]} creates an endpoint,
+// subscribed to all the events when none are given. The secret signing its
+// callbacks is only returned here.
+func (s *APIV1Service) createWebhook(c echo.Context, owner int32) error {
+	req := &WebhookRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	endpoint := req.endpoint()
+	endpoint.UserID = owner
+	created, secret, err := s.Webhooks.Create(c.Request().Context(), endpoint)
+	if err != nil {
+		return webhookError(c, err, "failed to create webhook")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"webhook": created, "secret": secret})
+}
+
+// PUT /api/v1/system/webhooks/:id replaces an endpoint, keeping its secret.
+func (s *APIV1Service) updateWebhook(c echo.Context, owner int32) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid webhook id")
+	}
+	req := &WebhookRequest{}
+	if err := c.Bind(req); err != nil {
+		return restError(c, http.StatusBadRequest, "invalid request body")
+	}
+	endpoint := req.endpoint()
+	endpoint.ID, endpoint.UserID = int32(id), owner
+	updated, err := s.Webhooks.Update(c.Request().Context(), endpoint)
+	if err != nil {
+		return webhookError(c, err, "failed to update webhook")
+	}
+	return c.JSON(http.StatusOK, updated)
+}
+
+// DELETE /api/v1/system/webhooks/:id deletes an endpoint with its deliveries.
+func (s *APIV1Service) deleteWebhook(c echo.Context, owner int32) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid webhook id")
+	}
+	if err := s.Webhooks.Delete(c.Request().Context(), owner, int32(id)); err != nil {
+		return webhookError(c, err, "failed to delete webhook")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GET /api/v1/system/webhooks/:id/deliveries?status=failed&limit=50 lists the
+// latest deliveries of an endpoint with the outcome of their last attempt.
+func (s *APIV1Service) listWebhookDeliveries(c echo.Context, owner int32) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return restError(c, http.StatusBadRequest, "invalid webhook id")
+	}
+	find := &webhook.FindDelivery{EndpointID: int32(id), Status: c.QueryParam("status"), Limit: defaultWebhookDeliveries}
+	if find.Status != "" && !slices.Contains([]string{webhook.StatusPending, webhook.StatusSucceeded, webhook.StatusFailed}, find.Status) {
+		return restError(c, http.StatusBadRequest, "invalid status")
+	}
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxWebhookDeliveries {
+			return restError(c, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxWebhookDeliveries))
+		}
+		find.Limit = limit
+	}
+	ctx := c.Request().Context()
+	if _, err := s.Webhooks.Get(ctx, owner, int32(id)); err != nil {
+		return webhookError(c, err, "failed to get webhook")
+	}
+	deliveries, err := s.Webhooks.Deliveries(ctx, find)
+	if err != nil {
+		return webhookError(c, err, "failed to list webhook deliveries")
+	}
+	return c.JSON(http.StatusOK, map[string]any{"deliveries": deliveries})
+}
+
+// endpoint returns the endpoint of a request, enabled unless it says otherwise.
+func (r *WebhookRequest) endpoint() *webhook.Endpoint {
+	endpoint := &webhook.Endpoint{Name: r.Name, URL: r.URL, Events: r.Events, Enabled: true}
+	if r.Enabled != nil {
+		endpoint.Enabled = *r.Enabled
+	}
+	return endpoint
+}
+
+// webhookError maps the errors of the webhook registry to responses.
+func webhookError(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, webhook.ErrInvalid):
+		return restError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, webhook.ErrNotFound):
+		return restError(c, http.StatusNotFound, err.Error())
+	}
+	slog.Error(msg, "error", err)
+	return restError(c, http.StatusInternalServerError, msg)
+}
+
+// publishBlockWebhook publishes the block.completed and block.error events of
+// the blocks whose status the block manager updates.
+func (s *AIService) publishBlockWebhook(ctx context.Context, block *store.AIBlock) {
+	var event string
+	switch block.Status {
+	case store.AIBlockStatusCompleted:
+		event = webhook.EventBlockCompleted
+	case store.AIBlockStatusError:
+		event = webhook.EventBlockError
+	default:
+		return
+	}
+	// The chat may be canceled as soon as the block is done
+	ctx = context.WithoutCancel(ctx)
+	conversations, err := s.Store.ListAIConversations(ctx, &store.FindAIConversation{ID: &block.ConversationID})
+	if err != nil || len(conversations) == 0 {
+		slog.Warn("webhook: failed to find the conversation of a block", "block_id", block.ID, "error", err)
+		return
+	}
+	conversation := conversations[0]
+	data := map[string]any{
+		"block_uid":        block.UID,
+		"conversation_id":  conversation.ID,
+		"conversation_uid": conversation.UID,
+		"round_number":     block.RoundNumber,
+		"mode":             string(block.Mode),
+		"status":           string(block.Status),
+		"model":            block.ModelVersion,
+	}
+	// The contents of locked conversations stay sealed
+	if !block.Locked {
+		data["content"] = block.AssistantContent
+	}
+	if block.TokenUsage != nil {
+		data["token_usage"] = block.TokenUsage
+	}
+	if err := s.Webhooks.Publish(ctx, event, conversation.CreatorID, event+":"+block.UID, data); err != nil {
+		slog.Warn("webhook: failed to publish block event", "block_id", block.ID, "event", event, "error", err)
+	}
+}
+
+// publishDueSchedules publishes the schedule.due events of the schedules,
+// recurring ones included, that started within scheduleDueWindow, for the
+// users whose endpoints, or an instance one, subscribe to them.
+func (s *APIV1Service) publishDueSchedules(ctx context.Context) error {
+	users, instance, err := s.Webhooks.Subscribers(ctx, webhook.EventScheduleDue)
+	if err != nil {
+		return err
+	}
+	if instance {
+		normal := store.Normal
+		all, err := s.Store.ListUsers(ctx, &store.FindUser{RowStatus: &normal})
+		if err != nil {
+			return fmt.Errorf("failed to list users: %w", err)
+		}
+		users = users[:0]
+		for _, user := range all {
+			users = append(users, user.ID)
+		}
+	}
+	now := time.Now()
+	from := now.Add(-scheduleDueWindow)
+	schedules := schedule.NewService(s.Store)
+	var errs []error
+	for _, userID := range users {
+		instances, err := schedules.FindSchedules(ctx, userID, from, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %d: %w", userID, err))
+			continue
+		}
+		for _, due := range instances {
+			if due.StartTs <= from.Unix() || due.StartTs > now.Unix() {
+				continue
+			}
+			eventID := fmt.Sprintf("%s:%s:%d", webhook.EventScheduleDue, due.UID, due.StartTs)
+			if err := s.Webhooks.Publish(ctx, webhook.EventScheduleDue, userID, eventID, map[string]any{
+				"uid":          due.UID,
+				"title":        due.Title,
+				"description":  due.Description,
+				"location":     due.Location,
+				"timezone":     due.Timezone,
+				"start_ts":     due.StartTs,
+				"end_ts":       due.EndTs,
+				"all_day":      due.AllDay,
+				"is_recurring": due.IsRecurring,
+			}); err != nil {
+				errs = append(errs, fmt.Errorf("schedule %s: %w", due.UID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
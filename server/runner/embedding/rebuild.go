@@ -0,0 +1,296 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/hrygo/divinesense/ai"
+	"github.com/hrygo/divinesense/plugin/embedindex"
+	"github.com/hrygo/divinesense/store"
+)
+
+// RecallK is the rank within which the recall verification must find
+// each sampled memo.
+const RecallK = 10
+
+// recallQueryRunes is the length of the memo opening used as the query of
+// the recall verification.
+const recallQueryRunes = 200
+
+// RebuildOptions configures a rebuild.
+type RebuildOptions struct {
+	Model       string  // Target embedding model
+	ActiveModel string  // Active model when no rebuild was swapped yet
+	BatchSize   int     // Memos per embedding request (default: 16)
+	SampleSize  int     // Memos of the recall verification (default: 50)
+	MinRecall   float64 // Recall@RecallK required to swap
+
+	// Progress is called with the rebuild after each batch.
+	Progress func(*embedindex.Rebuild)
+}
+
+// Rebuilder re-embeds the memo corpus into the shadow index of a target
+// model, verifies its recall and swaps it in.
+type Rebuilder struct {
+	store     *store.Store
+	rebuilds  embedindex.Store
+	embedding ai.EmbeddingService // Of the target model
+	texts     *Runner
+}
+
+// NewRebuilder creates a rebuilder; embedding embeds with the target model.
+func NewRebuilder(store *store.Store, rebuilds embedindex.Store, embedding ai.EmbeddingService) *Rebuilder {
+	return &Rebuilder{
+		store:     store,
+		rebuilds:  rebuilds,
+		embedding: embedding,
+		texts:     &Runner{store: store},
+	}
+}
+
+// Rebuild runs or resumes the rebuild to opts.Model. The active index keeps
+// serving searches until the swap. On error, the returned rebuild, if any,
+// holds the progress to resume from.
+func (b *Rebuilder) Rebuild(ctx context.Context, opts RebuildOptions) (*embedindex.Rebuild, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 16
+	}
+	if opts.SampleSize <= 0 {
+		opts.SampleSize = 50
+	}
+	active, err := b.rebuilds.ActiveModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if active == "" {
+		active = opts.ActiveModel
+	}
+	if opts.Model == "" || opts.Model == active {
+		return nil, fmt.Errorf("%w: %q is already the active model", embedindex.ErrInvalid, opts.Model)
+	}
+
+	// The target model must fit the index before anything is written
+	probe, err := b.embedding.Embed(ctx, "embedding dimension probe")
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed with %s: %w", opts.Model, err)
+	}
+	if len(probe) != store.EmbeddingDimensions {
+		return nil, fmt.Errorf("%w: %s embeds %d dimensions, the index holds %d",
+			embedindex.ErrInvalid, opts.Model, len(probe), store.EmbeddingDimensions)
+	}
+
+	rebuild, err := b.open(ctx, opts.Model, active)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.fill(ctx, rebuild, opts); err != nil {
+		return b.fail(ctx, rebuild, err)
+	}
+
+	recall, err := b.verify(ctx, opts.Model, opts.SampleSize)
+	if err != nil {
+		return b.fail(ctx, rebuild, err)
+	}
+	rebuild.Recall = recall
+	if recall < opts.MinRecall {
+		err := fmt.Errorf("%w: recall@%d %.2f < %.2f", embedindex.ErrLowRecall, RecallK, recall, opts.MinRecall)
+		rebuild.Status = embedindex.StatusRejected
+		rebuild.Error = err.Error()
+		if saved, saveErr := b.rebuilds.UpdateRebuild(ctx, rebuild); saveErr == nil {
+			rebuild = saved
+		}
+		return rebuild, err
+	}
+	if rebuild, err = b.rebuilds.UpdateRebuild(ctx, rebuild); err != nil {
+		return nil, err
+	}
+
+	swapped, err := b.rebuilds.SwapRebuild(ctx, rebuild.ID, opts.ActiveModel)
+	if err != nil {
+		return rebuild, err
+	}
+	slog.Info("embedding index swapped", "from", swapped.SourceModel, "to", swapped.TargetModel, "recall", recall)
+	return swapped, nil
+}
+
+// open resumes the open rebuild to model, or starts a new one on an empty
+// shadow index.
+func (b *Rebuilder) open(ctx context.Context, model, active string) (*embedindex.Rebuild, error) {
+	rebuild, err := b.rebuilds.FindOpenRebuild(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+	if rebuild != nil && rebuild.SourceModel != active {
+		// Another index was swapped in since; its embeddings may be stale
+		rebuild.Status = embedindex.StatusCanceled
+		if _, err := b.rebuilds.UpdateRebuild(ctx, rebuild); err != nil {
+			return nil, err
+		}
+		rebuild = nil
+	}
+	if rebuild != nil {
+		slog.Info("resuming embedding rebuild", "id", rebuild.ID, "model", model, "processed", rebuild.Processed)
+		rebuild.Status = embedindex.StatusRunning
+		rebuild.Error = ""
+		return rebuild, nil
+	}
+
+	// Leftovers of an earlier index of the model would pass as embedded
+	if err := b.rebuilds.DeleteEmbeddings(ctx, model); err != nil {
+		return nil, err
+	}
+	return b.rebuilds.CreateRebuild(ctx, &embedindex.Rebuild{
+		SourceModel: active,
+		TargetModel: model,
+		Status:      embedindex.StatusRunning,
+	})
+}
+
+// fill embeds the memos without a target embedding, saving the progress
+// after each batch.
+func (b *Rebuilder) fill(ctx context.Context, rebuild *embedindex.Rebuild, opts RebuildOptions) error {
+	if err := b.count(ctx, rebuild); err != nil {
+		return err
+	}
+	for {
+		if err := b.save(ctx, rebuild, opts.Progress); err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		memos, err := b.store.FindMemosWithoutEmbedding(ctx, &store.FindMemosWithoutEmbedding{
+			Model: opts.Model,
+			Limit: opts.BatchSize,
+		})
+		if err != nil {
+			return err
+		}
+		if len(memos) == 0 {
+			break
+		}
+
+		texts := make([]string, len(memos))
+		for i, m := range memos {
+			texts[i] = b.texts.buildMemoContentWithAttachments(ctx, m)
+		}
+		vectors, err := b.embedding.EmbedBatch(ctx, texts)
+		if err != nil {
+			return err
+		}
+		if len(vectors) != len(memos) {
+			return fmt.Errorf("embedded %d of %d memos", len(vectors), len(memos))
+		}
+		for i, m := range memos {
+			if _, err := b.store.UpsertMemoEmbedding(ctx, &store.MemoEmbedding{
+				MemoID:    m.ID,
+				Embedding: vectors[i],
+				Model:     opts.Model,
+			}); err != nil {
+				return err
+			}
+		}
+		rebuild.Processed += len(memos)
+		// Memos created meanwhile grow the corpus
+		rebuild.Total = max(rebuild.Total, rebuild.Processed)
+	}
+
+	if err := b.count(ctx, rebuild); err != nil {
+		return err
+	}
+	return b.save(ctx, rebuild, opts.Progress)
+}
+
+func (b *Rebuilder) count(ctx context.Context, rebuild *embedindex.Rebuild) error {
+	total, err := b.rebuilds.CountMemos(ctx)
+	if err != nil {
+		return err
+	}
+	processed, err := b.rebuilds.CountEmbeddings(ctx, rebuild.TargetModel)
+	if err != nil {
+		return err
+	}
+	rebuild.Total, rebuild.Processed = total, processed
+	return nil
+}
+
+func (b *Rebuilder) save(ctx context.Context, rebuild *embedindex.Rebuild, progress func(*embedindex.Rebuild)) error {
+	saved, err := b.rebuilds.UpdateRebuild(ctx, rebuild)
+	if err != nil {
+		return err
+	}
+	*rebuild = *saved
+	if progress != nil {
+		progress(rebuild)
+	}
+	return nil
+}
+
+// verify measures the recall of the shadow index: the opening of each
+// sampled memo, as a query, must retrieve the memo within the top RecallK
+// memos of its creator.
+func (b *Rebuilder) verify(ctx context.Context, model string, sampleSize int) (float64, error) {
+	ids, err := b.rebuilds.SampleMemos(ctx, sampleSize)
+	if err != nil {
+		return 0, err
+	}
+	sampled, hits := 0, 0
+	for _, id := range ids {
+		memo, err := b.store.GetMemo(ctx, &store.FindMemo{ID: &id})
+		if err != nil {
+			return 0, err
+		}
+		if memo == nil {
+			continue // Deleted meanwhile
+		}
+		vector, err := b.embedding.Embed(ctx, recallQuery(memo.Content))
+		if err != nil {
+			return 0, err
+		}
+		results, err := b.store.VectorSearch(ctx, &store.VectorSearchOptions{
+			UserID: memo.CreatorID,
+			Vector: vector,
+			Limit:  RecallK,
+			Model:  model,
+		})
+		if err != nil {
+			return 0, err
+		}
+		sampled++
+		for _, result := range results {
+			if result.Memo.ID == memo.ID {
+				hits++
+				break
+			}
+		}
+	}
+	if sampled == 0 {
+		return 1, nil
+	}
+	return float64(hits) / float64(sampled), nil
+}
+
+// fail records err on the rebuild. Interrupted rebuilds stay running, to be
+// resumed.
+func (b *Rebuilder) fail(ctx context.Context, rebuild *embedindex.Rebuild, err error) (*embedindex.Rebuild, error) {
+	if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		rebuild.Status = embedindex.StatusFailed
+		rebuild.Error = err.Error()
+	}
+	if saved, saveErr := b.rebuilds.UpdateRebuild(context.WithoutCancel(ctx), rebuild); saveErr == nil {
+		rebuild = saved
+	} else {
+		slog.Error("failed to save embedding rebuild", "id", rebuild.ID, "error", saveErr)
+	}
+	return rebuild, err
+}
+
+func recallQuery(content string) string {
+	runes := []rune(content)
+	if len(runes) > recallQueryRunes {
+		return string(runes[:recallQueryRunes])
+	}
+	return content
+}
@@ -0,0 +1,295 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/internal/profile"
+	"github.com/hrygo/divinesense/plugin/embedindex"
+	"github.com/hrygo/divinesense/store"
+)
+
+// fakeCorpus is the memo and embedding tables, as a store driver and a
+// rebuild store.
+type fakeCorpus struct {
+	store.Driver
+
+	memos      []*store.Memo
+	embeddings map[string]map[int32][]float32 // model -> memo ID -> vector
+	rebuilds   []*embedindex.Rebuild
+}
+
+func newFakeCorpus(n int) *fakeCorpus {
+	c := &fakeCorpus{embeddings: map[string]map[int32][]float32{}}
+	for i := 1; i <= n; i++ {
+		c.memos = append(c.memos, &store.Memo{
+			ID:        int32(i),
+			CreatorID: 1,
+			Content:   fmt.Sprintf("memo number %d", i),
+			RowStatus: store.Normal,
+			UpdatedTs: int64(i) * 100,
+		})
+	}
+	return c
+}
+
+func (c *fakeCorpus) AgentStatsStore() store.AgentStatsStore       { return nil }
+func (c *fakeCorpus) SecurityAuditStore() store.SecurityAuditStore { return nil }
+
+func (c *fakeCorpus) ListAttachments(_ context.Context, _ *store.FindAttachment) ([]*store.Attachment, error) {
+	return nil, nil
+}
+
+// ListMemos supports the ID, IDList and updated_ts filters.
+func (c *fakeCorpus) ListMemos(_ context.Context, find *store.FindMemo) ([]*store.Memo, error) {
+	var since int64
+	for _, f := range find.Filters {
+		if _, err := fmt.Sscanf(f, "updated_ts >= %d", &since); err != nil {
+			return nil, err
+		}
+	}
+	memos := []*store.Memo{}
+	for _, memo := range c.memos {
+		if (find.ID == nil || memo.ID == *find.ID) &&
+			(len(find.IDList) == 0 || slices.Contains(find.IDList, memo.ID)) &&
+			memo.UpdatedTs >= since {
+			memos = append(memos, memo)
+		}
+	}
+	return memos, nil
+}
+
+func (c *fakeCorpus) FindMemosWithoutEmbedding(_ context.Context, find *store.FindMemosWithoutEmbedding) ([]*store.Memo, error) {
+	memos := []*store.Memo{}
+	for _, memo := range c.memos {
+		if _, ok := c.embeddings[find.Model][memo.ID]; !ok && len(memos) < find.Limit {
+			memos = append(memos, memo)
+		}
+	}
+	return memos, nil
+}
+
+func (c *fakeCorpus) UpsertMemoEmbedding(_ context.Context, embedding *store.MemoEmbedding) (*store.MemoEmbedding, error) {
+	if c.embeddings[embedding.Model] == nil {
+		c.embeddings[embedding.Model] = map[int32][]float32{}
+	}
+	c.embeddings[embedding.Model][embedding.MemoID] = embedding.Embedding
+	return embedding, nil
+}
+
+func (c *fakeCorpus) VectorSearch(_ context.Context, opts *store.VectorSearchOptions) ([]*store.MemoWithScore, error) {
+	results := []*store.MemoWithScore{}
+	for _, memo := range c.memos {
+		if vector, ok := c.embeddings[opts.Model][memo.ID]; ok && memo.CreatorID == opts.UserID {
+			var score float32
+			for i := range vector {
+				score += vector[i] * opts.Vector[i]
+			}
+			results = append(results, &store.MemoWithScore{Memo: memo, Score: score})
+		}
+	}
+	slices.SortStableFunc(results, func(a, b *store.MemoWithScore) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		}
+		return 0
+	})
+	return results[:min(opts.Limit, len(results))], nil
+}
+
+func (c *fakeCorpus) ActiveModel(_ context.Context) (string, error) {
+	for _, rebuild := range slices.Backward(c.rebuilds) {
+		if rebuild.Status == embedindex.StatusSwapped {
+			return rebuild.TargetModel, nil
+		}
+	}
+	return "", nil
+}
+
+func (c *fakeCorpus) FindOpenRebuild(_ context.Context, model string) (*embedindex.Rebuild, error) {
+	for _, rebuild := range slices.Backward(c.rebuilds) {
+		if rebuild.TargetModel == model && rebuild.Status.Open() {
+			saved := *rebuild
+			return &saved, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *fakeCorpus) CreateRebuild(_ context.Context, rebuild *embedindex.Rebuild) (*embedindex.Rebuild, error) {
+	saved := *rebuild
+	saved.ID = int32(len(c.rebuilds) + 1)
+	c.rebuilds = append(c.rebuilds, &saved)
+	created := saved
+	return &created, nil
+}
+
+func (c *fakeCorpus) UpdateRebuild(_ context.Context, rebuild *embedindex.Rebuild) (*embedindex.Rebuild, error) {
+	if rebuild.ID < 1 || int(rebuild.ID) > len(c.rebuilds) || !c.rebuilds[rebuild.ID-1].Status.Open() {
+		return nil, embedindex.ErrNotFound
+	}
+	saved := *rebuild
+	c.rebuilds[rebuild.ID-1] = &saved
+	updated := saved
+	return &updated, nil
+}
+
+func (c *fakeCorpus) SwapRebuild(ctx context.Context, id int32, activeModel string) (*embedindex.Rebuild, error) {
+	active, _ := c.ActiveModel(ctx)
+	if active == "" {
+		active = activeModel
+	}
+	rebuild := c.rebuilds[id-1]
+	if !rebuild.Status.Open() || rebuild.SourceModel != active {
+		return nil, embedindex.ErrInvalid
+	}
+	rebuild.Status = embedindex.StatusSwapped
+	swapped := *rebuild
+	return &swapped, nil
+}
+
+func (c *fakeCorpus) CountMemos(_ context.Context) (int, error) {
+	return len(c.memos), nil
+}
+
+func (c *fakeCorpus) CountEmbeddings(_ context.Context, model string) (int, error) {
+	return len(c.embeddings[model]), nil
+}
+
+func (c *fakeCorpus) DeleteEmbeddings(_ context.Context, model string) error {
+	delete(c.embeddings, model)
+	return nil
+}
+
+func (c *fakeCorpus) SampleMemos(_ context.Context, n int) ([]int32, error) {
+	ids := []int32{}
+	for _, memo := range c.memos[:min(n, len(c.memos))] {
+		ids = append(ids, memo.ID)
+	}
+	return ids, nil
+}
+
+// hashEmbedding embeds each text as a one-hot vector; queries match the
+// documents they equal.
+type hashEmbedding struct {
+	dimensions int
+	batches    int
+	failBatch  int  // 1-based batch to fail, if any
+	blindQuery bool // Embed every query the same way
+}
+
+func (e *hashEmbedding) vector(text string) []float32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(text))
+	vector := make([]float32, e.dimensions)
+	vector[int(h.Sum32())%e.dimensions] = 1
+	return vector
+}
+
+func (e *hashEmbedding) Embed(_ context.Context, text string) ([]float32, error) {
+	if e.blindQuery {
+		text = ""
+	}
+	return e.vector(text), nil
+}
+
+func (e *hashEmbedding) EmbedBatch(_ context.Context, texts []string) ([][]float32, error) {
+	e.batches++
+	if e.batches == e.failBatch {
+		return nil, errors.New("provider unavailable")
+	}
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.vector(text)
+	}
+	return vectors, nil
+}
+
+func (e *hashEmbedding) Dimensions() int {
+	return e.dimensions
+}
+
+func TestRebuilder_ResumeAndSwap(t *testing.T) {
+	ctx := context.Background()
+	corpus := newFakeCorpus(30)
+	// Leftovers of an earlier index of the target model
+	corpus.embeddings["new-model"] = map[int32][]float32{1: make([]float32, store.EmbeddingDimensions)}
+	embedding := &hashEmbedding{dimensions: store.EmbeddingDimensions, failBatch: 2}
+	rebuilder := NewRebuilder(store.New(corpus, &profile.Profile{}), corpus, embedding)
+	opts := RebuildOptions{Model: "new-model", ActiveModel: "old-model", BatchSize: 8, MinRecall: 0.9}
+
+	// The provider fails on the second batch
+	rebuild, err := rebuilder.Rebuild(ctx, opts)
+	require.Error(t, err)
+	assert.Equal(t, embedindex.StatusFailed, rebuild.Status)
+	assert.Equal(t, 8, rebuild.Processed)
+	assert.Equal(t, 30, rebuild.Total)
+	assert.Equal(t, embedding.vector("memo number 1"), corpus.embeddings["new-model"][1], "the leftovers are dropped")
+	active, _ := corpus.ActiveModel(ctx)
+	assert.Empty(t, active)
+
+	// Running it again resumes the rebuild
+	var progress []int
+	opts.Progress = func(rebuild *embedindex.Rebuild) { progress = append(progress, rebuild.Processed) }
+	rebuild, err = rebuilder.Rebuild(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), rebuild.ID)
+	assert.Equal(t, embedindex.StatusSwapped, rebuild.Status)
+	assert.Equal(t, []int{8, 16, 24, 30, 30}, progress)
+	assert.InDelta(t, 1.0, rebuild.Recall, 0.001)
+	assert.Len(t, corpus.embeddings["new-model"], 30)
+	active, _ = corpus.ActiveModel(ctx)
+	assert.Equal(t, "new-model", active)
+
+	// The swapped model is the active one
+	_, err = rebuilder.Rebuild(ctx, opts)
+	assert.ErrorIs(t, err, embedindex.ErrInvalid)
+}
+
+func TestRebuilder_LowRecall(t *testing.T) {
+	ctx := context.Background()
+	corpus := newFakeCorpus(30)
+	embedding := &hashEmbedding{dimensions: store.EmbeddingDimensions, blindQuery: true}
+	rebuilder := NewRebuilder(store.New(corpus, &profile.Profile{}), corpus, embedding)
+	opts := RebuildOptions{Model: "new-model", ActiveModel: "old-model", MinRecall: 0.9}
+
+	rebuild, err := rebuilder.Rebuild(ctx, opts)
+	assert.ErrorIs(t, err, embedindex.ErrLowRecall)
+	assert.Equal(t, embedindex.StatusRejected, rebuild.Status)
+	assert.Less(t, rebuild.Recall, 0.9)
+	active, _ := corpus.ActiveModel(ctx)
+	assert.Empty(t, active, "the active index is kept")
+
+	// A lower threshold swaps the rebuilt index without embedding it again
+	batches := embedding.batches
+	opts.MinRecall = 0
+	rebuild, err = rebuilder.Rebuild(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, embedindex.StatusSwapped, rebuild.Status)
+	assert.Equal(t, batches, embedding.batches)
+}
+
+func TestRebuilder_Invalid(t *testing.T) {
+	ctx := context.Background()
+	corpus := newFakeCorpus(3)
+	s := store.New(corpus, &profile.Profile{})
+
+	_, err := NewRebuilder(s, corpus, &hashEmbedding{dimensions: store.EmbeddingDimensions}).
+		Rebuild(ctx, RebuildOptions{Model: "old-model", ActiveModel: "old-model"})
+	assert.ErrorIs(t, err, embedindex.ErrInvalid)
+
+	_, err = NewRebuilder(s, corpus, &hashEmbedding{dimensions: 768}).
+		Rebuild(ctx, RebuildOptions{Model: "small-model", ActiveModel: "old-model"})
+	assert.ErrorIs(t, err, embedindex.ErrInvalid)
+	assert.Empty(t, corpus.rebuilds, "nothing is written for models that do not fit the index")
+}
@@ -0,0 +1,145 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hrygo/divinesense/store"
+)
+
+// ErrReindexRunning is returned when a reindex is requested while another
+// one is running.
+var ErrReindexRunning = errors.New("a reindex is already running")
+
+// ReindexProgress reports the progress of a reindex.
+type ReindexProgress struct {
+	// Since is the update time from which memos are reindexed; 0 for all.
+	Since     int64 `json:"since"`
+	Running   bool  `json:"running"`
+	Total     int   `json:"total"`
+	Processed int   `json:"processed"`
+	Failed    int   `json:"failed"`
+	// Model is the embedding model the memos are reindexed with.
+	Model      string `json:"model,omitempty"`
+	StartedTs  int64  `json:"started_ts"`
+	FinishedTs int64  `json:"finished_ts,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ReindexAll embeds all the memos again.
+func (r *Runner) ReindexAll() (*ReindexProgress, error) {
+	return r.ReindexSince(0)
+}
+
+// ReindexSince embeds again the memos updated at or after since (unix
+// seconds), replacing their embeddings in the active index. The reindex runs
+// in the background of Run; Progress reports it.
+func (r *Runner) ReindexSince(since int64) (*ReindexProgress, error) {
+	if since < 0 {
+		return nil, fmt.Errorf("invalid reindex start time %d", since)
+	}
+	r.mu.Lock()
+	if r.reindex != nil && r.reindex.Running {
+		r.mu.Unlock()
+		return nil, ErrReindexRunning
+	}
+	r.reindex = &ReindexProgress{Since: since, Running: true, StartedTs: time.Now().Unix()}
+	r.pending = true
+	progress := *r.reindex
+	r.mu.Unlock()
+
+	r.signal()
+	return &progress, nil
+}
+
+// Progress returns the progress of the latest reindex, or nil.
+func (r *Runner) Progress() *ReindexProgress {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.reindex == nil {
+		return nil
+	}
+	progress := *r.reindex
+	return &progress
+}
+
+// runReindex runs the requested reindex, if any.
+func (r *Runner) runReindex(ctx context.Context) {
+	r.mu.Lock()
+	if !r.pending {
+		r.mu.Unlock()
+		return
+	}
+	r.pending = false
+	since := r.reindex.Since
+	r.mu.Unlock()
+
+	err := r.reindexSince(ctx, since)
+	r.update(func(p *ReindexProgress) {
+		p.Running = false
+		p.FinishedTs = time.Now().Unix()
+		if err != nil {
+			p.Error = err.Error()
+		}
+	})
+	progress := r.Progress()
+	if err != nil {
+		slog.Error("memo reindex failed", "since", since, "processed", progress.Processed, "error", err)
+		return
+	}
+	slog.Info("memo reindex completed", "since", since, "total", progress.Total, "failed", progress.Failed)
+}
+
+func (r *Runner) reindexSince(ctx context.Context, since int64) error {
+	normal := store.Normal
+	find := &store.FindMemo{RowStatus: &normal, ExcludeContent: true, OrderByTimeAsc: true}
+	if since > 0 {
+		find.Filters = []string{fmt.Sprintf("updated_ts >= %d", since)}
+	}
+	memos, err := r.store.ListMemos(ctx, find)
+	if err != nil {
+		return fmt.Errorf("failed to list memos to reindex: %w", err)
+	}
+	ids := make([]int32, len(memos))
+	for i, m := range memos {
+		ids[i] = m.ID
+	}
+
+	model := r.store.EmbeddingModel()
+	r.update(func(p *ReindexProgress) {
+		p.Total = len(ids)
+		p.Model = model
+	})
+	for i := 0; i < len(ids); i += r.batchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch := ids[i:min(i+r.batchSize, len(ids))]
+		memos, err := r.listMemos(ctx, batch)
+		if err == nil {
+			err = r.processBatch(ctx, memos, model)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			slog.Warn("failed to reindex memos", "error", err)
+		}
+		r.update(func(p *ReindexProgress) {
+			p.Processed += len(batch)
+			if err != nil {
+				p.Failed += len(batch)
+			}
+		})
+	}
+	return nil
+}
+
+func (r *Runner) update(fn func(*ReindexProgress)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(r.reindex)
+}
@@ -0,0 +1,86 @@
+package embedding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/internal/profile"
+	"github.com/hrygo/divinesense/store"
+)
+
+func TestRunner_ReindexSince(t *testing.T) {
+	ctx := context.Background()
+	corpus := newFakeCorpus(20)
+	stale := make([]float32, store.EmbeddingDimensions)
+	corpus.embeddings[store.DefaultEmbeddingModel] = map[int32][]float32{}
+	for _, memo := range corpus.memos {
+		corpus.embeddings[store.DefaultEmbeddingModel][memo.ID] = stale
+	}
+	embedding := &hashEmbedding{dimensions: store.EmbeddingDimensions, failBatch: 2}
+	runner := NewRunner(store.New(corpus, &profile.Profile{}), embedding)
+	assert.Nil(t, runner.Progress())
+
+	// Memos 6 to 20 were updated since 600
+	progress, err := runner.ReindexSince(600)
+	require.NoError(t, err)
+	assert.True(t, progress.Running)
+	_, err = runner.ReindexAll()
+	assert.ErrorIs(t, err, ErrReindexRunning)
+
+	runner.runReindex(ctx)
+	progress = runner.Progress()
+	assert.False(t, progress.Running)
+	assert.Empty(t, progress.Error)
+	assert.Equal(t, 15, progress.Total)
+	assert.Equal(t, 15, progress.Processed)
+	assert.Equal(t, 7, progress.Failed, "the second batch failed")
+	assert.Equal(t, store.DefaultEmbeddingModel, progress.Model)
+	assert.NotZero(t, progress.FinishedTs)
+
+	embeddings := corpus.embeddings[store.DefaultEmbeddingModel]
+	assert.Equal(t, stale, embeddings[5], "not updated since")
+	assert.Equal(t, embedding.vector("memo number 6"), embeddings[6])
+	assert.Equal(t, embedding.vector("memo number 13"), embeddings[13])
+	assert.Equal(t, stale, embeddings[14], "in the failed batch")
+	assert.Equal(t, stale, embeddings[20], "in the failed batch")
+
+	// Another reindex may start once finished
+	_, err = runner.ReindexAll()
+	require.NoError(t, err)
+	runner.runReindex(ctx)
+	assert.Equal(t, 20, runner.Progress().Total)
+	assert.Zero(t, runner.Progress().Failed)
+	assert.Equal(t, embedding.vector("memo number 14"), embeddings[14])
+}
+
+func TestRunner_MemoChanged(t *testing.T) {
+	ctx := context.Background()
+	corpus := newFakeCorpus(3)
+	embedding := &hashEmbedding{dimensions: store.EmbeddingDimensions}
+	s := store.New(corpus, &profile.Profile{})
+	runner := NewRunner(s, embedding)
+
+	corpus.memos[1].Content = "edited"
+	corpus.memos[2].RowStatus = store.Archived
+	runner.MemoChanged(2)
+	runner.MemoChanged(3)
+	runner.MemoChanged(2)
+	select {
+	case <-runner.wake:
+	default:
+		t.Fatal("Run was not woken up")
+	}
+
+	runner.processChangedMemos(ctx)
+	embeddings := corpus.embeddings[store.DefaultEmbeddingModel]
+	assert.Len(t, embeddings, 1, "archived memos are not embedded")
+	assert.Equal(t, embedding.vector("edited"), embeddings[2])
+	assert.Equal(t, 1, embedding.batches)
+
+	// Changes are processed once
+	runner.processChangedMemos(ctx)
+	assert.Equal(t, 1, embedding.batches)
+}
@@ -4,18 +4,29 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/hrygo/divinesense/ai"
 	"github.com/hrygo/divinesense/store"
 )
 
+// Runner embeds the memos into the active index: periodically the memos
+// without an embedding, on demand the changed memos (see MemoChanged) and
+// the reindexed ones (see ReindexSince).
 type Runner struct {
 	embeddingService ai.EmbeddingService
 	store            *store.Store
-	model            string
 	interval         time.Duration
 	batchSize        int
+
+	// wake signals Run that memos changed or a reindex was requested
+	wake chan struct{}
+
+	mu      sync.Mutex
+	changed map[int32]struct{}
+	reindex *ReindexProgress // Latest reindex, nil before the first
+	pending bool             // Reindex requested, not started by Run yet
 }
 
 // NewRunner creates a vector embedding runner.
@@ -27,7 +38,8 @@ func NewRunner(store *store.Store, embeddingService ai.EmbeddingService) *Runner
 		embeddingService: embeddingService,
 		interval:         2 * time.Minute,
 		batchSize:        8,
-		model:            "BAAI/bge-m3",
+		wake:             make(chan struct{}, 1),
+		changed:          map[int32]struct{}{},
 	}
 }
 
@@ -43,6 +55,9 @@ func (r *Runner) Run(ctx context.Context) {
 		select {
 		case <-ticker.C:
 			r.processNewMemos(ctx)
+		case <-r.wake:
+			r.processChangedMemos(ctx)
+			r.runReindex(ctx)
 		case <-ctx.Done():
 			slog.Info("embedding runner stopped")
 			return
@@ -56,8 +71,11 @@ func (r *Runner) RunOnce(ctx context.Context) {
 }
 
 func (r *Runner) processNewMemos(ctx context.Context) {
+	// Fill the active index; rebuilds fill the shadow ones
+	model := r.store.EmbeddingModel()
+
 	// Find memos without embeddings
-	memos, err := r.findMemosWithoutEmbedding(ctx)
+	memos, err := r.findMemosWithoutEmbedding(ctx, model)
 	if err != nil {
 		slog.Error("failed to find memos without embedding", "error", err)
 		return
@@ -86,7 +104,7 @@ func (r *Runner) processNewMemos(ctx context.Context) {
 		}
 		batch := memos[i:end]
 
-		if err := r.processBatch(ctx, batch); err != nil {
+		if err := r.processBatch(ctx, batch, model); err != nil {
 			slog.Error("failed to process batch", "error", err)
 			continue
 		}
@@ -94,14 +112,79 @@ func (r *Runner) processNewMemos(ctx context.Context) {
 	}
 }
 
-func (r *Runner) findMemosWithoutEmbedding(ctx context.Context) ([]*store.Memo, error) {
+// MemoChanged implements store.MemoWatcher: the memo is embedded again by
+// Run, so that edits do not leave a stale embedding.
+func (r *Runner) MemoChanged(memoID int32) {
+	r.mu.Lock()
+	r.changed[memoID] = struct{}{}
+	r.mu.Unlock()
+	r.signal()
+}
+
+func (r *Runner) signal() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (r *Runner) processChangedMemos(ctx context.Context) {
+	r.mu.Lock()
+	ids := make([]int32, 0, len(r.changed))
+	for id := range r.changed {
+		ids = append(ids, id)
+	}
+	r.changed = map[int32]struct{}{}
+	r.mu.Unlock()
+	if len(ids) == 0 {
+		return
+	}
+
+	model := r.store.EmbeddingModel()
+	for i := 0; i < len(ids); i += r.batchSize {
+		if ctx.Err() != nil {
+			return
+		}
+		memos, err := r.listMemos(ctx, ids[i:min(i+r.batchSize, len(ids))])
+		if err != nil {
+			slog.Error("failed to list changed memos", "error", err)
+			continue
+		}
+		if len(memos) == 0 {
+			continue
+		}
+		if err := r.processBatch(ctx, memos, model); err != nil {
+			slog.Error("failed to embed changed memos", "error", err)
+			continue
+		}
+		slog.Debug("changed memos embedded", "count", len(memos))
+	}
+}
+
+// listMemos returns the memos to embed among ids: normal memos with content.
+func (r *Runner) listMemos(ctx context.Context, ids []int32) ([]*store.Memo, error) {
+	normal := store.Normal
+	memos, err := r.store.ListMemos(ctx, &store.FindMemo{IDList: ids, RowStatus: &normal})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*store.Memo, 0, len(memos))
+	for _, m := range memos {
+		if m.RowStatus == store.Normal && m.Content != "" {
+			list = append(list, m)
+		}
+	}
+	return list, nil
+}
+
+func (r *Runner) findMemosWithoutEmbedding(ctx context.Context, model string) ([]*store.Memo, error) {
 	return r.store.FindMemosWithoutEmbedding(ctx, &store.FindMemosWithoutEmbedding{
-		Model: r.model,
+		Model: model,
 		Limit: r.batchSize * 20, // Fetch more data, but process in small batches
 	})
 }
 
-func (r *Runner) processBatch(ctx context.Context, memos []*store.Memo) error {
+func (r *Runner) processBatch(ctx context.Context, memos []*store.Memo, model string) error {
 	// Check context before processing batch
 	select {
 	case <-ctx.Done():
@@ -121,13 +204,23 @@ func (r *Runner) processBatch(ctx context.Context, memos []*store.Memo) error {
 	if err != nil {
 		return err
 	}
+	if r.store.EmbeddingModel() != model {
+		return fmt.Errorf("embedding model swapped from %s during the batch", model)
+	}
+
+	if len(vectors) != len(memos) {
+		return fmt.Errorf("embedded %d of %d memos", len(vectors), len(memos))
+	}
 
 	// Store vectors
+	now := time.Now().Unix()
 	for i, m := range memos {
 		_, err := r.store.UpsertMemoEmbedding(ctx, &store.MemoEmbedding{
 			MemoID:    m.ID,
 			Embedding: vectors[i],
-			Model:     r.model,
+			Model:     model,
+			CreatedTs: now,
+			UpdatedTs: now,
 		})
 		if err != nil {
 			slog.Error("failed to upsert embedding", "memoID", m.ID, "error", err)
@@ -100,7 +100,7 @@ func TestNewRunner(t *testing.T) {
 	assert.Equal(t, mockService, runner.embeddingService)
 	assert.Equal(t, 2*time.Minute, runner.interval)
 	assert.Equal(t, 8, runner.batchSize)
-	assert.Equal(t, "BAAI/bge-m3", runner.model)
+	assert.Equal(t, store.DefaultEmbeddingModel, runner.store.EmbeddingModel())
 }
 
 // TestRunnerProcessBatch_EmptyBatch tests empty batch handling.
@@ -112,7 +112,7 @@ func TestRunnerProcessBatch_EmptyBatch(t *testing.T) {
 	runner := NewRunner(s, mockSvc)
 
 	// Empty batch should not cause panics
-	err := runner.processBatch(ctx, []*store.Memo{})
+	err := runner.processBatch(ctx, []*store.Memo{}, store.DefaultEmbeddingModel)
 	assert.NoError(t, err)
 }
 
@@ -127,7 +127,7 @@ func TestRunnerProcessBatch_EmbeddingFailure(t *testing.T) {
 	runner := NewRunner(s, mockSvc)
 
 	// Should return error when embedding service fails
-	err := runner.processBatch(ctx, []*store.Memo{{ID: 1, Content: "test"}})
+	err := runner.processBatch(ctx, []*store.Memo{{ID: 1, Content: "test"}}, store.DefaultEmbeddingModel)
 	assert.Error(t, err)
 }
 
@@ -217,9 +217,9 @@ func TestRunnerWithDifferentModels(t *testing.T) {
 			mockSvc := newMockEmbeddingService(1024)
 			s := &store.Store{}
 			runner := NewRunner(s, mockSvc)
-			runner.model = model
+			s.SetEmbeddingModel(model)
 
-			assert.Equal(t, model, runner.model)
+			assert.Equal(t, model, runner.store.EmbeddingModel())
 		})
 	}
 }
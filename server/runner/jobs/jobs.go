@@ -0,0 +1,300 @@
+// Package jobs runs the periodic background jobs of the server on cron
+// schedules, each run once across the replicas of a deployment.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hrygo/divinesense/plugin/scheduler"
+)
+
+var (
+	// ErrInvalid is returned when registering an invalid job.
+	ErrInvalid = errors.New("invalid job")
+	// ErrNotFound is returned when triggering an unknown job.
+	ErrNotFound = errors.New("job not found")
+	// ErrRunning is returned when triggering a job already running here.
+	ErrRunning = errors.New("job already running")
+)
+
+// Trigger is what started a run.
+type Trigger string
+
+const (
+	TriggerSchedule Trigger = "schedule"
+	TriggerManual   Trigger = "manual"
+)
+
+// Outcome is how a run ended.
+type Outcome string
+
+const (
+	OutcomeSucceeded Outcome = "succeeded"
+	OutcomeFailed    Outcome = "failed"
+	// OutcomeSkipped is a run that did not start, the job running already
+	// here or on another replica.
+	OutcomeSkipped Outcome = "skipped"
+)
+
+// Job is a periodic background job.
+type Job struct {
+	// Name identifies the job, e.g. geek-workspace-gc; it keys the lock
+	// shared by the replicas.
+	Name        string
+	Description string
+	// Schedule is a cron expression of 5 fields, or 6 with seconds, in the
+	// local time zone.
+	Schedule string
+	// Jitter delays each scheduled run by a random duration up to Jitter, so
+	// that the jobs of a schedule do not all start at once.
+	Jitter time.Duration
+	// Timeout bounds each run; 0 for no bound.
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+// Result is the result of a run of a job.
+type Result struct {
+	Trigger    Trigger `json:"trigger"`
+	Outcome    Outcome `json:"outcome"`
+	StartedTs  int64   `json:"started_ts"`
+	FinishedTs int64   `json:"finished_ts"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// Status is the state of a job.
+type Status struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Schedule    string `json:"schedule"`
+	// NextRunTs is the next scheduled run, before jitter; 0 until the
+	// scheduler runs.
+	NextRunTs int64 `json:"next_run_ts"`
+	Running   bool  `json:"running"`
+	// LastRun is nil for jobs that never ran since the server started.
+	LastRun *Result `json:"last_run"`
+}
+
+// Locker holds the lock of a job while it runs, so that a single replica
+// runs it at a time.
+type Locker interface {
+	// TryLock takes the lock of a job without waiting; ok is false when
+	// another replica holds it. unlock releases a lock taken.
+	TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error)
+}
+
+// Scheduler runs jobs on their schedules and on demand.
+type Scheduler struct {
+	// locker is nil for single-instance deployments.
+	locker Locker
+
+	mu   sync.Mutex
+	jobs map[string]*entry
+	// ctx is the context of manual runs, canceled with the one of Run.
+	ctx context.Context
+}
+
+// entry is a registered job.
+type entry struct {
+	job      *Job
+	schedule *scheduler.Schedule
+	running  atomic.Bool
+	// next and last are guarded by the mutex of the scheduler.
+	next time.Time
+	last *Result
+}
+
+// New creates a scheduler; a nil locker runs the jobs on every replica.
+func New(locker Locker) *Scheduler {
+	return &Scheduler{
+		locker: locker,
+		jobs:   make(map[string]*entry),
+		ctx:    context.Background(),
+	}
+}
+
+// Register adds a job. Must be called before Run.
+func (s *Scheduler) Register(job *Job) error {
+	if strings.TrimSpace(job.Name) == "" || job.Run == nil {
+		return fmt.Errorf("%w: a name and a run function are required", ErrInvalid)
+	}
+	if job.Jitter < 0 || job.Timeout < 0 {
+		return fmt.Errorf("%w: negative jitter or timeout", ErrInvalid)
+	}
+	schedule, err := scheduler.ParseCronExpression(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalid, job.Name, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[job.Name]; ok {
+		return fmt.Errorf("%w: %s registered twice", ErrInvalid, job.Name)
+	}
+	s.jobs[job.Name] = &entry{job: job, schedule: schedule}
+	return nil
+}
+
+// Run runs the jobs on their schedules until ctx is canceled.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	s.ctx = ctx
+	entries := make([]*entry, 0, len(s.jobs))
+	for _, e := range s.jobs {
+		entries = append(entries, e)
+	}
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, e := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, e)
+		}()
+	}
+	<-ctx.Done()
+	wg.Wait()
+}
+
+// List returns the state of the jobs, by name.
+func (s *Scheduler) List() []*Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]*Status, 0, len(s.jobs))
+	for _, e := range s.jobs {
+		status := &Status{
+			Name:        e.job.Name,
+			Description: e.job.Description,
+			Schedule:    e.job.Schedule,
+			Running:     e.running.Load(),
+		}
+		if !e.next.IsZero() {
+			status.NextRunTs = e.next.Unix()
+		}
+		if e.last != nil {
+			last := *e.last
+			status.LastRun = &last
+		}
+		statuses = append(statuses, status)
+	}
+	slices.SortFunc(statuses, func(a, b *Status) int { return strings.Compare(a.Name, b.Name) })
+	return statuses
+}
+
+// Trigger starts a run of a job now, in the background.
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.Lock()
+	e, ok := s.jobs[name]
+	ctx := s.ctx
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if !e.running.CompareAndSwap(false, true) {
+		return fmt.Errorf("%w: %s", ErrRunning, name)
+	}
+	go s.execute(ctx, e, TriggerManual)
+	return nil
+}
+
+// loop runs a job on its schedule until ctx is canceled.
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	for {
+		next := e.schedule.Next(time.Now())
+		if next.IsZero() {
+			slog.Warn("job schedule never fires", "job", e.job.Name, "schedule", e.job.Schedule)
+			return
+		}
+		s.mu.Lock()
+		e.next = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next) + jitter(e.job.Jitter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if !e.running.CompareAndSwap(false, true) {
+			now := time.Now().Unix()
+			s.record(e, &Result{Trigger: TriggerSchedule, Outcome: OutcomeSkipped, StartedTs: now, FinishedTs: now, Error: "already running"})
+			continue
+		}
+		s.execute(ctx, e, TriggerSchedule)
+	}
+}
+
+// execute runs a job once its lock is taken. The running flag of the job
+// must be set, and is cleared once done.
+func (s *Scheduler) execute(ctx context.Context, e *entry, trigger Trigger) {
+	defer e.running.Store(false)
+	result := &Result{Trigger: trigger, StartedTs: time.Now().Unix()}
+	defer func() {
+		result.FinishedTs = time.Now().Unix()
+		s.record(e, result)
+	}()
+
+	if s.locker != nil {
+		unlock, ok, err := s.locker.TryLock(ctx, e.job.Name)
+		if err != nil {
+			result.Outcome, result.Error = OutcomeFailed, err.Error()
+			slog.Warn("job failed to take its lock", "job", e.job.Name, "error", err)
+			return
+		}
+		if !ok {
+			result.Outcome, result.Error = OutcomeSkipped, "running on another replica"
+			return
+		}
+		defer unlock()
+	}
+
+	runCtx := ctx
+	if e.job.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, e.job.Timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	if err := runJob(runCtx, e.job); err != nil {
+		result.Outcome, result.Error = OutcomeFailed, err.Error()
+		slog.Warn("job failed", "job", e.job.Name, "trigger", trigger, "duration", time.Since(start), "error", err)
+		return
+	}
+	result.Outcome = OutcomeSucceeded
+	slog.Debug("job succeeded", "job", e.job.Name, "trigger", trigger, "duration", time.Since(start))
+}
+
+// record keeps the result of the last run of a job.
+func (s *Scheduler) record(e *entry, result *Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.last = result
+}
+
+// runJob runs a job, turning its panics into errors.
+func runJob(ctx context.Context, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// jitter returns a random duration up to bound.
+func jitter(bound time.Duration) time.Duration {
+	if bound <= 0 {
+		return 0
+	}
+	return rand.N(bound)
+}
@@ -0,0 +1,165 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLocker is a Locker shared by the replicas of a test.
+type fakeLocker struct {
+	mu     sync.Mutex
+	held   map[string]bool
+	broken bool
+}
+
+func (l *fakeLocker) TryLock(_ context.Context, name string) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.broken {
+		return nil, false, errors.New("connection refused")
+	}
+	if l.held[name] {
+		return nil, false, nil
+	}
+	l.held[name] = true
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.held, name)
+	}, true, nil
+}
+
+// lastRun waits for a job to have run once.
+func lastRun(t *testing.T, s *Scheduler, name string) *Result {
+	t.Helper()
+	var result *Result
+	require.Eventually(t, func() bool {
+		for _, status := range s.List() {
+			if status.Name == name && !status.Running && status.LastRun != nil {
+				result = status.LastRun
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+	return result
+}
+
+func TestRegister(t *testing.T) {
+	s := New(nil)
+	run := func(context.Context) error { return nil }
+	require.NoError(t, s.Register(&Job{Name: "a", Schedule: "0 3 * * *", Run: run}))
+
+	for _, invalid := range []*Job{
+		{Name: "a", Schedule: "0 4 * * *", Run: run},
+		{Name: " ", Schedule: "0 3 * * *", Run: run},
+		{Name: "b", Schedule: "0 3 * * *"},
+		{Name: "b", Schedule: "every day", Run: run},
+		{Name: "b", Schedule: "0 3 * * *", Jitter: -time.Second, Run: run},
+	} {
+		assert.ErrorIs(t, s.Register(invalid), ErrInvalid, "%+v", invalid)
+	}
+	require.Len(t, s.List(), 1)
+	assert.Nil(t, s.List()[0].LastRun)
+}
+
+func TestTrigger(t *testing.T) {
+	s := New(nil)
+	release := make(chan struct{})
+	require.NoError(t, s.Register(&Job{Name: "slow", Schedule: "0 3 * * *", Run: func(ctx context.Context) error {
+		<-release
+		return errors.New("disk full")
+	}}))
+	require.NoError(t, s.Register(&Job{Name: "panics", Schedule: "0 3 * * *", Run: func(ctx context.Context) error {
+		panic("boom")
+	}}))
+
+	require.NoError(t, s.Trigger("slow"))
+	assert.ErrorIs(t, s.Trigger("slow"), ErrRunning)
+	assert.ErrorIs(t, s.Trigger("missing"), ErrNotFound)
+	close(release)
+	result := lastRun(t, s, "slow")
+	assert.Equal(t, TriggerManual, result.Trigger)
+	assert.Equal(t, OutcomeFailed, result.Outcome)
+	assert.Equal(t, "disk full", result.Error)
+
+	require.NoError(t, s.Trigger("panics"))
+	result = lastRun(t, s, "panics")
+	assert.Equal(t, OutcomeFailed, result.Outcome)
+	assert.Equal(t, "panic: boom", result.Error)
+}
+
+func TestSingleFlightAcrossReplicas(t *testing.T) {
+	locker := &fakeLocker{held: map[string]bool{}}
+	runs := 0
+	job := func() *Job {
+		return &Job{Name: "prune", Schedule: "0 3 * * *", Run: func(context.Context) error {
+			runs++
+			return nil
+		}}
+	}
+	replica, other := New(locker), New(locker)
+	require.NoError(t, replica.Register(job()))
+	require.NoError(t, other.Register(job()))
+
+	unlock, ok, err := locker.TryLock(context.Background(), "prune")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, replica.Trigger("prune"))
+	result := lastRun(t, replica, "prune")
+	assert.Equal(t, OutcomeSkipped, result.Outcome, "the lock is held by another replica")
+	unlock()
+
+	require.NoError(t, other.Trigger("prune"))
+	assert.Equal(t, OutcomeSucceeded, lastRun(t, other, "prune").Outcome)
+	assert.Equal(t, 1, runs)
+	assert.Empty(t, locker.held, "the lock is released")
+
+	locker.broken = true
+	require.NoError(t, other.Trigger("prune"))
+	require.Eventually(t, func() bool { return other.List()[0].LastRun.Error == "connection refused" }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, runs)
+}
+
+func TestRunOnSchedule(t *testing.T) {
+	s := New(nil)
+	ran := make(chan struct{}, 1)
+	require.NoError(t, s.Register(&Job{Name: "tick", Schedule: "* * * * * *", Timeout: time.Second, Run: func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok, "runs are bounded by the timeout")
+		select {
+		case ran <- struct{}{}:
+		default:
+		}
+		return nil
+	}}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-ran:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the job did not run on its schedule")
+	}
+	result := lastRun(t, s, "tick")
+	assert.Equal(t, TriggerSchedule, result.Trigger)
+	assert.Equal(t, OutcomeSucceeded, result.Outcome)
+	assert.NotZero(t, s.List()[0].NextRunTs)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the scheduler did not stop")
+	}
+}
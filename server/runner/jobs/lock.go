@@ -0,0 +1,62 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"time"
+)
+
+// unlockTimeout bounds the release of a lock.
+const unlockTimeout = 10 * time.Second
+
+// PGLocker locks jobs with PostgreSQL session advisory locks, held on a
+// dedicated connection while the job runs. Locks of crashed replicas are
+// released with their connection.
+type PGLocker struct {
+	db *sql.DB
+}
+
+// NewPGLocker creates a locker over a PostgreSQL database.
+func NewPGLocker(db *sql.DB) *PGLocker {
+	return &PGLocker{db: db}
+}
+
+// TryLock implements Locker.
+func (l *PGLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get connection: %w", err)
+	}
+	key := lockKey(name)
+	var ok bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&ok); err != nil {
+		_ = conn.Close()
+		return nil, false, fmt.Errorf("failed to take lock: %w", err)
+	}
+	if !ok {
+		_ = conn.Close()
+		return nil, false, nil
+	}
+	return func() {
+		// The context of the job may be canceled already
+		ctx, cancel := context.WithTimeout(context.Background(), unlockTimeout)
+		defer cancel()
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", key); err != nil {
+			slog.Warn("failed to release job lock", "job", name, "error", err)
+			// Discard the connection, whose session still holds the lock
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		_ = conn.Close()
+	}, true, nil
+}
+
+// lockKey is the advisory lock key of a job.
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("divinesense.job:" + name))
+	return int64(h.Sum64())
+}
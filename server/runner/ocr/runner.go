@@ -5,23 +5,34 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hrygo/divinesense/internal/profile"
 	"github.com/hrygo/divinesense/plugin/ocr"
 	"github.com/hrygo/divinesense/plugin/textextract"
+	storepb "github.com/hrygo/divinesense/proto/gen/store"
 	"github.com/hrygo/divinesense/store"
 )
 
+// maxAttempts is how many times an attachment is tried before it is stored
+// with empty text and no longer retried.
+const maxAttempts = 3
+
 // Runner processes attachments for OCR and text extraction.
 type Runner struct {
 	store              *store.Store
-	ocrClient          *ocr.Client
+	ocrClient          ocr.Engine
 	textExtractClient  *textextract.Client
 	semaphore          chan struct{}
+	failures           map[int32]int
+	dataDir            string
 	interval           time.Duration
 	batchSize          int
+	mu                 sync.Mutex
 	ocrEnabled         bool
 	textExtractEnabled bool
 }
@@ -34,20 +45,35 @@ func NewRunner(store *store.Store, profile *profile.Profile) *Runner {
 			interval:  5 * time.Minute,
 			batchSize: 5,
 			semaphore: make(chan struct{}, 10), // Max 10 concurrent async processing
+			failures:  make(map[int32]int),
 		}
 	}
 
 	// Only create OCR clients if enabled
-	var ocrClient *ocr.Client
+	var ocrClient ocr.Engine
 	var textExtractClient *textextract.Client
 
-	if profile.OCREnabled {
-		ocrConfig := &ocr.Config{
-			TesseractPath: profile.TesseractPath,
-			DataPath:      profile.TessdataPath,
-			Languages:     profile.OCRLanguages,
+	ocrEnabled := profile.OCREnabled
+	if ocrEnabled {
+		engine, err := ocr.NewEngine(&ocr.EngineConfig{
+			Provider: profile.OCRProvider,
+			Tesseract: &ocr.Config{
+				TesseractPath: profile.TesseractPath,
+				DataPath:      profile.TessdataPath,
+				Languages:     profile.OCRLanguages,
+			},
+			Vision: &ocr.VisionConfig{
+				BaseURL: profile.OCRAPIBaseURL,
+				APIKey:  profile.OCRAPIKey,
+				Model:   profile.OCRAPIModel,
+			},
+		})
+		if err != nil {
+			slog.Warn("OCR disabled: invalid OCR provider configuration", "provider", profile.OCRProvider, "error", err)
+			ocrEnabled = false
+		} else {
+			ocrClient = engine
 		}
-		ocrClient = ocr.NewClient(ocrConfig)
 	}
 
 	if profile.TextExtractEnabled {
@@ -61,14 +87,21 @@ func NewRunner(store *store.Store, profile *profile.Profile) *Runner {
 		store:              store,
 		ocrClient:          ocrClient,
 		textExtractClient:  textExtractClient,
+		dataDir:            profile.Data,
 		interval:           5 * time.Minute,
 		batchSize:          5,
-		ocrEnabled:         profile.OCREnabled,
+		ocrEnabled:         ocrEnabled,
 		textExtractEnabled: profile.TextExtractEnabled,
 		semaphore:          make(chan struct{}, 10), // Max 10 concurrent async processing
+		failures:           make(map[int32]int),
 	}
 }
 
+// Enabled reports whether OCR or text extraction is enabled.
+func (r *Runner) Enabled() bool {
+	return r.ocrEnabled || r.textExtractEnabled
+}
+
 // Run starts the background task.
 func (r *Runner) Run(ctx context.Context) {
 	// Skip if both features are disabled
@@ -149,34 +182,44 @@ func (r *Runner) processPendingAttachments(ctx context.Context) {
 
 // findPendingAttachments finds attachments that need OCR or text extraction.
 func (r *Runner) findPendingAttachments(ctx context.Context) ([]*store.Attachment, error) {
-	// Find attachments where extracted_text and ocr_text are both empty
-	// and the MIME type is supported
+	// Find attachments of a supported MIME type whose text was never stored.
+	// Processed attachments store their text, even when empty.
+	types := r.supportedTypes()
+	if len(types) == 0 {
+		return nil, nil
+	}
 	attachments, err := r.store.ListAttachments(ctx, &store.FindAttachment{
-		Limit: intPtr(r.batchSize * 10),
+		TextPending: true,
+		TypeList:    types,
+		Limit:       intPtr(r.batchSize * 10),
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Filter for attachments that need processing
 	var pending []*store.Attachment
 	for _, att := range attachments {
 		if att.RowStatus != "NORMAL" {
 			continue
 		}
-		if att.ExtractedText != "" || att.OCRText != "" {
-			// Already processed
-			continue
-		}
-		if !r.needsProcessing(att.Type) {
-			continue
-		}
 		pending = append(pending, att)
 	}
 
 	return pending, nil
 }
 
+// supportedTypes returns the MIME types handled by the enabled clients.
+func (r *Runner) supportedTypes() []string {
+	var types []string
+	if r.ocrEnabled && r.ocrClient != nil {
+		types = append(types, ocr.SupportedMimeTypes...)
+	}
+	if r.textExtractEnabled && r.textExtractClient != nil {
+		types = append(types, textextract.SupportedMimeTypes...)
+	}
+	return types
+}
+
 // needsProcessing checks if an attachment type needs OCR or text extraction.
 func (r *Runner) needsProcessing(mimeType string) bool {
 	// Check if it's an image (OCR)
@@ -213,19 +256,24 @@ func (r *Runner) processAttachment(ctx context.Context, attachment *store.Attach
 	if attachmentWithBlob == nil {
 		return fmt.Errorf("attachment not found")
 	}
+	blob, err := r.readBlob(attachmentWithBlob)
+	if err != nil {
+		return err
+	}
 
 	update := &store.UpdateAttachment{
 		ID:        attachment.ID,
 		UpdatedTs: int64Ptr(time.Now().Unix()),
 	}
+	failed := false
 
 	// Process based on MIME type
 	if r.ocrEnabled && r.ocrClient != nil && r.ocrClient.IsSupported(attachmentWithBlob.Type) {
 		// OCR for images
-		text, err := r.ocrClient.ExtractText(ctx, attachmentWithBlob.Blob, attachmentWithBlob.Type)
+		text, err := r.ocrClient.ExtractText(ctx, blob, attachmentWithBlob.Type)
 		if err != nil {
 			slog.Warn("OCR failed", "id", attachment.ID, "error", err)
-			// Don't return error, continue with empty text
+			failed = true
 		} else {
 			update.OCRText = &text
 			slog.Info("OCR completed", "id", attachment.ID, "text_length", len(text))
@@ -234,29 +282,85 @@ func (r *Runner) processAttachment(ctx context.Context, attachment *store.Attach
 
 	if r.textExtractEnabled && r.textExtractClient != nil && r.textExtractClient.IsSupported(attachmentWithBlob.Type) {
 		// Text extraction for documents
-		result, err := r.textExtractClient.ExtractText(ctx, attachmentWithBlob.Blob, attachmentWithBlob.Type)
+		result, err := r.textExtractClient.ExtractText(ctx, blob, attachmentWithBlob.Type)
 		if err != nil {
 			slog.Warn("text extraction failed", "id", attachment.ID, "error", err)
+			failed = true
 		} else {
 			update.ExtractedText = &result.Text
 			slog.Info("text extraction completed", "id", attachment.ID, "text_length", len(result.Text))
 		}
 	}
 
+	// Failed attachments are retried on later runs, up to maxAttempts.
+	if failed && update.OCRText == nil && update.ExtractedText == nil {
+		if !r.giveUp(attachment.ID) {
+			return nil
+		}
+		slog.Warn("attachment text extraction abandoned", "id", attachment.ID, "attempts", maxAttempts)
+		empty := ""
+		update.OCRText = &empty
+	}
+
 	// Update attachment if we have new data
 	if update.OCRText != nil || update.ExtractedText != nil {
 		if err := r.store.UpdateAttachment(ctx, update); err != nil {
 			return fmt.Errorf("failed to update attachment: %w", err)
 		}
+		r.reembedMemo(ctx, attachmentWithBlob, update)
 	}
 
 	return nil
 }
 
+// readBlob returns the content of an attachment, reading local files from the
+// data directory.
+func (r *Runner) readBlob(attachment *store.Attachment) ([]byte, error) {
+	if attachment.StorageType != storepb.AttachmentStorageType_LOCAL {
+		return attachment.Blob, nil
+	}
+	path := filepath.FromSlash(attachment.Reference)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.dataDir, path)
+	}
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment file: %w", err)
+	}
+	return blob, nil
+}
+
+// giveUp records a failed attempt and reports whether the attachment ran out
+// of attempts.
+func (r *Runner) giveUp(attachmentID int32) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures[attachmentID]++
+	if r.failures[attachmentID] < maxAttempts {
+		return false
+	}
+	delete(r.failures, attachmentID)
+	return true
+}
+
+// reembedMemo drops the embedding of the attachment's memo when new text was
+// found, so that the embedding runner rebuilds it with the attachment text.
+func (r *Runner) reembedMemo(ctx context.Context, attachment *store.Attachment, update *store.UpdateAttachment) {
+	if attachment.MemoID == nil {
+		return
+	}
+	if (update.OCRText == nil || *update.OCRText == "") && (update.ExtractedText == nil || *update.ExtractedText == "") {
+		return
+	}
+	if err := r.store.DeleteMemoEmbedding(ctx, *attachment.MemoID); err != nil {
+		slog.Warn("failed to reset memo embedding", "memo_id", *attachment.MemoID, "error", err)
+	}
+}
+
 // ProcessAttachmentAsync processes a single attachment asynchronously.
 // This can be called when a new attachment is uploaded.
 func (r *Runner) ProcessAttachmentAsync(ctx context.Context, attachmentID int32) {
-	if !r.ocrEnabled && !r.textExtractEnabled {
+	if !r.Enabled() {
 		slog.Debug("OCR runner is disabled, skipping async processing", "attachment_id", attachmentID)
 		return
 	}
@@ -284,6 +388,9 @@ func (r *Runner) ProcessAttachmentAsync(ctx context.Context, attachmentID int32)
 			slog.Error("failed to get attachment for async processing", "id", attachmentID, "error", err)
 			return
 		}
+		if attachment == nil || !r.needsProcessing(attachment.Type) {
+			return
+		}
 
 		if err := r.processAttachment(ctx, attachment); err != nil {
 			slog.Error("async attachment processing failed", "id", attachmentID, "error", err)
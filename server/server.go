@@ -14,15 +14,13 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/pkg/errors"
 
-	"github.com/hrygo/divinesense/ai"
 	"github.com/hrygo/divinesense/internal/profile"
 	storepb "github.com/hrygo/divinesense/proto/gen/store"
+	"github.com/hrygo/divinesense/server/internal/observability"
 	apiv1 "github.com/hrygo/divinesense/server/router/api/v1"
 	"github.com/hrygo/divinesense/server/router/fileserver"
 	"github.com/hrygo/divinesense/server/router/frontend"
 	"github.com/hrygo/divinesense/server/router/rss"
-	"github.com/hrygo/divinesense/server/runner/embedding"
-	"github.com/hrygo/divinesense/server/runner/ocr"
 	"github.com/hrygo/divinesense/store"
 )
 
@@ -32,7 +30,9 @@ type Server struct {
 	Store   *store.Store
 
 	echoServer        *echo.Echo
+	apiV1Service      *apiv1.APIV1Service
 	runnerCancelFuncs []context.CancelFunc
+	shutdownTracing   func(context.Context) error
 }
 
 func NewServer(ctx context.Context, profile *profile.Profile, store *store.Store) (*Server, error) {
@@ -66,17 +66,34 @@ func NewServer(ctx context.Context, profile *profile.Profile, store *store.Store
 	}
 	s.Secret = secret
 
+	// Export the OpenTelemetry traces of the chat path.
+	shutdownTracing, err := observability.SetupTracing(ctx, observability.TracingConfig{
+		Endpoint:    profile.TracingEndpoint,
+		SampleRatio: profile.TracingSampleRatio,
+		Version:     profile.Version,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to set up tracing")
+	}
+	s.shutdownTracing = shutdownTracing
+
 	// Register healthz endpoint.
 	echoServer.GET("/healthz", func(c echo.Context) error {
 		return c.String(http.StatusOK, "Service ready.")
 	})
 
+	// Register Prometheus metrics endpoint.
+	if profile.Metrics {
+		echoServer.GET("/metrics", echo.WrapHandler(observability.MetricsHandler(profile.MetricsToken)))
+	}
+
 	// Serve frontend static files.
 	frontend.NewFrontendService(profile, store).Serve(ctx, echoServer)
 
 	rootGroup := echoServer.Group("")
 
 	apiV1Service := apiv1.NewAPIV1Service(s.Secret, profile, store)
+	s.apiV1Service = apiV1Service
 
 	// Register HTTP file server routes BEFORE gRPC-Gateway to ensure proper range request handling for Safari.
 	// This uses native HTTP serving (http.ServeContent) instead of gRPC for video/audio files.
@@ -137,6 +154,11 @@ func (s *Server) Shutdown(ctx context.Context) {
 		slog.Error("failed to shutdown server", slog.String("error", err.Error()))
 	}
 
+	// Flush pending trace spans.
+	if err := s.shutdownTracing(ctx); err != nil {
+		slog.Error("failed to shutdown tracing", slog.String("error", err.Error()))
+	}
+
 	// Close database connection.
 	if err := s.Store.Close(); err != nil {
 		slog.Error("failed to close database", slog.String("error", err.Error()))
@@ -148,31 +170,23 @@ func (s *Server) Shutdown(ctx context.Context) {
 func (s *Server) StartBackgroundRunners(ctx context.Context) {
 	// Start embedding runner if AI is enabled
 	// AI features are supported on PostgreSQL (with pgvector) and SQLite (with application-layer vector search)
-	if s.Profile.IsAIEnabled() && (s.Profile.Driver == "postgres" || s.Profile.Driver == "sqlite") {
-		aiConfig := ai.NewConfigFromProfile(s.Profile)
-		if err := aiConfig.Validate(); err == nil {
-			embeddingService, err := ai.NewEmbeddingService(&aiConfig.Embedding)
-			if err == nil {
-				embeddingRunner := embedding.NewRunner(s.Store, embeddingService)
-				embeddingCtx, embeddingCancel := context.WithCancel(ctx)
-				s.runnerCancelFuncs = append(s.runnerCancelFuncs, embeddingCancel)
-				go func() {
-					embeddingRunner.Run(embeddingCtx)
-					slog.Info("embedding runner stopped")
-				}()
-				slog.Info("embedding runner started")
-			} else {
-				slog.Warn("failed to create embedding service", "error", err)
-			}
-		} else {
-			slog.Warn("AI config validation failed", "error", err)
+	if embeddingRunner := s.apiV1Service.EmbeddingRunner; embeddingRunner != nil {
+		embeddingCtx, embeddingCancel := context.WithCancel(ctx)
+		s.runnerCancelFuncs = append(s.runnerCancelFuncs, embeddingCancel)
+		go func() {
+			embeddingRunner.Run(embeddingCtx)
+			slog.Info("embedding runner stopped")
+		}()
+		slog.Info("embedding runner started")
+		if index := s.apiV1Service.EmbeddingIndex; index != nil {
+			indexCtx, indexCancel := context.WithCancel(ctx)
+			s.runnerCancelFuncs = append(s.runnerCancelFuncs, indexCancel)
+			go index.Watch(indexCtx, time.Minute)
 		}
 	}
 
 	// Start OCR runner for attachment text extraction (if enabled)
-	if s.Profile.OCREnabled || s.Profile.TextExtractEnabled {
-		ocrRunner := ocr.NewRunner(s.Store, s.Profile)
-		ocrRunner.RunOnce(ctx)
+	if ocrRunner := s.apiV1Service.OCRRunner; ocrRunner != nil {
 		ocrCtx, ocrCancel := context.WithCancel(ctx)
 		s.runnerCancelFuncs = append(s.runnerCancelFuncs, ocrCancel)
 		go func() {
@@ -182,6 +196,95 @@ func (s *Server) StartBackgroundRunners(ctx context.Context) {
 		slog.Info("OCR runner started")
 	}
 
+	// Start scheduled message dispatcher (if AI chat is available)
+	if dispatcher := s.apiV1Service.NewScheduledMessageDispatcher(); dispatcher != nil {
+		dispatcherCtx, dispatcherCancel := context.WithCancel(ctx)
+		s.runnerCancelFuncs = append(s.runnerCancelFuncs, dispatcherCancel)
+		go func() {
+			dispatcher.Run(dispatcherCtx)
+			slog.Info("scheduled message dispatcher stopped")
+		}()
+		slog.Info("scheduled message dispatcher started")
+	}
+
+	// Start weekly knowledge-base health reports (PostgreSQL only)
+	if runner := s.apiV1Service.NewKBHealthRunner(); runner != nil {
+		runnerCtx, runnerCancel := context.WithCancel(ctx)
+		s.runnerCancelFuncs = append(s.runnerCancelFuncs, runnerCancel)
+		go func() {
+			runner.Run(runnerCtx)
+			slog.Info("kb health report runner stopped")
+		}()
+		slog.Info("kb health report runner started")
+	}
+
+	// Start the read-later queue: scores new items and sends digests (PostgreSQL only)
+	if queue := s.apiV1Service.ReadLaterQueue(); queue != nil {
+		queueCtx, queueCancel := context.WithCancel(ctx)
+		s.runnerCancelFuncs = append(s.runnerCancelFuncs, queueCancel)
+		go func() {
+			queue.Run(queueCtx)
+			slog.Info("read later runner stopped")
+		}()
+		slog.Info("read later runner started")
+	}
+
+	// Start webhook delivery: posts the queued events and retries failures (PostgreSQL only)
+	if webhooks := s.apiV1Service.Webhooks; webhooks != nil {
+		webhooksCtx, webhooksCancel := context.WithCancel(ctx)
+		s.runnerCancelFuncs = append(s.runnerCancelFuncs, webhooksCancel)
+		go func() {
+			webhooks.Run(webhooksCtx)
+			slog.Info("webhook delivery runner stopped")
+		}()
+		slog.Info("webhook delivery runner started")
+	}
+
+	// Start the Telegram gateway: polls the updates of the instance bot (PostgreSQL only)
+	if gateway := s.apiV1Service.Telegram; gateway != nil {
+		gatewayCtx, gatewayCancel := context.WithCancel(ctx)
+		s.runnerCancelFuncs = append(s.runnerCancelFuncs, gatewayCancel)
+		go func() {
+			gateway.Run(gatewayCtx)
+			slog.Info("telegram gateway stopped")
+		}()
+		slog.Info("telegram gateway started")
+	}
+
+	// Start PDF ingestion (PostgreSQL with text extraction and AI)
+	if documents := s.apiV1Service.PDFDocuments(); documents != nil {
+		documentsCtx, documentsCancel := context.WithCancel(ctx)
+		s.runnerCancelFuncs = append(s.runnerCancelFuncs, documentsCancel)
+		go func() {
+			documents.Run(documentsCtx)
+			slog.Info("pdf ingestion runner stopped")
+		}()
+		slog.Info("pdf ingestion runner started")
+	}
+
+	// Probe the resource pressure of AI chat for load shedding
+	if shedder := s.apiV1Service.LoadShedder(); shedder != nil {
+		shedderCtx, shedderCancel := context.WithCancel(ctx)
+		s.runnerCancelFuncs = append(s.runnerCancelFuncs, shedderCancel)
+		go func() {
+			shedder.Run(shedderCtx)
+			slog.Info("load shedder stopped")
+		}()
+		slog.Info("load shedder started")
+	}
+
+	// Run the periodic jobs, such as the garbage collection of the Geek Mode
+	// workspaces and the pruning of the offline sync change log
+	if scheduler := s.apiV1Service.Jobs; scheduler != nil {
+		jobsCtx, jobsCancel := context.WithCancel(ctx)
+		s.runnerCancelFuncs = append(s.runnerCancelFuncs, jobsCancel)
+		go func() {
+			scheduler.Run(jobsCtx)
+			slog.Info("job scheduler stopped")
+		}()
+		slog.Info("job scheduler started", "jobs", len(scheduler.List()))
+	}
+
 	// Log the number of goroutines running
 	slog.Info("background runners started", "goroutines", runtime.NumGoroutine())
 }
@@ -25,6 +25,10 @@ type Service interface {
 	// DeleteSchedule deletes a schedule by ID.
 	DeleteSchedule(ctx context.Context, userID int32, id int32) error
 
+	// SkipOccurrence skips a single occurrence of a recurring schedule, given its day
+	// (YYYY-MM-DD in the schedule's timezone), keeping the others.
+	SkipOccurrence(ctx context.Context, userID int32, id int32, day string) (*store.Schedule, error)
+
 	// CheckConflicts checks for schedule conflicts within a time range.
 	// Returns a list of conflicting schedules.
 	CheckConflicts(ctx context.Context, userID int32, startTs int64, endTs *int64, excludeIDs []int32) ([]*store.Schedule, error)
@@ -39,15 +43,20 @@ type ScheduleInstance struct {
 	Location    string
 	Timezone    string
 	ParentUID   string
-	StartTs     int64
-	ID          int32
-	AllDay      bool
-	IsRecurring bool
+	// Reminders is the JSON reminders of the schedule.
+	Reminders string
+	// RecurrenceRule is the JSON recurrence rule of recurring instances.
+	RecurrenceRule string
+	StartTs        int64
+	ID             int32
+	AllDay         bool
+	IsRecurring    bool
 }
 
 // CreateScheduleRequest represents the request to create a schedule.
 type CreateScheduleRequest struct {
-	EndTs           *int64
+	EndTs *int64
+	// RecurrenceRule is a JSON rule, an RRULE or a phrase like "每周三下午三点".
 	RecurrenceRule  *string
 	RecurrenceEndTs *int64
 	Title           string
@@ -2,7 +2,7 @@
 // querying, updating, and deleting schedules with recurring event support.
 //
 // Key features:
-//   - Recurring schedule expansion using RRule
+//   - Recurring schedule expansion from rules, RRULEs or phrases like "每周三"
 //   - Conflict detection and prevention (atomic via DB constraint)
 //   - Timezone-aware time handling
 //
@@ -37,6 +37,9 @@ const (
 var (
 	// ErrScheduleConflict is returned when a schedule conflicts with existing schedules.
 	ErrScheduleConflict = fmt.Errorf("schedule conflicts detected")
+
+	// ErrNoOccurrence is returned when skipping a day without an occurrence of a recurring schedule.
+	ErrNoOccurrence = errors.New("no occurrence on this day")
 )
 
 // ConflictError is a structured error for schedule conflicts with i18n support.
@@ -132,40 +135,33 @@ func (s *service) FindSchedules(ctx context.Context, userID int32, start, end ti
 				continue
 			}
 
-			// Generate instances starting from the schedule's start time
-			// This ensures we get the correct sequence from the first occurrence
-			instanceTimestamps := rule.GenerateInstances(sched.StartTs, endTs)
+			// Expand the occurrences within the query window in the schedule's time zone,
+			// up to the recurrence end and without the skipped ones
+			var until int64
+			if sched.RecurrenceEndTs != nil {
+				until = *sched.RecurrenceEndTs
+			}
+			occurrences := rule.Expand(time.Unix(sched.StartTs, 0).In(loadLocation(sched.Timezone)),
+				start, end, until, maxTotalInstances-len(instances))
 
 			// For each instance, create a schedule with adjusted time
-			for _, instanceTs := range instanceTimestamps {
-				// Check if we've hit the total instance limit
-				if len(instances) >= maxTotalInstances {
-					truncated = true
-					break
-				}
-
-				// Only add instances within the query window
-				if instanceTs < startTs || instanceTs > endTs {
-					continue
-				}
-
+			for _, occurrence := range occurrences {
 				instance := s.convertToInstance(sched, true, sched.UID)
-				instance.StartTs = instanceTs
+				instance.StartTs = occurrence.Unix()
+				instance.RecurrenceRule = *sched.RecurrenceRule
 
 				// Calculate end time for this instance
 				if sched.EndTs != nil && sched.StartTs > 0 {
 					duration := *sched.EndTs - sched.StartTs
-					endTsValue := instanceTs + duration
+					endTsValue := instance.StartTs + duration
 					instance.EndTs = &endTsValue
 				}
 
 				instances = append(instances, instance)
-
-				// Break if we've hit the limit
-				if len(instances) >= maxTotalInstances {
-					truncated = true
-					break
-				}
+			}
+			if len(instances) >= maxTotalInstances {
+				truncated = true
+				break
 			}
 		} else {
 			// Non-recurring schedule, add as-is if within time range
@@ -224,6 +220,15 @@ func (s *service) CreateSchedule(ctx context.Context, userID int32, create *Crea
 		timezone = DefaultTimezone
 	}
 
+	// Normalize the recurrence, given as a rule, an RRULE or a phrase like "每周三"
+	if create.RecurrenceRule != nil && strings.TrimSpace(*create.RecurrenceRule) != "" {
+		normalized, err := normalizeRecurrence(create, timezone)
+		if err != nil {
+			return nil, err
+		}
+		create = normalized
+	}
+
 	// Marshal reminders
 	var remindersStr string
 	if len(create.Reminders) > 0 {
@@ -346,6 +351,28 @@ func (s *service) UpdateSchedule(ctx context.Context, userID int32, id int32, up
 	}
 	if update.RecurrenceRule != nil {
 		storeUpdate.RecurrenceRule = update.RecurrenceRule
+		if strings.TrimSpace(*update.RecurrenceRule) != "" {
+			start := existing.StartTs
+			if update.StartTs != nil {
+				start = *update.StartTs
+			}
+			timezone := existing.Timezone
+			if update.Timezone != nil && *update.Timezone != "" {
+				timezone = *update.Timezone
+			}
+			rule, until, err := aischedule.ParseRecurrenceText(*update.RecurrenceRule, time.Unix(start, 0).In(loadLocation(timezone)))
+			if err != nil {
+				return nil, fmt.Errorf("invalid recurrence rule: %w", err)
+			}
+			ruleJSON, err := rule.ToJSON()
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal recurrence rule: %w", err)
+			}
+			storeUpdate.RecurrenceRule = &ruleJSON
+			if update.RecurrenceEndTs == nil && until > 0 {
+				storeUpdate.RecurrenceEndTs = &until
+			}
+		}
 	}
 	if update.RecurrenceEndTs != nil {
 		storeUpdate.RecurrenceEndTs = update.RecurrenceEndTs
@@ -427,6 +454,60 @@ func (s *service) DeleteSchedule(ctx context.Context, userID int32, id int32) er
 	return nil
 }
 
+// SkipOccurrence skips the occurrence of a recurring schedule on a day, YYYY-MM-DD in its time zone.
+func (s *service) SkipOccurrence(ctx context.Context, userID int32, id int32, day string) (*store.Schedule, error) {
+	find := &store.FindSchedule{
+		ID:        &id,
+		CreatorID: &userID,
+	}
+	existing, err := s.store.GetSchedule(ctx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule: %w", err)
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("schedule not found")
+	}
+	if existing.RecurrenceRule == nil || *existing.RecurrenceRule == "" {
+		return nil, fmt.Errorf("schedule %d is not recurring", id)
+	}
+	rule, err := aischedule.ParseRecurrenceRuleFromJSON(*existing.RecurrenceRule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+
+	// Verify an occurrence falls on the day
+	loc := loadLocation(existing.Timezone)
+	dayStart, err := time.ParseInLocation(time.DateOnly, day, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid day: %q (must be YYYY-MM-DD)", day)
+	}
+	var until int64
+	if existing.RecurrenceEndTs != nil {
+		until = *existing.RecurrenceEndTs
+	}
+	occurrences := rule.Expand(time.Unix(existing.StartTs, 0).In(loc), dayStart, dayStart.AddDate(0, 0, 1).Add(-time.Second), until, 1)
+	if len(occurrences) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoOccurrence, day)
+	}
+
+	if err := rule.Skip(day); err != nil {
+		return nil, err
+	}
+	ruleJSON, err := rule.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal recurrence rule: %w", err)
+	}
+	if err := s.store.UpdateSchedule(ctx, &store.UpdateSchedule{ID: id, RecurrenceRule: &ruleJSON}); err != nil {
+		return nil, fmt.Errorf("failed to update schedule: %w", err)
+	}
+
+	updated, err := s.store.GetSchedule(ctx, find)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get updated schedule: %w", err)
+	}
+	return updated, nil
+}
+
 // CheckConflicts checks for schedule conflicts within a time range.
 // Only checks against active (NORMAL) schedules, excluding archived ones.
 func (s *service) CheckConflicts(ctx context.Context, userID int32, startTs int64, endTs *int64, excludeIDs []int32) ([]*store.Schedule, error) {
@@ -487,7 +568,7 @@ func (s *service) CheckConflicts(ctx context.Context, userID int32, startTs int6
 
 // convertToInstance converts a store.Schedule to a ScheduleInstance.
 func (s *service) convertToInstance(sched *store.Schedule, isRecurring bool, parentUID string) *ScheduleInstance {
-	return &ScheduleInstance{
+	instance := &ScheduleInstance{
 		ID:          sched.ID,
 		UID:         sched.UID,
 		Title:       sched.Title,
@@ -500,6 +581,56 @@ func (s *service) convertToInstance(sched *store.Schedule, isRecurring bool, par
 		IsRecurring: isRecurring,
 		ParentUID:   parentUID,
 	}
+	if sched.Reminders != nil {
+		instance.Reminders = *sched.Reminders
+	}
+	return instance
+}
+
+// normalizeRecurrence returns a copy of a create request with its recurrence as the JSON rule
+// of schedules, starting at its first occurrence: a schedule created on Friday for "每周三"
+// starts on the next Wednesday. The recurrence end of an RRULE applies unless one is given.
+func normalizeRecurrence(create *CreateScheduleRequest, timezone string) (*CreateScheduleRequest, error) {
+	normalized := *create
+	start := time.Unix(create.StartTs, 0).In(loadLocation(timezone))
+	rule, until, err := aischedule.ParseRecurrenceText(*create.RecurrenceRule, start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+
+	first := rule.Anchor(start)
+	if first.IsZero() {
+		return nil, fmt.Errorf("invalid recurrence rule: no occurrence within a year")
+	}
+	if shift := first.Unix() - create.StartTs; shift != 0 {
+		normalized.StartTs = first.Unix()
+		if create.EndTs != nil {
+			endTs := *create.EndTs + shift
+			normalized.EndTs = &endTs
+		}
+	}
+
+	ruleJSON, err := rule.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal recurrence rule: %w", err)
+	}
+	normalized.RecurrenceRule = &ruleJSON
+	if create.RecurrenceEndTs == nil && until > 0 {
+		normalized.RecurrenceEndTs = &until
+	}
+	return &normalized, nil
+}
+
+// loadLocation returns the location of a timezone, UTC when invalid.
+func loadLocation(timezone string) *time.Location {
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
 }
 
 // buildConflictError builds a human-readable error message for schedule conflicts.
@@ -550,7 +681,7 @@ func formatTimestamp(ts int64, timezone string) string {
 }
 
 // checkRecurringConflicts checks for conflicts in recurring schedule instances.
-// Uses index-based approach and iterator for improved performance with long-running recurrences.
+// Uses index-based approach and a bounded expansion for improved performance with long-running recurrences.
 func (s *service) checkRecurringConflicts(ctx context.Context, userID int32, create *CreateScheduleRequest) ([]*RecurringConflict, error) {
 	// Parse recurrence rule
 	rule, err := aischedule.ParseRecurrenceRuleFromJSON(*create.RecurrenceRule)
@@ -591,31 +722,25 @@ func (s *service) checkRecurringConflicts(ctx context.Context, userID int32, cre
 	// Build conflict index for O(1) lookup
 	conflictIndex := s.buildConflictIndex(potentialConflicts)
 
-	// Use iterator for lazy evaluation
-	iterator := rule.Iterator(create.StartTs)
-
 	// Check instances one by one (up to increased limit)
 	const maxCheckCount = 500 // Increased from MaxInstancesToCheck (100)
-	checkCount := 0
+	timezone := create.Timezone
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+	start := time.Unix(create.StartTs, 0).In(loadLocation(timezone))
+	occurrences := rule.Expand(start, start, time.Unix(endTs, 0), endTs, maxCheckCount)
+	if len(occurrences) == maxCheckCount {
+		slog.Warn("recurring conflict check hit limit",
+			"limit", maxCheckCount,
+			"user_id", userID)
+	}
 
-	for {
-		instanceTs := iterator.Next()
-		if instanceTs == 0 {
-			break
-		}
-		if instanceTs > endTs {
-			break
-		}
-		if checkCount >= maxCheckCount {
-			slog.Warn("recurring conflict check hit limit",
-				"limit", maxCheckCount,
-				"user_id", userID)
-			break
-		}
+	for _, occurrence := range occurrences {
+		instanceTs := occurrence.Unix()
 
 		// Skip first instance - already checked in CreateSchedule
 		if instanceTs == create.StartTs {
-			checkCount++
 			continue
 		}
 
@@ -635,8 +760,6 @@ func (s *service) checkRecurringConflicts(ctx context.Context, userID int32, cre
 				}, nil
 			}
 		}
-
-		checkCount++
 	}
 
 	return nil, nil
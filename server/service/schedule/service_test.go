@@ -95,6 +95,9 @@ func (m *MockStoreForSchedule) UpdateSchedule(ctx context.Context, update *store
 			if update.Timezone != nil {
 				s.Timezone = *update.Timezone
 			}
+			if update.RecurrenceRule != nil {
+				s.RecurrenceRule = update.RecurrenceRule
+			}
 			break
 		}
 	}
@@ -235,6 +238,88 @@ func TestCreateScheduleValidation(t *testing.T) {
 	}
 }
 
+// TestCreateSchedule_RecurrencePhrase tests recurring schedules created from a phrase.
+func TestCreateSchedule_RecurrencePhrase(t *testing.T) {
+	ctx := context.Background()
+	userID := int32(1)
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+
+	mockStore := &MockStoreForSchedule{}
+	svc := &service{store: mockStore}
+
+	// Friday 2030-01-04 15:00, the weekly meeting is on Wednesdays
+	start := time.Date(2030, 1, 4, 15, 0, 0, 0, loc)
+	phrase := "每周三下午三点"
+	created, err := svc.CreateSchedule(ctx, userID, &CreateScheduleRequest{
+		Title:          "Weekly Sync",
+		StartTs:        start.Unix(),
+		EndTs:          func() *int64 { ts := start.Add(time.Hour).Unix(); return &ts }(),
+		Timezone:       "Asia/Shanghai",
+		RecurrenceRule: &phrase,
+	})
+	require.NoError(t, err)
+
+	first := time.Date(2030, 1, 9, 15, 0, 0, 0, loc)
+	assert.Equal(t, first.Unix(), created.StartTs)
+	assert.Equal(t, first.Add(time.Hour).Unix(), *created.EndTs)
+	require.NotNil(t, created.RecurrenceRule)
+	assert.JSONEq(t, `{"type":"weekly","weekdays":[3],"interval":1,"month_day":0}`, *created.RecurrenceRule)
+
+	_, err = svc.CreateSchedule(ctx, userID, &CreateScheduleRequest{
+		Title:          "Invalid",
+		StartTs:        start.Unix(),
+		RecurrenceRule: func() *string { s := "下午三点"; return &s }(),
+	})
+	assert.ErrorContains(t, err, "invalid recurrence rule")
+}
+
+// TestFindSchedules_RecurringWithException tests the expansion of recurring schedules
+// with a skipped occurrence and a recurrence end.
+func TestFindSchedules_RecurringWithException(t *testing.T) {
+	ctx := context.Background()
+	userID := int32(1)
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+
+	start := time.Date(2030, 1, 9, 15, 0, 0, 0, loc)
+	rule := `{"type":"weekly","weekdays":[3],"interval":1}`
+	until := time.Date(2030, 2, 6, 15, 0, 0, 0, loc).Unix()
+	mockStore := &MockStoreForSchedule{
+		schedules: []*store.Schedule{
+			{
+				ID:              1,
+				UID:             "weekly-sync",
+				CreatorID:       userID,
+				Title:           "Weekly Sync",
+				StartTs:         start.Unix(),
+				EndTs:           func() *int64 { ts := start.Add(time.Hour).Unix(); return &ts }(),
+				Timezone:        "Asia/Shanghai",
+				RecurrenceRule:  &rule,
+				RecurrenceEndTs: &until,
+				RowStatus:       store.Normal,
+			},
+		},
+	}
+	svc := &service{store: mockStore}
+
+	_, err = svc.SkipOccurrence(ctx, userID, 1, "2030-01-16")
+	require.NoError(t, err)
+	_, err = svc.SkipOccurrence(ctx, userID, 1, "2030-01-17")
+	assert.ErrorIs(t, err, ErrNoOccurrence)
+
+	instances, err := svc.FindSchedules(ctx, userID, start, start.AddDate(0, 3, 0))
+	require.NoError(t, err)
+
+	var days []string
+	for _, inst := range instances {
+		assert.True(t, inst.IsRecurring)
+		assert.Equal(t, inst.StartTs+3600, *inst.EndTs)
+		days = append(days, time.Unix(inst.StartTs, 0).In(loc).Format(time.DateOnly))
+	}
+	assert.Equal(t, []string{"2030-01-09", "2030-01-23", "2030-01-30", "2030-02-06"}, days)
+}
+
 // TestCheckConflicts tests the CheckConflicts method.
 func TestCheckConflicts(t *testing.T) {
 	ctx := context.Background()
@@ -66,6 +66,17 @@ type UserCostSettings struct {
 	BudgetResetAt          *time.Time
 }
 
+// UsageTotals represents a user's AI usage in a period.
+// UsageTotals 表示用户在一段时间内的 AI 用量。
+type UsageTotals struct {
+	SessionCount     int64 // Geek and Evolution mode sessions
+	SessionTokens    int64
+	SessionCostUSD   float64
+	ChatCount        int64 // Normal mode chat blocks, stored without a cost
+	ChatInputTokens  int64
+	ChatOutputTokens int64
+}
+
 // AgentStatsStore defines the interface for session statistics persistence.
 // AgentStatsStore 定义会话统计持久化的接口。
 type AgentStatsStore interface {
@@ -84,6 +95,9 @@ type AgentStatsStore interface {
 	// GetCostStats retrieves aggregated cost statistics.
 	GetCostStats(ctx context.Context, userID int32, days int) (*CostStats, error)
 
+	// GetUsageTotals retrieves the sessions and chat blocks of a user started in [start, end).
+	GetUsageTotals(ctx context.Context, userID int32, start, end time.Time) (*UsageTotals, error)
+
 	// GetUserCostSettings retrieves or creates user cost settings.
 	GetUserCostSettings(ctx context.Context, userID int32) (*UserCostSettings, error)
 
@@ -31,6 +31,9 @@ type AIBlock struct {
 	ArchivedAt        *int64 // Timestamp when block was archived (null if active)
 	CreatedTs         int64
 	UpdatedTs         int64
+	// Locked is set on read when the contents are sealed by a locked
+	// conversation that is not unlocked; they are returned empty.
+	Locked bool
 }
 
 // TokenUsage represents detailed token usage for a single block or LLM call.
@@ -83,6 +86,9 @@ const (
 	AIBlockStatusStreaming AIBlockStatus = "streaming"
 	AIBlockStatusCompleted AIBlockStatus = "completed"
 	AIBlockStatusError     AIBlockStatus = "error"
+	// AIBlockStatusInterrupted marks blocks stopped by the user; the assistant
+	// content is the partial answer produced before the stop.
+	AIBlockStatusInterrupted AIBlockStatus = "interrupted"
 )
 
 // SessionStats represents session statistics (compatible with agent_session_stats)
@@ -159,6 +165,7 @@ type FindAIBlock struct {
 	Mode           *AIBlockMode
 	CCSessionID    *string
 	ParentBlockID  *int64 // Filter by parent block (for branch queries)
+	UpdatedAfter   *int64 // Blocks updated after this time in milliseconds (for incremental sync)
 }
 
 // AIBlockStore defines the interface for block storage operations
@@ -0,0 +1,422 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Block metadata sections.
+//
+// The subsystems writing block metadata store their fields as typed sections,
+// one object per key, tagged with the version of the section schema. The
+// store validates the sections written to blocks, so that a misspelt field or
+// a value of the wrong type fails the write instead of being read back as
+// missing. Keys outside the sections stay free-form.
+const (
+	// MetadataSectionRouting holds the RoutingMetadata of sticky routing.
+	MetadataSectionRouting = "routing"
+	// MetadataSectionFork holds the ForkMetadata of forked blocks.
+	MetadataSectionFork = "fork"
+	// MetadataSectionBudget holds the BudgetMetadata of blocks stopped by
+	// their budget.
+	MetadataSectionBudget = "budget"
+	// MetadataSectionCitations holds the CitationsMetadata of the memos the
+	// answer cites.
+	MetadataSectionCitations = "citations"
+	// MetadataSectionImport holds the ImportMetadata of the blocks imported
+	// from other assistants.
+	MetadataSectionImport = "import"
+)
+
+// Current schema versions of the sections. Readers accept the versions up to
+// the current one; a change of a section that old readers cannot decode
+// bumps its version.
+const (
+	RoutingMetadataVersion   = 1
+	ForkMetadataVersion      = 1
+	BudgetMetadataVersion    = 1
+	CitationsMetadataVersion = 1
+	ImportMetadataVersion    = 1
+)
+
+// ErrInvalidBlockMetadata is returned for block writes whose metadata
+// sections fail validation.
+var ErrInvalidBlockMetadata = errors.New("invalid block metadata")
+
+// Fork types of ForkMetadata.
+const (
+	ForkTypeEdit   = "edit"   // The user edited the message of the parent
+	ForkTypeBranch = "branch" // The user branched off the parent
+)
+
+// routeMethods are the RouteMethod values of RoutingMetadata.
+var routeMethods = []string{"cache", "rule", "llm", "sticky", "metadata_sticky"}
+
+// RoutingMetadata is the routing section: the agent that answered the block
+// and the sticky window of the next rounds.
+type RoutingMetadata struct {
+	Version          int     `json:"version"`
+	LastAgent        string  `json:"last_agent,omitempty"`
+	Intent           string  `json:"intent,omitempty"`
+	IntentConfidence float32 `json:"intent_confidence,omitempty"` // 0-1
+	RouteMethod      string  `json:"route_method,omitempty"`      // cache, rule, llm, sticky or metadata_sticky
+	StickyUntil      int64   `json:"sticky_until,omitempty"`      // Unix seconds
+	StickyCount      int     `json:"sticky_count,omitempty"`
+}
+
+func (m *RoutingMetadata) validate() error {
+	if m.IntentConfidence < 0 || m.IntentConfidence > 1 || math.IsNaN(float64(m.IntentConfidence)) {
+		return fmt.Errorf("intent_confidence must be between 0 and 1")
+	}
+	if m.RouteMethod != "" && !slices.Contains(routeMethods, m.RouteMethod) {
+		return fmt.Errorf("unknown route_method %q", m.RouteMethod)
+	}
+	if m.StickyUntil < 0 || m.StickyCount < 0 {
+		return fmt.Errorf("sticky_until and sticky_count must not be negative")
+	}
+	return nil
+}
+
+// ForkMetadata is the fork section of the blocks created by ForkBlock.
+type ForkMetadata struct {
+	Version    int    `json:"version"`
+	ForkedFrom int64  `json:"forked_from"` // ID of the parent block
+	Reason     string `json:"reason"`
+	Type       string `json:"type"` // ForkTypeEdit or ForkTypeBranch
+}
+
+func (m *ForkMetadata) validate() error {
+	if m.ForkedFrom <= 0 {
+		return fmt.Errorf("forked_from must be a block id")
+	}
+	if m.Reason == "" {
+		return fmt.Errorf("fork reason cannot be empty")
+	}
+	if m.Type != ForkTypeEdit && m.Type != ForkTypeBranch {
+		return fmt.Errorf("unknown fork type %q", m.Type)
+	}
+	return nil
+}
+
+// BudgetMetadata is the budget section of the blocks stopped by their budget.
+type BudgetMetadata struct {
+	Version int          `json:"version"`
+	Budget  BudgetLimits `json:"budget"`
+	// Usage is the usage of the block when it was stopped.
+	Usage BudgetUsage `json:"usage"`
+	// PartialContent is the answer produced before the stop.
+	PartialContent string `json:"partial_content,omitempty"`
+}
+
+// BudgetLimits are the limits of the budget of a block; zero is no limit.
+type BudgetLimits struct {
+	MaxTokens  int     `json:"max_tokens"`
+	MaxCostUSD float64 `json:"max_cost_usd"`
+}
+
+// BudgetUsage is the usage of a block stopped by its budget.
+type BudgetUsage struct {
+	InputTokens      int     `json:"input_tokens"`
+	OutputTokens     int     `json:"output_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+	Estimated        bool    `json:"estimated,omitempty"`
+	Model            string  `json:"model,omitempty"`
+	ElapsedMs        int64   `json:"elapsed_ms"`
+}
+
+func (m *BudgetMetadata) validate() error {
+	if m.Budget.MaxTokens < 0 || m.Budget.MaxCostUSD < 0 {
+		return fmt.Errorf("budget limits must not be negative")
+	}
+	u := m.Usage
+	if u.InputTokens < 0 || u.OutputTokens < 0 || u.TotalTokens < 0 || u.EstimatedCostUSD < 0 || u.ElapsedMs < 0 {
+		return fmt.Errorf("usage must not be negative")
+	}
+	return nil
+}
+
+// CitationsMetadata is the citations section: the memos cited by the answer.
+type CitationsMetadata struct {
+	Version int      `json:"version"`
+	Memos   []string `json:"memos"` // UIDs, in citation order
+	// Document is the document the conversation was bound to, if any.
+	Document *DocumentCitation `json:"document,omitempty"`
+}
+
+// DocumentCitation is the single document a conversation is about and the
+// passages of it the answer cites.
+type DocumentCitation struct {
+	Kind  string `json:"kind"` // "memo" or "attachment"
+	UID   string `json:"uid"`
+	Title string `json:"title"`
+	// Spans are the cited passages, in citation order.
+	Spans []CitationSpan `json:"spans"`
+}
+
+// CitationSpan is a cited passage: its character (rune) offsets in the
+// document, end excluded, and its text when cited.
+type CitationSpan struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Text  string `json:"text"`
+}
+
+func (m *CitationsMetadata) validate() error {
+	for i, uid := range m.Memos {
+		if uid == "" {
+			return fmt.Errorf("memo uid cannot be empty")
+		}
+		if slices.Contains(m.Memos[:i], uid) {
+			return fmt.Errorf("memo %s is cited twice", uid)
+		}
+	}
+	if doc := m.Document; doc != nil {
+		if doc.Kind != "memo" && doc.Kind != "attachment" {
+			return fmt.Errorf("unknown document kind %q", doc.Kind)
+		}
+		if doc.UID == "" {
+			return fmt.Errorf("document uid cannot be empty")
+		}
+		for _, span := range doc.Spans {
+			if span.Start < 0 || span.End <= span.Start {
+				return fmt.Errorf("invalid document span %d-%d", span.Start, span.End)
+			}
+		}
+	}
+	return nil
+}
+
+// Import sources of ImportMetadata.
+const (
+	ImportSourceChatGPT = "chatgpt"
+	ImportSourceClaude  = "claude"
+)
+
+// ImportMetadata is the import section of the blocks imported from the
+// transcripts exported by other assistants.
+type ImportMetadata struct {
+	Version int    `json:"version"`
+	Source  string `json:"source"` // ImportSourceChatGPT or ImportSourceClaude
+	// MessageIDs are the IDs of the messages of the round in the export.
+	MessageIDs []string `json:"message_ids,omitempty"`
+}
+
+func (m *ImportMetadata) validate() error {
+	if m.Source != ImportSourceChatGPT && m.Source != ImportSourceClaude {
+		return fmt.Errorf("unknown import source %q", m.Source)
+	}
+	return nil
+}
+
+// metadataSection is a section schema.
+type metadataSection interface {
+	validate() error
+}
+
+// metadataSections are the section schemas by key: the current version and
+// the section type.
+var metadataSections = map[string]struct {
+	version int
+	new     func() metadataSection
+}{
+	MetadataSectionRouting:   {RoutingMetadataVersion, func() metadataSection { return &RoutingMetadata{} }},
+	MetadataSectionFork:      {ForkMetadataVersion, func() metadataSection { return &ForkMetadata{} }},
+	MetadataSectionBudget:    {BudgetMetadataVersion, func() metadataSection { return &BudgetMetadata{} }},
+	MetadataSectionCitations: {CitationsMetadataVersion, func() metadataSection { return &CitationsMetadata{} }},
+	MetadataSectionImport:    {ImportMetadataVersion, func() metadataSection { return &ImportMetadata{} }},
+}
+
+// ValidateBlockMetadata checks the sections of block metadata against their
+// schema: no unknown field, values of the right type, a supported version and
+// valid values. A nil section clears it and is valid.
+func ValidateBlockMetadata(metadata map[string]any) error {
+	for key, value := range metadata {
+		schema, ok := metadataSections[key]
+		if !ok || value == nil {
+			continue
+		}
+		section := schema.new()
+		if err := decodeMetadataSection(value, section, true); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidBlockMetadata, key, err)
+		}
+		version := sectionVersion(section)
+		if version < 1 || version > schema.version {
+			return fmt.Errorf("%w: %s: unsupported version %d", ErrInvalidBlockMetadata, key, version)
+		}
+		if err := section.validate(); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidBlockMetadata, key, err)
+		}
+	}
+	return nil
+}
+
+func sectionVersion(section metadataSection) int {
+	switch s := section.(type) {
+	case *RoutingMetadata:
+		return s.Version
+	case *ForkMetadata:
+		return s.Version
+	case *BudgetMetadata:
+		return s.Version
+	case *CitationsMetadata:
+		return s.Version
+	case *ImportMetadata:
+		return s.Version
+	}
+	return 0
+}
+
+// decodeMetadataSection decodes a metadata value, a section or its JSON
+// object, into a section. Strict decoding rejects unknown fields.
+func decodeMetadataSection(value any, section any, strict bool) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder.Decode(section)
+}
+
+// metadataSectionOf decodes the section of a key of block metadata, reporting
+// whether the block has it.
+func metadataSectionOf(metadata map[string]any, key string, section any) bool {
+	value, ok := metadata[key]
+	if !ok || value == nil {
+		return false
+	}
+	return decodeMetadataSection(value, section, false) == nil
+}
+
+// Routing returns the routing section of the block. Blocks written before the
+// sections hold its fields as top-level keys, which are read instead.
+func (b *AIBlock) Routing() (*RoutingMetadata, bool) {
+	routing := &RoutingMetadata{}
+	if metadataSectionOf(b.Metadata, MetadataSectionRouting, routing) {
+		return routing, true
+	}
+	if b.Metadata == nil {
+		return nil, false
+	}
+	routing.LastAgent, _ = b.Metadata[MetadataKeyLastAgent].(string)
+	routing.Intent, _ = b.Metadata[MetadataKeyIntent].(string)
+	routing.RouteMethod, _ = b.Metadata[MetadataKeyRouteMethod].(string)
+	if v, ok := b.Metadata[MetadataKeyIntentConfidence].(float64); ok {
+		routing.IntentConfidence = float32(v)
+	}
+	if v, ok := b.Metadata[MetadataKeyStickyUntil].(float64); ok {
+		routing.StickyUntil = int64(v)
+	}
+	if v, ok := b.Metadata[MetadataKeyStickyCount].(float64); ok {
+		routing.StickyCount = int(v)
+	}
+	if *routing == (RoutingMetadata{}) {
+		return nil, false
+	}
+	return routing, true
+}
+
+// Fork returns the fork section of a forked block, read from the top-level
+// keys of the blocks forked before the sections.
+func (b *AIBlock) Fork() (*ForkMetadata, bool) {
+	fork := &ForkMetadata{}
+	if metadataSectionOf(b.Metadata, MetadataSectionFork, fork) {
+		return fork, true
+	}
+	forkedFrom, ok := b.Metadata[legacyMetadataKeyForkedFrom].(float64)
+	if !ok {
+		return nil, false
+	}
+	fork.ForkedFrom = int64(forkedFrom)
+	fork.Reason, _ = b.Metadata[legacyMetadataKeyForkReason].(string)
+	fork.Type, _ = b.Metadata[legacyMetadataKeyForkType].(string)
+	return fork, true
+}
+
+// Budget returns the budget section of a block stopped by its budget, read
+// from the budget_exceeded key of the blocks stopped before the sections.
+func (b *AIBlock) Budget() (*BudgetMetadata, bool) {
+	budget := &BudgetMetadata{}
+	if metadataSectionOf(b.Metadata, MetadataSectionBudget, budget) ||
+		metadataSectionOf(b.Metadata, legacyMetadataKeyBudgetExceeded, budget) {
+		return budget, true
+	}
+	return nil, false
+}
+
+// Citations returns the citations section of the block.
+func (b *AIBlock) Citations() (*CitationsMetadata, bool) {
+	citations := &CitationsMetadata{}
+	if metadataSectionOf(b.Metadata, MetadataSectionCitations, citations) {
+		return citations, true
+	}
+	return nil, false
+}
+
+// setMetadataSection sets a section in update metadata, which replaces the
+// section of the block as a whole.
+func (u *UpdateAIBlock) setMetadataSection(key string, section any) {
+	if u.Metadata == nil {
+		u.Metadata = make(map[string]any)
+	}
+	u.Metadata[key] = section
+}
+
+// routing returns the routing section of the update, adding it if needed.
+func (u *UpdateAIBlock) routing() *RoutingMetadata {
+	if routing, ok := u.Metadata[MetadataSectionRouting].(*RoutingMetadata); ok {
+		return routing
+	}
+	routing := &RoutingMetadata{Version: RoutingMetadataVersion}
+	u.setMetadataSection(MetadataSectionRouting, routing)
+	return routing
+}
+
+// SetRouting sets the routing section, replacing the one of the block.
+func (u *UpdateAIBlock) SetRouting(routing *RoutingMetadata) {
+	routing.Version = RoutingMetadataVersion
+	u.setMetadataSection(MetadataSectionRouting, routing)
+}
+
+// SetBudget sets the budget section of a block stopped by its budget.
+func (u *UpdateAIBlock) SetBudget(budget *BudgetMetadata) {
+	budget.Version = BudgetMetadataVersion
+	u.setMetadataSection(MetadataSectionBudget, budget)
+}
+
+// SetCitations sets the citations section.
+func (u *UpdateAIBlock) SetCitations(citations *CitationsMetadata) {
+	citations.Version = CitationsMetadataVersion
+	u.setMetadataSection(MetadataSectionCitations, citations)
+}
+
+// NewForkMetadata returns the fork section of a block forked from a parent,
+// with the parent's user inputs replaced (edit) or inherited (branch).
+func NewForkMetadata(parentID int64, reason string, replaced bool) *ForkMetadata {
+	fork := &ForkMetadata{Version: ForkMetadataVersion, ForkedFrom: parentID, Reason: reason, Type: ForkTypeBranch}
+	if replaced {
+		fork.Type = ForkTypeEdit
+	}
+	return fork
+}
+
+// ForkedMetadata returns the metadata of a block forked from a parent: the
+// parent's metadata without its fork fields, and the fork section.
+func ForkedMetadata(parent map[string]any, fork *ForkMetadata) map[string]any {
+	metadata := make(map[string]any, len(parent)+1)
+	for k, v := range parent {
+		metadata[k] = v
+	}
+	for _, key := range []string{legacyMetadataKeyForkedFrom, legacyMetadataKeyForkReason, legacyMetadataKeyForkType} {
+		delete(metadata, key)
+	}
+	metadata[MetadataSectionFork] = fork
+	return metadata
+}
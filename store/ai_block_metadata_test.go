@@ -0,0 +1,119 @@
+package store
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTrip returns metadata as read back from the JSONB column.
+func roundTrip(t *testing.T, metadata map[string]any) map[string]any {
+	t.Helper()
+	data, err := json.Marshal(metadata)
+	require.NoError(t, err)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	return decoded
+}
+
+func TestValidateBlockMetadata(t *testing.T) {
+	update := &UpdateAIBlock{}
+	update.SetMetadataLastAgent("memo")
+	update.SetMetadataIntentConfidence(0.9)
+	update.SetBudget(&BudgetMetadata{Budget: BudgetLimits{MaxTokens: 1000}, Usage: BudgetUsage{TotalTokens: 1200}})
+	update.SetCitations(&CitationsMetadata{Memos: []string{"a1", "b2"}})
+	update.Metadata["suggestions"] = []string{"free-form keys are not checked"}
+	update.Metadata[MetadataSectionFork] = nil // Clears the section
+	require.NoError(t, ValidateBlockMetadata(update.Metadata))
+	require.NoError(t, ValidateBlockMetadata(roundTrip(t, update.Metadata)))
+	require.NoError(t, ValidateBlockMetadata(nil))
+
+	for name, metadata := range map[string]map[string]any{
+		"misspelt field": {MetadataSectionRouting: map[string]any{"version": 1, "last_agnet": "memo"}},
+		"wrong type":     {MetadataSectionRouting: map[string]any{"version": 1, "sticky_until": "tomorrow"}},
+		"not an object":  {MetadataSectionCitations: "a1"},
+		"no version":     {MetadataSectionCitations: map[string]any{"memos": []string{"a1"}}},
+		"newer version":  {MetadataSectionCitations: map[string]any{"version": 2, "memos": []string{"a1"}}},
+		"confidence":     {MetadataSectionRouting: &RoutingMetadata{Version: 1, IntentConfidence: 1.5}},
+		"route method":   {MetadataSectionRouting: &RoutingMetadata{Version: 1, RouteMethod: "guess"}},
+		"fork type":      {MetadataSectionFork: &ForkMetadata{Version: 1, ForkedFrom: 3, Reason: "r", Type: "copy"}},
+		"fork reason":    {MetadataSectionFork: NewForkMetadata(3, "", false)},
+		"budget":         {MetadataSectionBudget: &BudgetMetadata{Version: 1, Budget: BudgetLimits{MaxCostUSD: -1}}},
+		"cited twice":    {MetadataSectionCitations: &CitationsMetadata{Version: 1, Memos: []string{"a1", "a1"}}},
+		"document kind":  {MetadataSectionCitations: &CitationsMetadata{Version: 1, Document: &DocumentCitation{Kind: "space", UID: "d1"}}},
+		"document span": {MetadataSectionCitations: &CitationsMetadata{Version: 1, Document: &DocumentCitation{Kind: "memo", UID: "d1",
+			Spans: []CitationSpan{{Start: 40, End: 10}}}}},
+		"import source": {MetadataSectionImport: &ImportMetadata{Version: 1, Source: "gemini"}},
+	} {
+		assert.ErrorIs(t, ValidateBlockMetadata(metadata), ErrInvalidBlockMetadata, name)
+	}
+}
+
+func TestAIBlock_MetadataSections(t *testing.T) {
+	update := &UpdateAIBlock{}
+	update.SetMetadataLastAgent("schedule")
+	update.SetMetadataIntent("create")
+	update.SetMetadataIntentConfidence(0.75)
+	update.SetMetadataStickyUntil(1700000000)
+	update.SetBudget(&BudgetMetadata{Usage: BudgetUsage{TotalTokens: 42}, PartialContent: "partial"})
+
+	for _, metadata := range []map[string]any{update.Metadata, roundTrip(t, update.Metadata)} {
+		block := &AIBlock{Metadata: metadata}
+		routing, ok := block.Routing()
+		require.True(t, ok)
+		assert.Equal(t, &RoutingMetadata{Version: 1, LastAgent: "schedule", Intent: "create", IntentConfidence: 0.75, StickyUntil: 1700000000}, routing)
+		agent, _ := block.GetMetadataLastAgent()
+		assert.Equal(t, "schedule", agent)
+		budget, ok := block.Budget()
+		require.True(t, ok)
+		assert.Equal(t, 42, budget.Usage.TotalTokens)
+		_, ok = block.Fork()
+		assert.False(t, ok)
+		_, ok = block.Citations()
+		assert.False(t, ok)
+	}
+
+	forked := &AIBlock{Metadata: ForkedMetadata(update.Metadata, NewForkMetadata(7, "user_fork", true))}
+	fork, ok := forked.Fork()
+	require.True(t, ok)
+	assert.Equal(t, &ForkMetadata{Version: 1, ForkedFrom: 7, Reason: "user_fork", Type: ForkTypeEdit}, fork)
+	_, ok = forked.Routing()
+	assert.True(t, ok, "forks inherit the metadata of their parent")
+}
+
+func TestAIBlock_LegacyMetadata(t *testing.T) {
+	// Blocks written before the sections hold their fields as top-level keys
+	block := &AIBlock{Metadata: roundTrip(t, map[string]any{
+		MetadataKeyLastAgent:        "memo",
+		MetadataKeyIntentConfidence: 0.8,
+		MetadataKeyStickyUntil:      1700000000,
+		"forked_from":               12,
+		"fork_reason":               "user_fork",
+		"fork_type":                 "branch",
+		"budget_exceeded": map[string]any{
+			"budget": map[string]any{"max_tokens": 100},
+			"usage":  map[string]any{"total_tokens": 150},
+		},
+	})}
+
+	routing, ok := block.Routing()
+	require.True(t, ok)
+	assert.Equal(t, &RoutingMetadata{LastAgent: "memo", IntentConfidence: 0.8, StickyUntil: 1700000000}, routing)
+	fork, ok := block.Fork()
+	require.True(t, ok)
+	assert.Equal(t, &ForkMetadata{ForkedFrom: 12, Reason: "user_fork", Type: ForkTypeBranch}, fork)
+	budget, ok := block.Budget()
+	require.True(t, ok)
+	assert.Equal(t, 100, budget.Budget.MaxTokens)
+	assert.Equal(t, 150, budget.Usage.TotalTokens)
+
+	// Forking drops the legacy fork keys of the parent
+	forked := ForkedMetadata(block.Metadata, NewForkMetadata(13, "again", false))
+	assert.NotContains(t, forked, "forked_from")
+	assert.Contains(t, forked, MetadataKeyLastAgent)
+
+	_, ok = (&AIBlock{}).Routing()
+	assert.False(t, ok)
+}
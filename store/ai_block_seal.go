@@ -0,0 +1,246 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/hrygo/divinesense/store/cache"
+)
+
+// ErrConversationLocked is returned when the blocks of a locked conversation
+// are written while the conversation is not unlocked.
+var ErrConversationLocked = errors.New("conversation is locked")
+
+// sealedEventMetaKeys are the free-text event metadata sealed with the event
+// contents. Tool names, statuses and timings are kept in clear.
+var sealedEventMetaKeys = []string{"input_summary", "output_summary", "error_msg", "file_path"}
+
+// BlockSealer encrypts the blocks of locked conversations: user inputs,
+// assistant content, error messages and event contents are stored sealed,
+// and read back in clear only while their conversation is unlocked.
+type BlockSealer interface {
+	// HasLocks reports whether any conversation is locked.
+	HasLocks() bool
+	// Locked reports whether a conversation is locked.
+	Locked(conversationID int32) bool
+	// Unlocked reports whether the key of a locked conversation is held.
+	Unlocked(conversationID int32) bool
+	// Seal encrypts a content of a locked conversation, returning
+	// ErrConversationLocked while it is not unlocked.
+	Seal(conversationID int32, content string) (string, error)
+	// Open decrypts a content sealed by Seal; contents stored before the
+	// lock are returned as is.
+	Open(conversationID int32, content string) (string, error)
+}
+
+// SetBlockSealer enables the encryption of the blocks of locked conversations.
+func (s *Store) SetBlockSealer(sealer BlockSealer) {
+	s.blockSealer = sealer
+	s.blockConversationCache = cache.New(cache.Config{
+		DefaultTTL:      time.Hour,
+		CleanupInterval: 10 * time.Minute,
+		MaxItems:        10000,
+	})
+}
+
+// ConversationLockState reports whether a conversation is locked, and whether
+// it is unlocked.
+func (s *Store) ConversationLockState(conversationID int32) (locked, unlocked bool) {
+	if s.blockSealer == nil || !s.blockSealer.Locked(conversationID) {
+		return false, false
+	}
+	return true, s.blockSealer.Unlocked(conversationID)
+}
+
+// sealingConversation returns the conversation of a block when its contents
+// must be sealed.
+func (s *Store) sealingConversation(ctx context.Context, blockID int64) (int32, bool, error) {
+	if s.blockSealer == nil || !s.blockSealer.HasLocks() {
+		return 0, false, nil
+	}
+	key := strconv.FormatInt(blockID, 10)
+	if v, ok := s.blockConversationCache.Get(ctx, key); ok {
+		conversationID := v.(int32)
+		return conversationID, s.blockSealer.Locked(conversationID), nil
+	}
+	block, err := s.driver.GetAIBlock(ctx, blockID)
+	if err != nil {
+		return 0, false, err
+	}
+	if block == nil {
+		return 0, false, nil
+	}
+	s.blockConversationCache.Set(ctx, key, block.ConversationID)
+	return block.ConversationID, s.blockSealer.Locked(block.ConversationID), nil
+}
+
+// openBlocks caches the conversations of blocks, and opens the blocks of
+// locked conversations.
+func (s *Store) openBlocks(ctx context.Context, blocks ...*AIBlock) {
+	if s.blockSealer == nil {
+		return
+	}
+	for _, block := range blocks {
+		if block == nil {
+			continue
+		}
+		s.blockConversationCache.Set(ctx, strconv.FormatInt(block.ID, 10), block.ConversationID)
+		if s.blockSealer.Locked(block.ConversationID) {
+			OpenBlock(s.blockSealer, block)
+		}
+	}
+}
+
+// OpenBlock decrypts the sealed contents of a block. While the conversation
+// is not unlocked, the contents are cleared and the block is marked Locked.
+func OpenBlock(sealer BlockSealer, block *AIBlock) {
+	id := block.ConversationID
+	failed := false
+	open := func(content string) string {
+		if content == "" || failed {
+			return ""
+		}
+		opened, err := sealer.Open(id, content)
+		if err != nil {
+			failed = true
+			return ""
+		}
+		return opened
+	}
+
+	for i := range block.UserInputs {
+		block.UserInputs[i].Content = open(block.UserInputs[i].Content)
+	}
+	block.AssistantContent = open(block.AssistantContent)
+	block.ErrorMessage = open(block.ErrorMessage)
+	for i := range block.EventStream {
+		event := &block.EventStream[i]
+		event.Content = open(event.Content)
+		for _, key := range sealedEventMetaKeys {
+			if value, ok := event.Meta[key].(string); ok {
+				event.Meta[key] = open(value)
+			}
+		}
+	}
+	if !failed {
+		return
+	}
+
+	// Never return a mix of opened and sealed contents
+	for i := range block.UserInputs {
+		block.UserInputs[i].Content = ""
+	}
+	block.AssistantContent = ""
+	block.ErrorMessage = ""
+	for i := range block.EventStream {
+		event := &block.EventStream[i]
+		event.Content = ""
+		for _, key := range sealedEventMetaKeys {
+			delete(event.Meta, key)
+		}
+	}
+	block.Locked = true
+}
+
+// sealUserInputs returns sealed copies of user inputs.
+func sealUserInputs(sealer BlockSealer, conversationID int32, inputs []UserInput) ([]UserInput, error) {
+	sealed := make([]UserInput, len(inputs))
+	for i, input := range inputs {
+		content, err := sealer.Seal(conversationID, input.Content)
+		if err != nil {
+			return nil, err
+		}
+		input.Content = content
+		sealed[i] = input
+	}
+	return sealed, nil
+}
+
+// sealEvents returns sealed copies of events.
+func sealEvents(sealer BlockSealer, conversationID int32, events []BlockEvent) ([]BlockEvent, error) {
+	sealed := make([]BlockEvent, len(events))
+	for i, event := range events {
+		content, err := sealer.Seal(conversationID, event.Content)
+		if err != nil {
+			return nil, err
+		}
+		event.Content = content
+		if len(event.Meta) > 0 {
+			meta := make(map[string]any, len(event.Meta))
+			for k, v := range event.Meta {
+				meta[k] = v
+			}
+			for _, key := range sealedEventMetaKeys {
+				if value, ok := meta[key].(string); ok && value != "" {
+					if meta[key], err = sealer.Seal(conversationID, value); err != nil {
+						return nil, err
+					}
+				}
+			}
+			event.Meta = meta
+		}
+		sealed[i] = event
+	}
+	return sealed, nil
+}
+
+// sealString seals an optional content.
+func sealString(sealer BlockSealer, conversationID int32, content *string) (*string, error) {
+	if content == nil || *content == "" {
+		return content, nil
+	}
+	sealed, err := sealer.Seal(conversationID, *content)
+	if err != nil {
+		return nil, err
+	}
+	return &sealed, nil
+}
+
+// sealCreate returns a copy of a block creation with sealed user inputs.
+func (s *Store) sealCreate(create *CreateAIBlock) (*CreateAIBlock, error) {
+	if s.blockSealer == nil || !s.blockSealer.Locked(create.ConversationID) {
+		return create, nil
+	}
+	inputs, err := sealUserInputs(s.blockSealer, create.ConversationID, create.UserInputs)
+	if err != nil {
+		return nil, err
+	}
+	sealed := *create
+	sealed.UserInputs = inputs
+	return &sealed, nil
+}
+
+// sealUpdate returns a copy of a block update with sealed contents.
+func (s *Store) sealUpdate(ctx context.Context, update *UpdateAIBlock) (*UpdateAIBlock, error) {
+	if update.UserInputs == nil && update.AssistantContent == nil && update.EventStream == nil && update.ErrorMessage == nil {
+		return update, nil
+	}
+	conversationID, locked, err := s.sealingConversation(ctx, update.ID)
+	if err != nil || !locked {
+		return update, err
+	}
+	sealed := *update
+	if update.UserInputs != nil {
+		inputs, err := sealUserInputs(s.blockSealer, conversationID, *update.UserInputs)
+		if err != nil {
+			return nil, err
+		}
+		sealed.UserInputs = &inputs
+	}
+	if update.EventStream != nil {
+		events, err := sealEvents(s.blockSealer, conversationID, *update.EventStream)
+		if err != nil {
+			return nil, err
+		}
+		sealed.EventStream = &events
+	}
+	if sealed.AssistantContent, err = sealString(s.blockSealer, conversationID, update.AssistantContent); err != nil {
+		return nil, err
+	}
+	if sealed.ErrorMessage, err = sealString(s.blockSealer, conversationID, update.ErrorMessage); err != nil {
+		return nil, err
+	}
+	return &sealed, nil
+}
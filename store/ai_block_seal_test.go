@@ -0,0 +1,93 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reverseSealer seals contents by reversing them.
+type reverseSealer struct {
+	unlocked bool
+}
+
+func (s *reverseSealer) HasLocks() bool                     { return true }
+func (s *reverseSealer) Locked(conversationID int32) bool   { return true }
+func (s *reverseSealer) Unlocked(conversationID int32) bool { return s.unlocked }
+
+func (s *reverseSealer) Seal(_ int32, content string) (string, error) {
+	if !s.unlocked {
+		return "", ErrConversationLocked
+	}
+	return "sealed:" + reverse(content), nil
+}
+
+func (s *reverseSealer) Open(_ int32, content string) (string, error) {
+	sealed, ok := strings.CutPrefix(content, "sealed:")
+	if !ok {
+		return content, nil
+	}
+	if !s.unlocked {
+		return "", ErrConversationLocked
+	}
+	return reverse(sealed), nil
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func sealedBlock(t *testing.T, sealer BlockSealer) *AIBlock {
+	t.Helper()
+	inputs, err := sealUserInputs(sealer, 1, []UserInput{{Content: "question"}})
+	require.NoError(t, err)
+	events, err := sealEvents(sealer, 1, []BlockEvent{
+		{Type: "tool_use", Content: "search notes", Meta: map[string]any{"tool_name": "memo_search", "input_summary": "notes"}},
+	})
+	require.NoError(t, err)
+	answer, err := sealer.Seal(1, "answer")
+	require.NoError(t, err)
+	return &AIBlock{ConversationID: 1, UserInputs: inputs, AssistantContent: answer, EventStream: events}
+}
+
+func TestSealEvents_MetadataInClear(t *testing.T) {
+	block := sealedBlock(t, &reverseSealer{unlocked: true})
+	assert.Equal(t, "sealed:noitseuq", block.UserInputs[0].Content)
+	assert.Equal(t, "sealed:seton hcraes", block.EventStream[0].Content)
+	assert.Equal(t, "sealed:seton", block.EventStream[0].Meta["input_summary"])
+	assert.Equal(t, "memo_search", block.EventStream[0].Meta["tool_name"])
+}
+
+func TestOpenBlock(t *testing.T) {
+	sealer := &reverseSealer{unlocked: true}
+
+	block := sealedBlock(t, sealer)
+	OpenBlock(sealer, block)
+	assert.False(t, block.Locked)
+	assert.Equal(t, "question", block.UserInputs[0].Content)
+	assert.Equal(t, "answer", block.AssistantContent)
+	assert.Equal(t, "search notes", block.EventStream[0].Content)
+	assert.Equal(t, "notes", block.EventStream[0].Meta["input_summary"])
+
+	// Without the key, no content is returned, sealed or not
+	block = sealedBlock(t, sealer)
+	block.ErrorMessage = "stored before the lock"
+	sealer.unlocked = false
+	OpenBlock(sealer, block)
+	assert.True(t, block.Locked)
+	assert.Empty(t, block.UserInputs[0].Content)
+	assert.Empty(t, block.AssistantContent)
+	assert.Empty(t, block.ErrorMessage)
+	assert.Empty(t, block.EventStream[0].Content)
+	assert.NotContains(t, block.EventStream[0].Meta, "input_summary")
+	assert.Equal(t, "memo_search", block.EventStream[0].Meta["tool_name"])
+
+	_, err := sealUserInputs(sealer, 1, []UserInput{{Content: "more"}})
+	assert.ErrorIs(t, err, ErrConversationLocked)
+}
@@ -48,5 +48,13 @@ type DeleteAIConversation struct {
 	ID int32
 }
 
+// ImportAIConversation is a conversation imported from another assistant:
+// the conversation and its blocks, in round order. The blocks keep their
+// round numbers and timestamps.
+type ImportAIConversation struct {
+	Conversation *AIConversation
+	Blocks       []*AIBlock
+}
+
 // AIMessage types removed: ALL IN Block!
 // Use AIBlock from ai_block.go instead for all conversation persistence.
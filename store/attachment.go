@@ -45,9 +45,13 @@ type FindAttachment struct {
 	Limit          *int
 	Offset         *int
 	MemoIDList     []int32
+	TypeList       []string
 	Filters        []string
 	GetBlob        bool
 	HasRelatedMemo bool
+	// TextPending finds attachments whose text has not been extracted yet.
+	// Only PostgreSQL stores attachment text; other drivers find none.
+	TextPending bool
 }
 
 type UpdateAttachment struct {
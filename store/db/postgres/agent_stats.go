@@ -266,6 +266,41 @@ func (d *DB) GetDailyCostUsage(ctx context.Context, userID int32, startDate, end
 	return totalCost, nil
 }
 
+// GetUsageTotals retrieves the sessions and chat blocks of a user started in [start, end).
+func (d *DB) GetUsageTotals(ctx context.Context, userID int32, start, end time.Time) (*store.UsageTotals, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM agent_session_stats
+				WHERE user_id = $1 AND started_at >= $2 AND started_at < $3),
+			(SELECT COALESCE(SUM(total_tokens), 0) FROM agent_session_stats
+				WHERE user_id = $1 AND started_at >= $2 AND started_at < $3),
+			(SELECT COALESCE(SUM(total_cost_usd), 0)::FLOAT8 FROM agent_session_stats
+				WHERE user_id = $1 AND started_at >= $2 AND started_at < $3),
+			COUNT(b.id),
+			COALESCE(SUM((b.token_usage->>'prompt_tokens')::BIGINT), 0),
+			COALESCE(SUM((b.token_usage->>'completion_tokens')::BIGINT), 0)
+		FROM ai_block b
+		JOIN ai_conversation c ON c.id = b.conversation_id
+		WHERE c.creator_id = $1 AND b.block_type = 'message' AND b.mode = 'normal'
+		  AND b.created_ts >= $4 AND b.created_ts < $5
+	`
+
+	totals := &store.UsageTotals{}
+	err := d.db.QueryRowContext(ctx, query, userID, start, end, start.UnixMilli(), end.UnixMilli()).Scan(
+		&totals.SessionCount,
+		&totals.SessionTokens,
+		&totals.SessionCostUSD,
+		&totals.ChatCount,
+		&totals.ChatInputTokens,
+		&totals.ChatOutputTokens,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage totals: %w", err)
+	}
+
+	return totals, nil
+}
+
 // GetCostStats retrieves aggregated cost statistics.
 func (d *DB) GetCostStats(ctx context.Context, userID int32, days int) (*store.CostStats, error) {
 	startDate := time.Now().AddDate(0, 0, -days).Truncate(24 * time.Hour)
@@ -320,6 +320,9 @@ func (d *DB) ListAIBlocks(ctx context.Context, find *store.FindAIBlock) ([]*stor
 	if find.ParentBlockID != nil {
 		where, args = append(where, "parent_block_id = "+placeholder(len(args)+1)), append(args, *find.ParentBlockID)
 	}
+	if find.UpdatedAfter != nil {
+		where, args = append(where, "updated_ts > "+placeholder(len(args)+1)), append(args, *find.UpdatedAfter)
+	}
 
 	query := `
 		SELECT id, uid, conversation_id, round_number, block_type, mode,
@@ -649,6 +652,34 @@ func (d *DB) GetLatestAIBlock(ctx context.Context, conversationID int32) (*store
 	return block, nil
 }
 
+// ListAIBlockUIDs retrieves the UIDs of the blocks of a conversation in round order
+func (d *DB) ListAIBlockUIDs(ctx context.Context, conversationID int32) ([]string, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT uid FROM ai_block
+		WHERE conversation_id = $1
+		ORDER BY round_number ASC
+	`, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ai_block uids: %w", err)
+	}
+	defer rows.Close()
+
+	uids := make([]string, 0)
+	for rows.Next() {
+		var uid string
+		if err := rows.Scan(&uid); err != nil {
+			return nil, fmt.Errorf("failed to scan ai_block uid: %w", err)
+		}
+		uids = append(uids, uid)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate ai_block uids: %w", err)
+	}
+
+	return uids, nil
+}
+
 // GetPendingAIBlocks retrieves all pending/streaming blocks for cleanup
 func (d *DB) GetPendingAIBlocks(ctx context.Context) ([]*store.AIBlock, error) {
 	query := `
@@ -894,18 +925,9 @@ func (d *DB) ForkBlock(ctx context.Context, parentID int64, reason string, repla
 		userInputs = replaceUserInputs
 	}
 
-	// Prepare metadata: inherit parent's metadata and add fork reason
-	metadata := make(map[string]any)
-	for k, v := range parent.Metadata {
-		metadata[k] = v
-	}
-	metadata["forked_from"] = parentID
-	metadata["fork_reason"] = reason
-	if replaceUserInputs != nil && len(replaceUserInputs) > 0 {
-		metadata["fork_type"] = "edit"
-	} else {
-		metadata["fork_type"] = "branch"
-	}
+	// Prepare metadata: inherit parent's metadata and add the fork section
+	metadata := store.ForkedMetadata(parent.Metadata,
+		store.NewForkMetadata(parentID, reason, replaceUserInputs != nil && len(replaceUserInputs) > 0))
 
 	// Create a new block with the parent's conversation ID and user inputs
 	// The database trigger will auto-generate the branch_path based on parent_block_id
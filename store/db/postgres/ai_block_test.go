@@ -366,18 +366,9 @@ func (m *mockAIBlockStore) ForkBlock(ctx context.Context, parentID int64, reason
 		userInputs = replaceUserInputs
 	}
 
-	// Prepare metadata: inherit parent's metadata and add fork information
-	metadata := make(map[string]interface{})
-	for k, v := range parent.Metadata {
-		metadata[k] = v
-	}
-	metadata["forked_from"] = parentID
-	metadata["fork_reason"] = reason
-	if replaceUserInputs != nil && len(replaceUserInputs) > 0 {
-		metadata["fork_type"] = "edit"
-	} else {
-		metadata["fork_type"] = "branch"
-	}
+	// Prepare metadata: inherit parent's metadata and add the fork section
+	metadata := store.ForkedMetadata(parent.Metadata,
+		store.NewForkMetadata(parentID, reason, replaceUserInputs != nil && len(replaceUserInputs) > 0))
 
 	block := &store.AIBlock{
 		ID:               id,
@@ -1176,11 +1167,13 @@ func TestForkBlock_WithReplaceUserInputs(t *testing.T) {
 		"Forked block should have the edited message, not the original")
 
 	// Verify metadata contains fork information
-	assert.Equal(t, parent.ID, forkedBlock.Metadata["forked_from"],
+	fork, ok := forkedBlock.Fork()
+	require.True(t, ok, "Metadata should contain the fork section")
+	assert.Equal(t, parent.ID, fork.ForkedFrom,
 		"Metadata should contain parent block ID")
-	assert.Equal(t, forkReason, forkedBlock.Metadata["fork_reason"],
+	assert.Equal(t, forkReason, fork.Reason,
 		"Metadata should contain fork reason")
-	assert.Equal(t, "edit", forkedBlock.Metadata["fork_type"],
+	assert.Equal(t, store.ForkTypeEdit, fork.Type,
 		"Metadata should indicate edit type")
 }
 
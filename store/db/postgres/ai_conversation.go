@@ -3,9 +3,11 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/hrygo/divinesense/internal/util"
 	"github.com/hrygo/divinesense/store"
 )
 
@@ -142,6 +144,61 @@ func (d *DB) DeleteAIConversation(ctx context.Context, delete *store.DeleteAICon
 	return nil
 }
 
+// ImportAIConversation creates a conversation and its blocks in a single
+// transaction. The blocks keep their round numbers and timestamps, which the
+// triggers only fill in when unset.
+func (d *DB) ImportAIConversation(ctx context.Context, imported *store.ImportAIConversation) (*store.AIConversation, error) {
+	conversation := imported.Conversation
+	err := d.execInTx(ctx, func(tx *sql.Tx) error {
+		stmt := `INSERT INTO ai_conversation (uid, creator_id, title, title_source, parrot_id, pinned, created_ts, updated_ts)
+			VALUES (` + placeholders(8) + `)
+			RETURNING id`
+		if err := tx.QueryRowContext(ctx, stmt,
+			conversation.UID, conversation.CreatorID, conversation.Title, conversation.TitleSource,
+			conversation.ParrotID, conversation.Pinned, conversation.CreatedTs, conversation.UpdatedTs,
+		).Scan(&conversation.ID); err != nil {
+			return fmt.Errorf("failed to create ai_conversation: %w", err)
+		}
+
+		for _, block := range imported.Blocks {
+			if block.UID == "" {
+				block.UID = util.GenUUID()
+			}
+			userInputsJSON, err := json.Marshal(block.UserInputs)
+			if err != nil {
+				return fmt.Errorf("failed to marshal user_inputs: %w", err)
+			}
+			metadata := block.Metadata
+			if metadata == nil {
+				metadata = make(map[string]any)
+			}
+			metadataJSON, err := json.Marshal(metadata)
+			if err != nil {
+				return fmt.Errorf("failed to marshal metadata: %w", err)
+			}
+			block.ConversationID = conversation.ID
+			if err := tx.QueryRowContext(ctx, `
+				INSERT INTO ai_block (
+					uid, conversation_id, round_number, block_type, mode,
+					user_inputs, assistant_content, assistant_timestamp,
+					event_stream, status, metadata, created_ts, updated_ts
+				) VALUES (`+placeholders(13)+`)
+				RETURNING id`,
+				block.UID, block.ConversationID, block.RoundNumber, string(block.BlockType), string(block.Mode),
+				userInputsJSON, block.AssistantContent, block.AssistantTimestamp,
+				[]byte("[]"), string(block.Status), metadataJSON, block.CreatedTs, block.UpdatedTs,
+			).Scan(&block.ID); err != nil {
+				return fmt.Errorf("failed to create ai_block of round %d: %w", block.RoundNumber, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conversation, nil
+}
+
 // ai_message functions removed: ALL IN Block!
 // Message persistence is now handled by BlockManager in the main chat flow.
 // - CreateAIMessage (removed)
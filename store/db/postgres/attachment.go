@@ -74,9 +74,20 @@ func (d *DB) ListAttachments(ctx context.Context, find *store.FindAttachment) ([
 		}
 		where = append(where, "attachment.memo_id IN ("+strings.Join(holders, ", ")+")")
 	}
+	if len(find.TypeList) > 0 {
+		holders := make([]string, 0, len(find.TypeList))
+		for _, mimeType := range find.TypeList {
+			holders = append(holders, placeholder(len(args)+1))
+			args = append(args, mimeType)
+		}
+		where = append(where, "attachment.type IN ("+strings.Join(holders, ", ")+")")
+	}
 	if find.HasRelatedMemo {
 		where = append(where, "attachment.memo_id IS NOT NULL")
 	}
+	if find.TextPending {
+		where = append(where, "attachment.ocr_text IS NULL AND attachment.extracted_text IS NULL")
+	}
 	if v := find.StorageType; v != nil {
 		where, args = append(where, "attachment.storage_type = "+placeholder(len(args)+1)), append(args, v.String())
 	}
@@ -2,12 +2,16 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/pgvector/pgvector-go"
 	"github.com/pkg/errors"
 
+	"github.com/hrygo/divinesense/plugin/textsearch"
+	"github.com/hrygo/divinesense/plugin/vectorindex"
 	storepb "github.com/hrygo/divinesense/proto/gen/store"
 	"github.com/hrygo/divinesense/store"
 )
@@ -147,10 +151,30 @@ func (d *DB) VectorSearch(ctx context.Context, opts *store.VectorSearchOptions)
 		LIMIT ` + placeholder(5)
 
 	// Use default model if not specified
-	model := "BAAI/bge-m3"
+	model := opts.Model
+	if model == "" {
+		model = store.DefaultEmbeddingModel
+	}
+
+	// Search with the ef_search or probes of the configured vector index,
+	// set for the transaction of the search only
+	var querier interface {
+		QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	} = d.db
+	if param, value := vectorindex.ConfigFromProfile(d.profile).SearchSetting(); value > 0 {
+		tx, err := d.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to begin vector search")
+		}
+		defer func() { _ = tx.Rollback() }()
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL %s = %d", param, value)); err != nil {
+			return nil, errors.Wrap(err, "failed to set vector search parameter")
+		}
+		querier = tx
+	}
 
 	vector := pgvector.NewVector(opts.Vector)
-	rows, err := d.db.QueryContext(ctx, query,
+	rows, err := querier.QueryContext(ctx, query,
 		vector,
 		opts.UserID,
 		model,
@@ -271,7 +295,7 @@ func (d *DB) FindMemosWithoutEmbedding(ctx context.Context, find *store.FindMemo
 }
 
 // BM25Search performs full-text search using PostgreSQL's ts_vector with BM25 ranking.
-// Uses bilingual text search configuration for better Chinese and English support.
+// The text search configuration follows the language of the query; see textQuery.
 func (d *DB) BM25Search(ctx context.Context, opts *store.BM25SearchOptions) ([]*store.BM25Result, error) {
 	limit := opts.Limit
 	if limit <= 0 {
@@ -279,22 +303,36 @@ func (d *DB) BM25Search(ctx context.Context, opts *store.BM25SearchOptions) ([]*
 	}
 
 	// Build the base query
-	// Use PostgreSQL's full-text search with bilingual support
-	// The to_tsvector_chinese/to_tsquery_chinese functions auto-detect Chinese vs English
+	// Use PostgreSQL's full-text search in the text search configuration of the query language
 	// Note: ts_rank with normalization (32) divides by document length for fairer scoring
+	// The document also includes the OCR/extracted text of the memo's attachments
+	const document = `m.content || ' ' || COALESCE(att.text, '')`
+	vector, tsquery, arg := d.textQuery(document, opts.Query, placeholder(2))
+	args := []any{opts.UserID, arg}
+	score := `ts_rank(` + vector + `, ` + tsquery + `, 32)`
+	match := vector + ` @@ ` + tsquery
+	// Queries full-text search cannot match, such as a lone Chinese character, match substrings
+	if opts.Substring || textsearch.NeedsSubstring(opts.Query) {
+		score, match, args = substringMatch(document, strings.Fields(opts.Query), args[:1])
+	}
 	baseQuery := `
 		SELECT
 			m.id, m.uid, m.creator_id, m.created_ts, m.updated_ts, m.row_status,
 			m.visibility, m.pinned, m.content, m.payload,
-			ts_rank(to_tsvector_chinese(m.content), to_tsquery_chinese(` + placeholder(1) + `), 32) AS score
-		FROM memo m`
+			` + score + ` AS score
+		FROM memo m
+		LEFT JOIN LATERAL (
+			SELECT string_agg(COALESCE(NULLIF(a.ocr_text, ''), a.extracted_text), ' ') AS text
+			FROM attachment a
+			WHERE a.memo_id = m.id AND a.row_status = 'NORMAL'
+		) att ON TRUE`
 
 	// Add LEFT JOIN for comment exclusion if needed
 	joinClause := ""
 	whereClause := `
-		WHERE m.creator_id = ` + placeholder(2) + `
+		WHERE m.creator_id = ` + placeholder(1) + `
 			AND m.row_status = 'NORMAL'
-			AND to_tsvector_chinese(m.content) @@ to_tsquery_chinese(` + placeholder(3) + `)`
+			AND ` + match
 
 	if opts.ExcludeComments {
 		joinClause = `
@@ -303,11 +341,12 @@ func (d *DB) BM25Search(ctx context.Context, opts *store.BM25SearchOptions) ([]*
 			AND mr.memo_id IS NULL`
 	}
 
+	args = append(args, limit)
 	query := baseQuery + joinClause + whereClause + `
 		ORDER BY score DESC, m.updated_ts DESC
-		LIMIT ` + placeholder(4)
+		LIMIT ` + placeholder(len(args))
 
-	rows, err := d.db.QueryContext(ctx, query, opts.Query, opts.UserID, opts.Query, limit)
+	rows, err := d.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to BM25 search")
 	}
@@ -361,3 +400,24 @@ func (d *DB) BM25Search(ctx context.Context, opts *store.BM25SearchOptions) ([]*
 
 	return results, nil
 }
+
+// substringMatch returns the score and the condition of a substring search
+// of document for terms, with the terms appended to args. Every term must
+// occur; the score averages the saturated occurrence count tf/(tf+1.2) of
+// the terms, in [0, 1).
+func substringMatch(document string, terms []string, args []any) (string, string, []any) {
+	if len(terms) == 0 {
+		return "0", "FALSE", args
+	}
+	scores := make([]string, 0, len(terms))
+	matches := make([]string, 0, len(terms))
+	for _, term := range terms {
+		args = append(args, strings.ToLower(term))
+		p := placeholder(len(args))
+		tf := `((char_length(lower(` + document + `)) - char_length(replace(lower(` + document + `), ` + p + `, '')))::float / char_length(` + p + `))`
+		scores = append(scores, tf+` / (`+tf+` + 1.2)`)
+		matches = append(matches, `strpos(lower(`+document+`), `+p+`) > 0`)
+	}
+	return `((` + strings.Join(scores, " + ") + `) / ` + strconv.Itoa(len(terms)) + `)`,
+		`(` + strings.Join(matches, " AND ") + `)`, args
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hrygo/divinesense/plugin/textsearch"
+	storepb "github.com/hrygo/divinesense/proto/gen/store"
+	"github.com/hrygo/divinesense/store"
+)
+
+// likeEscaper escapes the wildcards of ILIKE patterns.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchMemos matches the memo contents with plainto_tsquery in the text
+// search configuration of the query language, ranked by ts_rank normalized
+// by the document length. Queries full-text search cannot match, such as a
+// lone Chinese character, match substrings over the trigram index instead,
+// ranked by word similarity.
+func (d *DB) SearchMemos(ctx context.Context, search *store.SearchMemos) ([]*store.MemoSearchResult, error) {
+	if !textsearch.NeedsSubstring(search.Query) {
+		vector, query, arg := d.textQuery("memo.content", search.Query, "$1")
+		return d.searchMemos(ctx, search, []any{arg}, []string{vector + " @@ " + query}, "ts_rank("+vector+", "+query+", 32)")
+	}
+	terms := strings.Fields(search.Query)
+	if len(terms) == 0 {
+		return []*store.MemoSearchResult{}, nil
+	}
+	args, where := []any{search.Query}, []string{}
+	for _, term := range terms {
+		args = append(args, "%"+likeEscaper.Replace(term)+"%")
+		where = append(where, "memo.content ILIKE "+placeholder(len(args)))
+	}
+	return d.searchMemos(ctx, search, args, where, "word_similarity($1, memo.content)")
+}
+
+// textQuery returns the tsvector of a document and the tsquery of the query
+// bound to placeholder p, in the text search configuration of the query
+// language, with the argument to bind. The default configuration searches
+// the bigrams of the CJK runs; on memo.content, it uses the
+// idx_memo_content_fts index as long as the default configuration is the
+// one of the index.
+func (d *DB) textQuery(document, query, p string) (vector, tsquery, arg string) {
+	config, bigrams := d.textSearch.For(textsearch.Detect(query))
+	if bigrams {
+		return "to_tsvector('" + config + "', cjk_bigrams(" + document + "))", "plainto_tsquery('" + config + "', " + p + ")", textsearch.Bigrams(query)
+	}
+	return "to_tsvector('" + config + "', COALESCE(" + document + ", ''))", "plainto_tsquery('" + config + "', " + p + ")", query
+}
+
+func (d *DB) searchMemos(ctx context.Context, search *store.SearchMemos, args []any, where []string, score string) ([]*store.MemoSearchResult, error) {
+	where = append(where, "memo.row_status = 'NORMAL'")
+	if search.ViewerID > 0 {
+		args = append(args, search.ViewerID)
+		where = append(where, "(memo.creator_id = "+placeholder(len(args))+" OR memo.visibility IN ('PUBLIC', 'PROTECTED'))")
+	} else {
+		where = append(where, "memo.visibility = 'PUBLIC'")
+	}
+	if search.ExcludeComments {
+		where = append(where, "memo_relation.related_memo_id IS NULL")
+	}
+	args = append(args, search.Limit, search.Offset)
+
+	query := `
+		SELECT
+			memo.id, memo.uid, memo.creator_id, memo.created_ts, memo.updated_ts, memo.row_status,
+			memo.visibility, memo.pinned, memo.content, memo.payload, parent_memo.uid,
+			` + score + ` AS score
+		FROM memo
+		LEFT JOIN memo_relation ON memo.id = memo_relation.memo_id AND memo_relation.type = 'COMMENT'
+		LEFT JOIN memo AS parent_memo ON memo_relation.related_memo_id = parent_memo.id
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY score DESC, memo.updated_ts DESC, memo.id DESC
+		LIMIT ` + placeholder(len(args)-1) + ` OFFSET ` + placeholder(len(args))
+
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search memos")
+	}
+	defer rows.Close()
+
+	results := []*store.MemoSearchResult{}
+	for rows.Next() {
+		var memo store.Memo
+		var payloadBytes []byte
+		result := &store.MemoSearchResult{Memo: &memo}
+		if err := rows.Scan(
+			&memo.ID,
+			&memo.UID,
+			&memo.CreatorID,
+			&memo.CreatedTs,
+			&memo.UpdatedTs,
+			&memo.RowStatus,
+			&memo.Visibility,
+			&memo.Pinned,
+			&memo.Content,
+			&payloadBytes,
+			&memo.ParentUID,
+			&result.Score,
+		); err != nil {
+			return nil, errors.Wrap(err, "failed to scan memo search result")
+		}
+		payload := &storepb.MemoPayload{}
+		if err := protojsonUnmarshaler.Unmarshal(payloadBytes, payload); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal payload")
+		}
+		memo.Payload = payload
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to search memos")
+	}
+	return results, nil
+}
@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/plugin/textsearch"
+)
+
+func TestTextQuery(t *testing.T) {
+	d := &DB{textSearch: textsearch.NewConfigs(map[string]string{"zh": "jiebacfg"})}
+
+	// The default configuration over bigrams matches the idx_memo_content_fts index
+	vector, tsquery, arg := d.textQuery("memo.content", "kubernetes upgrade", "$1")
+	require.Equal(t, "to_tsvector('simple', cjk_bigrams(memo.content))", vector)
+	require.Equal(t, "plainto_tsquery('simple', $1)", tsquery)
+	require.Equal(t, "kubernetes upgrade", arg)
+
+	vector, tsquery, arg = d.textQuery("memo.content", "机器学习", "$1")
+	require.Equal(t, "to_tsvector('jiebacfg', COALESCE(memo.content, ''))", vector)
+	require.Equal(t, "plainto_tsquery('jiebacfg', $1)", tsquery)
+	require.Equal(t, "机器学习", arg)
+
+	_, _, arg = (&DB{}).textQuery("memo.content", "机器学习", "$1")
+	require.Equal(t, " 机器 器学 学习 ", arg)
+}
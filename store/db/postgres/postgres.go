@@ -11,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/hrygo/divinesense/internal/profile"
+	"github.com/hrygo/divinesense/plugin/textsearch"
 	"github.com/hrygo/divinesense/store"
 )
 
@@ -34,6 +35,8 @@ import (
 type DB struct {
 	db      *sql.DB
 	profile *profile.Profile
+	// textSearch holds the full-text search configurations of the languages.
+	textSearch *textsearch.Configs
 }
 
 func NewDB(profile *profile.Profile) (store.Driver, error) {
@@ -68,8 +71,9 @@ func NewDB(profile *profile.Profile) (store.Driver, error) {
 	}
 
 	var driver store.Driver = &DB{
-		db:      db,
-		profile: profile,
+		db:         db,
+		profile:    profile,
+		textSearch: textsearch.NewConfigs(profile.FTSLanguages),
 	}
 
 	// Return the DB struct
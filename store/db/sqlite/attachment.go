@@ -70,9 +70,21 @@ func (d *DB) ListAttachments(ctx context.Context, find *store.FindAttachment) ([
 			args = append(args, id)
 		}
 	}
+	if len(find.TypeList) > 0 {
+		placeholders := make([]string, 0, len(find.TypeList))
+		for _, mimeType := range find.TypeList {
+			placeholders = append(placeholders, "?")
+			args = append(args, mimeType)
+		}
+		where = append(where, "`attachment`.`type` IN ("+strings.Join(placeholders, ",")+")")
+	}
 	if find.HasRelatedMemo {
 		where = append(where, "`attachment`.`memo_id` IS NOT NULL")
 	}
+	if find.TextPending {
+		// SQLite does not store attachment text.
+		where = append(where, "1 = 0")
+	}
 	if find.StorageType != nil {
 		where, args = append(where, "`attachment`.`storage_type` = ?"), append(args, find.StorageType.String())
 	}
@@ -236,7 +236,11 @@ func (d *DB) vectorSearchVec0(ctx context.Context, opts *store.VectorSearchOptio
 		limit = 10
 	}
 
-	model := "BAAI/bge-m3"
+	// Use default model if not specified
+	model := opts.Model
+	if model == "" {
+		model = DefaultEmbeddingModel
+	}
 
 	// Convert query vector to BLOB format (float32 array)
 	queryVectorBLOB, err := float32ArrayToBLOB(opts.Vector)
@@ -402,7 +406,11 @@ func (d *DB) vectorSearchGo(ctx context.Context, opts *store.VectorSearchOptions
 		limit = 10
 	}
 
-	model := "BAAI/bge-m3"
+	// Use default model if not specified
+	model := opts.Model
+	if model == "" {
+		model = DefaultEmbeddingModel
+	}
 
 	// Build optimized query with time-based filtering
 	// Strategy: Filter by creation time first, then limit candidates for similarity computation
@@ -621,7 +629,11 @@ func (d *DB) FindMemosWithoutEmbedding(ctx context.Context, find *store.FindMemo
 }
 
 // BM25Search performs full-text search using SQLite FTS5 if available.
+// Substring searches use LIKE.
 func (d *DB) BM25Search(ctx context.Context, opts *store.BM25SearchOptions) ([]*store.BM25Result, error) {
+	if opts.Substring {
+		return d.bm25SearchFallback(ctx, opts)
+	}
 	query := `
 		SELECT
 			m.id, m.uid, m.creator_id, m.created_ts, m.updated_ts, m.row_status,
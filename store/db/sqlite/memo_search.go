@@ -0,0 +1,123 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	storepb "github.com/hrygo/divinesense/proto/gen/store"
+	"github.com/hrygo/divinesense/store"
+)
+
+// SearchMemos matches the memo contents with the memo_fts index, ranked by
+// bm25. The unicode61 tokenizer does not segment CJK text, so queries with
+// Han characters, and builds without FTS5, search with LIKE instead.
+func (d *DB) SearchMemos(ctx context.Context, search *store.SearchMemos) ([]*store.MemoSearchResult, error) {
+	terms := strings.Fields(search.Query)
+	if len(terms) == 0 {
+		return []*store.MemoSearchResult{}, nil
+	}
+	if !hasHan(search.Query) {
+		results, err := d.searchMemos(ctx, search,
+			"JOIN `memo_fts` ON `memo_fts`.`rowid` = `memo`.`id`", "-bm25(`memo_fts`)",
+			[]string{"`memo_fts` MATCH ?"}, []any{ftsQuery(terms)})
+		if err == nil {
+			return results, nil
+		}
+	}
+
+	where, args := []string{}, []any{}
+	for _, term := range terms {
+		where = append(where, "`memo`.`content` LIKE ? ESCAPE '\\'")
+		args = append(args, "%"+escapeLike(term)+"%")
+	}
+	return d.searchMemos(ctx, search, "", "1.0", where, args)
+}
+
+func (d *DB) searchMemos(ctx context.Context, search *store.SearchMemos, join, score string, where []string, args []any) ([]*store.MemoSearchResult, error) {
+	where = append(where, "`memo`.`row_status` = 'NORMAL'")
+	if search.ViewerID > 0 {
+		where = append(where, "(`memo`.`creator_id` = ? OR `memo`.`visibility` IN ('PUBLIC', 'PROTECTED'))")
+		args = append(args, search.ViewerID)
+	} else {
+		where = append(where, "`memo`.`visibility` = 'PUBLIC'")
+	}
+	if search.ExcludeComments {
+		where = append(where, "`memo_relation`.`related_memo_id` IS NULL")
+	}
+	args = append(args, search.Limit, search.Offset)
+
+	query := "SELECT `memo`.`id`, `memo`.`uid`, `memo`.`creator_id`, `memo`.`created_ts`, `memo`.`updated_ts`, `memo`.`row_status`, " +
+		"`memo`.`visibility`, `memo`.`pinned`, `memo`.`content`, `memo`.`payload`, `parent_memo`.`uid`, " + score + " AS `score` " +
+		"FROM `memo` " + join + " " +
+		"LEFT JOIN `memo_relation` ON `memo`.`id` = `memo_relation`.`memo_id` AND `memo_relation`.`type` = 'COMMENT' " +
+		"LEFT JOIN `memo` AS `parent_memo` ON `memo_relation`.`related_memo_id` = `parent_memo`.`id` " +
+		"WHERE " + strings.Join(where, " AND ") + " " +
+		"ORDER BY `score` DESC, `memo`.`updated_ts` DESC, `memo`.`id` DESC " +
+		"LIMIT ? OFFSET ?"
+
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search memos")
+	}
+	defer rows.Close()
+
+	results := []*store.MemoSearchResult{}
+	for rows.Next() {
+		var memo store.Memo
+		var payloadBytes []byte
+		result := &store.MemoSearchResult{Memo: &memo}
+		if err := rows.Scan(
+			&memo.ID,
+			&memo.UID,
+			&memo.CreatorID,
+			&memo.CreatedTs,
+			&memo.UpdatedTs,
+			&memo.RowStatus,
+			&memo.Visibility,
+			&memo.Pinned,
+			&memo.Content,
+			&payloadBytes,
+			&memo.ParentUID,
+			&result.Score,
+		); err != nil {
+			return nil, errors.Wrap(err, "failed to scan memo search result")
+		}
+		payload := &storepb.MemoPayload{}
+		if err := protojsonUnmarshaler.Unmarshal(payloadBytes, payload); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal payload")
+		}
+		memo.Payload = payload
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to search memos")
+	}
+	return results, nil
+}
+
+// ftsQuery quotes every term as an FTS5 string, so that the query syntax
+// (AND, NEAR, *, column filters) is matched literally. The terms are ANDed.
+func ftsQuery(terms []string) string {
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		quoted = append(quoted, `"`+strings.ReplaceAll(term, `"`, `""`)+`"`)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// escapeLike escapes the LIKE wildcards of s for ESCAPE '\'.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func hasHan(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) {
+			return true
+		}
+	}
+	return false
+}
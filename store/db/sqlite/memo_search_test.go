@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hrygo/divinesense/store"
+)
+
+func newSearchTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "search.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	for _, stmt := range []string{
+		`CREATE TABLE memo (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			uid TEXT NOT NULL UNIQUE,
+			creator_id INTEGER NOT NULL,
+			created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+			updated_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+			row_status TEXT NOT NULL DEFAULT 'NORMAL',
+			content TEXT NOT NULL DEFAULT '',
+			visibility TEXT NOT NULL DEFAULT 'PRIVATE',
+			pinned INTEGER NOT NULL DEFAULT 0,
+			payload TEXT NOT NULL DEFAULT '{}'
+		)`,
+		`CREATE TABLE memo_relation (memo_id INTEGER NOT NULL, related_memo_id INTEGER NOT NULL, type TEXT NOT NULL)`,
+		`INSERT INTO memo (uid, creator_id, content, visibility) VALUES
+			('m1', 1, 'weekly planning meeting notes', 'PRIVATE'),
+			('m2', 2, 'planning the garden', 'PUBLIC'),
+			('m3', 2, 'private planning of user 2', 'PRIVATE'),
+			('m4', 1, '项目周会 planning', 'PROTECTED'),
+			('m5', 2, 'a comment on planning', 'PUBLIC'),
+			('m6', 1, '100% done_ish', 'PUBLIC')`,
+		`INSERT INTO memo_relation VALUES (5, 2, 'COMMENT')`,
+	} {
+		_, err := db.Exec(stmt)
+		require.NoError(t, err)
+	}
+	// Builds without FTS5 search with LIKE
+	if _, err := db.Exec(`CREATE VIRTUAL TABLE memo_fts USING fts5(content, content='memo', content_rowid='id', tokenize='unicode61')`); err == nil {
+		_, err = db.Exec(`INSERT INTO memo_fts(memo_fts) VALUES ('rebuild')`)
+		require.NoError(t, err)
+	}
+	return &DB{db: db}
+}
+
+func searchUIDs(t *testing.T, d *DB, search *store.SearchMemos) []string {
+	t.Helper()
+	require.NoError(t, search.Validate())
+	results, err := d.SearchMemos(context.Background(), search)
+	require.NoError(t, err)
+	uids := []string{}
+	for _, result := range results {
+		uids = append(uids, result.Memo.UID)
+	}
+	return uids
+}
+
+func TestSearchMemos(t *testing.T) {
+	d := newSearchTestDB(t)
+
+	// Viewers find their memos and the public and protected ones of others
+	assert.ElementsMatch(t, []string{"m1", "m2", "m4", "m5"}, searchUIDs(t, d, &store.SearchMemos{Query: "planning", ViewerID: 1}))
+	assert.ElementsMatch(t, []string{"m2", "m3", "m4", "m5"}, searchUIDs(t, d, &store.SearchMemos{Query: "planning", ViewerID: 2}))
+	assert.ElementsMatch(t, []string{"m2", "m5"}, searchUIDs(t, d, &store.SearchMemos{Query: "planning"}))
+	assert.ElementsMatch(t, []string{"m2"}, searchUIDs(t, d, &store.SearchMemos{Query: "planning", ExcludeComments: true}))
+
+	// Every term matches
+	assert.Equal(t, []string{"m1"}, searchUIDs(t, d, &store.SearchMemos{Query: "meeting planning", ViewerID: 1}))
+	assert.Equal(t, []string{"m4"}, searchUIDs(t, d, &store.SearchMemos{Query: "周会", ViewerID: 1}))
+
+	// The query syntax and wildcards match literally
+	assert.Empty(t, searchUIDs(t, d, &store.SearchMemos{Query: `planning OR "garden`, ViewerID: 1}))
+	assert.Equal(t, []string{"m6"}, searchUIDs(t, d, &store.SearchMemos{Query: "100%", ViewerID: 1}))
+	assert.Empty(t, searchUIDs(t, d, &store.SearchMemos{Query: "周_", ViewerID: 1}))
+
+	// Pages
+	all := searchUIDs(t, d, &store.SearchMemos{Query: "planning", ViewerID: 1})
+	page := searchUIDs(t, d, &store.SearchMemos{Query: "planning", ViewerID: 1, Limit: 2, Offset: 1})
+	assert.Equal(t, all[1:3], page)
+}
+
+func TestFTSQuery(t *testing.T) {
+	assert.Equal(t, `"a" "b*" """c"""`, ftsQuery([]string{"a", "b*", `"c"`}))
+	assert.Equal(t, `100\% a\_b \\`, escapeLike(`100% a_b \`))
+	assert.True(t, hasHan("周会 notes"))
+	assert.False(t, hasHan("notes"))
+}
+
+func TestBM25Search_Substring(t *testing.T) {
+	d := newSearchTestDB(t)
+
+	results, err := d.BM25Search(context.Background(), &store.BM25SearchOptions{UserID: 1, Query: "周会", Limit: 10, Substring: true})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "m4", results[0].Memo.UID)
+}
@@ -192,6 +192,10 @@ func (s *sqliteAgentStatsStore) GetCostStats(ctx context.Context, userID int32,
 	return nil, errors.New("agent session stats not supported in SQLite (use PostgreSQL for AI features)")
 }
 
+func (s *sqliteAgentStatsStore) GetUsageTotals(ctx context.Context, userID int32, start, end time.Time) (*store.UsageTotals, error) {
+	return nil, errors.New("agent session stats not supported in SQLite (use PostgreSQL for AI features)")
+}
+
 func (s *sqliteAgentStatsStore) GetUserCostSettings(ctx context.Context, userID int32) (*store.UserCostSettings, error) {
 	return nil, errors.New("agent session stats not supported in SQLite (use PostgreSQL for AI features)")
 }
@@ -228,6 +232,10 @@ func (d *DB) CreateAIBlock(ctx context.Context, create *store.CreateAIBlock) (*s
 	return nil, errors.New("AIBlock not supported in SQLite (use PostgreSQL for AI features)")
 }
 
+func (d *DB) ImportAIConversation(ctx context.Context, imported *store.ImportAIConversation) (*store.AIConversation, error) {
+	return nil, errors.New("AIBlock not supported in SQLite (use PostgreSQL for AI features)")
+}
+
 func (d *DB) GetAIBlock(ctx context.Context, id int64) (*store.AIBlock, error) {
 	return nil, errors.New("AIBlock not supported in SQLite (use PostgreSQL for AI features)")
 }
@@ -268,6 +276,11 @@ func (d *DB) GetLatestAIBlock(ctx context.Context, conversationID int32) (*store
 	return nil, errors.New("AIBlock not supported in SQLite (use PostgreSQL for AI features)")
 }
 
+func (d *DB) ListAIBlockUIDs(ctx context.Context, conversationID int32) ([]string, error) {
+	// Consistent with ListAIBlocks, which lists no blocks in SQLite.
+	return []string{}, nil
+}
+
 func (d *DB) GetPendingAIBlocks(ctx context.Context) ([]*store.AIBlock, error) {
 	return nil, errors.New("AIBlock not supported in SQLite (use PostgreSQL for AI features)")
 }
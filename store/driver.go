@@ -84,6 +84,9 @@ type Driver interface {
 	VectorSearch(ctx context.Context, opts *VectorSearchOptions) ([]*MemoWithScore, error)
 	BM25Search(ctx context.Context, opts *BM25SearchOptions) ([]*BM25Result, error)
 
+	// SearchMemos performs a keyword search of the memos visible to a viewer.
+	SearchMemos(ctx context.Context, search *SearchMemos) ([]*MemoSearchResult, error)
+
 	// MemoSummary model related methods.
 	UpsertMemoSummary(ctx context.Context, upsert *UpsertMemoSummary) (*MemoSummary, error)
 	ListMemoSummarys(ctx context.Context, find *FindMemoSummary) ([]*MemoSummary, error)
@@ -108,6 +111,7 @@ type Driver interface {
 	ListAIConversations(ctx context.Context, find *FindAIConversation) ([]*AIConversation, error)
 	UpdateAIConversation(ctx context.Context, update *UpdateAIConversation) (*AIConversation, error)
 	DeleteAIConversation(ctx context.Context, delete *DeleteAIConversation) error
+	ImportAIConversation(ctx context.Context, imported *ImportAIConversation) (*AIConversation, error)
 
 	// AIBlock model related methods (Unified Block Model).
 	CreateAIBlock(ctx context.Context, create *CreateAIBlock) (*AIBlock, error)
@@ -120,6 +124,7 @@ type Driver interface {
 	AppendEventsBatch(ctx context.Context, blockID int64, events []BlockEvent) error
 	UpdateAIBlockStatus(ctx context.Context, blockID int64, status AIBlockStatus) error
 	GetLatestAIBlock(ctx context.Context, conversationID int32) (*AIBlock, error)
+	ListAIBlockUIDs(ctx context.Context, conversationID int32) ([]string, error)
 	GetPendingAIBlocks(ctx context.Context) ([]*AIBlock, error)
 	CreateAIBlockWithRound(ctx context.Context, create *CreateAIBlock) (*AIBlock, error)
 
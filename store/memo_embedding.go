@@ -6,6 +6,14 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	// DefaultEmbeddingModel is the model of the memo embeddings until the
+	// server sets the configured or swapped-in model.
+	DefaultEmbeddingModel = "BAAI/bge-m3"
+	// EmbeddingDimensions is the size of the memo_embedding vectors.
+	EmbeddingDimensions = 1024
+)
+
 // MemoEmbedding represents the vector embedding of a memo.
 type MemoEmbedding struct {
 	Model     string
@@ -39,9 +47,10 @@ type VectorSearchOptions struct {
 	Vector          []float32
 	Limit           int
 	UserID          int32
-	CreatedAfter    int64 // Optional: only search memos created after this timestamp
-	MaxCandidates   int   // Optional: maximum candidates to fetch (default: limit * 10)
-	ExcludeComments bool  // Optional: exclude comments (memos with parent)
+	CreatedAfter    int64  // Optional: only search memos created after this timestamp
+	MaxCandidates   int    // Optional: maximum candidates to fetch (default: limit * 10)
+	ExcludeComments bool   // Optional: exclude comments (memos with parent)
+	Model           string // Optional: embedding model to search (default: the active model)
 }
 
 // Validate validates the VectorSearchOptions.
@@ -71,6 +80,10 @@ type BM25SearchOptions struct {
 	UserID          int32
 	MinScore        float32
 	ExcludeComments bool // Optional: exclude comments (memos with parent)
+	// Substring matches every whitespace-separated term of the query as a
+	// substring instead of a token; the full-text parsers do not segment
+	// Chinese, so short Chinese queries miss longer runs of Han characters.
+	Substring bool
 }
 
 // Validate validates the BM25SearchOptions.
@@ -142,11 +155,28 @@ func (s *Store) FindMemosWithoutEmbedding(ctx context.Context, find *FindMemosWi
 	return s.driver.FindMemosWithoutEmbedding(ctx, find)
 }
 
+// EmbeddingModel returns the model of the active memo embeddings: searches
+// use it and the embedding runner fills it.
+func (s *Store) EmbeddingModel() string {
+	if model, ok := s.embeddingModel.Load().(string); ok && model != "" {
+		return model
+	}
+	return DefaultEmbeddingModel
+}
+
+// SetEmbeddingModel switches the active memo embeddings to those of model.
+func (s *Store) SetEmbeddingModel(model string) {
+	s.embeddingModel.Store(model)
+}
+
 // VectorSearch performs vector similarity search.
 func (s *Store) VectorSearch(ctx context.Context, opts *VectorSearchOptions) ([]*MemoWithScore, error) {
 	if err := opts.Validate(); err != nil {
 		return nil, err
 	}
+	if opts.Model == "" {
+		opts.Model = s.EmbeddingModel()
+	}
 	return s.driver.VectorSearch(ctx, opts)
 }
 
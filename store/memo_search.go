@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// SearchMemos is a keyword search of the memos visible to a viewer.
+//
+// Unlike BM25Search, which ranks the memos of one user for retrieval, it
+// applies the visibility rules of ListMemos, so the result can be shown to
+// the viewer as is. Every driver implements it with its own full-text
+// search: tsvector on PostgreSQL, FTS5 on SQLite.
+type SearchMemos struct {
+	Query string
+	// ViewerID is the searching user; 0 for anonymous viewers, who only find
+	// public memos.
+	ViewerID        int32
+	ExcludeComments bool
+	Limit           int
+	Offset          int
+}
+
+// Validate validates the SearchMemos.
+func (s *SearchMemos) Validate() error {
+	if s.Query == "" {
+		return errors.Errorf("query cannot be empty")
+	}
+	if len(s.Query) > 500 {
+		return errors.Errorf("query too long (max 500 characters): %d", len(s.Query))
+	}
+	if s.Limit < 0 || s.Offset < 0 {
+		return errors.Errorf("limit and offset cannot be negative: %d, %d", s.Limit, s.Offset)
+	}
+	if s.Limit == 0 {
+		s.Limit = 10 // Default limit
+	}
+	if s.Limit > 1000 {
+		return errors.Errorf("limit too large (max 1000): %d", s.Limit)
+	}
+	return nil
+}
+
+// MemoSearchResult is a memo matching a SearchMemos, with its relevance
+// score. Scores are only comparable within a search.
+type MemoSearchResult struct {
+	Memo  *Memo
+	Score float32
+}
+
+// SearchMemos returns the memos matching the keywords of the search, most
+// relevant first.
+func (s *Store) SearchMemos(ctx context.Context, search *SearchMemos) ([]*MemoSearchResult, error) {
+	if err := search.Validate(); err != nil {
+		return nil, err
+	}
+	return s.driver.SearchMemos(ctx, search)
+}
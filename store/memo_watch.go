@@ -0,0 +1,20 @@
+package store
+
+// MemoWatcher is notified of the memos whose content changed: created
+// memos, and updated memos with a new content.
+type MemoWatcher interface {
+	// MemoChanged is called after the change is stored; it must not block.
+	MemoChanged(memoID int32)
+}
+
+// WatchMemos notifies a watcher of the memo content changes. Watchers are
+// added at startup, before the store is used.
+func (s *Store) WatchMemos(watcher MemoWatcher) {
+	s.memoWatchers = append(s.memoWatchers, watcher)
+}
+
+func (s *Store) notifyMemoChanged(memoID int32) {
+	for _, watcher := range s.memoWatchers {
+		watcher.MemoChanged(memoID)
+	}
+}
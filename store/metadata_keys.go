@@ -3,6 +3,10 @@ package store
 // Metadata key constants for AIBlock.Metadata field.
 // These keys follow the context-engineering.md architecture for
 // state management and sticky routing.
+//
+// The routing keys are the fields of the routing section (see
+// ai_block_metadata.go); blocks written before the sections hold them as
+// top-level keys, which the accessors still read.
 const (
 	// MetadataKeyLastAgent stores the last agent type for sticky routing.
 	// Values: "memo", "schedule", "amazing", "geek", "evolution"
@@ -34,94 +38,72 @@ const (
 	MetadataKeyEntities = "entities"
 )
 
+// Top-level keys of the blocks forked or stopped by their budget before the
+// fork and budget sections.
+const (
+	legacyMetadataKeyForkedFrom     = "forked_from"
+	legacyMetadataKeyForkReason     = "fork_reason"
+	legacyMetadataKeyForkType       = "fork_type"
+	legacyMetadataKeyBudgetExceeded = "budget_exceeded"
+)
+
 // GetMetadataLastAgent retrieves the last agent from block metadata.
 func (b *AIBlock) GetMetadataLastAgent() (string, bool) {
-	if b.Metadata == nil {
+	routing, ok := b.Routing()
+	if !ok || routing.LastAgent == "" {
 		return "", false
 	}
-	val, ok := b.Metadata[MetadataKeyLastAgent].(string)
-	return val, ok
+	return routing.LastAgent, true
 }
 
 // GetMetadataIntent retrieves the intent from block metadata.
 func (b *AIBlock) GetMetadataIntent() (string, bool) {
-	if b.Metadata == nil {
+	routing, ok := b.Routing()
+	if !ok || routing.Intent == "" {
 		return "", false
 	}
-	val, ok := b.Metadata[MetadataKeyIntent].(string)
-	return val, ok
+	return routing.Intent, true
 }
 
 // GetMetadataIntentConfidence retrieves the intent confidence from block metadata.
 func (b *AIBlock) GetMetadataIntentConfidence() (float32, bool) {
-	if b.Metadata == nil {
-		return 0, false
-	}
-	// Handle both float64 (from JSON) and float32
-	switch v := b.Metadata[MetadataKeyIntentConfidence].(type) {
-	case float64:
-		return float32(v), true
-	case float32:
-		return v, true
-	default:
+	routing, ok := b.Routing()
+	if !ok {
 		return 0, false
 	}
+	return routing.IntentConfidence, true
 }
 
 // GetMetadataStickyUntil retrieves the sticky expiration timestamp.
 func (b *AIBlock) GetMetadataStickyUntil() (int64, bool) {
-	if b.Metadata == nil {
-		return 0, false
-	}
-	// Handle both float64 (from JSON) and int64
-	switch v := b.Metadata[MetadataKeyStickyUntil].(type) {
-	case float64:
-		return int64(v), true
-	case int64:
-		return v, true
-	case int:
-		return int64(v), true
-	default:
+	routing, ok := b.Routing()
+	if !ok || routing.StickyUntil == 0 {
 		return 0, false
 	}
+	return routing.StickyUntil, true
 }
 
-// SetMetadataLastAgent sets the last agent in update metadata.
+// SetMetadataLastAgent sets the last agent in the routing section of the update.
 func (u *UpdateAIBlock) SetMetadataLastAgent(agent string) {
-	if u.Metadata == nil {
-		u.Metadata = make(map[string]any)
-	}
-	u.Metadata[MetadataKeyLastAgent] = agent
+	u.routing().LastAgent = agent
 }
 
-// SetMetadataIntent sets the intent in update metadata.
+// SetMetadataIntent sets the intent in the routing section of the update.
 func (u *UpdateAIBlock) SetMetadataIntent(intent string) {
-	if u.Metadata == nil {
-		u.Metadata = make(map[string]any)
-	}
-	u.Metadata[MetadataKeyIntent] = intent
+	u.routing().Intent = intent
 }
 
-// SetMetadataIntentConfidence sets the intent confidence in update metadata.
+// SetMetadataIntentConfidence sets the intent confidence in the routing section of the update.
 func (u *UpdateAIBlock) SetMetadataIntentConfidence(confidence float32) {
-	if u.Metadata == nil {
-		u.Metadata = make(map[string]any)
-	}
-	u.Metadata[MetadataKeyIntentConfidence] = confidence
+	u.routing().IntentConfidence = confidence
 }
 
 // SetMetadataStickyUntil sets the sticky expiration timestamp.
 func (u *UpdateAIBlock) SetMetadataStickyUntil(unixSeconds int64) {
-	if u.Metadata == nil {
-		u.Metadata = make(map[string]any)
-	}
-	u.Metadata[MetadataKeyStickyUntil] = unixSeconds
+	u.routing().StickyUntil = unixSeconds
 }
 
 // SetMetadataRouteMethod sets the routing method.
 func (u *UpdateAIBlock) SetMetadataRouteMethod(method string) {
-	if u.Metadata == nil {
-		u.Metadata = make(map[string]any)
-	}
-	u.Metadata[MetadataKeyRouteMethod] = method
+	u.routing().RouteMethod = method
 }
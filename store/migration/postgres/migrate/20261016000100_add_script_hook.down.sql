@@ -0,0 +1,4 @@
+-- Rollback script_hook table
+
+DROP INDEX IF EXISTS idx_script_hook_trigger_enabled;
+DROP TABLE IF EXISTS script_hook;
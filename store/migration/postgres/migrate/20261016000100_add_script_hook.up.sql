@@ -0,0 +1,24 @@
+-- Add script_hook table for admin-defined scripting hooks
+-- Hooks run embedded JavaScript at trigger points (memo.create, chat.request, chat.event)
+
+CREATE TABLE script_hook (
+  id SERIAL PRIMARY KEY,
+  name TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  trigger TEXT NOT NULL CHECK (trigger IN ('memo.create', 'chat.request', 'chat.event')),
+  source TEXT NOT NULL,
+  priority INTEGER NOT NULL DEFAULT 0,
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  creator_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_script_hook_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_script_hook_trigger_enabled ON script_hook(trigger, enabled, priority);
+
+COMMENT ON TABLE script_hook IS 'Admin-defined JavaScript hooks executed at trigger points';
+COMMENT ON COLUMN script_hook.trigger IS 'memo.create: before memo persist, chat.request: before agent dispatch, chat.event: per streamed event';
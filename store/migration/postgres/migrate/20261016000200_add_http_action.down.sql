@@ -0,0 +1,4 @@
+-- Rollback http_action table
+
+DROP INDEX IF EXISTS idx_http_action_enabled;
+DROP TABLE IF EXISTS http_action;
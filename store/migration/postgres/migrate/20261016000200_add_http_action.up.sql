@@ -0,0 +1,30 @@
+-- Add http_action table for admin-registered external HTTP actions
+-- Actions are exposed to parrots as tools, filtered by role and rate limited per action
+
+CREATE TABLE http_action (
+  id SERIAL PRIMARY KEY,
+  name TEXT NOT NULL UNIQUE,
+  description TEXT NOT NULL,
+  method TEXT NOT NULL CHECK (method IN ('GET', 'POST', 'PUT', 'PATCH', 'DELETE')),
+  url TEXT NOT NULL,
+  parameters JSONB NOT NULL DEFAULT '{}',
+  auth_header TEXT NOT NULL DEFAULT '',
+  auth_value TEXT NOT NULL DEFAULT '',
+  allowed_roles JSONB NOT NULL DEFAULT '[]',
+  rate_limit_per_minute INTEGER NOT NULL DEFAULT 0,
+  timeout_seconds INTEGER NOT NULL DEFAULT 0,
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  creator_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_http_action_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_http_action_enabled ON http_action(enabled);
+
+COMMENT ON TABLE http_action IS 'Admin-registered external HTTP endpoints exposed to parrots as tools';
+COMMENT ON COLUMN http_action.allowed_roles IS 'JSON array of user roles allowed to use the action; empty means all roles';
+COMMENT ON COLUMN http_action.rate_limit_per_minute IS '0 disables rate limiting';
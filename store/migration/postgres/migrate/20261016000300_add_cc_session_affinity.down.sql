@@ -0,0 +1,4 @@
+-- Rollback cc_session_affinity table
+
+DROP INDEX IF EXISTS idx_cc_session_affinity_updated;
+DROP TABLE IF EXISTS cc_session_affinity;
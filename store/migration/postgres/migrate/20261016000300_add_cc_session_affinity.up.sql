@@ -0,0 +1,14 @@
+-- Add cc_session_affinity table for multi-instance deployments
+-- Maps a conversation to the instance running its Claude Code CLI session
+
+CREATE TABLE cc_session_affinity (
+  conversation_id INTEGER PRIMARY KEY,
+  instance_id TEXT NOT NULL,
+  instance_url TEXT NOT NULL,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+CREATE INDEX idx_cc_session_affinity_updated ON cc_session_affinity(updated_ts);
+
+COMMENT ON TABLE cc_session_affinity IS 'Instance holding the live Claude Code session of each conversation';
+COMMENT ON COLUMN cc_session_affinity.instance_url IS 'Internal URL other instances forward follow-up messages to';
@@ -0,0 +1,5 @@
+-- Rollback scheduled_message table
+
+DROP INDEX IF EXISTS idx_scheduled_message_creator;
+DROP INDEX IF EXISTS idx_scheduled_message_due;
+DROP TABLE IF EXISTS scheduled_message;
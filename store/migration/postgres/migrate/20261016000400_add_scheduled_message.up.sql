@@ -0,0 +1,32 @@
+-- Add scheduled_message table for chat messages sent at a future time
+-- A background dispatcher sends due messages through the chat handler
+
+CREATE TABLE scheduled_message (
+  id SERIAL PRIMARY KEY,
+  creator_id INTEGER NOT NULL,
+  conversation_id INTEGER NOT NULL,
+  message TEXT NOT NULL,
+  agent_type TEXT NOT NULL DEFAULT '',
+  timezone TEXT NOT NULL DEFAULT '',
+  scheduled_ts BIGINT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'RUNNING', 'COMPLETED', 'FAILED', 'CANCELED')),
+  block_id BIGINT NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_scheduled_message_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE,
+  CONSTRAINT fk_scheduled_message_conversation
+    FOREIGN KEY (conversation_id)
+    REFERENCES ai_conversation(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_scheduled_message_due ON scheduled_message(status, scheduled_ts);
+CREATE INDEX idx_scheduled_message_creator ON scheduled_message(creator_id);
+
+COMMENT ON TABLE scheduled_message IS 'Chat messages scheduled to be sent into a conversation at a future time';
+COMMENT ON COLUMN scheduled_message.agent_type IS 'AgentType enum name of the chat request; empty means default routing';
+COMMENT ON COLUMN scheduled_message.block_id IS 'Block holding the answer once the message was sent';
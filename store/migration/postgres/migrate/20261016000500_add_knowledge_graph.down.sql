@@ -0,0 +1,10 @@
+-- Rollback knowledge graph tables
+
+DROP INDEX IF EXISTS idx_kg_relation_source;
+DROP INDEX IF EXISTS idx_kg_relation_to;
+DROP INDEX IF EXISTS idx_kg_relation_from;
+DROP TABLE IF EXISTS kg_relation;
+DROP INDEX IF EXISTS idx_kg_mention_source;
+DROP INDEX IF EXISTS idx_kg_mention_entity;
+DROP TABLE IF EXISTS kg_mention;
+DROP TABLE IF EXISTS kg_entity;
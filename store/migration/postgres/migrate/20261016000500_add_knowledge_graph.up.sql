@@ -0,0 +1,63 @@
+-- Add kg_entity, kg_mention and kg_relation tables for the personal knowledge graph
+-- Entities (people, projects, terms) are extracted from memos and conversations
+
+CREATE TABLE kg_entity (
+  id SERIAL PRIMARY KEY,
+  creator_id INTEGER NOT NULL,
+  name TEXT NOT NULL,
+  normalized_name TEXT NOT NULL,
+  kind TEXT NOT NULL CHECK (kind IN ('PERSON', 'PROJECT', 'TERM')),
+  description TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_kg_entity_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE,
+  CONSTRAINT uq_kg_entity_name UNIQUE (creator_id, kind, normalized_name)
+);
+
+CREATE TABLE kg_mention (
+  id SERIAL PRIMARY KEY,
+  entity_id INTEGER NOT NULL,
+  source_type TEXT NOT NULL CHECK (source_type IN ('MEMO', 'CONVERSATION')),
+  source_id INTEGER NOT NULL,
+  source_ref TEXT NOT NULL DEFAULT '',
+  snippet TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_kg_mention_entity
+    FOREIGN KEY (entity_id)
+    REFERENCES kg_entity(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_kg_mention_entity ON kg_mention(entity_id, created_ts DESC);
+CREATE INDEX idx_kg_mention_source ON kg_mention(source_type, source_id);
+
+CREATE TABLE kg_relation (
+  id SERIAL PRIMARY KEY,
+  from_entity_id INTEGER NOT NULL,
+  to_entity_id INTEGER NOT NULL,
+  relation TEXT NOT NULL,
+  source_type TEXT NOT NULL CHECK (source_type IN ('MEMO', 'CONVERSATION')),
+  source_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_kg_relation_from
+    FOREIGN KEY (from_entity_id)
+    REFERENCES kg_entity(id)
+    ON DELETE CASCADE,
+  CONSTRAINT fk_kg_relation_to
+    FOREIGN KEY (to_entity_id)
+    REFERENCES kg_entity(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_kg_relation_from ON kg_relation(from_entity_id);
+CREATE INDEX idx_kg_relation_to ON kg_relation(to_entity_id);
+CREATE INDEX idx_kg_relation_source ON kg_relation(source_type, source_id);
+
+COMMENT ON TABLE kg_entity IS 'People, projects and terms extracted from a user''s memos and conversations';
+COMMENT ON COLUMN kg_entity.normalized_name IS 'Lower-cased name with collapsed whitespace, used to merge mentions';
+COMMENT ON TABLE kg_mention IS 'Places where an entity is mentioned';
+COMMENT ON COLUMN kg_mention.source_ref IS 'Memo UID for memo mentions; empty for conversations';
+COMMENT ON TABLE kg_relation IS 'Relations between entities, one row per source stating them';
@@ -0,0 +1,7 @@
+-- Rollback flashcard tables
+
+DROP INDEX IF EXISTS idx_flashcard_review_creator;
+DROP TABLE IF EXISTS flashcard_review;
+DROP INDEX IF EXISTS idx_flashcard_memo;
+DROP INDEX IF EXISTS idx_flashcard_creator_due;
+DROP TABLE IF EXISTS flashcard;
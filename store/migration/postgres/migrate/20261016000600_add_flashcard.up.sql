@@ -0,0 +1,48 @@
+-- Add flashcard and flashcard_review tables for spaced-repetition flashcards
+-- Question/answer cards are generated from memos and scheduled with SM-2
+
+CREATE TABLE flashcard (
+  id SERIAL PRIMARY KEY,
+  creator_id INTEGER NOT NULL,
+  memo_id INTEGER NOT NULL,
+  question TEXT NOT NULL,
+  answer TEXT NOT NULL,
+  ease_factor DOUBLE PRECISION NOT NULL DEFAULT 2.5,
+  interval_days INTEGER NOT NULL DEFAULT 0,
+  review_count INTEGER NOT NULL DEFAULT 0,
+  due_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  last_review_ts BIGINT NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_flashcard_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE,
+  CONSTRAINT fk_flashcard_memo
+    FOREIGN KEY (memo_id)
+    REFERENCES memo(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_flashcard_creator_due ON flashcard(creator_id, due_ts);
+CREATE INDEX idx_flashcard_memo ON flashcard(memo_id);
+
+CREATE TABLE flashcard_review (
+  id SERIAL PRIMARY KEY,
+  card_id INTEGER NOT NULL,
+  creator_id INTEGER NOT NULL,
+  quality INTEGER NOT NULL CHECK (quality BETWEEN 0 AND 3),
+  interval_days INTEGER NOT NULL,
+  reviewed_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_flashcard_review_card
+    FOREIGN KEY (card_id)
+    REFERENCES flashcard(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_flashcard_review_creator ON flashcard_review(creator_id, reviewed_ts);
+
+COMMENT ON TABLE flashcard IS 'Question/answer cards generated from memos for spaced repetition';
+COMMENT ON COLUMN flashcard.due_ts IS 'Next review time; new cards are due on creation';
+COMMENT ON TABLE flashcard_review IS 'Review log of flashcards, used for statistics';
+COMMENT ON COLUMN flashcard_review.quality IS '0 = again, 1 = hard, 2 = good, 3 = easy';
@@ -0,0 +1,4 @@
+-- Rollback knowledge-base health report settings
+
+DROP INDEX IF EXISTS idx_kb_health_setting_due;
+DROP TABLE IF EXISTS kb_health_setting;
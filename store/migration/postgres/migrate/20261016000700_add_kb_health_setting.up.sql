@@ -0,0 +1,25 @@
+-- Add kb_health_setting table for the weekly knowledge-base health report
+-- One row per user who configured the report; thresholds are per user
+
+CREATE TABLE kb_health_setting (
+  user_id INTEGER PRIMARY KEY,
+  enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  delivery TEXT NOT NULL DEFAULT 'MEMO' CHECK (delivery IN ('MEMO', 'WEBHOOK')),
+  stale_days INTEGER NOT NULL DEFAULT 180,
+  duplicate_threshold DOUBLE PRECISION NOT NULL DEFAULT 0.9,
+  max_items INTEGER NOT NULL DEFAULT 10,
+  last_report_ts BIGINT NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_kb_health_setting_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_kb_health_setting_due ON kb_health_setting(last_report_ts) WHERE enabled;
+
+COMMENT ON TABLE kb_health_setting IS 'Per-user settings of the weekly knowledge-base health report';
+COMMENT ON COLUMN kb_health_setting.stale_days IS 'Notes not updated for this many days are reported as stale';
+COMMENT ON COLUMN kb_health_setting.duplicate_threshold IS 'Cosine similarity from which two notes are reported as duplicates';
+COMMENT ON COLUMN kb_health_setting.last_report_ts IS 'Time the last scheduled report was claimed for delivery';
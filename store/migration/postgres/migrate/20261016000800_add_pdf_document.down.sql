@@ -0,0 +1,8 @@
+-- Rollback PDF ingestion tables
+
+DROP INDEX IF EXISTS idx_pdf_chunk_hnsw;
+DROP INDEX IF EXISTS idx_pdf_chunk_creator;
+DROP TABLE IF EXISTS pdf_chunk;
+DROP INDEX IF EXISTS idx_pdf_document_status;
+DROP INDEX IF EXISTS idx_pdf_document_creator;
+DROP TABLE IF EXISTS pdf_document;
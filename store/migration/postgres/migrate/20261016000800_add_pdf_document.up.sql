@@ -0,0 +1,52 @@
+-- Add pdf_document and pdf_chunk tables for PDF ingestion
+-- PDF attachments are split into page-aware chunks and embedded for search with page citations
+
+CREATE TABLE pdf_document (
+  attachment_id INTEGER PRIMARY KEY,
+  creator_id INTEGER NOT NULL,
+  status TEXT NOT NULL DEFAULT 'PENDING'
+    CHECK (status IN ('PENDING', 'PROCESSING', 'DONE', 'FAILED', 'SKIPPED')),
+  error TEXT NOT NULL DEFAULT '',
+  page_count INTEGER NOT NULL DEFAULT 0,
+  chunk_count INTEGER NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_pdf_document_attachment
+    FOREIGN KEY (attachment_id)
+    REFERENCES attachment(id)
+    ON DELETE CASCADE,
+  CONSTRAINT fk_pdf_document_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_pdf_document_creator ON pdf_document(creator_id, created_ts);
+CREATE INDEX idx_pdf_document_status ON pdf_document(status, updated_ts) WHERE status IN ('PENDING', 'PROCESSING');
+
+CREATE TABLE pdf_chunk (
+  id SERIAL PRIMARY KEY,
+  attachment_id INTEGER NOT NULL,
+  creator_id INTEGER NOT NULL,
+  page INTEGER NOT NULL,
+  chunk_index INTEGER NOT NULL,
+  content TEXT NOT NULL,
+  embedding vector(1024) NOT NULL,
+  model VARCHAR(100) NOT NULL,
+  CONSTRAINT fk_pdf_chunk_document
+    FOREIGN KEY (attachment_id)
+    REFERENCES pdf_document(attachment_id)
+    ON DELETE CASCADE,
+  CONSTRAINT uq_pdf_chunk_index
+    UNIQUE (attachment_id, chunk_index)
+);
+
+CREATE INDEX idx_pdf_chunk_creator ON pdf_chunk(creator_id, model);
+CREATE INDEX idx_pdf_chunk_hnsw
+ON pdf_chunk USING hnsw (embedding vector_cosine_ops)
+WITH (m = 16, ef_construction = 64);
+
+COMMENT ON TABLE pdf_document IS 'Ingestion status of PDF attachments';
+COMMENT ON COLUMN pdf_document.error IS 'Why the document failed or was skipped (size or page limit)';
+COMMENT ON TABLE pdf_chunk IS 'Embedded passages of PDF attachments; chunks never span two pages';
+COMMENT ON COLUMN pdf_chunk.page IS '1-based page number, used for citations';
@@ -0,0 +1,5 @@
+-- Rollback code snippet table
+
+DROP INDEX IF EXISTS idx_code_snippet_symbols;
+DROP INDEX IF EXISTS idx_code_snippet_creator;
+DROP TABLE IF EXISTS code_snippet;
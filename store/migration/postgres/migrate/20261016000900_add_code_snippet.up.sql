@@ -0,0 +1,28 @@
+-- Add code_snippet table for snippet memos
+-- Code blocks of memos tagged #snippet are indexed with their language and symbols
+
+CREATE TABLE code_snippet (
+  memo_id INTEGER NOT NULL,
+  block_index INTEGER NOT NULL,
+  creator_id INTEGER NOT NULL,
+  language TEXT NOT NULL DEFAULT '',
+  code TEXT NOT NULL,
+  symbols TEXT[] NOT NULL DEFAULT '{}',
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  PRIMARY KEY (memo_id, block_index),
+  CONSTRAINT fk_code_snippet_memo
+    FOREIGN KEY (memo_id)
+    REFERENCES memo(id)
+    ON DELETE CASCADE,
+  CONSTRAINT fk_code_snippet_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_code_snippet_creator ON code_snippet(creator_id, language);
+CREATE INDEX idx_code_snippet_symbols ON code_snippet USING gin (symbols);
+
+COMMENT ON TABLE code_snippet IS 'Code blocks of snippet memos, searchable by symbol and regex';
+COMMENT ON COLUMN code_snippet.language IS 'Normalized language of the block, e.g. go or python; empty when unknown';
+COMMENT ON COLUMN code_snippet.symbols IS 'Names of the functions, types and classes defined by the block';
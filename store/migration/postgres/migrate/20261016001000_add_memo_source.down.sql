@@ -0,0 +1,4 @@
+-- Rollback memo source table
+
+DROP INDEX IF EXISTS idx_memo_source_creator_site;
+DROP TABLE IF EXISTS memo_source;
@@ -0,0 +1,26 @@
+-- Add memo_source table for the browser extension capture API
+-- Memos captured from a web page keep the page they come from
+
+CREATE TABLE memo_source (
+  memo_id INTEGER PRIMARY KEY,
+  creator_id INTEGER NOT NULL,
+  url TEXT NOT NULL,
+  title TEXT NOT NULL DEFAULT '',
+  site TEXT NOT NULL DEFAULT '',
+  client TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_memo_source_memo
+    FOREIGN KEY (memo_id)
+    REFERENCES memo(id)
+    ON DELETE CASCADE,
+  CONSTRAINT fk_memo_source_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_memo_source_creator_site ON memo_source(creator_id, site);
+
+COMMENT ON TABLE memo_source IS 'Web page a memo was captured from by a browser extension';
+COMMENT ON COLUMN memo_source.site IS 'Host name of the page, e.g. github.com';
+COMMENT ON COLUMN memo_source.client IS 'Capturing client as reported by the extension, e.g. divinesense-chrome/1.0.0';
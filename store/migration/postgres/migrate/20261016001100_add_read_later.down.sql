@@ -0,0 +1,7 @@
+-- Rollback read-later tables
+
+DROP INDEX IF EXISTS idx_read_later_setting_due;
+DROP TABLE IF EXISTS read_later_setting;
+DROP INDEX IF EXISTS idx_read_later_item_unscored;
+DROP INDEX IF EXISTS idx_read_later_item_queue;
+DROP TABLE IF EXISTS read_later_item;
@@ -0,0 +1,51 @@
+-- Add read_later_item and read_later_setting tables for the read-later queue
+-- Memos marked "read later" are ranked by an LLM against the user's stated interests
+
+CREATE TABLE read_later_item (
+  memo_id INTEGER PRIMARY KEY,
+  creator_id INTEGER NOT NULL,
+  status TEXT NOT NULL DEFAULT 'QUEUED'
+    CHECK (status IN ('QUEUED', 'READ', 'DISMISSED')),
+  score INTEGER CHECK (score BETWEEN 0 AND 100),
+  reason TEXT NOT NULL DEFAULT '',
+  scored_ts BIGINT NOT NULL DEFAULT 0,
+  snoozed_until BIGINT NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_read_later_item_memo
+    FOREIGN KEY (memo_id)
+    REFERENCES memo(id)
+    ON DELETE CASCADE,
+  CONSTRAINT fk_read_later_item_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_read_later_item_queue ON read_later_item(creator_id, status, score DESC NULLS LAST);
+CREATE INDEX idx_read_later_item_unscored ON read_later_item(scored_ts) WHERE status = 'QUEUED' AND score IS NULL;
+
+CREATE TABLE read_later_setting (
+  user_id INTEGER PRIMARY KEY,
+  interests TEXT NOT NULL DEFAULT '',
+  digest_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  delivery TEXT NOT NULL DEFAULT 'MEMO'
+    CHECK (delivery IN ('MEMO', 'WEBHOOK')),
+  digest_days INTEGER NOT NULL DEFAULT 1,
+  digest_size INTEGER NOT NULL DEFAULT 5,
+  last_digest_ts BIGINT NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_read_later_setting_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_read_later_setting_due ON read_later_setting(last_digest_ts) WHERE digest_enabled;
+
+COMMENT ON TABLE read_later_item IS 'Memos queued for reading later, ranked by interest';
+COMMENT ON COLUMN read_later_item.score IS 'Interest score from 0 to 100 given by the LLM; NULL until scored';
+COMMENT ON COLUMN read_later_item.scored_ts IS 'Last scoring attempt, used as a lease between runners';
+COMMENT ON COLUMN read_later_item.snoozed_until IS 'The item is hidden from the queue and digests until then';
+COMMENT ON TABLE read_later_setting IS 'Per-user interests and digest settings of the read-later queue';
@@ -0,0 +1,4 @@
+-- Rollback quiet hours
+
+ALTER TABLE scheduled_message DROP COLUMN IF EXISTS urgent;
+DROP TABLE IF EXISTS user_quiet_hours;
@@ -0,0 +1,23 @@
+-- Add user_quiet_hours table and scheduled_message.urgent
+-- Background jobs leave users alone during their quiet hours, except for urgent scheduled messages
+
+CREATE TABLE user_quiet_hours (
+  user_id INTEGER PRIMARY KEY,
+  enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  start_minute INTEGER NOT NULL DEFAULT 1320 CHECK (start_minute BETWEEN 0 AND 1439),
+  end_minute INTEGER NOT NULL DEFAULT 420 CHECK (end_minute BETWEEN 0 AND 1439),
+  timezone TEXT NOT NULL DEFAULT 'UTC',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_user_quiet_hours_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+ALTER TABLE scheduled_message ADD COLUMN urgent BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON TABLE user_quiet_hours IS 'Per-user daily quiet hours honored by background jobs and notifications';
+COMMENT ON COLUMN user_quiet_hours.start_minute IS 'Start of the quiet hours in minutes since local midnight, included';
+COMMENT ON COLUMN user_quiet_hours.end_minute IS 'End of the quiet hours in minutes since local midnight, excluded; before start_minute when spanning midnight';
+COMMENT ON COLUMN scheduled_message.urgent IS 'Urgent messages are sent during the quiet hours of their creator';
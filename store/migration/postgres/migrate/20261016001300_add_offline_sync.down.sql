@@ -0,0 +1,7 @@
+-- Rollback offline sync
+
+DROP TABLE IF EXISTS sync_conflict;
+DROP TRIGGER IF EXISTS trigger_ai_conversation_sync_change ON ai_conversation;
+DROP TRIGGER IF EXISTS trigger_memo_sync_change ON memo;
+DROP FUNCTION IF EXISTS record_sync_change();
+DROP TABLE IF EXISTS sync_change;
@@ -0,0 +1,67 @@
+-- Add the change log and conflicts of the offline sync protocol
+-- Writes to memos and conversations are logged by triggers, whatever API made them
+
+CREATE TABLE sync_change (
+  id BIGSERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  entity TEXT NOT NULL CHECK (entity IN ('memo', 'conversation')),
+  entity_uid TEXT NOT NULL,
+  changed_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+CREATE INDEX idx_sync_change_user ON sync_change(user_id, id);
+CREATE INDEX idx_sync_change_entity ON sync_change(entity, entity_uid, id);
+CREATE INDEX idx_sync_change_changed ON sync_change(changed_ts);
+
+-- Records a change of the row in sync_change; TG_ARGV[0] is the entity
+CREATE OR REPLACE FUNCTION record_sync_change()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    INSERT INTO sync_change (user_id, entity, entity_uid) VALUES (OLD.creator_id, TG_ARGV[0], OLD.uid);
+    RETURN OLD;
+  END IF;
+  INSERT INTO sync_change (user_id, entity, entity_uid) VALUES (NEW.creator_id, TG_ARGV[0], NEW.uid);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_memo_sync_change
+  AFTER INSERT OR DELETE OR UPDATE OF content, visibility, pinned, row_status ON memo
+  FOR EACH ROW
+  EXECUTE FUNCTION record_sync_change('memo');
+
+CREATE TRIGGER trigger_ai_conversation_sync_change
+  AFTER INSERT OR DELETE OR UPDATE OF title, parrot_id, pinned, row_status ON ai_conversation
+  FOR EACH ROW
+  EXECUTE FUNCTION record_sync_change('conversation');
+
+-- Existing memos and conversations are listed to clients pulling from scratch
+INSERT INTO sync_change (user_id, entity, entity_uid)
+SELECT creator_id, 'memo', uid FROM memo ORDER BY id;
+INSERT INTO sync_change (user_id, entity, entity_uid)
+SELECT creator_id, 'conversation', uid FROM ai_conversation ORDER BY id;
+
+CREATE TABLE sync_conflict (
+  id BIGSERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  entity TEXT NOT NULL CHECK (entity IN ('memo', 'conversation')),
+  entity_uid TEXT NOT NULL,
+  kind TEXT NOT NULL CHECK (kind IN ('edit_edit', 'edit_deleted', 'delete_edited')),
+  client JSONB NOT NULL,
+  server JSONB NOT NULL,
+  resolution TEXT NOT NULL DEFAULT ''
+    CHECK (resolution IN ('', 'server', 'client', 'merged')),
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  resolved_ts BIGINT NOT NULL DEFAULT 0,
+  CONSTRAINT fk_sync_conflict_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_sync_conflict_open ON sync_conflict(user_id) WHERE resolved_ts = 0;
+
+COMMENT ON TABLE sync_change IS 'Change log of memos and conversations read by offline-first clients; superseded changes and tombstones are pruned after 90 days';
+COMMENT ON COLUMN sync_change.id IS 'Change cursor and entity version';
+COMMENT ON TABLE sync_conflict IS 'Offline edits that conflicted with server changes, until the user resolves them';
@@ -0,0 +1,3 @@
+-- Rollback block budgets
+
+DROP TABLE IF EXISTS user_block_budget;
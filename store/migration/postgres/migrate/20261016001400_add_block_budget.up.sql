@@ -0,0 +1,16 @@
+-- Add user_block_budget table
+-- Default cost guardrail of the chat blocks of a user; requests may tighten it
+
+CREATE TABLE user_block_budget (
+  user_id INTEGER PRIMARY KEY,
+  max_tokens INTEGER NOT NULL DEFAULT 0 CHECK (max_tokens >= 0),
+  max_cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (max_cost_usd >= 0),
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_user_block_budget_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+COMMENT ON TABLE user_block_budget IS 'Per-user maximum tokens and estimated cost of a chat block; 0 is unlimited';
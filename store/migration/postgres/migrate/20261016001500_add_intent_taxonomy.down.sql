@@ -0,0 +1,3 @@
+-- Rollback intent taxonomy
+
+DROP TABLE IF EXISTS intent_taxonomy;
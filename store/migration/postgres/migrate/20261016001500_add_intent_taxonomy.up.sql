@@ -0,0 +1,13 @@
+-- Add intent_taxonomy table
+-- Admin-managed chat intents matched by the router before its built-in rules
+
+CREATE TABLE intent_taxonomy (
+  name TEXT PRIMARY KEY,
+  examples JSONB NOT NULL DEFAULT '[]',
+  target_expert TEXT NOT NULL DEFAULT '',
+  orchestrate BOOLEAN NOT NULL DEFAULT FALSE,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+COMMENT ON TABLE intent_taxonomy IS 'Chat intents with example phrases, target expert and orchestration flag';
@@ -0,0 +1,3 @@
+-- Rollback few-shot examples
+
+DROP TABLE IF EXISTS few_shot_example;
@@ -0,0 +1,15 @@
+-- Add few_shot_example table
+-- Admin-curated queries with their route and desired answer style, shown to
+-- the router's rule layer and expert prompts; holdout examples only evaluate
+
+CREATE TABLE few_shot_example (
+  id SERIAL PRIMARY KEY,
+  route TEXT NOT NULL,
+  query TEXT NOT NULL,
+  answer TEXT NOT NULL DEFAULT '',
+  holdout BOOLEAN NOT NULL DEFAULT FALSE,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+COMMENT ON TABLE few_shot_example IS 'Few-shot examples of routes and answer styles, with a holdout set for evaluation';
@@ -0,0 +1,4 @@
+-- Rollback canary experts
+
+DROP TABLE IF EXISTS canary_comparison;
+DROP TABLE IF EXISTS canary_rollout;
@@ -0,0 +1,33 @@
+-- Add canary_rollout and canary_comparison tables
+-- Canary experts serve a share of their incumbent's traffic while the
+-- incumbent answers in shadow for comparison
+
+CREATE TABLE canary_rollout (
+  name TEXT PRIMARY KEY,
+  percent INTEGER NOT NULL DEFAULT 0,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+COMMENT ON TABLE canary_rollout IS 'Traffic percentages of canary experts set by admins';
+
+CREATE TABLE canary_comparison (
+  id SERIAL PRIMARY KEY,
+  canary TEXT NOT NULL,
+  incumbent TEXT NOT NULL,
+  user_id INTEGER NOT NULL,
+  query TEXT NOT NULL,
+  canary_answer TEXT NOT NULL DEFAULT '',
+  incumbent_answer TEXT NOT NULL DEFAULT '',
+  canary_latency_ms BIGINT NOT NULL DEFAULT 0,
+  incumbent_latency_ms BIGINT NOT NULL DEFAULT 0,
+  canary_tokens INTEGER NOT NULL DEFAULT 0,
+  incumbent_tokens INTEGER NOT NULL DEFAULT 0,
+  canary_error TEXT NOT NULL DEFAULT '',
+  incumbent_error TEXT NOT NULL DEFAULT '',
+  similarity DOUBLE PRECISION NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+CREATE INDEX idx_canary_comparison_canary ON canary_comparison (canary, created_ts);
+
+COMMENT ON TABLE canary_comparison IS 'Requests served by a canary expert with the shadow answer of its incumbent';
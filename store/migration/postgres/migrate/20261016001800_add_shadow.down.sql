@@ -0,0 +1,4 @@
+-- Rollback shadow runs
+
+DROP TABLE IF EXISTS shadow_run;
+DROP TABLE IF EXISTS shadow_sample_rate;
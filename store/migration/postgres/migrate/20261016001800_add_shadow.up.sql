@@ -0,0 +1,33 @@
+-- Add shadow_sample_rate and shadow_run tables
+-- Shadows run modified prompts and models unseen on sampled requests, with
+-- the paired results kept for offline comparison
+
+CREATE TABLE shadow_sample_rate (
+  name TEXT PRIMARY KEY,
+  sample_rate INTEGER NOT NULL DEFAULT 0,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+COMMENT ON TABLE shadow_sample_rate IS 'Sample rates of shadow parrots set by admins';
+
+CREATE TABLE shadow_run (
+  id SERIAL PRIMARY KEY,
+  shadow TEXT NOT NULL,
+  production TEXT NOT NULL,
+  model TEXT NOT NULL DEFAULT '',
+  user_id INTEGER NOT NULL,
+  query TEXT NOT NULL,
+  production_answer TEXT NOT NULL DEFAULT '',
+  shadow_answer TEXT NOT NULL DEFAULT '',
+  production_latency_ms BIGINT NOT NULL DEFAULT 0,
+  shadow_latency_ms BIGINT NOT NULL DEFAULT 0,
+  production_tokens INTEGER NOT NULL DEFAULT 0,
+  shadow_tokens INTEGER NOT NULL DEFAULT 0,
+  production_error TEXT NOT NULL DEFAULT '',
+  shadow_error TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+CREATE INDEX idx_shadow_run_shadow ON shadow_run (shadow, created_ts);
+
+COMMENT ON TABLE shadow_run IS 'Requests answered by a production parrot and, unseen, by its shadow';
@@ -0,0 +1,3 @@
+-- Rollback locked conversations
+
+DROP TABLE IF EXISTS ai_conversation_lock;
@@ -0,0 +1,13 @@
+-- Add ai_conversation_lock table
+-- Locked conversations store their blocks encrypted with a key derived from
+-- a passphrase held by the user; only the salt and a verifier are stored
+
+CREATE TABLE ai_conversation_lock (
+  conversation_id INTEGER PRIMARY KEY REFERENCES ai_conversation(id) ON DELETE CASCADE,
+  creator_id INTEGER NOT NULL,
+  salt BYTEA NOT NULL,
+  verifier BYTEA NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+COMMENT ON TABLE ai_conversation_lock IS 'Conversations whose history is encrypted with a user passphrase';
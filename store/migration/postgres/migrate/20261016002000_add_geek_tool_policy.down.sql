@@ -0,0 +1,3 @@
+-- Rollback Geek Mode tool policies
+
+DROP TABLE IF EXISTS geek_tool_policy;
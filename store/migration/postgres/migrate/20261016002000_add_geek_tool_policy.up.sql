@@ -0,0 +1,12 @@
+-- Add geek_tool_policy table
+-- Tools Geek Mode workspaces may use: a default policy (user_id 0), and the
+-- policies of user workspaces replacing it
+
+CREATE TABLE geek_tool_policy (
+  user_id INTEGER PRIMARY KEY,
+  allowed_tools TEXT[] NOT NULL DEFAULT '{}',
+  disallowed_tools TEXT[] NOT NULL DEFAULT '{}',
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+COMMENT ON TABLE geek_tool_policy IS 'Claude Code tools permitted in Geek Mode workspaces (user_id 0 is the default)';
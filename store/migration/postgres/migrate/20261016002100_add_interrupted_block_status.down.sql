@@ -0,0 +1,5 @@
+-- Rollback: Remove interrupted block status
+UPDATE ai_block SET status = 'completed' WHERE status = 'interrupted';
+ALTER TABLE ai_block DROP CONSTRAINT IF EXISTS chk_ai_block_status;
+ALTER TABLE ai_block ADD CONSTRAINT chk_ai_block_status
+  CHECK (status IN ('pending', 'streaming', 'completed', 'error'));
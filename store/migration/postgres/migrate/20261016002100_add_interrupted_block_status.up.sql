@@ -0,0 +1,7 @@
+-- Add interrupted block status
+-- Blocks stopped by the user (StopChat) keep the partial answer produced
+-- before the stop
+
+ALTER TABLE ai_block DROP CONSTRAINT IF EXISTS chk_ai_block_status;
+ALTER TABLE ai_block ADD CONSTRAINT chk_ai_block_status
+  CHECK (status IN ('pending', 'streaming', 'completed', 'error', 'interrupted'));
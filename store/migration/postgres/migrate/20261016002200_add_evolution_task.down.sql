@@ -0,0 +1,3 @@
+-- Rollback evolution task backlog
+
+DROP TABLE IF EXISTS evolution_task;
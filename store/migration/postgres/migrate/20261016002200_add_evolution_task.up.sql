@@ -0,0 +1,31 @@
+-- Add evolution_task table
+-- Backlog of Evolution Mode improvement requests: admins approve them before
+-- a run, and each task traces its run (blocks, commits, diff, tests, PR)
+
+CREATE TABLE evolution_task (
+  id BIGSERIAL PRIMARY KEY,
+  title TEXT NOT NULL,
+  description TEXT NOT NULL,
+  creator_id INTEGER NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending',
+  reviewer_id INTEGER NOT NULL DEFAULT 0,
+  review_note TEXT NOT NULL DEFAULT '',
+  runner_id INTEGER NOT NULL DEFAULT 0,
+  conversation_id INTEGER NOT NULL DEFAULT 0,
+  block_ids BIGINT[] NOT NULL DEFAULT '{}',
+  base_commit TEXT NOT NULL DEFAULT '',
+  head_commit TEXT NOT NULL DEFAULT '',
+  diff_stat TEXT NOT NULL DEFAULT '',
+  pr_url TEXT NOT NULL DEFAULT '',
+  test_status TEXT NOT NULL DEFAULT '',
+  test_output TEXT NOT NULL DEFAULT '',
+  error TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT chk_evolution_task_status
+    CHECK (status IN ('pending', 'approved', 'rejected', 'running', 'completed', 'failed'))
+);
+
+CREATE INDEX idx_evolution_task_status ON evolution_task(status, id);
+
+COMMENT ON TABLE evolution_task IS 'Evolution Mode task backlog with approval workflow and run traceability';
@@ -0,0 +1,3 @@
+-- Rollback agent model selections
+
+DROP TABLE IF EXISTS ai_agent_model;
@@ -0,0 +1,12 @@
+-- Add ai_agent_model table
+-- LLM provider and model selected by admins per agent type, overriding the
+-- DIVINESENSE_AI_AGENT_MODELS selections
+
+CREATE TABLE ai_agent_model (
+  agent TEXT PRIMARY KEY,
+  provider TEXT NOT NULL,
+  model TEXT NOT NULL DEFAULT '',
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+COMMENT ON TABLE ai_agent_model IS 'LLM provider and model of agent types selected at runtime by admins';
@@ -0,0 +1,3 @@
+-- Rollback embedding index rebuilds
+
+DROP TABLE IF EXISTS embedding_rebuild;
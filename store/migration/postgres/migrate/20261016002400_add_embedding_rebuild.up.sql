@@ -0,0 +1,22 @@
+-- Add embedding_rebuild table
+-- Re-embeddings of the memo corpus into a shadow index when switching
+-- embedding models; the latest swapped rebuild holds the active model
+
+CREATE TABLE embedding_rebuild (
+  id SERIAL PRIMARY KEY,
+  source_model TEXT NOT NULL,
+  target_model TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'running',
+  total INTEGER NOT NULL DEFAULT 0,
+  processed INTEGER NOT NULL DEFAULT 0,
+  recall DOUBLE PRECISION NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT chk_embedding_rebuild_status
+    CHECK (status IN ('running', 'rejected', 'failed', 'swapped', 'canceled'))
+);
+
+CREATE INDEX idx_embedding_rebuild_status ON embedding_rebuild(status, updated_ts DESC);
+
+COMMENT ON TABLE embedding_rebuild IS 'Memo embedding index rebuilds with resumable progress and atomic model swap';
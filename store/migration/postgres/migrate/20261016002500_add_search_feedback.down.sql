@@ -0,0 +1,5 @@
+-- Rollback search relevance feedback
+
+DROP TABLE IF EXISTS search_weight;
+DROP TABLE IF EXISTS search_feedback;
+DROP TABLE IF EXISTS search_log;
@@ -0,0 +1,48 @@
+-- Add search_log, search_feedback and search_weight tables
+-- Hybrid searches with the ranks of their candidates, the relevance
+-- judgments of their users, and the semantic weight tuned from them
+
+CREATE TABLE search_log (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  query TEXT NOT NULL,
+  semantic_weight REAL NOT NULL,
+  candidates JSONB NOT NULL DEFAULT '[]',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_search_log_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_search_log_user_query ON search_log(user_id, query, created_ts DESC);
+CREATE INDEX idx_search_log_created ON search_log(created_ts);
+
+CREATE TABLE search_feedback (
+  search_id INTEGER NOT NULL,
+  memo_id INTEGER NOT NULL,
+  relevant BOOLEAN NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  PRIMARY KEY (search_id, memo_id),
+  CONSTRAINT fk_search_feedback_search
+    FOREIGN KEY (search_id)
+    REFERENCES search_log(id)
+    ON DELETE CASCADE,
+  CONSTRAINT fk_search_feedback_memo
+    FOREIGN KEY (memo_id)
+    REFERENCES memo(id)
+    ON DELETE CASCADE
+);
+
+CREATE TABLE search_weight (
+  user_id INTEGER PRIMARY KEY,
+  semantic_weight REAL NOT NULL CHECK (semantic_weight BETWEEN 0 AND 1),
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_search_weight_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+COMMENT ON TABLE search_log IS 'Hybrid memo searches with the vector and BM25 rank of each candidate';
+COMMENT ON TABLE search_weight IS 'Per-user hybrid search semantic weight tuned from relevance feedback';
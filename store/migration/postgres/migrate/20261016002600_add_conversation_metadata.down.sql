@@ -0,0 +1,2 @@
+-- Rollback: Remove metadata column from ai_conversation
+ALTER TABLE ai_conversation DROP COLUMN IF EXISTS metadata;
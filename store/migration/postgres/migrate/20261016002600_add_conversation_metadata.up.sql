@@ -0,0 +1,7 @@
+-- Migration: 20261016002600_add_conversation_metadata
+-- Description: Add a metadata column to ai_conversation for conversation settings
+
+-- Settings of the conversation, e.g. retrieval_scope: {"tags": [...]} or {"disabled": true}
+ALTER TABLE ai_conversation ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+COMMENT ON COLUMN ai_conversation.metadata IS 'Conversation settings, e.g. retrieval_scope';
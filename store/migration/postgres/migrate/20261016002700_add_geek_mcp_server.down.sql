@@ -0,0 +1,3 @@
+-- Rollback Geek Mode MCP servers
+
+DROP TABLE IF EXISTS geek_mcp_server;
@@ -0,0 +1,25 @@
+-- Add geek_mcp_server table
+-- External MCP servers users register for the Claude Code CLI of their Geek
+-- Mode sessions
+
+CREATE TABLE geek_mcp_server (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  name TEXT NOT NULL,
+  transport TEXT NOT NULL CHECK (transport IN ('stdio', 'http', 'sse')),
+  command TEXT NOT NULL DEFAULT '',
+  args TEXT[] NOT NULL DEFAULT '{}',
+  env JSONB NOT NULL DEFAULT '{}',
+  url TEXT NOT NULL DEFAULT '',
+  headers JSONB NOT NULL DEFAULT '{}',
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_geek_mcp_server_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE,
+  CONSTRAINT uq_geek_mcp_server_name UNIQUE (user_id, name)
+);
+
+COMMENT ON TABLE geek_mcp_server IS 'MCP servers users register for their Geek Mode Claude Code sessions';
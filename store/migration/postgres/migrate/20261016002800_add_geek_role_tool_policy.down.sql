@@ -0,0 +1,9 @@
+-- Rollback role tool policies
+
+DELETE FROM geek_tool_policy WHERE role <> '';
+ALTER TABLE geek_tool_policy DROP CONSTRAINT chk_geek_tool_policy_role;
+ALTER TABLE geek_tool_policy DROP CONSTRAINT geek_tool_policy_pkey;
+ALTER TABLE geek_tool_policy ADD PRIMARY KEY (user_id);
+ALTER TABLE geek_tool_policy DROP COLUMN role;
+
+COMMENT ON TABLE geek_tool_policy IS 'Claude Code tools permitted in Geek Mode workspaces (user_id 0 is the default)';
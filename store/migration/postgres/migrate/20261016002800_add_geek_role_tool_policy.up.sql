@@ -0,0 +1,11 @@
+-- Add role tool policies to geek_tool_policy
+-- Policies of the workspaces of a user role (user_id 0 and the role), between
+-- the policy of a workspace and the default one
+
+ALTER TABLE geek_tool_policy ADD COLUMN role TEXT NOT NULL DEFAULT '';
+ALTER TABLE geek_tool_policy DROP CONSTRAINT geek_tool_policy_pkey;
+ALTER TABLE geek_tool_policy ADD PRIMARY KEY (user_id, role);
+ALTER TABLE geek_tool_policy ADD CONSTRAINT chk_geek_tool_policy_role
+  CHECK (role IN ('', 'HOST', 'ADMIN', 'USER') AND (role = '' OR user_id = 0));
+
+COMMENT ON TABLE geek_tool_policy IS 'Claude Code tools permitted in Geek Mode workspaces (user_id 0 is the default, or the role policy with a role)';
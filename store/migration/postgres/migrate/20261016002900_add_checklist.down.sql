@@ -0,0 +1,3 @@
+-- Rollback checklists
+
+DROP TABLE IF EXISTS checklist;
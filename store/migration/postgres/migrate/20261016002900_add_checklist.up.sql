@@ -0,0 +1,20 @@
+-- Add checklist table
+-- Checklists of users (packing lists, travel preparations...) generated by the
+-- checklist expert and edited through the API
+
+CREATE TABLE checklist (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  title TEXT NOT NULL,
+  items JSONB NOT NULL DEFAULT '[]',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_checklist_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_checklist_user_updated ON checklist (user_id, updated_ts DESC);
+
+COMMENT ON TABLE checklist IS 'Checklists of users with their items and checked state';
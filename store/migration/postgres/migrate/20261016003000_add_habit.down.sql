@@ -0,0 +1,4 @@
+-- Rollback habits
+
+DROP TABLE IF EXISTS habit_completion;
+DROP TABLE IF EXISTS habit;
@@ -0,0 +1,36 @@
+-- Add habit and habit_completion tables
+-- Habits users track with their daily or weekly targets, and the days they
+-- did them, from which the streaks are computed
+
+CREATE TABLE habit (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  name TEXT NOT NULL,
+  frequency TEXT NOT NULL DEFAULT 'DAILY' CHECK (frequency IN ('DAILY', 'WEEKLY')),
+  target INTEGER NOT NULL DEFAULT 1 CHECK (target > 0),
+  archived BOOLEAN NOT NULL DEFAULT FALSE,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_habit_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_habit_user ON habit (user_id);
+
+COMMENT ON TABLE habit IS 'Habits users track with a daily or weekly target';
+
+CREATE TABLE habit_completion (
+  habit_id INTEGER NOT NULL,
+  date DATE NOT NULL,
+  count INTEGER NOT NULL CHECK (count > 0),
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  PRIMARY KEY (habit_id, date),
+  CONSTRAINT fk_habit_completion_habit
+    FOREIGN KEY (habit_id)
+    REFERENCES habit(id)
+    ON DELETE CASCADE
+);
+
+COMMENT ON TABLE habit_completion IS 'Number of times a habit was done on a day';
@@ -0,0 +1,3 @@
+-- Rollback expenses
+
+DROP TABLE IF EXISTS expense;
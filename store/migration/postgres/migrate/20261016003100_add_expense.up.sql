@@ -0,0 +1,23 @@
+-- Add expense table
+-- Expenses users record through the expense expert or the API, with amounts
+-- in minor units of their currency
+
+CREATE TABLE expense (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  amount BIGINT NOT NULL CHECK (amount > 0),
+  currency TEXT NOT NULL DEFAULT 'CNY',
+  category TEXT NOT NULL DEFAULT 'other',
+  note TEXT NOT NULL DEFAULT '',
+  date DATE NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_expense_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_expense_user_date ON expense (user_id, date DESC);
+
+COMMENT ON TABLE expense IS 'Expenses of users, amounts in minor units of the currency';
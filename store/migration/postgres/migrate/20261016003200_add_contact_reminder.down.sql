@@ -0,0 +1,3 @@
+-- Rollback contact reminders
+
+DROP TABLE IF EXISTS contact_reminder;
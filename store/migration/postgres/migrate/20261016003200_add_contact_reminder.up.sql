@@ -0,0 +1,24 @@
+-- Add contact_reminder table
+-- Follow-ups with people users know ("ping Alice in 2 weeks"), keyed by the
+-- normalized name of the person like the kg_entity people
+
+CREATE TABLE contact_reminder (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  person TEXT NOT NULL,
+  normalized_person TEXT NOT NULL,
+  note TEXT NOT NULL DEFAULT '',
+  due_date DATE NOT NULL,
+  done_ts BIGINT NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_contact_reminder_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_contact_reminder_user_person ON contact_reminder (user_id, normalized_person);
+CREATE INDEX idx_contact_reminder_user_due ON contact_reminder (user_id, due_date) WHERE done_ts = 0;
+
+COMMENT ON TABLE contact_reminder IS 'Follow-ups of users with the people they know';
@@ -0,0 +1,8 @@
+-- Rollback multilingual full-text search
+
+DROP INDEX IF EXISTS idx_memo_content_trgm;
+DROP INDEX IF EXISTS idx_memo_content_fts;
+DROP FUNCTION IF EXISTS cjk_bigrams(text);
+
+CREATE INDEX IF NOT EXISTS idx_memo_content_gin
+ON memo USING gin(to_tsvector('simple', COALESCE(content, '')));
@@ -0,0 +1,48 @@
+-- Multilingual full-text search
+-- The parsers of PostgreSQL do not segment CJK text, so memos are searched
+-- over the bigrams of their CJK runs (see plugin/textsearch), and lone CJK
+-- characters by substring over a trigram index.
+--
+-- Migration: 20261016003300
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- cjk_bigrams splits the runs of Han, kana and Hangul characters into their
+-- overlapping bigrams, separated from the rest of the text by spaces,
+-- e.g. '学习Go语言' into '学习 Go 语言'. The ranges are the ones of
+-- textsearch.Bigrams, which segments the queries.
+CREATE OR REPLACE FUNCTION cjk_bigrams(doc text)
+RETURNS text AS $$
+DECLARE
+    run text;
+    parts text[] := '{}';
+BEGIN
+    FOR run IN
+        SELECT m[1] FROM regexp_matches(COALESCE(doc, ''),
+            '([\u3040-\u30ff\u3400-\u4dbf\u4e00-\u9fff\uac00-\ud7af\uf900-\ufaff\U00020000-\U0002fa1f]+|[^\u3040-\u30ff\u3400-\u4dbf\u4e00-\u9fff\uac00-\ud7af\uf900-\ufaff\U00020000-\U0002fa1f]+)',
+            'g') AS m
+    LOOP
+        IF char_length(run) > 1 AND run ~ '^[\u3040-\u30ff\u3400-\u4dbf\u4e00-\u9fff\uac00-\ud7af\uf900-\ufaff\U00020000-\U0002fa1f]' THEN
+            FOR i IN 1 .. char_length(run) - 1 LOOP
+                parts := parts || substr(run, i, 2);
+            END LOOP;
+        ELSE
+            parts := parts || run;
+        END IF;
+    END LOOP;
+    RETURN array_to_string(parts, ' ');
+END;
+$$ LANGUAGE plpgsql IMMUTABLE PARALLEL SAFE;
+
+-- The full-text index of the default configuration replaces the one without
+-- segmentation
+DROP INDEX IF EXISTS idx_memo_content_gin;
+
+CREATE INDEX IF NOT EXISTS idx_memo_content_fts
+ON memo USING gin(to_tsvector('simple', cjk_bigrams(content)));
+
+CREATE INDEX IF NOT EXISTS idx_memo_content_trgm
+ON memo USING gin(content gin_trgm_ops);
+
+COMMENT ON INDEX idx_memo_content_fts IS 'GIN index for full-text search on the CJK bigrams of memo.content';
+COMMENT ON INDEX idx_memo_content_trgm IS 'Trigram index for substring search on memo.content';
@@ -0,0 +1,3 @@
+-- Rollback web fetch policy
+
+DROP TABLE IF EXISTS web_fetch_policy;
@@ -0,0 +1,18 @@
+-- Add web_fetch_policy table
+-- The admin policy of the web_fetch tool of parrots: a single row, whose
+-- absence leaves the tool disabled
+
+CREATE TABLE web_fetch_policy (
+  id INTEGER PRIMARY KEY DEFAULT 1,
+  enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  allowed_domains TEXT[] NOT NULL DEFAULT '{}',
+  denied_domains TEXT[] NOT NULL DEFAULT '{}',
+  rate_limit_per_minute INTEGER NOT NULL DEFAULT 10,
+  max_bytes INTEGER NOT NULL DEFAULT 1048576,
+  timeout_seconds INTEGER NOT NULL DEFAULT 10,
+  cache_ttl_seconds INTEGER NOT NULL DEFAULT 900,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT chk_web_fetch_policy_singleton CHECK (id = 1)
+);
+
+COMMENT ON TABLE web_fetch_policy IS 'Domains, rate limit and size and time limits of the web_fetch tool of parrots';
@@ -0,0 +1,4 @@
+-- Rollback webhook registry
+
+DROP TABLE IF EXISTS webhook_delivery;
+DROP TABLE IF EXISTS webhook_endpoint;
@@ -0,0 +1,41 @@
+-- Add webhook_endpoint and webhook_delivery tables
+-- Signed callbacks of block, memo and schedule events to the endpoints of
+-- users and of the instance, with retries and delivery logs
+
+CREATE TABLE webhook_endpoint (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER,
+  name TEXT NOT NULL,
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  events TEXT[] NOT NULL DEFAULT '{}',
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_webhook_endpoint_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_endpoint_user ON webhook_endpoint(user_id);
+
+CREATE TABLE webhook_delivery (
+  id BIGSERIAL PRIMARY KEY,
+  endpoint_id INTEGER NOT NULL,
+  event_id TEXT NOT NULL,
+  event TEXT NOT NULL,
+  payload JSONB NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  response_code INTEGER NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT '',
+  next_attempt_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_webhook_delivery_endpoint FOREIGN KEY (endpoint_id) REFERENCES webhook_endpoint(id) ON DELETE CASCADE,
+  CONSTRAINT uq_webhook_delivery_event UNIQUE (endpoint_id, event_id),
+  CONSTRAINT chk_webhook_delivery_status CHECK (status IN ('pending', 'succeeded', 'failed'))
+);
+
+CREATE INDEX idx_webhook_delivery_due ON webhook_delivery(next_attempt_ts) WHERE status = 'pending';
+
+COMMENT ON TABLE webhook_endpoint IS 'URLs receiving signed callbacks of events of a user, or of all users when user_id is NULL';
+COMMENT ON TABLE webhook_delivery IS 'Delivery log and retry queue of webhook callbacks';
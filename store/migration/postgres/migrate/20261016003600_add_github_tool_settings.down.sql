@@ -0,0 +1,3 @@
+-- Rollback GitHub tool settings
+
+DROP TABLE IF EXISTS github_tool_settings;
@@ -0,0 +1,14 @@
+-- Add github_tool_settings table
+-- The GitHub token of users and the repositories the GitHub tools of their
+-- Geek Mode sessions may read and comment on
+
+CREATE TABLE github_tool_settings (
+  user_id INTEGER PRIMARY KEY,
+  token TEXT NOT NULL,
+  repositories TEXT[] NOT NULL DEFAULT '{}',
+  allow_comments BOOLEAN NOT NULL DEFAULT FALSE,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_github_tool_settings_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE github_tool_settings IS 'GitHub token and allow-listed repositories of the GitHub tools of Geek Mode sessions';
@@ -0,0 +1,3 @@
+-- Rollback Telegram links
+
+DROP TABLE IF EXISTS telegram_link;
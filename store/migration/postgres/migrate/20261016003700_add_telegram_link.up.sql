@@ -0,0 +1,18 @@
+-- Add telegram_link table
+-- The Telegram accounts linked to users through the Telegram gateway, with
+-- the conversation of their chat with the instance bot
+
+CREATE TABLE telegram_link (
+  user_id INTEGER PRIMARY KEY,
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  telegram_user_id BIGINT UNIQUE,
+  telegram_username TEXT NOT NULL DEFAULT '',
+  conversation_id INTEGER NOT NULL DEFAULT 0,
+  link_code TEXT UNIQUE,
+  link_code_expires_ts BIGINT NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_telegram_link_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE telegram_link IS 'Telegram accounts linked to users through the Telegram gateway';
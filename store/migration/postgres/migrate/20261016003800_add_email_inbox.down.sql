@@ -0,0 +1,3 @@
+-- Rollback email inboxes
+
+DROP TABLE IF EXISTS email_inbox;
@@ -0,0 +1,12 @@
+-- Add email_inbox table
+-- The secret addresses of users on the inbound email domain, whose emails
+-- are saved as memos
+
+CREATE TABLE email_inbox (
+  user_id INTEGER PRIMARY KEY,
+  token TEXT NOT NULL UNIQUE,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_email_inbox_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE email_inbox IS 'Secret inbound email addresses of users, saving their emails as memos';
@@ -0,0 +1,3 @@
+-- Rollback email accounts
+
+DROP TABLE IF EXISTS email_account;
@@ -0,0 +1,16 @@
+-- Add email_account table
+-- The IMAP accounts the mail expert reads, with their passwords encrypted
+
+CREATE TABLE email_account (
+  user_id INTEGER PRIMARY KEY,
+  host TEXT NOT NULL,
+  port INTEGER NOT NULL DEFAULT 993,
+  username TEXT NOT NULL,
+  password_encrypted TEXT NOT NULL,
+  mailbox TEXT NOT NULL DEFAULT 'INBOX',
+  allow_private BOOLEAN NOT NULL DEFAULT FALSE,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_email_account_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE email_account IS 'IMAP accounts of users read by the mail expert, passwords encrypted';
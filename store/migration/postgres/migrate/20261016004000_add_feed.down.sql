@@ -0,0 +1,4 @@
+-- Rollback feeds
+
+DROP TABLE IF EXISTS feed_entry;
+DROP TABLE IF EXISTS feed_subscription;
@@ -0,0 +1,47 @@
+-- Add feed_subscription and feed_entry tables
+-- RSS/Atom subscriptions whose entries are ingested as read-later memos
+
+CREATE TABLE feed_subscription (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  url TEXT NOT NULL,
+  title TEXT NOT NULL DEFAULT '',
+  site_url TEXT NOT NULL DEFAULT '',
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  etag TEXT NOT NULL DEFAULT '',
+  last_modified TEXT NOT NULL DEFAULT '',
+  last_fetched_ts BIGINT NOT NULL DEFAULT 0,
+  next_fetch_ts BIGINT NOT NULL DEFAULT 0,
+  failures INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_feed_subscription_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE,
+  CONSTRAINT uq_feed_subscription_url UNIQUE (user_id, url)
+);
+
+CREATE INDEX idx_feed_subscription_due ON feed_subscription(next_fetch_ts) WHERE enabled;
+
+COMMENT ON TABLE feed_subscription IS 'RSS/Atom feeds users subscribe to, fetched on schedule';
+
+CREATE TABLE feed_entry (
+  id SERIAL PRIMARY KEY,
+  subscription_id INTEGER NOT NULL,
+  user_id INTEGER NOT NULL,
+  guid TEXT NOT NULL,
+  title TEXT NOT NULL DEFAULT '',
+  url TEXT NOT NULL DEFAULT '',
+  summary TEXT NOT NULL DEFAULT '',
+  published_ts BIGINT NOT NULL DEFAULT 0,
+  status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'INGESTED')),
+  attempts INTEGER NOT NULL DEFAULT 0,
+  ai_summary TEXT NOT NULL DEFAULT '',
+  tags TEXT[] NOT NULL DEFAULT '{}',
+  memo_uid TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_feed_entry_subscription FOREIGN KEY (subscription_id) REFERENCES feed_subscription(id) ON DELETE CASCADE,
+  CONSTRAINT uq_feed_entry_guid UNIQUE (subscription_id, guid)
+);
+
+CREATE INDEX idx_feed_entry_pending ON feed_entry(created_ts) WHERE status = 'PENDING';
+
+COMMENT ON TABLE feed_entry IS 'Entries of subscribed feeds, ingested as read-later memos by background jobs';
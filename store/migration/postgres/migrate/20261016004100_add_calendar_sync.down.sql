@@ -0,0 +1,5 @@
+-- Rollback calendar sync
+
+DROP TABLE IF EXISTS calendar_event;
+DROP TABLE IF EXISTS calendar_account;
+DROP TABLE IF EXISTS calendar_feed;
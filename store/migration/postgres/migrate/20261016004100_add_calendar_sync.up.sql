@@ -0,0 +1,40 @@
+-- Add calendar_feed, calendar_account and calendar_event tables
+-- iCalendar feeds and two-way CalDAV sync of schedules
+
+CREATE TABLE calendar_feed (
+  user_id INTEGER PRIMARY KEY,
+  token TEXT NOT NULL UNIQUE,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_calendar_feed_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE calendar_feed IS 'Secret iCalendar feed URLs of the schedules of users';
+
+CREATE TABLE calendar_account (
+  user_id INTEGER PRIMARY KEY,
+  url TEXT NOT NULL,
+  username TEXT NOT NULL,
+  password_encrypted TEXT NOT NULL,
+  timezone TEXT NOT NULL DEFAULT 'Asia/Shanghai',
+  allow_private BOOLEAN NOT NULL DEFAULT FALSE,
+  last_sync_ts BIGINT NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_calendar_account_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE calendar_account IS 'CalDAV calendars synced with the schedules of users, passwords encrypted';
+
+-- No foreign key to schedule: the link of a deleted schedule deletes its event
+CREATE TABLE calendar_event (
+  user_id INTEGER NOT NULL,
+  uid TEXT NOT NULL,
+  schedule_id INTEGER NOT NULL,
+  href TEXT NOT NULL,
+  etag TEXT NOT NULL DEFAULT '',
+  schedule_updated_ts BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (user_id, uid),
+  CONSTRAINT fk_calendar_event_account FOREIGN KEY (user_id) REFERENCES calendar_account(user_id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE calendar_event IS 'Schedules synced with events of CalDAV calendars';
@@ -0,0 +1,5 @@
+-- Rollback schedule reminders
+
+DROP TABLE IF EXISTS reminder_delivery;
+DROP TABLE IF EXISTS reminder_push_subscription;
+DROP TABLE IF EXISTS reminder_setting;
@@ -0,0 +1,52 @@
+-- Add reminder_setting, reminder_push_subscription and reminder_delivery tables
+-- Schedule reminders delivered through web push, email, Telegram and webhooks
+
+CREATE TABLE reminder_setting (
+  user_id INTEGER PRIMARY KEY,
+  enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  channels TEXT[] NOT NULL DEFAULT '{}',
+  lead_minutes INTEGER[] NOT NULL DEFAULT '{}',
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_reminder_setting_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE reminder_setting IS 'Reminder channels and default lead times of users';
+
+CREATE TABLE reminder_push_subscription (
+  endpoint TEXT PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  p256dh TEXT NOT NULL,
+  auth TEXT NOT NULL,
+  user_agent TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_reminder_push_subscription_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_reminder_push_subscription_user ON reminder_push_subscription(user_id);
+
+COMMENT ON TABLE reminder_push_subscription IS 'Browsers subscribed to the web push reminders of users';
+
+-- No foreign key to schedule: deliveries outlive deleted schedules until pruned
+CREATE TABLE reminder_delivery (
+  id BIGSERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  schedule_uid TEXT NOT NULL,
+  start_ts BIGINT NOT NULL,
+  lead_minutes INTEGER NOT NULL,
+  channel TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT '',
+  notification JSONB NOT NULL DEFAULT '{}',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  sent_ts BIGINT NOT NULL DEFAULT 0,
+  CONSTRAINT fk_reminder_delivery_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE,
+  CONSTRAINT uq_reminder_delivery UNIQUE (user_id, schedule_uid, start_ts, lead_minutes, channel),
+  CONSTRAINT chk_reminder_delivery_status CHECK (status IN ('pending', 'sent', 'failed', 'skipped'))
+);
+
+CREATE INDEX idx_reminder_delivery_user ON reminder_delivery(user_id, start_ts DESC);
+CREATE INDEX idx_reminder_delivery_failed ON reminder_delivery(updated_ts) WHERE status = 'failed';
+
+COMMENT ON TABLE reminder_delivery IS 'Schedule reminders sent to users, one per channel';
@@ -0,0 +1,8 @@
+-- Rollback reminder escalations
+
+DROP TABLE IF EXISTS reminder_escalation;
+DROP TABLE IF EXISTS reminder_critical;
+ALTER TABLE reminder_delivery DROP COLUMN IF EXISTS escalation_id;
+ALTER TABLE reminder_setting
+  DROP COLUMN IF EXISTS escalation_minutes,
+  DROP COLUMN IF EXISTS escalation_channels;
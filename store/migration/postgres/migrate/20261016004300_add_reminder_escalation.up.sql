@@ -0,0 +1,43 @@
+-- Add reminder_critical and reminder_escalation tables
+-- Critical schedule reminders escalating through a chain of channels until acknowledged
+
+ALTER TABLE reminder_setting
+  ADD COLUMN escalation_channels TEXT[] NOT NULL DEFAULT '{web_push,telegram,email}',
+  ADD COLUMN escalation_minutes INTEGER NOT NULL DEFAULT 5;
+
+ALTER TABLE reminder_delivery ADD COLUMN escalation_id BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE reminder_critical (
+  user_id INTEGER NOT NULL,
+  schedule_uid TEXT NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  PRIMARY KEY (user_id, schedule_uid),
+  CONSTRAINT fk_reminder_critical_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE reminder_critical IS 'Schedules whose reminders escalate until acknowledged';
+
+CREATE TABLE reminder_escalation (
+  id BIGSERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  schedule_uid TEXT NOT NULL,
+  start_ts BIGINT NOT NULL,
+  lead_minutes INTEGER NOT NULL,
+  channels TEXT[] NOT NULL DEFAULT '{}',
+  delay_minutes INTEGER NOT NULL,
+  step INTEGER NOT NULL DEFAULT 0,
+  status TEXT NOT NULL DEFAULT 'active',
+  next_ts BIGINT NOT NULL DEFAULT 0,
+  acknowledged_ts BIGINT NOT NULL DEFAULT 0,
+  notification JSONB NOT NULL DEFAULT '{}',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_reminder_escalation_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE,
+  CONSTRAINT uq_reminder_escalation UNIQUE (user_id, schedule_uid, start_ts, lead_minutes),
+  CONSTRAINT chk_reminder_escalation_status CHECK (status IN ('active', 'acknowledged', 'exhausted'))
+);
+
+CREATE INDEX idx_reminder_escalation_user ON reminder_escalation(user_id, created_ts DESC);
+CREATE INDEX idx_reminder_escalation_active ON reminder_escalation(next_ts) WHERE status = 'active';
+
+COMMENT ON TABLE reminder_escalation IS 'Critical reminders escalating through a chain of channels';
@@ -0,0 +1,3 @@
+-- Rollback status incidents
+
+DROP TABLE IF EXISTS status_incident;
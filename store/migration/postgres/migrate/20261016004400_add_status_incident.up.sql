@@ -0,0 +1,20 @@
+-- Add status_incident table
+-- Incidents posted by admins on the public status page
+
+CREATE TABLE status_incident (
+  id SERIAL PRIMARY KEY,
+  title TEXT NOT NULL,
+  message TEXT NOT NULL DEFAULT '',
+  impact TEXT NOT NULL DEFAULT 'major',
+  stage TEXT NOT NULL DEFAULT 'investigating',
+  components TEXT[] NOT NULL DEFAULT '{}',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  resolved_ts BIGINT NOT NULL DEFAULT 0,
+  CONSTRAINT chk_status_incident_impact CHECK (impact IN ('minor', 'major', 'critical')),
+  CONSTRAINT chk_status_incident_stage CHECK (stage IN ('investigating', 'identified', 'monitoring', 'resolved'))
+);
+
+CREATE INDEX idx_status_incident_stage ON status_incident(stage, created_ts DESC);
+
+COMMENT ON TABLE status_incident IS 'Incidents posted by admins on the public status page';
@@ -0,0 +1,14 @@
+-- Add feature_flag table
+-- Admin rules overriding the defaults of the feature flags
+
+CREATE TABLE feature_flag (
+  key TEXT PRIMARY KEY,
+  enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  percent INTEGER NOT NULL DEFAULT 0,
+  users INTEGER[] NOT NULL DEFAULT '{}',
+  roles TEXT[] NOT NULL DEFAULT '{}',
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT chk_feature_flag_percent CHECK (percent BETWEEN 0 AND 100)
+);
+
+COMMENT ON TABLE feature_flag IS 'Admin rules overriding the defaults of the feature flags';
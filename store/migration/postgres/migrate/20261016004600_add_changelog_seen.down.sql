@@ -0,0 +1,3 @@
+-- Rollback changelog seen tracking
+
+DROP TABLE IF EXISTS changelog_seen;
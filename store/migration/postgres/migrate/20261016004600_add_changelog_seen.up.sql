@@ -0,0 +1,11 @@
+-- Add changelog_seen table
+-- Latest release whose notes each user has seen, for the "what's new" of upgrades
+
+CREATE TABLE changelog_seen (
+  user_id INTEGER PRIMARY KEY,
+  version TEXT NOT NULL,
+  seen_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_changelog_seen_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE changelog_seen IS 'Latest release whose notes each user has seen';
@@ -0,0 +1,4 @@
+-- Rollback the action audit log
+
+DROP TABLE IF EXISTS action_audit;
+DROP FUNCTION IF EXISTS reject_action_audit_change();
@@ -0,0 +1,39 @@
+-- Add action_audit table
+-- Append-only audit log of the tool calls of Geek and Evolution Mode sessions
+
+CREATE TABLE action_audit (
+  id BIGSERIAL PRIMARY KEY,
+  -- No foreign key: records outlive the users they audit
+  user_id INTEGER NOT NULL,
+  mode TEXT NOT NULL,
+  session_id TEXT NOT NULL DEFAULT '',
+  tool TEXT NOT NULL,
+  tool_id TEXT NOT NULL DEFAULT '',
+  input TEXT NOT NULL DEFAULT '',
+  file_paths TEXT[] NOT NULL DEFAULT '{}',
+  status TEXT NOT NULL,
+  output TEXT NOT NULL DEFAULT '',
+  duration_ms BIGINT NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT chk_action_audit_mode CHECK (mode IN ('geek', 'evolution')),
+  CONSTRAINT chk_action_audit_status CHECK (status IN ('success', 'error', 'blocked', 'unfinished'))
+);
+
+CREATE INDEX idx_action_audit_created ON action_audit(created_ts DESC);
+CREATE INDEX idx_action_audit_user ON action_audit(user_id, created_ts DESC);
+CREATE INDEX idx_action_audit_session ON action_audit(session_id);
+
+-- Records are never updated nor deleted
+CREATE OR REPLACE FUNCTION reject_action_audit_change()
+RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'action_audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_action_audit_append_only
+  BEFORE UPDATE OR DELETE ON action_audit
+  FOR EACH ROW
+  EXECUTE FUNCTION reject_action_audit_change();
+
+COMMENT ON TABLE action_audit IS 'Append-only audit log of the tool calls of Geek and Evolution Mode sessions';
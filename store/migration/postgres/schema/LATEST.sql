@@ -48,11 +48,7 @@ CREATE INDEX memo_embedding_idx
 ON memo USING hnsw (embedding vector_cosine_ops)
 WITH (m = 16, ef_construction = 64);
 
--- Create GIN index for BM25 full-text search (V0.98.0)
-CREATE INDEX IF NOT EXISTS idx_memo_content_gin
-ON memo USING gin(to_tsvector('simple', COALESCE(content, '')));
-
-COMMENT ON INDEX idx_memo_content_gin IS 'GIN index for BM25 full-text search on memo.content';
+-- Full-text search indexes of memo.content: see Multilingual Full-Text Search
 
 -- Memo list query optimization indexes (V1.0.0)
 -- Composite index for creator_id + visibility filter + pinned/created_ts sort
@@ -245,6 +241,7 @@ CREATE TABLE ai_conversation (
   created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
   updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()),
   row_status TEXT NOT NULL DEFAULT 'NORMAL',
+  metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
   CONSTRAINT fk_ai_conversation_creator
     FOREIGN KEY (creator_id)
     REFERENCES "user"(id)
@@ -259,6 +256,8 @@ CREATE INDEX idx_ai_conversation_creator ON ai_conversation(creator_id);
 CREATE INDEX idx_ai_conversation_updated ON ai_conversation(updated_ts DESC);
 CREATE INDEX idx_ai_conversation_title_source ON ai_conversation(title_source);
 
+COMMENT ON COLUMN ai_conversation.metadata IS 'Conversation settings, e.g. retrieval_scope';
+
 -- ai_message
 CREATE TABLE ai_message (
   id SERIAL PRIMARY KEY,
@@ -608,7 +607,7 @@ CREATE TABLE ai_block (
   CONSTRAINT chk_ai_block_mode
     CHECK (mode IN ('normal', 'geek', 'evolution')),
   CONSTRAINT chk_ai_block_status
-    CHECK (status IN ('pending', 'streaming', 'completed', 'error'))
+    CHECK (status IN ('pending', 'streaming', 'completed', 'error', 'interrupted'))
 );
 
 -- Indexes for ai_block
@@ -890,6 +889,1341 @@ BEGIN
 END;
 $$ LANGUAGE plpgsql IMMUTABLE;
 
+-- =============================================================================
+-- Script Hooks (V1.1.0)
+-- =============================================================================
+-- script_hook
+-- Admin-defined JavaScript hooks executed at trigger points
+CREATE TABLE script_hook (
+  id SERIAL PRIMARY KEY,
+  name TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  trigger TEXT NOT NULL CHECK (trigger IN ('memo.create', 'chat.request', 'chat.event')),
+  source TEXT NOT NULL,
+  priority INTEGER NOT NULL DEFAULT 0,
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  creator_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_script_hook_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_script_hook_trigger_enabled ON script_hook(trigger, enabled, priority);
+
+COMMENT ON TABLE script_hook IS 'Admin-defined JavaScript hooks executed at trigger points';
+
+-- =============================================================================
+-- HTTP Actions (V1.1.0)
+-- =============================================================================
+-- http_action
+-- Admin-registered external HTTP endpoints exposed to parrots as tools
+CREATE TABLE http_action (
+  id SERIAL PRIMARY KEY,
+  name TEXT NOT NULL UNIQUE,
+  description TEXT NOT NULL,
+  method TEXT NOT NULL CHECK (method IN ('GET', 'POST', 'PUT', 'PATCH', 'DELETE')),
+  url TEXT NOT NULL,
+  parameters JSONB NOT NULL DEFAULT '{}',
+  auth_header TEXT NOT NULL DEFAULT '',
+  auth_value TEXT NOT NULL DEFAULT '',
+  allowed_roles JSONB NOT NULL DEFAULT '[]',
+  rate_limit_per_minute INTEGER NOT NULL DEFAULT 0,
+  timeout_seconds INTEGER NOT NULL DEFAULT 0,
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  creator_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_http_action_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_http_action_enabled ON http_action(enabled);
+
+COMMENT ON TABLE http_action IS 'Admin-registered external HTTP endpoints exposed to parrots as tools';
+
+-- =============================================================================
+-- CC Session Affinity (V1.1.0)
+-- =============================================================================
+-- cc_session_affinity
+-- Instance holding the live Claude Code session of each conversation
+CREATE TABLE cc_session_affinity (
+  conversation_id INTEGER PRIMARY KEY,
+  instance_id TEXT NOT NULL,
+  instance_url TEXT NOT NULL,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+CREATE INDEX idx_cc_session_affinity_updated ON cc_session_affinity(updated_ts);
+
+-- =============================================================================
+-- Scheduled Messages (V1.1.0)
+-- =============================================================================
+-- scheduled_message
+-- Chat messages scheduled to be sent into a conversation at a future time
+CREATE TABLE scheduled_message (
+  id SERIAL PRIMARY KEY,
+  creator_id INTEGER NOT NULL,
+  conversation_id INTEGER NOT NULL,
+  message TEXT NOT NULL,
+  agent_type TEXT NOT NULL DEFAULT '',
+  timezone TEXT NOT NULL DEFAULT '',
+  scheduled_ts BIGINT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'RUNNING', 'COMPLETED', 'FAILED', 'CANCELED')),
+  block_id BIGINT NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT '',
+  urgent BOOLEAN NOT NULL DEFAULT FALSE,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_scheduled_message_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE,
+  CONSTRAINT fk_scheduled_message_conversation
+    FOREIGN KEY (conversation_id)
+    REFERENCES ai_conversation(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_scheduled_message_due ON scheduled_message(status, scheduled_ts);
+CREATE INDEX idx_scheduled_message_creator ON scheduled_message(creator_id);
+
+COMMENT ON TABLE scheduled_message IS 'Chat messages scheduled to be sent into a conversation at a future time';
+
+-- =============================================================================
+-- Knowledge Graph (V1.1.0)
+-- =============================================================================
+-- kg_entity, kg_mention, kg_relation
+-- People, projects and terms extracted from memos and conversations
+CREATE TABLE kg_entity (
+  id SERIAL PRIMARY KEY,
+  creator_id INTEGER NOT NULL,
+  name TEXT NOT NULL,
+  normalized_name TEXT NOT NULL,
+  kind TEXT NOT NULL CHECK (kind IN ('PERSON', 'PROJECT', 'TERM')),
+  description TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_kg_entity_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE,
+  CONSTRAINT uq_kg_entity_name UNIQUE (creator_id, kind, normalized_name)
+);
+
+CREATE TABLE kg_mention (
+  id SERIAL PRIMARY KEY,
+  entity_id INTEGER NOT NULL,
+  source_type TEXT NOT NULL CHECK (source_type IN ('MEMO', 'CONVERSATION')),
+  source_id INTEGER NOT NULL,
+  source_ref TEXT NOT NULL DEFAULT '',
+  snippet TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_kg_mention_entity
+    FOREIGN KEY (entity_id)
+    REFERENCES kg_entity(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_kg_mention_entity ON kg_mention(entity_id, created_ts DESC);
+CREATE INDEX idx_kg_mention_source ON kg_mention(source_type, source_id);
+
+CREATE TABLE kg_relation (
+  id SERIAL PRIMARY KEY,
+  from_entity_id INTEGER NOT NULL,
+  to_entity_id INTEGER NOT NULL,
+  relation TEXT NOT NULL,
+  source_type TEXT NOT NULL CHECK (source_type IN ('MEMO', 'CONVERSATION')),
+  source_id INTEGER NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_kg_relation_from
+    FOREIGN KEY (from_entity_id)
+    REFERENCES kg_entity(id)
+    ON DELETE CASCADE,
+  CONSTRAINT fk_kg_relation_to
+    FOREIGN KEY (to_entity_id)
+    REFERENCES kg_entity(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_kg_relation_from ON kg_relation(from_entity_id);
+CREATE INDEX idx_kg_relation_to ON kg_relation(to_entity_id);
+CREATE INDEX idx_kg_relation_source ON kg_relation(source_type, source_id);
+
+COMMENT ON TABLE kg_entity IS 'People, projects and terms extracted from a user''s memos and conversations';
+COMMENT ON TABLE kg_mention IS 'Places where an entity is mentioned';
+COMMENT ON TABLE kg_relation IS 'Relations between entities, one row per source stating them';
+
+-- =============================================================================
+-- Flashcards (V1.1.0)
+-- =============================================================================
+-- flashcard, flashcard_review
+-- Question/answer cards generated from memos, scheduled with SM-2
+CREATE TABLE flashcard (
+  id SERIAL PRIMARY KEY,
+  creator_id INTEGER NOT NULL,
+  memo_id INTEGER NOT NULL,
+  question TEXT NOT NULL,
+  answer TEXT NOT NULL,
+  ease_factor DOUBLE PRECISION NOT NULL DEFAULT 2.5,
+  interval_days INTEGER NOT NULL DEFAULT 0,
+  review_count INTEGER NOT NULL DEFAULT 0,
+  due_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  last_review_ts BIGINT NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_flashcard_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE,
+  CONSTRAINT fk_flashcard_memo
+    FOREIGN KEY (memo_id)
+    REFERENCES memo(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_flashcard_creator_due ON flashcard(creator_id, due_ts);
+CREATE INDEX idx_flashcard_memo ON flashcard(memo_id);
+
+CREATE TABLE flashcard_review (
+  id SERIAL PRIMARY KEY,
+  card_id INTEGER NOT NULL,
+  creator_id INTEGER NOT NULL,
+  quality INTEGER NOT NULL CHECK (quality BETWEEN 0 AND 3),
+  interval_days INTEGER NOT NULL,
+  reviewed_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_flashcard_review_card
+    FOREIGN KEY (card_id)
+    REFERENCES flashcard(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_flashcard_review_creator ON flashcard_review(creator_id, reviewed_ts);
+
+COMMENT ON TABLE flashcard IS 'Question/answer cards generated from memos for spaced repetition';
+COMMENT ON TABLE flashcard_review IS 'Review log of flashcards, used for statistics';
+
+-- =============================================================================
+-- Knowledge-Base Health Report (V1.1.0)
+-- =============================================================================
+-- kb_health_setting
+-- Per-user settings of the weekly knowledge-base health report
+CREATE TABLE kb_health_setting (
+  user_id INTEGER PRIMARY KEY,
+  enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  delivery TEXT NOT NULL DEFAULT 'MEMO' CHECK (delivery IN ('MEMO', 'WEBHOOK')),
+  stale_days INTEGER NOT NULL DEFAULT 180,
+  duplicate_threshold DOUBLE PRECISION NOT NULL DEFAULT 0.9,
+  max_items INTEGER NOT NULL DEFAULT 10,
+  last_report_ts BIGINT NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_kb_health_setting_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_kb_health_setting_due ON kb_health_setting(last_report_ts) WHERE enabled;
+
+COMMENT ON TABLE kb_health_setting IS 'Per-user settings of the weekly knowledge-base health report';
+
+-- =============================================================================
+-- PDF Ingestion (V1.1.0)
+-- =============================================================================
+-- pdf_document, pdf_chunk
+-- PDF attachments split into page-aware, embedded chunks for search with page citations
+CREATE TABLE pdf_document (
+  attachment_id INTEGER PRIMARY KEY,
+  creator_id INTEGER NOT NULL,
+  status TEXT NOT NULL DEFAULT 'PENDING'
+    CHECK (status IN ('PENDING', 'PROCESSING', 'DONE', 'FAILED', 'SKIPPED')),
+  error TEXT NOT NULL DEFAULT '',
+  page_count INTEGER NOT NULL DEFAULT 0,
+  chunk_count INTEGER NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_pdf_document_attachment
+    FOREIGN KEY (attachment_id)
+    REFERENCES attachment(id)
+    ON DELETE CASCADE,
+  CONSTRAINT fk_pdf_document_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_pdf_document_creator ON pdf_document(creator_id, created_ts);
+CREATE INDEX idx_pdf_document_status ON pdf_document(status, updated_ts) WHERE status IN ('PENDING', 'PROCESSING');
+
+CREATE TABLE pdf_chunk (
+  id SERIAL PRIMARY KEY,
+  attachment_id INTEGER NOT NULL,
+  creator_id INTEGER NOT NULL,
+  page INTEGER NOT NULL,
+  chunk_index INTEGER NOT NULL,
+  content TEXT NOT NULL,
+  embedding vector(1024) NOT NULL,
+  model VARCHAR(100) NOT NULL,
+  CONSTRAINT fk_pdf_chunk_document
+    FOREIGN KEY (attachment_id)
+    REFERENCES pdf_document(attachment_id)
+    ON DELETE CASCADE,
+  CONSTRAINT uq_pdf_chunk_index
+    UNIQUE (attachment_id, chunk_index)
+);
+
+CREATE INDEX idx_pdf_chunk_creator ON pdf_chunk(creator_id, model);
+CREATE INDEX idx_pdf_chunk_hnsw
+ON pdf_chunk USING hnsw (embedding vector_cosine_ops)
+WITH (m = 16, ef_construction = 64);
+
+COMMENT ON TABLE pdf_document IS 'Ingestion status of PDF attachments';
+COMMENT ON TABLE pdf_chunk IS 'Embedded passages of PDF attachments; chunks never span two pages';
+
+-- =============================================================================
+-- Code Snippets (V1.1.0)
+-- =============================================================================
+-- code_snippet
+-- Code blocks of memos tagged #snippet, with language and defined symbols
+CREATE TABLE code_snippet (
+  memo_id INTEGER NOT NULL,
+  block_index INTEGER NOT NULL,
+  creator_id INTEGER NOT NULL,
+  language TEXT NOT NULL DEFAULT '',
+  code TEXT NOT NULL,
+  symbols TEXT[] NOT NULL DEFAULT '{}',
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  PRIMARY KEY (memo_id, block_index),
+  CONSTRAINT fk_code_snippet_memo
+    FOREIGN KEY (memo_id)
+    REFERENCES memo(id)
+    ON DELETE CASCADE,
+  CONSTRAINT fk_code_snippet_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_code_snippet_creator ON code_snippet(creator_id, language);
+CREATE INDEX idx_code_snippet_symbols ON code_snippet USING gin (symbols);
+
+COMMENT ON TABLE code_snippet IS 'Code blocks of snippet memos, searchable by symbol and regex';
+
+-- =============================================================================
+-- Web Capture (V1.1.0)
+-- =============================================================================
+-- memo_source
+-- Source page of memos captured by the browser extension
+CREATE TABLE memo_source (
+  memo_id INTEGER PRIMARY KEY,
+  creator_id INTEGER NOT NULL,
+  url TEXT NOT NULL,
+  title TEXT NOT NULL DEFAULT '',
+  site TEXT NOT NULL DEFAULT '',
+  client TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_memo_source_memo
+    FOREIGN KEY (memo_id)
+    REFERENCES memo(id)
+    ON DELETE CASCADE,
+  CONSTRAINT fk_memo_source_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_memo_source_creator_site ON memo_source(creator_id, site);
+
+COMMENT ON TABLE memo_source IS 'Web page a memo was captured from by a browser extension';
+
+-- =============================================================================
+-- Read Later (V1.1.0)
+-- =============================================================================
+-- read_later_item, read_later_setting
+-- Read-later queue ranked by an LLM against the user's interests, with periodic digests
+CREATE TABLE read_later_item (
+  memo_id INTEGER PRIMARY KEY,
+  creator_id INTEGER NOT NULL,
+  status TEXT NOT NULL DEFAULT 'QUEUED'
+    CHECK (status IN ('QUEUED', 'READ', 'DISMISSED')),
+  score INTEGER CHECK (score BETWEEN 0 AND 100),
+  reason TEXT NOT NULL DEFAULT '',
+  scored_ts BIGINT NOT NULL DEFAULT 0,
+  snoozed_until BIGINT NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_read_later_item_memo
+    FOREIGN KEY (memo_id)
+    REFERENCES memo(id)
+    ON DELETE CASCADE,
+  CONSTRAINT fk_read_later_item_creator
+    FOREIGN KEY (creator_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_read_later_item_queue ON read_later_item(creator_id, status, score DESC NULLS LAST);
+CREATE INDEX idx_read_later_item_unscored ON read_later_item(scored_ts) WHERE status = 'QUEUED' AND score IS NULL;
+
+CREATE TABLE read_later_setting (
+  user_id INTEGER PRIMARY KEY,
+  interests TEXT NOT NULL DEFAULT '',
+  digest_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  delivery TEXT NOT NULL DEFAULT 'MEMO'
+    CHECK (delivery IN ('MEMO', 'WEBHOOK')),
+  digest_days INTEGER NOT NULL DEFAULT 1,
+  digest_size INTEGER NOT NULL DEFAULT 5,
+  last_digest_ts BIGINT NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_read_later_setting_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_read_later_setting_due ON read_later_setting(last_digest_ts) WHERE digest_enabled;
+
+COMMENT ON TABLE read_later_item IS 'Memos queued for reading later, ranked by interest';
+COMMENT ON TABLE read_later_setting IS 'Per-user interests and digest settings of the read-later queue';
+
+-- =============================================================================
+-- Quiet Hours (V1.1.0)
+-- =============================================================================
+-- user_quiet_hours
+-- Per-user daily quiet hours honored by background jobs and notifications
+CREATE TABLE user_quiet_hours (
+  user_id INTEGER PRIMARY KEY,
+  enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  start_minute INTEGER NOT NULL DEFAULT 1320 CHECK (start_minute BETWEEN 0 AND 1439),
+  end_minute INTEGER NOT NULL DEFAULT 420 CHECK (end_minute BETWEEN 0 AND 1439),
+  timezone TEXT NOT NULL DEFAULT 'UTC',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_user_quiet_hours_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+COMMENT ON TABLE user_quiet_hours IS 'Per-user daily quiet hours honored by background jobs and notifications';
+
+-- =============================================================================
+-- Offline Sync (V1.1.0)
+-- =============================================================================
+-- sync_change, sync_conflict
+-- Change log of memos and conversations for offline-first clients, and the
+-- offline edits that conflicted with server changes
+CREATE TABLE sync_change (
+  id BIGSERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  entity TEXT NOT NULL CHECK (entity IN ('memo', 'conversation')),
+  entity_uid TEXT NOT NULL,
+  changed_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+CREATE INDEX idx_sync_change_user ON sync_change(user_id, id);
+CREATE INDEX idx_sync_change_entity ON sync_change(entity, entity_uid, id);
+CREATE INDEX idx_sync_change_changed ON sync_change(changed_ts);
+
+-- Records a change of the row in sync_change; TG_ARGV[0] is the entity
+CREATE OR REPLACE FUNCTION record_sync_change()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    INSERT INTO sync_change (user_id, entity, entity_uid) VALUES (OLD.creator_id, TG_ARGV[0], OLD.uid);
+    RETURN OLD;
+  END IF;
+  INSERT INTO sync_change (user_id, entity, entity_uid) VALUES (NEW.creator_id, TG_ARGV[0], NEW.uid);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_memo_sync_change
+  AFTER INSERT OR DELETE OR UPDATE OF content, visibility, pinned, row_status ON memo
+  FOR EACH ROW
+  EXECUTE FUNCTION record_sync_change('memo');
+
+CREATE TRIGGER trigger_ai_conversation_sync_change
+  AFTER INSERT OR DELETE OR UPDATE OF title, parrot_id, pinned, row_status ON ai_conversation
+  FOR EACH ROW
+  EXECUTE FUNCTION record_sync_change('conversation');
+
+CREATE TABLE sync_conflict (
+  id BIGSERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  entity TEXT NOT NULL CHECK (entity IN ('memo', 'conversation')),
+  entity_uid TEXT NOT NULL,
+  kind TEXT NOT NULL CHECK (kind IN ('edit_edit', 'edit_deleted', 'delete_edited')),
+  client JSONB NOT NULL,
+  server JSONB NOT NULL,
+  resolution TEXT NOT NULL DEFAULT ''
+    CHECK (resolution IN ('', 'server', 'client', 'merged')),
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  resolved_ts BIGINT NOT NULL DEFAULT 0,
+  CONSTRAINT fk_sync_conflict_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_sync_conflict_open ON sync_conflict(user_id) WHERE resolved_ts = 0;
+
+COMMENT ON TABLE sync_change IS 'Change log of memos and conversations read by offline-first clients; superseded changes and tombstones are pruned after 90 days';
+COMMENT ON TABLE sync_conflict IS 'Offline edits that conflicted with server changes, until the user resolves them';
+
+-- =============================================================================
+-- Block Budgets (V1.1.0)
+-- =============================================================================
+-- user_block_budget
+-- Per-user maximum tokens and estimated cost of a chat block; 0 is unlimited
+CREATE TABLE user_block_budget (
+  user_id INTEGER PRIMARY KEY,
+  max_tokens INTEGER NOT NULL DEFAULT 0 CHECK (max_tokens >= 0),
+  max_cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (max_cost_usd >= 0),
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_user_block_budget_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+COMMENT ON TABLE user_block_budget IS 'Per-user maximum tokens and estimated cost of a chat block; 0 is unlimited';
+
+-- =============================================================================
+-- Intent Taxonomy (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE intent_taxonomy (
+  name TEXT PRIMARY KEY,
+  examples JSONB NOT NULL DEFAULT '[]',
+  target_expert TEXT NOT NULL DEFAULT '',
+  orchestrate BOOLEAN NOT NULL DEFAULT FALSE,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+COMMENT ON TABLE intent_taxonomy IS 'Chat intents with example phrases, target expert and orchestration flag';
+
+-- =============================================================================
+-- Few-Shot Examples (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE few_shot_example (
+  id SERIAL PRIMARY KEY,
+  route TEXT NOT NULL,
+  query TEXT NOT NULL,
+  answer TEXT NOT NULL DEFAULT '',
+  holdout BOOLEAN NOT NULL DEFAULT FALSE,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+COMMENT ON TABLE few_shot_example IS 'Few-shot examples of routes and answer styles, with a holdout set for evaluation';
+
+-- =============================================================================
+-- Canary Experts (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE canary_rollout (
+  name TEXT PRIMARY KEY,
+  percent INTEGER NOT NULL DEFAULT 0,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+COMMENT ON TABLE canary_rollout IS 'Traffic percentages of canary experts set by admins';
+
+CREATE TABLE canary_comparison (
+  id SERIAL PRIMARY KEY,
+  canary TEXT NOT NULL,
+  incumbent TEXT NOT NULL,
+  user_id INTEGER NOT NULL,
+  query TEXT NOT NULL,
+  canary_answer TEXT NOT NULL DEFAULT '',
+  incumbent_answer TEXT NOT NULL DEFAULT '',
+  canary_latency_ms BIGINT NOT NULL DEFAULT 0,
+  incumbent_latency_ms BIGINT NOT NULL DEFAULT 0,
+  canary_tokens INTEGER NOT NULL DEFAULT 0,
+  incumbent_tokens INTEGER NOT NULL DEFAULT 0,
+  canary_error TEXT NOT NULL DEFAULT '',
+  incumbent_error TEXT NOT NULL DEFAULT '',
+  similarity DOUBLE PRECISION NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+CREATE INDEX idx_canary_comparison_canary ON canary_comparison (canary, created_ts);
+
+COMMENT ON TABLE canary_comparison IS 'Requests served by a canary expert with the shadow answer of its incumbent';
+
+-- =============================================================================
+-- Shadow Runs (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE shadow_sample_rate (
+  name TEXT PRIMARY KEY,
+  sample_rate INTEGER NOT NULL DEFAULT 0,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+COMMENT ON TABLE shadow_sample_rate IS 'Sample rates of shadow parrots set by admins';
+
+CREATE TABLE shadow_run (
+  id SERIAL PRIMARY KEY,
+  shadow TEXT NOT NULL,
+  production TEXT NOT NULL,
+  model TEXT NOT NULL DEFAULT '',
+  user_id INTEGER NOT NULL,
+  query TEXT NOT NULL,
+  production_answer TEXT NOT NULL DEFAULT '',
+  shadow_answer TEXT NOT NULL DEFAULT '',
+  production_latency_ms BIGINT NOT NULL DEFAULT 0,
+  shadow_latency_ms BIGINT NOT NULL DEFAULT 0,
+  production_tokens INTEGER NOT NULL DEFAULT 0,
+  shadow_tokens INTEGER NOT NULL DEFAULT 0,
+  production_error TEXT NOT NULL DEFAULT '',
+  shadow_error TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+CREATE INDEX idx_shadow_run_shadow ON shadow_run (shadow, created_ts);
+
+COMMENT ON TABLE shadow_run IS 'Requests answered by a production parrot and, unseen, by its shadow';
+
+-- =============================================================================
+-- Locked Conversations (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE ai_conversation_lock (
+  conversation_id INTEGER PRIMARY KEY REFERENCES ai_conversation(id) ON DELETE CASCADE,
+  creator_id INTEGER NOT NULL,
+  salt BYTEA NOT NULL,
+  verifier BYTEA NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+COMMENT ON TABLE ai_conversation_lock IS 'Conversations whose history is encrypted with a user passphrase';
+
+-- =============================================================================
+-- Geek Mode Tool Policies (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE geek_tool_policy (
+  user_id INTEGER NOT NULL,
+  role TEXT NOT NULL DEFAULT '',
+  allowed_tools TEXT[] NOT NULL DEFAULT '{}',
+  disallowed_tools TEXT[] NOT NULL DEFAULT '{}',
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  PRIMARY KEY (user_id, role),
+  CONSTRAINT chk_geek_tool_policy_role
+    CHECK (role IN ('', 'HOST', 'ADMIN', 'USER') AND (role = '' OR user_id = 0))
+);
+
+COMMENT ON TABLE geek_tool_policy IS 'Claude Code tools permitted in Geek Mode workspaces (user_id 0 is the default, or the role policy with a role)';
+
+-- =============================================================================
+-- Evolution Task Backlog (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE evolution_task (
+  id BIGSERIAL PRIMARY KEY,
+  title TEXT NOT NULL,
+  description TEXT NOT NULL,
+  creator_id INTEGER NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending',
+  reviewer_id INTEGER NOT NULL DEFAULT 0,
+  review_note TEXT NOT NULL DEFAULT '',
+  runner_id INTEGER NOT NULL DEFAULT 0,
+  conversation_id INTEGER NOT NULL DEFAULT 0,
+  block_ids BIGINT[] NOT NULL DEFAULT '{}',
+  base_commit TEXT NOT NULL DEFAULT '',
+  head_commit TEXT NOT NULL DEFAULT '',
+  diff_stat TEXT NOT NULL DEFAULT '',
+  pr_url TEXT NOT NULL DEFAULT '',
+  test_status TEXT NOT NULL DEFAULT '',
+  test_output TEXT NOT NULL DEFAULT '',
+  error TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT chk_evolution_task_status
+    CHECK (status IN ('pending', 'approved', 'rejected', 'running', 'completed', 'failed'))
+);
+
+CREATE INDEX idx_evolution_task_status ON evolution_task(status, id);
+
+COMMENT ON TABLE evolution_task IS 'Evolution Mode task backlog with approval workflow and run traceability';
+
+-- =============================================================================
+-- Agent Model Selection (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE ai_agent_model (
+  agent TEXT PRIMARY KEY,
+  provider TEXT NOT NULL,
+  model TEXT NOT NULL DEFAULT '',
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT
+);
+
+COMMENT ON TABLE ai_agent_model IS 'LLM provider and model of agent types selected at runtime by admins';
+
+-- =============================================================================
+-- Embedding Index Rebuilds (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE embedding_rebuild (
+  id SERIAL PRIMARY KEY,
+  source_model TEXT NOT NULL,
+  target_model TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'running',
+  total INTEGER NOT NULL DEFAULT 0,
+  processed INTEGER NOT NULL DEFAULT 0,
+  recall DOUBLE PRECISION NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT chk_embedding_rebuild_status
+    CHECK (status IN ('running', 'rejected', 'failed', 'swapped', 'canceled'))
+);
+
+CREATE INDEX idx_embedding_rebuild_status ON embedding_rebuild(status, updated_ts DESC);
+
+COMMENT ON TABLE embedding_rebuild IS 'Memo embedding index rebuilds with resumable progress and atomic model swap';
+
+-- =============================================================================
+-- Search Relevance Feedback (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE search_log (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  query TEXT NOT NULL,
+  semantic_weight REAL NOT NULL,
+  candidates JSONB NOT NULL DEFAULT '[]',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_search_log_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_search_log_user_query ON search_log(user_id, query, created_ts DESC);
+CREATE INDEX idx_search_log_created ON search_log(created_ts);
+
+CREATE TABLE search_feedback (
+  search_id INTEGER NOT NULL,
+  memo_id INTEGER NOT NULL,
+  relevant BOOLEAN NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  PRIMARY KEY (search_id, memo_id),
+  CONSTRAINT fk_search_feedback_search
+    FOREIGN KEY (search_id)
+    REFERENCES search_log(id)
+    ON DELETE CASCADE,
+  CONSTRAINT fk_search_feedback_memo
+    FOREIGN KEY (memo_id)
+    REFERENCES memo(id)
+    ON DELETE CASCADE
+);
+
+CREATE TABLE search_weight (
+  user_id INTEGER PRIMARY KEY,
+  semantic_weight REAL NOT NULL CHECK (semantic_weight BETWEEN 0 AND 1),
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_search_weight_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+COMMENT ON TABLE search_log IS 'Hybrid memo searches with the vector and BM25 rank of each candidate';
+COMMENT ON TABLE search_weight IS 'Per-user hybrid search semantic weight tuned from relevance feedback';
+
+-- =============================================================================
+-- Geek Mode MCP Servers (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE geek_mcp_server (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  name TEXT NOT NULL,
+  transport TEXT NOT NULL CHECK (transport IN ('stdio', 'http', 'sse')),
+  command TEXT NOT NULL DEFAULT '',
+  args TEXT[] NOT NULL DEFAULT '{}',
+  env JSONB NOT NULL DEFAULT '{}',
+  url TEXT NOT NULL DEFAULT '',
+  headers JSONB NOT NULL DEFAULT '{}',
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_geek_mcp_server_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE,
+  CONSTRAINT uq_geek_mcp_server_name UNIQUE (user_id, name)
+);
+
+COMMENT ON TABLE geek_mcp_server IS 'MCP servers users register for their Geek Mode Claude Code sessions';
+
+-- =============================================================================
+-- Checklists (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE checklist (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  title TEXT NOT NULL,
+  items JSONB NOT NULL DEFAULT '[]',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_checklist_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_checklist_user_updated ON checklist (user_id, updated_ts DESC);
+
+COMMENT ON TABLE checklist IS 'Checklists of users with their items and checked state';
+
+-- =============================================================================
+-- Habits (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE habit (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  name TEXT NOT NULL,
+  frequency TEXT NOT NULL DEFAULT 'DAILY' CHECK (frequency IN ('DAILY', 'WEEKLY')),
+  target INTEGER NOT NULL DEFAULT 1 CHECK (target > 0),
+  archived BOOLEAN NOT NULL DEFAULT FALSE,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_habit_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_habit_user ON habit (user_id);
+
+COMMENT ON TABLE habit IS 'Habits users track with a daily or weekly target';
+
+CREATE TABLE habit_completion (
+  habit_id INTEGER NOT NULL,
+  date DATE NOT NULL,
+  count INTEGER NOT NULL CHECK (count > 0),
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  PRIMARY KEY (habit_id, date),
+  CONSTRAINT fk_habit_completion_habit
+    FOREIGN KEY (habit_id)
+    REFERENCES habit(id)
+    ON DELETE CASCADE
+);
+
+COMMENT ON TABLE habit_completion IS 'Number of times a habit was done on a day';
+
+-- =============================================================================
+-- Expenses (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE expense (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  amount BIGINT NOT NULL CHECK (amount > 0),
+  currency TEXT NOT NULL DEFAULT 'CNY',
+  category TEXT NOT NULL DEFAULT 'other',
+  note TEXT NOT NULL DEFAULT '',
+  date DATE NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_expense_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_expense_user_date ON expense (user_id, date DESC);
+
+COMMENT ON TABLE expense IS 'Expenses of users, amounts in minor units of the currency';
+
+-- =============================================================================
+-- Contact Reminders (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE contact_reminder (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  person TEXT NOT NULL,
+  normalized_person TEXT NOT NULL,
+  note TEXT NOT NULL DEFAULT '',
+  due_date DATE NOT NULL,
+  done_ts BIGINT NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_contact_reminder_user
+    FOREIGN KEY (user_id)
+    REFERENCES "user"(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_contact_reminder_user_person ON contact_reminder (user_id, normalized_person);
+CREATE INDEX idx_contact_reminder_user_due ON contact_reminder (user_id, due_date) WHERE done_ts = 0;
+
+COMMENT ON TABLE contact_reminder IS 'Follow-ups of users with the people they know';
+
+-- =============================================================================
+-- Multilingual Full-Text Search (V1.1.0)
+-- =============================================================================
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- cjk_bigrams splits the runs of Han, kana and Hangul characters into their
+-- overlapping bigrams, separated from the rest of the text by spaces,
+-- e.g. '学习Go语言' into '学习 Go 语言'. The ranges are the ones of
+-- textsearch.Bigrams, which segments the queries.
+CREATE OR REPLACE FUNCTION cjk_bigrams(doc text)
+RETURNS text AS $$
+DECLARE
+    run text;
+    parts text[] := '{}';
+BEGIN
+    FOR run IN
+        SELECT m[1] FROM regexp_matches(COALESCE(doc, ''),
+            '([\u3040-\u30ff\u3400-\u4dbf\u4e00-\u9fff\uac00-\ud7af\uf900-\ufaff\U00020000-\U0002fa1f]+|[^\u3040-\u30ff\u3400-\u4dbf\u4e00-\u9fff\uac00-\ud7af\uf900-\ufaff\U00020000-\U0002fa1f]+)',
+            'g') AS m
+    LOOP
+        IF char_length(run) > 1 AND run ~ '^[\u3040-\u30ff\u3400-\u4dbf\u4e00-\u9fff\uac00-\ud7af\uf900-\ufaff\U00020000-\U0002fa1f]' THEN
+            FOR i IN 1 .. char_length(run) - 1 LOOP
+                parts := parts || substr(run, i, 2);
+            END LOOP;
+        ELSE
+            parts := parts || run;
+        END IF;
+    END LOOP;
+    RETURN array_to_string(parts, ' ');
+END;
+$$ LANGUAGE plpgsql IMMUTABLE PARALLEL SAFE;
+
+-- Full-text index of the default configuration, and trigram index of
+-- substring search
+CREATE INDEX IF NOT EXISTS idx_memo_content_fts
+ON memo USING gin(to_tsvector('simple', cjk_bigrams(content)));
+
+CREATE INDEX IF NOT EXISTS idx_memo_content_trgm
+ON memo USING gin(content gin_trgm_ops);
+
+COMMENT ON INDEX idx_memo_content_fts IS 'GIN index for full-text search on the CJK bigrams of memo.content';
+COMMENT ON INDEX idx_memo_content_trgm IS 'Trigram index for substring search on memo.content';
+
+-- =============================================================================
+-- Web Fetch Policy (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE web_fetch_policy (
+  id INTEGER PRIMARY KEY DEFAULT 1,
+  enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  allowed_domains TEXT[] NOT NULL DEFAULT '{}',
+  denied_domains TEXT[] NOT NULL DEFAULT '{}',
+  rate_limit_per_minute INTEGER NOT NULL DEFAULT 10,
+  max_bytes INTEGER NOT NULL DEFAULT 1048576,
+  timeout_seconds INTEGER NOT NULL DEFAULT 10,
+  cache_ttl_seconds INTEGER NOT NULL DEFAULT 900,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT chk_web_fetch_policy_singleton CHECK (id = 1)
+);
+
+COMMENT ON TABLE web_fetch_policy IS 'Domains, rate limit and size and time limits of the web_fetch tool of parrots';
+
+-- =============================================================================
+-- Webhook Registry (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE webhook_endpoint (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER,
+  name TEXT NOT NULL,
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  events TEXT[] NOT NULL DEFAULT '{}',
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_webhook_endpoint_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_endpoint_user ON webhook_endpoint(user_id);
+
+CREATE TABLE webhook_delivery (
+  id BIGSERIAL PRIMARY KEY,
+  endpoint_id INTEGER NOT NULL,
+  event_id TEXT NOT NULL,
+  event TEXT NOT NULL,
+  payload JSONB NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  response_code INTEGER NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT '',
+  next_attempt_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_webhook_delivery_endpoint FOREIGN KEY (endpoint_id) REFERENCES webhook_endpoint(id) ON DELETE CASCADE,
+  CONSTRAINT uq_webhook_delivery_event UNIQUE (endpoint_id, event_id),
+  CONSTRAINT chk_webhook_delivery_status CHECK (status IN ('pending', 'succeeded', 'failed'))
+);
+
+CREATE INDEX idx_webhook_delivery_due ON webhook_delivery(next_attempt_ts) WHERE status = 'pending';
+
+COMMENT ON TABLE webhook_endpoint IS 'URLs receiving signed callbacks of events of a user, or of all users when user_id is NULL';
+COMMENT ON TABLE webhook_delivery IS 'Delivery log and retry queue of webhook callbacks';
+
+-- =============================================================================
+-- GitHub Tool Settings (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE github_tool_settings (
+  user_id INTEGER PRIMARY KEY,
+  token TEXT NOT NULL,
+  repositories TEXT[] NOT NULL DEFAULT '{}',
+  allow_comments BOOLEAN NOT NULL DEFAULT FALSE,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_github_tool_settings_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE github_tool_settings IS 'GitHub token and allow-listed repositories of the GitHub tools of Geek Mode sessions';
+
+-- =============================================================================
+-- Telegram Link (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE telegram_link (
+  user_id INTEGER PRIMARY KEY,
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  telegram_user_id BIGINT UNIQUE,
+  telegram_username TEXT NOT NULL DEFAULT '',
+  conversation_id INTEGER NOT NULL DEFAULT 0,
+  link_code TEXT UNIQUE,
+  link_code_expires_ts BIGINT NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_telegram_link_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE telegram_link IS 'Telegram accounts linked to users through the Telegram gateway';
+
+-- =============================================================================
+-- Email Inbox (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE email_inbox (
+  user_id INTEGER PRIMARY KEY,
+  token TEXT NOT NULL UNIQUE,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_email_inbox_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE email_inbox IS 'Secret inbound email addresses of users, saving their emails as memos';
+
+-- =============================================================================
+-- Email Account (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE email_account (
+  user_id INTEGER PRIMARY KEY,
+  host TEXT NOT NULL,
+  port INTEGER NOT NULL DEFAULT 993,
+  username TEXT NOT NULL,
+  password_encrypted TEXT NOT NULL,
+  mailbox TEXT NOT NULL DEFAULT 'INBOX',
+  allow_private BOOLEAN NOT NULL DEFAULT FALSE,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_email_account_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE email_account IS 'IMAP accounts of users read by the mail expert, passwords encrypted';
+
+-- =============================================================================
+-- Feeds (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE feed_subscription (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  url TEXT NOT NULL,
+  title TEXT NOT NULL DEFAULT '',
+  site_url TEXT NOT NULL DEFAULT '',
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  etag TEXT NOT NULL DEFAULT '',
+  last_modified TEXT NOT NULL DEFAULT '',
+  last_fetched_ts BIGINT NOT NULL DEFAULT 0,
+  next_fetch_ts BIGINT NOT NULL DEFAULT 0,
+  failures INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_feed_subscription_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE,
+  CONSTRAINT uq_feed_subscription_url UNIQUE (user_id, url)
+);
+
+CREATE INDEX idx_feed_subscription_due ON feed_subscription(next_fetch_ts) WHERE enabled;
+
+COMMENT ON TABLE feed_subscription IS 'RSS/Atom feeds users subscribe to, fetched on schedule';
+
+CREATE TABLE feed_entry (
+  id SERIAL PRIMARY KEY,
+  subscription_id INTEGER NOT NULL,
+  user_id INTEGER NOT NULL,
+  guid TEXT NOT NULL,
+  title TEXT NOT NULL DEFAULT '',
+  url TEXT NOT NULL DEFAULT '',
+  summary TEXT NOT NULL DEFAULT '',
+  published_ts BIGINT NOT NULL DEFAULT 0,
+  status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'INGESTED')),
+  attempts INTEGER NOT NULL DEFAULT 0,
+  ai_summary TEXT NOT NULL DEFAULT '',
+  tags TEXT[] NOT NULL DEFAULT '{}',
+  memo_uid TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_feed_entry_subscription FOREIGN KEY (subscription_id) REFERENCES feed_subscription(id) ON DELETE CASCADE,
+  CONSTRAINT uq_feed_entry_guid UNIQUE (subscription_id, guid)
+);
+
+CREATE INDEX idx_feed_entry_pending ON feed_entry(created_ts) WHERE status = 'PENDING';
+
+COMMENT ON TABLE feed_entry IS 'Entries of subscribed feeds, ingested as read-later memos by background jobs';
+
+-- =============================================================================
+-- Calendar Sync (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE calendar_feed (
+  user_id INTEGER PRIMARY KEY,
+  token TEXT NOT NULL UNIQUE,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_calendar_feed_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE calendar_feed IS 'Secret iCalendar feed URLs of the schedules of users';
+
+CREATE TABLE calendar_account (
+  user_id INTEGER PRIMARY KEY,
+  url TEXT NOT NULL,
+  username TEXT NOT NULL,
+  password_encrypted TEXT NOT NULL,
+  timezone TEXT NOT NULL DEFAULT 'Asia/Shanghai',
+  allow_private BOOLEAN NOT NULL DEFAULT FALSE,
+  last_sync_ts BIGINT NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_calendar_account_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE calendar_account IS 'CalDAV calendars synced with the schedules of users, passwords encrypted';
+
+-- No foreign key to schedule: the link of a deleted schedule deletes its event
+CREATE TABLE calendar_event (
+  user_id INTEGER NOT NULL,
+  uid TEXT NOT NULL,
+  schedule_id INTEGER NOT NULL,
+  href TEXT NOT NULL,
+  etag TEXT NOT NULL DEFAULT '',
+  schedule_updated_ts BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (user_id, uid),
+  CONSTRAINT fk_calendar_event_account FOREIGN KEY (user_id) REFERENCES calendar_account(user_id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE calendar_event IS 'Schedules synced with events of CalDAV calendars';
+
+-- =============================================================================
+-- Schedule Reminders (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE reminder_setting (
+  user_id INTEGER PRIMARY KEY,
+  enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  channels TEXT[] NOT NULL DEFAULT '{}',
+  lead_minutes INTEGER[] NOT NULL DEFAULT '{}',
+  escalation_channels TEXT[] NOT NULL DEFAULT '{web_push,telegram,email}',
+  escalation_minutes INTEGER NOT NULL DEFAULT 5,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_reminder_setting_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE reminder_setting IS 'Reminder channels and default lead times of users';
+
+CREATE TABLE reminder_push_subscription (
+  endpoint TEXT PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  p256dh TEXT NOT NULL,
+  auth TEXT NOT NULL,
+  user_agent TEXT NOT NULL DEFAULT '',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_reminder_push_subscription_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_reminder_push_subscription_user ON reminder_push_subscription(user_id);
+
+COMMENT ON TABLE reminder_push_subscription IS 'Browsers subscribed to the web push reminders of users';
+
+-- No foreign key to schedule: deliveries outlive deleted schedules until pruned
+CREATE TABLE reminder_delivery (
+  id BIGSERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  schedule_uid TEXT NOT NULL,
+  start_ts BIGINT NOT NULL,
+  lead_minutes INTEGER NOT NULL,
+  channel TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT '',
+  notification JSONB NOT NULL DEFAULT '{}',
+  escalation_id BIGINT NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  sent_ts BIGINT NOT NULL DEFAULT 0,
+  CONSTRAINT fk_reminder_delivery_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE,
+  CONSTRAINT uq_reminder_delivery UNIQUE (user_id, schedule_uid, start_ts, lead_minutes, channel),
+  CONSTRAINT chk_reminder_delivery_status CHECK (status IN ('pending', 'sent', 'failed', 'skipped'))
+);
+
+CREATE INDEX idx_reminder_delivery_user ON reminder_delivery(user_id, start_ts DESC);
+CREATE INDEX idx_reminder_delivery_failed ON reminder_delivery(updated_ts) WHERE status = 'failed';
+
+COMMENT ON TABLE reminder_delivery IS 'Schedule reminders sent to users, one per channel';
+
+-- =============================================================================
+-- Reminder Escalations (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE reminder_critical (
+  user_id INTEGER NOT NULL,
+  schedule_uid TEXT NOT NULL,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  PRIMARY KEY (user_id, schedule_uid),
+  CONSTRAINT fk_reminder_critical_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE reminder_critical IS 'Schedules whose reminders escalate until acknowledged';
+
+CREATE TABLE reminder_escalation (
+  id BIGSERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  schedule_uid TEXT NOT NULL,
+  start_ts BIGINT NOT NULL,
+  lead_minutes INTEGER NOT NULL,
+  channels TEXT[] NOT NULL DEFAULT '{}',
+  delay_minutes INTEGER NOT NULL,
+  step INTEGER NOT NULL DEFAULT 0,
+  status TEXT NOT NULL DEFAULT 'active',
+  next_ts BIGINT NOT NULL DEFAULT 0,
+  acknowledged_ts BIGINT NOT NULL DEFAULT 0,
+  notification JSONB NOT NULL DEFAULT '{}',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_reminder_escalation_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE,
+  CONSTRAINT uq_reminder_escalation UNIQUE (user_id, schedule_uid, start_ts, lead_minutes),
+  CONSTRAINT chk_reminder_escalation_status CHECK (status IN ('active', 'acknowledged', 'exhausted'))
+);
+
+CREATE INDEX idx_reminder_escalation_user ON reminder_escalation(user_id, created_ts DESC);
+CREATE INDEX idx_reminder_escalation_active ON reminder_escalation(next_ts) WHERE status = 'active';
+
+COMMENT ON TABLE reminder_escalation IS 'Critical reminders escalating through a chain of channels';
+
+-- =============================================================================
+-- Status Incidents (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE status_incident (
+  id SERIAL PRIMARY KEY,
+  title TEXT NOT NULL,
+  message TEXT NOT NULL DEFAULT '',
+  impact TEXT NOT NULL DEFAULT 'major',
+  stage TEXT NOT NULL DEFAULT 'investigating',
+  components TEXT[] NOT NULL DEFAULT '{}',
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  resolved_ts BIGINT NOT NULL DEFAULT 0,
+  CONSTRAINT chk_status_incident_impact CHECK (impact IN ('minor', 'major', 'critical')),
+  CONSTRAINT chk_status_incident_stage CHECK (stage IN ('investigating', 'identified', 'monitoring', 'resolved'))
+);
+
+CREATE INDEX idx_status_incident_stage ON status_incident(stage, created_ts DESC);
+
+COMMENT ON TABLE status_incident IS 'Incidents posted by admins on the public status page';
+
+-- =============================================================================
+-- Feature Flags (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE feature_flag (
+  key TEXT PRIMARY KEY,
+  enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  percent INTEGER NOT NULL DEFAULT 0,
+  users INTEGER[] NOT NULL DEFAULT '{}',
+  roles TEXT[] NOT NULL DEFAULT '{}',
+  updated_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT chk_feature_flag_percent CHECK (percent BETWEEN 0 AND 100)
+);
+
+COMMENT ON TABLE feature_flag IS 'Admin rules overriding the defaults of the feature flags';
+
+-- =============================================================================
+-- Changelog (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE changelog_seen (
+  user_id INTEGER PRIMARY KEY,
+  version TEXT NOT NULL,
+  seen_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT fk_changelog_seen_user FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE changelog_seen IS 'Latest release whose notes each user has seen';
+
+-- =============================================================================
+-- Geek / Evolution Mode action audit (V1.1.0)
+-- =============================================================================
+
+CREATE TABLE action_audit (
+  id BIGSERIAL PRIMARY KEY,
+  -- No foreign key: records outlive the users they audit
+  user_id INTEGER NOT NULL,
+  mode TEXT NOT NULL,
+  session_id TEXT NOT NULL DEFAULT '',
+  tool TEXT NOT NULL,
+  tool_id TEXT NOT NULL DEFAULT '',
+  input TEXT NOT NULL DEFAULT '',
+  file_paths TEXT[] NOT NULL DEFAULT '{}',
+  status TEXT NOT NULL,
+  output TEXT NOT NULL DEFAULT '',
+  duration_ms BIGINT NOT NULL DEFAULT 0,
+  created_ts BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+  CONSTRAINT chk_action_audit_mode CHECK (mode IN ('geek', 'evolution')),
+  CONSTRAINT chk_action_audit_status CHECK (status IN ('success', 'error', 'blocked', 'unfinished'))
+);
+
+CREATE INDEX idx_action_audit_created ON action_audit(created_ts DESC);
+CREATE INDEX idx_action_audit_user ON action_audit(user_id, created_ts DESC);
+CREATE INDEX idx_action_audit_session ON action_audit(session_id);
+
+-- Records are never updated nor deleted
+CREATE OR REPLACE FUNCTION reject_action_audit_change()
+RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'action_audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_action_audit_append_only
+  BEFORE UPDATE OR DELETE ON action_audit
+  FOR EACH ROW
+  EXECUTE FUNCTION reject_action_audit_change();
+
+COMMENT ON TABLE action_audit IS 'Append-only audit log of the tool calls of Geek and Evolution Mode sessions';
+
 -- =============================================================================
 -- 版本记录
 -- =============================================================================
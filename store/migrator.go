@@ -165,11 +165,10 @@ func (s *Store) Migrate(ctx context.Context) error {
 // applyMigrations applies all necessary migration files between current and target schema versions.
 // It runs all migrations in a single transaction for atomicity.
 func (s *Store) applyMigrations(ctx context.Context, currentSchemaVersion, targetSchemaVersion string) error {
-	filePaths, err := fs.Glob(migrationFS, fmt.Sprintf("%s*/*.sql", s.getMigrationBasePath()))
+	filePaths, err := s.pendingMigrations(currentSchemaVersion, targetSchemaVersion)
 	if err != nil {
-		return errors.Wrap(err, "failed to read migration files")
+		return err
 	}
-	sort.Strings(filePaths)
 
 	// Start a transaction to apply migrations atomically
 	tx, err := s.driver.GetDB().Begin()
@@ -193,33 +192,24 @@ func (s *Store) applyMigrations(ctx context.Context, currentSchemaVersion, targe
 
 	migrationsApplied := 0
 	for _, filePath := range filePaths {
-		fileSchemaVersion, err := s.getSchemaVersionOfMigrateScript(filePath)
-		if err != nil {
-			return errors.Wrap(err, "failed to get schema version of migrate script")
+		// Validate migration filename before applying
+		filename := filepath.Base(filePath)
+		if err := validateMigrationFileName(filename); err != nil {
+			slog.Warn("migration file has invalid name but will be applied", slog.String("file", filePath), slog.String("error", err.Error()))
 		}
 
-		if shouldApplyMigration(fileSchemaVersion, currentSchemaVersion, targetSchemaVersion) {
-			// Validate migration filename before applying
-			filename := filepath.Base(filePath)
-			if err := validateMigrationFileName(filename); err != nil {
-				slog.Warn("migration file has invalid name but will be applied", slog.String("file", filePath), slog.String("error", err.Error()))
-			}
-
-			slog.Info("applying migration",
-				slog.String("file", filePath),
-				slog.String("version", fileSchemaVersion))
+		slog.Info("applying migration", slog.String("file", filePath))
 
-			bytes, err := migrationFS.ReadFile(filePath)
-			if err != nil {
-				return errors.Wrapf(err, "failed to read migration file: %s", filePath)
-			}
+		bytes, err := migrationFS.ReadFile(filePath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read migration file: %s", filePath)
+		}
 
-			stmt := string(bytes)
-			if err := s.execute(ctx, tx, stmt); err != nil {
-				return errors.Wrapf(err, "failed to execute migration %s: %s", filePath, err)
-			}
-			migrationsApplied++
+		stmt := string(bytes)
+		if err := s.execute(ctx, tx, stmt); err != nil {
+			return errors.Wrapf(err, "failed to execute migration %s: %s", filePath, err)
 		}
+		migrationsApplied++
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -236,6 +226,28 @@ func (s *Store) applyMigrations(ctx context.Context, currentSchemaVersion, targe
 	return nil
 }
 
+// pendingMigrations returns the migration files that upgrade the schema from
+// currentSchemaVersion to targetSchemaVersion, in the order they are applied.
+func (s *Store) pendingMigrations(currentSchemaVersion, targetSchemaVersion string) ([]string, error) {
+	filePaths, err := fs.Glob(migrationFS, fmt.Sprintf("%s*.up.sql", s.getMigrationBasePath()))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read migration files")
+	}
+	sort.Strings(filePaths)
+
+	var pending []string
+	for _, filePath := range filePaths {
+		fileSchemaVersion, err := s.getSchemaVersionOfMigrateScript(filePath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get schema version of migrate script")
+		}
+		if shouldApplyMigration(fileSchemaVersion, currentSchemaVersion, targetSchemaVersion) {
+			pending = append(pending, filePath)
+		}
+	}
+	return pending, nil
+}
+
 // preMigrate checks if the database is initialized and applies the latest schema if not.
 func (s *Store) preMigrate(ctx context.Context) error {
 	initialized, err := s.driver.IsInitialized(ctx)
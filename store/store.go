@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/hrygo/divinesense/internal/profile"
@@ -24,6 +25,16 @@ type Store struct {
 	// Store services
 	AgentStatsStore    AgentStatsStore    // session statistics persistence
 	SecurityAuditStore SecurityAuditStore // security audit logging
+
+	// Locked conversations (nil until SetBlockSealer)
+	blockSealer            BlockSealer
+	blockConversationCache *cache.Cache // block ID -> conversation ID
+
+	// Model of the active memo embeddings (see EmbeddingModel)
+	embeddingModel atomic.Value
+
+	// Notified of memo content changes (see WatchMemos)
+	memoWatchers []MemoWatcher
 }
 
 // New creates a new instance of Store.
@@ -59,6 +70,9 @@ func (s *Store) Close() error {
 	s.instanceSettingCache.Close()
 	s.userCache.Close()
 	s.userSettingCache.Close()
+	if s.blockConversationCache != nil {
+		s.blockConversationCache.Close()
+	}
 
 	return s.driver.Close()
 }
@@ -79,22 +93,71 @@ func (s *Store) DeleteAIConversation(ctx context.Context, delete *DeleteAIConver
 	return s.driver.DeleteAIConversation(ctx, delete)
 }
 
+// ImportAIConversation creates a conversation with its blocks at once. The
+// conversation is new, so not locked: the contents are not sealed.
+func (s *Store) ImportAIConversation(ctx context.Context, imported *ImportAIConversation) (*AIConversation, error) {
+	for _, block := range imported.Blocks {
+		if err := ValidateBlockMetadata(block.Metadata); err != nil {
+			return nil, err
+		}
+	}
+	return s.driver.ImportAIConversation(ctx, imported)
+}
+
 // AIBlock methods (Unified Block Model).
 // AIMessage functions removed: ALL IN Block!
+//
+// The contents of the blocks of locked conversations are sealed on write and
+// opened on read, see BlockSealer. The metadata sections are validated on
+// write, see ValidateBlockMetadata.
 func (s *Store) CreateAIBlock(ctx context.Context, create *CreateAIBlock) (*AIBlock, error) {
-	return s.driver.CreateAIBlock(ctx, create)
+	if err := ValidateBlockMetadata(create.Metadata); err != nil {
+		return nil, err
+	}
+	create, err := s.sealCreate(create)
+	if err != nil {
+		return nil, err
+	}
+	block, err := s.driver.CreateAIBlock(ctx, create)
+	if err != nil {
+		return nil, err
+	}
+	s.openBlocks(ctx, block)
+	return block, nil
 }
 
 func (s *Store) GetAIBlock(ctx context.Context, id int64) (*AIBlock, error) {
-	return s.driver.GetAIBlock(ctx, id)
+	block, err := s.driver.GetAIBlock(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.openBlocks(ctx, block)
+	return block, nil
 }
 
 func (s *Store) ListAIBlocks(ctx context.Context, find *FindAIBlock) ([]*AIBlock, error) {
-	return s.driver.ListAIBlocks(ctx, find)
+	blocks, err := s.driver.ListAIBlocks(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	s.openBlocks(ctx, blocks...)
+	return blocks, nil
 }
 
 func (s *Store) UpdateAIBlock(ctx context.Context, update *UpdateAIBlock) (*AIBlock, error) {
-	return s.driver.UpdateAIBlock(ctx, update)
+	if err := ValidateBlockMetadata(update.Metadata); err != nil {
+		return nil, err
+	}
+	update, err := s.sealUpdate(ctx, update)
+	if err != nil {
+		return nil, err
+	}
+	block, err := s.driver.UpdateAIBlock(ctx, update)
+	if err != nil {
+		return nil, err
+	}
+	s.openBlocks(ctx, block)
+	return block, nil
 }
 
 func (s *Store) DeleteAIBlock(ctx context.Context, id int64) error {
@@ -102,14 +165,43 @@ func (s *Store) DeleteAIBlock(ctx context.Context, id int64) error {
 }
 
 func (s *Store) AppendUserInput(ctx context.Context, blockID int64, input UserInput) error {
+	conversationID, locked, err := s.sealingConversation(ctx, blockID)
+	if err != nil {
+		return err
+	}
+	if locked {
+		if input.Content, err = s.blockSealer.Seal(conversationID, input.Content); err != nil {
+			return err
+		}
+	}
 	return s.driver.AppendUserInput(ctx, blockID, input)
 }
 
 func (s *Store) AppendEvent(ctx context.Context, blockID int64, event BlockEvent) error {
+	conversationID, locked, err := s.sealingConversation(ctx, blockID)
+	if err != nil {
+		return err
+	}
+	if locked {
+		sealed, err := sealEvents(s.blockSealer, conversationID, []BlockEvent{event})
+		if err != nil {
+			return err
+		}
+		event = sealed[0]
+	}
 	return s.driver.AppendEvent(ctx, blockID, event)
 }
 
 func (s *Store) AppendEventsBatch(ctx context.Context, blockID int64, events []BlockEvent) error {
+	conversationID, locked, err := s.sealingConversation(ctx, blockID)
+	if err != nil {
+		return err
+	}
+	if locked {
+		if events, err = sealEvents(s.blockSealer, conversationID, events); err != nil {
+			return err
+		}
+	}
 	return s.driver.AppendEventsBatch(ctx, blockID, events)
 }
 
@@ -118,18 +210,55 @@ func (s *Store) UpdateAIBlockStatus(ctx context.Context, blockID int64, status A
 }
 
 func (s *Store) GetLatestAIBlock(ctx context.Context, conversationID int32) (*AIBlock, error) {
-	return s.driver.GetLatestAIBlock(ctx, conversationID)
+	block, err := s.driver.GetLatestAIBlock(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	s.openBlocks(ctx, block)
+	return block, nil
+}
+
+// ListAIBlockUIDs returns the UIDs of all blocks of a conversation, in the
+// order of ListAIBlocks, without loading the blocks.
+func (s *Store) ListAIBlockUIDs(ctx context.Context, conversationID int32) ([]string, error) {
+	return s.driver.ListAIBlockUIDs(ctx, conversationID)
 }
 
 func (s *Store) GetPendingAIBlocks(ctx context.Context) ([]*AIBlock, error) {
-	return s.driver.GetPendingAIBlocks(ctx)
+	blocks, err := s.driver.GetPendingAIBlocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.openBlocks(ctx, blocks...)
+	return blocks, nil
 }
 
 func (s *Store) CreateAIBlockWithRound(ctx context.Context, create *CreateAIBlock) (*AIBlock, error) {
-	return s.driver.CreateAIBlockWithRound(ctx, create)
+	if err := ValidateBlockMetadata(create.Metadata); err != nil {
+		return nil, err
+	}
+	create, err := s.sealCreate(create)
+	if err != nil {
+		return nil, err
+	}
+	block, err := s.driver.CreateAIBlockWithRound(ctx, create)
+	if err != nil {
+		return nil, err
+	}
+	s.openBlocks(ctx, block)
+	return block, nil
 }
 
 func (s *Store) CompleteBlock(ctx context.Context, blockID int64, assistantContent string, sessionStats *SessionStats) error {
+	conversationID, locked, err := s.sealingConversation(ctx, blockID)
+	if err != nil {
+		return err
+	}
+	if locked && assistantContent != "" {
+		if assistantContent, err = s.blockSealer.Seal(conversationID, assistantContent); err != nil {
+			return err
+		}
+	}
 	return s.driver.CompleteBlock(ctx, blockID, assistantContent, sessionStats)
 }
 
@@ -184,15 +313,44 @@ func (s *Store) DeleteToolMetrics(ctx context.Context, delete *DeleteToolMetrics
 // ========== Tree Branching Methods (tree-conversation-branching) ==========
 
 func (s *Store) ForkBlock(ctx context.Context, parentID int64, reason string, replaceUserInputs []UserInput) (*AIBlock, error) {
-	return s.driver.ForkBlock(ctx, parentID, reason, replaceUserInputs)
+	conversationID, locked, err := s.sealingConversation(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if locked {
+		// Inherited user inputs are copied sealed
+		if replaceUserInputs != nil {
+			if replaceUserInputs, err = sealUserInputs(s.blockSealer, conversationID, replaceUserInputs); err != nil {
+				return nil, err
+			}
+		} else if !s.blockSealer.Unlocked(conversationID) {
+			return nil, ErrConversationLocked
+		}
+	}
+	block, err := s.driver.ForkBlock(ctx, parentID, reason, replaceUserInputs)
+	if err != nil {
+		return nil, err
+	}
+	s.openBlocks(ctx, block)
+	return block, nil
 }
 
 func (s *Store) ListChildBlocks(ctx context.Context, parentID int64) ([]*AIBlock, error) {
-	return s.driver.ListChildBlocks(ctx, parentID)
+	blocks, err := s.driver.ListChildBlocks(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	s.openBlocks(ctx, blocks...)
+	return blocks, nil
 }
 
 func (s *Store) GetActivePath(ctx context.Context, conversationID int32) ([]*AIBlock, error) {
-	return s.driver.GetActivePath(ctx, conversationID)
+	blocks, err := s.driver.GetActivePath(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	s.openBlocks(ctx, blocks...)
+	return blocks, nil
 }
 
 func (s *Store) DeleteBranch(ctx context.Context, blockID int64, cascade bool) error {
@@ -0,0 +1,388 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hrygo/divinesense/internal/version"
+)
+
+// Upgrade Advisor:
+//
+// PlanUpgrade previews what Migrate does on the next startup without changing
+// the database: the pending migrations, the table locks their statements take
+// with the size of the locked tables, and the steps an operator has to take by
+// hand. Manual steps come from the statements (extensions to install,
+// statements that cannot run in the migration transaction) and from
+// "-- manual: <step>" lines of the migration files.
+
+const (
+	// LargeTableRows and LargeTableBytes are the estimated rows and the size
+	// from which a table is large: locks scanning it block writes for long.
+	LargeTableRows  = 1_000_000
+	LargeTableBytes = 1 << 30
+)
+
+// Lock impacts of the statements of a migration.
+const (
+	// LockImpactBrief locks are held for a catalog update, independent of
+	// the size of the table.
+	LockImpactBrief = "brief"
+	// LockImpactScan locks are held while the table is scanned or rewritten.
+	LockImpactScan = "scan"
+)
+
+// UpgradePlan is the preview of the schema upgrade of the next startup.
+type UpgradePlan struct {
+	Driver string
+	Mode   string
+	// FreshInstall is set for empty databases, initialized with LATEST.sql.
+	FreshInstall bool
+	// DatabaseVersion is the schema version of the database, empty for fresh
+	// installs; TargetVersion the one of this build.
+	DatabaseVersion string
+	TargetVersion   string
+	Migrations      []*PendingMigration
+	// ManualSteps are the steps to take by hand before the upgrade.
+	ManualSteps []string
+	// Blockers fail the migration on startup.
+	Blockers []string
+}
+
+// PendingMigration is a migration file the startup applies.
+type PendingMigration struct {
+	File        string
+	Version     string
+	Statements  int
+	Locks       []*TableLock
+	ManualSteps []string
+	// Blockers are the statements the migration transaction cannot run.
+	Blockers []string
+}
+
+// TableLock is a table lock taken by a statement of a migration.
+type TableLock struct {
+	Table     string
+	Operation string
+	// Mode is the PostgreSQL lock mode, e.g. ACCESS EXCLUSIVE.
+	Mode   string
+	Impact string
+	// Rows and SizeBytes estimate the size of the table; Rows is -1 when
+	// the table has never been analyzed, and both are 0 for new tables.
+	Rows      int64
+	SizeBytes int64
+}
+
+// Large reports whether the lock scans a large table.
+func (l *TableLock) Large() bool {
+	return l.Impact == LockImpactScan && (l.Rows >= LargeTableRows || l.SizeBytes >= LargeTableBytes)
+}
+
+// Blocked reports whether the upgrade fails on startup.
+func (p *UpgradePlan) Blocked() bool {
+	return len(p.Blockers) > 0
+}
+
+// LargeLocks returns the locks scanning large tables.
+func (p *UpgradePlan) LargeLocks() []*TableLock {
+	var locks []*TableLock
+	for _, migration := range p.Migrations {
+		for _, lock := range migration.Locks {
+			if lock.Large() {
+				locks = append(locks, lock)
+			}
+		}
+	}
+	return locks
+}
+
+// PlanUpgrade previews the schema upgrade Migrate applies on startup.
+func (s *Store) PlanUpgrade(ctx context.Context) (*UpgradePlan, error) {
+	targetVersion, err := s.GetCurrentSchemaVersion()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get current schema version")
+	}
+	plan := &UpgradePlan{Driver: s.profile.Driver, Mode: s.profile.Mode, TargetVersion: targetVersion}
+
+	initialized, err := s.driver.IsInitialized(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check if database is initialized")
+	}
+	if !initialized {
+		plan.FreshInstall = true
+		bytes, err := migrationFS.ReadFile(s.getSchemaBasePath() + LatestSchemaFileName)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read latest schema file")
+		}
+		// Tables are created empty: only the manual steps matter
+		migration := s.analyzeMigration(LatestSchemaFileName, string(bytes))
+		plan.ManualSteps, plan.Blockers = migration.ManualSteps, migration.Blockers
+		return plan, s.checkExtensions(ctx, plan, []*PendingMigration{migration})
+	}
+
+	instanceBasicSetting, err := s.GetInstanceBasicSetting(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get instance basic setting")
+	}
+	plan.DatabaseVersion = instanceBasicSetting.SchemaVersion
+	if plan.Mode != modeProd {
+		// Only prod mode migrates existing databases
+		return plan, nil
+	}
+	if err := s.checkMinimumUpgradeVersion(ctx); err != nil {
+		plan.Blockers = append(plan.Blockers, err.Error())
+		return plan, nil
+	}
+	if !isVersionEmpty(plan.DatabaseVersion) && version.IsVersionGreaterThan(plan.DatabaseVersion, targetVersion) {
+		plan.Blockers = append(plan.Blockers, fmt.Sprintf("cannot downgrade schema version from %s to %s", plan.DatabaseVersion, targetVersion))
+		return plan, nil
+	}
+	if !isVersionEmpty(plan.DatabaseVersion) && !version.IsVersionGreaterThan(targetVersion, plan.DatabaseVersion) {
+		return plan, nil
+	}
+
+	filePaths, err := s.pendingMigrations(plan.DatabaseVersion, targetVersion)
+	if err != nil {
+		return nil, err
+	}
+	for _, filePath := range filePaths {
+		bytes, err := migrationFS.ReadFile(filePath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read migration file: %s", filePath)
+		}
+		migration := s.analyzeMigration(filepath.Base(filePath), string(bytes))
+		migration.Version, err = s.getSchemaVersionOfMigrateScript(filePath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get schema version of migrate script")
+		}
+		plan.Migrations = append(plan.Migrations, migration)
+		plan.ManualSteps = append(plan.ManualSteps, migration.ManualSteps...)
+		plan.Blockers = append(plan.Blockers, migration.Blockers...)
+	}
+	if err := s.checkExtensions(ctx, plan, plan.Migrations); err != nil {
+		return nil, err
+	}
+	return plan, s.sizeLockedTables(ctx, plan)
+}
+
+var (
+	manualStepRegexp  = regexp.MustCompile(`(?im)^\s*--\s*manual:\s*(.+?)\s*$`)
+	createIndexRegexp = regexp.MustCompile(`(?i)^CREATE\s+(?:UNIQUE\s+)?INDEX\s+(CONCURRENTLY\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(?:\S+\s+)?ON\s+(?:ONLY\s+)?([\w."]+)`)
+	alterTableRegexp  = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?([\w."]+)\s+(.*)$`)
+	tableStmtRegexp   = regexp.MustCompile(`(?i)^(UPDATE|DELETE\s+FROM|TRUNCATE(?:\s+TABLE)?|DROP\s+TABLE(?:\s+IF\s+EXISTS)?|REINDEX\s+TABLE|CLUSTER|VACUUM(?:\s+FULL)?(?:\s+ANALYZE)?)\s+(?:ONLY\s+)?([\w."]+)`)
+	triggerRegexp     = regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?TRIGGER\s+.*?\s+ON\s+([\w."]+)`)
+	extensionRegexp   = regexp.MustCompile(`(?i)^CREATE\s+EXTENSION\s+(?:IF\s+NOT\s+EXISTS\s+)?([\w"]+)`)
+	// Defaults evaluated per row, which make ADD COLUMN rewrite the table
+	volatileDefault = regexp.MustCompile(`(?i)\b(RANDOM|CLOCK_TIMESTAMP|TIMEOFDAY|GEN_RANDOM_UUID|UUID_GENERATE_V\d|NEXTVAL)\s*\(|\b(BIG|SMALL)?SERIAL\b|\bGENERATED\s+ALWAYS\s+AS\s*\(.*\)\s*STORED`)
+	alterTypeRegexp = regexp.MustCompile(`ALTER\s+(COLUMN\s+)?\S+\s+(SET\s+DATA\s+)?TYPE\b`)
+	addUniqueRegexp = regexp.MustCompile(`ADD\s+(CONSTRAINT\s+\S+\s+)?UNIQUE\b`)
+)
+
+// statementImpact is the impact of a statement of a migration.
+type statementImpact struct {
+	lock *TableLock
+	// step is the manual step the statement requires.
+	step string
+	// blocker is set for statements the migration transaction cannot run.
+	blocker string
+}
+
+// analyzeMigration lists the table locks and the manual steps of a migration.
+func (s *Store) analyzeMigration(file, sql string) *PendingMigration {
+	migration := &PendingMigration{File: file}
+	for _, match := range manualStepRegexp.FindAllStringSubmatch(sql, -1) {
+		migration.ManualSteps = append(migration.ManualSteps, fmt.Sprintf("%s: %s", file, match[1]))
+	}
+	for _, stmt := range s.splitSQL(sql) {
+		migration.Statements++
+		impact := analyzeStatement(strings.TrimSuffix(stmt, ";"))
+		if impact.lock != nil {
+			migration.Locks = append(migration.Locks, impact.lock)
+		}
+		if impact.step != "" {
+			migration.ManualSteps = append(migration.ManualSteps, fmt.Sprintf("%s: %s", file, impact.step))
+		}
+		if impact.blocker != "" {
+			migration.Blockers = append(migration.Blockers, fmt.Sprintf("%s: %s", file, impact.blocker))
+		}
+	}
+	return migration
+}
+
+// analyzeStatement returns the table lock a statement takes, and the manual
+// step it requires.
+func analyzeStatement(stmt string) statementImpact {
+	stmt = strings.Join(strings.Fields(stmt), " ")
+	upper := strings.ToUpper(stmt)
+
+	if match := createIndexRegexp.FindStringSubmatch(stmt); match != nil {
+		table := tableName(match[2])
+		if match[1] != "" {
+			return statementImpact{blocker: fmt.Sprintf("CREATE INDEX CONCURRENTLY on %s cannot run inside the migration transaction; build the index by hand, then drop CONCURRENTLY from the migration", table)}
+		}
+		return statementImpact{lock: &TableLock{Table: table, Operation: "CREATE INDEX", Mode: "SHARE", Impact: LockImpactScan}}
+	}
+	if match := alterTableRegexp.FindStringSubmatch(stmt); match != nil {
+		return statementImpact{lock: alterTableLock(tableName(match[1]), strings.ToUpper(match[2]))}
+	}
+	if match := tableStmtRegexp.FindStringSubmatch(stmt); match != nil {
+		operation := strings.ToUpper(strings.Join(strings.Fields(match[1]), " "))
+		lock := &TableLock{Table: tableName(match[2]), Operation: operation}
+		switch {
+		case strings.HasPrefix(operation, "VACUUM"):
+			return statementImpact{blocker: operation + " " + lock.Table + " cannot run inside the migration transaction; run it by hand after the upgrade"}
+		case operation == "UPDATE" || operation == "DELETE FROM":
+			lock.Mode, lock.Impact = "ROW EXCLUSIVE", LockImpactScan
+		case operation == "REINDEX TABLE" || operation == "CLUSTER":
+			lock.Mode, lock.Impact = "ACCESS EXCLUSIVE", LockImpactScan
+		default:
+			lock.Mode, lock.Impact = "ACCESS EXCLUSIVE", LockImpactBrief
+		}
+		return statementImpact{lock: lock}
+	}
+	if match := triggerRegexp.FindStringSubmatch(stmt); match != nil {
+		return statementImpact{lock: &TableLock{Table: tableName(match[1]), Operation: "CREATE TRIGGER", Mode: "SHARE ROW EXCLUSIVE", Impact: LockImpactBrief}}
+	}
+	if match := extensionRegexp.FindStringSubmatch(stmt); match != nil {
+		return statementImpact{step: extensionStep(strings.ToLower(strings.Trim(match[1], `"`)))}
+	}
+	if strings.HasPrefix(upper, "ALTER TYPE ") && strings.Contains(upper, " ADD VALUE") {
+		return statementImpact{step: "ALTER TYPE ... ADD VALUE requires PostgreSQL 12 or later inside the migration transaction"}
+	}
+	return statementImpact{}
+}
+
+// alterTableLock returns the lock of the actions of an ALTER TABLE.
+func alterTableLock(table, actions string) *TableLock {
+	lock := &TableLock{Table: table, Mode: "ACCESS EXCLUSIVE", Impact: LockImpactScan}
+	notValid := strings.Contains(actions, "NOT VALID")
+	switch {
+	case alterTypeRegexp.MatchString(actions):
+		lock.Operation = "ALTER COLUMN TYPE"
+	case strings.Contains(actions, "ADD COLUMN") && volatileDefault.MatchString(actions):
+		lock.Operation = "ADD COLUMN with a volatile default"
+	case strings.Contains(actions, "SET NOT NULL"):
+		lock.Operation = "SET NOT NULL"
+	case strings.Contains(actions, "PRIMARY KEY") || addUniqueRegexp.MatchString(actions):
+		lock.Operation = "ADD PRIMARY KEY/UNIQUE"
+	case strings.Contains(actions, "FOREIGN KEY") && !notValid:
+		lock.Operation, lock.Mode = "ADD FOREIGN KEY", "SHARE ROW EXCLUSIVE"
+	case strings.Contains(actions, "CHECK") && !notValid:
+		lock.Operation = "ADD CHECK"
+	default:
+		lock.Operation, lock.Impact = "ALTER TABLE "+firstWords(actions, 2), LockImpactBrief
+	}
+	return lock
+}
+
+// extensionStep is the manual step of an extension the migration creates.
+func extensionStep(name string) string {
+	return fmt.Sprintf("extension %s must be available on the PostgreSQL server, and created by a superuser unless the database user may create it", name)
+}
+
+// checkExtensions drops the manual steps of the extensions the database
+// already has.
+func (s *Store) checkExtensions(ctx context.Context, plan *UpgradePlan, migrations []*PendingMigration) error {
+	if s.profile.Driver != "postgres" || len(plan.ManualSteps) == 0 {
+		return nil
+	}
+	rows, err := s.driver.GetDB().QueryContext(ctx, "SELECT extname FROM pg_extension")
+	if err != nil {
+		return errors.Wrap(err, "failed to list extensions")
+	}
+	defer rows.Close()
+	var installed []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return errors.Wrap(err, "failed to scan extension")
+		}
+		installed = append(installed, extensionStep(name))
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "failed to list extensions")
+	}
+
+	done := func(step string) bool {
+		return slices.ContainsFunc(installed, func(installedStep string) bool { return strings.HasSuffix(step, ": "+installedStep) })
+	}
+	plan.ManualSteps = slices.DeleteFunc(plan.ManualSteps, done)
+	for _, migration := range migrations {
+		migration.ManualSteps = slices.DeleteFunc(migration.ManualSteps, done)
+	}
+	return nil
+}
+
+// sizeLockedTables estimates the size of the tables the migrations lock.
+func (s *Store) sizeLockedTables(ctx context.Context, plan *UpgradePlan) error {
+	var tables []string
+	for _, migration := range plan.Migrations {
+		for _, lock := range migration.Locks {
+			if !slices.Contains(tables, lock.Table) {
+				tables = append(tables, lock.Table)
+			}
+		}
+	}
+	if s.profile.Driver != "postgres" || len(tables) == 0 {
+		return nil
+	}
+
+	placeholders, args := make([]string, len(tables)), make([]any, len(tables))
+	for i, table := range tables {
+		placeholders[i], args[i] = fmt.Sprintf("$%d", i+1), table
+	}
+	rows, err := s.driver.GetDB().QueryContext(ctx, `
+		SELECT c.relname, c.reltuples::BIGINT, pg_total_relation_size(c.oid)
+		FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('r', 'p') AND n.nspname = current_schema() AND c.relname IN (`+strings.Join(placeholders, ", ")+`)`, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed to get table sizes")
+	}
+	defer rows.Close()
+	type size struct{ rows, bytes int64 }
+	sizes := map[string]size{}
+	for rows.Next() {
+		var name string
+		var sz size
+		if err := rows.Scan(&name, &sz.rows, &sz.bytes); err != nil {
+			return errors.Wrap(err, "failed to scan table size")
+		}
+		sizes[name] = sz
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "failed to get table sizes")
+	}
+
+	for _, migration := range plan.Migrations {
+		for _, lock := range migration.Locks {
+			// Tables missing are created by the upgrade
+			sz := sizes[lock.Table]
+			lock.Rows, lock.SizeBytes = sz.rows, sz.bytes
+		}
+	}
+	return nil
+}
+
+// tableName normalizes a table name of a statement: unquoted, without the
+// schema.
+func tableName(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	if unquoted := strings.Trim(name, `"`); unquoted != name {
+		return unquoted
+	}
+	return strings.ToLower(name)
+}
+
+// firstWords returns the first n words of s.
+func firstWords(s string, n int) string {
+	words := strings.Fields(s)
+	return strings.Join(words[:min(n, len(words))], " ")
+}
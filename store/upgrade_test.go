@@ -0,0 +1,102 @@
+package store
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeStatement(t *testing.T) {
+	tests := []struct {
+		stmt      string
+		operation string
+		table     string
+		mode      string
+		impact    string
+		step      bool
+		blocker   bool
+	}{
+		{stmt: "CREATE INDEX idx_memo_creator ON memo(creator_id)", operation: "CREATE INDEX", table: "memo", mode: "SHARE", impact: LockImpactScan},
+		{stmt: "CREATE UNIQUE INDEX IF NOT EXISTS idx ON public.\"user\" (username)", operation: "CREATE INDEX", table: "user", mode: "SHARE", impact: LockImpactScan},
+		{stmt: "CREATE INDEX CONCURRENTLY idx ON memo(content)", blocker: true},
+		{stmt: "ALTER TABLE geek_tool_policy ADD COLUMN role TEXT NOT NULL DEFAULT ''", operation: "ALTER TABLE ADD COLUMN", table: "geek_tool_policy", mode: "ACCESS EXCLUSIVE", impact: LockImpactBrief},
+		{stmt: "ALTER TABLE memo ADD COLUMN uid UUID NOT NULL DEFAULT gen_random_uuid()", operation: "ADD COLUMN with a volatile default", table: "memo", mode: "ACCESS EXCLUSIVE", impact: LockImpactScan},
+		{stmt: "ALTER TABLE memo ALTER COLUMN content TYPE VARCHAR(4096)", operation: "ALTER COLUMN TYPE", table: "memo", mode: "ACCESS EXCLUSIVE", impact: LockImpactScan},
+		{stmt: "ALTER TABLE memo ALTER COLUMN content SET NOT NULL", operation: "SET NOT NULL", table: "memo", mode: "ACCESS EXCLUSIVE", impact: LockImpactScan},
+		{stmt: "ALTER TABLE geek_tool_policy ADD PRIMARY KEY (user_id, role)", operation: "ADD PRIMARY KEY/UNIQUE", table: "geek_tool_policy", mode: "ACCESS EXCLUSIVE", impact: LockImpactScan},
+		{stmt: "ALTER TABLE memo ADD CONSTRAINT chk_memo CHECK (length(content) > 0)", operation: "ADD CHECK", table: "memo", mode: "ACCESS EXCLUSIVE", impact: LockImpactScan},
+		{stmt: "ALTER TABLE memo ADD CONSTRAINT chk_memo CHECK (length(content) > 0) NOT VALID", operation: "ALTER TABLE ADD CONSTRAINT", table: "memo", mode: "ACCESS EXCLUSIVE", impact: LockImpactBrief},
+		{stmt: "ALTER TABLE memo ADD CONSTRAINT fk_memo FOREIGN KEY (creator_id) REFERENCES \"user\"(id)", operation: "ADD FOREIGN KEY", table: "memo", mode: "SHARE ROW EXCLUSIVE", impact: LockImpactScan},
+		{stmt: "UPDATE memo SET visibility = 'PRIVATE' WHERE visibility = ''", operation: "UPDATE", table: "memo", mode: "ROW EXCLUSIVE", impact: LockImpactScan},
+		{stmt: "DROP TABLE IF EXISTS legacy", operation: "DROP TABLE IF EXISTS", table: "legacy", mode: "ACCESS EXCLUSIVE", impact: LockImpactBrief},
+		{stmt: "VACUUM FULL memo", blocker: true},
+		{stmt: "CREATE TRIGGER trigger_x BEFORE UPDATE OR DELETE ON action_audit FOR EACH ROW EXECUTE FUNCTION f()", operation: "CREATE TRIGGER", table: "action_audit", mode: "SHARE ROW EXCLUSIVE", impact: LockImpactBrief},
+		{stmt: "CREATE EXTENSION IF NOT EXISTS pg_trgm", step: true},
+		{stmt: "CREATE TABLE feature (id SERIAL PRIMARY KEY)"},
+		{stmt: "COMMENT ON TABLE memo IS 'Memos'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.stmt, func(t *testing.T) {
+			impact := analyzeStatement(tt.stmt)
+			assert.Equal(t, tt.step, impact.step != "", "manual step: %q", impact.step)
+			assert.Equal(t, tt.blocker, impact.blocker != "", "blocker: %q", impact.blocker)
+			if tt.operation == "" {
+				assert.Nil(t, impact.lock)
+				return
+			}
+			require.NotNil(t, impact.lock)
+			assert.Equal(t, &TableLock{Table: tt.table, Operation: tt.operation, Mode: tt.mode, Impact: tt.impact}, impact.lock)
+		})
+	}
+}
+
+func TestAnalyzeMigration(t *testing.T) {
+	s := &Store{}
+	migration := s.analyzeMigration("20270101000000_backfill.up.sql", `-- Backfill the memo uid
+-- manual: back up the memo table first
+
+UPDATE memo SET uid = id::TEXT WHERE uid = '';
+CREATE INDEX CONCURRENTLY idx_memo_uid ON memo(uid);
+`)
+	assert.Equal(t, 2, migration.Statements)
+	require.Len(t, migration.Locks, 1)
+	assert.Equal(t, "UPDATE", migration.Locks[0].Operation)
+	assert.Equal(t, []string{"20270101000000_backfill.up.sql: back up the memo table first"}, migration.ManualSteps)
+	require.Len(t, migration.Blockers, 1)
+	assert.Contains(t, migration.Blockers[0], "CONCURRENTLY")
+
+	migration.Locks[0].Rows = LargeTableRows
+	plan := &UpgradePlan{Migrations: []*PendingMigration{migration}}
+	assert.Len(t, plan.LargeLocks(), 1)
+}
+
+// Migrations run in one transaction: none may need to run outside of it.
+func TestMigrationsRunInTransaction(t *testing.T) {
+	s := &Store{}
+	filePaths, err := fs.Glob(migrationFS, "migration/postgres/migrate/*.up.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, filePaths)
+	for _, filePath := range filePaths {
+		bytes, err := migrationFS.ReadFile(filePath)
+		require.NoError(t, err)
+		migration := s.analyzeMigration(filepath.Base(filePath), string(bytes))
+		assert.Empty(t, migration.Blockers)
+	}
+}
+
+// Migrations apply when newer than the database: a future version would make
+// the migrations created before it skipped.
+func TestMigrationVersionsNotInFuture(t *testing.T) {
+	filePaths, err := fs.Glob(migrationFS, "migration/postgres/migrate/*.up.sql")
+	require.NoError(t, err)
+	now := time.Now().UTC().Format("20060102150405")
+	for _, filePath := range filePaths {
+		timestamp, _, _ := strings.Cut(filepath.Base(filePath), "_")
+		assert.LessOrEqual(t, timestamp, now, filePath)
+	}
+}
@@ -536,6 +536,9 @@ export function fromProtoBlockStatus(status: BlockStatus): "pending" | "streamin
       return "completed";
     case BlockStatus.ERROR:
       return "error";
+    case BlockStatus.INTERRUPTED:
+      // Stopped by the user: the partial answer is final
+      return "completed";
     default:
       return "pending";
   }
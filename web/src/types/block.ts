@@ -66,6 +66,7 @@ export const BLOCK_STATUS = {
   STREAMING: "BLOCK_STATUS_STREAMING",
   COMPLETED: "BLOCK_STATUS_COMPLETED",
   ERROR: "BLOCK_STATUS_ERROR",
+  INTERRUPTED: "BLOCK_STATUS_INTERRUPTED",
 } as const;
 
 /**
@@ -77,14 +78,19 @@ export const EVENT_TYPE = {
   TOOL_RESULT: "tool_result",
   ANSWER: "answer",
   ERROR: "error",
+  STOPPED: "stopped",
 } as const;
 
 /**
- * Type guard for checking if a status is terminal (completed or error)
+ * Type guard for checking if a status is terminal (completed, error or interrupted)
  */
 export function isTerminalStatus(status: BlockStatusEnum | string): boolean {
   const statusStr = typeof status === "string" ? status : String(status);
-  return statusStr === String(BLOCK_STATUS.COMPLETED) || statusStr === String(BLOCK_STATUS.ERROR);
+  return (
+    statusStr === String(BLOCK_STATUS.COMPLETED) ||
+    statusStr === String(BLOCK_STATUS.ERROR) ||
+    statusStr === String(BLOCK_STATUS.INTERRUPTED)
+  );
 }
 
 /**
@@ -169,6 +175,8 @@ export function getBlockStatusName(status: BlockStatusEnum | string): string {
       return "completed";
     case String(BLOCK_STATUS.ERROR):
       return "error";
+    case String(BLOCK_STATUS.INTERRUPTED):
+      return "interrupted";
     default:
       return "unspecified";
   }
@@ -427,7 +427,8 @@ export type DangerCategory =
   | "network" // Network/download operations
   | "database" // Database operations
   | "git" // Git operations
-  | "permission"; // Permission changes
+  | "permission" // Permission changes
+  | "quota"; // Workspace disk quota
 
 /**
  * Danger level severity
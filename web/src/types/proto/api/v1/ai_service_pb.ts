@@ -7,14 +7,14 @@ import { enumDesc, fileDesc, messageDesc, serviceDesc } from "@bufbuild/protobuf
 import { file_google_api_annotations } from "../../google/api/annotations_pb";
 import { file_google_api_field_behavior } from "../../google/api/field_behavior_pb";
 import type { EmptySchema } from "@bufbuild/protobuf/wkt";
-import { file_google_protobuf_empty } from "@bufbuild/protobuf/wkt";
-import type { Message } from "@bufbuild/protobuf";
+import { file_google_protobuf_empty, file_google_protobuf_struct } from "@bufbuild/protobuf/wkt";
+import type { JsonObject, Message } from "@bufbuild/protobuf";
 
 /**
  * Describes the file api/v1/ai_service.proto.
  */
 export const file_api_v1_ai_service: GenFile = /*@__PURE__*/
-  fileDesc("ChdhcGkvdjEvYWlfc2VydmljZS5wcm90bxIMbWVtb3MuYXBpLnYxIjoKFVNlbWFudGljU2VhcmNoUmVxdWVzdBISCgVxdWVyeRgBIAEoCUID4EECEg0KBWxpbWl0GAIgASgFIkUKFlNlbWFudGljU2VhcmNoUmVzcG9uc2USKwoHcmVzdWx0cxgBIAMoCzIaLm1lbW9zLmFwaS52MS5TZWFyY2hSZXN1bHQiPAoMU2VhcmNoUmVzdWx0EgwKBG5hbWUYASABKAkSDwoHc25pcHBldBgCIAEoCRINCgVzY29yZRgDIAEoAiI5ChJTdWdnZXN0VGFnc1JlcXVlc3QSFAoHY29udGVudBgBIAEoCUID4EECEg0KBWxpbWl0GAIgASgFIiMKE1N1Z2dlc3RUYWdzUmVzcG9uc2USDAoEdGFncxgBIAMoCSIlCg1Gb3JtYXRSZXF1ZXN0EhQKB2NvbnRlbnQYASABKAlCA+BBAiJECg5Gb3JtYXRSZXNwb25zZRIRCglmb3JtYXR0ZWQYASABKAkSDwoHY2hhbmdlZBgCIAEoCBIOCgZzb3VyY2UYAyABKAkiNAoOU3VtbWFyeVJlcXVlc3QSFAoHY29udGVudBgBIAEoCUID4EECEgwKBG5hbWUYAiABKAkiMgoPU3VtbWFyeVJlc3BvbnNlEg8KB3N1bW1hcnkYASABKAkSDgoGc291cmNlGAIgASgJIp8CCgtDaGF0UmVxdWVzdBIUCgdtZXNzYWdlGAEgASgJQgPgQQISFQoNdXNlcl90aW1lem9uZRgDIAEoCRI8ChNzY2hlZHVsZV9xdWVyeV9tb2RlGAQgASgOMh8ubWVtb3MuYXBpLnYxLlNjaGVkdWxlUXVlcnlNb2RlEisKCmFnZW50X3R5cGUYBSABKA4yFy5tZW1vcy5hcGkudjEuQWdlbnRUeXBlEhcKD2NvbnZlcnNhdGlvbl9pZBgGIAEoBRIcChRpc190ZW1wX2NvbnZlcnNhdGlvbhgHIAEoCBIRCglnZWVrX21vZGUYCiABKAgSFgoOZXZvbHV0aW9uX21vZGUYDCABKAgSFgoOZGV2aWNlX2NvbnRleHQYCyABKAkigAIKDkFJQ29udmVyc2F0aW9uEgoKAmlkGAEgASgFEgsKA3VpZBgCIAEoCRISCgpjcmVhdG9yX2lkGAMgASgFEg0KBXRpdGxlGAQgASgJEhQKDHRpdGxlX3NvdXJjZRgLIAEoCRIqCglwYXJyb3RfaWQYBSABKA4yFy5tZW1vcy5hcGkudjEuQWdlbnRUeXBlEg4KBnBpbm5lZBgGIAEoCBISCgpjcmVhdGVkX3RzGAcgASgDEhIKCnVwZGF0ZWRfdHMYCCABKAMSIwoGYmxvY2tzGAkgAygLMhMubWVtb3MuYXBpLnYxLkJsb2NrEhMKC2Jsb2NrX2NvdW50GAogASgFIhwKGkxpc3RBSUNvbnZlcnNhdGlvbnNSZXF1ZXN0IlIKG0xpc3RBSUNvbnZlcnNhdGlvbnNSZXNwb25zZRIzCg1jb252ZXJzYXRpb25zGAEgAygLMhwubWVtb3MuYXBpLnYxLkFJQ29udmVyc2F0aW9uIiYKGEdldEFJQ29udmVyc2F0aW9uUmVxdWVzdBIKCgJpZBgBIAEoBSJYChtDcmVhdGVBSUNvbnZlcnNhdGlvblJlcXVlc3QSDQoFdGl0bGUYASABKAkSKgoJcGFycm90X2lkGAIgASgOMhcubWVtb3MuYXBpLnYxLkFnZW50VHlwZSJnChtVcGRhdGVBSUNvbnZlcnNhdGlvblJlcXVlc3QSCgoCaWQYASABKAUSEgoFdGl0bGUYAiABKAlIAIgBARITCgZwaW5uZWQYAyABKAhIAYgBAUIICgZfdGl0bGVCCQoHX3Bpbm5lZCIuCiBHZW5lcmF0ZUNvbnZlcnNhdGlvblRpdGxlUmVxdWVzdBIKCgJpZBgBIAEoBSJICiFHZW5lcmF0ZUNvbnZlcnNhdGlvblRpdGxlUmVzcG9uc2USDQoFdGl0bGUYASABKAkSFAoMdGl0bGVfc291cmNlGAIgASgJIikKG0RlbGV0ZUFJQ29udmVyc2F0aW9uUmVxdWVzdBIKCgJpZBgBIAEoBSI6ChpBZGRDb250ZXh0U2VwYXJhdG9yUmVxdWVzdBIcCg9jb252ZXJzYXRpb25faWQYASABKAVCA+BBAiJACiBDbGVhckNvbnZlcnNhdGlvbk1lc3NhZ2VzUmVxdWVzdBIcCg9jb252ZXJzYXRpb25faWQYASABKAVCA+BBAiI/Cg9TdG9wQ2hhdFJlcXVlc3QSHAoPY29udmVyc2F0aW9uX2lkGAEgASgFQgPgQQISDgoGcmVhc29uGAIgASgJImYKEERhbmdlckJsb2NrRXZlbnQSEQoJb3BlcmF0aW9uGAEgASgJEg4KBnJlYXNvbhgCIAEoCRIXCg9wYXR0ZXJuX21hdGNoZWQYAyABKAkSFgoOYnlwYXNzX2FsbG93ZWQYBCABKAgi5gIKDENoYXRSZXNwb25zZRIPCgdjb250ZW50GAEgASgJEg8KB3NvdXJjZXMYAiADKAkSDAoEZG9uZRgDIAEoCBJGChhzY2hlZHVsZV9jcmVhdGlvbl9pbnRlbnQYBCABKAsyJC5tZW1vcy5hcGkudjEuU2NoZWR1bGVDcmVhdGlvbkludGVudBJAChVzY2hlZHVsZV9xdWVyeV9yZXN1bHQYBSABKAsyIS5tZW1vcy5hcGkudjEuU2NoZWR1bGVRdWVyeVJlc3VsdBISCgpldmVudF90eXBlGAYgASgJEhIKCmV2ZW50X2RhdGEYByABKAkSLwoKZXZlbnRfbWV0YRgIIAEoCzIbLm1lbW9zLmFwaS52MS5FdmVudE1ldGFkYXRhEjEKDWJsb2NrX3N1bW1hcnkYCSABKAsyGi5tZW1vcy5hcGkudjEuQmxvY2tTdW1tYXJ5EhAKCGJsb2NrX2lkGAogASgDIlsKFlNjaGVkdWxlQ3JlYXRpb25JbnRlbnQSEAoIZGV0ZWN0ZWQYASABKAgSHAoUc2NoZWR1bGVfZGVzY3JpcHRpb24YAiABKAkSEQoJcmVhc29uaW5nGAMgASgJIo0BChNTY2hlZHVsZVF1ZXJ5UmVzdWx0EhAKCGRldGVjdGVkGAEgASgIEjAKCXNjaGVkdWxlcxgCIAMoCzIdLm1lbW9zLmFwaS52MS5TY2hlZHVsZVN1bW1hcnkSHgoWdGltZV9yYW5nZV9kZXNjcmlwdGlvbhgDIAEoCRISCgpxdWVyeV90eXBlGAQgASgJIpsBCg9TY2hlZHVsZVN1bW1hcnkSCwoDdWlkGAEgASgJEg0KBXRpdGxlGAIgASgJEhAKCHN0YXJ0X3RzGAMgASgDEg4KBmVuZF90cxgEIAEoAxIPCgdhbGxfZGF5GAUgASgIEhAKCGxvY2F0aW9uGAYgASgJEhcKD3JlY3VycmVuY2VfcnVsZRgHIAEoCRIOCgZzdGF0dXMYCCABKAkiOgoWR2V0UmVsYXRlZE1lbW9zUmVxdWVzdBIRCgRuYW1lGAEgASgJQgPgQQISDQoFbGltaXQYAiABKAUiRAoXR2V0UmVsYXRlZE1lbW9zUmVzcG9uc2USKQoFbWVtb3MYASADKAsyGi5tZW1vcy5hcGkudjEuU2VhcmNoUmVzdWx0It0BChNQYXJyb3RTZWxmQ29nbml0aW9uEgwKBG5hbWUYASABKAkSDQoFZW1vamkYAiABKAkSDQoFdGl0bGUYAyABKAkSEwoLcGVyc29uYWxpdHkYBCADKAkSFAoMY2FwYWJpbGl0aWVzGAUgAygJEhMKC2xpbWl0YXRpb25zGAYgAygJEhUKDXdvcmtpbmdfc3R5bGUYByABKAkSFgoOZmF2b3JpdGVfdG9vbHMYCCADKAkSGQoRc2VsZl9pbnRyb2R1Y3Rpb24YCSABKAkSEAoIZnVuX2ZhY3QYCiABKAkiUQodR2V0UGFycm90U2VsZkNvZ25pdGlvblJlcXVlc3QSMAoKYWdlbnRfdHlwZRgBIAEoDjIXLm1lbW9zLmFwaS52MS5BZ2VudFR5cGVCA+BBAiJbCh5HZXRQYXJyb3RTZWxmQ29nbml0aW9uUmVzcG9uc2USOQoOc2VsZl9jb2duaXRpb24YASABKAsyIS5tZW1vcy5hcGkudjEuUGFycm90U2VsZkNvZ25pdGlvbiIUChJMaXN0UGFycm90c1JlcXVlc3QiQAoTTGlzdFBhcnJvdHNSZXNwb25zZRIpCgdwYXJyb3RzGAEgAygLMhgubWVtb3MuYXBpLnYxLlBhcnJvdEluZm8iggEKClBhcnJvdEluZm8SKwoKYWdlbnRfdHlwZRgBIAEoDjIXLm1lbW9zLmFwaS52MS5BZ2VudFR5cGUSDAoEbmFtZRgCIAEoCRI5Cg5zZWxmX2NvZ25pdGlvbhgDIAEoCzIhLm1lbW9zLmFwaS52MS5QYXJyb3RTZWxmQ29nbml0aW9uIlsKF0RldGVjdER1cGxpY2F0ZXNSZXF1ZXN0Eg0KBXRpdGxlGAEgASgJEhQKB2NvbnRlbnQYAiABKAlCA+BBAhIMCgR0YWdzGAMgAygJEg0KBXRvcF9rGAQgASgFIrUBChhEZXRlY3REdXBsaWNhdGVzUmVzcG9uc2USFQoNaGFzX2R1cGxpY2F0ZRgBIAEoCBITCgtoYXNfcmVsYXRlZBgCIAEoCBItCgpkdXBsaWNhdGVzGAMgAygLMhkubWVtb3MuYXBpLnYxLlNpbWlsYXJNZW1vEioKB3JlbGF0ZWQYBCADKAsyGS5tZW1vcy5hcGkudjEuU2ltaWxhck1lbW8SEgoKbGF0ZW5jeV9tcxgFIAEoAyK1AQoLU2ltaWxhck1lbW8SCgoCaWQYASABKAkSDAoEbmFtZRgCIAEoCRINCgV0aXRsZRgDIAEoCRIPCgdzbmlwcGV0GAQgASgJEhIKCnNpbWlsYXJpdHkYBSABKAESEwoLc2hhcmVkX3RhZ3MYBiADKAkSDQoFbGV2ZWwYByABKAkSNAoJYnJlYWtkb3duGAggASgLMiEubWVtb3MuYXBpLnYxLlNpbWlsYXJpdHlCcmVha2Rvd24iTgoTU2ltaWxhcml0eUJyZWFrZG93bhIOCgZ2ZWN0b3IYASABKAESFAoMdGFnX2NvX29jY3VyGAIgASgBEhEKCXRpbWVfcHJveBgDIAEoASJHChFNZXJnZU1lbW9zUmVxdWVzdBIYCgtzb3VyY2VfbmFtZRgBIAEoCUID4EECEhgKC3RhcmdldF9uYW1lGAIgASgJQgPgQQIiKQoSTWVyZ2VNZW1vc1Jlc3BvbnNlEhMKC21lcmdlZF9uYW1lGAEgASgJIkYKEExpbmtNZW1vc1JlcXVlc3QSGAoLbWVtb19uYW1lXzEYASABKAlCA+BBAhIYCgttZW1vX25hbWVfMhgCIAEoCUID4EECIiQKEUxpbmtNZW1vc1Jlc3BvbnNlEg8KB3N1Y2Nlc3MYASABKAgiUgoYR2V0S25vd2xlZGdlR3JhcGhSZXF1ZXN0EgwKBHRhZ3MYASADKAkSFgoObWluX2ltcG9ydGFuY2UYAiABKAESEAoIY2x1c3RlcnMYAyADKAUipgEKGUdldEtub3dsZWRnZUdyYXBoUmVzcG9uc2USJgoFbm9kZXMYASADKAsyFy5tZW1vcy5hcGkudjEuR3JhcGhOb2RlEiYKBWVkZ2VzGAIgAygLMhcubWVtb3MuYXBpLnYxLkdyYXBoRWRnZRInCgVzdGF0cxgDIAEoCzIYLm1lbW9zLmFwaS52MS5HcmFwaFN0YXRzEhAKCGJ1aWxkX21zGAQgASgDInsKCUdyYXBoTm9kZRIKCgJpZBgBIAEoCRINCgVsYWJlbBgCIAEoCRIMCgR0eXBlGAMgASgJEgwKBHRhZ3MYBCADKAkSEgoKaW1wb3J0YW5jZRgFIAEoARIPCgdjbHVzdGVyGAYgASgFEhIKCmNyZWF0ZWRfdHMYByABKAMiSQoJR3JhcGhFZGdlEg4KBnNvdXJjZRgBIAEoCRIOCgZ0YXJnZXQYAiABKAkSDAoEdHlwZRgDIAEoCRIOCgZ3ZWlnaHQYBCABKAEiigEKCkdyYXBoU3RhdHMSEgoKbm9kZV9jb3VudBgBIAEoBRISCgplZGdlX2NvdW50GAIgASgFEhUKDWNsdXN0ZXJfY291bnQYAyABKAUSEgoKbGlua19lZGdlcxgEIAEoBRIRCgl0YWdfZWRnZXMYBSABKAUSFgoOc2VtYW50aWNfZWRnZXMYBiABKAUiJQoUR2V0RHVlUmV2aWV3c1JlcXVlc3QSDQoFbGltaXQYASABKAUiUwoVR2V0RHVlUmV2aWV3c1Jlc3BvbnNlEicKBWl0ZW1zGAEgAygLMhgubWVtb3MuYXBpLnYxLlJldmlld0l0ZW0SEQoJdG90YWxfZHVlGAIgASgFIssBCgpSZXZpZXdJdGVtEhAKCG1lbW9fdWlkGAEgASgJEhEKCW1lbW9fbmFtZRgCIAEoCRINCgV0aXRsZRgDIAEoCRIPCgdzbmlwcGV0GAQgASgJEgwKBHRhZ3MYBSADKAkSFgoObGFzdF9yZXZpZXdfdHMYBiABKAMSFAoMcmV2aWV3X2NvdW50GAcgASgFEhYKDm5leHRfcmV2aWV3X3RzGAggASgDEhAKCHByaW9yaXR5GAkgASgBEhIKCmNyZWF0ZWRfdHMYCiABKAMiXwoTUmVjb3JkUmV2aWV3UmVxdWVzdBIVCghtZW1vX3VpZBgBIAEoCUID4EECEjEKB3F1YWxpdHkYAiABKA4yGy5tZW1vcy5hcGkudjEuUmV2aWV3UXVhbGl0eUID4EECInUKG1JlY29yZFJvdXRlckZlZWRiYWNrUmVxdWVzdBISCgVpbnB1dBgBIAEoCUID4EECEhYKCXByZWRpY3RlZBgCIAEoCUID4EECEhMKBmFjdHVhbBgDIAEoCUID4EECEhUKCGZlZWRiYWNrGAQgASgJQgPgQQIiFwoVR2V0UmV2aWV3U3RhdHNSZXF1ZXN0IskBChZHZXRSZXZpZXdTdGF0c1Jlc3BvbnNlEhMKC3RvdGFsX21lbW9zGAEgASgFEhEKCWR1ZV90b2RheRgCIAEoBRIWCg5yZXZpZXdlZF90b2RheRgDIAEoBRIRCgluZXdfbWVtb3MYBCABKAUSFgoObWFzdGVyZWRfbWVtb3MYBSABKAUSEwoLc3RyZWFrX2RheXMYBiABKAUSFQoNdG90YWxfcmV2aWV3cxgHIAEoBRIYChBhdmVyYWdlX2FjY3VyYWN5GAggASgFIsACCg1FdmVudE1ldGFkYXRhEhMKC2R1cmF0aW9uX21zGAEgASgDEhkKEXRvdGFsX2R1cmF0aW9uX21zGAIgASgDEhEKCXRvb2xfbmFtZRgDIAEoCRIPCgd0b29sX2lkGAQgASgJEhQKDGlucHV0X3Rva2VucxgFIAEoBRIVCg1vdXRwdXRfdG9rZW5zGAYgASgFEhoKEmNhY2hlX3dyaXRlX3Rva2VucxgHIAEoBRIZChFjYWNoZV9yZWFkX3Rva2VucxgIIAEoBRIOCgZzdGF0dXMYCSABKAkSEQoJZXJyb3JfbXNnGAogASgJEhUKDWlucHV0X3N1bW1hcnkYCyABKAkSFgoOb3V0cHV0X3N1bW1hcnkYDCABKAkSEQoJZmlsZV9wYXRoGA0gASgJEhIKCmxpbmVfY291bnQYDiABKAUipQMKDEJsb2NrU3VtbWFyeRISCgpzZXNzaW9uX2lkGAEgASgJEhkKEXRvdGFsX2R1cmF0aW9uX21zGAIgASgDEhwKFHRoaW5raW5nX2R1cmF0aW9uX21zGAMgASgDEhgKEHRvb2xfZHVyYXRpb25fbXMYBCABKAMSHgoWZ2VuZXJhdGlvbl9kdXJhdGlvbl9tcxgFIAEoAxIaChJ0b3RhbF9pbnB1dF90b2tlbnMYBiABKAUSGwoTdG90YWxfb3V0cHV0X3Rva2VucxgHIAEoBRIgChh0b3RhbF9jYWNoZV93cml0ZV90b2tlbnMYCCABKAUSHwoXdG90YWxfY2FjaGVfcmVhZF90b2tlbnMYCSABKAUSFwoPdG9vbF9jYWxsX2NvdW50GAogASgFEhIKCnRvb2xzX3VzZWQYCyADKAkSFgoOZmlsZXNfbW9kaWZpZWQYDCABKAUSEgoKZmlsZV9wYXRocxgNIAMoCRIWCg50b3RhbF9jb3N0X3VzZBgQIAEoARIOCgZzdGF0dXMYDiABKAkSEQoJZXJyb3JfbXNnGA8gASgJItUECgxTZXNzaW9uU3RhdHMSCgoCaWQYASABKAMSEgoKc2Vzc2lvbl9pZBgCIAEoCRIXCg9jb252ZXJzYXRpb25faWQYAyABKAMSDwoHdXNlcl9pZBgEIAEoBRISCgphZ2VudF90eXBlGAUgASgJEhIKCnN0YXJ0ZWRfYXQYBiABKAMSEAoIZW5kZWRfYXQYByABKAMSGQoRdG90YWxfZHVyYXRpb25fbXMYCCABKAMSHAoUdGhpbmtpbmdfZHVyYXRpb25fbXMYCSABKAMSGAoQdG9vbF9kdXJhdGlvbl9tcxgKIAEoAxIeChZnZW5lcmF0aW9uX2R1cmF0aW9uX21zGAsgASgDEhQKDGlucHV0X3Rva2VucxgMIAEoBRIVCg1vdXRwdXRfdG9rZW5zGA0gASgFEhoKEmNhY2hlX3dyaXRlX3Rva2VucxgOIAEoBRIZChFjYWNoZV9yZWFkX3Rva2VucxgPIAEoBRIUCgx0b3RhbF90b2tlbnMYECABKAUSFgoOdG90YWxfY29zdF91c2QYESABKAESFwoPdG9vbF9jYWxsX2NvdW50GBIgASgFEhIKCnRvb2xzX3VzZWQYEyADKAkSFgoOZmlsZXNfbW9kaWZpZWQYFCABKAUSEgoKZmlsZV9wYXRocxgVIAMoCRISCgptb2RlbF91c2VkGBYgASgJEhAKCGlzX2Vycm9yGBcgASgIEhUKDWVycm9yX21lc3NhZ2UYGCABKAkSEgoKY3JlYXRlZF9hdBgZIAEoAxISCgp1cGRhdGVkX2F0GBogASgDIjEKFkdldFNlc3Npb25TdGF0c1JlcXVlc3QSFwoKc2Vzc2lvbl9pZBgBIAEoCUID4EECIkYKF0xpc3RTZXNzaW9uU3RhdHNSZXF1ZXN0Eg0KBWxpbWl0GAEgASgFEg4KBm9mZnNldBgCIAEoBRIMCgRkYXlzGAMgASgFInUKGExpc3RTZXNzaW9uU3RhdHNSZXNwb25zZRIsCghzZXNzaW9ucxgBIAMoCzIaLm1lbW9zLmFwaS52MS5TZXNzaW9uU3RhdHMSEwoLdG90YWxfY291bnQYAiABKAMSFgoOdG90YWxfY29zdF91c2QYAyABKAEiIwoTR2V0Q29zdFN0YXRzUmVxdWVzdBIMCgRkYXlzGAEgASgFIscBCglDb3N0U3RhdHMSFgoOdG90YWxfY29zdF91c2QYASABKAESGQoRZGFpbHlfYXZlcmFnZV91c2QYAiABKAESFQoNc2Vzc2lvbl9jb3VudBgDIAEoAxI6ChZtb3N0X2V4cGVuc2l2ZV9zZXNzaW9uGAQgASgLMhoubWVtb3MuYXBpLnYxLlNlc3Npb25TdGF0cxI0Cg9kYWlseV9icmVha2Rvd24YBSADKAsyGy5tZW1vcy5hcGkudjEuRGFpbHlDb3N0RGF0YSJGCg1EYWlseUNvc3REYXRhEgwKBGRhdGUYASABKAkSEAoIY29zdF91c2QYAiABKAESFQoNc2Vzc2lvbl9jb3VudBgDIAEoAyKqAQoQVXNlckNvc3RTZXR0aW5ncxIYChBkYWlseV9idWRnZXRfdXNkGAEgASgBEiEKGXBlcl9zZXNzaW9uX3RocmVzaG9sZF91c2QYAiABKAESFQoNYWxlcnRfZW5hYmxlZBgDIAEoCBITCgthbGVydF9lbWFpbBgEIAEoCBIUCgxhbGVydF9pbl9hcHAYBSABKAgSFwoPYnVkZ2V0X3Jlc2V0X2F0GAYgASgDIpoCChpTZXRVc2VyQ29zdFNldHRpbmdzUmVxdWVzdBIdChBkYWlseV9idWRnZXRfdXNkGAEgASgBSACIAQESJgoZcGVyX3Nlc3Npb25fdGhyZXNob2xkX3VzZBgCIAEoAUgBiAEBEhoKDWFsZXJ0X2VuYWJsZWQYAyABKAhIAogBARIYCgthbGVydF9lbWFpbBgEIAEoCEgDiAEBEhkKDGFsZXJ0X2luX2FwcBgFIAEoCEgEiAEBQhMKEV9kYWlseV9idWRnZXRfdXNkQhwKGl9wZXJfc2Vzc2lvbl90aHJlc2hvbGRfdXNkQhAKDl9hbGVydF9lbmFibGVkQg4KDF9hbGVydF9lbWFpbEIPCg1fYWxlcnRfaW5fYXBwItIFCgVCbG9jaxIKCgJpZBgBIAEoAxILCgN1aWQYAiABKAkSFwoPY29udmVyc2F0aW9uX2lkGAMgASgFEhQKDHJvdW5kX251bWJlchgEIAEoBRIrCgpibG9ja190eXBlGAUgASgOMhcubWVtb3MuYXBpLnYxLkJsb2NrVHlwZRIlCgRtb2RlGAYgASgOMhcubWVtb3MuYXBpLnYxLkJsb2NrTW9kZRIsCgt1c2VyX2lucHV0cxgHIAMoCzIXLm1lbW9zLmFwaS52MS5Vc2VySW5wdXQSGQoRYXNzaXN0YW50X2NvbnRlbnQYCCABKAkSGwoTYXNzaXN0YW50X3RpbWVzdGFtcBgJIAEoAxIuCgxldmVudF9zdHJlYW0YCiADKAsyGC5tZW1vcy5hcGkudjEuQmxvY2tFdmVudBIxCg1zZXNzaW9uX3N0YXRzGAsgASgLMhoubWVtb3MuYXBpLnYxLlNlc3Npb25TdGF0cxIVCg1jY19zZXNzaW9uX2lkGAwgASgJEikKBnN0YXR1cxgNIAEoDjIZLm1lbW9zLmFwaS52MS5CbG9ja1N0YXR1cxIXCg9wYXJlbnRfYmxvY2tfaWQYDiABKAMSEwoLYnJhbmNoX3BhdGgYDyABKAkSLQoLdG9rZW5fdXNhZ2UYEyABKAsyGC5tZW1vcy5hcGkudjEuVG9rZW5Vc2FnZRIVCg1jb3N0X2VzdGltYXRlGBQgASgDEhUKDW1vZGVsX3ZlcnNpb24YFSABKAkSFQoNdXNlcl9mZWVkYmFjaxgWIAEoCRIaChJyZWdlbmVyYXRpb25fY291bnQYFyABKAUSFQoNZXJyb3JfbWVzc2FnZRgYIAEoCRITCgthcmNoaXZlZF9hdBgZIAEoAxIQCghtZXRhZGF0YRgQIAEoCRISCgpjcmVhdGVkX3RzGBEgASgDEhIKCnVwZGF0ZWRfdHMYEiABKAMiiwEKClRva2VuVXNhZ2USFQoNcHJvbXB0X3Rva2VucxgBIAEoBRIZChFjb21wbGV0aW9uX3Rva2VucxgCIAEoBRIUCgx0b3RhbF90b2tlbnMYAyABKAUSGQoRY2FjaGVfcmVhZF90b2tlbnMYBCABKAUSGgoSY2FjaGVfd3JpdGVfdG9rZW5zGAUgASgFIkEKCVVzZXJJbnB1dBIPCgdjb250ZW50GAEgASgJEhEKCXRpbWVzdGFtcBgCIAEoAxIQCghtZXRhZGF0YRgDIAEoCSJMCgpCbG9ja0V2ZW50EgwKBHR5cGUYASABKAkSDwoHY29udGVudBgCIAEoCRIRCgl0aW1lc3RhbXAYAyABKAMSDAoEbWV0YRgEIAEoCSLBAQoRTGlzdEJsb2Nrc1JlcXVlc3QSHAoPY29udmVyc2F0aW9uX2lkGAEgASgFQgPgQQISKQoGc3RhdHVzGAIgASgOMhkubWVtb3MuYXBpLnYxLkJsb2NrU3RhdHVzEiUKBG1vZGUYAyABKA4yFy5tZW1vcy5hcGkudjEuQmxvY2tNb2RlEhUKDWNjX3Nlc3Npb25faWQYBCABKAkSDQoFbGltaXQYBSABKAUSFgoObGFzdF9ibG9ja191aWQYBiABKAkikQEKEkxpc3RCbG9ja3NSZXNwb25zZRIjCgZibG9ja3MYASADKAsyEy5tZW1vcy5hcGkudjEuQmxvY2sSEAoIaGFzX21vcmUYAiABKAgSEwoLdG90YWxfY291bnQYAyABKAUSGAoQbGF0ZXN0X2Jsb2NrX3VpZBgEIAEoCRIVCg1zeW5jX3JlcXVpcmVkGAUgASgIIiIKD0dldEJsb2NrUmVxdWVzdBIPCgJpZBgBIAEoA0ID4EECIt0BChJDcmVhdGVCbG9ja1JlcXVlc3QSHAoPY29udmVyc2F0aW9uX2lkGAEgASgFQgPgQQISKwoKYmxvY2tfdHlwZRgCIAEoDjIXLm1lbW9zLmFwaS52MS5CbG9ja1R5cGUSJQoEbW9kZRgDIAEoDjIXLm1lbW9zLmFwaS52MS5CbG9ja01vZGUSLAoLdXNlcl9pbnB1dHMYBCADKAsyFy5tZW1vcy5hcGkudjEuVXNlcklucHV0EhAKCG1ldGFkYXRhGAUgASgJEhUKDWNjX3Nlc3Npb25faWQYBiABKAkiuQIKElVwZGF0ZUJsb2NrUmVxdWVzdBIPCgJpZBgBIAEoA0ID4EECEh4KEWFzc2lzdGFudF9jb250ZW50GAIgASgJSACIAQESLgoMZXZlbnRfc3RyZWFtGAMgAygLMhgubWVtb3MuYXBpLnYxLkJsb2NrRXZlbnQSMQoNc2Vzc2lvbl9zdGF0cxgEIAEoCzIaLm1lbW9zLmFwaS52MS5TZXNzaW9uU3RhdHMSGgoNY2Nfc2Vzc2lvbl9pZBgFIAEoCUgBiAEBEi4KBnN0YXR1cxgGIAEoDjIZLm1lbW9zLmFwaS52MS5CbG9ja1N0YXR1c0gCiAEBEhAKCG1ldGFkYXRhGAcgASgJQhQKEl9hc3Npc3RhbnRfY29udGVudEIQCg5fY2Nfc2Vzc2lvbl9pZEIJCgdfc3RhdHVzIiUKEkRlbGV0ZUJsb2NrUmVxdWVzdBIPCgJpZBgBIAEoA0ID4EECIlYKFkFwcGVuZFVzZXJJbnB1dFJlcXVlc3QSDwoCaWQYASABKANCA+BBAhIrCgVpbnB1dBgCIAEoCzIXLm1lbW9zLmFwaS52MS5Vc2VySW5wdXRCA+BBAiJTChJBcHBlbmRFdmVudFJlcXVlc3QSDwoCaWQYASABKANCA+BBAhIsCgVldmVudBgCIAEoCzIYLm1lbW9zLmFwaS52MS5CbG9ja0V2ZW50QgPgQQIieQoQRm9ya0Jsb2NrUmVxdWVzdBIPCgJpZBgBIAEoA0ID4EECEhMKBnJlYXNvbhgCIAEoCUgAiAEBEjQKE3JlcGxhY2VfdXNlcl9pbnB1dHMYAyADKAsyFy5tZW1vcy5hcGkudjEuVXNlcklucHV0QgkKB19yZWFzb24iKwoYTGlzdEJsb2NrQnJhbmNoZXNSZXF1ZXN0Eg8KAmlkGAEgASgDQgPgQQIiZAoZTGlzdEJsb2NrQnJhbmNoZXNSZXNwb25zZRIrCghicmFuY2hlcxgBIAMoCzIZLm1lbW9zLmFwaS52MS5CbG9ja0JyYW5jaBIaChJhY3RpdmVfYnJhbmNoX3BhdGgYAiABKAkihgEKC0Jsb2NrQnJhbmNoEiIKBWJsb2NrGAEgASgLMhMubWVtb3MuYXBpLnYxLkJsb2NrEhMKC2JyYW5jaF9wYXRoGAIgASgJEhEKCWlzX2FjdGl2ZRgDIAEoCBIrCghjaGlsZHJlbhgEIAMoCzIZLm1lbW9zLmFwaS52MS5CbG9ja0JyYW5jaCJUChNTd2l0Y2hCcmFuY2hSZXF1ZXN0EhwKD2NvbnZlcnNhdGlvbl9pZBgBIAEoBUID4EECEh8KEnRhcmdldF9icmFuY2hfcGF0aBgCIAEoCUID4EECIjcKE0RlbGV0ZUJyYW5jaFJlcXVlc3QSDwoCaWQYASABKANCA+BBAhIPCgdjYXNjYWRlGAIgASgIKjcKEVNjaGVkdWxlUXVlcnlNb2RlEggKBEFVVE8QABIMCghTVEFOREFSRBABEgoKBlNUUklDVBACKogBCglBZ2VudFR5cGUSFgoSQUdFTlRfVFlQRV9ERUZBVUxUEAASEwoPQUdFTlRfVFlQRV9NRU1PEAESFwoTQUdFTlRfVFlQRV9TQ0hFRFVMRRACEhYKEkFHRU5UX1RZUEVfR0VORVJBTBADEhcKE0FHRU5UX1RZUEVfSURFQVRJT04QBSIECAQQBCqUAQoNUmV2aWV3UXVhbGl0eRIeChpSRVZJRVdfUVVBTElUWV9VTlNQRUNJRklFRBAAEhgKFFJFVklFV19RVUFMSVRZX0FHQUlOEAESFwoTUkVWSUVXX1FVQUxJVFlfSEFSRBACEhcKE1JFVklFV19RVUFMSVRZX0dPT0QQAxIXChNSRVZJRVdfUVVBTElUWV9FQVNZEAQqYQoJQmxvY2tUeXBlEhoKFkJMT0NLX1RZUEVfVU5TUEVDSUZJRUQQABIWChJCTE9DS19UWVBFX01FU1NBR0UQARIgChxCTE9DS19UWVBFX0NPTlRFWFRfU0VQQVJBVE9SEAIqbQoJQmxvY2tNb2RlEhoKFkJMT0NLX01PREVfVU5TUEVDSUZJRUQQABIVChFCTE9DS19NT0RFX05PUk1BTBABEhMKD0JMT0NLX01PREVfR0VFSxACEhgKFEJMT0NLX01PREVfRVZPTFVUSU9OEAMqlQEKC0Jsb2NrU3RhdHVzEhwKGEJMT0NLX1NUQVRVU19VTlNQRUNJRklFRBAAEhgKFEJMT0NLX1NUQVRVU19QRU5ESU5HEAESGgoWQkxPQ0tfU1RBVFVTX1NUUkVBTUlORxACEhoKFkJMT0NLX1NUQVRVU19DT01QTEVURUQQAxIWChJCTE9DS19TVEFUVVNfRVJST1IQBDLUKAoJQUlTZXJ2aWNlEnkKDlNlbWFudGljU2VhcmNoEiMubWVtb3MuYXBpLnYxLlNlbWFudGljU2VhcmNoUmVxdWVzdBokLm1lbW9zLmFwaS52MS5TZW1hbnRpY1NlYXJjaFJlc3BvbnNlIhyC0+STAhY6ASoiES9hcGkvdjEvYWkvc2VhcmNoEnYKC1N1Z2dlc3RUYWdzEiAubWVtb3MuYXBpLnYxLlN1Z2dlc3RUYWdzUmVxdWVzdBohLm1lbW9zLmFwaS52MS5TdWdnZXN0VGFnc1Jlc3BvbnNlIiKC0+STAhw6ASoiFy9hcGkvdjEvYWkvc3VnZ2VzdC10YWdzEmEKBkZvcm1hdBIbLm1lbW9zLmFwaS52MS5Gb3JtYXRSZXF1ZXN0GhwubWVtb3MuYXBpLnYxLkZvcm1hdFJlc3BvbnNlIhyC0+STAhY6ASoiES9hcGkvdjEvYWkvZm9ybWF0EmUKB1N1bW1hcnkSHC5tZW1vcy5hcGkudjEuU3VtbWFyeVJlcXVlc3QaHS5tZW1vcy5hcGkudjEuU3VtbWFyeVJlc3BvbnNlIh2C0+STAhc6ASoiEi9hcGkvdjEvYWkvc3VtbWFyeRJbCgRDaGF0EhkubWVtb3MuYXBpLnYxLkNoYXRSZXF1ZXN0GhoubWVtb3MuYXBpLnYxLkNoYXRSZXNwb25zZSIagtPkkwIUOgEqIg8vYXBpL3YxL2FpL2NoYXQwARKGAQoPR2V0UmVsYXRlZE1lbW9zEiQubWVtb3MuYXBpLnYxLkdldFJlbGF0ZWRNZW1vc1JlcXVlc3QaJS5tZW1vcy5hcGkudjEuR2V0UmVsYXRlZE1lbW9zUmVzcG9uc2UiJoLT5JMCIBIeL2FwaS92MS97bmFtZT1tZW1vcy8qfS9yZWxhdGVkEqsBChZHZXRQYXJyb3RTZWxmQ29nbml0aW9uEisubWVtb3MuYXBpLnYxLkdldFBhcnJvdFNlbGZDb2duaXRpb25SZXF1ZXN0GiwubWVtb3MuYXBpLnYxLkdldFBhcnJvdFNlbGZDb2duaXRpb25SZXNwb25zZSI2gtPkkwIwEi4vYXBpL3YxL2FpL3BhcnJvdHMve2FnZW50X3R5cGV9L3NlbGYtY29nbml0aW9uEm4KC0xpc3RQYXJyb3RzEiAubWVtb3MuYXBpLnYxLkxpc3RQYXJyb3RzUmVxdWVzdBohLm1lbW9zLmFwaS52MS5MaXN0UGFycm90c1Jlc3BvbnNlIhqC0+STAhQSEi9hcGkvdjEvYWkvcGFycm90cxKKAQoQRGV0ZWN0RHVwbGljYXRlcxIlLm1lbW9zLmFwaS52MS5EZXRlY3REdXBsaWNhdGVzUmVxdWVzdBomLm1lbW9zLmFwaS52MS5EZXRlY3REdXBsaWNhdGVzUmVzcG9uc2UiJ4LT5JMCIToBKiIcL2FwaS92MS9haS9kZXRlY3QtZHVwbGljYXRlcxJyCgpNZXJnZU1lbW9zEh8ubWVtb3MuYXBpLnYxLk1lcmdlTWVtb3NSZXF1ZXN0GiAubWVtb3MuYXBpLnYxLk1lcmdlTWVtb3NSZXNwb25zZSIhgtPkkwIbOgEqIhYvYXBpL3YxL2FpL21lcmdlLW1lbW9zEm4KCUxpbmtNZW1vcxIeLm1lbW9zLmFwaS52MS5MaW5rTWVtb3NSZXF1ZXN0Gh8ubWVtb3MuYXBpLnYxLkxpbmtNZW1vc1Jlc3BvbnNlIiCC0+STAho6ASoiFS9hcGkvdjEvYWkvbGluay1tZW1vcxKIAQoRR2V0S25vd2xlZGdlR3JhcGgSJi5tZW1vcy5hcGkudjEuR2V0S25vd2xlZGdlR3JhcGhSZXF1ZXN0GicubWVtb3MuYXBpLnYxLkdldEtub3dsZWRnZUdyYXBoUmVzcG9uc2UiIoLT5JMCHBIaL2FwaS92MS9haS9rbm93bGVkZ2UtZ3JhcGgSeAoNR2V0RHVlUmV2aWV3cxIiLm1lbW9zLmFwaS52MS5HZXREdWVSZXZpZXdzUmVxdWVzdBojLm1lbW9zLmFwaS52MS5HZXREdWVSZXZpZXdzUmVzcG9uc2UiHoLT5JMCGBIWL2FwaS92MS9haS9yZXZpZXdzL2R1ZRJ6CgxSZWNvcmRSZXZpZXcSIS5tZW1vcy5hcGkudjEuUmVjb3JkUmV2aWV3UmVxdWVzdBoWLmdvb2dsZS5wcm90b2J1Zi5FbXB0eSIvgtPkkwIpOgEqIiQvYXBpL3YxL2FpL3Jldmlld3Mve21lbW9fdWlkfS9yZWNvcmQSgQEKFFJlY29yZFJvdXRlckZlZWRiYWNrEikubWVtb3MuYXBpLnYxLlJlY29yZFJvdXRlckZlZWRiYWNrUmVxdWVzdBoWLmdvb2dsZS5wcm90b2J1Zi5FbXB0eSImgtPkkwIgOgEqIhsvYXBpL3YxL2FpL3JvdXRpbmcvZmVlZGJhY2sSfQoOR2V0UmV2aWV3U3RhdHMSIy5tZW1vcy5hcGkudjEuR2V0UmV2aWV3U3RhdHNSZXF1ZXN0GiQubWVtb3MuYXBpLnYxLkdldFJldmlld1N0YXRzUmVzcG9uc2UiIILT5JMCGhIYL2FwaS92MS9haS9yZXZpZXdzL3N0YXRzEowBChNMaXN0QUlDb252ZXJzYXRpb25zEigubWVtb3MuYXBpLnYxLkxpc3RBSUNvbnZlcnNhdGlvbnNSZXF1ZXN0GikubWVtb3MuYXBpLnYxLkxpc3RBSUNvbnZlcnNhdGlvbnNSZXNwb25zZSIggtPkkwIaEhgvYXBpL3YxL2FpL2NvbnZlcnNhdGlvbnMSgAEKEUdldEFJQ29udmVyc2F0aW9uEiYubWVtb3MuYXBpLnYxLkdldEFJQ29udmVyc2F0aW9uUmVxdWVzdBocLm1lbW9zLmFwaS52MS5BSUNvbnZlcnNhdGlvbiIlgtPkkwIfEh0vYXBpL3YxL2FpL2NvbnZlcnNhdGlvbnMve2lkfRKEAQoUQ3JlYXRlQUlDb252ZXJzYXRpb24SKS5tZW1vcy5hcGkudjEuQ3JlYXRlQUlDb252ZXJzYXRpb25SZXF1ZXN0GhwubWVtb3MuYXBpLnYxLkFJQ29udmVyc2F0aW9uIiOC0+STAh06ASoiGC9hcGkvdjEvYWkvY29udmVyc2F0aW9ucxKJAQoUVXBkYXRlQUlDb252ZXJzYXRpb24SKS5tZW1vcy5hcGkudjEuVXBkYXRlQUlDb252ZXJzYXRpb25SZXF1ZXN0GhwubWVtb3MuYXBpLnYxLkFJQ29udmVyc2F0aW9uIiiC0+STAiI6ASoyHS9hcGkvdjEvYWkvY29udmVyc2F0aW9ucy97aWR9ErUBChlHZW5lcmF0ZUNvbnZlcnNhdGlvblRpdGxlEi4ubWVtb3MuYXBpLnYxLkdlbmVyYXRlQ29udmVyc2F0aW9uVGl0bGVSZXF1ZXN0Gi8ubWVtb3MuYXBpLnYxLkdlbmVyYXRlQ29udmVyc2F0aW9uVGl0bGVSZXNwb25zZSI3gtPkkwIxOgEqIiwvYXBpL3YxL2FpL2NvbnZlcnNhdGlvbnMve2lkfS9nZW5lcmF0ZS10aXRsZRKAAQoURGVsZXRlQUlDb252ZXJzYXRpb24SKS5tZW1vcy5hcGkudjEuRGVsZXRlQUlDb252ZXJzYXRpb25SZXF1ZXN0GhYuZ29vZ2xlLnByb3RvYnVmLkVtcHR5IiWC0+STAh8qHS9hcGkvdjEvYWkvY29udmVyc2F0aW9ucy97aWR9EpgBChNBZGRDb250ZXh0U2VwYXJhdG9yEigubWVtb3MuYXBpLnYxLkFkZENvbnRleHRTZXBhcmF0b3JSZXF1ZXN0GhYuZ29vZ2xlLnByb3RvYnVmLkVtcHR5Ij+C0+STAjk6ASoiNC9hcGkvdjEvYWkvY29udmVyc2F0aW9ucy97Y29udmVyc2F0aW9uX2lkfS9zZXBhcmF0b3ISoAEKGUNsZWFyQ29udmVyc2F0aW9uTWVzc2FnZXMSLi5tZW1vcy5hcGkudjEuQ2xlYXJDb252ZXJzYXRpb25NZXNzYWdlc1JlcXVlc3QaFi5nb29nbGUucHJvdG9idWYuRW1wdHkiO4LT5JMCNSozL2FwaS92MS9haS9jb252ZXJzYXRpb25zL3tjb252ZXJzYXRpb25faWR9L21lc3NhZ2VzEmIKCFN0b3BDaGF0Eh0ubWVtb3MuYXBpLnYxLlN0b3BDaGF0UmVxdWVzdBoWLmdvb2dsZS5wcm90b2J1Zi5FbXB0eSIfgtPkkwIZOgEqIhQvYXBpL3YxL2FpL2NoYXQvc3RvcBJ9Cg9HZXRTZXNzaW9uU3RhdHMSJC5tZW1vcy5hcGkudjEuR2V0U2Vzc2lvblN0YXRzUmVxdWVzdBoaLm1lbW9zLmFwaS52MS5TZXNzaW9uU3RhdHMiKILT5JMCIhIgL2FwaS92MS9haS9zZXNzaW9ucy97c2Vzc2lvbl9pZH0SfgoQTGlzdFNlc3Npb25TdGF0cxIlLm1lbW9zLmFwaS52MS5MaXN0U2Vzc2lvblN0YXRzUmVxdWVzdBomLm1lbW9zLmFwaS52MS5MaXN0U2Vzc2lvblN0YXRzUmVzcG9uc2UiG4LT5JMCFRITL2FwaS92MS9haS9zZXNzaW9ucxJpCgxHZXRDb3N0U3RhdHMSIS5tZW1vcy5hcGkudjEuR2V0Q29zdFN0YXRzUmVxdWVzdBoXLm1lbW9zLmFwaS52MS5Db3N0U3RhdHMiHYLT5JMCFxIVL2FwaS92MS9haS9jb3N0LXN0YXRzEm8KE0dldFVzZXJDb3N0U2V0dGluZ3MSFi5nb29nbGUucHJvdG9idWYuRW1wdHkaHi5tZW1vcy5hcGkudjEuVXNlckNvc3RTZXR0aW5ncyIggtPkkwIaEhgvYXBpL3YxL2FpL2Nvc3Qtc2V0dGluZ3MShAEKE1NldFVzZXJDb3N0U2V0dGluZ3MSKC5tZW1vcy5hcGkudjEuU2V0VXNlckNvc3RTZXR0aW5nc1JlcXVlc3QaHi5tZW1vcy5hcGkudjEuVXNlckNvc3RTZXR0aW5ncyIjgtPkkwIdOgEqMhgvYXBpL3YxL2FpL2Nvc3Qtc2V0dGluZ3MSigEKCkxpc3RCbG9ja3MSHy5tZW1vcy5hcGkudjEuTGlzdEJsb2Nrc1JlcXVlc3QaIC5tZW1vcy5hcGkudjEuTGlzdEJsb2Nrc1Jlc3BvbnNlIjmC0+STAjMSMS9hcGkvdjEvYWkvY29udmVyc2F0aW9ucy97Y29udmVyc2F0aW9uX2lkfS9ibG9ja3MSXgoIR2V0QmxvY2sSHS5tZW1vcy5hcGkudjEuR2V0QmxvY2tSZXF1ZXN0GhMubWVtb3MuYXBpLnYxLkJsb2NrIh6C0+STAhgSFi9hcGkvdjEvYWkvYmxvY2tzL3tpZH0SggEKC0NyZWF0ZUJsb2NrEiAubWVtb3MuYXBpLnYxLkNyZWF0ZUJsb2NrUmVxdWVzdBoTLm1lbW9zLmFwaS52MS5CbG9jayI8gtPkkwI2OgEqIjEvYXBpL3YxL2FpL2NvbnZlcnNhdGlvbnMve2NvbnZlcnNhdGlvbl9pZH0vYmxvY2tzEmcKC1VwZGF0ZUJsb2NrEiAubWVtb3MuYXBpLnYxLlVwZGF0ZUJsb2NrUmVxdWVzdBoTLm1lbW9zLmFwaS52MS5CbG9jayIhgtPkkwIbOgEqMhYvYXBpL3YxL2FpL2Jsb2Nrcy97aWR9EmcKC0RlbGV0ZUJsb2NrEiAubWVtb3MuYXBpLnYxLkRlbGV0ZUJsb2NrUmVxdWVzdBoWLmdvb2dsZS5wcm90b2J1Zi5FbXB0eSIegtPkkwIYKhYvYXBpL3YxL2FpL2Jsb2Nrcy97aWR9EnkKD0FwcGVuZFVzZXJJbnB1dBIkLm1lbW9zLmFwaS52MS5BcHBlbmRVc2VySW5wdXRSZXF1ZXN0GhYuZ29vZ2xlLnByb3RvYnVmLkVtcHR5IiiC0+STAiI6ASoiHS9hcGkvdjEvYWkvYmxvY2tzL3tpZH0vaW5wdXRzEnEKC0FwcGVuZEV2ZW50EiAubWVtb3MuYXBpLnYxLkFwcGVuZEV2ZW50UmVxdWVzdBoWLmdvb2dsZS5wcm90b2J1Zi5FbXB0eSIogtPkkwIiOgEqIh0vYXBpL3YxL2FpL2Jsb2Nrcy97aWR9L2V2ZW50cxJoCglGb3JrQmxvY2sSHi5tZW1vcy5hcGkudjEuRm9ya0Jsb2NrUmVxdWVzdBoTLm1lbW9zLmFwaS52MS5CbG9jayImgtPkkwIgOgEqIhsvYXBpL3YxL2FpL2Jsb2Nrcy97aWR9L2ZvcmsSjQEKEUxpc3RCbG9ja0JyYW5jaGVzEiYubWVtb3MuYXBpLnYxLkxpc3RCbG9ja0JyYW5jaGVzUmVxdWVzdBonLm1lbW9zLmFwaS52MS5MaXN0QmxvY2tCcmFuY2hlc1Jlc3BvbnNlIieC0+STAiESHy9hcGkvdjEvYWkvYmxvY2tzL3tpZH0vYnJhbmNoZXMSjgEKDFN3aXRjaEJyYW5jaBIhLm1lbW9zLmFwaS52MS5Td2l0Y2hCcmFuY2hSZXF1ZXN0GhYuZ29vZ2xlLnByb3RvYnVmLkVtcHR5IkOC0+STAj06ASoiOC9hcGkvdjEvYWkvY29udmVyc2F0aW9ucy97Y29udmVyc2F0aW9uX2lkfS9zd2l0Y2gtYnJhbmNoEnAKDERlbGV0ZUJyYW5jaBIhLm1lbW9zLmFwaS52MS5EZWxldGVCcmFuY2hSZXF1ZXN0GhYuZ29vZ2xlLnByb3RvYnVmLkVtcHR5IiWC0+STAh8qHS9hcGkvdjEvYWkvYmxvY2tzL3tpZH0vYnJhbmNoQqkBChBjb20ubWVtb3MuYXBpLnYxQg5BaVNlcnZpY2VQcm90b1ABWjNnaXRodWIuY29tL2hyeWdvL2RpdmluZXNlbnNlL3Byb3RvL2dlbi9hcGkvdjE7YXBpdjGiAgNNQViqAgxNZW1vcy5BcGkuVjHKAgxNZW1vc1xBcGlcVjHiAhhNZW1vc1xBcGlcVjFcR1BCTWV0YWRhdGHqAg5NZW1vczo6QXBpOjpWMWIGcHJvdG8z", [file_google_api_annotations, file_google_api_field_behavior, file_google_protobuf_empty]);
+  fileDesc("ChdhcGkvdjEvYWlfc2VydmljZS5wcm90bxIMbWVtb3MuYXBpLnYxIjoKFVNlbWFudGljU2VhcmNoUmVxdWVzdBISCgVxdWVyeRgBIAEoCUID4EECEg0KBWxpbWl0GAIgASgFIkUKFlNlbWFudGljU2VhcmNoUmVzcG9uc2USKwoHcmVzdWx0cxgBIAMoCzIaLm1lbW9zLmFwaS52MS5TZWFyY2hSZXN1bHQiPAoMU2VhcmNoUmVzdWx0EgwKBG5hbWUYASABKAkSDwoHc25pcHBldBgCIAEoCRINCgVzY29yZRgDIAEoAiI5ChJTdWdnZXN0VGFnc1JlcXVlc3QSFAoHY29udGVudBgBIAEoCUID4EECEg0KBWxpbWl0GAIgASgFIiMKE1N1Z2dlc3RUYWdzUmVzcG9uc2USDAoEdGFncxgBIAMoCSIlCg1Gb3JtYXRSZXF1ZXN0EhQKB2NvbnRlbnQYASABKAlCA+BBAiJECg5Gb3JtYXRSZXNwb25zZRIRCglmb3JtYXR0ZWQYASABKAkSDwoHY2hhbmdlZBgCIAEoCBIOCgZzb3VyY2UYAyABKAkiNAoOU3VtbWFyeVJlcXVlc3QSFAoHY29udGVudBgBIAEoCUID4EECEgwKBG5hbWUYAiABKAkiMgoPU3VtbWFyeVJlc3BvbnNlEg8KB3N1bW1hcnkYASABKAkSDgoGc291cmNlGAIgASgJIp8CCgtDaGF0UmVxdWVzdBIUCgdtZXNzYWdlGAEgASgJQgPgQQISFQoNdXNlcl90aW1lem9uZRgDIAEoCRI8ChNzY2hlZHVsZV9xdWVyeV9tb2RlGAQgASgOMh8ubWVtb3MuYXBpLnYxLlNjaGVkdWxlUXVlcnlNb2RlEisKCmFnZW50X3R5cGUYBSABKA4yFy5tZW1vcy5hcGkudjEuQWdlbnRUeXBlEhcKD2NvbnZlcnNhdGlvbl9pZBgGIAEoBRIcChRpc190ZW1wX2NvbnZlcnNhdGlvbhgHIAEoCBIRCglnZWVrX21vZGUYCiABKAgSFgoOZXZvbHV0aW9uX21vZGUYDCABKAgSFgoOZGV2aWNlX2NvbnRleHQYCyABKAkigAIKDkFJQ29udmVyc2F0aW9uEgoKAmlkGAEgASgFEgsKA3VpZBgCIAEoCRISCgpjcmVhdG9yX2lkGAMgASgFEg0KBXRpdGxlGAQgASgJEhQKDHRpdGxlX3NvdXJjZRgLIAEoCRIqCglwYXJyb3RfaWQYBSABKA4yFy5tZW1vcy5hcGkudjEuQWdlbnRUeXBlEg4KBnBpbm5lZBgGIAEoCBISCgpjcmVhdGVkX3RzGAcgASgDEhIKCnVwZGF0ZWRfdHMYCCABKAMSIwoGYmxvY2tzGAkgAygLMhMubWVtb3MuYXBpLnYxLkJsb2NrEhMKC2Jsb2NrX2NvdW50GAogASgFIhwKGkxpc3RBSUNvbnZlcnNhdGlvbnNSZXF1ZXN0IlIKG0xpc3RBSUNvbnZlcnNhdGlvbnNSZXNwb25zZRIzCg1jb252ZXJzYXRpb25zGAEgAygLMhwubWVtb3MuYXBpLnYxLkFJQ29udmVyc2F0aW9uIiYKGEdldEFJQ29udmVyc2F0aW9uUmVxdWVzdBIKCgJpZBgBIAEoBSJYChtDcmVhdGVBSUNvbnZlcnNhdGlvblJlcXVlc3QSDQoFdGl0bGUYASABKAkSKgoJcGFycm90X2lkGAIgASgOMhcubWVtb3MuYXBpLnYxLkFnZW50VHlwZSJnChtVcGRhdGVBSUNvbnZlcnNhdGlvblJlcXVlc3QSCgoCaWQYASABKAUSEgoFdGl0bGUYAiABKAlIAIgBARITCgZwaW5uZWQYAyABKAhIAYgBAUIICgZfdGl0bGVCCQoHX3Bpbm5lZCIuCiBHZW5lcmF0ZUNvbnZlcnNhdGlvblRpdGxlUmVxdWVzdBIKCgJpZBgBIAEoBSJICiFHZW5lcmF0ZUNvbnZlcnNhdGlvblRpdGxlUmVzcG9uc2USDQoFdGl0bGUYASABKAkSFAoMdGl0bGVfc291cmNlGAIgASgJIikKG0RlbGV0ZUFJQ29udmVyc2F0aW9uUmVxdWVzdBIKCgJpZBgBIAEoBSI6ChpBZGRDb250ZXh0U2VwYXJhdG9yUmVxdWVzdBIcCg9jb252ZXJzYXRpb25faWQYASABKAVCA+BBAiJACiBDbGVhckNvbnZlcnNhdGlvbk1lc3NhZ2VzUmVxdWVzdBIcCg9jb252ZXJzYXRpb25faWQYASABKAVCA+BBAiIfCh1DcmVhdGVDaGF0U3RyZWFtVGlja2V0UmVxdWVzdCI2ChBDaGF0U3RyZWFtVGlja2V0Eg4KBnRpY2tldBgBIAEoCRISCgpleHBpcmVzX3RzGAIgASgDIj8KD1N0b3BDaGF0UmVxdWVzdBIcCg9jb252ZXJzYXRpb25faWQYASABKAVCA+BBAhIOCgZyZWFzb24YAiABKAkifAoRU3VibWl0Rm9ybVJlcXVlc3QSFQoIYmxvY2tfaWQYASABKANCA+BBAhIUCgdmb3JtX2lkGAIgASgJQgPgQQISKAoHcGF5bG9hZBgDIAEoCzIXLmdvb2dsZS5wcm90b2J1Zi5TdHJ1Y3QSEAoIdGltZXpvbmUYBCABKAkiZgoQRGFuZ2VyQmxvY2tFdmVudBIRCglvcGVyYXRpb24YASABKAkSDgoGcmVhc29uGAIgASgJEhcKD3BhdHRlcm5fbWF0Y2hlZBgDIAEoCRIWCg5ieXBhc3NfYWxsb3dlZBgEIAEoCCL4AgoMQ2hhdFJlc3BvbnNlEg8KB2NvbnRlbnQYASABKAkSDwoHc291cmNlcxgCIAMoCRIMCgRkb25lGAMgASgIEkYKGHNjaGVkdWxlX2NyZWF0aW9uX2ludGVudBgEIAEoCzIkLm1lbW9zLmFwaS52MS5TY2hlZHVsZUNyZWF0aW9uSW50ZW50EkAKFXNjaGVkdWxlX3F1ZXJ5X3Jlc3VsdBgFIAEoCzIhLm1lbW9zLmFwaS52MS5TY2hlZHVsZVF1ZXJ5UmVzdWx0EhIKCmV2ZW50X3R5cGUYBiABKAkSEgoKZXZlbnRfZGF0YRgHIAEoCRIvCgpldmVudF9tZXRhGAggASgLMhsubWVtb3MuYXBpLnYxLkV2ZW50TWV0YWRhdGESMQoNYmxvY2tfc3VtbWFyeRgJIAEoCzIaLm1lbW9zLmFwaS52MS5CbG9ja1N1bW1hcnkSEAoIYmxvY2tfaWQYCiABKAMSEAoIdHJhY2VfaWQYCyABKAkiWwoWU2NoZWR1bGVDcmVhdGlvbkludGVudBIQCghkZXRlY3RlZBgBIAEoCBIcChRzY2hlZHVsZV9kZXNjcmlwdGlvbhgCIAEoCRIRCglyZWFzb25pbmcYAyABKAkijQEKE1NjaGVkdWxlUXVlcnlSZXN1bHQSEAoIZGV0ZWN0ZWQYASABKAgSMAoJc2NoZWR1bGVzGAIgAygLMh0ubWVtb3MuYXBpLnYxLlNjaGVkdWxlU3VtbWFyeRIeChZ0aW1lX3JhbmdlX2Rlc2NyaXB0aW9uGAMgASgJEhIKCnF1ZXJ5X3R5cGUYBCABKAkimwEKD1NjaGVkdWxlU3VtbWFyeRILCgN1aWQYASABKAkSDQoFdGl0bGUYAiABKAkSEAoIc3RhcnRfdHMYAyABKAMSDgoGZW5kX3RzGAQgASgDEg8KB2FsbF9kYXkYBSABKAgSEAoIbG9jYXRpb24YBiABKAkSFwoPcmVjdXJyZW5jZV9ydWxlGAcgASgJEg4KBnN0YXR1cxgIIAEoCSI6ChZHZXRSZWxhdGVkTWVtb3NSZXF1ZXN0EhEKBG5hbWUYASABKAlCA+BBAhINCgVsaW1pdBgCIAEoBSJEChdHZXRSZWxhdGVkTWVtb3NSZXNwb25zZRIpCgVtZW1vcxgBIAMoCzIaLm1lbW9zLmFwaS52MS5TZWFyY2hSZXN1bHQi3QEKE1BhcnJvdFNlbGZDb2duaXRpb24SDAoEbmFtZRgBIAEoCRINCgVlbW9qaRgCIAEoCRINCgV0aXRsZRgDIAEoCRITCgtwZXJzb25hbGl0eRgEIAMoCRIUCgxjYXBhYmlsaXRpZXMYBSADKAkSEwoLbGltaXRhdGlvbnMYBiADKAkSFQoNd29ya2luZ19zdHlsZRgHIAEoCRIWCg5mYXZvcml0ZV90b29scxgIIAMoCRIZChFzZWxmX2ludHJvZHVjdGlvbhgJIAEoCRIQCghmdW5fZmFjdBgKIAEoCSJRCh1HZXRQYXJyb3RTZWxmQ29nbml0aW9uUmVxdWVzdBIwCgphZ2VudF90eXBlGAEgASgOMhcubWVtb3MuYXBpLnYxLkFnZW50VHlwZUID4EECIlsKHkdldFBhcnJvdFNlbGZDb2duaXRpb25SZXNwb25zZRI5Cg5zZWxmX2NvZ25pdGlvbhgBIAEoCzIhLm1lbW9zLmFwaS52MS5QYXJyb3RTZWxmQ29nbml0aW9uIhQKEkxpc3RQYXJyb3RzUmVxdWVzdCJAChNMaXN0UGFycm90c1Jlc3BvbnNlEikKB3BhcnJvdHMYASADKAsyGC5tZW1vcy5hcGkudjEuUGFycm90SW5mbyKCAQoKUGFycm90SW5mbxIrCgphZ2VudF90eXBlGAEgASgOMhcubWVtb3MuYXBpLnYxLkFnZW50VHlwZRIMCgRuYW1lGAIgASgJEjkKDnNlbGZfY29nbml0aW9uGAMgASgLMiEubWVtb3MuYXBpLnYxLlBhcnJvdFNlbGZDb2duaXRpb24iWwoXRGV0ZWN0RHVwbGljYXRlc1JlcXVlc3QSDQoFdGl0bGUYASABKAkSFAoHY29udGVudBgCIAEoCUID4EECEgwKBHRhZ3MYAyADKAkSDQoFdG9wX2sYBCABKAUitQEKGERldGVjdER1cGxpY2F0ZXNSZXNwb25zZRIVCg1oYXNfZHVwbGljYXRlGAEgASgIEhMKC2hhc19yZWxhdGVkGAIgASgIEi0KCmR1cGxpY2F0ZXMYAyADKAsyGS5tZW1vcy5hcGkudjEuU2ltaWxhck1lbW8SKgoHcmVsYXRlZBgEIAMoCzIZLm1lbW9zLmFwaS52MS5TaW1pbGFyTWVtbxISCgpsYXRlbmN5X21zGAUgASgDIrUBCgtTaW1pbGFyTWVtbxIKCgJpZBgBIAEoCRIMCgRuYW1lGAIgASgJEg0KBXRpdGxlGAMgASgJEg8KB3NuaXBwZXQYBCABKAkSEgoKc2ltaWxhcml0eRgFIAEoARITCgtzaGFyZWRfdGFncxgGIAMoCRINCgVsZXZlbBgHIAEoCRI0CglicmVha2Rvd24YCCABKAsyIS5tZW1vcy5hcGkudjEuU2ltaWxhcml0eUJyZWFrZG93biJOChNTaW1pbGFyaXR5QnJlYWtkb3duEg4KBnZlY3RvchgBIAEoARIUCgx0YWdfY29fb2NjdXIYAiABKAESEQoJdGltZV9wcm94GAMgASgBIkcKEU1lcmdlTWVtb3NSZXF1ZXN0EhgKC3NvdXJjZV9uYW1lGAEgASgJQgPgQQISGAoLdGFyZ2V0X25hbWUYAiABKAlCA+BBAiIpChJNZXJnZU1lbW9zUmVzcG9uc2USEwoLbWVyZ2VkX25hbWUYASABKAkiRgoQTGlua01lbW9zUmVxdWVzdBIYCgttZW1vX25hbWVfMRgBIAEoCUID4EECEhgKC21lbW9fbmFtZV8yGAIgASgJQgPgQQIiJAoRTGlua01lbW9zUmVzcG9uc2USDwoHc3VjY2VzcxgBIAEoCCJSChhHZXRLbm93bGVkZ2VHcmFwaFJlcXVlc3QSDAoEdGFncxgBIAMoCRIWCg5taW5faW1wb3J0YW5jZRgCIAEoARIQCghjbHVzdGVycxgDIAMoBSKmAQoZR2V0S25vd2xlZGdlR3JhcGhSZXNwb25zZRImCgVub2RlcxgBIAMoCzIXLm1lbW9zLmFwaS52MS5HcmFwaE5vZGUSJgoFZWRnZXMYAiADKAsyFy5tZW1vcy5hcGkudjEuR3JhcGhFZGdlEicKBXN0YXRzGAMgASgLMhgubWVtb3MuYXBpLnYxLkdyYXBoU3RhdHMSEAoIYnVpbGRfbXMYBCABKAMiewoJR3JhcGhOb2RlEgoKAmlkGAEgASgJEg0KBWxhYmVsGAIgASgJEgwKBHR5cGUYAyABKAkSDAoEdGFncxgEIAMoCRISCgppbXBvcnRhbmNlGAUgASgBEg8KB2NsdXN0ZXIYBiABKAUSEgoKY3JlYXRlZF90cxgHIAEoAyJJCglHcmFwaEVkZ2USDgoGc291cmNlGAEgASgJEg4KBnRhcmdldBgCIAEoCRIMCgR0eXBlGAMgASgJEg4KBndlaWdodBgEIAEoASKKAQoKR3JhcGhTdGF0cxISCgpub2RlX2NvdW50GAEgASgFEhIKCmVkZ2VfY291bnQYAiABKAUSFQoNY2x1c3Rlcl9jb3VudBgDIAEoBRISCgpsaW5rX2VkZ2VzGAQgASgFEhEKCXRhZ19lZGdlcxgFIAEoBRIWCg5zZW1hbnRpY19lZGdlcxgGIAEoBSIlChRHZXREdWVSZXZpZXdzUmVxdWVzdBINCgVsaW1pdBgBIAEoBSJTChVHZXREdWVSZXZpZXdzUmVzcG9uc2USJwoFaXRlbXMYASADKAsyGC5tZW1vcy5hcGkudjEuUmV2aWV3SXRlbRIRCgl0b3RhbF9kdWUYAiABKAUiywEKClJldmlld0l0ZW0SEAoIbWVtb191aWQYASABKAkSEQoJbWVtb19uYW1lGAIgASgJEg0KBXRpdGxlGAMgASgJEg8KB3NuaXBwZXQYBCABKAkSDAoEdGFncxgFIAMoCRIWCg5sYXN0X3Jldmlld190cxgGIAEoAxIUCgxyZXZpZXdfY291bnQYByABKAUSFgoObmV4dF9yZXZpZXdfdHMYCCABKAMSEAoIcHJpb3JpdHkYCSABKAESEgoKY3JlYXRlZF90cxgKIAEoAyJfChNSZWNvcmRSZXZpZXdSZXF1ZXN0EhUKCG1lbW9fdWlkGAEgASgJQgPgQQISMQoHcXVhbGl0eRgCIAEoDjIbLm1lbW9zLmFwaS52MS5SZXZpZXdRdWFsaXR5QgPgQQIidQobUmVjb3JkUm91dGVyRmVlZGJhY2tSZXF1ZXN0EhIKBWlucHV0GAEgASgJQgPgQQISFgoJcHJlZGljdGVkGAIgASgJQgPgQQISEwoGYWN0dWFsGAMgASgJQgPgQQISFQoIZmVlZGJhY2sYBCABKAlCA+BBAiIXChVHZXRSZXZpZXdTdGF0c1JlcXVlc3QiyQEKFkdldFJldmlld1N0YXRzUmVzcG9uc2USEwoLdG90YWxfbWVtb3MYASABKAUSEQoJZHVlX3RvZGF5GAIgASgFEhYKDnJldmlld2VkX3RvZGF5GAMgASgFEhEKCW5ld19tZW1vcxgEIAEoBRIWCg5tYXN0ZXJlZF9tZW1vcxgFIAEoBRITCgtzdHJlYWtfZGF5cxgGIAEoBRIVCg10b3RhbF9yZXZpZXdzGAcgASgFEhgKEGF2ZXJhZ2VfYWNjdXJhY3kYCCABKAUiwAIKDUV2ZW50TWV0YWRhdGESEwoLZHVyYXRpb25fbXMYASABKAMSGQoRdG90YWxfZHVyYXRpb25fbXMYAiABKAMSEQoJdG9vbF9uYW1lGAMgASgJEg8KB3Rvb2xfaWQYBCABKAkSFAoMaW5wdXRfdG9rZW5zGAUgASgFEhUKDW91dHB1dF90b2tlbnMYBiABKAUSGgoSY2FjaGVfd3JpdGVfdG9rZW5zGAcgASgFEhkKEWNhY2hlX3JlYWRfdG9rZW5zGAggASgFEg4KBnN0YXR1cxgJIAEoCRIRCgllcnJvcl9tc2cYCiABKAkSFQoNaW5wdXRfc3VtbWFyeRgLIAEoCRIWCg5vdXRwdXRfc3VtbWFyeRgMIAEoCRIRCglmaWxlX3BhdGgYDSABKAkSEgoKbGluZV9jb3VudBgOIAEoBSKlAwoMQmxvY2tTdW1tYXJ5EhIKCnNlc3Npb25faWQYASABKAkSGQoRdG90YWxfZHVyYXRpb25fbXMYAiABKAMSHAoUdGhpbmtpbmdfZHVyYXRpb25fbXMYAyABKAMSGAoQdG9vbF9kdXJhdGlvbl9tcxgEIAEoAxIeChZnZW5lcmF0aW9uX2R1cmF0aW9uX21zGAUgASgDEhoKEnRvdGFsX2lucHV0X3Rva2VucxgGIAEoBRIbChN0b3RhbF9vdXRwdXRfdG9rZW5zGAcgASgFEiAKGHRvdGFsX2NhY2hlX3dyaXRlX3Rva2VucxgIIAEoBRIfChd0b3RhbF9jYWNoZV9yZWFkX3Rva2VucxgJIAEoBRIXCg90b29sX2NhbGxfY291bnQYCiABKAUSEgoKdG9vbHNfdXNlZBgLIAMoCRIWCg5maWxlc19tb2RpZmllZBgMIAEoBRISCgpmaWxlX3BhdGhzGA0gAygJEhYKDnRvdGFsX2Nvc3RfdXNkGBAgASgBEg4KBnN0YXR1cxgOIAEoCRIRCgllcnJvcl9tc2cYDyABKAki1QQKDFNlc3Npb25TdGF0cxIKCgJpZBgBIAEoAxISCgpzZXNzaW9uX2lkGAIgASgJEhcKD2NvbnZlcnNhdGlvbl9pZBgDIAEoAxIPCgd1c2VyX2lkGAQgASgFEhIKCmFnZW50X3R5cGUYBSABKAkSEgoKc3RhcnRlZF9hdBgGIAEoAxIQCghlbmRlZF9hdBgHIAEoAxIZChF0b3RhbF9kdXJhdGlvbl9tcxgIIAEoAxIcChR0aGlua2luZ19kdXJhdGlvbl9tcxgJIAEoAxIYChB0b29sX2R1cmF0aW9uX21zGAogASgDEh4KFmdlbmVyYXRpb25fZHVyYXRpb25fbXMYCyABKAMSFAoMaW5wdXRfdG9rZW5zGAwgASgFEhUKDW91dHB1dF90b2tlbnMYDSABKAUSGgoSY2FjaGVfd3JpdGVfdG9rZW5zGA4gASgFEhkKEWNhY2hlX3JlYWRfdG9rZW5zGA8gASgFEhQKDHRvdGFsX3Rva2VucxgQIAEoBRIWCg50b3RhbF9jb3N0X3VzZBgRIAEoARIXCg90b29sX2NhbGxfY291bnQYEiABKAUSEgoKdG9vbHNfdXNlZBgTIAMoCRIWCg5maWxlc19tb2RpZmllZBgUIAEoBRISCgpmaWxlX3BhdGhzGBUgAygJEhIKCm1vZGVsX3VzZWQYFiABKAkSEAoIaXNfZXJyb3IYFyABKAgSFQoNZXJyb3JfbWVzc2FnZRgYIAEoCRISCgpjcmVhdGVkX2F0GBkgASgDEhIKCnVwZGF0ZWRfYXQYGiABKAMiMQoWR2V0U2Vzc2lvblN0YXRzUmVxdWVzdBIXCgpzZXNzaW9uX2lkGAEgASgJQgPgQQIiRgoXTGlzdFNlc3Npb25TdGF0c1JlcXVlc3QSDQoFbGltaXQYASABKAUSDgoGb2Zmc2V0GAIgASgFEgwKBGRheXMYAyABKAUidQoYTGlzdFNlc3Npb25TdGF0c1Jlc3BvbnNlEiwKCHNlc3Npb25zGAEgAygLMhoubWVtb3MuYXBpLnYxLlNlc3Npb25TdGF0cxITCgt0b3RhbF9jb3VudBgCIAEoAxIWCg50b3RhbF9jb3N0X3VzZBgDIAEoASIjChNHZXRDb3N0U3RhdHNSZXF1ZXN0EgwKBGRheXMYASABKAUixwEKCUNvc3RTdGF0cxIWCg50b3RhbF9jb3N0X3VzZBgBIAEoARIZChFkYWlseV9hdmVyYWdlX3VzZBgCIAEoARIVCg1zZXNzaW9uX2NvdW50GAMgASgDEjoKFm1vc3RfZXhwZW5zaXZlX3Nlc3Npb24YBCABKAsyGi5tZW1vcy5hcGkudjEuU2Vzc2lvblN0YXRzEjQKD2RhaWx5X2JyZWFrZG93bhgFIAMoCzIbLm1lbW9zLmFwaS52MS5EYWlseUNvc3REYXRhIkYKDURhaWx5Q29zdERhdGESDAoEZGF0ZRgBIAEoCRIQCghjb3N0X3VzZBgCIAEoARIVCg1zZXNzaW9uX2NvdW50GAMgASgDIqoBChBVc2VyQ29zdFNldHRpbmdzEhgKEGRhaWx5X2J1ZGdldF91c2QYASABKAESIQoZcGVyX3Nlc3Npb25fdGhyZXNob2xkX3VzZBgCIAEoARIVCg1hbGVydF9lbmFibGVkGAMgASgIEhMKC2FsZXJ0X2VtYWlsGAQgASgIEhQKDGFsZXJ0X2luX2FwcBgFIAEoCBIXCg9idWRnZXRfcmVzZXRfYXQYBiABKAMimgIKGlNldFVzZXJDb3N0U2V0dGluZ3NSZXF1ZXN0Eh0KEGRhaWx5X2J1ZGdldF91c2QYASABKAFIAIgBARImChlwZXJfc2Vzc2lvbl90aHJlc2hvbGRfdXNkGAIgASgBSAGIAQESGgoNYWxlcnRfZW5hYmxlZBgDIAEoCEgCiAEBEhgKC2FsZXJ0X2VtYWlsGAQgASgISAOIAQESGQoMYWxlcnRfaW5fYXBwGAUgASgISASIAQFCEwoRX2RhaWx5X2J1ZGdldF91c2RCHAoaX3Blcl9zZXNzaW9uX3RocmVzaG9sZF91c2RCEAoOX2FsZXJ0X2VuYWJsZWRCDgoMX2FsZXJ0X2VtYWlsQg8KDV9hbGVydF9pbl9hcHAi0gUKBUJsb2NrEgoKAmlkGAEgASgDEgsKA3VpZBgCIAEoCRIXCg9jb252ZXJzYXRpb25faWQYAyABKAUSFAoMcm91bmRfbnVtYmVyGAQgASgFEisKCmJsb2NrX3R5cGUYBSABKA4yFy5tZW1vcy5hcGkudjEuQmxvY2tUeXBlEiUKBG1vZGUYBiABKA4yFy5tZW1vcy5hcGkudjEuQmxvY2tNb2RlEiwKC3VzZXJfaW5wdXRzGAcgAygLMhcubWVtb3MuYXBpLnYxLlVzZXJJbnB1dBIZChFhc3Npc3RhbnRfY29udGVudBgIIAEoCRIbChNhc3Npc3RhbnRfdGltZXN0YW1wGAkgASgDEi4KDGV2ZW50X3N0cmVhbRgKIAMoCzIYLm1lbW9zLmFwaS52MS5CbG9ja0V2ZW50EjEKDXNlc3Npb25fc3RhdHMYCyABKAsyGi5tZW1vcy5hcGkudjEuU2Vzc2lvblN0YXRzEhUKDWNjX3Nlc3Npb25faWQYDCABKAkSKQoGc3RhdHVzGA0gASgOMhkubWVtb3MuYXBpLnYxLkJsb2NrU3RhdHVzEhcKD3BhcmVudF9ibG9ja19pZBgOIAEoAxITCgticmFuY2hfcGF0aBgPIAEoCRItCgt0b2tlbl91c2FnZRgTIAEoCzIYLm1lbW9zLmFwaS52MS5Ub2tlblVzYWdlEhUKDWNvc3RfZXN0aW1hdGUYFCABKAMSFQoNbW9kZWxfdmVyc2lvbhgVIAEoCRIVCg11c2VyX2ZlZWRiYWNrGBYgASgJEhoKEnJlZ2VuZXJhdGlvbl9jb3VudBgXIAEoBRIVCg1lcnJvcl9tZXNzYWdlGBggASgJEhMKC2FyY2hpdmVkX2F0GBkgASgDEhAKCG1ldGFkYXRhGBAgASgJEhIKCmNyZWF0ZWRfdHMYESABKAMSEgoKdXBkYXRlZF90cxgSIAEoAyKLAQoKVG9rZW5Vc2FnZRIVCg1wcm9tcHRfdG9rZW5zGAEgASgFEhkKEWNvbXBsZXRpb25fdG9rZW5zGAIgASgFEhQKDHRvdGFsX3Rva2VucxgDIAEoBRIZChFjYWNoZV9yZWFkX3Rva2VucxgEIAEoBRIaChJjYWNoZV93cml0ZV90b2tlbnMYBSABKAUiQQoJVXNlcklucHV0Eg8KB2NvbnRlbnQYASABKAkSEQoJdGltZXN0YW1wGAIgASgDEhAKCG1ldGFkYXRhGAMgASgJIkwKCkJsb2NrRXZlbnQSDAoEdHlwZRgBIAEoCRIPCgdjb250ZW50GAIgASgJEhEKCXRpbWVzdGFtcBgDIAEoAxIMCgRtZXRhGAQgASgJIsEBChFMaXN0QmxvY2tzUmVxdWVzdBIcCg9jb252ZXJzYXRpb25faWQYASABKAVCA+BBAhIpCgZzdGF0dXMYAiABKA4yGS5tZW1vcy5hcGkudjEuQmxvY2tTdGF0dXMSJQoEbW9kZRgDIAEoDjIXLm1lbW9zLmFwaS52MS5CbG9ja01vZGUSFQoNY2Nfc2Vzc2lvbl9pZBgEIAEoCRINCgVsaW1pdBgFIAEoBRIWCg5sYXN0X2Jsb2NrX3VpZBgGIAEoCSKRAQoSTGlzdEJsb2Nrc1Jlc3BvbnNlEiMKBmJsb2NrcxgBIAMoCzITLm1lbW9zLmFwaS52MS5CbG9jaxIQCghoYXNfbW9yZRgCIAEoCBITCgt0b3RhbF9jb3VudBgDIAEoBRIYChBsYXRlc3RfYmxvY2tfdWlkGAQgASgJEhUKDXN5bmNfcmVxdWlyZWQYBSABKAgiIgoPR2V0QmxvY2tSZXF1ZXN0Eg8KAmlkGAEgASgDQgPgQQIi3QEKEkNyZWF0ZUJsb2NrUmVxdWVzdBIcCg9jb252ZXJzYXRpb25faWQYASABKAVCA+BBAhIrCgpibG9ja190eXBlGAIgASgOMhcubWVtb3MuYXBpLnYxLkJsb2NrVHlwZRIlCgRtb2RlGAMgASgOMhcubWVtb3MuYXBpLnYxLkJsb2NrTW9kZRIsCgt1c2VyX2lucHV0cxgEIAMoCzIXLm1lbW9zLmFwaS52MS5Vc2VySW5wdXQSEAoIbWV0YWRhdGEYBSABKAkSFQoNY2Nfc2Vzc2lvbl9pZBgGIAEoCSK5AgoSVXBkYXRlQmxvY2tSZXF1ZXN0Eg8KAmlkGAEgASgDQgPgQQISHgoRYXNzaXN0YW50X2NvbnRlbnQYAiABKAlIAIgBARIuCgxldmVudF9zdHJlYW0YAyADKAsyGC5tZW1vcy5hcGkudjEuQmxvY2tFdmVudBIxCg1zZXNzaW9uX3N0YXRzGAQgASgLMhoubWVtb3MuYXBpLnYxLlNlc3Npb25TdGF0cxIaCg1jY19zZXNzaW9uX2lkGAUgASgJSAGIAQESLgoGc3RhdHVzGAYgASgOMhkubWVtb3MuYXBpLnYxLkJsb2NrU3RhdHVzSAKIAQESEAoIbWV0YWRhdGEYByABKAlCFAoSX2Fzc2lzdGFudF9jb250ZW50QhAKDl9jY19zZXNzaW9uX2lkQgkKB19zdGF0dXMiJQoSRGVsZXRlQmxvY2tSZXF1ZXN0Eg8KAmlkGAEgASgDQgPgQQIiVgoWQXBwZW5kVXNlcklucHV0UmVxdWVzdBIPCgJpZBgBIAEoA0ID4EECEisKBWlucHV0GAIgASgLMhcubWVtb3MuYXBpLnYxLlVzZXJJbnB1dEID4EECIlMKEkFwcGVuZEV2ZW50UmVxdWVzdBIPCgJpZBgBIAEoA0ID4EECEiwKBWV2ZW50GAIgASgLMhgubWVtb3MuYXBpLnYxLkJsb2NrRXZlbnRCA+BBAiJ5ChBGb3JrQmxvY2tSZXF1ZXN0Eg8KAmlkGAEgASgDQgPgQQISEwoGcmVhc29uGAIgASgJSACIAQESNAoTcmVwbGFjZV91c2VyX2lucHV0cxgDIAMoCzIXLm1lbW9zLmFwaS52MS5Vc2VySW5wdXRCCQoHX3JlYXNvbiIrChhMaXN0QmxvY2tCcmFuY2hlc1JlcXVlc3QSDwoCaWQYASABKANCA+BBAiJkChlMaXN0QmxvY2tCcmFuY2hlc1Jlc3BvbnNlEisKCGJyYW5jaGVzGAEgAygLMhkubWVtb3MuYXBpLnYxLkJsb2NrQnJhbmNoEhoKEmFjdGl2ZV9icmFuY2hfcGF0aBgCIAEoCSKGAQoLQmxvY2tCcmFuY2gSIgoFYmxvY2sYASABKAsyEy5tZW1vcy5hcGkudjEuQmxvY2sSEwoLYnJhbmNoX3BhdGgYAiABKAkSEQoJaXNfYWN0aXZlGAMgASgIEisKCGNoaWxkcmVuGAQgAygLMhkubWVtb3MuYXBpLnYxLkJsb2NrQnJhbmNoIlQKE1N3aXRjaEJyYW5jaFJlcXVlc3QSHAoPY29udmVyc2F0aW9uX2lkGAEgASgFQgPgQQISHwoSdGFyZ2V0X2JyYW5jaF9wYXRoGAIgASgJQgPgQQIiNwoTRGVsZXRlQnJhbmNoUmVxdWVzdBIPCgJpZBgBIAEoA0ID4EECEg8KB2Nhc2NhZGUYAiABKAgiEQoPR2V0VXNhZ2VSZXF1ZXN0IrUBCgVVc2FnZRIUCgxwZXJpb2Rfc3RhcnQYASABKAMSEgoKcGVyaW9kX2VuZBgCIAEoAxIUCgx0b3RhbF90b2tlbnMYAyABKAMSFgoOdG90YWxfY29zdF91c2QYBCABKAESFQoNc2Vzc2lvbl9jb3VudBgFIAEoAxITCgt0b2tlbl9xdW90YRgGIAEoAxIWCg5jb3N0X3F1b3RhX3VzZBgHIAEoARIQCghleGNlZWRlZBgIIAEoCCIiChJSdW5TZWxmVGVzdFJlcXVlc3QSDAoEbGl2ZRgBIAEoCCJSCg1TZWxmVGVzdENoZWNrEgwKBG5hbWUYASABKAkSDgoGcGFzc2VkGAIgASgIEg4KBmRldGFpbBgDIAEoCRITCgtkdXJhdGlvbl9tcxgEIAEoAyJwCg5TZWxmVGVzdFJlcG9ydBIOCgZwYXNzZWQYASABKAgSDAoEbW9kZRgCIAEoCRIrCgZjaGVja3MYAyADKAsyGy5tZW1vcy5hcGkudjEuU2VsZlRlc3RDaGVjaxITCgtkdXJhdGlvbl9tcxgEIAEoAyJIChZHZXRCbG9ja0NoYW5nZXNSZXF1ZXN0EhwKD2NvbnZlcnNhdGlvbl9pZBgBIAEoBUID4EECEhAKCHNpbmNlX3RzGAIgASgDIlgKC0Jsb2NrQ2hhbmdlEiIKBWJsb2NrGAEgASgLMhMubWVtb3MuYXBpLnYxLkJsb2NrEg8KB2NyZWF0ZWQYAiABKAgSFAoMZXZlbnRfb2Zmc2V0GAMgASgFImoKF0dldEJsb2NrQ2hhbmdlc1Jlc3BvbnNlEioKB2NoYW5nZXMYASADKAsyGS5tZW1vcy5hcGkudjEuQmxvY2tDaGFuZ2USEgoKYmxvY2tfdWlkcxgCIAMoCRIPCgdzeW5jX3RzGAMgASgDIlwKGUdldEJsb2NrVHJhbnNjcmlwdFJlcXVlc3QSDwoCaWQYASABKANCA+BBAhIuCgZkZXRhaWwYAiABKA4yHi5tZW1vcy5hcGkudjEuVHJhbnNjcmlwdERldGFpbCLjAgoPQmxvY2tUcmFuc2NyaXB0EhAKCGJsb2NrX2lkGAEgASgDEhcKD2NvbnZlcnNhdGlvbl9pZBgCIAEoBRIuCgZkZXRhaWwYAyABKA4yHi5tZW1vcy5hcGkudjEuVHJhbnNjcmlwdERldGFpbBIOCgZzdGF0dXMYBCABKAkSEwoLdXNlcl9pbnB1dHMYBSADKAkSDgoGYW5zd2VyGAYgASgJEisKBXRvb2xzGAcgAygLMhwubWVtb3MuYXBpLnYxLlRyYW5zY3JpcHRUb29sEi4KB2VudHJpZXMYCCADKAsyHS5tZW1vcy5hcGkudjEuVHJhbnNjcmlwdEVudHJ5Eg0KBWVycm9yGAkgASgJEi4KBnNvdXJjZRgKIAEoCzIeLm1lbW9zLmFwaS52MS5UcmFuc2NyaXB0U291cmNlEhAKCG1hcmtkb3duGAsgASgJEhIKCmNyZWF0ZWRfdHMYDCABKAMicAoOVHJhbnNjcmlwdFRvb2wSDAoEbmFtZRgBIAEoCRIMCgRsaW5lGAIgASgJEg4KBmZhaWxlZBgDIAEoCBITCgtkdXJhdGlvbl9tcxgEIAEoAxINCgVpbnB1dBgFIAEoCRIOCgZvdXRwdXQYBiABKAkiXAoPVHJhbnNjcmlwdEVudHJ5EgwKBHR5cGUYASABKAkSDwoHY29udGVudBgCIAEoCRIqCgR0b29sGAMgASgLMhwubWVtb3MuYXBpLnYxLlRyYW5zY3JpcHRUb29sIn0KEFRyYW5zY3JpcHRTb3VyY2USDAoEa2luZBgBIAEoCRILCgN1aWQYAiABKAkSDQoFdGl0bGUYAyABKAkSMQoIcGFzc2FnZXMYBCADKAsyHy5tZW1vcy5hcGkudjEuVHJhbnNjcmlwdFBhc3NhZ2USDAoEdGV4dBgFIAEoCSI9ChFUcmFuc2NyaXB0UGFzc2FnZRINCgVzdGFydBgBIAEoBRILCgNlbmQYAiABKAUSDAoEdGV4dBgDIAEoCSITChFSZWluZGV4QWxsUmVxdWVzdCIpChNSZWluZGV4U2luY2VSZXF1ZXN0EhIKBXNpbmNlGAEgASgDQgPgQQIiGwoZR2V0UmVpbmRleFByb2dyZXNzUmVxdWVzdCKqAQoPUmVpbmRleFByb2dyZXNzEg0KBXNpbmNlGAEgASgDEg8KB3J1bm5pbmcYAiABKAgSDQoFdG90YWwYAyABKAUSEQoJcHJvY2Vzc2VkGAQgASgFEg4KBmZhaWxlZBgFIAEoBRINCgVtb2RlbBgGIAEoCRISCgpzdGFydGVkX3RzGAcgASgDEhMKC2ZpbmlzaGVkX3RzGAggASgDEg0KBWVycm9yGAkgASgJIsYBCglNQ1BTZXJ2ZXISCgoCaWQYASABKAUSDAoEbmFtZRgCIAEoCRIRCgl0cmFuc3BvcnQYAyABKAkSDwoHY29tbWFuZBgEIAEoCRIMCgRhcmdzGAUgAygJEgsKA3VybBgGIAEoCRIPCgdlbmFibGVkGAcgASgIEhEKCWVudl9uYW1lcxgIIAMoCRIUCgxoZWFkZXJfbmFtZXMYCSADKAkSEgoKY3JlYXRlZF90cxgKIAEoAxISCgp1cGRhdGVkX3RzGAsgASgDIhcKFUxpc3RNQ1BTZXJ2ZXJzUmVxdWVzdCJCChZMaXN0TUNQU2VydmVyc1Jlc3BvbnNlEigKB3NlcnZlcnMYASADKAsyFy5tZW1vcy5hcGkudjEuTUNQU2VydmVyIu0CChZDcmVhdGVNQ1BTZXJ2ZXJSZXF1ZXN0EhEKBG5hbWUYASABKAlCA+BBAhIWCgl0cmFuc3BvcnQYAiABKAlCA+BBAhIPCgdjb21tYW5kGAMgASgJEgwKBGFyZ3MYBCADKAkSOgoDZW52GAUgAygLMi0ubWVtb3MuYXBpLnYxLkNyZWF0ZU1DUFNlcnZlclJlcXVlc3QuRW52RW50cnkSCwoDdXJsGAYgASgJEkIKB2hlYWRlcnMYByADKAsyMS5tZW1vcy5hcGkudjEuQ3JlYXRlTUNQU2VydmVyUmVxdWVzdC5IZWFkZXJzRW50cnkSFAoHZW5hYmxlZBgIIAEoCEgAiAEBGioKCEVudkVudHJ5EgsKA2tleRgBIAEoCRINCgV2YWx1ZRgCIAEoCToCOAEaLgoMSGVhZGVyc0VudHJ5EgsKA2tleRgBIAEoCRINCgV2YWx1ZRgCIAEoCToCOAFCCgoIX2VuYWJsZWQiqAMKFlVwZGF0ZU1DUFNlcnZlclJlcXVlc3QSDwoCaWQYASABKAVCA+BBAhIRCgRuYW1lGAIgASgJQgPgQQISFgoJdHJhbnNwb3J0GAMgASgJQgPgQQISDwoHY29tbWFuZBgEIAEoCRIMCgRhcmdzGAUgAygJEjoKA2VudhgGIAMoCzItLm1lbW9zLmFwaS52MS5VcGRhdGVNQ1BTZXJ2ZXJSZXF1ZXN0LkVudkVudHJ5EgsKA3VybBgHIAEoCRJCCgdoZWFkZXJzGAggAygLMjEubWVtb3MuYXBpLnYxLlVwZGF0ZU1DUFNlcnZlclJlcXVlc3QuSGVhZGVyc0VudHJ5EhQKB2VuYWJsZWQYCSABKAhIAIgBARIRCgljbGVhcl9lbnYYCiABKAgSFQoNY2xlYXJfaGVhZGVycxgLIAEoCBoqCghFbnZFbnRyeRILCgNrZXkYASABKAkSDQoFdmFsdWUYAiABKAk6AjgBGi4KDEhlYWRlcnNFbnRyeRILCgNrZXkYASABKAkSDQoFdmFsdWUYAiABKAk6AjgBQgoKCF9lbmFibGVkIikKFkRlbGV0ZU1DUFNlcnZlclJlcXVlc3QSDwoCaWQYASABKAVCA+BBAiJmChlJbXBvcnRDb252ZXJzYXRpb25SZXF1ZXN0EjYKBmZvcm1hdBgBIAEoDjImLm1lbW9zLmFwaS52MS5Db252ZXJzYXRpb25JbXBvcnRGb3JtYXQSEQoEZGF0YRgCIAEoCUID4EECImgKGkltcG9ydENvbnZlcnNhdGlvblJlc3BvbnNlEjkKDWNvbnZlcnNhdGlvbnMYASADKAsyIi5tZW1vcy5hcGkudjEuSW1wb3J0ZWRDb252ZXJzYXRpb24SDwoHc2tpcHBlZBgCIAEoBSJCChRJbXBvcnRlZENvbnZlcnNhdGlvbhILCgN1aWQYASABKAkSDQoFdGl0bGUYAiABKAkSDgoGcm91bmRzGAMgASgFIt0BChFBY3Rpb25BdWRpdFJlY29yZBIKCgJpZBgBIAEoAxIPCgd1c2VyX2lkGAIgASgFEgwKBG1vZGUYAyABKAkSEgoKc2Vzc2lvbl9pZBgEIAEoCRIMCgR0b29sGAUgASgJEg8KB3Rvb2xfaWQYBiABKAkSDQoFaW5wdXQYByABKAkSEgoKZmlsZV9wYXRocxgIIAMoCRIOCgZzdGF0dXMYCSABKAkSDgoGb3V0cHV0GAogASgJEhMKC2R1cmF0aW9uX21zGAsgASgDEhIKCmNyZWF0ZWRfdHMYDCABKAMioAEKEUFjdGlvbkF1ZGl0RmlsdGVyEg8KB3VzZXJfaWQYASABKAUSDAoEbW9kZRgCIAEoCRISCgpzZXNzaW9uX2lkGAMgASgJEgwKBHRvb2wYBCABKAkSDgoGc3RhdHVzGAUgASgJEgwKBGZyb20YBiABKAMSCgoCdG8YByABKAMSEQoJYmVmb3JlX2lkGAggASgDEg0KBWxpbWl0GAkgASgFIkkKFkxpc3RBY3Rpb25BdWRpdFJlcXVlc3QSLwoGZmlsdGVyGAEgASgLMh8ubWVtb3MuYXBpLnYxLkFjdGlvbkF1ZGl0RmlsdGVyImMKF0xpc3RBY3Rpb25BdWRpdFJlc3BvbnNlEjAKB3JlY29yZHMYASADKAsyHy5tZW1vcy5hcGkudjEuQWN0aW9uQXVkaXRSZWNvcmQSFgoObmV4dF9iZWZvcmVfaWQYAiABKAMiggEKGEV4cG9ydEFjdGlvbkF1ZGl0UmVxdWVzdBIvCgZmaWx0ZXIYASABKAsyHy5tZW1vcy5hcGkudjEuQWN0aW9uQXVkaXRGaWx0ZXISNQoGZm9ybWF0GAIgASgOMiUubWVtb3MuYXBpLnYxLkFjdGlvbkF1ZGl0RXhwb3J0Rm9ybWF0IlEKGUV4cG9ydEFjdGlvbkF1ZGl0UmVzcG9uc2USDAoEZGF0YRgBIAEoDBIQCghmaWxlbmFtZRgCIAEoCRIUCgxjb250ZW50X3R5cGUYAyABKAkiTgoLTExNUHJvdmlkZXISDAoEbmFtZRgBIAEoCRIQCghwcm92aWRlchgCIAEoCRIQCghiYXNlX3VybBgDIAEoCRINCgVtb2RlbBgEIAEoCSJgCgpBZ2VudE1vZGVsEg0KBWFnZW50GAEgASgJEhAKCHByb3ZpZGVyGAIgASgJEg0KBW1vZGVsGAMgASgJEg4KBnNvdXJjZRgEIAEoCRISCgp1cGRhdGVkX3RzGAUgASgDIhgKFkxpc3RBZ2VudE1vZGVsc1JlcXVlc3QicQoXTGlzdEFnZW50TW9kZWxzUmVzcG9uc2USLAoJcHJvdmlkZXJzGAEgAygLMhkubWVtb3MuYXBpLnYxLkxMTVByb3ZpZGVyEigKBmFnZW50cxgCIAMoCzIYLm1lbW9zLmFwaS52MS5BZ2VudE1vZGVsIkkKF1VwZGF0ZUFnZW50TW9kZWxSZXF1ZXN0Eg0KBWFnZW50GAEgASgJEhAKCHByb3ZpZGVyGAIgASgJEg0KBW1vZGVsGAMgASgJIigKF0RlbGV0ZUFnZW50TW9kZWxSZXF1ZXN0Eg0KBWFnZW50GAEgASgJIlwKEkdlZWtXb3Jrc3BhY2VVc2FnZRINCgVieXRlcxgBIAEoAxINCgVmaWxlcxgCIAEoBRITCgtsaW1pdF9ieXRlcxgDIAEoAxITCgttZWFzdXJlZF90cxgEIAEoAyJ0ChJHZWVrV29ya3NwYWNlRW50cnkSDAoEbmFtZRgBIAEoCRIMCgRwYXRoGAIgASgJEg4KBmlzX2RpchgDIAEoCBIPCgdzeW1saW5rGAQgASgIEgwKBHNpemUYBSABKAMSEwoLbW9kaWZpZWRfdHMYBiABKAMiHgocR2V0R2Vla1dvcmtzcGFjZVVzYWdlUmVxdWVzdCItCh1MaXN0R2Vla1dvcmtzcGFjZUZpbGVzUmVxdWVzdBIMCgRwYXRoGAEgASgJInQKHkxpc3RHZWVrV29ya3NwYWNlRmlsZXNSZXNwb25zZRIMCgRwYXRoGAEgASgJEjEKB2VudHJpZXMYAiADKAsyIC5tZW1vcy5hcGkudjEuR2Vla1dvcmtzcGFjZUVudHJ5EhEKCXRydW5jYXRlZBgDIAEoCCIsChxSZWFkR2Vla1dvcmtzcGFjZUZpbGVSZXF1ZXN0EgwKBHBhdGgYASABKAkikgEKGEdlZWtXb3Jrc3BhY2VGaWxlQ29udGVudBIvCgVlbnRyeRgBIAEoCzIgLm1lbW9zLmFwaS52MS5HZWVrV29ya3NwYWNlRW50cnkSEQoJbWltZV90eXBlGAIgASgJEg4KBmJpbmFyeRgDIAEoCBIRCgl0cnVuY2F0ZWQYBCABKAgSDwoHY29udGVudBgFIAEoCSIwCiBEb3dubG9hZEdlZWtXb3Jrc3BhY2VGaWxlUmVxdWVzdBIMCgRwYXRoGAEgASgJIlkKIURvd25sb2FkR2Vla1dvcmtzcGFjZUZpbGVSZXNwb25zZRIMCgRkYXRhGAEgASgMEhAKCGZpbGVuYW1lGAIgASgJEhQKDGNvbnRlbnRfdHlwZRgDIAEoCSIuCh5EZWxldGVHZWVrV29ya3NwYWNlRmlsZVJlcXVlc3QSDAoEcGF0aBgBIAEoCSo3ChFTY2hlZHVsZVF1ZXJ5TW9kZRIICgRBVVRPEAASDAoIU1RBTkRBUkQQARIKCgZTVFJJQ1QQAiqIAQoJQWdlbnRUeXBlEhYKEkFHRU5UX1RZUEVfREVGQVVMVBAAEhMKD0FHRU5UX1RZUEVfTUVNTxABEhcKE0FHRU5UX1RZUEVfU0NIRURVTEUQAhIWChJBR0VOVF9UWVBFX0dFTkVSQUwQAxIXChNBR0VOVF9UWVBFX0lERUFUSU9OEAUiBAgEEAQqlAEKDVJldmlld1F1YWxpdHkSHgoaUkVWSUVXX1FVQUxJVFlfVU5TUEVDSUZJRUQQABIYChRSRVZJRVdfUVVBTElUWV9BR0FJThABEhcKE1JFVklFV19RVUFMSVRZX0hBUkQQAhIXChNSRVZJRVdfUVVBTElUWV9HT09EEAMSFwoTUkVWSUVXX1FVQUxJVFlfRUFTWRAEKmEKCUJsb2NrVHlwZRIaChZCTE9DS19UWVBFX1VOU1BFQ0lGSUVEEAASFgoSQkxPQ0tfVFlQRV9NRVNTQUdFEAESIAocQkxPQ0tfVFlQRV9DT05URVhUX1NFUEFSQVRPUhACKm0KCUJsb2NrTW9kZRIaChZCTE9DS19NT0RFX1VOU1BFQ0lGSUVEEAASFQoRQkxPQ0tfTU9ERV9OT1JNQUwQARITCg9CTE9DS19NT0RFX0dFRUsQAhIYChRCTE9DS19NT0RFX0VWT0xVVElPThADKrMBCgtCbG9ja1N0YXR1cxIcChhCTE9DS19TVEFUVVNfVU5TUEVDSUZJRUQQABIYChRCTE9DS19TVEFUVVNfUEVORElORxABEhoKFkJMT0NLX1NUQVRVU19TVFJFQU1JTkcQAhIaChZCTE9DS19TVEFUVVNfQ09NUExFVEVEEAMSFgoSQkxPQ0tfU1RBVFVTX0VSUk9SEAQSHAoYQkxPQ0tfU1RBVFVTX0lOVEVSUlVQVEVEEAUqcAoQVHJhbnNjcmlwdERldGFpbBIhCh1UUkFOU0NSSVBUX0RFVEFJTF9VTlNQRUNJRklFRBAAEh0KGVRSQU5TQ1JJUFRfREVUQUlMX01JTklNQUwQARIaChZUUkFOU0NSSVBUX0RFVEFJTF9GVUxMEAIqlQEKGENvbnZlcnNhdGlvbkltcG9ydEZvcm1hdBIqCiZDT05WRVJTQVRJT05fSU1QT1JUX0ZPUk1BVF9VTlNQRUNJRklFRBAAEiYKIkNPTlZFUlNBVElPTl9JTVBPUlRfRk9STUFUX0NIQVRHUFQQARIlCiFDT05WRVJTQVRJT05fSU1QT1JUX0ZPUk1BVF9DTEFVREUQAiqPAQoXQWN0aW9uQXVkaXRFeHBvcnRGb3JtYXQSKgomQUNUSU9OX0FVRElUX0VYUE9SVF9GT1JNQVRfVU5TUEVDSUZJRUQQABIiCh5BQ1RJT05fQVVESVRfRVhQT1JUX0ZPUk1BVF9DU1YQARIkCiBBQ1RJT05fQVVESVRfRVhQT1JUX0ZPUk1BVF9KU09OTBACMtZBCglBSVNlcnZpY2USeQoOU2VtYW50aWNTZWFyY2gSIy5tZW1vcy5hcGkudjEuU2VtYW50aWNTZWFyY2hSZXF1ZXN0GiQubWVtb3MuYXBpLnYxLlNlbWFudGljU2VhcmNoUmVzcG9uc2UiHILT5JMCFjoBKiIRL2FwaS92MS9haS9zZWFyY2gSdgoLU3VnZ2VzdFRhZ3MSIC5tZW1vcy5hcGkudjEuU3VnZ2VzdFRhZ3NSZXF1ZXN0GiEubWVtb3MuYXBpLnYxLlN1Z2dlc3RUYWdzUmVzcG9uc2UiIoLT5JMCHDoBKiIXL2FwaS92MS9haS9zdWdnZXN0LXRhZ3MSYQoGRm9ybWF0EhsubWVtb3MuYXBpLnYxLkZvcm1hdFJlcXVlc3QaHC5tZW1vcy5hcGkudjEuRm9ybWF0UmVzcG9uc2UiHILT5JMCFjoBKiIRL2FwaS92MS9haS9mb3JtYXQSZQoHU3VtbWFyeRIcLm1lbW9zLmFwaS52MS5TdW1tYXJ5UmVxdWVzdBodLm1lbW9zLmFwaS52MS5TdW1tYXJ5UmVzcG9uc2UiHYLT5JMCFzoBKiISL2FwaS92MS9haS9zdW1tYXJ5ElsKBENoYXQSGS5tZW1vcy5hcGkudjEuQ2hhdFJlcXVlc3QaGi5tZW1vcy5hcGkudjEuQ2hhdFJlc3BvbnNlIhqC0+STAhQ6ASoiDy9hcGkvdjEvYWkvY2hhdDABEoYBCg9HZXRSZWxhdGVkTWVtb3MSJC5tZW1vcy5hcGkudjEuR2V0UmVsYXRlZE1lbW9zUmVxdWVzdBolLm1lbW9zLmFwaS52MS5HZXRSZWxhdGVkTWVtb3NSZXNwb25zZSImgtPkkwIgEh4vYXBpL3YxL3tuYW1lPW1lbW9zLyp9L3JlbGF0ZWQSqwEKFkdldFBhcnJvdFNlbGZDb2duaXRpb24SKy5tZW1vcy5hcGkudjEuR2V0UGFycm90U2VsZkNvZ25pdGlvblJlcXVlc3QaLC5tZW1vcy5hcGkudjEuR2V0UGFycm90U2VsZkNvZ25pdGlvblJlc3BvbnNlIjaC0+STAjASLi9hcGkvdjEvYWkvcGFycm90cy97YWdlbnRfdHlwZX0vc2VsZi1jb2duaXRpb24SbgoLTGlzdFBhcnJvdHMSIC5tZW1vcy5hcGkudjEuTGlzdFBhcnJvdHNSZXF1ZXN0GiEubWVtb3MuYXBpLnYxLkxpc3RQYXJyb3RzUmVzcG9uc2UiGoLT5JMCFBISL2FwaS92MS9haS9wYXJyb3RzEooBChBEZXRlY3REdXBsaWNhdGVzEiUubWVtb3MuYXBpLnYxLkRldGVjdER1cGxpY2F0ZXNSZXF1ZXN0GiYubWVtb3MuYXBpLnYxLkRldGVjdER1cGxpY2F0ZXNSZXNwb25zZSIngtPkkwIhOgEqIhwvYXBpL3YxL2FpL2RldGVjdC1kdXBsaWNhdGVzEnIKCk1lcmdlTWVtb3MSHy5tZW1vcy5hcGkudjEuTWVyZ2VNZW1vc1JlcXVlc3QaIC5tZW1vcy5hcGkudjEuTWVyZ2VNZW1vc1Jlc3BvbnNlIiGC0+STAhs6ASoiFi9hcGkvdjEvYWkvbWVyZ2UtbWVtb3MSbgoJTGlua01lbW9zEh4ubWVtb3MuYXBpLnYxLkxpbmtNZW1vc1JlcXVlc3QaHy5tZW1vcy5hcGkudjEuTGlua01lbW9zUmVzcG9uc2UiIILT5JMCGjoBKiIVL2FwaS92MS9haS9saW5rLW1lbW9zEogBChFHZXRLbm93bGVkZ2VHcmFwaBImLm1lbW9zLmFwaS52MS5HZXRLbm93bGVkZ2VHcmFwaFJlcXVlc3QaJy5tZW1vcy5hcGkudjEuR2V0S25vd2xlZGdlR3JhcGhSZXNwb25zZSIigtPkkwIcEhovYXBpL3YxL2FpL2tub3dsZWRnZS1ncmFwaBJ4Cg1HZXREdWVSZXZpZXdzEiIubWVtb3MuYXBpLnYxLkdldER1ZVJldmlld3NSZXF1ZXN0GiMubWVtb3MuYXBpLnYxLkdldER1ZVJldmlld3NSZXNwb25zZSIegtPkkwIYEhYvYXBpL3YxL2FpL3Jldmlld3MvZHVlEnoKDFJlY29yZFJldmlldxIhLm1lbW9zLmFwaS52MS5SZWNvcmRSZXZpZXdSZXF1ZXN0GhYuZ29vZ2xlLnByb3RvYnVmLkVtcHR5Ii+C0+STAik6ASoiJC9hcGkvdjEvYWkvcmV2aWV3cy97bWVtb191aWR9L3JlY29yZBKBAQoUUmVjb3JkUm91dGVyRmVlZGJhY2sSKS5tZW1vcy5hcGkudjEuUmVjb3JkUm91dGVyRmVlZGJhY2tSZXF1ZXN0GhYuZ29vZ2xlLnByb3RvYnVmLkVtcHR5IiaC0+STAiA6ASoiGy9hcGkvdjEvYWkvcm91dGluZy9mZWVkYmFjaxJ9Cg5HZXRSZXZpZXdTdGF0cxIjLm1lbW9zLmFwaS52MS5HZXRSZXZpZXdTdGF0c1JlcXVlc3QaJC5tZW1vcy5hcGkudjEuR2V0UmV2aWV3U3RhdHNSZXNwb25zZSIggtPkkwIaEhgvYXBpL3YxL2FpL3Jldmlld3Mvc3RhdHMSjAEKE0xpc3RBSUNvbnZlcnNhdGlvbnMSKC5tZW1vcy5hcGkudjEuTGlzdEFJQ29udmVyc2F0aW9uc1JlcXVlc3QaKS5tZW1vcy5hcGkudjEuTGlzdEFJQ29udmVyc2F0aW9uc1Jlc3BvbnNlIiCC0+STAhoSGC9hcGkvdjEvYWkvY29udmVyc2F0aW9ucxKAAQoRR2V0QUlDb252ZXJzYXRpb24SJi5tZW1vcy5hcGkudjEuR2V0QUlDb252ZXJzYXRpb25SZXF1ZXN0GhwubWVtb3MuYXBpLnYxLkFJQ29udmVyc2F0aW9uIiWC0+STAh8SHS9hcGkvdjEvYWkvY29udmVyc2F0aW9ucy97aWR9EoQBChRDcmVhdGVBSUNvbnZlcnNhdGlvbhIpLm1lbW9zLmFwaS52MS5DcmVhdGVBSUNvbnZlcnNhdGlvblJlcXVlc3QaHC5tZW1vcy5hcGkudjEuQUlDb252ZXJzYXRpb24iI4LT5JMCHToBKiIYL2FwaS92MS9haS9jb252ZXJzYXRpb25zEokBChRVcGRhdGVBSUNvbnZlcnNhdGlvbhIpLm1lbW9zLmFwaS52MS5VcGRhdGVBSUNvbnZlcnNhdGlvblJlcXVlc3QaHC5tZW1vcy5hcGkudjEuQUlDb252ZXJzYXRpb24iKILT5JMCIjoBKjIdL2FwaS92MS9haS9jb252ZXJzYXRpb25zL3tpZH0StQEKGUdlbmVyYXRlQ29udmVyc2F0aW9uVGl0bGUSLi5tZW1vcy5hcGkudjEuR2VuZXJhdGVDb252ZXJzYXRpb25UaXRsZVJlcXVlc3QaLy5tZW1vcy5hcGkudjEuR2VuZXJhdGVDb252ZXJzYXRpb25UaXRsZVJlc3BvbnNlIjeC0+STAjE6ASoiLC9hcGkvdjEvYWkvY29udmVyc2F0aW9ucy97aWR9L2dlbmVyYXRlLXRpdGxlEpMBChJJbXBvcnRDb252ZXJzYXRpb24SJy5tZW1vcy5hcGkudjEuSW1wb3J0Q29udmVyc2F0aW9uUmVxdWVzdBooLm1lbW9zLmFwaS52MS5JbXBvcnRDb252ZXJzYXRpb25SZXNwb25zZSIqgtPkkwIkOgEqIh8vYXBpL3YxL2FpL2NvbnZlcnNhdGlvbnMvaW1wb3J0EoABChREZWxldGVBSUNvbnZlcnNhdGlvbhIpLm1lbW9zLmFwaS52MS5EZWxldGVBSUNvbnZlcnNhdGlvblJlcXVlc3QaFi5nb29nbGUucHJvdG9idWYuRW1wdHkiJYLT5JMCHyodL2FwaS92MS9haS9jb252ZXJzYXRpb25zL3tpZH0SmAEKE0FkZENvbnRleHRTZXBhcmF0b3ISKC5tZW1vcy5hcGkudjEuQWRkQ29udGV4dFNlcGFyYXRvclJlcXVlc3QaFi5nb29nbGUucHJvdG9idWYuRW1wdHkiP4LT5JMCOToBKiI0L2FwaS92MS9haS9jb252ZXJzYXRpb25zL3tjb252ZXJzYXRpb25faWR9L3NlcGFyYXRvchKgAQoZQ2xlYXJDb252ZXJzYXRpb25NZXNzYWdlcxIuLm1lbW9zLmFwaS52MS5DbGVhckNvbnZlcnNhdGlvbk1lc3NhZ2VzUmVxdWVzdBoWLmdvb2dsZS5wcm90b2J1Zi5FbXB0eSI7gtPkkwI1KjMvYXBpL3YxL2FpL2NvbnZlcnNhdGlvbnMve2NvbnZlcnNhdGlvbl9pZH0vbWVzc2FnZXMSkAEKFkNyZWF0ZUNoYXRTdHJlYW1UaWNrZXQSKy5tZW1vcy5hcGkudjEuQ3JlYXRlQ2hhdFN0cmVhbVRpY2tldFJlcXVlc3QaHi5tZW1vcy5hcGkudjEuQ2hhdFN0cmVhbVRpY2tldCIpgtPkkwIjOgEqIh4vYXBpL3YxL2FpL2NoYXQvc3RyZWFtLXRpY2tldHMSYgoIU3RvcENoYXQSHS5tZW1vcy5hcGkudjEuU3RvcENoYXRSZXF1ZXN0GhYuZ29vZ2xlLnByb3RvYnVmLkVtcHR5Ih+C0+STAhk6ASoiFC9hcGkvdjEvYWkvY2hhdC9zdG9wEnkKClN1Ym1pdEZvcm0SHy5tZW1vcy5hcGkudjEuU3VibWl0Rm9ybVJlcXVlc3QaGi5tZW1vcy5hcGkudjEuQ2hhdFJlc3BvbnNlIiyC0+STAiY6ASoiIS9hcGkvdjEvYWkvYmxvY2tzL3tibG9ja19pZH0vZm9ybTABEn0KD0dldFNlc3Npb25TdGF0cxIkLm1lbW9zLmFwaS52MS5HZXRTZXNzaW9uU3RhdHNSZXF1ZXN0GhoubWVtb3MuYXBpLnYxLlNlc3Npb25TdGF0cyIogtPkkwIiEiAvYXBpL3YxL2FpL3Nlc3Npb25zL3tzZXNzaW9uX2lkfRJ+ChBMaXN0U2Vzc2lvblN0YXRzEiUubWVtb3MuYXBpLnYxLkxpc3RTZXNzaW9uU3RhdHNSZXF1ZXN0GiYubWVtb3MuYXBpLnYxLkxpc3RTZXNzaW9uU3RhdHNSZXNwb25zZSIbgtPkkwIVEhMvYXBpL3YxL2FpL3Nlc3Npb25zEmkKDEdldENvc3RTdGF0cxIhLm1lbW9zLmFwaS52MS5HZXRDb3N0U3RhdHNSZXF1ZXN0GhcubWVtb3MuYXBpLnYxLkNvc3RTdGF0cyIdgtPkkwIXEhUvYXBpL3YxL2FpL2Nvc3Qtc3RhdHMSbwoTR2V0VXNlckNvc3RTZXR0aW5ncxIWLmdvb2dsZS5wcm90b2J1Zi5FbXB0eRoeLm1lbW9zLmFwaS52MS5Vc2VyQ29zdFNldHRpbmdzIiCC0+STAhoSGC9hcGkvdjEvYWkvY29zdC1zZXR0aW5ncxKEAQoTU2V0VXNlckNvc3RTZXR0aW5ncxIoLm1lbW9zLmFwaS52MS5TZXRVc2VyQ29zdFNldHRpbmdzUmVxdWVzdBoeLm1lbW9zLmFwaS52MS5Vc2VyQ29zdFNldHRpbmdzIiOC0+STAh06ASoyGC9hcGkvdjEvYWkvY29zdC1zZXR0aW5ncxKKAQoKTGlzdEJsb2NrcxIfLm1lbW9zLmFwaS52MS5MaXN0QmxvY2tzUmVxdWVzdBogLm1lbW9zLmFwaS52MS5MaXN0QmxvY2tzUmVzcG9uc2UiOYLT5JMCMxIxL2FwaS92MS9haS9jb252ZXJzYXRpb25zL3tjb252ZXJzYXRpb25faWR9L2Jsb2NrcxKgAQoPR2V0QmxvY2tDaGFuZ2VzEiQubWVtb3MuYXBpLnYxLkdldEJsb2NrQ2hhbmdlc1JlcXVlc3QaJS5tZW1vcy5hcGkudjEuR2V0QmxvY2tDaGFuZ2VzUmVzcG9uc2UiQILT5JMCOhI4L2FwaS92MS9haS9jb252ZXJzYXRpb25zL3tjb252ZXJzYXRpb25faWR9L2Jsb2NrLWNoYW5nZXMSXgoIR2V0QmxvY2sSHS5tZW1vcy5hcGkudjEuR2V0QmxvY2tSZXF1ZXN0GhMubWVtb3MuYXBpLnYxLkJsb2NrIh6C0+STAhgSFi9hcGkvdjEvYWkvYmxvY2tzL3tpZH0ShwEKEkdldEJsb2NrVHJhbnNjcmlwdBInLm1lbW9zLmFwaS52MS5HZXRCbG9ja1RyYW5zY3JpcHRSZXF1ZXN0Gh0ubWVtb3MuYXBpLnYxLkJsb2NrVHJhbnNjcmlwdCIpgtPkkwIjEiEvYXBpL3YxL2FpL2Jsb2Nrcy97aWR9L3RyYW5zY3JpcHQSggEKC0NyZWF0ZUJsb2NrEiAubWVtb3MuYXBpLnYxLkNyZWF0ZUJsb2NrUmVxdWVzdBoTLm1lbW9zLmFwaS52MS5CbG9jayI8gtPkkwI2OgEqIjEvYXBpL3YxL2FpL2NvbnZlcnNhdGlvbnMve2NvbnZlcnNhdGlvbl9pZH0vYmxvY2tzEmcKC1VwZGF0ZUJsb2NrEiAubWVtb3MuYXBpLnYxLlVwZGF0ZUJsb2NrUmVxdWVzdBoTLm1lbW9zLmFwaS52MS5CbG9jayIhgtPkkwIbOgEqMhYvYXBpL3YxL2FpL2Jsb2Nrcy97aWR9EmcKC0RlbGV0ZUJsb2NrEiAubWVtb3MuYXBpLnYxLkRlbGV0ZUJsb2NrUmVxdWVzdBoWLmdvb2dsZS5wcm90b2J1Zi5FbXB0eSIegtPkkwIYKhYvYXBpL3YxL2FpL2Jsb2Nrcy97aWR9EnkKD0FwcGVuZFVzZXJJbnB1dBIkLm1lbW9zLmFwaS52MS5BcHBlbmRVc2VySW5wdXRSZXF1ZXN0GhYuZ29vZ2xlLnByb3RvYnVmLkVtcHR5IiiC0+STAiI6ASoiHS9hcGkvdjEvYWkvYmxvY2tzL3tpZH0vaW5wdXRzEnEKC0FwcGVuZEV2ZW50EiAubWVtb3MuYXBpLnYxLkFwcGVuZEV2ZW50UmVxdWVzdBoWLmdvb2dsZS5wcm90b2J1Zi5FbXB0eSIogtPkkwIiOgEqIh0vYXBpL3YxL2FpL2Jsb2Nrcy97aWR9L2V2ZW50cxJoCglGb3JrQmxvY2sSHi5tZW1vcy5hcGkudjEuRm9ya0Jsb2NrUmVxdWVzdBoTLm1lbW9zLmFwaS52MS5CbG9jayImgtPkkwIgOgEqIhsvYXBpL3YxL2FpL2Jsb2Nrcy97aWR9L2ZvcmsSjQEKEUxpc3RCbG9ja0JyYW5jaGVzEiYubWVtb3MuYXBpLnYxLkxpc3RCbG9ja0JyYW5jaGVzUmVxdWVzdBonLm1lbW9zLmFwaS52MS5MaXN0QmxvY2tCcmFuY2hlc1Jlc3BvbnNlIieC0+STAiESHy9hcGkvdjEvYWkvYmxvY2tzL3tpZH0vYnJhbmNoZXMSjgEKDFN3aXRjaEJyYW5jaBIhLm1lbW9zLmFwaS52MS5Td2l0Y2hCcmFuY2hSZXF1ZXN0GhYuZ29vZ2xlLnByb3RvYnVmLkVtcHR5IkOC0+STAj06ASoiOC9hcGkvdjEvYWkvY29udmVyc2F0aW9ucy97Y29udmVyc2F0aW9uX2lkfS9zd2l0Y2gtYnJhbmNoEnAKDERlbGV0ZUJyYW5jaBIhLm1lbW9zLmFwaS52MS5EZWxldGVCcmFuY2hSZXF1ZXN0GhYuZ29vZ2xlLnByb3RvYnVmLkVtcHR5IiWC0+STAh8qHS9hcGkvdjEvYWkvYmxvY2tzL3tpZH0vYnJhbmNoElgKCEdldFVzYWdlEh0ubWVtb3MuYXBpLnYxLkdldFVzYWdlUmVxdWVzdBoTLm1lbW9zLmFwaS52MS5Vc2FnZSIYgtPkkwISEhAvYXBpL3YxL2FpL3VzYWdlEm4KC1J1blNlbGZUZXN0EiAubWVtb3MuYXBpLnYxLlJ1blNlbGZUZXN0UmVxdWVzdBocLm1lbW9zLmFwaS52MS5TZWxmVGVzdFJlcG9ydCIfgtPkkwIZOgEqIhQvYXBpL3YxL2FpL3NlbGYtdGVzdBJ2CgpSZWluZGV4QWxsEh8ubWVtb3MuYXBpLnYxLlJlaW5kZXhBbGxSZXF1ZXN0Gh0ubWVtb3MuYXBpLnYxLlJlaW5kZXhQcm9ncmVzcyIogtPkkwIiOgEqIh0vYXBpL3YxL2FpL2VtYmVkZGluZ3MvcmVpbmRleBKAAQoMUmVpbmRleFNpbmNlEiEubWVtb3MuYXBpLnYxLlJlaW5kZXhTaW5jZVJlcXVlc3QaHS5tZW1vcy5hcGkudjEuUmVpbmRleFByb2dyZXNzIi6C0+STAig6ASoiIy9hcGkvdjEvYWkvZW1iZWRkaW5ncy9yZWluZGV4LXNpbmNlEoMBChJHZXRSZWluZGV4UHJvZ3Jlc3MSJy5tZW1vcy5hcGkudjEuR2V0UmVpbmRleFByb2dyZXNzUmVxdWVzdBodLm1lbW9zLmFwaS52MS5SZWluZGV4UHJvZ3Jlc3MiJYLT5JMCHxIdL2FwaS92MS9haS9lbWJlZGRpbmdzL3JlaW5kZXgSewoOTGlzdE1DUFNlcnZlcnMSIy5tZW1vcy5hcGkudjEuTGlzdE1DUFNlcnZlcnNSZXF1ZXN0GiQubWVtb3MuYXBpLnYxLkxpc3RNQ1BTZXJ2ZXJzUmVzcG9uc2UiHoLT5JMCGBIWL2FwaS92MS9haS9tY3Atc2VydmVycxJzCg9DcmVhdGVNQ1BTZXJ2ZXISJC5tZW1vcy5hcGkudjEuQ3JlYXRlTUNQU2VydmVyUmVxdWVzdBoXLm1lbW9zLmFwaS52MS5NQ1BTZXJ2ZXIiIYLT5JMCGzoBKiIWL2FwaS92MS9haS9tY3Atc2VydmVycxJ4Cg9VcGRhdGVNQ1BTZXJ2ZXISJC5tZW1vcy5hcGkudjEuVXBkYXRlTUNQU2VydmVyUmVxdWVzdBoXLm1lbW9zLmFwaS52MS5NQ1BTZXJ2ZXIiJoLT5JMCIDoBKhobL2FwaS92MS9haS9tY3Atc2VydmVycy97aWR9EnQKD0RlbGV0ZU1DUFNlcnZlchIkLm1lbW9zLmFwaS52MS5EZWxldGVNQ1BTZXJ2ZXJSZXF1ZXN0GhYuZ29vZ2xlLnByb3RvYnVmLkVtcHR5IiOC0+STAh0qGy9hcGkvdjEvYWkvbWNwLXNlcnZlcnMve2lkfRJ/Cg9MaXN0QWN0aW9uQXVkaXQSJC5tZW1vcy5hcGkudjEuTGlzdEFjdGlvbkF1ZGl0UmVxdWVzdBolLm1lbW9zLmFwaS52MS5MaXN0QWN0aW9uQXVkaXRSZXNwb25zZSIfgtPkkwIZEhcvYXBpL3YxL2FpL2FjdGlvbi1hdWRpdBKMAQoRRXhwb3J0QWN0aW9uQXVkaXQSJi5tZW1vcy5hcGkudjEuRXhwb3J0QWN0aW9uQXVkaXRSZXF1ZXN0GicubWVtb3MuYXBpLnYxLkV4cG9ydEFjdGlvbkF1ZGl0UmVzcG9uc2UiJoLT5JMCIBIeL2FwaS92MS9haS9hY3Rpb24tYXVkaXQ6ZXhwb3J0EnkKD0xpc3RBZ2VudE1vZGVscxIkLm1lbW9zLmFwaS52MS5MaXN0QWdlbnRNb2RlbHNSZXF1ZXN0GiUubWVtb3MuYXBpLnYxLkxpc3RBZ2VudE1vZGVsc1Jlc3BvbnNlIhmC0+STAhMSES9hcGkvdjEvYWkvbW9kZWxzEnkKEFVwZGF0ZUFnZW50TW9kZWwSJS5tZW1vcy5hcGkudjEuVXBkYXRlQWdlbnRNb2RlbFJlcXVlc3QaGC5tZW1vcy5hcGkudjEuQWdlbnRNb2RlbCIkgtPkkwIeOgEqGhkvYXBpL3YxL2FpL21vZGVscy97YWdlbnR9EnQKEERlbGV0ZUFnZW50TW9kZWwSJS5tZW1vcy5hcGkudjEuRGVsZXRlQWdlbnRNb2RlbFJlcXVlc3QaFi5nb29nbGUucHJvdG9idWYuRW1wdHkiIYLT5JMCGyoZL2FwaS92MS9haS9tb2RlbHMve2FnZW50fRKOAQoVR2V0R2Vla1dvcmtzcGFjZVVzYWdlEioubWVtb3MuYXBpLnYxLkdldEdlZWtXb3Jrc3BhY2VVc2FnZVJlcXVlc3QaIC5tZW1vcy5hcGkudjEuR2Vla1dvcmtzcGFjZVVzYWdlIieC0+STAiESHy9hcGkvdjEvYWkvZ2Vlay13b3Jrc3BhY2UvdXNhZ2USnAEKFkxpc3RHZWVrV29ya3NwYWNlRmlsZXMSKy5tZW1vcy5hcGkudjEuTGlzdEdlZWtXb3Jrc3BhY2VGaWxlc1JlcXVlc3QaLC5tZW1vcy5hcGkudjEuTGlzdEdlZWtXb3Jrc3BhY2VGaWxlc1Jlc3BvbnNlIieC0+STAiESHy9hcGkvdjEvYWkvZ2Vlay13b3Jrc3BhY2UvZmlsZXMSmQEKFVJlYWRHZWVrV29ya3NwYWNlRmlsZRIqLm1lbW9zLmFwaS52MS5SZWFkR2Vla1dvcmtzcGFjZUZpbGVSZXF1ZXN0GiYubWVtb3MuYXBpLnYxLkdlZWtXb3Jrc3BhY2VGaWxlQ29udGVudCIsgtPkkwImEiQvYXBpL3YxL2FpL2dlZWstd29ya3NwYWNlL2ZpbGVzOnJlYWQSrgEKGURvd25sb2FkR2Vla1dvcmtzcGFjZUZpbGUSLi5tZW1vcy5hcGkudjEuRG93bmxvYWRHZWVrV29ya3NwYWNlRmlsZVJlcXVlc3QaLy5tZW1vcy5hcGkudjEuRG93bmxvYWRHZWVrV29ya3NwYWNlRmlsZVJlc3BvbnNlIjCC0+STAioSKC9hcGkvdjEvYWkvZ2Vlay13b3Jrc3BhY2UvZmlsZXM6ZG93bmxvYWQSiAEKF0RlbGV0ZUdlZWtXb3Jrc3BhY2VGaWxlEiwubWVtb3MuYXBpLnYxLkRlbGV0ZUdlZWtXb3Jrc3BhY2VGaWxlUmVxdWVzdBoWLmdvb2dsZS5wcm90b2J1Zi5FbXB0eSIngtPkkwIhKh8vYXBpL3YxL2FpL2dlZWstd29ya3NwYWNlL2ZpbGVzQqkBChBjb20ubWVtb3MuYXBpLnYxQg5BaVNlcnZpY2VQcm90b1ABWjNnaXRodWIuY29tL2hyeWdvL2RpdmluZXNlbnNlL3Byb3RvL2dlbi9hcGkvdjE7YXBpdjGiAgNNQViqAgxNZW1vcy5BcGkuVjHKAgxNZW1vc1xBcGlcVjHiAhhNZW1vc1xBcGlcVjFcR1BCTWV0YWRhdGHqAg5NZW1vczo6QXBpOjpWMWIGcHJvdG8z", [file_google_api_annotations, file_google_api_field_behavior, file_google_protobuf_empty, file_google_protobuf_struct]);
 
 /**
  * SemanticSearchRequest is the request for SemanticSearch.
@@ -589,6 +589,48 @@ export type ClearConversationMessagesRequest = Message<"memos.api.v1.ClearConver
 export const ClearConversationMessagesRequestSchema: GenMessage<ClearConversationMessagesRequest> = /*@__PURE__*/
   messageDesc(file_api_v1_ai_service, 20);
 
+/**
+ * CreateChatStreamTicketRequest is the request for CreateChatStreamTicket.
+ *
+ * @generated from message memos.api.v1.CreateChatStreamTicketRequest
+ */
+export type CreateChatStreamTicketRequest = Message<"memos.api.v1.CreateChatStreamTicketRequest"> & {
+};
+
+/**
+ * Describes the message memos.api.v1.CreateChatStreamTicketRequest.
+ * Use `create(CreateChatStreamTicketRequestSchema)` to create a new message.
+ */
+export const CreateChatStreamTicketRequestSchema: GenMessage<CreateChatStreamTicketRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 21);
+
+/**
+ * ChatStreamTicket authenticates a single chat stream, passed as its ticket
+ * query parameter.
+ *
+ * @generated from message memos.api.v1.ChatStreamTicket
+ */
+export type ChatStreamTicket = Message<"memos.api.v1.ChatStreamTicket"> & {
+  /**
+   * @generated from field: string ticket = 1;
+   */
+  ticket: string;
+
+  /**
+   * Unix timestamp after which the ticket is rejected
+   *
+   * @generated from field: int64 expires_ts = 2;
+   */
+  expiresTs: bigint;
+};
+
+/**
+ * Describes the message memos.api.v1.ChatStreamTicket.
+ * Use `create(ChatStreamTicketSchema)` to create a new message.
+ */
+export const ChatStreamTicketSchema: GenMessage<ChatStreamTicket> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 22);
+
 /**
  * StopChatRequest is the request for stopping an ongoing chat stream.
  *
@@ -615,7 +657,49 @@ export type StopChatRequest = Message<"memos.api.v1.StopChatRequest"> & {
  * Use `create(StopChatRequestSchema)` to create a new message.
  */
 export const StopChatRequestSchema: GenMessage<StopChatRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 21);
+  messageDesc(file_api_v1_ai_service, 23);
+
+/**
+ * SubmitFormRequest answers the form_request event pending on a block.
+ *
+ * @generated from message memos.api.v1.SubmitFormRequest
+ */
+export type SubmitFormRequest = Message<"memos.api.v1.SubmitFormRequest"> & {
+  /**
+   * Block waiting on the form
+   *
+   * @generated from field: int64 block_id = 1;
+   */
+  blockId: bigint;
+
+  /**
+   * Must match the pending form, guarding against stale submissions
+   *
+   * @generated from field: string form_id = 2;
+   */
+  formId: string;
+
+  /**
+   * Answers keyed by field name
+   *
+   * @generated from field: google.protobuf.Struct payload = 3;
+   */
+  payload?: JsonObject;
+
+  /**
+   * User timezone for the follow-up (IANA format, e.g., "Asia/Shanghai")
+   *
+   * @generated from field: string timezone = 4;
+   */
+  timezone: string;
+};
+
+/**
+ * Describes the message memos.api.v1.SubmitFormRequest.
+ * Use `create(SubmitFormRequestSchema)` to create a new message.
+ */
+export const SubmitFormRequestSchema: GenMessage<SubmitFormRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 24);
 
 /**
  * DangerBlockEvent represents a dangerous operation that was blocked.
@@ -657,7 +741,7 @@ export type DangerBlockEvent = Message<"memos.api.v1.DangerBlockEvent"> & {
  * Use `create(DangerBlockEventSchema)` to create a new message.
  */
 export const DangerBlockEventSchema: GenMessage<DangerBlockEvent> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 22);
+  messageDesc(file_api_v1_ai_service, 25);
 
 /**
  * ChatResponse is the response for Chat.
@@ -742,6 +826,15 @@ export type ChatResponse = Message<"memos.api.v1.ChatResponse"> & {
    * @generated from field: int64 block_id = 10;
    */
   blockId: bigint;
+
+  /**
+   * Tracing: OpenTelemetry trace ID of this chat round, for debug views to link to the trace
+   *
+   * Hex trace ID; empty when tracing is off or the round is not sampled
+   *
+   * @generated from field: string trace_id = 11;
+   */
+  traceId: string;
 };
 
 /**
@@ -749,7 +842,7 @@ export type ChatResponse = Message<"memos.api.v1.ChatResponse"> & {
  * Use `create(ChatResponseSchema)` to create a new message.
  */
 export const ChatResponseSchema: GenMessage<ChatResponse> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 23);
+  messageDesc(file_api_v1_ai_service, 26);
 
 /**
  * ScheduleCreationIntent represents AI's analysis of user's intent to create a schedule.
@@ -784,7 +877,7 @@ export type ScheduleCreationIntent = Message<"memos.api.v1.ScheduleCreationInten
  * Use `create(ScheduleCreationIntentSchema)` to create a new message.
  */
 export const ScheduleCreationIntentSchema: GenMessage<ScheduleCreationIntent> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 24);
+  messageDesc(file_api_v1_ai_service, 27);
 
 /**
  * ScheduleQueryResult contains the result of schedule query intent detection and the queried schedules.
@@ -826,7 +919,7 @@ export type ScheduleQueryResult = Message<"memos.api.v1.ScheduleQueryResult"> &
  * Use `create(ScheduleQueryResultSchema)` to create a new message.
  */
 export const ScheduleQueryResultSchema: GenMessage<ScheduleQueryResult> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 25);
+  messageDesc(file_api_v1_ai_service, 28);
 
 /**
  * ScheduleSummary represents a simplified schedule for query results.
@@ -897,7 +990,7 @@ export type ScheduleSummary = Message<"memos.api.v1.ScheduleSummary"> & {
  * Use `create(ScheduleSummarySchema)` to create a new message.
  */
 export const ScheduleSummarySchema: GenMessage<ScheduleSummary> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 26);
+  messageDesc(file_api_v1_ai_service, 29);
 
 /**
  * GetRelatedMemosRequest is the request for GetRelatedMemos.
@@ -925,7 +1018,7 @@ export type GetRelatedMemosRequest = Message<"memos.api.v1.GetRelatedMemosReques
  * Use `create(GetRelatedMemosRequestSchema)` to create a new message.
  */
 export const GetRelatedMemosRequestSchema: GenMessage<GetRelatedMemosRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 27);
+  messageDesc(file_api_v1_ai_service, 30);
 
 /**
  * GetRelatedMemosResponse is the response for GetRelatedMemos.
@@ -944,7 +1037,7 @@ export type GetRelatedMemosResponse = Message<"memos.api.v1.GetRelatedMemosRespo
  * Use `create(GetRelatedMemosResponseSchema)` to create a new message.
  */
 export const GetRelatedMemosResponseSchema: GenMessage<GetRelatedMemosResponse> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 28);
+  messageDesc(file_api_v1_ai_service, 31);
 
 /**
  * ParrotSelfCognition represents a parrot's metacognitive understanding of itself.
@@ -1028,7 +1121,7 @@ export type ParrotSelfCognition = Message<"memos.api.v1.ParrotSelfCognition"> &
  * Use `create(ParrotSelfCognitionSchema)` to create a new message.
  */
 export const ParrotSelfCognitionSchema: GenMessage<ParrotSelfCognition> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 29);
+  messageDesc(file_api_v1_ai_service, 32);
 
 /**
  * GetParrotSelfCognitionRequest is the request for GetParrotSelfCognition.
@@ -1049,7 +1142,7 @@ export type GetParrotSelfCognitionRequest = Message<"memos.api.v1.GetParrotSelfC
  * Use `create(GetParrotSelfCognitionRequestSchema)` to create a new message.
  */
 export const GetParrotSelfCognitionRequestSchema: GenMessage<GetParrotSelfCognitionRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 30);
+  messageDesc(file_api_v1_ai_service, 33);
 
 /**
  * GetParrotSelfCognitionResponse is the response for GetParrotSelfCognition.
@@ -1070,7 +1163,7 @@ export type GetParrotSelfCognitionResponse = Message<"memos.api.v1.GetParrotSelf
  * Use `create(GetParrotSelfCognitionResponseSchema)` to create a new message.
  */
 export const GetParrotSelfCognitionResponseSchema: GenMessage<GetParrotSelfCognitionResponse> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 31);
+  messageDesc(file_api_v1_ai_service, 34);
 
 /**
  * ListParrotsRequest is the request for ListParrots.
@@ -1085,7 +1178,7 @@ export type ListParrotsRequest = Message<"memos.api.v1.ListParrotsRequest"> & {
  * Use `create(ListParrotsRequestSchema)` to create a new message.
  */
 export const ListParrotsRequestSchema: GenMessage<ListParrotsRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 32);
+  messageDesc(file_api_v1_ai_service, 35);
 
 /**
  * ListParrotsResponse is the response for ListParrots.
@@ -1106,7 +1199,7 @@ export type ListParrotsResponse = Message<"memos.api.v1.ListParrotsResponse"> &
  * Use `create(ListParrotsResponseSchema)` to create a new message.
  */
 export const ListParrotsResponseSchema: GenMessage<ListParrotsResponse> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 33);
+  messageDesc(file_api_v1_ai_service, 36);
 
 /**
  * ParrotInfo represents basic information about a parrot.
@@ -1141,7 +1234,7 @@ export type ParrotInfo = Message<"memos.api.v1.ParrotInfo"> & {
  * Use `create(ParrotInfoSchema)` to create a new message.
  */
 export const ParrotInfoSchema: GenMessage<ParrotInfo> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 34);
+  messageDesc(file_api_v1_ai_service, 37);
 
 /**
  * DetectDuplicatesRequest is the request for DetectDuplicates.
@@ -1183,7 +1276,7 @@ export type DetectDuplicatesRequest = Message<"memos.api.v1.DetectDuplicatesRequ
  * Use `create(DetectDuplicatesRequestSchema)` to create a new message.
  */
 export const DetectDuplicatesRequestSchema: GenMessage<DetectDuplicatesRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 35);
+  messageDesc(file_api_v1_ai_service, 38);
 
 /**
  * DetectDuplicatesResponse is the response for DetectDuplicates.
@@ -1232,7 +1325,7 @@ export type DetectDuplicatesResponse = Message<"memos.api.v1.DetectDuplicatesRes
  * Use `create(DetectDuplicatesResponseSchema)` to create a new message.
  */
 export const DetectDuplicatesResponseSchema: GenMessage<DetectDuplicatesResponse> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 36);
+  messageDesc(file_api_v1_ai_service, 39);
 
 /**
  * SimilarMemo represents a memo similar to the input.
@@ -1302,7 +1395,7 @@ export type SimilarMemo = Message<"memos.api.v1.SimilarMemo"> & {
  * Use `create(SimilarMemoSchema)` to create a new message.
  */
 export const SimilarMemoSchema: GenMessage<SimilarMemo> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 37);
+  messageDesc(file_api_v1_ai_service, 40);
 
 /**
  * SimilarityBreakdown shows how similarity was calculated.
@@ -1337,7 +1430,7 @@ export type SimilarityBreakdown = Message<"memos.api.v1.SimilarityBreakdown"> &
  * Use `create(SimilarityBreakdownSchema)` to create a new message.
  */
 export const SimilarityBreakdownSchema: GenMessage<SimilarityBreakdown> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 38);
+  messageDesc(file_api_v1_ai_service, 41);
 
 /**
  * MergeMemosRequest is the request for MergeMemos.
@@ -1365,7 +1458,7 @@ export type MergeMemosRequest = Message<"memos.api.v1.MergeMemosRequest"> & {
  * Use `create(MergeMemosRequestSchema)` to create a new message.
  */
 export const MergeMemosRequestSchema: GenMessage<MergeMemosRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 39);
+  messageDesc(file_api_v1_ai_service, 42);
 
 /**
  * MergeMemosResponse is the response for MergeMemos.
@@ -1386,7 +1479,7 @@ export type MergeMemosResponse = Message<"memos.api.v1.MergeMemosResponse"> & {
  * Use `create(MergeMemosResponseSchema)` to create a new message.
  */
 export const MergeMemosResponseSchema: GenMessage<MergeMemosResponse> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 40);
+  messageDesc(file_api_v1_ai_service, 43);
 
 /**
  * LinkMemosRequest is the request for LinkMemos.
@@ -1414,7 +1507,7 @@ export type LinkMemosRequest = Message<"memos.api.v1.LinkMemosRequest"> & {
  * Use `create(LinkMemosRequestSchema)` to create a new message.
  */
 export const LinkMemosRequestSchema: GenMessage<LinkMemosRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 41);
+  messageDesc(file_api_v1_ai_service, 44);
 
 /**
  * LinkMemosResponse is the response for LinkMemos.
@@ -1435,7 +1528,7 @@ export type LinkMemosResponse = Message<"memos.api.v1.LinkMemosResponse"> & {
  * Use `create(LinkMemosResponseSchema)` to create a new message.
  */
 export const LinkMemosResponseSchema: GenMessage<LinkMemosResponse> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 42);
+  messageDesc(file_api_v1_ai_service, 45);
 
 /**
  * GetKnowledgeGraphRequest is the request for GetKnowledgeGraph.
@@ -1470,7 +1563,7 @@ export type GetKnowledgeGraphRequest = Message<"memos.api.v1.GetKnowledgeGraphRe
  * Use `create(GetKnowledgeGraphRequestSchema)` to create a new message.
  */
 export const GetKnowledgeGraphRequestSchema: GenMessage<GetKnowledgeGraphRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 43);
+  messageDesc(file_api_v1_ai_service, 46);
 
 /**
  * GetKnowledgeGraphResponse is the response for GetKnowledgeGraph.
@@ -1512,7 +1605,7 @@ export type GetKnowledgeGraphResponse = Message<"memos.api.v1.GetKnowledgeGraphR
  * Use `create(GetKnowledgeGraphResponseSchema)` to create a new message.
  */
 export const GetKnowledgeGraphResponseSchema: GenMessage<GetKnowledgeGraphResponse> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 44);
+  messageDesc(file_api_v1_ai_service, 47);
 
 /**
  * GraphNode represents a node in the knowledge graph.
@@ -1575,7 +1668,7 @@ export type GraphNode = Message<"memos.api.v1.GraphNode"> & {
  * Use `create(GraphNodeSchema)` to create a new message.
  */
 export const GraphNodeSchema: GenMessage<GraphNode> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 45);
+  messageDesc(file_api_v1_ai_service, 48);
 
 /**
  * GraphEdge represents an edge in the knowledge graph.
@@ -1617,7 +1710,7 @@ export type GraphEdge = Message<"memos.api.v1.GraphEdge"> & {
  * Use `create(GraphEdgeSchema)` to create a new message.
  */
 export const GraphEdgeSchema: GenMessage<GraphEdge> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 46);
+  messageDesc(file_api_v1_ai_service, 49);
 
 /**
  * GraphStats contains graph statistics.
@@ -1673,7 +1766,7 @@ export type GraphStats = Message<"memos.api.v1.GraphStats"> & {
  * Use `create(GraphStatsSchema)` to create a new message.
  */
 export const GraphStatsSchema: GenMessage<GraphStats> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 47);
+  messageDesc(file_api_v1_ai_service, 50);
 
 /**
  * GetDueReviewsRequest is the request for GetDueReviews.
@@ -1694,7 +1787,7 @@ export type GetDueReviewsRequest = Message<"memos.api.v1.GetDueReviewsRequest">
  * Use `create(GetDueReviewsRequestSchema)` to create a new message.
  */
 export const GetDueReviewsRequestSchema: GenMessage<GetDueReviewsRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 48);
+  messageDesc(file_api_v1_ai_service, 51);
 
 /**
  * GetDueReviewsResponse is the response for GetDueReviews.
@@ -1720,7 +1813,7 @@ export type GetDueReviewsResponse = Message<"memos.api.v1.GetDueReviewsResponse"
  * Use `create(GetDueReviewsResponseSchema)` to create a new message.
  */
 export const GetDueReviewsResponseSchema: GenMessage<GetDueReviewsResponse> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 49);
+  messageDesc(file_api_v1_ai_service, 52);
 
 /**
  * ReviewItem represents a memo in the review queue.
@@ -1804,7 +1897,7 @@ export type ReviewItem = Message<"memos.api.v1.ReviewItem"> & {
  * Use `create(ReviewItemSchema)` to create a new message.
  */
 export const ReviewItemSchema: GenMessage<ReviewItem> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 50);
+  messageDesc(file_api_v1_ai_service, 53);
 
 /**
  * RecordReviewRequest is the request for RecordReview.
@@ -1828,7 +1921,7 @@ export type RecordReviewRequest = Message<"memos.api.v1.RecordReviewRequest"> &
  * Use `create(RecordReviewRequestSchema)` to create a new message.
  */
 export const RecordReviewRequestSchema: GenMessage<RecordReviewRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 51);
+  messageDesc(file_api_v1_ai_service, 54);
 
 /**
  * RecordRouterFeedbackRequest is the request for RecordRouterFeedback.
@@ -1864,7 +1957,7 @@ export type RecordRouterFeedbackRequest = Message<"memos.api.v1.RecordRouterFeed
  * Use `create(RecordRouterFeedbackRequestSchema)` to create a new message.
  */
 export const RecordRouterFeedbackRequestSchema: GenMessage<RecordRouterFeedbackRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 52);
+  messageDesc(file_api_v1_ai_service, 55);
 
 /**
  * GetReviewStatsRequest is the request for GetReviewStats.
@@ -1879,7 +1972,7 @@ export type GetReviewStatsRequest = Message<"memos.api.v1.GetReviewStatsRequest"
  * Use `create(GetReviewStatsRequestSchema)` to create a new message.
  */
 export const GetReviewStatsRequestSchema: GenMessage<GetReviewStatsRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 53);
+  messageDesc(file_api_v1_ai_service, 56);
 
 /**
  * GetReviewStatsResponse is the response for GetReviewStats.
@@ -1949,7 +2042,7 @@ export type GetReviewStatsResponse = Message<"memos.api.v1.GetReviewStatsRespons
  * Use `create(GetReviewStatsResponseSchema)` to create a new message.
  */
 export const GetReviewStatsResponseSchema: GenMessage<GetReviewStatsResponse> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 54);
+  messageDesc(file_api_v1_ai_service, 57);
 
 /**
  * EventMetadata provides enhanced metadata for streaming events.
@@ -2074,7 +2167,7 @@ export type EventMetadata = Message<"memos.api.v1.EventMetadata"> & {
  * Use `create(EventMetadataSchema)` to create a new message.
  */
 export const EventMetadataSchema: GenMessage<EventMetadata> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 55);
+  messageDesc(file_api_v1_ai_service, 58);
 
 /**
  * BlockSummary provides end-of-block statistics for a single chat round.
@@ -2220,7 +2313,7 @@ export type BlockSummary = Message<"memos.api.v1.BlockSummary"> & {
  * Use `create(BlockSummarySchema)` to create a new message.
  */
 export const BlockSummarySchema: GenMessage<BlockSummary> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 56);
+  messageDesc(file_api_v1_ai_service, 59);
 
 /**
  * SessionStats represents detailed statistics for a single session.
@@ -2366,7 +2459,7 @@ export type SessionStats = Message<"memos.api.v1.SessionStats"> & {
  * Use `create(SessionStatsSchema)` to create a new message.
  */
 export const SessionStatsSchema: GenMessage<SessionStats> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 57);
+  messageDesc(file_api_v1_ai_service, 60);
 
 /**
  * GetSessionStatsRequest is the request for GetSessionStats.
@@ -2385,7 +2478,7 @@ export type GetSessionStatsRequest = Message<"memos.api.v1.GetSessionStatsReques
  * Use `create(GetSessionStatsRequestSchema)` to create a new message.
  */
 export const GetSessionStatsRequestSchema: GenMessage<GetSessionStatsRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 58);
+  messageDesc(file_api_v1_ai_service, 61);
 
 /**
  * ListSessionStatsRequest is the request for ListSessionStats.
@@ -2420,7 +2513,7 @@ export type ListSessionStatsRequest = Message<"memos.api.v1.ListSessionStatsRequ
  * Use `create(ListSessionStatsRequestSchema)` to create a new message.
  */
 export const ListSessionStatsRequestSchema: GenMessage<ListSessionStatsRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 59);
+  messageDesc(file_api_v1_ai_service, 62);
 
 /**
  * ListSessionStatsResponse is the response for ListSessionStats.
@@ -2451,7 +2544,7 @@ export type ListSessionStatsResponse = Message<"memos.api.v1.ListSessionStatsRes
  * Use `create(ListSessionStatsResponseSchema)` to create a new message.
  */
 export const ListSessionStatsResponseSchema: GenMessage<ListSessionStatsResponse> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 60);
+  messageDesc(file_api_v1_ai_service, 63);
 
 /**
  * GetCostStatsRequest is the request for GetCostStats.
@@ -2472,7 +2565,7 @@ export type GetCostStatsRequest = Message<"memos.api.v1.GetCostStatsRequest"> &
  * Use `create(GetCostStatsRequestSchema)` to create a new message.
  */
 export const GetCostStatsRequestSchema: GenMessage<GetCostStatsRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 61);
+  messageDesc(file_api_v1_ai_service, 64);
 
 /**
  * CostStats represents aggregated cost statistics.
@@ -2511,7 +2604,7 @@ export type CostStats = Message<"memos.api.v1.CostStats"> & {
  * Use `create(CostStatsSchema)` to create a new message.
  */
 export const CostStatsSchema: GenMessage<CostStats> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 62);
+  messageDesc(file_api_v1_ai_service, 65);
 
 /**
  * DailyCostData represents cost data for a single day.
@@ -2542,7 +2635,7 @@ export type DailyCostData = Message<"memos.api.v1.DailyCostData"> & {
  * Use `create(DailyCostDataSchema)` to create a new message.
  */
 export const DailyCostDataSchema: GenMessage<DailyCostData> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 63);
+  messageDesc(file_api_v1_ai_service, 66);
 
 /**
  * UserCostSettings represents user-specific cost control settings.
@@ -2590,7 +2683,7 @@ export type UserCostSettings = Message<"memos.api.v1.UserCostSettings"> & {
  * Use `create(UserCostSettingsSchema)` to create a new message.
  */
 export const UserCostSettingsSchema: GenMessage<UserCostSettings> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 64);
+  messageDesc(file_api_v1_ai_service, 67);
 
 /**
  * SetUserCostSettingsRequest is the request for SetUserCostSettings.
@@ -2631,7 +2724,7 @@ export type SetUserCostSettingsRequest = Message<"memos.api.v1.SetUserCostSettin
  * Use `create(SetUserCostSettingsRequestSchema)` to create a new message.
  */
 export const SetUserCostSettingsRequestSchema: GenMessage<SetUserCostSettingsRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 65);
+  messageDesc(file_api_v1_ai_service, 68);
 
 /**
  * Block represents a single conversation block (round).
@@ -2822,7 +2915,7 @@ export type Block = Message<"memos.api.v1.Block"> & {
  * Use `create(BlockSchema)` to create a new message.
  */
 export const BlockSchema: GenMessage<Block> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 66);
+  messageDesc(file_api_v1_ai_service, 69);
 
 /**
  * TokenUsage represents detailed token usage for a single block or LLM call.
@@ -2871,7 +2964,7 @@ export type TokenUsage = Message<"memos.api.v1.TokenUsage"> & {
  * Use `create(TokenUsageSchema)` to create a new message.
  */
 export const TokenUsageSchema: GenMessage<TokenUsage> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 67);
+  messageDesc(file_api_v1_ai_service, 70);
 
 /**
  * UserInput represents a single user input within a block.
@@ -2902,7 +2995,7 @@ export type UserInput = Message<"memos.api.v1.UserInput"> & {
  * Use `create(UserInputSchema)` to create a new message.
  */
 export const UserInputSchema: GenMessage<UserInput> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 68);
+  messageDesc(file_api_v1_ai_service, 71);
 
 /**
  * BlockEvent represents an event in the event stream.
@@ -2941,7 +3034,7 @@ export type BlockEvent = Message<"memos.api.v1.BlockEvent"> & {
  * Use `create(BlockEventSchema)` to create a new message.
  */
 export const BlockEventSchema: GenMessage<BlockEvent> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 69);
+  messageDesc(file_api_v1_ai_service, 72);
 
 /**
  * ListBlocksRequest is the request for ListBlocks.
@@ -2997,7 +3090,7 @@ export type ListBlocksRequest = Message<"memos.api.v1.ListBlocksRequest"> & {
  * Use `create(ListBlocksRequestSchema)` to create a new message.
  */
 export const ListBlocksRequestSchema: GenMessage<ListBlocksRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 70);
+  messageDesc(file_api_v1_ai_service, 73);
 
 /**
  * ListBlocksResponse is the response for ListBlocks.
@@ -3048,7 +3141,7 @@ export type ListBlocksResponse = Message<"memos.api.v1.ListBlocksResponse"> & {
  * Use `create(ListBlocksResponseSchema)` to create a new message.
  */
 export const ListBlocksResponseSchema: GenMessage<ListBlocksResponse> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 71);
+  messageDesc(file_api_v1_ai_service, 74);
 
 /**
  * GetBlockRequest is the request for GetBlock.
@@ -3067,7 +3160,7 @@ export type GetBlockRequest = Message<"memos.api.v1.GetBlockRequest"> & {
  * Use `create(GetBlockRequestSchema)` to create a new message.
  */
 export const GetBlockRequestSchema: GenMessage<GetBlockRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 72);
+  messageDesc(file_api_v1_ai_service, 75);
 
 /**
  * CreateBlockRequest is the request for CreateBlock.
@@ -3119,7 +3212,7 @@ export type CreateBlockRequest = Message<"memos.api.v1.CreateBlockRequest"> & {
  * Use `create(CreateBlockRequestSchema)` to create a new message.
  */
 export const CreateBlockRequestSchema: GenMessage<CreateBlockRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 73);
+  messageDesc(file_api_v1_ai_service, 76);
 
 /**
  * UpdateBlockRequest is the request for UpdateBlock.
@@ -3172,7 +3265,7 @@ export type UpdateBlockRequest = Message<"memos.api.v1.UpdateBlockRequest"> & {
  * Use `create(UpdateBlockRequestSchema)` to create a new message.
  */
 export const UpdateBlockRequestSchema: GenMessage<UpdateBlockRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 74);
+  messageDesc(file_api_v1_ai_service, 77);
 
 /**
  * DeleteBlockRequest is the request for DeleteBlock.
@@ -3191,7 +3284,7 @@ export type DeleteBlockRequest = Message<"memos.api.v1.DeleteBlockRequest"> & {
  * Use `create(DeleteBlockRequestSchema)` to create a new message.
  */
 export const DeleteBlockRequestSchema: GenMessage<DeleteBlockRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 75);
+  messageDesc(file_api_v1_ai_service, 78);
 
 /**
  * AppendUserInputRequest is the request for AppendUserInput.
@@ -3217,7 +3310,7 @@ export type AppendUserInputRequest = Message<"memos.api.v1.AppendUserInputReques
  * Use `create(AppendUserInputRequestSchema)` to create a new message.
  */
 export const AppendUserInputRequestSchema: GenMessage<AppendUserInputRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 76);
+  messageDesc(file_api_v1_ai_service, 79);
 
 /**
  * AppendEventRequest is the request for AppendEvent.
@@ -3243,7 +3336,7 @@ export type AppendEventRequest = Message<"memos.api.v1.AppendEventRequest"> & {
  * Use `create(AppendEventRequestSchema)` to create a new message.
  */
 export const AppendEventRequestSchema: GenMessage<AppendEventRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 77);
+  messageDesc(file_api_v1_ai_service, 80);
 
 /**
  * ForkBlockRequest is the request for ForkBlock.
@@ -3278,7 +3371,7 @@ export type ForkBlockRequest = Message<"memos.api.v1.ForkBlockRequest"> & {
  * Use `create(ForkBlockRequestSchema)` to create a new message.
  */
 export const ForkBlockRequestSchema: GenMessage<ForkBlockRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 78);
+  messageDesc(file_api_v1_ai_service, 81);
 
 /**
  * ListBlockBranchesRequest is the request for ListBlockBranches.
@@ -3299,7 +3392,7 @@ export type ListBlockBranchesRequest = Message<"memos.api.v1.ListBlockBranchesRe
  * Use `create(ListBlockBranchesRequestSchema)` to create a new message.
  */
 export const ListBlockBranchesRequestSchema: GenMessage<ListBlockBranchesRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 79);
+  messageDesc(file_api_v1_ai_service, 82);
 
 /**
  * ListBlockBranchesResponse is the response for ListBlockBranches.
@@ -3325,7 +3418,7 @@ export type ListBlockBranchesResponse = Message<"memos.api.v1.ListBlockBranchesR
  * Use `create(ListBlockBranchesResponseSchema)` to create a new message.
  */
 export const ListBlockBranchesResponseSchema: GenMessage<ListBlockBranchesResponse> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 80);
+  messageDesc(file_api_v1_ai_service, 83);
 
 /**
  * BlockBranch represents a branch in the conversation tree.
@@ -3367,7 +3460,7 @@ export type BlockBranch = Message<"memos.api.v1.BlockBranch"> & {
  * Use `create(BlockBranchSchema)` to create a new message.
  */
 export const BlockBranchSchema: GenMessage<BlockBranch> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 81);
+  messageDesc(file_api_v1_ai_service, 84);
 
 /**
  * SwitchBranchRequest is the request for SwitchBranch.
@@ -3393,7 +3486,7 @@ export type SwitchBranchRequest = Message<"memos.api.v1.SwitchBranchRequest"> &
  * Use `create(SwitchBranchRequestSchema)` to create a new message.
  */
 export const SwitchBranchRequestSchema: GenMessage<SwitchBranchRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 82);
+  messageDesc(file_api_v1_ai_service, 85);
 
 /**
  * DeleteBranchRequest is the request for DeleteBranch.
@@ -3421,114 +3514,1815 @@ export type DeleteBranchRequest = Message<"memos.api.v1.DeleteBranchRequest"> &
  * Use `create(DeleteBranchRequestSchema)` to create a new message.
  */
 export const DeleteBranchRequestSchema: GenMessage<DeleteBranchRequest> = /*@__PURE__*/
-  messageDesc(file_api_v1_ai_service, 83);
+  messageDesc(file_api_v1_ai_service, 86);
 
 /**
- * ScheduleQueryMode specifies the query mode for schedule filtering.
+ * GetUsageRequest is the request for GetUsage.
  *
- * @generated from enum memos.api.v1.ScheduleQueryMode
+ * @generated from message memos.api.v1.GetUsageRequest
  */
-export enum ScheduleQueryMode {
+export type GetUsageRequest = Message<"memos.api.v1.GetUsageRequest"> & {
+};
+
+/**
+ * Describes the message memos.api.v1.GetUsageRequest.
+ * Use `create(GetUsageRequestSchema)` to create a new message.
+ */
+export const GetUsageRequestSchema: GenMessage<GetUsageRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 87);
+
+/**
+ * Usage is the user's AI usage of the current calendar month (UTC).
+ *
+ * @generated from message memos.api.v1.Usage
+ */
+export type Usage = Message<"memos.api.v1.Usage"> & {
   /**
-   * Auto-select query mode based on query type (relative time → STANDARD, absolute time → STRICT)
+   * Unix timestamp of the month start
    *
-   * @generated from enum value: AUTO = 0;
+   * @generated from field: int64 period_start = 1;
    */
-  AUTO = 0,
+  periodStart: bigint;
 
   /**
-   * Standard mode: return schedules that have any part within the query range
+   * Unix timestamp of the next month start
    *
-   * @generated from enum value: STANDARD = 1;
+   * @generated from field: int64 period_end = 2;
    */
-  STANDARD = 1,
+  periodEnd: bigint;
 
   /**
-   * Strict mode: return only schedules completely within the query range
+   * Input and output tokens
    *
-   * @generated from enum value: STRICT = 2;
+   * @generated from field: int64 total_tokens = 3;
    */
-  STRICT = 2,
-}
+  totalTokens: bigint;
 
-/**
- * Describes the enum memos.api.v1.ScheduleQueryMode.
- */
-export const ScheduleQueryModeSchema: GenEnum<ScheduleQueryMode> = /*@__PURE__*/
-  enumDesc(file_api_v1_ai_service, 0);
+  /**
+   * @generated from field: double total_cost_usd = 4;
+   */
+  totalCostUsd: number;
 
-/**
- * AgentType specifies the type of AI agent to use for the request.
- *
- * @generated from enum memos.api.v1.AgentType
- */
-export enum AgentType {
   /**
-   * Default behavior (existing logic)
-   *
-   * @generated from enum value: AGENT_TYPE_DEFAULT = 0;
+   * @generated from field: int64 session_count = 5;
    */
-  DEFAULT = 0,
+  sessionCount: bigint;
 
   /**
-   * 🦜 Memo Parrot - Note assistant
+   * Monthly token quota (0 = unlimited)
    *
-   * @generated from enum value: AGENT_TYPE_MEMO = 1;
+   * @generated from field: int64 token_quota = 6;
    */
-  MEMO = 1,
+  tokenQuota: bigint;
 
   /**
-   * 🦜 Schedule Parrot - Schedule assistant
+   * Monthly cost quota (0 = unlimited)
    *
-   * @generated from enum value: AGENT_TYPE_SCHEDULE = 2;
+   * @generated from field: double cost_quota_usd = 7;
    */
-  SCHEDULE = 2,
+  costQuotaUsd: number;
 
   /**
-   * 🦜 General Parrot (通才) - General purpose assistant
+   * Whether new chats are rejected
    *
-   * @generated from enum value: AGENT_TYPE_GENERAL = 3;
+   * @generated from field: bool exceeded = 8;
    */
-  GENERAL = 3,
+  exceeded: boolean;
+};
+
+/**
+ * Describes the message memos.api.v1.Usage.
+ * Use `create(UsageSchema)` to create a new message.
+ */
+export const UsageSchema: GenMessage<Usage> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 88);
 
+/**
+ * RunSelfTestRequest is the request for RunSelfTest.
+ *
+ * @generated from message memos.api.v1.RunSelfTestRequest
+ */
+export type RunSelfTestRequest = Message<"memos.api.v1.RunSelfTestRequest"> & {
   /**
-   * 💡 Ideation Parrot (灵光) - Creative assistant for brainstorming and ideation
+   * Run against the configured model instead of a mock LLM
    *
-   * @generated from enum value: AGENT_TYPE_IDEATION = 5;
+   * @generated from field: bool live = 1;
    */
-  IDEATION = 5,
-}
+  live: boolean;
+};
 
 /**
- * Describes the enum memos.api.v1.AgentType.
+ * Describes the message memos.api.v1.RunSelfTestRequest.
+ * Use `create(RunSelfTestRequestSchema)` to create a new message.
  */
-export const AgentTypeSchema: GenEnum<AgentType> = /*@__PURE__*/
-  enumDesc(file_api_v1_ai_service, 1);
+export const RunSelfTestRequestSchema: GenMessage<RunSelfTestRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 89);
 
 /**
- * ReviewQuality represents the user's assessment of recall difficulty.
+ * SelfTestCheck is the outcome of one step of a self-test.
  *
- * @generated from enum memos.api.v1.ReviewQuality
+ * @generated from message memos.api.v1.SelfTestCheck
  */
-export enum ReviewQuality {
+export type SelfTestCheck = Message<"memos.api.v1.SelfTestCheck"> & {
   /**
-   * @generated from enum value: REVIEW_QUALITY_UNSPECIFIED = 0;
+   * @generated from field: string name = 1;
    */
-  UNSPECIFIED = 0,
+  name: string;
 
   /**
-   * Complete blackout
-   *
-   * @generated from enum value: REVIEW_QUALITY_AGAIN = 1;
+   * @generated from field: bool passed = 2;
    */
-  AGAIN = 1,
+  passed: boolean;
 
   /**
-   * Correct with difficulty
-   *
-   * @generated from enum value: REVIEW_QUALITY_HARD = 2;
+   * @generated from field: string detail = 3;
    */
-  HARD = 2,
+  detail: string;
+
+  /**
+   * @generated from field: int64 duration_ms = 4;
+   */
+  durationMs: bigint;
+};
+
+/**
+ * Describes the message memos.api.v1.SelfTestCheck.
+ * Use `create(SelfTestCheckSchema)` to create a new message.
+ */
+export const SelfTestCheckSchema: GenMessage<SelfTestCheck> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 90);
+
+/**
+ * SelfTestReport is the pass/fail report of a self-test.
+ *
+ * @generated from message memos.api.v1.SelfTestReport
+ */
+export type SelfTestReport = Message<"memos.api.v1.SelfTestReport"> & {
+  /**
+   * Whether all checks passed
+   *
+   * @generated from field: bool passed = 1;
+   */
+  passed: boolean;
+
+  /**
+   * "mock" or "live"
+   *
+   * @generated from field: string mode = 2;
+   */
+  mode: string;
+
+  /**
+   * @generated from field: repeated memos.api.v1.SelfTestCheck checks = 3;
+   */
+  checks: SelfTestCheck[];
+
+  /**
+   * @generated from field: int64 duration_ms = 4;
+   */
+  durationMs: bigint;
+};
+
+/**
+ * Describes the message memos.api.v1.SelfTestReport.
+ * Use `create(SelfTestReportSchema)` to create a new message.
+ */
+export const SelfTestReportSchema: GenMessage<SelfTestReport> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 91);
+
+/**
+ * GetBlockChangesRequest is the request for GetBlockChanges.
+ *
+ * @generated from message memos.api.v1.GetBlockChangesRequest
+ */
+export type GetBlockChangesRequest = Message<"memos.api.v1.GetBlockChangesRequest"> & {
+  /**
+   * @generated from field: int32 conversation_id = 1;
+   */
+  conversationId: number;
+
+  /**
+   * sync_ts of the previous response in milliseconds (0 = all blocks)
+   *
+   * @generated from field: int64 since_ts = 2;
+   */
+  sinceTs: bigint;
+};
+
+/**
+ * Describes the message memos.api.v1.GetBlockChangesRequest.
+ * Use `create(GetBlockChangesRequestSchema)` to create a new message.
+ */
+export const GetBlockChangesRequestSchema: GenMessage<GetBlockChangesRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 92);
+
+/**
+ * BlockChange is a block created or updated since a sync.
+ *
+ * @generated from message memos.api.v1.BlockChange
+ */
+export type BlockChange = Message<"memos.api.v1.BlockChange"> & {
+  /**
+   * For blocks that existed at the time of the sync, event_stream only holds
+   * the events from event_offset on.
+   *
+   * @generated from field: memos.api.v1.Block block = 1;
+   */
+  block?: Block;
+
+  /**
+   * Created blocks are sent whole; clients insert them, or replace their copy.
+   *
+   * @generated from field: bool created = 2;
+   */
+  created: boolean;
+
+  /**
+   * Index of the first event of block.event_stream; clients replace their
+   * events from that index on.
+   *
+   * @generated from field: int32 event_offset = 3;
+   */
+  eventOffset: number;
+};
+
+/**
+ * Describes the message memos.api.v1.BlockChange.
+ * Use `create(BlockChangeSchema)` to create a new message.
+ */
+export const BlockChangeSchema: GenMessage<BlockChange> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 93);
+
+/**
+ * GetBlockChangesResponse is the response for GetBlockChanges.
+ *
+ * @generated from message memos.api.v1.GetBlockChangesResponse
+ */
+export type GetBlockChangesResponse = Message<"memos.api.v1.GetBlockChangesResponse"> & {
+  /**
+   * @generated from field: repeated memos.api.v1.BlockChange changes = 1;
+   */
+  changes: BlockChange[];
+
+  /**
+   * UIDs of all blocks of the conversation in order; clients drop the blocks
+   * they hold that are not listed, e.g. deleted branches.
+   *
+   * @generated from field: repeated string block_uids = 2;
+   */
+  blockUids: string[];
+
+  /**
+   * since_ts of the next request
+   *
+   * @generated from field: int64 sync_ts = 3;
+   */
+  syncTs: bigint;
+};
+
+/**
+ * Describes the message memos.api.v1.GetBlockChangesResponse.
+ * Use `create(GetBlockChangesResponseSchema)` to create a new message.
+ */
+export const GetBlockChangesResponseSchema: GenMessage<GetBlockChangesResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 94);
+
+/**
+ * GetBlockTranscriptRequest is the request for GetBlockTranscript.
+ *
+ * @generated from message memos.api.v1.GetBlockTranscriptRequest
+ */
+export type GetBlockTranscriptRequest = Message<"memos.api.v1.GetBlockTranscriptRequest"> & {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id: bigint;
+
+  /**
+   * @generated from field: memos.api.v1.TranscriptDetail detail = 2;
+   */
+  detail: TranscriptDetail;
+};
+
+/**
+ * Describes the message memos.api.v1.GetBlockTranscriptRequest.
+ * Use `create(GetBlockTranscriptRequestSchema)` to create a new message.
+ */
+export const GetBlockTranscriptRequestSchema: GenMessage<GetBlockTranscriptRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 95);
+
+/**
+ * BlockTranscript is the compact transcript of a block.
+ *
+ * @generated from message memos.api.v1.BlockTranscript
+ */
+export type BlockTranscript = Message<"memos.api.v1.BlockTranscript"> & {
+  /**
+   * @generated from field: int64 block_id = 1;
+   */
+  blockId: bigint;
+
+  /**
+   * @generated from field: int32 conversation_id = 2;
+   */
+  conversationId: number;
+
+  /**
+   * @generated from field: memos.api.v1.TranscriptDetail detail = 3;
+   */
+  detail: TranscriptDetail;
+
+  /**
+   * @generated from field: string status = 4;
+   */
+  status: string;
+
+  /**
+   * @generated from field: repeated string user_inputs = 5;
+   */
+  userInputs: string[];
+
+  /**
+   * @generated from field: string answer = 6;
+   */
+  answer: string;
+
+  /**
+   * Tool calls in order.
+   *
+   * @generated from field: repeated memos.api.v1.TranscriptTool tools = 7;
+   */
+  tools: TranscriptTool[];
+
+  /**
+   * Steps of the block in order (full detail only).
+   *
+   * @generated from field: repeated memos.api.v1.TranscriptEntry entries = 8;
+   */
+  entries: TranscriptEntry[];
+
+  /**
+   * @generated from field: string error = 9;
+   */
+  error: string;
+
+  /**
+   * Document of a conversation bound to one.
+   *
+   * @generated from field: memos.api.v1.TranscriptSource source = 10;
+   */
+  source?: TranscriptSource;
+
+  /**
+   * The transcript rendered for exports.
+   *
+   * @generated from field: string markdown = 11;
+   */
+  markdown: string;
+
+  /**
+   * @generated from field: int64 created_ts = 12;
+   */
+  createdTs: bigint;
+};
+
+/**
+ * Describes the message memos.api.v1.BlockTranscript.
+ * Use `create(BlockTranscriptSchema)` to create a new message.
+ */
+export const BlockTranscriptSchema: GenMessage<BlockTranscript> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 96);
+
+/**
+ * TranscriptTool is a tool call of a block transcript.
+ *
+ * @generated from message memos.api.v1.TranscriptTool
+ */
+export type TranscriptTool = Message<"memos.api.v1.TranscriptTool"> & {
+  /**
+   * @generated from field: string name = 1;
+   */
+  name: string;
+
+  /**
+   * The call summarized on one line
+   *
+   * @generated from field: string line = 2;
+   */
+  line: string;
+
+  /**
+   * @generated from field: bool failed = 3;
+   */
+  failed: boolean;
+
+  /**
+   * @generated from field: int64 duration_ms = 4;
+   */
+  durationMs: bigint;
+
+  /**
+   * Input and output are kept in full transcripts only.
+   *
+   * @generated from field: string input = 5;
+   */
+  input: string;
+
+  /**
+   * @generated from field: string output = 6;
+   */
+  output: string;
+};
+
+/**
+ * Describes the message memos.api.v1.TranscriptTool.
+ * Use `create(TranscriptToolSchema)` to create a new message.
+ */
+export const TranscriptToolSchema: GenMessage<TranscriptTool> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 97);
+
+/**
+ * TranscriptEntry is a step of a full transcript.
+ *
+ * @generated from message memos.api.v1.TranscriptEntry
+ */
+export type TranscriptEntry = Message<"memos.api.v1.TranscriptEntry"> & {
+  /**
+   * "thinking", "tool", "answer" or "error"
+   *
+   * @generated from field: string type = 1;
+   */
+  type: string;
+
+  /**
+   * @generated from field: string content = 2;
+   */
+  content: string;
+
+  /**
+   * @generated from field: memos.api.v1.TranscriptTool tool = 3;
+   */
+  tool?: TranscriptTool;
+};
+
+/**
+ * Describes the message memos.api.v1.TranscriptEntry.
+ * Use `create(TranscriptEntrySchema)` to create a new message.
+ */
+export const TranscriptEntrySchema: GenMessage<TranscriptEntry> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 98);
+
+/**
+ * TranscriptSource is the document a block answered about, with the passages
+ * the answer cites, so that exports stand on their own.
+ *
+ * @generated from message memos.api.v1.TranscriptSource
+ */
+export type TranscriptSource = Message<"memos.api.v1.TranscriptSource"> & {
+  /**
+   * @generated from field: string kind = 1;
+   */
+  kind: string;
+
+  /**
+   * @generated from field: string uid = 2;
+   */
+  uid: string;
+
+  /**
+   * @generated from field: string title = 3;
+   */
+  title: string;
+
+  /**
+   * Cited passages as they read when cited.
+   *
+   * @generated from field: repeated memos.api.v1.TranscriptPassage passages = 4;
+   */
+  passages: TranscriptPassage[];
+
+  /**
+   * The whole document (full detail only).
+   *
+   * @generated from field: string text = 5;
+   */
+  text: string;
+};
+
+/**
+ * Describes the message memos.api.v1.TranscriptSource.
+ * Use `create(TranscriptSourceSchema)` to create a new message.
+ */
+export const TranscriptSourceSchema: GenMessage<TranscriptSource> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 99);
+
+/**
+ * TranscriptPassage is a cited passage of a document.
+ *
+ * @generated from message memos.api.v1.TranscriptPassage
+ */
+export type TranscriptPassage = Message<"memos.api.v1.TranscriptPassage"> & {
+  /**
+   * Offset of the passage in the document
+   *
+   * @generated from field: int32 start = 1;
+   */
+  start: number;
+
+  /**
+   * @generated from field: int32 end = 2;
+   */
+  end: number;
+
+  /**
+   * @generated from field: string text = 3;
+   */
+  text: string;
+};
+
+/**
+ * Describes the message memos.api.v1.TranscriptPassage.
+ * Use `create(TranscriptPassageSchema)` to create a new message.
+ */
+export const TranscriptPassageSchema: GenMessage<TranscriptPassage> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 100);
+
+/**
+ * ReindexAllRequest is the request for ReindexAll.
+ *
+ * @generated from message memos.api.v1.ReindexAllRequest
+ */
+export type ReindexAllRequest = Message<"memos.api.v1.ReindexAllRequest"> & {
+};
+
+/**
+ * Describes the message memos.api.v1.ReindexAllRequest.
+ * Use `create(ReindexAllRequestSchema)` to create a new message.
+ */
+export const ReindexAllRequestSchema: GenMessage<ReindexAllRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 101);
+
+/**
+ * ReindexSinceRequest is the request for ReindexSince.
+ *
+ * @generated from message memos.api.v1.ReindexSinceRequest
+ */
+export type ReindexSinceRequest = Message<"memos.api.v1.ReindexSinceRequest"> & {
+  /**
+   * Unix timestamp of the oldest memo update to reindex
+   *
+   * @generated from field: int64 since = 1;
+   */
+  since: bigint;
+};
+
+/**
+ * Describes the message memos.api.v1.ReindexSinceRequest.
+ * Use `create(ReindexSinceRequestSchema)` to create a new message.
+ */
+export const ReindexSinceRequestSchema: GenMessage<ReindexSinceRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 102);
+
+/**
+ * GetReindexProgressRequest is the request for GetReindexProgress.
+ *
+ * @generated from message memos.api.v1.GetReindexProgressRequest
+ */
+export type GetReindexProgressRequest = Message<"memos.api.v1.GetReindexProgressRequest"> & {
+};
+
+/**
+ * Describes the message memos.api.v1.GetReindexProgressRequest.
+ * Use `create(GetReindexProgressRequestSchema)` to create a new message.
+ */
+export const GetReindexProgressRequestSchema: GenMessage<GetReindexProgressRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 103);
+
+/**
+ * ReindexProgress is the progress of a reindex of the memo embeddings.
+ *
+ * @generated from message memos.api.v1.ReindexProgress
+ */
+export type ReindexProgress = Message<"memos.api.v1.ReindexProgress"> & {
+  /**
+   * Unix timestamp from which memos are reindexed (0 = all)
+   *
+   * @generated from field: int64 since = 1;
+   */
+  since: bigint;
+
+  /**
+   * @generated from field: bool running = 2;
+   */
+  running: boolean;
+
+  /**
+   * @generated from field: int32 total = 3;
+   */
+  total: number;
+
+  /**
+   * @generated from field: int32 processed = 4;
+   */
+  processed: number;
+
+  /**
+   * @generated from field: int32 failed = 5;
+   */
+  failed: number;
+
+  /**
+   * Embedding model the memos are reindexed with
+   *
+   * @generated from field: string model = 6;
+   */
+  model: string;
+
+  /**
+   * @generated from field: int64 started_ts = 7;
+   */
+  startedTs: bigint;
+
+  /**
+   * @generated from field: int64 finished_ts = 8;
+   */
+  finishedTs: bigint;
+
+  /**
+   * @generated from field: string error = 9;
+   */
+  error: string;
+};
+
+/**
+ * Describes the message memos.api.v1.ReindexProgress.
+ * Use `create(ReindexProgressSchema)` to create a new message.
+ */
+export const ReindexProgressSchema: GenMessage<ReindexProgress> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 104);
+
+/**
+ * MCPServer is an MCP server a user registered for Geek Mode. Environment
+ * variables and headers may hold credentials, so only their names are returned.
+ *
+ * @generated from message memos.api.v1.MCPServer
+ */
+export type MCPServer = Message<"memos.api.v1.MCPServer"> & {
+  /**
+   * @generated from field: int32 id = 1;
+   */
+  id: number;
+
+  /**
+   * Tools are named mcp__<name>__<tool>
+   *
+   * @generated from field: string name = 2;
+   */
+  name: string;
+
+  /**
+   * "stdio", "http" or "sse"
+   *
+   * @generated from field: string transport = 3;
+   */
+  transport: string;
+
+  /**
+   * Command of a stdio server
+   *
+   * @generated from field: string command = 4;
+   */
+  command: string;
+
+  /**
+   * @generated from field: repeated string args = 5;
+   */
+  args: string[];
+
+  /**
+   * Endpoint of an http or sse server
+   *
+   * @generated from field: string url = 6;
+   */
+  url: string;
+
+  /**
+   * @generated from field: bool enabled = 7;
+   */
+  enabled: boolean;
+
+  /**
+   * @generated from field: repeated string env_names = 8;
+   */
+  envNames: string[];
+
+  /**
+   * @generated from field: repeated string header_names = 9;
+   */
+  headerNames: string[];
+
+  /**
+   * @generated from field: int64 created_ts = 10;
+   */
+  createdTs: bigint;
+
+  /**
+   * @generated from field: int64 updated_ts = 11;
+   */
+  updatedTs: bigint;
+};
+
+/**
+ * Describes the message memos.api.v1.MCPServer.
+ * Use `create(MCPServerSchema)` to create a new message.
+ */
+export const MCPServerSchema: GenMessage<MCPServer> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 105);
+
+/**
+ * ListMCPServersRequest is the request for ListMCPServers.
+ *
+ * @generated from message memos.api.v1.ListMCPServersRequest
+ */
+export type ListMCPServersRequest = Message<"memos.api.v1.ListMCPServersRequest"> & {
+};
+
+/**
+ * Describes the message memos.api.v1.ListMCPServersRequest.
+ * Use `create(ListMCPServersRequestSchema)` to create a new message.
+ */
+export const ListMCPServersRequestSchema: GenMessage<ListMCPServersRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 106);
+
+/**
+ * ListMCPServersResponse is the response for ListMCPServers.
+ *
+ * @generated from message memos.api.v1.ListMCPServersResponse
+ */
+export type ListMCPServersResponse = Message<"memos.api.v1.ListMCPServersResponse"> & {
+  /**
+   * @generated from field: repeated memos.api.v1.MCPServer servers = 1;
+   */
+  servers: MCPServer[];
+};
+
+/**
+ * Describes the message memos.api.v1.ListMCPServersResponse.
+ * Use `create(ListMCPServersResponseSchema)` to create a new message.
+ */
+export const ListMCPServersResponseSchema: GenMessage<ListMCPServersResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 107);
+
+/**
+ * CreateMCPServerRequest is the request for CreateMCPServer.
+ *
+ * @generated from message memos.api.v1.CreateMCPServerRequest
+ */
+export type CreateMCPServerRequest = Message<"memos.api.v1.CreateMCPServerRequest"> & {
+  /**
+   * @generated from field: string name = 1;
+   */
+  name: string;
+
+  /**
+   * "stdio", "http" or "sse"
+   *
+   * @generated from field: string transport = 2;
+   */
+  transport: string;
+
+  /**
+   * @generated from field: string command = 3;
+   */
+  command: string;
+
+  /**
+   * @generated from field: repeated string args = 4;
+   */
+  args: string[];
+
+  /**
+   * Environment variables of a stdio server
+   *
+   * @generated from field: map<string, string> env = 5;
+   */
+  env: { [key: string]: string };
+
+  /**
+   * @generated from field: string url = 6;
+   */
+  url: string;
+
+  /**
+   * HTTP headers of an http or sse server
+   *
+   * @generated from field: map<string, string> headers = 7;
+   */
+  headers: { [key: string]: string };
+
+  /**
+   * Defaults to true
+   *
+   * @generated from field: optional bool enabled = 8;
+   */
+  enabled?: boolean;
+};
+
+/**
+ * Describes the message memos.api.v1.CreateMCPServerRequest.
+ * Use `create(CreateMCPServerRequestSchema)` to create a new message.
+ */
+export const CreateMCPServerRequestSchema: GenMessage<CreateMCPServerRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 108);
+
+/**
+ * UpdateMCPServerRequest is the request for UpdateMCPServer. Empty env and
+ * headers keep the current values, which clients cannot read back.
+ *
+ * @generated from message memos.api.v1.UpdateMCPServerRequest
+ */
+export type UpdateMCPServerRequest = Message<"memos.api.v1.UpdateMCPServerRequest"> & {
+  /**
+   * @generated from field: int32 id = 1;
+   */
+  id: number;
+
+  /**
+   * @generated from field: string name = 2;
+   */
+  name: string;
+
+  /**
+   * @generated from field: string transport = 3;
+   */
+  transport: string;
+
+  /**
+   * @generated from field: string command = 4;
+   */
+  command: string;
+
+  /**
+   * @generated from field: repeated string args = 5;
+   */
+  args: string[];
+
+  /**
+   * @generated from field: map<string, string> env = 6;
+   */
+  env: { [key: string]: string };
+
+  /**
+   * @generated from field: string url = 7;
+   */
+  url: string;
+
+  /**
+   * @generated from field: map<string, string> headers = 8;
+   */
+  headers: { [key: string]: string };
+
+  /**
+   * Defaults to true
+   *
+   * @generated from field: optional bool enabled = 9;
+   */
+  enabled?: boolean;
+
+  /**
+   * Remove the environment variables instead of keeping them
+   *
+   * @generated from field: bool clear_env = 10;
+   */
+  clearEnv: boolean;
+
+  /**
+   * Remove the headers instead of keeping them
+   *
+   * @generated from field: bool clear_headers = 11;
+   */
+  clearHeaders: boolean;
+};
+
+/**
+ * Describes the message memos.api.v1.UpdateMCPServerRequest.
+ * Use `create(UpdateMCPServerRequestSchema)` to create a new message.
+ */
+export const UpdateMCPServerRequestSchema: GenMessage<UpdateMCPServerRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 109);
+
+/**
+ * DeleteMCPServerRequest is the request for DeleteMCPServer.
+ *
+ * @generated from message memos.api.v1.DeleteMCPServerRequest
+ */
+export type DeleteMCPServerRequest = Message<"memos.api.v1.DeleteMCPServerRequest"> & {
+  /**
+   * @generated from field: int32 id = 1;
+   */
+  id: number;
+};
+
+/**
+ * Describes the message memos.api.v1.DeleteMCPServerRequest.
+ * Use `create(DeleteMCPServerRequestSchema)` to create a new message.
+ */
+export const DeleteMCPServerRequestSchema: GenMessage<DeleteMCPServerRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 110);
+
+/**
+ * ImportConversationRequest is the request for ImportConversation.
+ *
+ * @generated from message memos.api.v1.ImportConversationRequest
+ */
+export type ImportConversationRequest = Message<"memos.api.v1.ImportConversationRequest"> & {
+  /**
+   * @generated from field: memos.api.v1.ConversationImportFormat format = 1;
+   */
+  format: ConversationImportFormat;
+
+  /**
+   * The conversations.json of a ChatGPT or Claude data export, or a single
+   * conversation of it.
+   *
+   * @generated from field: string data = 2;
+   */
+  data: string;
+};
+
+/**
+ * Describes the message memos.api.v1.ImportConversationRequest.
+ * Use `create(ImportConversationRequestSchema)` to create a new message.
+ */
+export const ImportConversationRequestSchema: GenMessage<ImportConversationRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 111);
+
+/**
+ * ImportConversationResponse is the response for ImportConversation.
+ *
+ * @generated from message memos.api.v1.ImportConversationResponse
+ */
+export type ImportConversationResponse = Message<"memos.api.v1.ImportConversationResponse"> & {
+  /**
+   * @generated from field: repeated memos.api.v1.ImportedConversation conversations = 1;
+   */
+  conversations: ImportedConversation[];
+
+  /**
+   * Conversations without any message of the user
+   *
+   * @generated from field: int32 skipped = 2;
+   */
+  skipped: number;
+};
+
+/**
+ * Describes the message memos.api.v1.ImportConversationResponse.
+ * Use `create(ImportConversationResponseSchema)` to create a new message.
+ */
+export const ImportConversationResponseSchema: GenMessage<ImportConversationResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 112);
+
+/**
+ * ImportedConversation is a conversation created by an import.
+ *
+ * @generated from message memos.api.v1.ImportedConversation
+ */
+export type ImportedConversation = Message<"memos.api.v1.ImportedConversation"> & {
+  /**
+   * @generated from field: string uid = 1;
+   */
+  uid: string;
+
+  /**
+   * @generated from field: string title = 2;
+   */
+  title: string;
+
+  /**
+   * @generated from field: int32 rounds = 3;
+   */
+  rounds: number;
+};
+
+/**
+ * Describes the message memos.api.v1.ImportedConversation.
+ * Use `create(ImportedConversationSchema)` to create a new message.
+ */
+export const ImportedConversationSchema: GenMessage<ImportedConversation> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 113);
+
+/**
+ * ActionAuditRecord is a tool call of a Geek or Evolution Mode session.
+ *
+ * @generated from message memos.api.v1.ActionAuditRecord
+ */
+export type ActionAuditRecord = Message<"memos.api.v1.ActionAuditRecord"> & {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id: bigint;
+
+  /**
+   * @generated from field: int32 user_id = 2;
+   */
+  userId: number;
+
+  /**
+   * "geek" or "evolution"
+   *
+   * @generated from field: string mode = 3;
+   */
+  mode: string;
+
+  /**
+   * @generated from field: string session_id = 4;
+   */
+  sessionId: string;
+
+  /**
+   * @generated from field: string tool = 5;
+   */
+  tool: string;
+
+  /**
+   * @generated from field: string tool_id = 6;
+   */
+  toolId: string;
+
+  /**
+   * Summary of the input of the tool, e.g. the command of Bash
+   *
+   * @generated from field: string input = 7;
+   */
+  input: string;
+
+  /**
+   * @generated from field: repeated string file_paths = 8;
+   */
+  filePaths: string[];
+
+  /**
+   * "success", "error", "blocked" or "unfinished"
+   *
+   * @generated from field: string status = 9;
+   */
+  status: string;
+
+  /**
+   * Summary of the output, the error for failed and blocked tools
+   *
+   * @generated from field: string output = 10;
+   */
+  output: string;
+
+  /**
+   * @generated from field: int64 duration_ms = 11;
+   */
+  durationMs: bigint;
+
+  /**
+   * @generated from field: int64 created_ts = 12;
+   */
+  createdTs: bigint;
+};
+
+/**
+ * Describes the message memos.api.v1.ActionAuditRecord.
+ * Use `create(ActionAuditRecordSchema)` to create a new message.
+ */
+export const ActionAuditRecordSchema: GenMessage<ActionAuditRecord> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 114);
+
+/**
+ * ActionAuditFilter selects audit records; zero fields select all.
+ *
+ * @generated from message memos.api.v1.ActionAuditFilter
+ */
+export type ActionAuditFilter = Message<"memos.api.v1.ActionAuditFilter"> & {
+  /**
+   * @generated from field: int32 user_id = 1;
+   */
+  userId: number;
+
+  /**
+   * "geek" or "evolution"
+   *
+   * @generated from field: string mode = 2;
+   */
+  mode: string;
+
+  /**
+   * @generated from field: string session_id = 3;
+   */
+  sessionId: string;
+
+  /**
+   * @generated from field: string tool = 4;
+   */
+  tool: string;
+
+  /**
+   * "success", "error", "blocked" or "unfinished"
+   *
+   * @generated from field: string status = 5;
+   */
+  status: string;
+
+  /**
+   * Unix timestamp of the oldest call, inclusive
+   *
+   * @generated from field: int64 from = 6;
+   */
+  from: bigint;
+
+  /**
+   * Unix timestamp of the latest call, inclusive
+   *
+   * @generated from field: int64 to = 7;
+   */
+  to: bigint;
+
+  /**
+   * Only records older than this one
+   *
+   * @generated from field: int64 before_id = 8;
+   */
+  beforeId: bigint;
+
+  /**
+   * 1 to 500
+   *
+   * @generated from field: int32 limit = 9;
+   */
+  limit: number;
+};
+
+/**
+ * Describes the message memos.api.v1.ActionAuditFilter.
+ * Use `create(ActionAuditFilterSchema)` to create a new message.
+ */
+export const ActionAuditFilterSchema: GenMessage<ActionAuditFilter> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 115);
+
+/**
+ * ListActionAuditRequest is the request for ListActionAudit.
+ *
+ * @generated from message memos.api.v1.ListActionAuditRequest
+ */
+export type ListActionAuditRequest = Message<"memos.api.v1.ListActionAuditRequest"> & {
+  /**
+   * @generated from field: memos.api.v1.ActionAuditFilter filter = 1;
+   */
+  filter?: ActionAuditFilter;
+};
+
+/**
+ * Describes the message memos.api.v1.ListActionAuditRequest.
+ * Use `create(ListActionAuditRequestSchema)` to create a new message.
+ */
+export const ListActionAuditRequestSchema: GenMessage<ListActionAuditRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 116);
+
+/**
+ * ListActionAuditResponse is the response for ListActionAudit.
+ *
+ * @generated from message memos.api.v1.ListActionAuditResponse
+ */
+export type ListActionAuditResponse = Message<"memos.api.v1.ListActionAuditResponse"> & {
+  /**
+   * @generated from field: repeated memos.api.v1.ActionAuditRecord records = 1;
+   */
+  records: ActionAuditRecord[];
+
+  /**
+   * The before_id of the next page, set while more records may follow.
+   *
+   * @generated from field: int64 next_before_id = 2;
+   */
+  nextBeforeId: bigint;
+};
+
+/**
+ * Describes the message memos.api.v1.ListActionAuditResponse.
+ * Use `create(ListActionAuditResponseSchema)` to create a new message.
+ */
+export const ListActionAuditResponseSchema: GenMessage<ListActionAuditResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 117);
+
+/**
+ * ExportActionAuditRequest is the request for ExportActionAudit.
+ *
+ * @generated from message memos.api.v1.ExportActionAuditRequest
+ */
+export type ExportActionAuditRequest = Message<"memos.api.v1.ExportActionAuditRequest"> & {
+  /**
+   * @generated from field: memos.api.v1.ActionAuditFilter filter = 1;
+   */
+  filter?: ActionAuditFilter;
+
+  /**
+   * @generated from field: memos.api.v1.ActionAuditExportFormat format = 2;
+   */
+  format: ActionAuditExportFormat;
+};
+
+/**
+ * Describes the message memos.api.v1.ExportActionAuditRequest.
+ * Use `create(ExportActionAuditRequestSchema)` to create a new message.
+ */
+export const ExportActionAuditRequestSchema: GenMessage<ExportActionAuditRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 118);
+
+/**
+ * ExportActionAuditResponse is the response for ExportActionAudit.
+ *
+ * @generated from message memos.api.v1.ExportActionAuditResponse
+ */
+export type ExportActionAuditResponse = Message<"memos.api.v1.ExportActionAuditResponse"> & {
+  /**
+   * @generated from field: bytes data = 1;
+   */
+  data: Uint8Array;
+
+  /**
+   * @generated from field: string filename = 2;
+   */
+  filename: string;
+
+  /**
+   * @generated from field: string content_type = 3;
+   */
+  contentType: string;
+};
+
+/**
+ * Describes the message memos.api.v1.ExportActionAuditResponse.
+ * Use `create(ExportActionAuditResponseSchema)` to create a new message.
+ */
+export const ExportActionAuditResponseSchema: GenMessage<ExportActionAuditResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 119);
+
+/**
+ * LLMProvider is a configured LLM provider.
+ *
+ * @generated from message memos.api.v1.LLMProvider
+ */
+export type LLMProvider = Message<"memos.api.v1.LLMProvider"> & {
+  /**
+   * @generated from field: string name = 1;
+   */
+  name: string;
+
+  /**
+   * e.g. "openai", "deepseek" or "ollama"
+   *
+   * @generated from field: string provider = 2;
+   */
+  provider: string;
+
+  /**
+   * @generated from field: string base_url = 3;
+   */
+  baseUrl: string;
+
+  /**
+   * Default model
+   *
+   * @generated from field: string model = 4;
+   */
+  model: string;
+};
+
+/**
+ * Describes the message memos.api.v1.LLMProvider.
+ * Use `create(LLMProviderSchema)` to create a new message.
+ */
+export const LLMProviderSchema: GenMessage<LLMProvider> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 120);
+
+/**
+ * AgentModel is the provider and model of an agent type.
+ *
+ * @generated from message memos.api.v1.AgentModel
+ */
+export type AgentModel = Message<"memos.api.v1.AgentModel"> & {
+  /**
+   * A parrot name or "orchestrator"
+   *
+   * @generated from field: string agent = 1;
+   */
+  agent: string;
+
+  /**
+   * @generated from field: string provider = 2;
+   */
+  provider: string;
+
+  /**
+   * Empty for the default model of the provider
+   *
+   * @generated from field: string model = 3;
+   */
+  model: string;
+
+  /**
+   * "default", "env" or "admin"
+   *
+   * @generated from field: string source = 4;
+   */
+  source: string;
+
+  /**
+   * @generated from field: int64 updated_ts = 5;
+   */
+  updatedTs: bigint;
+};
+
+/**
+ * Describes the message memos.api.v1.AgentModel.
+ * Use `create(AgentModelSchema)` to create a new message.
+ */
+export const AgentModelSchema: GenMessage<AgentModel> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 121);
+
+/**
+ * ListAgentModelsRequest is the request for ListAgentModels.
+ *
+ * @generated from message memos.api.v1.ListAgentModelsRequest
+ */
+export type ListAgentModelsRequest = Message<"memos.api.v1.ListAgentModelsRequest"> & {
+};
+
+/**
+ * Describes the message memos.api.v1.ListAgentModelsRequest.
+ * Use `create(ListAgentModelsRequestSchema)` to create a new message.
+ */
+export const ListAgentModelsRequestSchema: GenMessage<ListAgentModelsRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 122);
+
+/**
+ * ListAgentModelsResponse is the response for ListAgentModels.
+ *
+ * @generated from message memos.api.v1.ListAgentModelsResponse
+ */
+export type ListAgentModelsResponse = Message<"memos.api.v1.ListAgentModelsResponse"> & {
+  /**
+   * @generated from field: repeated memos.api.v1.LLMProvider providers = 1;
+   */
+  providers: LLMProvider[];
+
+  /**
+   * @generated from field: repeated memos.api.v1.AgentModel agents = 2;
+   */
+  agents: AgentModel[];
+};
+
+/**
+ * Describes the message memos.api.v1.ListAgentModelsResponse.
+ * Use `create(ListAgentModelsResponseSchema)` to create a new message.
+ */
+export const ListAgentModelsResponseSchema: GenMessage<ListAgentModelsResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 123);
+
+/**
+ * UpdateAgentModelRequest is the request for UpdateAgentModel.
+ *
+ * @generated from message memos.api.v1.UpdateAgentModelRequest
+ */
+export type UpdateAgentModelRequest = Message<"memos.api.v1.UpdateAgentModelRequest"> & {
+  /**
+   * A parrot name or "orchestrator"
+   *
+   * @generated from field: string agent = 1;
+   */
+  agent: string;
+
+  /**
+   * @generated from field: string provider = 2;
+   */
+  provider: string;
+
+  /**
+   * Empty for the default model of the provider
+   *
+   * @generated from field: string model = 3;
+   */
+  model: string;
+};
+
+/**
+ * Describes the message memos.api.v1.UpdateAgentModelRequest.
+ * Use `create(UpdateAgentModelRequestSchema)` to create a new message.
+ */
+export const UpdateAgentModelRequestSchema: GenMessage<UpdateAgentModelRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 124);
+
+/**
+ * DeleteAgentModelRequest is the request for DeleteAgentModel.
+ *
+ * @generated from message memos.api.v1.DeleteAgentModelRequest
+ */
+export type DeleteAgentModelRequest = Message<"memos.api.v1.DeleteAgentModelRequest"> & {
+  /**
+   * @generated from field: string agent = 1;
+   */
+  agent: string;
+};
+
+/**
+ * Describes the message memos.api.v1.DeleteAgentModelRequest.
+ * Use `create(DeleteAgentModelRequestSchema)` to create a new message.
+ */
+export const DeleteAgentModelRequestSchema: GenMessage<DeleteAgentModelRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 125);
+
+/**
+ * GeekWorkspaceUsage is the disk usage of a Geek Mode workspace.
+ *
+ * @generated from message memos.api.v1.GeekWorkspaceUsage
+ */
+export type GeekWorkspaceUsage = Message<"memos.api.v1.GeekWorkspaceUsage"> & {
+  /**
+   * @generated from field: int64 bytes = 1;
+   */
+  bytes: bigint;
+
+  /**
+   * @generated from field: int32 files = 2;
+   */
+  files: number;
+
+  /**
+   * 0 for workspaces without limit
+   *
+   * @generated from field: int64 limit_bytes = 3;
+   */
+  limitBytes: bigint;
+
+  /**
+   * @generated from field: int64 measured_ts = 4;
+   */
+  measuredTs: bigint;
+};
+
+/**
+ * Describes the message memos.api.v1.GeekWorkspaceUsage.
+ * Use `create(GeekWorkspaceUsageSchema)` to create a new message.
+ */
+export const GeekWorkspaceUsageSchema: GenMessage<GeekWorkspaceUsage> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 126);
+
+/**
+ * GeekWorkspaceEntry is a file or directory of a Geek Mode workspace.
+ *
+ * @generated from message memos.api.v1.GeekWorkspaceEntry
+ */
+export type GeekWorkspaceEntry = Message<"memos.api.v1.GeekWorkspaceEntry"> & {
+  /**
+   * @generated from field: string name = 1;
+   */
+  name: string;
+
+  /**
+   * Relative to the workspace, with forward slashes
+   *
+   * @generated from field: string path = 2;
+   */
+  path: string;
+
+  /**
+   * @generated from field: bool is_dir = 3;
+   */
+  isDir: boolean;
+
+  /**
+   * @generated from field: bool symlink = 4;
+   */
+  symlink: boolean;
+
+  /**
+   * @generated from field: int64 size = 5;
+   */
+  size: bigint;
+
+  /**
+   * @generated from field: int64 modified_ts = 6;
+   */
+  modifiedTs: bigint;
+};
+
+/**
+ * Describes the message memos.api.v1.GeekWorkspaceEntry.
+ * Use `create(GeekWorkspaceEntrySchema)` to create a new message.
+ */
+export const GeekWorkspaceEntrySchema: GenMessage<GeekWorkspaceEntry> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 127);
+
+/**
+ * GetGeekWorkspaceUsageRequest is the request for GetGeekWorkspaceUsage.
+ *
+ * @generated from message memos.api.v1.GetGeekWorkspaceUsageRequest
+ */
+export type GetGeekWorkspaceUsageRequest = Message<"memos.api.v1.GetGeekWorkspaceUsageRequest"> & {
+};
+
+/**
+ * Describes the message memos.api.v1.GetGeekWorkspaceUsageRequest.
+ * Use `create(GetGeekWorkspaceUsageRequestSchema)` to create a new message.
+ */
+export const GetGeekWorkspaceUsageRequestSchema: GenMessage<GetGeekWorkspaceUsageRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 128);
+
+/**
+ * ListGeekWorkspaceFilesRequest is the request for ListGeekWorkspaceFiles.
+ *
+ * @generated from message memos.api.v1.ListGeekWorkspaceFilesRequest
+ */
+export type ListGeekWorkspaceFilesRequest = Message<"memos.api.v1.ListGeekWorkspaceFilesRequest"> & {
+  /**
+   * Relative to the workspace; empty for the workspace itself
+   *
+   * @generated from field: string path = 1;
+   */
+  path: string;
+};
+
+/**
+ * Describes the message memos.api.v1.ListGeekWorkspaceFilesRequest.
+ * Use `create(ListGeekWorkspaceFilesRequestSchema)` to create a new message.
+ */
+export const ListGeekWorkspaceFilesRequestSchema: GenMessage<ListGeekWorkspaceFilesRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 129);
+
+/**
+ * ListGeekWorkspaceFilesResponse is the response for ListGeekWorkspaceFiles.
+ *
+ * @generated from message memos.api.v1.ListGeekWorkspaceFilesResponse
+ */
+export type ListGeekWorkspaceFilesResponse = Message<"memos.api.v1.ListGeekWorkspaceFilesResponse"> & {
+  /**
+   * @generated from field: string path = 1;
+   */
+  path: string;
+
+  /**
+   * @generated from field: repeated memos.api.v1.GeekWorkspaceEntry entries = 2;
+   */
+  entries: GeekWorkspaceEntry[];
+
+  /**
+   * Set when the directory has more entries than listed
+   *
+   * @generated from field: bool truncated = 3;
+   */
+  truncated: boolean;
+};
+
+/**
+ * Describes the message memos.api.v1.ListGeekWorkspaceFilesResponse.
+ * Use `create(ListGeekWorkspaceFilesResponseSchema)` to create a new message.
+ */
+export const ListGeekWorkspaceFilesResponseSchema: GenMessage<ListGeekWorkspaceFilesResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 130);
+
+/**
+ * ReadGeekWorkspaceFileRequest is the request for ReadGeekWorkspaceFile.
+ *
+ * @generated from message memos.api.v1.ReadGeekWorkspaceFileRequest
+ */
+export type ReadGeekWorkspaceFileRequest = Message<"memos.api.v1.ReadGeekWorkspaceFileRequest"> & {
+  /**
+   * @generated from field: string path = 1;
+   */
+  path: string;
+};
+
+/**
+ * Describes the message memos.api.v1.ReadGeekWorkspaceFileRequest.
+ * Use `create(ReadGeekWorkspaceFileRequestSchema)` to create a new message.
+ */
+export const ReadGeekWorkspaceFileRequestSchema: GenMessage<ReadGeekWorkspaceFileRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 131);
+
+/**
+ * GeekWorkspaceFileContent is a file of a Geek Mode workspace with its content.
+ *
+ * @generated from message memos.api.v1.GeekWorkspaceFileContent
+ */
+export type GeekWorkspaceFileContent = Message<"memos.api.v1.GeekWorkspaceFileContent"> & {
+  /**
+   * @generated from field: memos.api.v1.GeekWorkspaceEntry entry = 1;
+   */
+  entry?: GeekWorkspaceEntry;
+
+  /**
+   * @generated from field: string mime_type = 2;
+   */
+  mimeType: string;
+
+  /**
+   * Binary files have no content; download them instead
+   *
+   * @generated from field: bool binary = 3;
+   */
+  binary: boolean;
+
+  /**
+   * Set when the file is larger than 1 MiB
+   *
+   * @generated from field: bool truncated = 4;
+   */
+  truncated: boolean;
+
+  /**
+   * @generated from field: string content = 5;
+   */
+  content: string;
+};
+
+/**
+ * Describes the message memos.api.v1.GeekWorkspaceFileContent.
+ * Use `create(GeekWorkspaceFileContentSchema)` to create a new message.
+ */
+export const GeekWorkspaceFileContentSchema: GenMessage<GeekWorkspaceFileContent> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 132);
+
+/**
+ * DownloadGeekWorkspaceFileRequest is the request for DownloadGeekWorkspaceFile.
+ *
+ * @generated from message memos.api.v1.DownloadGeekWorkspaceFileRequest
+ */
+export type DownloadGeekWorkspaceFileRequest = Message<"memos.api.v1.DownloadGeekWorkspaceFileRequest"> & {
+  /**
+   * @generated from field: string path = 1;
+   */
+  path: string;
+};
+
+/**
+ * Describes the message memos.api.v1.DownloadGeekWorkspaceFileRequest.
+ * Use `create(DownloadGeekWorkspaceFileRequestSchema)` to create a new message.
+ */
+export const DownloadGeekWorkspaceFileRequestSchema: GenMessage<DownloadGeekWorkspaceFileRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 133);
+
+/**
+ * DownloadGeekWorkspaceFileResponse is the response for DownloadGeekWorkspaceFile.
+ *
+ * @generated from message memos.api.v1.DownloadGeekWorkspaceFileResponse
+ */
+export type DownloadGeekWorkspaceFileResponse = Message<"memos.api.v1.DownloadGeekWorkspaceFileResponse"> & {
+  /**
+   * @generated from field: bytes data = 1;
+   */
+  data: Uint8Array;
+
+  /**
+   * @generated from field: string filename = 2;
+   */
+  filename: string;
+
+  /**
+   * Always application/octet-stream
+   *
+   * @generated from field: string content_type = 3;
+   */
+  contentType: string;
+};
+
+/**
+ * Describes the message memos.api.v1.DownloadGeekWorkspaceFileResponse.
+ * Use `create(DownloadGeekWorkspaceFileResponseSchema)` to create a new message.
+ */
+export const DownloadGeekWorkspaceFileResponseSchema: GenMessage<DownloadGeekWorkspaceFileResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 134);
+
+/**
+ * DeleteGeekWorkspaceFileRequest is the request for DeleteGeekWorkspaceFile.
+ *
+ * @generated from message memos.api.v1.DeleteGeekWorkspaceFileRequest
+ */
+export type DeleteGeekWorkspaceFileRequest = Message<"memos.api.v1.DeleteGeekWorkspaceFileRequest"> & {
+  /**
+   * @generated from field: string path = 1;
+   */
+  path: string;
+};
+
+/**
+ * Describes the message memos.api.v1.DeleteGeekWorkspaceFileRequest.
+ * Use `create(DeleteGeekWorkspaceFileRequestSchema)` to create a new message.
+ */
+export const DeleteGeekWorkspaceFileRequestSchema: GenMessage<DeleteGeekWorkspaceFileRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_ai_service, 135);
+
+/**
+ * ScheduleQueryMode specifies the query mode for schedule filtering.
+ *
+ * @generated from enum memos.api.v1.ScheduleQueryMode
+ */
+export enum ScheduleQueryMode {
+  /**
+   * Auto-select query mode based on query type (relative time → STANDARD, absolute time → STRICT)
+   *
+   * @generated from enum value: AUTO = 0;
+   */
+  AUTO = 0,
+
+  /**
+   * Standard mode: return schedules that have any part within the query range
+   *
+   * @generated from enum value: STANDARD = 1;
+   */
+  STANDARD = 1,
+
+  /**
+   * Strict mode: return only schedules completely within the query range
+   *
+   * @generated from enum value: STRICT = 2;
+   */
+  STRICT = 2,
+}
+
+/**
+ * Describes the enum memos.api.v1.ScheduleQueryMode.
+ */
+export const ScheduleQueryModeSchema: GenEnum<ScheduleQueryMode> = /*@__PURE__*/
+  enumDesc(file_api_v1_ai_service, 0);
+
+/**
+ * AgentType specifies the type of AI agent to use for the request.
+ *
+ * @generated from enum memos.api.v1.AgentType
+ */
+export enum AgentType {
+  /**
+   * Default behavior (existing logic)
+   *
+   * @generated from enum value: AGENT_TYPE_DEFAULT = 0;
+   */
+  DEFAULT = 0,
+
+  /**
+   * 🦜 Memo Parrot - Note assistant
+   *
+   * @generated from enum value: AGENT_TYPE_MEMO = 1;
+   */
+  MEMO = 1,
+
+  /**
+   * 🦜 Schedule Parrot - Schedule assistant
+   *
+   * @generated from enum value: AGENT_TYPE_SCHEDULE = 2;
+   */
+  SCHEDULE = 2,
+
+  /**
+   * 🦜 General Parrot (通才) - General purpose assistant
+   *
+   * @generated from enum value: AGENT_TYPE_GENERAL = 3;
+   */
+  GENERAL = 3,
+
+  /**
+   * 💡 Ideation Parrot (灵光) - Creative assistant for brainstorming and ideation
+   *
+   * @generated from enum value: AGENT_TYPE_IDEATION = 5;
+   */
+  IDEATION = 5,
+}
+
+/**
+ * Describes the enum memos.api.v1.AgentType.
+ */
+export const AgentTypeSchema: GenEnum<AgentType> = /*@__PURE__*/
+  enumDesc(file_api_v1_ai_service, 1);
+
+/**
+ * ReviewQuality represents the user's assessment of recall difficulty.
+ *
+ * @generated from enum memos.api.v1.ReviewQuality
+ */
+export enum ReviewQuality {
+  /**
+   * @generated from enum value: REVIEW_QUALITY_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * Complete blackout
+   *
+   * @generated from enum value: REVIEW_QUALITY_AGAIN = 1;
+   */
+  AGAIN = 1,
+
+  /**
+   * Correct with difficulty
+   *
+   * @generated from enum value: REVIEW_QUALITY_HARD = 2;
+   */
+  HARD = 2,
 
   /**
    * Correct with hesitation
@@ -3660,6 +5454,13 @@ export enum BlockStatus {
    * @generated from enum value: BLOCK_STATUS_ERROR = 4;
    */
   ERROR = 4,
+
+  /**
+   * Stopped by the user
+   *
+   * @generated from enum value: BLOCK_STATUS_INTERRUPTED = 5;
+   */
+  INTERRUPTED = 5,
 }
 
 /**
@@ -3668,6 +5469,100 @@ export enum BlockStatus {
 export const BlockStatusSchema: GenEnum<BlockStatus> = /*@__PURE__*/
   enumDesc(file_api_v1_ai_service, 5);
 
+/**
+ * TranscriptDetail is the detail of a block transcript.
+ *
+ * @generated from enum memos.api.v1.TranscriptDetail
+ */
+export enum TranscriptDetail {
+  /**
+   * Minimal
+   *
+   * @generated from enum value: TRANSCRIPT_DETAIL_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * The final answer and a one-liner per tool call
+   *
+   * @generated from enum value: TRANSCRIPT_DETAIL_MINIMAL = 1;
+   */
+  MINIMAL = 1,
+
+  /**
+   * Also the thinking, tool inputs and outputs, and errors, in order
+   *
+   * @generated from enum value: TRANSCRIPT_DETAIL_FULL = 2;
+   */
+  FULL = 2,
+}
+
+/**
+ * Describes the enum memos.api.v1.TranscriptDetail.
+ */
+export const TranscriptDetailSchema: GenEnum<TranscriptDetail> = /*@__PURE__*/
+  enumDesc(file_api_v1_ai_service, 6);
+
+/**
+ * ConversationImportFormat is the format of an exported conversation.
+ *
+ * @generated from enum memos.api.v1.ConversationImportFormat
+ */
+export enum ConversationImportFormat {
+  /**
+   * Detected from the data
+   *
+   * @generated from enum value: CONVERSATION_IMPORT_FORMAT_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * @generated from enum value: CONVERSATION_IMPORT_FORMAT_CHATGPT = 1;
+   */
+  CHATGPT = 1,
+
+  /**
+   * @generated from enum value: CONVERSATION_IMPORT_FORMAT_CLAUDE = 2;
+   */
+  CLAUDE = 2,
+}
+
+/**
+ * Describes the enum memos.api.v1.ConversationImportFormat.
+ */
+export const ConversationImportFormatSchema: GenEnum<ConversationImportFormat> = /*@__PURE__*/
+  enumDesc(file_api_v1_ai_service, 7);
+
+/**
+ * ActionAuditExportFormat is the format of an audit export.
+ *
+ * @generated from enum memos.api.v1.ActionAuditExportFormat
+ */
+export enum ActionAuditExportFormat {
+  /**
+   * CSV
+   *
+   * @generated from enum value: ACTION_AUDIT_EXPORT_FORMAT_UNSPECIFIED = 0;
+   */
+  UNSPECIFIED = 0,
+
+  /**
+   * @generated from enum value: ACTION_AUDIT_EXPORT_FORMAT_CSV = 1;
+   */
+  CSV = 1,
+
+  /**
+   * @generated from enum value: ACTION_AUDIT_EXPORT_FORMAT_JSONL = 2;
+   */
+  JSONL = 2,
+}
+
+/**
+ * Describes the enum memos.api.v1.ActionAuditExportFormat.
+ */
+export const ActionAuditExportFormatSchema: GenEnum<ActionAuditExportFormat> = /*@__PURE__*/
+  enumDesc(file_api_v1_ai_service, 8);
+
 /**
  * AIService provides AI-powered features for memo management.
  *
@@ -3885,6 +5780,17 @@ export const AIService: GenService<{
     input: typeof GenerateConversationTitleRequestSchema;
     output: typeof GenerateConversationTitleResponseSchema;
   },
+  /**
+   * ImportConversation imports the conversations of a ChatGPT or Claude data
+   * export as conversations of the user, with a block per round.
+   *
+   * @generated from rpc memos.api.v1.AIService.ImportConversation
+   */
+  importConversation: {
+    methodKind: "unary";
+    input: typeof ImportConversationRequestSchema;
+    output: typeof ImportConversationResponseSchema;
+  },
   /**
    * DeleteAIConversation deletes an AI conversation.
    *
@@ -3915,6 +5821,18 @@ export const AIService: GenService<{
     input: typeof ClearConversationMessagesRequestSchema;
     output: typeof EmptySchema;
   },
+  /**
+   * CreateChatStreamTicket issues a short-lived, single-use ticket
+   * authenticating a GET /api/v1/ai/chat/stream request, for the EventSource
+   * clients which cannot set the Authorization header.
+   *
+   * @generated from rpc memos.api.v1.AIService.CreateChatStreamTicket
+   */
+  createChatStreamTicket: {
+    methodKind: "unary";
+    input: typeof CreateChatStreamTicketRequestSchema;
+    output: typeof ChatStreamTicketSchema;
+  },
   /**
    * StopChat cancels an ongoing chat stream and terminates the associated session.
    *
@@ -3925,6 +5843,17 @@ export const AIService: GenService<{
     input: typeof StopChatRequestSchema;
     output: typeof EmptySchema;
   },
+  /**
+   * SubmitForm answers the form a block is waiting on and continues the agent
+   * in the same block, streaming the follow-up like Chat.
+   *
+   * @generated from rpc memos.api.v1.AIService.SubmitForm
+   */
+  submitForm: {
+    methodKind: "server_streaming";
+    input: typeof SubmitFormRequestSchema;
+    output: typeof ChatResponseSchema;
+  },
   /**
    * GetSessionStats retrieves statistics for a specific session.
    *
@@ -3985,6 +5914,18 @@ export const AIService: GenService<{
     input: typeof ListBlocksRequestSchema;
     output: typeof ListBlocksResponseSchema;
   },
+  /**
+   * GetBlockChanges retrieves the blocks of a conversation created or updated
+   * since a sync, and of existing blocks only the events appended since then.
+   * Clients poll it in place of refetching the conversation.
+   *
+   * @generated from rpc memos.api.v1.AIService.GetBlockChanges
+   */
+  getBlockChanges: {
+    methodKind: "unary";
+    input: typeof GetBlockChangesRequestSchema;
+    output: typeof GetBlockChangesResponseSchema;
+  },
   /**
    * GetBlock retrieves a specific block.
    *
@@ -3995,6 +5936,18 @@ export const AIService: GenService<{
     input: typeof GetBlockRequestSchema;
     output: typeof BlockSchema;
   },
+  /**
+   * GetBlockTranscript retrieves the compact transcript of a block, collapsed
+   * from its raw event stream, for clients that do not render event streams
+   * and for exports.
+   *
+   * @generated from rpc memos.api.v1.AIService.GetBlockTranscript
+   */
+  getBlockTranscript: {
+    methodKind: "unary";
+    input: typeof GetBlockTranscriptRequestSchema;
+    output: typeof BlockTranscriptSchema;
+  },
   /**
    * CreateBlock creates a new conversation block.
    *
@@ -4090,6 +6043,211 @@ export const AIService: GenService<{
     input: typeof DeleteBranchRequestSchema;
     output: typeof EmptySchema;
   },
+  /**
+   * GetUsage retrieves the user's AI usage of the current month against the quotas.
+   *
+   * @generated from rpc memos.api.v1.AIService.GetUsage
+   */
+  getUsage: {
+    methodKind: "unary";
+    input: typeof GetUsageRequestSchema;
+    output: typeof UsageSchema;
+  },
+  /**
+   * RunSelfTest runs a tiny chat end to end in a throwaway conversation and
+   * reports each check. Admin only.
+   *
+   * @generated from rpc memos.api.v1.AIService.RunSelfTest
+   */
+  runSelfTest: {
+    methodKind: "unary";
+    input: typeof RunSelfTestRequestSchema;
+    output: typeof SelfTestReportSchema;
+  },
+  /**
+   * ReindexAll embeds all the memos again in the background. Admin only.
+   *
+   * @generated from rpc memos.api.v1.AIService.ReindexAll
+   */
+  reindexAll: {
+    methodKind: "unary";
+    input: typeof ReindexAllRequestSchema;
+    output: typeof ReindexProgressSchema;
+  },
+  /**
+   * ReindexSince embeds again the memos updated at or after a time in the
+   * background. Admin only.
+   *
+   * @generated from rpc memos.api.v1.AIService.ReindexSince
+   */
+  reindexSince: {
+    methodKind: "unary";
+    input: typeof ReindexSinceRequestSchema;
+    output: typeof ReindexProgressSchema;
+  },
+  /**
+   * GetReindexProgress retrieves the progress of the latest reindex. Admin only.
+   *
+   * @generated from rpc memos.api.v1.AIService.GetReindexProgress
+   */
+  getReindexProgress: {
+    methodKind: "unary";
+    input: typeof GetReindexProgressRequestSchema;
+    output: typeof ReindexProgressSchema;
+  },
+  /**
+   * ListMCPServers lists the MCP servers the user registered for Geek Mode.
+   *
+   * @generated from rpc memos.api.v1.AIService.ListMCPServers
+   */
+  listMCPServers: {
+    methodKind: "unary";
+    input: typeof ListMCPServersRequestSchema;
+    output: typeof ListMCPServersResponseSchema;
+  },
+  /**
+   * CreateMCPServer registers an MCP server for the Geek Mode sessions of the
+   * user. Sessions pick it up at their next request; stdio servers only in
+   * workspaces whose tool policy permits Bash.
+   *
+   * @generated from rpc memos.api.v1.AIService.CreateMCPServer
+   */
+  createMCPServer: {
+    methodKind: "unary";
+    input: typeof CreateMCPServerRequestSchema;
+    output: typeof MCPServerSchema;
+  },
+  /**
+   * UpdateMCPServer replaces an MCP server of the user.
+   *
+   * @generated from rpc memos.api.v1.AIService.UpdateMCPServer
+   */
+  updateMCPServer: {
+    methodKind: "unary";
+    input: typeof UpdateMCPServerRequestSchema;
+    output: typeof MCPServerSchema;
+  },
+  /**
+   * DeleteMCPServer removes an MCP server of the user.
+   *
+   * @generated from rpc memos.api.v1.AIService.DeleteMCPServer
+   */
+  deleteMCPServer: {
+    methodKind: "unary";
+    input: typeof DeleteMCPServerRequestSchema;
+    output: typeof EmptySchema;
+  },
+  /**
+   * ListActionAudit lists the tool calls of the Geek and Evolution Mode
+   * sessions, latest first. Admin only.
+   *
+   * @generated from rpc memos.api.v1.AIService.ListActionAudit
+   */
+  listActionAudit: {
+    methodKind: "unary";
+    input: typeof ListActionAuditRequestSchema;
+    output: typeof ListActionAuditResponseSchema;
+  },
+  /**
+   * ExportActionAudit exports the matching tool calls, latest first, as CSV or
+   * JSON Lines. Admin only.
+   *
+   * @generated from rpc memos.api.v1.AIService.ExportActionAudit
+   */
+  exportActionAudit: {
+    methodKind: "unary";
+    input: typeof ExportActionAuditRequestSchema;
+    output: typeof ExportActionAuditResponseSchema;
+  },
+  /**
+   * ListAgentModels lists the configured LLM providers, the default one first,
+   * and the provider and model of each agent type. Admin only.
+   *
+   * @generated from rpc memos.api.v1.AIService.ListAgentModels
+   */
+  listAgentModels: {
+    methodKind: "unary";
+    input: typeof ListAgentModelsRequestSchema;
+    output: typeof ListAgentModelsResponseSchema;
+  },
+  /**
+   * UpdateAgentModel selects the provider and model of an agent type; its next
+   * LLM calls use them. Admin only.
+   *
+   * @generated from rpc memos.api.v1.AIService.UpdateAgentModel
+   */
+  updateAgentModel: {
+    methodKind: "unary";
+    input: typeof UpdateAgentModelRequestSchema;
+    output: typeof AgentModelSchema;
+  },
+  /**
+   * DeleteAgentModel removes the selection of an agent type, which returns to
+   * its DIVINESENSE_AI_AGENT_MODELS selection, or to the default provider.
+   * Admin only.
+   *
+   * @generated from rpc memos.api.v1.AIService.DeleteAgentModel
+   */
+  deleteAgentModel: {
+    methodKind: "unary";
+    input: typeof DeleteAgentModelRequestSchema;
+    output: typeof EmptySchema;
+  },
+  /**
+   * GetGeekWorkspaceUsage returns the disk usage of the Geek Mode workspace of
+   * the user and its limit.
+   *
+   * @generated from rpc memos.api.v1.AIService.GetGeekWorkspaceUsage
+   */
+  getGeekWorkspaceUsage: {
+    methodKind: "unary";
+    input: typeof GetGeekWorkspaceUsageRequestSchema;
+    output: typeof GeekWorkspaceUsageSchema;
+  },
+  /**
+   * ListGeekWorkspaceFiles lists a directory of the Geek Mode workspace of the
+   * user, where the sessions leave their artifacts.
+   *
+   * @generated from rpc memos.api.v1.AIService.ListGeekWorkspaceFiles
+   */
+  listGeekWorkspaceFiles: {
+    methodKind: "unary";
+    input: typeof ListGeekWorkspaceFilesRequestSchema;
+    output: typeof ListGeekWorkspaceFilesResponseSchema;
+  },
+  /**
+   * ReadGeekWorkspaceFile returns a file of the workspace with its text
+   * content, up to 1 MiB; binary files have none.
+   *
+   * @generated from rpc memos.api.v1.AIService.ReadGeekWorkspaceFile
+   */
+  readGeekWorkspaceFile: {
+    methodKind: "unary";
+    input: typeof ReadGeekWorkspaceFileRequestSchema;
+    output: typeof GeekWorkspaceFileContentSchema;
+  },
+  /**
+   * DownloadGeekWorkspaceFile returns a file of the workspace, up to 64 MiB,
+   * to save as an attachment.
+   *
+   * @generated from rpc memos.api.v1.AIService.DownloadGeekWorkspaceFile
+   */
+  downloadGeekWorkspaceFile: {
+    methodKind: "unary";
+    input: typeof DownloadGeekWorkspaceFileRequestSchema;
+    output: typeof DownloadGeekWorkspaceFileResponseSchema;
+  },
+  /**
+   * DeleteGeekWorkspaceFile removes a file of the workspace, or a directory
+   * with its content.
+   *
+   * @generated from rpc memos.api.v1.AIService.DeleteGeekWorkspaceFile
+   */
+  deleteGeekWorkspaceFile: {
+    methodKind: "unary";
+    input: typeof DeleteGeekWorkspaceFileRequestSchema;
+    output: typeof EmptySchema;
+  },
 }> = /*@__PURE__*/
   serviceDesc(file_api_v1_ai_service, 0);
 